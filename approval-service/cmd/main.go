@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,12 +13,15 @@ import (
 	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 
+	"approval-service/internal/clients"
 	"approval-service/internal/config"
+	"approval-service/internal/database"
 	localevents "approval-service/internal/events"
 	"approval-service/internal/handlers"
+	"approval-service/internal/jobqueue"
 	"approval-service/internal/jobs"
-	"approval-service/internal/models"
 	"approval-service/internal/repository"
 	"approval-service/internal/seeders"
 	"approval-service/internal/services"
@@ -62,24 +66,26 @@ func main() {
 	// Initialize configuration
 	cfg := config.Load()
 
-	// Initialize database
-	db, err := config.InitDB(cfg)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return config.InitDB(cfg) })
 	if err != nil {
 		logger.Fatal("Failed to connect to database:", err)
 	}
 
-	// Run database migrations
-	logger.Info("Running database migrations...")
-	if err := db.AutoMigrate(
-		&models.ApprovalWorkflow{},
-		&models.ApprovalRequest{},
-		&models.ApprovalDecision{},
-		&models.ApprovalAuditLog{},
-		&models.ApprovalDelegation{},
-	); err != nil {
-		logger.Fatalf("Failed to run migrations: %v", err)
+	// "approval-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			logger.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		logger.Fatalf("Database schema is not ready: %v", err)
 	}
-	logger.Info("Database migrations completed")
+	logger.Info("Database schema is up to date")
 
 	// Seed system workflows (product_creation, category_creation, etc.)
 	logger.Info("Seeding system workflows...")
@@ -133,10 +139,19 @@ func main() {
 	approvalHandler := handlers.NewApprovalHandler(approvalService, eventsPublisher)
 	delegationHandler := handlers.NewDelegationHandler(approvalRepo, rbacMiddleware)
 
-	// Start escalation job
-	escalationJob := jobs.NewEscalationJob(approvalRepo, publisher, logger)
-	jobCtx, jobCancel := context.WithCancel(context.Background())
-	go escalationJob.Start(jobCtx)
+	// Start escalation job from the persistent job runner: one replica runs it at a time and
+	// platform owners can pause or trigger it from /api/v1/admin/jobs
+	escalationJob := jobs.NewEscalationJob(approvalRepo, publisher, clients.NewBusinessCalendarClient(), logger)
+	jobRunner := jobqueue.NewRunner(db, logger.WithField("component", "jobqueue"))
+	jobRunner.Register(jobqueue.Job{
+		Name:        "approval_escalation",
+		Description: "Escalates pending approval requests past their escalation timeout and expires timed out ones",
+		Interval:    jobs.EscalationJobInterval,
+		Run:         escalationJob.RunOnce,
+	})
+	if err := jobRunner.Start(); err != nil {
+		logger.Fatalf("Failed to start background job runner: %v", err)
+	}
 	logger.Info("Escalation job started")
 
 	// Initialize Gin router
@@ -183,6 +198,10 @@ func main() {
 		// Used for auto-approval when the requester has sufficient privileges (e.g., store_owner)
 		api.POST("/approvals/:id/approve/internal", approvalHandler.ApproveRequestInternal)
 
+		// Service-to-service endpoint for requesting changes (no RBAC - internal services only)
+		// Used by products-service when a reviewer sends a vendor product back with per-field change requests
+		api.POST("/approvals/:id/request-changes/internal", approvalHandler.RequestChangesRequestInternal)
+
 		// User-facing endpoints
 		api.POST("/approvals", rbacMiddleware.RequirePermission(rbac.PermissionApprovalsCreate), approvalHandler.CreateRequest)
 		api.GET("/approvals/pending", rbacMiddleware.RequirePermission(rbac.PermissionApprovalsRead), approvalHandler.ListPendingRequests)
@@ -202,6 +221,16 @@ func main() {
 		api.GET("/delegations/incoming", delegationHandler.ListDelegatedToMe)
 		api.GET("/delegations/:id", delegationHandler.GetDelegation)
 		api.POST("/delegations/:id/revoke", delegationHandler.RevokeDelegation)
+
+		// Out-of-office delegation: approved time off activates a delegation to the
+		// staff member's out-of-office delegate for the time-off window
+		api.GET("/delegations/out-of-office", delegationHandler.GetOutOfOfficeRule)
+		api.PUT("/delegations/out-of-office", delegationHandler.SetOutOfOfficeRule)
+		api.DELETE("/delegations/out-of-office", delegationHandler.DeleteOutOfOfficeRule)
+		api.GET("/delegations/conflicts", rbacMiddleware.RequirePermission(rbac.PermissionDelegationsRead), delegationHandler.ListDelegationConflicts)
+
+		// Service-to-service endpoint for the staff time-off module to push time-off records
+		api.POST("/delegations/time-off/internal", delegationHandler.SyncTimeOff)
 	}
 
 	// Admin endpoints for workflow management
@@ -212,6 +241,10 @@ func main() {
 		admin.PUT("/approval-workflows/:id", rbacMiddleware.RequirePermission(rbac.PermissionApprovalsManage), approvalHandler.UpdateWorkflow)
 	}
 
+	// Background jobs - platform owners only, since escalation spans every tenant
+	jobsAdmin := admin.Group("", rbacMiddleware.RequireMinPriority(jobqueue.PlatformOwnerPriority))
+	jobqueue.NewHandler(jobRunner).RegisterRoutes(jobsAdmin)
+
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -221,13 +254,18 @@ func main() {
 		port = "8099"
 	}
 
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+
 	// Setup graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		logger.Infof("Approval service starting on port %s", port)
-		if err := router.Run(":" + port); err != nil {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("Failed to start server:", err)
 		}
 	}()
@@ -236,9 +274,15 @@ func main() {
 	<-quit
 	logger.Info("Shutting down server...")
 
+	// Stop accepting connections and let in-flight requests finish
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.WithError(err).Warn("Server forced to shutdown")
+	}
+
 	// Stop escalation job
-	jobCancel()
-	escalationJob.Stop()
+	jobRunner.Stop()
 	logger.Info("Escalation job stopped")
 
 	logger.Info("Server shutdown complete")
@@ -3,12 +3,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets"
+
+	"approval-service/internal/tenancy"
 )
 
 // Config holds all configuration for the service
@@ -18,6 +21,7 @@ type Config struct {
 	DatabaseURL     string
 	StaffServiceURL string
 	NATSURL         string
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
 }
 
 // Load loads configuration from environment variables
@@ -28,6 +32,7 @@ func Load() *Config {
 		DatabaseURL:     getEnv("DATABASE_URL", ""),
 		StaffServiceURL: getEnv("STAFF_SERVICE_URL", "http://staff-service:8080"),
 		NATSURL:         getEnv("NATS_URL", ""),
+		ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
 	}
 }
 
@@ -68,5 +73,19 @@ func InitDB(cfg *Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
+	}
+
 	return db, nil
 }
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
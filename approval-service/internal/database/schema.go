@@ -0,0 +1,32 @@
+package database
+
+import (
+	"gorm.io/gorm"
+
+	"approval-service/internal/jobqueue"
+	"approval-service/internal/models"
+)
+
+// serviceName names the service in the migration lock key and log messages
+const serviceName = "approval-service"
+
+// BaselineVersion is the last SQL migration folded into the model schema. Databases that
+// predate versioned migrations were built by AutoMigrate, and new databases get the same
+// schema from SyncModels, so only files numbered after it are applied from SQL.
+const BaselineVersion = 9
+
+// SyncModels brings the schema in line with the GORM models. It must only run while holding
+// the migration lock.
+func SyncModels(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&models.ApprovalWorkflow{},
+		&models.ApprovalRequest{},
+		&models.ApprovalDecision{},
+		&models.ApprovalAuditLog{},
+		&models.ApprovalDelegation{},
+		&models.StaffTimeOff{},
+		&models.OutOfOfficeRule{},
+		&jobqueue.JobState{},
+		&jobqueue.JobRun{},
+	)
+}
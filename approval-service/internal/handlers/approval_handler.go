@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -95,6 +96,8 @@ func (h *ApprovalHandler) CreateRequest(c *gin.Context) {
 		status := http.StatusInternalServerError
 		if err == services.ErrWorkflowNotFound {
 			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrInvalidBundle) {
+			status = http.StatusBadRequest
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -249,14 +252,17 @@ func (h *ApprovalHandler) ApproveRequest(c *gin.Context) {
 	userRole := c.GetString("user_role")
 
 	var body struct {
-		Comment string `json:"comment"`
+		Comment string   `json:"comment"`
+		ItemIDs []string `json:"itemIds"` // Bundle items to approve; the rest are rejected
 	}
 	_ = c.ShouldBindJSON(&body)
 
-	request, err := h.service.ApproveRequest(c.Request.Context(), id, userID, userRole, actor.ActorName, actor.ActorEmail, body.Comment)
+	request, err := h.service.ApproveRequestItems(c.Request.Context(), id, userID, userRole, actor.ActorName, actor.ActorEmail, body.Comment, body.ItemIDs)
 	if err != nil {
 		status := http.StatusInternalServerError
 		switch err {
+		case services.ErrUnknownBundleItem, services.ErrNotBundle:
+			status = http.StatusBadRequest
 		case services.ErrRequestNotFound:
 			status = http.StatusNotFound
 		case services.ErrUnauthorizedApprover:
@@ -297,14 +303,17 @@ func (h *ApprovalHandler) ApproveRequestInternal(c *gin.Context) {
 	userRole := c.GetString("user_role")
 
 	var body struct {
-		Comment string `json:"comment"`
+		Comment string   `json:"comment"`
+		ItemIDs []string `json:"itemIds"` // Bundle items to approve; the rest are rejected
 	}
 	_ = c.ShouldBindJSON(&body)
 
-	request, err := h.service.ApproveRequest(c.Request.Context(), id, userID, userRole, actor.ActorName, actor.ActorEmail, body.Comment)
+	request, err := h.service.ApproveRequestItems(c.Request.Context(), id, userID, userRole, actor.ActorName, actor.ActorEmail, body.Comment, body.ItemIDs)
 	if err != nil {
 		status := http.StatusInternalServerError
 		switch err {
+		case services.ErrUnknownBundleItem, services.ErrNotBundle:
+			status = http.StatusBadRequest
 		case services.ErrRequestNotFound:
 			status = http.StatusNotFound
 		case services.ErrUnauthorizedApprover:
@@ -436,6 +445,60 @@ func (h *ApprovalHandler) RequestChangesRequest(c *gin.Context) {
 	c.JSON(http.StatusOK, request)
 }
 
+// RequestChangesRequestInternal sends an approval request back to the requester without RBAC check
+// This is used by other services whose reviewers record the requested changes themselves
+// POST /api/v1/approvals/:id/request-changes/internal
+func (h *ApprovalHandler) RequestChangesRequestInternal(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "invalid request id"})
+		return
+	}
+
+	userIDStr := c.GetString("user_id")
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "invalid user_id"})
+		return
+	}
+
+	userRole := c.GetString("user_role")
+
+	var body struct {
+		Comment string `json:"comment" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "comment describing required changes is required"})
+		return
+	}
+
+	request, err := h.service.RequestChanges(c.Request.Context(), id, userID, userRole, body.Comment)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case services.ErrRequestNotFound:
+			status = http.StatusNotFound
+		case services.ErrUnauthorizedApprover:
+			status = http.StatusForbidden
+		case services.ErrRequestAlreadyDecided:
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	// Return wrapped response for service-to-service compatibility
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"id":     request.ID.String(),
+			"status": request.Status,
+		},
+		"message": "Changes requested successfully",
+	})
+}
+
 // CancelRequest cancels an approval request
 // @Summary Cancel request
 // @Tags Approvals
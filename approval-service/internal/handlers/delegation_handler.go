@@ -33,6 +33,8 @@ type CreateDelegationRequest struct {
 	Reason     string     `json:"reason"`
 	StartDate  time.Time  `json:"startDate" binding:"required"`
 	EndDate    time.Time  `json:"endDate" binding:"required"`
+	// AllowConflict creates the delegation even if the delegate is away during it
+	AllowConflict bool `json:"allowConflict"`
 }
 
 // DelegationResponse represents a delegation in API responses
@@ -50,6 +52,10 @@ type DelegationResponse struct {
 	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
 	RevokedBy    *uuid.UUID `json:"revokedBy,omitempty"`
 	RevokeReason string     `json:"revokeReason,omitempty"`
+	Source       string     `json:"source"`
+	TimeOffID    *string    `json:"timeOffId,omitempty"`
+	HasConflict  bool       `json:"hasConflict"`
+	ConflictNote string     `json:"conflictNote,omitempty"`
 	CreatedAt    time.Time  `json:"createdAt"`
 	UpdatedAt    time.Time  `json:"updatedAt"`
 }
@@ -113,15 +119,29 @@ func (h *DelegationHandler) CreateDelegation(c *gin.Context) {
 		return
 	}
 
+	// Check whether the delegate is away too
+	conflictNote, err := h.detectConflict(c.Request.Context(), tenantID, req.DelegateID, req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check delegate time off"})
+		return
+	}
+	if conflictNote != "" && !req.AllowConflict {
+		c.JSON(http.StatusConflict, gin.H{"error": conflictNote})
+		return
+	}
+
 	delegation := &models.ApprovalDelegation{
-		TenantID:    tenantID,
-		DelegatorID: staffID,
-		DelegateID:  req.DelegateID,
-		WorkflowID:  req.WorkflowID,
-		Reason:      req.Reason,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
-		IsActive:    true,
+		TenantID:     tenantID,
+		DelegatorID:  staffID,
+		DelegateID:   req.DelegateID,
+		WorkflowID:   req.WorkflowID,
+		Reason:       req.Reason,
+		StartDate:    req.StartDate,
+		EndDate:      req.EndDate,
+		IsActive:     true,
+		Source:       models.DelegationSourceManual,
+		HasConflict:  conflictNote != "",
+		ConflictNote: conflictNote,
 	}
 
 	if err := h.repo.CreateDelegation(c.Request.Context(), delegation); err != nil {
@@ -309,10 +329,17 @@ func (h *DelegationHandler) createDelegationAuditLog(c *gin.Context, delegation
 		"delegate_id":   delegation.DelegateID,
 		"start_date":    delegation.StartDate,
 		"end_date":      delegation.EndDate,
+		"source":        delegation.Source,
 	}
 	if delegation.WorkflowID != nil {
 		metadata["workflow_id"] = *delegation.WorkflowID
 	}
+	if delegation.TimeOffID != nil {
+		metadata["time_off_id"] = *delegation.TimeOffID
+	}
+	if delegation.HasConflict {
+		metadata["conflict_note"] = delegation.ConflictNote
+	}
 	if delegation.RevokeReason != "" {
 		metadata["revoke_reason"] = delegation.RevokeReason
 	}
@@ -347,6 +374,10 @@ func toDelegationResponse(d *models.ApprovalDelegation) DelegationResponse {
 		RevokedAt:    d.RevokedAt,
 		RevokedBy:    d.RevokedBy,
 		RevokeReason: d.RevokeReason,
+		Source:       d.Source,
+		TimeOffID:    d.TimeOffID,
+		HasConflict:  d.HasConflict,
+		ConflictNote: d.ConflictNote,
 		CreatedAt:    d.CreatedAt,
 		UpdatedAt:    d.UpdatedAt,
 	}
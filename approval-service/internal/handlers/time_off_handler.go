@@ -0,0 +1,383 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"approval-service/internal/models"
+	"approval-service/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// outOfOfficeReason is the reason given on delegations created from time off
+const outOfOfficeReason = "Out of office"
+
+// OutOfOfficeRuleRequest represents a request to set the caller's out-of-office delegate
+type OutOfOfficeRuleRequest struct {
+	DelegateID uuid.UUID  `json:"delegateId" binding:"required"`
+	WorkflowID *uuid.UUID `json:"workflowId,omitempty"`
+	Enabled    *bool      `json:"enabled,omitempty"`
+}
+
+// SyncTimeOffRequest represents a staff time-off record pushed by the staff time-off module
+type SyncTimeOffRequest struct {
+	TimeOffID string    `json:"timeOffId" binding:"required"`
+	StaffID   uuid.UUID `json:"staffId" binding:"required"`
+	StartDate time.Time `json:"startDate" binding:"required"`
+	EndDate   time.Time `json:"endDate" binding:"required"`
+	Status    string    `json:"status" binding:"required,oneof=approved cancelled"`
+}
+
+// SyncTimeOffResponse reports the delegation a time-off record activated, if any, and the
+// delegations to the staff member that now conflict with their time off
+type SyncTimeOffResponse struct {
+	TimeOff    *models.StaffTimeOff `json:"timeOff"`
+	Delegation *DelegationResponse  `json:"delegation,omitempty"`
+	Conflicts  []DelegationResponse `json:"conflicts"`
+}
+
+// GetOutOfOfficeRule returns the caller's out-of-office delegate
+// @Summary Get my out-of-office delegate
+// @Tags Delegations
+// @Produce json
+// @Success 200 {object} models.OutOfOfficeRule
+// @Failure 404 {object} map[string]string
+// @Router /delegations/out-of-office [get]
+func (h *DelegationHandler) GetOutOfOfficeRule(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+	staffID, err := uuid.Parse(c.GetString("staffId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid staff ID"})
+		return
+	}
+
+	rule, err := h.repo.GetOutOfOfficeRule(c.Request.Context(), tenantID, staffID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no out-of-office delegate set"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve out-of-office delegate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// SetOutOfOfficeRule sets who receives the caller's approval authority while they are on
+// time off. Delegations are created for time off that is already synced and hasn't ended.
+// @Summary Set my out-of-office delegate
+// @Tags Delegations
+// @Accept json
+// @Produce json
+// @Param request body OutOfOfficeRuleRequest true "Out-of-office delegate"
+// @Success 200 {object} models.OutOfOfficeRule
+// @Failure 400 {object} map[string]string
+// @Router /delegations/out-of-office [put]
+func (h *DelegationHandler) SetOutOfOfficeRule(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+	staffID, err := uuid.Parse(c.GetString("staffId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid staff ID"})
+		return
+	}
+
+	var req OutOfOfficeRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+	if req.DelegateID == staffID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot delegate to yourself"})
+		return
+	}
+
+	rule := &models.OutOfOfficeRule{
+		TenantID:   tenantID,
+		StaffID:    staffID,
+		DelegateID: req.DelegateID,
+		WorkflowID: req.WorkflowID,
+		Enabled:    req.Enabled == nil || *req.Enabled,
+	}
+	if err := h.repo.SaveOutOfOfficeRule(c.Request.Context(), rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save out-of-office delegate"})
+		return
+	}
+
+	timeOff, err := h.repo.ListUpcomingTimeOff(c.Request.Context(), tenantID, staffID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve time off"})
+		return
+	}
+	for i := range timeOff {
+		if _, err := h.applyTimeOff(c, &timeOff[i]); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delegate for existing time off"})
+			return
+		}
+	}
+
+	rule, err = h.repo.GetOutOfOfficeRule(c.Request.Context(), tenantID, staffID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve out-of-office delegate"})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteOutOfOfficeRule removes the caller's out-of-office delegate. Delegations already
+// created from time off stay until revoked.
+// @Summary Remove my out-of-office delegate
+// @Tags Delegations
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /delegations/out-of-office [delete]
+func (h *DelegationHandler) DeleteOutOfOfficeRule(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+	staffID, err := uuid.Parse(c.GetString("staffId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid staff ID"})
+		return
+	}
+
+	if err := h.repo.DeleteOutOfOfficeRule(c.Request.Context(), tenantID, staffID); err != nil {
+		if err == repository.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no out-of-office delegate set"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove out-of-office delegate"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SyncTimeOff records a staff member's time off from the staff time-off module. Approved
+// time off activates a delegation to their out-of-office delegate for the time-off window;
+// cancelled time off revokes it. Delegations to the staff member are re-checked for conflicts.
+// @Summary Sync staff time off (internal)
+// @Tags Delegations
+// @Accept json
+// @Produce json
+// @Param request body SyncTimeOffRequest true "Time-off record"
+// @Success 200 {object} SyncTimeOffResponse
+// @Failure 400 {object} map[string]string
+// @Router /delegations/time-off/internal [post]
+func (h *DelegationHandler) SyncTimeOff(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	var req SyncTimeOffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.EndDate.After(req.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end date must be after start date"})
+		return
+	}
+
+	timeOff := &models.StaffTimeOff{
+		TenantID:  tenantID,
+		TimeOffID: req.TimeOffID,
+		StaffID:   req.StaffID,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Status:    req.Status,
+		UpdatedAt: time.Now(),
+	}
+	if err := h.repo.UpsertTimeOff(c.Request.Context(), timeOff); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save time off"})
+		return
+	}
+
+	delegation, err := h.applyTimeOff(c, timeOff)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update out-of-office delegation"})
+		return
+	}
+
+	conflicts, err := h.refreshDelegateConflicts(c, tenantID, req.StaffID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check delegation conflicts"})
+		return
+	}
+
+	resp := SyncTimeOffResponse{
+		TimeOff:   timeOff,
+		Conflicts: make([]DelegationResponse, len(conflicts)),
+	}
+	if delegation != nil {
+		d := toDelegationResponse(delegation)
+		resp.Delegation = &d
+	}
+	for i := range conflicts {
+		resp.Conflicts[i] = toDelegationResponse(&conflicts[i])
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListDelegationConflicts lists current and scheduled delegations whose delegate is away
+// during the delegation, so admins can arrange cover
+// @Summary List delegation conflicts
+// @Tags Delegations
+// @Produce json
+// @Success 200 {array} DelegationResponse
+// @Router /delegations/conflicts [get]
+func (h *DelegationHandler) ListDelegationConflicts(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	delegations, err := h.repo.ListDelegationConflicts(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list delegation conflicts"})
+		return
+	}
+
+	responses := make([]DelegationResponse, len(delegations))
+	for i, d := range delegations {
+		responses[i] = toDelegationResponse(&d)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// applyTimeOff brings the delegation for a time-off record in line with the record and the
+// staff member's out-of-office rule. Returns the active delegation, or nil if there is none.
+func (h *DelegationHandler) applyTimeOff(c *gin.Context, timeOff *models.StaffTimeOff) (*models.ApprovalDelegation, error) {
+	ctx := c.Request.Context()
+
+	existing, err := h.repo.GetDelegationByTimeOffID(ctx, timeOff.TenantID, timeOff.TimeOffID)
+	if err != nil && err != repository.ErrNotFound {
+		return nil, err
+	}
+
+	var rule *models.OutOfOfficeRule
+	if timeOff.Status == models.TimeOffStatusApproved && timeOff.EndDate.After(time.Now()) {
+		rule, err = h.repo.GetOutOfOfficeRule(ctx, timeOff.TenantID, timeOff.StaffID)
+		if err != nil && err != repository.ErrNotFound {
+			return nil, err
+		}
+		if rule != nil && !rule.Enabled {
+			rule = nil
+		}
+	}
+
+	// Without a rule, leave delegations alone unless the time off was cancelled
+	if rule == nil {
+		if existing == nil || timeOff.Status != models.TimeOffStatusCancelled {
+			return existing, nil
+		}
+		return nil, h.revokeTimeOffDelegation(c, existing, "time off cancelled")
+	}
+
+	if existing != nil {
+		if existing.DelegateID == rule.DelegateID && sameWorkflow(existing.WorkflowID, rule.WorkflowID) {
+			if err := h.repo.UpdateDelegationWindow(ctx, existing.ID, timeOff.StartDate, timeOff.EndDate); err != nil {
+				return nil, err
+			}
+			existing.StartDate, existing.EndDate = timeOff.StartDate, timeOff.EndDate
+			return existing, h.checkConflict(c, existing)
+		}
+		if err := h.revokeTimeOffDelegation(c, existing, "out-of-office delegate changed"); err != nil {
+			return nil, err
+		}
+	}
+
+	timeOffID := timeOff.TimeOffID
+	delegation := &models.ApprovalDelegation{
+		TenantID:    timeOff.TenantID,
+		DelegatorID: timeOff.StaffID,
+		DelegateID:  rule.DelegateID,
+		WorkflowID:  rule.WorkflowID,
+		Reason:      outOfOfficeReason,
+		StartDate:   timeOff.StartDate,
+		EndDate:     timeOff.EndDate,
+		IsActive:    true,
+		Source:      models.DelegationSourceTimeOff,
+		TimeOffID:   &timeOffID,
+	}
+	if err := h.repo.CreateDelegation(ctx, delegation); err != nil {
+		return nil, err
+	}
+	h.createDelegationAuditLog(c, delegation, models.AuditEventDelegationCreated, timeOff.StaffID)
+
+	return delegation, h.checkConflict(c, delegation)
+}
+
+// revokeTimeOffDelegation revokes a delegation created from time off on the delegator's behalf
+func (h *DelegationHandler) revokeTimeOffDelegation(c *gin.Context, delegation *models.ApprovalDelegation, reason string) error {
+	if err := h.repo.RevokeDelegation(c.Request.Context(), delegation.ID, delegation.DelegatorID, reason); err != nil && err != repository.ErrNotFound {
+		return err
+	}
+	delegation.IsActive = false
+	delegation.RevokeReason = reason
+	h.createDelegationAuditLog(c, delegation, models.AuditEventDelegationRevoked, delegation.DelegatorID)
+	return nil
+}
+
+// refreshDelegateConflicts re-checks the current and scheduled delegations to a staff member
+// against their time off. Returns the delegations that conflict.
+func (h *DelegationHandler) refreshDelegateConflicts(c *gin.Context, tenantID string, delegateID uuid.UUID) ([]models.ApprovalDelegation, error) {
+	delegations, err := h.repo.ListDelegationsByDelegate(c.Request.Context(), tenantID, delegateID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []models.ApprovalDelegation
+	for i := range delegations {
+		if err := h.checkConflict(c, &delegations[i]); err != nil {
+			return nil, err
+		}
+		if delegations[i].HasConflict {
+			conflicts = append(conflicts, delegations[i])
+		}
+	}
+	return conflicts, nil
+}
+
+// checkConflict updates a delegation's conflict flag from its delegate's time off, writing
+// an audit log entry when a new conflict is found
+func (h *DelegationHandler) checkConflict(c *gin.Context, delegation *models.ApprovalDelegation) error {
+	note, err := h.detectConflict(c.Request.Context(), delegation.TenantID, delegation.DelegateID, delegation.StartDate, delegation.EndDate)
+	if err != nil {
+		return err
+	}
+	hasConflict := note != ""
+	if hasConflict == delegation.HasConflict && note == delegation.ConflictNote {
+		return nil
+	}
+
+	if err := h.repo.SetDelegationConflict(c.Request.Context(), delegation.ID, hasConflict, note); err != nil {
+		return err
+	}
+	delegation.HasConflict, delegation.ConflictNote = hasConflict, note
+	if hasConflict {
+		h.createDelegationAuditLog(c, delegation, models.AuditEventDelegationConflict, delegation.DelegatorID)
+	}
+	return nil
+}
+
+// detectConflict describes the delegate's time off overlapping a delegation window, or
+// returns "" when the delegate is available
+func (h *DelegationHandler) detectConflict(ctx context.Context, tenantID string, delegateID uuid.UUID, startDate, endDate time.Time) (string, error) {
+	timeOff, err := h.repo.FindOverlappingTimeOff(ctx, tenantID, delegateID, startDate, endDate)
+	if err != nil || len(timeOff) == 0 {
+		return "", err
+	}
+	awayFrom, awayUntil := timeOff[0].StartDate, timeOff[0].EndDate
+	for _, t := range timeOff[1:] {
+		if t.EndDate.After(awayUntil) {
+			awayUntil = t.EndDate
+		}
+	}
+	return fmt.Sprintf("delegate is away from %s to %s during this delegation",
+		awayFrom.Format(time.RFC3339), awayUntil.Format(time.RFC3339)), nil
+}
+
+func sameWorkflow(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,108 @@
+// Package jobqueue runs a service's recurring background jobs from a persistent schedule.
+//
+// Every replica registers the same jobs, but each run is claimed through the job's row in
+// background_jobs, so a job runs on one replica at a time and keeps its schedule across
+// restarts. Every attempt is recorded in background_job_runs; failed attempts are retried with
+// backoff and a run that exhausts its attempts is kept as a dead letter until an admin retries
+// or dismisses it. Jobs can be paused, resumed and triggered through the admin API.
+//
+// The package is kept identical in every service that uses it; change all copies together.
+package jobqueue
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlatformOwnerPriority is the role priority required for the jobs admin API. Jobs sweep
+// data across all tenants, so only platform owners may pause or trigger them.
+const PlatformOwnerPriority = 200
+
+// RunStatus is the outcome of one attempt of a job
+type RunStatus string
+
+const (
+	RunStatusRunning    RunStatus = "RUNNING"
+	RunStatusSucceeded  RunStatus = "SUCCEEDED"
+	RunStatusFailed     RunStatus = "FAILED"      // Failed, another attempt follows
+	RunStatusDeadLetter RunStatus = "DEAD_LETTER" // Failed on its last attempt
+)
+
+// RunTrigger records what started a run
+type RunTrigger string
+
+const (
+	RunTriggerSchedule RunTrigger = "SCHEDULE"
+	RunTriggerManual   RunTrigger = "MANUAL"
+	RunTriggerRetry    RunTrigger = "RETRY" // A dead letter retried from the admin API
+)
+
+// JobState is the persisted schedule, lease and metrics of a registered job
+type JobState struct {
+	Name             string     `json:"name" gorm:"primaryKey;type:varchar(100)"`
+	Description      string     `json:"description" gorm:"type:text"`
+	IntervalSeconds  int64      `json:"intervalSeconds" gorm:"not null"`
+	Paused           bool       `json:"paused" gorm:"not null;default:false"`
+	PausedBy         *string    `json:"pausedBy,omitempty" gorm:"type:varchar(255)"`
+	PausedAt         *time.Time `json:"pausedAt,omitempty"`
+	NextRunAt        time.Time  `json:"nextRunAt" gorm:"not null"`
+	TriggerRequested bool       `json:"triggerRequested" gorm:"not null;default:false"` // Run at the next poll, even when paused
+	TriggeredBy      *string    `json:"triggeredBy,omitempty" gorm:"type:varchar(255)"`
+	PendingTrigger   RunTrigger `json:"-" gorm:"type:varchar(20)"`
+	LockedBy         *string    `json:"lockedBy,omitempty" gorm:"type:varchar(255)"` // Instance running the job
+	LockedUntil      *time.Time `json:"lockedUntil,omitempty"`
+	LastRunAt        *time.Time `json:"lastRunAt,omitempty"`
+	LastStatus       *RunStatus `json:"lastStatus,omitempty" gorm:"type:varchar(20)"`
+	LastError        *string    `json:"lastError,omitempty" gorm:"type:text"`
+	LastDurationMs   int64      `json:"lastDurationMs"`
+	RunCount         int64      `json:"runCount" gorm:"not null;default:0"` // Attempts, including retries
+	SuccessCount     int64      `json:"successCount" gorm:"not null;default:0"`
+	FailureCount     int64      `json:"failureCount" gorm:"not null;default:0"`
+	DeadLetterCount  int64      `json:"deadLetterCount" gorm:"not null;default:0"`
+	TotalDurationMs  int64      `json:"totalDurationMs" gorm:"not null;default:0"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+}
+
+// TableName returns the table name for GORM
+func (JobState) TableName() string {
+	return "background_jobs"
+}
+
+// JobRun is one attempt of a job
+type JobRun struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	JobName     string     `json:"jobName" gorm:"type:varchar(100);not null;index:idx_background_job_runs_job"`
+	Status      RunStatus  `json:"status" gorm:"type:varchar(20);not null;index"`
+	Trigger     RunTrigger `json:"trigger" gorm:"type:varchar(20);not null"`
+	TriggeredBy *string    `json:"triggeredBy,omitempty" gorm:"type:varchar(255)"`
+	Attempt     int        `json:"attempt" gorm:"not null"`
+	Instance    string     `json:"instance" gorm:"type:varchar(255)"`
+	Error       *string    `json:"error,omitempty" gorm:"type:text"`
+	StartedAt   time.Time  `json:"startedAt" gorm:"not null;index:idx_background_job_runs_job"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+	DurationMs  int64      `json:"durationMs"`
+	ResolvedAt  *time.Time `json:"resolvedAt,omitempty"` // Dead letters: when an admin retried or dismissed it
+	ResolvedBy  *string    `json:"resolvedBy,omitempty" gorm:"type:varchar(255)"`
+}
+
+// TableName returns the table name for GORM
+func (JobRun) TableName() string {
+	return "background_job_runs"
+}
+
+// JobSummary is a job as returned by the admin API
+type JobSummary struct {
+	JobState
+	Registered        bool    `json:"registered"` // False for jobs this version no longer runs
+	Running           bool    `json:"running"`
+	AverageDurationMs int64   `json:"averageDurationMs"`
+	SuccessRate       float64 `json:"successRate"` // Share of attempts that succeeded, 0-1
+}
+
+// JobDetail is a job with its most recent runs
+type JobDetail struct {
+	JobSummary
+	RecentRuns []JobRun `json:"recentRuns"`
+}
@@ -3,77 +3,53 @@ package jobs
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"approval-service/internal/clients"
 	"approval-service/internal/models"
 	"approval-service/internal/repository"
 	"github.com/Tesseract-Nexus/go-shared/events"
 )
 
-// EscalationJob handles automatic escalation of pending approval requests
+// EscalationJobInterval is how often pending requests are checked for escalation
+const EscalationJobInterval = 15 * time.Minute
+
+// EscalationJob handles automatic escalation of pending approval requests. It is scheduled by
+// the background job runner.
 type EscalationJob struct {
 	repo      *repository.ApprovalRepository
 	publisher *events.Publisher
+	calendars *clients.BusinessCalendarClient
 	logger    *logrus.Logger
-	interval  time.Duration
-	stopCh    chan struct{}
 }
 
-// NewEscalationJob creates a new escalation job
-func NewEscalationJob(repo *repository.ApprovalRepository, publisher *events.Publisher, logger *logrus.Logger) *EscalationJob {
+// NewEscalationJob creates a new escalation job. Escalation timers count only the tenant's
+// business hours when calendars is set and the tenant has a business calendar.
+func NewEscalationJob(repo *repository.ApprovalRepository, publisher *events.Publisher, calendars *clients.BusinessCalendarClient, logger *logrus.Logger) *EscalationJob {
 	return &EscalationJob{
 		repo:      repo,
 		publisher: publisher,
+		calendars: calendars,
 		logger:    logger,
-		interval:  15 * time.Minute, // Check every 15 minutes
-		stopCh:    make(chan struct{}),
-	}
-}
-
-// Start begins the escalation job
-func (j *EscalationJob) Start(ctx context.Context) {
-	j.logger.Info("Escalation job started")
-
-	ticker := time.NewTicker(j.interval)
-	defer ticker.Stop()
-
-	// Run immediately on start
-	j.runEscalationCheck(ctx)
-
-	for {
-		select {
-		case <-ticker.C:
-			j.runEscalationCheck(ctx)
-		case <-j.stopCh:
-			j.logger.Info("Escalation job stopped")
-			return
-		case <-ctx.Done():
-			j.logger.Info("Escalation job context cancelled")
-			return
-		}
 	}
 }
 
-// Stop signals the job to stop
-func (j *EscalationJob) Stop() {
-	close(j.stopCh)
-}
-
-// runEscalationCheck finds and escalates pending requests
-func (j *EscalationJob) runEscalationCheck(ctx context.Context) {
+// RunOnce finds and escalates pending requests, then expires timed out ones. It fails only
+// when the requests can't be loaded; a request that fails to escalate is retried next run.
+func (j *EscalationJob) RunOnce(ctx context.Context) error {
 	j.logger.Debug("Running escalation check...")
 
 	// Find pending requests that need escalation
-	requests, err := j.repo.FindRequestsNeedingEscalation(ctx)
+	requests, err := j.repo.FindRequestsNeedingEscalation(ctx, j.businessTimeSince)
 	if err != nil {
-		j.logger.Errorf("Failed to find requests needing escalation: %v", err)
-		return
+		return fmt.Errorf("failed to find requests needing escalation: %w", err)
 	}
 
 	if len(requests) == 0 {
 		j.logger.Debug("No requests need escalation")
-		return
+		return j.expireTimedOutRequests(ctx)
 	}
 
 	j.logger.Infof("Found %d requests needing escalation", len(requests))
@@ -87,7 +63,21 @@ func (j *EscalationJob) runEscalationCheck(ctx context.Context) {
 	}
 
 	// Also check for expired requests
-	j.expireTimedOutRequests(ctx)
+	return j.expireTimedOutRequests(ctx)
+}
+
+// businessTimeSince returns the tenant's business time since from. Falls back to wall-clock
+// time when the tenant has no calendar or it can't be fetched.
+func (j *EscalationJob) businessTimeSince(ctx context.Context, tenantID string, from time.Time) time.Duration {
+	if j.calendars == nil {
+		return time.Since(from)
+	}
+	calendar, err := j.calendars.Resolve(ctx, tenantID, "")
+	if err != nil {
+		j.logger.Warnf("Failed to fetch business calendar for tenant %s, using wall-clock time: %v", tenantID, err)
+		return time.Since(from)
+	}
+	return time.Duration(calendar.ElapsedBusinessMinutes(from, time.Now())) * time.Minute
 }
 
 // escalateRequest escalates a single request to the next level
@@ -151,16 +141,16 @@ func (j *EscalationJob) escalateRequest(ctx context.Context, request *models.App
 }
 
 // expireTimedOutRequests marks requests as expired if they've exceeded their timeout
-func (j *EscalationJob) expireTimedOutRequests(ctx context.Context) {
+func (j *EscalationJob) expireTimedOutRequests(ctx context.Context) error {
 	expired, err := j.repo.ExpireTimedOutRequests(ctx)
 	if err != nil {
-		j.logger.Errorf("Failed to expire timed out requests: %v", err)
-		return
+		return fmt.Errorf("failed to expire timed out requests: %w", err)
 	}
 
 	if expired > 0 {
 		j.logger.Infof("Expired %d timed out approval requests", expired)
 	}
+	return nil
 }
 
 // createEscalationAuditLog creates an audit log entry for the escalation
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"approval-service/internal/tenancy"
 )
 
 // TenantMiddleware extracts tenant ID from headers
@@ -25,6 +27,8 @@ func TenantMiddleware() gin.HandlerFunc {
 		}
 
 		c.Set("tenant_id", tenantID)
+		// Scope database statements made with the request context (see internal/tenancy)
+		c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), tenantID))
 		c.Next()
 	}
 }
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+)
+
+// MaxBundleItems bounds the items a single bundled approval request can cover
+const MaxBundleItems = 500
+
+// Bundle item outcomes
+const (
+	ItemOutcomePending  = "pending"
+	ItemOutcomeApproved = "approved"
+	ItemOutcomeRejected = "rejected"
+)
+
+// BundleItem is one item of a bulk operation covered by a bundled approval request,
+// e.g. one product of a bulk price change. Approvers can approve a subset of the items;
+// the decision event carries every item's outcome.
+type BundleItem struct {
+	ID         string                 `json:"id"`                   // Caller's key for the item, unique within the bundle
+	ResourceID string                 `json:"resourceId,omitempty"` // Resource the item acts on
+	Label      string                 `json:"label,omitempty"`      // Shown to approvers, e.g. the product name
+	Data       map[string]interface{} `json:"data,omitempty"`       // Item-specific action data, e.g. old and new price
+	Outcome    string                 `json:"outcome"`
+}
+
+// IsBundle reports whether the request covers several items of a bulk operation
+func (r *ApprovalRequest) IsBundle() bool {
+	return len(r.Items) > 0 && string(r.Items) != "null"
+}
+
+// BundleItems returns the items of a bundled request, or nil for a single-item request
+func (r *ApprovalRequest) BundleItems() ([]BundleItem, error) {
+	if !r.IsBundle() {
+		return nil, nil
+	}
+	var items []BundleItem
+	if err := json.Unmarshal(r.Items, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
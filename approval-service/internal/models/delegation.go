@@ -20,6 +20,10 @@ type ApprovalDelegation struct {
 	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
 	RevokedBy    *uuid.UUID `gorm:"type:uuid" json:"revokedBy,omitempty"`
 	RevokeReason string     `gorm:"type:text" json:"revokeReason,omitempty"`
+	Source       string     `gorm:"type:varchar(20);not null;default:'manual'" json:"source"`
+	TimeOffID    *string    `gorm:"type:varchar(255);index" json:"timeOffId,omitempty"`
+	HasConflict  bool       `gorm:"default:false" json:"hasConflict"`
+	ConflictNote string     `gorm:"type:text" json:"conflictNote,omitempty"`
 	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
 }
@@ -69,8 +73,16 @@ func (d *ApprovalDelegation) GetStatus() string {
 	return DelegationStatusActive
 }
 
+// Delegation sources: created by the delegator, or from their out-of-office rule when
+// their time off is synced
+const (
+	DelegationSourceManual  = "manual"
+	DelegationSourceTimeOff = "time_off"
+)
+
 // AuditEventDelegated is the audit event type for delegation creation
 const (
-	AuditEventDelegationCreated = "delegation_created"
-	AuditEventDelegationRevoked = "delegation_revoked"
+	AuditEventDelegationCreated  = "delegation_created"
+	AuditEventDelegationRevoked  = "delegation_revoked"
+	AuditEventDelegationConflict = "delegation_conflict"
 )
@@ -24,6 +24,9 @@ type ApprovalRequest struct {
 	ResourceType string         `gorm:"type:varchar(50)" json:"resourceType,omitempty"`
 	ResourceID   *uuid.UUID     `gorm:"type:uuid" json:"resourceId,omitempty"`
 
+	// Bulk operations: one request covering several items, each with its own outcome
+	Items datatypes.JSON `gorm:"type:jsonb" json:"items,omitempty"`
+
 	// Request context
 	Reason   string `gorm:"type:text" json:"reason,omitempty"`
 	Priority string `gorm:"type:varchar(20);default:'normal'" json:"priority"`
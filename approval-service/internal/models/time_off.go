@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Time off statuses synced from the staff time-off module
+const (
+	TimeOffStatusApproved  = "approved"
+	TimeOffStatusCancelled = "cancelled"
+)
+
+// StaffTimeOff is a staff member's time off, synced from the staff time-off module.
+// Approved time off activates the staff member's out-of-office delegation and marks
+// delegations to them as conflicting.
+type StaffTimeOff struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID  string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_staff_time_off_record" json:"tenantId"`
+	TimeOffID string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_staff_time_off_record" json:"timeOffId"` // ID in the staff time-off module
+	StaffID   uuid.UUID `gorm:"type:uuid;not null;index" json:"staffId"`
+	StartDate time.Time `gorm:"not null" json:"startDate"`
+	EndDate   time.Time `gorm:"not null" json:"endDate"`
+	Status    string    `gorm:"type:varchar(20);not null" json:"status"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName returns the table name for StaffTimeOff
+func (StaffTimeOff) TableName() string {
+	return "approval_staff_time_off"
+}
+
+// OutOfOfficeRule names who receives a staff member's approval authority while they are
+// on time off. Each synced time off creates a delegation for its window.
+type OutOfOfficeRule struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID   string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_out_of_office_staff" json:"tenantId"`
+	StaffID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_out_of_office_staff" json:"staffId"`
+	DelegateID uuid.UUID  `gorm:"type:uuid;not null" json:"delegateId"`
+	WorkflowID *uuid.UUID `gorm:"type:uuid" json:"workflowId,omitempty"` // Optional: specific workflow, null = all workflows
+	Enabled    bool       `gorm:"default:true" json:"enabled"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName returns the table name for OutOfOfficeRule
+func (OutOfOfficeRule) TableName() string {
+	return "approval_out_of_office_rules"
+}
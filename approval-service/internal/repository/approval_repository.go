@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"approval-service/internal/models"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -63,6 +64,7 @@ type ApprovalRepositoryInterface interface {
 	ListPendingRequests(ctx context.Context, tenantID string, approverRole string, statusFilter string, limit, offset int) ([]models.ApprovalRequest, int64, error)
 	ListRequestsByRequester(ctx context.Context, tenantID string, requesterID uuid.UUID, limit, offset int) ([]models.ApprovalRequest, int64, error)
 	UpdateRequestStatus(ctx context.Context, request *models.ApprovalRequest, newStatus string) error
+	UpdateBundleItems(ctx context.Context, requestID uuid.UUID, items datatypes.JSON) error
 	HasPendingApprovalForResource(ctx context.Context, tenantID string, resourceType string, resourceID uuid.UUID, actionType string) (bool, *models.ApprovalRequest, error)
 
 	// Decision methods
@@ -252,6 +254,13 @@ func (r *ApprovalRepository) UpdateRequestStatus(ctx context.Context, request *m
 	return nil
 }
 
+// UpdateBundleItems saves the per-item outcomes of a bundled request
+func (r *ApprovalRepository) UpdateBundleItems(ctx context.Context, requestID uuid.UUID, items datatypes.JSON) error {
+	return r.db.WithContext(ctx).Model(&models.ApprovalRequest{}).
+		Where("id = ?", requestID).
+		Update("items", items).Error
+}
+
 // UpdateRequestWithLock updates a request with optimistic locking
 func (r *ApprovalRepository) UpdateRequestWithLock(ctx context.Context, request *models.ApprovalRequest) error {
 	oldVersion := request.Version
@@ -364,9 +373,14 @@ func (r *ApprovalRepository) UpdateWorkflow(ctx context.Context, workflow *model
 	return nil
 }
 
+// ElapsedFunc returns how much of the time since from counts toward a tenant's escalation
+// timers, e.g. only the tenant's business hours
+type ElapsedFunc func(ctx context.Context, tenantID string, from time.Time) time.Duration
+
 // FindRequestsNeedingEscalation finds pending requests that need escalation
-// based on their workflow's escalation configuration
-func (r *ApprovalRepository) FindRequestsNeedingEscalation(ctx context.Context) ([]models.ApprovalRequest, error) {
+// based on their workflow's escalation configuration. elapsed measures the time since
+// creation or the last escalation; nil uses wall-clock time.
+func (r *ApprovalRepository) FindRequestsNeedingEscalation(ctx context.Context, elapsed ElapsedFunc) ([]models.ApprovalRequest, error) {
 	var requests []models.ApprovalRequest
 
 	// Find pending requests with escalation-enabled workflows
@@ -414,7 +428,11 @@ func (r *ApprovalRepository) FindRequestsNeedingEscalation(ctx context.Context)
 			referenceTime = req.CreatedAt
 		}
 
-		if time.Since(referenceTime) >= escalationThreshold {
+		waited := time.Since(referenceTime)
+		if elapsed != nil {
+			waited = elapsed(ctx, req.TenantID, referenceTime)
+		}
+		if waited >= escalationThreshold {
 			needsEscalation = append(needsEscalation, req)
 		}
 	}
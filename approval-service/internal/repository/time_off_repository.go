@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"approval-service/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// --- Time Off Methods ---
+
+// UpsertTimeOff creates or updates a synced time-off record, keyed by its time-off module ID
+func (r *ApprovalRepository) UpsertTimeOff(ctx context.Context, timeOff *models.StaffTimeOff) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "time_off_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"staff_id", "start_date", "end_date", "status", "updated_at"}),
+	}).Create(timeOff).Error
+}
+
+// FindOverlappingTimeOff finds a staff member's approved time off overlapping a window
+func (r *ApprovalRepository) FindOverlappingTimeOff(ctx context.Context, tenantID string, staffID uuid.UUID, startDate, endDate time.Time) ([]models.StaffTimeOff, error) {
+	var timeOff []models.StaffTimeOff
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND staff_id = ? AND status = ?", tenantID, staffID, models.TimeOffStatusApproved).
+		Where("start_date < ? AND end_date > ?", endDate, startDate).
+		Order("start_date ASC").
+		Find(&timeOff).Error
+	return timeOff, err
+}
+
+// ListUpcomingTimeOff lists a staff member's approved time off that hasn't ended
+func (r *ApprovalRepository) ListUpcomingTimeOff(ctx context.Context, tenantID string, staffID uuid.UUID) ([]models.StaffTimeOff, error) {
+	var timeOff []models.StaffTimeOff
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND staff_id = ? AND status = ?", tenantID, staffID, models.TimeOffStatusApproved).
+		Where("end_date > ?", time.Now()).
+		Order("start_date ASC").
+		Find(&timeOff).Error
+	return timeOff, err
+}
+
+// --- Out-of-Office Rule Methods ---
+
+// GetOutOfOfficeRule retrieves a staff member's out-of-office rule
+func (r *ApprovalRepository) GetOutOfOfficeRule(ctx context.Context, tenantID string, staffID uuid.UUID) (*models.OutOfOfficeRule, error) {
+	var rule models.OutOfOfficeRule
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND staff_id = ?", tenantID, staffID).
+		First(&rule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// SaveOutOfOfficeRule creates or replaces a staff member's out-of-office rule
+func (r *ApprovalRepository) SaveOutOfOfficeRule(ctx context.Context, rule *models.OutOfOfficeRule) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "staff_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"delegate_id", "workflow_id", "enabled", "updated_at"}),
+	}).Create(rule).Error
+}
+
+// DeleteOutOfOfficeRule deletes a staff member's out-of-office rule
+func (r *ApprovalRepository) DeleteOutOfOfficeRule(ctx context.Context, tenantID string, staffID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND staff_id = ?", tenantID, staffID).
+		Delete(&models.OutOfOfficeRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// --- Time Off Delegation Methods ---
+
+// GetDelegationByTimeOffID retrieves the active delegation created for a time-off record
+func (r *ApprovalRepository) GetDelegationByTimeOffID(ctx context.Context, tenantID, timeOffID string) (*models.ApprovalDelegation, error) {
+	var delegation models.ApprovalDelegation
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND time_off_id = ? AND is_active = ?", tenantID, timeOffID, true).
+		Where("revoked_at IS NULL").
+		First(&delegation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &delegation, nil
+}
+
+// UpdateDelegationWindow moves a delegation's activation window
+func (r *ApprovalRepository) UpdateDelegationWindow(ctx context.Context, id uuid.UUID, startDate, endDate time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.ApprovalDelegation{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"start_date": startDate,
+			"end_date":   endDate,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// SetDelegationConflict records whether a delegation's delegate is away during its window
+func (r *ApprovalRepository) SetDelegationConflict(ctx context.Context, id uuid.UUID, hasConflict bool, note string) error {
+	return r.db.WithContext(ctx).Model(&models.ApprovalDelegation{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"has_conflict":  hasConflict,
+			"conflict_note": note,
+			"updated_at":    time.Now(),
+		}).Error
+}
+
+// ListDelegationConflicts lists a tenant's current and scheduled delegations whose delegate
+// is away during the delegation window
+func (r *ApprovalRepository) ListDelegationConflicts(ctx context.Context, tenantID string) ([]models.ApprovalDelegation, error) {
+	var delegations []models.ApprovalDelegation
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND has_conflict = ? AND is_active = ?", tenantID, true, true).
+		Where("revoked_at IS NULL AND end_date > ?", time.Now()).
+		Order("start_date ASC").
+		Find(&delegations).Error
+	return delegations, err
+}
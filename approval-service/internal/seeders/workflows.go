@@ -61,6 +61,31 @@ func SeedSystemWorkflows(db *gorm.DB) error {
 			IsSystem: true,
 			IsActive: true,
 		},
+		// Inventory Adjustment Approval
+		// inventory-service only raises requests for adjustments above the tenant's thresholds
+		{
+			TenantID:    "system",
+			Name:        "inventory_adjustment",
+			DisplayName: "Inventory Adjustment Approval",
+			Description: "Approval workflow for manual stock adjustments above the tenant's quantity or value thresholds",
+			TriggerType: "always",
+			TriggerConfig: datatypes.JSON(`{}`),
+			ApproverConfig: datatypes.JSON(`{
+				"approver_role": "manager",
+				"require_different_user": true,
+				"require_active_staff": true
+			}`),
+			TimeoutHours: 72,
+			EscalationConfig: datatypes.JSON(`{
+				"enabled": true,
+				"levels": [
+					{"after_hours": 24, "escalate_to_role": "admin"},
+					{"after_hours": 48, "escalate_to_role": "owner"}
+				]
+			}`),
+			IsSystem: true,
+			IsActive: true,
+		},
 	}
 
 	for _, workflow := range workflows {
@@ -66,6 +66,8 @@ type CreateRequestInput struct {
 	Reason          string                 `json:"reason,omitempty"`
 	Priority        string                 `json:"priority,omitempty"`
 	RequesterName   string                 `json:"requesterName,omitempty"`
+	// Items bundles the items of a bulk operation into this one request
+	Items []BundleItemInput `json:"items,omitempty"`
 }
 
 // CheckApproval checks if an action requires approval
@@ -128,6 +130,15 @@ func (s *ApprovalService) CreateRequest(ctx context.Context, tenantID string, re
 	// Generate execution ID for idempotency
 	executionID := uuid.New()
 
+	// Bundle the items of a bulk operation
+	var items datatypes.JSON
+	if len(input.Items) > 0 {
+		items, err = buildBundleItems(input.Items)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Parse ResourceID from string to UUID if provided
 	var resourceID *uuid.UUID
 	if input.ResourceID != "" {
@@ -160,6 +171,7 @@ func (s *ApprovalService) CreateRequest(ctx context.Context, tenantID string, re
 		ActionData:          datatypes.JSON(actionDataJSON),
 		ResourceType:        input.ResourceType,
 		ResourceID:          resourceID,
+		Items:               items,
 		Reason:              input.Reason,
 		Priority:            priority,
 		CurrentApproverRole: result.RequiredRole,
@@ -180,10 +192,17 @@ func (s *ApprovalService) CreateRequest(ctx context.Context, tenantID string, re
 	return request, nil
 }
 
-// ApproveRequest approves an approval request
-// Fix #6: Wrapped in transaction for atomicity
+// ApproveRequest approves an approval request, and all items of a bundle
 func (s *ApprovalService) ApproveRequest(ctx context.Context, requestID uuid.UUID, approverID uuid.UUID, approverRole string, approverName string, approverEmail string, comment string) (*models.ApprovalRequest, error) {
+	return s.ApproveRequestItems(ctx, requestID, approverID, approverRole, approverName, approverEmail, comment, nil)
+}
+
+// ApproveRequestItems approves an approval request. For a bundle, itemIDs approves a subset
+// of its items and rejects the rest; no itemIDs approves every item.
+// Fix #6: Wrapped in transaction for atomicity
+func (s *ApprovalService) ApproveRequestItems(ctx context.Context, requestID uuid.UUID, approverID uuid.UUID, approverRole string, approverName string, approverEmail string, comment string, itemIDs []string) (*models.ApprovalRequest, error) {
 	var request *models.ApprovalRequest
+	var outcome *bundleOutcome
 	var delegatedFrom *uuid.UUID
 	var actualRole string
 
@@ -201,6 +220,10 @@ func (s *ApprovalService) ApproveRequest(ctx context.Context, requestID uuid.UUI
 		return nil, ErrRequestAlreadyDecided
 	}
 
+	if len(itemIDs) > 0 && !request.IsBundle() {
+		return nil, ErrNotBundle
+	}
+
 	// Check self-approval based on workflow configuration
 	if request.RequesterID == approverID {
 		// Check if workflow allows self-approval
@@ -269,6 +292,20 @@ func (s *ApprovalService) ApproveRequest(ctx context.Context, requestID uuid.UUI
 			Comment:      comment,
 		}
 
+		// Record each bundled item's outcome
+		if txRequest.IsBundle() {
+			items, bundleResult, err := decideBundle(txRequest, models.DecisionApproved, itemIDs)
+			if err != nil {
+				return err
+			}
+			if err := txRepo.UpdateBundleItems(ctx, txRequest.ID, items); err != nil {
+				return fmt.Errorf("failed to update bundle items: %w", err)
+			}
+			txRequest.Items = items
+			outcome = bundleResult
+			decision.Conditions, _ = json.Marshal(bundleResult)
+		}
+
 		if err := txRepo.CreateDecision(ctx, decision); err != nil {
 			return fmt.Errorf("failed to create decision: %w", err)
 		}
@@ -295,6 +332,10 @@ func (s *ApprovalService) ApproveRequest(ctx context.Context, requestID uuid.UUI
 		metadata["delegated_from"] = delegatedFrom.String()
 		metadata["via_delegation"] = true
 	}
+	if outcome != nil {
+		metadata["approved_items"] = len(outcome.Approved)
+		metadata["rejected_items"] = len(outcome.Rejected)
+	}
 	s.createAuditLog(ctx, request, models.AuditEventApproved, &approverID, metadata)
 
 	// Publish approval.granted event
@@ -363,6 +404,18 @@ func (s *ApprovalService) RejectRequest(ctx context.Context, requestID uuid.UUID
 			Comment:      comment,
 		}
 
+		// Reject every bundled item
+		if txRequest.IsBundle() {
+			items, _, err := decideBundle(txRequest, models.DecisionRejected, nil)
+			if err != nil {
+				return err
+			}
+			if err := txRepo.UpdateBundleItems(ctx, txRequest.ID, items); err != nil {
+				return fmt.Errorf("failed to update bundle items: %w", err)
+			}
+			txRequest.Items = items
+		}
+
 		if err := txRepo.CreateDecision(ctx, decision); err != nil {
 			return fmt.Errorf("failed to create decision: %w", err)
 		}
@@ -927,7 +980,7 @@ func (s *ApprovalService) publishApprovalEvent(ctx context.Context, eventType st
 	if len(request.ActionData) > 0 {
 		var actionData map[string]interface{}
 		if err := json.Unmarshal(request.ActionData, &actionData); err == nil {
-			event.ActionData = actionData
+			event.ActionData = bundleEventData(request, actionData)
 
 			// Fallback: extract ResourceID from actionData if not set on request
 			// This handles legacy approval requests created before ResourceID fix
@@ -88,6 +88,11 @@ func (m *MockApprovalRepository) UpdateRequestStatus(ctx context.Context, reques
 	return args.Error(0)
 }
 
+func (m *MockApprovalRepository) UpdateBundleItems(ctx context.Context, requestID uuid.UUID, items datatypes.JSON) error {
+	args := m.Called(ctx, requestID, items)
+	return args.Error(0)
+}
+
 func (m *MockApprovalRepository) CreateDecision(ctx context.Context, decision *models.ApprovalDecision) error {
 	args := m.Called(ctx, decision)
 	return args.Error(0)
@@ -610,6 +615,119 @@ func TestApproveRequest_ViaDelegation(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// ===========================================
+// Bundle Tests
+// ===========================================
+
+// Helper function to create a test bundle request
+func createTestBundleRequest(tenantID string, workflowID uuid.UUID, requesterID uuid.UUID, itemIDs ...string) *models.ApprovalRequest {
+	inputs := make([]BundleItemInput, len(itemIDs))
+	for i, id := range itemIDs {
+		inputs[i] = BundleItemInput{ID: id, Label: "Product " + id}
+	}
+	items, _ := buildBundleItems(inputs)
+
+	request := createTestRequest(tenantID, workflowID, requesterID)
+	request.ActionType = "product.bulk_price_change"
+	request.Items = items
+	return request
+}
+
+func TestBuildBundleItems_DuplicateID(t *testing.T) {
+	_, err := buildBundleItems([]BundleItemInput{{ID: "p1"}, {ID: "p1"}})
+
+	assert.ErrorIs(t, err, ErrInvalidBundle)
+}
+
+func TestApproveRequestItems_PartialApproval(t *testing.T) {
+	ctx := context.Background()
+	tenantID := "tenant-123"
+	approverID := uuid.New()
+	requesterID := uuid.New()
+	workflowID := uuid.New()
+
+	mockRepo := new(MockApprovalRepository)
+	service := &ApprovalService{repo: mockRepo}
+
+	request := createTestBundleRequest(tenantID, workflowID, requesterID, "p1", "p2", "p3")
+
+	mockRepo.On("GetRequestByID", ctx, request.ID).
+		Return(request, nil)
+	mockRepo.On("UpdateBundleItems", ctx, request.ID, mock.AnythingOfType("datatypes.JSON")).
+		Return(nil)
+	mockRepo.On("CreateDecision", ctx, mock.AnythingOfType("*models.ApprovalDecision")).
+		Return(nil)
+	mockRepo.On("UpdateRequestStatus", ctx, request, models.StatusApproved).
+		Return(nil)
+	mockRepo.On("CreateAuditLog", ctx, mock.AnythingOfType("*models.ApprovalAuditLog")).
+		Return(nil)
+
+	result, err := service.ApproveRequestItems(ctx, request.ID, approverID, "manager", "Test Approver", "approver@test.com", "Only p1 and p3", []string{"p1", "p3"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, result.Status)
+
+	items, err := result.BundleItems()
+	assert.NoError(t, err)
+	outcomes := map[string]string{}
+	for _, item := range items {
+		outcomes[item.ID] = item.Outcome
+	}
+	assert.Equal(t, map[string]string{
+		"p1": models.ItemOutcomeApproved,
+		"p2": models.ItemOutcomeRejected,
+		"p3": models.ItemOutcomeApproved,
+	}, outcomes)
+
+	actionData := bundleEventData(result, map[string]interface{}{})
+	assert.Equal(t, []string{"p1", "p3"}, actionData["approvedItemIds"])
+	assert.Equal(t, []string{"p2"}, actionData["rejectedItemIds"])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestApproveRequestItems_UnknownItem(t *testing.T) {
+	ctx := context.Background()
+	tenantID := "tenant-123"
+	approverID := uuid.New()
+	requesterID := uuid.New()
+	workflowID := uuid.New()
+
+	mockRepo := new(MockApprovalRepository)
+	service := &ApprovalService{repo: mockRepo}
+
+	request := createTestBundleRequest(tenantID, workflowID, requesterID, "p1", "p2")
+
+	mockRepo.On("GetRequestByID", ctx, request.ID).
+		Return(request, nil)
+
+	result, err := service.ApproveRequestItems(ctx, request.ID, approverID, "manager", "Test Approver", "approver@test.com", "", []string{"p9"})
+
+	assert.ErrorIs(t, err, ErrUnknownBundleItem)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "CreateDecision", mock.Anything, mock.Anything)
+}
+
+func TestApproveRequestItems_NotBundle(t *testing.T) {
+	ctx := context.Background()
+	tenantID := "tenant-123"
+	approverID := uuid.New()
+	requesterID := uuid.New()
+	workflowID := uuid.New()
+
+	mockRepo := new(MockApprovalRepository)
+	service := &ApprovalService{repo: mockRepo}
+
+	request := createTestRequest(tenantID, workflowID, requesterID)
+
+	mockRepo.On("GetRequestByID", ctx, request.ID).
+		Return(request, nil)
+
+	result, err := service.ApproveRequestItems(ctx, request.ID, approverID, "manager", "Test Approver", "approver@test.com", "", []string{"p1"})
+
+	assert.ErrorIs(t, err, ErrNotBundle)
+	assert.Nil(t, result)
+}
+
 // ===========================================
 // List Requests Tests
 // ===========================================
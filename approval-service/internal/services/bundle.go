@@ -0,0 +1,128 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"approval-service/internal/models"
+	"gorm.io/datatypes"
+)
+
+var (
+	ErrInvalidBundle     = errors.New("invalid bundle items")
+	ErrUnknownBundleItem = errors.New("unknown bundle item")
+	ErrNotBundle         = errors.New("request is not a bundle; items cannot be selected")
+)
+
+// BundleItemInput is one item of a bulk operation submitted for approval in a bundle
+type BundleItemInput struct {
+	ID         string                 `json:"id"`
+	ResourceID string                 `json:"resourceId,omitempty"`
+	Label      string                 `json:"label,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+}
+
+// bundleOutcome summarizes a decision on a bundle
+type bundleOutcome struct {
+	Approved []string `json:"approvedItemIds"`
+	Rejected []string `json:"rejectedItemIds"`
+}
+
+// buildBundleItems validates bundle items and marshals them with pending outcomes
+func buildBundleItems(inputs []BundleItemInput) (datatypes.JSON, error) {
+	if len(inputs) > models.MaxBundleItems {
+		return nil, fmt.Errorf("%w: at most %d items per bundle", ErrInvalidBundle, models.MaxBundleItems)
+	}
+
+	seen := make(map[string]bool, len(inputs))
+	items := make([]models.BundleItem, len(inputs))
+	for i, input := range inputs {
+		if input.ID == "" {
+			return nil, fmt.Errorf("%w: item %d has no id", ErrInvalidBundle, i)
+		}
+		if seen[input.ID] {
+			return nil, fmt.Errorf("%w: duplicate item id %q", ErrInvalidBundle, input.ID)
+		}
+		seen[input.ID] = true
+		items[i] = models.BundleItem{
+			ID:         input.ID,
+			ResourceID: input.ResourceID,
+			Label:      input.Label,
+			Data:       input.Data,
+			Outcome:    models.ItemOutcomePending,
+		}
+	}
+
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle items: %w", err)
+	}
+	return datatypes.JSON(itemsJSON), nil
+}
+
+// decideBundle sets the outcome of every item of a bundle. An approval approves the
+// selected items, or all items when none are selected, and rejects the rest; a rejection
+// rejects every item.
+func decideBundle(request *models.ApprovalRequest, decision string, selectedIDs []string) (datatypes.JSON, *bundleOutcome, error) {
+	items, err := request.BundleItems()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read bundle items: %w", err)
+	}
+
+	selected := make(map[string]bool, len(selectedIDs))
+	for _, id := range selectedIDs {
+		selected[id] = true
+	}
+	known := 0
+
+	outcome := &bundleOutcome{Approved: []string{}, Rejected: []string{}}
+	for i := range items {
+		approve := decision == models.DecisionApproved && (len(selected) == 0 || selected[items[i].ID])
+		if selected[items[i].ID] {
+			known++
+		}
+		if approve {
+			items[i].Outcome = models.ItemOutcomeApproved
+			outcome.Approved = append(outcome.Approved, items[i].ID)
+		} else {
+			items[i].Outcome = models.ItemOutcomeRejected
+			outcome.Rejected = append(outcome.Rejected, items[i].ID)
+		}
+	}
+	if known != len(selected) {
+		return nil, nil, ErrUnknownBundleItem
+	}
+
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal bundle items: %w", err)
+	}
+	return datatypes.JSON(itemsJSON), outcome, nil
+}
+
+// bundleEventData adds a bundle's items and their outcomes to decision event action data,
+// so the requesting service gets every item's outcome in a single callback
+func bundleEventData(request *models.ApprovalRequest, actionData map[string]interface{}) map[string]interface{} {
+	items, err := request.BundleItems()
+	if err != nil || len(items) == 0 {
+		return actionData
+	}
+	if actionData == nil {
+		actionData = make(map[string]interface{})
+	}
+
+	approved, rejected := []string{}, []string{}
+	for _, item := range items {
+		switch item.Outcome {
+		case models.ItemOutcomeApproved:
+			approved = append(approved, item.ID)
+		case models.ItemOutcomeRejected:
+			rejected = append(rejected, item.ID)
+		}
+	}
+	actionData["items"] = items
+	actionData["approvedItemIds"] = approved
+	actionData["rejectedItemIds"] = rejected
+	return actionData
+}
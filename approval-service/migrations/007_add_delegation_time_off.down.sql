@@ -0,0 +1,13 @@
+-- Migration: Remove out-of-office delegation
+
+DROP TABLE IF EXISTS approval_out_of_office_rules;
+DROP TABLE IF EXISTS approval_staff_time_off;
+
+DROP INDEX IF EXISTS idx_delegations_conflicts;
+DROP INDEX IF EXISTS idx_delegations_time_off;
+
+ALTER TABLE approval_delegations
+    DROP COLUMN IF EXISTS conflict_note,
+    DROP COLUMN IF EXISTS has_conflict,
+    DROP COLUMN IF EXISTS time_off_id,
+    DROP COLUMN IF EXISTS source;
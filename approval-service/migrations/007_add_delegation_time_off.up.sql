@@ -0,0 +1,46 @@
+-- Migration: Add out-of-office delegation
+-- Approved staff time off activates a delegation to the staff member's out-of-office
+-- delegate, and delegations whose delegate is also away are flagged as conflicts
+
+ALTER TABLE approval_delegations
+    ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'manual',  -- manual or time_off
+    ADD COLUMN IF NOT EXISTS time_off_id VARCHAR(255),                       -- Time-off record the delegation covers
+    ADD COLUMN IF NOT EXISTS has_conflict BOOLEAN DEFAULT false,             -- Delegate is also away during the window
+    ADD COLUMN IF NOT EXISTS conflict_note TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_delegations_time_off ON approval_delegations(tenant_id, time_off_id) WHERE time_off_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_delegations_conflicts ON approval_delegations(tenant_id) WHERE has_conflict = true AND is_active = true;
+
+-- Time off synced from the staff time-off module
+CREATE TABLE IF NOT EXISTS approval_staff_time_off (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    time_off_id VARCHAR(255) NOT NULL,   -- ID in the staff time-off module
+    staff_id UUID NOT NULL,
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL,         -- approved or cancelled
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT time_off_dates_valid CHECK (end_date > start_date)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_staff_time_off_record ON approval_staff_time_off(tenant_id, time_off_id);
+CREATE INDEX IF NOT EXISTS idx_approval_staff_time_off_staff_id ON approval_staff_time_off(staff_id);
+
+-- Who receives a staff member's approval authority while they are on time off
+CREATE TABLE IF NOT EXISTS approval_out_of_office_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    staff_id UUID NOT NULL,
+    delegate_id UUID NOT NULL,
+    workflow_id UUID REFERENCES approval_workflows(id) ON DELETE CASCADE,  -- Optional: specific workflow
+    enabled BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT out_of_office_not_self CHECK (staff_id != delegate_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_out_of_office_staff ON approval_out_of_office_rules(tenant_id, staff_id);
@@ -0,0 +1,4 @@
+-- Migration: Remove bundled approval requests
+
+ALTER TABLE approval_requests
+    DROP COLUMN IF EXISTS items;
@@ -0,0 +1,6 @@
+-- Migration: Add bundled approval requests
+-- One approval request covers the items of a bulk operation; approvers can approve a
+-- subset and each item's outcome is recorded alongside it
+
+ALTER TABLE approval_requests
+    ADD COLUMN IF NOT EXISTS items JSONB;  -- [{id, resourceId, label, data, outcome}]
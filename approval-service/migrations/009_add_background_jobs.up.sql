@@ -0,0 +1,48 @@
+-- Migration: Persistent background jobs
+-- Purpose: Schedule, lease and metrics of recurring background jobs, and the history of
+-- their runs including dead letters awaiting an admin retry or dismissal.
+
+CREATE TABLE IF NOT EXISTS background_jobs (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT,
+    interval_seconds BIGINT NOT NULL,
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    paused_by VARCHAR(255),
+    paused_at TIMESTAMPTZ,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    trigger_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    triggered_by VARCHAR(255),
+    pending_trigger VARCHAR(20),
+    locked_by VARCHAR(255),
+    locked_until TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    last_status VARCHAR(20),
+    last_error TEXT,
+    last_duration_ms BIGINT,
+    run_count BIGINT NOT NULL DEFAULT 0,
+    success_count BIGINT NOT NULL DEFAULT 0,
+    failure_count BIGINT NOT NULL DEFAULT 0,
+    dead_letter_count BIGINT NOT NULL DEFAULT 0,
+    total_duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS background_job_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    trigger VARCHAR(20) NOT NULL,
+    triggered_by VARCHAR(255),
+    attempt INTEGER NOT NULL,
+    instance VARCHAR(255),
+    error TEXT,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ,
+    duration_ms BIGINT,
+    resolved_at TIMESTAMPTZ,
+    resolved_by VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS idx_background_job_runs_job ON background_job_runs(job_name, started_at);
+CREATE INDEX IF NOT EXISTS idx_background_job_runs_status ON background_job_runs(status);
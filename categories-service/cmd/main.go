@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -14,11 +15,14 @@ import (
 	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 	"categories-service/internal/config"
+	"categories-service/internal/database"
 	"categories-service/internal/events"
 	"categories-service/internal/handlers"
 	"categories-service/internal/middleware"
 	"categories-service/internal/repository"
+	"categories-service/internal/serviceauth"
 	"categories-service/internal/subscribers"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
@@ -55,12 +59,26 @@ func main() {
 	// Initialize configuration
 	cfg := config.Load()
 
-	// Initialize database
-	db, err := config.InitDB(cfg)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return config.InitDB(cfg) })
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	// "categories-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
+	}
+
 	// Initialize Redis client
 	redisURL := os.Getenv("REDIS_URL")
 	if redisURL == "" {
@@ -101,10 +119,11 @@ func main() {
 
 	// Initialize repository with Redis for caching
 	categoryRepo := repository.NewCategoryRepository(db, redisClient)
+	importMappingRepo := repository.NewImportMappingRepository(db)
 
 	// Initialize handlers
 	categoryHandler := handlers.NewCategoryHandler(categoryRepo, eventsPublisher)
-	importHandler := handlers.NewImportHandler(categoryRepo)
+	importHandler := handlers.NewImportHandler(categoryRepo, importMappingRepo)
 	approvalCallbackHandler := handlers.NewApprovalCallbackHandler(categoryRepo)
 
 	// Initialize and start approval subscriber for NATS events
@@ -152,6 +171,9 @@ func main() {
 	router.GET("/health", handlers.HealthCheck)
 	router.GET("/ready", handlers.HealthCheck)
 
+	// Prometheus metrics, including event consumer lag
+	router.GET("/metrics", gosharedmw.Handler())
+
 	// Protected API routes
 	api := router.Group("/api/v1")
 	
@@ -172,6 +194,7 @@ func main() {
 			// Read operations
 			categories.GET("", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesRead), categoryHandler.GetCategoryList)
 			categories.GET("/tree", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesRead), categoryHandler.GetCategoryTree)
+			categories.GET("/trash", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesRead), categoryHandler.GetTrashedCategories)
 			categories.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesRead), categoryHandler.GetCategory)
 			categories.GET("/analytics", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesRead), categoryHandler.GetCategoryAnalytics)
 			categories.GET("/:id/audit", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesRead), categoryHandler.GetCategoryAudit)
@@ -189,15 +212,20 @@ func main() {
 			// Delete operations
 			categories.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesDelete), categoryHandler.DeleteCategory)
 			categories.DELETE("/bulk", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesDelete), categoryHandler.BulkDeleteCategories)
+			categories.POST("/:id/restore", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesDelete), categoryHandler.RestoreCategory)
 
 			// Import/Export operations
 			categories.GET("/import/template", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesRead), importHandler.GetImportTemplate)
 			categories.POST("/import", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesCreate), importHandler.ImportCategories)
+			categories.POST("/import/preview", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesCreate), importHandler.PreviewImport)
+			categories.GET("/import/mappings", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesRead), importHandler.ListMappingPresets)
+			categories.POST("/import/mappings", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesCreate), importHandler.SaveMappingPreset)
+			categories.DELETE("/import/mappings/:presetId", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesCreate), importHandler.DeleteMappingPreset)
 			categories.POST("/export", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesRead), categoryHandler.ExportCategories)
 
 			// Approval endpoints
 			categories.POST("/:id/submit-for-approval", rbacMiddleware.RequirePermission(rbac.PermissionCategoriesUpdate), approvalCallbackHandler.SubmitCategoryForApproval)
-			categories.POST("/approval-callback", approvalCallbackHandler.HandleApprovalCallback)
+			categories.POST("/approval-callback", serviceauth.New(serviceauth.ConfigFromEnv("categories-service"), nil).Require("approval-service"), approvalCallbackHandler.HandleApprovalCallback)
 		}
 	}
 
@@ -222,13 +250,18 @@ func main() {
 		port = "8083"
 	}
 
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+
 	// Graceful shutdown handling
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		log.Printf("Categories service starting on port %s", port)
-		if err := router.Run(":" + port); err != nil {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server:", err)
 		}
 	}()
@@ -237,6 +270,13 @@ func main() {
 	<-quit
 	log.Println("Shutting down categories-service...")
 
+	// Stop accepting connections and let in-flight requests finish
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
 	// Stop approval subscriber
 	if approvalSubscriber != nil {
 		approvalSubscriber.Stop()
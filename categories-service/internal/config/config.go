@@ -1,11 +1,11 @@
 package config
 
 import (
-	"categories-service/internal/models"
+	"categories-service/internal/tenancy"
 	"fmt"
-	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -24,8 +24,9 @@ type Config struct {
 	DBSSLMode  string
 
 	// Server
-	Port        string
-	Environment string
+	Port            string
+	Environment     string
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
 
 	// JWT
 	JWTSecret string
@@ -53,8 +54,9 @@ func Load() *Config {
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
 
 		// Server
-		Port:        getEnv("PORT", "8083"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Port:            getEnv("PORT", "8083"),
+		Environment:     getEnv("ENVIRONMENT", "development"),
+		ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
 
 		// JWT
 		JWTSecret: secrets.GetJWTSecret(),
@@ -87,13 +89,9 @@ func InitDB(cfg *Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	// Run auto-migration to ensure schema is up to date
-	// This will add any missing columns (like 'images') to existing tables
-	if err := db.AutoMigrate(&models.Category{}); err != nil {
-		log.Printf("Warning: Auto-migration failed: %v", err)
-		// Don't fail startup, just log the warning
-	} else {
-		log.Println("✓ Database schema migration completed")
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
 	}
 
 	return db, nil
@@ -104,4 +102,13 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -552,6 +553,76 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Category deleted"})
 }
 
+// GetTrashedCategories lists soft-deleted categories that can still be restored
+func (h *CategoryHandler) GetTrashedCategories(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	categories, total, err := h.repo.GetDeleted(tenantID, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get deleted categories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    categories,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// RestoreCategory moves a soft-deleted category out of the trash with tenant isolation
+func (h *CategoryHandler) RestoreCategory(c *gin.Context) {
+	tenantID, ok := h.getTenantID(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.repo.Restore(tenantID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "CATEGORY_NOT_FOUND",
+					"message": "Category not found in trash",
+				},
+			})
+			return
+		}
+		if errors.Is(err, repository.ErrParentCategoryDeleted) {
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "PARENT_CATEGORY_DELETED",
+					"message": "Restore the parent category first",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": category})
+}
+
 // UpdateCategoryStatus updates category status
 func (h *CategoryHandler) UpdateCategoryStatus(c *gin.Context) {
 	tenantID, ok := h.getTenantID(c)
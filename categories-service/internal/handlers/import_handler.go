@@ -60,11 +60,12 @@ type ImportResult struct {
 }
 
 type ImportHandler struct {
-	repo *repository.CategoryRepository
+	repo        *repository.CategoryRepository
+	mappingRepo *repository.ImportMappingRepository
 }
 
-func NewImportHandler(repo *repository.CategoryRepository) *ImportHandler {
-	return &ImportHandler{repo: repo}
+func NewImportHandler(repo *repository.CategoryRepository, mappingRepo *repository.ImportMappingRepository) *ImportHandler {
+	return &ImportHandler{repo: repo, mappingRepo: mappingRepo}
 }
 
 // CategoryImportTemplate returns the template definition for categories
@@ -311,6 +312,19 @@ func (h *ImportHandler) ImportCategories(c *gin.Context) {
 		return
 	}
 
+	mapping, err := h.resolveColumnMapping(c, tenantID.(string), "categories")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_MAPPING",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	rows = applyColumnMapping(rows, mapping)
+
 	result := h.processImportRows(tenantID.(string), userID.(string), rows, skipDuplicates, validateOnly)
 
 	c.JSON(http.StatusOK, result)
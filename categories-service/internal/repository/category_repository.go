@@ -19,8 +19,9 @@ const (
 )
 
 var (
-	ErrCategoryNotFound = errors.New("category not found")
-	ErrAccessDenied     = errors.New("access denied: category does not belong to tenant")
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrAccessDenied          = errors.New("access denied: category does not belong to tenant")
+	ErrParentCategoryDeleted = errors.New("parent category is deleted")
 )
 
 type CategoryRepository struct {
@@ -190,6 +191,59 @@ func (r *CategoryRepository) Delete(tenantID, id string) error {
 	return nil
 }
 
+// GetDeleted retrieves soft-deleted categories for a tenant, most recently deleted first
+func (r *CategoryRepository) GetDeleted(tenantID string, limit, offset int) ([]models.Category, int64, error) {
+	var categories []models.Category
+	var total int64
+
+	query := r.db.Unscoped().Model(&models.Category{}).
+		Where("tenant_id = ? AND deleted_at IS NOT NULL", tenantID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("deleted_at DESC").Limit(limit).Offset(offset).Find(&categories).Error
+	return categories, total, err
+}
+
+// Restore restores a soft-deleted category with tenant isolation
+// A category whose parent is still in the trash cannot be restored, since it would be
+// unreachable from the tree
+func (r *CategoryRepository) Restore(tenantID, id string) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Unscoped().
+		Where("id = ? AND tenant_id = ? AND deleted_at IS NOT NULL", id, tenantID).
+		First(&category).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+
+	if category.ParentID != nil {
+		var parentCount int64
+		if err := r.db.Model(&models.Category{}).
+			Where("id = ? AND tenant_id = ?", *category.ParentID, tenantID).
+			Count(&parentCount).Error; err != nil {
+			return nil, err
+		}
+		if parentCount == 0 {
+			return nil, ErrParentCategoryDeleted
+		}
+	}
+
+	if err := r.db.Unscoped().Model(&models.Category{}).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		Update("deleted_at", nil).Error; err != nil {
+		return nil, err
+	}
+
+	category.DeletedAt = nil
+	r.invalidateCategoryCaches(context.Background(), tenantID, &id)
+	return &category, nil
+}
+
 // ExistsForTenant checks if a category exists and belongs to the given tenant
 func (r *CategoryRepository) ExistsForTenant(tenantID, id string) (bool, error) {
 	var count int64
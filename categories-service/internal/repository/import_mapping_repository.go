@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"time"
+
+	"categories-service/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ImportMappingRepository stores per-tenant import column mapping presets
+type ImportMappingRepository struct {
+	db *gorm.DB
+}
+
+func NewImportMappingRepository(db *gorm.DB) *ImportMappingRepository {
+	return &ImportMappingRepository{db: db}
+}
+
+// List returns the tenant's presets for an entity, ordered by name
+func (r *ImportMappingRepository) List(tenantID, entity string) ([]models.ImportMappingPreset, error) {
+	var presets []models.ImportMappingPreset
+	err := r.db.Where("tenant_id = ? AND entity = ?", tenantID, entity).
+		Order("name ASC").
+		Find(&presets).Error
+	return presets, err
+}
+
+// GetByID retrieves a preset with tenant isolation
+func (r *ImportMappingRepository) GetByID(tenantID string, id uuid.UUID) (*models.ImportMappingPreset, error) {
+	var preset models.ImportMappingPreset
+	if err := r.db.Where("id = ? AND tenant_id = ?", id, tenantID).First(&preset).Error; err != nil {
+		return nil, err
+	}
+	return &preset, nil
+}
+
+// Save creates a preset or replaces the mapping of an existing preset with the same name
+func (r *ImportMappingRepository) Save(preset *models.ImportMappingPreset) error {
+	now := time.Now()
+	preset.UpdatedAt = now
+	if preset.CreatedAt.IsZero() {
+		preset.CreatedAt = now
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "entity"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mapping", "updated_at"}),
+	}).Create(preset).Error
+}
+
+// Delete removes a preset with tenant isolation
+func (r *ImportMappingRepository) Delete(tenantID string, id uuid.UUID) (bool, error) {
+	result := r.db.Where("id = ? AND tenant_id = ?", id, tenantID).Delete(&models.ImportMappingPreset{})
+	return result.RowsAffected > 0, result.Error
+}
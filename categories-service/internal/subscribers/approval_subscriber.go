@@ -7,16 +7,16 @@ import (
 
 	"github.com/sirupsen/logrus"
 	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"categories-service/internal/eventconsumer"
 	"categories-service/internal/models"
 	"categories-service/internal/repository"
 )
 
 // ApprovalSubscriber handles incoming approval events for categories
 type ApprovalSubscriber struct {
-	subscriber *gosharedevents.Subscriber
+	consumer   *eventconsumer.Consumer
 	repo       *repository.CategoryRepository
 	logger     *logrus.Entry
-	cancel     context.CancelFunc
 }
 
 // NewApprovalSubscriber creates a new approval event subscriber for categories
@@ -29,19 +29,20 @@ func NewApprovalSubscriber(
 		natsURL = "nats://nats.nats.svc.cluster.local:4222"
 	}
 
-	config := gosharedevents.DefaultSubscriberConfig(natsURL, "categories-service-approvals")
-	config.Name = "categories-service-approval-subscriber"
-	config.DeliverPolicy = "new"
-	config.MaxDeliver = 3
-	config.AckWait = 30 * time.Second
-
-	subscriber, err := gosharedevents.NewSubscriber(config, logger)
+	consumer, err := eventconsumer.New(eventconsumer.Config{
+		NATSURL:    natsURL,
+		ClientName: "categories-service-approval-subscriber",
+		Durable:    "categories-service-approvals",
+		Stream:     gosharedevents.StreamApprovals,
+		MaxDeliver: 3,
+		AckWait:    30 * time.Second,
+	}, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ApprovalSubscriber{
-		subscriber: subscriber,
+		consumer:   consumer,
 		repo:       repo,
 		logger:     logger.WithField("component", "approval-subscriber"),
 	}, nil
@@ -49,15 +50,12 @@ func NewApprovalSubscriber(
 
 // Start starts listening for approval events
 func (s *ApprovalSubscriber) Start(ctx context.Context) error {
-	ctx, cancel := context.WithCancel(ctx)
-	s.cancel = cancel
-
 	// Subscribe to approval.granted events
 	subjects := []string{gosharedevents.ApprovalGranted}
 
 	s.logger.Info("Starting category approval event subscription...")
 
-	err := s.subscriber.SubscribeApprovalEvents(ctx, subjects, s.handleApprovalEvent)
+	err := s.consumer.Start(ctx, subjects, eventconsumer.JSON(s.handleApprovalEvent))
 	if err != nil {
 		return err
 	}
@@ -147,11 +145,8 @@ func (s *ApprovalSubscriber) executeCategoryUpdateApprove(ctx context.Context, e
 
 // Stop stops the approval subscriber
 func (s *ApprovalSubscriber) Stop() {
-	if s.cancel != nil {
-		s.cancel()
-	}
-	if s.subscriber != nil {
-		s.subscriber.Close()
+	if s.consumer != nil {
+		s.consumer.Stop()
 	}
 	s.logger.Info("Category approval subscriber stopped")
 }
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,13 +14,16 @@ import (
 	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 	"coupons-service/internal/clients"
+	"coupons-service/internal/codes"
 	"coupons-service/internal/config"
+	"coupons-service/internal/database"
 	"coupons-service/internal/events"
 	"coupons-service/internal/handlers"
 	"coupons-service/internal/middleware"
-	"coupons-service/internal/models"
 	"coupons-service/internal/repository"
+	"coupons-service/internal/workers"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"github.com/Tesseract-Nexus/go-shared/rbac"
@@ -58,18 +64,25 @@ func main() {
 	// Initialize configuration
 	cfg := config.Load()
 
-	// Initialize database
-	db, err := config.InitDB(cfg)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return config.InitDB(cfg) })
 	if err != nil {
 		logger.Fatal("Failed to connect to database:", err)
 	}
 
-	// Run database migrations to create tables if they don't exist
-	logger.Info("Running database migrations...")
-	if err := db.AutoMigrate(&models.Coupon{}, &models.CouponUsage{}); err != nil {
-		logger.Fatalf("Failed to run migrations: %v", err)
+	// "coupons-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			logger.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		logger.Fatalf("Database schema is not ready: %v", err)
 	}
-	logger.Info("✓ Database migrations completed")
 
 	// Initialize Redis client (optional - graceful degradation if Redis unavailable)
 	var redisClient *redis.Client
@@ -109,13 +122,45 @@ func main() {
 	// Initialize notification clients for email notifications
 	notificationClient := clients.NewNotificationClient()
 	tenantClient := clients.NewTenantClient()
+	customerClient := clients.NewCustomerClient()
 	logger.Info("✓ Notification client initialized")
 
+	// Generated coupon code format (refuses to start with guessable codes)
+	codeFormat := codes.FormatFromEnv("COUPON_CODE", codes.DefaultFormat)
+	if err := codeFormat.Validate(); err != nil {
+		logger.Fatalf("Invalid coupon code format: %v", err)
+	}
+
 	// Initialize repository
-	couponRepo := repository.NewCouponRepository(db, redisClient)
+	couponRepo := repository.NewCouponRepository(db, redisClient, codeFormat)
+	importMappingRepo := repository.NewImportMappingRepository(db)
+
+	// Background workers stop when the service shuts down
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+
+	// Start coupon schedule worker (activates/deactivates coupons as schedules start, pause and expire)
+	scheduleWorker := workers.NewScheduleWorker(couponRepo, eventsPublisher, logger)
+	go scheduleWorker.Start(workerCtx)
+	logger.Info("✓ Coupon schedule worker started")
 
 	// Initialize handlers with events publisher for NATS notifications
-	couponHandler := handlers.NewCouponHandler(couponRepo, notificationClient, tenantClient, eventsPublisher)
+	couponHandler := handlers.NewCouponHandler(couponRepo, notificationClient, tenantClient, customerClient, eventsPublisher)
+	importHandler := handlers.NewImportHandler(couponRepo, importMappingRepo)
+
+	// Brute-force protection for code lookups (per-IP/per-customer throttling and lockouts)
+	codeGuard := middleware.NewCodeGuard(redisClient, middleware.CodeGuardConfigFromEnv(), func(ctx context.Context, alert middleware.EnumerationAlert) {
+		logger.WithFields(logrus.Fields{
+			"tenant_id":      alert.TenantID,
+			"endpoint":       alert.Scope,
+			"client_ip":      alert.ClientIP,
+			"distinct_codes": alert.DistinctCodes,
+		}).Warn("Suspected coupon code enumeration")
+		if eventsPublisher != nil {
+			if err := eventsPublisher.PublishEnumerationSuspected(ctx, alert.TenantID, alert.Scope, alert.ClientIP, alert.CustomerID, alert.DistinctCodes, alert.LockoutSeconds); err != nil {
+				logger.WithError(err).Warn("Failed to publish enumeration alert")
+			}
+		}
+	})
 
 	// Initialize Gin router
 	if cfg.Environment == "production" {
@@ -129,9 +174,11 @@ func main() {
 	// Security headers middleware
 	router.Use(gosharedmw.SecurityHeaders())
 
-	// Rate limiting middleware (uses Redis for distributed rate limiting)
+	// Rate limiting middleware (uses Redis for distributed rate limiting; profiles are managed in staff-service)
 	if redisClient != nil {
-		router.Use(gosharedmw.RedisRateLimitMiddlewareWithProfile(redisClient, "standard"))
+		rateLimiter := middleware.NewProfileRateLimiter(redisClient)
+		go rateLimiter.Start(workerCtx)
+		router.Use(rateLimiter.Middleware())
 		logger.Info("✓ Redis-based rate limiting enabled")
 	} else {
 		router.Use(gosharedmw.RateLimit())
@@ -160,7 +207,9 @@ func main() {
 	publicAPI.Use(middleware.TenantMiddleware()) // Only extract tenant, no auth required
 	{
 		// Coupon validation for storefront (guests can validate coupons)
-		publicAPI.POST("/coupons/validate", couponHandler.ValidateCoupon)
+		publicAPI.POST("/coupons/validate", codeGuard.Middleware("validate"), couponHandler.ValidateCoupon)
+		// Coupons targeted at the signed-in customer (X-Customer-ID set by the storefront BFF)
+		publicAPI.GET("/coupons/my-offers", couponHandler.GetMyOffers)
 	}
 
 	// Protected API routes
@@ -184,13 +233,20 @@ func main() {
 			coupons.GET("", rbacMiddleware.RequirePermission(rbac.PermissionCouponsRead), couponHandler.GetCouponList)
 			coupons.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionCouponsRead), couponHandler.GetCoupon)
 			coupons.GET("/analytics", rbacMiddleware.RequirePermission(rbac.PermissionCouponsRead), couponHandler.GetCouponAnalytics)
+			coupons.GET("/trash", rbacMiddleware.RequirePermission(rbac.PermissionCouponsRead), couponHandler.GetTrashedCoupons)
 			coupons.GET("/usage/:id", rbacMiddleware.RequirePermission(rbac.PermissionCouponsRead), couponHandler.GetCouponUsage)
 			coupons.POST("/export", rbacMiddleware.RequirePermission(rbac.PermissionCouponsRead), couponHandler.ExportCoupons)
+			coupons.GET("/import/template", rbacMiddleware.RequirePermission(rbac.PermissionCouponsRead), importHandler.GetImportTemplate)
+			coupons.GET("/import/mappings", rbacMiddleware.RequirePermission(rbac.PermissionCouponsRead), importHandler.ListMappingPresets)
 			// Note: /validate moved to public routes for storefront access
 
 			// Create operations
 			coupons.POST("", rbacMiddleware.RequirePermission(rbac.PermissionCouponsCreate), couponHandler.CreateCoupon)
 			coupons.POST("/bulk", rbacMiddleware.RequirePermission(rbac.PermissionCouponsCreate), couponHandler.BulkCreateCoupons)
+			coupons.POST("/import", rbacMiddleware.RequirePermission(rbac.PermissionCouponsCreate), importHandler.ImportCoupons)
+			coupons.POST("/import/preview", rbacMiddleware.RequirePermission(rbac.PermissionCouponsCreate), importHandler.PreviewImport)
+			coupons.POST("/import/mappings", rbacMiddleware.RequirePermission(rbac.PermissionCouponsCreate), importHandler.SaveMappingPreset)
+			coupons.DELETE("/import/mappings/:presetId", rbacMiddleware.RequirePermission(rbac.PermissionCouponsCreate), importHandler.DeleteMappingPreset)
 
 			// Update operations
 			coupons.PUT("/:id", rbacMiddleware.RequirePermission(rbac.PermissionCouponsUpdate), couponHandler.UpdateCoupon)
@@ -199,6 +255,7 @@ func main() {
 
 			// Delete operations
 			coupons.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionCouponsDelete), couponHandler.DeleteCoupon)
+			coupons.POST("/:id/restore", rbacMiddleware.RequirePermission(rbac.PermissionCouponsDelete), couponHandler.RestoreCoupon)
 		}
 	}
 
@@ -211,8 +268,31 @@ func main() {
 		port = "8082"
 	}
 
-	logger.Infof("Coupons service starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		logger.Fatal("Failed to start server:", err)
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
 	}
+	go func() {
+		logger.Infof("Coupons service starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("Shutting down coupons-service...")
+
+	// Stop accepting connections and let in-flight requests finish before stopping workers
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.WithError(err).Warn("Server forced to shutdown")
+	}
+
+	stopWorkers()
+	logger.Info("Coupons service stopped")
 }
\ No newline at end of file
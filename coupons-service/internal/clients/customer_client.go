@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"coupons-service/internal/serviceauth"
+)
+
+// ErrCustomerNotFound is returned when customers-service has no such customer
+var ErrCustomerNotFound = errors.New("customer not found")
+
+// CustomerClient handles HTTP communication with customers-service for coupon targeting
+type CustomerClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// CustomerTargeting is a customer's segments and order count, as returned by customers-service
+type CustomerTargeting struct {
+	CustomerID  string   `json:"customerId"`
+	SegmentIDs  []string `json:"segmentIds"`
+	TotalOrders int      `json:"totalOrders"`
+}
+
+// NewCustomerClient creates a new customer client
+func NewCustomerClient() *CustomerClient {
+	baseURL := os.Getenv("CUSTOMERS_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://customers-service:8080"
+	}
+
+	return &CustomerClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// GetCustomerTargeting fetches the segments a customer belongs to and their order count
+func (c *CustomerClient) GetCustomerTargeting(ctx context.Context, tenantID, customerID string) (*CustomerTargeting, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/internal/customers/%s/targeting", c.baseURL, customerID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	serviceauth.Sign(req, "coupons-service")
+
+	req.Header.Set("X-Tenant-ID", tenantID)
+	req.Header.Set("X-Internal-Service", "coupons-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch customer targeting: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCustomerNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("customers-service returned status %d", resp.StatusCode)
+	}
+
+	var targeting CustomerTargeting
+	if err := json.NewDecoder(resp.Body).Decode(&targeting); err != nil {
+		return nil, fmt.Errorf("failed to decode customer targeting: %w", err)
+	}
+	return &targeting, nil
+}
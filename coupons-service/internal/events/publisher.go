@@ -3,11 +3,24 @@ package events
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/Tesseract-Nexus/go-shared/events"
+	"coupons-service/internal/models"
 )
 
+// Coupon schedule event types. They have no go-shared constants yet; storefronts drop
+// cached coupons when a coupon starts or stops being in effect.
+const (
+	CouponActivated   = "coupon.activated"
+	CouponDeactivated = "coupon.deactivated"
+)
+
+// CouponEnumerationSuspected has no go-shared constant yet; security alerting subscribes
+// to it to flag clients guessing coupon codes
+const CouponEnumerationSuspected = "coupon.enumeration_suspected"
+
 // Publisher wraps the shared events publisher for coupon-specific events
 type Publisher struct {
 	publisher *events.Publisher
@@ -92,6 +105,68 @@ func (p *Publisher) PublishCouponDeleted(ctx context.Context, tenantID, couponID
 	return p.publisher.Publish(ctx, event)
 }
 
+// PublishCouponActivated publishes a coupon activated event when a coupon's schedule puts it in effect
+func (p *Publisher) PublishCouponActivated(ctx context.Context, tenantID, couponID, couponCode, validUntil, nextTransitionAt string) error {
+	event := events.NewCouponEvent(CouponActivated, tenantID)
+	event.CouponID = couponID
+	event.CouponCode = couponCode
+	event.ValidUntil = validUntil
+	event.Status = "ACTIVE"
+	event.Metadata = map[string]interface{}{"nextTransitionAt": nextTransitionAt}
+
+	return p.publisher.Publish(ctx, event)
+}
+
+// PublishCouponDeactivated publishes a coupon deactivated event when a coupon's schedule takes it
+// out of effect, either between recurring windows (SCHEDULED) or for good (EXPIRED)
+func (p *Publisher) PublishCouponDeactivated(ctx context.Context, tenantID, couponID, couponCode, status, nextTransitionAt string) error {
+	event := events.NewCouponEvent(CouponDeactivated, tenantID)
+	event.CouponID = couponID
+	event.CouponCode = couponCode
+	event.Status = status
+	event.Metadata = map[string]interface{}{"nextTransitionAt": nextTransitionAt}
+
+	return p.publisher.Publish(ctx, event)
+}
+
+// PublishScheduleChange publishes coupon.activated when a coupon's status moves to ACTIVE and
+// coupon.deactivated when it moves away from ACTIVE
+func (p *Publisher) PublishScheduleChange(ctx context.Context, coupon *models.Coupon, previous models.CouponStatus) error {
+	nextTransitionAt := ""
+	if coupon.NextTransitionAt != nil {
+		nextTransitionAt = coupon.NextTransitionAt.Format(time.RFC3339)
+	}
+
+	switch {
+	case previous == coupon.Status:
+		return nil
+	case coupon.Status == models.StatusActive:
+		validUntil := ""
+		if coupon.ValidUntil != nil {
+			validUntil = coupon.ValidUntil.Format(time.RFC3339)
+		}
+		return p.PublishCouponActivated(ctx, coupon.TenantID, coupon.ID.String(), coupon.Code, validUntil, nextTransitionAt)
+	case previous == models.StatusActive:
+		return p.PublishCouponDeactivated(ctx, coupon.TenantID, coupon.ID.String(), coupon.Code, string(coupon.Status), nextTransitionAt)
+	}
+	return nil
+}
+
+// PublishEnumerationSuspected publishes an alert that a client looks like it is guessing
+// coupon codes
+func (p *Publisher) PublishEnumerationSuspected(ctx context.Context, tenantID, scope, clientIP, customerID string, distinctCodes int64, lockoutSeconds int) error {
+	event := events.NewCouponEvent(CouponEnumerationSuspected, tenantID)
+	event.CustomerID = customerID
+	event.Metadata = map[string]interface{}{
+		"endpoint":       scope,
+		"clientIp":       clientIP,
+		"distinctCodes":  distinctCodes,
+		"lockoutSeconds": lockoutSeconds,
+	}
+
+	return p.publisher.Publish(ctx, event)
+}
+
 // IsConnected returns true if connected to NATS
 func (p *Publisher) IsConnected() bool {
 	return p.publisher.IsConnected()
@@ -2,15 +2,19 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
+	"coupons-service/internal/bulk"
 	"coupons-service/internal/clients"
 	"coupons-service/internal/events"
+	"coupons-service/internal/middleware"
 	"coupons-service/internal/models"
 	"coupons-service/internal/repository"
 )
@@ -19,14 +23,16 @@ type CouponHandler struct {
 	repo               *repository.CouponRepository
 	notificationClient *clients.NotificationClient
 	tenantClient       *clients.TenantClient
+	customerClient     *clients.CustomerClient
 	eventsPublisher    *events.Publisher
 }
 
-func NewCouponHandler(repo *repository.CouponRepository, notificationClient *clients.NotificationClient, tenantClient *clients.TenantClient, eventsPublisher *events.Publisher) *CouponHandler {
+func NewCouponHandler(repo *repository.CouponRepository, notificationClient *clients.NotificationClient, tenantClient *clients.TenantClient, customerClient *clients.CustomerClient, eventsPublisher *events.Publisher) *CouponHandler {
 	return &CouponHandler{
 		repo:               repo,
 		notificationClient: notificationClient,
 		tenantClient:       tenantClient,
+		customerClient:     customerClient,
 		eventsPublisher:    eventsPublisher,
 	}
 }
@@ -59,130 +65,36 @@ func (h *CouponHandler) CreateCoupon(c *gin.Context) {
 		return
 	}
 
-	// Convert arrays to JSON
-	var excludedTenants, excludedVendors, categoryIDs, productIDs, userGroupIDs, countryCodes, regionCodes, tags, allowedPaymentMethods, daysOfWeek *models.JSON
-
-	if len(req.ExcludedTenants) > 0 {
-		excludedTenants = &models.JSON{}
-		for i, v := range req.ExcludedTenants {
-			(*excludedTenants)[strconv.Itoa(i)] = v
-		}
-	}
-
-	if len(req.ExcludedVendors) > 0 {
-		excludedVendors = &models.JSON{}
-		for i, v := range req.ExcludedVendors {
-			(*excludedVendors)[strconv.Itoa(i)] = v
-		}
-	}
-
-	if len(req.CategoryIDs) > 0 {
-		categoryIDs = &models.JSON{}
-		for i, v := range req.CategoryIDs {
-			(*categoryIDs)[strconv.Itoa(i)] = v
-		}
-	}
-
-	if len(req.ProductIDs) > 0 {
-		productIDs = &models.JSON{}
-		for i, v := range req.ProductIDs {
-			(*productIDs)[strconv.Itoa(i)] = v
-		}
-	}
-
-	if len(req.UserGroupIDs) > 0 {
-		userGroupIDs = &models.JSON{}
-		for i, v := range req.UserGroupIDs {
-			(*userGroupIDs)[strconv.Itoa(i)] = v
-		}
-	}
-
-	if len(req.CountryCodes) > 0 {
-		countryCodes = &models.JSON{}
-		for i, v := range req.CountryCodes {
-			(*countryCodes)[strconv.Itoa(i)] = v
-		}
-	}
-
-	if len(req.RegionCodes) > 0 {
-		regionCodes = &models.JSON{}
-		for i, v := range req.RegionCodes {
-			(*regionCodes)[strconv.Itoa(i)] = v
-		}
-	}
-
-	if len(req.Tags) > 0 {
-		tags = &models.JSON{}
-		for i, v := range req.Tags {
-			(*tags)[strconv.Itoa(i)] = v
-		}
-	}
-
-	if len(req.AllowedPaymentMethods) > 0 {
-		allowedPaymentMethods = &models.JSON{}
-		for i, v := range req.AllowedPaymentMethods {
-			(*allowedPaymentMethods)[strconv.Itoa(i)] = string(v)
-		}
+	timezone := requestTimezone(req.Timezone)
+	if err := models.ValidateSchedule(timezone, req.RecurringWindows); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_SCHEDULE",
+				Message: err.Error(),
+			},
+		})
+		return
 	}
 
-	if len(req.DaysOfWeek) > 0 {
-		daysOfWeek = &models.JSON{}
-		for i, v := range req.DaysOfWeek {
-			(*daysOfWeek)[strconv.Itoa(i)] = v
+	// Single-use and targeted coupons are usually created without a vanity code
+	if req.Code == "" {
+		code, err := h.repo.GenerateUniqueCode(tenantID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "CODE_GENERATION_FAILED",
+					Message: "Failed to generate coupon code",
+					Details: &models.JSON{"error": err.Error()},
+				},
+			})
+			return
 		}
+		req.Code = code
 	}
 
-	coupon := &models.Coupon{
-		TenantID:              tenantID,
-		CreatedByID:           userID,
-		UpdatedByID:           userID,
-		Code:                  req.Code,
-		Description:           req.Description,
-		DisplayText:           req.DisplayText,
-		ImageURL:              req.ImageURL,
-		ThumbnailURL:          req.ThumbnailURL,
-		Scope:                 req.Scope,
-		Priority:              models.PriorityMedium,
-		DiscountType:          req.DiscountType,
-		DiscountValue:         req.DiscountValue,
-		MaxDiscount:           req.MaxDiscount,
-		MinOrderValue:         req.MinOrderValue,
-		MaxDiscountPerVendor:  req.MaxDiscountPerVendor,
-		MaxUsageCount:         req.MaxUsageCount,
-		MaxUsagePerUser:       req.MaxUsagePerUser,
-		MaxUsagePerTenant:     req.MaxUsagePerTenant,
-		MaxUsagePerVendor:     req.MaxUsagePerVendor,
-		FirstTimeUserOnly:     req.FirstTimeUserOnly != nil && *req.FirstTimeUserOnly,
-		MinItemCount:          req.MinItemCount,
-		MaxItemCount:          req.MaxItemCount,
-		ExcludedTenants:       excludedTenants,
-		ExcludedVendors:       excludedVendors,
-		CategoryIDs:           categoryIDs,
-		ProductIDs:            productIDs,
-		UserGroupIDs:          userGroupIDs,
-		CountryCodes:          countryCodes,
-		RegionCodes:           regionCodes,
-		ValidFrom:             req.ValidFrom,
-		ValidUntil:            req.ValidUntil,
-		DaysOfWeek:            daysOfWeek,
-		AllowedPaymentMethods: allowedPaymentMethods,
-		StackableWithOther:    req.StackableWithOther != nil && *req.StackableWithOther,
-		StackablePriority:     0,
-		Combination:           models.CombinationNone,
-		IsActive:              req.IsActive == nil || *req.IsActive,
-		Metadata:              req.Metadata,
-		Tags:                  tags,
-	}
-
-	if req.Priority != nil {
-		coupon.Priority = *req.Priority
-	}
-	if req.StackablePriority != nil {
-		coupon.StackablePriority = *req.StackablePriority
-	}
-	if req.Combination != nil {
-		coupon.Combination = *req.Combination
-	}
+	coupon := couponFromRequest(tenantID, userID, timezone, &req)
 
 	if err := h.repo.CreateCoupon(coupon); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -196,31 +108,7 @@ func (h *CouponHandler) CreateCoupon(c *gin.Context) {
 		return
 	}
 
-	// Publish coupon.created event for real-time admin notifications via NATS
-	if h.eventsPublisher != nil {
-		go func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-
-			validUntil := ""
-			if coupon.ValidUntil != nil {
-				validUntil = coupon.ValidUntil.Format(time.RFC3339)
-			}
-
-			if err := h.eventsPublisher.PublishCouponCreated(
-				ctx,
-				tenantID,
-				coupon.ID.String(),
-				coupon.Code,
-				string(coupon.DiscountType),
-				coupon.DiscountValue,
-				coupon.ValidFrom.Format(time.RFC3339),
-				validUntil,
-			); err != nil {
-				log.Printf("[COUPON] Failed to publish coupon created event: %v", err)
-			}
-		}()
-	}
+	h.publishCouponCreated(tenantID, coupon)
 
 	// Send coupon created notification via HTTP (for email notifications)
 	if h.notificationClient != nil {
@@ -449,86 +337,16 @@ func (h *CouponHandler) UpdateCoupon(c *gin.Context) {
 		return
 	}
 
-	// Update fields
-	coupon.UpdatedByID = userID
-
-	if req.Description != nil {
-		coupon.Description = req.Description
-	}
-	if req.DisplayText != nil {
-		coupon.DisplayText = req.DisplayText
-	}
-	if req.ImageURL != nil {
-		coupon.ImageURL = req.ImageURL
-	}
-	if req.ThumbnailURL != nil {
-		coupon.ThumbnailURL = req.ThumbnailURL
-	}
-	if req.Priority != nil {
-		coupon.Priority = *req.Priority
-	}
-	if req.Status != nil {
-		coupon.Status = *req.Status
-	}
-	if req.IsActive != nil {
-		coupon.IsActive = *req.IsActive
-	}
-
-	// Update numeric fields
-	if req.MaxDiscount != nil {
-		coupon.MaxDiscount = req.MaxDiscount
-	}
-	if req.MinOrderValue != nil {
-		coupon.MinOrderValue = req.MinOrderValue
-	}
-	if req.MaxDiscountPerVendor != nil {
-		coupon.MaxDiscountPerVendor = req.MaxDiscountPerVendor
-	}
-
-	// Update usage limits
-	if req.MaxUsageCount != nil {
-		coupon.MaxUsageCount = req.MaxUsageCount
-	}
-	if req.MaxUsagePerUser != nil {
-		coupon.MaxUsagePerUser = req.MaxUsagePerUser
-	}
-	if req.MaxUsagePerTenant != nil {
-		coupon.MaxUsagePerTenant = req.MaxUsagePerTenant
-	}
-	if req.MaxUsagePerVendor != nil {
-		coupon.MaxUsagePerVendor = req.MaxUsagePerVendor
-	}
-
-	// Update restrictions
-	if req.FirstTimeUserOnly != nil {
-		coupon.FirstTimeUserOnly = *req.FirstTimeUserOnly
-	}
-	if req.MinItemCount != nil {
-		coupon.MinItemCount = req.MinItemCount
-	}
-	if req.MaxItemCount != nil {
-		coupon.MaxItemCount = req.MaxItemCount
-	}
-
-	// Update time fields
-	if req.ValidUntil != nil {
-		coupon.ValidUntil = req.ValidUntil
-	}
-
-	// Update stacking options
-	if req.StackableWithOther != nil {
-		coupon.StackableWithOther = *req.StackableWithOther
-	}
-	if req.StackablePriority != nil {
-		coupon.StackablePriority = *req.StackablePriority
-	}
-	if req.Combination != nil {
-		coupon.Combination = *req.Combination
-	}
-
-	// Update metadata
-	if req.Metadata != nil {
-		coupon.Metadata = req.Metadata
+	previousStatus := coupon.Status
+	if err := applyCouponUpdate(coupon, userID, &req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_SCHEDULE",
+				Message: err.Error(),
+			},
+		})
+		return
 	}
 
 	if err := h.repo.UpdateCoupon(coupon); err != nil {
@@ -543,25 +361,7 @@ func (h *CouponHandler) UpdateCoupon(c *gin.Context) {
 		return
 	}
 
-	// Publish coupon.updated event for real-time admin notifications via NATS
-	if h.eventsPublisher != nil {
-		go func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-
-			if err := h.eventsPublisher.PublishCouponUpdated(
-				ctx,
-				tenantID,
-				coupon.ID.String(),
-				coupon.Code,
-				string(coupon.DiscountType),
-				coupon.DiscountValue,
-				string(coupon.Status),
-			); err != nil {
-				log.Printf("[COUPON] Failed to publish coupon updated event: %v", err)
-			}
-		}()
-	}
+	h.publishCouponUpdated(tenantID, coupon, previousStatus)
 
 	c.JSON(http.StatusOK, models.CouponResponse{
 		Success: true,
@@ -654,26 +454,140 @@ func (h *CouponHandler) DeleteCoupon(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-// ValidateCoupon validates a coupon for use
-// @Summary Validate coupon
-// @Description Validate if a coupon can be used
+// GetTrashedCoupons lists soft-deleted coupons
+// @Summary List deleted coupons
+// @Description Get a paginated list of soft-deleted coupons that can be restored
 // @Tags coupons
 // @Accept json
 // @Produce json
-// @Param validation body models.ValidateCouponRequest true "Validation data"
-// @Success 200 {object} models.CouponValidationResponse
-// @Failure 400 {object} models.ErrorResponse
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} models.CouponListResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /coupons/validate [post]
+// @Router /coupons/trash [get]
 // @Security BearerAuth
-func (h *CouponHandler) ValidateCoupon(c *gin.Context) {
+func (h *CouponHandler) GetTrashedCoupons(c *gin.Context) {
 	tenantID := c.GetString("tenant_id")
 
-	var req models.ValidateCouponRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Success: false,
-			Error: models.Error{
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	coupons, total, err := h.repo.GetDeletedCoupons(tenantID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to fetch deleted coupons",
+				Details: &models.JSON{"error": err.Error()},
+			},
+		})
+		return
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, models.CouponListResponse{
+		Success: true,
+		Data:    coupons,
+		Pagination: &models.PaginationInfo{
+			Page:        page,
+			Limit:       limit,
+			Total:       total,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	})
+}
+
+// RestoreCoupon restores a soft-deleted coupon
+// @Summary Restore coupon
+// @Description Move a soft-deleted coupon out of the trash
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Param id path string true "Coupon ID"
+// @Success 200 {object} models.CouponResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /coupons/{id}/restore [post]
+// @Security BearerAuth
+func (h *CouponHandler) RestoreCoupon(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid coupon ID format",
+			},
+		})
+		return
+	}
+
+	coupon, err := h.repo.RestoreCoupon(tenantID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "RESTORE_FAILED",
+				Message: "Failed to restore coupon",
+				Details: &models.JSON{"error": err.Error()},
+			},
+		})
+		return
+	}
+
+	if coupon == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Coupon not found in trash",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CouponResponse{
+		Success: true,
+		Data:    coupon,
+	})
+}
+
+// ValidateCoupon validates a coupon for use
+// @Summary Validate coupon
+// @Description Validate if a coupon can be used
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Param validation body models.ValidateCouponRequest true "Validation data"
+// @Success 200 {object} models.CouponValidationResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /coupons/validate [post]
+// @Security BearerAuth
+func (h *CouponHandler) ValidateCoupon(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.ValidateCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
 				Code:    "INVALID_REQUEST",
 				Message: err.Error(),
 			},
@@ -696,6 +610,7 @@ func (h *CouponHandler) ValidateCoupon(c *gin.Context) {
 	}
 
 	if coupon == nil {
+		middleware.MarkCodeFailure(c, req.Code)
 		c.JSON(http.StatusOK, models.CouponValidationResponse{
 			Success:    true,
 			Valid:      false,
@@ -707,6 +622,11 @@ func (h *CouponHandler) ValidateCoupon(c *gin.Context) {
 
 	// Validate coupon
 	valid, discountAmount, reasonCode, message := h.validateCouponLogic(coupon, &req)
+	if valid {
+		if ok, code, msg := h.checkTargeting(c.Request.Context(), tenantID, coupon, req.UserID, req.IsFirstTimeUser); !ok {
+			valid, reasonCode, message = false, code, msg
+		}
+	}
 
 	response := models.CouponValidationResponse{
 		Success:    true,
@@ -789,6 +709,18 @@ func (h *CouponHandler) ApplyCoupon(c *gin.Context) {
 		return
 	}
 
+	// Check the customer against the coupon's targeting
+	if ok, code, msg := h.checkTargeting(c.Request.Context(), tenantID, coupon, req.UserID, nil); !ok {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    code,
+				Message: msg,
+			},
+		})
+		return
+	}
+
 	// Calculate discount amount
 	discountAmount := h.calculateDiscountAmount(coupon, req.OrderValue)
 
@@ -970,13 +902,161 @@ func (h *CouponHandler) GetCouponAnalytics(c *gin.Context) {
 	})
 }
 
-// Placeholder implementations for bulk operations
+// BulkCreateCoupons creates multiple coupons
+// @Summary Bulk create coupons
+// @Description Create multiple coupons; each coupon succeeds or fails on its own unless atomic=true
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Param coupons body models.BulkCreateCouponsRequest true "Coupons to create"
+// @Param atomic query bool false "Create all or none"
+// @Success 200 {object} bulk.Response
+// @Success 207 {object} bulk.Response
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 422 {object} bulk.Response
+// @Router /coupons/bulk [post]
+// @Security BearerAuth
 func (h *CouponHandler) BulkCreateCoupons(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
+	tenantID := c.GetString("tenant_id")
+	userID := c.GetString("user_id")
+
+	var req models.BulkCreateCouponsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if len(req.Coupons) > bulk.MaxItems {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "TOO_MANY_ITEMS",
+				Message: fmt.Sprintf("Maximum %d coupons can be created at once", bulk.MaxItems),
+			},
+		})
+		return
+	}
+
+	result := h.repo.BulkWrite(len(req.Coupons), bulk.ParseOptions(c), func(repo *repository.CouponRepository, i int) (bulk.Outcome, error) {
+		item := &req.Coupons[i]
+		if err := binding.Validator.ValidateStruct(item); err != nil {
+			return bulk.Outcome{}, bulk.Fail("INVALID_REQUEST", "%s", err.Error())
+		}
+
+		timezone := requestTimezone(item.Timezone)
+		if err := models.ValidateSchedule(timezone, item.RecurringWindows); err != nil {
+			return bulk.Outcome{}, bulk.Fail("INVALID_SCHEDULE", "%s", err.Error())
+		}
+
+		if item.Code == "" {
+			code, err := repo.GenerateUniqueCode(tenantID)
+			if err != nil {
+				return bulk.Outcome{}, bulk.Fail("CODE_GENERATION_FAILED", "Failed to generate coupon code: %v", err)
+			}
+			item.Code = code
+		} else {
+			existing, err := repo.GetCouponByCode(tenantID, item.Code)
+			if err != nil {
+				return bulk.Outcome{}, err
+			}
+			if existing != nil {
+				return bulk.Outcome{}, bulk.Fail("DUPLICATE_CODE", "Coupon code %q already exists", item.Code)
+			}
+		}
+
+		coupon := couponFromRequest(tenantID, userID, timezone, item)
+		if err := repo.CreateCoupon(coupon); err != nil {
+			return bulk.Outcome{}, bulk.Fail("CREATE_FAILED", "Failed to create coupon: %v", err)
+		}
+		return bulk.Created(coupon), nil
+	})
+
+	for _, data := range result.Applied() {
+		h.publishCouponCreated(tenantID, data.(*models.Coupon))
+	}
+
+	c.JSON(result.HTTPStatus(), result)
 }
 
+// BulkUpdateCoupons updates multiple coupons
+// @Summary Bulk update coupons
+// @Description Update multiple coupons; each coupon succeeds or fails on its own unless atomic=true
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Param coupons body models.BulkUpdateCouponsRequest true "Coupon updates, each with the coupon ID"
+// @Param atomic query bool false "Update all or none"
+// @Success 200 {object} bulk.Response
+// @Success 207 {object} bulk.Response
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 422 {object} bulk.Response
+// @Router /coupons/bulk [put]
+// @Security BearerAuth
 func (h *CouponHandler) BulkUpdateCoupons(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
+	tenantID := c.GetString("tenant_id")
+	userID := c.GetString("user_id")
+
+	var req models.BulkUpdateCouponsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if len(req.Coupons) > bulk.MaxItems {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "TOO_MANY_ITEMS",
+				Message: fmt.Sprintf("Maximum %d coupons can be updated at once", bulk.MaxItems),
+			},
+		})
+		return
+	}
+
+	previousStatuses := make([]models.CouponStatus, len(req.Coupons))
+	result := h.repo.BulkWrite(len(req.Coupons), bulk.ParseOptions(c), func(repo *repository.CouponRepository, i int) (bulk.Outcome, error) {
+		item := &req.Coupons[i]
+		if err := binding.Validator.ValidateStruct(&item.UpdateCouponRequest); err != nil {
+			return bulk.Outcome{}, bulk.Fail("INVALID_REQUEST", "%s", err.Error())
+		}
+
+		coupon, err := repo.GetCouponByID(tenantID, item.ID)
+		if err != nil {
+			return bulk.Outcome{}, err
+		}
+		if coupon == nil {
+			return bulk.Outcome{}, bulk.Fail("NOT_FOUND", "Coupon %s not found", item.ID)
+		}
+
+		previousStatuses[i] = coupon.Status
+		if err := applyCouponUpdate(coupon, userID, &item.UpdateCouponRequest); err != nil {
+			return bulk.Outcome{}, bulk.Fail("INVALID_SCHEDULE", "%s", err.Error())
+		}
+		if err := repo.UpdateCoupon(coupon); err != nil {
+			return bulk.Outcome{}, bulk.Fail("UPDATE_FAILED", "Failed to update coupon: %v", err)
+		}
+		return bulk.Updated(coupon), nil
+	})
+
+	for i, item := range result.Results {
+		if item.Status == bulk.StatusUpdated {
+			h.publishCouponUpdated(tenantID, item.Data.(*models.Coupon), previousStatuses[i])
+		}
+	}
+
+	c.JSON(result.HTTPStatus(), result)
 }
 
 func (h *CouponHandler) ExportCoupons(c *gin.Context) {
@@ -985,9 +1065,328 @@ func (h *CouponHandler) ExportCoupons(c *gin.Context) {
 
 // Helper functions
 
+// requestTimezone is the schedule timezone a create request asks for, defaulting when unset
+func requestTimezone(timezone *string) string {
+	if timezone != nil && *timezone != "" {
+		return *timezone
+	}
+	return models.DefaultTimezone
+}
+
+// publishCouponCreated announces a new coupon for real-time admin notifications (non-blocking)
+func (h *CouponHandler) publishCouponCreated(tenantID string, coupon *models.Coupon) {
+	if h.eventsPublisher == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		validUntil := ""
+		if coupon.ValidUntil != nil {
+			validUntil = coupon.ValidUntil.Format(time.RFC3339)
+		}
+
+		if err := h.eventsPublisher.PublishCouponCreated(
+			ctx,
+			tenantID,
+			coupon.ID.String(),
+			coupon.Code,
+			string(coupon.DiscountType),
+			coupon.DiscountValue,
+			coupon.ValidFrom.Format(time.RFC3339),
+			validUntil,
+		); err != nil {
+			log.Printf("[COUPON] Failed to publish coupon created event: %v", err)
+		}
+	}()
+}
+
+// publishCouponUpdated announces a coupon change and any schedule transition (non-blocking)
+func (h *CouponHandler) publishCouponUpdated(tenantID string, coupon *models.Coupon, previousStatus models.CouponStatus) {
+	if h.eventsPublisher == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := h.eventsPublisher.PublishCouponUpdated(
+			ctx,
+			tenantID,
+			coupon.ID.String(),
+			coupon.Code,
+			string(coupon.DiscountType),
+			coupon.DiscountValue,
+			string(coupon.Status),
+		); err != nil {
+			log.Printf("[COUPON] Failed to publish coupon updated event: %v", err)
+		}
+
+		// Publish coupon.activated/deactivated so storefronts drop cached coupons
+		if err := h.eventsPublisher.PublishScheduleChange(ctx, coupon, previousStatus); err != nil {
+			log.Printf("[COUPON] Failed to publish coupon schedule event: %v", err)
+		}
+	}()
+}
+
+// couponFromRequest builds a new coupon from a create request. The schedule must already be
+// validated and the code set.
+func couponFromRequest(tenantID, userID, timezone string, req *models.CreateCouponRequest) *models.Coupon {
+	// Convert arrays to JSON
+	var excludedTenants, excludedVendors, categoryIDs, productIDs, userGroupIDs, countryCodes, regionCodes, tags, allowedPaymentMethods, daysOfWeek *models.JSON
+
+	if len(req.ExcludedTenants) > 0 {
+		excludedTenants = &models.JSON{}
+		for i, v := range req.ExcludedTenants {
+			(*excludedTenants)[strconv.Itoa(i)] = v
+		}
+	}
+
+	if len(req.ExcludedVendors) > 0 {
+		excludedVendors = &models.JSON{}
+		for i, v := range req.ExcludedVendors {
+			(*excludedVendors)[strconv.Itoa(i)] = v
+		}
+	}
+
+	if len(req.CategoryIDs) > 0 {
+		categoryIDs = &models.JSON{}
+		for i, v := range req.CategoryIDs {
+			(*categoryIDs)[strconv.Itoa(i)] = v
+		}
+	}
+
+	if len(req.ProductIDs) > 0 {
+		productIDs = &models.JSON{}
+		for i, v := range req.ProductIDs {
+			(*productIDs)[strconv.Itoa(i)] = v
+		}
+	}
+
+	if len(req.UserGroupIDs) > 0 {
+		userGroupIDs = &models.JSON{}
+		for i, v := range req.UserGroupIDs {
+			(*userGroupIDs)[strconv.Itoa(i)] = v
+		}
+	}
+
+	if len(req.CountryCodes) > 0 {
+		countryCodes = &models.JSON{}
+		for i, v := range req.CountryCodes {
+			(*countryCodes)[strconv.Itoa(i)] = v
+		}
+	}
+
+	if len(req.RegionCodes) > 0 {
+		regionCodes = &models.JSON{}
+		for i, v := range req.RegionCodes {
+			(*regionCodes)[strconv.Itoa(i)] = v
+		}
+	}
+
+	if len(req.Tags) > 0 {
+		tags = &models.JSON{}
+		for i, v := range req.Tags {
+			(*tags)[strconv.Itoa(i)] = v
+		}
+	}
+
+	if len(req.AllowedPaymentMethods) > 0 {
+		allowedPaymentMethods = &models.JSON{}
+		for i, v := range req.AllowedPaymentMethods {
+			(*allowedPaymentMethods)[strconv.Itoa(i)] = string(v)
+		}
+	}
+
+	if len(req.DaysOfWeek) > 0 {
+		daysOfWeek = &models.JSON{}
+		for i, v := range req.DaysOfWeek {
+			(*daysOfWeek)[strconv.Itoa(i)] = v
+		}
+	}
+
+	coupon := &models.Coupon{
+		TenantID:              tenantID,
+		CreatedByID:           userID,
+		UpdatedByID:           userID,
+		Code:                  req.Code,
+		Description:           req.Description,
+		DisplayText:           req.DisplayText,
+		ImageURL:              req.ImageURL,
+		ThumbnailURL:          req.ThumbnailURL,
+		Scope:                 req.Scope,
+		Priority:              models.PriorityMedium,
+		DiscountType:          req.DiscountType,
+		DiscountValue:         req.DiscountValue,
+		MaxDiscount:           req.MaxDiscount,
+		MinOrderValue:         req.MinOrderValue,
+		MaxDiscountPerVendor:  req.MaxDiscountPerVendor,
+		MaxUsageCount:         req.MaxUsageCount,
+		MaxUsagePerUser:       req.MaxUsagePerUser,
+		MaxUsagePerTenant:     req.MaxUsagePerTenant,
+		MaxUsagePerVendor:     req.MaxUsagePerVendor,
+		FirstTimeUserOnly:     req.FirstTimeUserOnly != nil && *req.FirstTimeUserOnly,
+		MinItemCount:          req.MinItemCount,
+		MaxItemCount:          req.MaxItemCount,
+		ExcludedTenants:       excludedTenants,
+		ExcludedVendors:       excludedVendors,
+		CategoryIDs:           categoryIDs,
+		ProductIDs:            productIDs,
+		UserGroupIDs:          userGroupIDs,
+		CountryCodes:          countryCodes,
+		RegionCodes:           regionCodes,
+		CustomerIDs:           jsonList(req.CustomerIDs),
+		CustomerSegmentIDs:    jsonList(req.CustomerSegmentIDs),
+		ValidFrom:             req.ValidFrom,
+		ValidUntil:            req.ValidUntil,
+		DaysOfWeek:            daysOfWeek,
+		Timezone:              timezone,
+		RecurringWindows:      req.RecurringWindows,
+		AllowedPaymentMethods: allowedPaymentMethods,
+		StackableWithOther:    req.StackableWithOther != nil && *req.StackableWithOther,
+		StackablePriority:     0,
+		Combination:           models.CombinationNone,
+		IsActive:              req.IsActive == nil || *req.IsActive,
+		Metadata:              req.Metadata,
+		Tags:                  tags,
+	}
+
+	if req.Priority != nil {
+		coupon.Priority = *req.Priority
+	}
+	if req.StackablePriority != nil {
+		coupon.StackablePriority = *req.StackablePriority
+	}
+	if req.Combination != nil {
+		coupon.Combination = *req.Combination
+	}
+
+	// Coupons starting later or outside their recurring windows start out SCHEDULED
+	coupon.Status = models.StatusActive
+	coupon.RefreshSchedule(time.Now())
+
+	return coupon
+}
+
+// applyCouponUpdate applies the fields set in an update request to a coupon, returning an error
+// when the resulting schedule is invalid
+func applyCouponUpdate(coupon *models.Coupon, userID string, req *models.UpdateCouponRequest) error {
+	coupon.UpdatedByID = userID
+
+	if req.Description != nil {
+		coupon.Description = req.Description
+	}
+	if req.DisplayText != nil {
+		coupon.DisplayText = req.DisplayText
+	}
+	if req.ImageURL != nil {
+		coupon.ImageURL = req.ImageURL
+	}
+	if req.ThumbnailURL != nil {
+		coupon.ThumbnailURL = req.ThumbnailURL
+	}
+	if req.Priority != nil {
+		coupon.Priority = *req.Priority
+	}
+	if req.Status != nil {
+		coupon.Status = *req.Status
+	}
+	if req.IsActive != nil {
+		coupon.IsActive = *req.IsActive
+	}
+
+	// Update numeric fields
+	if req.MaxDiscount != nil {
+		coupon.MaxDiscount = req.MaxDiscount
+	}
+	if req.MinOrderValue != nil {
+		coupon.MinOrderValue = req.MinOrderValue
+	}
+	if req.MaxDiscountPerVendor != nil {
+		coupon.MaxDiscountPerVendor = req.MaxDiscountPerVendor
+	}
+
+	// Update usage limits
+	if req.MaxUsageCount != nil {
+		coupon.MaxUsageCount = req.MaxUsageCount
+	}
+	if req.MaxUsagePerUser != nil {
+		coupon.MaxUsagePerUser = req.MaxUsagePerUser
+	}
+	if req.MaxUsagePerTenant != nil {
+		coupon.MaxUsagePerTenant = req.MaxUsagePerTenant
+	}
+	if req.MaxUsagePerVendor != nil {
+		coupon.MaxUsagePerVendor = req.MaxUsagePerVendor
+	}
+
+	// Update restrictions
+	if req.FirstTimeUserOnly != nil {
+		coupon.FirstTimeUserOnly = *req.FirstTimeUserOnly
+	}
+	if req.MinItemCount != nil {
+		coupon.MinItemCount = req.MinItemCount
+	}
+	if req.MaxItemCount != nil {
+		coupon.MaxItemCount = req.MaxItemCount
+	}
+
+	// Update customer targeting (an empty list removes the restriction)
+	if req.CustomerIDs != nil {
+		coupon.CustomerIDs = jsonList(req.CustomerIDs)
+	}
+	if req.CustomerSegmentIDs != nil {
+		coupon.CustomerSegmentIDs = jsonList(req.CustomerSegmentIDs)
+	}
+
+	// Update time fields
+	if req.ValidFrom != nil {
+		coupon.ValidFrom = *req.ValidFrom
+	}
+	if req.ValidUntil != nil {
+		coupon.ValidUntil = req.ValidUntil
+	}
+
+	// Update schedule
+	if req.Timezone != nil || req.RecurringWindows != nil {
+		if req.Timezone != nil {
+			coupon.Timezone = *req.Timezone
+		}
+		if req.RecurringWindows != nil {
+			coupon.RecurringWindows = req.RecurringWindows
+		}
+		if err := models.ValidateSchedule(coupon.Timezone, coupon.RecurringWindows); err != nil {
+			return err
+		}
+	}
+
+	// Update stacking options
+	if req.StackableWithOther != nil {
+		coupon.StackableWithOther = *req.StackableWithOther
+	}
+	if req.StackablePriority != nil {
+		coupon.StackablePriority = *req.StackablePriority
+	}
+	if req.Combination != nil {
+		coupon.Combination = *req.Combination
+	}
+
+	// Update metadata
+	if req.Metadata != nil {
+		coupon.Metadata = req.Metadata
+	}
+
+	// Re-evaluate the schedule against the new validity period and windows
+	coupon.RefreshSchedule(time.Now())
+
+	return nil
+}
+
 func (h *CouponHandler) validateCouponLogic(coupon *models.Coupon, req *models.ValidateCouponRequest) (bool, float64, string, string) {
-	// Check if coupon is active
-	if !coupon.IsActive || coupon.Status != models.StatusActive {
+	// Check if coupon is active. SCHEDULED coupons are checked against their schedule
+	// directly, so they apply from the moment a window opens.
+	if !coupon.IsActive || (coupon.Status != models.StatusActive && coupon.Status != models.StatusScheduled) {
 		return false, 0, "INACTIVE", "Coupon is not active"
 	}
 
@@ -999,6 +1398,9 @@ func (h *CouponHandler) validateCouponLogic(coupon *models.Coupon, req *models.V
 	if coupon.ValidUntil != nil && now.After(*coupon.ValidUntil) {
 		return false, 0, "EXPIRED", "Coupon has expired"
 	}
+	if status, _ := coupon.ScheduleState(now); status == models.StatusScheduled {
+		return false, 0, "OUTSIDE_WINDOW", "Coupon is not valid at this time"
+	}
 
 	// Check minimum order value
 	if coupon.MinOrderValue != nil && req.OrderValue < *coupon.MinOrderValue {
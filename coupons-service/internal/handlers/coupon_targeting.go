@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"coupons-service/internal/clients"
+	"coupons-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// jsonList stores a list the way coupon target criteria are stored, keyed by index
+func jsonList(values []string) *models.JSON {
+	if len(values) == 0 {
+		return nil
+	}
+	list := models.JSON{}
+	for i, v := range values {
+		list[strconv.Itoa(i)] = v
+	}
+	return &list
+}
+
+// jsonListValues returns the values of a list stored by jsonList
+func jsonListValues(list *models.JSON) []string {
+	if list == nil {
+		return nil
+	}
+	values := make([]string, 0, len(*list))
+	for _, v := range *list {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// isTargeted reports whether a coupon is restricted to specific customers or segments
+func isTargeted(coupon *models.Coupon) bool {
+	return len(jsonListValues(coupon.CustomerIDs)) > 0 || len(jsonListValues(coupon.CustomerSegmentIDs)) > 0
+}
+
+// checkTargeting checks a customer against a coupon's customer, segment and first-purchase
+// targeting. isFirstTimeUser is only trusted for guests, who have no order history to check.
+func (h *CouponHandler) checkTargeting(ctx context.Context, tenantID string, coupon *models.Coupon, customerID string, isFirstTimeUser *bool) (bool, string, string) {
+	targeted := isTargeted(coupon)
+	if !targeted && !coupon.FirstTimeUserOnly {
+		return true, "", ""
+	}
+
+	if customerID == "" {
+		if !targeted && isFirstTimeUser != nil && *isFirstTimeUser {
+			return true, "", ""
+		}
+		return false, "CUSTOMER_REQUIRED", "Sign in to use this coupon"
+	}
+
+	matched := !targeted
+	for _, id := range jsonListValues(coupon.CustomerIDs) {
+		if id == customerID {
+			matched = true
+			break
+		}
+	}
+
+	// Segments and order history come from customers-service
+	segmentIDs := jsonListValues(coupon.CustomerSegmentIDs)
+	if (!matched && len(segmentIDs) > 0) || coupon.FirstTimeUserOnly {
+		if h.customerClient == nil {
+			return false, "TARGETING_UNAVAILABLE", "Coupon eligibility could not be checked"
+		}
+		targeting, err := h.customerClient.GetCustomerTargeting(ctx, tenantID, customerID)
+		if errors.Is(err, clients.ErrCustomerNotFound) {
+			targeting = &clients.CustomerTargeting{}
+		} else if err != nil {
+			log.Printf("[COUPON] Failed to fetch targeting for customer %s: %v", customerID, err)
+			return false, "TARGETING_UNAVAILABLE", "Coupon eligibility could not be checked"
+		}
+
+		if !matched {
+			matched = containsAny(segmentIDs, targeting.SegmentIDs)
+		}
+		if coupon.FirstTimeUserOnly && targeting.TotalOrders > 0 {
+			return false, "FIRST_PURCHASE_ONLY", "Coupon is only valid on a first purchase"
+		}
+	}
+
+	if !matched {
+		return false, "NOT_ELIGIBLE", "Coupon is not available to this customer"
+	}
+	return true, "", ""
+}
+
+// containsAny reports whether any candidate is in values
+func containsAny(values, candidates []string) bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	for _, c := range candidates {
+		if set[c] {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMyOffers lists the coupons targeted at the signed-in customer
+// @Summary Get my offers
+// @Description Get active coupons targeted at the customer directly or through their segments
+// @Tags coupons
+// @Produce json
+// @Param X-Customer-ID header string true "Customer ID"
+// @Success 200 {object} models.CouponListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /coupons/my-offers [get]
+func (h *CouponHandler) GetMyOffers(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	customerID := c.GetHeader("X-Customer-ID")
+	if customerID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "CUSTOMER_REQUIRED",
+				Message: "Missing X-Customer-ID header",
+			},
+		})
+		return
+	}
+
+	targeting := &clients.CustomerTargeting{}
+	if h.customerClient != nil {
+		result, err := h.customerClient.GetCustomerTargeting(c.Request.Context(), tenantID, customerID)
+		if err != nil && !errors.Is(err, clients.ErrCustomerNotFound) {
+			c.JSON(http.StatusBadGateway, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "CUSTOMER_LOOKUP_FAILED",
+					Message: "Failed to look up customer segments",
+				},
+			})
+			return
+		}
+		if result != nil {
+			targeting = result
+		}
+	}
+
+	coupons, err := h.repo.GetTargetedCoupons(tenantID, customerID, targeting.SegmentIDs, targeting.TotalOrders == 0, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to fetch offers",
+				Details: &models.JSON{"error": err.Error()},
+			},
+		})
+		return
+	}
+
+	// Drop coupons that are between recurring windows or used up
+	now := time.Now()
+	offers := make([]models.Coupon, 0, len(coupons))
+	for _, coupon := range coupons {
+		if status, _ := coupon.ScheduleState(now); status != models.StatusActive {
+			continue
+		}
+		if coupon.MaxUsageCount != nil && coupon.CurrentUsageCount >= *coupon.MaxUsageCount {
+			continue
+		}
+		offers = append(offers, coupon)
+	}
+
+	c.JSON(http.StatusOK, models.CouponListResponse{
+		Success: true,
+		Data:    offers,
+	})
+}
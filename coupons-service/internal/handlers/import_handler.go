@@ -0,0 +1,630 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"coupons-service/internal/models"
+	"coupons-service/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// MaxImportRows limits how many coupons can be imported in one request
+const MaxImportRows = 1000
+
+// ImportFormat represents the file format for import
+type ImportFormat string
+
+const (
+	ImportFormatCSV  ImportFormat = "csv"
+	ImportFormatXLSX ImportFormat = "xlsx"
+)
+
+// ImportTemplateColumn defines a column in the import template
+type ImportTemplateColumn struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Type        string `json:"type"`
+	Example     string `json:"example"`
+}
+
+// ImportTemplate defines the structure of an import template
+type ImportTemplate struct {
+	Entity     string                 `json:"entity"`
+	Version    string                 `json:"version"`
+	Columns    []ImportTemplateColumn `json:"columns"`
+	SampleData []map[string]string    `json:"sampleData,omitempty"`
+}
+
+// ImportRowError represents an error for a specific row
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ImportResult represents the result of an import operation
+type ImportResult struct {
+	Success      bool             `json:"success"`
+	TotalRows    int              `json:"totalRows"`
+	SuccessCount int              `json:"successCount"`
+	FailedCount  int              `json:"failedCount"`
+	SkippedCount int              `json:"skippedCount"`
+	Errors       []ImportRowError `json:"errors,omitempty"`
+	CreatedIDs   []string         `json:"createdIds,omitempty"`
+}
+
+type ImportHandler struct {
+	repo        *repository.CouponRepository
+	mappingRepo *repository.ImportMappingRepository
+}
+
+func NewImportHandler(repo *repository.CouponRepository, mappingRepo *repository.ImportMappingRepository) *ImportHandler {
+	return &ImportHandler{repo: repo, mappingRepo: mappingRepo}
+}
+
+// CouponImportTemplate returns the template definition for coupons
+func CouponImportTemplate() ImportTemplate {
+	return ImportTemplate{
+		Entity:  "coupons",
+		Version: "1.0",
+		Columns: []ImportTemplateColumn{
+			{Name: "code", Description: "Coupon code (unique per tenant)", Required: true, Type: "string", Example: "SUMMER20"},
+			{Name: "discountType", Description: "PERCENTAGE, FIXED, BUY_X_GET_Y or FREE_SHIPPING", Required: true, Type: "string", Example: "PERCENTAGE"},
+			{Name: "discountValue", Description: "Discount amount or percentage", Required: true, Type: "number", Example: "20"},
+			{Name: "validFrom", Description: "Start date (YYYY-MM-DD or RFC3339)", Required: true, Type: "date", Example: "2025-06-01"},
+			{Name: "validUntil", Description: "End date (YYYY-MM-DD or RFC3339)", Required: false, Type: "date", Example: "2025-08-31"},
+			{Name: "description", Description: "Internal description", Required: false, Type: "string", Example: "Summer sale"},
+			{Name: "displayText", Description: "Text shown to customers", Required: false, Type: "string", Example: "20% off everything"},
+			{Name: "scope", Description: "APPLICATION, TENANT, VENDOR or CUSTOM (default TENANT)", Required: false, Type: "string", Example: "TENANT"},
+			{Name: "priority", Description: "LOW, MEDIUM or HIGH (default MEDIUM)", Required: false, Type: "string", Example: "MEDIUM"},
+			{Name: "maxDiscount", Description: "Maximum discount amount", Required: false, Type: "number", Example: "50"},
+			{Name: "minOrderValue", Description: "Minimum order value", Required: false, Type: "number", Example: "100"},
+			{Name: "maxUsageCount", Description: "Total redemptions allowed", Required: false, Type: "number", Example: "500"},
+			{Name: "maxUsagePerUser", Description: "Redemptions allowed per customer", Required: false, Type: "number", Example: "1"},
+			{Name: "firstTimeUserOnly", Description: "Only for first-time customers (true/false)", Required: false, Type: "boolean", Example: "false"},
+			{Name: "isActive", Description: "Whether coupon is active (true/false)", Required: false, Type: "boolean", Example: "true"},
+			{Name: "categoryIds", Description: "Comma-separated category IDs", Required: false, Type: "string", Example: ""},
+			{Name: "productIds", Description: "Comma-separated product IDs", Required: false, Type: "string", Example: ""},
+			{Name: "tags", Description: "Comma-separated tags", Required: false, Type: "string", Example: "summer,sale"},
+		},
+		SampleData: []map[string]string{
+			{
+				"code":              "SUMMER20",
+				"discountType":      "PERCENTAGE",
+				"discountValue":     "20",
+				"validFrom":         "2025-06-01",
+				"validUntil":        "2025-08-31",
+				"description":       "Summer sale",
+				"displayText":       "20% off everything",
+				"scope":             "TENANT",
+				"priority":          "MEDIUM",
+				"maxDiscount":       "50",
+				"minOrderValue":     "100",
+				"maxUsageCount":     "500",
+				"maxUsagePerUser":   "1",
+				"firstTimeUserOnly": "false",
+				"isActive":          "true",
+				"tags":              "summer,sale",
+			},
+			{
+				"code":          "FREESHIP",
+				"discountType":  "FREE_SHIPPING",
+				"discountValue": "1",
+				"validFrom":     "2025-06-01",
+				"displayText":   "Free shipping",
+				"minOrderValue": "50",
+				"isActive":      "true",
+			},
+		},
+	}
+}
+
+// GetImportTemplate returns the import template definition or file
+// GET /api/v1/coupons/import/template
+func (h *ImportHandler) GetImportTemplate(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+
+	template := CouponImportTemplate()
+
+	switch format {
+	case "csv":
+		h.generateCSVTemplate(c, template)
+	case "xlsx":
+		h.generateXLSXTemplate(c, template)
+	default:
+		c.JSON(http.StatusOK, gin.H{
+			"success":  true,
+			"template": template,
+		})
+	}
+}
+
+// generateCSVTemplate generates and downloads a CSV template
+func (h *ImportHandler) generateCSVTemplate(c *gin.Context, template ImportTemplate) {
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", "attachment; filename=coupons_import_template.csv")
+
+	writer := csv.NewWriter(c.Writer)
+	defer writer.Flush()
+
+	headers := make([]string, len(template.Columns))
+	for i, col := range template.Columns {
+		headers[i] = col.Name
+	}
+	writer.Write(headers)
+
+	for _, sample := range template.SampleData {
+		row := make([]string, len(template.Columns))
+		for i, col := range template.Columns {
+			row[i] = sample[col.Name]
+		}
+		writer.Write(row)
+	}
+}
+
+// generateXLSXTemplate generates and downloads an Excel template
+func (h *ImportHandler) generateXLSXTemplate(c *gin.Context, template ImportTemplate) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheetName := "Coupons"
+	f.SetSheetName("Sheet1", sheetName)
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"4472C4"}, Pattern: 1},
+	})
+
+	requiredStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"C65911"}, Pattern: 1},
+	})
+
+	for i, col := range template.Columns {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		headerText := col.Name
+		if col.Required {
+			headerText = col.Name + " *"
+		}
+		f.SetCellValue(sheetName, cell, headerText)
+
+		if col.Required {
+			f.SetCellStyle(sheetName, cell, cell, requiredStyle)
+		} else {
+			f.SetCellStyle(sheetName, cell, cell, headerStyle)
+		}
+
+		colName, _ := excelize.ColumnNumberToName(i + 1)
+		f.SetColWidth(sheetName, colName, colName, 20)
+	}
+
+	for rowIdx, sample := range template.SampleData {
+		for colIdx, col := range template.Columns {
+			cell, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx+2)
+			f.SetCellValue(sheetName, cell, sample[col.Name])
+		}
+	}
+
+	f.NewSheet("Instructions")
+	f.SetCellValue("Instructions", "A1", "Coupon Import Instructions")
+	f.SetCellValue("Instructions", "A3", "Column Definitions:")
+
+	for i, col := range template.Columns {
+		row := i + 4
+		f.SetCellValue("Instructions", fmt.Sprintf("A%d", row), col.Name)
+		f.SetCellValue("Instructions", fmt.Sprintf("B%d", row), col.Description)
+		required := "Optional"
+		if col.Required {
+			required = "Required"
+		}
+		f.SetCellValue("Instructions", fmt.Sprintf("C%d", row), required)
+		f.SetCellValue("Instructions", fmt.Sprintf("D%d", row), col.Type)
+		f.SetCellValue("Instructions", fmt.Sprintf("E%d", row), col.Example)
+	}
+
+	f.SetColWidth("Instructions", "A", "A", 20)
+	f.SetColWidth("Instructions", "B", "B", 40)
+	f.SetColWidth("Instructions", "C", "C", 15)
+	f.SetColWidth("Instructions", "D", "D", 15)
+	f.SetColWidth("Instructions", "E", "E", 40)
+
+	sheetIdx, _ := f.GetSheetIndex(sheetName)
+	f.SetActiveSheet(sheetIdx)
+
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Disposition", "attachment; filename=coupons_import_template.xlsx")
+
+	f.Write(c.Writer)
+}
+
+// ImportCoupons imports coupons from CSV or Excel file
+// POST /api/v1/coupons/import
+func (h *ImportHandler) ImportCoupons(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	userID := c.GetString("user_id")
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FILE_REQUIRED",
+				Message: "Please upload a CSV or Excel file",
+			},
+		})
+		return
+	}
+	defer file.Close()
+
+	skipDuplicates := c.DefaultPostForm("skipDuplicates", "false") == "true"
+	validateOnly := c.DefaultPostForm("validateOnly", "false") == "true"
+
+	format, ok := detectImportFormat(header.Filename)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_FORMAT",
+				Message: "Only CSV and XLSX files are supported",
+			},
+		})
+		return
+	}
+
+	var rows []map[string]string
+	if format == ImportFormatCSV {
+		rows, err = h.parseCSV(file)
+	} else {
+		rows, err = h.parseXLSX(file)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "PARSE_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if len(rows) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "EMPTY_FILE",
+				Message: "The file contains no data rows",
+			},
+		})
+		return
+	}
+
+	if len(rows) > MaxImportRows {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "TOO_MANY_ROWS",
+				Message: fmt.Sprintf("Maximum %d coupons can be imported at once", MaxImportRows),
+			},
+		})
+		return
+	}
+
+	mapping, err := h.resolveColumnMapping(c, tenantID, "coupons")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_MAPPING",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	rows = applyColumnMapping(rows, mapping)
+
+	result := h.processImportRows(tenantID, userID, rows, skipDuplicates, validateOnly)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// detectImportFormat determines the import format from the file name
+func detectImportFormat(filename string) (ImportFormat, bool) {
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".csv"):
+		return ImportFormatCSV, true
+	case strings.HasSuffix(lower, ".xlsx"):
+		return ImportFormatXLSX, true
+	}
+	return "", false
+}
+
+func (h *ImportHandler) parseCSV(file io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(file)
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	for i := range headers {
+		headers[i] = normalizeImportHeader(headers[i])
+	}
+
+	var rows []map[string]string
+	lineNum := 1
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading line %d: %w", lineNum+1, err)
+		}
+
+		row := make(map[string]string)
+		for i, value := range record {
+			if i < len(headers) {
+				row[headers[i]] = strings.TrimSpace(value)
+			}
+		}
+		row["_row"] = strconv.Itoa(lineNum + 1)
+		rows = append(rows, row)
+		lineNum++
+	}
+
+	return rows, nil
+}
+
+func (h *ImportHandler) parseXLSX(file io.Reader) ([]map[string]string, error) {
+	f, err := excelize.OpenReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Excel file: %w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("no sheets found in Excel file")
+	}
+
+	sheetName := sheets[0]
+	for _, name := range sheets {
+		if strings.EqualFold(name, "Coupons") {
+			sheetName = name
+			break
+		}
+	}
+
+	excelRows, err := f.GetRows(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sheet: %w", err)
+	}
+
+	if len(excelRows) < 2 {
+		return nil, fmt.Errorf("file must have a header row and at least one data row")
+	}
+
+	headers := excelRows[0]
+	for i := range headers {
+		headers[i] = normalizeImportHeader(headers[i])
+	}
+
+	var rows []map[string]string
+	for rowIdx, excelRow := range excelRows[1:] {
+		row := make(map[string]string)
+		for i, value := range excelRow {
+			if i < len(headers) {
+				row[headers[i]] = strings.TrimSpace(value)
+			}
+		}
+		row["_row"] = strconv.Itoa(rowIdx + 2)
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (h *ImportHandler) processImportRows(tenantID, userID string, rows []map[string]string, skipDuplicates, validateOnly bool) *ImportResult {
+	result := &ImportResult{
+		TotalRows:  len(rows),
+		Errors:     make([]ImportRowError, 0),
+		CreatedIDs: make([]string, 0),
+	}
+
+	template := CouponImportTemplate()
+	seenCodes := make(map[string]int)
+	valid := 0
+
+	for _, row := range rows {
+		rowNum, _ := strconv.Atoi(row["_row"])
+
+		rowErrors := validateImportRow(row, rowNum, template.Columns)
+		coupon, err := couponFromImportRow(tenantID, userID, row)
+		if err != nil && len(rowErrors) == 0 {
+			rowErrors = append(rowErrors, ImportRowError{
+				Row:     rowNum,
+				Code:    "INVALID",
+				Message: err.Error(),
+			})
+		}
+		if len(rowErrors) > 0 {
+			result.Errors = append(result.Errors, rowErrors...)
+			result.FailedCount++
+			continue
+		}
+
+		code := strings.ToUpper(coupon.Code)
+		if firstRow, ok := seenCodes[code]; ok {
+			result.Errors = append(result.Errors, ImportRowError{
+				Row:     rowNum,
+				Column:  "code",
+				Code:    "DUPLICATE_IN_FILE",
+				Message: fmt.Sprintf("Coupon code '%s' already appears on row %d", coupon.Code, firstRow),
+			})
+			result.FailedCount++
+			continue
+		}
+		seenCodes[code] = rowNum
+
+		if existing, _ := h.repo.GetCouponByCode(tenantID, coupon.Code); existing != nil {
+			if skipDuplicates {
+				result.SkippedCount++
+				continue
+			}
+			result.Errors = append(result.Errors, ImportRowError{
+				Row:     rowNum,
+				Column:  "code",
+				Code:    "DUPLICATE",
+				Message: fmt.Sprintf("Coupon code '%s' already exists", coupon.Code),
+			})
+			result.FailedCount++
+			continue
+		}
+
+		if validateOnly {
+			valid++
+			continue
+		}
+
+		if err := h.repo.CreateCoupon(coupon); err != nil {
+			result.Errors = append(result.Errors, ImportRowError{
+				Row:     rowNum,
+				Code:    "CREATE_FAILED",
+				Message: err.Error(),
+			})
+			result.FailedCount++
+			continue
+		}
+		result.CreatedIDs = append(result.CreatedIDs, coupon.ID.String())
+	}
+
+	if validateOnly {
+		result.SuccessCount = valid
+		result.Success = len(result.Errors) == 0
+		return result
+	}
+
+	result.SuccessCount = len(result.CreatedIDs)
+	result.Success = result.SuccessCount > 0
+	return result
+}
+
+// couponFromImportRow converts a validated import row into a coupon
+func couponFromImportRow(tenantID, userID string, row map[string]string) (*models.Coupon, error) {
+	discountValue, _ := strconv.ParseFloat(row["discountvalue"], 64)
+	if discountValue <= 0 {
+		return nil, fmt.Errorf("discountValue must be greater than 0")
+	}
+
+	discountType := models.DiscountType(strings.ToUpper(row["discounttype"]))
+	switch discountType {
+	case models.DiscountPercentage, models.DiscountFixed, models.DiscountBuyXGetY, models.DiscountFreeShipping:
+	default:
+		return nil, fmt.Errorf("unknown discountType '%s'", row["discounttype"])
+	}
+	if discountType == models.DiscountPercentage && discountValue > 100 {
+		return nil, fmt.Errorf("percentage discount cannot exceed 100")
+	}
+
+	validFrom, err := parseImportDate(row["validfrom"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid validFrom date")
+	}
+
+	coupon := &models.Coupon{
+		TenantID:          tenantID,
+		CreatedByID:       userID,
+		UpdatedByID:       userID,
+		Code:              row["code"],
+		Scope:             models.ScopeTenant,
+		Status:            models.StatusActive,
+		Priority:          models.PriorityMedium,
+		IsActive:          true,
+		DiscountType:      discountType,
+		DiscountValue:     discountValue,
+		ValidFrom:         validFrom,
+		Combination:       models.CombinationNone,
+		FirstTimeUserOnly: strings.EqualFold(row["firsttimeuseronly"], "true"),
+		CategoryIDs:       importListJSON(row["categoryids"]),
+		ProductIDs:        importListJSON(row["productids"]),
+		Tags:              importListJSON(row["tags"]),
+	}
+
+	if row["validuntil"] != "" {
+		validUntil, err := parseImportDate(row["validuntil"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid validUntil date")
+		}
+		if validUntil.Before(validFrom) {
+			return nil, fmt.Errorf("validUntil must be after validFrom")
+		}
+		coupon.ValidUntil = &validUntil
+	}
+	if row["description"] != "" {
+		coupon.Description = stringPtr(row["description"])
+	}
+	if row["displaytext"] != "" {
+		coupon.DisplayText = stringPtr(row["displaytext"])
+	}
+	if row["scope"] != "" {
+		coupon.Scope = models.CouponScope(strings.ToUpper(row["scope"]))
+	}
+	if row["priority"] != "" {
+		coupon.Priority = models.CouponPriority(strings.ToUpper(row["priority"]))
+	}
+	if row["isactive"] != "" {
+		coupon.IsActive = strings.EqualFold(row["isactive"], "true")
+	}
+	if v, err := strconv.ParseFloat(row["maxdiscount"], 64); err == nil {
+		coupon.MaxDiscount = &v
+	}
+	if v, err := strconv.ParseFloat(row["minordervalue"], 64); err == nil {
+		coupon.MinOrderValue = &v
+	}
+	if v, err := strconv.Atoi(row["maxusagecount"]); err == nil {
+		coupon.MaxUsageCount = &v
+	}
+	if v, err := strconv.Atoi(row["maxusageperuser"]); err == nil {
+		coupon.MaxUsagePerUser = &v
+	}
+
+	return coupon, nil
+}
+
+// parseImportDate accepts YYYY-MM-DD or RFC3339 dates
+func parseImportDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// importListJSON converts a comma-separated cell into the indexed JSON list used for coupon targets
+func importListJSON(value string) *models.JSON {
+	if value == "" {
+		return nil
+	}
+	list := models.JSON{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list[strconv.Itoa(len(list))] = item
+		}
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return &list
+}
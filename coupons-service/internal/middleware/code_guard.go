@@ -0,0 +1,230 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// codeFailureKey is set on the gin context by handlers when a code doesn't exist
+const codeFailureKey = "code_guard_failed_code"
+
+// lockoutMemory is how long past lockouts count towards the next lockout's length
+const lockoutMemory = 24 * time.Hour
+
+// CodeGuardConfig holds the attempt and lockout limits for code lookup endpoints
+type CodeGuardConfig struct {
+	AttemptLimit   int           // Lookups allowed per client and per customer...
+	AttemptWindow  time.Duration // ...in this window
+	FailureLimit   int           // Unknown codes allowed per client and per customer...
+	FailureWindow  time.Duration // ...in this window before a lockout
+	LockoutBase    time.Duration // First lockout; doubles with each lockout in lockoutMemory
+	LockoutMax     time.Duration // Longest lockout
+	AlertThreshold int           // Distinct unknown codes from one client in FailureWindow that signal enumeration
+}
+
+// CodeGuardConfigFromEnv reads the code guard limits from CODE_GUARD_* environment variables
+func CodeGuardConfigFromEnv() CodeGuardConfig {
+	return CodeGuardConfig{
+		AttemptLimit:   envInt("CODE_GUARD_ATTEMPT_LIMIT", 30),
+		AttemptWindow:  envDuration("CODE_GUARD_ATTEMPT_WINDOW", time.Minute),
+		FailureLimit:   envInt("CODE_GUARD_FAILURE_LIMIT", 10),
+		FailureWindow:  envDuration("CODE_GUARD_FAILURE_WINDOW", 15*time.Minute),
+		LockoutBase:    envDuration("CODE_GUARD_LOCKOUT_BASE", time.Minute),
+		LockoutMax:     envDuration("CODE_GUARD_LOCKOUT_MAX", lockoutMemory),
+		AlertThreshold: envInt("CODE_GUARD_ALERT_THRESHOLD", 25),
+	}
+}
+
+func envInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if v, err := time.ParseDuration(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
+
+// EnumerationAlert describes a client that looks like it is guessing codes
+type EnumerationAlert struct {
+	TenantID       string
+	Scope          string
+	ClientIP       string
+	CustomerID     string
+	DistinctCodes  int64
+	LockoutSeconds int
+}
+
+// CodeGuard throttles code lookups per client IP and per customer, locks out identities
+// that keep trying unknown codes with exponentially growing lockouts, and raises an alert
+// when one client tries many distinct unknown codes. State lives in Redis so limits hold
+// across replicas.
+type CodeGuard struct {
+	client  *redis.Client
+	config  CodeGuardConfig
+	onAlert func(ctx context.Context, alert EnumerationAlert)
+}
+
+// NewCodeGuard creates a code guard. onAlert may be nil.
+func NewCodeGuard(client *redis.Client, config CodeGuardConfig, onAlert func(ctx context.Context, alert EnumerationAlert)) *CodeGuard {
+	return &CodeGuard{
+		client:  client,
+		config:  config,
+		onAlert: onAlert,
+	}
+}
+
+// MarkCodeFailure records that the code in this request doesn't exist, so the guard
+// counts it towards a lockout
+func MarkCodeFailure(c *gin.Context, code string) {
+	c.Set(codeFailureKey, code)
+}
+
+// Middleware guards a code lookup endpoint. scope names the endpoint in Redis keys and
+// alerts, e.g. "validate". Without Redis the guard is a no-op and only the general rate
+// limiter applies.
+func (g *CodeGuard) Middleware(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if g == nil || g.client == nil {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		tenantID := c.GetString("tenant_id")
+		clientIP := rateLimitClientIP(c)
+		customerID := c.GetString("user_id")
+		if customerID == "" {
+			customerID = c.GetHeader("X-Customer-ID")
+		}
+
+		identities := []string{"ip:" + clientIP}
+		if customerID != "" {
+			identities = append(identities, "customer:"+customerID)
+		}
+		prefix := "codeguard:" + tenantID + ":"
+
+		for _, identity := range identities {
+			if ttl, err := g.client.TTL(ctx, prefix+"lock:"+identity).Result(); err == nil && ttl > 0 {
+				g.reject(c, "CODE_ATTEMPTS_LOCKED", "Too many invalid codes. Please try again later.", ttl)
+				return
+			}
+			count, ttl, err := g.increment(ctx, prefix+"attempts:"+scope+":"+identity, g.config.AttemptWindow)
+			if err == nil && count > int64(g.config.AttemptLimit) {
+				g.reject(c, "TOO_MANY_ATTEMPTS", "Too many code checks. Please try again later.", ttl)
+				return
+			}
+		}
+
+		c.Next()
+
+		// Successful lookups deliberately don't reset failures, otherwise an attacker holding
+		// one valid code could interleave it to dodge lockouts
+		code := c.GetString(codeFailureKey)
+		if code == "" {
+			return
+		}
+		for _, identity := range identities {
+			g.recordFailure(ctx, prefix, identity)
+		}
+		g.trackDistinctCodes(ctx, prefix, scope, tenantID, clientIP, customerID, code)
+	}
+}
+
+// increment bumps a windowed counter and returns its value and remaining window
+func (g *CodeGuard) increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	pipe := g.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, window)
+	ttl := pipe.TTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, err
+	}
+	return incr.Val(), ttl.Val(), nil
+}
+
+// recordFailure counts an unknown code against an identity and locks it out once it
+// reaches the failure limit. Each lockout within lockoutMemory doubles the next one.
+func (g *CodeGuard) recordFailure(ctx context.Context, prefix, identity string) {
+	failures, _, err := g.increment(ctx, prefix+"failures:"+identity, g.config.FailureWindow)
+	if err != nil || failures < int64(g.config.FailureLimit) {
+		return
+	}
+
+	level, _, err := g.increment(ctx, prefix+"lockouts:"+identity, lockoutMemory)
+	if err != nil {
+		return
+	}
+	g.client.Set(ctx, prefix+"lock:"+identity, "1", g.lockoutDuration(level))
+	g.client.Del(ctx, prefix+"failures:"+identity)
+}
+
+// lockoutDuration is LockoutBase doubled for each earlier lockout, capped at LockoutMax
+func (g *CodeGuard) lockoutDuration(level int64) time.Duration {
+	duration := g.config.LockoutBase
+	for i := int64(1); i < level && duration < g.config.LockoutMax; i++ {
+		duration *= 2
+	}
+	if duration > g.config.LockoutMax {
+		duration = g.config.LockoutMax
+	}
+	return duration
+}
+
+// trackDistinctCodes records a hash of each unknown code per client and raises one alert
+// per failure window once a client has tried AlertThreshold distinct codes. Many distinct
+// codes (rather than one mistyped code retried) is the signature of enumeration.
+func (g *CodeGuard) trackDistinctCodes(ctx context.Context, prefix, scope, tenantID, clientIP, customerID, code string) {
+	sum := sha256.Sum256([]byte(code))
+	key := prefix + "codes:ip:" + clientIP
+
+	pipe := g.client.TxPipeline()
+	pipe.SAdd(ctx, key, hex.EncodeToString(sum[:8]))
+	pipe.ExpireNX(ctx, key, g.config.FailureWindow)
+	card := pipe.SCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil || card.Val() < int64(g.config.AlertThreshold) {
+		return
+	}
+
+	first, err := g.client.SetNX(ctx, prefix+"alerted:ip:"+clientIP, "1", g.config.FailureWindow).Result()
+	if err != nil || !first || g.onAlert == nil {
+		return
+	}
+	lockout, _ := g.client.TTL(ctx, prefix+"lock:ip:"+clientIP).Result()
+	g.onAlert(ctx, EnumerationAlert{
+		TenantID:       tenantID,
+		Scope:          scope,
+		ClientIP:       clientIP,
+		CustomerID:     customerID,
+		DistinctCodes:  card.Val(),
+		LockoutSeconds: int(lockout.Seconds()),
+	})
+}
+
+// reject aborts with 429 and a Retry-After header
+func (g *CodeGuard) reject(c *gin.Context, code, message string, retryAfter time.Duration) {
+	seconds := int(retryAfter.Seconds()) + 1
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"success": false,
+		"error": gin.H{
+			"code":    code,
+			"message": message,
+			"details": gin.H{
+				"retry_after_seconds": seconds,
+			},
+		},
+	})
+}
@@ -128,13 +128,15 @@ type Coupon struct {
 	MaxItemCount      *int `json:"maxItemCount,omitempty"`
 
 	// Target Criteria (stored as JSON arrays)
-	ExcludedTenants *JSON `json:"excludedTenants,omitempty" gorm:"type:jsonb"`
-	ExcludedVendors *JSON `json:"excludedVendors,omitempty" gorm:"type:jsonb"`
-	CategoryIDs     *JSON `json:"categoryIds,omitempty" gorm:"type:jsonb"`
-	ProductIDs      *JSON `json:"productIds,omitempty" gorm:"type:jsonb"`
-	UserGroupIDs    *JSON `json:"userGroupIds,omitempty" gorm:"type:jsonb"`
-	CountryCodes    *JSON `json:"countryCodes,omitempty" gorm:"type:jsonb"`
-	RegionCodes     *JSON `json:"regionCodes,omitempty" gorm:"type:jsonb"`
+	ExcludedTenants    *JSON `json:"excludedTenants,omitempty" gorm:"type:jsonb"`
+	ExcludedVendors    *JSON `json:"excludedVendors,omitempty" gorm:"type:jsonb"`
+	CategoryIDs        *JSON `json:"categoryIds,omitempty" gorm:"type:jsonb"`
+	ProductIDs         *JSON `json:"productIds,omitempty" gorm:"type:jsonb"`
+	UserGroupIDs       *JSON `json:"userGroupIds,omitempty" gorm:"type:jsonb"`
+	CountryCodes       *JSON `json:"countryCodes,omitempty" gorm:"type:jsonb"`
+	RegionCodes        *JSON `json:"regionCodes,omitempty" gorm:"type:jsonb"`
+	CustomerIDs        *JSON `json:"customerIds,omitempty" gorm:"type:jsonb"`        // Only these customers can use the coupon
+	CustomerSegmentIDs *JSON `json:"customerSegmentIds,omitempty" gorm:"type:jsonb"` // Only members of these customers-service segments
 
 	// Time Restrictions
 	ValidFrom   time.Time  `json:"validFrom" gorm:"not null"`
@@ -142,6 +144,12 @@ type Coupon struct {
 	DaysOfWeek  *JSON      `json:"daysOfWeek,omitempty" gorm:"type:jsonb"` // [1,2,3,4,5] for Mon-Fri
 	TimeWindows *JSON      `json:"timeWindows,omitempty" gorm:"type:jsonb"`
 
+	// Scheduling: recurring windows are evaluated in Timezone, and the schedule worker
+	// moves Status between SCHEDULED, ACTIVE and EXPIRED at NextTransitionAt
+	Timezone         string          `json:"timezone" gorm:"type:varchar(64);not null;default:'UTC'"`
+	RecurringWindows ScheduleWindows `json:"recurringWindows,omitempty" gorm:"type:jsonb"`
+	NextTransitionAt *time.Time      `json:"nextTransitionAt,omitempty" gorm:"index"`
+
 	// Payment and Stacking
 	AllowedPaymentMethods *JSON             `json:"allowedPaymentMethods,omitempty" gorm:"type:jsonb"`
 	StackableWithOther    bool              `json:"stackableWithOther" gorm:"default:false"`
@@ -187,7 +195,7 @@ type CouponUsage struct {
 
 // CreateCouponRequest represents a request to create a new coupon
 type CreateCouponRequest struct {
-	Code                  string             `json:"code" binding:"required"`
+	Code                  string             `json:"code"` // Generated in the configured code format when empty
 	Description           *string            `json:"description,omitempty"`
 	DisplayText           *string            `json:"displayText,omitempty"`
 	ImageURL              *string            `json:"imageUrl,omitempty"`
@@ -213,9 +221,13 @@ type CreateCouponRequest struct {
 	UserGroupIDs          []string           `json:"userGroupIds,omitempty"`
 	CountryCodes          []string           `json:"countryCodes,omitempty"`
 	RegionCodes           []string           `json:"regionCodes,omitempty"`
+	CustomerIDs           []string           `json:"customerIds,omitempty"`
+	CustomerSegmentIDs    []string           `json:"customerSegmentIds,omitempty"`
 	ValidFrom             time.Time          `json:"validFrom" binding:"required"`
 	ValidUntil            *time.Time         `json:"validUntil,omitempty"`
 	DaysOfWeek            []int              `json:"daysOfWeek,omitempty"`
+	Timezone              *string            `json:"timezone,omitempty"`         // IANA name, e.g. Asia/Kolkata; default UTC
+	RecurringWindows      []ScheduleWindow   `json:"recurringWindows,omitempty"` // e.g. weekends only or a daily happy hour
 	AllowedPaymentMethods []PaymentMethod    `json:"allowedPaymentMethods,omitempty"`
 	StackableWithOther    *bool              `json:"stackableWithOther,omitempty"`
 	StackablePriority     *int               `json:"stackablePriority,omitempty"`
@@ -250,8 +262,13 @@ type UpdateCouponRequest struct {
 	UserGroupIDs          []string           `json:"userGroupIds,omitempty"`
 	CountryCodes          []string           `json:"countryCodes,omitempty"`
 	RegionCodes           []string           `json:"regionCodes,omitempty"`
+	CustomerIDs           []string           `json:"customerIds,omitempty"`
+	CustomerSegmentIDs    []string           `json:"customerSegmentIds,omitempty"`
+	ValidFrom             *time.Time         `json:"validFrom,omitempty"`
 	ValidUntil            *time.Time         `json:"validUntil,omitempty"`
 	DaysOfWeek            []int              `json:"daysOfWeek,omitempty"`
+	Timezone              *string            `json:"timezone,omitempty"`         // IANA name, e.g. Asia/Kolkata; default UTC
+	RecurringWindows      []ScheduleWindow   `json:"recurringWindows,omitempty"` // e.g. weekends only or a daily happy hour
 	AllowedPaymentMethods []PaymentMethod    `json:"allowedPaymentMethods,omitempty"`
 	StackableWithOther    *bool              `json:"stackableWithOther,omitempty"`
 	StackablePriority     *int               `json:"stackablePriority,omitempty"`
@@ -261,6 +278,23 @@ type UpdateCouponRequest struct {
 	Tags                  []string           `json:"tags,omitempty"`
 }
 
+// BulkCreateCouponsRequest represents a request to create several coupons. Each coupon is
+// validated on its own so one bad coupon doesn't fail the rest.
+type BulkCreateCouponsRequest struct {
+	Coupons []CreateCouponRequest `json:"coupons" binding:"required,min=1"`
+}
+
+// BulkUpdateCouponItem is one coupon's changes in a bulk update
+type BulkUpdateCouponItem struct {
+	ID uuid.UUID `json:"id"`
+	UpdateCouponRequest
+}
+
+// BulkUpdateCouponsRequest represents a request to update several coupons
+type BulkUpdateCouponsRequest struct {
+	Coupons []BulkUpdateCouponItem `json:"coupons" binding:"required,min=1"`
+}
+
 // ValidateCouponRequest represents a request to validate a coupon
 type ValidateCouponRequest struct {
 	Code              string          `json:"code" binding:"required"`
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportMappingPreset is a saved column mapping for an importer, so tenants whose
+// exports use different headers don't have to remap columns on every upload.
+// Mapping keys are normalized source headers, values are template column names.
+type ImportMappingPreset struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID  string    `json:"tenantId" gorm:"not null;uniqueIndex:idx_import_mapping_presets_name"`
+	Entity    string    `json:"entity" gorm:"not null;uniqueIndex:idx_import_mapping_presets_name"`
+	Name      string    `json:"name" gorm:"not null;uniqueIndex:idx_import_mapping_presets_name"`
+	Mapping   JSON      `json:"mapping" gorm:"type:jsonb;not null"`
+	CreatedBy *string   `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName returns the table name for the ImportMappingPreset model
+func (ImportMappingPreset) TableName() string {
+	return "import_mapping_presets"
+}
+
+// SaveImportMappingPresetRequest creates or replaces a named mapping preset
+type SaveImportMappingPresetRequest struct {
+	Entity  string            `json:"entity"`
+	Name    string            `json:"name" binding:"required"`
+	Mapping map[string]string `json:"mapping" binding:"required"`
+}
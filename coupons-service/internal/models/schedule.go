@@ -0,0 +1,222 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimezone is used for coupons created without a timezone
+const DefaultTimezone = "UTC"
+
+// ScheduleWindow is a recurring window in the coupon's timezone during which the coupon
+// is in effect, e.g. weekends or a daily happy hour
+type ScheduleWindow struct {
+	Days      []int  `json:"days,omitempty"`      // 1-7 for Mon-Sun (0 is also Sunday); empty = every day
+	StartTime string `json:"startTime,omitempty"` // HH:MM, empty = start of day
+	EndTime   string `json:"endTime,omitempty"`   // HH:MM, empty or 24:00 = end of day; before StartTime = ends next day
+}
+
+// ScheduleWindows is a list of recurring windows stored as JSONB
+type ScheduleWindows []ScheduleWindow
+
+func (w ScheduleWindows) Value() (driver.Value, error) {
+	if w == nil {
+		return nil, nil
+	}
+	return json.Marshal(w)
+}
+
+func (w *ScheduleWindows) Scan(value interface{}) error {
+	if value == nil {
+		*w = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, w)
+}
+
+// scheduleLookahead bounds how far ahead recurring windows are expanded
+const scheduleLookahead = 8 * 24 * time.Hour
+
+// ValidateSchedule checks a timezone and its recurring windows
+func ValidateSchedule(timezone string, windows []ScheduleWindow) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", timezone)
+	}
+	for i, w := range windows {
+		for _, day := range w.Days {
+			if day < 0 || day > 7 {
+				return fmt.Errorf("window %d: day %d must be between 1 (Monday) and 7 (Sunday)", i, day)
+			}
+		}
+		start, err := parseClock(w.StartTime, 0)
+		if err != nil {
+			return fmt.Errorf("window %d: %v", i, err)
+		}
+		end, err := parseClock(w.EndTime, 24*60)
+		if err != nil {
+			return fmt.Errorf("window %d: %v", i, err)
+		}
+		if start == end {
+			return fmt.Errorf("window %d: start and end time must differ", i)
+		}
+	}
+	return nil
+}
+
+// parseClock parses HH:MM into minutes after midnight
+func parseClock(clock string, empty int) (int, error) {
+	if clock == "" {
+		return empty, nil
+	}
+	parts := strings.Split(clock, ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("time %q must be HH:MM", clock)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("time %q must be HH:MM", clock)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 || hours < 0 || hours > 24 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("time %q must be HH:MM", clock)
+	}
+	return hours*60 + minutes, nil
+}
+
+// appliesOn reports whether the window recurs on a weekday
+func (w ScheduleWindow) appliesOn(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if time.Weekday(d%7) == day {
+			return true
+		}
+	}
+	return false
+}
+
+type scheduleInterval struct {
+	start, end time.Time
+}
+
+// windowIntervals expands recurring windows into merged intervals around now, so that
+// back-to-back windows (e.g. Saturday and Sunday) form one interval
+func windowIntervals(windows []ScheduleWindow, loc *time.Location, now time.Time) []scheduleInterval {
+	local := now.In(loc)
+	first := time.Date(local.Year(), local.Month(), local.Day()-1, 0, 0, 0, 0, loc)
+
+	var intervals []scheduleInterval
+	for day := first; day.Before(now.Add(scheduleLookahead)); day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc) {
+		for _, w := range windows {
+			if !w.appliesOn(day.Weekday()) {
+				continue
+			}
+			startMin, err := parseClock(w.StartTime, 0)
+			if err != nil {
+				continue
+			}
+			endMin, err := parseClock(w.EndTime, 24*60)
+			if err != nil || startMin == endMin {
+				continue
+			}
+			if endMin < startMin {
+				endMin += 24 * 60
+			}
+			intervals = append(intervals, scheduleInterval{
+				start: time.Date(day.Year(), day.Month(), day.Day(), 0, startMin, 0, 0, loc),
+				end:   time.Date(day.Year(), day.Month(), day.Day(), 0, endMin, 0, 0, loc),
+			})
+		}
+	}
+
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start.Before(intervals[j].start) })
+	var merged []scheduleInterval
+	for _, interval := range intervals {
+		if n := len(merged); n > 0 && !interval.start.After(merged[n-1].end) {
+			if interval.end.After(merged[n-1].end) {
+				merged[n-1].end = interval.end
+			}
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	return merged
+}
+
+// Location returns the coupon's timezone, falling back to UTC
+func (c *Coupon) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ScheduleState returns whether the coupon's schedule puts it in effect at now — ACTIVE,
+// SCHEDULED (not started or between recurring windows) or EXPIRED — and when that next
+// changes. The next transition is nil when the state can't change anymore.
+func (c *Coupon) ScheduleState(now time.Time) (CouponStatus, *time.Time) {
+	if c.ValidUntil != nil && !now.Before(*c.ValidUntil) {
+		return StatusExpired, nil
+	}
+	if now.Before(c.ValidFrom) {
+		next := c.ValidFrom
+		return StatusScheduled, &next
+	}
+	if len(c.RecurringWindows) == 0 {
+		return StatusActive, c.ValidUntil
+	}
+
+	status := StatusScheduled
+	var next *time.Time
+	for _, interval := range windowIntervals(c.RecurringWindows, c.Location(), now) {
+		if !interval.end.After(now) {
+			continue
+		}
+		if !interval.start.After(now) {
+			status = StatusActive
+			next = &interval.end
+		} else {
+			next = &interval.start
+		}
+		break
+	}
+
+	if c.ValidUntil != nil && (next == nil || c.ValidUntil.Before(*next)) {
+		next = c.ValidUntil
+	}
+	return status, next
+}
+
+// IsScheduleManaged reports whether the coupon's status follows its schedule. INACTIVE
+// and FULLY_REDEEMED coupons are left alone.
+func (c *Coupon) IsScheduleManaged() bool {
+	return c.Status == StatusActive || c.Status == StatusScheduled || c.Status == StatusExpired
+}
+
+// RefreshSchedule moves a schedule-managed coupon to the status its schedule gives at now
+// and records when it next changes. It reports whether the status changed.
+func (c *Coupon) RefreshSchedule(now time.Time) bool {
+	if !c.IsScheduleManaged() {
+		c.NextTransitionAt = nil
+		return false
+	}
+	status, next := c.ScheduleState(now)
+	changed := status != c.Status
+	c.Status = status
+	c.NextTransitionAt = next
+	return changed
+}
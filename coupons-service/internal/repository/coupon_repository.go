@@ -10,6 +10,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/Tesseract-Nexus/go-shared/cache"
+	"coupons-service/internal/bulk"
+	"coupons-service/internal/codes"
 	"coupons-service/internal/models"
 	"gorm.io/gorm"
 )
@@ -22,15 +24,17 @@ const (
 )
 
 type CouponRepository struct {
-	db    *gorm.DB
-	redis *redis.Client
-	cache *cache.CacheLayer
+	db         *gorm.DB
+	redis      *redis.Client
+	cache      *cache.CacheLayer
+	codeFormat codes.Format
 }
 
-func NewCouponRepository(db *gorm.DB, redisClient *redis.Client) *CouponRepository {
+func NewCouponRepository(db *gorm.DB, redisClient *redis.Client, codeFormat codes.Format) *CouponRepository {
 	repo := &CouponRepository{
-		db:    db,
-		redis: redisClient,
+		db:         db,
+		redis:      redisClient,
+		codeFormat: codeFormat,
 	}
 
 	// Initialize CacheLayer with the existing Redis client
@@ -140,6 +144,28 @@ func (r *CouponRepository) GetCouponByID(tenantID string, id uuid.UUID) (*models
 	return &coupon, nil
 }
 
+// GenerateUniqueCode generates a coupon code in the configured format that the tenant
+// doesn't use yet
+func (r *CouponRepository) GenerateUniqueCode(tenantID string) (string, error) {
+	for i := 0; i < 10; i++ {
+		code, err := r.codeFormat.Generate()
+		if err != nil {
+			return "", err
+		}
+
+		var count int64
+		err = r.db.Unscoped().Model(&models.Coupon{}).Where("tenant_id = ? AND code = ?", tenantID, code).Count(&count).Error
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return code, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to generate unique code after 10 attempts")
+}
+
 // GetCouponByCode retrieves a coupon by code (with caching - critical for validation)
 func (r *CouponRepository) GetCouponByCode(tenantID, code string) (*models.Coupon, error) {
 	ctx := context.Background()
@@ -204,6 +230,50 @@ func (r *CouponRepository) DeleteCoupon(tenantID string, id uuid.UUID) error {
 	return err
 }
 
+// GetDeletedCoupons retrieves a paginated list of soft-deleted coupons, most recently deleted first
+func (r *CouponRepository) GetDeletedCoupons(tenantID string, page, limit int) ([]models.Coupon, int64, error) {
+	var coupons []models.Coupon
+	var total int64
+
+	query := r.db.Unscoped().Model(&models.Coupon{}).
+		Where("tenant_id = ? AND deleted_at IS NOT NULL", tenantID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("deleted_at DESC").Find(&coupons).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return coupons, total, nil
+}
+
+// RestoreCoupon restores a soft-deleted coupon. Returns nil if the coupon is not in the trash.
+func (r *CouponRepository) RestoreCoupon(tenantID string, id uuid.UUID) (*models.Coupon, error) {
+	var coupon models.Coupon
+	err := r.db.Unscoped().
+		Where("tenant_id = ? AND id = ? AND deleted_at IS NOT NULL", tenantID, id).
+		First(&coupon).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := r.db.Unscoped().Model(&models.Coupon{}).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		Update("deleted_at", nil).Error; err != nil {
+		return nil, err
+	}
+
+	coupon.DeletedAt = nil
+	r.invalidateCouponCaches(context.Background(), tenantID, id, coupon.Code)
+	return &coupon, nil
+}
+
 // GetCouponList retrieves a paginated list of coupons with filters
 func (r *CouponRepository) GetCouponList(tenantID string, filters *models.CouponFilters, page, limit int) ([]models.Coupon, int64, error) {
 	var coupons []models.Coupon
@@ -427,31 +497,74 @@ func (r *CouponRepository) applyFilters(query *gorm.DB, filters *models.CouponFi
 	return query
 }
 
-// BulkCreateCoupons creates multiple coupons in a transaction
-func (r *CouponRepository) BulkCreateCoupons(coupons []models.Coupon) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		for _, coupon := range coupons {
-			if err := tx.Create(&coupon).Error; err != nil {
-				return err
-			}
-		}
-		return nil
+// BulkWrite applies count items of a bulk request with partial success. apply gets a
+// repository that writes through the current chunk's transaction; caches of the applied
+// coupons are cleared once their chunk has committed.
+func (r *CouponRepository) BulkWrite(count int, opts bulk.Options, apply func(repo *CouponRepository, index int) (bulk.Outcome, error)) *bulk.Response {
+	result := bulk.Run(r.db, count, opts, func(tx *gorm.DB, index int) (bulk.Outcome, error) {
+		txRepo := *r
+		txRepo.db = tx
+		return apply(&txRepo, index)
 	})
-}
 
-// BulkUpdateCoupons updates multiple coupons in a transaction
-func (r *CouponRepository) BulkUpdateCoupons(tenantID string, updates []struct {
-	ID   uuid.UUID
-	Data map[string]interface{}
-}) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		for _, update := range updates {
-			if err := tx.Model(&models.Coupon{}).
-				Where("tenant_id = ? AND id = ?", tenantID, update.ID).
-				Updates(update.Data).Error; err != nil {
-				return err
-			}
+	for _, data := range result.Applied() {
+		if coupon, ok := data.(*models.Coupon); ok {
+			r.invalidateCouponCaches(context.Background(), coupon.TenantID, coupon.ID, coupon.Code)
 		}
-		return nil
-	})
+	}
+	return result
+}
+
+// GetCouponsDueForTransition retrieves schedule-managed coupons, across tenants, whose
+// schedule changes their status at or before now
+func (r *CouponRepository) GetCouponsDueForTransition(now time.Time, limit int) ([]models.Coupon, error) {
+	var coupons []models.Coupon
+	err := r.db.
+		Where("next_transition_at <= ?", now).
+		Where("status IN ?", []models.CouponStatus{models.StatusScheduled, models.StatusActive, models.StatusExpired}).
+		Order("next_transition_at ASC").
+		Limit(limit).
+		Find(&coupons).Error
+	return coupons, err
+}
+
+// TransitionCoupon saves a coupon's schedule-driven status if it is still previousStatus,
+// so only one worker replica applies (and announces) each transition
+func (r *CouponRepository) TransitionCoupon(coupon *models.Coupon, previousStatus models.CouponStatus) (bool, error) {
+	result := r.db.Model(&models.Coupon{}).
+		Where("tenant_id = ? AND id = ? AND status = ?", coupon.TenantID, coupon.ID, previousStatus).
+		Updates(map[string]interface{}{
+			"status":             coupon.Status,
+			"next_transition_at": coupon.NextTransitionAt,
+			"updated_at":         time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	r.invalidateCouponCaches(context.Background(), coupon.TenantID, coupon.ID, coupon.Code)
+	return true, nil
+}
+
+// GetTargetedCoupons retrieves a tenant's active coupons targeted at a customer directly or
+// through one of their segments. First-purchase coupons are left out unless firstPurchase.
+func (r *CouponRepository) GetTargetedCoupons(tenantID, customerID string, segmentIDs []string, firstPurchase bool, now time.Time) ([]models.Coupon, error) {
+	// Target lists are stored as index-keyed JSON objects
+	targeted := r.db.Where("jsonb_typeof(customer_ids) = 'object' AND EXISTS (SELECT 1 FROM jsonb_each_text(customer_ids) AS t(key, value) WHERE t.value = ?)", customerID)
+	if len(segmentIDs) > 0 {
+		targeted = targeted.Or("jsonb_typeof(customer_segment_ids) = 'object' AND EXISTS (SELECT 1 FROM jsonb_each_text(customer_segment_ids) AS t(key, value) WHERE t.value IN ?)", segmentIDs)
+	}
+
+	query := r.db.Where("tenant_id = ? AND is_active = ? AND status = ?", tenantID, true, models.StatusActive).
+		Where("valid_from <= ? AND (valid_until IS NULL OR valid_until > ?)", now, now).
+		Where(targeted)
+	if !firstPurchase {
+		query = query.Where("first_time_user_only = ?", false)
+	}
+
+	var coupons []models.Coupon
+	err := query.Order("valid_until ASC NULLS LAST").Find(&coupons).Error
+	return coupons, err
 }
@@ -0,0 +1,100 @@
+// Package workers provides background job processors for the coupons service.
+package workers
+
+import (
+	"context"
+	"time"
+
+	"coupons-service/internal/events"
+	"coupons-service/internal/models"
+	"coupons-service/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ScheduleCheckInterval is how often coupon schedules are checked for due transitions
+	ScheduleCheckInterval = 1 * time.Minute
+
+	// scheduleBatchSize is the number of due coupons processed per batch
+	scheduleBatchSize = 100
+)
+
+// ScheduleWorker activates and deactivates coupons as their schedules start, pause between
+// recurring windows and expire, and publishes coupon.activated/deactivated events so
+// storefronts can drop cached coupons.
+type ScheduleWorker struct {
+	repo            *repository.CouponRepository
+	eventsPublisher *events.Publisher
+	logger          *logrus.Entry
+}
+
+// NewScheduleWorker creates a new coupon schedule worker
+func NewScheduleWorker(repo *repository.CouponRepository, eventsPublisher *events.Publisher, logger *logrus.Logger) *ScheduleWorker {
+	return &ScheduleWorker{
+		repo:            repo,
+		eventsPublisher: eventsPublisher,
+		logger:          logger.WithField("component", "workers.schedule"),
+	}
+}
+
+// Start runs the schedule check loop until ctx is done
+func (w *ScheduleWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(ScheduleCheckInterval)
+	defer ticker.Stop()
+
+	w.processDue(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			w.processDue(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// processDue applies every transition that is due, in batches
+func (w *ScheduleWorker) processDue(ctx context.Context) {
+	for {
+		now := time.Now()
+		coupons, err := w.repo.GetCouponsDueForTransition(now, scheduleBatchSize)
+		if err != nil {
+			w.logger.WithError(err).Error("Failed to fetch coupons due for schedule transition")
+			return
+		}
+
+		for i := range coupons {
+			w.transition(ctx, &coupons[i], now)
+		}
+		if len(coupons) < scheduleBatchSize {
+			return
+		}
+	}
+}
+
+// transition moves one coupon to its scheduled status and announces the change
+func (w *ScheduleWorker) transition(ctx context.Context, coupon *models.Coupon, now time.Time) {
+	previous := coupon.Status
+	changed := coupon.RefreshSchedule(now)
+
+	saved, err := w.repo.TransitionCoupon(coupon, previous)
+	if err != nil {
+		w.logger.WithError(err).WithField("coupon_id", coupon.ID).Error("Failed to save coupon schedule transition")
+		return
+	}
+	if !saved || !changed {
+		return
+	}
+
+	w.logger.WithFields(logrus.Fields{
+		"coupon_id": coupon.ID,
+		"tenant_id": coupon.TenantID,
+		"from":      previous,
+		"to":        coupon.Status,
+	}).Info("Coupon schedule transition applied")
+	if w.eventsPublisher != nil {
+		if err := w.eventsPublisher.PublishScheduleChange(ctx, coupon, previous); err != nil {
+			w.logger.WithError(err).WithField("coupon_id", coupon.ID).Warn("Failed to publish coupon schedule event")
+		}
+	}
+}
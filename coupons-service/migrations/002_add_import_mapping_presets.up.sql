@@ -0,0 +1,16 @@
+-- Import column mapping presets
+-- Tenants can save how their spreadsheet headers map to coupon import columns
+-- and reuse the preset on later imports.
+
+CREATE TABLE IF NOT EXISTS import_mapping_presets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    entity VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    mapping JSONB NOT NULL DEFAULT '{}',
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_import_mapping_presets_name ON import_mapping_presets(tenant_id, entity, name);
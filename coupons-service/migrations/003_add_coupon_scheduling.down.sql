@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_coupons_next_transition_at;
+ALTER TABLE coupons
+    DROP COLUMN IF EXISTS next_transition_at,
+    DROP COLUMN IF EXISTS recurring_windows,
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,18 @@
+-- Coupon scheduling
+-- Coupons carry a timezone and optional recurring windows (e.g. weekends only or a daily
+-- happy hour). The schedule worker moves status between SCHEDULED, ACTIVE and EXPIRED at
+-- next_transition_at and publishes coupon.activated/deactivated events.
+
+ALTER TABLE coupons
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    ADD COLUMN IF NOT EXISTS recurring_windows JSONB,  -- [{days, startTime, endTime}]
+    ADD COLUMN IF NOT EXISTS next_transition_at TIMESTAMP WITH TIME ZONE;
+
+-- Let the worker pick up existing coupons at their next start or expiry
+UPDATE coupons SET next_transition_at = valid_from
+    WHERE status = 'SCHEDULED' AND next_transition_at IS NULL;
+UPDATE coupons SET next_transition_at = valid_until
+    WHERE status = 'ACTIVE' AND valid_until IS NOT NULL AND next_transition_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_coupons_next_transition_at ON coupons(next_transition_at)
+    WHERE next_transition_at IS NOT NULL;
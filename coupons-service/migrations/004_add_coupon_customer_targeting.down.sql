@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_coupons_customer_segment_ids;
+DROP INDEX IF EXISTS idx_coupons_customer_ids;
+ALTER TABLE coupons
+    DROP COLUMN IF EXISTS customer_segment_ids,
+    DROP COLUMN IF EXISTS customer_ids;
@@ -0,0 +1,10 @@
+-- Customer targeting
+-- Coupons can be restricted to specific customers or to members of customers-service
+-- segments. Like the other target lists they are stored as index-keyed JSON objects.
+
+ALTER TABLE coupons
+    ADD COLUMN IF NOT EXISTS customer_ids JSONB,
+    ADD COLUMN IF NOT EXISTS customer_segment_ids JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_coupons_customer_ids ON coupons USING GIN (customer_ids);
+CREATE INDEX IF NOT EXISTS idx_coupons_customer_segment_ids ON coupons USING GIN (customer_segment_ids);
@@ -14,14 +14,21 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
 	"customers-service/internal/clients"
 	"customers-service/internal/config"
+	"customers-service/internal/database"
+	"customers-service/internal/encryption"
 	"customers-service/internal/events"
 	"customers-service/internal/handlers"
+	"customers-service/internal/jobqueue"
 	"customers-service/internal/middleware"
 	"customers-service/internal/models"
 	"customers-service/internal/repository"
+	"customers-service/internal/runtimeconfig"
+	"customers-service/internal/serviceauth"
 	"customers-service/internal/services"
+	"customers-service/internal/tenancy"
 	"customers-service/internal/workers"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -37,16 +44,41 @@ func main() {
 
 	// Load configuration
 	cfg := config.New()
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		runSeed(cfg, os.Args[2:])
+		return
+	}
 
-	// Initialize database
-	db, err := initDatabase(cfg.DatabaseURL)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return initDatabase(cfg.DatabaseURL) })
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
-	// Auto-migrate models
-	if err := autoMigrate(db); err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
+	// "customers-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
+	}
+
+	// Per-tenant field encryption for PII columns (disabled, i.e. plaintext, until a key is configured)
+	keyring, err := initFieldEncryption(cfg, db)
+	if err != nil {
+		log.Fatalf("Failed to initialize field encryption: %v", err)
+	}
+	if keyring != nil {
+		defer keyring.Close()
+		log.Println("✓ Field encryption enabled")
+	} else {
+		log.Println("WARNING: FIELD_ENCRYPTION_KMS_KEY not set, PII fields are stored unencrypted")
 	}
 
 	// Initialize Redis client (optional - graceful degradation if Redis unavailable)
@@ -80,6 +112,9 @@ func main() {
 	segmentRepo := repository.NewSegmentRepository(db)
 	abandonedCartRepo := repository.NewAbandonedCartRepository(db)
 	customerListRepo := repository.NewCustomerListRepository(db)
+	accountDeletionRepo := repository.NewAccountDeletionRepository(db)
+	customerImportRepo := repository.NewCustomerImportRepository(db)
+	securityEventRepo := repository.NewSecurityEventRepository(db)
 
 	// Initialize notification clients for email notifications
 	notificationClient := clients.NewNotificationClient()
@@ -91,14 +126,29 @@ func main() {
 	segmentService := services.NewSegmentService(segmentRepo)
 	abandonedCartService := services.NewAbandonedCartService(abandonedCartRepo, customerRepo, notificationClient, tenantClient)
 	customerListService := services.NewCustomerListService(customerListRepo)
+	accountDeletionService := services.NewAccountDeletionService(accountDeletionRepo, customerRepo, notificationClient, tenantClient,
+		cfg.AccountDeletionCoolingOff, cfg.AccountDeletionServices)
+	securityEventService := services.NewSecurityEventService(securityEventRepo, customerRepo)
+	customerService.SetSecurityEvents(securityEventService)
+	timelineService := services.NewTimelineService(customerRepo, clients.NewTimelineClient(), redisClient)
 
 	// Initialize segment evaluator for dynamic segment membership
 	segmentEvaluator := services.NewSegmentEvaluator(customerRepo, segmentRepo)
 	customerService.SetSegmentEvaluator(segmentEvaluator)
+	customerImportService := services.NewCustomerImportService(customerImportRepo, customerRepo, segmentRepo)
+	customerImportService.SetSegmentEvaluator(segmentEvaluator)
 	log.Println("✓ Dynamic segment evaluator initialized")
 
+	// Runtime config: tunables that can change without a rollout, from RUNTIME_CONFIG_FILE or
+	// overrides saved through /api/v1/admin/config
+	runtimeConfig := runtimeconfig.New("customers-service", redisClient, cfg.RuntimeConfigFile, logrus.WithField("component", "runtimeconfig"))
+	priceLockTTL := runtimeConfig.Duration("cart.price_lock_ttl", "How long a checkout price lock holds the cart's prices", cfg.CartPriceLockTTL)
+	runtimeConfigCtx, stopRuntimeConfig := context.WithCancel(context.Background())
+	go runtimeConfig.Start(runtimeConfigCtx)
+
 	// Initialize cart validation service
 	cartValidationService := services.NewCartValidationService(db)
+	cartValidationService.SetPriceLock(cfg.CartPriceLockSecret, priceLockTTL.Get)
 
 	// Initialize NATS events publisher
 	eventsPublisher, err := events.NewPublisher(nil) // Uses default logrus logger
@@ -106,6 +156,9 @@ func main() {
 		log.Printf("WARNING: Failed to initialize events publisher: %v (NATS events disabled)", err)
 	} else {
 		defer eventsPublisher.Close()
+		accountDeletionService.SetPublisher(eventsPublisher)
+		customerImportService.SetPublisher(eventsPublisher)
+		securityEventService.SetPublisher(eventsPublisher)
 		log.Println("✓ Events publisher initialized (NATS connected)")
 	}
 
@@ -115,13 +168,47 @@ func main() {
 	segmentHandler := handlers.NewSegmentHandler(segmentService)
 	paymentMethodHandler := handlers.NewPaymentMethodHandler(customerService)
 	wishlistHandler := handlers.NewWishlistHandler(db)
-	cartHandler := handlers.NewCartHandlerWithValidation(db, cartValidationService)
+	cartHandler := handlers.NewCartHandlerWithValidation(db, cartValidationService, eventsPublisher)
 	abandonedCartHandler := handlers.NewAbandonedCartHandler(abandonedCartService)
 	customerListHandler := handlers.NewCustomerListHandler(customerListService)
+	accountDeletionHandler := handlers.NewAccountDeletionHandler(accountDeletionService)
+	timelineHandler := handlers.NewTimelineHandler(timelineService)
+	customerImportHandler := handlers.NewCustomerImportHandler(customerImportService)
+	securityEventHandler := handlers.NewSecurityEventHandler(securityEventService)
 
 	// Initialize background workers
 	cartExpirationWorker := workers.NewCartExpirationWorker(db, 1*time.Hour)
 	cartValidationWorker := workers.NewCartValidationWorker(db, cartValidationService, 15*time.Minute)
+	// Abandoned cart retention is configured per tenant in staff-service
+	abandonedCartRetentionWorker := workers.NewAbandonedCartRetentionWorker(db, clients.NewRetentionClient(), workers.DefaultRetentionPurgeInterval)
+	accountDeletionWorker := workers.NewAccountDeletionWorker(accountDeletionService, workers.DefaultAccountDeletionInterval)
+	customerImportWorker := workers.NewCustomerImportWorker(customerImportRepo, customerImportService, workers.DefaultCustomerImportInterval)
+	var keyRotationWorker *workers.KeyRotationWorker
+	if keyring != nil {
+		keyRotationWorker = workers.NewKeyRotationWorker(db, keyring, cfg.FieldEncryptionRotationAge, workers.DefaultKeyRotationInterval,
+			&models.Customer{}, &models.CustomerAddress{})
+	}
+
+	// Cart sweeps run from the persistent job runner: one replica runs each sweep at a time,
+	// failures are retried, and platform owners can pause or trigger them from /api/v1/admin/jobs
+	jobRunner := jobqueue.NewRunner(db, logrus.WithField("component", "jobqueue"))
+	jobRunner.Register(jobqueue.Job{
+		Name:        "cart_expiration",
+		Description: "Deletes expired carts and removes cart items older than 90 days",
+		Interval:    workers.DefaultExpirationCheckInterval,
+		Run: func(ctx context.Context) error {
+			return cartExpirationWorker.ForceRun(tenancy.WithCrossTenant(ctx))
+		},
+	})
+	jobRunner.Register(jobqueue.Job{
+		Name:        "cart_validation",
+		Description: "Revalidates prices and availability of carts not validated recently",
+		Interval:    15 * time.Minute,
+		StartDelay:  30 * time.Second, // Let the service warm up first
+		Run: func(ctx context.Context) error {
+			return cartValidationWorker.ForceRun(tenancy.WithCrossTenant(ctx))
+		},
+	})
 
 	// Initialize product event subscriber for cart validation
 	productSubscriber, err := events.NewProductEventSubscriber(cartValidationService)
@@ -149,6 +236,24 @@ func main() {
 		log.Println("✓ Customer registration event subscriber initialized")
 	}
 
+	// Initialize account deletion subscriber
+	// This listens for customer.anonymized confirmations from the services taking part in account deletion
+	accountDeletionSubscriber, err := events.NewAccountDeletionSubscriber(accountDeletionService, nil)
+	if err != nil {
+		log.Printf("WARNING: Failed to initialize account deletion subscriber: %v (account deletions will not complete)", err)
+	} else {
+		log.Println("✓ Account deletion subscriber initialized")
+	}
+
+	// Initialize auth event subscriber
+	// This listens for logins, password changes and lockouts to build customers' security logs
+	authEventSubscriber, err := events.NewAuthEventSubscriber(securityEventService, nil)
+	if err != nil {
+		log.Printf("WARNING: Failed to initialize auth event subscriber: %v (login auditing disabled)", err)
+	} else {
+		log.Println("✓ Auth event subscriber initialized")
+	}
+
 	// Initialize OpenTelemetry tracing
 	var tracerProvider *tracing.TracerProvider
 	var tracerErr error
@@ -172,9 +277,24 @@ func main() {
 	if staffServiceURL == "" {
 		staffServiceURL = "http://staff-service:8080"
 	}
-	rbacMiddleware := rbac.NewMiddlewareWithURL(staffServiceURL, nil)
+	rbacClient := rbac.NewClient(staffServiceURL)
+	rbacMiddleware := rbac.NewMiddleware(rbacClient, nil)
 	log.Println("✓ RBAC middleware initialized")
 
+	// Reuse effective permissions for RBAC_CACHE_TTL, dropping them early when staff-service
+	// publishes rbac.permissions_changed
+	rbacCacheTTL, _ := time.ParseDuration(os.Getenv("RBAC_CACHE_TTL"))
+	rbacCache := middleware.NewRBACPermissionCache(rbacClient, rbacCacheTTL)
+	go rbacCache.Start(context.Background())
+	rbacSubscriber, err := events.NewRBACSubscriber(rbacCache)
+	if err != nil {
+		log.Printf("WARNING: Failed to initialize RBAC subscriber: %v (permission changes apply once cached permissions expire)", err)
+	} else if err := rbacSubscriber.Start(); err != nil {
+		log.Printf("WARNING: RBAC subscriber failed to start: %v", err)
+	} else {
+		log.Println("✓ RBAC permission cache invalidation subscriber started")
+	}
+
 	// Set up Gin router
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -186,9 +306,11 @@ func main() {
 	// Security headers middleware
 	router.Use(gosharedmw.SecurityHeaders())
 
-	// Rate limiting middleware (uses Redis for distributed rate limiting)
+	// Rate limiting middleware (uses Redis for distributed rate limiting; profiles are managed in staff-service)
 	if redisClient != nil {
-		router.Use(gosharedmw.RedisRateLimitMiddlewareWithProfile(redisClient, "standard"))
+		rateLimiter := middleware.NewProfileRateLimiter(redisClient)
+		go rateLimiter.Start(context.Background())
+		router.Use(rateLimiter.Middleware())
 		log.Println("✓ Redis-based rate limiting enabled")
 	} else {
 		router.Use(gosharedmw.RateLimit())
@@ -222,18 +344,24 @@ func main() {
 	// In production: Use Istio JWT claim headers (x-jwt-claim-*)
 	// In development: Use simple header-based auth for testing
 	if cfg.Environment == "production" {
-		router.Use(istioAuth)
+		// Requests with an X-API-Key header are authenticated with the tenant API key instead
+		router.Use(middleware.APIKeyAuth(staffServiceURL, istioAuth))
 		// TenantMiddleware ensures tenant_id is always extracted from X-Tenant-ID header
 		// This is critical when Istio JWT claim headers are not present (e.g., BFF requests)
 		router.Use(middleware.TenantMiddleware())
 		router.Use(gosharedmw.VendorScopeFilter())
+		// Admins with an X-Impersonation-Token act as the session's vendor or staff member
+		router.Use(middleware.ImpersonationAuth(staffServiceURL))
+		router.Use(middleware.TenantScopeMiddleware())
 		log.Println("✓ Using Istio auth middleware (production mode)")
 	} else {
 		// Development mode: use simple header extraction
 		router.Use(middleware.TenantMiddleware())
 		router.Use(middleware.UserMiddleware())
+		router.Use(middleware.TenantScopeMiddleware())
 		log.Println("✓ Using development auth middleware")
 	}
+	router.Use(rbacCache.Track())
 
 	// Health endpoints
 	router.GET("/health", healthHandler.HealthCheck)
@@ -254,9 +382,21 @@ func main() {
 			customers.POST("", rbacMiddleware.RequirePermission(rbac.PermissionCustomersCreate), customerHandler.CreateCustomer)
 			customers.GET("", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), customerHandler.ListCustomers)
 			customers.GET("/batch", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), customerHandler.BatchGetCustomers)
+			customers.GET("/trash", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), customerHandler.ListTrashedCustomers)
+
+			// Bulk import (CSV/XLSX, processed asynchronously)
+			customers.GET("/import/template", rbacMiddleware.RequirePermission(rbac.PermissionCustomersCreate), customerImportHandler.GetImportTemplate)
+			customers.POST("/import/preview", rbacMiddleware.RequirePermission(rbac.PermissionCustomersCreate), customerImportHandler.PreviewImport)
+			customers.POST("/import", rbacMiddleware.RequirePermission(rbac.PermissionCustomersCreate), customerImportHandler.StartImport)
+			customers.GET("/import/jobs", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), customerImportHandler.ListImportJobs)
+			customers.GET("/import/jobs/:jobId", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), customerImportHandler.GetImportJob)
+			customers.GET("/import/jobs/:jobId/errors", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), customerImportHandler.GetImportErrors)
+			customers.POST("/import/jobs/:jobId/resume", rbacMiddleware.RequirePermission(rbac.PermissionCustomersCreate), customerImportHandler.ResumeImportJob)
+
 			customers.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), customerHandler.GetCustomer)
 			customers.PUT("/:id", rbacMiddleware.RequirePermission(rbac.PermissionCustomersUpdate), customerHandler.UpdateCustomer)
 			customers.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionCustomersDelete), customerHandler.DeleteCustomer)
+			customers.POST("/:id/restore", rbacMiddleware.RequirePermission(rbac.PermissionCustomersDelete), customerHandler.RestoreCustomer)
 
 			// Customer addresses
 			customers.POST("/:id/addresses", rbacMiddleware.RequirePermission(rbac.PermissionCustomersUpdate), customerHandler.AddAddress)
@@ -272,6 +412,9 @@ func main() {
 			// Communication history
 			customers.GET("/:id/communications", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), customerHandler.GetCommunicationHistory)
 
+			// Unified timeline for the support agent panel
+			customers.GET("/:id/timeline", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), timelineHandler.GetTimeline)
+
 			// Order stats - called after order placement
 			customers.POST("/:id/record-order", rbacMiddleware.RequirePermission(rbac.PermissionCustomersUpdate), customerHandler.RecordOrder)
 
@@ -284,8 +427,12 @@ func main() {
 
 			// Payment methods
 			customers.GET("/:id/payment-methods", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), paymentMethodHandler.GetPaymentMethods)
+			customers.POST("/:id/payment-methods", rbacMiddleware.RequirePermission(rbac.PermissionCustomersUpdate), paymentMethodHandler.AddPaymentMethod)
 			customers.DELETE("/:id/payment-methods/:methodId", rbacMiddleware.RequirePermission(rbac.PermissionCustomersUpdate), paymentMethodHandler.DeletePaymentMethod)
 
+			// Security audit log (logins, password and payment method changes, suspicious activity)
+			customers.GET("/:id/security-events", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), securityEventHandler.ListSecurityEvents)
+
 			// Wishlist
 			customers.GET("/:id/wishlist", rbacMiddleware.RequirePermission(rbac.PermissionCustomersRead), wishlistHandler.GetWishlist)
 			customers.POST("/:id/wishlist", rbacMiddleware.RequirePermission(rbac.PermissionCustomersUpdate), wishlistHandler.AddToWishlist)
@@ -354,16 +501,31 @@ func main() {
 			segments.POST("/:id/customers", rbacMiddleware.RequirePermission(rbac.PermissionCustomersUpdate), segmentHandler.AddCustomersToSegment)
 			segments.DELETE("/:id/customers", rbacMiddleware.RequirePermission(rbac.PermissionCustomersUpdate), segmentHandler.RemoveCustomersFromSegment)
 		}
+
+		// Background jobs and runtime config - platform owners only, since both span every
+		// tenant. Config requests must also be signed with CONFIG_ADMIN_SECRET.
+		admin := v1.Group("/admin")
+		admin.Use(rbacMiddleware.RequireMinPriority(jobqueue.PlatformOwnerPriority))
+		jobqueue.NewHandler(jobRunner).RegisterRoutes(admin)
+		runtimeconfig.NewHandler(runtimeConfig, cfg.ConfigAdminSecret).RegisterRoutes(admin)
 	}
 
 	// Internal endpoints for service-to-service calls (no RBAC)
-	// These are used by CronJobs and internal services
+	// These are used by CronJobs and internal services, each route allowing only its callers
+	internalAuth := serviceauth.New(serviceauth.ConfigFromEnv("customers-service"), nil)
 	internal := router.Group("/internal")
 	{
-		// Abandoned cart detection - called by CronJob
-		internal.POST("/abandoned-carts/detect", abandonedCartHandler.TriggerDetection)
-		internal.POST("/abandoned-carts/send-reminders", abandonedCartHandler.TriggerReminders)
-		internal.POST("/abandoned-carts/expire", abandonedCartHandler.ExpireOldCarts)
+		// Abandoned cart detection - called by CronJob, which runs as customers-service
+		cronOnly := internalAuth.Require("customers-service")
+		internal.POST("/abandoned-carts/detect", cronOnly, abandonedCartHandler.TriggerDetection)
+		internal.POST("/abandoned-carts/send-reminders", cronOnly, abandonedCartHandler.TriggerReminders)
+		internal.POST("/abandoned-carts/expire", cronOnly, abandonedCartHandler.ExpireOldCarts)
+
+		// Coupon targeting - called by coupons-service
+		internal.GET("/customers/:id/targeting", internalAuth.Require("coupons-service"), segmentHandler.GetCustomerTargeting)
+
+		// Segment audience export - called by marketing-service
+		internal.GET("/customers/segments/:id/audience", internalAuth.Require("marketing-service"), segmentHandler.GetSegmentAudience)
 	}
 
 	// Public/Storefront endpoints for customer-facing operations
@@ -373,6 +535,8 @@ func main() {
 	publicCustomers := storefront.Group("/customers")
 	publicCustomers.Use(middleware.CustomerAuthMiddleware(db))
 	publicCustomers.Use(middleware.RequireSameCustomer())
+	// CustomerAuthMiddleware may resolve the customer's tenant, so re-scope the request context
+	publicCustomers.Use(middleware.TenantScopeMiddleware())
 	{
 		// Cart (for storefront use - customers managing their own cart)
 		publicCustomers.GET("/:id/cart", cartHandler.GetCart)
@@ -416,12 +580,25 @@ func main() {
 		// Profile (for storefront use - customers viewing/updating their own profile)
 		publicCustomers.GET("/:id", customerHandler.GetCustomer)
 		publicCustomers.PATCH("/:id", customerHandler.UpdateCustomer)
+
+		// Account deletion (request, confirm from the emailed link, cancel during cooling-off, status)
+		publicCustomers.GET("/:id/deletion", accountDeletionHandler.GetDeletionStatus)
+		publicCustomers.POST("/:id/deletion", accountDeletionHandler.RequestDeletion)
+		publicCustomers.POST("/:id/deletion/confirm", accountDeletionHandler.ConfirmDeletion)
+		publicCustomers.POST("/:id/deletion/cancel", accountDeletionHandler.CancelDeletion)
 	}
 	log.Println("✓ Public storefront endpoints initialized")
 
 	// Start background workers
-	cartExpirationWorker.Start()
-	cartValidationWorker.Start()
+	if err := jobRunner.Start(); err != nil {
+		log.Fatalf("Failed to start background job runner: %v", err)
+	}
+	abandonedCartRetentionWorker.Start()
+	accountDeletionWorker.Start()
+	customerImportWorker.Start()
+	if keyRotationWorker != nil {
+		keyRotationWorker.Start()
+	}
 	log.Println("✓ Background workers started")
 
 	// Start event subscribers
@@ -454,6 +631,28 @@ func main() {
 		}
 	}
 
+	// Start account deletion subscriber for anonymization confirmations
+	if accountDeletionSubscriber != nil {
+		ctx := context.Background()
+		if err := accountDeletionSubscriber.Start(ctx); err != nil {
+			log.Printf("WARNING: Failed to start account deletion subscriber: %v", err)
+		} else {
+			log.Println("✓ Account deletion subscriber started (listening for customer.anonymized events)")
+		}
+		defer accountDeletionSubscriber.Stop()
+	}
+
+	// Start auth event subscriber for customer security logs
+	if authEventSubscriber != nil {
+		ctx := context.Background()
+		if err := authEventSubscriber.Start(ctx); err != nil {
+			log.Printf("WARNING: Failed to start auth event subscriber: %v", err)
+		} else {
+			log.Println("✓ Auth event subscriber started (listening for auth events)")
+		}
+		defer authEventSubscriber.Stop()
+	}
+
 	// Start server
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
@@ -475,21 +674,38 @@ func main() {
 
 	log.Println("Shutting down customers-service...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
+	// Stop accepting connections and let in-flight requests finish before stopping workers
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
 	// Stop background workers
-	cartExpirationWorker.Stop()
-	cartValidationWorker.Stop()
+	jobRunner.Stop()
+	abandonedCartRetentionWorker.Stop()
+	accountDeletionWorker.Stop()
+	customerImportWorker.Stop()
+	if keyRotationWorker != nil {
+		keyRotationWorker.Stop()
+	}
 	log.Println("✓ Background workers stopped")
 
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+	stopRuntimeConfig()
+	rbacCache.Stop()
+	if rbacSubscriber != nil {
+		rbacSubscriber.Stop()
+	}
+	if productSubscriber != nil {
+		productSubscriber.Stop()
 	}
 
 	// Shutdown tracer provider
 	if tracerProvider != nil {
-		if err := tracerProvider.Shutdown(ctx); err != nil {
+		tracerCtx, tracerCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer tracerCancel()
+		if err := tracerProvider.Shutdown(tracerCtx); err != nil {
 			log.Printf("Error shutting down tracer provider: %v", err)
 		} else {
 			log.Println("✓ Tracer provider shut down")
@@ -505,6 +721,11 @@ func initDatabase(databaseURL string) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database instance: %w", err)
@@ -517,21 +738,30 @@ func initDatabase(databaseURL string) (*gorm.DB, error) {
 	return db, nil
 }
 
-func autoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&models.Customer{},
-		&models.CustomerAddress{},
-		&models.CustomerPaymentMethod{},
-		&models.CustomerSegment{},
-		&repository.CustomerSegmentMember{},
-		&models.CustomerNote{},
-		&models.CustomerCommunication{},
-		&models.CustomerWishlistItem{},
-		&models.CustomerCart{},
-		&models.AbandonedCart{},
-		&models.AbandonedCartRecoveryAttempt{},
-		&models.AbandonedCartSettings{},
-		&models.CustomerList{},
-		&models.CustomerListItem{},
-	)
+// initFieldEncryption configures the encrypted GORM serializer. It returns a nil keyring
+// when no key is configured, in which case encrypted fields are written in plaintext.
+func initFieldEncryption(cfg *config.Config, db *gorm.DB) (*encryption.Keyring, error) {
+	var wrapper encryption.KeyWrapper
+	switch {
+	case cfg.FieldEncryptionKMSKey != "":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		kmsWrapper, err := encryption.NewKMSKeyWrapper(ctx, cfg.FieldEncryptionKMSKey)
+		if err != nil {
+			return nil, err
+		}
+		wrapper = kmsWrapper
+	case cfg.FieldEncryptionLocalKey != "" && cfg.Environment != "production":
+		localWrapper, err := encryption.NewLocalKeyWrapper(cfg.FieldEncryptionLocalKey)
+		if err != nil {
+			return nil, err
+		}
+		wrapper = localWrapper
+	default:
+		return nil, nil
+	}
+
+	keyring := encryption.NewKeyring(db, wrapper)
+	encryption.Configure(keyring)
+	return keyring, nil
 }
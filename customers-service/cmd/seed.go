@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"time"
+
+	"customers-service/internal/config"
+	"customers-service/internal/loadseed"
+	"customers-service/internal/models"
+	"customers-service/internal/tenancy"
+	"customers-service/internal/workers"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// runSeed handles "customers-service seed [flags]", filling the database with load-test
+// customers and their open carts. Cart items are products seeded by products-service with the
+// same flags. Customers and carts that already exist are left alone.
+func runSeed(cfg *config.Config, args []string) {
+	opts, err := loadseed.Parse("customers-service", args)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Invalid seed options: %v", err)
+	}
+	if err := loadseed.CheckEnvironment(cfg.Environment); err != nil {
+		log.Fatalf("Refusing to seed: %v", err)
+	}
+
+	db, err := initDatabase(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	keyring, err := initFieldEncryption(cfg, db)
+	if err != nil {
+		log.Fatalf("Failed to initialize field encryption: %v", err)
+	}
+	if keyring != nil {
+		defer keyring.Close()
+	}
+
+	started := time.Now()
+	totalCustomers, totalCarts := 0, 0
+	for t, tenantID := range opts.TenantIDs() {
+		ctx := tenancy.WithTenant(context.Background(), tenantID)
+		tx := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true})
+
+		customers := opts.Count(t, opts.Customers)
+		batch := make([]models.Customer, 0, opts.BatchSize)
+		for i := 0; i < customers; i++ {
+			batch = append(batch, seedCustomer(opts, tenantID, i, started))
+			if len(batch) == opts.BatchSize || i == customers-1 {
+				if err := tx.Create(&batch).Error; err != nil {
+					log.Fatalf("Failed to seed customers for %s: %v", tenantID, err)
+				}
+				batch = batch[:0]
+			}
+		}
+
+		carts, err := seedCarts(tx, opts, t, tenantID, customers, started)
+		if err != nil {
+			log.Fatalf("Failed to seed carts for %s: %v", tenantID, err)
+		}
+		totalCustomers += customers
+		totalCarts += carts
+		log.Printf("Seeded %s: %d customers, %d carts", tenantID, customers, carts)
+	}
+	fmt.Fprintf(os.Stdout, "Seeded %d customers and %d carts for %d tenants in %s\n",
+		totalCustomers, totalCarts, opts.Tenants, time.Since(started).Round(time.Second))
+}
+
+func seedCustomer(opts *loadseed.Options, tenantID string, index int, now time.Time) models.Customer {
+	c := opts.CustomerAt(tenantID, index)
+	return models.Customer{
+		ID:           c.ID,
+		TenantID:     tenantID,
+		Email:        c.Email,
+		FirstName:    c.FirstName,
+		LastName:     c.LastName,
+		Status:       models.CustomerStatusActive,
+		CustomerType: models.CustomerTypeRetail,
+		Country:      c.Address.Country,
+		CountryCode:  c.Address.CountryCode,
+		Tags:         []string{"loadtest"},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// seedCarts gives an open cart to customers spread evenly over the tenant's customers, at most
+// one each since a customer has a single cart
+func seedCarts(tx *gorm.DB, opts *loadseed.Options, t int, tenantID string, customers int, now time.Time) (int, error) {
+	products := opts.Count(t, opts.Products)
+	count := min(opts.Count(t, opts.Carts), customers)
+	if products == 0 || count == 0 {
+		return 0, nil
+	}
+
+	r := opts.Rand(tenantID, "carts")
+	batch := make([]models.CustomerCart, 0, opts.BatchSize)
+	for i := 0; i < count; i++ {
+		cart, err := seedCart(opts, r, tenantID, i*customers/count, products, now)
+		if err != nil {
+			return 0, err
+		}
+		batch = append(batch, cart)
+		if len(batch) == opts.BatchSize || i == count-1 {
+			if err := tx.Create(&batch).Error; err != nil {
+				return 0, err
+			}
+			batch = batch[:0]
+		}
+	}
+	return count, nil
+}
+
+func seedCart(opts *loadseed.Options, r *rand.Rand, tenantID string, customer, products int, now time.Time) (models.CustomerCart, error) {
+	// Carts are touched within the last two weeks, so none are due for expiry yet
+	changedAt := now.Add(-time.Duration(r.Int64N(int64(14 * 24 * time.Hour)))).Truncate(time.Second)
+
+	lines := 1 + r.IntN(opts.MaxItems)
+	items := make([]models.CartItem, 0, lines)
+	seen := make(map[int]bool, lines)
+	subtotal, itemCount := 0.0, 0
+	for len(items) < lines && len(seen) < products {
+		index := opts.Pick(r, products)
+		if seen[index] {
+			continue
+		}
+		seen[index] = true
+
+		p := opts.ProductAt(tenantID, index)
+		quantity := 1 + r.IntN(2)
+		items = append(items, models.CartItem{
+			ID:         loadseed.ID("cart-item", tenantID, customer*opts.MaxItems+len(items)).String(),
+			ProductID:  p.ID.String(),
+			Name:       p.Name,
+			Price:      p.Price,
+			PriceAtAdd: p.Price,
+			Quantity:   quantity,
+			Status:     models.CartItemStatusAvailable,
+			AddedAt:    &changedAt,
+		})
+		subtotal += p.Price * float64(quantity)
+		itemCount += quantity
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return models.CustomerCart{}, err
+	}
+	expiresAt := changedAt.Add(workers.CartItemMaxAge)
+	return models.CustomerCart{
+		ID:             loadseed.ID("cart", tenantID, customer),
+		CustomerID:     loadseed.ID("customer", tenantID, customer),
+		TenantID:       tenantID,
+		Items:          models.JSONB(data),
+		Subtotal:       subtotal,
+		ItemCount:      itemCount,
+		LastItemChange: changedAt,
+		ExpiresAt:      &expiresAt,
+		CreatedAt:      changedAt,
+		UpdatedAt:      changedAt,
+	}, nil
+}
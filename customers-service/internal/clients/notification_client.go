@@ -289,3 +289,78 @@ func (c *NotificationClient) SendAccountUnlockedNotification(ctx context.Context
 
 	return c.sendNotification(ctx, req)
 }
+
+// AccountDeletionNotification represents an account deletion notification payload.
+type AccountDeletionNotification struct {
+	TenantID         string     `json:"tenantId"`
+	CustomerID       string     `json:"customerId"`
+	CustomerEmail    string     `json:"customerEmail"`
+	CustomerName     string     `json:"customerName"`
+	ConfirmationLink string     `json:"confirmationLink,omitempty"`
+	ScheduledFor     *time.Time `json:"scheduledFor,omitempty"`
+	StorefrontURL    string     `json:"storefrontUrl,omitempty"`
+}
+
+// SendAccountDeletionVerification asks the customer to confirm their account deletion request.
+func (c *NotificationClient) SendAccountDeletionVerification(ctx context.Context, notification *AccountDeletionNotification) error {
+	req := notificationRequest{
+		Channel:        "EMAIL",
+		RecipientEmail: notification.CustomerEmail,
+		Subject:        "Confirm your account deletion request",
+		TemplateName:   "account_deletion_verification",
+		TenantID:       notification.TenantID,
+		UserID:         notification.CustomerID,
+		Variables: map[string]interface{}{
+			"customerName":     notification.CustomerName,
+			"customerEmail":    notification.CustomerEmail,
+			"confirmationLink": notification.ConfirmationLink,
+			"storefrontUrl":    notification.StorefrontURL,
+		},
+	}
+
+	return c.sendNotification(ctx, req)
+}
+
+// SendAccountDeletionScheduled tells the customer when their account will be deleted and how to cancel.
+func (c *NotificationClient) SendAccountDeletionScheduled(ctx context.Context, notification *AccountDeletionNotification) error {
+	scheduledFor := ""
+	if notification.ScheduledFor != nil {
+		scheduledFor = notification.ScheduledFor.Format("2 January 2006")
+	}
+
+	req := notificationRequest{
+		Channel:        "EMAIL",
+		RecipientEmail: notification.CustomerEmail,
+		Subject:        "Your account is scheduled for deletion",
+		TemplateName:   "account_deletion_scheduled",
+		TenantID:       notification.TenantID,
+		UserID:         notification.CustomerID,
+		Variables: map[string]interface{}{
+			"customerName":  notification.CustomerName,
+			"customerEmail": notification.CustomerEmail,
+			"scheduledFor":  scheduledFor,
+			"storefrontUrl": notification.StorefrontURL,
+		},
+	}
+
+	return c.sendNotification(ctx, req)
+}
+
+// SendAccountDeletionCompleted confirms to the customer that their account has been deleted.
+func (c *NotificationClient) SendAccountDeletionCompleted(ctx context.Context, notification *AccountDeletionNotification) error {
+	req := notificationRequest{
+		Channel:        "EMAIL",
+		RecipientEmail: notification.CustomerEmail,
+		Subject:        "Your account has been deleted",
+		TemplateName:   "account_deletion_completed",
+		TenantID:       notification.TenantID,
+		UserID:         notification.CustomerID,
+		Variables: map[string]interface{}{
+			"customerName":  notification.CustomerName,
+			"customerEmail": notification.CustomerEmail,
+			"storefrontUrl": notification.StorefrontURL,
+		},
+	}
+
+	return c.sendNotification(ctx, req)
+}
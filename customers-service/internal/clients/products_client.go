@@ -12,6 +12,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"customers-service/internal/models"
 )
 
 // ProductsClient handles fetching product information for cart validation.
@@ -40,6 +42,24 @@ type ProductInfo struct {
 	Images          []string `json:"images,omitempty"`
 	DeletedAt       *string  `json:"deletedAt,omitempty"` // Set if product is soft-deleted
 	Found           bool     `json:"found"`               // Whether the product exists
+
+	// Metadata is kept raw so a malformed merchant entry can't hide the product
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+}
+
+// LineItemPropertyRules returns the line-item properties the product accepts, declared
+// in its metadata as lineItemProperties. A product without any returns none.
+func (p *ProductInfo) LineItemPropertyRules() ([]models.LineItemPropertyRule, error) {
+	if len(p.Metadata) == 0 {
+		return nil, nil
+	}
+	var metadata struct {
+		LineItemProperties []models.LineItemPropertyRule `json:"lineItemProperties"`
+	}
+	if err := json.Unmarshal(p.Metadata, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse line item properties: %w", err)
+	}
+	return metadata.LineItemProperties, nil
 }
 
 // BatchProductResult contains results for a batch product lookup.
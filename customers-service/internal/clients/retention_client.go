@@ -0,0 +1,143 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"customers-service/internal/serviceauth"
+)
+
+// RetentionClient fetches per-tenant retention policies from staff-service, where they are
+// stored centrally, and reports purge summaries back for the deletion audit trail.
+type RetentionClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// RetentionPolicy is a tenant's override of a resource's default retention.
+type RetentionPolicy struct {
+	TenantID      string `json:"tenantId"`
+	RetentionDays int    `json:"retentionDays"`
+	DryRun        bool   `json:"dryRun"`
+}
+
+// RetentionPolicySet is the default retention for a resource plus every tenant override.
+type RetentionPolicySet struct {
+	Resource    string            `json:"resource"`
+	DefaultDays int               `json:"defaultDays"`
+	Policies    []RetentionPolicy `json:"policies"`
+}
+
+// PolicyFor returns the retention that applies to a tenant.
+func (s *RetentionPolicySet) PolicyFor(tenantID string) (retentionDays int, dryRun bool) {
+	for _, p := range s.Policies {
+		if p.TenantID == tenantID {
+			return p.RetentionDays, p.DryRun
+		}
+	}
+	return s.DefaultDays, false
+}
+
+// ShortestDays returns the smallest retention across the default and all overrides.
+func (s *RetentionPolicySet) ShortestDays() int {
+	shortest := s.DefaultDays
+	for _, p := range s.Policies {
+		if p.RetentionDays < shortest {
+			shortest = p.RetentionDays
+		}
+	}
+	return shortest
+}
+
+// RetentionPurgeRun is the audit summary of one purge for one tenant.
+type RetentionPurgeRun struct {
+	TenantID      string    `json:"tenantId"`
+	Resource      string    `json:"resource"`
+	RetentionDays int       `json:"retentionDays"`
+	Cutoff        time.Time `json:"cutoff"`
+	DryRun        bool      `json:"dryRun"`
+	MatchedCount  int64     `json:"matchedCount"`
+	DeletedCount  int64     `json:"deletedCount"`
+	Status        string    `json:"status"`
+	Error         *string   `json:"error,omitempty"`
+	StartedAt     time.Time `json:"startedAt"`
+	CompletedAt   time.Time `json:"completedAt"`
+}
+
+// NewRetentionClient creates a new retention client.
+func NewRetentionClient() *RetentionClient {
+	baseURL := os.Getenv("STAFF_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://staff-service:8080"
+	}
+
+	return &RetentionClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetPolicySet fetches the retention policies for a resource.
+func (c *RetentionClient) GetPolicySet(ctx context.Context, resource string) (*RetentionPolicySet, error) {
+	url := fmt.Sprintf("%s/api/v1/internal/retention/policies/%s", c.baseURL, resource)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	serviceauth.Sign(req, "customers-service")
+	req.Header.Set("X-Internal-Service", "customers-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch retention policies: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("staff-service returned status %d for retention policies", resp.StatusCode)
+	}
+
+	var result struct {
+		Data RetentionPolicySet `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode retention policies: %w", err)
+	}
+	return &result.Data, nil
+}
+
+// RecordPurgeRun reports a purge summary to the central deletion audit trail.
+func (c *RetentionClient) RecordPurgeRun(ctx context.Context, run *RetentionPurgeRun) error {
+	body, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/internal/retention/runs", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	serviceauth.Sign(req, "customers-service")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Internal-Service", "customers-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to record purge run: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("staff-service returned status %d recording purge run", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// TimelineClient reads a customer's records from the services that own them for the unified
+// customer timeline. Requests carry the calling agent's headers, so each service applies the
+// agent's own permissions.
+type TimelineClient struct {
+	ordersURL    string
+	ticketsURL   string
+	reviewsURL   string
+	marketingURL string
+	httpClient   *http.Client
+}
+
+// TimelineOrder is the part of an order the timeline shows, with its payment.
+type TimelineOrder struct {
+	ID            string    `json:"id"`
+	OrderNumber   string    `json:"orderNumber"`
+	Status        string    `json:"status"`
+	PaymentStatus string    `json:"paymentStatus"`
+	Total         float64   `json:"total"`
+	Currency      string    `json:"currency"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Payment       *struct {
+		ID          string     `json:"id"`
+		Method      string     `json:"method"`
+		Status      string     `json:"status"`
+		Amount      float64    `json:"amount"`
+		Currency    string     `json:"currency"`
+		ProcessedAt *time.Time `json:"processedAt"`
+		CreatedAt   time.Time  `json:"createdAt"`
+	} `json:"payment"`
+}
+
+// TimelineTicket is the part of a support ticket the timeline shows.
+type TimelineTicket struct {
+	ID           string    `json:"id"`
+	TicketNumber string    `json:"ticketNumber"`
+	Title        string    `json:"title"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// TimelineReview is the part of a review the timeline shows.
+type TimelineReview struct {
+	ID         string    `json:"id"`
+	Title      *string   `json:"title"`
+	TargetID   string    `json:"targetId"`
+	TargetType string    `json:"targetType"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TimelineLoyaltyTransaction is the part of a loyalty transaction the timeline shows.
+type TimelineLoyaltyTransaction struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	Points      int       `json:"points"`
+	Description string    `json:"description"`
+	OrderID     *string   `json:"orderId"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// NewTimelineClient creates a new timeline client.
+func NewTimelineClient() *TimelineClient {
+	return &TimelineClient{
+		ordersURL:    serviceURL("ORDERS_SERVICE_URL", "http://orders-service.marketplace.svc.cluster.local:8080"),
+		ticketsURL:   serviceURL("TICKETS_SERVICE_URL", "http://tickets-service.marketplace.svc.cluster.local:8080"),
+		reviewsURL:   serviceURL("REVIEWS_SERVICE_URL", "http://reviews-service.marketplace.svc.cluster.local:8080"),
+		marketingURL: serviceURL("MARKETING_SERVICE_URL", "http://marketing-service.marketplace.svc.cluster.local:8080"),
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+func serviceURL(env, fallback string) string {
+	if v := os.Getenv(env); v != "" {
+		return strings.TrimSuffix(v, "/")
+	}
+	return fallback
+}
+
+// ListOrders lists a customer's most recent orders.
+func (c *TimelineClient) ListOrders(ctx context.Context, headers http.Header, customerID string, limit int) ([]TimelineOrder, error) {
+	query := url.Values{}
+	query.Set("customerId", customerID)
+	query.Set("limit", fmt.Sprint(limit))
+
+	var result struct {
+		Orders []TimelineOrder `json:"orders"`
+	}
+	if err := c.get(ctx, headers, c.ordersURL+"/api/v1/orders?"+query.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("orders-service: %w", err)
+	}
+	return result.Orders, nil
+}
+
+// ListTickets lists the most recent tickets a customer's user account opened.
+func (c *TimelineClient) ListTickets(ctx context.Context, headers http.Header, userID string, limit int) ([]TimelineTicket, error) {
+	query := url.Values{}
+	query.Set("createdBy", userID)
+	query.Set("limit", fmt.Sprint(limit))
+
+	var result struct {
+		Data []TimelineTicket `json:"data"`
+	}
+	if err := c.get(ctx, headers, c.ticketsURL+"/api/v1/tickets?"+query.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("tickets-service: %w", err)
+	}
+	return result.Data, nil
+}
+
+// ListReviews lists the most recent reviews a customer's user account wrote.
+func (c *TimelineClient) ListReviews(ctx context.Context, headers http.Header, userID string, limit int) ([]TimelineReview, error) {
+	query := url.Values{}
+	query.Set("userId", userID)
+	query.Set("limit", fmt.Sprint(limit))
+
+	var result struct {
+		Data []TimelineReview `json:"data"`
+	}
+	if err := c.get(ctx, headers, c.reviewsURL+"/api/v1/reviews?"+query.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("reviews-service: %w", err)
+	}
+	return result.Data, nil
+}
+
+// ListLoyaltyTransactions lists a customer's most recent loyalty transactions.
+func (c *TimelineClient) ListLoyaltyTransactions(ctx context.Context, headers http.Header, customerID string, limit int) ([]TimelineLoyaltyTransaction, error) {
+	query := url.Values{}
+	query.Set("limit", fmt.Sprint(limit))
+
+	var result struct {
+		Transactions []TimelineLoyaltyTransaction `json:"transactions"`
+	}
+	endpoint := fmt.Sprintf("%s/api/v1/loyalty/customers/%s/transactions?%s", c.marketingURL, url.PathEscape(customerID), query.Encode())
+	if err := c.get(ctx, headers, endpoint, &result); err != nil {
+		return nil, fmt.Errorf("marketing-service: %w", err)
+	}
+	return result.Transactions, nil
+}
+
+// get performs a GET with the caller's headers and decodes the JSON response.
+func (c *TimelineClient) get(ctx context.Context, headers http.Header, endpoint string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range headers {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
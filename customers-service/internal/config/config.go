@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets"
@@ -18,6 +20,28 @@ type Config struct {
 	NotificationServiceURL string
 	TenantServiceURL       string
 	RedisURL               string
+	ShutdownTimeout        time.Duration // How long in-flight requests get to finish on SIGTERM
+
+	// Field encryption: tenant data keys are wrapped with the Cloud KMS key. The local key
+	// (base64, 32 bytes) is only honoured outside production.
+	FieldEncryptionKMSKey      string
+	FieldEncryptionLocalKey    string
+	FieldEncryptionRotationAge time.Duration
+
+	// Account deletion: how long a confirmed deletion waits before anonymization starts, and
+	// the services that must confirm they anonymized the customer before it completes
+	AccountDeletionCoolingOff time.Duration
+	AccountDeletionServices   []string
+
+	// Cart price locks: the secret shared with orders-service for signing checkout price-lock
+	// tokens, and how long a lock holds. Locks are disabled without a secret.
+	CartPriceLockSecret string
+	CartPriceLockTTL    time.Duration
+
+	// Runtime config: an optional JSON file of setting overrides, and the secret that signs
+	// requests to the config admin API (the API is disabled without one)
+	RuntimeConfigFile string
+	ConfigAdminSecret string
 }
 
 // New creates a new configuration from environment variables
@@ -33,6 +57,20 @@ func New() *Config {
 		NotificationServiceURL: getEnv("NOTIFICATION_SERVICE_URL", "http://notification-service.global.svc.cluster.local:8090"),
 		TenantServiceURL:       getEnv("TENANT_SERVICE_URL", "http://tenant-service.global.svc.cluster.local:8087"),
 		RedisURL:               getEnv("REDIS_URL", "redis://redis.redis-marketplace.svc.cluster.local:6379/0"),
+		ShutdownTimeout:        getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+
+		FieldEncryptionKMSKey:      os.Getenv("FIELD_ENCRYPTION_KMS_KEY"),
+		FieldEncryptionLocalKey:    os.Getenv("FIELD_ENCRYPTION_LOCAL_KEY"),
+		FieldEncryptionRotationAge: getEnvDays("FIELD_ENCRYPTION_ROTATION_DAYS", 90),
+
+		AccountDeletionCoolingOff: getEnvDays("ACCOUNT_DELETION_COOLING_OFF_DAYS", 14),
+		AccountDeletionServices:   getEnvList("ACCOUNT_DELETION_SERVICES", "orders-service,marketing-service"),
+
+		CartPriceLockSecret: os.Getenv("CART_PRICE_LOCK_SECRET"),
+		CartPriceLockTTL:    getEnvMinutes("CART_PRICE_LOCK_MINUTES", 15),
+
+		RuntimeConfigFile: getEnv("RUNTIME_CONFIG_FILE", ""),
+		ConfigAdminSecret: secrets.GetSecretOrEnv("CONFIG_ADMIN_SECRET_NAME", "CONFIG_ADMIN_SECRET", ""),
 	}
 }
 
@@ -93,3 +131,42 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvList(key, defaultValue string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvDays(key string, defaultDays int) time.Duration {
+	days := defaultDays
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			days = parsed
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func getEnvMinutes(key string, defaultMinutes int) time.Duration {
+	minutes := defaultMinutes
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			minutes = parsed
+		}
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,392 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The migrator is kept identical in every service; change all copies together. Each service
+// declares its serviceName, BaselineVersion and SyncModels in schema.go.
+
+// migrationLockKey identifies the Postgres advisory lock that serializes migrations across
+// replicas, derived from a name so it cannot collide with another service sharing the server
+var migrationLockKey = func() int64 {
+	h := fnv.New64a()
+	h.Write([]byte(serviceName + ":schema-migrations"))
+	return int64(h.Sum64())
+}()
+
+// migrationFilePattern matches NNN_name.sql and golang-migrate's NNN_name.up.sql; down files
+// are never run
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)(?:\.up)?\.sql$`)
+
+// legacyMigrationsTable is where a schema_migrations table left by the golang-migrate CLI is
+// moved when the migrator takes over the database
+const legacyMigrationsTable = "schema_migrations_golang_migrate"
+
+// SchemaMigration records a migration applied to this database
+type SchemaMigration struct {
+	Version    int       `json:"version" gorm:"primaryKey;autoIncrement:false"`
+	Name       string    `json:"name" gorm:"type:varchar(255);not null"`
+	Checksum   string    `json:"checksum,omitempty" gorm:"type:varchar(64)"` // SHA-256 of the file as applied
+	AppliedAt  time.Time `json:"appliedAt" gorm:"not null"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+// TableName returns the table name for GORM
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migration is a versioned SQL file from the migrations directory
+type Migration struct {
+	Version  int
+	Name     string
+	Path     string
+	Checksum string
+}
+
+// MigrationStatus describes one migration as seen by "migrate status"
+type MigrationStatus struct {
+	Version          int
+	Name             string
+	AppliedAt        *time.Time
+	ChecksumMismatch bool // The file changed after it was applied
+}
+
+// Migrator applies versioned migrations under a Postgres advisory lock, so replicas starting
+// together never run schema changes concurrently
+type Migrator struct {
+	db  *gorm.DB
+	dir string
+}
+
+// NewMigrator creates a migrator reading SQL files from dir
+func NewMigrator(db *gorm.DB, dir string) *Migrator {
+	return &Migrator{db: db, dir: dir}
+}
+
+// Migrate applies pending migrations and returns how many ran. The baseline runs SyncModels
+// once; with syncModels set (development) it runs on every call so model changes apply
+// without a SQL file. Each SQL file runs in its own transaction together with its record.
+func (m *Migrator) Migrate(ctx context.Context, syncModels bool) (int, error) {
+	migrations, err := m.migrations()
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	err = m.withLock(ctx, func(db *gorm.DB) error {
+		legacyVersion, err := m.adoptLegacyTable(db)
+		if err != nil {
+			return err
+		}
+		if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+		if err := m.recordLegacy(db, migrations, legacyVersion); err != nil {
+			return err
+		}
+		done, err := m.applied(db)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := done[BaselineVersion]; !ok || syncModels {
+			start := time.Now()
+			if err := SyncModels(db); err != nil {
+				return fmt.Errorf("failed to sync model schema: %w", err)
+			}
+			if !ok {
+				if err := db.Create(&SchemaMigration{
+					Version:    BaselineVersion,
+					Name:       "baseline",
+					AppliedAt:  time.Now(),
+					DurationMs: time.Since(start).Milliseconds(),
+				}).Error; err != nil {
+					return fmt.Errorf("failed to record baseline: %w", err)
+				}
+				applied++
+				log.Printf("✓ Applied migration %03d baseline", BaselineVersion)
+			}
+		}
+
+		for _, migration := range migrations {
+			if record, ok := done[migration.Version]; ok {
+				if record.Checksum != migration.Checksum {
+					log.Printf("Warning: migration %03d_%s changed after it was applied; add a new migration instead of editing it",
+						migration.Version, migration.Name)
+				}
+				continue
+			}
+			if err := m.apply(db, migration); err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// WaitForSchema blocks until another process (normally the migrate init job) has brought the
+// schema up to the latest migration, or the timeout passes
+func (m *Migrator) WaitForSchema(ctx context.Context, timeout time.Duration) error {
+	latest, err := m.LatestVersion()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	logged := false
+	for {
+		current, err := m.CurrentVersion(ctx)
+		if err == nil && current >= latest {
+			return nil
+		}
+		if !logged {
+			log.Printf("Waiting for schema version %03d (current %03d); run \"%s migrate\" to apply pending migrations", latest, current, serviceName)
+			logged = true
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("schema not ready after %s: %w", timeout, err)
+			}
+			return fmt.Errorf("schema is at version %03d, expected %03d after waiting %s", current, latest, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// LatestVersion returns the version the code expects the schema to be at
+func (m *Migrator) LatestVersion() (int, error) {
+	migrations, err := m.migrations()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return BaselineVersion, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// CurrentVersion returns the highest applied version, or 0 on a database never migrated
+func (m *Migrator) CurrentVersion(ctx context.Context) (int, error) {
+	db := m.db.WithContext(ctx)
+	if !m.hasTable(db) {
+		return 0, nil
+	}
+	var version int
+	err := db.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// Status lists the baseline and every SQL migration with when it was applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := m.migrations()
+	if err != nil {
+		return nil, err
+	}
+	db := m.db.WithContext(ctx)
+	done := map[int]SchemaMigration{}
+	if m.hasTable(db) {
+		if done, err = m.applied(db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations)+1)
+	baseline := MigrationStatus{Version: BaselineVersion, Name: "baseline"}
+	if record, ok := done[BaselineVersion]; ok {
+		baseline.AppliedAt = &record.AppliedAt
+	}
+	statuses = append(statuses, baseline)
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := done[migration.Version]; ok {
+			status.AppliedAt = &record.AppliedAt
+			status.ChecksumMismatch = record.Checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// migrations reads the SQL files numbered after the baseline, in version order
+func (m *Migrator) migrations() ([]Migration, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory %s: %w", m.dir, err)
+	}
+
+	var migrations []Migration
+	seen := map[int]string{}
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(match[1])
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+		if version <= BaselineVersion {
+			continue
+		}
+
+		path := filepath.Join(m.dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     match[2],
+			Path:     path,
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// hasTable reports whether schema_migrations exists and is the migrator's own, not golang-migrate's
+func (m *Migrator) hasTable(db *gorm.DB) bool {
+	return db.Migrator().HasTable(&SchemaMigration{}) && db.Migrator().HasColumn(&SchemaMigration{}, "name")
+}
+
+// adoptLegacyTable moves a schema_migrations table written by the golang-migrate CLI out of the
+// way and returns the version it had reached, or 0 if there was none
+func (m *Migrator) adoptLegacyTable(db *gorm.DB) (int, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) || !db.Migrator().HasColumn(&SchemaMigration{}, "dirty") {
+		return 0, nil
+	}
+
+	var legacy struct {
+		Version int
+		Dirty   bool
+	}
+	if err := db.Raw("SELECT version, dirty FROM schema_migrations ORDER BY version DESC LIMIT 1").Scan(&legacy).Error; err != nil {
+		return 0, fmt.Errorf("failed to read golang-migrate schema_migrations: %w", err)
+	}
+	if legacy.Dirty {
+		return 0, fmt.Errorf("golang-migrate left migration %03d dirty; repair it before migrating", legacy.Version)
+	}
+	if err := db.Migrator().RenameTable("schema_migrations", legacyMigrationsTable); err != nil {
+		return 0, fmt.Errorf("failed to move golang-migrate schema_migrations: %w", err)
+	}
+	log.Printf("Adopted golang-migrate schema at version %03d (table kept as %s)", legacy.Version, legacyMigrationsTable)
+	return legacy.Version, nil
+}
+
+// recordLegacy records the SQL files golang-migrate had already applied, so they aren't run again
+func (m *Migrator) recordLegacy(db *gorm.DB, migrations []Migration, legacyVersion int) error {
+	for _, migration := range migrations {
+		if migration.Version > legacyVersion {
+			break
+		}
+		if err := db.Create(&SchemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Checksum:  migration.Checksum,
+			AppliedAt: time.Now(),
+		}).Error; err != nil {
+			return fmt.Errorf("failed to record migration %03d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) applied(db *gorm.DB) (map[int]SchemaMigration, error) {
+	var records []SchemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	done := make(map[int]SchemaMigration, len(records))
+	for _, record := range records {
+		done[record.Version] = record
+	}
+	return done, nil
+}
+
+func (m *Migrator) apply(db *gorm.DB, migration Migration) error {
+	content, err := os.ReadFile(migration.Path)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(string(content)).Error; err != nil {
+			return err
+		}
+		return tx.Create(&SchemaMigration{
+			Version:    migration.Version,
+			Name:       migration.Name,
+			Checksum:   migration.Checksum,
+			AppliedAt:  time.Now(),
+			DurationMs: time.Since(start).Milliseconds(),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("migration %03d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+	log.Printf("✓ Applied migration %03d_%s in %s", migration.Version, migration.Name, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// withLock runs fn while holding the migration advisory lock. Advisory locks belong to a
+// database session, so the lock is taken on a dedicated connection kept open until fn returns.
+func (m *Migrator) withLock(ctx context.Context, fn func(db *gorm.DB) error) error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open migration lock connection: %w", err)
+	}
+	defer conn.Close()
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", migrationLockKey).Scan(&acquired); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if !acquired {
+		log.Println("Another replica is migrating the database, waiting for the migration lock...")
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errors.New("timed out waiting for the migration lock")
+			}
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			log.Printf("Warning: failed to release migration lock: %v", err)
+		}
+	}()
+
+	return fn(m.db.WithContext(ctx))
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"gorm.io/gorm"
+
+	"customers-service/internal/encryption"
+	"customers-service/internal/jobqueue"
+	"customers-service/internal/models"
+	"customers-service/internal/repository"
+)
+
+// serviceName names the service in the migration lock key and log messages
+const serviceName = "customers-service"
+
+// BaselineVersion is the last SQL migration folded into the model schema. Databases that
+// predate versioned migrations were built by AutoMigrate, and new databases get the same
+// schema from SyncModels, so only files numbered after it are applied from SQL.
+const BaselineVersion = 16
+
+// SyncModels brings the schema in line with the GORM models. It must only run while holding
+// the migration lock.
+func SyncModels(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&models.Customer{},
+		&models.CustomerAddress{},
+		&models.CustomerPaymentMethod{},
+		&models.CustomerSegment{},
+		&repository.CustomerSegmentMember{},
+		&models.CustomerNote{},
+		&models.CustomerCommunication{},
+		&models.CustomerWishlistItem{},
+		&models.CustomerCart{},
+		&models.AbandonedCart{},
+		&models.AbandonedCartRecoveryAttempt{},
+		&models.AbandonedCartSettings{},
+		&models.CustomerList{},
+		&models.CustomerListItem{},
+		&encryption.TenantDataKey{},
+		&models.AccountDeletionRequest{},
+		&models.CustomerImportJob{},
+		&models.CustomerImportError{},
+		&models.CustomerSecurityEvent{},
+		&models.CustomerDevice{},
+		&jobqueue.JobState{},
+		&jobqueue.JobRun{},
+	)
+}
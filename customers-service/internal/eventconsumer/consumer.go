@@ -0,0 +1,366 @@
+// Package eventconsumer consumes JetStream subjects through a durable pull consumer shared by
+// every replica of a service.
+//
+// All replicas bind to the same durable consumer, so JetStream hands each message to one of
+// them (work-queue semantics): adding pods spreads the load, and a restarting pod resumes from
+// the consumer's acknowledged position instead of reprocessing or missing events. Messages are
+// acknowledged explicitly once the handler succeeds. A failed message is redelivered with
+// backoff; after MaxDeliver attempts, or straight away for errors wrapped with Permanent, it is
+// republished to the DEAD_LETTERS stream and terminated. Consumer lag and outcomes are exported
+// as Prometheus metrics.
+//
+// The package is kept identical in every service that uses it; change all copies together.
+package eventconsumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DeadLetterStream keeps poison messages of every service for inspection and replay
+	DeadLetterStream = "DEAD_LETTERS"
+
+	// DeadLetterSubjectPrefix is followed by the durable consumer name that gave up on the message
+	DeadLetterSubjectPrefix = "deadletter."
+
+	// DeadLetterMaxAge is how long dead letters are kept
+	DeadLetterMaxAge = 14 * 24 * time.Hour
+
+	// Headers added to dead letters
+	HeaderStream     = "Dead-Letter-Stream"
+	HeaderSubject    = "Dead-Letter-Subject"
+	HeaderConsumer   = "Dead-Letter-Consumer"
+	HeaderSequence   = "Dead-Letter-Sequence"
+	HeaderDeliveries = "Dead-Letter-Deliveries"
+	HeaderError      = "Dead-Letter-Error"
+
+	lagPollInterval = 15 * time.Second
+)
+
+// DefaultBackoff is the delay before each redelivery of a failed message; the last entry
+// repeats for any further attempts
+var DefaultBackoff = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute, 10 * time.Minute}
+
+// Handler processes one message. Returning nil acknowledges it; an error redelivers it.
+type Handler func(ctx context.Context, msg jetstream.Msg) error
+
+// Config describes a durable consumer
+type Config struct {
+	NATSURL       string
+	ClientName    string // NATS connection name
+	Durable       string // Consumer name, the same for every replica of the service
+	Stream        string
+	AckWait       time.Duration // Time the handler has before the message is redelivered (default 30s)
+	MaxDeliver    int           // Attempts before a message is dead-lettered (default 5)
+	MaxAckPending int           // Messages in flight across all replicas (default 100)
+	Backoff       []time.Duration
+}
+
+// Consumer is a durable, horizontally scalable JetStream consumer
+type Consumer struct {
+	config   Config
+	nc       *nats.Conn
+	js       jetstream.JetStream
+	logger   *logrus.Entry
+	consumer jetstream.Consumer
+
+	mu       sync.Mutex
+	consume  jetstream.ConsumeContext
+	stopping bool
+	inFlight sync.WaitGroup
+	cancel   context.CancelFunc
+}
+
+// permanentError marks a failure that redelivery can't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the message is dead-lettered without further attempts, e.g. for a
+// payload that can't be decoded
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// JSON adapts a handler of decoded events. Payloads that don't decode are dead-lettered.
+func JSON[T any](handler func(ctx context.Context, event *T) error) Handler {
+	return func(ctx context.Context, msg jetstream.Msg) error {
+		var event T
+		if err := json.Unmarshal(msg.Data(), &event); err != nil {
+			return Permanent(fmt.Errorf("failed to decode %s: %w", msg.Subject(), err))
+		}
+		return handler(ctx, &event)
+	}
+}
+
+// New connects to NATS, retrying with backoff for up to about a minute
+func New(config Config, logger *logrus.Logger) (*Consumer, error) {
+	if config.Durable == "" || config.Stream == "" {
+		return nil, errors.New("durable name and stream are required")
+	}
+	if config.AckWait <= 0 {
+		config.AckWait = 30 * time.Second
+	}
+	if config.MaxDeliver <= 0 {
+		config.MaxDeliver = 5
+	}
+	if config.MaxAckPending <= 0 {
+		config.MaxAckPending = 100
+	}
+	if len(config.Backoff) == 0 {
+		config.Backoff = DefaultBackoff
+	}
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+
+	c := &Consumer{
+		config: config,
+		logger: logger.WithFields(logrus.Fields{"component": "event-consumer", "consumer": config.Durable}),
+	}
+
+	delay := 2 * time.Second
+	var err error
+	for attempt := 1; attempt <= 5; attempt++ {
+		if err = c.connect(); err == nil {
+			return c, nil
+		}
+		c.logger.WithError(err).Warnf("Failed to connect to NATS (attempt %d/5), retrying in %s", attempt, delay)
+		time.Sleep(delay)
+		delay *= 2
+	}
+	return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+}
+
+func (c *Consumer) connect() error {
+	nc, err := nats.Connect(c.config.NATSURL,
+		nats.Name(c.config.ClientName),
+		nats.Timeout(10*time.Second),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(2*time.Second),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				c.logger.WithError(err).Warn("[NATS] Disconnected")
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			c.logger.WithField("url", nc.ConnectedUrl()).Info("[NATS] Reconnected")
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return err
+	}
+	c.nc = nc
+	c.js = js
+	return nil
+}
+
+// JetStream returns the consumer's JetStream context, e.g. to ensure the stream exists
+func (c *Consumer) JetStream() jetstream.JetStream {
+	return c.js
+}
+
+// Start creates or updates the durable consumer for subjects and starts handing messages to
+// handler. An existing consumer keeps its position, so no events are skipped or replayed.
+func (c *Consumer) Start(ctx context.Context, subjects []string, handler Handler) error {
+	stream, err := c.js.Stream(ctx, c.config.Stream)
+	if err != nil {
+		return fmt.Errorf("failed to get stream %s: %w", c.config.Stream, err)
+	}
+
+	consumer, err := stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Name:           c.config.Durable,
+		Durable:        c.config.Durable,
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		AckWait:        c.config.AckWait,
+		MaxDeliver:     c.config.MaxDeliver,
+		MaxAckPending:  c.config.MaxAckPending,
+		DeliverPolicy:  jetstream.DeliverNewPolicy, // Only applies when the consumer is first created
+		FilterSubjects: subjects,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer %s: %w", c.config.Durable, err)
+	}
+	c.consumer = consumer
+
+	if _, err := c.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     DeadLetterStream,
+		Subjects: []string{DeadLetterSubjectPrefix + ">"},
+		MaxAge:   DeadLetterMaxAge,
+		Storage:  jetstream.FileStorage,
+	}); err != nil {
+		c.logger.WithError(err).Warn("Failed to ensure dead letter stream, poison messages will only be logged")
+	}
+
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	consume, err := consumer.Consume(func(msg jetstream.Msg) {
+		c.handle(runCtx, msg, handler)
+	}, jetstream.ConsumeErrHandler(func(_ jetstream.ConsumeContext, err error) {
+		c.logger.WithError(err).Warn("Consumer error")
+	}))
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to start consuming %s: %w", c.config.Durable, err)
+	}
+
+	c.mu.Lock()
+	c.consume = consume
+	c.cancel = cancel
+	c.mu.Unlock()
+
+	go c.reportLag(runCtx)
+
+	c.logger.WithFields(logrus.Fields{
+		"stream":   c.config.Stream,
+		"subjects": subjects,
+	}).Info("Durable consumer started")
+	return nil
+}
+
+// Stop stops fetching, waits for the message being handled and closes the connection.
+// Messages fetched but not yet handled are released to the other replicas.
+func (c *Consumer) Stop() {
+	c.mu.Lock()
+	c.stopping = true
+	consume, cancel := c.consume, c.cancel
+	c.mu.Unlock()
+
+	if consume != nil {
+		consume.Stop()
+	}
+	c.inFlight.Wait()
+	if cancel != nil {
+		cancel()
+	}
+	if c.nc != nil {
+		c.nc.Close()
+	}
+	c.logger.Info("Durable consumer stopped")
+}
+
+func (c *Consumer) handle(ctx context.Context, msg jetstream.Msg, handler Handler) {
+	c.mu.Lock()
+	if c.stopping {
+		c.mu.Unlock()
+		_ = msg.Nak()
+		return
+	}
+	c.inFlight.Add(1)
+	c.mu.Unlock()
+	defer c.inFlight.Done()
+
+	start := time.Now()
+	err := handler(ctx, msg)
+	handlerDuration.WithLabelValues(c.config.Durable).Observe(time.Since(start).Seconds())
+
+	if err == nil {
+		if ackErr := msg.Ack(); ackErr != nil {
+			c.logger.WithError(ackErr).Warn("Failed to acknowledge message")
+		}
+		messagesTotal.WithLabelValues(c.config.Durable, "acked").Inc()
+		return
+	}
+
+	var deliveries uint64 = 1
+	var sequence uint64
+	if meta, metaErr := msg.Metadata(); metaErr == nil {
+		deliveries = meta.NumDelivered
+		sequence = meta.Sequence.Stream
+	}
+	logger := c.logger.WithFields(logrus.Fields{
+		"subject":    msg.Subject(),
+		"sequence":   sequence,
+		"deliveries": deliveries,
+	}).WithError(err)
+
+	var permanent *permanentError
+	if errors.As(err, &permanent) || deliveries >= uint64(c.config.MaxDeliver) {
+		c.deadLetter(ctx, msg, err, deliveries, sequence)
+		if termErr := msg.Term(); termErr != nil {
+			logger.WithField("term_error", termErr).Warn("Failed to terminate poison message")
+		}
+		messagesTotal.WithLabelValues(c.config.Durable, "dead_lettered").Inc()
+		logger.Error("Message moved to dead letters")
+		return
+	}
+
+	delay := c.config.Backoff[len(c.config.Backoff)-1]
+	if int(deliveries) <= len(c.config.Backoff) {
+		delay = c.config.Backoff[deliveries-1]
+	}
+	if nakErr := msg.NakWithDelay(delay); nakErr != nil {
+		logger.WithField("nak_error", nakErr).Warn("Failed to request redelivery, message will be redelivered after the ack wait")
+	}
+	messagesTotal.WithLabelValues(c.config.Durable, "retried").Inc()
+	logger.Warnf("Handler failed, redelivering in %s", delay)
+}
+
+// deadLetter republishes a poison message with the reason it failed
+func (c *Consumer) deadLetter(ctx context.Context, msg jetstream.Msg, cause error, deliveries, sequence uint64) {
+	dead := nats.NewMsg(DeadLetterSubjectPrefix + c.config.Durable)
+	dead.Data = msg.Data()
+	for key, values := range msg.Headers() {
+		for _, value := range values {
+			dead.Header.Add(key, value)
+		}
+	}
+	dead.Header.Set(HeaderStream, c.config.Stream)
+	dead.Header.Set(HeaderSubject, msg.Subject())
+	dead.Header.Set(HeaderConsumer, c.config.Durable)
+	dead.Header.Set(HeaderSequence, strconv.FormatUint(sequence, 10))
+	dead.Header.Set(HeaderDeliveries, strconv.FormatUint(deliveries, 10))
+	dead.Header.Set(HeaderError, cause.Error())
+
+	publishCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if _, err := c.js.PublishMsg(publishCtx, dead); err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"subject": msg.Subject(),
+			"payload": string(msg.Data()),
+		}).Error("Failed to publish dead letter")
+	}
+}
+
+// reportLag exports the consumer's backlog; every replica reports the same shared consumer
+func (c *Consumer) reportLag(ctx context.Context) {
+	ticker := time.NewTicker(lagPollInterval)
+	defer ticker.Stop()
+
+	for {
+		infoCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		info, err := c.consumer.Info(infoCtx)
+		cancel()
+		if err == nil {
+			pendingMessages.WithLabelValues(c.config.Durable).Set(float64(info.NumPending))
+			ackPendingMessages.WithLabelValues(c.config.Durable).Set(float64(info.NumAckPending))
+			redeliveredMessages.WithLabelValues(c.config.Durable).Set(float64(info.NumRedelivered))
+		} else if ctx.Err() == nil {
+			c.logger.WithError(err).Debug("Failed to read consumer info")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
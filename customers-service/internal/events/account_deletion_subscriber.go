@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/Tesseract-Nexus/go-shared/events"
+	"customers-service/internal/services"
+	"customers-service/internal/tenancy"
+)
+
+// AccountDeletionSubscriber listens for customer.anonymized confirmations from the services
+// taking part in account deletion and completes deletions once all of them have confirmed.
+type AccountDeletionSubscriber struct {
+	subscriber *events.Subscriber
+	service    *services.AccountDeletionService
+	cancel     context.CancelFunc
+}
+
+// NewAccountDeletionSubscriber creates a new account deletion confirmation subscriber.
+func NewAccountDeletionSubscriber(service *services.AccountDeletionService, logger *logrus.Logger) (*AccountDeletionSubscriber, error) {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetLevel(logrus.InfoLevel)
+	}
+
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	config := events.DefaultSubscriberConfig(natsURL, "customers-service-account-deletion")
+	config.Name = "customers-service-account-deletion"
+	config.MaxDeliver = 5
+	config.AckWait = 30 * time.Second
+
+	subscriber, err := events.NewSubscriber(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountDeletionSubscriber{
+		subscriber: subscriber,
+		service:    service,
+	}, nil
+}
+
+// Start begins listening for anonymization confirmations.
+func (s *AccountDeletionSubscriber) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	if err := s.subscriber.Subscribe(ctx, events.StreamCustomers, []string{AnonymizedEvent}, s.handleAnonymized); err != nil {
+		return err
+	}
+
+	log.Println("[AccountDeletionSubscriber] Started listening for customer.anonymized events")
+	return nil
+}
+
+// Stop stops the subscriber.
+func (s *AccountDeletionSubscriber) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.subscriber != nil {
+		s.subscriber.Close()
+	}
+}
+
+// handleAnonymized records a service's confirmation that it anonymized a customer.
+func (s *AccountDeletionSubscriber) handleAnonymized(ctx context.Context, msg *events.Message) error {
+	var event events.CustomerEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Printf("[AccountDeletionSubscriber] Failed to unmarshal customer.anonymized event: %v", err)
+		return nil // Don't redeliver malformed messages
+	}
+
+	service, _ := event.Metadata["service"].(string)
+	requestIDStr, _ := event.Metadata["requestId"].(string)
+	requestID, err := uuid.Parse(requestIDStr)
+	if event.TenantID == "" || service == "" || err != nil {
+		log.Printf("[AccountDeletionSubscriber] Skipping customer.anonymized event without tenant, service or request ID")
+		return nil
+	}
+
+	ctx = tenancy.WithTenant(ctx, event.TenantID)
+	return s.service.RecordServiceAnonymized(ctx, event.TenantID, requestID, service)
+}
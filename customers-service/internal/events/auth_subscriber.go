@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/Tesseract-Nexus/go-shared/events"
+	"customers-service/internal/models"
+	"customers-service/internal/services"
+	"customers-service/internal/tenancy"
+)
+
+// authEventTypes maps the auth events customers' security logs are built from to the
+// security events they are recorded as
+var authEventTypes = map[string]models.SecurityEventType{
+	events.LoginSuccess:    models.SecurityEventLoginSucceeded,
+	events.LoginFailed:     models.SecurityEventLoginFailed,
+	events.PasswordChanged: models.SecurityEventPasswordChanged,
+	events.PasswordReset:   models.SecurityEventPasswordReset,
+	events.AccountLocked:   models.SecurityEventAccountLocked,
+}
+
+// AuthEventSubscriber listens for logins, password changes and lockouts from the auth service
+// and records them in storefront customers' security logs.
+type AuthEventSubscriber struct {
+	subscriber *events.Subscriber
+	service    *services.SecurityEventService
+	cancel     context.CancelFunc
+}
+
+// NewAuthEventSubscriber creates a new auth event subscriber.
+func NewAuthEventSubscriber(service *services.SecurityEventService, logger *logrus.Logger) (*AuthEventSubscriber, error) {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetLevel(logrus.InfoLevel)
+	}
+
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	config := events.DefaultSubscriberConfig(natsURL, "customers-service-security-events")
+	config.Name = "customers-service-security-events"
+	config.MaxDeliver = 5
+	config.AckWait = 30 * time.Second
+
+	subscriber, err := events.NewSubscriber(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthEventSubscriber{
+		subscriber: subscriber,
+		service:    service,
+	}, nil
+}
+
+// Start begins listening for auth events.
+func (s *AuthEventSubscriber) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	subjects := []string{events.LoginSuccess, events.LoginFailed, events.PasswordChanged, events.PasswordReset, events.AccountLocked}
+	if err := s.subscriber.Subscribe(ctx, events.StreamAuth, subjects, s.handleAuthEvent); err != nil {
+		return err
+	}
+
+	log.Println("[AuthEventSubscriber] Started listening for login, password and lockout events")
+	return nil
+}
+
+// Stop stops the subscriber.
+func (s *AuthEventSubscriber) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.subscriber != nil {
+		s.subscriber.Close()
+	}
+}
+
+// handleAuthEvent records an auth event for the customer it belongs to, if any.
+func (s *AuthEventSubscriber) handleAuthEvent(ctx context.Context, msg *events.Message) error {
+	var event events.AuthEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Printf("[AuthEventSubscriber] Failed to unmarshal auth event: %v", err)
+		return nil // Don't redeliver malformed messages
+	}
+
+	eventType, ok := authEventTypes[event.EventType]
+	if !ok || event.TenantID == "" || (event.UserID == "" && event.Email == "") {
+		return nil
+	}
+
+	activity := services.SecurityActivity{
+		Type:       eventType,
+		IPAddress:  event.IPAddress,
+		UserAgent:  event.UserAgent,
+		DeviceID:   event.DeviceID,
+		DeviceType: event.DeviceType,
+		Location:   event.Location,
+		OccurredAt: event.Timestamp,
+	}
+	switch eventType {
+	case models.SecurityEventLoginSucceeded:
+		if event.LoginMethod != "" {
+			activity.Description = "Signed in with " + event.LoginMethod
+		}
+	case models.SecurityEventLoginFailed:
+		if event.FailedAttempts > 0 {
+			activity.Description = fmt.Sprintf("Failed attempt %d", event.FailedAttempts)
+		}
+	case models.SecurityEventAccountLocked:
+		activity.Description = event.LockReason
+		if event.LockedUntil != "" {
+			activity.Description += " (locked until " + event.LockedUntil + ")"
+		}
+	}
+
+	ctx = tenancy.WithTenant(ctx, event.TenantID)
+	if err := s.service.RecordForUser(ctx, event.TenantID, event.UserID, event.Email, activity); err != nil {
+		log.Printf("[AuthEventSubscriber] Failed to record %s for user %s: %v", event.EventType, event.UserID, err)
+		return err
+	}
+	return nil
+}
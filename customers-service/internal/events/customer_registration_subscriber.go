@@ -14,6 +14,7 @@ import (
 	"github.com/nats-io/nats.go/jetstream"
 	"customers-service/internal/models"
 	"customers-service/internal/services"
+	"customers-service/internal/tenancy"
 )
 
 // CustomerRegistrationSubscriber handles customer.registered events from tenant-service.
@@ -175,6 +176,7 @@ func (s *CustomerRegistrationSubscriber) handleRegistrationEvent(ctx context.Con
 		return fmt.Errorf("missing required fields: email=%s, tenantId=%s, customerId=%s",
 			event.CustomerEmail, event.TenantID, event.CustomerID)
 	}
+	ctx = tenancy.WithTenant(ctx, event.TenantID)
 
 	// Parse customer ID
 	customerID, err := uuid.Parse(event.CustomerID)
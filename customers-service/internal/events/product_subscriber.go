@@ -9,17 +9,19 @@ import (
 	"os"
 	"time"
 
-	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
+	"customers-service/internal/eventconsumer"
 	"customers-service/internal/models"
 	"customers-service/internal/services"
+	"customers-service/internal/tenancy"
 )
 
-// ProductEventSubscriber handles product and inventory events for cart updates.
+// ProductEventSubscriber handles product and inventory events for cart updates. Every replica
+// shares the same durable consumers, so each event is handled once and pods can be added freely.
 type ProductEventSubscriber struct {
-	js                    jetstream.JetStream
+	products              *eventconsumer.Consumer
+	inventory             *eventconsumer.Consumer
 	cartValidationService *services.CartValidationService
-	consumerName          string
 }
 
 // ProductEvent represents a product change event.
@@ -55,65 +57,66 @@ func NewProductEventSubscriber(cartValidationService *services.CartValidationSer
 		natsURL = "nats://nats.nats.svc.cluster.local:4222"
 	}
 
-	nc, err := nats.Connect(natsURL,
-		nats.Name("customers-service"),
-		nats.RetryOnFailedConnect(true),
-		nats.MaxReconnects(-1),                   // Unlimited reconnects for production resilience
-		nats.ReconnectWait(2*time.Second),
-		nats.ReconnectBufSize(8 * 1024 * 1024),   // 8MB buffer for messages during reconnect
-		nats.ReconnectHandler(func(nc *nats.Conn) {
-			log.Printf("[NATS] Reconnected to %s", nc.ConnectedUrl())
-		}),
-		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
-			log.Printf("[NATS] Disconnected: %v", err)
-		}),
-		nats.ClosedHandler(func(nc *nats.Conn) {
-			log.Println("[NATS] Connection closed")
-		}),
-		nats.ErrorHandler(func(nc *nats.Conn, sub *nats.Subscription, err error) {
-			log.Printf("[NATS] Error: %v", err)
-		}),
-	)
+	products, err := eventconsumer.New(eventconsumer.Config{
+		NATSURL:    natsURL,
+		ClientName: "customers-service-cart-products",
+		Durable:    "customers-service-cart-products",
+		Stream:     "PRODUCT_EVENTS",
+		MaxDeliver: 3,
+		AckWait:    30 * time.Second,
+	}, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+		return nil, err
 	}
 
-	js, err := jetstream.New(nc)
+	inventory, err := eventconsumer.New(eventconsumer.Config{
+		NATSURL:    natsURL,
+		ClientName: "customers-service-cart-inventory",
+		Durable:    "customers-service-cart-inventory",
+		Stream:     "INVENTORY_EVENTS",
+		MaxDeliver: 3,
+		AckWait:    30 * time.Second,
+	}, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+		products.Stop()
+		return nil, err
 	}
 
-	hostname, _ := os.Hostname()
-	consumerName := fmt.Sprintf("cart-validator-%s", hostname)
-
 	return &ProductEventSubscriber{
-		js:                    js,
+		products:              products,
+		inventory:             inventory,
 		cartValidationService: cartValidationService,
-		consumerName:          consumerName,
 	}, nil
 }
 
 // Start begins listening for product and inventory events.
 func (s *ProductEventSubscriber) Start(ctx context.Context) error {
 	// Ensure streams exist
-	if err := s.ensureStreams(ctx); err != nil {
-		log.Printf("Warning: failed to ensure streams: %v", err)
-	}
-
-	// Subscribe to product events
-	go s.subscribeToProductEvents(ctx)
+	s.ensureStreams(ctx)
 
-	// Subscribe to inventory events
-	go s.subscribeToInventoryEvents(ctx)
+	if err := s.products.Start(ctx, []string{"product.>"}, s.handleProductEvent); err != nil {
+		return fmt.Errorf("failed to subscribe to product events: %w", err)
+	}
+	if err := s.inventory.Start(ctx, []string{"inventory.>"}, s.handleInventoryEvent); err != nil {
+		return fmt.Errorf("failed to subscribe to inventory events: %w", err)
+	}
 
 	log.Println("Product event subscriber started")
 	return nil
 }
 
+// Stop finishes the events being handled and disconnects.
+func (s *ProductEventSubscriber) Stop() {
+	s.products.Stop()
+	s.inventory.Stop()
+}
+
 // ensureStreams ensures the required streams exist.
-func (s *ProductEventSubscriber) ensureStreams(ctx context.Context) error {
+func (s *ProductEventSubscriber) ensureStreams(ctx context.Context) {
+	js := s.products.JetStream()
+
 	// Try to create or get PRODUCT_EVENTS stream
-	_, err := s.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+	_, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
 		Name:      "PRODUCT_EVENTS",
 		Subjects:  []string{"product.>"},
 		Retention: jetstream.LimitsPolicy,
@@ -126,7 +129,7 @@ func (s *ProductEventSubscriber) ensureStreams(ctx context.Context) error {
 	}
 
 	// Try to create or get INVENTORY_EVENTS stream
-	_, err = s.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+	_, err = js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
 		Name:      "INVENTORY_EVENTS",
 		Subjects:  []string{"inventory.>"},
 		Retention: jetstream.LimitsPolicy,
@@ -137,112 +140,17 @@ func (s *ProductEventSubscriber) ensureStreams(ctx context.Context) error {
 	if err != nil {
 		log.Printf("Warning: could not create INVENTORY_EVENTS stream: %v", err)
 	}
-
-	return nil
-}
-
-// subscribeToProductEvents subscribes to product change events.
-func (s *ProductEventSubscriber) subscribeToProductEvents(ctx context.Context) {
-	consumer, err := s.js.CreateOrUpdateConsumer(ctx, "PRODUCT_EVENTS", jetstream.ConsumerConfig{
-		Durable:       s.consumerName + "-products",
-		FilterSubject: "product.>",
-		AckPolicy:     jetstream.AckExplicitPolicy,
-		AckWait:       30 * time.Second,
-		MaxDeliver:    3,
-		DeliverPolicy: jetstream.DeliverNewPolicy,
-	})
-	if err != nil {
-		log.Printf("Warning: failed to create product events consumer: %v", err)
-		return
-	}
-
-	msgs, err := consumer.Messages()
-	if err != nil {
-		log.Printf("Warning: failed to get product messages iterator: %v", err)
-		return
-	}
-
-	for {
-		select {
-		case <-ctx.Done():
-			msgs.Stop()
-			return
-		default:
-			msg, err := msgs.Next()
-			if err != nil {
-				if err == context.Canceled {
-					return
-				}
-				log.Printf("Error getting next product message: %v", err)
-				time.Sleep(time.Second)
-				continue
-			}
-
-			if err := s.handleProductEvent(ctx, msg); err != nil {
-				log.Printf("Error handling product event: %v", err)
-				msg.Nak()
-			} else {
-				msg.Ack()
-			}
-		}
-	}
-}
-
-// subscribeToInventoryEvents subscribes to inventory change events.
-func (s *ProductEventSubscriber) subscribeToInventoryEvents(ctx context.Context) {
-	consumer, err := s.js.CreateOrUpdateConsumer(ctx, "INVENTORY_EVENTS", jetstream.ConsumerConfig{
-		Durable:       s.consumerName + "-inventory",
-		FilterSubject: "inventory.>",
-		AckPolicy:     jetstream.AckExplicitPolicy,
-		AckWait:       30 * time.Second,
-		MaxDeliver:    3,
-		DeliverPolicy: jetstream.DeliverNewPolicy,
-	})
-	if err != nil {
-		log.Printf("Warning: failed to create inventory events consumer: %v", err)
-		return
-	}
-
-	msgs, err := consumer.Messages()
-	if err != nil {
-		log.Printf("Warning: failed to get inventory messages iterator: %v", err)
-		return
-	}
-
-	for {
-		select {
-		case <-ctx.Done():
-			msgs.Stop()
-			return
-		default:
-			msg, err := msgs.Next()
-			if err != nil {
-				if err == context.Canceled {
-					return
-				}
-				log.Printf("Error getting next inventory message: %v", err)
-				time.Sleep(time.Second)
-				continue
-			}
-
-			if err := s.handleInventoryEvent(ctx, msg); err != nil {
-				log.Printf("Error handling inventory event: %v", err)
-				msg.Nak()
-			} else {
-				msg.Ack()
-			}
-		}
-	}
 }
 
 // handleProductEvent processes a product event.
 func (s *ProductEventSubscriber) handleProductEvent(ctx context.Context, msg jetstream.Msg) error {
 	var event ProductEvent
 	if err := json.Unmarshal(msg.Data(), &event); err != nil {
-		return fmt.Errorf("failed to unmarshal product event: %w", err)
+		return eventconsumer.Permanent(fmt.Errorf("failed to unmarshal product event: %w", err))
 	}
 
 	log.Printf("Processing product event: %s for product %s (tenant: %s)", event.EventType, event.ProductID, event.TenantID)
+	ctx = tenancy.WithTenant(ctx, event.TenantID)
 
 	switch event.EventType {
 	case "product.deleted", "product.archived":
@@ -274,10 +182,11 @@ func (s *ProductEventSubscriber) handleProductEvent(ctx context.Context, msg jet
 func (s *ProductEventSubscriber) handleInventoryEvent(ctx context.Context, msg jetstream.Msg) error {
 	var event InventoryEvent
 	if err := json.Unmarshal(msg.Data(), &event); err != nil {
-		return fmt.Errorf("failed to unmarshal inventory event: %w", err)
+		return eventconsumer.Permanent(fmt.Errorf("failed to unmarshal inventory event: %w", err))
 	}
 
 	log.Printf("Processing inventory event: %s (tenant: %s, items: %d)", event.EventType, event.TenantID, len(event.Items))
+	ctx = tenancy.WithTenant(ctx, event.TenantID)
 
 	switch event.EventType {
 	case "inventory.out_of_stock":
@@ -12,6 +12,22 @@ import (
 	"customers-service/internal/models"
 )
 
+// CartStartedEvent is published when a customer's cart goes from empty to non-empty.
+// It has no go-shared constant yet; orders-service analytics uses it for cart conversion.
+const CartStartedEvent = "customer.cart_started"
+
+// Account deletion events. customers-service asks every participating service to anonymize a
+// customer; each replies with a customer.anonymized event carrying its name in metadata.
+const (
+	AnonymizationRequestedEvent = "customer.anonymization_requested"
+	AnonymizedEvent             = "customer.anonymized"
+)
+
+// SecurityAlertEvent is published when a customer's account sees activity they should be told
+// about, such as a login from a new device or a changed password, so notification and
+// marketing services can send a security alert email. Metadata describes the activity.
+const SecurityAlertEvent = "customer.security_alert"
+
 // Publisher wraps the go-shared events publisher for customer-specific events
 type Publisher struct {
 	publisher *events.Publisher
@@ -82,6 +98,41 @@ func (p *Publisher) PublishCustomerDeleted(ctx context.Context, customer *models
 	return p.publish(ctx, event)
 }
 
+// PublishCartStarted publishes a customer.cart_started event for a cart that just received its first items
+func (p *Publisher) PublishCartStarted(ctx context.Context, customer *models.Customer, tenantID string) error {
+	event := p.buildCustomerEvent(CartStartedEvent, customer, tenantID)
+	return p.publish(ctx, event)
+}
+
+// PublishAnonymizationRequested publishes a customer.anonymization_requested event for an
+// account deletion. The event carries the customer's current details so services can find
+// their copies, and the placeholder email they should replace them with.
+func (p *Publisher) PublishAnonymizationRequested(ctx context.Context, customer *models.Customer, tenantID string, requestID uuid.UUID, anonymizedEmail string) error {
+	event := p.buildCustomerEvent(AnonymizationRequestedEvent, customer, tenantID)
+	event.Metadata = map[string]interface{}{
+		"requestId":       requestID.String(),
+		"anonymizedEmail": anonymizedEmail,
+	}
+	return p.publish(ctx, event)
+}
+
+// PublishSecurityAlert publishes a customer.security_alert event for a security event
+func (p *Publisher) PublishSecurityAlert(ctx context.Context, customer *models.Customer, tenantID string, securityEvent *models.CustomerSecurityEvent) error {
+	event := p.buildCustomerEvent(SecurityAlertEvent, customer, tenantID)
+	event.Metadata = map[string]interface{}{
+		"securityEventId": securityEvent.ID.String(),
+		"type":            string(securityEvent.Type),
+		"suspicious":      securityEvent.Suspicious,
+		"ipAddress":       securityEvent.IPAddress,
+		"userAgent":       securityEvent.UserAgent,
+		"deviceType":      securityEvent.DeviceType,
+		"location":        securityEvent.Location,
+		"description":     securityEvent.Description,
+		"occurredAt":      securityEvent.OccurredAt.Format(time.RFC3339),
+	}
+	return p.publish(ctx, event)
+}
+
 // buildCustomerEvent creates a CustomerEvent from a customer model
 func (p *Publisher) buildCustomerEvent(eventType string, customer *models.Customer, tenantID string) *events.CustomerEvent {
 	event := events.NewCustomerEvent(eventType, tenantID)
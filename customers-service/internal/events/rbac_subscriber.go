@@ -0,0 +1,99 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"customers-service/internal/middleware"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+)
+
+// RBACPermissionsChangedSubject is published by staff-service when roles, role permissions,
+// role assignments or API key scopes change
+const RBACPermissionsChangedSubject = "rbac.permissions_changed"
+
+// PermissionsChangedEvent lists the affected staff; none means the whole tenant
+type PermissionsChangedEvent struct {
+	EventType string    `json:"eventType"`
+	TenantID  string    `json:"tenantId"`
+	Timestamp time.Time `json:"timestamp"`
+	StaffIDs  []string  `json:"staffIds"`
+}
+
+// RBACSubscriber drops cached effective permissions when staff-service changes them.
+// It uses a core NATS subscription rather than a durable consumer, since every replica
+// holds its own cache.
+type RBACSubscriber struct {
+	nc    *nats.Conn
+	sub   *nats.Subscription
+	cache *middleware.RBACPermissionCache
+}
+
+// NewRBACSubscriber creates a new RBAC permissions subscriber.
+func NewRBACSubscriber(cache *middleware.RBACPermissionCache) (*RBACSubscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	nc, err := nats.Connect(natsURL,
+		nats.Name("customers-service-rbac"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(2*time.Second),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			log.Printf("[NATS-RBAC] Reconnected to %s", nc.ConnectedUrl())
+		}),
+		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
+			log.Printf("[NATS-RBAC] Disconnected: %v", err)
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	return &RBACSubscriber{nc: nc, cache: cache}, nil
+}
+
+// Start subscribes to permission changes.
+func (s *RBACSubscriber) Start() error {
+	sub, err := s.nc.Subscribe(RBACPermissionsChangedSubject, s.handleMessage)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", RBACPermissionsChangedSubject, err)
+	}
+	s.sub = sub
+	return nil
+}
+
+func (s *RBACSubscriber) handleMessage(msg *nats.Msg) {
+	var event PermissionsChangedEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil || event.TenantID == "" {
+		log.Printf("[NATS-RBAC] Skipping malformed permissions event")
+		return
+	}
+
+	if len(event.StaffIDs) == 0 {
+		s.cache.InvalidateTenant(event.TenantID)
+		return
+	}
+
+	staffIDs := make([]uuid.UUID, 0, len(event.StaffIDs))
+	for _, id := range event.StaffIDs {
+		if parsed, err := uuid.Parse(id); err == nil {
+			staffIDs = append(staffIDs, parsed)
+		}
+	}
+	s.cache.InvalidateStaff(event.TenantID, staffIDs)
+}
+
+// Stop unsubscribes and closes the NATS connection.
+func (s *RBACSubscriber) Stop() {
+	if s.sub != nil {
+		_ = s.sub.Unsubscribe()
+	}
+	s.nc.Close()
+}
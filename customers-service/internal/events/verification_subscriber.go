@@ -12,6 +12,7 @@ import (
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"customers-service/internal/services"
+	"customers-service/internal/tenancy"
 )
 
 // VerificationEventSubscriber handles verification events for customer email verification.
@@ -182,6 +183,7 @@ func (s *VerificationEventSubscriber) handleVerificationEvent(ctx context.Contex
 	if event.Email == "" || event.TenantID == "" {
 		return fmt.Errorf("missing required fields: email=%s, tenantId=%s", event.Email, event.TenantID)
 	}
+	ctx = tenancy.WithTenant(ctx, event.TenantID)
 
 	// Mark customer email as verified and send welcome email
 	customer, err := s.customerService.VerifyEmailByAddress(ctx, event.TenantID, event.Email)
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"customers-service/internal/services"
+)
+
+// AccountDeletionHandler handles storefront account deletion HTTP requests
+type AccountDeletionHandler struct {
+	service *services.AccountDeletionService
+}
+
+// NewAccountDeletionHandler creates a new account deletion handler
+func NewAccountDeletionHandler(service *services.AccountDeletionService) *AccountDeletionHandler {
+	return &AccountDeletionHandler{service: service}
+}
+
+// RequestDeletionRequest is the body of an account deletion request
+type RequestDeletionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ConfirmDeletionRequest carries the token from the emailed confirmation link
+type ConfirmDeletionRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// RequestDeletion starts an account deletion and emails a confirmation link
+// POST /api/v1/storefront/customers/:id/deletion
+func (h *AccountDeletionHandler) RequestDeletion(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		return
+	}
+
+	var req RequestDeletionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	request, err := h.service.RequestDeletion(c.Request.Context(), tenantID, customerID, req.Reason)
+	if err != nil {
+		respondAccountDeletionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, request)
+}
+
+// ConfirmDeletion confirms an account deletion from the emailed link and schedules it
+// POST /api/v1/storefront/customers/:id/deletion/confirm
+func (h *AccountDeletionHandler) ConfirmDeletion(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		return
+	}
+
+	var req ConfirmDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := h.service.ConfirmDeletion(c.Request.Context(), tenantID, customerID, req.Token)
+	if err != nil {
+		respondAccountDeletionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// CancelDeletion cancels an account deletion that has not started processing
+// POST /api/v1/storefront/customers/:id/deletion/cancel
+func (h *AccountDeletionHandler) CancelDeletion(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		return
+	}
+
+	request, err := h.service.CancelDeletion(c.Request.Context(), tenantID, customerID)
+	if err != nil {
+		respondAccountDeletionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// GetDeletionStatus returns the customer's most recent account deletion request
+// GET /api/v1/storefront/customers/:id/deletion
+func (h *AccountDeletionHandler) GetDeletionStatus(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		return
+	}
+
+	request, err := h.service.GetDeletionStatus(c.Request.Context(), tenantID, customerID)
+	if err != nil {
+		respondAccountDeletionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+// respondAccountDeletionError maps account deletion errors to responses
+func respondAccountDeletionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAccountDeletionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidDeletionToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAccountDeletionInProgress), errors.Is(err, services.ErrAccountDeletionNotCancellable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+	}
+}
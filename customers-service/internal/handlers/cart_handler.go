@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"customers-service/internal/events"
 	"customers-service/internal/models"
 	"customers-service/internal/services"
 	"gorm.io/gorm"
@@ -21,6 +24,7 @@ const (
 type CartHandler struct {
 	db                    *gorm.DB
 	cartValidationService *services.CartValidationService
+	eventsPublisher       *events.Publisher
 }
 
 func NewCartHandler(db *gorm.DB) *CartHandler {
@@ -30,11 +34,13 @@ func NewCartHandler(db *gorm.DB) *CartHandler {
 	}
 }
 
-// NewCartHandlerWithValidation creates a cart handler with a custom validation service
-func NewCartHandlerWithValidation(db *gorm.DB, validationService *services.CartValidationService) *CartHandler {
+// NewCartHandlerWithValidation creates a cart handler with a custom validation service.
+// eventsPublisher may be nil, in which case cart events are not published.
+func NewCartHandlerWithValidation(db *gorm.DB, validationService *services.CartValidationService, eventsPublisher *events.Publisher) *CartHandler {
 	return &CartHandler{
 		db:                    db,
 		cartValidationService: validationService,
+		eventsPublisher:       eventsPublisher,
 	}
 }
 
@@ -62,7 +68,7 @@ func (h *CartHandler) GetCart(c *gin.Context) {
 	includeValidation := c.Query("validate") == "true"
 
 	var cart models.CustomerCart
-	if err := h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
+	if err := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
 		First(&cart).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			// Return empty cart
@@ -145,13 +151,22 @@ func (h *CartHandler) ValidateCart(c *gin.Context) {
 		return
 	}
 
+	// Optional body: {"lockPrices": true, ...} also returns a price-lock token for checkout
+	var lockReq services.CartPriceLockRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&lockReq); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	result, err := h.cartValidationService.ValidateCart(c.Request.Context(), tenantID, customerUUID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Validation failed: %v", err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"cartId":              result.CartID,
 		"items":               result.Items,
 		"subtotal":            result.CurrentSubtotal,
@@ -164,7 +179,20 @@ func (h *CartHandler) ValidateCart(c *gin.Context) {
 		"priceChangedCount":   result.PriceChangedCount,
 		"validatedAt":         result.ValidatedAt,
 		"expiresAt":           result.ExpiresAt,
-	})
+	}
+
+	if lockReq.LockPrices {
+		lock, err := h.cartValidationService.LockPrices(tenantID, customerUUID.String(), result, &lockReq)
+		if err != nil {
+			// The validation result is still useful; the storefront shows why checkout can't lock
+			response["priceLock"] = nil
+			response["priceLockError"] = err.Error()
+		} else {
+			response["priceLock"] = lock
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // RemoveUnavailableItems removes all unavailable items from the cart
@@ -188,7 +216,7 @@ func (h *CartHandler) RemoveUnavailableItems(c *gin.Context) {
 	}
 
 	var cart models.CustomerCart
-	if err := h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
+	if err := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
 		First(&cart).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Cart not found"})
 		return
@@ -234,7 +262,7 @@ func (h *CartHandler) RemoveUnavailableItems(c *gin.Context) {
 		"unavailable_count":     0,
 	}
 
-	if err := h.db.Model(&cart).Updates(updates).Error; err != nil {
+	if err := h.db.WithContext(c.Request.Context()).Model(&cart).Updates(updates).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update cart"})
 		return
 	}
@@ -269,7 +297,7 @@ func (h *CartHandler) AcceptPriceChanges(c *gin.Context) {
 	}
 
 	var cart models.CustomerCart
-	if err := h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
+	if err := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
 		First(&cart).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Cart not found"})
 		return
@@ -298,7 +326,7 @@ func (h *CartHandler) AcceptPriceChanges(c *gin.Context) {
 		"has_price_changes": false,
 	}
 
-	if err := h.db.Model(&cart).Updates(updates).Error; err != nil {
+	if err := h.db.WithContext(c.Request.Context()).Model(&cart).Updates(updates).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update cart"})
 		return
 	}
@@ -341,6 +369,10 @@ func (h *CartHandler) SyncCart(c *gin.Context) {
 		return
 	}
 
+	if !h.validateLineItemProperties(c, tenantID, req.Items) {
+		return
+	}
+
 	now := time.Now()
 
 	// Ensure each item has proper metadata
@@ -380,7 +412,8 @@ func (h *CartHandler) SyncCart(c *gin.Context) {
 
 	// Upsert cart
 	var cart models.CustomerCart
-	result := h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).First(&cart)
+	result := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).First(&cart)
+	previousItemCount := cart.ItemCount
 
 	if result.Error == gorm.ErrRecordNotFound {
 		// Create new cart - set LastItemChange since items are being added
@@ -393,7 +426,7 @@ func (h *CartHandler) SyncCart(c *gin.Context) {
 			LastItemChange: now,
 			ExpiresAt:      &expiresAt,
 		}
-		if err := h.db.Create(&cart).Error; err != nil {
+		if err := h.db.WithContext(c.Request.Context()).Create(&cart).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create cart"})
 			return
 		}
@@ -418,12 +451,16 @@ func (h *CartHandler) SyncCart(c *gin.Context) {
 		// Extend expiration on cart activity
 		cart.ExpiresAt = &expiresAt
 
-		if err := h.db.Save(&cart).Error; err != nil {
+		if err := h.db.WithContext(c.Request.Context()).Save(&cart).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update cart"})
 			return
 		}
 	}
 
+	if previousItemCount == 0 && itemCount > 0 {
+		h.publishCartStarted(c, customerUUID, tenantID)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":   "Cart synced",
 		"id":        cart.ID,
@@ -460,6 +497,12 @@ func (h *CartHandler) AddToCart(c *gin.Context) {
 		return
 	}
 
+	lines := []models.CartItem{newItem}
+	if !h.validateLineItemProperties(c, tenantID, lines) {
+		return
+	}
+	newItem = lines[0]
+
 	now := time.Now()
 
 	// Set item metadata for tracking
@@ -475,7 +518,8 @@ func (h *CartHandler) AddToCart(c *gin.Context) {
 
 	// Get or create cart
 	var cart models.CustomerCart
-	result := h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).First(&cart)
+	result := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).First(&cart)
+	previousItemCount := cart.ItemCount
 
 	var items []models.CartItem
 	if result.Error == gorm.ErrRecordNotFound {
@@ -499,10 +543,10 @@ func (h *CartHandler) AddToCart(c *gin.Context) {
 		}
 	}
 
-	// Check if item already exists (by productId and variantId)
+	// Check if item already exists (by productId, variantId and properties)
 	found := false
 	for i, item := range items {
-		if item.ProductID == newItem.ProductID && item.VariantID == newItem.VariantID {
+		if sameCartLine(item, newItem) {
 			items[i].Quantity += newItem.Quantity
 			// Update price if provided (may have changed)
 			if newItem.Price > 0 {
@@ -542,17 +586,21 @@ func (h *CartHandler) AddToCart(c *gin.Context) {
 	cart.ExpiresAt = &expiresAt
 
 	if cart.ID == uuid.Nil {
-		if err := h.db.Create(&cart).Error; err != nil {
+		if err := h.db.WithContext(c.Request.Context()).Create(&cart).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create cart"})
 			return
 		}
 	} else {
-		if err := h.db.Save(&cart).Error; err != nil {
+		if err := h.db.WithContext(c.Request.Context()).Save(&cart).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update cart"})
 			return
 		}
 	}
 
+	if previousItemCount == 0 && itemCount > 0 {
+		h.publishCartStarted(c, customerUUID, tenantID)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":   "Item added to cart",
 		"id":        cart.ID,
@@ -584,16 +632,22 @@ func (h *CartHandler) UpdateCartItem(c *gin.Context) {
 		return
 	}
 
+	// Either may be omitted; a quantity of 0 or less removes the item
 	var req struct {
-		Quantity int `json:"quantity"`
+		Quantity   *int               `json:"quantity"`
+		Properties *map[string]string `json:"properties"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.Quantity == nil && req.Properties == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "quantity or properties is required"})
+		return
+	}
 
 	var cart models.CustomerCart
-	if err := h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
+	if err := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
 		First(&cart).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Cart not found"})
 		return
@@ -611,11 +665,21 @@ func (h *CartHandler) UpdateCartItem(c *gin.Context) {
 	for _, item := range items {
 		if item.ID == itemID {
 			found = true
-			if req.Quantity > 0 {
-				item.Quantity = req.Quantity
-				newItems = append(newItems, item)
+			if req.Quantity != nil && *req.Quantity <= 0 {
+				continue // Item is removed
 			}
-			// If quantity is 0 or less, item is removed
+			if req.Quantity != nil {
+				item.Quantity = *req.Quantity
+			}
+			if req.Properties != nil {
+				item.Properties = *req.Properties
+				lines := []models.CartItem{item}
+				if !h.validateLineItemProperties(c, tenantID, lines) {
+					return
+				}
+				item = lines[0]
+			}
+			newItems = append(newItems, item)
 		} else {
 			newItems = append(newItems, item)
 		}
@@ -640,7 +704,7 @@ func (h *CartHandler) UpdateCartItem(c *gin.Context) {
 	cart.ItemCount = itemCount
 	cart.LastItemChange = time.Now() // Items were modified
 
-	if err := h.db.Save(&cart).Error; err != nil {
+	if err := h.db.WithContext(c.Request.Context()).Save(&cart).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update cart"})
 		return
 	}
@@ -675,7 +739,7 @@ func (h *CartHandler) RemoveFromCart(c *gin.Context) {
 	}
 
 	var cart models.CustomerCart
-	if err := h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
+	if err := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
 		First(&cart).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Cart not found"})
 		return
@@ -717,7 +781,7 @@ func (h *CartHandler) RemoveFromCart(c *gin.Context) {
 	cart.ItemCount = itemCount
 	cart.LastItemChange = time.Now() // Items were modified
 
-	if err := h.db.Save(&cart).Error; err != nil {
+	if err := h.db.WithContext(c.Request.Context()).Save(&cart).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update cart"})
 		return
 	}
@@ -750,7 +814,7 @@ func (h *CartHandler) ClearCart(c *gin.Context) {
 		return
 	}
 
-	if err := h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
+	if err := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
 		Delete(&models.CustomerCart{}).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear cart"})
 		return
@@ -789,7 +853,7 @@ func (h *CartHandler) GetAbandonedCarts(c *gin.Context) {
 	var carts []models.CustomerCart
 	cutoffTime := time.Now().Add(-time.Duration(abandonedMinutes) * time.Minute)
 
-	if err := h.db.Where("tenant_id = ? AND updated_at < ? AND item_count > 0", tenantID, cutoffTime).
+	if err := h.db.WithContext(c.Request.Context()).Where("tenant_id = ? AND updated_at < ? AND item_count > 0", tenantID, cutoffTime).
 		Order("updated_at DESC").
 		Find(&carts).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch abandoned carts"})
@@ -804,7 +868,7 @@ func (h *CartHandler) GetAbandonedCarts(c *gin.Context) {
 
 	var customers []models.Customer
 	if len(customerIDs) > 0 {
-		h.db.Where("id IN ?", customerIDs).Find(&customers)
+		h.db.WithContext(c.Request.Context()).Where("id IN ?", customerIDs).Find(&customers)
 	}
 
 	// Create customer map for quick lookup
@@ -893,7 +957,8 @@ func (h *CartHandler) MergeCart(c *gin.Context) {
 	var cart models.CustomerCart
 	var existingItems []models.CartItem
 
-	result := h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).First(&cart)
+	result := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).First(&cart)
+	previousItemCount := cart.ItemCount
 	if result.Error == nil && len(cart.Items) > 0 {
 		json.Unmarshal(cart.Items, &existingItems)
 	} else if result.Error == gorm.ErrRecordNotFound {
@@ -906,11 +971,15 @@ func (h *CartHandler) MergeCart(c *gin.Context) {
 		return
 	}
 
+	if !h.validateLineItemProperties(c, tenantID, req.GuestItems) {
+		return
+	}
+
 	// Merge guest items into existing items
 	for _, guestItem := range req.GuestItems {
 		found := false
 		for i, existingItem := range existingItems {
-			if existingItem.ProductID == guestItem.ProductID && existingItem.VariantID == guestItem.VariantID {
+			if sameCartLine(existingItem, guestItem) {
 				// Add quantities
 				existingItems[i].Quantity += guestItem.Quantity
 				found = true
@@ -937,17 +1006,21 @@ func (h *CartHandler) MergeCart(c *gin.Context) {
 	cart.LastItemChange = time.Now() // Items were modified by merge
 
 	if cart.ID == uuid.Nil {
-		if err := h.db.Create(&cart).Error; err != nil {
+		if err := h.db.WithContext(c.Request.Context()).Create(&cart).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create cart"})
 			return
 		}
 	} else {
-		if err := h.db.Save(&cart).Error; err != nil {
+		if err := h.db.WithContext(c.Request.Context()).Save(&cart).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update cart"})
 			return
 		}
 	}
 
+	if previousItemCount == 0 && itemCount > 0 {
+		h.publishCartStarted(c, customerUUID, tenantID)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":   "Cart merged",
 		"id":        cart.ID,
@@ -956,3 +1029,38 @@ func (h *CartHandler) MergeCart(c *gin.Context) {
 		"itemCount": itemCount,
 	})
 }
+
+// validateLineItemProperties checks the items' properties against their products' rules,
+// normalizing them in place. It writes the error response and returns false on failure.
+func (h *CartHandler) validateLineItemProperties(c *gin.Context, tenantID string, items []models.CartItem) bool {
+	err := h.cartValidationService.ValidateLineItemProperties(c.Request.Context(), tenantID, items)
+	if errors.Is(err, services.ErrInvalidLineItemProperties) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate cart items"})
+		return false
+	}
+	return true
+}
+
+// sameCartLine reports whether two items are the same cart line: the same product and
+// variant with the same customizations
+func sameCartLine(a, b models.CartItem) bool {
+	return a.ProductID == b.ProductID && a.VariantID == b.VariantID && maps.Equal(a.Properties, b.Properties)
+}
+
+// publishCartStarted emits customer.cart_started when a cart receives its first items.
+// Failures are logged by the publisher and never block the cart request.
+func (h *CartHandler) publishCartStarted(c *gin.Context, customerID uuid.UUID, tenantID string) {
+	if h.eventsPublisher == nil {
+		return
+	}
+
+	var customer models.Customer
+	if err := h.db.WithContext(c.Request.Context()).Where("id = ? AND tenant_id = ?", customerID, tenantID).First(&customer).Error; err != nil {
+		return
+	}
+	_ = h.eventsPublisher.PublishCartStarted(c.Request.Context(), &customer, tenantID)
+}
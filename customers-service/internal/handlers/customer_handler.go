@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -12,7 +14,9 @@ import (
 	"github.com/Tesseract-Nexus/go-shared/security"
 	"customers-service/internal/clients"
 	"customers-service/internal/events"
+	"customers-service/internal/fieldset"
 	"customers-service/internal/models"
+	"customers-service/internal/repository"
 	"customers-service/internal/services"
 )
 
@@ -80,13 +84,36 @@ func (h *CustomerHandler) GetCustomer(c *gin.Context) {
 		return
 	}
 
+	selection, ok := parseCustomerFieldset(c)
+	if !ok {
+		return
+	}
+
 	customer, err := h.service.GetCustomer(c.Request.Context(), tenantID, customerID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, customer)
+	body, err := selection.Filter(customer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		return
+	}
+
+	setVersionETag(c, customer.Version)
+	c.JSON(http.StatusOK, body)
+}
+
+// parseCustomerFieldset reads ?fields= and ?include= for a customer response, answering 400
+// when they are invalid
+func parseCustomerFieldset(c *gin.Context) (*fieldset.Selection, bool) {
+	selection, err := fieldset.Parse(c, repository.CustomerRelations...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return selection, true
 }
 
 // BatchGetCustomers retrieves multiple customers by IDs in a single request
@@ -228,8 +255,25 @@ func (h *CustomerHandler) UpdateCustomer(c *gin.Context) {
 		return
 	}
 
+	// If-Match takes precedence over expectedVersion in the body
+	if version, present, err := parseIfMatch(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if present {
+		req.ExpectedVersion = version
+	}
+
 	customer, err := h.service.UpdateCustomer(c.Request.Context(), tenantID, customerID, req)
 	if err != nil {
+		var conflict *models.VersionConflictError
+		if errors.As(err, &conflict) {
+			setVersionETag(c, conflict.CurrentVersion)
+			c.JSON(http.StatusConflict, gin.H{
+				"error":          "customer was modified by another request",
+				"currentVersion": conflict.CurrentVersion,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
 		return
 	}
@@ -239,9 +283,31 @@ func (h *CustomerHandler) UpdateCustomer(c *gin.Context) {
 		_ = h.eventsPublisher.PublishCustomerUpdated(c.Request.Context(), customer, tenantID)
 	}
 
+	setVersionETag(c, customer.Version)
 	c.JSON(http.StatusOK, customer)
 }
 
+// parseIfMatch reads the If-Match header as a customer version. Accepts "N",
+// W/"N" or a bare N; "*" (or no header) means the update is unconditional and
+// present is false in that case.
+func parseIfMatch(c *gin.Context) (version *int, present bool, err error) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" || raw == "*" {
+		return nil, false, nil
+	}
+	raw = strings.Trim(strings.TrimPrefix(raw, "W/"), "\"")
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 1 {
+		return nil, true, fmt.Errorf("invalid If-Match header: expected a customer version")
+	}
+	return &v, true, nil
+}
+
+// setVersionETag exposes the customer version so clients can send it back in If-Match
+func setVersionETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf("\"%d\"", version))
+}
+
 // ListCustomers handles GET /api/v1/customers
 func (h *CustomerHandler) ListCustomers(c *gin.Context) {
 	tenantID := c.GetString("tenant_id")
@@ -280,13 +346,25 @@ func (h *CustomerHandler) ListCustomers(c *gin.Context) {
 	req.SortBy = c.Query("sort_by")
 	req.SortOrder = c.Query("sort_order")
 
+	selection, ok := parseCustomerFieldset(c)
+	if !ok {
+		return
+	}
+	req.Include = selection.Relations()
+
 	response, err := h.service.ListCustomers(c.Request.Context(), req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	body, err := selection.FilterIn(response, "customers")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		return
+	}
+
+	c.JSON(http.StatusOK, body)
 }
 
 // DeleteCustomer handles DELETE /api/v1/customers/:id
@@ -321,6 +399,56 @@ func (h *CustomerHandler) DeleteCustomer(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "customer deleted successfully"})
 }
 
+// ListTrashedCustomers handles GET /api/v1/customers/trash
+func (h *CustomerHandler) ListTrashedCustomers(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		tenantID = c.Query("tenant_id")
+	}
+
+	var page, pageSize int
+	if c.Query("page") != "" {
+		fmt.Sscanf(c.Query("page"), "%d", &page)
+	}
+	if c.Query("page_size") != "" {
+		fmt.Sscanf(c.Query("page_size"), "%d", &pageSize)
+	}
+
+	response, err := h.service.ListDeletedCustomers(c.Request.Context(), tenantID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RestoreCustomer handles POST /api/v1/customers/:id/restore
+func (h *CustomerHandler) RestoreCustomer(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		tenantID = c.Query("tenant_id")
+	}
+
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid customer ID"})
+		return
+	}
+
+	customer, err := h.service.RestoreCustomer(c.Request.Context(), tenantID, customerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		return
+	}
+	if customer == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "customer not found in trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, customer)
+}
+
 // AddAddress handles POST /api/v1/customers/:id/addresses
 // Creates a new address for a customer with comprehensive validation
 func (h *CustomerHandler) AddAddress(c *gin.Context) {
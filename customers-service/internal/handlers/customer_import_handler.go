@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"customers-service/internal/models"
+	"customers-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CustomerImportHandler handles bulk customer import HTTP requests
+type CustomerImportHandler struct {
+	service *services.CustomerImportService
+}
+
+// NewCustomerImportHandler creates a new customer import handler
+func NewCustomerImportHandler(service *services.CustomerImportService) *CustomerImportHandler {
+	return &CustomerImportHandler{service: service}
+}
+
+// importJobResponse adds the progress percentage to an import job
+type importJobResponse struct {
+	*models.CustomerImportJob
+	PercentComplete int `json:"percentComplete"`
+}
+
+func newImportJobResponse(job *models.CustomerImportJob) importJobResponse {
+	return importJobResponse{CustomerImportJob: job, PercentComplete: job.PercentComplete()}
+}
+
+// GetImportTemplate returns a CSV template with every import column and an example row
+// GET /api/v1/customers/import/template
+func (h *CustomerImportHandler) GetImportTemplate(c *gin.Context) {
+	example := map[string]string{
+		"email":          "jane.doe@example.com",
+		"firstName":      "Jane",
+		"lastName":       "Doe",
+		"phone":          "+61400000000",
+		"dateOfBirth":    "1990-04-21",
+		"country":        "Australia",
+		"countryCode":    "AU",
+		"customerType":   "RETAIL",
+		"tags":           "vip|newsletter",
+		"notes":          "Imported from previous store",
+		"marketingOptIn": "yes",
+		"company":        "",
+		"addressLine1":   "1 Example Street",
+		"addressLine2":   "",
+		"city":           "Sydney",
+		"state":          "NSW",
+		"postalCode":     "2000",
+		"addressCountry": "AU",
+	}
+
+	headers := make([]string, len(models.CustomerImportColumns))
+	row := make([]string, len(models.CustomerImportColumns))
+	for i, col := range models.CustomerImportColumns {
+		headers[i] = col.Name
+		if col.Required {
+			headers[i] += " *"
+		}
+		row[i] = example[col.Name]
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(headers)
+	w.Write(row)
+	w.Flush()
+
+	c.Header("Content-Disposition", `attachment; filename="customer_import_template.csv"`)
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+// PreviewImport shows how the first rows of an uploaded file would be mapped and validated
+// POST /api/v1/customers/import/preview
+func (h *CustomerImportHandler) PreviewImport(c *gin.Context) {
+	format, data, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+
+	mapping, ok := parseImportMapping(c)
+	if !ok {
+		return
+	}
+
+	limit := 10
+	if rows := c.PostForm("rows"); rows != "" {
+		if n, err := strconv.Atoi(rows); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	preview, err := h.service.PreviewImport(format, data, mapping, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"preview": preview,
+		"columns": models.CustomerImportColumns,
+	})
+}
+
+// StartImport queues an uploaded file for import
+// POST /api/v1/customers/import
+func (h *CustomerImportHandler) StartImport(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		tenantID = c.Query("tenant_id")
+	}
+
+	format, data, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+
+	mapping, ok := parseImportMapping(c)
+	if !ok {
+		return
+	}
+
+	segmentIDs, err := parseImportSegmentIDs(c.PostForm("segmentIds"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(c.PostForm("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	strategy := models.DuplicateStrategy(strings.ToLower(c.DefaultPostForm("duplicateStrategy", string(models.DuplicateSkip))))
+	file, _ := c.FormFile("file")
+
+	job, err := h.service.CreateImportJob(c.Request.Context(), services.CreateImportJobRequest{
+		TenantID:              tenantID,
+		CreatedBy:             c.GetString("user_id"),
+		FileName:              file.Filename,
+		Format:                format,
+		Data:                  data,
+		Mapping:               mapping,
+		DuplicateStrategy:     strategy,
+		SegmentIDs:            segmentIDs,
+		Tags:                  tags,
+		DefaultMarketingOptIn: c.PostForm("defaultMarketingOptIn") == "true",
+		MarkEmailVerified:     c.PostForm("markEmailVerified") == "true",
+	})
+	if err != nil {
+		respondCustomerImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newImportJobResponse(job))
+}
+
+// ListImportJobs lists the tenant's import jobs
+// GET /api/v1/customers/import/jobs
+func (h *CustomerImportHandler) ListImportJobs(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		tenantID = c.Query("tenant_id")
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	jobs, total, err := h.service.ListImportJobs(c.Request.Context(), tenantID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		return
+	}
+
+	items := make([]importJobResponse, len(jobs))
+	for i := range jobs {
+		items[i] = newImportJobResponse(&jobs[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":       items,
+		"total":      total,
+		"page":       page,
+		"pageSize":   pageSize,
+		"totalPages": int((total + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
+
+// GetImportJob returns an import job and its progress
+// GET /api/v1/customers/import/jobs/:jobId
+func (h *CustomerImportHandler) GetImportJob(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		tenantID = c.Query("tenant_id")
+	}
+
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import job ID"})
+		return
+	}
+
+	job, err := h.service.GetImportJob(c.Request.Context(), tenantID, jobID)
+	if err != nil {
+		respondCustomerImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newImportJobResponse(job))
+}
+
+// GetImportErrors returns an import job's per-row error report as JSON or CSV
+// GET /api/v1/customers/import/jobs/:jobId/errors?format=csv
+func (h *CustomerImportHandler) GetImportErrors(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		tenantID = c.Query("tenant_id")
+	}
+
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import job ID"})
+		return
+	}
+
+	rowErrors, err := h.service.GetImportErrors(c.Request.Context(), tenantID, jobID)
+	if err != nil {
+		respondCustomerImportError(c, err)
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, gin.H{
+			"errors": rowErrors,
+			"total":  len(rowErrors),
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"row", "email", "column", "code", "message"})
+	for _, e := range rowErrors {
+		w.Write([]string{strconv.Itoa(e.Row), e.Email, e.Column, e.Code, e.Message})
+	}
+	w.Flush()
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="customer_import_%s_errors.csv"`, jobID))
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+// ResumeImportJob re-queues a failed import from the row where it stopped
+// POST /api/v1/customers/import/jobs/:jobId/resume
+func (h *CustomerImportHandler) ResumeImportJob(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		tenantID = c.Query("tenant_id")
+	}
+
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import job ID"})
+		return
+	}
+
+	job, err := h.service.ResumeImportJob(c.Request.Context(), tenantID, jobID)
+	if err != nil {
+		respondCustomerImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newImportJobResponse(job))
+}
+
+// readImportFile reads the uploaded "file" form field and detects its format. It writes the
+// error response and returns false when the upload is missing, too large or unsupported.
+func readImportFile(c *gin.Context) (models.CustomerImportFormat, []byte, bool) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return "", nil, false
+	}
+	if file.Size > models.MaxCustomerImportFileBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": fmt.Sprintf("file exceeds the %d MB import limit", models.MaxCustomerImportFileBytes/(1024*1024)),
+		})
+		return "", nil, false
+	}
+
+	format, ok := services.DetectImportFormat(file.Filename)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported file type. Upload a .csv or .xlsx file"})
+		return "", nil, false
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return "", nil, false
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return "", nil, false
+	}
+	return format, data, true
+}
+
+// parseImportMapping reads the optional "mapping" form field, a JSON object of source header
+// to import column
+func parseImportMapping(c *gin.Context) (map[string]string, bool) {
+	raw := c.PostForm("mapping")
+	if raw == "" {
+		return nil, true
+	}
+
+	var mapping map[string]string
+	if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mapping must be a JSON object of source header to column"})
+		return nil, false
+	}
+
+	normalized, err := services.NormalizeColumnMapping(mapping)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return normalized, true
+}
+
+// parseImportSegmentIDs accepts segment IDs as a JSON array or a comma-separated list
+func parseImportSegmentIDs(raw string) ([]uuid.UUID, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	var values []string
+	if strings.HasPrefix(raw, "[") {
+		if err := json.Unmarshal([]byte(raw), &values); err != nil {
+			return nil, fmt.Errorf("segmentIds must be a JSON array or comma-separated list")
+		}
+	} else {
+		values = strings.Split(raw, ",")
+	}
+
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid segment ID: %s", value)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func respondCustomerImportError(c *gin.Context, err error) {
+	var validationErr *services.ImportValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Message, "code": validationErr.Code})
+	case errors.Is(err, services.ErrImportJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrImportJobNotResumable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+	}
+}
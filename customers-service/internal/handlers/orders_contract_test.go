@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"os"
+	"testing"
+
+	"customers-service/internal/contracts"
+	"customers-service/internal/models"
+	"customers-service/internal/services"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+// ordersContractChecks verify customers-service against each contract orders-service keeps for
+// it in orders-service/contracts/customers-service. A new contract there needs a check here.
+var ordersContractChecks = map[string]func(t *testing.T, c *contracts.Contract){
+	"customer-search": func(t *testing.T, c *contracts.Contract) {
+		checkContractResponse(t, c, services.ListCustomersResponse{
+			Customers:  []models.Customer{contractCustomer()},
+			Total:      1,
+			Page:       1,
+			PageSize:   20,
+			TotalPages: 1,
+		})
+	},
+	"customer-create": func(t *testing.T, c *contracts.Contract) {
+		var req services.CreateCustomerRequest
+		checkContractRequest(t, c, &req)
+		checkContractResponse(t, c, contractCustomer())
+	},
+	// RecordOrder responds with its own summary; orders-service only reads the status
+	"record-order": func(t *testing.T, c *contracts.Contract) {
+		var req RecordOrderRequest
+		checkContractRequest(t, c, &req)
+	},
+}
+
+func contractCustomer() models.Customer {
+	return models.Customer{
+		ID:        uuid.New(),
+		TenantID:  "acme",
+		Email:     "priya@example.com",
+		FirstName: "Priya",
+		LastName:  "Sharma",
+		Phone:     "+919800000000",
+		Status:    models.CustomerStatusActive,
+	}
+}
+
+// TestOrdersServiceContracts checks customers-service still accepts what orders-service sends
+// and returns what it reads
+func TestOrdersServiceContracts(t *testing.T) {
+	all, err := contracts.Load(os.DirFS("../../../orders-service/contracts"), "customers-service")
+	if err != nil {
+		t.Fatalf("failed to load orders-service contracts: %v", err)
+	}
+	for i := range all {
+		contract := &all[i]
+		t.Run(contract.ID(), func(t *testing.T) {
+			check, ok := ordersContractChecks[contract.Name]
+			if !ok {
+				t.Fatalf("no customers-service check for %s", contract.ID())
+			}
+			check(t, contract)
+		})
+	}
+}
+
+func checkContractRequest(t *testing.T, c *contracts.Contract, req interface{}) {
+	t.Helper()
+	if err := c.DecodeRequest(req); err != nil {
+		t.Fatalf("request doesn't decode: %v", err)
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		t.Errorf("request fails validation: %v", err)
+	}
+}
+
+func checkContractResponse(t *testing.T, c *contracts.Contract, resp interface{}) {
+	t.Helper()
+	if err := c.VerifyResponse(resp); err != nil {
+		t.Errorf("response doesn't give orders-service what it expects: %v", err)
+	}
+}
@@ -2,25 +2,30 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"customers-service/internal/models"
+	"customers-service/internal/services"
+	"gorm.io/gorm"
 )
 
 // PaymentMethodHandler handles payment method HTTP requests
 type PaymentMethodHandler struct {
 	service interface {
 		GetPaymentMethods(ctx context.Context, tenantID string, customerID uuid.UUID) ([]models.CustomerPaymentMethod, error)
-		DeletePaymentMethod(ctx context.Context, tenantID string, methodID uuid.UUID) error
+		AddPaymentMethod(ctx context.Context, tenantID string, customerID uuid.UUID, req services.AddPaymentMethodRequest, ipAddress, userAgent string) (*models.CustomerPaymentMethod, error)
+		DeletePaymentMethod(ctx context.Context, tenantID string, customerID, methodID uuid.UUID, ipAddress, userAgent string) error
 	}
 }
 
 // NewPaymentMethodHandler creates a new payment method handler
 func NewPaymentMethodHandler(service interface {
 	GetPaymentMethods(ctx context.Context, tenantID string, customerID uuid.UUID) ([]models.CustomerPaymentMethod, error)
-	DeletePaymentMethod(ctx context.Context, tenantID string, methodID uuid.UUID) error
+	AddPaymentMethod(ctx context.Context, tenantID string, customerID uuid.UUID, req services.AddPaymentMethodRequest, ipAddress, userAgent string) (*models.CustomerPaymentMethod, error)
+	DeletePaymentMethod(ctx context.Context, tenantID string, customerID, methodID uuid.UUID, ipAddress, userAgent string) error
 }) *PaymentMethodHandler {
 	return &PaymentMethodHandler{service: service}
 }
@@ -47,6 +52,38 @@ func (h *PaymentMethodHandler) GetPaymentMethods(c *gin.Context) {
 	c.JSON(http.StatusOK, paymentMethods)
 }
 
+// AddPaymentMethod handles POST /api/v1/customers/:id/payment-methods
+func (h *PaymentMethodHandler) AddPaymentMethod(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		tenantID = c.Query("tenant_id")
+	}
+
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid customer ID"})
+		return
+	}
+
+	var req services.AddPaymentMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	method, err := h.service.AddPaymentMethod(c.Request.Context(), tenantID, customerID, req, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, method)
+}
+
 // DeletePaymentMethod handles DELETE /api/v1/customers/:id/payment-methods/:methodId
 func (h *PaymentMethodHandler) DeletePaymentMethod(c *gin.Context) {
 	tenantID := c.GetString("tenant_id")
@@ -54,13 +91,23 @@ func (h *PaymentMethodHandler) DeletePaymentMethod(c *gin.Context) {
 		tenantID = c.Query("tenant_id")
 	}
 
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid customer ID"})
+		return
+	}
+
 	methodID, err := uuid.Parse(c.Param("methodId"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payment method ID"})
 		return
 	}
 
-	if err := h.service.DeletePaymentMethod(c.Request.Context(), tenantID, methodID); err != nil {
+	if err := h.service.DeletePaymentMethod(c.Request.Context(), tenantID, customerID, methodID, c.ClientIP(), c.Request.UserAgent()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "payment method not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
 		return
 	}
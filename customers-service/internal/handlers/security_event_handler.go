@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"customers-service/internal/models"
+	"customers-service/internal/repository"
+	"customers-service/internal/services"
+)
+
+// SecurityEventHandler handles customer security audit HTTP requests
+type SecurityEventHandler struct {
+	service *services.SecurityEventService
+}
+
+// NewSecurityEventHandler creates a new security event handler
+func NewSecurityEventHandler(service *services.SecurityEventService) *SecurityEventHandler {
+	return &SecurityEventHandler{service: service}
+}
+
+// ListSecurityEvents returns a customer's logins, account changes and suspicious activity,
+// most recent first. Filter with ?type= and ?suspicious=true.
+// GET /api/v1/customers/:id/security-events
+func (h *SecurityEventHandler) ListSecurityEvents(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		tenantID = c.Query("tenant_id")
+	}
+
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid customer ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	filter := repository.SecurityEventFilter{
+		Type:           models.SecurityEventType(c.Query("type")),
+		SuspiciousOnly: c.Query("suspicious") == "true",
+	}
+
+	events, total, err := h.service.ListEvents(c.Request.Context(), tenantID, customerID, filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":     events,
+		"total":      total,
+		"page":       page,
+		"pageSize":   pageSize,
+		"totalPages": int((total + int64(pageSize) - 1) / int64(pageSize)),
+	})
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"customers-service/internal/services"
+	"gorm.io/gorm"
 )
 
 // SegmentHandler handles segment HTTP requests
@@ -219,3 +222,67 @@ func (h *SegmentHandler) GetSegmentCustomers(c *gin.Context) {
 
 	c.JSON(http.StatusOK, customers)
 }
+
+// GetSegmentAudience handles GET /internal/customers/segments/:id/audience?after=&limit=
+// Used by marketing-service to export a segment's opted-in members to marketing platforms
+func (h *SegmentHandler) GetSegmentAudience(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tenant_id is required"})
+		return
+	}
+
+	segmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid segment ID"})
+		return
+	}
+	after := uuid.Nil
+	if value := c.Query("after"); value != "" {
+		if after, err = uuid.Parse(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid after cursor"})
+			return
+		}
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	members, err := h.service.GetSegmentAudience(c.Request.Context(), tenantID, segmentID, after, limit)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "segment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// GetCustomerTargeting handles GET /internal/customers/:id/targeting
+// Used by coupons-service to check customer- and segment-targeted coupons
+func (h *SegmentHandler) GetCustomerTargeting(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tenant_id is required"})
+		return
+	}
+
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid customer ID"})
+		return
+	}
+
+	targeting, err := h.service.GetCustomerTargeting(c.Request.Context(), tenantID, customerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		return
+	}
+
+	c.JSON(http.StatusOK, targeting)
+}
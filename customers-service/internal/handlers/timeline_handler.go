@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"customers-service/internal/services"
+)
+
+const (
+	defaultTimelineLimit = 100
+	maxTimelineLimit     = 200
+)
+
+// TimelineHandler handles customer timeline HTTP requests
+type TimelineHandler struct {
+	service *services.TimelineService
+}
+
+// NewTimelineHandler creates a new timeline handler
+func NewTimelineHandler(service *services.TimelineService) *TimelineHandler {
+	return &TimelineHandler{service: service}
+}
+
+// GetTimeline returns the customer's orders, payments, tickets, reviews, loyalty transactions
+// and communications as one chronological feed, newest first
+// GET /api/v1/customers/:id/timeline?limit=100
+func (h *TimelineHandler) GetTimeline(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid customer ID"})
+		return
+	}
+
+	limit := defaultTimelineLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > maxTimelineLimit {
+		limit = maxTimelineLimit
+	}
+
+	timeline, err := h.service.GetTimeline(c.Request.Context(), tenantID, customerID, forwardedHeaders(c), limit)
+	if err != nil {
+		if errors.Is(err, services.ErrTimelineCustomerNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+// forwardedHeaders returns the caller's auth, tenant and user headers for calls to other
+// services made on their behalf
+func forwardedHeaders(c *gin.Context) http.Header {
+	headers := http.Header{}
+	for key, values := range c.Request.Header {
+		canonical := http.CanonicalHeaderKey(key)
+		switch {
+		case canonical == "Authorization",
+			canonical == "X-Tenant-Id",
+			canonical == "X-Vendor-Id",
+			canonical == "X-User-Id",
+			strings.HasPrefix(canonical, "X-Jwt-Claim-"):
+			headers[canonical] = values
+		}
+	}
+	if headers.Get("X-Tenant-ID") == "" {
+		headers.Set("X-Tenant-ID", c.GetString("tenant_id"))
+	}
+	return headers
+}
@@ -38,7 +38,7 @@ func (h *WishlistHandler) GetWishlist(c *gin.Context) {
 	}
 
 	var items []models.CustomerWishlistItem
-	if err := h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
+	if err := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
 		Order("added_at DESC").
 		Find(&items).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch wishlist"})
@@ -85,7 +85,7 @@ func (h *WishlistHandler) AddToWishlist(c *gin.Context) {
 
 	// Check if already in wishlist
 	var existing models.CustomerWishlistItem
-	if err := h.db.Where("customer_id = ? AND tenant_id = ? AND product_id = ?",
+	if err := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ? AND product_id = ?",
 		customerUUID, tenantID, req.ProductID).First(&existing).Error; err == nil {
 		c.JSON(http.StatusOK, gin.H{
 			"message": "Item already in wishlist",
@@ -103,7 +103,7 @@ func (h *WishlistHandler) AddToWishlist(c *gin.Context) {
 		ProductImage: req.ProductImage,
 	}
 
-	if err := h.db.Create(&item).Error; err != nil {
+	if err := h.db.WithContext(c.Request.Context()).Create(&item).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add to wishlist"})
 		return
 	}
@@ -135,7 +135,7 @@ func (h *WishlistHandler) RemoveFromWishlist(c *gin.Context) {
 		return
 	}
 
-	result := h.db.Where("customer_id = ? AND tenant_id = ? AND product_id = ?",
+	result := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ? AND product_id = ?",
 		customerUUID, tenantID, productID).Delete(&models.CustomerWishlistItem{})
 
 	if result.Error != nil {
@@ -186,7 +186,7 @@ func (h *WishlistHandler) SyncWishlist(c *gin.Context) {
 	}
 
 	// Start transaction
-	tx := h.db.Begin()
+	tx := h.db.WithContext(c.Request.Context()).Begin()
 
 	// Delete existing items
 	if err := tx.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
@@ -217,7 +217,7 @@ func (h *WishlistHandler) SyncWishlist(c *gin.Context) {
 
 	// Return updated wishlist
 	var items []models.CustomerWishlistItem
-	h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
+	h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
 		Order("added_at DESC").Find(&items)
 
 	c.JSON(http.StatusOK, gin.H{
@@ -247,7 +247,7 @@ func (h *WishlistHandler) ClearWishlist(c *gin.Context) {
 		return
 	}
 
-	if err := h.db.Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
+	if err := h.db.WithContext(c.Request.Context()).Where("customer_id = ? AND tenant_id = ?", customerUUID, tenantID).
 		Delete(&models.CustomerWishlistItem{}).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear wishlist"})
 		return
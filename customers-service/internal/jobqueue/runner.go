@@ -0,0 +1,566 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultTimeout bounds a single attempt of a job
+	DefaultTimeout = 10 * time.Minute
+
+	// DefaultMaxAttempts is how often a failing run is attempted before it becomes a dead letter
+	DefaultMaxAttempts = 3
+
+	// DefaultRetryBackoff is the wait before the first retry; it doubles for each further retry
+	DefaultRetryBackoff = 30 * time.Second
+
+	// PollInterval is how often each replica looks for due jobs
+	PollInterval = 5 * time.Second
+
+	// RunRetention is how long run history is kept. Unresolved dead letters are kept until
+	// an admin retries or dismisses them.
+	RunRetention = 30 * 24 * time.Hour
+
+	pruneInterval = time.Hour
+)
+
+var (
+	// ErrJobNotFound is returned for a job that is not registered in this service
+	ErrJobNotFound = errors.New("job not found")
+
+	// ErrRunNotFound is returned for an unknown run
+	ErrRunNotFound = errors.New("job run not found")
+
+	// ErrNotDeadLetter is returned when retrying or dismissing a run that is not an
+	// unresolved dead letter
+	ErrNotDeadLetter = errors.New("job run is not an unresolved dead letter")
+)
+
+// Job is a recurring unit of background work
+type Job struct {
+	Name         string
+	Description  string
+	Interval     time.Duration // Time between the end of one run and the start of the next
+	StartDelay   time.Duration // Delay before the first run on a database that never ran the job
+	Timeout      time.Duration
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	Run          func(ctx context.Context) error
+}
+
+// Runner schedules registered jobs on this replica and serves the admin operations
+type Runner struct {
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+	instance string
+
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	active map[string]bool // Jobs running on this replica
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	lastPrune time.Time
+}
+
+// NewRunner creates a runner storing job state in db
+func NewRunner(db *gorm.DB, logger logrus.FieldLogger) *Runner {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	return &Runner{
+		db:       db,
+		logger:   logger,
+		instance: fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8]),
+		jobs:     make(map[string]*Job),
+		active:   make(map[string]bool),
+	}
+}
+
+// Register adds a job. Jobs must be registered before Start.
+func (r *Runner) Register(job Job) {
+	if job.Name == "" || job.Run == nil || job.Interval <= 0 {
+		panic(fmt.Sprintf("jobqueue: job %q needs a name, an interval and a Run function", job.Name))
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = DefaultTimeout
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+	if job.RetryBackoff <= 0 {
+		job.RetryBackoff = DefaultRetryBackoff
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.jobs[job.Name]; exists {
+		panic(fmt.Sprintf("jobqueue: job %q registered twice", job.Name))
+	}
+	r.jobs[job.Name] = &job
+}
+
+// Start records the registered jobs and begins polling for due runs. Existing jobs keep their
+// schedule, pause state and metrics.
+func (r *Runner) Start() error {
+	now := time.Now()
+	for _, job := range r.registered() {
+		state := JobState{
+			Name:            job.Name,
+			Description:     job.Description,
+			IntervalSeconds: int64(job.Interval / time.Second),
+			NextRunAt:       now.Add(job.StartDelay),
+		}
+		err := r.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"description", "interval_seconds", "updated_at"}),
+		}).Create(&state).Error
+		if err != nil {
+			return fmt.Errorf("failed to register job %s: %w", job.Name, err)
+		}
+	}
+
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	r.wg.Add(1)
+	go r.loop()
+	r.logger.WithField("instance", r.instance).Infof("Background job runner started with %d jobs", len(r.jobs))
+	return nil
+}
+
+// Stop stops polling, cancels running jobs and waits for them to record their outcome.
+// Interrupted jobs are rescheduled to run right away on another replica.
+func (r *Runner) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+	r.logger.Info("Background job runner stopped")
+}
+
+func (r *Runner) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.poll()
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll claims and starts every due job that is not running anywhere
+func (r *Runner) poll() {
+	jobs := r.registered()
+	names := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		names = append(names, job.Name)
+	}
+
+	var states []JobState
+	if err := r.db.WithContext(r.ctx).Where("name IN ?", names).Find(&states).Error; err != nil {
+		if r.ctx.Err() == nil {
+			r.logger.WithError(err).Warn("Failed to load background jobs")
+		}
+		return
+	}
+
+	now := time.Now()
+	for _, state := range states {
+		job := r.job(state.Name)
+		if job == nil || r.isActive(job.Name) {
+			continue
+		}
+		due := state.TriggerRequested || (!state.Paused && !state.NextRunAt.After(now))
+		if !due || (state.LockedUntil != nil && state.LockedUntil.After(now)) {
+			continue
+		}
+
+		result := r.db.WithContext(r.ctx).Model(&JobState{}).
+			Where("name = ? AND (locked_until IS NULL OR locked_until < ?)", job.Name, now).
+			Where("trigger_requested OR (NOT paused AND next_run_at <= ?)", now).
+			Updates(map[string]interface{}{
+				"locked_by":         r.instance,
+				"locked_until":      now.Add(lease(job)),
+				"trigger_requested": false,
+				"triggered_by":      nil,
+				"pending_trigger":   "",
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			continue // Another replica claimed it first
+		}
+
+		trigger, triggeredBy := RunTriggerSchedule, (*string)(nil)
+		if state.TriggerRequested {
+			trigger, triggeredBy = RunTriggerManual, state.TriggeredBy
+			if state.PendingTrigger != "" {
+				trigger = state.PendingTrigger
+			}
+		}
+
+		r.setActive(job.Name, true)
+		r.wg.Add(1)
+		go r.execute(job, trigger, triggeredBy)
+	}
+
+	if time.Since(r.lastPrune) >= pruneInterval {
+		r.lastPrune = time.Now()
+		r.prune()
+	}
+}
+
+// execute runs a claimed job, retrying failed attempts with backoff, then releases the lease
+func (r *Runner) execute(job *Job, trigger RunTrigger, triggeredBy *string) {
+	defer r.wg.Done()
+	defer r.setActive(job.Name, false)
+
+	logger := r.logger.WithField("job", job.Name)
+	start := time.Now()
+
+	var status RunStatus
+	var err error
+	for attempt := 1; attempt <= job.MaxAttempts; attempt++ {
+		status, err = r.attempt(job, attempt, trigger, triggeredBy)
+		if status != RunStatusFailed || r.ctx.Err() != nil {
+			break
+		}
+
+		backoff := job.RetryBackoff << (attempt - 1)
+		logger.WithError(err).Warnf("Attempt %d/%d failed, retrying in %s", attempt, job.MaxAttempts, backoff)
+		select {
+		case <-r.ctx.Done():
+		case <-time.After(backoff):
+		}
+		if r.ctx.Err() != nil {
+			break
+		}
+	}
+
+	finished := time.Now()
+	next := finished.Add(job.Interval)
+	switch status {
+	case RunStatusSucceeded:
+		logger.Infof("Completed in %s", finished.Sub(start).Round(time.Millisecond))
+	case RunStatusDeadLetter:
+		logger.WithError(err).Errorf("Failed after %d attempts, moved to dead letters", job.MaxAttempts)
+	default:
+		// Interrupted by shutdown: let another replica pick it up straight away
+		next = finished
+		logger.WithError(err).Warn("Interrupted by shutdown, rescheduled")
+	}
+
+	var lastError *string
+	if err != nil {
+		message := err.Error()
+		lastError = &message
+	}
+	release := r.db.Model(&JobState{}).
+		Where("name = ? AND locked_by = ?", job.Name, r.instance).
+		Updates(map[string]interface{}{
+			"locked_by":        nil,
+			"locked_until":     nil,
+			"last_run_at":      start,
+			"last_status":      status,
+			"last_error":       lastError,
+			"last_duration_ms": finished.Sub(start).Milliseconds(),
+			"next_run_at":      next,
+		})
+	if release.Error != nil {
+		logger.WithError(release.Error).Warn("Failed to release job lease")
+	}
+}
+
+// attempt runs the job once and records the attempt and its metrics
+func (r *Runner) attempt(job *Job, attempt int, trigger RunTrigger, triggeredBy *string) (RunStatus, error) {
+	run := JobRun{
+		ID:          uuid.New(),
+		JobName:     job.Name,
+		Status:      RunStatusRunning,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Attempt:     attempt,
+		Instance:    r.instance,
+		StartedAt:   time.Now(),
+	}
+	if err := r.db.Create(&run).Error; err != nil {
+		r.logger.WithError(err).WithField("job", job.Name).Warn("Failed to record job run")
+	}
+
+	ctx, cancel := context.WithTimeout(r.ctx, job.Timeout)
+	err := safeRun(ctx, job)
+	cancel()
+
+	finished := time.Now()
+	durationMs := finished.Sub(run.StartedAt).Milliseconds()
+	status := RunStatusSucceeded
+	counter := "success_count"
+	if err != nil {
+		status, counter = RunStatusFailed, "failure_count"
+		if attempt == job.MaxAttempts && r.ctx.Err() == nil {
+			status, counter = RunStatusDeadLetter, "dead_letter_count"
+		}
+	}
+
+	var runError *string
+	if err != nil {
+		message := err.Error()
+		runError = &message
+	}
+	if err := r.db.Model(&JobRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+		"status":      status,
+		"error":       runError,
+		"finished_at": finished,
+		"duration_ms": durationMs,
+	}).Error; err != nil {
+		r.logger.WithError(err).WithField("job", job.Name).Warn("Failed to record job run outcome")
+	}
+	if err := r.db.Model(&JobState{}).Where("name = ?", job.Name).Updates(map[string]interface{}{
+		"run_count":         gorm.Expr("run_count + 1"),
+		counter:             gorm.Expr(counter + " + 1"),
+		"total_duration_ms": gorm.Expr("total_duration_ms + ?", durationMs),
+	}).Error; err != nil {
+		r.logger.WithError(err).WithField("job", job.Name).Warn("Failed to update job metrics")
+	}
+
+	return status, err
+}
+
+// safeRun runs the job, turning a panic into an error so one bad run can't take the service down
+func safeRun(ctx context.Context, job *Job) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// prune deletes run history past the retention period, keeping unresolved dead letters
+func (r *Runner) prune() {
+	cutoff := time.Now().Add(-RunRetention)
+	result := r.db.WithContext(r.ctx).
+		Where("started_at < ? AND (status <> ? OR resolved_at IS NOT NULL)", cutoff, RunStatusDeadLetter).
+		Delete(&JobRun{})
+	if result.Error != nil {
+		if r.ctx.Err() == nil {
+			r.logger.WithError(result.Error).Warn("Failed to prune job run history")
+		}
+		return
+	}
+	if result.RowsAffected > 0 {
+		r.logger.Infof("Pruned %d job runs older than %s", result.RowsAffected, RunRetention)
+	}
+}
+
+// lease is how long a claim holds: every attempt timing out plus every backoff, with a margin
+func lease(job *Job) time.Duration {
+	total := time.Duration(job.MaxAttempts)*job.Timeout + time.Minute
+	for attempt := 1; attempt < job.MaxAttempts; attempt++ {
+		total += job.RetryBackoff << (attempt - 1)
+	}
+	return total
+}
+
+// List returns every job with its metrics, registered jobs first
+func (r *Runner) List(ctx context.Context) ([]JobSummary, error) {
+	var states []JobState
+	if err := r.db.WithContext(ctx).Order("name").Find(&states).Error; err != nil {
+		return nil, err
+	}
+	summaries := make([]JobSummary, 0, len(states))
+	for _, state := range states {
+		summaries = append(summaries, r.summarize(state))
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Registered && !summaries[j].Registered
+	})
+	return summaries, nil
+}
+
+// Get returns a registered job with its most recent runs
+func (r *Runner) Get(ctx context.Context, name string, runs int) (*JobDetail, error) {
+	state, err := r.state(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	detail := &JobDetail{JobSummary: r.summarize(*state), RecentRuns: []JobRun{}}
+	if err := r.db.WithContext(ctx).
+		Where("job_name = ?", name).
+		Order("started_at DESC").
+		Limit(runs).
+		Find(&detail.RecentRuns).Error; err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// SetPaused pauses or resumes the schedule of a job. A paused job can still be triggered.
+func (r *Runner) SetPaused(ctx context.Context, name string, paused bool, actor string) (*JobSummary, error) {
+	if _, err := r.state(ctx, name); err != nil {
+		return nil, err
+	}
+	updates := map[string]interface{}{"paused": paused, "paused_by": nil, "paused_at": nil}
+	if paused {
+		updates["paused_by"] = actor
+		updates["paused_at"] = time.Now()
+	}
+	return r.update(ctx, name, updates)
+}
+
+// Trigger asks for a run of the job at the next poll of any replica
+func (r *Runner) Trigger(ctx context.Context, name, actor string) (*JobSummary, error) {
+	if _, err := r.state(ctx, name); err != nil {
+		return nil, err
+	}
+	return r.update(ctx, name, map[string]interface{}{
+		"trigger_requested": true,
+		"triggered_by":      actor,
+		"pending_trigger":   RunTriggerManual,
+	})
+}
+
+// DeadLetters lists dead-lettered runs, newest first
+func (r *Runner) DeadLetters(ctx context.Context, includeResolved bool, limit int) ([]JobRun, error) {
+	query := r.db.WithContext(ctx).Where("status = ?", RunStatusDeadLetter)
+	if !includeResolved {
+		query = query.Where("resolved_at IS NULL")
+	}
+	runs := []JobRun{}
+	err := query.Order("started_at DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+// RetryDeadLetter resolves a dead letter and triggers a fresh run of its job
+func (r *Runner) RetryDeadLetter(ctx context.Context, id uuid.UUID, actor string) (*JobRun, error) {
+	run, err := r.resolveDeadLetter(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.update(ctx, run.JobName, map[string]interface{}{
+		"trigger_requested": true,
+		"triggered_by":      actor,
+		"pending_trigger":   RunTriggerRetry,
+	}); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// DismissDeadLetter resolves a dead letter without running the job again
+func (r *Runner) DismissDeadLetter(ctx context.Context, id uuid.UUID, actor string) (*JobRun, error) {
+	return r.resolveDeadLetter(ctx, id, actor)
+}
+
+func (r *Runner) resolveDeadLetter(ctx context.Context, id uuid.UUID, actor string) (*JobRun, error) {
+	var run JobRun
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&run).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRunNotFound
+		}
+		return nil, err
+	}
+	if r.job(run.JobName) == nil {
+		return nil, ErrJobNotFound
+	}
+
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&JobRun{}).
+		Where("id = ? AND status = ? AND resolved_at IS NULL", id, RunStatusDeadLetter).
+		Updates(map[string]interface{}{"resolved_at": now, "resolved_by": actor})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotDeadLetter
+	}
+	run.ResolvedAt = &now
+	run.ResolvedBy = &actor
+	return &run, nil
+}
+
+func (r *Runner) update(ctx context.Context, name string, updates map[string]interface{}) (*JobSummary, error) {
+	if err := r.db.WithContext(ctx).Model(&JobState{}).Where("name = ?", name).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	state, err := r.state(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	summary := r.summarize(*state)
+	return &summary, nil
+}
+
+// state loads the persisted state of a registered job
+func (r *Runner) state(ctx context.Context, name string) (*JobState, error) {
+	if r.job(name) == nil {
+		return nil, ErrJobNotFound
+	}
+	var state JobState
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&state).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *Runner) summarize(state JobState) JobSummary {
+	summary := JobSummary{
+		JobState:   state,
+		Registered: r.job(state.Name) != nil,
+		Running:    state.LockedUntil != nil && state.LockedUntil.After(time.Now()),
+	}
+	if state.RunCount > 0 {
+		summary.AverageDurationMs = state.TotalDurationMs / state.RunCount
+		summary.SuccessRate = float64(state.SuccessCount) / float64(state.RunCount)
+	}
+	return summary
+}
+
+func (r *Runner) registered() []*Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	jobs := make([]*Job, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+func (r *Runner) job(name string) *Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.jobs[name]
+}
+
+func (r *Runner) isActive(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.active[name]
+}
+
+func (r *Runner) setActive(name string, active bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.active[name] = active
+}
@@ -0,0 +1,272 @@
+// Package loadseed generates load-test data. Each service seeds its own tables with
+// "<service> seed"; tenants, products and customers are derived from their tenant and index
+// alone, so the carts and orders seeded by one service reference the products and customers
+// seeded by another without the services calling each other. Pass every service the same
+// flags to get a consistent dataset.
+//
+// It is kept identical in every service that uses it; change all copies together.
+package loadseed
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrProduction is returned when seeding is attempted in production
+var ErrProduction = errors.New("load-test seeding is disabled in production")
+
+// namespace makes seeded IDs stable across services and runs
+var namespace = uuid.MustParse("6f1c2a0e-4b7d-4e55-9a51-3c8f0d2b7a10")
+
+// Options controls the volume and shape of the seeded data. Per-tenant counts are averages;
+// with a tenant skew the first tenants get more and the last fewer.
+type Options struct {
+	Prefix     string // Tenant IDs are <prefix>-001, <prefix>-002 ...
+	Tenants    int
+	Products   int
+	Categories int
+	Customers  int
+	Carts      int // Customers with an open cart, at most one each
+	Orders     int
+
+	TenantSkew  float64 // 0 sizes tenants equally; higher values concentrate data in the first tenants
+	ProductSkew float64 // 0 picks products uniformly; higher values favour a few best sellers
+	MaxItems    int     // Lines per cart and order, 1 to MaxItems
+	Days        int     // Orders are spread over the last Days days
+
+	OrderStatuses Weights // e.g. DELIVERED=55,SHIPPED=10,...
+
+	BatchSize int
+	Seed      int64 // Varies carts and orders between runs; products and customers don't depend on it
+}
+
+// DefaultOrderStatuses is a mature store's mix: most orders delivered, some in flight
+const DefaultOrderStatuses = "DELIVERED=55,SHIPPED=10,CONFIRMED=12,PLACED=8,CANCELLED=10,REFUNDED=5"
+
+// Parse reads the seed flags for service from args
+func Parse(service string, args []string) (*Options, error) {
+	opts := &Options{}
+	var statuses string
+	fs := flag.NewFlagSet(service+" seed", flag.ContinueOnError)
+	fs.StringVar(&opts.Prefix, "prefix", "loadtest", "tenant ID prefix")
+	fs.IntVar(&opts.Tenants, "tenants", 10, "number of tenants")
+	fs.IntVar(&opts.Products, "products", 1000, "products per tenant (average)")
+	fs.IntVar(&opts.Categories, "categories", 25, "product categories per tenant")
+	fs.IntVar(&opts.Customers, "customers", 2000, "customers per tenant (average)")
+	fs.IntVar(&opts.Carts, "carts", 300, "open carts per tenant (average)")
+	fs.IntVar(&opts.Orders, "orders", 5000, "orders per tenant (average)")
+	fs.Float64Var(&opts.TenantSkew, "tenant-skew", 1, "how unevenly data is spread over tenants (0 = evenly)")
+	fs.Float64Var(&opts.ProductSkew, "product-skew", 1.5, "how strongly carts and orders favour popular products (0 = uniformly)")
+	fs.IntVar(&opts.MaxItems, "max-items", 5, "maximum lines per cart and order")
+	fs.IntVar(&opts.Days, "days", 365, "days of order history")
+	fs.StringVar(&statuses, "order-statuses", DefaultOrderStatuses, "order status mix as STATUS=weight pairs")
+	fs.IntVar(&opts.BatchSize, "batch-size", 500, "rows per insert")
+	fs.Int64Var(&opts.Seed, "seed", 1, "random seed for carts and orders")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	weights, err := ParseWeights(statuses)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -order-statuses: %w", err)
+	}
+	opts.OrderStatuses = weights
+
+	if opts.Tenants < 1 || opts.Categories < 1 || opts.MaxItems < 1 || opts.Days < 1 || opts.BatchSize < 1 {
+		return nil, errors.New("-tenants, -categories, -max-items, -days and -batch-size must be at least 1")
+	}
+	if opts.Products < 0 || opts.Customers < 0 || opts.Carts < 0 || opts.Orders < 0 || opts.TenantSkew < 0 || opts.ProductSkew < 0 {
+		return nil, errors.New("counts and skews can't be negative")
+	}
+	return opts, nil
+}
+
+// CheckEnvironment refuses to seed in production. Seeded rows live alongside real tenants'
+// data, so they must never be written there.
+func CheckEnvironment(environment string) error {
+	switch strings.ToLower(environment) {
+	case "production", "prod":
+		return ErrProduction
+	}
+	return nil
+}
+
+// TenantIDs returns the seeded tenants, largest first
+func (o *Options) TenantIDs() []string {
+	ids := make([]string, o.Tenants)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%s-%03d", o.Prefix, i+1)
+	}
+	return ids
+}
+
+// Count scales a per-tenant average for the tenant at index: its share follows a power law
+// 1/(index+1)^TenantSkew, normalized so the tenants add up to average*Tenants
+func (o *Options) Count(tenantIndex, average int) int {
+	if o.TenantSkew == 0 {
+		return average
+	}
+	total := 0.0
+	for i := 0; i < o.Tenants; i++ {
+		total += math.Pow(float64(i+1), -o.TenantSkew)
+	}
+	share := math.Pow(float64(tenantIndex+1), -o.TenantSkew) / total
+	return int(math.Round(share * float64(average*o.Tenants)))
+}
+
+// Rand returns the random source for one kind of record of a tenant, so tenants can be
+// seeded in any order with the same result
+func (o *Options) Rand(tenantID, kind string) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(o.Seed), hash(tenantID+"/"+kind)))
+}
+
+// Pick returns an index below n, skewed towards 0 by ProductSkew
+func (o *Options) Pick(r *rand.Rand, n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return min(int(float64(n)*math.Pow(r.Float64(), 1+o.ProductSkew)), n-1)
+}
+
+// OrderTime returns a creation time within the last Days days, busier towards the present as
+// a growing store would be
+func (o *Options) OrderTime(r *rand.Rand, now time.Time) time.Time {
+	age := time.Duration(math.Pow(r.Float64(), 1.5) * float64(time.Duration(o.Days)*24*time.Hour))
+	return now.Add(-age).Truncate(time.Second)
+}
+
+// ID returns the stable ID of the index-th record of a kind ("product", "customer" ...) for a tenant
+func ID(kind, tenantID string, index int) uuid.UUID {
+	return uuid.NewSHA1(namespace, []byte(kind+"/"+tenantID+"/"+strconv.Itoa(index)))
+}
+
+// Product describes a seeded product; every service derives the same values
+type Product struct {
+	ID         uuid.UUID
+	Name       string
+	SKU        string
+	Price      float64
+	CategoryID uuid.UUID
+}
+
+var (
+	adjectives = []string{"Classic", "Organic", "Premium", "Everyday", "Compact", "Deluxe", "Vintage", "Eco", "Travel", "Studio"}
+	nouns      = []string{"Mug", "Backpack", "Lamp", "T-Shirt", "Notebook", "Headphones", "Candle", "Water Bottle", "Sneakers", "Blanket", "Tea Set", "Phone Case"}
+	firstNames = []string{"Olivia", "Liam", "Aarav", "Mia", "Noah", "Priya", "Lucas", "Sofia", "Ethan", "Zara", "Mateo", "Chloe", "Arjun", "Ava", "Leo", "Isla"}
+	lastNames  = []string{"Smith", "Patel", "Nguyen", "Garcia", "Brown", "Kumar", "Wilson", "Chen", "Taylor", "Singh", "Martin", "Lee", "Walker", "Khan"}
+)
+
+// ProductAt returns the index-th product of a tenant. Prices are log-normal around 30 with a
+// long tail, ending in .99.
+func (o *Options) ProductAt(tenantID string, index int) Product {
+	r := rand.New(rand.NewPCG(hash(tenantID), uint64(index)))
+	price := math.Floor(math.Exp(3.4+0.9*r.NormFloat64())) + 0.99
+	return Product{
+		ID:         ID("product", tenantID, index),
+		Name:       fmt.Sprintf("%s %s %d", adjectives[r.IntN(len(adjectives))], nouns[r.IntN(len(nouns))], index+1),
+		SKU:        fmt.Sprintf("LT-%06d", index+1),
+		Price:      math.Min(price, 4999.99),
+		CategoryID: ID("category", tenantID, index%o.Categories),
+	}
+}
+
+// Customer describes a seeded customer; every service derives the same values
+type Customer struct {
+	ID        uuid.UUID
+	FirstName string
+	LastName  string
+	Email     string
+	Address   Address
+}
+
+// Address is a seeded shipping address
+type Address struct {
+	Street      string
+	City        string
+	State       string
+	StateCode   string
+	PostalCode  string
+	Country     string
+	CountryCode string
+}
+
+var addresses = []Address{
+	{City: "Austin", State: "Texas", StateCode: "TX", Country: "United States", CountryCode: "US"},
+	{City: "Seattle", State: "Washington", StateCode: "WA", Country: "United States", CountryCode: "US"},
+	{City: "Sydney", State: "New South Wales", StateCode: "NSW", Country: "Australia", CountryCode: "AU"},
+	{City: "Melbourne", State: "Victoria", StateCode: "VIC", Country: "Australia", CountryCode: "AU"},
+	{City: "Mumbai", State: "Maharashtra", StateCode: "MH", Country: "India", CountryCode: "IN"},
+	{City: "Bengaluru", State: "Karnataka", StateCode: "KA", Country: "India", CountryCode: "IN"},
+	{City: "London", State: "England", StateCode: "ENG", Country: "United Kingdom", CountryCode: "GB"},
+}
+
+// CustomerAt returns the index-th customer of a tenant
+func (o *Options) CustomerAt(tenantID string, index int) Customer {
+	r := rand.New(rand.NewPCG(hash(tenantID), uint64(index)|1<<62))
+	address := addresses[r.IntN(len(addresses))]
+	address.Street = fmt.Sprintf("%d %s Street", 1+r.IntN(999), lastNames[r.IntN(len(lastNames))])
+	address.PostalCode = fmt.Sprintf("%05d", r.IntN(100000))
+	return Customer{
+		ID:        ID("customer", tenantID, index),
+		FirstName: firstNames[r.IntN(len(firstNames))],
+		LastName:  lastNames[r.IntN(len(lastNames))],
+		Email:     fmt.Sprintf("customer%06d@%s.example.com", index+1, tenantID),
+		Address:   address,
+	}
+}
+
+// Weights is a weighted choice between values
+type Weights struct {
+	values     []string
+	cumulative []float64
+}
+
+// ParseWeights parses "A=3,B=1" into a choice that picks A three times as often as B
+func ParseWeights(s string) (Weights, error) {
+	var w Weights
+	pairs := strings.Split(s, ",")
+	sort.Strings(pairs) // Same picks whatever order the pairs were given in
+	total := 0.0
+	for _, pair := range pairs {
+		value, weightStr, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		weight, err := strconv.ParseFloat(weightStr, 64)
+		if !ok || value == "" || err != nil || weight < 0 {
+			return w, fmt.Errorf("%q is not VALUE=weight", pair)
+		}
+		total += weight
+		w.values = append(w.values, strings.ToUpper(value))
+		w.cumulative = append(w.cumulative, total)
+	}
+	if total == 0 {
+		return w, errors.New("weights add up to 0")
+	}
+	return w, nil
+}
+
+// Pick returns a value with probability proportional to its weight
+func (w Weights) Pick(r *rand.Rand) string {
+	x := r.Float64() * w.cumulative[len(w.cumulative)-1]
+	i := sort.SearchFloat64s(w.cumulative, x)
+	return w.values[min(i, len(w.values)-1)]
+}
+
+// Values returns the values in the order they are picked from
+func (w Weights) Values() []string {
+	return w.values
+}
+
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
@@ -0,0 +1,244 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+
+	"customers-service/internal/serviceauth"
+)
+
+// APIKeyHeader carries a tenant API key on server-to-server requests
+const APIKeyHeader = "X-API-Key"
+
+const (
+	apiKeyValidTTL     = 30 * time.Second // Revocations take effect within this
+	apiKeyInvalidTTL   = 10 * time.Second
+	apiKeyCacheMax     = 10000
+	apiKeyRatePeriod   = time.Minute
+	apiKeyCheckTimeout = 5 * time.Second
+)
+
+// apiKeyIdentity is staff-service's validation of an API key
+type apiKeyIdentity struct {
+	KeyID              string   `json:"keyId"`
+	TenantID           string   `json:"tenantId"`
+	VendorID           *string  `json:"vendorId,omitempty"`
+	Name               string   `json:"name"`
+	Permissions        []string `json:"permissions"`
+	RateLimitPerMinute int      `json:"rateLimitPerMinute"`
+}
+
+type apiKeyCacheEntry struct {
+	identity  *apiKeyIdentity // nil for invalid keys
+	expiresAt time.Time
+}
+
+type apiKeyRateWindow struct {
+	start time.Time
+	count int
+}
+
+type apiKeyAuthenticator struct {
+	validateURL string
+	httpClient  *http.Client
+
+	mu      sync.Mutex
+	cache   map[string]apiKeyCacheEntry
+	windows map[string]*apiKeyRateWindow
+}
+
+// APIKeyAuth authenticates requests carrying a tenant API key issued by staff-service and
+// passes every other request to next (normally IstioAuth). Key requests are attributed to
+// the key's ID, which staff-service resolves to the key's scoped permissions, so RBAC
+// checks apply unchanged. Each key is rate limited per service instance.
+func APIKeyAuth(staffServiceURL string, next gin.HandlerFunc) gin.HandlerFunc {
+	a := &apiKeyAuthenticator{
+		validateURL: strings.TrimSuffix(staffServiceURL, "/") + "/api/v1/internal/api-keys/validate",
+		httpClient:  &http.Client{Timeout: apiKeyCheckTimeout},
+		cache:       make(map[string]apiKeyCacheEntry),
+		windows:     make(map[string]*apiKeyRateWindow),
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			next(c)
+			return
+		}
+
+		identity, err := a.identify(key)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "service_unavailable",
+				"message": "Unable to validate API key",
+			})
+			return
+		}
+		if identity == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "unauthorized",
+				"message": "Invalid, expired or revoked API key",
+			})
+			return
+		}
+
+		remaining, retryAfter := a.allow(identity)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(identity.RateLimitPerMinute))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if retryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "rate_limited",
+				"message": fmt.Sprintf("API key rate limit of %d requests per minute exceeded", identity.RateLimitPerMinute),
+			})
+			return
+		}
+
+		setAPIKeyContext(c, identity)
+		c.Next()
+	}
+}
+
+// setAPIKeyContext sets the context values IstioAuth sets for users, with the key's ID
+// standing in for the staff ID. Identity headers a caller could spoof are dropped.
+func setAPIKeyContext(c *gin.Context, identity *apiKeyIdentity) {
+	for name := range c.Request.Header {
+		if strings.HasPrefix(strings.ToLower(name), "x-jwt-claim-") {
+			c.Request.Header.Del(name)
+		}
+	}
+	c.Request.Header.Del("X-Internal-Service")
+	c.Request.Header.Del("X-Vendor-ID")
+	c.Request.Header.Set("X-Tenant-ID", identity.TenantID)
+
+	username := "api-key:" + identity.Name
+	authCtx := &gosharedmw.AuthContext{
+		UserID:   identity.KeyID,
+		StaffID:  identity.KeyID,
+		TenantID: identity.TenantID,
+		Username: username,
+		Roles:    []string{},
+	}
+	if identity.VendorID != nil {
+		authCtx.VendorID = *identity.VendorID
+		c.Set("vendor_id", authCtx.VendorID)
+		c.Set("vendorId", authCtx.VendorID)
+	}
+
+	c.Set(gosharedmw.AuthContextKey, authCtx)
+	c.Set("user_id", identity.KeyID)
+	c.Set("userId", identity.KeyID)
+	c.Set("staff_id", identity.KeyID)
+	c.Set("staffId", identity.KeyID)
+	c.Set("tenant_id", identity.TenantID)
+	c.Set("tenantId", identity.TenantID)
+	c.Set("username", username)
+	c.Set("userName", username)
+	c.Set("roles", authCtx.Roles)
+	c.Set("api_key_id", identity.KeyID)
+	c.Set("auth_method", "api_key")
+}
+
+// identify returns the key's identity, nil for an invalid key, or an error if staff-service
+// couldn't be asked
+func (a *apiKeyAuthenticator) identify(key string) (*apiKeyIdentity, error) {
+	sum := sha256.Sum256([]byte(key))
+	cacheKey := hex.EncodeToString(sum[:])
+
+	a.mu.Lock()
+	entry, ok := a.cache[cacheKey]
+	a.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.identity, nil
+	}
+
+	identity, err := a.validate(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := apiKeyValidTTL
+	if identity == nil {
+		ttl = apiKeyInvalidTTL
+	}
+	a.mu.Lock()
+	if len(a.cache) >= apiKeyCacheMax {
+		a.cache = make(map[string]apiKeyCacheEntry)
+	}
+	a.cache[cacheKey] = apiKeyCacheEntry{identity: identity, expiresAt: time.Now().Add(ttl)}
+	a.mu.Unlock()
+	return identity, nil
+}
+
+func (a *apiKeyAuthenticator) validate(key string) (*apiKeyIdentity, error) {
+	body, err := json.Marshal(map[string]string{"key": key})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, a.validateURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	serviceauth.Sign(req, "customers-service")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Internal-Service", "customers-service")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call staff-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusBadRequest {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("staff-service returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success bool            `json:"success"`
+		Data    *apiKeyIdentity `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode API key validation: %w", err)
+	}
+	if !result.Success || result.Data == nil {
+		return nil, nil
+	}
+	return result.Data, nil
+}
+
+// allow counts the request against the key's fixed one-minute window. It returns the
+// requests remaining, or how long until the window resets when the limit is reached.
+func (a *apiKeyAuthenticator) allow(identity *apiKeyIdentity) (int, time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	window, ok := a.windows[identity.KeyID]
+	if !ok || now.Sub(window.start) >= apiKeyRatePeriod {
+		if len(a.windows) >= apiKeyCacheMax {
+			a.windows = make(map[string]*apiKeyRateWindow)
+		}
+		window = &apiKeyRateWindow{start: now}
+		a.windows[identity.KeyID] = window
+	}
+
+	if window.count >= identity.RateLimitPerMinute {
+		return 0, window.start.Add(apiKeyRatePeriod).Sub(now)
+	}
+	window.count++
+	return identity.RateLimitPerMinute - window.count, 0
+}
@@ -79,7 +79,7 @@ func CustomerAuthMiddleware(db *gorm.DB) gin.HandlerFunc {
 				}
 				if err := db.Table("customers").
 					Select("id, tenant_id").
-					Where("tenant_id = ? AND LOWER(email) = LOWER(?)", tenantID, email).
+					Where("tenant_id = ? AND LOWER(email) = LOWER(?) AND deleted_at IS NULL", tenantID, email).
 					First(&customer).Error; err == nil {
 					resolvedID = customer.ID
 					tenantID = customer.TenantID
@@ -96,7 +96,7 @@ func CustomerAuthMiddleware(db *gorm.DB) gin.HandlerFunc {
 				}
 				if err := db.Table("customers").
 					Select("id, tenant_id").
-					Where("id = ?", userID).
+					Where("id = ? AND deleted_at IS NULL", userID).
 					First(&customer).Error; err == nil {
 					resolvedID = customer.ID
 					tenantID = customer.TenantID
@@ -195,7 +195,7 @@ func CustomerAuthMiddleware(db *gorm.DB) gin.HandlerFunc {
 			}
 			if err := db.Table("customers").
 				Select("id").
-				Where("tenant_id = ? AND LOWER(email) = LOWER(?)", tenantID, email).
+				Where("tenant_id = ? AND LOWER(email) = LOWER(?) AND deleted_at IS NULL", tenantID, email).
 				First(&customer).Error; err == nil {
 				customerID = customer.ID
 				log.Printf("[CustomerAuth] Resolved keycloak sub → customer ID %s (tenant: %s)",
@@ -0,0 +1,285 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keys written by staff-service's rate limit admin API (/api/v1/rate-limits)
+const (
+	rateLimitSettingsKey    = "ratelimit:config:settings"
+	rateLimitTenantsKey     = "ratelimit:config:tenants"
+	rateLimitVersionKey     = "ratelimit:config:version"
+	rateLimitReloadInterval = 10 * time.Second
+)
+
+type rateLimitProfile struct {
+	RequestsPerWindow int `json:"requestsPerWindow"`
+	WindowSeconds     int `json:"windowSeconds"`
+}
+
+type rateLimitEndpointGroup struct {
+	Name         string   `json:"name"`
+	Methods      []string `json:"methods"`
+	PathPrefixes []string `json:"pathPrefixes"`
+	PathContains []string `json:"pathContains"`
+	Profile      string   `json:"profile"`
+}
+
+func (g *rateLimitEndpointGroup) matches(method, path string) bool {
+	if len(g.Methods) > 0 {
+		found := false
+		for _, m := range g.Methods {
+			if strings.EqualFold(m, method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, prefix := range g.PathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	for _, part := range g.PathContains {
+		if strings.Contains(path, part) {
+			return true
+		}
+	}
+	return false
+}
+
+type rateLimitSettings struct {
+	DefaultProfile string                      `json:"defaultProfile"`
+	Profiles       map[string]rateLimitProfile `json:"profiles"`
+	EndpointGroups []rateLimitEndpointGroup    `json:"endpointGroups"`
+}
+
+type tenantRateLimitOverride struct {
+	Multiplier float64                     `json:"multiplier"`
+	Profiles   map[string]rateLimitProfile `json:"profiles"`
+}
+
+// standardRateLimit is go-shared's "standard" profile, used when a profile is missing
+var standardRateLimit = rateLimitProfile{RequestsPerWindow: 100, WindowSeconds: 1}
+
+// defaultRateLimitSettings match staff-service's defaults and apply until profiles are saved
+func defaultRateLimitSettings() *rateLimitSettings {
+	return &rateLimitSettings{
+		DefaultProfile: "standard",
+		Profiles: map[string]rateLimitProfile{
+			"standard":   standardRateLimit,
+			"export":     {RequestsPerWindow: 10, WindowSeconds: 60},
+			"storefront": {RequestsPerWindow: 500, WindowSeconds: 1},
+		},
+		EndpointGroups: []rateLimitEndpointGroup{
+			{Name: "exports", PathContains: []string{"/export"}, Profile: "export"},
+			{Name: "storefront_reads", Methods: []string{"GET"}, PathContains: []string{"/storefront/", "/public/"}, Profile: "storefront"},
+		},
+	}
+}
+
+// ProfileRateLimiter rate limits requests per tenant and client IP using the profile of the
+// endpoint group they match, raised or lowered by the tenant's override. Profiles are managed
+// through staff-service and reloaded from Redis when they change. Counting uses go-shared's
+// Redis sliding window, and fails open when Redis errors.
+type ProfileRateLimiter struct {
+	client *redis.Client
+
+	mu       sync.RWMutex
+	settings *rateLimitSettings
+	tenants  map[string]tenantRateLimitOverride
+	version  int64
+}
+
+// NewProfileRateLimiter creates a rate limiter and loads the current profiles
+func NewProfileRateLimiter(client *redis.Client) *ProfileRateLimiter {
+	l := &ProfileRateLimiter{
+		client:   client,
+		settings: defaultRateLimitSettings(),
+		tenants:  map[string]tenantRateLimitOverride{},
+		version:  -1,
+	}
+	l.reload(context.Background())
+	return l
+}
+
+// Start reloads the profiles whenever they change, until ctx is done
+func (l *ProfileRateLimiter) Start(ctx context.Context) {
+	ticker := time.NewTicker(rateLimitReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.reload(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reload reads the profiles and tenant overrides if their version changed. On errors the
+// current profiles are kept.
+func (l *ProfileRateLimiter) reload(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	version, err := l.client.Get(ctx, rateLimitVersionKey).Int64()
+	if err != nil && err != redis.Nil {
+		return
+	}
+	l.mu.RLock()
+	unchanged := version == l.version
+	l.mu.RUnlock()
+	if unchanged {
+		return
+	}
+
+	settings := defaultRateLimitSettings()
+	data, err := l.client.Get(ctx, rateLimitSettingsKey).Bytes()
+	if err != nil && err != redis.Nil {
+		return
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, settings); err != nil {
+			return
+		}
+	}
+
+	values, err := l.client.HGetAll(ctx, rateLimitTenantsKey).Result()
+	if err != nil {
+		return
+	}
+	tenants := make(map[string]tenantRateLimitOverride, len(values))
+	for tenantID, value := range values {
+		var override tenantRateLimitOverride
+		if err := json.Unmarshal([]byte(value), &override); err == nil {
+			tenants[tenantID] = override
+		}
+	}
+
+	l.mu.Lock()
+	l.settings = settings
+	l.tenants = tenants
+	l.version = version
+	l.mu.Unlock()
+}
+
+// limitFor returns the endpoint group and profile that apply to the request
+func (l *ProfileRateLimiter) limitFor(method, path, tenantID string) (string, rateLimitProfile) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	group, profileName := "default", l.settings.DefaultProfile
+	for i := range l.settings.EndpointGroups {
+		if l.settings.EndpointGroups[i].matches(method, path) {
+			group, profileName = l.settings.EndpointGroups[i].Name, l.settings.EndpointGroups[i].Profile
+			break
+		}
+	}
+
+	profile, ok := l.settings.Profiles[profileName]
+	if !ok {
+		profile = standardRateLimit
+	}
+	if override, ok := l.tenants[tenantID]; ok && tenantID != "" {
+		if p, ok := override.Profiles[profileName]; ok {
+			profile = p
+		} else if override.Multiplier > 0 {
+			profile.RequestsPerWindow = int(math.Ceil(float64(profile.RequestsPerWindow) * override.Multiplier))
+		}
+	}
+	if profile.RequestsPerWindow <= 0 || profile.WindowSeconds <= 0 {
+		profile = standardRateLimit
+	}
+	return group, profile
+}
+
+// Middleware returns the rate limiting middleware
+func (l *ProfileRateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if strings.HasPrefix(path, "/health") || strings.HasPrefix(path, "/ready") || strings.HasPrefix(path, "/metrics") {
+			c.Next()
+			return
+		}
+
+		tenantID := c.GetHeader("X-Tenant-ID")
+		if tenantID == "" {
+			tenantID = c.GetHeader("X-Vendor-ID")
+		}
+		group, profile := l.limitFor(c.Request.Method, path, tenantID)
+
+		identifier := "ip:" + rateLimitClientIP(c)
+		if tenantID != "" {
+			identifier = "t:" + tenantID + ":" + identifier
+		}
+		limiter := gosharedmw.NewRedisRateLimiter(l.client, gosharedmw.RedisRateLimitConfig{
+			RequestsPerSecond: profile.RequestsPerWindow,
+			WindowDuration:    time.Duration(profile.WindowSeconds) * time.Second,
+			KeyPrefix:         "ratelimit:" + group + ":",
+		})
+		result, err := limiter.Check(c.Request.Context(), identifier)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+		if !result.Allowed {
+			retryAfter := int(result.RetryAfter.Seconds()) + 1
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"error": gin.H{
+					"code":    "RATE_LIMIT_EXCEEDED",
+					"message": "Too many requests. Please try again later.",
+					"details": gin.H{
+						"retry_after_seconds": retryAfter,
+						"limit":               result.Limit,
+						"endpoint_group":      group,
+					},
+				},
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitClientIP returns the client IP, preferring the headers set by the gateway
+func rateLimitClientIP(c *gin.Context) string {
+	if ip := c.GetHeader("X-Real-Client-IP"); ip != "" {
+		if strings.Count(ip, ":") == 1 {
+			ip = ip[:strings.LastIndex(ip, ":")]
+		}
+		return strings.TrimSpace(ip)
+	}
+	if xff := c.GetHeader("X-Forwarded-For"); xff != "" {
+		if ip := strings.TrimSpace(strings.Split(xff, ",")[0]); ip != "" {
+			return ip
+		}
+	}
+	if ip := c.GetHeader("X-Real-IP"); ip != "" {
+		return strings.TrimSpace(ip)
+	}
+	return c.ClientIP()
+}
@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"customers-service/internal/tenancy"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -25,6 +28,19 @@ func TenantMiddleware() gin.HandlerFunc {
 	}
 }
 
+// TenantScopeMiddleware copies the resolved tenant (and vendor scope, for vendor staff) onto the
+// request context so the tenancy GORM plugin scopes every statement made with c.Request.Context().
+// It must run after the middleware that resolves tenant_id; it can be applied again on groups
+// that re-resolve the tenant (e.g. CustomerAuthMiddleware).
+func TenantScopeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := tenancy.WithTenant(c.Request.Context(), c.GetString("tenant_id"))
+		ctx = tenancy.WithVendor(ctx, gosharedmw.GetVendorScopeFilter(c))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
 // UserMiddleware extracts X-User-ID header and sets it in context
 // This is required for the RBAC middleware to verify permissions
 func UserMiddleware() gin.HandlerFunc {
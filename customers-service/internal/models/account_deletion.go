@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// AccountDeletionStatus represents the stage of a customer's account deletion request
+type AccountDeletionStatus string
+
+const (
+	// AccountDeletionPendingVerification waits for the customer to confirm from the emailed link
+	AccountDeletionPendingVerification AccountDeletionStatus = "PENDING_VERIFICATION"
+	// AccountDeletionScheduled waits out the cooling-off period; the customer can still cancel
+	AccountDeletionScheduled AccountDeletionStatus = "SCHEDULED"
+	// AccountDeletionProcessing waits for every participating service to anonymize its data
+	AccountDeletionProcessing AccountDeletionStatus = "PROCESSING"
+	AccountDeletionCompleted  AccountDeletionStatus = "COMPLETED"
+	AccountDeletionCancelled  AccountDeletionStatus = "CANCELLED"
+)
+
+// AccountDeletionRequest tracks a storefront customer's request to delete their account.
+// The customer record is anonymized only after every service in PendingServices has
+// confirmed it anonymized its own copy of the customer's data.
+type AccountDeletionRequest struct {
+	ID         uuid.UUID             `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID   string                `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_account_deletion_tenant_customer"`
+	CustomerID uuid.UUID             `json:"customerId" gorm:"type:uuid;not null;index:idx_account_deletion_tenant_customer"`
+	Status     AccountDeletionStatus `json:"status" gorm:"type:varchar(30);not null;index:idx_account_deletion_status"`
+	Reason     string                `json:"reason,omitempty" gorm:"type:text"`
+
+	// Verification: only the SHA-256 hash of the emailed token is stored
+	VerificationTokenHash string     `json:"-" gorm:"type:varchar(64);index"`
+	VerificationExpiresAt *time.Time `json:"-"`
+	VerifiedAt            *time.Time `json:"verifiedAt,omitempty"`
+
+	// Cooling-off: anonymization starts once ScheduledFor has passed
+	ScheduledFor *time.Time `json:"scheduledFor,omitempty" gorm:"index:idx_account_deletion_status"`
+	CancelledAt  *time.Time `json:"cancelledAt,omitempty"`
+
+	// Orchestration: services still to confirm, and those that have
+	PendingServices     pq.StringArray `json:"pendingServices" gorm:"type:text[]"`
+	CompletedServices   pq.StringArray `json:"completedServices" gorm:"type:text[]"`
+	ProcessingStartedAt *time.Time     `json:"processingStartedAt,omitempty"`
+	LastPublishedAt     *time.Time     `json:"-"`
+	CompletedAt         *time.Time     `json:"completedAt,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName returns the table name for GORM
+func (AccountDeletionRequest) TableName() string {
+	return "account_deletion_requests"
+}
+
+// IsOpen reports whether the request can still be cancelled
+func (r *AccountDeletionRequest) IsOpen() bool {
+	return r.Status == AccountDeletionPendingVerification || r.Status == AccountDeletionScheduled
+}
@@ -38,7 +38,7 @@ type Customer struct {
 	Email      string         `json:"email" gorm:"type:varchar(255);not null;index:idx_customers_tenant_email,unique"`
 	FirstName  string         `json:"firstName" gorm:"type:varchar(100);not null"`
 	LastName   string         `json:"lastName" gorm:"type:varchar(100);not null"`
-	Phone      string         `json:"phone" gorm:"type:varchar(50)"`
+	Phone      string         `json:"phone" gorm:"type:text;serializer:encrypted"` // Encrypted with the tenant's data key
 	Status       CustomerStatus `json:"status" gorm:"type:varchar(20);default:'ACTIVE';index:idx_customers_tenant_status"`
 	CustomerType CustomerType   `json:"customerType" gorm:"type:varchar(20);default:'RETAIL'"`
 
@@ -79,9 +79,10 @@ type Customer struct {
 	VerificationTokenExpiresAt *time.Time `json:"-"`
 
 	// Metadata
-	CreatedAt time.Time      `json:"createdAt" gorm:"index:idx_customers_tenant_created,sort:desc"`
-	UpdatedAt time.Time      `json:"updatedAt"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	CreatedAt time.Time       `json:"createdAt" gorm:"index:idx_customers_tenant_created,sort:desc"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+	DeletedAt *gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"index"`
+	Version   int             `json:"version" gorm:"not null;default:1"` // Incremented on every update (optimistic locking)
 
 	// Relationships (not stored in DB, loaded via joins)
 	Addresses      []CustomerAddress      `json:"addresses,omitempty" gorm:"foreignKey:CustomerID;constraint:OnDelete:CASCADE"`
@@ -108,17 +109,17 @@ type CustomerAddress struct {
 	IsDefault   bool        `json:"isDefault" gorm:"default:false"`
 	Label       string      `json:"label" gorm:"type:varchar(50)"` // User-defined label (e.g., "Home", "Work")
 
-	// Address fields
-	FirstName    string `json:"firstName" gorm:"type:varchar(100)"`
-	LastName     string `json:"lastName" gorm:"type:varchar(100)"`
-	Company      string `json:"company" gorm:"type:varchar(255)"`
-	AddressLine1 string `json:"addressLine1" gorm:"type:varchar(255);not null"`
-	AddressLine2 string `json:"addressLine2" gorm:"type:varchar(255)"`
+	// Address fields (name, street, postal code and phone are encrypted with the tenant's data key)
+	FirstName    string `json:"firstName" gorm:"type:text;serializer:encrypted"`
+	LastName     string `json:"lastName" gorm:"type:text;serializer:encrypted"`
+	Company      string `json:"company" gorm:"type:text;serializer:encrypted"`
+	AddressLine1 string `json:"addressLine1" gorm:"type:text;not null;serializer:encrypted"`
+	AddressLine2 string `json:"addressLine2" gorm:"type:text;serializer:encrypted"`
 	City         string `json:"city" gorm:"type:varchar(100);not null"`
 	State        string `json:"state" gorm:"type:varchar(100)"`
-	PostalCode   string `json:"postalCode" gorm:"type:varchar(20);not null"`
+	PostalCode   string `json:"postalCode" gorm:"type:text;not null;serializer:encrypted"`
 	Country      string `json:"country" gorm:"type:varchar(2);not null"` // ISO 2-letter code
-	Phone        string `json:"phone" gorm:"type:varchar(50)"`
+	Phone        string `json:"phone" gorm:"type:text;serializer:encrypted"`
 
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
@@ -329,4 +330,8 @@ type CartItem struct {
 	AvailableStock  int            `json:"availableStock"`  // Current available stock
 	AddedAt         *time.Time     `json:"addedAt"`         // When item was added (for 90-day per-item expiration)
 	LastValidatedAt *time.Time     `json:"lastValidatedAt"` // When item was last validated
+
+	// Line-item customizations such as engraving text, a gift message or selected add-ons.
+	// Lines of the same product with different properties are kept apart.
+	Properties map[string]string `json:"properties,omitempty"`
 }
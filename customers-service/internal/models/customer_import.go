@@ -0,0 +1,163 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CustomerImportFormat is the file format of a customer import
+type CustomerImportFormat string
+
+const (
+	CustomerImportCSV  CustomerImportFormat = "csv"
+	CustomerImportXLSX CustomerImportFormat = "xlsx"
+)
+
+// DuplicateStrategy decides what happens to a row whose email already belongs to a customer
+type DuplicateStrategy string
+
+const (
+	// DuplicateSkip leaves the existing customer untouched
+	DuplicateSkip DuplicateStrategy = "skip"
+	// DuplicateMerge fills the existing customer's empty fields and adds the row's tags
+	DuplicateMerge DuplicateStrategy = "merge"
+	// DuplicateUpdate overwrites the existing customer with the row's non-empty fields
+	DuplicateUpdate DuplicateStrategy = "update"
+)
+
+// CustomerImportStatus is the stage of a customer import job
+type CustomerImportStatus string
+
+const (
+	CustomerImportPending    CustomerImportStatus = "PENDING"
+	CustomerImportProcessing CustomerImportStatus = "PROCESSING"
+	CustomerImportCompleted  CustomerImportStatus = "COMPLETED"
+	CustomerImportFailed     CustomerImportStatus = "FAILED"
+)
+
+// Customer import limits
+const (
+	MaxCustomerImportFileBytes = 20 << 20 // 20MB
+	MaxCustomerImportRows      = 100000
+	CustomerImportChunkSize    = 200
+	CustomerImportStaleAfter   = 5 * time.Minute
+)
+
+// CustomerImportJob tracks an asynchronous customer import. The uploaded file is kept with
+// the job until it completes, so an interrupted import resumes from NextRow.
+type CustomerImportJob struct {
+	ID                uuid.UUID            `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID          string               `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_customer_import_jobs_tenant"`
+	Status            CustomerImportStatus `json:"status" gorm:"type:varchar(20);not null;default:'PENDING';index"`
+	FileName          string               `json:"fileName" gorm:"type:varchar(255);not null"`
+	Format            CustomerImportFormat `json:"format" gorm:"type:varchar(10);not null"`
+	FileData          []byte               `json:"-" gorm:"type:bytea"`
+	ColumnMapping     JSONB                `json:"columnMapping,omitempty" gorm:"type:jsonb"` // Source header to import column
+	DuplicateStrategy DuplicateStrategy    `json:"duplicateStrategy" gorm:"type:varchar(10);not null;default:'skip'"`
+
+	// Applied to every created, merged or updated customer
+	SegmentIDs pq.StringArray `json:"segmentIds" gorm:"type:text[]"` // Static segments to add customers to
+	Tags       pq.StringArray `json:"tags" gorm:"type:text[]"`
+	// DefaultMarketingOptIn is used for new customers whose row leaves marketingOptIn blank.
+	// It is never applied to existing customers.
+	DefaultMarketingOptIn bool `json:"defaultMarketingOptIn" gorm:"default:false"`
+	MarkEmailVerified     bool `json:"markEmailVerified" gorm:"default:false"` // Emails were verified by the previous platform
+
+	// Progress
+	TotalRows    int `json:"totalRows"`
+	NextRow      int `json:"nextRow"` // Data rows already committed; processing resumes here
+	CreatedCount int `json:"createdCount"`
+	UpdatedCount int `json:"updatedCount"` // Merged or updated duplicates
+	SkippedCount int `json:"skippedCount"`
+	FailedCount  int `json:"failedCount"`
+
+	LastError   *string    `json:"lastError,omitempty" gorm:"type:text"`
+	Attempts    int        `json:"attempts"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	HeartbeatAt *time.Time `json:"heartbeatAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+
+	CreatedBy *string   `json:"createdBy,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName returns the table name for GORM
+func (CustomerImportJob) TableName() string {
+	return "customer_import_jobs"
+}
+
+// PercentComplete returns the job progress as a percentage
+func (j *CustomerImportJob) PercentComplete() int {
+	if j.TotalRows == 0 {
+		if j.Status == CustomerImportCompleted {
+			return 100
+		}
+		return 0
+	}
+	return j.NextRow * 100 / j.TotalRows
+}
+
+// CustomerImportError is a problem with one row of a customer import. Rows skipped as
+// duplicates are recorded too, with the DUPLICATE code.
+type CustomerImportError struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	JobID     uuid.UUID `json:"jobId" gorm:"type:uuid;not null;index"`
+	TenantID  string    `json:"-" gorm:"type:varchar(255);not null"`
+	Row       int       `json:"row" gorm:"column:row_number;not null"`
+	Email     string    `json:"email,omitempty" gorm:"type:varchar(255)"`
+	Column    string    `json:"column,omitempty" gorm:"column:column_name;type:varchar(100)"`
+	Code      string    `json:"code" gorm:"type:varchar(50);not null"`
+	Message   string    `json:"message" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName returns the table name for GORM
+func (CustomerImportError) TableName() string {
+	return "customer_import_errors"
+}
+
+// CustomerImportColumn describes one column of the customer import template
+type CustomerImportColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // string, email, date, boolean, list
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
+}
+
+// CustomerImportColumns lists the columns a customer import understands
+var CustomerImportColumns = []CustomerImportColumn{
+	{Name: "email", Type: "email", Required: true, Description: "Identifies the customer; duplicates are matched on it"},
+	{Name: "firstName", Type: "string", Required: true},
+	{Name: "lastName", Type: "string", Required: true},
+	{Name: "phone", Type: "string"},
+	{Name: "dateOfBirth", Type: "date", Description: "YYYY-MM-DD"},
+	{Name: "country", Type: "string", Description: "Full country name"},
+	{Name: "countryCode", Type: "string", Description: "ISO 3166-1 alpha-2 code"},
+	{Name: "customerType", Type: "string", Description: "RETAIL, WHOLESALE or VIP"},
+	{Name: "tags", Type: "list", Description: "Separated by | or ,"},
+	{Name: "notes", Type: "string"},
+	{Name: "marketingOptIn", Type: "boolean", Description: "Marketing consent; blank uses the import's default"},
+	{Name: "company", Type: "string"},
+	{Name: "addressLine1", Type: "string", Description: "Adds a default address to customers without one"},
+	{Name: "addressLine2", Type: "string"},
+	{Name: "city", Type: "string"},
+	{Name: "state", Type: "string"},
+	{Name: "postalCode", Type: "string"},
+	{Name: "addressCountry", Type: "string", Description: "ISO 3166-1 alpha-2 code; defaults to countryCode"},
+}
+
+// CustomerImportPreview shows how an uploaded file would be imported, without importing it
+type CustomerImportPreview struct {
+	SourceHeaders    []string              `json:"sourceHeaders"`
+	SuggestedMapping map[string]string     `json:"suggestedMapping"`
+	AppliedMapping   map[string]string     `json:"appliedMapping"`
+	UnmappedHeaders  []string              `json:"unmappedHeaders,omitempty"`
+	MissingRequired  []string              `json:"missingRequired,omitempty"`
+	TotalRows        int                   `json:"totalRows"`
+	ValidRowCount    int                   `json:"validRowCount"`
+	Rows             []map[string]string   `json:"rows"`
+	Errors           []CustomerImportError `json:"errors"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// LineItemPropertyType is the kind of input a line-item property takes
+type LineItemPropertyType string
+
+const (
+	LineItemPropertyTypeText        LineItemPropertyType = "TEXT"         // Free text, e.g. engraving or a gift message
+	LineItemPropertyTypeSelect      LineItemPropertyType = "SELECT"       // One of Options
+	LineItemPropertyTypeMultiSelect LineItemPropertyType = "MULTI_SELECT" // Comma-separated Options, e.g. add-ons
+)
+
+// LineItemPropertyRule describes a customization a product accepts on its cart lines.
+// Products declare their rules in metadata.lineItemProperties in products-service.
+type LineItemPropertyRule struct {
+	Name      string               `json:"name"`
+	Label     string               `json:"label,omitempty"`
+	Type      LineItemPropertyType `json:"type"`
+	Required  bool                 `json:"required,omitempty"`
+	MaxLength int                  `json:"maxLength,omitempty"` // TEXT only; 0 means the default limit
+	Pattern   string               `json:"pattern,omitempty"`   // TEXT only; regular expression the whole value must match
+	Options   []string             `json:"options,omitempty"`   // SELECT and MULTI_SELECT
+}
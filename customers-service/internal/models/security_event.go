@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SecurityEventType is the kind of account activity recorded for a customer
+type SecurityEventType string
+
+const (
+	SecurityEventLoginSucceeded SecurityEventType = "LOGIN_SUCCEEDED"
+	SecurityEventLoginFailed    SecurityEventType = "LOGIN_FAILED"
+	// SecurityEventNewDeviceLogin is a successful login from a device the customer hasn't used before
+	SecurityEventNewDeviceLogin SecurityEventType = "NEW_DEVICE_LOGIN"
+	// SecurityEventRepeatedLoginFailures is recorded once failed logins reach the alert threshold
+	SecurityEventRepeatedLoginFailures SecurityEventType = "REPEATED_LOGIN_FAILURES"
+	SecurityEventPasswordChanged       SecurityEventType = "PASSWORD_CHANGED"
+	SecurityEventPasswordReset         SecurityEventType = "PASSWORD_RESET"
+	SecurityEventAccountLocked         SecurityEventType = "ACCOUNT_LOCKED"
+	SecurityEventPaymentMethodAdded    SecurityEventType = "PAYMENT_METHOD_ADDED"
+	SecurityEventPaymentMethodRemoved  SecurityEventType = "PAYMENT_METHOD_REMOVED"
+)
+
+// IsAlert reports whether the customer should be told about this kind of activity.
+// Routine logins and failed attempts below the threshold are only recorded.
+func (t SecurityEventType) IsAlert() bool {
+	switch t {
+	case SecurityEventLoginSucceeded, SecurityEventLoginFailed:
+		return false
+	}
+	return true
+}
+
+// CustomerSecurityEvent is an entry in a storefront customer's security audit log
+type CustomerSecurityEvent struct {
+	ID         uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID   string            `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_customer_security_events_customer"`
+	CustomerID uuid.UUID         `json:"customerId" gorm:"type:uuid;not null;index:idx_customer_security_events_customer"`
+	Type       SecurityEventType `json:"type" gorm:"type:varchar(40);not null"`
+	// Suspicious marks activity the customer may not have done themselves
+	Suspicious  bool      `json:"suspicious" gorm:"not null;default:false"`
+	IPAddress   string    `json:"ipAddress,omitempty" gorm:"type:varchar(64)"`
+	UserAgent   string    `json:"userAgent,omitempty" gorm:"type:text"`
+	DeviceID    string    `json:"deviceId,omitempty" gorm:"type:varchar(64)"` // Fingerprint of the customer's device, see CustomerDevice
+	DeviceType  string    `json:"deviceType,omitempty" gorm:"type:varchar(20)"`
+	Location    string    `json:"location,omitempty" gorm:"type:varchar(255)"`
+	Description string    `json:"description,omitempty" gorm:"type:text"`
+	OccurredAt  time.Time `json:"occurredAt" gorm:"not null;index:idx_customer_security_events_customer"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName returns the table name for GORM
+func (CustomerSecurityEvent) TableName() string {
+	return "customer_security_events"
+}
+
+// CustomerDevice is a device a customer has logged in from, used to spot logins from new devices
+type CustomerDevice struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID    string    `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:idx_customer_devices_fingerprint"`
+	CustomerID  uuid.UUID `json:"customerId" gorm:"type:uuid;not null;uniqueIndex:idx_customer_devices_fingerprint"`
+	Fingerprint string    `json:"fingerprint" gorm:"type:varchar(64);not null;uniqueIndex:idx_customer_devices_fingerprint"`
+	UserAgent   string    `json:"userAgent,omitempty" gorm:"type:text"`
+	LastIP      string    `json:"lastIp,omitempty" gorm:"type:varchar(64)"`
+	FirstSeenAt time.Time `json:"firstSeenAt" gorm:"not null"`
+	LastSeenAt  time.Time `json:"lastSeenAt" gorm:"not null"`
+}
+
+// TableName returns the table name for GORM
+func (CustomerDevice) TableName() string {
+	return "customer_devices"
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// TimelineEntryType identifies where a timeline entry came from
+type TimelineEntryType string
+
+const (
+	TimelineEntryOrder         TimelineEntryType = "order"
+	TimelineEntryPayment       TimelineEntryType = "payment"
+	TimelineEntryTicket        TimelineEntryType = "ticket"
+	TimelineEntryReview        TimelineEntryType = "review"
+	TimelineEntryLoyalty       TimelineEntryType = "loyalty"
+	TimelineEntryCommunication TimelineEntryType = "communication"
+)
+
+// Timeline sources, one per service the timeline reads from
+const (
+	TimelineSourceOrders         = "orders"
+	TimelineSourceTickets        = "tickets"
+	TimelineSourceReviews        = "reviews"
+	TimelineSourceLoyalty        = "loyalty"
+	TimelineSourceCommunications = "communications"
+)
+
+// Timeline source statuses
+const (
+	TimelineSourceOK          = "ok"
+	TimelineSourceUnavailable = "unavailable"
+	TimelineSourceSkipped     = "skipped" // e.g. tickets and reviews for a guest without a user account
+)
+
+// TimelineEntry is one event in a customer's unified timeline
+type TimelineEntry struct {
+	Type       TimelineEntryType `json:"type"`
+	ID         string            `json:"id"`
+	OccurredAt time.Time         `json:"occurredAt"`
+	Title      string            `json:"title"`
+	Status     string            `json:"status,omitempty"`
+	Amount     *float64          `json:"amount,omitempty"`
+	Currency   string            `json:"currency,omitempty"`
+	Points     *int              `json:"points,omitempty"`
+	// Reference is the ID of the record the entry belongs to, e.g. the order of a payment
+	Reference string `json:"reference,omitempty"`
+}
+
+// CustomerTimeline is a customer's orders, payments, tickets, reviews, loyalty transactions
+// and communications in one feed, newest first
+type CustomerTimeline struct {
+	CustomerID string          `json:"customerId"`
+	Entries    []TimelineEntry `json:"entries"`
+	// Sources reports each source's status; entries from unavailable sources are missing
+	Sources map[string]string `json:"sources"`
+	Partial bool              `json:"partial"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "fmt"
+
+// VersionConflictError is returned when an update was made against a stale
+// version of a customer. CurrentVersion is the version currently stored.
+type VersionConflictError struct {
+	CurrentVersion int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict: current version is %d", e.CurrentVersion)
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"customers-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AccountDeletionRepository handles account deletion request data operations
+type AccountDeletionRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountDeletionRepository creates a new account deletion repository
+func NewAccountDeletionRepository(db *gorm.DB) *AccountDeletionRepository {
+	return &AccountDeletionRepository{db: db}
+}
+
+// Create creates a new account deletion request
+func (r *AccountDeletionRepository) Create(ctx context.Context, request *models.AccountDeletionRequest) error {
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+// Update saves an account deletion request
+func (r *AccountDeletionRepository) Update(ctx context.Context, request *models.AccountDeletionRequest) error {
+	return r.db.WithContext(ctx).Save(request).Error
+}
+
+// GetLatestByCustomer retrieves a customer's most recent account deletion request, or nil if
+// they have never asked for one
+func (r *AccountDeletionRepository) GetLatestByCustomer(ctx context.Context, tenantID string, customerID uuid.UUID) (*models.AccountDeletionRequest, error) {
+	var request models.AccountDeletionRequest
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND customer_id = ?", tenantID, customerID).
+		Order("created_at DESC").
+		First(&request).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetDueScheduled retrieves scheduled requests, across tenants, whose cooling-off period has passed
+func (r *AccountDeletionRepository) GetDueScheduled(ctx context.Context, now time.Time, limit int) ([]models.AccountDeletionRequest, error) {
+	var requests []models.AccountDeletionRequest
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_for <= ?", models.AccountDeletionScheduled, now).
+		Order("scheduled_for ASC").
+		Limit(limit).
+		Find(&requests).Error
+	return requests, err
+}
+
+// GetStalledProcessing retrieves processing requests, across tenants, whose anonymization
+// request was last published before the given time
+func (r *AccountDeletionRepository) GetStalledProcessing(ctx context.Context, publishedBefore time.Time, limit int) ([]models.AccountDeletionRequest, error) {
+	var requests []models.AccountDeletionRequest
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND (last_published_at IS NULL OR last_published_at < ?)", models.AccountDeletionProcessing, publishedBefore).
+		Order("last_published_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&requests).Error
+	return requests, err
+}
+
+// StartProcessing moves a scheduled request to processing. It reports false when the request
+// was cancelled or picked up by another worker in the meantime.
+func (r *AccountDeletionRepository) StartProcessing(ctx context.Context, request *models.AccountDeletionRequest, services []string, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.AccountDeletionRequest{}).
+		Where("tenant_id = ? AND id = ? AND status = ?", request.TenantID, request.ID, models.AccountDeletionScheduled).
+		Updates(map[string]interface{}{
+			"status":                models.AccountDeletionProcessing,
+			"pending_services":      pq.StringArray(services),
+			"processing_started_at": now,
+			"updated_at":            now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	request.Status = models.AccountDeletionProcessing
+	request.PendingServices = services
+	request.ProcessingStartedAt = &now
+	return true, nil
+}
+
+// MarkPublished records when the anonymization request was last published for a request
+func (r *AccountDeletionRepository) MarkPublished(ctx context.Context, tenantID string, requestID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.AccountDeletionRequest{}).
+		Where("tenant_id = ? AND id = ?", tenantID, requestID).
+		Update("last_published_at", at).Error
+}
+
+// RecordServiceAnonymized moves a service from the pending to the completed services of a
+// processing request. It reports false for confirmations of other statuses and repeated ones,
+// which leave the request unchanged.
+func (r *AccountDeletionRepository) RecordServiceAnonymized(ctx context.Context, tenantID string, requestID uuid.UUID, service string) (*models.AccountDeletionRequest, bool, error) {
+	var request models.AccountDeletionRequest
+	changed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND id = ?", tenantID, requestID).
+			First(&request).Error; err != nil {
+			return err
+		}
+		if request.Status != models.AccountDeletionProcessing {
+			return nil
+		}
+
+		pending := make([]string, 0, len(request.PendingServices))
+		found := false
+		for _, s := range request.PendingServices {
+			if s == service {
+				found = true
+				continue
+			}
+			pending = append(pending, s)
+		}
+		if !found {
+			return nil
+		}
+
+		request.PendingServices = pending
+		request.CompletedServices = append(request.CompletedServices, service)
+		changed = true
+		return tx.Model(&request).Updates(map[string]interface{}{
+			"pending_services":   request.PendingServices,
+			"completed_services": request.CompletedServices,
+			"updated_at":         time.Now(),
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, fmt.Errorf("account deletion request not found")
+		}
+		return nil, false, err
+	}
+	return &request, changed, nil
+}
+
+// AnonymizeCustomer replaces a customer's personal data with placeholders, removes the data
+// that only makes sense for a live account and soft deletes the customer. Order statistics
+// are kept so tenant reporting stays intact.
+func (r *AccountDeletionRepository) AnonymizeCustomer(ctx context.Context, tenantID string, customerID uuid.UUID, placeholderEmail string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&models.Customer{}).
+			Where("tenant_id = ? AND id = ?", tenantID, customerID).
+			Updates(map[string]interface{}{
+				"email":                         placeholderEmail,
+				"first_name":                    "Deleted",
+				"last_name":                     "Customer",
+				"phone":                         "",
+				"user_id":                       nil,
+				"status":                        models.CustomerStatusInactive,
+				"date_of_birth":                 nil,
+				"avatar_url":                    "",
+				"tags":                          nil,
+				"notes":                         "",
+				"marketing_opt_in":              false,
+				"lock_reason":                   "",
+				"unlock_reason":                 "",
+				"verification_token":            "",
+				"verification_token_expires_at": nil,
+				"updated_at":                    now,
+				"deleted_at":                    now,
+				"version":                       gorm.Expr("version + 1"),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to anonymize customer: %w", err)
+		}
+
+		if err := tx.Model(&models.AbandonedCart{}).
+			Where("tenant_id = ? AND customer_id = ?", tenantID, customerID).
+			Updates(map[string]interface{}{
+				"customer_email":      placeholderEmail,
+				"customer_first_name": "",
+				"customer_last_name":  "",
+				"next_reminder_at":    nil,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to anonymize abandoned carts: %w", err)
+		}
+
+		if err := tx.Where("list_id IN (?)",
+			tx.Model(&models.CustomerList{}).Select("id").Where("tenant_id = ? AND customer_id = ?", tenantID, customerID),
+		).Delete(&models.CustomerListItem{}).Error; err != nil {
+			return fmt.Errorf("failed to delete list items: %w", err)
+		}
+
+		owned := []interface{}{
+			&models.CustomerAddress{},
+			&models.CustomerPaymentMethod{},
+			&models.CustomerWishlistItem{},
+			&models.CustomerCart{},
+			&models.CustomerList{},
+			&models.CustomerNote{},
+			&models.CustomerCommunication{},
+			&models.CustomerSecurityEvent{},
+			&models.CustomerDevice{},
+		}
+		for _, model := range owned {
+			if err := tx.Where("tenant_id = ? AND customer_id = ?", tenantID, customerID).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to delete %T: %w", model, err)
+			}
+		}
+
+		return tx.Exec("DELETE FROM customer_segment_members WHERE customer_id = ?", customerID).Error
+	})
+}
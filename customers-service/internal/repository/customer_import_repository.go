@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"customers-service/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CustomerImportRepository handles customer import job data operations
+type CustomerImportRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomerImportRepository creates a new customer import repository
+func NewCustomerImportRepository(db *gorm.DB) *CustomerImportRepository {
+	return &CustomerImportRepository{db: db}
+}
+
+// Create queues a new import job
+func (r *CustomerImportRepository) Create(ctx context.Context, job *models.CustomerImportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// GetByID retrieves one of the tenant's import jobs
+func (r *CustomerImportRepository) GetByID(ctx context.Context, tenantID string, jobID uuid.UUID) (*models.CustomerImportJob, error) {
+	var job models.CustomerImportJob
+	err := r.db.WithContext(ctx).
+		Omit("file_data").
+		Where("tenant_id = ? AND id = ?", tenantID, jobID).
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// List retrieves the tenant's import jobs, newest first
+func (r *CustomerImportRepository) List(ctx context.Context, tenantID string, limit, offset int) ([]models.CustomerImportJob, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.CustomerImportJob{}).Where("tenant_id = ?", tenantID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []models.CustomerImportJob
+	err := query.Omit("file_data").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&jobs).Error
+	return jobs, total, err
+}
+
+// ClaimNext claims the oldest queued job across tenants, or a processing job whose worker
+// stopped sending heartbeats. It returns nil when there is nothing to do.
+func (r *CustomerImportRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.CustomerImportJob, error) {
+	var job models.CustomerImportJob
+	now := time.Now()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND heartbeat_at < ?)",
+				models.CustomerImportPending, models.CustomerImportProcessing, now.Add(-staleAfter)).
+			Order("created_at ASC").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":       models.CustomerImportProcessing,
+			"heartbeat_at": now,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"updated_at":   now,
+		}
+		if job.StartedAt == nil {
+			updates["started_at"] = now
+		}
+		return tx.Model(&models.CustomerImportJob{}).
+			Where("tenant_id = ? AND id = ?", job.TenantID, job.ID).
+			Updates(updates).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = models.CustomerImportProcessing
+	job.Attempts++
+	return &job, nil
+}
+
+// SaveChunk commits a processed chunk's progress and row errors together, so NextRow never
+// advances past rows whose errors were not recorded
+func (r *CustomerImportRepository) SaveChunk(ctx context.Context, job *models.CustomerImportJob, rowErrors []models.CustomerImportError) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(rowErrors) > 0 {
+			for i := range rowErrors {
+				rowErrors[i].JobID = job.ID
+				rowErrors[i].TenantID = job.TenantID
+				rowErrors[i].CreatedAt = now
+			}
+			if err := tx.CreateInBatches(rowErrors, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.CustomerImportJob{}).
+			Where("tenant_id = ? AND id = ?", job.TenantID, job.ID).
+			Updates(map[string]interface{}{
+				"next_row":      job.NextRow,
+				"created_count": job.CreatedCount,
+				"updated_count": job.UpdatedCount,
+				"skipped_count": job.SkippedCount,
+				"failed_count":  job.FailedCount,
+				"heartbeat_at":  now,
+				"updated_at":    now,
+			}).Error
+	})
+}
+
+// MarkCompleted marks the job finished and releases the stored file
+func (r *CustomerImportRepository) MarkCompleted(ctx context.Context, job *models.CustomerImportJob) error {
+	now := time.Now()
+	err := r.db.WithContext(ctx).Model(&models.CustomerImportJob{}).
+		Where("tenant_id = ? AND id = ?", job.TenantID, job.ID).
+		Updates(map[string]interface{}{
+			"status":       models.CustomerImportCompleted,
+			"completed_at": now,
+			"file_data":    nil,
+			"updated_at":   now,
+		}).Error
+	if err == nil {
+		job.Status = models.CustomerImportCompleted
+		job.CompletedAt = &now
+	}
+	return err
+}
+
+// MarkFailed marks the job failed; the file is kept so the job can be resumed
+func (r *CustomerImportRepository) MarkFailed(ctx context.Context, job *models.CustomerImportJob, message string) error {
+	err := r.db.WithContext(ctx).Model(&models.CustomerImportJob{}).
+		Where("tenant_id = ? AND id = ?", job.TenantID, job.ID).
+		Updates(map[string]interface{}{
+			"status":     models.CustomerImportFailed,
+			"last_error": message,
+			"updated_at": time.Now(),
+		}).Error
+	if err == nil {
+		job.Status = models.CustomerImportFailed
+		job.LastError = &message
+	}
+	return err
+}
+
+// Requeue hands a job interrupted by shutdown back to the queue
+func (r *CustomerImportRepository) Requeue(ctx context.Context, job *models.CustomerImportJob) error {
+	return r.db.WithContext(ctx).Model(&models.CustomerImportJob{}).
+		Where("tenant_id = ? AND id = ? AND status = ?", job.TenantID, job.ID, models.CustomerImportProcessing).
+		Updates(map[string]interface{}{
+			"status":     models.CustomerImportPending,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// Resume puts a failed job back in the queue. It reports false if the job is not failed.
+func (r *CustomerImportRepository) Resume(ctx context.Context, tenantID string, jobID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.CustomerImportJob{}).
+		Where("tenant_id = ? AND id = ? AND status = ? AND file_data IS NOT NULL", tenantID, jobID, models.CustomerImportFailed).
+		Updates(map[string]interface{}{
+			"status":     models.CustomerImportPending,
+			"last_error": nil,
+			"updated_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetErrors retrieves a job's row errors in row order
+func (r *CustomerImportRepository) GetErrors(ctx context.Context, tenantID string, jobID uuid.UUID) ([]models.CustomerImportError, error) {
+	var rowErrors []models.CustomerImportError
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND job_id = ?", tenantID, jobID).
+		Order("row_number ASC, created_at ASC").
+		Find(&rowErrors).Error
+	return rowErrors, err
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/Tesseract-Nexus/go-shared/cache"
 	"customers-service/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Cache TTL constants for customers
@@ -193,6 +194,32 @@ func (r *CustomerRepository) GetByEmail(ctx context.Context, tenantID string, em
 	return &customer, nil
 }
 
+// GetByEmails retrieves the customers with the given emails, compared case-insensitively,
+// keyed by lower-cased email
+func (r *CustomerRepository) GetByEmails(ctx context.Context, tenantID string, emails []string) (map[string]*models.Customer, error) {
+	found := make(map[string]*models.Customer, len(emails))
+	if len(emails) == 0 {
+		return found, nil
+	}
+
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+
+	var customers []models.Customer
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND LOWER(email) IN ?", tenantID, lowered).
+		Find(&customers).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range customers {
+		found[strings.ToLower(customers[i].Email)] = &customers[i]
+	}
+	return found, nil
+}
+
 // GetByUserID retrieves a customer by user ID
 func (r *CustomerRepository) GetByUserID(ctx context.Context, tenantID string, userID uuid.UUID) (*models.Customer, error) {
 	var customer models.Customer
@@ -219,8 +246,18 @@ type ListFilter struct {
 	Tags         []string
 	Limit        int
 	Offset       int
-	SortBy       string // Default: created_at
-	SortOrder    string // asc or desc
+	SortBy       string   // Default: created_at
+	SortOrder    string   // asc or desc
+	Include      []string // Relations to load, by JSON name; none by default
+}
+
+// CustomerRelations are the JSON names of the relations a customer response can expand
+var CustomerRelations = []string{"addresses", "paymentMethods"}
+
+// customerPreloads maps each relation to its association name
+var customerPreloads = map[string]string{
+	"addresses":      "Addresses",
+	"paymentMethods": "PaymentMethods",
 }
 
 // List retrieves customers with filters and pagination
@@ -272,6 +309,12 @@ func (r *CustomerRepository) List(ctx context.Context, filter ListFilter) ([]mod
 		query = query.Offset(filter.Offset)
 	}
 
+	for _, relation := range filter.Include {
+		if association, ok := customerPreloads[relation]; ok {
+			query = query.Preload(association)
+		}
+	}
+
 	var customers []models.Customer
 	if err := query.Find(&customers).Error; err != nil {
 		return nil, 0, err
@@ -281,12 +324,36 @@ func (r *CustomerRepository) List(ctx context.Context, filter ListFilter) ([]mod
 }
 
 // Update updates a customer
+// The write only applies while the stored version still equals customer.Version;
+// otherwise a *models.VersionConflictError carrying the stored version is returned.
 func (r *CustomerRepository) Update(ctx context.Context, customer *models.Customer) error {
-	err := r.db.WithContext(ctx).Save(customer).Error
-	if err == nil {
+	expected := customer.Version
+	customer.Version = expected + 1
+
+	result := r.db.WithContext(ctx).Model(customer).
+		Where("tenant_id = ? AND version = ?", customer.TenantID, expected).
+		Select("*").Omit(clause.Associations).
+		Updates(customer)
+	if result.Error != nil {
+		customer.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		customer.Version = expected
+		// The cached copy may be the stale one, drop it so the client re-reads
 		r.invalidateCustomerCaches(ctx, customer.TenantID, customer.ID, customer.Email)
+
+		var current models.Customer
+		if err := r.db.WithContext(ctx).Select("version").
+			Where("id = ? AND tenant_id = ?", customer.ID, customer.TenantID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		return &models.VersionConflictError{CurrentVersion: current.Version}
 	}
-	return err
+
+	r.invalidateCustomerCaches(ctx, customer.TenantID, customer.ID, customer.Email)
+	return nil
 }
 
 // Delete soft deletes a customer
@@ -307,6 +374,49 @@ func (r *CustomerRepository) Delete(ctx context.Context, tenantID string, custom
 	return err
 }
 
+// ListDeleted retrieves soft-deleted customers, most recently deleted first
+func (r *CustomerRepository) ListDeleted(ctx context.Context, tenantID string, limit, offset int) ([]models.Customer, int64, error) {
+	query := r.db.WithContext(ctx).Unscoped().Model(&models.Customer{}).
+		Where("tenant_id = ? AND deleted_at IS NOT NULL", tenantID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var customers []models.Customer
+	if err := query.Order("deleted_at DESC").Limit(limit).Offset(offset).Find(&customers).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return customers, total, nil
+}
+
+// Restore restores a soft-deleted customer
+// Returns nil without error if the customer is not in the trash
+func (r *CustomerRepository) Restore(ctx context.Context, tenantID string, customerID uuid.UUID) (*models.Customer, error) {
+	var customer models.Customer
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("tenant_id = ? AND id = ? AND deleted_at IS NOT NULL", tenantID, customerID).
+		First(&customer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.Customer{}).
+		Where("tenant_id = ? AND id = ?", tenantID, customerID).
+		Update("deleted_at", nil).Error; err != nil {
+		return nil, err
+	}
+
+	customer.DeletedAt = nil
+	r.invalidateCustomerCaches(ctx, tenantID, customerID, customer.Email)
+	return &customer, nil
+}
+
 // UpdateStats updates customer statistics (total_orders, total_spent, etc.)
 func (r *CustomerRepository) UpdateStats(ctx context.Context, customerID uuid.UUID, stats map[string]interface{}) error {
 	return r.db.WithContext(ctx).
@@ -390,10 +500,22 @@ func (r *CustomerRepository) GetPaymentMethods(ctx context.Context, tenantID str
 	return methods, err
 }
 
-// DeletePaymentMethod deletes a payment method
-func (r *CustomerRepository) DeletePaymentMethod(ctx context.Context, tenantID string, methodID uuid.UUID) error {
+// GetPaymentMethod retrieves one of a customer's payment methods
+func (r *CustomerRepository) GetPaymentMethod(ctx context.Context, tenantID string, customerID, methodID uuid.UUID) (*models.CustomerPaymentMethod, error) {
+	var method models.CustomerPaymentMethod
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND customer_id = ? AND id = ?", tenantID, customerID, methodID).
+		First(&method).Error
+	if err != nil {
+		return nil, err
+	}
+	return &method, nil
+}
+
+// DeletePaymentMethod deletes one of a customer's payment methods
+func (r *CustomerRepository) DeletePaymentMethod(ctx context.Context, tenantID string, customerID, methodID uuid.UUID) error {
 	return r.db.WithContext(ctx).
-		Where("tenant_id = ? AND id = ?", tenantID, methodID).
+		Where("tenant_id = ? AND customer_id = ? AND id = ?", tenantID, customerID, methodID).
 		Delete(&models.CustomerPaymentMethod{}).Error
 }
 
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"customers-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SecurityEventRepository handles customer security events and known devices
+type SecurityEventRepository struct {
+	db *gorm.DB
+}
+
+// NewSecurityEventRepository creates a new security event repository
+func NewSecurityEventRepository(db *gorm.DB) *SecurityEventRepository {
+	return &SecurityEventRepository{db: db}
+}
+
+// Create records a security event
+func (r *SecurityEventRepository) Create(ctx context.Context, event *models.CustomerSecurityEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// SecurityEventFilter narrows a customer's security events
+type SecurityEventFilter struct {
+	Type           models.SecurityEventType
+	SuspiciousOnly bool
+}
+
+// List retrieves a customer's security events, most recent first
+func (r *SecurityEventRepository) List(ctx context.Context, tenantID string, customerID uuid.UUID, filter SecurityEventFilter, page, pageSize int) ([]models.CustomerSecurityEvent, int64, error) {
+	var events []models.CustomerSecurityEvent
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.CustomerSecurityEvent{}).
+		Where("tenant_id = ? AND customer_id = ?", tenantID, customerID)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.SuspiciousOnly {
+		query = query.Where("suspicious = ?", true)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("occurred_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&events).Error
+	return events, total, err
+}
+
+// CountSince counts a customer's events of a type since a time
+func (r *SecurityEventRepository) CountSince(ctx context.Context, tenantID string, customerID uuid.UUID, eventType models.SecurityEventType, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.CustomerSecurityEvent{}).
+		Where("tenant_id = ? AND customer_id = ? AND type = ? AND occurred_at >= ?", tenantID, customerID, eventType, since).
+		Count(&count).Error
+	return count, err
+}
+
+// TouchDevice records a login from a device. It reports whether the device is new for the
+// customer and, if so, how many devices the customer already had.
+func (r *SecurityEventRepository) TouchDevice(ctx context.Context, device *models.CustomerDevice) (bool, int64, error) {
+	var known int64
+	err := r.db.WithContext(ctx).Model(&models.CustomerDevice{}).
+		Where("tenant_id = ? AND customer_id = ?", device.TenantID, device.CustomerID).
+		Count(&known).Error
+	if err != nil {
+		return false, 0, err
+	}
+
+	// Concurrent logins from the same new device insert it once; the others see it as known
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(device)
+	if result.Error != nil {
+		return false, 0, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, known, nil
+	}
+
+	err = r.db.WithContext(ctx).Model(&models.CustomerDevice{}).
+		Where("tenant_id = ? AND customer_id = ? AND fingerprint = ?", device.TenantID, device.CustomerID, device.Fingerprint).
+		Updates(map[string]interface{}{"last_seen_at": device.LastSeenAt, "last_ip": device.LastIP}).Error
+	return false, 0, err
+}
@@ -139,6 +139,21 @@ func (r *SegmentRepository) GetSegmentCustomers(ctx context.Context, tenantID st
 	return customers, err
 }
 
+// GetSegmentAudience returns a page of a segment's active, marketing opted-in customers in
+// ID order, starting after the given customer ID
+func (r *SegmentRepository) GetSegmentAudience(ctx context.Context, tenantID string, segmentID, after uuid.UUID, limit int) ([]models.Customer, error) {
+	var customers []models.Customer
+	err := r.db.WithContext(ctx).
+		Joins("JOIN customer_segment_members ON customer_segment_members.customer_id = customers.id").
+		Where("customer_segment_members.segment_id = ? AND customer_segment_members.tenant_id = ?", segmentID, tenantID).
+		Where("customers.marketing_opt_in = ? AND customers.status = ? AND customers.deleted_at IS NULL", true, models.CustomerStatusActive).
+		Where("customers.id > ?", after).
+		Order("customers.id ASC").
+		Limit(limit).
+		Find(&customers).Error
+	return customers, err
+}
+
 // IsCustomerInSegment checks if a customer is in a segment
 func (r *SegmentRepository) IsCustomerInSegment(ctx context.Context, segmentID uuid.UUID, customerID uuid.UUID) (bool, error) {
 	var count int64
@@ -148,6 +163,27 @@ func (r *SegmentRepository) IsCustomerInSegment(ctx context.Context, segmentID u
 	return count > 0, err
 }
 
+// GetCustomerSegmentIDs returns the active segments a customer belongs to
+func (r *SegmentRepository) GetCustomerSegmentIDs(ctx context.Context, tenantID string, customerID uuid.UUID) ([]uuid.UUID, error) {
+	var segmentIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&CustomerSegmentMember{}).
+		Joins("JOIN customer_segments ON customer_segments.id = customer_segment_members.segment_id").
+		Where("customer_segment_members.tenant_id = ? AND customer_segment_members.customer_id = ?", tenantID, customerID).
+		Where("customer_segments.is_active = ?", true).
+		Pluck("customer_segment_members.segment_id", &segmentIDs).Error
+	return segmentIDs, err
+}
+
+// GetCustomerTotalOrders returns a customer's order count, or gorm.ErrRecordNotFound
+func (r *SegmentRepository) GetCustomerTotalOrders(ctx context.Context, tenantID string, customerID uuid.UUID) (int, error) {
+	var customer models.Customer
+	err := r.db.WithContext(ctx).
+		Select("id", "total_orders").
+		Where("id = ? AND tenant_id = ?", customerID, tenantID).
+		First(&customer).Error
+	return customer.TotalOrders, err
+}
+
 // AddCustomersToSegment adds customers to a segment with auto-added flag
 func (r *SegmentRepository) AddCustomersToSegment(ctx context.Context, segmentID uuid.UUID, customerIDs []uuid.UUID, addedAutomatically bool) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
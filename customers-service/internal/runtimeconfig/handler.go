@@ -0,0 +1,171 @@
+package runtimeconfig
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// PlatformOwnerPriority is the role priority required for the config admin API. Settings apply to
+// every tenant, so only platform owners may change them.
+const PlatformOwnerPriority = 200
+
+// Signed requests carry the time they were signed and an HMAC-SHA256 of it, the method, the path
+// and the body, keyed with the service's config admin secret
+const (
+	TimestampHeader = "X-Config-Timestamp" // Unix seconds
+	SignatureHeader = "X-Config-Signature" // Hex encoded
+
+	maxSignatureAge     = 5 * time.Minute
+	defaultAuditLimit   = 50
+	maxAuditLimit       = maxAuditEntries
+	maxSignedBodyLength = 64 << 10
+)
+
+// Handler serves the runtime config admin API
+type Handler struct {
+	store  *Store
+	secret []byte
+}
+
+// NewHandler creates a handler for store. Requests must be signed with secret; without a secret
+// the API is disabled.
+func NewHandler(store *Store, secret string) *Handler {
+	return &Handler{store: store, secret: []byte(secret)}
+}
+
+// RegisterRoutes mounts the admin API on group. Requests are checked for a valid signature; role
+// checks are left to the caller, and the routes should be limited to platform owners.
+func (h *Handler) RegisterRoutes(group *gin.RouterGroup) {
+	config := group.Group("/config", h.requireSignature)
+	config.GET("", h.GetConfig)
+	config.POST("/reload", h.ReloadConfig)
+	config.GET("/audit", h.GetAudit)
+	config.PUT("/:key", h.SetSetting)
+	config.DELETE("/:key", h.UnsetSetting)
+}
+
+// Sign returns the signature for a request, for callers of the admin API
+func Sign(secret string, timestamp int64, method, path string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "\n" + method + "\n" + path + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// requireSignature rejects requests without a fresh, valid signature. The body is read to verify
+// it and then restored for the handler.
+func (h *Handler) requireSignature(c *gin.Context) {
+	if len(h.secret) == 0 {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"success": false, "error": "Config admin API is disabled", "message": "no config admin secret is configured"})
+		return
+	}
+
+	timestamp, err := strconv.ParseInt(c.GetHeader(TimestampHeader), 10, 64)
+	if err != nil {
+		abortUnsigned(c, "missing or invalid "+TimestampHeader)
+		return
+	}
+	if age := time.Since(time.Unix(timestamp, 0)); age > maxSignatureAge || age < -maxSignatureAge {
+		abortUnsigned(c, "signature expired")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSignedBodyLength+1))
+	if err != nil || len(body) > maxSignedBodyLength {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body"})
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	expected := Sign(string(h.secret), timestamp, c.Request.Method, c.Request.URL.Path, body)
+	if !hmac.Equal([]byte(expected), []byte(c.GetHeader(SignatureHeader))) {
+		abortUnsigned(c, "signature does not match")
+		return
+	}
+	c.Next()
+}
+
+func abortUnsigned(c *gin.Context, message string) {
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Invalid request signature", "message": message})
+}
+
+// GetConfig handles GET /config: every setting with its effective value and source
+func (h *Handler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": h.store.Effective()})
+}
+
+// ReloadConfig handles POST /config/reload. Other replicas reload on their next check.
+func (h *Handler) ReloadConfig(c *gin.Context) {
+	effective := h.store.Reload(c.Request.Context(), gosharedmw.GetIstioUserID(c))
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": effective})
+}
+
+// GetAudit handles GET /config/audit, the last ?limit changes (default 50)
+func (h *Handler) GetAudit(c *gin.Context) {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	if limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+	entries, err := h.store.Audit(c.Request.Context(), limit)
+	if err != nil {
+		respondError(c, err, "Failed to read config audit log")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": entries})
+}
+
+// SetSettingRequest is the body of PUT /config/:key
+type SetSettingRequest struct {
+	Value string `json:"value"`
+}
+
+// SetSetting handles PUT /config/:key, overriding the setting on every replica
+func (h *Handler) SetSetting(c *gin.Context) {
+	var req SetSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request body", "message": err.Error()})
+		return
+	}
+	view, err := h.store.Set(c.Request.Context(), c.Param("key"), req.Value, gosharedmw.GetIstioUserID(c))
+	if err != nil {
+		respondError(c, err, "Failed to update setting")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": view})
+}
+
+// UnsetSetting handles DELETE /config/:key, removing its override
+func (h *Handler) UnsetSetting(c *gin.Context) {
+	view, err := h.store.Unset(c.Request.Context(), c.Param("key"), gosharedmw.GetIstioUserID(c))
+	if err != nil {
+		respondError(c, err, "Failed to reset setting")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": view})
+}
+
+func respondError(c *gin.Context, err error, failure string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrUnknownSetting):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrInvalidValue):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrOverridesUnavailable):
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, gin.H{"success": false, "error": failure, "message": err.Error()})
+}
@@ -0,0 +1,594 @@
+// Package runtimeconfig holds a service's non-secret tunables (cache TTLs, thresholds, feature
+// toggles) and reloads them while the service runs, so changing one no longer needs a rollout.
+//
+// A setting starts from the value the service loaded from its environment at startup. It can be
+// overridden by a JSON file (RUNTIME_CONFIG_FILE, usually a mounted ConfigMap) and, above that,
+// by an override saved through the admin API, which is stored in Redis and shared by every
+// replica. Replicas pick up file and Redis changes within ReloadInterval. Secrets are never
+// registered here; they keep coming from Secret Manager or the environment.
+//
+// The package is kept identical in every service that uses it; change all copies together.
+package runtimeconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// ReloadInterval is how often the file and the Redis overrides are checked for changes
+const ReloadInterval = 10 * time.Second
+
+// maxAuditEntries is how many changes the audit log keeps per service
+const maxAuditEntries = 500
+
+var (
+	ErrUnknownSetting        = errors.New("unknown setting")
+	ErrInvalidValue          = errors.New("invalid value")
+	ErrOverridesUnavailable  = errors.New("overrides need Redis, which is unavailable")
+	errSettingDefinedTwice   = errors.New("setting defined twice")
+	errSettingDefinedStarted = errors.New("settings must be defined before Start")
+)
+
+// Type is the kind of value a setting holds
+type Type string
+
+const (
+	TypeDuration Type = "duration" // Go duration, e.g. "15m"
+	TypeInt      Type = "int"
+	TypeFloat    Type = "float"
+	TypeBool     Type = "bool"
+	TypeString   Type = "string"
+)
+
+// Source is where a setting's effective value comes from
+type Source string
+
+const (
+	SourceStartup  Source = "startup"  // Environment or built-in default, read at startup
+	SourceFile     Source = "file"     // RUNTIME_CONFIG_FILE
+	SourceOverride Source = "override" // Saved through the admin API
+)
+
+type setting struct {
+	key         string
+	typ         Type
+	description string
+	startup     any
+}
+
+// SettingView is a setting as returned by the admin API
+type SettingView struct {
+	Key          string `json:"key"`
+	Type         Type   `json:"type"`
+	Description  string `json:"description"`
+	Value        string `json:"value"`
+	Source       Source `json:"source"`
+	StartupValue string `json:"startupValue"`
+}
+
+// AuditEntry records one change made through the admin API
+type AuditEntry struct {
+	At       time.Time `json:"at"`
+	Actor    string    `json:"actor"`
+	Action   string    `json:"action"` // set, unset or reload
+	Key      string    `json:"key,omitempty"`
+	OldValue string    `json:"oldValue,omitempty"`
+	NewValue string    `json:"newValue,omitempty"`
+	Instance string    `json:"instance"`
+}
+
+// Effective is the current configuration of the service
+type Effective struct {
+	Service  string        `json:"service"`
+	Instance string        `json:"instance"`
+	File     string        `json:"file,omitempty"`
+	Version  int64         `json:"version"` // Redis override version, -1 before the first load
+	LoadedAt time.Time     `json:"loadedAt"`
+	Settings []SettingView `json:"settings"`
+}
+
+// Store holds the registered settings and their effective values
+type Store struct {
+	service  string
+	client   *redis.Client // nil disables overrides
+	file     string
+	instance string
+	logger   logrus.FieldLogger
+
+	mu        sync.RWMutex
+	started   bool
+	settings  map[string]*setting
+	values    map[string]any
+	sources   map[string]Source
+	version   int64
+	fileStamp string
+	fileRaw   map[string]string
+	loadedAt  time.Time
+	audit     []AuditEntry // Used when Redis is unavailable
+}
+
+// New creates a store for service. client may be nil, in which case only the file is watched;
+// file may be empty.
+func New(service string, client *redis.Client, file string, logger logrus.FieldLogger) *Store {
+	instance, _ := os.Hostname()
+	return &Store{
+		service:  service,
+		client:   client,
+		file:     file,
+		instance: instance,
+		logger:   logger,
+		settings: map[string]*setting{},
+		values:   map[string]any{},
+		sources:  map[string]Source{},
+		version:  -1,
+	}
+}
+
+// Duration is a duration setting
+type Duration struct {
+	store *Store
+	key   string
+}
+
+// Get returns the effective value
+func (d *Duration) Get() time.Duration { return d.store.get(d.key).(time.Duration) }
+
+// Int is an integer setting
+type Int struct {
+	store *Store
+	key   string
+}
+
+// Get returns the effective value
+func (i *Int) Get() int { return i.store.get(i.key).(int) }
+
+// Float is a floating point setting
+type Float struct {
+	store *Store
+	key   string
+}
+
+// Get returns the effective value
+func (f *Float) Get() float64 { return f.store.get(f.key).(float64) }
+
+// Bool is a feature toggle
+type Bool struct {
+	store *Store
+	key   string
+}
+
+// Get returns the effective value
+func (b *Bool) Get() bool { return b.store.get(b.key).(bool) }
+
+// String is a string setting
+type String struct {
+	store *Store
+	key   string
+}
+
+// Get returns the effective value
+func (s *String) Get() string { return s.store.get(s.key).(string) }
+
+// Duration registers a duration setting whose startup value is value
+func (s *Store) Duration(key, description string, value time.Duration) *Duration {
+	s.define(key, TypeDuration, description, value)
+	return &Duration{store: s, key: key}
+}
+
+// Int registers an integer setting whose startup value is value
+func (s *Store) Int(key, description string, value int) *Int {
+	s.define(key, TypeInt, description, value)
+	return &Int{store: s, key: key}
+}
+
+// Float registers a floating point setting whose startup value is value
+func (s *Store) Float(key, description string, value float64) *Float {
+	s.define(key, TypeFloat, description, value)
+	return &Float{store: s, key: key}
+}
+
+// Bool registers a feature toggle whose startup value is value
+func (s *Store) Bool(key, description string, value bool) *Bool {
+	s.define(key, TypeBool, description, value)
+	return &Bool{store: s, key: key}
+}
+
+// String registers a string setting whose startup value is value
+func (s *Store) String(key, description string, value string) *String {
+	s.define(key, TypeString, description, value)
+	return &String{store: s, key: key}
+}
+
+// define panics on programming errors, like registering a job twice in jobqueue
+func (s *Store) define(key string, typ Type, description string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		panic(fmt.Sprintf("runtimeconfig: %s: %v", key, errSettingDefinedStarted))
+	}
+	if _, ok := s.settings[key]; ok {
+		panic(fmt.Sprintf("runtimeconfig: %s: %v", key, errSettingDefinedTwice))
+	}
+	s.settings[key] = &setting{key: key, typ: typ, description: description, startup: value}
+	s.values[key] = value
+	s.sources[key] = SourceStartup
+}
+
+func (s *Store) get(key string) any {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[key]
+}
+
+// Start loads the file and overrides, then reloads them whenever they change until ctx is done
+func (s *Store) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.started = true
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	s.reload(ctx, false)
+
+	ticker := time.NewTicker(ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.reload(ctx, false)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reload re-reads the file and the Redis overrides when they changed, or always when force is
+// set. A source that can't be read keeps its last values.
+func (s *Store) reload(ctx context.Context, force bool) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	s.mu.RLock()
+	fileRaw, fileStamp, version := s.fileRaw, s.fileStamp, s.version
+	s.mu.RUnlock()
+
+	fileChanged := false
+	if s.file != "" {
+		raw, stamp, err := s.readFile(fileStamp, force)
+		if err != nil {
+			s.logger.WithError(err).WithField("file", s.file).Warn("Failed to read runtime config file, keeping current values")
+		} else if raw != nil {
+			fileRaw, fileStamp, fileChanged = raw, stamp, true
+		}
+	}
+
+	var overrides map[string]string
+	overridesChanged := false
+	if s.client != nil {
+		current, err := s.client.Get(ctx, s.redisKey("version")).Int64()
+		if err != nil && err != redis.Nil {
+			s.logger.WithError(err).Warn("Failed to read runtime config version, keeping current overrides")
+		} else if force || current != version {
+			values, err := s.client.HGetAll(ctx, s.redisKey("overrides")).Result()
+			if err != nil {
+				s.logger.WithError(err).Warn("Failed to read runtime config overrides, keeping current overrides")
+			} else {
+				overrides, version, overridesChanged = values, current, true
+			}
+		}
+	}
+	if !fileChanged && !overridesChanged {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !overridesChanged {
+		overrides = s.currentOverrides()
+	}
+	s.fileRaw, s.fileStamp, s.version = fileRaw, fileStamp, version
+	s.loadedAt = time.Now()
+
+	for key, st := range s.settings {
+		value, source := st.startup, SourceStartup
+		if raw, ok := fileRaw[key]; ok {
+			if parsed, err := parseValue(st.typ, raw); err == nil {
+				value, source = parsed, SourceFile
+			} else {
+				s.logger.WithError(err).WithField("key", key).Warn("Ignoring invalid value in runtime config file")
+			}
+		}
+		if raw, ok := overrides[key]; ok {
+			if parsed, err := parseValue(st.typ, raw); err == nil {
+				value, source = parsed, SourceOverride
+			} else {
+				s.logger.WithError(err).WithField("key", key).Warn("Ignoring invalid runtime config override")
+			}
+		}
+		if old := s.values[key]; old != value {
+			s.logger.WithFields(logrus.Fields{
+				"key": key, "old": formatValue(old), "new": formatValue(value), "source": source,
+			}).Info("Runtime config setting changed")
+		}
+		s.values[key] = value
+		s.sources[key] = source
+	}
+}
+
+// currentOverrides rebuilds the last loaded overrides from the sources, so a file-only reload
+// keeps them. Callers hold s.mu.
+func (s *Store) currentOverrides() map[string]string {
+	overrides := map[string]string{}
+	for key, source := range s.sources {
+		if source == SourceOverride {
+			overrides[key] = formatValue(s.values[key])
+		}
+	}
+	return overrides
+}
+
+// readFile returns the file's values if it changed since stamp (modification time and size),
+// or nil when it did not. A missing file has no values.
+func (s *Store) readFile(stamp string, force bool) (map[string]string, string, error) {
+	info, err := os.Stat(s.file)
+	if os.IsNotExist(err) {
+		if stamp == "" && !force {
+			return nil, "", nil
+		}
+		return map[string]string{}, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	current := info.ModTime().UTC().Format(time.RFC3339Nano) + "/" + strconv.FormatInt(info.Size(), 10)
+	if current == stamp && !force {
+		return nil, "", nil
+	}
+
+	data, err := os.ReadFile(s.file)
+	if err != nil {
+		return nil, "", err
+	}
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, "", fmt.Errorf("decode %s: %w", s.file, err)
+	}
+	raw := make(map[string]string, len(decoded))
+	for key, value := range decoded {
+		var str string
+		if err := json.Unmarshal(value, &str); err == nil {
+			raw[key] = str
+		} else {
+			raw[key] = string(value) // Numbers and booleans
+		}
+	}
+	return raw, current, nil
+}
+
+// Effective returns every setting with its effective value and where it comes from
+func (s *Store) Effective() Effective {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	views := make([]SettingView, 0, len(s.settings))
+	for key := range s.settings {
+		views = append(views, s.view(key))
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Key < views[j].Key })
+	return Effective{
+		Service:  s.service,
+		Instance: s.instance,
+		File:     s.file,
+		Version:  s.version,
+		LoadedAt: s.loadedAt,
+		Settings: views,
+	}
+}
+
+// view builds the admin view of a setting. Callers hold s.mu.
+func (s *Store) view(key string) SettingView {
+	st := s.settings[key]
+	return SettingView{
+		Key:          key,
+		Type:         st.typ,
+		Description:  st.description,
+		Value:        formatValue(s.values[key]),
+		Source:       s.sources[key],
+		StartupValue: formatValue(st.startup),
+	}
+}
+
+// Set saves an override for key, shared by every replica, and applies it on this one
+func (s *Store) Set(ctx context.Context, key, value, actor string) (SettingView, error) {
+	s.mu.RLock()
+	st, ok := s.settings[key]
+	var old string
+	if ok {
+		old = formatValue(s.values[key])
+	}
+	s.mu.RUnlock()
+	if !ok {
+		return SettingView{}, fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+	}
+	parsed, err := parseValue(st.typ, value)
+	if err != nil {
+		return SettingView{}, fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+	if s.client == nil {
+		return SettingView{}, ErrOverridesUnavailable
+	}
+
+	value = formatValue(parsed)
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, s.redisKey("overrides"), key, value)
+	pipe.Incr(ctx, s.redisKey("version"))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return SettingView{}, fmt.Errorf("save override: %w", err)
+	}
+	s.recordAudit(ctx, AuditEntry{Actor: actor, Action: "set", Key: key, OldValue: old, NewValue: value})
+	s.reload(ctx, false)
+	return s.settingView(key), nil
+}
+
+// Unset removes the override for key, returning it to its file or startup value
+func (s *Store) Unset(ctx context.Context, key, actor string) (SettingView, error) {
+	s.mu.RLock()
+	_, ok := s.settings[key]
+	var old string
+	if ok {
+		old = formatValue(s.values[key])
+	}
+	s.mu.RUnlock()
+	if !ok {
+		return SettingView{}, fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+	}
+	if s.client == nil {
+		return SettingView{}, ErrOverridesUnavailable
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.HDel(ctx, s.redisKey("overrides"), key)
+	pipe.Incr(ctx, s.redisKey("version"))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return SettingView{}, fmt.Errorf("remove override: %w", err)
+	}
+	s.reload(ctx, false)
+	view := s.settingView(key)
+	s.recordAudit(ctx, AuditEntry{Actor: actor, Action: "unset", Key: key, OldValue: old, NewValue: view.Value})
+	return view, nil
+}
+
+// Reload re-reads the file and overrides on this replica now and asks the others to do the same
+func (s *Store) Reload(ctx context.Context, actor string) Effective {
+	if s.client != nil {
+		if err := s.client.Incr(ctx, s.redisKey("version")).Err(); err != nil {
+			s.logger.WithError(err).Warn("Failed to bump runtime config version, other replicas reload on their own schedule")
+		}
+	}
+	s.reload(ctx, true)
+	s.recordAudit(ctx, AuditEntry{Actor: actor, Action: "reload"})
+	return s.Effective()
+}
+
+func (s *Store) settingView(key string) SettingView {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.view(key)
+}
+
+// recordAudit appends entry to the shared audit log, or to this replica's log without Redis
+func (s *Store) recordAudit(ctx context.Context, entry AuditEntry) {
+	entry.At = time.Now().UTC()
+	entry.Instance = s.instance
+	s.logger.WithFields(logrus.Fields{
+		"actor": entry.Actor, "action": entry.Action, "key": entry.Key, "old": entry.OldValue, "new": entry.NewValue,
+	}).Info("Runtime config changed through admin API")
+
+	if s.client != nil {
+		data, err := json.Marshal(entry)
+		if err == nil {
+			pipe := s.client.TxPipeline()
+			pipe.LPush(ctx, s.redisKey("audit"), data)
+			pipe.LTrim(ctx, s.redisKey("audit"), 0, maxAuditEntries-1)
+			if _, err = pipe.Exec(ctx); err == nil {
+				return
+			}
+		}
+		s.logger.WithError(err).Warn("Failed to save runtime config audit entry")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.audit = append([]AuditEntry{entry}, s.audit...)
+	if len(s.audit) > maxAuditEntries {
+		s.audit = s.audit[:maxAuditEntries]
+	}
+}
+
+// Audit returns the most recent changes, newest first
+func (s *Store) Audit(ctx context.Context, limit int) ([]AuditEntry, error) {
+	if s.client == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		if limit > len(s.audit) {
+			limit = len(s.audit)
+		}
+		return append([]AuditEntry(nil), s.audit[:limit]...), nil
+	}
+
+	values, err := s.client.LRange(ctx, s.redisKey("audit"), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+	entries := make([]AuditEntry, 0, len(values))
+	for _, value := range values {
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(value), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (s *Store) redisKey(name string) string {
+	return "runtimeconfig:" + s.service + ":" + name
+}
+
+// parseValue parses raw as a value of typ. Negative numbers and durations are rejected, since no
+// tunable is meaningful below zero.
+func parseValue(typ Type, raw string) (any, error) {
+	switch typ {
+	case TypeDuration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, err
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("duration %s is negative", raw)
+		}
+		return d, nil
+	case TypeInt:
+		i, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("%d is negative", i)
+		}
+		return i, nil
+	case TypeFloat:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, err
+		}
+		if f < 0 {
+			return nil, fmt.Errorf("%s is negative", raw)
+		}
+		return f, nil
+	case TypeBool:
+		return strconv.ParseBool(raw)
+	default:
+		return raw, nil
+	}
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case time.Duration:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
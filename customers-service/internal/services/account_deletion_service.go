@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"customers-service/internal/clients"
+	"customers-service/internal/models"
+	"customers-service/internal/repository"
+	"customers-service/internal/tenancy"
+)
+
+const (
+	// accountDeletionTokenTTL is how long the emailed confirmation link stays valid
+	accountDeletionTokenTTL = 24 * time.Hour
+
+	// accountDeletionRepublishAfter is how long a processing request waits for confirmations
+	// before the anonymization request is published again
+	accountDeletionRepublishAfter = 1 * time.Hour
+
+	// accountDeletionBatchSize is the number of requests handled per worker run and stage
+	accountDeletionBatchSize = 50
+)
+
+var (
+	ErrAccountDeletionNotFound       = errors.New("no account deletion request found")
+	ErrAccountDeletionInProgress     = errors.New("account deletion is already in progress")
+	ErrAccountDeletionNotCancellable = errors.New("account deletion can no longer be cancelled")
+	ErrInvalidDeletionToken          = errors.New("invalid or expired confirmation token")
+)
+
+// AnonymizationPublisher publishes the event asking other services to anonymize a customer.
+// It is implemented by the events publisher, which cannot be imported from here.
+type AnonymizationPublisher interface {
+	PublishAnonymizationRequested(ctx context.Context, customer *models.Customer, tenantID string, requestID uuid.UUID, anonymizedEmail string) error
+}
+
+// AccountDeletionService handles self-service account deletion: verification, the cooling-off
+// period and anonymization across the services that hold the customer's data
+type AccountDeletionService struct {
+	repo               *repository.AccountDeletionRepository
+	customerRepo       *repository.CustomerRepository
+	notificationClient *clients.NotificationClient
+	tenantClient       *clients.TenantClient
+	publisher          AnonymizationPublisher
+	coolingOff         time.Duration
+	services           []string
+}
+
+// NewAccountDeletionService creates a new account deletion service. services lists the
+// services that must confirm they anonymized a customer before the deletion completes.
+func NewAccountDeletionService(repo *repository.AccountDeletionRepository, customerRepo *repository.CustomerRepository, notificationClient *clients.NotificationClient, tenantClient *clients.TenantClient, coolingOff time.Duration, services []string) *AccountDeletionService {
+	return &AccountDeletionService{
+		repo:               repo,
+		customerRepo:       customerRepo,
+		notificationClient: notificationClient,
+		tenantClient:       tenantClient,
+		coolingOff:         coolingOff,
+		services:           services,
+	}
+}
+
+// SetPublisher sets the publisher used to ask other services to anonymize a customer.
+// Without one, deletions wait in processing until a publisher is available.
+func (s *AccountDeletionService) SetPublisher(publisher AnonymizationPublisher) {
+	s.publisher = publisher
+}
+
+// RequestDeletion starts an account deletion and emails the customer a confirmation link.
+// Asking again before confirming sends a fresh link; asking after confirming changes nothing.
+func (s *AccountDeletionService) RequestDeletion(ctx context.Context, tenantID string, customerID uuid.UUID, reason string) (*models.AccountDeletionRequest, error) {
+	customer, err := s.customerRepo.GetByID(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("customer not found: %w", err)
+	}
+
+	request, err := s.repo.GetLatestByCustomer(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if request != nil {
+		switch request.Status {
+		case models.AccountDeletionProcessing:
+			return nil, ErrAccountDeletionInProgress
+		case models.AccountDeletionScheduled:
+			return request, nil
+		}
+	}
+
+	token, err := generateDeletionToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(accountDeletionTokenTTL)
+
+	if request != nil && request.Status == models.AccountDeletionPendingVerification {
+		request.Reason = reason
+		request.VerificationTokenHash = hashDeletionToken(token)
+		request.VerificationExpiresAt = &expiresAt
+		if err := s.repo.Update(ctx, request); err != nil {
+			return nil, fmt.Errorf("failed to update account deletion request: %w", err)
+		}
+	} else {
+		request = &models.AccountDeletionRequest{
+			TenantID:              tenantID,
+			CustomerID:            customerID,
+			Status:                models.AccountDeletionPendingVerification,
+			Reason:                reason,
+			VerificationTokenHash: hashDeletionToken(token),
+			VerificationExpiresAt: &expiresAt,
+		}
+		if err := s.repo.Create(ctx, request); err != nil {
+			return nil, fmt.Errorf("failed to create account deletion request: %w", err)
+		}
+	}
+
+	s.notify(customer, func(notifyCtx context.Context, notification *clients.AccountDeletionNotification) error {
+		notification.ConfirmationLink = fmt.Sprintf("%s/account/delete/confirm?token=%s", notification.StorefrontURL, token)
+		return s.notificationClient.SendAccountDeletionVerification(notifyCtx, notification)
+	})
+
+	log.Printf("[AccountDeletionService] Customer %s requested account deletion", customerID)
+	return request, nil
+}
+
+// ConfirmDeletion verifies the emailed token and schedules the deletion for the end of the
+// cooling-off period
+func (s *AccountDeletionService) ConfirmDeletion(ctx context.Context, tenantID string, customerID uuid.UUID, token string) (*models.AccountDeletionRequest, error) {
+	request, err := s.repo.GetLatestByCustomer(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.Status != models.AccountDeletionPendingVerification {
+		return nil, ErrAccountDeletionNotFound
+	}
+	if request.VerificationExpiresAt == nil || time.Now().After(*request.VerificationExpiresAt) ||
+		subtle.ConstantTimeCompare([]byte(hashDeletionToken(token)), []byte(request.VerificationTokenHash)) != 1 {
+		return nil, ErrInvalidDeletionToken
+	}
+
+	now := time.Now()
+	scheduledFor := now.Add(s.coolingOff)
+	request.Status = models.AccountDeletionScheduled
+	request.VerifiedAt = &now
+	request.ScheduledFor = &scheduledFor
+	request.VerificationTokenHash = ""
+	request.VerificationExpiresAt = nil
+	if err := s.repo.Update(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to schedule account deletion: %w", err)
+	}
+
+	if customer, err := s.customerRepo.GetByID(ctx, tenantID, customerID); err == nil {
+		s.notify(customer, func(notifyCtx context.Context, notification *clients.AccountDeletionNotification) error {
+			notification.ScheduledFor = &scheduledFor
+			return s.notificationClient.SendAccountDeletionScheduled(notifyCtx, notification)
+		})
+	}
+
+	log.Printf("[AccountDeletionService] Account deletion for customer %s scheduled for %s", customerID, scheduledFor.Format(time.RFC3339))
+	return request, nil
+}
+
+// CancelDeletion cancels a deletion that has not started processing
+func (s *AccountDeletionService) CancelDeletion(ctx context.Context, tenantID string, customerID uuid.UUID) (*models.AccountDeletionRequest, error) {
+	request, err := s.repo.GetLatestByCustomer(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.Status == models.AccountDeletionCancelled {
+		return nil, ErrAccountDeletionNotFound
+	}
+	if !request.IsOpen() {
+		return nil, ErrAccountDeletionNotCancellable
+	}
+
+	now := time.Now()
+	request.Status = models.AccountDeletionCancelled
+	request.CancelledAt = &now
+	request.VerificationTokenHash = ""
+	request.VerificationExpiresAt = nil
+	if err := s.repo.Update(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+
+	log.Printf("[AccountDeletionService] Customer %s cancelled account deletion", customerID)
+	return request, nil
+}
+
+// GetDeletionStatus returns the customer's most recent account deletion request
+func (s *AccountDeletionService) GetDeletionStatus(ctx context.Context, tenantID string, customerID uuid.UUID) (*models.AccountDeletionRequest, error) {
+	request, err := s.repo.GetLatestByCustomer(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, ErrAccountDeletionNotFound
+	}
+	return request, nil
+}
+
+// ProcessDueDeletions starts anonymization for requests past their cooling-off period and
+// publishes the anonymization request again for processing requests that are still waiting
+// on confirmations. It is called by the account deletion worker with a cross-tenant context.
+func (s *AccountDeletionService) ProcessDueDeletions(ctx context.Context) (started, republished int, err error) {
+	now := time.Now()
+
+	due, err := s.repo.GetDueScheduled(ctx, now, accountDeletionBatchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get due account deletions: %w", err)
+	}
+	for i := range due {
+		request := &due[i]
+		tenantCtx := tenancy.WithTenant(ctx, request.TenantID)
+		if err := s.startProcessing(tenantCtx, request); err != nil {
+			log.Printf("[AccountDeletionService] Failed to start account deletion %s: %v", request.ID, err)
+			continue
+		}
+		started++
+	}
+
+	stalled, err := s.repo.GetStalledProcessing(ctx, now.Add(-accountDeletionRepublishAfter), accountDeletionBatchSize)
+	if err != nil {
+		return started, 0, fmt.Errorf("failed to get stalled account deletions: %w", err)
+	}
+	for i := range stalled {
+		request := &stalled[i]
+		tenantCtx := tenancy.WithTenant(ctx, request.TenantID)
+		// Every service confirmed but the local anonymization failed: retry it
+		if len(request.PendingServices) == 0 {
+			if err := s.completeDeletion(tenantCtx, request); err != nil {
+				log.Printf("[AccountDeletionService] Failed to complete account deletion %s: %v", request.ID, err)
+			}
+			continue
+		}
+		if err := s.publishAnonymizationRequest(tenantCtx, request); err != nil {
+			log.Printf("[AccountDeletionService] Failed to republish account deletion %s: %v", request.ID, err)
+			continue
+		}
+		republished++
+	}
+
+	return started, republished, nil
+}
+
+// RecordServiceAnonymized records that a service anonymized the customer of a deletion
+// request, and completes the deletion once every service has
+func (s *AccountDeletionService) RecordServiceAnonymized(ctx context.Context, tenantID string, requestID uuid.UUID, service string) error {
+	request, changed, err := s.repo.RecordServiceAnonymized(ctx, tenantID, requestID, service)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	log.Printf("[AccountDeletionService] %s anonymized customer %s (%d services pending)", service, request.CustomerID, len(request.PendingServices))
+	if len(request.PendingServices) > 0 {
+		return nil
+	}
+	return s.completeDeletion(ctx, request)
+}
+
+// startProcessing deactivates the customer and asks the participating services to anonymize it
+func (s *AccountDeletionService) startProcessing(ctx context.Context, request *models.AccountDeletionRequest) error {
+	customer, err := s.customerRepo.GetByID(ctx, request.TenantID, request.CustomerID)
+	if err != nil {
+		// The customer was deleted some other way; there is nothing left to anonymize here
+		now := time.Now()
+		request.Status = models.AccountDeletionCancelled
+		request.CancelledAt = &now
+		log.Printf("[AccountDeletionService] Customer %s no longer exists, cancelling account deletion %s", request.CustomerID, request.ID)
+		return s.repo.Update(ctx, request)
+	}
+
+	ok, err := s.repo.StartProcessing(ctx, request, s.services, time.Now())
+	if err != nil || !ok {
+		return err
+	}
+
+	// Stop marketing and sign-ins while the other services catch up
+	if err := s.customerRepo.UpdateStats(ctx, customer.ID, map[string]interface{}{
+		"status":           models.CustomerStatusInactive,
+		"marketing_opt_in": false,
+	}); err != nil {
+		log.Printf("[AccountDeletionService] Failed to deactivate customer %s: %v", customer.ID, err)
+	}
+	s.customerRepo.InvalidateCache(ctx, customer.TenantID, customer.ID)
+
+	if len(s.services) == 0 {
+		return s.completeDeletion(ctx, request)
+	}
+	return s.publishAnonymizationRequest(ctx, request)
+}
+
+// publishAnonymizationRequest publishes the anonymization request for a processing request
+func (s *AccountDeletionService) publishAnonymizationRequest(ctx context.Context, request *models.AccountDeletionRequest) error {
+	if s.publisher == nil {
+		return fmt.Errorf("events publisher not configured")
+	}
+
+	customer, err := s.customerRepo.GetByID(ctx, request.TenantID, request.CustomerID)
+	if err != nil {
+		return fmt.Errorf("customer not found: %w", err)
+	}
+
+	if err := s.publisher.PublishAnonymizationRequested(ctx, customer, request.TenantID, request.ID, anonymizedEmail(request.CustomerID)); err != nil {
+		return err
+	}
+	return s.repo.MarkPublished(ctx, request.TenantID, request.ID, time.Now())
+}
+
+// completeDeletion anonymizes the customer record once every service has confirmed, and
+// sends the completion email to the address the customer had
+func (s *AccountDeletionService) completeDeletion(ctx context.Context, request *models.AccountDeletionRequest) error {
+	customer, err := s.customerRepo.GetByID(ctx, request.TenantID, request.CustomerID)
+	if err != nil {
+		return fmt.Errorf("customer not found: %w", err)
+	}
+
+	// Invalidate while the cache keys still carry the original email
+	s.customerRepo.InvalidateCache(ctx, customer.TenantID, customer.ID)
+	if err := s.repo.AnonymizeCustomer(ctx, request.TenantID, request.CustomerID, anonymizedEmail(request.CustomerID)); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	request.Status = models.AccountDeletionCompleted
+	request.CompletedAt = &now
+	request.Reason = ""
+	if err := s.repo.Update(ctx, request); err != nil {
+		return fmt.Errorf("failed to complete account deletion: %w", err)
+	}
+
+	s.notify(customer, s.notificationClient.SendAccountDeletionCompleted)
+
+	log.Printf("[AccountDeletionService] Account deletion %s completed for customer %s", request.ID, request.CustomerID)
+	return nil
+}
+
+// notify sends an account deletion email to the customer without blocking the caller
+func (s *AccountDeletionService) notify(customer *models.Customer, send func(context.Context, *clients.AccountDeletionNotification) error) {
+	if s.notificationClient == nil {
+		return
+	}
+
+	go func() {
+		notifyCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		notification := &clients.AccountDeletionNotification{
+			TenantID:      customer.TenantID,
+			CustomerID:    customer.ID.String(),
+			CustomerEmail: customer.Email,
+			CustomerName:  customer.FirstName + " " + customer.LastName,
+			StorefrontURL: s.tenantClient.BuildStorefrontURL(notifyCtx, customer.TenantID),
+		}
+		if err := send(notifyCtx, notification); err != nil {
+			log.Printf("[AccountDeletionService] Failed to send account deletion notification: %v", err)
+		}
+	}()
+}
+
+// anonymizedEmail is the placeholder address a deleted customer is left with in every service
+func anonymizedEmail(customerID uuid.UUID) string {
+	return fmt.Sprintf("deleted-%s@anonymized.invalid", customerID)
+}
+
+// generateDeletionToken generates the random token emailed to confirm a deletion
+func generateDeletionToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashDeletionToken hashes a confirmation token for storage
+func hashDeletionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
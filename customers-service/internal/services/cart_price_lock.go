@@ -0,0 +1,132 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrPriceLockDisabled is returned when no price-lock secret is configured
+	ErrPriceLockDisabled = errors.New("cart price locks are not enabled")
+	// ErrPriceLockUnavailableItems is returned when a cart with unavailable items is locked
+	ErrPriceLockUnavailableItems = errors.New("cart has unavailable items; remove them before checkout")
+)
+
+// CartPriceLockRequest asks ValidateCart to lock the validated unit prices. Discounts, shipping
+// and tax aren't locked: orders-service still computes them when the order is created.
+type CartPriceLockRequest struct {
+	LockPrices bool   `json:"lockPrices"`
+	Currency   string `json:"currency"`
+}
+
+// PriceLockItem is a cart line at its locked unit price
+type PriceLockItem struct {
+	ProductID string  `json:"productId"`
+	VariantID string  `json:"variantId,omitempty"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unitPrice"`
+}
+
+// CartPriceLock is the payload of a price-lock token. orders-service verifies the token and
+// charges these unit prices, or asks the customer to re-quote when the order no longer matches.
+// Keep in sync with orders-service's PriceLock.
+type CartPriceLock struct {
+	TenantID   string          `json:"tenantId"`
+	CartID     string          `json:"cartId"`
+	CustomerID string          `json:"customerId"`
+	Currency   string          `json:"currency,omitempty"`
+	Items      []PriceLockItem `json:"items"`
+	Subtotal   float64         `json:"subtotal"`
+	IssuedAt   int64           `json:"iat"`
+	ExpiresAt  int64           `json:"exp"`
+}
+
+// CartPriceLockResponse is the lock returned to the storefront
+type CartPriceLockResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Currency  string    `json:"currency,omitempty"`
+	Subtotal  float64   `json:"subtotal"`
+}
+
+// SetPriceLock enables price-lock tokens signed with the secret shared with orders-service. ttl
+// is read for every lock, so changes to the runtime setting apply to new locks.
+func (s *CartValidationService) SetPriceLock(secret string, ttl func() time.Duration) {
+	if secret == "" {
+		return
+	}
+	s.priceLockSecret = []byte(secret)
+	s.priceLockTTL = ttl
+}
+
+// LockPrices freezes a validated cart's unit prices for the lock period and returns a signed
+// token for CreateOrder. Only prices just validated against products-service are signed;
+// nothing the client sends is.
+func (s *CartValidationService) LockPrices(tenantID, customerID string, result *CartValidationResult, req *CartPriceLockRequest) (*CartPriceLockResponse, error) {
+	if len(s.priceLockSecret) == 0 {
+		return nil, ErrPriceLockDisabled
+	}
+	if result.HasUnavailableItems {
+		return nil, ErrPriceLockUnavailableItems
+	}
+	if len(result.Items) == 0 {
+		return nil, fmt.Errorf("cart is empty")
+	}
+
+	now := time.Now()
+	lock := CartPriceLock{
+		TenantID:   tenantID,
+		CartID:     result.CartID.String(),
+		CustomerID: customerID,
+		Currency:   strings.ToUpper(req.Currency),
+		IssuedAt:   now.Unix(),
+		ExpiresAt:  now.Add(s.priceLockTTL()).Unix(),
+	}
+
+	for _, item := range result.Items {
+		lock.Items = append(lock.Items, PriceLockItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			UnitPrice: roundPrice(item.Price),
+		})
+		lock.Subtotal += roundPrice(item.Price) * float64(item.Quantity)
+	}
+	lock.Subtotal = roundPrice(lock.Subtotal)
+
+	token, err := s.signPriceLock(&lock)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CartPriceLockResponse{
+		Token:     token,
+		ExpiresAt: time.Unix(lock.ExpiresAt, 0),
+		Currency:  lock.Currency,
+		Subtotal:  lock.Subtotal,
+	}, nil
+}
+
+// signPriceLock encodes the lock as base64url(payload) "." base64url(HMAC-SHA256(payload))
+func (s *CartValidationService) signPriceLock(lock *CartPriceLock) (string, error) {
+	payload, err := json.Marshal(lock)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode price lock: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, s.priceLockSecret)
+	mac.Write([]byte(encoded))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func roundPrice(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
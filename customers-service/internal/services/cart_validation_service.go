@@ -14,8 +14,10 @@ import (
 
 // CartValidationService handles cart item validation against current product state.
 type CartValidationService struct {
-	db             *gorm.DB
-	productsClient *clients.ProductsClient
+	db              *gorm.DB
+	productsClient  *clients.ProductsClient
+	priceLockSecret []byte
+	priceLockTTL    func() time.Duration
 }
 
 // NewCartValidationService creates a new cart validation service.
@@ -58,6 +60,7 @@ type ValidatedItem struct {
 	LastValidatedAt *time.Time              `json:"lastValidatedAt"`
 	StatusMessage   string                  `json:"statusMessage,omitempty"`
 	PriceChange     *PriceChangeInfo        `json:"priceChange,omitempty"`
+	Properties      map[string]string       `json:"properties,omitempty"`
 }
 
 // PriceChangeInfo contains details about a price change.
@@ -171,6 +174,7 @@ func (s *CartValidationService) validateItems(ctx context.Context, tenantID stri
 			Image:           item.Image,
 			AddedAt:         item.AddedAt,
 			LastValidatedAt: &now,
+			Properties:      item.Properties,
 		}
 
 		// Set price info
@@ -260,6 +264,7 @@ func (s *CartValidationService) updateCartValidation(ctx context.Context, cart *
 			AvailableStock:  item.AvailableStock,
 			AddedAt:         item.AddedAt,
 			LastValidatedAt: item.LastValidatedAt,
+			Properties:      item.Properties,
 		}
 	}
 
@@ -389,7 +394,7 @@ func (s *CartValidationService) UpdateItemPrice(ctx context.Context, tenantID, p
 
 // RemoveExpiredCarts removes carts that have expired.
 func (s *CartValidationService) RemoveExpiredCarts(ctx context.Context) (int64, error) {
-	result := s.db.Where("expires_at IS NOT NULL AND expires_at < ?", time.Now()).Delete(&models.CustomerCart{})
+	result := s.db.WithContext(ctx).Where("expires_at IS NOT NULL AND expires_at < ?", time.Now()).Delete(&models.CustomerCart{})
 	if result.Error != nil {
 		return 0, fmt.Errorf("failed to delete expired carts: %w", result.Error)
 	}
@@ -401,7 +406,7 @@ func (s *CartValidationService) GetCartsNeedingValidation(ctx context.Context, m
 	cutoff := time.Now().Add(-maxAge)
 
 	var carts []models.CustomerCart
-	err := s.db.Where("item_count > 0 AND (last_validated_at IS NULL OR last_validated_at < ?)", cutoff).
+	err := s.db.WithContext(ctx).Where("item_count > 0 AND (last_validated_at IS NULL OR last_validated_at < ?)", cutoff).
 		Order("last_validated_at ASC NULLS FIRST").
 		Limit(limit).
 		Find(&carts).Error
@@ -0,0 +1,623 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"customers-service/internal/models"
+	"customers-service/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrImportJobNotFound is returned for import jobs that don't exist in the tenant
+	ErrImportJobNotFound = errors.New("import job not found")
+	// ErrImportJobNotResumable is returned when resuming a job that has not failed
+	ErrImportJobNotResumable = errors.New("import job has not failed and cannot be resumed")
+)
+
+// ImportValidationError is a problem with an import request that the caller can fix
+type ImportValidationError struct {
+	Code    string
+	Message string
+}
+
+func (e *ImportValidationError) Error() string {
+	return e.Message
+}
+
+// CustomerEventPublisher publishes customer lifecycle events. It is implemented by the events
+// publisher, which cannot be imported from here.
+type CustomerEventPublisher interface {
+	PublishCustomerCreated(ctx context.Context, customer *models.Customer, tenantID string) error
+	PublishCustomerUpdated(ctx context.Context, customer *models.Customer, tenantID string) error
+}
+
+// CustomerImportService imports customers from CSV and XLSX files. Uploads are queued as jobs
+// and processed in chunks by the import worker.
+type CustomerImportService struct {
+	repo             *repository.CustomerImportRepository
+	customerRepo     *repository.CustomerRepository
+	segmentRepo      *repository.SegmentRepository
+	segmentEvaluator *SegmentEvaluator
+	publisher        CustomerEventPublisher
+}
+
+// NewCustomerImportService creates a new customer import service
+func NewCustomerImportService(repo *repository.CustomerImportRepository, customerRepo *repository.CustomerRepository, segmentRepo *repository.SegmentRepository) *CustomerImportService {
+	return &CustomerImportService{
+		repo:         repo,
+		customerRepo: customerRepo,
+		segmentRepo:  segmentRepo,
+	}
+}
+
+// SetSegmentEvaluator sets the evaluator that adds imported customers to matching dynamic segments
+func (s *CustomerImportService) SetSegmentEvaluator(evaluator *SegmentEvaluator) {
+	s.segmentEvaluator = evaluator
+}
+
+// SetPublisher sets the publisher for customer created and updated events
+func (s *CustomerImportService) SetPublisher(publisher CustomerEventPublisher) {
+	s.publisher = publisher
+}
+
+// CreateImportJobRequest describes an uploaded customer import
+type CreateImportJobRequest struct {
+	TenantID              string
+	CreatedBy             string
+	FileName              string
+	Format                models.CustomerImportFormat
+	Data                  []byte
+	Mapping               map[string]string // Normalized with NormalizeColumnMapping
+	DuplicateStrategy     models.DuplicateStrategy
+	SegmentIDs            []uuid.UUID
+	Tags                  []string
+	DefaultMarketingOptIn bool
+	MarkEmailVerified     bool
+}
+
+// PreviewImport shows how the first rows of a file would be mapped and which of them would
+// fail validation, without importing anything
+func (s *CustomerImportService) PreviewImport(format models.CustomerImportFormat, data []byte, explicit map[string]string, limit int) (*models.CustomerImportPreview, error) {
+	total, err := countImportRows(format, data)
+	if err != nil {
+		return nil, &ImportValidationError{Code: "PARSE_ERROR", Message: err.Error()}
+	}
+
+	reader, err := newImportRowReader(format, data)
+	if err != nil {
+		return nil, &ImportValidationError{Code: "PARSE_ERROR", Message: err.Error()}
+	}
+	defer reader.Close()
+
+	rows, err := reader.ReadBatch(limit)
+	if err != nil {
+		return nil, &ImportValidationError{Code: "PARSE_ERROR", Message: err.Error()}
+	}
+
+	mapping := resolveColumnMapping(reader.headers, explicit)
+	preview := &models.CustomerImportPreview{
+		SourceHeaders:    reader.headers,
+		SuggestedMapping: SuggestColumnMapping(reader.headers),
+		AppliedMapping:   mapping,
+		TotalRows:        total,
+		Rows:             make([]map[string]string, 0, len(rows)),
+		Errors:           make([]models.CustomerImportError, 0),
+	}
+
+	mapped := make(map[string]bool)
+	for _, header := range reader.headers {
+		if column, ok := mapping[header]; ok {
+			mapped[column] = true
+		} else if header != "" {
+			preview.UnmappedHeaders = append(preview.UnmappedHeaders, header)
+		}
+	}
+	for _, col := range models.CustomerImportColumns {
+		if col.Required && !mapped[col.Name] {
+			preview.MissingRequired = append(preview.MissingRequired, col.Name)
+		}
+	}
+
+	for _, row := range rows {
+		row = applyColumnMapping(row, mapping)
+		preview.Rows = append(preview.Rows, row)
+		if _, rowErrors := parseImportRow(row); len(rowErrors) > 0 {
+			preview.Errors = append(preview.Errors, rowErrors...)
+		} else {
+			preview.ValidRowCount++
+		}
+	}
+	return preview, nil
+}
+
+// CreateImportJob validates an upload and queues it for the import worker
+func (s *CustomerImportService) CreateImportJob(ctx context.Context, req CreateImportJobRequest) (*models.CustomerImportJob, error) {
+	switch req.DuplicateStrategy {
+	case models.DuplicateSkip, models.DuplicateMerge, models.DuplicateUpdate:
+	default:
+		return nil, &ImportValidationError{Code: "INVALID_STRATEGY", Message: "duplicateStrategy must be 'skip', 'merge' or 'update'"}
+	}
+
+	total, err := countImportRows(req.Format, req.Data)
+	if err != nil {
+		return nil, &ImportValidationError{Code: "PARSE_ERROR", Message: err.Error()}
+	}
+	if total == 0 {
+		return nil, &ImportValidationError{Code: "EMPTY_FILE", Message: "The file has no customer rows"}
+	}
+	if total > models.MaxCustomerImportRows {
+		return nil, &ImportValidationError{
+			Code:    "TOO_MANY_ROWS",
+			Message: fmt.Sprintf("The file has %d rows; imports are limited to %d rows", total, models.MaxCustomerImportRows),
+		}
+	}
+
+	segmentIDs := make([]string, 0, len(req.SegmentIDs))
+	for _, id := range req.SegmentIDs {
+		segment, err := s.segmentRepo.GetSegment(ctx, req.TenantID, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, &ImportValidationError{Code: "INVALID_SEGMENT", Message: fmt.Sprintf("Segment %s not found", id)}
+			}
+			return nil, fmt.Errorf("failed to load segment: %w", err)
+		}
+		if segment.IsDynamic {
+			return nil, &ImportValidationError{
+				Code:    "INVALID_SEGMENT",
+				Message: fmt.Sprintf("Segment '%s' is dynamic; customers can only be imported into static segments", segment.Name),
+			}
+		}
+		segmentIDs = append(segmentIDs, id.String())
+	}
+
+	job := &models.CustomerImportJob{
+		TenantID:              req.TenantID,
+		Status:                models.CustomerImportPending,
+		FileName:              req.FileName,
+		Format:                req.Format,
+		FileData:              req.Data,
+		DuplicateStrategy:     req.DuplicateStrategy,
+		SegmentIDs:            segmentIDs,
+		Tags:                  req.Tags,
+		DefaultMarketingOptIn: req.DefaultMarketingOptIn,
+		MarkEmailVerified:     req.MarkEmailVerified,
+		TotalRows:             total,
+	}
+	if req.CreatedBy != "" {
+		job.CreatedBy = &req.CreatedBy
+	}
+	if len(req.Mapping) > 0 {
+		raw, err := json.Marshal(req.Mapping)
+		if err != nil {
+			return nil, err
+		}
+		job.ColumnMapping = models.JSONB(raw)
+	}
+
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to queue import job: %w", err)
+	}
+	return job, nil
+}
+
+// ListImportJobs lists the tenant's import jobs, newest first
+func (s *CustomerImportService) ListImportJobs(ctx context.Context, tenantID string, page, pageSize int) ([]models.CustomerImportJob, int64, error) {
+	return s.repo.List(ctx, tenantID, pageSize, (page-1)*pageSize)
+}
+
+// GetImportJob retrieves one of the tenant's import jobs
+func (s *CustomerImportService) GetImportJob(ctx context.Context, tenantID string, jobID uuid.UUID) (*models.CustomerImportJob, error) {
+	job, err := s.repo.GetByID(ctx, tenantID, jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImportJobNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetImportErrors retrieves the per-row error report of an import job
+func (s *CustomerImportService) GetImportErrors(ctx context.Context, tenantID string, jobID uuid.UUID) ([]models.CustomerImportError, error) {
+	if _, err := s.GetImportJob(ctx, tenantID, jobID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetErrors(ctx, tenantID, jobID)
+}
+
+// ResumeImportJob re-queues a failed import job, which continues after its last committed chunk
+func (s *CustomerImportService) ResumeImportJob(ctx context.Context, tenantID string, jobID uuid.UUID) (*models.CustomerImportJob, error) {
+	if _, err := s.GetImportJob(ctx, tenantID, jobID); err != nil {
+		return nil, err
+	}
+	resumed, err := s.repo.Resume(ctx, tenantID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !resumed {
+		return nil, ErrImportJobNotResumable
+	}
+	return s.GetImportJob(ctx, tenantID, jobID)
+}
+
+// ProcessImportJob imports a claimed job chunk by chunk, committing progress and row errors
+// after every chunk so an interrupted job resumes where it stopped. ctx must be scoped to the
+// job's tenant. It returns ctx.Err() if interrupted by shutdown.
+func (s *CustomerImportService) ProcessImportJob(ctx context.Context, job *models.CustomerImportJob) error {
+	reader, err := newImportRowReader(job.Format, job.FileData)
+	if err != nil {
+		return s.repo.MarkFailed(ctx, job, err.Error())
+	}
+	defer reader.Close()
+
+	if err := reader.Skip(job.NextRow); err != nil && err != io.EOF {
+		return s.repo.MarkFailed(ctx, job, err.Error())
+	}
+
+	var explicit map[string]string
+	if len(job.ColumnMapping) > 0 {
+		if err := json.Unmarshal(job.ColumnMapping, &explicit); err != nil {
+			return s.repo.MarkFailed(ctx, job, "invalid column mapping")
+		}
+	}
+	mapping := resolveColumnMapping(reader.headers, explicit)
+
+	segmentIDs := make([]uuid.UUID, 0, len(job.SegmentIDs))
+	for _, id := range job.SegmentIDs {
+		if parsed, err := uuid.Parse(id); err == nil {
+			segmentIDs = append(segmentIDs, parsed)
+		}
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rows, err := reader.ReadBatch(models.CustomerImportChunkSize)
+		if err != nil {
+			return s.repo.MarkFailed(ctx, job, err.Error())
+		}
+		if len(rows) == 0 {
+			break
+		}
+		for i := range rows {
+			rows[i] = applyColumnMapping(rows[i], mapping)
+		}
+
+		imported, rowErrors, err := s.importChunk(ctx, job, rows)
+		if err != nil {
+			return s.repo.MarkFailed(ctx, job, fmt.Sprintf("failed to import rows %s-%s: %v", rows[0]["_row"], rows[len(rows)-1]["_row"], err))
+		}
+		for _, segmentID := range segmentIDs {
+			if len(imported) == 0 {
+				break
+			}
+			if err := s.segmentRepo.AddCustomersToSegmentManual(ctx, job.TenantID, segmentID, imported); err != nil {
+				return s.repo.MarkFailed(ctx, job, fmt.Sprintf("failed to add customers to segment %s: %v", segmentID, err))
+			}
+		}
+
+		job.NextRow += len(rows)
+		if err := s.repo.SaveChunk(ctx, job, rowErrors); err != nil {
+			// Progress was not committed; the chunk is imported again when the job is resumed
+			return s.repo.MarkFailed(ctx, job, fmt.Sprintf("failed to save progress at row %s: %v", rows[0]["_row"], err))
+		}
+	}
+
+	return s.repo.MarkCompleted(ctx, job)
+}
+
+// importChunk writes one chunk of mapped rows and returns the IDs of the customers it created,
+// merged or updated along with the chunk's row errors
+func (s *CustomerImportService) importChunk(ctx context.Context, job *models.CustomerImportJob, rows []map[string]string) ([]uuid.UUID, []models.CustomerImportError, error) {
+	var rowErrors []models.CustomerImportError
+	parsed := make([]*importedCustomer, len(rows))
+	emails := make([]string, 0, len(rows))
+	for i, row := range rows {
+		customer, errs := parseImportRow(row)
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			job.FailedCount++
+			continue
+		}
+		parsed[i] = customer
+		emails = append(emails, customer.email)
+	}
+
+	existing, err := s.customerRepo.GetByEmails(ctx, job.TenantID, emails)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var imported []uuid.UUID
+	for _, row := range parsed {
+		if row == nil {
+			continue
+		}
+
+		customer := existing[row.email]
+		created := customer == nil
+		if !created && job.DuplicateStrategy == models.DuplicateSkip {
+			job.SkippedCount++
+			rowErrors = append(rowErrors, row.rowError("email", "DUPLICATE", "A customer with this email already exists; skipped"))
+			continue
+		}
+
+		if created {
+			customer = row.newCustomer(job)
+			err = s.customerRepo.Create(ctx, customer)
+		} else {
+			row.applyTo(customer, job)
+			err = s.customerRepo.Update(ctx, customer)
+		}
+		if err != nil {
+			job.FailedCount++
+			rowErrors = append(rowErrors, row.rowError("", "WRITE_FAILED", fmt.Sprintf("Failed to save customer: %v", err)))
+			continue
+		}
+		existing[row.email] = customer
+
+		if row.address != nil {
+			if err := s.addAddress(ctx, customer, row.address); err != nil {
+				rowErrors = append(rowErrors, row.rowError("addressLine1", "ADDRESS_FAILED", fmt.Sprintf("Customer saved but the address was not: %v", err)))
+			}
+		}
+
+		if created {
+			job.CreatedCount++
+		} else {
+			job.UpdatedCount++
+		}
+		imported = append(imported, customer.ID)
+		s.afterImport(ctx, customer, created)
+	}
+	return imported, rowErrors, nil
+}
+
+// addAddress gives a customer the row's address as their default, unless they already have one
+func (s *CustomerImportService) addAddress(ctx context.Context, customer *models.Customer, address *models.CustomerAddress) error {
+	addresses, err := s.customerRepo.GetAddresses(ctx, customer.TenantID, customer.ID)
+	if err != nil {
+		return err
+	}
+	if len(addresses) > 0 {
+		return nil
+	}
+	address.CustomerID = customer.ID
+	address.TenantID = customer.TenantID
+	address.IsDefault = true
+	return s.customerRepo.AddAddress(ctx, address)
+}
+
+// afterImport publishes the customer's event and updates their dynamic segment memberships
+func (s *CustomerImportService) afterImport(ctx context.Context, customer *models.Customer, created bool) {
+	if s.publisher != nil {
+		var err error
+		if created {
+			err = s.publisher.PublishCustomerCreated(ctx, customer, customer.TenantID)
+		} else {
+			err = s.publisher.PublishCustomerUpdated(ctx, customer, customer.TenantID)
+		}
+		if err != nil {
+			log.Printf("[CustomerImport] Failed to publish event for customer %s: %v", customer.ID, err)
+		}
+	}
+	if s.segmentEvaluator != nil {
+		if err := s.segmentEvaluator.EvaluateCustomerSegments(ctx, customer); err != nil {
+			log.Printf("[CustomerImport] Failed to evaluate segments for customer %s: %v", customer.ID, err)
+		}
+	}
+}
+
+// importedCustomer is a validated import row
+type importedCustomer struct {
+	row            int
+	email          string // Lower-cased
+	firstName      string
+	lastName       string
+	phone          string
+	dateOfBirth    *time.Time
+	country        string
+	countryCode    string
+	customerType   models.CustomerType
+	tags           []string
+	notes          string
+	marketingOptIn *bool // nil when the row leaves it blank
+	address        *models.CustomerAddress
+}
+
+// parseImportRow validates a mapped row and converts it into an importedCustomer
+func parseImportRow(row map[string]string) (*importedCustomer, []models.CustomerImportError) {
+	rowNum, _ := strconv.Atoi(row["_row"])
+	c := &importedCustomer{
+		row:         rowNum,
+		email:       strings.ToLower(row["email"]),
+		firstName:   row["firstName"],
+		lastName:    row["lastName"],
+		phone:       row["phone"],
+		country:     row["country"],
+		countryCode: strings.ToUpper(row["countryCode"]),
+		notes:       row["notes"],
+	}
+
+	var errs []models.CustomerImportError
+	invalid := func(column, code, message string) {
+		errs = append(errs, c.rowError(column, code, message))
+	}
+
+	for _, col := range models.CustomerImportColumns {
+		if col.Required && row[col.Name] == "" {
+			invalid(col.Name, "REQUIRED", fmt.Sprintf("%s is required", col.Name))
+		}
+	}
+	if c.email != "" {
+		if addr, err := mail.ParseAddress(c.email); err != nil || addr.Address != c.email {
+			invalid("email", "INVALID", "email must be a valid email address")
+		}
+	}
+	if len(c.firstName) > 100 {
+		invalid("firstName", "INVALID", "firstName must be at most 100 characters")
+	}
+	if len(c.lastName) > 100 {
+		invalid("lastName", "INVALID", "lastName must be at most 100 characters")
+	}
+	if c.countryCode != "" && len(c.countryCode) != 2 {
+		invalid("countryCode", "INVALID", "countryCode must be a 2-letter ISO code")
+	}
+	if value := row["dateOfBirth"]; value != "" {
+		dob, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			invalid("dateOfBirth", "INVALID", "dateOfBirth must be a date in YYYY-MM-DD format")
+		} else {
+			c.dateOfBirth = &dob
+		}
+	}
+	if value := row["customerType"]; value != "" {
+		c.customerType = models.CustomerType(strings.ToUpper(value))
+		switch c.customerType {
+		case models.CustomerTypeRetail, models.CustomerTypeWholesale, models.CustomerTypeVIP:
+		default:
+			invalid("customerType", "INVALID", "customerType must be RETAIL, WHOLESALE or VIP")
+		}
+	}
+	if value := row["marketingOptIn"]; value != "" {
+		optIn, err := parseImportBool(value)
+		if err != nil {
+			invalid("marketingOptIn", "INVALID", "marketingOptIn must be true or false")
+		} else {
+			c.marketingOptIn = &optIn
+		}
+	}
+	if value := row["tags"]; value != "" {
+		c.tags = splitImportList(value)
+	}
+
+	if row["addressLine1"] != "" {
+		addressCountry := strings.ToUpper(row["addressCountry"])
+		if addressCountry == "" {
+			addressCountry = c.countryCode
+		}
+		switch {
+		case row["city"] == "":
+			invalid("city", "REQUIRED", "city is required with an address")
+		case row["postalCode"] == "":
+			invalid("postalCode", "REQUIRED", "postalCode is required with an address")
+		case len(addressCountry) != 2:
+			invalid("addressCountry", "INVALID", "addressCountry or countryCode must be a 2-letter ISO code with an address")
+		default:
+			c.address = &models.CustomerAddress{
+				AddressType:  models.AddressTypeBoth,
+				FirstName:    c.firstName,
+				LastName:     c.lastName,
+				Company:      row["company"],
+				AddressLine1: row["addressLine1"],
+				AddressLine2: row["addressLine2"],
+				City:         row["city"],
+				State:        row["state"],
+				PostalCode:   row["postalCode"],
+				Country:      addressCountry,
+				Phone:        c.phone,
+			}
+		}
+	}
+
+	return c, errs
+}
+
+// newCustomer builds a customer from the row. A blank marketingOptIn takes the job's default.
+func (c *importedCustomer) newCustomer(job *models.CustomerImportJob) *models.Customer {
+	customer := &models.Customer{
+		TenantID:       job.TenantID,
+		Email:          c.email,
+		FirstName:      c.firstName,
+		LastName:       c.lastName,
+		Phone:          c.phone,
+		DateOfBirth:    c.dateOfBirth,
+		Country:        c.country,
+		CountryCode:    c.countryCode,
+		Status:         models.CustomerStatusActive,
+		CustomerType:   c.customerType,
+		Tags:           mergeTags(c.tags, job.Tags),
+		Notes:          c.notes,
+		MarketingOptIn: job.DefaultMarketingOptIn,
+		EmailVerified:  job.MarkEmailVerified,
+	}
+	if customer.CustomerType == "" {
+		customer.CustomerType = models.CustomerTypeRetail
+	}
+	if c.marketingOptIn != nil {
+		customer.MarketingOptIn = *c.marketingOptIn
+	}
+	return customer
+}
+
+// applyTo writes the row onto an existing customer. Merging only fills empty fields, while
+// updating overwrites fields the row has values for. Both add the row's and the job's tags,
+// and both apply an explicit marketingOptIn, since it is the customer's latest consent.
+func (c *importedCustomer) applyTo(customer *models.Customer, job *models.CustomerImportJob) {
+	overwrite := job.DuplicateStrategy == models.DuplicateUpdate
+	set := func(field *string, value string) {
+		if value != "" && (overwrite || *field == "") {
+			*field = value
+		}
+	}
+
+	set(&customer.FirstName, c.firstName)
+	set(&customer.LastName, c.lastName)
+	set(&customer.Phone, c.phone)
+	set(&customer.Country, c.country)
+	set(&customer.CountryCode, c.countryCode)
+	set(&customer.Notes, c.notes)
+	if c.dateOfBirth != nil && (overwrite || customer.DateOfBirth == nil) {
+		customer.DateOfBirth = c.dateOfBirth
+	}
+	if c.customerType != "" && overwrite {
+		customer.CustomerType = c.customerType
+	}
+	if c.marketingOptIn != nil {
+		customer.MarketingOptIn = *c.marketingOptIn
+	}
+	if job.MarkEmailVerified {
+		customer.EmailVerified = true
+	}
+	customer.Tags = mergeTags(customer.Tags, c.tags, job.Tags)
+}
+
+func (c *importedCustomer) rowError(column, code, message string) models.CustomerImportError {
+	return models.CustomerImportError{
+		Row:     c.row,
+		Email:   c.email,
+		Column:  column,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// mergeTags combines tag lists, dropping case-insensitive duplicates
+func mergeTags(lists ...[]string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, list := range lists {
+		for _, tag := range list {
+			key := strings.ToLower(tag)
+			if tag == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -10,6 +10,7 @@ import (
 	"customers-service/internal/clients"
 	"customers-service/internal/models"
 	"customers-service/internal/repository"
+	"customers-service/internal/tenancy"
 )
 
 // CustomerService handles customer business logic
@@ -18,6 +19,7 @@ type CustomerService struct {
 	notificationClient *clients.NotificationClient
 	tenantClient       *clients.TenantClient
 	segmentEvaluator   *SegmentEvaluator
+	securityEvents     *SecurityEventService
 }
 
 // NewCustomerService creates a new customer service
@@ -34,6 +36,11 @@ func (s *CustomerService) SetSegmentEvaluator(evaluator *SegmentEvaluator) {
 	s.segmentEvaluator = evaluator
 }
 
+// SetSecurityEvents sets the service payment method changes are recorded with (optional)
+func (s *CustomerService) SetSecurityEvents(securityEvents *SecurityEventService) {
+	s.securityEvents = securityEvents
+}
+
 // CreateCustomerRequest represents request to create a customer
 // Note: TenantID is NOT binding:required because it comes from the X-Tenant-ID header,
 // not from the request body. The handler must extract it from context.
@@ -118,7 +125,7 @@ func (s *CustomerService) CreateCustomer(ctx context.Context, req CreateCustomer
 	// Evaluate dynamic segments for the new customer (non-blocking)
 	if s.segmentEvaluator != nil {
 		go func() {
-			evalCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			evalCtx, cancel := context.WithTimeout(tenancy.WithTenant(context.Background(), customer.TenantID), 30*time.Second)
 			defer cancel()
 			if err := s.segmentEvaluator.EvaluateCustomerSegments(evalCtx, customer); err != nil {
 				log.Printf("[CustomerService] Failed to evaluate segments for new customer: %v", err)
@@ -164,7 +171,7 @@ func (s *CustomerService) CreateFromEvent(ctx context.Context, customer *models.
 	// Evaluate dynamic segments for the new customer (non-blocking)
 	if s.segmentEvaluator != nil {
 		go func() {
-			evalCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			evalCtx, cancel := context.WithTimeout(tenancy.WithTenant(context.Background(), customer.TenantID), 30*time.Second)
 			defer cancel()
 			if err := s.segmentEvaluator.EvaluateCustomerSegments(evalCtx, customer); err != nil {
 				log.Printf("[CustomerService] Failed to evaluate segments for customer from event: %v", err)
@@ -205,6 +212,9 @@ type UpdateCustomerRequest struct {
 	MarketingOptIn *bool                  `json:"marketingOptIn"`
 	Tags           []string               `json:"tags"`
 	Notes          *string                `json:"notes"`
+	// ExpectedVersion rejects the update if the customer changed since it was read.
+	// The handler fills it from the If-Match header when present.
+	ExpectedVersion *int `json:"expectedVersion"`
 }
 
 // UpdateCustomer updates a customer
@@ -214,6 +224,10 @@ func (s *CustomerService) UpdateCustomer(ctx context.Context, tenantID string, c
 		return nil, err
 	}
 
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != customer.Version {
+		return nil, &models.VersionConflictError{CurrentVersion: customer.Version}
+	}
+
 	// Update fields if provided
 	if req.FirstName != nil {
 		customer.FirstName = *req.FirstName
@@ -259,7 +273,7 @@ func (s *CustomerService) UpdateCustomer(ctx context.Context, tenantID string, c
 	// Re-evaluate dynamic segments after customer update (non-blocking)
 	if s.segmentEvaluator != nil {
 		go func() {
-			evalCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			evalCtx, cancel := context.WithTimeout(tenancy.WithTenant(context.Background(), customer.TenantID), 30*time.Second)
 			defer cancel()
 			if err := s.segmentEvaluator.EvaluateCustomerSegments(evalCtx, customer); err != nil {
 				log.Printf("[CustomerService] Failed to evaluate segments for updated customer: %v", err)
@@ -281,6 +295,7 @@ type ListCustomersRequest struct {
 	PageSize     int                    `json:"pageSize"`
 	SortBy       string                 `json:"sortBy"`
 	SortOrder    string                 `json:"sortOrder"`
+	Include      []string               `json:"include"` // Relations to load with each customer
 }
 
 // ListCustomersResponse represents response for listing customers
@@ -314,6 +329,7 @@ func (s *CustomerService) ListCustomers(ctx context.Context, req ListCustomersRe
 		Offset:       offset,
 		SortBy:       req.SortBy,
 		SortOrder:    req.SortOrder,
+		Include:      req.Include,
 	}
 
 	customers, total, err := s.repo.List(ctx, filter)
@@ -340,6 +356,40 @@ func (s *CustomerService) DeleteCustomer(ctx context.Context, tenantID string, c
 	return s.repo.Delete(ctx, tenantID, customerID)
 }
 
+// ListDeletedCustomers lists soft-deleted customers with pagination
+func (s *CustomerService) ListDeletedCustomers(ctx context.Context, tenantID string, page, pageSize int) (*ListCustomersResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	customers, total, err := s.repo.ListDeleted(ctx, tenantID, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted customers: %w", err)
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &ListCustomersResponse{
+		Customers:  customers,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// RestoreCustomer restores a soft-deleted customer
+// Returns nil without error if the customer is not in the trash
+func (s *CustomerService) RestoreCustomer(ctx context.Context, tenantID string, customerID uuid.UUID) (*models.Customer, error) {
+	return s.repo.Restore(ctx, tenantID, customerID)
+}
+
 // RecordOrder records order information for a customer and returns updated customer
 func (s *CustomerService) RecordOrder(ctx context.Context, tenantID string, customerID uuid.UUID, orderTotal float64) (*models.Customer, error) {
 	customer, err := s.repo.GetByID(ctx, tenantID, customerID)
@@ -464,9 +514,92 @@ func (s *CustomerService) GetPaymentMethods(ctx context.Context, tenantID string
 	return s.repo.GetPaymentMethods(ctx, tenantID, customerID)
 }
 
-// DeletePaymentMethod deletes a payment method
-func (s *CustomerService) DeletePaymentMethod(ctx context.Context, tenantID string, methodID uuid.UUID) error {
-	return s.repo.DeletePaymentMethod(ctx, tenantID, methodID)
+// AddPaymentMethodRequest represents a request to save a customer's payment method
+type AddPaymentMethodRequest struct {
+	PaymentGateway         string             `json:"paymentGateway" binding:"required,max=50"`
+	GatewayPaymentMethodID string             `json:"gatewayPaymentMethodId" binding:"required,max=255"`
+	PaymentType            models.PaymentType `json:"paymentType" binding:"required,oneof=card bank_account paypal upi"`
+	CardBrand              string             `json:"cardBrand" binding:"max=20"`
+	LastFour               string             `json:"lastFour" binding:"omitempty,len=4,numeric"`
+	ExpiryMonth            int                `json:"expiryMonth" binding:"omitempty,min=1,max=12"`
+	ExpiryYear             int                `json:"expiryYear"`
+	IsDefault              bool               `json:"isDefault"`
+}
+
+// AddPaymentMethod saves a payment method for a customer and records it in their security log.
+// ipAddress and userAgent describe the client the request came from.
+func (s *CustomerService) AddPaymentMethod(ctx context.Context, tenantID string, customerID uuid.UUID, req AddPaymentMethodRequest, ipAddress, userAgent string) (*models.CustomerPaymentMethod, error) {
+	customer, err := s.repo.GetByID(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("customer not found: %w", err)
+	}
+
+	method := &models.CustomerPaymentMethod{
+		CustomerID:             customer.ID,
+		TenantID:               tenantID,
+		PaymentGateway:         req.PaymentGateway,
+		GatewayPaymentMethodID: req.GatewayPaymentMethodID,
+		PaymentType:            req.PaymentType,
+		CardBrand:              req.CardBrand,
+		LastFour:               req.LastFour,
+		ExpiryMonth:            req.ExpiryMonth,
+		ExpiryYear:             req.ExpiryYear,
+		IsDefault:              req.IsDefault,
+		IsActive:               true,
+	}
+	if err := s.repo.AddPaymentMethod(ctx, method); err != nil {
+		return nil, fmt.Errorf("failed to add payment method: %w", err)
+	}
+
+	s.recordPaymentMethodActivity(ctx, tenantID, customer, models.SecurityEventPaymentMethodAdded, method, ipAddress, userAgent)
+	return method, nil
+}
+
+// DeletePaymentMethod deletes one of a customer's payment methods and records it in their
+// security log. ipAddress and userAgent describe the client the request came from.
+func (s *CustomerService) DeletePaymentMethod(ctx context.Context, tenantID string, customerID, methodID uuid.UUID, ipAddress, userAgent string) error {
+	method, err := s.repo.GetPaymentMethod(ctx, tenantID, customerID, methodID)
+	if err != nil {
+		return fmt.Errorf("payment method not found: %w", err)
+	}
+	if err := s.repo.DeletePaymentMethod(ctx, tenantID, customerID, methodID); err != nil {
+		return err
+	}
+
+	if s.securityEvents != nil {
+		customer, err := s.repo.GetByID(ctx, tenantID, customerID)
+		if err != nil {
+			log.Printf("[CustomerService] Failed to load customer %s to record payment method removal: %v", customerID, err)
+			return nil
+		}
+		s.recordPaymentMethodActivity(ctx, tenantID, customer, models.SecurityEventPaymentMethodRemoved, method, ipAddress, userAgent)
+	}
+	return nil
+}
+
+// recordPaymentMethodActivity records a payment method change in the customer's security log.
+// The change has already been made, so failures are logged rather than returned.
+func (s *CustomerService) recordPaymentMethodActivity(ctx context.Context, tenantID string, customer *models.Customer, eventType models.SecurityEventType, method *models.CustomerPaymentMethod, ipAddress, userAgent string) {
+	if s.securityEvents == nil {
+		return
+	}
+
+	description := string(method.PaymentType)
+	if method.CardBrand != "" {
+		description = method.CardBrand
+	}
+	if method.LastFour != "" {
+		description += " ending " + method.LastFour
+	}
+	_, err := s.securityEvents.Record(ctx, tenantID, customer, SecurityActivity{
+		Type:        eventType,
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
+		Description: description,
+	})
+	if err != nil {
+		log.Printf("[CustomerService] Failed to record %s for customer %s: %v", eventType, customer.ID, err)
+	}
 }
 
 // GenerateVerificationToken generates a verification token for a customer
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"customers-service/internal/models"
+)
+
+const (
+	// maxLineItemProperties bounds the properties on one cart line
+	maxLineItemProperties = 20
+	// maxLineItemPropertyNameLength bounds property names
+	maxLineItemPropertyNameLength = 100
+	// defaultLineItemPropertyValueLength bounds values when a rule doesn't set its own limit
+	defaultLineItemPropertyValueLength = 500
+)
+
+// ErrInvalidLineItemProperties is returned when a cart line's properties break its product's rules
+var ErrInvalidLineItemProperties = errors.New("invalid line item properties")
+
+// ValidateLineItemProperties checks each item's properties against its product's rules
+// and normalizes them in place: names and values are trimmed and empty values dropped.
+// Products without rules, or that can't be looked up, accept any properties within the
+// default limits; cart validation flags missing products separately.
+func (s *CartValidationService) ValidateLineItemProperties(ctx context.Context, tenantID string, items []models.CartItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	productIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		if !slices.Contains(productIDs, item.ProductID) {
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+	products, err := s.productsClient.GetProducts(ctx, tenantID, productIDs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch products: %w", err)
+	}
+	rulesByProduct := make(map[string][]models.LineItemPropertyRule, len(products))
+	for _, product := range products {
+		if !product.Found || product.Product == nil {
+			continue
+		}
+		if rules, err := product.Product.LineItemPropertyRules(); err == nil {
+			rulesByProduct[product.ID] = rules
+		}
+	}
+
+	for i := range items {
+		properties, err := checkLineItemProperties(rulesByProduct[items[i].ProductID], items[i].Properties)
+		if err != nil {
+			name := items[i].Name
+			if name == "" {
+				name = items[i].ProductID
+			}
+			return fmt.Errorf("%w: %s: %v", ErrInvalidLineItemProperties, name, err)
+		}
+		items[i].Properties = properties
+	}
+	return nil
+}
+
+// checkLineItemProperties returns the cleaned properties, or why they break the rules
+func checkLineItemProperties(rules []models.LineItemPropertyRule, properties map[string]string) (map[string]string, error) {
+	cleaned := make(map[string]string, len(properties))
+	for name, value := range properties {
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if name == "" || value == "" {
+			continue
+		}
+		if utf8.RuneCountInString(name) > maxLineItemPropertyNameLength {
+			return nil, fmt.Errorf("property name %q is longer than %d characters", name, maxLineItemPropertyNameLength)
+		}
+		cleaned[name] = value
+	}
+	if len(cleaned) > maxLineItemProperties {
+		return nil, fmt.Errorf("at most %d properties are allowed", maxLineItemProperties)
+	}
+
+	if len(rules) == 0 {
+		for name, value := range cleaned {
+			if utf8.RuneCountInString(value) > defaultLineItemPropertyValueLength {
+				return nil, fmt.Errorf("%s is longer than %d characters", name, defaultLineItemPropertyValueLength)
+			}
+		}
+		return nilIfEmpty(cleaned), nil
+	}
+
+	rulesByName := make(map[string]models.LineItemPropertyRule, len(rules))
+	for _, rule := range rules {
+		rulesByName[rule.Name] = rule
+	}
+	for name, value := range cleaned {
+		rule, ok := rulesByName[name]
+		if !ok {
+			return nil, fmt.Errorf("%s is not a property of this product", name)
+		}
+		label := rule.Label
+		if label == "" {
+			label = rule.Name
+		}
+
+		switch rule.Type {
+		case models.LineItemPropertyTypeSelect:
+			if !slices.Contains(rule.Options, value) {
+				return nil, fmt.Errorf("%s must be one of %s", label, strings.Join(rule.Options, ", "))
+			}
+		case models.LineItemPropertyTypeMultiSelect:
+			var selected []string
+			for _, option := range strings.Split(value, ",") {
+				option = strings.TrimSpace(option)
+				if option == "" || slices.Contains(selected, option) {
+					continue
+				}
+				if !slices.Contains(rule.Options, option) {
+					return nil, fmt.Errorf("%s must be chosen from %s", label, strings.Join(rule.Options, ", "))
+				}
+				selected = append(selected, option)
+			}
+			if len(selected) == 0 {
+				delete(cleaned, name)
+				continue
+			}
+			cleaned[name] = strings.Join(selected, ", ")
+		default:
+			maxLength := rule.MaxLength
+			if maxLength <= 0 {
+				maxLength = defaultLineItemPropertyValueLength
+			}
+			if utf8.RuneCountInString(value) > maxLength {
+				return nil, fmt.Errorf("%s is longer than %d characters", label, maxLength)
+			}
+			// A pattern that doesn't compile is the merchant's mistake, not the shopper's
+			if rule.Pattern != "" {
+				if pattern, err := regexp.Compile(`^(?:` + rule.Pattern + `)$`); err == nil && !pattern.MatchString(value) {
+					return nil, fmt.Errorf("%s is not in the expected format", label)
+				}
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		if _, ok := cleaned[rule.Name]; rule.Required && !ok {
+			label := rule.Label
+			if label == "" {
+				label = rule.Name
+			}
+			return nil, fmt.Errorf("%s is required", label)
+		}
+	}
+	return nilIfEmpty(cleaned), nil
+}
+
+func nilIfEmpty(properties map[string]string) map[string]string {
+	if len(properties) == 0 {
+		return nil
+	}
+	return properties
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"customers-service/internal/models"
+	"customers-service/internal/repository"
+)
+
+const (
+	// loginFailureAlertThreshold is how many failed logins within loginFailureWindow are
+	// treated as an attack on the account
+	loginFailureAlertThreshold = 5
+	loginFailureWindow         = 15 * time.Minute
+)
+
+// SecurityAlertPublisher publishes security alerts for notification and marketing services
+// to email customers. It is implemented by the events publisher, which cannot be imported from here.
+type SecurityAlertPublisher interface {
+	PublishSecurityAlert(ctx context.Context, customer *models.Customer, tenantID string, event *models.CustomerSecurityEvent) error
+}
+
+// SecurityActivity is account activity to record for a customer
+type SecurityActivity struct {
+	Type        models.SecurityEventType
+	IPAddress   string
+	UserAgent   string
+	DeviceID    string // Client-supplied device identifier, if any
+	DeviceType  string
+	Location    string
+	Description string
+	OccurredAt  time.Time
+}
+
+// SecurityEventService records storefront customers' logins and account changes, flags
+// suspicious activity and publishes alerts about it
+type SecurityEventService struct {
+	repo         *repository.SecurityEventRepository
+	customerRepo *repository.CustomerRepository
+	publisher    SecurityAlertPublisher
+}
+
+// NewSecurityEventService creates a new security event service
+func NewSecurityEventService(repo *repository.SecurityEventRepository, customerRepo *repository.CustomerRepository) *SecurityEventService {
+	return &SecurityEventService{
+		repo:         repo,
+		customerRepo: customerRepo,
+	}
+}
+
+// SetPublisher sets the publisher used for security alerts. Without one, events are only recorded.
+func (s *SecurityEventService) SetPublisher(publisher SecurityAlertPublisher) {
+	s.publisher = publisher
+}
+
+// RecordForUser records activity reported for a user account, matched to the tenant's customer
+// by user ID and then by email. Activity for users who aren't customers, such as staff, is ignored.
+func (s *SecurityEventService) RecordForUser(ctx context.Context, tenantID, userID, email string, activity SecurityActivity) error {
+	var customer *models.Customer
+	var err error
+	if id, parseErr := uuid.Parse(userID); parseErr == nil {
+		customer, err = s.customerRepo.GetByUserID(ctx, tenantID, id)
+		if err != nil {
+			return err
+		}
+	}
+	if customer == nil && email != "" {
+		customer, err = s.customerRepo.GetByEmail(ctx, tenantID, email)
+		if err != nil {
+			return err
+		}
+	}
+	if customer == nil {
+		return nil
+	}
+
+	_, err = s.Record(ctx, tenantID, customer, activity)
+	return err
+}
+
+// Record records activity for a customer. A successful login from a device the customer
+// hasn't used before is recorded as a suspicious new device login, unless it's their first
+// device. Reaching the failed login threshold records a suspicious repeated failures event.
+// Alerts are published for everything but routine logins.
+func (s *SecurityEventService) Record(ctx context.Context, tenantID string, customer *models.Customer, activity SecurityActivity) (*models.CustomerSecurityEvent, error) {
+	if activity.OccurredAt.IsZero() {
+		activity.OccurredAt = time.Now()
+	}
+
+	event := &models.CustomerSecurityEvent{
+		TenantID:    tenantID,
+		CustomerID:  customer.ID,
+		Type:        activity.Type,
+		Suspicious:  activity.Type == models.SecurityEventAccountLocked,
+		IPAddress:   activity.IPAddress,
+		UserAgent:   activity.UserAgent,
+		DeviceID:    deviceFingerprint(activity.DeviceID, activity.UserAgent),
+		DeviceType:  activity.DeviceType,
+		Location:    activity.Location,
+		Description: activity.Description,
+		OccurredAt:  activity.OccurredAt,
+	}
+
+	if event.Type == models.SecurityEventLoginSucceeded && event.DeviceID != "" {
+		isNew, known, err := s.repo.TouchDevice(ctx, &models.CustomerDevice{
+			TenantID:    tenantID,
+			CustomerID:  customer.ID,
+			Fingerprint: event.DeviceID,
+			UserAgent:   activity.UserAgent,
+			LastIP:      activity.IPAddress,
+			FirstSeenAt: activity.OccurredAt,
+			LastSeenAt:  activity.OccurredAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record device: %w", err)
+		}
+		if isNew && known > 0 {
+			event.Type = models.SecurityEventNewDeviceLogin
+			event.Suspicious = true
+		}
+	}
+
+	if err := s.repo.Create(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to record security event: %w", err)
+	}
+	s.alert(ctx, customer, event)
+
+	if event.Type == models.SecurityEventLoginFailed {
+		failures, err := s.repo.CountSince(ctx, tenantID, customer.ID, models.SecurityEventLoginFailed, event.OccurredAt.Add(-loginFailureWindow))
+		if err != nil {
+			return nil, err
+		}
+		// Only the attempt that reaches the threshold raises the alert, not every one after it
+		if failures == loginFailureAlertThreshold {
+			repeated := *event
+			repeated.ID = uuid.Nil
+			repeated.Type = models.SecurityEventRepeatedLoginFailures
+			repeated.Suspicious = true
+			repeated.Description = fmt.Sprintf("%d failed logins within %s", failures, loginFailureWindow)
+			if err := s.repo.Create(ctx, &repeated); err != nil {
+				return nil, fmt.Errorf("failed to record security event: %w", err)
+			}
+			s.alert(ctx, customer, &repeated)
+		}
+	}
+
+	return event, nil
+}
+
+// ListEvents retrieves a customer's security events, most recent first
+func (s *SecurityEventService) ListEvents(ctx context.Context, tenantID string, customerID uuid.UUID, filter repository.SecurityEventFilter, page, pageSize int) ([]models.CustomerSecurityEvent, int64, error) {
+	return s.repo.List(ctx, tenantID, customerID, filter, page, pageSize)
+}
+
+// alert publishes a security alert, if the event is one the customer should hear about
+func (s *SecurityEventService) alert(ctx context.Context, customer *models.Customer, event *models.CustomerSecurityEvent) {
+	if s.publisher == nil || !event.Type.IsAlert() {
+		return
+	}
+	if err := s.publisher.PublishSecurityAlert(ctx, customer, event.TenantID, event); err != nil {
+		log.Printf("[SecurityEventService] Failed to publish %s alert for customer %s: %v", event.Type, customer.ID, err)
+	}
+}
+
+// deviceFingerprint identifies the device a login came from: the client's device ID if it sent
+// one, otherwise a hash of its user agent. Empty if there is nothing to go on.
+func deviceFingerprint(deviceID, userAgent string) string {
+	source := strings.TrimSpace(deviceID)
+	if source == "" {
+		source = strings.ToLower(strings.TrimSpace(userAgent))
+	}
+	if source == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
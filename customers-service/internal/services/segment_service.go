@@ -128,3 +128,71 @@ func (s *SegmentService) RemoveCustomersFromSegment(ctx context.Context, tenantI
 func (s *SegmentService) GetSegmentCustomers(ctx context.Context, tenantID string, segmentID uuid.UUID) ([]models.Customer, error) {
 	return s.repo.GetSegmentCustomers(ctx, tenantID, segmentID)
 }
+
+// maxSegmentAudiencePage bounds a page of a segment's audience
+const maxSegmentAudiencePage = 1000
+
+// AudienceMember is a segment member's contact details for export to marketing platforms
+type AudienceMember struct {
+	CustomerID  uuid.UUID `json:"customerId"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone,omitempty"`
+	FirstName   string    `json:"firstName,omitempty"`
+	LastName    string    `json:"lastName,omitempty"`
+	CountryCode string    `json:"countryCode,omitempty"`
+}
+
+// GetSegmentAudience returns a page of the segment's members that can be marketed to:
+// active customers who opted in. Pages follow customer ID; pass the last ID to continue.
+func (s *SegmentService) GetSegmentAudience(ctx context.Context, tenantID string, segmentID, after uuid.UUID, limit int) ([]AudienceMember, error) {
+	if _, err := s.repo.GetSegment(ctx, tenantID, segmentID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxSegmentAudiencePage {
+		limit = maxSegmentAudiencePage
+	}
+	customers, err := s.repo.GetSegmentAudience(ctx, tenantID, segmentID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]AudienceMember, 0, len(customers))
+	for _, customer := range customers {
+		members = append(members, AudienceMember{
+			CustomerID:  customer.ID,
+			Email:       customer.Email,
+			Phone:       customer.Phone,
+			FirstName:   customer.FirstName,
+			LastName:    customer.LastName,
+			CountryCode: customer.CountryCode,
+		})
+	}
+	return members, nil
+}
+
+// CustomerTargeting is what coupons-service needs to check a customer against coupon
+// targeting: their segments and whether they have purchased before
+type CustomerTargeting struct {
+	CustomerID  uuid.UUID   `json:"customerId"`
+	SegmentIDs  []uuid.UUID `json:"segmentIds"`
+	TotalOrders int         `json:"totalOrders"`
+}
+
+// GetCustomerTargeting returns a customer's segments and order count
+func (s *SegmentService) GetCustomerTargeting(ctx context.Context, tenantID string, customerID uuid.UUID) (*CustomerTargeting, error) {
+	totalOrders, err := s.repo.GetCustomerTotalOrders(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	segmentIDs, err := s.repo.GetCustomerSegmentIDs(ctx, tenantID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if segmentIDs == nil {
+		segmentIDs = []uuid.UUID{}
+	}
+	return &CustomerTargeting{
+		CustomerID:  customerID,
+		SegmentIDs:  segmentIDs,
+		TotalOrders: totalOrders,
+	}, nil
+}
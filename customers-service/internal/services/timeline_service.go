@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/Tesseract-Nexus/go-shared/cache"
+	"customers-service/internal/clients"
+	"customers-service/internal/models"
+	"customers-service/internal/repository"
+)
+
+const (
+	// TimelineCacheTTL is how long each service's part of a timeline is cached
+	TimelineCacheTTL = 2 * time.Minute
+	// timelineSourceLimit is how many recent records are read from each source
+	timelineSourceLimit = 50
+)
+
+// ErrTimelineCustomerNotFound is returned for a timeline of an unknown customer
+var ErrTimelineCustomerNotFound = errors.New("customer not found")
+
+// TimelineService builds the unified customer timeline for the support agent panel by
+// reading orders, tickets, reviews and loyalty transactions from their services in parallel
+// and merging them with the customer's communications. A service that fails leaves its
+// entries out and marks the timeline partial rather than failing it.
+type TimelineService struct {
+	repo   *repository.CustomerRepository
+	client *clients.TimelineClient
+	cache  *cache.CacheLayer
+}
+
+// NewTimelineService creates a new timeline service. Without Redis, nothing is cached.
+func NewTimelineService(repo *repository.CustomerRepository, client *clients.TimelineClient, redisClient *redis.Client) *TimelineService {
+	s := &TimelineService{repo: repo, client: client}
+	if redisClient != nil {
+		s.cache = cache.NewCacheLayerFromClient(redisClient, cache.CacheConfig{
+			L1Enabled:  true,
+			L1MaxItems: 1000,
+			L1TTL:      30 * time.Second,
+			DefaultTTL: TimelineCacheTTL,
+			KeyPrefix:  "tesseract:customers:",
+		})
+	}
+	return s
+}
+
+// GetTimeline returns up to limit of the customer's most recent timeline entries. headers
+// are forwarded to the other services so the agent's own permissions apply there.
+func (s *TimelineService) GetTimeline(ctx context.Context, tenantID string, customerID uuid.UUID, headers http.Header, limit int) (*models.CustomerTimeline, error) {
+	customer, err := s.repo.GetByID(ctx, tenantID, customerID)
+	if err != nil || customer == nil {
+		return nil, ErrTimelineCustomerNotFound
+	}
+
+	id := customerID.String()
+	userID := ""
+	if customer.UserID != nil {
+		userID = customer.UserID.String()
+	}
+
+	sources := map[string]func() ([]models.TimelineEntry, error){
+		models.TimelineSourceOrders: func() ([]models.TimelineEntry, error) {
+			orders, err := s.client.ListOrders(ctx, headers, id, timelineSourceLimit)
+			return orderEntries(orders), err
+		},
+		models.TimelineSourceLoyalty: func() ([]models.TimelineEntry, error) {
+			txns, err := s.client.ListLoyaltyTransactions(ctx, headers, id, timelineSourceLimit)
+			return loyaltyEntries(txns), err
+		},
+	}
+	// Tickets and reviews belong to the customer's user account; guests have neither
+	if userID != "" {
+		sources[models.TimelineSourceTickets] = func() ([]models.TimelineEntry, error) {
+			tickets, err := s.client.ListTickets(ctx, headers, userID, timelineSourceLimit)
+			return ticketEntries(tickets), err
+		}
+		sources[models.TimelineSourceReviews] = func() ([]models.TimelineEntry, error) {
+			reviews, err := s.client.ListReviews(ctx, headers, userID, timelineSourceLimit)
+			return reviewEntries(reviews), err
+		}
+	}
+
+	timeline := &models.CustomerTimeline{
+		CustomerID: id,
+		Entries:    []models.TimelineEntry{},
+		Sources: map[string]string{
+			models.TimelineSourceTickets: models.TimelineSourceSkipped,
+			models.TimelineSourceReviews: models.TimelineSourceSkipped,
+		},
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for source, load := range sources {
+		wg.Add(1)
+		go func(source string, load func() ([]models.TimelineEntry, error)) {
+			defer wg.Done()
+			entries, err := s.cached(ctx, tenantID, id, source, load)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("[TimelineService] Failed to load %s for customer %s: %v", source, id, err)
+				timeline.Sources[source] = models.TimelineSourceUnavailable
+				timeline.Partial = true
+				return
+			}
+			timeline.Sources[source] = models.TimelineSourceOK
+			timeline.Entries = append(timeline.Entries, entries...)
+		}(source, load)
+	}
+
+	comms, commsErr := s.repo.GetCommunications(ctx, tenantID, customerID, timelineSourceLimit)
+	wg.Wait()
+
+	if commsErr != nil {
+		log.Printf("[TimelineService] Failed to load communications for customer %s: %v", id, commsErr)
+		timeline.Sources[models.TimelineSourceCommunications] = models.TimelineSourceUnavailable
+		timeline.Partial = true
+	} else {
+		timeline.Sources[models.TimelineSourceCommunications] = models.TimelineSourceOK
+		timeline.Entries = append(timeline.Entries, communicationEntries(comms)...)
+	}
+
+	sort.SliceStable(timeline.Entries, func(i, j int) bool {
+		return timeline.Entries[i].OccurredAt.After(timeline.Entries[j].OccurredAt)
+	})
+	if limit > 0 && len(timeline.Entries) > limit {
+		timeline.Entries = timeline.Entries[:limit]
+	}
+	return timeline, nil
+}
+
+// cached returns a source's entries from the cache, loading and caching them on a miss.
+// Failed loads are not cached.
+func (s *TimelineService) cached(ctx context.Context, tenantID, customerID, source string, load func() ([]models.TimelineEntry, error)) ([]models.TimelineEntry, error) {
+	if s.cache == nil {
+		return load()
+	}
+
+	key := fmt.Sprintf("timeline:%s:%s:%s", tenantID, customerID, source)
+	var entries []models.TimelineEntry
+	if err := s.cache.GetJSON(ctx, key, &entries); err == nil {
+		return entries, nil
+	}
+
+	entries, err := load()
+	if err != nil {
+		return nil, err
+	}
+	_ = s.cache.SetJSON(ctx, key, entries, TimelineCacheTTL)
+	return entries, nil
+}
+
+func orderEntries(orders []clients.TimelineOrder) []models.TimelineEntry {
+	entries := make([]models.TimelineEntry, 0, len(orders))
+	for _, o := range orders {
+		total := o.Total
+		entries = append(entries, models.TimelineEntry{
+			Type:       models.TimelineEntryOrder,
+			ID:         o.ID,
+			OccurredAt: o.CreatedAt,
+			Title:      "Order " + o.OrderNumber,
+			Status:     o.Status,
+			Amount:     &total,
+			Currency:   o.Currency,
+		})
+
+		if p := o.Payment; p != nil {
+			amount := p.Amount
+			occurredAt := p.CreatedAt
+			if p.ProcessedAt != nil {
+				occurredAt = *p.ProcessedAt
+			}
+			entries = append(entries, models.TimelineEntry{
+				Type:       models.TimelineEntryPayment,
+				ID:         p.ID,
+				OccurredAt: occurredAt,
+				Title:      fmt.Sprintf("Payment for order %s (%s)", o.OrderNumber, p.Method),
+				Status:     p.Status,
+				Amount:     &amount,
+				Currency:   p.Currency,
+				Reference:  o.ID,
+			})
+		}
+	}
+	return entries
+}
+
+func ticketEntries(tickets []clients.TimelineTicket) []models.TimelineEntry {
+	entries := make([]models.TimelineEntry, 0, len(tickets))
+	for _, t := range tickets {
+		entries = append(entries, models.TimelineEntry{
+			Type:       models.TimelineEntryTicket,
+			ID:         t.ID,
+			OccurredAt: t.CreatedAt,
+			Title:      fmt.Sprintf("Ticket %s: %s", t.TicketNumber, t.Title),
+			Status:     t.Status,
+		})
+	}
+	return entries
+}
+
+func reviewEntries(reviews []clients.TimelineReview) []models.TimelineEntry {
+	entries := make([]models.TimelineEntry, 0, len(reviews))
+	for _, r := range reviews {
+		title := "Review"
+		if r.Title != nil && *r.Title != "" {
+			title = "Review: " + *r.Title
+		}
+		entries = append(entries, models.TimelineEntry{
+			Type:       models.TimelineEntryReview,
+			ID:         r.ID,
+			OccurredAt: r.CreatedAt,
+			Title:      title,
+			Status:     r.Status,
+			Reference:  r.TargetID,
+		})
+	}
+	return entries
+}
+
+func loyaltyEntries(txns []clients.TimelineLoyaltyTransaction) []models.TimelineEntry {
+	entries := make([]models.TimelineEntry, 0, len(txns))
+	for _, t := range txns {
+		points := t.Points
+		title := t.Description
+		if title == "" {
+			title = "Loyalty " + t.Type
+		}
+		entry := models.TimelineEntry{
+			Type:       models.TimelineEntryLoyalty,
+			ID:         t.ID,
+			OccurredAt: t.CreatedAt,
+			Title:      title,
+			Status:     t.Type,
+			Points:     &points,
+		}
+		if t.OrderID != nil {
+			entry.Reference = *t.OrderID
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func communicationEntries(comms []models.CustomerCommunication) []models.TimelineEntry {
+	entries := make([]models.TimelineEntry, 0, len(comms))
+	for _, c := range comms {
+		title := c.Subject
+		if title == "" {
+			title = fmt.Sprintf("%s %s", c.Direction, c.CommunicationType)
+		}
+		entries = append(entries, models.TimelineEntry{
+			Type:       models.TimelineEntryCommunication,
+			ID:         c.ID.String(),
+			OccurredAt: c.CreatedAt,
+			Title:      title,
+			Status:     string(c.Status),
+		})
+	}
+	return entries
+}
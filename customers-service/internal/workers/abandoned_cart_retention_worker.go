@@ -0,0 +1,198 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"customers-service/internal/clients"
+	"customers-service/internal/models"
+	"customers-service/internal/tenancy"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultRetentionPurgeInterval is the default interval for retention purges
+	DefaultRetentionPurgeInterval = 6 * time.Hour
+
+	// RetentionPurgeBatchSize is the number of abandoned carts deleted per batch
+	RetentionPurgeBatchSize = 500
+
+	// AbandonedCartsRetentionResource is the retention resource name for abandoned carts
+	AbandonedCartsRetentionResource = "abandoned_carts"
+)
+
+// AbandonedCartRetentionWorker enforces per-tenant abandoned cart retention policies.
+// Policies are fetched from staff-service; a summary of every purge is reported back.
+type AbandonedCartRetentionWorker struct {
+	db              *gorm.DB
+	retentionClient *clients.RetentionClient
+	interval        time.Duration
+	stopChan        chan struct{}
+	doneChan        chan struct{}
+	mu              sync.Mutex
+	running         bool
+}
+
+// NewAbandonedCartRetentionWorker creates a new abandoned cart retention worker.
+func NewAbandonedCartRetentionWorker(db *gorm.DB, retentionClient *clients.RetentionClient, interval time.Duration) *AbandonedCartRetentionWorker {
+	if interval == 0 {
+		interval = DefaultRetentionPurgeInterval
+	}
+
+	return &AbandonedCartRetentionWorker{
+		db:              db,
+		retentionClient: retentionClient,
+		interval:        interval,
+		stopChan:        make(chan struct{}),
+		doneChan:        make(chan struct{}),
+	}
+}
+
+// Start begins the retention purge loop.
+func (w *AbandonedCartRetentionWorker) Start() {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	go w.run()
+	log.Printf("Abandoned cart retention worker started with interval: %v", w.interval)
+}
+
+// Stop stops the retention purge loop.
+func (w *AbandonedCartRetentionWorker) Stop() {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = false
+	w.mu.Unlock()
+
+	close(w.stopChan)
+	<-w.doneChan
+	log.Println("Abandoned cart retention worker stopped")
+}
+
+// run is the main retention purge loop.
+func (w *AbandonedCartRetentionWorker) run() {
+	defer close(w.doneChan)
+
+	if err := w.purge(context.Background()); err != nil {
+		log.Printf("Initial abandoned cart retention purge failed: %v", err)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopChan:
+			return
+		case <-ticker.C:
+			if err := w.purge(context.Background()); err != nil {
+				log.Printf("Abandoned cart retention purge failed: %v", err)
+			}
+		}
+	}
+}
+
+// purge applies each tenant's retention. If the policies can't be fetched nothing is
+// deleted, since falling back to defaults could discard data a tenant chose to keep longer.
+func (w *AbandonedCartRetentionWorker) purge(ctx context.Context) error {
+	policies, err := w.retentionClient.GetPolicySet(ctx, AbandonedCartsRetentionResource)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var tenantIDs []string
+	if err := w.db.WithContext(ctx).Unscoped().
+		Model(&models.AbandonedCart{}).
+		Where("abandoned_at < ?", now.AddDate(0, 0, -policies.ShortestDays())).
+		Distinct().
+		Pluck("tenant_id", &tenantIDs).Error; err != nil {
+		return err
+	}
+
+	for _, tenantID := range tenantIDs {
+		days, dryRun := policies.PolicyFor(tenantID)
+		run := &clients.RetentionPurgeRun{
+			TenantID:      tenantID,
+			Resource:      AbandonedCartsRetentionResource,
+			RetentionDays: days,
+			Cutoff:        now.AddDate(0, 0, -days),
+			DryRun:        dryRun,
+			StartedAt:     time.Now().UTC(),
+		}
+
+		err := w.db.WithContext(ctx).Unscoped().
+			Model(&models.AbandonedCart{}).
+			Where("tenant_id = ? AND abandoned_at < ?", tenantID, run.Cutoff).
+			Count(&run.MatchedCount).Error
+		if err == nil && run.MatchedCount == 0 {
+			continue
+		}
+		if err == nil && !dryRun {
+			err = w.deleteBefore(tenancy.WithTenant(ctx, tenantID), run)
+		}
+
+		run.Status = "completed"
+		if err != nil {
+			run.Status = "failed"
+			msg := err.Error()
+			run.Error = &msg
+		}
+		run.CompletedAt = time.Now().UTC()
+
+		if err := w.retentionClient.RecordPurgeRun(ctx, run); err != nil {
+			log.Printf("Failed to record abandoned cart purge for tenant %s: %v", tenantID, err)
+		}
+		log.Printf("Abandoned cart retention for tenant %s: %d matched, %d deleted (dry run: %v, status: %s)",
+			tenantID, run.MatchedCount, run.DeletedCount, dryRun, run.Status)
+	}
+
+	return nil
+}
+
+// deleteBefore hard-deletes the tenant's abandoned carts older than the cutoff, with their
+// recovery attempts, in batches so each transaction stays short.
+func (w *AbandonedCartRetentionWorker) deleteBefore(ctx context.Context, run *clients.RetentionPurgeRun) error {
+	for {
+		var deleted int64
+		err := w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var ids []string
+			if err := tx.Unscoped().
+				Model(&models.AbandonedCart{}).
+				Where("tenant_id = ? AND abandoned_at < ?", run.TenantID, run.Cutoff).
+				Limit(RetentionPurgeBatchSize).
+				Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+
+			if err := tx.Where("abandoned_cart_id IN ?", ids).
+				Delete(&models.AbandonedCartRecoveryAttempt{}).Error; err != nil {
+				return err
+			}
+			result := tx.Unscoped().Where("id IN ?", ids).Delete(&models.AbandonedCart{})
+			deleted = result.RowsAffected
+			return result.Error
+		})
+		if err != nil {
+			return err
+		}
+
+		run.DeletedCount += deleted
+		if deleted < RetentionPurgeBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"customers-service/internal/services"
+	"customers-service/internal/tenancy"
+)
+
+// DefaultAccountDeletionInterval is the default interval for account deletion checks
+const DefaultAccountDeletionInterval = 15 * time.Minute
+
+// AccountDeletionWorker starts anonymization for account deletions whose cooling-off period
+// has passed, and re-sends the anonymization request for deletions still waiting on services.
+type AccountDeletionWorker struct {
+	service  *services.AccountDeletionService
+	interval time.Duration
+	stopChan chan struct{}
+	doneChan chan struct{}
+	mu       sync.Mutex
+	running  bool
+}
+
+// NewAccountDeletionWorker creates a new account deletion worker.
+func NewAccountDeletionWorker(service *services.AccountDeletionService, interval time.Duration) *AccountDeletionWorker {
+	if interval == 0 {
+		interval = DefaultAccountDeletionInterval
+	}
+
+	return &AccountDeletionWorker{
+		service:  service,
+		interval: interval,
+		stopChan: make(chan struct{}),
+		doneChan: make(chan struct{}),
+	}
+}
+
+// Start begins the account deletion loop.
+func (w *AccountDeletionWorker) Start() {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	go w.run()
+	log.Printf("Account deletion worker started with interval: %v", w.interval)
+}
+
+// Stop stops the account deletion loop.
+func (w *AccountDeletionWorker) Stop() {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = false
+	w.mu.Unlock()
+
+	close(w.stopChan)
+	<-w.doneChan
+	log.Println("Account deletion worker stopped")
+}
+
+// run is the main account deletion loop.
+func (w *AccountDeletionWorker) run() {
+	defer close(w.doneChan)
+
+	w.process()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopChan:
+			return
+		case <-ticker.C:
+			w.process()
+		}
+	}
+}
+
+// process handles due and stalled deletions across all tenants.
+func (w *AccountDeletionWorker) process() {
+	ctx := tenancy.WithCrossTenant(context.Background())
+	started, republished, err := w.service.ProcessDueDeletions(ctx)
+	if err != nil {
+		log.Printf("Account deletion check failed: %v", err)
+		return
+	}
+	if started > 0 || republished > 0 {
+		log.Printf("Account deletion check: %d deletions started, %d anonymization requests re-sent", started, republished)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"customers-service/internal/models"
+	"customers-service/internal/tenancy"
 	"gorm.io/gorm"
 )
 
@@ -111,8 +112,8 @@ func (w *CartExpirationWorker) Stats() ExpirationStats {
 func (w *CartExpirationWorker) run() {
 	defer close(w.doneChan)
 
-	// Run initial cleanup on startup
-	ctx := context.Background()
+	// Run initial cleanup on startup. The sweep spans all tenants.
+	ctx := tenancy.WithCrossTenant(context.Background())
 	if err := w.processExpiredCarts(ctx); err != nil {
 		log.Printf("Initial cart expiration check failed: %v", err)
 	}
@@ -125,7 +126,7 @@ func (w *CartExpirationWorker) run() {
 		case <-w.stopChan:
 			return
 		case <-ticker.C:
-			ctx := context.Background()
+			ctx := tenancy.WithCrossTenant(context.Background())
 			if err := w.processExpiredCarts(ctx); err != nil {
 				log.Printf("Cart expiration check failed: %v", err)
 				w.mu.Lock()
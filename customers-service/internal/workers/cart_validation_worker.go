@@ -9,6 +9,7 @@ import (
 
 	"customers-service/internal/models"
 	"customers-service/internal/services"
+	"customers-service/internal/tenancy"
 	"gorm.io/gorm"
 )
 
@@ -132,7 +133,7 @@ func (w *CartValidationWorker) run() {
 		case <-w.stopChan:
 			return
 		case <-ticker.C:
-			ctx := context.Background()
+			ctx := tenancy.WithCrossTenant(context.Background())
 			if err := w.validateStaleCarts(ctx); err != nil {
 				log.Printf("Cart validation check failed: %v", err)
 				w.mu.Lock()
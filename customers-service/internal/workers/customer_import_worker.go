@@ -0,0 +1,128 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"customers-service/internal/models"
+	"customers-service/internal/repository"
+	"customers-service/internal/services"
+	"customers-service/internal/tenancy"
+)
+
+// DefaultCustomerImportInterval is how often the import worker checks for queued imports
+const DefaultCustomerImportInterval = 5 * time.Second
+
+// CustomerImportWorker runs queued customer imports one at a time. Jobs are claimed with row
+// locks, so running the worker on every replica is safe.
+type CustomerImportWorker struct {
+	repo     *repository.CustomerImportRepository
+	service  *services.CustomerImportService
+	interval time.Duration
+	ctx      context.Context
+	cancel   context.CancelFunc
+	doneChan chan struct{}
+	mu       sync.Mutex
+	running  bool
+}
+
+// NewCustomerImportWorker creates a new customer import worker.
+func NewCustomerImportWorker(repo *repository.CustomerImportRepository, service *services.CustomerImportService, interval time.Duration) *CustomerImportWorker {
+	if interval == 0 {
+		interval = DefaultCustomerImportInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &CustomerImportWorker{
+		repo:     repo,
+		service:  service,
+		interval: interval,
+		ctx:      ctx,
+		cancel:   cancel,
+		doneChan: make(chan struct{}),
+	}
+}
+
+// Start begins polling for queued imports.
+func (w *CustomerImportWorker) Start() {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	go w.run()
+	log.Printf("Customer import worker started with interval: %v", w.interval)
+}
+
+// Stop stops the worker. A job in progress is handed back to the queue after its current chunk.
+func (w *CustomerImportWorker) Stop() {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = false
+	w.mu.Unlock()
+
+	w.cancel()
+	<-w.doneChan
+	log.Println("Customer import worker stopped")
+}
+
+// run is the main polling loop.
+func (w *CustomerImportWorker) run() {
+	defer close(w.doneChan)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.drainQueue()
+		}
+	}
+}
+
+// drainQueue runs claimed jobs until the queue is empty or the worker is stopping.
+func (w *CustomerImportWorker) drainQueue() {
+	for w.ctx.Err() == nil {
+		job, err := w.repo.ClaimNext(tenancy.WithCrossTenant(w.ctx), models.CustomerImportStaleAfter)
+		if err != nil {
+			log.Printf("Failed to claim customer import job: %v", err)
+			return
+		}
+		if job == nil {
+			return
+		}
+
+		log.Printf("Processing customer import %s for tenant %s (resuming at row %d, attempt %d)",
+			job.ID, job.TenantID, job.NextRow, job.Attempts)
+
+		if err := w.service.ProcessImportJob(tenancy.WithTenant(w.ctx, job.TenantID), job); err != nil {
+			if w.ctx.Err() != nil {
+				// Shutting down mid-job: hand the job back so another replica resumes it
+				if reqErr := w.repo.Requeue(tenancy.WithTenant(context.Background(), job.TenantID), job); reqErr != nil {
+					log.Printf("Failed to requeue customer import %s: %v", job.ID, reqErr)
+				}
+				return
+			}
+			log.Printf("Customer import %s failed: %v", job.ID, err)
+			continue
+		}
+		if job.Status == models.CustomerImportFailed {
+			log.Printf("Customer import %s failed: %s", job.ID, *job.LastError)
+			continue
+		}
+
+		log.Printf("Customer import %s finished: %d created, %d updated, %d skipped, %d failed",
+			job.ID, job.CreatedCount, job.UpdatedCount, job.SkippedCount, job.FailedCount)
+	}
+}
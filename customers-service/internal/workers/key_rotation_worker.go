@@ -0,0 +1,163 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"customers-service/internal/encryption"
+	"customers-service/internal/tenancy"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultKeyRotationInterval is the default interval between key rotation runs
+	DefaultKeyRotationInterval = 24 * time.Hour
+
+	// DefaultKeyRotationAge is how long a tenant data key stays active before it is rotated
+	DefaultKeyRotationAge = 90 * 24 * time.Hour
+)
+
+// KeyRotationWorker rotates tenant data encryption keys older than the rotation age and
+// re-encrypts rows that are still on a retired key, or still in plaintext, with the
+// tenant's active key.
+type KeyRotationWorker struct {
+	db          *gorm.DB
+	keyring     *encryption.Keyring
+	models      []interface{}
+	rotationAge time.Duration
+	interval    time.Duration
+	stopChan    chan struct{}
+	doneChan    chan struct{}
+	mu          sync.Mutex
+	running     bool
+}
+
+// NewKeyRotationWorker creates a new key rotation worker for the given models, which must
+// have a tenant_id column and at least one encrypted field.
+func NewKeyRotationWorker(db *gorm.DB, keyring *encryption.Keyring, rotationAge, interval time.Duration, models ...interface{}) *KeyRotationWorker {
+	if rotationAge == 0 {
+		rotationAge = DefaultKeyRotationAge
+	}
+	if interval == 0 {
+		interval = DefaultKeyRotationInterval
+	}
+
+	return &KeyRotationWorker{
+		db:          db,
+		keyring:     keyring,
+		models:      models,
+		rotationAge: rotationAge,
+		interval:    interval,
+		stopChan:    make(chan struct{}),
+		doneChan:    make(chan struct{}),
+	}
+}
+
+// Start begins the key rotation loop.
+func (w *KeyRotationWorker) Start() {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	go w.run()
+	log.Printf("Key rotation worker started with interval: %v (rotation age: %v)", w.interval, w.rotationAge)
+}
+
+// Stop stops the key rotation loop.
+func (w *KeyRotationWorker) Stop() {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = false
+	w.mu.Unlock()
+
+	close(w.stopChan)
+	<-w.doneChan
+	log.Println("Key rotation worker stopped")
+}
+
+// run is the main key rotation loop.
+func (w *KeyRotationWorker) run() {
+	defer close(w.doneChan)
+
+	if err := w.rotate(context.Background()); err != nil {
+		log.Printf("Initial key rotation failed: %v", err)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopChan:
+			return
+		case <-ticker.C:
+			if err := w.rotate(context.Background()); err != nil {
+				log.Printf("Key rotation failed: %v", err)
+			}
+		}
+	}
+}
+
+// rotate rotates the keys that are due, then re-encrypts every tenant that has retired
+// keys or plaintext rows. A failing tenant is logged and retried on the next run.
+func (w *KeyRotationWorker) rotate(ctx context.Context) error {
+	allTenants := tenancy.WithCrossTenant(ctx)
+
+	due, err := w.keyring.KeysDueForRotation(allTenants, time.Now().Add(-w.rotationAge))
+	if err != nil {
+		return err
+	}
+	for _, key := range due {
+		version, err := w.keyring.Rotate(tenancy.WithTenant(ctx, key.TenantID), key.TenantID)
+		if err != nil {
+			log.Printf("Failed to rotate data key for tenant %s: %v", key.TenantID, err)
+			continue
+		}
+		log.Printf("Rotated data key for tenant %s: v%d -> v%d", key.TenantID, key.Version, version)
+	}
+
+	tenantIDs, err := w.keyring.TenantsWithRetiredKeys(allTenants)
+	if err != nil {
+		return err
+	}
+	pending := make(map[string]bool, len(tenantIDs))
+	for _, tenantID := range tenantIDs {
+		pending[tenantID] = true
+	}
+	for _, model := range w.models {
+		plaintext, err := encryption.PlaintextTenants(allTenants, w.db, model)
+		if err != nil {
+			return err
+		}
+		for _, tenantID := range plaintext {
+			if tenantID != "" {
+				pending[tenantID] = true
+			}
+		}
+	}
+
+	for tenantID := range pending {
+		tenantCtx := tenancy.WithTenant(ctx, tenantID)
+		for _, model := range w.models {
+			count, err := encryption.ReencryptTenant(tenantCtx, w.db, w.keyring, model, tenantID)
+			if err != nil {
+				log.Printf("Failed to re-encrypt %T rows for tenant %s: %v", model, tenantID, err)
+				continue
+			}
+			if count > 0 {
+				log.Printf("Re-encrypted %d %T rows for tenant %s", count, model, tenantID)
+			}
+		}
+	}
+
+	return nil
+}
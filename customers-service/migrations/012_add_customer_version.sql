@@ -0,0 +1,7 @@
+-- Migration: Add version column to customers table
+-- Purpose: Optimistic concurrency control for customer updates (If-Match / expectedVersion)
+
+ALTER TABLE customers
+ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN customers.version IS 'Incremented on every update; updates sent with a stale version are rejected with 409';
@@ -0,0 +1,31 @@
+-- Migration: Per-tenant field encryption
+-- Purpose: Store tenant data encryption keys (wrapped by Cloud KMS) and widen PII columns
+-- that now hold ciphertext (enc:v1:<key version>:<tenant id>:<payload>).
+-- Existing plaintext stays readable and is encrypted by the key rotation worker.
+
+CREATE TABLE IF NOT EXISTS tenant_data_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    version INTEGER NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    kek_name VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    retired_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_data_keys_version ON tenant_data_keys(tenant_id, version);
+CREATE INDEX IF NOT EXISTS idx_tenant_data_keys_status ON tenant_data_keys(status);
+
+COMMENT ON TABLE tenant_data_keys IS 'Per-tenant data encryption keys, wrapped by the Cloud KMS key encryption key';
+
+ALTER TABLE customers ALTER COLUMN phone TYPE TEXT;
+
+ALTER TABLE customer_addresses
+    ALTER COLUMN first_name TYPE TEXT,
+    ALTER COLUMN last_name TYPE TEXT,
+    ALTER COLUMN company TYPE TEXT,
+    ALTER COLUMN address_line1 TYPE TEXT,
+    ALTER COLUMN address_line2 TYPE TEXT,
+    ALTER COLUMN postal_code TYPE TEXT,
+    ALTER COLUMN phone TYPE TEXT;
@@ -0,0 +1,29 @@
+-- Migration: Self-service account deletion
+-- Purpose: Track storefront customers' account deletion requests through email verification,
+-- the cooling-off period and anonymization by every participating service.
+
+CREATE TABLE IF NOT EXISTS account_deletion_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    customer_id UUID NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    reason TEXT,
+    verification_token_hash VARCHAR(64),
+    verification_expires_at TIMESTAMPTZ,
+    verified_at TIMESTAMPTZ,
+    scheduled_for TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    pending_services TEXT[],
+    completed_services TEXT[],
+    processing_started_at TIMESTAMPTZ,
+    last_published_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_deletion_tenant_customer ON account_deletion_requests(tenant_id, customer_id);
+CREATE INDEX IF NOT EXISTS idx_account_deletion_status ON account_deletion_requests(status, scheduled_for);
+CREATE INDEX IF NOT EXISTS idx_account_deletion_requests_verification_token_hash ON account_deletion_requests(verification_token_hash);
+
+COMMENT ON TABLE account_deletion_requests IS 'Storefront account deletion requests; the customer is anonymized once every service in pending_services has confirmed';
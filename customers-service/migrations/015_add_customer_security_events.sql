@@ -0,0 +1,37 @@
+-- Migration: Customer security audit log
+-- Purpose: Record storefront customers' logins, password changes and payment method changes,
+-- and the devices they log in from so logins from new devices can be flagged.
+
+CREATE TABLE IF NOT EXISTS customer_security_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    customer_id UUID NOT NULL,
+    type VARCHAR(40) NOT NULL,
+    suspicious BOOLEAN NOT NULL DEFAULT FALSE,
+    ip_address VARCHAR(64),
+    user_agent TEXT,
+    device_id VARCHAR(64),
+    device_type VARCHAR(20),
+    location VARCHAR(255),
+    description TEXT,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_security_events_customer ON customer_security_events(tenant_id, customer_id, occurred_at);
+
+CREATE TABLE IF NOT EXISTS customer_devices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    customer_id UUID NOT NULL,
+    fingerprint VARCHAR(64) NOT NULL,
+    user_agent TEXT,
+    last_ip VARCHAR(64),
+    first_seen_at TIMESTAMPTZ NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customer_devices_fingerprint ON customer_devices(tenant_id, customer_id, fingerprint);
+
+COMMENT ON TABLE customer_security_events IS 'Storefront customer security audit log; suspicious marks activity the customer may not have done themselves';
+COMMENT ON TABLE customer_devices IS 'Devices customers have logged in from, keyed by a hash of the client device ID or user agent';
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,12 +14,17 @@ import (
 	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
+	"gift-cards-service/internal/clients"
+	"gift-cards-service/internal/codes"
 	"gift-cards-service/internal/config"
+	"gift-cards-service/internal/database"
 	"gift-cards-service/internal/events"
 	"gift-cards-service/internal/handlers"
 	"gift-cards-service/internal/middleware"
-	"gift-cards-service/internal/models"
 	"gift-cards-service/internal/repository"
+	"gift-cards-service/internal/serviceauth"
+	"gift-cards-service/internal/workers"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"github.com/Tesseract-Nexus/go-shared/rbac"
@@ -57,18 +65,25 @@ func main() {
 	// Initialize configuration
 	cfg := config.Load()
 
-	// Initialize database
-	db, err := config.InitDB(cfg)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return config.InitDB(cfg) })
 	if err != nil {
 		logger.Fatal("Failed to connect to database:", err)
 	}
 
-	// Run database migrations to create tables if they don't exist
-	logger.Info("Running database migrations...")
-	if err := db.AutoMigrate(&models.GiftCard{}, &models.GiftCardTransaction{}); err != nil {
-		logger.Fatalf("Failed to run migrations: %v", err)
+	// "gift-cards-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			logger.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		logger.Fatalf("Database schema is not ready: %v", err)
 	}
-	logger.Info("Database migrations completed")
 
 	// Initialize Redis client (graceful degradation if unavailable)
 	var redisClient *redis.Client
@@ -98,12 +113,41 @@ func main() {
 		logger.Info("✓ NATS events publisher initialized")
 	}
 
+	// Gift card code format (refuses to start with guessable codes)
+	codeFormat := codes.FormatFromEnv("GIFT_CARD_CODE", codes.DefaultFormat)
+	if err := codeFormat.Validate(); err != nil {
+		logger.Fatalf("Invalid gift card code format: %v", err)
+	}
+	logger.Infof("✓ Gift card codes: %d characters, %.0f bits of entropy", codeFormat.Length, codeFormat.EntropyBits())
+
 	// Initialize repository with Redis caching
-	giftCardRepo := repository.NewGiftCardRepository(db, redisClient)
+	giftCardRepo := repository.NewGiftCardRepository(db, redisClient, codeFormat)
+
+	// Brute-force protection for code lookups (per-IP/per-customer throttling and lockouts)
+	codeGuard := middleware.NewCodeGuard(redisClient, middleware.CodeGuardConfigFromEnv(), func(ctx context.Context, alert middleware.EnumerationAlert) {
+		logger.WithFields(logrus.Fields{
+			"tenant_id":      alert.TenantID,
+			"endpoint":       alert.Scope,
+			"client_ip":      alert.ClientIP,
+			"distinct_codes": alert.DistinctCodes,
+		}).Warn("Suspected gift card code enumeration")
+		if eventsPublisher != nil {
+			if err := eventsPublisher.PublishEnumerationSuspected(ctx, alert.TenantID, alert.Scope, alert.ClientIP, alert.CustomerID, alert.DistinctCodes, alert.LockoutSeconds); err != nil {
+				logger.WithError(err).Warn("Failed to publish enumeration alert")
+			}
+		}
+	})
 
 	// Initialize handlers
 	giftCardHandler := handlers.NewGiftCardHandler(giftCardRepo, eventsPublisher)
 
+	// Email scheduled gift cards to their recipients once their delivery time passes
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	deliveryWorker := workers.NewDeliveryWorker(giftCardRepo, clients.NewNotificationClient(), eventsPublisher, logger)
+	go deliveryWorker.Start(workerCtx)
+	logger.Info("✓ Gift card delivery worker started")
+
 	// Initialize RBAC middleware
 	staffServiceURL := os.Getenv("STAFF_SERVICE_URL")
 	if staffServiceURL == "" {
@@ -124,9 +168,11 @@ func main() {
 	// Security headers middleware
 	router.Use(gosharedmw.SecurityHeaders())
 
-	// Rate limiting middleware (uses Redis for distributed rate limiting)
+	// Rate limiting middleware (uses Redis for distributed rate limiting; profiles are managed in staff-service)
 	if redisClient != nil {
-		router.Use(gosharedmw.RedisRateLimitMiddlewareWithProfile(redisClient, "standard"))
+		rateLimiter := middleware.NewProfileRateLimiter(redisClient)
+		go rateLimiter.Start(context.Background())
+		router.Use(rateLimiter.Middleware())
 		logger.Info("✓ Redis-based rate limiting enabled")
 	} else {
 		router.Use(gosharedmw.RateLimit())
@@ -149,13 +195,25 @@ func main() {
 		publicGiftCards := publicAPI.Group("/gift-cards")
 		{
 			// Public endpoints for storefront
-			publicGiftCards.POST("/purchase", giftCardHandler.CreateGiftCard)  // Allow anonymous purchase
-			publicGiftCards.POST("/balance", giftCardHandler.CheckBalance)     // Check balance
-			publicGiftCards.POST("/apply", giftCardHandler.ApplyGiftCard)      // Apply to order
-			publicGiftCards.POST("/redeem", giftCardHandler.RedeemGiftCard)    // Redeem at checkout
+			publicGiftCards.POST("/purchase", giftCardHandler.CreateGiftCard)                               // Allow anonymous purchase
+			publicGiftCards.POST("/balance", codeGuard.Middleware("balance"), giftCardHandler.CheckBalance) // Check balance
+			publicGiftCards.POST("/apply", codeGuard.Middleware("apply"), giftCardHandler.ApplyGiftCard)    // Apply to order
+			publicGiftCards.POST("/redeem", codeGuard.Middleware("redeem"), giftCardHandler.RedeemGiftCard) // Redeem at checkout
 		}
 	}
 
+	// Internal service-to-service routes, authenticated by the caller's mesh identity or a
+	// service token rather than a user JWT
+	internalAuth := serviceauth.New(serviceauth.ConfigFromEnv("gift-cards-service"), logger)
+	internalAPI := router.Group("/api/v1/internal")
+	internalAPI.Use(internalAuth.Require("payment-service"), middleware.TenantMiddleware())
+	{
+		// Issue store credit as a gift card - called by payment-service for refunds
+		internalAPI.POST("/gift-cards", giftCardHandler.CreateGiftCard)
+		// Put a returned order's gift card payment back on the card - called by payment-service
+		internalAPI.POST("/gift-cards/refund", giftCardHandler.RefundGiftCard)
+	}
+
 	// Protected API routes (auth required) - for admin operations
 	api := router.Group("/api/v1")
 
@@ -176,11 +234,18 @@ func main() {
 			giftCards.POST("", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsCreate), giftCardHandler.CreateGiftCard)
 			giftCards.GET("", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsRead), giftCardHandler.ListGiftCards)
 			giftCards.GET("/stats", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsRead), giftCardHandler.GetGiftCardStats)
+			giftCards.GET("/deliveries", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsRead), giftCardHandler.ListDeliveries)
+			giftCards.GET("/email-template", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsRead), giftCardHandler.GetEmailTemplate)
+			giftCards.PUT("/email-template", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsManage), giftCardHandler.SaveEmailTemplate)
+			giftCards.DELETE("/email-template", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsManage), giftCardHandler.DeleteEmailTemplate)
 			giftCards.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsRead), giftCardHandler.GetGiftCard)
 			giftCards.PUT("/:id", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsUpdate), giftCardHandler.UpdateGiftCard)
 			giftCards.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsManage), giftCardHandler.DeleteGiftCard)
 			giftCards.PATCH("/:id/status", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsUpdate), giftCardHandler.UpdateGiftCardStatus)
 			giftCards.GET("/:id/transactions", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsRead), giftCardHandler.GetTransactionHistory)
+			giftCards.GET("/:id/delivery", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsRead), giftCardHandler.GetDelivery)
+			giftCards.PUT("/:id/delivery", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsUpdate), giftCardHandler.RescheduleDelivery)
+			giftCards.POST("/:id/delivery/cancel", rbacMiddleware.RequirePermission(rbac.PermissionGiftCardsUpdate), giftCardHandler.CancelDelivery)
 			// Note: /balance, /purchase, /apply, /redeem are public routes above
 		}
 	}
@@ -194,8 +259,31 @@ func main() {
 		port = "8080"
 	}
 
-	logger.Infof("Gift Cards service starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		logger.Fatal("Failed to start server:", err)
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
 	}
+	go func() {
+		logger.Infof("Gift Cards service starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("Shutting down gift-cards-service...")
+	stopWorkers()
+
+	// Stop accepting connections and let in-flight requests finish
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.WithError(err).Warn("Server forced to shutdown")
+	}
+
+	logger.Info("Gift Cards service stopped")
 }
@@ -0,0 +1,78 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// NotificationClient handles HTTP communication with notification-service
+type NotificationClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// notificationRequest is the API request format for notification-service
+type notificationRequest struct {
+	To        string            `json:"to"`
+	Subject   string            `json:"subject"`
+	Template  string            `json:"template"`
+	Variables map[string]string `json:"variables"`
+}
+
+// NewNotificationClient creates a new notification client
+func NewNotificationClient() *NotificationClient {
+	baseURL := os.Getenv("NOTIFICATION_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://notification-service.marketplace.svc.cluster.local:8090"
+	}
+
+	return &NotificationClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// SendGiftCardDelivery emails a gift card to its recipient with the tenant's rendered template
+func (c *NotificationClient) SendGiftCardDelivery(ctx context.Context, tenantID, to, subject, template string, variables map[string]string) error {
+	return c.sendNotification(ctx, tenantID, &notificationRequest{
+		To:        to,
+		Subject:   subject,
+		Template:  template,
+		Variables: variables,
+	})
+}
+
+// sendNotification sends a notification request to notification-service
+func (c *NotificationClient) sendNotification(ctx context.Context, tenantID string, req *notificationRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v1/notifications/send", bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Tenant-ID", tenantID)
+	httpReq.Header.Set("X-Internal-Service", "gift-cards-service")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("notification service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
+	"gift-cards-service/internal/tenancy"
+
 	"github.com/Tesseract-Nexus/go-shared/secrets")
 
 type Config struct {
@@ -24,8 +27,9 @@ type Config struct {
 	RedisURL string
 
 	// Server
-	Port        string
-	Environment string
+	Port            string
+	Environment     string
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
 
 	// JWT
 	JWTSecret string
@@ -53,8 +57,9 @@ func Load() *Config {
 		RedisURL: getEnv("REDIS_URL", "redis://redis.redis-marketplace.svc.cluster.local:6379/0"),
 
 		// Server
-		Port:        getEnv("PORT", "8080"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Port:            getEnv("PORT", "8080"),
+		Environment:     getEnv("ENVIRONMENT", "development"),
+		ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
 
 		// JWT
 		JWTSecret: secrets.GetJWTSecret(),
@@ -84,6 +89,11 @@ func InitDB(cfg *Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
+	}
+
 	return db, nil
 }
 
@@ -93,3 +103,12 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
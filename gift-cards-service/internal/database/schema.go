@@ -0,0 +1,21 @@
+package database
+
+import (
+	"gorm.io/gorm"
+
+	"gift-cards-service/internal/models"
+)
+
+// serviceName names the service in the migration lock key and log messages
+const serviceName = "gift-cards-service"
+
+// BaselineVersion is the last SQL migration folded into the model schema. Databases that
+// predate versioned migrations were built by AutoMigrate, and new databases get the same
+// schema from SyncModels, so only files numbered after it are applied from SQL.
+const BaselineVersion = 2
+
+// SyncModels brings the schema in line with the GORM models. It must only run while holding
+// the migration lock.
+func SyncModels(db *gorm.DB) error {
+	return db.AutoMigrate(&models.GiftCard{}, &models.GiftCardTransaction{}, &models.GiftCardDelivery{}, &models.GiftCardEmailTemplate{})
+}
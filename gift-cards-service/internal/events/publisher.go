@@ -3,11 +3,21 @@ package events
 import (
 	"context"
 	"os"
+	"time"
 
+	"gift-cards-service/internal/models"
 	"github.com/sirupsen/logrus"
 	"github.com/Tesseract-Nexus/go-shared/events"
 )
 
+// GiftCardEnumerationSuspected has no go-shared constant yet; security alerting subscribes
+// to it to flag clients guessing gift card codes
+const GiftCardEnumerationSuspected = "gift_card.enumeration_suspected"
+
+// GiftCardDelivered is published once a scheduled gift card email has been handed to
+// notification-service
+const GiftCardDelivered = "gift_card.delivered"
+
 // Publisher wraps the shared events publisher for gift card-specific events
 type Publisher struct {
 	publisher *events.Publisher
@@ -40,8 +50,9 @@ func NewPublisher(logger *logrus.Logger) (*Publisher, error) {
 	}, nil
 }
 
-// PublishGiftCardCreated publishes a gift card created event
-func (p *Publisher) PublishGiftCardCreated(ctx context.Context, tenantID, giftCardID, giftCardCode, purchaserID, purchaserEmail, purchaserName, recipientEmail, recipientName, message string, initialBalance float64, currency string) error {
+// PublishGiftCardCreated publishes a gift card created event. deliveryDate is set when the
+// card is emailed to its recipient later by the delivery worker.
+func (p *Publisher) PublishGiftCardCreated(ctx context.Context, tenantID, giftCardID, giftCardCode, purchaserID, purchaserEmail, purchaserName, recipientEmail, recipientName, message string, initialBalance float64, currency, deliveryDate string) error {
 	event := events.NewGiftCardEvent(events.GiftCardCreated, tenantID)
 	event.GiftCardID = giftCardID
 	event.GiftCardCode = giftCardCode
@@ -55,6 +66,26 @@ func (p *Publisher) PublishGiftCardCreated(ctx context.Context, tenantID, giftCa
 	event.CurrentBalance = initialBalance
 	event.Currency = currency
 	event.Status = "PENDING"
+	if deliveryDate != "" {
+		event.DeliveryMethod = "EMAIL"
+		event.DeliveryDate = deliveryDate
+	}
+
+	return p.publisher.Publish(ctx, event)
+}
+
+// PublishGiftCardDelivered publishes that a scheduled gift card email was sent
+func (p *Publisher) PublishGiftCardDelivered(ctx context.Context, card *models.GiftCard, delivery *models.GiftCardDelivery) error {
+	event := events.NewGiftCardEvent(GiftCardDelivered, card.TenantID)
+	event.GiftCardID = card.ID.String()
+	event.GiftCardCode = card.Code
+	event.RecipientEmail = delivery.RecipientEmail
+	event.CurrentBalance = card.CurrentBalance
+	event.InitialBalance = card.InitialBalance
+	event.Currency = card.CurrencyCode
+	event.Status = string(card.Status)
+	event.DeliveryMethod = "EMAIL"
+	event.DeliveryDate = delivery.DeliverAt.Format(time.RFC3339)
 
 	return p.publisher.Publish(ctx, event)
 }
@@ -102,6 +133,21 @@ func (p *Publisher) PublishGiftCardRefunded(ctx context.Context, tenantID, giftC
 	return p.publisher.Publish(ctx, event)
 }
 
+// PublishEnumerationSuspected publishes an alert that a client looks like it is guessing
+// gift card codes
+func (p *Publisher) PublishEnumerationSuspected(ctx context.Context, tenantID, scope, clientIP, customerID string, distinctCodes int64, lockoutSeconds int) error {
+	event := events.NewGiftCardEvent(GiftCardEnumerationSuspected, tenantID)
+	event.Metadata = map[string]interface{}{
+		"endpoint":       scope,
+		"clientIp":       clientIP,
+		"customerId":     customerID,
+		"distinctCodes":  distinctCodes,
+		"lockoutSeconds": lockoutSeconds,
+	}
+
+	return p.publisher.Publish(ctx, event)
+}
+
 // IsConnected returns true if connected to NATS
 func (p *Publisher) IsConnected() bool {
 	return p.publisher.IsConnected()
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gift-cards-service/internal/models"
+	"gift-cards-service/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ListDeliveries lists scheduled gift card emails, soonest first
+// GET /api/v1/gift-cards/deliveries
+func (h *GiftCardHandler) ListDeliveries(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	deliveries, total, err := h.repo.ListDeliveries(tenantID.(string), models.DeliveryStatus(c.Query("status")), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve gift card deliveries",
+			},
+		})
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	c.JSON(http.StatusOK, models.GiftCardDeliveryListResponse{
+		Success: true,
+		Data:    deliveries,
+		Pagination: &models.PaginationInfo{
+			Page:        page,
+			Limit:       limit,
+			Total:       total,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	})
+}
+
+// GetDelivery retrieves a gift card's scheduled email
+// GET /api/v1/gift-cards/:id/delivery
+func (h *GiftCardHandler) GetDelivery(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	id, ok := parseGiftCardID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.repo.GetDelivery(tenantID.(string), id)
+	if err != nil {
+		respondDeliveryError(c, err, "FETCH_FAILED", "Failed to retrieve gift card delivery")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GiftCardDeliveryResponse{
+		Success: true,
+		Data:    delivery,
+	})
+}
+
+// RescheduleDelivery moves a pending or failed gift card email to a new time or address
+// PUT /api/v1/gift-cards/:id/delivery
+func (h *GiftCardHandler) RescheduleDelivery(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	id, ok := parseGiftCardID(c)
+	if !ok {
+		return
+	}
+
+	var req models.RescheduleDeliveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	giftCard, err := h.repo.GetGiftCardByID(tenantID.(string), id)
+	if err != nil {
+		respondDeliveryError(c, err, "UPDATE_FAILED", "Failed to reschedule gift card delivery")
+		return
+	}
+	if message := models.ValidateDeliverAt(req.DeliverAt, giftCard.ExpiresAt, time.Now()); message != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_DELIVERY",
+				Message: message,
+			},
+		})
+		return
+	}
+
+	delivery, err := h.repo.RescheduleDelivery(tenantID.(string), id, req.DeliverAt, req.RecipientEmail, actorID(c))
+	if err != nil {
+		respondDeliveryError(c, err, "UPDATE_FAILED", "Failed to reschedule gift card delivery")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GiftCardDeliveryResponse{
+		Success: true,
+		Data:    delivery,
+		Message: stringPtr("Gift card delivery rescheduled successfully"),
+	})
+}
+
+// CancelDelivery stops a pending or failed gift card email; the card itself stays usable
+// POST /api/v1/gift-cards/:id/delivery/cancel
+func (h *GiftCardHandler) CancelDelivery(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	id, ok := parseGiftCardID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.repo.CancelDelivery(tenantID.(string), id, actorID(c))
+	if err != nil {
+		respondDeliveryError(c, err, "UPDATE_FAILED", "Failed to cancel gift card delivery")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GiftCardDeliveryResponse{
+		Success: true,
+		Data:    delivery,
+		Message: stringPtr("Gift card delivery cancelled successfully"),
+	})
+}
+
+// GetEmailTemplate returns the tenant's gift card email template, or the default one
+// GET /api/v1/gift-cards/email-template
+func (h *GiftCardHandler) GetEmailTemplate(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	template, err := h.repo.GetEmailTemplate(tenantID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve gift card email template",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GiftCardEmailTemplateResponse{
+		Success: true,
+		Data:    template,
+	})
+}
+
+// SaveEmailTemplate customizes the tenant's gift card emails
+// PUT /api/v1/gift-cards/email-template
+func (h *GiftCardHandler) SaveEmailTemplate(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	var req models.SaveEmailTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	if message := req.Validate(); message != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_TEMPLATE",
+				Message: message,
+			},
+		})
+		return
+	}
+
+	template := &models.GiftCardEmailTemplate{
+		TenantID:  tenantID.(string),
+		Template:  models.DefaultGiftCardEmailTemplate,
+		Subject:   req.Subject,
+		Heading:   req.Heading,
+		Body:      req.Body,
+		StoreName: req.StoreName,
+		UpdatedBy: actorID(c),
+	}
+	if req.Template != nil && *req.Template != "" {
+		template.Template = *req.Template
+	}
+
+	if err := h.repo.SaveEmailTemplate(template); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_FAILED",
+				Message: "Failed to save gift card email template",
+			},
+		})
+		return
+	}
+
+	saved, err := h.repo.GetEmailTemplate(tenantID.(string))
+	if err != nil {
+		saved = template
+	}
+	c.JSON(http.StatusOK, models.GiftCardEmailTemplateResponse{
+		Success: true,
+		Data:    saved,
+		Message: stringPtr("Gift card email template saved successfully"),
+	})
+}
+
+// DeleteEmailTemplate resets the tenant's gift card emails to the default template
+// DELETE /api/v1/gift-cards/email-template
+func (h *GiftCardHandler) DeleteEmailTemplate(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	if err := h.repo.DeleteEmailTemplate(tenantID.(string)); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "DELETE_FAILED",
+				Message: "Failed to reset gift card email template",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: stringPtr("Gift card email template reset to default"),
+	})
+}
+
+func parseGiftCardID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid gift card ID",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// actorID returns the authenticated user's ID, if any
+func actorID(c *gin.Context) *string {
+	if uid, ok := c.Get("user_id"); ok {
+		if s, ok := uid.(string); ok && s != "" {
+			return &s
+		}
+	}
+	return nil
+}
+
+func respondDeliveryError(c *gin.Context, err error, code, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Gift card has no scheduled delivery",
+			},
+		})
+	case errors.Is(err, repository.ErrDeliveryNotChangeable):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "DELIVERY_NOT_CHANGEABLE",
+				Message: "Delivery has already been sent, cancelled or is being sent",
+			},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    code,
+				Message: message,
+			},
+		})
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,8 +10,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gift-cards-service/internal/events"
+	"gift-cards-service/internal/middleware"
 	"gift-cards-service/internal/models"
 	"gift-cards-service/internal/repository"
+	"gorm.io/gorm"
 )
 
 type GiftCardHandler struct {
@@ -64,7 +67,31 @@ func (h *GiftCardHandler) CreateGiftCard(c *gin.Context) {
 		}
 	}
 
-	if err := h.repo.CreateGiftCard(tenantID.(string), giftCard); err != nil {
+	// A deliverAt emails the card to the recipient later instead of right away
+	var delivery *models.GiftCardDelivery
+	if req.DeliverAt != nil {
+		message := models.ValidateDeliverAt(*req.DeliverAt, req.ExpiresAt, time.Now())
+		if req.RecipientEmail == nil || *req.RecipientEmail == "" {
+			message = "recipientEmail is required to schedule a delivery"
+		}
+		if message != "" {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "INVALID_DELIVERY",
+					Message: message,
+				},
+			})
+			return
+		}
+		delivery = &models.GiftCardDelivery{
+			RecipientEmail: *req.RecipientEmail,
+			DeliverAt:      *req.DeliverAt,
+			CreatedBy:      giftCard.CreatedBy,
+		}
+	}
+
+	if err := h.repo.CreateGiftCard(tenantID.(string), giftCard, delivery); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
@@ -78,7 +105,12 @@ func (h *GiftCardHandler) CreateGiftCard(c *gin.Context) {
 	// Publish gift card created event for notifications (email to recipient)
 	if h.publisher != nil {
 		var recipientEmail, recipientName, senderName, message string
-		if giftCard.RecipientEmail != nil {
+		var deliveryDate string
+		if delivery != nil {
+			// The delivery worker emails the recipient at deliverAt, so consumers of this
+			// event must not email them now
+			deliveryDate = delivery.DeliverAt.Format(time.RFC3339)
+		} else if giftCard.RecipientEmail != nil {
 			recipientEmail = *giftCard.RecipientEmail
 		}
 		if giftCard.RecipientName != nil {
@@ -114,6 +146,7 @@ func (h *GiftCardHandler) CreateGiftCard(c *gin.Context) {
 			message,
 			giftCard.InitialBalance,
 			giftCard.CurrencyCode,
+			deliveryDate,
 		); err != nil {
 			// Log but don't fail the request - gift card was created successfully
 			c.Error(err)
@@ -253,6 +286,9 @@ func (h *GiftCardHandler) CheckBalance(c *gin.Context) {
 
 	giftCard, err := h.repo.GetGiftCardByCode(tenantID.(string), req.Code)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.MarkCodeFailure(c, req.Code)
+		}
 		c.JSON(http.StatusNotFound, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
@@ -319,7 +355,11 @@ func (h *GiftCardHandler) RedeemGiftCard(c *gin.Context) {
 		statusCode := http.StatusInternalServerError
 		errorCode := "REDEMPTION_FAILED"
 
-		if err.Error() == "gift card is not active" {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.MarkCodeFailure(c, req.Code)
+			statusCode = http.StatusNotFound
+			errorCode = "NOT_FOUND"
+		} else if err.Error() == "gift card is not active" {
 			statusCode = http.StatusBadRequest
 			errorCode = "CARD_NOT_ACTIVE"
 		} else if err.Error() == "gift card has expired" {
@@ -347,6 +387,65 @@ func (h *GiftCardHandler) RedeemGiftCard(c *gin.Context) {
 	})
 }
 
+// RefundGiftCard puts an amount back on a gift card - called by payment-service when a
+// return refunds an order the card paid for
+func (h *GiftCardHandler) RefundGiftCard(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	var req models.RefundGiftCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	giftCard, err := h.repo.GetGiftCardByCode(tenantID.(string), req.Code)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Gift card not found",
+			},
+		})
+		return
+	}
+
+	if err := h.repo.RefundGiftCard(tenantID.(string), giftCard.ID, req.Amount, req.OrderID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "REFUND_FAILED",
+				Message: "Failed to refund gift card",
+			},
+		})
+		return
+	}
+
+	giftCard, _ = h.repo.GetGiftCardByID(tenantID.(string), giftCard.ID)
+
+	if h.publisher != nil && giftCard != nil {
+		orderID := ""
+		if req.OrderID != nil {
+			orderID = req.OrderID.String()
+		}
+		if err := h.publisher.PublishGiftCardRefunded(context.Background(), tenantID.(string), giftCard.ID.String(), giftCard.Code, orderID, req.Amount, giftCard.CurrentBalance, giftCard.CurrencyCode); err != nil {
+			c.Error(err)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.GiftCardResponse{
+		Success: true,
+		Data:    giftCard,
+		Message: stringPtr("Gift card refunded successfully"),
+	})
+}
+
 // ApplyGiftCard applies a gift card to an order
 func (h *GiftCardHandler) ApplyGiftCard(c *gin.Context) {
 	tenantID, _ := c.Get("tenant_id")
@@ -366,6 +465,9 @@ func (h *GiftCardHandler) ApplyGiftCard(c *gin.Context) {
 	// Get gift card to validate
 	giftCard, err := h.repo.GetGiftCardByCode(tenantID.(string), req.Code)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.MarkCodeFailure(c, req.Code)
+		}
 		c.JSON(http.StatusNotFound, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
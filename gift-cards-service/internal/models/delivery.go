@@ -0,0 +1,214 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxDeliveryLead bounds how far ahead a gift card email can be scheduled
+const MaxDeliveryLead = 366 * 24 * time.Hour
+
+// DeliveryStatus represents where a scheduled gift card email is
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "PENDING"   // Waiting for deliver_at, or for a retry
+	DeliveryStatusSending   DeliveryStatus = "SENDING"   // Claimed by the delivery worker
+	DeliveryStatusSent      DeliveryStatus = "SENT"      // Accepted by notification-service
+	DeliveryStatusFailed    DeliveryStatus = "FAILED"    // Out of attempts; reschedule to try again
+	DeliveryStatusCancelled DeliveryStatus = "CANCELLED" // Cancelled, or the gift card can no longer be used
+)
+
+// GiftCardDelivery is the email that sends a gift card to its recipient at deliver_at
+type GiftCardDelivery struct {
+	ID             uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID       string         `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	GiftCardID     uuid.UUID      `json:"giftCardId" gorm:"type:uuid;not null;uniqueIndex"`
+	RecipientEmail string         `json:"recipientEmail" gorm:"type:varchar(255);not null"`
+	DeliverAt      time.Time      `json:"deliverAt" gorm:"not null"`
+	Status         DeliveryStatus `json:"status" gorm:"type:varchar(20);not null;default:'PENDING';index:idx_gift_card_deliveries_due"`
+	Attempts       int            `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time      `json:"nextAttemptAt" gorm:"not null;index:idx_gift_card_deliveries_due"`
+	LockedUntil    *time.Time     `json:"-"`
+	LastError      *string        `json:"lastError,omitempty" gorm:"type:text"`
+	SentAt         *time.Time     `json:"sentAt,omitempty"`
+	CancelledAt    *time.Time     `json:"cancelledAt,omitempty"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+	CreatedBy      *string        `json:"createdBy,omitempty"`
+	UpdatedBy      *string        `json:"updatedBy,omitempty"`
+}
+
+// TableName returns the table name for GiftCardDelivery
+func (GiftCardDelivery) TableName() string {
+	return "gift_card_deliveries"
+}
+
+// ValidateDeliverAt checks a requested delivery time against now and the gift card's expiry
+func ValidateDeliverAt(deliverAt time.Time, expiresAt *time.Time, now time.Time) string {
+	if deliverAt.After(now.Add(MaxDeliveryLead)) {
+		return "deliverAt can be at most a year ahead"
+	}
+	if expiresAt != nil && !deliverAt.Before(*expiresAt) {
+		return "deliverAt must be before the gift card expires"
+	}
+	return ""
+}
+
+// RescheduleDeliveryRequest moves a pending or failed delivery, optionally to a new address
+type RescheduleDeliveryRequest struct {
+	DeliverAt      time.Time `json:"deliverAt" binding:"required"`
+	RecipientEmail *string   `json:"recipientEmail,omitempty" binding:"omitempty,email"`
+}
+
+// GiftCardDeliveryResponse represents a single delivery
+type GiftCardDeliveryResponse struct {
+	Success bool              `json:"success"`
+	Data    *GiftCardDelivery `json:"data,omitempty"`
+	Message *string           `json:"message,omitempty"`
+}
+
+// GiftCardDeliveryListResponse represents a page of deliveries
+type GiftCardDeliveryListResponse struct {
+	Success    bool               `json:"success"`
+	Data       []GiftCardDelivery `json:"data"`
+	Pagination *PaginationInfo    `json:"pagination,omitempty"`
+}
+
+// Email templates
+
+// DefaultGiftCardEmailTemplate is the notification-service template gift cards are sent with
+const DefaultGiftCardEmailTemplate = "gift_card_delivery"
+
+// templatePlaceholders are the values a tenant's subject, heading and body can use
+var templatePlaceholders = []string{
+	"recipientName", "senderName", "amount", "currency", "code", "message", "expiresAt", "storeName",
+}
+
+// GiftCardEmailTemplate is a tenant's wording for gift card emails. Subject, heading and body
+// may use {{placeholders}} such as {{senderName}} and {{amount}}.
+type GiftCardEmailTemplate struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID  string    `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Template  string    `json:"template" gorm:"type:varchar(100);not null"` // notification-service template key
+	Subject   string    `json:"subject" gorm:"type:varchar(255);not null"`
+	Heading   *string   `json:"heading,omitempty" gorm:"type:varchar(255)"`
+	Body      *string   `json:"body,omitempty" gorm:"type:text"`
+	StoreName *string   `json:"storeName,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	UpdatedBy *string   `json:"updatedBy,omitempty"`
+}
+
+// TableName returns the table name for GiftCardEmailTemplate
+func (GiftCardEmailTemplate) TableName() string {
+	return "gift_card_email_templates"
+}
+
+// DefaultEmailTemplate is used by tenants that haven't customized their gift card emails
+func DefaultEmailTemplate(tenantID string) *GiftCardEmailTemplate {
+	return &GiftCardEmailTemplate{
+		TenantID: tenantID,
+		Template: DefaultGiftCardEmailTemplate,
+		Subject:  "{{senderName}} sent you a {{currency}} {{amount}} gift card",
+	}
+}
+
+// Render fills the template's placeholders for a gift card, returning the subject and the
+// variables passed to notification-service
+func (t *GiftCardEmailTemplate) Render(card *GiftCard) (string, map[string]string) {
+	values := map[string]string{
+		"recipientName": stringValue(card.RecipientName),
+		"senderName":    stringValue(card.SenderName),
+		"amount":        fmt.Sprintf("%.2f", card.InitialBalance),
+		"currency":      card.CurrencyCode,
+		"code":          card.Code,
+		"message":       stringValue(card.Message),
+		"storeName":     stringValue(t.StoreName),
+	}
+	if values["senderName"] == "" {
+		values["senderName"] = "Someone"
+	}
+	if card.ExpiresAt != nil {
+		values["expiresAt"] = card.ExpiresAt.Format("January 2, 2006")
+	}
+
+	pairs := make([]string, 0, len(values)*2)
+	for key, value := range values {
+		pairs = append(pairs, "{{"+key+"}}", value)
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	variables := map[string]string{
+		"giftCardId": card.ID.String(),
+		"tenantId":   card.TenantID,
+		"heading":    replacer.Replace(stringValue(t.Heading)),
+		"body":       replacer.Replace(stringValue(t.Body)),
+	}
+	for key, value := range values {
+		variables[key] = value
+	}
+	return replacer.Replace(t.Subject), variables
+}
+
+// SaveEmailTemplateRequest represents a request to customize a tenant's gift card emails
+type SaveEmailTemplateRequest struct {
+	Template  *string `json:"template,omitempty" binding:"omitempty,max=100"`
+	Subject   string  `json:"subject" binding:"required,max=255"`
+	Heading   *string `json:"heading,omitempty" binding:"omitempty,max=255"`
+	Body      *string `json:"body,omitempty" binding:"omitempty,max=5000"`
+	StoreName *string `json:"storeName,omitempty" binding:"omitempty,max=255"`
+}
+
+// Validate checks that the subject, heading and body only use known placeholders
+func (r *SaveEmailTemplateRequest) Validate() string {
+	for _, text := range []string{r.Subject, stringValue(r.Heading), stringValue(r.Body)} {
+		if name := unknownPlaceholder(text); name != "" {
+			return fmt.Sprintf("Unknown placeholder {{%s}}; use one of %s", name, strings.Join(templatePlaceholders, ", "))
+		}
+	}
+	return ""
+}
+
+// unknownPlaceholder returns the first {{name}} in text that isn't a template placeholder
+func unknownPlaceholder(text string) string {
+	for {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			return ""
+		}
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			return ""
+		}
+		name := text[start+2 : start+end]
+		known := false
+		for _, placeholder := range templatePlaceholders {
+			if name == placeholder {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return name
+		}
+		text = text[start+end+2:]
+	}
+}
+
+// GiftCardEmailTemplateResponse represents a tenant's gift card email template
+type GiftCardEmailTemplateResponse struct {
+	Success bool                   `json:"success"`
+	Data    *GiftCardEmailTemplate `json:"data"`
+	Message *string                `json:"message,omitempty"`
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -138,6 +138,7 @@ type CreateGiftCardRequest struct {
 	Message        *string    `json:"message,omitempty"`
 	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
 	Metadata       *JSON      `json:"metadata,omitempty"`
+	DeliverAt      *time.Time `json:"deliverAt,omitempty"` // Email the card to recipientEmail at this time, e.g. a birthday
 }
 
 // PurchaseGiftCardRequest represents a request to purchase a gift card
@@ -157,6 +158,14 @@ type RedeemGiftCardRequest struct {
 	Amount float64 `json:"amount" binding:"required,gt=0"`
 }
 
+// RefundGiftCardRequest represents a request to put an amount back on a gift card, e.g.
+// when an order it paid for is returned
+type RefundGiftCardRequest struct {
+	Code    string     `json:"code" binding:"required"`
+	Amount  float64    `json:"amount" binding:"required,gt=0"`
+	OrderID *uuid.UUID `json:"orderId,omitempty"`
+}
+
 // ApplyGiftCardRequest represents a request to apply a gift card to an order
 type ApplyGiftCardRequest struct {
 	Code    string     `json:"code" binding:"required"`
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gift-cards-service/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrDeliveryNotChangeable is returned when rescheduling or cancelling a delivery that has
+// already been sent, cancelled or claimed for sending
+var ErrDeliveryNotChangeable = errors.New("delivery can no longer be changed")
+
+// GetDelivery retrieves the scheduled delivery of a gift card
+func (r *GiftCardRepository) GetDelivery(tenantID string, giftCardID uuid.UUID) (*models.GiftCardDelivery, error) {
+	var delivery models.GiftCardDelivery
+	err := r.db.Where("tenant_id = ? AND gift_card_id = ?", tenantID, giftCardID).First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries retrieves a tenant's deliveries, soonest first, optionally in one status
+func (r *GiftCardRepository) ListDeliveries(tenantID string, status models.DeliveryStatus, page, limit int) ([]models.GiftCardDelivery, int64, error) {
+	var deliveries []models.GiftCardDelivery
+	var total int64
+
+	query := r.db.Model(&models.GiftCardDelivery{}).Where("tenant_id = ?", tenantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("deliver_at ASC").Offset(offset).Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// RescheduleDelivery moves a pending or failed delivery to deliverAt, optionally to a new
+// recipient, and gives it a fresh set of attempts
+func (r *GiftCardRepository) RescheduleDelivery(tenantID string, giftCardID uuid.UUID, deliverAt time.Time, recipientEmail *string, updatedBy *string) (*models.GiftCardDelivery, error) {
+	updates := map[string]interface{}{
+		"deliver_at":      deliverAt,
+		"next_attempt_at": deliverAt,
+		"status":          models.DeliveryStatusPending,
+		"attempts":        0,
+		"last_error":      nil,
+		"updated_at":      time.Now(),
+		"updated_by":      updatedBy,
+	}
+	if recipientEmail != nil {
+		updates["recipient_email"] = *recipientEmail
+	}
+	return r.updateChangeableDelivery(tenantID, giftCardID, updates)
+}
+
+// CancelDelivery cancels a pending or failed delivery; the gift card itself stays usable
+func (r *GiftCardRepository) CancelDelivery(tenantID string, giftCardID uuid.UUID, updatedBy *string) (*models.GiftCardDelivery, error) {
+	now := time.Now()
+	return r.updateChangeableDelivery(tenantID, giftCardID, map[string]interface{}{
+		"status":       models.DeliveryStatusCancelled,
+		"cancelled_at": now,
+		"updated_at":   now,
+		"updated_by":   updatedBy,
+	})
+}
+
+// updateChangeableDelivery applies updates to a delivery that hasn't been sent, cancelled or
+// claimed yet. The status check is part of the update, so a delivery the worker claims in
+// the meantime is left alone.
+func (r *GiftCardRepository) updateChangeableDelivery(tenantID string, giftCardID uuid.UUID, updates map[string]interface{}) (*models.GiftCardDelivery, error) {
+	var delivery models.GiftCardDelivery
+	result := r.db.Model(&delivery).
+		Clauses(clause.Returning{}).
+		Where("tenant_id = ? AND gift_card_id = ? AND status IN ?", tenantID, giftCardID,
+			[]models.DeliveryStatus{models.DeliveryStatusPending, models.DeliveryStatusFailed}).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.GetDelivery(tenantID, giftCardID); err != nil {
+			return nil, err
+		}
+		return nil, ErrDeliveryNotChangeable
+	}
+	return &delivery, nil
+}
+
+// ClaimDueDeliveries marks up to limit due deliveries as sending until lockUntil and returns
+// them, earliest first. Deliveries whose claim expired, because a worker stopped mid-send,
+// are taken over.
+func (r *GiftCardRepository) ClaimDueDeliveries(ctx context.Context, now, lockUntil time.Time, limit int) ([]models.GiftCardDelivery, error) {
+	var deliveries []models.GiftCardDelivery
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE gift_card_deliveries SET status = ?, locked_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM gift_card_deliveries
+			WHERE (status = ? AND next_attempt_at <= ?) OR (status = ? AND locked_until < ?)
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		models.DeliveryStatusSending, lockUntil, now,
+		models.DeliveryStatusPending, now, models.DeliveryStatusSending, now,
+		limit,
+	).Scan(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim gift card deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// SaveDeliveryAttempt records the outcome of a send and releases the claim
+func (r *GiftCardRepository) SaveDeliveryAttempt(ctx context.Context, delivery *models.GiftCardDelivery) error {
+	delivery.LockedUntil = nil
+	delivery.UpdatedAt = time.Now()
+	err := r.db.WithContext(ctx).Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "locked_until", "last_error", "sent_at", "cancelled_at", "updated_at").
+		Updates(delivery).Error
+	if err != nil {
+		return fmt.Errorf("failed to save gift card delivery: %w", err)
+	}
+	return nil
+}
+
+// GetEmailTemplate retrieves a tenant's gift card email template, or the default one if the
+// tenant hasn't customized it
+func (r *GiftCardRepository) GetEmailTemplate(tenantID string) (*models.GiftCardEmailTemplate, error) {
+	var template models.GiftCardEmailTemplate
+	err := r.db.Where("tenant_id = ?", tenantID).First(&template).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultEmailTemplate(tenantID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// SaveEmailTemplate creates or replaces a tenant's gift card email template
+func (r *GiftCardRepository) SaveEmailTemplate(template *models.GiftCardEmailTemplate) error {
+	now := time.Now()
+	template.ID = uuid.New()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"template", "subject", "heading", "body", "store_name", "updated_at", "updated_by"}),
+	}).Create(template).Error
+}
+
+// DeleteEmailTemplate resets a tenant's gift card emails to the default template
+func (r *GiftCardRepository) DeleteEmailTemplate(tenantID string) error {
+	return r.db.Where("tenant_id = ?", tenantID).Delete(&models.GiftCardEmailTemplate{}).Error
+}
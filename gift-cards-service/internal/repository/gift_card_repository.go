@@ -2,8 +2,6 @@ package repository
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -12,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/Tesseract-Nexus/go-shared/cache"
+	"gift-cards-service/internal/codes"
 	"gift-cards-service/internal/models"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -25,15 +24,17 @@ const (
 )
 
 type GiftCardRepository struct {
-	db    *gorm.DB
-	redis *redis.Client
-	cache *cache.CacheLayer
+	db         *gorm.DB
+	redis      *redis.Client
+	cache      *cache.CacheLayer
+	codeFormat codes.Format
 }
 
-func NewGiftCardRepository(db *gorm.DB, redisClient *redis.Client) *GiftCardRepository {
+func NewGiftCardRepository(db *gorm.DB, redisClient *redis.Client, codeFormat codes.Format) *GiftCardRepository {
 	repo := &GiftCardRepository{
-		db:    db,
-		redis: redisClient,
+		db:         db,
+		redis:      redisClient,
+		codeFormat: codeFormat,
 	}
 
 	// Initialize CacheLayer with the existing Redis client
@@ -95,11 +96,14 @@ func (r *GiftCardRepository) CacheStats() *cache.CacheStats {
 // GenerateUniqueCode generates a unique gift card code
 func (r *GiftCardRepository) GenerateUniqueCode() (string, error) {
 	for i := 0; i < 10; i++ {
-		code := generateGiftCardCode()
+		code, err := r.codeFormat.Generate()
+		if err != nil {
+			return "", err
+		}
 
 		// Check if code already exists
 		var count int64
-		err := r.db.Model(&models.GiftCard{}).Where("code = ?", code).Count(&count).Error
+		err = r.db.Model(&models.GiftCard{}).Where("code = ?", code).Count(&count).Error
 		if err != nil {
 			return "", err
 		}
@@ -112,18 +116,9 @@ func (r *GiftCardRepository) GenerateUniqueCode() (string, error) {
 	return "", fmt.Errorf("failed to generate unique code after 10 attempts")
 }
 
-// generateGiftCardCode generates a random gift card code (format: XXXX-XXXX-XXXX-XXXX)
-func generateGiftCardCode() string {
-	bytes := make([]byte, 8)
-	rand.Read(bytes)
-	code := strings.ToUpper(hex.EncodeToString(bytes))
-
-	// Format as XXXX-XXXX-XXXX-XXXX
-	return fmt.Sprintf("%s-%s-%s-%s", code[0:4], code[4:8], code[8:12], code[12:16])
-}
-
-// CreateGiftCard creates a new gift card
-func (r *GiftCardRepository) CreateGiftCard(tenantID string, giftCard *models.GiftCard) error {
+// CreateGiftCard creates a new gift card, and schedules its delivery email when delivery is
+// set
+func (r *GiftCardRepository) CreateGiftCard(tenantID string, giftCard *models.GiftCard, delivery *models.GiftCardDelivery) error {
 	if giftCard.Code == "" {
 		code, err := r.GenerateUniqueCode()
 		if err != nil {
@@ -138,7 +133,19 @@ func (r *GiftCardRepository) CreateGiftCard(tenantID string, giftCard *models.Gi
 	giftCard.CreatedAt = time.Now()
 	giftCard.UpdatedAt = time.Now()
 
-	err := r.db.Create(giftCard).Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(giftCard).Error; err != nil {
+			return err
+		}
+		if delivery == nil {
+			return nil
+		}
+		delivery.TenantID = tenantID
+		delivery.GiftCardID = giftCard.ID
+		delivery.Status = models.DeliveryStatusPending
+		delivery.NextAttemptAt = delivery.DeliverAt
+		return tx.Create(delivery).Error
+	})
 	if err == nil {
 		r.invalidateGiftCardCaches(context.Background(), tenantID, giftCard.ID, giftCard.Code)
 	}
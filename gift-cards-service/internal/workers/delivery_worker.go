@@ -0,0 +1,179 @@
+// Package workers provides background job processors for the gift cards service.
+package workers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gift-cards-service/internal/clients"
+	"gift-cards-service/internal/events"
+	"gift-cards-service/internal/models"
+	"gift-cards-service/internal/repository"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// DeliveryCheckInterval is how often due gift card emails are picked up
+	DeliveryCheckInterval = 1 * time.Minute
+
+	// MaxDeliveryAttempts is how many times an email is tried before the delivery fails
+	MaxDeliveryAttempts = 5
+
+	// deliveryBatchSize is the number of deliveries claimed per batch
+	deliveryBatchSize = 50
+
+	// deliveryClaimTTL is how long a claimed delivery stays with this worker; a claim that
+	// outlives it is taken over by the next check
+	deliveryClaimTTL = 5 * time.Minute
+
+	// deliveryRetryBase is the delay before the first retry, doubled on each later one
+	deliveryRetryBase = 5 * time.Minute
+)
+
+// DeliveryWorker emails scheduled gift cards to their recipients once deliver_at passes,
+// using the tenant's email template, and retries failed sends with backoff.
+type DeliveryWorker struct {
+	repo               *repository.GiftCardRepository
+	notificationClient *clients.NotificationClient
+	eventsPublisher    *events.Publisher
+	logger             *logrus.Entry
+}
+
+// NewDeliveryWorker creates a new gift card delivery worker
+func NewDeliveryWorker(repo *repository.GiftCardRepository, notificationClient *clients.NotificationClient, eventsPublisher *events.Publisher, logger *logrus.Logger) *DeliveryWorker {
+	return &DeliveryWorker{
+		repo:               repo,
+		notificationClient: notificationClient,
+		eventsPublisher:    eventsPublisher,
+		logger:             logger.WithField("component", "workers.delivery"),
+	}
+}
+
+// Start runs the delivery loop until ctx is done
+func (w *DeliveryWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(DeliveryCheckInterval)
+	defer ticker.Stop()
+
+	w.processDue(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			w.processDue(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// processDue sends every delivery that is due, in batches
+func (w *DeliveryWorker) processDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		now := time.Now()
+		deliveries, err := w.repo.ClaimDueDeliveries(ctx, now, now.Add(deliveryClaimTTL), deliveryBatchSize)
+		if err != nil {
+			w.logger.WithError(err).Error("Failed to claim due gift card deliveries")
+			return
+		}
+
+		for i := range deliveries {
+			w.deliver(ctx, &deliveries[i])
+		}
+		if len(deliveries) < deliveryBatchSize {
+			return
+		}
+	}
+}
+
+// deliver sends one gift card email and records the outcome
+func (w *DeliveryWorker) deliver(ctx context.Context, delivery *models.GiftCardDelivery) {
+	log := w.logger.WithFields(logrus.Fields{
+		"delivery_id":  delivery.ID,
+		"gift_card_id": delivery.GiftCardID,
+		"tenant_id":    delivery.TenantID,
+	})
+	now := time.Now()
+
+	card, err := w.repo.GetGiftCardByID(delivery.TenantID, delivery.GiftCardID)
+	if reason := unusableReason(card, err, now); reason != "" {
+		// Don't send a card the recipient can't use
+		delivery.Status = models.DeliveryStatusCancelled
+		delivery.CancelledAt = &now
+		delivery.LastError = &reason
+		w.save(ctx, delivery, log)
+		log.WithField("reason", reason).Info("Gift card delivery cancelled")
+		return
+	}
+
+	if err == nil {
+		err = w.send(ctx, card, delivery)
+	}
+	if err != nil {
+		w.retry(delivery, err, now)
+		w.save(ctx, delivery, log)
+		log.WithError(err).WithFields(logrus.Fields{
+			"attempts": delivery.Attempts,
+			"status":   delivery.Status,
+		}).Warn("Gift card delivery attempt failed")
+		return
+	}
+
+	delivery.Status = models.DeliveryStatusSent
+	delivery.Attempts++
+	delivery.SentAt = &now
+	delivery.LastError = nil
+	w.save(ctx, delivery, log)
+	log.Info("Gift card delivered")
+
+	if w.eventsPublisher != nil {
+		if err := w.eventsPublisher.PublishGiftCardDelivered(ctx, card, delivery); err != nil {
+			log.WithError(err).Warn("Failed to publish gift card delivered event")
+		}
+	}
+}
+
+// unusableReason says why a card shouldn't be sent, or returns "" if it can be
+func unusableReason(card *models.GiftCard, err error, now time.Time) string {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return "gift card was deleted"
+	case err != nil:
+		return ""
+	case card.Status != models.GiftCardStatusActive:
+		return "gift card is " + string(card.Status)
+	case card.ExpiresAt != nil && !card.ExpiresAt.After(now):
+		return "gift card has expired"
+	}
+	return ""
+}
+
+// send renders the tenant's template for the card and hands it to notification-service
+func (w *DeliveryWorker) send(ctx context.Context, card *models.GiftCard, delivery *models.GiftCardDelivery) error {
+	template, err := w.repo.GetEmailTemplate(delivery.TenantID)
+	if err != nil {
+		return err
+	}
+	subject, variables := template.Render(card)
+	return w.notificationClient.SendGiftCardDelivery(ctx, delivery.TenantID, delivery.RecipientEmail, subject, template.Template, variables)
+}
+
+// retry schedules the next attempt with exponential backoff, or fails the delivery once it
+// is out of attempts
+func (w *DeliveryWorker) retry(delivery *models.GiftCardDelivery, cause error, now time.Time) {
+	message := cause.Error()
+	delivery.Attempts++
+	delivery.LastError = &message
+	if delivery.Attempts >= MaxDeliveryAttempts {
+		delivery.Status = models.DeliveryStatusFailed
+		return
+	}
+	delivery.Status = models.DeliveryStatusPending
+	delivery.NextAttemptAt = now.Add(deliveryRetryBase << (delivery.Attempts - 1))
+}
+
+func (w *DeliveryWorker) save(ctx context.Context, delivery *models.GiftCardDelivery, log *logrus.Entry) {
+	if err := w.repo.SaveDeliveryAttempt(ctx, delivery); err != nil {
+		log.WithError(err).Error("Failed to save gift card delivery")
+	}
+}
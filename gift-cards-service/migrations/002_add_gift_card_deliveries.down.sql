@@ -0,0 +1,9 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_gift_card_email_templates_tenant_id;
+DROP INDEX IF EXISTS idx_gift_card_deliveries_due;
+DROP INDEX IF EXISTS idx_gift_card_deliveries_gift_card_id;
+DROP INDEX IF EXISTS idx_gift_card_deliveries_tenant_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS gift_card_email_templates;
+DROP TABLE IF EXISTS gift_card_deliveries;
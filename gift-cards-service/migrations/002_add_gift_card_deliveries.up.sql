@@ -0,0 +1,44 @@
+-- Scheduled gift card emails, sent by the delivery worker at deliver_at
+CREATE TABLE IF NOT EXISTS gift_card_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    gift_card_id UUID NOT NULL REFERENCES gift_cards(id) ON DELETE CASCADE,
+    recipient_email VARCHAR(255) NOT NULL,
+    deliver_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+
+    -- Retry tracking
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP,
+    last_error TEXT,
+
+    sent_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+
+    -- Audit fields
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS idx_gift_card_deliveries_tenant_id ON gift_card_deliveries(tenant_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gift_card_deliveries_gift_card_id ON gift_card_deliveries(gift_card_id);
+CREATE INDEX IF NOT EXISTS idx_gift_card_deliveries_due ON gift_card_deliveries(status, next_attempt_at);
+
+-- Per-tenant wording for gift card emails
+CREATE TABLE IF NOT EXISTS gift_card_email_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    template VARCHAR(100) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    heading VARCHAR(255),
+    body TEXT,
+    store_name VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by VARCHAR(255)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gift_card_email_templates_tenant_id ON gift_card_email_templates(tenant_id);
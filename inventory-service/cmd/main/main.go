@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,12 +13,17 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"inventory-service/internal/clients"
 	"inventory-service/internal/config"
+	"inventory-service/internal/database"
 	"inventory-service/internal/events"
 	"inventory-service/internal/handlers"
+	"inventory-service/internal/jobs"
 	"inventory-service/internal/middleware"
-	"inventory-service/internal/models"
 	"inventory-service/internal/repository"
+	"inventory-service/internal/serviceauth"
+	"inventory-service/internal/subscribers"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"github.com/Tesseract-Nexus/go-shared/rbac"
@@ -33,26 +39,24 @@ func main() {
 	// Initialize configuration
 	cfg := config.Load()
 
-	// Initialize database
-	db, err := config.InitDB(cfg)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return config.InitDB(cfg) })
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	// Auto-migrate models
-	if err := db.AutoMigrate(
-		&models.Warehouse{},
-		&models.Supplier{},
-		&models.PurchaseOrder{},
-		&models.PurchaseOrderItem{},
-		&models.InventoryTransfer{},
-		&models.InventoryTransferItem{},
-		&models.StockLevel{},
-		&models.InventoryReservation{},
-		&models.InventoryAlert{},
-		&models.AlertThreshold{},
-	); err != nil {
-		log.Fatal("Failed to migrate database:", err)
+	// "inventory-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
 	}
 
 	// Initialize logrus logger
@@ -107,10 +111,42 @@ func main() {
 
 	// Initialize repository with Redis client
 	inventoryRepo := repository.NewInventoryRepository(db, redisClient)
+	importMappingRepo := repository.NewImportMappingRepository(db)
 
 	// Initialize handlers with event publisher
 	inventoryHandler := handlers.NewInventoryHandler(inventoryRepo, eventPublisher)
-	importHandler := handlers.NewImportHandler(inventoryRepo)
+	importHandler := handlers.NewImportHandler(inventoryRepo, importMappingRepo)
+	productsClient := clients.NewProductsClient(cfg.ProductsServiceURL)
+	storefrontHandler := handlers.NewStorefrontHandler(inventoryRepo, productsClient)
+	pickupHandler := handlers.NewPickupHandler(inventoryRepo, productsClient)
+	orderStockHandler := handlers.NewOrderStockHandler(inventoryRepo, productsClient)
+	reservationHandler := handlers.NewReservationHandler(inventoryRepo, productsClient, eventPublisher)
+	transferHandler := handlers.NewTransferHandler(inventoryRepo, clients.NewShippingClient(cfg.ShippingServiceURL))
+	locationHandler := handlers.NewLocationHandler(inventoryRepo)
+	catalogHandler := handlers.NewSupplierCatalogHandler(inventoryRepo)
+	stockBatchHandler := handlers.NewStockBatchHandler(inventoryRepo, productsClient)
+	adjustmentHandler := handlers.NewAdjustmentHandler(inventoryRepo, clients.NewApprovalClient(cfg.ApprovalServiceURL), eventPublisher)
+
+	// Initialize approval subscriber, which applies stock adjustments once approval-service approves them
+	var approvalSubscriber *subscribers.ApprovalSubscriber
+	if cfg.NATSURL != "" {
+		approvalSubscriber, err = subscribers.NewApprovalSubscriber(inventoryRepo, eventPublisher, logger)
+		if err != nil {
+			log.Printf("WARNING: Failed to initialize approval subscriber: %v (large stock adjustments must be resolved manually)", err)
+		} else {
+			go func() {
+				if err := approvalSubscriber.Start(context.Background()); err != nil {
+					log.Printf("WARNING: Approval subscriber error: %v", err)
+				}
+			}()
+			log.Println("✓ Approval subscriber initialized (listening for stock adjustment decisions)")
+		}
+	}
+
+	// Release stock reservations whose TTL elapsed
+	reservationExpiryJob := jobs.NewReservationExpiryJob(inventoryRepo, eventPublisher, logger, cfg.ReservationExpiryInterval)
+	go reservationExpiryJob.Start(context.Background())
+	log.Println("✓ Reservation expiry job started")
 
 	// Initialize OpenTelemetry tracing
 	var tracerProvider *tracing.TracerProvider
@@ -148,9 +184,11 @@ func main() {
 	// Security headers middleware
 	router.Use(gosharedmw.SecurityHeaders())
 
-	// Rate limiting middleware (uses Redis for distributed rate limiting)
+	// Rate limiting middleware (uses Redis for distributed rate limiting; profiles are managed in staff-service)
 	if redisClient != nil {
-		router.Use(gosharedmw.RedisRateLimitMiddlewareWithProfile(redisClient, "standard"))
+		rateLimiter := middleware.NewProfileRateLimiter(redisClient)
+		go rateLimiter.Start(context.Background())
+		router.Use(rateLimiter.Middleware())
 		log.Println("✓ Redis-based rate limiting enabled")
 	} else {
 		router.Use(gosharedmw.RateLimit())
@@ -170,6 +208,35 @@ func main() {
 	router.GET("/ready", handlers.HealthCheck)
 	router.GET("/metrics", gosharedmw.Handler())
 
+	// Public storefront endpoints (no auth required, only tenant context)
+	// Safe for anonymous traffic: responses are cached and only expose shopper-facing fields
+	storefront := router.Group("/api/v1/storefront")
+	storefront.Use(middleware.TenantMiddleware())
+	{
+		storefront.GET("/availability", storefrontHandler.GetAvailability)
+	}
+
+	// Internal service-to-service routes (no RBAC - callers authenticate as a service)
+	// Used by orders-service for click-and-collect orders, checkout stock checks and holds, and
+	// scheduled low stock reports, and by products-service for stock on product listings
+	internalAuth := serviceauth.New(serviceauth.ConfigFromEnv("inventory-service"), logger)
+	internal := router.Group("/internal")
+	internal.Use(internalAuth.Require("orders-service", "products-service"), middleware.TenantMiddleware())
+	{
+		internal.GET("/pickup-locations/:id", pickupHandler.GetPickupLocation)
+		internal.POST("/stock/deduct", pickupHandler.DeductStock)
+		internal.POST("/stock/restore", pickupHandler.RestoreStock)
+		internal.POST("/stock/reserve", orderStockHandler.ReserveStock)
+		internal.POST("/stock/confirm", orderStockHandler.ConfirmStock)
+		internal.POST("/stock/release", orderStockHandler.ReleaseStock)
+		internal.GET("/stock/low", inventoryHandler.GetLowStockItems)
+		internal.POST("/stock/batch", stockBatchHandler.GetStockBatch)
+		internal.POST("/reservations", reservationHandler.CreateReservation)
+		internal.GET("/reservations/:id", reservationHandler.GetReservation)
+		internal.POST("/reservations/:id/confirm", reservationHandler.ConfirmReservation)
+		internal.POST("/reservations/:id/release", reservationHandler.ReleaseReservation)
+	}
+
 	// Protected API routes
 	api := router.Group("/api/v1")
 
@@ -191,6 +258,15 @@ func main() {
 		warehouses.PUT("/:id", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), inventoryHandler.UpdateWarehouse)
 		warehouses.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), inventoryHandler.DeleteWarehouse)
 
+		// Bin locations, bin stock and pick paths
+		warehouses.GET("/:id/locations", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), locationHandler.ListLocations)
+		warehouses.POST("/:id/locations", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), locationHandler.CreateLocation)
+		warehouses.PUT("/:id/locations/:locationId", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), locationHandler.UpdateLocation)
+		warehouses.DELETE("/:id/locations/:locationId", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), locationHandler.DeleteLocation)
+		warehouses.GET("/:id/bin-stock", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), locationHandler.ListBinStock)
+		warehouses.POST("/:id/bin-stock/move", rbacMiddleware.RequirePermission(rbac.PermissionInventoryAdjust), locationHandler.MoveBinStock)
+		warehouses.POST("/:id/pick-path", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), locationHandler.PlanPickPath)
+
 		// Bulk operations
 		warehouses.POST("/bulk", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), inventoryHandler.BulkCreateWarehouses)
 		warehouses.DELETE("/bulk", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), inventoryHandler.BulkDeleteWarehouses)
@@ -198,6 +274,10 @@ func main() {
 		// Import/Export
 		warehouses.GET("/import/template", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), importHandler.GetWarehouseImportTemplate)
 		warehouses.POST("/import", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), importHandler.ImportWarehouses)
+		warehouses.POST("/import/preview", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), importHandler.PreviewWarehouseImport)
+		warehouses.GET("/import/mappings", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), importHandler.ListWarehouseMappingPresets)
+		warehouses.POST("/import/mappings", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), importHandler.SaveWarehouseMappingPreset)
+		warehouses.DELETE("/import/mappings/:presetId", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), importHandler.DeleteMappingPreset)
 	}
 
 	// Supplier routes with RBAC
@@ -209,6 +289,13 @@ func main() {
 		suppliers.PUT("/:id", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), inventoryHandler.UpdateSupplier)
 		suppliers.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), inventoryHandler.DeleteSupplier)
 
+		// Price lists
+		suppliers.GET("/catalog/compare", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), catalogHandler.CompareSuppliers)
+		suppliers.GET("/:id/catalog", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), catalogHandler.ListSupplierCatalog)
+		suppliers.POST("/:id/catalog", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), catalogHandler.CreateSupplierCatalogEntry)
+		suppliers.PUT("/:id/catalog/:entryId", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), catalogHandler.UpdateSupplierCatalogEntry)
+		suppliers.DELETE("/:id/catalog/:entryId", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), catalogHandler.DeleteSupplierCatalogEntry)
+
 		// Bulk operations
 		suppliers.POST("/bulk", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), inventoryHandler.BulkCreateSuppliers)
 		suppliers.DELETE("/bulk", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), inventoryHandler.BulkDeleteSuppliers)
@@ -216,6 +303,10 @@ func main() {
 		// Import/Export
 		suppliers.GET("/import/template", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), importHandler.GetSupplierImportTemplate)
 		suppliers.POST("/import", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), importHandler.ImportSuppliers)
+		suppliers.POST("/import/preview", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), importHandler.PreviewSupplierImport)
+		suppliers.GET("/import/mappings", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), importHandler.ListSupplierMappingPresets)
+		suppliers.POST("/import/mappings", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), importHandler.SaveSupplierMappingPreset)
+		suppliers.DELETE("/import/mappings/:presetId", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), importHandler.DeleteMappingPreset)
 	}
 
 	// Purchase Order routes with RBAC
@@ -226,6 +317,8 @@ func main() {
 		purchaseOrders.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), inventoryHandler.GetPurchaseOrder)
 		purchaseOrders.PUT("/:id/status", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), inventoryHandler.UpdatePurchaseOrderStatus)
 		purchaseOrders.POST("/:id/receive", rbacMiddleware.RequirePermission(rbac.PermissionInventoryAdjust), inventoryHandler.ReceivePurchaseOrder)
+		purchaseOrders.GET("/:id/putaway-suggestions", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), locationHandler.GetPurchaseOrderPutaway)
+		purchaseOrders.GET("/:id/landed-costs", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), catalogHandler.ListPurchaseOrderLandedCosts)
 	}
 
 	// Inventory Transfer routes with RBAC
@@ -233,9 +326,24 @@ func main() {
 	{
 		transfers.POST("", rbacMiddleware.RequirePermission(rbac.PermissionInventoryAdjust), inventoryHandler.CreateInventoryTransfer)
 		transfers.GET("", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), inventoryHandler.ListInventoryTransfers)
+		transfers.GET("/in-transit", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), transferHandler.ListInTransitStock)
 		transfers.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), inventoryHandler.GetInventoryTransfer)
 		transfers.PUT("/:id/status", rbacMiddleware.RequirePermission(rbac.PermissionInventoryAdjust), inventoryHandler.UpdateTransferStatus)
 		transfers.POST("/:id/complete", rbacMiddleware.RequirePermission(rbac.PermissionInventoryAdjust), inventoryHandler.CompleteInventoryTransfer)
+
+		// Shipment and receiving
+		transfers.POST("/:id/ship", rbacMiddleware.RequirePermission(rbac.PermissionInventoryAdjust), transferHandler.ShipTransfer)
+		transfers.GET("/:id/tracking", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), transferHandler.GetTransferTracking)
+		transfers.POST("/:id/receive", rbacMiddleware.RequirePermission(rbac.PermissionInventoryAdjust), transferHandler.ReceiveTransfer)
+		transfers.GET("/:id/pick-path", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), locationHandler.GetTransferPickPath)
+	}
+
+	// Adjustment proposals raised by transfer receiving discrepancies and large manual adjustments
+	proposals := api.Group("/adjustment-proposals")
+	{
+		proposals.GET("", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), transferHandler.ListAdjustmentProposals)
+		proposals.POST("/:id/approve", rbacMiddleware.RequirePermission(rbac.PermissionInventoryAdjust), transferHandler.ApproveAdjustmentProposal)
+		proposals.POST("/:id/reject", rbacMiddleware.RequirePermission(rbac.PermissionInventoryAdjust), transferHandler.RejectAdjustmentProposal)
 	}
 
 	// Stock Level routes with RBAC
@@ -244,6 +352,22 @@ func main() {
 		stock.GET("", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), inventoryHandler.ListStockLevels)
 		stock.GET("/level", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), inventoryHandler.GetStockLevel)
 		stock.GET("/low", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), inventoryHandler.GetLowStockItems)
+		stock.GET("/valuation", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), catalogHandler.GetInventoryValuation)
+		stock.POST("/batch", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), stockBatchHandler.GetStockBatch)
+
+		// Manual adjustments; large ones wait for approval
+		stock.POST("/adjust", rbacMiddleware.RequirePermission(rbac.PermissionInventoryAdjust), adjustmentHandler.AdjustStock)
+		stock.GET("/adjustment-settings", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), adjustmentHandler.GetAdjustmentSettings)
+		stock.PUT("/adjustment-settings", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), adjustmentHandler.UpdateAdjustmentSettings)
+	}
+
+	// Stock reservations held for orders and carts during checkout
+	reservations := api.Group("/reservations")
+	{
+		reservations.POST("", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), reservationHandler.CreateReservation)
+		reservations.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionInventoryRead), reservationHandler.GetReservation)
+		reservations.POST("/:id/confirm", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), reservationHandler.ConfirmReservation)
+		reservations.POST("/:id/release", rbacMiddleware.RequirePermission(rbac.PermissionInventoryUpdate), reservationHandler.ReleaseReservation)
 	}
 
 	// Alert routes with RBAC
@@ -273,13 +397,18 @@ func main() {
 		port = "8088"
 	}
 
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+
 	// Graceful shutdown handling
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		log.Printf("Inventory service starting on port %s", port)
-		if err := router.Run(":" + port); err != nil {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server:", err)
 		}
 	}()
@@ -288,6 +417,18 @@ func main() {
 	<-quit
 	log.Println("Shutting down inventory-service...")
 
+	// Stop accepting connections and let in-flight requests finish before stopping workers
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
+	reservationExpiryJob.Stop()
+	if approvalSubscriber != nil {
+		approvalSubscriber.Stop()
+	}
+
 	// Shutdown tracer provider
 	if tracerProvider != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
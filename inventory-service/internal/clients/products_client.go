@@ -0,0 +1,209 @@
+// Package clients provides HTTP clients for service-to-service communication.
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxSKUCacheEntries bounds the SKU cache, which anonymous requests can fill
+const maxSKUCacheEntries = 50000
+
+// ErrSKUNotFound is returned when no product or variant has the SKU
+var ErrSKUNotFound = errors.New("sku not found")
+
+// SKUMatch identifies the product, and variant if any, that a SKU belongs to.
+type SKUMatch struct {
+	ProductID uuid.UUID
+	VariantID *uuid.UUID
+}
+
+type skuCacheEntry struct {
+	match     *SKUMatch // nil when the SKU doesn't exist
+	expiresAt time.Time
+}
+
+// ProductsClient resolves SKUs through products-service. Stock levels are keyed by
+// product and variant ID, so storefront lookups by SKU go through here first.
+type ProductsClient struct {
+	baseURL    string
+	httpClient *http.Client
+	cacheTTL   time.Duration
+	missTTL    time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]skuCacheEntry
+}
+
+// productsListResponse is the subset of products-service's product list used here
+type productsListResponse struct {
+	Success bool `json:"success"`
+	Data    []struct {
+		ID       uuid.UUID `json:"id"`
+		SKU      string    `json:"sku"`
+		Variants []struct {
+			ID  uuid.UUID `json:"id"`
+			SKU string    `json:"sku"`
+		} `json:"variants"`
+	} `json:"data"`
+}
+
+// NewProductsClient creates a new products client with caching.
+func NewProductsClient(baseURL string) *ProductsClient {
+	return &ProductsClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+		// SKUs rarely move between products; unknown SKUs are cached briefly so
+		// anonymous traffic can't hammer products-service with misses
+		cacheTTL: 10 * time.Minute,
+		missTTL:  1 * time.Minute,
+		cache:    make(map[string]skuCacheEntry),
+	}
+}
+
+// ResolveSKU returns the product and variant the SKU belongs to, or ErrSKUNotFound.
+func (c *ProductsClient) ResolveSKU(ctx context.Context, tenantID, sku string) (*SKUMatch, error) {
+	cacheKey := tenantID + ":" + sku
+
+	c.mu.RLock()
+	entry, ok := c.cache[cacheKey]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		if entry.match == nil {
+			return nil, ErrSKUNotFound
+		}
+		return entry.match, nil
+	}
+
+	match, err := c.fetchSKU(ctx, tenantID, sku)
+	if err != nil && !errors.Is(err, ErrSKUNotFound) {
+		return nil, err
+	}
+
+	ttl := c.cacheTTL
+	if match == nil {
+		ttl = c.missTTL
+	}
+	c.mu.Lock()
+	if len(c.cache) >= maxSKUCacheEntries {
+		now := time.Now()
+		for key, e := range c.cache {
+			if now.After(e.expiresAt) {
+				delete(c.cache, key)
+			}
+		}
+	}
+	if len(c.cache) < maxSKUCacheEntries {
+		c.cache[cacheKey] = skuCacheEntry{match: match, expiresAt: time.Now().Add(ttl)}
+	}
+	c.mu.Unlock()
+
+	return match, err
+}
+
+func (c *ProductsClient) fetchSKU(ctx context.Context, tenantID, sku string) (*SKUMatch, error) {
+	reqURL := fmt.Sprintf("%s/api/v1/storefront/products?sku=%s&includeVariants=true&limit=1", c.baseURL, url.QueryEscape(sku))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Tenant-ID", tenantID)
+	req.Header.Set("X-Internal-Service", "inventory-service")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("products API returned status %d", resp.StatusCode)
+	}
+
+	var apiResp productsListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	for _, product := range apiResp.Data {
+		for _, variant := range product.Variants {
+			if variant.SKU == sku {
+				variantID := variant.ID
+				return &SKUMatch{ProductID: product.ID, VariantID: &variantID}, nil
+			}
+		}
+		if product.SKU == sku {
+			return &SKUMatch{ProductID: product.ID}, nil
+		}
+	}
+	return nil, ErrSKUNotFound
+}
+
+// maxSKUResolveWorkers bounds concurrent lookups when resolving a batch of SKUs
+const maxSKUResolveWorkers = 16
+
+// ResolveSKUs resolves many SKUs concurrently through the SKU cache. SKUs that don't
+// exist are left out of the result; any other failure fails the whole batch.
+func (c *ProductsClient) ResolveSKUs(ctx context.Context, tenantID string, skus []string) (map[string]*SKUMatch, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	matches := make(map[string]*SKUMatch, len(skus))
+	queue := make(chan string)
+
+	for i := 0; i < min(maxSKUResolveWorkers, len(skus)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sku := range queue {
+				match, err := c.ResolveSKU(ctx, tenantID, sku)
+				mu.Lock()
+				switch {
+				case err == nil:
+					matches[sku] = match
+				case !errors.Is(err, ErrSKUNotFound) && firstErr == nil:
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[string]bool, len(skus))
+	for _, sku := range skus {
+		if seen[sku] {
+			continue
+		}
+		seen[sku] = true
+		select {
+		case queue <- sku:
+		case <-ctx.Done():
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return matches, ctx.Err()
+}
@@ -0,0 +1,172 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrShipmentNotFound is returned when shipping-service has no such shipment for the tenant
+var ErrShipmentNotFound = errors.New("shipment not found")
+
+// ShippingClient books and tracks the carrier shipments inventory transfers travel on,
+// through shipping-service.
+type ShippingClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// ShipmentAddress is an address in shipping-service's format
+type ShipmentAddress struct {
+	Name       string `json:"name"`
+	Company    string `json:"company,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Street     string `json:"street"`
+	Street2    string `json:"street2,omitempty"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postalCode"`
+	Country    string `json:"country"`
+}
+
+// CreateShipmentRequest books a shipment. Shipments are keyed by order, so transfers
+// pass their own ID and number.
+type CreateShipmentRequest struct {
+	OrderID     uuid.UUID       `json:"orderId"`
+	OrderNumber string          `json:"orderNumber"`
+	Carrier     string          `json:"carrier,omitempty"`
+	ServiceType string          `json:"serviceType,omitempty"`
+	FromAddress ShipmentAddress `json:"fromAddress"`
+	ToAddress   ShipmentAddress `json:"toAddress"`
+	Weight      float64         `json:"weight"`
+	Length      float64         `json:"length"`
+	Width       float64         `json:"width"`
+	Height      float64         `json:"height"`
+}
+
+// Shipment is the subset of a shipping-service shipment used for transfers
+type Shipment struct {
+	ID             string `json:"id"`
+	Carrier        string `json:"carrier"`
+	TrackingNumber string `json:"trackingNumber"`
+	TrackingURL    string `json:"trackingUrl"`
+	Status         string `json:"status"`
+}
+
+// TrackingEvent is one carrier scan of a shipment
+type TrackingEvent struct {
+	Status      string    `json:"status"`
+	Location    string    `json:"location"`
+	Description string    `json:"description"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// ShipmentTracking is a shipment's carrier tracking
+type ShipmentTracking struct {
+	ShipmentID        string          `json:"shipmentId"`
+	TrackingNumber    string          `json:"trackingNumber"`
+	Status            string          `json:"status"`
+	Carrier           string          `json:"carrier"`
+	EstimatedDelivery *time.Time      `json:"estimatedDelivery"`
+	ActualDelivery    *time.Time      `json:"actualDelivery"`
+	Events            []TrackingEvent `json:"events"`
+}
+
+// NewShippingClient creates a new shipping client
+func NewShippingClient(baseURL string) *ShippingClient {
+	// Shipping service routes are at /api/*
+	baseURL = strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/api/v1")
+	return &ShippingClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// CreateShipment books a carrier shipment
+func (c *ShippingClient) CreateShipment(ctx context.Context, tenantID string, req *CreateShipmentRequest) (*Shipment, error) {
+	var shipment Shipment
+	if err := c.do(ctx, http.MethodPost, tenantID, "/api/shipments", req, &shipment); err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+// GetShipment fetches a shipment by ID
+func (c *ShippingClient) GetShipment(ctx context.Context, tenantID, shipmentID string) (*Shipment, error) {
+	var shipment Shipment
+	if err := c.do(ctx, http.MethodGet, tenantID, "/api/shipments/"+url.PathEscape(shipmentID), nil, &shipment); err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+// TrackShipment fetches a shipment's carrier tracking by tracking number
+func (c *ShippingClient) TrackShipment(ctx context.Context, tenantID, trackingNumber string) (*ShipmentTracking, error) {
+	var tracking ShipmentTracking
+	if err := c.do(ctx, http.MethodGet, tenantID, "/api/track/"+url.PathEscape(trackingNumber), nil, &tracking); err != nil {
+		return nil, err
+	}
+	return &tracking, nil
+}
+
+// CancelShipment cancels a booked shipment
+func (c *ShippingClient) CancelShipment(ctx context.Context, tenantID, shipmentID, reason string) error {
+	body := map[string]string{"reason": reason}
+	return c.do(ctx, http.MethodPut, tenantID, "/api/shipments/"+url.PathEscape(shipmentID)+"/cancel", body, nil)
+}
+
+// do sends a request to shipping-service and decodes the data of its response
+func (c *ShippingClient) do(ctx context.Context, method, tenantID, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", tenantID)
+	req.Header.Set("X-Internal-Service", "inventory-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach shipping-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrShipmentNotFound
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("shipping-service returned status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	result := struct {
+		Data interface{} `json:"data"`
+	}{Data: out}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+
+	"inventory-service/internal/tenancy"
 )
 
 type Config struct {
@@ -24,8 +27,9 @@ type Config struct {
 	RedisURL string
 
 	// Server
-	Port        string
-	Environment string
+	Port            string
+	Environment     string
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
 
 	// JWT
 	JWTSecret string
@@ -33,6 +37,18 @@ type Config struct {
 	// NATS
 	NATSURL string
 
+	// Products service, used to resolve SKUs for storefront availability
+	ProductsServiceURL string
+
+	// Shipping service, used to book and track inventory transfer shipments
+	ShippingServiceURL string
+
+	// Approval service, decides stock adjustments above the tenant's thresholds
+	ApprovalServiceURL string
+
+	// How often expired stock reservations are released
+	ReservationExpiryInterval time.Duration
+
 	// Pagination
 	DefaultPageSize int
 	MaxPageSize     int
@@ -56,8 +72,9 @@ func Load() *Config {
 		RedisURL: getEnv("REDIS_URL", "redis://redis.redis-marketplace.svc.cluster.local:6379/0"),
 
 		// Server
-		Port:        getEnv("PORT", "8088"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Port:            getEnv("PORT", "8088"),
+		Environment:     getEnv("ENVIRONMENT", "development"),
+		ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
 
 		// JWT - fetch from GCP Secret Manager if enabled
 		JWTSecret: secrets.GetJWTSecret(),
@@ -65,6 +82,18 @@ func Load() *Config {
 		// NATS
 		NATSURL: getEnv("NATS_URL", ""),
 
+		// Products service
+		ProductsServiceURL: getEnv("PRODUCTS_SERVICE_URL", "http://products-service.marketplace.svc.cluster.local:8080"),
+
+		// Shipping service
+		ShippingServiceURL: getEnv("SHIPPING_SERVICE_URL", "http://shipping-service.marketplace.svc.cluster.local:8080"),
+
+		// Approval service
+		ApprovalServiceURL: getEnv("APPROVAL_SERVICE_URL", "http://approval-service.marketplace.svc.cluster.local:8099"),
+
+		// Reservations
+		ReservationExpiryInterval: getEnvAsDuration("RESERVATION_EXPIRY_INTERVAL", 30*time.Second),
+
 		// Pagination
 		DefaultPageSize: defaultPageSize,
 		MaxPageSize:     maxPageSize,
@@ -90,6 +119,11 @@ func InitDB(cfg *Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
+	}
+
 	return db, nil
 }
 
@@ -99,3 +133,12 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
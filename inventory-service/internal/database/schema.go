@@ -0,0 +1,39 @@
+package database
+
+import (
+	"gorm.io/gorm"
+
+	"inventory-service/internal/models"
+)
+
+// serviceName names the service in the migration lock key and log messages
+const serviceName = "inventory-service"
+
+// BaselineVersion is the last SQL migration folded into the model schema. Databases that
+// predate versioned migrations were built by AutoMigrate, and new databases get the same
+// schema from SyncModels, so only files numbered after it are applied from SQL.
+const BaselineVersion = 8
+
+// SyncModels brings the schema in line with the GORM models. It must only run while holding
+// the migration lock.
+func SyncModels(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&models.Warehouse{},
+		&models.Supplier{},
+		&models.SupplierCatalogEntry{},
+		&models.PurchaseOrder{},
+		&models.PurchaseOrderItem{},
+		&models.PurchaseOrderLandedCost{},
+		&models.InventoryTransfer{},
+		&models.InventoryTransferItem{},
+		&models.InventoryAdjustmentProposal{},
+		&models.AdjustmentApprovalSettings{},
+		&models.StockLevel{},
+		&models.WarehouseLocation{},
+		&models.BinStock{},
+		&models.InventoryReservation{},
+		&models.InventoryAlert{},
+		&models.AlertThreshold{},
+		&models.ImportMappingPreset{},
+	)
+}
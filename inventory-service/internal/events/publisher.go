@@ -6,10 +6,118 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/Tesseract-Nexus/go-shared/events"
+	"inventory-service/internal/models"
 )
 
+// Warehouse event types. shipping-service keeps its ship-from address book in sync from these.
+const (
+	WarehouseUpserted = "inventory.warehouse_upserted"
+	WarehouseDeleted  = "inventory.warehouse_deleted"
+)
+
+// WarehouseEvent carries a warehouse's address and contact details
+type WarehouseEvent struct {
+	events.BaseEvent
+	WarehouseID string `json:"warehouseId"`
+	VendorID    string `json:"vendorId,omitempty"`
+	Code        string `json:"code,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Address1    string `json:"address1,omitempty"`
+	Address2    string `json:"address2,omitempty"`
+	City        string `json:"city,omitempty"`
+	State       string `json:"state,omitempty"`
+	PostalCode  string `json:"postalCode,omitempty"`
+	Country     string `json:"country,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+	Email       string `json:"email,omitempty"`
+	ManagerName string `json:"managerName,omitempty"`
+	IsDefault   bool   `json:"isDefault"`
+}
+
+// GetSubject returns the NATS subject for this event
+func (e *WarehouseEvent) GetSubject() string {
+	return e.EventType
+}
+
+// GetStream returns the NATS stream name for this event
+func (e *WarehouseEvent) GetStream() string {
+	return events.StreamInventory
+}
+
+// LandedCostsReceived is published when a purchase order receipt records landed costs.
+// products-service keeps product and variant cost prices in sync from it.
+const LandedCostsReceived = "inventory.landed_costs_received"
+
+// LandedCostLine is the landed unit cost of one received purchase order line
+type LandedCostLine struct {
+	ProductID      string  `json:"productId"`
+	VariantID      string  `json:"variantId,omitempty"`
+	Quantity       int     `json:"quantity"`
+	LandedUnitCost float64 `json:"landedUnitCost"`
+}
+
+// LandedCostEvent carries the landed costs of a purchase order receipt
+type LandedCostEvent struct {
+	events.BaseEvent
+	PurchaseOrderID string           `json:"purchaseOrderId"`
+	WarehouseID     string           `json:"warehouseId"`
+	CurrencyCode    string           `json:"currencyCode"`
+	Lines           []LandedCostLine `json:"lines"`
+}
+
+// GetSubject returns the NATS subject for this event
+func (e *LandedCostEvent) GetSubject() string {
+	return e.EventType
+}
+
+// GetStream returns the NATS stream name for this event
+func (e *LandedCostEvent) GetStream() string {
+	return events.StreamInventory
+}
+
+// Reservation event types. orders-service and customers-service carts use them to follow stock
+// held at checkout.
+const (
+	ReservationCreated   = "inventory.reservation_created"
+	ReservationConfirmed = "inventory.reservation_confirmed"
+	ReservationReleased  = "inventory.reservation_released"
+	ReservationExpired   = "inventory.reservation_expired"
+)
+
+// ReservationLine is one reserved product or variant
+type ReservationLine struct {
+	ProductID   string `json:"productId"`
+	VariantID   string `json:"variantId,omitempty"`
+	WarehouseID string `json:"warehouseId"`
+	Quantity    int    `json:"quantity"`
+}
+
+// ReservationEvent carries a reservation's lines after it was created or settled
+type ReservationEvent struct {
+	events.BaseEvent
+	ReservationID string            `json:"reservationId"`
+	OrderID       string            `json:"orderId,omitempty"`
+	CartID        string            `json:"cartId,omitempty"`
+	Status        string            `json:"status"`
+	ExpiresAt     time.Time         `json:"expiresAt"`
+	Reason        string            `json:"reason,omitempty"`
+	Lines         []ReservationLine `json:"lines"`
+}
+
+// GetSubject returns the NATS subject for this event
+func (e *ReservationEvent) GetSubject() string {
+	return e.EventType
+}
+
+// GetStream returns the NATS stream name for this event
+func (e *ReservationEvent) GetStream() string {
+	return events.StreamInventory
+}
+
 // InventoryEventPublisher handles publishing inventory-related events to NATS
 type InventoryEventPublisher struct {
 	publisher *events.Publisher
@@ -160,6 +268,159 @@ func (p *InventoryEventPublisher) PublishStockAdjusted(ctx context.Context, tena
 	return nil
 }
 
+// PublishAdjustmentApplied publishes an inventory.adjusted event for an applied adjustment
+// proposal
+func (p *InventoryEventPublisher) PublishAdjustmentApplied(ctx context.Context, proposal *models.InventoryAdjustmentProposal) error {
+	if proposal.PreviousOnHand == nil || proposal.NewOnHand == nil {
+		return nil
+	}
+	adjustedBy := ""
+	if proposal.ResolvedBy != nil {
+		adjustedBy = *proposal.ResolvedBy
+	}
+	return p.PublishStockAdjusted(ctx, proposal.TenantID, proposal.ProductID.String(), "", "",
+		*proposal.PreviousOnHand, *proposal.NewOnHand, string(proposal.Reason), adjustedBy, proposal.WarehouseID.String(), "")
+}
+
+// PublishWarehouseUpserted publishes an inventory.warehouse_upserted event after a warehouse is created or updated
+func (p *InventoryEventPublisher) PublishWarehouseUpserted(ctx context.Context, tenantID string, warehouse *models.Warehouse) error {
+	event := &WarehouseEvent{
+		BaseEvent: events.BaseEvent{
+			EventType: WarehouseUpserted,
+			TenantID:  tenantID,
+			Timestamp: time.Now().UTC(),
+		},
+		WarehouseID: warehouse.ID.String(),
+		VendorID:    warehouse.VendorID,
+		Code:        warehouse.Code,
+		Name:        warehouse.Name,
+		Status:      string(warehouse.Status),
+		Address1:    warehouse.Address1,
+		Address2:    derefString(warehouse.Address2),
+		City:        warehouse.City,
+		State:       warehouse.State,
+		PostalCode:  warehouse.PostalCode,
+		Country:     warehouse.Country,
+		Phone:       derefString(warehouse.Phone),
+		Email:       derefString(warehouse.Email),
+		ManagerName: derefString(warehouse.ManagerName),
+		IsDefault:   warehouse.IsDefault,
+	}
+
+	if err := p.publisher.Publish(ctx, event); err != nil {
+		p.logger.WithField("warehouseId", event.WarehouseID).WithError(err).Error("Failed to publish inventory.warehouse_upserted event")
+		return err
+	}
+
+	p.logger.WithField("warehouseId", event.WarehouseID).Info("Published inventory.warehouse_upserted event")
+	return nil
+}
+
+// PublishWarehouseDeleted publishes an inventory.warehouse_deleted event
+func (p *InventoryEventPublisher) PublishWarehouseDeleted(ctx context.Context, tenantID string, warehouseID string) error {
+	event := &WarehouseEvent{
+		BaseEvent: events.BaseEvent{
+			EventType: WarehouseDeleted,
+			TenantID:  tenantID,
+			Timestamp: time.Now().UTC(),
+		},
+		WarehouseID: warehouseID,
+	}
+
+	if err := p.publisher.Publish(ctx, event); err != nil {
+		p.logger.WithField("warehouseId", warehouseID).WithError(err).Error("Failed to publish inventory.warehouse_deleted event")
+		return err
+	}
+
+	p.logger.WithField("warehouseId", warehouseID).Info("Published inventory.warehouse_deleted event")
+	return nil
+}
+
+// PublishLandedCostsReceived publishes an inventory.landed_costs_received event for a purchase order receipt
+func (p *InventoryEventPublisher) PublishLandedCostsReceived(ctx context.Context, tenantID string, costs []models.PurchaseOrderLandedCost) error {
+	if len(costs) == 0 {
+		return nil
+	}
+	event := &LandedCostEvent{
+		BaseEvent: events.BaseEvent{
+			EventType: LandedCostsReceived,
+			TenantID:  tenantID,
+			Timestamp: time.Now().UTC(),
+		},
+		PurchaseOrderID: costs[0].PurchaseOrderID.String(),
+		WarehouseID:     costs[0].WarehouseID.String(),
+		CurrencyCode:    costs[0].CurrencyCode,
+		Lines:           make([]LandedCostLine, 0, len(costs)),
+	}
+	for _, cost := range costs {
+		line := LandedCostLine{
+			ProductID:      cost.ProductID.String(),
+			Quantity:       cost.Quantity,
+			LandedUnitCost: cost.LandedUnitCost,
+		}
+		if cost.VariantID != nil {
+			line.VariantID = cost.VariantID.String()
+		}
+		event.Lines = append(event.Lines, line)
+	}
+
+	if err := p.publisher.Publish(ctx, event); err != nil {
+		p.logger.WithField("purchaseOrderId", event.PurchaseOrderID).WithError(err).Error("Failed to publish inventory.landed_costs_received event")
+		return err
+	}
+
+	p.logger.WithField("purchaseOrderId", event.PurchaseOrderID).Info("Published inventory.landed_costs_received event")
+	return nil
+}
+
+// PublishReservation publishes a reservation event of eventType, e.g. ReservationExpired
+func (p *InventoryEventPublisher) PublishReservation(ctx context.Context, eventType, tenantID string, reservation *models.Reservation, reason string) error {
+	event := &ReservationEvent{
+		BaseEvent: events.BaseEvent{
+			EventType: eventType,
+			TenantID:  tenantID,
+			Timestamp: time.Now().UTC(),
+		},
+		ReservationID: reservation.ID.String(),
+		OrderID:       derefUUID(reservation.OrderID),
+		CartID:        derefUUID(reservation.CartID),
+		Status:        reservation.Status,
+		ExpiresAt:     reservation.ExpiresAt,
+		Reason:        reason,
+		Lines:         make([]ReservationLine, 0, len(reservation.Items)),
+	}
+	for _, item := range reservation.Items {
+		event.Lines = append(event.Lines, ReservationLine{
+			ProductID:   item.ProductID.String(),
+			VariantID:   derefUUID(item.VariantID),
+			WarehouseID: item.WarehouseID.String(),
+			Quantity:    item.Quantity,
+		})
+	}
+
+	if err := p.publisher.Publish(ctx, event); err != nil {
+		p.logger.WithField("reservationId", event.ReservationID).WithError(err).Errorf("Failed to publish %s event", eventType)
+		return err
+	}
+
+	p.logger.WithField("reservationId", event.ReservationID).Infof("Published %s event", eventType)
+	return nil
+}
+
+func derefUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // IsConnected returns true if connected to NATS
 func (p *InventoryEventPublisher) IsConnected() bool {
 	return p.publisher.IsConnected()
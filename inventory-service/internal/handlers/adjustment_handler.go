@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"inventory-service/internal/clients"
+	"inventory-service/internal/events"
+	"inventory-service/internal/models"
+	"inventory-service/internal/repository"
+)
+
+// AdjustmentHandler handles manual stock adjustments and the thresholds above which they
+// need approval
+type AdjustmentHandler struct {
+	repo           *repository.InventoryRepository
+	approvals      *clients.ApprovalClient
+	eventPublisher *events.InventoryEventPublisher
+}
+
+func NewAdjustmentHandler(repo *repository.InventoryRepository, approvals *clients.ApprovalClient, eventPublisher *events.InventoryEventPublisher) *AdjustmentHandler {
+	return &AdjustmentHandler{
+		repo:           repo,
+		approvals:      approvals,
+		eventPublisher: eventPublisher,
+	}
+}
+
+// AdjustStock changes a warehouse's on-hand stock. Adjustments under the tenant's
+// thresholds are applied at once; larger ones are queued as pending proposals and sent to
+// approval-service, and applied when the approval arrives.
+// POST /api/v1/stock/adjust
+func (h *AdjustmentHandler) AdjustStock(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.StockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	proposal, err := h.repo.CreateStockAdjustment(c.Request.Context(), tenantID, &req, optionalString(userID))
+	if err != nil {
+		if errors.Is(err, repository.ErrAdjustmentWarehouseNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "NOT_FOUND",
+					Message: "Warehouse not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "ADJUST_FAILED",
+				Message: "Failed to adjust stock",
+			},
+		})
+		return
+	}
+
+	if !proposal.RequiresApproval {
+		if h.eventPublisher != nil {
+			go func() {
+				_ = h.eventPublisher.PublishAdjustmentApplied(context.Background(), proposal)
+			}()
+		}
+		c.JSON(http.StatusOK, models.AdjustmentProposalResponse{
+			Success: true,
+			Data:    proposal,
+			Message: stringPtr("Stock adjusted"),
+		})
+		return
+	}
+
+	// If approval-service can't be reached the proposal stays in the pending queue, where
+	// it can be approved or rejected directly
+	message := "Adjustment exceeds the approval threshold and is awaiting approval"
+	approvalID, err := h.approvals.CreateApprovalRequest(c.Request.Context(), tenantID, userID, &clients.CreateApprovalRequest{
+		WorkflowName:  clients.AdjustmentApprovalWorkflow,
+		ActionType:    clients.AdjustmentApprovalActionType,
+		ResourceType:  clients.AdjustmentApprovalResourceType,
+		ResourceID:    proposal.ID.String(),
+		Reason:        fmt.Sprintf("Stock adjustment of %+d units (%s) worth %.2f", proposal.QuantityChange, proposal.Reason, proposal.Value),
+		RequesterName: c.GetString("username"),
+		ActionData: map[string]interface{}{
+			"proposal_id":     proposal.ID.String(),
+			"warehouse_id":    proposal.WarehouseID.String(),
+			"product_id":      proposal.ProductID.String(),
+			"quantity_change": proposal.QuantityChange,
+			"unit_cost":       proposal.UnitCost,
+			"value":           proposal.Value,
+			"reason":          string(proposal.Reason),
+			"notes":           proposal.Message,
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to request approval for stock adjustment %s: %v", proposal.ID, err)
+		message = "Adjustment exceeds the approval threshold; approval-service is unavailable, so it is queued for approval here"
+	} else if err := h.repo.SetAdjustmentApprovalRequest(c.Request.Context(), tenantID, proposal.ID, approvalID); err != nil {
+		log.Printf("Failed to link stock adjustment %s to approval request %s: %v", proposal.ID, approvalID, err)
+	} else {
+		proposal.ApprovalRequestID = &approvalID
+	}
+
+	c.JSON(http.StatusAccepted, models.AdjustmentProposalResponse{
+		Success: true,
+		Data:    proposal,
+		Message: stringPtr(message),
+	})
+}
+
+// GetAdjustmentSettings returns the tenant's adjustment approval thresholds
+// GET /api/v1/stock/adjustment-settings
+func (h *AdjustmentHandler) GetAdjustmentSettings(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	settings, err := h.repo.GetAdjustmentSettings(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve adjustment settings",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.AdjustmentApprovalSettingsResponse{
+		Success: true,
+		Data:    settings,
+	})
+}
+
+// UpdateAdjustmentSettings changes the tenant's adjustment approval thresholds
+// PUT /api/v1/stock/adjustment-settings
+func (h *AdjustmentHandler) UpdateAdjustmentSettings(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.UpdateAdjustmentApprovalSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	settings, err := h.repo.GetAdjustmentSettings(c.Request.Context(), tenantID)
+	if err == nil {
+		if req.Enabled != nil {
+			settings.Enabled = *req.Enabled
+		}
+		if req.QuantityThreshold != nil {
+			settings.QuantityThreshold = *req.QuantityThreshold
+		}
+		if req.ValueThreshold != nil {
+			settings.ValueThreshold = *req.ValueThreshold
+		}
+		settings.UpdatedBy = optionalString(c.GetString("user_id"))
+		err = h.repo.UpsertAdjustmentSettings(c.Request.Context(), settings)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_FAILED",
+				Message: "Failed to update adjustment settings",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.AdjustmentApprovalSettingsResponse{
+		Success: true,
+		Data:    settings,
+		Message: stringPtr("Adjustment settings updated"),
+	})
+}
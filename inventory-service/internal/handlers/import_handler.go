@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"inventory-service/internal/bulk"
 	"inventory-service/internal/models"
 	"inventory-service/internal/repository"
 	"github.com/xuri/excelize/v2"
@@ -59,11 +60,12 @@ type ImportResult struct {
 }
 
 type ImportHandler struct {
-	repo *repository.InventoryRepository
+	repo        *repository.InventoryRepository
+	mappingRepo *repository.ImportMappingRepository
 }
 
-func NewImportHandler(repo *repository.InventoryRepository) *ImportHandler {
-	return &ImportHandler{repo: repo}
+func NewImportHandler(repo *repository.InventoryRepository, mappingRepo *repository.ImportMappingRepository) *ImportHandler {
+	return &ImportHandler{repo: repo, mappingRepo: mappingRepo}
 }
 
 // WarehouseImportTemplate returns the template for warehouses
@@ -315,6 +317,16 @@ func (h *ImportHandler) ImportWarehouses(c *gin.Context) {
 		return
 	}
 
+	mapping, err := h.resolveColumnMapping(c, tenantID.(string), "warehouses")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_MAPPING", Message: err.Error()},
+		})
+		return
+	}
+	rows = applyColumnMapping(rows, mapping)
+
 	result := h.processWarehouseRows(tenantID.(string), userID.(string), rows, skipDuplicates, validateOnly)
 	c.JSON(http.StatusOK, result)
 }
@@ -355,6 +367,16 @@ func (h *ImportHandler) ImportSuppliers(c *gin.Context) {
 		return
 	}
 
+	mapping, err := h.resolveColumnMapping(c, tenantID.(string), "suppliers")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_MAPPING", Message: err.Error()},
+		})
+		return
+	}
+	rows = applyColumnMapping(rows, mapping)
+
 	result := h.processSupplierRows(tenantID.(string), userID.(string), rows, skipDuplicates, validateOnly)
 	c.JSON(http.StatusOK, result)
 }
@@ -548,36 +570,11 @@ func (h *ImportHandler) processWarehouseRows(tenantID, userID string, rows []map
 		return result
 	}
 
-	bulkResult, err := h.repo.BulkCreateWarehouses(tenantID, warehouses, skipDuplicates)
-	if err != nil && bulkResult.Success == 0 {
-		result.Success = false
-		result.Errors = append(result.Errors, ImportRowError{
-			Row:     0,
-			Code:    "BULK_CREATE_FAILED",
-			Message: err.Error(),
-		})
-		return result
-	}
-
-	for _, wh := range bulkResult.Created {
-		result.CreatedIDs = append(result.CreatedIDs, wh.ID.String())
-	}
-
-	for _, bulkErr := range bulkResult.Errors {
-		rowNum := 0
-		if bulkErr.Index < len(rows) {
-			rowNum, _ = strconv.Atoi(rows[bulkErr.Index]["_row"])
-		}
-		result.Errors = append(result.Errors, ImportRowError{
-			Row:     rowNum,
-			Code:    bulkErr.Code,
-			Message: bulkErr.Message,
-		})
-	}
-
-	result.Success = bulkResult.Success > 0
-	result.SuccessCount = bulkResult.Success
-	result.FailedCount = bulkResult.Failed + (result.TotalRows - len(warehouses))
+	bulkResult := h.repo.BulkCreateWarehouses(tenantID, warehouses, skipDuplicates, bulk.Options{})
+	recordBulkResult(result, bulkResult, rows, func(data interface{}) string {
+		return data.(*models.Warehouse).ID.String()
+	})
+	result.FailedCount += result.TotalRows - len(warehouses)
 
 	return result
 }
@@ -704,38 +701,37 @@ func (h *ImportHandler) processSupplierRows(tenantID, userID string, rows []map[
 		return result
 	}
 
-	bulkResult, err := h.repo.BulkCreateSuppliers(tenantID, suppliers, skipDuplicates)
-	if err != nil && bulkResult.Success == 0 {
-		result.Success = false
-		result.Errors = append(result.Errors, ImportRowError{
-			Row:     0,
-			Code:    "BULK_CREATE_FAILED",
-			Message: err.Error(),
-		})
-		return result
-	}
+	bulkResult := h.repo.BulkCreateSuppliers(tenantID, suppliers, skipDuplicates, bulk.Options{})
+	recordBulkResult(result, bulkResult, rows, func(data interface{}) string {
+		return data.(*models.Supplier).ID.String()
+	})
+	result.FailedCount += result.TotalRows - len(suppliers)
 
-	for _, sup := range bulkResult.Created {
-		result.CreatedIDs = append(result.CreatedIDs, sup.ID.String())
-	}
+	return result
+}
 
-	for _, bulkErr := range bulkResult.Errors {
-		rowNum := 0
-		if bulkErr.Index < len(rows) {
-			rowNum, _ = strconv.Atoi(rows[bulkErr.Index]["_row"])
+// recordBulkResult copies the per-row outcome of a bulk create into an import result
+func recordBulkResult(result *ImportResult, bulkResult *bulk.Response, rows []map[string]string, createdID func(data interface{}) string) {
+	for _, item := range bulkResult.Results {
+		switch item.Status {
+		case bulk.StatusCreated:
+			result.CreatedIDs = append(result.CreatedIDs, createdID(item.Data))
+		case bulk.StatusFailed:
+			rowNum := 0
+			if item.Index < len(rows) {
+				rowNum, _ = strconv.Atoi(rows[item.Index]["_row"])
+			}
+			result.Errors = append(result.Errors, ImportRowError{
+				Row:     rowNum,
+				Code:    item.Error.Code,
+				Message: item.Error.Message,
+			})
 		}
-		result.Errors = append(result.Errors, ImportRowError{
-			Row:     rowNum,
-			Code:    bulkErr.Code,
-			Message: bulkErr.Message,
-		})
 	}
 
-	result.Success = bulkResult.Success > 0
-	result.SuccessCount = bulkResult.Success
-	result.FailedCount = bulkResult.Failed + (result.TotalRows - len(suppliers))
-
-	return result
+	result.Success = bulkResult.SuccessCount > 0
+	result.SuccessCount = bulkResult.SuccessCount
+	result.FailedCount = bulkResult.FailedCount
 }
 
 func strPtr(s string) *string {
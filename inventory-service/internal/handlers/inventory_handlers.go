@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"inventory-service/internal/bulk"
 	"inventory-service/internal/events"
 	"inventory-service/internal/models"
 	"inventory-service/internal/repository"
@@ -23,6 +26,38 @@ func NewInventoryHandler(repo *repository.InventoryRepository, eventPublisher *e
 	}
 }
 
+// publishWarehouseUpserted lets shipping-service sync the warehouse into its ship-from address book (non-blocking)
+func (h *InventoryHandler) publishWarehouseUpserted(tenantID string, warehouse *models.Warehouse) {
+	if h.eventPublisher == nil || warehouse == nil {
+		return
+	}
+	go func() {
+		_ = h.eventPublisher.PublishWarehouseUpserted(context.Background(), tenantID, warehouse)
+	}()
+}
+
+// publishWarehouseDeleted lets shipping-service drop the warehouse from its ship-from address book (non-blocking)
+func (h *InventoryHandler) publishWarehouseDeleted(tenantID string, warehouseIDs ...string) {
+	if h.eventPublisher == nil || len(warehouseIDs) == 0 {
+		return
+	}
+	go func() {
+		for _, id := range warehouseIDs {
+			_ = h.eventPublisher.PublishWarehouseDeleted(context.Background(), tenantID, id)
+		}
+	}()
+}
+
+// publishLandedCosts lets products-service update cost prices from a receipt's landed costs (non-blocking)
+func (h *InventoryHandler) publishLandedCosts(tenantID string, costs []models.PurchaseOrderLandedCost) {
+	if h.eventPublisher == nil || len(costs) == 0 {
+		return
+	}
+	go func() {
+		_ = h.eventPublisher.PublishLandedCostsReceived(context.Background(), tenantID, costs)
+	}()
+}
+
 // ========== Warehouse Handlers ==========
 
 // CreateWarehouse creates a new warehouse
@@ -78,6 +113,11 @@ func (h *InventoryHandler) CreateWarehouse(c *gin.Context) {
 		warehouse.Priority = *req.Priority
 	}
 
+	if req.PickupEnabled != nil {
+		warehouse.PickupEnabled = *req.PickupEnabled
+	}
+	warehouse.PickupInstructions = req.PickupInstructions
+
 	if err := h.repo.CreateWarehouse(tenantID.(string), warehouse); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -89,6 +129,8 @@ func (h *InventoryHandler) CreateWarehouse(c *gin.Context) {
 		return
 	}
 
+	h.publishWarehouseUpserted(tenantID.(string), warehouse)
+
 	c.JSON(http.StatusCreated, models.WarehouseResponse{
 		Success: true,
 		Data:    warehouse,
@@ -233,6 +275,7 @@ func (h *InventoryHandler) UpdateWarehouse(c *gin.Context) {
 	}
 
 	warehouse, _ := h.repo.GetWarehouseByID(tenantID.(string), id)
+	h.publishWarehouseUpserted(tenantID.(string), warehouse)
 
 	c.JSON(http.StatusOK, models.WarehouseResponse{
 		Success: true,
@@ -269,6 +312,8 @@ func (h *InventoryHandler) DeleteWarehouse(c *gin.Context) {
 		return
 	}
 
+	h.publishWarehouseDeleted(tenantID.(string), id.String())
+
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
 		Message: stringPtr("Warehouse deleted successfully"),
@@ -738,7 +783,9 @@ func (h *InventoryHandler) ReceivePurchaseOrder(c *gin.Context) {
 	}
 
 	var req struct {
-		ReceivedItems map[string]int `json:"receivedItems" binding:"required"`
+		ReceivedItems map[string]int               `json:"receivedItems" binding:"required"`
+		Putaway       map[string]string            `json:"putaway,omitempty"` // Item ID to the bin its units go in
+		LandedCosts   []models.LandedCostComponent `json:"landedCosts,omitempty" binding:"dive"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -762,19 +809,36 @@ func (h *InventoryHandler) ReceivePurchaseOrder(c *gin.Context) {
 		receivedItems[itemID] = qty
 	}
 
-	if err := h.repo.ReceivePurchaseOrder(tenantID.(string), id, receivedItems); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Success: false,
-			Error: models.Error{
-				Code:    "RECEIVE_FAILED",
-				Message: "Failed to receive purchase order",
-			},
-		})
+	putaway := make(map[uuid.UUID]uuid.UUID)
+	for itemIDStr, binIDStr := range req.Putaway {
+		itemID, err := uuid.Parse(itemIDStr)
+		if err != nil {
+			continue
+		}
+		binID, err := uuid.Parse(binIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "VALIDATION_ERROR",
+					Message: "Invalid putaway location ID " + binIDStr,
+				},
+			})
+			return
+		}
+		putaway[itemID] = binID
+	}
+
+	receipt, err := h.repo.ReceivePurchaseOrder(tenantID.(string), id, receivedItems, putaway, req.LandedCosts)
+	if err != nil {
+		respondLocationError(c, err, "RECEIVE_FAILED", "Failed to receive purchase order")
 		return
 	}
+	h.publishLandedCosts(tenantID.(string), receipt.LandedCosts)
 
-	c.JSON(http.StatusOK, models.SuccessResponse{
+	c.JSON(http.StatusOK, models.PurchaseOrderReceiptResponse{
 		Success: true,
+		Data:    receipt,
 		Message: stringPtr("Purchase order received successfully"),
 	})
 }
@@ -958,16 +1022,26 @@ func (h *InventoryHandler) UpdateTransferStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.UpdateTransferStatus(tenantID.(string), id, req.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+	// Status changes that move stock go through the transfer workflow
+	switch req.Status {
+	case models.InventoryTransferStatusInTransit:
+		err = h.repo.ShipInventoryTransfer(c.Request.Context(), tenantID.(string), id, nil, nil, nil)
+	case models.InventoryTransferStatusCancelled:
+		err = h.repo.CancelInventoryTransfer(c.Request.Context(), tenantID.(string), id)
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
-				Code:    "UPDATE_FAILED",
-				Message: "Failed to update transfer status",
+				Code:    "VALIDATION_ERROR",
+				Message: "Status must be IN_TRANSIT or CANCELLED; use /complete or /receive to complete a transfer",
 			},
 		})
 		return
 	}
+	if err != nil {
+		respondTransferError(c, err, "UPDATE_FAILED", "Failed to update transfer status")
+		return
+	}
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
@@ -1019,14 +1093,24 @@ func (h *InventoryHandler) CompleteInventoryTransfer(c *gin.Context) {
 		}
 	}
 
-	if err := h.repo.CompleteInventoryTransfer(tenantID.(string), id, receivedItems); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Success: false,
-			Error: models.Error{
-				Code:    "COMPLETE_FAILED",
-				Message: "Failed to complete inventory transfer",
-			},
-		})
+	// In-transit transfers are received, so discrepancies are recorded
+	transfer, err := h.repo.GetInventoryTransferByID(tenantID.(string), id)
+	if err != nil {
+		respondTransferError(c, repository.ErrTransferNotFound, "", "")
+		return
+	}
+	if transfer.Status == models.InventoryTransferStatusInTransit {
+		items := make([]models.ReceiveTransferItemRequest, 0, len(receivedItems))
+		for itemID, qty := range receivedItems {
+			items = append(items, models.ReceiveTransferItemRequest{ItemID: itemID, QuantityReceived: qty})
+		}
+		userID := c.GetString("user_id")
+		_, err = h.repo.ReceiveInventoryTransfer(c.Request.Context(), tenantID.(string), id, items, optionalString(userID))
+	} else {
+		err = h.repo.CompleteInventoryTransfer(tenantID.(string), id, receivedItems)
+	}
+	if err != nil {
+		respondTransferError(c, err, "COMPLETE_FAILED", "Failed to complete inventory transfer")
 		return
 	}
 
@@ -1089,6 +1173,11 @@ func (h *InventoryHandler) GetStockLevel(c *gin.Context) {
 		return
 	}
 
+	stocks := []models.StockLevel{*stock}
+	if err := h.repo.AttachBinStock(c.Request.Context(), tenantID.(string), stocks); err == nil {
+		stock = &stocks[0]
+	}
+
 	c.JSON(http.StatusOK, models.SuccessResponse{
 		Success: true,
 		Data:    stock,
@@ -1132,6 +1221,16 @@ func (h *InventoryHandler) ListStockLevels(c *gin.Context) {
 		})
 		return
 	}
+	if err := h.repo.AttachBinStock(c.Request.Context(), tenantID.(string), stocks); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve bin stock",
+			},
+		})
+		return
+	}
 
 	response := models.StockLevelResponse{
 		Success: true,
@@ -1218,12 +1317,12 @@ func (h *InventoryHandler) BulkCreateWarehouses(c *gin.Context) {
 		return
 	}
 
-	if len(req.Warehouses) > 100 {
+	if len(req.Warehouses) > bulk.MaxItems {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
 				Code:    "VALIDATION_ERROR",
-				Message: "Maximum 100 warehouses allowed per request",
+				Message: fmt.Sprintf("Maximum %d warehouses allowed per request", bulk.MaxItems),
 			},
 		})
 		return
@@ -1266,63 +1365,16 @@ func (h *InventoryHandler) BulkCreateWarehouses(c *gin.Context) {
 		warehouses[i] = warehouse
 	}
 
-	// Perform bulk create
-	result, err := h.repo.BulkCreateWarehouses(tenantID.(string), warehouses, req.SkipDuplicates)
-	if err != nil && result.Success == 0 {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Success: false,
-			Error: models.Error{
-				Code:    "BULK_CREATE_FAILED",
-				Message: err.Error(),
-			},
-		})
-		return
+	result := h.repo.BulkCreateWarehouses(tenantID.(string), warehouses, req.SkipDuplicates, bulk.ParseOptions(c))
+	for i := range result.Results {
+		result.Results[i].ExternalID = req.Warehouses[i].ExternalID
 	}
 
-	// Build response
-	results := make([]models.BulkCreateResultItem, 0)
-
-	// Add successful items
-	for _, warehouse := range result.Created {
-		var externalID *string
-		for i, item := range req.Warehouses {
-			if item.Code == warehouse.Code {
-				externalID = item.ExternalID
-				results = append(results, models.BulkCreateResultItem{
-					Index:      i,
-					ExternalID: externalID,
-					Success:    true,
-					Data:       warehouse,
-				})
-				break
-			}
-		}
-	}
-
-	// Add failed items
-	for _, bulkErr := range result.Errors {
-		var externalID *string
-		if bulkErr.Index < len(req.Warehouses) {
-			externalID = req.Warehouses[bulkErr.Index].ExternalID
-		}
-		results = append(results, models.BulkCreateResultItem{
-			Index:      bulkErr.Index,
-			ExternalID: externalID,
-			Success:    false,
-			Error: &models.Error{
-				Code:    bulkErr.Code,
-				Message: bulkErr.Message,
-			},
-		})
+	for _, data := range result.Applied() {
+		h.publishWarehouseUpserted(tenantID.(string), data.(*models.Warehouse))
 	}
 
-	c.JSON(http.StatusOK, models.BulkCreateWarehousesResponse{
-		Success:      result.Success > 0,
-		TotalCount:   result.Total,
-		SuccessCount: result.Success,
-		FailedCount:  result.Failed,
-		Results:      results,
-	})
+	c.JSON(result.HTTPStatus(), result)
 }
 
 // BulkDeleteWarehouses deletes multiple warehouses
@@ -1365,6 +1417,18 @@ func (h *InventoryHandler) BulkDeleteWarehouses(c *gin.Context) {
 		return
 	}
 
+	failed := make(map[string]bool, len(failedIDs))
+	for _, id := range failedIDs {
+		failed[id] = true
+	}
+	deletedIDs := make([]string, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		if !failed[id.String()] {
+			deletedIDs = append(deletedIDs, id.String())
+		}
+	}
+	h.publishWarehouseDeleted(tenantID.(string), deletedIDs...)
+
 	c.JSON(http.StatusOK, models.BulkDeleteResponse{
 		Success:      len(failedIDs) == 0,
 		TotalCount:   len(req.IDs),
@@ -1403,12 +1467,12 @@ func (h *InventoryHandler) BulkCreateSuppliers(c *gin.Context) {
 		return
 	}
 
-	if len(req.Suppliers) > 100 {
+	if len(req.Suppliers) > bulk.MaxItems {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
 				Code:    "VALIDATION_ERROR",
-				Message: "Maximum 100 suppliers allowed per request",
+				Message: fmt.Sprintf("Maximum %d suppliers allowed per request", bulk.MaxItems),
 			},
 		})
 		return
@@ -1448,63 +1512,12 @@ func (h *InventoryHandler) BulkCreateSuppliers(c *gin.Context) {
 		suppliers[i] = supplier
 	}
 
-	// Perform bulk create
-	result, err := h.repo.BulkCreateSuppliers(tenantID.(string), suppliers, req.SkipDuplicates)
-	if err != nil && result.Success == 0 {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Success: false,
-			Error: models.Error{
-				Code:    "BULK_CREATE_FAILED",
-				Message: err.Error(),
-			},
-		})
-		return
-	}
-
-	// Build response
-	results := make([]models.BulkCreateResultItem, 0)
-
-	// Add successful items
-	for _, supplier := range result.Created {
-		var externalID *string
-		for i, item := range req.Suppliers {
-			if item.Code == supplier.Code {
-				externalID = item.ExternalID
-				results = append(results, models.BulkCreateResultItem{
-					Index:      i,
-					ExternalID: externalID,
-					Success:    true,
-					Data:       supplier,
-				})
-				break
-			}
-		}
+	result := h.repo.BulkCreateSuppliers(tenantID.(string), suppliers, req.SkipDuplicates, bulk.ParseOptions(c))
+	for i := range result.Results {
+		result.Results[i].ExternalID = req.Suppliers[i].ExternalID
 	}
 
-	// Add failed items
-	for _, bulkErr := range result.Errors {
-		var externalID *string
-		if bulkErr.Index < len(req.Suppliers) {
-			externalID = req.Suppliers[bulkErr.Index].ExternalID
-		}
-		results = append(results, models.BulkCreateResultItem{
-			Index:      bulkErr.Index,
-			ExternalID: externalID,
-			Success:    false,
-			Error: &models.Error{
-				Code:    bulkErr.Code,
-				Message: bulkErr.Message,
-			},
-		})
-	}
-
-	c.JSON(http.StatusOK, models.BulkCreateSuppliersResponse{
-		Success:      result.Success > 0,
-		TotalCount:   result.Total,
-		SuccessCount: result.Success,
-		FailedCount:  result.Failed,
-		Results:      results,
-	})
+	c.JSON(result.HTTPStatus(), result)
 }
 
 // BulkDeleteSuppliers deletes multiple suppliers
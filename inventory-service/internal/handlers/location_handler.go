@@ -0,0 +1,417 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-service/internal/models"
+	"inventory-service/internal/repository"
+)
+
+// LocationHandler handles the bin locations within warehouses, the stock held in them,
+// pick paths and putaway suggestions
+type LocationHandler struct {
+	repo *repository.InventoryRepository
+}
+
+func NewLocationHandler(repo *repository.InventoryRepository) *LocationHandler {
+	return &LocationHandler{repo: repo}
+}
+
+// ListLocations lists a warehouse's zones, aisles, shelves and bins
+// GET /api/v1/warehouses/:id/locations
+func (h *LocationHandler) ListLocations(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	warehouseID, ok := warehouseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var locationType *models.LocationType
+	if typeStr := c.Query("type"); typeStr != "" {
+		t := models.LocationType(typeStr)
+		locationType = &t
+	}
+	var parentID *uuid.UUID
+	if parentStr := c.Query("parentId"); parentStr != "" {
+		if pid, err := uuid.Parse(parentStr); err == nil {
+			parentID = &pid
+		}
+	}
+
+	locations, err := h.repo.ListLocations(c.Request.Context(), tenantID, warehouseID, locationType, parentID)
+	if err != nil {
+		respondLocationError(c, err, "FETCH_FAILED", "Failed to retrieve locations")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.LocationListResponse{
+		Success: true,
+		Data:    locations,
+	})
+}
+
+// CreateLocation creates a zone, aisle, shelf or bin in a warehouse
+// POST /api/v1/warehouses/:id/locations
+func (h *LocationHandler) CreateLocation(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	warehouseID, ok := warehouseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	location := &models.WarehouseLocation{
+		WarehouseID: warehouseID,
+		ParentID:    req.ParentID,
+		Type:        req.Type,
+		Code:        req.Code,
+		Name:        req.Name,
+		Capacity:    req.Capacity,
+		IsActive:    true,
+	}
+	if req.PickSequence != nil {
+		location.PickSequence = *req.PickSequence
+	}
+
+	if err := h.repo.CreateLocation(c.Request.Context(), tenantID, location); err != nil {
+		respondLocationError(c, err, "CREATE_FAILED", "Failed to create location")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.LocationResponse{
+		Success: true,
+		Data:    location,
+	})
+}
+
+// UpdateLocation updates a location's name, pick sequence, capacity or active flag
+// PUT /api/v1/warehouses/:id/locations/:locationId
+func (h *LocationHandler) UpdateLocation(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	warehouseID, ok := warehouseIDParam(c)
+	if !ok {
+		return
+	}
+	locationID, ok := locationIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.PickSequence != nil {
+		updates["pick_sequence"] = *req.PickSequence
+	}
+	if req.Capacity != nil {
+		updates["capacity"] = *req.Capacity
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	location, err := h.repo.UpdateLocation(c.Request.Context(), tenantID, warehouseID, locationID, updates)
+	if err != nil {
+		respondLocationError(c, err, "UPDATE_FAILED", "Failed to update location")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.LocationResponse{
+		Success: true,
+		Data:    location,
+	})
+}
+
+// DeleteLocation deletes a location with no child locations and no stock
+// DELETE /api/v1/warehouses/:id/locations/:locationId
+func (h *LocationHandler) DeleteLocation(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	warehouseID, ok := warehouseIDParam(c)
+	if !ok {
+		return
+	}
+	locationID, ok := locationIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteLocation(c.Request.Context(), tenantID, warehouseID, locationID); err != nil {
+		respondLocationError(c, err, "DELETE_FAILED", "Failed to delete location")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: stringPtr("Location deleted successfully"),
+	})
+}
+
+// ListBinStock lists the stock held in a warehouse's bins in pick path order
+// GET /api/v1/warehouses/:id/bin-stock
+func (h *LocationHandler) ListBinStock(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	warehouseID, ok := warehouseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var locationID, productID *uuid.UUID
+	if locationStr := c.Query("locationId"); locationStr != "" {
+		if lid, err := uuid.Parse(locationStr); err == nil {
+			locationID = &lid
+		}
+	}
+	if productStr := c.Query("productId"); productStr != "" {
+		if pid, err := uuid.Parse(productStr); err == nil {
+			productID = &pid
+		}
+	}
+
+	bins, err := h.repo.ListBinStock(c.Request.Context(), tenantID, warehouseID, locationID, productID)
+	if err != nil {
+		respondLocationError(c, err, "FETCH_FAILED", "Failed to retrieve bin stock")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BinStockListResponse{
+		Success: true,
+		Data:    bins,
+	})
+}
+
+// MoveBinStock moves stock into, out of or between a warehouse's bins
+// POST /api/v1/warehouses/:id/bin-stock/move
+func (h *LocationHandler) MoveBinStock(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	warehouseID, ok := warehouseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.MoveBinStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	bins, err := h.repo.MoveBinStock(c.Request.Context(), tenantID, warehouseID, &req)
+	if err != nil {
+		respondLocationError(c, err, "MOVE_FAILED", "Failed to move bin stock")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BinStockListResponse{
+		Success: true,
+		Data:    bins,
+	})
+}
+
+// PlanPickPath plans the order to visit bins in to pick a set of items
+// POST /api/v1/warehouses/:id/pick-path
+func (h *LocationHandler) PlanPickPath(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	warehouseID, ok := warehouseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.PickPathRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	path, err := h.repo.PlanPickPath(c.Request.Context(), tenantID, warehouseID, req.Items)
+	if err != nil {
+		respondLocationError(c, err, "PICK_PATH_FAILED", "Failed to plan pick path")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PickPathResponse{
+		Success: true,
+		Data:    path,
+	})
+}
+
+// GetTransferPickPath plans the pick path for a pending transfer at its source warehouse
+// GET /api/v1/transfers/:id/pick-path
+func (h *LocationHandler) GetTransferPickPath(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, ok := transferIDParam(c)
+	if !ok {
+		return
+	}
+
+	path, err := h.repo.PlanTransferPickPath(c.Request.Context(), tenantID, id)
+	if err != nil {
+		respondTransferError(c, err, "PICK_PATH_FAILED", "Failed to plan pick path")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PickPathResponse{
+		Success: true,
+		Data:    path,
+	})
+}
+
+// GetPurchaseOrderPutaway suggests bins for a purchase order's units still to be received
+// GET /api/v1/purchase-orders/:id/putaway-suggestions
+func (h *LocationHandler) GetPurchaseOrderPutaway(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid purchase order ID",
+			},
+		})
+		return
+	}
+
+	putaway, err := h.repo.GetPurchaseOrderPutaway(c.Request.Context(), tenantID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Purchase order not found",
+			},
+		})
+		return
+	}
+	if err != nil {
+		respondLocationError(c, err, "FETCH_FAILED", "Failed to suggest putaway")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PutawayResponse{
+		Success: true,
+		Data:    putaway,
+	})
+}
+
+func warehouseIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid warehouse ID",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func locationIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("locationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid location ID",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondLocationError maps location and bin stock repository errors to HTTP responses
+func respondLocationError(c *gin.Context, err error, code, message string) {
+	switch {
+	case errors.Is(err, repository.ErrLocationNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Warehouse location not found",
+			},
+		})
+	case errors.Is(err, repository.ErrInvalidLocation):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_LOCATION",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, repository.ErrLocationCodeTaken), errors.Is(err, repository.ErrLocationInUse):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "CONFLICT",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, repository.ErrBinCapacity):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "BIN_CAPACITY_EXCEEDED",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, repository.ErrInsufficientBinStock):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INSUFFICIENT_STOCK",
+				Message: err.Error(),
+			},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    code,
+				Message: message,
+			},
+		})
+	}
+}
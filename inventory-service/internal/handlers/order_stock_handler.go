@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"inventory-service/internal/clients"
+	"inventory-service/internal/models"
+	"inventory-service/internal/repository"
+)
+
+// defaultReservationTTL is how long a checkout hold lasts when the caller doesn't say
+const defaultReservationTTL = 30 * time.Minute
+
+// OrderStockHandler serves checkout stock reservations for orders-service
+type OrderStockHandler struct {
+	repo     *repository.InventoryRepository
+	products *clients.ProductsClient
+}
+
+func NewOrderStockHandler(repo *repository.InventoryRepository, products *clients.ProductsClient) *OrderStockHandler {
+	return &OrderStockHandler{
+		repo:     repo,
+		products: products,
+	}
+}
+
+// ReserveStock holds an order's items until it is paid or abandoned
+// POST /internal/stock/reserve
+func (h *OrderStockHandler) ReserveStock(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.ReserveOrderStockRequest
+	if !bindOrderStockRequest(c, &req) {
+		return
+	}
+
+	lines, ok := resolveStockLines(c, h.products, tenantID, req.Items)
+	if !ok {
+		return
+	}
+
+	ttl := defaultReservationTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	reservations, err := h.repo.ReserveOrderStock(c.Request.Context(), tenantID, req.OrderID, lines, time.Now().Add(ttl))
+	if err != nil {
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "INSUFFICIENT_STOCK",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "RESERVE_FAILED",
+				Message: "Failed to reserve stock",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    reservations,
+	})
+}
+
+// ConfirmStock takes a paid order's reserved items out of stock
+// POST /internal/stock/confirm
+func (h *OrderStockHandler) ConfirmStock(c *gin.Context) {
+	var req models.OrderStockRequest
+	if !bindOrderStockRequest(c, &req) {
+		return
+	}
+
+	if err := h.repo.ConfirmOrderStock(c.Request.Context(), c.GetString("tenant_id"), req.OrderID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "CONFIRM_FAILED",
+				Message: "Failed to confirm reserved stock",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: stringPtr("Reserved stock confirmed"),
+	})
+}
+
+// ReleaseStock returns an abandoned order's reserved items to available stock
+// POST /internal/stock/release
+func (h *OrderStockHandler) ReleaseStock(c *gin.Context) {
+	var req models.OrderStockRequest
+	if !bindOrderStockRequest(c, &req) {
+		return
+	}
+
+	if err := h.repo.ReleaseOrderStock(c.Request.Context(), c.GetString("tenant_id"), req.OrderID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "RELEASE_FAILED",
+				Message: "Failed to release reserved stock",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: stringPtr("Reserved stock released"),
+	})
+}
+
+func bindOrderStockRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return false
+	}
+	return true
+}
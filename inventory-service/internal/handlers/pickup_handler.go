@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"inventory-service/internal/clients"
+	"inventory-service/internal/models"
+	"inventory-service/internal/repository"
+)
+
+// PickupHandler serves click-and-collect stock operations for orders-service
+type PickupHandler struct {
+	repo     *repository.InventoryRepository
+	products *clients.ProductsClient
+}
+
+func NewPickupHandler(repo *repository.InventoryRepository, products *clients.ProductsClient) *PickupHandler {
+	return &PickupHandler{
+		repo:     repo,
+		products: products,
+	}
+}
+
+// GetPickupLocation returns an active warehouse that offers click-and-collect
+// GET /internal/pickup-locations/:id
+func (h *PickupHandler) GetPickupLocation(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid pickup location ID",
+			},
+		})
+		return
+	}
+
+	warehouse, err := h.repo.GetPickupLocation(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondPickupError(c, err, "FETCH_FAILED", "Failed to retrieve pickup location")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WarehouseResponse{
+		Success: true,
+		Data:    warehouse,
+	})
+}
+
+// DeductStock deducts a click-and-collect order's items from its pickup location
+// POST /internal/stock/deduct
+func (h *PickupHandler) DeductStock(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.DeductPickupStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	lines, ok := resolveStockLines(c, h.products, tenantID, req.Items)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeductPickupStock(c.Request.Context(), tenantID, req.WarehouseID, req.OrderID, lines); err != nil {
+		h.respondPickupError(c, err, "DEDUCT_FAILED", "Failed to deduct stock")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: stringPtr("Stock deducted successfully"),
+	})
+}
+
+// RestoreStock returns a cancelled click-and-collect order's items to its pickup location
+// POST /internal/stock/restore
+func (h *PickupHandler) RestoreStock(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.RestorePickupStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if err := h.repo.RestorePickupStock(c.Request.Context(), tenantID, req.OrderID); err != nil {
+		h.respondPickupError(c, err, "RESTORE_FAILED", "Failed to restore stock")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: stringPtr("Stock restored successfully"),
+	})
+}
+
+// resolveStockLines turns order items into reservation lines. Variant stock is tracked
+// separately; a product-level SKU resolves without a variant. It responds and returns false
+// when products-service can't resolve a SKU.
+func resolveStockLines(c *gin.Context, products *clients.ProductsClient, tenantID string, items []models.PickupStockItem) ([]models.InventoryReservation, bool) {
+	lines := make([]models.InventoryReservation, 0, len(items))
+	for _, item := range items {
+		line := models.InventoryReservation{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+		if item.SKU != "" {
+			match, err := products.ResolveSKU(c.Request.Context(), tenantID, item.SKU)
+			if err != nil && !errors.Is(err, clients.ErrSKUNotFound) {
+				c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+					Success: false,
+					Error: models.Error{
+						Code:    "SERVICE_UNAVAILABLE",
+						Message: "Failed to resolve SKU " + item.SKU,
+					},
+				})
+				return nil, false
+			}
+			if match != nil && match.ProductID == item.ProductID {
+				line.VariantID = match.VariantID
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, true
+}
+
+// respondPickupError maps click-and-collect repository errors to HTTP responses
+func (h *PickupHandler) respondPickupError(c *gin.Context, err error, code, message string) {
+	switch {
+	case errors.Is(err, repository.ErrPickupLocationNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Pickup location not found",
+			},
+		})
+	case errors.Is(err, repository.ErrInsufficientPickupStock):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INSUFFICIENT_STOCK",
+				Message: err.Error(),
+			},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    code,
+				Message: message,
+			},
+		})
+	}
+}
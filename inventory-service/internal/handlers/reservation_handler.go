@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"inventory-service/internal/clients"
+	"inventory-service/internal/events"
+	"inventory-service/internal/models"
+	"inventory-service/internal/repository"
+)
+
+// ReservationHandler serves stock reservations held for an order or a cart until checkout
+// completes, with a TTL after which the reservation expiry job releases them
+type ReservationHandler struct {
+	repo           *repository.InventoryRepository
+	products       *clients.ProductsClient
+	eventPublisher *events.InventoryEventPublisher
+}
+
+func NewReservationHandler(repo *repository.InventoryRepository, products *clients.ProductsClient, eventPublisher *events.InventoryEventPublisher) *ReservationHandler {
+	return &ReservationHandler{
+		repo:           repo,
+		products:       products,
+		eventPublisher: eventPublisher,
+	}
+}
+
+// CreateReservation holds an order's or a cart's items
+// POST /api/v1/reservations
+func (h *ReservationHandler) CreateReservation(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.CreateReservationRequest
+	if !bindOrderStockRequest(c, &req) {
+		return
+	}
+	if (req.OrderID == nil) == (req.CartID == nil) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "Exactly one of orderId and cartId is required",
+			},
+		})
+		return
+	}
+
+	lines, ok := resolveStockLines(c, h.products, tenantID, req.Items)
+	if !ok {
+		return
+	}
+
+	ttl := defaultReservationTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	reservation, created, err := h.repo.ReserveStock(c.Request.Context(), tenantID, req.OrderID, req.CartID, lines, time.Now().Add(ttl))
+	if err != nil {
+		h.respondReservationError(c, err, "RESERVE_FAILED", "Failed to reserve stock")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		h.publish(events.ReservationCreated, tenantID, reservation, "")
+	}
+	c.JSON(status, models.SuccessResponse{
+		Success: true,
+		Data:    reservation,
+	})
+}
+
+// GetReservation returns a reservation and its lines
+// GET /api/v1/reservations/:id
+func (h *ReservationHandler) GetReservation(c *gin.Context) {
+	id, ok := parseReservationID(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.repo.GetReservation(c.Request.Context(), c.GetString("tenant_id"), id)
+	if err != nil {
+		h.respondReservationError(c, err, "FETCH_FAILED", "Failed to retrieve reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    reservation,
+	})
+}
+
+// ConfirmReservation takes a paid reservation's items out of stock
+// POST /api/v1/reservations/:id/confirm
+func (h *ReservationHandler) ConfirmReservation(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, ok := parseReservationID(c)
+	if !ok {
+		return
+	}
+	var req models.ConfirmReservationRequest
+	if c.Request.ContentLength > 0 && !bindOrderStockRequest(c, &req) {
+		return
+	}
+
+	reservation, settled, err := h.repo.ConfirmReservation(c.Request.Context(), tenantID, id, req.OrderID)
+	if err != nil {
+		h.respondReservationError(c, err, "CONFIRM_FAILED", "Failed to confirm reservation")
+		return
+	}
+
+	if settled {
+		h.publish(events.ReservationConfirmed, tenantID, reservation, "")
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    reservation,
+	})
+}
+
+// ReleaseReservation returns an abandoned reservation's items to available stock
+// POST /api/v1/reservations/:id/release
+func (h *ReservationHandler) ReleaseReservation(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, ok := parseReservationID(c)
+	if !ok {
+		return
+	}
+	var req models.ReleaseReservationRequest
+	if c.Request.ContentLength > 0 && !bindOrderStockRequest(c, &req) {
+		return
+	}
+
+	reservation, settled, err := h.repo.ReleaseReservation(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondReservationError(c, err, "RELEASE_FAILED", "Failed to release reservation")
+		return
+	}
+
+	if settled {
+		h.publish(events.ReservationReleased, tenantID, reservation, req.Reason)
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    reservation,
+	})
+}
+
+func (h *ReservationHandler) publish(eventType, tenantID string, reservation *models.Reservation, reason string) {
+	if h.eventPublisher == nil {
+		return
+	}
+	go func() {
+		_ = h.eventPublisher.PublishReservation(context.Background(), eventType, tenantID, reservation, reason)
+	}()
+}
+
+func parseReservationID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid reservation ID",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondReservationError maps reservation repository errors to HTTP responses
+func (h *ReservationHandler) respondReservationError(c *gin.Context, err error, code, message string) {
+	switch {
+	case errors.Is(err, repository.ErrReservationNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Reservation not found",
+			},
+		})
+	case errors.Is(err, repository.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INSUFFICIENT_STOCK",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, repository.ErrReservationSettled):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "RESERVATION_SETTLED",
+				Message: err.Error(),
+			},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    code,
+				Message: message,
+			},
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"inventory-service/internal/clients"
+	"inventory-service/internal/models"
+	"inventory-service/internal/repository"
+)
+
+// StockBatchHandler serves stock lookups for many SKUs in one call, for listing and
+// checkout in other services
+type StockBatchHandler struct {
+	repo     *repository.InventoryRepository
+	products *clients.ProductsClient
+}
+
+func NewStockBatchHandler(repo *repository.InventoryRepository, products *clients.ProductsClient) *StockBatchHandler {
+	return &StockBatchHandler{
+		repo:     repo,
+		products: products,
+	}
+}
+
+// GetStockBatch returns stock levels per warehouse for up to 500 SKUs or product/variant
+// IDs, optionally with their active reservations
+// POST /api/v1/stock/batch
+func (h *StockBatchHandler) GetStockBatch(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.StockBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	skus := make([]string, 0, len(req.SKUs))
+	for _, sku := range req.SKUs {
+		if sku = strings.TrimSpace(sku); sku != "" {
+			skus = append(skus, sku)
+		}
+	}
+	if len(skus)+len(req.Items) == 0 || len(skus)+len(req.Items) > models.MaxStockBatchSize {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: fmt.Sprintf("Between 1 and %d skus and items are required", models.MaxStockBatchSize),
+			},
+		})
+		return
+	}
+
+	matches, err := h.products.ResolveSKUs(c.Request.Context(), tenantID, skus)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "SERVICE_UNAVAILABLE",
+				Message: "SKU lookup is temporarily unavailable",
+			},
+		})
+		return
+	}
+
+	items := make([]models.StockBatchItem, 0, len(skus)+len(req.Items))
+	itemSKUs := make([]string, 0, len(skus))
+	notFound := []string{}
+	for _, sku := range skus {
+		match, ok := matches[sku]
+		if !ok {
+			notFound = append(notFound, sku)
+			continue
+		}
+		items = append(items, models.StockBatchItem{ProductID: match.ProductID, VariantID: match.VariantID})
+		itemSKUs = append(itemSKUs, sku)
+	}
+	items = append(items, req.Items...)
+
+	entries, err := h.repo.GetStockBatch(c.Request.Context(), tenantID, items, req.WarehouseID, req.IncludeReservations)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve stock levels",
+			},
+		})
+		return
+	}
+	for i, sku := range itemSKUs {
+		entries[i].SKU = sku
+	}
+
+	c.JSON(http.StatusOK, models.StockBatchResponse{
+		Success: true,
+		Data: &models.StockBatchResult{
+			Items:     entries,
+			NotFound:  notFound,
+			CheckedAt: time.Now(),
+		},
+	})
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"inventory-service/internal/clients"
+	"inventory-service/internal/models"
+	"inventory-service/internal/repository"
+)
+
+// storefrontAvailabilityMaxAge lets CDNs and browsers reuse availability briefly
+const storefrontAvailabilityMaxAge = "public, max-age=30"
+
+// StorefrontHandler serves anonymous storefront reads
+type StorefrontHandler struct {
+	repo     *repository.InventoryRepository
+	products *clients.ProductsClient
+}
+
+func NewStorefrontHandler(repo *repository.InventoryRepository, products *clients.ProductsClient) *StorefrontHandler {
+	return &StorefrontHandler{
+		repo:     repo,
+		products: products,
+	}
+}
+
+// GetAvailability returns a SKU's overall availability and its click-and-collect locations.
+// With a postcode, locations sharing more of its leading characters are listed first.
+// GET /api/v1/storefront/availability?sku=&postcode=
+func (h *StorefrontHandler) GetAvailability(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	sku := strings.TrimSpace(c.Query("sku"))
+	if sku == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "sku is required",
+			},
+		})
+		return
+	}
+
+	match, err := h.products.ResolveSKU(c.Request.Context(), tenantID, sku)
+	if errors.Is(err, clients.ErrSKUNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Product not found",
+			},
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "SERVICE_UNAVAILABLE",
+				Message: "Availability is temporarily unavailable",
+			},
+		})
+		return
+	}
+
+	availability, err := h.repo.GetStorefrontAvailability(c.Request.Context(), tenantID, match.ProductID, match.VariantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve availability",
+			},
+		})
+		return
+	}
+	availability.SKU = sku
+
+	if postcode := normalizePostcode(c.Query("postcode")); postcode != "" {
+		locations := availability.PickupLocations
+		sort.SliceStable(locations, func(i, j int) bool {
+			return postcodeMatchLength(postcode, locations[i].PostalCode) > postcodeMatchLength(postcode, locations[j].PostalCode)
+		})
+	}
+
+	c.Header("Cache-Control", storefrontAvailabilityMaxAge)
+	c.Header("Vary", "X-Tenant-ID")
+	c.JSON(http.StatusOK, models.StorefrontAvailabilityResponse{
+		Success: true,
+		Data:    availability,
+	})
+}
+
+// normalizePostcode uppercases a postcode and drops spaces and punctuation
+func normalizePostcode(postcode string) string {
+	var b strings.Builder
+	for _, r := range postcode {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// postcodeMatchLength counts the leading characters two postcodes share, a rough proximity
+// measure that works for US ZIP codes and UK/Canadian outward codes alike
+func postcodeMatchLength(normalized, other string) int {
+	other = normalizePostcode(other)
+	n := 0
+	for n < len(normalized) && n < len(other) && normalized[n] == other[n] {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,404 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-service/internal/models"
+	"inventory-service/internal/repository"
+)
+
+// SupplierCatalogHandler handles supplier price lists, supplier comparison, purchase
+// order landed costs and inventory valuation
+type SupplierCatalogHandler struct {
+	repo *repository.InventoryRepository
+}
+
+func NewSupplierCatalogHandler(repo *repository.InventoryRepository) *SupplierCatalogHandler {
+	return &SupplierCatalogHandler{repo: repo}
+}
+
+// ListSupplierCatalog lists a supplier's catalog entries
+// GET /api/v1/suppliers/:id/catalog
+func (h *SupplierCatalogHandler) ListSupplierCatalog(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	supplierID, ok := supplierIDParam(c)
+	if !ok {
+		return
+	}
+
+	page := 0
+	limit := 0
+	if p, err := parseInt(c.Query("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := parseInt(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	entries, total, err := h.repo.ListSupplierCatalog(c.Request.Context(), tenantID, supplierID, c.Query("sku"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve supplier catalog",
+			},
+		})
+		return
+	}
+
+	response := models.SupplierCatalogListResponse{
+		Success: true,
+		Data:    entries,
+	}
+	if page > 0 && limit > 0 {
+		totalPages := int(total) / limit
+		if int(total)%limit > 0 {
+			totalPages++
+		}
+		response.Pagination = &models.PaginationMeta{
+			Page:       page,
+			Limit:      limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateSupplierCatalogEntry lists a SKU in a supplier's catalog
+// POST /api/v1/suppliers/:id/catalog
+func (h *SupplierCatalogHandler) CreateSupplierCatalogEntry(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	supplierID, ok := supplierIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateSupplierCatalogEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	if req.ValidFrom != nil && req.ValidTo != nil && req.ValidTo.Before(*req.ValidFrom) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "validTo must not be before validFrom",
+			},
+		})
+		return
+	}
+
+	entry := &models.SupplierCatalogEntry{
+		SupplierID:       supplierID,
+		SKU:              req.SKU,
+		ProductID:        req.ProductID,
+		VariantID:        req.VariantID,
+		SupplierSKU:      req.SupplierSKU,
+		MinOrderQuantity: 1,
+		UnitCost:         req.UnitCost,
+		CurrencyCode:     "USD",
+		LeadTimeDays:     req.LeadTimeDays,
+		ValidFrom:        req.ValidFrom,
+		ValidTo:          req.ValidTo,
+		IsActive:         true,
+		Notes:            req.Notes,
+	}
+	if req.MinOrderQuantity != nil {
+		entry.MinOrderQuantity = *req.MinOrderQuantity
+	}
+	if req.CurrencyCode != nil {
+		entry.CurrencyCode = strings.ToUpper(*req.CurrencyCode)
+	}
+	if req.IsPreferred != nil {
+		entry.IsPreferred = *req.IsPreferred
+	}
+
+	if err := h.repo.CreateSupplierCatalogEntry(c.Request.Context(), tenantID, entry); err != nil {
+		respondCatalogError(c, err, "CREATE_FAILED", "Failed to create catalog entry")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SupplierCatalogResponse{
+		Success: true,
+		Data:    entry,
+	})
+}
+
+// UpdateSupplierCatalogEntry updates a supplier's price, MOQ, lead time or validity for a SKU
+// PUT /api/v1/suppliers/:id/catalog/:entryId
+func (h *SupplierCatalogHandler) UpdateSupplierCatalogEntry(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	supplierID, ok := supplierIDParam(c)
+	if !ok {
+		return
+	}
+	entryID, ok := catalogEntryIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSupplierCatalogEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.SupplierSKU != nil {
+		updates["supplier_sku"] = *req.SupplierSKU
+	}
+	if req.MinOrderQuantity != nil {
+		updates["min_order_quantity"] = *req.MinOrderQuantity
+	}
+	if req.UnitCost != nil {
+		updates["unit_cost"] = *req.UnitCost
+	}
+	if req.CurrencyCode != nil {
+		updates["currency_code"] = strings.ToUpper(*req.CurrencyCode)
+	}
+	if req.LeadTimeDays != nil {
+		updates["lead_time_days"] = *req.LeadTimeDays
+	}
+	if req.ValidFrom != nil {
+		updates["valid_from"] = *req.ValidFrom
+	}
+	if req.ValidTo != nil {
+		updates["valid_to"] = *req.ValidTo
+	}
+	if req.IsPreferred != nil {
+		updates["is_preferred"] = *req.IsPreferred
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+
+	entry, err := h.repo.UpdateSupplierCatalogEntry(c.Request.Context(), tenantID, supplierID, entryID, updates)
+	if err != nil {
+		respondCatalogError(c, err, "UPDATE_FAILED", "Failed to update catalog entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SupplierCatalogResponse{
+		Success: true,
+		Data:    entry,
+	})
+}
+
+// DeleteSupplierCatalogEntry removes a SKU from a supplier's catalog
+// DELETE /api/v1/suppliers/:id/catalog/:entryId
+func (h *SupplierCatalogHandler) DeleteSupplierCatalogEntry(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	supplierID, ok := supplierIDParam(c)
+	if !ok {
+		return
+	}
+	entryID, ok := catalogEntryIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteSupplierCatalogEntry(c.Request.Context(), tenantID, supplierID, entryID); err != nil {
+		respondCatalogError(c, err, "DELETE_FAILED", "Failed to delete catalog entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: stringPtr("Catalog entry deleted successfully"),
+	})
+}
+
+// CompareSuppliers ranks the suppliers listing a SKU by what a quantity would cost
+// GET /api/v1/suppliers/catalog/compare
+func (h *SupplierCatalogHandler) CompareSuppliers(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	sku := c.Query("sku")
+	if sku == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "sku is required",
+			},
+		})
+		return
+	}
+
+	quantity := 1
+	if q, err := parseInt(c.Query("quantity")); err == nil && q > 0 {
+		quantity = q
+	}
+
+	comparison, err := h.repo.CompareSuppliers(c.Request.Context(), tenantID, sku, quantity, c.Query("currency"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to compare suppliers",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SupplierComparisonResponse{
+		Success: true,
+		Data:    comparison,
+	})
+}
+
+// ListPurchaseOrderLandedCosts lists the landed costs recorded on a purchase order's receipts
+// GET /api/v1/purchase-orders/:id/landed-costs
+func (h *SupplierCatalogHandler) ListPurchaseOrderLandedCosts(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid purchase order ID",
+			},
+		})
+		return
+	}
+
+	costs, err := h.repo.ListPurchaseOrderLandedCosts(c.Request.Context(), tenantID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve landed costs",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.LandedCostListResponse{
+		Success: true,
+		Data:    costs,
+	})
+}
+
+// GetInventoryValuation values on-hand stock at weighted average landed cost
+// GET /api/v1/stock/valuation
+func (h *SupplierCatalogHandler) GetInventoryValuation(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var warehouseID *uuid.UUID
+	if warehouseIDStr := c.Query("warehouseId"); warehouseIDStr != "" {
+		if wid, err := uuid.Parse(warehouseIDStr); err == nil {
+			warehouseID = &wid
+		}
+	}
+
+	valuation, err := h.repo.GetInventoryValuation(c.Request.Context(), tenantID, warehouseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to value inventory",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.InventoryValuationResponse{
+		Success: true,
+		Data:    valuation,
+	})
+}
+
+func supplierIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid supplier ID",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func catalogEntryIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("entryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid catalog entry ID",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondCatalogError maps supplier catalog repository errors to HTTP responses
+func respondCatalogError(c *gin.Context, err error, code, message string) {
+	switch {
+	case errors.Is(err, repository.ErrCatalogEntryNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Catalog entry not found",
+			},
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Supplier not found",
+			},
+		})
+	case errors.Is(err, repository.ErrCatalogSKUTaken):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "DUPLICATE_SKU",
+				Message: err.Error(),
+			},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    code,
+				Message: message,
+			},
+		})
+	}
+}
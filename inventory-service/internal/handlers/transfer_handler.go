@@ -0,0 +1,513 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"inventory-service/internal/clients"
+	"inventory-service/internal/models"
+	"inventory-service/internal/repository"
+)
+
+// TransferHandler handles shipping, tracking and receiving inventory transfers, and the
+// adjustment proposals raised for receiving discrepancies
+type TransferHandler struct {
+	repo     *repository.InventoryRepository
+	shipping *clients.ShippingClient
+}
+
+func NewTransferHandler(repo *repository.InventoryRepository, shipping *clients.ShippingClient) *TransferHandler {
+	return &TransferHandler{
+		repo:     repo,
+		shipping: shipping,
+	}
+}
+
+// ShipTransfer dispatches a pending transfer, moving its stock into transit
+// POST /api/v1/transfers/:id/ship
+func (h *TransferHandler) ShipTransfer(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, ok := transferIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.ShipInventoryTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	shipped := make(map[uuid.UUID]int, len(req.ShippedItems))
+	for itemIDStr, qty := range req.ShippedItems {
+		itemID, err := uuid.Parse(itemIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "VALIDATION_ERROR",
+					Message: "Invalid transfer item ID " + itemIDStr,
+				},
+			})
+			return
+		}
+		shipped[itemID] = qty
+	}
+
+	transfer, err := h.repo.GetInventoryTransferByID(tenantID, id)
+	if err != nil {
+		respondTransferError(c, repository.ErrTransferNotFound, "", "")
+		return
+	}
+	if transfer.Status != models.InventoryTransferStatusPending {
+		respondTransferError(c, repository.ErrTransferState, "", "")
+		return
+	}
+
+	shipment, ok := h.resolveShipment(c, tenantID, transfer, &req)
+	if !ok {
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if err := h.repo.ShipInventoryTransfer(c.Request.Context(), tenantID, id, shipped, shipment, optionalString(userID)); err != nil {
+		// Don't leave a booked shipment behind for a transfer that didn't ship
+		if req.BookShipment != nil && shipment.ShipmentID != nil {
+			_ = h.shipping.CancelShipment(c.Request.Context(), tenantID, *shipment.ShipmentID, "Inventory transfer could not be shipped")
+		}
+		respondTransferError(c, err, "SHIP_FAILED", "Failed to ship inventory transfer")
+		return
+	}
+
+	transfer, _ = h.repo.GetInventoryTransferByID(tenantID, id)
+	c.JSON(http.StatusOK, models.InventoryTransferResponse{
+		Success: true,
+		Data:    transfer,
+		Message: stringPtr("Inventory transfer shipped successfully"),
+	})
+}
+
+// resolveShipment books, looks up or takes by hand the shipment a transfer travels on.
+// It writes the error response and returns false if shipping-service can't be used.
+func (h *TransferHandler) resolveShipment(c *gin.Context, tenantID string, transfer *models.InventoryTransfer, req *models.ShipInventoryTransferRequest) (*models.TransferShipment, bool) {
+	ctx := c.Request.Context()
+
+	var shipment *clients.Shipment
+	var err error
+	switch {
+	case req.BookShipment != nil:
+		if transfer.FromWarehouse == nil || transfer.ToWarehouse == nil {
+			respondTransferError(c, errors.New("transfer warehouses not found"), "SHIP_FAILED", "Transfer warehouses not found")
+			return nil, false
+		}
+		shipment, err = h.shipping.CreateShipment(ctx, tenantID, &clients.CreateShipmentRequest{
+			OrderID:     transfer.ID,
+			OrderNumber: transfer.TransferNumber,
+			Carrier:     req.BookShipment.Carrier,
+			ServiceType: req.BookShipment.ServiceType,
+			FromAddress: warehouseShipmentAddress(transfer.FromWarehouse),
+			ToAddress:   warehouseShipmentAddress(transfer.ToWarehouse),
+			Weight:      req.BookShipment.Weight,
+			Length:      req.BookShipment.Length,
+			Width:       req.BookShipment.Width,
+			Height:      req.BookShipment.Height,
+		})
+	case req.ShipmentID != nil && *req.ShipmentID != "":
+		shipment, err = h.shipping.GetShipment(ctx, tenantID, *req.ShipmentID)
+	default:
+		return &models.TransferShipment{
+			Carrier:        req.Carrier,
+			TrackingNumber: req.TrackingNumber,
+			TrackingURL:    req.TrackingURL,
+		}, true
+	}
+
+	if errors.Is(err, clients.ErrShipmentNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "SHIPMENT_NOT_FOUND",
+				Message: "Shipment not found",
+			},
+		})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "SHIPPING_SERVICE_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return nil, false
+	}
+
+	return &models.TransferShipment{
+		ShipmentID:     stringPtr(shipment.ID),
+		Carrier:        optionalString(shipment.Carrier),
+		TrackingNumber: optionalString(shipment.TrackingNumber),
+		TrackingURL:    optionalString(shipment.TrackingURL),
+		Status:         optionalString(shipment.Status),
+	}, true
+}
+
+// GetTransferTracking returns the carrier tracking of a transfer's shipment
+// GET /api/v1/transfers/:id/tracking
+func (h *TransferHandler) GetTransferTracking(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, ok := transferIDParam(c)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.repo.GetInventoryTransferByID(tenantID, id)
+	if err != nil {
+		respondTransferError(c, repository.ErrTransferNotFound, "", "")
+		return
+	}
+	if transfer.TrackingNumber == nil || *transfer.TrackingNumber == "" {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NO_TRACKING",
+				Message: "Transfer has no tracking number",
+			},
+		})
+		return
+	}
+
+	tracking, err := h.shipping.TrackShipment(c.Request.Context(), tenantID, *transfer.TrackingNumber)
+	if err != nil {
+		// Hand-entered tracking numbers aren't known to shipping-service
+		status := http.StatusBadGateway
+		if errors.Is(err, clients.ErrShipmentNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "TRACKING_UNAVAILABLE",
+				Message: "Tracking is not available for this shipment",
+			},
+		})
+		return
+	}
+
+	if tracking.Status != "" {
+		_ = h.repo.UpdateTransferShipmentStatus(c.Request.Context(), tenantID, id, tracking.Status)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tracking,
+	})
+}
+
+// ReceiveTransfer receives an in-transit transfer at its destination, recording any
+// discrepancies with adjustment proposals and alerts
+// POST /api/v1/transfers/:id/receive
+func (h *TransferHandler) ReceiveTransfer(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, ok := transferIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReceiveInventoryTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	result, err := h.repo.ReceiveInventoryTransfer(c.Request.Context(), tenantID, id, req.Items, optionalString(userID))
+	if err != nil {
+		respondTransferError(c, err, "RECEIVE_FAILED", "Failed to receive inventory transfer")
+		return
+	}
+
+	message := "Inventory transfer received successfully"
+	if len(result.Proposals) > 0 {
+		message = "Inventory transfer received with discrepancies"
+	}
+	c.JSON(http.StatusOK, models.ReceiveTransferResponse{
+		Success: true,
+		Data:    result,
+		Message: stringPtr(message),
+	})
+}
+
+// ListInTransitStock lists stock that is between warehouses on transfers
+// GET /api/v1/transfers/in-transit
+func (h *TransferHandler) ListInTransitStock(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var warehouseID *uuid.UUID
+	if warehouseIDStr := c.Query("warehouseId"); warehouseIDStr != "" {
+		id, err := uuid.Parse(warehouseIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "INVALID_ID",
+					Message: "Invalid warehouse ID",
+				},
+			})
+			return
+		}
+		warehouseID = &id
+	}
+
+	stock, err := h.repo.ListInTransitStock(c.Request.Context(), tenantID, warehouseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve in-transit stock",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.InTransitStockResponse{
+		Success: true,
+		Data:    stock,
+	})
+}
+
+// ListAdjustmentProposals lists adjustment proposals raised by transfer discrepancies and
+// manual stock adjustments. status=PENDING&source=MANUAL is the queue of adjustments
+// waiting for approval.
+// GET /api/v1/adjustment-proposals
+func (h *TransferHandler) ListAdjustmentProposals(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var filters repository.AdjustmentProposalFilters
+	if statusStr := c.Query("status"); statusStr != "" {
+		s := models.AdjustmentProposalStatus(statusStr)
+		filters.Status = &s
+	}
+	if sourceStr := c.Query("source"); sourceStr != "" {
+		s := models.AdjustmentSource(sourceStr)
+		filters.Source = &s
+	}
+	if transferIDStr := c.Query("transferId"); transferIDStr != "" {
+		if id, err := uuid.Parse(transferIDStr); err == nil {
+			filters.TransferID = &id
+		}
+	}
+	if warehouseIDStr := c.Query("warehouseId"); warehouseIDStr != "" {
+		if id, err := uuid.Parse(warehouseIDStr); err == nil {
+			filters.WarehouseID = &id
+		}
+	}
+
+	page := 0
+	limit := 0
+	if p, err := parseInt(c.Query("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := parseInt(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	proposals, total, err := h.repo.ListAdjustmentProposals(c.Request.Context(), tenantID, filters, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve adjustment proposals",
+			},
+		})
+		return
+	}
+
+	response := models.AdjustmentProposalListResponse{
+		Success: true,
+		Data:    proposals,
+	}
+	if page > 0 && limit > 0 {
+		totalPages := int(total) / limit
+		if int(total)%limit > 0 {
+			totalPages++
+		}
+		response.Pagination = &models.PaginationMeta{
+			Page:       page,
+			Limit:      limit,
+			TotalItems: total,
+			TotalPages: totalPages,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ApproveAdjustmentProposal applies an adjustment proposal's stock change
+// POST /api/v1/adjustment-proposals/:id/approve
+func (h *TransferHandler) ApproveAdjustmentProposal(c *gin.Context) {
+	h.resolveAdjustmentProposal(c, true)
+}
+
+// RejectAdjustmentProposal closes an adjustment proposal without changing stock
+// POST /api/v1/adjustment-proposals/:id/reject
+func (h *TransferHandler) RejectAdjustmentProposal(c *gin.Context) {
+	h.resolveAdjustmentProposal(c, false)
+}
+
+func (h *TransferHandler) resolveAdjustmentProposal(c *gin.Context, approve bool) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid adjustment proposal ID",
+			},
+		})
+		return
+	}
+
+	var req models.ResolveAdjustmentProposalRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "VALIDATION_ERROR",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+	}
+
+	userID := c.GetString("user_id")
+	proposal, err := h.repo.ResolveAdjustmentProposal(c.Request.Context(), tenantID, id, approve, optionalString(userID), req.Notes)
+	if err != nil {
+		respondTransferError(c, err, "RESOLVE_FAILED", "Failed to resolve adjustment proposal")
+		return
+	}
+
+	message := "Adjustment proposal rejected"
+	if approve {
+		message = "Adjustment proposal approved and stock adjusted"
+	}
+	c.JSON(http.StatusOK, models.AdjustmentProposalResponse{
+		Success: true,
+		Data:    proposal,
+		Message: stringPtr(message),
+	})
+}
+
+// transferIDParam parses the transfer ID path parameter, writing the error response if invalid
+func transferIDParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid transfer ID",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondTransferError maps transfer repository errors to HTTP responses
+func respondTransferError(c *gin.Context, err error, code, message string) {
+	switch {
+	case errors.Is(err, repository.ErrTransferNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Inventory transfer not found",
+			},
+		})
+	case errors.Is(err, repository.ErrProposalNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Adjustment proposal not found",
+			},
+		})
+	case errors.Is(err, repository.ErrTransferState), errors.Is(err, repository.ErrProposalResolved),
+		errors.Is(err, repository.ErrProposalAwaitingApproval):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_STATE",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, repository.ErrTransferQuantity):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, repository.ErrInsufficientTransferStock):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INSUFFICIENT_STOCK",
+				Message: err.Error(),
+			},
+		})
+	default:
+		// Receiving puts stock away in bins
+		respondLocationError(c, err, code, message)
+	}
+}
+
+// warehouseShipmentAddress converts a warehouse to a shipping-service address
+func warehouseShipmentAddress(warehouse *models.Warehouse) clients.ShipmentAddress {
+	address := clients.ShipmentAddress{
+		Name:       warehouse.Name,
+		Street:     warehouse.Address1,
+		City:       warehouse.City,
+		State:      warehouse.State,
+		PostalCode: warehouse.PostalCode,
+		Country:    warehouse.Country,
+	}
+	if warehouse.Address2 != nil {
+		address.Street2 = *warehouse.Address2
+	}
+	if warehouse.Phone != nil {
+		address.Phone = *warehouse.Phone
+	}
+	if warehouse.Email != nil {
+		address.Email = *warehouse.Email
+	}
+	return address
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,90 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"inventory-service/internal/events"
+	"inventory-service/internal/repository"
+)
+
+// ReservationExpiryJob releases reservations that outlived their TTL without being confirmed or
+// released, and announces each one so orders-service and carts can react
+type ReservationExpiryJob struct {
+	repo           *repository.InventoryRepository
+	eventPublisher *events.InventoryEventPublisher
+	logger         *logrus.Logger
+	interval       time.Duration
+	batchSize      int
+	stopCh         chan struct{}
+}
+
+// NewReservationExpiryJob creates a new reservation expiry job running every interval
+func NewReservationExpiryJob(repo *repository.InventoryRepository, eventPublisher *events.InventoryEventPublisher, logger *logrus.Logger, interval time.Duration) *ReservationExpiryJob {
+	return &ReservationExpiryJob{
+		repo:           repo,
+		eventPublisher: eventPublisher,
+		logger:         logger,
+		interval:       interval,
+		batchSize:      200,
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// Start begins the reservation expiry job
+func (j *ReservationExpiryJob) Start(ctx context.Context) {
+	j.logger.Info("Reservation expiry job started")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.run(ctx)
+		case <-j.stopCh:
+			j.logger.Info("Reservation expiry job stopped")
+			return
+		case <-ctx.Done():
+			j.logger.Info("Reservation expiry job context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *ReservationExpiryJob) Stop() {
+	close(j.stopCh)
+}
+
+func (j *ReservationExpiryJob) run(ctx context.Context) {
+	// A full batch means more have expired; keep going rather than wait for the next tick
+	for {
+		expired, scanned, err := j.repo.ExpireReservations(ctx, time.Now(), j.batchSize)
+		for i := range expired {
+			reservation := &expired[i]
+			if j.eventPublisher != nil {
+				_ = j.eventPublisher.PublishReservation(ctx, events.ReservationExpired, reservation.Items[0].TenantID, reservation, "ttl_elapsed")
+			}
+		}
+		if len(expired) > 0 {
+			j.logger.Infof("Released %d expired reservations", len(expired))
+		}
+		if err != nil {
+			j.logger.Errorf("Failed to expire reservations: %v", err)
+			return
+		}
+		if scanned < j.batchSize {
+			return
+		}
+
+		select {
+		case <-j.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
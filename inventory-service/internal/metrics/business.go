@@ -0,0 +1,58 @@
+// Package metrics records the service's business metrics next to the go-shared HTTP metrics,
+// on the same /metrics endpoint. Labels are bounded sets (operation, reason), never tenant,
+// product or warehouse IDs.
+package metrics
+
+import (
+	"strings"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reservation operations
+const (
+	ReservationReserve      = "reserve"
+	ReservationPickupDeduct = "pickup_deduct"
+)
+
+// Reservation failure reasons
+const (
+	FailureInsufficientStock = "insufficient_stock"
+	FailureNotFound          = "not_found"
+	FailureError             = "error"
+)
+
+var reservationFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tesseract",
+	Subsystem: "business",
+	Name:      "inventory_reservation_failures_total",
+	Help:      "Stock reservations and pickup deductions that failed, by operation and reason",
+}, []string{"operation", "reason"})
+
+// RecordReservationFailure counts a reservation that could not be made
+func RecordReservationFailure(operation, reason string) {
+	reservationFailuresTotal.WithLabelValues(operation, reason).Inc()
+}
+
+var reservationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tesseract",
+	Subsystem: "business",
+	Name:      "inventory_reservations_total",
+	Help:      "Stock reservations made and settled, by the status they moved to",
+}, []string{"status"})
+
+// RecordReservation counts a reservation made (ACTIVE) or settled (CONFIRMED, RELEASED, EXPIRED)
+func RecordReservation(status string) {
+	reservationsTotal.WithLabelValues(strings.ToLower(status)).Inc()
+}
+
+// RecordCacheLookup counts a stock or availability cache read as a hit or miss
+func RecordCacheLookup(keyPrefix string, hit bool) {
+	if hit {
+		gosharedmw.RecordCacheHitGlobal("redis", keyPrefix)
+	} else {
+		gosharedmw.RecordCacheMissGlobal("redis", keyPrefix)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"inventory-service/internal/tenancy"
 )
 
 // TenantMiddleware extracts tenant ID from headers
@@ -35,6 +37,8 @@ func TenantMiddleware() gin.HandlerFunc {
 
 		// Set tenant ID in context for handlers to use
 		c.Set("tenant_id", tenantID)
+		// Scope database statements made with the request context (see internal/tenancy)
+		c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), tenantID))
 		c.Next()
 	}
 }
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Thresholds used for tenants that haven't configured their own
+const (
+	DefaultAdjustmentQuantityThreshold = 100
+	DefaultAdjustmentValueThreshold    = 1000.0
+)
+
+// AdjustmentApprovalSettings are a tenant's thresholds for manual stock adjustments. An
+// adjustment whose quantity or value reaches either threshold waits for approval in
+// approval-service; a threshold of zero is not checked.
+type AdjustmentApprovalSettings struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID string    `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex"`
+
+	Enabled           bool    `json:"enabled" gorm:"not null"`
+	QuantityThreshold int     `json:"quantityThreshold" gorm:"not null"`                 // Units added or removed
+	ValueThreshold    float64 `json:"valueThreshold" gorm:"type:decimal(14,2);not null"` // Units times unit cost
+
+	UpdatedBy *string   `json:"updatedBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (AdjustmentApprovalSettings) TableName() string {
+	return "inventory_adjustment_settings"
+}
+
+// DefaultAdjustmentApprovalSettings returns the thresholds used until a tenant sets its own
+func DefaultAdjustmentApprovalSettings(tenantID string) *AdjustmentApprovalSettings {
+	return &AdjustmentApprovalSettings{
+		TenantID:          tenantID,
+		Enabled:           true,
+		QuantityThreshold: DefaultAdjustmentQuantityThreshold,
+		ValueThreshold:    DefaultAdjustmentValueThreshold,
+	}
+}
+
+// RequiresApproval reports whether an adjustment of quantityChange units worth value
+// reaches one of the thresholds
+func (s *AdjustmentApprovalSettings) RequiresApproval(quantityChange int, value float64) bool {
+	if !s.Enabled {
+		return false
+	}
+	if quantityChange < 0 {
+		quantityChange = -quantityChange
+	}
+	if s.QuantityThreshold > 0 && quantityChange >= s.QuantityThreshold {
+		return true
+	}
+	return s.ValueThreshold > 0 && value >= s.ValueThreshold
+}
+
+// StockAdjustmentRequest represents a manual change to a warehouse's on-hand stock
+type StockAdjustmentRequest struct {
+	WarehouseID    uuid.UUID        `json:"warehouseId" binding:"required"`
+	ProductID      uuid.UUID        `json:"productId" binding:"required"`
+	VariantID      *uuid.UUID       `json:"variantId,omitempty"`
+	QuantityChange int              `json:"quantityChange" binding:"required,ne=0"` // Positive adds units, negative removes them
+	Reason         AdjustmentReason `json:"reason" binding:"required,oneof=CYCLE_COUNT DAMAGED LOST FOUND CORRECTION OTHER"`
+	Notes          string           `json:"notes,omitempty"`
+}
+
+// UpdateAdjustmentApprovalSettingsRequest represents request to update adjustment thresholds
+type UpdateAdjustmentApprovalSettingsRequest struct {
+	Enabled           *bool    `json:"enabled,omitempty"`
+	QuantityThreshold *int     `json:"quantityThreshold,omitempty" binding:"omitempty,gte=0"`
+	ValueThreshold    *float64 `json:"valueThreshold,omitempty" binding:"omitempty,gte=0"`
+}
+
+// AdjustmentApprovalSettingsResponse represents response for adjustment thresholds
+type AdjustmentApprovalSettingsResponse struct {
+	Success bool                        `json:"success"`
+	Data    *AdjustmentApprovalSettings `json:"data,omitempty"`
+	Message *string                     `json:"message,omitempty"`
+}
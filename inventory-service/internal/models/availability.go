@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockStatus summarises stock for storefront display
+type StockStatus string
+
+const (
+	StockStatusInStock    StockStatus = "IN_STOCK"
+	StockStatusLowStock   StockStatus = "LOW_STOCK"
+	StockStatusOutOfStock StockStatus = "OUT_OF_STOCK"
+)
+
+// PickupLocation is a click-and-collect location's availability for one SKU.
+// It only carries what a storefront may show to anonymous shoppers.
+type PickupLocation struct {
+	WarehouseID        uuid.UUID   `json:"warehouseId"`
+	Name               string      `json:"name"`
+	Address1           string      `json:"address1"`
+	Address2           *string     `json:"address2,omitempty"`
+	City               string      `json:"city"`
+	State              string      `json:"state"`
+	PostalCode         string      `json:"postalCode"`
+	Country            string      `json:"country"`
+	Phone              *string     `json:"phone,omitempty"`
+	PickupInstructions *string     `json:"pickupInstructions,omitempty"`
+	Status             StockStatus `json:"status"`
+	QuantityAvailable  int         `json:"quantityAvailable"`
+}
+
+// StorefrontAvailability is a SKU's availability across all active locations, plus
+// the click-and-collect locations it can be picked up from
+type StorefrontAvailability struct {
+	SKU               string           `json:"sku"`
+	ProductID         uuid.UUID        `json:"productId"`
+	VariantID         *uuid.UUID       `json:"variantId,omitempty"`
+	Status            StockStatus      `json:"status"`
+	QuantityAvailable int              `json:"quantityAvailable"`
+	PickupAvailable   bool             `json:"pickupAvailable"`
+	PickupLocations   []PickupLocation `json:"pickupLocations"`
+	CheckedAt         time.Time        `json:"checkedAt"`
+}
+
+// StorefrontAvailabilityResponse represents response for storefront availability
+type StorefrontAvailabilityResponse struct {
+	Success bool                    `json:"success"`
+	Data    *StorefrontAvailability `json:"data,omitempty"`
+}
+
+// PickupStockItem is one order line fulfilled from a pickup location. The SKU tells
+// variant lines apart, since orders only carry the product ID.
+type PickupStockItem struct {
+	ProductID uuid.UUID `json:"productId" binding:"required"`
+	SKU       string    `json:"sku"`
+	Quantity  int       `json:"quantity" binding:"required,min=1"`
+}
+
+// DeductPickupStockRequest represents a request to deduct a click-and-collect order's
+// items from the chosen location
+type DeductPickupStockRequest struct {
+	WarehouseID uuid.UUID         `json:"warehouseId" binding:"required"`
+	OrderID     uuid.UUID         `json:"orderId" binding:"required"`
+	Items       []PickupStockItem `json:"items" binding:"required,min=1,dive"`
+}
+
+// RestorePickupStockRequest represents a request to return a cancelled click-and-collect
+// order's items to its location
+type RestorePickupStockRequest struct {
+	OrderID uuid.UUID `json:"orderId" binding:"required"`
+}
+
+// ReserveOrderStockRequest represents a request to hold a shipped order's items during
+// checkout. The hold lapses after TTLSeconds unless it is confirmed or released first.
+type ReserveOrderStockRequest struct {
+	OrderID    uuid.UUID         `json:"orderId" binding:"required"`
+	Items      []PickupStockItem `json:"items" binding:"required,min=1,dive"`
+	TTLSeconds int               `json:"ttlSeconds" binding:"omitempty,min=60,max=86400"`
+}
+
+// OrderStockRequest identifies the order whose reservations to confirm or release
+type OrderStockRequest struct {
+	OrderID uuid.UUID `json:"orderId" binding:"required"`
+}
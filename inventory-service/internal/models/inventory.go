@@ -66,6 +66,10 @@ type Warehouse struct {
 	LogoURL         *string `json:"logoUrl,omitempty" gorm:"column:logo_url"` // Warehouse logo/icon
 	Metadata        *JSON   `json:"metadata,omitempty" gorm:"type:jsonb"`
 
+	// Click-and-collect: customers can pick up orders at this location
+	PickupEnabled      bool    `json:"pickupEnabled" gorm:"default:false"`
+	PickupInstructions *string `json:"pickupInstructions,omitempty" gorm:"type:text"`
+
 	// Audit fields
 	CreatedAt time.Time       `json:"createdAt"`
 	UpdatedAt time.Time       `json:"updatedAt"`
@@ -106,6 +110,19 @@ type InventoryTransfer struct {
 	ShippedAt         *time.Time `json:"shippedAt,omitempty"`
 	CompletedAt       *time.Time `json:"completedAt,omitempty"`
 
+	// Shipment booked or linked through shipping-service
+	ShipmentID        *string    `json:"shipmentId,omitempty" gorm:"type:varchar(255);index"`
+	Carrier           *string    `json:"carrier,omitempty" gorm:"type:varchar(50)"`
+	TrackingNumber    *string    `json:"trackingNumber,omitempty" gorm:"type:varchar(255);index"`
+	TrackingURL       *string    `json:"trackingUrl,omitempty" gorm:"type:varchar(500)"`
+	ShipmentStatus    *string    `json:"shipmentStatus,omitempty" gorm:"type:varchar(50)"`
+	ShippedBy         *string    `json:"shippedBy,omitempty"`
+
+	// Receiving
+	ReceivedBy        *string    `json:"receivedBy,omitempty"`
+	ReceivedAt        *time.Time `json:"receivedAt,omitempty"`
+	HasDiscrepancy    bool       `json:"hasDiscrepancy" gorm:"default:false"`
+
 	Notes             *string `json:"notes,omitempty" gorm:"type:text"`
 	Metadata          *JSON   `json:"metadata,omitempty" gorm:"type:jsonb"`
 
@@ -130,6 +147,10 @@ type InventoryTransferItem struct {
 	QuantityShipped   int `json:"quantityShipped" gorm:"default:0"`
 	QuantityReceived  int `json:"quantityReceived" gorm:"default:0"`
 
+	// Received minus shipped: negative when short, positive when over
+	QuantityDiscrepancy int     `json:"quantityDiscrepancy" gorm:"default:0"`
+	DiscrepancyReason   *string `json:"discrepancyReason,omitempty" gorm:"type:text"`
+
 	Notes       *string `json:"notes,omitempty" gorm:"type:text"`
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
@@ -272,19 +293,22 @@ type Supplier struct {
 	UpdatedBy *string         `json:"updatedBy,omitempty"`
 }
 
-// InventoryReservation represents reserved inventory for orders
+// InventoryReservation represents inventory reserved for an order or a cart. Lines reserved
+// together share a ReservationID.
 type InventoryReservation struct {
-	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	TenantID    string     `json:"tenantId" gorm:"type:varchar(255);not null;index"`
-	WarehouseID uuid.UUID  `json:"warehouseId" gorm:"type:uuid;not null;index"`
-	ProductID   uuid.UUID  `json:"productId" gorm:"type:uuid;not null;index"`
-	VariantID   *uuid.UUID `json:"variantId,omitempty" gorm:"type:uuid;index"`
-
-	Quantity      int       `json:"quantity" gorm:"not null"`
-	OrderID       uuid.UUID `json:"orderId" gorm:"type:uuid;not null;index"`
-	ReservedAt    time.Time `json:"reservedAt"`
-	ExpiresAt     time.Time `json:"expiresAt"`
-	Status        string    `json:"status" gorm:"type:varchar(20);not null;default:'ACTIVE'"`
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID      string     `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	ReservationID *uuid.UUID `json:"reservationId,omitempty" gorm:"type:uuid;index"`
+	WarehouseID   uuid.UUID  `json:"warehouseId" gorm:"type:uuid;not null;index"`
+	ProductID     uuid.UUID  `json:"productId" gorm:"type:uuid;not null;index"`
+	VariantID     *uuid.UUID `json:"variantId,omitempty" gorm:"type:uuid;index"`
+
+	Quantity      int        `json:"quantity" gorm:"not null"`
+	OrderID       *uuid.UUID `json:"orderId,omitempty" gorm:"type:uuid;index"`
+	CartID        *uuid.UUID `json:"cartId,omitempty" gorm:"type:uuid;index"`
+	ReservedAt    time.Time  `json:"reservedAt"`
+	ExpiresAt     time.Time  `json:"expiresAt"`
+	Status        string     `json:"status" gorm:"type:varchar(20);not null;default:'ACTIVE'"`
 
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
@@ -303,14 +327,20 @@ type StockLevel struct {
 	QuantityOnHand int `json:"quantityOnHand" gorm:"not null;default:0"`
 	QuantityReserved int `json:"quantityReserved" gorm:"not null;default:0"`
 	QuantityAvailable int `json:"quantityAvailable" gorm:"not null;default:0"`
+	QuantityInTransit int `json:"quantityInTransit" gorm:"not null;default:0"` // Inbound on transfers; not yet on hand
 
 	ReorderPoint int `json:"reorderPoint" gorm:"default:0"`
 	ReorderQuantity int `json:"reorderQuantity" gorm:"default:0"`
 
+	UnitCost float64 `json:"unitCost" gorm:"type:decimal(12,4);not null;default:0"` // Weighted average landed cost of on-hand units
+
 	LastRestockedAt *time.Time `json:"lastRestockedAt,omitempty"`
 
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Bins holding this stock, attached in responses
+	Bins []BinStock `json:"bins,omitempty" gorm:"-"`
 }
 
 // TableName implementations
@@ -354,6 +384,7 @@ const (
 	AlertTypeOutOfStock  AlertType = "OUT_OF_STOCK"
 	AlertTypeOverstock   AlertType = "OVERSTOCK"
 	AlertTypeExpiringSoon AlertType = "EXPIRING_SOON"
+	AlertTypeTransferDiscrepancy AlertType = "TRANSFER_DISCREPANCY"
 )
 
 // AlertStatus represents the status of an alert
@@ -429,6 +460,8 @@ type CreateWarehouseRequest struct {
 	Priority    *int            `json:"priority,omitempty"`
 	LogoURL     *string         `json:"logoUrl,omitempty"` // Warehouse logo/icon
 	Metadata    *JSON           `json:"metadata,omitempty"`
+	PickupEnabled      *bool   `json:"pickupEnabled,omitempty"`
+	PickupInstructions *string `json:"pickupInstructions,omitempty"`
 }
 
 type CreateSupplierRequest struct {
@@ -618,33 +651,6 @@ type BulkCreateSuppliersRequest struct {
 	SkipDuplicates bool                     `json:"skipDuplicates,omitempty"`
 }
 
-// BulkCreateResultItem represents result for a single item (generic pattern)
-type BulkCreateResultItem struct {
-	Index      int         `json:"index"`
-	ExternalID *string     `json:"externalId,omitempty"`
-	Success    bool        `json:"success"`
-	Data       interface{} `json:"data,omitempty"`
-	Error      *Error      `json:"error,omitempty"`
-}
-
-// BulkCreateWarehousesResponse represents bulk create response for warehouses
-type BulkCreateWarehousesResponse struct {
-	Success      bool                   `json:"success"`
-	TotalCount   int                    `json:"totalCount"`
-	SuccessCount int                    `json:"successCount"`
-	FailedCount  int                    `json:"failedCount"`
-	Results      []BulkCreateResultItem `json:"results"`
-}
-
-// BulkCreateSuppliersResponse represents bulk create response for suppliers
-type BulkCreateSuppliersResponse struct {
-	Success      bool                   `json:"success"`
-	TotalCount   int                    `json:"totalCount"`
-	SuccessCount int                    `json:"successCount"`
-	FailedCount  int                    `json:"failedCount"`
-	Results      []BulkCreateResultItem `json:"results"`
-}
-
 // BulkDeleteRequest represents a generic bulk delete request
 type BulkDeleteRequest struct {
 	IDs []uuid.UUID `json:"ids" binding:"required,min=1,max=100"`
@@ -0,0 +1,207 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocationType is a level of the location hierarchy within a warehouse
+type LocationType string
+
+const (
+	LocationTypeZone  LocationType = "ZONE"
+	LocationTypeAisle LocationType = "AISLE"
+	LocationTypeShelf LocationType = "SHELF"
+	LocationTypeBin   LocationType = "BIN"
+)
+
+// ParentType returns the level a location of this type sits under. Zones sit directly
+// under the warehouse.
+func (t LocationType) ParentType() (LocationType, bool) {
+	switch t {
+	case LocationTypeAisle:
+		return LocationTypeZone, true
+	case LocationTypeShelf:
+		return LocationTypeAisle, true
+	case LocationTypeBin:
+		return LocationTypeShelf, true
+	}
+	return "", false
+}
+
+// IsValid reports whether the location type is known
+func (t LocationType) IsValid() bool {
+	switch t {
+	case LocationTypeZone, LocationTypeAisle, LocationTypeShelf, LocationTypeBin:
+		return true
+	}
+	return false
+}
+
+// WarehouseLocation is a zone, aisle, shelf or bin within a warehouse. Stock is held in
+// bins; FullCode joins the codes down the hierarchy, e.g. "A-01-03-B2". Pick paths visit
+// locations in PickSequence order at each level.
+type WarehouseLocation struct {
+	ID           uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID     string       `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	WarehouseID  uuid.UUID    `json:"warehouseId" gorm:"type:uuid;not null;uniqueIndex:idx_warehouse_location_code"`
+	ParentID     *uuid.UUID   `json:"parentId,omitempty" gorm:"type:uuid;index"`
+	Type         LocationType `json:"type" gorm:"type:varchar(20);not null"`
+	Code         string       `json:"code" gorm:"type:varchar(50);not null"`
+	FullCode     string       `json:"fullCode" gorm:"type:varchar(255);not null;uniqueIndex:idx_warehouse_location_code"`
+	Name         *string      `json:"name,omitempty" gorm:"type:varchar(255)"`
+	PickSequence int          `json:"pickSequence" gorm:"default:0"`
+	Capacity     *int         `json:"capacity,omitempty"` // Units a bin holds; unlimited if unset
+	IsActive     bool         `json:"isActive" gorm:"default:true"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (WarehouseLocation) TableName() string {
+	return "warehouse_locations"
+}
+
+// BinStock is the quantity of a product or variant held in one bin. Bin quantities add
+// up to at most the warehouse's on-hand stock; the rest is unassigned.
+type BinStock struct {
+	ID          uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID    string             `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	WarehouseID uuid.UUID          `json:"warehouseId" gorm:"type:uuid;not null;index:idx_bin_stock_item"`
+	ProductID   uuid.UUID          `json:"productId" gorm:"type:uuid;not null;index:idx_bin_stock_item"`
+	VariantID   *uuid.UUID         `json:"variantId,omitempty" gorm:"type:uuid;index:idx_bin_stock_item"`
+	LocationID  uuid.UUID          `json:"locationId" gorm:"type:uuid;not null;index"`
+	Location    *WarehouseLocation `json:"location,omitempty" gorm:"foreignKey:LocationID"`
+	Quantity    int                `json:"quantity" gorm:"not null;default:0"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (BinStock) TableName() string {
+	return "bin_stock"
+}
+
+// BinPlacement is a quantity placed, or suggested for placing, in a bin
+type BinPlacement struct {
+	LocationID uuid.UUID `json:"locationId"`
+	BinCode    string    `json:"binCode"`
+	Quantity   int       `json:"quantity"`
+	// EXISTING_STOCK when the bin already holds the item, EMPTY_BIN otherwise,
+	// ASSIGNED when the bin was chosen at receiving
+	Reason string `json:"reason"`
+}
+
+// Putaway reasons
+const (
+	PutawayReasonExistingStock = "EXISTING_STOCK"
+	PutawayReasonEmptyBin      = "EMPTY_BIN"
+	PutawayReasonAssigned      = "ASSIGNED"
+)
+
+// Putaway is where a received line's units went, or where they are suggested to go.
+// Unplaced units didn't fit in any bin.
+type Putaway struct {
+	ItemID     uuid.UUID      `json:"itemId"`
+	ProductID  uuid.UUID      `json:"productId"`
+	VariantID  *uuid.UUID     `json:"variantId,omitempty"`
+	Quantity   int            `json:"quantity"`
+	Applied    bool           `json:"applied"`
+	Placements []BinPlacement `json:"placements"`
+	Unplaced   int            `json:"unplaced"`
+}
+
+// PickItem is a product or variant quantity to pick
+type PickItem struct {
+	ProductID uuid.UUID  `json:"productId" binding:"required"`
+	VariantID *uuid.UUID `json:"variantId,omitempty"`
+	Quantity  int        `json:"quantity" binding:"required,min=1"`
+}
+
+// PickStop is one bin visited on a pick path
+type PickStop struct {
+	Sequence   int        `json:"sequence"`
+	LocationID uuid.UUID  `json:"locationId"`
+	BinCode    string     `json:"binCode"`
+	ZoneCode   string     `json:"zoneCode,omitempty"`
+	AisleCode  string     `json:"aisleCode,omitempty"`
+	ShelfCode  string     `json:"shelfCode,omitempty"`
+	ProductID  uuid.UUID  `json:"productId"`
+	VariantID  *uuid.UUID `json:"variantId,omitempty"`
+	Quantity   int        `json:"quantity"`
+}
+
+// PickPath is the order to visit bins in to pick a set of items. Shortfall lists
+// quantities not held in any bin, to be picked from unassigned stock.
+type PickPath struct {
+	WarehouseID uuid.UUID  `json:"warehouseId"`
+	Stops       []PickStop `json:"stops"`
+	Shortfall   []PickItem `json:"shortfall"`
+}
+
+// CreateLocationRequest represents request to create a warehouse location
+type CreateLocationRequest struct {
+	ParentID     *uuid.UUID   `json:"parentId,omitempty"`
+	Type         LocationType `json:"type" binding:"required"`
+	Code         string       `json:"code" binding:"required,min=1,max=50"`
+	Name         *string      `json:"name,omitempty"`
+	PickSequence *int         `json:"pickSequence,omitempty"`
+	Capacity     *int         `json:"capacity,omitempty" binding:"omitempty,min=1"`
+}
+
+// UpdateLocationRequest represents request to update a warehouse location
+type UpdateLocationRequest struct {
+	Name         *string `json:"name,omitempty"`
+	PickSequence *int    `json:"pickSequence,omitempty"`
+	Capacity     *int    `json:"capacity,omitempty" binding:"omitempty,min=1"`
+	IsActive     *bool   `json:"isActive,omitempty"`
+}
+
+// MoveBinStockRequest represents request to move stock into, out of or between bins.
+// Without FromLocationID units come from unassigned stock; without ToLocationID they
+// become unassigned.
+type MoveBinStockRequest struct {
+	ProductID      uuid.UUID  `json:"productId" binding:"required"`
+	VariantID      *uuid.UUID `json:"variantId,omitempty"`
+	FromLocationID *uuid.UUID `json:"fromLocationId,omitempty"`
+	ToLocationID   *uuid.UUID `json:"toLocationId,omitempty"`
+	Quantity       int        `json:"quantity" binding:"required,min=1"`
+}
+
+// PickPathRequest represents request to plan a pick path
+type PickPathRequest struct {
+	Items []PickItem `json:"items" binding:"required,min=1,dive"`
+}
+
+// LocationResponse represents response for a single location
+type LocationResponse struct {
+	Success bool               `json:"success"`
+	Data    *WarehouseLocation `json:"data,omitempty"`
+	Message *string            `json:"message,omitempty"`
+}
+
+// LocationListResponse represents response for list of locations
+type LocationListResponse struct {
+	Success bool                `json:"success"`
+	Data    []WarehouseLocation `json:"data"`
+}
+
+// BinStockListResponse represents response for bin stock
+type BinStockListResponse struct {
+	Success bool       `json:"success"`
+	Data    []BinStock `json:"data"`
+}
+
+// PutawayResponse represents response for putaway suggestions
+type PutawayResponse struct {
+	Success bool      `json:"success"`
+	Data    []Putaway `json:"data"`
+	Message *string   `json:"message,omitempty"`
+}
+
+// PickPathResponse represents response for a pick path
+type PickPathResponse struct {
+	Success bool      `json:"success"`
+	Data    *PickPath `json:"data,omitempty"`
+}
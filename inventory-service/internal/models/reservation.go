@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateReservationRequest represents a request to hold stock for an order or a cart. Exactly
+// one of OrderID and CartID is set. The hold lapses after TTLSeconds unless it is confirmed or
+// released first.
+type CreateReservationRequest struct {
+	OrderID    *uuid.UUID        `json:"orderId,omitempty"`
+	CartID     *uuid.UUID        `json:"cartId,omitempty"`
+	Items      []PickupStockItem `json:"items" binding:"required,min=1,dive"`
+	TTLSeconds int               `json:"ttlSeconds" binding:"omitempty,min=60,max=86400"`
+}
+
+// ConfirmReservationRequest represents a request to take a reservation's stock out of stock on
+// hand. OrderID records the order a cart reservation was checked out as.
+type ConfirmReservationRequest struct {
+	OrderID *uuid.UUID `json:"orderId,omitempty"`
+}
+
+// ReleaseReservationRequest represents a request to return a reservation's stock
+type ReleaseReservationRequest struct {
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+// Reservation is the lines reserved together for an order or a cart. Its lines always share a
+// status and expiry.
+type Reservation struct {
+	ID        uuid.UUID              `json:"id"`
+	OrderID   *uuid.UUID             `json:"orderId,omitempty"`
+	CartID    *uuid.UUID             `json:"cartId,omitempty"`
+	Status    string                 `json:"status"`
+	ExpiresAt time.Time              `json:"expiresAt"`
+	Items     []InventoryReservation `json:"items"`
+}
+
+// NewReservation groups reserved lines into their reservation
+func NewReservation(id uuid.UUID, lines []InventoryReservation) *Reservation {
+	reservation := &Reservation{ID: id, Items: lines}
+	if len(lines) > 0 {
+		reservation.OrderID = lines[0].OrderID
+		reservation.CartID = lines[0].CartID
+		reservation.Status = lines[0].Status
+		reservation.ExpiresAt = lines[0].ExpiresAt
+	}
+	return reservation
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxStockBatchSize caps the SKUs and items in one batch stock lookup
+const MaxStockBatchSize = 500
+
+// StockBatchItem is a product or variant to look up by ID, skipping SKU resolution
+type StockBatchItem struct {
+	ProductID uuid.UUID  `json:"productId" binding:"required"`
+	VariantID *uuid.UUID `json:"variantId,omitempty"`
+}
+
+// StockBatchRequest represents request for stock levels of many SKUs in one call.
+// Callers that already know product and variant IDs can pass items instead of SKUs.
+type StockBatchRequest struct {
+	SKUs                []string         `json:"skus,omitempty" binding:"max=500"`
+	Items               []StockBatchItem `json:"items,omitempty" binding:"max=500,dive"`
+	WarehouseID         *uuid.UUID       `json:"warehouseId,omitempty"`
+	IncludeReservations bool             `json:"includeReservations,omitempty"`
+}
+
+// StockReservation is an active reservation against a warehouse's stock, held for an order
+// or a cart
+type StockReservation struct {
+	ID        uuid.UUID  `json:"id"`
+	OrderID   *uuid.UUID `json:"orderId,omitempty"`
+	CartID    *uuid.UUID `json:"cartId,omitempty"`
+	Quantity  int        `json:"quantity"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+}
+
+// WarehouseStock is a product or variant's stock at one warehouse
+type WarehouseStock struct {
+	WarehouseID       uuid.UUID          `json:"warehouseId"`
+	QuantityOnHand    int                `json:"quantityOnHand"`
+	QuantityReserved  int                `json:"quantityReserved"`
+	QuantityAvailable int                `json:"quantityAvailable"`
+	QuantityInTransit int                `json:"quantityInTransit"`
+	Reservations      []StockReservation `json:"reservations,omitempty"`
+}
+
+// StockBatchEntry is a product or variant's stock across warehouses
+type StockBatchEntry struct {
+	SKU               string           `json:"sku,omitempty"`
+	ProductID         uuid.UUID        `json:"productId"`
+	VariantID         *uuid.UUID       `json:"variantId,omitempty"`
+	QuantityOnHand    int              `json:"quantityOnHand"`
+	QuantityReserved  int              `json:"quantityReserved"`
+	QuantityAvailable int              `json:"quantityAvailable"`
+	QuantityInTransit int              `json:"quantityInTransit"`
+	Warehouses        []WarehouseStock `json:"warehouses"`
+}
+
+// StockBatchResult is the stock for a batch lookup. NotFound lists SKUs that don't
+// belong to any product or variant.
+type StockBatchResult struct {
+	Items     []StockBatchEntry `json:"items"`
+	NotFound  []string          `json:"notFound"`
+	CheckedAt time.Time         `json:"checkedAt"`
+}
+
+// StockBatchResponse represents response for a batch stock lookup
+type StockBatchResponse struct {
+	Success bool              `json:"success"`
+	Data    *StockBatchResult `json:"data,omitempty"`
+}
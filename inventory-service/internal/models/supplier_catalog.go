@@ -0,0 +1,212 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SupplierCatalogEntry is a supplier's price for one SKU. A SKU can be listed by several
+// suppliers, which is what the comparison ranks.
+type SupplierCatalogEntry struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID         string     `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	SupplierID       uuid.UUID  `json:"supplierId" gorm:"type:uuid;not null;uniqueIndex:idx_supplier_catalog_sku"`
+	Supplier         *Supplier  `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
+	SKU              string     `json:"sku" gorm:"type:varchar(100);not null;index;uniqueIndex:idx_supplier_catalog_sku"`
+	ProductID        *uuid.UUID `json:"productId,omitempty" gorm:"type:uuid;index"`
+	VariantID        *uuid.UUID `json:"variantId,omitempty" gorm:"type:uuid"`
+	SupplierSKU      *string    `json:"supplierSku,omitempty" gorm:"type:varchar(100)"` // The supplier's own part number
+	MinOrderQuantity int        `json:"minOrderQuantity" gorm:"not null;default:1"`
+	UnitCost         float64    `json:"unitCost" gorm:"type:decimal(12,4);not null"`
+	CurrencyCode     string     `json:"currencyCode" gorm:"type:varchar(3);not null;default:'USD'"`
+	LeadTimeDays     *int       `json:"leadTimeDays,omitempty"` // Falls back to the supplier's lead time
+	ValidFrom        *time.Time `json:"validFrom,omitempty"`
+	ValidTo          *time.Time `json:"validTo,omitempty"`
+	IsPreferred      bool       `json:"isPreferred" gorm:"default:false"`
+	IsActive         bool       `json:"isActive" gorm:"default:true"`
+	Notes            *string    `json:"notes,omitempty" gorm:"type:text"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (SupplierCatalogEntry) TableName() string {
+	return "supplier_catalog_entries"
+}
+
+// SupplierOffer is one supplier's price for a SKU at a requested quantity. Orders below
+// the MOQ are rounded up to it.
+type SupplierOffer struct {
+	EntryID          uuid.UUID `json:"entryId"`
+	SupplierID       uuid.UUID `json:"supplierId"`
+	SupplierName     string    `json:"supplierName"`
+	SupplierSKU      *string   `json:"supplierSku,omitempty"`
+	Rating           *float64  `json:"rating,omitempty"`
+	MinOrderQuantity int       `json:"minOrderQuantity"`
+	OrderQuantity    int       `json:"orderQuantity"`
+	UnitCost         float64   `json:"unitCost"`
+	TotalCost        float64   `json:"totalCost"`
+	CurrencyCode     string    `json:"currencyCode"`
+	LeadTimeDays     *int      `json:"leadTimeDays,omitempty"`
+	IsPreferred      bool      `json:"isPreferred"`
+	Rank             int       `json:"rank"` // By total cost within the currency, then lead time
+}
+
+// SupplierComparison ranks the suppliers listing a SKU. Prices aren't converted, so
+// offers are ranked within their currency.
+type SupplierComparison struct {
+	SKU      string          `json:"sku"`
+	Quantity int             `json:"quantity"`
+	Offers   []SupplierOffer `json:"offers"`
+}
+
+// LandedCostType is a cost of getting goods into the warehouse on top of their price
+type LandedCostType string
+
+const (
+	LandedCostTypeFreight LandedCostType = "FREIGHT"
+	LandedCostTypeDuty    LandedCostType = "DUTY"
+	LandedCostTypeFee     LandedCostType = "FEE"
+)
+
+// LandedCostAllocation is how a landed cost is spread over the received lines
+type LandedCostAllocation string
+
+const (
+	// LandedCostAllocationValue spreads the cost in proportion to each line's value
+	LandedCostAllocationValue LandedCostAllocation = "VALUE"
+	// LandedCostAllocationQuantity spreads the cost in proportion to each line's units
+	LandedCostAllocationQuantity LandedCostAllocation = "QUANTITY"
+)
+
+// LandedCostComponent is a freight, duty or fee amount paid on a purchase order receipt,
+// in the purchase order's currency
+type LandedCostComponent struct {
+	Type        LandedCostType       `json:"type" binding:"required,oneof=FREIGHT DUTY FEE"`
+	Amount      float64              `json:"amount" binding:"min=0"`
+	Allocation  LandedCostAllocation `json:"allocation,omitempty" binding:"omitempty,oneof=VALUE QUANTITY"` // Defaults to VALUE
+	Description *string              `json:"description,omitempty"`
+}
+
+// PurchaseOrderLandedCost is the landed cost of a purchase order line on one receipt: its
+// price plus its share of the receipt's freight, duty and fees. The landed unit cost is
+// what the received units are valued at.
+type PurchaseOrderLandedCost struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID        string     `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	PurchaseOrderID uuid.UUID  `json:"purchaseOrderId" gorm:"type:uuid;not null;index"`
+	ItemID          uuid.UUID  `json:"itemId" gorm:"type:uuid;not null"`
+	WarehouseID     uuid.UUID  `json:"warehouseId" gorm:"type:uuid;not null"`
+	ProductID       uuid.UUID  `json:"productId" gorm:"type:uuid;not null;index"`
+	VariantID       *uuid.UUID `json:"variantId,omitempty" gorm:"type:uuid"`
+
+	Quantity       int     `json:"quantity" gorm:"not null"`
+	UnitCost       float64 `json:"unitCost" gorm:"type:decimal(12,4);not null"`
+	Freight        float64 `json:"freight" gorm:"type:decimal(12,2);not null;default:0"`
+	Duty           float64 `json:"duty" gorm:"type:decimal(12,2);not null;default:0"`
+	Fees           float64 `json:"fees" gorm:"type:decimal(12,2);not null;default:0"`
+	LandedUnitCost float64 `json:"landedUnitCost" gorm:"type:decimal(12,4);not null"`
+	TotalCost      float64 `json:"totalCost" gorm:"type:decimal(12,2);not null"`
+	CurrencyCode   string  `json:"currencyCode" gorm:"type:varchar(3);not null"`
+
+	ReceivedAt time.Time `json:"receivedAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (PurchaseOrderLandedCost) TableName() string {
+	return "purchase_order_landed_costs"
+}
+
+// PurchaseOrderReceipt is the outcome of receiving a purchase order: where each line was
+// or should be put away, and what its units cost landed
+type PurchaseOrderReceipt struct {
+	Putaway     []Putaway                 `json:"putaway"`
+	LandedCosts []PurchaseOrderLandedCost `json:"landedCosts"`
+}
+
+// WarehouseValuation is the value of a warehouse's on-hand stock at landed cost
+type WarehouseValuation struct {
+	WarehouseID uuid.UUID `json:"warehouseId"`
+	Items       int64     `json:"items"`
+	Units       int64     `json:"units"`
+	Value       float64   `json:"value"`
+	Uncosted    int64     `json:"uncosted"` // Stock levels with units on hand but no cost yet
+}
+
+// InventoryValuation is the value of on-hand stock at weighted average landed cost
+type InventoryValuation struct {
+	Units       int64                `json:"units"`
+	Value       float64              `json:"value"`
+	Warehouses  []WarehouseValuation `json:"warehouses"`
+	GeneratedAt time.Time            `json:"generatedAt"`
+}
+
+// CreateSupplierCatalogEntryRequest represents request to list a SKU for a supplier
+type CreateSupplierCatalogEntryRequest struct {
+	SKU              string     `json:"sku" binding:"required,max=100"`
+	ProductID        *uuid.UUID `json:"productId,omitempty"`
+	VariantID        *uuid.UUID `json:"variantId,omitempty"`
+	SupplierSKU      *string    `json:"supplierSku,omitempty"`
+	MinOrderQuantity *int       `json:"minOrderQuantity,omitempty" binding:"omitempty,min=1"`
+	UnitCost         float64    `json:"unitCost" binding:"required,gt=0"`
+	CurrencyCode     *string    `json:"currencyCode,omitempty" binding:"omitempty,len=3"`
+	LeadTimeDays     *int       `json:"leadTimeDays,omitempty" binding:"omitempty,min=0"`
+	ValidFrom        *time.Time `json:"validFrom,omitempty"`
+	ValidTo          *time.Time `json:"validTo,omitempty"`
+	IsPreferred      *bool      `json:"isPreferred,omitempty"`
+	Notes            *string    `json:"notes,omitempty"`
+}
+
+// UpdateSupplierCatalogEntryRequest represents request to update a catalog entry
+type UpdateSupplierCatalogEntryRequest struct {
+	SupplierSKU      *string    `json:"supplierSku,omitempty"`
+	MinOrderQuantity *int       `json:"minOrderQuantity,omitempty" binding:"omitempty,min=1"`
+	UnitCost         *float64   `json:"unitCost,omitempty" binding:"omitempty,gt=0"`
+	CurrencyCode     *string    `json:"currencyCode,omitempty" binding:"omitempty,len=3"`
+	LeadTimeDays     *int       `json:"leadTimeDays,omitempty" binding:"omitempty,min=0"`
+	ValidFrom        *time.Time `json:"validFrom,omitempty"`
+	ValidTo          *time.Time `json:"validTo,omitempty"`
+	IsPreferred      *bool      `json:"isPreferred,omitempty"`
+	IsActive         *bool      `json:"isActive,omitempty"`
+	Notes            *string    `json:"notes,omitempty"`
+}
+
+// SupplierCatalogResponse represents response for a single catalog entry
+type SupplierCatalogResponse struct {
+	Success bool                  `json:"success"`
+	Data    *SupplierCatalogEntry `json:"data,omitempty"`
+	Message *string               `json:"message,omitempty"`
+}
+
+// SupplierCatalogListResponse represents response for a supplier's catalog
+type SupplierCatalogListResponse struct {
+	Success    bool                   `json:"success"`
+	Data       []SupplierCatalogEntry `json:"data"`
+	Pagination *PaginationMeta        `json:"pagination,omitempty"`
+}
+
+// SupplierComparisonResponse represents response for a supplier comparison
+type SupplierComparisonResponse struct {
+	Success bool                `json:"success"`
+	Data    *SupplierComparison `json:"data,omitempty"`
+}
+
+// PurchaseOrderReceiptResponse represents response for a purchase order receipt
+type PurchaseOrderReceiptResponse struct {
+	Success bool                  `json:"success"`
+	Data    *PurchaseOrderReceipt `json:"data,omitempty"`
+	Message *string               `json:"message,omitempty"`
+}
+
+// LandedCostListResponse represents response for a purchase order's landed costs
+type LandedCostListResponse struct {
+	Success bool                      `json:"success"`
+	Data    []PurchaseOrderLandedCost `json:"data"`
+}
+
+// InventoryValuationResponse represents response for inventory valuation
+type InventoryValuationResponse struct {
+	Success bool                `json:"success"`
+	Data    *InventoryValuation `json:"data,omitempty"`
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdjustmentProposalStatus represents the status of an adjustment proposal
+type AdjustmentProposalStatus string
+
+const (
+	AdjustmentProposalStatusPending  AdjustmentProposalStatus = "PENDING"
+	AdjustmentProposalStatusApproved AdjustmentProposalStatus = "APPROVED"
+	AdjustmentProposalStatusRejected AdjustmentProposalStatus = "REJECTED"
+)
+
+// AdjustmentReason explains why an adjustment was proposed
+type AdjustmentReason string
+
+const (
+	// AdjustmentReasonShortReceived: fewer units arrived than were shipped
+	AdjustmentReasonShortReceived AdjustmentReason = "SHORT_RECEIVED"
+	// AdjustmentReasonOverReceived: more units arrived than were shipped
+	AdjustmentReasonOverReceived AdjustmentReason = "OVER_RECEIVED"
+
+	// Reasons for manual stock adjustments
+	AdjustmentReasonCycleCount AdjustmentReason = "CYCLE_COUNT"
+	AdjustmentReasonDamaged    AdjustmentReason = "DAMAGED"
+	AdjustmentReasonLost       AdjustmentReason = "LOST"
+	AdjustmentReasonFound      AdjustmentReason = "FOUND"
+	AdjustmentReasonCorrection AdjustmentReason = "CORRECTION"
+	AdjustmentReasonOther      AdjustmentReason = "OTHER"
+)
+
+// AdjustmentSource is where an adjustment proposal came from
+type AdjustmentSource string
+
+const (
+	AdjustmentSourceTransfer AdjustmentSource = "TRANSFER"
+	AdjustmentSourceManual   AdjustmentSource = "MANUAL"
+)
+
+// InventoryAdjustmentProposal is a stock correction that waits for approval before it is
+// applied. Transfer proposals are raised when a transfer is received with a discrepancy:
+// approving a short receipt returns the missing units to the source warehouse (they never
+// left), rejecting it writes them off as lost in transit. Approving an over receipt deducts
+// the surplus from the source warehouse, which shipped more than it recorded.
+//
+// Manual proposals are stock adjustments above the tenant's approval thresholds. They are
+// decided in approval-service and applied when the decision event arrives. Manual
+// adjustments under the thresholds are applied at once and recorded here as approved, so
+// every manual adjustment keeps its requested and applied quantities.
+type InventoryAdjustmentProposal struct {
+	ID             uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID       string           `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	Source         AdjustmentSource `json:"source" gorm:"type:varchar(20);not null;default:'TRANSFER';index"`
+	TransferID     *uuid.UUID       `json:"transferId,omitempty" gorm:"type:uuid;index"`
+	TransferItemID *uuid.UUID       `json:"transferItemId,omitempty" gorm:"type:uuid"`
+	TransferNumber string           `json:"transferNumber,omitempty" gorm:"type:varchar(50)"`
+	WarehouseID    uuid.UUID        `json:"warehouseId" gorm:"type:uuid;not null"`
+	ProductID      uuid.UUID        `json:"productId" gorm:"type:uuid;not null"`
+	VariantID      *uuid.UUID       `json:"variantId,omitempty" gorm:"type:uuid"`
+
+	Reason          AdjustmentReason         `json:"reason" gorm:"type:varchar(30);not null"`
+	QuantityChange  int                      `json:"quantityChange" gorm:"not null"` // Requested change to the warehouse's on-hand stock
+	AppliedQuantity *int                     `json:"appliedQuantity,omitempty"`      // Change actually applied; manual removals stop at zero on hand
+	Status          AdjustmentProposalStatus `json:"status" gorm:"type:varchar(20);not null;default:'PENDING';index"`
+	Message         string                   `json:"message" gorm:"type:text"`
+
+	// Value of the change at the stock level's unit cost when it was requested
+	UnitCost float64 `json:"unitCost" gorm:"type:decimal(12,4);not null;default:0"`
+	Value    float64 `json:"value" gorm:"type:decimal(14,2);not null;default:0"`
+
+	// Manual adjustments
+	RequiresApproval  bool    `json:"requiresApproval" gorm:"default:false"`
+	ApprovalRequestID *string `json:"approvalRequestId,omitempty" gorm:"type:varchar(255);index"`
+	RequestedBy       *string `json:"requestedBy,omitempty"`
+	PreviousOnHand    *int    `json:"previousOnHand,omitempty"` // On-hand stock just before the change was applied
+	NewOnHand         *int    `json:"newOnHand,omitempty"`
+
+	ResolvedBy      *string    `json:"resolvedBy,omitempty"`
+	ResolvedAt      *time.Time `json:"resolvedAt,omitempty"`
+	ResolutionNotes *string    `json:"resolutionNotes,omitempty" gorm:"type:text"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (InventoryAdjustmentProposal) TableName() string {
+	return "inventory_adjustment_proposals"
+}
+
+// TransferShipment is the carrier shipment a transfer travels on
+type TransferShipment struct {
+	ShipmentID     *string
+	Carrier        *string
+	TrackingNumber *string
+	TrackingURL    *string
+	Status         *string
+}
+
+// InTransitStock is stock that has left its source warehouse on a transfer and not yet
+// been received at the destination
+type InTransitStock struct {
+	TransferID      uuid.UUID  `json:"transferId"`
+	TransferNumber  string     `json:"transferNumber"`
+	FromWarehouseID uuid.UUID  `json:"fromWarehouseId"`
+	ToWarehouseID   uuid.UUID  `json:"toWarehouseId"`
+	ProductID       uuid.UUID  `json:"productId"`
+	VariantID       *uuid.UUID `json:"variantId,omitempty"`
+	Quantity        int        `json:"quantity"`
+	ShippedAt       *time.Time `json:"shippedAt,omitempty"`
+	Carrier         *string    `json:"carrier,omitempty"`
+	TrackingNumber  *string    `json:"trackingNumber,omitempty"`
+}
+
+// BookTransferShipmentRequest books a carrier shipment between the transfer's
+// warehouses through shipping-service
+type BookTransferShipmentRequest struct {
+	Carrier     string  `json:"carrier,omitempty"` // Auto-selected by shipping-service if empty
+	ServiceType string  `json:"serviceType,omitempty"`
+	Weight      float64 `json:"weight" binding:"required,gt=0"` // in kg
+	Length      float64 `json:"length" binding:"required,gt=0"` // in cm
+	Width       float64 `json:"width" binding:"required,gt=0"`
+	Height      float64 `json:"height" binding:"required,gt=0"`
+}
+
+// ShipInventoryTransferRequest represents a request to dispatch a transfer. The shipment
+// is either booked through shipping-service, linked to an existing shipping-service
+// shipment, or entered by hand with carrier and tracking number.
+type ShipInventoryTransferRequest struct {
+	BookShipment   *BookTransferShipmentRequest `json:"bookShipment,omitempty"`
+	ShipmentID     *string                      `json:"shipmentId,omitempty"`
+	Carrier        *string                      `json:"carrier,omitempty"`
+	TrackingNumber *string                      `json:"trackingNumber,omitempty"`
+	TrackingURL    *string                      `json:"trackingUrl,omitempty"`
+	ShippedItems   map[string]int               `json:"shippedItems,omitempty"` // Item ID to quantity; defaults to the requested quantity
+}
+
+// ReceiveTransferItemRequest is the count for one transfer item at the destination
+type ReceiveTransferItemRequest struct {
+	ItemID           uuid.UUID `json:"itemId" binding:"required"`
+	QuantityReceived int        `json:"quantityReceived" binding:"min=0"`
+	Reason           *string    `json:"reason,omitempty"`
+	LocationID       *uuid.UUID `json:"locationId,omitempty"` // Bin to put the units away in
+}
+
+// ReceiveInventoryTransferRequest represents a request to receive an in-transit transfer.
+// Items that aren't listed are received as shipped.
+type ReceiveInventoryTransferRequest struct {
+	Items []ReceiveTransferItemRequest `json:"items,omitempty" binding:"dive"`
+}
+
+// ResolveAdjustmentProposalRequest represents a request to approve or reject a proposal
+type ResolveAdjustmentProposalRequest struct {
+	Notes *string `json:"notes,omitempty"`
+}
+
+// ReceiveTransferResult is a received transfer with the proposals and alerts raised for
+// its discrepancies, and where its units were or should be put away
+type ReceiveTransferResult struct {
+	Transfer  *InventoryTransfer            `json:"transfer"`
+	Proposals []InventoryAdjustmentProposal `json:"proposals"`
+	Alerts    []InventoryAlert              `json:"alerts"`
+	Putaway   []Putaway                     `json:"putaway"`
+}
+
+// ReceiveTransferResponse represents response for a received transfer
+type ReceiveTransferResponse struct {
+	Success bool                   `json:"success"`
+	Data    *ReceiveTransferResult `json:"data,omitempty"`
+	Message *string                `json:"message,omitempty"`
+}
+
+// InTransitStockResponse represents response for in-transit stock
+type InTransitStockResponse struct {
+	Success bool             `json:"success"`
+	Data    []InTransitStock `json:"data"`
+}
+
+// AdjustmentProposalResponse represents response for a single adjustment proposal
+type AdjustmentProposalResponse struct {
+	Success bool                         `json:"success"`
+	Data    *InventoryAdjustmentProposal `json:"data,omitempty"`
+	Message *string                      `json:"message,omitempty"`
+}
+
+// AdjustmentProposalListResponse represents response for list of adjustment proposals
+type AdjustmentProposalListResponse struct {
+	Success    bool                          `json:"success"`
+	Data       []InventoryAdjustmentProposal `json:"data"`
+	Pagination *PaginationMeta               `json:"pagination,omitempty"`
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-service/internal/models"
+)
+
+var (
+	// ErrProposalAwaitingApproval is returned when a proposal sent to approval-service is
+	// resolved directly instead of through its approval request
+	ErrProposalAwaitingApproval = errors.New("adjustment proposal is awaiting approval in approval-service")
+	// ErrAdjustmentWarehouseNotFound is returned when a stock adjustment names a warehouse
+	// the tenant doesn't have
+	ErrAdjustmentWarehouseNotFound = errors.New("warehouse not found")
+)
+
+// ========== Manual Stock Adjustment Operations ==========
+
+// GetAdjustmentSettings retrieves the tenant's adjustment approval thresholds, falling
+// back to the defaults if the tenant hasn't set any
+func (r *InventoryRepository) GetAdjustmentSettings(ctx context.Context, tenantID string) (*models.AdjustmentApprovalSettings, error) {
+	return getAdjustmentSettings(r.db.WithContext(ctx), tenantID)
+}
+
+// UpsertAdjustmentSettings creates or replaces the tenant's adjustment approval thresholds
+func (r *InventoryRepository) UpsertAdjustmentSettings(ctx context.Context, settings *models.AdjustmentApprovalSettings) error {
+	now := time.Now()
+	settings.UpdatedAt = now
+	if settings.CreatedAt.IsZero() {
+		settings.CreatedAt = now
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "quantity_threshold", "value_threshold", "updated_by", "updated_at"}),
+	}).Create(settings).Error
+}
+
+// CreateStockAdjustment records a manual stock adjustment. The change is valued at the
+// stock level's unit cost; if it reaches the tenant's thresholds the proposal is left
+// pending for approval, otherwise it is applied straight away.
+func (r *InventoryRepository) CreateStockAdjustment(ctx context.Context, tenantID string, req *models.StockAdjustmentRequest, requestedBy *string) (*models.InventoryAdjustmentProposal, error) {
+	now := time.Now()
+	proposal := &models.InventoryAdjustmentProposal{
+		ID:             uuid.New(),
+		TenantID:       tenantID,
+		Source:         models.AdjustmentSourceManual,
+		WarehouseID:    req.WarehouseID,
+		ProductID:      req.ProductID,
+		VariantID:      req.VariantID,
+		Reason:         req.Reason,
+		QuantityChange: req.QuantityChange,
+		Status:         models.AdjustmentProposalStatusPending,
+		Message:        req.Notes,
+		RequestedBy:    requestedBy,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var warehouse models.Warehouse
+		err := tx.Select("id").Where("tenant_id = ? AND id = ?", tenantID, req.WarehouseID).First(&warehouse).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAdjustmentWarehouseNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		settings, err := getAdjustmentSettings(tx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		var stock models.StockLevel
+		err = stockLevelQuery(tx, tenantID, req.WarehouseID, req.ProductID, req.VariantID).First(&stock).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		proposal.UnitCost = stock.UnitCost
+		proposal.Value = math.Round(math.Abs(float64(req.QuantityChange))*stock.UnitCost*100) / 100
+		proposal.RequiresApproval = settings.RequiresApproval(req.QuantityChange, proposal.Value)
+
+		if proposal.RequiresApproval {
+			return tx.Create(proposal).Error
+		}
+
+		proposal.Status = models.AdjustmentProposalStatusApproved
+		proposal.ResolvedBy = requestedBy
+		proposal.ResolvedAt = &now
+		if err := r.applyAdjustmentProposalTx(tx, proposal); err != nil {
+			return err
+		}
+		return tx.Create(proposal).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if proposal.Status == models.AdjustmentProposalStatusApproved {
+		r.invalidateStockCaches(ctx, tenantID, proposal.WarehouseID, proposal.ProductID, proposal.VariantID)
+	}
+	return proposal, nil
+}
+
+// SetAdjustmentApprovalRequest links a pending proposal to the approval-service request
+// that will decide it
+func (r *InventoryRepository) SetAdjustmentApprovalRequest(ctx context.Context, tenantID string, id uuid.UUID, approvalRequestID string) error {
+	return r.db.WithContext(ctx).Model(&models.InventoryAdjustmentProposal{}).
+		Where("tenant_id = ? AND id = ? AND status = ?", tenantID, id, models.AdjustmentProposalStatusPending).
+		Updates(map[string]interface{}{
+			"approval_request_id": approvalRequestID,
+			"updated_at":          time.Now(),
+		}).Error
+}
+
+// ResolveAdjustmentApproval applies or closes the proposal waiting on an approval-service
+// request once the request is decided
+func (r *InventoryRepository) ResolveAdjustmentApproval(ctx context.Context, tenantID, approvalRequestID string, approve bool, resolvedBy, notes *string) (*models.InventoryAdjustmentProposal, error) {
+	return r.resolveAdjustmentProposal(ctx, tenantID, "approval_request_id = ?", approvalRequestID, true, approve, resolvedBy, notes)
+}
+
+// resolveAdjustmentProposal approves or rejects the pending proposal matching the condition
+func (r *InventoryRepository) resolveAdjustmentProposal(ctx context.Context, tenantID, condition string, arg interface{}, fromApproval, approve bool, resolvedBy, notes *string) (*models.InventoryAdjustmentProposal, error) {
+	var proposal models.InventoryAdjustmentProposal
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ?", tenantID).
+			Where(condition, arg).
+			First(&proposal).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProposalNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if proposal.Status != models.AdjustmentProposalStatusPending {
+			return ErrProposalResolved
+		}
+		if proposal.ApprovalRequestID != nil && !fromApproval {
+			return ErrProposalAwaitingApproval
+		}
+
+		proposal.Status = models.AdjustmentProposalStatusRejected
+		if approve {
+			proposal.Status = models.AdjustmentProposalStatusApproved
+			if err := r.applyAdjustmentProposalTx(tx, &proposal); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		proposal.ResolvedBy = resolvedBy
+		proposal.ResolvedAt = &now
+		proposal.ResolutionNotes = notes
+		proposal.UpdatedAt = now
+		return tx.Model(&models.InventoryAdjustmentProposal{}).
+			Where("id = ?", proposal.ID).
+			Updates(map[string]interface{}{
+				"status":           proposal.Status,
+				"applied_quantity": proposal.AppliedQuantity,
+				"previous_on_hand": proposal.PreviousOnHand,
+				"new_on_hand":      proposal.NewOnHand,
+				"resolved_by":      resolvedBy,
+				"resolved_at":      &now,
+				"resolution_notes": notes,
+				"updated_at":       now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if approve {
+		r.invalidateStockCaches(ctx, tenantID, proposal.WarehouseID, proposal.ProductID, proposal.VariantID)
+	}
+	return &proposal, nil
+}
+
+// applyAdjustmentProposalTx applies a proposal's quantity change and records what was
+// applied. Transfer proposals fail if the warehouse can't cover a removal; manual
+// removals take the stock down to zero at most, so a count that has moved on since the
+// request still applies.
+func (r *InventoryRepository) applyAdjustmentProposalTx(tx *gorm.DB, proposal *models.InventoryAdjustmentProposal) error {
+	var stock models.StockLevel
+	err := stockLevelQuery(tx, proposal.TenantID, proposal.WarehouseID, proposal.ProductID, proposal.VariantID).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&stock).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	previous := stock.QuantityOnHand
+
+	applied := proposal.QuantityChange
+	if applied < 0 && proposal.Source == models.AdjustmentSourceManual && -applied > previous {
+		applied = -previous
+	}
+
+	switch {
+	case applied > 0:
+		err = r.addStockTx(tx, proposal.TenantID, proposal.WarehouseID, proposal.ProductID, proposal.VariantID, applied)
+	case applied < 0 && proposal.Source == models.AdjustmentSourceManual:
+		err = r.removeStockTx(tx, proposal.TenantID, proposal.WarehouseID, proposal.ProductID, proposal.VariantID, -applied)
+	case applied < 0:
+		err = r.removeTransferStockTx(tx, proposal.TenantID, proposal.WarehouseID, proposal.ProductID, proposal.VariantID, -applied)
+	}
+	if err != nil {
+		return err
+	}
+
+	current := previous + applied
+	proposal.AppliedQuantity = &applied
+	proposal.PreviousOnHand = &previous
+	proposal.NewOnHand = &current
+	return nil
+}
+
+func getAdjustmentSettings(db *gorm.DB, tenantID string) (*models.AdjustmentApprovalSettings, error) {
+	var settings models.AdjustmentApprovalSettings
+	err := db.Where("tenant_id = ?", tenantID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultAdjustmentApprovalSettings(tenantID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-service/internal/metrics"
+	"inventory-service/internal/models"
+)
+
+// StorefrontAvailabilityCacheTTL is short because stock changes with every order, but
+// long enough to absorb bursts of anonymous product page views
+const StorefrontAvailabilityCacheTTL = 30 * time.Second
+
+// generateAvailabilityCacheKey creates a cache key for storefront availability lookups
+func generateAvailabilityCacheKey(tenantID string, productID uuid.UUID, variantID *uuid.UUID) string {
+	if variantID != nil {
+		return fmt.Sprintf("storefront:availability:%s:%s:%s", tenantID, productID.String(), variantID.String())
+	}
+	return fmt.Sprintf("storefront:availability:%s:%s:nil", tenantID, productID.String())
+}
+
+// GetStorefrontAvailability returns a product's (or variant's) availability across the tenant's
+// active warehouses, and per location for those offering click-and-collect, highest priority
+// first. Pickup locations without a stock record are listed as out of stock.
+func (r *InventoryRepository) GetStorefrontAvailability(ctx context.Context, tenantID string, productID uuid.UUID, variantID *uuid.UUID) (*models.StorefrontAvailability, error) {
+	cacheKey := "tesseract:inventory:" + generateAvailabilityCacheKey(tenantID, productID, variantID)
+
+	if r.redis != nil {
+		if val, err := r.redis.Get(ctx, cacheKey).Result(); err == nil {
+			var availability models.StorefrontAvailability
+			if err := json.Unmarshal([]byte(val), &availability); err == nil {
+				metrics.RecordCacheLookup("storefront_availability", true)
+				return &availability, nil
+			}
+		}
+		metrics.RecordCacheLookup("storefront_availability", false)
+	}
+
+	var warehouses []models.Warehouse
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND status = ?", tenantID, models.WarehouseStatusActive).
+		Order("priority DESC, name ASC").
+		Find(&warehouses).Error; err != nil {
+		return nil, err
+	}
+
+	var stocks []models.StockLevel
+	query := r.db.WithContext(ctx).Where("tenant_id = ? AND product_id = ?", tenantID, productID)
+	if variantID != nil {
+		query = query.Where("variant_id = ?", *variantID)
+	} else {
+		query = query.Where("variant_id IS NULL")
+	}
+	if err := query.Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+
+	stockByWarehouse := make(map[uuid.UUID]models.StockLevel, len(stocks))
+	for _, stock := range stocks {
+		stockByWarehouse[stock.WarehouseID] = stock
+	}
+
+	availability := &models.StorefrontAvailability{
+		ProductID:       productID,
+		VariantID:       variantID,
+		PickupLocations: []models.PickupLocation{},
+		CheckedAt:       time.Now().UTC(),
+	}
+	totalReorderPoint := 0
+	for _, warehouse := range warehouses {
+		stock := stockByWarehouse[warehouse.ID]
+		quantity := stock.QuantityAvailable
+		if quantity < 0 {
+			quantity = 0
+		}
+		availability.QuantityAvailable += quantity
+		totalReorderPoint += stock.ReorderPoint
+
+		if !warehouse.PickupEnabled {
+			continue
+		}
+		location := models.PickupLocation{
+			WarehouseID:        warehouse.ID,
+			Name:               warehouse.Name,
+			Address1:           warehouse.Address1,
+			Address2:           warehouse.Address2,
+			City:               warehouse.City,
+			State:              warehouse.State,
+			PostalCode:         warehouse.PostalCode,
+			Country:            warehouse.Country,
+			Phone:              warehouse.Phone,
+			PickupInstructions: warehouse.PickupInstructions,
+			Status:             stockStatus(quantity, stock.ReorderPoint),
+			QuantityAvailable:  quantity,
+		}
+		if quantity > 0 {
+			availability.PickupAvailable = true
+		}
+		availability.PickupLocations = append(availability.PickupLocations, location)
+	}
+	availability.Status = stockStatus(availability.QuantityAvailable, totalReorderPoint)
+
+	if r.redis != nil {
+		if data, err := json.Marshal(availability); err == nil {
+			r.redis.Set(ctx, cacheKey, data, StorefrontAvailabilityCacheTTL)
+		}
+	}
+
+	return availability, nil
+}
+
+// stockStatus reports low stock at or below the reorder point
+func stockStatus(quantity, reorderPoint int) models.StockStatus {
+	switch {
+	case quantity <= 0:
+		return models.StockStatusOutOfStock
+	case quantity <= reorderPoint:
+		return models.StockStatusLowStock
+	default:
+		return models.StockStatusInStock
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/Tesseract-Nexus/go-shared/cache"
+	"inventory-service/internal/bulk"
+	"inventory-service/internal/metrics"
 	"inventory-service/internal/models"
 	"gorm.io/gorm"
 )
@@ -80,6 +82,12 @@ func (r *InventoryRepository) invalidateStockCaches(ctx context.Context, tenantI
 
 	// Invalidate low stock cache
 	_ = r.cache.DeletePattern(ctx, fmt.Sprintf("stock:low:%s:*", tenantID))
+
+	// Invalidate storefront availability for this product
+	_ = r.cache.DeletePattern(ctx, fmt.Sprintf("storefront:availability:%s:%s:*", tenantID, productID.String()))
+
+	// Invalidate batch stock lookups for this product
+	_ = r.cache.DeletePattern(ctx, fmt.Sprintf("stock:batch:%s:%s:*", tenantID, productID.String()))
 }
 
 // invalidateTenantStockListCaches invalidates all stock list caches for a tenant
@@ -103,6 +111,9 @@ func (r *InventoryRepository) invalidateWarehouseCaches(ctx context.Context, ten
 	}
 	// Invalidate all warehouse lists
 	_ = r.cache.DeletePattern(ctx, fmt.Sprintf("warehouse:*:%s:*", tenantID))
+
+	// Pickup locations are part of storefront availability
+	_ = r.cache.DeletePattern(ctx, fmt.Sprintf("storefront:availability:%s:*", tenantID))
 }
 
 // Health returns the health status of Redis connection
@@ -368,8 +379,11 @@ func (r *InventoryRepository) UpdatePurchaseOrderStatus(tenantID string, id uuid
 		Updates(updates).Error
 }
 
-// ReceivePurchaseOrder marks items as received and updates stock levels
-func (r *InventoryRepository) ReceivePurchaseOrder(tenantID string, poID uuid.UUID, receivedItems map[uuid.UUID]int) error {
+// ReceivePurchaseOrder marks items as received and updates stock levels. Items with a bin
+// in putaway are placed in it; bins are suggested for the rest. The landed costs are
+// spread over the received lines, and the received units are valued at their price plus
+// their share.
+func (r *InventoryRepository) ReceivePurchaseOrder(tenantID string, poID uuid.UUID, receivedItems map[uuid.UUID]int, putaway map[uuid.UUID]uuid.UUID, landedCosts []models.LandedCostComponent) (*models.PurchaseOrderReceipt, error) {
 	tx := r.db.Begin()
 	defer func() {
 		if r := recover(); r != nil {
@@ -383,9 +397,23 @@ func (r *InventoryRepository) ReceivePurchaseOrder(tenantID string, poID uuid.UU
 		Preload("Items").
 		First(&po).Error; err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
+	}
+
+	var lines []receivedLine
+	for _, item := range po.Items {
+		if receivedQty := receivedItems[item.ID]; receivedQty > 0 {
+			lines = append(lines, receivedLine{item: item, quantity: receivedQty})
+		}
 	}
+	shares := allocateLandedCosts(lines, landedCosts)
 
+	receipt := &models.PurchaseOrderReceipt{
+		Putaway:     []models.Putaway{},
+		LandedCosts: []models.PurchaseOrderLandedCost{},
+	}
+	receivedAt := time.Now()
+	var planner *putawayPlanner
 	allReceived := true
 	for _, item := range po.Items {
 		if receivedQty, ok := receivedItems[item.ID]; ok {
@@ -394,13 +422,37 @@ func (r *InventoryRepository) ReceivePurchaseOrder(tenantID string, poID uuid.UU
 				Where("id = ?", item.ID).
 				Update("quantity_received", receivedQty).Error; err != nil {
 				tx.Rollback()
-				return err
+				return nil, err
 			}
 
 			// Update stock level
 			if err := r.addStockTx(tx, tenantID, po.WarehouseID, item.ProductID, item.VariantID, receivedQty); err != nil {
 				tx.Rollback()
-				return err
+				return nil, err
+			}
+
+			if receivedQty > 0 {
+				cost := landedCostRecord(tenantID, &po, receivedLine{item: item, quantity: receivedQty}, shares[item.ID], receivedAt)
+				if err := tx.Create(cost).Error; err != nil {
+					tx.Rollback()
+					return nil, err
+				}
+				if err := applyUnitCostTx(tx, tenantID, po.WarehouseID, item.ProductID, item.VariantID, receivedQty, cost.LandedUnitCost); err != nil {
+					tx.Rollback()
+					return nil, err
+				}
+				receipt.LandedCosts = append(receipt.LandedCosts, *cost)
+
+				var locationID *uuid.UUID
+				if binID, ok := putaway[item.ID]; ok {
+					locationID = &binID
+				}
+				entry, err := r.putawayTx(tx, tenantID, po.WarehouseID, &planner, item.ID, item.ProductID, item.VariantID, receivedQty, locationID)
+				if err != nil {
+					tx.Rollback()
+					return nil, err
+				}
+				receipt.Putaway = append(receipt.Putaway, *entry)
 			}
 
 			if receivedQty < item.QuantityOrdered {
@@ -425,18 +477,21 @@ func (r *InventoryRepository) ReceivePurchaseOrder(tenantID string, poID uuid.UU
 			"updated_at":    time.Now(),
 		}).Error; err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 
 	// Update supplier stats
 	if allReceived {
 		if err := r.UpdateSupplierStats(tenantID, po.SupplierID, po.Total); err != nil {
 			tx.Rollback()
-			return err
+			return nil, err
 		}
 	}
 
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return receipt, nil
 }
 
 // ========== Inventory Transfer Operations ==========
@@ -550,6 +605,12 @@ func (r *InventoryRepository) CompleteInventoryTransfer(tenantID string, transfe
 		return err
 	}
 
+	// In-transit transfers are received instead, completed ones already moved their stock
+	if transfer.Status != models.InventoryTransferStatusPending {
+		tx.Rollback()
+		return ErrTransferState
+	}
+
 	for _, item := range transfer.Items {
 		receivedQty := item.QuantityRequested
 		if qty, ok := receivedItems[item.ID]; ok {
@@ -578,6 +639,10 @@ func (r *InventoryRepository) CompleteInventoryTransfer(tenantID string, transfe
 			tx.Rollback()
 			return err
 		}
+		if err := carryUnitCostTx(tx, tenantID, transfer.FromWarehouseID, transfer.ToWarehouseID, item.ProductID, item.VariantID, receivedQty); err != nil {
+			tx.Rollback()
+			return err
+		}
 	}
 
 	// Update transfer status
@@ -609,9 +674,11 @@ func (r *InventoryRepository) GetStockLevel(tenantID string, warehouseID, produc
 		if err == nil {
 			var stock models.StockLevel
 			if err := json.Unmarshal([]byte(val), &stock); err == nil {
+				metrics.RecordCacheLookup("stock", true)
 				return &stock, nil
 			}
 		}
+		metrics.RecordCacheLookup("stock", false)
 	}
 
 	// Query from database
@@ -710,6 +777,9 @@ func (r *InventoryRepository) UpdateStockLevel(tenantID string, stockID uuid.UUI
 				"quantity_available": newAvailable,
 				"updated_at":         time.Now(),
 			}).Error
+		if updateErr == nil {
+			updateErr = consumeBinStockTx(r.db, tenantID, currentStock.WarehouseID, currentStock.ProductID, currentStock.VariantID, currentStock.QuantityOnHand-newOnHand)
+		}
 	} else {
 		// For additions, use the existing logic
 		updateErr = r.db.Model(&models.StockLevel{}).
@@ -817,11 +887,15 @@ func (r *InventoryRepository) removeStockTx(tx *gorm.DB, tenantID string, wareho
 		updateQuery = updateQuery.Where("variant_id IS NULL")
 	}
 
-	return updateQuery.Updates(map[string]interface{}{
+	if err := updateQuery.Updates(map[string]interface{}{
 		"quantity_on_hand":   newOnHand,
 		"quantity_available": newAvailable,
 		"updated_at":         time.Now(),
-	}).Error
+	}).Error; err != nil {
+		return err
+	}
+
+	return consumeBinStockTx(tx, tenantID, warehouseID, productID, variantID, quantity)
 }
 
 // GetLowStockItems returns items below reorder point
@@ -837,179 +911,46 @@ func (r *InventoryRepository) GetLowStockItems(tenantID string, warehouseID *uui
 	return stocks, err
 }
 
-// ========== Reservation Operations ==========
-
-// CreateReservation creates an inventory reservation
-func (r *InventoryRepository) CreateReservation(tenantID string, reservation *models.InventoryReservation) error {
-	reservation.TenantID = tenantID
-	reservation.ReservedAt = time.Now()
-	reservation.CreatedAt = time.Now()
-	reservation.UpdatedAt = time.Now()
-
-	// Update stock level to reduce available quantity
-	query := r.db.Model(&models.StockLevel{}).
-		Where("tenant_id = ? AND warehouse_id = ? AND product_id = ?",
-			tenantID, reservation.WarehouseID, reservation.ProductID)
-
-	if reservation.VariantID != nil {
-		query = query.Where("variant_id = ?", *reservation.VariantID)
-	} else {
-		query = query.Where("variant_id IS NULL")
-	}
-
-	if err := query.Updates(map[string]interface{}{
-		"quantity_reserved":  gorm.Expr("quantity_reserved + ?", reservation.Quantity),
-		"quantity_available": gorm.Expr("quantity_available - ?", reservation.Quantity),
-		"updated_at":         time.Now(),
-	}).Error; err != nil {
-		return err
-	}
-
-	return r.db.Create(reservation).Error
-}
-
-// ReleaseReservation releases an inventory reservation
-func (r *InventoryRepository) ReleaseReservation(tenantID string, reservationID uuid.UUID) error {
-	var reservation models.InventoryReservation
-	if err := r.db.Where("tenant_id = ? AND id = ?", tenantID, reservationID).
-		First(&reservation).Error; err != nil {
-		return err
-	}
-
-	// Update stock level to increase available quantity
-	query := r.db.Model(&models.StockLevel{}).
-		Where("tenant_id = ? AND warehouse_id = ? AND product_id = ?",
-			tenantID, reservation.WarehouseID, reservation.ProductID)
-
-	if reservation.VariantID != nil {
-		query = query.Where("variant_id = ?", *reservation.VariantID)
-	} else {
-		query = query.Where("variant_id IS NULL")
-	}
-
-	if err := query.Updates(map[string]interface{}{
-		"quantity_reserved":  gorm.Expr("quantity_reserved - ?", reservation.Quantity),
-		"quantity_available": gorm.Expr("quantity_available + ?", reservation.Quantity),
-		"updated_at":         time.Now(),
-	}).Error; err != nil {
-		return err
-	}
-
-	return r.db.Delete(&reservation).Error
-}
-
-// ReleaseExpiredReservations releases reservations that have expired
-func (r *InventoryRepository) ReleaseExpiredReservations(tenantID string) error {
-	var reservations []models.InventoryReservation
-	if err := r.db.Where("tenant_id = ? AND expires_at < ? AND status = ?",
-		tenantID, time.Now(), "ACTIVE").
-		Find(&reservations).Error; err != nil {
-		return err
-	}
-
-	for _, reservation := range reservations {
-		r.ReleaseReservation(tenantID, reservation.ID)
-	}
-
-	return nil
-}
-
 // ============================================================================
 // Bulk Create Operations - Consistent pattern for all services
 // ============================================================================
 
-// BulkCreateError represents an error for a single item in bulk create
-type BulkCreateError struct {
-	Index      int
-	ExternalID *string
-	Code       string
-	Message    string
-}
-
-// BulkCreateWarehouseResult represents the result of a bulk warehouse create
-type BulkCreateWarehouseResult struct {
-	Created []*models.Warehouse
-	Errors  []BulkCreateError
-	Total   int
-	Success int
-	Failed  int
-}
-
-// BulkCreateWarehouses creates multiple warehouses in a transaction
+// BulkCreateWarehouses creates multiple warehouses. Each warehouse succeeds or fails on its
+// own unless opts.Atomic is set.
 // SECURITY: All warehouses are assigned the provided tenantID
-func (r *InventoryRepository) BulkCreateWarehouses(tenantID string, warehouses []*models.Warehouse, skipDuplicates bool) (*BulkCreateWarehouseResult, error) {
-	result := &BulkCreateWarehouseResult{
-		Created: make([]*models.Warehouse, 0, len(warehouses)),
-		Errors:  make([]BulkCreateError, 0),
-		Total:   len(warehouses),
-	}
-
-	err := r.db.Transaction(func(tx *gorm.DB) error {
-		for i, warehouse := range warehouses {
-			// SECURITY: Always enforce tenant isolation
-			warehouse.TenantID = tenantID
-			warehouse.CreatedAt = time.Now()
-			warehouse.UpdatedAt = time.Now()
-
-			// Set default status if not provided
-			if warehouse.Status == "" {
-				warehouse.Status = models.WarehouseStatusActive
-			}
-
-			// Check for duplicate code within tenant
-			var existingCount int64
-			if err := tx.Model(&models.Warehouse{}).
-				Where("tenant_id = ? AND code = ?", tenantID, warehouse.Code).
-				Count(&existingCount).Error; err != nil {
-				result.Errors = append(result.Errors, BulkCreateError{
-					Index:   i,
-					Code:    "DB_ERROR",
-					Message: "Failed to check for duplicate code",
-				})
-				continue
-			}
-
-			if existingCount > 0 {
-				if skipDuplicates {
-					continue
-				}
-				result.Errors = append(result.Errors, BulkCreateError{
-					Index:   i,
-					Code:    "DUPLICATE_CODE",
-					Message: fmt.Sprintf("Warehouse with code '%s' already exists for this tenant", warehouse.Code),
-				})
-				continue
-			}
+func (r *InventoryRepository) BulkCreateWarehouses(tenantID string, warehouses []*models.Warehouse, skipDuplicates bool, opts bulk.Options) *bulk.Response {
+	return bulk.Run(r.db, len(warehouses), opts, func(tx *gorm.DB, i int) (bulk.Outcome, error) {
+		warehouse := warehouses[i]
+
+		// SECURITY: Always enforce tenant isolation
+		warehouse.TenantID = tenantID
+		warehouse.CreatedAt = time.Now()
+		warehouse.UpdatedAt = time.Now()
+
+		// Set default status if not provided
+		if warehouse.Status == "" {
+			warehouse.Status = models.WarehouseStatusActive
+		}
 
-			// Create the warehouse
-			if err := tx.Create(warehouse).Error; err != nil {
-				result.Errors = append(result.Errors, BulkCreateError{
-					Index:   i,
-					Code:    "CREATE_FAILED",
-					Message: err.Error(),
-				})
-				continue
+		// Check for duplicate code within tenant
+		var existingCount int64
+		if err := tx.Model(&models.Warehouse{}).
+			Where("tenant_id = ? AND code = ?", tenantID, warehouse.Code).
+			Count(&existingCount).Error; err != nil {
+			return bulk.Outcome{}, bulk.Fail("DB_ERROR", "Failed to check for duplicate code")
+		}
+		if existingCount > 0 {
+			if skipDuplicates {
+				return bulk.Skipped(), nil
 			}
-
-			result.Created = append(result.Created, warehouse)
+			return bulk.Outcome{}, bulk.Fail("DUPLICATE_CODE", "Warehouse with code '%s' already exists for this tenant", warehouse.Code)
 		}
 
-		result.Success = len(result.Created)
-		result.Failed = len(result.Errors)
-
-		// If all failed, rollback
-		if result.Success == 0 && result.Total > 0 {
-			return fmt.Errorf("all warehouses failed to create")
+		if err := tx.Create(warehouse).Error; err != nil {
+			return bulk.Outcome{}, bulk.Fail("CREATE_FAILED", "%s", err.Error())
 		}
-
-		return nil
+		return bulk.Created(warehouse), nil
 	})
-
-	if err != nil && result.Success == 0 {
-		return result, err
-	}
-
-	return result, nil
 }
 
 // BulkDeleteWarehouses deletes multiple warehouses by IDs
@@ -1037,90 +978,42 @@ func (r *InventoryRepository) BulkDeleteWarehouses(tenantID string, ids []uuid.U
 	return totalDeleted, failedIDs, err
 }
 
-// BulkCreateSupplierResult represents the result of a bulk supplier create
-type BulkCreateSupplierResult struct {
-	Created []*models.Supplier
-	Errors  []BulkCreateError
-	Total   int
-	Success int
-	Failed  int
-}
-
-// BulkCreateSuppliers creates multiple suppliers in a transaction
+// BulkCreateSuppliers creates multiple suppliers. Each supplier succeeds or fails on its own
+// unless opts.Atomic is set.
 // SECURITY: All suppliers are assigned the provided tenantID
-func (r *InventoryRepository) BulkCreateSuppliers(tenantID string, suppliers []*models.Supplier, skipDuplicates bool) (*BulkCreateSupplierResult, error) {
-	result := &BulkCreateSupplierResult{
-		Created: make([]*models.Supplier, 0, len(suppliers)),
-		Errors:  make([]BulkCreateError, 0),
-		Total:   len(suppliers),
-	}
-
-	err := r.db.Transaction(func(tx *gorm.DB) error {
-		for i, supplier := range suppliers {
-			// SECURITY: Always enforce tenant isolation
-			supplier.TenantID = tenantID
-			supplier.CreatedAt = time.Now()
-			supplier.UpdatedAt = time.Now()
-
-			// Set default status if not provided
-			if supplier.Status == "" {
-				supplier.Status = models.SupplierStatusActive
-			}
-
-			// Check for duplicate code within tenant
-			var existingCount int64
-			if err := tx.Model(&models.Supplier{}).
-				Where("tenant_id = ? AND code = ?", tenantID, supplier.Code).
-				Count(&existingCount).Error; err != nil {
-				result.Errors = append(result.Errors, BulkCreateError{
-					Index:   i,
-					Code:    "DB_ERROR",
-					Message: "Failed to check for duplicate code",
-				})
-				continue
-			}
-
-			if existingCount > 0 {
-				if skipDuplicates {
-					continue
-				}
-				result.Errors = append(result.Errors, BulkCreateError{
-					Index:   i,
-					Code:    "DUPLICATE_CODE",
-					Message: fmt.Sprintf("Supplier with code '%s' already exists for this tenant", supplier.Code),
-				})
-				continue
-			}
+func (r *InventoryRepository) BulkCreateSuppliers(tenantID string, suppliers []*models.Supplier, skipDuplicates bool, opts bulk.Options) *bulk.Response {
+	return bulk.Run(r.db, len(suppliers), opts, func(tx *gorm.DB, i int) (bulk.Outcome, error) {
+		supplier := suppliers[i]
+
+		// SECURITY: Always enforce tenant isolation
+		supplier.TenantID = tenantID
+		supplier.CreatedAt = time.Now()
+		supplier.UpdatedAt = time.Now()
+
+		// Set default status if not provided
+		if supplier.Status == "" {
+			supplier.Status = models.SupplierStatusActive
+		}
 
-			// Create the supplier
-			if err := tx.Create(supplier).Error; err != nil {
-				result.Errors = append(result.Errors, BulkCreateError{
-					Index:   i,
-					Code:    "CREATE_FAILED",
-					Message: err.Error(),
-				})
-				continue
+		// Check for duplicate code within tenant
+		var existingCount int64
+		if err := tx.Model(&models.Supplier{}).
+			Where("tenant_id = ? AND code = ?", tenantID, supplier.Code).
+			Count(&existingCount).Error; err != nil {
+			return bulk.Outcome{}, bulk.Fail("DB_ERROR", "Failed to check for duplicate code")
+		}
+		if existingCount > 0 {
+			if skipDuplicates {
+				return bulk.Skipped(), nil
 			}
-
-			result.Created = append(result.Created, supplier)
+			return bulk.Outcome{}, bulk.Fail("DUPLICATE_CODE", "Supplier with code '%s' already exists for this tenant", supplier.Code)
 		}
 
-		result.Success = len(result.Created)
-		result.Failed = len(result.Errors)
-
-		// If all failed, rollback
-		if result.Success == 0 && result.Total > 0 {
-			return fmt.Errorf("all suppliers failed to create")
+		if err := tx.Create(supplier).Error; err != nil {
+			return bulk.Outcome{}, bulk.Fail("CREATE_FAILED", "%s", err.Error())
 		}
-
-		return nil
+		return bulk.Created(supplier), nil
 	})
-
-	if err != nil && result.Success == 0 {
-		return result, err
-	}
-
-	return result, nil
 }
 
 // BulkDeleteSuppliers deletes multiple suppliers by IDs
@@ -0,0 +1,700 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-service/internal/models"
+)
+
+var (
+	// ErrLocationNotFound is returned when a location doesn't exist in the warehouse
+	ErrLocationNotFound = errors.New("warehouse location not found")
+	// ErrInvalidLocation is returned when a location doesn't fit the zone → aisle → shelf → bin
+	// hierarchy, or isn't an active bin where one is required
+	ErrInvalidLocation = errors.New("invalid warehouse location")
+	// ErrLocationCodeTaken is returned when a location's full code is already used in the warehouse
+	ErrLocationCodeTaken = errors.New("location code already exists in warehouse")
+	// ErrLocationInUse is returned when deleting a location that has child locations or stock
+	ErrLocationInUse = errors.New("location has child locations or stock")
+	// ErrBinCapacity is returned when a bin can't hold the units placed in it
+	ErrBinCapacity = errors.New("bin capacity exceeded")
+	// ErrInsufficientBinStock is returned when a bin, or the unassigned stock, can't cover a move
+	ErrInsufficientBinStock = errors.New("insufficient bin stock")
+)
+
+// ========== Location Operations ==========
+
+// CreateLocation creates a zone, aisle, shelf or bin under its parent in the hierarchy
+func (r *InventoryRepository) CreateLocation(ctx context.Context, tenantID string, location *models.WarehouseLocation) error {
+	if !location.Type.IsValid() {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidLocation, location.Type)
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var warehouse models.Warehouse
+		err := tx.Select("id").Where("tenant_id = ? AND id = ?", tenantID, location.WarehouseID).First(&warehouse).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: warehouse not found", ErrInvalidLocation)
+		}
+		if err != nil {
+			return err
+		}
+
+		location.FullCode = location.Code
+		parentType, hasParent := location.Type.ParentType()
+		switch {
+		case hasParent && location.ParentID == nil:
+			return fmt.Errorf("%w: a %s needs a parent %s", ErrInvalidLocation, location.Type, parentType)
+		case !hasParent && location.ParentID != nil:
+			return fmt.Errorf("%w: zones sit directly under the warehouse", ErrInvalidLocation)
+		case hasParent:
+			parent, err := getLocationTx(tx, tenantID, location.WarehouseID, *location.ParentID)
+			if err != nil {
+				return err
+			}
+			if parent.Type != parentType {
+				return fmt.Errorf("%w: a %s must sit under a %s", ErrInvalidLocation, location.Type, parentType)
+			}
+			location.FullCode = parent.FullCode + "-" + location.Code
+		}
+
+		var count int64
+		if err := tx.Model(&models.WarehouseLocation{}).
+			Where("warehouse_id = ? AND full_code = ?", location.WarehouseID, location.FullCode).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrLocationCodeTaken
+		}
+
+		location.TenantID = tenantID
+		return tx.Create(location).Error
+	})
+}
+
+// GetLocation retrieves a location in a warehouse
+func (r *InventoryRepository) GetLocation(ctx context.Context, tenantID string, warehouseID, id uuid.UUID) (*models.WarehouseLocation, error) {
+	return getLocationTx(r.db.WithContext(ctx), tenantID, warehouseID, id)
+}
+
+func getLocationTx(tx *gorm.DB, tenantID string, warehouseID, id uuid.UUID) (*models.WarehouseLocation, error) {
+	var location models.WarehouseLocation
+	err := tx.Where("tenant_id = ? AND warehouse_id = ? AND id = ?", tenantID, warehouseID, id).First(&location).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrLocationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// ListLocations lists a warehouse's locations in code order, optionally of one type or
+// under one parent
+func (r *InventoryRepository) ListLocations(ctx context.Context, tenantID string, warehouseID uuid.UUID, locationType *models.LocationType, parentID *uuid.UUID) ([]models.WarehouseLocation, error) {
+	var locations []models.WarehouseLocation
+	query := r.db.WithContext(ctx).Where("tenant_id = ? AND warehouse_id = ?", tenantID, warehouseID)
+	if locationType != nil {
+		query = query.Where("type = ?", *locationType)
+	}
+	if parentID != nil {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	err := query.Order("full_code ASC").Find(&locations).Error
+	return locations, err
+}
+
+// UpdateLocation updates a location's name, pick sequence, capacity or active flag.
+// Codes are fixed once created, as they are part of every descendant's full code.
+func (r *InventoryRepository) UpdateLocation(ctx context.Context, tenantID string, warehouseID, id uuid.UUID, updates map[string]interface{}) (*models.WarehouseLocation, error) {
+	if _, err := r.GetLocation(ctx, tenantID, warehouseID, id); err != nil {
+		return nil, err
+	}
+	updates["updated_at"] = time.Now()
+	if err := r.db.WithContext(ctx).Model(&models.WarehouseLocation{}).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return r.GetLocation(ctx, tenantID, warehouseID, id)
+}
+
+// DeleteLocation deletes a location with no child locations and no stock
+func (r *InventoryRepository) DeleteLocation(ctx context.Context, tenantID string, warehouseID, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := getLocationTx(tx, tenantID, warehouseID, id); err != nil {
+			return err
+		}
+
+		var children, stocked int64
+		if err := tx.Model(&models.WarehouseLocation{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.BinStock{}).Where("location_id = ? AND quantity > 0", id).Count(&stocked).Error; err != nil {
+			return err
+		}
+		if children > 0 || stocked > 0 {
+			return ErrLocationInUse
+		}
+
+		if err := tx.Where("location_id = ?", id).Delete(&models.BinStock{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WarehouseLocation{}, "id = ?", id).Error
+	})
+}
+
+// ========== Bin Stock Operations ==========
+
+// binStockQuery scopes a query to one product or variant's bin stock at a warehouse
+func binStockQuery(tx *gorm.DB, tenantID string, warehouseID, productID uuid.UUID, variantID *uuid.UUID) *gorm.DB {
+	query := tx.Model(&models.BinStock{}).
+		Where("tenant_id = ? AND warehouse_id = ? AND product_id = ?", tenantID, warehouseID, productID)
+	if variantID != nil {
+		return query.Where("variant_id = ?", *variantID)
+	}
+	return query.Where("variant_id IS NULL")
+}
+
+// ListBinStock lists the stock held in a warehouse's bins, optionally for one bin or product
+func (r *InventoryRepository) ListBinStock(ctx context.Context, tenantID string, warehouseID uuid.UUID, locationID, productID *uuid.UUID) ([]models.BinStock, error) {
+	var bins []models.BinStock
+	query := r.db.WithContext(ctx).Preload("Location").
+		Where("tenant_id = ? AND warehouse_id = ? AND quantity > 0", tenantID, warehouseID)
+	if locationID != nil {
+		query = query.Where("location_id = ?", *locationID)
+	}
+	if productID != nil {
+		query = query.Where("product_id = ?", *productID)
+	}
+	if err := query.Find(&bins).Error; err != nil {
+		return nil, err
+	}
+
+	tree, err := loadLocationTree(r.db.WithContext(ctx), tenantID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(bins, func(i, j int) bool { return tree.before(bins[i].LocationID, bins[j].LocationID) })
+	return bins, nil
+}
+
+// AttachBinStock fills in the bins holding each stock level
+func (r *InventoryRepository) AttachBinStock(ctx context.Context, tenantID string, stocks []models.StockLevel) error {
+	if len(stocks) == 0 {
+		return nil
+	}
+	warehouseIDs := make([]uuid.UUID, 0, len(stocks))
+	productIDs := make([]uuid.UUID, 0, len(stocks))
+	for _, stock := range stocks {
+		warehouseIDs = append(warehouseIDs, stock.WarehouseID)
+		productIDs = append(productIDs, stock.ProductID)
+	}
+
+	var bins []models.BinStock
+	if err := r.db.WithContext(ctx).Preload("Location").
+		Where("tenant_id = ? AND warehouse_id IN ? AND product_id IN ? AND quantity > 0", tenantID, warehouseIDs, productIDs).
+		Order("created_at ASC").
+		Find(&bins).Error; err != nil {
+		return err
+	}
+
+	byItem := make(map[string][]models.BinStock)
+	for _, bin := range bins {
+		key := stockItemKey(bin.WarehouseID, bin.ProductID, bin.VariantID)
+		byItem[key] = append(byItem[key], bin)
+	}
+	for i := range stocks {
+		stocks[i].Bins = byItem[stockItemKey(stocks[i].WarehouseID, stocks[i].ProductID, stocks[i].VariantID)]
+	}
+	return nil
+}
+
+func stockItemKey(warehouseID, productID uuid.UUID, variantID *uuid.UUID) string {
+	if variantID == nil {
+		return warehouseID.String() + "/" + productID.String()
+	}
+	return warehouseID.String() + "/" + productID.String() + "/" + variantID.String()
+}
+
+// MoveBinStock moves units into, out of or between bins. Units moved into a bin come
+// from the warehouse's unassigned stock when no source bin is given; units moved out of
+// a bin without a destination become unassigned. On-hand stock doesn't change.
+func (r *InventoryRepository) MoveBinStock(ctx context.Context, tenantID string, warehouseID uuid.UUID, req *models.MoveBinStockRequest) ([]models.BinStock, error) {
+	if req.FromLocationID == nil && req.ToLocationID == nil {
+		return nil, fmt.Errorf("%w: a source or destination bin is required", ErrInvalidLocation)
+	}
+	if req.FromLocationID != nil && req.ToLocationID != nil && *req.FromLocationID == *req.ToLocationID {
+		return nil, fmt.Errorf("%w: source and destination are the same bin", ErrInvalidLocation)
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if req.FromLocationID != nil {
+			if _, err := resolveBinTx(tx, tenantID, warehouseID, *req.FromLocationID, false); err != nil {
+				return err
+			}
+			result := binStockQuery(tx, tenantID, warehouseID, req.ProductID, req.VariantID).
+				Where("location_id = ? AND quantity >= ?", *req.FromLocationID, req.Quantity).
+				Updates(map[string]interface{}{
+					"quantity":   gorm.Expr("quantity - ?", req.Quantity),
+					"updated_at": time.Now(),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrInsufficientBinStock
+			}
+		} else {
+			unassigned, err := unassignedStockTx(tx, tenantID, warehouseID, req.ProductID, req.VariantID)
+			if err != nil {
+				return err
+			}
+			if unassigned < req.Quantity {
+				return fmt.Errorf("%w: %d units unassigned", ErrInsufficientBinStock, unassigned)
+			}
+		}
+
+		if req.ToLocationID == nil {
+			return nil
+		}
+		bin, err := resolveBinTx(tx, tenantID, warehouseID, *req.ToLocationID, true)
+		if err != nil {
+			return err
+		}
+		return addBinStockTx(tx, tenantID, bin, req.ProductID, req.VariantID, req.Quantity)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var bins []models.BinStock
+	err = binStockQuery(r.db.WithContext(ctx), tenantID, warehouseID, req.ProductID, req.VariantID).
+		Preload("Location").
+		Where("quantity > 0").
+		Find(&bins).Error
+	return bins, err
+}
+
+// resolveBinTx loads a bin in a warehouse. Stock can still be taken out of inactive bins,
+// but only active bins take new stock.
+func resolveBinTx(tx *gorm.DB, tenantID string, warehouseID, id uuid.UUID, receiving bool) (*models.WarehouseLocation, error) {
+	location, err := getLocationTx(tx, tenantID, warehouseID, id)
+	if err != nil {
+		return nil, err
+	}
+	if location.Type != models.LocationTypeBin {
+		return nil, fmt.Errorf("%w: %s is a %s, not a bin", ErrInvalidLocation, location.FullCode, location.Type)
+	}
+	if receiving && !location.IsActive {
+		return nil, fmt.Errorf("%w: bin %s is inactive", ErrInvalidLocation, location.FullCode)
+	}
+	return location, nil
+}
+
+// unassignedStockTx returns the on-hand units of a product or variant that aren't in any bin
+func unassignedStockTx(tx *gorm.DB, tenantID string, warehouseID, productID uuid.UUID, variantID *uuid.UUID) (int, error) {
+	var stock models.StockLevel
+	err := stockLevelQuery(tx, tenantID, warehouseID, productID, variantID).First(&stock).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var binned int
+	if err := binStockQuery(tx, tenantID, warehouseID, productID, variantID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&binned).Error; err != nil {
+		return 0, err
+	}
+	return stock.QuantityOnHand - binned, nil
+}
+
+// addBinStockTx places units of a product or variant in a bin, within the bin's capacity
+func addBinStockTx(tx *gorm.DB, tenantID string, bin *models.WarehouseLocation, productID uuid.UUID, variantID *uuid.UUID, quantity int) error {
+	if bin.Capacity != nil {
+		var used int
+		if err := tx.Model(&models.BinStock{}).
+			Select("COALESCE(SUM(quantity), 0)").
+			Where("location_id = ?", bin.ID).
+			Scan(&used).Error; err != nil {
+			return err
+		}
+		if used+quantity > *bin.Capacity {
+			return fmt.Errorf("%w: bin %s has room for %d units", ErrBinCapacity, bin.FullCode, *bin.Capacity-used)
+		}
+	}
+
+	result := binStockQuery(tx, tenantID, bin.WarehouseID, productID, variantID).
+		Where("location_id = ?", bin.ID).
+		Updates(map[string]interface{}{
+			"quantity":   gorm.Expr("quantity + ?", quantity),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	return tx.Create(&models.BinStock{
+		TenantID:    tenantID,
+		WarehouseID: bin.WarehouseID,
+		ProductID:   productID,
+		VariantID:   variantID,
+		LocationID:  bin.ID,
+		Quantity:    quantity,
+	}).Error
+}
+
+// consumeBinStockTx takes removed units out of bins in pick path order, the order they
+// are picked in. Units beyond what the bins hold come out of unassigned stock.
+func consumeBinStockTx(tx *gorm.DB, tenantID string, warehouseID, productID uuid.UUID, variantID *uuid.UUID, quantity int) error {
+	var bins []models.BinStock
+	if err := binStockQuery(tx, tenantID, warehouseID, productID, variantID).
+		Where("quantity > 0").
+		Find(&bins).Error; err != nil {
+		return err
+	}
+	if len(bins) == 0 {
+		return nil
+	}
+
+	tree, err := loadLocationTree(tx, tenantID, warehouseID)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(bins, func(i, j int) bool { return tree.before(bins[i].LocationID, bins[j].LocationID) })
+
+	for _, bin := range bins {
+		if quantity <= 0 {
+			break
+		}
+		take := min(bin.Quantity, quantity)
+		if err := tx.Model(&models.BinStock{}).
+			Where("id = ?", bin.ID).
+			Updates(map[string]interface{}{
+				"quantity":   gorm.Expr("quantity - ?", take),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		quantity -= take
+	}
+	return nil
+}
+
+// ========== Pick Paths ==========
+
+// locationTree indexes a warehouse's locations to order bins along the pick path
+type locationTree map[uuid.UUID]*models.WarehouseLocation
+
+func loadLocationTree(tx *gorm.DB, tenantID string, warehouseID uuid.UUID) (locationTree, error) {
+	var locations []models.WarehouseLocation
+	if err := tx.Where("tenant_id = ? AND warehouse_id = ?", tenantID, warehouseID).Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	tree := make(locationTree, len(locations))
+	for i := range locations {
+		tree[locations[i].ID] = &locations[i]
+	}
+	return tree, nil
+}
+
+// path returns a location and its ancestors, zone first
+func (t locationTree) path(id uuid.UUID) []*models.WarehouseLocation {
+	var path []*models.WarehouseLocation
+	for location := t[id]; location != nil; {
+		path = append([]*models.WarehouseLocation{location}, path...)
+		if location.ParentID == nil {
+			break
+		}
+		location = t[*location.ParentID]
+	}
+	return path
+}
+
+// before reports whether bin a comes before bin b on the pick path: zones, then aisles,
+// shelves and bins in pick sequence order, with the full code breaking ties
+func (t locationTree) before(a, b uuid.UUID) bool {
+	pathA, pathB := t.path(a), t.path(b)
+	for i := 0; i < len(pathA) && i < len(pathB); i++ {
+		if pathA[i].PickSequence != pathB[i].PickSequence {
+			return pathA[i].PickSequence < pathB[i].PickSequence
+		}
+		if pathA[i].Code != pathB[i].Code {
+			return pathA[i].Code < pathB[i].Code
+		}
+	}
+	return len(pathA) < len(pathB)
+}
+
+// stop describes a bin as a pick stop
+func (t locationTree) stop(id uuid.UUID) models.PickStop {
+	stop := models.PickStop{LocationID: id}
+	for _, location := range t.path(id) {
+		switch location.Type {
+		case models.LocationTypeZone:
+			stop.ZoneCode = location.Code
+		case models.LocationTypeAisle:
+			stop.AisleCode = location.Code
+		case models.LocationTypeShelf:
+			stop.ShelfCode = location.Code
+		case models.LocationTypeBin:
+			stop.BinCode = location.FullCode
+		}
+	}
+	return stop
+}
+
+// PlanPickPath plans the bins to pick a set of items from in a warehouse. Each item is
+// taken from bins in pick path order, and the stops for all items are visited in one
+// pass. Quantities the bins can't cover are reported as shortfall.
+func (r *InventoryRepository) PlanPickPath(ctx context.Context, tenantID string, warehouseID uuid.UUID, items []models.PickItem) (*models.PickPath, error) {
+	db := r.db.WithContext(ctx)
+	tree, err := loadLocationTree(db, tenantID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	path := &models.PickPath{
+		WarehouseID: warehouseID,
+		Stops:       []models.PickStop{},
+		Shortfall:   []models.PickItem{},
+	}
+	for _, item := range items {
+		var bins []models.BinStock
+		if err := binStockQuery(db, tenantID, warehouseID, item.ProductID, item.VariantID).
+			Where("quantity > 0").
+			Find(&bins).Error; err != nil {
+			return nil, err
+		}
+		sort.SliceStable(bins, func(i, j int) bool { return tree.before(bins[i].LocationID, bins[j].LocationID) })
+
+		remaining := item.Quantity
+		for _, bin := range bins {
+			if remaining <= 0 {
+				break
+			}
+			if location := tree[bin.LocationID]; location == nil || !location.IsActive {
+				continue
+			}
+			stop := tree.stop(bin.LocationID)
+			stop.ProductID = item.ProductID
+			stop.VariantID = item.VariantID
+			stop.Quantity = min(bin.Quantity, remaining)
+			path.Stops = append(path.Stops, stop)
+			remaining -= stop.Quantity
+		}
+		if remaining > 0 {
+			path.Shortfall = append(path.Shortfall, models.PickItem{
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+				Quantity:  remaining,
+			})
+		}
+	}
+
+	sort.SliceStable(path.Stops, func(i, j int) bool {
+		return tree.before(path.Stops[i].LocationID, path.Stops[j].LocationID)
+	})
+	for i := range path.Stops {
+		path.Stops[i].Sequence = i + 1
+	}
+	return path, nil
+}
+
+// PlanTransferPickPath plans the pick path for a transfer's items at its source warehouse
+func (r *InventoryRepository) PlanTransferPickPath(ctx context.Context, tenantID string, transferID uuid.UUID) (*models.PickPath, error) {
+	transfer, err := r.GetInventoryTransferByID(tenantID, transferID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Status != models.InventoryTransferStatusPending {
+		return nil, ErrTransferState
+	}
+
+	items := make([]models.PickItem, 0, len(transfer.Items))
+	for _, item := range transfer.Items {
+		items = append(items, models.PickItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.QuantityRequested,
+		})
+	}
+	return r.PlanPickPath(ctx, tenantID, transfer.FromWarehouseID, items)
+}
+
+// ========== Putaway ==========
+
+// putawayPlanner suggests bins for received units. Bins that already hold the item come
+// first, then empty bins, each in pick path order. Suggestions made by one planner count
+// against bin capacity for the ones after them.
+type putawayPlanner struct {
+	tree     locationTree
+	bins     []*models.WarehouseLocation
+	used     map[uuid.UUID]int
+	holdings map[string]map[uuid.UUID]bool
+}
+
+func newPutawayPlanner(tx *gorm.DB, tenantID string, warehouseID uuid.UUID) (*putawayPlanner, error) {
+	tree, err := loadLocationTree(tx, tenantID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	planner := &putawayPlanner{
+		tree:     tree,
+		used:     make(map[uuid.UUID]int),
+		holdings: make(map[string]map[uuid.UUID]bool),
+	}
+	for _, location := range tree {
+		if location.Type == models.LocationTypeBin && location.IsActive {
+			planner.bins = append(planner.bins, location)
+		}
+	}
+	sort.SliceStable(planner.bins, func(i, j int) bool { return tree.before(planner.bins[i].ID, planner.bins[j].ID) })
+
+	var stock []models.BinStock
+	if err := tx.Where("tenant_id = ? AND warehouse_id = ? AND quantity > 0", tenantID, warehouseID).Find(&stock).Error; err != nil {
+		return nil, err
+	}
+	for _, bin := range stock {
+		planner.used[bin.LocationID] += bin.Quantity
+		key := stockItemKey(warehouseID, bin.ProductID, bin.VariantID)
+		if planner.holdings[key] == nil {
+			planner.holdings[key] = make(map[uuid.UUID]bool)
+		}
+		planner.holdings[key][bin.LocationID] = true
+	}
+	return planner, nil
+}
+
+// room returns how many more units a bin takes, or -1 if it's unlimited
+func (p *putawayPlanner) room(bin *models.WarehouseLocation) int {
+	if bin.Capacity == nil {
+		return -1
+	}
+	return max(*bin.Capacity-p.used[bin.ID], 0)
+}
+
+// suggest spreads a quantity over bins and returns the placements and units left over
+func (p *putawayPlanner) suggest(warehouseID, productID uuid.UUID, variantID *uuid.UUID, quantity int) ([]models.BinPlacement, int) {
+	placements := []models.BinPlacement{}
+	held := p.holdings[stockItemKey(warehouseID, productID, variantID)]
+
+	place := func(bin *models.WarehouseLocation, reason string) {
+		room := p.room(bin)
+		if room == 0 {
+			return
+		}
+		qty := quantity
+		if room > 0 {
+			qty = min(room, quantity)
+		}
+		placements = append(placements, models.BinPlacement{
+			LocationID: bin.ID,
+			BinCode:    bin.FullCode,
+			Quantity:   qty,
+			Reason:     reason,
+		})
+		p.used[bin.ID] += qty
+		quantity -= qty
+	}
+
+	for _, bin := range p.bins {
+		if quantity > 0 && held[bin.ID] {
+			place(bin, models.PutawayReasonExistingStock)
+		}
+	}
+	for _, bin := range p.bins {
+		if quantity > 0 && !held[bin.ID] && p.used[bin.ID] == 0 {
+			place(bin, models.PutawayReasonEmptyBin)
+		}
+	}
+	return placements, quantity
+}
+
+// GetPurchaseOrderPutaway suggests bins for the units of a purchase order still to be received
+func (r *InventoryRepository) GetPurchaseOrderPutaway(ctx context.Context, tenantID string, poID uuid.UUID) ([]models.Putaway, error) {
+	db := r.db.WithContext(ctx)
+	var po models.PurchaseOrder
+	if err := db.Where("tenant_id = ? AND id = ?", tenantID, poID).Preload("Items").First(&po).Error; err != nil {
+		return nil, err
+	}
+
+	planner, err := newPutawayPlanner(db, tenantID, po.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	putaway := []models.Putaway{}
+	for _, item := range po.Items {
+		outstanding := item.QuantityOrdered - item.QuantityReceived
+		if outstanding <= 0 {
+			continue
+		}
+		placements, unplaced := planner.suggest(po.WarehouseID, item.ProductID, item.VariantID, outstanding)
+		putaway = append(putaway, models.Putaway{
+			ItemID:     item.ID,
+			ProductID:  item.ProductID,
+			VariantID:  item.VariantID,
+			Quantity:   outstanding,
+			Placements: placements,
+			Unplaced:   unplaced,
+		})
+	}
+	return putaway, nil
+}
+
+// putawayTx puts received units away. With a bin they are placed there; otherwise they
+// stay unassigned and bins are suggested for them.
+func (r *InventoryRepository) putawayTx(tx *gorm.DB, tenantID string, warehouseID uuid.UUID, planner **putawayPlanner, itemID, productID uuid.UUID, variantID *uuid.UUID, quantity int, locationID *uuid.UUID) (*models.Putaway, error) {
+	putaway := &models.Putaway{
+		ItemID:    itemID,
+		ProductID: productID,
+		VariantID: variantID,
+		Quantity:  quantity,
+	}
+
+	if locationID != nil {
+		bin, err := resolveBinTx(tx, tenantID, warehouseID, *locationID, true)
+		if err != nil {
+			return nil, err
+		}
+		if err := addBinStockTx(tx, tenantID, bin, productID, variantID, quantity); err != nil {
+			return nil, err
+		}
+		if *planner != nil {
+			(*planner).used[bin.ID] += quantity
+		}
+		putaway.Applied = true
+		putaway.Placements = []models.BinPlacement{{
+			LocationID: bin.ID,
+			BinCode:    bin.FullCode,
+			Quantity:   quantity,
+			Reason:     models.PutawayReasonAssigned,
+		}}
+		return putaway, nil
+	}
+
+	if *planner == nil {
+		p, err := newPutawayPlanner(tx, tenantID, warehouseID)
+		if err != nil {
+			return nil, err
+		}
+		*planner = p
+	}
+	putaway.Placements, putaway.Unplaced = (*planner).suggest(warehouseID, productID, variantID, quantity)
+	return putaway, nil
+}
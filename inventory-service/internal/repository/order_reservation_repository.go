@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-service/internal/metrics"
+	"inventory-service/internal/models"
+)
+
+// Reservation statuses for stock held for an order or a cart during checkout. An ACTIVE
+// reservation keeps stock out of quantity_available until it is paid for (CONFIRMED, the stock
+// leaves quantity_on_hand), abandoned (RELEASED) or left past its expiry (EXPIRED). Released and
+// expired stock is available again.
+const (
+	ReservationStatusActive    = "ACTIVE"
+	ReservationStatusConfirmed = "CONFIRMED"
+	ReservationStatusReleased  = "RELEASED"
+	ReservationStatusExpired   = "EXPIRED"
+)
+
+var (
+	// ErrInsufficientStock is returned when no active warehouse can cover an order line
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrReservationNotFound is returned when no reservation has the given ID
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrReservationSettled is returned when confirming a released or expired reservation, or
+	// releasing a confirmed one
+	ErrReservationSettled = errors.New("reservation already settled")
+)
+
+// ReserveOrderStock holds an order's lines until expiresAt, each from the active warehouse with
+// the most stock available. Products without a stock level anywhere aren't tracked in
+// inventory-service and are skipped. Reserving an order twice is a no-op, so callers can
+// safely retry.
+func (r *InventoryRepository) ReserveOrderStock(ctx context.Context, tenantID string, orderID uuid.UUID, lines []models.InventoryReservation, expiresAt time.Time) ([]models.InventoryReservation, error) {
+	reservation, _, err := r.ReserveStock(ctx, tenantID, &orderID, nil, lines, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return reservation.Items, nil
+}
+
+// ReserveStock holds lines for an order or a cart (exactly one of orderID and cartID) until
+// expiresAt, as ReserveOrderStock does. While the order already has an active or confirmed
+// reservation, or the cart an active one, that reservation is returned with created false
+// instead, so callers can safely retry.
+func (r *InventoryRepository) ReserveStock(ctx context.Context, tenantID string, orderID, cartID *uuid.UUID, lines []models.InventoryReservation, expiresAt time.Time) (*models.Reservation, bool, error) {
+	holder, holderID := "order_id", orderID
+	statuses := []string{ReservationStatusActive, ReservationStatusConfirmed}
+	if orderID == nil {
+		// A cart can be reserved again once its last reservation was checked out
+		holder, holderID = "cart_id", cartID
+		statuses = []string{ReservationStatusActive}
+	}
+
+	reservationID := uuid.New()
+	var existing []models.InventoryReservation
+	reserved := []models.InventoryReservation{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tenant_id = ? AND "+holder+" = ? AND status IN ?", tenantID, *holderID, statuses).
+			Find(&existing).Error; err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			return nil
+		}
+
+		now := time.Now()
+		for _, line := range lines {
+			var stocks []models.StockLevel
+			query := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "stock_levels"}}).
+				Select("stock_levels.*").
+				Joins("JOIN warehouses ON warehouses.id = stock_levels.warehouse_id AND warehouses.status = ?", models.WarehouseStatusActive).
+				Where("stock_levels.tenant_id = ? AND stock_levels.product_id = ?", tenantID, line.ProductID)
+			if line.VariantID != nil {
+				query = query.Where("stock_levels.variant_id = ?", *line.VariantID)
+			} else {
+				query = query.Where("stock_levels.variant_id IS NULL")
+			}
+			if err := query.Order("stock_levels.quantity_available DESC").Find(&stocks).Error; err != nil {
+				return err
+			}
+			if len(stocks) == 0 {
+				continue
+			}
+			if stocks[0].QuantityAvailable < line.Quantity {
+				return fmt.Errorf("%w: product %s", ErrInsufficientStock, line.ProductID)
+			}
+
+			if err := tx.Model(&models.StockLevel{}).
+				Where("id = ?", stocks[0].ID).
+				Updates(map[string]interface{}{
+					"quantity_reserved":  gorm.Expr("quantity_reserved + ?", line.Quantity),
+					"quantity_available": gorm.Expr("quantity_available - ?", line.Quantity),
+					"updated_at":         now,
+				}).Error; err != nil {
+				return err
+			}
+
+			reservation := models.InventoryReservation{
+				TenantID:      tenantID,
+				ReservationID: &reservationID,
+				WarehouseID:   stocks[0].WarehouseID,
+				ProductID:     line.ProductID,
+				VariantID:     line.VariantID,
+				Quantity:      line.Quantity,
+				OrderID:       orderID,
+				CartID:        cartID,
+				ReservedAt:    now,
+				ExpiresAt:     expiresAt,
+				Status:        ReservationStatusActive,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			}
+			if err := tx.Create(&reservation).Error; err != nil {
+				return err
+			}
+			reserved = append(reserved, reservation)
+		}
+		return nil
+	})
+	if err != nil {
+		metrics.RecordReservationFailure(metrics.ReservationReserve, reservationFailureReason(err))
+		return nil, false, err
+	}
+
+	if len(existing) > 0 {
+		id := uuid.Nil
+		if existing[0].ReservationID != nil {
+			id = *existing[0].ReservationID
+		}
+		return models.NewReservation(id, existing), false, nil
+	}
+
+	metrics.RecordReservation(ReservationStatusActive)
+	for _, reservation := range reserved {
+		r.invalidateStockCaches(ctx, tenantID, reservation.WarehouseID, reservation.ProductID, reservation.VariantID)
+	}
+	return &models.Reservation{
+		ID:        reservationID,
+		OrderID:   orderID,
+		CartID:    cartID,
+		Status:    ReservationStatusActive,
+		ExpiresAt: expiresAt,
+		Items:     reserved,
+	}, true, nil
+}
+
+// GetReservation returns the lines reserved together under id
+func (r *InventoryRepository) GetReservation(ctx context.Context, tenantID string, id uuid.UUID) (*models.Reservation, error) {
+	var lines []models.InventoryReservation
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND reservation_id = ?", tenantID, id).
+		Order("created_at ASC").
+		Find(&lines).Error; err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, ErrReservationNotFound
+	}
+	return models.NewReservation(id, lines), nil
+}
+
+// ConfirmReservation takes an active reservation out of stock on hand, recording orderID on it
+// when given. Confirming it again is a no-op, reported by settled being false.
+func (r *InventoryRepository) ConfirmReservation(ctx context.Context, tenantID string, id uuid.UUID, orderID *uuid.UUID) (reservation *models.Reservation, settled bool, err error) {
+	updates := map[string]interface{}{}
+	if orderID != nil {
+		updates["order_id"] = *orderID
+	}
+	return r.settleReservation(ctx, tenantID, id, ReservationStatusConfirmed, updates, confirmReservedStock)
+}
+
+// ReleaseReservation returns an active reservation to available stock. Releasing it again, or
+// after it expired, is a no-op, reported by settled being false.
+func (r *InventoryRepository) ReleaseReservation(ctx context.Context, tenantID string, id uuid.UUID) (reservation *models.Reservation, settled bool, err error) {
+	return r.settleReservation(ctx, tenantID, id, ReservationStatusReleased, nil, releaseReservedStock)
+}
+
+func (r *InventoryRepository) settleReservation(ctx context.Context, tenantID string, id uuid.UUID, status string, updates map[string]interface{}, stockChange func(models.InventoryReservation) map[string]interface{}) (*models.Reservation, bool, error) {
+	settled, err := r.settleReservations(ctx, tenantID, "reservation_id", id, status, updates, stockChange)
+	if err != nil {
+		return nil, false, err
+	}
+	reservation, err := r.GetReservation(ctx, tenantID, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(settled) == 0 && reservation.Status != status &&
+		!(status == ReservationStatusReleased && reservation.Status == ReservationStatusExpired) {
+		return nil, false, fmt.Errorf("%w: reservation is %s", ErrReservationSettled, reservation.Status)
+	}
+	return reservation, len(settled) > 0, nil
+}
+
+// ExpireReservations releases up to limit active reservation lines that expired before now,
+// along with the rest of their reservations. It returns the reservations it expired and how
+// many lines it found, so callers can tell whether more are waiting.
+func (r *InventoryRepository) ExpireReservations(ctx context.Context, now time.Time, limit int) ([]models.Reservation, int, error) {
+	var lines []models.InventoryReservation
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", ReservationStatusActive, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&lines).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Lines reserved before reservations had IDs are grouped by their order
+	type holder struct {
+		tenantID string
+		column   string
+		id       uuid.UUID
+	}
+	var holders []holder
+	seen := make(map[holder]bool)
+	for _, line := range lines {
+		h := holder{tenantID: line.TenantID}
+		switch {
+		case line.ReservationID != nil:
+			h.column, h.id = "reservation_id", *line.ReservationID
+		case line.OrderID != nil:
+			h.column, h.id = "order_id", *line.OrderID
+		default:
+			continue
+		}
+		if !seen[h] {
+			seen[h] = true
+			holders = append(holders, h)
+		}
+	}
+
+	var expired []models.Reservation
+	var errs []error
+	for _, h := range holders {
+		settled, err := r.settleReservations(ctx, h.tenantID, h.column, h.id, ReservationStatusExpired, nil, releaseReservedStock)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", h.column, h.id, err))
+			continue
+		}
+		if len(settled) == 0 {
+			continue
+		}
+		id := uuid.Nil
+		if settled[0].ReservationID != nil {
+			id = *settled[0].ReservationID
+		}
+		expired = append(expired, *models.NewReservation(id, settled))
+	}
+	return expired, len(lines), errors.Join(errs...)
+}
+
+// ReleaseOrderStock returns an order's active reservations to available stock. Orders with
+// nothing reserved are a no-op.
+func (r *InventoryRepository) ReleaseOrderStock(ctx context.Context, tenantID string, orderID uuid.UUID) error {
+	_, err := r.settleReservations(ctx, tenantID, "order_id", orderID, ReservationStatusReleased, nil, releaseReservedStock)
+	return err
+}
+
+// ConfirmOrderStock takes a paid order's active reservations out of stock on hand. Orders with
+// nothing reserved are a no-op.
+func (r *InventoryRepository) ConfirmOrderStock(ctx context.Context, tenantID string, orderID uuid.UUID) error {
+	_, err := r.settleReservations(ctx, tenantID, "order_id", orderID, ReservationStatusConfirmed, nil, confirmReservedStock)
+	return err
+}
+
+func releaseReservedStock(reservation models.InventoryReservation) map[string]interface{} {
+	return map[string]interface{}{
+		"quantity_reserved":  gorm.Expr("quantity_reserved - ?", reservation.Quantity),
+		"quantity_available": gorm.Expr("quantity_available + ?", reservation.Quantity),
+	}
+}
+
+func confirmReservedStock(reservation models.InventoryReservation) map[string]interface{} {
+	return map[string]interface{}{
+		"quantity_reserved": gorm.Expr("quantity_reserved - ?", reservation.Quantity),
+		"quantity_on_hand":  gorm.Expr("quantity_on_hand - ?", reservation.Quantity),
+	}
+}
+
+// settleReservations moves the active reservation lines whose column matches id to status,
+// applying the stock level change each one needs and any further column updates. It returns
+// the lines it moved.
+func (r *InventoryRepository) settleReservations(ctx context.Context, tenantID, column string, id uuid.UUID, status string, updates map[string]interface{}, stockChange func(models.InventoryReservation) map[string]interface{}) ([]models.InventoryReservation, error) {
+	var reservations []models.InventoryReservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND "+column+" = ? AND status = ?", tenantID, id, ReservationStatusActive).
+			Find(&reservations).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i, reservation := range reservations {
+			stockUpdates := stockChange(reservation)
+			stockUpdates["updated_at"] = now
+			query := tx.Model(&models.StockLevel{}).
+				Where("tenant_id = ? AND warehouse_id = ? AND product_id = ?", tenantID, reservation.WarehouseID, reservation.ProductID)
+			if reservation.VariantID != nil {
+				query = query.Where("variant_id = ?", *reservation.VariantID)
+			} else {
+				query = query.Where("variant_id IS NULL")
+			}
+			if err := query.Updates(stockUpdates).Error; err != nil {
+				return err
+			}
+
+			reservationUpdates := map[string]interface{}{
+				"status":     status,
+				"updated_at": now,
+			}
+			for name, value := range updates {
+				reservationUpdates[name] = value
+			}
+			if err := tx.Model(&models.InventoryReservation{}).
+				Where("id = ?", reservation.ID).
+				Updates(reservationUpdates).Error; err != nil {
+				return err
+			}
+
+			reservations[i].Status = status
+			reservations[i].UpdatedAt = now
+			if orderID, ok := updates["order_id"].(uuid.UUID); ok {
+				reservations[i].OrderID = &orderID
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reservations) > 0 {
+		metrics.RecordReservation(status)
+	}
+	for _, reservation := range reservations {
+		r.invalidateStockCaches(ctx, tenantID, reservation.WarehouseID, reservation.ProductID, reservation.VariantID)
+	}
+	return reservations, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-service/internal/metrics"
+	"inventory-service/internal/models"
+)
+
+// Reservation statuses used for click-and-collect orders. Stock is deducted when the
+// order is placed, so these never expire like ACTIVE reservations do.
+const (
+	ReservationStatusPickupDeducted = "PICKUP_DEDUCTED"
+	ReservationStatusPickupRestored = "PICKUP_RESTORED"
+)
+
+var (
+	// ErrPickupLocationNotFound is returned when a warehouse doesn't exist, isn't active
+	// or doesn't offer click-and-collect
+	ErrPickupLocationNotFound = errors.New("pickup location not found")
+	// ErrInsufficientPickupStock is returned when the location can't cover an order line
+	ErrInsufficientPickupStock = errors.New("insufficient stock at pickup location")
+)
+
+// GetPickupLocation returns an active, pickup-enabled warehouse
+func (r *InventoryRepository) GetPickupLocation(ctx context.Context, tenantID string, id uuid.UUID) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ? AND status = ? AND pickup_enabled = ?", tenantID, id, models.WarehouseStatusActive, true).
+		First(&warehouse).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPickupLocationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// DeductPickupStock removes a click-and-collect order's lines from the pickup location and
+// records each as a reservation against the order. Deducting an order twice is a no-op, so
+// callers can safely retry.
+func (r *InventoryRepository) DeductPickupStock(ctx context.Context, tenantID string, warehouseID, orderID uuid.UUID, lines []models.InventoryReservation) error {
+	if _, err := r.GetPickupLocation(ctx, tenantID, warehouseID); err != nil {
+		metrics.RecordReservationFailure(metrics.ReservationPickupDeduct, reservationFailureReason(err))
+		return err
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&models.InventoryReservation{}).
+			Where("tenant_id = ? AND order_id = ? AND status = ?", tenantID, orderID, ReservationStatusPickupDeducted).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return nil
+		}
+
+		now := time.Now()
+		for _, line := range lines {
+			var stock models.StockLevel
+			query := tx.Where("tenant_id = ? AND warehouse_id = ? AND product_id = ?", tenantID, warehouseID, line.ProductID)
+			if line.VariantID != nil {
+				query = query.Where("variant_id = ?", *line.VariantID)
+			} else {
+				query = query.Where("variant_id IS NULL")
+			}
+			err := query.First(&stock).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && stock.QuantityAvailable < line.Quantity) {
+				return fmt.Errorf("%w: product %s", ErrInsufficientPickupStock, line.ProductID)
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := r.removeStockTx(tx, tenantID, warehouseID, line.ProductID, line.VariantID, line.Quantity); err != nil {
+				return err
+			}
+
+			reservation := models.InventoryReservation{
+				TenantID:    tenantID,
+				WarehouseID: warehouseID,
+				ProductID:   line.ProductID,
+				VariantID:   line.VariantID,
+				Quantity:    line.Quantity,
+				OrderID:     &orderID,
+				ReservedAt:  now,
+				ExpiresAt:   now,
+				Status:      ReservationStatusPickupDeducted,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+			if err := tx.Create(&reservation).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		metrics.RecordReservationFailure(metrics.ReservationPickupDeduct, reservationFailureReason(err))
+		return err
+	}
+
+	for _, line := range lines {
+		r.invalidateStockCaches(ctx, tenantID, warehouseID, line.ProductID, line.VariantID)
+	}
+	return nil
+}
+
+// reservationFailureReason classifies a failed reservation for the failure metric
+func reservationFailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrInsufficientPickupStock), errors.Is(err, ErrInsufficientStock):
+		return metrics.FailureInsufficientStock
+	case errors.Is(err, ErrPickupLocationNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		return metrics.FailureNotFound
+	}
+	return metrics.FailureError
+}
+
+// RestorePickupStock returns a click-and-collect order's deducted lines to their location.
+// Orders with nothing left to restore are a no-op.
+func (r *InventoryRepository) RestorePickupStock(ctx context.Context, tenantID string, orderID uuid.UUID) error {
+	var reservations []models.InventoryReservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tenant_id = ? AND order_id = ? AND status = ?", tenantID, orderID, ReservationStatusPickupDeducted).
+			Find(&reservations).Error; err != nil {
+			return err
+		}
+
+		for _, reservation := range reservations {
+			if err := r.addStockTx(tx, tenantID, reservation.WarehouseID, reservation.ProductID, reservation.VariantID, reservation.Quantity); err != nil {
+				return err
+			}
+			if err := tx.Model(&models.InventoryReservation{}).
+				Where("id = ?", reservation.ID).
+				Updates(map[string]interface{}{
+					"status":     ReservationStatusPickupRestored,
+					"updated_at": time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range reservations {
+		r.invalidateStockCaches(ctx, tenantID, reservation.WarehouseID, reservation.ProductID, reservation.VariantID)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-service/internal/models"
+)
+
+// StockBatchCacheTTL is short because batch lookups back checkout, and reservations
+// change with every cart
+const StockBatchCacheTTL = 30 * time.Second
+
+// generateStockBatchCacheKey creates a cache key for a product or variant's stock across
+// warehouses, as served to batch lookups
+func generateStockBatchCacheKey(tenantID string, productID uuid.UUID, variantID *uuid.UUID) string {
+	if variantID != nil {
+		return fmt.Sprintf("stock:batch:%s:%s:%s", tenantID, productID.String(), variantID.String())
+	}
+	return fmt.Sprintf("stock:batch:%s:%s:nil", tenantID, productID.String())
+}
+
+// GetStockBatch returns the stock of many products or variants per warehouse, with their
+// active reservations, in one round trip to Redis and at most two queries for the misses.
+// Entries follow the order of items, duplicates included. With a warehouse, only its
+// stock is returned.
+func (r *InventoryRepository) GetStockBatch(ctx context.Context, tenantID string, items []models.StockBatchItem, warehouseID *uuid.UUID, includeReservations bool) ([]models.StockBatchEntry, error) {
+	stockByKey := make(map[string][]models.WarehouseStock, len(items))
+	var keys []string
+	for _, item := range items {
+		key := generateStockBatchCacheKey(tenantID, item.ProductID, item.VariantID)
+		if _, ok := stockByKey[key]; !ok {
+			stockByKey[key] = nil
+			keys = append(keys, key)
+		}
+	}
+
+	missing := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		missing[key] = true
+	}
+	if r.redis != nil && len(keys) > 0 {
+		cacheKeys := make([]string, len(keys))
+		for i, key := range keys {
+			cacheKeys[i] = "tesseract:inventory:" + key
+		}
+		if values, err := r.redis.MGet(ctx, cacheKeys...).Result(); err == nil {
+			for i, value := range values {
+				raw, ok := value.(string)
+				if !ok {
+					continue
+				}
+				var stock []models.WarehouseStock
+				if err := json.Unmarshal([]byte(raw), &stock); err == nil {
+					stockByKey[keys[i]] = stock
+					delete(missing, keys[i])
+				}
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		if err := r.loadStockBatch(ctx, tenantID, items, missing, stockByKey); err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make([]models.StockBatchEntry, 0, len(items))
+	for _, item := range items {
+		entry := models.StockBatchEntry{
+			ProductID:  item.ProductID,
+			VariantID:  item.VariantID,
+			Warehouses: []models.WarehouseStock{},
+		}
+		for _, stock := range stockByKey[generateStockBatchCacheKey(tenantID, item.ProductID, item.VariantID)] {
+			if warehouseID != nil && stock.WarehouseID != *warehouseID {
+				continue
+			}
+			if !includeReservations {
+				stock.Reservations = nil
+			}
+			entry.QuantityOnHand += stock.QuantityOnHand
+			entry.QuantityReserved += stock.QuantityReserved
+			entry.QuantityAvailable += max(stock.QuantityAvailable, 0)
+			entry.QuantityInTransit += stock.QuantityInTransit
+			entry.Warehouses = append(entry.Warehouses, stock)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// loadStockBatch reads the stock and active reservations of the missing items from the
+// database and caches them, including items with no stock anywhere
+func (r *InventoryRepository) loadStockBatch(ctx context.Context, tenantID string, items []models.StockBatchItem, missing map[string]bool, stockByKey map[string][]models.WarehouseStock) error {
+	productIDs := make([]uuid.UUID, 0, len(missing))
+	seen := make(map[uuid.UUID]bool, len(missing))
+	for _, item := range items {
+		if missing[generateStockBatchCacheKey(tenantID, item.ProductID, item.VariantID)] && !seen[item.ProductID] {
+			seen[item.ProductID] = true
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+
+	var stocks []models.StockLevel
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND product_id IN ?", tenantID, productIDs).
+		Order("warehouse_id ASC").
+		Find(&stocks).Error; err != nil {
+		return err
+	}
+	var reservations []models.InventoryReservation
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND product_id IN ? AND status = ?", tenantID, productIDs, "ACTIVE").
+		Order("expires_at ASC").
+		Find(&reservations).Error; err != nil {
+		return err
+	}
+
+	reservationsByStock := make(map[string][]models.StockReservation)
+	for _, reservation := range reservations {
+		key := stockItemKey(reservation.WarehouseID, reservation.ProductID, reservation.VariantID)
+		reservationsByStock[key] = append(reservationsByStock[key], models.StockReservation{
+			ID:        reservation.ID,
+			OrderID:   reservation.OrderID,
+			CartID:    reservation.CartID,
+			Quantity:  reservation.Quantity,
+			ExpiresAt: reservation.ExpiresAt,
+		})
+	}
+
+	for key := range missing {
+		stockByKey[key] = []models.WarehouseStock{}
+	}
+	for _, stock := range stocks {
+		key := generateStockBatchCacheKey(tenantID, stock.ProductID, stock.VariantID)
+		if !missing[key] {
+			continue
+		}
+		stockByKey[key] = append(stockByKey[key], models.WarehouseStock{
+			WarehouseID:       stock.WarehouseID,
+			QuantityOnHand:    stock.QuantityOnHand,
+			QuantityReserved:  stock.QuantityReserved,
+			QuantityAvailable: stock.QuantityAvailable,
+			QuantityInTransit: stock.QuantityInTransit,
+			Reservations:      reservationsByStock[stockItemKey(stock.WarehouseID, stock.ProductID, stock.VariantID)],
+		})
+	}
+
+	if r.redis != nil {
+		pipe := r.redis.Pipeline()
+		for key := range missing {
+			if data, err := json.Marshal(stockByKey[key]); err == nil {
+				pipe.Set(ctx, "tesseract:inventory:"+key, data, StockBatchCacheTTL)
+			}
+		}
+		_, _ = pipe.Exec(ctx)
+	}
+	return nil
+}
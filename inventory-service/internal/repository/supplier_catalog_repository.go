@@ -0,0 +1,332 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-service/internal/models"
+)
+
+var (
+	// ErrCatalogEntryNotFound is returned when a catalog entry doesn't exist for the supplier
+	ErrCatalogEntryNotFound = errors.New("supplier catalog entry not found")
+	// ErrCatalogSKUTaken is returned when the supplier already lists the SKU
+	ErrCatalogSKUTaken = errors.New("supplier already lists this SKU")
+)
+
+// ========== Supplier Catalog Operations ==========
+
+// CreateSupplierCatalogEntry lists a SKU in a supplier's catalog
+func (r *InventoryRepository) CreateSupplierCatalogEntry(ctx context.Context, tenantID string, entry *models.SupplierCatalogEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var supplier models.Supplier
+		if err := tx.Select("id").Where("tenant_id = ? AND id = ?", tenantID, entry.SupplierID).First(&supplier).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.SupplierCatalogEntry{}).
+			Where("supplier_id = ? AND sku = ?", entry.SupplierID, entry.SKU).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrCatalogSKUTaken
+		}
+
+		entry.TenantID = tenantID
+		return tx.Create(entry).Error
+	})
+}
+
+// GetSupplierCatalogEntry retrieves a supplier's catalog entry
+func (r *InventoryRepository) GetSupplierCatalogEntry(ctx context.Context, tenantID string, supplierID, id uuid.UUID) (*models.SupplierCatalogEntry, error) {
+	var entry models.SupplierCatalogEntry
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND supplier_id = ? AND id = ?", tenantID, supplierID, id).
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCatalogEntryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ListSupplierCatalog lists a supplier's catalog, optionally filtered by SKU prefix
+func (r *InventoryRepository) ListSupplierCatalog(ctx context.Context, tenantID string, supplierID uuid.UUID, skuPrefix string, page, limit int) ([]models.SupplierCatalogEntry, int64, error) {
+	var entries []models.SupplierCatalogEntry
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.SupplierCatalogEntry{}).
+		Where("tenant_id = ? AND supplier_id = ?", tenantID, supplierID)
+	if skuPrefix != "" {
+		query = query.Where("sku LIKE ?", skuPrefix+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if page > 0 && limit > 0 {
+		query = query.Offset((page - 1) * limit).Limit(limit)
+	}
+
+	err := query.Order("sku ASC").Find(&entries).Error
+	return entries, total, err
+}
+
+// UpdateSupplierCatalogEntry updates a supplier's catalog entry
+func (r *InventoryRepository) UpdateSupplierCatalogEntry(ctx context.Context, tenantID string, supplierID, id uuid.UUID, updates map[string]interface{}) (*models.SupplierCatalogEntry, error) {
+	if _, err := r.GetSupplierCatalogEntry(ctx, tenantID, supplierID, id); err != nil {
+		return nil, err
+	}
+	updates["updated_at"] = time.Now()
+	if err := r.db.WithContext(ctx).Model(&models.SupplierCatalogEntry{}).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return r.GetSupplierCatalogEntry(ctx, tenantID, supplierID, id)
+}
+
+// DeleteSupplierCatalogEntry removes a SKU from a supplier's catalog
+func (r *InventoryRepository) DeleteSupplierCatalogEntry(ctx context.Context, tenantID string, supplierID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND supplier_id = ? AND id = ?", tenantID, supplierID, id).
+		Delete(&models.SupplierCatalogEntry{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCatalogEntryNotFound
+	}
+	return nil
+}
+
+// CompareSuppliers ranks the active suppliers currently listing a SKU by what the
+// quantity would cost from each, optionally only in one currency
+func (r *InventoryRepository) CompareSuppliers(ctx context.Context, tenantID, sku string, quantity int, currencyCode string) (*models.SupplierComparison, error) {
+	now := time.Now()
+	var entries []models.SupplierCatalogEntry
+	query := r.db.WithContext(ctx).Preload("Supplier").
+		Where("tenant_id = ? AND sku = ? AND is_active = ?", tenantID, sku, true).
+		Where("valid_from IS NULL OR valid_from <= ?", now).
+		Where("valid_to IS NULL OR valid_to >= ?", now)
+	if currencyCode != "" {
+		query = query.Where("currency_code = ?", strings.ToUpper(currencyCode))
+	}
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	comparison := &models.SupplierComparison{
+		SKU:      sku,
+		Quantity: quantity,
+		Offers:   []models.SupplierOffer{},
+	}
+	for _, entry := range entries {
+		if entry.Supplier == nil || entry.Supplier.Status != models.SupplierStatusActive {
+			continue
+		}
+		orderQty := max(quantity, entry.MinOrderQuantity)
+		offer := models.SupplierOffer{
+			EntryID:          entry.ID,
+			SupplierID:       entry.SupplierID,
+			SupplierName:     entry.Supplier.Name,
+			SupplierSKU:      entry.SupplierSKU,
+			Rating:           entry.Supplier.Rating,
+			MinOrderQuantity: entry.MinOrderQuantity,
+			OrderQuantity:    orderQty,
+			UnitCost:         entry.UnitCost,
+			TotalCost:        roundCents(entry.UnitCost * float64(orderQty)),
+			CurrencyCode:     entry.CurrencyCode,
+			LeadTimeDays:     entry.LeadTimeDays,
+			IsPreferred:      entry.IsPreferred,
+		}
+		if offer.LeadTimeDays == nil {
+			offer.LeadTimeDays = entry.Supplier.LeadTimeDays
+		}
+		comparison.Offers = append(comparison.Offers, offer)
+	}
+
+	offers := comparison.Offers
+	sort.SliceStable(offers, func(i, j int) bool {
+		a, b := offers[i], offers[j]
+		if a.CurrencyCode != b.CurrencyCode {
+			return a.CurrencyCode < b.CurrencyCode
+		}
+		if a.TotalCost != b.TotalCost {
+			return a.TotalCost < b.TotalCost
+		}
+		if leadA, leadB := leadTimeOrMax(a.LeadTimeDays), leadTimeOrMax(b.LeadTimeDays); leadA != leadB {
+			return leadA < leadB
+		}
+		return a.IsPreferred && !b.IsPreferred
+	})
+	for i := range offers {
+		offers[i].Rank = 1
+		if i > 0 && offers[i-1].CurrencyCode == offers[i].CurrencyCode {
+			offers[i].Rank = offers[i-1].Rank + 1
+		}
+	}
+	return comparison, nil
+}
+
+func leadTimeOrMax(days *int) int {
+	if days == nil {
+		return math.MaxInt
+	}
+	return *days
+}
+
+// ========== Landed Cost ==========
+
+// receivedLine is a purchase order line received in one receipt
+type receivedLine struct {
+	item     models.PurchaseOrderItem
+	quantity int
+}
+
+// allocateLandedCosts spreads each landed cost component over the received lines by
+// value or quantity. Amounts are rounded to cents, with the rounding remainder going
+// to the last line so the shares add up to the component.
+func allocateLandedCosts(lines []receivedLine, components []models.LandedCostComponent) map[uuid.UUID]map[models.LandedCostType]float64 {
+	shares := make(map[uuid.UUID]map[models.LandedCostType]float64, len(lines))
+	for _, line := range lines {
+		shares[line.item.ID] = make(map[models.LandedCostType]float64)
+	}
+	if len(lines) == 0 {
+		return shares
+	}
+
+	for _, component := range components {
+		if component.Amount <= 0 {
+			continue
+		}
+		weights := make([]float64, len(lines))
+		var total float64
+		for i, line := range lines {
+			weights[i] = float64(line.quantity)
+			if component.Allocation != models.LandedCostAllocationQuantity {
+				weights[i] *= line.item.UnitCost
+			}
+			total += weights[i]
+		}
+		if total == 0 {
+			// Free goods carry no value to allocate by
+			for i, line := range lines {
+				weights[i] = float64(line.quantity)
+				total += weights[i]
+			}
+		}
+
+		allocated := 0.0
+		for i, line := range lines {
+			share := roundCents(component.Amount * weights[i] / total)
+			if i == len(lines)-1 {
+				share = roundCents(component.Amount - allocated)
+			}
+			allocated += share
+			shares[line.item.ID][component.Type] += share
+		}
+	}
+	return shares
+}
+
+// landedCostRecord builds the landed cost of a received line from its allocated shares
+func landedCostRecord(tenantID string, po *models.PurchaseOrder, line receivedLine, shares map[models.LandedCostType]float64, receivedAt time.Time) *models.PurchaseOrderLandedCost {
+	record := &models.PurchaseOrderLandedCost{
+		TenantID:        tenantID,
+		PurchaseOrderID: po.ID,
+		ItemID:          line.item.ID,
+		WarehouseID:     po.WarehouseID,
+		ProductID:       line.item.ProductID,
+		VariantID:       line.item.VariantID,
+		Quantity:        line.quantity,
+		UnitCost:        line.item.UnitCost,
+		Freight:         roundCents(shares[models.LandedCostTypeFreight]),
+		Duty:            roundCents(shares[models.LandedCostTypeDuty]),
+		Fees:            roundCents(shares[models.LandedCostTypeFee]),
+		CurrencyCode:    po.CurrencyCode,
+		ReceivedAt:      receivedAt,
+	}
+	record.TotalCost = roundCents(line.item.UnitCost*float64(line.quantity) + record.Freight + record.Duty + record.Fees)
+	record.LandedUnitCost = math.Round(record.TotalCost/float64(line.quantity)*10000) / 10000
+	return record
+}
+
+// applyUnitCostTx folds units received at a cost into a stock level's weighted average
+// cost. It runs after the units were added, so on-hand stock already includes them.
+func applyUnitCostTx(tx *gorm.DB, tenantID string, warehouseID, productID uuid.UUID, variantID *uuid.UUID, quantity int, unitCost float64) error {
+	return stockLevelQuery(tx, tenantID, warehouseID, productID, variantID).
+		Update("unit_cost", gorm.Expr(
+			"CASE WHEN quantity_on_hand > 0 THEN (GREATEST(quantity_on_hand - ?, 0) * unit_cost + ? * ?) / quantity_on_hand ELSE ? END",
+			quantity, quantity, unitCost, unitCost,
+		)).Error
+}
+
+// carryUnitCostTx values units moved between warehouses at the source's average cost
+func carryUnitCostTx(tx *gorm.DB, tenantID string, fromWarehouseID, toWarehouseID, productID uuid.UUID, variantID *uuid.UUID, quantity int) error {
+	var source models.StockLevel
+	err := stockLevelQuery(tx, tenantID, fromWarehouseID, productID, variantID).Select("unit_cost").First(&source).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && source.UnitCost == 0) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return applyUnitCostTx(tx, tenantID, toWarehouseID, productID, variantID, quantity, source.UnitCost)
+}
+
+// ListPurchaseOrderLandedCosts lists the landed costs recorded on a purchase order's receipts
+func (r *InventoryRepository) ListPurchaseOrderLandedCosts(ctx context.Context, tenantID string, poID uuid.UUID) ([]models.PurchaseOrderLandedCost, error) {
+	var costs []models.PurchaseOrderLandedCost
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND purchase_order_id = ?", tenantID, poID).
+		Order("received_at ASC").
+		Find(&costs).Error
+	return costs, err
+}
+
+// GetInventoryValuation values on-hand stock at weighted average landed cost, by warehouse
+func (r *InventoryRepository) GetInventoryValuation(ctx context.Context, tenantID string, warehouseID *uuid.UUID) (*models.InventoryValuation, error) {
+	valuation := &models.InventoryValuation{
+		Warehouses:  []models.WarehouseValuation{},
+		GeneratedAt: time.Now(),
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.StockLevel{}).
+		Select(`warehouse_id,
+			COUNT(*) AS items,
+			COALESCE(SUM(quantity_on_hand), 0) AS units,
+			COALESCE(SUM(quantity_on_hand * unit_cost), 0) AS value,
+			COUNT(*) FILTER (WHERE quantity_on_hand > 0 AND unit_cost = 0) AS uncosted`).
+		Where("tenant_id = ? AND quantity_on_hand > 0", tenantID).
+		Group("warehouse_id").
+		Order("value DESC")
+	if warehouseID != nil {
+		query = query.Where("warehouse_id = ?", *warehouseID)
+	}
+	if err := query.Scan(&valuation.Warehouses).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range valuation.Warehouses {
+		valuation.Warehouses[i].Value = roundCents(valuation.Warehouses[i].Value)
+		valuation.Units += valuation.Warehouses[i].Units
+		valuation.Value += valuation.Warehouses[i].Value
+	}
+	valuation.Value = roundCents(valuation.Value)
+	return valuation, nil
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
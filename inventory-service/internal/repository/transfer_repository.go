@@ -0,0 +1,467 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-service/internal/models"
+)
+
+var (
+	// ErrTransferNotFound is returned when a transfer doesn't exist for the tenant
+	ErrTransferNotFound = errors.New("inventory transfer not found")
+	// ErrTransferState is returned when a transfer's status doesn't allow the action
+	ErrTransferState = errors.New("inventory transfer status does not allow this action")
+	// ErrTransferQuantity is returned when a shipped or received quantity is out of range
+	ErrTransferQuantity = errors.New("invalid transfer quantity")
+	// ErrInsufficientTransferStock is returned when a warehouse can't cover a transfer line
+	ErrInsufficientTransferStock = errors.New("insufficient stock for transfer")
+	// ErrProposalNotFound is returned when an adjustment proposal doesn't exist for the tenant
+	ErrProposalNotFound = errors.New("adjustment proposal not found")
+	// ErrProposalResolved is returned when an adjustment proposal was already approved or rejected
+	ErrProposalResolved = errors.New("adjustment proposal already resolved")
+)
+
+// stockMove identifies a stock level touched by a transfer, for cache invalidation
+type stockMove struct {
+	warehouseID uuid.UUID
+	productID   uuid.UUID
+	variantID   *uuid.UUID
+}
+
+// lockTransferTx loads a transfer with its items, locking it for the rest of the transaction
+func lockTransferTx(tx *gorm.DB, tenantID string, id uuid.UUID) (*models.InventoryTransfer, error) {
+	var transfer models.InventoryTransfer
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		First(&transfer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Where("transfer_id = ?", transfer.ID).Find(&transfer.Items).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// stockLevelQuery scopes a query to one product or variant at a warehouse
+func stockLevelQuery(tx *gorm.DB, tenantID string, warehouseID, productID uuid.UUID, variantID *uuid.UUID) *gorm.DB {
+	query := tx.Model(&models.StockLevel{}).
+		Where("tenant_id = ? AND warehouse_id = ? AND product_id = ?", tenantID, warehouseID, productID)
+	if variantID != nil {
+		return query.Where("variant_id = ?", *variantID)
+	}
+	return query.Where("variant_id IS NULL")
+}
+
+// removeTransferStockTx removes stock for a transfer line, failing with
+// ErrInsufficientTransferStock if the warehouse doesn't hold enough
+func (r *InventoryRepository) removeTransferStockTx(tx *gorm.DB, tenantID string, warehouseID, productID uuid.UUID, variantID *uuid.UUID, quantity int) error {
+	var stock models.StockLevel
+	err := stockLevelQuery(tx, tenantID, warehouseID, productID, variantID).First(&stock).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && stock.QuantityOnHand < quantity) {
+		return fmt.Errorf("%w: product %s", ErrInsufficientTransferStock, productID)
+	}
+	if err != nil {
+		return err
+	}
+	return r.removeStockTx(tx, tenantID, warehouseID, productID, variantID, quantity)
+}
+
+// adjustInTransitTx changes the stock inbound to a warehouse on transfers. In-transit
+// units count towards neither on-hand nor available stock.
+func adjustInTransitTx(tx *gorm.DB, tenantID string, warehouseID, productID uuid.UUID, variantID *uuid.UUID, delta int) error {
+	result := stockLevelQuery(tx, tenantID, warehouseID, productID, variantID).
+		Updates(map[string]interface{}{
+			"quantity_in_transit": gorm.Expr("GREATEST(quantity_in_transit + ?, 0)", delta),
+			"updated_at":          time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 || delta <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	return tx.Create(&models.StockLevel{
+		TenantID:          tenantID,
+		WarehouseID:       warehouseID,
+		ProductID:         productID,
+		VariantID:         variantID,
+		QuantityInTransit: delta,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}).Error
+}
+
+func (r *InventoryRepository) invalidateStockMoves(ctx context.Context, tenantID string, moves []stockMove) {
+	for _, move := range moves {
+		r.invalidateStockCaches(ctx, tenantID, move.warehouseID, move.productID, move.variantID)
+	}
+}
+
+// ShipInventoryTransfer dispatches a pending transfer. Shipped units leave the source
+// warehouse and are held in transit against the destination until received. Items
+// missing from shipped are shipped at their requested quantity.
+func (r *InventoryRepository) ShipInventoryTransfer(ctx context.Context, tenantID string, id uuid.UUID, shipped map[uuid.UUID]int, shipment *models.TransferShipment, shippedBy *string) error {
+	var moves []stockMove
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		transfer, err := lockTransferTx(tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != models.InventoryTransferStatusPending {
+			return ErrTransferState
+		}
+
+		for _, item := range transfer.Items {
+			qty := item.QuantityRequested
+			if q, ok := shipped[item.ID]; ok {
+				qty = q
+			}
+			if qty < 0 || qty > item.QuantityRequested {
+				return fmt.Errorf("%w: item %s ships %d of %d requested", ErrTransferQuantity, item.ID, qty, item.QuantityRequested)
+			}
+
+			if qty > 0 {
+				if err := r.removeTransferStockTx(tx, tenantID, transfer.FromWarehouseID, item.ProductID, item.VariantID, qty); err != nil {
+					return err
+				}
+				if err := adjustInTransitTx(tx, tenantID, transfer.ToWarehouseID, item.ProductID, item.VariantID, qty); err != nil {
+					return err
+				}
+				moves = append(moves,
+					stockMove{transfer.FromWarehouseID, item.ProductID, item.VariantID},
+					stockMove{transfer.ToWarehouseID, item.ProductID, item.VariantID})
+			}
+
+			if err := tx.Model(&models.InventoryTransferItem{}).
+				Where("id = ?", item.ID).
+				Updates(map[string]interface{}{
+					"quantity_shipped": qty,
+					"updated_at":       time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"status":     models.InventoryTransferStatusInTransit,
+			"shipped_at": &now,
+			"shipped_by": shippedBy,
+			"updated_at": now,
+		}
+		if shipment != nil {
+			updates["shipment_id"] = shipment.ShipmentID
+			updates["carrier"] = shipment.Carrier
+			updates["tracking_number"] = shipment.TrackingNumber
+			updates["tracking_url"] = shipment.TrackingURL
+			updates["shipment_status"] = shipment.Status
+		}
+		return tx.Model(&models.InventoryTransfer{}).Where("id = ?", transfer.ID).Updates(updates).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	r.invalidateStockMoves(ctx, tenantID, moves)
+	return nil
+}
+
+// ReceiveInventoryTransfer receives an in-transit transfer at its destination and
+// completes it. Received units go on hand at the destination; items missing from
+// received arrive as shipped. Each item whose count differs from what was shipped is
+// recorded with its discrepancy, an adjustment proposal against the source warehouse
+// and a TRANSFER_DISCREPANCY alert. Items counted with a bin are put away in it; bins
+// are suggested for the rest.
+func (r *InventoryRepository) ReceiveInventoryTransfer(ctx context.Context, tenantID string, id uuid.UUID, received []models.ReceiveTransferItemRequest, receivedBy *string) (*models.ReceiveTransferResult, error) {
+	counts := make(map[uuid.UUID]models.ReceiveTransferItemRequest, len(received))
+	for _, item := range received {
+		counts[item.ItemID] = item
+	}
+
+	result := &models.ReceiveTransferResult{
+		Proposals: []models.InventoryAdjustmentProposal{},
+		Alerts:    []models.InventoryAlert{},
+		Putaway:   []models.Putaway{},
+	}
+	var moves []stockMove
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		transfer, err := lockTransferTx(tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != models.InventoryTransferStatusInTransit {
+			return ErrTransferState
+		}
+		var planner *putawayPlanner
+
+		var destination models.Warehouse
+		tx.Select("name").Where("id = ?", transfer.ToWarehouseID).First(&destination)
+
+		now := time.Now()
+		for _, item := range transfer.Items {
+			qty := item.QuantityShipped
+			var reason *string
+			var locationID *uuid.UUID
+			if count, ok := counts[item.ID]; ok {
+				qty = count.QuantityReceived
+				reason = count.Reason
+				locationID = count.LocationID
+			}
+			if qty < 0 {
+				return fmt.Errorf("%w: item %s", ErrTransferQuantity, item.ID)
+			}
+
+			if item.QuantityShipped > 0 {
+				if err := adjustInTransitTx(tx, tenantID, transfer.ToWarehouseID, item.ProductID, item.VariantID, -item.QuantityShipped); err != nil {
+					return err
+				}
+			}
+			if qty > 0 {
+				if err := r.addStockTx(tx, tenantID, transfer.ToWarehouseID, item.ProductID, item.VariantID, qty); err != nil {
+					return err
+				}
+				if err := carryUnitCostTx(tx, tenantID, transfer.FromWarehouseID, transfer.ToWarehouseID, item.ProductID, item.VariantID, qty); err != nil {
+					return err
+				}
+				putaway, err := r.putawayTx(tx, tenantID, transfer.ToWarehouseID, &planner, item.ID, item.ProductID, item.VariantID, qty, locationID)
+				if err != nil {
+					return err
+				}
+				result.Putaway = append(result.Putaway, *putaway)
+			}
+			moves = append(moves, stockMove{transfer.ToWarehouseID, item.ProductID, item.VariantID})
+
+			discrepancy := qty - item.QuantityShipped
+			if err := tx.Model(&models.InventoryTransferItem{}).
+				Where("id = ?", item.ID).
+				Updates(map[string]interface{}{
+					"quantity_received":    qty,
+					"quantity_discrepancy": discrepancy,
+					"discrepancy_reason":   reason,
+					"updated_at":           now,
+				}).Error; err != nil {
+				return err
+			}
+			if discrepancy == 0 {
+				continue
+			}
+
+			proposal, alert := discrepancyRecords(transfer, &item, qty, destination.Name)
+			if err := tx.Create(proposal).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(alert).Error; err != nil {
+				return err
+			}
+			result.Proposals = append(result.Proposals, *proposal)
+			result.Alerts = append(result.Alerts, *alert)
+		}
+
+		return tx.Model(&models.InventoryTransfer{}).
+			Where("id = ?", transfer.ID).
+			Updates(map[string]interface{}{
+				"status":          models.InventoryTransferStatusCompleted,
+				"received_at":     &now,
+				"received_by":     receivedBy,
+				"completed_at":    &now,
+				"completed_by":    receivedBy,
+				"has_discrepancy": len(result.Proposals) > 0,
+				"updated_at":      now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.invalidateStockMoves(ctx, tenantID, moves)
+	result.Transfer, err = r.GetInventoryTransferByID(tenantID, id)
+	return result, err
+}
+
+// discrepancyRecords builds the adjustment proposal and alert for a transfer item
+// received with a different count than was shipped
+func discrepancyRecords(transfer *models.InventoryTransfer, item *models.InventoryTransferItem, received int, destinationName string) (*models.InventoryAdjustmentProposal, *models.InventoryAlert) {
+	now := time.Now()
+	discrepancy := received - item.QuantityShipped
+
+	proposal := &models.InventoryAdjustmentProposal{
+		TenantID:       transfer.TenantID,
+		Source:         models.AdjustmentSourceTransfer,
+		TransferID:     &transfer.ID,
+		TransferItemID: &item.ID,
+		TransferNumber: transfer.TransferNumber,
+		WarehouseID:    transfer.FromWarehouseID,
+		ProductID:      item.ProductID,
+		VariantID:      item.VariantID,
+		Status:         models.AdjustmentProposalStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	alert := &models.InventoryAlert{
+		TenantID:      transfer.TenantID,
+		WarehouseID:   &transfer.ToWarehouseID,
+		ProductID:     item.ProductID,
+		VariantID:     item.VariantID,
+		Type:          models.AlertTypeTransferDiscrepancy,
+		Status:        models.AlertStatusActive,
+		Title:         "Transfer Discrepancy",
+		CurrentQty:    received,
+		ThresholdQty:  item.QuantityShipped,
+		WarehouseName: stringPtr(destinationName),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if discrepancy < 0 {
+		// The missing units either never left the source or were lost on the way
+		proposal.Reason = models.AdjustmentReasonShortReceived
+		proposal.QuantityChange = -discrepancy
+		proposal.Message = fmt.Sprintf("Transfer %s: %d shipped, %d received. Approve to return %d to the source warehouse if they were never shipped; reject to write them off as lost in transit.",
+			transfer.TransferNumber, item.QuantityShipped, received, -discrepancy)
+		alert.Priority = models.AlertPriorityHigh
+		alert.Message = fmt.Sprintf("Transfer %s arrived %d short: %d shipped, %d received", transfer.TransferNumber, -discrepancy, item.QuantityShipped, received)
+	} else {
+		// The source sent more than it recorded, so its stock is overstated
+		proposal.Reason = models.AdjustmentReasonOverReceived
+		proposal.QuantityChange = -discrepancy
+		proposal.Message = fmt.Sprintf("Transfer %s: %d shipped, %d received. Approve to deduct the extra %d from the source warehouse.",
+			transfer.TransferNumber, item.QuantityShipped, received, discrepancy)
+		alert.Priority = models.AlertPriorityMedium
+		alert.Message = fmt.Sprintf("Transfer %s arrived %d over: %d shipped, %d received", transfer.TransferNumber, discrepancy, item.QuantityShipped, received)
+	}
+	return proposal, alert
+}
+
+// CancelInventoryTransfer cancels a pending or in-transit transfer. Stock in transit goes
+// back to the source warehouse.
+func (r *InventoryRepository) CancelInventoryTransfer(ctx context.Context, tenantID string, id uuid.UUID) error {
+	var moves []stockMove
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		transfer, err := lockTransferTx(tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != models.InventoryTransferStatusPending && transfer.Status != models.InventoryTransferStatusInTransit {
+			return ErrTransferState
+		}
+
+		if transfer.Status == models.InventoryTransferStatusInTransit {
+			for _, item := range transfer.Items {
+				if item.QuantityShipped <= 0 {
+					continue
+				}
+				if err := adjustInTransitTx(tx, tenantID, transfer.ToWarehouseID, item.ProductID, item.VariantID, -item.QuantityShipped); err != nil {
+					return err
+				}
+				if err := r.addStockTx(tx, tenantID, transfer.FromWarehouseID, item.ProductID, item.VariantID, item.QuantityShipped); err != nil {
+					return err
+				}
+				moves = append(moves,
+					stockMove{transfer.FromWarehouseID, item.ProductID, item.VariantID},
+					stockMove{transfer.ToWarehouseID, item.ProductID, item.VariantID})
+			}
+		}
+
+		return tx.Model(&models.InventoryTransfer{}).
+			Where("id = ?", transfer.ID).
+			Updates(map[string]interface{}{
+				"status":     models.InventoryTransferStatusCancelled,
+				"updated_at": time.Now(),
+			}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	r.invalidateStockMoves(ctx, tenantID, moves)
+	return nil
+}
+
+// UpdateTransferShipmentStatus records the latest carrier status of a transfer's shipment
+func (r *InventoryRepository) UpdateTransferShipmentStatus(ctx context.Context, tenantID string, id uuid.UUID, status string) error {
+	return r.db.WithContext(ctx).Model(&models.InventoryTransfer{}).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		Updates(map[string]interface{}{
+			"shipment_status": status,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+// ListInTransitStock lists the transfer lines currently in transit, optionally to or from
+// one warehouse
+func (r *InventoryRepository) ListInTransitStock(ctx context.Context, tenantID string, warehouseID *uuid.UUID) ([]models.InTransitStock, error) {
+	stock := []models.InTransitStock{}
+	query := r.db.WithContext(ctx).
+		Table("inventory_transfer_items AS i").
+		Select(`t.id AS transfer_id, t.transfer_number, t.from_warehouse_id, t.to_warehouse_id,
+			i.product_id, i.variant_id, i.quantity_shipped AS quantity, t.shipped_at, t.carrier, t.tracking_number`).
+		Joins("JOIN inventory_transfers t ON t.id = i.transfer_id").
+		Where("t.tenant_id = ? AND t.status = ? AND t.deleted_at IS NULL AND i.quantity_shipped > 0",
+			tenantID, models.InventoryTransferStatusInTransit)
+	if warehouseID != nil {
+		query = query.Where("t.from_warehouse_id = ? OR t.to_warehouse_id = ?", *warehouseID, *warehouseID)
+	}
+
+	err := query.Order("t.shipped_at ASC").Scan(&stock).Error
+	return stock, err
+}
+
+// ========== Adjustment Proposal Operations ==========
+
+// AdjustmentProposalFilters narrows a list of adjustment proposals
+type AdjustmentProposalFilters struct {
+	Status      *models.AdjustmentProposalStatus
+	Source      *models.AdjustmentSource
+	TransferID  *uuid.UUID
+	WarehouseID *uuid.UUID
+}
+
+// ListAdjustmentProposals retrieves adjustment proposals with pagination
+func (r *InventoryRepository) ListAdjustmentProposals(ctx context.Context, tenantID string, filters AdjustmentProposalFilters, page, limit int) ([]models.InventoryAdjustmentProposal, int64, error) {
+	var proposals []models.InventoryAdjustmentProposal
+	var total int64
+	query := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
+
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	if filters.Source != nil {
+		query = query.Where("source = ?", *filters.Source)
+	}
+	if filters.TransferID != nil {
+		query = query.Where("transfer_id = ?", *filters.TransferID)
+	}
+	if filters.WarehouseID != nil {
+		query = query.Where("warehouse_id = ?", *filters.WarehouseID)
+	}
+
+	if err := query.Model(&models.InventoryAdjustmentProposal{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if page > 0 && limit > 0 {
+		query = query.Offset((page - 1) * limit).Limit(limit)
+	}
+
+	err := query.Order("created_at DESC").Find(&proposals).Error
+	return proposals, total, err
+}
+
+// ResolveAdjustmentProposal approves or rejects a pending adjustment proposal. Approving
+// applies the quantity change to the proposal's warehouse. Proposals waiting on an
+// approval-service request can only be resolved by its decision.
+func (r *InventoryRepository) ResolveAdjustmentProposal(ctx context.Context, tenantID string, id uuid.UUID, approve bool, resolvedBy, notes *string) (*models.InventoryAdjustmentProposal, error) {
+	return r.resolveAdjustmentProposal(ctx, tenantID, "id = ?", id, false, approve, resolvedBy, notes)
+}
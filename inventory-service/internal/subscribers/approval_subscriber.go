@@ -0,0 +1,131 @@
+package subscribers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/sirupsen/logrus"
+	"inventory-service/internal/clients"
+	"inventory-service/internal/eventconsumer"
+	"inventory-service/internal/events"
+	"inventory-service/internal/repository"
+)
+
+// ApprovalSubscriber applies or closes stock adjustments when approval-service decides
+// their approval requests
+type ApprovalSubscriber struct {
+	consumer       *eventconsumer.Consumer
+	repo           *repository.InventoryRepository
+	eventPublisher *events.InventoryEventPublisher
+	logger         *logrus.Entry
+}
+
+// NewApprovalSubscriber creates a new approval event subscriber for stock adjustments
+func NewApprovalSubscriber(
+	repo *repository.InventoryRepository,
+	eventPublisher *events.InventoryEventPublisher,
+	logger *logrus.Logger,
+) (*ApprovalSubscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	consumer, err := eventconsumer.New(eventconsumer.Config{
+		NATSURL:    natsURL,
+		ClientName: "inventory-service-approval-subscriber",
+		Durable:    "inventory-service-approvals",
+		Stream:     gosharedevents.StreamApprovals,
+		MaxDeliver: 3,
+		AckWait:    30 * time.Second,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ApprovalSubscriber{
+		consumer:       consumer,
+		repo:           repo,
+		eventPublisher: eventPublisher,
+		logger:         logger.WithField("component", "approval-subscriber"),
+	}, nil
+}
+
+// Start starts listening for approval decisions
+func (s *ApprovalSubscriber) Start(ctx context.Context) error {
+	subjects := []string{
+		gosharedevents.ApprovalGranted,
+		gosharedevents.ApprovalRejected,
+		gosharedevents.ApprovalCancelled,
+		gosharedevents.ApprovalExpired,
+	}
+
+	s.logger.Info("Starting stock adjustment approval event subscription...")
+
+	err := s.consumer.Start(ctx, subjects, eventconsumer.JSON(s.handleApprovalEvent))
+	if err != nil {
+		return err
+	}
+
+	s.logger.WithField("subjects", subjects).Info("Stock adjustment approval subscriber started successfully")
+	return nil
+}
+
+// handleApprovalEvent applies the adjustment behind an approved request, and rejects it
+// for any other decision
+func (s *ApprovalSubscriber) handleApprovalEvent(ctx context.Context, event *gosharedevents.ApprovalEvent) error {
+	if event.ResourceType != clients.AdjustmentApprovalResourceType {
+		return nil
+	}
+
+	logger := s.logger.WithFields(logrus.Fields{
+		"event_type":  event.EventType,
+		"approval_id": event.ApprovalRequestID,
+		"proposal_id": event.ResourceID,
+		"status":      event.Status,
+	})
+
+	approve := event.Status == "approved"
+	var resolvedBy, notes *string
+	if event.ApproverID != "" {
+		resolvedBy = &event.ApproverID
+	}
+	switch {
+	case event.DecisionNotes != "":
+		notes = &event.DecisionNotes
+	case event.DecisionReason != "":
+		notes = &event.DecisionReason
+	case !approve:
+		reason := "Approval request " + event.Status
+		notes = &reason
+	}
+
+	proposal, err := s.repo.ResolveAdjustmentApproval(ctx, event.TenantID, event.ApprovalRequestID, approve, resolvedBy, notes)
+	if errors.Is(err, repository.ErrProposalNotFound) || errors.Is(err, repository.ErrProposalResolved) {
+		// Redelivered, or the proposal was resolved in inventory-service when the approval
+		// request couldn't be linked to it
+		logger.Info("Stock adjustment already resolved, skipping")
+		return nil
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to resolve stock adjustment")
+		return err
+	}
+
+	if approve && s.eventPublisher != nil {
+		_ = s.eventPublisher.PublishAdjustmentApplied(ctx, proposal)
+	}
+	logger.WithField("new_status", proposal.Status).Info("Stock adjustment resolved by approval decision")
+	return nil
+}
+
+// Stop stops the approval subscriber
+func (s *ApprovalSubscriber) Stop() {
+	if s.consumer != nil {
+		s.consumer.Stop()
+	}
+	s.logger.Info("Approval subscriber stopped")
+}
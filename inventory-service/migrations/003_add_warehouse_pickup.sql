@@ -0,0 +1,7 @@
+-- Migration: Click-and-collect pickup locations
+-- Warehouses and stores with pickup enabled are listed by the storefront availability endpoint.
+
+ALTER TABLE warehouses ADD COLUMN IF NOT EXISTS pickup_enabled BOOLEAN DEFAULT FALSE;
+ALTER TABLE warehouses ADD COLUMN IF NOT EXISTS pickup_instructions TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_warehouses_pickup ON warehouses(tenant_id) WHERE pickup_enabled = TRUE AND deleted_at IS NULL;
@@ -0,0 +1,45 @@
+-- Migration: Inventory transfer shipments, in-transit stock and receiving discrepancies
+-- Shipped transfer stock leaves the source warehouse and is held in transit against the
+-- destination until received. Receiving differences raise adjustment proposals.
+
+ALTER TABLE inventory_transfers ADD COLUMN IF NOT EXISTS shipment_id VARCHAR(255);
+ALTER TABLE inventory_transfers ADD COLUMN IF NOT EXISTS carrier VARCHAR(50);
+ALTER TABLE inventory_transfers ADD COLUMN IF NOT EXISTS tracking_number VARCHAR(255);
+ALTER TABLE inventory_transfers ADD COLUMN IF NOT EXISTS tracking_url VARCHAR(500);
+ALTER TABLE inventory_transfers ADD COLUMN IF NOT EXISTS shipment_status VARCHAR(50);
+ALTER TABLE inventory_transfers ADD COLUMN IF NOT EXISTS shipped_by TEXT;
+ALTER TABLE inventory_transfers ADD COLUMN IF NOT EXISTS received_by TEXT;
+ALTER TABLE inventory_transfers ADD COLUMN IF NOT EXISTS received_at TIMESTAMPTZ;
+ALTER TABLE inventory_transfers ADD COLUMN IF NOT EXISTS has_discrepancy BOOLEAN DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_inventory_transfers_shipment_id ON inventory_transfers(shipment_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_transfers_tracking_number ON inventory_transfers(tracking_number);
+
+ALTER TABLE inventory_transfer_items ADD COLUMN IF NOT EXISTS quantity_discrepancy INTEGER DEFAULT 0;
+ALTER TABLE inventory_transfer_items ADD COLUMN IF NOT EXISTS discrepancy_reason TEXT;
+
+ALTER TABLE stock_levels ADD COLUMN IF NOT EXISTS quantity_in_transit INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS inventory_adjustment_proposals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    transfer_id UUID NOT NULL,
+    transfer_item_id UUID NOT NULL,
+    transfer_number VARCHAR(50),
+    warehouse_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    variant_id UUID,
+    reason VARCHAR(30) NOT NULL,
+    quantity_change INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    message TEXT,
+    resolved_by TEXT,
+    resolved_at TIMESTAMPTZ,
+    resolution_notes TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_adjustment_proposals_tenant_id ON inventory_adjustment_proposals(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_adjustment_proposals_transfer_id ON inventory_adjustment_proposals(transfer_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_adjustment_proposals_status ON inventory_adjustment_proposals(status);
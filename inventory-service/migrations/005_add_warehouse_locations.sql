@@ -0,0 +1,39 @@
+-- Migration: Warehouse bin locations and stock by bin
+-- Locations nest zone -> aisle -> shelf -> bin. Bin stock adds up to at most a
+-- warehouse's on-hand stock; the rest is unassigned.
+
+CREATE TABLE IF NOT EXISTS warehouse_locations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    warehouse_id UUID NOT NULL,
+    parent_id UUID,
+    type VARCHAR(20) NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    full_code VARCHAR(255) NOT NULL,
+    name VARCHAR(255),
+    pick_sequence INTEGER DEFAULT 0,
+    capacity BIGINT,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_warehouse_locations_tenant_id ON warehouse_locations(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_warehouse_locations_parent_id ON warehouse_locations(parent_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_warehouse_location_code ON warehouse_locations(warehouse_id, full_code);
+
+CREATE TABLE IF NOT EXISTS bin_stock (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    warehouse_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    variant_id UUID,
+    location_id UUID NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_bin_stock_tenant_id ON bin_stock(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_bin_stock_location_id ON bin_stock(location_id);
+CREATE INDEX IF NOT EXISTS idx_bin_stock_item ON bin_stock(warehouse_id, product_id, variant_id);
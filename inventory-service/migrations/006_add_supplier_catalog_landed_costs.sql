@@ -0,0 +1,55 @@
+-- Migration: Supplier price lists, landed costs and inventory valuation
+-- Received purchase order units are valued at their price plus their share of the
+-- receipt's freight, duty and fees; stock levels keep a weighted average of that cost.
+
+CREATE TABLE IF NOT EXISTS supplier_catalog_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    supplier_id UUID NOT NULL,
+    sku VARCHAR(100) NOT NULL,
+    product_id UUID,
+    variant_id UUID,
+    supplier_sku VARCHAR(100),
+    min_order_quantity INTEGER NOT NULL DEFAULT 1,
+    unit_cost DECIMAL(12,4) NOT NULL,
+    currency_code VARCHAR(3) NOT NULL DEFAULT 'USD',
+    lead_time_days BIGINT,
+    valid_from TIMESTAMPTZ,
+    valid_to TIMESTAMPTZ,
+    is_preferred BOOLEAN DEFAULT FALSE,
+    is_active BOOLEAN DEFAULT TRUE,
+    notes TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_catalog_entries_tenant_id ON supplier_catalog_entries(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_catalog_entries_sku ON supplier_catalog_entries(sku);
+CREATE INDEX IF NOT EXISTS idx_supplier_catalog_entries_product_id ON supplier_catalog_entries(product_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_supplier_catalog_sku ON supplier_catalog_entries(supplier_id, sku);
+
+CREATE TABLE IF NOT EXISTS purchase_order_landed_costs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    purchase_order_id UUID NOT NULL,
+    item_id UUID NOT NULL,
+    warehouse_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    variant_id UUID,
+    quantity INTEGER NOT NULL,
+    unit_cost DECIMAL(12,4) NOT NULL,
+    freight DECIMAL(12,2) NOT NULL DEFAULT 0,
+    duty DECIMAL(12,2) NOT NULL DEFAULT 0,
+    fees DECIMAL(12,2) NOT NULL DEFAULT 0,
+    landed_unit_cost DECIMAL(12,4) NOT NULL,
+    total_cost DECIMAL(12,2) NOT NULL,
+    currency_code VARCHAR(3) NOT NULL,
+    received_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_landed_costs_tenant_id ON purchase_order_landed_costs(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_landed_costs_purchase_order_id ON purchase_order_landed_costs(purchase_order_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_landed_costs_product_id ON purchase_order_landed_costs(product_id);
+
+ALTER TABLE stock_levels ADD COLUMN IF NOT EXISTS unit_cost DECIMAL(12,4) NOT NULL DEFAULT 0;
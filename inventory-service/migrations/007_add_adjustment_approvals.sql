@@ -0,0 +1,37 @@
+-- Migration: Approval workflow for manual stock adjustments
+-- Adjustments above a tenant's quantity or value threshold are kept as pending adjustment
+-- proposals until approval-service decides them; every manual adjustment records the
+-- requested and applied quantities.
+
+ALTER TABLE inventory_adjustment_proposals ALTER COLUMN transfer_id DROP NOT NULL;
+ALTER TABLE inventory_adjustment_proposals ALTER COLUMN transfer_item_id DROP NOT NULL;
+
+ALTER TABLE inventory_adjustment_proposals ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'TRANSFER';
+ALTER TABLE inventory_adjustment_proposals ADD COLUMN IF NOT EXISTS applied_quantity BIGINT;
+ALTER TABLE inventory_adjustment_proposals ADD COLUMN IF NOT EXISTS unit_cost DECIMAL(12,4) NOT NULL DEFAULT 0;
+ALTER TABLE inventory_adjustment_proposals ADD COLUMN IF NOT EXISTS value DECIMAL(14,2) NOT NULL DEFAULT 0;
+ALTER TABLE inventory_adjustment_proposals ADD COLUMN IF NOT EXISTS requires_approval BOOLEAN DEFAULT FALSE;
+ALTER TABLE inventory_adjustment_proposals ADD COLUMN IF NOT EXISTS approval_request_id VARCHAR(255);
+ALTER TABLE inventory_adjustment_proposals ADD COLUMN IF NOT EXISTS requested_by TEXT;
+ALTER TABLE inventory_adjustment_proposals ADD COLUMN IF NOT EXISTS previous_on_hand BIGINT;
+ALTER TABLE inventory_adjustment_proposals ADD COLUMN IF NOT EXISTS new_on_hand BIGINT;
+
+-- Approved transfer proposals applied their full quantity
+UPDATE inventory_adjustment_proposals SET applied_quantity = quantity_change
+WHERE status = 'APPROVED' AND applied_quantity IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_inventory_adjustment_proposals_source ON inventory_adjustment_proposals(source);
+CREATE INDEX IF NOT EXISTS idx_inventory_adjustment_proposals_approval_request_id ON inventory_adjustment_proposals(approval_request_id);
+
+CREATE TABLE IF NOT EXISTS inventory_adjustment_settings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    quantity_threshold BIGINT NOT NULL,
+    value_threshold DECIMAL(14,2) NOT NULL,
+    updated_by TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inventory_adjustment_settings_tenant_id ON inventory_adjustment_settings(tenant_id);
@@ -0,0 +1,12 @@
+-- Migration: Reservations API
+-- Lines reserved together share a reservation_id, and a reservation can hold stock for a cart
+-- instead of an order. EXPIRED reservations were released by the expiry job once their TTL
+-- elapsed.
+
+ALTER TABLE inventory_reservations ADD COLUMN IF NOT EXISTS reservation_id UUID;
+ALTER TABLE inventory_reservations ADD COLUMN IF NOT EXISTS cart_id UUID;
+ALTER TABLE inventory_reservations ALTER COLUMN order_id DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_inventory_reservations_reservation_id ON inventory_reservations(reservation_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_reservations_cart_id ON inventory_reservations(cart_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_reservations_active_expiry ON inventory_reservations(expires_at) WHERE status = 'ACTIVE';
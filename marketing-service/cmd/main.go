@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,13 +13,15 @@ import (
 	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 	"marketing-service/internal/config"
+	"marketing-service/internal/database"
 	marketingevents "marketing-service/internal/events"
 	"marketing-service/internal/handlers"
 	"marketing-service/internal/middleware"
-	"marketing-service/internal/models"
 	"marketing-service/internal/repository"
 	"marketing-service/internal/services"
+	"marketing-service/internal/subscribers"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"github.com/Tesseract-Nexus/go-shared/rbac"
@@ -57,29 +62,25 @@ func main() {
 	// Initialize configuration
 	cfg := config.Load()
 
-	// Initialize database
-	db, err := config.InitDB(cfg)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return config.InitDB(cfg) })
 	if err != nil {
 		logger.Fatal("Failed to connect to database:", err)
 	}
 
-	// Run database migrations to create tables if they don't exist
-	logger.Info("Running database migrations...")
-	if err := db.AutoMigrate(
-		&models.Campaign{},
-		&models.CustomerSegment{},
-		&models.AbandonedCart{},
-		&models.LoyaltyProgram{},
-		&models.CustomerLoyalty{},
-		&models.LoyaltyTransaction{},
-		&models.Referral{},
-		&models.CouponCode{},
-		&models.CouponUsage{},
-		&models.CampaignRecipient{},
-	); err != nil {
-		logger.Fatalf("Failed to run migrations: %v", err)
+	// "marketing-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			logger.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		logger.Fatalf("Database schema is not ready: %v", err)
 	}
-	logger.Info("Database migrations completed")
 
 	// Initialize repository and service
 	marketingRepo := repository.NewMarketingRepository(db, logger)
@@ -106,6 +107,24 @@ func main() {
 	}
 	marketingService.SetEmailDefaults(fromEmail, fromName)
 
+	// Open/click tracking for sent campaigns
+	if tracking := services.NewTrackingSigner(cfg.TrackingBaseURL, cfg.TrackingSecret); tracking != nil {
+		marketingService.SetTracking(tracking)
+		logger.Info("Email open/click tracking enabled")
+	} else {
+		logger.Info("Email open/click tracking disabled (TRACKING_BASE_URL or TRACKING_SECRET not set)")
+	}
+	marketingService.SetAttributionWindow(time.Duration(cfg.AttributionWindowDays) * 24 * time.Hour)
+
+	// Segment exports to Mautic, Meta custom audiences and Google Customer Match
+	metaAudiences := services.NewMetaAudienceClient(cfg)
+	googleAds := services.NewGoogleAdsClient(cfg)
+	marketingService.SetAudienceExports(services.NewCustomersClient(cfg.CustomersServiceURL), metaAudiences, googleAds)
+	logger.WithFields(logrus.Fields{
+		"meta":   metaAudiences.IsEnabled(),
+		"google": googleAds.IsEnabled(),
+	}).Info("Segment export platforms configured")
+
 	// Initialize NATS events publisher
 	eventsPublisher, err := marketingevents.NewPublisher(logger)
 	if err != nil {
@@ -118,6 +137,7 @@ func main() {
 	// Initialize handlers
 	marketingHandlers := handlers.NewMarketingHandlers(marketingService, eventsPublisher, logger)
 	mauticHandlers := handlers.NewMauticHandlers(mauticClient, marketingService, logger)
+	marketingHandlers.SetEmailWebhookSecret(cfg.EmailWebhookSecret)
 
 	// Initialize RBAC middleware
 	staffServiceURL := os.Getenv("STAFF_SERVICE_URL")
@@ -145,6 +165,15 @@ func main() {
 	router.GET("/health", handlers.HealthCheck)
 	router.GET("/ready", handlers.HealthCheck)
 
+	// Email provider webhooks (HMAC-signed, no auth) - bounces, complaints and unsubscribes
+	router.POST("/webhooks/email/:tenant_id", marketingHandlers.EmailProviderWebhook)
+	// Provider event webhooks (Mautic signed; SES and SendGrid by URL token) - delivery and engagement
+	router.POST("/webhooks/email/:tenant_id/:provider", marketingHandlers.ProviderWebhook)
+
+	// Open pixel and click redirect (signed tokens, no auth)
+	router.GET("/t/o/:token", marketingHandlers.TrackOpen)
+	router.GET("/t/c/:token", marketingHandlers.TrackClick)
+
 	// Public storefront API routes (no auth required) - for customer-facing operations
 	storefrontAPI := router.Group("/api/v1/storefront")
 	storefrontAPI.Use(middleware.TenantMiddleware())
@@ -190,6 +219,35 @@ func main() {
 			campaigns.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsManage), marketingHandlers.DeleteCampaign)
 			campaigns.POST("/:id/send", rbacMiddleware.RequirePermission(rbac.PermissionMarketingEmailSend), marketingHandlers.SendCampaign)
 			campaigns.POST("/:id/schedule", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsManage), marketingHandlers.ScheduleCampaign)
+			campaigns.GET("/:id/suppression-stats", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsView), marketingHandlers.GetCampaignSuppressionStats)
+			campaigns.GET("/:id/deliverability", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsView), marketingHandlers.GetCampaignDeliverability)
+			campaigns.GET("/:id/recipients", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsView), marketingHandlers.ListCampaignRecipients)
+			campaigns.GET("/:id/attribution", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsView), marketingHandlers.GetCampaignAttribution)
+			campaigns.GET("/:id/conversions", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsView), marketingHandlers.ListCampaignConversions)
+		}
+
+		// Suppression list (hard bounces, spam complaints, unsubscribes) - enforced before every send
+		suppressions := v1.Group("/suppressions")
+		{
+			suppressions.POST("", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsManage), marketingHandlers.AddSuppression)
+			suppressions.GET("", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsView), marketingHandlers.ListSuppressions)
+			suppressions.GET("/stats", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsView), marketingHandlers.GetSuppressionStats)
+			suppressions.GET("/export", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsView), marketingHandlers.ExportSuppressions)
+			suppressions.POST("/import", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsManage), marketingHandlers.ImportSuppressions)
+			suppressions.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsManage), marketingHandlers.DeleteSuppression)
+		}
+
+		// Deliverability reports and provider webhook setup
+		deliverability := v1.Group("/deliverability")
+		{
+			deliverability.GET("/report", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsView), marketingHandlers.GetDeliverabilityReport)
+			deliverability.GET("/webhooks", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsManage), marketingHandlers.GetProviderWebhookPaths)
+		}
+
+		// Revenue attribution from order events carrying storefront UTM touches
+		attribution := v1.Group("/attribution")
+		{
+			attribution.GET("/report", rbacMiddleware.RequirePermission(rbac.PermissionMarketingCampaignsView), marketingHandlers.GetAttributionReport)
 		}
 
 		// Segments with RBAC - uses marketing:segments:view and marketing:segments:manage
@@ -202,6 +260,18 @@ func main() {
 			segments.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionMarketingSegmentsManage), marketingHandlers.DeleteSegment)
 		}
 
+		// Segment exports to marketing platform audiences - uses marketing:segments:view and marketing:segments:manage
+		segmentExports := v1.Group("/segment-exports")
+		{
+			segmentExports.POST("", rbacMiddleware.RequirePermission(rbac.PermissionMarketingSegmentsManage), marketingHandlers.CreateSegmentExport)
+			segmentExports.GET("", rbacMiddleware.RequirePermission(rbac.PermissionMarketingSegmentsView), marketingHandlers.ListSegmentExports)
+			segmentExports.GET("/runs", rbacMiddleware.RequirePermission(rbac.PermissionMarketingSegmentsView), marketingHandlers.ListSegmentExportRuns)
+			segmentExports.GET("/:id", rbacMiddleware.RequirePermission(rbac.PermissionMarketingSegmentsView), marketingHandlers.GetSegmentExport)
+			segmentExports.PUT("/:id", rbacMiddleware.RequirePermission(rbac.PermissionMarketingSegmentsManage), marketingHandlers.UpdateSegmentExport)
+			segmentExports.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionMarketingSegmentsManage), marketingHandlers.DeleteSegmentExport)
+			segmentExports.POST("/:id/sync", rbacMiddleware.RequirePermission(rbac.PermissionMarketingSegmentsManage), marketingHandlers.SyncSegmentExport)
+		}
+
 		// Abandoned Carts with RBAC - uses marketing:carts:view and marketing:carts:recover
 		abandonedCarts := v1.Group("/abandoned-carts")
 		{
@@ -223,6 +293,8 @@ func main() {
 			loyalty.GET("/customers/:customer_id/referrals", rbacMiddleware.RequirePermission(rbac.PermissionMarketingLoyaltyView), marketingHandlers.GetReferrals)
 			loyalty.GET("/customers/:customer_id/referrals/stats", rbacMiddleware.RequirePermission(rbac.PermissionMarketingLoyaltyView), marketingHandlers.GetReferralStats)
 			loyalty.POST("/birthday-bonuses", rbacMiddleware.RequirePermission(rbac.PermissionMarketingLoyaltyManage), marketingHandlers.TriggerBirthdayBonuses)
+			// Review rewards, issued by reviews-service when an incentivized review is approved
+			loyalty.POST("/review-incentives", rbacMiddleware.RequirePermissionAllowInternal(rbac.PermissionMarketingLoyaltyPointsAdjust), marketingHandlers.IssueReviewIncentive)
 		}
 
 		// Coupons with RBAC - uses marketing:coupons:view and marketing:coupons:manage
@@ -258,20 +330,75 @@ func main() {
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Attribute orders to campaigns from order events
+	orderSubscriber, err := subscribers.NewOrderSubscriber(marketingService, logger)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to initialize order subscriber - campaign revenue attribution disabled")
+	} else {
+		go func() {
+			if err := orderSubscriber.Start(context.Background()); err != nil {
+				logger.WithError(err).Warn("Order subscriber failed to start")
+			}
+		}()
+		defer orderSubscriber.Stop()
+	}
+
+	// Remove deleted customers' marketing data and confirm back to customers-service
+	if eventsPublisher != nil {
+		customerDeletionSubscriber, err := subscribers.NewCustomerDeletionSubscriber(marketingService, eventsPublisher, logger)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to initialize customer deletion subscriber - account deletions will not complete")
+		} else {
+			go func() {
+				if err := customerDeletionSubscriber.Start(context.Background()); err != nil {
+					logger.WithError(err).Warn("Customer deletion subscriber failed to start")
+				}
+			}()
+			defer customerDeletionSubscriber.Stop()
+		}
+	}
+
+	// Background jobs stop when the service shuts down
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+
 	// Start daily birthday bonus cron
 	go func() {
 		// Run once on startup (after a short delay to let DB settle)
-		time.Sleep(30 * time.Second)
-		ctx := context.Background()
-		if err := marketingService.AwardBirthdayBonusesAllTenants(ctx); err != nil {
+		select {
+		case <-time.After(30 * time.Second):
+		case <-workerCtx.Done():
+			return
+		}
+		if err := marketingService.AwardBirthdayBonusesAllTenants(workerCtx); err != nil {
 			logger.WithError(err).Error("Failed to run initial birthday bonuses")
 		}
 		// Then run daily
 		ticker := time.NewTicker(24 * time.Hour)
 		defer ticker.Stop()
-		for range ticker.C {
-			if err := marketingService.AwardBirthdayBonusesAllTenants(ctx); err != nil {
-				logger.WithError(err).Error("Failed to run daily birthday bonuses")
+		for {
+			select {
+			case <-workerCtx.Done():
+				return
+			case <-ticker.C:
+				if err := marketingService.AwardBirthdayBonusesAllTenants(workerCtx); err != nil {
+					logger.WithError(err).Error("Failed to run daily birthday bonuses")
+				}
+			}
+		}
+	}()
+
+	// Sync scheduled segment exports that are due
+	go func() {
+		ticker := time.NewTicker(15 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-workerCtx.Done():
+				return
+			case <-ticker.C:
+				if _, err := marketingService.SyncDueSegmentExports(workerCtx); err != nil {
+					logger.WithError(err).Error("Failed to sync scheduled segment exports")
+				}
 			}
 		}
 	}()
@@ -282,8 +409,33 @@ func main() {
 		port = "8080"
 	}
 
-	logger.Infof("Marketing service starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		logger.Fatal("Failed to start server:", err)
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
 	}
+	go func() {
+		logger.Infof("Marketing service starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("Shutting down marketing-service...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting connections and let in-flight requests finish; subscribers and the
+	// events publisher are stopped by their deferred calls once main returns
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.WithError(err).Warn("Server forced to shutdown")
+	}
+	stopWorkers()
+
+	logger.Info("Marketing service stopped")
 }
@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets"
+
+	"marketing-service/internal/tenancy"
 )
 
 type Config struct {
@@ -22,8 +25,9 @@ type Config struct {
 	DBSSLMode  string
 
 	// Server
-	Port        string
-	Environment string
+	Port            string
+	Environment     string
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
 
 	// JWT
 	JWTSecret string
@@ -40,6 +44,30 @@ type Config struct {
 
 	// Notification Service (for campaign sending)
 	NotificationServiceURL string
+
+	// Email provider bounce/complaint webhooks (HMAC-SHA256 shared secret)
+	EmailWebhookSecret string
+
+	// Open/click tracking (disabled unless both are set)
+	TrackingBaseURL string
+	TrackingSecret  string
+
+	// Revenue attribution: days after a campaign touch an order is still credited to it
+	AttributionWindowDays int
+
+	// Customers Service (segment members for audience exports)
+	CustomersServiceURL string
+
+	// Meta custom audiences (Marketing API system user token)
+	MetaAccessToken string
+	MetaAPIVersion  string
+
+	// Google Customer Match (Google Ads API, OAuth refresh token of the manager account)
+	GoogleAdsDeveloperToken  string
+	GoogleAdsClientID        string
+	GoogleAdsClientSecret    string
+	GoogleAdsRefreshToken    string
+	GoogleAdsLoginCustomerID string
 }
 
 func Load() *Config {
@@ -47,6 +75,7 @@ func Load() *Config {
 	defaultPageSize, _ := strconv.Atoi(getEnv("DEFAULT_PAGE_SIZE", "20"))
 	maxPageSize, _ := strconv.Atoi(getEnv("MAX_PAGE_SIZE", "100"))
 	mauticEnabled, _ := strconv.ParseBool(getEnv("MAUTIC_ENABLED", "true"))
+	attributionWindowDays, _ := strconv.Atoi(getEnv("ATTRIBUTION_WINDOW_DAYS", "30"))
 
 	return &Config{
 		// Database
@@ -58,8 +87,9 @@ func Load() *Config {
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
 
 		// Server
-		Port:        getEnv("PORT", "8080"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Port:            getEnv("PORT", "8080"),
+		Environment:     getEnv("ENVIRONMENT", "development"),
+		ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
 
 		// JWT
 		JWTSecret: secrets.GetJWTSecret(),
@@ -76,6 +106,28 @@ func Load() *Config {
 
 		// Notification Service
 		NotificationServiceURL: getEnv("NOTIFICATION_SERVICE_URL", "http://notification-service.notification.svc.cluster.local:8090"),
+
+		// Email provider webhooks
+		EmailWebhookSecret: secrets.GetSecretOrEnv("EMAIL_WEBHOOK_SECRET_NAME", "EMAIL_WEBHOOK_SECRET", ""),
+
+		// Open/click tracking - public URL of this service and the token signing secret
+		TrackingBaseURL: getEnv("TRACKING_BASE_URL", ""),
+		TrackingSecret:  secrets.GetSecretOrEnv("TRACKING_SECRET_NAME", "TRACKING_SECRET", ""),
+
+		// Revenue attribution
+		AttributionWindowDays: attributionWindowDays,
+
+		// Customers Service
+		CustomersServiceURL: getEnv("CUSTOMERS_SERVICE_URL", "http://customers-service.marketplace.svc.cluster.local:8080"),
+
+		// Ad platform audiences - credentials from GCP Secret Manager
+		MetaAccessToken:          secrets.GetSecretOrEnv("META_ACCESS_TOKEN_SECRET_NAME", "META_ACCESS_TOKEN", ""),
+		MetaAPIVersion:           getEnv("META_API_VERSION", "v21.0"),
+		GoogleAdsDeveloperToken:  secrets.GetSecretOrEnv("GOOGLE_ADS_DEVELOPER_TOKEN_SECRET_NAME", "GOOGLE_ADS_DEVELOPER_TOKEN", ""),
+		GoogleAdsClientID:        getEnv("GOOGLE_ADS_CLIENT_ID", ""),
+		GoogleAdsClientSecret:    secrets.GetSecretOrEnv("GOOGLE_ADS_CLIENT_SECRET_NAME", "GOOGLE_ADS_CLIENT_SECRET", ""),
+		GoogleAdsRefreshToken:    secrets.GetSecretOrEnv("GOOGLE_ADS_REFRESH_TOKEN_SECRET_NAME", "GOOGLE_ADS_REFRESH_TOKEN", ""),
+		GoogleAdsLoginCustomerID: getEnv("GOOGLE_ADS_LOGIN_CUSTOMER_ID", ""),
 	}
 }
 
@@ -98,6 +150,11 @@ func InitDB(cfg *Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
+	}
+
 	return db, nil
 }
 
@@ -107,3 +164,12 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
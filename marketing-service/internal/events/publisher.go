@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/Tesseract-Nexus/go-shared/events"
 )
@@ -169,6 +170,25 @@ func (p *Publisher) PublishCouponDeleted(ctx context.Context, tenantID, couponID
 	return p.publisher.Publish(ctx, event)
 }
 
+// ===== CUSTOMER EVENTS =====
+
+// CustomerAnonymizedEvent confirms to customers-service that a service anonymized a deleted customer
+const CustomerAnonymizedEvent = "customer.anonymized"
+
+// PublishCustomerAnonymized confirms that marketing-service anonymized a customer for an
+// account deletion
+func (p *Publisher) PublishCustomerAnonymized(ctx context.Context, tenantID, customerID, anonymizedEmail, requestID string) error {
+	event := events.NewCustomerEvent(CustomerAnonymizedEvent, tenantID)
+	event.SourceID = uuid.New().String()
+	event.CustomerID = customerID
+	event.CustomerEmail = anonymizedEmail
+	event.Metadata = map[string]interface{}{
+		"service":   "marketing-service",
+		"requestId": requestID,
+	}
+	return p.publisher.PublishCustomer(ctx, event)
+}
+
 // IsConnected returns true if connected to NATS
 func (p *Publisher) IsConnected() bool {
 	return p.publisher.IsConnected()
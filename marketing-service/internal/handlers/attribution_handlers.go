@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"marketing-service/internal/models"
+)
+
+// ===== REVENUE ATTRIBUTION =====
+
+// GetCampaignAttribution returns a campaign's attributed orders and revenue under the
+// first-touch and last-touch models
+// GET /api/v1/campaigns/:id/attribution
+func (h *MarketingHandlers) GetCampaignAttribution(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+		return
+	}
+
+	attribution, err := h.service.GetCampaignAttribution(c.Request.Context(), tenantID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to get campaign attribution")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get campaign attribution"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"campaignId": id,
+		"firstTouch": attribution[models.AttributionFirstTouch],
+		"lastTouch":  attribution[models.AttributionLastTouch],
+	})
+}
+
+// ListCampaignConversions lists the orders attributed to a campaign
+// GET /api/v1/campaigns/:id/conversions
+func (h *MarketingHandlers) ListCampaignConversions(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+		return
+	}
+	model, ok := attributionModelParam(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "model must be FIRST_TOUCH or LAST_TOUCH"})
+		return
+	}
+
+	limit, offset := h.getLimit(c), h.getOffset(c)
+	conversions, total, err := h.service.ListCampaignConversions(c.Request.Context(), tenantID, id, model, limit, offset)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to list campaign conversions")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list campaign conversions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"conversions": conversions,
+		"model":       model,
+		"total":       total,
+		"limit":       limit,
+		"offset":      offset,
+	})
+}
+
+// GetAttributionReport returns attributed orders and revenue by campaign for a period
+// GET /api/v1/attribution/report
+func (h *MarketingHandlers) GetAttributionReport(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	model, ok := attributionModelParam(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "model must be FIRST_TOUCH or LAST_TOUCH"})
+		return
+	}
+	from, to := h.getDateRange(c)
+
+	report, err := h.service.GetAttributionReport(c.Request.Context(), tenantID, model, from, to)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get attribution report")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get attribution report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// attributionModelParam reads the model query parameter, defaulting to last touch
+func attributionModelParam(c *gin.Context) (models.AttributionModel, bool) {
+	model := models.AttributionModel(strings.ToUpper(c.DefaultQuery("model", string(models.AttributionLastTouch))))
+	return model, model.IsValid()
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"marketing-service/internal/models"
+	"marketing-service/internal/services"
+)
+
+// trackingPixel is a transparent 1x1 GIF
+var trackingPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// ===== ENGAGEMENT TRACKING =====
+
+// TrackOpen records an email open and serves the tracking pixel. The pixel is served
+// whatever the token, so broken tokens never show as broken images.
+// GET /t/o/:token
+func (h *MarketingHandlers) TrackOpen(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".gif")
+	if err := h.service.RecordTrackedOpen(c.Request.Context(), token, trackingRecipient(c), c.Request.UserAgent(), c.ClientIP()); err != nil && !errors.Is(err, services.ErrInvalidTrackingToken) {
+		h.logger.WithError(err).Warn("Failed to record email open")
+	}
+
+	c.Header("Cache-Control", "no-store, no-cache, must-revalidate, max-age=0")
+	c.Header("Pragma", "no-cache")
+	c.Data(http.StatusOK, "image/gif", trackingPixel)
+}
+
+// TrackClick records a link click and redirects to the link. Only links in a validly
+// signed token are followed.
+// GET /t/c/:token
+func (h *MarketingHandlers) TrackClick(c *gin.Context) {
+	target, err := h.service.RecordTrackedClick(c.Request.Context(), c.Param("token"), trackingRecipient(c), c.Request.UserAgent(), c.ClientIP())
+	if target == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to record email click")
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, target)
+}
+
+// trackingRecipient returns the recipient address Mautic put in a tracking URL. Mautic
+// doesn't URL-encode it, so a '+' in the address arrives as a space.
+func trackingRecipient(c *gin.Context) string {
+	return strings.ReplaceAll(c.Query("r"), " ", "+")
+}
+
+// ProviderWebhook records delivery and engagement events from an email provider. Mautic
+// signs deliveries with the shared secret; SES (SNS) and SendGrid can't sign, so their
+// webhook URLs carry a per-tenant token instead.
+// POST /webhooks/email/:tenant_id/:provider
+func (h *MarketingHandlers) ProviderWebhook(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	provider := c.Param("provider")
+
+	if h.emailWebhookSecret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email webhooks are not configured"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read body"})
+		return
+	}
+
+	var authorized bool
+	switch provider {
+	case models.EngagementProviderMautic:
+		authorized = verifyMauticWebhookSignature(body, c.GetHeader("Webhook-Signature"), h.emailWebhookSecret)
+	case models.EngagementProviderSES, models.EngagementProviderSendGrid:
+		authorized = hmac.Equal([]byte(c.Query("token")), []byte(providerWebhookToken(tenantID, h.emailWebhookSecret)))
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown email provider"})
+		return
+	}
+	if !authorized {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+		return
+	}
+
+	recorded, err := h.service.ProcessProviderWebhook(c.Request.Context(), tenantID, provider, body)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidProviderPayload) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.WithError(err).WithField("provider", provider).Error("Failed to process email provider webhook")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recorded": recorded})
+}
+
+// GetProviderWebhookPaths returns the tenant's email provider webhook paths, including
+// the token SES and SendGrid webhooks authenticate with
+// GET /api/v1/deliverability/webhooks
+func (h *MarketingHandlers) GetProviderWebhookPaths(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if h.emailWebhookSecret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email webhooks are not configured"})
+		return
+	}
+
+	token := providerWebhookToken(tenantID, h.emailWebhookSecret)
+	base := "/webhooks/email/" + tenantID
+	c.JSON(http.StatusOK, gin.H{
+		"generic":  base,
+		"mautic":   base + "/" + models.EngagementProviderMautic,
+		"ses":      base + "/" + models.EngagementProviderSES + "?token=" + token,
+		"sendgrid": base + "/" + models.EngagementProviderSendGrid + "?token=" + token,
+	})
+}
+
+// providerWebhookToken derives a tenant's webhook URL token from the shared secret
+func providerWebhookToken(tenantID, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("webhook:" + tenantID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyMauticWebhookSignature checks Mautic's base64 HMAC-SHA256 of the body
+func verifyMauticWebhookSignature(body []byte, signature, secret string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
+
+// ===== DELIVERABILITY REPORTS =====
+
+// GetCampaignDeliverability returns a campaign's deliverability and engagement report
+// GET /api/v1/campaigns/:id/deliverability
+func (h *MarketingHandlers) GetCampaignDeliverability(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+		return
+	}
+
+	report, err := h.service.GetCampaignDeliverability(c.Request.Context(), tenantID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to get campaign deliverability")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get campaign deliverability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ListCampaignRecipients lists a campaign's per-recipient engagement
+// GET /api/v1/campaigns/:id/recipients
+func (h *MarketingHandlers) ListCampaignRecipients(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+		return
+	}
+
+	filter := &models.RecipientFilter{
+		CampaignID: id,
+		Status:     models.RecipientStatus(c.Query("status")),
+		Limit:      h.getLimit(c),
+		Offset:     h.getOffset(c),
+	}
+	recipients, total, err := h.service.ListCampaignRecipients(c.Request.Context(), tenantID, filter)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to list campaign recipients")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list campaign recipients"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recipients": recipients,
+		"total":      total,
+		"limit":      filter.Limit,
+		"offset":     filter.Offset,
+	})
+}
+
+// GetDeliverabilityReport returns deliverability across the campaigns sent in a period
+// GET /api/v1/deliverability/report
+func (h *MarketingHandlers) GetDeliverabilityReport(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	from, to := h.getDateRange(c)
+
+	report, err := h.service.GetDeliverabilityReport(c.Request.Context(), tenantID, from, to)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get deliverability report")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get deliverability report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
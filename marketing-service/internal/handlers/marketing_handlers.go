@@ -16,9 +16,10 @@ import (
 
 // MarketingHandlers handles HTTP requests for marketing
 type MarketingHandlers struct {
-	service   *services.MarketingService
-	publisher *marketingevents.Publisher
-	logger    *logrus.Logger
+	service            *services.MarketingService
+	publisher          *marketingevents.Publisher
+	logger             *logrus.Logger
+	emailWebhookSecret string
 }
 
 // NewMarketingHandlers creates a new marketing handlers instance
@@ -1068,6 +1069,32 @@ func (h *MarketingHandlers) TriggerBirthdayBonuses(c *gin.Context) {
 	})
 }
 
+// IssueReviewIncentive rewards a customer for an approved incentivized review.
+// Called by reviews-service; repeated calls for a review return the original reward.
+// POST /api/v1/loyalty/review-incentives
+func (h *MarketingHandlers) IssueReviewIncentive(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.IssueReviewIncentiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.IssueReviewIncentive(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.logger.WithError(err).WithField("review_id", req.ReviewID).Error("Failed to issue review incentive")
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusCreated
+	if result.AlreadyIssued {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
+
 // ===== HELPER FUNCTIONS =====
 
 func (h *MarketingHandlers) getLimit(c *gin.Context) int {
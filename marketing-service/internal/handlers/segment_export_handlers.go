@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"marketing-service/internal/models"
+	"marketing-service/internal/services"
+)
+
+// ===== SEGMENT EXPORTS =====
+
+// CreateSegmentExportRequest exports a customers-service segment to a platform audience
+type CreateSegmentExportRequest struct {
+	SegmentID         uuid.UUID               `json:"segmentId" binding:"required"`
+	SegmentName       string                  `json:"segmentName,omitempty"`
+	Platform          models.AudiencePlatform `json:"platform" binding:"required"`
+	AccountID         string                  `json:"accountId,omitempty"`
+	AudienceID        string                  `json:"audienceId,omitempty"`
+	SyncIntervalHours int                     `json:"syncIntervalHours"`
+}
+
+// CreateSegmentExport creates a segment export
+// POST /api/v1/segment-exports
+func (h *MarketingHandlers) CreateSegmentExport(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req CreateSegmentExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	export := &models.SegmentExport{
+		TenantID:          tenantID,
+		SegmentID:         req.SegmentID,
+		SegmentName:       req.SegmentName,
+		Platform:          req.Platform,
+		AccountID:         req.AccountID,
+		AudienceID:        req.AudienceID,
+		SyncIntervalHours: req.SyncIntervalHours,
+		IsActive:          true,
+		CreatedBy:         c.GetString("user_id"),
+	}
+	if err := h.service.CreateSegmentExport(c.Request.Context(), export); err != nil {
+		h.respondSegmentExportError(c, err, "Failed to create segment export")
+		return
+	}
+
+	c.JSON(http.StatusCreated, export)
+}
+
+// ListSegmentExports lists segment exports, optionally for one segment
+// GET /api/v1/segment-exports?segmentId=
+func (h *MarketingHandlers) ListSegmentExports(c *gin.Context) {
+	filter := &models.SegmentExportFilter{
+		TenantID: c.GetString("tenant_id"),
+		Limit:    h.getLimit(c),
+		Offset:   h.getOffset(c),
+	}
+	if segmentIDStr := c.Query("segmentId"); segmentIDStr != "" {
+		segmentID, err := uuid.Parse(segmentIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid segment ID"})
+			return
+		}
+		filter.SegmentID = &segmentID
+	}
+
+	exports, total, err := h.service.ListSegmentExports(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list segment exports")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list segment exports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exports": exports,
+		"total":   total,
+		"limit":   filter.Limit,
+		"offset":  filter.Offset,
+	})
+}
+
+// GetSegmentExport retrieves a segment export
+// GET /api/v1/segment-exports/:id
+func (h *MarketingHandlers) GetSegmentExport(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid segment export ID"})
+		return
+	}
+
+	export, err := h.service.GetSegmentExport(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondSegmentExportError(c, err, "Failed to get segment export")
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// UpdateSegmentExport changes a segment export's name, schedule or active flag
+// PUT /api/v1/segment-exports/:id
+func (h *MarketingHandlers) UpdateSegmentExport(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid segment export ID"})
+		return
+	}
+
+	var update models.SegmentExportUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	export, err := h.service.UpdateSegmentExport(c.Request.Context(), tenantID, id, &update)
+	if err != nil {
+		h.respondSegmentExportError(c, err, "Failed to update segment export")
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// DeleteSegmentExport stops exporting a segment; the platform audience is left as it is
+// DELETE /api/v1/segment-exports/:id
+func (h *MarketingHandlers) DeleteSegmentExport(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid segment export ID"})
+		return
+	}
+
+	if err := h.service.DeleteSegmentExport(c.Request.Context(), tenantID, id); err != nil {
+		h.respondSegmentExportError(c, err, "Failed to delete segment export")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Segment export deleted successfully"})
+}
+
+// SyncSegmentExport starts syncing a segment export now. The sync runs in the background;
+// its progress is in the returned run.
+// POST /api/v1/segment-exports/:id/sync
+func (h *MarketingHandlers) SyncSegmentExport(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid segment export ID"})
+		return
+	}
+
+	run, err := h.service.StartSegmentExportSync(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondSegmentExportError(c, err, "Failed to start segment export sync")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, run)
+}
+
+// ListSegmentExportRuns lists export history, optionally for one segment or export
+// GET /api/v1/segment-exports/runs?segmentId=&exportId=
+func (h *MarketingHandlers) ListSegmentExportRuns(c *gin.Context) {
+	filter := &models.SegmentExportFilter{
+		TenantID: c.GetString("tenant_id"),
+		Limit:    h.getLimit(c),
+		Offset:   h.getOffset(c),
+	}
+	if segmentIDStr := c.Query("segmentId"); segmentIDStr != "" {
+		segmentID, err := uuid.Parse(segmentIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid segment ID"})
+			return
+		}
+		filter.SegmentID = &segmentID
+	}
+	if exportIDStr := c.Query("exportId"); exportIDStr != "" {
+		exportID, err := uuid.Parse(exportIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid segment export ID"})
+			return
+		}
+		filter.ExportID = &exportID
+	}
+
+	runs, total, err := h.service.ListSegmentExportRuns(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list segment export runs")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list segment export runs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"runs":   runs,
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// respondSegmentExportError maps segment export errors to responses
+func (h *MarketingHandlers) respondSegmentExportError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Segment export not found"})
+	case errors.Is(err, services.ErrSegmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Segment not found"})
+	case errors.Is(err, services.ErrInvalidSegmentExport), errors.Is(err, services.ErrAudiencePlatformDisabled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSegmentExportSyncing):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.WithError(err).Error(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"marketing-service/internal/models"
+	"marketing-service/internal/services"
+)
+
+// maxWebhookBodySize bounds email provider webhook payloads
+const maxWebhookBodySize = 1 << 20
+
+// SetEmailWebhookSecret sets the shared secret email provider webhooks are signed with
+func (h *MarketingHandlers) SetEmailWebhookSecret(secret string) {
+	h.emailWebhookSecret = secret
+}
+
+// ===== SUPPRESSION LIST =====
+
+// AddSuppressionRequest is a manual suppression list entry
+type AddSuppressionRequest struct {
+	Email      string                   `json:"email" binding:"required"`
+	Reason     models.SuppressionReason `json:"reason"`
+	CustomerID *uuid.UUID               `json:"customerId,omitempty"`
+	Details    string                   `json:"details,omitempty"`
+}
+
+// AddSuppression adds an address to the suppression list
+// POST /api/v1/suppressions
+func (h *MarketingHandlers) AddSuppression(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req AddSuppressionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Reason == "" {
+		req.Reason = models.SuppressionReasonManual
+	}
+
+	suppression := &models.Suppression{
+		TenantID:   tenantID,
+		Email:      req.Email,
+		CustomerID: req.CustomerID,
+		Reason:     req.Reason,
+		Source:     models.SuppressionSourceManual,
+		Details:    req.Details,
+	}
+	if _, err := h.service.AddSuppression(c.Request.Context(), suppression); err != nil {
+		if errors.Is(err, services.ErrInvalidSuppression) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to add suppression")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add suppression"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, suppression)
+}
+
+// ListSuppressions lists the suppression list
+// GET /api/v1/suppressions
+func (h *MarketingHandlers) ListSuppressions(c *gin.Context) {
+	filter := &models.SuppressionFilter{
+		TenantID:    c.GetString("tenant_id"),
+		SearchQuery: c.Query("search"),
+		Limit:       h.getLimit(c),
+		Offset:      h.getOffset(c),
+	}
+	if reasonStr := c.Query("reason"); reasonStr != "" {
+		reason := models.SuppressionReason(reasonStr)
+		filter.Reason = &reason
+	}
+	if sourceStr := c.Query("source"); sourceStr != "" {
+		source := models.SuppressionSource(sourceStr)
+		filter.Source = &source
+	}
+
+	suppressions, total, err := h.service.ListSuppressions(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list suppressions")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list suppressions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"suppressions": suppressions,
+		"total":        total,
+		"limit":        filter.Limit,
+		"offset":       filter.Offset,
+	})
+}
+
+// DeleteSuppression removes an address from the suppression list
+// DELETE /api/v1/suppressions/:id
+func (h *MarketingHandlers) DeleteSuppression(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid suppression ID"})
+		return
+	}
+
+	if err := h.service.RemoveSuppression(c.Request.Context(), tenantID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Suppression not found"})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to delete suppression")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete suppression"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Suppression removed"})
+}
+
+// ImportSuppressions imports a CSV upload (form field "file") into the suppression list
+// POST /api/v1/suppressions/import
+func (h *MarketingHandlers) ImportSuppressions(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A CSV file is required in the \"file\" field"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.ImportSuppressions(c.Request.Context(), tenantID, file)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSuppression) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to import suppressions")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import suppressions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExportSuppressions downloads the suppression list as CSV
+// GET /api/v1/suppressions/export
+func (h *MarketingHandlers) ExportSuppressions(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", "attachment; filename=suppressions.csv")
+	if err := h.service.ExportSuppressions(c.Request.Context(), tenantID, c.Writer); err != nil {
+		h.logger.WithError(err).Error("Failed to export suppressions")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export suppressions"})
+		return
+	}
+}
+
+// GetSuppressionStats counts the suppression list by reason and source
+// GET /api/v1/suppressions/stats
+func (h *MarketingHandlers) GetSuppressionStats(c *gin.Context) {
+	stats, err := h.service.GetSuppressionStats(c.Request.Context(), c.GetString("tenant_id"))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get suppression stats")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get suppression stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetCampaignSuppressionStats reports how the suppression list affected a campaign send
+// GET /api/v1/campaigns/:id/suppression-stats
+func (h *MarketingHandlers) GetCampaignSuppressionStats(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+		return
+	}
+
+	stats, err := h.service.GetCampaignSuppressionStats(c.Request.Context(), tenantID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to get campaign suppression stats")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get campaign suppression stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// EmailProviderWebhook ingests bounce, complaint and unsubscribe events from the email
+// provider. The body is one event or an array of events, signed with an HMAC-SHA256 of
+// the raw body in X-Webhook-Signature.
+// POST /webhooks/email/:tenant_id
+func (h *MarketingHandlers) EmailProviderWebhook(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	if h.emailWebhookSecret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Email webhooks are not configured"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read body"})
+		return
+	}
+	if !verifyEmailWebhookSignature(body, c.GetHeader("X-Webhook-Signature"), h.emailWebhookSecret) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+		return
+	}
+
+	var events []models.EmailProviderEvent
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(body, &events)
+	} else {
+		var event models.EmailProviderEvent
+		err = json.Unmarshal(body, &event)
+		events = append(events, event)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payload"})
+		return
+	}
+
+	suppressed := 0
+	for i := range events {
+		changed, err := h.service.ProcessEmailProviderEvent(c.Request.Context(), tenantID, &events[i])
+		if err != nil {
+			// Bad addresses are dropped rather than retried forever by the provider
+			if !errors.Is(err, services.ErrInvalidSuppression) {
+				h.logger.WithError(err).Error("Failed to process email provider event")
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process event"})
+				return
+			}
+			h.logger.WithError(err).Warn("Ignoring email provider event")
+			continue
+		}
+		if changed {
+			suppressed++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": len(events), "suppressed": suppressed})
+}
+
+// verifyEmailWebhookSignature checks a hex HMAC-SHA256 of the body
+func verifyEmailWebhookSignature(body []byte, signature, secret string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected))
+}
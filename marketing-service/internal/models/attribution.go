@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttributionModel decides which campaign touch gets credit for an order
+type AttributionModel string
+
+const (
+	AttributionFirstTouch AttributionModel = "FIRST_TOUCH"
+	AttributionLastTouch  AttributionModel = "LAST_TOUCH"
+)
+
+// IsValid reports whether the model is known
+func (m AttributionModel) IsValid() bool {
+	return m == AttributionFirstTouch || m == AttributionLastTouch
+}
+
+// ConversionStatus is the state of an attributed order
+type ConversionStatus string
+
+const (
+	ConversionStatusAttributed ConversionStatus = "ATTRIBUTED"
+	ConversionStatusCancelled  ConversionStatus = "CANCELLED"
+)
+
+// CampaignConversion is an order attributed to a campaign under one attribution model
+type CampaignConversion struct {
+	ID             uuid.UUID        `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID       string           `gorm:"type:varchar(100);not null;uniqueIndex:idx_conversions_order_model" json:"tenantId"`
+	CampaignID     uuid.UUID        `gorm:"type:uuid;not null;index:idx_conversions_campaign" json:"campaignId"`
+	OrderID        string           `gorm:"type:varchar(100);not null;uniqueIndex:idx_conversions_order_model" json:"orderId"`
+	Model          AttributionModel `gorm:"type:varchar(20);not null;uniqueIndex:idx_conversions_order_model" json:"model"`
+	OrderNumber    string           `gorm:"type:varchar(100)" json:"orderNumber,omitempty"`
+	CustomerID     *uuid.UUID       `gorm:"type:uuid" json:"customerId,omitempty"`
+	Revenue        float64          `gorm:"type:decimal(15,2);not null" json:"revenue"`
+	RefundedAmount float64          `gorm:"type:decimal(15,2);default:0" json:"refundedAmount"`
+	Currency       string           `gorm:"type:varchar(3)" json:"currency,omitempty"`
+	Status         ConversionStatus `gorm:"type:varchar(20);not null;default:'ATTRIBUTED'" json:"status"`
+	UTMSource      string           `gorm:"type:varchar(255)" json:"utmSource,omitempty"`
+	UTMMedium      string           `gorm:"type:varchar(255)" json:"utmMedium,omitempty"`
+	UTMCampaign    string           `gorm:"type:varchar(255)" json:"utmCampaign,omitempty"`
+	UTMContent     string           `gorm:"type:varchar(255)" json:"utmContent,omitempty"`
+	UTMTerm        string           `gorm:"type:varchar(255)" json:"utmTerm,omitempty"`
+	TouchedAt      *time.Time       `json:"touchedAt,omitempty"`
+	OrderedAt      time.Time        `gorm:"not null;index:idx_conversions_ordered" json:"orderedAt"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	UpdatedAt      time.Time        `json:"updatedAt"`
+}
+
+func (CampaignConversion) TableName() string {
+	return "campaign_conversions"
+}
+
+// UTMTouch is one set of UTM parameters the storefront captured when the shopper landed
+type UTMTouch struct {
+	Source   string     `json:"source,omitempty"`
+	Medium   string     `json:"medium,omitempty"`
+	Campaign string     `json:"campaign,omitempty"`
+	Content  string     `json:"content,omitempty"`
+	Term     string     `json:"term,omitempty"`
+	LandedAt *time.Time `json:"landedAt,omitempty"`
+}
+
+// OrderAttribution is the first and last touch carried in order event metadata
+type OrderAttribution struct {
+	FirstTouch *UTMTouch `json:"firstTouch,omitempty"`
+	LastTouch  *UTMTouch `json:"lastTouch,omitempty"`
+}
+
+// Touch returns the touch an attribution model credits
+func (a *OrderAttribution) Touch(model AttributionModel) *UTMTouch {
+	if model == AttributionFirstTouch {
+		return a.FirstTouch
+	}
+	return a.LastTouch
+}
+
+// OrderConversion is a placed order with the attribution the storefront captured
+type OrderConversion struct {
+	TenantID    string
+	OrderID     string
+	OrderNumber string
+	CustomerID  *uuid.UUID
+	Total       float64
+	Currency    string
+	OrderedAt   time.Time
+	Attribution OrderAttribution
+}
+
+// CampaignAttribution is the orders and revenue attributed to a campaign under one model.
+// Amounts are summed in the orders' currencies.
+type CampaignAttribution struct {
+	CampaignID        uuid.UUID        `json:"campaignId"`
+	Name              string           `json:"name,omitempty"`
+	Model             AttributionModel `json:"model"`
+	Orders            int64            `json:"orders"`
+	Revenue           float64          `json:"revenue"`
+	Refunded          float64          `json:"refunded"`
+	NetRevenue        float64          `json:"netRevenue"`
+	AverageOrderValue float64          `json:"averageOrderValue"`
+}
+
+// AttributionReport is attributed orders and revenue across a tenant's campaigns
+type AttributionReport struct {
+	From       time.Time             `json:"from"`
+	To         time.Time             `json:"to"`
+	Model      AttributionModel      `json:"model"`
+	Orders     int64                 `json:"orders"`
+	Revenue    float64               `json:"revenue"`
+	Refunded   float64               `json:"refunded"`
+	NetRevenue float64               `json:"netRevenue"`
+	ByCampaign []CampaignAttribution `json:"byCampaign"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EngagementType is the kind of delivery or engagement event recorded for a recipient
+type EngagementType string
+
+const (
+	EngagementSent         EngagementType = "SENT"
+	EngagementDelivered    EngagementType = "DELIVERED"
+	EngagementOpened       EngagementType = "OPENED"
+	EngagementClicked      EngagementType = "CLICKED"
+	EngagementBounced      EngagementType = "BOUNCED"
+	EngagementComplained   EngagementType = "COMPLAINED"
+	EngagementUnsubscribed EngagementType = "UNSUBSCRIBED"
+)
+
+// Engagement event sources
+const (
+	EngagementProviderTracking = "tracking" // our own pixel and redirect endpoints
+	EngagementProviderMautic   = "mautic"
+	EngagementProviderSES      = "ses"
+	EngagementProviderSendGrid = "sendgrid"
+	EngagementProviderPostal   = "postal"
+	EngagementProviderGeneric  = "generic"
+)
+
+// EngagementEvent is one delivery or engagement event for a campaign recipient, as
+// reported by the tracking endpoints or an email provider webhook
+type EngagementEvent struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID        string         `gorm:"type:varchar(100);not null;index:idx_engagement_tenant_time;uniqueIndex:idx_engagement_provider_event,where:provider_event_id <> ''" json:"tenantId"`
+	CampaignID      *uuid.UUID     `gorm:"type:uuid;index:idx_engagement_campaign" json:"campaignId,omitempty"`
+	RecipientID     *uuid.UUID     `gorm:"type:uuid;index:idx_engagement_recipient" json:"recipientId,omitempty"`
+	CustomerID      *uuid.UUID     `gorm:"type:uuid" json:"customerId,omitempty"`
+	Email           string         `gorm:"type:varchar(255)" json:"email,omitempty"`
+	Type            EngagementType `gorm:"type:varchar(20);not null" json:"type"`
+	HardBounce      bool           `gorm:"default:false" json:"hardBounce,omitempty"`
+	URL             string         `gorm:"type:text" json:"url,omitempty"`
+	Provider        string         `gorm:"type:varchar(20);not null;uniqueIndex:idx_engagement_provider_event,where:provider_event_id <> ''" json:"provider"`
+	ProviderEventID string         `gorm:"type:varchar(255);uniqueIndex:idx_engagement_provider_event,where:provider_event_id <> ''" json:"providerEventId,omitempty"`
+	Details         string         `gorm:"type:text" json:"details,omitempty"`
+	UserAgent       string         `gorm:"type:varchar(500)" json:"userAgent,omitempty"`
+	IPAddress       string         `gorm:"type:varchar(45)" json:"ipAddress,omitempty"`
+	OccurredAt      time.Time      `gorm:"not null;index:idx_engagement_tenant_time" json:"occurredAt"`
+	CreatedAt       time.Time      `json:"createdAt"`
+}
+
+func (EngagementEvent) TableName() string {
+	return "campaign_engagement_events"
+}
+
+// LinkClicks is the click count for one link in a campaign
+type LinkClicks struct {
+	URL          string `json:"url"`
+	Clicks       int64  `json:"clicks"`
+	UniqueClicks int64  `json:"uniqueClicks"`
+}
+
+// DeliverabilityRates are engagement rates in percent. Delivery, bounce and complaint
+// rates are against sent; open and click rates against delivered.
+type DeliverabilityRates struct {
+	DeliveryRate    float64 `json:"deliveryRate"`
+	BounceRate      float64 `json:"bounceRate"`
+	ComplaintRate   float64 `json:"complaintRate"`
+	OpenRate        float64 `json:"openRate"`
+	ClickRate       float64 `json:"clickRate"`
+	ClickToOpenRate float64 `json:"clickToOpenRate"`
+	UnsubscribeRate float64 `json:"unsubscribeRate"`
+}
+
+// DeliverabilityCounts are unique-recipient counts for each kind of event
+type DeliverabilityCounts struct {
+	Sent         int64 `json:"sent"`
+	Delivered    int64 `json:"delivered"`
+	Opened       int64 `json:"opened"`
+	Clicked      int64 `json:"clicked"`
+	Bounced      int64 `json:"bounced"`
+	Complained   int64 `json:"complained"`
+	Unsubscribed int64 `json:"unsubscribed"`
+}
+
+// CampaignDeliverability is the deliverability report for one campaign
+type CampaignDeliverability struct {
+	CampaignID uuid.UUID  `json:"campaignId"`
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	SentAt     *time.Time `json:"sentAt,omitempty"`
+	DeliverabilityCounts
+	Rates    DeliverabilityRates `json:"rates"`
+	TopLinks []LinkClicks        `json:"topLinks,omitempty"`
+}
+
+// DailyEngagement is the number of events of each type on one day
+type DailyEngagement struct {
+	Date   string           `json:"date"`
+	Counts map[string]int64 `json:"counts"`
+}
+
+// DeliverabilityReport aggregates deliverability over the campaigns sent in a period
+type DeliverabilityReport struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Campaigns int       `json:"campaigns"`
+	DeliverabilityCounts
+	Rates      DeliverabilityRates      `json:"rates"`
+	ByCampaign []CampaignDeliverability `json:"byCampaign"`
+	Daily      []DailyEngagement        `json:"daily"`
+}
+
+// RecipientFilter filters campaign recipients
+type RecipientFilter struct {
+	CampaignID uuid.UUID
+	Status     RecipientStatus
+	Limit      int
+	Offset     int
+}
@@ -31,6 +31,15 @@ type Campaign struct {
 	ScheduledAt *time.Time      `json:"scheduledAt,omitempty"`
 	SentAt      *time.Time      `json:"sentAt,omitempty"`
 
+	// Mautic email the campaign was sent as, to attribute Mautic webhook events
+	MauticEmailID *int          `gorm:"index:idx_campaigns_mautic_email" json:"mauticEmailId,omitempty"`
+
+	// UTM parameters appended to links; defaulted at send (utm_campaign to the campaign ID)
+	UTMSource   string          `gorm:"type:varchar(255)" json:"utmSource,omitempty"`
+	UTMMedium   string          `gorm:"type:varchar(255)" json:"utmMedium,omitempty"`
+	UTMCampaign string          `gorm:"type:varchar(255);index:idx_campaigns_utm_campaign" json:"utmCampaign,omitempty"`
+	DisableUTM  bool            `gorm:"default:false" json:"disableUtm"`
+
 	// Analytics
 	TotalRecipients int64        `gorm:"default:0" json:"totalRecipients"`
 	Sent            int64        `gorm:"default:0" json:"sent"`
@@ -40,8 +49,14 @@ type Campaign struct {
 	Converted       int64        `gorm:"default:0" json:"converted"`
 	Unsubscribed    int64        `gorm:"default:0" json:"unsubscribed"`
 	Failed          int64        `gorm:"default:0" json:"failed"`
+	Bounced         int64        `gorm:"default:0" json:"bounced"`
+	Complained      int64        `gorm:"default:0" json:"complained"`
 	Revenue         float64      `gorm:"type:decimal(15,2);default:0" json:"revenue"`
 
+	// Suppression list enforcement at send time
+	Suppressed       int64          `gorm:"default:0" json:"suppressed"`
+	SuppressionStats datatypes.JSON `gorm:"type:jsonb" json:"suppressionStats,omitempty"` // Count by reason
+
 	// Metadata
 	Metadata    datatypes.JSON  `gorm:"type:jsonb" json:"metadata,omitempty"`
 	CreatedBy   uuid.UUID       `gorm:"type:uuid" json:"createdBy"`
@@ -196,6 +211,7 @@ type LoyaltyProgram struct {
 	SignupBonus     int             `gorm:"default:0" json:"signupBonus"`
 	BirthdayBonus   int             `gorm:"default:0" json:"birthdayBonus"`
 	ReferralBonus   int             `gorm:"default:0" json:"referralBonus"`
+	ReviewBonus     int             `gorm:"default:0" json:"reviewBonus"` // Points for an approved incentivized review
 
 	CreatedAt       time.Time       `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt       time.Time       `gorm:"autoUpdateTime" json:"updatedAt"`
@@ -275,6 +291,32 @@ const (
 	LoyaltyTxnAdjust    LoyaltyTxnType = "ADJUSTMENT"
 )
 
+// ReviewIncentiveType is the reward issued for an approved incentivized review
+type ReviewIncentiveType string
+
+const (
+	ReviewIncentivePoints   ReviewIncentiveType = "LOYALTY_POINTS"
+	ReviewIncentiveDiscount ReviewIncentiveType = "DISCOUNT"
+)
+
+// IssueReviewIncentiveRequest is sent by reviews-service when an incentivized review is approved
+type IssueReviewIncentiveRequest struct {
+	CustomerID uuid.UUID           `json:"customerId" binding:"required"`
+	ReviewID   uuid.UUID           `json:"reviewId" binding:"required"`
+	Type       ReviewIncentiveType `json:"type" binding:"required"`
+	Value      float64             `json:"value"` // Points, or percent off for discounts. 0 uses the program's review bonus.
+}
+
+// ReviewIncentiveResult is the reward issued for a review. Issuing is idempotent per review,
+// so a repeated request returns the original reward with AlreadyIssued set.
+type ReviewIncentiveResult struct {
+	Type          ReviewIncentiveType `json:"type"`
+	Points        int                 `json:"points,omitempty"`
+	TransactionID *uuid.UUID          `json:"transactionId,omitempty"`
+	CouponCode    string              `json:"couponCode,omitempty"`
+	AlreadyIssued bool                `json:"alreadyIssued"`
+}
+
 // Referral represents a referral relationship between customers
 type Referral struct {
 	ID                    uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
@@ -381,8 +423,10 @@ type CouponUsage struct {
 // CampaignRecipient represents a recipient in a campaign
 type CampaignRecipient struct {
 	ID              uuid.UUID           `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	CampaignID      uuid.UUID           `gorm:"type:uuid;not null;index:idx_recipients_campaign" json:"campaignId"`
-	CustomerID      uuid.UUID           `gorm:"type:uuid;not null;index:idx_recipients_customer" json:"customerId"`
+	TenantID        string              `gorm:"type:varchar(100);index:idx_campaign_recipients_tenant" json:"tenantId"`
+	CampaignID      uuid.UUID           `gorm:"type:uuid;not null;index:idx_recipients_campaign;uniqueIndex:idx_recipients_campaign_email,where:email <> ''" json:"campaignId"`
+	CustomerID      *uuid.UUID          `gorm:"type:uuid;index:idx_recipients_customer" json:"customerId,omitempty"`
+	Email           string              `gorm:"type:varchar(255);uniqueIndex:idx_recipients_campaign_email,where:email <> ''" json:"email,omitempty"`
 
 	Status          RecipientStatus     `gorm:"type:varchar(50);not null;default:'PENDING'" json:"status"`
 	SentAt          *time.Time          `json:"sentAt,omitempty"`
@@ -391,6 +435,13 @@ type CampaignRecipient struct {
 	ClickedAt       *time.Time          `json:"clickedAt,omitempty"`
 	ConvertedAt     *time.Time          `json:"convertedAt,omitempty"`
 	UnsubscribedAt  *time.Time          `json:"unsubscribedAt,omitempty"`
+	BouncedAt       *time.Time          `json:"bouncedAt,omitempty"`
+	ComplainedAt    *time.Time          `json:"complainedAt,omitempty"`
+
+	// Engagement counts (opens and clicks repeat; timestamps above record the first)
+	OpenCount       int                 `gorm:"default:0" json:"openCount"`
+	ClickCount      int                 `gorm:"default:0" json:"clickCount"`
+	LastEventAt     *time.Time          `json:"lastEventAt,omitempty"`
 
 	ErrorMessage    string              `gorm:"type:text" json:"errorMessage,omitempty"`
 
@@ -410,6 +461,8 @@ const (
 	RecipientStatusConverted    RecipientStatus = "CONVERTED"
 	RecipientStatusFailed       RecipientStatus = "FAILED"
 	RecipientStatusUnsubscribed RecipientStatus = "UNSUBSCRIBED"
+	RecipientStatusBounced      RecipientStatus = "BOUNCED"
+	RecipientStatusComplained   RecipientStatus = "COMPLAINED"
 )
 
 // CampaignFilter represents filters for campaign queries
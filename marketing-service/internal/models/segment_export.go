@@ -0,0 +1,137 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AudiencePlatform is a marketing platform a segment can be exported to
+type AudiencePlatform string
+
+const (
+	AudiencePlatformMautic AudiencePlatform = "MAUTIC"
+	AudiencePlatformMeta   AudiencePlatform = "META"   // Meta custom audience
+	AudiencePlatformGoogle AudiencePlatform = "GOOGLE" // Google Ads Customer Match user list
+)
+
+// IsValid reports whether the platform is known
+func (p AudiencePlatform) IsValid() bool {
+	return p == AudiencePlatformMautic || p == AudiencePlatformMeta || p == AudiencePlatformGoogle
+}
+
+// SegmentExportStatus is whether an export is being synced
+type SegmentExportStatus string
+
+const (
+	SegmentExportStatusIdle    SegmentExportStatus = "IDLE"
+	SegmentExportStatusSyncing SegmentExportStatus = "SYNCING"
+)
+
+// SegmentExport keeps a customers-service segment in sync with an audience on a marketing
+// platform. Only members who opted in to marketing are exported.
+type SegmentExport struct {
+	ID          uuid.UUID        `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string           `gorm:"type:varchar(100);not null;index:idx_segment_exports_tenant" json:"tenantId"`
+	SegmentID   uuid.UUID        `gorm:"type:uuid;not null;index:idx_segment_exports_segment" json:"segmentId"` // customers-service segment
+	SegmentName string           `gorm:"type:varchar(255)" json:"segmentName,omitempty"`
+	Platform    AudiencePlatform `gorm:"type:varchar(20);not null" json:"platform"`
+
+	// Where the audience lives: the Mautic segment ID (created on first sync if empty),
+	// the Meta custom audience ID, or the Google user list ID under AccountID
+	AccountID  string `gorm:"type:varchar(100)" json:"accountId,omitempty"` // Google Ads customer ID
+	AudienceID string `gorm:"type:varchar(100)" json:"audienceId,omitempty"`
+
+	// Scheduled sync; 0 syncs on demand only
+	SyncIntervalHours int        `gorm:"default:0" json:"syncIntervalHours"`
+	NextSyncAt        *time.Time `gorm:"index:idx_segment_exports_next_sync" json:"nextSyncAt,omitempty"`
+
+	IsActive      bool                `gorm:"default:true" json:"isActive"`
+	Status        SegmentExportStatus `gorm:"type:varchar(20);not null;default:'IDLE'" json:"status"`
+	SyncStartedAt *time.Time          `json:"syncStartedAt,omitempty"`
+	LastSyncedAt  *time.Time          `json:"lastSyncedAt,omitempty"`
+	LastError     string              `gorm:"type:text" json:"lastError,omitempty"`
+	MemberCount   int64               `gorm:"default:0" json:"memberCount"` // Members currently in the platform audience
+
+	CreatedBy string    `gorm:"type:varchar(100)" json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (SegmentExport) TableName() string {
+	return "segment_exports"
+}
+
+// SegmentExportUpdate changes an export's settings; nil fields are left as they are
+type SegmentExportUpdate struct {
+	SegmentName       *string `json:"segmentName,omitempty"`
+	SyncIntervalHours *int    `json:"syncIntervalHours,omitempty"`
+	IsActive          *bool   `json:"isActive,omitempty"`
+}
+
+// SegmentExportMember is a customer last exported to a platform audience. Only hashes of
+// the identifiers sent are kept, so changes and removals can be sent without storing PII.
+type SegmentExportMember struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID   string    `gorm:"type:varchar(100);not null" json:"tenantId"`
+	ExportID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_segment_export_members_customer" json:"exportId"`
+	CustomerID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_segment_export_members_customer" json:"customerId"`
+	EmailHash  string    `gorm:"type:varchar(64)" json:"emailHash,omitempty"`   // SHA-256 of the normalized email
+	PhoneHash  string    `gorm:"type:varchar(64)" json:"phoneHash,omitempty"`   // SHA-256 of the normalized phone
+	ExternalID string    `gorm:"type:varchar(100)" json:"externalId,omitempty"` // Mautic contact ID
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+func (SegmentExportMember) TableName() string {
+	return "segment_export_members"
+}
+
+// SegmentExportTrigger is what started an export run
+type SegmentExportTrigger string
+
+const (
+	SegmentExportTriggerManual    SegmentExportTrigger = "MANUAL"
+	SegmentExportTriggerScheduled SegmentExportTrigger = "SCHEDULED"
+)
+
+// SegmentExportRunStatus is the outcome of an export run
+type SegmentExportRunStatus string
+
+const (
+	SegmentExportRunRunning   SegmentExportRunStatus = "RUNNING"
+	SegmentExportRunSucceeded SegmentExportRunStatus = "SUCCEEDED"
+	SegmentExportRunFailed    SegmentExportRunStatus = "FAILED"
+)
+
+// SegmentExportRun is one sync of a segment to a platform: the members added, updated,
+// removed and left alone. A failed run keeps the changes it managed to send.
+type SegmentExportRun struct {
+	ID          uuid.UUID              `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string                 `gorm:"type:varchar(100);not null;index:idx_segment_export_runs_tenant" json:"tenantId"`
+	ExportID    uuid.UUID              `gorm:"type:uuid;not null;index:idx_segment_export_runs_export" json:"exportId"`
+	SegmentID   uuid.UUID              `gorm:"type:uuid;not null;index:idx_segment_export_runs_segment" json:"segmentId"`
+	Platform    AudiencePlatform       `gorm:"type:varchar(20);not null" json:"platform"`
+	Trigger     SegmentExportTrigger   `gorm:"type:varchar(20);not null" json:"trigger"`
+	Status      SegmentExportRunStatus `gorm:"type:varchar(20);not null" json:"status"`
+	Added       int                    `gorm:"default:0" json:"added"`
+	Updated     int                    `gorm:"default:0" json:"updated"` // Old identifiers removed and new ones added
+	Removed     int                    `gorm:"default:0" json:"removed"`
+	Unchanged   int                    `gorm:"default:0" json:"unchanged"`
+	Skipped     int                    `gorm:"default:0" json:"skipped"` // Suppressed members and members with no identifier the platform accepts
+	Error       string                 `gorm:"type:text" json:"error,omitempty"`
+	StartedAt   time.Time              `gorm:"not null" json:"startedAt"`
+	CompletedAt *time.Time             `json:"completedAt,omitempty"`
+}
+
+func (SegmentExportRun) TableName() string {
+	return "segment_export_runs"
+}
+
+// SegmentExportFilter represents filters for listing exports and their runs
+type SegmentExportFilter struct {
+	TenantID  string     `json:"tenantId"`
+	SegmentID *uuid.UUID `json:"segmentId,omitempty"`
+	ExportID  *uuid.UUID `json:"exportId,omitempty"`
+	Limit     int        `json:"limit"`
+	Offset    int        `json:"offset"`
+}
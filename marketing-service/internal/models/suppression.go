@@ -0,0 +1,130 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Suppression is an address that must not receive marketing email from a tenant
+type Suppression struct {
+	ID         uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID   string            `gorm:"type:varchar(100);not null;uniqueIndex:idx_suppressions_tenant_email" json:"tenantId"`
+	Email      string            `gorm:"type:varchar(255);not null;uniqueIndex:idx_suppressions_tenant_email" json:"email"`
+	CustomerID *uuid.UUID        `gorm:"type:uuid;index:idx_suppressions_customer" json:"customerId,omitempty"`
+	Reason     SuppressionReason `gorm:"type:varchar(50);not null" json:"reason"`
+	Source     SuppressionSource `gorm:"type:varchar(50);not null" json:"source"`
+
+	// Campaign whose send caused the bounce, complaint or unsubscribe, when known
+	CampaignID *uuid.UUID `gorm:"type:uuid;index:idx_suppressions_campaign" json:"campaignId,omitempty"`
+	Details    string     `gorm:"type:text" json:"details,omitempty"`
+
+	// When the address was marked do-not-contact in Mautic; nil until the next send syncs it
+	ProviderSyncedAt *time.Time `json:"providerSyncedAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// SuppressionReason is why an address is suppressed
+type SuppressionReason string
+
+const (
+	SuppressionReasonHardBounce    SuppressionReason = "HARD_BOUNCE"
+	SuppressionReasonSpamComplaint SuppressionReason = "SPAM_COMPLAINT"
+	SuppressionReasonUnsubscribe   SuppressionReason = "UNSUBSCRIBE"
+	SuppressionReasonManual        SuppressionReason = "MANUAL"
+)
+
+// IsValid reports whether the reason is known
+func (r SuppressionReason) IsValid() bool {
+	return r.Priority() > 0
+}
+
+// Priority orders reasons so a stronger reason replaces a weaker one for the same address
+// (a complaint outranks a bounce, which outranks an unsubscribe)
+func (r SuppressionReason) Priority() int {
+	switch r {
+	case SuppressionReasonSpamComplaint:
+		return 4
+	case SuppressionReasonHardBounce:
+		return 3
+	case SuppressionReasonUnsubscribe:
+		return 2
+	case SuppressionReasonManual:
+		return 1
+	}
+	return 0
+}
+
+// SuppressionSource is how an address got on the suppression list
+type SuppressionSource string
+
+const (
+	SuppressionSourceWebhook SuppressionSource = "WEBHOOK" // Email provider bounce/complaint events
+	SuppressionSourceImport  SuppressionSource = "IMPORT"
+	SuppressionSourceManual  SuppressionSource = "MANUAL"
+)
+
+// SuppressionFilter represents filters for suppression queries
+type SuppressionFilter struct {
+	TenantID    string
+	Reason      *SuppressionReason
+	Source      *SuppressionSource
+	SearchQuery string
+	Limit       int
+	Offset      int
+}
+
+// SuppressionImportResult summarizes a suppression list import
+type SuppressionImportResult struct {
+	Imported int                    `json:"imported"`
+	Updated  int                    `json:"updated"`
+	Skipped  int                    `json:"skipped"`
+	Errors   []SuppressionImportRow `json:"errors,omitempty"`
+}
+
+// SuppressionImportRow is a row that couldn't be imported
+type SuppressionImportRow struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// EmailProviderEvent is a delivery, engagement, bounce, complaint or unsubscribe event
+// reported by the email provider. Generic providers post {"event", "email", "bounceType",
+// "campaignId"}; Postal (behind Mautic) posts {"event": "MessageSent"|"MessageBounced"|...,
+// "payload": {...}}.
+type EmailProviderEvent struct {
+	Event      string `json:"event"`
+	Email      string `json:"email,omitempty"`
+	BounceType string `json:"bounceType,omitempty"` // hard/permanent or soft/transient
+	CampaignID string `json:"campaignId,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	URL        string `json:"url,omitempty"`     // clicked link
+	EventID    string `json:"eventId,omitempty"` // provider event ID, for de-duplication
+
+	// Postal, which delivers Mautic's email
+	UUID    string `json:"uuid,omitempty"`
+	Payload *struct {
+		Status  string `json:"status,omitempty"` // MessageDeliveryFailed: HardFail, SoftFail
+		Details string `json:"details,omitempty"`
+		URL     string `json:"url,omitempty"` // MessageLinkClicked
+		Message *struct {
+			To string `json:"to"`
+		} `json:"message,omitempty"`
+		OriginalMessage *struct {
+			To string `json:"to"`
+		} `json:"original_message,omitempty"`
+	} `json:"payload,omitempty"`
+}
+
+// CampaignSuppressionStats reports how the suppression list affected one campaign send
+type CampaignSuppressionStats struct {
+	CampaignID uuid.UUID `json:"campaignId"`
+	// Suppressed addresses excluded when the campaign was sent
+	SuppressedAtSend         int64            `json:"suppressedAtSend"`
+	SuppressedAtSendByReason map[string]int64 `json:"suppressedAtSendByReason"`
+	// Addresses suppressed because of this campaign (bounces, complaints, unsubscribes)
+	NewSuppressions         int64            `json:"newSuppressions"`
+	NewSuppressionsByReason map[string]int64 `json:"newSuppressionsByReason"`
+}
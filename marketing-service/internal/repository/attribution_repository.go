@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"marketing-service/internal/models"
+)
+
+// ===== REVENUE ATTRIBUTION =====
+
+// CreateCampaignConversion stores an attributed order. It reports false when the order
+// was already attributed under the same model.
+func (r *MarketingRepository) CreateCampaignConversion(ctx context.Context, conversion *models.CampaignConversion) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "order_id"}, {Name: "model"}},
+			DoNothing: true,
+		}).
+		Create(conversion)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetConversionsByOrder retrieves an order's attributions under every model
+func (r *MarketingRepository) GetConversionsByOrder(ctx context.Context, tenantID, orderID string) ([]*models.CampaignConversion, error) {
+	var conversions []*models.CampaignConversion
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND order_id = ?", tenantID, orderID).
+		Find(&conversions).Error
+	return conversions, err
+}
+
+// UpdateCampaignConversion updates an attributed order
+func (r *MarketingRepository) UpdateCampaignConversion(ctx context.Context, conversion *models.CampaignConversion) error {
+	return r.db.WithContext(ctx).Save(conversion).Error
+}
+
+// ListCampaignConversions retrieves the orders attributed to a campaign under a model
+func (r *MarketingRepository) ListCampaignConversions(ctx context.Context, tenantID string, campaignID uuid.UUID, model models.AttributionModel, limit, offset int) ([]*models.CampaignConversion, int64, error) {
+	var conversions []*models.CampaignConversion
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.CampaignConversion{}).
+		Where("tenant_id = ? AND campaign_id = ? AND model = ?", tenantID, campaignID, model)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("ordered_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&conversions).Error
+	return conversions, total, err
+}
+
+// GetCampaignByUTM retrieves the campaign a utm_campaign value points at, preferring
+// the most recently sent when a tenant reused the value
+func (r *MarketingRepository) GetCampaignByUTM(ctx context.Context, tenantID, utmCampaign string) (*models.Campaign, error) {
+	var campaign models.Campaign
+	query := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
+	if id, err := uuid.Parse(utmCampaign); err == nil {
+		query = query.Where("utm_campaign = ? OR id = ?", utmCampaign, id)
+	} else {
+		query = query.Where("utm_campaign = ?", utmCampaign)
+	}
+
+	err := query.Order("sent_at DESC NULLS LAST").First(&campaign).Error
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// AdjustCampaignRevenue adds to a campaign's converted orders and revenue
+func (r *MarketingRepository) AdjustCampaignRevenue(ctx context.Context, campaignID uuid.UUID, orders int64, revenue float64) error {
+	return r.db.WithContext(ctx).Model(&models.Campaign{}).
+		Where("id = ?", campaignID).
+		UpdateColumns(map[string]interface{}{
+			"converted": gorm.Expr("converted + ?", orders),
+			"revenue":   gorm.Expr("revenue + ?", revenue),
+		}).Error
+}
+
+// MarkRecipientConverted records a conversion on a customer's campaign recipient record
+func (r *MarketingRepository) MarkRecipientConverted(ctx context.Context, campaignID, customerID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.CampaignRecipient{}).
+		Where("campaign_id = ? AND customer_id = ? AND converted_at IS NULL", campaignID, customerID).
+		Updates(map[string]interface{}{
+			"converted_at": at,
+			"status": gorm.Expr("CASE WHEN status IN (?) THEN status ELSE ? END",
+				[]models.RecipientStatus{models.RecipientStatusUnsubscribed, models.RecipientStatusBounced, models.RecipientStatusComplained},
+				models.RecipientStatusConverted),
+		}).Error
+}
+
+// GetCampaignAttribution sums attributed orders and revenue by campaign under a model.
+// campaignID narrows it to one campaign; from/to narrow it to orders placed in a period.
+func (r *MarketingRepository) GetCampaignAttribution(ctx context.Context, tenantID string, model models.AttributionModel, campaignID *uuid.UUID, from, to *time.Time) ([]models.CampaignAttribution, error) {
+	var rows []models.CampaignAttribution
+	query := r.db.WithContext(ctx).Model(&models.CampaignConversion{}).
+		Select(`campaign_id, model,
+			COUNT(*) AS orders,
+			COALESCE(SUM(revenue), 0) AS revenue,
+			COALESCE(SUM(refunded_amount), 0) AS refunded`).
+		Where("tenant_id = ? AND model = ? AND status = ?", tenantID, model, models.ConversionStatusAttributed)
+	if campaignID != nil {
+		query = query.Where("campaign_id = ?", *campaignID)
+	}
+	if from != nil {
+		query = query.Where("ordered_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("ordered_at < ?", *to)
+	}
+
+	err := query.Group("campaign_id, model").Order("revenue DESC").Scan(&rows).Error
+	return rows, err
+}
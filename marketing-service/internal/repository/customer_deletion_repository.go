@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"marketing-service/internal/models"
+)
+
+// ===== CUSTOMER DELETION =====
+
+// AnonymizeCustomerData clears a deleted customer's email, IP address and user agent from
+// campaign engagement and recipients, matched by customer ID or email, and stops reminders
+// for their abandoned carts. Counts and timestamps are kept for campaign reporting.
+func (r *MarketingRepository) AnonymizeCustomerData(ctx context.Context, tenantID string, customerID uuid.UUID, email string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.EngagementEvent{}).
+			Where("tenant_id = ? AND (customer_id = ? OR (email <> '' AND LOWER(email) = LOWER(?)))", tenantID, customerID, email).
+			Updates(map[string]interface{}{
+				"email":      "",
+				"details":    "",
+				"user_agent": "",
+				"ip_address": "",
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.CampaignRecipient{}).
+			Where("tenant_id = ? AND (customer_id = ? OR (email <> '' AND LOWER(email) = LOWER(?)))", tenantID, customerID, email).
+			Update("email", "").Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.AbandonedCart{}).
+			Where("tenant_id = ? AND customer_id = ? AND status IN ?", tenantID, customerID,
+				[]models.AbandonedStatus{models.AbandonedStatusPending, models.AbandonedStatusReminded}).
+			Update("status", models.AbandonedStatusIgnored).Error
+	})
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"marketing-service/internal/models"
+)
+
+// ===== CAMPAIGN ENGAGEMENT =====
+
+// CreateEngagementEvent stores an engagement event. Provider events are stored once; it
+// reports false when an event with the same provider event ID was already stored.
+func (r *MarketingRepository) CreateEngagementEvent(ctx context.Context, event *models.EngagementEvent) (bool, error) {
+	if event.ProviderEventID == "" {
+		return true, r.db.WithContext(ctx).Create(event).Error
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "tenant_id"}, {Name: "provider"}, {Name: "provider_event_id"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "provider_event_id <> ''"}}},
+			DoNothing:   true,
+		}).
+		Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetOrCreateCampaignRecipient retrieves a campaign's recipient record for an address,
+// creating it on the first event for that address
+func (r *MarketingRepository) GetOrCreateCampaignRecipient(ctx context.Context, tenantID string, campaignID uuid.UUID, email string, customerID *uuid.UUID) (*models.CampaignRecipient, error) {
+	recipient := &models.CampaignRecipient{
+		TenantID:   tenantID,
+		CampaignID: campaignID,
+		CustomerID: customerID,
+		Email:      email,
+		Status:     models.RecipientStatusPending,
+	}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "campaign_id"}, {Name: "email"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "email <> ''"}}},
+			DoNothing:   true,
+		}).
+		Create(recipient).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var existing models.CampaignRecipient
+	err = r.db.WithContext(ctx).
+		Where("campaign_id = ? AND email = ?", campaignID, email).
+		First(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+// IncrementCampaignCounter adds one to a campaign engagement counter column
+func (r *MarketingRepository) IncrementCampaignCounter(ctx context.Context, campaignID uuid.UUID, column string) error {
+	return r.db.WithContext(ctx).Model(&models.Campaign{}).
+		Where("id = ?", campaignID).
+		UpdateColumn(column, gorm.Expr("? + ?", clause.Column{Name: column}, 1)).
+		Error
+}
+
+// GetCampaignByMauticEmailID retrieves the campaign sent as a Mautic email
+func (r *MarketingRepository) GetCampaignByMauticEmailID(ctx context.Context, tenantID string, mauticEmailID int) (*models.Campaign, error) {
+	var campaign models.Campaign
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND mautic_email_id = ?", tenantID, mauticEmailID).
+		First(&campaign).Error
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// ListCampaignRecipients retrieves a campaign's recipient engagement records
+func (r *MarketingRepository) ListCampaignRecipients(ctx context.Context, tenantID string, filter *models.RecipientFilter) ([]*models.CampaignRecipient, int64, error) {
+	var recipients []*models.CampaignRecipient
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.CampaignRecipient{}).
+		Where("tenant_id = ? AND campaign_id = ?", tenantID, filter.CampaignID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("last_event_at DESC NULLS LAST").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&recipients).Error
+	return recipients, total, err
+}
+
+// GetCampaignLinkClicks retrieves a campaign's most clicked links
+func (r *MarketingRepository) GetCampaignLinkClicks(ctx context.Context, tenantID string, campaignID uuid.UUID, limit int) ([]models.LinkClicks, error) {
+	var links []models.LinkClicks
+	err := r.db.WithContext(ctx).Model(&models.EngagementEvent{}).
+		Select("url, COUNT(*) AS clicks, COUNT(DISTINCT email) AS unique_clicks").
+		Where("tenant_id = ? AND campaign_id = ? AND type = ? AND url <> ''", tenantID, campaignID, models.EngagementClicked).
+		Group("url").
+		Order("clicks DESC").
+		Limit(limit).
+		Scan(&links).Error
+	return links, err
+}
+
+// GetCampaignsSentBetween retrieves a tenant's campaigns sent in a period
+func (r *MarketingRepository) GetCampaignsSentBetween(ctx context.Context, tenantID string, from, to time.Time) ([]*models.Campaign, error) {
+	var campaigns []*models.Campaign
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND sent_at >= ? AND sent_at < ?", tenantID, from, to).
+		Order("sent_at DESC").
+		Find(&campaigns).Error
+	return campaigns, err
+}
+
+// GetDailyEngagement counts a tenant's engagement events by day and type
+func (r *MarketingRepository) GetDailyEngagement(ctx context.Context, tenantID string, from, to time.Time) ([]models.DailyEngagement, error) {
+	var rows []struct {
+		Day   time.Time
+		Type  string
+		Count int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.EngagementEvent{}).
+		Select("DATE_TRUNC('day', occurred_at) AS day, type, COUNT(*) AS count").
+		Where("tenant_id = ? AND occurred_at >= ? AND occurred_at < ?", tenantID, from, to).
+		Group("day, type").
+		Order("day").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var daily []models.DailyEngagement
+	for _, row := range rows {
+		date := row.Day.Format("2006-01-02")
+		if n := len(daily); n == 0 || daily[n-1].Date != date {
+			daily = append(daily, models.DailyEngagement{Date: date, Counts: map[string]int64{}})
+		}
+		daily[len(daily)-1].Counts[row.Type] = row.Count
+	}
+	return daily, nil
+}
@@ -81,7 +81,12 @@ func (r *MarketingRepository) ListCampaigns(ctx context.Context, filter *models.
 
 // UpdateCampaign updates a campaign
 func (r *MarketingRepository) UpdateCampaign(ctx context.Context, campaign *models.Campaign) error {
-	return r.db.WithContext(ctx).Save(campaign).Error
+	// Engagement and revenue counters are only moved by IncrementCampaignCounter and
+	// AdjustCampaignRevenue, so a stale copy of the campaign can't overwrite events
+	// recorded meanwhile
+	return r.db.WithContext(ctx).
+		Omit("sent", "delivered", "opened", "clicked", "unsubscribed", "bounced", "complained", "converted", "revenue").
+		Save(campaign).Error
 }
 
 // DeleteCampaign soft deletes a campaign
@@ -509,6 +514,18 @@ func (r *MarketingRepository) HasBirthdayBonusThisYear(ctx context.Context, tena
 	return count > 0, err
 }
 
+// GetLoyaltyTransactionByReference finds the transaction recorded for a referenced entity, such as a review
+func (r *MarketingRepository) GetLoyaltyTransactionByReference(ctx context.Context, tenantID, referenceType string, referenceID uuid.UUID) (*models.LoyaltyTransaction, error) {
+	var txn models.LoyaltyTransaction
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND reference_type = ? AND reference_id = ?", tenantID, referenceType, referenceID).
+		First(&txn).Error
+	if err != nil {
+		return nil, err
+	}
+	return &txn, nil
+}
+
 // GetActiveBirthdayBonusTenantIDs returns distinct tenant IDs with active loyalty programs that have birthday bonuses
 func (r *MarketingRepository) GetActiveBirthdayBonusTenantIDs(ctx context.Context) ([]string, error) {
 	var tenantIDs []string
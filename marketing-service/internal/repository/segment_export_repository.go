@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+
+	"marketing-service/internal/models"
+)
+
+// ===== SEGMENT EXPORTS =====
+
+// CreateSegmentExport creates a segment export
+func (r *MarketingRepository) CreateSegmentExport(ctx context.Context, export *models.SegmentExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+// GetSegmentExport retrieves a segment export by ID
+func (r *MarketingRepository) GetSegmentExport(ctx context.Context, tenantID string, id uuid.UUID) (*models.SegmentExport, error) {
+	var export models.SegmentExport
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ListSegmentExports retrieves segment exports with filters
+func (r *MarketingRepository) ListSegmentExports(ctx context.Context, filter *models.SegmentExportFilter) ([]*models.SegmentExport, int64, error) {
+	var exports []*models.SegmentExport
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.SegmentExport{}).Where("tenant_id = ?", filter.TenantID)
+	if filter.SegmentID != nil {
+		query = query.Where("segment_id = ?", *filter.SegmentID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&exports).Error
+
+	return exports, total, err
+}
+
+// UpdateSegmentExportSettings saves the settings of an export a user can change, leaving
+// the sync state alone
+func (r *MarketingRepository) UpdateSegmentExportSettings(ctx context.Context, export *models.SegmentExport) error {
+	return r.db.WithContext(ctx).Model(&models.SegmentExport{}).
+		Where("tenant_id = ? AND id = ?", export.TenantID, export.ID).
+		Updates(map[string]interface{}{
+			"segment_name":        export.SegmentName,
+			"sync_interval_hours": export.SyncIntervalHours,
+			"next_sync_at":        export.NextSyncAt,
+			"is_active":           export.IsActive,
+			"updated_at":          time.Now(),
+		}).Error
+}
+
+// DeleteSegmentExport deletes an export and its member records. Run history is kept.
+func (r *MarketingRepository) DeleteSegmentExport(ctx context.Context, tenantID string, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND export_id = ?", tenantID, id).
+		Delete(&models.SegmentExportMember{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		Delete(&models.SegmentExport{}).Error
+}
+
+// ClaimSegmentExport marks an export as syncing, unless a sync started after staleBefore
+// still holds it. It reports whether the claim was made.
+func (r *MarketingRepository) ClaimSegmentExport(ctx context.Context, tenantID string, id uuid.UUID, now, staleBefore time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.SegmentExport{}).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		Where("status <> ? OR sync_started_at IS NULL OR sync_started_at < ?", models.SegmentExportStatusSyncing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":          models.SegmentExportStatusSyncing,
+			"sync_started_at": now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// SetSegmentExportAudience records the platform audience created for an export
+func (r *MarketingRepository) SetSegmentExportAudience(ctx context.Context, id uuid.UUID, audienceID string) error {
+	return r.db.WithContext(ctx).Model(&models.SegmentExport{}).
+		Where("id = ?", id).
+		Update("audience_id", audienceID).Error
+}
+
+// FinishSegmentExportSync releases an export's sync claim and saves the sync outcome
+func (r *MarketingRepository) FinishSegmentExportSync(ctx context.Context, export *models.SegmentExport) error {
+	return r.db.WithContext(ctx).Model(&models.SegmentExport{}).
+		Where("id = ?", export.ID).
+		Updates(map[string]interface{}{
+			"status":          models.SegmentExportStatusIdle,
+			"sync_started_at": nil,
+			"last_synced_at":  export.LastSyncedAt,
+			"last_error":      export.LastError,
+			"member_count":    export.MemberCount,
+			"next_sync_at":    export.NextSyncAt,
+		}).Error
+}
+
+// GetDueSegmentExports retrieves active scheduled exports, across tenants, whose next
+// sync is due and that no current sync holds
+func (r *MarketingRepository) GetDueSegmentExports(ctx context.Context, now, staleBefore time.Time, limit int) ([]*models.SegmentExport, error) {
+	var exports []*models.SegmentExport
+	err := r.db.WithContext(ctx).
+		Where("is_active = ? AND sync_interval_hours > 0", true).
+		Where("next_sync_at IS NULL OR next_sync_at <= ?", now).
+		Where("status <> ? OR sync_started_at IS NULL OR sync_started_at < ?", models.SegmentExportStatusSyncing, staleBefore).
+		Order("next_sync_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&exports).Error
+	return exports, err
+}
+
+// ===== SEGMENT EXPORT MEMBERS =====
+
+// GetSegmentExportMembers retrieves the members last exported for an export
+func (r *MarketingRepository) GetSegmentExportMembers(ctx context.Context, exportID uuid.UUID) ([]*models.SegmentExportMember, error) {
+	var members []*models.SegmentExportMember
+	err := r.db.WithContext(ctx).
+		Where("export_id = ?", exportID).
+		Find(&members).Error
+	return members, err
+}
+
+// GetSegmentExportMembersByCustomer retrieves a customer's members across a tenant's exports
+func (r *MarketingRepository) GetSegmentExportMembersByCustomer(ctx context.Context, tenantID string, customerID uuid.UUID) ([]*models.SegmentExportMember, error) {
+	var members []*models.SegmentExportMember
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND customer_id = ?", tenantID, customerID).
+		Find(&members).Error
+	return members, err
+}
+
+// UpsertSegmentExportMembers records members as exported with their current identifiers
+func (r *MarketingRepository) UpsertSegmentExportMembers(ctx context.Context, members []*models.SegmentExportMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "export_id"}, {Name: "customer_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email_hash", "phone_hash", "external_id", "exported_at"}),
+	}).CreateInBatches(members, 500).Error
+}
+
+// DeleteSegmentExportMembers removes members no longer in an export's audience
+func (r *MarketingRepository) DeleteSegmentExportMembers(ctx context.Context, exportID uuid.UUID, customerIDs []uuid.UUID) error {
+	if len(customerIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Where("export_id = ? AND customer_id IN ?", exportID, customerIDs).
+		Delete(&models.SegmentExportMember{}).Error
+}
+
+// CountSegmentExportMembers counts the members currently exported for an export
+func (r *MarketingRepository) CountSegmentExportMembers(ctx context.Context, exportID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.SegmentExportMember{}).
+		Where("export_id = ?", exportID).
+		Count(&count).Error
+	return count, err
+}
+
+// GetSuppressedEmails returns which of the given addresses are on a tenant's suppression list
+func (r *MarketingRepository) GetSuppressedEmails(ctx context.Context, tenantID string, emails []string) (map[string]bool, error) {
+	suppressed := make(map[string]bool)
+	if len(emails) == 0 {
+		return suppressed, nil
+	}
+	var found []string
+	err := r.db.WithContext(ctx).Model(&models.Suppression{}).
+		Where("tenant_id = ? AND email IN ?", tenantID, emails).
+		Pluck("email", &found).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, email := range found {
+		suppressed[email] = true
+	}
+	return suppressed, nil
+}
+
+// ===== SEGMENT EXPORT RUNS =====
+
+// CreateSegmentExportRun creates an export run
+func (r *MarketingRepository) CreateSegmentExportRun(ctx context.Context, run *models.SegmentExportRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+// UpdateSegmentExportRun updates an export run
+func (r *MarketingRepository) UpdateSegmentExportRun(ctx context.Context, run *models.SegmentExportRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+// ListSegmentExportRuns retrieves export runs, newest first
+func (r *MarketingRepository) ListSegmentExportRuns(ctx context.Context, filter *models.SegmentExportFilter) ([]*models.SegmentExportRun, int64, error) {
+	var runs []*models.SegmentExportRun
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.SegmentExportRun{}).Where("tenant_id = ?", filter.TenantID)
+	if filter.SegmentID != nil {
+		query = query.Where("segment_id = ?", *filter.SegmentID)
+	}
+	if filter.ExportID != nil {
+		query = query.Where("export_id = ?", *filter.ExportID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("started_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&runs).Error
+
+	return runs, total, err
+}
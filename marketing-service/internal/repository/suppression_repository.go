@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"marketing-service/internal/models"
+)
+
+// ===== SUPPRESSION LIST =====
+
+// GetSuppressionByEmail retrieves a tenant's suppression for an address
+func (r *MarketingRepository) GetSuppressionByEmail(ctx context.Context, tenantID, email string) (*models.Suppression, error) {
+	var suppression models.Suppression
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND email = ?", tenantID, email).
+		First(&suppression).Error
+	if err != nil {
+		return nil, err
+	}
+	return &suppression, nil
+}
+
+// GetSuppression retrieves a suppression by ID
+func (r *MarketingRepository) GetSuppression(ctx context.Context, tenantID string, id uuid.UUID) (*models.Suppression, error) {
+	var suppression models.Suppression
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		First(&suppression).Error
+	if err != nil {
+		return nil, err
+	}
+	return &suppression, nil
+}
+
+// CreateSuppression creates a suppression
+func (r *MarketingRepository) CreateSuppression(ctx context.Context, suppression *models.Suppression) error {
+	return r.db.WithContext(ctx).Create(suppression).Error
+}
+
+// UpdateSuppression updates a suppression
+func (r *MarketingRepository) UpdateSuppression(ctx context.Context, suppression *models.Suppression) error {
+	return r.db.WithContext(ctx).Save(suppression).Error
+}
+
+// DeleteSuppression removes an address from the suppression list
+func (r *MarketingRepository) DeleteSuppression(ctx context.Context, tenantID string, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		Delete(&models.Suppression{}).Error
+}
+
+// ListSuppressions retrieves suppressions with filters. A zero limit returns all rows.
+func (r *MarketingRepository) ListSuppressions(ctx context.Context, filter *models.SuppressionFilter) ([]*models.Suppression, int64, error) {
+	var suppressions []*models.Suppression
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Suppression{}).Where("tenant_id = ?", filter.TenantID)
+
+	if filter.Reason != nil {
+		query = query.Where("reason = ?", *filter.Reason)
+	}
+	if filter.Source != nil {
+		query = query.Where("source = ?", *filter.Source)
+	}
+	if filter.SearchQuery != "" {
+		query = query.Where("email ILIKE ?", "%"+filter.SearchQuery+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order("created_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+	err := query.Find(&suppressions).Error
+
+	return suppressions, total, err
+}
+
+// IsCustomerSuppressed reports whether a customer's address is on the suppression list
+func (r *MarketingRepository) IsCustomerSuppressed(ctx context.Context, tenantID string, customerID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Suppression{}).
+		Where("tenant_id = ? AND customer_id = ?", tenantID, customerID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetUnsyncedSuppressions retrieves suppressions not yet pushed to the email provider
+func (r *MarketingRepository) GetUnsyncedSuppressions(ctx context.Context, tenantID string, limit int) ([]*models.Suppression, error) {
+	var suppressions []*models.Suppression
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND provider_synced_at IS NULL", tenantID).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&suppressions).Error
+	return suppressions, err
+}
+
+// MarkSuppressionSynced records that a suppression was pushed to the email provider
+func (r *MarketingRepository) MarkSuppressionSynced(ctx context.Context, id uuid.UUID, syncedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Suppression{}).
+		Where("id = ?", id).
+		Update("provider_synced_at", syncedAt).Error
+}
+
+// CountSuppressionsByReason counts a tenant's suppressions per reason, optionally only
+// those caused by one campaign
+func (r *MarketingRepository) CountSuppressionsByReason(ctx context.Context, tenantID string, campaignID *uuid.UUID) (map[string]int64, error) {
+	var rows []struct {
+		Reason string
+		Count  int64
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.Suppression{}).
+		Select("reason, COUNT(*) as count").
+		Where("tenant_id = ?", tenantID)
+	if campaignID != nil {
+		query = query.Where("campaign_id = ?", *campaignID)
+	}
+	if err := query.Group("reason").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Reason] = row.Count
+	}
+	return counts, nil
+}
+
+// CountSuppressionsBySource counts a tenant's suppressions per source
+func (r *MarketingRepository) CountSuppressionsBySource(ctx context.Context, tenantID string) (map[string]int64, error) {
+	var rows []struct {
+		Source string
+		Count  int64
+	}
+
+	err := r.db.WithContext(ctx).Model(&models.Suppression{}).
+		Select("source, COUNT(*) as count").
+		Where("tenant_id = ?", tenantID).
+		Group("source").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Source] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"marketing-service/internal/models"
+)
+
+// defaultAttributionWindow is how long after a campaign touch an order is credited to it
+const defaultAttributionWindow = 30 * 24 * time.Hour
+
+// attributionModels are the models every order is attributed under
+var attributionModels = []models.AttributionModel{models.AttributionFirstTouch, models.AttributionLastTouch}
+
+// ===== REVENUE ATTRIBUTION =====
+
+// SetAttributionWindow sets how long after a campaign touch an order is credited to it
+func (s *MarketingService) SetAttributionWindow(window time.Duration) {
+	if window > 0 {
+		s.attributionWindow = window
+	}
+}
+
+// RecordOrderConversion attributes a placed order to the campaigns in its first and last
+// UTM touch. Touches that don't name one of the tenant's campaigns, or that are older
+// than the attribution window, get no credit. Last-touch conversions also count towards
+// the campaign's converted and revenue totals. Replayed orders are ignored.
+func (s *MarketingService) RecordOrderConversion(ctx context.Context, order *models.OrderConversion) error {
+	for _, model := range attributionModels {
+		touch := order.Attribution.Touch(model)
+		if touch == nil || touch.Campaign == "" {
+			continue
+		}
+		if touch.LandedAt != nil && order.OrderedAt.Sub(*touch.LandedAt) > s.attributionWindow {
+			continue
+		}
+
+		campaign, err := s.repo.GetCampaignByUTM(ctx, order.TenantID, touch.Campaign)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		conversion := &models.CampaignConversion{
+			TenantID:    order.TenantID,
+			CampaignID:  campaign.ID,
+			Model:       model,
+			OrderID:     order.OrderID,
+			OrderNumber: order.OrderNumber,
+			CustomerID:  order.CustomerID,
+			Revenue:     order.Total,
+			Currency:    order.Currency,
+			Status:      models.ConversionStatusAttributed,
+			UTMSource:   touch.Source,
+			UTMMedium:   touch.Medium,
+			UTMCampaign: touch.Campaign,
+			UTMContent:  touch.Content,
+			UTMTerm:     touch.Term,
+			TouchedAt:   touch.LandedAt,
+			OrderedAt:   order.OrderedAt,
+		}
+		created, err := s.repo.CreateCampaignConversion(ctx, conversion)
+		if err != nil {
+			return err
+		}
+		if !created || model != models.AttributionLastTouch {
+			continue
+		}
+
+		if err := s.repo.AdjustCampaignRevenue(ctx, campaign.ID, 1, order.Total); err != nil {
+			return err
+		}
+		if order.CustomerID != nil {
+			if err := s.repo.MarkRecipientConverted(ctx, campaign.ID, *order.CustomerID, order.OrderedAt); err != nil {
+				return err
+			}
+		}
+		s.logger.WithFields(logrus.Fields{
+			"tenant_id":   order.TenantID,
+			"campaign_id": campaign.ID,
+			"order_id":    order.OrderID,
+		}).Info("Order attributed to campaign")
+	}
+	return nil
+}
+
+// CancelOrderConversion takes a cancelled order's revenue back off its campaigns
+func (s *MarketingService) CancelOrderConversion(ctx context.Context, tenantID, orderID string) error {
+	conversions, err := s.repo.GetConversionsByOrder(ctx, tenantID, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, conversion := range conversions {
+		if conversion.Status != models.ConversionStatusAttributed {
+			continue
+		}
+		conversion.Status = models.ConversionStatusCancelled
+		if err := s.repo.UpdateCampaignConversion(ctx, conversion); err != nil {
+			return err
+		}
+		if conversion.Model == models.AttributionLastTouch {
+			net := conversion.Revenue - conversion.RefundedAmount
+			if err := s.repo.AdjustCampaignRevenue(ctx, conversion.CampaignID, -1, -net); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RefundOrderConversion records a refund against an attributed order. Refunds are capped
+// at the order's revenue.
+func (s *MarketingService) RefundOrderConversion(ctx context.Context, tenantID, orderID string, amount float64) error {
+	if amount <= 0 {
+		return nil
+	}
+	conversions, err := s.repo.GetConversionsByOrder(ctx, tenantID, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, conversion := range conversions {
+		if conversion.Status != models.ConversionStatusAttributed {
+			continue
+		}
+		refund := math.Min(amount, conversion.Revenue-conversion.RefundedAmount)
+		if refund <= 0 {
+			continue
+		}
+		conversion.RefundedAmount += refund
+		if err := s.repo.UpdateCampaignConversion(ctx, conversion); err != nil {
+			return err
+		}
+		if conversion.Model == models.AttributionLastTouch {
+			if err := s.repo.AdjustCampaignRevenue(ctx, conversion.CampaignID, 0, -refund); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetCampaignAttribution returns a campaign's attributed orders and revenue under each model
+func (s *MarketingService) GetCampaignAttribution(ctx context.Context, tenantID string, campaignID uuid.UUID) (map[models.AttributionModel]*models.CampaignAttribution, error) {
+	campaign, err := s.repo.GetCampaign(ctx, tenantID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[models.AttributionModel]*models.CampaignAttribution, len(attributionModels))
+	for _, model := range attributionModels {
+		rows, err := s.repo.GetCampaignAttribution(ctx, tenantID, model, &campaignID, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		row := models.CampaignAttribution{CampaignID: campaignID, Model: model}
+		if len(rows) > 0 {
+			row = rows[0]
+		}
+		row.Name = campaign.Name
+		finishAttribution(&row)
+		result[model] = &row
+	}
+	return result, nil
+}
+
+// ListCampaignConversions lists the orders attributed to a campaign under a model
+func (s *MarketingService) ListCampaignConversions(ctx context.Context, tenantID string, campaignID uuid.UUID, model models.AttributionModel, limit, offset int) ([]*models.CampaignConversion, int64, error) {
+	if _, err := s.repo.GetCampaign(ctx, tenantID, campaignID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListCampaignConversions(ctx, tenantID, campaignID, model, limit, offset)
+}
+
+// GetAttributionReport returns attributed orders and revenue by campaign for orders
+// placed in a period
+func (s *MarketingService) GetAttributionReport(ctx context.Context, tenantID string, model models.AttributionModel, from, to time.Time) (*models.AttributionReport, error) {
+	rows, err := s.repo.GetCampaignAttribution(ctx, tenantID, model, nil, &from, &to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.AttributionReport{
+		From:       from,
+		To:         to,
+		Model:      model,
+		ByCampaign: make([]models.CampaignAttribution, 0, len(rows)),
+	}
+	for _, row := range rows {
+		if campaign, err := s.repo.GetCampaign(ctx, tenantID, row.CampaignID); err == nil {
+			row.Name = campaign.Name
+		}
+		finishAttribution(&row)
+		report.ByCampaign = append(report.ByCampaign, row)
+
+		report.Orders += row.Orders
+		report.Revenue += row.Revenue
+		report.Refunded += row.Refunded
+	}
+	report.NetRevenue = roundMoney(report.Revenue - report.Refunded)
+	return report, nil
+}
+
+// finishAttribution fills in the derived figures of an attribution row
+func finishAttribution(row *models.CampaignAttribution) {
+	row.NetRevenue = roundMoney(row.Revenue - row.Refunded)
+	if row.Orders > 0 {
+		row.AverageOrderValue = roundMoney(row.Revenue / float64(row.Orders))
+	}
+}
+
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
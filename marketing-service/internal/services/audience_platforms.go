@@ -0,0 +1,498 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"marketing-service/internal/config"
+	"marketing-service/internal/models"
+)
+
+const (
+	// metaAudienceBatchSize is the most users Meta accepts in one audience update
+	metaAudienceBatchSize = 10000
+	// googleAudienceBatchSize bounds the operations sent in one offline user data job request
+	googleAudienceBatchSize = 10000
+	// mauticAudienceBatchSize bounds the contacts updated between saves; Mautic takes one
+	// request per contact
+	mauticAudienceBatchSize = 100
+
+	googleAdsAPIVersion = "v18"
+	googleAdsBaseURL    = "https://googleads.googleapis.com/" + googleAdsAPIVersion
+	googleOAuthTokenURL = "https://oauth2.googleapis.com/token"
+)
+
+// audienceEntry is a member added to or removed from a platform audience. Members being
+// added carry their contact details; removals only have what was stored on export.
+type audienceEntry struct {
+	CustomerID uuid.UUID
+	EmailHash  string
+	PhoneHash  string
+	ExternalID string // Mautic contact ID, set on add
+
+	member  *AudienceMember
+	changed bool // Replaces identifiers exported earlier
+}
+
+// audiencePlatform sends audience changes to one marketing platform
+type audiencePlatform interface {
+	IsEnabled() bool
+	batchSize() int
+	// identify returns the hashes of the member's identifiers as the platform matches them,
+	// both empty when the member has none it accepts
+	identify(member AudienceMember) (emailHash, phoneHash string)
+	// prepare creates the platform audience if the export doesn't name one yet
+	prepare(ctx context.Context, export *models.SegmentExport) error
+	add(ctx context.Context, export *models.SegmentExport, entries []*audienceEntry) error
+	remove(ctx context.Context, export *models.SegmentExport, entries []*audienceEntry) error
+}
+
+// ===== IDENTIFIER HASHING =====
+
+// hashIdentifier returns the hex SHA-256 of a normalized identifier, as the ad platforms take it
+func hashIdentifier(value string) string {
+	if value == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeAudienceEmail trims and lowercases an address, or returns "" if it isn't one
+func normalizeAudienceEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !strings.Contains(email, "@") {
+		return ""
+	}
+	return email
+}
+
+// normalizeAudiencePhone returns a phone number's digits with the country code. Numbers
+// without a leading + are skipped: the country can't be told reliably and a wrong guess
+// would match someone else.
+func normalizeAudiencePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	if !strings.HasPrefix(phone, "+") {
+		return ""
+	}
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	if digits.Len() < 8 || digits.Len() > 15 {
+		return ""
+	}
+	return digits.String()
+}
+
+// ===== MAUTIC =====
+
+// mauticAudience exports to a Mautic segment. Mautic stores the contacts themselves, so
+// it's sent the plain email and name; the hashes only detect changes between syncs.
+type mauticAudience struct {
+	client *MauticClient
+}
+
+func (m *mauticAudience) IsEnabled() bool {
+	return m.client != nil && m.client.IsEnabled()
+}
+
+func (m *mauticAudience) batchSize() int {
+	return mauticAudienceBatchSize
+}
+
+func (m *mauticAudience) identify(member AudienceMember) (string, string) {
+	email := normalizeAudienceEmail(member.Email)
+	if email == "" {
+		return "", ""
+	}
+	return hashIdentifier(email), hashIdentifier(normalizeAudiencePhone(member.Phone))
+}
+
+func (m *mauticAudience) prepare(ctx context.Context, export *models.SegmentExport) error {
+	if export.AudienceID != "" {
+		return nil
+	}
+	name := export.SegmentName
+	if name == "" {
+		name = "Segment " + export.SegmentID.String()
+	}
+	segmentID, err := m.client.CreateSegment(ctx, &MauticSegment{
+		Name:        name,
+		Description: fmt.Sprintf("Exported from marketplace segment %s", export.SegmentID),
+		IsPublished: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create Mautic segment: %w", err)
+	}
+	export.AudienceID = strconv.Itoa(segmentID)
+	return nil
+}
+
+func (m *mauticAudience) add(ctx context.Context, export *models.SegmentExport, entries []*audienceEntry) error {
+	segmentID, err := strconv.Atoi(export.AudienceID)
+	if err != nil {
+		return fmt.Errorf("invalid Mautic segment ID %q", export.AudienceID)
+	}
+	for _, entry := range entries {
+		contactID, err := m.client.CreateOrUpdateContact(ctx, &MauticContact{
+			Email:     normalizeAudienceEmail(entry.member.Email),
+			FirstName: entry.member.FirstName,
+			LastName:  entry.member.LastName,
+			Phone:     entry.member.Phone,
+		})
+		if err != nil {
+			return err
+		}
+		if err := m.client.AddContactToSegment(ctx, segmentID, contactID); err != nil {
+			return err
+		}
+		entry.ExternalID = strconv.Itoa(contactID)
+	}
+	return nil
+}
+
+func (m *mauticAudience) remove(ctx context.Context, export *models.SegmentExport, entries []*audienceEntry) error {
+	segmentID, err := strconv.Atoi(export.AudienceID)
+	if err != nil {
+		return fmt.Errorf("invalid Mautic segment ID %q", export.AudienceID)
+	}
+	for _, entry := range entries {
+		contactID, err := strconv.Atoi(entry.ExternalID)
+		if err != nil {
+			continue // Never reached Mautic
+		}
+		if err := m.client.RemoveContactFromSegment(ctx, segmentID, contactID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ===== META CUSTOM AUDIENCES =====
+
+// MetaAudienceClient updates Meta custom audiences through the Marketing API
+type MetaAudienceClient struct {
+	baseURL     string
+	accessToken string
+	httpClient  *http.Client
+}
+
+// NewMetaAudienceClient creates a new Meta Marketing API client
+func NewMetaAudienceClient(cfg *config.Config) *MetaAudienceClient {
+	return &MetaAudienceClient{
+		baseURL:     "https://graph.facebook.com/" + cfg.MetaAPIVersion,
+		accessToken: cfg.MetaAccessToken,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+}
+
+// IsEnabled returns whether a Meta access token is configured
+func (c *MetaAudienceClient) IsEnabled() bool {
+	return c != nil && c.accessToken != ""
+}
+
+func (c *MetaAudienceClient) batchSize() int {
+	return metaAudienceBatchSize
+}
+
+// identify hashes the email and the phone's digits with country code, per Meta's rules
+func (c *MetaAudienceClient) identify(member AudienceMember) (string, string) {
+	return hashIdentifier(normalizeAudienceEmail(member.Email)), hashIdentifier(normalizeAudiencePhone(member.Phone))
+}
+
+// prepare requires an existing audience; custom audiences need terms accepted in Ads Manager
+func (c *MetaAudienceClient) prepare(ctx context.Context, export *models.SegmentExport) error {
+	if export.AudienceID == "" {
+		return fmt.Errorf("%w: audienceId is required for Meta", ErrInvalidSegmentExport)
+	}
+	return nil
+}
+
+func (c *MetaAudienceClient) add(ctx context.Context, export *models.SegmentExport, entries []*audienceEntry) error {
+	return c.updateUsers(ctx, http.MethodPost, export.AudienceID, entries)
+}
+
+func (c *MetaAudienceClient) remove(ctx context.Context, export *models.SegmentExport, entries []*audienceEntry) error {
+	return c.updateUsers(ctx, http.MethodDelete, export.AudienceID, entries)
+}
+
+// updateUsers adds (POST) or removes (DELETE) hashed users from a custom audience
+func (c *MetaAudienceClient) updateUsers(ctx context.Context, method, audienceID string, entries []*audienceEntry) error {
+	data := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		data = append(data, []string{entry.EmailHash, entry.PhoneHash})
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"payload": map[string]interface{}{
+			"schema": []string{"EMAIL", "PHONE"},
+			"data":   data,
+		},
+		"access_token": c.accessToken,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	reqURL := fmt.Sprintf("%s/%s/users", c.baseURL, url.PathEscape(audienceID))
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update Meta audience: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("Meta API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("Meta API error (status %d)", resp.StatusCode)
+	}
+	return nil
+}
+
+// ===== GOOGLE CUSTOMER MATCH =====
+
+// GoogleAdsClient updates Customer Match user lists through the Google Ads API
+type GoogleAdsClient struct {
+	developerToken  string
+	clientID        string
+	clientSecret    string
+	refreshToken    string
+	loginCustomerID string
+	httpClient      *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewGoogleAdsClient creates a new Google Ads API client
+func NewGoogleAdsClient(cfg *config.Config) *GoogleAdsClient {
+	return &GoogleAdsClient{
+		developerToken:  cfg.GoogleAdsDeveloperToken,
+		clientID:        cfg.GoogleAdsClientID,
+		clientSecret:    cfg.GoogleAdsClientSecret,
+		refreshToken:    cfg.GoogleAdsRefreshToken,
+		loginCustomerID: googleCustomerID(cfg.GoogleAdsLoginCustomerID),
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+}
+
+// IsEnabled returns whether Google Ads API credentials are configured
+func (c *GoogleAdsClient) IsEnabled() bool {
+	return c != nil && c.developerToken != "" && c.clientID != "" && c.clientSecret != "" && c.refreshToken != ""
+}
+
+func (c *GoogleAdsClient) batchSize() int {
+	return googleAudienceBatchSize
+}
+
+// identify hashes the email and the E.164 phone, per Google's rules. Gmail ignores dots in
+// the local part, so Google matches those addresses without them.
+func (c *GoogleAdsClient) identify(member AudienceMember) (string, string) {
+	email := normalizeAudienceEmail(member.Email)
+	if local, domain, ok := strings.Cut(email, "@"); ok && (domain == "gmail.com" || domain == "googlemail.com") {
+		email = strings.ReplaceAll(local, ".", "") + "@" + domain
+	}
+	phone := normalizeAudiencePhone(member.Phone)
+	if phone != "" {
+		phone = "+" + phone
+	}
+	return hashIdentifier(email), hashIdentifier(phone)
+}
+
+// prepare requires an existing user list; Customer Match lists are created in Google Ads
+func (c *GoogleAdsClient) prepare(ctx context.Context, export *models.SegmentExport) error {
+	if export.AccountID == "" || export.AudienceID == "" {
+		return fmt.Errorf("%w: accountId and audienceId are required for Google", ErrInvalidSegmentExport)
+	}
+	return nil
+}
+
+func (c *GoogleAdsClient) add(ctx context.Context, export *models.SegmentExport, entries []*audienceEntry) error {
+	return c.uploadUsers(ctx, export, "create", entries)
+}
+
+func (c *GoogleAdsClient) remove(ctx context.Context, export *models.SegmentExport, entries []*audienceEntry) error {
+	return c.uploadUsers(ctx, export, "remove", entries)
+}
+
+// uploadUsers runs an offline user data job that adds ("create") or removes ("remove")
+// hashed users from a Customer Match list. Google processes the job asynchronously.
+func (c *GoogleAdsClient) uploadUsers(ctx context.Context, export *models.SegmentExport, operation string, entries []*audienceEntry) error {
+	customerID := googleCustomerID(export.AccountID)
+
+	// Members opted in to marketing, which covers ad personalization
+	var job struct {
+		ResourceName string `json:"resourceName"`
+	}
+	err := c.do(ctx, fmt.Sprintf("%s/customers/%s/offlineUserDataJobs:create", googleAdsBaseURL, customerID), map[string]interface{}{
+		"job": map[string]interface{}{
+			"type": "CUSTOMER_MATCH_USER_LIST",
+			"customerMatchUserListMetadata": map[string]interface{}{
+				"userList": fmt.Sprintf("customers/%s/userLists/%s", customerID, export.AudienceID),
+				"consent": map[string]string{
+					"adUserData":        "GRANTED",
+					"adPersonalization": "GRANTED",
+				},
+			},
+		},
+	}, &job)
+	if err != nil {
+		return fmt.Errorf("failed to create offline user data job: %w", err)
+	}
+
+	operations := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		var identifiers []map[string]string
+		if entry.EmailHash != "" {
+			identifiers = append(identifiers, map[string]string{"hashedEmail": entry.EmailHash})
+		}
+		if entry.PhoneHash != "" {
+			identifiers = append(identifiers, map[string]string{"hashedPhoneNumber": entry.PhoneHash})
+		}
+		operations = append(operations, map[string]interface{}{
+			operation: map[string]interface{}{"userIdentifiers": identifiers},
+		})
+	}
+	err = c.do(ctx, fmt.Sprintf("%s/%s:addOperations", googleAdsBaseURL, job.ResourceName), map[string]interface{}{
+		"enablePartialFailure": true,
+		"operations":           operations,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to add offline user data job operations: %w", err)
+	}
+
+	if err := c.do(ctx, fmt.Sprintf("%s/%s:run", googleAdsBaseURL, job.ResourceName), map[string]interface{}{}, nil); err != nil {
+		return fmt.Errorf("failed to run offline user data job: %w", err)
+	}
+	return nil
+}
+
+// do posts a request to the Google Ads API and decodes the response into out, if given
+func (c *GoogleAdsClient) do(ctx context.Context, reqURL string, body, out interface{}) error {
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("developer-token", c.developerToken)
+	if c.loginCustomerID != "" {
+		req.Header.Set("login-customer-id", c.loginCustomerID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("Google Ads API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("Google Ads API error (status %d)", resp.StatusCode)
+	}
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// token returns an OAuth access token, refreshing it shortly before it expires
+func (c *GoogleAdsClient) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.accessToken != "" && time.Now().Before(c.expiresAt) {
+		return c.accessToken, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("client_id", c.clientID)
+	form.Set("client_secret", c.clientSecret)
+	form.Set("refresh_token", c.refreshToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, googleOAuthTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh Google access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to refresh Google access token: status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", errors.New("Google token response has no access token")
+	}
+	c.accessToken = result.AccessToken
+	c.expiresAt = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return c.accessToken, nil
+}
+
+// googleCustomerID strips the dashes Google Ads shows in customer IDs
+func googleCustomerID(id string) string {
+	return strings.ReplaceAll(strings.TrimSpace(id), "-", "")
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ===== CUSTOMER DELETION =====
+
+// AnonymizeCustomer removes a deleted customer's personal data from marketing: the customer
+// leaves every exported platform audience, campaign engagement and recipients lose their
+// email, IP address and user agent, and the Mautic contact is deleted. Suppressions are kept,
+// along with the Mautic contact of a suppressed address, so it stays do-not-contact.
+func (s *MarketingService) AnonymizeCustomer(ctx context.Context, tenantID string, customerID uuid.UUID, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	if err := s.removeFromSegmentExports(ctx, tenantID, customerID); err != nil {
+		return err
+	}
+
+	if err := s.repo.AnonymizeCustomerData(ctx, tenantID, customerID, email); err != nil {
+		return fmt.Errorf("failed to anonymize customer data: %w", err)
+	}
+
+	if email == "" || s.mauticClient == nil || !s.mauticClient.IsEnabled() {
+		return nil
+	}
+	suppressed, err := s.repo.GetSuppressedEmails(ctx, tenantID, []string{email})
+	if err != nil {
+		return err
+	}
+	if suppressed[email] {
+		return nil
+	}
+	return s.mauticClient.DeleteContactByEmail(ctx, email)
+}
+
+// removeFromSegmentExports removes a customer from the platform audiences they were exported
+// to. Members of a platform that is no longer configured are only forgotten locally.
+func (s *MarketingService) removeFromSegmentExports(ctx context.Context, tenantID string, customerID uuid.UUID) error {
+	members, err := s.repo.GetSegmentExportMembersByCustomer(ctx, tenantID, customerID)
+	if err != nil {
+		return fmt.Errorf("failed to get segment export members: %w", err)
+	}
+
+	for _, member := range members {
+		export, err := s.repo.GetSegmentExport(ctx, tenantID, member.ExportID)
+		if err != nil {
+			return fmt.Errorf("failed to get segment export: %w", err)
+		}
+
+		platform, err := s.platformFor(export.Platform)
+		switch {
+		case errors.Is(err, ErrAudiencePlatformDisabled):
+			s.logger.WithFields(logrus.Fields{
+				"exportId": export.ID,
+				"platform": export.Platform,
+			}).Warn("Audience platform no longer configured; deleted customer left in platform audience")
+		case err != nil:
+			return err
+		case export.AudienceID != "":
+			if err := platform.remove(ctx, export, []*audienceEntry{exportedEntry(member, false)}); err != nil {
+				return fmt.Errorf("failed to remove customer from %s: %w", export.Platform, err)
+			}
+		}
+
+		if err := s.repo.DeleteSegmentExportMembers(ctx, export.ID, []uuid.UUID{customerID}); err != nil {
+			return fmt.Errorf("failed to delete segment export member: %w", err)
+		}
+	}
+	return nil
+}
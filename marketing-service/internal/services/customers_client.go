@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"marketing-service/internal/serviceauth"
+)
+
+// ErrSegmentNotFound is returned when customers-service has no such segment
+var ErrSegmentNotFound = errors.New("segment not found")
+
+// AudienceMember is a segment member's contact details, as exported to audiences
+type AudienceMember struct {
+	CustomerID  uuid.UUID `json:"customerId"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone,omitempty"`
+	FirstName   string    `json:"firstName,omitempty"`
+	LastName    string    `json:"lastName,omitempty"`
+	CountryCode string    `json:"countryCode,omitempty"`
+}
+
+// CustomersClient reads segment members from customers-service
+type CustomersClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewCustomersClient creates a new customers-service client
+func NewCustomersClient(baseURL string) *CustomersClient {
+	return &CustomersClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetSegmentAudience returns a page of a segment's marketing opted-in members, in customer
+// ID order after the given ID. An empty page means the end of the segment.
+func (c *CustomersClient) GetSegmentAudience(ctx context.Context, tenantID string, segmentID, after uuid.UUID, limit int) ([]AudienceMember, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	if after != uuid.Nil {
+		query.Set("after", after.String())
+	}
+	reqURL := fmt.Sprintf("%s/internal/customers/segments/%s/audience?%s", c.baseURL, segmentID, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	serviceauth.Sign(req, "marketing-service")
+	req.Header.Set("X-Tenant-ID", tenantID)
+	req.Header.Set("X-Internal-Service", "marketing-service")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch segment members: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrSegmentNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("customers API returned status %d", resp.StatusCode)
+	}
+
+	var page struct {
+		Members []AudienceMember `json:"members"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return page.Members, nil
+}
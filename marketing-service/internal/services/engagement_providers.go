@@ -0,0 +1,442 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"marketing-service/internal/models"
+)
+
+var (
+	// ErrUnknownEmailProvider is returned for a webhook from a provider we don't support
+	ErrUnknownEmailProvider = errors.New("unknown email provider")
+	// ErrInvalidProviderPayload is returned for a webhook body that can't be parsed
+	ErrInvalidProviderPayload = errors.New("invalid provider payload")
+)
+
+// ProcessProviderWebhook records the events in a Mautic, SES (via SNS) or SendGrid
+// webhook delivery. Callers authenticate the request first. It returns the number of
+// events recorded; events that fail are logged and skipped so the provider doesn't
+// redeliver the whole batch.
+func (s *MarketingService) ProcessProviderWebhook(ctx context.Context, tenantID, provider string, body []byte) (int, error) {
+	var events []*models.EngagementEvent
+	var err error
+	switch provider {
+	case models.EngagementProviderMautic:
+		events, err = s.parseMauticWebhook(ctx, tenantID, body)
+	case models.EngagementProviderSES:
+		events, err = s.parseSESNotification(ctx, body)
+	case models.EngagementProviderSendGrid:
+		events, err = parseSendGridEvents(body)
+	default:
+		return 0, ErrUnknownEmailProvider
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	for _, event := range events {
+		event.TenantID = tenantID
+		event.Provider = provider
+		if _, err := s.RecordEngagement(ctx, event); err != nil {
+			s.logger.WithError(err).WithField("provider", provider).Warn("Failed to record email provider event")
+			continue
+		}
+		recorded++
+	}
+	return recorded, nil
+}
+
+// ===== SENDGRID =====
+
+// sendGridEvent is one event in a SendGrid Event Webhook delivery. campaign_id and
+// customer_id are custom args set on send.
+type sendGridEvent struct {
+	Email      string `json:"email"`
+	Event      string `json:"event"`
+	Timestamp  int64  `json:"timestamp"`
+	EventID    string `json:"sg_event_id"`
+	URL        string `json:"url"`
+	Type       string `json:"type"` // bounce events: bounce or blocked
+	Reason     string `json:"reason"`
+	UserAgent  string `json:"useragent"`
+	IP         string `json:"ip"`
+	CampaignID string `json:"campaign_id"`
+	CustomerID string `json:"customer_id"`
+}
+
+var sendGridEventTypes = map[string]models.EngagementType{
+	"processed":         models.EngagementSent,
+	"delivered":         models.EngagementDelivered,
+	"open":              models.EngagementOpened,
+	"click":             models.EngagementClicked,
+	"bounce":            models.EngagementBounced,
+	"spamreport":        models.EngagementComplained,
+	"unsubscribe":       models.EngagementUnsubscribed,
+	"group_unsubscribe": models.EngagementUnsubscribed,
+}
+
+func parseSendGridEvents(body []byte) ([]*models.EngagementEvent, error) {
+	var raw []sendGridEvent
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProviderPayload, err)
+	}
+
+	events := make([]*models.EngagementEvent, 0, len(raw))
+	for _, e := range raw {
+		eventType, ok := sendGridEventTypes[e.Event]
+		if !ok {
+			continue
+		}
+		event := &models.EngagementEvent{
+			Email:           e.Email,
+			Type:            eventType,
+			HardBounce:      eventType == models.EngagementBounced && e.Type != "blocked",
+			URL:             e.URL,
+			ProviderEventID: e.EventID,
+			Details:         e.Reason,
+			UserAgent:       truncate(e.UserAgent, 500),
+			IPAddress:       e.IP,
+			CampaignID:      parseUUIDPtr(e.CampaignID),
+			CustomerID:      parseUUIDPtr(e.CustomerID),
+		}
+		if e.Timestamp > 0 {
+			event.OccurredAt = time.Unix(e.Timestamp, 0)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// ===== AMAZON SES =====
+
+// snsEnvelope is an Amazon SNS HTTP delivery
+type snsEnvelope struct {
+	Type         string `json:"Type"`
+	MessageID    string `json:"MessageId"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+type sesRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	DiagnosticCode string `json:"diagnosticCode"`
+}
+
+// sesEvent is an SES event publishing record or notification. campaign_id and
+// customer_id are message tags set on send.
+type sesEvent struct {
+	EventType        string `json:"eventType"`
+	NotificationType string `json:"notificationType"`
+	Mail             struct {
+		Timestamp   time.Time           `json:"timestamp"`
+		Destination []string            `json:"destination"`
+		Tags        map[string][]string `json:"tags"`
+	} `json:"mail"`
+	Bounce *struct {
+		BounceType        string         `json:"bounceType"` // Permanent, Transient or Undetermined
+		BounceSubType     string         `json:"bounceSubType"`
+		BouncedRecipients []sesRecipient `json:"bouncedRecipients"`
+		Timestamp         time.Time      `json:"timestamp"`
+	} `json:"bounce"`
+	Complaint *struct {
+		ComplainedRecipients  []sesRecipient `json:"complainedRecipients"`
+		ComplaintFeedbackType string         `json:"complaintFeedbackType"`
+		Timestamp             time.Time      `json:"timestamp"`
+	} `json:"complaint"`
+	Delivery *struct {
+		Recipients []string  `json:"recipients"`
+		Timestamp  time.Time `json:"timestamp"`
+	} `json:"delivery"`
+	Open *struct {
+		IPAddress string    `json:"ipAddress"`
+		UserAgent string    `json:"userAgent"`
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"open"`
+	Click *struct {
+		IPAddress string    `json:"ipAddress"`
+		UserAgent string    `json:"userAgent"`
+		Link      string    `json:"link"`
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"click"`
+}
+
+func (s *MarketingService) parseSESNotification(ctx context.Context, body []byte) ([]*models.EngagementEvent, error) {
+	var envelope snsEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProviderPayload, err)
+	}
+
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		return nil, s.confirmSNSSubscription(ctx, envelope.SubscribeURL)
+	case "Notification":
+	default:
+		return nil, nil
+	}
+
+	var e sesEvent
+	if err := json.Unmarshal([]byte(envelope.Message), &e); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProviderPayload, err)
+	}
+	kind := e.EventType
+	if kind == "" {
+		kind = e.NotificationType
+	}
+
+	base := models.EngagementEvent{
+		OccurredAt: e.Mail.Timestamp,
+		CampaignID: parseUUIDPtr(sesTag(e.Mail.Tags, "campaign_id")),
+		CustomerID: parseUUIDPtr(sesTag(e.Mail.Tags, "customer_id")),
+	}
+	var events []*models.EngagementEvent
+	add := func(email string, fill func(*models.EngagementEvent)) {
+		event := base
+		event.Email = email
+		// One SNS message per notification; redeliveries keep the message ID
+		event.ProviderEventID = envelope.MessageID + ":" + strings.ToLower(email)
+		fill(&event)
+		events = append(events, &event)
+	}
+
+	switch kind {
+	case "Send":
+		for _, email := range e.Mail.Destination {
+			add(email, func(ev *models.EngagementEvent) { ev.Type = models.EngagementSent })
+		}
+	case "Delivery":
+		if e.Delivery != nil {
+			for _, email := range e.Delivery.Recipients {
+				add(email, func(ev *models.EngagementEvent) {
+					ev.Type = models.EngagementDelivered
+					ev.OccurredAt = e.Delivery.Timestamp
+				})
+			}
+		}
+	case "Bounce":
+		if e.Bounce != nil {
+			for _, r := range e.Bounce.BouncedRecipients {
+				r := r
+				add(r.EmailAddress, func(ev *models.EngagementEvent) {
+					ev.Type = models.EngagementBounced
+					ev.HardBounce = e.Bounce.BounceType == "Permanent"
+					ev.Details = strings.TrimSpace(e.Bounce.BounceSubType + " " + r.DiagnosticCode)
+					ev.OccurredAt = e.Bounce.Timestamp
+				})
+			}
+		}
+	case "Complaint":
+		if e.Complaint != nil {
+			for _, r := range e.Complaint.ComplainedRecipients {
+				add(r.EmailAddress, func(ev *models.EngagementEvent) {
+					ev.Type = models.EngagementComplained
+					ev.Details = e.Complaint.ComplaintFeedbackType
+					ev.OccurredAt = e.Complaint.Timestamp
+				})
+			}
+		}
+	case "Open":
+		if e.Open != nil {
+			for _, email := range e.Mail.Destination {
+				add(email, func(ev *models.EngagementEvent) {
+					ev.Type = models.EngagementOpened
+					ev.UserAgent = truncate(e.Open.UserAgent, 500)
+					ev.IPAddress = e.Open.IPAddress
+					ev.OccurredAt = e.Open.Timestamp
+				})
+			}
+		}
+	case "Click":
+		if e.Click != nil {
+			for _, email := range e.Mail.Destination {
+				add(email, func(ev *models.EngagementEvent) {
+					ev.Type = models.EngagementClicked
+					ev.URL = e.Click.Link
+					ev.UserAgent = truncate(e.Click.UserAgent, 500)
+					ev.IPAddress = e.Click.IPAddress
+					ev.OccurredAt = e.Click.Timestamp
+				})
+			}
+		}
+	}
+	return events, nil
+}
+
+// confirmSNSSubscription confirms a new SNS topic subscription. Only AWS URLs are
+// followed, so the webhook can't be used to make us fetch arbitrary URLs.
+func (s *MarketingService) confirmSNSSubscription(ctx context.Context, subscribeURL string) error {
+	u, err := url.Parse(subscribeURL)
+	if err != nil || u.Scheme != "https" || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+		return fmt.Errorf("%w: untrusted SubscribeURL", ErrInvalidProviderPayload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to confirm SNS subscription: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to confirm SNS subscription: status %d", resp.StatusCode)
+	}
+
+	s.logger.WithField("host", u.Hostname()).Info("Confirmed SNS subscription for SES events")
+	return nil
+}
+
+func sesTag(tags map[string][]string, name string) string {
+	if values := tags[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// ===== MAUTIC =====
+
+// mauticWebhookContact is a contact in a Mautic webhook payload
+type mauticWebhookContact struct {
+	ID     int `json:"id"`
+	Fields struct {
+		Core struct {
+			Email struct {
+				Value string `json:"value"`
+			} `json:"email"`
+		} `json:"core"`
+	} `json:"fields"`
+}
+
+// mauticWebhookPayload holds the Mautic webhook events we track. A delivery can batch
+// several events of each type.
+type mauticWebhookPayload struct {
+	EmailSent []struct {
+		Email struct {
+			ID int `json:"id"`
+		} `json:"email"`
+		Contact   mauticWebhookContact `json:"contact"`
+		Timestamp string               `json:"timestamp"`
+	} `json:"mautic.email_on_send"`
+	EmailOpened []struct {
+		Stat struct {
+			ID           int    `json:"id"`
+			EmailAddress string `json:"emailAddress"`
+			DateRead     string `json:"dateRead"`
+			Email        struct {
+				ID int `json:"id"`
+			} `json:"email"`
+		} `json:"stat"`
+		Timestamp string `json:"timestamp"`
+	} `json:"mautic.email_on_open"`
+	ChannelChanged []struct {
+		Contact   mauticWebhookContact `json:"contact"`
+		Channel   string               `json:"channel"`
+		NewStatus string               `json:"new_status"`
+		Timestamp string               `json:"timestamp"`
+	} `json:"mautic.lead_channel_subscription_changed"`
+}
+
+func (s *MarketingService) parseMauticWebhook(ctx context.Context, tenantID string, body []byte) ([]*models.EngagementEvent, error) {
+	var payload mauticWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProviderPayload, err)
+	}
+
+	// Campaigns are matched by the Mautic email they were sent as
+	campaigns := map[int]*uuid.UUID{}
+	campaignFor := func(mauticEmailID int) (*uuid.UUID, error) {
+		if id, ok := campaigns[mauticEmailID]; ok {
+			return id, nil
+		}
+		var id *uuid.UUID
+		campaign, err := s.repo.GetCampaignByMauticEmailID(ctx, tenantID, mauticEmailID)
+		if err == nil {
+			id = &campaign.ID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		campaigns[mauticEmailID] = id
+		return id, nil
+	}
+
+	var events []*models.EngagementEvent
+	for _, e := range payload.EmailSent {
+		campaignID, err := campaignFor(e.Email.ID)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &models.EngagementEvent{
+			CampaignID:      campaignID,
+			Email:           e.Contact.Fields.Core.Email.Value,
+			Type:            models.EngagementSent,
+			ProviderEventID: fmt.Sprintf("send:%d:%d", e.Email.ID, e.Contact.ID),
+			OccurredAt:      parseMauticTime(e.Timestamp),
+		})
+	}
+	for _, e := range payload.EmailOpened {
+		campaignID, err := campaignFor(e.Stat.Email.ID)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &models.EngagementEvent{
+			CampaignID:      campaignID,
+			Email:           e.Stat.EmailAddress,
+			Type:            models.EngagementOpened,
+			ProviderEventID: "open:" + strconv.Itoa(e.Stat.ID) + ":" + e.Stat.DateRead,
+			OccurredAt:      parseMauticTime(e.Timestamp),
+		})
+	}
+	for _, e := range payload.ChannelChanged {
+		if e.Channel != "email" {
+			continue
+		}
+		event := &models.EngagementEvent{
+			Email:           e.Contact.Fields.Core.Email.Value,
+			ProviderEventID: fmt.Sprintf("dnc:%d:%s:%s", e.Contact.ID, e.NewStatus, e.Timestamp),
+			Details:         "Mautic channel status changed to " + e.NewStatus,
+			OccurredAt:      parseMauticTime(e.Timestamp),
+		}
+		switch e.NewStatus {
+		case "unsubscribed":
+			event.Type = models.EngagementUnsubscribed
+		case "bounced":
+			event.Type = models.EngagementBounced
+			event.HardBounce = true
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// parseMauticTime parses a Mautic webhook timestamp, falling back to now
+func parseMauticTime(value string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
+
+func parseUUIDPtr(value string) *uuid.UUID {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
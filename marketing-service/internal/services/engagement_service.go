@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"marketing-service/internal/models"
+)
+
+// topLinksLimit is the number of links shown in a campaign deliverability report
+const topLinksLimit = 10
+
+// engagementCounters maps each engagement type to the campaign counter column bumped on
+// a recipient's first event of that type
+var engagementCounters = map[models.EngagementType]string{
+	models.EngagementSent:         "sent",
+	models.EngagementDelivered:    "delivered",
+	models.EngagementOpened:       "opened",
+	models.EngagementClicked:      "clicked",
+	models.EngagementBounced:      "bounced",
+	models.EngagementComplained:   "complained",
+	models.EngagementUnsubscribed: "unsubscribed",
+}
+
+// recipientStatusRank orders recipient statuses so a late event (a delivery report after
+// an open) doesn't move a recipient backwards. Bounces, complaints and unsubscribes win.
+var recipientStatusRank = map[models.RecipientStatus]int{
+	models.RecipientStatusPending:      0,
+	models.RecipientStatusFailed:       1,
+	models.RecipientStatusSent:         1,
+	models.RecipientStatusDelivered:    2,
+	models.RecipientStatusOpened:       3,
+	models.RecipientStatusClicked:      4,
+	models.RecipientStatusConverted:    5,
+	models.RecipientStatusUnsubscribed: 6,
+	models.RecipientStatusBounced:      7,
+	models.RecipientStatusComplained:   8,
+}
+
+// ===== CAMPAIGN ENGAGEMENT =====
+
+// SetTracking enables open and click tracking for campaigns sent from now on
+func (s *MarketingService) SetTracking(tracking *TrackingSigner) {
+	s.tracking = tracking
+}
+
+// RecordEngagement records a delivery or engagement event: it stores the event, updates
+// the recipient's engagement record and the campaign's counters, and suppresses the
+// address on a hard bounce, complaint or unsubscribe. Provider events are idempotent.
+// It reports whether the address was newly suppressed.
+func (s *MarketingService) RecordEngagement(ctx context.Context, event *models.EngagementEvent) (bool, error) {
+	if event.Email != "" {
+		email, err := normalizeEmail(event.Email)
+		if err != nil {
+			return false, err
+		}
+		event.Email = email
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	// Only attribute events to the tenant's own campaigns
+	if event.CampaignID != nil {
+		if _, err := s.repo.GetCampaign(ctx, event.TenantID, *event.CampaignID); err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, err
+			}
+			event.CampaignID = nil
+		}
+	}
+
+	var recipient *models.CampaignRecipient
+	if event.CampaignID != nil && event.Email != "" {
+		var err error
+		recipient, err = s.repo.GetOrCreateCampaignRecipient(ctx, event.TenantID, *event.CampaignID, event.Email, event.CustomerID)
+		if err != nil {
+			return false, err
+		}
+		event.RecipientID = &recipient.ID
+	}
+
+	stored, err := s.repo.CreateEngagementEvent(ctx, event)
+	if err != nil {
+		return false, err
+	}
+	if !stored {
+		// Redelivered provider event
+		return false, nil
+	}
+
+	if recipient != nil {
+		if err := s.applyEngagement(ctx, recipient, event); err != nil {
+			return false, err
+		}
+	}
+	return s.suppressFromEngagement(ctx, event)
+}
+
+// applyEngagement updates a recipient's engagement record with an event and bumps the
+// campaign counters for the recipient's first event of each type
+func (s *MarketingService) applyEngagement(ctx context.Context, recipient *models.CampaignRecipient, event *models.EngagementEvent) error {
+	at := event.OccurredAt
+	var firsts []models.EngagementType
+	markFirst := func(field **time.Time, eventType models.EngagementType) {
+		if *field == nil {
+			*field = &at
+			firsts = append(firsts, eventType)
+		}
+	}
+
+	status := recipient.Status
+	switch event.Type {
+	case models.EngagementSent:
+		markFirst(&recipient.SentAt, event.Type)
+		status = models.RecipientStatusSent
+	case models.EngagementDelivered:
+		markFirst(&recipient.DeliveredAt, event.Type)
+		status = models.RecipientStatusDelivered
+	case models.EngagementOpened:
+		recipient.OpenCount++
+		markFirst(&recipient.OpenedAt, event.Type)
+		status = models.RecipientStatusOpened
+	case models.EngagementClicked:
+		recipient.ClickCount++
+		markFirst(&recipient.ClickedAt, event.Type)
+		// A click means the email was opened, even if images were blocked
+		if recipient.OpenedAt == nil {
+			recipient.OpenCount++
+			markFirst(&recipient.OpenedAt, models.EngagementOpened)
+		}
+		status = models.RecipientStatusClicked
+	case models.EngagementBounced:
+		// Soft bounces are kept as events only; the provider retries them
+		if event.HardBounce {
+			markFirst(&recipient.BouncedAt, event.Type)
+			status = models.RecipientStatusBounced
+		}
+	case models.EngagementComplained:
+		markFirst(&recipient.ComplainedAt, event.Type)
+		status = models.RecipientStatusComplained
+	case models.EngagementUnsubscribed:
+		markFirst(&recipient.UnsubscribedAt, event.Type)
+		status = models.RecipientStatusUnsubscribed
+	}
+
+	if recipientStatusRank[status] > recipientStatusRank[recipient.Status] {
+		recipient.Status = status
+	}
+	if recipient.CustomerID == nil {
+		recipient.CustomerID = event.CustomerID
+	}
+	if recipient.LastEventAt == nil || at.After(*recipient.LastEventAt) {
+		recipient.LastEventAt = &at
+	}
+
+	if err := s.repo.UpdateCampaignRecipient(ctx, recipient); err != nil {
+		return err
+	}
+	for _, eventType := range firsts {
+		if err := s.repo.IncrementCampaignCounter(ctx, recipient.CampaignID, engagementCounters[eventType]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// suppressFromEngagement puts the address on the suppression list for hard bounces,
+// complaints and unsubscribes
+func (s *MarketingService) suppressFromEngagement(ctx context.Context, event *models.EngagementEvent) (bool, error) {
+	var reason models.SuppressionReason
+	switch {
+	case event.Type == models.EngagementBounced && event.HardBounce:
+		reason = models.SuppressionReasonHardBounce
+	case event.Type == models.EngagementComplained:
+		reason = models.SuppressionReasonSpamComplaint
+	case event.Type == models.EngagementUnsubscribed:
+		reason = models.SuppressionReasonUnsubscribe
+	}
+	if reason == "" || event.Email == "" {
+		return false, nil
+	}
+
+	changed, err := s.AddSuppression(ctx, &models.Suppression{
+		TenantID:   event.TenantID,
+		Email:      event.Email,
+		CustomerID: event.CustomerID,
+		Reason:     reason,
+		Source:     models.SuppressionSourceWebhook,
+		CampaignID: event.CampaignID,
+		Details:    event.Details,
+	})
+	if err != nil {
+		return false, err
+	}
+	if changed {
+		s.logger.WithFields(logrus.Fields{
+			"tenant_id": event.TenantID,
+			"reason":    reason,
+			"provider":  event.Provider,
+		}).Info("Address suppressed from email provider event")
+	}
+	return changed, nil
+}
+
+// RecordTrackedOpen records an open from the tracking pixel. recipient is the address
+// Mautic substituted into the pixel URL, used when the token doesn't carry one.
+func (s *MarketingService) RecordTrackedOpen(ctx context.Context, signed, recipient, userAgent, ip string) error {
+	_, err := s.recordTracked(ctx, models.EngagementOpened, signed, recipient, userAgent, ip)
+	return err
+}
+
+// RecordTrackedClick records a click from the redirect endpoint and returns the link to
+// redirect to
+func (s *MarketingService) RecordTrackedClick(ctx context.Context, signed, recipient, userAgent, ip string) (string, error) {
+	token, err := s.recordTracked(ctx, models.EngagementClicked, signed, recipient, userAgent, ip)
+	if token == nil {
+		return "", err
+	}
+	return token.URL, err
+}
+
+// recordTracked verifies a tracking token and records its event. The token is returned
+// whenever it verified, even if recording failed, so clicks still redirect.
+func (s *MarketingService) recordTracked(ctx context.Context, eventType models.EngagementType, signed, recipient, userAgent, ip string) (*TrackingToken, error) {
+	if s.tracking == nil {
+		return nil, ErrInvalidTrackingToken
+	}
+	token, err := s.tracking.Verify(signed)
+	if err != nil {
+		return nil, err
+	}
+	if eventType == models.EngagementClicked && token.URL == "" {
+		return nil, ErrInvalidTrackingToken
+	}
+
+	email := token.Email
+	if email == "" {
+		email = recipient
+	}
+	// Unrendered previews carry the Mautic token instead of an address
+	if _, err := normalizeEmail(email); err != nil {
+		email = ""
+	}
+
+	event := &models.EngagementEvent{
+		TenantID:  token.TenantID,
+		Email:     email,
+		Type:      eventType,
+		URL:       token.URL,
+		Provider:  models.EngagementProviderTracking,
+		UserAgent: truncate(userAgent, 500),
+		IPAddress: ip,
+	}
+	if campaignID, err := uuid.Parse(token.CampaignID); err == nil {
+		event.CampaignID = &campaignID
+	}
+
+	_, err = s.RecordEngagement(ctx, event)
+	return token, err
+}
+
+// GetCampaignDeliverability returns a campaign's deliverability and engagement report
+func (s *MarketingService) GetCampaignDeliverability(ctx context.Context, tenantID string, campaignID uuid.UUID) (*models.CampaignDeliverability, error) {
+	campaign, err := s.repo.GetCampaign(ctx, tenantID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := campaignDeliverability(campaign)
+	report.TopLinks, err = s.repo.GetCampaignLinkClicks(ctx, tenantID, campaignID, topLinksLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ListCampaignRecipients lists a campaign's per-recipient engagement records
+func (s *MarketingService) ListCampaignRecipients(ctx context.Context, tenantID string, filter *models.RecipientFilter) ([]*models.CampaignRecipient, int64, error) {
+	if _, err := s.repo.GetCampaign(ctx, tenantID, filter.CampaignID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListCampaignRecipients(ctx, tenantID, filter)
+}
+
+// GetDeliverabilityReport aggregates deliverability over the campaigns a tenant sent in
+// a period, with a daily breakdown of events
+func (s *MarketingService) GetDeliverabilityReport(ctx context.Context, tenantID string, from, to time.Time) (*models.DeliverabilityReport, error) {
+	campaigns, err := s.repo.GetCampaignsSentBetween(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.DeliverabilityReport{
+		From:       from,
+		To:         to,
+		Campaigns:  len(campaigns),
+		ByCampaign: make([]models.CampaignDeliverability, 0, len(campaigns)),
+	}
+	for _, campaign := range campaigns {
+		row := campaignDeliverability(campaign)
+		report.ByCampaign = append(report.ByCampaign, row)
+
+		report.Sent += row.Sent
+		report.Delivered += row.Delivered
+		report.Opened += row.Opened
+		report.Clicked += row.Clicked
+		report.Bounced += row.Bounced
+		report.Complained += row.Complained
+		report.Unsubscribed += row.Unsubscribed
+	}
+	report.Rates = deliverabilityRates(report.DeliverabilityCounts)
+
+	report.Daily, err = s.repo.GetDailyEngagement(ctx, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// campaignDeliverability builds a campaign's report from its counters
+func campaignDeliverability(campaign *models.Campaign) models.CampaignDeliverability {
+	counts := models.DeliverabilityCounts{
+		Sent:         campaign.Sent,
+		Delivered:    campaign.Delivered,
+		Opened:       campaign.Opened,
+		Clicked:      campaign.Clicked,
+		Bounced:      campaign.Bounced,
+		Complained:   campaign.Complained,
+		Unsubscribed: campaign.Unsubscribed,
+	}
+	// Providers that don't report sends still report deliveries and bounces
+	if attempted := counts.Delivered + counts.Bounced; counts.Sent < attempted {
+		counts.Sent = attempted
+	}
+
+	return models.CampaignDeliverability{
+		CampaignID:           campaign.ID,
+		Name:                 campaign.Name,
+		Status:               string(campaign.Status),
+		SentAt:               campaign.SentAt,
+		DeliverabilityCounts: counts,
+		Rates:                deliverabilityRates(counts),
+	}
+}
+
+// deliverabilityRates computes engagement rates from counts
+func deliverabilityRates(c models.DeliverabilityCounts) models.DeliverabilityRates {
+	return models.DeliverabilityRates{
+		DeliveryRate:    percent(c.Delivered, c.Sent),
+		BounceRate:      percent(c.Bounced, c.Sent),
+		ComplaintRate:   percent(c.Complained, c.Sent),
+		OpenRate:        percent(c.Opened, c.Delivered),
+		ClickRate:       percent(c.Clicked, c.Delivered),
+		ClickToOpenRate: percent(c.Clicked, c.Opened),
+		UnsubscribeRate: percent(c.Unsubscribed, c.Delivered),
+	}
+}
+
+// percent returns n as a percentage of total, rounded to two decimals
+func percent(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(n)*10000/float64(total)) / 100
+}
+
+// truncate shortens s to at most n bytes
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
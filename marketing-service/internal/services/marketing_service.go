@@ -24,6 +24,14 @@ type MarketingService struct {
 	logger       *logrus.Logger
 	fromEmail    string
 	fromName     string
+	tracking     *TrackingSigner
+
+	// attributionWindow is how long after a campaign touch an order is still credited to it
+	attributionWindow time.Duration
+
+	// Segment exports: members come from customers-service, audiences live on the platforms
+	customersClient   *CustomersClient
+	audiencePlatforms map[models.AudiencePlatform]audiencePlatform
 }
 
 // NewMarketingService creates a new marketing service
@@ -42,6 +50,8 @@ func NewMarketingService(repo *repository.MarketingRepository, mauticClient *Mau
 		logger:       logger,
 		fromEmail:    fromEmail,
 		fromName:     fromName,
+
+		attributionWindow: defaultAttributionWindow,
 	}
 }
 
@@ -282,6 +292,20 @@ func (s *MarketingService) ProcessAbandonedCarts(ctx context.Context, tenantID s
 	}
 
 	for _, cart := range carts {
+		// Customers on the suppression list get no reminders
+		suppressed, err := s.repo.IsCustomerSuppressed(ctx, tenantID, cart.CustomerID)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to check suppression list")
+			continue
+		}
+		if suppressed {
+			cart.Status = models.AbandonedStatusIgnored
+			if err := s.repo.UpdateAbandonedCart(ctx, cart); err != nil {
+				s.logger.WithError(err).Error("Failed to update abandoned cart")
+			}
+			continue
+		}
+
 		// Send recovery email/SMS via notification service
 		s.logger.WithFields(logrus.Fields{
 			"cart_id":     cart.ID,
@@ -746,6 +770,135 @@ func (s *MarketingService) AwardBirthdayBonusesAllTenants(ctx context.Context) e
 	return nil
 }
 
+// ===== REVIEW INCENTIVES =====
+
+// reviewCouponValidity is how long a review reward coupon can be used
+const reviewCouponValidity = 90 * 24 * time.Hour
+
+// reviewIncentiveReference is the loyalty transaction reference type for review rewards
+const reviewIncentiveReference = "review"
+
+// IssueReviewIncentive rewards a customer for an approved incentivized review with loyalty points
+// or a single-use discount coupon. Rewards are issued once per review; repeated calls return
+// the original reward.
+func (s *MarketingService) IssueReviewIncentive(ctx context.Context, tenantID string, req *models.IssueReviewIncentiveRequest) (*models.ReviewIncentiveResult, error) {
+	switch req.Type {
+	case models.ReviewIncentivePoints:
+		return s.issueReviewPoints(ctx, tenantID, req)
+	case models.ReviewIncentiveDiscount:
+		return s.issueReviewCoupon(ctx, tenantID, req)
+	}
+	return nil, fmt.Errorf("unsupported review incentive type %q", req.Type)
+}
+
+func (s *MarketingService) issueReviewPoints(ctx context.Context, tenantID string, req *models.IssueReviewIncentiveRequest) (*models.ReviewIncentiveResult, error) {
+	if existing, err := s.repo.GetLoyaltyTransactionByReference(ctx, tenantID, reviewIncentiveReference, req.ReviewID); err == nil {
+		return &models.ReviewIncentiveResult{
+			Type:          models.ReviewIncentivePoints,
+			Points:        existing.Points,
+			TransactionID: &existing.ID,
+			AlreadyIssued: true,
+		}, nil
+	}
+
+	program, err := s.repo.GetLoyaltyProgram(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loyalty program: %w", err)
+	}
+	if !program.IsActive {
+		return nil, fmt.Errorf("loyalty program is not active")
+	}
+
+	points := int(req.Value)
+	if points <= 0 {
+		points = program.ReviewBonus
+	}
+	if points <= 0 {
+		return nil, fmt.Errorf("no points configured for review incentives")
+	}
+
+	loyalty, err := s.repo.GetCustomerLoyalty(ctx, tenantID, req.CustomerID)
+	if err != nil {
+		loyalty, err = s.EnrollCustomer(ctx, tenantID, req.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	loyalty.TotalPoints += points
+	loyalty.AvailablePoints += points
+	loyalty.LifetimePoints += points
+	now := time.Now()
+	loyalty.LastEarned = &now
+
+	if err := s.repo.UpdateCustomerLoyalty(ctx, loyalty); err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.AddDate(0, 0, program.PointsExpiry)
+	reviewID := req.ReviewID
+	txn := &models.LoyaltyTransaction{
+		TenantID:      tenantID,
+		CustomerID:    req.CustomerID,
+		LoyaltyID:     loyalty.ID,
+		Type:          models.LoyaltyTxnBonus,
+		Points:        points,
+		Description:   fmt.Sprintf("Review reward #%s", reviewID.String()[:8]),
+		ReferenceID:   &reviewID,
+		ReferenceType: reviewIncentiveReference,
+		ExpiresAt:     &expiresAt,
+	}
+	if err := s.repo.CreateLoyaltyTransaction(ctx, txn); err != nil {
+		return nil, err
+	}
+
+	return &models.ReviewIncentiveResult{
+		Type:          models.ReviewIncentivePoints,
+		Points:        points,
+		TransactionID: &txn.ID,
+	}, nil
+}
+
+func (s *MarketingService) issueReviewCoupon(ctx context.Context, tenantID string, req *models.IssueReviewIncentiveRequest) (*models.ReviewIncentiveResult, error) {
+	// The code is derived from the review, so a repeated request finds the coupon already issued
+	code := "REVIEW-" + strings.ToUpper(strings.ReplaceAll(req.ReviewID.String(), "-", "")[:12])
+	if existing, err := s.repo.GetCouponByCode(ctx, tenantID, code); err == nil {
+		return &models.ReviewIncentiveResult{
+			Type:          models.ReviewIncentiveDiscount,
+			CouponCode:    existing.Code,
+			AlreadyIssued: true,
+		}, nil
+	}
+
+	if req.Value <= 0 || req.Value > 100 {
+		return nil, fmt.Errorf("discount must be between 0 and 100 percent")
+	}
+
+	now := time.Now()
+	coupon := &models.CouponCode{
+		TenantID:         tenantID,
+		Code:             code,
+		Name:             "Review reward",
+		Description:      fmt.Sprintf("Thank-you discount for review #%s", req.ReviewID.String()[:8]),
+		Type:             models.CouponTypePercentage,
+		DiscountValue:    req.Value,
+		MaxUsage:         1,
+		UsagePerCustomer: 1,
+		ValidFrom:        now,
+		ValidUntil:       now.Add(reviewCouponValidity),
+		IsActive:         true,
+		IsPublic:         false,
+	}
+	if err := s.CreateCoupon(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	return &models.ReviewIncentiveResult{
+		Type:       models.ReviewIncentiveDiscount,
+		CouponCode: coupon.Code,
+	}, nil
+}
+
 // ===== COUPONS =====
 
 // CreateCoupon creates a new coupon
@@ -951,13 +1104,26 @@ func (s *MarketingService) processCampaign(ctx context.Context, campaign *models
 		return
 	}
 
-	// Step 1: Sync campaign to Mautic (creates email template)
-	syncResult, err := s.mauticClient.SyncCampaign(ctx, campaign, s.fromEmail, s.fromName)
+	// Step 1: Sync campaign to Mautic (creates email template), with UTM parameters and
+	// open and click tracking added to the sent copy of the content
+	sendable := *campaign
+	if !campaign.DisableUTM {
+		applyUTMDefaults(campaign)
+		sendable.Content = AppendUTM(campaign.Content, campaignUTMParams(campaign))
+	}
+	if s.tracking != nil {
+		sendable.Content = s.tracking.InstrumentHTML(sendable.Content, campaign.TenantID, campaign.ID)
+	}
+	syncResult, err := s.mauticClient.SyncCampaign(ctx, &sendable, s.fromEmail, s.fromName)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to sync campaign to Mautic")
 		s.updateCampaignStatus(ctx, campaign, models.CampaignStatusPaused, "Mautic sync failed: "+err.Error())
 		return
 	}
+	if syncResult.MauticID != 0 {
+		mauticEmailID := syncResult.MauticID
+		campaign.MauticEmailID = &mauticEmailID
+	}
 
 	s.logger.WithField("mautic_email_id", syncResult.MauticID).Info("Campaign synced to Mautic")
 
@@ -978,14 +1144,27 @@ func (s *MarketingService) processCampaign(ctx context.Context, campaign *models
 		}
 	}
 
-	// Step 3: Send campaign via Mautic
+	// Step 3: Push the suppression list to Mautic so suppressed addresses are skipped
+	suppressed, err := s.enforceSuppressions(ctx, campaign.TenantID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to enforce suppression list")
+		s.updateCampaignStatus(ctx, campaign, models.CampaignStatusPaused, "Suppression sync failed: "+err.Error())
+		return
+	}
+	campaign.Suppressed = 0
+	for _, count := range suppressed {
+		campaign.Suppressed += count
+	}
+	campaign.SuppressionStats, _ = json.Marshal(suppressed)
+
+	// Step 4: Send campaign via Mautic
 	if err := s.mauticClient.SendCampaign(ctx, campaign, syncResult.MauticID, mauticSegmentID); err != nil {
 		s.logger.WithError(err).Error("Failed to send campaign via Mautic")
 		s.updateCampaignStatus(ctx, campaign, models.CampaignStatusPaused, "Mautic send failed: "+err.Error())
 		return
 	}
 
-	// Step 4: Update campaign status to sent
+	// Step 5: Update campaign status to sent
 	s.updateCampaignStatus(ctx, campaign, models.CampaignStatusSent, "")
 	s.logger.WithField("campaign_id", campaign.ID).Info("Campaign sent successfully via Mautic")
 }
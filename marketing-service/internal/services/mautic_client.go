@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -237,6 +238,26 @@ func (c *MauticClient) AddContactToSegment(ctx context.Context, segmentID, conta
 	return nil
 }
 
+// RemoveContactFromSegment removes a contact from a segment in Mautic
+func (c *MauticClient) RemoveContactFromSegment(ctx context.Context, segmentID, contactID int) error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	endpoint := fmt.Sprintf("/segments/%d/contact/%d/remove", segmentID, contactID)
+	_, err := c.doRequest(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to remove contact from segment: %w", err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"segmentId": segmentID,
+		"contactId": contactID,
+	}).Debug("Removed contact from segment in Mautic")
+
+	return nil
+}
+
 // ==================== EMAIL OPERATIONS ====================
 
 // CreateEmail creates a new email template in Mautic
@@ -352,7 +373,7 @@ func (c *MauticClient) GetContactByEmail(ctx context.Context, email string) (*Ma
 		return nil, nil
 	}
 
-	endpoint := fmt.Sprintf("/contacts?search=%s", email)
+	endpoint := fmt.Sprintf("/contacts?search=%s", url.QueryEscape(email))
 	respBody, err := c.doRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
@@ -384,6 +405,84 @@ func (c *MauticClient) GetContactByEmail(ctx context.Context, email string) (*Ma
 	return nil, nil
 }
 
+// DeleteContactByEmail deletes the Mautic contact with an address, if there is one
+func (c *MauticClient) DeleteContactByEmail(ctx context.Context, email string) error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	contact, err := c.GetContactByEmail(ctx, email)
+	if err != nil || contact == nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("/contacts/%d/delete", contact.ID)
+	if _, err := c.doRequest(ctx, "DELETE", endpoint, nil); err != nil {
+		return fmt.Errorf("failed to delete contact: %w", err)
+	}
+
+	c.logger.WithField("contactId", contact.ID).Debug("Deleted contact in Mautic")
+	return nil
+}
+
+// Mautic do-not-contact reasons
+const (
+	MauticDNCUnsubscribed = 1
+	MauticDNCBounced      = 2
+	MauticDNCManual       = 3
+)
+
+// AddDoNotContact marks an address do-not-contact on the email channel so Mautic skips it
+// in every segment send. The contact is created if Mautic doesn't know it yet, so the flag
+// is in place before the address is ever synced into a segment.
+func (c *MauticClient) AddDoNotContact(ctx context.Context, email string, reason int, comments string) error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	contact, err := c.GetContactByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	contactID := 0
+	if contact != nil {
+		contactID = contact.ID
+	} else {
+		contactID, err = c.CreateOrUpdateContact(ctx, &MauticContact{Email: email})
+		if err != nil {
+			return err
+		}
+	}
+
+	endpoint := fmt.Sprintf("/contacts/%d/dnc/email/add", contactID)
+	body := map[string]interface{}{
+		"reason":   reason,
+		"comments": comments,
+	}
+	if _, err := c.doRequest(ctx, "POST", endpoint, body); err != nil {
+		return fmt.Errorf("failed to add do-not-contact: %w", err)
+	}
+	return nil
+}
+
+// RemoveDoNotContact clears the email do-not-contact flag for an address
+func (c *MauticClient) RemoveDoNotContact(ctx context.Context, email string) error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	contact, err := c.GetContactByEmail(ctx, email)
+	if err != nil || contact == nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("/contacts/%d/dnc/email/remove", contact.ID)
+	if _, err := c.doRequest(ctx, "POST", endpoint, nil); err != nil {
+		return fmt.Errorf("failed to remove do-not-contact: %w", err)
+	}
+	return nil
+}
+
 // ==================== CAMPAIGN OPERATIONS ====================
 
 // SyncCampaign syncs a local campaign to Mautic (creates email template)
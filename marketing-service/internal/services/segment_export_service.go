@@ -0,0 +1,440 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"marketing-service/internal/models"
+)
+
+const (
+	// segmentAudiencePageSize is the members fetched from customers-service per request
+	segmentAudiencePageSize = 1000
+	// segmentExportSyncTimeout bounds one sync; a claim older than this is taken over
+	segmentExportSyncTimeout = time.Hour
+	// dueSegmentExportsBatch bounds the scheduled exports synced per scheduler tick
+	dueSegmentExportsBatch = 20
+)
+
+var (
+	// ErrInvalidSegmentExport is returned for an export that can't be created or synced as given
+	ErrInvalidSegmentExport = errors.New("invalid segment export")
+	// ErrAudiencePlatformDisabled is returned for a platform without configured credentials
+	ErrAudiencePlatformDisabled = errors.New("audience platform is not configured")
+	// ErrSegmentExportSyncing is returned when an export is already being synced
+	ErrSegmentExportSyncing = errors.New("segment export is already syncing")
+)
+
+// ===== SEGMENT EXPORTS =====
+
+// SetAudienceExports enables segment exports, reading members from customers-service.
+// Mautic uses the service's Mautic client; Meta and Google are enabled when configured.
+func (s *MarketingService) SetAudienceExports(customers *CustomersClient, meta *MetaAudienceClient, google *GoogleAdsClient) {
+	s.customersClient = customers
+	s.audiencePlatforms = map[models.AudiencePlatform]audiencePlatform{
+		models.AudiencePlatformMautic: &mauticAudience{client: s.mauticClient},
+		models.AudiencePlatformMeta:   meta,
+		models.AudiencePlatformGoogle: google,
+	}
+}
+
+// platformFor returns the enabled client for an export's platform
+func (s *MarketingService) platformFor(platform models.AudiencePlatform) (audiencePlatform, error) {
+	if !platform.IsValid() {
+		return nil, fmt.Errorf("%w: unknown platform %q", ErrInvalidSegmentExport, platform)
+	}
+	client, ok := s.audiencePlatforms[platform]
+	if !ok || !client.IsEnabled() || s.customersClient == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAudiencePlatformDisabled, platform)
+	}
+	return client, nil
+}
+
+// CreateSegmentExport creates an export of a customers-service segment to a platform
+// audience. Meta and Google audiences must already exist; a Mautic segment is created on
+// the first sync unless one is given.
+func (s *MarketingService) CreateSegmentExport(ctx context.Context, export *models.SegmentExport) error {
+	export.Platform = models.AudiencePlatform(strings.ToUpper(string(export.Platform)))
+	export.AccountID = strings.TrimSpace(export.AccountID)
+	export.AudienceID = strings.TrimSpace(export.AudienceID)
+	if export.SegmentID == uuid.Nil {
+		return fmt.Errorf("%w: segmentId is required", ErrInvalidSegmentExport)
+	}
+	if export.SyncIntervalHours < 0 {
+		return fmt.Errorf("%w: syncIntervalHours can't be negative", ErrInvalidSegmentExport)
+	}
+	if _, err := s.platformFor(export.Platform); err != nil {
+		return err
+	}
+	switch export.Platform {
+	case models.AudiencePlatformMeta:
+		if export.AudienceID == "" {
+			return fmt.Errorf("%w: audienceId (the custom audience ID) is required for Meta", ErrInvalidSegmentExport)
+		}
+	case models.AudiencePlatformGoogle:
+		if export.AccountID == "" || export.AudienceID == "" {
+			return fmt.Errorf("%w: accountId and audienceId (the user list ID) are required for Google", ErrInvalidSegmentExport)
+		}
+	}
+
+	// Checks the segment exists
+	if _, err := s.customersClient.GetSegmentAudience(ctx, export.TenantID, export.SegmentID, uuid.Nil, 1); err != nil {
+		return err
+	}
+
+	export.ID = uuid.Nil
+	export.Status = models.SegmentExportStatusIdle
+	export.SyncStartedAt = nil
+	export.LastSyncedAt = nil
+	export.LastError = ""
+	export.MemberCount = 0
+	export.NextSyncAt = nil
+	if export.SyncIntervalHours > 0 {
+		now := time.Now()
+		export.NextSyncAt = &now
+	}
+	return s.repo.CreateSegmentExport(ctx, export)
+}
+
+// GetSegmentExport retrieves a segment export
+func (s *MarketingService) GetSegmentExport(ctx context.Context, tenantID string, id uuid.UUID) (*models.SegmentExport, error) {
+	return s.repo.GetSegmentExport(ctx, tenantID, id)
+}
+
+// ListSegmentExports lists segment exports
+func (s *MarketingService) ListSegmentExports(ctx context.Context, filter *models.SegmentExportFilter) ([]*models.SegmentExport, int64, error) {
+	return s.repo.ListSegmentExports(ctx, filter)
+}
+
+// UpdateSegmentExport changes an export's name, schedule and whether it's active. The
+// platform and audience are fixed; create a new export to send a segment elsewhere.
+func (s *MarketingService) UpdateSegmentExport(ctx context.Context, tenantID string, id uuid.UUID, update *models.SegmentExportUpdate) (*models.SegmentExport, error) {
+	if update.SyncIntervalHours != nil && *update.SyncIntervalHours < 0 {
+		return nil, fmt.Errorf("%w: syncIntervalHours can't be negative", ErrInvalidSegmentExport)
+	}
+	export, err := s.repo.GetSegmentExport(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.SegmentName != nil {
+		export.SegmentName = strings.TrimSpace(*update.SegmentName)
+	}
+	if update.IsActive != nil {
+		export.IsActive = *update.IsActive
+	}
+	if update.SyncIntervalHours != nil && *update.SyncIntervalHours != export.SyncIntervalHours {
+		export.SyncIntervalHours = *update.SyncIntervalHours
+		export.NextSyncAt = nil
+		if export.SyncIntervalHours > 0 {
+			next := time.Now()
+			if export.LastSyncedAt != nil {
+				next = export.LastSyncedAt.Add(time.Duration(export.SyncIntervalHours) * time.Hour)
+			}
+			export.NextSyncAt = &next
+		}
+	}
+
+	if err := s.repo.UpdateSegmentExportSettings(ctx, export); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// DeleteSegmentExport stops exporting a segment. The platform audience is left as it is.
+func (s *MarketingService) DeleteSegmentExport(ctx context.Context, tenantID string, id uuid.UUID) error {
+	export, err := s.repo.GetSegmentExport(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if export.Status == models.SegmentExportStatusSyncing && export.SyncStartedAt != nil &&
+		time.Since(*export.SyncStartedAt) < segmentExportSyncTimeout {
+		return ErrSegmentExportSyncing
+	}
+	return s.repo.DeleteSegmentExport(ctx, tenantID, id)
+}
+
+// ListSegmentExportRuns lists the sync history of a segment's exports
+func (s *MarketingService) ListSegmentExportRuns(ctx context.Context, filter *models.SegmentExportFilter) ([]*models.SegmentExportRun, int64, error) {
+	return s.repo.ListSegmentExportRuns(ctx, filter)
+}
+
+// StartSegmentExportSync syncs an export in the background and returns its run
+func (s *MarketingService) StartSegmentExportSync(ctx context.Context, tenantID string, id uuid.UUID) (*models.SegmentExportRun, error) {
+	export, err := s.repo.GetSegmentExport(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.platformFor(export.Platform); err != nil {
+		return nil, err
+	}
+
+	run, err := s.claimSegmentExport(ctx, export, models.SegmentExportTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		syncCtx, cancel := context.WithTimeout(context.Background(), segmentExportSyncTimeout)
+		defer cancel()
+		s.runSegmentExport(syncCtx, export, run)
+	}()
+	return run, nil
+}
+
+// SyncDueSegmentExports syncs the scheduled exports that are due, across tenants. It
+// returns the number of exports synced.
+func (s *MarketingService) SyncDueSegmentExports(ctx context.Context) (int, error) {
+	if s.customersClient == nil {
+		return 0, nil
+	}
+	now := time.Now()
+	exports, err := s.repo.GetDueSegmentExports(ctx, now, now.Add(-segmentExportSyncTimeout), dueSegmentExportsBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due segment exports: %w", err)
+	}
+
+	synced := 0
+	for _, export := range exports {
+		// A platform that's no longer configured fails the run, which is recorded and
+		// moves the export's next sync on
+		run, err := s.claimSegmentExport(ctx, export, models.SegmentExportTriggerScheduled)
+		if err != nil {
+			if !errors.Is(err, ErrSegmentExportSyncing) {
+				s.logger.WithError(err).WithField("export_id", export.ID).Error("Failed to start scheduled segment export")
+			}
+			continue
+		}
+		syncCtx, cancel := context.WithTimeout(ctx, segmentExportSyncTimeout)
+		s.runSegmentExport(syncCtx, export, run)
+		cancel()
+		synced++
+	}
+	return synced, nil
+}
+
+// claimSegmentExport marks an export as syncing and records the run
+func (s *MarketingService) claimSegmentExport(ctx context.Context, export *models.SegmentExport, trigger models.SegmentExportTrigger) (*models.SegmentExportRun, error) {
+	now := time.Now()
+	claimed, err := s.repo.ClaimSegmentExport(ctx, export.TenantID, export.ID, now, now.Add(-segmentExportSyncTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim segment export: %w", err)
+	}
+	if !claimed {
+		return nil, ErrSegmentExportSyncing
+	}
+
+	run := &models.SegmentExportRun{
+		TenantID:  export.TenantID,
+		ExportID:  export.ID,
+		SegmentID: export.SegmentID,
+		Platform:  export.Platform,
+		Trigger:   trigger,
+		Status:    models.SegmentExportRunRunning,
+		StartedAt: now,
+	}
+	if err := s.repo.CreateSegmentExportRun(ctx, run); err != nil {
+		export.LastError = err.Error()
+		_ = s.repo.FinishSegmentExportSync(ctx, export)
+		return nil, fmt.Errorf("failed to create segment export run: %w", err)
+	}
+	return run, nil
+}
+
+// runSegmentExport syncs a claimed export, then records the outcome on the run and export
+func (s *MarketingService) runSegmentExport(ctx context.Context, export *models.SegmentExport, run *models.SegmentExportRun) {
+	syncErr := s.syncSegmentExport(ctx, export, run)
+
+	// The sync may have run out of time; the outcome is still saved
+	ctx = context.WithoutCancel(ctx)
+	now := time.Now()
+	run.CompletedAt = &now
+	if syncErr != nil {
+		run.Status = models.SegmentExportRunFailed
+		run.Error = syncErr.Error()
+		export.LastError = syncErr.Error()
+	} else {
+		run.Status = models.SegmentExportRunSucceeded
+		export.LastSyncedAt = &now
+		export.LastError = ""
+	}
+	if err := s.repo.UpdateSegmentExportRun(ctx, run); err != nil {
+		s.logger.WithError(err).WithField("run_id", run.ID).Error("Failed to save segment export run")
+	}
+
+	if count, err := s.repo.CountSegmentExportMembers(ctx, export.ID); err == nil {
+		export.MemberCount = count
+	}
+	if export.SyncIntervalHours > 0 {
+		next := now.Add(time.Duration(export.SyncIntervalHours) * time.Hour)
+		export.NextSyncAt = &next
+	}
+	if err := s.repo.FinishSegmentExportSync(ctx, export); err != nil {
+		s.logger.WithError(err).WithField("export_id", export.ID).Error("Failed to release segment export")
+	}
+
+	logger := s.logger.WithFields(logrus.Fields{
+		"tenant_id": export.TenantID,
+		"export_id": export.ID,
+		"platform":  export.Platform,
+		"added":     run.Added,
+		"updated":   run.Updated,
+		"removed":   run.Removed,
+		"unchanged": run.Unchanged,
+		"skipped":   run.Skipped,
+	})
+	if syncErr != nil {
+		logger.WithError(syncErr).Warn("Segment export failed")
+		return
+	}
+	logger.Info("Segment exported")
+}
+
+// syncSegmentExport compares the segment's members with those last exported and sends
+// the difference: new members are added, members who left (or were suppressed) are
+// removed, and members whose email or phone changed are removed and re-added. Member
+// records are saved after each batch the platform accepts, so a failed sync resumes
+// where it stopped.
+func (s *MarketingService) syncSegmentExport(ctx context.Context, export *models.SegmentExport, run *models.SegmentExportRun) error {
+	platform, err := s.platformFor(export.Platform)
+	if err != nil {
+		return err
+	}
+	audienceID := export.AudienceID
+	if err := platform.prepare(ctx, export); err != nil {
+		return err
+	}
+	if export.AudienceID != audienceID {
+		if err := s.repo.SetSegmentExportAudience(ctx, export.ID, export.AudienceID); err != nil {
+			return fmt.Errorf("failed to save platform audience: %w", err)
+		}
+	}
+
+	stored, err := s.repo.GetSegmentExportMembers(ctx, export.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get exported members: %w", err)
+	}
+	exported := make(map[uuid.UUID]*models.SegmentExportMember, len(stored))
+	for _, member := range stored {
+		exported[member.CustomerID] = member
+	}
+
+	var adds, removes []*audienceEntry
+	current := make(map[uuid.UUID]bool)
+	after := uuid.Nil
+	for {
+		page, err := s.customersClient.GetSegmentAudience(ctx, export.TenantID, export.SegmentID, after, segmentAudiencePageSize)
+		if err != nil {
+			return fmt.Errorf("failed to get segment members: %w", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		after = page[len(page)-1].CustomerID
+
+		emails := make([]string, 0, len(page))
+		for _, member := range page {
+			if email := normalizeAudienceEmail(member.Email); email != "" {
+				emails = append(emails, email)
+			}
+		}
+		suppressed, err := s.repo.GetSuppressedEmails(ctx, export.TenantID, emails)
+		if err != nil {
+			return fmt.Errorf("failed to check suppressions: %w", err)
+		}
+
+		for i := range page {
+			member := &page[i]
+			emailHash, phoneHash := platform.identify(*member)
+			if suppressed[normalizeAudienceEmail(member.Email)] || (emailHash == "" && phoneHash == "") {
+				run.Skipped++
+				continue
+			}
+			current[member.CustomerID] = true
+
+			entry := &audienceEntry{CustomerID: member.CustomerID, EmailHash: emailHash, PhoneHash: phoneHash, member: member}
+			previous, ok := exported[member.CustomerID]
+			switch {
+			case !ok:
+				adds = append(adds, entry)
+			case previous.EmailHash != emailHash || previous.PhoneHash != phoneHash:
+				entry.changed = true
+				removes = append(removes, exportedEntry(previous, true))
+				adds = append(adds, entry)
+			default:
+				run.Unchanged++
+			}
+		}
+		if len(page) < segmentAudiencePageSize {
+			break
+		}
+	}
+	for customerID, member := range exported {
+		if !current[customerID] {
+			removes = append(removes, exportedEntry(member, false))
+		}
+	}
+
+	size := platform.batchSize()
+	for start := 0; start < len(removes); start += size {
+		batch := removes[start:min(start+size, len(removes))]
+		if err := platform.remove(ctx, export, batch); err != nil {
+			return fmt.Errorf("failed to remove members from %s: %w", export.Platform, err)
+		}
+		customerIDs := make([]uuid.UUID, 0, len(batch))
+		for _, entry := range batch {
+			customerIDs = append(customerIDs, entry.CustomerID)
+			if !entry.changed {
+				run.Removed++
+			}
+		}
+		if err := s.repo.DeleteSegmentExportMembers(ctx, export.ID, customerIDs); err != nil {
+			return fmt.Errorf("failed to save removed members: %w", err)
+		}
+	}
+
+	for start := 0; start < len(adds); start += size {
+		batch := adds[start:min(start+size, len(adds))]
+		if err := platform.add(ctx, export, batch); err != nil {
+			return fmt.Errorf("failed to add members to %s: %w", export.Platform, err)
+		}
+		now := time.Now()
+		members := make([]*models.SegmentExportMember, 0, len(batch))
+		for _, entry := range batch {
+			members = append(members, &models.SegmentExportMember{
+				TenantID:   export.TenantID,
+				ExportID:   export.ID,
+				CustomerID: entry.CustomerID,
+				EmailHash:  entry.EmailHash,
+				PhoneHash:  entry.PhoneHash,
+				ExternalID: entry.ExternalID,
+				ExportedAt: now,
+			})
+			if entry.changed {
+				run.Updated++
+			} else {
+				run.Added++
+			}
+		}
+		if err := s.repo.UpsertSegmentExportMembers(ctx, members); err != nil {
+			return fmt.Errorf("failed to save added members: %w", err)
+		}
+	}
+	return nil
+}
+
+// exportedEntry is a stored member as sent for removal
+func exportedEntry(member *models.SegmentExportMember, changed bool) *audienceEntry {
+	return &audienceEntry{
+		CustomerID: member.CustomerID,
+		EmailHash:  member.EmailHash,
+		PhoneHash:  member.PhoneHash,
+		ExternalID: member.ExternalID,
+		changed:    changed,
+	}
+}
@@ -0,0 +1,384 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"marketing-service/internal/models"
+)
+
+// suppressionSyncBatchSize is the number of suppressions pushed to Mautic per batch
+const suppressionSyncBatchSize = 200
+
+// ErrInvalidSuppression is returned for an invalid address or reason
+var ErrInvalidSuppression = errors.New("invalid suppression")
+
+// ===== SUPPRESSION LIST =====
+
+// AddSuppression puts an address on the tenant's suppression list. An address that is
+// already suppressed keeps its entry unless the new reason is stronger (a complaint
+// replaces an unsubscribe, not the other way round). It reports whether anything changed.
+func (s *MarketingService) AddSuppression(ctx context.Context, suppression *models.Suppression) (bool, error) {
+	email, err := normalizeEmail(suppression.Email)
+	if err != nil {
+		return false, err
+	}
+	if !suppression.Reason.IsValid() {
+		return false, fmt.Errorf("%w: unknown reason %q", ErrInvalidSuppression, suppression.Reason)
+	}
+	suppression.Email = email
+
+	existing, err := s.repo.GetSuppressionByEmail(ctx, suppression.TenantID, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true, s.repo.CreateSuppression(ctx, suppression)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if suppression.Reason.Priority() <= existing.Reason.Priority() {
+		*suppression = *existing
+		return false, nil
+	}
+	existing.Reason = suppression.Reason
+	existing.Source = suppression.Source
+	existing.Details = suppression.Details
+	if suppression.CampaignID != nil {
+		existing.CampaignID = suppression.CampaignID
+	}
+	if suppression.CustomerID != nil {
+		existing.CustomerID = suppression.CustomerID
+	}
+	existing.ProviderSyncedAt = nil // Resync so Mautic records the new reason
+	if err := s.repo.UpdateSuppression(ctx, existing); err != nil {
+		return false, err
+	}
+	*suppression = *existing
+	return true, nil
+}
+
+// GetSuppression retrieves a suppression by ID
+func (s *MarketingService) GetSuppression(ctx context.Context, tenantID string, id uuid.UUID) (*models.Suppression, error) {
+	return s.repo.GetSuppression(ctx, tenantID, id)
+}
+
+// ListSuppressions retrieves suppressions with filters
+func (s *MarketingService) ListSuppressions(ctx context.Context, filter *models.SuppressionFilter) ([]*models.Suppression, int64, error) {
+	return s.repo.ListSuppressions(ctx, filter)
+}
+
+// RemoveSuppression takes an address off the suppression list and clears its
+// do-not-contact flag in Mautic
+func (s *MarketingService) RemoveSuppression(ctx context.Context, tenantID string, id uuid.UUID) error {
+	suppression, err := s.repo.GetSuppression(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteSuppression(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	if suppression.ProviderSyncedAt != nil && s.mauticClient != nil {
+		if err := s.mauticClient.RemoveDoNotContact(ctx, suppression.Email); err != nil {
+			s.logger.WithError(err).WithField("suppression_id", id).Warn("Failed to clear Mautic do-not-contact")
+		}
+	}
+	return nil
+}
+
+// ImportSuppressions adds addresses from a CSV with an email column and optional reason
+// and customer_id columns. A header row is required; reason defaults to MANUAL.
+func (s *MarketingService) ImportSuppressions(ctx context.Context, tenantID string, r io.Reader) (*models.SuppressionImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header row", ErrInvalidSuppression)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	emailCol, ok := columns["email"]
+	if !ok {
+		return nil, fmt.Errorf("%w: header has no email column", ErrInvalidSuppression)
+	}
+	column := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	result := &models.SuppressionImportResult{}
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, models.SuppressionImportRow{Row: row, Error: err.Error()})
+			continue
+		}
+		if emailCol >= len(record) || strings.TrimSpace(record[emailCol]) == "" {
+			result.Skipped++
+			continue
+		}
+
+		suppression := &models.Suppression{
+			TenantID: tenantID,
+			Email:    record[emailCol],
+			Reason:   models.SuppressionReasonManual,
+			Source:   models.SuppressionSourceImport,
+		}
+		if reason := column(record, "reason"); reason != "" {
+			suppression.Reason = models.SuppressionReason(strings.ToUpper(reason))
+		}
+		if customerID := column(record, "customer_id"); customerID != "" {
+			parsed, err := uuid.Parse(customerID)
+			if err != nil {
+				result.Errors = append(result.Errors, models.SuppressionImportRow{Row: row, Error: "invalid customer_id"})
+				continue
+			}
+			suppression.CustomerID = &parsed
+		}
+
+		existing, _ := s.repo.GetSuppressionByEmail(ctx, tenantID, strings.ToLower(strings.TrimSpace(suppression.Email)))
+		changed, err := s.AddSuppression(ctx, suppression)
+		switch {
+		case err != nil:
+			result.Errors = append(result.Errors, models.SuppressionImportRow{Row: row, Error: err.Error()})
+		case !changed:
+			result.Skipped++
+		case existing != nil:
+			result.Updated++
+		default:
+			result.Imported++
+		}
+	}
+
+	return result, nil
+}
+
+// ExportSuppressions writes the tenant's suppression list as CSV
+func (s *MarketingService) ExportSuppressions(ctx context.Context, tenantID string, w io.Writer) error {
+	suppressions, _, err := s.repo.ListSuppressions(ctx, &models.SuppressionFilter{TenantID: tenantID})
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"email", "reason", "source", "customer_id", "campaign_id", "details", "created_at"}); err != nil {
+		return err
+	}
+	for _, sup := range suppressions {
+		customerID, campaignID := "", ""
+		if sup.CustomerID != nil {
+			customerID = sup.CustomerID.String()
+		}
+		if sup.CampaignID != nil {
+			campaignID = sup.CampaignID.String()
+		}
+		record := []string{sup.Email, string(sup.Reason), string(sup.Source), customerID, campaignID, sup.Details, sup.CreatedAt.Format(time.RFC3339)}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// GetSuppressionStats counts the tenant's suppressions by reason and source
+func (s *MarketingService) GetSuppressionStats(ctx context.Context, tenantID string) (map[string]interface{}, error) {
+	byReason, err := s.repo.CountSuppressionsByReason(ctx, tenantID, nil)
+	if err != nil {
+		return nil, err
+	}
+	bySource, err := s.repo.CountSuppressionsBySource(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, count := range byReason {
+		total += count
+	}
+	return map[string]interface{}{
+		"total":    total,
+		"byReason": byReason,
+		"bySource": bySource,
+	}, nil
+}
+
+// GetCampaignSuppressionStats reports how many addresses a campaign's send excluded and
+// how many suppressions the campaign itself caused
+func (s *MarketingService) GetCampaignSuppressionStats(ctx context.Context, tenantID string, campaignID uuid.UUID) (*models.CampaignSuppressionStats, error) {
+	campaign, err := s.repo.GetCampaign(ctx, tenantID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.CampaignSuppressionStats{
+		CampaignID:               campaign.ID,
+		SuppressedAtSend:         campaign.Suppressed,
+		SuppressedAtSendByReason: map[string]int64{},
+	}
+	if len(campaign.SuppressionStats) > 0 {
+		_ = json.Unmarshal(campaign.SuppressionStats, &stats.SuppressedAtSendByReason)
+	}
+
+	stats.NewSuppressionsByReason, err = s.repo.CountSuppressionsByReason(ctx, tenantID, &campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, count := range stats.NewSuppressionsByReason {
+		stats.NewSuppressions += count
+	}
+	return stats, nil
+}
+
+// ProcessEmailProviderEvent records a generic or Postal provider event. It reports
+// whether the address was newly suppressed.
+func (s *MarketingService) ProcessEmailProviderEvent(ctx context.Context, tenantID string, event *models.EmailProviderEvent) (bool, error) {
+	engagement := classifyEmailProviderEvent(event)
+	if engagement == nil {
+		return false, nil
+	}
+	engagement.TenantID = tenantID
+	return s.RecordEngagement(ctx, engagement)
+}
+
+// classifyEmailProviderEvent maps a provider event to an engagement event, or nil for
+// events that aren't tracked
+func classifyEmailProviderEvent(event *models.EmailProviderEvent) *models.EngagementEvent {
+	engagement := &models.EngagementEvent{
+		Email:           event.Email,
+		Details:         event.Reason,
+		URL:             event.URL,
+		Provider:        models.EngagementProviderGeneric,
+		ProviderEventID: event.EventID,
+	}
+	if campaignID, err := uuid.Parse(event.CampaignID); err == nil {
+		engagement.CampaignID = &campaignID
+	}
+
+	switch strings.ToLower(event.Event) {
+	case "sent", "processed":
+		engagement.Type = models.EngagementSent
+		return engagement
+	case "delivered", "delivery":
+		engagement.Type = models.EngagementDelivered
+		return engagement
+	case "open", "opened":
+		engagement.Type = models.EngagementOpened
+		return engagement
+	case "click", "clicked":
+		engagement.Type = models.EngagementClicked
+		return engagement
+	case "bounce", "bounced", "hard_bounce":
+		bounceType := strings.ToLower(event.BounceType)
+		engagement.Type = models.EngagementBounced
+		engagement.HardBounce = bounceType != "soft" && bounceType != "transient"
+		return engagement
+	case "complaint", "spam_complaint", "spamreport":
+		engagement.Type = models.EngagementComplained
+		return engagement
+	case "unsubscribe", "unsubscribed":
+		engagement.Type = models.EngagementUnsubscribed
+		return engagement
+	}
+
+	// Postal, which delivers Mautic's email
+	if event.Payload == nil {
+		return nil
+	}
+	engagement.Provider = models.EngagementProviderPostal
+	engagement.ProviderEventID = event.UUID
+	engagement.Details = event.Payload.Details
+	switch event.Event {
+	case "MessageSent":
+		engagement.Type = models.EngagementDelivered
+	case "MessageLoaded":
+		engagement.Type = models.EngagementOpened
+	case "MessageLinkClicked":
+		engagement.Type = models.EngagementClicked
+		engagement.URL = event.Payload.URL
+	case "MessageBounced":
+		if event.Payload.OriginalMessage == nil {
+			return nil
+		}
+		engagement.Type = models.EngagementBounced
+		engagement.HardBounce = true
+		engagement.Email = event.Payload.OriginalMessage.To
+		engagement.Details = "Bounce received"
+		return engagement
+	case "MessageDeliveryFailed":
+		engagement.Type = models.EngagementBounced
+		engagement.HardBounce = event.Payload.Status == "HardFail"
+	default:
+		return nil
+	}
+	if event.Payload.Message == nil {
+		return nil
+	}
+	engagement.Email = event.Payload.Message.To
+	return engagement
+}
+
+// enforceSuppressions pushes suppressions Mautic doesn't know about yet as do-not-contact
+// flags, so the send that follows skips them, and returns the tenant's suppression counts
+// by reason. Sends must not go ahead when this fails.
+func (s *MarketingService) enforceSuppressions(ctx context.Context, tenantID string) (map[string]int64, error) {
+	for {
+		pending, err := s.repo.GetUnsyncedSuppressions(ctx, tenantID, suppressionSyncBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, sup := range pending {
+			if err := s.mauticClient.AddDoNotContact(ctx, sup.Email, mauticDNCReason(sup.Reason), string(sup.Reason)); err != nil {
+				return nil, fmt.Errorf("failed to sync suppression %s: %w", sup.ID, err)
+			}
+			if err := s.repo.MarkSuppressionSynced(ctx, sup.ID, time.Now()); err != nil {
+				return nil, err
+			}
+		}
+		if len(pending) < suppressionSyncBatchSize {
+			break
+		}
+	}
+
+	return s.repo.CountSuppressionsByReason(ctx, tenantID, nil)
+}
+
+// mauticDNCReason maps a suppression reason to Mautic's do-not-contact reason
+func mauticDNCReason(reason models.SuppressionReason) int {
+	switch reason {
+	case models.SuppressionReasonHardBounce:
+		return MauticDNCBounced
+	case models.SuppressionReasonSpamComplaint, models.SuppressionReasonUnsubscribe:
+		return MauticDNCUnsubscribed
+	}
+	return MauticDNCManual
+}
+
+// normalizeEmail validates an address and lower-cases it for matching
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", fmt.Errorf("%w: invalid email %q", ErrInvalidSuppression, email)
+	}
+	return email, nil
+}
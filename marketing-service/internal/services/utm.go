@@ -0,0 +1,67 @@
+package services
+
+import (
+	"html"
+	"net/url"
+	"strings"
+
+	"marketing-service/internal/models"
+)
+
+// applyUTMDefaults fills in a campaign's UTM parameters that weren't set: the campaign
+// type as source, the channel as medium and the campaign ID as campaign, which is what
+// conversions are matched on
+func applyUTMDefaults(campaign *models.Campaign) {
+	if campaign.UTMSource == "" {
+		campaign.UTMSource = strings.ToLower(string(campaign.Type))
+	}
+	if campaign.UTMMedium == "" {
+		campaign.UTMMedium = strings.ToLower(string(campaign.Channel))
+	}
+	if campaign.UTMCampaign == "" {
+		campaign.UTMCampaign = campaign.ID.String()
+	}
+}
+
+// campaignUTMParams returns the UTM query parameters for a campaign's links
+func campaignUTMParams(campaign *models.Campaign) url.Values {
+	params := url.Values{}
+	params.Set("utm_source", campaign.UTMSource)
+	params.Set("utm_medium", campaign.UTMMedium)
+	params.Set("utm_campaign", campaign.UTMCampaign)
+	return params
+}
+
+// AppendUTM adds UTM parameters to the http(s) links in campaign HTML. Links that already
+// carry UTM parameters or contain Mautic tokens are left as they are.
+func AppendUTM(content string, params url.Values) string {
+	if content == "" || len(params) == 0 {
+		return content
+	}
+	encoded := params.Encode()
+
+	return trackedLinkPattern.ReplaceAllStringFunc(content, func(match string) string {
+		parts := trackedLinkPattern.FindStringSubmatch(match)
+		target := html.UnescapeString(parts[2])
+		if strings.Contains(target, "{") {
+			return match
+		}
+		u, err := url.Parse(target)
+		if err != nil {
+			return match
+		}
+		for key := range u.Query() {
+			if strings.HasPrefix(strings.ToLower(key), "utm_") {
+				return match
+			}
+		}
+
+		// Appended rather than re-encoded so the link's own query is untouched
+		if u.RawQuery == "" {
+			u.RawQuery = encoded
+		} else {
+			u.RawQuery += "&" + encoded
+		}
+		return parts[1] + html.EscapeString(u.String()) + parts[3]
+	})
+}
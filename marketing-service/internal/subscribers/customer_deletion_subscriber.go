@@ -0,0 +1,111 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"marketing-service/internal/events"
+	"marketing-service/internal/services"
+)
+
+// AnonymizationRequestedSubject is published by customers-service when a deleted customer's
+// cooling-off period ends
+const AnonymizationRequestedSubject = "customer.anonymization_requested"
+
+// CustomerDeletionSubscriber removes the marketing data of customers who deleted their
+// account and confirms back to customers-service
+type CustomerDeletionSubscriber struct {
+	subscriber *gosharedevents.Subscriber
+	service    *services.MarketingService
+	publisher  *events.Publisher
+	logger     *logrus.Entry
+	cancel     context.CancelFunc
+}
+
+// NewCustomerDeletionSubscriber creates a new customer deletion subscriber
+func NewCustomerDeletionSubscriber(service *services.MarketingService, publisher *events.Publisher, logger *logrus.Logger) (*CustomerDeletionSubscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	config := gosharedevents.DefaultSubscriberConfig(natsURL, "marketing-service-customer-deletion")
+	config.Name = "marketing-service-customer-deletion"
+	config.MaxDeliver = 10
+	config.AckWait = 60 * time.Second
+
+	subscriber, err := gosharedevents.NewSubscriber(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CustomerDeletionSubscriber{
+		subscriber: subscriber,
+		service:    service,
+		publisher:  publisher,
+		logger:     logger.WithField("component", "customer-deletion-subscriber"),
+	}, nil
+}
+
+// Start starts listening for anonymization requests
+func (s *CustomerDeletionSubscriber) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	subjects := []string{AnonymizationRequestedSubject}
+	if err := s.subscriber.Subscribe(ctx, gosharedevents.StreamCustomers, subjects, s.handleAnonymizationRequested); err != nil {
+		return err
+	}
+
+	s.logger.WithField("subjects", subjects).Info("Customer deletion subscriber started successfully")
+	return nil
+}
+
+// handleAnonymizationRequested removes the customer's marketing data. Anonymizing is
+// idempotent, so requests customers-service re-sends are handled the same way.
+func (s *CustomerDeletionSubscriber) handleAnonymizationRequested(ctx context.Context, msg *gosharedevents.Message) error {
+	var event gosharedevents.CustomerEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		s.logger.WithError(err).Error("Failed to unmarshal anonymization request")
+		return nil // Don't redeliver malformed messages
+	}
+
+	requestID, _ := event.Metadata["requestId"].(string)
+	anonymizedEmail, _ := event.Metadata["anonymizedEmail"].(string)
+	customerID, err := uuid.Parse(event.CustomerID)
+	if event.TenantID == "" || requestID == "" || anonymizedEmail == "" || err != nil {
+		s.logger.Warn("Skipping anonymization request without tenant, customer, request ID or placeholder email")
+		return nil
+	}
+
+	if err := s.service.AnonymizeCustomer(ctx, event.TenantID, customerID, event.CustomerEmail); err != nil {
+		return err
+	}
+
+	if err := s.publisher.PublishCustomerAnonymized(ctx, event.TenantID, event.CustomerID, anonymizedEmail, requestID); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"tenantID":   event.TenantID,
+		"customerID": event.CustomerID,
+	}).Info("Anonymized marketing data of deleted customer")
+	return nil
+}
+
+// Stop stops the subscriber
+func (s *CustomerDeletionSubscriber) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.subscriber != nil {
+		s.subscriber.Close()
+	}
+	s.logger.Info("Customer deletion subscriber stopped")
+}
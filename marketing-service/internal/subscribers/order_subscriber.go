@@ -0,0 +1,138 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"marketing-service/internal/models"
+	"marketing-service/internal/services"
+)
+
+// OrderSubscriber attributes orders to campaigns from the UTM touches the storefront
+// captured, carried in order event metadata, and takes cancelled and refunded orders
+// back off
+type OrderSubscriber struct {
+	subscriber *gosharedevents.Subscriber
+	service    *services.MarketingService
+	logger     *logrus.Entry
+	cancel     context.CancelFunc
+}
+
+// NewOrderSubscriber creates a new order event subscriber
+func NewOrderSubscriber(service *services.MarketingService, logger *logrus.Logger) (*OrderSubscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	config := gosharedevents.DefaultSubscriberConfig(natsURL, "marketing-service-attribution")
+	config.Name = "marketing-service-attribution"
+	config.MaxDeliver = 5
+	config.AckWait = 30 * time.Second
+
+	subscriber, err := gosharedevents.NewSubscriber(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderSubscriber{
+		subscriber: subscriber,
+		service:    service,
+		logger:     logger.WithField("component", "order-subscriber"),
+	}, nil
+}
+
+// Start starts listening for order events
+func (s *OrderSubscriber) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	subjects := []string{gosharedevents.OrderCreated, gosharedevents.OrderCancelled, gosharedevents.OrderRefunded}
+	if err := s.subscriber.Subscribe(ctx, gosharedevents.StreamOrders, subjects, s.handleOrderEvent); err != nil {
+		return err
+	}
+
+	s.logger.WithField("subjects", subjects).Info("Order attribution subscriber started successfully")
+	return nil
+}
+
+// handleOrderEvent records, cancels or refunds an order's campaign attribution
+func (s *OrderSubscriber) handleOrderEvent(ctx context.Context, msg *gosharedevents.Message) error {
+	var event gosharedevents.OrderEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		s.logger.WithError(err).Error("Failed to unmarshal order event")
+		return nil // Don't redeliver malformed messages
+	}
+	if event.TenantID == "" || event.OrderID == "" {
+		return nil
+	}
+
+	switch msg.Subject {
+	case gosharedevents.OrderCancelled:
+		return s.service.CancelOrderConversion(ctx, event.TenantID, event.OrderID)
+	case gosharedevents.OrderRefunded:
+		return s.service.RefundOrderConversion(ctx, event.TenantID, event.OrderID, event.RefundAmount)
+	}
+
+	attribution, ok := orderAttribution(event.Metadata)
+	if !ok {
+		return nil
+	}
+	order := &models.OrderConversion{
+		TenantID:    event.TenantID,
+		OrderID:     event.OrderID,
+		OrderNumber: event.OrderNumber,
+		Total:       event.TotalAmount,
+		Currency:    event.Currency,
+		OrderedAt:   orderTime(event, msg.Timestamp),
+		Attribution: attribution,
+	}
+	if customerID, err := uuid.Parse(event.CustomerID); err == nil && customerID != uuid.Nil {
+		order.CustomerID = &customerID
+	}
+	return s.service.RecordOrderConversion(ctx, order)
+}
+
+// Stop stops the subscriber
+func (s *OrderSubscriber) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.subscriber != nil {
+		s.subscriber.Close()
+	}
+	s.logger.Info("Order attribution subscriber stopped")
+}
+
+// orderAttribution reads the UTM touches orders-service puts in metadata.attribution
+func orderAttribution(metadata map[string]interface{}) (models.OrderAttribution, bool) {
+	var attribution models.OrderAttribution
+	raw, ok := metadata["attribution"]
+	if !ok {
+		return attribution, false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil || json.Unmarshal(data, &attribution) != nil {
+		return attribution, false
+	}
+	return attribution, attribution.FirstTouch != nil || attribution.LastTouch != nil
+}
+
+func orderTime(event gosharedevents.OrderEvent, messageTimestamp time.Time) time.Time {
+	if t, err := time.Parse(time.RFC3339, event.OrderDate); err == nil {
+		return t.UTC()
+	}
+	if !event.Timestamp.IsZero() {
+		return event.Timestamp.UTC()
+	}
+	if !messageTimestamp.IsZero() {
+		return messageTimestamp.UTC()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_loyalty_txn_reference;
+ALTER TABLE loyalty_programs DROP COLUMN IF EXISTS review_bonus;
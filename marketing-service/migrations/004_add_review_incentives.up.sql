@@ -0,0 +1,5 @@
+-- Loyalty points awarded for approved incentivized reviews (reviews-service)
+ALTER TABLE loyalty_programs ADD COLUMN IF NOT EXISTS review_bonus INTEGER DEFAULT 0;
+
+-- Review rewards are looked up by the review they were issued for
+CREATE INDEX IF NOT EXISTS idx_loyalty_txn_reference ON loyalty_transactions(tenant_id, reference_type, reference_id);
@@ -0,0 +1,4 @@
+ALTER TABLE campaigns DROP COLUMN IF EXISTS suppression_stats;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS suppressed;
+
+DROP TABLE IF EXISTS suppressions;
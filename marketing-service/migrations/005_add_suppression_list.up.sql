@@ -0,0 +1,22 @@
+-- Tenant suppression list: addresses that must not receive marketing email
+CREATE TABLE IF NOT EXISTS suppressions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    customer_id UUID,
+    reason VARCHAR(50) NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    campaign_id UUID,
+    details TEXT,
+    provider_synced_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suppressions_tenant_email ON suppressions(tenant_id, email);
+CREATE INDEX IF NOT EXISTS idx_suppressions_customer ON suppressions(customer_id);
+CREATE INDEX IF NOT EXISTS idx_suppressions_campaign ON suppressions(campaign_id);
+
+-- Addresses excluded by the suppression list when a campaign was sent
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS suppressed BIGINT DEFAULT 0;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS suppression_stats JSONB;
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS campaign_engagement_events;
+
+DROP INDEX IF EXISTS idx_campaigns_mautic_email;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS mautic_email_id;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS complained;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS bounced;
+
+DROP INDEX IF EXISTS idx_recipients_campaign_email;
+ALTER TABLE campaign_recipients DROP COLUMN IF EXISTS last_event_at;
+ALTER TABLE campaign_recipients DROP COLUMN IF EXISTS click_count;
+ALTER TABLE campaign_recipients DROP COLUMN IF EXISTS open_count;
+ALTER TABLE campaign_recipients DROP COLUMN IF EXISTS complained_at;
+ALTER TABLE campaign_recipients DROP COLUMN IF EXISTS bounced_at;
+ALTER TABLE campaign_recipients DROP COLUMN IF EXISTS email;
+DELETE FROM campaign_recipients WHERE customer_id IS NULL;
+ALTER TABLE campaign_recipients ALTER COLUMN customer_id SET NOT NULL;
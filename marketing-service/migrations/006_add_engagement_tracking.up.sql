@@ -0,0 +1,43 @@
+-- Per-recipient engagement records, keyed by address so provider events can be matched
+ALTER TABLE campaign_recipients ALTER COLUMN customer_id DROP NOT NULL;
+ALTER TABLE campaign_recipients ADD COLUMN IF NOT EXISTS email VARCHAR(255);
+ALTER TABLE campaign_recipients ADD COLUMN IF NOT EXISTS bounced_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE campaign_recipients ADD COLUMN IF NOT EXISTS complained_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE campaign_recipients ADD COLUMN IF NOT EXISTS open_count INTEGER DEFAULT 0;
+ALTER TABLE campaign_recipients ADD COLUMN IF NOT EXISTS click_count INTEGER DEFAULT 0;
+ALTER TABLE campaign_recipients ADD COLUMN IF NOT EXISTS last_event_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipients_campaign_email ON campaign_recipients(campaign_id, email) WHERE email <> '';
+
+-- Campaign counters and the Mautic email used to attribute Mautic webhook events
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS bounced BIGINT DEFAULT 0;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS complained BIGINT DEFAULT 0;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS mautic_email_id INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_mautic_email ON campaigns(mautic_email_id);
+
+-- Raw delivery and engagement events from the tracking endpoints and provider webhooks
+CREATE TABLE IF NOT EXISTS campaign_engagement_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(100) NOT NULL,
+    campaign_id UUID,
+    recipient_id UUID,
+    customer_id UUID,
+    email VARCHAR(255),
+    type VARCHAR(20) NOT NULL,
+    hard_bounce BOOLEAN DEFAULT FALSE,
+    url TEXT,
+    provider VARCHAR(20) NOT NULL,
+    provider_event_id VARCHAR(255),
+    details TEXT,
+    user_agent VARCHAR(500),
+    ip_address VARCHAR(45),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_engagement_tenant_time ON campaign_engagement_events(tenant_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_engagement_campaign ON campaign_engagement_events(campaign_id);
+CREATE INDEX IF NOT EXISTS idx_engagement_recipient ON campaign_engagement_events(recipient_id);
+-- Provider redeliveries are dropped
+CREATE UNIQUE INDEX IF NOT EXISTS idx_engagement_provider_event ON campaign_engagement_events(tenant_id, provider, provider_event_id) WHERE provider_event_id <> '';
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS campaign_conversions;
+
+DROP INDEX IF EXISTS idx_campaigns_utm_campaign;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS disable_utm;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS utm_campaign;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS utm_medium;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS utm_source;
@@ -0,0 +1,35 @@
+-- UTM parameters appended to campaign links; utm_campaign defaults to the campaign ID
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS utm_source VARCHAR(255);
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS utm_medium VARCHAR(255);
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS utm_campaign VARCHAR(255);
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS disable_utm BOOLEAN DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_utm_campaign ON campaigns(utm_campaign);
+
+-- Orders attributed to campaigns, one row per order and attribution model
+CREATE TABLE IF NOT EXISTS campaign_conversions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(100) NOT NULL,
+    campaign_id UUID NOT NULL,
+    model VARCHAR(20) NOT NULL,
+    order_id VARCHAR(100) NOT NULL,
+    order_number VARCHAR(100),
+    customer_id UUID,
+    revenue DECIMAL(15,2) NOT NULL,
+    refunded_amount DECIMAL(15,2) DEFAULT 0,
+    currency VARCHAR(3),
+    status VARCHAR(20) NOT NULL DEFAULT 'ATTRIBUTED',
+    utm_source VARCHAR(255),
+    utm_medium VARCHAR(255),
+    utm_campaign VARCHAR(255),
+    utm_content VARCHAR(255),
+    utm_term VARCHAR(255),
+    touched_at TIMESTAMP WITH TIME ZONE,
+    ordered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_conversions_order_model ON campaign_conversions(tenant_id, order_id, model);
+CREATE INDEX IF NOT EXISTS idx_conversions_campaign ON campaign_conversions(campaign_id);
+CREATE INDEX IF NOT EXISTS idx_conversions_ordered ON campaign_conversions(ordered_at);
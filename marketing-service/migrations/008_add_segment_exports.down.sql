@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS segment_export_runs;
+DROP TABLE IF EXISTS segment_export_members;
+DROP TABLE IF EXISTS segment_exports;
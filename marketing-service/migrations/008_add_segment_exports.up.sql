@@ -0,0 +1,62 @@
+-- Customers-service segments kept in sync with Mautic, Meta and Google audiences
+CREATE TABLE IF NOT EXISTS segment_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(100) NOT NULL,
+    segment_id UUID NOT NULL,
+    segment_name VARCHAR(255),
+    platform VARCHAR(20) NOT NULL,
+    account_id VARCHAR(100),
+    audience_id VARCHAR(100),
+    sync_interval_hours INTEGER DEFAULT 0,
+    next_sync_at TIMESTAMP WITH TIME ZONE,
+    is_active BOOLEAN DEFAULT TRUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'IDLE',
+    sync_started_at TIMESTAMP WITH TIME ZONE,
+    last_synced_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    member_count BIGINT DEFAULT 0,
+    created_by VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_segment_exports_tenant ON segment_exports(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_segment_exports_segment ON segment_exports(segment_id);
+CREATE INDEX IF NOT EXISTS idx_segment_exports_next_sync ON segment_exports(next_sync_at);
+
+-- Members last exported, by hashes of the identifiers sent (no PII stored)
+CREATE TABLE IF NOT EXISTS segment_export_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(100) NOT NULL,
+    export_id UUID NOT NULL,
+    customer_id UUID NOT NULL,
+    email_hash VARCHAR(64),
+    phone_hash VARCHAR(64),
+    external_id VARCHAR(100),
+    exported_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_segment_export_members_customer ON segment_export_members(export_id, customer_id);
+
+-- Export history: one row per sync
+CREATE TABLE IF NOT EXISTS segment_export_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(100) NOT NULL,
+    export_id UUID NOT NULL,
+    segment_id UUID NOT NULL,
+    platform VARCHAR(20) NOT NULL,
+    trigger VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    added INTEGER DEFAULT 0,
+    updated INTEGER DEFAULT 0,
+    removed INTEGER DEFAULT 0,
+    unchanged INTEGER DEFAULT 0,
+    skipped INTEGER DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_segment_export_runs_tenant ON segment_export_runs(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_segment_export_runs_export ON segment_export_runs(export_id);
+CREATE INDEX IF NOT EXISTS idx_segment_export_runs_segment ON segment_export_runs(segment_id);
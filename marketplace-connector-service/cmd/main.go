@@ -3,15 +3,17 @@ package main
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"marketplace-connector-service/internal/config"
 	"marketplace-connector-service/internal/database"
 	"marketplace-connector-service/internal/handlers"
 	"marketplace-connector-service/internal/middleware"
-	"marketplace-connector-service/internal/models"
 	"marketplace-connector-service/internal/repository"
 	"marketplace-connector-service/internal/secrets"
 	"marketplace-connector-service/internal/services"
@@ -22,26 +24,25 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Connect to database
-	db, err := database.Connect(cfg.DatabaseURL, cfg.Environment)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return database.Open(cfg.DatabaseURL, cfg.Environment) })
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Auto-migrate models
-	if err := db.AutoMigrate(
-		&models.MarketplaceConnection{},
-		&models.MarketplaceCredentials{},
-		&models.MarketplaceSyncJob{},
-		&models.MarketplaceSyncLog{},
-		&models.MarketplaceProductMapping{},
-		&models.MarketplaceOrderMapping{},
-		&models.MarketplaceInventoryMapping{},
-		&models.MarketplaceWebhookEvent{},
-	); err != nil {
-		log.Printf("Warning: Auto-migration failed: %v", err)
+	// "marketplace-connector-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
 	}
-	log.Println("Database models migrated")
 
 	// Initialize GCP Secret Manager
 	var secretManager *secrets.GCPSecretManager
@@ -87,10 +88,35 @@ func main() {
 	router := setupRouter(cfg, db, healthHandler, connectionHandler, syncHandler, webhookHandler, catalogHandler, inventoryHandler, apiKeyHandler, auditHandler)
 
 	// Start server
-	log.Printf("Marketplace Connector Service starting on port %s (env: %s)", cfg.Port, cfg.Environment)
-	if err := router.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: router,
+	}
+	go func() {
+		log.Printf("Marketplace Connector Service starting on port %s (env: %s)", cfg.Port, cfg.Environment)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down marketplace-connector-service...")
+
+	// Stop accepting connections and let in-flight requests finish
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
 	}
+	log.Println("Marketplace Connector Service stopped")
 }
 
 // setupRouter configures the HTTP router
@@ -13,8 +13,9 @@ import (
 // Config holds all configuration for the marketplace connector service
 type Config struct {
 	// Server
-	Port        string
-	Environment string
+	Port            string
+	Environment     string
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
 
 	// Database
 	DatabaseURL string
@@ -57,9 +58,10 @@ func Load() *Config {
 	}
 
 	config := &Config{
-		Port:        getEnv("PORT", "8099"),
-		Environment: getEnv("ENVIRONMENT", "development"),
-		DatabaseURL: databaseURL,
+		Port:            getEnv("PORT", "8099"),
+		Environment:     getEnv("ENVIRONMENT", "development"),
+		ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+		DatabaseURL:     databaseURL,
 
 		// GCP
 		GCPProjectID: getEnv("GCP_PROJECT_ID", ""),
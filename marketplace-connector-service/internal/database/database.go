@@ -7,10 +7,12 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+
+	"marketplace-connector-service/internal/tenancy"
 )
 
-// Connect establishes a connection to the PostgreSQL database
-func Connect(databaseURL string, environment string) (*gorm.DB, error) {
+// Open establishes a connection to the PostgreSQL database
+func Open(databaseURL string, environment string) (*gorm.DB, error) {
 	logLevel := logger.Info
 	if environment == "production" {
 		logLevel = logger.Silent
@@ -23,6 +25,11 @@ func Connect(databaseURL string, environment string) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
+	}
+
 	// Test connection
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"gorm.io/gorm"
+
+	"marketplace-connector-service/internal/models"
+)
+
+// serviceName names the service in the migration lock key and log messages
+const serviceName = "marketplace-connector-service"
+
+// BaselineVersion is the last SQL migration folded into the model schema. Databases that
+// predate versioned migrations were built by AutoMigrate, and new databases get the same
+// schema from SyncModels, so only files numbered after it are applied from SQL.
+const BaselineVersion = 3
+
+// SyncModels brings the schema in line with the GORM models. It must only run while holding
+// the migration lock.
+func SyncModels(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&models.MarketplaceConnection{},
+		&models.MarketplaceCredentials{},
+		&models.MarketplaceSyncJob{},
+		&models.MarketplaceSyncLog{},
+		&models.MarketplaceProductMapping{},
+		&models.MarketplaceOrderMapping{},
+		&models.MarketplaceInventoryMapping{},
+		&models.MarketplaceWebhookEvent{},
+	)
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"marketplace-connector-service/internal/tenancy"
 )
 
 // SecurityHeaders adds security headers to responses
@@ -62,6 +64,8 @@ func TenantMiddleware() gin.HandlerFunc {
 		}
 		if tenantID != "" {
 			c.Set("tenantId", tenantID)
+			// Scope database statements made with the request context (see internal/tenancy)
+			c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), tenantID))
 		}
 		c.Next()
 	}
@@ -0,0 +1,191 @@
+// Command contractgen writes the Go types orders-service's clients send and decode from the
+// schemas in contracts/, so a client and the contract its provider is tested against can't
+// drift apart. Objects named with x-go-type become structs; the same name used in several
+// contracts must describe the same struct. Strings are plain strings unless their x-go-type is
+// uuid.UUID.
+//
+//	go run ./cmd/contractgen -contracts contracts -out internal/clients/contracts_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"orders-service/internal/contracts"
+)
+
+// initialisms are the words written in capitals in Go names, as in the hand-written clients
+var initialisms = map[string]bool{
+	"id": true, "sku": true, "url": true, "b2b": true,
+	"gst": true, "gstin": true, "cgst": true, "sgst": true, "igst": true, "utgst": true,
+	"vat": true, "hsn": true, "sac": true,
+}
+
+func main() {
+	dir := flag.String("contracts", "contracts", "directory holding one subdirectory of contracts per provider")
+	out := flag.String("out", "internal/clients/contracts_gen.go", "file to write")
+	pkg := flag.String("package", "clients", "package of the generated file")
+	flag.Parse()
+
+	all, err := contracts.Load(os.DirFS(*dir), "")
+	if err != nil {
+		log.Fatalf("Failed to load contracts: %v", err)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID() < all[j].ID() })
+
+	g := &generator{defined: make(map[string]string), source: make(map[string]string)}
+	for _, contract := range all {
+		for _, schema := range []*contracts.Schema{contract.Request.Schema, contract.Response.Schema} {
+			if schema == nil || schema.GoType == "" {
+				continue
+			}
+			if _, err := g.goType(schema, contract.ID()); err != nil {
+				log.Fatalf("%s: %v", contract.ID(), err)
+			}
+		}
+	}
+
+	src, err := g.file(*pkg)
+	if err != nil {
+		log.Fatalf("Failed to format generated code: %v", err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+}
+
+type generator struct {
+	order   []string
+	defined map[string]string // type name -> declaration
+	source  map[string]string // type name -> contract that first declared it
+	uuid    bool
+}
+
+func (g *generator) file(pkg string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by contractgen from orders-service/contracts; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	if g.uuid {
+		buf.WriteString("import \"github.com/google/uuid\"\n\n")
+	}
+	for _, name := range g.order {
+		buf.WriteString(g.defined[name])
+		buf.WriteString("\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+// goType returns the Go type for a schema, declaring the structs it needs
+func (g *generator) goType(s *contracts.Schema, contractID string) (string, error) {
+	switch s.Type.Main() {
+	case "object":
+		if s.GoType == "" {
+			return "map[string]interface{}", nil
+		}
+		return s.GoType, g.declare(s, contractID)
+	case "array":
+		if s.Items == nil {
+			return "[]interface{}", nil
+		}
+		elem, err := g.goType(s.Items, contractID)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case "string":
+		if s.GoType == "uuid.UUID" {
+			g.uuid = true
+			return s.GoType, nil
+		}
+		return "string", nil
+	case "integer":
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	}
+	return "", fmt.Errorf("unsupported schema type %v", s.Type)
+}
+
+func (g *generator) declare(s *contracts.Schema, contractID string) error {
+	// Take the type's place before its fields, so structs come before the ones they contain
+	_, seen := g.source[s.GoType]
+	if !seen {
+		g.source[s.GoType] = contractID
+		g.order = append(g.order, s.GoType)
+	}
+
+	var buf bytes.Buffer
+	if s.Description != "" {
+		fmt.Fprintf(&buf, "// %s %s\n", s.GoType, s.Description)
+	}
+	fmt.Fprintf(&buf, "type %s struct {\n", s.GoType)
+	for _, prop := range s.Properties {
+		typ, err := g.goType(prop.Schema, contractID)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", s.GoType, prop.Name, err)
+		}
+		required := s.IsRequired(prop.Name)
+		if (prop.Schema.Type.Nullable() || (prop.Schema.Type.Main() == "object" && !required)) &&
+			!strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") {
+			typ = "*" + typ
+		}
+		tag := prop.Name
+		if !required {
+			tag += ",omitempty"
+		}
+
+		name := prop.Schema.GoName
+		if name == "" {
+			name = exportedName(prop.Name)
+		}
+		fmt.Fprintf(&buf, "\t%s %s `json:\"%s\"`", name, typ, tag)
+		if prop.Schema.Description != "" && prop.Schema.GoType == "" {
+			fmt.Fprintf(&buf, " // %s", prop.Schema.Description)
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+
+	decl := buf.String()
+	if seen {
+		if g.defined[s.GoType] != decl {
+			return fmt.Errorf("%s is declared differently in %s", s.GoType, g.source[s.GoType])
+		}
+		return nil
+	}
+	g.defined[s.GoType] = decl
+	return nil
+}
+
+// exportedName turns a JSON property name into a Go field name, e.g. customerGstin into
+// CustomerGSTIN
+func exportedName(jsonName string) string {
+	var words []string
+	start := 0
+	for i, r := range jsonName {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, jsonName[start:i])
+			start = i
+		}
+	}
+	words = append(words, jsonName[start:])
+
+	var name strings.Builder
+	for _, word := range words {
+		if initialisms[strings.ToLower(word)] {
+			name.WriteString(strings.ToUpper(word))
+			continue
+		}
+		name.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return name.String()
+}
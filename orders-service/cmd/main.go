@@ -4,9 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
@@ -21,13 +21,16 @@ import (
 
 	"orders-service/internal/clients"
 	"orders-service/internal/config"
+	"orders-service/internal/database"
 	"orders-service/internal/events"
 	"orders-service/internal/handlers"
+	"orders-service/internal/jobs"
 	"orders-service/internal/middleware"
-	"orders-service/internal/models"
 	"orders-service/internal/repository"
+	"orders-service/internal/serviceauth"
 	"orders-service/internal/services"
 	"orders-service/internal/subscribers"
+	"orders-service/internal/tenancy"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"github.com/Tesseract-Nexus/go-shared/rbac"
@@ -60,15 +63,28 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	dbCfg := database.ConfigFromEnv(cfg.IsProduction())
+
+	// "orders-service migrate [status]" applies migrations and exits, for running as an init job
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(cfg, dbCfg, os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		runSeed(cfg, os.Args[2:])
+		return
+	}
+
 	// Initialize database
-	db, err := initDatabase(cfg)
+	db, err := initDatabase(cfg, dbCfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
-	// Auto-migrate database schema
-	if err := migrateDatabase(db); err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
+	// Migrate, or wait for the migrate init job, before serving. Replicas starting together
+	// serialize on the migration lock instead of racing AutoMigrate.
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
 	}
 
 	// Initialize Redis client (optional - graceful degradation if Redis unavailable)
@@ -104,6 +120,16 @@ func main() {
 	cancellationSettingsRepo := repository.NewCancellationSettingsRepository(db)
 	receiptSettingsRepo := repository.NewReceiptSettingsRepository(db)
 	receiptDocumentRepo := repository.NewReceiptDocumentRepository(db)
+	vendorAnalyticsRepo := repository.NewVendorAnalyticsRepository(db)
+	tenantAnalyticsRepo := repository.NewTenantAnalyticsRepository(db)
+	reportScheduleRepo := repository.NewReportScheduleRepository(db)
+	orderIntegrationRepo := repository.NewOrderIntegrationRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	checkoutSagaRepo := repository.NewCheckoutSagaRepository(db)
+	stuckOrderRepo := repository.NewStuckOrderRepository(db)
+	orderNumberSettingsRepo := repository.NewOrderNumberSettingsRepository(db)
+	orderImportRepo := repository.NewOrderImportRepository(db)
+	notificationRoutingRepo := repository.NewNotificationRoutingRepository(db)
 
 	// Initialize clients
 	productsServiceURL := os.Getenv("PRODUCTS_SERVICE_URL")
@@ -135,6 +161,13 @@ func main() {
 	shippingClient := clients.NewShippingClient()
 	log.Println("Shipping client initialized for auto-shipment creation")
 
+	// Initialize inventory service client for click-and-collect orders
+	inventoryServiceURL := os.Getenv("INVENTORY_SERVICE_URL")
+	if inventoryServiceURL == "" {
+		inventoryServiceURL = "http://inventory-service:8088"
+	}
+	inventoryClient := clients.NewInventoryClient(inventoryServiceURL)
+
 	// Initialize payment service client for refunds
 	paymentServiceURL := os.Getenv("PAYMENT_SERVICE_URL")
 	if paymentServiceURL == "" {
@@ -147,6 +180,9 @@ func main() {
 	approvalClient := clients.NewApprovalClient()
 	log.Println("Approval client initialized for refund/cancellation workflows")
 
+	// Initialize tickets client for stuck order escalations
+	ticketsClient := clients.NewTicketsClient()
+
 	// Initialize document client for receipt storage
 	documentClient := clients.NewDocumentClient()
 	log.Println("Document client initialized for receipt storage")
@@ -190,9 +226,30 @@ func main() {
 	if staffServiceURL == "" {
 		staffServiceURL = "http://staff-service:8080"
 	}
-	rbacMiddleware := rbac.NewMiddlewareWithURL(staffServiceURL, nil)
+	rbacClient := rbac.NewClient(staffServiceURL)
+	rbacMiddleware := rbac.NewMiddleware(rbacClient, nil)
 	log.Println("✓ RBAC middleware initialized")
 
+	// Reuse effective permissions for RBAC_CACHE_TTL, dropping them early when staff-service
+	// publishes rbac.permissions_changed
+	rbacCacheTTL, _ := time.ParseDuration(os.Getenv("RBAC_CACHE_TTL"))
+	rbacCache := middleware.NewRBACPermissionCache(rbacClient, rbacCacheTTL)
+	go rbacCache.Start(context.Background())
+	rbacSubscriber, err := subscribers.NewRBACSubscriber(rbacCache, logger)
+	if err != nil {
+		log.Printf("WARNING: Failed to initialize RBAC subscriber: %v (permission changes apply once cached permissions expire)", err)
+	} else if err := rbacSubscriber.Start(); err != nil {
+		log.Printf("WARNING: RBAC subscriber failed to start: %v", err)
+	} else {
+		log.Println("✓ RBAC permission cache invalidation subscriber started")
+	}
+
+	// Replay order creation responses for retried requests with an Idempotency-Key, for
+	// IDEMPOTENCY_TTL (default 24h); Redis when available, the database otherwise
+	idempotencyTTL, _ := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL"))
+	idempotency := middleware.NewIdempotency(repository.NewIdempotencyRepository(db, redisClient), idempotencyTTL)
+	go idempotency.Start(context.Background())
+
 	// Initialize guest token service for guest order access
 	guestTokenSvc := services.NewGuestTokenService()
 	log.Println("Guest token service initialized for guest order access")
@@ -200,10 +257,25 @@ func main() {
 	// Initialize services
 	// Note: cancellationSettingsService is initialized first as it's a dependency for orderService
 	cancellationSettingsService := services.NewCancellationSettingsService(cancellationSettingsRepo)
-	orderService := services.NewOrderService(orderRepo, returnRepo, cancellationSettingsService, productsClient, taxClient, customersClient, notificationClient, tenantClient, shippingClient, eventsPublisher, guestTokenSvc)
-	returnService := services.NewReturnService(returnRepo, orderRepo, paymentClient)
+	// Customer order notifications go out on each tenant's routed channels, digested and logged per order
+	orderNotifier := services.NewOrderNotifier(notificationClient, notificationRoutingRepo)
+	orderService := services.NewOrderService(orderRepo, returnRepo, cancellationSettingsService, productsClient, taxClient, customersClient, orderNotifier, tenantClient, shippingClient, inventoryClient, paymentClient, eventsPublisher, guestTokenSvc, services.NewPriceLockVerifier())
+	returnService := services.NewReturnService(returnRepo, orderRepo, paymentClient, shippingClient)
 	paymentConfigService := services.NewPaymentConfigService(db, eventsPublisher)
 	receiptService := services.NewReceiptService(receiptSettingsRepo, receiptDocumentRepo, documentClient, tenantClient, redisClient)
+	orderDocumentService := services.NewOrderDocumentService(receiptService, receiptDocumentRepo, documentClient, shippingClient, tenantClient)
+	vendorAnalyticsService := services.NewVendorAnalyticsService(vendorAnalyticsRepo)
+	tenantAnalyticsService := services.NewTenantAnalyticsService(tenantAnalyticsRepo)
+	reportScheduleService := services.NewReportScheduleService(reportScheduleRepo, tenantAnalyticsService, vendorAnalyticsRepo, inventoryClient, productsClient, documentClient, notificationClient)
+	orderIntegrationService := services.NewOrderIntegrationService(orderIntegrationRepo)
+	webhookService := services.NewWebhookService(webhookRepo)
+	checkoutService := services.NewCheckoutService(orderService, checkoutSagaRepo, inventoryClient, paymentClient, cfg.Checkout.HoldTTL)
+	stuckOrderService := services.NewStuckOrderService(stuckOrderRepo, orderRepo, eventsPublisher, ticketsClient,
+		time.Duration(cfg.StuckOrders.PaidNotFulfilledHours)*time.Hour,
+		time.Duration(cfg.StuckOrders.ShippedNotDeliveredDays)*24*time.Hour,
+		logger)
+	orderNumberSettingsService := services.NewOrderNumberSettingsService(orderNumberSettingsRepo)
+	orderImportService := services.NewOrderImportService(orderImportRepo, customersClient)
 
 	// Initialize handlers
 	orderHandler := handlers.NewOrderHandler(orderService)
@@ -214,6 +286,17 @@ func main() {
 	cancellationSettingsHandler := handlers.NewCancellationSettingsHandler(cancellationSettingsService)
 	receiptHandler := handlers.NewReceiptHandler(receiptService, orderService, guestTokenSvc)
 	log.Println("✓ Receipt handler initialized")
+	orderDocumentHandler := handlers.NewOrderDocumentHandler(orderDocumentService, orderService)
+	vendorAnalyticsHandler := handlers.NewVendorAnalyticsHandler(vendorAnalyticsService)
+	tenantAnalyticsHandler := handlers.NewTenantAnalyticsHandler(tenantAnalyticsService)
+	reportScheduleHandler := handlers.NewReportScheduleHandler(reportScheduleService)
+	orderIntegrationHandler := handlers.NewOrderIntegrationHandler(orderIntegrationService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	checkoutHandler := handlers.NewCheckoutHandler(checkoutService)
+	stuckOrderHandler := handlers.NewStuckOrderHandler(stuckOrderService)
+	orderNumberSettingsHandler := handlers.NewOrderNumberSettingsHandler(orderNumberSettingsService)
+	orderImportHandler := handlers.NewOrderImportHandler(orderImportService)
+	notificationRoutingHandler := handlers.NewNotificationRoutingHandler(orderNotifier)
 
 	// Start approval event subscriber
 	approvalSubscriber, err = subscribers.NewApprovalSubscriber(orderService, approvalClient, logger)
@@ -228,61 +311,233 @@ func main() {
 		log.Println("Approval event subscriber started for processing approved refunds/cancellations")
 	}
 
+	// Start vendor stats rollup job (backs the vendor analytics dashboard)
+	vendorStatsJob := jobs.NewVendorStatsJob(vendorAnalyticsRepo, logger)
+	go vendorStatsJob.Start(context.Background())
+	log.Println("✓ Vendor stats rollup job started")
+
+	// Start tenant analytics rollup job and the subscriber feeding it payment/customer signals
+	tenantAnalyticsJob := jobs.NewTenantAnalyticsJob(tenantAnalyticsRepo, productsClient, logger)
+	go tenantAnalyticsJob.Start(context.Background())
+	log.Println("✓ Tenant analytics rollup job started")
+
+	// Start order automation job (auto-confirmation and unpaid order cancellation per tenant settings)
+	orderAutomationJob := jobs.NewOrderAutomationJob(cancellationSettingsRepo, orderRepo, orderService, logger)
+	go orderAutomationJob.Start(context.Background())
+	log.Println("✓ Order automation job started")
+
+	// Start scheduled reports job (renders and emails due report schedules)
+	scheduledReportsJob := jobs.NewScheduledReportsJob(reportScheduleService, logger)
+	go scheduledReportsJob.Start(context.Background())
+	log.Println("✓ Scheduled reports job started")
+
+	// Start order integration delivery job (sends queued timeline events to tenants' ERPs)
+	orderIntegrationJob := jobs.NewOrderIntegrationDeliveryJob(orderIntegrationService, logger)
+	go orderIntegrationJob.Start(context.Background())
+	log.Println("✓ Order integration delivery job started")
+
+	// Start webhook dispatcher job (sends queued order lifecycle events to tenants' webhooks)
+	webhookDispatcherJob := jobs.NewWebhookDispatcherJob(webhookService, logger)
+	go webhookDispatcherJob.Start(context.Background())
+	log.Println("✓ Webhook dispatcher job started")
+
+	// Start notification digest job (sends order notifications held for customers' digests)
+	notificationDigestJob := jobs.NewNotificationDigestJob(orderNotifier, logger)
+	go notificationDigestJob.Start(context.Background())
+	log.Println("✓ Notification digest job started")
+
+	// Start checkout saga job (retries failed checkout steps and undoes checkouts left unpaid)
+	checkoutSagaJob := jobs.NewCheckoutSagaJob(checkoutService, logger)
+	go checkoutSagaJob.Start(context.Background())
+	log.Println("✓ Checkout saga job started")
+
+	// Start stuck order watchdog (flags orders stuck past their thresholds and opens ops tickets)
+	stuckOrderWatchdogJob := jobs.NewStuckOrderWatchdogJob(stuckOrderService, logger)
+	go stuckOrderWatchdogJob.Start(context.Background())
+	log.Println("✓ Stuck order watchdog job started")
+
+	// Start order import processing job (imports queued historical and offline order files)
+	orderImportJob := jobs.NewOrderImportProcessingJob(orderImportRepo, orderImportService, logger)
+	go orderImportJob.Start(context.Background())
+	log.Println("✓ Order import processing job started")
+
+	analyticsSubscriber, err := subscribers.NewAnalyticsSubscriber(tenantAnalyticsRepo, logger)
+	if err != nil {
+		log.Printf("WARNING: Failed to initialize analytics subscriber: %v (payment and customer metrics will be empty)", err)
+	} else {
+		go func() {
+			if err := analyticsSubscriber.Start(context.Background()); err != nil {
+				log.Printf("WARNING: Analytics subscriber failed to start: %v", err)
+			}
+		}()
+		log.Println("Analytics event subscriber started for payment and customer metrics")
+	}
+
+	// Anonymize orders of customers who deleted their account (needs the publisher to confirm)
+	var customerDeletionSubscriber *subscribers.CustomerDeletionSubscriber
+	if eventsPublisher != nil {
+		customerDeletionSubscriber, err = subscribers.NewCustomerDeletionSubscriber(orderRepo, eventsPublisher, logger)
+		if err != nil {
+			log.Printf("WARNING: Failed to initialize customer deletion subscriber: %v (account deletions will not complete)", err)
+		} else {
+			go func() {
+				if err := customerDeletionSubscriber.Start(context.Background()); err != nil {
+					log.Printf("WARNING: Customer deletion subscriber failed to start: %v", err)
+				}
+			}()
+			log.Println("Customer deletion subscriber started for account deletion anonymization")
+		}
+	}
+
+	// Start live event bridge (NATS -> admin dashboard server-sent events)
+	liveEventHub := services.NewLiveEventHub()
+	liveEventsHandler := handlers.NewLiveEventsHandler(liveEventHub, rbacMiddleware)
+	liveEventsSubscriber, err := subscribers.NewLiveEventsSubscriber(liveEventHub, logger)
+	if err != nil {
+		log.Printf("WARNING: Failed to initialize live events subscriber: %v (live event stream will be idle)", err)
+	} else if err := liveEventsSubscriber.Start(); err != nil {
+		log.Printf("WARNING: Live events subscriber failed to start: %v", err)
+	} else {
+		log.Println("✓ Live events subscriber started")
+	}
+
 	// Initialize guest order handler for public endpoints
 	guestOrderHandler := handlers.NewGuestOrderHandler(orderService, guestTokenSvc)
 
 	// Setup router
-	router := setupRouter(cfg, orderHandler, returnHandler, shippingHandler, approvalHandler, paymentConfigHandler, guestOrderHandler, cancellationSettingsHandler, receiptHandler, metrics, rbacMiddleware, logger)
+	router := setupRouter(cfg, orderHandler, returnHandler, shippingHandler, approvalHandler, paymentConfigHandler, guestOrderHandler, cancellationSettingsHandler, receiptHandler, orderDocumentHandler, vendorAnalyticsHandler, tenantAnalyticsHandler, reportScheduleHandler, orderIntegrationHandler, webhookHandler, checkoutHandler, stuckOrderHandler, orderNumberSettingsHandler, orderImportHandler, notificationRoutingHandler, liveEventsHandler, metrics, rbacMiddleware, rbacCache, idempotency, staffServiceURL, logger)
+
+	// Start server
+	srv := &http.Server{
+		Addr:    cfg.GetServerAddress(),
+		Handler: router,
+	}
+	go func() {
+		log.Printf("Starting Orders Service on %s", cfg.GetServerAddress())
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
 
 	// Graceful shutdown handling
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down Orders Service...")
 
-	go func() {
-		<-quit
-		log.Println("Shutting down Orders Service...")
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
 
-		// Stop approval subscriber
-		if approvalSubscriber != nil {
-			approvalSubscriber.Stop()
-			log.Println("✓ Approval subscriber stopped")
-		}
+	// End live event streams first; they stay open indefinitely and would hold up draining
+	if liveEventsSubscriber != nil {
+		liveEventsSubscriber.Stop()
+	}
+	liveEventHub.Close()
+	log.Println("✓ Live events subscriber stopped")
 
-		// Close events publisher
-		if eventsPublisher != nil {
-			eventsPublisher.Close()
-			log.Println("✓ Events publisher closed")
-		}
+	// Stop accepting connections and let in-flight requests finish before stopping workers
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
 
-		// Shutdown tracer provider
-		if tracerProvider != nil {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			if err := tracerProvider.Shutdown(ctx); err != nil {
-				log.Printf("Error shutting down tracer provider: %v", err)
-			} else {
-				log.Println("✓ Tracer provider shut down")
-			}
-		}
+	// Stop approval subscriber
+	if approvalSubscriber != nil {
+		approvalSubscriber.Stop()
+		log.Println("✓ Approval subscriber stopped")
+	}
 
-		log.Println("Orders service stopped")
-		os.Exit(0)
-	}()
+	// Stop vendor stats rollup job
+	vendorStatsJob.Stop()
+	log.Println("✓ Vendor stats rollup job stopped")
 
-	// Start server
-	log.Printf("Starting Orders Service on %s", cfg.GetServerAddress())
-	if err := router.Run(cfg.GetServerAddress()); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	// Stop tenant analytics job and subscriber
+	tenantAnalyticsJob.Stop()
+	if analyticsSubscriber != nil {
+		analyticsSubscriber.Stop()
+	}
+	log.Println("✓ Tenant analytics job and subscriber stopped")
+
+	if customerDeletionSubscriber != nil {
+		customerDeletionSubscriber.Stop()
+		log.Println("✓ Customer deletion subscriber stopped")
+	}
+
+	// Stop order automation job
+	orderAutomationJob.Stop()
+	log.Println("✓ Order automation job stopped")
+
+	// Stop scheduled reports job
+	scheduledReportsJob.Stop()
+	log.Println("✓ Scheduled reports job stopped")
+
+	// Stop order integration delivery job
+	orderIntegrationJob.Stop()
+	log.Println("✓ Order integration delivery job stopped")
+
+	// Stop webhook dispatcher job
+	webhookDispatcherJob.Stop()
+	log.Println("✓ Webhook dispatcher job stopped")
+
+	// Stop notification digest job
+	notificationDigestJob.Stop()
+	log.Println("✓ Notification digest job stopped")
+
+	// Stop checkout saga job
+	checkoutSagaJob.Stop()
+	log.Println("✓ Checkout saga job stopped")
+
+	// Stop stuck order watchdog job
+	stuckOrderWatchdogJob.Stop()
+	log.Println("✓ Stuck order watchdog job stopped")
+
+	// Stop order import processing job; an import in progress is requeued after its current chunk
+	orderImportJob.Stop()
+	log.Println("✓ Order import processing job stopped")
+
+	// Stop RBAC permission cache invalidation
+	rbacCache.Stop()
+	if rbacSubscriber != nil {
+		rbacSubscriber.Stop()
+	}
+	log.Println("✓ RBAC subscriber stopped")
+
+	idempotency.Stop()
+
+	// Close events publisher
+	if eventsPublisher != nil {
+		eventsPublisher.Close()
+		log.Println("✓ Events publisher closed")
+	}
+
+	// Shutdown tracer provider
+	if tracerProvider != nil {
+		tracerCtx, tracerCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer tracerCancel()
+		if err := tracerProvider.Shutdown(tracerCtx); err != nil {
+			log.Printf("Error shutting down tracer provider: %v", err)
+		} else {
+			log.Println("✓ Tracer provider shut down")
+		}
 	}
+
+	log.Println("Orders service stopped")
 }
 
-// initDatabase initializes the database connection
-func initDatabase(cfg *config.Config) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(cfg.GetDatabaseDSN()), &gorm.Config{})
+// initDatabase initializes the database connection, waiting while the database is still
+// starting up
+func initDatabase(cfg *config.Config, dbCfg database.Config) (*gorm.DB, error) {
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) {
+		return gorm.Open(postgres.Open(cfg.GetDatabaseDSN()), &gorm.Config{})
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
+	}
+
 	// Get underlying sql.DB to configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -296,97 +551,19 @@ func initDatabase(cfg *config.Config) (*gorm.DB, error) {
 	return db, nil
 }
 
-// migrateDatabase runs database migrations
-func migrateDatabase(db *gorm.DB) error {
-	// Pre-migration: Drop old unique constraints that may not exist
-	// GORM may try to drop these constraints during AutoMigrate when detecting model changes.
-	// We proactively drop them using IF EXISTS to prevent errors.
-	oldConstraints := []struct {
-		table      string
-		constraint string
-	}{
-		{"orders", "uni_orders_order_number"},
-		{"orders", "orders_order_number_key"},
-		{"returns", "uni_returns_rma_number"},
-		{"returns", "returns_rma_number_key"},
-	}
-
-	for _, c := range oldConstraints {
-		sql := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s", c.table, c.constraint)
-		if err := db.Exec(sql).Error; err != nil {
-			log.Printf("Note: Could not drop constraint %s.%s: %v", c.table, c.constraint, err)
-		}
-	}
-
-	// Also drop old unique indexes that GORM might try to remove
-	oldIndexes := []struct {
-		table string
-		index string
-	}{
-		{"orders", "idx_orders_order_number"},
-		{"orders", "uni_orders_order_number"},
-		{"returns", "idx_returns_rma_number"},
-		{"returns", "uni_returns_rma_number"},
+// runMigrate applies pending migrations, or with "status" lists them, then exits
+func runMigrate(cfg *config.Config, dbCfg database.Config, args []string) {
+	db, err := initDatabase(cfg, dbCfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
 	}
-
-	for _, idx := range oldIndexes {
-		sql := fmt.Sprintf("DROP INDEX IF EXISTS %s", idx.index)
-		if err := db.Exec(sql).Error; err != nil {
-			log.Printf("Note: Could not drop index %s: %v", idx.index, err)
-		}
+	if err := database.RunCommand(db, dbCfg, args); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
 	}
-
-	// Run GORM AutoMigrate with error handling for constraint issues
-	err := db.AutoMigrate(
-		&models.Order{},
-		&models.OrderItem{},
-		&models.OrderCustomer{},
-		&models.OrderShipping{},
-		&models.OrderPayment{},
-		&models.OrderTimeline{},
-		&models.OrderDiscount{},
-		&models.OrderRefund{},
-		&models.OrderSplit{},
-		&models.Return{},
-		&models.ReturnItem{},
-		&models.ReturnTimeline{},
-		&models.ReturnPolicy{},
-		&models.ShippingMethod{},
-		&models.CancellationSettings{},
-		&models.ReceiptSettings{},
-		&models.ReceiptDocument{},
-	)
-
-	// If migration fails due to constraint issues, try again after dropping any remaining constraints
-	if err != nil && strings.Contains(err.Error(), "does not exist") {
-		log.Printf("Migration encountered constraint issue, retrying: %v", err)
-		// Try the migration again - the constraint should already be gone now
-		return db.AutoMigrate(
-			&models.Order{},
-			&models.OrderItem{},
-			&models.OrderCustomer{},
-			&models.OrderShipping{},
-			&models.OrderPayment{},
-			&models.OrderTimeline{},
-			&models.OrderDiscount{},
-			&models.OrderRefund{},
-			&models.OrderSplit{},
-			&models.Return{},
-			&models.ReturnItem{},
-			&models.ReturnTimeline{},
-			&models.ReturnPolicy{},
-			&models.ShippingMethod{},
-			&models.CancellationSettings{},
-			&models.ReceiptSettings{},
-			&models.ReceiptDocument{},
-		)
-	}
-
-	return err
 }
 
 // setupRouter configures the Gin router with middleware and routes
-func setupRouter(cfg *config.Config, orderHandler *handlers.OrderHandler, returnHandler *handlers.ReturnHandlers, shippingHandler *handlers.ShippingHandler, approvalHandler *handlers.ApprovalAwareHandler, paymentConfigHandler *handlers.PaymentConfigHandler, guestOrderHandler *handlers.GuestOrderHandler, cancellationSettingsHandler *handlers.CancellationSettingsHandler, receiptHandler *handlers.ReceiptHandler, metrics *gosharedmw.Metrics, rbacMw *rbac.Middleware, logger *logrus.Logger) *gin.Engine {
+func setupRouter(cfg *config.Config, orderHandler *handlers.OrderHandler, returnHandler *handlers.ReturnHandlers, shippingHandler *handlers.ShippingHandler, approvalHandler *handlers.ApprovalAwareHandler, paymentConfigHandler *handlers.PaymentConfigHandler, guestOrderHandler *handlers.GuestOrderHandler, cancellationSettingsHandler *handlers.CancellationSettingsHandler, receiptHandler *handlers.ReceiptHandler, orderDocumentHandler *handlers.OrderDocumentHandler, vendorAnalyticsHandler *handlers.VendorAnalyticsHandler, tenantAnalyticsHandler *handlers.TenantAnalyticsHandler, reportScheduleHandler *handlers.ReportScheduleHandler, orderIntegrationHandler *handlers.OrderIntegrationHandler, webhookHandler *handlers.WebhookHandler, checkoutHandler *handlers.CheckoutHandler, stuckOrderHandler *handlers.StuckOrderHandler, orderNumberSettingsHandler *handlers.OrderNumberSettingsHandler, orderImportHandler *handlers.OrderImportHandler, notificationRoutingHandler *handlers.NotificationRoutingHandler, liveEventsHandler *handlers.LiveEventsHandler, metrics *gosharedmw.Metrics, rbacMw *rbac.Middleware, rbacCache *middleware.RBACPermissionCache, idempotency *middleware.Idempotency, staffServiceURL string, logger *logrus.Logger) *gin.Engine {
 	// Set Gin mode
 	if cfg.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
@@ -428,33 +605,51 @@ func setupRouter(cfg *config.Config, orderHandler *handlers.OrderHandler, return
 	// Authentication middleware using Istio JWT claims
 	// Istio validates JWT and injects x-jwt-claim-* headers
 	// AllowLegacyHeaders provides backward compatibility during migration
-	api.Use(gosharedmw.IstioAuth(gosharedmw.IstioAuthConfig{
+	// Requests with an X-API-Key header are authenticated with the tenant API key instead
+	api.Use(middleware.APIKeyAuth(staffServiceURL, gosharedmw.IstioAuth(gosharedmw.IstioAuthConfig{
 		RequireAuth:               true,
 		AllowLegacyHeaders:        false,
 		AllowInternalServiceCalls: true, // Allow admin BFF service-to-service calls
 		SkipPaths:                 []string{"/health", "/ready", "/metrics", "/swagger"},
-	}))
+	})))
+	api.Use(rbacCache.Track())
 
 	// Vendor scope filter for marketplace mode
 	// Sets vendor_scope_filter for vendor-scoped users ONLY
 	// Tenant-level admins (store_owner, store_admin) get no filter = see all orders
 	// Vendor-level staff (vendor_owner, vendor_admin) get their vendor_id = see only their orders
 	api.Use(gosharedmw.VendorScopeFilter())
+	// Admins with an X-Impersonation-Token act as the session's vendor or staff member
+	api.Use(middleware.ImpersonationAuth(staffServiceURL))
 	{
 		orders := api.Group("/orders")
 		{
 			// Read operations - require orders:view permission
 			orders.GET("", rbacMw.RequirePermissionAllowInternal(rbac.PermissionOrdersRead), orderHandler.ListOrders)
 			orders.GET("/batch", rbacMw.RequirePermission(rbac.PermissionOrdersRead), orderHandler.BatchGetOrders)
+			// Orders flagged by the stuck order watchdog, with their escalation tickets
+			orders.GET("/stuck", rbacMw.RequirePermission(rbac.PermissionOrdersRead), stuckOrderHandler.ListStuckOrders)
+			// Imports of historical and offline orders, processed in the background
+			orders.GET("/import/template", rbacMw.RequirePermission(rbac.PermissionOrdersCreate), orderImportHandler.GetImportTemplate)
+			orders.POST("/import/preview", rbacMw.RequirePermission(rbac.PermissionOrdersCreate), orderImportHandler.PreviewImport)
+			orders.POST("/import", rbacMw.RequirePermission(rbac.PermissionOrdersCreate), orderImportHandler.StartImport)
+			orders.GET("/import/jobs", rbacMw.RequirePermission(rbac.PermissionOrdersRead), orderImportHandler.ListImportJobs)
+			orders.GET("/import/jobs/:jobId", rbacMw.RequirePermission(rbac.PermissionOrdersRead), orderImportHandler.GetImportJob)
+			orders.GET("/import/jobs/:jobId/errors", rbacMw.RequirePermission(rbac.PermissionOrdersRead), orderImportHandler.GetImportErrors)
+			orders.POST("/import/jobs/:jobId/resume", rbacMw.RequirePermission(rbac.PermissionOrdersCreate), orderImportHandler.ResumeImportJob)
 			// Allow internal service calls for GetOrder (used by storefront BFF for success page)
 			orders.GET("/:id", rbacMw.RequirePermissionAllowInternal(rbac.PermissionOrdersRead), orderHandler.GetOrder)
 			orders.GET("/:id/valid-transitions", rbacMw.RequirePermission(rbac.PermissionOrdersRead), orderHandler.GetValidStatusTransitions)
 			orders.GET("/:id/tracking", rbacMw.RequirePermissionAllowInternal(rbac.PermissionOrdersRead), orderHandler.GetOrderTracking)
 			orders.GET("/:id/children", rbacMw.RequirePermission(rbac.PermissionOrdersRead), orderHandler.GetChildOrders)
+			orders.GET("/:id/revisions", rbacMw.RequirePermission(rbac.PermissionOrdersRead), orderHandler.ListOrderRevisions)
+			orders.GET("/:id/fulfillments", rbacMw.RequirePermission(rbac.PermissionOrdersRead), orderHandler.ListFulfillments)
+			orders.GET("/:id/notifications", rbacMw.RequirePermission(rbac.PermissionOrdersRead), notificationRoutingHandler.ListOrderNotifications)
 			orders.GET("/number/:orderNumber", rbacMw.RequirePermission(rbac.PermissionOrdersRead), orderHandler.GetOrderByNumber)
+			orders.GET("/analytics/vendor", rbacMw.RequirePermission(rbac.PermissionOrdersRead), vendorAnalyticsHandler.GetVendorAnalytics)
 
 			// Create operations - require orders:create permission
-			orders.POST("", rbacMw.RequirePermission(rbac.PermissionOrdersCreate), orderHandler.CreateOrder)
+			orders.POST("", rbacMw.RequirePermission(rbac.PermissionOrdersCreate), idempotency.Require(), orderHandler.CreateOrder)
 
 			// Update operations - require orders:update permission
 			orders.PUT("/:id", rbacMw.RequirePermission(rbac.PermissionOrdersUpdate), orderHandler.UpdateOrder)
@@ -462,7 +657,11 @@ func setupRouter(cfg *config.Config, orderHandler *handlers.OrderHandler, return
 			orders.PATCH("/:id/payment-status", rbacMw.RequirePermissionAllowInternal(rbac.PermissionOrdersUpdate), orderHandler.UpdatePaymentStatus)
 			orders.PATCH("/:id/fulfillment-status", rbacMw.RequirePermission(rbac.PermissionOrdersUpdate), orderHandler.UpdateFulfillmentStatus)
 			orders.POST("/:id/tracking", rbacMw.RequirePermission(rbac.PermissionOrdersShip), orderHandler.AddShippingTracking)
+			orders.POST("/:id/fulfillments", rbacMw.RequirePermission(rbac.PermissionOrdersShip), orderHandler.CreateFulfillment)
+			orders.PATCH("/:id/fulfillments/:fulfillmentId/status", rbacMw.RequirePermission(rbac.PermissionOrdersShip), orderHandler.UpdateFulfillment)
 			orders.POST("/:id/split", rbacMw.RequirePermission(rbac.PermissionOrdersUpdate), orderHandler.SplitOrder)
+			orders.POST("/:id/split-by-vendor", rbacMw.RequirePermission(rbac.PermissionOrdersUpdate), orderHandler.SplitOrderByVendor)
+			orders.POST("/:id/edit", rbacMw.RequirePermission(rbac.PermissionOrdersUpdate), orderHandler.EditOrder)
 
 			// Sensitive operations with approval workflow - require specific permissions
 			// These handlers check if approval is needed based on thresholds
@@ -480,15 +679,18 @@ func setupRouter(cfg *config.Config, orderHandler *handlers.OrderHandler, return
 			// Receipt storage endpoints - generate and store receipt in document service
 			orders.POST("/:id/receipt/generate", rbacMw.RequirePermission(rbac.PermissionOrdersUpdate), receiptHandler.GenerateAndStoreReceipt)
 			orders.GET("/:id/receipts", rbacMw.RequirePermission(rbac.PermissionOrdersRead), receiptHandler.GetOrderReceiptDocuments)
+
+			// Document pack - packing slip, invoice and shipping labels in one merged PDF or zip
+			orders.GET("/:id/documents", rbacMw.RequirePermission(rbac.PermissionOrdersRead), orderDocumentHandler.GetDocumentPack)
 		}
 
 		// Internal callback endpoint for approval service
 		// This is called by approval-service when an approval status changes
 		approvals := api.Group("/approvals")
 		{
-			// Callback from approval-service - no RBAC since it's service-to-service
-			// The approval-service should authenticate using a service token
-			approvals.POST("/callback", approvalHandler.HandleApprovalCallback)
+			// Callback from approval-service - no RBAC since it's service-to-service,
+			// authenticated by approval-service's mesh identity or service token
+			approvals.POST("/callback", serviceauth.New(serviceauth.ConfigFromEnv("orders-service"), logger).Require("approval-service"), approvalHandler.HandleApprovalCallback)
 		}
 
 		returns := api.Group("/returns")
@@ -499,6 +701,7 @@ func setupRouter(cfg *config.Config, orderHandler *handlers.OrderHandler, return
 			returns.GET("/policy", rbacMw.RequirePermission(rbac.PermissionReturnsRead), returnHandler.GetReturnPolicy)
 			returns.GET("/rma/:rma", rbacMw.RequirePermission(rbac.PermissionReturnsRead), returnHandler.GetReturnByRMA)
 			returns.GET("/:id", rbacMw.RequirePermission(rbac.PermissionReturnsRead), returnHandler.GetReturn)
+			returns.GET("/:id/intake-qr", rbacMw.RequirePermission(rbac.PermissionReturnsRead), returnHandler.GetIntakeQRCode)
 
 			// Create operations
 			returns.POST("", rbacMw.RequirePermission(rbac.PermissionReturnsCreate), returnHandler.CreateReturn)
@@ -509,15 +712,71 @@ func setupRouter(cfg *config.Config, orderHandler *handlers.OrderHandler, return
 			// Approval operations
 			returns.POST("/:id/approve", rbacMw.RequirePermission(rbac.PermissionReturnsApprove), returnHandler.ApproveReturn)
 			returns.POST("/:id/reject", rbacMw.RequirePermission(rbac.PermissionReturnsReject), returnHandler.RejectReturn)
+			returns.POST("/:id/label", rbacMw.RequirePermission(rbac.PermissionReturnsApprove), returnHandler.GenerateReturnLabel)
 
 			// Processing operations
 			returns.POST("/:id/in-transit", rbacMw.RequirePermission(rbac.PermissionReturnsInspect), returnHandler.MarkInTransit)
+			returns.POST("/intake/scan", rbacMw.RequirePermission(rbac.PermissionReturnsInspect), returnHandler.ScanIntake)
 			returns.POST("/:id/received", rbacMw.RequirePermission(rbac.PermissionReturnsInspect), returnHandler.MarkReceived)
 			returns.POST("/:id/inspect", rbacMw.RequirePermission(rbac.PermissionReturnsInspect), returnHandler.InspectReturn)
 			returns.POST("/:id/complete", rbacMw.RequirePermission(rbac.PermissionReturnsRefund), returnHandler.CompleteReturn)
 			returns.POST("/:id/cancel", rbacMw.RequirePermission(rbac.PermissionReturnsReject), returnHandler.CancelReturn)
 		}
 
+		// Tenant-wide business analytics (admin dashboard)
+		analytics := api.Group("/analytics")
+		{
+			analytics.GET("/overview", rbacMw.RequirePermission(rbac.PermissionAnalyticsDashboardView), tenantAnalyticsHandler.GetOverview)
+			analytics.GET("/sales", rbacMw.RequirePermission(rbac.PermissionAnalyticsSalesView), tenantAnalyticsHandler.GetSales)
+			analytics.GET("/categories", rbacMw.RequirePermission(rbac.PermissionAnalyticsProductsView), tenantAnalyticsHandler.GetTopCategories)
+			// Margins use the product costs snapshotted on order items, so need cost visibility
+			analytics.GET("/margins", rbacMw.RequirePermission("catalog:costs:view"), tenantAnalyticsHandler.GetMarginReport)
+			analytics.GET("/margins/orders/:id", rbacMw.RequirePermission("catalog:costs:view"), tenantAnalyticsHandler.GetOrderMargin)
+		}
+
+		// Scheduled email reports (sales summary, low stock, vendor payouts)
+		reportSchedules := api.Group("/report-schedules")
+		{
+			reportSchedules.GET("", rbacMw.RequirePermission(rbac.PermissionAnalyticsReportsView), reportScheduleHandler.ListSchedules)
+			reportSchedules.POST("", rbacMw.RequirePermission(rbac.PermissionAnalyticsExport), reportScheduleHandler.CreateSchedule)
+			reportSchedules.GET("/deliveries/:deliveryId/download", rbacMw.RequirePermission(rbac.PermissionAnalyticsReportsView), reportScheduleHandler.DownloadDelivery)
+			reportSchedules.GET("/:id", rbacMw.RequirePermission(rbac.PermissionAnalyticsReportsView), reportScheduleHandler.GetSchedule)
+			reportSchedules.PUT("/:id", rbacMw.RequirePermission(rbac.PermissionAnalyticsExport), reportScheduleHandler.UpdateSchedule)
+			reportSchedules.DELETE("/:id", rbacMw.RequirePermission(rbac.PermissionAnalyticsExport), reportScheduleHandler.DeleteSchedule)
+			reportSchedules.POST("/:id/run", rbacMw.RequirePermission(rbac.PermissionAnalyticsExport), reportScheduleHandler.RunSchedule)
+			reportSchedules.GET("/:id/deliveries", rbacMw.RequirePermission(rbac.PermissionAnalyticsReportsView), reportScheduleHandler.ListDeliveries)
+		}
+
+		// Outbound order integrations (ERP pushes of timeline events) and their delivery log
+		orderIntegrations := api.Group("/order-integrations")
+		{
+			orderIntegrations.GET("", rbacMw.RequirePermission(rbac.PermissionSettingsRead), orderIntegrationHandler.ListIntegrations)
+			orderIntegrations.POST("", rbacMw.RequirePermission(rbac.PermissionSettingsUpdate), orderIntegrationHandler.CreateIntegration)
+			orderIntegrations.GET("/:id", rbacMw.RequirePermission(rbac.PermissionSettingsRead), orderIntegrationHandler.GetIntegration)
+			orderIntegrations.PUT("/:id", rbacMw.RequirePermission(rbac.PermissionSettingsUpdate), orderIntegrationHandler.UpdateIntegration)
+			orderIntegrations.DELETE("/:id", rbacMw.RequirePermission(rbac.PermissionSettingsUpdate), orderIntegrationHandler.DeleteIntegration)
+			orderIntegrations.POST("/:id/preview", rbacMw.RequirePermission(rbac.PermissionSettingsUpdate), orderIntegrationHandler.PreviewIntegration)
+			orderIntegrations.GET("/:id/deliveries", rbacMw.RequirePermission(rbac.PermissionSettingsRead), orderIntegrationHandler.ListDeliveries)
+			orderIntegrations.POST("/:id/deliveries/replay-failed", rbacMw.RequirePermission(rbac.PermissionSettingsUpdate), orderIntegrationHandler.ReplayFailedDeliveries)
+			orderIntegrations.POST("/:id/deliveries/:deliveryId/replay", rbacMw.RequirePermission(rbac.PermissionSettingsUpdate), orderIntegrationHandler.ReplayDelivery)
+		}
+
+		// Order lifecycle webhooks (signed JSON events) and their delivery log
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.GET("", rbacMw.RequirePermission(rbac.PermissionSettingsRead), webhookHandler.ListSubscriptions)
+			webhooks.POST("", rbacMw.RequirePermission(rbac.PermissionSettingsUpdate), webhookHandler.CreateSubscription)
+			webhooks.GET("/:id", rbacMw.RequirePermission(rbac.PermissionSettingsRead), webhookHandler.GetSubscription)
+			webhooks.PUT("/:id", rbacMw.RequirePermission(rbac.PermissionSettingsUpdate), webhookHandler.UpdateSubscription)
+			webhooks.DELETE("/:id", rbacMw.RequirePermission(rbac.PermissionSettingsUpdate), webhookHandler.DeleteSubscription)
+			webhooks.POST("/:id/rotate-secret", rbacMw.RequirePermission(rbac.PermissionSettingsUpdate), webhookHandler.RotateSecret)
+			webhooks.GET("/:id/deliveries", rbacMw.RequirePermission(rbac.PermissionSettingsRead), webhookHandler.ListDeliveries)
+			webhooks.POST("/:id/deliveries/:deliveryId/replay", rbacMw.RequirePermission(rbac.PermissionSettingsUpdate), webhookHandler.ReplayDelivery)
+		}
+
+		// Live admin event stream (SSE); each event type is further filtered by its read permission
+		api.GET("/events/stream", rbacMw.RequireAnyPermission(rbac.PermissionOrdersRead, rbac.PermissionPaymentsRead, rbac.PermissionTicketsRead), liveEventsHandler.StreamEvents)
+
 		// Shipping methods
 		shipping := api.Group("/shipping-methods")
 		{
@@ -569,6 +828,20 @@ func setupRouter(cfg *config.Config, orderHandler *handlers.OrderHandler, return
 				receipt.GET("", rbacMw.RequirePermission("settings:store:view"), receiptHandler.GetReceiptSettings)
 				receipt.PUT("", rbacMw.RequirePermission("settings:store:edit"), receiptHandler.UpdateReceiptSettings)
 			}
+
+			// Order number format - prefix, date component, sequence padding and per-storefront prefixes
+			orderNumbers := settings.Group("/order-numbers")
+			{
+				orderNumbers.GET("", rbacMw.RequirePermission("settings:store:view"), orderNumberSettingsHandler.GetSettings)
+				orderNumbers.PUT("", rbacMw.RequirePermission("settings:store:edit"), orderNumberSettingsHandler.UpdateSettings)
+			}
+
+			// Customer notification channels per event and digesting of rapid successive updates
+			notifications := settings.Group("/notifications")
+			{
+				notifications.GET("", rbacMw.RequirePermission("settings:store:view"), notificationRoutingHandler.GetSettings)
+				notifications.PUT("", rbacMw.RequirePermission("settings:store:edit"), notificationRoutingHandler.UpdateSettings)
+			}
 		}
 	}
 
@@ -585,10 +858,18 @@ func setupRouter(cfg *config.Config, orderHandler *handlers.OrderHandler, return
 			// Create order - supports both guest and authenticated checkout
 			// If Authorization header present, customer ID extracted from JWT
 			// If no auth, customerId should be provided in request body (guest checkout)
-			storefrontOrders.POST("", orderHandler.CreateOrder)
+			storefrontOrders.POST("", idempotency.Require(), orderHandler.CreateOrder)
 			storefrontOrders.POST("/cancel", orderHandler.StorefrontCancelOrder)
 		}
 
+		// Saga checkout - creates the order, holds its stock and creates its payment intent,
+		// undoing the order if a step can't complete
+		storefrontCheckout := storefront.Group("/checkout")
+		{
+			storefrontCheckout.POST("", checkoutHandler.Checkout)
+			storefrontCheckout.GET("/:orderId", checkoutHandler.GetCheckout)
+		}
+
 		// Payment methods for checkout - returns enabled methods for the tenant
 		storefrontPayments := storefront.Group("/payments")
 		{
@@ -607,6 +888,8 @@ func setupRouter(cfg *config.Config, orderHandler *handlers.OrderHandler, return
 		customerStorefront.GET("/orders/:id/tracking", orderHandler.GetCustomerOrderTracking)
 		// Download receipt for own orders
 		customerStorefront.GET("/orders/:id/receipt", receiptHandler.GetCustomerOrderReceipt)
+		// Status of own returns, with the return shipping label once generated
+		customerStorefront.GET("/returns/:id", returnHandler.GetCustomerReturn)
 	}
 	log.Println("✓ Public storefront endpoints initialized")
 
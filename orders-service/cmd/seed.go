@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"os"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"orders-service/internal/config"
+	"orders-service/internal/database"
+	"orders-service/internal/loadseed"
+	"orders-service/internal/models"
+)
+
+// seedOrderState is what an order status in -order-statuses means for the order's columns
+type seedOrderState struct {
+	status      models.OrderStatus
+	payment     models.PaymentStatus
+	fulfillment models.FulfillmentStatus
+}
+
+var seedOrderStates = map[string]seedOrderState{
+	"PLACED":     {models.OrderStatusPlaced, models.PaymentStatusPending, models.FulfillmentStatusUnfulfilled},
+	"CONFIRMED":  {models.OrderStatusConfirmed, models.PaymentStatusPaid, models.FulfillmentStatusUnfulfilled},
+	"PROCESSING": {models.OrderStatusProcessing, models.PaymentStatusPaid, models.FulfillmentStatusPacked},
+	"SHIPPED":    {models.OrderStatusShipped, models.PaymentStatusPaid, models.FulfillmentStatusInTransit},
+	"DELIVERED":  {models.OrderStatusDelivered, models.PaymentStatusPaid, models.FulfillmentStatusDelivered},
+	"CANCELLED":  {models.OrderStatusCancelled, models.PaymentStatusPending, models.FulfillmentStatusUnfulfilled},
+	"REFUNDED":   {models.OrderStatusDelivered, models.PaymentStatusRefunded, models.FulfillmentStatusReturned},
+}
+
+// runSeed handles "orders-service seed [flags]", filling the database with load-test orders.
+// Orders reference the products and customers that products-service and customers-service
+// seed with the same flags. Existing seeded orders are left as they are, so it can be re-run
+// to top up after raising the counts.
+func runSeed(cfg *config.Config, args []string) {
+	opts, err := loadseed.Parse("orders-service", args)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Invalid seed options: %v", err)
+	}
+	if err := loadseed.CheckEnvironment(cfg.App.Environment); err != nil {
+		log.Fatalf("Refusing to seed: %v", err)
+	}
+	for _, state := range opts.OrderStatuses.Values() {
+		if _, ok := seedOrderStates[state]; !ok {
+			log.Fatalf("Invalid seed options: unknown order status %q", state)
+		}
+	}
+
+	db, err := initDatabase(cfg, database.ConfigFromEnv(cfg.IsProduction()))
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	started := time.Now()
+	total := 0
+	for t, tenantID := range opts.TenantIDs() {
+		products := opts.Count(t, opts.Products)
+		customers := opts.Count(t, opts.Customers)
+		count := opts.Count(t, opts.Orders)
+		if products == 0 || customers == 0 {
+			log.Printf("Skipping %s: no products or customers to order", tenantID)
+			continue
+		}
+
+		r := opts.Rand(tenantID, "orders")
+		batch := make([]models.Order, 0, opts.BatchSize)
+		for i := 0; i < count; i++ {
+			batch = append(batch, seedOrder(opts, r, tenantID, i, products, customers, started))
+			if len(batch) == opts.BatchSize || i == count-1 {
+				if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&batch).Error; err != nil {
+					log.Fatalf("Failed to seed orders for %s: %v", tenantID, err)
+				}
+				batch = batch[:0]
+			}
+		}
+		total += count
+		log.Printf("Seeded %s: %d orders", tenantID, count)
+	}
+	fmt.Fprintf(os.Stdout, "Seeded %d orders for %d tenants in %s\n", total, opts.Tenants, time.Since(started).Round(time.Second))
+}
+
+// seedOrder builds the index-th order of a tenant with its items, customer, shipping and
+// payment. No timeline is written, so seeding doesn't queue webhooks or integrations.
+func seedOrder(opts *loadseed.Options, r *rand.Rand, tenantID string, index, products, customers int, now time.Time) models.Order {
+	id := loadseed.ID("order", tenantID, index)
+	createdAt := opts.OrderTime(r, now)
+	state := seedOrderStates[opts.OrderStatuses.Pick(r)]
+	customer := opts.CustomerAt(tenantID, opts.Pick(r, customers))
+
+	lines := 1 + r.IntN(opts.MaxItems)
+	items := make([]models.OrderItem, 0, lines)
+	subtotal := 0.0
+	for j := 0; j < lines; j++ {
+		product := opts.ProductAt(tenantID, opts.Pick(r, products))
+		quantity := 1 + int(math.Floor(math.Pow(r.Float64(), 3)*4)) // Mostly 1
+		lineTotal := math.Round(product.Price*float64(quantity)*100) / 100
+		subtotal += lineTotal
+		items = append(items, models.OrderItem{
+			ID:          loadseed.ID("order-item", tenantID, index*opts.MaxItems+j),
+			OrderID:     id,
+			ProductID:   product.ID,
+			ProductName: product.Name,
+			SKU:         product.SKU,
+			Quantity:    quantity,
+			UnitPrice:   product.Price,
+			TotalPrice:  lineTotal,
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+		})
+	}
+
+	shippingCost := 0.0
+	if subtotal < 50 {
+		shippingCost = 5.99
+	}
+	tax := math.Round(subtotal*10) / 100
+	total := math.Round((subtotal+tax+shippingCost)*100) / 100
+
+	var processedAt *time.Time
+	if state.payment != models.PaymentStatusPending {
+		processedAt = &createdAt
+	}
+	return models.Order{
+		ID:                id,
+		TenantID:          tenantID,
+		OrderNumber:       fmt.Sprintf("LT-%07d", index+1),
+		CustomerID:        customer.ID,
+		Status:            state.status,
+		PaymentStatus:     state.payment,
+		FulfillmentStatus: state.fulfillment,
+		FulfillmentType:   models.FulfillmentTypeShipping,
+		Currency:          "USD",
+		Subtotal:          math.Round(subtotal*100) / 100,
+		TaxAmount:         tax,
+		ShippingCost:      shippingCost,
+		Total:             total,
+		CreatedAt:         createdAt,
+		UpdatedAt:         createdAt,
+		Version:           1,
+		Items:             items,
+		Customer: &models.OrderCustomer{
+			ID:        loadseed.ID("order-customer", tenantID, index),
+			OrderID:   id,
+			FirstName: customer.FirstName,
+			LastName:  customer.LastName,
+			Email:     customer.Email,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		},
+		Shipping: &models.OrderShipping{
+			ID:          loadseed.ID("order-shipping", tenantID, index),
+			OrderID:     id,
+			Method:      "Standard",
+			Cost:        shippingCost,
+			Street:      customer.Address.Street,
+			City:        customer.Address.City,
+			State:       customer.Address.State,
+			StateCode:   customer.Address.StateCode,
+			PostalCode:  customer.Address.PostalCode,
+			Country:     customer.Address.Country,
+			CountryCode: customer.Address.CountryCode,
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+		},
+		Payment: &models.OrderPayment{
+			ID:          loadseed.ID("order-payment", tenantID, index),
+			OrderID:     id,
+			Method:      "card",
+			Status:      state.payment,
+			Amount:      total,
+			Currency:    "USD",
+			ProcessedAt: processedAt,
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+		},
+	}
+}
@@ -0,0 +1,141 @@
+package clients
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"orders-service/internal/contracts"
+)
+
+const (
+	contractTenantID  = "acme"
+	contractProductID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+	contractOrderID   = "3f2b8a44-5c1e-4d7a-9f0b-2a6c8e1d4b90"
+)
+
+// contractCalls makes the client call each contract covers, against a stub provider at baseURL
+var contractCalls = map[string]func(t *testing.T, baseURL string) error{
+	"products-service/inventory-check": func(t *testing.T, baseURL string) error {
+		resp, err := NewProductsClient(baseURL).CheckStock([]StockCheckItem{{ProductID: contractProductID, Quantity: 2}}, contractTenantID)
+		if err == nil && len(resp.Results) == 0 {
+			t.Errorf("expected stock results")
+		}
+		return err
+	},
+	"products-service/inventory-bulk-deduct": func(t *testing.T, baseURL string) error {
+		return NewProductsClient(baseURL).DeductInventoryWithIdempotency(
+			[]InventoryItem{{ProductID: contractProductID, Quantity: 2}}, "Order ORD-1001 placed", contractOrderID, contractTenantID)
+	},
+	"products-service/inventory-bulk-restore": func(t *testing.T, baseURL string) error {
+		return NewProductsClient(baseURL).RestoreInventoryWithIdempotency(
+			[]InventoryItem{{ProductID: contractProductID, Quantity: 2}}, "Order ORD-1001 cancelled", contractOrderID, contractTenantID)
+	},
+	"products-service/product": func(t *testing.T, baseURL string) error {
+		product, err := NewProductsClient(baseURL).GetProduct(contractProductID, contractTenantID)
+		if err == nil && product.ID != contractProductID {
+			t.Errorf("expected product %s, got %q", contractProductID, product.ID)
+		}
+		return err
+	},
+	"tax-service/calculate": func(t *testing.T, baseURL string) error {
+		customerID := uuid.MustParse("5d0c7b1e-2f4a-4c6e-9a3b-8e1f2d7c6a50")
+		resp, err := NewTaxClient(baseURL+"/api/v1").CalculateTax(&TaxCalculationRequest{
+			ShippingAddress: AddressInput{City: "Bengaluru", StateCode: "KA", Country: "India", CountryCode: "IN"},
+			LineItems:       []LineItemInput{{ProductID: contractProductID, HSNCode: "6205", Quantity: 2, UnitPrice: 1499, Subtotal: 2998}},
+			ShippingAmount:  99,
+			CustomerID:      &customerID,
+		}, contractTenantID)
+		if err == nil && resp.GSTSummary == nil {
+			t.Errorf("expected a GST summary")
+		}
+		return err
+	},
+	"customers-service/customer-search": func(t *testing.T, baseURL string) error {
+		customer, err := NewCustomersClient(baseURL).(*customersClient).findByEmail("priya@example.com", contractTenantID)
+		if err == nil && customer == nil {
+			t.Errorf("expected the customer to be found by email")
+		}
+		return err
+	},
+	"customers-service/customer-create": func(t *testing.T, baseURL string) error {
+		customer, err := NewCustomersClient(baseURL).(*customersClient).createCustomer(CreateCustomerRequest{
+			Email: "priya@example.com", FirstName: "Priya", LastName: "Sharma",
+		}, contractTenantID)
+		if err == nil && customer.ID == "" {
+			t.Errorf("expected the created customer's ID")
+		}
+		return err
+	},
+	"customers-service/record-order": func(t *testing.T, baseURL string) error {
+		return NewCustomersClient(baseURL).RecordOrder("5d0c7b1e-2f4a-4c6e-9a3b-8e1f2d7c6a50", RecordOrderRequest{
+			OrderID: contractOrderID, OrderNumber: "ORD-1001", TotalAmount: 3456.76,
+		}, contractTenantID)
+	},
+}
+
+// TestClientsHonourContracts runs each client against a provider stub answering with the
+// contract's example, checking the client sends what the contract promises and can read the
+// response
+func TestClientsHonourContracts(t *testing.T) {
+	all, err := contracts.Load(os.DirFS("../../contracts"), "")
+	if err != nil {
+		t.Fatalf("failed to load contracts: %v", err)
+	}
+
+	covered := make(map[string]bool)
+	for i := range all {
+		contract := &all[i]
+		key := contract.Provider + "/" + contract.Name
+		covered[key] = true
+
+		t.Run(contract.ID(), func(t *testing.T) {
+			if err := contract.CheckExamples(); err != nil {
+				t.Fatalf("contract examples don't match its schemas: %v", err)
+			}
+			call, ok := contractCalls[key]
+			if !ok {
+				t.Fatalf("no client call exercises %s", contract.ID())
+			}
+
+			called := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				if r.Method != contract.Request.Method || !contract.MatchesPath(r.URL.Path) {
+					t.Errorf("client called %s %s, contract is for %s %s", r.Method, r.URL.Path, contract.Request.Method, contract.Request.Path)
+				}
+				if contract.Request.Schema != nil {
+					body, _ := io.ReadAll(r.Body)
+					if err := contract.Request.Schema.Validate(body); err != nil {
+						t.Errorf("request doesn't match the contract: %v", err)
+					}
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(contract.Response.Status)
+				if len(contract.Response.Example) > 0 {
+					w.Write(contract.Response.Example)
+				} else {
+					w.Write([]byte("{}"))
+				}
+			}))
+			defer server.Close()
+
+			if err := call(t, server.URL); err != nil {
+				t.Errorf("client failed on the contract's response: %v", err)
+			}
+			if !called {
+				t.Errorf("client didn't call the provider")
+			}
+		})
+	}
+
+	for key := range contractCalls {
+		if !covered[key] {
+			t.Errorf("no contract for %s", key)
+		}
+	}
+}
@@ -9,7 +9,8 @@ import (
 	"time"
 )
 
-// CustomersClient defines the interface for communicating with customers-service
+// CustomersClient defines the interface for communicating with customers-service. Its request and
+// response types are generated from contracts/customers-service.
 type CustomersClient interface {
 	// GetOrCreateCustomer finds an existing customer by email or creates a new one
 	GetOrCreateCustomer(req CreateCustomerRequest, tenantID string) (*Customer, error)
@@ -17,31 +18,6 @@ type CustomersClient interface {
 	RecordOrder(customerID string, req RecordOrderRequest, tenantID string) error
 }
 
-// CreateCustomerRequest represents a request to create a customer
-type CreateCustomerRequest struct {
-	Email     string `json:"email"`
-	FirstName string `json:"firstName"`
-	LastName  string `json:"lastName"`
-	Phone     string `json:"phone,omitempty"`
-}
-
-// RecordOrderRequest represents a request to record an order
-type RecordOrderRequest struct {
-	OrderID     string  `json:"orderId"`
-	OrderNumber string  `json:"orderNumber"`
-	TotalAmount float64 `json:"totalAmount"`
-}
-
-// Customer represents a customer from customers-service
-type Customer struct {
-	ID        string `json:"id"`
-	Email     string `json:"email"`
-	FirstName string `json:"firstName"`
-	LastName  string `json:"lastName"`
-	Phone     string `json:"phone"`
-	Status    string `json:"status"`
-}
-
 type customersClient struct {
 	baseURL    string
 	httpClient *http.Client
@@ -97,9 +73,7 @@ func (c *customersClient) findByEmail(email string, tenantID string) (*Customer,
 	}
 
 	// Backend returns {customers: [...], total, page, pageSize, totalPages}
-	var result struct {
-		Customers []Customer `json:"customers"`
-	}
+	var result customerListResponse
 	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
@@ -0,0 +1,5 @@
+package clients
+
+// Request and response types for the endpoints in contracts/ are generated from the contracts'
+// schemas; edit the contract and regenerate rather than editing contracts_gen.go
+//go:generate go run ../../cmd/contractgen -contracts ../../contracts -out contracts_gen.go
@@ -0,0 +1,284 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"orders-service/internal/serviceauth"
+)
+
+var (
+	// ErrPickupLocationUnavailable is returned when the location doesn't exist or doesn't offer click-and-collect
+	ErrPickupLocationUnavailable = errors.New("pickup location unavailable")
+	// ErrInsufficientPickupStock is returned when the pickup location can't cover the order
+	ErrInsufficientPickupStock = errors.New("insufficient stock at pickup location")
+	// ErrInsufficientStock is returned when no warehouse can cover an order line
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// InventoryClient defines the interface for click-and-collect and checkout stock operations in inventory-service
+type InventoryClient interface {
+	// GetPickupLocation fetches an active, pickup-enabled location
+	GetPickupLocation(locationID string, tenantID string) (*PickupLocation, error)
+	// DeductPickupStock deducts an order's items from its pickup location; retries for the same order are no-ops
+	DeductPickupStock(locationID string, orderID string, items []PickupStockItem, tenantID string) error
+	// RestorePickupStock returns an order's deducted items to its pickup location
+	RestorePickupStock(orderID string, tenantID string) error
+	// ListLowStock lists stock levels at or below their reorder point, optionally for one warehouse
+	ListLowStock(warehouseID string, tenantID string) ([]LowStockLevel, error)
+	// ReserveOrderStock holds a shipped order's items for ttl; retries for the same order are no-ops
+	ReserveOrderStock(orderID string, items []PickupStockItem, ttl time.Duration, tenantID string) error
+	// ConfirmOrderStock takes a paid order's reserved items out of stock
+	ConfirmOrderStock(orderID string, tenantID string) error
+	// ReleaseOrderStock returns an abandoned order's reserved items to available stock
+	ReleaseOrderStock(orderID string, tenantID string) error
+}
+
+// PickupLocation is the subset of an inventory-service warehouse needed for a pickup order
+type PickupLocation struct {
+	ID                 string  `json:"id"`
+	Name               string  `json:"name"`
+	Address1           string  `json:"address1"`
+	Address2           *string `json:"address2,omitempty"`
+	City               string  `json:"city"`
+	State              string  `json:"state"`
+	PostalCode         string  `json:"postalCode"`
+	Country            string  `json:"country"`
+	Phone              *string `json:"phone,omitempty"`
+	PickupInstructions *string `json:"pickupInstructions,omitempty"`
+}
+
+// PickupStockItem represents an order item deducted from a pickup location
+type PickupStockItem struct {
+	ProductID string `json:"productId"`
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+}
+
+// LowStockLevel is an inventory-service stock level at or below its reorder point
+type LowStockLevel struct {
+	WarehouseID       string  `json:"warehouseId"`
+	ProductID         string  `json:"productId"`
+	VariantID         *string `json:"variantId,omitempty"`
+	VendorID          string  `json:"vendorId,omitempty"`
+	QuantityOnHand    int     `json:"quantityOnHand"`
+	QuantityReserved  int     `json:"quantityReserved"`
+	QuantityAvailable int     `json:"quantityAvailable"`
+	ReorderPoint      int     `json:"reorderPoint"`
+	ReorderQuantity   int     `json:"reorderQuantity"`
+}
+
+type lowStockResponse struct {
+	Success bool            `json:"success"`
+	Data    []LowStockLevel `json:"data"`
+}
+
+type pickupLocationResponse struct {
+	Success bool           `json:"success"`
+	Data    PickupLocation `json:"data"`
+}
+
+type inventoryClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewInventoryClient creates a new inventory service client
+func NewInventoryClient(baseURL string) InventoryClient {
+	return &inventoryClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetPickupLocation fetches an active, pickup-enabled location
+func (c *inventoryClient) GetPickupLocation(locationID string, tenantID string) (*PickupLocation, error) {
+	url := fmt.Sprintf("%s/internal/pickup-locations/%s", c.baseURL, locationID)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	serviceauth.Sign(req, "orders-service")
+
+	req.Header.Set("X-Tenant-ID", tenantID)
+	req.Header.Set("X-Internal-Service", "orders-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+		return nil, ErrPickupLocationUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("inventory service returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var locationResp pickupLocationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&locationResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &locationResp.Data, nil
+}
+
+// DeductPickupStock deducts an order's items from its pickup location
+func (c *inventoryClient) DeductPickupStock(locationID string, orderID string, items []PickupStockItem, tenantID string) error {
+	reqBody := map[string]interface{}{
+		"warehouseId": locationID,
+		"orderId":     orderID,
+		"items":       items,
+	}
+
+	resp, err := c.post("/internal/stock/deduct", reqBody, tenantID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrPickupLocationUnavailable
+	case http.StatusConflict:
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %s", ErrInsufficientPickupStock, string(bodyBytes))
+	default:
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("inventory service returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+}
+
+// RestorePickupStock returns an order's deducted items to its pickup location (on cancellation)
+func (c *inventoryClient) RestorePickupStock(orderID string, tenantID string) error {
+	resp, err := c.post("/internal/stock/restore", map[string]interface{}{"orderId": orderID}, tenantID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("inventory service returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+// ListLowStock lists stock levels at or below their reorder point
+func (c *inventoryClient) ListLowStock(warehouseID string, tenantID string) ([]LowStockLevel, error) {
+	url := fmt.Sprintf("%s/internal/stock/low", c.baseURL)
+	if warehouseID != "" {
+		url += "?warehouseId=" + warehouseID
+	}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	serviceauth.Sign(req, "orders-service")
+
+	req.Header.Set("X-Tenant-ID", tenantID)
+	req.Header.Set("X-Internal-Service", "orders-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("inventory service returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var stockResp lowStockResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stockResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return stockResp.Data, nil
+}
+
+// ReserveOrderStock holds a shipped order's items during checkout
+func (c *inventoryClient) ReserveOrderStock(orderID string, items []PickupStockItem, ttl time.Duration, tenantID string) error {
+	reqBody := map[string]interface{}{
+		"orderId":    orderID,
+		"items":      items,
+		"ttlSeconds": int(ttl.Seconds()),
+	}
+
+	resp, err := c.post("/internal/stock/reserve", reqBody, tenantID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusConflict:
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %s", ErrInsufficientStock, string(bodyBytes))
+	default:
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("inventory service returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+}
+
+// ConfirmOrderStock takes a paid order's reserved items out of stock
+func (c *inventoryClient) ConfirmOrderStock(orderID string, tenantID string) error {
+	return c.postOrderStock("/internal/stock/confirm", orderID, tenantID)
+}
+
+// ReleaseOrderStock returns an abandoned order's reserved items to available stock
+func (c *inventoryClient) ReleaseOrderStock(orderID string, tenantID string) error {
+	return c.postOrderStock("/internal/stock/release", orderID, tenantID)
+}
+
+func (c *inventoryClient) postOrderStock(path string, orderID string, tenantID string) error {
+	resp, err := c.post(path, map[string]interface{}{"orderId": orderID}, tenantID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("inventory service returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+	return nil
+}
+
+func (c *inventoryClient) post(path string, reqBody interface{}, tenantID string) (*http.Response, error) {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.baseURL+path, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	serviceauth.Sign(req, "orders-service")
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", tenantID)
+	req.Header.Set("X-Internal-Service", "orders-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return resp, nil
+}
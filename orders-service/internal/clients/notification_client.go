@@ -19,10 +19,20 @@ type NotificationClient interface {
 	SendOrderShipped(ctx context.Context, order *OrderNotification) error
 	// SendOrderDelivered sends delivery confirmation email
 	SendOrderDelivered(ctx context.Context, order *OrderNotification) error
+	// SendOrderReadyForPickup tells a click-and-collect customer their order can be collected
+	SendOrderReadyForPickup(ctx context.Context, order *OrderNotification) error
 	// SendOrderCancelled sends cancellation email
 	SendOrderCancelled(ctx context.Context, order *OrderNotification) error
 	// SendOrderRefunded sends refund confirmation email
 	SendOrderRefunded(ctx context.Context, order *OrderNotification) error
+	// SendOrderUpdated tells the customer staff changed their order's items, and what they owe or get back
+	SendOrderUpdated(ctx context.Context, order *OrderNotification) error
+	// SendOrderMessage sends an order status update by SMS or WhatsApp, using order.OrderStatus as set by the caller
+	SendOrderMessage(ctx context.Context, channel string, order *OrderNotification) error
+	// SendOrderDigest sends a customer several order updates in one message
+	SendOrderDigest(ctx context.Context, digest *OrderDigestNotification) error
+	// SendScheduledReport emails a rendered report's download link to one recipient
+	SendScheduledReport(ctx context.Context, report *ScheduledReportNotification) error
 }
 
 // notificationClient implements NotificationClient
@@ -50,6 +60,7 @@ func NewNotificationClient() NotificationClient {
 type SendNotificationRequest struct {
 	Channel        string                 `json:"channel"`
 	RecipientEmail string                 `json:"recipientEmail"`
+	RecipientPhone string                 `json:"recipientPhone,omitempty"` // For SMS and WHATSAPP
 	Subject        string                 `json:"subject"`
 	TemplateName   string                 `json:"templateName,omitempty"`
 	Variables      map[string]interface{} `json:"variables,omitempty"`
@@ -63,6 +74,7 @@ type OrderNotification struct {
 	OrderNumber       string
 	OrderDate         string
 	CustomerEmail     string
+	CustomerPhone     string
 	CustomerName      string
 	OrderStatus       string
 	TrackingURL       string
@@ -84,13 +96,39 @@ type OrderNotification struct {
 	EstimatedDelivery string
 	DeliveryDate      string
 	DeliveryLocation  string
+	PickupLocation    *Address
+	PickupInstructions string
 	CancelledDate     string
 	CancellationReason string
 	RefundAmount      string
 	RefundDays        string
+	AmountDue         string // Set when an edit raised the total of a paid order
 	BusinessName      string
 }
 
+// OrderDigestNotification is several updates to a customer's orders sent together on one channel
+type OrderDigestNotification struct {
+	TenantID      string
+	Channel       string
+	CustomerEmail string
+	CustomerPhone string
+	CustomerName  string
+	Updates       []*OrderNotification // Oldest first; OrderStatus is set on each
+}
+
+// ScheduledReportNotification contains a rendered scheduled report for one recipient
+type ScheduledReportNotification struct {
+	TenantID       string
+	RecipientEmail string
+	ReportName     string
+	ReportType     string
+	Period         string
+	Format         string
+	RowCount       int
+	DownloadURL    string
+	ExpiresAt      string
+}
+
 // OrderItem represents an item in an order notification
 type OrderItem struct {
 	Name     string `json:"name"`
@@ -99,6 +137,8 @@ type OrderItem struct {
 	Quantity int    `json:"quantity"`
 	Price    string `json:"price"`
 	Currency string `json:"currency"`
+
+	Properties map[string]string `json:"properties,omitempty"` // Customizations to show, e.g. engraving text
 }
 
 // Address represents a shipping/billing address
@@ -187,6 +227,31 @@ func (c *notificationClient) SendOrderDelivered(ctx context.Context, order *Orde
 	return nil
 }
 
+// SendOrderReadyForPickup sends the ready-for-pickup email for a click-and-collect order
+func (c *notificationClient) SendOrderReadyForPickup(ctx context.Context, order *OrderNotification) error {
+	if order == nil {
+		log.Printf("[NotificationClient] Skipping ready for pickup notification - order is nil")
+		return nil
+	}
+	if order.CustomerEmail == "" {
+		log.Printf("[NotificationClient] Skipping ready for pickup notification - no customer email for order %s", order.OrderNumber)
+		return nil
+	}
+
+	order.OrderStatus = "READY_FOR_PICKUP"
+	req := c.buildNotificationRequest(order)
+	req.Subject = fmt.Sprintf("Your Order Is Ready for Pickup - #%s", order.OrderNumber)
+	req.TemplateName = "order_customer" // Unified template, uses OrderStatus to determine content
+
+	if err := c.send(ctx, order.TenantID, req); err != nil {
+		log.Printf("[NotificationClient] Failed to send ready for pickup notification: %v", err)
+		return err
+	}
+
+	log.Printf("[NotificationClient] Ready for pickup notification sent for order %s to %s", order.OrderNumber, order.CustomerEmail)
+	return nil
+}
+
 // SendOrderCancelled sends cancellation email
 func (c *notificationClient) SendOrderCancelled(ctx context.Context, order *OrderNotification) error {
 	if order == nil {
@@ -237,6 +302,125 @@ func (c *notificationClient) SendOrderRefunded(ctx context.Context, order *Order
 	return nil
 }
 
+// SendOrderUpdated tells the customer staff changed their order's items
+func (c *notificationClient) SendOrderUpdated(ctx context.Context, order *OrderNotification) error {
+	if order == nil {
+		log.Printf("[NotificationClient] Skipping order updated notification - order is nil")
+		return nil
+	}
+	if order.CustomerEmail == "" {
+		log.Printf("[NotificationClient] Skipping order updated notification - no customer email for order %s", order.OrderNumber)
+		return nil
+	}
+
+	order.OrderStatus = "UPDATED"
+	req := c.buildNotificationRequest(order)
+	req.Subject = fmt.Sprintf("Your Order Has Been Updated - #%s", order.OrderNumber)
+	req.TemplateName = "order_customer" // Unified template, uses OrderStatus to determine content
+
+	if err := c.send(ctx, order.TenantID, req); err != nil {
+		log.Printf("[NotificationClient] Failed to send order updated notification: %v", err)
+		return err
+	}
+
+	log.Printf("[NotificationClient] Order updated notification sent for order %s to %s", order.OrderNumber, order.CustomerEmail)
+	return nil
+}
+
+// SendOrderMessage sends an order status update as a text message
+func (c *notificationClient) SendOrderMessage(ctx context.Context, channel string, order *OrderNotification) error {
+	if order == nil {
+		log.Printf("[NotificationClient] Skipping %s notification - order is nil", channel)
+		return nil
+	}
+	if order.CustomerPhone == "" {
+		log.Printf("[NotificationClient] Skipping %s notification - no customer phone for order %s", channel, order.OrderNumber)
+		return nil
+	}
+
+	req := c.buildNotificationRequest(order)
+	req.Channel = channel
+	req.RecipientEmail = ""
+	req.RecipientPhone = order.CustomerPhone
+	req.TemplateName = "order_customer_text" // Short form of order_customer, also keyed on OrderStatus
+
+	if err := c.send(ctx, order.TenantID, req); err != nil {
+		log.Printf("[NotificationClient] Failed to send %s notification: %v", channel, err)
+		return err
+	}
+
+	log.Printf("[NotificationClient] %s %s notification sent for order %s", channel, order.OrderStatus, order.OrderNumber)
+	return nil
+}
+
+// SendOrderDigest sends a customer several order updates in one message
+func (c *notificationClient) SendOrderDigest(ctx context.Context, digest *OrderDigestNotification) error {
+	if digest == nil || len(digest.Updates) == 0 {
+		log.Printf("[NotificationClient] Skipping order digest - no updates")
+		return nil
+	}
+
+	var updates []map[string]interface{}
+	for _, update := range digest.Updates {
+		updates = append(updates, c.buildNotificationRequest(update).Variables)
+	}
+	req := SendNotificationRequest{
+		Channel:        digest.Channel,
+		RecipientEmail: digest.CustomerEmail,
+		Subject:        fmt.Sprintf("Updates to Your Orders (%d)", len(digest.Updates)),
+		TemplateName:   "order_customer_digest",
+		Variables: map[string]interface{}{
+			"customerName":  digest.CustomerName,
+			"customerEmail": digest.CustomerEmail,
+			"updates":       updates,
+		},
+	}
+	if digest.Channel != "EMAIL" {
+		req.RecipientEmail = ""
+		req.RecipientPhone = digest.CustomerPhone
+	}
+
+	if err := c.send(ctx, digest.TenantID, req); err != nil {
+		log.Printf("[NotificationClient] Failed to send order digest: %v", err)
+		return err
+	}
+
+	log.Printf("[NotificationClient] %s order digest with %d updates sent", digest.Channel, len(digest.Updates))
+	return nil
+}
+
+// SendScheduledReport emails a rendered report's download link to one recipient
+func (c *notificationClient) SendScheduledReport(ctx context.Context, report *ScheduledReportNotification) error {
+	if report == nil || report.RecipientEmail == "" {
+		log.Printf("[NotificationClient] Skipping scheduled report - no recipient")
+		return nil
+	}
+
+	req := SendNotificationRequest{
+		Channel:        "EMAIL",
+		RecipientEmail: report.RecipientEmail,
+		Subject:        fmt.Sprintf("%s - %s", report.ReportName, report.Period),
+		TemplateName:   "scheduled_report",
+		Variables: map[string]interface{}{
+			"reportName":  report.ReportName,
+			"reportType":  report.ReportType,
+			"period":      report.Period,
+			"format":      report.Format,
+			"rowCount":    report.RowCount,
+			"downloadUrl": report.DownloadURL,
+			"expiresAt":   report.ExpiresAt,
+		},
+	}
+
+	if err := c.send(ctx, report.TenantID, req); err != nil {
+		log.Printf("[NotificationClient] Failed to send scheduled report: %v", err)
+		return err
+	}
+
+	log.Printf("[NotificationClient] Scheduled report %q sent to %s", report.ReportName, report.RecipientEmail)
+	return nil
+}
+
 // buildNotificationRequest builds the notification request from order data
 func (c *notificationClient) buildNotificationRequest(order *OrderNotification) SendNotificationRequest {
 	// Convert items to map format
@@ -266,6 +450,20 @@ func (c *notificationClient) buildNotificationRequest(order *OrderNotification)
 		}
 	}
 
+	// Build pickup location map for click-and-collect orders
+	var pickupLocation map[string]interface{}
+	if order.PickupLocation != nil {
+		pickupLocation = map[string]interface{}{
+			"name":       order.PickupLocation.Name,
+			"line1":      order.PickupLocation.Line1,
+			"line2":      order.PickupLocation.Line2,
+			"city":       order.PickupLocation.City,
+			"state":      order.PickupLocation.State,
+			"postalCode": order.PickupLocation.PostalCode,
+			"country":    order.PickupLocation.Country,
+		}
+	}
+
 	return SendNotificationRequest{
 		Channel:        "EMAIL",
 		RecipientEmail: order.CustomerEmail,
@@ -276,6 +474,7 @@ func (c *notificationClient) buildNotificationRequest(order *OrderNotification)
 			"orderStatus":        order.OrderStatus,
 			"customerName":       order.CustomerName,
 			"customerEmail":      order.CustomerEmail,
+			"customerPhone":      order.CustomerPhone,
 			"trackingUrl":        order.TrackingURL,
 			"orderDetailsUrl":    order.OrderDetailsURL,
 			"shopUrl":            order.ShopURL,
@@ -295,10 +494,13 @@ func (c *notificationClient) buildNotificationRequest(order *OrderNotification)
 			"estimatedDelivery":  order.EstimatedDelivery,
 			"deliveryDate":       order.DeliveryDate,
 			"deliveryLocation":   order.DeliveryLocation,
+			"pickupLocation":     pickupLocation,
+			"pickupInstructions": order.PickupInstructions,
 			"cancelledDate":      order.CancelledDate,
 			"cancellationReason": order.CancellationReason,
 			"refundAmount":       order.RefundAmount,
 			"refundDays":         order.RefundDays,
+			"amountDue":          order.AmountDue,
 			"businessName":       order.BusinessName,
 		},
 	}
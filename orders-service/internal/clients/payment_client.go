@@ -3,20 +3,30 @@ package clients
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
+	"orders-service/internal/serviceauth"
 )
 
+// ErrPaymentIntentRejected is returned when payment-service refuses a payment intent, e.g. for a
+// gateway the tenant hasn't configured. Retrying the same request won't help.
+var ErrPaymentIntentRejected = errors.New("payment intent rejected")
+
 // PaymentClient defines the interface for communicating with payment-service
 type PaymentClient interface {
+	// CreatePaymentIntent starts a payment for an order, returning the gateway's intent as-is for the storefront
+	CreatePaymentIntent(req CreatePaymentIntentRequest, tenantID string) (json.RawMessage, error)
 	// CreateRefund creates a refund for a payment
 	CreateRefund(paymentID uuid.UUID, req CreateRefundRequest, tenantID string) (*RefundResponse, error)
 	// GetPaymentsByOrder retrieves payments for an order
 	GetPaymentsByOrder(orderID uuid.UUID, tenantID string) ([]Payment, error)
+	// ExecuteRefundPlan refunds an order across its payments and gift cards in one call
+	ExecuteRefundPlan(req RefundPlanRequest, tenantID string) (*RefundPlanResponse, error)
 }
 
 // CreateRefundRequest represents a request to create a refund
@@ -26,6 +36,61 @@ type CreateRefundRequest struct {
 	Notes  string  `json:"notes,omitempty"`
 }
 
+// RefundPlanRequest refunds an order across the tenders it was paid with; payment-service
+// executes each line on its own and reports every line's outcome
+type RefundPlanRequest struct {
+	OrderID  string           `json:"orderId"`
+	ReturnID string           `json:"returnId,omitempty"`
+	Reason   string           `json:"reason,omitempty"`
+	Notes    string           `json:"notes,omitempty"`
+	Lines    []RefundPlanLine `json:"lines"`
+}
+
+// RefundPlanLine is the part of a refund plan paid out to one payment or gift card
+type RefundPlanLine struct {
+	Tender       string   `json:"tender"` // PAYMENT or GIFT_CARD
+	PaymentID    string   `json:"paymentId,omitempty"`
+	GiftCardCode string   `json:"giftCardCode,omitempty"`
+	Amount       float64  `json:"amount"`
+	Tenders      []string `json:"tenders,omitempty"` // Overrides the tenant's refund fallback chain for a PAYMENT line
+}
+
+// RefundPlanResponse has one result per plan line, in request order
+type RefundPlanResponse struct {
+	OrderID string                 `json:"orderId"`
+	Lines   []RefundPlanLineResult `json:"lines"`
+}
+
+// RefundPlanLineResult is the outcome of one refund plan line
+type RefundPlanLineResult struct {
+	Tender    string  `json:"tender"`
+	Amount    float64 `json:"amount"`
+	Status    string  `json:"status"`
+	RefundID  string  `json:"refundId,omitempty"`
+	Reference string  `json:"reference,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// CreatePaymentIntentRequest represents a request to start paying for an order
+type CreatePaymentIntentRequest struct {
+	TenantID      string            `json:"tenantId"`
+	OrderID       string            `json:"orderId"`
+	Amount        float64           `json:"amount"`
+	Currency      string            `json:"currency"`
+	CustomerID    *uuid.UUID        `json:"customerId,omitempty"`
+	GatewayType   string            `json:"gatewayType"`
+	PaymentMethod string            `json:"paymentMethod,omitempty"`
+	CustomerEmail string            `json:"customerEmail,omitempty"`
+	CustomerPhone string            `json:"customerPhone,omitempty"`
+	CustomerName  string            `json:"customerName,omitempty"`
+	Description   string            `json:"description,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	ReturnURL     string            `json:"returnUrl,omitempty"`
+	CancelURL     string            `json:"cancelUrl,omitempty"`
+	UPIApp        string            `json:"upiApp,omitempty"`
+	VPA           string            `json:"vpa,omitempty"`
+}
+
 // RefundResponse represents a refund response from payment-service
 type RefundResponse struct {
 	ID              string  `json:"id"`
@@ -64,6 +129,47 @@ func NewPaymentClient(baseURL string) PaymentClient {
 	}
 }
 
+// CreatePaymentIntent starts a payment for an order
+func (c *paymentClient) CreatePaymentIntent(req CreatePaymentIntentRequest, tenantID string) (json.RawMessage, error) {
+	url := fmt.Sprintf("%s/api/v1/payments/create-intent", c.baseURL)
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payment intent request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Tenant-ID", tenantID)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call payment service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: status %d: %s", ErrPaymentIntentRejected, resp.StatusCode, string(body))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("payment service returned status %d: %s", resp.StatusCode, string(body))
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("payment service returned invalid JSON")
+	}
+
+	return json.RawMessage(body), nil
+}
+
 // CreateRefund creates a refund for a payment
 func (c *paymentClient) CreateRefund(paymentID uuid.UUID, req CreateRefundRequest, tenantID string) (*RefundResponse, error) {
 	url := fmt.Sprintf("%s/api/v1/payments/%s/refund", c.baseURL, paymentID.String())
@@ -139,3 +245,48 @@ func (c *paymentClient) GetPaymentsByOrder(orderID uuid.UUID, tenantID string) (
 
 	return payments, nil
 }
+
+// ExecuteRefundPlan refunds an order across its payments and gift cards in one call
+func (c *paymentClient) ExecuteRefundPlan(req RefundPlanRequest, tenantID string) (*RefundPlanResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/refunds/plans", c.baseURL)
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal refund plan: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	serviceauth.Sign(httpReq, "orders-service")
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Tenant-ID", tenantID)
+	httpReq.Header.Set("X-Internal-Service", "orders-service")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call payment service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("payment service returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var plan RefundPlanResponse
+	if err := json.Unmarshal(body, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse refund plan response: %w", err)
+	}
+	if len(plan.Lines) != len(req.Lines) {
+		return nil, fmt.Errorf("payment service returned %d refund plan results for %d lines", len(plan.Lines), len(req.Lines))
+	}
+
+	return &plan, nil
+}
@@ -9,7 +9,8 @@ import (
 	"time"
 )
 
-// ProductsClient defines the interface for communicating with products-service
+// ProductsClient defines the interface for communicating with products-service. Its request and
+// response types are generated from contracts/products-service.
 type ProductsClient interface {
 	CheckStock(items []StockCheckItem, tenantID string) (*StockCheckResponse, error)
 	DeductInventory(items []InventoryItem, reason string, tenantID string) error
@@ -22,63 +23,6 @@ type ProductsClient interface {
 	GetProduct(productID string, tenantID string) (*Product, error)
 }
 
-// StockCheckItem represents a product to check
-type StockCheckItem struct {
-	ProductID string `json:"productId"`
-	Quantity  int    `json:"quantity"`
-}
-
-// StockCheckResult represents stock availability
-type StockCheckResult struct {
-	ProductID   string `json:"productId"`
-	Available   bool   `json:"available"`
-	InStock     int    `json:"inStock"`
-	Requested   int    `json:"requested"`
-	ProductName string `json:"productName,omitempty"`
-}
-
-// StockCheckResponse is the response from stock check
-type StockCheckResponse struct {
-	Success    bool               `json:"success"`
-	AllInStock bool               `json:"allInStock"`
-	Results    []StockCheckResult `json:"results"`
-	Message    *string            `json:"message,omitempty"`
-}
-
-// InventoryItem represents an item for inventory operations
-type InventoryItem struct {
-	ProductID string `json:"productId"`
-	Quantity  int    `json:"quantity"`
-}
-
-// InventoryRequest is the request for bulk inventory operations
-type InventoryRequest struct {
-	Items          []InventoryItem `json:"items"`
-	Reason         string          `json:"reason"`
-	OrderID        string          `json:"orderId,omitempty"`        // For traceability
-	IdempotencyKey string          `json:"idempotencyKey,omitempty"` // Prevents duplicate operations
-}
-
-// Product represents the product fields required for shipping calculations
-type Product struct {
-	ID         string             `json:"id"`
-	Weight     *string            `json:"weight,omitempty"`
-	Dimensions *ProductDimensions `json:"dimensions,omitempty"`
-}
-
-// ProductDimensions represents stored product dimensions
-type ProductDimensions struct {
-	Length string `json:"length"`
-	Width  string `json:"width"`
-	Height string `json:"height"`
-	Unit   string `json:"unit"`
-}
-
-type productResponse struct {
-	Success bool    `json:"success"`
-	Data    Product `json:"data"`
-}
-
 type productsClient struct {
 	baseURL    string
 	httpClient *http.Client
@@ -96,11 +40,7 @@ func NewProductsClient(baseURL string) ProductsClient {
 
 // CheckStock checks if products are available in requested quantities
 func (c *productsClient) CheckStock(items []StockCheckItem, tenantID string) (*StockCheckResponse, error) {
-	reqBody := map[string]interface{}{
-		"items": items,
-	}
-
-	body, err := json.Marshal(reqBody)
+	body, err := json.Marshal(stockCheckRequest{Items: items})
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -17,6 +18,12 @@ type ShippingClient interface {
 	CreateShipment(ctx context.Context, req *CreateShipmentRequest) (*ShipmentResponse, error)
 	// GetShippingSettings fetches tenant's shipping settings including warehouse address
 	GetShippingSettings(ctx context.Context, tenantID string) (*ShippingSettings, error)
+	// GetShipmentsByOrder lists the shipments created for an order
+	GetShipmentsByOrder(ctx context.Context, tenantID, orderID string) ([]ShipmentResponse, error)
+	// GetShipmentLabel fetches the carrier's shipping label PDF for a shipment
+	GetShipmentLabel(ctx context.Context, tenantID, shipmentID string) ([]byte, error)
+	// CreateReturnLabel books the customer's return parcel with a carrier and returns its label
+	CreateReturnLabel(ctx context.Context, req *ReturnLabelRequest) (*ReturnLabelResponse, error)
 }
 
 // shippingClient implements ShippingClient
@@ -95,6 +102,31 @@ type ShipmentResponse struct {
 	TrackingNumber string  `json:"trackingNumber,omitempty"`
 	Status         string  `json:"status"`
 	ShippingCost   float64 `json:"shippingCost"`
+	LabelURL       string  `json:"labelUrl,omitempty"`
+}
+
+// ReturnLabelRequest contains the data needed to book a return parcel from the customer back to the warehouse
+type ReturnLabelRequest struct {
+	TenantID        string           `json:"-"` // Passed via header
+	OrderID         string           `json:"orderId"`
+	OrderNumber     string           `json:"orderNumber"`
+	ReturnID        string           `json:"returnId"`
+	RMANumber       string           `json:"rmaNumber"`
+	CustomerAddress *ShipmentAddress `json:"customerAddress"`
+	ReturnAddress   *ShipmentAddress `json:"returnAddress"`
+	Weight          float64          `json:"weight"`
+	Length          float64          `json:"length"`
+	Width           float64          `json:"width"`
+	Height          float64          `json:"height"`
+}
+
+// ReturnLabelResponse contains the return label created by the carrier
+type ReturnLabelResponse struct {
+	ShipmentID     string     `json:"shipmentId"`
+	Carrier        string     `json:"carrier"`
+	TrackingNumber string     `json:"trackingNumber"`
+	LabelURL       string     `json:"labelUrl"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
 }
 
 // ShippingSettings contains tenant's shipping configuration including warehouse address
@@ -191,3 +223,107 @@ func (c *shippingClient) GetShippingSettings(ctx context.Context, tenantID strin
 
 	return &settings, nil
 }
+
+// GetShipmentsByOrder lists the shipments created for an order
+func (c *shippingClient) GetShipmentsByOrder(ctx context.Context, tenantID, orderID string) ([]ShipmentResponse, error) {
+	url := fmt.Sprintf("%s/api/shipments/order/%s", c.baseURL, orderID)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-Tenant-ID", tenantID)
+	httpReq.Header.Set("X-Internal-Service", "orders-service")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		log.Printf("[ShippingClient] Get shipments for order %s failed with status %d", orderID, resp.StatusCode)
+		return nil, fmt.Errorf("shipping-service returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data []ShipmentResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Data, nil
+}
+
+// GetShipmentLabel fetches the carrier's shipping label PDF for a shipment
+func (c *shippingClient) GetShipmentLabel(ctx context.Context, tenantID, shipmentID string) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/shipments/%s/label", c.baseURL, shipmentID)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-Tenant-ID", tenantID)
+	httpReq.Header.Set("X-Internal-Service", "orders-service")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		log.Printf("[ShippingClient] Get label for shipment %s failed with status %d", shipmentID, resp.StatusCode)
+		return nil, fmt.Errorf("shipping-service returned status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// CreateReturnLabel books the customer's return parcel with a carrier and returns its label
+func (c *shippingClient) CreateReturnLabel(ctx context.Context, req *ReturnLabelRequest) (*ReturnLabelResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request is nil")
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/returns/label", c.baseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Tenant-ID", req.TenantID)
+	httpReq.Header.Set("X-Internal-Service", "orders-service")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var errResp map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		log.Printf("[ShippingClient] Create return label for RMA %s failed with status %d: %v", req.RMANumber, resp.StatusCode, errResp)
+		return nil, fmt.Errorf("shipping-service returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data *ReturnLabelResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("shipping-service returned no return label")
+	}
+
+	return result.Data, nil
+}
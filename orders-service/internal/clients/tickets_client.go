@@ -0,0 +1,95 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"orders-service/internal/serviceauth"
+)
+
+// CreateTicketRequest represents a ticket raised automatically in tickets-service
+type CreateTicketRequest struct {
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Type        string                 `json:"type"`
+	Priority    string                 `json:"priority"`
+	Tags        []string               `json:"tags,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// TicketResponse represents the ticket returned by tickets-service
+type TicketResponse struct {
+	ID           string `json:"id"`
+	TicketNumber string `json:"ticketNumber"`
+	Status       string `json:"status"`
+}
+
+// TicketsClient handles communication with the tickets service
+type TicketsClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewTicketsClient creates a new tickets service client
+func NewTicketsClient() *TicketsClient {
+	baseURL := os.Getenv("TICKETS_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://tickets-service:8080"
+	}
+
+	return &TicketsClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// CreateTicket creates a ticket through tickets-service's internal endpoint
+func (c *TicketsClient) CreateTicket(ctx context.Context, tenantID string, req CreateTicketRequest) (*TicketResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/internal/tickets", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	serviceauth.Sign(httpReq, "orders-service")
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Tenant-ID", tenantID)
+	httpReq.Header.Set("X-Internal-Service", "orders-service")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tickets service returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Success bool           `json:"success"`
+		Data    TicketResponse `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &result.Data, nil
+}
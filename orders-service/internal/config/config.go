@@ -4,22 +4,26 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	App      AppConfig
-	RedisURL string
+	Server      ServerConfig
+	Database    DatabaseConfig
+	App         AppConfig
+	RedisURL    string
+	StuckOrders StuckOrderConfig
+	Checkout    CheckoutConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Host string
-	Port int
+	Host            string
+	Port            int
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
 }
 
 // DatabaseConfig holds database configuration
@@ -39,12 +43,26 @@ type AppConfig struct {
 	JWTSecret   string
 }
 
+// StuckOrderConfig holds how long an order may sit in a state before the watchdog escalates it
+type StuckOrderConfig struct {
+	PaidNotFulfilledHours   int
+	ShippedNotDeliveredDays int
+}
+
+// CheckoutConfig holds how the checkout saga holds stock
+type CheckoutConfig struct {
+	// HoldTTL is how long a checked-out order's stock is held for payment before the order is
+	// cancelled
+	HoldTTL time.Duration
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	config := &Config{
 		Server: ServerConfig{
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
-			Port: getEnvAsInt("SERVER_PORT", 8080),
+			Host:            getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:            getEnvAsInt("SERVER_PORT", 8080),
+			ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -60,6 +78,13 @@ func Load() (*Config, error) {
 			JWTSecret:   secrets.GetJWTSecret(), // Fetch from GCP Secret Manager if enabled
 		},
 		RedisURL: getEnv("REDIS_URL", "redis://redis.redis-marketplace.svc.cluster.local:6379/0"),
+		StuckOrders: StuckOrderConfig{
+			PaidNotFulfilledHours:   getEnvAsInt("STUCK_ORDER_PAID_HOURS", 48),
+			ShippedNotDeliveredDays: getEnvAsInt("STUCK_ORDER_SHIPPED_DAYS", 14),
+		},
+		Checkout: CheckoutConfig{
+			HoldTTL: time.Duration(getEnvAsInt("CHECKOUT_HOLD_MINUTES", 30)) * time.Minute,
+		},
 	}
 
 	return config, nil
@@ -103,3 +128,12 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,87 @@
+package contracts
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["id", "items"],
+  "properties": {
+    "id": {"type": "string", "format": "uuid"},
+    "note": {"type": ["string", "null"]},
+    "status": {"type": "string", "enum": ["ACTIVE", "INACTIVE"]},
+    "items": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["quantity"],
+        "properties": {"quantity": {"type": "integer"}, "price": {"type": "number"}}
+      }
+    }
+  }
+}`
+
+func TestSchemaValidate(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(testSchema), &schema); err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{"valid", `{"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "items": [{"quantity": 2, "price": 10}]}`, ""},
+		{"extra properties allowed", `{"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "items": [], "added": true}`, ""},
+		{"nullable", `{"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "items": [], "note": null}`, ""},
+		{"missing required", `{"items": []}`, `$: missing required property "id"`},
+		{"wrong type", `{"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "items": {}}`, "$.items: is object, want array"},
+		{"not null", `{"id": null, "items": []}`, "$.id: is null, want string"},
+		{"bad uuid", `{"id": "42", "items": []}`, `$.id: "42" is not a uuid`},
+		{"not in enum", `{"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "items": [], "status": "GONE"}`, "$.status: GONE is not one of"},
+		{"integer", `{"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "items": [{"quantity": 1.5}]}`, "$.items[0].quantity: is number, want integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.doc))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPropertiesKeepSchemaOrder(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(testSchema), &schema); err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	var names []string
+	for _, prop := range schema.Properties {
+		names = append(names, prop.Name)
+	}
+	if got := strings.Join(names, ","); got != "id,note,status,items" {
+		t.Errorf("expected properties in schema order, got %s", got)
+	}
+}
+
+func TestMatchesPath(t *testing.T) {
+	contract := Contract{Request: Request{Path: "/api/v1/customers/{id}/record-order"}}
+	if !contract.MatchesPath("/api/v1/customers/5d0c7b1e-2f4a-4c6e-9a3b-8e1f2d7c6a50/record-order") {
+		t.Errorf("expected path parameter to match")
+	}
+	if contract.MatchesPath("/api/v1/customers/record-order") {
+		t.Errorf("expected missing segment not to match")
+	}
+}
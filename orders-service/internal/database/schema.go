@@ -0,0 +1,121 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"orders-service/internal/models"
+)
+
+// serviceName names the service in the migration lock key and log messages
+const serviceName = "orders-service"
+
+// BaselineVersion is the last SQL migration folded into the model schema. Databases that
+// predate versioned migrations already have everything up to it, and new databases get it from
+// SyncModels, so only files numbered after it are applied from SQL. The older files include
+// seed data and non-idempotent statements and must never be replayed.
+const BaselineVersion = 26
+
+// schemaModels lists every model whose table AutoMigrate keeps in sync
+func schemaModels() []interface{} {
+	return []interface{}{
+		&models.Order{},
+		&models.OrderItem{},
+		&models.OrderCustomer{},
+		&models.OrderShipping{},
+		&models.OrderPickup{},
+		&models.OrderPayment{},
+		&models.OrderTimeline{},
+		&models.OrderDiscount{},
+		&models.OrderRefund{},
+		&models.OrderSplit{},
+		&models.Return{},
+		&models.ReturnItem{},
+		&models.ReturnTimeline{},
+		&models.ReturnPolicy{},
+		&models.ShippingMethod{},
+		&models.CancellationSettings{},
+		&models.ReceiptSettings{},
+		&models.ReceiptDocument{},
+		&models.VendorDailyStat{},
+		&models.AnalyticsEvent{},
+		&models.TenantDailyMetric{},
+		&models.TenantDailyCategorySale{},
+		&models.ReportSchedule{},
+		&models.ReportDelivery{},
+		&models.OrderIntegration{},
+		&models.OrderIntegrationDelivery{},
+		&models.StuckOrderAlert{},
+		&models.OrderNumberSettings{},
+		&models.OrderNumberSequence{},
+		&models.OrderImportJob{},
+		&models.OrderImportError{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.CheckoutSaga{},
+		&models.IdempotencyRecord{},
+		&models.OrderRevision{},
+		&models.OrderFulfillment{},
+		&models.OrderFulfillmentItem{},
+		&models.NotificationRoutingSettings{},
+		&models.OrderNotificationLog{},
+	}
+}
+
+// SyncModels brings the schema in line with the GORM models. It must only run while holding
+// the migration lock: concurrent AutoMigrate runs from several replicas race on constraint
+// changes and have left tables without their constraints.
+func SyncModels(db *gorm.DB) error {
+	// Pre-migration: Drop old unique constraints that may not exist
+	// GORM may try to drop these constraints during AutoMigrate when detecting model changes.
+	// We proactively drop them using IF EXISTS to prevent errors.
+	oldConstraints := []struct {
+		table      string
+		constraint string
+	}{
+		{"orders", "uni_orders_order_number"},
+		{"orders", "orders_order_number_key"},
+		{"returns", "uni_returns_rma_number"},
+		{"returns", "returns_rma_number_key"},
+	}
+
+	for _, c := range oldConstraints {
+		sql := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s", c.table, c.constraint)
+		if err := db.Exec(sql).Error; err != nil {
+			log.Printf("Note: Could not drop constraint %s.%s: %v", c.table, c.constraint, err)
+		}
+	}
+
+	// Also drop old unique indexes that GORM might try to remove
+	oldIndexes := []struct {
+		table string
+		index string
+	}{
+		{"orders", "idx_orders_order_number"},
+		{"orders", "uni_orders_order_number"},
+		{"returns", "idx_returns_rma_number"},
+		{"returns", "uni_returns_rma_number"},
+	}
+
+	for _, idx := range oldIndexes {
+		sql := fmt.Sprintf("DROP INDEX IF EXISTS %s", idx.index)
+		if err := db.Exec(sql).Error; err != nil {
+			log.Printf("Note: Could not drop index %s: %v", idx.index, err)
+		}
+	}
+
+	// Run GORM AutoMigrate with error handling for constraint issues
+	err := db.AutoMigrate(schemaModels()...)
+
+	// If migration fails due to constraint issues, try again after dropping any remaining constraints
+	if err != nil && strings.Contains(err.Error(), "does not exist") {
+		log.Printf("Migration encountered constraint issue, retrying: %v", err)
+		// Try the migration again - the constraint should already be gone now
+		return db.AutoMigrate(schemaModels()...)
+	}
+
+	return err
+}
@@ -0,0 +1,34 @@
+package eventconsumer
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	messagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "nats_consumer_messages_total",
+		Help: "Messages handled by this replica, by outcome (acked, retried, dead_lettered)",
+	}, []string{"consumer", "result"})
+
+	handlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "nats_consumer_handler_duration_seconds",
+		Help:    "Time spent handling a message",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"consumer"})
+
+	pendingMessages = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nats_consumer_pending_messages",
+		Help: "Messages in the stream not yet delivered to the consumer (consumer lag)",
+	}, []string{"consumer"})
+
+	ackPendingMessages = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nats_consumer_ack_pending_messages",
+		Help: "Messages delivered to the consumer and not yet acknowledged",
+	}, []string{"consumer"})
+
+	redeliveredMessages = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nats_consumer_redelivered_messages",
+		Help: "Messages currently being redelivered to the consumer",
+	}, []string{"consumer"})
+)
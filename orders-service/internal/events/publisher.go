@@ -101,6 +101,30 @@ func (p *Publisher) PublishOrderDelivered(ctx context.Context, order *models.Ord
 	return p.publish(ctx, event)
 }
 
+// PublishOrderReadyForPickup publishes an order.ready_for_pickup event
+func (p *Publisher) PublishOrderReadyForPickup(ctx context.Context, order *models.Order, tenantID string) error {
+	event := p.buildOrderEvent("order.ready_for_pickup", order, tenantID)
+	if order.Pickup != nil {
+		event.Metadata = map[string]interface{}{
+			"pickupLocationId":   order.Pickup.LocationID.String(),
+			"pickupLocationName": order.Pickup.LocationName,
+		}
+	}
+	return p.publish(ctx, event)
+}
+
+// PublishOrderPickedUp publishes an order.picked_up event
+func (p *Publisher) PublishOrderPickedUp(ctx context.Context, order *models.Order, tenantID string) error {
+	event := p.buildOrderEvent("order.picked_up", order, tenantID)
+	if order.Pickup != nil {
+		event.Metadata = map[string]interface{}{
+			"pickupLocationId":   order.Pickup.LocationID.String(),
+			"pickupLocationName": order.Pickup.LocationName,
+		}
+	}
+	return p.publish(ctx, event)
+}
+
 // PublishOrderCancelled publishes an order.cancelled event
 func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *models.Order, reason string, tenantID string) error {
 	event := p.buildOrderEvent(events.OrderCancelled, order, tenantID)
@@ -117,6 +141,30 @@ func (p *Publisher) PublishOrderRefunded(ctx context.Context, order *models.Orde
 	return p.publish(ctx, event)
 }
 
+// PublishOrderEdited publishes an order.edited event after staff change a placed order's items
+func (p *Publisher) PublishOrderEdited(ctx context.Context, order *models.Order, revision *models.OrderRevision, tenantID string) error {
+	event := p.buildOrderEvent("order.edited", order, tenantID)
+	event.Metadata = map[string]interface{}{
+		"revision":      revision.Revision,
+		"previousTotal": revision.PreviousTotal,
+		"paymentDelta":  revision.PaymentDelta,
+		"paymentAction": string(revision.PaymentAction),
+	}
+	return p.publish(ctx, event)
+}
+
+// PublishOrderStuck publishes an order.stuck alert when the watchdog finds an order stuck past its threshold
+func (p *Publisher) PublishOrderStuck(ctx context.Context, order *models.Order, reason string, stuckSince time.Time, threshold time.Duration, tenantID string) error {
+	event := p.buildOrderEvent("order.stuck", order, tenantID)
+	if event.Metadata == nil {
+		event.Metadata = map[string]interface{}{}
+	}
+	event.Metadata["stuckReason"] = reason
+	event.Metadata["stuckSince"] = stuckSince.UTC().Format(time.RFC3339)
+	event.Metadata["thresholdHours"] = threshold.Hours()
+	return p.publish(ctx, event)
+}
+
 // PublishPaymentReceived publishes a payment.captured event
 func (p *Publisher) PublishPaymentReceived(ctx context.Context, order *models.Order, transactionID string, tenantID string) error {
 	event := events.NewPaymentEvent(events.PaymentCaptured, tenantID)
@@ -204,6 +252,11 @@ func (p *Publisher) buildOrderEvent(eventType string, order *models.Order, tenan
 		event.PaymentMethod = order.Payment.Method
 	}
 
+	// Marketing attribution, for campaign revenue reporting in marketing-service
+	if attribution := order.AttributionMetadata(); attribution != nil {
+		event.Metadata = map[string]interface{}{"attribution": attribution}
+	}
+
 	return event
 }
 
@@ -324,3 +377,22 @@ func (p *Publisher) publishPaymentConfig(ctx context.Context, event *events.Paym
 
 	return nil
 }
+
+// ===== Account Deletion Events =====
+
+// CustomerAnonymizedEvent confirms to customers-service that a service anonymized a deleted customer
+const CustomerAnonymizedEvent = "customer.anonymized"
+
+// PublishCustomerAnonymized confirms that orders-service anonymized a customer for an account
+// deletion. It publishes synchronously so a failed confirmation can be retried by redelivery.
+func (p *Publisher) PublishCustomerAnonymized(ctx context.Context, tenantID, customerID, anonymizedEmail, requestID string) error {
+	event := events.NewCustomerEvent(CustomerAnonymizedEvent, tenantID)
+	event.SourceID = uuid.New().String()
+	event.CustomerID = customerID
+	event.CustomerEmail = anonymizedEmail
+	event.Metadata = map[string]interface{}{
+		"service":   "orders-service",
+		"requestId": requestID,
+	}
+	return p.publisher.PublishCustomer(ctx, event)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"orders-service/internal/models"
+	"orders-service/internal/services"
+)
+
+// CheckoutHandler runs storefront checkouts through the checkout saga
+type CheckoutHandler struct {
+	service *services.CheckoutService
+}
+
+// NewCheckoutHandler creates a new checkout handler
+func NewCheckoutHandler(service *services.CheckoutService) *CheckoutHandler {
+	return &CheckoutHandler{service: service}
+}
+
+// Checkout places an order, holds its stock and creates its payment intent
+// @Summary Check out an order
+// @Description Returns 201 once the payment intent is ready, 202 while a failed step is being retried and 409 when the checkout was undone
+// @Tags storefront
+// @Accept json
+// @Produce json
+// @Param request body services.CheckoutRequest true "Order and payment"
+// @Success 201 {object} services.CheckoutResult
+// @Success 202 {object} services.CheckoutResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} services.CheckoutResult
+// @Router /storefront/checkout [post]
+func (h *CheckoutHandler) Checkout(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	var req services.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := req.Order.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	applyOrderRequestHeaders(c, &req.Order)
+
+	result, err := h.service.Checkout(c.Request.Context(), &req, tenantID)
+	if err != nil {
+		if errors.Is(err, services.ErrOrderNotAwaitingPayment) {
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "Order not awaiting payment",
+				Message: err.Error(),
+			})
+			return
+		}
+		respondCreateOrderError(c, err)
+		return
+	}
+
+	switch result.Checkout.Status {
+	case models.CheckoutSagaAwaitingPayment, models.CheckoutSagaCompleted:
+		c.JSON(http.StatusCreated, result)
+	case models.CheckoutSagaCompensating, models.CheckoutSagaCompensated, models.CheckoutSagaFailed:
+		c.JSON(http.StatusConflict, result)
+	default:
+		c.JSON(http.StatusAccepted, result)
+	}
+}
+
+// GetCheckout returns where an order's checkout saga got to, for storefronts polling a 202
+// @Summary Get an order's checkout
+// @Tags storefront
+// @Produce json
+// @Param orderId path string true "Order ID"
+// @Success 200 {object} models.CheckoutSaga
+// @Failure 404 {object} ErrorResponse
+// @Router /storefront/checkout/{orderId} [get]
+func (h *CheckoutHandler) GetCheckout(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+	orderID, err := uuid.Parse(c.Param("orderId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid order ID",
+			Message: "Order ID must be a valid UUID",
+		})
+		return
+	}
+
+	saga, err := h.service.GetCheckout(c.Request.Context(), tenantID, orderID)
+	if err != nil {
+		if errors.Is(err, services.ErrCheckoutNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to get checkout",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, saga)
+}
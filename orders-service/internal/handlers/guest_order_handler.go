@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"crypto/subtle"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -67,6 +68,7 @@ func (h *GuestOrderHandler) LookupOrder(c *gin.Context) {
 		return
 	}
 
+	h.orderService.ApplySelfCancelStatus(tenantID, order)
 	c.JSON(http.StatusOK, services.MaskOrderForPublic(order))
 }
 
@@ -119,7 +121,11 @@ func (h *GuestOrderHandler) CancelOrder(c *gin.Context) {
 	if reason == "" {
 		reason = "Cancelled by customer"
 	}
-	cancelledOrder, err := h.orderService.CancelOrder(order.ID, reason, tenantID)
+	cancelledOrder, err := h.orderService.CustomerCancelOrder(order.ID, reason, tenantID)
+	if errors.Is(err, services.ErrSelfCancelNotAllowed) {
+		c.JSON(http.StatusBadRequest, GuestErrorResponse{Error: "CANCEL_NOT_ALLOWED", Message: "This order can no longer be cancelled"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, GuestErrorResponse{Error: "CANCEL_FAILED", Message: "Unable to cancel this order"})
 		return
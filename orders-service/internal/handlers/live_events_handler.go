@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/Tesseract-Nexus/go-shared/rbac"
+	"github.com/gin-gonic/gin"
+	"orders-service/internal/models"
+	"orders-service/internal/services"
+)
+
+const (
+	liveEventHeartbeatInterval = 20 * time.Second
+	liveEventRetryMillis       = 5000
+)
+
+// liveEventPermissions maps each streamed event type to the permission required to receive it
+var liveEventPermissions = map[string]string{
+	gosharedevents.OrderCreated:     rbac.PermissionOrdersRead,
+	gosharedevents.PaymentCaptured:  rbac.PermissionPaymentsRead,
+	gosharedevents.PaymentSucceeded: rbac.PermissionPaymentsRead,
+	gosharedevents.TicketCreated:    rbac.PermissionTicketsRead,
+}
+
+// LiveEventsHandler streams platform events to the admin dashboard over server-sent events
+type LiveEventsHandler struct {
+	hub    *services.LiveEventHub
+	rbacMw *rbac.Middleware
+}
+
+// NewLiveEventsHandler creates a new live events handler
+func NewLiveEventsHandler(hub *services.LiveEventHub, rbacMw *rbac.Middleware) *LiveEventsHandler {
+	return &LiveEventsHandler{
+		hub:    hub,
+		rbacMw: rbacMw,
+	}
+}
+
+// StreamEvents streams the tenant's live events
+// @Summary Stream live admin events
+// @Description Server-sent events for order.created, payment.captured/payment.succeeded and ticket.created. Each event type is only sent to users holding its read permission. Reconnects resume from the Last-Event-ID header; a "resync" event means events were missed and the dashboard should reload.
+// @Tags events
+// @Produce text/event-stream
+// @Param types query string false "Comma-separated event types to receive (defaults to all permitted)"
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Success 200 {string} string "text/event-stream"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /events/stream [get]
+func (h *LiveEventsHandler) StreamEvents(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	if gosharedmw.GetVendorScopeFilter(c) != "" {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Access denied",
+			Message: "The live event stream is not available to vendor users",
+		})
+		return
+	}
+
+	types, err := h.allowedTypes(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid event types",
+			Message: err.Error(),
+		})
+		return
+	}
+	if len(types) == 0 {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Access denied",
+			Message: "You do not have permission to receive any of the requested event types",
+		})
+		return
+	}
+
+	// EventSource sends Last-Event-ID on reconnect; the query param lets clients resume on a fresh connection
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+
+	client, replay, resync := h.hub.Subscribe(tenantID, lastEventID)
+	defer h.hub.Unsubscribe(client)
+
+	w := c.Writer
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", liveEventRetryMillis)
+	if resync {
+		fmt.Fprintf(w, "event: resync\ndata: {}\n\n")
+	}
+	for _, event := range replay {
+		if types[event.Type] {
+			if err := writeLiveEvent(w, event); err != nil {
+				return
+			}
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(liveEventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-client.Events:
+			if !ok {
+				// Dropped for falling behind or shutting down; the client reconnects and replays
+				return
+			}
+			if !types[event.Type] {
+				continue
+			}
+			if err := writeLiveEvent(w, event); err != nil {
+				return
+			}
+			w.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprintf(w, ": heartbeat %d\n\n", time.Now().Unix()); err != nil {
+				return
+			}
+			w.Flush()
+		}
+	}
+}
+
+// allowedTypes resolves the requested event types, keeping only those the user may see
+func (h *LiveEventsHandler) allowedTypes(c *gin.Context) (map[string]bool, error) {
+	requested := make([]string, 0, len(liveEventPermissions))
+	if raw := c.Query("types"); raw != "" {
+		for _, eventType := range strings.Split(raw, ",") {
+			eventType = strings.TrimSpace(eventType)
+			if _, ok := liveEventPermissions[eventType]; !ok {
+				return nil, fmt.Errorf("unsupported event type %q", eventType)
+			}
+			requested = append(requested, eventType)
+		}
+	} else {
+		for eventType := range liveEventPermissions {
+			requested = append(requested, eventType)
+		}
+	}
+
+	// Check each permission once; both payment subjects share one
+	checked := make(map[string]bool)
+	types := make(map[string]bool)
+	for _, eventType := range requested {
+		permission := liveEventPermissions[eventType]
+		allowed, ok := checked[permission]
+		if !ok {
+			allowed = h.rbacMw.HasPermission(c, permission)
+			checked[permission] = allowed
+		}
+		if allowed {
+			types[eventType] = true
+		}
+	}
+	return types, nil
+}
+
+func writeLiveEvent(w gin.ResponseWriter, event models.LiveEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil // Skip events that can't be encoded rather than dropping the stream
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"orders-service/internal/models"
+	"orders-service/internal/services"
+)
+
+// NotificationRoutingHandler handles HTTP requests for tenants' customer notification routing
+// and the per-order send log
+type NotificationRoutingHandler struct {
+	notifier *services.OrderNotifier
+}
+
+// NewNotificationRoutingHandler creates a new notification routing handler
+func NewNotificationRoutingHandler(notifier *services.OrderNotifier) *NotificationRoutingHandler {
+	return &NotificationRoutingHandler{notifier: notifier}
+}
+
+// GetSettings returns the tenant's notification routing and the channels each event goes to
+// GET /api/v1/settings/notifications
+// RBAC: settings:store:view
+func (h *NotificationRoutingHandler) GetSettings(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "MISSING_TENANT_ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	resp, err := h.notifier.GetRouting(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "FETCH_FAILED",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateSettings replaces the tenant's notification routing
+// PUT /api/v1/settings/notifications
+// RBAC: settings:store:edit
+func (h *NotificationRoutingHandler) UpdateSettings(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "MISSING_TENANT_ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	var req models.UpdateNotificationRoutingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.notifier.UpdateRouting(c.Request.Context(), tenantID, &req, getUserID(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidNotificationRouting) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "INVALID_ROUTING",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "UPDATE_FAILED",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListOrderNotifications returns every customer notification sent, queued or skipped for an order
+// GET /api/v1/orders/:id/notifications
+// RBAC: orders:read
+func (h *NotificationRoutingHandler) ListOrderNotifications(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "MISSING_TENANT_ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_ID",
+			Message: "Order ID must be a valid UUID",
+		})
+		return
+	}
+
+	entries, err := h.notifier.ListOrderNotifications(c.Request.Context(), tenantID, orderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "FETCH_FAILED",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": entries})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"orders-service/internal/models"
+	"orders-service/internal/services"
+)
+
+// OrderDocumentHandler handles HTTP requests for order document packs
+type OrderDocumentHandler struct {
+	documentService services.OrderDocumentService
+	orderService    services.OrderService
+}
+
+// NewOrderDocumentHandler creates a new order document handler
+func NewOrderDocumentHandler(documentService services.OrderDocumentService, orderService services.OrderService) *OrderDocumentHandler {
+	return &OrderDocumentHandler{
+		documentService: documentService,
+		orderService:    orderService,
+	}
+}
+
+// GetDocumentPack returns an order's packing slip, invoice and shipping labels in one download
+// GET /api/v1/orders/:id/documents?types=packing_slip,invoice,label&format=pdf|zip&delivery=url
+// RBAC: orders:view
+func (h *OrderDocumentHandler) GetDocumentPack(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "MISSING_TENANT_ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_ORDER_ID",
+			Message: "Order ID must be a valid UUID",
+		})
+		return
+	}
+
+	types, err := services.ParseOrderDocumentTypes(c.Query("types"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_DOCUMENT_TYPE",
+			Message: fmt.Sprintf("%v (supported: packing_slip, invoice, label)", err),
+		})
+		return
+	}
+
+	format := models.OrderDocumentPackFormat(c.DefaultQuery("format", string(models.OrderDocumentPackPDF)))
+	if format != models.OrderDocumentPackPDF && format != models.OrderDocumentPackZIP {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_FORMAT",
+			Message: "format must be pdf or zip",
+		})
+		return
+	}
+
+	order, err := h.orderService.GetOrder(orderID, tenantID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "ORDER_NOT_FOUND",
+			Message: "Order not found",
+		})
+		return
+	}
+
+	pack, err := h.documentService.BuildDocumentPack(c.Request.Context(), order, tenantID, &models.OrderDocumentPackRequest{
+		Types:  types,
+		Format: format,
+		Locale: c.Query("locale"),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNoShipmentForLabel):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "NO_SHIPMENT",
+				Message: "Order has no shipment yet. Create a shipment or leave out the label type",
+			})
+		case errors.Is(err, services.ErrLabelUnavailable):
+			c.JSON(http.StatusBadGateway, ErrorResponse{
+				Error:   "LABEL_UNAVAILABLE",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "GENERATION_FAILED",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	if c.Query("delivery") == "url" {
+		resp, err := h.documentService.StoreDocumentPack(c.Request.Context(), order, tenantID, pack)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, ErrorResponse{
+				Error:   "STORAGE_FAILED",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", pack.Filename))
+	c.Header("X-Document-Count", fmt.Sprintf("%d", len(pack.Documents)))
+	c.Data(http.StatusOK, pack.ContentType, pack.Data)
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -10,7 +12,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"orders-service/internal/clients"
+	"orders-service/internal/fieldset"
 	"orders-service/internal/models"
+	"orders-service/internal/repository"
 	"orders-service/internal/services"
 )
 
@@ -79,23 +84,19 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		})
 		return
 	}
-
-	// Capture storefront host for building correct email URLs (supports custom domains)
-	if storefrontHost := c.GetHeader("X-Storefront-Host"); storefrontHost != "" {
-		req.StorefrontHost = storefrontHost
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
 	}
 
-	// Capture idempotency key for duplicate order prevention
-	if idempotencyKey := c.GetHeader("X-Idempotency-Key"); idempotencyKey != "" {
-		req.IdempotencyKey = idempotencyKey
-	}
+	applyOrderRequestHeaders(c, &req)
 
 	order, err := h.orderService.CreateOrder(req, tenantID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Failed to create order",
-			Message: err.Error(),
-		})
+		respondCreateOrderError(c, err)
 		return
 	}
 
@@ -111,12 +112,70 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	c.JSON(http.StatusCreated, order)
 }
 
+// applyOrderRequestHeaders copies the storefront's request headers onto an order request
+func applyOrderRequestHeaders(c *gin.Context, req *services.CreateOrderRequest) {
+	// Capture storefront host for building correct email URLs (supports custom domains)
+	if storefrontHost := c.GetHeader("X-Storefront-Host"); storefrontHost != "" {
+		req.StorefrontHost = storefrontHost
+	}
+
+	// Storefront ID selects a per-storefront order number prefix
+	req.StorefrontID = c.GetHeader("X-Storefront-ID")
+
+	// Capture idempotency key for duplicate order prevention
+	if idempotencyKey := c.GetHeader("X-Idempotency-Key"); idempotencyKey != "" {
+		req.IdempotencyKey = idempotencyKey
+	} else if idempotencyKey := c.GetHeader("Idempotency-Key"); idempotencyKey != "" {
+		req.IdempotencyKey = idempotencyKey
+	}
+}
+
+// respondCreateOrderError maps order creation errors to HTTP responses
+func respondCreateOrderError(c *gin.Context, err error) {
+	var requoteErr *services.RequoteRequiredError
+	if errors.As(err, &requoteErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "REQUOTE_REQUIRED",
+			"reason":  requoteErr.Reason,
+			"message": requoteErr.Message,
+		})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidPriceLock) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid price lock",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, clients.ErrPickupLocationUnavailable) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Pickup location unavailable",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, clients.ErrInsufficientPickupStock) {
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Insufficient stock at pickup location",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "Failed to create order",
+		Message: err.Error(),
+	})
+}
+
 // GetOrder retrieves an order by ID
 // @Summary Get order by ID
 // @Description Get a specific order by its ID
 // @Tags orders
 // @Produce json
 // @Param id path string true "Order ID"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,status,items.sku"
+// @Param include query string false "Comma-separated relations to return"
 // @Success 200 {object} models.Order
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
@@ -141,6 +200,11 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		return
 	}
 
+	selection, ok := parseOrderFieldset(c)
+	if !ok {
+		return
+	}
+
 	order, err := h.orderService.GetOrder(id, tenantID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{
@@ -150,7 +214,17 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, order)
+	body, err := selection.Filter(order)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to get order",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("ETag", versionETag(order.Version))
+	c.JSON(http.StatusOK, body)
 }
 
 // BatchGetOrders retrieves multiple orders by IDs in a single request
@@ -306,6 +380,9 @@ func (h *OrderHandler) GetOrderByNumber(c *gin.Context) {
 // @Param dateTo query string false "Date to filter (RFC3339 format)"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
+// @Param includeVendorOrders query bool false "Also list the per-vendor orders of split marketplace checkouts"
+// @Param fields query string false "Comma-separated fields to return for each order, e.g. id,orderNumber,total,customer.email"
+// @Param include query string false "Comma-separated relations to load; without fields or include every relation is loaded"
 // @Success 200 {object} services.OrderListResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -340,6 +417,13 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 		Limit:    20,
 	}
 
+	// Tenant-level users see a marketplace checkout once, as the parent order; its vendor
+	// orders are listed under /orders/:id/children unless includeVendorOrders=true.
+	// Vendor-scoped users only ever match their own vendor orders.
+	if vendorScopeFilter == "" && c.Query("includeVendorOrders") != "true" {
+		filters.ExcludeVendorSplits = true
+	}
+
 	// Parse query parameters
 	if pageStr := c.Query("page"); pageStr != "" {
 		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
@@ -380,6 +464,14 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 		}
 	}
 
+	selection, ok := parseOrderFieldset(c)
+	if !ok {
+		return
+	}
+	if selection.Active() {
+		filters.Include = selection.Relations()
+	}
+
 	response, err := h.orderService.ListOrders(filters, tenantID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -389,7 +481,30 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	body, err := selection.FilterIn(response, "orders")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to list orders",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, body)
+}
+
+// parseOrderFieldset reads ?fields= and ?include= for an order response, answering 400
+// when they are invalid
+func parseOrderFieldset(c *gin.Context) (*fieldset.Selection, bool) {
+	selection, err := fieldset.Parse(c, repository.OrderRelations...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid fields",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	return selection, true
 }
 
 // UpdateOrder updates an existing order
@@ -434,8 +549,29 @@ func (h *OrderHandler) UpdateOrder(c *gin.Context) {
 		return
 	}
 
+	// If-Match wins over expectedVersion in the body
+	if version, present, err := parseIfMatch(c); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid If-Match header",
+			Message: err.Error(),
+		})
+		return
+	} else if present {
+		req.ExpectedVersion = version
+	}
+
 	order, err := h.orderService.UpdateOrder(id, req, tenantID)
 	if err != nil {
+		var conflict *models.VersionConflictError
+		if errors.As(err, &conflict) {
+			c.Header("ETag", versionETag(conflict.CurrentVersion))
+			c.JSON(http.StatusConflict, gin.H{
+				"error":          "Version conflict",
+				"message":        "Order was modified by another request; reload and retry",
+				"currentVersion": conflict.CurrentVersion,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Failed to update order",
 			Message: err.Error(),
@@ -443,9 +579,28 @@ func (h *OrderHandler) UpdateOrder(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", versionETag(order.Version))
 	c.JSON(http.StatusOK, order)
 }
 
+// parseIfMatch extracts an order version from If-Match ("3", W/"3" or 3).
+// A missing header or "*" leaves the update unconditional (present == false).
+func parseIfMatch(c *gin.Context) (version *int, present bool, err error) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" || raw == "*" {
+		return nil, false, nil
+	}
+	v, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(raw, "W/"), `"`))
+	if err != nil || v < 1 {
+		return nil, true, fmt.Errorf("If-Match must be an order version, got %q", raw)
+	}
+	return &v, true, nil
+}
+
+func versionETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
 // UpdateOrderStatus updates the status of an order
 // @Summary Update order status
 // @Description Update the status of an order
@@ -952,6 +1107,47 @@ func (h *OrderHandler) SplitOrder(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// SplitOrderByVendor splits a marketplace checkout into one order per vendor
+// @Summary Split an order by vendor
+// @Description Split a multi-vendor order into vendor orders, each with its own fulfillment, shipping and payout totals. Checkouts are split automatically; use this to retry a failed split.
+// @Tags orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} services.VendorSplitResponse
+// @Router /orders/{id}/split-by-vendor [post]
+func (h *OrderHandler) SplitOrderByVendor(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tenant_id is required"})
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID format"})
+		return
+	}
+
+	// Get user ID from context if available
+	var userID *uuid.UUID
+	if userIDStr := c.GetString("user_id"); userIDStr != "" {
+		if parsedID, err := uuid.Parse(userIDStr); err == nil {
+			userID = &parsedID
+		}
+	}
+
+	response, err := h.orderService.SplitOrderByVendor(id, userID, tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // GetChildOrders gets all child orders for a parent order
 // @Summary Get child orders
 // @Description Get all child orders for a split parent order
@@ -985,6 +1181,335 @@ func (h *OrderHandler) GetChildOrders(c *gin.Context) {
 	c.JSON(http.StatusOK, orders)
 }
 
+// EditOrder changes the items of a placed order before fulfillment
+// @Summary Edit order items
+// @Description Add, remove or change the quantity of items on an order that hasn't started fulfillment. Paid orders are charged or refunded the difference in total.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param If-Match header string false "Expected order version"
+// @Param request body services.EditOrderRequest true "Item changes"
+// @Success 200 {object} services.EditOrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/edit [post]
+func (h *OrderHandler) EditOrder(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid order ID",
+			Message: "Order ID must be a valid UUID",
+		})
+		return
+	}
+
+	var req services.EditOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if version, present, err := parseIfMatch(c); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid If-Match header",
+			Message: err.Error(),
+		})
+		return
+	} else if present {
+		req.ExpectedVersion = version
+	}
+
+	var editedBy *uuid.UUID
+	if userIDStr := c.GetString("user_id"); userIDStr != "" {
+		if parsedID, err := uuid.Parse(userIDStr); err == nil {
+			editedBy = &parsedID
+		}
+	}
+
+	response, err := h.orderService.EditOrder(id, req, editedBy, tenantID)
+	if err != nil {
+		var conflict *models.VersionConflictError
+		switch {
+		case errors.As(err, &conflict):
+			c.Header("ETag", versionETag(conflict.CurrentVersion))
+			c.JSON(http.StatusConflict, gin.H{
+				"error":          "Version conflict",
+				"message":        "Order was modified by another request; reload and retry",
+				"currentVersion": conflict.CurrentVersion,
+			})
+		case errors.Is(err, services.ErrOrderNotEditable):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "Order not editable",
+				Message: "Orders can only be edited before fulfillment starts",
+			})
+		case errors.Is(err, services.ErrInvalidOrderEdit):
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid order edit",
+				Message: err.Error(),
+			})
+		case errors.Is(err, clients.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "Insufficient stock",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Failed to edit order",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Header("ETag", versionETag(response.Order.Version))
+	c.JSON(http.StatusOK, response)
+}
+
+// ListOrderRevisions lists the edits made to an order
+// @Summary List order revisions
+// @Description Get an order's edit history with the payment outcome of each edit, oldest first
+// @Tags orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {array} models.OrderRevision
+// @Failure 404 {object} ErrorResponse
+// @Router /orders/{id}/revisions [get]
+func (h *OrderHandler) ListOrderRevisions(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid order ID",
+			Message: "Order ID must be a valid UUID",
+		})
+		return
+	}
+
+	revisions, err := h.orderService.ListOrderRevisions(id, tenantID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Order not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// CreateFulfillment ships some of an order's items in their own parcel
+// @Summary Create a fulfillment
+// @Description Put some of an order's remaining items in a parcel with its own tracking. Without a tracking number the parcel is booked with shipping-service unless createShipment is false.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body services.CreateFulfillmentRequest true "Items and quantities in the parcel"
+// @Success 201 {object} models.OrderFulfillment
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /orders/{id}/fulfillments [post]
+func (h *OrderHandler) CreateFulfillment(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid order ID",
+			Message: "Order ID must be a valid UUID",
+		})
+		return
+	}
+
+	var req services.CreateFulfillmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var createdBy *uuid.UUID
+	if userIDStr := c.GetString("user_id"); userIDStr != "" {
+		if parsedID, err := uuid.Parse(userIDStr); err == nil {
+			createdBy = &parsedID
+		}
+	}
+
+	fulfillment, err := h.orderService.CreateFulfillment(id, req, createdBy, tenantID)
+	if err != nil {
+		h.fulfillmentError(c, err, "Failed to create fulfillment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, fulfillment)
+}
+
+// ListFulfillments lists the parcels an order has been shipped in
+// @Summary List fulfillments
+// @Description Get an order's fulfillments with their items and tracking, oldest first
+// @Tags orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {array} models.OrderFulfillment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /orders/{id}/fulfillments [get]
+func (h *OrderHandler) ListFulfillments(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid order ID",
+			Message: "Order ID must be a valid UUID",
+		})
+		return
+	}
+
+	fulfillments, err := h.orderService.ListFulfillments(id, tenantID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Order not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, fulfillments)
+}
+
+// UpdateFulfillment moves one of an order's parcels to a new fulfillment status
+// @Summary Update a fulfillment's status
+// @Description Move a parcel along (e.g. DISPATCHED, IN_TRANSIT, DELIVERED), optionally adding its tracking. The order's fulfillment status is rolled up from all of its parcels.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param fulfillmentId path string true "Fulfillment ID"
+// @Param request body services.UpdateOrderFulfillmentRequest true "New status and tracking"
+// @Success 200 {object} models.OrderFulfillment
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /orders/{id}/fulfillments/{fulfillmentId}/status [patch]
+func (h *OrderHandler) UpdateFulfillment(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid order ID",
+			Message: "Order ID must be a valid UUID",
+		})
+		return
+	}
+	fulfillmentID, err := uuid.Parse(c.Param("fulfillmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid fulfillment ID",
+			Message: "Fulfillment ID must be a valid UUID",
+		})
+		return
+	}
+
+	var req services.UpdateOrderFulfillmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fulfillment, err := h.orderService.UpdateFulfillment(id, fulfillmentID, req, tenantID)
+	if err != nil {
+		h.fulfillmentError(c, err, "Failed to update fulfillment")
+		return
+	}
+
+	c.JSON(http.StatusOK, fulfillment)
+}
+
+// fulfillmentError writes the response for a failed fulfillment request
+func (h *OrderHandler) fulfillmentError(c *gin.Context, err error, fallback string) {
+	var conflict *models.VersionConflictError
+	switch {
+	case errors.As(err, &conflict):
+		c.Header("ETag", versionETag(conflict.CurrentVersion))
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          "Version conflict",
+			"message":        "Order was modified by another request; reload and retry",
+			"currentVersion": conflict.CurrentVersion,
+		})
+	case errors.Is(err, services.ErrOrderNotFulfillable):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Order not fulfillable",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidFulfillment):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid fulfillment",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrShipmentBookingFailed):
+		c.JSON(http.StatusBadGateway, ErrorResponse{
+			Error:   "Shipment booking failed",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   fallback,
+			Message: err.Error(),
+		})
+	}
+}
+
 // @Description Check if the service is healthy
 // @Tags health
 // @Produce json
@@ -1078,10 +1603,12 @@ func (h *OrderHandler) ListCustomerOrders(c *gin.Context) {
 		return
 	}
 
+	// Customers see the order they placed, not the per-vendor orders it was split into
 	filters := services.OrderListFilters{
-		CustomerEmail: &customerEmail,
-		Page:          1,
-		Limit:         20,
+		CustomerEmail:       &customerEmail,
+		ExcludeVendorSplits: true,
+		Page:                1,
+		Limit:               20,
 	}
 
 	// Parse query parameters
@@ -1111,6 +1638,12 @@ func (h *OrderHandler) ListCustomerOrders(c *gin.Context) {
 		return
 	}
 
+	orders := make([]*models.Order, len(response.Orders))
+	for i := range response.Orders {
+		orders[i] = &response.Orders[i]
+	}
+	h.orderService.ApplySelfCancelStatus(tenantID, orders...)
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -1173,6 +1706,7 @@ func (h *OrderHandler) GetCustomerOrder(c *gin.Context) {
 		return
 	}
 
+	h.orderService.ApplySelfCancelStatus(tenantID, order)
 	c.JSON(http.StatusOK, order)
 }
 
@@ -1282,21 +1816,20 @@ func (h *OrderHandler) StorefrontCancelOrder(c *gin.Context) {
 		return
 	}
 
-	// Only allow cancelling orders in PLACED or CONFIRMED status
-	if order.Status != models.OrderStatusPlaced && order.Status != models.OrderStatusConfirmed {
+	reason := req.Reason
+	if reason == "" {
+		reason = "Cancelled by customer"
+	}
+
+	// Customers can only cancel before fulfillment starts and within the store's self-cancel window
+	cancelledOrder, err := h.orderService.CustomerCancelOrder(order.ID, reason, tenantID)
+	if errors.Is(err, services.ErrSelfCancelNotAllowed) {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "Cannot cancel",
 			Message: "This order can no longer be cancelled",
 		})
 		return
 	}
-
-	reason := req.Reason
-	if reason == "" {
-		reason = "Cancelled by customer"
-	}
-
-	cancelledOrder, err := h.orderService.CancelOrder(order.ID, reason, tenantID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error:   "Cancel failed",
@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"orders-service/internal/models"
+	"orders-service/internal/services"
+)
+
+// OrderImportHandler handles imports of historical and offline orders
+type OrderImportHandler struct {
+	service *services.OrderImportService
+}
+
+// NewOrderImportHandler creates a new order import handler
+func NewOrderImportHandler(service *services.OrderImportService) *OrderImportHandler {
+	return &OrderImportHandler{service: service}
+}
+
+// orderImportJobResponse adds the progress percentage to an import job
+type orderImportJobResponse struct {
+	*models.OrderImportJob
+	PercentComplete int `json:"percentComplete"`
+}
+
+func newOrderImportJobResponse(job *models.OrderImportJob) orderImportJobResponse {
+	return orderImportJobResponse{OrderImportJob: job, PercentComplete: job.PercentComplete()}
+}
+
+// GetImportTemplate returns a CSV template with every import column and an example order of
+// two line items
+// GET /api/v1/orders/import/template
+func (h *OrderImportHandler) GetImportTemplate(c *gin.Context) {
+	examples := []map[string]string{
+		{
+			"orderNumber":       "1001",
+			"orderDate":         "2024-03-18",
+			"email":             "jane.doe@example.com",
+			"firstName":         "Jane",
+			"lastName":          "Doe",
+			"phone":             "+61400000000",
+			"currency":          "AUD",
+			"status":            "COMPLETED",
+			"paymentStatus":     "PAID",
+			"fulfillmentStatus": "DELIVERED",
+			"paymentMethod":     "card",
+			"transactionId":     "ch_123456",
+			"sku":               "TSHIRT-BLK-M",
+			"productName":       "Black T-Shirt (M)",
+			"quantity":          "2",
+			"unitPrice":         "25.00",
+			"itemTax":           "5.00",
+			"taxAmount":         "7.50",
+			"shippingCost":      "10.00",
+			"discountAmount":    "0",
+			"total":             "82.50",
+			"shippingMethod":    "Standard",
+			"carrier":           "Australia Post",
+			"trackingNumber":    "AP123456789",
+			"street":            "1 Example Street",
+			"city":              "Sydney",
+			"state":             "NSW",
+			"postalCode":        "2000",
+			"country":           "Australia",
+			"countryCode":       "AU",
+		},
+		{
+			"orderNumber": "1001",
+			"sku":         "CAP-RED",
+			"productName": "Red Cap",
+			"quantity":    "1",
+			"unitPrice":   "15.00",
+			"itemTax":     "2.50",
+		},
+	}
+
+	headers := make([]string, len(models.OrderImportColumns))
+	for i, col := range models.OrderImportColumns {
+		headers[i] = col.Name
+		if col.Required {
+			headers[i] += " *"
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(headers)
+	for _, example := range examples {
+		row := make([]string, len(models.OrderImportColumns))
+		for i, col := range models.OrderImportColumns {
+			row[i] = example[col.Name]
+		}
+		w.Write(row)
+	}
+	w.Flush()
+
+	c.Header("Content-Disposition", `attachment; filename="order_import_template.csv"`)
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+// PreviewImport shows how the first rows of an uploaded file would be mapped, grouped into
+// orders and validated
+// POST /api/v1/orders/import/preview
+func (h *OrderImportHandler) PreviewImport(c *gin.Context) {
+	if _, ok := h.requireTenantStaff(c); !ok {
+		return
+	}
+
+	format, data, ok := readOrderImportFile(c)
+	if !ok {
+		return
+	}
+
+	mapping, ok := parseOrderImportMapping(c)
+	if !ok {
+		return
+	}
+
+	limit := 20
+	if rows := c.PostForm("rows"); rows != "" {
+		if n, err := strconv.Atoi(rows); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	preview, err := h.service.PreviewImport(format, data, mapping, limit)
+	if err != nil {
+		respondOrderImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"preview": preview,
+		"columns": models.OrderImportColumns,
+	})
+}
+
+// StartImport queues an uploaded file for import
+// POST /api/v1/orders/import
+func (h *OrderImportHandler) StartImport(c *gin.Context) {
+	tenantID, ok := h.requireTenantStaff(c)
+	if !ok {
+		return
+	}
+
+	format, data, ok := readOrderImportFile(c)
+	if !ok {
+		return
+	}
+
+	mapping, ok := parseOrderImportMapping(c)
+	if !ok {
+		return
+	}
+
+	storefrontID := c.PostForm("storefrontId")
+	if storefrontID == "" {
+		storefrontID = c.GetHeader("X-Storefront-ID")
+	}
+	file, _ := c.FormFile("file")
+
+	job, err := h.service.CreateImportJob(c.Request.Context(), services.CreateOrderImportRequest{
+		TenantID:        tenantID,
+		CreatedBy:       getUserID(c),
+		FileName:        file.Filename,
+		Format:          format,
+		Data:            data,
+		Mapping:         mapping,
+		Source:          c.PostForm("source"),
+		StorefrontID:    storefrontID,
+		Currency:        c.PostForm("currency"),
+		CreateCustomers: c.DefaultPostForm("createCustomers", "true") == "true",
+	})
+	if err != nil {
+		respondOrderImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newOrderImportJobResponse(job))
+}
+
+// ListImportJobs lists the tenant's order import jobs
+// GET /api/v1/orders/import/jobs?page=1&limit=20
+func (h *OrderImportHandler) ListImportJobs(c *gin.Context) {
+	tenantID, ok := h.requireTenantStaff(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	jobs, total, err := h.service.ListImportJobs(c.Request.Context(), tenantID, page, limit)
+	if err != nil {
+		respondOrderImportError(c, err)
+		return
+	}
+
+	items := make([]orderImportJobResponse, len(jobs))
+	for i := range jobs {
+		items[i] = newOrderImportJobResponse(&jobs[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":       items,
+		"total":      total,
+		"page":       page,
+		"limit":      limit,
+		"totalPages": int((total + int64(limit) - 1) / int64(limit)),
+	})
+}
+
+// GetImportJob returns an order import job and its progress
+// GET /api/v1/orders/import/jobs/:jobId
+func (h *OrderImportHandler) GetImportJob(c *gin.Context) {
+	tenantID, ok := h.requireTenantStaff(c)
+	if !ok {
+		return
+	}
+
+	jobID, ok := parseOrderImportJobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.GetImportJob(c.Request.Context(), tenantID, jobID)
+	if err != nil {
+		respondOrderImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newOrderImportJobResponse(job))
+}
+
+// GetImportErrors returns an order import job's per-row error report as JSON or CSV
+// GET /api/v1/orders/import/jobs/:jobId/errors?format=csv
+func (h *OrderImportHandler) GetImportErrors(c *gin.Context) {
+	tenantID, ok := h.requireTenantStaff(c)
+	if !ok {
+		return
+	}
+
+	jobID, ok := parseOrderImportJobID(c)
+	if !ok {
+		return
+	}
+
+	rowErrors, err := h.service.GetImportErrors(c.Request.Context(), tenantID, jobID)
+	if err != nil {
+		respondOrderImportError(c, err)
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, gin.H{
+			"errors": rowErrors,
+			"total":  len(rowErrors),
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"row", "orderNumber", "column", "code", "message"})
+	for _, e := range rowErrors {
+		w.Write([]string{strconv.Itoa(e.Row), e.OrderNumber, e.Column, e.Code, e.Message})
+	}
+	w.Flush()
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="order_import_%s_errors.csv"`, jobID))
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+// ResumeImportJob re-queues a failed order import from the row where it stopped
+// POST /api/v1/orders/import/jobs/:jobId/resume
+func (h *OrderImportHandler) ResumeImportJob(c *gin.Context) {
+	tenantID, ok := h.requireTenantStaff(c)
+	if !ok {
+		return
+	}
+
+	jobID, ok := parseOrderImportJobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.ResumeImportJob(c.Request.Context(), tenantID, jobID)
+	if err != nil {
+		respondOrderImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newOrderImportJobResponse(job))
+}
+
+// requireTenantStaff returns the tenant ID, rejecting vendor-scoped users since imported orders
+// belong to the store rather than a vendor
+func (h *OrderImportHandler) requireTenantStaff(c *gin.Context) (string, bool) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "MISSING_TENANT_ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return "", false
+	}
+	if gosharedmw.GetVendorScopeFilter(c) != "" {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "ACCESS_DENIED",
+			Message: "Order imports are only available to store staff",
+		})
+		return "", false
+	}
+	return tenantID, true
+}
+
+func parseOrderImportJobID(c *gin.Context) (uuid.UUID, bool) {
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_ID",
+			Message: "Invalid import job ID",
+		})
+		return uuid.Nil, false
+	}
+	return jobID, true
+}
+
+// readOrderImportFile reads the uploaded "file" form field and detects its format. It writes
+// the error response and returns false when the upload is missing, too large or unsupported.
+func readOrderImportFile(c *gin.Context) (models.OrderImportFormat, []byte, bool) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "MISSING_FILE",
+			Message: "file is required",
+		})
+		return "", nil, false
+	}
+	if file.Size > models.MaxOrderImportFileBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+			Error:   "FILE_TOO_LARGE",
+			Message: fmt.Sprintf("file exceeds the %d MB import limit", models.MaxOrderImportFileBytes/(1024*1024)),
+		})
+		return "", nil, false
+	}
+
+	format, ok := services.DetectImportFormat(file.Filename)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "UNSUPPORTED_FILE_TYPE",
+			Message: "Upload a .csv or .xlsx file",
+		})
+		return "", nil, false
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_FILE",
+			Message: "Failed to read uploaded file",
+		})
+		return "", nil, false
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_FILE",
+			Message: "Failed to read uploaded file",
+		})
+		return "", nil, false
+	}
+	return format, data, true
+}
+
+// parseOrderImportMapping reads the optional "mapping" form field, a JSON object of source
+// header to import column
+func parseOrderImportMapping(c *gin.Context) (map[string]string, bool) {
+	raw := c.PostForm("mapping")
+	if raw == "" {
+		return nil, true
+	}
+
+	var mapping map[string]string
+	if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_MAPPING",
+			Message: "mapping must be a JSON object of source header to column",
+		})
+		return nil, false
+	}
+
+	normalized, err := services.NormalizeColumnMapping(mapping)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_MAPPING",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	return normalized, true
+}
+
+func respondOrderImportError(c *gin.Context, err error) {
+	var validationErr *services.ImportValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: validationErr.Code, Message: validationErr.Message})
+	case errors.Is(err, services.ErrImportJobNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "NOT_FOUND", Message: err.Error()})
+	case errors.Is(err, services.ErrImportJobNotResumable):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "NOT_RESUMABLE", Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "INTERNAL_ERROR", Message: "An internal error occurred"})
+	}
+}
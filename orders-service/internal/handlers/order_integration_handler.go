@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"orders-service/internal/models"
+	"orders-service/internal/services"
+)
+
+// OrderIntegrationHandler manages outbound order integrations to external systems such as ERPs
+type OrderIntegrationHandler struct {
+	service *services.OrderIntegrationService
+}
+
+// NewOrderIntegrationHandler creates a new order integration handler
+func NewOrderIntegrationHandler(service *services.OrderIntegrationService) *OrderIntegrationHandler {
+	return &OrderIntegrationHandler{service: service}
+}
+
+// ListIntegrations returns the tenant's order integrations
+// @Summary List order integrations
+// @Tags integrations
+// @Produce json
+// @Success 200 {array} models.OrderIntegration
+// @Router /order-integrations [get]
+func (h *OrderIntegrationHandler) ListIntegrations(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	integrations, err := h.service.List(c.Request.Context(), tenantID)
+	if err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": integrations})
+}
+
+// GetIntegration returns one order integration
+// @Summary Get an order integration
+// @Tags integrations
+// @Produce json
+// @Param id path string true "Integration ID"
+// @Success 200 {object} models.OrderIntegration
+// @Failure 404 {object} ErrorResponse
+// @Router /order-integrations/{id} [get]
+func (h *OrderIntegrationHandler) GetIntegration(c *gin.Context) {
+	tenantID, id, ok := h.integrationParams(c)
+	if !ok {
+		return
+	}
+
+	integration, err := h.service.Get(c.Request.Context(), tenantID, id)
+	if err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": integration})
+}
+
+// CreateIntegration adds an order integration
+// @Summary Create an order integration
+// @Description Push order timeline events to an external endpoint, rendered with a Go template
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Param request body models.OrderIntegrationRequest true "Integration"
+// @Success 201 {object} models.OrderIntegration
+// @Failure 400 {object} ErrorResponse
+// @Router /order-integrations [post]
+func (h *OrderIntegrationHandler) CreateIntegration(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	var req models.OrderIntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	integration, err := h.service.Create(c.Request.Context(), tenantID, getUserID(c), &req)
+	if err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": integration})
+}
+
+// UpdateIntegration replaces an order integration's settings
+// @Summary Update an order integration
+// @Description Secrets left empty keep their current value
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Param id path string true "Integration ID"
+// @Param request body models.OrderIntegrationRequest true "Integration"
+// @Success 200 {object} models.OrderIntegration
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /order-integrations/{id} [put]
+func (h *OrderIntegrationHandler) UpdateIntegration(c *gin.Context) {
+	tenantID, id, ok := h.integrationParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.OrderIntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	integration, err := h.service.Update(c.Request.Context(), tenantID, id, getUserID(c), &req)
+	if err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": integration})
+}
+
+// DeleteIntegration removes an order integration
+// @Summary Delete an order integration
+// @Tags integrations
+// @Param id path string true "Integration ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /order-integrations/{id} [delete]
+func (h *OrderIntegrationHandler) DeleteIntegration(c *gin.Context) {
+	tenantID, id, ok := h.integrationParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), tenantID, id); err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PreviewIntegration renders an integration's payload for an order without sending it
+// @Summary Preview an integration payload
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Param id path string true "Integration ID"
+// @Param request body models.IntegrationPreviewRequest true "Order and event"
+// @Success 200 {object} models.IntegrationPreview
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /order-integrations/{id}/preview [post]
+func (h *OrderIntegrationHandler) PreviewIntegration(c *gin.Context) {
+	tenantID, id, ok := h.integrationParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.IntegrationPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	preview, err := h.service.Preview(c.Request.Context(), tenantID, id, &req)
+	if err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": preview})
+}
+
+// ListDeliveries returns an integration's delivery log
+// @Summary List integration deliveries
+// @Tags integrations
+// @Produce json
+// @Param id path string true "Integration ID"
+// @Param status query string false "PENDING, DELIVERING, DELIVERED or FAILED"
+// @Param orderId query string false "Order ID"
+// @Param page query int false "Page"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {array} models.OrderIntegrationDelivery
+// @Failure 404 {object} ErrorResponse
+// @Router /order-integrations/{id}/deliveries [get]
+func (h *OrderIntegrationHandler) ListDeliveries(c *gin.Context) {
+	tenantID, id, ok := h.integrationParams(c)
+	if !ok {
+		return
+	}
+
+	filter := models.IntegrationDeliveryFilter{
+		Status: models.IntegrationDeliveryStatus(c.Query("status")),
+	}
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if orderIDStr := c.Query("orderId"); orderIDStr != "" {
+		orderID, err := uuid.Parse(orderIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid order ID",
+				Message: "Order ID must be a valid UUID",
+			})
+			return
+		}
+		filter.OrderID = &orderID
+	}
+
+	deliveries, total, err := h.service.ListDeliveries(c.Request.Context(), tenantID, id, filter)
+	if err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": deliveries, "total": total})
+}
+
+// ReplayDelivery queues a delivered or failed delivery to be sent again
+// @Summary Replay an integration delivery
+// @Tags integrations
+// @Produce json
+// @Param id path string true "Integration ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202 {object} models.OrderIntegrationDelivery
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /order-integrations/{id}/deliveries/{deliveryId}/replay [post]
+func (h *OrderIntegrationHandler) ReplayDelivery(c *gin.Context) {
+	tenantID, id, ok := h.integrationParams(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid delivery ID",
+			Message: "Delivery ID must be a valid UUID",
+		})
+		return
+	}
+
+	replay, err := h.service.ReplayDelivery(c.Request.Context(), tenantID, id, deliveryID)
+	if err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": replay})
+}
+
+// ReplayFailedDeliveries queues the integration's failed deliveries to be sent again
+// @Summary Replay failed integration deliveries
+// @Description Replays up to 500 failed deliveries created since `since` (RFC 3339, default 7 days ago)
+// @Tags integrations
+// @Produce json
+// @Param id path string true "Integration ID"
+// @Param since query string false "Earliest delivery creation time"
+// @Success 202 {object} map[string]int
+// @Failure 404 {object} ErrorResponse
+// @Router /order-integrations/{id}/deliveries/replay-failed [post]
+func (h *OrderIntegrationHandler) ReplayFailedDeliveries(c *gin.Context) {
+	tenantID, id, ok := h.integrationParams(c)
+	if !ok {
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -7)
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid since",
+				Message: "since must be an RFC 3339 timestamp",
+			})
+			return
+		}
+		since = parsed
+	}
+
+	queued, err := h.service.ReplayFailed(c.Request.Context(), tenantID, id, since)
+	if err != nil {
+		respondIntegrationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": gin.H{"queued": queued}})
+}
+
+func (h *OrderIntegrationHandler) integrationParams(c *gin.Context) (string, uuid.UUID, bool) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return "", uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid integration ID",
+			Message: "Integration ID must be a valid UUID",
+		})
+		return "", uuid.Nil, false
+	}
+
+	return tenantID, id, true
+}
+
+func respondIntegrationError(c *gin.Context, err error) {
+	var validationErr *services.IntegrationValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid integration",
+			Message: validationErr.Message,
+		})
+	case errors.Is(err, services.ErrOrderIntegrationNotFound),
+		errors.Is(err, services.ErrIntegrationDeliveryNotFound),
+		errors.Is(err, services.ErrIntegrationOrderNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Not found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrIntegrationDeliveryOutstanding):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Delivery outstanding",
+			Message: "Only delivered or failed deliveries can be replayed",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to process order integration",
+			Message: err.Error(),
+		})
+	}
+}
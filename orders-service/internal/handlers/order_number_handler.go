@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"orders-service/internal/models"
+	"orders-service/internal/services"
+)
+
+// OrderNumberSettingsHandler handles HTTP requests for tenants' order number formats
+type OrderNumberSettingsHandler struct {
+	service *services.OrderNumberSettingsService
+}
+
+// NewOrderNumberSettingsHandler creates a new order number settings handler
+func NewOrderNumberSettingsHandler(service *services.OrderNumberSettingsService) *OrderNumberSettingsHandler {
+	return &OrderNumberSettingsHandler{service: service}
+}
+
+// GetSettings returns the tenant's order number format and the next numbers it will issue
+// GET /api/v1/settings/order-numbers
+// RBAC: settings:store:view
+func (h *OrderNumberSettingsHandler) GetSettings(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "MISSING_TENANT_ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	resp, err := h.service.GetSettings(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "FETCH_FAILED",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateSettings replaces the tenant's order number format
+// PUT /api/v1/settings/order-numbers
+// RBAC: settings:store:edit
+func (h *OrderNumberSettingsHandler) UpdateSettings(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "MISSING_TENANT_ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	var req models.UpdateOrderNumberSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.service.UpdateSettings(c.Request.Context(), tenantID, &req, getUserID(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidOrderNumberFormat) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "INVALID_FORMAT",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "UPDATE_FAILED",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"orders-service/internal/models"
+	"orders-service/internal/services"
+)
+
+// ReportScheduleHandler manages scheduled email reports for the admin dashboard
+type ReportScheduleHandler struct {
+	service *services.ReportScheduleService
+}
+
+// NewReportScheduleHandler creates a new report schedule handler
+func NewReportScheduleHandler(service *services.ReportScheduleService) *ReportScheduleHandler {
+	return &ReportScheduleHandler{service: service}
+}
+
+// ListSchedules returns the tenant's report schedules
+// @Summary List report schedules
+// @Tags reports
+// @Produce json
+// @Success 200 {array} models.ReportSchedule
+// @Failure 403 {object} ErrorResponse
+// @Router /report-schedules [get]
+func (h *ReportScheduleHandler) ListSchedules(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	schedules, err := h.service.List(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to list report schedules",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": schedules})
+}
+
+// GetSchedule returns one report schedule
+// @Summary Get a report schedule
+// @Tags reports
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} models.ReportSchedule
+// @Failure 404 {object} ErrorResponse
+// @Router /report-schedules/{id} [get]
+func (h *ReportScheduleHandler) GetSchedule(c *gin.Context) {
+	tenantID, id, ok := h.scheduleParams(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.Get(c.Request.Context(), tenantID, id)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": schedule})
+}
+
+// CreateSchedule adds a recurring report
+// @Summary Create a report schedule
+// @Description Daily, weekly or monthly sales summary, low stock or vendor payout report emailed as CSV or PDF
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param request body models.ReportScheduleRequest true "Schedule"
+// @Success 201 {object} models.ReportSchedule
+// @Failure 400 {object} ErrorResponse
+// @Router /report-schedules [post]
+func (h *ReportScheduleHandler) CreateSchedule(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.service.Create(c.Request.Context(), tenantID, getUserID(c), &req)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": schedule})
+}
+
+// UpdateSchedule replaces a report schedule's settings
+// @Summary Update a report schedule
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param request body models.ReportScheduleRequest true "Schedule"
+// @Success 200 {object} models.ReportSchedule
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /report-schedules/{id} [put]
+func (h *ReportScheduleHandler) UpdateSchedule(c *gin.Context) {
+	tenantID, id, ok := h.scheduleParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.service.Update(c.Request.Context(), tenantID, id, getUserID(c), &req)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": schedule})
+}
+
+// DeleteSchedule removes a report schedule
+// @Summary Delete a report schedule
+// @Tags reports
+// @Param id path string true "Schedule ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /report-schedules/{id} [delete]
+func (h *ReportScheduleHandler) DeleteSchedule(c *gin.Context) {
+	tenantID, id, ok := h.scheduleParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), tenantID, id); err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RunSchedule sends a schedule's report for its most recent period immediately
+// @Summary Run a report schedule now
+// @Tags reports
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} models.ReportDelivery
+// @Failure 404 {object} ErrorResponse
+// @Router /report-schedules/{id}/run [post]
+func (h *ReportScheduleHandler) RunSchedule(c *gin.Context) {
+	tenantID, id, ok := h.scheduleParams(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.RunNow(c.Request.Context(), tenantID, id)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": delivery})
+}
+
+// ListDeliveries returns a schedule's delivery history
+// @Summary List report deliveries
+// @Tags reports
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {array} models.ReportDelivery
+// @Failure 404 {object} ErrorResponse
+// @Router /report-schedules/{id}/deliveries [get]
+func (h *ReportScheduleHandler) ListDeliveries(c *gin.Context) {
+	tenantID, id, ok := h.scheduleParams(c)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.service.ListDeliveries(c.Request.Context(), tenantID, id)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": deliveries})
+}
+
+// DownloadDelivery returns a short-lived link to a delivered report file
+// @Summary Download a delivered report
+// @Tags reports
+// @Produce json
+// @Param deliveryId path string true "Delivery ID"
+// @Success 200 {object} models.ReportDownload
+// @Failure 404 {object} ErrorResponse
+// @Router /report-schedules/deliveries/{deliveryId}/download [get]
+func (h *ReportScheduleHandler) DownloadDelivery(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid delivery ID",
+			Message: "Delivery ID must be a valid UUID",
+		})
+		return
+	}
+
+	download, err := h.service.GetDownload(c.Request.Context(), tenantID, deliveryID)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": download})
+}
+
+func (h *ReportScheduleHandler) scheduleParams(c *gin.Context) (string, uuid.UUID, bool) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return "", uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid schedule ID",
+			Message: "Schedule ID must be a valid UUID",
+		})
+		return "", uuid.Nil, false
+	}
+
+	return tenantID, id, true
+}
+
+func respondReportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrReportScheduleNotFound), errors.Is(err, services.ErrReportDeliveryNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Not found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrReportFileUnavailable):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Report file unavailable",
+			Message: "This delivery failed before its file was stored",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to process report schedule",
+			Message: err.Error(),
+		})
+	}
+}
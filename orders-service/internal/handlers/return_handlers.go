@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"orders-service/internal/models"
 	"orders-service/internal/services"
@@ -198,12 +199,12 @@ func (h *ReturnHandlers) ListReturns(c *gin.Context) {
 
 // ApproveReturn approves a return request
 // @Summary Approve return
-// @Description Admin approves a return request
+// @Description Admin approves a return request, optionally generating the return shipping label
 // @Tags Returns
 // @Accept json
 // @Produce json
 // @Param id path string true "Return ID"
-// @Param request body map[string]string true "Approval details"
+// @Param request body map[string]interface{} true "Approval details (notes, generateLabel)"
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/returns/{id}/approve [post]
 func (h *ReturnHandlers) ApproveReturn(c *gin.Context) {
@@ -232,7 +233,8 @@ func (h *ReturnHandlers) ApproveReturn(c *gin.Context) {
 	}
 
 	var req struct {
-		Notes string `json:"notes"`
+		Notes         string `json:"notes"`
+		GenerateLabel bool   `json:"generateLabel"` // Book the return parcel with shipping-service once approved
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
@@ -257,7 +259,112 @@ func (h *ReturnHandlers) ApproveReturn(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Return approved successfully"})
+	if !req.GenerateLabel {
+		c.JSON(http.StatusOK, gin.H{"message": "Return approved successfully"})
+		return
+	}
+
+	// The approval stands when the label can't be generated; staff can retry it on its own
+	labeled, err := h.returnService.GenerateReturnLabel(tenantID, id, &approvedBy)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Return approved, but the return label could not be generated",
+			"labelError": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Return approved successfully", "return": labeled})
+}
+
+// GenerateReturnLabel generates the return shipping label for an approved return
+// @Summary Generate return label
+// @Description Books the return parcel with shipping-service and stores its label and tracking on the return
+// @Tags Returns
+// @Produce json
+// @Param id path string true "Return ID"
+// @Success 200 {object} models.Return
+// @Router /api/v1/returns/{id}/label [post]
+func (h *ReturnHandlers) GenerateReturnLabel(c *gin.Context) {
+	tenantID, ok := getReturnTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing tenant ID", "message": "X-Tenant-ID header is required"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return ID"})
+		return
+	}
+
+	// Get user ID from context
+	var userID *uuid.UUID
+	if userIDStr, exists := c.Get("user_id"); exists {
+		if parsedID, err := uuid.Parse(userIDStr.(string)); err == nil {
+			userID = &parsedID
+		}
+	}
+
+	ret, err := h.returnService.GenerateReturnLabel(tenantID, id, userID)
+	if errors.Is(err, services.ErrReturnNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Return not found"})
+		return
+	}
+	if errors.Is(err, services.ErrReturnNotLabelable) || errors.Is(err, services.ErrReturnLabelExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cannot generate return label", "message": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrReturnLabelFailed) {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to generate return label", "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate return label"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ret)
+}
+
+// GetCustomerReturn retrieves the status of a customer's own return
+// @Summary Get customer return status
+// @Description Customer views the status of a return on one of their orders, with its shipping label once generated
+// @Tags Returns
+// @Produce json
+// @Param id path string true "Return ID"
+// @Success 200 {object} services.CustomerReturnStatus
+// @Router /api/v1/storefront/my/returns/{id} [get]
+func (h *ReturnHandlers) GetCustomerReturn(c *gin.Context) {
+	tenantID, ok := getReturnTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing tenant ID", "message": "X-Tenant-ID header is required"})
+		return
+	}
+
+	customerEmail := c.GetString("customer_email")
+	if customerEmail == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized", "message": "Customer authentication required"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return ID"})
+		return
+	}
+
+	status, err := h.returnService.GetCustomerReturn(tenantID, id, customerEmail)
+	if errors.Is(err, services.ErrReturnNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Return not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch return"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
 }
 
 // RejectReturn rejects a return request
@@ -630,6 +737,107 @@ func (h *ReturnHandlers) CancelReturn(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Return cancelled successfully"})
 }
 
+// GetIntakeQRCode returns the drop-off QR code for an approved return
+// @Summary Get return intake QR code
+// @Description PNG QR code encoding the return's intake code, for the customer to bring to drop-off
+// @Tags Returns
+// @Produce png
+// @Param id path string true "Return ID"
+// @Param size query int false "Image size in pixels" default(256)
+// @Success 200 {file} binary
+// @Router /api/v1/returns/{id}/intake-qr [get]
+func (h *ReturnHandlers) GetIntakeQRCode(c *gin.Context) {
+	tenantID, ok := getReturnTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing tenant ID", "message": "X-Tenant-ID header is required"})
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid return ID"})
+		return
+	}
+
+	// SECURITY: Verify return belongs to this tenant
+	ret, err := h.returnService.GetReturn(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Return not found"})
+		return
+	}
+	if ret.TenantID != tenantID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Return not found"})
+		return
+	}
+
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "256"))
+	if size < 64 || size > 1024 {
+		size = 256
+	}
+
+	image, err := h.returnService.RenderIntakeQRCode(ret, size)
+	if errors.Is(err, services.ErrReturnNotReceivable) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Return has no intake code until it is approved"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate QR code"})
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", image)
+}
+
+// ScanIntake receives a return by scanning its drop-off QR code
+// @Summary Scan return intake
+// @Description Warehouse staff scan a return's QR code to mark it received and get a pre-filled inspection
+// @Tags Returns
+// @Accept json
+// @Produce json
+// @Param request body map[string]string true "Scanned intake code"
+// @Success 200 {object} services.ReturnIntakeResult
+// @Router /api/v1/returns/intake/scan [post]
+func (h *ReturnHandlers) ScanIntake(c *gin.Context) {
+	tenantID, ok := getReturnTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing tenant ID", "message": "X-Tenant-ID header is required"})
+		return
+	}
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	// Get user ID from context
+	var userID *uuid.UUID
+	if userIDStr, exists := c.Get("user_id"); exists {
+		if parsedID, err := uuid.Parse(userIDStr.(string)); err == nil {
+			userID = &parsedID
+		}
+	}
+
+	result, err := h.returnService.ScanReturnIntake(tenantID, req.Code, userID)
+	if errors.Is(err, services.ErrIntakeCodeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Return not found"})
+		return
+	}
+	if errors.Is(err, services.ErrReturnNotReceivable) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Return is not awaiting items", "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to receive return"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // GetReturnStats retrieves return statistics
 // @Summary Get return stats
 // @Description Get return statistics for tenant
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+	"orders-service/internal/services"
+)
+
+// StuckOrderHandler serves the ops view of orders flagged by the stuck order watchdog
+type StuckOrderHandler struct {
+	service *services.StuckOrderService
+}
+
+// NewStuckOrderHandler creates a new stuck order handler
+func NewStuckOrderHandler(service *services.StuckOrderService) *StuckOrderHandler {
+	return &StuckOrderHandler{service: service}
+}
+
+// ListStuckOrders returns the tenant's stuck order alerts with their escalation tickets
+// GET /api/v1/orders/stuck?reason=PAID_NOT_FULFILLED|SHIPPED_NOT_DELIVERED&status=open|resolved|all&page=1&limit=50
+// RBAC: orders:read
+func (h *StuckOrderHandler) ListStuckOrders(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "MISSING_TENANT_ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+	if gosharedmw.GetVendorScopeFilter(c) != "" {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "ACCESS_DENIED",
+			Message: "Stuck orders are only available to store staff",
+		})
+		return
+	}
+
+	filters := repository.StuckOrderFilters{
+		Reason: models.StuckOrderReason(c.Query("reason")),
+	}
+	switch filters.Reason {
+	case "", models.StuckOrderPaidNotFulfilled, models.StuckOrderShippedNotDelivered:
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_REASON",
+			Message: "reason must be PAID_NOT_FULFILLED or SHIPPED_NOT_DELIVERED",
+		})
+		return
+	}
+
+	switch c.DefaultQuery("status", "open") {
+	case "open":
+		resolved := false
+		filters.Resolved = &resolved
+	case "resolved":
+		resolved := true
+		filters.Resolved = &resolved
+	case "all":
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "INVALID_STATUS",
+			Message: "status must be open, resolved or all",
+		})
+		return
+	}
+
+	filters.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filters.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	if filters.Limit < 1 || filters.Limit > 200 {
+		filters.Limit = 50
+	}
+
+	resp, err := h.service.List(c.Request.Context(), tenantID, filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "LIST_FAILED",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
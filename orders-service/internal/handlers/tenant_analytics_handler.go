@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"orders-service/internal/services"
+)
+
+// TenantAnalyticsHandler serves tenant-wide business analytics for the admin dashboard
+type TenantAnalyticsHandler struct {
+	analyticsService *services.TenantAnalyticsService
+}
+
+// NewTenantAnalyticsHandler creates a new tenant analytics handler
+func NewTenantAnalyticsHandler(analyticsService *services.TenantAnalyticsService) *TenantAnalyticsHandler {
+	return &TenantAnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetOverview returns sales, AOV, cart conversion, refund rate and a daily series
+// @Summary Get tenant analytics overview
+// @Description Tenant-wide metrics built from daily rollups. Not available to vendor-scoped users.
+// @Tags analytics
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} models.TenantAnalyticsOverview
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /analytics/overview [get]
+func (h *TenantAnalyticsHandler) GetOverview(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parseAnalyticsRange(c)
+	if !ok {
+		return
+	}
+
+	overview, err := h.analyticsService.GetOverview(tenantID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to load analytics",
+			Message: "Unable to compute analytics at this time",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+// GetSales returns only the daily sales series
+// @Summary Get tenant daily sales
+// @Description Daily gross sales, orders, AOV, refunds, new customers and carts started
+// @Tags analytics
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success 200 {array} models.TenantDailyPoint
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /analytics/sales [get]
+func (h *TenantAnalyticsHandler) GetSales(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parseAnalyticsRange(c)
+	if !ok {
+		return
+	}
+
+	overview, err := h.analyticsService.GetOverview(tenantID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to load analytics",
+			Message: "Unable to compute analytics at this time",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":  overview.From,
+		"to":    overview.To,
+		"daily": overview.Daily,
+	})
+}
+
+// GetTopCategories returns categories ranked by revenue
+// @Summary Get top categories
+// @Description Categories ranked by revenue with share of total categorized revenue
+// @Tags analytics
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param limit query int false "Number of categories" default(10)
+// @Success 200 {array} models.CategorySales
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /analytics/categories [get]
+func (h *TenantAnalyticsHandler) GetTopCategories(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parseAnalyticsRange(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	categories, err := h.analyticsService.GetTopCategories(tenantID, from, to, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to load analytics",
+			Message: "Unable to compute analytics at this time",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":       from.Format("2006-01-02"),
+		"to":         to.Format("2006-01-02"),
+		"categories": categories,
+	})
+}
+
+// GetMarginReport returns gross margin over the period's orders and a page of orders
+// @Summary Get order margin report
+// @Description Revenue, snapshotted product cost and gross margin for uncancelled orders. Requires catalog:costs:view.
+// @Tags analytics
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Orders per page" default(20)
+// @Success 200 {object} models.MarginReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /analytics/margins [get]
+func (h *TenantAnalyticsHandler) GetMarginReport(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parseAnalyticsRange(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	report, err := h.analyticsService.GetMarginReport(tenantID, from, to, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to load margins",
+			Message: "Unable to compute margins at this time",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetOrderMargin returns an order's gross margin per item
+// @Summary Get order gross margin
+// @Description Revenue less discounts, snapshotted product cost and gross margin for one order. Requires catalog:costs:view.
+// @Tags analytics
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} models.OrderMargin
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /analytics/margins/orders/{id} [get]
+func (h *TenantAnalyticsHandler) GetOrderMargin(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid order ID",
+			Message: "Order ID must be a valid UUID",
+		})
+		return
+	}
+
+	margin, err := h.analyticsService.GetOrderMargin(tenantID, orderID)
+	if errors.Is(err, services.ErrMarginOrderNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Order not found",
+			Message: "No order with that ID",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to load margin",
+			Message: "Unable to compute the order's margin at this time",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, margin)
+}
+
+// requireTenantWideAccess resolves the tenant and rejects vendor-scoped users,
+// who must use the vendor analytics endpoint instead of tenant-wide data
+func requireTenantWideAccess(c *gin.Context) (string, bool) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return "", false
+	}
+
+	if gosharedmw.GetVendorScopeFilter(c) != "" {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Access denied",
+			Message: "Tenant-wide analytics are not available to vendor users; use /orders/analytics/vendor",
+		})
+		return "", false
+	}
+
+	return tenantID, true
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"orders-service/internal/services"
+)
+
+// VendorAnalyticsHandler serves vendor-scoped dashboard analytics
+type VendorAnalyticsHandler struct {
+	analyticsService *services.VendorAnalyticsService
+}
+
+// NewVendorAnalyticsHandler creates a new vendor analytics handler
+func NewVendorAnalyticsHandler(analyticsService *services.VendorAnalyticsService) *VendorAnalyticsHandler {
+	return &VendorAnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetVendorAnalytics returns GMV, order counts, return rate and top products for a vendor
+// @Summary Get vendor analytics
+// @Description Vendor dashboard metrics built from daily rollups. Vendor-scoped users always see their own vendor; tenant-level users must pass vendorId.
+// @Tags orders
+// @Produce json
+// @Param vendorId query string false "Vendor ID (tenant-level users only)"
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} models.VendorAnalytics
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /orders/analytics/vendor [get]
+func (h *VendorAnalyticsHandler) GetVendorAnalytics(c *gin.Context) {
+	tenantID, ok := getTenantID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "X-Tenant-ID header is required",
+		})
+		return
+	}
+
+	vendorID, ok := resolveAnalyticsVendor(c)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parseAnalyticsRange(c)
+	if !ok {
+		return
+	}
+
+	analytics, err := h.analyticsService.GetVendorAnalytics(tenantID, vendorID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to load vendor analytics",
+			Message: "Unable to compute analytics at this time",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// resolveAnalyticsVendor picks the vendor to report on. Vendor-scoped users are pinned
+// to their own vendor by VendorScopeFilter and cannot request another vendor's data.
+func resolveAnalyticsVendor(c *gin.Context) (string, bool) {
+	requested := c.Query("vendorId")
+	scoped := gosharedmw.GetVendorScopeFilter(c)
+
+	if scoped != "" {
+		if requested != "" && requested != scoped {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "Access denied",
+				Message: "You can only view analytics for your own vendor",
+			})
+			return "", false
+		}
+		return scoped, true
+	}
+
+	if requested == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing vendor ID",
+			Message: "vendorId query parameter is required for tenant-level users",
+		})
+		return "", false
+	}
+	return requested, true
+}
+
+// parseAnalyticsRange reads from/to (YYYY-MM-DD, inclusive) and defaults to the last 30 days
+func parseAnalyticsRange(c *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -29)
+
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid from date",
+				Message: "from must be formatted as YYYY-MM-DD",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid to date",
+				Message: "to must be formatted as YYYY-MM-DD",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid date range",
+			Message: "from must be on or before to",
+		})
+		return time.Time{}, time.Time{}, false
+	}
+	if to.Sub(from) > time.Duration(services.MaxAnalyticsRangeDays-1)*24*time.Hour {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid date range",
+			Message: "date range cannot exceed one year",
+		})
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
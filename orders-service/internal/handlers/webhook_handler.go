@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"orders-service/internal/models"
+	"orders-service/internal/services"
+)
+
+// WebhookHandler manages tenants' order lifecycle webhook subscriptions
+type WebhookHandler struct {
+	service *services.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(service *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// ListSubscriptions returns the tenant's webhook subscriptions
+// @Summary List webhook subscriptions
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} models.WebhookSubscription
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := h.service.List(c.Request.Context(), tenantID)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": subscriptions})
+}
+
+// GetSubscription returns one webhook subscription
+// @Summary Get a webhook subscription
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 404 {object} ErrorResponse
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+	tenantID, id, ok := h.subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.service.Get(c.Request.Context(), tenantID, id)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": subscription})
+}
+
+// CreateSubscription adds a webhook subscription
+// @Summary Create a webhook subscription
+// @Description Sends order.created, order.paid, order.fulfilled, order.cancelled and order.refunded events as signed JSON. The signing secret is only returned here and when rotated.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body models.WebhookSubscriptionRequest true "Subscription"
+// @Success 201 {object} models.WebhookSubscriptionWithSecret
+// @Failure 400 {object} ErrorResponse
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	subscription, err := h.service.Create(c.Request.Context(), tenantID, getUserID(c), &req)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": subscription})
+}
+
+// UpdateSubscription replaces a webhook subscription's settings
+// @Summary Update a webhook subscription
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param request body models.WebhookSubscriptionRequest true "Subscription"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateSubscription(c *gin.Context) {
+	tenantID, id, ok := h.subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	subscription, err := h.service.Update(c.Request.Context(), tenantID, id, getUserID(c), &req)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": subscription})
+}
+
+// RotateSecret replaces a webhook subscription's signing secret
+// @Summary Rotate a webhook signing secret
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} models.WebhookSubscriptionWithSecret
+// @Failure 404 {object} ErrorResponse
+// @Router /webhooks/{id}/rotate-secret [post]
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	tenantID, id, ok := h.subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.service.RotateSecret(c.Request.Context(), tenantID, id, getUserID(c))
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": subscription})
+}
+
+// DeleteSubscription removes a webhook subscription
+// @Summary Delete a webhook subscription
+// @Tags webhooks
+// @Param id path string true "Subscription ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	tenantID, id, ok := h.subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), tenantID, id); err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries returns a webhook subscription's delivery log
+// @Summary List webhook deliveries
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param status query string false "PENDING, DELIVERING, DELIVERED or FAILED"
+// @Param event query string false "Event, e.g. order.paid"
+// @Param orderId query string false "Order ID"
+// @Param page query int false "Page"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 404 {object} ErrorResponse
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	tenantID, id, ok := h.subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	filter := models.WebhookDeliveryFilter{
+		Status: models.WebhookDeliveryStatus(c.Query("status")),
+		Event:  c.Query("event"),
+	}
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if orderIDStr := c.Query("orderId"); orderIDStr != "" {
+		orderID, err := uuid.Parse(orderIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid order ID",
+				Message: "Order ID must be a valid UUID",
+			})
+			return
+		}
+		filter.OrderID = &orderID
+	}
+
+	deliveries, total, err := h.service.ListDeliveries(c.Request.Context(), tenantID, id, filter)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": deliveries, "total": total})
+}
+
+// ReplayDelivery queues a delivered or failed delivery to be sent again
+// @Summary Replay a webhook delivery
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202 {object} models.WebhookDelivery
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /webhooks/{id}/deliveries/{deliveryId}/replay [post]
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	tenantID, id, ok := h.subscriptionParams(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid delivery ID",
+			Message: "Delivery ID must be a valid UUID",
+		})
+		return
+	}
+
+	replay, err := h.service.ReplayDelivery(c.Request.Context(), tenantID, id, deliveryID)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": replay})
+}
+
+func (h *WebhookHandler) subscriptionParams(c *gin.Context) (string, uuid.UUID, bool) {
+	tenantID, ok := requireTenantWideAccess(c)
+	if !ok {
+		return "", uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid subscription ID",
+			Message: "Subscription ID must be a valid UUID",
+		})
+		return "", uuid.Nil, false
+	}
+
+	return tenantID, id, true
+}
+
+func respondWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhookURL):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid webhook subscription",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrWebhookSubscriptionNotFound),
+		errors.Is(err, services.ErrWebhookDeliveryNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Not found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrWebhookDeliveryOutstanding):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Delivery outstanding",
+			Message: "Only delivered or failed deliveries can be replayed",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Failed to process webhook subscription",
+			Message: err.Error(),
+		})
+	}
+}
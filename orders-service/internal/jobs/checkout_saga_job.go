@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/services"
+)
+
+// CheckoutSagaJob retries checkout saga steps that failed, watches unpaid checkouts for payment
+// and undoes the ones whose stock hold expired
+type CheckoutSagaJob struct {
+	checkoutService *services.CheckoutService
+	logger          *logrus.Logger
+	interval        time.Duration
+	batchSize       int
+	stopCh          chan struct{}
+}
+
+// NewCheckoutSagaJob creates a new checkout saga job
+func NewCheckoutSagaJob(checkoutService *services.CheckoutService, logger *logrus.Logger) *CheckoutSagaJob {
+	return &CheckoutSagaJob{
+		checkoutService: checkoutService,
+		logger:          logger,
+		interval:        5 * time.Second,
+		batchSize:       100,
+		stopCh:          make(chan struct{}),
+	}
+}
+
+// Start begins the checkout saga job
+func (j *CheckoutSagaJob) Start(ctx context.Context) {
+	j.logger.Info("Checkout saga job started")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.run(ctx)
+		case <-j.stopCh:
+			j.logger.Info("Checkout saga job stopped")
+			return
+		case <-ctx.Done():
+			j.logger.Info("Checkout saga job context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *CheckoutSagaJob) Stop() {
+	close(j.stopCh)
+}
+
+func (j *CheckoutSagaJob) run(ctx context.Context) {
+	// A full batch means more are due; keep going rather than wait for the next tick
+	for {
+		finished, claimed, err := j.checkoutService.ProcessDue(ctx, time.Now(), j.batchSize)
+		if err != nil {
+			j.logger.Errorf("Failed to process checkout sagas: %v", err)
+			return
+		}
+		if finished > 0 {
+			j.logger.Infof("Finished %d checkout sagas", finished)
+		}
+		if claimed < j.batchSize {
+			return
+		}
+
+		select {
+		case <-j.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
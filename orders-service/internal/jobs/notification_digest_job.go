@@ -0,0 +1,80 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/services"
+)
+
+// NotificationDigestJob sends customers the order notifications held for their digest once the
+// tenant's digest window has passed
+type NotificationDigestJob struct {
+	notifier  *services.OrderNotifier
+	logger    *logrus.Logger
+	interval  time.Duration
+	batchSize int
+	stopCh    chan struct{}
+}
+
+// NewNotificationDigestJob creates a new notification digest job
+func NewNotificationDigestJob(notifier *services.OrderNotifier, logger *logrus.Logger) *NotificationDigestJob {
+	return &NotificationDigestJob{
+		notifier:  notifier,
+		logger:    logger,
+		interval:  30 * time.Second,
+		batchSize: 500,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start begins the notification digest job
+func (j *NotificationDigestJob) Start(ctx context.Context) {
+	j.logger.Info("Notification digest job started")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.run(ctx)
+		case <-j.stopCh:
+			j.logger.Info("Notification digest job stopped")
+			return
+		case <-ctx.Done():
+			j.logger.Info("Notification digest job context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *NotificationDigestJob) Stop() {
+	close(j.stopCh)
+}
+
+func (j *NotificationDigestJob) run(ctx context.Context) {
+	for {
+		sent, claimed, err := j.notifier.FlushDue(ctx, time.Now(), j.batchSize)
+		if err != nil {
+			j.logger.Errorf("Failed to send notification digests: %v", err)
+			return
+		}
+		if sent > 0 {
+			j.logger.Infof("Sent %d queued order notifications", sent)
+		}
+		if claimed < j.batchSize {
+			return
+		}
+
+		select {
+		case <-j.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
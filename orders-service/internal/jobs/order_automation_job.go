@@ -0,0 +1,116 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+	"orders-service/internal/services"
+)
+
+// OrderAutomationJob applies each tenant's order automation settings: it starts fulfillment
+// once the auto-confirmation delay has passed and cancels orders left unpaid too long
+type OrderAutomationJob struct {
+	settingsRepo *repository.CancellationSettingsRepository
+	orderRepo    repository.OrderRepository
+	orderService services.OrderService
+	logger       *logrus.Logger
+	interval     time.Duration
+	batchSize    int
+	stopCh       chan struct{}
+}
+
+// NewOrderAutomationJob creates a new order automation job
+func NewOrderAutomationJob(settingsRepo *repository.CancellationSettingsRepository, orderRepo repository.OrderRepository, orderService services.OrderService, logger *logrus.Logger) *OrderAutomationJob {
+	return &OrderAutomationJob{
+		settingsRepo: settingsRepo,
+		orderRepo:    orderRepo,
+		orderService: orderService,
+		logger:       logger,
+		interval:     time.Minute, // Settings are in minutes, so check every minute
+		batchSize:    100,         // Per tenant per run; the rest are picked up on the next tick
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// Start begins the automation job
+func (j *OrderAutomationJob) Start(ctx context.Context) {
+	j.logger.Info("Order automation job started")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.run(ctx)
+		case <-j.stopCh:
+			j.logger.Info("Order automation job stopped")
+			return
+		case <-ctx.Done():
+			j.logger.Info("Order automation job context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *OrderAutomationJob) Stop() {
+	close(j.stopCh)
+}
+
+// run processes every tenant that has auto-confirmation or unpaid order cancellation enabled
+func (j *OrderAutomationJob) run(ctx context.Context) {
+	settingsList, err := j.settingsRepo.ListWithOrderAutomation(ctx)
+	if err != nil {
+		j.logger.Errorf("Failed to load order automation settings: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, settings := range settingsList {
+		if settings.UnpaidOrderCancelHours > 0 {
+			j.cancelUnpaidOrders(settings.TenantID, settings.UnpaidOrderCancelHours, now)
+		}
+		if settings.AutoConfirmDelayMinutes > 0 {
+			j.startFulfillment(settings.TenantID, settings.AutoConfirmDelayMinutes, now)
+		}
+	}
+}
+
+// cancelUnpaidOrders cancels orders placed more than hours ago that were never paid
+func (j *OrderAutomationJob) cancelUnpaidOrders(tenantID string, hours int, now time.Time) {
+	ids, err := j.orderRepo.ListUnpaidOrderIDs(tenantID, now.Add(-time.Duration(hours)*time.Hour), j.batchSize)
+	if err != nil {
+		j.logger.Errorf("Failed to list unpaid orders for tenant %s: %v", tenantID, err)
+		return
+	}
+
+	reason := fmt.Sprintf("Payment not received within %d hours", hours)
+	for _, id := range ids {
+		if _, err := j.orderService.ExpireUnpaidOrder(id, reason, tenantID); err != nil {
+			j.logger.Warnf("Failed to cancel unpaid order %s for tenant %s: %v", id, tenantID, err)
+			continue
+		}
+		j.logger.Infof("Cancelled unpaid order %s for tenant %s", id, tenantID)
+	}
+}
+
+// startFulfillment moves orders paid more than minutes ago into fulfillment
+func (j *OrderAutomationJob) startFulfillment(tenantID string, minutes int, now time.Time) {
+	ids, err := j.orderRepo.ListAwaitingFulfillmentOrderIDs(tenantID, now.Add(-time.Duration(minutes)*time.Minute), j.batchSize)
+	if err != nil {
+		j.logger.Errorf("Failed to list orders awaiting fulfillment for tenant %s: %v", tenantID, err)
+		return
+	}
+
+	notes := fmt.Sprintf("Auto-confirmed %d minutes after payment", minutes)
+	for _, id := range ids {
+		if _, err := j.orderService.UpdateFulfillmentStatus(id, models.FulfillmentStatusProcessing, notes, tenantID); err != nil {
+			j.logger.Warnf("Failed to auto-confirm order %s for tenant %s: %v", id, tenantID, err)
+		}
+	}
+}
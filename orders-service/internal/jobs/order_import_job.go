@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+	"orders-service/internal/services"
+)
+
+// OrderImportProcessingJob runs queued order imports one at a time. Jobs are claimed with row
+// locks, so running it on every replica is safe.
+type OrderImportProcessingJob struct {
+	repo     *repository.OrderImportRepository
+	service  *services.OrderImportService
+	logger   *logrus.Logger
+	interval time.Duration
+	stopCh   chan struct{}
+}
+
+// NewOrderImportProcessingJob creates a new order import processing job
+func NewOrderImportProcessingJob(repo *repository.OrderImportRepository, service *services.OrderImportService, logger *logrus.Logger) *OrderImportProcessingJob {
+	return &OrderImportProcessingJob{
+		repo:     repo,
+		service:  service,
+		logger:   logger,
+		interval: 5 * time.Second,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start begins the order import processing job
+func (j *OrderImportProcessingJob) Start(ctx context.Context) {
+	j.logger.Info("Order import processing job started")
+
+	// Cancelled on Stop, so an import in progress stops after its current chunk
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-j.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.run(ctx)
+		case <-ctx.Done():
+			j.logger.Info("Order import processing job stopped")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *OrderImportProcessingJob) Stop() {
+	close(j.stopCh)
+}
+
+// run processes claimed imports until the queue is empty or the job is stopping
+func (j *OrderImportProcessingJob) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := j.repo.ClaimNext(ctx, models.OrderImportStaleAfter)
+		if err != nil {
+			j.logger.Errorf("Failed to claim order import job: %v", err)
+			return
+		}
+		if job == nil {
+			return
+		}
+
+		j.logger.Infof("Processing order import %s for tenant %s (resuming at row %d, attempt %d)",
+			job.ID, job.TenantID, job.NextRow, job.Attempts)
+
+		if err := j.service.ProcessImportJob(ctx, job); err != nil {
+			if ctx.Err() != nil {
+				// Shutting down mid-job: hand the job back so another replica resumes it
+				if reqErr := j.repo.Requeue(context.Background(), job); reqErr != nil {
+					j.logger.Errorf("Failed to requeue order import %s: %v", job.ID, reqErr)
+				}
+				return
+			}
+			j.logger.Errorf("Order import %s failed: %v", job.ID, err)
+			continue
+		}
+		if job.Status == models.OrderImportFailed {
+			j.logger.Errorf("Order import %s failed: %s", job.ID, *job.LastError)
+			continue
+		}
+
+		j.logger.Infof("Order import %s finished: %d orders imported, %d skipped, %d failed",
+			job.ID, job.CreatedCount, job.SkippedCount, job.FailedCount)
+	}
+}
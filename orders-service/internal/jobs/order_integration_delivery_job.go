@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/services"
+)
+
+// OrderIntegrationDeliveryJob sends queued order timeline events to tenants' external
+// integrations
+type OrderIntegrationDeliveryJob struct {
+	integrationService *services.OrderIntegrationService
+	logger             *logrus.Logger
+	interval           time.Duration
+	batchSize          int
+	stopCh             chan struct{}
+}
+
+// NewOrderIntegrationDeliveryJob creates a new order integration delivery job
+func NewOrderIntegrationDeliveryJob(integrationService *services.OrderIntegrationService, logger *logrus.Logger) *OrderIntegrationDeliveryJob {
+	return &OrderIntegrationDeliveryJob{
+		integrationService: integrationService,
+		logger:             logger,
+		interval:           15 * time.Second,
+		batchSize:          100,
+		stopCh:             make(chan struct{}),
+	}
+}
+
+// Start begins the order integration delivery job
+func (j *OrderIntegrationDeliveryJob) Start(ctx context.Context) {
+	j.logger.Info("Order integration delivery job started")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.run(ctx)
+		case <-j.stopCh:
+			j.logger.Info("Order integration delivery job stopped")
+			return
+		case <-ctx.Done():
+			j.logger.Info("Order integration delivery job context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *OrderIntegrationDeliveryJob) Stop() {
+	close(j.stopCh)
+}
+
+func (j *OrderIntegrationDeliveryJob) run(ctx context.Context) {
+	// A full batch means more are waiting; keep going rather than wait for the next tick
+	for {
+		delivered, claimed, err := j.integrationService.DeliverDue(ctx, time.Now(), j.batchSize)
+		if err != nil {
+			j.logger.Errorf("Failed to deliver order integration events: %v", err)
+			return
+		}
+		if delivered > 0 {
+			j.logger.Infof("Delivered %d order integration events", delivered)
+		}
+		if claimed < j.batchSize {
+			return
+		}
+
+		select {
+		case <-j.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
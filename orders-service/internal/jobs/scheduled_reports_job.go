@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/services"
+)
+
+// ScheduledReportsJob renders and emails tenants' scheduled reports when they come due
+type ScheduledReportsJob struct {
+	reportService *services.ReportScheduleService
+	logger        *logrus.Logger
+	interval      time.Duration
+	batchSize     int
+	stopCh        chan struct{}
+}
+
+// NewScheduledReportsJob creates a new scheduled reports job
+func NewScheduledReportsJob(reportService *services.ReportScheduleService, logger *logrus.Logger) *ScheduledReportsJob {
+	return &ScheduledReportsJob{
+		reportService: reportService,
+		logger:        logger,
+		interval:      5 * time.Minute, // Reports run on the hour, so a few minutes late is fine
+		batchSize:     50,              // The rest are picked up on the next tick
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// Start begins the scheduled reports job
+func (j *ScheduledReportsJob) Start(ctx context.Context) {
+	j.logger.Info("Scheduled reports job started")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.run(ctx)
+		case <-j.stopCh:
+			j.logger.Info("Scheduled reports job stopped")
+			return
+		case <-ctx.Done():
+			j.logger.Info("Scheduled reports job context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *ScheduledReportsJob) Stop() {
+	close(j.stopCh)
+}
+
+func (j *ScheduledReportsJob) run(ctx context.Context) {
+	ran, err := j.reportService.RunDue(ctx, time.Now(), j.batchSize)
+	if err != nil {
+		j.logger.Errorf("Failed to run scheduled reports: %v", err)
+		return
+	}
+	if ran > 0 {
+		j.logger.Infof("Sent %d scheduled reports", ran)
+	}
+}
@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/services"
+)
+
+// StuckOrderWatchdogJob periodically flags orders stuck past their thresholds and escalates
+// them to ops tickets
+type StuckOrderWatchdogJob struct {
+	stuckOrderService *services.StuckOrderService
+	logger            *logrus.Logger
+	interval          time.Duration
+	batchSize         int
+	stopCh            chan struct{}
+}
+
+// NewStuckOrderWatchdogJob creates a new stuck order watchdog job
+func NewStuckOrderWatchdogJob(stuckOrderService *services.StuckOrderService, logger *logrus.Logger) *StuckOrderWatchdogJob {
+	return &StuckOrderWatchdogJob{
+		stuckOrderService: stuckOrderService,
+		logger:            logger,
+		interval:          15 * time.Minute,
+		batchSize:         200,
+		stopCh:            make(chan struct{}),
+	}
+}
+
+// Start begins the stuck order watchdog job
+func (j *StuckOrderWatchdogJob) Start(ctx context.Context) {
+	j.logger.Info("Stuck order watchdog job started")
+
+	// Run once at startup so a restart doesn't delay detection by a full interval
+	j.run(ctx)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.run(ctx)
+		case <-j.stopCh:
+			j.logger.Info("Stuck order watchdog job stopped")
+			return
+		case <-ctx.Done():
+			j.logger.Info("Stuck order watchdog job context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *StuckOrderWatchdogJob) Stop() {
+	close(j.stopCh)
+}
+
+func (j *StuckOrderWatchdogJob) run(ctx context.Context) {
+	result, err := j.stuckOrderService.Scan(ctx, time.Now(), j.batchSize)
+	if err != nil {
+		j.logger.Errorf("Stuck order watchdog scan failed: %v", err)
+		return
+	}
+	if result.Detected > 0 || result.Escalated > 0 || result.Resolved > 0 {
+		j.logger.Infof("Stuck order watchdog: %d detected, %d escalated, %d resolved", result.Detected, result.Escalated, result.Resolved)
+	}
+}
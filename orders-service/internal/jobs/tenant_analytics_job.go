@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/clients"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+// TenantAnalyticsJob keeps the tenant_daily_metrics and tenant_daily_category_sales rollups current
+type TenantAnalyticsJob struct {
+	repo           *repository.TenantAnalyticsRepository
+	productsClient clients.ProductsClient
+	logger         *logrus.Logger
+	interval       time.Duration
+	lookbackDays   int
+	backfillDays   int
+	categoryCache  map[string]string // tenantID/productID -> categoryID
+	stopCh         chan struct{}
+}
+
+// NewTenantAnalyticsJob creates a new tenant analytics rollup job
+func NewTenantAnalyticsJob(repo *repository.TenantAnalyticsRepository, productsClient clients.ProductsClient, logger *logrus.Logger) *TenantAnalyticsJob {
+	return &TenantAnalyticsJob{
+		repo:           repo,
+		productsClient: productsClient,
+		logger:         logger,
+		interval:       15 * time.Minute, // Refresh every 15 minutes
+		lookbackDays:   7,                // Re-roll recent days so late cancellations/refunds are picked up
+		backfillDays:   90,               // First run after startup backfills a quarter of history
+		categoryCache:  make(map[string]string),
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// Start begins the rollup job
+func (j *TenantAnalyticsJob) Start(ctx context.Context) {
+	j.logger.Info("Tenant analytics rollup job started")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	j.runRollup(j.backfillDays)
+
+	for {
+		select {
+		case <-ticker.C:
+			j.runRollup(j.lookbackDays)
+		case <-j.stopCh:
+			j.logger.Info("Tenant analytics rollup job stopped")
+			return
+		case <-ctx.Done():
+			j.logger.Info("Tenant analytics rollup job context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *TenantAnalyticsJob) Stop() {
+	close(j.stopCh)
+}
+
+// runRollup recomputes today plus the previous days-1 days
+func (j *TenantAnalyticsJob) runRollup(days int) {
+	today := time.Now().UTC()
+	total := 0
+	for i := days - 1; i >= 0; i-- {
+		day := today.AddDate(0, 0, -i)
+		n, err := j.repo.RollupDay(day)
+		if err != nil {
+			j.logger.Errorf("Failed to roll up tenant metrics for %s: %v", day.Format("2006-01-02"), err)
+			continue
+		}
+		total += n
+		if err := j.rollupCategories(day); err != nil {
+			j.logger.Errorf("Failed to roll up category sales for %s: %v", day.Format("2006-01-02"), err)
+		}
+	}
+	j.logger.Debugf("Tenant analytics rollup refreshed %d rows over %d days", total, days)
+}
+
+// rollupCategories groups a day's product sales by category, resolving categories via products-service
+func (j *TenantAnalyticsJob) rollupCategories(day time.Time) error {
+	sales, err := j.repo.ListProductSales(day)
+	if err != nil {
+		return err
+	}
+
+	statDate := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	byTenant := make(map[string]map[string]*models.TenantDailyCategorySale)
+	for _, sale := range sales {
+		categoryID := j.categoryFor(sale.TenantID, sale.ProductID)
+		categories, ok := byTenant[sale.TenantID]
+		if !ok {
+			categories = make(map[string]*models.TenantDailyCategorySale)
+			byTenant[sale.TenantID] = categories
+		}
+		row, ok := categories[categoryID]
+		if !ok {
+			row = &models.TenantDailyCategorySale{TenantID: sale.TenantID, StatDate: statDate, CategoryID: categoryID}
+			categories[categoryID] = row
+		}
+		row.Revenue += sale.Revenue
+		row.Quantity += sale.Quantity
+	}
+
+	for tenantID, categories := range byTenant {
+		rows := make([]models.TenantDailyCategorySale, 0, len(categories))
+		for _, row := range categories {
+			rows = append(rows, *row)
+		}
+		if err := j.repo.ReplaceCategorySales(tenantID, day, rows); err != nil {
+			j.logger.Errorf("Failed to store category sales for tenant %s: %v", tenantID, err)
+		}
+	}
+	return nil
+}
+
+// categoryFor resolves a product's category, caching hits. Lookup failures are not cached
+// so a transient products-service outage doesn't permanently mislabel a product.
+func (j *TenantAnalyticsJob) categoryFor(tenantID, productID string) string {
+	key := tenantID + "/" + productID
+	if categoryID, ok := j.categoryCache[key]; ok {
+		return categoryID
+	}
+
+	product, err := j.productsClient.GetProduct(productID, tenantID)
+	if err != nil {
+		j.logger.Debugf("Could not resolve category for product %s: %v", productID, err)
+		return models.UncategorizedCategoryID
+	}
+
+	categoryID := product.CategoryID
+	if categoryID == "" {
+		categoryID = models.UncategorizedCategoryID
+	}
+	j.categoryCache[key] = categoryID
+	return categoryID
+}
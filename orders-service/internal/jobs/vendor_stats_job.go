@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/repository"
+)
+
+// VendorStatsJob keeps the vendor_daily_stats rollup table current
+type VendorStatsJob struct {
+	repo         *repository.VendorAnalyticsRepository
+	logger       *logrus.Logger
+	interval     time.Duration
+	lookbackDays int
+	backfillDays int
+	stopCh       chan struct{}
+}
+
+// NewVendorStatsJob creates a new vendor stats rollup job
+func NewVendorStatsJob(repo *repository.VendorAnalyticsRepository, logger *logrus.Logger) *VendorStatsJob {
+	return &VendorStatsJob{
+		repo:         repo,
+		logger:       logger,
+		interval:     15 * time.Minute, // Refresh every 15 minutes
+		lookbackDays: 7,                // Re-roll recent days so late cancellations/returns are picked up
+		backfillDays: 90,               // First run after startup backfills a quarter of history
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// Start begins the rollup job
+func (j *VendorStatsJob) Start(ctx context.Context) {
+	j.logger.Info("Vendor stats rollup job started")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	j.runRollup(j.backfillDays)
+
+	for {
+		select {
+		case <-ticker.C:
+			j.runRollup(j.lookbackDays)
+		case <-j.stopCh:
+			j.logger.Info("Vendor stats rollup job stopped")
+			return
+		case <-ctx.Done():
+			j.logger.Info("Vendor stats rollup job context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *VendorStatsJob) Stop() {
+	close(j.stopCh)
+}
+
+// runRollup recomputes today plus the previous days-1 days
+func (j *VendorStatsJob) runRollup(days int) {
+	today := time.Now().UTC()
+	total := 0
+	for i := days - 1; i >= 0; i-- {
+		day := today.AddDate(0, 0, -i)
+		n, err := j.repo.RollupDay(day)
+		if err != nil {
+			j.logger.Errorf("Failed to roll up vendor stats for %s: %v", day.Format("2006-01-02"), err)
+			continue
+		}
+		total += n
+	}
+	j.logger.Debugf("Vendor stats rollup refreshed %d rows over %d days", total, days)
+}
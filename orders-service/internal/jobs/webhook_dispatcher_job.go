@@ -0,0 +1,80 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/services"
+)
+
+// WebhookDispatcherJob sends queued order lifecycle events to tenants' webhook subscriptions
+type WebhookDispatcherJob struct {
+	webhookService *services.WebhookService
+	logger         *logrus.Logger
+	interval       time.Duration
+	batchSize      int
+	stopCh         chan struct{}
+}
+
+// NewWebhookDispatcherJob creates a new webhook dispatcher job
+func NewWebhookDispatcherJob(webhookService *services.WebhookService, logger *logrus.Logger) *WebhookDispatcherJob {
+	return &WebhookDispatcherJob{
+		webhookService: webhookService,
+		logger:         logger,
+		interval:       5 * time.Second,
+		batchSize:      100,
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// Start begins the webhook dispatcher job
+func (j *WebhookDispatcherJob) Start(ctx context.Context) {
+	j.logger.Info("Webhook dispatcher job started")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.run(ctx)
+		case <-j.stopCh:
+			j.logger.Info("Webhook dispatcher job stopped")
+			return
+		case <-ctx.Done():
+			j.logger.Info("Webhook dispatcher job context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *WebhookDispatcherJob) Stop() {
+	close(j.stopCh)
+}
+
+func (j *WebhookDispatcherJob) run(ctx context.Context) {
+	// A full batch means more are waiting; keep going rather than wait for the next tick
+	for {
+		delivered, claimed, err := j.webhookService.DispatchDue(ctx, time.Now(), j.batchSize)
+		if err != nil {
+			j.logger.Errorf("Failed to dispatch webhooks: %v", err)
+			return
+		}
+		if delivered > 0 {
+			j.logger.Infof("Delivered %d webhooks", delivered)
+		}
+		if claimed < j.batchSize {
+			return
+		}
+
+		select {
+		case <-j.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
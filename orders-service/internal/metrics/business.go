@@ -0,0 +1,73 @@
+// Package metrics records the service's business metrics next to the go-shared HTTP metrics,
+// on the same /metrics endpoint.
+//
+// Labels are kept to bounded sets. Only the order counters are per tenant; everything else is
+// labelled by event or outcome so a busy tenant can't multiply the series.
+package metrics
+
+import (
+	"strings"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Order milestones counted per tenant in tesseract_business_orders_processed_total
+const (
+	OrderCreated   = "created"
+	OrderPaid      = "paid"
+	OrderCancelled = "cancelled"
+)
+
+// Outbound delivery sources
+const (
+	DeliveryWebhook     = "webhook"
+	DeliveryIntegration = "order_integration"
+)
+
+var deliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tesseract",
+	Subsystem: "business",
+	Name:      "webhook_deliveries_total",
+	Help:      "Webhook and order integration delivery attempts, by outcome (delivered, retrying, failed)",
+}, []string{"source", "event", "result"})
+
+var checkoutSagasTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tesseract",
+	Subsystem: "business",
+	Name:      "checkout_sagas_total",
+	Help:      "Checkout sagas that finished, by outcome (completed, compensated, failed)",
+}, []string{"result"})
+
+// RecordOrder counts an order reaching a milestone, using go-shared's per-tenant order counter
+func RecordOrder(tenantID, milestone string) {
+	gosharedmw.OrdersProcessed.WithLabelValues(tenantID, milestone).Inc()
+}
+
+// RecordDelivery counts a delivery attempt by the delivery's status afterwards. Pending means
+// the attempt failed and another is scheduled.
+func RecordDelivery(source, event, status string) {
+	result := "retrying"
+	switch status {
+	case "DELIVERED":
+		result = "delivered"
+	case "FAILED":
+		result = "failed"
+	}
+	deliveriesTotal.WithLabelValues(source, event, result).Inc()
+}
+
+// RecordCheckoutSaga counts a checkout saga finishing with a COMPLETED, COMPENSATED or FAILED status
+func RecordCheckoutSaga(status string) {
+	checkoutSagasTotal.WithLabelValues(strings.ToLower(status)).Inc()
+}
+
+// RecordCacheLookup counts an order cache read as a hit or miss
+func RecordCacheLookup(keyPrefix string, hit bool) {
+	if hit {
+		gosharedmw.RecordCacheHitGlobal("redis", keyPrefix)
+	} else {
+		gosharedmw.RecordCacheMissGlobal("redis", keyPrefix)
+	}
+}
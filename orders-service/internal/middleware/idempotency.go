@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+const (
+	// DefaultIdempotencyTTL is how long a finished request's response is replayed
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	// idempotencyLockTTL is how long a running request holds its key. Order creation calls
+	// products, inventory and payment services, so this is well past their timeouts.
+	idempotencyLockTTL = 2 * time.Minute
+
+	// maxIdempotencyKeyLength matches the width of the key column
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency replays responses to retried requests. A request with an Idempotency-Key header
+// (or the older X-Idempotency-Key) is run once per tenant, route and key; retries within the
+// TTL get the first response back with an Idempotent-Replayed header, and reusing the key for
+// a different body is a 422. Server errors and conflicts aren't stored, so they can be retried
+// with the same key.
+type Idempotency struct {
+	repo   *repository.IdempotencyRepository
+	ttl    time.Duration
+	stopCh chan struct{}
+}
+
+// NewIdempotency creates the idempotency middleware. A ttl of 0 uses DefaultIdempotencyTTL.
+func NewIdempotency(repo *repository.IdempotencyRepository, ttl time.Duration) *Idempotency {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	return &Idempotency{
+		repo:   repo,
+		ttl:    ttl,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Require makes the route idempotent for requests that send a key. It must run after the
+// tenant is resolved.
+func (i *Idempotency) Require() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			key = c.GetHeader("X-Idempotency-Key")
+		}
+		tenantID := c.GetString("tenant_id")
+		if key == "" || tenantID == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "INVALID_IDEMPOTENCY_KEY",
+				"message": "Idempotency-Key must be at most 255 characters",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "INVALID_REQUEST",
+				"message": "Failed to read request body",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+
+		now := time.Now()
+		rec := &models.IdempotencyRecord{
+			TenantID:    tenantID,
+			Scope:       c.Request.Method + " " + c.FullPath(),
+			Key:         key,
+			RequestHash: hex.EncodeToString(sum[:]),
+			LockedUntil: now.Add(idempotencyLockTTL),
+			ExpiresAt:   now.Add(i.ttl),
+		}
+
+		ctx := c.Request.Context()
+		existing, err := i.repo.Reserve(ctx, rec)
+		if err != nil {
+			// Don't turn away checkouts because the store is down; the order's own
+			// idempotency key still stops a duplicate order
+			log.Printf("[Idempotency] Failed to reserve key for tenant %s: %v", tenantID, err)
+			c.Next()
+			return
+		}
+		if existing != nil {
+			replay(c, rec, existing)
+			return
+		}
+
+		writer := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// Store with a fresh context so a client that hung up still leaves the response behind
+		storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		status := writer.Status()
+		if !replayableStatus(status) {
+			if err := i.repo.Release(storeCtx, rec); err != nil {
+				log.Printf("[Idempotency] Failed to release key for tenant %s: %v", tenantID, err)
+			}
+			return
+		}
+		rec.StatusCode = status
+		rec.ContentType = writer.Header().Get("Content-Type")
+		rec.Body = writer.body.Bytes()
+		if err := i.repo.Complete(storeCtx, rec); err != nil {
+			log.Printf("[Idempotency] Failed to store response for tenant %s: %v", tenantID, err)
+		}
+	}
+}
+
+// replay answers a retried request from the record holding its key
+func replay(c *gin.Context, rec, existing *models.IdempotencyRecord) {
+	if existing.RequestHash != rec.RequestHash {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "IDEMPOTENCY_KEY_REUSED",
+			"message": "Idempotency-Key was already used for a different request",
+		})
+		return
+	}
+	if !existing.Completed {
+		retryAfter := math.Ceil(time.Until(existing.LockedUntil).Seconds())
+		c.Header("Retry-After", strconv.Itoa(int(math.Max(1, retryAfter))))
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error":   "IDEMPOTENCY_REQUEST_IN_PROGRESS",
+			"message": "A request with this Idempotency-Key is still being processed",
+		})
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	contentType := existing.ContentType
+	if contentType == "" {
+		contentType = "application/json; charset=utf-8"
+	}
+	c.Data(existing.StatusCode, contentType, existing.Body)
+	c.Abort()
+}
+
+// replayableStatus reports whether a response is final for its request. Server errors,
+// timeouts, conflicts and rate limits may go differently next time.
+func replayableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+// Start deletes expired database records until the middleware is stopped
+func (i *Idempotency) Start(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := i.repo.DeleteExpired(ctx, time.Now()); err != nil {
+				log.Printf("[Idempotency] %v", err)
+			}
+		case <-i.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Stop stops deleting expired records
+func (i *Idempotency) Stop() {
+	close(i.stopCh)
+}
+
+// capturingWriter keeps a copy of the response body as it is written
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
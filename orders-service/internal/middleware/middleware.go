@@ -11,6 +11,8 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+
+	"orders-service/internal/tenancy"
 )
 
 // SetupCORS configures CORS middleware
@@ -33,8 +35,8 @@ func SetupCORS() gin.HandlerFunc {
 			"https://*.civica.tech",
 		},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "accept", "origin", "Cache-Control", "X-Requested-With", "X-Tenant-ID", "X-Vendor-ID", "X-User-ID"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "accept", "origin", "Cache-Control", "X-Requested-With", "X-Tenant-ID", "X-Vendor-ID", "X-User-ID", "Idempotency-Key", "X-Idempotency-Key"},
+		ExposeHeaders:    []string{"Content-Length", "Idempotent-Replayed", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	})
@@ -104,6 +106,8 @@ func TenantID() gin.HandlerFunc {
 		}
 		if tenantID != "" {
 			c.Set("tenant_id", tenantID)
+			// Scope database statements made with the request context (see internal/tenancy)
+			c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), tenantID))
 		}
 		c.Next()
 	}
@@ -177,6 +181,8 @@ func RequireTenantID() gin.HandlerFunc {
 			return
 		}
 		c.Set("tenant_id", tenantID)
+		// Scope database statements made with the request context (see internal/tenancy)
+		c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), tenantID))
 		c.Next()
 	}
 }
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// maxUTMValueLength bounds each UTM value the storefront passes through
+const maxUTMValueLength = 255
+
+// UTMTouch is one set of UTM parameters the storefront captured when the shopper landed
+type UTMTouch struct {
+	Source   string     `json:"source,omitempty"`
+	Medium   string     `json:"medium,omitempty"`
+	Campaign string     `json:"campaign,omitempty"`
+	Content  string     `json:"content,omitempty"`
+	Term     string     `json:"term,omitempty"`
+	LandedAt *time.Time `json:"landedAt,omitempty"`
+}
+
+// IsEmpty reports whether the touch carries no UTM parameters
+func (t *UTMTouch) IsEmpty() bool {
+	return t == nil || (t.Source == "" && t.Medium == "" && t.Campaign == "" && t.Content == "" && t.Term == "")
+}
+
+func (t *UTMTouch) clean() *UTMTouch {
+	if t.IsEmpty() {
+		return nil
+	}
+	return &UTMTouch{
+		Source:   cleanUTMValue(t.Source),
+		Medium:   cleanUTMValue(t.Medium),
+		Campaign: cleanUTMValue(t.Campaign),
+		Content:  cleanUTMValue(t.Content),
+		Term:     cleanUTMValue(t.Term),
+		LandedAt: t.LandedAt,
+	}
+}
+
+func cleanUTMValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > maxUTMValueLength {
+		value = value[:maxUTMValueLength]
+	}
+	return value
+}
+
+// OrderAttribution is the first and last marketing touch the storefront captured before
+// checkout. It is passed through to order events for campaign revenue attribution.
+type OrderAttribution struct {
+	FirstTouch *UTMTouch `json:"firstTouch,omitempty"`
+	LastTouch  *UTMTouch `json:"lastTouch,omitempty"`
+}
+
+// AttributionJSON encodes attribution for storage, or nil when there are no touches
+func AttributionJSON(attribution *OrderAttribution) JSONB {
+	if attribution == nil {
+		return nil
+	}
+	cleaned := OrderAttribution{
+		FirstTouch: attribution.FirstTouch.clean(),
+		LastTouch:  attribution.LastTouch.clean(),
+	}
+	if cleaned.FirstTouch == nil && cleaned.LastTouch == nil {
+		return nil
+	}
+	data, err := json.Marshal(cleaned)
+	if err != nil {
+		return nil
+	}
+	return JSONB(data)
+}
+
+// AttributionMetadata decodes the order's attribution for event metadata
+func (o *Order) AttributionMetadata() map[string]interface{} {
+	if len(o.Attribution) == 0 {
+		return nil
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(o.Attribution, &metadata); err != nil {
+		return nil
+	}
+	return metadata
+}
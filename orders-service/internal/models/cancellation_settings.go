@@ -45,6 +45,11 @@ type CancellationSettings struct {
 	// Customer-facing policy text (can contain HTML)
 	PolicyText string `json:"policyText" gorm:"type:text"`
 
+	// Order automation (0 disables each)
+	SelfCancelWindowMinutes int `json:"selfCancelWindowMinutes" gorm:"default:0"` // How long after placing an order customers may cancel it themselves
+	AutoConfirmDelayMinutes int `json:"autoConfirmDelayMinutes" gorm:"default:0"` // Delay after payment before fulfillment starts automatically
+	UnpaidOrderCancelHours  int `json:"unpaidOrderCancelHours" gorm:"default:0"`  // Unpaid orders older than this are cancelled automatically
+
 	// Audit fields
 	CreatedAt time.Time      `json:"createdAt"`
 	UpdatedAt time.Time      `json:"updatedAt"`
@@ -159,6 +164,9 @@ type CreateCancellationSettingsRequest struct {
 	CancellationReasons       []string           `json:"cancellationReasons,omitempty"`
 	RequireApprovalForPolicyChanges *bool        `json:"requireApprovalForPolicyChanges,omitempty"`
 	PolicyText                string             `json:"policyText,omitempty"`
+	SelfCancelWindowMinutes   *int               `json:"selfCancelWindowMinutes,omitempty" binding:"omitempty,min=0"`
+	AutoConfirmDelayMinutes   *int               `json:"autoConfirmDelayMinutes,omitempty" binding:"omitempty,min=0"`
+	UnpaidOrderCancelHours    *int               `json:"unpaidOrderCancelHours,omitempty" binding:"omitempty,min=0"`
 }
 
 // UpdateCancellationSettingsRequest is the request body for updating cancellation settings
@@ -175,6 +183,9 @@ type UpdateCancellationSettingsRequest struct {
 	CancellationReasons       []string           `json:"cancellationReasons,omitempty"`
 	RequireApprovalForPolicyChanges *bool        `json:"requireApprovalForPolicyChanges,omitempty"`
 	PolicyText                string             `json:"policyText,omitempty"`
+	SelfCancelWindowMinutes   *int               `json:"selfCancelWindowMinutes,omitempty" binding:"omitempty,min=0"`
+	AutoConfirmDelayMinutes   *int               `json:"autoConfirmDelayMinutes,omitempty" binding:"omitempty,min=0"`
+	UnpaidOrderCancelHours    *int               `json:"unpaidOrderCancelHours,omitempty" binding:"omitempty,min=0"`
 }
 
 // CancellationSettingsResponse is the API response wrapper
@@ -214,5 +225,28 @@ func DefaultCancellationSettings(tenantID, storefrontID string) *CancellationSet
 		},
 		RequireApprovalForPolicyChanges: false,
 		PolicyText: "",
+		SelfCancelWindowMinutes: 30,
+	}
+}
+
+// SelfCancelStatus tells a customer whether they can still cancel their order themselves
+type SelfCancelStatus struct {
+	Allowed          bool       `json:"allowed"`
+	CancellableUntil *time.Time `json:"cancellableUntil,omitempty"` // Unset when the store has no self-cancel window
+}
+
+// SelfCancelStatusFor reports whether the customer may cancel the order at the given time.
+// Customers can only cancel before fulfillment starts, and within the self-cancel window if one is set.
+func (s *CancellationSettings) SelfCancelStatusFor(order *Order, now time.Time) *SelfCancelStatus {
+	status := &SelfCancelStatus{
+		Allowed: s.Enabled && (order.Status == OrderStatusPlaced || order.Status == OrderStatusConfirmed),
+	}
+	if s.SelfCancelWindowMinutes > 0 {
+		until := order.CreatedAt.Add(time.Duration(s.SelfCancelWindowMinutes) * time.Minute)
+		status.CancellableUntil = &until
+		if !now.Before(until) {
+			status.Allowed = false
+		}
 	}
+	return status
 }
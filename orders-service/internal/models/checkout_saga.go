@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckoutSagaStatus is the step a checkout saga is on, or how it ended
+type CheckoutSagaStatus string
+
+const (
+	CheckoutSagaReservingStock  CheckoutSagaStatus = "RESERVING_STOCK"  // Order created, holding its stock in inventory-service
+	CheckoutSagaCreatingPayment CheckoutSagaStatus = "CREATING_PAYMENT" // Stock held, creating the payment intent
+	CheckoutSagaAwaitingPayment CheckoutSagaStatus = "AWAITING_PAYMENT" // Intent created, waiting for the customer to pay
+	CheckoutSagaCompleted       CheckoutSagaStatus = "COMPLETED"        // Paid and the held stock taken
+	CheckoutSagaCompensating    CheckoutSagaStatus = "COMPENSATING"     // Releasing the stock and cancelling the order
+	CheckoutSagaCompensated     CheckoutSagaStatus = "COMPENSATED"      // Stock released and order cancelled
+	CheckoutSagaFailed          CheckoutSagaStatus = "FAILED"           // Compensation kept failing; needs a person
+)
+
+// IsFinished reports whether the saga has nothing left to do
+func (s CheckoutSagaStatus) IsFinished() bool {
+	return s == CheckoutSagaCompleted || s == CheckoutSagaCompensated || s == CheckoutSagaFailed
+}
+
+// CheckoutPayment is how the customer chose to pay, kept on the saga so the payment intent can
+// be created again after a failed attempt
+type CheckoutPayment struct {
+	GatewayType   string `json:"gatewayType" binding:"required"`
+	PaymentMethod string `json:"paymentMethod,omitempty"`
+	ReturnURL     string `json:"returnUrl,omitempty"`
+	CancelURL     string `json:"cancelUrl,omitempty"`
+	UPIApp        string `json:"upiApp,omitempty"`
+	VPA           string `json:"vpa,omitempty"`
+}
+
+// CheckoutSaga tracks one checkout across orders-service, inventory-service and
+// payment-service: the order is created, its stock held, then a payment intent created. When a
+// step fails for good, the steps already done are undone. Failed calls are retried by the
+// checkout saga job from NextAttemptAt.
+type CheckoutSaga struct {
+	ID       uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID string             `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_checkout_sagas_tenant"`
+	OrderID  uuid.UUID          `json:"orderId" gorm:"type:uuid;not null;uniqueIndex:uniq_checkout_sagas_order"`
+	Status   CheckoutSagaStatus `json:"status" gorm:"type:varchar(30);not null;index:idx_checkout_sagas_due"`
+
+	Payment       JSONB      `json:"payment" gorm:"type:jsonb"`                 // CheckoutPayment
+	PaymentIntent JSONB      `json:"paymentIntent,omitempty" gorm:"type:jsonb"` // payment-service's response, passed to the storefront
+	HoldExpiresAt *time.Time `json:"holdExpiresAt,omitempty"`                   // Unpaid orders are cancelled and their stock released after this
+
+	Attempts      int        `json:"attempts" gorm:"default:0"` // Failed attempts at the current step
+	NextAttemptAt time.Time  `json:"nextAttemptAt" gorm:"not null;index:idx_checkout_sagas_due"`
+	LockedUntil   *time.Time `json:"-"`
+	LastError     string     `json:"lastError,omitempty" gorm:"type:text"`
+	FailureReason string     `json:"failureReason,omitempty" gorm:"type:text"` // Why the checkout was undone
+
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+func (CheckoutSaga) TableName() string {
+	return "checkout_sagas"
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItemFulfillmentStatus is how much of an order item has been fulfilled
+type ItemFulfillmentStatus string
+
+const (
+	ItemFulfillmentUnfulfilled        ItemFulfillmentStatus = "UNFULFILLED"
+	ItemFulfillmentPartiallyFulfilled ItemFulfillmentStatus = "PARTIALLY_FULFILLED"
+	ItemFulfillmentFulfilled          ItemFulfillmentStatus = "FULFILLED"
+)
+
+// ItemFulfillmentStatusFor returns the status of an item with the given quantity fulfilled
+func ItemFulfillmentStatusFor(fulfilled, quantity int) ItemFulfillmentStatus {
+	switch {
+	case fulfilled <= 0:
+		return ItemFulfillmentUnfulfilled
+	case fulfilled < quantity:
+		return ItemFulfillmentPartiallyFulfilled
+	default:
+		return ItemFulfillmentFulfilled
+	}
+}
+
+// UnfulfilledQuantity returns how many of the item haven't gone out in a fulfillment yet
+func (i *OrderItem) UnfulfilledQuantity() int {
+	if i.FulfilledQuantity >= i.Quantity {
+		return 0
+	}
+	return i.Quantity - i.FulfilledQuantity
+}
+
+// OrderFulfillment is one parcel of an order: the items in it, its carrier and tracking, and
+// how far it has got. An order shipped in several parcels has one per parcel.
+type OrderFulfillment struct {
+	ID             uuid.UUID              `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID       string                 `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_order_fulfillments_tenant"`
+	OrderID        uuid.UUID              `json:"orderId" gorm:"type:uuid;not null;uniqueIndex:uniq_order_fulfillments_order_number"`
+	Number         int                    `json:"number" gorm:"not null;uniqueIndex:uniq_order_fulfillments_order_number"` // 1 for the order's first parcel
+	Status         FulfillmentStatus      `json:"status" gorm:"type:varchar(30);not null"`
+	Carrier        string                 `json:"carrier,omitempty"`
+	TrackingNumber string                 `json:"trackingNumber,omitempty"`
+	TrackingURL    string                 `json:"trackingUrl,omitempty"`
+	ShipmentID     string                 `json:"shipmentId,omitempty"` // shipping-service shipment, when one was created for the parcel
+	LabelURL       string                 `json:"labelUrl,omitempty"`
+	Notes          string                 `json:"notes,omitempty" gorm:"type:text"`
+	Items          []OrderFulfillmentItem `json:"items" gorm:"foreignKey:FulfillmentID"`
+	CreatedBy      *uuid.UUID             `json:"createdBy,omitempty" gorm:"type:uuid"`
+	ShippedAt      *time.Time             `json:"shippedAt,omitempty"`
+	DeliveredAt    *time.Time             `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+}
+
+// TableName specifies the table name for OrderFulfillment
+func (OrderFulfillment) TableName() string {
+	return "order_fulfillments"
+}
+
+// OrderFulfillmentItem is the quantity of an order item packed in a fulfillment
+type OrderFulfillmentItem struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	FulfillmentID uuid.UUID `json:"fulfillmentId" gorm:"type:uuid;not null;index"`
+	OrderItemID   uuid.UUID `json:"orderItemId" gorm:"type:uuid;not null;index"`
+	ProductName   string    `json:"productName"`
+	SKU           string    `json:"sku"`
+	Quantity      int       `json:"quantity" gorm:"not null"`
+}
+
+// TableName specifies the table name for OrderFulfillmentItem
+func (OrderFulfillmentItem) TableName() string {
+	return "order_fulfillment_items"
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyRecord remembers a request sent with an Idempotency-Key header, and once it has
+// finished, the response it got. A retry with the same key gets that response back instead of
+// running the request again.
+type IdempotencyRecord struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID    string    `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:uniq_idempotency_records_key"`
+	Scope       string    `json:"scope" gorm:"type:varchar(255);not null;uniqueIndex:uniq_idempotency_records_key"` // Method and route the key was used on
+	Key         string    `json:"key" gorm:"type:varchar(255);not null;uniqueIndex:uniq_idempotency_records_key"`
+	RequestHash string    `json:"requestHash" gorm:"type:varchar(64);not null"` // SHA-256 of the request body
+
+	Completed   bool      `json:"completed" gorm:"default:false"`
+	StatusCode  int       `json:"statusCode"`
+	ContentType string    `json:"contentType" gorm:"type:varchar(255)"`
+	Body        []byte    `json:"body" gorm:"type:bytea"`
+	LockedUntil time.Time `json:"lockedUntil"` // An unfinished request past this is taken to have died, and the key is free again
+
+	ExpiresAt time.Time `json:"expiresAt" gorm:"not null;index:idx_idempotency_records_expires"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName specifies the table name for IdempotencyRecord
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_records"
+}
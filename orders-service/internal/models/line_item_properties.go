@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+)
+
+const (
+	// maxLineItemProperties bounds the properties kept on one order item
+	maxLineItemProperties = 20
+	// maxLineItemPropertyLength bounds each property name and value
+	maxLineItemPropertyLength = 1000
+)
+
+// LineItemProperties are the customizations a shopper chose for an order item, such as
+// engraving text, a gift message or add-ons. They are validated against the product's
+// rules in the cart, so checkout only bounds them.
+type LineItemProperties map[string]string
+
+// CleanLineItemProperties trims the properties, drops empty ones and truncates any
+// beyond the limits, or returns nil when none are left
+func CleanLineItemProperties(properties map[string]string) LineItemProperties {
+	cleaned := make(LineItemProperties, len(properties))
+	for name, value := range properties {
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if name == "" || value == "" || len(cleaned) >= maxLineItemProperties {
+			continue
+		}
+		cleaned[truncateProperty(name)] = truncateProperty(value)
+	}
+	if len(cleaned) == 0 {
+		return nil
+	}
+	return cleaned
+}
+
+func truncateProperty(value string) string {
+	if len(value) <= maxLineItemPropertyLength {
+		return value
+	}
+	return strings.ToValidUTF8(value[:maxLineItemPropertyLength], "")
+}
+
+// Value implements driver.Valuer for JSONB storage
+func (p LineItemProperties) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan implements sql.Scanner for JSONB retrieval
+func (p *LineItemProperties) Scan(value interface{}) error {
+	if value == nil {
+		*p = nil
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	}
+	return nil
+}
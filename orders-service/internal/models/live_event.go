@@ -0,0 +1,15 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// LiveEvent is a platform event relayed to admin dashboards over the live event stream
+type LiveEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	TenantID   string          `json:"tenantId"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Payload    json.RawMessage `json:"payload"`
+}
@@ -0,0 +1,189 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationChannel is how a customer is sent an order notification
+type NotificationChannel string
+
+const (
+	NotificationChannelEmail    NotificationChannel = "EMAIL"
+	NotificationChannelSMS      NotificationChannel = "SMS"
+	NotificationChannelWhatsApp NotificationChannel = "WHATSAPP"
+)
+
+// Customer notifications sent as an order moves through its lifecycle
+const (
+	NotificationEventOrderConfirmed      = "order.confirmed"
+	NotificationEventOrderShipped        = "order.shipped"
+	NotificationEventOrderDelivered      = "order.delivered"
+	NotificationEventOrderReadyForPickup = "order.ready_for_pickup"
+	NotificationEventOrderCancelled      = "order.cancelled"
+	NotificationEventOrderRefunded       = "order.refunded"
+	NotificationEventOrderUpdated        = "order.updated" // Staff edited the order's items
+)
+
+// NotificationEvents lists every customer notification in the order they happen
+var NotificationEvents = []string{
+	NotificationEventOrderConfirmed,
+	NotificationEventOrderUpdated,
+	NotificationEventOrderShipped,
+	NotificationEventOrderReadyForPickup,
+	NotificationEventOrderDelivered,
+	NotificationEventOrderCancelled,
+	NotificationEventOrderRefunded,
+}
+
+// NotificationRoutes maps a notification event to the channels it is sent on
+type NotificationRoutes map[string][]NotificationChannel
+
+// Value implements driver.Valuer for JSONB storage
+func (r NotificationRoutes) Value() (driver.Value, error) {
+	if r == nil {
+		return json.Marshal(map[string][]NotificationChannel{})
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements sql.Scanner for JSONB retrieval
+func (r *NotificationRoutes) Scan(value interface{}) error {
+	if value == nil {
+		*r = NotificationRoutes{}
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, r)
+	case string:
+		return json.Unmarshal([]byte(v), r)
+	}
+	return nil
+}
+
+// NotificationRoutingSettings is a tenant's choice of channels for each order notification and
+// whether rapid successive notifications to a customer are combined into one digest. Tenants
+// without settings get every notification by email straight away.
+type NotificationRoutingSettings struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID string    `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:idx_notification_routing_settings_tenant"`
+
+	// Events missing from Routes go by email; an event routed to no channels isn't sent
+	Routes NotificationRoutes `json:"routes" gorm:"type:jsonb;default:'{}'"`
+
+	// DigestEnabled holds each notification for DigestWindowMinutes. Everything queued for the
+	// same customer and channel in that time goes out as one digest.
+	DigestEnabled       bool `json:"digestEnabled" gorm:"default:false"`
+	DigestWindowMinutes int  `json:"digestWindowMinutes" gorm:"default:10"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedBy string    `json:"createdBy,omitempty" gorm:"type:varchar(255)"`
+	UpdatedBy string    `json:"updatedBy,omitempty" gorm:"type:varchar(255)"`
+}
+
+func (NotificationRoutingSettings) TableName() string {
+	return "notification_routing_settings"
+}
+
+// ChannelsFor returns the channels an event is sent on
+func (s *NotificationRoutingSettings) ChannelsFor(event string) []NotificationChannel {
+	if channels, ok := s.Routes[event]; ok {
+		return channels
+	}
+	return []NotificationChannel{NotificationChannelEmail}
+}
+
+// DigestWindow is how long notifications are held for a digest, or 0 when they're sent at once
+func (s *NotificationRoutingSettings) DigestWindow() time.Duration {
+	if !s.DigestEnabled {
+		return 0
+	}
+	return time.Duration(s.DigestWindowMinutes) * time.Minute
+}
+
+// Validate checks the routes only name known events and channels
+func (s *NotificationRoutingSettings) Validate() error {
+	known := make(map[string]bool, len(NotificationEvents))
+	for _, event := range NotificationEvents {
+		known[event] = true
+	}
+	for event, channels := range s.Routes {
+		if !known[event] {
+			return fmt.Errorf("unknown notification event %q", event)
+		}
+		seen := make(map[NotificationChannel]bool)
+		for _, channel := range channels {
+			switch channel {
+			case NotificationChannelEmail, NotificationChannelSMS, NotificationChannelWhatsApp:
+			default:
+				return fmt.Errorf("channel for %s must be EMAIL, SMS or WHATSAPP", event)
+			}
+			if seen[channel] {
+				return fmt.Errorf("channel %s is listed twice for %s", channel, event)
+			}
+			seen[channel] = true
+		}
+	}
+	if s.DigestEnabled && (s.DigestWindowMinutes < 1 || s.DigestWindowMinutes > 120) {
+		return fmt.Errorf("digestWindowMinutes must be between 1 and 120")
+	}
+	return nil
+}
+
+// UpdateNotificationRoutingRequest replaces a tenant's notification routing
+type UpdateNotificationRoutingRequest struct {
+	Routes              map[string][]NotificationChannel `json:"routes"`
+	DigestEnabled       bool                             `json:"digestEnabled"`
+	DigestWindowMinutes int                              `json:"digestWindowMinutes"` // Defaults to 10
+}
+
+// NotificationRoutingResponse is a tenant's notification routing with every event's channels filled in
+type NotificationRoutingResponse struct {
+	Settings   *NotificationRoutingSettings `json:"settings"`
+	Configured bool                         `json:"configured"` // False when the tenant uses the email-only defaults
+	Effective  NotificationRoutes           `json:"effective"`
+}
+
+// NotificationLogStatus is where a customer notification got to
+type NotificationLogStatus string
+
+const (
+	NotificationLogQueued  NotificationLogStatus = "QUEUED"  // Held for the customer's digest
+	NotificationLogSending NotificationLogStatus = "SENDING" // Claimed by the digest job
+	NotificationLogSent    NotificationLogStatus = "SENT"
+	NotificationLogFailed  NotificationLogStatus = "FAILED"
+	NotificationLogSkipped NotificationLogStatus = "SKIPPED" // No email or phone number for the channel
+)
+
+// OrderNotificationLog is one order notification on one channel: the digest queue entry while
+// it waits and, once sent, the send log support staff look at
+type OrderNotificationLog struct {
+	ID          uuid.UUID             `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID    string                `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_order_notification_logs_tenant"`
+	OrderID     uuid.UUID             `json:"orderId" gorm:"type:uuid;not null;index:idx_order_notification_logs_order"`
+	OrderNumber string                `json:"orderNumber" gorm:"type:varchar(50)"`
+	Event       string                `json:"event" gorm:"type:varchar(50);not null"`
+	Channel     NotificationChannel   `json:"channel" gorm:"type:varchar(20);not null"`
+	Recipient   string                `json:"recipient" gorm:"type:varchar(255);index:idx_order_notification_logs_recipient"` // Email address or phone number
+	Status      NotificationLogStatus `json:"status" gorm:"type:varchar(20);not null;index:idx_order_notification_logs_due"`
+
+	SendAfter   *time.Time `json:"sendAfter,omitempty" gorm:"index:idx_order_notification_logs_due"` // When a queued notification's digest goes out
+	LockedUntil *time.Time `json:"-"`
+	DigestID    *uuid.UUID `json:"digestId,omitempty" gorm:"type:uuid"` // Shared by notifications sent together in one digest
+	Payload     JSONB      `json:"-" gorm:"type:jsonb"`                 // The notification's content, kept until it's sent
+	Error       string     `json:"error,omitempty" gorm:"type:text"`
+	SentAt      *time.Time `json:"sentAt,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (OrderNotificationLog) TableName() string {
+	return "order_notification_logs"
+}
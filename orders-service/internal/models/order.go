@@ -95,6 +95,19 @@ const (
 	FulfillmentStatusDelivered      FulfillmentStatus = "DELIVERED"        // Successfully delivered
 	FulfillmentStatusFailedDelivery FulfillmentStatus = "FAILED_DELIVERY"  // Delivery attempt failed
 	FulfillmentStatusReturned       FulfillmentStatus = "RETURNED"         // Returned to warehouse
+	FulfillmentStatusReadyForPickup FulfillmentStatus = "READY_FOR_PICKUP" // Waiting at the pickup location
+	FulfillmentStatusPickedUp       FulfillmentStatus = "PICKED_UP"        // Collected by the customer
+
+	// Set from an order's fulfillments when some parcels are with the carrier and others aren't shipped yet
+	FulfillmentStatusPartiallyShipped FulfillmentStatus = "PARTIALLY_SHIPPED"
+)
+
+// FulfillmentType represents how the customer receives the order
+type FulfillmentType string
+
+const (
+	FulfillmentTypeShipping FulfillmentType = "SHIPPING" // Shipped to the customer's address
+	FulfillmentTypePickup   FulfillmentType = "PICKUP"   // Collected from a store (click-and-collect)
 )
 
 // Order represents the main order entity
@@ -110,6 +123,7 @@ type Order struct {
 	Status            OrderStatus       `json:"status" gorm:"type:varchar(20);not null;default:'PLACED';index:idx_orders_tenant_status"`
 	PaymentStatus     PaymentStatus     `json:"paymentStatus" gorm:"type:varchar(30);not null;default:'PENDING'"`
 	FulfillmentStatus FulfillmentStatus `json:"fulfillmentStatus" gorm:"type:varchar(30);not null;default:'UNFULFILLED'"`
+	FulfillmentType   FulfillmentType   `json:"fulfillmentType" gorm:"type:varchar(20);not null;default:'SHIPPING'"`
 	Currency          string            `json:"currency" gorm:"type:varchar(3);not null;default:'USD'"`
 	Subtotal          float64           `json:"subtotal" gorm:"type:decimal(10,2);not null"`
 	TaxAmount         float64           `json:"taxAmount" gorm:"type:decimal(10,2);default:0"`
@@ -120,8 +134,10 @@ type Order struct {
 	CreatedAt         time.Time         `json:"createdAt" gorm:"index:idx_orders_tenant_created,sort:desc"`
 	UpdatedAt         time.Time         `json:"updatedAt"`
 	DeletedAt         gorm.DeletedAt    `json:"-" gorm:"index"`
+	Version           int               `json:"version" gorm:"not null;default:1"` // Optimistic locking, bumped on every update
 
-	// Order splitting fields
+	// Order splitting fields. A VENDOR split keeps the parent intact for the customer and
+	// gives each vendor a child order carrying its own fulfillment, shipping and payout totals.
 	ParentOrderID     *uuid.UUID        `json:"parentOrderId,omitempty" gorm:"type:uuid;index"`
 	IsSplit           bool              `json:"isSplit" gorm:"default:false"`
 	SplitReason       string            `json:"splitReason,omitempty" gorm:"type:varchar(50)"`
@@ -148,6 +164,16 @@ type Order struct {
 
 	// Storefront host for building email URLs (custom domain or default subdomain)
 	StorefrontHost string `json:"storefrontHost,omitempty" gorm:"type:varchar(255)"`
+	// Storefront the order was placed on, for per-storefront order number prefixes
+	StorefrontID string `json:"storefrontId,omitempty" gorm:"type:varchar(255)"`
+
+	// Marketing attribution (OrderAttribution: first/last UTM touch captured by the storefront)
+	Attribution JSONB `json:"attribution,omitempty" gorm:"type:jsonb"`
+
+	// Set on historical orders brought in by an order import. Imported orders skip payment,
+	// fulfillment and automation triggers.
+	ImportJobID  *uuid.UUID `json:"importJobId,omitempty" gorm:"type:uuid;index"`
+	ImportSource string     `json:"importSource,omitempty" gorm:"type:varchar(50)"` // Previous platform or "offline"
 
 	// Receipt/Invoice tracking
 	ReceiptNumber      string     `json:"receiptNumber,omitempty" gorm:"type:varchar(50);index:idx_orders_receipt_number"`
@@ -160,9 +186,23 @@ type Order struct {
 	Items     []OrderItem     `json:"items" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
 	Customer  *OrderCustomer  `json:"customer" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
 	Shipping  *OrderShipping  `json:"shipping" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	Pickup    *OrderPickup    `json:"pickup,omitempty" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
 	Payment   *OrderPayment   `json:"payment" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
 	Timeline  []OrderTimeline `json:"timeline" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
 	Discounts []OrderDiscount `json:"discounts" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+
+	// Customer self-cancel eligibility, only populated on storefront responses
+	SelfCancel *SelfCancelStatus `json:"selfCancel,omitempty" gorm:"-"`
+}
+
+// IsPickup reports whether the order is collected from a pickup location rather than shipped
+func (o *Order) IsPickup() bool {
+	return o.FulfillmentType == FulfillmentTypePickup
+}
+
+// IsImported reports whether the order was brought in by an order import rather than placed
+func (o *Order) IsImported() bool {
+	return o.ImportJobID != nil
 }
 
 // OrderItem represents an item in an order
@@ -177,6 +217,15 @@ type OrderItem struct {
 	Quantity    int       `json:"quantity" gorm:"not null"`
 	UnitPrice   float64   `json:"unitPrice" gorm:"type:decimal(10,2);not null"`
 	TotalPrice  float64   `json:"totalPrice" gorm:"type:decimal(10,2);not null"`
+	// Product cost per unit when ordered, from products-service. Only shown in margin reports.
+	UnitCost *float64 `json:"-" gorm:"type:decimal(12,4)"`
+
+	// Customizations for fulfillment, e.g. engraving text or a gift message
+	Properties LineItemProperties `json:"properties,omitempty" gorm:"type:jsonb"`
+
+	// How much of the item has gone out in the order's fulfillments
+	FulfilledQuantity int                   `json:"fulfilledQuantity" gorm:"default:0"`
+	FulfillmentStatus ItemFulfillmentStatus `json:"fulfillmentStatus" gorm:"type:varchar(30);default:'UNFULFILLED'"`
 
 	// Tax fields
 	TaxAmount   float64 `json:"taxAmount" gorm:"type:decimal(10,2);default:0"`
@@ -237,11 +286,33 @@ type OrderShipping struct {
 	CountryCode string `json:"countryCode"`            // ISO 3166-1 alpha-2 (IN, US, GB, etc.)
 
 	EstimatedDelivery *time.Time `json:"estimatedDelivery"`
+	ShippedAt         *time.Time `json:"shippedAt,omitempty"` // When the order was first dispatched
 	ActualDelivery    *time.Time `json:"actualDelivery"`
 	CreatedAt         time.Time  `json:"createdAt"`
 	UpdatedAt         time.Time  `json:"updatedAt"`
 }
 
+// OrderPickup represents the pickup location and collection progress of a click-and-collect order.
+// The location is an inventory-service warehouse; its details are copied so the order keeps
+// them if the location later changes.
+type OrderPickup struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OrderID      uuid.UUID  `json:"orderId" gorm:"type:uuid;not null;unique"`
+	LocationID   uuid.UUID  `json:"locationId" gorm:"type:uuid;not null;index"`
+	LocationName string     `json:"locationName" gorm:"not null"`
+	Street       string     `json:"street" gorm:"not null"`
+	City         string     `json:"city" gorm:"not null"`
+	State        string     `json:"state"`
+	PostalCode   string     `json:"postalCode"`
+	Country      string     `json:"country" gorm:"not null"`
+	Phone        string     `json:"phone,omitempty"`
+	Instructions string     `json:"instructions,omitempty" gorm:"type:text"`
+	ReadyAt      *time.Time `json:"readyAt,omitempty"`
+	PickedUpAt   *time.Time `json:"pickedUpAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
 // OrderPayment represents payment information for an order
 type OrderPayment struct {
 	ID            uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
@@ -267,13 +338,17 @@ type OrderTimeline struct {
 	CreatedAt   time.Time `json:"createdAt"`
 }
 
+// DiscountTypeGiftCard marks an order discount as a gift card redeemed at checkout; the
+// card's code is kept in CouponCode so returns can credit it back
+const DiscountTypeGiftCard = "gift_card"
+
 // OrderDiscount represents discounts applied to an order
 type OrderDiscount struct {
 	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	OrderID      uuid.UUID  `json:"orderId" gorm:"type:uuid;not null"`
 	CouponID     *uuid.UUID `json:"couponId" gorm:"type:uuid"`
 	CouponCode   string     `json:"couponCode"`
-	DiscountType string     `json:"discountType" gorm:"not null"` // percentage, fixed
+	DiscountType string     `json:"discountType" gorm:"not null"` // percentage, fixed, gift_card
 	Amount       float64    `json:"amount" gorm:"type:decimal(10,2);not null"`
 	Description  string     `json:"description"`
 	CreatedAt    time.Time  `json:"createdAt"`
@@ -305,6 +380,16 @@ func generateOrderNumber() string {
 	return fmt.Sprintf("ORD-%d", timestamp)
 }
 
+// VersionConflictError reports that an order was changed by someone else after
+// the caller read it; CurrentVersion is what is stored now
+type VersionConflictError struct {
+	CurrentVersion int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("order version conflict: current version is %d", e.CurrentVersion)
+}
+
 // SplitType represents the reason for order splitting
 type SplitType string
 
@@ -313,6 +398,7 @@ const (
 	SplitTypeBackorder          SplitType = "BACKORDER"           // Out of stock items
 	SplitTypeMultiWarehouse     SplitType = "MULTI_WAREHOUSE"     // Items from different warehouses
 	SplitTypeManual             SplitType = "MANUAL"              // Manual split by admin
+	SplitTypeVendor             SplitType = "VENDOR"              // Marketplace checkout split into one order per vendor
 )
 
 // OrderSplit represents a split operation record
@@ -328,6 +414,16 @@ type OrderSplit struct {
 	CreatedAt       time.Time  `json:"createdAt"`
 }
 
+// IsVendorSplitParent reports whether the order is a marketplace checkout split into vendor orders
+func (o *Order) IsVendorSplitParent() bool {
+	return o.IsSplit && o.ParentOrderID == nil && o.SplitReason == string(SplitTypeVendor)
+}
+
+// IsVendorSplitChild reports whether the order is one vendor's share of a marketplace checkout
+func (o *Order) IsVendorSplitChild() bool {
+	return o.ParentOrderID != nil && o.SplitReason == string(SplitTypeVendor)
+}
+
 // SplitOrderRequest represents a request to split an order
 type SplitOrderRequest struct {
 	ItemIDs   []uuid.UUID `json:"itemIds" binding:"required"`   // Items to move to new order
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// OrderDocumentType is a document that can be included in an order's document pack
+type OrderDocumentType string
+
+const (
+	OrderDocumentPackingSlip OrderDocumentType = "packing_slip"
+	OrderDocumentInvoice     OrderDocumentType = "invoice"
+	OrderDocumentLabel       OrderDocumentType = "label" // One per shipment, fetched from shipping-service
+)
+
+// DefaultOrderDocumentTypes is the pack returned when no types are requested
+var DefaultOrderDocumentTypes = []OrderDocumentType{OrderDocumentPackingSlip, OrderDocumentInvoice, OrderDocumentLabel}
+
+// OrderDocumentPackFormat is how the documents in a pack are combined
+type OrderDocumentPackFormat string
+
+const (
+	OrderDocumentPackPDF OrderDocumentPackFormat = "pdf" // One merged PDF
+	OrderDocumentPackZIP OrderDocumentPackFormat = "zip" // One file per document
+)
+
+// OrderDocumentPackRequest selects the documents to collect for an order
+type OrderDocumentPackRequest struct {
+	Types  []OrderDocumentType     `json:"types"`
+	Format OrderDocumentPackFormat `json:"format"`
+	Locale string                  `json:"locale"`
+}
+
+// OrderDocumentFile is one document in a pack
+type OrderDocumentFile struct {
+	Type     OrderDocumentType `json:"type"`
+	Filename string            `json:"filename"`
+	Size     int64             `json:"size"`
+	Data     []byte            `json:"-"`
+}
+
+// OrderDocumentPack is the combined download for an order's documents
+type OrderDocumentPack struct {
+	OrderNumber string                  `json:"orderNumber"`
+	Format      OrderDocumentPackFormat `json:"format"`
+	Filename    string                  `json:"filename"`
+	ContentType string                  `json:"contentType"`
+	Documents   []OrderDocumentFile     `json:"documents"`
+	Data        []byte                  `json:"-"`
+}
+
+// OrderDocumentPackURLResponse is returned when a pack is stored in document-service
+// instead of being streamed
+type OrderDocumentPackURLResponse struct {
+	OrderNumber string              `json:"orderNumber"`
+	Filename    string              `json:"filename"`
+	DocumentID  string              `json:"documentId,omitempty"`
+	DownloadURL string              `json:"downloadUrl"`
+	ExpiresAt   time.Time           `json:"expiresAt"`
+	Documents   []OrderDocumentFile `json:"documents"`
+}
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderImportFormat is the file format of an order import
+type OrderImportFormat string
+
+const (
+	OrderImportCSV  OrderImportFormat = "csv"
+	OrderImportXLSX OrderImportFormat = "xlsx"
+)
+
+// OrderImportStatus is the stage of an order import job
+type OrderImportStatus string
+
+const (
+	OrderImportPending    OrderImportStatus = "PENDING"
+	OrderImportProcessing OrderImportStatus = "PROCESSING"
+	OrderImportCompleted  OrderImportStatus = "COMPLETED"
+	OrderImportFailed     OrderImportStatus = "FAILED"
+)
+
+// Order import limits
+const (
+	MaxOrderImportFileBytes = 20 << 20 // 20MB
+	MaxOrderImportRows      = 200000
+	OrderImportChunkSize    = 200 // Rows per chunk; an order's rows are never split across chunks
+	OrderImportStaleAfter   = 5 * time.Minute
+)
+
+// OrderImportJob tracks an asynchronous import of historical orders from a previous platform or
+// offline sales. Imported orders are written with their final statuses and never trigger
+// payments, inventory, notifications, shipments or order automation. The uploaded file is kept
+// with the job until it completes, so an interrupted import resumes from NextRow.
+type OrderImportJob struct {
+	ID            uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID      string            `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_order_import_jobs_tenant"`
+	Status        OrderImportStatus `json:"status" gorm:"type:varchar(20);not null;default:'PENDING';index"`
+	FileName      string            `json:"fileName" gorm:"type:varchar(255);not null"`
+	Format        OrderImportFormat `json:"format" gorm:"type:varchar(10);not null"`
+	FileData      []byte            `json:"-" gorm:"type:bytea"`
+	ColumnMapping JSONB             `json:"columnMapping,omitempty" gorm:"type:jsonb"` // Source header to import column
+
+	// Applied to every imported order
+	Source       string `json:"source" gorm:"type:varchar(50);not null"`                // Previous platform, e.g. "shopify", or "offline"
+	StorefrontID string `json:"storefrontId,omitempty" gorm:"type:varchar(255)"`        // Storefront the orders are attributed to
+	Currency     string `json:"currency" gorm:"type:varchar(3);not null;default:'USD'"` // For rows without a currency
+	// CreateCustomers finds or creates a customers-service customer for each order email, so
+	// imported orders show in the customer's history. Orders without an email stay guest orders.
+	CreateCustomers bool `json:"createCustomers" gorm:"default:false"`
+
+	// Progress
+	TotalRows    int `json:"totalRows"`
+	NextRow      int `json:"nextRow"`      // Data rows already committed; processing resumes here
+	CreatedCount int `json:"createdCount"` // Orders imported
+	SkippedCount int `json:"skippedCount"` // Orders whose number already exists
+	FailedCount  int `json:"failedCount"`  // Orders rejected by validation or failed to save
+
+	LastError   *string    `json:"lastError,omitempty" gorm:"type:text"`
+	Attempts    int        `json:"attempts"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	HeartbeatAt *time.Time `json:"heartbeatAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+
+	CreatedBy *string   `json:"createdBy,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName returns the table name for GORM
+func (OrderImportJob) TableName() string {
+	return "order_import_jobs"
+}
+
+// PercentComplete returns the job progress as a percentage
+func (j *OrderImportJob) PercentComplete() int {
+	if j.TotalRows == 0 {
+		if j.Status == OrderImportCompleted {
+			return 100
+		}
+		return 0
+	}
+	return j.NextRow * 100 / j.TotalRows
+}
+
+// OrderImportError is a problem with one row of an order import. Orders skipped as duplicates
+// and orders imported without their customer link are recorded too.
+type OrderImportError struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	JobID       uuid.UUID `json:"jobId" gorm:"type:uuid;not null;index"`
+	TenantID    string    `json:"-" gorm:"type:varchar(255);not null"`
+	Row         int       `json:"row" gorm:"column:row_number;not null"`
+	OrderNumber string    `json:"orderNumber,omitempty" gorm:"type:varchar(100)"`
+	Column      string    `json:"column,omitempty" gorm:"column:column_name;type:varchar(100)"`
+	Code        string    `json:"code" gorm:"type:varchar(50);not null"`
+	Message     string    `json:"message" gorm:"type:text;not null"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName returns the table name for GORM
+func (OrderImportError) TableName() string {
+	return "order_import_errors"
+}
+
+// OrderImportColumn describes one column of the order import template
+type OrderImportColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // string, email, date, number, integer
+	Required    bool   `json:"required"`
+	Description string `json:"description"`
+}
+
+// OrderImportColumns lists the columns an order import understands. Each row is one line item;
+// an order's rows must be consecutive and its order-level values are read from its first row.
+var OrderImportColumns = []OrderImportColumn{
+	{Name: "orderNumber", Type: "string", Required: true, Description: "Groups line items into orders; kept as the order number"},
+	{Name: "orderDate", Type: "date", Required: true, Description: "YYYY-MM-DD or RFC 3339 timestamp"},
+	{Name: "email", Type: "email", Description: "Links the order to a customer; blank for walk-in sales"},
+	{Name: "firstName", Type: "string"},
+	{Name: "lastName", Type: "string"},
+	{Name: "phone", Type: "string"},
+	{Name: "currency", Type: "string", Description: "ISO 4217 code; defaults to the import's currency"},
+	{Name: "status", Type: "string", Description: "PLACED, CONFIRMED, PROCESSING, SHIPPED, DELIVERED, COMPLETED or CANCELLED; defaults to COMPLETED"},
+	{Name: "paymentStatus", Type: "string", Description: "PENDING, PAID, FAILED, PARTIALLY_REFUNDED or REFUNDED; defaults to PAID"},
+	{Name: "fulfillmentStatus", Type: "string", Description: "Defaults from status, e.g. DELIVERED for completed orders"},
+	{Name: "paymentMethod", Type: "string", Description: "Defaults to the import source"},
+	{Name: "transactionId", Type: "string"},
+	{Name: "sku", Type: "string", Required: true},
+	{Name: "productName", Type: "string", Required: true},
+	{Name: "productId", Type: "string", Description: "Catalog product UUID; blank links the item to a stub ID derived from its SKU"},
+	{Name: "quantity", Type: "integer", Required: true},
+	{Name: "unitPrice", Type: "number", Required: true},
+	{Name: "itemTax", Type: "number", Description: "Tax on the line item"},
+	{Name: "taxAmount", Type: "number", Description: "Order tax; defaults to the sum of itemTax"},
+	{Name: "shippingCost", Type: "number"},
+	{Name: "discountAmount", Type: "number"},
+	{Name: "total", Type: "number", Description: "Order total; defaults to subtotal + tax + shipping - discount"},
+	{Name: "notes", Type: "string"},
+	{Name: "shippingMethod", Type: "string"},
+	{Name: "carrier", Type: "string"},
+	{Name: "trackingNumber", Type: "string"},
+	{Name: "street", Type: "string", Description: "Adds a shipping address to the order"},
+	{Name: "city", Type: "string"},
+	{Name: "state", Type: "string"},
+	{Name: "postalCode", Type: "string"},
+	{Name: "country", Type: "string"},
+	{Name: "countryCode", Type: "string", Description: "ISO 3166-1 alpha-2 code"},
+}
+
+// OrderImportPreview shows how an uploaded file would be imported, without importing it
+type OrderImportPreview struct {
+	SourceHeaders    []string            `json:"sourceHeaders"`
+	SuggestedMapping map[string]string   `json:"suggestedMapping"`
+	AppliedMapping   map[string]string   `json:"appliedMapping"`
+	UnmappedHeaders  []string            `json:"unmappedHeaders,omitempty"`
+	MissingRequired  []string            `json:"missingRequired,omitempty"`
+	TotalRows        int                 `json:"totalRows"`
+	OrderCount       int                 `json:"orderCount"` // Orders in the previewed rows
+	ValidOrderCount  int                 `json:"validOrderCount"`
+	Rows             []map[string]string `json:"rows"`
+	Errors           []OrderImportError  `json:"errors"`
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"orders-service/internal/encryption"
+)
+
+// IntegrationTarget is the external system an order integration pushes to. It only labels the
+// integration; the payload format comes from its template.
+type IntegrationTarget string
+
+const (
+	IntegrationTargetSAPB1    IntegrationTarget = "sap_b1"
+	IntegrationTargetTally    IntegrationTarget = "tally"
+	IntegrationTargetNetSuite IntegrationTarget = "netsuite"
+	IntegrationTargetCustom   IntegrationTarget = "custom"
+)
+
+// IntegrationTemplateEngine is the language of an integration's payload template
+type IntegrationTemplateEngine string
+
+const (
+	// IntegrationTemplateGo is a Go text/template executed against the order
+	IntegrationTemplateGo IntegrationTemplateEngine = "go_template"
+)
+
+// IntegrationDeliveryStatus is where a delivery is in the outbox
+type IntegrationDeliveryStatus string
+
+const (
+	IntegrationDeliveryPending    IntegrationDeliveryStatus = "PENDING"    // Waiting for its next attempt
+	IntegrationDeliveryDelivering IntegrationDeliveryStatus = "DELIVERING" // Claimed by a worker
+	IntegrationDeliveryDelivered  IntegrationDeliveryStatus = "DELIVERED"
+	IntegrationDeliveryFailed     IntegrationDeliveryStatus = "FAILED" // Out of attempts; can be replayed
+)
+
+// OrderIntegration pushes a tenant's order timeline events to an external system such as an
+// ERP. Each event is rendered with the integration's template and sent to its endpoint.
+type OrderIntegration struct {
+	ID          uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID    string            `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_order_integrations_tenant"`
+	Name        string            `json:"name" gorm:"type:varchar(255);not null"`
+	Target      IntegrationTarget `json:"target" gorm:"type:varchar(30);not null;default:'custom'"`
+	EndpointURL string            `json:"endpointUrl" gorm:"type:varchar(1000);not null"`
+	HTTPMethod  string            `json:"httpMethod" gorm:"type:varchar(10);not null;default:'POST'"`
+	ContentType string            `json:"contentType" gorm:"type:varchar(100);not null;default:'application/json'"`
+
+	// Events are the timeline events sent (e.g. ORDER_CREATED, STATUS_CHANGED); empty sends all
+	Events pq.StringArray `json:"events" gorm:"type:text[]"`
+
+	TemplateEngine IntegrationTemplateEngine `json:"templateEngine" gorm:"type:varchar(20);not null;default:'go_template'"`
+	Template       string                    `json:"template" gorm:"type:text;not null"`
+
+	// Optional auth header (e.g. Authorization: Bearer ...) and HMAC signing secret, stored encrypted
+	AuthHeaderName  string                     `json:"authHeaderName,omitempty" gorm:"type:varchar(100)"`
+	AuthHeaderValue encryption.EncryptedString `json:"-" gorm:"type:text"`
+	SigningSecret   encryption.EncryptedString `json:"-" gorm:"type:text"`
+	HasAuthHeader   bool                       `json:"hasAuthHeader" gorm:"-"`
+	HasSigningKey   bool                       `json:"hasSigningSecret" gorm:"-"`
+
+	MaxAttempts    int  `json:"maxAttempts" gorm:"default:10"`
+	TimeoutSeconds int  `json:"timeoutSeconds" gorm:"default:15"`
+	IsActive       bool `json:"isActive" gorm:"default:true"`
+
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	CreatedBy string         `json:"createdBy,omitempty" gorm:"type:varchar(255)"`
+	UpdatedBy string         `json:"updatedBy,omitempty" gorm:"type:varchar(255)"`
+}
+
+func (OrderIntegration) TableName() string {
+	return "order_integrations"
+}
+
+// AfterFind reports which secrets are set without exposing them
+func (i *OrderIntegration) AfterFind(tx *gorm.DB) error {
+	i.HasAuthHeader = i.AuthHeaderValue != ""
+	i.HasSigningKey = i.SigningSecret != ""
+	return nil
+}
+
+// OrderIntegrationDelivery is one timeline event for one integration. It is the outbox entry
+// the delivery job works from and, once sent, the delivery log.
+type OrderIntegrationDelivery struct {
+	ID            uuid.UUID                 `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID      string                    `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_order_integration_deliveries_tenant"`
+	IntegrationID uuid.UUID                 `json:"integrationId" gorm:"type:uuid;not null;index:idx_order_integration_deliveries_integration"`
+	OrderID       uuid.UUID                 `json:"orderId" gorm:"type:uuid;not null;index:idx_order_integration_deliveries_order"`
+	TimelineID    *uuid.UUID                `json:"timelineId,omitempty" gorm:"type:uuid"`
+	Event         string                    `json:"event" gorm:"type:varchar(100);not null"`
+	Status        IntegrationDeliveryStatus `json:"status" gorm:"type:varchar(20);not null;default:'PENDING';index:idx_order_integration_deliveries_due"`
+	Attempts      int                       `json:"attempts" gorm:"default:0"`
+	NextAttemptAt time.Time                 `json:"nextAttemptAt" gorm:"not null;index:idx_order_integration_deliveries_due"`
+	LockedUntil   *time.Time                `json:"-"`
+	ReplayOf      *uuid.UUID                `json:"replayOf,omitempty" gorm:"type:uuid"`
+
+	// Last attempt
+	RequestBody    string     `json:"requestBody,omitempty" gorm:"type:text"`
+	ResponseStatus int        `json:"responseStatus,omitempty"`
+	ResponseBody   string     `json:"responseBody,omitempty" gorm:"type:text"` // Truncated
+	Error          string     `json:"error,omitempty" gorm:"type:text"`
+	LastAttemptAt  *time.Time `json:"lastAttemptAt,omitempty"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"index:idx_order_integration_deliveries_integration"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (OrderIntegrationDelivery) TableName() string {
+	return "order_integration_deliveries"
+}
+
+// AfterCreate adds an outbox delivery for every active integration of the order's tenant that
+// takes the event, and for every webhook subscription taking the lifecycle event it stands for.
+// It runs in the transaction that wrote the timeline entry, so an event is delivered if and only
+// if it was recorded.
+func (t *OrderTimeline) AfterCreate(tx *gorm.DB) error {
+	if err := enqueueOrderWebhooks(tx, t); err != nil {
+		return err
+	}
+	return tx.Exec(`
+		INSERT INTO order_integration_deliveries
+			(id, tenant_id, integration_id, order_id, timeline_id, event, status, attempts, next_attempt_at, created_at, updated_at)
+		SELECT gen_random_uuid(), i.tenant_id, i.id, o.id, ?, ?, ?, 0, NOW(), NOW(), NOW()
+		FROM order_integrations i
+		JOIN orders o ON o.tenant_id = i.tenant_id
+		WHERE o.id = ?
+		  AND i.is_active
+		  AND i.deleted_at IS NULL
+		  AND (i.events IS NULL OR cardinality(i.events) = 0 OR ? = ANY(i.events))`,
+		t.ID, t.Event, IntegrationDeliveryPending, t.OrderID, t.Event,
+	).Error
+}
+
+// OrderIntegrationRequest creates or replaces an order integration. Secrets left empty on an
+// update keep their current value.
+type OrderIntegrationRequest struct {
+	Name            string                    `json:"name" binding:"required,max=255"`
+	Target          IntegrationTarget         `json:"target" binding:"omitempty,oneof=sap_b1 tally netsuite custom"`
+	EndpointURL     string                    `json:"endpointUrl" binding:"required,url,max=1000"`
+	HTTPMethod      string                    `json:"httpMethod" binding:"omitempty,oneof=POST PUT PATCH"`
+	ContentType     string                    `json:"contentType" binding:"max=100"`
+	Events          []string                  `json:"events" binding:"max=50"`
+	TemplateEngine  IntegrationTemplateEngine `json:"templateEngine" binding:"omitempty,oneof=go_template"`
+	Template        string                    `json:"template" binding:"required"`
+	AuthHeaderName  string                    `json:"authHeaderName" binding:"max=100"`
+	AuthHeaderValue string                    `json:"authHeaderValue"`
+	SigningSecret   string                    `json:"signingSecret"`
+	MaxAttempts     *int                      `json:"maxAttempts" binding:"omitempty,min=1,max=25"`
+	TimeoutSeconds  *int                      `json:"timeoutSeconds" binding:"omitempty,min=1,max=60"`
+	IsActive        *bool                     `json:"isActive"`
+}
+
+// IntegrationPreviewRequest renders an integration's template for an order without sending it
+type IntegrationPreviewRequest struct {
+	OrderID uuid.UUID `json:"orderId" binding:"required"`
+	Event   string    `json:"event"`
+}
+
+// IntegrationPreview is a rendered payload
+type IntegrationPreview struct {
+	ContentType string `json:"contentType"`
+	Body        string `json:"body"`
+}
+
+// IntegrationDeliveryFilter narrows an integration's delivery log
+type IntegrationDeliveryFilter struct {
+	Status  IntegrationDeliveryStatus
+	OrderID *uuid.UUID
+	Page    int
+	Limit   int
+}
@@ -0,0 +1,238 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderNumberDateFormat is the date component placed between the prefix and the sequence
+type OrderNumberDateFormat string
+
+const (
+	OrderNumberDateNone     OrderNumberDateFormat = ""
+	OrderNumberDateYear     OrderNumberDateFormat = "YYYY"
+	OrderNumberDateShortYr  OrderNumberDateFormat = "YY"
+	OrderNumberDateMonth    OrderNumberDateFormat = "YYYYMM"
+	OrderNumberDateShortMon OrderNumberDateFormat = "YYMM"
+	OrderNumberDateDay      OrderNumberDateFormat = "YYYYMMDD"
+)
+
+// OrderNumberReset is how often the sequence starts again from StartSequence
+type OrderNumberReset string
+
+const (
+	OrderNumberResetNever   OrderNumberReset = "never"
+	OrderNumberResetYearly  OrderNumberReset = "yearly"
+	OrderNumberResetMonthly OrderNumberReset = "monthly"
+	OrderNumberResetDaily   OrderNumberReset = "daily"
+)
+
+var orderNumberPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9\-_/#]{0,20}$`)
+
+// OrderNumberSettings is a tenant's order number format: prefix, optional date component and a
+// zero-padded sequence, e.g. "INV-2026-000123". Tenants without settings keep the default
+// ORD-<timestamp> numbers.
+type OrderNumberSettings struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID string    `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:idx_order_number_settings_tenant"`
+
+	Prefix             string                `json:"prefix" gorm:"type:varchar(20);default:'ORD-'"`
+	StorefrontPrefixes StringMap             `json:"storefrontPrefixes" gorm:"type:jsonb;default:'{}'"` // Storefront ID -> prefix used instead of Prefix
+	DateFormat         OrderNumberDateFormat `json:"dateFormat" gorm:"type:varchar(10);default:''"`
+	Separator          string                `json:"separator" gorm:"type:varchar(1);default:'-'"` // Between the date component and the sequence
+	SequencePadding    int                   `json:"sequencePadding" gorm:"default:6"`
+	StartSequence      int64                 `json:"startSequence" gorm:"default:1"`
+	ResetPeriod        OrderNumberReset      `json:"resetPeriod" gorm:"type:varchar(10);default:'never'"`
+
+	// GapFree allocates the sequence in the order's own transaction so a failed order never
+	// burns a number. Needed where invoices must be numbered without gaps; it serialises order
+	// creation per prefix.
+	GapFree bool `json:"gapFree" gorm:"default:false"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedBy string    `json:"createdBy,omitempty" gorm:"type:varchar(255)"`
+	UpdatedBy string    `json:"updatedBy,omitempty" gorm:"type:varchar(255)"`
+}
+
+func (OrderNumberSettings) TableName() string {
+	return "order_number_settings"
+}
+
+// OrderNumberSequence is the last sequence number issued for a tenant's prefix and reset period
+type OrderNumberSequence struct {
+	TenantID  string `gorm:"type:varchar(255);primaryKey"`
+	Scope     string `gorm:"type:varchar(100);primaryKey"` // Prefix and reset period, e.g. "INV-|2026"
+	LastValue int64  `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+}
+
+func (OrderNumberSequence) TableName() string {
+	return "order_number_sequences"
+}
+
+// PrefixFor returns the prefix for orders placed on a storefront
+func (s *OrderNumberSettings) PrefixFor(storefrontID string) string {
+	if prefix, ok := s.StorefrontPrefixes[storefrontID]; ok && storefrontID != "" {
+		return prefix
+	}
+	return s.Prefix
+}
+
+// Literal returns the fixed part of an order number before the sequence, e.g. "INV-2026-"
+func (s *OrderNumberSettings) Literal(prefix string, at time.Time) string {
+	at = at.UTC()
+	var date string
+	switch s.DateFormat {
+	case OrderNumberDateYear:
+		date = at.Format("2006")
+	case OrderNumberDateShortYr:
+		date = at.Format("06")
+	case OrderNumberDateMonth:
+		date = at.Format("200601")
+	case OrderNumberDateShortMon:
+		date = at.Format("0601")
+	case OrderNumberDateDay:
+		date = at.Format("20060102")
+	}
+	if date == "" {
+		return prefix
+	}
+	return prefix + date + s.Separator
+}
+
+// SequenceScope identifies the counter an order number is drawn from: numbers share a sequence
+// while their prefix and reset period match
+func (s *OrderNumberSettings) SequenceScope(prefix string, at time.Time) string {
+	at = at.UTC()
+	switch s.ResetPeriod {
+	case OrderNumberResetYearly:
+		return prefix + "|" + at.Format("2006")
+	case OrderNumberResetMonthly:
+		return prefix + "|" + at.Format("2006-01")
+	case OrderNumberResetDaily:
+		return prefix + "|" + at.Format("2006-01-02")
+	default:
+		return prefix + "|"
+	}
+}
+
+// Format renders an order number from its literal part and sequence
+func (s *OrderNumberSettings) Format(literal string, sequence int64) string {
+	return fmt.Sprintf("%s%0*d", literal, s.SequencePadding, sequence)
+}
+
+// Validate checks the format can only produce numbers that stay unique within the tenant
+func (s *OrderNumberSettings) Validate() error {
+	if !orderNumberPrefixPattern.MatchString(s.Prefix) {
+		return fmt.Errorf("prefix may only contain letters, digits and - _ / # (up to 20 characters)")
+	}
+	for storefrontID, prefix := range s.StorefrontPrefixes {
+		if storefrontID == "" {
+			return fmt.Errorf("storefront prefixes need a storefront ID")
+		}
+		if !orderNumberPrefixPattern.MatchString(prefix) {
+			return fmt.Errorf("prefix for storefront %s may only contain letters, digits and - _ / # (up to 20 characters)", storefrontID)
+		}
+	}
+
+	switch s.DateFormat {
+	case OrderNumberDateNone, OrderNumberDateYear, OrderNumberDateShortYr, OrderNumberDateMonth, OrderNumberDateShortMon, OrderNumberDateDay:
+	default:
+		return fmt.Errorf("dateFormat must be one of YYYY, YY, YYYYMM, YYMM, YYYYMMDD or empty")
+	}
+	switch s.Separator {
+	case "", "-", "_", "/":
+	default:
+		return fmt.Errorf("separator must be -, _, / or empty")
+	}
+	if s.SequencePadding < 1 || s.SequencePadding > 12 {
+		return fmt.Errorf("sequencePadding must be between 1 and 12")
+	}
+	if s.StartSequence < 1 {
+		return fmt.Errorf("startSequence must be at least 1")
+	}
+
+	// A sequence that restarts must have the period in the number, or numbers repeat
+	hasYear := s.DateFormat != OrderNumberDateNone
+	hasMonth := s.DateFormat == OrderNumberDateMonth || s.DateFormat == OrderNumberDateShortMon || s.DateFormat == OrderNumberDateDay
+	hasDay := s.DateFormat == OrderNumberDateDay
+	switch s.ResetPeriod {
+	case OrderNumberResetNever:
+	case OrderNumberResetYearly:
+		if !hasYear {
+			return fmt.Errorf("a yearly reset needs a date component with the year")
+		}
+	case OrderNumberResetMonthly:
+		if !hasMonth {
+			return fmt.Errorf("a monthly reset needs a date component with the month (YYYYMM, YYMM or YYYYMMDD)")
+		}
+	case OrderNumberResetDaily:
+		if !hasDay {
+			return fmt.Errorf("a daily reset needs the YYYYMMDD date component")
+		}
+	default:
+		return fmt.Errorf("resetPeriod must be never, yearly, monthly or daily")
+	}
+	return nil
+}
+
+// UpdateOrderNumberSettingsRequest replaces a tenant's order number format
+type UpdateOrderNumberSettingsRequest struct {
+	Prefix             string                `json:"prefix"`
+	StorefrontPrefixes map[string]string     `json:"storefrontPrefixes"`
+	DateFormat         OrderNumberDateFormat `json:"dateFormat"`
+	Separator          *string               `json:"separator"` // Defaults to "-"
+	SequencePadding    int                   `json:"sequencePadding"`
+	StartSequence      int64                 `json:"startSequence"`
+	ResetPeriod        OrderNumberReset      `json:"resetPeriod"`
+	GapFree            bool                  `json:"gapFree"`
+}
+
+// OrderNumberPreview is the next number a prefix will issue
+type OrderNumberPreview struct {
+	StorefrontID string `json:"storefrontId,omitempty"`
+	Prefix       string `json:"prefix"`
+	NextNumber   string `json:"nextNumber"`
+	// ExistingMax is the highest sequence already used by orders with this number pattern;
+	// the sequence continues after it so backfilled or imported orders are never reused
+	ExistingMax int64 `json:"existingMax,omitempty"`
+}
+
+// OrderNumberSettingsResponse is a tenant's order number format with the next numbers it will issue
+type OrderNumberSettingsResponse struct {
+	Settings   *OrderNumberSettings `json:"settings"`
+	Configured bool                 `json:"configured"` // False when the tenant uses the default ORD-<timestamp> numbers
+	Next       []OrderNumberPreview `json:"next"`
+}
+
+// StringMap is a custom type for JSONB storage of string maps
+type StringMap map[string]string
+
+// Value implements driver.Valuer for JSONB storage
+func (m StringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return json.Marshal(map[string]string{})
+	}
+	return json.Marshal(m)
+}
+
+// Scan implements sql.Scanner for JSONB retrieval
+func (m *StringMap) Scan(value interface{}) error {
+	if value == nil {
+		*m = StringMap{}
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	}
+	return nil
+}
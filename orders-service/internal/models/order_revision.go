@@ -0,0 +1,116 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevisionPaymentAction is what an order edit did about the change in the order's total
+type RevisionPaymentAction string
+
+const (
+	RevisionPaymentNone   RevisionPaymentAction = "NONE"   // Total unchanged, or the order isn't paid yet
+	RevisionPaymentCharge RevisionPaymentAction = "CHARGE" // Customer owes more; a payment intent was created for the difference
+	RevisionPaymentRefund RevisionPaymentAction = "REFUND" // Customer paid too much; the difference was refunded
+)
+
+// RevisionPaymentStatus is where an order edit's charge or refund got to
+type RevisionPaymentStatus string
+
+const (
+	RevisionPaymentNotRequired RevisionPaymentStatus = "NOT_REQUIRED"
+	RevisionPaymentPending     RevisionPaymentStatus = "PENDING" // Charge awaiting the customer, or refund in progress
+	RevisionPaymentSucceeded   RevisionPaymentStatus = "SUCCEEDED"
+	RevisionPaymentFailed      RevisionPaymentStatus = "FAILED" // Needs to be settled by hand
+)
+
+// OrderRevisionChange is one line of an order edit
+type OrderRevisionChange struct {
+	OrderItemID      uuid.UUID `json:"orderItemId"`
+	ProductID        uuid.UUID `json:"productId"`
+	ProductName      string    `json:"productName"`
+	SKU              string    `json:"sku"`
+	UnitPrice        float64   `json:"unitPrice"`
+	PreviousQuantity int       `json:"previousQuantity"` // 0 for an added item
+	NewQuantity      int       `json:"newQuantity"`      // 0 for a removed item
+}
+
+// OrderRevisionChanges is stored as JSONB
+type OrderRevisionChanges []OrderRevisionChange
+
+// Value implements driver.Valuer for JSONB storage
+func (c OrderRevisionChanges) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner for JSONB retrieval
+func (c *OrderRevisionChanges) Scan(value interface{}) error {
+	if value == nil {
+		*c = nil
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	}
+	return nil
+}
+
+// OrderRevision records one edit of a placed order: the item changes, the totals before and
+// after, and how the difference was charged or refunded
+type OrderRevision struct {
+	ID       uuid.UUID            `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID string               `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_order_revisions_tenant"`
+	OrderID  uuid.UUID            `json:"orderId" gorm:"type:uuid;not null;uniqueIndex:uniq_order_revisions_order_revision"`
+	Revision int                  `json:"revision" gorm:"not null;uniqueIndex:uniq_order_revisions_order_revision"` // 1 for the first edit
+	Changes  OrderRevisionChanges `json:"changes" gorm:"type:jsonb"`
+	Reason   string               `json:"reason" gorm:"type:text"`
+
+	PreviousSubtotal       float64 `json:"previousSubtotal" gorm:"type:decimal(10,2)"`
+	PreviousTaxAmount      float64 `json:"previousTaxAmount" gorm:"type:decimal(10,2)"`
+	PreviousDiscountAmount float64 `json:"previousDiscountAmount" gorm:"type:decimal(10,2)"`
+	PreviousTotal          float64 `json:"previousTotal" gorm:"type:decimal(10,2)"`
+	NewSubtotal            float64 `json:"newSubtotal" gorm:"type:decimal(10,2)"`
+	NewTaxAmount           float64 `json:"newTaxAmount" gorm:"type:decimal(10,2)"`
+	NewDiscountAmount      float64 `json:"newDiscountAmount" gorm:"type:decimal(10,2)"`
+	NewTotal               float64 `json:"newTotal" gorm:"type:decimal(10,2)"`
+
+	PaymentDelta     float64               `json:"paymentDelta" gorm:"type:decimal(10,2)"` // NewTotal - PreviousTotal
+	PaymentAction    RevisionPaymentAction `json:"paymentAction" gorm:"type:varchar(20);not null"`
+	PaymentStatus    RevisionPaymentStatus `json:"paymentStatus" gorm:"type:varchar(20);not null"`
+	PaymentReference string                `json:"paymentReference,omitempty"`                // Refund ID, or the payment intent's ID
+	PaymentIntent    JSONB                 `json:"paymentIntent,omitempty" gorm:"type:jsonb"` // payment-service's intent for a charge, for collecting it from the customer
+	PaymentError     string                `json:"paymentError,omitempty" gorm:"type:text"`
+
+	CustomerNotified bool       `json:"customerNotified" gorm:"default:false"`
+	EditedBy         *uuid.UUID `json:"editedBy,omitempty" gorm:"type:uuid"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+}
+
+// TableName specifies the table name for OrderRevision
+func (OrderRevision) TableName() string {
+	return "order_revisions"
+}
+
+// IsEditable reports whether staff can still change the order's items: it has been placed but
+// nothing has been picked, it isn't part of a split, and any payment hasn't been refunded
+func (o *Order) IsEditable() bool {
+	switch o.Status {
+	case OrderStatusPlaced, OrderStatusConfirmed, OrderStatusProcessing:
+	default:
+		return false
+	}
+	if o.FulfillmentStatus != FulfillmentStatusUnfulfilled || o.IsPickup() {
+		return false
+	}
+	if o.IsSplit || o.ParentOrderID != nil {
+		return false
+	}
+	return o.PaymentStatus == PaymentStatusPending || o.PaymentStatus == PaymentStatusPaid
+}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ReportType identifies the data a scheduled report contains
+type ReportType string
+
+const (
+	ReportTypeSalesSummary  ReportType = "sales_summary"  // Daily orders, sales, refunds and new customers
+	ReportTypeLowStock      ReportType = "low_stock"      // Stock at or below its reorder point (a snapshot, not a period)
+	ReportTypeVendorPayouts ReportType = "vendor_payouts" // Per-vendor GMV, refunds and net payable
+)
+
+// ReportFrequency is how often a scheduled report is sent
+type ReportFrequency string
+
+const (
+	ReportFrequencyDaily   ReportFrequency = "daily"
+	ReportFrequencyWeekly  ReportFrequency = "weekly"
+	ReportFrequencyMonthly ReportFrequency = "monthly"
+)
+
+// ReportFormat is the file format a report is rendered to
+type ReportFormat string
+
+const (
+	ReportFormatCSV ReportFormat = "csv"
+	ReportFormatPDF ReportFormat = "pdf"
+)
+
+// ReportDeliveryStatus is the outcome of one report run
+type ReportDeliveryStatus string
+
+const (
+	ReportDeliverySent          ReportDeliveryStatus = "sent"
+	ReportDeliveryPartiallySent ReportDeliveryStatus = "partially_sent" // Rendered, but some recipients failed
+	ReportDeliveryFailed        ReportDeliveryStatus = "failed"
+)
+
+// ReportSchedule is a tenant's recurring report. Runs happen at HourUTC on every day (daily),
+// on DayOfWeek (weekly) or on DayOfMonth (monthly), and cover the period that just ended.
+type ReportSchedule struct {
+	ID         uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID   string          `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_report_schedules_tenant"`
+	Name       string          `json:"name" gorm:"type:varchar(255);not null"`
+	ReportType ReportType      `json:"reportType" gorm:"type:varchar(30);not null"`
+	Frequency  ReportFrequency `json:"frequency" gorm:"type:varchar(20);not null"`
+	Format     ReportFormat    `json:"format" gorm:"type:varchar(10);not null;default:'csv'"`
+	Recipients pq.StringArray  `json:"recipients" gorm:"type:text[];not null"`
+
+	// Filters (empty means all)
+	VendorID    string `json:"vendorId,omitempty" gorm:"type:varchar(255)"`    // Sales summary and vendor payouts
+	WarehouseID string `json:"warehouseId,omitempty" gorm:"type:varchar(255)"` // Low stock
+
+	// Timing
+	HourUTC    int `json:"hourUtc" gorm:"default:6"`    // 0-23
+	DayOfWeek  int `json:"dayOfWeek" gorm:"default:1"`  // Weekly: 0 = Sunday ... 6 = Saturday
+	DayOfMonth int `json:"dayOfMonth" gorm:"default:1"` // Monthly: 1-28 so every month has the day
+
+	IsActive   bool                 `json:"isActive" gorm:"default:true"`
+	NextRunAt  time.Time            `json:"nextRunAt" gorm:"not null;index:idx_report_schedules_due"`
+	LastRunAt  *time.Time           `json:"lastRunAt,omitempty"`
+	LastStatus ReportDeliveryStatus `json:"lastStatus,omitempty" gorm:"type:varchar(20)"`
+
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	CreatedBy string         `json:"createdBy,omitempty" gorm:"type:varchar(255)"`
+	UpdatedBy string         `json:"updatedBy,omitempty" gorm:"type:varchar(255)"`
+}
+
+func (ReportSchedule) TableName() string {
+	return "report_schedules"
+}
+
+// NextRunAfter returns the first run time strictly after t
+func (s *ReportSchedule) NextRunAfter(t time.Time) time.Time {
+	t = t.UTC()
+	next := time.Date(t.Year(), t.Month(), t.Day(), s.HourUTC, 0, 0, 0, time.UTC)
+
+	switch s.Frequency {
+	case ReportFrequencyWeekly:
+		next = next.AddDate(0, 0, (s.DayOfWeek-int(next.Weekday())+7)%7)
+		if !next.After(t) {
+			next = next.AddDate(0, 0, 7)
+		}
+	case ReportFrequencyMonthly:
+		next = time.Date(t.Year(), t.Month(), s.DayOfMonth, s.HourUTC, 0, 0, 0, time.UTC)
+		if !next.After(t) {
+			next = next.AddDate(0, 1, 0)
+		}
+	default:
+		if !next.After(t) {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
+
+// PeriodEndingAt returns the inclusive date range covered by a run at runAt: the previous day,
+// the previous seven days or the previous calendar month
+func (s *ReportSchedule) PeriodEndingAt(runAt time.Time) (time.Time, time.Time) {
+	runAt = runAt.UTC()
+	today := time.Date(runAt.Year(), runAt.Month(), runAt.Day(), 0, 0, 0, 0, time.UTC)
+	to := today.AddDate(0, 0, -1)
+
+	switch s.Frequency {
+	case ReportFrequencyWeekly:
+		return today.AddDate(0, 0, -7), to
+	case ReportFrequencyMonthly:
+		firstOfMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return firstOfMonth.AddDate(0, -1, 0), firstOfMonth.AddDate(0, 0, -1)
+	default:
+		return to, to
+	}
+}
+
+// ReportDelivery is the history entry for one run of a schedule
+type ReportDelivery struct {
+	ID          uuid.UUID            `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID    string               `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_report_deliveries_tenant"`
+	ScheduleID  uuid.UUID            `json:"scheduleId" gorm:"type:uuid;not null;index:idx_report_deliveries_schedule"`
+	ReportType  ReportType           `json:"reportType" gorm:"type:varchar(30);not null"`
+	Format      ReportFormat         `json:"format" gorm:"type:varchar(10);not null"`
+	PeriodStart time.Time            `json:"periodStart" gorm:"type:date"`
+	PeriodEnd   time.Time            `json:"periodEnd" gorm:"type:date"`
+	Recipients  pq.StringArray       `json:"recipients" gorm:"type:text[]"`
+	Status      ReportDeliveryStatus `json:"status" gorm:"type:varchar(20);not null"`
+	Manual      bool                 `json:"manual" gorm:"default:false"` // Sent from "run now" rather than the schedule
+	RowCount    int                  `json:"rowCount" gorm:"default:0"`
+
+	// Rendered file in document-service
+	FileName      string `json:"fileName,omitempty" gorm:"type:varchar(255)"`
+	FileSize      int64  `json:"fileSize" gorm:"default:0"`
+	StorageBucket string `json:"-" gorm:"type:varchar(255)"`
+	StoragePath   string `json:"-" gorm:"type:varchar(500)"`
+
+	Error     string    `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index:idx_report_deliveries_schedule"`
+}
+
+func (ReportDelivery) TableName() string {
+	return "report_deliveries"
+}
+
+// ReportScheduleRequest creates or replaces a report schedule
+type ReportScheduleRequest struct {
+	Name        string          `json:"name" binding:"required,max=255"`
+	ReportType  ReportType      `json:"reportType" binding:"required,oneof=sales_summary low_stock vendor_payouts"`
+	Frequency   ReportFrequency `json:"frequency" binding:"required,oneof=daily weekly monthly"`
+	Format      ReportFormat    `json:"format" binding:"omitempty,oneof=csv pdf"`
+	Recipients  []string        `json:"recipients" binding:"required,min=1,max=20,dive,email"`
+	VendorID    string          `json:"vendorId"`
+	WarehouseID string          `json:"warehouseId"`
+	HourUTC     *int            `json:"hourUtc" binding:"omitempty,min=0,max=23"`
+	DayOfWeek   *int            `json:"dayOfWeek" binding:"omitempty,min=0,max=6"`
+	DayOfMonth  *int            `json:"dayOfMonth" binding:"omitempty,min=1,max=28"`
+	IsActive    *bool           `json:"isActive"`
+}
+
+// ReportDownload is a short-lived link to a delivered report file
+type ReportDownload struct {
+	URL       string    `json:"url"`
+	FileName  string    `json:"fileName"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
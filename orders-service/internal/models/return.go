@@ -1,6 +1,9 @@
 package models
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -73,12 +76,17 @@ type Return struct {
 	RefundMethod     RefundMethod   `json:"refundMethod" gorm:"type:varchar(30)"`
 	RefundProcessedAt *time.Time    `json:"refundProcessedAt"`
 	RestockingFee    float64        `json:"restockingFee" gorm:"type:decimal(10,2);default:0"`
+	RefundBreakdown  *RefundBreakdown `json:"refundBreakdown,omitempty" gorm:"type:jsonb"` // Itemized fees and the tenders the refund was paid out to
 
 	// Shipping details
 	ReturnShippingCost      float64  `json:"returnShippingCost" gorm:"type:decimal(10,2);default:0"`
 	ReturnTrackingNumber    string   `json:"returnTrackingNumber"`
 	ReturnCarrier           string   `json:"returnCarrier"`
 	ReturnShippingLabelURL  string   `json:"returnShippingLabelUrl"`
+	ReturnShipmentID        string   `json:"returnShipmentId,omitempty"` // shipping-service shipment, when the label was generated through it
+
+	// Drop-off intake code, set on approval and encoded in the return's QR code
+	IntakeCode              *string  `json:"intakeCode,omitempty" gorm:"type:varchar(32);uniqueIndex:idx_returns_intake_code"`
 
 	// Exchange details (if applicable)
 	ExchangeOrderID  *uuid.UUID     `json:"exchangeOrderId" gorm:"type:uuid"`
@@ -149,6 +157,8 @@ type ReturnPolicy struct {
 	AllowStoreCredit      bool           `json:"allowStoreCredit" gorm:"default:true"`
 	RestockingFeePercent  float64        `json:"restockingFeePercent" gorm:"type:decimal(5,2);default:0"`
 	FreeReturnShipping    bool           `json:"freeReturnShipping" gorm:"default:false"`
+	ReturnShippingFee     float64        `json:"returnShippingFee" gorm:"type:decimal(10,2);default:0"` // Deducted when return shipping isn't free and the return has no label cost
+	WaiveFeesForMerchantFault bool       `json:"waiveFeesForMerchantFault" gorm:"default:true"` // No fees for defective, wrong or misdescribed items
 	AutoApproveReturns    bool           `json:"autoApproveReturns" gorm:"default:false"`
 	RequirePhotos         bool           `json:"requirePhotos" gorm:"default:false"`
 	PolicyText            string         `json:"policyText" gorm:"type:text"`
@@ -168,6 +178,14 @@ func (r *Return) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// NewReturnIntakeCode generates an unguessable intake code. It only uses characters from
+// the QR alphanumeric set, which keeps the printed code small.
+func NewReturnIntakeCode() string {
+	b := make([]byte, 10)
+	rand.Read(b)
+	return "RTN-" + strings.ToUpper(hex.EncodeToString(b))
+}
+
 // TableName specifies the table name for Return
 func (Return) TableName() string {
 	return "returns"
@@ -244,6 +262,11 @@ func (r *Return) CanCancel() bool {
 	return r.Status == ReturnStatusPending || r.Status == ReturnStatusApproved
 }
 
+// CanIntake checks if return items can be received by scanning the intake code
+func (r *Return) CanIntake() bool {
+	return r.Status == ReturnStatusApproved || r.Status == ReturnStatusInTransit
+}
+
 // CanComplete checks if return can be marked as completed
 func (r *Return) CanComplete() bool {
 	return r.Status == ReturnStatusInspecting || r.Status == ReturnStatusReceived
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// RefundTender is where part of a return's refund is paid out
+type RefundTender string
+
+const (
+	RefundTenderPayment  RefundTender = "PAYMENT"   // A payment-service payment of the order
+	RefundTenderGiftCard RefundTender = "GIFT_CARD" // A gift card applied to the order at checkout
+)
+
+// Refund line statuses, as reported by payment-service
+const (
+	RefundLineSucceeded = "SUCCEEDED"
+	RefundLinePending   = "PENDING"
+	RefundLineFailed    = "FAILED"
+)
+
+// RefundBreakdown itemizes how a return's refund was worked out and where it was paid out.
+// It is stored on the return so a partly failed refund can be retried without paying any
+// tender twice, and so later returns of the same order know what each tender has left.
+type RefundBreakdown struct {
+	Items                   []RefundBreakdownItem `json:"items"`
+	ItemsTotal              float64               `json:"itemsTotal"`
+	RestockingFee           float64               `json:"restockingFee"`
+	ReturnShippingDeduction float64               `json:"returnShippingDeduction"`
+	RefundTotal             float64               `json:"refundTotal"`
+	Tenders                 []RefundTenderLine    `json:"tenders,omitempty"`
+}
+
+// RefundBreakdownItem is one returned item's share of the refund
+type RefundBreakdownItem struct {
+	ReturnItemID  uuid.UUID `json:"returnItemId"`
+	ProductName   string    `json:"productName"`
+	Quantity      int       `json:"quantity"`
+	Amount        float64   `json:"amount"`
+	RestockingFee float64   `json:"restockingFee"`
+	FeeWaived     bool      `json:"feeWaived,omitempty"` // Merchant fault, see ReturnPolicy.WaiveFeesForMerchantFault
+}
+
+// RefundTenderLine is the part of a refund paid out to one tender
+type RefundTenderLine struct {
+	Tender       RefundTender `json:"tender"`
+	PaymentID    string       `json:"paymentId,omitempty"`
+	GiftCardCode string       `json:"giftCardCode,omitempty"`
+	Amount       float64      `json:"amount"`
+	Status       string       `json:"status,omitempty"`
+	RefundID     string       `json:"refundId,omitempty"`
+	Reference    string       `json:"reference,omitempty"`
+	Error        string       `json:"error,omitempty"`
+}
+
+// Value implements driver.Valuer for JSONB storage
+func (b RefundBreakdown) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+// Scan implements sql.Scanner for JSONB retrieval
+func (b *RefundBreakdown) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, b)
+	case string:
+		return json.Unmarshal([]byte(v), b)
+	}
+	return nil
+}
+
+// Settled reports whether the line's money has gone out, or is on its way
+func (l RefundTenderLine) Settled() bool {
+	return l.Status != "" && l.Status != RefundLineFailed
+}
+
+// SameTender reports whether two lines pay out to the same payment or gift card
+func (l RefundTenderLine) SameTender(other RefundTenderLine) bool {
+	return l.Tender == other.Tender && l.PaymentID == other.PaymentID && l.GiftCardCode == other.GiftCardCode
+}
+
+// MaskedGiftCardCode shows only the last four characters of a gift card code
+func MaskedGiftCardCode(code string) string {
+	if len(code) <= 4 {
+		return code
+	}
+	return "****" + code[len(code)-4:]
+}
+
+// IsMerchantFault reports whether an item came back because of the merchant rather than the
+// customer: it was defective, the wrong item or not as described
+func (i ReturnItem) IsMerchantFault(returnReason ReturnReason) bool {
+	if i.IsDefective {
+		return true
+	}
+	reason := i.Reason
+	if reason == "" {
+		reason = returnReason
+	}
+	switch reason {
+	case ReturnReasonDefective, ReturnReasonWrongItem, ReturnReasonNotAsDescribed:
+		return true
+	}
+	return false
+}
+
+// CalculateRefundBreakdown works out a return's refund under the policy: a restocking fee
+// on each item, unless it came back through merchant fault and the policy waives fees, and
+// a deduction for return shipping when the customer pays for it
+func (r *Return) CalculateRefundBreakdown(policy *ReturnPolicy) *RefundBreakdown {
+	breakdown := &RefundBreakdown{Items: make([]RefundBreakdownItem, 0, len(r.Items))}
+	allMerchantFault := len(r.Items) > 0
+
+	for _, item := range r.Items {
+		line := RefundBreakdownItem{
+			ReturnItemID: item.ID,
+			ProductName:  item.ProductName,
+			Quantity:     item.Quantity,
+			Amount:       roundCents(item.RefundAmount),
+		}
+		merchantFault := item.IsMerchantFault(r.Reason)
+		if !merchantFault {
+			allMerchantFault = false
+		}
+		if merchantFault && policy.WaiveFeesForMerchantFault {
+			line.FeeWaived = true
+		} else if policy.RestockingFeePercent > 0 {
+			line.RestockingFee = roundCents(line.Amount * policy.RestockingFeePercent / 100.0)
+		}
+		breakdown.Items = append(breakdown.Items, line)
+		breakdown.ItemsTotal += line.Amount
+		breakdown.RestockingFee += line.RestockingFee
+	}
+
+	if !policy.FreeReturnShipping && !(allMerchantFault && policy.WaiveFeesForMerchantFault) {
+		// A label cost recorded on the return wins over the policy's flat fee
+		breakdown.ReturnShippingDeduction = r.ReturnShippingCost
+		if breakdown.ReturnShippingDeduction == 0 {
+			breakdown.ReturnShippingDeduction = policy.ReturnShippingFee
+		}
+	}
+
+	breakdown.ItemsTotal = roundCents(breakdown.ItemsTotal)
+	breakdown.RestockingFee = roundCents(breakdown.RestockingFee)
+	breakdown.RefundTotal = roundCents(math.Max(0, breakdown.ItemsTotal-breakdown.RestockingFee-breakdown.ReturnShippingDeduction))
+	return breakdown
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
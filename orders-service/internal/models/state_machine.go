@@ -27,14 +27,45 @@ var ValidPaymentTransitions = map[PaymentStatus][]PaymentStatus{
 // ValidFulfillmentTransitions defines valid state transitions for FulfillmentStatus
 var ValidFulfillmentTransitions = map[FulfillmentStatus][]FulfillmentStatus{
 	FulfillmentStatusUnfulfilled:    {FulfillmentStatusProcessing},
-	FulfillmentStatusProcessing:     {FulfillmentStatusPacked, FulfillmentStatusUnfulfilled}, // Can go back if issue found
-	FulfillmentStatusPacked:         {FulfillmentStatusDispatched, FulfillmentStatusProcessing},
+	FulfillmentStatusProcessing:     {FulfillmentStatusPacked, FulfillmentStatusUnfulfilled, FulfillmentStatusReadyForPickup}, // Can go back if issue found
+	FulfillmentStatusPacked:         {FulfillmentStatusDispatched, FulfillmentStatusProcessing, FulfillmentStatusReadyForPickup},
 	FulfillmentStatusDispatched:     {FulfillmentStatusInTransit},
 	FulfillmentStatusInTransit:      {FulfillmentStatusOutForDelivery, FulfillmentStatusReturned},
 	FulfillmentStatusOutForDelivery: {FulfillmentStatusDelivered, FulfillmentStatusFailedDelivery},
 	FulfillmentStatusDelivered:      {}, // Terminal state
 	FulfillmentStatusFailedDelivery: {FulfillmentStatusOutForDelivery, FulfillmentStatusReturned}, // Retry or return
 	FulfillmentStatusReturned:       {FulfillmentStatusProcessing}, // Can be reprocessed
+	FulfillmentStatusReadyForPickup: {FulfillmentStatusPickedUp, FulfillmentStatusProcessing}, // Back to processing if not collected
+	FulfillmentStatusPickedUp:       {}, // Terminal state
+
+	// Only reached by rolling up an order's fulfillments, which move it on as the rest ship
+	FulfillmentStatusPartiallyShipped: {FulfillmentStatusDispatched, FulfillmentStatusInTransit, FulfillmentStatusOutForDelivery, FulfillmentStatusDelivered},
+}
+
+// shippingOnlyFulfillmentStatuses involve a carrier and don't apply to click-and-collect orders
+var shippingOnlyFulfillmentStatuses = map[FulfillmentStatus]bool{
+	FulfillmentStatusDispatched:     true,
+	FulfillmentStatusInTransit:      true,
+	FulfillmentStatusOutForDelivery: true,
+	FulfillmentStatusDelivered:      true,
+	FulfillmentStatusFailedDelivery: true,
+	FulfillmentStatusReturned:       true,
+
+	FulfillmentStatusPartiallyShipped: true,
+}
+
+// pickupOnlyFulfillmentStatuses only apply to click-and-collect orders
+var pickupOnlyFulfillmentStatuses = map[FulfillmentStatus]bool{
+	FulfillmentStatusReadyForPickup: true,
+	FulfillmentStatusPickedUp:       true,
+}
+
+// FulfillmentStatusAppliesTo checks if a fulfillment status can be used for the given fulfillment type
+func FulfillmentStatusAppliesTo(status FulfillmentStatus, fulfillmentType FulfillmentType) bool {
+	if fulfillmentType == FulfillmentTypePickup {
+		return !shippingOnlyFulfillmentStatuses[status]
+	}
+	return !pickupOnlyFulfillmentStatuses[status]
 }
 
 // CanTransitionOrderStatus checks if a transition from one order status to another is valid
@@ -79,6 +110,17 @@ func CanTransitionFulfillmentStatus(from, to FulfillmentStatus) bool {
 	return false
 }
 
+// ValidateFulfillmentStatusForType returns an error if the status doesn't apply to the fulfillment type
+func ValidateFulfillmentStatusForType(status FulfillmentStatus, fulfillmentType FulfillmentType) error {
+	if !FulfillmentStatusAppliesTo(status, fulfillmentType) {
+		if fulfillmentType == FulfillmentTypePickup {
+			return fmt.Errorf("fulfillment status %s does not apply to pickup orders", status)
+		}
+		return fmt.Errorf("fulfillment status %s only applies to pickup orders", status)
+	}
+	return nil
+}
+
 // ValidateOrderStatusTransition returns an error if the transition is invalid
 func ValidateOrderStatusTransition(from, to OrderStatus) error {
 	if !CanTransitionOrderStatus(from, to) {
@@ -118,6 +160,17 @@ func GetNextValidFulfillmentStatuses(current FulfillmentStatus) []FulfillmentSta
 	return ValidFulfillmentTransitions[current]
 }
 
+// GetNextValidFulfillmentStatusesForType returns the valid next fulfillment statuses for an order's fulfillment type
+func GetNextValidFulfillmentStatusesForType(current FulfillmentStatus, fulfillmentType FulfillmentType) []FulfillmentStatus {
+	next := []FulfillmentStatus{}
+	for _, status := range ValidFulfillmentTransitions[current] {
+		if FulfillmentStatusAppliesTo(status, fulfillmentType) {
+			next = append(next, status)
+		}
+	}
+	return next
+}
+
 // IsTerminalOrderStatus checks if the order status is a terminal state
 func IsTerminalOrderStatus(status OrderStatus) bool {
 	return len(ValidOrderTransitions[status]) == 0
@@ -184,6 +237,8 @@ func (s FulfillmentStatus) DisplayName() string {
 		return "Packed"
 	case FulfillmentStatusDispatched:
 		return "Dispatched"
+	case FulfillmentStatusPartiallyShipped:
+		return "Partially Shipped"
 	case FulfillmentStatusInTransit:
 		return "In Transit"
 	case FulfillmentStatusOutForDelivery:
@@ -194,6 +249,10 @@ func (s FulfillmentStatus) DisplayName() string {
 		return "Delivery Failed"
 	case FulfillmentStatusReturned:
 		return "Returned"
+	case FulfillmentStatusReadyForPickup:
+		return "Ready for Pickup"
+	case FulfillmentStatusPickedUp:
+		return "Picked Up"
 	default:
 		return string(s)
 	}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StuckOrderReason is the state an order has been stuck in past its threshold
+type StuckOrderReason string
+
+const (
+	StuckOrderPaidNotFulfilled    StuckOrderReason = "PAID_NOT_FULFILLED"    // Paid, fulfillment not dispatched
+	StuckOrderShippedNotDelivered StuckOrderReason = "SHIPPED_NOT_DELIVERED" // Dispatched, never delivered
+)
+
+// StuckOrderAlert records an order the watchdog found stuck and the ticket raised for it.
+// An alert resolves itself once the order moves on; only one alert per order and reason is open at a time.
+type StuckOrderAlert struct {
+	ID                uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID          string            `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_stuck_order_alerts_tenant;uniqueIndex:idx_stuck_order_alerts_open,where:resolved_at IS NULL"`
+	OrderID           uuid.UUID         `json:"orderId" gorm:"type:uuid;not null;uniqueIndex:idx_stuck_order_alerts_open,where:resolved_at IS NULL"`
+	OrderNumber       string            `json:"orderNumber" gorm:"type:varchar(100)"`
+	Reason            StuckOrderReason  `json:"reason" gorm:"type:varchar(40);not null;uniqueIndex:idx_stuck_order_alerts_open,where:resolved_at IS NULL"`
+	Status            OrderStatus       `json:"status" gorm:"type:varchar(20)"`            // Order status when detected
+	FulfillmentStatus FulfillmentStatus `json:"fulfillmentStatus" gorm:"type:varchar(30)"` // Fulfillment status when detected
+	StuckSince        time.Time         `json:"stuckSince" gorm:"not null"`                // When the order entered the stuck state
+	DetectedAt        time.Time         `json:"detectedAt" gorm:"not null"`
+
+	// Escalation to tickets-service; retried by the watchdog until a ticket exists
+	TicketID           string     `json:"ticketId,omitempty" gorm:"type:varchar(255)"`
+	TicketNumber       string     `json:"ticketNumber,omitempty" gorm:"type:varchar(100)"`
+	EscalationAttempts int        `json:"escalationAttempts" gorm:"default:0"`
+	EscalationError    string     `json:"escalationError,omitempty" gorm:"type:text"`
+	EscalatedAt        *time.Time `json:"escalatedAt,omitempty"`
+
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty" gorm:"index:idx_stuck_order_alerts_resolved"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+func (StuckOrderAlert) TableName() string {
+	return "stuck_order_alerts"
+}
+
+// StuckOrderCandidate is an order past a stuck threshold that has no open alert yet
+type StuckOrderCandidate struct {
+	OrderID           uuid.UUID
+	TenantID          string
+	OrderNumber       string
+	Status            OrderStatus
+	FulfillmentStatus FulfillmentStatus
+	StuckSince        time.Time
+}
+
+// StuckOrderListResponse is the ops view of stuck orders
+type StuckOrderListResponse struct {
+	Alerts     []StuckOrderAlert           `json:"alerts"`
+	Total      int64                       `json:"total"`
+	OpenCounts map[StuckOrderReason]int64  `json:"openCounts"`
+	Thresholds map[StuckOrderReason]string `json:"thresholds"`
+	Page       int                         `json:"page"`
+	Limit      int                         `json:"limit"`
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnalyticsEventKind identifies the kind of external signal recorded in the analytics ledger
+type AnalyticsEventKind string
+
+const (
+	AnalyticsEventPaymentCaptured  AnalyticsEventKind = "payment_captured"
+	AnalyticsEventPaymentRefunded  AnalyticsEventKind = "payment_refunded"
+	AnalyticsEventCustomerAcquired AnalyticsEventKind = "customer_acquired"
+	AnalyticsEventCartStarted      AnalyticsEventKind = "cart_started"
+)
+
+// AnalyticsEvent is a deduplicated ledger of events consumed from other services
+// (payments, customers, carts). The dedupe key makes NATS redeliveries harmless.
+type AnalyticsEvent struct {
+	ID         uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID   string             `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:idx_analytics_events_dedupe;index:idx_analytics_events_tenant_time"`
+	Kind       AnalyticsEventKind `json:"kind" gorm:"type:varchar(30);not null;uniqueIndex:idx_analytics_events_dedupe"`
+	DedupeKey  string             `json:"dedupeKey" gorm:"type:varchar(255);not null;uniqueIndex:idx_analytics_events_dedupe"`
+	Amount     float64            `json:"amount" gorm:"type:decimal(14,2);default:0"`
+	OccurredAt time.Time          `json:"occurredAt" gorm:"not null;index:idx_analytics_events_tenant_time"`
+	CreatedAt  time.Time          `json:"createdAt"`
+}
+
+func (AnalyticsEvent) TableName() string {
+	return "analytics_events"
+}
+
+// TenantDailyMetric is the tenant-wide daily rollup backing /api/v1/analytics
+type TenantDailyMetric struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID       string    `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:idx_tenant_daily_metrics_key"`
+	StatDate       time.Time `json:"statDate" gorm:"type:date;not null;uniqueIndex:idx_tenant_daily_metrics_key"`
+	GrossSales     float64   `json:"grossSales" gorm:"type:decimal(14,2);default:0"`
+	OrderCount     int64     `json:"orderCount" gorm:"default:0"`
+	CancelledCount int64     `json:"cancelledCount" gorm:"default:0"`
+	ItemsSold      int64     `json:"itemsSold" gorm:"default:0"`
+	CapturedAmount float64   `json:"capturedAmount" gorm:"type:decimal(14,2);default:0"`
+	RefundCount    int64     `json:"refundCount" gorm:"default:0"`
+	RefundAmount   float64   `json:"refundAmount" gorm:"type:decimal(14,2);default:0"`
+	NewCustomers   int64     `json:"newCustomers" gorm:"default:0"`
+	CartsStarted   int64     `json:"cartsStarted" gorm:"default:0"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+func (TenantDailyMetric) TableName() string {
+	return "tenant_daily_metrics"
+}
+
+// TenantDailyCategorySale is the per-category sales rollup for a tenant and day
+type TenantDailyCategorySale struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID   string    `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:idx_tenant_daily_category_sales_key"`
+	StatDate   time.Time `json:"statDate" gorm:"type:date;not null;uniqueIndex:idx_tenant_daily_category_sales_key"`
+	CategoryID string    `json:"categoryId" gorm:"type:varchar(255);not null;uniqueIndex:idx_tenant_daily_category_sales_key"`
+	Revenue    float64   `json:"revenue" gorm:"type:decimal(14,2);default:0"`
+	Quantity   int64     `json:"quantity" gorm:"default:0"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (TenantDailyCategorySale) TableName() string {
+	return "tenant_daily_category_sales"
+}
+
+// UncategorizedCategoryID groups sales whose product category could not be resolved
+const UncategorizedCategoryID = "uncategorized"
+
+// ProductDaySale is a tenant's sales of a single product on one day (rollup input)
+type ProductDaySale struct {
+	TenantID  string
+	ProductID string
+	Revenue   float64
+	Quantity  int64
+}
+
+// TenantAnalyticsSummary aggregates the tenant rollups over the requested period
+type TenantAnalyticsSummary struct {
+	GrossSales        float64 `json:"grossSales"`
+	NetSales          float64 `json:"netSales"` // Gross sales minus refunds
+	OrderCount        int64   `json:"orderCount"`
+	CancelledCount    int64   `json:"cancelledCount"`
+	ItemsSold         int64   `json:"itemsSold"`
+	AverageOrderValue float64 `json:"averageOrderValue"`
+	CapturedAmount    float64 `json:"capturedAmount"`
+	RefundCount       int64   `json:"refundCount"`
+	RefundAmount      float64 `json:"refundAmount"`
+	RefundRate        float64 `json:"refundRate"` // Refunds as a percentage of orders
+	NewCustomers      int64   `json:"newCustomers"`
+	CartsStarted      int64   `json:"cartsStarted"`
+	CartConversion    float64 `json:"cartConversion"` // Orders as a percentage of carts started
+}
+
+// TenantDailyPoint is a single day in the tenant sales time series
+type TenantDailyPoint struct {
+	Date              string  `json:"date"`
+	GrossSales        float64 `json:"grossSales"`
+	OrderCount        int64   `json:"orderCount"`
+	AverageOrderValue float64 `json:"averageOrderValue"`
+	RefundAmount      float64 `json:"refundAmount"`
+	NewCustomers      int64   `json:"newCustomers"`
+	CartsStarted      int64   `json:"cartsStarted"`
+}
+
+// CategorySales ranks categories by revenue for the period
+type CategorySales struct {
+	CategoryID string  `json:"categoryId"`
+	Revenue    float64 `json:"revenue"`
+	Quantity   int64   `json:"quantity"`
+	Share      float64 `json:"share"` // Percentage of categorized revenue
+}
+
+// TenantAnalyticsOverview is the admin dashboard payload
+type TenantAnalyticsOverview struct {
+	From    string                 `json:"from"`
+	To      string                 `json:"to"`
+	Summary TenantAnalyticsSummary `json:"summary"`
+	Daily   []TenantDailyPoint     `json:"daily"`
+}
+
+// OrderItemMargin is the gross margin of an order line at the cost snapshotted when it was ordered
+type OrderItemMargin struct {
+	OrderItemID uuid.UUID `json:"orderItemId"`
+	ProductID   uuid.UUID `json:"productId"`
+	ProductName string    `json:"productName"`
+	SKU         string    `json:"sku"`
+	Quantity    int       `json:"quantity"`
+	Revenue     float64   `json:"revenue"`
+	UnitCost    *float64  `json:"unitCost,omitempty"` // Unset when the product had no cost when ordered
+	Cost        *float64  `json:"cost,omitempty"`
+	GrossMargin *float64  `json:"grossMargin,omitempty"`
+}
+
+// OrderMargin is an order's gross margin: item revenue less order discounts, minus the cost
+// of the items that have one
+type OrderMargin struct {
+	OrderID       uuid.UUID         `json:"orderId"`
+	OrderNumber   string            `json:"orderNumber"`
+	Status        OrderStatus       `json:"status"`
+	Currency      string            `json:"currency"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	Revenue       float64           `json:"revenue"`
+	Cost          float64           `json:"cost"`
+	GrossMargin   float64           `json:"grossMargin"`
+	MarginPercent float64           `json:"marginPercent"`
+	UncostedItems int               `json:"uncostedItems"` // Lines left out of the cost, so the margin is overstated
+	Items         []OrderItemMargin `json:"items,omitempty"`
+}
+
+// MarginReport totals gross margin over the period's orders, with a page of orders
+type MarginReport struct {
+	From          string        `json:"from"`
+	To            string        `json:"to"`
+	OrderCount    int64         `json:"orderCount"`
+	Revenue       float64       `json:"revenue"`
+	Cost          float64       `json:"cost"`
+	GrossMargin   float64       `json:"grossMargin"`
+	MarginPercent float64       `json:"marginPercent"`
+	UncostedItems int64         `json:"uncostedItems"`
+	Orders        []OrderMargin `json:"orders"`
+	Page          int           `json:"page"`
+	Limit         int           `json:"limit"`
+}
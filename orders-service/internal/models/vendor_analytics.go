@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VendorDailyStat is a per-vendor, per-day rollup of order activity.
+// Rows are recomputed by the vendor stats rollup job so vendor dashboards
+// never have to scan the raw orders table across a whole tenant.
+type VendorDailyStat struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID       string    `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:idx_vendor_daily_stats_key"`
+	VendorID       string    `json:"vendorId" gorm:"type:varchar(255);not null;uniqueIndex:idx_vendor_daily_stats_key"`
+	StatDate       time.Time `json:"statDate" gorm:"type:date;not null;uniqueIndex:idx_vendor_daily_stats_key"`
+	GMV            float64   `json:"gmv" gorm:"column:gmv;type:decimal(14,2);default:0"`
+	OrderCount     int64     `json:"orderCount" gorm:"default:0"`
+	CancelledCount int64     `json:"cancelledCount" gorm:"default:0"`
+	ItemsSold      int64     `json:"itemsSold" gorm:"default:0"`
+	ReturnCount    int64     `json:"returnCount" gorm:"default:0"`
+	RefundAmount   float64   `json:"refundAmount" gorm:"type:decimal(14,2);default:0"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+func (VendorDailyStat) TableName() string {
+	return "vendor_daily_stats"
+}
+
+// VendorAnalyticsSummary aggregates the rollups over the requested period
+type VendorAnalyticsSummary struct {
+	GMV               float64 `json:"gmv"`
+	OrderCount        int64   `json:"orderCount"`
+	CancelledCount    int64   `json:"cancelledCount"`
+	ItemsSold         int64   `json:"itemsSold"`
+	AverageOrderValue float64 `json:"averageOrderValue"`
+	ReturnCount       int64   `json:"returnCount"`
+	ReturnRate        float64 `json:"returnRate"` // Returns as a percentage of orders
+	RefundAmount      float64 `json:"refundAmount"`
+}
+
+// VendorDailyPoint is a single day in the vendor dashboard time series
+type VendorDailyPoint struct {
+	Date        string  `json:"date"`
+	GMV         float64 `json:"gmv"`
+	OrderCount  int64   `json:"orderCount"`
+	ItemsSold   int64   `json:"itemsSold"`
+	ReturnCount int64   `json:"returnCount"`
+}
+
+// VendorTopProduct ranks a vendor's products by revenue for the period
+type VendorTopProduct struct {
+	ProductID   uuid.UUID `json:"productId"`
+	ProductName string    `json:"productName"`
+	SKU         string    `json:"sku"`
+	Quantity    int64     `json:"quantity"`
+	Revenue     float64   `json:"revenue"`
+}
+
+// VendorPayoutTotal sums a vendor's rollups over a payout period
+type VendorPayoutTotal struct {
+	VendorID     string  `json:"vendorId"`
+	OrderCount   int64   `json:"orderCount"`
+	GMV          float64 `json:"gmv" gorm:"column:gmv"`
+	RefundAmount float64 `json:"refundAmount"`
+}
+
+// VendorAnalytics is the vendor dashboard payload
+type VendorAnalytics struct {
+	VendorID    string                 `json:"vendorId"`
+	From        string                 `json:"from"`
+	To          string                 `json:"to"`
+	Summary     VendorAnalyticsSummary `json:"summary"`
+	Daily       []VendorDailyPoint     `json:"daily"`
+	TopProducts []VendorTopProduct     `json:"topProducts"`
+}
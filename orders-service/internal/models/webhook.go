@@ -0,0 +1,166 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"orders-service/internal/encryption"
+)
+
+// Order lifecycle events a webhook subscription can receive
+const (
+	WebhookEventOrderCreated   = "order.created"
+	WebhookEventOrderPaid      = "order.paid"
+	WebhookEventOrderFulfilled = "order.fulfilled" // Shipped, or collected for pickup orders
+	WebhookEventOrderCancelled = "order.cancelled"
+	WebhookEventOrderRefunded  = "order.refunded" // Fully or partially; sent for every refund
+)
+
+// WebhookEvents lists every event in the order they happen
+var WebhookEvents = []string{
+	WebhookEventOrderCreated,
+	WebhookEventOrderPaid,
+	WebhookEventOrderFulfilled,
+	WebhookEventOrderCancelled,
+	WebhookEventOrderRefunded,
+}
+
+// WebhookDeliveryStatus is where a delivery is in the outbox
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending    WebhookDeliveryStatus = "PENDING"    // Waiting for its next attempt
+	WebhookDeliveryDelivering WebhookDeliveryStatus = "DELIVERING" // Claimed by the dispatcher
+	WebhookDeliveryDelivered  WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryFailed     WebhookDeliveryStatus = "FAILED" // Out of attempts; can be replayed
+)
+
+// WebhookSubscription sends a tenant's order lifecycle events as signed JSON to an endpoint.
+// Unlike an OrderIntegration the payload has a fixed format, so it suits receivers that
+// consume events rather than a specific ERP's import format.
+type WebhookSubscription struct {
+	ID          uuid.UUID                  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID    string                     `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_webhook_subscriptions_tenant"`
+	Name        string                     `json:"name" gorm:"type:varchar(255);not null"`
+	URL         string                     `json:"url" gorm:"type:varchar(1000);not null"`
+	Events      pq.StringArray             `json:"events" gorm:"type:text[]"` // Empty receives every event
+	Secret      encryption.EncryptedString `json:"-" gorm:"type:text;not null"`
+	MaxAttempts int                        `json:"maxAttempts" gorm:"default:8"`
+	IsActive    bool                       `json:"isActive" gorm:"default:true"`
+
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	CreatedBy string         `json:"createdBy,omitempty" gorm:"type:varchar(255)"`
+	UpdatedBy string         `json:"updatedBy,omitempty" gorm:"type:varchar(255)"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// WebhookDelivery is one event for one subscription: the dispatcher's outbox entry and, once
+// sent, the delivery log. The payload is built on the first attempt and resent unchanged on
+// retries, so receivers can de-duplicate on the delivery ID.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID       string                `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_webhook_deliveries_tenant"`
+	SubscriptionID uuid.UUID             `json:"subscriptionId" gorm:"type:uuid;not null;index:idx_webhook_deliveries_subscription"`
+	OrderID        uuid.UUID             `json:"orderId" gorm:"type:uuid;not null;index:idx_webhook_deliveries_order"`
+	Event          string                `json:"event" gorm:"type:varchar(50);not null"`
+	Status         WebhookDeliveryStatus `json:"status" gorm:"type:varchar(20);not null;default:'PENDING';index:idx_webhook_deliveries_due"`
+	Attempts       int                   `json:"attempts" gorm:"default:0"`
+	NextAttemptAt  time.Time             `json:"nextAttemptAt" gorm:"not null;index:idx_webhook_deliveries_due"`
+	LockedUntil    *time.Time            `json:"-"`
+	ReplayOf       *uuid.UUID            `json:"replayOf,omitempty" gorm:"type:uuid"`
+
+	Payload        string     `json:"payload,omitempty" gorm:"type:text"`
+	ResponseStatus int        `json:"responseStatus,omitempty"`
+	ResponseBody   string     `json:"responseBody,omitempty" gorm:"type:text"` // Truncated
+	Error          string     `json:"error,omitempty" gorm:"type:text"`
+	LastAttemptAt  *time.Time `json:"lastAttemptAt,omitempty"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"index:idx_webhook_deliveries_subscription"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// enqueueOrderWebhooks adds an outbox delivery for every active subscription of the order's
+// tenant that takes the lifecycle event a timeline entry stands for. The event is derived from
+// the order's statuses, which the same transaction has just updated. Except for refunds, each
+// event is queued at most once per order and subscription (uniq_webhook_deliveries_event), so
+// repeated status updates don't resend it.
+func enqueueOrderWebhooks(tx *gorm.DB, t *OrderTimeline) error {
+	return tx.Exec(`
+		INSERT INTO webhook_deliveries
+			(id, tenant_id, subscription_id, order_id, event, status, attempts, next_attempt_at, created_at, updated_at)
+		SELECT gen_random_uuid(), s.tenant_id, s.id, o.id, e.event, ?, 0, NOW(), NOW(), NOW()
+		FROM orders o
+		CROSS JOIN LATERAL (SELECT CASE
+			WHEN ? IN ('ORDER_CREATED', 'ORDER_CREATED_FROM_SPLIT') THEN ?
+			WHEN ? = 'PAYMENT_STATUS_CHANGED' AND o.payment_status = 'PAID' THEN ?
+			WHEN ? = 'PAYMENT_STATUS_CHANGED' AND o.payment_status IN ('REFUNDED', 'PARTIALLY_REFUNDED') THEN ?
+			WHEN ? = 'STATUS_CHANGED' AND o.status = 'CANCELLED' THEN ?
+			WHEN ? = 'STATUS_CHANGED' AND o.status = 'SHIPPED' THEN ?
+			WHEN ? = 'FULFILLMENT_STATUS_CHANGED' AND o.fulfillment_status IN ('DISPATCHED', 'PICKED_UP') THEN ?
+			END AS event) e
+		JOIN webhook_subscriptions s ON s.tenant_id = o.tenant_id
+		WHERE o.id = ?
+		  AND e.event IS NOT NULL
+		  AND s.is_active
+		  AND s.deleted_at IS NULL
+		  AND (s.events IS NULL OR cardinality(s.events) = 0 OR e.event = ANY(s.events))
+		ON CONFLICT DO NOTHING`,
+		WebhookDeliveryPending,
+		t.Event, WebhookEventOrderCreated,
+		t.Event, WebhookEventOrderPaid,
+		t.Event, WebhookEventOrderRefunded,
+		t.Event, WebhookEventOrderCancelled,
+		t.Event, WebhookEventOrderFulfilled,
+		t.Event, WebhookEventOrderFulfilled,
+		t.OrderID,
+	).Error
+}
+
+// WebhookSubscriptionRequest creates or replaces a webhook subscription
+type WebhookSubscriptionRequest struct {
+	Name        string   `json:"name" binding:"required,max=255"`
+	URL         string   `json:"url" binding:"required,url,max=1000"`
+	Events      []string `json:"events" binding:"max=10,dive,oneof=order.created order.paid order.fulfilled order.cancelled order.refunded"`
+	MaxAttempts *int     `json:"maxAttempts" binding:"omitempty,min=1,max=15"`
+	IsActive    *bool    `json:"isActive"`
+}
+
+// WebhookSubscriptionWithSecret is returned when a subscription is created or its secret is
+// rotated; it is the only time the signing secret is shown
+type WebhookSubscriptionWithSecret struct {
+	WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// WebhookPayload is the JSON body sent for every event
+type WebhookPayload struct {
+	ID        string    `json:"id"` // Delivery ID, stable across retries
+	Event     string    `json:"event"`
+	TenantID  string    `json:"tenantId"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      struct {
+		Order *Order `json:"order"`
+	} `json:"data"`
+}
+
+// WebhookDeliveryFilter narrows a subscription's delivery log
+type WebhookDeliveryFilter struct {
+	Status  WebhookDeliveryStatus
+	Event   string
+	OrderID *uuid.UUID
+	Page    int
+	Limit   int
+}
@@ -156,3 +156,18 @@ func (r *CancellationSettingsRepository) ListByTenant(ctx context.Context, tenan
 
 	return settings, nil
 }
+
+// ListWithOrderAutomation retrieves tenant-level settings that enable auto-confirmation or unpaid order cancellation
+func (r *CancellationSettingsRepository) ListWithOrderAutomation(ctx context.Context) ([]models.CancellationSettings, error) {
+	var settings []models.CancellationSettings
+	err := r.db.WithContext(ctx).
+		Where("(storefront_id = '' OR storefront_id IS NULL) AND deleted_at IS NULL").
+		Where("auto_confirm_delay_minutes > 0 OR unpaid_order_cancel_hours > 0").
+		Find(&settings).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order automation settings: %w", err)
+	}
+
+	return settings, nil
+}
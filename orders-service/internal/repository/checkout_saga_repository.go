@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"orders-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CheckoutSagaRepository handles database operations for checkout sagas
+type CheckoutSagaRepository struct {
+	db *gorm.DB
+}
+
+// NewCheckoutSagaRepository creates a new repository instance
+func NewCheckoutSagaRepository(db *gorm.DB) *CheckoutSagaRepository {
+	return &CheckoutSagaRepository{db: db}
+}
+
+// Create inserts a new checkout saga
+func (r *CheckoutSagaRepository) Create(ctx context.Context, saga *models.CheckoutSaga) error {
+	if err := r.db.WithContext(ctx).Create(saga).Error; err != nil {
+		return fmt.Errorf("failed to create checkout saga: %w", err)
+	}
+	return nil
+}
+
+// GetByOrderID retrieves the saga of a tenant's order, or nil if it wasn't checked out with one
+func (r *CheckoutSagaRepository) GetByOrderID(ctx context.Context, tenantID string, orderID uuid.UUID) (*models.CheckoutSaga, error) {
+	var saga models.CheckoutSaga
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND order_id = ?", tenantID, orderID).
+		First(&saga).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get checkout saga: %w", err)
+	}
+	return &saga, nil
+}
+
+// ClaimDue reserves up to limit unfinished sagas whose next attempt is due until lockUntil and
+// returns them, oldest first. Sagas whose claim expired are taken over.
+func (r *CheckoutSagaRepository) ClaimDue(ctx context.Context, now, lockUntil time.Time, limit int) ([]models.CheckoutSaga, error) {
+	var sagas []models.CheckoutSaga
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE checkout_sagas SET locked_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM checkout_sagas
+			WHERE status NOT IN ? AND next_attempt_at <= ?
+			  AND (locked_until IS NULL OR locked_until < ?)
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		lockUntil, now, finishedCheckoutSagaStatuses, now, now, limit,
+	).Scan(&sagas).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim checkout sagas: %w", err)
+	}
+	return sagas, nil
+}
+
+// Save records a saga's progress and releases the claim
+func (r *CheckoutSagaRepository) Save(ctx context.Context, saga *models.CheckoutSaga) error {
+	saga.LockedUntil = nil
+	saga.UpdatedAt = time.Now()
+	err := r.db.WithContext(ctx).Model(saga).
+		Select("status", "payment_intent", "hold_expires_at", "attempts", "next_attempt_at", "locked_until",
+			"last_error", "failure_reason", "updated_at", "completed_at").
+		Updates(saga).Error
+	if err != nil {
+		return fmt.Errorf("failed to save checkout saga: %w", err)
+	}
+	return nil
+}
+
+var finishedCheckoutSagaStatuses = []models.CheckoutSagaStatus{
+	models.CheckoutSagaCompleted,
+	models.CheckoutSagaCompensated,
+	models.CheckoutSagaFailed,
+}
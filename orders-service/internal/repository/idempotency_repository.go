@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"orders-service/internal/models"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyRepository stores idempotency records in Redis, with their TTL set on the key,
+// and falls back to the idempotency_records table when Redis isn't configured or can't be
+// reached
+type IdempotencyRepository struct {
+	db    *gorm.DB
+	redis *redis.Client
+}
+
+// NewIdempotencyRepository creates a new repository instance. redis may be nil.
+func NewIdempotencyRepository(db *gorm.DB, redisClient *redis.Client) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db, redis: redisClient}
+}
+
+func idempotencyRedisKey(rec *models.IdempotencyRecord) string {
+	return fmt.Sprintf("tesseract:orders:idempotency:%s:%s:%s", rec.TenantID, rec.Scope, rec.Key)
+}
+
+// Reserve claims rec's key for a request about to run. It returns nil if the key is now held
+// by the caller, or the record already holding it: a finished request to replay, or one still
+// running.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, rec *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	if r.redis != nil {
+		existing, err := r.reserveRedis(ctx, rec)
+		if err == nil {
+			return existing, nil
+		}
+		log.Printf("[Idempotency] Redis unavailable, using database: %v", err)
+	}
+	return r.reserveDB(ctx, rec)
+}
+
+func (r *IdempotencyRepository) reserveRedis(ctx context.Context, rec *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	key := idempotencyRedisKey(rec)
+
+	// The key expires with the lock, so a request that died frees it
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := r.redis.SetNX(ctx, key, payload, time.Until(rec.LockedUntil)).Result()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return nil, nil
+		}
+
+		val, err := r.redis.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue // Expired between the two calls
+		}
+		if err != nil {
+			return nil, err
+		}
+		var existing models.IdempotencyRecord
+		if err := json.Unmarshal(val, &existing); err != nil {
+			return nil, err
+		}
+		return &existing, nil
+	}
+	return nil, fmt.Errorf("idempotency key %s kept changing", rec.Key)
+}
+
+func (r *IdempotencyRepository) reserveDB(ctx context.Context, rec *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	db := r.db.WithContext(ctx)
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(rec)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	// Take the key over if its record expired or its request died
+	now := time.Now()
+	result = db.Model(&models.IdempotencyRecord{}).
+		Where("tenant_id = ? AND scope = ? AND key = ?", rec.TenantID, rec.Scope, rec.Key).
+		Where("expires_at < ? OR (completed = ? AND locked_until < ?)", now, false, now).
+		Updates(map[string]interface{}{
+			"request_hash": rec.RequestHash,
+			"completed":    false,
+			"status_code":  0,
+			"content_type": "",
+			"body":         nil,
+			"locked_until": rec.LockedUntil,
+			"expires_at":   rec.ExpiresAt,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing models.IdempotencyRecord
+	err := db.Where("tenant_id = ? AND scope = ? AND key = ?", rec.TenantID, rec.Scope, rec.Key).
+		First(&existing).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency record: %w", err)
+	}
+	return &existing, nil
+}
+
+// Complete stores the response of a reserved request until the record expires
+func (r *IdempotencyRepository) Complete(ctx context.Context, rec *models.IdempotencyRecord) error {
+	rec.Completed = true
+	if r.redis != nil {
+		payload, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		err = r.redis.Set(ctx, idempotencyRedisKey(rec), payload, time.Until(rec.ExpiresAt)).Err()
+		if err == nil {
+			return nil
+		}
+		log.Printf("[Idempotency] Redis unavailable, using database: %v", err)
+	}
+
+	err := r.db.WithContext(ctx).Model(&models.IdempotencyRecord{}).
+		Where("tenant_id = ? AND scope = ? AND key = ?", rec.TenantID, rec.Scope, rec.Key).
+		Updates(map[string]interface{}{
+			"completed":    true,
+			"status_code":  rec.StatusCode,
+			"content_type": rec.ContentType,
+			"body":         rec.Body,
+			"updated_at":   time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Release frees a reserved key whose request shouldn't be replayed, so it can be retried
+func (r *IdempotencyRepository) Release(ctx context.Context, rec *models.IdempotencyRecord) error {
+	if r.redis != nil {
+		err := r.redis.Del(ctx, idempotencyRedisKey(rec)).Err()
+		if err == nil {
+			return nil
+		}
+		log.Printf("[Idempotency] Redis unavailable, using database: %v", err)
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND scope = ? AND key = ? AND completed = ?", rec.TenantID, rec.Scope, rec.Key, false).
+		Delete(&models.IdempotencyRecord{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes database records that expired before now; Redis expires its own
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", now).
+		Delete(&models.IdempotencyRecord{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency records: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"orders-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationRoutingRepository handles database operations for tenants' notification routing
+// and the order notification log
+type NotificationRoutingRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRoutingRepository creates a new repository instance
+func NewNotificationRoutingRepository(db *gorm.DB) *NotificationRoutingRepository {
+	return &NotificationRoutingRepository{db: db}
+}
+
+// GetSettings retrieves a tenant's notification routing, or nil if the tenant uses the defaults
+func (r *NotificationRoutingRepository) GetSettings(ctx context.Context, tenantID string) (*models.NotificationRoutingSettings, error) {
+	var settings models.NotificationRoutingSettings
+	err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).First(&settings).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification routing settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// UpsertSettings creates or replaces a tenant's notification routing
+func (r *NotificationRoutingRepository) UpsertSettings(ctx context.Context, settings *models.NotificationRoutingSettings) error {
+	existing, err := r.GetSettings(ctx, settings.TenantID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		settings.ID = existing.ID
+		settings.CreatedAt = existing.CreatedAt
+		settings.CreatedBy = existing.CreatedBy
+		if err := r.db.WithContext(ctx).Save(settings).Error; err != nil {
+			return fmt.Errorf("failed to update notification routing settings: %w", err)
+		}
+		return nil
+	}
+
+	if settings.ID == uuid.Nil {
+		settings.ID = uuid.New()
+	}
+	if err := r.db.WithContext(ctx).Create(settings).Error; err != nil {
+		return fmt.Errorf("failed to create notification routing settings: %w", err)
+	}
+	return nil
+}
+
+// CreateLog records a notification that was sent, skipped or queued for a digest
+func (r *NotificationRoutingRepository) CreateLog(ctx context.Context, entry *models.OrderNotificationLog) error {
+	if err := r.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create notification log: %w", err)
+	}
+	return nil
+}
+
+// QueuedDigestSendAfter returns when the digest already building for a customer on a channel
+// goes out, or nil if nothing is queued for them
+func (r *NotificationRoutingRepository) QueuedDigestSendAfter(ctx context.Context, tenantID string, channel models.NotificationChannel, recipient string) (*time.Time, error) {
+	var entry models.OrderNotificationLog
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND channel = ? AND recipient = ? AND status = ?", tenantID, channel, recipient, models.NotificationLogQueued).
+		Order("send_after ASC").
+		First(&entry).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queued notifications: %w", err)
+	}
+	return entry.SendAfter, nil
+}
+
+// ClaimDueLogs claims queued notifications whose digest is due, and ones whose claim expired,
+// oldest first. A customer's queued notifications share a send time so they are claimed together.
+func (r *NotificationRoutingRepository) ClaimDueLogs(ctx context.Context, now, lockUntil time.Time, limit int) ([]models.OrderNotificationLog, error) {
+	var entries []models.OrderNotificationLog
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE order_notification_logs SET status = ?, locked_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM order_notification_logs
+			WHERE (status = ? AND send_after <= ?) OR (status = ? AND locked_until < ?)
+			ORDER BY send_after, created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		models.NotificationLogSending, lockUntil, now,
+		models.NotificationLogQueued, now, models.NotificationLogSending, now,
+		limit,
+	).Scan(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim queued notifications: %w", err)
+	}
+	return entries, nil
+}
+
+// SaveLogResult records how a claimed notification went and releases the claim
+func (r *NotificationRoutingRepository) SaveLogResult(ctx context.Context, entry *models.OrderNotificationLog) error {
+	entry.LockedUntil = nil
+	err := r.db.WithContext(ctx).Model(entry).
+		Select("status", "locked_until", "digest_id", "payload", "error", "sent_at", "updated_at").
+		Updates(entry).Error
+	if err != nil {
+		return fmt.Errorf("failed to save notification log: %w", err)
+	}
+	return nil
+}
+
+// ListByOrder retrieves the notifications sent or queued for an order, newest first
+func (r *NotificationRoutingRepository) ListByOrder(ctx context.Context, tenantID string, orderID uuid.UUID) ([]models.OrderNotificationLog, error) {
+	var entries []models.OrderNotificationLog
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND order_id = ?", tenantID, orderID).
+		Order("created_at DESC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification logs: %w", err)
+	}
+	return entries, nil
+}
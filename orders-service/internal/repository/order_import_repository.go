@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"orders-service/internal/models"
+)
+
+// OrderImportRepository handles order import jobs and writes imported orders
+type OrderImportRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderImportRepository creates a new order import repository
+func NewOrderImportRepository(db *gorm.DB) *OrderImportRepository {
+	return &OrderImportRepository{db: db}
+}
+
+// Create queues a new import job
+func (r *OrderImportRepository) Create(ctx context.Context, job *models.OrderImportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// GetByID retrieves one of the tenant's import jobs
+func (r *OrderImportRepository) GetByID(ctx context.Context, tenantID string, jobID uuid.UUID) (*models.OrderImportJob, error) {
+	var job models.OrderImportJob
+	err := r.db.WithContext(ctx).
+		Omit("file_data").
+		Where("tenant_id = ? AND id = ?", tenantID, jobID).
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// List retrieves the tenant's import jobs, newest first
+func (r *OrderImportRepository) List(ctx context.Context, tenantID string, limit, offset int) ([]models.OrderImportJob, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.OrderImportJob{}).Where("tenant_id = ?", tenantID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []models.OrderImportJob
+	err := query.Omit("file_data").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&jobs).Error
+	return jobs, total, err
+}
+
+// ClaimNext claims the oldest queued job across tenants, or a processing job whose worker
+// stopped sending heartbeats. It returns nil when there is nothing to do.
+func (r *OrderImportRepository) ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.OrderImportJob, error) {
+	var job models.OrderImportJob
+	now := time.Now()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND heartbeat_at < ?)",
+				models.OrderImportPending, models.OrderImportProcessing, now.Add(-staleAfter)).
+			Order("created_at ASC").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":       models.OrderImportProcessing,
+			"heartbeat_at": now,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"updated_at":   now,
+		}
+		if job.StartedAt == nil {
+			updates["started_at"] = now
+		}
+		return tx.Model(&models.OrderImportJob{}).
+			Where("tenant_id = ? AND id = ?", job.TenantID, job.ID).
+			Updates(updates).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = models.OrderImportProcessing
+	job.Attempts++
+	return &job, nil
+}
+
+// SaveChunk commits a processed chunk's progress and row errors together, so NextRow never
+// advances past rows whose errors were not recorded
+func (r *OrderImportRepository) SaveChunk(ctx context.Context, job *models.OrderImportJob, rowErrors []models.OrderImportError) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(rowErrors) > 0 {
+			for i := range rowErrors {
+				rowErrors[i].JobID = job.ID
+				rowErrors[i].TenantID = job.TenantID
+				rowErrors[i].CreatedAt = now
+			}
+			if err := tx.CreateInBatches(rowErrors, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.OrderImportJob{}).
+			Where("tenant_id = ? AND id = ?", job.TenantID, job.ID).
+			Updates(map[string]interface{}{
+				"next_row":      job.NextRow,
+				"created_count": job.CreatedCount,
+				"skipped_count": job.SkippedCount,
+				"failed_count":  job.FailedCount,
+				"heartbeat_at":  now,
+				"updated_at":    now,
+			}).Error
+	})
+}
+
+// MarkCompleted marks the job finished and releases the stored file
+func (r *OrderImportRepository) MarkCompleted(ctx context.Context, job *models.OrderImportJob) error {
+	now := time.Now()
+	err := r.db.WithContext(ctx).Model(&models.OrderImportJob{}).
+		Where("tenant_id = ? AND id = ?", job.TenantID, job.ID).
+		Updates(map[string]interface{}{
+			"status":       models.OrderImportCompleted,
+			"completed_at": now,
+			"file_data":    nil,
+			"updated_at":   now,
+		}).Error
+	if err == nil {
+		job.Status = models.OrderImportCompleted
+		job.CompletedAt = &now
+	}
+	return err
+}
+
+// MarkFailed marks the job failed; the file is kept so the job can be resumed
+func (r *OrderImportRepository) MarkFailed(ctx context.Context, job *models.OrderImportJob, message string) error {
+	err := r.db.WithContext(ctx).Model(&models.OrderImportJob{}).
+		Where("tenant_id = ? AND id = ?", job.TenantID, job.ID).
+		Updates(map[string]interface{}{
+			"status":     models.OrderImportFailed,
+			"last_error": message,
+			"updated_at": time.Now(),
+		}).Error
+	if err == nil {
+		job.Status = models.OrderImportFailed
+		job.LastError = &message
+	}
+	return err
+}
+
+// Requeue hands a job interrupted by shutdown back to the queue
+func (r *OrderImportRepository) Requeue(ctx context.Context, job *models.OrderImportJob) error {
+	return r.db.WithContext(ctx).Model(&models.OrderImportJob{}).
+		Where("tenant_id = ? AND id = ? AND status = ?", job.TenantID, job.ID, models.OrderImportProcessing).
+		Updates(map[string]interface{}{
+			"status":     models.OrderImportPending,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// Resume puts a failed job back in the queue. It reports false if the job is not failed.
+func (r *OrderImportRepository) Resume(ctx context.Context, tenantID string, jobID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.OrderImportJob{}).
+		Where("tenant_id = ? AND id = ? AND status = ? AND file_data IS NOT NULL", tenantID, jobID, models.OrderImportFailed).
+		Updates(map[string]interface{}{
+			"status":     models.OrderImportPending,
+			"last_error": nil,
+			"updated_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetErrors retrieves a job's row errors in row order
+func (r *OrderImportRepository) GetErrors(ctx context.Context, tenantID string, jobID uuid.UUID) ([]models.OrderImportError, error) {
+	var rowErrors []models.OrderImportError
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND job_id = ?", tenantID, jobID).
+		Order("row_number ASC, created_at ASC").
+		Find(&rowErrors).Error
+	return rowErrors, err
+}
+
+// ExistingOrderNumbers returns which of the order numbers the tenant already uses. Soft-deleted
+// orders keep their numbers, so they are included.
+func (r *OrderImportRepository) ExistingOrderNumbers(ctx context.Context, tenantID string, orderNumbers []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(orderNumbers) == 0 {
+		return existing, nil
+	}
+
+	var taken []string
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Order{}).
+		Where("tenant_id = ? AND order_number IN ?", tenantID, orderNumbers).
+		Pluck("order_number", &taken).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, number := range taken {
+		existing[number] = true
+	}
+	return existing, nil
+}
+
+// CreateImportedOrder writes an imported order with its items, customer, shipping and payment.
+// Unlike OrderRepository.Create it keeps the order's own CreatedAt and records an ORDER_IMPORTED
+// timeline event, and it never publishes events or touches inventory.
+func (r *OrderImportRepository) CreateImportedOrder(ctx context.Context, order *models.Order) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Orders exported without a number are numbered in the tenant's format
+		if order.OrderNumber == "" {
+			if err := assignOrderNumber(r.db.WithContext(ctx), tx, order); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(order).Error; err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+
+		timeline := models.OrderTimeline{
+			OrderID:     order.ID,
+			Event:       "ORDER_IMPORTED",
+			Description: fmt.Sprintf("Order imported from %s", order.ImportSource),
+			Timestamp:   order.CreatedAt,
+			CreatedBy:   "system",
+		}
+		if err := tx.Create(&timeline).Error; err != nil {
+			return fmt.Errorf("failed to create timeline event: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"orders-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrderIntegrationRepository handles database operations for order integrations and their
+// delivery outbox
+type OrderIntegrationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderIntegrationRepository creates a new repository instance
+func NewOrderIntegrationRepository(db *gorm.DB) *OrderIntegrationRepository {
+	return &OrderIntegrationRepository{db: db}
+}
+
+// Create inserts a new order integration
+func (r *OrderIntegrationRepository) Create(ctx context.Context, integration *models.OrderIntegration) error {
+	if err := r.db.WithContext(ctx).Create(integration).Error; err != nil {
+		return fmt.Errorf("failed to create order integration: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a tenant's order integration, or nil if it doesn't exist
+func (r *OrderIntegrationRepository) GetByID(ctx context.Context, tenantID string, id uuid.UUID) (*models.OrderIntegration, error) {
+	var integration models.OrderIntegration
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		First(&integration).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get order integration: %w", err)
+	}
+	return &integration, nil
+}
+
+// List returns a tenant's order integrations, newest first
+func (r *OrderIntegrationRepository) List(ctx context.Context, tenantID string) ([]models.OrderIntegration, error) {
+	var integrations []models.OrderIntegration
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("created_at DESC").
+		Find(&integrations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order integrations: %w", err)
+	}
+	return integrations, nil
+}
+
+// Update saves an order integration
+func (r *OrderIntegrationRepository) Update(ctx context.Context, integration *models.OrderIntegration) error {
+	if err := r.db.WithContext(ctx).Save(integration).Error; err != nil {
+		return fmt.Errorf("failed to update order integration: %w", err)
+	}
+	return nil
+}
+
+// Delete soft-deletes a tenant's order integration. Deliveries still in the outbox are
+// dropped when the job finds the integration gone.
+func (r *OrderIntegrationRepository) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		Delete(&models.OrderIntegration{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete order integration: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetIntegrationForDelivery retrieves an integration for the delivery job, including deleted
+// ones so their deliveries can be closed out
+func (r *OrderIntegrationRepository) GetIntegrationForDelivery(ctx context.Context, id uuid.UUID) (*models.OrderIntegration, error) {
+	var integration models.OrderIntegration
+	err := r.db.WithContext(ctx).Unscoped().First(&integration, "id = ?", id).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order integration: %w", err)
+	}
+	return &integration, nil
+}
+
+// GetOrderForDelivery loads an order with everything a payload template can use. Deleted
+// orders are included so ORDER_DELETED events can still be rendered.
+func (r *OrderIntegrationRepository) GetOrderForDelivery(ctx context.Context, tenantID string, orderID uuid.UUID) (*models.Order, error) {
+	var order models.Order
+	err := r.db.WithContext(ctx).Unscoped().
+		Preload("Items").
+		Preload("Customer").
+		Preload("Shipping").
+		Preload("Pickup").
+		Preload("Payment").
+		Preload("Discounts").
+		Where("tenant_id = ?", tenantID).
+		First(&order, "id = ?", orderID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return &order, nil
+}
+
+// ClaimDueDeliveries marks up to limit due deliveries as delivering until lockUntil and
+// returns them, oldest first. Deliveries whose claim expired are taken over. A delivery waits
+// while an older one for the same integration and order is still outstanding, so each
+// order's events arrive in order.
+func (r *OrderIntegrationRepository) ClaimDueDeliveries(ctx context.Context, now, lockUntil time.Time, limit int) ([]models.OrderIntegrationDelivery, error) {
+	var deliveries []models.OrderIntegrationDelivery
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE order_integration_deliveries SET status = ?, locked_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT d.id FROM order_integration_deliveries d
+			WHERE ((d.status = ? AND d.next_attempt_at <= ?) OR (d.status = ? AND d.locked_until < ?))
+			  AND NOT EXISTS (
+				SELECT 1 FROM order_integration_deliveries p
+				WHERE p.integration_id = d.integration_id
+				  AND p.order_id = d.order_id
+				  AND p.status IN (?, ?)
+				  AND p.created_at < d.created_at)
+			ORDER BY d.created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		models.IntegrationDeliveryDelivering, lockUntil, now,
+		models.IntegrationDeliveryPending, now, models.IntegrationDeliveryDelivering, now,
+		models.IntegrationDeliveryPending, models.IntegrationDeliveryDelivering,
+		limit,
+	).Scan(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim integration deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// SaveDeliveryAttempt records the outcome of an attempt and releases the claim
+func (r *OrderIntegrationRepository) SaveDeliveryAttempt(ctx context.Context, delivery *models.OrderIntegrationDelivery) error {
+	delivery.LockedUntil = nil
+	err := r.db.WithContext(ctx).Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "locked_until", "request_body", "response_status",
+			"response_body", "error", "last_attempt_at", "delivered_at", "updated_at").
+		Updates(delivery).Error
+	if err != nil {
+		return fmt.Errorf("failed to save integration delivery: %w", err)
+	}
+	return nil
+}
+
+// GetDelivery retrieves one of an integration's deliveries, or nil if it doesn't exist
+func (r *OrderIntegrationRepository) GetDelivery(ctx context.Context, tenantID string, integrationID, id uuid.UUID) (*models.OrderIntegrationDelivery, error) {
+	var delivery models.OrderIntegrationDelivery
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND integration_id = ? AND id = ?", tenantID, integrationID, id).
+		First(&delivery).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get integration delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries returns an integration's delivery log, newest first
+func (r *OrderIntegrationRepository) ListDeliveries(ctx context.Context, tenantID string, integrationID uuid.UUID, filter models.IntegrationDeliveryFilter) ([]models.OrderIntegrationDelivery, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.OrderIntegrationDelivery{}).
+		Where("tenant_id = ? AND integration_id = ?", tenantID, integrationID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.OrderID != nil {
+		query = query.Where("order_id = ?", *filter.OrderID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count integration deliveries: %w", err)
+	}
+
+	var deliveries []models.OrderIntegrationDelivery
+	err := query.Order("created_at DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list integration deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// CreateReplays queues a new delivery for each of the given deliveries, linked to the
+// original, so the log keeps every attempt
+func (r *OrderIntegrationRepository) CreateReplays(ctx context.Context, originals []models.OrderIntegrationDelivery, now time.Time) ([]models.OrderIntegrationDelivery, error) {
+	if len(originals) == 0 {
+		return nil, nil
+	}
+
+	replays := make([]models.OrderIntegrationDelivery, 0, len(originals))
+	for _, o := range originals {
+		originalID := o.ID
+		replays = append(replays, models.OrderIntegrationDelivery{
+			TenantID:      o.TenantID,
+			IntegrationID: o.IntegrationID,
+			OrderID:       o.OrderID,
+			TimelineID:    o.TimelineID,
+			Event:         o.Event,
+			Status:        models.IntegrationDeliveryPending,
+			NextAttemptAt: now,
+			ReplayOf:      &originalID,
+		})
+	}
+	if err := r.db.WithContext(ctx).Create(&replays).Error; err != nil {
+		return nil, fmt.Errorf("failed to queue integration replays: %w", err)
+	}
+	return replays, nil
+}
+
+// GetFailedDeliveries returns an integration's failed deliveries created since the given time,
+// oldest first
+func (r *OrderIntegrationRepository) GetFailedDeliveries(ctx context.Context, tenantID string, integrationID uuid.UUID, since time.Time, limit int) ([]models.OrderIntegrationDelivery, error) {
+	var deliveries []models.OrderIntegrationDelivery
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND integration_id = ? AND status = ? AND created_at >= ?",
+			tenantID, integrationID, models.IntegrationDeliveryFailed, since).
+		Order("created_at").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failed integration deliveries: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"orders-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrderNumberSettingsRepository handles database operations for tenants' order number formats
+type OrderNumberSettingsRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderNumberSettingsRepository creates a new repository instance
+func NewOrderNumberSettingsRepository(db *gorm.DB) *OrderNumberSettingsRepository {
+	return &OrderNumberSettingsRepository{db: db}
+}
+
+// GetByTenant retrieves a tenant's order number settings, or nil if the tenant uses the default format
+func (r *OrderNumberSettingsRepository) GetByTenant(ctx context.Context, tenantID string) (*models.OrderNumberSettings, error) {
+	return getOrderNumberSettings(r.db.WithContext(ctx), tenantID)
+}
+
+// Upsert creates or replaces a tenant's order number settings
+func (r *OrderNumberSettingsRepository) Upsert(ctx context.Context, settings *models.OrderNumberSettings) error {
+	existing, err := r.GetByTenant(ctx, settings.TenantID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		settings.ID = existing.ID
+		settings.CreatedAt = existing.CreatedAt
+		settings.CreatedBy = existing.CreatedBy
+		if err := r.db.WithContext(ctx).Save(settings).Error; err != nil {
+			return fmt.Errorf("failed to update order number settings: %w", err)
+		}
+		return nil
+	}
+
+	if settings.ID == uuid.Nil {
+		settings.ID = uuid.New()
+	}
+	if err := r.db.WithContext(ctx).Create(settings).Error; err != nil {
+		return fmt.Errorf("failed to create order number settings: %w", err)
+	}
+	return nil
+}
+
+// PeekNext returns the number the given prefix would issue next without using it up, and the
+// highest sequence existing orders already hold for that pattern
+func (r *OrderNumberSettingsRepository) PeekNext(ctx context.Context, settings *models.OrderNumberSettings, prefix string, at time.Time) (string, int64, error) {
+	db := r.db.WithContext(ctx)
+	literal := settings.Literal(prefix, at)
+
+	var sequence models.OrderNumberSequence
+	next := settings.StartSequence
+	err := db.Where("tenant_id = ? AND scope = ?", settings.TenantID, settings.SequenceScope(prefix, at)).First(&sequence).Error
+	if err == nil {
+		next = sequence.LastValue + 1
+	} else if err != gorm.ErrRecordNotFound {
+		return "", 0, fmt.Errorf("failed to get order number sequence: %w", err)
+	}
+
+	digits := len(settings.Format("", next))
+	existingMax, err := maxExistingOrderSequence(db, settings.TenantID, literal, digits)
+	if err != nil {
+		return "", 0, err
+	}
+	if existingMax >= next {
+		next = existingMax + 1
+	}
+	return settings.Format(literal, next), existingMax, nil
+}
+
+// assignOrderNumber gives an order without a number the next number in its tenant's format.
+// Gap-free formats draw the sequence inside the order's transaction, so it's released if the
+// order isn't saved; others draw it straight away so concurrent checkouts don't queue on it.
+// Tenants without settings keep the default number from Order.BeforeCreate.
+func assignOrderNumber(db, tx *gorm.DB, order *models.Order) error {
+	settings, err := getOrderNumberSettings(tx, order.TenantID)
+	if err != nil || settings == nil {
+		return err
+	}
+
+	conn := db
+	if settings.GapFree {
+		conn = tx
+	}
+	number, err := allocateOrderNumber(conn, settings, order.StorefrontID, time.Now())
+	if err != nil {
+		return err
+	}
+	order.OrderNumber = number
+	return nil
+}
+
+// allocateOrderNumber takes the next sequence number for the prefix and period, skipping past
+// numbers already held by imported or backfilled orders
+func allocateOrderNumber(db *gorm.DB, settings *models.OrderNumberSettings, storefrontID string, at time.Time) (string, error) {
+	prefix := settings.PrefixFor(storefrontID)
+	literal := settings.Literal(prefix, at)
+	scope := settings.SequenceScope(prefix, at)
+
+	if err := db.Exec("INSERT INTO order_number_sequences (tenant_id, scope, last_value, updated_at) VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING",
+		settings.TenantID, scope, settings.StartSequence-1, time.Now()).Error; err != nil {
+		return "", fmt.Errorf("failed to create order number sequence: %w", err)
+	}
+
+	for attempt := 0; attempt < 3; attempt++ {
+		var sequence int64
+		err := db.Raw("UPDATE order_number_sequences SET last_value = last_value + 1, updated_at = ? WHERE tenant_id = ? AND scope = ? RETURNING last_value",
+			time.Now(), settings.TenantID, scope).Scan(&sequence).Error
+		if err != nil {
+			return "", fmt.Errorf("failed to allocate order number: %w", err)
+		}
+		number := settings.Format(literal, sequence)
+
+		// Soft-deleted orders keep their numbers, so they are checked too
+		var taken int64
+		if err := db.Raw("SELECT COUNT(*) FROM orders WHERE tenant_id = ? AND order_number = ?", settings.TenantID, number).Scan(&taken).Error; err != nil {
+			return "", fmt.Errorf("failed to check order number: %w", err)
+		}
+		if taken == 0 {
+			return number, nil
+		}
+
+		existingMax, err := maxExistingOrderSequence(db, settings.TenantID, literal, len(number)-len(literal))
+		if err != nil {
+			return "", err
+		}
+		if err := db.Exec("UPDATE order_number_sequences SET last_value = GREATEST(last_value, ?) WHERE tenant_id = ? AND scope = ?",
+			existingMax, settings.TenantID, scope).Error; err != nil {
+			return "", fmt.Errorf("failed to advance order number sequence: %w", err)
+		}
+	}
+	return "", fmt.Errorf("no free order number for %q: existing orders keep taking the next number", literal)
+}
+
+// maxExistingOrderSequence returns the highest sequence among the tenant's order numbers that are
+// the literal followed by at most maxDigits digits. The digit limit keeps legacy ORD-<timestamp>
+// numbers from pushing a new "ORD-" sequence into the billions.
+func maxExistingOrderSequence(db *gorm.DB, tenantID, literal string, maxDigits int) (int64, error) {
+	var existingMax int64
+	err := db.Raw(`SELECT COALESCE(MAX(CAST(SUBSTRING(order_number FROM ?) AS BIGINT)), 0) FROM orders
+		WHERE tenant_id = ? AND LEFT(order_number, ?) = ?
+		AND SUBSTRING(order_number FROM ?) ~ '^[0-9]+$' AND LENGTH(order_number) - ? <= ?`,
+		len(literal)+1, tenantID, len(literal), literal, len(literal)+1, len(literal), maxDigits).Scan(&existingMax).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to find existing order numbers: %w", err)
+	}
+	return existingMax, nil
+}
+
+func getOrderNumberSettings(db *gorm.DB, tenantID string) (*models.OrderNumberSettings, error) {
+	var settings models.OrderNumberSettings
+	if err := db.Where("tenant_id = ?", tenantID).First(&settings).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get order number settings: %w", err)
+	}
+	return &settings, nil
+}
@@ -9,8 +9,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/Tesseract-Nexus/go-shared/cache"
+	"orders-service/internal/metrics"
 	"orders-service/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Cache TTL constants for orders
@@ -41,10 +43,26 @@ type OrderRepository interface {
 	RemoveItems(orderID uuid.UUID, itemIDs []uuid.UUID, tenantID string) error
 	UpdateTotals(orderID uuid.UUID, subtotal, taxAmount, total float64, tenantID string) error
 	CreateSplit(split *models.OrderSplit) error
+	CreateVendorSplit(parent *models.Order, vendorOrders []models.Order, splits []models.OrderSplit) error
 	GetChildOrders(parentOrderID uuid.UUID, tenantID string) ([]models.Order, error)
 	BatchGetByIDs(ids []uuid.UUID, tenantID string) ([]*models.Order, error)
 	// Idempotency
 	FindByIdempotencyKey(tenantID, key string) (*models.Order, error)
+	// Order edits
+	ApplyRevision(order *models.Order, removedItemIDs []uuid.UUID, revision *models.OrderRevision) error
+	UpdateRevisionPayment(revision *models.OrderRevision) error
+	ListRevisions(orderID uuid.UUID, tenantID string) ([]models.OrderRevision, error)
+	// Partial fulfillments
+	CreateFulfillment(order *models.Order, fulfillment *models.OrderFulfillment) error
+	GetFulfillment(id, orderID uuid.UUID, tenantID string) (*models.OrderFulfillment, error)
+	ListFulfillments(orderID uuid.UUID, tenantID string) ([]models.OrderFulfillment, error)
+	UpdateFulfillment(fulfillment *models.OrderFulfillment, notes string) error
+	HasFulfillments(orderID uuid.UUID, tenantID string) (bool, error)
+	// Order automation
+	ListUnpaidOrderIDs(tenantID string, placedBefore time.Time, limit int) ([]uuid.UUID, error)
+	ListAwaitingFulfillmentOrderIDs(tenantID string, paidBefore time.Time, limit int) ([]uuid.UUID, error)
+	// Account deletion
+	AnonymizeCustomer(tenantID string, customerID uuid.UUID, email, anonymizedEmail string) (int, error)
 	// Health check methods for Redis
 	RedisHealth(ctx context.Context) error
 	CacheStats() *cache.CacheStats
@@ -59,8 +77,27 @@ type OrderFilters struct {
 	Status        *models.OrderStatus
 	DateFrom      *time.Time
 	DateTo        *time.Time
-	Page          int
-	Limit         int
+	// ExcludeVendorSplits hides the per-vendor child orders of a marketplace checkout,
+	// leaving the parent order that the customer placed
+	ExcludeVendorSplits bool
+	// Include lists the relations to load by JSON name; nil loads all of them
+	Include []string
+	Page    int
+	Limit   int
+}
+
+// OrderRelations are the JSON names of an order's relations, in preload order
+var OrderRelations = []string{"items", "customer", "shipping", "pickup", "payment", "timeline", "discounts"}
+
+// orderPreloads maps each relation to its association name
+var orderPreloads = map[string]string{
+	"items":     "Items",
+	"customer":  "Customer",
+	"shipping":  "Shipping",
+	"pickup":    "Pickup",
+	"payment":   "Payment",
+	"timeline":  "Timeline",
+	"discounts": "Discounts",
 }
 
 type orderRepository struct {
@@ -141,6 +178,13 @@ func (r *orderRepository) CacheStats() *cache.CacheStats {
 // Create creates a new order with all related entities
 func (r *orderRepository) Create(order *models.Order) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Number the order in the tenant's format, if it has one
+		if order.OrderNumber == "" {
+			if err := assignOrderNumber(r.db, tx, order); err != nil {
+				return err
+			}
+		}
+
 		// Create the main order
 		if err := tx.Create(order).Error; err != nil {
 			return fmt.Errorf("failed to create order: %w", err)
@@ -173,9 +217,11 @@ func (r *orderRepository) GetByID(id uuid.UUID, tenantID string) (*models.Order,
 		if err == nil {
 			var order models.Order
 			if err := json.Unmarshal([]byte(val), &order); err == nil {
+				metrics.RecordCacheLookup("order", true)
 				return &order, nil
 			}
 		}
+		metrics.RecordCacheLookup("order", false)
 	}
 
 	// Query from database
@@ -183,6 +229,7 @@ func (r *orderRepository) GetByID(id uuid.UUID, tenantID string) (*models.Order,
 	err := r.db.Preload("Items").
 		Preload("Customer").
 		Preload("Shipping").
+		Preload("Pickup").
 		Preload("Payment").
 		Preload("Timeline").
 		Preload("Discounts").
@@ -218,9 +265,11 @@ func (r *orderRepository) GetByOrderNumber(orderNumber string, tenantID string)
 		if err == nil {
 			var order models.Order
 			if err := json.Unmarshal([]byte(val), &order); err == nil {
+				metrics.RecordCacheLookup("order_number", true)
 				return &order, nil
 			}
 		}
+		metrics.RecordCacheLookup("order_number", false)
 	}
 
 	// Query from database
@@ -228,6 +277,7 @@ func (r *orderRepository) GetByOrderNumber(orderNumber string, tenantID string)
 	err := r.db.Preload("Items").
 		Preload("Customer").
 		Preload("Shipping").
+		Preload("Pickup").
 		Preload("Payment").
 		Preload("Timeline").
 		Preload("Discounts").
@@ -258,6 +308,7 @@ func (r *orderRepository) FindByIdempotencyKey(tenantID, key string) (*models.Or
 	err := r.db.Preload("Items").
 		Preload("Customer").
 		Preload("Shipping").
+		Preload("Pickup").
 		Preload("Payment").
 		Preload("Timeline").
 		Preload("Discounts").
@@ -285,6 +336,7 @@ func (r *orderRepository) BatchGetByIDs(ids []uuid.UUID, tenantID string) ([]*mo
 	err := r.db.Preload("Items").
 		Preload("Customer").
 		Preload("Shipping").
+		Preload("Pickup").
 		Preload("Payment").
 		Preload("Timeline").
 		Preload("Discounts").
@@ -337,6 +389,9 @@ func (r *orderRepository) List(filters OrderFilters) ([]models.Order, int64, err
 	if filters.DateTo != nil {
 		query = query.Where("created_at <= ?", *filters.DateTo)
 	}
+	if filters.ExcludeVendorSplits {
+		query = query.Where("NOT (parent_order_id IS NOT NULL AND split_reason = ?)", models.SplitTypeVendor)
+	}
 
 	// Count total records
 	if err := query.Count(&total).Error; err != nil {
@@ -353,13 +408,16 @@ func (r *orderRepository) List(filters OrderFilters) ([]models.Order, int64, err
 	}
 
 	// Execute query with preloads
-	err := query.Preload("Items").
-		Preload("Customer").
-		Preload("Shipping").
-		Preload("Payment").
-		Preload("Timeline").
-		Preload("Discounts").
-		Order("created_at DESC").
+	include := filters.Include
+	if include == nil {
+		include = OrderRelations
+	}
+	for _, relation := range include {
+		if association, ok := orderPreloads[relation]; ok {
+			query = query.Preload(association)
+		}
+	}
+	err := query.Order("created_at DESC").
 		Find(&orders).Error
 
 	if err != nil {
@@ -372,11 +430,28 @@ func (r *orderRepository) List(filters OrderFilters) ([]models.Order, int64, err
 	return orders, total, nil
 }
 
-// Update updates an existing order
+// Update updates an existing order. order.Version must be the version that was read;
+// if the stored row has moved on a *models.VersionConflictError is returned instead.
 func (r *orderRepository) Update(order *models.Order) error {
+	expected := order.Version
+	order.Version = expected + 1
+
 	err := r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Save(order).Error; err != nil {
-			return fmt.Errorf("failed to update order: %w", err)
+		result := tx.Model(order).
+			Where("tenant_id = ? AND version = ?", order.TenantID, expected).
+			Select("*").Omit(clause.Associations).
+			Updates(order)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update order: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			var current models.Order
+			if err := tx.Select("version").
+				Where("id = ? AND tenant_id = ?", order.ID, order.TenantID).
+				First(&current).Error; err != nil {
+				return fmt.Errorf("failed to update order: %w", err)
+			}
+			return &models.VersionConflictError{CurrentVersion: current.Version}
 		}
 
 		// Add timeline event for update
@@ -394,11 +469,13 @@ func (r *orderRepository) Update(order *models.Order) error {
 		return nil
 	})
 
-	// Invalidate cache if update was successful
-	if err == nil {
-		r.invalidateOrderCaches(context.Background(), order.TenantID, order.ID, order.OrderNumber)
+	if err != nil {
+		order.Version = expected
 	}
 
+	// Invalidate cache on success, and on conflict so a stale cached copy is not served again
+	r.invalidateOrderCaches(context.Background(), order.TenantID, order.ID, order.OrderNumber)
+
 	return err
 }
 
@@ -436,10 +513,16 @@ func (r *orderRepository) Delete(id uuid.UUID, tenantID string) error {
 func (r *orderRepository) UpdateStatus(id uuid.UUID, status models.OrderStatus, notes string, tenantID string) error {
 	err := r.db.Transaction(func(tx *gorm.DB) error {
 		// Update order status
-		if err := tx.Model(&models.Order{}).Where("id = ? AND tenant_id = ?", id, tenantID).Update("status", status).Error; err != nil {
+		if err := tx.Model(&models.Order{}).Where("id = ? AND tenant_id = ?", id, tenantID).Updates(map[string]interface{}{"status": status, "version": gorm.Expr("version + 1")}).Error; err != nil {
 			return fmt.Errorf("failed to update order status: %w", err)
 		}
 
+		if status == models.OrderStatusShipped {
+			if err := markOrderShipped(tx, id); err != nil {
+				return err
+			}
+		}
+
 		// Add timeline event
 		description := fmt.Sprintf("Order status changed to %s", string(status))
 		if notes != "" {
@@ -478,7 +561,7 @@ func (r *orderRepository) UpdatePaymentStatus(id uuid.UUID, status models.Paymen
 		}
 
 		// Update payment status on the order itself
-		if err := tx.Model(&models.Order{}).Where("id = ? AND tenant_id = ?", id, tenantID).Update("payment_status", status).Error; err != nil {
+		if err := tx.Model(&models.Order{}).Where("id = ? AND tenant_id = ?", id, tenantID).Updates(map[string]interface{}{"payment_status": status, "version": gorm.Expr("version + 1")}).Error; err != nil {
 			return fmt.Errorf("failed to update order payment status: %w", err)
 		}
 
@@ -535,10 +618,31 @@ func (r *orderRepository) UpdateFulfillmentStatus(id uuid.UUID, status models.Fu
 		}
 
 		// Update fulfillment status on the order
-		if err := tx.Model(&models.Order{}).Where("id = ? AND tenant_id = ?", id, tenantID).Update("fulfillment_status", status).Error; err != nil {
+		if err := tx.Model(&models.Order{}).Where("id = ? AND tenant_id = ?", id, tenantID).Updates(map[string]interface{}{"fulfillment_status": status, "version": gorm.Expr("version + 1")}).Error; err != nil {
 			return fmt.Errorf("failed to update fulfillment status: %w", err)
 		}
 
+		// Record click-and-collect readiness and collection times
+		pickupColumn := ""
+		switch status {
+		case models.FulfillmentStatusReadyForPickup:
+			pickupColumn = "ready_at"
+		case models.FulfillmentStatusPickedUp:
+			pickupColumn = "picked_up_at"
+		}
+		if pickupColumn != "" {
+			if err := tx.Model(&models.OrderPickup{}).Where("order_id = ?", id).Updates(map[string]interface{}{pickupColumn: time.Now(), "updated_at": time.Now()}).Error; err != nil {
+				return fmt.Errorf("failed to update pickup details: %w", err)
+			}
+		}
+
+		switch status {
+		case models.FulfillmentStatusDispatched, models.FulfillmentStatusInTransit:
+			if err := markOrderShipped(tx, id); err != nil {
+				return err
+			}
+		}
+
 		// Add timeline event
 		description := fmt.Sprintf("Fulfillment status changed to %s", status.DisplayName())
 		if notes != "" {
@@ -567,6 +671,15 @@ func (r *orderRepository) UpdateFulfillmentStatus(id uuid.UUID, status models.Fu
 	return err
 }
 
+// markOrderShipped records when an order was first dispatched; later transit updates keep the original time
+func markOrderShipped(tx *gorm.DB, orderID uuid.UUID) error {
+	now := time.Now()
+	if err := tx.Model(&models.OrderShipping{}).Where("order_id = ? AND shipped_at IS NULL", orderID).Updates(map[string]interface{}{"shipped_at": now, "updated_at": now}).Error; err != nil {
+		return fmt.Errorf("failed to record shipped time: %w", err)
+	}
+	return nil
+}
+
 // UpdateShippingTracking updates the shipping tracking information for an order
 func (r *orderRepository) UpdateShippingTracking(id uuid.UUID, carrier string, trackingNumber string, trackingUrl string, tenantID string) error {
 	err := r.db.Transaction(func(tx *gorm.DB) error {
@@ -617,7 +730,7 @@ func (r *orderRepository) UpdateShippingTracking(id uuid.UUID, carrier string, t
 func (r *orderRepository) UpdateCustomerID(id uuid.UUID, customerID uuid.UUID, tenantID string) error {
 	err := r.db.Model(&models.Order{}).
 		Where("id = ? AND tenant_id = ?", id, tenantID).
-		Update("customer_id", customerID).Error
+		Updates(map[string]interface{}{"customer_id": customerID, "version": gorm.Expr("version + 1")}).Error
 	if err != nil {
 		return fmt.Errorf("failed to update customer ID: %w", err)
 	}
@@ -708,6 +821,7 @@ func (r *orderRepository) UpdateTotals(orderID uuid.UUID, subtotal, taxAmount, t
 			"tax_amount": taxAmount,
 			"total":      total,
 			"is_split":   true,
+			"version":    gorm.Expr("version + 1"),
 		}).Error; err != nil {
 		return fmt.Errorf("failed to update order totals: %w", err)
 	}
@@ -715,6 +829,277 @@ func (r *orderRepository) UpdateTotals(orderID uuid.UUID, subtotal, taxAmount, t
 	return nil
 }
 
+// ApplyRevision saves an edited order's items and totals, as long as nobody else changed the
+// order since it was read, along with the revision and a timeline event
+func (r *orderRepository) ApplyRevision(order *models.Order, removedItemIDs []uuid.UUID, revision *models.OrderRevision) error {
+	expected := order.Version
+	order.Version = expected + 1
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Order{}).
+			Where("id = ? AND tenant_id = ? AND version = ?", order.ID, order.TenantID, expected).
+			Updates(map[string]interface{}{
+				"subtotal":        order.Subtotal,
+				"tax_amount":      order.TaxAmount,
+				"discount_amount": order.DiscountAmount,
+				"total":           order.Total,
+				"vendor_id":       order.VendorID,
+				"version":         order.Version,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update order: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			var current models.Order
+			if err := tx.Select("version").
+				Where("id = ? AND tenant_id = ?", order.ID, order.TenantID).
+				First(&current).Error; err != nil {
+				return fmt.Errorf("failed to update order: %w", err)
+			}
+			return &models.VersionConflictError{CurrentVersion: current.Version}
+		}
+
+		if len(removedItemIDs) > 0 {
+			if err := tx.Where("order_id = ? AND id IN ?", order.ID, removedItemIDs).
+				Delete(&models.OrderItem{}).Error; err != nil {
+				return fmt.Errorf("failed to remove order items: %w", err)
+			}
+		}
+		for i := range order.Items {
+			if err := tx.Save(&order.Items[i]).Error; err != nil {
+				return fmt.Errorf("failed to save order item: %w", err)
+			}
+		}
+		for _, discount := range order.Discounts {
+			if err := tx.Model(&models.OrderDiscount{}).
+				Where("id = ?", discount.ID).
+				Update("amount", discount.Amount).Error; err != nil {
+				return fmt.Errorf("failed to update order discount: %w", err)
+			}
+		}
+
+		var latest int
+		if err := tx.Model(&models.OrderRevision{}).
+			Where("order_id = ?", order.ID).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&latest).Error; err != nil {
+			return fmt.Errorf("failed to number order revision: %w", err)
+		}
+		revision.Revision = latest + 1
+		if err := tx.Create(revision).Error; err != nil {
+			return fmt.Errorf("failed to create order revision: %w", err)
+		}
+
+		timeline := models.OrderTimeline{
+			OrderID:     order.ID,
+			Event:       "ORDER_EDITED",
+			Description: fmt.Sprintf("Order edited (revision %d): total %.2f -> %.2f", revision.Revision, revision.PreviousTotal, revision.NewTotal),
+			Timestamp:   time.Now(),
+			CreatedBy:   "system",
+		}
+		if revision.EditedBy != nil {
+			timeline.CreatedBy = revision.EditedBy.String()
+		}
+		if err := tx.Create(&timeline).Error; err != nil {
+			return fmt.Errorf("failed to create timeline event: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		order.Version = expected
+	}
+	r.invalidateOrderCaches(context.Background(), order.TenantID, order.ID, order.OrderNumber)
+
+	return err
+}
+
+// UpdateRevisionPayment records how an order edit's charge or refund went
+func (r *orderRepository) UpdateRevisionPayment(revision *models.OrderRevision) error {
+	if err := r.db.Model(revision).
+		Select("payment_action", "payment_status", "payment_reference", "payment_intent", "payment_error", "customer_notified").
+		Updates(revision).Error; err != nil {
+		return fmt.Errorf("failed to update order revision: %w", err)
+	}
+	return nil
+}
+
+// ListRevisions retrieves an order's edits, oldest first
+func (r *orderRepository) ListRevisions(orderID uuid.UUID, tenantID string) ([]models.OrderRevision, error) {
+	var revisions []models.OrderRevision
+	if err := r.db.Where("order_id = ? AND tenant_id = ?", orderID, tenantID).
+		Order("revision ASC").
+		Find(&revisions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list order revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// CreateFulfillment saves a new fulfillment with the next number for the order and the order's
+// updated fulfilled quantities, as long as nobody else changed the order since it was read
+func (r *orderRepository) CreateFulfillment(order *models.Order, fulfillment *models.OrderFulfillment) error {
+	expected := order.Version
+	order.Version = expected + 1
+
+	inFulfillment := make(map[uuid.UUID]bool, len(fulfillment.Items))
+	for _, item := range fulfillment.Items {
+		inFulfillment[item.OrderItemID] = true
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Order{}).
+			Where("id = ? AND tenant_id = ? AND version = ?", order.ID, order.TenantID, expected).
+			Update("version", order.Version)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update order: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			var current models.Order
+			if err := tx.Select("version").
+				Where("id = ? AND tenant_id = ?", order.ID, order.TenantID).
+				First(&current).Error; err != nil {
+				return fmt.Errorf("failed to update order: %w", err)
+			}
+			return &models.VersionConflictError{CurrentVersion: current.Version}
+		}
+
+		for _, item := range order.Items {
+			if !inFulfillment[item.ID] {
+				continue
+			}
+			if err := tx.Model(&models.OrderItem{}).Where("id = ? AND order_id = ?", item.ID, order.ID).
+				Updates(map[string]interface{}{
+					"fulfilled_quantity": item.FulfilledQuantity,
+					"fulfillment_status": item.FulfillmentStatus,
+				}).Error; err != nil {
+				return fmt.Errorf("failed to update order item: %w", err)
+			}
+		}
+
+		var latest int
+		if err := tx.Model(&models.OrderFulfillment{}).
+			Where("order_id = ?", order.ID).
+			Select("COALESCE(MAX(number), 0)").
+			Scan(&latest).Error; err != nil {
+			return fmt.Errorf("failed to number fulfillment: %w", err)
+		}
+		fulfillment.Number = latest + 1
+		if err := tx.Create(fulfillment).Error; err != nil {
+			return fmt.Errorf("failed to create fulfillment: %w", err)
+		}
+
+		if fulfillment.ShippedAt != nil {
+			if err := markOrderShipped(tx, order.ID); err != nil {
+				return err
+			}
+		}
+
+		description := fmt.Sprintf("Fulfillment #%d created (%s)", fulfillment.Number, fulfillment.Status.DisplayName())
+		if fulfillment.TrackingNumber != "" {
+			description += fmt.Sprintf(" with tracking %s", fulfillment.TrackingNumber)
+		}
+		timeline := models.OrderTimeline{
+			OrderID:     order.ID,
+			Event:       "FULFILLMENT_CREATED",
+			Description: description,
+			Timestamp:   time.Now(),
+			CreatedBy:   "system",
+		}
+		if fulfillment.CreatedBy != nil {
+			timeline.CreatedBy = fulfillment.CreatedBy.String()
+		}
+		if err := tx.Create(&timeline).Error; err != nil {
+			return fmt.Errorf("failed to create timeline event: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		order.Version = expected
+	}
+	r.invalidateOrderCaches(context.Background(), order.TenantID, order.ID, order.OrderNumber)
+
+	return err
+}
+
+// GetFulfillment retrieves one of an order's fulfillments with its items
+func (r *orderRepository) GetFulfillment(id, orderID uuid.UUID, tenantID string) (*models.OrderFulfillment, error) {
+	var fulfillment models.OrderFulfillment
+	err := r.db.Preload("Items").
+		Where("id = ? AND order_id = ? AND tenant_id = ?", id, orderID, tenantID).
+		First(&fulfillment).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("fulfillment with ID %s not found", id.String())
+		}
+		return nil, fmt.Errorf("failed to get fulfillment: %w", err)
+	}
+	return &fulfillment, nil
+}
+
+// ListFulfillments retrieves an order's fulfillments with their items, in the order they were created
+func (r *orderRepository) ListFulfillments(orderID uuid.UUID, tenantID string) ([]models.OrderFulfillment, error) {
+	var fulfillments []models.OrderFulfillment
+	if err := r.db.Preload("Items").
+		Where("order_id = ? AND tenant_id = ?", orderID, tenantID).
+		Order("number ASC").
+		Find(&fulfillments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list fulfillments: %w", err)
+	}
+	return fulfillments, nil
+}
+
+// UpdateFulfillment saves a fulfillment's status and tracking and records the change on the
+// order's timeline
+func (r *orderRepository) UpdateFulfillment(fulfillment *models.OrderFulfillment, notes string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(fulfillment).
+			Select("status", "carrier", "tracking_number", "tracking_url", "shipped_at", "delivered_at", "updated_at").
+			Updates(fulfillment).Error; err != nil {
+			return fmt.Errorf("failed to update fulfillment: %w", err)
+		}
+
+		if fulfillment.ShippedAt != nil {
+			if err := markOrderShipped(tx, fulfillment.OrderID); err != nil {
+				return err
+			}
+		}
+
+		description := fmt.Sprintf("Fulfillment #%d changed to %s", fulfillment.Number, fulfillment.Status.DisplayName())
+		if notes != "" {
+			description += fmt.Sprintf(". Notes: %s", notes)
+		}
+		timeline := models.OrderTimeline{
+			OrderID:     fulfillment.OrderID,
+			Event:       "FULFILLMENT_UPDATED",
+			Description: description,
+			Timestamp:   time.Now(),
+			CreatedBy:   "admin",
+		}
+		if err := tx.Create(&timeline).Error; err != nil {
+			return fmt.Errorf("failed to create timeline event: %w", err)
+		}
+		return nil
+	})
+
+	r.invalidateOrderCaches(context.Background(), fulfillment.TenantID, fulfillment.OrderID, "")
+
+	return err
+}
+
+// HasFulfillments reports whether any of an order's items have been put in a fulfillment
+func (r *orderRepository) HasFulfillments(orderID uuid.UUID, tenantID string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.OrderFulfillment{}).
+		Where("order_id = ? AND tenant_id = ?", orderID, tenantID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check fulfillments: %w", err)
+	}
+	return count > 0, nil
+}
+
 // CreateSplit creates an order split record
 func (r *orderRepository) CreateSplit(split *models.OrderSplit) error {
 	if err := r.db.Create(split).Error; err != nil {
@@ -724,12 +1109,60 @@ func (r *orderRepository) CreateSplit(split *models.OrderSplit) error {
 	return nil
 }
 
+// CreateVendorSplit saves the vendor orders of a marketplace checkout and their split records,
+// and marks the parent as split, in one transaction so a failed split can simply be retried
+func (r *orderRepository) CreateVendorSplit(parent *models.Order, vendorOrders []models.Order, splits []models.OrderSplit) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range vendorOrders {
+			if err := tx.Create(&vendorOrders[i]).Error; err != nil {
+				return fmt.Errorf("failed to create vendor order: %w", err)
+			}
+
+			timeline := models.OrderTimeline{
+				OrderID:     vendorOrders[i].ID,
+				Event:       "ORDER_CREATED_FROM_SPLIT",
+				Description: fmt.Sprintf("Created from vendor split of %s", parent.OrderNumber),
+				Timestamp:   time.Now(),
+				CreatedBy:   "system",
+			}
+			if err := tx.Create(&timeline).Error; err != nil {
+				return fmt.Errorf("failed to create timeline event: %w", err)
+			}
+		}
+
+		for i := range splits {
+			if err := tx.Create(&splits[i]).Error; err != nil {
+				return fmt.Errorf("failed to create order split record: %w", err)
+			}
+		}
+
+		if err := tx.Model(&models.Order{}).
+			Where("id = ? AND tenant_id = ?", parent.ID, parent.TenantID).
+			Updates(map[string]interface{}{
+				"is_split":     true,
+				"split_reason": string(models.SplitTypeVendor),
+				"version":      gorm.Expr("version + 1"),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to mark order as split: %w", err)
+		}
+
+		return nil
+	})
+
+	if err == nil {
+		r.invalidateOrderCaches(context.Background(), parent.TenantID, parent.ID, parent.OrderNumber)
+	}
+
+	return err
+}
+
 // GetChildOrders retrieves all child orders for a parent order
 func (r *orderRepository) GetChildOrders(parentOrderID uuid.UUID, tenantID string) ([]models.Order, error) {
 	var orders []models.Order
 	err := r.db.Preload("Items").
 		Preload("Customer").
 		Preload("Shipping").
+		Preload("Pickup").
 		Preload("Payment").
 		Where("parent_order_id = ? AND tenant_id = ?", parentOrderID, tenantID).
 		Find(&orders).Error
@@ -740,3 +1173,109 @@ func (r *orderRepository) GetChildOrders(parentOrderID uuid.UUID, tenantID strin
 
 	return orders, nil
 }
+
+// ListUnpaidOrderIDs returns orders still awaiting payment that were placed before the cutoff, oldest first
+func (r *orderRepository) ListUnpaidOrderIDs(tenantID string, placedBefore time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.Order{}).
+		Where("tenant_id = ? AND status = ? AND payment_status IN ? AND created_at < ?",
+			tenantID, models.OrderStatusPlaced, []models.PaymentStatus{models.PaymentStatusPending, models.PaymentStatusFailed}, placedBefore).
+		Where("NOT (parent_order_id IS NOT NULL AND split_reason = ?)", models.SplitTypeVendor). // Expired with their parent checkout
+		Where("import_job_id IS NULL").                                                          // Imported orders are never auto-cancelled
+		Order("created_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unpaid orders: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ListAwaitingFulfillmentOrderIDs returns confirmed orders paid before the cutoff whose fulfillment hasn't started, oldest first
+func (r *orderRepository) ListAwaitingFulfillmentOrderIDs(tenantID string, paidBefore time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.Order{}).
+		Joins("JOIN order_payments ON order_payments.order_id = orders.id").
+		Where("orders.tenant_id = ? AND orders.status = ? AND orders.payment_status = ? AND orders.fulfillment_status = ?",
+			tenantID, models.OrderStatusConfirmed, models.PaymentStatusPaid, models.FulfillmentStatusUnfulfilled).
+		Where("order_payments.processed_at < ?", paidBefore).
+		Where("NOT (orders.parent_order_id IS NULL AND orders.split_reason = ?)", models.SplitTypeVendor). // Fulfilled through their vendor orders
+		Where("orders.import_job_id IS NULL").                                                             // Fulfilled outside the platform
+		Order("order_payments.processed_at ASC").
+		Limit(limit).
+		Pluck("orders.id", &ids).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders awaiting fulfillment: %w", err)
+	}
+
+	return ids, nil
+}
+
+// AnonymizeCustomer replaces the contact details and street address on a deleted customer's
+// orders, including guest orders placed with their email. Amounts, items, tax details and the
+// city, state and country of the shipping address are kept for the tenant's records.
+func (r *orderRepository) AnonymizeCustomer(tenantID string, customerID uuid.UUID, email, anonymizedEmail string) (int, error) {
+	var orders []models.Order
+	query := r.db.Unscoped().Select("id", "order_number").Where("tenant_id = ?", tenantID)
+	if email != "" {
+		query = query.Where("customer_id = ? OR id IN (?)", customerID,
+			r.db.Model(&models.OrderCustomer{}).Select("order_id").Where("LOWER(email) = LOWER(?)", email))
+	} else {
+		query = query.Where("customer_id = ?", customerID)
+	}
+	if err := query.Find(&orders).Error; err != nil {
+		return 0, fmt.Errorf("failed to find customer orders: %w", err)
+	}
+	if len(orders) == 0 {
+		return 0, nil
+	}
+
+	orderIDs := make([]uuid.UUID, len(orders))
+	for i, order := range orders {
+		orderIDs[i] = order.ID
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.OrderCustomer{}).
+			Where("order_id IN ?", orderIDs).
+			Updates(map[string]interface{}{
+				"first_name": "Deleted",
+				"last_name":  "Customer",
+				"email":      anonymizedEmail,
+				"phone":      "",
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.OrderShipping{}).
+			Where("order_id IN ?", orderIDs).
+			Updates(map[string]interface{}{
+				"street":      "",
+				"postal_code": "",
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ReceiptDocument{}).
+			Where("tenant_id = ? AND order_id IN ?", tenantID, orderIDs).
+			Update("customer_email", anonymizedEmail).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.Order{}).
+			Where("tenant_id = ? AND id IN ?", tenantID, orderIDs).
+			Updates(map[string]interface{}{
+				"customer_gstin":      "",
+				"customer_vat_number": "",
+				"version":             gorm.Expr("version + 1"),
+			}).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize customer orders: %w", err)
+	}
+
+	for _, order := range orders {
+		r.invalidateOrderCaches(context.Background(), tenantID, order.ID, order.OrderNumber)
+	}
+	return len(orders), nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"orders-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReportScheduleRepository handles database operations for scheduled reports and their delivery history
+type ReportScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewReportScheduleRepository creates a new repository instance
+func NewReportScheduleRepository(db *gorm.DB) *ReportScheduleRepository {
+	return &ReportScheduleRepository{db: db}
+}
+
+// Create inserts a new report schedule
+func (r *ReportScheduleRepository) Create(ctx context.Context, schedule *models.ReportSchedule) error {
+	if err := r.db.WithContext(ctx).Create(schedule).Error; err != nil {
+		return fmt.Errorf("failed to create report schedule: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a tenant's report schedule, or nil if it doesn't exist
+func (r *ReportScheduleRepository) GetByID(ctx context.Context, tenantID string, id uuid.UUID) (*models.ReportSchedule, error) {
+	var schedule models.ReportSchedule
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		First(&schedule).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get report schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// List returns a tenant's report schedules, newest first
+func (r *ReportScheduleRepository) List(ctx context.Context, tenantID string) ([]models.ReportSchedule, error) {
+	var schedules []models.ReportSchedule
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("created_at DESC").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// Update saves a report schedule
+func (r *ReportScheduleRepository) Update(ctx context.Context, schedule *models.ReportSchedule) error {
+	if err := r.db.WithContext(ctx).Save(schedule).Error; err != nil {
+		return fmt.Errorf("failed to update report schedule: %w", err)
+	}
+	return nil
+}
+
+// Delete soft-deletes a tenant's report schedule
+func (r *ReportScheduleRepository) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		Delete(&models.ReportSchedule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete report schedule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListDue returns active schedules whose next run is at or before now, across all tenants
+func (r *ReportScheduleRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.ReportSchedule, error) {
+	var schedules []models.ReportSchedule
+	err := r.db.WithContext(ctx).
+		Where("is_active = ? AND next_run_at <= ?", true, now).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&schedules).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due report schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// ClaimRun advances a due schedule to its next run time. It only succeeds for the replica that
+// still sees the old next_run_at, so each run is sent once.
+func (r *ReportScheduleRepository) ClaimRun(ctx context.Context, schedule *models.ReportSchedule, nextRunAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.ReportSchedule{}).
+		Where("id = ? AND next_run_at = ?", schedule.ID, schedule.NextRunAt).
+		Update("next_run_at", nextRunAt)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim report schedule: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RecordRun stores the outcome of a schedule's latest run
+func (r *ReportScheduleRepository) RecordRun(ctx context.Context, id uuid.UUID, ranAt time.Time, status models.ReportDeliveryStatus) error {
+	return r.db.WithContext(ctx).Model(&models.ReportSchedule{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_run_at": ranAt, "last_status": status}).Error
+}
+
+// CreateDelivery records one run of a schedule
+func (r *ReportScheduleRepository) CreateDelivery(ctx context.Context, delivery *models.ReportDelivery) error {
+	if err := r.db.WithContext(ctx).Create(delivery).Error; err != nil {
+		return fmt.Errorf("failed to create report delivery: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns a schedule's most recent deliveries
+func (r *ReportScheduleRepository) ListDeliveries(ctx context.Context, tenantID string, scheduleID uuid.UUID, limit int) ([]models.ReportDelivery, error) {
+	var deliveries []models.ReportDelivery
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND schedule_id = ?", tenantID, scheduleID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetDelivery retrieves a tenant's report delivery, or nil if it doesn't exist
+func (r *ReportScheduleRepository) GetDelivery(ctx context.Context, tenantID string, id uuid.UUID) (*models.ReportDelivery, error) {
+	var delivery models.ReportDelivery
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		First(&delivery).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get report delivery: %w", err)
+	}
+	return &delivery, nil
+}
@@ -60,6 +60,28 @@ func (r *ReturnRepository) GetReturnByID(id uuid.UUID) (*models.Return, error) {
 	return &ret, nil
 }
 
+// GetReturnByIntakeCode retrieves a tenant's return by its drop-off intake code
+func (r *ReturnRepository) GetReturnByIntakeCode(tenantID, code string) (*models.Return, error) {
+	var ret models.Return
+	err := r.db.
+		Preload("Items").
+		Preload("Timeline").
+		Preload("Order").
+		Preload("Order.Items").
+		Preload("Order.Customer").
+		Where("tenant_id = ?", tenantID).
+		First(&ret, "intake_code = ?", code).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("return not found")
+		}
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
 // GetReturnByRMANumber retrieves a return by RMA number
 func (r *ReturnRepository) GetReturnByRMANumber(rmaNumber string) (*models.Return, error) {
 	var ret models.Return
@@ -157,6 +179,42 @@ func (r *ReturnRepository) UpdateReturnStatus(returnID uuid.UUID, status models.
 	})
 }
 
+// ReceiveReturn marks a return as received if it is still awaiting its items.
+// It reports false when another request already moved the return on.
+func (r *ReturnRepository) ReceiveReturn(returnID uuid.UUID, message string, userID *uuid.UUID) (bool, error) {
+	received := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Return{}).
+			Where("id = ? AND status IN ?", returnID, []models.ReturnStatus{
+				models.ReturnStatusApproved,
+				models.ReturnStatusInTransit,
+			}).
+			Update("status", models.ReturnStatusReceived)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update return status: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		received = true
+
+		// Create timeline entry
+		timeline := models.ReturnTimeline{
+			ReturnID:  returnID,
+			Status:    models.ReturnStatusReceived,
+			Message:   message,
+			CreatedBy: userID,
+			CreatedAt: time.Now(),
+		}
+		if err := tx.Create(&timeline).Error; err != nil {
+			return fmt.Errorf("failed to create timeline entry: %w", err)
+		}
+
+		return nil
+	})
+	return received, err
+}
+
 // ApproveReturn approves a return request
 func (r *ReturnRepository) ApproveReturn(returnID, approvedBy uuid.UUID, notes string) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
@@ -168,6 +226,7 @@ func (r *ReturnRepository) ApproveReturn(returnID, approvedBy uuid.UUID, notes s
 			"approved_by": approvedBy,
 			"approved_at": now,
 			"admin_notes": notes,
+			"intake_code": models.NewReturnIntakeCode(),
 		}
 
 		if err := tx.Model(&models.Return{}).
@@ -193,6 +252,37 @@ func (r *ReturnRepository) ApproveReturn(returnID, approvedBy uuid.UUID, notes s
 	})
 }
 
+// SetReturnLabel stores the return shipping label booked for an approved return and records it
+// on the timeline
+func (r *ReturnRepository) SetReturnLabel(returnID uuid.UUID, shipmentID, carrier, trackingNumber, labelURL string, createdBy *uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"return_shipment_id":        shipmentID,
+			"return_carrier":            carrier,
+			"return_tracking_number":    trackingNumber,
+			"return_shipping_label_url": labelURL,
+		}
+		if err := tx.Model(&models.Return{}).
+			Where("id = ?", returnID).
+			Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to save return label: %w", err)
+		}
+
+		timeline := models.ReturnTimeline{
+			ReturnID:  returnID,
+			Status:    models.ReturnStatusApproved,
+			Message:   fmt.Sprintf("Return shipping label created - Carrier: %s, Tracking: %s", carrier, trackingNumber),
+			CreatedBy: createdBy,
+			CreatedAt: time.Now(),
+		}
+		if err := tx.Create(&timeline).Error; err != nil {
+			return fmt.Errorf("failed to create timeline entry: %w", err)
+		}
+
+		return nil
+	})
+}
+
 // RejectReturn rejects a return request
 func (r *ReturnRepository) RejectReturn(returnID, rejectedBy uuid.UUID, reason string) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
@@ -230,18 +320,21 @@ func (r *ReturnRepository) RejectReturn(returnID, rejectedBy uuid.UUID, reason s
 }
 
 // CompleteReturn marks return as completed and processes refund
-func (r *ReturnRepository) CompleteReturn(returnID, processedBy uuid.UUID, refundAmount float64, refundMethod models.RefundMethod) error {
+func (r *ReturnRepository) CompleteReturn(returnID, processedBy uuid.UUID, refundMethod models.RefundMethod, breakdown *models.RefundBreakdown, timeline []models.ReturnTimeline) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		now := time.Now()
 
 		// Update return
 		updates := map[string]interface{}{
-			"status":              models.ReturnStatusCompleted,
-			"refund_amount":       refundAmount,
-			"refund_method":       refundMethod,
-			"refund_processed_at": now,
-			"inspected_by":        processedBy,
-			"inspected_at":        now,
+			"status":               models.ReturnStatusCompleted,
+			"refund_amount":        breakdown.RefundTotal,
+			"refund_method":        refundMethod,
+			"refund_processed_at":  now,
+			"restocking_fee":       breakdown.RestockingFee,
+			"return_shipping_cost": breakdown.ReturnShippingDeduction,
+			"refund_breakdown":     breakdown,
+			"inspected_by":         processedBy,
+			"inspected_at":         now,
 		}
 
 		if err := tx.Model(&models.Return{}).
@@ -250,14 +343,14 @@ func (r *ReturnRepository) CompleteReturn(returnID, processedBy uuid.UUID, refun
 			return fmt.Errorf("failed to complete return: %w", err)
 		}
 
-		// Create timeline entry
-		timeline := models.ReturnTimeline{
+		// Create timeline entries
+		timeline = append(timeline, models.ReturnTimeline{
 			ReturnID:  returnID,
 			Status:    models.ReturnStatusCompleted,
-			Message:   fmt.Sprintf("Return completed. Refund of $%.2f processed via %s", refundAmount, refundMethod),
+			Message:   fmt.Sprintf("Return completed. Refund of $%.2f processed via %s", breakdown.RefundTotal, refundMethod),
 			CreatedBy: &processedBy,
 			CreatedAt: now,
-		}
+		})
 		if err := tx.Create(&timeline).Error; err != nil {
 			return fmt.Errorf("failed to create timeline entry: %w", err)
 		}
@@ -266,6 +359,26 @@ func (r *ReturnRepository) CompleteReturn(returnID, processedBy uuid.UUID, refun
 	})
 }
 
+// RecordRefundAttempt saves a partly failed refund's breakdown, so a retry only pays the
+// tenders that failed, along with timeline entries describing the attempt
+func (r *ReturnRepository) RecordRefundAttempt(returnID uuid.UUID, breakdown *models.RefundBreakdown, timeline []models.ReturnTimeline) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Return{}).
+			Where("id = ?", returnID).
+			Update("refund_breakdown", breakdown).Error; err != nil {
+			return fmt.Errorf("failed to save refund breakdown: %w", err)
+		}
+
+		if len(timeline) > 0 {
+			if err := tx.Create(&timeline).Error; err != nil {
+				return fmt.Errorf("failed to create timeline entry: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
 // CancelReturn cancels a return request
 func (r *ReturnRepository) CancelReturn(returnID uuid.UUID, userID *uuid.UUID, reason string) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
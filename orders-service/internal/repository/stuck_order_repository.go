@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"orders-service/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StuckOrderRepository handles database operations for the stuck order watchdog
+type StuckOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewStuckOrderRepository creates a new repository instance
+func NewStuckOrderRepository(db *gorm.DB) *StuckOrderRepository {
+	return &StuckOrderRepository{db: db}
+}
+
+// StuckOrderFilters filters the ops list of stuck order alerts
+type StuckOrderFilters struct {
+	Reason   models.StuckOrderReason
+	Resolved *bool // nil returns open and resolved alerts
+	Page     int
+	Limit    int
+}
+
+var (
+	paidUnfulfilledStatuses     = []models.OrderStatus{models.OrderStatusConfirmed, models.OrderStatusProcessing}
+	paidUnfulfilledFulfillments = []models.FulfillmentStatus{models.FulfillmentStatusUnfulfilled, models.FulfillmentStatusProcessing, models.FulfillmentStatusPacked}
+	inTransitFulfillments       = []models.FulfillmentStatus{models.FulfillmentStatusDispatched, models.FulfillmentStatusInTransit, models.FulfillmentStatusOutForDelivery}
+	finishedStatuses            = []models.OrderStatus{models.OrderStatusDelivered, models.OrderStatusCompleted, models.OrderStatusCancelled}
+	finishedFulfillments        = []models.FulfillmentStatus{models.FulfillmentStatusDelivered, models.FulfillmentStatusPickedUp, models.FulfillmentStatusReturned}
+)
+
+// ListPaidNotFulfilled returns paid orders whose fulfillment hasn't been dispatched since before
+// the cutoff and that have no open alert, oldest first
+func (r *StuckOrderRepository) ListPaidNotFulfilled(ctx context.Context, paidBefore time.Time, limit int) ([]models.StuckOrderCandidate, error) {
+	var candidates []models.StuckOrderCandidate
+	err := r.db.WithContext(ctx).Model(&models.Order{}).
+		Select("orders.id AS order_id, orders.tenant_id, orders.order_number, orders.status, orders.fulfillment_status, order_payments.processed_at AS stuck_since").
+		Joins("JOIN order_payments ON order_payments.order_id = orders.id").
+		Where("orders.status IN ? AND orders.payment_status = ? AND orders.fulfillment_status IN ?",
+			paidUnfulfilledStatuses, models.PaymentStatusPaid, paidUnfulfilledFulfillments).
+		Where("order_payments.processed_at < ?", paidBefore).
+		Where("NOT (orders.parent_order_id IS NULL AND orders.split_reason = ?)", models.SplitTypeVendor). // Fulfilled through their vendor orders
+		Where("orders.import_job_id IS NULL").                                                             // Fulfilled outside the platform
+		Where("NOT EXISTS (SELECT 1 FROM stuck_order_alerts a WHERE a.order_id = orders.id AND a.reason = ? AND a.resolved_at IS NULL)", models.StuckOrderPaidNotFulfilled).
+		Order("order_payments.processed_at ASC").
+		Limit(limit).
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list paid unfulfilled orders: %w", err)
+	}
+	return candidates, nil
+}
+
+// ListShippedNotDelivered returns orders dispatched before the cutoff that were never delivered
+// and have no open alert, oldest first. Orders shipped before shipped_at was tracked fall back
+// to their last update time.
+func (r *StuckOrderRepository) ListShippedNotDelivered(ctx context.Context, shippedBefore time.Time, limit int) ([]models.StuckOrderCandidate, error) {
+	var candidates []models.StuckOrderCandidate
+	err := r.db.WithContext(ctx).Model(&models.Order{}).
+		Select("orders.id AS order_id, orders.tenant_id, orders.order_number, orders.status, orders.fulfillment_status, COALESCE(order_shippings.shipped_at, orders.updated_at) AS stuck_since").
+		Joins("LEFT JOIN order_shippings ON order_shippings.order_id = orders.id").
+		Where("(orders.status = ? OR orders.fulfillment_status IN ?)", models.OrderStatusShipped, inTransitFulfillments).
+		Where("orders.status NOT IN ? AND orders.fulfillment_status NOT IN ?", finishedStatuses, finishedFulfillments).
+		Where("COALESCE(order_shippings.shipped_at, orders.updated_at) < ?", shippedBefore).
+		Where("NOT (orders.parent_order_id IS NULL AND orders.split_reason = ?)", models.SplitTypeVendor).
+		Where("orders.import_job_id IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM stuck_order_alerts a WHERE a.order_id = orders.id AND a.reason = ? AND a.resolved_at IS NULL)", models.StuckOrderShippedNotDelivered).
+		Order("stuck_since ASC").
+		Limit(limit).
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shipped undelivered orders: %w", err)
+	}
+	return candidates, nil
+}
+
+// CreateAlert inserts an alert, reporting false when the order already has an open alert for the reason
+func (r *StuckOrderRepository) CreateAlert(ctx context.Context, alert *models.StuckOrderAlert) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(alert)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to create stuck order alert: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListPendingEscalations returns open alerts without a ticket that haven't used up their attempts
+func (r *StuckOrderRepository) ListPendingEscalations(ctx context.Context, maxAttempts, limit int) ([]models.StuckOrderAlert, error) {
+	var alerts []models.StuckOrderAlert
+	err := r.db.WithContext(ctx).
+		Where("resolved_at IS NULL AND (ticket_id IS NULL OR ticket_id = '') AND escalation_attempts < ?", maxAttempts).
+		Order("detected_at ASC").
+		Limit(limit).
+		Find(&alerts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending stuck order escalations: %w", err)
+	}
+	return alerts, nil
+}
+
+// UpdateAlert saves an alert
+func (r *StuckOrderRepository) UpdateAlert(ctx context.Context, alert *models.StuckOrderAlert) error {
+	if err := r.db.WithContext(ctx).Save(alert).Error; err != nil {
+		return fmt.Errorf("failed to update stuck order alert: %w", err)
+	}
+	return nil
+}
+
+// ResolveRecovered closes open alerts whose orders have left the stuck state, returning how many closed
+func (r *StuckOrderRepository) ResolveRecovered(ctx context.Context, now time.Time) (int64, error) {
+	db := r.db.WithContext(ctx)
+
+	paid := db.Model(&models.StuckOrderAlert{}).
+		Where("resolved_at IS NULL AND reason = ?", models.StuckOrderPaidNotFulfilled).
+		Where("NOT EXISTS (?)", db.Model(&models.Order{}).Select("1").
+			Where("orders.id = stuck_order_alerts.order_id AND orders.status IN ? AND orders.payment_status = ? AND orders.fulfillment_status IN ?",
+				paidUnfulfilledStatuses, models.PaymentStatusPaid, paidUnfulfilledFulfillments)).
+		Updates(map[string]interface{}{"resolved_at": now, "updated_at": now})
+	if paid.Error != nil {
+		return 0, fmt.Errorf("failed to resolve recovered stuck order alerts: %w", paid.Error)
+	}
+
+	shipped := db.Model(&models.StuckOrderAlert{}).
+		Where("resolved_at IS NULL AND reason = ?", models.StuckOrderShippedNotDelivered).
+		Where("NOT EXISTS (?)", db.Model(&models.Order{}).Select("1").
+			Where("orders.id = stuck_order_alerts.order_id AND orders.status NOT IN ? AND orders.fulfillment_status NOT IN ?",
+				finishedStatuses, finishedFulfillments)).
+		Updates(map[string]interface{}{"resolved_at": now, "updated_at": now})
+	if shipped.Error != nil {
+		return 0, fmt.Errorf("failed to resolve recovered stuck order alerts: %w", shipped.Error)
+	}
+
+	return paid.RowsAffected + shipped.RowsAffected, nil
+}
+
+// List returns a tenant's stuck order alerts, oldest stuck first
+func (r *StuckOrderRepository) List(ctx context.Context, tenantID string, filters StuckOrderFilters) ([]models.StuckOrderAlert, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.StuckOrderAlert{}).Where("tenant_id = ?", tenantID)
+	if filters.Reason != "" {
+		query = query.Where("reason = ?", filters.Reason)
+	}
+	if filters.Resolved != nil {
+		if *filters.Resolved {
+			query = query.Where("resolved_at IS NOT NULL")
+		} else {
+			query = query.Where("resolved_at IS NULL")
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count stuck order alerts: %w", err)
+	}
+
+	var alerts []models.StuckOrderAlert
+	err := query.Order("stuck_since ASC").
+		Offset((filters.Page - 1) * filters.Limit).
+		Limit(filters.Limit).
+		Find(&alerts).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list stuck order alerts: %w", err)
+	}
+	return alerts, total, nil
+}
+
+// CountOpenByReason returns a tenant's open alert counts per reason
+func (r *StuckOrderRepository) CountOpenByReason(ctx context.Context, tenantID string) (map[models.StuckOrderReason]int64, error) {
+	var rows []struct {
+		Reason models.StuckOrderReason
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.StuckOrderAlert{}).
+		Select("reason, COUNT(*) AS count").
+		Where("tenant_id = ? AND resolved_at IS NULL", tenantID).
+		Group("reason").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open stuck order alerts: %w", err)
+	}
+
+	counts := map[models.StuckOrderReason]int64{
+		models.StuckOrderPaidNotFulfilled:    0,
+		models.StuckOrderShippedNotDelivered: 0,
+	}
+	for _, row := range rows {
+		counts[row.Reason] = row.Count
+	}
+	return counts, nil
+}
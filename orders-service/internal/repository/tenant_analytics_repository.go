@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"time"
+
+	"orders-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TenantAnalyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantAnalyticsRepository(db *gorm.DB) *TenantAnalyticsRepository {
+	return &TenantAnalyticsRepository{db: db}
+}
+
+// RecordEvent appends an event to the analytics ledger, ignoring duplicates
+func (r *TenantAnalyticsRepository) RecordEvent(event *models.AnalyticsEvent) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error
+}
+
+// RollupDay recomputes tenant_daily_metrics for every tenant with activity on the given (UTC) day
+func (r *TenantAnalyticsRepository) RollupDay(day time.Time) (int, error) {
+	start, end := utcDayBounds(day)
+
+	metrics := make(map[string]*models.TenantDailyMetric)
+	metricFor := func(tenantID string) *models.TenantDailyMetric {
+		if m, ok := metrics[tenantID]; ok {
+			return m
+		}
+		m := &models.TenantDailyMetric{TenantID: tenantID, StatDate: start}
+		metrics[tenantID] = m
+		return m
+	}
+
+	// A split marketplace checkout is counted once, as the parent; its vendor orders repeat it
+	var orderRows []struct {
+		TenantID       string
+		GrossSales     float64
+		OrderCount     int64
+		CancelledCount int64
+	}
+	err := r.db.Model(&models.Order{}).
+		Select(`tenant_id,
+			COALESCE(SUM(CASE WHEN status <> ? THEN total ELSE 0 END), 0) AS gross_sales,
+			COUNT(*) FILTER (WHERE status <> ?) AS order_count,
+			COUNT(*) FILTER (WHERE status = ?) AS cancelled_count`,
+			models.OrderStatusCancelled, models.OrderStatusCancelled, models.OrderStatusCancelled).
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Where("NOT (parent_order_id IS NOT NULL AND split_reason = ?)", models.SplitTypeVendor).
+		Group("tenant_id").
+		Scan(&orderRows).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range orderRows {
+		m := metricFor(row.TenantID)
+		m.GrossSales = row.GrossSales
+		m.OrderCount = row.OrderCount
+		m.CancelledCount = row.CancelledCount
+	}
+
+	var itemRows []struct {
+		TenantID  string
+		ItemsSold int64
+	}
+	err = r.db.Table("order_items oi").
+		Select("o.tenant_id, COALESCE(SUM(oi.quantity), 0) AS items_sold").
+		Joins("JOIN orders o ON o.id = oi.order_id AND o.deleted_at IS NULL").
+		Where("o.status <> ? AND o.created_at >= ? AND o.created_at < ?", models.OrderStatusCancelled, start, end).
+		Where("NOT (o.parent_order_id IS NOT NULL AND o.split_reason = ?)", models.SplitTypeVendor).
+		Group("o.tenant_id").
+		Scan(&itemRows).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range itemRows {
+		metricFor(row.TenantID).ItemsSold = row.ItemsSold
+	}
+
+	// Signals from other services, recorded by the analytics subscriber
+	var eventRows []struct {
+		TenantID string
+		Kind     models.AnalyticsEventKind
+		Count    int64
+		Amount   float64
+	}
+	err = r.db.Model(&models.AnalyticsEvent{}).
+		Select("tenant_id, kind, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+		Where("occurred_at >= ? AND occurred_at < ?", start, end).
+		Group("tenant_id, kind").
+		Scan(&eventRows).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range eventRows {
+		m := metricFor(row.TenantID)
+		switch row.Kind {
+		case models.AnalyticsEventPaymentCaptured:
+			m.CapturedAmount = row.Amount
+		case models.AnalyticsEventPaymentRefunded:
+			m.RefundCount = row.Count
+			m.RefundAmount = row.Amount
+		case models.AnalyticsEventCustomerAcquired:
+			m.NewCustomers = row.Count
+		case models.AnalyticsEventCartStarted:
+			m.CartsStarted = row.Count
+		}
+	}
+
+	if len(metrics) == 0 {
+		return 0, nil
+	}
+
+	rows := make([]models.TenantDailyMetric, 0, len(metrics))
+	for _, m := range metrics {
+		rows = append(rows, *m)
+	}
+
+	err = r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}, {Name: "stat_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"gross_sales", "order_count", "cancelled_count", "items_sold", "captured_amount",
+			"refund_count", "refund_amount", "new_customers", "carts_started", "updated_at",
+		}),
+	}).Create(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return len(rows), nil
+}
+
+// ListProductSales returns per-tenant product sales for the given (UTC) day, excluding cancelled orders
+func (r *TenantAnalyticsRepository) ListProductSales(day time.Time) ([]models.ProductDaySale, error) {
+	start, end := utcDayBounds(day)
+
+	var sales []models.ProductDaySale
+	err := r.db.Table("order_items oi").
+		Select("o.tenant_id, oi.product_id::text AS product_id, COALESCE(SUM(oi.total_price), 0) AS revenue, COALESCE(SUM(oi.quantity), 0) AS quantity").
+		Joins("JOIN orders o ON o.id = oi.order_id AND o.deleted_at IS NULL").
+		Where("o.status <> ? AND o.created_at >= ? AND o.created_at < ?", models.OrderStatusCancelled, start, end).
+		Where("NOT (o.parent_order_id IS NOT NULL AND o.split_reason = ?)", models.SplitTypeVendor).
+		Group("o.tenant_id, oi.product_id").
+		Scan(&sales).Error
+	return sales, err
+}
+
+// ReplaceCategorySales swaps a tenant's category rollup for one day
+func (r *TenantAnalyticsRepository) ReplaceCategorySales(tenantID string, day time.Time, rows []models.TenantDailyCategorySale) error {
+	start, _ := utcDayBounds(day)
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tenant_id = ? AND stat_date = ?", tenantID, start).
+			Delete(&models.TenantDailyCategorySale{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// GetDailyMetrics returns a tenant's rollups between from and to (inclusive dates)
+func (r *TenantAnalyticsRepository) GetDailyMetrics(tenantID string, from, to time.Time) ([]models.TenantDailyMetric, error) {
+	var metrics []models.TenantDailyMetric
+	err := r.db.
+		Where("tenant_id = ? AND stat_date >= ? AND stat_date <= ?", tenantID, from, to).
+		Order("stat_date ASC").
+		Find(&metrics).Error
+	return metrics, err
+}
+
+// GetTopCategories ranks a tenant's categories by revenue between from and to (inclusive dates)
+func (r *TenantAnalyticsRepository) GetTopCategories(tenantID string, from, to time.Time, limit int) ([]models.CategorySales, error) {
+	var categories []models.CategorySales
+	err := r.db.Model(&models.TenantDailyCategorySale{}).
+		Select("category_id, COALESCE(SUM(revenue), 0) AS revenue, COALESCE(SUM(quantity), 0) AS quantity").
+		Where("tenant_id = ? AND stat_date >= ? AND stat_date <= ?", tenantID, from, to).
+		Group("category_id").
+		Order("revenue DESC").
+		Limit(limit).
+		Scan(&categories).Error
+	return categories, err
+}
+
+// GetCategoryRevenueTotal returns total categorized revenue for share calculations
+func (r *TenantAnalyticsRepository) GetCategoryRevenueTotal(tenantID string, from, to time.Time) (float64, error) {
+	var total float64
+	err := r.db.Model(&models.TenantDailyCategorySale{}).
+		Select("COALESCE(SUM(revenue), 0)").
+		Where("tenant_id = ? AND stat_date >= ? AND stat_date <= ?", tenantID, from, to).
+		Scan(&total).Error
+	return total, err
+}
+
+// MarginTotals are the summed revenue, discounts and snapshotted costs of a period's orders
+type MarginTotals struct {
+	OrderCount    int64
+	ItemRevenue   float64
+	Discounts     float64
+	Cost          float64
+	UncostedItems int64
+}
+
+// marginOrders scopes to a tenant's uncancelled orders created between from and to
+// (inclusive dates), counting a split marketplace checkout once, as the parent
+func (r *TenantAnalyticsRepository) marginOrders(tenantID string, from, to time.Time) *gorm.DB {
+	return r.db.Model(&models.Order{}).
+		Where("orders.tenant_id = ? AND orders.status <> ?", tenantID, models.OrderStatusCancelled).
+		Where("orders.created_at >= ? AND orders.created_at < ?", from, to.AddDate(0, 0, 1)).
+		Where("NOT (orders.parent_order_id IS NOT NULL AND orders.split_reason = ?)", models.SplitTypeVendor)
+}
+
+// GetMarginTotals sums revenue and cost over a tenant's orders between from and to (inclusive dates)
+func (r *TenantAnalyticsRepository) GetMarginTotals(tenantID string, from, to time.Time) (*MarginTotals, error) {
+	totals := &MarginTotals{}
+
+	var orderRow struct {
+		OrderCount int64
+		Discounts  float64
+	}
+	err := r.marginOrders(tenantID, from, to).
+		Select("COUNT(*) AS order_count, COALESCE(SUM(orders.discount_amount), 0) AS discounts").
+		Scan(&orderRow).Error
+	if err != nil {
+		return nil, err
+	}
+	totals.OrderCount = orderRow.OrderCount
+	totals.Discounts = orderRow.Discounts
+
+	var itemRow struct {
+		ItemRevenue   float64
+		Cost          float64
+		UncostedItems int64
+	}
+	err = r.marginOrders(tenantID, from, to).
+		Joins("JOIN order_items oi ON oi.order_id = orders.id").
+		Select(`COALESCE(SUM(oi.total_price), 0) AS item_revenue,
+			COALESCE(SUM(oi.unit_cost * oi.quantity), 0) AS cost,
+			COUNT(*) FILTER (WHERE oi.unit_cost IS NULL) AS uncosted_items`).
+		Scan(&itemRow).Error
+	if err != nil {
+		return nil, err
+	}
+	totals.ItemRevenue = itemRow.ItemRevenue
+	totals.Cost = itemRow.Cost
+	totals.UncostedItems = itemRow.UncostedItems
+	return totals, nil
+}
+
+// ListMarginOrders returns a page of a tenant's orders between from and to (inclusive dates),
+// newest first, with their items
+func (r *TenantAnalyticsRepository) ListMarginOrders(tenantID string, from, to time.Time, page, limit int) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.marginOrders(tenantID, from, to).
+		Preload("Items").
+		Order("orders.created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+// GetOrderWithItems returns one of a tenant's orders with its items
+func (r *TenantAnalyticsRepository) GetOrderWithItems(tenantID string, orderID uuid.UUID) (*models.Order, error) {
+	var order models.Order
+	err := r.db.Preload("Items").
+		Where("tenant_id = ? AND id = ?", tenantID, orderID).
+		First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func utcDayBounds(day time.Time) (time.Time, time.Time) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
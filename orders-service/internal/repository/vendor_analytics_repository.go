@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"time"
+
+	"orders-service/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VendorAnalyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewVendorAnalyticsRepository(db *gorm.DB) *VendorAnalyticsRepository {
+	return &VendorAnalyticsRepository{db: db}
+}
+
+type vendorDayKey struct {
+	TenantID string
+	VendorID string
+}
+
+// RollupDay recomputes vendor_daily_stats for every tenant/vendor with
+// activity on the given (UTC) day. Re-running a day is idempotent.
+func (r *VendorAnalyticsRepository) RollupDay(day time.Time) (int, error) {
+	start, end := utcDayBounds(day)
+
+	stats := make(map[vendorDayKey]*models.VendorDailyStat)
+	statFor := func(tenantID, vendorID string) *models.VendorDailyStat {
+		key := vendorDayKey{TenantID: tenantID, VendorID: vendorID}
+		if s, ok := stats[key]; ok {
+			return s
+		}
+		s := &models.VendorDailyStat{TenantID: tenantID, VendorID: vendorID, StatDate: start}
+		stats[key] = s
+		return s
+	}
+
+	// Orders and GMV (cancelled orders are counted separately and excluded from GMV)
+	var orderRows []struct {
+		TenantID       string
+		VendorID       string
+		GMV            float64
+		OrderCount     int64
+		CancelledCount int64
+	}
+	err := r.db.Model(&models.Order{}).
+		Select(`tenant_id, vendor_id,
+			COALESCE(SUM(CASE WHEN status <> ? THEN total ELSE 0 END), 0) AS gmv,
+			COUNT(*) FILTER (WHERE status <> ?) AS order_count,
+			COUNT(*) FILTER (WHERE status = ?) AS cancelled_count`,
+			models.OrderStatusCancelled, models.OrderStatusCancelled, models.OrderStatusCancelled).
+		Where("vendor_id IS NOT NULL AND vendor_id <> '' AND created_at >= ? AND created_at < ?", start, end).
+		Group("tenant_id, vendor_id").
+		Scan(&orderRows).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range orderRows {
+		s := statFor(row.TenantID, row.VendorID)
+		s.GMV = row.GMV
+		s.OrderCount = row.OrderCount
+		s.CancelledCount = row.CancelledCount
+	}
+
+	// Units sold on non-cancelled orders
+	var itemRows []struct {
+		TenantID  string
+		VendorID  string
+		ItemsSold int64
+	}
+	err = r.db.Table("order_items oi").
+		Select("o.tenant_id, o.vendor_id, COALESCE(SUM(oi.quantity), 0) AS items_sold").
+		Joins("JOIN orders o ON o.id = oi.order_id AND o.deleted_at IS NULL").
+		Where("o.vendor_id IS NOT NULL AND o.vendor_id <> '' AND o.status <> ? AND o.created_at >= ? AND o.created_at < ?",
+			models.OrderStatusCancelled, start, end).
+		Group("o.tenant_id, o.vendor_id").
+		Scan(&itemRows).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range itemRows {
+		statFor(row.TenantID, row.VendorID).ItemsSold = row.ItemsSold
+	}
+
+	// Returns opened that day, attributed to the vendor of the original order
+	var returnRows []struct {
+		TenantID     string
+		VendorID     string
+		ReturnCount  int64
+		RefundAmount float64
+	}
+	err = r.db.Table("returns rt").
+		Select(`o.tenant_id, o.vendor_id, COUNT(*) AS return_count,
+			COALESCE(SUM(CASE WHEN rt.status = ? THEN rt.refund_amount ELSE 0 END), 0) AS refund_amount`,
+			models.ReturnStatusCompleted).
+		Joins("JOIN orders o ON o.id = rt.order_id").
+		Where("rt.deleted_at IS NULL AND o.vendor_id IS NOT NULL AND o.vendor_id <> '' AND rt.status <> ? AND rt.created_at >= ? AND rt.created_at < ?",
+			models.ReturnStatusCancelled, start, end).
+		Group("o.tenant_id, o.vendor_id").
+		Scan(&returnRows).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range returnRows {
+		s := statFor(row.TenantID, row.VendorID)
+		s.ReturnCount = row.ReturnCount
+		s.RefundAmount = row.RefundAmount
+	}
+
+	if len(stats) == 0 {
+		return 0, nil
+	}
+
+	rows := make([]models.VendorDailyStat, 0, len(stats))
+	for _, s := range stats {
+		rows = append(rows, *s)
+	}
+
+	err = r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}, {Name: "vendor_id"}, {Name: "stat_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"gmv", "order_count", "cancelled_count", "items_sold", "return_count", "refund_amount", "updated_at",
+		}),
+	}).Create(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return len(rows), nil
+}
+
+// GetDailyStats returns the rollups for a vendor between from and to (inclusive dates)
+func (r *VendorAnalyticsRepository) GetDailyStats(tenantID, vendorID string, from, to time.Time) ([]models.VendorDailyStat, error) {
+	var stats []models.VendorDailyStat
+	err := r.db.
+		Where("tenant_id = ? AND vendor_id = ? AND stat_date >= ? AND stat_date <= ?", tenantID, vendorID, from, to).
+		Order("stat_date ASC").
+		Find(&stats).Error
+	return stats, err
+}
+
+// GetPayoutTotals sums each vendor's rollups between from and to (inclusive dates); an empty
+// vendorID returns every vendor in the tenant
+func (r *VendorAnalyticsRepository) GetPayoutTotals(tenantID, vendorID string, from, to time.Time) ([]models.VendorPayoutTotal, error) {
+	var totals []models.VendorPayoutTotal
+	query := r.db.Model(&models.VendorDailyStat{}).
+		Select(`vendor_id, COALESCE(SUM(order_count), 0) AS order_count, COALESCE(SUM(gmv), 0) AS gmv,
+			COALESCE(SUM(refund_amount), 0) AS refund_amount`).
+		Where("tenant_id = ? AND stat_date >= ? AND stat_date <= ?", tenantID, from, to)
+	if vendorID != "" {
+		query = query.Where("vendor_id = ?", vendorID)
+	}
+	err := query.Group("vendor_id").Order("gmv DESC").Scan(&totals).Error
+	return totals, err
+}
+
+// GetTopProducts ranks the vendor's products by revenue over [from, to)
+func (r *VendorAnalyticsRepository) GetTopProducts(tenantID, vendorID string, from, to time.Time, limit int) ([]models.VendorTopProduct, error) {
+	var products []models.VendorTopProduct
+	err := r.db.Table("order_items oi").
+		Select(`oi.product_id, MAX(oi.product_name) AS product_name, MAX(oi.sku) AS sku,
+			COALESCE(SUM(oi.quantity), 0) AS quantity, COALESCE(SUM(oi.total_price), 0) AS revenue`).
+		Joins("JOIN orders o ON o.id = oi.order_id AND o.deleted_at IS NULL").
+		Where("o.tenant_id = ? AND o.vendor_id = ? AND o.status <> ? AND o.created_at >= ? AND o.created_at < ?",
+			tenantID, vendorID, models.OrderStatusCancelled, from, to).
+		Group("oi.product_id").
+		Order("revenue DESC").
+		Limit(limit).
+		Scan(&products).Error
+	return products, err
+}
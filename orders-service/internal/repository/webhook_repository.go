@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"orders-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookRepository handles database operations for webhook subscriptions and their delivery
+// outbox
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new repository instance
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Create inserts a new webhook subscription
+func (r *WebhookRepository) Create(ctx context.Context, subscription *models.WebhookSubscription) error {
+	if err := r.db.WithContext(ctx).Create(subscription).Error; err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a tenant's webhook subscription, or nil if it doesn't exist
+func (r *WebhookRepository) GetByID(ctx context.Context, tenantID string, id uuid.UUID) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		First(&subscription).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return &subscription, nil
+}
+
+// List returns a tenant's webhook subscriptions, newest first
+func (r *WebhookRepository) List(ctx context.Context, tenantID string) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("created_at DESC").
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// Update saves a webhook subscription
+func (r *WebhookRepository) Update(ctx context.Context, subscription *models.WebhookSubscription) error {
+	if err := r.db.WithContext(ctx).Save(subscription).Error; err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// Delete soft-deletes a tenant's webhook subscription. Deliveries still in the outbox are
+// failed when the dispatcher finds the subscription gone.
+func (r *WebhookRepository) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		Delete(&models.WebhookSubscription{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetSubscriptionForDelivery retrieves a subscription for the dispatcher, including deleted
+// ones so their deliveries can be closed out
+func (r *WebhookRepository) GetSubscriptionForDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := r.db.WithContext(ctx).Unscoped().First(&subscription, "id = ?", id).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return &subscription, nil
+}
+
+// GetOrderForDelivery loads an order with the details included in webhook payloads
+func (r *WebhookRepository) GetOrderForDelivery(ctx context.Context, tenantID string, orderID uuid.UUID) (*models.Order, error) {
+	var order models.Order
+	err := r.db.WithContext(ctx).Unscoped().
+		Preload("Items").
+		Preload("Customer").
+		Preload("Shipping").
+		Preload("Pickup").
+		Preload("Payment").
+		Preload("Discounts").
+		Where("tenant_id = ?", tenantID).
+		First(&order, "id = ?", orderID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return &order, nil
+}
+
+// ClaimDueDeliveries marks up to limit due deliveries as delivering until lockUntil and
+// returns them, oldest first. Deliveries whose claim expired are taken over. A delivery waits
+// while an older one for the same subscription and order is still outstanding, so each order's
+// events arrive in order.
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now, lockUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries SET status = ?, locked_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT d.id FROM webhook_deliveries d
+			WHERE ((d.status = ? AND d.next_attempt_at <= ?) OR (d.status = ? AND d.locked_until < ?))
+			  AND NOT EXISTS (
+				SELECT 1 FROM webhook_deliveries p
+				WHERE p.subscription_id = d.subscription_id
+				  AND p.order_id = d.order_id
+				  AND p.status IN (?, ?)
+				  AND p.created_at < d.created_at)
+			ORDER BY d.created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		models.WebhookDeliveryDelivering, lockUntil, now,
+		models.WebhookDeliveryPending, now, models.WebhookDeliveryDelivering, now,
+		models.WebhookDeliveryPending, models.WebhookDeliveryDelivering,
+		limit,
+	).Scan(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// SaveDeliveryAttempt records the outcome of an attempt and releases the claim
+func (r *WebhookRepository) SaveDeliveryAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.LockedUntil = nil
+	err := r.db.WithContext(ctx).Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "locked_until", "payload", "response_status",
+			"response_body", "error", "last_attempt_at", "delivered_at", "updated_at").
+		Updates(delivery).Error
+	if err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetDelivery retrieves one of a subscription's deliveries, or nil if it doesn't exist
+func (r *WebhookRepository) GetDelivery(ctx context.Context, tenantID string, subscriptionID, id uuid.UUID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND subscription_id = ? AND id = ?", tenantID, subscriptionID, id).
+		First(&delivery).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries returns a subscription's delivery log, newest first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, tenantID string, subscriptionID uuid.UUID, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("tenant_id = ? AND subscription_id = ?", tenantID, subscriptionID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+	if filter.OrderID != nil {
+		query = query.Where("order_id = ?", *filter.OrderID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	var deliveries []models.WebhookDelivery
+	err := query.Order("created_at DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// CreateReplay queues a new delivery of the same event, linked to the original, so the log
+// keeps every attempt
+func (r *WebhookRepository) CreateReplay(ctx context.Context, original *models.WebhookDelivery, now time.Time) (*models.WebhookDelivery, error) {
+	originalID := original.ID
+	replay := &models.WebhookDelivery{
+		TenantID:       original.TenantID,
+		SubscriptionID: original.SubscriptionID,
+		OrderID:        original.OrderID,
+		Event:          original.Event,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		ReplayOf:       &originalID,
+	}
+	if err := r.db.WithContext(ctx).Create(replay).Error; err != nil {
+		return nil, fmt.Errorf("failed to queue webhook replay: %w", err)
+	}
+	return replay, nil
+}
@@ -93,6 +93,15 @@ func (s *cancellationSettingsService) CreateSettings(ctx context.Context, tenant
 	if req.PolicyText != "" {
 		settings.PolicyText = req.PolicyText
 	}
+	if req.SelfCancelWindowMinutes != nil {
+		settings.SelfCancelWindowMinutes = *req.SelfCancelWindowMinutes
+	}
+	if req.AutoConfirmDelayMinutes != nil {
+		settings.AutoConfirmDelayMinutes = *req.AutoConfirmDelayMinutes
+	}
+	if req.UnpaidOrderCancelHours != nil {
+		settings.UnpaidOrderCancelHours = *req.UnpaidOrderCancelHours
+	}
 
 	if err := s.repo.Create(ctx, settings); err != nil {
 		return nil, fmt.Errorf("failed to create cancellation settings: %w", err)
@@ -155,6 +164,15 @@ func (s *cancellationSettingsService) UpdateSettings(ctx context.Context, tenant
 	if req.PolicyText != "" {
 		settings.PolicyText = req.PolicyText
 	}
+	if req.SelfCancelWindowMinutes != nil {
+		settings.SelfCancelWindowMinutes = *req.SelfCancelWindowMinutes
+	}
+	if req.AutoConfirmDelayMinutes != nil {
+		settings.AutoConfirmDelayMinutes = *req.AutoConfirmDelayMinutes
+	}
+	if req.UnpaidOrderCancelHours != nil {
+		settings.UnpaidOrderCancelHours = *req.UnpaidOrderCancelHours
+	}
 
 	// Upsert the settings
 	if err := s.repo.Upsert(ctx, settings); err != nil {
@@ -0,0 +1,362 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"orders-service/internal/clients"
+	"orders-service/internal/metrics"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+const (
+	// checkoutClaimDuration is how long a claimed saga is reserved for one caller
+	checkoutClaimDuration = 2 * time.Minute
+	checkoutMaxAttempts   = 8
+	checkoutRetryBase     = 5 * time.Second
+	checkoutMaxBackoff    = 10 * time.Minute
+	// checkoutPaymentPoll is how often an unpaid order is checked for payment or hold expiry
+	checkoutPaymentPoll = time.Minute
+	// checkoutReservationGrace keeps inventory-service's reservation alive a little past the
+	// hold, so the saga, not the reservation's own expiry, decides what happens to the stock
+	checkoutReservationGrace = 15 * time.Minute
+)
+
+var (
+	// ErrCheckoutNotFound is returned when an order wasn't checked out with a saga
+	ErrCheckoutNotFound = errors.New("checkout not found")
+	// ErrOrderNotAwaitingPayment is returned when an idempotent replay finds an order that was
+	// already paid or cancelled outside a checkout
+	ErrOrderNotAwaitingPayment = errors.New("order is no longer awaiting payment")
+)
+
+// CheckoutRequest is a storefront checkout: the order to place and how it will be paid
+type CheckoutRequest struct {
+	Order   CreateOrderRequest     `json:"order" binding:"required"`
+	Payment models.CheckoutPayment `json:"payment" binding:"required"`
+}
+
+// CheckoutResult is the placed order and where its checkout saga got to
+type CheckoutResult struct {
+	Order    *models.Order        `json:"order"`
+	Checkout *models.CheckoutSaga `json:"checkout"`
+}
+
+// CheckoutService runs checkout as a saga: the order is created, its stock held in
+// inventory-service, then a payment intent created in payment-service. The held stock is taken
+// once the order is paid. If stock runs out, payment-service refuses the intent, or the hold
+// expires unpaid, the stock is released and the order cancelled. Calls that fail are retried
+// with backoff by the checkout saga job.
+type CheckoutService struct {
+	orderService    OrderService
+	repo            *repository.CheckoutSagaRepository
+	inventoryClient clients.InventoryClient
+	paymentClient   clients.PaymentClient
+	holdTTL         time.Duration
+}
+
+// NewCheckoutService creates a new checkout service. holdTTL is how long an order's stock is
+// held waiting for payment.
+func NewCheckoutService(orderService OrderService, repo *repository.CheckoutSagaRepository, inventoryClient clients.InventoryClient, paymentClient clients.PaymentClient, holdTTL time.Duration) *CheckoutService {
+	return &CheckoutService{
+		orderService:    orderService,
+		repo:            repo,
+		inventoryClient: inventoryClient,
+		paymentClient:   paymentClient,
+		holdTTL:         holdTTL,
+	}
+}
+
+// Checkout places the order and runs the saga as far as it can go now. Steps that fail with a
+// retryable error are left to the checkout saga job; the returned saga says where it stopped.
+// Replaying a checkout with the same idempotency key returns the existing saga.
+func (s *CheckoutService) Checkout(ctx context.Context, req *CheckoutRequest, tenantID string) (*CheckoutResult, error) {
+	order, err := s.orderService.CreateOrder(req.Order, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	saga, err := s.repo.GetByOrderID(ctx, tenantID, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if saga != nil {
+		return &CheckoutResult{Order: order, Checkout: saga}, nil
+	}
+	if order.Status != models.OrderStatusPlaced || order.PaymentStatus == models.PaymentStatusPaid {
+		return nil, ErrOrderNotAwaitingPayment
+	}
+
+	payment, err := json.Marshal(req.Payment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal checkout payment: %w", err)
+	}
+	now := time.Now()
+	lockUntil := now.Add(checkoutClaimDuration)
+	saga = &models.CheckoutSaga{
+		TenantID:      tenantID,
+		OrderID:       order.ID,
+		Status:        models.CheckoutSagaReservingStock,
+		Payment:       models.JSONB(payment),
+		NextAttemptAt: now,
+		LockedUntil:   &lockUntil,
+	}
+	// Pickup orders already took their stock from the pickup location when they were created
+	if order.IsPickup() {
+		saga.Status = models.CheckoutSagaCreatingPayment
+	}
+	if err := s.repo.Create(ctx, saga); err != nil {
+		return nil, err
+	}
+
+	s.advance(ctx, saga, order, now)
+
+	if saga.Status == models.CheckoutSagaCompensated {
+		if cancelled, err := s.orderService.GetOrder(order.ID, tenantID); err == nil {
+			order = cancelled
+		}
+	}
+	return &CheckoutResult{Order: order, Checkout: saga}, nil
+}
+
+// GetCheckout returns the saga an order was checked out with
+func (s *CheckoutService) GetCheckout(ctx context.Context, tenantID string, orderID uuid.UUID) (*models.CheckoutSaga, error) {
+	saga, err := s.repo.GetByOrderID(ctx, tenantID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if saga == nil {
+		return nil, ErrCheckoutNotFound
+	}
+	return saga, nil
+}
+
+// ProcessDue advances up to limit sagas whose next attempt is due and returns how many finished
+// and how many were claimed
+func (s *CheckoutService) ProcessDue(ctx context.Context, now time.Time, limit int) (int, int, error) {
+	sagas, err := s.repo.ClaimDue(ctx, now, now.Add(checkoutClaimDuration), limit)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	finished := 0
+	for i := range sagas {
+		saga := &sagas[i]
+		order, err := s.orderService.GetOrder(saga.OrderID, saga.TenantID)
+		if err != nil {
+			retryCheckoutSaga(saga, now, fmt.Errorf("failed to load order: %w", err))
+			s.save(ctx, saga)
+			continue
+		}
+		s.advance(ctx, saga, order, now)
+		if saga.Status.IsFinished() {
+			finished++
+		}
+	}
+	return finished, len(sagas), nil
+}
+
+// advance runs the saga's steps until one fails, has to wait, or the saga finishes, then saves
+// it and releases the claim
+func (s *CheckoutService) advance(ctx context.Context, saga *models.CheckoutSaga, order *models.Order, now time.Time) {
+	for !saga.Status.IsFinished() {
+		status := saga.Status
+
+		var err error
+		switch saga.Status {
+		case models.CheckoutSagaReservingStock:
+			err = s.reserveStock(saga, order)
+		case models.CheckoutSagaCreatingPayment:
+			err = s.createPaymentIntent(saga, order, now)
+		case models.CheckoutSagaAwaitingPayment:
+			err = s.checkPayment(saga, order, now)
+		case models.CheckoutSagaCompensating:
+			err = s.compensate(saga, order)
+		}
+		if err != nil {
+			retryCheckoutSaga(saga, now, err)
+			break
+		}
+		if saga.Status == status {
+			break
+		}
+		saga.Attempts = 0
+		saga.LastError = ""
+	}
+	s.save(ctx, saga)
+}
+
+func (s *CheckoutService) save(ctx context.Context, saga *models.CheckoutSaga) {
+	if saga.Status.IsFinished() && saga.CompletedAt == nil {
+		completedAt := time.Now()
+		saga.CompletedAt = &completedAt
+		metrics.RecordCheckoutSaga(string(saga.Status))
+	}
+	if err := s.repo.Save(ctx, saga); err != nil {
+		log.Printf("WARNING: failed to save checkout saga %s for order %s: %v", saga.ID, saga.OrderID, err)
+	}
+}
+
+// reserveStock holds a shipped order's items in inventory-service
+func (s *CheckoutService) reserveStock(saga *models.CheckoutSaga, order *models.Order) error {
+	items := make([]clients.PickupStockItem, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = clients.PickupStockItem{
+			ProductID: item.ProductID.String(),
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	err := s.inventoryClient.ReserveOrderStock(order.ID.String(), items, s.holdTTL+checkoutReservationGrace, saga.TenantID)
+	if errors.Is(err, clients.ErrInsufficientStock) {
+		startCheckoutCompensation(saga, err.Error())
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+	saga.Status = models.CheckoutSagaCreatingPayment
+	return nil
+}
+
+// createPaymentIntent starts the payment the storefront completes with the customer, and starts
+// the hold on the order's stock
+func (s *CheckoutService) createPaymentIntent(saga *models.CheckoutSaga, order *models.Order, now time.Time) error {
+	var payment models.CheckoutPayment
+	if err := json.Unmarshal(saga.Payment, &payment); err != nil {
+		startCheckoutCompensation(saga, fmt.Sprintf("unreadable payment details: %v", err))
+		return nil
+	}
+
+	req := clients.CreatePaymentIntentRequest{
+		TenantID:      saga.TenantID,
+		OrderID:       order.ID.String(),
+		Amount:        order.Total,
+		Currency:      order.Currency,
+		CustomerID:    &order.CustomerID,
+		GatewayType:   payment.GatewayType,
+		PaymentMethod: payment.PaymentMethod,
+		Description:   fmt.Sprintf("Order %s", order.OrderNumber),
+		Metadata: map[string]string{
+			"orderNumber": order.OrderNumber,
+			"checkoutId":  saga.ID.String(),
+		},
+		ReturnURL: payment.ReturnURL,
+		CancelURL: payment.CancelURL,
+		UPIApp:    payment.UPIApp,
+		VPA:       payment.VPA,
+	}
+	if order.Customer != nil {
+		req.CustomerEmail = order.Customer.Email
+		req.CustomerPhone = order.Customer.Phone
+		req.CustomerName = order.Customer.FirstName + " " + order.Customer.LastName
+	}
+
+	intent, err := s.paymentClient.CreatePaymentIntent(req, saga.TenantID)
+	if errors.Is(err, clients.ErrPaymentIntentRejected) {
+		startCheckoutCompensation(saga, err.Error())
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create payment intent: %w", err)
+	}
+
+	holdExpiresAt := now.Add(s.holdTTL)
+	saga.PaymentIntent = models.JSONB(intent)
+	saga.HoldExpiresAt = &holdExpiresAt
+	saga.Status = models.CheckoutSagaAwaitingPayment
+	return nil
+}
+
+// checkPayment takes the held stock once the order is paid, and undoes the checkout when the
+// order was cancelled or the hold ran out
+func (s *CheckoutService) checkPayment(saga *models.CheckoutSaga, order *models.Order, now time.Time) error {
+	current, err := s.orderService.GetOrder(order.ID, saga.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to load order: %w", err)
+	}
+	*order = *current
+
+	switch {
+	case order.PaymentStatus == models.PaymentStatusPaid:
+		if !order.IsPickup() {
+			if err := s.inventoryClient.ConfirmOrderStock(order.ID.String(), saga.TenantID); err != nil {
+				return fmt.Errorf("failed to confirm reserved stock: %w", err)
+			}
+		}
+		saga.Status = models.CheckoutSagaCompleted
+	case order.Status == models.OrderStatusCancelled:
+		startCheckoutCompensation(saga, "order was cancelled before payment")
+	case saga.HoldExpiresAt != nil && now.After(*saga.HoldExpiresAt):
+		startCheckoutCompensation(saga, "payment not received before the stock hold expired")
+	default:
+		saga.NextAttemptAt = now.Add(checkoutPaymentPoll)
+	}
+	return nil
+}
+
+// compensate releases the order's held stock and cancels the order. An order that got paid in
+// the meantime is left alone for someone to refund or fulfil.
+func (s *CheckoutService) compensate(saga *models.CheckoutSaga, order *models.Order) error {
+	current, err := s.orderService.GetOrder(order.ID, saga.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to load order: %w", err)
+	}
+	*order = *current
+
+	if order.PaymentStatus == models.PaymentStatusPaid {
+		saga.Status = models.CheckoutSagaFailed
+		saga.FailureReason = fmt.Sprintf("order was paid while the checkout was being undone (%s)", saga.FailureReason)
+		return nil
+	}
+
+	if !order.IsPickup() {
+		if err := s.inventoryClient.ReleaseOrderStock(order.ID.String(), saga.TenantID); err != nil {
+			return fmt.Errorf("failed to release reserved stock: %w", err)
+		}
+	}
+	if order.Status != models.OrderStatusCancelled {
+		if _, err := s.orderService.ExpireUnpaidOrder(order.ID, saga.FailureReason, saga.TenantID); err != nil {
+			return fmt.Errorf("failed to cancel order: %w", err)
+		}
+	}
+	saga.Status = models.CheckoutSagaCompensated
+	return nil
+}
+
+// retryCheckoutSaga schedules the failed step again with exponential backoff. A forward step
+// that keeps failing is undone; compensation that keeps failing is left FAILED.
+func retryCheckoutSaga(saga *models.CheckoutSaga, now time.Time, err error) {
+	saga.Attempts++
+	saga.LastError = err.Error()
+	if saga.Attempts < checkoutMaxAttempts {
+		backoff := checkoutRetryBase << (saga.Attempts - 1)
+		if backoff > checkoutMaxBackoff {
+			backoff = checkoutMaxBackoff
+		}
+		saga.NextAttemptAt = now.Add(backoff)
+		return
+	}
+
+	if saga.Status == models.CheckoutSagaCompensating || saga.Status == models.CheckoutSagaAwaitingPayment {
+		saga.Status = models.CheckoutSagaFailed
+		saga.FailureReason = fmt.Sprintf("gave up after %d attempts: %s", saga.Attempts, saga.LastError)
+		return
+	}
+	startCheckoutCompensation(saga, fmt.Sprintf("gave up after %d attempts: %s", saga.Attempts, saga.LastError))
+	saga.Attempts = 0
+	saga.NextAttemptAt = now
+}
+
+func startCheckoutCompensation(saga *models.CheckoutSaga, reason string) {
+	saga.Status = models.CheckoutSagaCompensating
+	saga.FailureReason = reason
+}
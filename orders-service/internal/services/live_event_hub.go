@@ -0,0 +1,167 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"orders-service/internal/models"
+)
+
+const (
+	liveEventHistorySize     = 256 // Events kept per tenant for Last-Event-ID replay
+	liveEventClientQueueSize = 64
+)
+
+// LiveEventHub fans platform events out to connected dashboard streams, per tenant.
+// History is in memory and per replica. Event IDs carry the hub's epoch, so an ID issued by
+// another replica or a previous process is recognised as unknown and the client is told to resync.
+type LiveEventHub struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	history map[string][]liveEventEntry
+	clients map[string]map[*LiveEventClient]struct{}
+}
+
+type liveEventEntry struct {
+	seq   uint64
+	event models.LiveEvent
+}
+
+// LiveEventClient is a single connected stream. Events is closed when the client is
+// unsubscribed or dropped for falling behind.
+type LiveEventClient struct {
+	Events   chan models.LiveEvent
+	tenantID string
+	closed   bool
+}
+
+// NewLiveEventHub creates a new live event hub
+func NewLiveEventHub() *LiveEventHub {
+	return &LiveEventHub{
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		history: make(map[string][]liveEventEntry),
+		clients: make(map[string]map[*LiveEventClient]struct{}),
+	}
+}
+
+// Publish records an event in the tenant's history and delivers it to the tenant's clients.
+// A client whose queue is full is dropped rather than allowed to block the bridge; it
+// reconnects with Last-Event-ID and catches up from history.
+func (h *LiveEventHub) Publish(eventType, tenantID string, occurredAt time.Time, payload json.RawMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	entry := liveEventEntry{
+		seq: h.seq,
+		event: models.LiveEvent{
+			ID:         fmt.Sprintf("%s-%d", h.epoch, h.seq),
+			Type:       eventType,
+			TenantID:   tenantID,
+			OccurredAt: occurredAt,
+			Payload:    payload,
+		},
+	}
+
+	history := append(h.history[tenantID], entry)
+	if len(history) > liveEventHistorySize {
+		history = history[len(history)-liveEventHistorySize:]
+	}
+	h.history[tenantID] = history
+
+	for client := range h.clients[tenantID] {
+		select {
+		case client.Events <- entry.event:
+		default:
+			h.removeLocked(client)
+		}
+	}
+}
+
+// Subscribe registers a client for a tenant. When lastEventID is set, it returns the events
+// published after it; resync is true when that point is no longer covered by history and the
+// client should reload its data instead of relying on the replay.
+func (h *LiveEventHub) Subscribe(tenantID, lastEventID string) (client *LiveEventClient, replay []models.LiveEvent, resync bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client = &LiveEventClient{
+		Events:   make(chan models.LiveEvent, liveEventClientQueueSize),
+		tenantID: tenantID,
+	}
+	if h.clients[tenantID] == nil {
+		h.clients[tenantID] = make(map[*LiveEventClient]struct{})
+	}
+	h.clients[tenantID][client] = struct{}{}
+
+	if lastEventID == "" {
+		return client, nil, false
+	}
+
+	lastSeq, ok := h.parseEventID(lastEventID)
+	if !ok {
+		return client, nil, true
+	}
+
+	history := h.history[tenantID]
+	// A full history may have evicted events the client never saw
+	if len(history) == liveEventHistorySize && history[0].seq > lastSeq+1 {
+		resync = true
+	}
+	for _, entry := range history {
+		if entry.seq > lastSeq {
+			replay = append(replay, entry.event)
+		}
+	}
+	return client, replay, resync
+}
+
+// Unsubscribe removes a client and closes its event channel
+func (h *LiveEventHub) Unsubscribe(client *LiveEventClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(client)
+}
+
+// Close disconnects every client, ending their streams
+func (h *LiveEventHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, clients := range h.clients {
+		for client := range clients {
+			h.removeLocked(client)
+		}
+	}
+}
+
+func (h *LiveEventHub) removeLocked(client *LiveEventClient) {
+	if client.closed {
+		return
+	}
+	client.closed = true
+	close(client.Events)
+
+	clients := h.clients[client.tenantID]
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(h.clients, client.tenantID)
+	}
+}
+
+// parseEventID returns the sequence of an ID issued by this hub
+func (h *LiveEventHub) parseEventID(id string) (uint64, bool) {
+	epoch, seq, found := strings.Cut(id, "-")
+	if !found || epoch != h.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil || n > h.seq {
+		return 0, false
+	}
+	return n, true
+}
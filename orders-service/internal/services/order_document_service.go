@@ -0,0 +1,492 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/johnfercher/maroto/v2"
+	"github.com/johnfercher/maroto/v2/pkg/components/col"
+	"github.com/johnfercher/maroto/v2/pkg/components/text"
+	"github.com/johnfercher/maroto/v2/pkg/config"
+	"github.com/johnfercher/maroto/v2/pkg/consts/align"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"github.com/johnfercher/maroto/v2/pkg/merge"
+	"github.com/johnfercher/maroto/v2/pkg/props"
+
+	"orders-service/internal/clients"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+var (
+	// ErrInvalidDocumentType is returned for a document type the pack does not support
+	ErrInvalidDocumentType = errors.New("invalid document type")
+	// ErrNoShipmentForLabel is returned when a label is requested for an order that has not been shipped
+	ErrNoShipmentForLabel = errors.New("order has no shipment to print a label for")
+	// ErrLabelUnavailable is returned when shipping-service cannot provide a shipment's label
+	ErrLabelUnavailable = errors.New("shipping label is not available")
+)
+
+// OrderDocumentService collects an order's fulfillment documents into a single download
+type OrderDocumentService interface {
+	// BuildDocumentPack renders or fetches the requested documents, generating any that are
+	// missing, and combines them into a merged PDF or a zip
+	BuildDocumentPack(ctx context.Context, order *models.Order, tenantID string, req *models.OrderDocumentPackRequest) (*models.OrderDocumentPack, error)
+
+	// StoreDocumentPack uploads a pack to document-service and returns a presigned download URL
+	StoreDocumentPack(ctx context.Context, order *models.Order, tenantID string, pack *models.OrderDocumentPack) (*models.OrderDocumentPackURLResponse, error)
+}
+
+type orderDocumentService struct {
+	receiptService ReceiptService
+	documentRepo   *repository.ReceiptDocumentRepository
+	documentClient clients.DocumentClient
+	shippingClient clients.ShippingClient
+	tenantClient   clients.TenantClient
+}
+
+// NewOrderDocumentService creates a new order document service
+func NewOrderDocumentService(
+	receiptService ReceiptService,
+	documentRepo *repository.ReceiptDocumentRepository,
+	documentClient clients.DocumentClient,
+	shippingClient clients.ShippingClient,
+	tenantClient clients.TenantClient,
+) OrderDocumentService {
+	return &orderDocumentService{
+		receiptService: receiptService,
+		documentRepo:   documentRepo,
+		documentClient: documentClient,
+		shippingClient: shippingClient,
+		tenantClient:   tenantClient,
+	}
+}
+
+// ParseOrderDocumentTypes parses a comma-separated list of document types, keeping the
+// requested order and dropping duplicates. An empty list selects every type.
+func ParseOrderDocumentTypes(value string) ([]models.OrderDocumentType, error) {
+	if strings.TrimSpace(value) == "" {
+		return models.DefaultOrderDocumentTypes, nil
+	}
+
+	seen := make(map[models.OrderDocumentType]bool)
+	var types []models.OrderDocumentType
+	for _, part := range strings.Split(value, ",") {
+		docType := models.OrderDocumentType(strings.ToLower(strings.TrimSpace(part)))
+		if docType == "" {
+			continue
+		}
+		switch docType {
+		case models.OrderDocumentPackingSlip, models.OrderDocumentInvoice, models.OrderDocumentLabel:
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidDocumentType, docType)
+		}
+		if !seen[docType] {
+			seen[docType] = true
+			types = append(types, docType)
+		}
+	}
+	if len(types) == 0 {
+		return models.DefaultOrderDocumentTypes, nil
+	}
+	return types, nil
+}
+
+// BuildDocumentPack renders or fetches the requested documents and combines them
+func (s *orderDocumentService) BuildDocumentPack(ctx context.Context, order *models.Order, tenantID string, req *models.OrderDocumentPackRequest) (*models.OrderDocumentPack, error) {
+	types := models.DefaultOrderDocumentTypes
+	format := models.OrderDocumentPackPDF
+	var locale string
+	if req != nil {
+		if len(req.Types) > 0 {
+			types = req.Types
+		}
+		if req.Format != "" {
+			format = req.Format
+		}
+		locale = req.Locale
+	}
+
+	var files []models.OrderDocumentFile
+	for _, docType := range types {
+		switch docType {
+		case models.OrderDocumentPackingSlip:
+			data, err := s.generatePackingSlip(order, tenantID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate packing slip: %w", err)
+			}
+			files = append(files, models.OrderDocumentFile{
+				Type:     docType,
+				Filename: fmt.Sprintf("packing-slip-%s.pdf", order.OrderNumber),
+				Data:     data,
+			})
+		case models.OrderDocumentInvoice:
+			data, err := s.invoicePDF(ctx, order, tenantID, locale)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate invoice: %w", err)
+			}
+			files = append(files, models.OrderDocumentFile{
+				Type:     docType,
+				Filename: fmt.Sprintf("invoice-%s.pdf", order.OrderNumber),
+				Data:     data,
+			})
+		case models.OrderDocumentLabel:
+			labels, err := s.shippingLabels(ctx, order, tenantID)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, labels...)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidDocumentType, docType)
+		}
+	}
+
+	for i := range files {
+		files[i].Size = int64(len(files[i].Data))
+	}
+
+	pack := &models.OrderDocumentPack{
+		OrderNumber: order.OrderNumber,
+		Format:      format,
+		Documents:   files,
+	}
+
+	switch format {
+	case models.OrderDocumentPackPDF:
+		pdfs := make([][]byte, len(files))
+		for i, f := range files {
+			pdfs[i] = f.Data
+		}
+		data := pdfs[0]
+		if len(pdfs) > 1 {
+			merged, err := merge.Bytes(pdfs...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to merge documents: %w", err)
+			}
+			data = merged
+		}
+		pack.Data = data
+		pack.ContentType = "application/pdf"
+		pack.Filename = fmt.Sprintf("documents-%s.pdf", order.OrderNumber)
+	case models.OrderDocumentPackZIP:
+		data, err := zipOrderDocuments(files)
+		if err != nil {
+			return nil, fmt.Errorf("failed to zip documents: %w", err)
+		}
+		pack.Data = data
+		pack.ContentType = "application/zip"
+		pack.Filename = fmt.Sprintf("documents-%s.zip", order.OrderNumber)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+
+	return pack, nil
+}
+
+// StoreDocumentPack uploads a pack to the receipts bucket and returns a presigned download URL
+func (s *orderDocumentService) StoreDocumentPack(ctx context.Context, order *models.Order, tenantID string, pack *models.OrderDocumentPack) (*models.OrderDocumentPackURLResponse, error) {
+	if s.documentClient == nil {
+		return nil, fmt.Errorf("document service is not configured")
+	}
+
+	storage := s.receiptService.GetStorageConfig()
+	now := time.Now()
+	// {prefix}/{tenant_id}/packs/{year}/{month}/{order_number}_{timestamp}_{filename}
+	path := fmt.Sprintf("%s/%s/packs/%d/%02d/%s_%d_%s",
+		storage.PathPrefix, tenantID, now.Year(), now.Month(), order.OrderNumber, now.Unix(), pack.Filename)
+
+	uploadResp, err := s.documentClient.UploadDocument(ctx, &clients.DocumentUploadRequest{
+		TenantID:    tenantID,
+		Bucket:      storage.Bucket,
+		Path:        path,
+		Filename:    pack.Filename,
+		ContentType: pack.ContentType,
+		Data:        pack.Data,
+		IsPublic:    false,
+		Tags: map[string]string{
+			"tenant_id":     tenantID,
+			"order_id":      order.ID.String(),
+			"order_number":  order.OrderNumber,
+			"document_type": "DOCUMENT_PACK",
+		},
+		EntityType: "order_documents",
+		EntityID:   order.ID.String(),
+		ProductID:  "marketplace",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload document pack: %w", err)
+	}
+
+	presigned, err := s.documentClient.GetPresignedURL(ctx, &clients.PresignedURLRequest{
+		TenantID:  tenantID,
+		Bucket:    storage.Bucket,
+		Path:      path,
+		Method:    "GET",
+		ExpiresIn: 3600,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get download URL: %w", err)
+	}
+
+	return &models.OrderDocumentPackURLResponse{
+		OrderNumber: order.OrderNumber,
+		Filename:    pack.Filename,
+		DocumentID:  uploadResp.ID,
+		DownloadURL: presigned.URL,
+		ExpiresAt:   presigned.ExpiresAt,
+		Documents:   pack.Documents,
+	}, nil
+}
+
+// invoicePDF renders the order's invoice, first storing one in document-service if the
+// order does not have an invoice yet
+func (s *orderDocumentService) invoicePDF(ctx context.Context, order *models.Order, tenantID, locale string) ([]byte, error) {
+	hasInvoice := false
+	if s.documentRepo != nil {
+		docs, err := s.documentRepo.GetByOrderID(order.ID, tenantID)
+		if err != nil {
+			log.Printf("WARNING: Failed to check existing invoices for order %s: %v", order.OrderNumber, err)
+		}
+		for _, doc := range docs {
+			if doc.DocumentType == models.ReceiptDocumentTypeInvoice || doc.DocumentType == models.ReceiptDocumentTypeTaxInvoice {
+				hasInvoice = true
+				break
+			}
+		}
+	}
+
+	if !hasInvoice {
+		// ForceRegenerate: the order may already have a receipt, which would otherwise be returned
+		_, err := s.receiptService.GenerateAndStoreReceipt(ctx, order, tenantID, &models.GenerateReceiptAndStoreRequest{
+			OrderID:         order.ID,
+			DocumentType:    models.ReceiptDocumentTypeInvoice,
+			Format:          models.ReceiptFormatPDF,
+			Locale:          locale,
+			ForceRegenerate: true,
+		})
+		if err != nil {
+			log.Printf("WARNING: Failed to store invoice for order %s: %v", order.OrderNumber, err)
+		}
+	}
+
+	data, _, err := s.receiptService.GenerateReceipt(order, tenantID, &models.ReceiptGenerationRequest{
+		Format: models.ReceiptFormatPDF,
+		Locale: locale,
+	})
+	return data, err
+}
+
+// shippingLabels fetches the label of every active shipment on the order
+func (s *orderDocumentService) shippingLabels(ctx context.Context, order *models.Order, tenantID string) ([]models.OrderDocumentFile, error) {
+	if s.shippingClient == nil {
+		return nil, ErrLabelUnavailable
+	}
+
+	shipments, err := s.shippingClient.GetShipmentsByOrder(ctx, tenantID, order.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLabelUnavailable, err)
+	}
+
+	var files []models.OrderDocumentFile
+	for _, shipment := range shipments {
+		if shipment.Status == "CANCELLED" || shipment.Status == "FAILED" {
+			continue
+		}
+		data, err := s.shippingClient.GetShipmentLabel(ctx, tenantID, shipment.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: shipment %s: %v", ErrLabelUnavailable, shipment.ID, err)
+		}
+
+		name := shipment.TrackingNumber
+		if name == "" {
+			name = shipment.ID
+		}
+		files = append(files, models.OrderDocumentFile{
+			Type:     models.OrderDocumentLabel,
+			Filename: fmt.Sprintf("label-%s.pdf", name),
+			Data:     data,
+		})
+	}
+
+	if len(files) == 0 {
+		return nil, ErrNoShipmentForLabel
+	}
+	return files, nil
+}
+
+// generatePackingSlip renders a price-free packing slip listing the items to pick and pack
+func (s *orderDocumentService) generatePackingSlip(order *models.Order, tenantID string) ([]byte, error) {
+	settings, err := s.receiptService.GetOrCreateSettings(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt settings: %w", err)
+	}
+	businessName := settings.BusinessName
+	if s.tenantClient != nil {
+		if name := s.tenantClient.GetTenantName(context.Background(), tenantID); name != "" {
+			businessName = name
+		}
+	}
+
+	cfg := config.NewBuilder().
+		WithPageNumber().
+		WithLeftMargin(15).
+		WithTopMargin(15).
+		WithRightMargin(15).
+		Build()
+
+	m := maroto.New(cfg)
+
+	m.AddRow(22,
+		col.New(6).Add(
+			text.New(businessName, props.Text{Size: 14, Style: fontstyle.Bold, Color: pdfDarkText}),
+			text.New(settings.BusinessAddress, props.Text{Size: 8, Color: pdfLightText, Top: 7}),
+		),
+		col.New(6).Add(
+			text.New("PACKING SLIP", props.Text{Size: 22, Style: fontstyle.Bold, Align: align.Right, Color: pdfDarkText}),
+			text.New(order.OrderNumber, props.Text{Size: 9, Top: 9, Align: align.Right, Color: pdfAccent}),
+			text.New(order.CreatedAt.Format("January 02, 2006"), props.Text{Size: 8, Top: 14, Align: align.Right, Color: pdfLightText}),
+		),
+	).WithStyle(&props.Cell{BackgroundColor: pdfHeaderBg})
+	m.AddRow(1).WithStyle(&props.Cell{BackgroundColor: pdfAccent})
+	m.AddRow(4)
+
+	s.addPackingSlipAddresses(m, order)
+	s.addPackingSlipItems(m, order)
+
+	if order.Notes != "" {
+		m.AddRow(4)
+		m.AddRow(14,
+			col.New(12).Add(
+				text.New("NOTES", props.Text{Size: 8, Style: fontstyle.Bold, Color: pdfLightText}),
+				text.New(order.Notes, props.Text{Size: 9, Color: pdfDarkText, Top: 5}),
+			),
+		)
+	}
+
+	pdfDoc, err := m.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+	return pdfDoc.GetBytes(), nil
+}
+
+// addPackingSlipAddresses adds the ship-to address and shipping method
+func (s *orderDocumentService) addPackingSlipAddresses(m core.Maroto, order *models.Order) {
+	var recipient, phone string
+	if order.Customer != nil {
+		recipient = strings.TrimSpace(order.Customer.FirstName + " " + order.Customer.LastName)
+		phone = order.Customer.Phone
+	}
+
+	var address, method string
+	if order.Shipping != nil {
+		cityLine := order.Shipping.City
+		if order.Shipping.State != "" {
+			cityLine += ", " + order.Shipping.State
+		}
+		if order.Shipping.PostalCode != "" {
+			cityLine += " " + order.Shipping.PostalCode
+		}
+		parts := []string{order.Shipping.Street, cityLine}
+		if order.Shipping.Country != "" {
+			parts = append(parts, order.Shipping.Country)
+		}
+		address = strings.Join(parts, "\n")
+
+		method = order.Shipping.Method
+		if order.Shipping.Carrier != "" {
+			method += " (" + order.Shipping.Carrier + ")"
+		}
+		if order.Shipping.TrackingNumber != "" {
+			method += "\nTracking: " + order.Shipping.TrackingNumber
+		}
+	}
+
+	m.AddRow(6,
+		col.New(6).Add(text.New("SHIP TO", props.Text{Size: 8, Style: fontstyle.Bold, Color: pdfLightText})),
+		col.New(6).Add(text.New("SHIPPING METHOD", props.Text{Size: 8, Style: fontstyle.Bold, Color: pdfLightText})),
+	)
+	m.AddRow(24,
+		col.New(6).Add(
+			text.New(recipient, props.Text{Size: 10, Style: fontstyle.Bold, Color: pdfDarkText}),
+			text.New(address, props.Text{Size: 9, Color: pdfDarkText, Top: 5}),
+			text.New(phone, props.Text{Size: 8, Color: pdfMediumText, Top: 19}),
+		),
+		col.New(6).Add(
+			text.New(method, props.Text{Size: 9, Color: pdfDarkText}),
+		),
+	)
+	m.AddRow(4)
+}
+
+// addPackingSlipItems adds the items to pack with their customizations, without prices
+func (s *orderDocumentService) addPackingSlipItems(m core.Maroto, order *models.Order) {
+	m.AddRow(9,
+		col.New(7).Add(text.New("ITEM", props.Text{Size: 8, Style: fontstyle.Bold, Color: pdfWhite, Top: 2})),
+		col.New(3).Add(text.New("SKU", props.Text{Size: 8, Style: fontstyle.Bold, Color: pdfWhite, Align: align.Center, Top: 2})),
+		col.New(2).Add(text.New("QTY", props.Text{Size: 8, Style: fontstyle.Bold, Color: pdfWhite, Align: align.Center, Top: 2})),
+	).WithStyle(&props.Cell{BackgroundColor: pdfTotalBg})
+
+	totalQty := 0
+	for i, item := range order.Items {
+		totalQty += item.Quantity
+
+		names := make([]string, 0, len(item.Properties))
+		for name := range item.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		details := make([]string, len(names))
+		for j, name := range names {
+			details[j] = fmt.Sprintf("%s: %s", name, item.Properties[name])
+		}
+
+		itemCol := col.New(7).Add(text.New(item.ProductName, props.Text{Size: 9, Color: pdfDarkText, Top: 1}))
+		rowHeight := 8.0
+		if len(details) > 0 {
+			itemCol.Add(text.New(strings.Join(details, "\n"), props.Text{Size: 7, Color: pdfMediumText, Top: 6}))
+			rowHeight += 4 * float64(len(details))
+		}
+
+		r := m.AddRow(rowHeight,
+			itemCol,
+			col.New(3).Add(text.New(item.SKU, props.Text{Size: 8, Color: pdfMediumText, Align: align.Center, Top: 1})),
+			col.New(2).Add(text.New(fmt.Sprintf("%d", item.Quantity), props.Text{Size: 10, Style: fontstyle.Bold, Color: pdfDarkText, Align: align.Center, Top: 1})),
+		)
+		if i%2 == 0 {
+			r.WithStyle(&props.Cell{BackgroundColor: pdfHeaderBg})
+		}
+	}
+
+	m.AddRow(8,
+		col.New(10).Add(text.New("Total items", props.Text{Size: 9, Style: fontstyle.Bold, Color: pdfDarkText, Align: align.Right, Top: 2})),
+		col.New(2).Add(text.New(fmt.Sprintf("%d", totalQty), props.Text{Size: 10, Style: fontstyle.Bold, Color: pdfDarkText, Align: align.Center, Top: 2})),
+	)
+}
+
+// zipOrderDocuments packs each document into a zip archive
+func zipOrderDocuments(files []models.OrderDocumentFile) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.Filename)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(f.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
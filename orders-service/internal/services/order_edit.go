@@ -0,0 +1,481 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"orders-service/internal/clients"
+	"orders-service/internal/models"
+)
+
+var (
+	// ErrOrderNotEditable is returned when an order has started fulfillment, been split, or been
+	// refunded, so its items can no longer be changed
+	ErrOrderNotEditable = errors.New("order can no longer be edited")
+	// ErrInvalidOrderEdit is returned when an edit refers to items not on the order, or would
+	// leave it empty or unchanged
+	ErrInvalidOrderEdit = errors.New("invalid order edit")
+)
+
+// EditOrderRequest changes the items of a placed order. Items not listed are left as they are.
+type EditOrderRequest struct {
+	Items  []EditOrderItemRequest `json:"items" binding:"required,min=1,dive"`
+	Reason string                 `json:"reason"`
+	// ExpectedVersion makes the edit conditional; the handler takes it from If-Match when set
+	ExpectedVersion *int `json:"expectedVersion,omitempty"`
+	// NotifyCustomer emails the customer the updated order; defaults to true
+	NotifyCustomer *bool `json:"notifyCustomer,omitempty"`
+}
+
+// EditOrderItemRequest changes the quantity of an existing line, or adds a new one when
+// orderItemId is omitted. New lines are priced from products-service.
+type EditOrderItemRequest struct {
+	OrderItemID *uuid.UUID `json:"orderItemId,omitempty"`
+	Quantity    int        `json:"quantity" binding:"min=0"` // 0 removes the line
+
+	// New lines only
+	ProductID uuid.UUID `json:"productId,omitempty"`
+	Image     string    `json:"image,omitempty"`
+}
+
+// EditOrderResponse is the edited order and the revision recording the edit
+type EditOrderResponse struct {
+	Order    *models.Order         `json:"order"`
+	Revision *models.OrderRevision `json:"revision"`
+}
+
+// EditOrder changes the items of an order that hasn't started fulfillment. Stock for added
+// quantities is deducted before the edit is saved and removed quantities are restored after;
+// for a paid order the difference in total is charged with a new payment intent or refunded
+// against the original payment.
+func (s *orderService) EditOrder(id uuid.UUID, req EditOrderRequest, editedBy *uuid.UUID, tenantID string) (*EditOrderResponse, error) {
+	order, err := s.orderRepo.GetByID(id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != order.Version {
+		return nil, &models.VersionConflictError{CurrentVersion: order.Version}
+	}
+	if !order.IsEditable() {
+		return nil, ErrOrderNotEditable
+	}
+
+	products, err := s.editedOrderProducts(req.Items, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, removed, changes, err := applyItemEdits(order, req.Items, products)
+	if err != nil {
+		return nil, err
+	}
+
+	// Net change per product, so moving quantity between lines of the same product is free
+	increases, decreases := stockChanges(changes)
+	if len(increases) > 0 {
+		stockCheckItems := make([]clients.StockCheckItem, len(increases))
+		for i, item := range increases {
+			stockCheckItems[i] = clients.StockCheckItem{ProductID: item.ProductID, Quantity: item.Quantity}
+		}
+		stockResponse, err := s.productsClient.CheckStock(stockCheckItems, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check stock availability: %w", err)
+		}
+		if !stockResponse.AllInStock {
+			var outOfStockProducts []string
+			for _, result := range stockResponse.Results {
+				if !result.Available {
+					outOfStockProducts = append(outOfStockProducts, fmt.Sprintf("%s (requested: %d, available: %d)",
+						result.ProductName, result.Requested, result.InStock))
+				}
+			}
+			return nil, fmt.Errorf("%w for products: %v", clients.ErrInsufficientStock, outOfStockProducts)
+		}
+	}
+
+	revision := &models.OrderRevision{
+		TenantID:               tenantID,
+		OrderID:                order.ID,
+		Changes:                changes,
+		Reason:                 req.Reason,
+		PreviousSubtotal:       order.Subtotal,
+		PreviousTaxAmount:      order.TaxAmount,
+		PreviousDiscountAmount: order.DiscountAmount,
+		PreviousTotal:          order.Total,
+		PaymentAction:          models.RevisionPaymentNone,
+		PaymentStatus:          models.RevisionPaymentNotRequired,
+		EditedBy:               editedBy,
+	}
+
+	previousSubtotal := order.Subtotal
+	order.Items = items
+	order.Subtotal = 0
+	for _, item := range items {
+		order.Subtotal += item.TotalPrice
+	}
+	order.Subtotal = roundCurrency(order.Subtotal)
+	recalculateDiscounts(order, previousSubtotal)
+	order.TaxAmount = s.editedOrderTax(order, tenantID)
+	order.Total = roundCurrency(math.Max(0, order.Subtotal+order.TaxAmount+order.ShippingCost-order.DiscountAmount))
+	order.VendorID = ""
+	if vendorIDs := orderVendorIDs(order.Items); len(vendorIDs) == 1 {
+		order.VendorID = vendorIDs[0]
+	}
+
+	revision.NewSubtotal = order.Subtotal
+	revision.NewTaxAmount = order.TaxAmount
+	revision.NewDiscountAmount = order.DiscountAmount
+	revision.NewTotal = order.Total
+	revision.PaymentDelta = roundCurrency(order.Total - revision.PreviousTotal)
+
+	// Take the added stock before saving, so a failed deduction leaves the order as it was
+	if len(increases) > 0 {
+		if err := s.productsClient.DeductInventory(increases, fmt.Sprintf("Order %s edited", order.OrderNumber), tenantID); err != nil {
+			return nil, fmt.Errorf("failed to reserve inventory: %w", err)
+		}
+	}
+
+	if err := s.orderRepo.ApplyRevision(order, removed, revision); err != nil {
+		if len(increases) > 0 {
+			if restoreErr := s.productsClient.RestoreInventory(increases, fmt.Sprintf("Order %s edit failed", order.OrderNumber), tenantID); restoreErr != nil {
+				fmt.Printf("WARNING: Failed to restore inventory after failed edit of order %s: %v\n", order.OrderNumber, restoreErr)
+			}
+		}
+		return nil, err
+	}
+
+	if len(decreases) > 0 {
+		if err := s.productsClient.RestoreInventory(decreases, fmt.Sprintf("Order %s edited", order.OrderNumber), tenantID); err != nil {
+			fmt.Printf("WARNING: Failed to restore inventory for edited order %s: %v\n", order.OrderNumber, err)
+		}
+	}
+
+	if order.PaymentStatus == models.PaymentStatusPaid && math.Abs(revision.PaymentDelta) >= 0.01 {
+		s.settleRevisionPayment(order, revision, tenantID)
+	}
+
+	notify := req.NotifyCustomer == nil || *req.NotifyCustomer
+	if notify && s.notificationClient != nil && order.Customer != nil && order.Customer.Email != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		notification := s.buildOrderNotification(ctx, order, tenantID)
+		switch revision.PaymentAction {
+		case models.RevisionPaymentCharge:
+			notification.AmountDue = fmt.Sprintf("%.2f", revision.PaymentDelta)
+		case models.RevisionPaymentRefund:
+			notification.RefundAmount = fmt.Sprintf("%.2f", -revision.PaymentDelta)
+		}
+		if err := s.notificationClient.SendOrderUpdated(ctx, notification); err != nil {
+			fmt.Printf("WARNING: Failed to send order updated notification for order %s: %v\n", order.OrderNumber, err)
+		} else {
+			revision.CustomerNotified = true
+		}
+		cancel()
+	}
+
+	if revision.PaymentAction != models.RevisionPaymentNone || revision.CustomerNotified {
+		if err := s.orderRepo.UpdateRevisionPayment(revision); err != nil {
+			fmt.Printf("WARNING: Failed to record payment for revision %d of order %s: %v\n", revision.Revision, order.OrderNumber, err)
+		}
+	}
+
+	if s.eventsPublisher != nil {
+		s.eventsPublisher.PublishOrderEdited(context.Background(), order, revision, tenantID)
+	}
+
+	return &EditOrderResponse{Order: order, Revision: revision}, nil
+}
+
+// ListOrderRevisions returns an order's edit history, oldest first
+func (s *orderService) ListOrderRevisions(id uuid.UUID, tenantID string) ([]models.OrderRevision, error) {
+	if _, err := s.orderRepo.GetByID(id, tenantID); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.ListRevisions(id, tenantID)
+}
+
+// editedOrderProducts fetches the products of the lines an edit adds, so they are named and
+// priced from the catalog rather than the request
+func (s *orderService) editedOrderProducts(edits []EditOrderItemRequest, tenantID string) (map[uuid.UUID]*clients.Product, error) {
+	products := make(map[uuid.UUID]*clients.Product)
+	for _, edit := range edits {
+		if edit.OrderItemID != nil || edit.ProductID == uuid.Nil {
+			continue
+		}
+		if _, seen := products[edit.ProductID]; seen {
+			continue
+		}
+		product, err := s.productsClient.GetProduct(edit.ProductID.String(), tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get product %s: %w", edit.ProductID, err)
+		}
+		products[edit.ProductID] = product
+	}
+	return products, nil
+}
+
+// applyItemEdits returns the order's items after the edit, the IDs of lines removed, and a
+// change for every line whose quantity changed. New lines take their name, SKU, vendor and
+// price from products.
+func applyItemEdits(order *models.Order, edits []EditOrderItemRequest, products map[uuid.UUID]*clients.Product) ([]models.OrderItem, []uuid.UUID, models.OrderRevisionChanges, error) {
+	quantities := make(map[uuid.UUID]int)
+	for _, edit := range edits {
+		if edit.OrderItemID == nil {
+			continue
+		}
+		if _, dup := quantities[*edit.OrderItemID]; dup {
+			return nil, nil, nil, fmt.Errorf("%w: order item %s is listed more than once", ErrInvalidOrderEdit, *edit.OrderItemID)
+		}
+		quantities[*edit.OrderItemID] = edit.Quantity
+	}
+
+	var items []models.OrderItem
+	var removed []uuid.UUID
+	var changes models.OrderRevisionChanges
+	for _, item := range order.Items {
+		quantity, edited := quantities[item.ID]
+		delete(quantities, item.ID)
+		if !edited || quantity == item.Quantity {
+			items = append(items, item)
+			continue
+		}
+
+		changes = append(changes, models.OrderRevisionChange{
+			OrderItemID:      item.ID,
+			ProductID:        item.ProductID,
+			ProductName:      item.ProductName,
+			SKU:              item.SKU,
+			UnitPrice:        item.UnitPrice,
+			PreviousQuantity: item.Quantity,
+			NewQuantity:      quantity,
+		})
+		if quantity == 0 {
+			removed = append(removed, item.ID)
+			continue
+		}
+		item.Quantity = quantity
+		item.TotalPrice = roundCurrency(item.UnitPrice * float64(quantity))
+		items = append(items, item)
+	}
+	for itemID := range quantities {
+		return nil, nil, nil, fmt.Errorf("%w: order item %s is not on the order", ErrInvalidOrderEdit, itemID)
+	}
+
+	for _, edit := range edits {
+		if edit.OrderItemID != nil {
+			continue
+		}
+		if edit.ProductID == uuid.Nil || edit.Quantity < 1 {
+			return nil, nil, nil, fmt.Errorf("%w: new items need a productId and a quantity of at least 1", ErrInvalidOrderEdit)
+		}
+		product := products[edit.ProductID]
+		if product == nil {
+			return nil, nil, nil, fmt.Errorf("%w: product %s not found", ErrInvalidOrderEdit, edit.ProductID)
+		}
+		unitPrice, err := strconv.ParseFloat(strings.TrimSpace(product.Price), 64)
+		if err != nil || unitPrice < 0 {
+			return nil, nil, nil, fmt.Errorf("%w: product %s has no valid price", ErrInvalidOrderEdit, edit.ProductID)
+		}
+		item := models.OrderItem{
+			ID:          uuid.New(),
+			OrderID:     order.ID,
+			VendorID:    product.VendorID,
+			ProductID:   edit.ProductID,
+			ProductName: product.Name,
+			SKU:         product.SKU,
+			Image:       edit.Image,
+			Quantity:    edit.Quantity,
+			UnitPrice:   unitPrice,
+			TotalPrice:  roundCurrency(unitPrice * float64(edit.Quantity)),
+		}
+		items = append(items, item)
+		changes = append(changes, models.OrderRevisionChange{
+			OrderItemID: item.ID,
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			SKU:         item.SKU,
+			UnitPrice:   item.UnitPrice,
+			NewQuantity: item.Quantity,
+		})
+	}
+
+	if len(changes) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: nothing to change", ErrInvalidOrderEdit)
+	}
+	if len(items) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: an order must keep at least one item; cancel it instead", ErrInvalidOrderEdit)
+	}
+	return items, removed, changes, nil
+}
+
+// recalculateDiscounts reprices the order's discounts for its new subtotal. A percentage coupon
+// keeps the rate it was applied at; fixed coupons and gift cards keep their amount. Together
+// they never take more than the subtotal.
+func recalculateDiscounts(order *models.Order, previousSubtotal float64) {
+	if len(order.Discounts) == 0 {
+		order.DiscountAmount = roundCurrency(math.Min(order.DiscountAmount, order.Subtotal))
+		return
+	}
+
+	remaining := order.Subtotal
+	total := 0.0
+	for i := range order.Discounts {
+		discount := &order.Discounts[i]
+		amount := discount.Amount
+		if strings.EqualFold(discount.DiscountType, "percentage") && previousSubtotal > 0 {
+			amount = order.Subtotal * discount.Amount / previousSubtotal
+		}
+		discount.Amount = roundCurrency(math.Max(0, math.Min(amount, remaining)))
+		remaining -= discount.Amount
+		total += discount.Amount
+	}
+	order.DiscountAmount = roundCurrency(total)
+}
+
+// stockChanges nets an edit's changes by product into stock to take and stock to give back
+func stockChanges(changes models.OrderRevisionChanges) (increases, decreases []clients.InventoryItem) {
+	net := make(map[uuid.UUID]int)
+	var order []uuid.UUID
+	for _, change := range changes {
+		if _, seen := net[change.ProductID]; !seen {
+			order = append(order, change.ProductID)
+		}
+		net[change.ProductID] += change.NewQuantity - change.PreviousQuantity
+	}
+	for _, productID := range order {
+		switch diff := net[productID]; {
+		case diff > 0:
+			increases = append(increases, clients.InventoryItem{ProductID: productID.String(), Quantity: diff})
+		case diff < 0:
+			decreases = append(decreases, clients.InventoryItem{ProductID: productID.String(), Quantity: -diff})
+		}
+	}
+	return increases, decreases
+}
+
+// editedOrderTax recalculates tax for the order's new items the way it was calculated at checkout
+func (s *orderService) editedOrderTax(order *models.Order, tenantID string) float64 {
+	req := CreateOrderRequest{Items: make([]CreateOrderItemRequest, len(order.Items))}
+	for i, item := range order.Items {
+		req.Items[i] = CreateOrderItemRequest{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+		}
+	}
+	if order.Shipping != nil {
+		req.Shipping = CreateOrderShippingRequest{
+			City:       order.Shipping.City,
+			State:      order.Shipping.State,
+			PostalCode: order.Shipping.PostalCode,
+			Country:    order.Shipping.Country,
+			Cost:       order.ShippingCost,
+		}
+	}
+
+	taxResp, err := s.taxClient.CalculateTax(s.buildTaxCalculationRequest(req, order.Subtotal, tenantID), tenantID)
+	if err != nil {
+		fmt.Printf("WARNING: Tax service unavailable, using fallback calculation: %v\n", err)
+		return roundCurrency(order.Subtotal * 0.085) // Fallback 8.5% tax
+	}
+	return roundCurrency(taxResp.TaxAmount)
+}
+
+// settleRevisionPayment charges or refunds the change in a paid order's total, recording the
+// outcome on the revision. A failure is left on the revision for staff to settle by hand
+// rather than undoing the edit.
+func (s *orderService) settleRevisionPayment(order *models.Order, revision *models.OrderRevision, tenantID string) {
+	if revision.PaymentDelta > 0 {
+		revision.PaymentAction = models.RevisionPaymentCharge
+	} else {
+		revision.PaymentAction = models.RevisionPaymentRefund
+	}
+	revision.PaymentStatus = models.RevisionPaymentFailed
+
+	if s.paymentClient == nil {
+		revision.PaymentError = "payment service is not configured"
+		return
+	}
+	payments, err := s.paymentClient.GetPaymentsByOrder(order.ID, tenantID)
+	if err != nil {
+		revision.PaymentError = fmt.Sprintf("failed to get payments for order: %v", err)
+		return
+	}
+	// The largest completed payment is the one the order was paid with
+	var original *clients.Payment
+	for i, payment := range payments {
+		switch payment.Status {
+		case "SUCCEEDED", "COMPLETED", "CAPTURED":
+			if original == nil || payment.Amount > original.Amount {
+				original = &payments[i]
+			}
+		}
+	}
+	if original == nil {
+		revision.PaymentError = "no completed payment found for order"
+		return
+	}
+	if revision.PaymentAction == models.RevisionPaymentRefund && -revision.PaymentDelta > original.Amount+0.005 {
+		revision.PaymentError = fmt.Sprintf("refund of %.2f exceeds the original payment of %.2f", -revision.PaymentDelta, original.Amount)
+		return
+	}
+
+	if revision.PaymentAction == models.RevisionPaymentRefund {
+		paymentID, err := uuid.Parse(original.ID)
+		if err != nil {
+			revision.PaymentError = fmt.Sprintf("invalid payment ID %q", original.ID)
+			return
+		}
+		refund, err := s.paymentClient.CreateRefund(paymentID, clients.CreateRefundRequest{
+			Amount: -revision.PaymentDelta,
+			Reason: "order_edited",
+			Notes:  fmt.Sprintf("Order %s revision %d", order.OrderNumber, revision.Revision),
+		}, tenantID)
+		if err != nil {
+			revision.PaymentError = err.Error()
+			return
+		}
+		revision.PaymentReference = refund.ID
+		revision.PaymentStatus = models.RevisionPaymentPending
+		if refund.Status == "SUCCEEDED" || refund.Status == "COMPLETED" {
+			revision.PaymentStatus = models.RevisionPaymentSucceeded
+		}
+		return
+	}
+
+	intentReq := clients.CreatePaymentIntentRequest{
+		TenantID:    tenantID,
+		OrderID:     order.ID.String(),
+		Amount:      revision.PaymentDelta,
+		Currency:    order.Currency,
+		CustomerID:  &order.CustomerID,
+		GatewayType: original.GatewayType,
+		Description: fmt.Sprintf("Additional charge for order %s", order.OrderNumber),
+		Metadata: map[string]string{
+			"orderId":  order.ID.String(),
+			"revision": fmt.Sprintf("%d", revision.Revision),
+		},
+	}
+	if order.Customer != nil {
+		intentReq.CustomerEmail = order.Customer.Email
+	}
+	intent, err := s.paymentClient.CreatePaymentIntent(intentReq, tenantID)
+	if err != nil {
+		revision.PaymentError = err.Error()
+		return
+	}
+	var ref struct {
+		PaymentIntentID string `json:"paymentIntentId"`
+	}
+	_ = json.Unmarshal(intent, &ref)
+	revision.PaymentReference = ref.PaymentIntentID
+	revision.PaymentIntent = models.JSONB(intent)
+	revision.PaymentStatus = models.RevisionPaymentPending
+}
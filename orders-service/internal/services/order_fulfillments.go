@@ -0,0 +1,459 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"orders-service/internal/clients"
+	"orders-service/internal/models"
+)
+
+var (
+	// ErrOrderNotFulfillable is returned when an order can't be shipped in parcels: it is
+	// cancelled, unpaid, collected in store, fulfilled by its vendor orders, or already shipped whole
+	ErrOrderNotFulfillable = errors.New("order cannot be fulfilled in parts")
+	// ErrInvalidFulfillment is returned when a fulfillment refers to items not on the order,
+	// ships more than is left, or moves to a status it can't reach
+	ErrInvalidFulfillment = errors.New("invalid fulfillment")
+	// ErrOrderHasFulfillments is returned when an order-level fulfillment update is made to an
+	// order that ships in parcels; each parcel is updated instead
+	ErrOrderHasFulfillments = errors.New("order is fulfilled through its fulfillments")
+	// ErrShipmentBookingFailed is returned when shipping-service couldn't book a parcel
+	ErrShipmentBookingFailed = errors.New("failed to book shipment")
+)
+
+// CreateFulfillmentRequest ships some of an order's items in their own parcel. Without a
+// tracking number the parcel is booked with shipping-service unless CreateShipment is false.
+type CreateFulfillmentRequest struct {
+	Items          []CreateFulfillmentItemRequest `json:"items" binding:"required,min=1,dive"`
+	Carrier        string                         `json:"carrier"`
+	TrackingNumber string                         `json:"trackingNumber"`
+	TrackingURL    string                         `json:"trackingUrl"`
+	CreateShipment *bool                          `json:"createShipment,omitempty"`
+	Notes          string                         `json:"notes"`
+}
+
+// CreateFulfillmentItemRequest is an order item and how many of it go in the parcel
+type CreateFulfillmentItemRequest struct {
+	OrderItemID uuid.UUID `json:"orderItemId" binding:"required"`
+	Quantity    int       `json:"quantity" binding:"required,min=1"`
+}
+
+// UpdateOrderFulfillmentRequest moves one parcel along, adding its tracking if it wasn't known
+// when the parcel was created
+type UpdateOrderFulfillmentRequest struct {
+	Status         models.FulfillmentStatus `json:"status" binding:"required"`
+	Carrier        string                   `json:"carrier"`
+	TrackingNumber string                   `json:"trackingNumber"`
+	TrackingURL    string                   `json:"trackingUrl"`
+	Notes          string                   `json:"notes"`
+}
+
+// CreateFulfillment puts some of an order's remaining items in a parcel. A parcel with a
+// tracking number is dispatched straight away; one booked with shipping-service is packed
+// with its label until it is handed to the carrier.
+func (s *orderService) CreateFulfillment(id uuid.UUID, req CreateFulfillmentRequest, createdBy *uuid.UUID, tenantID string) (*models.OrderFulfillment, error) {
+	order, err := s.orderRepo.GetByID(id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkFulfillable(order, tenantID); err != nil {
+		return nil, err
+	}
+
+	fulfillment := &models.OrderFulfillment{
+		ID:             uuid.New(),
+		TenantID:       tenantID,
+		OrderID:        order.ID,
+		Status:         models.FulfillmentStatusPacked,
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+		TrackingURL:    req.TrackingURL,
+		Notes:          req.Notes,
+		CreatedBy:      createdBy,
+	}
+	if err := packFulfillmentItems(order, fulfillment, req.Items); err != nil {
+		return nil, err
+	}
+
+	if fulfillment.TrackingNumber == "" && (req.CreateShipment == nil || *req.CreateShipment) && s.shippingClient != nil {
+		shipment, err := s.bookFulfillmentShipment(order, fulfillment, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrShipmentBookingFailed, err)
+		}
+		fulfillment.ShipmentID = shipment.ID
+		fulfillment.LabelURL = shipment.LabelURL
+		fulfillment.TrackingNumber = shipment.TrackingNumber
+		if shipment.Carrier != "" {
+			fulfillment.Carrier = shipment.Carrier
+		}
+	} else if req.TrackingNumber != "" {
+		now := time.Now()
+		fulfillment.Status = models.FulfillmentStatusDispatched
+		fulfillment.ShippedAt = &now
+	}
+
+	if err := s.orderRepo.CreateFulfillment(order, fulfillment); err != nil {
+		if fulfillment.ShipmentID != "" {
+			fmt.Printf("WARNING: Shipment %s was booked for order %s but its fulfillment wasn't saved\n", fulfillment.ShipmentID, order.OrderNumber)
+		}
+		return nil, err
+	}
+
+	if fulfillment.Status == models.FulfillmentStatusDispatched {
+		s.notifyFulfillmentShipped(order, fulfillment, tenantID)
+	}
+	s.rollUpFulfillments(order.ID, tenantID)
+
+	return fulfillment, nil
+}
+
+// ListFulfillments returns an order's parcels, oldest first
+func (s *orderService) ListFulfillments(id uuid.UUID, tenantID string) ([]models.OrderFulfillment, error) {
+	if _, err := s.orderRepo.GetByID(id, tenantID); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.ListFulfillments(id, tenantID)
+}
+
+// UpdateFulfillment moves one of an order's parcels to a new status and rolls the order's
+// fulfillment status up from all of its parcels
+func (s *orderService) UpdateFulfillment(id, fulfillmentID uuid.UUID, req UpdateOrderFulfillmentRequest, tenantID string) (*models.OrderFulfillment, error) {
+	order, err := s.orderRepo.GetByID(id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == models.OrderStatusCancelled {
+		return nil, fmt.Errorf("%w: order is cancelled", ErrOrderNotFulfillable)
+	}
+
+	fulfillment, err := s.orderRepo.GetFulfillment(fulfillmentID, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status == models.FulfillmentStatusUnfulfilled {
+		return nil, fmt.Errorf("%w: a parcel can't go back to unfulfilled", ErrInvalidFulfillment)
+	}
+	if err := models.ValidateFulfillmentStatusTransition(fulfillment.Status, req.Status); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFulfillment, err)
+	}
+	if err := models.ValidateFulfillmentStatusForType(req.Status, order.FulfillmentType); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFulfillment, err)
+	}
+
+	if req.Carrier != "" {
+		fulfillment.Carrier = req.Carrier
+	}
+	if req.TrackingNumber != "" {
+		fulfillment.TrackingNumber = req.TrackingNumber
+	}
+	if req.TrackingURL != "" {
+		fulfillment.TrackingURL = req.TrackingURL
+	}
+	fulfillment.Status = req.Status
+	now := time.Now()
+	switch req.Status {
+	case models.FulfillmentStatusDispatched, models.FulfillmentStatusInTransit:
+		if fulfillment.ShippedAt == nil {
+			fulfillment.ShippedAt = &now
+		}
+	case models.FulfillmentStatusDelivered:
+		fulfillment.DeliveredAt = &now
+	}
+	fulfillment.UpdatedAt = now
+
+	if err := s.orderRepo.UpdateFulfillment(fulfillment, req.Notes); err != nil {
+		return nil, err
+	}
+
+	if req.Status == models.FulfillmentStatusDispatched {
+		s.notifyFulfillmentShipped(order, fulfillment, tenantID)
+	}
+	s.rollUpFulfillments(order.ID, tenantID)
+
+	return fulfillment, nil
+}
+
+// checkFulfillable returns an error unless the order can be shipped in parcels
+func (s *orderService) checkFulfillable(order *models.Order, tenantID string) error {
+	switch {
+	case order.Status == models.OrderStatusCancelled:
+		return fmt.Errorf("%w: order is cancelled", ErrOrderNotFulfillable)
+	case order.PaymentStatus != models.PaymentStatusPaid && order.PaymentStatus != models.PaymentStatusPartiallyRefunded:
+		return fmt.Errorf("%w: payment is not confirmed", ErrOrderNotFulfillable)
+	case order.IsPickup():
+		return fmt.Errorf("%w: order is collected from a pickup location", ErrOrderNotFulfillable)
+	case order.IsVendorSplitParent():
+		return fmt.Errorf("%w: order is fulfilled through its vendor orders", ErrOrderNotFulfillable)
+	}
+
+	switch order.FulfillmentStatus {
+	case models.FulfillmentStatusUnfulfilled, models.FulfillmentStatusProcessing,
+		models.FulfillmentStatusPacked, models.FulfillmentStatusPartiallyShipped:
+		return nil
+	}
+	// Past packing without parcels means the order was shipped whole
+	has, err := s.orderRepo.HasFulfillments(order.ID, tenantID)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return fmt.Errorf("%w: order was already shipped as a whole", ErrOrderNotFulfillable)
+	}
+	return nil
+}
+
+// packFulfillmentItems adds the requested quantities to the fulfillment and to the order
+// items' fulfilled quantities, rejecting anything beyond what is left to ship
+func packFulfillmentItems(order *models.Order, fulfillment *models.OrderFulfillment, requested []CreateFulfillmentItemRequest) error {
+	quantities := make(map[uuid.UUID]int, len(requested))
+	var itemOrder []uuid.UUID
+	for _, item := range requested {
+		if _, seen := quantities[item.OrderItemID]; !seen {
+			itemOrder = append(itemOrder, item.OrderItemID)
+		}
+		quantities[item.OrderItemID] += item.Quantity
+	}
+
+	for _, itemID := range itemOrder {
+		quantity := quantities[itemID]
+		var item *models.OrderItem
+		for i := range order.Items {
+			if order.Items[i].ID == itemID {
+				item = &order.Items[i]
+				break
+			}
+		}
+		if item == nil {
+			return fmt.Errorf("%w: item %s is not on this order", ErrInvalidFulfillment, itemID)
+		}
+		if quantity > item.UnfulfilledQuantity() {
+			return fmt.Errorf("%w: only %d of %s left to fulfill", ErrInvalidFulfillment, item.UnfulfilledQuantity(), item.SKU)
+		}
+
+		item.FulfilledQuantity += quantity
+		item.FulfillmentStatus = models.ItemFulfillmentStatusFor(item.FulfilledQuantity, item.Quantity)
+		fulfillment.Items = append(fulfillment.Items, models.OrderFulfillmentItem{
+			ID:            uuid.New(),
+			FulfillmentID: fulfillment.ID,
+			OrderItemID:   item.ID,
+			ProductName:   item.ProductName,
+			SKU:           item.SKU,
+			Quantity:      quantity,
+		})
+	}
+	return nil
+}
+
+// fulfillmentOrderItems returns the order's items as they are in the parcel, each with the
+// quantity packed in it
+func fulfillmentOrderItems(order *models.Order, fulfillment *models.OrderFulfillment) []models.OrderItem {
+	var items []models.OrderItem
+	for _, packed := range fulfillment.Items {
+		for _, item := range order.Items {
+			if item.ID == packed.OrderItemID {
+				item.Quantity = packed.Quantity
+				item.TotalPrice = roundCurrency(item.UnitPrice * float64(packed.Quantity))
+				items = append(items, item)
+				break
+			}
+		}
+	}
+	return items
+}
+
+// bookFulfillmentShipment books a parcel with shipping-service from the tenant's warehouse,
+// sized from the products in it rather than the whole order's package
+func (s *orderService) bookFulfillmentShipment(order *models.Order, fulfillment *models.OrderFulfillment, tenantID string) (*clients.ShipmentResponse, error) {
+	if order.Shipping == nil || order.Customer == nil {
+		return nil, fmt.Errorf("order has no shipping information")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	settings, err := s.shippingClient.GetShippingSettings(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch shipping settings: %w", err)
+	}
+	if settings == nil || settings.Warehouse == nil {
+		return nil, fmt.Errorf("no warehouse address configured")
+	}
+
+	parcel := *order
+	parcel.Items = fulfillmentOrderItems(order, fulfillment)
+	shipping := *order.Shipping
+	shipping.PackageWeight, shipping.PackageLength, shipping.PackageWidth, shipping.PackageHeight = 0, 0, 0, 0
+	parcel.Shipping = &shipping
+	weight, length, width, height := s.calculateShipmentMetrics(&parcel, tenantID)
+
+	var shipmentItems []clients.ShipmentItem
+	var value float64
+	for _, item := range parcel.Items {
+		shipmentItems = append(shipmentItems, clients.ShipmentItem{
+			Name:     item.ProductName,
+			SKU:      item.SKU,
+			Quantity: item.Quantity,
+			Price:    item.UnitPrice,
+		})
+		value += item.TotalPrice
+	}
+
+	carrier := fulfillment.Carrier
+	if carrier == "" {
+		carrier = order.Shipping.Carrier
+	}
+
+	return s.shippingClient.CreateShipment(ctx, &clients.CreateShipmentRequest{
+		TenantID:    tenantID,
+		OrderID:     order.ID.String(),
+		OrderNumber: order.OrderNumber,
+		FromAddress: settings.Warehouse,
+		ToAddress: &clients.ShipmentAddress{
+			Name:       fmt.Sprintf("%s %s", order.Customer.FirstName, order.Customer.LastName),
+			Phone:      order.Customer.Phone,
+			Email:      order.Customer.Email,
+			Street:     order.Shipping.Street,
+			City:       order.Shipping.City,
+			State:      order.Shipping.State,
+			PostalCode: order.Shipping.PostalCode,
+			Country:    order.Shipping.Country,
+		},
+		Weight:             weight,
+		Length:             length,
+		Width:              width,
+		Height:             height,
+		Carrier:            carrier,
+		CourierServiceCode: order.Shipping.CourierServiceCode,
+		Items:              shipmentItems,
+		OrderValue:         roundCurrency(value),
+	})
+}
+
+// notifyFulfillmentShipped tells the customer a parcel is on its way, listing only the items in it
+func (s *orderService) notifyFulfillmentShipped(order *models.Order, fulfillment *models.OrderFulfillment, tenantID string) {
+	if s.eventsPublisher != nil {
+		s.eventsPublisher.PublishOrderShipped(context.Background(), order, tenantID)
+	}
+	if s.notificationClient == nil || order.Customer == nil || order.Customer.Email == "" {
+		return
+	}
+
+	parcel := *order
+	parcel.Items = fulfillmentOrderItems(order, fulfillment)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		notification := s.buildOrderNotification(ctx, &parcel, tenantID)
+		notification.Carrier = fulfillment.Carrier
+		notification.TrackingNumber = fulfillment.TrackingNumber
+		notification.TrackingURL = fulfillment.TrackingURL
+		if err := s.notificationClient.SendOrderShipped(ctx, notification); err != nil {
+			fmt.Printf("WARNING: Failed to send fulfillment shipped email: %v\n", err)
+		}
+	}()
+}
+
+// rollUpFulfillments sets the order's fulfillment status from its parcels. Once every item is in
+// a parcel the order is as far along as its least advanced parcel; before that it is partially
+// shipped if any parcel has gone, otherwise still processing. Failed deliveries and returns are
+// left on their parcels.
+func (s *orderService) rollUpFulfillments(orderID uuid.UUID, tenantID string) {
+	order, err := s.orderRepo.GetByID(orderID, tenantID)
+	if err != nil {
+		fmt.Printf("WARNING: Failed to load order %s for fulfillment roll-up: %v\n", orderID, err)
+		return
+	}
+	fulfillments, err := s.orderRepo.ListFulfillments(orderID, tenantID)
+	if err != nil {
+		fmt.Printf("WARNING: Failed to load fulfillments of order %s: %v\n", order.OrderNumber, err)
+		return
+	}
+
+	least := -1
+	shipped := false
+	var leastStatus models.FulfillmentStatus
+	for _, fulfillment := range fulfillments {
+		rank, ok := fulfillmentProgress[fulfillment.Status]
+		if !ok {
+			continue
+		}
+		if rank >= fulfillmentProgress[models.FulfillmentStatusDispatched] {
+			shipped = true
+		}
+		if least == -1 || rank < least {
+			least = rank
+			leastStatus = fulfillment.Status
+		}
+	}
+	if least == -1 {
+		return
+	}
+
+	allPacked := true
+	for _, item := range order.Items {
+		if item.UnfulfilledQuantity() > 0 {
+			allPacked = false
+			break
+		}
+	}
+
+	rolledUp := leastStatus
+	if !allPacked {
+		rolledUp = models.FulfillmentStatusProcessing
+		if shipped {
+			rolledUp = models.FulfillmentStatusPartiallyShipped
+		}
+	}
+	if rolledUp == order.FulfillmentStatus {
+		return
+	}
+
+	if err := s.orderRepo.UpdateFulfillmentStatus(order.ID, rolledUp, "Updated from fulfillments", tenantID); err != nil {
+		fmt.Printf("WARNING: Failed to roll up fulfillment for order %s: %v\n", order.OrderNumber, err)
+		return
+	}
+
+	status := order.Status
+	if status == models.OrderStatusConfirmed {
+		if err := s.orderRepo.UpdateStatus(order.ID, models.OrderStatusProcessing, "Fulfillment started", tenantID); err != nil {
+			fmt.Printf("WARNING: Failed to update order status to processing: %v\n", err)
+			return
+		}
+		status = models.OrderStatusProcessing
+	}
+
+	updatedOrder, err := s.orderRepo.GetByID(order.ID, tenantID)
+	if err != nil {
+		fmt.Printf("WARNING: Failed to reload order %s after fulfillment roll-up: %v\n", order.OrderNumber, err)
+		return
+	}
+	if updatedOrder.IsVendorSplitChild() {
+		s.rollUpVendorFulfillment(updatedOrder, tenantID)
+	}
+
+	if rolledUp != models.FulfillmentStatusDelivered || status != models.OrderStatusProcessing {
+		return
+	}
+	if err := s.orderRepo.UpdateStatus(order.ID, models.OrderStatusCompleted, "All fulfillments delivered", tenantID); err != nil {
+		fmt.Printf("WARNING: Failed to update order status to completed: %v\n", err)
+		return
+	}
+	if s.notificationClient != nil && updatedOrder.Customer != nil && updatedOrder.Customer.Email != "" {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			notification := s.buildOrderNotification(ctx, updatedOrder, tenantID)
+			notification.DeliveryDate = time.Now().Format("January 2, 2006")
+			if err := s.notificationClient.SendOrderDelivered(ctx, notification); err != nil {
+				fmt.Printf("WARNING: Failed to send order delivered email: %v\n", err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/xuri/excelize/v2"
+	"orders-service/internal/models"
+)
+
+// importRowReader streams data rows from a CSV or XLSX file so large imports never hold the
+// whole sheet in memory
+type importRowReader struct {
+	headers []string
+	next    func() ([]string, int, error) // Returns io.EOF when exhausted
+	close   func()
+}
+
+// newImportRowReader opens a row reader and consumes the header row. For XLSX files a sheet
+// named "Orders" is preferred over the first sheet.
+func newImportRowReader(format models.OrderImportFormat, data []byte) (*importRowReader, error) {
+	var r *importRowReader
+
+	switch format {
+	case models.OrderImportCSV:
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		line := 0
+		r = &importRowReader{
+			next: func() ([]string, int, error) {
+				for {
+					record, err := reader.Read()
+					if err != nil {
+						if err != io.EOF {
+							err = fmt.Errorf("error reading line %d: %w", line+1, err)
+						}
+						return nil, 0, err
+					}
+					line++
+					if !isBlankRow(record) {
+						return record, line, nil
+					}
+				}
+			},
+			close: func() {},
+		}
+	case models.OrderImportXLSX:
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Excel file: %w", err)
+		}
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			f.Close()
+			return nil, fmt.Errorf("no sheets found in Excel file")
+		}
+		sheetName := sheets[0]
+		for _, name := range sheets {
+			if strings.EqualFold(name, "Orders") {
+				sheetName = name
+				break
+			}
+		}
+
+		rows, err := f.Rows(sheetName)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to read sheet: %w", err)
+		}
+		line := 0
+		r = &importRowReader{
+			next: func() ([]string, int, error) {
+				for rows.Next() {
+					line++
+					cols, err := rows.Columns()
+					if err != nil {
+						return nil, 0, fmt.Errorf("error reading row %d: %w", line, err)
+					}
+					if !isBlankRow(cols) {
+						return cols, line, nil
+					}
+				}
+				if err := rows.Error(); err != nil {
+					return nil, 0, fmt.Errorf("failed to read sheet: %w", err)
+				}
+				return nil, 0, io.EOF
+			},
+			close: func() {
+				rows.Close()
+				f.Close()
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+
+	headers, _, err := r.next()
+	if err != nil {
+		r.close()
+		if err == io.EOF {
+			return nil, fmt.Errorf("file is missing a header row")
+		}
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	r.headers = make([]string, len(headers))
+	for i, header := range headers {
+		r.headers[i] = normalizeImportHeader(header)
+	}
+	return r, nil
+}
+
+// Read returns the next data row keyed by normalized source header, with "_row" set to the
+// row's line number in the file
+func (r *importRowReader) Read() (map[string]string, error) {
+	record, line, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]string, len(r.headers)+1)
+	for i, value := range record {
+		if i < len(r.headers) && r.headers[i] != "" {
+			row[r.headers[i]] = strings.TrimSpace(value)
+		}
+	}
+	row["_row"] = strconv.Itoa(line)
+	return row, nil
+}
+
+// ReadBatch reads up to size data rows; an empty slice means the file is exhausted
+func (r *importRowReader) ReadBatch(size int) ([]map[string]string, error) {
+	rows := make([]map[string]string, 0, size)
+	for len(rows) < size {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Skip discards n data rows, for resuming a job
+func (r *importRowReader) Skip(n int) error {
+	for i := 0; i < n; i++ {
+		if _, _, err := r.next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close releases the underlying file
+func (r *importRowReader) Close() {
+	r.close()
+}
+
+func isBlankRow(cols []string) bool {
+	for _, c := range cols {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// countImportRows counts the data rows in an import file
+func countImportRows(format models.OrderImportFormat, data []byte) (int, error) {
+	reader, err := newImportRowReader(format, data)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	count := 0
+	for {
+		if _, _, err := reader.next(); err != nil {
+			if err == io.EOF {
+				return count, nil
+			}
+			return count, err
+		}
+		count++
+	}
+}
+
+// DetectImportFormat determines the import format from the uploaded file name
+func DetectImportFormat(filename string) (models.OrderImportFormat, bool) {
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".csv"):
+		return models.OrderImportCSV, true
+	case strings.HasSuffix(lower, ".xlsx"):
+		return models.OrderImportXLSX, true
+	}
+	return "", false
+}
+
+// importColumnAliases maps the canonical form of headers used by other commerce platforms'
+// order exports to import columns
+var importColumnAliases = map[string]string{
+	"name":             "orderNumber", // Shopify exports the order number as "Name"
+	"order":            "orderNumber",
+	"orderid":          "orderNumber",
+	"ordername":        "orderNumber",
+	"createdat":        "orderDate",
+	"date":             "orderDate",
+	"datecreated":      "orderDate",
+	"orderdatetime":    "orderDate",
+	"emailaddress":     "email",
+	"customeremail":    "email",
+	"billingemail":     "email",
+	"billingfirstname": "firstName",
+	"billinglastname":  "lastName",
+	"billingphone":     "phone",
+	"phonenumber":      "phone",
+	"currencycode":     "currency",
+	"orderstatus":      "status",
+	"financialstatus":  "paymentStatus",
+	"paymentgateway":   "paymentMethod",
+	"lineitemsku":      "sku",
+	"itemsku":          "sku",
+	"variantsku":       "sku",
+	"lineitemname":     "productName",
+	"itemname":         "productName",
+	"product":          "productName",
+	"lineitemquantity": "quantity",
+	"itemquantity":     "quantity",
+	"qty":              "quantity",
+	"lineitemprice":    "unitPrice",
+	"itemprice":        "unitPrice",
+	"price":            "unitPrice",
+	"lineitemtax":      "itemTax",
+	"taxes":            "taxAmount",
+	"tax":              "taxAmount",
+	"totaltax":         "taxAmount",
+	"shipping":         "shippingCost",
+	"shippingtotal":    "shippingCost",
+	"discount":         "discountAmount",
+	"totaldiscount":    "discountAmount",
+	"ordertotal":       "total",
+	"grandtotal":       "total",
+	"totalprice":       "total",
+	"note":             "notes",
+	"trackingcompany":  "carrier",
+	"shippingaddress1": "street",
+	"shippingstreet":   "street",
+	"address1":         "street",
+	"shippingcity":     "city",
+	"shippingprovince": "state",
+	"shippingstate":    "state",
+	"province":         "state",
+	"shippingzip":      "postalCode",
+	"zip":              "postalCode",
+	"zipcode":          "postalCode",
+	"postcode":         "postalCode",
+	"shippingcountry":  "countryCode",
+}
+
+// normalizeImportHeader trims a source header, lower-cases it and drops a required marker
+func normalizeImportHeader(header string) string {
+	return strings.TrimSuffix(strings.TrimSpace(strings.ToLower(header)), " *")
+}
+
+// canonicalImportKey strips case and punctuation so "Order Number", "order_number" and
+// "orderNumber" compare equal
+func canonicalImportKey(header string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(header) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// importColumnByKey indexes the import columns and their aliases by canonical key
+func importColumnByKey() map[string]string {
+	byKey := make(map[string]string, len(models.OrderImportColumns)+len(importColumnAliases))
+	for alias, column := range importColumnAliases {
+		byKey[alias] = column
+	}
+	for _, col := range models.OrderImportColumns {
+		byKey[canonicalImportKey(col.Name)] = col.Name
+	}
+	return byKey
+}
+
+// SuggestColumnMapping matches source headers to import columns by name or a known alias
+func SuggestColumnMapping(headers []string) map[string]string {
+	byKey := importColumnByKey()
+	suggested := make(map[string]string)
+	for _, header := range headers {
+		if column, ok := byKey[canonicalImportKey(header)]; ok {
+			suggested[header] = column
+		}
+	}
+	return suggested
+}
+
+// NormalizeColumnMapping normalizes the source headers of a caller's mapping and resolves its
+// targets to import column names. Empty targets are dropped, so a header can be ignored by
+// mapping it to "".
+func NormalizeColumnMapping(mapping map[string]string) (map[string]string, error) {
+	byKey := make(map[string]string, len(models.OrderImportColumns))
+	for _, col := range models.OrderImportColumns {
+		byKey[canonicalImportKey(col.Name)] = col.Name
+	}
+
+	normalized := make(map[string]string, len(mapping))
+	for source, target := range mapping {
+		source = normalizeImportHeader(source)
+		if source == "" {
+			continue
+		}
+		if strings.TrimSpace(target) == "" {
+			normalized[source] = ""
+			continue
+		}
+		column, ok := byKey[canonicalImportKey(target)]
+		if !ok {
+			return nil, fmt.Errorf("'%s' is not an order import column", target)
+		}
+		normalized[source] = column
+	}
+	return normalized, nil
+}
+
+// resolveColumnMapping combines the suggested mapping for the file's headers with the caller's
+// explicit mapping, which wins
+func resolveColumnMapping(headers []string, explicit map[string]string) map[string]string {
+	mapping := SuggestColumnMapping(headers)
+	for source, target := range explicit {
+		if target == "" {
+			delete(mapping, source)
+			continue
+		}
+		mapping[source] = target
+	}
+	return mapping
+}
+
+// applyColumnMapping re-keys a row from source headers to import columns. The first non-empty
+// value wins when several headers map to the same column.
+func applyColumnMapping(row map[string]string, mapping map[string]string) map[string]string {
+	mapped := map[string]string{"_row": row["_row"]}
+	for header, value := range row {
+		column, ok := mapping[header]
+		if !ok || value == "" {
+			continue
+		}
+		if mapped[column] == "" {
+			mapped[column] = value
+		}
+	}
+	return mapped
+}
+
+// parseImportAmount parses a money cell, tolerating currency symbols and thousands separators
+func parseImportAmount(value string) (float64, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) || r == '.' || r == '-' {
+			return r
+		}
+		return -1
+	}, value)
+	if cleaned == "" {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return strconv.ParseFloat(cleaned, 64)
+}
@@ -0,0 +1,766 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"orders-service/internal/clients"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+var (
+	// ErrImportJobNotFound is returned for import jobs that don't exist in the tenant
+	ErrImportJobNotFound = errors.New("import job not found")
+	// ErrImportJobNotResumable is returned when resuming a job that has not failed
+	ErrImportJobNotResumable = errors.New("import job has not failed and cannot be resumed")
+)
+
+// ImportValidationError is a problem with an import request that the caller can fix
+type ImportValidationError struct {
+	Code    string
+	Message string
+}
+
+func (e *ImportValidationError) Error() string {
+	return e.Message
+}
+
+// importedProductNamespace derives stub product IDs for imported items without a productId.
+// The ID is stable per tenant and SKU, so an imported SKU's sales group together in reports.
+var importedProductNamespace = uuid.MustParse("6f1c2a4e-3b7d-5e8f-9a0b-1c2d3e4f5a6b")
+
+// OrderImportService imports historical orders from CSV and XLSX files. Uploads are queued as
+// jobs and processed in chunks by the order import job. Imported orders are written directly
+// with their final statuses, bypassing checkout: no payment is taken, no inventory is deducted,
+// no emails or events are sent and no shipments are created.
+type OrderImportService struct {
+	repo            *repository.OrderImportRepository
+	customersClient clients.CustomersClient
+}
+
+// NewOrderImportService creates a new order import service
+func NewOrderImportService(repo *repository.OrderImportRepository, customersClient clients.CustomersClient) *OrderImportService {
+	return &OrderImportService{
+		repo:            repo,
+		customersClient: customersClient,
+	}
+}
+
+// CreateOrderImportRequest describes an uploaded order import
+type CreateOrderImportRequest struct {
+	TenantID        string
+	CreatedBy       string
+	FileName        string
+	Format          models.OrderImportFormat
+	Data            []byte
+	Mapping         map[string]string // Normalized with NormalizeColumnMapping
+	Source          string
+	StorefrontID    string
+	Currency        string
+	CreateCustomers bool
+}
+
+// PreviewImport shows how the first rows of a file would be mapped and grouped into orders, and
+// which of those orders would fail validation, without importing anything
+func (s *OrderImportService) PreviewImport(format models.OrderImportFormat, data []byte, explicit map[string]string, limit int) (*models.OrderImportPreview, error) {
+	total, err := countImportRows(format, data)
+	if err != nil {
+		return nil, &ImportValidationError{Code: "PARSE_ERROR", Message: err.Error()}
+	}
+
+	reader, err := newImportRowReader(format, data)
+	if err != nil {
+		return nil, &ImportValidationError{Code: "PARSE_ERROR", Message: err.Error()}
+	}
+	defer reader.Close()
+
+	rows, err := reader.ReadBatch(limit)
+	if err != nil {
+		return nil, &ImportValidationError{Code: "PARSE_ERROR", Message: err.Error()}
+	}
+
+	mapping := resolveColumnMapping(reader.headers, explicit)
+	preview := &models.OrderImportPreview{
+		SourceHeaders:    reader.headers,
+		SuggestedMapping: SuggestColumnMapping(reader.headers),
+		AppliedMapping:   mapping,
+		TotalRows:        total,
+		Rows:             make([]map[string]string, 0, len(rows)),
+		Errors:           make([]models.OrderImportError, 0),
+	}
+
+	mapped := make(map[string]bool)
+	for _, header := range reader.headers {
+		if column, ok := mapping[header]; ok {
+			mapped[column] = true
+		} else if header != "" {
+			preview.UnmappedHeaders = append(preview.UnmappedHeaders, header)
+		}
+	}
+	for _, col := range models.OrderImportColumns {
+		if col.Required && !mapped[col.Name] {
+			preview.MissingRequired = append(preview.MissingRequired, col.Name)
+		}
+	}
+
+	for _, row := range rows {
+		preview.Rows = append(preview.Rows, applyColumnMapping(row, mapping))
+	}
+	for _, group := range groupImportRows(preview.Rows) {
+		preview.OrderCount++
+		if _, rowErrors := parseImportOrder(group); len(rowErrors) > 0 {
+			preview.Errors = append(preview.Errors, rowErrors...)
+		} else {
+			preview.ValidOrderCount++
+		}
+	}
+	return preview, nil
+}
+
+// CreateImportJob validates an upload and queues it for the order import job
+func (s *OrderImportService) CreateImportJob(ctx context.Context, req CreateOrderImportRequest) (*models.OrderImportJob, error) {
+	req.Source = strings.ToLower(strings.TrimSpace(req.Source))
+	if req.Source == "" {
+		return nil, &ImportValidationError{Code: "INVALID_SOURCE", Message: "source is required, e.g. the previous platform or 'offline'"}
+	}
+	if len(req.Source) > 50 {
+		return nil, &ImportValidationError{Code: "INVALID_SOURCE", Message: "source must be at most 50 characters"}
+	}
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if req.Currency == "" {
+		req.Currency = "USD"
+	}
+	if len(req.Currency) != 3 {
+		return nil, &ImportValidationError{Code: "INVALID_CURRENCY", Message: "currency must be a 3-letter ISO code"}
+	}
+
+	total, err := countImportRows(req.Format, req.Data)
+	if err != nil {
+		return nil, &ImportValidationError{Code: "PARSE_ERROR", Message: err.Error()}
+	}
+	if total == 0 {
+		return nil, &ImportValidationError{Code: "EMPTY_FILE", Message: "The file has no order rows"}
+	}
+	if total > models.MaxOrderImportRows {
+		return nil, &ImportValidationError{
+			Code:    "TOO_MANY_ROWS",
+			Message: fmt.Sprintf("The file has %d rows; imports are limited to %d rows", total, models.MaxOrderImportRows),
+		}
+	}
+
+	job := &models.OrderImportJob{
+		TenantID:        req.TenantID,
+		Status:          models.OrderImportPending,
+		FileName:        req.FileName,
+		Format:          req.Format,
+		FileData:        req.Data,
+		Source:          req.Source,
+		StorefrontID:    req.StorefrontID,
+		Currency:        req.Currency,
+		CreateCustomers: req.CreateCustomers,
+		TotalRows:       total,
+	}
+	if req.CreatedBy != "" {
+		job.CreatedBy = &req.CreatedBy
+	}
+	if len(req.Mapping) > 0 {
+		raw, err := json.Marshal(req.Mapping)
+		if err != nil {
+			return nil, err
+		}
+		job.ColumnMapping = models.JSONB(raw)
+	}
+
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to queue import job: %w", err)
+	}
+	return job, nil
+}
+
+// ListImportJobs lists the tenant's import jobs, newest first
+func (s *OrderImportService) ListImportJobs(ctx context.Context, tenantID string, page, pageSize int) ([]models.OrderImportJob, int64, error) {
+	return s.repo.List(ctx, tenantID, pageSize, (page-1)*pageSize)
+}
+
+// GetImportJob retrieves one of the tenant's import jobs
+func (s *OrderImportService) GetImportJob(ctx context.Context, tenantID string, jobID uuid.UUID) (*models.OrderImportJob, error) {
+	job, err := s.repo.GetByID(ctx, tenantID, jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImportJobNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetImportErrors retrieves the per-row error report of an import job
+func (s *OrderImportService) GetImportErrors(ctx context.Context, tenantID string, jobID uuid.UUID) ([]models.OrderImportError, error) {
+	if _, err := s.GetImportJob(ctx, tenantID, jobID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetErrors(ctx, tenantID, jobID)
+}
+
+// ResumeImportJob re-queues a failed import job, which continues after its last committed chunk
+func (s *OrderImportService) ResumeImportJob(ctx context.Context, tenantID string, jobID uuid.UUID) (*models.OrderImportJob, error) {
+	if _, err := s.GetImportJob(ctx, tenantID, jobID); err != nil {
+		return nil, err
+	}
+	resumed, err := s.repo.Resume(ctx, tenantID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !resumed {
+		return nil, ErrImportJobNotResumable
+	}
+	return s.GetImportJob(ctx, tenantID, jobID)
+}
+
+// ProcessImportJob imports a claimed job chunk by chunk, committing progress and row errors
+// after every chunk so an interrupted job resumes where it stopped. An order's rows are never
+// split across chunks: the last order read is held back until the next chunk shows where it
+// ends. It returns ctx.Err() if interrupted by shutdown.
+func (s *OrderImportService) ProcessImportJob(ctx context.Context, job *models.OrderImportJob) error {
+	reader, err := newImportRowReader(job.Format, job.FileData)
+	if err != nil {
+		return s.repo.MarkFailed(ctx, job, err.Error())
+	}
+	defer reader.Close()
+
+	if err := reader.Skip(job.NextRow); err != nil && err != io.EOF {
+		return s.repo.MarkFailed(ctx, job, err.Error())
+	}
+
+	var explicit map[string]string
+	if len(job.ColumnMapping) > 0 {
+		if err := json.Unmarshal(job.ColumnMapping, &explicit); err != nil {
+			return s.repo.MarkFailed(ctx, job, "invalid column mapping")
+		}
+	}
+	mapping := resolveColumnMapping(reader.headers, explicit)
+
+	// Rows already read from the file but not yet committed; NextRow does not count them
+	var pending []map[string]string
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch, err := reader.ReadBatch(models.OrderImportChunkSize)
+		if err != nil {
+			return s.repo.MarkFailed(ctx, job, err.Error())
+		}
+		for i := range batch {
+			batch[i] = applyColumnMapping(batch[i], mapping)
+		}
+		rows := append(pending, batch...)
+		pending = nil
+		if len(rows) == 0 {
+			break
+		}
+
+		groups := groupImportRows(rows)
+		if len(batch) == models.OrderImportChunkSize {
+			// The file may continue the last order; an order longer than a chunk keeps growing
+			pending = groups[len(groups)-1]
+			groups = groups[:len(groups)-1]
+			if len(groups) == 0 {
+				continue
+			}
+		}
+
+		rowErrors, err := s.importChunk(ctx, job, groups)
+		if err != nil {
+			return s.repo.MarkFailed(ctx, job, fmt.Sprintf("failed to import rows %s-%s: %v", rows[0]["_row"], rows[len(rows)-1]["_row"], err))
+		}
+
+		job.NextRow += len(rows) - len(pending)
+		if err := s.repo.SaveChunk(ctx, job, rowErrors); err != nil {
+			// Progress was not committed; the chunk's orders are skipped as duplicates when resumed
+			return s.repo.MarkFailed(ctx, job, fmt.Sprintf("failed to save progress at row %s: %v", rows[0]["_row"], err))
+		}
+	}
+
+	return s.repo.MarkCompleted(ctx, job)
+}
+
+// importChunk writes one chunk of grouped order rows and returns the chunk's row errors
+func (s *OrderImportService) importChunk(ctx context.Context, job *models.OrderImportJob, groups [][]map[string]string) ([]models.OrderImportError, error) {
+	var rowErrors []models.OrderImportError
+	parsed := make([]*importedOrder, 0, len(groups))
+	numbers := make([]string, 0, len(groups))
+	for _, group := range groups {
+		order, errs := parseImportOrder(group)
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			job.FailedCount++
+			continue
+		}
+		parsed = append(parsed, order)
+		numbers = append(numbers, order.orderNumber)
+	}
+
+	existing, err := s.repo.ExistingOrderNumbers(ctx, job.TenantID, numbers)
+	if err != nil {
+		return nil, err
+	}
+
+	customerIDs := make(map[string]uuid.UUID)
+	for _, o := range parsed {
+		if existing[o.orderNumber] {
+			job.SkippedCount++
+			rowErrors = append(rowErrors, o.rowError(o.row, "orderNumber", "DUPLICATE", "An order with this number already exists; skipped"))
+			continue
+		}
+
+		customerID := uuid.Nil
+		if job.CreateCustomers && o.email != "" {
+			if id, ok := customerIDs[o.email]; ok {
+				customerID = id
+			} else if id, err := s.findOrCreateCustomer(o, job.TenantID); err != nil {
+				rowErrors = append(rowErrors, o.rowError(o.row, "email", "CUSTOMER_NOT_LINKED", fmt.Sprintf("Order imported as a guest order: %v", err)))
+			} else {
+				customerID = id
+				customerIDs[o.email] = id
+			}
+		}
+
+		order := o.newOrder(job, customerID)
+		if err := s.repo.CreateImportedOrder(ctx, order); err != nil {
+			job.FailedCount++
+			rowErrors = append(rowErrors, o.rowError(o.row, "", "WRITE_FAILED", fmt.Sprintf("Failed to save order: %v", err)))
+			continue
+		}
+		existing[o.orderNumber] = true
+		job.CreatedCount++
+	}
+	return rowErrors, nil
+}
+
+// findOrCreateCustomer links an imported order's email to a customers-service customer,
+// creating a stub customer from the order's contact details if there is none
+func (s *OrderImportService) findOrCreateCustomer(o *importedOrder, tenantID string) (uuid.UUID, error) {
+	if s.customersClient == nil {
+		return uuid.Nil, errors.New("customers service is not configured")
+	}
+	customer, err := s.customersClient.GetOrCreateCustomer(clients.CreateCustomerRequest{
+		Email:     o.email,
+		FirstName: o.firstName,
+		LastName:  o.lastName,
+		Phone:     o.phone,
+	}, tenantID)
+	if err != nil {
+		log.Printf("[OrderImport] Failed to find or create customer for order %s: %v", o.orderNumber, err)
+		return uuid.Nil, err
+	}
+	return uuid.Parse(customer.ID)
+}
+
+// groupImportRows splits mapped rows into orders: consecutive rows sharing an order number.
+// Rows without an order number each form their own (invalid) order.
+func groupImportRows(rows []map[string]string) [][]map[string]string {
+	var groups [][]map[string]string
+	for _, row := range rows {
+		last := len(groups) - 1
+		if last >= 0 && row["orderNumber"] != "" && groups[last][0]["orderNumber"] == row["orderNumber"] {
+			groups[last] = append(groups[last], row)
+			continue
+		}
+		groups = append(groups, []map[string]string{row})
+	}
+	return groups
+}
+
+// importedOrder is a validated group of import rows
+type importedOrder struct {
+	row               int // First row of the order
+	orderNumber       string
+	placedAt          time.Time
+	email             string // Lower-cased
+	firstName         string
+	lastName          string
+	phone             string
+	currency          string // Empty uses the job's currency
+	status            models.OrderStatus
+	paymentStatus     models.PaymentStatus
+	fulfillmentStatus models.FulfillmentStatus
+	paymentMethod     string
+	transactionID     string
+	items             []importedOrderItem
+	taxAmount         *float64 // nil sums the items' tax
+	shippingCost      float64
+	discountAmount    float64
+	total             *float64 // nil derives it from the other amounts
+	notes             string
+	shippingMethod    string
+	carrier           string
+	trackingNumber    string
+	address           *models.OrderShipping
+}
+
+// importedOrderItem is a validated line item row
+type importedOrderItem struct {
+	sku         string
+	productName string
+	productID   uuid.UUID // uuid.Nil gets a stub ID from the SKU
+	quantity    int
+	unitPrice   float64
+	taxAmount   float64
+}
+
+// importOrderDateLayouts are the order date formats found in other platforms' exports
+var importOrderDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05 -0700", // Shopify
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// importOrderStatuses maps order status spellings to order statuses
+var importOrderStatuses = map[string]models.OrderStatus{
+	"placed":     models.OrderStatusPlaced,
+	"pending":    models.OrderStatusPlaced,
+	"open":       models.OrderStatusPlaced,
+	"confirmed":  models.OrderStatusConfirmed,
+	"processing": models.OrderStatusProcessing,
+	"shipped":    models.OrderStatusShipped,
+	"delivered":  models.OrderStatusDelivered,
+	"completed":  models.OrderStatusCompleted,
+	"complete":   models.OrderStatusCompleted,
+	"closed":     models.OrderStatusCompleted,
+	"cancelled":  models.OrderStatusCancelled,
+	"canceled":   models.OrderStatusCancelled,
+}
+
+// importPaymentStatuses maps payment status spellings, including Shopify financial statuses
+var importPaymentStatuses = map[string]models.PaymentStatus{
+	"pending":            models.PaymentStatusPending,
+	"authorized":         models.PaymentStatusPending,
+	"unpaid":             models.PaymentStatusPending,
+	"paid":               models.PaymentStatusPaid,
+	"failed":             models.PaymentStatusFailed,
+	"voided":             models.PaymentStatusFailed,
+	"partially_refunded": models.PaymentStatusPartiallyRefunded,
+	"partiallyrefunded":  models.PaymentStatusPartiallyRefunded,
+	"refunded":           models.PaymentStatusRefunded,
+}
+
+// importFulfillmentStatuses maps fulfillment status spellings, including Shopify's
+var importFulfillmentStatuses = map[string]models.FulfillmentStatus{
+	"unfulfilled":      models.FulfillmentStatusUnfulfilled,
+	"processing":       models.FulfillmentStatusProcessing,
+	"partial":          models.FulfillmentStatusProcessing,
+	"packed":           models.FulfillmentStatusPacked,
+	"dispatched":       models.FulfillmentStatusDispatched,
+	"shipped":          models.FulfillmentStatusDispatched,
+	"in_transit":       models.FulfillmentStatusInTransit,
+	"out_for_delivery": models.FulfillmentStatusOutForDelivery,
+	"fulfilled":        models.FulfillmentStatusDelivered,
+	"delivered":        models.FulfillmentStatusDelivered,
+	"failed_delivery":  models.FulfillmentStatusFailedDelivery,
+	"returned":         models.FulfillmentStatusReturned,
+	"picked_up":        models.FulfillmentStatusPickedUp,
+}
+
+// importStatusKey normalizes a status cell for the status maps
+func importStatusKey(value string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), " ", "_")
+}
+
+// parseImportOrder validates a group of mapped rows and converts it into an importedOrder.
+// Order-level values are read from the first row, as in other platforms' exports.
+func parseImportOrder(rows []map[string]string) (*importedOrder, []models.OrderImportError) {
+	first := rows[0]
+	rowNum, _ := strconv.Atoi(first["_row"])
+	o := &importedOrder{
+		row:            rowNum,
+		orderNumber:    strings.TrimPrefix(first["orderNumber"], "#"),
+		email:          strings.ToLower(first["email"]),
+		firstName:      first["firstName"],
+		lastName:       first["lastName"],
+		phone:          first["phone"],
+		currency:       strings.ToUpper(first["currency"]),
+		paymentMethod:  first["paymentMethod"],
+		transactionID:  first["transactionId"],
+		notes:          first["notes"],
+		shippingMethod: first["shippingMethod"],
+		carrier:        first["carrier"],
+		trackingNumber: first["trackingNumber"],
+	}
+
+	var errs []models.OrderImportError
+	invalid := func(row int, column, code, message string) {
+		errs = append(errs, o.rowError(row, column, code, message))
+	}
+	amount := func(row int, column, value string) float64 {
+		v, err := parseImportAmount(value)
+		if err != nil || v < 0 {
+			invalid(row, column, "INVALID", fmt.Sprintf("%s must be a non-negative amount", column))
+			return 0
+		}
+		return v
+	}
+
+	if o.orderNumber == "" {
+		invalid(o.row, "orderNumber", "REQUIRED", "orderNumber is required")
+	} else if len(o.orderNumber) > 100 {
+		invalid(o.row, "orderNumber", "INVALID", "orderNumber must be at most 100 characters")
+	}
+	if value := first["orderDate"]; value == "" {
+		invalid(o.row, "orderDate", "REQUIRED", "orderDate is required")
+	} else {
+		for _, layout := range importOrderDateLayouts {
+			if placedAt, err := time.Parse(layout, value); err == nil {
+				o.placedAt = placedAt
+				break
+			}
+		}
+		if o.placedAt.IsZero() {
+			invalid(o.row, "orderDate", "INVALID", "orderDate must be a date in YYYY-MM-DD or RFC 3339 format")
+		} else if o.placedAt.After(time.Now()) {
+			invalid(o.row, "orderDate", "INVALID", "orderDate cannot be in the future")
+		}
+	}
+	if o.email != "" {
+		if addr, err := mail.ParseAddress(o.email); err != nil || addr.Address != o.email {
+			invalid(o.row, "email", "INVALID", "email must be a valid email address")
+		}
+	}
+	if o.currency != "" && len(o.currency) != 3 {
+		invalid(o.row, "currency", "INVALID", "currency must be a 3-letter ISO code")
+	}
+
+	o.status = models.OrderStatusCompleted
+	if value := first["status"]; value != "" {
+		status, ok := importOrderStatuses[importStatusKey(value)]
+		if !ok {
+			invalid(o.row, "status", "INVALID", "status must be PLACED, CONFIRMED, PROCESSING, SHIPPED, DELIVERED, COMPLETED or CANCELLED")
+		}
+		o.status = status
+	}
+	o.paymentStatus = models.PaymentStatusPaid
+	if o.status == models.OrderStatusPlaced {
+		o.paymentStatus = models.PaymentStatusPending
+	}
+	if value := first["paymentStatus"]; value != "" {
+		status, ok := importPaymentStatuses[importStatusKey(value)]
+		if !ok {
+			invalid(o.row, "paymentStatus", "INVALID", "paymentStatus must be PENDING, PAID, FAILED, PARTIALLY_REFUNDED or REFUNDED")
+		}
+		o.paymentStatus = status
+	}
+	o.fulfillmentStatus = defaultImportFulfillment(o.status)
+	if value := first["fulfillmentStatus"]; value != "" {
+		status, ok := importFulfillmentStatuses[importStatusKey(value)]
+		if !ok {
+			invalid(o.row, "fulfillmentStatus", "INVALID", fmt.Sprintf("fulfillmentStatus '%s' is not recognised", value))
+		}
+		o.fulfillmentStatus = status
+	}
+
+	if value := first["taxAmount"]; value != "" {
+		tax := amount(o.row, "taxAmount", value)
+		o.taxAmount = &tax
+	}
+	if value := first["shippingCost"]; value != "" {
+		o.shippingCost = amount(o.row, "shippingCost", value)
+	}
+	if value := first["discountAmount"]; value != "" {
+		o.discountAmount = amount(o.row, "discountAmount", value)
+	}
+	if value := first["total"]; value != "" {
+		total := amount(o.row, "total", value)
+		o.total = &total
+	}
+
+	for _, row := range rows {
+		itemRow, _ := strconv.Atoi(row["_row"])
+		item := importedOrderItem{
+			sku:         row["sku"],
+			productName: row["productName"],
+		}
+		for _, column := range []string{"sku", "productName", "quantity", "unitPrice"} {
+			if row[column] == "" {
+				invalid(itemRow, column, "REQUIRED", fmt.Sprintf("%s is required", column))
+			}
+		}
+		if value := row["quantity"]; value != "" {
+			quantity, err := strconv.Atoi(value)
+			if err != nil || quantity < 1 {
+				invalid(itemRow, "quantity", "INVALID", "quantity must be a whole number of at least 1")
+			}
+			item.quantity = quantity
+		}
+		if value := row["unitPrice"]; value != "" {
+			item.unitPrice = amount(itemRow, "unitPrice", value)
+		}
+		if value := row["itemTax"]; value != "" {
+			item.taxAmount = amount(itemRow, "itemTax", value)
+		}
+		if value := row["productId"]; value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				invalid(itemRow, "productId", "INVALID", "productId must be a product UUID")
+			}
+			item.productID = id
+		}
+		o.items = append(o.items, item)
+	}
+
+	if first["street"] != "" {
+		countryCode := strings.ToUpper(first["countryCode"])
+		country := first["country"]
+		if country == "" {
+			country = countryCode
+		}
+		switch {
+		case first["city"] == "":
+			invalid(o.row, "city", "REQUIRED", "city is required with a shipping address")
+		case country == "":
+			invalid(o.row, "country", "REQUIRED", "country or countryCode is required with a shipping address")
+		case countryCode != "" && len(countryCode) != 2:
+			invalid(o.row, "countryCode", "INVALID", "countryCode must be a 2-letter ISO code")
+		default:
+			o.address = &models.OrderShipping{
+				Street:      first["street"],
+				City:        first["city"],
+				State:       first["state"],
+				PostalCode:  first["postalCode"],
+				Country:     country,
+				CountryCode: countryCode,
+			}
+		}
+	}
+
+	return o, errs
+}
+
+// defaultImportFulfillment is the fulfillment status of an imported order whose row leaves it
+// blank, following from the order status
+func defaultImportFulfillment(status models.OrderStatus) models.FulfillmentStatus {
+	switch status {
+	case models.OrderStatusProcessing:
+		return models.FulfillmentStatusProcessing
+	case models.OrderStatusShipped:
+		return models.FulfillmentStatusDispatched
+	case models.OrderStatusDelivered, models.OrderStatusCompleted:
+		return models.FulfillmentStatusDelivered
+	}
+	return models.FulfillmentStatusUnfulfilled
+}
+
+// newOrder builds the order to import, flagged with the job so checkout, payment and
+// fulfillment triggers leave it alone
+func (o *importedOrder) newOrder(job *models.OrderImportJob, customerID uuid.UUID) *models.Order {
+	currency := o.currency
+	if currency == "" {
+		currency = job.Currency
+	}
+	paymentMethod := o.paymentMethod
+	if paymentMethod == "" {
+		paymentMethod = job.Source
+	}
+
+	order := &models.Order{
+		TenantID:          job.TenantID,
+		OrderNumber:       o.orderNumber,
+		CustomerID:        customerID,
+		Status:            o.status,
+		PaymentStatus:     o.paymentStatus,
+		FulfillmentStatus: o.fulfillmentStatus,
+		FulfillmentType:   models.FulfillmentTypeShipping,
+		Currency:          currency,
+		ShippingCost:      roundCurrency(o.shippingCost),
+		DiscountAmount:    roundCurrency(o.discountAmount),
+		Notes:             o.notes,
+		StorefrontID:      job.StorefrontID,
+		ImportJobID:       &job.ID,
+		ImportSource:      job.Source,
+		CreatedAt:         o.placedAt,
+		Customer: &models.OrderCustomer{
+			FirstName: o.firstName,
+			LastName:  o.lastName,
+			Email:     o.email,
+			Phone:     o.phone,
+		},
+	}
+
+	var itemTax float64
+	for _, item := range o.items {
+		productID := item.productID
+		if productID == uuid.Nil {
+			productID = uuid.NewSHA1(importedProductNamespace, []byte(job.TenantID+":"+strings.ToLower(item.sku)))
+		}
+		totalPrice := roundCurrency(item.unitPrice * float64(item.quantity))
+		order.Items = append(order.Items, models.OrderItem{
+			ProductID:   productID,
+			ProductName: item.productName,
+			SKU:         item.sku,
+			Quantity:    item.quantity,
+			UnitPrice:   roundCurrency(item.unitPrice),
+			TotalPrice:  totalPrice,
+			TaxAmount:   roundCurrency(item.taxAmount),
+		})
+		order.Subtotal += totalPrice
+		itemTax += item.taxAmount
+	}
+	order.Subtotal = roundCurrency(order.Subtotal)
+
+	order.TaxAmount = roundCurrency(itemTax)
+	if o.taxAmount != nil {
+		order.TaxAmount = roundCurrency(*o.taxAmount)
+	}
+	order.Total = roundCurrency(order.Subtotal + order.TaxAmount + order.ShippingCost - order.DiscountAmount)
+	if o.total != nil {
+		order.Total = roundCurrency(*o.total)
+	}
+
+	order.Payment = &models.OrderPayment{
+		Method:        paymentMethod,
+		Status:        o.paymentStatus,
+		Amount:        order.Total,
+		Currency:      currency,
+		TransactionID: o.transactionID,
+	}
+	if o.paymentStatus != models.PaymentStatusPending && o.paymentStatus != models.PaymentStatusFailed {
+		processedAt := o.placedAt
+		order.Payment.ProcessedAt = &processedAt
+	}
+
+	if o.address != nil {
+		shipping := *o.address
+		shipping.Method = o.shippingMethod
+		if shipping.Method == "" {
+			shipping.Method = "Standard"
+		}
+		shipping.Carrier = o.carrier
+		shipping.TrackingNumber = o.trackingNumber
+		shipping.Cost = order.ShippingCost
+		order.Shipping = &shipping
+	}
+
+	return order
+}
+
+func (o *importedOrder) rowError(row int, column, code, message string) models.OrderImportError {
+	return models.OrderImportError{
+		Row:         row,
+		OrderNumber: o.orderNumber,
+		Column:      column,
+		Code:        code,
+		Message:     message,
+	}
+}
@@ -0,0 +1,548 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"orders-service/internal/encryption"
+	"orders-service/internal/metrics"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+const (
+	// integrationClaimDuration is how long a claimed delivery is reserved for one worker
+	integrationClaimDuration = 5 * time.Minute
+	// integrationMaxBackoff caps the delay between retries
+	integrationMaxBackoff     = 6 * time.Hour
+	integrationResponseLimit  = 2048
+	integrationReplayLimit    = 500
+	integrationDeliveriesPage = 50
+)
+
+var (
+	// ErrOrderIntegrationNotFound is returned when an integration doesn't exist for the tenant
+	ErrOrderIntegrationNotFound = errors.New("order integration not found")
+	// ErrIntegrationDeliveryNotFound is returned when a delivery doesn't exist for the integration
+	ErrIntegrationDeliveryNotFound = errors.New("integration delivery not found")
+	// ErrIntegrationDeliveryOutstanding is returned when replaying a delivery that hasn't finished
+	ErrIntegrationDeliveryOutstanding = errors.New("delivery is still pending")
+	// ErrIntegrationOrderNotFound is returned when previewing an unknown order
+	ErrIntegrationOrderNotFound = errors.New("order not found")
+)
+
+// IntegrationValidationError reports an invalid integration setting, such as a template that
+// doesn't parse
+type IntegrationValidationError struct {
+	Message string
+}
+
+func (e *IntegrationValidationError) Error() string {
+	return e.Message
+}
+
+// IntegrationPayloadData is what an integration's template is executed against
+type IntegrationPayloadData struct {
+	Event       string        `json:"event"`
+	DeliveryID  string        `json:"deliveryId"`
+	TenantID    string        `json:"tenantId"`
+	Timestamp   time.Time     `json:"timestamp"`
+	Order       *models.Order `json:"order"`
+	Integration struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Target string `json:"target"`
+	} `json:"integration"`
+}
+
+// integrationTemplateFuncs are available to every payload template
+var integrationTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"xml": func(s string) string {
+		var buf bytes.Buffer
+		_ = xml.EscapeText(&buf, []byte(s))
+		return buf.String()
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"date": func(layout string, t time.Time) string {
+		return t.UTC().Format(layout)
+	},
+	"money": func(amount float64) string {
+		return strconv.FormatFloat(amount, 'f', 2, 64)
+	},
+	"default": func(fallback, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// OrderIntegrationService manages tenants' outbound order integrations and delivers their
+// outbox. Timeline events are queued in the transaction that records them, then rendered with
+// the integration's template and sent with retries; every delivery is kept as a log entry
+// that can be replayed.
+type OrderIntegrationService struct {
+	repo       *repository.OrderIntegrationRepository
+	httpClient *http.Client
+}
+
+// NewOrderIntegrationService creates a new order integration service
+func NewOrderIntegrationService(repo *repository.OrderIntegrationRepository) *OrderIntegrationService {
+	return &OrderIntegrationService{
+		repo:       repo,
+		httpClient: newTenantEndpointClient(),
+	}
+}
+
+// newTenantEndpointClient returns an HTTP client for calling endpoints configured by tenants.
+// Endpoints on private, loopback and link-local addresses are refused, so a tenant can't reach
+// services inside the cluster, unless ORDER_INTEGRATIONS_ALLOW_PRIVATE_NETWORKS=true (for local
+// development).
+func newTenantEndpointClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if os.Getenv("ORDER_INTEGRATIONS_ALLOW_PRIVATE_NETWORKS") != "true" {
+		dialer.Control = rejectPrivateAddress
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// rejectPrivateAddress refuses connections to addresses that aren't publicly routable. It
+// checks the resolved address, so DNS names pointing inside the network are refused too.
+func rejectPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("integration endpoint address %s is not allowed", host)
+	}
+	return nil
+}
+
+// List returns the tenant's order integrations
+func (s *OrderIntegrationService) List(ctx context.Context, tenantID string) ([]models.OrderIntegration, error) {
+	return s.repo.List(ctx, tenantID)
+}
+
+// Get returns one of the tenant's order integrations
+func (s *OrderIntegrationService) Get(ctx context.Context, tenantID string, id uuid.UUID) (*models.OrderIntegration, error) {
+	integration, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if integration == nil {
+		return nil, ErrOrderIntegrationNotFound
+	}
+	return integration, nil
+}
+
+// Create adds an order integration
+func (s *OrderIntegrationService) Create(ctx context.Context, tenantID, userID string, req *models.OrderIntegrationRequest) (*models.OrderIntegration, error) {
+	integration := &models.OrderIntegration{
+		TenantID:  tenantID,
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+	if err := applyIntegrationRequest(integration, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, integration); err != nil {
+		return nil, err
+	}
+	integration.AfterFind(nil)
+	return integration, nil
+}
+
+// Update replaces an order integration's settings
+func (s *OrderIntegrationService) Update(ctx context.Context, tenantID string, id uuid.UUID, userID string, req *models.OrderIntegrationRequest) (*models.OrderIntegration, error) {
+	integration, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	integration.UpdatedBy = userID
+	if err := applyIntegrationRequest(integration, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, integration); err != nil {
+		return nil, err
+	}
+	integration.AfterFind(nil)
+	return integration, nil
+}
+
+// Delete removes an order integration
+func (s *OrderIntegrationService) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, tenantID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOrderIntegrationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Preview renders an integration's template for an order without sending it
+func (s *OrderIntegrationService) Preview(ctx context.Context, tenantID string, id uuid.UUID, req *models.IntegrationPreviewRequest) (*models.IntegrationPreview, error) {
+	integration, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.repo.GetOrderForDelivery(ctx, tenantID, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrIntegrationOrderNotFound
+	}
+
+	event := req.Event
+	if event == "" {
+		event = "ORDER_UPDATED"
+	}
+	body, err := renderIntegrationPayload(integration, order, event, "preview", time.Now())
+	if err != nil {
+		return nil, &IntegrationValidationError{Message: err.Error()}
+	}
+	return &models.IntegrationPreview{ContentType: integration.ContentType, Body: body}, nil
+}
+
+// ListDeliveries returns a page of an integration's delivery log
+func (s *OrderIntegrationService) ListDeliveries(ctx context.Context, tenantID string, id uuid.UUID, filter models.IntegrationDeliveryFilter) ([]models.OrderIntegrationDelivery, int64, error) {
+	if _, err := s.Get(ctx, tenantID, id); err != nil {
+		return nil, 0, err
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = integrationDeliveriesPage
+	}
+	return s.repo.ListDeliveries(ctx, tenantID, id, filter)
+}
+
+// ReplayDelivery queues a delivered or failed delivery to be sent again, rendered from the
+// order as it is now
+func (s *OrderIntegrationService) ReplayDelivery(ctx context.Context, tenantID string, id, deliveryID uuid.UUID) (*models.OrderIntegrationDelivery, error) {
+	if _, err := s.Get(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.repo.GetDelivery(ctx, tenantID, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, ErrIntegrationDeliveryNotFound
+	}
+	if delivery.Status == models.IntegrationDeliveryPending || delivery.Status == models.IntegrationDeliveryDelivering {
+		return nil, ErrIntegrationDeliveryOutstanding
+	}
+
+	replays, err := s.repo.CreateReplays(ctx, []models.OrderIntegrationDelivery{*delivery}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &replays[0], nil
+}
+
+// ReplayFailed queues every failed delivery created since the given time to be sent again,
+// e.g. after the ERP was down. It returns how many were queued.
+func (s *OrderIntegrationService) ReplayFailed(ctx context.Context, tenantID string, id uuid.UUID, since time.Time) (int, error) {
+	if _, err := s.Get(ctx, tenantID, id); err != nil {
+		return 0, err
+	}
+
+	failed, err := s.repo.GetFailedDeliveries(ctx, tenantID, id, since, integrationReplayLimit)
+	if err != nil {
+		return 0, err
+	}
+	replays, err := s.repo.CreateReplays(ctx, failed, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return len(replays), nil
+}
+
+// DeliverDue attempts up to limit due outbox deliveries and returns how many were delivered
+// and how many were attempted
+func (s *OrderIntegrationService) DeliverDue(ctx context.Context, now time.Time, limit int) (int, int, error) {
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, now, now.Add(integrationClaimDuration), limit)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	integrations := make(map[uuid.UUID]*models.OrderIntegration)
+	delivered := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		integration, ok := integrations[delivery.IntegrationID]
+		if !ok {
+			integration, err = s.repo.GetIntegrationForDelivery(ctx, delivery.IntegrationID)
+			if err != nil {
+				log.Printf("[OrderIntegration] Failed to load integration %s: %v", delivery.IntegrationID, err)
+				continue // The claim expires and the delivery is retried
+			}
+			integrations[delivery.IntegrationID] = integration
+		}
+
+		attempts := delivery.Attempts
+		if s.deliver(ctx, integration, delivery, time.Now()) {
+			delivered++
+		}
+		// A paused integration's delivery is put back without an attempt
+		if delivery.Attempts > attempts || delivery.Status == models.IntegrationDeliveryFailed {
+			metrics.RecordDelivery(metrics.DeliveryIntegration, delivery.Event, string(delivery.Status))
+		}
+		if err := s.repo.SaveDeliveryAttempt(ctx, delivery); err != nil {
+			log.Printf("[OrderIntegration] Failed to save delivery %s: %v", delivery.ID, err)
+		}
+	}
+	return delivered, len(deliveries), nil
+}
+
+// deliver makes one attempt at a delivery and updates it with the outcome. It reports
+// whether the delivery succeeded.
+func (s *OrderIntegrationService) deliver(ctx context.Context, integration *models.OrderIntegration, delivery *models.OrderIntegrationDelivery, now time.Time) bool {
+	switch {
+	case integration.DeletedAt.Valid:
+		failIntegrationDelivery(delivery, "integration was deleted")
+		return false
+	case !integration.IsActive:
+		// Paused: keep the delivery queued without using up an attempt
+		delivery.Status = models.IntegrationDeliveryPending
+		delivery.NextAttemptAt = now.Add(15 * time.Minute)
+		return false
+	}
+
+	order, err := s.repo.GetOrderForDelivery(ctx, delivery.TenantID, delivery.OrderID)
+	if err != nil {
+		s.retryIntegrationDelivery(integration, delivery, now, err.Error())
+		return false
+	}
+	if order == nil {
+		failIntegrationDelivery(delivery, "order not found")
+		return false
+	}
+
+	// A template error won't fix itself; fail now so the template can be corrected and the
+	// delivery replayed
+	body, err := renderIntegrationPayload(integration, order, delivery.Event, delivery.ID.String(), now)
+	if err != nil {
+		failIntegrationDelivery(delivery, err.Error())
+		return false
+	}
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.RequestBody = body
+
+	status, respBody, err := s.send(ctx, integration, delivery, body, now)
+	delivery.ResponseStatus = status
+	delivery.ResponseBody = respBody
+	if err != nil {
+		s.retryIntegrationDelivery(integration, delivery, now, err.Error())
+		return false
+	}
+
+	delivery.Status = models.IntegrationDeliveryDelivered
+	delivery.DeliveredAt = &now
+	delivery.Error = ""
+	return true
+}
+
+// send posts a rendered payload to the integration's endpoint. Signed integrations get
+// X-Signature: sha256=HMAC(secret, timestamp + "." + body) alongside X-Timestamp.
+func (s *OrderIntegrationService) send(ctx context.Context, integration *models.OrderIntegration, delivery *models.OrderIntegrationDelivery, body string, now time.Time) (int, string, error) {
+	timeout := time.Duration(integration.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, integration.HTTPMethod, integration.EndpointURL, strings.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", integration.ContentType)
+	req.Header.Set("User-Agent", "Tesseract-Order-Integrations/1.0")
+	req.Header.Set("X-Event", delivery.Event)
+	req.Header.Set("X-Delivery-ID", delivery.ID.String()) // Stable across retries, for receiver de-duplication
+	req.Header.Set("X-Timestamp", timestamp)
+	if integration.AuthHeaderName != "" && integration.AuthHeaderValue != "" {
+		req.Header.Set(integration.AuthHeaderName, integration.AuthHeaderValue.String())
+	}
+	if integration.SigningSecret != "" {
+		mac := hmac.New(sha256.New, []byte(integration.SigningSecret.String()))
+		mac.Write([]byte(timestamp + "." + body))
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, integrationResponseLimit))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(respBody), fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(respBody), nil
+}
+
+// retryIntegrationDelivery schedules the next attempt with exponential backoff (1, 2, 4 ...
+// minutes, capped at 6 hours), or fails the delivery once it is out of attempts
+func (s *OrderIntegrationService) retryIntegrationDelivery(integration *models.OrderIntegration, delivery *models.OrderIntegrationDelivery, now time.Time, reason string) {
+	delivery.Error = reason
+	if delivery.Attempts >= integration.MaxAttempts {
+		delivery.Status = models.IntegrationDeliveryFailed
+		return
+	}
+
+	backoff := time.Minute << uint(min(delivery.Attempts, 12))
+	if backoff > integrationMaxBackoff {
+		backoff = integrationMaxBackoff
+	}
+	delivery.Status = models.IntegrationDeliveryPending
+	delivery.NextAttemptAt = now.Add(backoff)
+}
+
+func failIntegrationDelivery(delivery *models.OrderIntegrationDelivery, reason string) {
+	delivery.Status = models.IntegrationDeliveryFailed
+	delivery.Error = reason
+}
+
+// renderIntegrationPayload executes an integration's template for an order event
+func renderIntegrationPayload(integration *models.OrderIntegration, order *models.Order, event, deliveryID string, now time.Time) (string, error) {
+	tmpl, err := parseIntegrationTemplate(integration.Template)
+	if err != nil {
+		return "", err
+	}
+
+	data := IntegrationPayloadData{
+		Event:      event,
+		DeliveryID: deliveryID,
+		TenantID:   integration.TenantID,
+		Timestamp:  now.UTC(),
+		Order:      order,
+	}
+	data.Integration.ID = integration.ID.String()
+	data.Integration.Name = integration.Name
+	data.Integration.Target = string(integration.Target)
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("template failed: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func parseIntegrationTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("payload").Funcs(integrationTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// applyIntegrationRequest validates a request and copies it onto an integration
+func applyIntegrationRequest(integration *models.OrderIntegration, req *models.OrderIntegrationRequest) error {
+	endpoint, err := url.Parse(req.EndpointURL)
+	if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+		return &IntegrationValidationError{Message: "endpointUrl must be an http or https URL"}
+	}
+	if _, err := parseIntegrationTemplate(req.Template); err != nil {
+		return &IntegrationValidationError{Message: err.Error()}
+	}
+	if req.AuthHeaderName == "" && req.AuthHeaderValue != "" ||
+		req.AuthHeaderName != "" && req.AuthHeaderValue == "" && integration.AuthHeaderValue == "" {
+		return &IntegrationValidationError{Message: "authHeaderName and authHeaderValue must be set together"}
+	}
+
+	integration.Name = req.Name
+	integration.Target = req.Target
+	if integration.Target == "" {
+		integration.Target = models.IntegrationTargetCustom
+	}
+	integration.EndpointURL = req.EndpointURL
+	integration.HTTPMethod = req.HTTPMethod
+	if integration.HTTPMethod == "" {
+		integration.HTTPMethod = http.MethodPost
+	}
+	integration.ContentType = req.ContentType
+	if integration.ContentType == "" {
+		integration.ContentType = "application/json"
+	}
+	integration.Events = pq.StringArray(req.Events)
+	integration.TemplateEngine = req.TemplateEngine
+	if integration.TemplateEngine == "" {
+		integration.TemplateEngine = models.IntegrationTemplateGo
+	}
+	integration.Template = req.Template
+
+	integration.AuthHeaderName = req.AuthHeaderName
+	if req.AuthHeaderName == "" {
+		integration.AuthHeaderValue = ""
+	} else if req.AuthHeaderValue != "" {
+		integration.AuthHeaderValue = encryption.EncryptedString(req.AuthHeaderValue)
+	}
+	if req.SigningSecret != "" {
+		integration.SigningSecret = encryption.EncryptedString(req.SigningSecret)
+	}
+
+	integration.MaxAttempts = 10
+	if req.MaxAttempts != nil {
+		integration.MaxAttempts = *req.MaxAttempts
+	}
+	integration.TimeoutSeconds = 15
+	if req.TimeoutSeconds != nil {
+		integration.TimeoutSeconds = *req.TimeoutSeconds
+	}
+	integration.IsActive = true
+	if req.IsActive != nil {
+		integration.IsActive = *req.IsActive
+	}
+	return nil
+}
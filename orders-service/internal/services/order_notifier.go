@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"orders-service/internal/clients"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+// ErrInvalidNotificationRouting is returned for routing that names unknown events or channels
+var ErrInvalidNotificationRouting = errors.New("invalid notification routing")
+
+// digestClaimDuration is how long the digest job holds queued notifications it is sending
+const digestClaimDuration = 2 * time.Minute
+
+// notificationEventStatus is the OrderStatus the customer templates render for each event
+var notificationEventStatus = map[string]string{
+	models.NotificationEventOrderConfirmed:      "CONFIRMED",
+	models.NotificationEventOrderShipped:        "SHIPPED",
+	models.NotificationEventOrderDelivered:      "DELIVERED",
+	models.NotificationEventOrderReadyForPickup: "READY_FOR_PICKUP",
+	models.NotificationEventOrderCancelled:      "CANCELLED",
+	models.NotificationEventOrderRefunded:       "REFUNDED",
+	models.NotificationEventOrderUpdated:        "UPDATED",
+}
+
+// OrderNotifier sends customer order notifications on the channels the tenant routes each
+// event to, holding them for a digest when the tenant has digests on, and logs every send
+// against its order. It wraps the notification client, so order code keeps calling
+// SendOrderShipped and the like.
+type OrderNotifier struct {
+	client clients.NotificationClient
+	repo   *repository.NotificationRoutingRepository
+}
+
+// NewOrderNotifier creates a new order notifier
+func NewOrderNotifier(client clients.NotificationClient, repo *repository.NotificationRoutingRepository) *OrderNotifier {
+	return &OrderNotifier{client: client, repo: repo}
+}
+
+// SendOrderConfirmation routes the order confirmation
+func (n *OrderNotifier) SendOrderConfirmation(ctx context.Context, order *clients.OrderNotification) error {
+	return n.notify(ctx, models.NotificationEventOrderConfirmed, order)
+}
+
+// SendOrderShipped routes the shipping notification
+func (n *OrderNotifier) SendOrderShipped(ctx context.Context, order *clients.OrderNotification) error {
+	return n.notify(ctx, models.NotificationEventOrderShipped, order)
+}
+
+// SendOrderDelivered routes the delivery confirmation
+func (n *OrderNotifier) SendOrderDelivered(ctx context.Context, order *clients.OrderNotification) error {
+	return n.notify(ctx, models.NotificationEventOrderDelivered, order)
+}
+
+// SendOrderReadyForPickup routes the ready-for-pickup notification
+func (n *OrderNotifier) SendOrderReadyForPickup(ctx context.Context, order *clients.OrderNotification) error {
+	return n.notify(ctx, models.NotificationEventOrderReadyForPickup, order)
+}
+
+// SendOrderCancelled routes the cancellation notification
+func (n *OrderNotifier) SendOrderCancelled(ctx context.Context, order *clients.OrderNotification) error {
+	return n.notify(ctx, models.NotificationEventOrderCancelled, order)
+}
+
+// SendOrderRefunded routes the refund confirmation
+func (n *OrderNotifier) SendOrderRefunded(ctx context.Context, order *clients.OrderNotification) error {
+	return n.notify(ctx, models.NotificationEventOrderRefunded, order)
+}
+
+// SendOrderUpdated routes the order edit notification
+func (n *OrderNotifier) SendOrderUpdated(ctx context.Context, order *clients.OrderNotification) error {
+	return n.notify(ctx, models.NotificationEventOrderUpdated, order)
+}
+
+// SendOrderMessage sends a text message directly, without routing or logging
+func (n *OrderNotifier) SendOrderMessage(ctx context.Context, channel string, order *clients.OrderNotification) error {
+	return n.client.SendOrderMessage(ctx, channel, order)
+}
+
+// SendOrderDigest sends a digest directly, without routing or logging
+func (n *OrderNotifier) SendOrderDigest(ctx context.Context, digest *clients.OrderDigestNotification) error {
+	return n.client.SendOrderDigest(ctx, digest)
+}
+
+// SendScheduledReport isn't an order notification and goes straight to the client
+func (n *OrderNotifier) SendScheduledReport(ctx context.Context, report *clients.ScheduledReportNotification) error {
+	return n.client.SendScheduledReport(ctx, report)
+}
+
+// notify sends or queues an event on each channel it is routed to. It returns the first send
+// error, after trying every channel.
+func (n *OrderNotifier) notify(ctx context.Context, event string, order *clients.OrderNotification) error {
+	if order == nil {
+		return nil
+	}
+	settings := n.routing(ctx, order.TenantID)
+	orderID, _ := uuid.Parse(order.OrderID)
+
+	var firstErr error
+	for _, channel := range settings.ChannelsFor(event) {
+		entry := &models.OrderNotificationLog{
+			TenantID:    order.TenantID,
+			OrderID:     orderID,
+			OrderNumber: order.OrderNumber,
+			Event:       event,
+			Channel:     channel,
+			Recipient:   notificationRecipient(channel, order.CustomerEmail, order.CustomerPhone),
+		}
+
+		switch {
+		case entry.Recipient == "":
+			entry.Status = models.NotificationLogSkipped
+			entry.Error = fmt.Sprintf("customer has no %s", recipientKind(channel))
+		case settings.DigestWindow() > 0 && n.queue(ctx, entry, event, order, settings.DigestWindow()):
+		default:
+			if err := n.sendOne(ctx, event, channel, order); err != nil {
+				entry.Status = models.NotificationLogFailed
+				entry.Error = err.Error()
+				if firstErr == nil {
+					firstErr = err
+				}
+			} else {
+				now := time.Now()
+				entry.Status = models.NotificationLogSent
+				entry.SentAt = &now
+			}
+		}
+
+		if err := n.repo.CreateLog(ctx, entry); err != nil {
+			log.Printf("[OrderNotifier] Failed to log %s %s for order %s: %v", channel, event, order.OrderNumber, err)
+		}
+	}
+	return firstErr
+}
+
+// queue holds a notification for the customer's digest on its channel, joining a digest that
+// is already building. It reports false if the notification should be sent straight away.
+func (n *OrderNotifier) queue(ctx context.Context, entry *models.OrderNotificationLog, event string, order *clients.OrderNotification, window time.Duration) bool {
+	sendAfter, err := n.repo.QueuedDigestSendAfter(ctx, entry.TenantID, entry.Channel, entry.Recipient)
+	if err != nil {
+		log.Printf("[OrderNotifier] Sending %s for order %s without a digest: %v", event, order.OrderNumber, err)
+		return false
+	}
+	if sendAfter == nil {
+		due := time.Now().Add(window)
+		sendAfter = &due
+	}
+
+	queued := *order
+	queued.OrderStatus = notificationEventStatus[event]
+	payload, err := json.Marshal(&queued)
+	if err != nil {
+		log.Printf("[OrderNotifier] Sending %s for order %s without a digest: %v", event, order.OrderNumber, err)
+		return false
+	}
+
+	entry.Status = models.NotificationLogQueued
+	entry.SendAfter = sendAfter
+	entry.Payload = models.JSONB(payload)
+	return true
+}
+
+// sendOne sends a single event on one channel: email through its own template, text channels
+// through the short template
+func (n *OrderNotifier) sendOne(ctx context.Context, event string, channel models.NotificationChannel, order *clients.OrderNotification) error {
+	if channel == models.NotificationChannelEmail {
+		switch event {
+		case models.NotificationEventOrderConfirmed:
+			return n.client.SendOrderConfirmation(ctx, order)
+		case models.NotificationEventOrderShipped:
+			return n.client.SendOrderShipped(ctx, order)
+		case models.NotificationEventOrderDelivered:
+			return n.client.SendOrderDelivered(ctx, order)
+		case models.NotificationEventOrderReadyForPickup:
+			return n.client.SendOrderReadyForPickup(ctx, order)
+		case models.NotificationEventOrderCancelled:
+			return n.client.SendOrderCancelled(ctx, order)
+		case models.NotificationEventOrderRefunded:
+			return n.client.SendOrderRefunded(ctx, order)
+		case models.NotificationEventOrderUpdated:
+			return n.client.SendOrderUpdated(ctx, order)
+		}
+		return fmt.Errorf("unknown notification event %q", event)
+	}
+
+	order.OrderStatus = notificationEventStatus[event]
+	return n.client.SendOrderMessage(ctx, string(channel), order)
+}
+
+// FlushDue sends the digests that are due. A customer with one queued notification gets it as
+// usual; several are combined into one digest. It returns how many notifications were sent and
+// how many were claimed.
+func (n *OrderNotifier) FlushDue(ctx context.Context, now time.Time, limit int) (int, int, error) {
+	entries, err := n.repo.ClaimDueLogs(ctx, now, now.Add(digestClaimDuration), limit)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Group by customer and channel, keeping claim order so each digest reads oldest first
+	type digestKey struct {
+		tenantID  string
+		channel   models.NotificationChannel
+		recipient string
+	}
+	var keys []digestKey
+	groups := make(map[digestKey][]*models.OrderNotificationLog)
+	for i := range entries {
+		entry := &entries[i]
+		key := digestKey{entry.TenantID, entry.Channel, entry.Recipient}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], entry)
+	}
+
+	sent := 0
+	for _, key := range keys {
+		group := groups[key]
+		if err := n.sendDigest(ctx, key.tenantID, key.channel, group); err != nil {
+			log.Printf("[OrderNotifier] Failed to send %s digest to %s: %v", key.channel, key.recipient, err)
+		}
+
+		for _, entry := range group {
+			if entry.Status == models.NotificationLogSent {
+				sent++
+			}
+			if err := n.repo.SaveLogResult(ctx, entry); err != nil {
+				log.Printf("[OrderNotifier] Failed to save notification log %s: %v", entry.ID, err)
+			}
+		}
+	}
+	return sent, len(entries), nil
+}
+
+// sendDigest sends one customer's queued notifications and marks each entry with the outcome
+func (n *OrderNotifier) sendDigest(ctx context.Context, tenantID string, channel models.NotificationChannel, group []*models.OrderNotificationLog) error {
+	var entries []*models.OrderNotificationLog
+	var updates []*clients.OrderNotification
+	for _, entry := range group {
+		var update clients.OrderNotification
+		if err := json.Unmarshal(entry.Payload, &update); err != nil {
+			entry.Status = models.NotificationLogFailed
+			entry.Error = fmt.Sprintf("invalid queued notification: %v", err)
+			continue
+		}
+		entries = append(entries, entry)
+		updates = append(updates, &update)
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	var err error
+	var digestID *uuid.UUID
+	if len(updates) == 1 {
+		err = n.sendOne(ctx, entries[0].Event, channel, updates[0])
+	} else {
+		id := uuid.New()
+		digestID = &id
+		latest := updates[len(updates)-1]
+		err = n.client.SendOrderDigest(ctx, &clients.OrderDigestNotification{
+			TenantID:      tenantID,
+			Channel:       string(channel),
+			CustomerEmail: latest.CustomerEmail,
+			CustomerPhone: latest.CustomerPhone,
+			CustomerName:  latest.CustomerName,
+			Updates:       updates,
+		})
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		entry.DigestID = digestID
+		if err != nil {
+			entry.Status = models.NotificationLogFailed
+			entry.Error = err.Error()
+			continue
+		}
+		entry.Status = models.NotificationLogSent
+		entry.SentAt = &now
+		entry.Payload = nil // The content isn't needed once sent
+	}
+	return err
+}
+
+// routing returns the tenant's notification routing, or the defaults
+func (n *OrderNotifier) routing(ctx context.Context, tenantID string) *models.NotificationRoutingSettings {
+	settings, err := n.repo.GetSettings(ctx, tenantID)
+	if err != nil {
+		log.Printf("[OrderNotifier] Using default routing for tenant %s: %v", tenantID, err)
+	}
+	if settings == nil {
+		return defaultNotificationRouting(tenantID)
+	}
+	return settings
+}
+
+// GetRouting returns the tenant's notification routing with the channels every event goes to
+func (n *OrderNotifier) GetRouting(ctx context.Context, tenantID string) (*models.NotificationRoutingResponse, error) {
+	settings, err := n.repo.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	configured := settings != nil
+	if !configured {
+		settings = defaultNotificationRouting(tenantID)
+	}
+	return notificationRoutingResponse(settings, configured), nil
+}
+
+// UpdateRouting validates and saves the tenant's notification routing. Notifications already
+// queued keep the send time they were given.
+func (n *OrderNotifier) UpdateRouting(ctx context.Context, tenantID string, req *models.UpdateNotificationRoutingRequest, userID string) (*models.NotificationRoutingResponse, error) {
+	settings := defaultNotificationRouting(tenantID)
+	settings.Routes = models.NotificationRoutes(req.Routes)
+	settings.DigestEnabled = req.DigestEnabled
+	if req.DigestWindowMinutes != 0 {
+		settings.DigestWindowMinutes = req.DigestWindowMinutes
+	}
+	settings.CreatedBy = userID
+	settings.UpdatedBy = userID
+
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNotificationRouting, err)
+	}
+	if err := n.repo.UpsertSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return notificationRoutingResponse(settings, true), nil
+}
+
+// ListOrderNotifications returns the notifications sent or queued for an order, newest first
+func (n *OrderNotifier) ListOrderNotifications(ctx context.Context, tenantID string, orderID uuid.UUID) ([]models.OrderNotificationLog, error) {
+	return n.repo.ListByOrder(ctx, tenantID, orderID)
+}
+
+func defaultNotificationRouting(tenantID string) *models.NotificationRoutingSettings {
+	return &models.NotificationRoutingSettings{
+		TenantID:            tenantID,
+		Routes:              models.NotificationRoutes{},
+		DigestWindowMinutes: 10,
+	}
+}
+
+func notificationRoutingResponse(settings *models.NotificationRoutingSettings, configured bool) *models.NotificationRoutingResponse {
+	effective := make(models.NotificationRoutes, len(models.NotificationEvents))
+	for _, event := range models.NotificationEvents {
+		effective[event] = settings.ChannelsFor(event)
+	}
+	return &models.NotificationRoutingResponse{
+		Settings:   settings,
+		Configured: configured,
+		Effective:  effective,
+	}
+}
+
+// notificationRecipient is the address a channel delivers to
+func notificationRecipient(channel models.NotificationChannel, email, phone string) string {
+	if channel == models.NotificationChannelEmail {
+		return email
+	}
+	return phone
+}
+
+func recipientKind(channel models.NotificationChannel) string {
+	if channel == models.NotificationChannelEmail {
+		return "email address"
+	}
+	return "phone number"
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+// ErrInvalidOrderNumberFormat is returned for an order number format that could repeat numbers
+var ErrInvalidOrderNumberFormat = errors.New("invalid order number format")
+
+// OrderNumberSettingsService manages tenants' order number formats
+type OrderNumberSettingsService struct {
+	repo *repository.OrderNumberSettingsRepository
+}
+
+// NewOrderNumberSettingsService creates a new order number settings service
+func NewOrderNumberSettingsService(repo *repository.OrderNumberSettingsRepository) *OrderNumberSettingsService {
+	return &OrderNumberSettingsService{repo: repo}
+}
+
+// GetSettings returns the tenant's order number format and the next numbers it will issue.
+// Tenants that haven't configured one get the defaults a new format starts from.
+func (s *OrderNumberSettingsService) GetSettings(ctx context.Context, tenantID string) (*models.OrderNumberSettingsResponse, error) {
+	settings, err := s.repo.GetByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return &models.OrderNumberSettingsResponse{
+			Settings:   defaultOrderNumberSettings(tenantID),
+			Configured: false,
+			Next:       []models.OrderNumberPreview{},
+		}, nil
+	}
+
+	next, err := s.previewNext(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	return &models.OrderNumberSettingsResponse{
+		Settings:   settings,
+		Configured: true,
+		Next:       next,
+	}, nil
+}
+
+// UpdateSettings validates and saves the tenant's order number format. Sequences carry on from
+// the highest matching number existing orders already hold, so imported or backfilled orders
+// are never renumbered or reused.
+func (s *OrderNumberSettingsService) UpdateSettings(ctx context.Context, tenantID string, req *models.UpdateOrderNumberSettingsRequest, userID string) (*models.OrderNumberSettingsResponse, error) {
+	settings := defaultOrderNumberSettings(tenantID)
+	settings.Prefix = req.Prefix
+	settings.StorefrontPrefixes = models.StringMap(req.StorefrontPrefixes)
+	settings.DateFormat = req.DateFormat
+	if req.Separator != nil {
+		settings.Separator = *req.Separator
+	}
+	if req.SequencePadding != 0 {
+		settings.SequencePadding = req.SequencePadding
+	}
+	if req.StartSequence != 0 {
+		settings.StartSequence = req.StartSequence
+	}
+	if req.ResetPeriod != "" {
+		settings.ResetPeriod = req.ResetPeriod
+	}
+	settings.GapFree = req.GapFree
+	settings.CreatedBy = userID
+	settings.UpdatedBy = userID
+
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOrderNumberFormat, err)
+	}
+	if settings.Prefix == "" && settings.DateFormat == models.OrderNumberDateNone {
+		return nil, fmt.Errorf("%w: a prefix or date component is required", ErrInvalidOrderNumberFormat)
+	}
+
+	if err := s.repo.Upsert(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	next, err := s.previewNext(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	return &models.OrderNumberSettingsResponse{
+		Settings:   settings,
+		Configured: true,
+		Next:       next,
+	}, nil
+}
+
+// previewNext lists the next number for the default prefix and each storefront prefix
+func (s *OrderNumberSettingsService) previewNext(ctx context.Context, settings *models.OrderNumberSettings) ([]models.OrderNumberPreview, error) {
+	now := time.Now()
+	storefrontIDs := make([]string, 0, len(settings.StorefrontPrefixes))
+	for storefrontID := range settings.StorefrontPrefixes {
+		storefrontIDs = append(storefrontIDs, storefrontID)
+	}
+	sort.Strings(storefrontIDs)
+
+	previews := make([]models.OrderNumberPreview, 0, len(storefrontIDs)+1)
+	for _, storefrontID := range append([]string{""}, storefrontIDs...) {
+		prefix := settings.PrefixFor(storefrontID)
+		next, existingMax, err := s.repo.PeekNext(ctx, settings, prefix, now)
+		if err != nil {
+			return nil, err
+		}
+		previews = append(previews, models.OrderNumberPreview{
+			StorefrontID: storefrontID,
+			Prefix:       prefix,
+			NextNumber:   next,
+			ExistingMax:  existingMax,
+		})
+	}
+	return previews, nil
+}
+
+func defaultOrderNumberSettings(tenantID string) *models.OrderNumberSettings {
+	return &models.OrderNumberSettings{
+		TenantID:           tenantID,
+		Prefix:             "ORD-",
+		StorefrontPrefixes: models.StringMap{},
+		DateFormat:         models.OrderNumberDateNone,
+		Separator:          "-",
+		SequencePadding:    6,
+		StartSequence:      1,
+		ResetPeriod:        models.OrderNumberResetNever,
+	}
+}
@@ -3,8 +3,10 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -13,6 +15,7 @@ import (
 	"github.com/Tesseract-Nexus/go-shared/security"
 	"orders-service/internal/clients"
 	"orders-service/internal/events"
+	"orders-service/internal/metrics"
 	"orders-service/internal/models"
 	"orders-service/internal/repository"
 )
@@ -33,22 +36,80 @@ type OrderService interface {
 	AddShippingTracking(id uuid.UUID, carrier string, trackingNumber string, trackingUrl string, tenantID string) (*models.Order, error)
 	GetValidStatusTransitions(id uuid.UUID, tenantID string) (*ValidTransitionsResponse, error)
 	SplitOrder(id uuid.UUID, req models.SplitOrderRequest, userID *uuid.UUID, tenantID string) (*SplitOrderResponse, error)
+	SplitOrderByVendor(id uuid.UUID, userID *uuid.UUID, tenantID string) (*VendorSplitResponse, error)
 	GetChildOrders(parentOrderID uuid.UUID, tenantID string) ([]models.Order, error)
 	BatchGetOrders(ids []uuid.UUID, tenantID string) ([]*models.Order, error)
+	// Customer self-service and order automation
+	ApplySelfCancelStatus(tenantID string, orders ...*models.Order)
+	CustomerCancelOrder(id uuid.UUID, reason string, tenantID string) (*models.Order, error)
+	ExpireUnpaidOrder(id uuid.UUID, reason string, tenantID string) (*models.Order, error)
+	// Staff edits of placed orders
+	EditOrder(id uuid.UUID, req EditOrderRequest, editedBy *uuid.UUID, tenantID string) (*EditOrderResponse, error)
+	ListOrderRevisions(id uuid.UUID, tenantID string) ([]models.OrderRevision, error)
+	// Partial fulfillments
+	CreateFulfillment(id uuid.UUID, req CreateFulfillmentRequest, createdBy *uuid.UUID, tenantID string) (*models.OrderFulfillment, error)
+	ListFulfillments(id uuid.UUID, tenantID string) ([]models.OrderFulfillment, error)
+	UpdateFulfillment(id, fulfillmentID uuid.UUID, req UpdateOrderFulfillmentRequest, tenantID string) (*models.OrderFulfillment, error)
 }
 
+// ErrSelfCancelNotAllowed is returned when a customer tries to cancel an order outside the self-cancel window
+// or after fulfillment has started
+var ErrSelfCancelNotAllowed = errors.New("order can no longer be cancelled by the customer")
+
 // DTOs and Request/Response types
 type CreateOrderRequest struct {
 	CustomerID uuid.UUID                    `json:"customerId" binding:"required"`
 	Currency   string                       `json:"currency"`
 	Items      []CreateOrderItemRequest     `json:"items" binding:"required,min=1"`
 	Customer   CreateOrderCustomerRequest   `json:"customer" binding:"required"`
-	Shipping   CreateOrderShippingRequest   `json:"shipping" binding:"required"`
+	Shipping   CreateOrderShippingRequest   `json:"shipping"` // Required unless the order is collected from a pickup location
 	Payment    CreateOrderPaymentRequest    `json:"payment" binding:"required"`
+	// FulfillmentType defaults to SHIPPING; PICKUP orders are collected from Pickup.LocationID
+	FulfillmentType models.FulfillmentType    `json:"fulfillmentType,omitempty" binding:"omitempty,oneof=SHIPPING PICKUP"`
+	Pickup          *CreateOrderPickupRequest `json:"pickup,omitempty"`
 	Discounts  []CreateOrderDiscountRequest `json:"discounts"`
 	Notes          string                       `json:"notes"`
 	StorefrontHost string                       `json:"storefrontHost,omitempty"` // Set from X-Storefront-Host header
+	StorefrontID   string                       `json:"-"`                        // Set from X-Storefront-ID header
 	IdempotencyKey string                       `json:"-"`                        // Set from X-Idempotency-Key header
+	// Attribution is the UTM first/last touch the storefront captured, passed on to order events
+	Attribution *models.OrderAttribution `json:"attribution,omitempty"`
+	// PriceLockToken from cart validation; the locked unit prices are charged
+	PriceLockToken string `json:"priceLockToken,omitempty"`
+}
+
+// CreateOrderPickupRequest selects the location a click-and-collect order is collected from
+type CreateOrderPickupRequest struct {
+	LocationID uuid.UUID `json:"locationId" binding:"required"` // inventory-service warehouse with pickup enabled
+}
+
+// Validate checks the fields that depend on the fulfillment type
+func (r *CreateOrderRequest) Validate() error {
+	if r.FulfillmentType == models.FulfillmentTypePickup {
+		if r.Pickup == nil {
+			return fmt.Errorf("pickup.locationId is required for pickup orders")
+		}
+		return nil
+	}
+
+	missing := []string{}
+	for field, value := range map[string]string{
+		"method":     r.Shipping.Method,
+		"street":     r.Shipping.Street,
+		"city":       r.Shipping.City,
+		"state":      r.Shipping.State,
+		"postalCode": r.Shipping.PostalCode,
+		"country":    r.Shipping.Country,
+	} {
+		if strings.TrimSpace(value) == "" {
+			missing = append(missing, "shipping."+field)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+	}
+	return nil
 }
 
 type CreateOrderItemRequest struct {
@@ -58,6 +119,10 @@ type CreateOrderItemRequest struct {
 	Image       string    `json:"image,omitempty"` // Product image URL
 	Quantity    int       `json:"quantity" binding:"required,min=1"`
 	UnitPrice   float64   `json:"unitPrice" binding:"required,min=0"`
+	VendorID    string    `json:"vendorId,omitempty"` // Marketplace vendor that fulfills the item; empty for the tenant's own stock
+
+	// Line-item properties from the cart, e.g. engraving text or a gift message
+	Properties map[string]string `json:"properties,omitempty"`
 }
 
 type CreateOrderCustomerRequest struct {
@@ -68,15 +133,15 @@ type CreateOrderCustomerRequest struct {
 }
 
 type CreateOrderShippingRequest struct {
-	Method             string  `json:"method" binding:"required"`
+	Method             string  `json:"method"`
 	Carrier            string  `json:"carrier"`
 	CourierServiceCode string  `json:"courierServiceCode"` // Carrier-specific courier ID for auto-selection
 	Cost               float64 `json:"cost" binding:"min=0"`
-	Street             string  `json:"street" binding:"required"`
-	City               string  `json:"city" binding:"required"`
-	State              string  `json:"state" binding:"required"`
-	PostalCode         string  `json:"postalCode" binding:"required"`
-	Country            string  `json:"country" binding:"required"`
+	Street             string  `json:"street"`
+	City               string  `json:"city"`
+	State              string  `json:"state"`
+	PostalCode         string  `json:"postalCode"`
+	Country            string  `json:"country"`
 	// Package dimensions (captured at checkout for accurate shipping)
 	PackageWeight float64 `json:"packageWeight"` // Weight in kg
 	PackageLength float64 `json:"packageLength"` // Length in cm
@@ -86,6 +151,21 @@ type CreateOrderShippingRequest struct {
 	BaseRate      float64 `json:"baseRate"`      // Original carrier rate before markup
 	MarkupAmount  float64 `json:"markupAmount"`  // Markup amount applied
 	MarkupPercent float64 `json:"markupPercent"` // Markup percentage (e.g., 10 for 10%)
+	// Per-vendor shipping chosen from cart rates; Cost above is their sum.
+	// Used to attribute shipping when a multi-vendor checkout is split.
+	VendorShipments []CreateOrderVendorShipmentRequest `json:"vendorShipments,omitempty" binding:"omitempty,dive"`
+}
+
+// CreateOrderVendorShipmentRequest is the shipping one vendor's package was rated at
+type CreateOrderVendorShipmentRequest struct {
+	VendorID           string  `json:"vendorId"` // Empty for the tenant's own package
+	Method             string  `json:"method"`
+	Carrier            string  `json:"carrier"`
+	CourierServiceCode string  `json:"courierServiceCode"`
+	Cost               float64 `json:"cost" binding:"min=0"`
+	BaseRate           float64 `json:"baseRate"`
+	MarkupAmount       float64 `json:"markupAmount"`
+	MarkupPercent      float64 `json:"markupPercent"`
 }
 
 type CreateOrderPaymentRequest struct {
@@ -110,6 +190,8 @@ type UpdateOrderRequest struct {
 	ReceiptDocumentID  *uuid.UUID `json:"receiptDocumentId,omitempty"`
 	ReceiptShortURL    string     `json:"receiptShortUrl,omitempty"`
 	ReceiptGeneratedAt *time.Time `json:"receiptGeneratedAt,omitempty"`
+	// ExpectedVersion makes the update conditional; the handler takes it from If-Match when set
+	ExpectedVersion *int `json:"expectedVersion,omitempty"`
 }
 
 type OrderListFilters struct {
@@ -119,8 +201,12 @@ type OrderListFilters struct {
 	Status        *models.OrderStatus
 	DateFrom      *time.Time
 	DateTo        *time.Time
-	Page          int
-	Limit         int
+	// ExcludeVendorSplits lists a marketplace checkout once, as the parent order
+	ExcludeVendorSplits bool
+	// Include lists the relations to load; nil loads all of them
+	Include []string
+	Page    int
+	Limit   int
 }
 
 type OrderListResponse struct {
@@ -158,6 +244,13 @@ type SplitOrderResponse struct {
 	Split         *models.OrderSplit `json:"split"`
 }
 
+// VendorSplitResponse is a marketplace checkout and the vendor orders it was split into
+type VendorSplitResponse struct {
+	ParentOrder  *models.Order       `json:"parentOrder"`
+	VendorOrders []models.Order      `json:"vendorOrders"`
+	Splits       []models.OrderSplit `json:"splits"`
+}
+
 type orderService struct {
 	orderRepo                    repository.OrderRepository
 	returnRepo                   *repository.ReturnRepository
@@ -168,12 +261,15 @@ type orderService struct {
 	notificationClient           clients.NotificationClient
 	tenantClient                 clients.TenantClient
 	shippingClient               clients.ShippingClient
+	inventoryClient              clients.InventoryClient
+	paymentClient                clients.PaymentClient
 	eventsPublisher              *events.Publisher // Optional: for real-time admin notifications via NATS
 	guestTokenService            *GuestTokenService
+	priceLockVerifier            *PriceLockVerifier
 }
 
 // NewOrderService creates a new order service
-func NewOrderService(orderRepo repository.OrderRepository, returnRepo *repository.ReturnRepository, cancellationSettingsService CancellationSettingsService, productsClient clients.ProductsClient, taxClient clients.TaxClient, customersClient clients.CustomersClient, notificationClient clients.NotificationClient, tenantClient clients.TenantClient, shippingClient clients.ShippingClient, eventsPublisher *events.Publisher, guestTokenService *GuestTokenService) OrderService {
+func NewOrderService(orderRepo repository.OrderRepository, returnRepo *repository.ReturnRepository, cancellationSettingsService CancellationSettingsService, productsClient clients.ProductsClient, taxClient clients.TaxClient, customersClient clients.CustomersClient, notificationClient clients.NotificationClient, tenantClient clients.TenantClient, shippingClient clients.ShippingClient, inventoryClient clients.InventoryClient, paymentClient clients.PaymentClient, eventsPublisher *events.Publisher, guestTokenService *GuestTokenService, priceLockVerifier *PriceLockVerifier) OrderService {
 	return &orderService{
 		orderRepo:                    orderRepo,
 		returnRepo:                   returnRepo,
@@ -184,8 +280,11 @@ func NewOrderService(orderRepo repository.OrderRepository, returnRepo *repositor
 		notificationClient:           notificationClient,
 		tenantClient:                 tenantClient,
 		shippingClient:               shippingClient,
+		inventoryClient:              inventoryClient,
+		paymentClient:                paymentClient,
 		eventsPublisher:              eventsPublisher,
 		guestTokenService:            guestTokenService,
+		priceLockVerifier:            priceLockVerifier,
 	}
 }
 
@@ -228,6 +327,41 @@ func (s *orderService) CreateOrder(req CreateOrderRequest, tenantID string) (*mo
 		return nil, fmt.Errorf("insufficient stock for products: %v", outOfStockProducts)
 	}
 
+	// Click-and-collect: the pickup location stands in for the shipping address,
+	// so tax is calculated where the customer takes possession
+	fulfillmentType := models.FulfillmentTypeShipping
+	var pickupLocation *clients.PickupLocation
+	if req.FulfillmentType == models.FulfillmentTypePickup {
+		fulfillmentType = models.FulfillmentTypePickup
+		pickupLocation, err = s.inventoryClient.GetPickupLocation(req.Pickup.LocationID.String(), tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pickup location: %w", err)
+		}
+		req.Shipping = CreateOrderShippingRequest{
+			Method:     "pickup",
+			Street:     pickupLocation.Address1,
+			City:       pickupLocation.City,
+			State:      pickupLocation.State,
+			PostalCode: pickupLocation.PostalCode,
+			Country:    pickupLocation.Country,
+		}
+	}
+
+	// Honor the amounts the customer was quoted at checkout, or ask them to re-quote
+	var priceLock *PriceLock
+	if req.PriceLockToken != "" {
+		if s.priceLockVerifier == nil {
+			return nil, fmt.Errorf("%w: price locks are not enabled", ErrInvalidPriceLock)
+		}
+		priceLock, err = s.priceLockVerifier.Verify(req.PriceLockToken, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyPriceLock(&req, priceLock); err != nil {
+			return nil, err
+		}
+	}
+
 	// Step 2: Calculate subtotal
 	subtotal := s.calculateSubtotal(req.Items)
 	discountAmount := s.calculateDiscountAmount(req.Discounts)
@@ -283,6 +417,7 @@ func (s *orderService) CreateOrder(req CreateOrderRequest, tenantID string) (*mo
 		Status:            models.OrderStatusPlaced,
 		PaymentStatus:     models.PaymentStatusPending,
 		FulfillmentStatus: models.FulfillmentStatusUnfulfilled,
+		FulfillmentType:   fulfillmentType,
 		Currency:          s.getCurrency(req.Currency),
 		Subtotal:          subtotal,
 		TaxAmount:         taxAmount,
@@ -300,6 +435,8 @@ func (s *orderService) CreateOrder(req CreateOrderRequest, tenantID string) (*mo
 		VATAmount:         vatAmount,
 		IsReverseCharge:   isReverseCharge,
 		StorefrontHost:    req.StorefrontHost,
+		StorefrontID:      req.StorefrontID,
+		Attribution:       models.AttributionJSON(req.Attribution),
 	}
 
 	// Set idempotency key if provided
@@ -307,6 +444,17 @@ func (s *orderService) CreateOrder(req CreateOrderRequest, tenantID string) (*mo
 		order.IdempotencyKey = &req.IdempotencyKey
 	}
 
+	// Snapshot product costs so margins reflect what the goods cost when they were sold
+	unitCosts := make(map[string]float64)
+	for _, result := range stockResponse.Results {
+		if result.CostPrice == nil {
+			continue
+		}
+		if cost, err := strconv.ParseFloat(strings.TrimSpace(*result.CostPrice), 64); err == nil && cost >= 0 {
+			unitCosts[result.ProductID] = cost
+		}
+	}
+
 	// Create order items
 	for _, itemReq := range req.Items {
 		item := models.OrderItem{
@@ -319,10 +467,22 @@ func (s *orderService) CreateOrder(req CreateOrderRequest, tenantID string) (*mo
 			Quantity:    itemReq.Quantity,
 			UnitPrice:   itemReq.UnitPrice,
 			TotalPrice:  itemReq.UnitPrice * float64(itemReq.Quantity),
+			VendorID:    itemReq.VendorID,
+			Properties:  models.CleanLineItemProperties(itemReq.Properties),
+		}
+		if cost, ok := unitCosts[itemReq.ProductID.String()]; ok {
+			item.UnitCost = &cost
 		}
 		order.Items = append(order.Items, item)
 	}
 
+	// A checkout from a single vendor is that vendor's order; one spanning several
+	// vendors is split into vendor orders once saved
+	vendorIDs := orderVendorIDs(order.Items)
+	if len(vendorIDs) == 1 {
+		order.VendorID = vendorIDs[0]
+	}
+
 	// Create customer info
 	order.Customer = &models.OrderCustomer{
 		ID:        uuid.New(),
@@ -404,11 +564,60 @@ func (s *orderService) CreateOrder(req CreateOrderRequest, tenantID string) (*mo
 		order.Discounts = append(order.Discounts, discount)
 	}
 
+	// Create pickup info and take the items out of the chosen location's stock.
+	// Unlike shipped orders this must succeed, since the customer is told where to collect.
+	if pickupLocation != nil {
+		order.Pickup = &models.OrderPickup{
+			ID:           uuid.New(),
+			OrderID:      order.ID,
+			LocationID:   req.Pickup.LocationID,
+			LocationName: pickupLocation.Name,
+			Street:       pickupLocation.Address1,
+			City:         pickupLocation.City,
+			State:        pickupLocation.State,
+			PostalCode:   pickupLocation.PostalCode,
+			Country:      pickupLocation.Country,
+		}
+		if pickupLocation.Phone != nil {
+			order.Pickup.Phone = *pickupLocation.Phone
+		}
+		if pickupLocation.PickupInstructions != nil {
+			order.Pickup.Instructions = *pickupLocation.PickupInstructions
+		}
+
+		pickupItems := make([]clients.PickupStockItem, len(req.Items))
+		for i, item := range req.Items {
+			pickupItems[i] = clients.PickupStockItem{
+				ProductID: item.ProductID.String(),
+				SKU:       item.SKU,
+				Quantity:  item.Quantity,
+			}
+		}
+		if err := s.inventoryClient.DeductPickupStock(pickupLocation.ID, order.ID.String(), pickupItems, tenantID); err != nil {
+			return nil, fmt.Errorf("failed to reserve stock at pickup location: %w", err)
+		}
+	}
+
 	// Step 3: Save to database
 	if err := s.orderRepo.Create(order); err != nil {
+		if pickupLocation != nil {
+			if restoreErr := s.inventoryClient.RestorePickupStock(order.ID.String(), tenantID); restoreErr != nil {
+				fmt.Printf("WARNING: Failed to restore pickup stock for unsaved order %s: %v\n", order.ID, restoreErr)
+			}
+		}
 		return nil, fmt.Errorf("failed to create order: %w", err)
 	}
 
+	// The parent order stays valid if the split fails; it can be retried from /orders/:id/split-by-vendor
+	if len(vendorIDs) > 1 {
+		if _, err := s.splitByVendor(order, req.Shipping.VendorShipments, nil, tenantID); err != nil {
+			fmt.Printf("WARNING: Failed to split order %s by vendor: %v\n", order.OrderNumber, err)
+		} else {
+			order.IsSplit = true
+			order.SplitReason = string(models.SplitTypeVendor)
+		}
+	}
+
 	// Step 4: Deduct inventory with idempotency key to prevent duplicate deductions
 	inventoryItems := make([]clients.InventoryItem, len(req.Items))
 	for i, item := range req.Items {
@@ -434,6 +643,8 @@ func (s *orderService) CreateOrder(req CreateOrderRequest, tenantID string) (*mo
 	// At this point, order is PLACED but not yet CONFIRMED (payment pending)
 	// This prevents sending confirmation emails for abandoned checkouts
 
+	metrics.RecordOrder(tenantID, metrics.OrderCreated)
+
 	// Publish order.created event for real-time admin notifications
 	if s.eventsPublisher != nil {
 		s.eventsPublisher.PublishOrderCreated(context.Background(), order, tenantID)
@@ -470,6 +681,9 @@ func (s *orderService) ListOrders(filters OrderListFilters, tenantID string) (*O
 		DateTo:        filters.DateTo,
 		Page:          filters.Page,
 		Limit:         filters.Limit,
+
+		ExcludeVendorSplits: filters.ExcludeVendorSplits,
+		Include:             filters.Include,
 	}
 
 	orders, total, err := s.orderRepo.List(repoFilters)
@@ -492,6 +706,10 @@ func (s *orderService) UpdateOrder(id uuid.UUID, req UpdateOrderRequest, tenantI
 		return nil, err
 	}
 
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != order.Version {
+		return nil, &models.VersionConflictError{CurrentVersion: order.Version}
+	}
+
 	// Update fields
 	if req.Notes != "" {
 		order.Notes = req.Notes
@@ -576,6 +794,10 @@ func (s *orderService) UpdateOrderStatus(id uuid.UUID, status models.OrderStatus
 		}()
 	}
 
+	if previousStatus != status && status == models.OrderStatusCancelled {
+		metrics.RecordOrder(tenantID, metrics.OrderCancelled)
+	}
+
 	// Publish order status change events for real-time admin notifications
 	if s.eventsPublisher != nil && previousStatus != status {
 		switch status {
@@ -650,26 +872,8 @@ func (s *orderService) CancelOrder(id uuid.UUID, reason string, tenantID string)
 	// Add timeline event for the cancellation with the customer's name
 	s.orderRepo.AddTimelineEventByName(id, "ORDER_CANCELLED", cancellationNotes, cancelledBy, tenantID)
 
-	// Restore inventory with idempotency key to prevent duplicate restorations
-	inventoryItems := make([]clients.InventoryItem, len(order.Items))
-	for i, item := range order.Items {
-		inventoryItems[i] = clients.InventoryItem{
-			ProductID: item.ProductID.String(),
-			Quantity:  item.Quantity,
-		}
-	}
-
-	// Use idempotency-enabled method to prevent duplicate restorations on retries
-	if err := s.productsClient.RestoreInventoryWithIdempotency(
-		inventoryItems,
-		fmt.Sprintf("Order %s cancelled", order.OrderNumber),
-		order.ID.String(),
-		tenantID,
-	); err != nil {
-		// Log the error but don't fail the cancellation
-		// The idempotency key ensures this can be safely retried
-		fmt.Printf("WARNING: Failed to restore inventory for order %s: %v\n", order.OrderNumber, err)
-	}
+	s.restoreCancelledOrderStock(order, tenantID)
+	s.cancelVendorOrders(order, cancellationNotes, tenantID)
 
 	// Get updated order
 	updatedOrder, _ := s.orderRepo.GetByID(id, tenantID)
@@ -766,6 +970,46 @@ func (s *orderService) CancelOrder(id uuid.UUID, reason string, tenantID string)
 		}
 	}
 
+	s.notifyOrderCancelled(updatedOrder, reason, tenantID)
+
+	return updatedOrder, nil
+}
+
+// restoreCancelledOrderStock returns a cancelled order's items to stock
+func (s *orderService) restoreCancelledOrderStock(order *models.Order, tenantID string) {
+	// Restore inventory with idempotency key to prevent duplicate restorations
+	inventoryItems := make([]clients.InventoryItem, len(order.Items))
+	for i, item := range order.Items {
+		inventoryItems[i] = clients.InventoryItem{
+			ProductID: item.ProductID.String(),
+			Quantity:  item.Quantity,
+		}
+	}
+
+	// Use idempotency-enabled method to prevent duplicate restorations on retries
+	if err := s.productsClient.RestoreInventoryWithIdempotency(
+		inventoryItems,
+		fmt.Sprintf("Order %s cancelled", order.OrderNumber),
+		order.ID.String(),
+		tenantID,
+	); err != nil {
+		// Log the error but don't fail the cancellation
+		// The idempotency key ensures this can be safely retried
+		fmt.Printf("WARNING: Failed to restore inventory for order %s: %v\n", order.OrderNumber, err)
+	}
+
+	// Return click-and-collect items to the pickup location's stock
+	if order.IsPickup() {
+		if err := s.inventoryClient.RestorePickupStock(order.ID.String(), tenantID); err != nil {
+			fmt.Printf("WARNING: Failed to restore pickup stock for order %s: %v\n", order.OrderNumber, err)
+		}
+	}
+}
+
+// notifyOrderCancelled counts the cancellation, emails the customer and publishes order.cancelled
+func (s *orderService) notifyOrderCancelled(updatedOrder *models.Order, reason string, tenantID string) {
+	metrics.RecordOrder(tenantID, metrics.OrderCancelled)
+
 	// Send order cancelled email via notification-service
 	if s.notificationClient != nil && updatedOrder != nil && updatedOrder.Customer != nil && updatedOrder.Customer.Email != "" {
 		go func() {
@@ -785,10 +1029,71 @@ func (s *orderService) CancelOrder(id uuid.UUID, reason string, tenantID string)
 	if s.eventsPublisher != nil && updatedOrder != nil {
 		s.eventsPublisher.PublishOrderCancelled(context.Background(), updatedOrder, reason, tenantID)
 	}
+}
+
+// ApplySelfCancelStatus sets whether the customer can still cancel each order themselves,
+// for storefront responses
+func (s *orderService) ApplySelfCancelStatus(tenantID string, orders ...*models.Order) {
+	settings := s.getOrderSettings(tenantID)
+	now := time.Now()
+	for _, order := range orders {
+		order.SelfCancel = settings.SelfCancelStatusFor(order, now)
+	}
+}
+
+// CustomerCancelOrder cancels an order on the customer's behalf, enforcing the tenant's self-cancel window
+func (s *orderService) CustomerCancelOrder(id uuid.UUID, reason string, tenantID string) (*models.Order, error) {
+	order, err := s.orderRepo.GetByID(id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.getOrderSettings(tenantID).SelfCancelStatusFor(order, time.Now()).Allowed {
+		return nil, ErrSelfCancelNotAllowed
+	}
+
+	return s.CancelOrder(id, reason, tenantID)
+}
+
+// ExpireUnpaidOrder cancels an order whose payment never arrived. Nothing was charged, so unlike
+// CancelOrder no fee or refund applies; the items are just released back to stock.
+func (s *orderService) ExpireUnpaidOrder(id uuid.UUID, reason string, tenantID string) (*models.Order, error) {
+	order, err := s.orderRepo.GetByID(id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != models.OrderStatusPlaced || order.PaymentStatus == models.PaymentStatusPaid {
+		return nil, fmt.Errorf("order %s is no longer awaiting payment", order.OrderNumber)
+	}
+
+	if err := s.orderRepo.UpdateStatus(id, models.OrderStatusCancelled, fmt.Sprintf("Cancelled: %s", reason), tenantID); err != nil {
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+	s.orderRepo.AddTimelineEventByName(id, "ORDER_CANCELLED", fmt.Sprintf("Cancelled: %s", reason), "system", tenantID)
+
+	s.restoreCancelledOrderStock(order, tenantID)
+
+	updatedOrder, _ := s.orderRepo.GetByID(id, tenantID)
+	s.notifyOrderCancelled(updatedOrder, reason, tenantID)
 
 	return updatedOrder, nil
 }
 
+// getOrderSettings returns the tenant's cancellation and order automation settings, falling back to defaults
+func (s *orderService) getOrderSettings(tenantID string) *models.CancellationSettings {
+	if s.cancellationSettingsService != nil {
+		settings, err := s.cancellationSettingsService.GetSettings(context.Background(), tenantID, "")
+		if err == nil && settings != nil {
+			return settings
+		}
+		if err != nil {
+			fmt.Printf("WARNING: Failed to get cancellation settings for tenant %s: %v\n", tenantID, err)
+		}
+	}
+	return models.DefaultCancellationSettings(tenantID, "")
+}
+
 // RefundOrder processes a refund for an order
 func (s *orderService) RefundOrder(id uuid.UUID, amount *float64, reason string, tenantID string) (*models.Order, error) {
 	order, err := s.orderRepo.GetByID(id, tenantID)
@@ -858,6 +1163,13 @@ func (s *orderService) UpdatePaymentStatus(orderID uuid.UUID, paymentStatus mode
 		return nil, fmt.Errorf("failed to update payment status: %w", err)
 	}
 
+	if paymentStatus == models.PaymentStatusPaid && order.PaymentStatus != models.PaymentStatusPaid {
+		metrics.RecordOrder(tenantID, metrics.OrderPaid)
+	}
+
+	// The customer pays a split marketplace checkout once, on the parent
+	vendorOrders := s.syncVendorOrderPayment(order, paymentStatus, transactionID, &now, tenantID)
+
 	// If payment completed (PAID), update order status to confirmed and record customer order
 	if paymentStatus == models.PaymentStatusPaid && order.Status == models.OrderStatusPlaced {
 		if err := s.orderRepo.UpdateStatus(orderID, models.OrderStatusConfirmed, "Payment completed", tenantID); err != nil {
@@ -889,8 +1201,15 @@ func (s *orderService) UpdatePaymentStatus(orderID uuid.UUID, paymentStatus mode
 			s.eventsPublisher.PublishPaymentReceived(context.Background(), updatedOrder, transactionID, tenantID)
 		}
 
-		// Auto-create shipment using customer's selected carrier from checkout
-		if s.shippingClient != nil && updatedOrder != nil && updatedOrder.Shipping != nil {
+		// Auto-create shipment using customer's selected carrier from checkout.
+		// A split checkout ships as its vendor orders rather than as the parent.
+		if s.shippingClient != nil && len(vendorOrders) > 0 {
+			for i := range vendorOrders {
+				if vendorOrders[i].Shipping != nil {
+					go s.autoCreateShipment(&vendorOrders[i], tenantID)
+				}
+			}
+		} else if s.shippingClient != nil && updatedOrder != nil && updatedOrder.Shipping != nil {
 			go s.autoCreateShipment(updatedOrder, tenantID)
 		}
 	}
@@ -978,10 +1297,23 @@ func (s *orderService) recordCustomerOrder(order *models.Order, tenantID string)
 // autoCreateShipment creates a shipment automatically after payment is confirmed
 // Uses the carrier and shipping cost selected by customer at checkout
 func (s *orderService) autoCreateShipment(order *models.Order, tenantID string) {
+	if order.IsImported() {
+		fmt.Printf("[OrderService] Order %s was imported from %s, skipping auto-shipment\n", order.OrderNumber, order.ImportSource)
+		return
+	}
+	if order.IsPickup() {
+		fmt.Printf("[OrderService] Order %s is collected from a pickup location, skipping auto-shipment\n", order.OrderNumber)
+		return
+	}
 	if order.Shipping == nil {
 		fmt.Printf("[OrderService] No shipping info for order %s, skipping auto-shipment\n", order.OrderNumber)
 		return
 	}
+	// Vendors ship their own orders from their own origin
+	if order.VendorID != "" && order.IsVendorSplitChild() {
+		fmt.Printf("[OrderService] Order %s is fulfilled by vendor %s, skipping auto-shipment\n", order.OrderNumber, order.VendorID)
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -1285,6 +1617,11 @@ func (s *orderService) AddShippingTracking(id uuid.UUID, carrier string, trackin
 	if order.Status != models.OrderStatusConfirmed && order.Status != models.OrderStatusProcessing {
 		return nil, fmt.Errorf("cannot add tracking to order with status: %s", order.Status)
 	}
+	if has, err := s.orderRepo.HasFulfillments(id, tenantID); err != nil {
+		return nil, err
+	} else if has {
+		return nil, ErrOrderHasFulfillments
+	}
 
 	// Update shipping with tracking information
 	if err := s.orderRepo.UpdateShippingTracking(id, carrier, trackingNumber, trackingUrl, tenantID); err != nil {
@@ -1467,6 +1804,9 @@ func (s *orderService) UpdateFulfillmentStatus(id uuid.UUID, status models.Fulfi
 	if err := models.ValidateFulfillmentStatusTransition(order.FulfillmentStatus, status); err != nil {
 		return nil, fmt.Errorf("invalid fulfillment status transition: %w", err)
 	}
+	if err := models.ValidateFulfillmentStatusForType(status, order.FulfillmentType); err != nil {
+		return nil, fmt.Errorf("invalid fulfillment status transition: %w", err)
+	}
 
 	// Check that order is in a valid state for fulfillment updates
 	if order.Status == models.OrderStatusCancelled {
@@ -1475,6 +1815,14 @@ func (s *orderService) UpdateFulfillmentStatus(id uuid.UUID, status models.Fulfi
 	if order.PaymentStatus != models.PaymentStatusPaid && order.PaymentStatus != models.PaymentStatusPartiallyRefunded {
 		return nil, fmt.Errorf("cannot update fulfillment status before payment is confirmed")
 	}
+	if order.IsVendorSplitParent() {
+		return nil, fmt.Errorf("order is fulfilled through its vendor orders")
+	}
+	if has, err := s.orderRepo.HasFulfillments(id, tenantID); err != nil {
+		return nil, err
+	} else if has {
+		return nil, ErrOrderHasFulfillments
+	}
 
 	// Update fulfillment status
 	if err := s.orderRepo.UpdateFulfillmentStatus(id, status, notes, tenantID); err != nil {
@@ -1516,6 +1864,41 @@ func (s *orderService) UpdateFulfillmentStatus(id uuid.UUID, status models.Fulfi
 		}
 	}
 
+	// Click-and-collect: notify the customer once the order is waiting at the location
+	if status == models.FulfillmentStatusReadyForPickup {
+		if s.eventsPublisher != nil {
+			s.eventsPublisher.PublishOrderReadyForPickup(context.Background(), updatedOrder, tenantID)
+		}
+		if s.notificationClient != nil && updatedOrder.Customer != nil && updatedOrder.Customer.Email != "" {
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+
+				notification := s.buildOrderNotification(ctx, updatedOrder, tenantID)
+				if err := s.notificationClient.SendOrderReadyForPickup(ctx, notification); err != nil {
+					fmt.Printf("WARNING: Failed to send order ready for pickup email: %v\n", err)
+				}
+			}()
+		}
+	}
+
+	// If collected, move order to COMPLETED
+	if status == models.FulfillmentStatusPickedUp && updatedOrder.Status == models.OrderStatusProcessing {
+		if err := s.orderRepo.UpdateStatus(id, models.OrderStatusCompleted, "Order picked up", tenantID); err != nil {
+			fmt.Printf("WARNING: Failed to update order status to completed: %v\n", err)
+		}
+
+		finalOrder, _ := s.orderRepo.GetByID(id, tenantID)
+		if s.eventsPublisher != nil && finalOrder != nil {
+			s.eventsPublisher.PublishOrderPickedUp(context.Background(), finalOrder, tenantID)
+		}
+	}
+
+	// A vendor order's progress moves the customer's parent order along
+	if updatedOrder.IsVendorSplitChild() {
+		s.rollUpVendorFulfillment(updatedOrder, tenantID)
+	}
+
 	// Send order shipped email when dispatched
 	if status == models.FulfillmentStatusDispatched && s.notificationClient != nil && updatedOrder.Customer != nil && updatedOrder.Customer.Email != "" {
 		go func() {
@@ -1546,7 +1929,7 @@ func (s *orderService) GetValidStatusTransitions(id uuid.UUID, tenantID string)
 		CurrentFulfillmentStatus: order.FulfillmentStatus,
 		ValidOrderStatuses:       models.GetNextValidOrderStatuses(order.Status),
 		ValidPaymentStatuses:     models.GetNextValidPaymentStatuses(order.PaymentStatus),
-		ValidFulfillmentStatuses: models.GetNextValidFulfillmentStatuses(order.FulfillmentStatus),
+		ValidFulfillmentStatuses: models.GetNextValidFulfillmentStatusesForType(order.FulfillmentStatus, order.FulfillmentType),
 	}, nil
 }
 
@@ -1569,6 +1952,7 @@ func (s *orderService) buildOrderNotification(ctx context.Context, order *models
 	// Set customer details
 	if order.Customer != nil {
 		notification.CustomerEmail = order.Customer.Email
+		notification.CustomerPhone = order.Customer.Phone
 		notification.CustomerName = fmt.Sprintf("%s %s", order.Customer.FirstName, order.Customer.LastName)
 	}
 
@@ -1612,14 +1996,28 @@ func (s *orderService) buildOrderNotification(ctx context.Context, order *models
 	// Build order items
 	for _, item := range order.Items {
 		notification.Items = append(notification.Items, clients.OrderItem{
-			Name:     item.ProductName,
-			SKU:      item.SKU,
-			Quantity: item.Quantity,
-			Price:    fmt.Sprintf("%.2f", item.UnitPrice),
-			Currency: order.Currency,
+			Name:       item.ProductName,
+			SKU:        item.SKU,
+			Quantity:   item.Quantity,
+			Price:      fmt.Sprintf("%.2f", item.UnitPrice),
+			Currency:   order.Currency,
+			Properties: item.Properties,
 		})
 	}
 
+	// Set pickup location for click-and-collect orders
+	if order.Pickup != nil {
+		notification.PickupLocation = &clients.Address{
+			Name:       order.Pickup.LocationName,
+			Line1:      order.Pickup.Street,
+			City:       order.Pickup.City,
+			State:      order.Pickup.State,
+			PostalCode: order.Pickup.PostalCode,
+			Country:    order.Pickup.Country,
+		}
+		notification.PickupInstructions = order.Pickup.Instructions
+	}
+
 	// Set shipping address
 	if order.Shipping != nil {
 		notification.ShippingAddress = &clients.Address{
@@ -1733,6 +2131,8 @@ func (s *orderService) SplitOrder(id uuid.UUID, req models.SplitOrderRequest, us
 			Quantity:    item.Quantity,
 			UnitPrice:   item.UnitPrice,
 			TotalPrice:  item.TotalPrice,
+			UnitCost:    item.UnitCost,
+			Properties:  item.Properties,
 			TaxAmount:   item.TaxAmount,
 			TaxRate:     item.TaxRate,
 			HSNCode:     item.HSNCode,
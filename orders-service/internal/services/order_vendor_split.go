@@ -0,0 +1,374 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"orders-service/internal/models"
+)
+
+// orderVendorIDs returns the distinct vendors fulfilling the items, in the order they first
+// appear. Items without a vendor come from the tenant's own stock and count as one more vendor.
+func orderVendorIDs(items []models.OrderItem) []string {
+	seen := make(map[string]bool)
+	var vendorIDs []string
+	for _, item := range items {
+		if !seen[item.VendorID] {
+			seen[item.VendorID] = true
+			vendorIDs = append(vendorIDs, item.VendorID)
+		}
+	}
+	return vendorIDs
+}
+
+// SplitOrderByVendor splits a marketplace checkout into one vendor order per vendor.
+// Checkouts are split automatically when created; this retries a split that failed then.
+func (s *orderService) SplitOrderByVendor(id uuid.UUID, userID *uuid.UUID, tenantID string) (*VendorSplitResponse, error) {
+	order, err := s.orderRepo.GetByID(id, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if order.ParentOrderID != nil || order.IsSplit {
+		return nil, fmt.Errorf("order has already been split")
+	}
+	if order.Status == models.OrderStatusCancelled {
+		return nil, fmt.Errorf("cannot split a cancelled order")
+	}
+	if order.FulfillmentStatus != models.FulfillmentStatusUnfulfilled {
+		return nil, fmt.Errorf("cannot split an order once fulfillment has started")
+	}
+	if len(orderVendorIDs(order.Items)) < 2 {
+		return nil, fmt.Errorf("all items in the order are fulfilled by the same vendor")
+	}
+
+	return s.splitByVendor(order, nil, userID, tenantID)
+}
+
+// splitByVendor creates a vendor order for each vendor's items. The parent keeps every item and
+// the customer's totals; each vendor order carries the vendor's share of tax, discount and shipping
+// so it can be fulfilled, shipped and paid out on its own. Shipping comes from the vendor's
+// checkout shipment when every vendor has one, and is otherwise shared out by subtotal.
+func (s *orderService) splitByVendor(order *models.Order, shipments []CreateOrderVendorShipmentRequest, userID *uuid.UUID, tenantID string) (*VendorSplitResponse, error) {
+	vendorIDs := orderVendorIDs(order.Items)
+	itemsByVendor := make(map[string][]models.OrderItem)
+	subtotals := make([]float64, len(vendorIDs))
+	for _, item := range order.Items {
+		itemsByVendor[item.VendorID] = append(itemsByVendor[item.VendorID], item)
+	}
+	for i, vendorID := range vendorIDs {
+		for _, item := range itemsByVendor[vendorID] {
+			subtotals[i] += item.TotalPrice
+		}
+	}
+
+	shipmentByVendor := make(map[string]*CreateOrderVendorShipmentRequest)
+	for i := range shipments {
+		shipmentByVendor[shipments[i].VendorID] = &shipments[i]
+	}
+	useShipments := len(shipmentByVendor) > 0
+	for _, vendorID := range vendorIDs {
+		if shipmentByVendor[vendorID] == nil {
+			useShipments = false
+		}
+	}
+
+	taxShares := allocateBySubtotal(order.TaxAmount, subtotals)
+	discountShares := allocateBySubtotal(order.DiscountAmount, subtotals)
+	shippingShares := allocateBySubtotal(order.ShippingCost, subtotals)
+
+	vendorOrders := make([]models.Order, 0, len(vendorIDs))
+	splits := make([]models.OrderSplit, 0, len(vendorIDs))
+	for i, vendorID := range vendorIDs {
+		shipment := shipmentByVendor[vendorID]
+		shippingCost := shippingShares[i]
+		if useShipments {
+			shippingCost = shipment.Cost
+		}
+		total := roundCurrency(subtotals[i] + taxShares[i] + shippingCost - discountShares[i])
+
+		vendorOrder := models.Order{
+			ID:                uuid.New(),
+			TenantID:          tenantID,
+			VendorID:          vendorID,
+			OrderNumber:       fmt.Sprintf("%s-%d", order.OrderNumber, i+1),
+			CustomerID:        order.CustomerID,
+			Status:            order.Status,
+			PaymentStatus:     order.PaymentStatus,
+			FulfillmentStatus: models.FulfillmentStatusUnfulfilled,
+			FulfillmentType:   order.FulfillmentType,
+			Currency:          order.Currency,
+			Subtotal:          subtotals[i],
+			TaxAmount:         taxShares[i],
+			ShippingCost:      shippingCost,
+			DiscountAmount:    discountShares[i],
+			Total:             total,
+			Notes:             fmt.Sprintf("Vendor order for %s", order.OrderNumber),
+			IsInterstate:      order.IsInterstate,
+			IsReverseCharge:   order.IsReverseCharge,
+			StorefrontHost:    order.StorefrontHost,
+			StorefrontID:      order.StorefrontID,
+			ParentOrderID:     &order.ID,
+			IsSplit:           true,
+			SplitReason:       string(models.SplitTypeVendor),
+		}
+
+		itemIDs := make([]uuid.UUID, 0, len(itemsByVendor[vendorID]))
+		for _, item := range itemsByVendor[vendorID] {
+			itemIDs = append(itemIDs, item.ID)
+			vendorOrder.Items = append(vendorOrder.Items, models.OrderItem{
+				VendorID:    item.VendorID,
+				ProductID:   item.ProductID,
+				ProductName: item.ProductName,
+				SKU:         item.SKU,
+				Image:       item.Image,
+				Quantity:    item.Quantity,
+				UnitPrice:   item.UnitPrice,
+				TotalPrice:  item.TotalPrice,
+				UnitCost:    item.UnitCost,
+				Properties:  item.Properties,
+				TaxAmount:   item.TaxAmount,
+				TaxRate:     item.TaxRate,
+				HSNCode:     item.HSNCode,
+				SACCode:     item.SACCode,
+				GSTSlab:     item.GSTSlab,
+				CGSTAmount:  item.CGSTAmount,
+				SGSTAmount:  item.SGSTAmount,
+				IGSTAmount:  item.IGSTAmount,
+			})
+		}
+
+		if order.Customer != nil {
+			vendorOrder.Customer = &models.OrderCustomer{
+				FirstName: order.Customer.FirstName,
+				LastName:  order.Customer.LastName,
+				Email:     order.Customer.Email,
+				Phone:     order.Customer.Phone,
+			}
+		}
+
+		if order.Shipping != nil {
+			vendorOrder.Shipping = &models.OrderShipping{
+				Method:      order.Shipping.Method,
+				Carrier:     order.Shipping.Carrier,
+				Cost:        shippingCost,
+				Street:      order.Shipping.Street,
+				City:        order.Shipping.City,
+				State:       order.Shipping.State,
+				StateCode:   order.Shipping.StateCode,
+				PostalCode:  order.Shipping.PostalCode,
+				Country:     order.Shipping.Country,
+				CountryCode: order.Shipping.CountryCode,
+			}
+			if useShipments {
+				vendorOrder.Shipping.Method = shipment.Method
+				vendorOrder.Shipping.Carrier = shipment.Carrier
+				vendorOrder.Shipping.CourierServiceCode = shipment.CourierServiceCode
+				vendorOrder.Shipping.BaseRate = shipment.BaseRate
+				vendorOrder.Shipping.MarkupAmount = shipment.MarkupAmount
+				vendorOrder.Shipping.MarkupPercent = shipment.MarkupPercent
+			}
+		}
+
+		if order.Pickup != nil {
+			pickup := *order.Pickup
+			pickup.ID = uuid.Nil
+			pickup.OrderID = uuid.Nil
+			vendorOrder.Pickup = &pickup
+		}
+
+		// The customer pays the parent once; each vendor order records its share for payouts
+		if order.Payment != nil {
+			vendorOrder.Payment = &models.OrderPayment{
+				Method:        order.Payment.Method,
+				Status:        order.Payment.Status,
+				Amount:        total,
+				Currency:      order.Payment.Currency,
+				TransactionID: order.Payment.TransactionID,
+				ProcessedAt:   order.Payment.ProcessedAt,
+			}
+		}
+
+		itemIDsJSON, _ := json.Marshal(itemIDs)
+		splits = append(splits, models.OrderSplit{
+			TenantID:        tenantID,
+			OriginalOrderID: order.ID,
+			NewOrderID:      vendorOrder.ID,
+			SplitType:       models.SplitTypeVendor,
+			ItemIDs:         models.JSONB(itemIDsJSON),
+			Reason:          vendorSplitReason(vendorID),
+			CreatedBy:       userID,
+		})
+		vendorOrders = append(vendorOrders, vendorOrder)
+	}
+
+	if err := s.orderRepo.CreateVendorSplit(order, vendorOrders, splits); err != nil {
+		return nil, fmt.Errorf("failed to split order by vendor: %w", err)
+	}
+
+	description := fmt.Sprintf("Order split into %d vendor orders", len(vendorOrders))
+	s.orderRepo.AddTimelineEvent(order.ID, "ORDER_SPLIT", description, userID, tenantID)
+
+	updatedParent, _ := s.orderRepo.GetByID(order.ID, tenantID)
+	createdVendorOrders, _ := s.orderRepo.GetChildOrders(order.ID, tenantID)
+
+	return &VendorSplitResponse{
+		ParentOrder:  updatedParent,
+		VendorOrders: createdVendorOrders,
+		Splits:       splits,
+	}, nil
+}
+
+// vendorSplitReason describes whose items a vendor order holds
+func vendorSplitReason(vendorID string) string {
+	if vendorID == "" {
+		return "Items fulfilled by the store"
+	}
+	return fmt.Sprintf("Items fulfilled by vendor %s", vendorID)
+}
+
+// allocateBySubtotal shares an order-level amount between vendors in proportion to their
+// subtotals, rounded to cents. The last vendor takes the rounding difference so the shares
+// always add up to the amount.
+func allocateBySubtotal(amount float64, subtotals []float64) []float64 {
+	shares := make([]float64, len(subtotals))
+	if amount == 0 || len(subtotals) == 0 {
+		return shares
+	}
+
+	var total float64
+	for _, subtotal := range subtotals {
+		total += subtotal
+	}
+
+	var allocated float64
+	for i, subtotal := range subtotals {
+		if i == len(subtotals)-1 {
+			shares[i] = roundCurrency(amount - allocated)
+			break
+		}
+		if total > 0 {
+			shares[i] = roundCurrency(amount * subtotal / total)
+		} else {
+			shares[i] = roundCurrency(amount / float64(len(subtotals)))
+		}
+		allocated += shares[i]
+	}
+	return shares
+}
+
+// vendorOrders returns the vendor orders of a split marketplace checkout
+func (s *orderService) vendorOrders(order *models.Order, tenantID string) []models.Order {
+	if !order.IsVendorSplitParent() {
+		return nil
+	}
+	children, err := s.orderRepo.GetChildOrders(order.ID, tenantID)
+	if err != nil {
+		fmt.Printf("WARNING: Failed to load vendor orders for order %s: %v\n", order.OrderNumber, err)
+		return nil
+	}
+	vendorOrders := children[:0]
+	for _, child := range children {
+		if child.IsVendorSplitChild() {
+			vendorOrders = append(vendorOrders, child)
+		}
+	}
+	return vendorOrders
+}
+
+// syncVendorOrderPayment mirrors a payment on the parent checkout to its vendor orders,
+// confirming them once paid
+func (s *orderService) syncVendorOrderPayment(order *models.Order, paymentStatus models.PaymentStatus, transactionID string, processedAt *time.Time, tenantID string) []models.Order {
+	vendorOrders := s.vendorOrders(order, tenantID)
+	for _, vendorOrder := range vendorOrders {
+		if err := s.orderRepo.UpdatePaymentStatus(vendorOrder.ID, paymentStatus, transactionID, processedAt, tenantID); err != nil {
+			fmt.Printf("WARNING: Failed to update payment status of vendor order %s: %v\n", vendorOrder.OrderNumber, err)
+			continue
+		}
+		if paymentStatus == models.PaymentStatusPaid && vendorOrder.Status == models.OrderStatusPlaced {
+			if err := s.orderRepo.UpdateStatus(vendorOrder.ID, models.OrderStatusConfirmed, "Payment completed", tenantID); err != nil {
+				fmt.Printf("WARNING: Failed to confirm vendor order %s: %v\n", vendorOrder.OrderNumber, err)
+			}
+		}
+	}
+	return vendorOrders
+}
+
+// cancelVendorOrders cancels the vendor orders of a cancelled marketplace checkout
+func (s *orderService) cancelVendorOrders(order *models.Order, notes string, tenantID string) {
+	for _, vendorOrder := range s.vendorOrders(order, tenantID) {
+		if vendorOrder.Status == models.OrderStatusCancelled {
+			continue
+		}
+		if err := s.orderRepo.UpdateStatus(vendorOrder.ID, models.OrderStatusCancelled, notes, tenantID); err != nil {
+			fmt.Printf("WARNING: Failed to cancel vendor order %s: %v\n", vendorOrder.OrderNumber, err)
+		}
+	}
+}
+
+// fulfillmentProgress ranks the shipping fulfillment statuses an order can roll up to, from its
+// vendor orders or from its fulfillments
+var fulfillmentProgress = map[models.FulfillmentStatus]int{
+	models.FulfillmentStatusUnfulfilled:      0,
+	models.FulfillmentStatusProcessing:       1,
+	models.FulfillmentStatusPacked:           2,
+	models.FulfillmentStatusPartiallyShipped: 3,
+	models.FulfillmentStatusDispatched:       4,
+	models.FulfillmentStatusInTransit:        5,
+	models.FulfillmentStatusOutForDelivery:   6,
+	models.FulfillmentStatusDelivered:        7,
+}
+
+// rollUpVendorFulfillment moves the parent checkout to the least advanced fulfillment status of
+// its vendor orders, so the customer's order shows delivered once every vendor has delivered.
+// Failed deliveries, returns and pickup statuses are left on the vendor orders.
+func (s *orderService) rollUpVendorFulfillment(vendorOrder *models.Order, tenantID string) {
+	parent, err := s.orderRepo.GetByID(*vendorOrder.ParentOrderID, tenantID)
+	if err != nil {
+		fmt.Printf("WARNING: Failed to load parent of vendor order %s: %v\n", vendorOrder.OrderNumber, err)
+		return
+	}
+
+	least := -1
+	var rolledUp models.FulfillmentStatus
+	for _, sibling := range s.vendorOrders(parent, tenantID) {
+		if sibling.Status == models.OrderStatusCancelled {
+			continue
+		}
+		rank, ok := fulfillmentProgress[sibling.FulfillmentStatus]
+		if !ok {
+			return
+		}
+		if least == -1 || rank < least {
+			least = rank
+			rolledUp = sibling.FulfillmentStatus
+		}
+	}
+	if least == -1 || rolledUp == parent.FulfillmentStatus {
+		return
+	}
+	if rank, ok := fulfillmentProgress[parent.FulfillmentStatus]; ok && rank > least {
+		return
+	}
+
+	if err := s.orderRepo.UpdateFulfillmentStatus(parent.ID, rolledUp, "Updated from vendor orders", tenantID); err != nil {
+		fmt.Printf("WARNING: Failed to roll up fulfillment for order %s: %v\n", parent.OrderNumber, err)
+		return
+	}
+
+	status := parent.Status
+	if rolledUp != models.FulfillmentStatusUnfulfilled && status == models.OrderStatusConfirmed {
+		if err := s.orderRepo.UpdateStatus(parent.ID, models.OrderStatusProcessing, "Fulfillment started", tenantID); err != nil {
+			fmt.Printf("WARNING: Failed to update order status to processing: %v\n", err)
+			return
+		}
+		status = models.OrderStatusProcessing
+	}
+	if rolledUp == models.FulfillmentStatusDelivered && status == models.OrderStatusProcessing {
+		if err := s.orderRepo.UpdateStatus(parent.ID, models.OrderStatusCompleted, "All vendor orders delivered", tenantID); err != nil {
+			fmt.Printf("WARNING: Failed to update order status to completed: %v\n", err)
+		}
+	}
+}
@@ -55,6 +55,10 @@ func MaskOrderForPublic(order *models.Order) map[string]interface{} {
 		"createdAt":     order.CreatedAt.Format(time.RFC3339),
 	}
 
+	if order.SelfCancel != nil {
+		result["selfCancel"] = order.SelfCancel
+	}
+
 	// Items (public info only)
 	var items []map[string]interface{}
 	for _, item := range order.Items {
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrInvalidPriceLock is returned for a price-lock token that is malformed, forged or issued
+// for another tenant or customer
+var ErrInvalidPriceLock = errors.New("invalid price lock token")
+
+// Requote reasons reported when a price lock can no longer be honored
+const (
+	RequoteReasonExpired         = "expired"
+	RequoteReasonItemsChanged    = "items_changed"
+	RequoteReasonCurrencyChanged = "currency_changed"
+)
+
+// RequoteRequiredError means the checkout no longer matches its price lock, so the customer
+// has to revalidate the cart and confirm the new totals
+type RequoteRequiredError struct {
+	Reason  string
+	Message string
+}
+
+func (e *RequoteRequiredError) Error() string {
+	return e.Message
+}
+
+// PriceLockItem is a cart line at its locked unit price
+type PriceLockItem struct {
+	ProductID string  `json:"productId"`
+	VariantID string  `json:"variantId,omitempty"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unitPrice"`
+}
+
+// PriceLock is the payload of a price-lock token issued by customers-service when the cart
+// is validated at checkout. It only locks catalog unit prices; discounts, shipping and tax are
+// computed as for any other order. Keep in sync with customers-service's CartPriceLock.
+type PriceLock struct {
+	TenantID   string          `json:"tenantId"`
+	CartID     string          `json:"cartId"`
+	CustomerID string          `json:"customerId"`
+	Currency   string          `json:"currency,omitempty"`
+	Items      []PriceLockItem `json:"items"`
+	Subtotal   float64         `json:"subtotal"`
+	IssuedAt   int64           `json:"iat"`
+	ExpiresAt  int64           `json:"exp"`
+}
+
+// PriceLockVerifier verifies the stateless HMAC-SHA256 price-lock tokens signed by customers-service
+type PriceLockVerifier struct {
+	secret []byte
+}
+
+// NewPriceLockVerifier creates a verifier using the CART_PRICE_LOCK_SECRET env var shared with
+// customers-service. Without it, orders carrying a price-lock token are rejected.
+func NewPriceLockVerifier() *PriceLockVerifier {
+	secret := os.Getenv("CART_PRICE_LOCK_SECRET")
+	if secret == "" {
+		fmt.Println("WARNING: CART_PRICE_LOCK_SECRET not set, cart price locks are disabled")
+	}
+	return &PriceLockVerifier{secret: []byte(secret)}
+}
+
+// Verify checks the token's signature, tenant and expiry and returns the locked quote
+func (v *PriceLockVerifier) Verify(token, tenantID string) (*PriceLock, error) {
+	if len(v.secret) == 0 {
+		return nil, fmt.Errorf("%w: price locks are not enabled", ErrInvalidPriceLock)
+	}
+
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidPriceLock
+	}
+	providedMAC, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidPriceLock
+	}
+
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(parts[0]))
+	if !hmac.Equal(providedMAC, mac.Sum(nil)) {
+		return nil, ErrInvalidPriceLock
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidPriceLock
+	}
+	var lock PriceLock
+	if err := json.Unmarshal(payload, &lock); err != nil {
+		return nil, ErrInvalidPriceLock
+	}
+
+	if lock.TenantID != tenantID {
+		return nil, ErrInvalidPriceLock
+	}
+	if time.Now().Unix() > lock.ExpiresAt {
+		return nil, &RequoteRequiredError{
+			Reason:  RequoteReasonExpired,
+			Message: "price lock has expired; revalidate the cart to get current prices",
+		}
+	}
+
+	return &lock, nil
+}
+
+// applyPriceLock replaces the request's unit prices and currency with the locked ones. It fails
+// with a RequoteRequiredError when the items or currency differ from what was quoted.
+func applyPriceLock(req *CreateOrderRequest, lock *PriceLock) error {
+	if lock.CustomerID != req.CustomerID.String() {
+		return ErrInvalidPriceLock
+	}
+
+	if lock.Currency != "" && req.Currency != "" && !strings.EqualFold(lock.Currency, req.Currency) {
+		return &RequoteRequiredError{
+			Reason:  RequoteReasonCurrencyChanged,
+			Message: fmt.Sprintf("prices were locked in %s but the order is in %s", lock.Currency, strings.ToUpper(req.Currency)),
+		}
+	}
+
+	// Order items carry no variant, so lines are matched on product and quantity
+	if len(req.Items) != len(lock.Items) {
+		return &RequoteRequiredError{
+			Reason:  RequoteReasonItemsChanged,
+			Message: "cart items changed since prices were locked",
+		}
+	}
+	used := make([]bool, len(lock.Items))
+	for i, item := range req.Items {
+		matched := false
+		for j, locked := range lock.Items {
+			if used[j] || locked.ProductID != item.ProductID.String() || locked.Quantity != item.Quantity {
+				continue
+			}
+			used[j] = true
+			req.Items[i].UnitPrice = locked.UnitPrice
+			matched = true
+			break
+		}
+		if !matched {
+			return &RequoteRequiredError{
+				Reason:  RequoteReasonItemsChanged,
+				Message: fmt.Sprintf("%s changed since prices were locked", item.ProductName),
+			}
+		}
+	}
+
+	if req.Currency == "" {
+		req.Currency = lock.Currency
+	}
+	return nil
+}
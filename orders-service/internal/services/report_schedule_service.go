@@ -0,0 +1,535 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"orders-service/internal/clients"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+const (
+	// reportLinkExpiry is how long the emailed download link stays valid (document-service caps presigned URLs at 7 days)
+	reportLinkExpiry      = 7 * 24 * time.Hour
+	reportDownloadExpiry  = 15 * time.Minute
+	reportDeliveriesLimit = 50
+)
+
+var (
+	// ErrReportScheduleNotFound is returned when a schedule doesn't exist for the tenant
+	ErrReportScheduleNotFound = errors.New("report schedule not found")
+	// ErrReportDeliveryNotFound is returned when a delivery doesn't exist for the tenant
+	ErrReportDeliveryNotFound = errors.New("report delivery not found")
+	// ErrReportFileUnavailable is returned when a delivery failed before its file was stored
+	ErrReportFileUnavailable = errors.New("report file is not available")
+)
+
+// ReportScheduleService manages tenants' scheduled reports and renders and emails them when due
+type ReportScheduleService struct {
+	repo                *repository.ReportScheduleRepository
+	tenantAnalytics     *TenantAnalyticsService
+	vendorAnalyticsRepo *repository.VendorAnalyticsRepository
+	inventoryClient     clients.InventoryClient
+	productsClient      clients.ProductsClient
+	documentClient      clients.DocumentClient
+	notificationClient  clients.NotificationClient
+	bucket              string
+	pathPrefix          string
+}
+
+// NewReportScheduleService creates a new report schedule service
+func NewReportScheduleService(
+	repo *repository.ReportScheduleRepository,
+	tenantAnalytics *TenantAnalyticsService,
+	vendorAnalyticsRepo *repository.VendorAnalyticsRepository,
+	inventoryClient clients.InventoryClient,
+	productsClient clients.ProductsClient,
+	documentClient clients.DocumentClient,
+	notificationClient clients.NotificationClient,
+) *ReportScheduleService {
+	bucket := os.Getenv("REPORT_STORAGE_BUCKET")
+	if bucket == "" {
+		bucket = os.Getenv("RECEIPT_STORAGE_BUCKET")
+	}
+	if bucket == "" {
+		bucket = "marketplace-receipts"
+	}
+
+	pathPrefix := os.Getenv("REPORT_STORAGE_PATH_PREFIX")
+	if pathPrefix == "" {
+		pathPrefix = "reports"
+	}
+
+	return &ReportScheduleService{
+		repo:                repo,
+		tenantAnalytics:     tenantAnalytics,
+		vendorAnalyticsRepo: vendorAnalyticsRepo,
+		inventoryClient:     inventoryClient,
+		productsClient:      productsClient,
+		documentClient:      documentClient,
+		notificationClient:  notificationClient,
+		bucket:              bucket,
+		pathPrefix:          pathPrefix,
+	}
+}
+
+// List returns the tenant's report schedules
+func (s *ReportScheduleService) List(ctx context.Context, tenantID string) ([]models.ReportSchedule, error) {
+	return s.repo.List(ctx, tenantID)
+}
+
+// Get returns one of the tenant's report schedules
+func (s *ReportScheduleService) Get(ctx context.Context, tenantID string, id uuid.UUID) (*models.ReportSchedule, error) {
+	schedule, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrReportScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// Create adds a report schedule; its first run is the next matching time
+func (s *ReportScheduleService) Create(ctx context.Context, tenantID, actor string, req *models.ReportScheduleRequest) (*models.ReportSchedule, error) {
+	schedule := &models.ReportSchedule{
+		TenantID:   tenantID,
+		HourUTC:    6,
+		DayOfWeek:  1,
+		DayOfMonth: 1,
+		IsActive:   true,
+		CreatedBy:  actor,
+		UpdatedBy:  actor,
+	}
+	applyReportScheduleRequest(schedule, req)
+	schedule.NextRunAt = schedule.NextRunAfter(time.Now())
+
+	if err := s.repo.Create(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Update replaces a report schedule's settings and recomputes its next run
+func (s *ReportScheduleService) Update(ctx context.Context, tenantID string, id uuid.UUID, actor string, req *models.ReportScheduleRequest) (*models.ReportSchedule, error) {
+	schedule, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyReportScheduleRequest(schedule, req)
+	schedule.UpdatedBy = actor
+	schedule.NextRunAt = schedule.NextRunAfter(time.Now())
+
+	if err := s.repo.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Delete removes a report schedule; its delivery history is kept
+func (s *ReportScheduleService) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	if _, err := s.Get(ctx, tenantID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, tenantID, id)
+}
+
+// ListDeliveries returns a schedule's most recent deliveries
+func (s *ReportScheduleService) ListDeliveries(ctx context.Context, tenantID string, id uuid.UUID) ([]models.ReportDelivery, error) {
+	if _, err := s.Get(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, tenantID, id, reportDeliveriesLimit)
+}
+
+// GetDownload returns a short-lived link to a delivered report file
+func (s *ReportScheduleService) GetDownload(ctx context.Context, tenantID string, deliveryID uuid.UUID) (*models.ReportDownload, error) {
+	delivery, err := s.repo.GetDelivery(ctx, tenantID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, ErrReportDeliveryNotFound
+	}
+	if delivery.StoragePath == "" {
+		return nil, ErrReportFileUnavailable
+	}
+
+	presigned, err := s.documentClient.GetPresignedURL(ctx, &clients.PresignedURLRequest{
+		TenantID:  tenantID,
+		Bucket:    delivery.StorageBucket,
+		Path:      delivery.StoragePath,
+		Method:    "GET",
+		ExpiresIn: int(reportDownloadExpiry.Seconds()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report download URL: %w", err)
+	}
+	return &models.ReportDownload{
+		URL:       presigned.URL,
+		FileName:  delivery.FileName,
+		ExpiresAt: presigned.ExpiresAt,
+	}, nil
+}
+
+// RunNow renders and sends a schedule's report for the most recent period without changing its next run
+func (s *ReportScheduleService) RunNow(ctx context.Context, tenantID string, id uuid.UUID) (*models.ReportDelivery, error) {
+	schedule, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, schedule, time.Now(), true)
+}
+
+// RunDue sends every report that is due and returns how many were run. Each schedule is claimed
+// before it runs so that replicas don't send the same report twice.
+func (s *ReportScheduleService) RunDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	schedules, err := s.repo.ListDue(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	ran := 0
+	for i := range schedules {
+		schedule := &schedules[i]
+		claimed, err := s.repo.ClaimRun(ctx, schedule, schedule.NextRunAfter(now))
+		if err != nil {
+			log.Printf("WARNING: Failed to claim report schedule %s: %v", schedule.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		// Cover the period that ended at the scheduled time, even if the worker runs late
+		if _, err := s.run(ctx, schedule, schedule.NextRunAt, false); err != nil {
+			log.Printf("WARNING: Failed to record report delivery for schedule %s: %v", schedule.ID, err)
+		}
+		ran++
+	}
+	return ran, nil
+}
+
+// run renders a schedule's report, stores it in document-service and emails a download link to
+// each recipient. Rendering and delivery failures are recorded on the delivery rather than returned.
+func (s *ReportScheduleService) run(ctx context.Context, schedule *models.ReportSchedule, runAt time.Time, manual bool) (*models.ReportDelivery, error) {
+	from, to := schedule.PeriodEndingAt(runAt)
+	delivery := &models.ReportDelivery{
+		TenantID:    schedule.TenantID,
+		ScheduleID:  schedule.ID,
+		ReportType:  schedule.ReportType,
+		Format:      schedule.Format,
+		PeriodStart: from,
+		PeriodEnd:   to,
+		Recipients:  schedule.Recipients,
+		Status:      models.ReportDeliveryFailed,
+		Manual:      manual,
+	}
+
+	if err := s.deliver(ctx, schedule, delivery); err != nil {
+		delivery.Error = err.Error()
+	}
+
+	if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	if err := s.repo.RecordRun(ctx, schedule.ID, time.Now(), delivery.Status); err != nil {
+		log.Printf("WARNING: Failed to record run for report schedule %s: %v", schedule.ID, err)
+	}
+	return delivery, nil
+}
+
+func (s *ReportScheduleService) deliver(ctx context.Context, schedule *models.ReportSchedule, delivery *models.ReportDelivery) error {
+	table, err := s.buildReport(ctx, schedule, delivery.PeriodStart, delivery.PeriodEnd)
+	if err != nil {
+		return fmt.Errorf("failed to build report: %w", err)
+	}
+	delivery.RowCount = len(table.Rows)
+
+	data, contentType, err := renderReport(table, schedule.Format)
+	if err != nil {
+		return err
+	}
+
+	delivery.FileName = fmt.Sprintf("%s_%s.%s", schedule.ReportType, delivery.PeriodEnd.Format("2006-01-02"), schedule.Format)
+	path := fmt.Sprintf("%s/%s/%s/%s/%s", s.pathPrefix, schedule.TenantID, schedule.ID, time.Now().UTC().Format("20060102T150405"), delivery.FileName)
+	if _, err := s.documentClient.UploadDocument(ctx, &clients.DocumentUploadRequest{
+		TenantID:    schedule.TenantID,
+		Bucket:      s.bucket,
+		Path:        path,
+		Filename:    delivery.FileName,
+		ContentType: contentType,
+		Data:        data,
+		IsPublic:    false,
+		Tags: map[string]string{
+			"report_type": string(schedule.ReportType),
+			"schedule_id": schedule.ID.String(),
+		},
+		EntityType: "report",
+		EntityID:   schedule.ID.String(),
+		ProductID:  "marketplace",
+	}); err != nil {
+		return fmt.Errorf("failed to store report: %w", err)
+	}
+	delivery.FileSize = int64(len(data))
+	delivery.StorageBucket = s.bucket
+	delivery.StoragePath = path
+
+	presigned, err := s.documentClient.GetPresignedURL(ctx, &clients.PresignedURLRequest{
+		TenantID:  schedule.TenantID,
+		Bucket:    s.bucket,
+		Path:      path,
+		Method:    "GET",
+		ExpiresIn: int(reportLinkExpiry.Seconds()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get report download URL: %w", err)
+	}
+
+	sent := 0
+	var lastErr error
+	for _, recipient := range schedule.Recipients {
+		err := s.notificationClient.SendScheduledReport(ctx, &clients.ScheduledReportNotification{
+			TenantID:       schedule.TenantID,
+			RecipientEmail: recipient,
+			ReportName:     schedule.Name,
+			ReportType:     string(schedule.ReportType),
+			Period:         table.Period,
+			Format:         string(schedule.Format),
+			RowCount:       delivery.RowCount,
+			DownloadURL:    presigned.URL,
+			ExpiresAt:      presigned.ExpiresAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		sent++
+	}
+
+	switch {
+	case sent == len(schedule.Recipients):
+		delivery.Status = models.ReportDeliverySent
+		return nil
+	case sent > 0:
+		delivery.Status = models.ReportDeliveryPartiallySent
+	}
+	return fmt.Errorf("failed to email %d of %d recipients: %w", len(schedule.Recipients)-sent, len(schedule.Recipients), lastErr)
+}
+
+func (s *ReportScheduleService) buildReport(ctx context.Context, schedule *models.ReportSchedule, from, to time.Time) (*reportTable, error) {
+	switch schedule.ReportType {
+	case models.ReportTypeSalesSummary:
+		return s.buildSalesSummary(schedule, from, to)
+	case models.ReportTypeLowStock:
+		return s.buildLowStock(schedule)
+	case models.ReportTypeVendorPayouts:
+		return s.buildVendorPayouts(schedule, from, to)
+	default:
+		return nil, fmt.Errorf("unknown report type %q", schedule.ReportType)
+	}
+}
+
+// buildSalesSummary lists daily sales for the tenant, or for one vendor when the schedule is filtered
+func (s *ReportScheduleService) buildSalesSummary(schedule *models.ReportSchedule, from, to time.Time) (*reportTable, error) {
+	table := &reportTable{
+		Title:  schedule.Name,
+		Period: reportPeriod(from, to),
+	}
+
+	if schedule.VendorID != "" {
+		stats, err := s.vendorAnalyticsRepo.GetDailyStats(schedule.TenantID, schedule.VendorID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		table.Columns = []string{"Date", "Orders", "Cancelled", "Items Sold", "GMV", "Refunds"}
+		var gmv, refunds float64
+		var orders int64
+		for _, stat := range stats {
+			table.Rows = append(table.Rows, []string{
+				stat.StatDate.Format("2006-01-02"),
+				strconv.FormatInt(stat.OrderCount, 10),
+				strconv.FormatInt(stat.CancelledCount, 10),
+				strconv.FormatInt(stat.ItemsSold, 10),
+				formatAmount(stat.GMV),
+				formatAmount(stat.RefundAmount),
+			})
+			gmv += stat.GMV
+			refunds += stat.RefundAmount
+			orders += stat.OrderCount
+		}
+		table.Summary = []reportSummaryLine{
+			{Label: "Vendor", Value: schedule.VendorID},
+			{Label: "Orders", Value: strconv.FormatInt(orders, 10)},
+			{Label: "GMV", Value: formatAmount(gmv)},
+			{Label: "Refunds", Value: formatAmount(refunds)},
+		}
+		return table, nil
+	}
+
+	overview, err := s.tenantAnalytics.GetOverview(schedule.TenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	table.Columns = []string{"Date", "Orders", "Gross Sales", "Avg Order Value", "Refunds", "New Customers"}
+	for _, point := range overview.Daily {
+		table.Rows = append(table.Rows, []string{
+			point.Date,
+			strconv.FormatInt(point.OrderCount, 10),
+			formatAmount(point.GrossSales),
+			formatAmount(point.AverageOrderValue),
+			formatAmount(point.RefundAmount),
+			strconv.FormatInt(point.NewCustomers, 10),
+		})
+	}
+	summary := overview.Summary
+	table.Summary = []reportSummaryLine{
+		{Label: "Orders", Value: strconv.FormatInt(summary.OrderCount, 10)},
+		{Label: "Gross sales", Value: formatAmount(summary.GrossSales)},
+		{Label: "Net sales", Value: formatAmount(summary.NetSales)},
+		{Label: "Average order value", Value: formatAmount(summary.AverageOrderValue)},
+		{Label: "Refunds", Value: formatAmount(summary.RefundAmount)},
+		{Label: "New customers", Value: strconv.FormatInt(summary.NewCustomers, 10)},
+	}
+	return table, nil
+}
+
+// buildLowStock lists stock at or below its reorder point as of now, most urgent first
+func (s *ReportScheduleService) buildLowStock(schedule *models.ReportSchedule) (*reportTable, error) {
+	levels, err := s.inventoryClient.ListLowStock(schedule.WarehouseID, schedule.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.VendorID != "" {
+		filtered := levels[:0]
+		for _, level := range levels {
+			if level.VendorID == schedule.VendorID {
+				filtered = append(filtered, level)
+			}
+		}
+		levels = filtered
+	}
+	sort.SliceStable(levels, func(i, j int) bool {
+		return levels[i].QuantityAvailable-levels[i].ReorderPoint < levels[j].QuantityAvailable-levels[j].ReorderPoint
+	})
+
+	// Resolve product names once per product rather than once per warehouse
+	products := make(map[string]*clients.Product)
+	table := &reportTable{
+		Title:   schedule.Name,
+		Period:  "As of " + time.Now().UTC().Format("2006-01-02 15:04") + " UTC",
+		Columns: []string{"Product", "SKU", "Warehouse", "Available", "Reserved", "Reorder Point", "Reorder Qty"},
+	}
+	for _, level := range levels {
+		product, ok := products[level.ProductID]
+		if !ok {
+			product, err = s.productsClient.GetProduct(level.ProductID, schedule.TenantID)
+			if err != nil {
+				product = nil
+			}
+			products[level.ProductID] = product
+		}
+		name, sku := level.ProductID, ""
+		if product != nil {
+			if product.Name != "" {
+				name = product.Name
+			}
+			sku = product.SKU
+		}
+		table.Rows = append(table.Rows, []string{
+			name,
+			sku,
+			level.WarehouseID,
+			strconv.Itoa(level.QuantityAvailable),
+			strconv.Itoa(level.QuantityReserved),
+			strconv.Itoa(level.ReorderPoint),
+			strconv.Itoa(level.ReorderQuantity),
+		})
+	}
+	table.Summary = []reportSummaryLine{
+		{Label: "Items below reorder point", Value: strconv.Itoa(len(table.Rows))},
+	}
+	return table, nil
+}
+
+// buildVendorPayouts lists each vendor's GMV, refunds and net payable for the period
+func (s *ReportScheduleService) buildVendorPayouts(schedule *models.ReportSchedule, from, to time.Time) (*reportTable, error) {
+	totals, err := s.vendorAnalyticsRepo.GetPayoutTotals(schedule.TenantID, schedule.VendorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &reportTable{
+		Title:   schedule.Name,
+		Period:  reportPeriod(from, to),
+		Columns: []string{"Vendor", "Orders", "GMV", "Refunds", "Net Payable"},
+	}
+	var gmv, refunds float64
+	for _, total := range totals {
+		net := roundCurrency(total.GMV - total.RefundAmount)
+		table.Rows = append(table.Rows, []string{
+			total.VendorID,
+			strconv.FormatInt(total.OrderCount, 10),
+			formatAmount(total.GMV),
+			formatAmount(total.RefundAmount),
+			formatAmount(net),
+		})
+		gmv += total.GMV
+		refunds += total.RefundAmount
+	}
+	table.Summary = []reportSummaryLine{
+		{Label: "Vendors", Value: strconv.Itoa(len(totals))},
+		{Label: "GMV", Value: formatAmount(gmv)},
+		{Label: "Refunds", Value: formatAmount(refunds)},
+		{Label: "Net payable", Value: formatAmount(roundCurrency(gmv - refunds))},
+	}
+	return table, nil
+}
+
+func applyReportScheduleRequest(schedule *models.ReportSchedule, req *models.ReportScheduleRequest) {
+	schedule.Name = req.Name
+	schedule.ReportType = req.ReportType
+	schedule.Frequency = req.Frequency
+	schedule.Format = req.Format
+	if schedule.Format == "" {
+		schedule.Format = models.ReportFormatCSV
+	}
+	schedule.Recipients = pq.StringArray(req.Recipients)
+	schedule.VendorID = req.VendorID
+	schedule.WarehouseID = req.WarehouseID
+	if req.HourUTC != nil {
+		schedule.HourUTC = *req.HourUTC
+	}
+	if req.DayOfWeek != nil {
+		schedule.DayOfWeek = *req.DayOfWeek
+	}
+	if req.DayOfMonth != nil {
+		schedule.DayOfMonth = *req.DayOfMonth
+	}
+	if req.IsActive != nil {
+		schedule.IsActive = *req.IsActive
+	}
+}
+
+func reportPeriod(from, to time.Time) string {
+	if from.Equal(to) {
+		return from.Format("2 Jan 2006")
+	}
+	return from.Format("2 Jan 2006") + " - " + to.Format("2 Jan 2006")
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
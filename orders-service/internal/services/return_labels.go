@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"orders-service/internal/clients"
+	"orders-service/internal/models"
+)
+
+var (
+	// ErrReturnNotFound is returned when a return doesn't exist or belongs to another tenant or customer
+	ErrReturnNotFound = errors.New("return not found")
+	// ErrReturnNotLabelable is returned when a label is requested for a return that isn't approved
+	ErrReturnNotLabelable = errors.New("return labels can only be generated for approved returns")
+	// ErrReturnLabelExists is returned when the return already has a shipping label
+	ErrReturnLabelExists = errors.New("return already has a shipping label")
+	// ErrReturnLabelFailed is returned when shipping-service couldn't book the return parcel
+	ErrReturnLabelFailed = errors.New("return label could not be generated")
+)
+
+// GenerateReturnLabel books the return parcel from the customer's shipping address to the
+// tenant's warehouse through shipping-service and stores the label and tracking on the return
+func (s *ReturnService) GenerateReturnLabel(tenantID string, returnID uuid.UUID, userID *uuid.UUID) (*models.Return, error) {
+	ret, err := s.returnRepo.GetReturnByID(returnID)
+	if err != nil || ret.TenantID != tenantID {
+		return nil, ErrReturnNotFound
+	}
+	if ret.Status != models.ReturnStatusApproved {
+		return nil, ErrReturnNotLabelable
+	}
+	if ret.ReturnShippingLabelURL != "" {
+		return nil, ErrReturnLabelExists
+	}
+	if s.shippingClient == nil {
+		return nil, fmt.Errorf("%w: shipping-service is not configured", ErrReturnLabelFailed)
+	}
+
+	order, err := s.orderRepo.GetByID(ret.OrderID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("order not found: %w", err)
+	}
+	if order.Shipping == nil || order.Customer == nil {
+		return nil, fmt.Errorf("%w: order has no shipping address", ErrReturnLabelFailed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	settings, err := s.shippingClient.GetShippingSettings(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReturnLabelFailed, err)
+	}
+	if settings == nil || settings.Warehouse == nil {
+		return nil, fmt.Errorf("%w: no warehouse address configured", ErrReturnLabelFailed)
+	}
+
+	weight, length, width, height := returnParcelMetrics(order, ret)
+	label, err := s.shippingClient.CreateReturnLabel(ctx, &clients.ReturnLabelRequest{
+		TenantID:    tenantID,
+		OrderID:     order.ID.String(),
+		OrderNumber: order.OrderNumber,
+		ReturnID:    ret.ID.String(),
+		RMANumber:   ret.RMANumber,
+		CustomerAddress: &clients.ShipmentAddress{
+			Name:       strings.TrimSpace(order.Customer.FirstName + " " + order.Customer.LastName),
+			Phone:      order.Customer.Phone,
+			Email:      order.Customer.Email,
+			Street:     order.Shipping.Street,
+			City:       order.Shipping.City,
+			State:      order.Shipping.State,
+			PostalCode: order.Shipping.PostalCode,
+			Country:    order.Shipping.Country,
+		},
+		ReturnAddress: settings.Warehouse,
+		Weight:        weight,
+		Length:        length,
+		Width:         width,
+		Height:        height,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReturnLabelFailed, err)
+	}
+
+	if err := s.returnRepo.SetReturnLabel(ret.ID, label.ShipmentID, label.Carrier, label.TrackingNumber, label.LabelURL, userID); err != nil {
+		return nil, err
+	}
+	return s.returnRepo.GetReturnByID(ret.ID)
+}
+
+// returnParcelMetrics sizes the return parcel from the order's package, scaling the weight by
+// the share of the order's items being returned
+func returnParcelMetrics(order *models.Order, ret *models.Return) (float64, float64, float64, float64) {
+	weight, length, width, height := 0.5, 20.0, 15.0, 10.0
+	if order.Shipping != nil {
+		if order.Shipping.PackageLength > 0 {
+			length = order.Shipping.PackageLength
+		}
+		if order.Shipping.PackageWidth > 0 {
+			width = order.Shipping.PackageWidth
+		}
+		if order.Shipping.PackageHeight > 0 {
+			height = order.Shipping.PackageHeight
+		}
+
+		ordered, returned := 0, 0
+		for _, item := range order.Items {
+			ordered += item.Quantity
+		}
+		for _, item := range ret.Items {
+			returned += item.Quantity
+		}
+		if order.Shipping.PackageWeight > 0 && ordered > 0 {
+			if share := order.Shipping.PackageWeight * float64(returned) / float64(ordered); share > 0 {
+				weight = share
+			}
+		}
+	}
+	return weight, length, width, height
+}
+
+// CustomerReturnStatus is what a customer sees of their return on the storefront
+type CustomerReturnStatus struct {
+	ID           uuid.UUID              `json:"id"`
+	RMANumber    string                 `json:"rmaNumber"`
+	OrderID      uuid.UUID              `json:"orderId"`
+	Status       models.ReturnStatus    `json:"status"`
+	ReturnType   models.ReturnType      `json:"returnType"`
+	RefundAmount float64                `json:"refundAmount"`
+	Items        []CustomerReturnItem   `json:"items"`
+	Label        *CustomerReturnLabel   `json:"label,omitempty"`
+	Timeline     []CustomerReturnUpdate `json:"timeline"`
+	CreatedAt    time.Time              `json:"createdAt"`
+}
+
+// CustomerReturnItem is an item on a customer's return
+type CustomerReturnItem struct {
+	ProductName string `json:"productName"`
+	SKU         string `json:"sku"`
+	Quantity    int    `json:"quantity"`
+}
+
+// CustomerReturnLabel is the shipping label the customer prints for their return parcel
+type CustomerReturnLabel struct {
+	LabelURL       string `json:"labelUrl"`
+	Carrier        string `json:"carrier,omitempty"`
+	TrackingNumber string `json:"trackingNumber,omitempty"`
+}
+
+// CustomerReturnUpdate is a status change on a customer's return
+type CustomerReturnUpdate struct {
+	Status    models.ReturnStatus `json:"status"`
+	Message   string              `json:"message"`
+	CreatedAt time.Time           `json:"createdAt"`
+}
+
+// GetCustomerReturn returns the status of a return on one of the customer's orders, with its
+// shipping label once one has been generated. Staff notes are left out.
+func (s *ReturnService) GetCustomerReturn(tenantID string, returnID uuid.UUID, customerEmail string) (*CustomerReturnStatus, error) {
+	ret, err := s.returnRepo.GetReturnByID(returnID)
+	if err != nil || ret.TenantID != tenantID {
+		return nil, ErrReturnNotFound
+	}
+	if ret.Order == nil || ret.Order.Customer == nil || !strings.EqualFold(ret.Order.Customer.Email, customerEmail) {
+		return nil, ErrReturnNotFound
+	}
+
+	status := &CustomerReturnStatus{
+		ID:           ret.ID,
+		RMANumber:    ret.RMANumber,
+		OrderID:      ret.OrderID,
+		Status:       ret.Status,
+		ReturnType:   ret.ReturnType,
+		RefundAmount: ret.RefundAmount,
+		Items:        make([]CustomerReturnItem, 0, len(ret.Items)),
+		Timeline:     make([]CustomerReturnUpdate, 0, len(ret.Timeline)),
+		CreatedAt:    ret.CreatedAt,
+	}
+	for _, item := range ret.Items {
+		status.Items = append(status.Items, CustomerReturnItem{
+			ProductName: item.ProductName,
+			SKU:         item.SKU,
+			Quantity:    item.Quantity,
+		})
+	}
+	if ret.ReturnShippingLabelURL != "" {
+		status.Label = &CustomerReturnLabel{
+			LabelURL:       ret.ReturnShippingLabelURL,
+			Carrier:        ret.ReturnCarrier,
+			TrackingNumber: ret.ReturnTrackingNumber,
+		}
+	}
+	for _, entry := range ret.Timeline {
+		status.Timeline = append(status.Timeline, CustomerReturnUpdate{
+			Status:    entry.Status,
+			Message:   entry.Message,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+	return status, nil
+}
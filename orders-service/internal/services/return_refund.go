@@ -0,0 +1,237 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"orders-service/internal/clients"
+	"orders-service/internal/models"
+)
+
+// processRefund pays out the breakdown's refund total across the tenders the order was paid
+// with, in proportion to what each has left, records every line on the breakdown and returns
+// the lines paid out by this attempt. Lines that succeeded on an earlier attempt are kept and
+// not paid again. If any line fails, the attempt is saved to the return's timeline and an
+// error is returned so the return stays open for a retry.
+func (s *ReturnService) processRefund(ret *models.Return, breakdown *models.RefundBreakdown, method models.RefundMethod, processedBy *uuid.UUID) ([]models.RefundTenderLine, error) {
+	if breakdown.RefundTotal <= 0 {
+		return nil, fmt.Errorf("refund amount must be greater than 0")
+	}
+
+	overrides, err := refundTendersFor(method)
+	if err != nil {
+		return nil, err
+	}
+	if s.paymentClient == nil {
+		return nil, fmt.Errorf("payment client not configured")
+	}
+
+	// Keep what an earlier attempt already paid out
+	refunded := 0.0
+	if ret.RefundBreakdown != nil {
+		for _, line := range ret.RefundBreakdown.Tenders {
+			if line.Settled() {
+				breakdown.Tenders = append(breakdown.Tenders, line)
+				refunded += line.Amount
+			}
+		}
+	}
+	remaining := roundCurrency(breakdown.RefundTotal - refunded)
+	if remaining <= 0 {
+		return nil, nil
+	}
+
+	tenders, err := s.refundableTenders(ret)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := allocateRefund(remaining, tenders)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := clients.RefundPlanRequest{
+		OrderID:  ret.OrderID.String(),
+		ReturnID: ret.ID.String(),
+		Reason:   fmt.Sprintf("Return refund for RMA %s", ret.RMANumber),
+		Notes:    fmt.Sprintf("Return ID: %s, Reason: %s", ret.ID.String(), ret.Reason),
+		Lines:    make([]clients.RefundPlanLine, len(lines)),
+	}
+	for i, line := range lines {
+		plan.Lines[i] = clients.RefundPlanLine{
+			Tender:       string(line.Tender),
+			PaymentID:    line.PaymentID,
+			GiftCardCode: line.GiftCardCode,
+			Amount:       line.Amount,
+		}
+		if line.Tender == models.RefundTenderPayment {
+			plan.Lines[i].Tenders = overrides
+		}
+	}
+
+	result, err := s.paymentClient.ExecuteRefundPlan(plan, ret.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute refund plan: %w", err)
+	}
+
+	failed := 0
+	for i, line := range lines {
+		outcome := result.Lines[i]
+		line.Status = outcome.Status
+		line.RefundID = outcome.RefundID
+		line.Reference = outcome.Reference
+		line.Error = outcome.Error
+		if !line.Settled() {
+			line.Status = models.RefundLineFailed
+			failed++
+		}
+		lines[i] = line
+	}
+	breakdown.Tenders = append(breakdown.Tenders, lines...)
+
+	if failed > 0 {
+		timeline := refundTimeline(ret, ret.Status, breakdown, lines, processedBy)
+		if err := s.returnRepo.RecordRefundAttempt(ret.ID, breakdown, timeline); err != nil {
+			return nil, fmt.Errorf("failed to record refund attempt: %w", err)
+		}
+		return nil, fmt.Errorf("%d of %d refund lines failed", failed, len(lines))
+	}
+	return lines, nil
+}
+
+// refundTendersFor maps a return's refund method to the payment-service tenders a PAYMENT
+// line may use; nil leaves it to the tenant's refund fallback chain. Gift card lines always
+// go back to the card.
+func refundTendersFor(method models.RefundMethod) ([]string, error) {
+	switch method {
+	case models.RefundMethodOriginal:
+		return nil, nil
+	case models.RefundMethodStoreCredit:
+		return []string{"STORE_CREDIT"}, nil
+	case models.RefundMethodBankTransfer:
+		return []string{"BANK_TRANSFER"}, nil
+	}
+	return nil, fmt.Errorf("invalid refund method: %s", method)
+}
+
+// refundableTenders lists the order's successful payments and redeemed gift cards, each with
+// the amount not yet refunded by this or any other return of the order
+func (s *ReturnService) refundableTenders(ret *models.Return) ([]models.RefundTenderLine, error) {
+	payments, err := s.paymentClient.GetPaymentsByOrder(ret.OrderID, ret.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments for order: %w", err)
+	}
+	order, err := s.orderRepo.GetByID(ret.OrderID, ret.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("order not found: %w", err)
+	}
+
+	var tenders []models.RefundTenderLine
+	for _, payment := range payments {
+		switch payment.Status {
+		case "SUCCEEDED", "COMPLETED", "CAPTURED":
+			tenders = append(tenders, models.RefundTenderLine{
+				Tender:    models.RefundTenderPayment,
+				PaymentID: payment.ID,
+				Amount:    payment.Amount,
+			})
+		}
+	}
+	for _, discount := range order.Discounts {
+		if discount.DiscountType == models.DiscountTypeGiftCard && discount.CouponCode != "" {
+			tenders = append(tenders, models.RefundTenderLine{
+				Tender:       models.RefundTenderGiftCard,
+				GiftCardCode: discount.CouponCode,
+				Amount:       discount.Amount,
+			})
+		}
+	}
+	if len(tenders) == 0 {
+		return nil, fmt.Errorf("no completed payment found for order")
+	}
+
+	returns, err := s.returnRepo.GetReturnsByOrderID(ret.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get returns for order: %w", err)
+	}
+	for _, other := range returns {
+		if other.RefundBreakdown == nil {
+			continue
+		}
+		for _, line := range other.RefundBreakdown.Tenders {
+			if !line.Settled() {
+				continue
+			}
+			for i := range tenders {
+				if tenders[i].SameTender(line) {
+					tenders[i].Amount = roundCurrency(tenders[i].Amount - line.Amount)
+				}
+			}
+		}
+	}
+	return tenders, nil
+}
+
+// allocateRefund splits total across the tenders in proportion to what each has left. Any
+// rounding remainder goes to the tender with the most left.
+func allocateRefund(total float64, tenders []models.RefundTenderLine) ([]models.RefundTenderLine, error) {
+	available := 0.0
+	for _, t := range tenders {
+		if t.Amount > 0 {
+			available += t.Amount
+		}
+	}
+	available = roundCurrency(available)
+	if total > available {
+		return nil, fmt.Errorf("refund of %.2f exceeds the %.2f left to refund on the order", total, available)
+	}
+
+	lines := make([]models.RefundTenderLine, 0, len(tenders))
+	allocated, largest, largestLeft := 0.0, -1, 0.0
+	for _, t := range tenders {
+		if t.Amount <= 0 {
+			continue
+		}
+		if t.Amount > largestLeft {
+			largest, largestLeft = len(lines), t.Amount
+		}
+		t.Amount = roundCurrency(total * t.Amount / available)
+		allocated += t.Amount
+		lines = append(lines, t)
+	}
+	if largest >= 0 {
+		lines[largest].Amount = roundCurrency(lines[largest].Amount + total - allocated)
+	}
+
+	kept := lines[:0]
+	for _, line := range lines {
+		if line.Amount > 0 {
+			kept = append(kept, line)
+		}
+	}
+	return kept, nil
+}
+
+// refundTimeline describes a refund attempt: the fee breakdown and one entry per tender line
+func refundTimeline(ret *models.Return, status models.ReturnStatus, breakdown *models.RefundBreakdown, lines []models.RefundTenderLine, processedBy *uuid.UUID) []models.ReturnTimeline {
+	timeline := []models.ReturnTimeline{
+		ret.CreateTimelineEntry(status, fmt.Sprintf(
+			"Refund breakdown: items $%.2f, restocking fee $%.2f, return shipping $%.2f, refund $%.2f",
+			breakdown.ItemsTotal, breakdown.RestockingFee, breakdown.ReturnShippingDeduction, breakdown.RefundTotal,
+		), processedBy),
+	}
+	for _, line := range lines {
+		var tender string
+		switch line.Tender {
+		case models.RefundTenderGiftCard:
+			tender = "gift card " + models.MaskedGiftCardCode(line.GiftCardCode)
+		default:
+			tender = "payment " + line.PaymentID
+		}
+		entry := ret.CreateTimelineEntry(status, fmt.Sprintf("Refund of $%.2f to %s: %s", line.Amount, tender, strings.ToLower(line.Status)), processedBy)
+		entry.Notes = line.Error
+		timeline = append(timeline, entry)
+	}
+	return timeline
+}
@@ -1,26 +1,41 @@
 package services
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"image/png"
+	"strings"
 	"time"
 
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
 	"github.com/google/uuid"
 	"orders-service/internal/clients"
 	"orders-service/internal/models"
 	"orders-service/internal/repository"
 )
 
+var (
+	// ErrIntakeCodeNotFound is returned when a scanned code doesn't belong to any of the tenant's returns
+	ErrIntakeCodeNotFound = errors.New("intake code not found")
+	// ErrReturnNotReceivable is returned when a scanned return isn't awaiting its items
+	ErrReturnNotReceivable = errors.New("return is not awaiting items")
+)
+
 type ReturnService struct {
-	returnRepo    *repository.ReturnRepository
-	orderRepo     repository.OrderRepository
-	paymentClient clients.PaymentClient
+	returnRepo     *repository.ReturnRepository
+	orderRepo      repository.OrderRepository
+	paymentClient  clients.PaymentClient
+	shippingClient clients.ShippingClient
 }
 
-func NewReturnService(returnRepo *repository.ReturnRepository, orderRepo repository.OrderRepository, paymentClient clients.PaymentClient) *ReturnService {
+func NewReturnService(returnRepo *repository.ReturnRepository, orderRepo repository.OrderRepository, paymentClient clients.PaymentClient, shippingClient clients.ShippingClient) *ReturnService {
 	return &ReturnService{
-		returnRepo:    returnRepo,
-		orderRepo:     orderRepo,
-		paymentClient: paymentClient,
+		returnRepo:     returnRepo,
+		orderRepo:      orderRepo,
+		paymentClient:  paymentClient,
+		shippingClient: shippingClient,
 	}
 }
 
@@ -38,15 +53,7 @@ func (s *ReturnService) CreateReturnRequest(req *CreateReturnRequest) (*models.R
 	}
 
 	// Get return policy
-	policy, err := s.returnRepo.GetReturnPolicy(req.TenantID)
-	if err != nil {
-		// Use default policy if not found
-		policy = &models.ReturnPolicy{
-			ReturnWindowDays: 30,
-			AllowExchange:    true,
-			AllowStoreCredit: true,
-		}
-	}
+	policy := s.returnPolicy(req.TenantID)
 
 	// Check if within return window
 	if !s.isWithinReturnWindow(order.CreatedAt, policy.ReturnWindowDays) {
@@ -105,24 +112,18 @@ func (s *ReturnService) CreateReturnRequest(req *CreateReturnRequest) (*models.R
 		ret.Items = append(ret.Items, returnItem)
 	}
 
-	// Calculate refund amount
-	totalRefund := 0.0
-	for _, item := range ret.Items {
-		totalRefund += item.RefundAmount
-	}
-
-	// Apply restocking fee if applicable
-	if policy.RestockingFeePercent > 0 {
-		ret.RestockingFee = totalRefund * (policy.RestockingFeePercent / 100.0)
-	}
-
-	ret.RefundAmount = totalRefund - ret.RestockingFee
+	// Estimate the refund; it is worked out again from the inspected items on completion
+	breakdown := ret.CalculateRefundBreakdown(policy)
+	ret.RestockingFee = breakdown.RestockingFee
+	ret.RefundAmount = breakdown.RefundTotal
 
 	// Auto-approve if enabled in policy
 	if policy.AutoApproveReturns {
 		ret.Status = models.ReturnStatusApproved
 		now := time.Now()
 		ret.ApprovedAt = &now
+		intakeCode := models.NewReturnIntakeCode()
+		ret.IntakeCode = &intakeCode
 	}
 
 	// Create return in database
@@ -167,7 +168,7 @@ func (s *ReturnService) ApproveReturn(returnID, approvedBy uuid.UUID, notes stri
 	}
 
 	// TODO: Send notification to customer
-	// TODO: Generate return shipping label
+	// The return shipping label is generated separately (GenerateReturnLabel) when staff ask for one
 
 	return nil
 }
@@ -232,6 +233,89 @@ func (s *ReturnService) MarkReturnReceived(returnID uuid.UUID, userID *uuid.UUID
 	return s.returnRepo.UpdateReturnStatus(returnID, models.ReturnStatusReceived, "Items received at warehouse", userID)
 }
 
+// ScanReturnIntake receives a return at the warehouse from the code in its QR code.
+// Scanning a return that was already received returns it again rather than failing, so a
+// second scan of the same parcel is harmless. The result carries an inspection draft
+// pre-filled from the customer's return reasons.
+func (s *ReturnService) ScanReturnIntake(tenantID, code string, userID *uuid.UUID) (*ReturnIntakeResult, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	ret, err := s.returnRepo.GetReturnByIntakeCode(tenantID, code)
+	if err != nil {
+		return nil, ErrIntakeCodeNotFound
+	}
+
+	result := &ReturnIntakeResult{RMANumber: ret.RMANumber}
+	if ret.Order != nil {
+		result.OrderNumber = ret.Order.OrderNumber
+	}
+
+	switch {
+	case ret.CanIntake():
+		received, err := s.returnRepo.ReceiveReturn(ret.ID, "Items received at warehouse (drop-off scan)", userID)
+		if err != nil {
+			return nil, err
+		}
+		result.AlreadyReceived = !received
+	case ret.Status == models.ReturnStatusReceived || ret.Status == models.ReturnStatusInspecting:
+		result.AlreadyReceived = true
+	default:
+		return nil, fmt.Errorf("%w (current status: %s)", ErrReturnNotReceivable, ret.Status)
+	}
+
+	ret, err = s.returnRepo.GetReturnByID(ret.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Return = ret
+	result.Inspection = draftInspection(ret)
+	return result, nil
+}
+
+// RenderIntakeQRCode renders a return's intake code as a square PNG QR code
+func (s *ReturnService) RenderIntakeQRCode(ret *models.Return, size int) ([]byte, error) {
+	if ret.IntakeCode == nil {
+		return nil, ErrReturnNotReceivable
+	}
+
+	code, err := qr.Encode(*ret.IntakeCode, qr.M, qr.AlphaNumeric)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	code, err = barcode.Scale(code, size, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scale QR code: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, code); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// draftInspection pre-fills item conditions from the return reasons: items reported
+// defective are expected damaged and not resellable, everything else as new.
+func draftInspection(ret *models.Return) ReturnInspectionDraft {
+	draft := ReturnInspectionDraft{
+		ItemConditions: make(map[string]ItemCondition, len(ret.Items)),
+	}
+	for _, item := range ret.Items {
+		reason := item.Reason
+		if reason == "" {
+			reason = ret.Reason
+		}
+		condition := ItemCondition{Condition: "NEW", CanResell: true}
+		if reason == models.ReturnReasonDefective {
+			condition = ItemCondition{Condition: "DAMAGED", IsDefective: true}
+		}
+		draft.ItemConditions[item.ID.String()] = condition
+	}
+	if ret.CustomerNotes != "" {
+		draft.InspectionNotes = "Customer notes: " + ret.CustomerNotes
+	}
+	return draft
+}
+
 // InspectReturn inspects returned items
 func (s *ReturnService) InspectReturn(returnID, inspectedBy uuid.UUID, inspectionNotes string, itemConditions map[uuid.UUID]ItemCondition) error {
 	ret, err := s.returnRepo.GetReturnByID(returnID)
@@ -280,16 +364,18 @@ func (s *ReturnService) CompleteReturn(returnID, processedBy uuid.UUID, refundMe
 		return fmt.Errorf("return cannot be completed (current status: %s)", ret.Status)
 	}
 
-	// Calculate final refund amount
-	refundAmount := ret.CalculateRefundAmount()
+	// Calculate final refund amount, with fees, from the inspected items
+	breakdown := ret.CalculateRefundBreakdown(s.returnPolicy(ret.TenantID))
 
-	// Process refund
-	if err := s.processRefund(ret, refundAmount, refundMethod); err != nil {
+	// Split the refund across the order's payments and gift cards
+	lines, err := s.processRefund(ret, breakdown, refundMethod, &processedBy)
+	if err != nil {
 		return fmt.Errorf("failed to process refund: %w", err)
 	}
 
 	// Complete return in database
-	if err := s.returnRepo.CompleteReturn(returnID, processedBy, refundAmount, refundMethod); err != nil {
+	timeline := refundTimeline(ret, models.ReturnStatusCompleted, breakdown, lines, &processedBy)
+	if err := s.returnRepo.CompleteReturn(returnID, processedBy, refundMethod, breakdown, timeline); err != nil {
 		return err
 	}
 
@@ -364,74 +450,18 @@ func (s *ReturnService) isWithinReturnWindow(orderDate time.Time, windowDays int
 	return time.Now().Before(deadline)
 }
 
-func (s *ReturnService) processRefund(ret *models.Return, amount float64, method models.RefundMethod) error {
-	if amount <= 0 {
-		return fmt.Errorf("refund amount must be greater than 0")
-	}
-
-	// Validate refund method
-	switch method {
-	case models.RefundMethodOriginal:
-		// Process refund to original payment method via payment-service
-		if s.paymentClient == nil {
-			return fmt.Errorf("payment client not configured")
-		}
-
-		// Get payments for this order
-		payments, err := s.paymentClient.GetPaymentsByOrder(ret.OrderID, ret.TenantID)
-		if err != nil {
-			return fmt.Errorf("failed to get payments for order: %w", err)
-		}
-
-		// Find the successful payment to refund
-		var paymentToRefund *clients.Payment
-		for i := range payments {
-			if payments[i].Status == "COMPLETED" || payments[i].Status == "CAPTURED" {
-				paymentToRefund = &payments[i]
-				break
-			}
-		}
-
-		if paymentToRefund == nil {
-			return fmt.Errorf("no completed payment found for order")
-		}
-
-		// Create refund request
-		refundReq := clients.CreateRefundRequest{
-			Amount: amount,
-			Reason: fmt.Sprintf("Return refund for RMA %s", ret.RMANumber),
-			Notes:  fmt.Sprintf("Return ID: %s, Reason: %s", ret.ID.String(), ret.Reason),
-		}
-
-		// Parse payment ID
-		paymentID, err := uuid.Parse(paymentToRefund.ID)
-		if err != nil {
-			return fmt.Errorf("invalid payment ID: %w", err)
-		}
-
-		// Call payment service to create refund
-		refundResp, err := s.paymentClient.CreateRefund(paymentID, refundReq, ret.TenantID)
-		if err != nil {
-			return fmt.Errorf("failed to process refund: %w", err)
+// returnPolicy returns the tenant's return policy, or the default one if they haven't set one
+func (s *ReturnService) returnPolicy(tenantID string) *models.ReturnPolicy {
+	policy, err := s.returnRepo.GetReturnPolicy(tenantID)
+	if err != nil {
+		return &models.ReturnPolicy{
+			ReturnWindowDays:          30,
+			AllowExchange:             true,
+			AllowStoreCredit:          true,
+			WaiveFeesForMerchantFault: true,
 		}
-
-		// Log successful refund
-		fmt.Printf("Refund processed successfully: ID=%s, Amount=%.2f, Status=%s\n",
-			refundResp.ID, refundResp.Amount, refundResp.Status)
-
-	case models.RefundMethodStoreCredit:
-		// Store credit is not yet implemented
-		return fmt.Errorf("store credit refunds are not yet implemented")
-
-	case models.RefundMethodBankTransfer:
-		// Bank transfer is not yet implemented
-		return fmt.Errorf("bank transfer refunds are not yet implemented")
-
-	default:
-		return fmt.Errorf("invalid refund method: %s", method)
 	}
-
-	return nil
+	return policy
 }
 
 // DTOs
@@ -457,3 +487,19 @@ type ItemCondition struct {
 	IsDefective bool   `json:"isDefective"`
 	CanResell   bool   `json:"canResell"`
 }
+
+// ReturnIntakeResult is the outcome of scanning a return's intake code
+type ReturnIntakeResult struct {
+	Return          *models.Return        `json:"return"`
+	RMANumber       string                `json:"rmaNumber"`
+	OrderNumber     string                `json:"orderNumber"`
+	AlreadyReceived bool                  `json:"alreadyReceived"`
+	Inspection      ReturnInspectionDraft `json:"inspection"`
+}
+
+// ReturnInspectionDraft has the same shape as the inspect request, so it can be
+// reviewed and submitted as-is
+type ReturnInspectionDraft struct {
+	InspectionNotes string                   `json:"inspectionNotes"`
+	ItemConditions  map[string]ItemCondition `json:"itemConditions"`
+}
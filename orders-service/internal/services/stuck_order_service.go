@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"orders-service/internal/clients"
+	"orders-service/internal/events"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+// maxStuckOrderEscalationAttempts caps ticket creation retries for an alert; the alert stays
+// visible on the stuck orders endpoint either way
+const maxStuckOrderEscalationAttempts = 5
+
+// StuckOrderScanResult summarises one watchdog pass
+type StuckOrderScanResult struct {
+	Detected  int
+	Escalated int
+	Resolved  int64
+}
+
+// StuckOrderService is the dead man's switch for orders: it flags orders stuck in a state past
+// their threshold, raises order.stuck alerts and opens tickets for ops in tickets-service
+type StuckOrderService struct {
+	repo          *repository.StuckOrderRepository
+	orderRepo     repository.OrderRepository
+	publisher     *events.Publisher
+	ticketsClient *clients.TicketsClient
+	thresholds    map[models.StuckOrderReason]time.Duration
+	logger        *logrus.Logger
+}
+
+// NewStuckOrderService creates a new stuck order service
+func NewStuckOrderService(repo *repository.StuckOrderRepository, orderRepo repository.OrderRepository, publisher *events.Publisher, ticketsClient *clients.TicketsClient, paidNotFulfilled, shippedNotDelivered time.Duration, logger *logrus.Logger) *StuckOrderService {
+	return &StuckOrderService{
+		repo:          repo,
+		orderRepo:     orderRepo,
+		publisher:     publisher,
+		ticketsClient: ticketsClient,
+		thresholds: map[models.StuckOrderReason]time.Duration{
+			models.StuckOrderPaidNotFulfilled:    paidNotFulfilled,
+			models.StuckOrderShippedNotDelivered: shippedNotDelivered,
+		},
+		logger: logger,
+	}
+}
+
+// Scan resolves alerts for orders that have moved on, flags newly stuck orders and escalates
+// alerts that don't have a ticket yet
+func (s *StuckOrderService) Scan(ctx context.Context, now time.Time, batchSize int) (*StuckOrderScanResult, error) {
+	result := &StuckOrderScanResult{}
+
+	resolved, err := s.repo.ResolveRecovered(ctx, now)
+	if err != nil {
+		return result, err
+	}
+	result.Resolved = resolved
+
+	paid, err := s.repo.ListPaidNotFulfilled(ctx, now.Add(-s.thresholds[models.StuckOrderPaidNotFulfilled]), batchSize)
+	if err != nil {
+		return result, err
+	}
+	shipped, err := s.repo.ListShippedNotDelivered(ctx, now.Add(-s.thresholds[models.StuckOrderShippedNotDelivered]), batchSize)
+	if err != nil {
+		return result, err
+	}
+
+	for _, candidate := range paid {
+		if s.flag(ctx, candidate, models.StuckOrderPaidNotFulfilled, now) {
+			result.Detected++
+		}
+	}
+	for _, candidate := range shipped {
+		if s.flag(ctx, candidate, models.StuckOrderShippedNotDelivered, now) {
+			result.Detected++
+		}
+	}
+
+	pending, err := s.repo.ListPendingEscalations(ctx, maxStuckOrderEscalationAttempts, batchSize)
+	if err != nil {
+		return result, err
+	}
+	for i := range pending {
+		if s.escalate(ctx, &pending[i]) {
+			result.Escalated++
+		}
+	}
+
+	return result, nil
+}
+
+// Thresholds returns how long an order may stay in each state before it's flagged
+func (s *StuckOrderService) Thresholds() map[models.StuckOrderReason]time.Duration {
+	return s.thresholds
+}
+
+// List returns a tenant's stuck order alerts with open counts per reason
+func (s *StuckOrderService) List(ctx context.Context, tenantID string, filters repository.StuckOrderFilters) (*models.StuckOrderListResponse, error) {
+	alerts, total, err := s.repo.List(ctx, tenantID, filters)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.CountOpenByReason(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds := make(map[models.StuckOrderReason]string, len(s.thresholds))
+	for reason, threshold := range s.thresholds {
+		thresholds[reason] = threshold.String()
+	}
+
+	return &models.StuckOrderListResponse{
+		Alerts:     alerts,
+		Total:      total,
+		OpenCounts: counts,
+		Thresholds: thresholds,
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+	}, nil
+}
+
+// flag records an alert for a stuck order, notes it on the order timeline and publishes
+// order.stuck. It reports false when the order was already flagged.
+func (s *StuckOrderService) flag(ctx context.Context, candidate models.StuckOrderCandidate, reason models.StuckOrderReason, now time.Time) bool {
+	alert := &models.StuckOrderAlert{
+		TenantID:          candidate.TenantID,
+		OrderID:           candidate.OrderID,
+		OrderNumber:       candidate.OrderNumber,
+		Reason:            reason,
+		Status:            candidate.Status,
+		FulfillmentStatus: candidate.FulfillmentStatus,
+		StuckSince:        candidate.StuckSince,
+		DetectedAt:        now,
+	}
+	created, err := s.repo.CreateAlert(ctx, alert)
+	if err != nil {
+		s.logger.WithError(err).WithField("orderId", candidate.OrderID).Error("Failed to record stuck order alert")
+		return false
+	}
+	if !created {
+		return false
+	}
+
+	threshold := s.thresholds[reason]
+	description := fmt.Sprintf("Order flagged as stuck: %s for over %s", stuckReasonDescription(reason), formatThreshold(threshold))
+	if err := s.orderRepo.AddTimelineEventByName(candidate.OrderID, "ORDER_STUCK", description, "Order watchdog", candidate.TenantID); err != nil {
+		s.logger.WithError(err).WithField("orderId", candidate.OrderID).Warn("Failed to add stuck order timeline event")
+	}
+
+	if s.publisher != nil {
+		order, err := s.orderRepo.GetByID(candidate.OrderID, candidate.TenantID)
+		if err != nil {
+			s.logger.WithError(err).WithField("orderId", candidate.OrderID).Warn("Failed to load stuck order for alert event")
+		} else {
+			_ = s.publisher.PublishOrderStuck(ctx, order, string(reason), candidate.StuckSince, threshold, candidate.TenantID)
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"tenantId":    candidate.TenantID,
+		"orderNumber": candidate.OrderNumber,
+		"reason":      reason,
+		"stuckSince":  candidate.StuckSince,
+	}).Warn("Stuck order detected")
+	return true
+}
+
+// escalate opens an ops ticket for an alert, recording the failure for the next pass to retry
+func (s *StuckOrderService) escalate(ctx context.Context, alert *models.StuckOrderAlert) bool {
+	threshold := s.thresholds[alert.Reason]
+	ticket, err := s.ticketsClient.CreateTicket(ctx, alert.TenantID, clients.CreateTicketRequest{
+		Title: fmt.Sprintf("Order %s stuck: %s", alert.OrderNumber, stuckReasonDescription(alert.Reason)),
+		Description: fmt.Sprintf("Order %s has been %s since %s, past the %s threshold. It was %s / %s when detected.",
+			alert.OrderNumber, stuckReasonDescription(alert.Reason), alert.StuckSince.UTC().Format(time.RFC1123),
+			formatThreshold(threshold), alert.Status, alert.FulfillmentStatus),
+		Type:     "INCIDENT",
+		Priority: "HIGH",
+		Tags:     []string{"stuck-order", string(alert.Reason)},
+		Metadata: map[string]interface{}{
+			"orderId":      alert.OrderID.String(),
+			"orderNumber":  alert.OrderNumber,
+			"stuckReason":  string(alert.Reason),
+			"stuckSince":   alert.StuckSince.UTC().Format(time.RFC3339),
+			"stuckAlertId": alert.ID.String(),
+		},
+	})
+
+	alert.EscalationAttempts++
+	if err != nil {
+		alert.EscalationError = err.Error()
+		s.logger.WithError(err).WithField("orderNumber", alert.OrderNumber).Warn("Failed to create ticket for stuck order")
+	} else {
+		now := time.Now()
+		alert.TicketID = ticket.ID
+		alert.TicketNumber = ticket.TicketNumber
+		alert.EscalationError = ""
+		alert.EscalatedAt = &now
+	}
+
+	if updateErr := s.repo.UpdateAlert(ctx, alert); updateErr != nil {
+		s.logger.WithError(updateErr).WithField("orderNumber", alert.OrderNumber).Error("Failed to save stuck order escalation")
+		return false
+	}
+	return err == nil
+}
+
+func stuckReasonDescription(reason models.StuckOrderReason) string {
+	switch reason {
+	case models.StuckOrderPaidNotFulfilled:
+		return "paid but not fulfilled"
+	case models.StuckOrderShippedNotDelivered:
+		return "shipped but not delivered"
+	default:
+		return string(reason)
+	}
+}
+
+// formatThreshold renders whole days as days and anything shorter as hours, e.g. "14 days", "48 hours"
+func formatThreshold(d time.Duration) string {
+	if d >= 72*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d days", int(d.Hours()/24))
+	}
+	return fmt.Sprintf("%g hours", d.Hours())
+}
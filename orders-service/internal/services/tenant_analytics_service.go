@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+const (
+	defaultTopCategoriesLimit = 10
+	maxTopCategoriesLimit     = 50
+	defaultMarginOrdersLimit  = 20
+	maxMarginOrdersLimit      = 100
+)
+
+// ErrMarginOrderNotFound is returned when the order asked for a margin doesn't exist
+var ErrMarginOrderNotFound = errors.New("order not found")
+
+type TenantAnalyticsService struct {
+	repo *repository.TenantAnalyticsRepository
+}
+
+func NewTenantAnalyticsService(repo *repository.TenantAnalyticsRepository) *TenantAnalyticsService {
+	return &TenantAnalyticsService{repo: repo}
+}
+
+// GetOverview builds the tenant dashboard summary and daily series for the inclusive range [from, to]
+func (s *TenantAnalyticsService) GetOverview(tenantID string, from, to time.Time) (*models.TenantAnalyticsOverview, error) {
+	metrics, err := s.repo.GetDailyMetrics(tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := make(map[string]models.TenantDailyMetric, len(metrics))
+	for _, metric := range metrics {
+		byDate[metric.StatDate.Format("2006-01-02")] = metric
+	}
+
+	overview := &models.TenantAnalyticsOverview{
+		From:  from.Format("2006-01-02"),
+		To:    to.Format("2006-01-02"),
+		Daily: []models.TenantDailyPoint{},
+	}
+
+	// Emit a point for every day so charts don't have gaps
+	summary := &overview.Summary
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		metric := byDate[date]
+		point := models.TenantDailyPoint{
+			Date:         date,
+			GrossSales:   metric.GrossSales,
+			OrderCount:   metric.OrderCount,
+			RefundAmount: metric.RefundAmount,
+			NewCustomers: metric.NewCustomers,
+			CartsStarted: metric.CartsStarted,
+		}
+		if metric.OrderCount > 0 {
+			point.AverageOrderValue = roundCurrency(metric.GrossSales / float64(metric.OrderCount))
+		}
+		overview.Daily = append(overview.Daily, point)
+
+		summary.GrossSales += metric.GrossSales
+		summary.OrderCount += metric.OrderCount
+		summary.CancelledCount += metric.CancelledCount
+		summary.ItemsSold += metric.ItemsSold
+		summary.CapturedAmount += metric.CapturedAmount
+		summary.RefundCount += metric.RefundCount
+		summary.RefundAmount += metric.RefundAmount
+		summary.NewCustomers += metric.NewCustomers
+		summary.CartsStarted += metric.CartsStarted
+	}
+
+	summary.GrossSales = roundCurrency(summary.GrossSales)
+	summary.CapturedAmount = roundCurrency(summary.CapturedAmount)
+	summary.RefundAmount = roundCurrency(summary.RefundAmount)
+	summary.NetSales = roundCurrency(summary.GrossSales - summary.RefundAmount)
+	if summary.OrderCount > 0 {
+		summary.AverageOrderValue = roundCurrency(summary.GrossSales / float64(summary.OrderCount))
+		summary.RefundRate = percentage(summary.RefundCount, summary.OrderCount)
+	}
+	if summary.CartsStarted > 0 {
+		summary.CartConversion = percentage(summary.OrderCount, summary.CartsStarted)
+	}
+
+	return overview, nil
+}
+
+// GetTopCategories ranks categories by revenue for the inclusive range [from, to]
+func (s *TenantAnalyticsService) GetTopCategories(tenantID string, from, to time.Time, limit int) ([]models.CategorySales, error) {
+	if limit <= 0 {
+		limit = defaultTopCategoriesLimit
+	}
+	if limit > maxTopCategoriesLimit {
+		limit = maxTopCategoriesLimit
+	}
+
+	categories, err := s.repo.GetTopCategories(tenantID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.repo.GetCategoryRevenueTotal(tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if categories == nil {
+		categories = []models.CategorySales{}
+	}
+	for i := range categories {
+		categories[i].Revenue = roundCurrency(categories[i].Revenue)
+		if total > 0 {
+			categories[i].Share = math.Round(categories[i].Revenue/total*10000) / 100
+		}
+	}
+	return categories, nil
+}
+
+// GetMarginReport totals gross margin over the orders in the inclusive range [from, to] and
+// returns a page of them with their own margins
+func (s *TenantAnalyticsService) GetMarginReport(tenantID string, from, to time.Time, page, limit int) (*models.MarginReport, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = defaultMarginOrdersLimit
+	}
+	if limit > maxMarginOrdersLimit {
+		limit = maxMarginOrdersLimit
+	}
+
+	totals, err := s.repo.GetMarginTotals(tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.repo.ListMarginOrders(tenantID, from, to, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	revenue := roundCurrency(totals.ItemRevenue - totals.Discounts)
+	cost := roundCurrency(totals.Cost)
+	report := &models.MarginReport{
+		From:          from.Format("2006-01-02"),
+		To:            to.Format("2006-01-02"),
+		OrderCount:    totals.OrderCount,
+		Revenue:       revenue,
+		Cost:          cost,
+		GrossMargin:   roundCurrency(revenue - cost),
+		MarginPercent: marginPercent(revenue, cost),
+		UncostedItems: totals.UncostedItems,
+		Orders:        make([]models.OrderMargin, 0, len(orders)),
+		Page:          page,
+		Limit:         limit,
+	}
+	for i := range orders {
+		report.Orders = append(report.Orders, orderMargin(&orders[i], false))
+	}
+	return report, nil
+}
+
+// GetOrderMargin returns an order's gross margin with a line per item
+func (s *TenantAnalyticsService) GetOrderMargin(tenantID string, orderID uuid.UUID) (*models.OrderMargin, error) {
+	order, err := s.repo.GetOrderWithItems(tenantID, orderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMarginOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	margin := orderMargin(order, true)
+	return &margin, nil
+}
+
+// orderMargin computes an order's gross margin from its items' snapshotted costs
+func orderMargin(order *models.Order, withItems bool) models.OrderMargin {
+	margin := models.OrderMargin{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		Status:      order.Status,
+		Currency:    order.Currency,
+		CreatedAt:   order.CreatedAt,
+	}
+
+	var itemRevenue, cost float64
+	for _, item := range order.Items {
+		itemRevenue += item.TotalPrice
+		line := models.OrderItemMargin{
+			OrderItemID: item.ID,
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			SKU:         item.SKU,
+			Quantity:    item.Quantity,
+			Revenue:     item.TotalPrice,
+		}
+		if item.UnitCost == nil {
+			margin.UncostedItems++
+		} else {
+			lineCost := roundCurrency(*item.UnitCost * float64(item.Quantity))
+			lineMargin := roundCurrency(item.TotalPrice - lineCost)
+			cost += lineCost
+			line.UnitCost = item.UnitCost
+			line.Cost = &lineCost
+			line.GrossMargin = &lineMargin
+		}
+		if withItems {
+			margin.Items = append(margin.Items, line)
+		}
+	}
+
+	margin.Revenue = roundCurrency(itemRevenue - order.DiscountAmount)
+	margin.Cost = roundCurrency(cost)
+	margin.GrossMargin = roundCurrency(margin.Revenue - margin.Cost)
+	margin.MarginPercent = marginPercent(margin.Revenue, margin.Cost)
+	return margin
+}
+
+// marginPercent returns gross margin as a percentage of revenue, rounded to two decimals
+func marginPercent(revenue, cost float64) float64 {
+	if revenue <= 0 {
+		return 0
+	}
+	return math.Round((revenue-cost)/revenue*10000) / 100
+}
+
+// percentage returns part/whole as a percentage rounded to two decimals
+func percentage(part, whole int64) float64 {
+	return math.Round(float64(part)/float64(whole)*10000) / 100
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+const (
+	// MaxAnalyticsRangeDays caps analytics date ranges to keep queries bounded
+	MaxAnalyticsRangeDays = 366
+	vendorTopProductsLimit = 10
+)
+
+type VendorAnalyticsService struct {
+	repo *repository.VendorAnalyticsRepository
+}
+
+func NewVendorAnalyticsService(repo *repository.VendorAnalyticsRepository) *VendorAnalyticsService {
+	return &VendorAnalyticsService{repo: repo}
+}
+
+// GetVendorAnalytics builds the vendor dashboard for the inclusive date range [from, to].
+// Totals come from the daily rollups; top products are read live from order items.
+func (s *VendorAnalyticsService) GetVendorAnalytics(tenantID, vendorID string, from, to time.Time) (*models.VendorAnalytics, error) {
+	stats, err := s.repo.GetDailyStats(tenantID, vendorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := make(map[string]models.VendorDailyStat, len(stats))
+	for _, stat := range stats {
+		byDate[stat.StatDate.Format("2006-01-02")] = stat
+	}
+
+	analytics := &models.VendorAnalytics{
+		VendorID: vendorID,
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Daily:    []models.VendorDailyPoint{},
+	}
+
+	// Emit a point for every day so charts don't have gaps
+	summary := &analytics.Summary
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		stat := byDate[date]
+		analytics.Daily = append(analytics.Daily, models.VendorDailyPoint{
+			Date:        date,
+			GMV:         stat.GMV,
+			OrderCount:  stat.OrderCount,
+			ItemsSold:   stat.ItemsSold,
+			ReturnCount: stat.ReturnCount,
+		})
+		summary.GMV += stat.GMV
+		summary.OrderCount += stat.OrderCount
+		summary.CancelledCount += stat.CancelledCount
+		summary.ItemsSold += stat.ItemsSold
+		summary.ReturnCount += stat.ReturnCount
+		summary.RefundAmount += stat.RefundAmount
+	}
+
+	summary.GMV = roundCurrency(summary.GMV)
+	summary.RefundAmount = roundCurrency(summary.RefundAmount)
+	if summary.OrderCount > 0 {
+		summary.AverageOrderValue = roundCurrency(summary.GMV / float64(summary.OrderCount))
+		summary.ReturnRate = percentage(summary.ReturnCount, summary.OrderCount)
+	}
+
+	topProducts, err := s.repo.GetTopProducts(tenantID, vendorID, from, to.AddDate(0, 0, 1), vendorTopProductsLimit)
+	if err != nil {
+		return nil, err
+	}
+	if topProducts == nil {
+		topProducts = []models.VendorTopProduct{}
+	}
+	analytics.TopProducts = topProducts
+
+	return analytics, nil
+}
+
+func roundCurrency(v float64) float64 {
+	return math.Round(v*100) / 100
+}
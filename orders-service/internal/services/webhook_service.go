@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"orders-service/internal/encryption"
+	"orders-service/internal/metrics"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+const (
+	// webhookClaimDuration is how long a claimed delivery is reserved for one dispatcher
+	webhookClaimDuration = 2 * time.Minute
+	// webhookMaxBackoff caps the delay between retries
+	webhookMaxBackoff       = 6 * time.Hour
+	webhookTimeout          = 10 * time.Second
+	webhookResponseLimit    = 2048
+	webhookDeliveriesPage   = 50
+	webhookDefaultAttempts  = 8
+	webhookSecretPrefix     = "whsec_"
+	webhookSecretRandomSize = 32
+)
+
+var (
+	// ErrWebhookSubscriptionNotFound is returned when a subscription doesn't exist for the tenant
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	// ErrWebhookDeliveryNotFound is returned when a delivery doesn't exist for the subscription
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	// ErrWebhookDeliveryOutstanding is returned when replaying a delivery that hasn't finished
+	ErrWebhookDeliveryOutstanding = errors.New("delivery is still pending")
+	// ErrInvalidWebhookURL is returned for endpoints that aren't absolute http(s) URLs
+	ErrInvalidWebhookURL = errors.New("url must be an http or https URL")
+)
+
+// WebhookService manages tenants' webhook subscriptions and dispatches their outbox. Order
+// lifecycle events are queued in the transaction that records them on the order timeline,
+// then sent as signed JSON with retries; every delivery is kept as a log entry.
+type WebhookService struct {
+	repo       *repository.WebhookRepository
+	httpClient *http.Client
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo *repository.WebhookRepository) *WebhookService {
+	return &WebhookService{
+		repo:       repo,
+		httpClient: newTenantEndpointClient(),
+	}
+}
+
+// List returns the tenant's webhook subscriptions
+func (s *WebhookService) List(ctx context.Context, tenantID string) ([]models.WebhookSubscription, error) {
+	return s.repo.List(ctx, tenantID)
+}
+
+// Get returns one of the tenant's webhook subscriptions
+func (s *WebhookService) Get(ctx context.Context, tenantID string, id uuid.UUID) (*models.WebhookSubscription, error) {
+	subscription, err := s.repo.GetByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrWebhookSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+// Create adds a webhook subscription with a new signing secret, returned only this once
+func (s *WebhookService) Create(ctx context.Context, tenantID, userID string, req *models.WebhookSubscriptionRequest) (*models.WebhookSubscriptionWithSecret, error) {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	subscription := &models.WebhookSubscription{
+		TenantID:  tenantID,
+		Secret:    encryption.EncryptedString(secret),
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+	if err := applyWebhookRequest(subscription, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return &models.WebhookSubscriptionWithSecret{WebhookSubscription: *subscription, Secret: secret}, nil
+}
+
+// Update replaces a webhook subscription's settings. The signing secret is kept.
+func (s *WebhookService) Update(ctx context.Context, tenantID string, id uuid.UUID, userID string, req *models.WebhookSubscriptionRequest) (*models.WebhookSubscription, error) {
+	subscription, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.UpdatedBy = userID
+	if err := applyWebhookRequest(subscription, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// RotateSecret replaces a subscription's signing secret. Deliveries sent from now on, including
+// retries, are signed with the new secret.
+func (s *WebhookService) RotateSecret(ctx context.Context, tenantID string, id uuid.UUID, userID string) (*models.WebhookSubscriptionWithSecret, error) {
+	subscription, err := s.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Secret = encryption.EncryptedString(secret)
+	subscription.UpdatedBy = userID
+	if err := s.repo.Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return &models.WebhookSubscriptionWithSecret{WebhookSubscription: *subscription, Secret: secret}, nil
+}
+
+// Delete removes a webhook subscription
+func (s *WebhookService) Delete(ctx context.Context, tenantID string, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, tenantID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWebhookSubscriptionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListDeliveries returns a page of a subscription's delivery log
+func (s *WebhookService) ListDeliveries(ctx context.Context, tenantID string, id uuid.UUID, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, int64, error) {
+	if _, err := s.Get(ctx, tenantID, id); err != nil {
+		return nil, 0, err
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = webhookDeliveriesPage
+	}
+	return s.repo.ListDeliveries(ctx, tenantID, id, filter)
+}
+
+// ReplayDelivery queues a delivered or failed delivery to be sent again, with the order as it
+// is now
+func (s *WebhookService) ReplayDelivery(ctx context.Context, tenantID string, id, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	if _, err := s.Get(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.repo.GetDelivery(ctx, tenantID, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if delivery.Status == models.WebhookDeliveryPending || delivery.Status == models.WebhookDeliveryDelivering {
+		return nil, ErrWebhookDeliveryOutstanding
+	}
+	return s.repo.CreateReplay(ctx, delivery, time.Now())
+}
+
+// DispatchDue attempts up to limit due deliveries and returns how many were delivered and how
+// many were attempted
+func (s *WebhookService) DispatchDue(ctx context.Context, now time.Time, limit int) (int, int, error) {
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, now, now.Add(webhookClaimDuration), limit)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	subscriptions := make(map[uuid.UUID]*models.WebhookSubscription)
+	delivered := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.repo.GetSubscriptionForDelivery(ctx, delivery.SubscriptionID)
+			if err != nil {
+				log.Printf("[Webhooks] Failed to load subscription %s: %v", delivery.SubscriptionID, err)
+				continue // The claim expires and the delivery is retried
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		attempts := delivery.Attempts
+		if s.dispatch(ctx, subscription, delivery, time.Now()) {
+			delivered++
+		}
+		// A disabled subscription's delivery is put back without an attempt
+		if delivery.Attempts > attempts || delivery.Status == models.WebhookDeliveryFailed {
+			metrics.RecordDelivery(metrics.DeliveryWebhook, delivery.Event, string(delivery.Status))
+		}
+		if err := s.repo.SaveDeliveryAttempt(ctx, delivery); err != nil {
+			log.Printf("[Webhooks] Failed to save delivery %s: %v", delivery.ID, err)
+		}
+	}
+	return delivered, len(deliveries), nil
+}
+
+// dispatch makes one attempt at a delivery and updates it with the outcome. It reports whether
+// the delivery succeeded.
+func (s *WebhookService) dispatch(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery, now time.Time) bool {
+	switch {
+	case subscription.DeletedAt.Valid:
+		failWebhookDelivery(delivery, "subscription was deleted")
+		return false
+	case !subscription.IsActive:
+		// Disabled: keep the delivery queued without using up an attempt
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.NextAttemptAt = now.Add(15 * time.Minute)
+		return false
+	}
+
+	if delivery.Payload == "" {
+		order, err := s.repo.GetOrderForDelivery(ctx, delivery.TenantID, delivery.OrderID)
+		if err != nil {
+			retryWebhookDelivery(subscription, delivery, now, err.Error())
+			return false
+		}
+		if order == nil {
+			failWebhookDelivery(delivery, "order not found")
+			return false
+		}
+		payload, err := buildWebhookPayload(delivery, order)
+		if err != nil {
+			failWebhookDelivery(delivery, err.Error())
+			return false
+		}
+		delivery.Payload = payload
+	}
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	status, respBody, err := s.send(ctx, subscription, delivery, now)
+	delivery.ResponseStatus = status
+	delivery.ResponseBody = respBody
+	if err != nil {
+		retryWebhookDelivery(subscription, delivery, now, err.Error())
+		return false
+	}
+
+	delivery.Status = models.WebhookDeliveryDelivered
+	delivery.DeliveredAt = &now
+	delivery.Error = ""
+	return true
+}
+
+// send posts a delivery's payload to the subscription's URL, signed with
+// X-Webhook-Signature: sha256=HMAC(secret, timestamp + "." + body) alongside X-Webhook-Timestamp
+func (s *WebhookService) send(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery, now time.Time) (int, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Tesseract-Webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-ID", delivery.ID.String()) // Stable across retries, for receiver de-duplication
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+signWebhook(subscription.Secret.String(), timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(respBody), fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(respBody), nil
+}
+
+// retryWebhookDelivery schedules the next attempt with exponential backoff (1, 2, 4 ... minutes,
+// capped at 6 hours), or fails the delivery once it is out of attempts
+func retryWebhookDelivery(subscription *models.WebhookSubscription, delivery *models.WebhookDelivery, now time.Time, reason string) {
+	delivery.Error = reason
+	if delivery.Attempts >= subscription.MaxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+		return
+	}
+
+	backoff := time.Minute << uint(min(delivery.Attempts, 12))
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.NextAttemptAt = now.Add(backoff)
+}
+
+func failWebhookDelivery(delivery *models.WebhookDelivery, reason string) {
+	delivery.Status = models.WebhookDeliveryFailed
+	delivery.Error = reason
+}
+
+func buildWebhookPayload(delivery *models.WebhookDelivery, order *models.Order) (string, error) {
+	payload := models.WebhookPayload{
+		ID:        delivery.ID.String(),
+		Event:     delivery.Event,
+		TenantID:  delivery.TenantID,
+		CreatedAt: delivery.CreatedAt.UTC(),
+	}
+	payload.Data.Order = order
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode payload: %w", err)
+	}
+	return string(body), nil
+}
+
+func signWebhook(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretRandomSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return webhookSecretPrefix + hex.EncodeToString(b), nil
+}
+
+// applyWebhookRequest validates a request and copies it onto a subscription
+func applyWebhookRequest(subscription *models.WebhookSubscription, req *models.WebhookSubscriptionRequest) error {
+	endpoint, err := url.Parse(req.URL)
+	if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	subscription.Name = req.Name
+	subscription.URL = req.URL
+	subscription.Events = pq.StringArray(req.Events)
+	subscription.MaxAttempts = webhookDefaultAttempts
+	if req.MaxAttempts != nil {
+		subscription.MaxAttempts = *req.MaxAttempts
+	}
+	subscription.IsActive = true
+	if req.IsActive != nil {
+		subscription.IsActive = *req.IsActive
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/models"
+	"orders-service/internal/repository"
+)
+
+// CartStartedSubject is published by customers-service when a cart goes from empty to non-empty
+const CartStartedSubject = "customer.cart_started"
+
+// AnalyticsSubscriber records payment and customer signals into the analytics ledger.
+// Order metrics are read straight from the orders tables by the rollup job.
+type AnalyticsSubscriber struct {
+	paymentSubscriber  *gosharedevents.Subscriber
+	customerSubscriber *gosharedevents.Subscriber
+	repo               *repository.TenantAnalyticsRepository
+	logger             *logrus.Entry
+	cancel             context.CancelFunc
+}
+
+// NewAnalyticsSubscriber creates a new analytics event subscriber
+func NewAnalyticsSubscriber(repo *repository.TenantAnalyticsRepository, logger *logrus.Logger) (*AnalyticsSubscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	// One subscriber per stream: each shared subscriber drives a single consume loop
+	paymentSubscriber, err := gosharedevents.NewSubscriber(analyticsSubscriberConfig(natsURL, "orders-service-analytics-payments"), logger)
+	if err != nil {
+		return nil, err
+	}
+	customerSubscriber, err := gosharedevents.NewSubscriber(analyticsSubscriberConfig(natsURL, "orders-service-analytics-customers"), logger)
+	if err != nil {
+		paymentSubscriber.Close()
+		return nil, err
+	}
+
+	return &AnalyticsSubscriber{
+		paymentSubscriber:  paymentSubscriber,
+		customerSubscriber: customerSubscriber,
+		repo:               repo,
+		logger:             logger.WithField("component", "analytics-subscriber"),
+	}, nil
+}
+
+func analyticsSubscriberConfig(natsURL, consumerName string) *gosharedevents.SubscriberConfig {
+	config := gosharedevents.DefaultSubscriberConfig(natsURL, consumerName)
+	config.Name = consumerName
+	config.DeliverPolicy = "all" // The ledger is deduplicated, so replaying retained history is safe
+	config.MaxDeliver = 5
+	config.AckWait = 30 * time.Second
+	return config
+}
+
+// Start starts listening for payment and customer events
+func (s *AnalyticsSubscriber) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	paymentSubjects := []string{gosharedevents.PaymentCaptured, gosharedevents.PaymentSucceeded, gosharedevents.PaymentRefunded}
+	if err := s.paymentSubscriber.Subscribe(ctx, gosharedevents.StreamPayments, paymentSubjects, s.handlePaymentEvent); err != nil {
+		return err
+	}
+
+	customerSubjects := []string{gosharedevents.CustomerCreated, gosharedevents.CustomerRegistered, CartStartedSubject}
+	if err := s.customerSubscriber.Subscribe(ctx, gosharedevents.StreamCustomers, customerSubjects, s.handleCustomerEvent); err != nil {
+		return err
+	}
+
+	s.logger.WithField("subjects", append(paymentSubjects, customerSubjects...)).Info("Analytics subscriber started successfully")
+	return nil
+}
+
+// handlePaymentEvent records captured and refunded amounts
+func (s *AnalyticsSubscriber) handlePaymentEvent(ctx context.Context, msg *gosharedevents.Message) error {
+	var event gosharedevents.PaymentEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		s.logger.WithError(err).Error("Failed to unmarshal payment event")
+		return nil // Don't redeliver malformed messages
+	}
+	if event.TenantID == "" || event.PaymentID == "" {
+		return nil
+	}
+
+	record := &models.AnalyticsEvent{
+		TenantID:   event.TenantID,
+		OccurredAt: eventTime(event.Timestamp, msg.Timestamp),
+	}
+
+	switch msg.Subject {
+	case gosharedevents.PaymentRefunded:
+		record.Kind = models.AnalyticsEventPaymentRefunded
+		record.DedupeKey = event.PaymentID
+		if event.RefundID != "" {
+			record.DedupeKey = event.RefundID
+		}
+		record.Amount = event.RefundAmount
+	default:
+		// payment.captured and payment.succeeded describe the same capture; key on the payment
+		record.Kind = models.AnalyticsEventPaymentCaptured
+		record.DedupeKey = event.PaymentID
+		record.Amount = event.Amount
+	}
+
+	return s.repo.RecordEvent(record)
+}
+
+// handleCustomerEvent records new customers and started carts
+func (s *AnalyticsSubscriber) handleCustomerEvent(ctx context.Context, msg *gosharedevents.Message) error {
+	var event gosharedevents.CustomerEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		s.logger.WithError(err).Error("Failed to unmarshal customer event")
+		return nil // Don't redeliver malformed messages
+	}
+	if event.TenantID == "" {
+		return nil
+	}
+
+	record := &models.AnalyticsEvent{
+		TenantID:   event.TenantID,
+		OccurredAt: eventTime(event.Timestamp, msg.Timestamp),
+	}
+
+	switch msg.Subject {
+	case CartStartedSubject:
+		if event.SourceID == "" {
+			return nil
+		}
+		record.Kind = models.AnalyticsEventCartStarted
+		record.DedupeKey = event.SourceID
+	default:
+		// customer.created and customer.registered both mark acquisition; count each customer once
+		if event.CustomerID == "" {
+			return nil
+		}
+		record.Kind = models.AnalyticsEventCustomerAcquired
+		record.DedupeKey = event.CustomerID
+	}
+
+	return s.repo.RecordEvent(record)
+}
+
+// Stop stops the subscriber
+func (s *AnalyticsSubscriber) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.paymentSubscriber != nil {
+		s.paymentSubscriber.Close()
+	}
+	if s.customerSubscriber != nil {
+		s.customerSubscriber.Close()
+	}
+	s.logger.Info("Analytics subscriber stopped")
+}
+
+func eventTime(eventTimestamp, messageTimestamp time.Time) time.Time {
+	if !eventTimestamp.IsZero() {
+		return eventTimestamp.UTC()
+	}
+	if !messageTimestamp.IsZero() {
+		return messageTimestamp.UTC()
+	}
+	return time.Now().UTC()
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/sirupsen/logrus"
 	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
 	"orders-service/internal/clients"
+	"orders-service/internal/eventconsumer"
 	"orders-service/internal/models"
 )
 
@@ -21,11 +22,10 @@ type OrderExecutor interface {
 
 // ApprovalSubscriber handles incoming approval events
 type ApprovalSubscriber struct {
-	subscriber     *gosharedevents.Subscriber
+	consumer       *eventconsumer.Consumer
 	orderExecutor  OrderExecutor
 	approvalClient *clients.ApprovalClient
 	logger         *logrus.Entry
-	cancel         context.CancelFunc
 }
 
 // NewApprovalSubscriber creates a new approval event subscriber
@@ -39,19 +39,20 @@ func NewApprovalSubscriber(
 		natsURL = "nats://nats.nats.svc.cluster.local:4222"
 	}
 
-	config := gosharedevents.DefaultSubscriberConfig(natsURL, "orders-service-approvals")
-	config.Name = "orders-service-approval-subscriber"
-	config.DeliverPolicy = "new"
-	config.MaxDeliver = 3
-	config.AckWait = 30 * time.Second
-
-	subscriber, err := gosharedevents.NewSubscriber(config, logger)
+	consumer, err := eventconsumer.New(eventconsumer.Config{
+		NATSURL:    natsURL,
+		ClientName: "orders-service-approval-subscriber",
+		Durable:    "orders-service-approvals",
+		Stream:     gosharedevents.StreamApprovals,
+		MaxDeliver: 3,
+		AckWait:    30 * time.Second,
+	}, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ApprovalSubscriber{
-		subscriber:     subscriber,
+		consumer:       consumer,
 		orderExecutor:  orderExecutor,
 		approvalClient: approvalClient,
 		logger:         logger.WithField("component", "approval-subscriber"),
@@ -60,15 +61,12 @@ func NewApprovalSubscriber(
 
 // Start starts listening for approval events
 func (s *ApprovalSubscriber) Start(ctx context.Context) error {
-	ctx, cancel := context.WithCancel(ctx)
-	s.cancel = cancel
-
 	// Subscribe to approval.granted events for orders
 	subjects := []string{gosharedevents.ApprovalGranted}
 
 	s.logger.Info("Starting approval event subscription...")
 
-	err := s.subscriber.SubscribeApprovalEvents(ctx, subjects, s.handleApprovalEvent)
+	err := s.consumer.Start(ctx, subjects, eventconsumer.JSON(s.handleApprovalEvent))
 	if err != nil {
 		return err
 	}
@@ -189,11 +187,8 @@ func (s *ApprovalSubscriber) executeCancel(ctx context.Context, event *gosharede
 
 // Stop stops the approval subscriber
 func (s *ApprovalSubscriber) Stop() {
-	if s.cancel != nil {
-		s.cancel()
-	}
-	if s.subscriber != nil {
-		s.subscriber.Close()
+	if s.consumer != nil {
+		s.consumer.Stop()
 	}
 	s.logger.Info("Approval subscriber stopped")
 }
@@ -0,0 +1,99 @@
+package subscribers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/nats-io/nats.go"
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/services"
+)
+
+// LiveEventSubjects are the NATS subjects relayed to the admin live event stream.
+// payment-service reports captures as payment.succeeded, so both capture subjects are bridged.
+var LiveEventSubjects = []string{
+	gosharedevents.OrderCreated,
+	gosharedevents.PaymentCaptured,
+	gosharedevents.PaymentSucceeded,
+	gosharedevents.TicketCreated,
+}
+
+// LiveEventsSubscriber bridges NATS events into the live event hub.
+// It uses plain core NATS subscriptions rather than a JetStream consumer: every replica must
+// see every event for its own connected clients, and nothing needs redelivery because
+// clients that miss events resync from the REST APIs.
+type LiveEventsSubscriber struct {
+	nc     *nats.Conn
+	subs   []*nats.Subscription
+	hub    *services.LiveEventHub
+	logger *logrus.Entry
+}
+
+// NewLiveEventsSubscriber creates a new live events subscriber
+func NewLiveEventsSubscriber(hub *services.LiveEventHub, logger *logrus.Logger) (*LiveEventsSubscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	nc, err := nats.Connect(natsURL,
+		nats.Name("orders-service-live-events"),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(2*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	return &LiveEventsSubscriber{
+		nc:     nc,
+		hub:    hub,
+		logger: logger.WithField("component", "live-events-subscriber"),
+	}, nil
+}
+
+// Start subscribes to the live event subjects
+func (s *LiveEventsSubscriber) Start() error {
+	for _, subject := range LiveEventSubjects {
+		sub, err := s.nc.Subscribe(subject, s.handleMessage)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+		}
+		s.subs = append(s.subs, sub)
+	}
+
+	s.logger.WithField("subjects", LiveEventSubjects).Info("Live events subscriber started successfully")
+	return nil
+}
+
+func (s *LiveEventsSubscriber) handleMessage(msg *nats.Msg) {
+	var envelope gosharedevents.BaseEvent
+	if err := json.Unmarshal(msg.Data, &envelope); err != nil {
+		s.logger.WithError(err).WithField("subject", msg.Subject).Debug("Skipping malformed live event")
+		return
+	}
+	if envelope.TenantID == "" {
+		return
+	}
+
+	occurredAt := envelope.Timestamp
+	if occurredAt.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+
+	s.hub.Publish(msg.Subject, envelope.TenantID, occurredAt, json.RawMessage(msg.Data))
+}
+
+// Stop unsubscribes and closes the NATS connection
+func (s *LiveEventsSubscriber) Stop() {
+	for _, sub := range s.subs {
+		_ = sub.Unsubscribe()
+	}
+	if s.nc != nil {
+		s.nc.Close()
+	}
+	s.logger.Info("Live events subscriber stopped")
+}
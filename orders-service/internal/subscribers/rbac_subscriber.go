@@ -0,0 +1,100 @@
+package subscribers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/sirupsen/logrus"
+	"orders-service/internal/middleware"
+)
+
+// RBACPermissionsChangedSubject is published by staff-service when roles, role permissions,
+// role assignments or API key scopes change
+const RBACPermissionsChangedSubject = "rbac.permissions_changed"
+
+// permissionsChangedEvent lists the affected staff; none means the whole tenant
+type permissionsChangedEvent struct {
+	TenantID string   `json:"tenantId"`
+	StaffIDs []string `json:"staffIds"`
+}
+
+// RBACSubscriber drops cached effective permissions when staff-service changes them.
+// Like the live events bridge it uses a core NATS subscription, since every replica
+// holds its own cache.
+type RBACSubscriber struct {
+	nc     *nats.Conn
+	sub    *nats.Subscription
+	cache  *middleware.RBACPermissionCache
+	logger *logrus.Entry
+}
+
+// NewRBACSubscriber creates a new RBAC permissions subscriber
+func NewRBACSubscriber(cache *middleware.RBACPermissionCache, logger *logrus.Logger) (*RBACSubscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	nc, err := nats.Connect(natsURL,
+		nats.Name("orders-service-rbac"),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(2*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	return &RBACSubscriber{
+		nc:     nc,
+		cache:  cache,
+		logger: logger.WithField("component", "rbac-subscriber"),
+	}, nil
+}
+
+// Start subscribes to permission changes
+func (s *RBACSubscriber) Start() error {
+	sub, err := s.nc.Subscribe(RBACPermissionsChangedSubject, s.handleMessage)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", RBACPermissionsChangedSubject, err)
+	}
+	s.sub = sub
+
+	s.logger.WithField("subject", RBACPermissionsChangedSubject).Info("RBAC subscriber started successfully")
+	return nil
+}
+
+func (s *RBACSubscriber) handleMessage(msg *nats.Msg) {
+	var event permissionsChangedEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil || event.TenantID == "" {
+		s.logger.WithField("subject", msg.Subject).Debug("Skipping malformed permissions event")
+		return
+	}
+
+	if len(event.StaffIDs) == 0 {
+		s.cache.InvalidateTenant(event.TenantID)
+		return
+	}
+
+	staffIDs := make([]uuid.UUID, 0, len(event.StaffIDs))
+	for _, id := range event.StaffIDs {
+		if parsed, err := uuid.Parse(id); err == nil {
+			staffIDs = append(staffIDs, parsed)
+		}
+	}
+	s.cache.InvalidateStaff(event.TenantID, staffIDs)
+}
+
+// Stop unsubscribes and closes the NATS connection
+func (s *RBACSubscriber) Stop() {
+	if s.sub != nil {
+		_ = s.sub.Unsubscribe()
+	}
+	if s.nc != nil {
+		s.nc.Close()
+	}
+	s.logger.Info("RBAC subscriber stopped")
+}
@@ -0,0 +1,25 @@
+-- Migration: 013_vendor_daily_stats.sql
+-- Description: Add vendor_daily_stats rollup table backing the vendor analytics dashboard
+-- Rows are recomputed by the vendor stats rollup job; one row per tenant/vendor/day
+
+CREATE TABLE IF NOT EXISTS vendor_daily_stats (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    vendor_id VARCHAR(255) NOT NULL,
+    stat_date DATE NOT NULL,
+
+    -- Order activity (GMV excludes cancelled orders)
+    gmv DECIMAL(14,2) DEFAULT 0,
+    order_count BIGINT DEFAULT 0,
+    cancelled_count BIGINT DEFAULT 0,
+    items_sold BIGINT DEFAULT 0,
+
+    -- Returns opened that day against the vendor's orders
+    return_count BIGINT DEFAULT 0,
+    refund_amount DECIMAL(14,2) DEFAULT 0,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vendor_daily_stats_key ON vendor_daily_stats(tenant_id, vendor_id, stat_date);
@@ -0,0 +1,53 @@
+-- Migration: 014_tenant_analytics.sql
+-- Description: Tenant-wide analytics rollups backing /api/v1/analytics/*
+-- analytics_events is a deduplicated ledger of payment/customer/cart events consumed from NATS;
+-- tenant_daily_metrics and tenant_daily_category_sales are recomputed by the rollup job
+
+CREATE TABLE IF NOT EXISTS analytics_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    kind VARCHAR(30) NOT NULL, -- payment_captured, payment_refunded, customer_acquired, cart_started
+    dedupe_key VARCHAR(255) NOT NULL,
+    amount DECIMAL(14,2) DEFAULT 0,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_analytics_events_dedupe ON analytics_events(tenant_id, kind, dedupe_key);
+CREATE INDEX IF NOT EXISTS idx_analytics_events_tenant_time ON analytics_events(tenant_id, occurred_at);
+
+CREATE TABLE IF NOT EXISTS tenant_daily_metrics (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    stat_date DATE NOT NULL,
+
+    -- From orders (cancelled orders excluded from sales)
+    gross_sales DECIMAL(14,2) DEFAULT 0,
+    order_count BIGINT DEFAULT 0,
+    cancelled_count BIGINT DEFAULT 0,
+    items_sold BIGINT DEFAULT 0,
+
+    -- From the analytics_events ledger
+    captured_amount DECIMAL(14,2) DEFAULT 0,
+    refund_count BIGINT DEFAULT 0,
+    refund_amount DECIMAL(14,2) DEFAULT 0,
+    new_customers BIGINT DEFAULT 0,
+    carts_started BIGINT DEFAULT 0,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_daily_metrics_key ON tenant_daily_metrics(tenant_id, stat_date);
+
+CREATE TABLE IF NOT EXISTS tenant_daily_category_sales (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    stat_date DATE NOT NULL,
+    category_id VARCHAR(255) NOT NULL,
+    revenue DECIMAL(14,2) DEFAULT 0,
+    quantity BIGINT DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_daily_category_sales_key ON tenant_daily_category_sales(tenant_id, stat_date, category_id);
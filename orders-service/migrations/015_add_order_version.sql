@@ -0,0 +1,3 @@
+-- Add version column for optimistic concurrency control on order updates
+-- Existing orders start at version 1; PUT /orders/:id accepts If-Match or expectedVersion
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
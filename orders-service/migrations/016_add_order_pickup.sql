@@ -0,0 +1,22 @@
+-- Click-and-collect: orders are either shipped or collected from an inventory-service location
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS fulfillment_type VARCHAR(20) NOT NULL DEFAULT 'SHIPPING';
+
+CREATE TABLE IF NOT EXISTS order_pickups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    location_id UUID NOT NULL,
+    location_name TEXT NOT NULL,
+    street TEXT NOT NULL,
+    city TEXT NOT NULL,
+    state TEXT,
+    postal_code TEXT,
+    country TEXT NOT NULL,
+    phone TEXT,
+    instructions TEXT,
+    ready_at TIMESTAMPTZ,
+    picked_up_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_pickups_location_id ON order_pickups(location_id);
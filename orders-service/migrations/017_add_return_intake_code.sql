@@ -0,0 +1,3 @@
+-- Drop-off intake: approved returns get a code that warehouse staff scan from the return's QR code
+ALTER TABLE returns ADD COLUMN IF NOT EXISTS intake_code VARCHAR(32);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_returns_intake_code ON returns(intake_code);
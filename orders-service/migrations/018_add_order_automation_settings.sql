@@ -0,0 +1,5 @@
+-- Order automation: customer self-cancel window, auto-confirmation delay and unpaid order cancellation.
+-- 0 disables each, so existing tenants keep their current behavior until they opt in.
+ALTER TABLE cancellation_settings ADD COLUMN IF NOT EXISTS self_cancel_window_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE cancellation_settings ADD COLUMN IF NOT EXISTS auto_confirm_delay_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE cancellation_settings ADD COLUMN IF NOT EXISTS unpaid_order_cancel_hours INTEGER NOT NULL DEFAULT 0;
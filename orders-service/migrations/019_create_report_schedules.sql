@@ -0,0 +1,52 @@
+-- Scheduled email reports: tenants' recurring sales summary, low stock and vendor payout reports,
+-- and the history of each run
+CREATE TABLE IF NOT EXISTS report_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    report_type VARCHAR(30) NOT NULL,
+    frequency VARCHAR(20) NOT NULL,
+    format VARCHAR(10) NOT NULL DEFAULT 'csv',
+    recipients TEXT[] NOT NULL,
+    vendor_id VARCHAR(255),
+    warehouse_id VARCHAR(255),
+    hour_utc INTEGER DEFAULT 6,
+    day_of_week INTEGER DEFAULT 1,
+    day_of_month INTEGER DEFAULT 1,
+    is_active BOOLEAN DEFAULT TRUE,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    last_status VARCHAR(20),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_schedules_tenant ON report_schedules(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_report_schedules_due ON report_schedules(next_run_at);
+CREATE INDEX IF NOT EXISTS idx_report_schedules_deleted_at ON report_schedules(deleted_at);
+
+CREATE TABLE IF NOT EXISTS report_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    schedule_id UUID NOT NULL,
+    report_type VARCHAR(30) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    period_start DATE,
+    period_end DATE,
+    recipients TEXT[],
+    status VARCHAR(20) NOT NULL,
+    manual BOOLEAN DEFAULT FALSE,
+    row_count INTEGER DEFAULT 0,
+    file_name VARCHAR(255),
+    file_size BIGINT DEFAULT 0,
+    storage_bucket VARCHAR(255),
+    storage_path VARCHAR(500),
+    error TEXT,
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_deliveries_tenant ON report_deliveries(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_report_deliveries_schedule ON report_deliveries(schedule_id, created_at);
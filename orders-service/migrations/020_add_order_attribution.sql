@@ -0,0 +1,3 @@
+-- Marketing attribution: the first and last UTM touch the storefront captured before checkout,
+-- passed on in order events so marketing-service can attribute revenue to campaigns
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS attribution JSONB;
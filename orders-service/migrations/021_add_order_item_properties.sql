@@ -0,0 +1,3 @@
+-- Line-item properties: customizations chosen in the cart (engraving text, gift message, add-ons)
+-- kept on the order item so fulfillment sees them
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS properties JSONB;
@@ -0,0 +1,55 @@
+-- Outbound order integrations: tenants' ERP endpoints with payload templates, and the outbox
+-- of timeline events to deliver to them, kept as the delivery log
+CREATE TABLE IF NOT EXISTS order_integrations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    target VARCHAR(30) NOT NULL DEFAULT 'custom',
+    endpoint_url VARCHAR(1000) NOT NULL,
+    http_method VARCHAR(10) NOT NULL DEFAULT 'POST',
+    content_type VARCHAR(100) NOT NULL DEFAULT 'application/json',
+    events TEXT[],
+    template_engine VARCHAR(20) NOT NULL DEFAULT 'go_template',
+    template TEXT NOT NULL,
+    auth_header_name VARCHAR(100),
+    auth_header_value TEXT,
+    signing_secret TEXT,
+    max_attempts INTEGER DEFAULT 10,
+    timeout_seconds INTEGER DEFAULT 15,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_integrations_tenant ON order_integrations(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_order_integrations_deleted_at ON order_integrations(deleted_at);
+
+CREATE TABLE IF NOT EXISTS order_integration_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    integration_id UUID NOT NULL,
+    order_id UUID NOT NULL,
+    timeline_id UUID,
+    event VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ,
+    replay_of UUID,
+    request_body TEXT,
+    response_status INTEGER,
+    response_body TEXT,
+    error TEXT,
+    last_attempt_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_integration_deliveries_tenant ON order_integration_deliveries(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_order_integration_deliveries_integration ON order_integration_deliveries(integration_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_order_integration_deliveries_order ON order_integration_deliveries(order_id);
+CREATE INDEX IF NOT EXISTS idx_order_integration_deliveries_due ON order_integration_deliveries(status, next_attempt_at);
@@ -0,0 +1,28 @@
+-- Stuck order watchdog: when an order was first dispatched, and the alerts raised for orders
+-- stuck past their thresholds together with the ops ticket opened for each
+ALTER TABLE order_shippings ADD COLUMN IF NOT EXISTS shipped_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS stuck_order_alerts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    order_id UUID NOT NULL,
+    order_number VARCHAR(100),
+    reason VARCHAR(40) NOT NULL,
+    status VARCHAR(20),
+    fulfillment_status VARCHAR(30),
+    stuck_since TIMESTAMPTZ NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL,
+    ticket_id VARCHAR(255),
+    ticket_number VARCHAR(100),
+    escalation_attempts INTEGER DEFAULT 0,
+    escalation_error TEXT,
+    escalated_at TIMESTAMPTZ,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_stuck_order_alerts_tenant ON stuck_order_alerts(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_stuck_order_alerts_resolved ON stuck_order_alerts(resolved_at);
+-- One open alert per order and reason
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stuck_order_alerts_open ON stuck_order_alerts(tenant_id, order_id, reason) WHERE resolved_at IS NULL;
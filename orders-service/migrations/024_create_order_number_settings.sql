@@ -0,0 +1,31 @@
+-- Per-tenant order number formats and the sequences they draw from. Tenants without settings
+-- keep the default ORD-<timestamp> numbers.
+CREATE TABLE IF NOT EXISTS order_number_settings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    prefix VARCHAR(20) DEFAULT 'ORD-',
+    storefront_prefixes JSONB DEFAULT '{}',
+    date_format VARCHAR(10) DEFAULT '',
+    separator VARCHAR(1) DEFAULT '-',
+    sequence_padding INTEGER DEFAULT 6,
+    start_sequence BIGINT DEFAULT 1,
+    reset_period VARCHAR(10) DEFAULT 'never',
+    gap_free BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_number_settings_tenant ON order_number_settings(tenant_id);
+
+-- Last number issued per prefix and reset period, e.g. scope 'INV-|2026'
+CREATE TABLE IF NOT EXISTS order_number_sequences (
+    tenant_id VARCHAR(255) NOT NULL,
+    scope VARCHAR(100) NOT NULL,
+    last_value BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ,
+    PRIMARY KEY (tenant_id, scope)
+);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS storefront_id VARCHAR(255);
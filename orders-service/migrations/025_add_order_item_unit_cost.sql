@@ -0,0 +1,3 @@
+-- Product cost per unit when ordered, snapshotted from products-service for gross margin
+-- reporting. Items ordered before this, or for products without a cost, stay NULL.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS unit_cost DECIMAL(12,4);
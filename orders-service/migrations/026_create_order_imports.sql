@@ -0,0 +1,50 @@
+-- Order imports: historical orders from previous platforms and offline sales, uploaded as
+-- CSV/XLSX and processed in the background. Imported orders are flagged with their job so
+-- payment, fulfillment and automation triggers skip them.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS import_job_id UUID;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS import_source VARCHAR(50);
+CREATE INDEX IF NOT EXISTS idx_orders_import_job_id ON orders(import_job_id);
+
+CREATE TABLE IF NOT EXISTS order_import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    file_name VARCHAR(255) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    file_data BYTEA,
+    column_mapping JSONB,
+    source VARCHAR(50) NOT NULL,
+    storefront_id VARCHAR(255),
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    create_customers BOOLEAN DEFAULT FALSE,
+    total_rows INTEGER DEFAULT 0,
+    next_row INTEGER DEFAULT 0,
+    created_count INTEGER DEFAULT 0,
+    skipped_count INTEGER DEFAULT 0,
+    failed_count INTEGER DEFAULT 0,
+    last_error TEXT,
+    attempts INTEGER DEFAULT 0,
+    started_at TIMESTAMPTZ,
+    heartbeat_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    created_by VARCHAR(255),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_import_jobs_tenant ON order_import_jobs(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_order_import_jobs_status ON order_import_jobs(status);
+
+CREATE TABLE IF NOT EXISTS order_import_errors (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL,
+    tenant_id VARCHAR(255) NOT NULL,
+    row_number INTEGER NOT NULL,
+    order_number VARCHAR(100),
+    column_name VARCHAR(100),
+    code VARCHAR(50) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_import_errors_job_id ON order_import_errors(job_id);
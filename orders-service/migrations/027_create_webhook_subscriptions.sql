@@ -0,0 +1,52 @@
+-- Order lifecycle webhooks: tenants' subscriptions, and the outbox of events to send them,
+-- kept as the delivery log
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    url VARCHAR(1000) NOT NULL,
+    events TEXT[],
+    secret TEXT NOT NULL,
+    max_attempts INTEGER DEFAULT 8,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_tenant ON webhook_subscriptions(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_deleted_at ON webhook_subscriptions(deleted_at);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    subscription_id UUID NOT NULL,
+    order_id UUID NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ,
+    replay_of UUID,
+    payload TEXT,
+    response_status INTEGER,
+    response_body TEXT,
+    error TEXT,
+    last_attempt_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_tenant ON webhook_deliveries(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_order ON webhook_deliveries(order_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+
+-- Each lifecycle event is queued once per order and subscription; a status set twice (or an
+-- order marked shipped both ways) doesn't resend it. Refunds can happen more than once, and
+-- replays are new deliveries of the same event.
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_webhook_deliveries_event ON webhook_deliveries(subscription_id, order_id, event)
+    WHERE replay_of IS NULL AND event <> 'order.refunded';
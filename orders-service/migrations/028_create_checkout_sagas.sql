@@ -0,0 +1,23 @@
+-- Checkout sagas: order creation, stock holds in inventory-service and payment intents in
+-- payment-service, with the step each checkout is on so failed calls can be retried or undone
+CREATE TABLE IF NOT EXISTS checkout_sagas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    order_id UUID NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    payment JSONB,
+    payment_intent JSONB,
+    hold_expires_at TIMESTAMPTZ,
+    attempts INTEGER DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ,
+    last_error TEXT,
+    failure_reason TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkout_sagas_tenant ON checkout_sagas(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_checkout_sagas_due ON checkout_sagas(status, next_attempt_at);
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_checkout_sagas_order ON checkout_sagas(order_id);
@@ -0,0 +1,6 @@
+-- Itemized return refunds: fees, return shipping deductions and the payments and gift cards
+-- each refund was split across
+ALTER TABLE returns ADD COLUMN IF NOT EXISTS refund_breakdown JSONB;
+
+ALTER TABLE return_policies ADD COLUMN IF NOT EXISTS return_shipping_fee DECIMAL(10,2) DEFAULT 0;
+ALTER TABLE return_policies ADD COLUMN IF NOT EXISTS waive_fees_for_merchant_fault BOOLEAN DEFAULT TRUE;
@@ -0,0 +1,20 @@
+-- Idempotency records: the response to each order creation request sent with an
+-- Idempotency-Key, replayed to retries until it expires. Used when Redis is unavailable.
+CREATE TABLE IF NOT EXISTS idempotency_records (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    scope VARCHAR(255) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    completed BOOLEAN DEFAULT FALSE,
+    status_code INTEGER,
+    content_type VARCHAR(255),
+    body BYTEA,
+    locked_until TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_idempotency_records_key ON idempotency_records(tenant_id, scope, key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_records_expires ON idempotency_records(expires_at);
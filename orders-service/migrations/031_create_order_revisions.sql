@@ -0,0 +1,29 @@
+-- Order revisions: each staff edit of a placed order's items, with the totals before and
+-- after and the charge or refund made for the difference
+CREATE TABLE IF NOT EXISTS order_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    changes JSONB,
+    reason TEXT,
+    previous_subtotal DECIMAL(10,2),
+    previous_tax_amount DECIMAL(10,2),
+    previous_total DECIMAL(10,2),
+    new_subtotal DECIMAL(10,2),
+    new_tax_amount DECIMAL(10,2),
+    new_total DECIMAL(10,2),
+    payment_delta DECIMAL(10,2),
+    payment_action VARCHAR(20) NOT NULL,
+    payment_status VARCHAR(20) NOT NULL,
+    payment_reference TEXT,
+    payment_intent JSONB,
+    payment_error TEXT,
+    customer_notified BOOLEAN DEFAULT FALSE,
+    edited_by UUID,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_order_revisions_order_revision ON order_revisions(order_id, revision);
+CREATE INDEX IF NOT EXISTS idx_order_revisions_tenant ON order_revisions(tenant_id);
@@ -0,0 +1,40 @@
+-- Notification routing: per tenant, the channels each customer order notification is sent on
+-- and whether rapid successive notifications are combined into a digest
+CREATE TABLE IF NOT EXISTS notification_routing_settings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    routes JSONB DEFAULT '{}',
+    digest_enabled BOOLEAN DEFAULT FALSE,
+    digest_window_minutes INTEGER DEFAULT 10,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_routing_settings_tenant ON notification_routing_settings(tenant_id);
+
+-- Order notification log: every notification sent, skipped or queued for a digest, per channel
+CREATE TABLE IF NOT EXISTS order_notification_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    order_id UUID NOT NULL,
+    order_number VARCHAR(50),
+    event VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    recipient VARCHAR(255),
+    status VARCHAR(20) NOT NULL,
+    send_after TIMESTAMPTZ,
+    locked_until TIMESTAMPTZ,
+    digest_id UUID,
+    payload JSONB,
+    error TEXT,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_notification_logs_tenant ON order_notification_logs(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_order_notification_logs_order ON order_notification_logs(order_id);
+CREATE INDEX IF NOT EXISTS idx_order_notification_logs_recipient ON order_notification_logs(recipient);
+CREATE INDEX IF NOT EXISTS idx_order_notification_logs_due ON order_notification_logs(status, send_after);
@@ -0,0 +1,38 @@
+-- Partial fulfillments: an order shipped in several parcels has one fulfillment per parcel,
+-- each with its own tracking, and each item records how much of it has gone out
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS fulfilled_quantity INTEGER DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS fulfillment_status VARCHAR(30) DEFAULT 'UNFULFILLED';
+
+CREATE TABLE IF NOT EXISTS order_fulfillments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    carrier TEXT,
+    tracking_number TEXT,
+    tracking_url TEXT,
+    shipment_id TEXT,
+    label_url TEXT,
+    notes TEXT,
+    created_by UUID,
+    shipped_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_order_fulfillments_order_number ON order_fulfillments(order_id, number);
+CREATE INDEX IF NOT EXISTS idx_order_fulfillments_tenant ON order_fulfillments(tenant_id);
+
+CREATE TABLE IF NOT EXISTS order_fulfillment_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    fulfillment_id UUID NOT NULL REFERENCES order_fulfillments(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL,
+    product_name TEXT,
+    sku TEXT,
+    quantity INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_fulfillment_items_fulfillment_id ON order_fulfillment_items(fulfillment_id);
+CREATE INDEX IF NOT EXISTS idx_order_fulfillment_items_order_item_id ON order_fulfillment_items(order_item_id);
@@ -0,0 +1,3 @@
+-- Return labels: the shipping-service shipment booked for a return's parcel when its label is
+-- generated on approval (carrier, tracking and label URL already live on returns)
+ALTER TABLE returns ADD COLUMN IF NOT EXISTS return_shipment_id VARCHAR(255);
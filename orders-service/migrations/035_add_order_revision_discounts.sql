@@ -0,0 +1,4 @@
+-- Order revisions: the discount before and after an edit, now that percentage coupons are
+-- recalculated against the edited subtotal
+ALTER TABLE order_revisions ADD COLUMN IF NOT EXISTS previous_discount_amount DECIMAL(10,2);
+ALTER TABLE order_revisions ADD COLUMN IF NOT EXISTS new_discount_amount DECIMAL(10,2);
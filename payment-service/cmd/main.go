@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,14 +18,18 @@ import (
 	"github.com/Tesseract-Nexus/go-shared/rbac"
 	"payment-service/internal/clients"
 	"payment-service/internal/config"
+	"payment-service/internal/database"
+	"payment-service/internal/encryption"
 	"payment-service/internal/gateway"
 	"payment-service/internal/handlers"
 	"payment-service/internal/middleware"
 	"payment-service/internal/models"
 	"payment-service/internal/repository"
+	"payment-service/internal/serviceauth"
 	"payment-service/internal/services"
 	"payment-service/internal/events"
 	"payment-service/internal/subscribers"
+	"payment-service/internal/tenancy"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -32,33 +39,36 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Connect to database
-	db, err := connectDatabase(cfg.DatabaseURL)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return connectDatabase(cfg.DatabaseURL) })
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Auto-migrate models
-	if err := db.AutoMigrate(
-		&models.PaymentGatewayConfig{},
-		&models.PaymentTransaction{},
-		&models.RefundTransaction{},
-		&models.WebhookEvent{},
-		&models.SavedPaymentMethod{},
-		&models.GatewayCustomer{},
-		&models.PaymentDispute{},
-		&models.PaymentSettings{},
-		&models.PlatformFeeLedger{},
-		&models.PaymentGatewayRegion{},
-		&models.PaymentGatewayTemplate{},
-		// Ad billing models
-		&models.AdCommissionTier{},
-		&models.AdCampaignPayment{},
-		&models.AdBillingInvoice{},
-		&models.AdRevenueLedger{},
-		&models.AdVendorBalance{},
-	); err != nil {
-		log.Printf("Warning: Auto-migration failed: %v", err)
+	// "payment-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
+	}
+
+	// Per-tenant encryption of gateway secrets (disabled, i.e. plaintext, until a key is configured)
+	keyring, err := initFieldEncryption(cfg, db)
+	if err != nil {
+		log.Fatalf("Failed to initialize field encryption: %v", err)
+	}
+	if keyring != nil {
+		defer keyring.Close()
+		log.Println("✓ Field encryption enabled")
+	} else {
+		log.Println("WARNING: FIELD_ENCRYPTION_KMS_KEY not set, gateway secrets are stored unencrypted")
 	}
 
 	// Seed gateway templates (idempotent - safe to run multiple times)
@@ -142,6 +152,7 @@ func main() {
 		paymentService = services.NewPaymentService(paymentRepo, notificationClient, tenantClient)
 		log.Println("✓ PaymentService initialized with static credentials")
 	}
+	paymentService.SetGiftCardClient(clients.NewGiftCardClient())
 	webhookService := services.NewWebhookService(paymentRepo, notificationClient, tenantClient)
 	platformFeeService := services.NewPlatformFeeService(db, paymentRepo)
 
@@ -169,6 +180,7 @@ func main() {
 	gatewayHandler := handlers.NewGatewayHandler(gatewaySelectorService, platformFeeService)
 	approvalGatewayHandler := handlers.NewApprovalGatewayHandler(paymentRepo, gatewaySelectorService, approvalClient)
 	adBillingHandler := handlers.NewAdBillingHandler(adBillingService)
+	gatewayPropagationHandler := handlers.NewGatewayPropagationHandler(services.NewGatewayPropagationService(paymentRepo))
 
 	// Initialize credentials handler (only if credentials service is available)
 	var credentialsHandler *handlers.CredentialsHandler
@@ -197,8 +209,38 @@ func main() {
 	} else {
 		defer eventsPublisher.Close()
 		log.Println("✓ NATS events publisher initialized")
+		// Campaign budget exhaustion pauses campaigns through these events
+		adBillingService.SetEventsPublisher(eventsPublisher)
 	}
 
+	// Initialize payment link service (links from checkout and webhooks settle through it)
+	paymentLinkService := services.NewPaymentLinkService(db, paymentService, tenantClient, eventsPublisher, cfg.PaymentLinkSigningSecret)
+	paymentService.SetPaymentLinkService(paymentLinkService)
+	webhookService.SetPaymentLinkService(paymentLinkService)
+	paymentLinkHandler := handlers.NewPaymentLinkHandler(paymentLinkService)
+	if cfg.PaymentLinkSigningSecret == "" {
+		log.Println("WARNING: PAYMENT_LINK_SIGNING_SECRET not set, payment links are disabled")
+	} else {
+		log.Println("✓ Payment link service initialized")
+	}
+
+	// Initialize terminal service for in-person (Stripe Terminal / Razorpay POS) payments
+	terminalService := services.NewTerminalService(db, paymentService)
+	terminalHandler := handlers.NewTerminalHandler(terminalService)
+	log.Println("✓ Terminal service initialized")
+
+	// Initialize vendor payout service (holds and clawbacks for chargebacks and returns)
+	vendorPayoutService := services.NewVendorPayoutService(db, paymentRepo)
+	paymentService.SetVendorPayoutService(vendorPayoutService)
+	webhookService.SetVendorPayoutService(vendorPayoutService)
+	vendorPayoutHandler := handlers.NewVendorPayoutHandler(vendorPayoutService)
+	log.Println("✓ Vendor payout service initialized")
+
+	// Initialize commission invoice service (monthly invoices to vendors for platform fees)
+	commissionInvoiceService := services.NewCommissionInvoiceService(db, clients.NewVendorClient())
+	commissionInvoiceHandler := handlers.NewCommissionInvoiceHandler(commissionInvoiceService)
+	log.Println("✓ Commission invoice service initialized")
+
 	// Initialize approval event subscriber
 	subscriberLogger := logrus.New()
 	subscriberLogger.SetFormatter(&logrus.JSONFormatter{})
@@ -229,14 +271,113 @@ func main() {
 		log.Println("✓ Payment config subscriber started (syncing from orders-service)")
 	}
 
+	// Enforce webhook event retention (policies are configured per tenant in staff-service)
+	webhookRetentionWorker := services.NewWebhookRetentionWorker(db, clients.NewRetentionClient())
+	go webhookRetentionWorker.Start()
+	log.Println("✓ Webhook retention worker started")
+
+	var paymentLinkExpiryWorker *services.PaymentLinkExpiryWorker
+	if cfg.PaymentLinkSigningSecret != "" {
+		paymentLinkExpiryWorker = services.NewPaymentLinkExpiryWorker(paymentLinkService)
+		go paymentLinkExpiryWorker.Start()
+		log.Println("✓ Payment link expiry worker started")
+	}
+
+	// Time out UPI intent/QR/collect payments the customer never completed
+	upiPaymentTimeoutWorker := services.NewUPIPaymentTimeoutWorker(paymentService)
+	go upiPaymentTimeoutWorker.Start()
+	log.Println("✓ UPI payment timeout worker started")
+
+	// Invoice vendors for last month's commission once the month has ended
+	commissionInvoiceWorker := services.NewCommissionInvoiceWorker(commissionInvoiceService)
+	go commissionInvoiceWorker.Start()
+	log.Println("✓ Commission invoice worker started")
+
+	var keyRotationWorker *services.KeyRotationWorker
+	if keyring != nil {
+		keyRotationWorker = services.NewKeyRotationWorker(db, keyring, cfg.FieldEncryptionRotationAge, &models.PaymentGatewayConfig{})
+		go keyRotationWorker.Start()
+		log.Println("✓ Key rotation worker started")
+	}
+
 	// Setup router
-	router := setupRouter(paymentHandler, webhookHandler, gatewayHandler, approvalGatewayHandler, adBillingHandler, credentialsHandler, rbacMiddleware)
+	router := setupRouter(paymentHandler, webhookHandler, gatewayHandler, approvalGatewayHandler, adBillingHandler, credentialsHandler, paymentLinkHandler, terminalHandler, vendorPayoutHandler, commissionInvoiceHandler, gatewayPropagationHandler, rbacMiddleware)
 
 	// Start server
-	log.Printf("Payment Service starting on port %s (env: %s)", cfg.Port, cfg.Environment)
-	if err := router.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: router,
+	}
+	go func() {
+		log.Printf("Payment Service starting on port %s (env: %s)", cfg.Port, cfg.Environment)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down payment-service...")
+
+	// Stop accepting connections and let in-flight requests finish before stopping workers
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
+	// Stop background workers
+	webhookRetentionWorker.Stop()
+	upiPaymentTimeoutWorker.Stop()
+	commissionInvoiceWorker.Stop()
+	if paymentLinkExpiryWorker != nil {
+		paymentLinkExpiryWorker.Stop()
 	}
+	if keyRotationWorker != nil {
+		keyRotationWorker.Stop()
+	}
+	log.Println("✓ Background workers stopped")
+
+	if approvalSubscriber != nil {
+		approvalSubscriber.Stop()
+	}
+	if paymentConfigSubscriber != nil {
+		paymentConfigSubscriber.Stop()
+	}
+	log.Println("✓ Event subscribers stopped")
+
+	log.Println("Payment service stopped")
+}
+
+// initFieldEncryption configures the encrypted GORM serializer. It returns a nil keyring
+// when no key is configured, in which case encrypted fields are written in plaintext.
+func initFieldEncryption(cfg *config.Config, db *gorm.DB) (*encryption.Keyring, error) {
+	var wrapper encryption.KeyWrapper
+	switch {
+	case cfg.FieldEncryptionKMSKey != "":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		kmsWrapper, err := encryption.NewKMSKeyWrapper(ctx, cfg.FieldEncryptionKMSKey)
+		if err != nil {
+			return nil, err
+		}
+		wrapper = kmsWrapper
+	case cfg.FieldEncryptionLocalKey != "" && cfg.Environment != "production":
+		localWrapper, err := encryption.NewLocalKeyWrapper(cfg.FieldEncryptionLocalKey)
+		if err != nil {
+			return nil, err
+		}
+		wrapper = localWrapper
+	default:
+		return nil, nil
+	}
+
+	keyring := encryption.NewKeyring(db, wrapper)
+	encryption.Configure(keyring)
+	return keyring, nil
 }
 
 // connectDatabase establishes a connection to the database
@@ -252,6 +393,11 @@ func connectDatabase(databaseURL string) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
+	}
+
 	// Test connection
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -267,7 +413,7 @@ func connectDatabase(databaseURL string) (*gorm.DB, error) {
 }
 
 // setupRouter configures the HTTP router
-func setupRouter(paymentHandler *handlers.PaymentHandler, webhookHandler *handlers.WebhookHandler, gatewayHandler *handlers.GatewayHandler, approvalGatewayHandler *handlers.ApprovalGatewayHandler, adBillingHandler *handlers.AdBillingHandler, credentialsHandler *handlers.CredentialsHandler, rbacMw *rbac.Middleware) *gin.Engine {
+func setupRouter(paymentHandler *handlers.PaymentHandler, webhookHandler *handlers.WebhookHandler, gatewayHandler *handlers.GatewayHandler, approvalGatewayHandler *handlers.ApprovalGatewayHandler, adBillingHandler *handlers.AdBillingHandler, credentialsHandler *handlers.CredentialsHandler, paymentLinkHandler *handlers.PaymentLinkHandler, terminalHandler *handlers.TerminalHandler, vendorPayoutHandler *handlers.VendorPayoutHandler, commissionInvoiceHandler *handlers.CommissionInvoiceHandler, gatewayPropagationHandler *handlers.GatewayPropagationHandler, rbacMw *rbac.Middleware) *gin.Engine {
 	router := gin.Default()
 
 	// Initialize rate limiters
@@ -302,6 +448,7 @@ func setupRouter(paymentHandler *handlers.PaymentHandler, webhookHandler *handle
 		AllowLegacyHeaders: true,  // Allow X-Tenant-ID fallback during migration
 		SkipPaths: []string{
 			"/health",
+			"/metrics",
 			"/webhooks/",
 		},
 	}))
@@ -326,6 +473,9 @@ func setupRouter(paymentHandler *handlers.PaymentHandler, webhookHandler *handle
 		})
 	})
 
+	// Prometheus metrics, including event consumer lag
+	router.GET("/metrics", gosharedmw.Handler())
+
 	// API routes - require tenant ID for all API endpoints
 	v1 := router.Group("/api/v1")
 	v1.Use(middleware.RequireTenantID())
@@ -339,6 +489,7 @@ func setupRouter(paymentHandler *handlers.PaymentHandler, webhookHandler *handle
 				middleware.RateLimitMiddleware(rateLimits.CreatePayment, "tenant"),
 				paymentHandler.CreatePaymentIntent)
 			payments.POST("/confirm", paymentHandler.ConfirmPayment)
+			payments.GET("/:id/upi-status", paymentHandler.GetUPIPaymentStatus)
 
 			// Admin read routes - require payments:read permission
 			// Allow internal service calls for by-gateway-id (used by storefront BFF for success page)
@@ -352,8 +503,83 @@ func setupRouter(paymentHandler *handlers.PaymentHandler, webhookHandler *handle
 				rbacMw.RequirePermission(rbac.PermissionPaymentsRefund),
 				middleware.RateLimitMiddleware(rateLimits.RefundRequest, "tenant"),
 				paymentHandler.CreateRefund)
+
+			// Settlement report imports - internal reconciliation jobs or payments:fees:manage
+			payments.POST("/:id/settlement", rbacMw.RequirePermissionAllowInternal(rbac.PermissionPaymentsFeesManage), paymentHandler.RecordSettlement)
+		}
+
+		// Refunds and their fallback chain (original tender -> store credit -> bank transfer)
+		refunds := v1.Group("/refunds")
+		{
+			refunds.GET("/:id", rbacMw.RequirePermission(rbac.PermissionPaymentsRead), paymentHandler.GetRefund)
+			refunds.POST("/:id/bank-transfer", rbacMw.RequirePermission(rbac.PermissionPaymentsRefund), paymentHandler.RecordBankTransfer)
+			// Split refunds for completed returns, called by orders-service
+			refunds.POST("/plans", serviceauth.New(serviceauth.ConfigFromEnv("payment-service"), nil).Require("orders-service"), paymentHandler.ExecuteRefundPlan)
+		}
+
+		// Payment links for invoices and manual orders (admin)
+		paymentLinks := v1.Group("/payment-links")
+		{
+			paymentLinks.GET("", rbacMw.RequirePermission(rbac.PermissionPaymentsRead), paymentLinkHandler.ListPaymentLinks)
+			paymentLinks.GET("/:id", rbacMw.RequirePermission(rbac.PermissionPaymentsRead), paymentLinkHandler.GetPaymentLink)
+			paymentLinks.POST("",
+				rbacMw.RequirePermission(rbac.PermissionPaymentsRefund),
+				middleware.RateLimitMiddleware(rateLimits.CreatePayment, "tenant"),
+				paymentLinkHandler.CreatePaymentLink)
+			paymentLinks.POST("/:id/cancel", rbacMw.RequirePermission(rbac.PermissionPaymentsRefund), paymentLinkHandler.CancelPaymentLink)
 		}
 
+		// Hosted payment link page - storefront routes (no RBAC, access is by signed link)
+		publicPaymentLinks := v1.Group("/public/payment-links")
+		{
+			publicPaymentLinks.GET("/:code", paymentLinkHandler.ResolvePaymentLink)
+			publicPaymentLinks.POST("/:code/pay",
+				middleware.RateLimitMiddleware(rateLimits.CreatePayment, "tenant"),
+				paymentLinkHandler.PayPaymentLink)
+		}
+
+		// In-person payments on store card readers / POS devices
+		terminal := v1.Group("/terminal")
+		{
+			// Reader management - require payments:gateway permissions
+			terminal.GET("/readers", rbacMw.RequirePermission(rbac.PermissionPaymentsGatewayRead), terminalHandler.ListReaders)
+			terminal.POST("/readers", rbacMw.RequirePermission(rbac.PermissionPaymentsGatewayManage), terminalHandler.RegisterReader)
+			terminal.DELETE("/readers/:id", rbacMw.RequirePermission(rbac.PermissionPaymentsGatewayManage), terminalHandler.DeleteReader)
+
+			// Point of sale - store staff ringing up orders
+			terminal.POST("/connection-tokens", rbacMw.RequirePermission(rbac.PermissionOrdersCreate), terminalHandler.CreateConnectionToken)
+			terminal.POST("/payments",
+				rbacMw.RequirePermission(rbac.PermissionOrdersCreate),
+				middleware.RateLimitMiddleware(rateLimits.CreatePayment, "tenant"),
+				terminalHandler.CreatePayment)
+			terminal.POST("/payments/:id/capture", rbacMw.RequirePermission(rbac.PermissionOrdersCreate), terminalHandler.CapturePayment)
+			terminal.POST("/payments/:id/cancel", rbacMw.RequirePermission(rbac.PermissionOrdersCreate), terminalHandler.CancelPayment)
+		}
+
+		// Vendor payout holds and clawbacks for chargebacks and returns - require payments:read permission
+		vendorPayouts := v1.Group("/vendor-payouts")
+		{
+			vendorPayouts.GET("/vendors/:vendorId/statement", rbacMw.RequirePermission(rbac.PermissionPaymentsRead), vendorPayoutHandler.GetStatement)
+		}
+
+		// Marketplace commission invoices to vendors
+		commissionInvoices := v1.Group("/commission-invoices")
+		{
+			// Issuer, tax and numbering settings and invoicing runs - require payments:fees:manage permission
+			commissionInvoices.GET("/settings", rbacMw.RequirePermission(rbac.PermissionPaymentsFeesManage), commissionInvoiceHandler.GetSettings)
+			commissionInvoices.PUT("/settings", rbacMw.RequirePermission(rbac.PermissionPaymentsFeesManage), commissionInvoiceHandler.UpdateSettings)
+			commissionInvoices.POST("/generate", rbacMw.RequirePermission(rbac.PermissionPaymentsFeesManage), commissionInvoiceHandler.GenerateInvoices)
+			commissionInvoices.GET("", rbacMw.RequirePermission(rbac.PermissionPaymentsFeesManage), commissionInvoiceHandler.ListInvoices)
+
+			// Vendor access to their own invoices - require payments:read permission
+			commissionInvoices.GET("/vendors/:vendorId", rbacMw.RequirePermission(rbac.PermissionPaymentsRead), commissionInvoiceHandler.ListVendorInvoices)
+			commissionInvoices.GET("/:id", rbacMw.RequirePermission(rbac.PermissionPaymentsRead), commissionInvoiceHandler.GetInvoice)
+			commissionInvoices.GET("/:id/pdf", rbacMw.RequirePermission(rbac.PermissionPaymentsRead), commissionInvoiceHandler.DownloadInvoicePDF)
+		}
+
+		// Reconciliation of in-person and online payments - require payments:read permission
+		v1.GET("/payment-reconciliation", rbacMw.RequirePermission(rbac.PermissionPaymentsRead), terminalHandler.GetReconciliation)
+
 		// Order payments - require payments:read permission
 		v1.GET("/orders/:orderId/payments", rbacMw.RequirePermission(rbac.PermissionPaymentsRead), paymentHandler.ListPaymentsByOrder)
 
@@ -366,6 +592,7 @@ func setupRouter(paymentHandler *handlers.PaymentHandler, webhookHandler *handle
 			gatewayConfigs.GET("/templates", rbacMw.RequirePermission(rbac.PermissionPaymentsGatewayRead), gatewayHandler.GetGatewayTemplates)
 			gatewayConfigs.GET("/:id/regions", rbacMw.RequirePermission(rbac.PermissionPaymentsGatewayRead), gatewayHandler.GetGatewayRegions)
 			gatewayConfigs.GET("/pending-approvals", rbacMw.RequirePermission(rbac.PermissionPaymentsGatewayRead), approvalGatewayHandler.GetPendingGatewayApprovals)
+			gatewayConfigs.GET("/drift", rbacMw.RequirePermission(rbac.PermissionPaymentsGatewayRead), gatewayPropagationHandler.GetGatewayDrift)
 
 			// Management operations - require payments:gateway:manage permission
 			// These operations require owner approval (handled by approval-aware handlers)
@@ -375,9 +602,11 @@ func setupRouter(paymentHandler *handlers.PaymentHandler, webhookHandler *handle
 			gatewayConfigs.POST("/from-template/:gatewayType", rbacMw.RequirePermission(rbac.PermissionPaymentsGatewayManage), approvalGatewayHandler.CreateGatewayFromTemplateWithApproval)
 			gatewayConfigs.POST("/validate", rbacMw.RequirePermission(rbac.PermissionPaymentsGatewayManage), gatewayHandler.ValidateGatewayCredentials)
 			gatewayConfigs.POST("/:id/regions", rbacMw.RequirePermission(rbac.PermissionPaymentsGatewayManage), gatewayHandler.CreateGatewayRegion)
+			// Applying a tenant-level config to storefronts is limited to owners in the handler
+			gatewayConfigs.POST("/:id/propagate", rbacMw.RequirePermission(rbac.PermissionPaymentsGatewayManage), gatewayPropagationHandler.PropagateGatewayConfig)
 
-			// Approval callback endpoint for processing approved requests
-			gatewayConfigs.POST("/approval-callback", approvalGatewayHandler.HandleApprovalCallback)
+			// Approval callback endpoint for processing approved requests, called by approval-service
+			gatewayConfigs.POST("/approval-callback", serviceauth.New(serviceauth.ConfigFromEnv("payment-service"), nil).Require("approval-service"), approvalGatewayHandler.HandleApprovalCallback)
 		}
 
 		// Gateway selection and payment methods
@@ -435,10 +664,16 @@ func setupRouter(paymentHandler *handlers.PaymentHandler, webhookHandler *handle
 			// Campaign payment lookup - requires ads:billing:view permission
 			adBilling.GET("/campaigns/:campaignId/payment", rbacMw.RequirePermission(rbac.PermissionAdsBillingView), adBillingHandler.GetPaymentByCampaign)
 
+			// Budget pacing - spend is reported by the ads system, top-ups require ads:billing:manage
+			adBilling.GET("/campaigns/:campaignId/budget", rbacMw.RequirePermissionAllowInternal(rbac.PermissionAdsBillingView), adBillingHandler.GetCampaignBudget)
+			adBilling.POST("/campaigns/:campaignId/spend", rbacMw.RequirePermissionAllowInternal(rbac.PermissionAdsBillingManage), adBillingHandler.RecordCampaignSpend)
+			adBilling.POST("/campaigns/:campaignId/top-up", rbacMw.RequirePermission(rbac.PermissionAdsBillingManage), adBillingHandler.TopUpCampaignBudget)
+
 			// Vendor billing - requires ads:billing:view permission
 			adBilling.GET("/vendors/:vendorId/billing", rbacMw.RequirePermission(rbac.PermissionAdsBillingView), adBillingHandler.GetVendorBilling)
 			adBilling.GET("/vendors/:vendorId/balance", rbacMw.RequirePermission(rbac.PermissionAdsBillingView), adBillingHandler.GetVendorBalance)
 			adBilling.GET("/vendors/:vendorId/ledger", rbacMw.RequirePermission(rbac.PermissionAdsBillingView), adBillingHandler.GetVendorLedger)
+			adBilling.GET("/vendors/:vendorId/budgets", rbacMw.RequirePermission(rbac.PermissionAdsBillingView), adBillingHandler.GetVendorBudgets)
 
 			// Revenue reporting - requires ads:revenue:view permission (platform admin)
 			adBilling.GET("/revenue", rbacMw.RequirePermission(rbac.PermissionAdsRevenueView), adBillingHandler.GetTenantAdRevenue)
@@ -0,0 +1,136 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"payment-service/internal/serviceauth"
+)
+
+// GiftCardClient issues store credit as gift cards through gift-cards-service
+type GiftCardClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// StoreCreditRequest is a gift card issued in place of a refund to the original tender
+type StoreCreditRequest struct {
+	InitialBalance float64                `json:"initialBalance"`
+	CurrencyCode   string                 `json:"currencyCode,omitempty"`
+	RecipientEmail *string                `json:"recipientEmail,omitempty"`
+	RecipientName  *string                `json:"recipientName,omitempty"`
+	SenderName     *string                `json:"senderName,omitempty"`
+	Message        *string                `json:"message,omitempty"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// StoreCredit is the gift card created for a store credit refund
+type StoreCredit struct {
+	ID             string  `json:"id"`
+	Code           string  `json:"code"`
+	InitialBalance float64 `json:"initialBalance"`
+	CurrencyCode   string  `json:"currencyCode"`
+}
+
+// NewGiftCardClient creates a new gift card client
+func NewGiftCardClient() *GiftCardClient {
+	baseURL := os.Getenv("GIFT_CARDS_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://gift-cards-service:8080"
+	}
+
+	return &GiftCardClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// IssueStoreCredit creates a gift card for the tenant; gift-cards-service emails the code
+// to the recipient
+func (c *GiftCardClient) IssueStoreCredit(ctx context.Context, tenantID string, credit *StoreCreditRequest) (*StoreCredit, error) {
+	body, err := json.Marshal(credit)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/internal/gift-cards", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	serviceauth.Sign(req, "payment-service")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", tenantID)
+	req.Header.Set("X-Internal-Service", "payment-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue store credit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("gift-cards-service returned status %d issuing store credit", resp.StatusCode)
+	}
+
+	var result struct {
+		Data StoreCredit `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode store credit: %w", err)
+	}
+	return &result.Data, nil
+}
+
+// giftCardRefundRequest puts an amount back on an existing gift card
+type giftCardRefundRequest struct {
+	Code    string  `json:"code"`
+	Amount  float64 `json:"amount"`
+	OrderID string  `json:"orderId,omitempty"`
+}
+
+// RefundToGiftCard puts amount back on the gift card with code, e.g. the part of a returned
+// order the card paid for. It returns the gift card's ID.
+func (c *GiftCardClient) RefundToGiftCard(ctx context.Context, tenantID, code string, amount float64, orderID string) (string, error) {
+	body, err := json.Marshal(giftCardRefundRequest{Code: code, Amount: amount, OrderID: orderID})
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/internal/gift-cards/refund", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	serviceauth.Sign(req, "payment-service")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", tenantID)
+	req.Header.Set("X-Internal-Service", "payment-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to refund gift card: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("gift-cards-service returned status %d refunding gift card", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode gift card: %w", err)
+	}
+	return result.Data.ID, nil
+}
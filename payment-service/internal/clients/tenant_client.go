@@ -147,6 +147,12 @@ func (c *TenantClient) BuildOrderDetailsURL(ctx context.Context, tenantID, order
 	return fmt.Sprintf("https://%s.%s/orders/%s", slug, c.baseDomain, orderID)
 }
 
+// BuildPaymentLinkURL builds the hosted payment page URL for a signed payment link
+func (c *TenantClient) BuildPaymentLinkURL(ctx context.Context, tenantID, code, signature string) string {
+	slug := c.GetTenantSlug(ctx, tenantID)
+	return fmt.Sprintf("https://%s.%s/pay/%s?sig=%s", slug, c.baseDomain, code, signature)
+}
+
 // BuildRetryPaymentURL builds the checkout URL for retrying a failed payment
 func (c *TenantClient) BuildRetryPaymentURL(ctx context.Context, tenantID, orderID string) string {
 	slug := c.GetTenantSlug(ctx, tenantID)
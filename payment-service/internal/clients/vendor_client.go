@@ -0,0 +1,118 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"payment-service/internal/serviceauth"
+)
+
+// VendorClient looks up marketplace vendors in vendor-service
+type VendorClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// VendorAddress is one of a vendor's addresses
+type VendorAddress struct {
+	AddressType  string  `json:"addressType"`
+	AddressLine1 string  `json:"addressLine1"`
+	AddressLine2 *string `json:"addressLine2,omitempty"`
+	City         string  `json:"city"`
+	State        string  `json:"state"`
+	PostalCode   string  `json:"postalCode"`
+	Country      string  `json:"country"`
+	IsDefault    bool    `json:"isDefault"`
+}
+
+// Vendor is the part of a vendor-service vendor needed to bill it
+type Vendor struct {
+	ID                      string          `json:"id"`
+	Name                    string          `json:"name"`
+	Email                   string          `json:"email"`
+	TaxIdentificationNumber *string         `json:"taxIdentificationNumber,omitempty"`
+	Addresses               []VendorAddress `json:"addresses,omitempty"`
+}
+
+// NewVendorClient creates a new vendor client
+func NewVendorClient() *VendorClient {
+	baseURL := os.Getenv("VENDOR_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://vendor-service:8080"
+	}
+
+	return &VendorClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetVendor returns the tenant's vendor with vendorID, including its addresses
+func (c *VendorClient) GetVendor(ctx context.Context, tenantID, vendorID string) (*Vendor, error) {
+	url := fmt.Sprintf("%s/internal/vendors/%s", c.baseURL, vendorID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	serviceauth.Sign(req, "payment-service")
+	req.Header.Set("X-Tenant-ID", tenantID)
+	req.Header.Set("X-Internal-Service", "payment-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vendor: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vendor-service returned status %d getting vendor %s", resp.StatusCode, vendorID)
+	}
+
+	var result struct {
+		Data Vendor `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode vendor: %w", err)
+	}
+	return &result.Data, nil
+}
+
+// BillingAddress returns the vendor's business address, falling back to its default address
+// and then to any address. It returns nil when the vendor has none.
+func (v *Vendor) BillingAddress() *VendorAddress {
+	var fallback *VendorAddress
+	for i := range v.Addresses {
+		a := &v.Addresses[i]
+		if strings.EqualFold(a.AddressType, "BUSINESS") {
+			return a
+		}
+		if fallback == nil || (a.IsDefault && !fallback.IsDefault) {
+			fallback = a
+		}
+	}
+	return fallback
+}
+
+// String formats the address on one line per part
+func (a *VendorAddress) String() string {
+	parts := []string{a.AddressLine1}
+	if a.AddressLine2 != nil && *a.AddressLine2 != "" {
+		parts = append(parts, *a.AddressLine2)
+	}
+	parts = append(parts, strings.TrimSpace(a.City+" "+a.PostalCode), a.State, a.Country)
+
+	lines := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			lines = append(lines, p)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
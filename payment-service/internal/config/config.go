@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets"
@@ -13,8 +14,9 @@ import (
 // Config holds all configuration for the payment service
 type Config struct {
 	// Server
-	Port        string
-	Environment string
+	Port            string
+	Environment     string
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
 
 	// Database
 	DatabaseURL string
@@ -56,6 +58,15 @@ type Config struct {
 	PayPalClientID     string
 	PayPalClientSecret string
 	PayPalMode         string // sandbox or live
+
+	// Field encryption: tenant data keys are wrapped with the Cloud KMS key. The local key
+	// (base64, 32 bytes) is only honoured outside production.
+	FieldEncryptionKMSKey      string
+	FieldEncryptionLocalKey    string
+	FieldEncryptionRotationAge time.Duration
+
+	// Payment links: HMAC secret used to sign hosted payment link URLs
+	PaymentLinkSigningSecret string
 }
 
 // buildDatabaseURL constructs the database URL from individual components
@@ -112,8 +123,9 @@ func getPasswordFromGCPOrEnv() string {
 // Load loads configuration from environment variables
 func Load() *Config {
 	config := &Config{
-		Port:        getEnv("PORT", "8092"),
-		Environment: getEnv("ENVIRONMENT", "devtest"),
+		Port:                   getEnv("PORT", "8092"),
+		Environment:            getEnv("ENVIRONMENT", "devtest"),
+		ShutdownTimeout:        getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
 		DatabaseURL:            buildDatabaseURL(),
 		RedisURL:               getEnv("REDIS_URL", "redis://redis.redis-marketplace.svc.cluster.local:6379/0"),
 		NotificationServiceURL: getEnv("NOTIFICATION_SERVICE_URL", "http://notification-service.marketplace.svc.cluster.local:8090"),
@@ -147,6 +159,14 @@ func Load() *Config {
 		PayPalClientID:     getEnv("PAYPAL_CLIENT_ID", ""),
 		PayPalClientSecret: getEnv("PAYPAL_CLIENT_SECRET", ""),
 		PayPalMode:         getEnv("PAYPAL_MODE", "sandbox"),
+
+		// Field encryption
+		FieldEncryptionKMSKey:      getEnv("FIELD_ENCRYPTION_KMS_KEY", ""),
+		FieldEncryptionLocalKey:    getEnv("FIELD_ENCRYPTION_LOCAL_KEY", ""),
+		FieldEncryptionRotationAge: getEnvDays("FIELD_ENCRYPTION_ROTATION_DAYS", 90),
+
+		// Payment links
+		PaymentLinkSigningSecret: getEnv("PAYMENT_LINK_SIGNING_SECRET", ""),
 	}
 
 	// Validate required fields
@@ -165,3 +185,23 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// getEnvDays reads a positive number of days from an environment variable
+func getEnvDays(key string, defaultDays int) time.Duration {
+	days := defaultDays
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			days = parsed
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
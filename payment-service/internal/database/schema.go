@@ -0,0 +1,48 @@
+package database
+
+import (
+	"gorm.io/gorm"
+
+	"payment-service/internal/encryption"
+	"payment-service/internal/models"
+)
+
+// serviceName names the service in the migration lock key and log messages
+const serviceName = "payment-service"
+
+// BaselineVersion is the last SQL migration folded into the model schema. Databases that
+// predate versioned migrations were built by AutoMigrate, and new databases get the same
+// schema from SyncModels, so only files numbered after it are applied from SQL.
+const BaselineVersion = 15
+
+// SyncModels brings the schema in line with the GORM models. It must only run while holding
+// the migration lock.
+func SyncModels(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&models.PaymentGatewayConfig{},
+		&models.PaymentTransaction{},
+		&models.RefundTransaction{},
+		&models.RefundTenderAttempt{},
+		&models.WebhookEvent{},
+		&models.SavedPaymentMethod{},
+		&models.GatewayCustomer{},
+		&models.PaymentDispute{},
+		&models.PaymentSettings{},
+		&models.PlatformFeeLedger{},
+		&models.PaymentGatewayRegion{},
+		&models.PaymentGatewayTemplate{},
+		// Ad billing models
+		&models.AdCommissionTier{},
+		&models.AdCampaignPayment{},
+		&models.AdBillingInvoice{},
+		&models.AdRevenueLedger{},
+		&models.AdVendorBalance{},
+		&models.AdCampaignBudget{},
+		&models.PaymentLink{},
+		&models.TerminalReader{},
+		&models.VendorPayoutLedger{},
+		&models.CommissionInvoiceSettings{},
+		&models.CommissionInvoice{},
+		&encryption.TenantDataKey{},
+	)
+}
@@ -0,0 +1,226 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the GORM serializer that encrypts a column with the row's tenant key:
+//
+//	Phone string `gorm:"type:text;serializer:encrypted"`
+//
+// String fields are stored as the encrypted string, other types as encrypted JSON. Values
+// are encrypted when written through a struct (Create, Save, Updates with a struct) and
+// decrypted when scanned. Updates with a map bypass serializers and must not be used for
+// these columns. Encrypted columns can't be searched or compared in SQL.
+const SerializerName = "encrypted"
+
+// ciphertextPrefix marks an encrypted value: enc:v1:<key version>:<tenant id>:<base64 nonce+ciphertext>.
+// Values without it are legacy plaintext and are returned as is until re-encrypted.
+const ciphertextPrefix = "enc:v1:"
+
+var (
+	// ErrEncryptionDisabled is returned when reading an encrypted value without a configured keyring
+	ErrEncryptionDisabled = errors.New("field encryption is not configured")
+
+	fieldKeyring atomic.Pointer[Keyring]
+)
+
+func init() {
+	schema.RegisterSerializer(SerializerName, FieldSerializer{})
+}
+
+// Configure sets the keyring used by the encrypted serializer. Until it is called (or when
+// called with nil) values are written in plaintext, so existing deployments keep working
+// before KMS is provisioned; the rotation job encrypts them once it is.
+func Configure(keyring *Keyring) {
+	fieldKeyring.Store(keyring)
+}
+
+// Enabled reports whether a keyring has been configured
+func Enabled() bool {
+	return fieldKeyring.Load() != nil
+}
+
+// IsEncrypted reports whether a stored value is in the encrypted format
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, ciphertextPrefix)
+}
+
+// Encrypt encrypts plaintext with the tenant's active key
+func (k *Keyring) Encrypt(ctx context.Context, tenantID, plaintext string) (string, error) {
+	version, key, err := k.ActiveKey(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), []byte(tenantID))
+
+	return ciphertextPrefix + strconv.Itoa(version) + ":" + tenantID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt. The tenant and key version are read from
+// the value itself, so it doesn't depend on which columns a query selected.
+func (k *Keyring) Decrypt(ctx context.Context, value string) (string, error) {
+	tenantID, version, sealed, err := parseCiphertext(value)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := k.Key(ctx, tenantID, version)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("encrypted value is too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(tenantID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+func parseCiphertext(value string) (string, int, []byte, error) {
+	if !IsEncrypted(value) {
+		return "", 0, nil, fmt.Errorf("value is not encrypted")
+	}
+	rest := strings.TrimPrefix(value, ciphertextPrefix)
+
+	// The tenant ID sits between the version and the payload; base64 has no colons
+	versionEnd := strings.Index(rest, ":")
+	payloadStart := strings.LastIndex(rest, ":")
+	if versionEnd <= 0 || payloadStart <= versionEnd {
+		return "", 0, nil, fmt.Errorf("malformed encrypted value")
+	}
+
+	version, err := strconv.Atoi(rest[:versionEnd])
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(rest[payloadStart+1:])
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	return rest[versionEnd+1 : payloadStart], version, sealed, nil
+}
+
+// FieldSerializer implements the encrypted GORM serializer
+type FieldSerializer struct{}
+
+// Scan implements schema.SerializerInterface
+func (FieldSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		stored = string(v)
+	case string:
+		stored = v
+	default:
+		return fmt.Errorf("unsupported value type %T for encrypted field %s", dbValue, field.Name)
+	}
+
+	plaintext := stored
+	if IsEncrypted(stored) {
+		keyring := fieldKeyring.Load()
+		if keyring == nil {
+			return fmt.Errorf("%w: can't read %s", ErrEncryptionDisabled, field.Name)
+		}
+		var err error
+		if plaintext, err = keyring.Decrypt(ctx, stored); err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", field.Name, err)
+		}
+	}
+
+	fieldValue := reflect.New(field.FieldType)
+	if field.FieldType.Kind() == reflect.String {
+		fieldValue.Elem().SetString(plaintext)
+	} else if plaintext != "" {
+		if err := json.Unmarshal([]byte(plaintext), fieldValue.Interface()); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", field.Name, err)
+		}
+	}
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return nil
+}
+
+// Value implements schema.SerializerValuerInterface
+func (FieldSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	var plaintext string
+	if s, ok := fieldValue.(string); ok {
+		plaintext = s
+	} else {
+		if rv := reflect.ValueOf(fieldValue); !rv.IsValid() || rv.IsZero() {
+			return nil, nil
+		}
+		b, err := json.Marshal(fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", field.Name, err)
+		}
+		plaintext = string(b)
+	}
+
+	keyring := fieldKeyring.Load()
+	if keyring == nil || plaintext == "" {
+		return plaintext, nil
+	}
+
+	tenantID := tenantOf(ctx, field.Schema, dst)
+	if tenantID == "" {
+		return nil, fmt.Errorf("can't encrypt %s: row has no tenant", field.Name)
+	}
+	return keyring.Encrypt(ctx, tenantID, plaintext)
+}
+
+func tenantOf(ctx context.Context, s *schema.Schema, row reflect.Value) string {
+	field := s.LookUpField("tenant_id")
+	if field == nil || row.Kind() != reflect.Struct {
+		return ""
+	}
+	v, _ := field.ValueOf(ctx, row)
+	switch tenantID := v.(type) {
+	case string:
+		return tenantID
+	case *string:
+		if tenantID != nil {
+			return *tenantID
+		}
+	}
+	return ""
+}
+
+// encryptedColumns returns the columns of the fields that use the encrypted serializer
+func encryptedColumns(fields []*schema.Field) []string {
+	var columns []string
+	for _, field := range fields {
+		if strings.EqualFold(field.TagSettings["SERIALIZER"], SerializerName) && field.DBName != "" {
+			columns = append(columns, field.DBName)
+		}
+	}
+	return columns
+}
@@ -0,0 +1,105 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ReencryptBatchSize is the number of rows loaded per re-encryption batch
+const ReencryptBatchSize = 200
+
+// ReencryptTenant rewrites the tenant's rows of model whose encrypted columns aren't on the
+// tenant's active key yet, including legacy plaintext and soft-deleted rows. Rows are
+// visited in primary key order, so the scan ends even if the key rotates again meanwhile.
+// It returns the number of rows rewritten.
+func ReencryptTenant(ctx context.Context, db *gorm.DB, keyring *Keyring, model interface{}, tenantID string) (int64, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return 0, err
+	}
+	columns := encryptedColumns(stmt.Schema.Fields)
+	if len(columns) == 0 {
+		return 0, nil
+	}
+	pk := stmt.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return 0, fmt.Errorf("%s has no primary key", stmt.Schema.Table)
+	}
+
+	version, _, err := keyring.ActiveKey(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+	staleCondition, staleArgs := notLikeAny(columns, ciphertextPrefix+strconv.Itoa(version)+":%")
+
+	var rewritten int64
+	var lastKey interface{}
+	for {
+		rows := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+		query := db.WithContext(ctx).Unscoped().
+			Model(model).
+			Where("tenant_id = ?", tenantID).
+			Where(staleCondition, staleArgs...).
+			Order(pk.DBName).
+			Limit(ReencryptBatchSize)
+		if lastKey != nil {
+			query = query.Where(pk.DBName+" > ?", lastKey)
+		}
+		if err := query.Find(rows.Interface()).Error; err != nil {
+			return rewritten, err
+		}
+
+		batch := rows.Elem()
+		for i := 0; i < batch.Len(); i++ {
+			row := batch.Index(i).Addr().Interface()
+			// Writing the decrypted values back re-encrypts them with the active key
+			if err := db.WithContext(ctx).Unscoped().Model(row).Select(columns).UpdateColumns(row).Error; err != nil {
+				return rewritten, fmt.Errorf("failed to re-encrypt %s row: %w", stmt.Schema.Table, err)
+			}
+			rewritten++
+			lastKey, _ = pk.ValueOf(ctx, batch.Index(i))
+		}
+
+		if batch.Len() < ReencryptBatchSize {
+			return rewritten, nil
+		}
+	}
+}
+
+// PlaintextTenants returns the tenants with rows of model that still hold unencrypted values
+// in an encrypted column, e.g. rows written before encryption was enabled
+func PlaintextTenants(ctx context.Context, db *gorm.DB, model interface{}) ([]string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	columns := encryptedColumns(stmt.Schema.Fields)
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	condition, args := notLikeAny(columns, ciphertextPrefix+"%")
+	var tenantIDs []string
+	err := db.WithContext(ctx).Unscoped().
+		Model(model).
+		Where(condition, args...).
+		Distinct().
+		Pluck("tenant_id", &tenantIDs).Error
+	return tenantIDs, err
+}
+
+// notLikeAny matches rows where any of the columns holds a non-empty value not matching pattern
+func notLikeAny(columns []string, pattern string) (string, []interface{}) {
+	conditions := make([]string, len(columns))
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		conditions[i] = fmt.Sprintf("(%s IS NOT NULL AND %s <> '' AND %s NOT LIKE ?)", column, column, column)
+		args[i] = pattern
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
@@ -8,6 +8,22 @@ import (
 	"github.com/Tesseract-Nexus/go-shared/events"
 )
 
+// Payment link event types. They share the PAYMENT_EVENTS stream so orders-service and
+// notification-service can follow invoice payments without a separate subscription.
+const (
+	PaymentLinkPartiallyPaid = "payment.link.partially_paid"
+	PaymentLinkPaid          = "payment.link.paid"
+	PaymentLinkExpired       = "payment.link.expired"
+	PaymentLinkCanceled      = "payment.link.canceled"
+)
+
+// Ad campaign budget event types. The ads system pauses a campaign on budget_exhausted and may
+// resume it on budget_replenished once a top-up is paid.
+const (
+	AdBudgetExhausted   = "payment.ad.budget_exhausted"
+	AdBudgetReplenished = "payment.ad.budget_replenished"
+)
+
 // Publisher wraps the shared events publisher for payment-specific events
 type Publisher struct {
 	publisher *events.Publisher
@@ -89,6 +105,44 @@ func (p *Publisher) PublishPaymentRefunded(ctx context.Context, tenantID, paymen
 	return p.publisher.PublishPayment(ctx, event)
 }
 
+// PublishPaymentLinkStatus publishes a payment link status change. paymentID is the
+// transaction that caused the change, or the link ID for expiry and cancellation.
+func (p *Publisher) PublishPaymentLinkStatus(ctx context.Context, eventType, tenantID, paymentID, linkID, linkCode, orderID, orderNumber, customerEmail string, amount, amountPaid float64, currency, status string) error {
+	event := events.NewPaymentEvent(eventType, tenantID)
+	event.PaymentID = paymentID
+	event.OrderID = orderID
+	event.OrderNumber = orderNumber
+	event.CustomerEmail = customerEmail
+	event.Amount = amount
+	event.Currency = currency
+	event.Status = status
+	event.Metadata = map[string]interface{}{
+		"paymentLinkId":   linkID,
+		"paymentLinkCode": linkCode,
+		"amountPaid":      amountPaid,
+	}
+
+	return p.publisher.PublishPayment(ctx, event)
+}
+
+// PublishAdBudgetStatus publishes a campaign budget running out or being topped up again.
+// paymentID is the ad payment behind the change, if any.
+func (p *Publisher) PublishAdBudgetStatus(ctx context.Context, eventType, tenantID, campaignID, vendorID, paymentID string, budget, spent, remaining float64, currency, status string) error {
+	event := events.NewPaymentEvent(eventType, tenantID)
+	event.PaymentID = paymentID
+	event.Amount = budget
+	event.Currency = currency
+	event.Status = status
+	event.Metadata = map[string]interface{}{
+		"campaignId":      campaignID,
+		"vendorId":        vendorID,
+		"spentAmount":     spent,
+		"remainingAmount": remaining,
+	}
+
+	return p.publisher.PublishPayment(ctx, event)
+}
+
 // IsConnected returns true if connected to NATS
 func (p *Publisher) IsConnected() bool {
 	return p.publisher.IsConnected()
@@ -0,0 +1,522 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"payment-service/internal/models"
+)
+
+// ============================================================================
+// Cashfree Gateway (India) - PG API 2023-08-01
+// ============================================================================
+
+const (
+	cashfreeSandboxURL    = "https://sandbox.cashfree.com/pg"
+	cashfreeProductionURL = "https://api.cashfree.com/pg"
+	cashfreeAPIVersion    = "2023-08-01"
+)
+
+// CashfreeGateway implements the PaymentGateway interface for Cashfree Payments.
+// APIKeyPublic holds the client (app) ID and APIKeySecret the client secret, which
+// also signs webhooks.
+type CashfreeGateway struct {
+	config       *models.PaymentGatewayConfig
+	clientID     string
+	clientSecret string
+	isTestMode   bool
+	httpClient   *http.Client
+}
+
+// NewCashfreeGateway creates a new Cashfree gateway instance
+func NewCashfreeGateway(config *models.PaymentGatewayConfig) (*CashfreeGateway, error) {
+	if config.APIKeyPublic == "" || config.APIKeySecret == "" {
+		return nil, fmt.Errorf("Cashfree client ID and secret are required")
+	}
+
+	return &CashfreeGateway{
+		config:       config,
+		clientID:     config.APIKeyPublic,
+		clientSecret: config.APIKeySecret,
+		isTestMode:   config.IsTestMode,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+func (g *CashfreeGateway) GetType() models.GatewayType { return models.GatewayCashfree }
+
+type cashfreeOrder struct {
+	CFOrderID        string            `json:"cf_order_id"`
+	OrderID          string            `json:"order_id"`
+	OrderAmount      float64           `json:"order_amount"`
+	OrderCurrency    string            `json:"order_currency"`
+	OrderStatus      string            `json:"order_status"`
+	PaymentSessionID string            `json:"payment_session_id"`
+	OrderTags        map[string]string `json:"order_tags"`
+}
+
+type cashfreePayment struct {
+	CFPaymentID     interface{} `json:"cf_payment_id"` // number in REST responses, string in some webhooks
+	PaymentStatus   string      `json:"payment_status"`
+	PaymentAmount   float64     `json:"payment_amount"`
+	PaymentCurrency string      `json:"payment_currency"`
+	PaymentGroup    string      `json:"payment_group"`
+	PaymentMessage  string      `json:"payment_message"`
+	PaymentTime     string      `json:"payment_time"`
+	PaymentMethod   struct {
+		UPI *struct {
+			UPIID string `json:"upi_id"`
+		} `json:"upi"`
+	} `json:"payment_method"`
+	ErrorDetails *struct {
+		ErrorCode        string `json:"error_code"`
+		ErrorDescription string `json:"error_description"`
+	} `json:"error_details"`
+}
+
+// CreatePaymentIntent creates a Cashfree order. For UPI methods the order's payment
+// session is immediately paid with the matching UPI channel (intent link, QR code or
+// collect request); otherwise the payment session ID is returned for the Cashfree
+// JS checkout.
+func (g *CashfreeGateway) CreatePaymentIntent(ctx context.Context, req *CreatePaymentRequest) (*PaymentIntentResult, error) {
+	orderID := req.PaymentID
+	if orderID == "" {
+		orderID = req.OrderID
+	}
+
+	// Cashfree requires a customer ID; guests are keyed by the order they're paying for
+	customerID := req.CustomerID
+	if customerID == "" {
+		customerID = "guest_" + strings.ReplaceAll(orderID, "-", "")
+	}
+
+	orderReq := map[string]interface{}{
+		"order_id":       orderID,
+		"order_amount":   req.Amount,
+		"order_currency": strings.ToUpper(req.Currency),
+		"customer_details": map[string]string{
+			"customer_id":    customerID,
+			"customer_email": req.CustomerEmail,
+			"customer_phone": req.CustomerPhone,
+			"customer_name":  req.CustomerName,
+		},
+		"order_meta": map[string]string{
+			"return_url": req.ReturnURL,
+			"notify_url": req.WebhookURL,
+		},
+		"order_note": req.Description,
+		"order_tags": map[string]string{
+			"tenant_id": req.TenantID,
+			"order_id":  req.OrderID,
+		},
+	}
+	if req.ExpiresInSeconds > 0 {
+		// Cashfree requires at least 15 minutes; the shorter UPI timeout is enforced by us
+		expiry := time.Duration(req.ExpiresInSeconds) * time.Second
+		if expiry < 16*time.Minute {
+			expiry = 16 * time.Minute
+		}
+		orderReq["order_expiry_time"] = time.Now().Add(expiry).Format(time.RFC3339)
+	}
+
+	var order cashfreeOrder
+	if err := g.call(ctx, "POST", "/orders", orderReq, &order); err != nil {
+		return nil, err
+	}
+
+	result := &PaymentIntentResult{
+		GatewayOrderID: order.OrderID,
+		PublicKey:      g.clientID,
+		Status:         order.OrderStatus,
+		RequiresAction: true,
+		ActionType:     "redirect",
+		ExpiresAt:      upiExpiresAt(req),
+		CheckoutOptions: map[string]interface{}{
+			"paymentSessionId": order.PaymentSessionID,
+			"orderId":          order.OrderID,
+			"mode":             g.mode(),
+		},
+	}
+
+	upi := map[string]interface{}{}
+	switch req.PaymentMethod {
+	case models.MethodUPIIntent:
+		upi["channel"] = "link"
+		result.ActionType = "upi_intent"
+	case models.MethodUPIQR:
+		upi["channel"] = "qrcode"
+		result.ActionType = "upi_qr"
+	case models.MethodUPI:
+		if req.VPA == "" {
+			return result, nil
+		}
+		upi["channel"] = "collect"
+		upi["upi_id"] = req.VPA
+		result.ActionType = "upi_collect"
+	default:
+		return result, nil
+	}
+
+	var session struct {
+		CFPaymentID interface{} `json:"cf_payment_id"`
+		Channel     string      `json:"channel"`
+		Data        struct {
+			URL     string                 `json:"url"`
+			Payload map[string]interface{} `json:"payload"`
+		} `json:"data"`
+	}
+	if err := g.call(ctx, "POST", "/orders/sessions", map[string]interface{}{
+		"payment_session_id": order.PaymentSessionID,
+		"payment_method":     map[string]interface{}{"upi": upi},
+	}, &session); err != nil {
+		return nil, err
+	}
+
+	if session.Data.Payload != nil {
+		if link, ok := session.Data.Payload["default"].(string); ok {
+			result.UPIIntentURL = link
+		}
+		if qr, ok := session.Data.Payload["qrcode"].(string); ok {
+			// Cashfree renders the QR itself and returns it as a data URI
+			result.UPIQRImageURL = qr
+		}
+	}
+	return result, nil
+}
+
+// ConfirmPayment fetches the order's latest payment after the customer returns
+func (g *CashfreeGateway) ConfirmPayment(ctx context.Context, req *ConfirmPaymentRequest) (*PaymentResult, error) {
+	return g.GetUPIPaymentStatus(ctx, req.GatewayOrderID)
+}
+
+// CapturePayment is not applicable; Cashfree payments are captured on success
+func (g *CashfreeGateway) CapturePayment(ctx context.Context, req *CapturePaymentRequest) (*PaymentResult, error) {
+	return g.GetUPIPaymentStatus(ctx, req.GatewayPaymentID)
+}
+
+// CancelPayment terminates an unpaid Cashfree order so it can no longer be paid
+func (g *CashfreeGateway) CancelPayment(ctx context.Context, paymentID string) error {
+	return g.call(ctx, "PATCH", "/orders/"+paymentID, map[string]string{
+		"order_status": "TERMINATED",
+	}, nil)
+}
+
+// CreateRefund refunds a Cashfree order. GatewayPaymentID is the Cashfree order ID.
+func (g *CashfreeGateway) CreateRefund(ctx context.Context, req *RefundRequest) (*RefundResult, error) {
+	refundID := req.IdempotencyKey
+	if refundID == "" {
+		refundID = fmt.Sprintf("refund_%d", time.Now().UnixNano())
+	}
+
+	var refund struct {
+		CFRefundID   string  `json:"cf_refund_id"`
+		RefundID     string  `json:"refund_id"`
+		RefundAmount float64 `json:"refund_amount"`
+		RefundStatus string  `json:"refund_status"`
+		StatusDesc   string  `json:"status_description"`
+	}
+	if err := g.call(ctx, "POST", "/orders/"+req.GatewayPaymentID+"/refunds", map[string]interface{}{
+		"refund_amount": req.Amount,
+		"refund_id":     refundID,
+		"refund_note":   req.Reason,
+	}, &refund); err != nil {
+		return nil, err
+	}
+
+	result := &RefundResult{
+		GatewayRefundID: refund.CFRefundID,
+		Status:          g.mapRefundStatus(refund.RefundStatus),
+		Amount:          refund.RefundAmount,
+		Currency:        strings.ToUpper(req.Currency),
+	}
+	if result.Status == models.RefundFailed {
+		result.FailureMessage = refund.StatusDesc
+	}
+	return result, nil
+}
+
+// GetPaymentDetails fetches the latest payment made against a Cashfree order
+func (g *CashfreeGateway) GetPaymentDetails(ctx context.Context, gatewayTxnID string) (*PaymentDetails, error) {
+	result, err := g.GetUPIPaymentStatus(ctx, gatewayTxnID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaymentDetails{
+		GatewayPaymentID: result.GatewayPaymentID,
+		GatewayOrderID:   gatewayTxnID,
+		Status:           result.Status,
+		Amount:           result.Amount,
+		Currency:         result.Currency,
+		PaymentMethod:    result.PaymentMethod,
+	}, nil
+}
+
+// GetUPIPaymentStatus returns the most relevant payment attempt on an order: a
+// successful one if any, otherwise the latest attempt
+func (g *CashfreeGateway) GetUPIPaymentStatus(ctx context.Context, gatewayOrderID string) (*PaymentResult, error) {
+	var payments []cashfreePayment
+	if err := g.call(ctx, "GET", "/orders/"+gatewayOrderID+"/payments", nil, &payments); err != nil {
+		return nil, err
+	}
+
+	if len(payments) == 0 {
+		return &PaymentResult{Status: models.PaymentPending, Currency: "INR"}, nil
+	}
+
+	chosen := payments[0]
+	for _, p := range payments {
+		if p.PaymentStatus == "SUCCESS" {
+			chosen = p
+			break
+		}
+	}
+	return g.paymentToResult(&chosen), nil
+}
+
+// VerifyWebhook verifies a Cashfree webhook. The signature argument is
+// "<x-webhook-timestamp>,<x-webhook-signature>"; the signature is
+// base64(HMAC-SHA256(timestamp + raw body, client secret)).
+func (g *CashfreeGateway) VerifyWebhook(payload []byte, signature string) error {
+	parts := strings.SplitN(signature, ",", 2)
+	if len(parts) != 2 {
+		return NewGatewayError("webhook_verification_failed", "Cashfree webhook timestamp and signature are required", false)
+	}
+
+	mac := hmac.New(sha256.New, []byte(g.clientSecret))
+	mac.Write([]byte(parts[0]))
+	mac.Write(payload)
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(parts[1])) {
+		return NewGatewayError("webhook_verification_failed", "Webhook signature verification failed", false)
+	}
+	return nil
+}
+
+// ProcessWebhook parses a Cashfree payment or refund webhook
+func (g *CashfreeGateway) ProcessWebhook(ctx context.Context, payload []byte) (*WebhookEvent, error) {
+	var body struct {
+		Type      string `json:"type"`
+		EventTime string `json:"event_time"`
+		Data      struct {
+			Order   cashfreeOrder   `json:"order"`
+			Payment cashfreePayment `json:"payment"`
+			Refund  struct {
+				CFRefundID   string  `json:"cf_refund_id"`
+				OrderID      string  `json:"order_id"`
+				RefundAmount float64 `json:"refund_amount"`
+				RefundStatus string  `json:"refund_status"`
+			} `json:"refund"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+
+	event := &WebhookEvent{
+		GatewayType: models.GatewayCashfree,
+		OrderID:     body.Data.Order.OrderID,
+		PaymentID:   cashfreeID(body.Data.Payment.CFPaymentID),
+		Amount:      body.Data.Payment.PaymentAmount,
+		Currency:    body.Data.Payment.PaymentCurrency,
+		Status:      body.Data.Payment.PaymentStatus,
+		RawPayload:  payload,
+	}
+	if tenantID := body.Data.Order.OrderTags["tenant_id"]; tenantID != "" {
+		event.Metadata = map[string]interface{}{"tenant_id": tenantID}
+	}
+	event.EventID = fmt.Sprintf("%s-%s-%s", body.Type, event.OrderID, body.EventTime)
+
+	switch body.Type {
+	case "PAYMENT_SUCCESS_WEBHOOK":
+		event.EventType = WebhookPaymentCaptured
+	case "PAYMENT_FAILED_WEBHOOK", "PAYMENT_USER_DROPPED_WEBHOOK":
+		event.EventType = WebhookPaymentFailed
+	case "REFUND_STATUS_WEBHOOK":
+		event.OrderID = body.Data.Refund.OrderID
+		event.RefundID = body.Data.Refund.CFRefundID
+		event.Amount = body.Data.Refund.RefundAmount
+		event.Status = body.Data.Refund.RefundStatus
+		switch g.mapRefundStatus(body.Data.Refund.RefundStatus) {
+		case models.RefundSucceeded:
+			event.EventType = WebhookRefundSucceeded
+		case models.RefundFailed:
+			event.EventType = WebhookRefundFailed
+		default:
+			event.EventType = WebhookRefundCreated
+		}
+	default:
+		event.EventType = WebhookUnknown
+	}
+
+	return event, nil
+}
+
+func (g *CashfreeGateway) SupportsFeature(feature Feature) bool {
+	return feature == FeaturePayments || feature == FeatureRefunds
+}
+
+func (g *CashfreeGateway) GetSupportedCountries() []string {
+	return []string{"IN"}
+}
+
+func (g *CashfreeGateway) GetSupportedPaymentMethods() []models.PaymentMethodType {
+	return GetGatewayPaymentMethods(models.GatewayCashfree)
+}
+
+// Helper methods
+
+func (g *CashfreeGateway) getBaseURL() string {
+	if g.isTestMode {
+		return cashfreeSandboxURL
+	}
+	return cashfreeProductionURL
+}
+
+func (g *CashfreeGateway) mode() string {
+	if g.isTestMode {
+		return "sandbox"
+	}
+	return "production"
+}
+
+// call sends an authenticated request to the Cashfree PG API and decodes the response into out
+func (g *CashfreeGateway) call(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal Cashfree request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, g.getBaseURL()+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create Cashfree request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-client-id", g.clientID)
+	httpReq.Header.Set("x-client-secret", g.clientSecret)
+	httpReq.Header.Set("x-api-version", cashfreeAPIVersion)
+
+	resp, err := g.httpClient.Do(httpReq)
+	if err != nil {
+		return NewGatewayError("cashfree_unavailable", err.Error(), true)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Code    string `json:"code"`
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = fmt.Sprintf("Cashfree request failed: %s - %s", resp.Status, string(respBody))
+		}
+		code := apiErr.Code
+		if code == "" {
+			code = "cashfree_error"
+		}
+		return NewGatewayError(code, apiErr.Message, resp.StatusCode >= 500)
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode Cashfree response: %w", err)
+	}
+	return nil
+}
+
+func (g *CashfreeGateway) paymentToResult(p *cashfreePayment) *PaymentResult {
+	result := &PaymentResult{
+		GatewayPaymentID: cashfreeID(p.CFPaymentID),
+		Status:           g.mapPaymentStatus(p.PaymentStatus),
+		Amount:           p.PaymentAmount,
+		Currency:         strings.ToUpper(p.PaymentCurrency),
+		PaymentMethod:    g.mapPaymentMethod(p.PaymentGroup),
+	}
+	result.NetAmount = result.Amount
+
+	if p.PaymentMethod.UPI != nil {
+		result.VPA = p.PaymentMethod.UPI.UPIID
+	}
+	if result.Status == models.PaymentFailed {
+		result.FailureCode = p.PaymentStatus
+		result.FailureMessage = p.PaymentMessage
+		if p.ErrorDetails != nil {
+			result.FailureCode = p.ErrorDetails.ErrorCode
+			result.FailureMessage = p.ErrorDetails.ErrorDescription
+		}
+	}
+	return result
+}
+
+func (g *CashfreeGateway) mapPaymentStatus(status string) models.PaymentStatus {
+	switch status {
+	case "SUCCESS":
+		return models.PaymentSucceeded
+	case "FAILED", "USER_DROPPED", "CANCELLED", "VOID":
+		return models.PaymentFailed
+	default:
+		return models.PaymentPending
+	}
+}
+
+func (g *CashfreeGateway) mapRefundStatus(status string) models.RefundStatus {
+	switch status {
+	case "SUCCESS":
+		return models.RefundSucceeded
+	case "CANCELLED":
+		return models.RefundFailed
+	default:
+		return models.RefundPending
+	}
+}
+
+func (g *CashfreeGateway) mapPaymentMethod(group string) models.PaymentMethodType {
+	switch group {
+	case "upi":
+		return models.MethodUPI
+	case "net_banking":
+		return models.MethodNetBanking
+	case "wallet":
+		return models.MethodWallet
+	case "cardless_emi":
+		return models.MethodCardlessEMI
+	case "pay_later":
+		return models.MethodPayLater
+	default:
+		return models.MethodCard
+	}
+}
+
+// cashfreeID normalises Cashfree IDs, which arrive as numbers or strings depending on the API
+func cashfreeID(v interface{}) string {
+	switch id := v.(type) {
+	case string:
+		return id
+	case float64:
+		return fmt.Sprintf("%.0f", id)
+	default:
+		return ""
+	}
+}
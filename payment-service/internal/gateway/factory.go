@@ -50,6 +50,8 @@ func (f *GatewayFactory) CreateGateway(config *models.PaymentGatewayConfig) (Pay
 		gw, err = NewPayPalGateway(config)
 	case models.GatewayPhonePe:
 		gw, err = NewPhonePeGateway(config)
+	case models.GatewayCashfree:
+		gw, err = NewCashfreeGateway(config)
 	case models.GatewayBharatPay:
 		gw, err = NewBharatPayGateway(config)
 	case models.GatewayAfterpay:
@@ -96,6 +98,7 @@ func GetSupportedGatewayTypes() []models.GatewayType {
 		models.GatewayPayPal,
 		models.GatewayRazorpay,
 		models.GatewayPhonePe,
+		models.GatewayCashfree,
 		models.GatewayBharatPay,
 		models.GatewayAfterpay,
 		models.GatewayZip,
@@ -132,6 +135,7 @@ func GetGatewayCountries(gatewayType models.GatewayType) []string {
 		models.GatewayPayPal:    {"US", "GB", "AU", "CA", "DE", "FR", "IT", "ES", "NL", "IN", "SG", "HK"},
 		models.GatewayRazorpay:  {"IN"},
 		models.GatewayPhonePe:   {"IN"},
+		models.GatewayCashfree:  {"IN"},
 		models.GatewayBharatPay: {"IN"},
 		models.GatewayAfterpay:  {"AU", "NZ", "US", "GB", "CA"},
 		models.GatewayZip:       {"AU", "NZ"},
@@ -163,6 +167,8 @@ func GetGatewayPaymentMethods(gatewayType models.GatewayType) []models.PaymentMe
 		models.GatewayRazorpay: {
 			models.MethodCard,
 			models.MethodUPI,
+			models.MethodUPIIntent,
+			models.MethodUPIQR,
 			models.MethodNetBanking,
 			models.MethodWallet,
 			models.MethodEMI,
@@ -170,9 +176,19 @@ func GetGatewayPaymentMethods(gatewayType models.GatewayType) []models.PaymentMe
 		},
 		models.GatewayPhonePe: {
 			models.MethodUPI,
+			models.MethodUPIIntent,
+			models.MethodUPIQR,
 			models.MethodWallet,
 			models.MethodCard,
 		},
+		models.GatewayCashfree: {
+			models.MethodUPI,
+			models.MethodUPIIntent,
+			models.MethodUPIQR,
+			models.MethodCard,
+			models.MethodNetBanking,
+			models.MethodWallet,
+		},
 		models.GatewayBharatPay: {
 			models.MethodUPI,
 			models.MethodNetBanking,
@@ -204,6 +220,8 @@ func GetPaymentMethodIcon(method models.PaymentMethodType) string {
 	icons := map[models.PaymentMethodType]string{
 		models.MethodCard:        "credit-card",
 		models.MethodUPI:         "smartphone",
+		models.MethodUPIIntent:   "smartphone",
+		models.MethodUPIQR:       "qr-code",
 		models.MethodNetBanking:  "building-2",
 		models.MethodWallet:      "wallet",
 		models.MethodEMI:         "calendar",
@@ -229,6 +247,8 @@ func GetPaymentMethodDisplayName(method models.PaymentMethodType) string {
 	names := map[models.PaymentMethodType]string{
 		models.MethodCard:        "Credit/Debit Card",
 		models.MethodUPI:         "UPI",
+		models.MethodUPIIntent:   "UPI App",
+		models.MethodUPIQR:       "UPI QR Code",
 		models.MethodNetBanking:  "Net Banking",
 		models.MethodWallet:      "Digital Wallet",
 		models.MethodEMI:         "EMI",
@@ -59,6 +59,7 @@ const (
 	FeatureGooglePay       Feature = "google_pay"
 	FeatureInstallments    Feature = "installments"
 	FeatureBNPL            Feature = "buy_now_pay_later"
+	FeatureTerminal        Feature = "terminal" // In-person payments on card readers / POS
 )
 
 // CreatePaymentRequest represents a request to create a payment
@@ -86,6 +87,10 @@ type CreatePaymentRequest struct {
 	PaymentMethod     models.PaymentMethodType
 	SaveCard          bool
 	SavedCardID       string
+	PaymentID         string    // Our payment transaction ID, used where a gateway needs a unique reference per attempt
+	UPIApp            string    // UPI intent: target app package
+	VPA               string    // UPI collect: customer's UPI ID
+	ExpiresInSeconds  int       // UPI: how long the intent/QR/collect request stays payable
 }
 
 // Address represents a billing or shipping address
@@ -107,8 +112,11 @@ type PaymentIntentResult struct {
 	RedirectURL       string                 `json:"redirectUrl,omitempty"`
 	Status            string                 `json:"status"`
 	RequiresAction    bool                   `json:"requiresAction"`
-	ActionType        string                 `json:"actionType,omitempty"` // redirect, 3ds, otp
+	ActionType        string                 `json:"actionType,omitempty"` // redirect, 3ds, otp, upi_intent, upi_qr, upi_collect
 	ExpiresAt         int64                  `json:"expiresAt,omitempty"`
+	UPIIntentURL      string                 `json:"upiIntentUrl,omitempty"`  // upi://pay deep link
+	UPIQRPayload      string                 `json:"upiQrPayload,omitempty"`  // String to encode in a QR code
+	UPIQRImageURL     string                 `json:"upiQrImageUrl,omitempty"` // Gateway-rendered QR image (URL or data URI)
 }
 
 // ConfirmPaymentRequest represents a request to confirm a payment
@@ -146,9 +154,21 @@ type PaymentResult struct {
 	WalletName        string                  `json:"walletName,omitempty"`
 	FailureCode       string                  `json:"failureCode,omitempty"`
 	FailureMessage    string                  `json:"failureMessage,omitempty"`
+	Settlement        *SettlementInfo         `json:"settlement,omitempty"`
 	RawResponse       map[string]interface{}  `json:"rawResponse,omitempty"`
 }
 
+// SettlementInfo is how the gateway settled a charge into the merchant's balance. Amounts are
+// in the settlement currency, which differs from the charge currency for cross-border payments.
+type SettlementInfo struct {
+	Currency     string  `json:"currency"`
+	Amount       float64 `json:"amount"`       // Gross amount after conversion
+	ExchangeRate float64 `json:"exchangeRate"` // Settlement units per unit of the charge currency, 1 when not converted
+	Fee          float64 `json:"fee"`          // Total gateway fee, including FXFee
+	FXFee        float64 `json:"fxFee"`        // Currency conversion part of Fee
+	Tax          float64 `json:"tax"`
+}
+
 // RefundRequest represents a request to create a refund
 type RefundRequest struct {
 	GatewayPaymentID string
@@ -187,6 +207,7 @@ type PaymentDetails struct {
 	CapturedAt        int64                   `json:"capturedAt,omitempty"`
 	CreatedAt         int64                   `json:"createdAt,omitempty"`
 	Refunds           []RefundResult          `json:"refunds,omitempty"`
+	Settlement        *SettlementInfo         `json:"settlement,omitempty"`
 	RawResponse       map[string]interface{}  `json:"rawResponse,omitempty"`
 }
 
@@ -204,6 +225,7 @@ type WebhookEvent struct {
 	Status      string                 `json:"status,omitempty"`
 	GatewayFee  float64                `json:"gatewayFee,omitempty"`
 	GatewayTax  float64                `json:"gatewayTax,omitempty"`
+	Settlement  *SettlementInfo        `json:"settlement,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 	RawPayload  []byte                 `json:"-"`
 }
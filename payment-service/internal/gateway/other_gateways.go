@@ -559,80 +559,6 @@ func (g *PayPalGateway) mapPayPalRefundStatus(status string) models.RefundStatus
 	}
 }
 
-// ============================================================================
-// PhonePe Gateway (India)
-// ============================================================================
-
-// PhonePeGateway implements the PaymentGateway interface for PhonePe
-type PhonePeGateway struct {
-	config     *models.PaymentGatewayConfig
-	merchantID string
-	apiKey     string
-	isTestMode bool
-}
-
-// NewPhonePeGateway creates a new PhonePe gateway instance
-func NewPhonePeGateway(config *models.PaymentGatewayConfig) (*PhonePeGateway, error) {
-	if config.APIKeyPublic == "" || config.APIKeySecret == "" {
-		return nil, fmt.Errorf("PhonePe merchant ID and API key are required")
-	}
-
-	return &PhonePeGateway{
-		config:     config,
-		merchantID: config.APIKeyPublic,
-		apiKey:     config.APIKeySecret,
-		isTestMode: config.IsTestMode,
-	}, nil
-}
-
-func (g *PhonePeGateway) GetType() models.GatewayType { return models.GatewayPhonePe }
-
-func (g *PhonePeGateway) CreatePaymentIntent(ctx context.Context, req *CreatePaymentRequest) (*PaymentIntentResult, error) {
-	// TODO: Implement PhonePe Pay API
-	// https://developer.phonepe.com/docs/pay-api
-	return nil, fmt.Errorf("PhonePe integration not yet implemented")
-}
-
-func (g *PhonePeGateway) ConfirmPayment(ctx context.Context, req *ConfirmPaymentRequest) (*PaymentResult, error) {
-	return nil, fmt.Errorf("PhonePe integration not yet implemented")
-}
-
-func (g *PhonePeGateway) CapturePayment(ctx context.Context, req *CapturePaymentRequest) (*PaymentResult, error) {
-	return nil, fmt.Errorf("PhonePe integration not yet implemented")
-}
-
-func (g *PhonePeGateway) CancelPayment(ctx context.Context, paymentID string) error {
-	return fmt.Errorf("PhonePe integration not yet implemented")
-}
-
-func (g *PhonePeGateway) CreateRefund(ctx context.Context, req *RefundRequest) (*RefundResult, error) {
-	return nil, fmt.Errorf("PhonePe integration not yet implemented")
-}
-
-func (g *PhonePeGateway) GetPaymentDetails(ctx context.Context, gatewayTxnID string) (*PaymentDetails, error) {
-	return nil, fmt.Errorf("PhonePe integration not yet implemented")
-}
-
-func (g *PhonePeGateway) VerifyWebhook(payload []byte, signature string) error {
-	return fmt.Errorf("PhonePe integration not yet implemented")
-}
-
-func (g *PhonePeGateway) ProcessWebhook(ctx context.Context, payload []byte) (*WebhookEvent, error) {
-	return nil, fmt.Errorf("PhonePe integration not yet implemented")
-}
-
-func (g *PhonePeGateway) SupportsFeature(feature Feature) bool {
-	return feature == FeaturePayments || feature == FeatureRefunds
-}
-
-func (g *PhonePeGateway) GetSupportedCountries() []string {
-	return []string{"IN"}
-}
-
-func (g *PhonePeGateway) GetSupportedPaymentMethods() []models.PaymentMethodType {
-	return GetGatewayPaymentMethods(models.GatewayPhonePe)
-}
-
 // ============================================================================
 // BharatPay Gateway (India)
 // ============================================================================
@@ -0,0 +1,436 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"payment-service/internal/models"
+)
+
+// ============================================================================
+// PhonePe Gateway (India) - PG v1 Standard Checkout
+// ============================================================================
+
+const (
+	phonepeSandboxURL    = "https://api-preprod.phonepe.com/apis/pg-sandbox"
+	phonepeProductionURL = "https://api.phonepe.com/apis/hermes"
+)
+
+// PhonePeGateway implements the PaymentGateway interface for PhonePe.
+// APIKeyPublic holds the merchant ID and APIKeySecret the salt key; the salt index
+// is read from Config["salt_index"] and defaults to 1.
+type PhonePeGateway struct {
+	config     *models.PaymentGatewayConfig
+	merchantID string
+	saltKey    string
+	saltIndex  string
+	isTestMode bool
+	httpClient *http.Client
+}
+
+// NewPhonePeGateway creates a new PhonePe gateway instance
+func NewPhonePeGateway(config *models.PaymentGatewayConfig) (*PhonePeGateway, error) {
+	if config.APIKeyPublic == "" || config.APIKeySecret == "" {
+		return nil, fmt.Errorf("PhonePe merchant ID and salt key are required")
+	}
+
+	saltIndex := "1"
+	if config.Config != nil {
+		switch v := config.Config["salt_index"].(type) {
+		case string:
+			if v != "" {
+				saltIndex = v
+			}
+		case float64:
+			saltIndex = fmt.Sprintf("%d", int(v))
+		}
+	}
+
+	return &PhonePeGateway{
+		config:     config,
+		merchantID: config.APIKeyPublic,
+		saltKey:    config.APIKeySecret,
+		saltIndex:  saltIndex,
+		isTestMode: config.IsTestMode,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+func (g *PhonePeGateway) GetType() models.GatewayType { return models.GatewayPhonePe }
+
+// phonepeResponse is the envelope shared by PhonePe pay, status, refund and callback payloads
+type phonepeResponse struct {
+	Success bool   `json:"success"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		MerchantID            string `json:"merchantId"`
+		MerchantTransactionID string `json:"merchantTransactionId"`
+		TransactionID         string `json:"transactionId"`
+		Amount                int64  `json:"amount"`
+		State                 string `json:"state"`
+		ResponseCode          string `json:"responseCode"`
+		InstrumentResponse    struct {
+			Type         string `json:"type"`
+			IntentURL    string `json:"intentUrl"`
+			QRData       string `json:"qrData"`
+			RedirectInfo struct {
+				URL string `json:"url"`
+			} `json:"redirectInfo"`
+		} `json:"instrumentResponse"`
+		PaymentInstrument struct {
+			Type     string `json:"type"`
+			UTR      string `json:"utr"`
+			CardType string `json:"cardType"`
+		} `json:"paymentInstrument"`
+	} `json:"data"`
+}
+
+// CreatePaymentIntent starts a PhonePe payment. UPI methods use the matching UPI
+// instrument (intent deep link, dynamic QR or collect request); everything else uses
+// the hosted pay page.
+func (g *PhonePeGateway) CreatePaymentIntent(ctx context.Context, req *CreatePaymentRequest) (*PaymentIntentResult, error) {
+	merchantTxnID := phonepeTransactionID(req.PaymentID, req.OrderID)
+
+	instrument := map[string]interface{}{"type": "PAY_PAGE"}
+	actionType := "redirect"
+	switch req.PaymentMethod {
+	case models.MethodUPIIntent:
+		instrument = map[string]interface{}{"type": "UPI_INTENT"}
+		if req.UPIApp != "" {
+			instrument["targetApp"] = req.UPIApp
+		}
+		actionType = "upi_intent"
+	case models.MethodUPIQR:
+		instrument = map[string]interface{}{"type": "UPI_QR"}
+		actionType = "upi_qr"
+	case models.MethodUPI:
+		if req.VPA != "" {
+			instrument = map[string]interface{}{"type": "UPI_COLLECT", "vpa": req.VPA}
+			actionType = "upi_collect"
+		}
+	}
+
+	payRequest := map[string]interface{}{
+		"merchantId":            g.merchantID,
+		"merchantTransactionId": merchantTxnID,
+		"merchantUserId":        phonepeUserID(req.CustomerID, req.CustomerEmail),
+		"amount":                int64(req.Amount * 100),
+		"callbackUrl":           req.WebhookURL,
+		"paymentInstrument":     instrument,
+	}
+	if req.ReturnURL != "" {
+		payRequest["redirectUrl"] = req.ReturnURL
+		payRequest["redirectMode"] = "REDIRECT"
+	}
+	if req.CustomerPhone != "" {
+		payRequest["mobileNumber"] = req.CustomerPhone
+	}
+
+	resp, err := g.post(ctx, "/pg/v1/pay", payRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PaymentIntentResult{
+		GatewayOrderID: merchantTxnID,
+		Status:         resp.Code,
+		RequiresAction: true,
+		ActionType:     actionType,
+		ExpiresAt:      upiExpiresAt(req),
+	}
+
+	instrumentResp := resp.Data.InstrumentResponse
+	result.RedirectURL = instrumentResp.RedirectInfo.URL
+	result.UPIIntentURL = instrumentResp.IntentURL
+	result.UPIQRPayload = instrumentResp.QRData
+	return result, nil
+}
+
+// ConfirmPayment checks the transaction status with PhonePe after the customer returns
+func (g *PhonePeGateway) ConfirmPayment(ctx context.Context, req *ConfirmPaymentRequest) (*PaymentResult, error) {
+	return g.GetUPIPaymentStatus(ctx, req.GatewayOrderID)
+}
+
+// CapturePayment is not applicable; PhonePe payments are captured on success
+func (g *PhonePeGateway) CapturePayment(ctx context.Context, req *CapturePaymentRequest) (*PaymentResult, error) {
+	return g.GetUPIPaymentStatus(ctx, req.GatewayPaymentID)
+}
+
+// CancelPayment is a no-op; PhonePe has no cancel API and unpaid transactions expire
+func (g *PhonePeGateway) CancelPayment(ctx context.Context, paymentID string) error {
+	return nil
+}
+
+// CreateRefund refunds a completed PhonePe transaction. GatewayPaymentID is the
+// merchant transaction ID the payment was created with.
+func (g *PhonePeGateway) CreateRefund(ctx context.Context, req *RefundRequest) (*RefundResult, error) {
+	refundTxnID := phonepeTransactionID(req.IdempotencyKey, "")
+	if refundTxnID == "" {
+		refundTxnID = fmt.Sprintf("R%d", time.Now().UnixNano())
+	}
+
+	resp, err := g.post(ctx, "/pg/v1/refund", map[string]interface{}{
+		"merchantId":            g.merchantID,
+		"merchantUserId":        g.merchantID,
+		"originalTransactionId": req.GatewayPaymentID,
+		"merchantTransactionId": refundTxnID,
+		"amount":                int64(req.Amount * 100),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.RefundPending
+	switch resp.Code {
+	case "PAYMENT_SUCCESS":
+		status = models.RefundSucceeded
+	case "PAYMENT_ERROR", "TRANSACTION_NOT_FOUND", "BAD_REQUEST":
+		status = models.RefundFailed
+	}
+
+	return &RefundResult{
+		GatewayRefundID: refundTxnID,
+		Status:          status,
+		Amount:          float64(resp.Data.Amount) / 100,
+		Currency:        "INR",
+	}, nil
+}
+
+// GetPaymentDetails fetches a transaction by its merchant transaction ID
+func (g *PhonePeGateway) GetPaymentDetails(ctx context.Context, gatewayTxnID string) (*PaymentDetails, error) {
+	result, err := g.GetUPIPaymentStatus(ctx, gatewayTxnID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaymentDetails{
+		GatewayPaymentID: result.GatewayPaymentID,
+		GatewayOrderID:   gatewayTxnID,
+		Status:           result.Status,
+		Amount:           result.Amount,
+		Currency:         result.Currency,
+		PaymentMethod:    result.PaymentMethod,
+	}, nil
+}
+
+// GetUPIPaymentStatus calls the PhonePe status API for a merchant transaction
+func (g *PhonePeGateway) GetUPIPaymentStatus(ctx context.Context, gatewayOrderID string) (*PaymentResult, error) {
+	path := fmt.Sprintf("/pg/v1/status/%s/%s", g.merchantID, gatewayOrderID)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", g.getBaseURL()+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create status request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-VERIFY", g.checksum(path))
+	httpReq.Header.Set("X-MERCHANT-ID", g.merchantID)
+
+	resp, err := g.do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	return g.responseToResult(resp), nil
+}
+
+// VerifyWebhook verifies a PhonePe server-to-server callback. The X-VERIFY header is
+// SHA256(base64 response + salt key) followed by ### and the salt index.
+func (g *PhonePeGateway) VerifyWebhook(payload []byte, signature string) error {
+	var callback struct {
+		Response string `json:"response"`
+	}
+	if err := json.Unmarshal(payload, &callback); err != nil || callback.Response == "" {
+		return NewGatewayError("webhook_verification_failed", "PhonePe callback has no response payload", false)
+	}
+
+	expected := g.checksum(callback.Response)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) != 1 {
+		return NewGatewayError("webhook_verification_failed", "Webhook signature verification failed", false)
+	}
+	return nil
+}
+
+// ProcessWebhook decodes a PhonePe callback into a webhook event
+func (g *PhonePeGateway) ProcessWebhook(ctx context.Context, payload []byte) (*WebhookEvent, error) {
+	var callback struct {
+		Response string `json:"response"`
+	}
+	if err := json.Unmarshal(payload, &callback); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(callback.Response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PhonePe callback: %w", err)
+	}
+
+	var resp phonepeResponse
+	if err := json.Unmarshal(decoded, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse PhonePe callback: %w", err)
+	}
+
+	event := &WebhookEvent{
+		EventID:     resp.Data.MerchantTransactionID + "-" + resp.Code,
+		GatewayType: models.GatewayPhonePe,
+		PaymentID:   resp.Data.TransactionID,
+		OrderID:     resp.Data.MerchantTransactionID,
+		Amount:      float64(resp.Data.Amount) / 100,
+		Currency:    "INR",
+		Status:      resp.Data.State,
+		RawPayload:  decoded,
+	}
+
+	switch g.mapPaymentStatus(resp.Code, resp.Data.State) {
+	case models.PaymentSucceeded:
+		event.EventType = WebhookPaymentCaptured
+	case models.PaymentFailed:
+		event.EventType = WebhookPaymentFailed
+	default:
+		event.EventType = WebhookUnknown
+	}
+
+	return event, nil
+}
+
+func (g *PhonePeGateway) SupportsFeature(feature Feature) bool {
+	return feature == FeaturePayments || feature == FeatureRefunds
+}
+
+func (g *PhonePeGateway) GetSupportedCountries() []string {
+	return []string{"IN"}
+}
+
+func (g *PhonePeGateway) GetSupportedPaymentMethods() []models.PaymentMethodType {
+	return GetGatewayPaymentMethods(models.GatewayPhonePe)
+}
+
+// Helper methods
+
+func (g *PhonePeGateway) getBaseURL() string {
+	if g.isTestMode {
+		return phonepeSandboxURL
+	}
+	return phonepeProductionURL
+}
+
+// checksum computes the X-VERIFY header value for a payload (or path)
+func (g *PhonePeGateway) checksum(payload string) string {
+	sum := sha256.Sum256([]byte(payload + g.saltKey))
+	return hex.EncodeToString(sum[:]) + "###" + g.saltIndex
+}
+
+// post sends a base64-encoded request to a PhonePe endpoint
+func (g *PhonePeGateway) post(ctx context.Context, path string, body map[string]interface{}) (*phonepeResponse, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal PhonePe request: %w", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(raw)
+
+	envelope, _ := json.Marshal(map[string]string{"request": encoded})
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", g.getBaseURL()+path, bytes.NewReader(envelope))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PhonePe request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-VERIFY", g.checksum(encoded+path))
+
+	return g.do(httpReq)
+}
+
+func (g *PhonePeGateway) do(httpReq *http.Request) (*phonepeResponse, error) {
+	resp, err := g.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, NewGatewayError("phonepe_unavailable", err.Error(), true)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	var parsed phonepeResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode PhonePe response: %s - %s", resp.Status, string(body))
+	}
+	if resp.StatusCode >= 400 || (!parsed.Success && parsed.Code != "PAYMENT_PENDING") {
+		return nil, NewGatewayError(strings.ToLower(parsed.Code), parsed.Message, resp.StatusCode >= 500)
+	}
+	return &parsed, nil
+}
+
+func (g *PhonePeGateway) responseToResult(resp *phonepeResponse) *PaymentResult {
+	result := &PaymentResult{
+		GatewayPaymentID: resp.Data.TransactionID,
+		Status:           g.mapPaymentStatus(resp.Code, resp.Data.State),
+		Amount:           float64(resp.Data.Amount) / 100,
+		Currency:         "INR",
+	}
+	result.NetAmount = result.Amount
+
+	switch resp.Data.PaymentInstrument.Type {
+	case "UPI":
+		result.PaymentMethod = models.MethodUPI
+	case "CARD":
+		result.PaymentMethod = models.MethodCard
+	case "NETBANKING":
+		result.PaymentMethod = models.MethodNetBanking
+	default:
+		result.PaymentMethod = models.MethodWallet
+	}
+
+	if result.Status == models.PaymentFailed {
+		result.FailureCode = resp.Data.ResponseCode
+		if result.FailureCode == "" {
+			result.FailureCode = resp.Code
+		}
+		result.FailureMessage = resp.Message
+	}
+	return result
+}
+
+func (g *PhonePeGateway) mapPaymentStatus(code, state string) models.PaymentStatus {
+	switch {
+	case code == "PAYMENT_SUCCESS" || state == "COMPLETED":
+		return models.PaymentSucceeded
+	case code == "PAYMENT_ERROR" || code == "PAYMENT_DECLINED" || code == "TIMED_OUT" || state == "FAILED":
+		return models.PaymentFailed
+	default:
+		return models.PaymentPending
+	}
+}
+
+// phonepeTransactionID derives a merchant transaction ID (max 35 alphanumeric characters)
+func phonepeTransactionID(paymentID, fallback string) string {
+	id := paymentID
+	if id == "" {
+		id = fallback
+	}
+	id = strings.ReplaceAll(id, "-", "")
+	if len(id) > 35 {
+		id = id[:35]
+	}
+	return id
+}
+
+// phonepeUserID derives the merchantUserId PhonePe requires for every payment
+func phonepeUserID(customerID, email string) string {
+	if customerID != "" {
+		return phonepeTransactionID(customerID, "")
+	}
+	if email != "" {
+		sum := sha256.Sum256([]byte(strings.ToLower(email)))
+		return "U" + hex.EncodeToString(sum[:])[:32]
+	}
+	return "GUEST"
+}
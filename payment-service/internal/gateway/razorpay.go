@@ -68,6 +68,11 @@ func (g *RazorpayGateway) CreatePaymentIntent(ctx context.Context, req *CreatePa
 	// Convert amount to paise (smallest currency unit for INR)
 	amountPaise := int64(req.Amount * 100)
 
+	// Dynamic QR codes are standalone and don't need an order
+	if req.PaymentMethod == models.MethodUPIQR {
+		return g.createUPIQRCode(req, amountPaise)
+	}
+
 	orderData := map[string]interface{}{
 		"amount":   amountPaise,
 		"currency": strings.ToUpper(req.Currency),
@@ -94,6 +99,11 @@ func (g *RazorpayGateway) CreatePaymentIntent(ctx context.Context, req *CreatePa
 	orderID, _ := order["id"].(string)
 	status, _ := order["status"].(string)
 
+	// UPI intent and collect are created server-side instead of through the checkout modal
+	if req.PaymentMethod == models.MethodUPIIntent || (req.PaymentMethod == models.MethodUPI && req.VPA != "") {
+		return g.createUPIPayment(req, orderID, amountPaise)
+	}
+
 	return &PaymentIntentResult{
 		GatewayOrderID: orderID,
 		PublicKey:      g.keyID,
@@ -149,6 +159,14 @@ func (g *RazorpayGateway) CapturePayment(ctx context.Context, req *CapturePaymen
 
 // CancelPayment cancels a payment (not directly supported by Razorpay)
 func (g *RazorpayGateway) CancelPayment(ctx context.Context, paymentID string) error {
+	// Close dynamic UPI QR codes so they can't be paid after we've given up on them
+	if strings.HasPrefix(paymentID, razorpayQRPrefix) {
+		if _, err := g.client.QrCode.Close(paymentID, nil, nil); err != nil {
+			return g.handleRazorpayError(err)
+		}
+		return nil
+	}
+
 	// Razorpay doesn't support direct cancellation
 	// Payments expire automatically if not captured
 	return nil
@@ -225,6 +243,7 @@ func (g *RazorpayGateway) GetPaymentDetails(ctx context.Context, gatewayTxnID st
 		CustomerEmail:    email,
 		CustomerName:     contact,
 		CreatedAt:        int64(createdAt),
+		Settlement:       RazorpaySettlement(payment),
 		RawResponse:      payment,
 	}, nil
 }
@@ -274,10 +293,20 @@ func (g *RazorpayGateway) ProcessWebhook(ctx context.Context, payload []byte) (*
 			}
 			webhookEvent.Currency, _ = entityData["currency"].(string)
 			webhookEvent.Status, _ = entityData["status"].(string)
+			webhookEvent.Settlement = RazorpaySettlement(entityData)
+		}
+	}
+
+	// QR code payments reference the QR code rather than an order
+	if qrCode, ok := eventPayload["qr_code"].(map[string]interface{}); ok {
+		if entityData, ok := qrCode["entity"].(map[string]interface{}); ok && webhookEvent.OrderID == "" {
+			webhookEvent.OrderID, _ = entityData["id"].(string)
 		}
 	}
 
 	switch eventType {
+	case "qr_code.credited":
+		webhookEvent.EventType = WebhookPaymentCaptured
 	case "payment.authorized":
 		webhookEvent.EventType = WebhookPaymentAuthorized
 	case "payment.captured":
@@ -307,6 +336,7 @@ func (g *RazorpayGateway) SupportsFeature(feature Feature) bool {
 		Feature3DSecure:      true,
 		FeatureSavedCards:    true,
 		FeatureInstallments:  true, // EMI
+		FeatureTerminal:      true, // Razorpay POS
 	}
 
 	return supportedFeatures[feature]
@@ -322,6 +352,37 @@ func (g *RazorpayGateway) GetSupportedPaymentMethods() []models.PaymentMethodTyp
 	return GetGatewayPaymentMethods(models.GatewayRazorpay)
 }
 
+// RazorpaySettlement reads settlement info from a Razorpay payment entity. Razorpay settles in
+// INR: international payments carry base_amount/base_currency and report fee and tax in the
+// base currency. The forex markup is not itemised on the payment, so FXFee comes from
+// settlement reports. Returns nil until the payment is captured and its fee is known.
+func RazorpaySettlement(payment map[string]interface{}) *SettlementInfo {
+	fee, ok := payment["fee"].(float64)
+	if !ok {
+		return nil
+	}
+	tax, _ := payment["tax"].(float64)
+	amount, _ := payment["amount"].(float64)
+	currency, _ := payment["currency"].(string)
+
+	info := &SettlementInfo{
+		Currency:     strings.ToUpper(currency),
+		Amount:       amount / 100,
+		ExchangeRate: 1,
+		Fee:          (fee - tax) / 100,
+		Tax:          tax / 100,
+	}
+	baseAmount, _ := payment["base_amount"].(float64)
+	baseCurrency, _ := payment["base_currency"].(string)
+	if baseCurrency != "" && !strings.EqualFold(baseCurrency, currency) && baseAmount > 0 && amount > 0 {
+		info.Currency = strings.ToUpper(baseCurrency)
+		info.Amount = baseAmount / 100
+		info.ExchangeRate = baseAmount / amount
+	}
+
+	return info
+}
+
 // Helper methods
 
 func (g *RazorpayGateway) paymentToResult(payment map[string]interface{}) *PaymentResult {
@@ -346,6 +407,7 @@ func (g *RazorpayGateway) paymentToResult(payment map[string]interface{}) *Payme
 
 	// Calculate net amount
 	result.NetAmount = result.Amount - result.GatewayFee - result.GatewayTax
+	result.Settlement = RazorpaySettlement(payment)
 
 	// Extract card details
 	if cardInfo, ok := payment["card"].(map[string]interface{}); ok {
@@ -318,6 +318,7 @@ func (g *StripeGateway) GetPaymentDetails(ctx context.Context, gatewayTxnID stri
 		CardLastFour:     cardLastFour,
 		CustomerEmail:    pi.ReceiptEmail,
 		CreatedAt:        pi.Created,
+		Settlement:       stripeLatestChargeSettlement(pi),
 		RawResponse: map[string]interface{}{
 			"id":     pi.ID,
 			"status": string(pi.Status),
@@ -364,6 +365,7 @@ func (g *StripeGateway) ProcessWebhook(ctx context.Context, payload []byte) (*We
 			if pi.LatestCharge != nil && pi.LatestCharge.BalanceTransaction != nil {
 				webhookEvent.GatewayFee = float64(pi.LatestCharge.BalanceTransaction.Fee) / 100
 			}
+			webhookEvent.Settlement = stripeLatestChargeSettlement(&pi)
 		}
 
 	case "payment_intent.payment_failed":
@@ -410,6 +412,7 @@ func (g *StripeGateway) SupportsFeature(feature Feature) bool {
 		FeatureGooglePay:     true,
 		FeatureInstallments:  true,
 		FeatureBNPL:          true, // via Klarna integration
+		FeatureTerminal:      true, // via Stripe Terminal
 	}
 
 	return supportedFeatures[feature]
@@ -425,6 +428,44 @@ func (g *StripeGateway) GetSupportedPaymentMethods() []models.PaymentMethodType
 	return GetGatewayPaymentMethods(models.GatewayStripe)
 }
 
+// StripeSettlement converts a charge's balance transaction to settlement info. Stripe reports
+// the balance transaction in the account's settlement currency; conversion fees are the fee
+// lines described as currency conversion.
+func StripeSettlement(bt *stripe.BalanceTransaction) *SettlementInfo {
+	if bt == nil || bt.Currency == "" {
+		return nil
+	}
+
+	info := &SettlementInfo{
+		Currency:     strings.ToUpper(string(bt.Currency)),
+		Amount:       float64(bt.Amount) / 100,
+		ExchangeRate: bt.ExchangeRate,
+		Fee:          float64(bt.Fee) / 100,
+	}
+	if info.ExchangeRate == 0 {
+		info.ExchangeRate = 1
+	}
+	for _, detail := range bt.FeeDetails {
+		switch {
+		case detail.Type == "tax":
+			info.Tax += float64(detail.Amount) / 100
+		case strings.Contains(strings.ToLower(detail.Description), "conversion"):
+			info.FXFee += float64(detail.Amount) / 100
+		}
+	}
+	// Stripe's fee total includes tax, which is tracked separately
+	info.Fee -= info.Tax
+
+	return info
+}
+
+func stripeLatestChargeSettlement(pi *stripe.PaymentIntent) *SettlementInfo {
+	if pi.LatestCharge == nil {
+		return nil
+	}
+	return StripeSettlement(pi.LatestCharge.BalanceTransaction)
+}
+
 // Helper methods
 
 func (g *StripeGateway) paymentIntentToResult(pi *stripe.PaymentIntent) *PaymentResult {
@@ -444,6 +485,7 @@ func (g *StripeGateway) paymentIntentToResult(pi *stripe.PaymentIntent) *Payment
 
 	// Calculate net amount
 	result.NetAmount = result.Amount - result.GatewayFee
+	result.Settlement = stripeLatestChargeSettlement(pi)
 
 	// Get payment method details
 	if pi.PaymentMethod != nil {
@@ -0,0 +1,263 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/paymentintent"
+	"github.com/stripe/stripe-go/v76/terminal/connectiontoken"
+	"github.com/stripe/stripe-go/v76/terminal/reader"
+
+	"payment-service/internal/models"
+)
+
+// TerminalGateway is implemented by gateways that can take in-person payments on
+// card readers or POS devices. In-person payments are authorized on the reader and
+// captured explicitly once the sale is finalized.
+type TerminalGateway interface {
+	// CreateConnectionToken returns a short-lived secret for the gateway's Terminal SDK
+	CreateConnectionToken(ctx context.Context, gatewayLocationID string) (string, error)
+
+	// RegisterReader pairs a reader with the gateway account
+	RegisterReader(ctx context.Context, req *RegisterReaderRequest) (*TerminalReaderResult, error)
+
+	// CreateTerminalPayment creates a payment and hands it to the reader for collection
+	CreateTerminalPayment(ctx context.Context, req *TerminalPaymentRequest) (*PaymentIntentResult, error)
+
+	// CaptureTerminalPayment captures an in-person payment authorized on the reader
+	CaptureTerminalPayment(ctx context.Context, req *CapturePaymentRequest) (*PaymentResult, error)
+
+	// CancelTerminalPayment clears the reader and cancels the pending payment
+	CancelTerminalPayment(ctx context.Context, gatewayReaderID, gatewayPaymentID string) error
+}
+
+// RegisterReaderRequest represents a request to register a card reader
+type RegisterReaderRequest struct {
+	TenantID          string
+	Label             string
+	RegistrationCode  string // Stripe: code shown on the reader
+	GatewayLocationID string // Stripe: terminal location the reader belongs to
+	DeviceID          string // Razorpay POS: device ID of the POS terminal
+}
+
+// TerminalReaderResult represents a reader as registered with the gateway
+type TerminalReaderResult struct {
+	GatewayReaderID   string
+	GatewayLocationID string
+	DeviceType        string
+	SerialNumber      string
+	Online            bool
+}
+
+// TerminalPaymentRequest represents an in-person payment to collect on a reader
+type TerminalPaymentRequest struct {
+	TenantID        string
+	OrderID         string
+	PaymentID       string
+	GatewayReaderID string
+	Amount          float64
+	Currency        string
+	Description     string
+	ReceiptEmail    string
+	Metadata        map[string]string
+}
+
+// CreateConnectionToken creates a Stripe Terminal connection token, optionally scoped to a location
+func (g *StripeGateway) CreateConnectionToken(ctx context.Context, gatewayLocationID string) (string, error) {
+	stripe.Key = g.secretKey
+
+	params := &stripe.TerminalConnectionTokenParams{}
+	if gatewayLocationID != "" {
+		params.Location = stripe.String(gatewayLocationID)
+	}
+
+	token, err := connectiontoken.New(params)
+	if err != nil {
+		return "", g.handleStripeError(err)
+	}
+	return token.Secret, nil
+}
+
+// RegisterReader registers a Stripe Terminal reader using its registration code
+func (g *StripeGateway) RegisterReader(ctx context.Context, req *RegisterReaderRequest) (*TerminalReaderResult, error) {
+	stripe.Key = g.secretKey
+
+	if req.RegistrationCode == "" || req.GatewayLocationID == "" {
+		return nil, NewGatewayError("invalid_request", "Stripe readers require a registration code and a Stripe location ID", false)
+	}
+
+	params := &stripe.TerminalReaderParams{
+		Label:            stripe.String(req.Label),
+		Location:         stripe.String(req.GatewayLocationID),
+		RegistrationCode: stripe.String(req.RegistrationCode),
+	}
+	params.AddMetadata("tenant_id", req.TenantID)
+
+	rdr, err := reader.New(params)
+	if err != nil {
+		return nil, g.handleStripeError(err)
+	}
+
+	result := &TerminalReaderResult{
+		GatewayReaderID:   rdr.ID,
+		GatewayLocationID: req.GatewayLocationID,
+		DeviceType:        string(rdr.DeviceType),
+		SerialNumber:      rdr.SerialNumber,
+		Online:            rdr.Status == "online",
+	}
+	if rdr.Location != nil {
+		result.GatewayLocationID = rdr.Location.ID
+	}
+	return result, nil
+}
+
+// CreateTerminalPayment creates a card_present PaymentIntent with manual capture and
+// pushes it to the reader (server-driven integration)
+func (g *StripeGateway) CreateTerminalPayment(ctx context.Context, req *TerminalPaymentRequest) (*PaymentIntentResult, error) {
+	stripe.Key = g.secretKey
+
+	params := &stripe.PaymentIntentParams{
+		Amount:             stripe.Int64(int64(req.Amount * 100)),
+		Currency:           stripe.String(strings.ToLower(req.Currency)),
+		PaymentMethodTypes: stripe.StringSlice([]string{"card_present"}),
+		CaptureMethod:      stripe.String(string(stripe.PaymentIntentCaptureMethodManual)),
+	}
+	if req.Description != "" {
+		params.Description = stripe.String(req.Description)
+	}
+	if req.ReceiptEmail != "" {
+		params.ReceiptEmail = stripe.String(req.ReceiptEmail)
+	}
+	for k, v := range req.Metadata {
+		params.AddMetadata(k, v)
+	}
+	params.AddMetadata("tenant_id", req.TenantID)
+	params.AddMetadata("order_id", req.OrderID)
+	params.AddMetadata("payment_id", req.PaymentID)
+	params.AddMetadata("channel", string(models.ChannelInPerson))
+	if req.PaymentID != "" {
+		params.SetIdempotencyKey("terminal-" + req.PaymentID)
+	}
+
+	pi, err := paymentintent.New(params)
+	if err != nil {
+		return nil, g.handleStripeError(err)
+	}
+
+	if _, err := reader.ProcessPaymentIntent(req.GatewayReaderID, &stripe.TerminalReaderProcessPaymentIntentParams{
+		PaymentIntent: stripe.String(pi.ID),
+	}); err != nil {
+		// Don't leave an orphaned intent behind if the reader rejected it (e.g. offline or busy)
+		_, _ = paymentintent.Cancel(pi.ID, &stripe.PaymentIntentCancelParams{})
+		return nil, g.handleStripeError(err)
+	}
+
+	return &PaymentIntentResult{
+		GatewayOrderID: pi.ID,
+		ClientSecret:   pi.ClientSecret,
+		PublicKey:      g.publishableKey,
+		Status:         string(pi.Status),
+		RequiresAction: true,
+		ActionType:     "terminal",
+	}, nil
+}
+
+// CaptureTerminalPayment captures an in-person PaymentIntent authorized on the reader
+func (g *StripeGateway) CaptureTerminalPayment(ctx context.Context, req *CapturePaymentRequest) (*PaymentResult, error) {
+	return g.CapturePayment(ctx, req)
+}
+
+// CancelTerminalPayment cancels the reader's current action and the PaymentIntent
+func (g *StripeGateway) CancelTerminalPayment(ctx context.Context, gatewayReaderID, gatewayPaymentID string) error {
+	stripe.Key = g.secretKey
+
+	if gatewayReaderID != "" {
+		// The reader may already be idle; cancelling the intent below is what matters
+		_, _ = reader.CancelAction(gatewayReaderID, &stripe.TerminalReaderCancelActionParams{})
+	}
+	return g.CancelPayment(ctx, gatewayPaymentID)
+}
+
+// CreateConnectionToken is not used by Razorpay POS, whose devices authenticate themselves
+func (g *RazorpayGateway) CreateConnectionToken(ctx context.Context, gatewayLocationID string) (string, error) {
+	return "", NewGatewayError("unsupported", "Razorpay POS devices do not use connection tokens", false)
+}
+
+// RegisterReader registers a Razorpay POS device. Devices are provisioned by Razorpay,
+// so registration only records the device ID the payment will be pushed to.
+func (g *RazorpayGateway) RegisterReader(ctx context.Context, req *RegisterReaderRequest) (*TerminalReaderResult, error) {
+	if req.DeviceID == "" {
+		return nil, NewGatewayError("invalid_request", "Razorpay POS devices require a device ID", false)
+	}
+
+	return &TerminalReaderResult{
+		GatewayReaderID: req.DeviceID,
+		DeviceType:      "razorpay_pos",
+	}, nil
+}
+
+// CreateTerminalPayment creates a Razorpay order addressed to the POS device, which
+// collects it in the Razorpay POS app
+func (g *RazorpayGateway) CreateTerminalPayment(ctx context.Context, req *TerminalPaymentRequest) (*PaymentIntentResult, error) {
+	notes := map[string]string{}
+	for k, v := range req.Metadata {
+		notes[k] = v
+	}
+	notes["tenant_id"] = req.TenantID
+	notes["order_id"] = req.OrderID
+	notes["payment_id"] = req.PaymentID
+	notes["channel"] = string(models.ChannelInPerson)
+	notes["pos_device_id"] = req.GatewayReaderID
+
+	order, err := g.client.Order.Create(map[string]interface{}{
+		"amount":          int64(req.Amount * 100),
+		"currency":        strings.ToUpper(req.Currency),
+		"receipt":         req.OrderID,
+		"payment_capture": 0,
+		"notes":           notes,
+	}, nil)
+	if err != nil {
+		return nil, g.handleRazorpayError(err)
+	}
+
+	orderID, _ := order["id"].(string)
+	status, _ := order["status"].(string)
+	return &PaymentIntentResult{
+		GatewayOrderID: orderID,
+		PublicKey:      g.keyID,
+		Status:         status,
+		RequiresAction: true,
+		ActionType:     "terminal",
+	}, nil
+}
+
+// CaptureTerminalPayment captures the payment authorized on the POS device against the order
+func (g *RazorpayGateway) CaptureTerminalPayment(ctx context.Context, req *CapturePaymentRequest) (*PaymentResult, error) {
+	payments, err := g.client.Order.Payments(req.GatewayPaymentID, nil, nil)
+	if err != nil {
+		return nil, g.handleRazorpayError(err)
+	}
+
+	items, _ := payments["items"].([]interface{})
+	for _, item := range items {
+		payment, ok := item.(map[string]interface{})
+		if !ok || payment["status"] != "authorized" {
+			continue
+		}
+		paymentID, _ := payment["id"].(string)
+		return g.CapturePayment(ctx, &CapturePaymentRequest{
+			GatewayPaymentID: paymentID,
+			Amount:           req.Amount,
+			Currency:         req.Currency,
+		})
+	}
+
+	return nil, NewGatewayError("payment_not_authorized", fmt.Sprintf("no authorized payment found for order %s", req.GatewayPaymentID), true)
+}
+
+// CancelTerminalPayment is a no-op for Razorpay; uncaptured orders expire on their own
+func (g *RazorpayGateway) CancelTerminalPayment(ctx context.Context, gatewayReaderID, gatewayPaymentID string) error {
+	return g.CancelPayment(ctx, gatewayPaymentID)
+}
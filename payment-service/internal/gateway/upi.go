@@ -0,0 +1,164 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"payment-service/internal/models"
+)
+
+// UPIStatusChecker is implemented by gateways that can report the state of a UPI
+// intent, QR or collect request by the reference returned from CreatePaymentIntent.
+// It backs storefront polling and the UPI timeout worker, since UPI payments complete
+// outside the checkout page and webhooks may arrive late or not at all.
+type UPIStatusChecker interface {
+	GetUPIPaymentStatus(ctx context.Context, gatewayOrderID string) (*PaymentResult, error)
+}
+
+// razorpayQRPrefix identifies Razorpay QR code IDs stored as the gateway reference
+const razorpayQRPrefix = "qr_"
+
+// createUPIPayment creates a server-to-server UPI payment against an order: an intent
+// payment returning a upi://pay link, or a collect request sent to the customer's VPA
+func (g *RazorpayGateway) createUPIPayment(req *CreatePaymentRequest, orderID string, amountPaise int64) (*PaymentIntentResult, error) {
+	upi := map[string]interface{}{"flow": "intent"}
+	actionType := "upi_intent"
+	if req.PaymentMethod == models.MethodUPI {
+		upi = map[string]interface{}{
+			"flow": "collect",
+			"vpa":  req.VPA,
+		}
+		if req.ExpiresInSeconds > 0 {
+			upi["expiry_time"] = (req.ExpiresInSeconds + 59) / 60 // minutes
+		}
+		actionType = "upi_collect"
+	}
+
+	payment, err := g.client.Payment.CreateUpi(map[string]interface{}{
+		"amount":      amountPaise,
+		"currency":    strings.ToUpper(req.Currency),
+		"order_id":    orderID,
+		"email":       req.CustomerEmail,
+		"contact":     req.CustomerPhone,
+		"method":      "upi",
+		"description": req.Description,
+		"upi":         upi,
+		"notes": map[string]string{
+			"tenant_id":  req.TenantID,
+			"order_id":   req.OrderID,
+			"payment_id": req.PaymentID,
+		},
+	}, nil)
+	if err != nil {
+		return nil, g.handleRazorpayError(err)
+	}
+
+	link, _ := payment["link"].(string)
+	return &PaymentIntentResult{
+		GatewayOrderID: orderID,
+		PublicKey:      g.keyID,
+		Status:         "created",
+		RequiresAction: true,
+		ActionType:     actionType,
+		ExpiresAt:      upiExpiresAt(req),
+		UPIIntentURL:   link,
+	}, nil
+}
+
+// createUPIQRCode creates a single-use, fixed-amount dynamic UPI QR code. Razorpay QR
+// payments are not tied to an order, so the QR code ID becomes the gateway reference.
+func (g *RazorpayGateway) createUPIQRCode(req *CreatePaymentRequest, amountPaise int64) (*PaymentIntentResult, error) {
+	expiresAt := upiExpiresAt(req)
+	// Razorpay rejects close_by less than two minutes in the future
+	if minClose := time.Now().Add(2*time.Minute + 30*time.Second).Unix(); expiresAt < minClose {
+		expiresAt = minClose
+	}
+
+	qr, err := g.client.QrCode.Create(map[string]interface{}{
+		"type":           "upi_qr",
+		"name":           req.Description,
+		"usage":          "single_use",
+		"fixed_amount":   true,
+		"payment_amount": amountPaise,
+		"description":    req.Description,
+		"close_by":       expiresAt,
+		"notes": map[string]string{
+			"tenant_id":  req.TenantID,
+			"order_id":   req.OrderID,
+			"payment_id": req.PaymentID,
+		},
+	}, nil)
+	if err != nil {
+		return nil, g.handleRazorpayError(err)
+	}
+
+	qrID, _ := qr["id"].(string)
+	status, _ := qr["status"].(string)
+	imageURL, _ := qr["image_url"].(string)
+	return &PaymentIntentResult{
+		GatewayOrderID: qrID,
+		PublicKey:      g.keyID,
+		Status:         status,
+		RequiresAction: true,
+		ActionType:     "upi_qr",
+		ExpiresAt:      expiresAt,
+		UPIQRImageURL:  imageURL,
+	}, nil
+}
+
+// GetUPIPaymentStatus returns the outcome of the payments made against a Razorpay order
+// or QR code, preferring a captured payment over an authorized one over the latest attempt
+func (g *RazorpayGateway) GetUPIPaymentStatus(ctx context.Context, gatewayOrderID string) (*PaymentResult, error) {
+	var payments map[string]interface{}
+	var err error
+	if strings.HasPrefix(gatewayOrderID, razorpayQRPrefix) {
+		payments, err = g.client.QrCode.FetchPayments(gatewayOrderID, nil, nil)
+	} else {
+		payments, err = g.client.Order.Payments(gatewayOrderID, nil, nil)
+	}
+	if err != nil {
+		return nil, g.handleRazorpayError(err)
+	}
+
+	items, _ := payments["items"].([]interface{})
+	var captured, authorized, latest map[string]interface{}
+	for _, item := range items {
+		payment, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if latest == nil {
+			latest = payment
+		}
+		switch payment["status"] {
+		case "captured":
+			if captured == nil {
+				captured = payment
+			}
+		case "authorized":
+			if authorized == nil {
+				authorized = payment
+			}
+		}
+	}
+
+	switch {
+	case captured != nil:
+		return g.paymentToResult(captured), nil
+	case authorized != nil:
+		return g.paymentToResult(authorized), nil
+	case latest != nil:
+		return g.paymentToResult(latest), nil
+	default:
+		return &PaymentResult{Status: models.PaymentPending, Currency: "INR"}, nil
+	}
+}
+
+// upiExpiresAt returns when a UPI request created now stops being payable
+func upiExpiresAt(req *CreatePaymentRequest) int64 {
+	if req.ExpiresInSeconds <= 0 {
+		return 0
+	}
+	return time.Now().Add(time.Duration(req.ExpiresInSeconds) * time.Second).Unix()
+}
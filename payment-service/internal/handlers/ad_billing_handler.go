@@ -654,3 +654,156 @@ func (h *AdBillingHandler) GetPaymentByCampaign(c *gin.Context) {
 		"data":    payment,
 	})
 }
+
+// GetCampaignBudget handles GET /api/v1/ads/billing/campaigns/:campaignId/budget
+func (h *AdBillingHandler) GetCampaignBudget(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	campaignID, err := uuid.Parse(c.Param("campaignId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid campaign ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	budget, err := h.service.GetCampaignBudget(c.Request.Context(), tenantID, campaignID)
+	if err != nil {
+		if errors.Is(err, services.ErrCampaignBudgetNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Budget not found",
+				Message: "No paid budget found for this campaign",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get campaign budget",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    budget,
+	})
+}
+
+// RecordCampaignSpend handles POST /api/v1/ads/billing/campaigns/:campaignId/spend
+// Called by the ads system as spend accrues. The returned budget has what is left and the
+// pacing, and the campaign is paused through an event when the budget runs out.
+func (h *AdBillingHandler) RecordCampaignSpend(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	campaignID, err := uuid.Parse(c.Param("campaignId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid campaign ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var req models.RecordAdSpendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	budget, err := h.service.RecordSpend(c.Request.Context(), tenantID, campaignID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrCampaignBudgetNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Budget not found",
+				Message: "No paid budget found for this campaign",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to record campaign spend",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    budget,
+	})
+}
+
+// TopUpCampaignBudget handles POST /api/v1/ads/billing/campaigns/:campaignId/top-up
+// Creates a pending payment for the extra budget, processed like any other ad payment.
+func (h *AdBillingHandler) TopUpCampaignBudget(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	campaignID, err := uuid.Parse(c.Param("campaignId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid campaign ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var req models.TopUpAdBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	payment, err := h.service.TopUpBudget(c.Request.Context(), tenantID, campaignID, req.Amount)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, services.ErrCampaignBudgetNotFound) {
+			statusCode = http.StatusNotFound
+		} else if errors.Is(err, services.ErrCampaignEnded) {
+			statusCode = http.StatusConflict
+		}
+		c.JSON(statusCode, models.ErrorResponse{
+			Error:   "Failed to top up campaign budget",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    payment,
+	})
+}
+
+// GetVendorBudgets handles GET /api/v1/ads/billing/vendors/:vendorId/budgets
+func (h *AdBillingHandler) GetVendorBudgets(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	vendorID, err := uuid.Parse(c.Param("vendorId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid vendor ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	budgets, err := h.service.ListVendorBudgets(c.Request.Context(), tenantID, vendorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get campaign budgets",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    budgets,
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -67,6 +68,8 @@ func (h *ApprovalGatewayHandler) CreateGatewayConfigWithApproval(c *gin.Context)
 		DisplayName:           req.DisplayName,
 		IsEnabled:             req.IsEnabled,
 		IsTestMode:            req.IsTestMode,
+		StorefrontID:          req.StorefrontID,
+		OverriddenFields:      req.OverriddenFields,
 		Config:                req.Config,
 		SupportsPayments:      req.SupportsPayments,
 		SupportsRefunds:       req.SupportsRefunds,
@@ -158,14 +161,24 @@ func (h *ApprovalGatewayHandler) UpdateGatewayConfigWithApproval(c *gin.Context)
 		return
 	}
 
-	var config models.PaymentGatewayConfig
-	if err := c.ShouldBindJSON(&config); err != nil {
+	var req gatewayConfigUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Invalid request",
 			Message: err.Error(),
 		})
 		return
 	}
+	config := req.PaymentGatewayConfig
+
+	expectedVersion, err := resolveExpectedVersion(c, req.ExpectedVersion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid If-Match header",
+			Message: err.Error(),
+		})
+		return
+	}
 
 	// Get existing config for context
 	existingConfig, err := h.repo.GetGatewayConfig(c.Request.Context(), configID)
@@ -177,13 +190,25 @@ func (h *ApprovalGatewayHandler) UpdateGatewayConfigWithApproval(c *gin.Context)
 		return
 	}
 
+	// Reject stale edits up front so they never reach the approval queue either
+	if expectedVersion != nil && *expectedVersion != existingConfig.Version {
+		respondGatewayVersionConflict(c, existingConfig.Version)
+		return
+	}
+
 	config.ID = configID
+	config.Version = existingConfig.Version
 
 	// Check if user has owner priority (can bypass approval)
 	userPriority := c.GetInt("user_priority")
 	if userPriority >= clients.RequiredPriorityForGatewayConfig || !h.approvalEnabled {
 		// Execute directly - owner can update without approval
 		if err := h.repo.UpdateGatewayConfig(c.Request.Context(), &config); err != nil {
+			var conflict *models.VersionConflictError
+			if errors.As(err, &conflict) {
+				respondGatewayVersionConflict(c, conflict.CurrentVersion)
+				return
+			}
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 				Error:   "Failed to update gateway config",
 				Message: err.Error(),
@@ -191,6 +216,7 @@ func (h *ApprovalGatewayHandler) UpdateGatewayConfigWithApproval(c *gin.Context)
 			return
 		}
 
+		c.Header("ETag", gatewayConfigETag(config.Version))
 		c.JSON(http.StatusOK, GatewayConfigResponse{
 			Success:          true,
 			Config:           &config,
@@ -528,12 +554,19 @@ func (h *ApprovalGatewayHandler) HandleApprovalCallback(c *gin.Context) {
 			return
 		}
 
-		config := &models.PaymentGatewayConfig{
-			ID:          configID,
-			DisplayName: configData["display_name"].(string),
-			IsEnabled:   configData["is_enabled"].(bool),
-			IsTestMode:  configData["is_test_mode"].(bool),
+		// Apply the approved fields on top of the current row so the version check
+		// and the untouched fields both come from the database
+		config, err := h.repo.GetGatewayConfig(c.Request.Context(), configID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Gateway config not found",
+				Message: err.Error(),
+			})
+			return
 		}
+		config.DisplayName = configData["display_name"].(string)
+		config.IsEnabled = configData["is_enabled"].(bool)
+		config.IsTestMode = configData["is_test_mode"].(bool)
 
 		if err := h.repo.UpdateGatewayConfig(c.Request.Context(), config); err != nil {
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"payment-service/internal/models"
+	"payment-service/internal/services"
+)
+
+// CommissionInvoiceHandler handles marketplace commission invoice HTTP requests
+type CommissionInvoiceHandler struct {
+	service *services.CommissionInvoiceService
+}
+
+// NewCommissionInvoiceHandler creates a new commission invoice handler
+func NewCommissionInvoiceHandler(service *services.CommissionInvoiceService) *CommissionInvoiceHandler {
+	return &CommissionInvoiceHandler{
+		service: service,
+	}
+}
+
+// GetSettings handles GET /api/v1/commission-invoices/settings
+func (h *CommissionInvoiceHandler) GetSettings(c *gin.Context) {
+	settings, err := h.service.GetSettings(c.Request.Context(), getTenantID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get commission invoice settings",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// UpdateSettings handles PUT /api/v1/commission-invoices/settings
+func (h *CommissionInvoiceHandler) UpdateSettings(c *gin.Context) {
+	var req models.UpdateCommissionInvoiceSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(c.Request.Context(), getTenantID(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to update commission invoice settings",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// GenerateInvoices handles POST /api/v1/commission-invoices/generate
+// Invoices every vendor with uninvoiced commission in the month. Vendors already invoiced for the
+// month are left alone, so it is safe to run again.
+func (h *CommissionInvoiceHandler) GenerateInvoices(c *gin.Context) {
+	var req models.GenerateCommissionInvoicesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	month, err := time.Parse("2006-01", req.Month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid month",
+			Message: "Month must be in YYYY-MM format",
+		})
+		return
+	}
+
+	invoices, err := h.service.GenerateMonthlyInvoices(c.Request.Context(), getTenantID(c), month)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrCommissionInvoicingDisabled) || errors.Is(err, services.ErrCommissionPeriodOpen) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, models.ErrorResponse{
+			Error:   "Failed to generate commission invoices",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoices,
+	})
+}
+
+// ListInvoices handles GET /api/v1/commission-invoices (?vendorId=&page=&limit=)
+func (h *CommissionInvoiceHandler) ListInvoices(c *gin.Context) {
+	h.listInvoices(c, c.Query("vendorId"))
+}
+
+// ListVendorInvoices handles GET /api/v1/commission-invoices/vendors/:vendorId
+// Vendors can only see their own invoices.
+func (h *CommissionInvoiceHandler) ListVendorInvoices(c *gin.Context) {
+	vendorID := c.Param("vendorId")
+	if !h.canAccessVendor(c, vendorID) {
+		return
+	}
+	h.listInvoices(c, vendorID)
+}
+
+func (h *CommissionInvoiceHandler) listInvoices(c *gin.Context, vendorID string) {
+	page := 1
+	limit := 20
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	invoices, total, err := h.service.ListInvoices(c.Request.Context(), getTenantID(c), vendorID, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to list commission invoices",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoices,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetInvoice handles GET /api/v1/commission-invoices/:id
+func (h *CommissionInvoiceHandler) GetInvoice(c *gin.Context) {
+	invoice, ok := h.getInvoice(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoice,
+	})
+}
+
+// DownloadInvoicePDF handles GET /api/v1/commission-invoices/:id/pdf
+func (h *CommissionInvoiceHandler) DownloadInvoicePDF(c *gin.Context) {
+	invoice, ok := h.getInvoice(c)
+	if !ok {
+		return
+	}
+
+	pdf, err := h.service.RenderInvoicePDF(invoice)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to render commission invoice",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", invoice.InvoiceNumber+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// getInvoice loads the invoice in the path, writing the error response when it can't be
+// returned to the caller
+func (h *CommissionInvoiceHandler) getInvoice(c *gin.Context) (*models.CommissionInvoice, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid invoice ID",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	invoice, err := h.service.GetInvoice(c.Request.Context(), getTenantID(c), id)
+	if errors.Is(err, services.ErrCommissionInvoiceNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Commission invoice not found",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get commission invoice",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	if !h.canAccessVendor(c, invoice.VendorID) {
+		return nil, false
+	}
+	return invoice, true
+}
+
+// canAccessVendor rejects vendors asking for another vendor's invoices
+func (h *CommissionInvoiceHandler) canAccessVendor(c *gin.Context, vendorID string) bool {
+	if callerVendorID := c.GetString("vendorID"); callerVendorID != "" && callerVendorID != vendorID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Vendors can only view their own commission invoices",
+		})
+		return false
+	}
+	return true
+}
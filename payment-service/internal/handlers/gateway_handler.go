@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -40,7 +41,7 @@ func (h *GatewayHandler) GetPaymentMethodsByCountry(c *gin.Context) {
 		return
 	}
 
-	methods, err := h.selectorService.GetPaymentMethods(c.Request.Context(), tenantID, countryCode)
+	methods, err := h.selectorService.GetPaymentMethods(c.Request.Context(), tenantID, c.GetHeader("X-Storefront-ID"), countryCode)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to get payment methods",
@@ -64,7 +65,7 @@ func (h *GatewayHandler) GetAvailableGateways(c *gin.Context) {
 		countryCode = "US" // Default to US
 	}
 
-	gateways, err := h.selectorService.GetAvailableGateways(c.Request.Context(), tenantID, countryCode)
+	gateways, err := h.selectorService.GetAvailableGateways(c.Request.Context(), tenantID, c.GetHeader("X-Storefront-ID"), countryCode)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to get available gateways",
@@ -480,6 +481,14 @@ func (h *GatewayHandler) UpdatePaymentSettings(c *gin.Context) {
 	settings.TenantID = tenantID
 
 	if err := h.feeService.UpdatePaymentSettings(c.Request.Context(), &settings); err != nil {
+		if errors.Is(err, services.ErrInvalidRefundTender) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid refund fallback chain",
+				Message: err.Error(),
+				Code:    "INVALID_REFUND_TENDER",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to update payment settings",
 			Message: err.Error(),
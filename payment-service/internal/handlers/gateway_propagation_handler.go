@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"payment-service/internal/clients"
+	"payment-service/internal/models"
+	"payment-service/internal/services"
+)
+
+// GatewayPropagationHandler handles propagation of tenant-level gateway configs to storefronts
+type GatewayPropagationHandler struct {
+	service *services.GatewayPropagationService
+}
+
+// NewGatewayPropagationHandler creates a new gateway propagation handler
+func NewGatewayPropagationHandler(service *services.GatewayPropagationService) *GatewayPropagationHandler {
+	return &GatewayPropagationHandler{
+		service: service,
+	}
+}
+
+// PropagateGatewayConfig handles POST /api/v1/gateway-configs/:id/propagate
+// Applies a tenant-level gateway config to the selected storefronts. Since it changes many
+// storefronts' gateways at once, it is limited to owners, who can change gateways without approval.
+func (h *GatewayPropagationHandler) PropagateGatewayConfig(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	configID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid config ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if c.GetInt("user_priority") < clients.RequiredPriorityForGatewayConfig {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Only owners can propagate gateway configuration to storefronts",
+		})
+		return
+	}
+
+	var req models.PropagateGatewayConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	results, err := h.service.Propagate(c.Request.Context(), tenantID, configID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Gateway config not found",
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrNotTenantDefault), errors.Is(err, services.ErrUnknownGatewayField):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid request",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to propagate gateway config",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Action == models.GatewayPropagationFailed {
+			failed++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": failed == 0,
+		"data":    results,
+		"failed":  failed,
+	})
+}
+
+// GetGatewayDrift handles GET /api/v1/gateway-configs/drift
+// Reports storefront gateway configs that differ from their tenant default on settings they
+// don't override, and storefront configs with no tenant default to inherit from
+func (h *GatewayPropagationHandler) GetGatewayDrift(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	report, err := h.service.DetectDrift(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to detect gateway config drift",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
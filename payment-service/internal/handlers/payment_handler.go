@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,6 +18,7 @@ import (
 type PaymentRepository interface {
 	GetGatewayConfig(ctx context.Context, configID uuid.UUID) (*models.PaymentGatewayConfig, error)
 	ListGatewayConfigs(ctx context.Context, tenantID string) ([]models.PaymentGatewayConfig, error)
+	ListStorefrontGatewayConfigs(ctx context.Context, tenantID, storefrontID string) ([]models.PaymentGatewayConfig, error)
 	CreateGatewayConfig(ctx context.Context, config *models.PaymentGatewayConfig) error
 	UpdateGatewayConfig(ctx context.Context, config *models.PaymentGatewayConfig) error
 	DeleteGatewayConfig(ctx context.Context, configID uuid.UUID) error
@@ -44,6 +49,14 @@ func (h *PaymentHandler) CreatePaymentIntent(c *gin.Context) {
 		return
 	}
 
+	// The storefront's gateway config overrides apply to payments taken on it
+	if storefrontID := c.GetHeader("X-Storefront-ID"); storefrontID != "" && req.Metadata["storefront_id"] == "" {
+		if req.Metadata == nil {
+			req.Metadata = make(map[string]string)
+		}
+		req.Metadata["storefront_id"] = storefrontID
+	}
+
 	response, err := h.service.CreatePaymentIntent(c.Request.Context(), req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -126,6 +139,44 @@ func (h *PaymentHandler) GetPaymentByGatewayID(c *gin.Context) {
 	c.JSON(http.StatusOK, payment)
 }
 
+// GetUPIPaymentStatus handles GET /api/v1/payments/:id/upi-status
+// Polled by the storefront while the customer completes a UPI intent, QR or collect payment
+func (h *PaymentHandler) GetUPIPaymentStatus(c *gin.Context) {
+	paymentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid payment ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	status, err := h.service.RefreshUPIPaymentStatus(c.Request.Context(), getTenantID(c), paymentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUPIPaymentNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Payment not found",
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrNotUPIPayment):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Not a UPI payment",
+				Message: err.Error(),
+				Code:    "NOT_UPI_PAYMENT",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to get UPI payment status",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
 // CancelPayment handles POST /api/v1/payments/:id/cancel
 func (h *PaymentHandler) CancelPayment(c *gin.Context) {
 	paymentID, err := uuid.Parse(c.Param("id"))
@@ -172,8 +223,127 @@ func (h *PaymentHandler) CreateRefund(c *gin.Context) {
 
 	refund, err := h.service.CreateRefund(c.Request.Context(), paymentID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to create refund",
+		switch {
+		case errors.Is(err, services.ErrInvalidRefundTender):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid refund tender",
+				Message: err.Error(),
+				Code:    "INVALID_REFUND_TENDER",
+			})
+		case errors.Is(err, services.ErrNoRefundTender):
+			c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse{
+				Error:   "Refund could not be issued",
+				Message: err.Error(),
+				Code:    "NO_REFUND_TENDER",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to create refund",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, refund)
+}
+
+// ExecuteRefundPlan handles POST /api/v1/refunds/plans
+// Refunds an order across its payments and gift cards; called by orders-service when a return completes
+func (h *PaymentHandler) ExecuteRefundPlan(c *gin.Context) {
+	var req models.RefundPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	plan, err := h.service.ExecuteRefundPlan(c.Request.Context(), getTenantID(c), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRefundPlan):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid refund plan",
+				Message: err.Error(),
+				Code:    "INVALID_REFUND_PLAN",
+			})
+		case errors.Is(err, services.ErrInvalidRefundTender):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid refund tender",
+				Message: err.Error(),
+				Code:    "INVALID_REFUND_TENDER",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to execute refund plan",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// RecordSettlement handles POST /api/v1/payments/:id/settlement
+// Records settlement currency, exchange rate and fees from a gateway settlement report
+func (h *PaymentHandler) RecordSettlement(c *gin.Context) {
+	paymentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid payment ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var req models.RecordSettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	payment, err := h.service.RecordSettlement(c.Request.Context(), paymentID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPaymentNotSettleable):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Payment cannot be settled",
+				Message: err.Error(),
+				Code:    "PAYMENT_NOT_CAPTURED",
+			})
+		default:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Failed to record settlement",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// GetRefund handles GET /api/v1/refunds/:id
+func (h *PaymentHandler) GetRefund(c *gin.Context) {
+	refundID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid refund ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	refund, err := h.service.GetRefund(c.Request.Context(), getTenantID(c), refundID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Refund not found",
 			Message: err.Error(),
 		})
 		return
@@ -182,6 +352,52 @@ func (h *PaymentHandler) CreateRefund(c *gin.Context) {
 	c.JSON(http.StatusOK, refund)
 }
 
+// RecordBankTransfer handles POST /api/v1/refunds/:id/bank-transfer
+func (h *PaymentHandler) RecordBankTransfer(c *gin.Context) {
+	refundID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid refund ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var req models.RecordBankTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	refund, err := h.service.RecordBankTransfer(c.Request.Context(), getTenantID(c), refundID, req, getActorID(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRefundNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Refund not found",
+				Message: err.Error(),
+			})
+		case errors.Is(err, services.ErrNoPendingBankTransfer):
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "No pending bank transfer",
+				Message: err.Error(),
+				Code:    "NO_PENDING_BANK_TRANSFER",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to record bank transfer",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, refund)
+}
+
 // ListPaymentsByOrder handles GET /api/v1/orders/:orderId/payments
 func (h *PaymentHandler) ListPaymentsByOrder(c *gin.Context) {
 	orderID, err := uuid.Parse(c.Param("orderId"))
@@ -231,10 +447,19 @@ func (h *PaymentHandler) ListRefundsByPayment(c *gin.Context) {
 // ==================== Gateway Config CRUD ====================
 
 // ListGatewayConfigs handles GET /api/v1/gateway-configs
+// Lists the tenant-level configs, or a storefront's overrides with ?storefrontId= ("*" for all storefronts)
 func (h *PaymentHandler) ListGatewayConfigs(c *gin.Context) {
 	tenantID := getTenantID(c)
 
-	configs, err := h.repo.ListGatewayConfigs(c.Request.Context(), tenantID)
+	var configs []models.PaymentGatewayConfig
+	var err error
+	if storefrontID := c.Query("storefrontId"); storefrontID == "*" {
+		configs, err = h.repo.ListStorefrontGatewayConfigs(c.Request.Context(), tenantID, "")
+	} else if storefrontID != "" {
+		configs, err = h.repo.ListStorefrontGatewayConfigs(c.Request.Context(), tenantID, storefrontID)
+	} else {
+		configs, err = h.repo.ListGatewayConfigs(c.Request.Context(), tenantID)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to list gateway configs",
@@ -266,6 +491,7 @@ func (h *PaymentHandler) GetGatewayConfig(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", gatewayConfigETag(config.Version))
 	c.JSON(http.StatusOK, config)
 }
 
@@ -305,17 +531,46 @@ func (h *PaymentHandler) UpdateGatewayConfig(c *gin.Context) {
 		return
 	}
 
-	var config models.PaymentGatewayConfig
-	if err := c.ShouldBindJSON(&config); err != nil {
+	var req gatewayConfigUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Invalid request",
 			Message: err.Error(),
 		})
 		return
 	}
+	config := req.PaymentGatewayConfig
+
+	expectedVersion, err := resolveExpectedVersion(c, req.ExpectedVersion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid If-Match header",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	existing, err := h.repo.GetGatewayConfig(c.Request.Context(), configID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Gateway config not found",
+			Message: err.Error(),
+		})
+		return
+	}
+	if expectedVersion != nil && *expectedVersion != existing.Version {
+		respondGatewayVersionConflict(c, existing.Version)
+		return
+	}
 
 	config.ID = configID
+	config.Version = existing.Version
 	if err := h.repo.UpdateGatewayConfig(c.Request.Context(), &config); err != nil {
+		var conflict *models.VersionConflictError
+		if errors.As(err, &conflict) {
+			respondGatewayVersionConflict(c, conflict.CurrentVersion)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to update gateway config",
 			Message: err.Error(),
@@ -323,6 +578,7 @@ func (h *PaymentHandler) UpdateGatewayConfig(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", gatewayConfigETag(config.Version))
 	c.JSON(http.StatusOK, config)
 }
 
@@ -348,6 +604,40 @@ func (h *PaymentHandler) DeleteGatewayConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Gateway config deleted successfully"})
 }
 
+// gatewayConfigUpdateRequest is the PUT body: the full gateway config plus an optional expected version
+type gatewayConfigUpdateRequest struct {
+	models.PaymentGatewayConfig
+	ExpectedVersion *int `json:"expectedVersion"`
+}
+
+// resolveExpectedVersion returns the version the client last saw, from If-Match ("3", W/"3" or 3)
+// or else the body's expectedVersion. nil means the update is unconditional
+func resolveExpectedVersion(c *gin.Context, bodyVersion *int) (*int, error) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" || raw == "*" {
+		return bodyVersion, nil
+	}
+	v, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(raw, "W/"), `"`))
+	if err != nil || v < 1 {
+		return nil, fmt.Errorf("If-Match must be a gateway config version, got %q", raw)
+	}
+	return &v, nil
+}
+
+func gatewayConfigETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// respondGatewayVersionConflict answers 409 carrying the stored version
+func respondGatewayVersionConflict(c *gin.Context, currentVersion int) {
+	c.Header("ETag", gatewayConfigETag(currentVersion))
+	c.JSON(http.StatusConflict, gin.H{
+		"error":          "Version conflict",
+		"message":        "Gateway configuration was modified by another request",
+		"currentVersion": currentVersion,
+	})
+}
+
 // getTenantID extracts tenant ID from context
 // Checks both "tenant_id" (set by IstioAuth) and "tenantID" (set by TenantMiddleware)
 func getTenantID(c *gin.Context) string {
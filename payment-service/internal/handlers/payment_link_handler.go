@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"payment-service/internal/models"
+	"payment-service/internal/services"
+)
+
+// PaymentLinkHandler handles payment link HTTP requests
+type PaymentLinkHandler struct {
+	service *services.PaymentLinkService
+}
+
+// NewPaymentLinkHandler creates a new payment link handler
+func NewPaymentLinkHandler(service *services.PaymentLinkService) *PaymentLinkHandler {
+	return &PaymentLinkHandler{
+		service: service,
+	}
+}
+
+// CreatePaymentLink handles POST /api/v1/payment-links
+func (h *PaymentLinkHandler) CreatePaymentLink(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	var req models.CreatePaymentLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	link, err := h.service.CreatePaymentLink(c.Request.Context(), tenantID, getActorID(c), &req)
+	if err != nil {
+		h.respondError(c, "Failed to create payment link", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    link,
+	})
+}
+
+// ListPaymentLinks handles GET /api/v1/payment-links
+func (h *PaymentLinkHandler) ListPaymentLinks(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	page := 1
+	limit := 20
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	var orderID *uuid.UUID
+	if orderIDStr := c.Query("orderId"); orderIDStr != "" {
+		parsed, err := uuid.Parse(orderIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid order ID",
+				Message: err.Error(),
+			})
+			return
+		}
+		orderID = &parsed
+	}
+
+	status := models.PaymentLinkStatus(c.Query("status"))
+	links, total, err := h.service.ListPaymentLinks(c.Request.Context(), tenantID, status, orderID, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to list payment links",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    links,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetPaymentLink handles GET /api/v1/payment-links/:id
+func (h *PaymentLinkHandler) GetPaymentLink(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid payment link ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	link, err := h.service.GetPaymentLink(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondError(c, "Failed to get payment link", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    link,
+	})
+}
+
+// CancelPaymentLink handles POST /api/v1/payment-links/:id/cancel
+func (h *PaymentLinkHandler) CancelPaymentLink(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid payment link ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	link, err := h.service.CancelPaymentLink(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondError(c, "Failed to cancel payment link", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    link,
+	})
+}
+
+// ResolvePaymentLink handles GET /api/v1/public/payment-links/:code?sig=
+// Used by the storefront to render the hosted payment page.
+func (h *PaymentLinkHandler) ResolvePaymentLink(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	link, err := h.service.ResolvePaymentLink(c.Request.Context(), tenantID, c.Param("code"), c.Query("sig"))
+	if err != nil {
+		h.respondError(c, "Failed to resolve payment link", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    link,
+	})
+}
+
+// PayPaymentLink handles POST /api/v1/public/payment-links/:code/pay
+func (h *PaymentLinkHandler) PayPaymentLink(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	var req models.PayPaymentLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	intent, err := h.service.PayPaymentLink(c.Request.Context(), tenantID, c.Param("code"), &req)
+	if err != nil {
+		h.respondError(c, "Failed to start payment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, intent)
+}
+
+// respondError maps payment link service errors to HTTP responses
+func (h *PaymentLinkHandler) respondError(c *gin.Context, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	code := ""
+	switch {
+	case errors.Is(err, services.ErrPaymentLinkNotFound), errors.Is(err, services.ErrInvalidPaymentLinkSignature):
+		// A bad signature is reported as not found so link codes can't be probed
+		statusCode = http.StatusNotFound
+		code = "PAYMENT_LINK_NOT_FOUND"
+	case errors.Is(err, services.ErrPaymentLinkNotPayable):
+		statusCode = http.StatusConflict
+		code = "PAYMENT_LINK_NOT_PAYABLE"
+	case errors.Is(err, services.ErrInvalidPaymentLinkAmount):
+		statusCode = http.StatusBadRequest
+		code = "INVALID_AMOUNT"
+	case errors.Is(err, services.ErrInvalidPaymentLinkExpiry):
+		statusCode = http.StatusBadRequest
+		code = "INVALID_EXPIRY"
+	case errors.Is(err, services.ErrInvalidTenantID):
+		statusCode = http.StatusBadRequest
+	case errors.Is(err, services.ErrPaymentLinksDisabled):
+		statusCode = http.StatusServiceUnavailable
+		code = "PAYMENT_LINKS_DISABLED"
+	}
+
+	c.JSON(statusCode, models.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+		Code:    code,
+	})
+}
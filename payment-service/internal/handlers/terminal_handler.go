@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"payment-service/internal/gateway"
+	"payment-service/internal/models"
+	"payment-service/internal/services"
+)
+
+// TerminalHandler handles in-person (terminal / POS) payment HTTP requests
+type TerminalHandler struct {
+	service *services.TerminalService
+}
+
+// NewTerminalHandler creates a new terminal handler
+func NewTerminalHandler(service *services.TerminalService) *TerminalHandler {
+	return &TerminalHandler{
+		service: service,
+	}
+}
+
+// CreateConnectionToken handles POST /api/v1/terminal/connection-tokens
+func (h *TerminalHandler) CreateConnectionToken(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	var req models.CreateConnectionTokenRequest
+	// The body is optional; without it the token is not scoped to a location
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid request",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	token, err := h.service.CreateConnectionToken(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, "Failed to create connection token", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    token,
+	})
+}
+
+// RegisterReader handles POST /api/v1/terminal/readers
+func (h *TerminalHandler) RegisterReader(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	var req models.RegisterTerminalReaderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	reader, err := h.service.RegisterReader(c.Request.Context(), tenantID, getActorID(c), &req)
+	if err != nil {
+		h.respondError(c, "Failed to register reader", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    reader,
+	})
+}
+
+// ListReaders handles GET /api/v1/terminal/readers?locationId=
+func (h *TerminalHandler) ListReaders(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	readers, err := h.service.ListReaders(c.Request.Context(), tenantID, c.Query("locationId"))
+	if err != nil {
+		h.respondError(c, "Failed to list readers", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    readers,
+	})
+}
+
+// DeleteReader handles DELETE /api/v1/terminal/readers/:id
+func (h *TerminalHandler) DeleteReader(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid reader ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.DeleteReader(c.Request.Context(), tenantID, id); err != nil {
+		h.respondError(c, "Failed to delete reader", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// CreatePayment handles POST /api/v1/terminal/payments
+func (h *TerminalHandler) CreatePayment(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	var req models.CreateTerminalPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	payment, err := h.service.CreatePayment(c.Request.Context(), tenantID, &req)
+	if err != nil {
+		h.respondError(c, "Failed to create terminal payment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    payment,
+	})
+}
+
+// CapturePayment handles POST /api/v1/terminal/payments/:id/capture
+func (h *TerminalHandler) CapturePayment(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid payment ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var req models.CaptureTerminalPaymentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid request",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	payment, err := h.service.CapturePayment(c.Request.Context(), tenantID, id, &req)
+	if err != nil {
+		h.respondError(c, "Failed to capture terminal payment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    payment,
+	})
+}
+
+// CancelPayment handles POST /api/v1/terminal/payments/:id/cancel
+func (h *TerminalHandler) CancelPayment(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid payment ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	payment, err := h.service.CancelPayment(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondError(c, "Failed to cancel terminal payment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    payment,
+	})
+}
+
+// GetReconciliation handles GET /api/v1/payment-reconciliation?startDate=&endDate=&channel=&locationId=
+func (h *TerminalHandler) GetReconciliation(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	// Default to the last 7 days
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -7)
+
+	if start := c.Query("startDate"); start != "" {
+		if t, err := time.Parse("2006-01-02", start); err == nil {
+			startDate = t
+		}
+	}
+	if end := c.Query("endDate"); end != "" {
+		if t, err := time.Parse("2006-01-02", end); err == nil {
+			endDate = t.Add(24 * time.Hour)
+		}
+	}
+
+	channel := models.PaymentChannel(c.Query("channel"))
+	if channel != "" && channel != models.ChannelOnline && channel != models.ChannelInPerson {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid channel",
+			Message: "channel must be ONLINE or IN_PERSON",
+		})
+		return
+	}
+
+	report, err := h.service.Reconcile(c.Request.Context(), tenantID, startDate, endDate, channel, c.Query("locationId"))
+	if err != nil {
+		h.respondError(c, "Failed to reconcile payments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// respondError maps terminal service errors to HTTP responses
+func (h *TerminalHandler) respondError(c *gin.Context, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	code := ""
+
+	var gatewayErr *gateway.GatewayError
+	switch {
+	case errors.Is(err, services.ErrTerminalReaderNotFound):
+		statusCode = http.StatusNotFound
+		code = "READER_NOT_FOUND"
+	case errors.Is(err, services.ErrTerminalPaymentNotFound):
+		statusCode = http.StatusNotFound
+		code = "PAYMENT_NOT_FOUND"
+	case errors.Is(err, services.ErrTerminalReaderExists):
+		statusCode = http.StatusConflict
+		code = "READER_EXISTS"
+	case errors.Is(err, services.ErrTerminalPaymentState):
+		statusCode = http.StatusConflict
+		code = "PAYMENT_NOT_PENDING"
+	case errors.Is(err, services.ErrTerminalNotSupported):
+		statusCode = http.StatusBadRequest
+		code = "TERMINAL_NOT_SUPPORTED"
+	case errors.Is(err, services.ErrInvalidTenantID):
+		statusCode = http.StatusBadRequest
+	case errors.As(err, &gatewayErr):
+		statusCode = http.StatusBadGateway
+		code = gatewayErr.Code
+	}
+
+	c.JSON(statusCode, models.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+		Code:    code,
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"payment-service/internal/models"
+	"payment-service/internal/services"
+)
+
+// VendorPayoutHandler handles vendor payout statement HTTP requests
+type VendorPayoutHandler struct {
+	service *services.VendorPayoutService
+}
+
+// NewVendorPayoutHandler creates a new vendor payout handler
+func NewVendorPayoutHandler(service *services.VendorPayoutService) *VendorPayoutHandler {
+	return &VendorPayoutHandler{
+		service: service,
+	}
+}
+
+// GetStatement handles GET /api/v1/vendor-payouts/vendors/:vendorId/statement
+// Lists the holds, releases and clawbacks made against a vendor's payouts for chargebacks
+// and returns, each with an explanation. Vendors can only see their own statement.
+func (h *VendorPayoutHandler) GetStatement(c *gin.Context) {
+	tenantID := getTenantID(c)
+	vendorID := c.Param("vendorId")
+
+	if callerVendorID := c.GetString("vendorID"); callerVendorID != "" && callerVendorID != vendorID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Vendors can only view their own payout statement",
+		})
+		return
+	}
+
+	var startDate, endDate time.Time
+	var err error
+
+	if startDateStr := c.Query("startDate"); startDateStr != "" {
+		startDate, err = time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid start date",
+				Message: "Date must be in YYYY-MM-DD format",
+			})
+			return
+		}
+	} else {
+		// Default to last 30 days
+		startDate = time.Now().AddDate(0, 0, -30)
+	}
+
+	if endDateStr := c.Query("endDate"); endDateStr != "" {
+		endDate, err = time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid end date",
+				Message: "Date must be in YYYY-MM-DD format",
+			})
+			return
+		}
+		// Include the whole end day
+		endDate = endDate.Add(24*time.Hour - 1*time.Second)
+	} else {
+		endDate = time.Now()
+	}
+
+	statement, err := h.service.GetStatement(c.Request.Context(), tenantID, vendorID, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get vendor payout statement",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    statement,
+	})
+}
@@ -235,19 +235,107 @@ func (h *WebhookHandler) HandlePayUWebhook(c *gin.Context) {
 
 // HandleCashfreeWebhook handles POST /webhooks/cashfree
 func (h *WebhookHandler) HandleCashfreeWebhook(c *gin.Context) {
-	// TODO: Implement Cashfree webhook handling
-	c.JSON(http.StatusNotImplemented, models.ErrorResponse{
-		Error:   "Not implemented",
-		Message: "Cashfree webhook handling not implemented yet",
+	timestamp := c.GetHeader("x-webhook-timestamp")
+	signature := c.GetHeader("x-webhook-signature")
+	if timestamp == "" || signature == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Missing signature",
+			Message: "x-webhook-timestamp and x-webhook-signature headers are required",
+		})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Failed to read request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Tenant ID comes from the notify_url query param, falling back to the order tags
+	tenantID := c.Query("tenant_id")
+	if tenantID == "" {
+		tenantID = extractTenantFromCashfreePayload(body)
+	}
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "tenant_id not found in query param or order tags",
+		})
+		return
+	}
+
+	if err := h.service.ProcessCashfreeWebhook(c.Request.Context(), body, timestamp, signature, tenantID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to process webhook",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook processed successfully",
 	})
 }
 
+// extractTenantFromCashfreePayload extracts tenant_id from Cashfree order tags
+func extractTenantFromCashfreePayload(body []byte) string {
+	var payload struct {
+		Data struct {
+			Order struct {
+				OrderTags map[string]string `json:"order_tags"`
+			} `json:"order"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return payload.Data.Order.OrderTags["tenant_id"]
+}
+
 // HandlePhonePeWebhook handles POST /webhooks/phonepe
 func (h *WebhookHandler) HandlePhonePeWebhook(c *gin.Context) {
-	// TODO: Implement PhonePe webhook handling
-	c.JSON(http.StatusNotImplemented, models.ErrorResponse{
-		Error:   "Not implemented",
-		Message: "PhonePe webhook handling not implemented yet",
+	signature := c.GetHeader("X-VERIFY")
+	if signature == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Missing signature",
+			Message: "X-VERIFY header is required",
+		})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Failed to read request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// PhonePe callbacks carry no merchant metadata, so the tenant comes from the callback URL
+	tenantID := c.Query("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Missing tenant ID",
+			Message: "tenant_id query param is required",
+		})
+		return
+	}
+
+	if err := h.service.ProcessPhonePeWebhook(c.Request.Context(), body, signature, tenantID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to process webhook",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook processed successfully",
 	})
 }
 
@@ -0,0 +1,26 @@
+// Package metrics records the service's business metrics, served on /metrics with the event
+// consumer metrics. Labels are bounded sets (gateway, outcome), never tenant or
+// payment IDs.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var paymentsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tesseract",
+	Subsystem: "business",
+	Name:      "payments_total",
+	Help:      "Payments reaching a final outcome, by gateway and result (succeeded, failed)",
+}, []string{"gateway", "result"})
+
+// RecordPaymentOutcome counts a payment that succeeded or failed. The success rate per gateway
+// is succeeded / (succeeded + failed).
+func RecordPaymentOutcome(gateway string, succeeded bool) {
+	result := "failed"
+	if succeeded {
+		result = "succeeded"
+	}
+	paymentsTotal.WithLabelValues(gateway, result).Inc()
+}
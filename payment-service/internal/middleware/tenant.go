@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"payment-service/internal/tenancy"
 )
 
 // Context keys for tenant information
@@ -89,6 +91,8 @@ func TenantMiddleware() gin.HandlerFunc {
 		ctx = context.WithValue(ctx, UserIDKey, userID)
 		ctx = context.WithValue(ctx, VendorIDKey, vendorID)
 		ctx = context.WithValue(ctx, RequestIDKey, requestID)
+		// Scope database statements made with the request context (see internal/tenancy)
+		ctx = tenancy.WithTenant(ctx, tenantID)
 		c.Request = c.Request.WithContext(ctx)
 
 		// Store tenant context in Gin context
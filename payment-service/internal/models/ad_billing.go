@@ -94,6 +94,9 @@ type AdCampaignPayment struct {
 	// Campaign duration (for commission calculation)
 	CampaignDays int `gorm:"" json:"campaignDays,omitempty"`
 
+	// Set on budget top-ups to the campaign's original payment
+	ParentPaymentID *uuid.UUID `gorm:"type:uuid;index:idx_ad_campaign_payments_parent" json:"parentPaymentId,omitempty"`
+
 	// Link to payment transaction
 	PaymentTransactionID *uuid.UUID `gorm:"type:uuid" json:"paymentTransactionId,omitempty"`
 
@@ -189,6 +192,9 @@ type AdRevenueLedger struct {
 	// Description
 	Description string `gorm:"type:text" json:"description,omitempty"`
 
+	// Ads system reference for a SPEND entry, so a retried spend report is only counted once
+	SpendRef *string `gorm:"type:varchar(255)" json:"spendRef,omitempty"`
+
 	// Metadata
 	Metadata JSONB `gorm:"type:jsonb" json:"metadata,omitempty"`
 
@@ -230,6 +236,53 @@ func (AdVendorBalance) TableName() string {
 	return "ad_vendor_balances"
 }
 
+// AdBudgetStatus represents whether a campaign still has budget to spend
+type AdBudgetStatus string
+
+const (
+	AdBudgetActive    AdBudgetStatus = "ACTIVE"
+	AdBudgetExhausted AdBudgetStatus = "EXHAUSTED"
+)
+
+// AdPaceStatus compares a campaign's spend with an even spread of its budget over its schedule
+type AdPaceStatus string
+
+const (
+	AdPaceOnTrack AdPaceStatus = "ON_TRACK"
+	AdPaceAhead   AdPaceStatus = "AHEAD"
+	AdPaceBehind  AdPaceStatus = "BEHIND"
+)
+
+// AdCampaignBudget tracks a campaign's paid budget against the spend reported by the ads system.
+// Created when the campaign's first payment is confirmed; top-ups add to BudgetAmount.
+type AdCampaignBudget struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string         `gorm:"type:varchar(255);not null;uniqueIndex:idx_ad_campaign_budgets_campaign,priority:1" json:"tenantId"`
+	VendorID    uuid.UUID      `gorm:"type:uuid;not null;index:idx_ad_campaign_budgets_vendor" json:"vendorId"`
+	CampaignID  uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_ad_campaign_budgets_campaign,priority:2" json:"campaignId"`
+	PaymentType AdPaymentType  `gorm:"type:varchar(20);not null" json:"paymentType"`
+	Status      AdBudgetStatus `gorm:"type:varchar(20);not null;default:'ACTIVE'" json:"status"`
+
+	BudgetAmount float64 `gorm:"type:decimal(12,2);not null;default:0" json:"budgetAmount"`
+	SpentAmount  float64 `gorm:"type:decimal(12,2);not null;default:0" json:"spentAmount"`
+	Currency     string  `gorm:"type:varchar(3);default:'USD'" json:"currency"`
+
+	// Schedule the budget is paced over
+	StartsAt time.Time `gorm:"not null" json:"startsAt"`
+	EndsAt   time.Time `gorm:"not null" json:"endsAt"`
+
+	ExhaustedAt *time.Time `json:"exhaustedAt,omitempty"`
+	LastSpendAt *time.Time `json:"lastSpendAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+}
+
+// TableName specifies the table name for AdCampaignBudget
+func (AdCampaignBudget) TableName() string {
+	return "ad_campaign_budgets"
+}
+
 // CommissionCalculation holds the result of commission calculation
 type CommissionCalculation struct {
 	TierID           uuid.UUID `json:"tierId"`
@@ -281,3 +334,27 @@ type CalculateCommissionRequest struct {
 	CampaignDays int     `json:"campaignDays" binding:"required,gt=0"`
 	BudgetAmount float64 `json:"budgetAmount" binding:"required,gt=0"`
 }
+
+// RecordAdSpendRequest represents spend reported by the ads system for a campaign
+type RecordAdSpendRequest struct {
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	SpendID     string  `json:"spendId"` // Ads system reference; a repeated spendId is ignored
+	Description string  `json:"description"`
+}
+
+// TopUpAdBudgetRequest represents a request to add budget to a running campaign
+type TopUpAdBudgetRequest struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+}
+
+// AdBudgetPacing is a campaign budget with its remaining amount and pacing
+type AdBudgetPacing struct {
+	AdCampaignBudget
+	RemainingAmount float64      `json:"remainingAmount"`
+	OverspentAmount float64      `json:"overspentAmount,omitempty"`
+	ExpectedSpend   float64      `json:"expectedSpend"` // Spend by now if the budget were spread evenly over the schedule
+	DailyBudget     float64      `json:"dailyBudget"`   // Remaining budget spread over the days left
+	DaysRemaining   int          `json:"daysRemaining"`
+	PaceStatus      AdPaceStatus `json:"paceStatus"`
+	DuplicateSpend  bool         `json:"duplicateSpend,omitempty"` // The reported spendId was already recorded
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommissionTaxScheme is the indirect tax charged on the marketplace's commission
+type CommissionTaxScheme string
+
+const (
+	// CommissionTaxNone charges no tax on commission
+	CommissionTaxNone CommissionTaxScheme = "NONE"
+	// CommissionTaxGST charges Indian GST: CGST + SGST within the marketplace's state, IGST across states
+	CommissionTaxGST CommissionTaxScheme = "GST"
+	// CommissionTaxVAT charges VAT, reverse charged to VAT-registered vendors in other countries
+	CommissionTaxVAT CommissionTaxScheme = "VAT"
+)
+
+// CommissionInvoiceSettings is how a tenant's marketplace invoices its vendors for commission:
+// the legal entity shown as the issuer, the tax charged and the invoice numbering. NextSequence
+// is the number the next invoice gets; invoices are numbered without gaps per tenant.
+type CommissionInvoiceSettings struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_commission_invoice_settings_tenant" json:"tenantId"`
+	Enabled  bool      `gorm:"default:false" json:"enabled"`
+
+	// Issuer shown on the invoice
+	LegalName string `gorm:"type:varchar(255)" json:"legalName"`
+	Address   string `gorm:"type:text" json:"address"`
+	TaxID     string `gorm:"type:varchar(50)" json:"taxId,omitempty"` // GSTIN or VAT number
+	Country   string `gorm:"type:varchar(2)" json:"country"`
+	State     string `gorm:"type:varchar(100)" json:"state,omitempty"` // decides intra- vs inter-state GST
+
+	// Tax on commission
+	TaxScheme CommissionTaxScheme `gorm:"type:varchar(10);default:'NONE'" json:"taxScheme"`
+	TaxRate   float64             `gorm:"type:decimal(5,2);default:0" json:"taxRate"` // percent, e.g. 18 for 18% GST
+
+	// Numbering: <InvoicePrefix>-<year>-<sequence>
+	InvoicePrefix string `gorm:"type:varchar(20);default:'COM'" json:"invoicePrefix"`
+	NextSequence  int64  `gorm:"default:1" json:"nextSequence"`
+
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+}
+
+// TableName specifies the table name for CommissionInvoiceSettings
+func (CommissionInvoiceSettings) TableName() string {
+	return "commission_invoice_settings"
+}
+
+// CommissionInvoice bills a marketplace vendor for the platform fees charged on their orders in
+// a calendar month, net of fees refunded, with the tax due on that commission. The issuer and
+// vendor details are copied onto the invoice when it is issued so it never changes afterwards.
+type CommissionInvoice struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID      string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_commission_invoices_number;uniqueIndex:idx_commission_invoices_period" json:"tenantId"`
+	VendorID      string    `gorm:"type:varchar(255);not null;index:idx_commission_invoices_vendor;uniqueIndex:idx_commission_invoices_period" json:"vendorId"`
+	InvoiceNumber string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_commission_invoices_number" json:"invoiceNumber"`
+	Sequence      int64     `gorm:"not null" json:"sequence"`
+
+	// Billing period: the calendar month the fees were charged in
+	PeriodStart time.Time `gorm:"not null;uniqueIndex:idx_commission_invoices_period" json:"periodStart"`
+	PeriodEnd   time.Time `gorm:"not null" json:"periodEnd"`
+	Currency    string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_commission_invoices_period" json:"currency"`
+
+	// Vendor billed
+	VendorName    string `gorm:"type:varchar(255)" json:"vendorName"`
+	VendorTaxID   string `gorm:"type:varchar(50)" json:"vendorTaxId,omitempty"`
+	VendorAddress string `gorm:"type:text" json:"vendorAddress,omitempty"`
+	VendorCountry string `gorm:"type:varchar(100)" json:"vendorCountry,omitempty"`
+	VendorState   string `gorm:"type:varchar(100)" json:"vendorState,omitempty"`
+
+	// Issuer
+	IssuerName    string `gorm:"type:varchar(255)" json:"issuerName"`
+	IssuerTaxID   string `gorm:"type:varchar(50)" json:"issuerTaxId,omitempty"`
+	IssuerAddress string `gorm:"type:text" json:"issuerAddress,omitempty"`
+
+	// Amounts
+	CommissionAmount float64 `gorm:"type:decimal(12,2);not null" json:"commissionAmount"`
+	RefundedAmount   float64 `gorm:"type:decimal(12,2);default:0" json:"refundedAmount"`
+	TaxableAmount    float64 `gorm:"type:decimal(12,2);not null" json:"taxableAmount"`
+
+	// Tax on the taxable amount. Only the components of the scheme applied are set.
+	TaxScheme     CommissionTaxScheme `gorm:"type:varchar(10);not null" json:"taxScheme"`
+	TaxRate       float64             `gorm:"type:decimal(5,2);default:0" json:"taxRate"`
+	CGSTAmount    float64             `gorm:"type:decimal(12,2);default:0" json:"cgstAmount,omitempty"`
+	SGSTAmount    float64             `gorm:"type:decimal(12,2);default:0" json:"sgstAmount,omitempty"`
+	IGSTAmount    float64             `gorm:"type:decimal(12,2);default:0" json:"igstAmount,omitempty"`
+	VATAmount     float64             `gorm:"type:decimal(12,2);default:0" json:"vatAmount,omitempty"`
+	TaxAmount     float64             `gorm:"type:decimal(12,2);default:0" json:"taxAmount"`
+	TotalAmount   float64             `gorm:"type:decimal(12,2);not null" json:"totalAmount"`
+	ReverseCharge bool                `gorm:"default:false" json:"reverseCharge"`
+	TaxNote       string              `gorm:"type:text" json:"taxNote,omitempty"`
+
+	// Fee ledger entries billed, one line each
+	EntryCount int                     `gorm:"default:0" json:"entryCount"`
+	LineItems  []CommissionInvoiceLine `gorm:"type:jsonb;serializer:json" json:"lineItems,omitempty"`
+
+	IssuedAt  time.Time `gorm:"not null;index:idx_commission_invoices_issued" json:"issuedAt"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+}
+
+// TableName specifies the table name for CommissionInvoice
+func (CommissionInvoice) TableName() string {
+	return "commission_invoices"
+}
+
+// CommissionInvoiceLine is a platform fee ledger entry billed on a commission invoice. Amount
+// is negative for fees refunded.
+type CommissionInvoiceLine struct {
+	LedgerEntryID uuid.UUID       `json:"ledgerEntryId"`
+	EntryType     LedgerEntryType `json:"entryType"`
+	OrderID       *uuid.UUID      `json:"orderId,omitempty"`
+	Date          time.Time       `json:"date"`
+	Amount        float64         `json:"amount"`
+}
@@ -18,6 +18,8 @@ type CreatePaymentIntentRequest struct {
 	Metadata       map[string]string `json:"metadata"`
 	ReturnURL      string            `json:"returnUrl"`  // For redirect-based gateways (PayPal)
 	CancelURL      string            `json:"cancelUrl"`  // For redirect-based gateways (PayPal)
+	UPIApp         string            `json:"upiApp"`     // UPI_INTENT: target app package (e.g. com.phonepe.app), optional
+	VPA            string            `json:"vpa"`        // UPI collect: customer's UPI ID
 }
 
 // PaymentIntentResponse represents the response after creating a payment intent
@@ -48,6 +50,22 @@ type PaymentIntentResponse struct {
 	// PayPal specific
 	PayPalOrderID     string          `json:"paypalOrderId,omitempty"`
 	PayPalApprovalURL string          `json:"paypalApprovalUrl,omitempty"`
+
+	// UPI intent / QR / collect (Razorpay, PhonePe, Cashfree)
+	UPI *UPIPaymentDetails `json:"upi,omitempty"`
+}
+
+// UPIPaymentDetails tells the storefront how to complete a UPI payment. Intent flows open
+// IntentURL on mobile; QR flows render QRPayload (or show QRImageURL); collect flows wait for
+// the customer to approve the request in their UPI app. In all cases the storefront polls
+// the payment's upi-status endpoint until it leaves PENDING or ExpiresAt passes.
+type UPIPaymentDetails struct {
+	Flow                PaymentMethodType `json:"flow"`
+	IntentURL           string            `json:"intentUrl,omitempty"`
+	QRPayload           string            `json:"qrPayload,omitempty"`
+	QRImageURL          string            `json:"qrImageUrl,omitempty"`
+	ExpiresAt           int64             `json:"expiresAt"`
+	PollIntervalSeconds int               `json:"pollIntervalSeconds"`
 }
 
 // ConfirmPaymentRequest represents a request to confirm a payment
@@ -68,6 +86,57 @@ type CreateRefundRequest struct {
 	Amount float64 `json:"amount" binding:"required"`
 	Reason string  `json:"reason"`
 	Notes  string  `json:"notes"`
+	// Tenders overrides the tenant's refund fallback chain for this refund,
+	// e.g. when the customer asked for store credit
+	Tenders []RefundTender `json:"tenders,omitempty"`
+	// ReturnID links a refund for a returned item to the return, so a
+	// marketplace vendor's payout clawback can be traced back to it
+	ReturnID string `json:"returnId,omitempty"`
+}
+
+// RefundPlanLineTender is where one line of a refund plan pays out
+type RefundPlanLineTender string
+
+const (
+	RefundPlanPayment  RefundPlanLineTender = "PAYMENT"   // A payment of the order, through the refund fallback chain
+	RefundPlanGiftCard RefundPlanLineTender = "GIFT_CARD" // A gift card the order was paid with, credited back
+)
+
+// RefundPlanRequest refunds one order across the tenders it was paid with. orders-service
+// sends one when a return completes; each line is executed on its own, so a failed line
+// doesn't undo the others.
+type RefundPlanRequest struct {
+	OrderID  string           `json:"orderId" binding:"required,uuid"`
+	ReturnID string           `json:"returnId,omitempty"`
+	Reason   string           `json:"reason"`
+	Notes    string           `json:"notes"`
+	Lines    []RefundPlanLine `json:"lines" binding:"required,min=1,max=20,dive"`
+}
+
+// RefundPlanLine is the part of a refund plan paid out to one tender
+type RefundPlanLine struct {
+	Tender       RefundPlanLineTender `json:"tender" binding:"required,oneof=PAYMENT GIFT_CARD"`
+	PaymentID    string               `json:"paymentId,omitempty"`    // PAYMENT lines
+	GiftCardCode string               `json:"giftCardCode,omitempty"` // GIFT_CARD lines
+	Amount       float64              `json:"amount" binding:"required,gt=0"`
+	// Tenders overrides the tenant's refund fallback chain for a PAYMENT line
+	Tenders []RefundTender `json:"tenders,omitempty"`
+}
+
+// RefundPlanLineResult is the outcome of one refund plan line
+type RefundPlanLineResult struct {
+	Tender    RefundPlanLineTender `json:"tender"`
+	Amount    float64              `json:"amount"`
+	Status    RefundStatus         `json:"status"`
+	RefundID  string               `json:"refundId,omitempty"`  // PAYMENT lines
+	Reference string               `json:"reference,omitempty"` // Gateway refund ID, or the gift card's ID
+	Error     string               `json:"error,omitempty"`
+}
+
+// RefundPlanResponse has a result for every line of a refund plan, in request order
+type RefundPlanResponse struct {
+	OrderID string                 `json:"orderId"`
+	Lines   []RefundPlanLineResult `json:"lines"`
 }
 
 // RefundResponse represents the response after creating a refund
@@ -77,10 +146,19 @@ type RefundResponse struct {
 	Amount          float64       `json:"amount"`
 	Currency        string        `json:"currency"`
 	Status          RefundStatus  `json:"status"`
+	Tender          RefundTender  `json:"tender,omitempty"`
 	GatewayRefundID string        `json:"gatewayRefundId,omitempty"`
+	Attempts        []RefundTenderAttempt `json:"attempts,omitempty"`
 	CreatedAt       string        `json:"createdAt"`
 }
 
+// RecordBankTransferRequest records the outcome of a manual bank transfer refund
+type RecordBankTransferRequest struct {
+	Status        RefundAttemptStatus `json:"status" binding:"required,oneof=SUCCEEDED FAILED"`
+	Reference     string              `json:"reference"`
+	OperatorNotes string              `json:"operatorNotes"`
+}
+
 // PaymentStatusResponse represents a payment status
 type PaymentStatusResponse struct {
 	ID                   string            `json:"id"`
@@ -96,10 +174,46 @@ type PaymentStatusResponse struct {
 	BillingName          string            `json:"billingName,omitempty"`
 	FailureCode          string            `json:"failureCode,omitempty"`
 	FailureMessage       string            `json:"failureMessage,omitempty"`
+	GatewayFee           float64           `json:"gatewayFee,omitempty"`
+	FXFee                float64           `json:"fxFee,omitempty"`
+	SettlementCurrency   string            `json:"settlementCurrency,omitempty"`
+	ExchangeRate         float64           `json:"exchangeRate,omitempty"`
+	SettlementAmount     float64           `json:"settlementAmount,omitempty"`
+	SettlementNetAmount  float64           `json:"settlementNetAmount,omitempty"`
 	ProcessedAt          *string           `json:"processedAt,omitempty"`
 	CreatedAt            string            `json:"createdAt"`
 }
 
+// RecordSettlementRequest records a payment's settlement from a gateway settlement report.
+// All amounts are in the settlement currency.
+type RecordSettlementRequest struct {
+	SettlementCurrency string  `json:"settlementCurrency" binding:"required,len=3"`
+	ExchangeRate       float64 `json:"exchangeRate" binding:"required,gt=0"`
+	SettlementAmount   float64 `json:"settlementAmount" binding:"omitempty,gte=0"` // Derived from the rate when omitted
+	GatewayFee         float64 `json:"gatewayFee" binding:"gte=0"`                 // Total fee, including FXFee
+	FXFee              float64 `json:"fxFee" binding:"gte=0"`
+	GatewayTax         float64 `json:"gatewayTax" binding:"gte=0"`
+}
+
+// UpdateCommissionInvoiceSettingsRequest sets how a tenant invoices its vendors for commission.
+// TaxRate is a percentage; numbering continues from the current sequence when the prefix changes.
+type UpdateCommissionInvoiceSettingsRequest struct {
+	Enabled       bool                `json:"enabled"`
+	LegalName     string              `json:"legalName" binding:"required"`
+	Address       string              `json:"address" binding:"required"`
+	TaxID         string              `json:"taxId"`
+	Country       string              `json:"country" binding:"required,len=2"`
+	State         string              `json:"state"`
+	TaxScheme     CommissionTaxScheme `json:"taxScheme" binding:"required,oneof=NONE GST VAT"`
+	TaxRate       float64             `json:"taxRate" binding:"gte=0,lte=100"`
+	InvoicePrefix string              `json:"invoicePrefix" binding:"omitempty,max=20"`
+}
+
+// GenerateCommissionInvoicesRequest invoices vendors for a month that has ended, as YYYY-MM
+type GenerateCommissionInvoicesRequest struct {
+	Month string `json:"month" binding:"required"`
+}
+
 // SavePaymentMethodRequest represents a request to save a payment method
 type SavePaymentMethodRequest struct {
 	TenantID               string            `json:"tenantId" binding:"required"`
@@ -137,6 +251,10 @@ type GatewayConfigRequest struct {
 	IsEnabled             bool              `json:"isEnabled"`
 	IsTestMode            bool              `json:"isTestMode"`
 
+	// Storefront override of the tenant-level config of the same type (empty for the tenant default)
+	StorefrontID          string            `json:"storefrontId"`
+	OverriddenFields      []string          `json:"overriddenFields"`
+
 	// Legacy credential fields (for backwards compatibility with Stripe/Razorpay)
 	APIKeyPublic          string            `json:"apiKeyPublic"`
 	APIKeySecret          string            `json:"apiKeySecret"`
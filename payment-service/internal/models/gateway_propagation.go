@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Gateway config settings that storefront overrides inherit from the tenant default. They are
+// the names used in OverriddenFields, propagation requests and drift reports. Credentials are
+// not among them: they live in the secret manager per tenant and are shared by all storefronts.
+const (
+	GatewayFieldDisplayName             = "displayName"
+	GatewayFieldIsEnabled               = "isEnabled"
+	GatewayFieldIsTestMode              = "isTestMode"
+	GatewayFieldConfig                  = "config"
+	GatewayFieldFeatures                = "features"      // supportsPayments, supportsRefunds, supportsSubscriptions
+	GatewayFieldPlatformSplit           = "platformSplit" // merchantAccountId, platformAccountId, supportsPlatformSplit
+	GatewayFieldSupportedCountries      = "supportedCountries"
+	GatewayFieldSupportedPaymentMethods = "supportedPaymentMethods"
+	GatewayFieldFeeStructure            = "feeStructure"
+	GatewayFieldLimits                  = "limits"  // minimumAmount, maximumAmount
+	GatewayFieldDisplay                 = "display" // priority, description, logoUrl
+)
+
+// PropagateGatewayConfigRequest applies a tenant-level gateway config to storefronts. Fields
+// limits which settings are copied (all by default). Settings a storefront overrides are skipped
+// unless Force is set, which also clears the override.
+type PropagateGatewayConfigRequest struct {
+	StorefrontIDs []string `json:"storefrontIds" binding:"required,min=1"`
+	Fields        []string `json:"fields,omitempty"`
+	Force         bool     `json:"force"`
+}
+
+// GatewayPropagationAction is what propagation did to a storefront's config
+type GatewayPropagationAction string
+
+const (
+	GatewayPropagationCreated   GatewayPropagationAction = "created"
+	GatewayPropagationUpdated   GatewayPropagationAction = "updated"
+	GatewayPropagationUnchanged GatewayPropagationAction = "unchanged"
+	GatewayPropagationFailed    GatewayPropagationAction = "failed"
+)
+
+// GatewayPropagationResult is the outcome of propagation for one storefront
+type GatewayPropagationResult struct {
+	StorefrontID  string                   `json:"storefrontId"`
+	ConfigID      uuid.UUID                `json:"configId,omitempty"`
+	Action        GatewayPropagationAction `json:"action"`
+	UpdatedFields []string                 `json:"updatedFields,omitempty"`
+	SkippedFields []string                 `json:"skippedFields,omitempty"` // Overridden by the storefront
+	Error         string                   `json:"error,omitempty"`
+}
+
+// GatewayConfigDrift is a storefront config that no longer matches the tenant default on
+// settings it doesn't deliberately override, or that has no tenant default to inherit from
+type GatewayConfigDrift struct {
+	StorefrontID     string      `json:"storefrontId"`
+	GatewayType      GatewayType `json:"gatewayType"`
+	ConfigID         uuid.UUID   `json:"configId"`
+	DefaultConfigID  *uuid.UUID  `json:"defaultConfigId,omitempty"`
+	MissingDefault   bool        `json:"missingDefault,omitempty"`
+	DriftedFields    []string    `json:"driftedFields,omitempty"`
+	OverriddenFields []string    `json:"overriddenFields,omitempty"`
+}
+
+// GatewayDriftReport summarizes drift across a tenant's storefront gateway configs
+type GatewayDriftReport struct {
+	CheckedAt         time.Time            `json:"checkedAt"`
+	StorefrontConfigs int                  `json:"storefrontConfigs"`
+	Drifted           int                  `json:"drifted"`
+	Drift             []GatewayConfigDrift `json:"drift"`
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -64,6 +65,21 @@ const (
 	MethodKlarna        PaymentMethodType = "KLARNA"
 	MethodRuPay         PaymentMethodType = "RUPAY"
 	MethodCardlessEMI   PaymentMethodType = "CARDLESS_EMI"
+	MethodUPIIntent     PaymentMethodType = "UPI_INTENT" // Opens the customer's UPI app via a upi:// deep link
+	MethodUPIQR         PaymentMethodType = "UPI_QR"     // Dynamic QR code scanned with any UPI app
+)
+
+// IsUPI reports whether the method is one of the UPI flows (collect, intent or QR)
+func (m PaymentMethodType) IsUPI() bool {
+	return m == MethodUPI || m == MethodUPIIntent || m == MethodUPIQR
+}
+
+// PaymentChannel represents where a payment was taken
+type PaymentChannel string
+
+const (
+	ChannelOnline   PaymentChannel = "ONLINE"
+	ChannelInPerson PaymentChannel = "IN_PERSON"
 )
 
 // FeePayer represents who pays the platform fee
@@ -115,6 +131,37 @@ const (
 	RefundCanceled  RefundStatus = "CANCELED"
 )
 
+// RefundTender is where a refund's money goes back to the customer
+type RefundTender string
+
+const (
+	TenderOriginal     RefundTender = "ORIGINAL_TENDER" // The card, UPI or wallet that paid, via the gateway
+	TenderStoreCredit  RefundTender = "STORE_CREDIT"    // A gift card issued through gift-cards-service
+	TenderBankTransfer RefundTender = "BANK_TRANSFER"   // A manual transfer an operator makes and records
+)
+
+// DefaultRefundFallbackChain is used when a tenant hasn't configured its own chain
+var DefaultRefundFallbackChain = []RefundTender{TenderOriginal, TenderStoreCredit, TenderBankTransfer}
+
+// IsValid reports whether the tender is a known refund tender
+func (t RefundTender) IsValid() bool {
+	switch t {
+	case TenderOriginal, TenderStoreCredit, TenderBankTransfer:
+		return true
+	}
+	return false
+}
+
+// RefundAttemptStatus represents the outcome of refunding to one tender in the chain
+type RefundAttemptStatus string
+
+const (
+	RefundAttemptPending   RefundAttemptStatus = "PENDING"   // Awaiting the gateway or an operator
+	RefundAttemptSucceeded RefundAttemptStatus = "SUCCEEDED"
+	RefundAttemptFailed    RefundAttemptStatus = "FAILED"
+	RefundAttemptSkipped   RefundAttemptStatus = "SKIPPED"   // Tender not usable for this payment
+)
+
 // DisputeStatus represents the dispute status
 type DisputeStatus string
 
@@ -242,10 +289,16 @@ type PaymentGatewayConfig struct {
 	IsEnabled              bool        `gorm:"default:true;index:idx_payment_gateways_enabled" json:"isEnabled"`
 	IsTestMode             bool        `gorm:"default:true" json:"isTestMode"`
 
+	// Storefront override: empty for the tenant-level default. A storefront's config replaces the
+	// default of the same gateway type on that storefront; OverriddenFields lists the settings it
+	// deliberately sets differently, which propagation and drift detection leave alone
+	StorefrontID           string      `gorm:"type:varchar(255);not null;default:'';index:idx_payment_gateways_storefront" json:"storefrontId,omitempty"`
+	OverriddenFields       StringArray `gorm:"type:text[]" json:"overriddenFields,omitempty"`
+
 	// API Credentials
 	APIKeyPublic           string      `gorm:"type:text" json:"apiKeyPublic"`
-	APIKeySecret           string      `gorm:"type:text" json:"-"` // Never expose in JSON
-	WebhookSecret          string      `gorm:"type:text" json:"-"` // Never expose in JSON
+	APIKeySecret           string      `gorm:"type:text;serializer:encrypted" json:"-"` // Never expose in JSON; encrypted with the tenant's data key
+	WebhookSecret          string      `gorm:"type:text;serializer:encrypted" json:"-"` // Never expose in JSON; encrypted with the tenant's data key
 
 	// Configuration
 	Config                 JSONB       `gorm:"type:jsonb" json:"config"`
@@ -278,6 +331,7 @@ type PaymentGatewayConfig struct {
 
 	CreatedAt              time.Time   `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
 	UpdatedAt              time.Time   `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	Version                int         `gorm:"not null;default:1" json:"version"` // Optimistic locking, bumped on every update
 
 	// Relationships
 	Regions                []PaymentGatewayRegion `gorm:"foreignKey:GatewayConfigID" json:"regions,omitempty"`
@@ -288,6 +342,15 @@ func (PaymentGatewayConfig) TableName() string {
 	return "payment_gateway_configs"
 }
 
+// VersionConflictError is returned when a gateway config update was based on a stale version
+type VersionConflictError struct {
+	CurrentVersion int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("gateway config version conflict: current version is %d", e.CurrentVersion)
+}
+
 // PaymentTransaction represents a payment transaction
 type PaymentTransaction struct {
 	ID                    uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
@@ -310,6 +373,15 @@ type PaymentTransaction struct {
 	NetAmount             float64           `gorm:"type:decimal(12,2)" json:"netAmount,omitempty"`
 	Currency              string            `gorm:"type:varchar(3);default:'USD'" json:"currency"`
 
+	// Settlement: what the gateway paid into the merchant's balance. Differs from Currency for
+	// cross-border payments. FXFee is in Currency, the settlement amounts in SettlementCurrency.
+	SettlementCurrency    string            `gorm:"type:varchar(3)" json:"settlementCurrency,omitempty"`
+	ExchangeRate          float64           `gorm:"type:decimal(18,8)" json:"exchangeRate,omitempty"` // Settlement units per unit of Currency
+	SettlementAmount      float64           `gorm:"type:decimal(12,2)" json:"settlementAmount,omitempty"`
+	FXFee                 float64           `gorm:"type:decimal(12,2);default:0" json:"fxFee"`
+	SettlementNetAmount   float64           `gorm:"type:decimal(12,2)" json:"settlementNetAmount,omitempty"` // Payout after gateway, FX and platform fees
+	SettlementRecordedAt  *time.Time        `json:"settlementRecordedAt,omitempty"`
+
 	// Idempotency and Retry
 	IdempotencyKey        string            `gorm:"type:varchar(255);index:idx_payment_transactions_idempotency" json:"idempotencyKey,omitempty"`
 	RetryCount            int               `gorm:"default:0" json:"retryCount"`
@@ -318,6 +390,11 @@ type PaymentTransaction struct {
 	// Geo info
 	CountryCode           string            `gorm:"type:varchar(2);index:idx_payment_transactions_country" json:"countryCode,omitempty"`
 
+	// Channel: online checkout or in-person at a store terminal
+	Channel               PaymentChannel    `gorm:"type:varchar(20);not null;default:'ONLINE';index:idx_payment_transactions_channel" json:"channel"`
+	TerminalReaderID      *uuid.UUID        `gorm:"type:uuid" json:"terminalReaderId,omitempty"`
+	LocationID            string            `gorm:"type:varchar(255);index:idx_payment_transactions_location" json:"locationId,omitempty"`
+
 	// Status
 	Status                PaymentStatus     `gorm:"type:varchar(50);not null;index:idx_payment_transactions_status" json:"status"`
 	PaymentMethodType     PaymentMethodType `gorm:"type:varchar(50)" json:"paymentMethodType,omitempty"`
@@ -379,6 +456,9 @@ type RefundTransaction struct {
 	Status               RefundStatus `gorm:"type:varchar(50);not null;index:idx_refunds_status" json:"status"`
 	Reason               string       `gorm:"type:varchar(255)" json:"reason,omitempty"`
 
+	// Tender the refund was (or is being) paid out to, from the fallback chain
+	Tender               RefundTender `gorm:"type:varchar(30)" json:"tender,omitempty"`
+
 	// Processing
 	ProcessedAt          *time.Time   `json:"processedAt,omitempty"`
 	FailedAt             *time.Time   `json:"failedAt,omitempty"`
@@ -396,6 +476,7 @@ type RefundTransaction struct {
 	// Relationships
 	PaymentTransaction   *PaymentTransaction `gorm:"foreignKey:PaymentTransactionID" json:"paymentTransaction,omitempty"`
 	FeeLedgerEntries     []PlatformFeeLedger `gorm:"foreignKey:RefundTransactionID" json:"feeLedgerEntries,omitempty"`
+	Attempts             []RefundTenderAttempt `gorm:"foreignKey:RefundTransactionID" json:"attempts,omitempty"`
 }
 
 // TableName specifies the table name for RefundTransaction
@@ -403,6 +484,34 @@ func (RefundTransaction) TableName() string {
 	return "refund_transactions"
 }
 
+// RefundTenderAttempt records one step of a refund's fallback chain
+type RefundTenderAttempt struct {
+	ID                  uuid.UUID           `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID            string              `gorm:"type:varchar(255);not null;index:idx_refund_tender_attempts_tenant" json:"tenantId"`
+	RefundTransactionID uuid.UUID           `gorm:"type:uuid;not null;index:idx_refund_tender_attempts_refund" json:"refundTransactionId"`
+	Sequence            int                 `gorm:"not null" json:"sequence"`
+	Tender              RefundTender        `gorm:"type:varchar(30);not null" json:"tender"`
+	Status              RefundAttemptStatus `gorm:"type:varchar(20);not null" json:"status"`
+
+	// Gateway refund ID, gift card ID or bank transfer reference (UTR)
+	Reference           string              `gorm:"type:varchar(255)" json:"reference,omitempty"`
+	FailureMessage      string              `gorm:"type:text" json:"failureMessage,omitempty"`
+
+	// Manual bank transfers are completed by an operator
+	OperatorNotes       string              `gorm:"type:text" json:"operatorNotes,omitempty"`
+	ProcessedBy         string              `gorm:"type:varchar(255)" json:"processedBy,omitempty"`
+	ProcessedAt         *time.Time          `json:"processedAt,omitempty"`
+
+	Metadata            JSONB               `gorm:"type:jsonb" json:"metadata,omitempty"`
+	CreatedAt           time.Time           `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt           time.Time           `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+}
+
+// TableName specifies the table name for RefundTenderAttempt
+func (RefundTenderAttempt) TableName() string {
+	return "refund_tender_attempts"
+}
+
 // WebhookEvent represents a webhook event from a payment gateway
 type WebhookEvent struct {
 	ID                   uuid.UUID   `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
@@ -552,6 +661,7 @@ type PaymentSettings struct {
 	// Currency
 	DefaultCurrency                   string    `gorm:"type:varchar(3);default:'USD'" json:"defaultCurrency"`
 	SupportedCurrencies               []string  `gorm:"type:varchar(3)[]" json:"supportedCurrencies"`
+	SettlementCurrency                string    `gorm:"type:varchar(3)" json:"settlementCurrency,omitempty"` // Payout currency; defaults to DefaultCurrency
 
 	// Platform Fees (5% by default)
 	PlatformFeeEnabled                bool      `gorm:"default:true" json:"platformFeeEnabled"`
@@ -581,6 +691,9 @@ type PaymentSettings struct {
 	SendPaymentReceipts               bool      `gorm:"default:true" json:"sendPaymentReceipts"`
 	SendRefundNotifications           bool      `gorm:"default:true" json:"sendRefundNotifications"`
 
+	// Refunds: tenders tried in order when the previous one can't be used
+	RefundFallbackChain               StringArray `gorm:"type:varchar(30)[]" json:"refundFallbackChain,omitempty"`
+
 	CreatedAt                         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
 	UpdatedAt                         time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
 }
@@ -590,6 +703,18 @@ func (PaymentSettings) TableName() string {
 	return "payment_settings"
 }
 
+// RefundChain returns the tenant's refund fallback chain, or the default when none is set
+func (s *PaymentSettings) RefundChain() []RefundTender {
+	if s == nil || len(s.RefundFallbackChain) == 0 {
+		return DefaultRefundFallbackChain
+	}
+	chain := make([]RefundTender, 0, len(s.RefundFallbackChain))
+	for _, t := range s.RefundFallbackChain {
+		chain = append(chain, RefundTender(t))
+	}
+	return chain
+}
+
 // PlatformFeeLedger represents the ledger for platform fee collection and reconciliation
 type PlatformFeeLedger struct {
 	ID                   uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
@@ -597,11 +722,20 @@ type PlatformFeeLedger struct {
 	PaymentTransactionID *uuid.UUID      `gorm:"type:uuid;index:idx_platform_fee_ledger_payment" json:"paymentTransactionId,omitempty"`
 	RefundTransactionID  *uuid.UUID      `gorm:"type:uuid;index:idx_platform_fee_ledger_refund" json:"refundTransactionId,omitempty"`
 
+	// Marketplace vendor the fee was charged to, and the commission invoice that billed it
+	VendorID             string          `gorm:"type:varchar(255);index:idx_platform_fee_ledger_vendor" json:"vendorId,omitempty"`
+	CommissionInvoiceID  *uuid.UUID      `gorm:"type:uuid;index:idx_platform_fee_ledger_invoice" json:"commissionInvoiceId,omitempty"`
+
 	// Entry details
 	EntryType            LedgerEntryType `gorm:"type:varchar(20);not null" json:"entryType"`
 	Amount               float64         `gorm:"type:decimal(12,2);not null" json:"amount"`
 	Currency             string          `gorm:"type:varchar(3);default:'USD'" json:"currency"`
 
+	// Amount in the currency the gateway settles in, for cross-border payments
+	SettlementAmount     float64         `gorm:"type:decimal(12,2)" json:"settlementAmount,omitempty"`
+	SettlementCurrency   string          `gorm:"type:varchar(3)" json:"settlementCurrency,omitempty"`
+	ExchangeRate         float64         `gorm:"type:decimal(18,8)" json:"exchangeRate,omitempty"`
+
 	// Status tracking
 	Status               LedgerStatus    `gorm:"type:varchar(20);default:'pending';index:idx_platform_fee_ledger_status" json:"status"`
 
@@ -701,12 +835,14 @@ func (PaymentGatewayTemplate) TableName() string {
 
 // FeeCalculation represents the result of fee calculation
 type FeeCalculation struct {
-	GrossAmount     float64 `json:"grossAmount"`
-	PlatformFee     float64 `json:"platformFee"`
-	PlatformPercent float64 `json:"platformPercent"`
-	GatewayFee      float64 `json:"gatewayFee"`
-	TaxAmount       float64 `json:"taxAmount"`
-	NetAmount       float64 `json:"netAmount"`
+	GrossAmount        float64 `json:"grossAmount"`
+	PlatformFee        float64 `json:"platformFee"`
+	PlatformPercent    float64 `json:"platformPercent"`
+	GatewayFee         float64 `json:"gatewayFee"`
+	FXFee              float64 `json:"fxFee"` // Estimated conversion fee when the charge and settlement currencies differ
+	TaxAmount          float64 `json:"taxAmount"`
+	NetAmount          float64 `json:"netAmount"`
+	SettlementCurrency string  `json:"settlementCurrency,omitempty"`
 }
 
 // PaymentMethodOption represents an available payment method for checkout
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentLinkStatus represents the status of a payment link
+type PaymentLinkStatus string
+
+const (
+	PaymentLinkActive        PaymentLinkStatus = "ACTIVE"
+	PaymentLinkPartiallyPaid PaymentLinkStatus = "PARTIALLY_PAID"
+	PaymentLinkPaid          PaymentLinkStatus = "PAID"
+	PaymentLinkExpired       PaymentLinkStatus = "EXPIRED"
+	PaymentLinkCanceled      PaymentLinkStatus = "CANCELED"
+)
+
+// PaymentLink is a hosted, signed link that lets a customer pay an invoice or a manual
+// order outside of checkout. Links can optionally be settled in several partial payments.
+type PaymentLink struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID string    `gorm:"type:varchar(255);not null;index:idx_payment_links_tenant" json:"tenantId"`
+	Code     string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_payment_links_code" json:"code"`
+
+	// Optional order the link settles; links without an order collect an arbitrary amount
+	OrderID     *uuid.UUID `gorm:"type:uuid;index:idx_payment_links_order" json:"orderId,omitempty"`
+	OrderNumber string     `gorm:"type:varchar(100)" json:"orderNumber,omitempty"`
+	Description string     `gorm:"type:text" json:"description,omitempty"`
+
+	// Amounts
+	Amount               float64 `gorm:"type:decimal(12,2);not null" json:"amount"`
+	AmountPaid           float64 `gorm:"type:decimal(12,2);default:0" json:"amountPaid"`
+	Currency             string  `gorm:"type:varchar(3);not null" json:"currency"`
+	AllowPartialPayments bool    `gorm:"default:false" json:"allowPartialPayments"`
+	MinimumPaymentAmount float64 `gorm:"type:decimal(12,2);default:0" json:"minimumPaymentAmount,omitempty"`
+
+	// Customer info (prefilled on the hosted page)
+	CustomerEmail string `gorm:"type:varchar(255)" json:"customerEmail,omitempty"`
+	CustomerName  string `gorm:"type:varchar(255)" json:"customerName,omitempty"`
+	CustomerPhone string `gorm:"type:varchar(50)" json:"customerPhone,omitempty"`
+
+	// Status
+	Status    PaymentLinkStatus `gorm:"type:varchar(20);not null;default:'ACTIVE';index:idx_payment_links_status" json:"status"`
+	ExpiresAt time.Time         `gorm:"not null;index:idx_payment_links_expires" json:"expiresAt"`
+	PaidAt    *time.Time        `json:"paidAt,omitempty"`
+
+	Metadata  JSONB     `gorm:"type:jsonb" json:"metadata,omitempty"`
+	CreatedBy string    `gorm:"type:varchar(255)" json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName specifies the table name for PaymentLink
+func (PaymentLink) TableName() string {
+	return "payment_links"
+}
+
+// AmountDue returns the amount still outstanding on the link
+func (l *PaymentLink) AmountDue() float64 {
+	due := l.Amount - l.AmountPaid
+	if due < 0 {
+		return 0
+	}
+	return due
+}
+
+// IsPayable reports whether the link can still accept payments at the given time
+func (l *PaymentLink) IsPayable(now time.Time) bool {
+	if l.Status != PaymentLinkActive && l.Status != PaymentLinkPartiallyPaid {
+		return false
+	}
+	return now.Before(l.ExpiresAt)
+}
+
+// CreatePaymentLinkRequest represents a request to create a payment link. OrderID is optional;
+// without it the link collects an arbitrary amount (e.g. an invoice).
+type CreatePaymentLinkRequest struct {
+	OrderID              *uuid.UUID        `json:"orderId"`
+	OrderNumber          string            `json:"orderNumber"`
+	Amount               float64           `json:"amount" binding:"required,gt=0"`
+	Currency             string            `json:"currency" binding:"required,len=3"`
+	Description          string            `json:"description"`
+	AllowPartialPayments bool              `json:"allowPartialPayments"`
+	MinimumPaymentAmount float64           `json:"minimumPaymentAmount" binding:"omitempty,gte=0"`
+	CustomerEmail        string            `json:"customerEmail" binding:"omitempty,email"`
+	CustomerName         string            `json:"customerName"`
+	CustomerPhone        string            `json:"customerPhone"`
+	ExpiresInHours       int               `json:"expiresInHours" binding:"omitempty,gt=0"`
+	ExpiresAt            *time.Time        `json:"expiresAt"`
+	Metadata             map[string]string `json:"metadata"`
+}
+
+// PaymentLinkResponse is returned to admins and includes the signed hosted URL
+type PaymentLinkResponse struct {
+	PaymentLink
+	AmountDue float64 `json:"amountDue"`
+	URL       string  `json:"url"`
+	Signature string  `json:"signature"`
+}
+
+// PublicPaymentLinkResponse is what the storefront renders on the hosted payment page
+type PublicPaymentLinkResponse struct {
+	Code                 string            `json:"code"`
+	OrderNumber          string            `json:"orderNumber,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	Amount               float64           `json:"amount"`
+	AmountPaid           float64           `json:"amountPaid"`
+	AmountDue            float64           `json:"amountDue"`
+	Currency             string            `json:"currency"`
+	AllowPartialPayments bool              `json:"allowPartialPayments"`
+	MinimumPaymentAmount float64           `json:"minimumPaymentAmount,omitempty"`
+	CustomerEmail        string            `json:"customerEmail,omitempty"`
+	CustomerName         string            `json:"customerName,omitempty"`
+	Status               PaymentLinkStatus `json:"status"`
+	ExpiresAt            time.Time         `json:"expiresAt"`
+}
+
+// PayPaymentLinkRequest starts a payment against a link from the hosted page. Amount is
+// only honoured for links that allow partial payments; otherwise the full amount due is charged.
+type PayPaymentLinkRequest struct {
+	Signature     string            `json:"signature" binding:"required"`
+	GatewayType   GatewayType       `json:"gatewayType" binding:"required"`
+	PaymentMethod PaymentMethodType `json:"paymentMethod"`
+	Amount        float64           `json:"amount" binding:"omitempty,gt=0"`
+	CustomerEmail string            `json:"customerEmail" binding:"omitempty,email"`
+	CustomerName  string            `json:"customerName"`
+	CustomerPhone string            `json:"customerPhone"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TerminalReaderStatus represents the last known connectivity of a card reader
+type TerminalReaderStatus string
+
+const (
+	TerminalReaderOnline  TerminalReaderStatus = "ONLINE"
+	TerminalReaderOffline TerminalReaderStatus = "OFFLINE"
+	TerminalReaderUnknown TerminalReaderStatus = "UNKNOWN"
+)
+
+// TerminalReader is a card reader or POS device registered to one of a tenant's store
+// locations. In-person payments taken on it are stored as regular payment transactions
+// with the IN_PERSON channel.
+type TerminalReader struct {
+	ID          uuid.UUID   `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID    string      `gorm:"type:varchar(255);not null;index:idx_terminal_readers_tenant_location" json:"tenantId"`
+	LocationID  string      `gorm:"type:varchar(255);not null;index:idx_terminal_readers_tenant_location" json:"locationId"`
+	GatewayType GatewayType `gorm:"type:varchar(50);not null;uniqueIndex:idx_terminal_readers_gateway_reader" json:"gatewayType"`
+
+	// Gateway identifiers: Stripe reader/location IDs, or the Razorpay POS device ID
+	GatewayReaderID   string `gorm:"type:varchar(255);not null;uniqueIndex:idx_terminal_readers_gateway_reader" json:"gatewayReaderId"`
+	GatewayLocationID string `gorm:"type:varchar(255)" json:"gatewayLocationId,omitempty"`
+
+	Label        string               `gorm:"type:varchar(255)" json:"label"`
+	DeviceType   string               `gorm:"type:varchar(100)" json:"deviceType,omitempty"`
+	SerialNumber string               `gorm:"type:varchar(100)" json:"serialNumber,omitempty"`
+	Status       TerminalReaderStatus `gorm:"type:varchar(20);not null;default:'UNKNOWN'" json:"status"`
+	LastSeenAt   *time.Time           `json:"lastSeenAt,omitempty"`
+
+	CreatedBy string    `gorm:"type:varchar(255)" json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName specifies the table name for TerminalReader
+func (TerminalReader) TableName() string {
+	return "terminal_readers"
+}
+
+// CreateConnectionTokenRequest requests a Stripe Terminal connection token for the POS app.
+// LocationID restricts the token to readers registered at that store location.
+type CreateConnectionTokenRequest struct {
+	LocationID string `json:"locationId"`
+}
+
+// ConnectionTokenResponse carries the short-lived secret the Terminal SDK connects with
+type ConnectionTokenResponse struct {
+	Secret            string `json:"secret"`
+	GatewayLocationID string `json:"gatewayLocationId,omitempty"`
+}
+
+// RegisterTerminalReaderRequest registers a reader at a store location. Stripe readers are
+// paired with the registration code shown on the device; Razorpay POS devices are
+// registered by their device ID.
+type RegisterTerminalReaderRequest struct {
+	GatewayType       GatewayType `json:"gatewayType" binding:"required"`
+	LocationID        string      `json:"locationId" binding:"required"`
+	Label             string      `json:"label" binding:"required"`
+	RegistrationCode  string      `json:"registrationCode"`
+	GatewayLocationID string      `json:"gatewayLocationId"`
+	DeviceID          string      `json:"deviceId"`
+	SerialNumber      string      `json:"serialNumber"`
+}
+
+// CreateTerminalPaymentRequest starts an in-person payment on a registered reader
+type CreateTerminalPaymentRequest struct {
+	ReaderID      uuid.UUID         `json:"readerId" binding:"required"`
+	OrderID       uuid.UUID         `json:"orderId" binding:"required"`
+	Amount        float64           `json:"amount" binding:"required,gt=0"`
+	Currency      string            `json:"currency" binding:"required,len=3"`
+	CustomerID    *uuid.UUID        `json:"customerId"`
+	CustomerEmail string            `json:"customerEmail" binding:"omitempty,email"`
+	CustomerName  string            `json:"customerName"`
+	Description   string            `json:"description"`
+	Metadata      map[string]string `json:"metadata"`
+}
+
+// CaptureTerminalPaymentRequest captures an authorized in-person payment. Amount is
+// optional and captures less than the authorized amount (e.g. after a tip adjustment).
+type CaptureTerminalPaymentRequest struct {
+	Amount float64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// TerminalPaymentResponse is returned when an in-person payment is started on a reader
+type TerminalPaymentResponse struct {
+	PaymentID        uuid.UUID     `json:"paymentId"`
+	ReaderID         uuid.UUID     `json:"readerId"`
+	GatewayType      GatewayType   `json:"gatewayType"`
+	GatewayPaymentID string        `json:"gatewayPaymentId"`
+	ClientSecret     string        `json:"clientSecret,omitempty"`
+	Amount           float64       `json:"amount"`
+	Currency         string        `json:"currency"`
+	Status           PaymentStatus `json:"status"`
+}
+
+// ReconciliationLine aggregates payment transactions for one channel, gateway, location and status
+type ReconciliationLine struct {
+	Channel     PaymentChannel `json:"channel"`
+	GatewayType GatewayType    `json:"gatewayType"`
+	LocationID  string         `json:"locationId,omitempty"`
+	Status      PaymentStatus  `json:"status"`
+	Currency    string         `json:"currency"`
+	Count       int64          `json:"count"`
+	Amount      float64        `json:"amount"`
+	GatewayFee  float64        `json:"gatewayFee"`
+	NetAmount   float64        `json:"netAmount"`
+}
+
+// ReconciliationReport reconciles in-person and online payments over a period
+type ReconciliationReport struct {
+	From  time.Time            `json:"from"`
+	To    time.Time            `json:"to"`
+	Lines []ReconciliationLine `json:"lines"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VendorPayoutEntryType represents the type of vendor payout ledger entry
+type VendorPayoutEntryType string
+
+const (
+	// VendorPayoutHold withholds an amount from the vendor's payouts while a dispute or refund is open
+	VendorPayoutHold VendorPayoutEntryType = "hold"
+	// VendorPayoutRelease returns a held amount to the vendor
+	VendorPayoutRelease VendorPayoutEntryType = "release"
+	// VendorPayoutClawback deducts an amount from the vendor's payouts for good
+	VendorPayoutClawback VendorPayoutEntryType = "clawback"
+)
+
+// VendorPayoutLedger records holds, releases and clawbacks against a marketplace vendor's
+// payouts when a chargeback or return touches one of their orders. A release or clawback
+// resolves the hold it references; Explanation is shown to the vendor on their statement.
+type VendorPayoutLedger struct {
+	ID                   uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID             string    `gorm:"type:varchar(255);not null;index:idx_vendor_payout_ledger_vendor" json:"tenantId"`
+	VendorID             string    `gorm:"type:varchar(255);not null;index:idx_vendor_payout_ledger_vendor" json:"vendorId"`
+	PaymentTransactionID uuid.UUID `gorm:"type:uuid;not null;index:idx_vendor_payout_ledger_payment" json:"paymentTransactionId"`
+	OrderID              uuid.UUID `gorm:"type:uuid;not null" json:"orderId"`
+
+	// What caused the entry: a chargeback, or a refund (optionally for a return)
+	DisputeID           *uuid.UUID `gorm:"type:uuid;index:idx_vendor_payout_ledger_dispute" json:"disputeId,omitempty"`
+	RefundTransactionID *uuid.UUID `gorm:"type:uuid;index:idx_vendor_payout_ledger_refund" json:"refundTransactionId,omitempty"`
+	ReturnID            string     `gorm:"type:varchar(255)" json:"returnId,omitempty"`
+
+	// Entry details
+	EntryType   VendorPayoutEntryType `gorm:"type:varchar(20);not null" json:"entryType"`
+	Amount      float64               `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Currency    string                `gorm:"type:varchar(3);default:'USD'" json:"currency"`
+	HoldID      *uuid.UUID            `gorm:"type:uuid;index:idx_vendor_payout_ledger_hold" json:"holdId,omitempty"`
+	Explanation string                `gorm:"type:text" json:"explanation"`
+
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;index:idx_vendor_payout_ledger_created" json:"createdAt"`
+}
+
+// TableName specifies the table name for VendorPayoutLedger
+func (VendorPayoutLedger) TableName() string {
+	return "vendor_payout_ledger"
+}
+
+// VendorPayoutStatement is a vendor's payout adjustments over a period. Held is what is
+// still withheld by open holds, whenever they were placed.
+type VendorPayoutStatement struct {
+	VendorID   string               `json:"vendorId"`
+	StartDate  time.Time            `json:"startDate"`
+	EndDate    time.Time            `json:"endDate"`
+	Held       float64              `json:"held"`
+	Released   float64              `json:"released"`
+	ClawedBack float64              `json:"clawedBack"`
+	Entries    []VendorPayoutLedger `json:"entries"`
+}
@@ -3,14 +3,18 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/Tesseract-Nexus/go-shared/cache"
+	"payment-service/internal/metrics"
 	"payment-service/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Cache TTL constants for payments
@@ -103,26 +107,84 @@ func (r *PaymentRepository) GetGatewayConfig(ctx context.Context, configID uuid.
 	return &config, nil
 }
 
-// GetGatewayConfigByType gets a gateway configuration by tenant and type
+// GetGatewayConfigByType gets the tenant-level gateway configuration of a type
 func (r *PaymentRepository) GetGatewayConfigByType(ctx context.Context, tenantID string, gatewayType models.GatewayType) (*models.PaymentGatewayConfig, error) {
 	var config models.PaymentGatewayConfig
-	err := r.db.WithContext(ctx).Where("tenant_id = ? AND gateway_type = ? AND is_enabled = true", tenantID, gatewayType).First(&config).Error
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND storefront_id = '' AND gateway_type = ? AND is_enabled = true", tenantID, gatewayType).First(&config).Error
 	if err != nil {
 		return nil, err
 	}
 	return &config, nil
 }
 
-// ListGatewayConfigs lists all gateway configurations for a tenant
+// GetStorefrontGatewayConfigByType gets the gateway configuration of a type in effect on a
+// storefront: its override if it has an enabled one, otherwise the tenant default
+func (r *PaymentRepository) GetStorefrontGatewayConfigByType(ctx context.Context, tenantID, storefrontID string, gatewayType models.GatewayType) (*models.PaymentGatewayConfig, error) {
+	if storefrontID != "" {
+		var config models.PaymentGatewayConfig
+		err := r.db.WithContext(ctx).Where("tenant_id = ? AND storefront_id = ? AND gateway_type = ? AND is_enabled = true", tenantID, storefrontID, gatewayType).First(&config).Error
+		if err == nil {
+			return &config, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return r.GetGatewayConfigByType(ctx, tenantID, gatewayType)
+}
+
+// ListGatewayConfigs lists the tenant-level gateway configurations for a tenant
 func (r *PaymentRepository) ListGatewayConfigs(ctx context.Context, tenantID string) ([]models.PaymentGatewayConfig, error) {
 	var configs []models.PaymentGatewayConfig
-	err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("priority ASC").Find(&configs).Error
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND storefront_id = ''", tenantID).Order("priority ASC").Find(&configs).Error
 	if err != nil {
 		return nil, err
 	}
 	return configs, nil
 }
 
+// ListStorefrontGatewayConfigs lists a storefront's gateway config overrides, or every
+// storefront's when storefrontID is empty
+func (r *PaymentRepository) ListStorefrontGatewayConfigs(ctx context.Context, tenantID, storefrontID string) ([]models.PaymentGatewayConfig, error) {
+	var configs []models.PaymentGatewayConfig
+	query := r.db.WithContext(ctx).Where("tenant_id = ? AND storefront_id <> ''", tenantID)
+	if storefrontID != "" {
+		query = query.Where("storefront_id = ?", storefrontID)
+	}
+	err := query.Order("storefront_id ASC, priority ASC").Find(&configs).Error
+	if err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// ListEffectiveGatewayConfigs lists the gateway configurations in effect on a storefront: the
+// tenant defaults, with the storefront's overrides replacing those of the same gateway type
+func (r *PaymentRepository) ListEffectiveGatewayConfigs(ctx context.Context, tenantID, storefrontID string) ([]models.PaymentGatewayConfig, error) {
+	defaults, err := r.ListGatewayConfigs(ctx, tenantID)
+	if err != nil || storefrontID == "" {
+		return defaults, err
+	}
+	overrides, err := r.ListStorefrontGatewayConfigs(ctx, tenantID, storefrontID)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[models.GatewayType]int, len(overrides))
+	for i, override := range overrides {
+		byType[override.GatewayType] = i
+	}
+	configs := make([]models.PaymentGatewayConfig, 0, len(defaults)+len(overrides))
+	for _, config := range defaults {
+		if _, overridden := byType[config.GatewayType]; !overridden {
+			configs = append(configs, config)
+		}
+	}
+	configs = append(configs, overrides...)
+	sort.SliceStable(configs, func(i, j int) bool { return configs[i].Priority < configs[j].Priority })
+	return configs, nil
+}
+
 // CreateGatewayConfig creates a new gateway configuration
 func (r *PaymentRepository) CreateGatewayConfig(ctx context.Context, config *models.PaymentGatewayConfig) error {
 	err := r.db.WithContext(ctx).Create(config).Error
@@ -133,13 +195,31 @@ func (r *PaymentRepository) CreateGatewayConfig(ctx context.Context, config *mod
 }
 
 // UpdateGatewayConfig updates a gateway configuration
+// Only applies while the stored version equals config.Version, otherwise returns *models.VersionConflictError
 func (r *PaymentRepository) UpdateGatewayConfig(ctx context.Context, config *models.PaymentGatewayConfig) error {
+	expected := config.Version
 	config.UpdatedAt = time.Now()
-	err := r.db.WithContext(ctx).Save(config).Error
-	if err == nil {
-		r.invalidateGatewayConfigCaches(ctx, config.TenantID)
+	config.Version = expected + 1
+
+	result := r.db.WithContext(ctx).Model(config).
+		Where("version = ?", expected).
+		Select("*").Omit(clause.Associations).
+		Updates(config)
+	if result.Error != nil {
+		config.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		config.Version = expected
+		current, err := r.GetGatewayConfig(ctx, config.ID)
+		if err != nil {
+			return err
+		}
+		return &models.VersionConflictError{CurrentVersion: current.Version}
 	}
-	return err
+
+	r.invalidateGatewayConfigCaches(ctx, config.TenantID)
+	return nil
 }
 
 // DeleteGatewayConfig deletes a gateway configuration
@@ -193,10 +273,37 @@ func (r *PaymentRepository) GetPaymentTransactionByOrderID(ctx context.Context,
 	return &payment, nil
 }
 
-// UpdatePaymentTransaction updates a payment transaction
+// ListExpiredUPIPayments lists pending UPI payments whose intent/QR/collect request expired before now
+func (r *PaymentRepository) ListExpiredUPIPayments(ctx context.Context, now time.Time, limit int) ([]models.PaymentTransaction, error) {
+	var payments []models.PaymentTransaction
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", []models.PaymentStatus{models.PaymentPending, models.PaymentProcessing}).
+		Where("payment_method_type IN ?", []models.PaymentMethodType{models.MethodUPI, models.MethodUPIIntent, models.MethodUPIQR}).
+		Where("metadata->>'upi_expires_at' IS NOT NULL AND (metadata->>'upi_expires_at')::numeric < ?", now.Unix()).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&payments).Error
+	return payments, err
+}
+
+// UpdatePaymentTransaction updates a payment transaction. A payment moving to succeeded or
+// failed is counted once for its gateway's success rate, whichever flow (webhook, polling,
+// terminal) got it there.
 func (r *PaymentRepository) UpdatePaymentTransaction(ctx context.Context, tx *models.PaymentTransaction) error {
+	final := tx.Status == models.PaymentSucceeded || tx.Status == models.PaymentFailed
+	var previous models.PaymentStatus
+	if final {
+		r.db.WithContext(ctx).Model(&models.PaymentTransaction{}).Where("id = ?", tx.ID).Select("status").Scan(&previous)
+	}
+
 	tx.UpdatedAt = time.Now()
-	return r.db.WithContext(ctx).Save(tx).Error
+	if err := r.db.WithContext(ctx).Save(tx).Error; err != nil {
+		return err
+	}
+	if final && previous != tx.Status {
+		metrics.RecordPaymentOutcome(string(tx.GatewayType), tx.Status == models.PaymentSucceeded)
+	}
+	return nil
 }
 
 // ListPaymentTransactionsByOrder lists all payments for an order
@@ -227,7 +334,10 @@ func (r *PaymentRepository) CreateRefundTransaction(ctx context.Context, refund
 // GetRefundTransaction gets a refund transaction by ID
 func (r *PaymentRepository) GetRefundTransaction(ctx context.Context, refundID uuid.UUID) (*models.RefundTransaction, error) {
 	var refund models.RefundTransaction
-	err := r.db.WithContext(ctx).Preload("PaymentTransaction").First(&refund, "id = ?", refundID).Error
+	err := r.db.WithContext(ctx).
+		Preload("PaymentTransaction").
+		Preload("Attempts", func(db *gorm.DB) *gorm.DB { return db.Order("sequence ASC") }).
+		First(&refund, "id = ?", refundID).Error
 	if err != nil {
 		return nil, err
 	}
@@ -237,19 +347,50 @@ func (r *PaymentRepository) GetRefundTransaction(ctx context.Context, refundID u
 // UpdateRefundTransaction updates a refund transaction
 func (r *PaymentRepository) UpdateRefundTransaction(ctx context.Context, refund *models.RefundTransaction) error {
 	refund.UpdatedAt = time.Now()
-	return r.db.WithContext(ctx).Save(refund).Error
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(refund).Error
 }
 
 // ListRefundTransactionsByPayment lists all refunds for a payment
 func (r *PaymentRepository) ListRefundTransactionsByPayment(ctx context.Context, paymentID uuid.UUID) ([]models.RefundTransaction, error) {
 	var refunds []models.RefundTransaction
-	err := r.db.WithContext(ctx).Where("payment_transaction_id = ?", paymentID).Order("created_at DESC").Find(&refunds).Error
+	err := r.db.WithContext(ctx).
+		Preload("Attempts", func(db *gorm.DB) *gorm.DB { return db.Order("sequence ASC") }).
+		Where("payment_transaction_id = ?", paymentID).Order("created_at DESC").Find(&refunds).Error
 	if err != nil {
 		return nil, err
 	}
 	return refunds, nil
 }
 
+// CreateRefundTenderAttempt records a step of a refund's fallback chain
+func (r *PaymentRepository) CreateRefundTenderAttempt(ctx context.Context, attempt *models.RefundTenderAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+// UpdateRefundTenderAttempt updates a refund tender attempt
+func (r *PaymentRepository) UpdateRefundTenderAttempt(ctx context.Context, attempt *models.RefundTenderAttempt) error {
+	attempt.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(attempt).Error
+}
+
+// GetDisputeByGatewayID retrieves a dispute by its gateway dispute ID
+func (r *PaymentRepository) GetDisputeByGatewayID(ctx context.Context, tenantID, gatewayDisputeID string) (*models.PaymentDispute, error) {
+	var dispute models.PaymentDispute
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND gateway_dispute_id = ?", tenantID, gatewayDisputeID).
+		First(&dispute).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+// SaveDispute creates or updates a dispute
+func (r *PaymentRepository) SaveDispute(ctx context.Context, dispute *models.PaymentDispute) error {
+	dispute.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(dispute).Error
+}
+
 // CreateWebhookEvent creates a new webhook event
 func (r *PaymentRepository) CreateWebhookEvent(ctx context.Context, event *models.WebhookEvent) error {
 	return r.db.WithContext(ctx).Create(event).Error
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"payment-service/internal/events"
 	"payment-service/internal/models"
 	"payment-service/internal/repository"
 	"gorm.io/gorm"
@@ -18,6 +19,7 @@ type AdBillingService struct {
 	db             *gorm.DB
 	repo           *repository.PaymentRepository
 	paymentService *PaymentService
+	publisher      *events.Publisher
 }
 
 // NewAdBillingService creates a new ad billing service
@@ -29,6 +31,12 @@ func NewAdBillingService(db *gorm.DB, repo *repository.PaymentRepository, paymen
 	}
 }
 
+// SetEventsPublisher publishes campaign budget exhaustion and top-ups for the ads system.
+// Without one, budgets are still tracked but campaigns aren't paused.
+func (s *AdBillingService) SetEventsPublisher(publisher *events.Publisher) {
+	s.publisher = publisher
+}
+
 // ErrTierNotFound is returned when no matching commission tier is found
 var ErrTierNotFound = errors.New("no matching commission tier found")
 
@@ -337,7 +345,8 @@ func (s *AdBillingService) ProcessPayment(ctx context.Context, tenantID string,
 // ConfirmPayment confirms a successful ad payment and updates ledger
 // Called by webhook service after successful payment confirmation from gateway
 func (s *AdBillingService) ConfirmPayment(ctx context.Context, paymentID uuid.UUID, transactionID uuid.UUID) error {
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	var replenished *models.AdCampaignBudget
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Get the payment
 		var adPayment models.AdCampaignPayment
 		if err := tx.First(&adPayment, "id = ?", paymentID).Error; err != nil {
@@ -421,8 +430,25 @@ func (s *AdBillingService) ConfirmPayment(ctx context.Context, paymentID uuid.UU
 			return fmt.Errorf("failed to create ledger entry: %w", err)
 		}
 
+		// The paid budget (first payment or top-up) becomes spendable by the campaign
+		budget, wasExhausted, err := s.creditCampaignBudget(tx, &adPayment, now)
+		if err != nil {
+			return err
+		}
+		if wasExhausted && budget.Status == models.AdBudgetActive {
+			replenished = budget
+		}
+
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if replenished != nil {
+		s.publishBudgetStatus(events.AdBudgetReplenished, replenished, paymentID.String())
+	}
+	return nil
 }
 
 // FailPayment marks a payment as failed
@@ -455,7 +481,8 @@ func (s *AdBillingService) RefundPayment(ctx context.Context, tenantID string, p
 		return ErrInvalidTenantID
 	}
 
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	var exhausted *models.AdCampaignBudget
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Get the payment with tenant isolation
 		var adPayment models.AdCampaignPayment
 		if err := tx.Where("tenant_id = ? AND id = ?", tenantID, paymentID).First(&adPayment).Error; err != nil {
@@ -519,8 +546,23 @@ func (s *AdBillingService) RefundPayment(ctx context.Context, tenantID string, p
 			return fmt.Errorf("failed to create ledger entry: %w", err)
 		}
 
+		// The refunded budget can no longer be spent
+		budget, err := s.debitCampaignBudget(tx, &adPayment, now)
+		if err != nil {
+			return err
+		}
+		exhausted = budget
+
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if exhausted != nil {
+		s.publishBudgetStatus(events.AdBudgetExhausted, exhausted, paymentID.String())
+	}
+	return nil
 }
 
 // GetVendorBillingHistory retrieves billing history for a vendor with pagination
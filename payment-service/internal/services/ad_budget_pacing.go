@@ -0,0 +1,404 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"payment-service/internal/events"
+	"payment-service/internal/models"
+)
+
+// adPaceTolerance is how far spend may drift from an even spread before a campaign counts as
+// ahead of or behind pace
+const adPaceTolerance = 0.1
+
+// ErrCampaignBudgetNotFound is returned when a campaign has no confirmed payment yet
+var ErrCampaignBudgetNotFound = errors.New("campaign has no paid budget")
+
+// ErrCampaignEnded is returned when topping up a campaign whose schedule is over
+var ErrCampaignEnded = errors.New("campaign has ended")
+
+// creditCampaignBudget adds a confirmed payment's budget to its campaign, creating the budget on
+// the campaign's first payment. Reports whether the budget had been exhausted before.
+func (s *AdBillingService) creditCampaignBudget(tx *gorm.DB, payment *models.AdCampaignPayment, now time.Time) (*models.AdCampaignBudget, bool, error) {
+	var budget models.AdCampaignBudget
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND campaign_id = ?", payment.TenantID, payment.CampaignID).
+		First(&budget).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		days := payment.CampaignDays
+		if days < 1 {
+			days = 1
+		}
+		budget = models.AdCampaignBudget{
+			ID:           uuid.New(),
+			TenantID:     payment.TenantID,
+			VendorID:     payment.VendorID,
+			CampaignID:   payment.CampaignID,
+			PaymentType:  payment.PaymentType,
+			Status:       models.AdBudgetActive,
+			BudgetAmount: payment.BudgetAmount,
+			Currency:     payment.Currency,
+			StartsAt:     now,
+			EndsAt:       now.AddDate(0, 0, days),
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := tx.Create(&budget).Error; err != nil {
+			return nil, false, fmt.Errorf("failed to create campaign budget: %w", err)
+		}
+		return &budget, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get campaign budget: %w", err)
+	}
+
+	wasExhausted := budget.Status == models.AdBudgetExhausted
+	budget.BudgetAmount = roundAmount(budget.BudgetAmount + payment.BudgetAmount)
+	if err := s.saveBudget(tx, &budget, now); err != nil {
+		return nil, false, err
+	}
+	return &budget, wasExhausted, nil
+}
+
+// debitCampaignBudget takes a refunded payment's budget off its campaign. Returns the budget if
+// the refund exhausted it.
+func (s *AdBillingService) debitCampaignBudget(tx *gorm.DB, payment *models.AdCampaignPayment, now time.Time) (*models.AdCampaignBudget, error) {
+	var budget models.AdCampaignBudget
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? AND campaign_id = ?", payment.TenantID, payment.CampaignID).
+		First(&budget).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Paid before budgets were tracked
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign budget: %w", err)
+	}
+
+	wasActive := budget.Status == models.AdBudgetActive
+	budget.BudgetAmount = math.Max(0, roundAmount(budget.BudgetAmount-payment.BudgetAmount))
+	if err := s.saveBudget(tx, &budget, now); err != nil {
+		return nil, err
+	}
+	if wasActive && budget.Status == models.AdBudgetExhausted {
+		return &budget, nil
+	}
+	return nil, nil
+}
+
+// saveBudget recomputes the budget's status from its amounts and stores it
+func (s *AdBillingService) saveBudget(tx *gorm.DB, budget *models.AdCampaignBudget, now time.Time) error {
+	if budget.SpentAmount >= budget.BudgetAmount {
+		if budget.Status != models.AdBudgetExhausted {
+			budget.Status = models.AdBudgetExhausted
+			budget.ExhaustedAt = &now
+		}
+	} else {
+		budget.Status = models.AdBudgetActive
+		budget.ExhaustedAt = nil
+	}
+	budget.UpdatedAt = now
+
+	if err := tx.Model(&models.AdCampaignBudget{}).
+		Where("id = ?", budget.ID).
+		Updates(map[string]any{
+			"budget_amount": budget.BudgetAmount,
+			"spent_amount":  budget.SpentAmount,
+			"status":        budget.Status,
+			"exhausted_at":  budget.ExhaustedAt,
+			"last_spend_at": budget.LastSpendAt,
+			"updated_at":    now,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update campaign budget: %w", err)
+	}
+	return nil
+}
+
+// RecordSpend records spend reported by the ads system against a campaign's budget. The spend
+// that uses up the budget exhausts it and the campaign is paused through a budget_exhausted
+// event. Spend reported after that (impressions already in flight) is still recorded.
+func (s *AdBillingService) RecordSpend(ctx context.Context, tenantID string, campaignID uuid.UUID, req *models.RecordAdSpendRequest) (*models.AdBudgetPacing, error) {
+	if tenantID == "" {
+		return nil, ErrInvalidTenantID
+	}
+	if req.Amount <= 0 {
+		return nil, errors.New("spend amount must be greater than 0")
+	}
+
+	var budget models.AdCampaignBudget
+	duplicate := false
+	exhausted := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The budget row lock serializes spend reports for the campaign
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND campaign_id = ?", tenantID, campaignID).
+			First(&budget).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCampaignBudgetNotFound
+			}
+			return fmt.Errorf("failed to get campaign budget: %w", err)
+		}
+
+		var spendRef *string
+		if req.SpendID != "" {
+			spendRef = &req.SpendID
+			var count int64
+			if err := tx.Model(&models.AdRevenueLedger{}).
+				Where("tenant_id = ? AND campaign_id = ? AND spend_ref = ?", tenantID, campaignID, req.SpendID).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to check spend reference: %w", err)
+			}
+			if count > 0 {
+				duplicate = true
+				return nil
+			}
+		}
+
+		now := time.Now()
+		amount := roundAmount(req.Amount)
+		wasActive := budget.Status == models.AdBudgetActive
+		budget.SpentAmount = roundAmount(budget.SpentAmount + amount)
+		budget.LastSpendAt = &now
+		if err := s.saveBudget(tx, &budget, now); err != nil {
+			return err
+		}
+		exhausted = wasActive && budget.Status == models.AdBudgetExhausted
+
+		// Direct campaigns are prepaid, so their spend draws down the vendor's ad balance.
+		// Sponsored campaigns only prepaid the commission, which isn't spent.
+		if budget.PaymentType == models.AdPaymentTypeDirect {
+			if err := tx.Model(&models.AdVendorBalance{}).
+				Where("tenant_id = ? AND vendor_id = ?", tenantID, budget.VendorID).
+				Updates(map[string]any{
+					"current_balance": gorm.Expr("current_balance - ?", amount),
+					"total_spent":     gorm.Expr("total_spent + ?", amount),
+					"updated_at":      now,
+				}).Error; err != nil {
+				return fmt.Errorf("failed to update vendor balance: %w", err)
+			}
+		}
+
+		var balance models.AdVendorBalance
+		if err := tx.Where("tenant_id = ? AND vendor_id = ?", tenantID, budget.VendorID).First(&balance).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get vendor balance: %w", err)
+		}
+
+		description := req.Description
+		if description == "" {
+			description = "Campaign spend"
+		}
+		ledgerEntry := &models.AdRevenueLedger{
+			ID:           uuid.New(),
+			TenantID:     tenantID,
+			VendorID:     budget.VendorID,
+			CampaignID:   campaignID,
+			EntryType:    models.AdLedgerSpend,
+			Amount:       -amount, // Negative for spend
+			Currency:     budget.Currency,
+			BalanceAfter: balance.CurrentBalance,
+			Description:  description,
+			SpendRef:     spendRef,
+			CreatedAt:    now,
+		}
+		if err := tx.Create(ledgerEntry).Error; err != nil {
+			return fmt.Errorf("failed to create ledger entry: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if exhausted {
+		s.publishBudgetStatus(events.AdBudgetExhausted, &budget, "")
+	}
+
+	pacing := budgetPacing(&budget, time.Now())
+	pacing.DuplicateSpend = duplicate
+	return pacing, nil
+}
+
+// GetCampaignBudget returns a campaign's budget with its remaining amount and pacing
+func (s *AdBillingService) GetCampaignBudget(ctx context.Context, tenantID string, campaignID uuid.UUID) (*models.AdBudgetPacing, error) {
+	if tenantID == "" {
+		return nil, ErrInvalidTenantID
+	}
+
+	var budget models.AdCampaignBudget
+	err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND campaign_id = ?", tenantID, campaignID).
+		First(&budget).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCampaignBudgetNotFound
+		}
+		return nil, fmt.Errorf("failed to get campaign budget: %w", err)
+	}
+
+	return budgetPacing(&budget, time.Now()), nil
+}
+
+// ListVendorBudgets returns the budgets of a vendor's campaigns, most recent first
+func (s *AdBillingService) ListVendorBudgets(ctx context.Context, tenantID string, vendorID uuid.UUID) ([]models.AdBudgetPacing, error) {
+	if tenantID == "" {
+		return nil, ErrInvalidTenantID
+	}
+
+	if vendorID == uuid.Nil {
+		return nil, ErrInvalidVendorID
+	}
+
+	var budgets []models.AdCampaignBudget
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND vendor_id = ?", tenantID, vendorID).
+		Order("starts_at DESC").
+		Find(&budgets).Error; err != nil {
+		return nil, fmt.Errorf("failed to get campaign budgets: %w", err)
+	}
+
+	now := time.Now()
+	result := make([]models.AdBudgetPacing, 0, len(budgets))
+	for i := range budgets {
+		result = append(result, *budgetPacing(&budgets[i], now))
+	}
+	return result, nil
+}
+
+// TopUpBudget creates a pending payment adding budget to a running campaign. It is paid like
+// any other ad payment, and its budget is added to the campaign once confirmed. Sponsored
+// top-ups carry the original payment's commission rate, prorated to the share of the campaign
+// still to run.
+func (s *AdBillingService) TopUpBudget(ctx context.Context, tenantID string, campaignID uuid.UUID, amount float64) (*models.AdCampaignPayment, error) {
+	if tenantID == "" {
+		return nil, ErrInvalidTenantID
+	}
+	if amount <= 0 {
+		return nil, errors.New("top-up amount must be greater than 0")
+	}
+
+	var budget models.AdCampaignBudget
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND campaign_id = ?", tenantID, campaignID).
+		First(&budget).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCampaignBudgetNotFound
+		}
+		return nil, fmt.Errorf("failed to get campaign budget: %w", err)
+	}
+
+	now := time.Now()
+	if !now.Before(budget.EndsAt) {
+		return nil, ErrCampaignEnded
+	}
+
+	var original models.AdCampaignPayment
+	if err := s.db.WithContext(ctx).
+		Preload("CommissionTier").
+		Where("tenant_id = ? AND campaign_id = ? AND parent_payment_id IS NULL AND status = ?", tenantID, campaignID, models.AdPaymentPaid).
+		Order("paid_at ASC").
+		First(&original).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCampaignBudgetNotFound
+		}
+		return nil, fmt.Errorf("failed to get campaign payment: %w", err)
+	}
+
+	amount = roundAmount(amount)
+	daysLeft := int(math.Ceil(budget.EndsAt.Sub(now).Hours() / 24))
+	payment := &models.AdCampaignPayment{
+		ID:              uuid.New(),
+		TenantID:        tenantID,
+		VendorID:        budget.VendorID,
+		CampaignID:      campaignID,
+		PaymentType:     original.PaymentType,
+		Status:          models.AdPaymentPending,
+		BudgetAmount:    amount,
+		TotalAmount:     amount, // Direct top-up = full amount
+		Currency:        budget.Currency,
+		CampaignDays:    daysLeft,
+		ParentPaymentID: &original.ID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if original.PaymentType == models.AdPaymentTypeSponsored {
+		proration := 1.0
+		if original.CampaignDays > 0 {
+			proration = math.Min(1, float64(daysLeft)/float64(original.CampaignDays))
+		}
+
+		commissionAmount := roundAmount(amount * original.CommissionRate * proration)
+		taxAmount := 0.0
+		if original.CommissionTier != nil && !original.CommissionTier.TaxInclusive {
+			// Same tax on top of commission as CalculateCommission
+			taxAmount = roundAmount(commissionAmount * 0.18)
+		}
+
+		payment.CommissionRate = original.CommissionRate
+		payment.CommissionAmount = commissionAmount
+		payment.TaxAmount = taxAmount
+		payment.TotalAmount = commissionAmount + taxAmount
+		payment.CommissionTierID = original.CommissionTierID
+		payment.Metadata = models.JSONB{"proration": proration}
+	}
+
+	if err := s.db.WithContext(ctx).Create(payment).Error; err != nil {
+		return nil, fmt.Errorf("failed to create top-up payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+// budgetPacing works out what is left of a budget and how its spend compares with an even
+// spread over the campaign's schedule
+func budgetPacing(budget *models.AdCampaignBudget, now time.Time) *models.AdBudgetPacing {
+	pacing := &models.AdBudgetPacing{
+		AdCampaignBudget: *budget,
+		RemainingAmount:  math.Max(0, roundAmount(budget.BudgetAmount-budget.SpentAmount)),
+		OverspentAmount:  math.Max(0, roundAmount(budget.SpentAmount-budget.BudgetAmount)),
+		PaceStatus:       models.AdPaceOnTrack,
+	}
+
+	elapsed := 1.0
+	if schedule := budget.EndsAt.Sub(budget.StartsAt); schedule > 0 {
+		elapsed = math.Min(1, math.Max(0, float64(now.Sub(budget.StartsAt))/float64(schedule)))
+	}
+	pacing.ExpectedSpend = roundAmount(budget.BudgetAmount * elapsed)
+
+	if now.Before(budget.EndsAt) {
+		pacing.DaysRemaining = int(math.Ceil(budget.EndsAt.Sub(now).Hours() / 24))
+		pacing.DailyBudget = roundAmount(pacing.RemainingAmount / float64(pacing.DaysRemaining))
+	}
+
+	switch {
+	case budget.SpentAmount > pacing.ExpectedSpend*(1+adPaceTolerance):
+		pacing.PaceStatus = models.AdPaceAhead
+	case budget.SpentAmount < pacing.ExpectedSpend*(1-adPaceTolerance):
+		pacing.PaceStatus = models.AdPaceBehind
+	}
+	return pacing
+}
+
+// publishBudgetStatus publishes a budget status change for the ads system (non-blocking)
+func (s *AdBillingService) publishBudgetStatus(eventType string, budget *models.AdCampaignBudget, paymentID string) {
+	if s.publisher == nil {
+		return
+	}
+
+	snapshot := *budget
+	remaining := math.Max(0, roundAmount(snapshot.BudgetAmount-snapshot.SpentAmount))
+	go func() {
+		if err := s.publisher.PublishAdBudgetStatus(context.Background(), eventType, snapshot.TenantID, snapshot.CampaignID.String(), snapshot.VendorID.String(),
+			paymentID, snapshot.BudgetAmount, snapshot.SpentAmount, remaining, snapshot.Currency, string(snapshot.Status)); err != nil {
+			fmt.Printf("[AdBillingService] Failed to publish %s for campaign %s: %v\n", eventType, snapshot.CampaignID, err)
+		}
+	}()
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/johnfercher/maroto/v2"
+	"github.com/johnfercher/maroto/v2/pkg/components/col"
+	"github.com/johnfercher/maroto/v2/pkg/components/text"
+	"github.com/johnfercher/maroto/v2/pkg/config"
+	"github.com/johnfercher/maroto/v2/pkg/consts/align"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"github.com/johnfercher/maroto/v2/pkg/props"
+
+	"payment-service/internal/models"
+)
+
+var (
+	invoiceDarkText  = &props.Color{Red: 33, Green: 37, Blue: 41}    // #212529
+	invoiceLightText = &props.Color{Red: 108, Green: 117, Blue: 125} // #6C757D
+	invoiceHeaderBg  = &props.Color{Red: 233, Green: 236, Blue: 239} // #E9ECEF
+)
+
+// RenderInvoicePDF renders a commission invoice as a PDF
+func (s *CommissionInvoiceService) RenderInvoicePDF(invoice *models.CommissionInvoice) ([]byte, error) {
+	cfg := config.NewBuilder().
+		WithPageNumber().
+		WithLeftMargin(15).
+		WithTopMargin(15).
+		WithRightMargin(15).
+		Build()
+
+	m := maroto.New(cfg)
+
+	title := "Tax Invoice"
+	if invoice.TaxScheme == models.CommissionTaxNone {
+		title = "Invoice"
+	}
+	m.AddRow(12,
+		col.New(6).Add(text.New(title, props.Text{Size: 18, Style: fontstyle.Bold, Color: invoiceDarkText})),
+		col.New(6).Add(text.New(invoice.InvoiceNumber, props.Text{Size: 12, Style: fontstyle.Bold, Color: invoiceDarkText, Align: align.Right, Top: 4})),
+	)
+	m.AddRow(6,
+		col.New(6).Add(text.New("Marketplace commission for "+invoice.PeriodStart.Format("January 2006"), props.Text{Size: 9, Color: invoiceLightText})),
+		col.New(6).Add(text.New("Issued "+invoice.IssuedAt.Format("02 Jan 2006"), props.Text{Size: 9, Color: invoiceLightText, Align: align.Right})),
+	)
+	m.AddRow(6)
+
+	// Issuer and vendor side by side
+	m.AddRow(6,
+		col.New(6).Add(text.New("From", props.Text{Size: 8, Style: fontstyle.Bold, Color: invoiceLightText})),
+		col.New(6).Add(text.New("Bill to", props.Text{Size: 8, Style: fontstyle.Bold, Color: invoiceLightText})),
+	)
+	m.AddRow(6,
+		col.New(6).Add(text.New(invoice.IssuerName, props.Text{Size: 10, Style: fontstyle.Bold, Color: invoiceDarkText})),
+		col.New(6).Add(text.New(invoice.VendorName, props.Text{Size: 10, Style: fontstyle.Bold, Color: invoiceDarkText})),
+	)
+	issuer := partyLines(invoice.IssuerAddress, invoice.IssuerTaxID)
+	vendor := partyLines(invoice.VendorAddress, invoice.VendorTaxID)
+	for i := 0; i < len(issuer) || i < len(vendor); i++ {
+		m.AddRow(5,
+			col.New(6).Add(text.New(lineAt(issuer, i), props.Text{Size: 9, Color: invoiceDarkText})),
+			col.New(6).Add(text.New(lineAt(vendor, i), props.Text{Size: 9, Color: invoiceDarkText})),
+		)
+	}
+	m.AddRow(8)
+
+	addInvoiceLines(m, invoice)
+	m.AddRow(4)
+
+	// Totals
+	addInvoiceTotal(m, "Commission", invoice.Currency, invoice.CommissionAmount, false)
+	if invoice.RefundedAmount > 0 {
+		addInvoiceTotal(m, "Less: commission refunded", invoice.Currency, -invoice.RefundedAmount, false)
+	}
+	addInvoiceTotal(m, "Taxable value", invoice.Currency, invoice.TaxableAmount, true)
+	for _, component := range []struct {
+		name   string
+		rate   float64
+		amount float64
+	}{
+		{"CGST", invoice.TaxRate / 2, invoice.CGSTAmount},
+		{"SGST", invoice.TaxRate / 2, invoice.SGSTAmount},
+		{"IGST", invoice.TaxRate, invoice.IGSTAmount},
+		{"VAT", invoice.TaxRate, invoice.VATAmount},
+	} {
+		if component.amount != 0 {
+			addInvoiceTotal(m, fmt.Sprintf("%s @ %g%%", component.name, component.rate), invoice.Currency, component.amount, false)
+		}
+	}
+	addInvoiceTotal(m, "Total due", invoice.Currency, invoice.TotalAmount, true)
+
+	if invoice.TaxNote != "" {
+		m.AddRow(6)
+		m.AddRow(6, col.New(12).Add(text.New(invoice.TaxNote, props.Text{Size: 9, Style: fontstyle.Italic, Color: invoiceDarkText})))
+	}
+
+	doc, err := m.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate commission invoice PDF: %w", err)
+	}
+	return doc.GetBytes(), nil
+}
+
+// addInvoiceLines lists the fees and fee refunds billed, one row per ledger entry
+func addInvoiceLines(m core.Maroto, invoice *models.CommissionInvoice) {
+	header := props.Text{Size: 8, Style: fontstyle.Bold, Color: invoiceDarkText, Top: 2}
+	m.AddRow(8,
+		col.New(3).Add(text.New("Date", header)),
+		col.New(5).Add(text.New("Order", header)),
+		col.New(2).Add(text.New("Type", header)),
+		col.New(2).Add(text.New("Amount", props.Text{Size: 8, Style: fontstyle.Bold, Color: invoiceDarkText, Top: 2, Align: align.Right})),
+	).WithStyle(&props.Cell{BackgroundColor: invoiceHeaderBg})
+
+	cell := props.Text{Size: 8, Color: invoiceDarkText, Top: 1}
+	for _, line := range invoice.LineItems {
+		order := ""
+		if line.OrderID != nil {
+			order = line.OrderID.String()
+		}
+		kind := "Commission"
+		if line.EntryType == models.LedgerEntryRefund {
+			kind = "Refund"
+		}
+		m.AddRow(6,
+			col.New(3).Add(text.New(line.Date.Format("02 Jan 2006"), cell)),
+			col.New(5).Add(text.New(order, cell)),
+			col.New(2).Add(text.New(kind, cell)),
+			col.New(2).Add(text.New(fmt.Sprintf("%.2f", line.Amount), props.Text{Size: 8, Color: invoiceDarkText, Top: 1, Align: align.Right})),
+		)
+	}
+}
+
+func addInvoiceTotal(m core.Maroto, label, currency string, amount float64, bold bool) {
+	style := fontstyle.Normal
+	if bold {
+		style = fontstyle.Bold
+	}
+	m.AddRow(6,
+		col.New(8),
+		col.New(2).Add(text.New(label, props.Text{Size: 9, Style: style, Color: invoiceDarkText})),
+		col.New(2).Add(text.New(fmt.Sprintf("%s %.2f", currency, amount), props.Text{Size: 9, Style: style, Color: invoiceDarkText, Align: align.Right})),
+	)
+}
+
+// partyLines is an address followed by its tax ID, one entry per printed line
+func partyLines(address, taxID string) []string {
+	var lines []string
+	if address != "" {
+		lines = strings.Split(address, "\n")
+	}
+	if taxID != "" {
+		lines = append(lines, "Tax ID: "+taxID)
+	}
+	return lines
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
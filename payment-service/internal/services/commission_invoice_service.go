@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"payment-service/internal/clients"
+	"payment-service/internal/models"
+)
+
+var (
+	// ErrCommissionInvoicingDisabled is returned when the tenant hasn't enabled commission invoices
+	ErrCommissionInvoicingDisabled = errors.New("commission invoicing is not enabled")
+	// ErrCommissionInvoiceNotFound is returned when no commission invoice matches
+	ErrCommissionInvoiceNotFound = errors.New("commission invoice not found")
+	// ErrCommissionPeriodOpen is returned when invoicing a month that hasn't ended yet
+	ErrCommissionPeriodOpen = errors.New("commission can only be invoiced for a month that has ended")
+)
+
+// CommissionInvoiceService bills marketplace vendors monthly for the platform fees charged on
+// their orders, from the platform fee ledger, with GST or VAT on the commission
+type CommissionInvoiceService struct {
+	db           *gorm.DB
+	vendorClient *clients.VendorClient
+}
+
+// NewCommissionInvoiceService creates a new commission invoice service
+func NewCommissionInvoiceService(db *gorm.DB, vendorClient *clients.VendorClient) *CommissionInvoiceService {
+	return &CommissionInvoiceService{
+		db:           db,
+		vendorClient: vendorClient,
+	}
+}
+
+// GetSettings returns the tenant's commission invoice settings, or disabled defaults when the
+// tenant has none yet
+func (s *CommissionInvoiceService) GetSettings(ctx context.Context, tenantID string) (*models.CommissionInvoiceSettings, error) {
+	var settings models.CommissionInvoiceSettings
+	err := s.db.WithContext(ctx).Where("tenant_id = ?", tenantID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.CommissionInvoiceSettings{
+			TenantID:      tenantID,
+			TaxScheme:     models.CommissionTaxNone,
+			InvoicePrefix: "COM",
+			NextSequence:  1,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdateSettings saves the tenant's commission invoice settings. The invoice sequence can't be
+// changed so numbering stays gapless.
+func (s *CommissionInvoiceService) UpdateSettings(ctx context.Context, tenantID string, req *models.UpdateCommissionInvoiceSettingsRequest) (*models.CommissionInvoiceSettings, error) {
+	settings, err := s.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.Enabled = req.Enabled
+	settings.LegalName = req.LegalName
+	settings.Address = req.Address
+	settings.TaxID = req.TaxID
+	settings.Country = strings.ToUpper(req.Country)
+	settings.State = req.State
+	settings.TaxScheme = req.TaxScheme
+	settings.TaxRate = req.TaxRate
+	if req.InvoicePrefix != "" {
+		settings.InvoicePrefix = req.InvoicePrefix
+	}
+	settings.UpdatedAt = time.Now()
+
+	if settings.ID == uuid.Nil {
+		settings.ID = uuid.New()
+		settings.CreatedAt = settings.UpdatedAt
+		err = s.db.WithContext(ctx).Create(settings).Error
+	} else {
+		err = s.db.WithContext(ctx).Omit("next_sequence").Save(settings).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// ListEnabledTenants returns the tenants that invoice their vendors for commission
+func (s *CommissionInvoiceService) ListEnabledTenants(ctx context.Context) ([]string, error) {
+	var tenantIDs []string
+	err := s.db.WithContext(ctx).
+		Model(&models.CommissionInvoiceSettings{}).
+		Where("enabled = ?", true).
+		Pluck("tenant_id", &tenantIDs).Error
+	return tenantIDs, err
+}
+
+// commissionGroup is the uninvoiced fees of one vendor in one currency
+type commissionGroup struct {
+	VendorID string
+	Currency string
+}
+
+// GenerateMonthlyInvoices issues an invoice to every vendor with uninvoiced platform fees charged
+// in month, one per currency. Vendors whose details can't be fetched from vendor-service are
+// skipped and picked up by the next run. It returns the invoices issued.
+func (s *CommissionInvoiceService) GenerateMonthlyInvoices(ctx context.Context, tenantID string, month time.Time) ([]models.CommissionInvoice, error) {
+	settings, err := s.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, ErrCommissionInvoicingDisabled
+	}
+
+	periodStart := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+	if periodEnd.After(time.Now()) {
+		return nil, ErrCommissionPeriodOpen
+	}
+
+	var groups []commissionGroup
+	if err := s.uninvoicedEntries(s.db.WithContext(ctx), tenantID, periodStart, periodEnd).
+		Select("vendor_id, currency").
+		Group("vendor_id, currency").
+		Scan(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to find uninvoiced fees: %w", err)
+	}
+
+	var issued []models.CommissionInvoice
+	for _, group := range groups {
+		vendor, err := s.vendorClient.GetVendor(ctx, tenantID, group.VendorID)
+		if err != nil {
+			log.Printf("Skipping commission invoice for vendor %s (tenant %s): %v", group.VendorID, tenantID, err)
+			continue
+		}
+
+		invoice, err := s.issueInvoice(ctx, tenantID, group, vendor, periodStart, periodEnd)
+		if err != nil {
+			return issued, fmt.Errorf("failed to invoice vendor %s: %w", group.VendorID, err)
+		}
+		if invoice != nil {
+			issued = append(issued, *invoice)
+		}
+	}
+	return issued, nil
+}
+
+// uninvoicedEntries scopes the ledger to vendor fees and fee refunds in [start, end) that
+// haven't been billed yet
+func (s *CommissionInvoiceService) uninvoicedEntries(db *gorm.DB, tenantID string, start, end time.Time) *gorm.DB {
+	return db.Model(&models.PlatformFeeLedger{}).
+		Where("tenant_id = ? AND vendor_id <> '' AND commission_invoice_id IS NULL", tenantID).
+		Where("entry_type IN ? AND status <> ?", []models.LedgerEntryType{models.LedgerEntryCollection, models.LedgerEntryRefund}, models.LedgerStatusFailed).
+		Where("created_at >= ? AND created_at < ?", start, end)
+}
+
+// issueInvoice bills the group's fees on a new invoice. The settings row is locked while the
+// invoice takes the next number, so numbers are never skipped or reused. It returns nil when
+// the group was invoiced concurrently.
+func (s *CommissionInvoiceService) issueInvoice(ctx context.Context, tenantID string, group commissionGroup, vendor *clients.Vendor, periodStart, periodEnd time.Time) (*models.CommissionInvoice, error) {
+	var invoice *models.CommissionInvoice
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var settings models.CommissionInvoiceSettings
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ?", tenantID).
+			First(&settings).Error; err != nil {
+			return err
+		}
+
+		var entries []models.PlatformFeeLedger
+		if err := s.uninvoicedEntries(tx, tenantID, periodStart, periodEnd).
+			Where("vendor_id = ? AND currency = ?", group.VendorID, group.Currency).
+			Preload("PaymentTransaction").
+			Preload("RefundTransaction.PaymentTransaction").
+			Order("created_at ASC").
+			Find(&entries).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		inv := &models.CommissionInvoice{
+			ID:            uuid.New(),
+			TenantID:      tenantID,
+			VendorID:      group.VendorID,
+			InvoiceNumber: fmt.Sprintf("%s-%d-%06d", settings.InvoicePrefix, periodStart.Year(), settings.NextSequence),
+			Sequence:      settings.NextSequence,
+			PeriodStart:   periodStart,
+			PeriodEnd:     periodEnd.Add(-time.Second),
+			Currency:      group.Currency,
+			VendorName:    vendor.Name,
+			IssuerName:    settings.LegalName,
+			IssuerTaxID:   settings.TaxID,
+			IssuerAddress: settings.Address,
+			EntryCount:    len(entries),
+			IssuedAt:      now,
+			CreatedAt:     now,
+		}
+		if vendor.TaxIdentificationNumber != nil {
+			inv.VendorTaxID = *vendor.TaxIdentificationNumber
+		}
+		if address := vendor.BillingAddress(); address != nil {
+			inv.VendorAddress = address.String()
+			inv.VendorCountry = address.Country
+			inv.VendorState = address.State
+		}
+
+		ids := make([]uuid.UUID, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+			if entry.Amount < 0 {
+				inv.RefundedAmount += -entry.Amount
+			} else {
+				inv.CommissionAmount += entry.Amount
+			}
+			inv.LineItems = append(inv.LineItems, models.CommissionInvoiceLine{
+				LedgerEntryID: entry.ID,
+				EntryType:     entry.EntryType,
+				OrderID:       ledgerEntryOrderID(&entry),
+				Date:          entry.CreatedAt,
+				Amount:        entry.Amount,
+			})
+		}
+		inv.CommissionAmount = roundAmount(inv.CommissionAmount)
+		inv.RefundedAmount = roundAmount(inv.RefundedAmount)
+		inv.TaxableAmount = roundAmount(inv.CommissionAmount - inv.RefundedAmount)
+		applyCommissionTax(inv, &settings)
+
+		if err := tx.Create(inv).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&settings).Update("next_sequence", settings.NextSequence+1).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.PlatformFeeLedger{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"commission_invoice_id": inv.ID,
+				"updated_at":            now,
+			}).Error; err != nil {
+			return err
+		}
+
+		invoice = inv
+		return nil
+	})
+	return invoice, err
+}
+
+// ledgerEntryOrderID returns the order a fee or fee refund was charged on
+func ledgerEntryOrderID(entry *models.PlatformFeeLedger) *uuid.UUID {
+	if entry.PaymentTransaction != nil {
+		return &entry.PaymentTransaction.OrderID
+	}
+	if entry.RefundTransaction != nil && entry.RefundTransaction.PaymentTransaction != nil {
+		return &entry.RefundTransaction.PaymentTransaction.OrderID
+	}
+	return nil
+}
+
+// applyCommissionTax works out the tax on the invoice's taxable amount under the tenant's scheme.
+// GST is split into CGST and SGST when the vendor is in the marketplace's state and charged as
+// IGST when it isn't; commission billed to a vendor outside India is a zero-rated export. VAT
+// on commission billed to a VAT-registered vendor in another country is reverse charged.
+func applyCommissionTax(inv *models.CommissionInvoice, settings *models.CommissionInvoiceSettings) {
+	inv.TaxScheme = settings.TaxScheme
+	inv.TaxRate = settings.TaxRate
+	tax := func(rate float64) float64 {
+		return roundAmount(inv.TaxableAmount * rate / 100)
+	}
+
+	switch settings.TaxScheme {
+	case models.CommissionTaxGST:
+		switch {
+		case inv.VendorCountry != "" && !isIndia(inv.VendorCountry):
+			inv.TaxNote = "Export of services, zero-rated under IGST"
+		case inv.VendorState != "" && strings.EqualFold(strings.TrimSpace(inv.VendorState), strings.TrimSpace(settings.State)):
+			inv.CGSTAmount = tax(settings.TaxRate / 2)
+			inv.SGSTAmount = tax(settings.TaxRate / 2)
+		default:
+			inv.IGSTAmount = tax(settings.TaxRate)
+		}
+		inv.TaxAmount = roundAmount(inv.CGSTAmount + inv.SGSTAmount + inv.IGSTAmount)
+	case models.CommissionTaxVAT:
+		if inv.VendorTaxID != "" && inv.VendorCountry != "" && !sameCountry(inv.VendorCountry, settings.Country) {
+			inv.ReverseCharge = true
+			inv.TaxNote = "Reverse charge: VAT to be accounted for by the recipient"
+		} else {
+			inv.VATAmount = tax(settings.TaxRate)
+		}
+		inv.TaxAmount = inv.VATAmount
+	default:
+		inv.TaxScheme = models.CommissionTaxNone
+		inv.TaxRate = 0
+	}
+
+	inv.TotalAmount = roundAmount(inv.TaxableAmount + inv.TaxAmount)
+}
+
+// isIndia reports whether a vendor-service country (an ISO code or a name) is India
+func isIndia(country string) bool {
+	return sameCountry(country, "IN") || strings.EqualFold(strings.TrimSpace(country), "India")
+}
+
+func sameCountry(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// ListInvoices lists the tenant's commission invoices, newest first, optionally for one vendor.
+// Line items are left out; they come with GetInvoice.
+func (s *CommissionInvoiceService) ListInvoices(ctx context.Context, tenantID, vendorID string, limit, offset int) ([]models.CommissionInvoice, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.CommissionInvoice{}).Where("tenant_id = ?", tenantID)
+	if vendorID != "" {
+		query = query.Where("vendor_id = ?", vendorID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var invoices []models.CommissionInvoice
+	if err := query.Omit("line_items").
+		Order("period_start DESC, sequence DESC").
+		Limit(limit).Offset(offset).
+		Find(&invoices).Error; err != nil {
+		return nil, 0, err
+	}
+	return invoices, total, nil
+}
+
+// GetInvoice returns one of the tenant's commission invoices with its line items
+func (s *CommissionInvoiceService) GetInvoice(ctx context.Context, tenantID string, id uuid.UUID) (*models.CommissionInvoice, error) {
+	var invoice models.CommissionInvoice
+	err := s.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).First(&invoice).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCommissionInvoiceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+const commissionInvoiceInterval = time.Hour
+
+// CommissionInvoiceWorker invoices vendors for last month's commission once the month has ended,
+// for every tenant with commission invoicing enabled. Runs after the first pick up vendors that
+// were skipped because vendor-service couldn't be reached.
+type CommissionInvoiceWorker struct {
+	invoiceService *CommissionInvoiceService
+	stopCh         chan struct{}
+}
+
+// NewCommissionInvoiceWorker creates a new commission invoice worker
+func NewCommissionInvoiceWorker(invoiceService *CommissionInvoiceService) *CommissionInvoiceWorker {
+	return &CommissionInvoiceWorker{
+		invoiceService: invoiceService,
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// Start issues due invoices immediately and then every commissionInvoiceInterval
+func (w *CommissionInvoiceWorker) Start() {
+	ticker := time.NewTicker(commissionInvoiceInterval)
+	defer ticker.Stop()
+
+	w.run()
+	for {
+		select {
+		case <-ticker.C:
+			w.run()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *CommissionInvoiceWorker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *CommissionInvoiceWorker) run() {
+	ctx := context.Background()
+	tenantIDs, err := w.invoiceService.ListEnabledTenants(ctx)
+	if err != nil {
+		log.Printf("Commission invoicing failed: %v", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	for _, tenantID := range tenantIDs {
+		issued, err := w.invoiceService.GenerateMonthlyInvoices(ctx, tenantID, lastMonth)
+		if err != nil && !errors.Is(err, ErrCommissionInvoicingDisabled) {
+			log.Printf("Commission invoicing failed for tenant %s: %v", tenantID, err)
+		}
+		if len(issued) > 0 {
+			log.Printf("Issued %d commission invoices for tenant %s", len(issued), tenantID)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"payment-service/internal/models"
+	"payment-service/internal/repository"
+)
+
+var (
+	// ErrNotTenantDefault is returned when propagating a config that is itself a storefront override
+	ErrNotTenantDefault = errors.New("only tenant-level gateway configs can be propagated")
+	// ErrUnknownGatewayField is returned for a field name that storefront configs don't inherit
+	ErrUnknownGatewayField = errors.New("unknown gateway config field")
+)
+
+// gatewayConfigField is a setting storefront overrides inherit from the tenant default
+type gatewayConfigField struct {
+	name  string
+	copy  func(dst, src *models.PaymentGatewayConfig)
+	equal func(a, b *models.PaymentGatewayConfig) bool
+}
+
+// gatewayConfigFields are the inherited settings, in the order they are reported
+var gatewayConfigFields = []gatewayConfigField{
+	{
+		name:  models.GatewayFieldDisplayName,
+		copy:  func(dst, src *models.PaymentGatewayConfig) { dst.DisplayName = src.DisplayName },
+		equal: func(a, b *models.PaymentGatewayConfig) bool { return a.DisplayName == b.DisplayName },
+	},
+	{
+		name:  models.GatewayFieldIsEnabled,
+		copy:  func(dst, src *models.PaymentGatewayConfig) { dst.IsEnabled = src.IsEnabled },
+		equal: func(a, b *models.PaymentGatewayConfig) bool { return a.IsEnabled == b.IsEnabled },
+	},
+	{
+		name:  models.GatewayFieldIsTestMode,
+		copy:  func(dst, src *models.PaymentGatewayConfig) { dst.IsTestMode = src.IsTestMode },
+		equal: func(a, b *models.PaymentGatewayConfig) bool { return a.IsTestMode == b.IsTestMode },
+	},
+	{
+		name:  models.GatewayFieldConfig,
+		copy:  func(dst, src *models.PaymentGatewayConfig) { dst.Config = copyJSONB(src.Config) },
+		equal: func(a, b *models.PaymentGatewayConfig) bool { return sameJSONB(a.Config, b.Config) },
+	},
+	{
+		name: models.GatewayFieldFeatures,
+		copy: func(dst, src *models.PaymentGatewayConfig) {
+			dst.SupportsPayments = src.SupportsPayments
+			dst.SupportsRefunds = src.SupportsRefunds
+			dst.SupportsSubscriptions = src.SupportsSubscriptions
+		},
+		equal: func(a, b *models.PaymentGatewayConfig) bool {
+			return a.SupportsPayments == b.SupportsPayments &&
+				a.SupportsRefunds == b.SupportsRefunds &&
+				a.SupportsSubscriptions == b.SupportsSubscriptions
+		},
+	},
+	{
+		name: models.GatewayFieldPlatformSplit,
+		copy: func(dst, src *models.PaymentGatewayConfig) {
+			dst.MerchantAccountID = src.MerchantAccountID
+			dst.PlatformAccountID = src.PlatformAccountID
+			dst.SupportsPlatformSplit = src.SupportsPlatformSplit
+		},
+		equal: func(a, b *models.PaymentGatewayConfig) bool {
+			return a.MerchantAccountID == b.MerchantAccountID &&
+				a.PlatformAccountID == b.PlatformAccountID &&
+				a.SupportsPlatformSplit == b.SupportsPlatformSplit
+		},
+	},
+	{
+		name: models.GatewayFieldSupportedCountries,
+		copy: func(dst, src *models.PaymentGatewayConfig) {
+			dst.SupportedCountries = append(models.StringArray(nil), src.SupportedCountries...)
+		},
+		equal: func(a, b *models.PaymentGatewayConfig) bool {
+			return sameStrings(a.SupportedCountries, b.SupportedCountries)
+		},
+	},
+	{
+		name: models.GatewayFieldSupportedPaymentMethods,
+		copy: func(dst, src *models.PaymentGatewayConfig) {
+			dst.SupportedPaymentMethods = append(models.StringArray(nil), src.SupportedPaymentMethods...)
+		},
+		equal: func(a, b *models.PaymentGatewayConfig) bool {
+			return sameStrings(a.SupportedPaymentMethods, b.SupportedPaymentMethods)
+		},
+	},
+	{
+		name:  models.GatewayFieldFeeStructure,
+		copy:  func(dst, src *models.PaymentGatewayConfig) { dst.FeeStructure = copyJSONB(src.FeeStructure) },
+		equal: func(a, b *models.PaymentGatewayConfig) bool { return sameJSONB(a.FeeStructure, b.FeeStructure) },
+	},
+	{
+		name: models.GatewayFieldLimits,
+		copy: func(dst, src *models.PaymentGatewayConfig) {
+			dst.MinimumAmount = src.MinimumAmount
+			dst.MaximumAmount = src.MaximumAmount
+		},
+		equal: func(a, b *models.PaymentGatewayConfig) bool {
+			return a.MinimumAmount == b.MinimumAmount && a.MaximumAmount == b.MaximumAmount
+		},
+	},
+	{
+		name: models.GatewayFieldDisplay,
+		copy: func(dst, src *models.PaymentGatewayConfig) {
+			dst.Priority = src.Priority
+			dst.Description = src.Description
+			dst.LogoURL = src.LogoURL
+		},
+		equal: func(a, b *models.PaymentGatewayConfig) bool {
+			return a.Priority == b.Priority && a.Description == b.Description && a.LogoURL == b.LogoURL
+		},
+	},
+}
+
+// GatewayPropagationService applies tenant-level gateway configs to storefront overrides and
+// reports storefront configs that have drifted from their tenant default
+type GatewayPropagationService struct {
+	repo *repository.PaymentRepository
+}
+
+// NewGatewayPropagationService creates a new gateway propagation service
+func NewGatewayPropagationService(repo *repository.PaymentRepository) *GatewayPropagationService {
+	return &GatewayPropagationService{
+		repo: repo,
+	}
+}
+
+// Propagate copies a tenant-level gateway config's settings to the selected storefronts.
+// Storefronts without an override for the gateway get one created as a copy of the default;
+// existing overrides are updated, skipping the fields they override unless req.Force is set.
+// Each storefront is applied independently, so one failing doesn't stop the others.
+func (s *GatewayPropagationService) Propagate(ctx context.Context, tenantID string, defaultID uuid.UUID, req models.PropagateGatewayConfigRequest) ([]models.GatewayPropagationResult, error) {
+	defaultConfig, err := s.repo.GetGatewayConfig(ctx, defaultID)
+	if err != nil {
+		return nil, err
+	}
+	if defaultConfig.TenantID != tenantID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if defaultConfig.StorefrontID != "" {
+		return nil, ErrNotTenantDefault
+	}
+
+	fields, err := selectGatewayConfigFields(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.repo.ListStorefrontGatewayConfigs(ctx, tenantID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storefront gateway configs: %w", err)
+	}
+	byStorefront := make(map[string]*models.PaymentGatewayConfig)
+	for i := range overrides {
+		if overrides[i].GatewayType == defaultConfig.GatewayType {
+			byStorefront[overrides[i].StorefrontID] = &overrides[i]
+		}
+	}
+
+	var results []models.GatewayPropagationResult
+	seen := make(map[string]bool)
+	for _, storefrontID := range req.StorefrontIDs {
+		storefrontID = strings.TrimSpace(storefrontID)
+		if storefrontID == "" || seen[storefrontID] {
+			continue
+		}
+		seen[storefrontID] = true
+
+		var result models.GatewayPropagationResult
+		if override, ok := byStorefront[storefrontID]; ok {
+			result = s.updateOverride(ctx, defaultConfig, override, fields, req.Force)
+		} else {
+			result = s.createOverride(ctx, defaultConfig, storefrontID)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// createOverride creates a storefront's config as a copy of the tenant default
+func (s *GatewayPropagationService) createOverride(ctx context.Context, defaultConfig *models.PaymentGatewayConfig, storefrontID string) models.GatewayPropagationResult {
+	result := models.GatewayPropagationResult{StorefrontID: storefrontID}
+
+	override := *defaultConfig
+	override.ID = uuid.Nil
+	override.StorefrontID = storefrontID
+	override.OverriddenFields = nil
+	override.Regions = nil
+	override.Version = 1
+	override.CreatedAt = time.Time{}
+	override.UpdatedAt = time.Time{}
+	for _, field := range gatewayConfigFields {
+		field.copy(&override, defaultConfig)
+	}
+
+	if err := s.repo.CreateGatewayConfig(ctx, &override); err != nil {
+		result.Action = models.GatewayPropagationFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.ConfigID = override.ID
+	result.Action = models.GatewayPropagationCreated
+	return result
+}
+
+// updateOverride copies the selected fields of the tenant default onto a storefront's config
+func (s *GatewayPropagationService) updateOverride(ctx context.Context, defaultConfig, override *models.PaymentGatewayConfig, fields []gatewayConfigField, force bool) models.GatewayPropagationResult {
+	result := models.GatewayPropagationResult{
+		StorefrontID: override.StorefrontID,
+		ConfigID:     override.ID,
+		Action:       models.GatewayPropagationUnchanged,
+	}
+
+	overridden := make(map[string]bool, len(override.OverriddenFields))
+	for _, name := range override.OverriddenFields {
+		overridden[name] = true
+	}
+
+	cleared := false
+	for _, field := range fields {
+		if overridden[field.name] {
+			if !force {
+				result.SkippedFields = append(result.SkippedFields, field.name)
+				continue
+			}
+			delete(overridden, field.name)
+			cleared = true
+		}
+		if !field.equal(override, defaultConfig) {
+			field.copy(override, defaultConfig)
+			result.UpdatedFields = append(result.UpdatedFields, field.name)
+		}
+	}
+	if len(result.UpdatedFields) == 0 && !cleared {
+		return result
+	}
+
+	if cleared {
+		remaining := models.StringArray{}
+		for _, name := range override.OverriddenFields {
+			if overridden[name] {
+				remaining = append(remaining, name)
+			}
+		}
+		override.OverriddenFields = remaining
+	}
+
+	if err := s.repo.UpdateGatewayConfig(ctx, override); err != nil {
+		result.Action = models.GatewayPropagationFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.Action = models.GatewayPropagationUpdated
+	return result
+}
+
+// DetectDrift compares every storefront gateway config with the tenant default of the same
+// type and reports those that differ on settings they don't override, or have no default
+func (s *GatewayPropagationService) DetectDrift(ctx context.Context, tenantID string) (*models.GatewayDriftReport, error) {
+	defaults, err := s.repo.ListGatewayConfigs(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gateway configs: %w", err)
+	}
+	overrides, err := s.repo.ListStorefrontGatewayConfigs(ctx, tenantID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storefront gateway configs: %w", err)
+	}
+
+	defaultsByType := make(map[models.GatewayType]*models.PaymentGatewayConfig, len(defaults))
+	for i := range defaults {
+		defaultsByType[defaults[i].GatewayType] = &defaults[i]
+	}
+
+	report := &models.GatewayDriftReport{
+		CheckedAt:         time.Now(),
+		StorefrontConfigs: len(overrides),
+		Drift:             []models.GatewayConfigDrift{},
+	}
+	for i := range overrides {
+		override := &overrides[i]
+		drift := models.GatewayConfigDrift{
+			StorefrontID:     override.StorefrontID,
+			GatewayType:      override.GatewayType,
+			ConfigID:         override.ID,
+			OverriddenFields: override.OverriddenFields,
+		}
+
+		defaultConfig, ok := defaultsByType[override.GatewayType]
+		if !ok {
+			drift.MissingDefault = true
+			report.Drift = append(report.Drift, drift)
+			continue
+		}
+		drift.DefaultConfigID = &defaultConfig.ID
+
+		overridden := make(map[string]bool, len(override.OverriddenFields))
+		for _, name := range override.OverriddenFields {
+			overridden[name] = true
+		}
+		for _, field := range gatewayConfigFields {
+			if !overridden[field.name] && !field.equal(override, defaultConfig) {
+				drift.DriftedFields = append(drift.DriftedFields, field.name)
+			}
+		}
+		if len(drift.DriftedFields) > 0 {
+			report.Drift = append(report.Drift, drift)
+		}
+	}
+	report.Drifted = len(report.Drift)
+	return report, nil
+}
+
+// selectGatewayConfigFields resolves field names to inherited settings; none selects all
+func selectGatewayConfigFields(names []string) ([]gatewayConfigField, error) {
+	if len(names) == 0 {
+		return gatewayConfigFields, nil
+	}
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		selected[name] = true
+	}
+	var fields []gatewayConfigField
+	for _, field := range gatewayConfigFields {
+		if selected[field.name] {
+			fields = append(fields, field)
+			delete(selected, field.name)
+		}
+	}
+	for name := range selected {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownGatewayField, name)
+	}
+	return fields, nil
+}
+
+// sameStrings compares string arrays, treating nil and empty as equal
+func sameStrings(a, b models.StringArray) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameJSONB compares JSON documents by their encoding, treating nil and empty as equal
+func sameJSONB(a, b models.JSONB) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+// copyJSONB returns a deep copy of a JSON document, so overrides don't share maps with the default
+func copyJSONB(src models.JSONB) models.JSONB {
+	if src == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(src)
+	if err != nil {
+		return src
+	}
+	var dst models.JSONB
+	if err := json.Unmarshal(encoded, &dst); err != nil {
+		return src
+	}
+	return dst
+}
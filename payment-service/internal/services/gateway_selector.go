@@ -60,10 +60,11 @@ type PaymentMethodInfo struct {
 	GatewayType models.GatewayType       `json:"gatewayType"`
 }
 
-// GetAvailableGateways returns all configured and enabled gateways for a tenant and country
-func (s *GatewaySelectorService) GetAvailableGateways(ctx context.Context, tenantID string, countryCode string) ([]GatewayOption, error) {
-	// Get all gateway configs for tenant
-	configs, err := s.repo.ListGatewayConfigs(ctx, tenantID)
+// GetAvailableGateways returns all configured and enabled gateways for a tenant and country.
+// On a storefront, its gateway config overrides replace the tenant defaults.
+func (s *GatewaySelectorService) GetAvailableGateways(ctx context.Context, tenantID, storefrontID string, countryCode string) ([]GatewayOption, error) {
+	// Get all gateway configs in effect for the tenant or storefront
+	configs, err := s.repo.ListEffectiveGatewayConfigs(ctx, tenantID, storefrontID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list gateway configs: %w", err)
 	}
@@ -133,8 +134,8 @@ func (s *GatewaySelectorService) GetAvailableGateways(ctx context.Context, tenan
 }
 
 // GetPaymentMethods returns all available payment methods for a tenant and country
-func (s *GatewaySelectorService) GetPaymentMethods(ctx context.Context, tenantID string, countryCode string) ([]PaymentMethodInfo, error) {
-	gateways, err := s.GetAvailableGateways(ctx, tenantID, countryCode)
+func (s *GatewaySelectorService) GetPaymentMethods(ctx context.Context, tenantID, storefrontID string, countryCode string) ([]PaymentMethodInfo, error) {
+	gateways, err := s.GetAvailableGateways(ctx, tenantID, storefrontID, countryCode)
 	if err != nil {
 		return nil, err
 	}
@@ -170,7 +171,7 @@ func (s *GatewaySelectorService) GetPaymentMethods(ctx context.Context, tenantID
 
 // GetPrimaryGateway returns the primary gateway for a tenant and country
 func (s *GatewaySelectorService) GetPrimaryGateway(ctx context.Context, tenantID string, countryCode string) (*models.PaymentGatewayConfig, error) {
-	gateways, err := s.GetAvailableGateways(ctx, tenantID, countryCode)
+	gateways, err := s.GetAvailableGateways(ctx, tenantID, "", countryCode)
 	if err != nil {
 		return nil, err
 	}
@@ -188,7 +189,7 @@ func (s *GatewaySelectorService) GetPrimaryGateway(ctx context.Context, tenantID
 
 // GetGatewayForPaymentMethod returns the best gateway for a specific payment method and country
 func (s *GatewaySelectorService) GetGatewayForPaymentMethod(ctx context.Context, tenantID string, countryCode string, methodType models.PaymentMethodType) (*models.PaymentGatewayConfig, error) {
-	gateways, err := s.GetAvailableGateways(ctx, tenantID, countryCode)
+	gateways, err := s.GetAvailableGateways(ctx, tenantID, "", countryCode)
 	if err != nil {
 		return nil, err
 	}
@@ -557,7 +558,7 @@ func (s *GatewaySelectorService) GetCountryGatewayMatrix(ctx context.Context, te
 	// Build matrix
 	matrix := make(map[string][]GatewayOption)
 	for country := range countrySet {
-		gateways, err := s.GetAvailableGateways(ctx, tenantID, country)
+		gateways, err := s.GetAvailableGateways(ctx, tenantID, "", country)
 		if err != nil {
 			continue
 		}
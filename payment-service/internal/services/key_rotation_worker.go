@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"payment-service/internal/encryption"
+)
+
+const keyRotationInterval = 24 * time.Hour
+
+// KeyRotationWorker rotates tenant data encryption keys older than the rotation age and
+// re-encrypts rows that are still on a retired key, or still in plaintext, with the
+// tenant's active key.
+type KeyRotationWorker struct {
+	db          *gorm.DB
+	keyring     *encryption.Keyring
+	models      []interface{}
+	rotationAge time.Duration
+	stopCh      chan struct{}
+}
+
+// NewKeyRotationWorker creates a new key rotation worker for the given models, which must
+// have a tenant_id column and at least one encrypted field
+func NewKeyRotationWorker(db *gorm.DB, keyring *encryption.Keyring, rotationAge time.Duration, models ...interface{}) *KeyRotationWorker {
+	return &KeyRotationWorker{
+		db:          db,
+		keyring:     keyring,
+		models:      models,
+		rotationAge: rotationAge,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start runs a rotation immediately and then every keyRotationInterval
+func (w *KeyRotationWorker) Start() {
+	ticker := time.NewTicker(keyRotationInterval)
+	defer ticker.Stop()
+
+	w.runRotation()
+	for {
+		select {
+		case <-ticker.C:
+			w.runRotation()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *KeyRotationWorker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *KeyRotationWorker) runRotation() {
+	if err := w.rotate(context.Background()); err != nil {
+		log.Printf("Key rotation failed: %v", err)
+	}
+}
+
+// rotate rotates the keys that are due, then re-encrypts every tenant that has retired
+// keys or plaintext rows. A failing tenant is logged and retried on the next run.
+func (w *KeyRotationWorker) rotate(ctx context.Context) error {
+	due, err := w.keyring.KeysDueForRotation(ctx, time.Now().Add(-w.rotationAge))
+	if err != nil {
+		return err
+	}
+	for _, key := range due {
+		version, err := w.keyring.Rotate(ctx, key.TenantID)
+		if err != nil {
+			log.Printf("Failed to rotate data key for tenant %s: %v", key.TenantID, err)
+			continue
+		}
+		log.Printf("Rotated data key for tenant %s: v%d -> v%d", key.TenantID, key.Version, version)
+	}
+
+	tenantIDs, err := w.keyring.TenantsWithRetiredKeys(ctx)
+	if err != nil {
+		return err
+	}
+	pending := make(map[string]bool, len(tenantIDs))
+	for _, tenantID := range tenantIDs {
+		pending[tenantID] = true
+	}
+	for _, model := range w.models {
+		plaintext, err := encryption.PlaintextTenants(ctx, w.db, model)
+		if err != nil {
+			return err
+		}
+		for _, tenantID := range plaintext {
+			if tenantID != "" {
+				pending[tenantID] = true
+			}
+		}
+	}
+
+	for tenantID := range pending {
+		for _, model := range w.models {
+			count, err := encryption.ReencryptTenant(ctx, w.db, w.keyring, model, tenantID)
+			if err != nil {
+				log.Printf("Failed to re-encrypt %T rows for tenant %s: %v", model, tenantID, err)
+				continue
+			}
+			if count > 0 {
+				log.Printf("Re-encrypted %d %T rows for tenant %s", count, model, tenantID)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	paymentLinkExpiryInterval  = 5 * time.Minute
+	paymentLinkExpiryBatchSize = 500
+)
+
+// PaymentLinkExpiryWorker expires open payment links once they pass their expiry, so
+// expiry events are published even for links nobody opens again.
+type PaymentLinkExpiryWorker struct {
+	linkService *PaymentLinkService
+	stopCh      chan struct{}
+}
+
+// NewPaymentLinkExpiryWorker creates a new payment link expiry worker
+func NewPaymentLinkExpiryWorker(linkService *PaymentLinkService) *PaymentLinkExpiryWorker {
+	return &PaymentLinkExpiryWorker{
+		linkService: linkService,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start expires due links immediately and then every paymentLinkExpiryInterval
+func (w *PaymentLinkExpiryWorker) Start() {
+	ticker := time.NewTicker(paymentLinkExpiryInterval)
+	defer ticker.Stop()
+
+	w.run()
+	for {
+		select {
+		case <-ticker.C:
+			w.run()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *PaymentLinkExpiryWorker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *PaymentLinkExpiryWorker) run() {
+	expired, err := w.linkService.ExpireDuePaymentLinks(context.Background(), paymentLinkExpiryBatchSize)
+	if err != nil {
+		log.Printf("Payment link expiry failed: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("Expired %d payment links", expired)
+	}
+}
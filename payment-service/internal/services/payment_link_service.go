@@ -0,0 +1,478 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"payment-service/internal/clients"
+	"payment-service/internal/events"
+	"payment-service/internal/models"
+)
+
+const (
+	// paymentLinkMetadataKey tags payment transactions created from a payment link
+	paymentLinkMetadataKey = "payment_link_id"
+
+	defaultPaymentLinkExpiry = 7 * 24 * time.Hour
+	maxPaymentLinkExpiry     = 90 * 24 * time.Hour
+)
+
+// ErrPaymentLinksDisabled is returned when no signing secret is configured
+var ErrPaymentLinksDisabled = errors.New("payment links are not configured")
+
+// ErrPaymentLinkNotFound is returned when the payment link does not exist
+var ErrPaymentLinkNotFound = errors.New("payment link not found")
+
+// ErrInvalidPaymentLinkSignature is returned when a hosted link was tampered with
+var ErrInvalidPaymentLinkSignature = errors.New("invalid payment link signature")
+
+// ErrPaymentLinkNotPayable is returned when the link is paid, expired or canceled
+var ErrPaymentLinkNotPayable = errors.New("payment link can no longer be paid")
+
+// ErrInvalidPaymentLinkAmount is returned when a payment amount is outside what the link allows
+var ErrInvalidPaymentLinkAmount = errors.New("invalid payment amount for this link")
+
+// ErrInvalidPaymentLinkExpiry is returned when the requested expiry is in the past or too far out
+var ErrInvalidPaymentLinkExpiry = errors.New("expiry must be in the future and at most 90 days away")
+
+// PaymentLinkService manages hosted, signed payment links for invoices and manual orders
+type PaymentLinkService struct {
+	db             *gorm.DB
+	paymentService *PaymentService
+	tenantClient   *clients.TenantClient
+	publisher      *events.Publisher
+	signingSecret  []byte
+}
+
+// NewPaymentLinkService creates a new payment link service. publisher may be nil, in which
+// case status changes are only reported to orders-service.
+func NewPaymentLinkService(db *gorm.DB, paymentService *PaymentService, tenantClient *clients.TenantClient, publisher *events.Publisher, signingSecret string) *PaymentLinkService {
+	return &PaymentLinkService{
+		db:             db,
+		paymentService: paymentService,
+		tenantClient:   tenantClient,
+		publisher:      publisher,
+		signingSecret:  []byte(signingSecret),
+	}
+}
+
+// CreatePaymentLink creates a new payment link and returns it with its signed hosted URL
+func (s *PaymentLinkService) CreatePaymentLink(ctx context.Context, tenantID, createdBy string, req *models.CreatePaymentLinkRequest) (*models.PaymentLinkResponse, error) {
+	if len(s.signingSecret) == 0 {
+		return nil, ErrPaymentLinksDisabled
+	}
+	if tenantID == "" {
+		return nil, ErrInvalidTenantID
+	}
+
+	amount := roundAmount(req.Amount)
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be greater than 0", ErrInvalidPaymentLinkAmount)
+	}
+	if req.MinimumPaymentAmount > amount {
+		return nil, fmt.Errorf("%w: minimum payment exceeds the link amount", ErrInvalidPaymentLinkAmount)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(defaultPaymentLinkExpiry)
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	} else if req.ExpiresInHours > 0 {
+		expiresAt = now.Add(time.Duration(req.ExpiresInHours) * time.Hour)
+	}
+	if !expiresAt.After(now) || expiresAt.Sub(now) > maxPaymentLinkExpiry {
+		return nil, ErrInvalidPaymentLinkExpiry
+	}
+
+	code, err := generatePaymentLinkCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate payment link code: %w", err)
+	}
+
+	metadata := make(models.JSONB)
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+
+	link := &models.PaymentLink{
+		TenantID:             tenantID,
+		Code:                 code,
+		OrderID:              req.OrderID,
+		OrderNumber:          req.OrderNumber,
+		Description:          req.Description,
+		Amount:               amount,
+		Currency:             strings.ToUpper(req.Currency),
+		AllowPartialPayments: req.AllowPartialPayments,
+		MinimumPaymentAmount: roundAmount(req.MinimumPaymentAmount),
+		CustomerEmail:        req.CustomerEmail,
+		CustomerName:         req.CustomerName,
+		CustomerPhone:        req.CustomerPhone,
+		Status:               models.PaymentLinkActive,
+		ExpiresAt:            expiresAt,
+		Metadata:             metadata,
+		CreatedBy:            createdBy,
+	}
+
+	if err := s.db.WithContext(ctx).Create(link).Error; err != nil {
+		return nil, fmt.Errorf("failed to create payment link: %w", err)
+	}
+
+	return s.toResponse(ctx, link), nil
+}
+
+// GetPaymentLink retrieves a tenant's payment link by ID
+func (s *PaymentLinkService) GetPaymentLink(ctx context.Context, tenantID string, id uuid.UUID) (*models.PaymentLinkResponse, error) {
+	var link models.PaymentLink
+	if err := s.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment link: %w", err)
+	}
+
+	s.expireIfDue(ctx, &link, time.Now())
+	return s.toResponse(ctx, &link), nil
+}
+
+// ListPaymentLinks lists a tenant's payment links, optionally filtered by status and order
+func (s *PaymentLinkService) ListPaymentLinks(ctx context.Context, tenantID string, status models.PaymentLinkStatus, orderID *uuid.UUID, limit, offset int) ([]models.PaymentLinkResponse, int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.PaymentLink{}).Where("tenant_id = ?", tenantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if orderID != nil {
+		query = query.Where("order_id = ?", *orderID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count payment links: %w", err)
+	}
+
+	var links []models.PaymentLink
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&links).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list payment links: %w", err)
+	}
+
+	responses := make([]models.PaymentLinkResponse, 0, len(links))
+	for i := range links {
+		responses = append(responses, *s.toResponse(ctx, &links[i]))
+	}
+	return responses, total, nil
+}
+
+// CancelPaymentLink cancels a link so it can no longer be paid. Payments already taken are kept.
+func (s *PaymentLinkService) CancelPaymentLink(ctx context.Context, tenantID string, id uuid.UUID) (*models.PaymentLinkResponse, error) {
+	var link models.PaymentLink
+	if err := s.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment link: %w", err)
+	}
+
+	if link.Status != models.PaymentLinkActive && link.Status != models.PaymentLinkPartiallyPaid {
+		return nil, ErrPaymentLinkNotPayable
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.PaymentLink{}).
+		Where("id = ? AND status IN ?", link.ID, []models.PaymentLinkStatus{models.PaymentLinkActive, models.PaymentLinkPartiallyPaid}).
+		Updates(map[string]interface{}{"status": models.PaymentLinkCanceled, "updated_at": time.Now()})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to cancel payment link: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrPaymentLinkNotPayable
+	}
+
+	link.Status = models.PaymentLinkCanceled
+	s.publishStatus(&link, events.PaymentLinkCanceled, link.ID.String())
+	return s.toResponse(ctx, &link), nil
+}
+
+// ResolvePaymentLink returns the public view of a link for the storefront's hosted payment page
+func (s *PaymentLinkService) ResolvePaymentLink(ctx context.Context, tenantID, code, signature string) (*models.PublicPaymentLinkResponse, error) {
+	link, err := s.getVerifiedLink(ctx, tenantID, code, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	s.expireIfDue(ctx, link, time.Now())
+	return &models.PublicPaymentLinkResponse{
+		Code:                 link.Code,
+		OrderNumber:          link.OrderNumber,
+		Description:          link.Description,
+		Amount:               link.Amount,
+		AmountPaid:           link.AmountPaid,
+		AmountDue:            link.AmountDue(),
+		Currency:             link.Currency,
+		AllowPartialPayments: link.AllowPartialPayments,
+		MinimumPaymentAmount: link.MinimumPaymentAmount,
+		CustomerEmail:        link.CustomerEmail,
+		CustomerName:         link.CustomerName,
+		Status:               link.Status,
+		ExpiresAt:            link.ExpiresAt,
+	}, nil
+}
+
+// PayPaymentLink creates a payment intent for (part of) the amount due on a link
+func (s *PaymentLinkService) PayPaymentLink(ctx context.Context, tenantID, code string, req *models.PayPaymentLinkRequest) (*models.PaymentIntentResponse, error) {
+	link, err := s.getVerifiedLink(ctx, tenantID, code, req.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if s.expireIfDue(ctx, link, now) || !link.IsPayable(now) {
+		return nil, ErrPaymentLinkNotPayable
+	}
+
+	due := roundAmount(link.AmountDue())
+	amount := due
+	if link.AllowPartialPayments && req.Amount > 0 {
+		amount = roundAmount(req.Amount)
+		// The minimum only applies while more than the minimum is still outstanding
+		if amount > due || amount < math.Min(link.MinimumPaymentAmount, due) {
+			return nil, fmt.Errorf("%w: amount must be between %.2f and %.2f", ErrInvalidPaymentLinkAmount, math.Min(link.MinimumPaymentAmount, due), due)
+		}
+	}
+
+	orderID := link.ID
+	if link.OrderID != nil {
+		orderID = *link.OrderID
+	}
+
+	customerEmail := firstNonEmpty(req.CustomerEmail, link.CustomerEmail)
+	customerName := firstNonEmpty(req.CustomerName, link.CustomerName)
+	customerPhone := firstNonEmpty(req.CustomerPhone, link.CustomerPhone)
+
+	description := link.Description
+	if description == "" && link.OrderNumber != "" {
+		description = "Payment for order " + link.OrderNumber
+	}
+
+	linkURL := s.tenantClient.BuildPaymentLinkURL(ctx, link.TenantID, link.Code, s.sign(link))
+	return s.paymentService.CreatePaymentIntent(ctx, models.CreatePaymentIntentRequest{
+		TenantID:      link.TenantID,
+		OrderID:       orderID.String(),
+		Amount:        amount,
+		Currency:      link.Currency,
+		GatewayType:   req.GatewayType,
+		PaymentMethod: req.PaymentMethod,
+		CustomerEmail: customerEmail,
+		CustomerPhone: customerPhone,
+		CustomerName:  customerName,
+		Description:   description,
+		Metadata: map[string]string{
+			paymentLinkMetadataKey: link.ID.String(),
+			"payment_link_code":    link.Code,
+			"order_number":         link.OrderNumber,
+		},
+		ReturnURL: linkURL + "&result=success",
+		CancelURL: linkURL + "&result=canceled",
+	})
+}
+
+// Handles reports whether a payment transaction was created from a payment link. It is safe
+// to call on a nil service so gateway flows don't need to know whether links are enabled.
+func (s *PaymentLinkService) Handles(payment *models.PaymentTransaction) bool {
+	if s == nil || payment == nil {
+		return false
+	}
+	_, ok := payment.Metadata[paymentLinkMetadataKey]
+	return ok
+}
+
+// RecordPayment recomputes the amount paid on the payment's link from its succeeded
+// transactions, so duplicate webhooks for the same payment are harmless. Orders-service is
+// only told the order is PAID once the link is settled in full.
+func (s *PaymentLinkService) RecordPayment(ctx context.Context, payment *models.PaymentTransaction) {
+	linkID, err := uuid.Parse(fmt.Sprint(payment.Metadata[paymentLinkMetadataKey]))
+	if err != nil {
+		fmt.Printf("[PaymentLinkService] Invalid payment link ID on payment %s: %v\n", payment.ID, err)
+		return
+	}
+
+	var link models.PaymentLink
+	changed := false
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", linkID).First(&link).Error; err != nil {
+			return err
+		}
+
+		var amountPaid float64
+		if err := tx.Model(&models.PaymentTransaction{}).
+			Where("metadata->>'payment_link_id' = ? AND status = ?", link.ID.String(), models.PaymentSucceeded).
+			Select("COALESCE(SUM(amount), 0)").Scan(&amountPaid).Error; err != nil {
+			return err
+		}
+		amountPaid = roundAmount(amountPaid)
+		if amountPaid == link.AmountPaid {
+			return nil
+		}
+
+		link.AmountPaid = amountPaid
+		if amountPaid >= link.Amount {
+			now := time.Now()
+			link.Status = models.PaymentLinkPaid
+			link.PaidAt = &now
+		} else if link.Status == models.PaymentLinkActive {
+			link.Status = models.PaymentLinkPartiallyPaid
+		}
+		changed = true
+		return tx.Save(&link).Error
+	})
+	if err != nil {
+		fmt.Printf("[PaymentLinkService] Failed to record payment %s on link %s: %v\n", payment.ID, linkID, err)
+		return
+	}
+	if !changed {
+		return
+	}
+
+	if link.Status == models.PaymentLinkPaid {
+		s.publishStatus(&link, events.PaymentLinkPaid, payment.ID.String())
+		if link.OrderID != nil {
+			s.paymentService.notifyOrderPaymentStatus(link.OrderID.String(), link.TenantID, payment.ID.String(), "PAID")
+		}
+		return
+	}
+	s.publishStatus(&link, events.PaymentLinkPartiallyPaid, payment.ID.String())
+}
+
+// ExpireDuePaymentLinks marks up to limit open links past their expiry as EXPIRED
+func (s *PaymentLinkService) ExpireDuePaymentLinks(ctx context.Context, limit int) (int, error) {
+	var links []models.PaymentLink
+	if err := s.db.WithContext(ctx).
+		Where("status IN ? AND expires_at <= ?", []models.PaymentLinkStatus{models.PaymentLinkActive, models.PaymentLinkPartiallyPaid}, time.Now()).
+		Limit(limit).Find(&links).Error; err != nil {
+		return 0, fmt.Errorf("failed to list expired payment links: %w", err)
+	}
+
+	expired := 0
+	for i := range links {
+		if s.expireIfDue(ctx, &links[i], time.Now()) {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// getVerifiedLink loads a tenant's link by code and checks the hosted URL signature
+func (s *PaymentLinkService) getVerifiedLink(ctx context.Context, tenantID, code, signature string) (*models.PaymentLink, error) {
+	if len(s.signingSecret) == 0 {
+		return nil, ErrPaymentLinksDisabled
+	}
+
+	var link models.PaymentLink
+	if err := s.db.WithContext(ctx).Where("tenant_id = ? AND code = ?", tenantID, code).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment link: %w", err)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(&link))) {
+		return nil, ErrInvalidPaymentLinkSignature
+	}
+	return &link, nil
+}
+
+// expireIfDue marks an open link past its expiry as EXPIRED and reports whether it did
+func (s *PaymentLinkService) expireIfDue(ctx context.Context, link *models.PaymentLink, now time.Time) bool {
+	if link.Status != models.PaymentLinkActive && link.Status != models.PaymentLinkPartiallyPaid {
+		return false
+	}
+	if now.Before(link.ExpiresAt) {
+		return false
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.PaymentLink{}).
+		Where("id = ? AND status = ?", link.ID, link.Status).
+		Updates(map[string]interface{}{"status": models.PaymentLinkExpired, "updated_at": now})
+	if result.Error != nil {
+		fmt.Printf("[PaymentLinkService] Failed to expire payment link %s: %v\n", link.ID, result.Error)
+		return false
+	}
+	if result.RowsAffected == 0 {
+		return false
+	}
+
+	link.Status = models.PaymentLinkExpired
+	s.publishStatus(link, events.PaymentLinkExpired, link.ID.String())
+	return true
+}
+
+// publishStatus publishes a link status change event (non-blocking)
+func (s *PaymentLinkService) publishStatus(link *models.PaymentLink, eventType, paymentID string) {
+	if s.publisher == nil {
+		return
+	}
+
+	orderID := ""
+	if link.OrderID != nil {
+		orderID = link.OrderID.String()
+	}
+	snapshot := *link
+	go func() {
+		if err := s.publisher.PublishPaymentLinkStatus(context.Background(), eventType, snapshot.TenantID, paymentID, snapshot.ID.String(), snapshot.Code,
+			orderID, snapshot.OrderNumber, snapshot.CustomerEmail, snapshot.Amount, snapshot.AmountPaid, snapshot.Currency, string(snapshot.Status)); err != nil {
+			fmt.Printf("[PaymentLinkService] Failed to publish %s for link %s: %v\n", eventType, snapshot.ID, err)
+		}
+	}()
+}
+
+// sign returns the HMAC signature binding a link's tenant, code and expiry
+func (s *PaymentLinkService) sign(link *models.PaymentLink) string {
+	mac := hmac.New(sha256.New, s.signingSecret)
+	fmt.Fprintf(mac, "%s:%s:%d", link.TenantID, link.Code, link.ExpiresAt.Unix())
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// toResponse builds the admin view of a link, including its signed hosted URL
+func (s *PaymentLinkService) toResponse(ctx context.Context, link *models.PaymentLink) *models.PaymentLinkResponse {
+	signature := s.sign(link)
+	return &models.PaymentLinkResponse{
+		PaymentLink: *link,
+		AmountDue:   link.AmountDue(),
+		URL:         s.tenantClient.BuildPaymentLinkURL(ctx, link.TenantID, link.Code, signature),
+		Signature:   signature,
+	}
+}
+
+// generatePaymentLinkCode returns a random, URL-safe link code
+func generatePaymentLinkCode() (string, error) {
+	b := make([]byte, 15)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToLower(base32.StdEncoding.EncodeToString(b)), nil
+}
+
+// roundAmount rounds an amount to 2 decimal places
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,6 +27,9 @@ type PaymentService struct {
 	credentialsService *PaymentCredentialsService
 	notificationClient *clients.NotificationClient
 	tenantClient       *clients.TenantClient
+	paymentLinks       *PaymentLinkService
+	giftCardClient     *clients.GiftCardClient
+	vendorPayouts      *VendorPayoutService
 	useDynamicCreds    bool
 }
 
@@ -43,6 +47,16 @@ type PaymentServiceConfig struct {
 	// PayPal
 	PayPalClientID     string
 	PayPalClientSecret string
+	// PhonePe (merchant ID + salt key)
+	PhonePeMerchantID string
+	PhonePeSaltKey    string
+	// Cashfree
+	CashfreeClientID     string
+	CashfreeClientSecret string
+	// WebhookBaseURL is the public base URL gateways call back to (PhonePe, Cashfree)
+	WebhookBaseURL string
+	// UPIPaymentTimeout is how long UPI intent/QR/collect requests stay payable
+	UPIPaymentTimeout time.Duration
 }
 
 // NewPaymentService creates a new payment service
@@ -75,6 +89,22 @@ func NewPaymentServiceWithCredentials(
 	}
 }
 
+// SetPaymentLinkService routes payments created from payment links to the link service
+// instead of updating the order directly
+func (s *PaymentService) SetPaymentLinkService(paymentLinks *PaymentLinkService) {
+	s.paymentLinks = paymentLinks
+}
+
+// SetGiftCardClient enables store credit as a refund tender
+func (s *PaymentService) SetGiftCardClient(giftCardClient *clients.GiftCardClient) {
+	s.giftCardClient = giftCardClient
+}
+
+// SetVendorPayoutService holds and claws back vendor payouts as refunds on vendors' orders progress
+func (s *PaymentService) SetVendorPayoutService(vendorPayouts *VendorPayoutService) {
+	s.vendorPayouts = vendorPayouts
+}
+
 // loadPaymentConfigFromEnv loads credentials from environment variables (sealed secrets)
 func loadPaymentConfigFromEnv() *PaymentServiceConfig {
 	return &PaymentServiceConfig{
@@ -86,6 +116,12 @@ func loadPaymentConfigFromEnv() *PaymentServiceConfig {
 		RazorpayWebhookSecret: getEnv("RAZORPAY_WEBHOOK_SECRET", ""),
 		PayPalClientID:        getEnv("PAYPAL_CLIENT_ID", ""),
 		PayPalClientSecret:    getEnv("PAYPAL_CLIENT_SECRET", ""),
+		PhonePeMerchantID:     getEnv("PHONEPE_MERCHANT_ID", ""),
+		PhonePeSaltKey:        getEnv("PHONEPE_SALT_KEY", ""),
+		CashfreeClientID:      getEnv("CASHFREE_APP_ID", ""),
+		CashfreeClientSecret:  getEnv("CASHFREE_SECRET_KEY", ""),
+		WebhookBaseURL:        getEnv("PAYMENT_WEBHOOK_BASE_URL", ""),
+		UPIPaymentTimeout:     time.Duration(getEnvInt("UPI_PAYMENT_TIMEOUT_MINUTES", 10)) * time.Minute,
 	}
 }
 
@@ -96,6 +132,13 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
 // getDefaultGatewayConfig creates a default gateway config from environment variables
 // Used when no tenant-specific config exists in the database
 func (s *PaymentService) getDefaultGatewayConfig(gatewayType models.GatewayType) *models.PaymentGatewayConfig {
@@ -144,6 +187,34 @@ func (s *PaymentService) getDefaultGatewayConfig(gatewayType models.GatewayType)
 			SupportsPayments: true,
 			SupportsRefunds:  true,
 		}
+	case models.GatewayPhonePe:
+		if s.config.PhonePeSaltKey == "" {
+			return nil
+		}
+		return &models.PaymentGatewayConfig{
+			GatewayType:      models.GatewayPhonePe,
+			DisplayName:      "PhonePe",
+			IsEnabled:        true,
+			IsTestMode:       true,
+			APIKeyPublic:     s.config.PhonePeMerchantID,
+			APIKeySecret:     s.config.PhonePeSaltKey,
+			SupportsPayments: true,
+			SupportsRefunds:  true,
+		}
+	case models.GatewayCashfree:
+		if s.config.CashfreeClientSecret == "" {
+			return nil
+		}
+		return &models.PaymentGatewayConfig{
+			GatewayType:      models.GatewayCashfree,
+			DisplayName:      "Cashfree",
+			IsEnabled:        true,
+			IsTestMode:       true,
+			APIKeyPublic:     s.config.CashfreeClientID,
+			APIKeySecret:     s.config.CashfreeClientSecret,
+			SupportsPayments: true,
+			SupportsRefunds:  true,
+		}
 	default:
 		return nil
 	}
@@ -223,27 +294,30 @@ func (s *PaymentService) applyEnvCredentials(config *models.PaymentGatewayConfig
 			config.APIKeyPublic = s.config.PayPalClientID
 			config.APIKeySecret = s.config.PayPalClientSecret
 		}
-	}
-}
-
-// CreatePaymentIntent creates a payment intent
-func (s *PaymentService) CreatePaymentIntent(ctx context.Context, req models.CreatePaymentIntentRequest) (*models.PaymentIntentResponse, error) {
-	// Extract vendor ID from metadata if present (for multi-vendor marketplaces)
-	vendorID := ""
-	if req.Metadata != nil {
-		if vid, ok := req.Metadata["vendor_id"]; ok {
-			vendorID = vid
+	case models.GatewayPhonePe:
+		if s.config.PhonePeSaltKey != "" {
+			config.APIKeyPublic = s.config.PhonePeMerchantID
+			config.APIKeySecret = s.config.PhonePeSaltKey
+		}
+	case models.GatewayCashfree:
+		if s.config.CashfreeClientSecret != "" {
+			config.APIKeyPublic = s.config.CashfreeClientID
+			config.APIKeySecret = s.config.CashfreeClientSecret
 		}
 	}
+}
 
+// loadGatewayConfig returns the gateway config in effect on the storefront (the tenant's, when
+// storefrontID is empty or it has no override) with credentials applied
+func (s *PaymentService) loadGatewayConfig(ctx context.Context, tenantID, storefrontID string, gatewayType models.GatewayType, vendorID string) (*models.PaymentGatewayConfig, error) {
 	// Get gateway configuration from DB (for non-sensitive settings like enabled, test mode, etc.)
-	gatewayConfig, err := s.repo.GetGatewayConfigByType(ctx, req.TenantID, req.GatewayType)
+	gatewayConfig, err := s.repo.GetStorefrontGatewayConfigByType(ctx, tenantID, storefrontID, gatewayType)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// If no DB config, create a default one using env vars
-			gatewayConfig = s.getDefaultGatewayConfig(req.GatewayType)
+			gatewayConfig = s.getDefaultGatewayConfig(gatewayType)
 			if gatewayConfig == nil {
-				return nil, fmt.Errorf("payment gateway %s not configured or not enabled", req.GatewayType)
+				return nil, fmt.Errorf("payment gateway %s not configured or not enabled", gatewayType)
 			}
 		} else {
 			return nil, fmt.Errorf("failed to get gateway config: %w", err)
@@ -252,9 +326,26 @@ func (s *PaymentService) CreatePaymentIntent(ctx context.Context, req models.Cre
 
 	// Apply credentials - prefer dynamic (GCP Secret Manager) over static (env vars)
 	// This enables multi-tenant credential isolation
-	if err := s.applyDynamicCredentials(ctx, gatewayConfig, req.TenantID, vendorID); err != nil {
+	if err := s.applyDynamicCredentials(ctx, gatewayConfig, tenantID, vendorID); err != nil {
 		return nil, fmt.Errorf("failed to load payment credentials: %w", err)
 	}
+	return gatewayConfig, nil
+}
+
+// CreatePaymentIntent creates a payment intent
+func (s *PaymentService) CreatePaymentIntent(ctx context.Context, req models.CreatePaymentIntentRequest) (*models.PaymentIntentResponse, error) {
+	// Extract vendor ID from metadata if present (for multi-vendor marketplaces)
+	vendorID := ""
+	if req.Metadata != nil {
+		if vid, ok := req.Metadata["vendor_id"]; ok {
+			vendorID = vid
+		}
+	}
+
+	gatewayConfig, err := s.loadGatewayConfig(ctx, req.TenantID, req.Metadata["storefront_id"], req.GatewayType, vendorID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create payment transaction record
 	orderID, err := uuid.Parse(req.OrderID)
@@ -280,6 +371,7 @@ func (s *PaymentService) CreatePaymentIntent(ctx context.Context, req models.Cre
 		PaymentMethodType: req.PaymentMethod,
 		BillingEmail:      req.CustomerEmail,
 		BillingName:       req.CustomerName,
+		Channel:           models.ChannelOnline,
 		Metadata:          metadata,
 	}
 
@@ -290,7 +382,13 @@ func (s *PaymentService) CreatePaymentIntent(ctx context.Context, req models.Cre
 	// Handle different gateway types
 	switch req.GatewayType {
 	case models.GatewayRazorpay:
+		// UPI intent, QR and collect go server-to-server; plain UPI uses the checkout modal
+		if isServerSideUPI(req.PaymentMethod, req.VPA) {
+			return s.createGatewayIntent(ctx, payment, gatewayConfig, req)
+		}
 		return s.createRazorpayIntent(ctx, payment, gatewayConfig, req)
+	case models.GatewayPhonePe, models.GatewayCashfree:
+		return s.createGatewayIntent(ctx, payment, gatewayConfig, req)
 	case models.GatewayStripe:
 		return s.createStripeIntent(ctx, payment, gatewayConfig, req)
 	case models.GatewayPayPal:
@@ -662,8 +760,13 @@ func (s *PaymentService) confirmRazorpayPayment(ctx context.Context, payment *mo
 		}()
 	}
 
-	// Notify orders service of payment status update (non-blocking)
-	if payment.Status == models.PaymentSucceeded {
+	// Notify orders service of payment status update (non-blocking).
+	// Link payments settle the order through the link, which may take several payments.
+	if s.paymentLinks.Handles(payment) {
+		if payment.Status == models.PaymentSucceeded {
+			go s.paymentLinks.RecordPayment(context.Background(), payment)
+		}
+	} else if payment.Status == models.PaymentSucceeded {
 		go s.notifyOrderPaymentStatus(payment.OrderID.String(), payment.TenantID, payment.ID.String(), "PAID")
 	} else if payment.Status == models.PaymentFailed {
 		go s.notifyOrderPaymentStatus(payment.OrderID.String(), payment.TenantID, payment.ID.String(), "FAILED")
@@ -730,6 +833,11 @@ func (s *PaymentService) GetPaymentByGatewayID(ctx context.Context, gatewayID st
 		return nil, err
 	}
 
+	return toPaymentStatusResponse(payment), nil
+}
+
+// toPaymentStatusResponse converts a payment transaction to the public status response
+func toPaymentStatusResponse(payment *models.PaymentTransaction) *models.PaymentStatusResponse {
 	response := &models.PaymentStatusResponse{
 		ID:                   payment.ID.String(),
 		OrderID:              payment.OrderID.String(),
@@ -744,6 +852,12 @@ func (s *PaymentService) GetPaymentByGatewayID(ctx context.Context, gatewayID st
 		BillingName:          payment.BillingName,
 		FailureCode:          payment.FailureCode,
 		FailureMessage:       payment.FailureMessage,
+		GatewayFee:           payment.GatewayFee,
+		FXFee:                payment.FXFee,
+		SettlementCurrency:   payment.SettlementCurrency,
+		ExchangeRate:         payment.ExchangeRate,
+		SettlementAmount:     payment.SettlementAmount,
+		SettlementNetAmount:  payment.SettlementNetAmount,
 		CreatedAt:            payment.CreatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 
@@ -752,7 +866,7 @@ func (s *PaymentService) GetPaymentByGatewayID(ctx context.Context, gatewayID st
 		response.ProcessedAt = &processedAt
 	}
 
-	return response, nil
+	return response
 }
 
 // CreateRefund creates a refund for a payment
@@ -768,10 +882,15 @@ func (s *PaymentService) CreateRefund(ctx context.Context, paymentID uuid.UUID,
 		return nil, errors.New("payment must be in succeeded status to refund")
 	}
 
-	// Get gateway config
+	// Get gateway config; without one the original tender is skipped
 	gatewayConfig, err := s.repo.GetGatewayConfig(ctx, payment.GatewayConfigID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gateway config: %w", err)
+		gatewayConfig = nil
+	}
+
+	chain, err := s.refundChain(ctx, payment.TenantID, req.Tenders)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create refund record
@@ -784,64 +903,64 @@ func (s *PaymentService) CreateRefund(ctx context.Context, paymentID uuid.UUID,
 		Reason:               req.Reason,
 		Notes:                req.Notes,
 	}
+	if req.ReturnID != "" {
+		refund.Metadata = models.JSONB{"return_id": req.ReturnID}
+	}
 
 	if err := s.repo.CreateRefundTransaction(ctx, refund); err != nil {
 		return nil, fmt.Errorf("failed to create refund transaction: %w", err)
 	}
 
-	// Process refund based on gateway type
-	switch payment.GatewayType {
-	case models.GatewayRazorpay:
-		return s.processRazorpayRefund(ctx, payment, refund, gatewayConfig, req)
-	default:
-		return nil, fmt.Errorf("unsupported gateway type: %s", payment.GatewayType)
+	// Walk the fallback chain until a tender accepts the refund
+	if err := s.runRefundChain(ctx, payment, refund, gatewayConfig, chain); err != nil {
+		now := time.Now()
+		refund.Status = models.RefundFailed
+		refund.FailedAt = &now
+		s.repo.UpdateRefundTransaction(ctx, refund)
+		syncVendorPayoutForRefund(ctx, s.vendorPayouts, payment, refund)
+		return nil, err
 	}
-}
 
-// processRazorpayRefund processes a Razorpay refund
-func (s *PaymentService) processRazorpayRefund(ctx context.Context, payment *models.PaymentTransaction, refund *models.RefundTransaction, config *models.PaymentGatewayConfig, req models.CreateRefundRequest) (*models.RefundResponse, error) {
-	// Extract vendor ID from payment metadata
-	vendorID := ""
-	if payment.Metadata != nil {
-		if vid, ok := payment.Metadata["vendor_id"].(string); ok {
-			vendorID = vid
-		}
+	if refund.Status == models.RefundSucceeded {
+		now := time.Now()
+		refund.ProcessedAt = &now
+	}
+	if err := s.repo.UpdateRefundTransaction(ctx, refund); err != nil {
+		return nil, fmt.Errorf("failed to update refund transaction: %w", err)
 	}
+	syncVendorPayoutForRefund(ctx, s.vendorPayouts, payment, refund)
 
-	// Apply dynamic credentials for this tenant/vendor
-	if err := s.applyDynamicCredentials(ctx, config, payment.TenantID, vendorID); err != nil {
-		return nil, fmt.Errorf("failed to load Razorpay credentials for refund: %w", err)
+	// Manual bank transfers settle once an operator records them
+	if refund.Tender != models.TenderBankTransfer {
+		s.settleRefund(ctx, payment, refund)
 	}
 
+	return toRefundResponse(refund), nil
+}
+
+// refundToRazorpay refunds a Razorpay payment to the original tender
+func (s *PaymentService) refundToRazorpay(payment *models.PaymentTransaction, refund *models.RefundTransaction, config *models.PaymentGatewayConfig) error {
 	client := razorpay.NewClient(config.APIKeyPublic, config.APIKeySecret, config.IsTestMode)
 
 	refundReq := razorpay.RefundRequest{
-		Amount: razorpay.AmountToRazorpayPaise(req.Amount),
+		Amount: razorpay.AmountToRazorpayPaise(refund.Amount),
 		Speed:  "normal",
 	}
 
 	razorpayRefund, err := client.CreateRefund(payment.GatewayTransactionID, refundReq)
 	if err != nil {
-		refund.Status = models.RefundFailed
-		refund.FailureMessage = err.Error()
-		s.repo.UpdateRefundTransaction(ctx, refund)
-		return nil, fmt.Errorf("failed to create Razorpay refund: %w", err)
+		return fmt.Errorf("failed to create Razorpay refund: %w", err)
 	}
 
-	// Update refund record
 	refund.GatewayRefundID = razorpayRefund.ID
 	refund.Status = razorpay.ConvertToRefundStatus(razorpayRefund.Status)
-	if refund.Status == models.RefundSucceeded {
-		now := time.Now()
-		refund.ProcessedAt = &now
-	}
-
-	if err := s.repo.UpdateRefundTransaction(ctx, refund); err != nil {
-		return nil, fmt.Errorf("failed to update refund transaction: %w", err)
-	}
+	return nil
+}
 
+// settleRefund marks a fully refunded payment and notifies the customer
+func (s *PaymentService) settleRefund(ctx context.Context, payment *models.PaymentTransaction, refund *models.RefundTransaction) {
 	// Update payment status if fully refunded
-	if req.Amount >= payment.Amount {
+	if refund.Amount >= payment.Amount {
 		payment.Status = models.PaymentRefunded
 		s.repo.UpdatePaymentTransaction(ctx, payment)
 	}
@@ -855,18 +974,21 @@ func (s *PaymentService) processRazorpayRefund(ctx context.Context, payment *mod
 			_ = s.notificationClient.SendPaymentRefundedNotification(context.Background(), notification)
 		}()
 	}
+}
 
-	response := &models.RefundResponse{
+// toRefundResponse builds the API response for a refund
+func toRefundResponse(refund *models.RefundTransaction) *models.RefundResponse {
+	return &models.RefundResponse{
 		RefundID:        refund.ID.String(),
-		PaymentID:       payment.ID.String(),
+		PaymentID:       refund.PaymentTransactionID.String(),
 		Amount:          refund.Amount,
 		Currency:        refund.Currency,
 		Status:          refund.Status,
+		Tender:          refund.Tender,
 		GatewayRefundID: refund.GatewayRefundID,
+		Attempts:        refund.Attempts,
 		CreatedAt:       refund.CreatedAt.Format(time.RFC3339),
 	}
-
-	return response, nil
 }
 
 // CancelPayment cancels a pending payment
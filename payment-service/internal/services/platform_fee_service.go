@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -64,13 +65,19 @@ func (s *PlatformFeeService) CalculateFees(ctx context.Context, tenantID string,
 	// Calculate net amount (amount merchant receives)
 	netAmount := amount - platformFee
 
+	settlementCurrency := settings.SettlementCurrency
+	if settlementCurrency == "" {
+		settlementCurrency = settings.DefaultCurrency
+	}
+
 	return &models.FeeCalculation{
-		GrossAmount:     amount,
-		PlatformFee:     platformFee,
-		PlatformPercent: platformPercent,
-		GatewayFee:      0, // Calculated by gateway after processing
-		TaxAmount:       0, // Calculated separately
-		NetAmount:       netAmount,
+		GrossAmount:        amount,
+		PlatformFee:        platformFee,
+		PlatformPercent:    platformPercent,
+		GatewayFee:         0, // Calculated by gateway after processing
+		TaxAmount:          0, // Calculated separately
+		NetAmount:          netAmount,
+		SettlementCurrency: strings.ToUpper(settlementCurrency),
 	}, nil
 }
 
@@ -84,11 +91,35 @@ func (s *PlatformFeeService) CalculateFeesWithGatewayFee(ctx context.Context, te
 	// Estimate gateway fee based on gateway type
 	gatewayFee := s.estimateGatewayFee(amount, currency, gatewayType)
 	calc.GatewayFee = gatewayFee
-	calc.NetAmount = amount - calc.PlatformFee - gatewayFee
+
+	// Cross-border: the gateway converts to the settlement currency for a fee
+	if calc.SettlementCurrency != "" && !strings.EqualFold(calc.SettlementCurrency, currency) {
+		calc.FXFee = s.estimateFXFee(amount, gatewayType)
+	}
+	calc.NetAmount = amount - calc.PlatformFee - gatewayFee - calc.FXFee
 
 	return calc, nil
 }
 
+// estimateFXFee estimates the currency conversion fee for display purposes
+func (s *PlatformFeeService) estimateFXFee(amount float64, gatewayType models.GatewayType) float64 {
+	// Approximate conversion markups; actual FX fees come from settlement data
+	fxRates := map[models.GatewayType]float64{
+		models.GatewayStripe:   0.01,  // 1% (2% for some regions)
+		models.GatewayPayPal:   0.03,  // 3-4% spread
+		models.GatewayRazorpay: 0.01,  // International cards
+		models.GatewayAfterpay: 0,     // Single-currency
+		models.GatewayZip:      0,     // Single-currency
+	}
+
+	rate, ok := fxRates[gatewayType]
+	if !ok {
+		rate = fxRates[models.GatewayStripe]
+	}
+
+	return math.Round(amount*rate*100) / 100
+}
+
 // estimateGatewayFee estimates the gateway fee for display purposes
 func (s *PlatformFeeService) estimateGatewayFee(amount float64, currency string, gatewayType models.GatewayType) float64 {
 	// These are approximate fees for estimation only
@@ -126,6 +157,7 @@ func (s *PlatformFeeService) RecordFeeCollection(ctx context.Context, payment *m
 		ID:                   uuid.New(),
 		TenantID:             payment.TenantID,
 		PaymentTransactionID: &payment.ID,
+		VendorID:             metadataString(payment.Metadata, "vendor_id"),
 		EntryType:            models.LedgerEntryCollection,
 		Amount:               payment.PlatformFee,
 		Currency:             payment.Currency,
@@ -135,6 +167,13 @@ func (s *PlatformFeeService) RecordFeeCollection(ctx context.Context, payment *m
 		UpdatedAt:            time.Now(),
 	}
 
+	// The fee is collected from the settled funds, so record it in the settlement currency too
+	if payment.SettlementCurrency != "" && payment.ExchangeRate > 0 {
+		entry.SettlementCurrency = payment.SettlementCurrency
+		entry.ExchangeRate = payment.ExchangeRate
+		entry.SettlementAmount = math.Round(payment.PlatformFee*payment.ExchangeRate*100) / 100
+	}
+
 	return s.db.WithContext(ctx).Create(entry).Error
 }
 
@@ -156,6 +195,12 @@ func (s *PlatformFeeService) RecordFeeRefund(ctx context.Context, refund *models
 		UpdatedAt:           time.Now(),
 	}
 
+	// Refunded commission is credited on the vendor's commission invoice, like the fee it reverses
+	var payment models.PaymentTransaction
+	if err := s.db.WithContext(ctx).Select("metadata").First(&payment, "id = ?", refund.PaymentTransactionID).Error; err == nil {
+		entry.VendorID = metadataString(payment.Metadata, "vendor_id")
+	}
+
 	return s.db.WithContext(ctx).Create(entry).Error
 }
 
@@ -340,17 +385,25 @@ func (s *PlatformFeeService) UpdatePaymentFees(ctx context.Context, paymentID uu
 		return fmt.Errorf("failed to get payment: %w", err)
 	}
 
-	netAmount := payment.Amount - payment.PlatformFee - gatewayFee - gatewayTax
+	netAmount := payment.Amount - payment.PlatformFee - gatewayFee - gatewayTax - payment.FXFee
+	updates := map[string]interface{}{
+		"gateway_fee":  gatewayFee,
+		"gateway_tax":  gatewayTax,
+		"net_amount":   netAmount,
+		"updated_at":   now,
+	}
+
+	// Keep the settlement-currency payout in step for cross-border payments
+	if payment.SettlementCurrency != "" && payment.ExchangeRate > 0 {
+		rate := payment.ExchangeRate
+		settlementFees := (payment.PlatformFee + gatewayFee + gatewayTax + payment.FXFee) * rate
+		updates["settlement_net_amount"] = math.Round((payment.SettlementAmount-settlementFees)*100) / 100
+	}
 
 	return s.db.WithContext(ctx).
 		Model(&models.PaymentTransaction{}).
 		Where("id = ?", paymentID).
-		Updates(map[string]interface{}{
-			"gateway_fee":  gatewayFee,
-			"gateway_tax":  gatewayTax,
-			"net_amount":   netAmount,
-			"updated_at":   now,
-		}).Error
+		Updates(updates).Error
 }
 
 // FeeLedgerFilters contains filters for fee ledger queries
@@ -389,6 +442,10 @@ func (s *PlatformFeeService) GetPaymentSettings(ctx context.Context, tenantID st
 
 // UpdatePaymentSettings updates or creates payment settings for a tenant
 func (s *PlatformFeeService) UpdatePaymentSettings(ctx context.Context, settings *models.PaymentSettings) error {
+	if err := ValidateRefundFallbackChain(settings.RefundFallbackChain); err != nil {
+		return err
+	}
+
 	// Try to get existing settings
 	existing, err := s.repo.GetPaymentSettings(ctx, settings.TenantID)
 	if err != nil {
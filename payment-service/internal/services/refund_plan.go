@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/models"
+)
+
+// ErrInvalidRefundPlan is returned when a refund plan names a tender the order wasn't paid with
+var ErrInvalidRefundPlan = errors.New("invalid refund plan")
+
+// ExecuteRefundPlan refunds an order across the tenders it was paid with: PAYMENT lines
+// refund one of the order's payments through the refund fallback chain, GIFT_CARD lines
+// put money back on the gift card. Every line is checked before any is executed. Lines are
+// then executed in order and independently; a failed line is reported in its result
+// rather than failing the plan, since lines already paid out can't be taken back.
+func (s *PaymentService) ExecuteRefundPlan(ctx context.Context, tenantID string, req models.RefundPlanRequest) (*models.RefundPlanResponse, error) {
+	orderID, err := uuid.Parse(req.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid order ID", ErrInvalidRefundPlan)
+	}
+
+	paymentIDs := make([]uuid.UUID, len(req.Lines))
+	for i, line := range req.Lines {
+		switch line.Tender {
+		case models.RefundPlanPayment:
+			paymentID, err := uuid.Parse(line.PaymentID)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d has an invalid payment ID", ErrInvalidRefundPlan, i+1)
+			}
+			payment, err := s.repo.GetPaymentTransaction(ctx, paymentID)
+			if err != nil || payment.TenantID != tenantID || payment.OrderID != orderID {
+				return nil, fmt.Errorf("%w: line %d names a payment that isn't the order's", ErrInvalidRefundPlan, i+1)
+			}
+			for _, t := range line.Tenders {
+				if !t.IsValid() {
+					return nil, fmt.Errorf("%w: %s", ErrInvalidRefundTender, t)
+				}
+			}
+			paymentIDs[i] = paymentID
+		case models.RefundPlanGiftCard:
+			if line.GiftCardCode == "" {
+				return nil, fmt.Errorf("%w: line %d has no gift card code", ErrInvalidRefundPlan, i+1)
+			}
+			if s.giftCardClient == nil {
+				return nil, fmt.Errorf("%w: gift card refunds are not configured", ErrInvalidRefundPlan)
+			}
+		default:
+			return nil, fmt.Errorf("%w: line %d has unknown tender %s", ErrInvalidRefundPlan, i+1, line.Tender)
+		}
+	}
+
+	response := &models.RefundPlanResponse{
+		OrderID: req.OrderID,
+		Lines:   make([]models.RefundPlanLineResult, len(req.Lines)),
+	}
+	for i, line := range req.Lines {
+		result := models.RefundPlanLineResult{Tender: line.Tender, Amount: line.Amount}
+
+		switch line.Tender {
+		case models.RefundPlanPayment:
+			refund, err := s.CreateRefund(ctx, paymentIDs[i], models.CreateRefundRequest{
+				Amount:   line.Amount,
+				Reason:   req.Reason,
+				Notes:    req.Notes,
+				Tenders:  line.Tenders,
+				ReturnID: req.ReturnID,
+			})
+			if err != nil {
+				result.Status = models.RefundFailed
+				result.Error = err.Error()
+				break
+			}
+			result.Status = refund.Status
+			result.RefundID = refund.RefundID
+			result.Reference = refund.GatewayRefundID
+		case models.RefundPlanGiftCard:
+			giftCardID, err := s.giftCardClient.RefundToGiftCard(ctx, tenantID, line.GiftCardCode, line.Amount, req.OrderID)
+			if err != nil {
+				result.Status = models.RefundFailed
+				result.Error = err.Error()
+				break
+			}
+			result.Status = models.RefundSucceeded
+			result.Reference = giftCardID
+		}
+
+		if result.Status == models.RefundFailed {
+			fmt.Printf("[PaymentService] Refund plan for order %s: line %d (%s %.2f) failed: %s\n", req.OrderID, i+1, line.Tender, line.Amount, result.Error)
+		}
+		response.Lines[i] = result
+	}
+	return response, nil
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/clients"
+	"payment-service/internal/gateway"
+	"payment-service/internal/models"
+)
+
+var (
+	// ErrRefundNotFound is returned when a refund doesn't exist for the tenant
+	ErrRefundNotFound = errors.New("refund not found")
+	// ErrInvalidRefundTender is returned for an unknown tender in a fallback chain
+	ErrInvalidRefundTender = errors.New("invalid refund tender")
+	// ErrNoRefundTender is returned when every tender in the fallback chain failed or was skipped
+	ErrNoRefundTender = errors.New("no refund tender in the fallback chain could be used")
+	// ErrNoPendingBankTransfer is returned when a refund isn't waiting on a manual bank transfer
+	ErrNoPendingBankTransfer = errors.New("refund has no pending bank transfer")
+)
+
+// errTenderSkipped marks a tender that can't be used for this payment, as opposed to one that failed
+type errTenderSkipped struct {
+	reason string
+}
+
+func (e *errTenderSkipped) Error() string {
+	return e.reason
+}
+
+// ValidateRefundFallbackChain checks that every tender in a chain is known
+func ValidateRefundFallbackChain(chain []string) error {
+	for _, t := range chain {
+		if !models.RefundTender(t).IsValid() {
+			return fmt.Errorf("%w: %s", ErrInvalidRefundTender, t)
+		}
+	}
+	return nil
+}
+
+// refundChain returns the requested chain, or the tenant's configured one
+func (s *PaymentService) refundChain(ctx context.Context, tenantID string, requested []models.RefundTender) ([]models.RefundTender, error) {
+	if len(requested) > 0 {
+		for _, t := range requested {
+			if !t.IsValid() {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidRefundTender, t)
+			}
+		}
+		return requested, nil
+	}
+
+	settings, err := s.repo.GetPaymentSettings(ctx, tenantID)
+	if err != nil {
+		settings = nil
+	}
+	return settings.RefundChain(), nil
+}
+
+// runRefundChain tries each tender in order and records an attempt for each. It stops at the
+// first tender that succeeded or is pending, and sets refund.Tender to it.
+func (s *PaymentService) runRefundChain(ctx context.Context, payment *models.PaymentTransaction, refund *models.RefundTransaction, config *models.PaymentGatewayConfig, chain []models.RefundTender) error {
+	for i, tender := range chain {
+		attempt := &models.RefundTenderAttempt{
+			TenantID:            refund.TenantID,
+			RefundTransactionID: refund.ID,
+			Sequence:            i + 1,
+			Tender:              tender,
+			Status:              models.RefundAttemptPending,
+		}
+		refund.Status = models.RefundPending
+
+		var err error
+		switch tender {
+		case models.TenderOriginal:
+			err = s.refundToOriginalTender(ctx, payment, refund, config, attempt)
+		case models.TenderStoreCredit:
+			err = s.refundToStoreCredit(ctx, payment, refund, attempt)
+		case models.TenderBankTransfer:
+			// Stays pending until an operator records the transfer
+		}
+
+		var skipped *errTenderSkipped
+		switch {
+		case errors.As(err, &skipped):
+			attempt.Status = models.RefundAttemptSkipped
+			attempt.FailureMessage = err.Error()
+		case err != nil:
+			attempt.Status = models.RefundAttemptFailed
+			attempt.FailureMessage = err.Error()
+		}
+
+		if createErr := s.repo.CreateRefundTenderAttempt(ctx, attempt); createErr != nil {
+			return fmt.Errorf("failed to record refund attempt: %w", createErr)
+		}
+		refund.Attempts = append(refund.Attempts, *attempt)
+
+		if err != nil {
+			fmt.Printf("[PaymentService] Refund %s: %s tender %s: %v\n", refund.ID, tender, attempt.Status, err)
+			refund.FailureMessage = err.Error()
+			continue
+		}
+
+		refund.Tender = tender
+		refund.FailureMessage = ""
+		return nil
+	}
+
+	return ErrNoRefundTender
+}
+
+// refundToOriginalTender refunds through the gateway that took the payment
+func (s *PaymentService) refundToOriginalTender(ctx context.Context, payment *models.PaymentTransaction, refund *models.RefundTransaction, config *models.PaymentGatewayConfig, attempt *models.RefundTenderAttempt) error {
+	if config == nil || payment.GatewayTransactionID == "" {
+		return &errTenderSkipped{reason: "payment has no gateway transaction to refund"}
+	}
+
+	// Apply dynamic credentials for this tenant/vendor
+	if err := s.applyDynamicCredentials(ctx, config, payment.TenantID, metadataString(payment.Metadata, "vendor_id")); err != nil {
+		return fmt.Errorf("failed to load %s credentials for refund: %w", payment.GatewayType, err)
+	}
+
+	if payment.GatewayType == models.GatewayRazorpay {
+		if err := s.refundToRazorpay(payment, refund, config); err != nil {
+			return err
+		}
+		if refund.Status == models.RefundFailed {
+			return fmt.Errorf("Razorpay refund %s failed", refund.GatewayRefundID)
+		}
+	} else {
+		gw, err := newRefundGateway(config)
+		if err != nil {
+			return &errTenderSkipped{reason: err.Error()}
+		}
+		result, err := gw.CreateRefund(ctx, &gateway.RefundRequest{
+			GatewayPaymentID: payment.GatewayTransactionID,
+			Amount:           refund.Amount,
+			Currency:         refund.Currency,
+			Reason:           refund.Reason,
+			IdempotencyKey:   refund.ID.String(),
+			Metadata: map[string]string{
+				"refund_id": refund.ID.String(),
+				"tenant_id": refund.TenantID,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create %s refund: %w", payment.GatewayType, err)
+		}
+		if result.Status == models.RefundFailed {
+			return fmt.Errorf("%s refund failed: %s", payment.GatewayType, result.FailureMessage)
+		}
+		refund.GatewayRefundID = result.GatewayRefundID
+		refund.Status = result.Status
+	}
+
+	attempt.Reference = refund.GatewayRefundID
+	if refund.Status == models.RefundSucceeded {
+		attempt.Status = models.RefundAttemptSucceeded
+		now := time.Now()
+		attempt.ProcessedAt = &now
+	}
+	return nil
+}
+
+// refundToStoreCredit issues the refund amount as a gift card emailed to the customer
+func (s *PaymentService) refundToStoreCredit(ctx context.Context, payment *models.PaymentTransaction, refund *models.RefundTransaction, attempt *models.RefundTenderAttempt) error {
+	if s.giftCardClient == nil {
+		return &errTenderSkipped{reason: "store credit is not configured"}
+	}
+	if payment.BillingEmail == "" {
+		return &errTenderSkipped{reason: "payment has no customer email to send store credit to"}
+	}
+
+	email := payment.BillingEmail
+	sender := "Store credit"
+	message := fmt.Sprintf("Store credit for your refund on order %s", payment.OrderID)
+	credit := &clients.StoreCreditRequest{
+		InitialBalance: refund.Amount,
+		CurrencyCode:   refund.Currency,
+		RecipientEmail: &email,
+		SenderName:     &sender,
+		Message:        &message,
+		Metadata: map[string]interface{}{
+			"source":     "refund",
+			"refund_id":  refund.ID.String(),
+			"payment_id": payment.ID.String(),
+			"order_id":   payment.OrderID.String(),
+		},
+	}
+	if payment.BillingName != "" {
+		name := payment.BillingName
+		credit.RecipientName = &name
+	}
+
+	giftCard, err := s.giftCardClient.IssueStoreCredit(ctx, payment.TenantID, credit)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	attempt.Status = models.RefundAttemptSucceeded
+	attempt.Reference = giftCard.ID
+	attempt.ProcessedAt = &now
+	attempt.Metadata = models.JSONB{"gift_card_code": giftCard.Code}
+	refund.Status = models.RefundSucceeded
+	return nil
+}
+
+// GetRefund returns a refund with its fallback chain attempts
+func (s *PaymentService) GetRefund(ctx context.Context, tenantID string, refundID uuid.UUID) (*models.RefundTransaction, error) {
+	refund, err := s.repo.GetRefundTransaction(ctx, refundID)
+	if err != nil || refund.TenantID != tenantID {
+		return nil, ErrRefundNotFound
+	}
+	return refund, nil
+}
+
+// RecordBankTransfer records the outcome of a manual bank transfer refund and settles the
+// refund when the transfer went through
+func (s *PaymentService) RecordBankTransfer(ctx context.Context, tenantID string, refundID uuid.UUID, req models.RecordBankTransferRequest, operator string) (*models.RefundTransaction, error) {
+	refund, err := s.GetRefund(ctx, tenantID, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	var attempt *models.RefundTenderAttempt
+	for i := range refund.Attempts {
+		a := &refund.Attempts[i]
+		if a.Tender == models.TenderBankTransfer && a.Status == models.RefundAttemptPending {
+			attempt = a
+			break
+		}
+	}
+	if attempt == nil {
+		return nil, ErrNoPendingBankTransfer
+	}
+
+	now := time.Now()
+	attempt.Status = req.Status
+	attempt.Reference = req.Reference
+	attempt.OperatorNotes = req.OperatorNotes
+	attempt.ProcessedBy = operator
+	attempt.ProcessedAt = &now
+	if err := s.repo.UpdateRefundTenderAttempt(ctx, attempt); err != nil {
+		return nil, fmt.Errorf("failed to update refund attempt: %w", err)
+	}
+
+	if req.Status == models.RefundAttemptSucceeded {
+		refund.Status = models.RefundSucceeded
+		refund.GatewayRefundID = req.Reference
+		refund.ProcessedAt = &now
+	} else {
+		refund.Status = models.RefundFailed
+		refund.FailedAt = &now
+		refund.FailureMessage = "bank transfer failed"
+		if req.OperatorNotes != "" {
+			refund.FailureMessage += ": " + req.OperatorNotes
+		}
+	}
+	if err := s.repo.UpdateRefundTransaction(ctx, refund); err != nil {
+		return nil, fmt.Errorf("failed to update refund transaction: %w", err)
+	}
+	syncVendorPayoutForRefund(ctx, s.vendorPayouts, refund.PaymentTransaction, refund)
+
+	if refund.Status == models.RefundSucceeded && refund.PaymentTransaction != nil {
+		s.settleRefund(ctx, refund.PaymentTransaction, refund)
+	}
+	return refund, nil
+}
+
+// newRefundGateway creates the gateway adapter used to refund non-Razorpay payments
+func newRefundGateway(config *models.PaymentGatewayConfig) (gateway.PaymentGateway, error) {
+	if config.GatewayType == models.GatewayStripe {
+		stripeGateway, err := gateway.NewStripeGateway(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Stripe gateway: %w", err)
+		}
+		return stripeGateway, nil
+	}
+	return newUPIGateway(config)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"payment-service/internal/gateway"
+	"payment-service/internal/models"
+)
+
+// ErrPaymentNotSettleable is returned when settlement is recorded for a payment that never succeeded
+var ErrPaymentNotSettleable = errors.New("payment has not been captured")
+
+// applySettlement records how the gateway settled a payment and recomputes its fees and payout.
+// Gateway fee, tax and FX fee are converted back to the payment currency so NetAmount stays
+// comparable with Amount; SettlementNetAmount is the payout in the settlement currency.
+func applySettlement(payment *models.PaymentTransaction, info *gateway.SettlementInfo) {
+	rate := info.ExchangeRate
+	if rate <= 0 {
+		rate = 1
+	}
+	currency := strings.ToUpper(info.Currency)
+	if currency == "" {
+		currency = payment.Currency
+	}
+
+	settlementAmount := info.Amount
+	if settlementAmount <= 0 {
+		settlementAmount = roundAmount(payment.Amount * rate)
+	}
+
+	now := time.Now()
+	payment.SettlementCurrency = currency
+	payment.ExchangeRate = rate
+	payment.SettlementAmount = settlementAmount
+	payment.FXFee = roundAmount(info.FXFee / rate)
+	payment.GatewayFee = roundAmount((info.Fee - info.FXFee) / rate)
+	payment.GatewayTax = roundAmount(info.Tax / rate)
+	payment.NetAmount = roundAmount(payment.Amount - payment.PlatformFee - payment.GatewayFee - payment.GatewayTax - payment.FXFee)
+	payment.SettlementNetAmount = roundAmount(settlementAmount - info.Fee - info.Tax - payment.PlatformFee*rate)
+	payment.SettlementRecordedAt = &now
+}
+
+// RecordSettlement records a payment's settlement currency, rate and fees from a gateway
+// settlement report, overriding what the webhooks reported
+func (s *PaymentService) RecordSettlement(ctx context.Context, paymentID uuid.UUID, req models.RecordSettlementRequest) (*models.PaymentTransaction, error) {
+	payment, err := s.repo.GetPaymentTransaction(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment transaction: %w", err)
+	}
+	if payment.Status != models.PaymentSucceeded && payment.Status != models.PaymentRefunded {
+		return nil, ErrPaymentNotSettleable
+	}
+	if req.FXFee > req.GatewayFee {
+		return nil, fmt.Errorf("fxFee cannot exceed the total gatewayFee")
+	}
+
+	applySettlement(payment, &gateway.SettlementInfo{
+		Currency:     req.SettlementCurrency,
+		Amount:       req.SettlementAmount,
+		ExchangeRate: req.ExchangeRate,
+		Fee:          req.GatewayFee,
+		FXFee:        req.FXFee,
+		Tax:          req.GatewayTax,
+	})
+
+	if err := s.repo.UpdatePaymentTransaction(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to update payment transaction: %w", err)
+	}
+	return payment, nil
+}
@@ -0,0 +1,435 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"payment-service/internal/gateway"
+	"payment-service/internal/models"
+)
+
+// ErrTerminalReaderNotFound is returned when the reader is not registered to the tenant
+var ErrTerminalReaderNotFound = errors.New("terminal reader not found")
+
+// ErrTerminalNotSupported is returned for gateways without in-person payment support
+var ErrTerminalNotSupported = errors.New("gateway does not support in-person payments")
+
+// ErrTerminalReaderExists is returned when the reader is already registered
+var ErrTerminalReaderExists = errors.New("terminal reader is already registered")
+
+// ErrTerminalPaymentNotFound is returned when the payment is not an in-person payment of the tenant
+var ErrTerminalPaymentNotFound = errors.New("terminal payment not found")
+
+// ErrTerminalPaymentState is returned when the payment can't be captured or canceled in its current state
+var ErrTerminalPaymentState = errors.New("terminal payment is not pending")
+
+// TerminalService handles in-person payments on card readers and POS devices. In-person
+// payments are stored as regular payment transactions with the IN_PERSON channel so they
+// reconcile, refund and settle orders exactly like online ones.
+type TerminalService struct {
+	db             *gorm.DB
+	paymentService *PaymentService
+}
+
+// NewTerminalService creates a new terminal service
+func NewTerminalService(db *gorm.DB, paymentService *PaymentService) *TerminalService {
+	return &TerminalService{
+		db:             db,
+		paymentService: paymentService,
+	}
+}
+
+// CreateConnectionToken issues a Stripe Terminal connection token for the POS app. When a
+// location is given, the token is scoped to the Stripe location of its readers.
+func (s *TerminalService) CreateConnectionToken(ctx context.Context, tenantID string, req *models.CreateConnectionTokenRequest) (*models.ConnectionTokenResponse, error) {
+	gatewayLocationID := ""
+	if req.LocationID != "" {
+		var reader models.TerminalReader
+		err := s.db.WithContext(ctx).
+			Where("tenant_id = ? AND location_id = ? AND gateway_type = ? AND gateway_location_id <> ''", tenantID, req.LocationID, models.GatewayStripe).
+			First(&reader).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to look up location readers: %w", err)
+		}
+		gatewayLocationID = reader.GatewayLocationID
+	}
+
+	terminal, err := s.terminalGateway(ctx, tenantID, models.GatewayStripe)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := terminal.CreateConnectionToken(ctx, gatewayLocationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection token: %w", err)
+	}
+
+	return &models.ConnectionTokenResponse{
+		Secret:            secret,
+		GatewayLocationID: gatewayLocationID,
+	}, nil
+}
+
+// RegisterReader registers a reader with the gateway and assigns it to a store location
+func (s *TerminalService) RegisterReader(ctx context.Context, tenantID, createdBy string, req *models.RegisterTerminalReaderRequest) (*models.TerminalReader, error) {
+	if tenantID == "" {
+		return nil, ErrInvalidTenantID
+	}
+
+	terminal, err := s.terminalGateway(ctx, tenantID, req.GatewayType)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := terminal.RegisterReader(ctx, &gateway.RegisterReaderRequest{
+		TenantID:          tenantID,
+		Label:             req.Label,
+		RegistrationCode:  req.RegistrationCode,
+		GatewayLocationID: req.GatewayLocationID,
+		DeviceID:          req.DeviceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register reader: %w", err)
+	}
+
+	var existing int64
+	if err := s.db.WithContext(ctx).Model(&models.TerminalReader{}).
+		Where("gateway_type = ? AND gateway_reader_id = ?", req.GatewayType, result.GatewayReaderID).
+		Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check reader: %w", err)
+	}
+	if existing > 0 {
+		return nil, ErrTerminalReaderExists
+	}
+
+	now := time.Now()
+	status := models.TerminalReaderUnknown
+	var lastSeenAt *time.Time
+	if result.Online {
+		status = models.TerminalReaderOnline
+		lastSeenAt = &now
+	}
+
+	serialNumber := result.SerialNumber
+	if serialNumber == "" {
+		serialNumber = req.SerialNumber
+	}
+
+	reader := &models.TerminalReader{
+		TenantID:          tenantID,
+		LocationID:        req.LocationID,
+		GatewayType:       req.GatewayType,
+		GatewayReaderID:   result.GatewayReaderID,
+		GatewayLocationID: result.GatewayLocationID,
+		Label:             req.Label,
+		DeviceType:        result.DeviceType,
+		SerialNumber:      serialNumber,
+		Status:            status,
+		LastSeenAt:        lastSeenAt,
+		CreatedBy:         createdBy,
+	}
+	if err := s.db.WithContext(ctx).Create(reader).Error; err != nil {
+		return nil, fmt.Errorf("failed to save reader: %w", err)
+	}
+	return reader, nil
+}
+
+// ListReaders lists a tenant's readers, optionally filtered by store location
+func (s *TerminalService) ListReaders(ctx context.Context, tenantID, locationID string) ([]models.TerminalReader, error) {
+	query := s.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
+	if locationID != "" {
+		query = query.Where("location_id = ?", locationID)
+	}
+
+	var readers []models.TerminalReader
+	if err := query.Order("location_id, label").Find(&readers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list readers: %w", err)
+	}
+	return readers, nil
+}
+
+// DeleteReader removes a reader from the tenant. Past payments keep their reader ID.
+func (s *TerminalService) DeleteReader(ctx context.Context, tenantID string, readerID uuid.UUID) error {
+	result := s.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, readerID).Delete(&models.TerminalReader{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete reader: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrTerminalReaderNotFound
+	}
+	return nil
+}
+
+// CreatePayment records an in-person payment and hands it to the reader for collection
+func (s *TerminalService) CreatePayment(ctx context.Context, tenantID string, req *models.CreateTerminalPaymentRequest) (*models.TerminalPaymentResponse, error) {
+	reader, err := s.getReader(ctx, tenantID, req.ReaderID)
+	if err != nil {
+		return nil, err
+	}
+
+	gatewayConfig, err := s.paymentService.loadGatewayConfig(ctx, tenantID, "", reader.GatewayType, req.Metadata["vendor_id"])
+	if err != nil {
+		return nil, err
+	}
+	terminal, err := newTerminalGateway(gatewayConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(models.JSONB)
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+
+	payment := &models.PaymentTransaction{
+		TenantID:          tenantID,
+		OrderID:           req.OrderID,
+		CustomerID:        req.CustomerID,
+		GatewayConfigID:   gatewayConfig.ID,
+		GatewayType:       reader.GatewayType,
+		Amount:            roundAmount(req.Amount),
+		Currency:          strings.ToUpper(req.Currency),
+		Status:            models.PaymentPending,
+		PaymentMethodType: models.MethodCard,
+		BillingEmail:      req.CustomerEmail,
+		BillingName:       req.CustomerName,
+		Channel:           models.ChannelInPerson,
+		TerminalReaderID:  &reader.ID,
+		LocationID:        reader.LocationID,
+		Metadata:          metadata,
+	}
+	if err := s.paymentService.repo.CreatePaymentTransaction(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to create payment transaction: %w", err)
+	}
+
+	result, err := terminal.CreateTerminalPayment(ctx, &gateway.TerminalPaymentRequest{
+		TenantID:        tenantID,
+		OrderID:         req.OrderID.String(),
+		PaymentID:       payment.ID.String(),
+		GatewayReaderID: reader.GatewayReaderID,
+		Amount:          payment.Amount,
+		Currency:        payment.Currency,
+		Description:     req.Description,
+		ReceiptEmail:    req.CustomerEmail,
+		Metadata:        req.Metadata,
+	})
+	if err != nil {
+		now := time.Now()
+		payment.Status = models.PaymentFailed
+		payment.FailedAt = &now
+		payment.FailureMessage = err.Error()
+		s.paymentService.repo.UpdatePaymentTransaction(ctx, payment)
+		return nil, fmt.Errorf("failed to start payment on reader: %w", err)
+	}
+
+	payment.GatewayTransactionID = result.GatewayOrderID
+	if err := s.paymentService.repo.UpdatePaymentTransaction(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to update payment transaction: %w", err)
+	}
+
+	s.touchReader(ctx, reader)
+	return &models.TerminalPaymentResponse{
+		PaymentID:        payment.ID,
+		ReaderID:         reader.ID,
+		GatewayType:      reader.GatewayType,
+		GatewayPaymentID: result.GatewayOrderID,
+		ClientSecret:     result.ClientSecret,
+		Amount:           payment.Amount,
+		Currency:         payment.Currency,
+		Status:           payment.Status,
+	}, nil
+}
+
+// CapturePayment captures an in-person payment authorized on the reader and marks the order paid
+func (s *TerminalService) CapturePayment(ctx context.Context, tenantID string, paymentID uuid.UUID, req *models.CaptureTerminalPaymentRequest) (*models.PaymentTransaction, error) {
+	payment, err := s.getPendingPayment(ctx, tenantID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	terminal, err := s.terminalGateway(ctx, tenantID, payment.GatewayType)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := payment.Amount
+	if req.Amount > 0 {
+		amount = roundAmount(req.Amount)
+	}
+	if amount > payment.Amount {
+		return nil, fmt.Errorf("%w: capture amount exceeds the authorized amount", ErrTerminalPaymentState)
+	}
+
+	result, err := terminal.CaptureTerminalPayment(ctx, &gateway.CapturePaymentRequest{
+		GatewayPaymentID: payment.GatewayTransactionID,
+		Amount:           amount,
+		Currency:         payment.Currency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture payment: %w", err)
+	}
+
+	now := time.Now()
+	payment.Status = models.PaymentSucceeded
+	payment.ProcessedAt = &now
+	payment.Amount = amount
+	if result.GatewayPaymentID != "" {
+		// Razorpay: refunds and webhooks address the captured payment, not the order
+		payment.GatewayTransactionID = result.GatewayPaymentID
+	}
+	payment.GatewayFee = result.GatewayFee
+	payment.GatewayTax = result.GatewayTax
+	payment.NetAmount = amount - result.GatewayFee - result.GatewayTax
+	if result.Settlement != nil {
+		applySettlement(payment, result.Settlement)
+	}
+	if result.CardBrand != "" {
+		payment.CardBrand = result.CardBrand
+		payment.CardLastFour = result.CardLastFour
+		payment.CardExpMonth = result.CardExpMonth
+		payment.CardExpYear = result.CardExpYear
+	}
+	if err := s.paymentService.repo.UpdatePaymentTransaction(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to update payment transaction: %w", err)
+	}
+
+	if s.paymentService.paymentLinks.Handles(payment) {
+		go s.paymentService.paymentLinks.RecordPayment(context.Background(), payment)
+	} else {
+		go s.paymentService.notifyOrderPaymentStatus(payment.OrderID.String(), payment.TenantID, payment.ID.String(), "PAID")
+	}
+	return payment, nil
+}
+
+// CancelPayment clears the reader and cancels an in-person payment that was not captured
+func (s *TerminalService) CancelPayment(ctx context.Context, tenantID string, paymentID uuid.UUID) (*models.PaymentTransaction, error) {
+	payment, err := s.getPendingPayment(ctx, tenantID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	terminal, err := s.terminalGateway(ctx, tenantID, payment.GatewayType)
+	if err != nil {
+		return nil, err
+	}
+
+	gatewayReaderID := ""
+	if payment.TerminalReaderID != nil {
+		if reader, err := s.getReader(ctx, tenantID, *payment.TerminalReaderID); err == nil {
+			gatewayReaderID = reader.GatewayReaderID
+		}
+	}
+
+	if err := terminal.CancelTerminalPayment(ctx, gatewayReaderID, payment.GatewayTransactionID); err != nil {
+		return nil, fmt.Errorf("failed to cancel payment: %w", err)
+	}
+
+	payment.Status = models.PaymentCanceled
+	if err := s.paymentService.repo.UpdatePaymentTransaction(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to update payment transaction: %w", err)
+	}
+	return payment, nil
+}
+
+// Reconcile aggregates a tenant's in-person and online payments over a period by channel,
+// gateway, store location and status, so both can be matched against gateway settlements
+func (s *TerminalService) Reconcile(ctx context.Context, tenantID string, from, to time.Time, channel models.PaymentChannel, locationID string) (*models.ReconciliationReport, error) {
+	query := s.db.WithContext(ctx).Model(&models.PaymentTransaction{}).
+		Select("channel, gateway_type, COALESCE(location_id, '') AS location_id, status, currency, "+
+			"COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount, "+
+			"COALESCE(SUM(gateway_fee), 0) AS gateway_fee, COALESCE(SUM(amount - gateway_fee - gateway_tax), 0) AS net_amount").
+		Where("tenant_id = ? AND created_at >= ? AND created_at < ?", tenantID, from, to)
+	if channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+	if locationID != "" {
+		query = query.Where("location_id = ?", locationID)
+	}
+
+	lines := []models.ReconciliationLine{}
+	if err := query.Group("channel, gateway_type, location_id, status, currency").
+		Order("channel, gateway_type, location_id, status").
+		Scan(&lines).Error; err != nil {
+		return nil, fmt.Errorf("failed to reconcile payments: %w", err)
+	}
+
+	return &models.ReconciliationReport{
+		From:  from,
+		To:    to,
+		Lines: lines,
+	}, nil
+}
+
+// getReader loads a tenant's reader
+func (s *TerminalService) getReader(ctx context.Context, tenantID string, readerID uuid.UUID) (*models.TerminalReader, error) {
+	var reader models.TerminalReader
+	if err := s.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, readerID).First(&reader).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTerminalReaderNotFound
+		}
+		return nil, fmt.Errorf("failed to get reader: %w", err)
+	}
+	return &reader, nil
+}
+
+// getPendingPayment loads a tenant's in-person payment that is still awaiting capture
+func (s *TerminalService) getPendingPayment(ctx context.Context, tenantID string, paymentID uuid.UUID) (*models.PaymentTransaction, error) {
+	payment, err := s.paymentService.repo.GetPaymentTransaction(ctx, paymentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTerminalPaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment.TenantID != tenantID || payment.Channel != models.ChannelInPerson {
+		return nil, ErrTerminalPaymentNotFound
+	}
+	if payment.Status != models.PaymentPending && payment.Status != models.PaymentProcessing {
+		return nil, ErrTerminalPaymentState
+	}
+	return payment, nil
+}
+
+// touchReader records that the reader just accepted a payment
+func (s *TerminalService) touchReader(ctx context.Context, reader *models.TerminalReader) {
+	now := time.Now()
+	if err := s.db.WithContext(ctx).Model(reader).
+		Updates(map[string]interface{}{"status": models.TerminalReaderOnline, "last_seen_at": now, "updated_at": now}).Error; err != nil {
+		fmt.Printf("[TerminalService] Failed to update reader %s: %v\n", reader.ID, err)
+	}
+}
+
+// terminalGateway loads the tenant's gateway config and returns its terminal implementation
+func (s *TerminalService) terminalGateway(ctx context.Context, tenantID string, gatewayType models.GatewayType) (gateway.TerminalGateway, error) {
+	gatewayConfig, err := s.paymentService.loadGatewayConfig(ctx, tenantID, "", gatewayType, "")
+	if err != nil {
+		return nil, err
+	}
+	return newTerminalGateway(gatewayConfig)
+}
+
+// newTerminalGateway builds the terminal implementation for a gateway config
+func newTerminalGateway(config *models.PaymentGatewayConfig) (gateway.TerminalGateway, error) {
+	switch config.GatewayType {
+	case models.GatewayStripe:
+		stripeGateway, err := gateway.NewStripeGateway(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Stripe gateway: %w", err)
+		}
+		return stripeGateway, nil
+	case models.GatewayRazorpay:
+		razorpayGateway, err := gateway.NewRazorpayGateway(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Razorpay gateway: %w", err)
+		}
+		return razorpayGateway, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrTerminalNotSupported, config.GatewayType)
+	}
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"payment-service/internal/clients"
+	"payment-service/internal/gateway"
+	"payment-service/internal/models"
+)
+
+const (
+	// upiExpiresAtMetadataKey stores when a UPI intent/QR/collect request stops being payable (unix seconds)
+	upiExpiresAtMetadataKey = "upi_expires_at"
+	// upiPollIntervalSeconds is how often the storefront should poll the upi-status endpoint
+	upiPollIntervalSeconds = 3
+	// upiTimeoutFailureCode marks payments failed because the customer never completed them
+	upiTimeoutFailureCode = "UPI_TIMEOUT"
+)
+
+var (
+	ErrUPIPaymentNotFound = errors.New("payment not found")
+	ErrNotUPIPayment      = errors.New("payment is not a UPI payment")
+)
+
+// createGatewayIntent creates a payment through the gateway adapter. It serves PhonePe and
+// Cashfree for every method, and Razorpay for the server-side UPI flows (intent, QR and
+// collect), returning the intent link or QR payload the storefront needs.
+func (s *PaymentService) createGatewayIntent(ctx context.Context, payment *models.PaymentTransaction, config *models.PaymentGatewayConfig, req models.CreatePaymentIntentRequest) (*models.PaymentIntentResponse, error) {
+	gw, err := newUPIGateway(config)
+	if err != nil {
+		payment.Status = models.PaymentFailed
+		payment.FailureMessage = err.Error()
+		s.repo.UpdatePaymentTransaction(ctx, payment)
+		return nil, err
+	}
+
+	customerID := ""
+	if req.CustomerID != nil {
+		customerID = req.CustomerID.String()
+	}
+
+	gatewayReq := &gateway.CreatePaymentRequest{
+		TenantID:      req.TenantID,
+		OrderID:       req.OrderID,
+		PaymentID:     payment.ID.String(),
+		Amount:        req.Amount,
+		Currency:      req.Currency,
+		CustomerEmail: req.CustomerEmail,
+		CustomerPhone: req.CustomerPhone,
+		CustomerName:  req.CustomerName,
+		CustomerID:    customerID,
+		Description:   req.Description,
+		ReturnURL:     req.ReturnURL,
+		CancelURL:     req.CancelURL,
+		WebhookURL:    s.gatewayWebhookURL(config.GatewayType, req.TenantID),
+		PaymentMethod: req.PaymentMethod,
+		UPIApp:        req.UPIApp,
+		VPA:           req.VPA,
+		Metadata: map[string]string{
+			"tenant_id": req.TenantID,
+			"order_id":  req.OrderID,
+		},
+	}
+	for k, v := range req.Metadata {
+		gatewayReq.Metadata[k] = v
+	}
+
+	upiFlow := isServerSideUPI(req.PaymentMethod, req.VPA)
+	if upiFlow {
+		gatewayReq.ExpiresInSeconds = int(s.config.UPIPaymentTimeout.Seconds())
+	}
+
+	result, err := gw.CreatePaymentIntent(ctx, gatewayReq)
+	if err != nil {
+		payment.Status = models.PaymentFailed
+		payment.FailureMessage = err.Error()
+		s.repo.UpdatePaymentTransaction(ctx, payment)
+		return nil, fmt.Errorf("failed to create %s payment: %w", config.GatewayType, err)
+	}
+
+	payment.GatewayTransactionID = result.GatewayOrderID
+	if upiFlow && result.ExpiresAt > 0 {
+		if payment.Metadata == nil {
+			payment.Metadata = make(models.JSONB)
+		}
+		payment.Metadata[upiExpiresAtMetadataKey] = result.ExpiresAt
+	}
+	if err := s.repo.UpdatePaymentTransaction(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to update payment transaction: %w", err)
+	}
+
+	options := result.CheckoutOptions
+	if options == nil {
+		options = make(map[string]interface{})
+	}
+	options["gatewayOrderId"] = result.GatewayOrderID
+	if result.RedirectURL != "" {
+		options["redirectUrl"] = result.RedirectURL
+	}
+
+	response := &models.PaymentIntentResponse{
+		PaymentIntentID: payment.ID.String(),
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+		Status:          payment.Status,
+		Options:         options,
+	}
+	if config.GatewayType == models.GatewayRazorpay && req.PaymentMethod != models.MethodUPIQR {
+		response.RazorpayOrderID = result.GatewayOrderID
+	}
+	if upiFlow {
+		response.UPI = &models.UPIPaymentDetails{
+			Flow:                req.PaymentMethod,
+			IntentURL:           result.UPIIntentURL,
+			QRPayload:           result.UPIQRPayload,
+			QRImageURL:          result.UPIQRImageURL,
+			ExpiresAt:           result.ExpiresAt,
+			PollIntervalSeconds: upiPollIntervalSeconds,
+		}
+	}
+
+	return response, nil
+}
+
+// RefreshUPIPaymentStatus polls the gateway for a pending UPI payment and returns its
+// current status. Payments still pending after their UPI request expired are failed
+// with UPI_TIMEOUT.
+func (s *PaymentService) RefreshUPIPaymentStatus(ctx context.Context, tenantID string, paymentID uuid.UUID) (*models.PaymentStatusResponse, error) {
+	payment, err := s.repo.GetPaymentTransaction(ctx, paymentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUPIPaymentNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment transaction: %w", err)
+	}
+	if payment.TenantID != tenantID {
+		return nil, ErrUPIPaymentNotFound
+	}
+	if !payment.PaymentMethodType.IsUPI() {
+		return nil, ErrNotUPIPayment
+	}
+
+	if isAwaitingCustomer(payment) {
+		if err := s.syncUPIPayment(ctx, payment); err != nil {
+			// Keep answering the storefront with the last known status
+			fmt.Printf("[PaymentService] Failed to refresh UPI payment %s: %v\n", payment.ID, err)
+		}
+	}
+
+	return toPaymentStatusResponse(payment), nil
+}
+
+// ExpireUPIPayments re-checks UPI payments whose request has expired and fails the ones
+// the customer never completed. It returns the number of payments timed out.
+func (s *PaymentService) ExpireUPIPayments(ctx context.Context, limit int) (int, error) {
+	payments, err := s.repo.ListExpiredUPIPayments(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired UPI payments: %w", err)
+	}
+
+	expired := 0
+	for i := range payments {
+		payment := &payments[i]
+		if err := s.syncUPIPayment(ctx, payment); err != nil {
+			fmt.Printf("[PaymentService] Failed to expire UPI payment %s: %v\n", payment.ID, err)
+			continue
+		}
+		if payment.FailureCode == upiTimeoutFailureCode {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// syncUPIPayment applies the gateway's view of a pending UPI payment, timing it out once
+// its request has expired without a result
+func (s *PaymentService) syncUPIPayment(ctx context.Context, payment *models.PaymentTransaction) error {
+	gatewayConfig, err := s.loadGatewayConfig(ctx, payment.TenantID, metadataString(payment.Metadata, "storefront_id"), payment.GatewayType, metadataString(payment.Metadata, "vendor_id"))
+	if err != nil {
+		return err
+	}
+	gw, err := newUPIGateway(gatewayConfig)
+	if err != nil {
+		return err
+	}
+
+	if checker, ok := gw.(gateway.UPIStatusChecker); ok && payment.GatewayTransactionID != "" {
+		result, err := checker.GetUPIPaymentStatus(ctx, payment.GatewayTransactionID)
+		if err != nil {
+			return err
+		}
+		if result.Status == models.PaymentSucceeded || result.Status == models.PaymentFailed {
+			applyPaymentResult(payment, result)
+			if err := s.repo.UpdatePaymentTransaction(ctx, payment); err != nil {
+				return fmt.Errorf("failed to update payment transaction: %w", err)
+			}
+			s.notifyPaymentOutcome(payment)
+			return nil
+		}
+	}
+
+	expiresAt := upiExpiresAt(payment)
+	if expiresAt.IsZero() || time.Now().Before(expiresAt) {
+		return nil
+	}
+
+	// Stop the QR code / order being paid after we've given up on it
+	if err := gw.CancelPayment(ctx, payment.GatewayTransactionID); err != nil {
+		fmt.Printf("[PaymentService] Failed to cancel expired UPI request %s: %v\n", payment.GatewayTransactionID, err)
+	}
+
+	now := time.Now()
+	payment.Status = models.PaymentFailed
+	payment.FailedAt = &now
+	payment.FailureCode = upiTimeoutFailureCode
+	payment.FailureMessage = "UPI payment was not completed before the request expired"
+	if err := s.repo.UpdatePaymentTransaction(ctx, payment); err != nil {
+		return fmt.Errorf("failed to update payment transaction: %w", err)
+	}
+	s.notifyPaymentOutcome(payment)
+	return nil
+}
+
+// notifyPaymentOutcome sends the customer notification and updates the order (or payment
+// link) for a payment that has just succeeded or failed
+func (s *PaymentService) notifyPaymentOutcome(payment *models.PaymentTransaction) {
+	if s.notificationClient != nil {
+		go func() {
+			notification := clients.BuildFromTransaction(payment, "")
+			if payment.Status == models.PaymentSucceeded {
+				notification.OrderDetailsURL = s.tenantClient.BuildOrderDetailsURL(context.Background(), payment.TenantID, payment.OrderID.String())
+				_ = s.notificationClient.SendPaymentCapturedNotification(context.Background(), notification)
+			} else if payment.Status == models.PaymentFailed {
+				notification.RetryURL = s.tenantClient.BuildRetryPaymentURL(context.Background(), payment.TenantID, payment.OrderID.String())
+				_ = s.notificationClient.SendPaymentFailedNotification(context.Background(), notification)
+			}
+		}()
+	}
+
+	if s.paymentLinks.Handles(payment) {
+		if payment.Status == models.PaymentSucceeded {
+			go s.paymentLinks.RecordPayment(context.Background(), payment)
+		}
+	} else if payment.Status == models.PaymentSucceeded {
+		go s.notifyOrderPaymentStatus(payment.OrderID.String(), payment.TenantID, payment.ID.String(), "PAID")
+	} else if payment.Status == models.PaymentFailed {
+		go s.notifyOrderPaymentStatus(payment.OrderID.String(), payment.TenantID, payment.ID.String(), "FAILED")
+	}
+}
+
+// gatewayWebhookURL builds the callback URL for gateways that take it per payment
+func (s *PaymentService) gatewayWebhookURL(gatewayType models.GatewayType, tenantID string) string {
+	if s.config.WebhookBaseURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/webhooks/%s?tenant_id=%s",
+		strings.TrimRight(s.config.WebhookBaseURL, "/"),
+		strings.ToLower(string(gatewayType)),
+		url.QueryEscape(tenantID))
+}
+
+// newUPIGateway creates the gateway adapter for the Indian gateways that take UPI payments
+func newUPIGateway(config *models.PaymentGatewayConfig) (gateway.PaymentGateway, error) {
+	switch config.GatewayType {
+	case models.GatewayRazorpay:
+		razorpayGateway, err := gateway.NewRazorpayGateway(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Razorpay gateway: %w", err)
+		}
+		return razorpayGateway, nil
+	case models.GatewayPhonePe:
+		phonepeGateway, err := gateway.NewPhonePeGateway(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize PhonePe gateway: %w", err)
+		}
+		return phonepeGateway, nil
+	case models.GatewayCashfree:
+		cashfreeGateway, err := gateway.NewCashfreeGateway(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Cashfree gateway: %w", err)
+		}
+		return cashfreeGateway, nil
+	default:
+		return nil, fmt.Errorf("unsupported gateway type: %s", config.GatewayType)
+	}
+}
+
+// applyPaymentResult copies a gateway payment result onto the transaction
+func applyPaymentResult(payment *models.PaymentTransaction, result *gateway.PaymentResult) {
+	now := time.Now()
+	payment.Status = result.Status
+	if result.Settlement != nil {
+		applySettlement(payment, result.Settlement)
+	} else if result.GatewayFee > 0 {
+		payment.GatewayFee = result.GatewayFee
+		payment.NetAmount = result.NetAmount
+	}
+	if result.CardBrand != "" {
+		payment.CardBrand = result.CardBrand
+		payment.CardLastFour = result.CardLastFour
+	}
+	if result.VPA != "" {
+		if payment.Metadata == nil {
+			payment.Metadata = make(models.JSONB)
+		}
+		payment.Metadata["upi_vpa"] = result.VPA
+	}
+	if result.GatewayPaymentID != "" {
+		if payment.Metadata == nil {
+			payment.Metadata = make(models.JSONB)
+		}
+		payment.Metadata["gateway_payment_id"] = result.GatewayPaymentID
+	}
+
+	switch result.Status {
+	case models.PaymentSucceeded:
+		payment.ProcessedAt = &now
+	case models.PaymentFailed:
+		payment.FailedAt = &now
+		payment.FailureCode = result.FailureCode
+		payment.FailureMessage = result.FailureMessage
+	}
+}
+
+// isServerSideUPI reports whether a payment uses a UPI flow completed outside checkout
+// (intent, QR, or a collect request to a known VPA)
+func isServerSideUPI(method models.PaymentMethodType, vpa string) bool {
+	return method == models.MethodUPIIntent || method == models.MethodUPIQR || (method == models.MethodUPI && vpa != "")
+}
+
+// isAwaitingCustomer reports whether a payment is still waiting on the customer
+func isAwaitingCustomer(payment *models.PaymentTransaction) bool {
+	return payment.Status == models.PaymentPending || payment.Status == models.PaymentProcessing
+}
+
+// upiExpiresAt reads when a payment's UPI request expires; zero if it has no expiry
+func upiExpiresAt(payment *models.PaymentTransaction) time.Time {
+	if payment.Metadata == nil {
+		return time.Time{}
+	}
+	switch v := payment.Metadata[upiExpiresAtMetadataKey].(type) {
+	case float64:
+		return time.Unix(int64(v), 0)
+	case int64:
+		return time.Unix(v, 0)
+	default:
+		return time.Time{}
+	}
+}
+
+// metadataString reads a string value from payment metadata
+func metadataString(metadata models.JSONB, key string) string {
+	if metadata == nil {
+		return ""
+	}
+	v, _ := metadata[key].(string)
+	return v
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	upiPaymentTimeoutInterval  = 1 * time.Minute
+	upiPaymentTimeoutBatchSize = 200
+)
+
+// UPIPaymentTimeoutWorker settles UPI intent, QR and collect payments whose request has
+// expired: it re-checks each with the gateway in case the webhook was missed, and fails
+// the ones the customer never completed so the order can be retried.
+type UPIPaymentTimeoutWorker struct {
+	paymentService *PaymentService
+	stopCh         chan struct{}
+}
+
+// NewUPIPaymentTimeoutWorker creates a new UPI payment timeout worker
+func NewUPIPaymentTimeoutWorker(paymentService *PaymentService) *UPIPaymentTimeoutWorker {
+	return &UPIPaymentTimeoutWorker{
+		paymentService: paymentService,
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// Start times out expired payments immediately and then every upiPaymentTimeoutInterval
+func (w *UPIPaymentTimeoutWorker) Start() {
+	ticker := time.NewTicker(upiPaymentTimeoutInterval)
+	defer ticker.Stop()
+
+	w.run()
+	for {
+		select {
+		case <-ticker.C:
+			w.run()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *UPIPaymentTimeoutWorker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *UPIPaymentTimeoutWorker) run() {
+	expired, err := w.paymentService.ExpireUPIPayments(context.Background(), upiPaymentTimeoutBatchSize)
+	if err != nil {
+		log.Printf("UPI payment timeout failed: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("Timed out %d UPI payments", expired)
+	}
+}
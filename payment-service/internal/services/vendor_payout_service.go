@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"payment-service/internal/models"
+	"payment-service/internal/repository"
+)
+
+// VendorPayoutService holds and claws back marketplace vendors' payouts when chargebacks and
+// refunds touch their orders. Payments belong to a vendor when their metadata carries a
+// vendor_id; payments without one are the tenant's own and are left alone.
+//
+// Sync methods are idempotent: they compare the dispute or refund with what the ledger
+// already holds for it, so gateway webhooks can be replayed safely.
+type VendorPayoutService struct {
+	db   *gorm.DB
+	repo *repository.PaymentRepository
+}
+
+// NewVendorPayoutService creates a new vendor payout service
+func NewVendorPayoutService(db *gorm.DB, repo *repository.PaymentRepository) *VendorPayoutService {
+	return &VendorPayoutService{
+		db:   db,
+		repo: repo,
+	}
+}
+
+// SyncDispute brings a vendor's payout ledger in line with a chargeback: an open dispute holds
+// the disputed amount, a won dispute releases it and a lost or accepted one claws it back.
+func (s *VendorPayoutService) SyncDispute(ctx context.Context, payment *models.PaymentTransaction, dispute *models.PaymentDispute) error {
+	vendorID := metadataString(payment.Metadata, "vendor_id")
+	if vendorID == "" {
+		return nil
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		entries, err := ledgerEntriesFor(tx, "dispute_id = ?", dispute.ID)
+		if err != nil {
+			return err
+		}
+		hold := openHold(entries)
+		base := models.VendorPayoutLedger{
+			TenantID:             payment.TenantID,
+			VendorID:             vendorID,
+			PaymentTransactionID: payment.ID,
+			OrderID:              payment.OrderID,
+			DisputeID:            &dispute.ID,
+			Amount:               dispute.Amount,
+			Currency:             dispute.Currency,
+		}
+
+		switch dispute.Status {
+		case models.DisputeNeedsResponse, models.DisputeUnderReview:
+			if len(entries) > 0 {
+				return nil
+			}
+			reason := ""
+			if dispute.Reason != "" {
+				reason = fmt.Sprintf(" (%s)", dispute.Reason)
+			}
+			base.EntryType = models.VendorPayoutHold
+			base.Explanation = fmt.Sprintf("The customer disputed the payment for order %s%s. %s %.2f is held from your payouts until the chargeback is decided.",
+				payment.OrderID, reason, dispute.Currency, dispute.Amount)
+		case models.DisputeWon:
+			if hold == nil {
+				return nil
+			}
+			base.EntryType = models.VendorPayoutRelease
+			base.Amount = hold.Amount
+			base.HoldID = &hold.ID
+			base.Explanation = fmt.Sprintf("The chargeback on order %s was decided in your favour. The held %s %.2f has been released to your payouts.",
+				payment.OrderID, hold.Currency, hold.Amount)
+		case models.DisputeLost, models.DisputeAccepted:
+			if hasEntry(entries, models.VendorPayoutClawback) {
+				return nil
+			}
+			if hold != nil {
+				base.HoldID = &hold.ID
+			}
+			outcome := "was lost"
+			if dispute.Status == models.DisputeAccepted {
+				outcome = "was accepted"
+			}
+			base.EntryType = models.VendorPayoutClawback
+			base.Explanation = fmt.Sprintf("The chargeback on order %s %s. %s %.2f returned to the customer has been deducted from your payouts.",
+				payment.OrderID, outcome, dispute.Currency, dispute.Amount)
+		default:
+			return nil
+		}
+
+		return tx.Create(&base).Error
+	})
+}
+
+// SyncRefund brings a vendor's payout ledger in line with a refund: a pending refund holds the
+// refunded amount, a completed one claws it back and a failed or canceled one releases it.
+// Refunds for returns carry the return ID in their metadata and are linked to it.
+func (s *VendorPayoutService) SyncRefund(ctx context.Context, payment *models.PaymentTransaction, refund *models.RefundTransaction) error {
+	vendorID := metadataString(payment.Metadata, "vendor_id")
+	if vendorID == "" {
+		return nil
+	}
+
+	returnID := metadataString(refund.Metadata, "return_id")
+	subject := fmt.Sprintf("A refund of %s %.2f on order %s", refund.Currency, refund.Amount, payment.OrderID)
+	if returnID != "" {
+		subject = fmt.Sprintf("A refund of %s %.2f for return %s on order %s", refund.Currency, refund.Amount, returnID, payment.OrderID)
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		entries, err := ledgerEntriesFor(tx, "refund_transaction_id = ?", refund.ID)
+		if err != nil {
+			return err
+		}
+		hold := openHold(entries)
+		base := models.VendorPayoutLedger{
+			TenantID:             payment.TenantID,
+			VendorID:             vendorID,
+			PaymentTransactionID: payment.ID,
+			OrderID:              payment.OrderID,
+			RefundTransactionID:  &refund.ID,
+			ReturnID:             returnID,
+			Amount:               refund.Amount,
+			Currency:             refund.Currency,
+		}
+
+		switch refund.Status {
+		case models.RefundPending:
+			if len(entries) > 0 {
+				return nil
+			}
+			base.EntryType = models.VendorPayoutHold
+			base.Explanation = subject + " is in progress. The amount is held from your payouts until it completes."
+		case models.RefundSucceeded:
+			if hasEntry(entries, models.VendorPayoutClawback) {
+				return nil
+			}
+			if hold != nil {
+				base.HoldID = &hold.ID
+			}
+			base.EntryType = models.VendorPayoutClawback
+			base.Explanation = subject + " was paid to the customer and has been deducted from your payouts."
+		case models.RefundFailed, models.RefundCanceled:
+			if hold == nil {
+				return nil
+			}
+			base.EntryType = models.VendorPayoutRelease
+			base.Amount = hold.Amount
+			base.HoldID = &hold.ID
+			base.Explanation = subject + " did not go through. The held amount has been released to your payouts."
+		default:
+			return nil
+		}
+
+		return tx.Create(&base).Error
+	})
+}
+
+// GetStatement returns a vendor's payout holds, releases and clawbacks between two dates,
+// oldest first, with totals
+func (s *VendorPayoutService) GetStatement(ctx context.Context, tenantID, vendorID string, startDate, endDate time.Time) (*models.VendorPayoutStatement, error) {
+	statement := &models.VendorPayoutStatement{
+		VendorID:  vendorID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Entries:   []models.VendorPayoutLedger{},
+	}
+
+	if err := s.db.WithContext(ctx).
+		Where("tenant_id = ? AND vendor_id = ? AND created_at BETWEEN ? AND ?", tenantID, vendorID, startDate, endDate).
+		Order("created_at ASC").
+		Find(&statement.Entries).Error; err != nil {
+		return nil, err
+	}
+	for _, entry := range statement.Entries {
+		switch entry.EntryType {
+		case models.VendorPayoutRelease:
+			statement.Released += entry.Amount
+		case models.VendorPayoutClawback:
+			statement.ClawedBack += entry.Amount
+		}
+	}
+
+	// Holds stay open across statement periods until something resolves them
+	if err := s.db.WithContext(ctx).
+		Model(&models.VendorPayoutLedger{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("tenant_id = ? AND vendor_id = ? AND entry_type = ?", tenantID, vendorID, models.VendorPayoutHold).
+		Where("id NOT IN (?)", s.db.Model(&models.VendorPayoutLedger{}).Select("hold_id").Where("hold_id IS NOT NULL")).
+		Scan(&statement.Held).Error; err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+// syncVendorPayoutForRefund updates the vendor's payout ledger after a refund changes status.
+// Failures are logged rather than failing the refund, which has already happened at the gateway.
+func syncVendorPayoutForRefund(ctx context.Context, payouts *VendorPayoutService, payment *models.PaymentTransaction, refund *models.RefundTransaction) {
+	if payouts == nil || payment == nil {
+		return
+	}
+	if err := payouts.SyncRefund(ctx, payment, refund); err != nil {
+		log.Printf("Failed to sync vendor payout for refund %s: %v", refund.ID, err)
+	}
+}
+
+// ledgerEntriesFor loads the ledger entries for a dispute or refund
+func ledgerEntriesFor(tx *gorm.DB, query string, id uuid.UUID) ([]models.VendorPayoutLedger, error) {
+	var entries []models.VendorPayoutLedger
+	if err := tx.Where(query, id).Order("created_at ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// openHold returns the hold among entries that no release or clawback has resolved yet
+func openHold(entries []models.VendorPayoutLedger) *models.VendorPayoutLedger {
+	resolved := make(map[uuid.UUID]bool)
+	for _, entry := range entries {
+		if entry.HoldID != nil {
+			resolved[*entry.HoldID] = true
+		}
+	}
+	for i := range entries {
+		if entries[i].EntryType == models.VendorPayoutHold && !resolved[entries[i].ID] {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+// hasEntry reports whether entries include one of the given type
+func hasEntry(entries []models.VendorPayoutLedger, entryType models.VendorPayoutEntryType) bool {
+	for _, entry := range entries {
+		if entry.EntryType == entryType {
+			return true
+		}
+	}
+	return false
+}
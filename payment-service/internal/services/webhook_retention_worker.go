@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"payment-service/internal/clients"
+	"payment-service/internal/models"
+)
+
+const (
+	webhookRetentionResource  = "webhook_events"
+	webhookRetentionInterval  = 6 * time.Hour
+	webhookRetentionBatchSize = 1000
+)
+
+// WebhookRetentionWorker enforces per-tenant retention of raw gateway webhook payloads.
+// Policies are fetched from staff-service and a summary of every purge is reported back.
+type WebhookRetentionWorker struct {
+	db              *gorm.DB
+	retentionClient *clients.RetentionClient
+	stopCh          chan struct{}
+}
+
+// NewWebhookRetentionWorker creates a new webhook retention worker
+func NewWebhookRetentionWorker(db *gorm.DB, retentionClient *clients.RetentionClient) *WebhookRetentionWorker {
+	return &WebhookRetentionWorker{
+		db:              db,
+		retentionClient: retentionClient,
+		stopCh:          make(chan struct{}),
+	}
+}
+
+// Start runs a purge immediately and then every webhookRetentionInterval
+func (w *WebhookRetentionWorker) Start() {
+	ticker := time.NewTicker(webhookRetentionInterval)
+	defer ticker.Stop()
+
+	w.runPurge()
+	for {
+		select {
+		case <-ticker.C:
+			w.runPurge()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *WebhookRetentionWorker) Stop() {
+	close(w.stopCh)
+}
+
+func (w *WebhookRetentionWorker) runPurge() {
+	if err := w.purge(context.Background()); err != nil {
+		log.Printf("Webhook retention purge failed: %v", err)
+	}
+}
+
+// purge applies each tenant's retention. If the policies can't be fetched nothing is
+// deleted, since falling back to defaults could discard data a tenant chose to keep longer.
+func (w *WebhookRetentionWorker) purge(ctx context.Context) error {
+	policies, err := w.retentionClient.GetPolicySet(ctx, webhookRetentionResource)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var tenantIDs []string
+	if err := w.db.WithContext(ctx).
+		Model(&models.WebhookEvent{}).
+		Where("created_at < ? AND tenant_id IS NOT NULL AND tenant_id <> ''", now.AddDate(0, 0, -policies.ShortestDays())).
+		Distinct().
+		Pluck("tenant_id", &tenantIDs).Error; err != nil {
+		return err
+	}
+
+	for _, tenantID := range tenantIDs {
+		days, dryRun := policies.PolicyFor(tenantID)
+		run := &clients.RetentionPurgeRun{
+			TenantID:      tenantID,
+			Resource:      webhookRetentionResource,
+			RetentionDays: days,
+			Cutoff:        now.AddDate(0, 0, -days),
+			DryRun:        dryRun,
+			StartedAt:     time.Now().UTC(),
+		}
+
+		err := w.db.WithContext(ctx).
+			Model(&models.WebhookEvent{}).
+			Where("tenant_id = ? AND created_at < ?", tenantID, run.Cutoff).
+			Count(&run.MatchedCount).Error
+		if err == nil && run.MatchedCount == 0 {
+			continue
+		}
+		if err == nil && !dryRun {
+			run.DeletedCount, err = w.deleteBefore(ctx, &tenantID, run.Cutoff)
+		}
+
+		run.Status = "completed"
+		if err != nil {
+			run.Status = "failed"
+			msg := err.Error()
+			run.Error = &msg
+		}
+		run.CompletedAt = time.Now().UTC()
+
+		if err := w.retentionClient.RecordPurgeRun(ctx, run); err != nil {
+			log.Printf("Failed to record webhook purge for tenant %s: %v", tenantID, err)
+		}
+		log.Printf("Webhook retention for tenant %s: %d matched, %d deleted (dry run: %v, status: %s)",
+			tenantID, run.MatchedCount, run.DeletedCount, dryRun, run.Status)
+	}
+
+	// Events whose tenant couldn't be resolved belong to no tenant policy; apply the default
+	deleted, err := w.deleteBefore(ctx, nil, now.AddDate(0, 0, -policies.DefaultDays))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("Webhook retention removed %d events without a tenant", deleted)
+	}
+
+	return nil
+}
+
+// deleteBefore deletes a tenant's events older than the cutoff in batches so each statement
+// stays short. A nil tenantID targets events without a tenant.
+func (w *WebhookRetentionWorker) deleteBefore(ctx context.Context, tenantID *string, cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		batch := w.db.Model(&models.WebhookEvent{}).Select("id").Where("created_at < ?", cutoff)
+		if tenantID != nil {
+			batch = batch.Where("tenant_id = ?", *tenantID)
+		} else {
+			batch = batch.Where("(tenant_id IS NULL OR tenant_id = '')")
+		}
+		batch = batch.Limit(webhookRetentionBatchSize)
+
+		result := w.db.WithContext(ctx).Where("id IN (?)", batch).Delete(&models.WebhookEvent{})
+		total += result.RowsAffected
+		if result.Error != nil {
+			return total, result.Error
+		}
+		if result.RowsAffected < webhookRetentionBatchSize {
+			return total, nil
+		}
+	}
+}
@@ -12,10 +12,13 @@ import (
 	"time"
 
 	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/balancetransaction"
+	"github.com/stripe/stripe-go/v76/charge"
 	"github.com/stripe/stripe-go/v76/checkout/session"
 	"github.com/stripe/stripe-go/v76/webhook"
 
 	"payment-service/internal/clients"
+	"payment-service/internal/gateway"
 	"payment-service/internal/models"
 	"payment-service/internal/razorpay"
 	"payment-service/internal/repository"
@@ -27,6 +30,8 @@ type WebhookService struct {
 	repo               *repository.PaymentRepository
 	notificationClient *clients.NotificationClient
 	tenantClient       *clients.TenantClient
+	paymentLinks       *PaymentLinkService
+	vendorPayouts      *VendorPayoutService
 }
 
 // NewWebhookService creates a new webhook service
@@ -38,6 +43,18 @@ func NewWebhookService(repo *repository.PaymentRepository, notificationClient *c
 	}
 }
 
+// SetPaymentLinkService routes payments created from payment links to the link service
+// instead of updating the order directly
+func (s *WebhookService) SetPaymentLinkService(paymentLinks *PaymentLinkService) {
+	s.paymentLinks = paymentLinks
+}
+
+// SetVendorPayoutService holds and claws back vendor payouts as disputes and refunds on
+// vendors' orders progress
+func (s *WebhookService) SetVendorPayoutService(vendorPayouts *VendorPayoutService) {
+	s.vendorPayouts = vendorPayouts
+}
+
 // ProcessRazorpayWebhook processes a Razorpay webhook event
 func (s *WebhookService) ProcessRazorpayWebhook(ctx context.Context, body []byte, signature string, tenantID string) error {
 	// Get gateway config to retrieve webhook secret
@@ -97,6 +114,11 @@ func (s *WebhookService) ProcessRazorpayWebhook(ctx context.Context, body []byte
 		err = s.handleRefundProcessed(ctx, payload.Payload)
 	case "refund.failed":
 		err = s.handleRefundFailed(ctx, payload.Payload)
+	case "qr_code.credited":
+		err = s.handleQRCodeCredited(ctx, payload.Payload)
+	case "payment.dispute.created", "payment.dispute.under_review", "payment.dispute.action_required",
+		"payment.dispute.won", "payment.dispute.lost", "payment.dispute.closed":
+		err = s.handleRazorpayDispute(ctx, payload.Payload)
 	default:
 		// Unknown event type, mark as processed
 		err = nil
@@ -175,6 +197,10 @@ func (s *WebhookService) ProcessStripeWebhook(ctx context.Context, body []byte,
 		err = s.handleStripePaymentIntentFailed(ctx, event.Data.Raw)
 	case "charge.refunded":
 		err = s.handleStripeChargeRefunded(ctx, event.Data.Raw)
+	case "charge.updated":
+		err = s.handleStripeChargeUpdated(ctx, event.Data.Raw)
+	case "charge.dispute.created", "charge.dispute.updated", "charge.dispute.closed":
+		err = s.handleStripeDispute(ctx, event.Data.Raw, tenantID)
 	default:
 		// Unknown event type, mark as processed
 		err = nil
@@ -208,11 +234,11 @@ func (s *WebhookService) handleStripeCheckoutSessionCompleted(ctx context.Contex
 		return errors.New("missing order_id in session metadata")
 	}
 
-	// Find payment by order ID
-	payment, err := s.repo.GetPaymentTransactionByOrderID(ctx, orderID)
+	// Find payment by session ID (stored as gateway transaction ID). An order can have several
+	// payments (e.g. partial payments through a payment link), so the order is only a fallback.
+	payment, err := s.repo.GetPaymentTransactionByGatewayID(ctx, sess.ID)
 	if err != nil {
-		// Try to find by session ID (stored as gateway transaction ID)
-		payment, err = s.repo.GetPaymentTransactionByGatewayID(ctx, sess.ID)
+		payment, err = s.repo.GetPaymentTransactionByOrderID(ctx, orderID)
 		if err != nil {
 			return fmt.Errorf("failed to find payment for order %s: %w", orderID, err)
 		}
@@ -266,7 +292,11 @@ func (s *WebhookService) handleStripeCheckoutSessionCompleted(ctx context.Contex
 
 	// If payment succeeded, notify orders service and send notification
 	if payment.Status == models.PaymentSucceeded {
-		go s.notifyOrderPaymentComplete(orderID, tenantID, payment.ID.String())
+		if s.paymentLinks.Handles(payment) {
+			go s.paymentLinks.RecordPayment(context.Background(), payment)
+		} else {
+			go s.notifyOrderPaymentComplete(orderID, tenantID, payment.ID.String())
+		}
 
 		// Send payment captured notification (non-blocking)
 		if s.notificationClient != nil {
@@ -316,10 +346,22 @@ func (s *WebhookService) handleStripePaymentIntentSucceeded(ctx context.Context,
 		payment.PaymentMethodType = mapStripePaymentMethodType(string(pi.PaymentMethod.Type))
 	}
 
+	// Settlement currency, rate and fees for the payout. The balance transaction may not
+	// exist yet, in which case charge.updated fills them in later.
+	if pi.LatestCharge != nil {
+		if settlement := fetchStripeSettlement(pi.LatestCharge.ID); settlement != nil {
+			applySettlement(payment, settlement)
+		}
+	}
+
 	if err := s.repo.UpdatePaymentTransaction(ctx, payment); err != nil {
 		return err
 	}
 
+	if s.paymentLinks.Handles(payment) {
+		go s.paymentLinks.RecordPayment(context.Background(), payment)
+	}
+
 	// Send payment captured notification (non-blocking)
 	if s.notificationClient != nil {
 		go func() {
@@ -379,6 +421,52 @@ func (s *WebhookService) handleStripePaymentIntentFailed(ctx context.Context, da
 	return nil
 }
 
+// handleStripeChargeUpdated records the settlement once Stripe attaches the charge's balance transaction
+func (s *WebhookService) handleStripeChargeUpdated(ctx context.Context, data json.RawMessage) error {
+	var ch stripe.Charge
+	if err := json.Unmarshal(data, &ch); err != nil {
+		return fmt.Errorf("failed to parse charge: %w", err)
+	}
+	if ch.BalanceTransaction == nil || ch.PaymentIntent == nil || !ch.Paid {
+		return nil
+	}
+
+	payment, err := s.repo.GetPaymentTransactionByGatewayID(ctx, ch.PaymentIntent.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find payment: %w", err)
+	}
+	// Settlement from a report or an earlier event is kept
+	if payment.SettlementRecordedAt != nil {
+		return nil
+	}
+
+	bt, err := balancetransaction.Get(ch.BalanceTransaction.ID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch balance transaction: %w", err)
+	}
+	settlement := gateway.StripeSettlement(bt)
+	if settlement == nil {
+		return nil
+	}
+	applySettlement(payment, settlement)
+	return s.repo.UpdatePaymentTransaction(ctx, payment)
+}
+
+// fetchStripeSettlement loads a charge's balance transaction, or nil while Stripe hasn't created it
+func fetchStripeSettlement(chargeID string) *gateway.SettlementInfo {
+	if chargeID == "" {
+		return nil
+	}
+	params := &stripe.ChargeParams{}
+	params.AddExpand("balance_transaction")
+	ch, err := charge.Get(chargeID, params)
+	if err != nil {
+		fmt.Printf("[WebhookService] Failed to fetch charge %s for settlement: %v\n", chargeID, err)
+		return nil
+	}
+	return gateway.StripeSettlement(ch.BalanceTransaction)
+}
+
 // handleStripeChargeRefunded handles charge.refunded event
 func (s *WebhookService) handleStripeChargeRefunded(ctx context.Context, data json.RawMessage) error {
 	var charge stripe.Charge
@@ -425,6 +513,135 @@ func (s *WebhookService) handleStripeChargeRefunded(ctx context.Context, data js
 	return nil
 }
 
+// stripeDisputeStatuses maps Stripe dispute statuses to ours. Inquiries (warning_*) don't
+// withdraw funds, so they aren't tracked.
+var stripeDisputeStatuses = map[stripe.DisputeStatus]models.DisputeStatus{
+	stripe.DisputeStatusNeedsResponse: models.DisputeNeedsResponse,
+	stripe.DisputeStatusUnderReview:   models.DisputeUnderReview,
+	stripe.DisputeStatusWon:           models.DisputeWon,
+	stripe.DisputeStatusLost:          models.DisputeLost,
+}
+
+// handleStripeDispute handles charge.dispute.created, charge.dispute.updated and charge.dispute.closed
+func (s *WebhookService) handleStripeDispute(ctx context.Context, data json.RawMessage, tenantID string) error {
+	var dispute stripe.Dispute
+	if err := json.Unmarshal(data, &dispute); err != nil {
+		return fmt.Errorf("failed to parse dispute: %w", err)
+	}
+
+	status, ok := stripeDisputeStatuses[dispute.Status]
+	if !ok {
+		return nil
+	}
+	if dispute.PaymentIntent == nil || dispute.PaymentIntent.ID == "" {
+		return errors.New("missing payment intent ID in dispute")
+	}
+
+	payment, err := s.repo.GetPaymentTransactionByGatewayID(ctx, dispute.PaymentIntent.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find payment: %w", err)
+	}
+
+	var respondBy *time.Time
+	if dispute.EvidenceDetails != nil && dispute.EvidenceDetails.DueBy > 0 {
+		due := time.Unix(dispute.EvidenceDetails.DueBy, 0)
+		respondBy = &due
+	}
+
+	return s.recordDispute(ctx, tenantID, payment, dispute.ID, float64(dispute.Amount)/100.0,
+		strings.ToUpper(string(dispute.Currency)), string(dispute.Reason), status, respondBy)
+}
+
+// razorpayDisputeStatuses maps Razorpay dispute statuses to ours. A closed dispute is one the
+// merchant accepted.
+var razorpayDisputeStatuses = map[string]models.DisputeStatus{
+	"open":         models.DisputeNeedsResponse,
+	"under_review": models.DisputeUnderReview,
+	"won":          models.DisputeWon,
+	"lost":         models.DisputeLost,
+	"closed":       models.DisputeAccepted,
+}
+
+// handleRazorpayDispute handles the payment.dispute.* events
+func (s *WebhookService) handleRazorpayDispute(ctx context.Context, payload map[string]interface{}) error {
+	disputeData, ok := razorpayEntity(payload, "dispute")
+	if !ok {
+		return errors.New("invalid dispute data in webhook")
+	}
+	disputeID, ok := disputeData["id"].(string)
+	if !ok {
+		return errors.New("missing dispute ID in webhook")
+	}
+	rawStatus, _ := disputeData["status"].(string)
+	status, ok := razorpayDisputeStatuses[rawStatus]
+	if !ok {
+		return nil
+	}
+
+	paymentData, ok := razorpayEntity(payload, "payment")
+	if !ok {
+		return errors.New("invalid payment data in webhook")
+	}
+	payment, err := s.findRazorpayPayment(ctx, paymentData)
+	if err != nil {
+		return err
+	}
+
+	amount, _ := disputeData["amount"].(float64)
+	currency, _ := disputeData["currency"].(string)
+	reason, _ := disputeData["reason_code"].(string)
+	var respondBy *time.Time
+	if due, ok := disputeData["respond_by"].(float64); ok && due > 0 {
+		t := time.Unix(int64(due), 0)
+		respondBy = &t
+	}
+
+	return s.recordDispute(ctx, payment.TenantID, payment, disputeID, amount/100.0, currency, reason, status, respondBy)
+}
+
+// recordDispute creates or updates a chargeback and holds, releases or claws back the
+// vendor's payout for it
+func (s *WebhookService) recordDispute(ctx context.Context, tenantID string, payment *models.PaymentTransaction, gatewayDisputeID string, amount float64, currency, reason string, status models.DisputeStatus, respondBy *time.Time) error {
+	dispute, err := s.repo.GetDisputeByGatewayID(ctx, tenantID, gatewayDisputeID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get dispute: %w", err)
+		}
+		dispute = &models.PaymentDispute{
+			TenantID:             tenantID,
+			PaymentTransactionID: payment.ID,
+			GatewayDisputeID:     gatewayDisputeID,
+		}
+	}
+
+	dispute.Amount = amount
+	dispute.Currency = currency
+	dispute.Reason = reason
+	dispute.Status = status
+	if respondBy != nil {
+		dispute.RespondBy = respondBy
+	}
+	switch status {
+	case models.DisputeWon, models.DisputeLost, models.DisputeAccepted:
+		if dispute.ResolvedAt == nil {
+			now := time.Now()
+			dispute.ResolvedAt = &now
+		}
+		dispute.Resolution = strings.ToLower(string(status))
+	}
+
+	if err := s.repo.SaveDispute(ctx, dispute); err != nil {
+		return fmt.Errorf("failed to save dispute: %w", err)
+	}
+
+	if s.vendorPayouts != nil {
+		if err := s.vendorPayouts.SyncDispute(ctx, payment, dispute); err != nil {
+			return fmt.Errorf("failed to sync vendor payout: %w", err)
+		}
+	}
+	return nil
+}
+
 // notifyOrderPaymentComplete sends notification to orders service that payment is complete
 func (s *WebhookService) notifyOrderPaymentComplete(orderID, tenantID, paymentID string) {
 	s.notifyOrderPaymentStatus(orderID, tenantID, paymentID, "PAID")
@@ -508,20 +725,14 @@ func mapStripePaymentMethodType(pmType string) models.PaymentMethodType {
 
 // handlePaymentAuthorized handles payment.authorized event
 func (s *WebhookService) handlePaymentAuthorized(ctx context.Context, payload map[string]interface{}) error {
-	paymentData, ok := payload["payment"].(map[string]interface{})
+	paymentData, ok := razorpayEntity(payload, "payment")
 	if !ok {
 		return errors.New("invalid payment data in webhook")
 	}
 
-	paymentID, ok := paymentData["id"].(string)
-	if !ok {
-		return errors.New("missing payment ID in webhook")
-	}
-
-	// Find payment by gateway transaction ID
-	payment, err := s.repo.GetPaymentTransactionByGatewayID(ctx, paymentID)
+	payment, err := s.findRazorpayPayment(ctx, paymentData)
 	if err != nil {
-		return fmt.Errorf("failed to find payment: %w", err)
+		return err
 	}
 
 	// Update payment status
@@ -531,20 +742,14 @@ func (s *WebhookService) handlePaymentAuthorized(ctx context.Context, payload ma
 
 // handlePaymentCaptured handles payment.captured event
 func (s *WebhookService) handlePaymentCaptured(ctx context.Context, payload map[string]interface{}) error {
-	paymentData, ok := payload["payment"].(map[string]interface{})
+	paymentData, ok := razorpayEntity(payload, "payment")
 	if !ok {
 		return errors.New("invalid payment data in webhook")
 	}
 
-	paymentID, ok := paymentData["id"].(string)
-	if !ok {
-		return errors.New("missing payment ID in webhook")
-	}
-
-	// Find payment by gateway transaction ID
-	payment, err := s.repo.GetPaymentTransactionByGatewayID(ctx, paymentID)
+	payment, err := s.findRazorpayPayment(ctx, paymentData)
 	if err != nil {
-		return fmt.Errorf("failed to find payment: %w", err)
+		return err
 	}
 
 	// Update payment status
@@ -567,12 +772,22 @@ func (s *WebhookService) handlePaymentCaptured(ctx context.Context, payload map[
 		}
 	}
 
+	// Settlement currency, rate and fees for the payout
+	if settlement := gateway.RazorpaySettlement(paymentData); settlement != nil {
+		applySettlement(payment, settlement)
+	}
+
 	if err := s.repo.UpdatePaymentTransaction(ctx, payment); err != nil {
 		return err
 	}
 
-	// Notify orders service that payment is complete (auto-update order payment status)
-	go s.notifyOrderPaymentComplete(payment.OrderID.String(), payment.TenantID, payment.ID.String())
+	// Notify orders service that payment is complete (auto-update order payment status).
+	// Link payments settle the order through the link, which may take several payments.
+	if s.paymentLinks.Handles(payment) {
+		go s.paymentLinks.RecordPayment(context.Background(), payment)
+	} else {
+		go s.notifyOrderPaymentComplete(payment.OrderID.String(), payment.TenantID, payment.ID.String())
+	}
 
 	// Send payment captured notification (non-blocking)
 	if s.notificationClient != nil {
@@ -588,20 +803,14 @@ func (s *WebhookService) handlePaymentCaptured(ctx context.Context, payload map[
 
 // handlePaymentFailed handles payment.failed event
 func (s *WebhookService) handlePaymentFailed(ctx context.Context, payload map[string]interface{}) error {
-	paymentData, ok := payload["payment"].(map[string]interface{})
+	paymentData, ok := razorpayEntity(payload, "payment")
 	if !ok {
 		return errors.New("invalid payment data in webhook")
 	}
 
-	paymentID, ok := paymentData["id"].(string)
-	if !ok {
-		return errors.New("missing payment ID in webhook")
-	}
-
-	// Find payment by gateway transaction ID
-	payment, err := s.repo.GetPaymentTransactionByGatewayID(ctx, paymentID)
+	payment, err := s.findRazorpayPayment(ctx, paymentData)
 	if err != nil {
-		return fmt.Errorf("failed to find payment: %w", err)
+		return err
 	}
 
 	// Update payment status
@@ -620,8 +829,11 @@ func (s *WebhookService) handlePaymentFailed(ctx context.Context, payload map[st
 		return err
 	}
 
-	// Notify orders service that payment failed (auto-update order payment status)
-	go s.notifyOrderPaymentFailed(payment.OrderID.String(), payment.TenantID, payment.ID.String())
+	// Notify orders service that payment failed (auto-update order payment status).
+	// A failed link payment leaves the link open for another attempt, so the order is untouched.
+	if !s.paymentLinks.Handles(payment) {
+		go s.notifyOrderPaymentFailed(payment.OrderID.String(), payment.TenantID, payment.ID.String())
+	}
 
 	// Send payment failed notification (non-blocking)
 	if s.notificationClient != nil {
@@ -730,6 +942,7 @@ func (s *WebhookService) handleRefundProcessed(ctx context.Context, payload map[
 	if err := s.repo.UpdateRefundTransaction(ctx, refund); err != nil {
 		return err
 	}
+	syncVendorPayoutForRefund(ctx, s.vendorPayouts, payment, refund)
 
 	// Notify orders service that payment was refunded (auto-update order payment status)
 	go s.notifyOrderPaymentRefunded(payment.OrderID.String(), payment.TenantID, payment.ID.String())
@@ -797,5 +1010,226 @@ func (s *WebhookService) handleRefundFailed(ctx context.Context, payload map[str
 		refund.FailureMessage = errorMsg
 	}
 
-	return s.repo.UpdateRefundTransaction(ctx, refund)
+	if err := s.repo.UpdateRefundTransaction(ctx, refund); err != nil {
+		return err
+	}
+	syncVendorPayoutForRefund(ctx, s.vendorPayouts, payment, refund)
+	return nil
+}
+
+// razorpayEntity returns the entity for a key in a Razorpay webhook payload. Razorpay wraps
+// entities as {"payment": {"entity": {...}}}; unwrapped payloads are accepted as well.
+func razorpayEntity(payload map[string]interface{}, key string) (map[string]interface{}, bool) {
+	data, ok := payload[key].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	if entity, ok := data["entity"].(map[string]interface{}); ok {
+		return entity, true
+	}
+	return data, true
+}
+
+// findRazorpayPayment finds the transaction for a Razorpay payment entity. Payments are
+// stored under their order ID until confirmed, so the order ID is tried as a fallback.
+func (s *WebhookService) findRazorpayPayment(ctx context.Context, paymentData map[string]interface{}) (*models.PaymentTransaction, error) {
+	paymentID, ok := paymentData["id"].(string)
+	if !ok {
+		return nil, errors.New("missing payment ID in webhook")
+	}
+
+	payment, err := s.repo.GetPaymentTransactionByGatewayID(ctx, paymentID)
+	if err == nil {
+		return payment, nil
+	}
+	if orderID, ok := paymentData["order_id"].(string); ok && orderID != "" {
+		if payment, orderErr := s.repo.GetPaymentTransactionByGatewayID(ctx, orderID); orderErr == nil {
+			return payment, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to find payment: %w", err)
+}
+
+// handleQRCodeCredited handles qr_code.credited, sent when a dynamic UPI QR code is paid
+func (s *WebhookService) handleQRCodeCredited(ctx context.Context, payload map[string]interface{}) error {
+	qrData, ok := razorpayEntity(payload, "qr_code")
+	if !ok {
+		return errors.New("invalid qr_code data in webhook")
+	}
+	qrID, ok := qrData["id"].(string)
+	if !ok {
+		return errors.New("missing QR code ID in webhook")
+	}
+
+	payment, err := s.repo.GetPaymentTransactionByGatewayID(ctx, qrID)
+	if err != nil {
+		return fmt.Errorf("failed to find payment: %w", err)
+	}
+	if payment.Status == models.PaymentSucceeded {
+		return nil
+	}
+
+	now := time.Now()
+	payment.Status = models.PaymentSucceeded
+	payment.ProcessedAt = &now
+	if paymentData, ok := razorpayEntity(payload, "payment"); ok {
+		if payment.Metadata == nil {
+			payment.Metadata = make(models.JSONB)
+		}
+		if id, ok := paymentData["id"].(string); ok {
+			payment.Metadata["gateway_payment_id"] = id
+		}
+		if vpa, ok := paymentData["vpa"].(string); ok {
+			payment.Metadata["upi_vpa"] = vpa
+		}
+	}
+
+	if err := s.repo.UpdatePaymentTransaction(ctx, payment); err != nil {
+		return err
+	}
+	s.notifyPaymentSettled(payment)
+	return nil
+}
+
+// ProcessPhonePeWebhook processes a PhonePe server-to-server callback
+func (s *WebhookService) ProcessPhonePeWebhook(ctx context.Context, body []byte, signature string, tenantID string) error {
+	return s.processGatewayWebhook(ctx, models.GatewayPhonePe, body, signature, tenantID)
+}
+
+// ProcessCashfreeWebhook processes a Cashfree webhook
+func (s *WebhookService) ProcessCashfreeWebhook(ctx context.Context, body []byte, timestamp, signature string, tenantID string) error {
+	return s.processGatewayWebhook(ctx, models.GatewayCashfree, body, timestamp+","+signature, tenantID)
+}
+
+// processGatewayWebhook verifies and applies a webhook through the gateway adapter
+func (s *WebhookService) processGatewayWebhook(ctx context.Context, gatewayType models.GatewayType, body []byte, signature string, tenantID string) error {
+	gatewayConfig, err := s.repo.GetGatewayConfigByType(ctx, tenantID, gatewayType)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%s gateway not configured for tenant %s", strings.ToLower(string(gatewayType)), tenantID)
+		}
+		return fmt.Errorf("failed to get gateway config: %w", err)
+	}
+
+	gw, err := newUPIGateway(gatewayConfig)
+	if err != nil {
+		return err
+	}
+	if err := gw.VerifyWebhook(body, signature); err != nil {
+		return fmt.Errorf("webhook signature verification failed: %w", err)
+	}
+
+	event, err := gw.ProcessWebhook(ctx, body)
+	if err != nil {
+		return err
+	}
+
+	// Check if event already processed (idempotency)
+	existingEvent, err := s.repo.GetWebhookEvent(ctx, gatewayType, event.EventID)
+	if err == nil && existingEvent != nil {
+		return nil
+	}
+
+	var eventPayload models.JSONB
+	_ = json.Unmarshal(event.RawPayload, &eventPayload)
+	webhookEvent := &models.WebhookEvent{
+		TenantID:    tenantID,
+		GatewayType: gatewayType,
+		EventID:     event.EventID,
+		EventType:   string(event.EventType),
+		Payload:     eventPayload,
+		Processed:   false,
+	}
+	if err := s.repo.CreateWebhookEvent(ctx, webhookEvent); err != nil {
+		return fmt.Errorf("failed to create webhook event: %w", err)
+	}
+
+	switch event.EventType {
+	case gateway.WebhookPaymentCaptured, gateway.WebhookPaymentFailed:
+		err = s.handleGatewayPaymentEvent(ctx, event)
+	default:
+		// Refund webhooks are informational; refunds are settled when created
+		err = nil
+	}
+
+	if err != nil {
+		webhookEvent.ProcessingError = err.Error()
+		webhookEvent.RetryCount++
+	} else {
+		webhookEvent.Processed = true
+		now := time.Now()
+		webhookEvent.ProcessedAt = &now
+	}
+	s.repo.UpdateWebhookEvent(ctx, webhookEvent)
+
+	return err
+}
+
+// handleGatewayPaymentEvent settles the payment a gateway-adapter webhook refers to.
+// The event's OrderID is the gateway reference stored when the payment was created.
+func (s *WebhookService) handleGatewayPaymentEvent(ctx context.Context, event *gateway.WebhookEvent) error {
+	payment, err := s.repo.GetPaymentTransactionByGatewayID(ctx, event.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to find payment: %w", err)
+	}
+	// A late failure (e.g. an abandoned attempt) must not undo a successful payment
+	if payment.Status == models.PaymentSucceeded || payment.Status == models.PaymentRefunded {
+		return nil
+	}
+
+	now := time.Now()
+	if payment.Metadata == nil {
+		payment.Metadata = make(models.JSONB)
+	}
+	if event.PaymentID != "" {
+		payment.Metadata["gateway_payment_id"] = event.PaymentID
+	}
+
+	if event.EventType == gateway.WebhookPaymentCaptured {
+		payment.Status = models.PaymentSucceeded
+		payment.ProcessedAt = &now
+		if event.Settlement != nil {
+			applySettlement(payment, event.Settlement)
+		}
+	} else {
+		if payment.Status == models.PaymentFailed {
+			return nil
+		}
+		payment.Status = models.PaymentFailed
+		payment.FailedAt = &now
+		payment.FailureCode = strings.ToUpper(event.Status)
+	}
+
+	if err := s.repo.UpdatePaymentTransaction(ctx, payment); err != nil {
+		return err
+	}
+	s.notifyPaymentSettled(payment)
+	return nil
+}
+
+// notifyPaymentSettled updates the order (or payment link) and notifies the customer
+// after a webhook moved a payment to succeeded or failed
+func (s *WebhookService) notifyPaymentSettled(payment *models.PaymentTransaction) {
+	if payment.Status == models.PaymentSucceeded {
+		if s.paymentLinks.Handles(payment) {
+			go s.paymentLinks.RecordPayment(context.Background(), payment)
+		} else {
+			go s.notifyOrderPaymentComplete(payment.OrderID.String(), payment.TenantID, payment.ID.String())
+		}
+	} else if !s.paymentLinks.Handles(payment) {
+		go s.notifyOrderPaymentFailed(payment.OrderID.String(), payment.TenantID, payment.ID.String())
+	}
+
+	if s.notificationClient != nil {
+		go func() {
+			notification := clients.BuildFromTransaction(payment, "")
+			if payment.Status == models.PaymentSucceeded {
+				notification.OrderDetailsURL = s.tenantClient.BuildOrderDetailsURL(context.Background(), payment.TenantID, payment.OrderID.String())
+				_ = s.notificationClient.SendPaymentCapturedNotification(context.Background(), notification)
+			} else {
+				notification.RetryURL = s.tenantClient.BuildRetryPaymentURL(context.Background(), payment.TenantID, payment.OrderID.String())
+				_ = s.notificationClient.SendPaymentFailedNotification(context.Background(), notification)
+			}
+		}()
+	}
 }
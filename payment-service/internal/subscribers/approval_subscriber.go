@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"payment-service/internal/eventconsumer"
 	"payment-service/internal/models"
 )
 
@@ -22,10 +23,9 @@ type GatewayConfigExecutor interface {
 
 // ApprovalSubscriber handles incoming approval events for gateway configs
 type ApprovalSubscriber struct {
-	subscriber        *gosharedevents.Subscriber
+	consumer          *eventconsumer.Consumer
 	gatewayExecutor   GatewayConfigExecutor
 	logger            *logrus.Entry
-	cancel            context.CancelFunc
 }
 
 // NewApprovalSubscriber creates a new approval event subscriber for payment-service
@@ -38,19 +38,20 @@ func NewApprovalSubscriber(
 		natsURL = "nats://nats.nats.svc.cluster.local:4222"
 	}
 
-	config := gosharedevents.DefaultSubscriberConfig(natsURL, "payment-service-approvals")
-	config.Name = "payment-service-approval-subscriber"
-	config.DeliverPolicy = "new"
-	config.MaxDeliver = 3
-	config.AckWait = 30 * time.Second
-
-	subscriber, err := gosharedevents.NewSubscriber(config, logger)
+	consumer, err := eventconsumer.New(eventconsumer.Config{
+		NATSURL:    natsURL,
+		ClientName: "payment-service-approval-subscriber",
+		Durable:    "payment-service-approvals",
+		Stream:     gosharedevents.StreamApprovals,
+		MaxDeliver: 3,
+		AckWait:    30 * time.Second,
+	}, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ApprovalSubscriber{
-		subscriber:      subscriber,
+		consumer:        consumer,
 		gatewayExecutor: gatewayExecutor,
 		logger:          logger.WithField("component", "approval-subscriber"),
 	}, nil
@@ -58,15 +59,12 @@ func NewApprovalSubscriber(
 
 // Start starts listening for approval events
 func (s *ApprovalSubscriber) Start(ctx context.Context) error {
-	ctx, cancel := context.WithCancel(ctx)
-	s.cancel = cancel
-
 	// Subscribe to approval.granted events for gateway configs
 	subjects := []string{gosharedevents.ApprovalGranted}
 
 	s.logger.Info("Starting approval event subscription for gateway configs...")
 
-	err := s.subscriber.SubscribeApprovalEvents(ctx, subjects, s.handleApprovalEvent)
+	err := s.consumer.Start(ctx, subjects, eventconsumer.JSON(s.handleApprovalEvent))
 	if err != nil {
 		return err
 	}
@@ -262,11 +260,8 @@ func (s *ApprovalSubscriber) executeDelete(ctx context.Context, event *gosharede
 
 // Stop stops the approval subscriber
 func (s *ApprovalSubscriber) Stop() {
-	if s.cancel != nil {
-		s.cancel()
-	}
-	if s.subscriber != nil {
-		s.subscriber.Close()
+	if s.consumer != nil {
+		s.consumer.Stop()
 	}
 	s.logger.Info("Approval subscriber stopped")
 }
@@ -11,16 +11,16 @@ import (
 	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
 	"github.com/Tesseract-Nexus/go-shared/security"
 	"gorm.io/gorm"
+	"payment-service/internal/eventconsumer"
 	"payment-service/internal/models"
 )
 
 // PaymentConfigSubscriber handles incoming payment config events from orders-service
 // and syncs them to payment-service's payment_gateway_configs table
 type PaymentConfigSubscriber struct {
-	subscriber *gosharedevents.Subscriber
+	consumer   *eventconsumer.Consumer
 	db         *gorm.DB
 	logger     *logrus.Entry
-	cancel     context.CancelFunc
 }
 
 // NewPaymentConfigSubscriber creates a new payment config event subscriber
@@ -33,19 +33,20 @@ func NewPaymentConfigSubscriber(
 		natsURL = "nats://nats.nats.svc.cluster.local:4222"
 	}
 
-	config := gosharedevents.DefaultSubscriberConfig(natsURL, "payment-service-config-sync")
-	config.Name = "payment-service-config-subscriber"
-	config.DeliverPolicy = "new"
-	config.MaxDeliver = 5 // Retry up to 5 times
-	config.AckWait = 30 * time.Second
-
-	subscriber, err := gosharedevents.NewSubscriber(config, logger)
+	consumer, err := eventconsumer.New(eventconsumer.Config{
+		NATSURL:    natsURL,
+		ClientName: "payment-service-config-subscriber",
+		Durable:    "payment-service-config-sync",
+		Stream:     gosharedevents.StreamPaymentConfigs,
+		MaxDeliver: 5, // Retry up to 5 times
+		AckWait:    30 * time.Second,
+	}, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	return &PaymentConfigSubscriber{
-		subscriber: subscriber,
+		consumer:   consumer,
 		db:         db,
 		logger:     logger.WithField("component", "payment-config-subscriber"),
 	}, nil
@@ -53,9 +54,6 @@ func NewPaymentConfigSubscriber(
 
 // Start starts listening for payment config events
 func (s *PaymentConfigSubscriber) Start(ctx context.Context) error {
-	ctx, cancel := context.WithCancel(ctx)
-	s.cancel = cancel
-
 	// Subscribe to all payment_config events
 	subjects := []string{
 		gosharedevents.PaymentConfigUpdated,
@@ -68,7 +66,7 @@ func (s *PaymentConfigSubscriber) Start(ctx context.Context) error {
 
 	// Subscribe to events
 	// Note: The stream is created by orders-service publisher
-	err := s.subscriber.Subscribe(ctx, gosharedevents.StreamPaymentConfigs, subjects, s.handlePaymentConfigMessage)
+	err := s.consumer.Start(ctx, subjects, eventconsumer.JSON(s.handlePaymentConfigEvent))
 	if err != nil {
 		return err
 	}
@@ -77,15 +75,8 @@ func (s *PaymentConfigSubscriber) Start(ctx context.Context) error {
 	return nil
 }
 
-// handlePaymentConfigMessage processes payment config messages from NATS
-func (s *PaymentConfigSubscriber) handlePaymentConfigMessage(ctx context.Context, msg *gosharedevents.Message) error {
-	// Parse the event from message data
-	var event gosharedevents.PaymentConfigEvent
-	if err := json.Unmarshal(msg.Data, &event); err != nil {
-		s.logger.WithError(err).Error("Failed to unmarshal payment config event")
-		return nil // Don't retry for invalid data
-	}
-
+// handlePaymentConfigEvent processes payment config events from NATS
+func (s *PaymentConfigSubscriber) handlePaymentConfigEvent(ctx context.Context, event *gosharedevents.PaymentConfigEvent) error {
 	s.logger.WithFields(logrus.Fields{
 		"event_type":          event.EventType,
 		"tenant_id":           event.TenantID,
@@ -97,9 +88,9 @@ func (s *PaymentConfigSubscriber) handlePaymentConfigMessage(ctx context.Context
 	// Handle different event types
 	switch event.EventType {
 	case gosharedevents.PaymentConfigUpdated, gosharedevents.PaymentConfigEnabled:
-		return s.syncPaymentConfig(ctx, &event)
+		return s.syncPaymentConfig(ctx, event)
 	case gosharedevents.PaymentConfigDisabled:
-		return s.disablePaymentConfig(ctx, &event)
+		return s.disablePaymentConfig(ctx, event)
 	case gosharedevents.PaymentConfigTested:
 		// Just log test results, no sync needed
 		s.logger.WithFields(logrus.Fields{
@@ -285,11 +276,8 @@ func (s *PaymentConfigSubscriber) decryptAndApplyCredentials(config *models.Paym
 
 // Stop stops the payment config subscriber
 func (s *PaymentConfigSubscriber) Stop() {
-	if s.cancel != nil {
-		s.cancel()
-	}
-	if s.subscriber != nil {
-		s.subscriber.Close()
+	if s.consumer != nil {
+		s.consumer.Stop()
 	}
 	s.logger.Info("Payment config subscriber stopped")
 }
@@ -0,0 +1,5 @@
+-- Gateway Config Versioning
+-- Migration 006: Optimistic concurrency control for PUT /api/v1/gateway-configs/:id
+-- Clients send If-Match or expectedVersion; stale updates are rejected with 409
+
+ALTER TABLE payment_gateway_configs ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
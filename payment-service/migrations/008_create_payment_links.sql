@@ -0,0 +1,44 @@
+-- Payment Links
+-- Migration 008: Hosted, signed payment links for invoices and manual orders
+-- Payments made through a link carry payment_link_id in payment_transactions.metadata
+
+CREATE TABLE IF NOT EXISTS payment_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    code VARCHAR(32) NOT NULL,
+
+    -- Optional order the link settles
+    order_id UUID,
+    order_number VARCHAR(100),
+    description TEXT,
+
+    -- Amounts
+    amount DECIMAL(12,2) NOT NULL,
+    amount_paid DECIMAL(12,2) DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    allow_partial_payments BOOLEAN DEFAULT FALSE,
+    minimum_payment_amount DECIMAL(12,2) DEFAULT 0,
+
+    -- Customer info
+    customer_email VARCHAR(255),
+    customer_name VARCHAR(255),
+    customer_phone VARCHAR(50),
+
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'PARTIALLY_PAID', 'PAID', 'EXPIRED', 'CANCELED')),
+    expires_at TIMESTAMP NOT NULL,
+    paid_at TIMESTAMP,
+
+    metadata JSONB,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_links_code ON payment_links(code);
+CREATE INDEX IF NOT EXISTS idx_payment_links_tenant ON payment_links(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_payment_links_order ON payment_links(order_id);
+CREATE INDEX IF NOT EXISTS idx_payment_links_status ON payment_links(status);
+CREATE INDEX IF NOT EXISTS idx_payment_links_expires ON payment_links(expires_at);
+
+-- Amount paid is recomputed from the link's succeeded transactions
+CREATE INDEX IF NOT EXISTS idx_payment_transactions_payment_link ON payment_transactions((metadata->>'payment_link_id'));
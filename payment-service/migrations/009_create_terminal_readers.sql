@@ -0,0 +1,34 @@
+-- Terminal / POS payments
+-- Migration 009: Card readers per store location and the payment channel on transactions
+-- In-person payments are regular payment_transactions with channel = 'IN_PERSON'
+
+CREATE TABLE IF NOT EXISTS terminal_readers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    location_id VARCHAR(255) NOT NULL,
+    gateway_type VARCHAR(50) NOT NULL,
+
+    -- Stripe reader/location IDs, or the Razorpay POS device ID
+    gateway_reader_id VARCHAR(255) NOT NULL,
+    gateway_location_id VARCHAR(255),
+
+    label VARCHAR(255),
+    device_type VARCHAR(100),
+    serial_number VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'UNKNOWN' CHECK (status IN ('ONLINE', 'OFFLINE', 'UNKNOWN')),
+    last_seen_at TIMESTAMP,
+
+    created_by VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_terminal_readers_gateway_reader ON terminal_readers(gateway_type, gateway_reader_id);
+CREATE INDEX IF NOT EXISTS idx_terminal_readers_tenant_location ON terminal_readers(tenant_id, location_id);
+
+ALTER TABLE payment_transactions ADD COLUMN IF NOT EXISTS channel VARCHAR(20) NOT NULL DEFAULT 'ONLINE';
+ALTER TABLE payment_transactions ADD COLUMN IF NOT EXISTS terminal_reader_id UUID;
+ALTER TABLE payment_transactions ADD COLUMN IF NOT EXISTS location_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_payment_transactions_channel ON payment_transactions(channel);
+CREATE INDEX IF NOT EXISTS idx_payment_transactions_location ON payment_transactions(location_id);
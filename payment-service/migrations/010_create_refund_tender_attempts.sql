@@ -0,0 +1,35 @@
+-- Refund fallback chain
+-- Migration 010: Per-refund tender attempts (original tender -> store credit -> manual bank transfer)
+-- and the tenant's configured fallback chain
+
+CREATE TABLE IF NOT EXISTS refund_tender_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    refund_transaction_id UUID NOT NULL REFERENCES refund_transactions(id) ON DELETE CASCADE,
+    sequence INTEGER NOT NULL,
+    tender VARCHAR(30) NOT NULL CHECK (tender IN ('ORIGINAL_TENDER', 'STORE_CREDIT', 'BANK_TRANSFER')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PENDING', 'SUCCEEDED', 'FAILED', 'SKIPPED')),
+
+    -- Gateway refund ID, gift card ID or bank transfer reference (UTR)
+    reference VARCHAR(255),
+    failure_message TEXT,
+
+    -- Manual bank transfers are completed by an operator
+    operator_notes TEXT,
+    processed_by VARCHAR(255),
+    processed_at TIMESTAMP,
+
+    metadata JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_tender_attempts_tenant ON refund_tender_attempts(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_refund_tender_attempts_refund ON refund_tender_attempts(refund_transaction_id);
+CREATE INDEX IF NOT EXISTS idx_refund_tender_attempts_pending_transfer ON refund_tender_attempts(tenant_id)
+    WHERE tender = 'BANK_TRANSFER' AND status = 'PENDING';
+
+ALTER TABLE refund_transactions ADD COLUMN IF NOT EXISTS tender VARCHAR(30);
+
+-- Empty means the default chain: ORIGINAL_TENDER, STORE_CREDIT, BANK_TRANSFER
+ALTER TABLE payment_settings ADD COLUMN IF NOT EXISTS refund_fallback_chain VARCHAR(30)[];
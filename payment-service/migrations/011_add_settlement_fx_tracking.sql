@@ -0,0 +1,19 @@
+-- Cross-border settlement tracking
+-- Migration 011: Settlement currency, exchange rate and FX fee per payment, the platform fee
+-- in the settlement currency, and the tenant's payout currency
+
+ALTER TABLE payment_transactions
+    ADD COLUMN IF NOT EXISTS settlement_currency VARCHAR(3),
+    ADD COLUMN IF NOT EXISTS exchange_rate DECIMAL(18,8),
+    ADD COLUMN IF NOT EXISTS settlement_amount DECIMAL(12,2),
+    ADD COLUMN IF NOT EXISTS fx_fee DECIMAL(12,2) DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS settlement_net_amount DECIMAL(12,2),
+    ADD COLUMN IF NOT EXISTS settlement_recorded_at TIMESTAMP;
+
+ALTER TABLE platform_fee_ledger
+    ADD COLUMN IF NOT EXISTS settlement_amount DECIMAL(12,2),
+    ADD COLUMN IF NOT EXISTS settlement_currency VARCHAR(3),
+    ADD COLUMN IF NOT EXISTS exchange_rate DECIMAL(18,8);
+
+ALTER TABLE payment_settings
+    ADD COLUMN IF NOT EXISTS settlement_currency VARCHAR(3);
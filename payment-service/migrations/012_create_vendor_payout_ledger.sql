@@ -0,0 +1,35 @@
+-- Vendor payout holds and clawbacks
+-- Migration 012: Ledger of holds, releases and clawbacks against marketplace vendors' payouts
+-- when a chargeback or refund (for a return) touches one of their orders
+
+CREATE TABLE IF NOT EXISTS vendor_payout_ledger (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    vendor_id VARCHAR(255) NOT NULL,
+    payment_transaction_id UUID NOT NULL REFERENCES payment_transactions(id),
+    order_id UUID NOT NULL,
+
+    -- What caused the entry: a chargeback, or a refund (optionally for a return)
+    dispute_id UUID REFERENCES payment_disputes(id),
+    refund_transaction_id UUID REFERENCES refund_transactions(id),
+    return_id VARCHAR(255),
+
+    entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN ('hold', 'release', 'clawback')),
+    amount DECIMAL(12,2) NOT NULL,
+    currency VARCHAR(3) DEFAULT 'USD',
+    -- The hold a release or clawback resolves
+    hold_id UUID REFERENCES vendor_payout_ledger(id),
+    explanation TEXT,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_payout_ledger_vendor ON vendor_payout_ledger(tenant_id, vendor_id);
+CREATE INDEX IF NOT EXISTS idx_vendor_payout_ledger_payment ON vendor_payout_ledger(payment_transaction_id);
+CREATE INDEX IF NOT EXISTS idx_vendor_payout_ledger_dispute ON vendor_payout_ledger(dispute_id);
+CREATE INDEX IF NOT EXISTS idx_vendor_payout_ledger_refund ON vendor_payout_ledger(refund_transaction_id);
+CREATE INDEX IF NOT EXISTS idx_vendor_payout_ledger_hold ON vendor_payout_ledger(hold_id);
+CREATE INDEX IF NOT EXISTS idx_vendor_payout_ledger_created ON vendor_payout_ledger(created_at);
+
+-- Chargebacks are looked up by their gateway dispute ID from webhooks
+CREATE INDEX IF NOT EXISTS idx_disputes_gateway_dispute ON payment_disputes(tenant_id, gateway_dispute_id);
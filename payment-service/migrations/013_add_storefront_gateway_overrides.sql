@@ -0,0 +1,15 @@
+-- Storefront gateway config overrides
+-- Migration 013: Gateway configs with an empty storefront_id are the tenant-level defaults;
+-- configs with one override the default of the same gateway type on that storefront
+
+ALTER TABLE payment_gateway_configs ADD COLUMN IF NOT EXISTS storefront_id VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE payment_gateway_configs ADD COLUMN IF NOT EXISTS overridden_fields TEXT[];
+
+-- One config per gateway type for each tenant, vendor and storefront
+DROP INDEX IF EXISTS unique_gateway_per_tenant_vendor;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_gateway_per_tenant_vendor_storefront ON payment_gateway_configs(tenant_id, COALESCE(vendor_id, ''), storefront_id, gateway_type);
+
+CREATE INDEX IF NOT EXISTS idx_payment_gateways_storefront ON payment_gateway_configs(storefront_id);
+
+COMMENT ON COLUMN payment_gateway_configs.storefront_id IS 'Storefront this config overrides the tenant default for. Empty for the tenant-level default.';
+COMMENT ON COLUMN payment_gateway_configs.overridden_fields IS 'Settings the storefront deliberately sets differently; skipped by propagation and drift detection.';
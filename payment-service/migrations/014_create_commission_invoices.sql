@@ -0,0 +1,94 @@
+-- Marketplace commission invoices
+-- Migration 014: Monthly invoices to marketplace vendors for the platform fees charged on their
+-- orders, with GST/VAT on the commission and gapless per-tenant numbering
+
+-- Fee ledger entries are attributed to the vendor and to the invoice that billed them
+ALTER TABLE platform_fee_ledger ADD COLUMN IF NOT EXISTS vendor_id VARCHAR(255);
+ALTER TABLE platform_fee_ledger ADD COLUMN IF NOT EXISTS commission_invoice_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_platform_fee_ledger_vendor ON platform_fee_ledger(vendor_id);
+CREATE INDEX IF NOT EXISTS idx_platform_fee_ledger_invoice ON platform_fee_ledger(commission_invoice_id);
+
+-- Backfill the vendor from the metadata of the payment the fee (or refunded fee) was charged on
+UPDATE platform_fee_ledger l
+SET vendor_id = p.metadata->>'vendor_id'
+FROM payment_transactions p
+WHERE l.payment_transaction_id = p.id AND l.vendor_id IS NULL AND p.metadata->>'vendor_id' IS NOT NULL;
+
+UPDATE platform_fee_ledger l
+SET vendor_id = p.metadata->>'vendor_id'
+FROM refund_transactions r
+JOIN payment_transactions p ON p.id = r.payment_transaction_id
+WHERE l.refund_transaction_id = r.id AND l.vendor_id IS NULL AND p.metadata->>'vendor_id' IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS commission_invoice_settings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    enabled BOOLEAN DEFAULT FALSE,
+
+    -- Issuer shown on the invoice
+    legal_name VARCHAR(255),
+    address TEXT,
+    tax_id VARCHAR(50),
+    country VARCHAR(2),
+    state VARCHAR(100),
+
+    tax_scheme VARCHAR(10) DEFAULT 'NONE' CHECK (tax_scheme IN ('NONE', 'GST', 'VAT')),
+    tax_rate DECIMAL(5,2) DEFAULT 0,
+
+    invoice_prefix VARCHAR(20) DEFAULT 'COM',
+    next_sequence BIGINT DEFAULT 1,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commission_invoice_settings_tenant ON commission_invoice_settings(tenant_id);
+
+CREATE TABLE IF NOT EXISTS commission_invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    vendor_id VARCHAR(255) NOT NULL,
+    invoice_number VARCHAR(50) NOT NULL,
+    sequence BIGINT NOT NULL,
+
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+
+    -- Vendor and issuer details as they were when the invoice was issued
+    vendor_name VARCHAR(255),
+    vendor_tax_id VARCHAR(50),
+    vendor_address TEXT,
+    vendor_country VARCHAR(100),
+    vendor_state VARCHAR(100),
+    issuer_name VARCHAR(255),
+    issuer_tax_id VARCHAR(50),
+    issuer_address TEXT,
+
+    commission_amount DECIMAL(12,2) NOT NULL,
+    refunded_amount DECIMAL(12,2) DEFAULT 0,
+    taxable_amount DECIMAL(12,2) NOT NULL,
+
+    tax_scheme VARCHAR(10) NOT NULL,
+    tax_rate DECIMAL(5,2) DEFAULT 0,
+    cgst_amount DECIMAL(12,2) DEFAULT 0,
+    sgst_amount DECIMAL(12,2) DEFAULT 0,
+    igst_amount DECIMAL(12,2) DEFAULT 0,
+    vat_amount DECIMAL(12,2) DEFAULT 0,
+    tax_amount DECIMAL(12,2) DEFAULT 0,
+    total_amount DECIMAL(12,2) NOT NULL,
+    reverse_charge BOOLEAN DEFAULT FALSE,
+    tax_note TEXT,
+
+    entry_count INTEGER DEFAULT 0,
+    line_items JSONB,
+
+    issued_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commission_invoices_number ON commission_invoices(tenant_id, invoice_number);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commission_invoices_period ON commission_invoices(tenant_id, vendor_id, period_start, currency);
+CREATE INDEX IF NOT EXISTS idx_commission_invoices_vendor ON commission_invoices(vendor_id);
+CREATE INDEX IF NOT EXISTS idx_commission_invoices_issued ON commission_invoices(issued_at);
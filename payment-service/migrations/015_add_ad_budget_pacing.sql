@@ -0,0 +1,52 @@
+-- Ad Campaign Budget Pacing
+-- Migration 015: Track campaign spend against paid budgets, budget top-ups and spend references
+
+-- =============================================================================
+-- Ad Campaign Budgets Table
+-- One row per campaign, created when its first payment is confirmed
+-- =============================================================================
+CREATE TABLE IF NOT EXISTS ad_campaign_budgets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    vendor_id UUID NOT NULL,
+    campaign_id UUID NOT NULL,
+    payment_type VARCHAR(20) NOT NULL CHECK (payment_type IN ('DIRECT', 'SPONSORED')),
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'EXHAUSTED')),
+
+    -- Paid budget (original payment plus top-ups) and spend reported by the ads system
+    budget_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    spent_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    currency VARCHAR(3) DEFAULT 'USD',
+
+    -- Schedule the budget is paced over
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+
+    exhausted_at TIMESTAMP,
+    last_spend_at TIMESTAMP,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    -- Ensure one budget per campaign per tenant
+    CONSTRAINT idx_ad_campaign_budgets_campaign UNIQUE (tenant_id, campaign_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ad_campaign_budgets_vendor ON ad_campaign_budgets(vendor_id);
+
+CREATE TRIGGER trigger_ad_campaign_budgets_updated_at
+    BEFORE UPDATE ON ad_campaign_budgets
+    FOR EACH ROW EXECUTE FUNCTION update_ad_billing_updated_at();
+
+-- =============================================================================
+-- Budget top-ups reference the campaign's original payment
+-- =============================================================================
+ALTER TABLE ad_campaign_payments ADD COLUMN IF NOT EXISTS parent_payment_id UUID REFERENCES ad_campaign_payments(id);
+CREATE INDEX IF NOT EXISTS idx_ad_campaign_payments_parent ON ad_campaign_payments(parent_payment_id);
+
+-- =============================================================================
+-- Spend reports carry the ads system's reference so retries are counted once
+-- =============================================================================
+ALTER TABLE ad_revenue_ledger ADD COLUMN IF NOT EXISTS spend_ref VARCHAR(255);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ad_revenue_ledger_spend_ref
+    ON ad_revenue_ledger(tenant_id, campaign_id, spend_ref) WHERE spend_ref IS NOT NULL;
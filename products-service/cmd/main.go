@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -14,12 +15,17 @@ import (
 	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 	"products-service/internal/clients"
 	"products-service/internal/config"
+	"products-service/internal/database"
 	"products-service/internal/events"
 	"products-service/internal/handlers"
+	"products-service/internal/jobs"
 	"products-service/internal/middleware"
 	"products-service/internal/repository"
+	"products-service/internal/search"
+	"products-service/internal/serviceauth"
 	"products-service/internal/subscribers"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
@@ -56,13 +62,31 @@ func main() {
 
 	// Initialize configuration
 	cfg := config.Load()
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		runSeed(cfg, os.Args[2:])
+		return
+	}
 
-	// Initialize database
-	db, err := config.InitDB(cfg)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return config.InitDB(cfg) })
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	// "products-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
+	}
+
 	// Initialize logger
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
@@ -95,6 +119,8 @@ func main() {
 
 	// Initialize repository
 	productsRepo := repository.NewProductsRepository(db, redisClient)
+	importJobRepo := repository.NewImportJobRepository(db)
+	importMappingRepo := repository.NewImportMappingRepository(db)
 
 	// Initialize event publisher for audit trail only if NATS_URL is set
 	var eventsPublisher *events.Publisher
@@ -125,9 +151,22 @@ func main() {
 	// Initialize handlers with event publisher (may be nil if NATS not configured)
 	productsHandler := handlers.NewProductsHandler(productsRepo, eventsPublisher)
 	documentHandler := handlers.NewDocumentHandler(cfg.DocumentServiceURL, cfg.ProductID)
-	importHandler := handlers.NewImportHandler(productsRepo, inventoryClient, categoriesClient, vendorClient)
+	importHandler := handlers.NewImportHandler(productsRepo, importJobRepo, importMappingRepo, documentHandler, inventoryClient, categoriesClient, vendorClient)
 	approvalProductsHandler := handlers.NewApprovalProductsHandler(productsRepo, approvalClient)
 	log.Println("✓ Approval handler initialized")
+	sitemapHandler := handlers.NewSitemapHandler(productsRepo, clients.NewTenantClient(), clients.NewContentClient())
+
+	// Start the async import worker (claims jobs with row locks, safe to run on every replica)
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+	importWorker := jobs.NewImportWorker(importJobRepo, importHandler, logger)
+	go importWorker.Start(workerCtx)
+	log.Println("✓ Import worker started")
+
+	// Rebuild storefront sitemaps hourly to pick up content pages and correct any drift
+	sitemapRebuildJob := jobs.NewSitemapRebuildJob(productsRepo, sitemapHandler, logger)
+	go sitemapRebuildJob.Start(workerCtx)
+	log.Println("✓ Sitemap rebuild job started")
 
 	// Initialize and start approval subscriber for NATS events
 	var approvalSubscriber *subscribers.ApprovalSubscriber
@@ -147,6 +186,75 @@ func main() {
 		}
 	}
 
+	// Keep storefront sitemaps current as products are published, archived or deleted
+	var sitemapSubscriber *subscribers.SitemapSubscriber
+	if natsURL != "" {
+		var err error
+		sitemapSubscriber, err = subscribers.NewSitemapSubscriber(productsRepo, logger)
+		if err != nil {
+			log.Printf("WARNING: Failed to initialize sitemap subscriber: %v (sitemaps update on scheduled rebuilds only)", err)
+		} else {
+			go func() {
+				if err := sitemapSubscriber.Start(context.Background()); err != nil {
+					log.Printf("WARNING: Sitemap subscriber error: %v", err)
+				}
+			}()
+			log.Println("✓ Sitemap subscriber initialized (listening for product events)")
+		}
+	}
+
+	// Keep cost prices at the landed cost of the latest inventory-service receipt
+	var costSubscriber *subscribers.CostSubscriber
+	if natsURL != "" {
+		var err error
+		costSubscriber, err = subscribers.NewCostSubscriber(productsRepo, logger)
+		if err != nil {
+			log.Printf("WARNING: Failed to initialize cost subscriber: %v (landed costs won't update cost prices)", err)
+		} else {
+			go func() {
+				if err := costSubscriber.Start(context.Background()); err != nil {
+					log.Printf("WARNING: Cost subscriber error: %v", err)
+				}
+			}()
+			log.Println("✓ Cost subscriber initialized (listening for inventory.landed_costs_received events)")
+		}
+	}
+
+	// Serve product searches from OpenSearch when configured, kept current from product events
+	var searchIndexSubscriber *subscribers.SearchIndexSubscriber
+	if cfg.OpenSearchURL != "" {
+		searchBackend := search.NewOpenSearch(search.OpenSearchConfig{
+			URL:         cfg.OpenSearchURL,
+			Index:       cfg.OpenSearchIndex,
+			Username:    cfg.OpenSearchUsername,
+			Password:    cfg.OpenSearchPassword,
+			PriceRanges: cfg.SearchPriceRanges,
+		}, logger)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := searchBackend.EnsureIndex(ctx); err != nil {
+			log.Printf("WARNING: Failed to prepare search index: %v (searches use PostgreSQL until it is reachable)", err)
+		}
+		cancel()
+		searchIndexer := search.NewIndexer(searchBackend, productsRepo)
+		productsHandler.SetSearchIndex(searchBackend, searchIndexer)
+		log.Printf("✓ Search index enabled (%s)", cfg.OpenSearchIndex)
+
+		if natsURL != "" {
+			var err error
+			searchIndexSubscriber, err = subscribers.NewSearchIndexSubscriber(searchIndexer, logger)
+			if err != nil {
+				log.Printf("WARNING: Failed to initialize search index subscriber: %v (the index updates on reindex only)", err)
+			} else {
+				go func() {
+					if err := searchIndexSubscriber.Start(context.Background()); err != nil {
+						log.Printf("WARNING: Search index subscriber error: %v", err)
+					}
+				}()
+				log.Println("✓ Search index subscriber initialized (listening for product events)")
+			}
+		}
+	}
+
 	// Initialize OpenTelemetry tracing
 	var tracerProvider *tracing.TracerProvider
 	if cfg.Environment == "production" {
@@ -169,9 +277,24 @@ func main() {
 	if staffServiceURL == "" {
 		staffServiceURL = "http://staff-service:8080"
 	}
-	rbacMw := rbac.NewMiddlewareWithURL(staffServiceURL, nil)
+	rbacClient := rbac.NewClient(staffServiceURL)
+	rbacMw := rbac.NewMiddleware(rbacClient, nil)
 	log.Println("✓ RBAC middleware initialized")
 
+	// Reuse effective permissions for RBAC_CACHE_TTL, dropping them early when staff-service
+	// publishes rbac.permissions_changed
+	rbacCacheTTL, _ := time.ParseDuration(os.Getenv("RBAC_CACHE_TTL"))
+	rbacCache := middleware.NewRBACPermissionCache(rbacClient, rbacCacheTTL)
+	go rbacCache.Start(workerCtx)
+	rbacSubscriber, err := subscribers.NewRBACSubscriber(rbacCache, logger)
+	if err != nil {
+		log.Printf("WARNING: Failed to initialize RBAC subscriber: %v (permission changes apply once cached permissions expire)", err)
+	} else if err := rbacSubscriber.Start(); err != nil {
+		log.Printf("WARNING: RBAC subscriber failed to start: %v", err)
+	} else {
+		log.Println("✓ RBAC permission cache invalidation subscriber started")
+	}
+
 	// Initialize Gin router
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -199,16 +322,24 @@ func main() {
 	// Authentication middleware using Istio JWT claims
 	// Istio validates JWT and injects x-jwt-claim-* headers
 	// AllowLegacyHeaders provides backward compatibility during migration
-	api.Use(gosharedmw.IstioAuth(gosharedmw.IstioAuthConfig{
+	// Requests with an X-API-Key header are authenticated with the tenant API key instead
+	api.Use(middleware.APIKeyAuth(staffServiceURL, gosharedmw.IstioAuth(gosharedmw.IstioAuthConfig{
 		RequireAuth:        true,
 		AllowLegacyHeaders: false,
 		SkipPaths:          []string{"/health", "/ready", "/metrics", "/swagger"},
-	}))
+	})))
+	api.Use(rbacCache.Track())
+	// Marks vendor users so vendor products go through review instead of being published directly
+	api.Use(gosharedmw.VendorScopeFilter())
+	// Admins with an X-Impersonation-Token act as the session's vendor or staff member
+	api.Use(middleware.ImpersonationAuth(staffServiceURL))
 
 	// API routes
 	v1 := api.Group("")
 	{
 		products := v1.Group("/products")
+		// Cost prices are only returned to staff holding catalog:costs:view and internal services
+		products.Use(middleware.CostVisibility(rbacMw.HasPermission))
 		{
 			// Read operations - require products:read permission
 			products.GET("", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetProducts)
@@ -218,10 +349,20 @@ func main() {
 			products.GET("/:id/variants", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetVariants)
 			products.GET("/:id/images", rbacMw.RequirePermission(rbac.PermissionProductsRead), documentHandler.GetProductImages)
 			products.GET("/analytics", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetAnalytics)
+			products.GET("/margins", rbacMw.RequirePermission(middleware.PermissionCostsView), productsHandler.GetMarginReport)
+			products.GET("/:id/cost-history", rbacMw.RequirePermission(middleware.PermissionCostsView), productsHandler.GetCostHistory)
+			products.GET("/:id/bundle", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetBundle)
 			products.GET("/stats", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetStats)
 			products.GET("/trending", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetTrendingProducts)
+			products.GET("/trash", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetTrashedProducts)
 			products.GET("/categories/:categoryId", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetProductsByCategory)
 			products.POST("/search", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.SearchProducts)
+			products.GET("/search/analyze", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.AnalyzeSearchQuery)
+			products.GET("/search/synonyms", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetSearchSynonyms)
+			products.GET("/search/settings", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetSearchSettings)
+			products.GET("/search/rules", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetSearchRules)
+			products.GET("/search/pins", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetSearchPins)
+			products.POST("/search/merchandising/preview", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.PreviewSearchMerchandising)
 			// AllowInternal: Allows Orders Service to check stock for guest checkout
 			products.POST("/inventory/check", rbacMw.RequirePermissionAllowInternal(rbac.PermissionInventoryRead), productsHandler.CheckStock)
 
@@ -229,6 +370,7 @@ func main() {
 			products.POST("", rbacMw.RequirePermission(rbac.PermissionProductsCreate), productsHandler.CreateProduct)
 			products.POST("/:id/variants", rbacMw.RequirePermission(rbac.PermissionProductsCreate), productsHandler.CreateVariant)
 			products.POST("/bulk", rbacMw.RequirePermission(rbac.PermissionProductsCreate), productsHandler.BulkCreateProducts)
+			products.POST("/:id/bundle", rbacMw.RequirePermission(rbac.PermissionProductsCreate), productsHandler.CreateBundle)
 
 			// Update operations - require products:update permission
 			products.PUT("/:id", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.UpdateProduct)
@@ -236,6 +378,21 @@ func main() {
 			products.PUT("/:id/price", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), approvalProductsHandler.UpdateProductPriceWithApproval) // Approval-aware
 			products.POST("/bulk/status", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.BulkUpdateStatus)
 			products.PUT("/:id/variants/:variantId", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.UpdateVariant)
+			products.PUT("/:id/bundle", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.UpdateBundle)
+
+			// Search tuning - tenant synonym groups, fuzzy matching and stop words
+			products.POST("/search/synonyms", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.CreateSearchSynonym)
+			products.PUT("/search/synonyms/:synonymId", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.UpdateSearchSynonym)
+			products.DELETE("/search/synonyms/:synonymId", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.DeleteSearchSynonym)
+			products.PUT("/search/settings", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.UpdateSearchSettings)
+			products.POST("/search/reindex", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.ReindexSearch)
+
+			// Search merchandising - boost/bury rules and pinned results for storefront search
+			products.POST("/search/rules", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.CreateSearchRule)
+			products.PUT("/search/rules/:ruleId", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.UpdateSearchRule)
+			products.DELETE("/search/rules/:ruleId", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.DeleteSearchRule)
+			products.POST("/search/pins", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.SaveSearchPin)
+			products.DELETE("/search/pins/:pinId", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.DeleteSearchPin)
 
 			// Images management - require products:update permission
 			products.POST("/:id/images", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.AddImage)
@@ -247,14 +404,28 @@ func main() {
 
 			// Delete operations - require products:delete permission
 			products.DELETE("/:id", rbacMw.RequirePermission(rbac.PermissionProductsDelete), productsHandler.DeleteProduct)
+			products.POST("/:id/restore", rbacMw.RequirePermission(rbac.PermissionProductsDelete), productsHandler.RestoreProduct)
 			products.DELETE("/:id/variants/:variantId", rbacMw.RequirePermission(rbac.PermissionProductsDelete), productsHandler.DeleteVariant)
+			products.DELETE("/:id/bundle", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.DeleteBundle)
 			products.DELETE("/bulk", rbacMw.RequirePermission(rbac.PermissionProductsDelete), approvalProductsHandler.BulkDeleteProductsWithApproval) // Approval-aware
 			products.POST("/:id/cascade/validate", rbacMw.RequirePermission(rbac.PermissionProductsDelete), productsHandler.ValidateCascadeDelete)
 			products.POST("/bulk/cascade/validate", rbacMw.RequirePermission(rbac.PermissionProductsDelete), productsHandler.ValidateBulkCascadeDelete)
 
 			// Approval endpoints
 			products.POST("/:id/submit-for-approval", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), approvalProductsHandler.SubmitProductForApproval)
-			products.POST("/approval-callback", approvalProductsHandler.HandleApprovalCallback)
+			products.POST("/approval-callback", serviceauth.New(serviceauth.ConfigFromEnv("products-service"), logger).Require("approval-service"), approvalProductsHandler.HandleApprovalCallback)
+
+			// Vendor product review (marketplace)
+			products.GET("/review-queue", rbacMw.RequirePermission(rbac.PermissionProductsPublish), approvalProductsHandler.GetReviewQueue)
+			products.POST("/:id/approve", rbacMw.RequirePermission(rbac.PermissionProductsPublish), approvalProductsHandler.ApproveProduct)
+			products.POST("/:id/request-changes", rbacMw.RequirePermission(rbac.PermissionProductsPublish), approvalProductsHandler.RequestProductChanges)
+			products.GET("/:id/change-requests", rbacMw.RequirePermission(rbac.PermissionProductsRead), approvalProductsHandler.GetChangeRequests)
+
+			// Duplicate detection - merge suggestions and the tenant's create/import policy
+			products.GET("/duplicates", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetDuplicateClusters)
+			products.POST("/duplicates/resolve", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.ResolveDuplicates)
+			products.GET("/duplicates/policy", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetDuplicatePolicy)
+			products.PUT("/duplicates/policy", rbacMw.RequirePermission(rbac.PermissionProductsPublish), productsHandler.UpdateDuplicatePolicy)
 
 			// Inventory operations - require inventory:update permission
 			products.PUT("/:id/inventory", rbacMw.RequirePermission(rbac.PermissionInventoryUpdate), productsHandler.UpdateInventory)
@@ -266,7 +437,20 @@ func main() {
 			// Import/Export - require specific permissions
 			products.GET("/import/template", rbacMw.RequirePermission(rbac.PermissionProductsImport), importHandler.GetImportTemplate)
 			products.POST("/import", rbacMw.RequirePermission(rbac.PermissionProductsImport), importHandler.ImportProducts)
+			products.POST("/import/preview", rbacMw.RequirePermission(rbac.PermissionProductsImport), importHandler.PreviewImport)
+			products.GET("/import/mappings", rbacMw.RequirePermission(rbac.PermissionProductsImport), importHandler.ListMappingPresets)
+			products.POST("/import/mappings", rbacMw.RequirePermission(rbac.PermissionProductsImport), importHandler.SaveMappingPreset)
+			products.DELETE("/import/mappings/:presetId", rbacMw.RequirePermission(rbac.PermissionProductsImport), importHandler.DeleteMappingPreset)
+			products.POST("/import/jobs", rbacMw.RequirePermission(rbac.PermissionProductsImport), importHandler.CreateImportJob)
+			products.GET("/import/jobs", rbacMw.RequirePermission(rbac.PermissionProductsImport), importHandler.ListImportJobs)
+			products.GET("/import/jobs/:jobId", rbacMw.RequirePermission(rbac.PermissionProductsImport), importHandler.GetImportJob)
+			products.GET("/import/jobs/:jobId/errors", rbacMw.RequirePermission(rbac.PermissionProductsImport), importHandler.GetImportJobErrors)
+			products.POST("/import/jobs/:jobId/resume", rbacMw.RequirePermission(rbac.PermissionProductsImport), importHandler.ResumeImportJob)
 			products.POST("/export", rbacMw.RequirePermission(rbac.PermissionProductsExport), productsHandler.ExportProducts)
+
+			// Localized content - translations per locale
+			products.PUT("/:id/translations/:locale", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.UpdateProductTranslation)
+			products.DELETE("/:id/translations/:locale", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), productsHandler.DeleteProductTranslation)
 		}
 
 		// Category management
@@ -285,6 +469,25 @@ func main() {
 
 			// Delete operations - require categories:delete permission
 			categories.DELETE("/:id", rbacMw.RequirePermission(rbac.PermissionCategoriesDelete), productsHandler.DeleteCategory)
+
+			// Localized content - translations per locale
+			categories.PUT("/:id/translations/:locale", rbacMw.RequirePermission(rbac.PermissionCategoriesUpdate), productsHandler.UpdateCategoryTranslation)
+			categories.DELETE("/:id/translations/:locale", rbacMw.RequirePermission(rbac.PermissionCategoriesUpdate), productsHandler.DeleteCategoryTranslation)
+		}
+
+		// Translation coverage and bulk translation import/export for products and categories
+		translations := v1.Group("/translations")
+		{
+			translations.GET("/status", rbacMw.RequirePermission(rbac.PermissionProductsRead), productsHandler.GetTranslationStatus)
+			translations.GET("/export", rbacMw.RequirePermission(rbac.PermissionProductsExport), productsHandler.ExportTranslations)
+			translations.POST("/import", rbacMw.RequirePermission(rbac.PermissionProductsImport), productsHandler.ImportTranslations)
+		}
+
+		// Storefront sitemap
+		sitemap := v1.Group("/sitemap")
+		{
+			sitemap.GET("", rbacMw.RequirePermission(rbac.PermissionProductsRead), sitemapHandler.GetSitemapStatus)
+			sitemap.POST("/rebuild", rbacMw.RequirePermission(rbac.PermissionProductsUpdate), sitemapHandler.RebuildSitemap)
 		}
 	}
 
@@ -294,6 +497,8 @@ func main() {
 	// =============================================================================
 	storefront := router.Group("/api/v1/storefront")
 	storefront.Use(middleware.TenantMiddleware()) // Require tenant context only
+	storefront.Use(middleware.StorefrontLocale()) // Translate content into ?locale= or Accept-Language
+	storefront.Use(middleware.Storefront())       // Add shopper-facing data such as JSON-LD
 	{
 		// Public product browsing
 		storefront.GET("/products", productsHandler.GetProducts)
@@ -306,6 +511,10 @@ func main() {
 		// Public category browsing
 		storefront.GET("/categories", productsHandler.GetCategories)
 		storefront.GET("/categories/:id", productsHandler.GetCategory)
+
+		// Sitemaps, served by the storefront at /sitemap.xml and /sitemaps/*
+		storefront.GET("/sitemap.xml", sitemapHandler.GetSitemapIndex)
+		storefront.GET("/sitemaps/:file", sitemapHandler.GetSitemapFile)
 	}
 
 	// Swagger documentation
@@ -317,13 +526,18 @@ func main() {
 		port = "8087"
 	}
 
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+
 	// Graceful shutdown handling
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		log.Printf("Products service starting on port %s", port)
-		if err := router.Run(":" + port); err != nil {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatal("Failed to start server:", err)
 		}
 	}()
@@ -332,12 +546,47 @@ func main() {
 	<-quit
 	log.Println("Shutting down products-service...")
 
+	// Stop accepting connections and let in-flight requests finish before stopping workers
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
+	// Stop import worker; an in-flight job is requeued for another replica
+	cancelWorkers()
+	log.Println("✓ Import worker and sitemap rebuild job stopped")
+
+	// Stop sitemap subscriber
+	if sitemapSubscriber != nil {
+		sitemapSubscriber.Stop()
+		log.Println("✓ Sitemap subscriber stopped")
+	}
+
+	// Stop search index subscriber
+	if searchIndexSubscriber != nil {
+		searchIndexSubscriber.Stop()
+		log.Println("✓ Search index subscriber stopped")
+	}
+
+	// Stop cost subscriber
+	if costSubscriber != nil {
+		costSubscriber.Stop()
+		log.Println("✓ Cost subscriber stopped")
+	}
+
 	// Stop approval subscriber
 	if approvalSubscriber != nil {
 		approvalSubscriber.Stop()
 		log.Println("✓ Approval subscriber stopped")
 	}
 
+	// Stop RBAC permission cache invalidation
+	if rbacSubscriber != nil {
+		rbacSubscriber.Stop()
+		log.Println("✓ RBAC subscriber stopped")
+	}
+
 	// Shutdown tracer provider
 	if tracerProvider != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+
+	"products-service/internal/config"
+	"products-service/internal/database"
+	"products-service/internal/loadseed"
+	"products-service/internal/models"
+)
+
+// seedAuthor is recorded as the creator of seeded rows
+const seedAuthor = "loadseed"
+
+// runSeed handles "products-service seed [flags]", filling the database with load-test
+// products. Carts and orders seeded by customers-service and orders-service
+// with the same flags reference these products. Rows that already exist are left alone.
+func runSeed(cfg *config.Config, args []string) {
+	opts, err := loadseed.Parse("products-service", args)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Invalid seed options: %v", err)
+	}
+	if err := loadseed.CheckEnvironment(cfg.Environment); err != nil {
+		log.Fatalf("Refusing to seed: %v", err)
+	}
+
+	db, err := config.InitDB(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	if err := database.Prepare(db, database.ConfigFromEnv(false)); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
+	}
+	// Batched inserts are too large to log statement by statement
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Warn)})
+
+	started := time.Now()
+	total := 0
+	for t, tenantID := range opts.TenantIDs() {
+		count := opts.Count(t, opts.Products)
+		batch := make([]models.Product, 0, opts.BatchSize)
+		for i := 0; i < count; i++ {
+			batch = append(batch, seedProduct(opts, tenantID, i, started))
+			if len(batch) == opts.BatchSize || i == count-1 {
+				if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&batch).Error; err != nil {
+					log.Fatalf("Failed to seed products for %s: %v", tenantID, err)
+				}
+				batch = batch[:0]
+			}
+		}
+		total += count
+		log.Printf("Seeded %s: %d products", tenantID, count)
+	}
+	fmt.Fprintf(os.Stdout, "Seeded %d products for %d tenants in %s\n", total, opts.Tenants, time.Since(started).Round(time.Second))
+}
+
+// seedProduct builds the index-th product of a tenant, active and in stock. All of a
+// tenant's products share one vendor; categories are spread over -categories IDs that
+// categories-service doesn't know about, which is enough for filtering by category.
+func seedProduct(opts *loadseed.Options, tenantID string, index int, now time.Time) models.Product {
+	p := opts.ProductAt(tenantID, index)
+	slug := fmt.Sprintf("lt-product-%06d", index+1)
+	keywords := strings.ToLower(p.Name)
+	quantity := 100
+	inStock := models.InventoryStatusInStock
+	currency := "USD"
+	author := seedAuthor
+	return models.Product{
+		ID:              p.ID,
+		TenantID:        tenantID,
+		VendorID:        loadseed.ID("vendor", tenantID, 0).String(),
+		CategoryID:      p.CategoryID.String(),
+		CreatedByID:     &author,
+		Name:            p.Name,
+		Slug:            &slug,
+		SKU:             p.SKU,
+		Price:           fmt.Sprintf("%.2f", p.Price),
+		Status:          models.ProductStatusActive,
+		InventoryStatus: &inStock,
+		Quantity:        &quantity,
+		SearchKeywords:  &keywords,
+		CurrencyCode:    &currency,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		CreatedBy:       &author,
+	}
+}
@@ -0,0 +1,239 @@
+// Package bulk runs batch create and update requests with partial success. Items are written
+// in transactional chunks with a savepoint around each one, so a bad item is rolled back and
+// reported on its own while the rest of its chunk commits. With atomic=true the whole batch
+// is one transaction that commits only if every item succeeds.
+//
+// It is kept identical in every service that uses it; change all copies together.
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxItems is the most items a bulk request may carry
+	MaxItems = 100
+	// DefaultChunkSize is how many items are committed together
+	DefaultChunkSize = 25
+)
+
+// Status is what happened to one item
+type Status string
+
+const (
+	StatusCreated Status = "created"
+	StatusUpdated Status = "updated"
+	StatusSkipped Status = "skipped"
+	StatusFailed  Status = "failed"
+	// StatusRolledBack marks an item that succeeded but was undone because an atomic batch failed
+	StatusRolledBack Status = "rolled_back"
+)
+
+// errRollback aborts a transaction whose items have already been recorded
+var errRollback = errors.New("bulk: rollback")
+
+// Error is an item failure reported to the client
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Fail returns an item failure with a code the client can act on
+func Fail(code, format string, args ...interface{}) error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Outcome is the result of applying one item
+type Outcome struct {
+	Status Status
+	Data   interface{}
+}
+
+// Created reports an item that was created
+func Created(data interface{}) Outcome {
+	return Outcome{Status: StatusCreated, Data: data}
+}
+
+// Updated reports an item that was updated
+func Updated(data interface{}) Outcome {
+	return Outcome{Status: StatusUpdated, Data: data}
+}
+
+// Skipped reports an item that was deliberately left alone, such as a duplicate the request
+// asked to skip
+func Skipped() Outcome {
+	return Outcome{Status: StatusSkipped}
+}
+
+// ItemResult is the result of one item of a batch
+type ItemResult struct {
+	Index      int         `json:"index"`
+	ExternalID *string     `json:"externalId,omitempty"`
+	Status     Status      `json:"status"`
+	Success    bool        `json:"success"`
+	Data       interface{} `json:"data,omitempty"`
+	Error      *Error      `json:"error,omitempty"`
+}
+
+// Response is the result of a batch, with one result per item in request order
+type Response struct {
+	// Success is true when at least one item was applied, or every item was skipped
+	Success      bool         `json:"success"`
+	Atomic       bool         `json:"atomic"`
+	TotalCount   int          `json:"totalCount"`
+	SuccessCount int          `json:"successCount"`
+	FailedCount  int          `json:"failedCount"`
+	SkippedCount int          `json:"skippedCount"`
+	Results      []ItemResult `json:"results"`
+}
+
+// Options controls how a batch is written
+type Options struct {
+	// Atomic writes the batch in one transaction that is rolled back if any item fails
+	Atomic bool
+	// ChunkSize is how many items commit together; DefaultChunkSize when zero
+	ChunkSize int
+}
+
+// ParseOptions reads ?atomic=true from the request
+func ParseOptions(c *gin.Context) Options {
+	return Options{Atomic: c.Query("atomic") == "true"}
+}
+
+// Run applies count items. apply writes item index through tx and returns its outcome, or an
+// error to fail the item; errors other than *Error are reported as WRITE_FAILED. apply must
+// only write through tx, and side effects such as events belong after Run returns.
+func Run(db *gorm.DB, count int, opts Options, apply func(tx *gorm.DB, index int) (Outcome, error)) *Response {
+	resp := &Response{
+		Atomic:     opts.Atomic,
+		TotalCount: count,
+		Results:    make([]ItemResult, count),
+	}
+	for i := range resp.Results {
+		resp.Results[i] = ItemResult{Index: i, Status: StatusFailed}
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if opts.Atomic {
+		chunkSize = count
+	}
+
+	for start := 0; start < count; start += chunkSize {
+		end := start + chunkSize
+		if end > count {
+			end = count
+		}
+		resp.runChunk(db, start, end, opts.Atomic, apply)
+	}
+
+	for _, result := range resp.Results {
+		switch result.Status {
+		case StatusCreated, StatusUpdated:
+			resp.SuccessCount++
+		case StatusSkipped:
+			resp.SkippedCount++
+		default:
+			resp.FailedCount++
+		}
+	}
+	resp.Success = resp.SuccessCount > 0 || (count > 0 && resp.SkippedCount == count)
+	return resp
+}
+
+// runChunk writes items [start, end) in one transaction
+func (r *Response) runChunk(db *gorm.DB, start, end int, atomic bool, apply func(tx *gorm.DB, index int) (Outcome, error)) {
+	failed := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for i := start; i < end; i++ {
+			if err := tx.SavePoint("bulk_item").Error; err != nil {
+				return err
+			}
+			outcome, err := apply(tx, i)
+			if err != nil {
+				if rbErr := tx.RollbackTo("bulk_item").Error; rbErr != nil {
+					return rbErr
+				}
+				r.Results[i].Error = itemError(err)
+				failed = true
+				continue
+			}
+			r.Results[i].Status = outcome.Status
+			r.Results[i].Success = true
+			r.Results[i].Data = outcome.Data
+		}
+		if atomic && failed {
+			return errRollback
+		}
+		return nil
+	})
+	if err == nil {
+		return
+	}
+
+	// Nothing in the chunk was kept
+	rolledBack := errors.Is(err, errRollback)
+	for i := start; i < end; i++ {
+		result := &r.Results[i]
+		if rolledBack && !result.Success {
+			continue // Keeps its own error
+		}
+		if !rolledBack && !result.Success && result.Error != nil {
+			continue
+		}
+		result.Success = false
+		result.Data = nil
+		if rolledBack {
+			result.Status = StatusRolledBack
+			result.Error = &Error{Code: "ROLLED_BACK", Message: "Not applied because another item in the atomic batch failed"}
+		} else {
+			result.Status = StatusFailed
+			result.Error = &Error{Code: "COMMIT_FAILED", Message: err.Error()}
+		}
+	}
+}
+
+// itemError turns an apply error into the error reported for its item
+func itemError(err error) *Error {
+	var itemErr *Error
+	if errors.As(err, &itemErr) {
+		return itemErr
+	}
+	return &Error{Code: "WRITE_FAILED", Message: err.Error()}
+}
+
+// HTTPStatus is the status code for the response: 200 when every item was applied or
+// skipped, 207 when some failed and 422 when none was applied
+func (r *Response) HTTPStatus() int {
+	switch {
+	case r.FailedCount == 0:
+		return http.StatusOK
+	case r.SuccessCount > 0:
+		return http.StatusMultiStatus
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+// Applied returns the data of the items that were created or updated, for side effects
+// once the batch has committed
+func (r *Response) Applied() []interface{} {
+	applied := make([]interface{}, 0, r.SuccessCount)
+	for _, result := range r.Results {
+		if result.Status == StatusCreated || result.Status == StatusUpdated {
+			applied = append(applied, result.Data)
+		}
+	}
+	return applied
+}
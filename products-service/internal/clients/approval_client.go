@@ -98,7 +98,8 @@ type ApprovalRequestResponse struct {
 
 // ApprovalRequestID contains the ID of the created approval
 type ApprovalRequestID struct {
-	ID string `json:"id"`
+	ID     string `json:"id"`
+	Status string `json:"status,omitempty"` // Set by approve/request-changes; "approved" once the whole chain has approved
 }
 
 // CheckApproval checks if an action requires approval
@@ -297,6 +298,45 @@ func (c *ApprovalClient) ApproveApprovalRequest(approvalID, tenantID, userID, us
 	return &approvalResp, nil
 }
 
+// RequestChanges sends an approval request back to the requester
+// Used when a reviewer records per-field change requests on a vendor product
+// Uses the internal endpoint which doesn't require RBAC permission
+func (c *ApprovalClient) RequestChanges(approvalID, tenantID, userID, userRole, userName, userEmail, comment string) (*ApprovalRequestResponse, error) {
+	reqBody := map[string]string{
+		"comment": comment,
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", c.baseURL+"/api/v1/approvals/"+approvalID+"/request-changes/internal", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	// Set Istio JWT claim headers for auth middleware
+	httpReq.Header.Set("x-jwt-claim-sub", userID)
+	httpReq.Header.Set("x-jwt-claim-tenant-id", tenantID)
+	httpReq.Header.Set("x-jwt-claim-email", userEmail)
+	httpReq.Header.Set("x-jwt-claim-name", userName)
+	httpReq.Header.Set("x-jwt-claim-roles", fmt.Sprintf("[\"%s\"]", userRole))
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call approval service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var approvalResp ApprovalRequestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&approvalResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &approvalResp, nil
+}
+
 // CanAutoApprove checks if the given role can auto-approve products/categories
 // Store owners and above can auto-approve their own creations
 // Case-insensitive matching for role names
@@ -0,0 +1,87 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// contentPageListLimit is the page size content-service allows when listing pages
+const contentPageListLimit = 100
+
+// ContentClient handles communication with the content-service
+type ContentClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// ContentPage is a published content page from content-service
+type ContentPage struct {
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	Slug         string     `json:"slug"`
+	RequiresAuth bool       `json:"requiresAuth"`
+	PublishedAt  *time.Time `json:"publishedAt,omitempty"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+type contentPageListResponse struct {
+	Success    bool          `json:"success"`
+	Data       []ContentPage `json:"data"`
+	Pagination *struct {
+		HasNext bool `json:"hasNext"`
+	} `json:"pagination,omitempty"`
+}
+
+// NewContentClient creates a new content client
+func NewContentClient() *ContentClient {
+	baseURL := os.Getenv("CONTENT_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://content-service:8080"
+	}
+
+	return &ContentClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// ListPublishedPages returns all of a tenant's published content pages
+func (c *ContentClient) ListPublishedPages(tenantID string) ([]ContentPage, error) {
+	var pages []ContentPage
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/api/v1/content-pages?status=PUBLISHED&page=%d&limit=%d", c.baseURL, page, contentPageListLimit)
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("X-Tenant-ID", tenantID)
+		req.Header.Set("X-Internal-Service", "products-service")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list content pages: %w", err)
+		}
+
+		var result contentPageListResponse
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("content-service returned status %d", resp.StatusCode)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode content pages: %w", err)
+		}
+
+		pages = append(pages, result.Data...)
+		if result.Pagination == nil || !result.Pagination.HasNext || len(result.Data) == 0 {
+			return pages, nil
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// MaxReviewStatsBatch is the most products reviews-service accepts in one stats request
+const MaxReviewStatsBatch = 500
+
+// ReviewsClient handles communication with the reviews-service
+type ReviewsClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// ProductReviewStats summarizes a product's approved reviews
+type ProductReviewStats struct {
+	ProductID     string          `json:"productId"`
+	ReviewCount   int             `json:"reviewCount"`
+	AverageRating float64         `json:"averageRating"`
+	ByRating      map[string]int  `json:"byRating"`
+	TopSnippets   []ReviewSnippet `json:"topSnippets,omitempty"`
+}
+
+// ReviewSnippet is a shortened review, most helpful first
+type ReviewSnippet struct {
+	ReviewID         string   `json:"reviewId"`
+	Title            *string  `json:"title,omitempty"`
+	Excerpt          string   `json:"excerpt"`
+	Rating           *float64 `json:"rating,omitempty"`
+	HelpfulCount     int      `json:"helpfulCount"`
+	VerifiedPurchase bool     `json:"verifiedPurchase"`
+}
+
+type productReviewStatsRequest struct {
+	ProductIDs []string `json:"productIds"`
+	Snippets   int      `json:"snippets"`
+}
+
+type productReviewStatsResponse struct {
+	Success bool                 `json:"success"`
+	Data    []ProductReviewStats `json:"data"`
+}
+
+// NewReviewsClient creates a new reviews client
+func NewReviewsClient() *ReviewsClient {
+	baseURL := os.Getenv("REVIEWS_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://reviews-service:8084"
+	}
+
+	return &ReviewsClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetProductReviewStats fetches review stats and up to snippets top reviews for each product,
+// keyed by product ID. Products without approved reviews are absent from the result.
+func (c *ReviewsClient) GetProductReviewStats(tenantID string, productIDs []string, snippets int) (map[string]ProductReviewStats, error) {
+	stats := make(map[string]ProductReviewStats, len(productIDs))
+	for start := 0; start < len(productIDs); start += MaxReviewStatsBatch {
+		end := start + MaxReviewStatsBatch
+		if end > len(productIDs) {
+			end = len(productIDs)
+		}
+
+		batch, err := c.getProductReviewStatsBatch(tenantID, productIDs[start:end], snippets)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range batch {
+			stats[s.ProductID] = s
+		}
+	}
+	return stats, nil
+}
+
+func (c *ReviewsClient) getProductReviewStatsBatch(tenantID string, productIDs []string, snippets int) ([]ProductReviewStats, error) {
+	url := fmt.Sprintf("%s/api/v1/reviews/stats/products", c.baseURL)
+
+	body, err := json.Marshal(productReviewStatsRequest{ProductIDs: productIDs, Snippets: snippets})
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("X-Tenant-ID", tenantID)
+	httpReq.Header.Set("X-Internal-Service", "products-service")
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get review stats: %d - %s", resp.StatusCode, string(respBody))
+	}
+
+	var result productReviewStatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
@@ -0,0 +1,110 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TenantClient looks up tenant slugs in tenant-service to build storefront URLs
+type TenantClient struct {
+	baseURL    string
+	baseDomain string
+	httpClient *http.Client
+	cacheTTL   time.Duration
+	mu         sync.RWMutex
+	slugs      map[string]tenantSlug
+}
+
+type tenantSlug struct {
+	slug      string
+	expiresAt time.Time
+}
+
+type tenantResponse struct {
+	Success bool `json:"success"`
+	Data    struct {
+		ID   string `json:"id"`
+		Slug string `json:"slug"`
+	} `json:"data"`
+}
+
+// NewTenantClient creates a new tenant client
+func NewTenantClient() *TenantClient {
+	baseURL := os.Getenv("TENANT_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://tenant-service.marketplace.svc.cluster.local:8080"
+	}
+
+	baseDomain := os.Getenv("BASE_DOMAIN")
+	if baseDomain == "" {
+		baseDomain = "tesserix.app"
+	}
+
+	return &TenantClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		baseDomain: baseDomain,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+		cacheTTL: 15 * time.Minute,
+		slugs:    make(map[string]tenantSlug),
+	}
+}
+
+// GetTenantSlug returns the tenant's slug, falling back to the tenant ID if tenant-service
+// can't be reached
+func (c *TenantClient) GetTenantSlug(tenantID string) string {
+	c.mu.RLock()
+	cached, ok := c.slugs[tenantID]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.slug
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/internal/tenants/%s", c.baseURL, tenantID), nil)
+	if err != nil {
+		log.Printf("[TENANT] Failed to create request: %v", err)
+		return tenantID
+	}
+	req.Header.Set("X-Internal-Service", "products-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		log.Printf("[TENANT] Failed to fetch tenant %s: %v", tenantID, err)
+		return tenantID
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("[TENANT] Non-200 response for tenant %s: %d", tenantID, resp.StatusCode)
+		return tenantID
+	}
+
+	var tenantResp tenantResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tenantResp); err != nil {
+		log.Printf("[TENANT] Failed to decode response: %v", err)
+		return tenantID
+	}
+
+	slug := tenantResp.Data.Slug
+	if slug == "" {
+		slug = tenantID
+	}
+
+	c.mu.Lock()
+	c.slugs[tenantID] = tenantSlug{slug: slug, expiresAt: time.Now().Add(c.cacheTTL)}
+	c.mu.Unlock()
+
+	return slug
+}
+
+// BuildStorefrontURL builds the tenant's storefront URL
+func (c *TenantClient) BuildStorefrontURL(tenantID string) string {
+	return fmt.Sprintf("https://%s.%s", c.GetTenantSlug(tenantID), c.baseDomain)
+}
@@ -6,9 +6,10 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets"
-	"products-service/internal/models"
+	"products-service/internal/tenancy"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -27,8 +28,9 @@ type Config struct {
 	RedisURL string
 
 	// Server
-	Port        string
-	Environment string
+	Port            string
+	Environment     string
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
 
 	// JWT
 	JWTSecret string
@@ -39,6 +41,13 @@ type Config struct {
 	SearchServiceURL   string
 	MLServiceURL       string
 
+	// Search index (optional; searches use PostgreSQL without one)
+	OpenSearchURL      string
+	OpenSearchIndex    string
+	OpenSearchUsername string
+	OpenSearchPassword string
+	SearchPriceRanges  []float64 // Upper bounds of the price range facet's buckets
+
 	// Multi-product support
 	ProductID string // Product identifier for document-service (e.g., "marketplace", "bookkeeping")
 
@@ -80,8 +89,9 @@ func Load() *Config {
 		RedisURL: getEnv("REDIS_URL", "redis://redis.redis-marketplace.svc.cluster.local:6379/0"),
 
 		// Server
-		Port:        getEnv("PORT", "8087"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Port:            getEnv("PORT", "8087"),
+		Environment:     getEnv("ENVIRONMENT", "development"),
+		ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
 
 		// JWT
 		JWTSecret: getEnv("JWT_SECRET", "your-secret-key"),
@@ -92,6 +102,13 @@ func Load() *Config {
 		SearchServiceURL:   getEnv("SEARCH_SERVICE_URL", "http://localhost:8092"),
 		MLServiceURL:       getEnv("ML_SERVICE_URL", "http://localhost:8090"),
 
+		// Search index
+		OpenSearchURL:      getEnv("OPENSEARCH_URL", ""),
+		OpenSearchIndex:    getEnv("OPENSEARCH_INDEX", "products"),
+		OpenSearchUsername: getEnv("OPENSEARCH_USERNAME", ""),
+		OpenSearchPassword: getEnv("OPENSEARCH_PASSWORD", ""),
+		SearchPriceRanges:  getEnvAsFloats("SEARCH_PRICE_RANGES"),
+
 		// Multi-product support - identifies this service to document-service
 		ProductID: getEnv("PRODUCT_ID", "marketplace"),
 
@@ -129,25 +146,10 @@ func InitDB(cfg *Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	// Auto-migrate models to keep schema up to date
-	// This will add missing columns but won't delete existing columns
-	// Note: Category is excluded because it has foreign key constraints that GORM doesn't handle well
-	log.Println("Running auto-migrations...")
-	if err := db.AutoMigrate(
-		&models.Product{},
-		&models.ProductVariant{},
-	); err != nil {
-		// Ignore errors about dropping non-existent constraints
-		// This can happen when schema was created without old constraints
-		// or when constraint naming conventions changed
-		errStr := err.Error()
-		if strings.Contains(errStr, "does not exist") && strings.Contains(errStr, "constraint") {
-			log.Printf("Note: Migration constraint warning (safe to ignore): %v", err)
-		} else {
-			return nil, fmt.Errorf("failed to run auto-migrations: %w", err)
-		}
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
 	}
-	log.Println("Auto-migrations completed successfully")
 
 	return db, nil
 }
@@ -157,4 +159,32 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsFloats parses a comma-separated list of ascending numbers, or returns nil if unset
+// or invalid
+func getEnvAsFloats(key string) []float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	var numbers []float64
+	for _, part := range strings.Split(value, ",") {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || (len(numbers) > 0 && number <= numbers[len(numbers)-1]) {
+			log.Printf("WARNING: Ignoring invalid %s %q", key, value)
+			return nil
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
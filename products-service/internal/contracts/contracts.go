@@ -0,0 +1,373 @@
+// Package contracts loads the consumer-driven contracts for internal endpoints and checks JSON
+// documents against their schemas. Consumers keep a contract per endpoint they call, under
+// contracts/<provider>/<name>.v<version>.json in the consumer's directory. The consumer's tests
+// run its client against the contract's examples; the provider's tests check its request and
+// response types against the contract's schemas.
+//
+// Schemas are a subset of JSON Schema: type (a name or a list of names, "null" marking nullable
+// values), format ("uuid", "date-time"), required, properties, items and enum. Properties a
+// schema doesn't list are allowed, so providers can add fields without breaking consumers.
+// x-go-type and x-go-name name the Go types and fields generated from a schema.
+//
+// This package is kept identical in every service that uses it; change all copies together.
+package contracts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Contract is what a consumer expects of one provider endpoint
+type Contract struct {
+	Consumer    string   `json:"consumer"`
+	Provider    string   `json:"provider"`
+	Name        string   `json:"name"`
+	Version     int      `json:"version"`
+	Description string   `json:"description"`
+	Request     Request  `json:"request"`
+	Response    Response `json:"response"`
+}
+
+// Request is the call the consumer makes. Path segments in braces, e.g. {id}, match any value.
+type Request struct {
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Schema  *Schema         `json:"schema,omitempty"`
+	Example json.RawMessage `json:"example,omitempty"`
+}
+
+// Response is what the consumer reads back
+type Response struct {
+	Status  int             `json:"status"`
+	Schema  *Schema         `json:"schema,omitempty"`
+	Example json.RawMessage `json:"example,omitempty"`
+}
+
+// ID identifies the contract in test output, e.g. products-service/inventory-check.v1
+func (c *Contract) ID() string {
+	return fmt.Sprintf("%s/%s.v%d", c.Provider, c.Name, c.Version)
+}
+
+// MatchesPath reports whether a request path fits the contract's path
+func (c *Contract) MatchesPath(requestPath string) bool {
+	want := strings.Split(strings.Trim(c.Request.Path, "/"), "/")
+	got := strings.Split(strings.Trim(requestPath, "/"), "/")
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if strings.HasPrefix(want[i], "{") && strings.HasSuffix(want[i], "}") {
+			if got[i] == "" {
+				return false
+			}
+			continue
+		}
+		if want[i] != got[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckExamples validates the contract's examples against its own schemas
+func (c *Contract) CheckExamples() error {
+	var errs []error
+	if c.Request.Schema != nil && len(c.Request.Example) > 0 {
+		if err := c.Request.Schema.Validate(c.Request.Example); err != nil {
+			errs = append(errs, fmt.Errorf("request example: %w", err))
+		}
+	}
+	if c.Response.Schema != nil && len(c.Response.Example) > 0 {
+		if err := c.Response.Schema.Validate(c.Response.Example); err != nil {
+			errs = append(errs, fmt.Errorf("response example: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// VerifyResponse checks that a provider value, marshalled as the provider's handler would send
+// it, gives the consumer everything its response schema expects
+func (c *Contract) VerifyResponse(v interface{}) error {
+	if c.Response.Schema == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return c.Response.Schema.Validate(data)
+}
+
+// DecodeRequest unmarshals the contract's request example into a provider's request type
+func (c *Contract) DecodeRequest(dst interface{}) error {
+	if len(c.Request.Example) == 0 {
+		return fmt.Errorf("%s has no request example", c.ID())
+	}
+	return json.Unmarshal(c.Request.Example, dst)
+}
+
+// Load reads every contract for provider from fsys, which holds one directory per provider.
+// An empty provider loads all of them.
+func Load(fsys fs.FS, provider string) ([]Contract, error) {
+	var contracts []Contract
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(name) != ".json" {
+			return nil
+		}
+		if provider != "" && path.Dir(name) != provider {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		var contract Contract
+		if err := json.Unmarshal(data, &contract); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if want := fmt.Sprintf("%s/%s.v%d.json", contract.Provider, contract.Name, contract.Version); name != want {
+			return fmt.Errorf("%s: contract for %s should be in %s", name, contract.ID(), want)
+		}
+		contracts = append(contracts, contract)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
+
+// Find returns the contract with the given provider and name at its latest version
+func Find(contracts []Contract, provider, name string) (*Contract, bool) {
+	var found *Contract
+	for i := range contracts {
+		c := &contracts[i]
+		if c.Provider == provider && c.Name == name && (found == nil || c.Version > found.Version) {
+			found = c
+		}
+	}
+	return found, found != nil
+}
+
+// Schema describes a JSON value
+type Schema struct {
+	Type        Types         `json:"type,omitempty"`
+	Format      string        `json:"format,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Required    []string      `json:"required,omitempty"`
+	Properties  Properties    `json:"properties,omitempty"`
+	Items       *Schema       `json:"items,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	GoType      string        `json:"x-go-type,omitempty"`
+	GoName      string        `json:"x-go-name,omitempty"`
+}
+
+// Types is a schema's type: one JSON type name, or several when the value may be null
+type Types []string
+
+// UnmarshalJSON accepts a single type name or a list of them
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("type must be a string or a list of strings")
+	}
+	*t = many
+	return nil
+}
+
+// Nullable reports whether null is allowed
+func (t Types) Nullable() bool {
+	return t.Has("null")
+}
+
+// Has reports whether the type list includes name
+func (t Types) Has(name string) bool {
+	for _, typ := range t {
+		if typ == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Main returns the non-null type
+func (t Types) Main() string {
+	for _, typ := range t {
+		if typ != "null" {
+			return typ
+		}
+	}
+	return ""
+}
+
+// Property is a named object property, kept in the order the schema lists it
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties are an object schema's properties in schema order
+type Properties []Property
+
+// UnmarshalJSON reads the properties object keeping its key order
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("properties must be an object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := tok.(string)
+		var schema Schema
+		if err := dec.Decode(&schema); err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+		*p = append(*p, Property{Name: name, Schema: &schema})
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// Get returns the named property's schema
+func (p Properties) Get(name string) (*Schema, bool) {
+	for _, prop := range p {
+		if prop.Name == name {
+			return prop.Schema, true
+		}
+	}
+	return nil, false
+}
+
+// IsRequired reports whether the object schema requires the named property
+func (s *Schema) IsRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks a JSON document against the schema, reporting every mismatch with its path
+func (s *Schema) Validate(data []byte) error {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	var problems []string
+	s.validate("$", doc, &problems)
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errors.New(strings.Join(problems, "; "))
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func (s *Schema) validate(at string, v interface{}, problems *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	if v == nil {
+		if len(s.Type) > 0 && !s.Type.Nullable() {
+			fail("is null, want %s", s.Type.Main())
+		}
+		return
+	}
+	if len(s.Type) > 0 && !s.Type.Has(jsonType(v)) &&
+		!(s.Type.Has("number") && jsonType(v) == "integer") {
+		fail("is %s, want %s", jsonType(v), strings.Join(s.Type, " or "))
+		return
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, allowed := range s.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("%v is not one of %v", v, s.Enum)
+		}
+	}
+
+	switch value := v.(type) {
+	case string:
+		switch s.Format {
+		case "uuid":
+			if !uuidPattern.MatchString(value) {
+				fail("%q is not a uuid", value)
+			}
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				fail("%q is not an RFC 3339 date-time", value)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		for _, prop := range s.Properties {
+			if field, ok := value[prop.Name]; ok {
+				prop.Schema.validate(at+"."+prop.Name, field, problems)
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range value {
+				s.Items.validate(fmt.Sprintf("%s[%d]", at, i), item, problems)
+			}
+		}
+	}
+}
+
+func jsonType(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if value == float64(int64(value)) {
+			return "integer"
+		}
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", v)
+}
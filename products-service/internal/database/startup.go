@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Config holds how the service connects to and migrates its database at startup
+type Config struct {
+	// Dir is the directory of versioned SQL migrations
+	Dir string
+	// OnStartup applies pending migrations before serving. Off in production by default, where
+	// the "migrate" subcommand runs them as an init container and replicas only wait for the schema.
+	OnStartup bool
+	// SyncModels re-syncs the model schema whenever migrations run at startup (development)
+	SyncModels bool
+	// ConnectTimeout is how long startup keeps retrying an unreachable database before giving up
+	ConnectTimeout time.Duration
+	// WaitTimeout is how long a replica waits for the schema (or the migration lock) at startup
+	WaitTimeout time.Duration
+}
+
+// ConfigFromEnv reads the configuration from MIGRATIONS_DIR (default migrations),
+// MIGRATE_ON_STARTUP (default true outside production), DB_WAIT_TIMEOUT_SECONDS (default 60)
+// and MIGRATION_WAIT_TIMEOUT_SECONDS (default 300)
+func ConfigFromEnv(production bool) Config {
+	cfg := Config{
+		Dir:            "migrations",
+		OnStartup:      !production,
+		SyncModels:     !production,
+		ConnectTimeout: 60 * time.Second,
+		WaitTimeout:    300 * time.Second,
+	}
+	if dir := os.Getenv("MIGRATIONS_DIR"); dir != "" {
+		cfg.Dir = dir
+	}
+	if onStartup, err := strconv.ParseBool(os.Getenv("MIGRATE_ON_STARTUP")); err == nil {
+		cfg.OnStartup = onStartup
+	}
+	if seconds, err := strconv.Atoi(os.Getenv("DB_WAIT_TIMEOUT_SECONDS")); err == nil && seconds > 0 {
+		cfg.ConnectTimeout = time.Duration(seconds) * time.Second
+	}
+	if seconds, err := strconv.Atoi(os.Getenv("MIGRATION_WAIT_TIMEOUT_SECONDS")); err == nil && seconds > 0 {
+		cfg.WaitTimeout = time.Duration(seconds) * time.Second
+	}
+	return cfg
+}
+
+// Connect opens the database with open and pings it, retrying with backoff for up to
+// ConnectTimeout, so a pod that starts before Postgres is reachable waits instead of crash-looping
+func Connect(cfg Config, open func() (*gorm.DB, error)) (*gorm.DB, error) {
+	deadline := time.Now().Add(cfg.ConnectTimeout)
+	backoff := time.Second
+	for {
+		db, err := open()
+		if err == nil {
+			if err = ping(db); err == nil {
+				return db, nil
+			}
+			closeDB(db)
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return nil, fmt.Errorf("database not reachable after %s: %w", cfg.ConnectTimeout, err)
+		}
+		log.Printf("Database not reachable, retrying in %s: %v", backoff, err)
+		time.Sleep(backoff)
+		if backoff < 10*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// Prepare gates startup on the schema: it applies pending migrations when OnStartup is set, and
+// otherwise waits for the migrate init container to bring the schema to the latest version
+func Prepare(db *gorm.DB, cfg Config) error {
+	migrator := NewMigrator(db, cfg.Dir)
+	if !cfg.OnStartup {
+		return migrator.WaitForSchema(context.Background(), cfg.WaitTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.WaitTimeout)
+	defer cancel()
+	_, err := migrator.Migrate(ctx, cfg.SyncModels)
+	return err
+}
+
+// RunCommand runs the "migrate" subcommand: with "status" it lists the migrations, otherwise it
+// applies pending ones. Used as the init container of every replica.
+func RunCommand(db *gorm.DB, cfg Config, args []string) error {
+	migrator := NewMigrator(db, cfg.Dir)
+
+	if len(args) > 0 && args[0] == "status" {
+		statuses, err := migrator.Status(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to read migration status: %w", err)
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			if status.ChecksumMismatch {
+				state += " (file changed since applied)"
+			}
+			fmt.Printf("%03d  %-45s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.WaitTimeout)
+	defer cancel()
+	applied, err := migrator.Migrate(ctx, false)
+	if err != nil {
+		return err
+	}
+	log.Printf("Database is up to date (%d migration(s) applied)", applied)
+	return nil
+}
+
+func ping(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+func closeDB(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
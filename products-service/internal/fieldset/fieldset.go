@@ -0,0 +1,256 @@
+// Package fieldset implements sparse fieldsets and relation expansion for GET endpoints.
+// Clients name the fields they need with ?fields=id,status,items.sku and the relations to
+// expand with ?include=items,customer; the response is pruned to match, and handlers pass
+// the requested relations on to the repository so relations nobody asked for aren't loaded.
+// A request with neither parameter gets the full response, as before.
+//
+// It is kept identical in every service that uses it; change all copies together.
+package fieldset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxFields bounds the number of fields a request may name
+	maxFields = 100
+	// maxDepth bounds how deeply a field path may reach into nested objects
+	maxDepth = 4
+)
+
+// alwaysKept are fields returned whenever an object is pruned, so clients can key rows
+var alwaysKept = []string{"id"}
+
+// node is one level of requested fields. A nil node keeps the whole value.
+type node map[string]node
+
+// Selection is the fields and relations requested for a response
+type Selection struct {
+	fields    node
+	includes  map[string]bool
+	relations []string
+}
+
+// Parse reads ?fields= and ?include= from the request. relations are the JSON names of the
+// relations the endpoint can expand; including anything else is an error.
+func Parse(c *gin.Context, relations ...string) (*Selection, error) {
+	s := &Selection{relations: relations}
+
+	if raw := strings.TrimSpace(c.Query("include")); raw != "" {
+		known := make(map[string]bool, len(relations))
+		for _, relation := range relations {
+			known[relation] = true
+		}
+		s.includes = make(map[string]bool)
+		for _, relation := range strings.Split(raw, ",") {
+			relation = strings.TrimSpace(relation)
+			if relation == "" {
+				continue
+			}
+			if !known[relation] {
+				if len(relations) == 0 {
+					return nil, fmt.Errorf("include is not supported on this endpoint")
+				}
+				return nil, fmt.Errorf("unknown include %q; expected one of %s", relation, strings.Join(relations, ", "))
+			}
+			s.includes[relation] = true
+		}
+	}
+
+	if raw := strings.TrimSpace(c.Query("fields")); raw != "" {
+		paths := strings.Split(raw, ",")
+		if len(paths) > maxFields {
+			return nil, fmt.Errorf("at most %d fields may be requested", maxFields)
+		}
+		s.fields = node{}
+		for _, path := range paths {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+			segments := strings.Split(path, ".")
+			if len(segments) > maxDepth {
+				return nil, fmt.Errorf("field %q is nested more than %d levels deep", path, maxDepth)
+			}
+			for _, segment := range segments {
+				if segment == "" {
+					return nil, fmt.Errorf("invalid field %q", path)
+				}
+			}
+			s.fields.add(segments)
+		}
+	}
+
+	return s, nil
+}
+
+// add records a field path. Asking for a whole object wins over asking for parts of it.
+func (n node) add(segments []string) {
+	child, seen := n[segments[0]]
+	if len(segments) == 1 {
+		n[segments[0]] = nil
+		return
+	}
+	if seen && child == nil {
+		return
+	}
+	if child == nil {
+		child = node{}
+		n[segments[0]] = child
+	}
+	child.add(segments[1:])
+}
+
+// Active reports whether the request asked for fields or relations at all
+func (s *Selection) Active() bool {
+	return s.fields != nil || s.includes != nil
+}
+
+// Includes reports whether the request asked for a relation, through ?include= or by naming
+// it or one of its fields in ?fields=
+func (s *Selection) Includes(relation string) bool {
+	if s.includes[relation] {
+		return true
+	}
+	_, named := s.fields[relation]
+	return named
+}
+
+// Relations returns the relations the request asked for, in the order the endpoint listed
+// them
+func (s *Selection) Relations() []string {
+	requested := []string{}
+	for _, relation := range s.relations {
+		if s.Includes(relation) {
+			requested = append(requested, relation)
+		}
+	}
+	return requested
+}
+
+// Filter prunes a response object, or a list of them, to the selection. Without a selection
+// the value is returned unchanged.
+func (s *Selection) Filter(v interface{}) (interface{}, error) {
+	if !s.Active() {
+		return v, nil
+	}
+	decoded, err := decode(v)
+	if err != nil {
+		return nil, err
+	}
+	return s.prune(decoded), nil
+}
+
+// FilterIn prunes the list or object under key of a response envelope, leaving the rest of
+// the envelope (totals, pagination) as it is
+func (s *Selection) FilterIn(envelope interface{}, key string) (interface{}, error) {
+	if !s.Active() {
+		return envelope, nil
+	}
+	decoded, err := decode(envelope)
+	if err != nil {
+		return nil, err
+	}
+	object, ok := decoded.(map[string]interface{})
+	if !ok {
+		return decoded, nil
+	}
+	if value, ok := object[key]; ok {
+		object[key] = s.prune(value)
+	}
+	return object, nil
+}
+
+// prune applies the selection to the top level of a response
+func (s *Selection) prune(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = s.prune(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		for _, relation := range s.relations {
+			if _, present := v[relation]; present && !s.Includes(relation) {
+				delete(v, relation)
+			}
+		}
+		if s.fields == nil {
+			return v
+		}
+		for key := range v {
+			if s.includes[key] {
+				continue
+			}
+			if _, wanted := s.fields[key]; !wanted && !isAlwaysKept(key) {
+				delete(v, key)
+			}
+		}
+		for key, child := range s.fields {
+			if child != nil && !s.includes[key] {
+				if nested, ok := v[key]; ok {
+					v[key] = child.prune(nested)
+				}
+			}
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// prune keeps only the requested fields of a nested object or list of objects
+func (n node) prune(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = n.prune(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		for key := range v {
+			if _, wanted := n[key]; !wanted && !isAlwaysKept(key) {
+				delete(v, key)
+			}
+		}
+		for key, child := range n {
+			if child != nil {
+				if nested, ok := v[key]; ok {
+					v[key] = child.prune(nested)
+				}
+			}
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func isAlwaysKept(key string) bool {
+	for _, kept := range alwaysKept {
+		if key == kept {
+			return true
+		}
+	}
+	return false
+}
+
+// decode turns a response into generic JSON values, keeping numbers exactly as encoded
+func decode(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
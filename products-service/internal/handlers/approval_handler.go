@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"products-service/internal/clients"
@@ -355,7 +356,7 @@ func (h *ApprovalProductsHandler) executePriceUpdate(c *gin.Context, tenantID st
 		Price: newPrice,
 	}
 
-	if err := h.repo.UpdateProduct(tenantID, productID, updates); err != nil {
+	if err := h.repo.UpdateProduct(tenantID, productID, updates, nil); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
@@ -423,7 +424,7 @@ func (h *ApprovalProductsHandler) HandleApprovalCallback(c *gin.Context) {
 		case string(clients.ApprovalTypePriceChange):
 			h.executeApprovedPriceChange(c, tenantIDStr, callback.ActionData)
 		case string(clients.ApprovalTypeProductCreate):
-			h.executeApprovedProductPublish(c, tenantIDStr, callback.ActionData)
+			h.executeApprovedProductPublish(c, tenantIDStr, callback.ApprovalID, callback.ActionData)
 		default:
 			c.JSON(http.StatusBadRequest, models.ErrorResponse{
 				Success: false,
@@ -555,10 +556,11 @@ func (h *ApprovalProductsHandler) executeApprovedPriceChange(c *gin.Context, ten
 	h.executePriceUpdate(c, tenantID, productID, newPriceStr)
 }
 
-// SubmitProductForApproval submits an existing draft product for approval
+// SubmitProductForApproval submits a draft, rejected or changes-requested product for approval
 // POST /api/v1/products/:id/submit-for-approval
 // If the user has a high-privilege role (store_owner, owner, admin, super_admin),
-// the product will be auto-approved and published immediately.
+// the product will be auto-approved and published immediately. Vendor users are never
+// auto-approved; their products wait in the review queue.
 func (h *ApprovalProductsHandler) SubmitProductForApproval(c *gin.Context) {
 	tenantID, _ := c.Get("tenant_id")
 	tenantIDStr := tenantID.(string)
@@ -606,13 +608,28 @@ func (h *ApprovalProductsHandler) SubmitProductForApproval(c *gin.Context) {
 		return
 	}
 
-	// Check if product is in draft status
-	if product.Status != models.ProductStatusDraft {
+	// Vendors can only submit their own products
+	vendorScope := gosharedmw.GetVendorScopeFilter(c)
+	if vendorScope != "" && product.VendorID != vendorScope {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Product not found",
+			},
+		})
+		return
+	}
+
+	// Drafts and products sent back by a reviewer can be (re)submitted
+	switch product.Status {
+	case models.ProductStatusDraft, models.ProductStatusRejected, models.ProductStatusChangesRequested:
+	default:
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
 				Code:    "INVALID_STATUS",
-				Message: "Only draft products can be submitted for approval",
+				Message: "Only draft, rejected or changes-requested products can be submitted for approval",
 			},
 		})
 		return
@@ -641,7 +658,7 @@ func (h *ApprovalProductsHandler) SubmitProductForApproval(c *gin.Context) {
 		approvalID := approvalResp.Data.ID
 
 		// Check if user can auto-approve (store_owner, owner, admin, super_admin)
-		if clients.CanAutoApprove(userRole) {
+		if vendorScope == "" && clients.CanAutoApprove(userRole) {
 			// Auto-approve the request
 			autoApproveResp, autoApproveErr := h.approvalClient.ApproveApprovalRequest(
 				approvalID,
@@ -681,8 +698,8 @@ func (h *ApprovalProductsHandler) SubmitProductForApproval(c *gin.Context) {
 			fmt.Printf("Auto-approval failed for product %s: %v\n", product.ID.String(), autoApproveErr)
 		}
 
-		// Update product status to PENDING (manual approval required)
-		if err := h.repo.UpdateProductStatus(tenantIDStr, productID, models.ProductStatusPending, nil); err != nil {
+		// Update product status to PENDING (manual approval required) and resolve open change requests
+		if err := h.repo.SubmitForReview(tenantIDStr, productID, approvalID); err != nil {
 			// Log error but don't fail - approval was created successfully
 			c.JSON(http.StatusAccepted, gin.H{
 				"success":     true,
@@ -721,7 +738,7 @@ func (h *ApprovalProductsHandler) SubmitProductForApproval(c *gin.Context) {
 }
 
 // executeApprovedProductPublish executes an approved product publication (PENDING -> ACTIVE)
-func (h *ApprovalProductsHandler) executeApprovedProductPublish(c *gin.Context, tenantID string, actionData map[string]any) {
+func (h *ApprovalProductsHandler) executeApprovedProductPublish(c *gin.Context, tenantID, approvalID string, actionData map[string]any) {
 	productIDStr, ok := actionData["product_id"].(string)
 	if !ok {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
@@ -771,8 +788,9 @@ func (h *ApprovalProductsHandler) executeApprovedProductPublish(c *gin.Context,
 		return
 	}
 
-	// Update product status to ACTIVE
-	if err := h.repo.UpdateProductStatus(tenantID, productID, models.ProductStatusActive, nil); err != nil {
+	// Update product status to ACTIVE, unless it has since moved on to a newer approval request
+	published, err := h.repo.PublishApproved(tenantID, productID, approvalID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
@@ -782,6 +800,16 @@ func (h *ApprovalProductsHandler) executeApprovedProductPublish(c *gin.Context,
 		})
 		return
 	}
+	if !published {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "STALE_APPROVAL",
+				Message: "Product is pending a newer approval request",
+			},
+		})
+		return
+	}
 
 	// Get updated product
 	updatedProduct, _ := h.repo.GetProductByID(tenantID, productID, false)
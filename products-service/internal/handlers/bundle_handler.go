@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"products-service/internal/middleware"
+	"products-service/internal/models"
+	"products-service/internal/repository"
+)
+
+// GetBundle retrieves a product's bundle components and its current price
+// GET /api/v1/products/:id/bundle
+func (h *ProductsHandler) GetBundle(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	productID, ok := parseBundleProductID(c)
+	if !ok {
+		return
+	}
+
+	bundle, price, err := h.repo.GetBundle(tenantID.(string), productID)
+	if err != nil {
+		respondBundleError(c, err, "Product is not a bundle", "FETCH_FAILED", "Failed to retrieve bundle")
+		return
+	}
+
+	hideBundleCosts(c, bundle)
+	c.JSON(http.StatusOK, models.BundleResponse{
+		Success: true,
+		Data:    bundle,
+		Price:   price,
+	})
+}
+
+// CreateBundle makes a product a bundle of other products
+// POST /api/v1/products/:id/bundle
+func (h *ProductsHandler) CreateBundle(c *gin.Context) {
+	h.saveBundle(c, false)
+}
+
+// UpdateBundle replaces a bundle's pricing and components
+// PUT /api/v1/products/:id/bundle
+func (h *ProductsHandler) UpdateBundle(c *gin.Context) {
+	h.saveBundle(c, true)
+}
+
+func (h *ProductsHandler) saveBundle(c *gin.Context, replace bool) {
+	tenantID, _ := c.Get("tenant_id")
+
+	productID, ok := parseBundleProductID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SaveBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	if message := req.Validate(productID); message != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_BUNDLE",
+				Message: message,
+			},
+		})
+		return
+	}
+
+	bundleToSave := req.Bundle(tenantID.(string), productID)
+	bundle, price, err := h.repo.SaveBundle(&bundleToSave, replace)
+	if err != nil {
+		notFound := "Product not found"
+		if replace {
+			notFound = "Product not found or not a bundle; use POST to create one"
+		}
+		respondBundleError(c, err, notFound, "SAVE_FAILED", "Failed to save bundle")
+		return
+	}
+
+	// Components pricing rewrites the product's price; announce it like any other price change
+	if bundle.Pricing == models.BundlePricingComponents && h.eventsPublisher != nil {
+		if product, err := h.repo.GetProductByID(tenantID.(string), productID, false); err == nil {
+			actor := gosharedmw.GetActorInfo(c)
+			_ = h.eventsPublisher.PublishProductUpdated(c.Request.Context(), product, nil, []string{"price"}, tenantID.(string), actor.ActorID, actor.ActorName, actor.ActorEmail, actor.ClientIP, actor.UserAgent)
+		}
+	}
+
+	status := http.StatusCreated
+	if replace {
+		status = http.StatusOK
+	}
+	hideBundleCosts(c, bundle)
+	c.JSON(status, models.BundleResponse{
+		Success: true,
+		Data:    bundle,
+		Price:   price,
+	})
+}
+
+// DeleteBundle turns a bundle back into an ordinary product
+// DELETE /api/v1/products/:id/bundle
+func (h *ProductsHandler) DeleteBundle(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	productID, ok := parseBundleProductID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteBundle(tenantID.(string), productID); err != nil {
+		respondBundleError(c, err, "Product is not a bundle", "DELETE_FAILED", "Failed to delete bundle")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Bundle deleted successfully",
+	})
+}
+
+func parseBundleProductID(c *gin.Context) (uuid.UUID, bool) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid product ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return productID, true
+}
+
+func respondBundleError(c *gin.Context, err error, notFound, code, message string) {
+	var bundleErr *repository.BundleError
+	switch {
+	case errors.As(err, &bundleErr):
+		status := http.StatusBadRequest
+		if bundleErr.Code == "BUNDLE_EXISTS" {
+			status = http.StatusConflict
+		}
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    bundleErr.Code,
+				Message: bundleErr.Message,
+			},
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: notFound,
+			},
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    code,
+				Message: message,
+			},
+		})
+	}
+}
+
+// hideBundleCosts clears the components' cost prices if the caller may not see costs
+func hideBundleCosts(c *gin.Context, bundle *models.ProductBundle) {
+	if bundle == nil || middleware.CanViewCosts(c) {
+		return
+	}
+	for _, component := range bundle.Components {
+		hideProductCost(c, component.Product)
+	}
+}
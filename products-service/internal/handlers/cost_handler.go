@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"products-service/internal/middleware"
+	"products-service/internal/models"
+)
+
+// hideProductCosts clears cost prices from products the caller may not see costs for
+func hideProductCosts(c *gin.Context, products []models.Product) {
+	if middleware.CanViewCosts(c) {
+		return
+	}
+	for i := range products {
+		products[i].HideCosts()
+	}
+}
+
+// hideProductCost clears a product's cost prices if the caller may not see costs
+func hideProductCost(c *gin.Context, product *models.Product) {
+	if product != nil && !middleware.CanViewCosts(c) {
+		product.HideCosts()
+	}
+}
+
+// hideVariantCosts clears cost prices from variants the caller may not see costs for
+func hideVariantCosts(c *gin.Context, variants []models.ProductVariant) {
+	if middleware.CanViewCosts(c) {
+		return
+	}
+	for i := range variants {
+		variants[i].CostPrice = nil
+	}
+}
+
+// GetCostHistory retrieves a product's cost price changes, including its variants'
+// GET /api/v1/products/:id/cost-history
+func (h *ProductsHandler) GetCostHistory(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid product ID format",
+			},
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	history, total, err := h.repo.ListCostHistory(tenantID.(string), productID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve cost history",
+			},
+		})
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	c.JSON(http.StatusOK, models.CostHistoryResponse{
+		Success: true,
+		Data:    history,
+		Pagination: &models.PaginationInfo{
+			Page:        page,
+			Limit:       limit,
+			Total:       total,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	})
+}
+
+// GetMarginReport retrieves price, cost and gross margin per product and variant, with
+// totals across the catalog
+// GET /api/v1/products/margins
+func (h *ProductsHandler) GetMarginReport(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	report, total, err := h.repo.GetMarginReport(tenantID.(string), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve margin report",
+			},
+		})
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	c.JSON(http.StatusOK, models.MarginReportResponse{
+		Success: true,
+		Data:    report,
+		Pagination: &models.PaginationInfo{
+			Page:        page,
+			Limit:       limit,
+			Total:       total,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	})
+}
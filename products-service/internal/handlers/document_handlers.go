@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"path"
 	"strings"
 	"time"
 
@@ -16,6 +19,15 @@ import (
 	"products-service/internal/models"
 )
 
+// maxSideloadImageSize caps a single image downloaded by SideloadProductImage
+const maxSideloadImageSize = 20 << 20
+
+// productImageTypes are the image types accepted as product images
+var productImageTypes = map[string]bool{
+	"image/jpeg": true, "image/jpg": true, "image/png": true,
+	"image/gif": true, "image/webp": true,
+}
+
 type DocumentHandler struct {
 	documentServiceURL string
 	productID          string
@@ -127,11 +139,7 @@ func (h *DocumentHandler) UploadProductImage(c *gin.Context) {
 	// Validate image file type using exact MIME matching
 	contentType := header.Header.Get("Content-Type")
 	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
-	allowedImageTypes := map[string]bool{
-		"image/jpeg": true, "image/jpg": true, "image/png": true,
-		"image/gif": true, "image/webp": true,
-	}
-	if !allowedImageTypes[mediaType] {
+	if !productImageTypes[mediaType] {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
@@ -242,6 +250,98 @@ func (h *DocumentHandler) UploadProductImage(c *gin.Context) {
 	c.Data(resp.StatusCode, "application/json", respBody)
 }
 
+// SideloadProductImage downloads an image from sourceURL and stores it in the product image
+// bucket, returning the hosted image. Imports use it so products don't depend on the source
+// host staying up; there's no user token during an import job, so it calls as the service.
+func (h *DocumentHandler) SideloadProductImage(ctx context.Context, tenantID, sku, sourceURL string) (*models.ProductImage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image URL: %w", err)
+	}
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image download returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSideloadImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) > maxSideloadImageSize {
+		return nil, fmt.Errorf("image exceeds %dMB", maxSideloadImageSize>>20)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !productImageTypes[contentType] {
+		contentType = http.DetectContentType(data)
+	}
+	if !productImageTypes[contentType] {
+		return nil, fmt.Errorf("unsupported image type %q (JPEG, PNG, GIF, WebP)", contentType)
+	}
+
+	filename := path.Base(req.URL.Path)
+	if filename == "" || filename == "/" || filename == "." {
+		filename = "product-image"
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("bucket", "product-images")
+	writer.WriteField("isPublic", "true")
+	writer.WriteField("tags", fmt.Sprintf("sku:%s,tenant_id:%s,image_type:gallery,source:import", sku, tenantID))
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to write form: %w", err)
+	}
+	writer.Close()
+
+	docReq, err := http.NewRequestWithContext(ctx, http.MethodPost, h.documentServiceURL+"/api/v1/documents/upload", &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload request: %w", err)
+	}
+	docReq.Header.Set("Content-Type", writer.FormDataContentType())
+	docReq.Header.Set("X-Tenant-ID", tenantID)
+	docReq.Header.Set("X-Product-ID", h.productID)
+	docReq.Header.Set("X-Internal-Service", "products-service")
+
+	docResp, err := h.httpClient.Do(docReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to communicate with document service: %w", err)
+	}
+	defer docResp.Body.Close()
+	if docResp.StatusCode < 200 || docResp.StatusCode >= 300 {
+		return nil, fmt.Errorf("document service returned status %d", docResp.StatusCode)
+	}
+
+	// Document service returns the document object directly, not wrapped
+	var doc struct {
+		ID     string `json:"id"`
+		URL    string `json:"url"`
+		Width  *int   `json:"width"`
+		Height *int   `json:"height"`
+	}
+	if err := json.NewDecoder(docResp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document response: %w", err)
+	}
+	if doc.URL == "" {
+		return nil, fmt.Errorf("document response missing URL")
+	}
+
+	return &models.ProductImage{
+		ID:     doc.ID,
+		URL:    doc.URL,
+		Width:  doc.Width,
+		Height: doc.Height,
+	}, nil
+}
+
 // GetProductImages retrieves images for a product
 // @Summary Get images for product
 // @Description Get a list of images associated with a product
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"products-service/internal/bulk"
 	"products-service/internal/clients"
 	"products-service/internal/models"
 	"products-service/internal/repository"
@@ -29,11 +31,17 @@ type ImportHandler struct {
 	inventoryClient  *clients.InventoryClient
 	categoriesClient *clients.CategoriesClient
 	vendorClient     *clients.VendorClient
+	jobRepo          *repository.ImportJobRepository
+	mappingRepo      *repository.ImportMappingRepository
+	media            *DocumentHandler
 }
 
-func NewImportHandler(repo *repository.ProductsRepository, inventoryClient *clients.InventoryClient, categoriesClient *clients.CategoriesClient, vendorClient *clients.VendorClient) *ImportHandler {
+func NewImportHandler(repo *repository.ProductsRepository, jobRepo *repository.ImportJobRepository, mappingRepo *repository.ImportMappingRepository, media *DocumentHandler, inventoryClient *clients.InventoryClient, categoriesClient *clients.CategoriesClient, vendorClient *clients.VendorClient) *ImportHandler {
 	return &ImportHandler{
 		repo:             repo,
+		jobRepo:          jobRepo,
+		mappingRepo:      mappingRepo,
+		media:            media,
 		inventoryClient:  inventoryClient,
 		categoriesClient: categoriesClient,
 		vendorClient:     vendorClient,
@@ -176,7 +184,8 @@ func (h *ImportHandler) generateXLSXTemplate(c *gin.Context, template models.Imp
 
 // ImportProducts imports products from CSV or Excel file with enterprise-grade batch processing
 // POST /api/v1/products/import
-// Supports large file imports with configurable batch sizes, retry logic, and partial commits
+// Supports large file imports with configurable batch sizes, retry logic, and partial commits.
+// Files over AsyncImportThreshold, or requests with async=true, are queued as an import job instead.
 func (h *ImportHandler) ImportProducts(c *gin.Context) {
 	// Use IstioAuth context keys
 	tenantID, _ := c.Get("tenant_id")
@@ -200,38 +209,17 @@ func (h *ImportHandler) ImportProducts(c *gin.Context) {
 
 	// Get import options
 	skipDuplicates := c.DefaultPostForm("skipDuplicates", "false") == "true"
-	updateExisting := c.DefaultPostForm("updateExisting", "false") == "true"
+	updateExisting := c.DefaultPostForm("updateExisting", "false") == "true" ||
+		c.DefaultPostForm("mode", "") == string(models.ImportModeUpsert)
 	validateOnly := c.DefaultPostForm("validateOnly", "false") == "true"
 
 	// Get batch processing options
-	batchSize := DefaultBatchSize
-	if bs := c.DefaultPostForm("batchSize", ""); bs != "" {
-		if parsed, err := strconv.Atoi(bs); err == nil && parsed > 0 {
-			batchSize = parsed
-			if batchSize > MaxBatchSize {
-				batchSize = MaxBatchSize
-			}
-		}
-	}
-
-	maxRetries := DefaultRetries
-	if mr := c.DefaultPostForm("maxRetries", ""); mr != "" {
-		if parsed, err := strconv.Atoi(mr); err == nil && parsed >= 0 {
-			maxRetries = parsed
-			if maxRetries > MaxRetries {
-				maxRetries = MaxRetries
-			}
-		}
-	}
+	batchSize := parseBatchSize(c.DefaultPostForm("batchSize", ""))
+	maxRetries := parseMaxRetries(c.DefaultPostForm("maxRetries", ""))
 
 	// Determine file format
-	filename := header.Filename
-	var format models.ImportFormat
-	if strings.HasSuffix(strings.ToLower(filename), ".csv") {
-		format = models.ImportFormatCSV
-	} else if strings.HasSuffix(strings.ToLower(filename), ".xlsx") {
-		format = models.ImportFormatXLSX
-	} else {
+	format, ok := detectImportFormat(header.Filename)
+	if !ok {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
@@ -242,6 +230,12 @@ func (h *ImportHandler) ImportProducts(c *gin.Context) {
 		return
 	}
 
+	// Large files would time out the request; hand them to the import worker
+	if !validateOnly && (c.DefaultPostForm("async", "false") == "true" || header.Size > AsyncImportThreshold) {
+		h.enqueueImportJob(c, file, header, format)
+		return
+	}
+
 	// Parse file
 	var rows []map[string]string
 	var parseErr error
@@ -274,6 +268,20 @@ func (h *ImportHandler) ImportProducts(c *gin.Context) {
 		return
 	}
 
+	// Apply the column mapping (explicit or saved preset) before validation
+	mapping, err := h.resolveColumnMapping(c, tenantID.(string), "products")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_MAPPING",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	rows = applyColumnMapping(rows, mapping)
+
 	// Process import with batch processing for enterprise-grade performance
 	userEmailStr := ""
 	if userEmail != nil {
@@ -300,6 +308,30 @@ func (h *ImportHandler) ImportProducts(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// parseBatchSize parses the batchSize option, clamped to MaxBatchSize
+func parseBatchSize(value string) int {
+	batchSize := DefaultBatchSize
+	if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+		batchSize = parsed
+		if batchSize > MaxBatchSize {
+			batchSize = MaxBatchSize
+		}
+	}
+	return batchSize
+}
+
+// parseMaxRetries parses the maxRetries option, clamped to MaxRetries
+func parseMaxRetries(value string) int {
+	maxRetries := DefaultRetries
+	if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+		maxRetries = parsed
+		if maxRetries > MaxRetries {
+			maxRetries = MaxRetries
+		}
+	}
+	return maxRetries
+}
+
 // processImportWithBatching handles large imports with batch processing, retry logic, and partial commits
 func (h *ImportHandler) processImportWithBatching(
 	tenantID, userID, userEmail string,
@@ -356,6 +388,7 @@ func (h *ImportHandler) processImportWithBatching(
 		result.FailedCount += batchResult.FailedCount
 		result.SkippedCount += batchResult.SkippedCount
 		result.Errors = append(result.Errors, batchResult.Errors...)
+		result.Warnings = append(result.Warnings, batchResult.Warnings...)
 		result.CreatedIDs = append(result.CreatedIDs, batchResult.CreatedIDs...)
 		result.UpdatedIDs = append(result.UpdatedIDs, batchResult.UpdatedIDs...)
 	}
@@ -408,6 +441,7 @@ func (h *ImportHandler) processBatchWithRetry(
 		batchResult.FailedCount = innerResult.FailedCount
 		batchResult.SkippedCount = innerResult.SkippedCount
 		batchResult.Errors = innerResult.Errors
+		batchResult.Warnings = innerResult.Warnings
 		batchResult.CreatedIDs = innerResult.CreatedIDs
 		batchResult.UpdatedIDs = innerResult.UpdatedIDs
 		batchResult.Success = innerResult.Success
@@ -455,6 +489,7 @@ func (h *ImportHandler) processSingleBatch(
 	}
 
 	products := make([]*models.Product, 0, len(rows))
+	productRows := make([]int, 0, len(rows))
 
 	for _, row := range rows {
 		rowNum, _ := strconv.Atoi(row["_row"])
@@ -470,6 +505,12 @@ func (h *ImportHandler) processSingleBatch(
 			h.addError(result, rowNum, "vendor", "REQUIRED", "Either 'vendorId' or 'vendorName' is required")
 		}
 
+		// Image URLs are attached as gallery images, copied into product media storage on import
+		images, imageErr := parseImageURLs(row["imageurls"])
+		if imageErr != nil {
+			h.addError(result, rowNum, "imageUrls", "INVALID", imageErr.Error())
+		}
+
 		// Skip row if it has validation errors
 		if h.hasRowErrors(result, rowNum) {
 			continue
@@ -509,6 +550,7 @@ func (h *ImportHandler) processSingleBatch(
 			ComparePrice:      optionalString(row["compareprice"]),
 			CostPrice:         optionalString(row["costprice"]),
 			Brand:             optionalString(row["brand"]),
+			Gtin:              optionalString(row["gtin"]),
 			SearchKeywords:    optionalString(row["searchkeywords"]),
 			Weight:            optionalString(row["weight"]),
 			Quantity:          parseOptionalInt(row["quantity"]),
@@ -520,10 +562,21 @@ func (h *ImportHandler) processSingleBatch(
 			UpdatedBy:         stringPtr(userID),
 			Status:            models.ProductStatusDraft,
 		}
+		if len(images) > 0 {
+			imagesArray := make(models.JSONArray, len(images))
+			for i, img := range images {
+				imagesArray[i] = img
+			}
+			product.Images = &imagesArray
+		}
 
 		products = append(products, product)
+		productRows = append(productRows, rowNum)
 	}
 
+	// Flag or drop probable duplicates of existing products per the tenant's policy
+	products, productRows = h.applyDuplicatePolicy(tenantID, products, productRows, result)
+
 	// If validate only, return validation results
 	if validateOnly {
 		result.Success = len(result.Errors) == 0
@@ -532,6 +585,9 @@ func (h *ImportHandler) processSingleBatch(
 		return result
 	}
 
+	// Copy image URLs into product media storage; rows whose images can't be fetched fail
+	products = h.sideloadImages(tenantID, products, productRows, result)
+
 	// If there are validation errors for some rows, we still process valid rows
 	if len(products) == 0 {
 		result.Success = false
@@ -549,6 +605,93 @@ func (h *ImportHandler) processSingleBatch(
 	return result
 }
 
+// applyDuplicatePolicy checks a batch's products against the tenant's existing catalog.
+// Under BLOCK, rows with probable duplicates fail with DUPLICATE_PRODUCT; under WARN they
+// are imported with a POSSIBLE_DUPLICATE warning. An existing product with the row's exact
+// SKU isn't reported, as skipDuplicates and upsert mode already decide what happens to it.
+func (h *ImportHandler) applyDuplicatePolicy(tenantID string, products []*models.Product, rowNums []int, result *models.ImportResult) ([]*models.Product, []int) {
+	if len(products) == 0 {
+		return products, rowNums
+	}
+	policy, err := h.repo.GetDuplicatePolicy(tenantID)
+	if err != nil || !policy.Enabled() {
+		return products, rowNums
+	}
+	matches, err := h.repo.FindDuplicates(tenantID, policy, products)
+	if err != nil {
+		fmt.Printf("Warning: Duplicate check failed for import batch: %v\n", err)
+		return products, rowNums
+	}
+
+	kept := make([]*models.Product, 0, len(products))
+	keptRows := make([]int, 0, len(products))
+	for i, product := range products {
+		var duplicates []models.DuplicateMatch
+		for _, m := range matches[i] {
+			if m.SKU != product.SKU {
+				duplicates = append(duplicates, m)
+			}
+		}
+		if len(duplicates) == 0 {
+			kept = append(kept, product)
+			keptRows = append(keptRows, rowNums[i])
+			continue
+		}
+
+		if policy.Mode == models.DuplicatePolicyBlock {
+			h.addError(result, rowNums[i], "", "DUPLICATE_PRODUCT", describeDuplicates(duplicates))
+			continue
+		}
+		result.Warnings = append(result.Warnings, models.ImportRowError{
+			Row:     rowNums[i],
+			Code:    "POSSIBLE_DUPLICATE",
+			Message: describeDuplicates(duplicates),
+		})
+		kept = append(kept, product)
+		keptRows = append(keptRows, rowNums[i])
+	}
+	return kept, keptRows
+}
+
+// sideloadImages replaces each product's image URLs with copies in product media storage.
+// A product whose images can't all be copied fails its row with IMAGE_DOWNLOAD_FAILED.
+func (h *ImportHandler) sideloadImages(tenantID string, products []*models.Product, rowNums []int, result *models.ImportResult) []*models.Product {
+	if h.media == nil {
+		return products
+	}
+
+	kept := make([]*models.Product, 0, len(products))
+	for i, product := range products {
+		if product.Images == nil {
+			kept = append(kept, product)
+			continue
+		}
+
+		var sideloadErr error
+		for j, item := range *product.Images {
+			img, ok := item.(models.ProductImage)
+			if !ok {
+				continue
+			}
+			hosted, err := h.media.SideloadProductImage(context.Background(), tenantID, product.SKU, img.URL)
+			if err != nil {
+				sideloadErr = fmt.Errorf("image '%s': %w", img.URL, err)
+				break
+			}
+			img.URL = hosted.URL
+			img.Width = hosted.Width
+			img.Height = hosted.Height
+			(*product.Images)[j] = img
+		}
+		if sideloadErr != nil {
+			h.addError(result, rowNums[i], "imageUrls", "IMAGE_DOWNLOAD_FAILED", sideloadErr.Error())
+			continue
+		}
+		kept = append(kept, product)
+	}
+	return kept
+}
+
 // Thread-safe cache resolution functions
 func (h *ImportHandler) resolveCategoryWithCache(tenantID, userID, userEmail string, row map[string]string, rowNum int, result *models.ImportResult, cache map[string]string, mutex *sync.RWMutex) string {
 	categoryID := row["categoryid"]
@@ -729,38 +872,30 @@ func (h *ImportHandler) resolveSupplierWithCache(tenantID string, row map[string
 
 // executeBulkCreate handles the bulk create operation
 func (h *ImportHandler) executeBulkCreate(tenantID string, products []*models.Product, rows []map[string]string, skipDuplicates bool, result *models.ImportResult) {
-	bulkResult, err := h.repo.BulkCreate(tenantID, products, skipDuplicates)
-	if err != nil && bulkResult.Success == 0 && bulkResult.Skipped == 0 {
-		result.Success = false
-		result.Errors = append(result.Errors, models.ImportRowError{
-			Row:     0,
-			Code:    "BULK_CREATE_FAILED",
-			Message: err.Error(),
-		})
-		return
-	}
-
-	for _, product := range bulkResult.Created {
-		result.CreatedIDs = append(result.CreatedIDs, product.ID.String())
-	}
-
-	for _, bulkErr := range bulkResult.Errors {
-		rowNum := 0
-		if bulkErr.Index < len(rows) {
-			rowNum, _ = strconv.Atoi(rows[bulkErr.Index]["_row"])
+	bulkResult := h.repo.BulkCreate(tenantID, products, skipDuplicates, bulk.Options{})
+
+	for _, item := range bulkResult.Results {
+		switch item.Status {
+		case bulk.StatusCreated:
+			result.CreatedIDs = append(result.CreatedIDs, item.Data.(*models.Product).ID.String())
+		case bulk.StatusFailed:
+			rowNum := 0
+			if item.Index < len(rows) {
+				rowNum, _ = strconv.Atoi(rows[item.Index]["_row"])
+			}
+			result.Errors = append(result.Errors, models.ImportRowError{
+				Row:     rowNum,
+				Code:    item.Error.Code,
+				Message: item.Error.Message,
+			})
 		}
-		result.Errors = append(result.Errors, models.ImportRowError{
-			Row:     rowNum,
-			Code:    bulkErr.Code,
-			Message: bulkErr.Message,
-		})
 	}
 
-	result.Success = bulkResult.Success > 0 || bulkResult.Skipped > 0
-	result.SuccessCount = bulkResult.Success
-	result.CreatedCount = len(bulkResult.Created)
-	result.FailedCount = bulkResult.Failed + (result.TotalRows - len(products))
-	result.SkippedCount = result.TotalRows - len(products) - bulkResult.Failed + bulkResult.Skipped
+	result.Success = bulkResult.SuccessCount > 0 || bulkResult.SkippedCount > 0
+	result.SuccessCount = bulkResult.SuccessCount
+	result.CreatedCount = bulkResult.SuccessCount
+	result.FailedCount = bulkResult.FailedCount + (result.TotalRows - len(products))
+	result.SkippedCount = result.TotalRows - len(products) - bulkResult.FailedCount + bulkResult.SkippedCount
 }
 
 // executeBulkUpsert handles the bulk upsert operation
@@ -806,95 +941,31 @@ func (h *ImportHandler) executeBulkUpsert(tenantID string, products []*models.Pr
 
 // parseCSV parses a CSV file into rows
 func (h *ImportHandler) parseCSV(file io.Reader) ([]map[string]string, error) {
-	reader := csv.NewReader(file)
+	return h.readAllRows(models.ImportFormatCSV, file)
+}
 
-	// Read header
-	headers, err := reader.Read()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV header: %w", err)
-	}
+// parseXLSX parses an Excel file into rows
+func (h *ImportHandler) parseXLSX(file io.Reader) ([]map[string]string, error) {
+	return h.readAllRows(models.ImportFormatXLSX, file)
+}
 
-	// Normalize headers
-	for i := range headers {
-		headers[i] = strings.TrimSpace(strings.ToLower(headers[i]))
-		// Remove required marker if present
-		headers[i] = strings.TrimSuffix(headers[i], " *")
+// readAllRows reads every data row of a file; only used for synchronous imports
+func (h *ImportHandler) readAllRows(format models.ImportFormat, file io.Reader) ([]map[string]string, error) {
+	reader, err := newImportRowReader(format, file)
+	if err != nil {
+		return nil, err
 	}
+	defer reader.Close()
 
 	var rows []map[string]string
-	lineNum := 1
-
 	for {
-		record, err := reader.Read()
+		row, err := reader.Read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("error reading line %d: %w", lineNum+1, err)
-		}
-
-		row := make(map[string]string)
-		for i, value := range record {
-			if i < len(headers) {
-				row[headers[i]] = strings.TrimSpace(value)
-			}
-		}
-		row["_row"] = strconv.Itoa(lineNum + 1) // Track row number for error reporting
-		rows = append(rows, row)
-		lineNum++
-	}
-
-	return rows, nil
-}
-
-// parseXLSX parses an Excel file into rows
-func (h *ImportHandler) parseXLSX(file io.Reader) ([]map[string]string, error) {
-	f, err := excelize.OpenReader(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open Excel file: %w", err)
-	}
-	defer f.Close()
-
-	// Get first sheet (should be "Products")
-	sheets := f.GetSheetList()
-	if len(sheets) == 0 {
-		return nil, fmt.Errorf("no sheets found in Excel file")
-	}
-
-	sheetName := sheets[0]
-	// Prefer "Products" sheet if it exists
-	for _, name := range sheets {
-		if strings.EqualFold(name, "Products") {
-			sheetName = name
-			break
-		}
-	}
-
-	excelRows, err := f.GetRows(sheetName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read sheet: %w", err)
-	}
-
-	if len(excelRows) < 2 {
-		return nil, fmt.Errorf("file must have a header row and at least one data row")
-	}
-
-	// First row is header
-	headers := excelRows[0]
-	for i := range headers {
-		headers[i] = strings.TrimSpace(strings.ToLower(headers[i]))
-		headers[i] = strings.TrimSuffix(headers[i], " *")
-	}
-
-	var rows []map[string]string
-	for rowIdx, excelRow := range excelRows[1:] {
-		row := make(map[string]string)
-		for i, value := range excelRow {
-			if i < len(headers) {
-				row[headers[i]] = strings.TrimSpace(value)
-			}
+			return nil, err
 		}
-		row["_row"] = strconv.Itoa(rowIdx + 2) // Track row number (1-indexed, +1 for header)
 		rows = append(rows, row)
 	}
 
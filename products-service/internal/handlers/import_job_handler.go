@@ -0,0 +1,706 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+	"products-service/internal/models"
+)
+
+const (
+	MaxImportFileBytes   = 50 << 20 // 50MB upload limit for async imports
+	AsyncImportThreshold = 1 << 20  // files larger than 1MB are always imported asynchronously
+)
+
+// importRowReader streams data rows from a CSV or XLSX file so large imports
+// never hold the whole sheet in memory
+type importRowReader struct {
+	headers []string
+	next    func() ([]string, int, error) // returns io.EOF when exhausted
+	close   func()
+}
+
+// newImportRowReader opens a row reader and consumes the header row
+func newImportRowReader(format models.ImportFormat, file io.Reader) (*importRowReader, error) {
+	var r *importRowReader
+
+	switch format {
+	case models.ImportFormatCSV:
+		reader := csv.NewReader(file)
+		line := 0
+		r = &importRowReader{
+			next: func() ([]string, int, error) {
+				record, err := reader.Read()
+				if err != nil {
+					if err != io.EOF {
+						err = fmt.Errorf("error reading line %d: %w", line+1, err)
+					}
+					return nil, 0, err
+				}
+				line++
+				return record, line, nil
+			},
+			close: func() {},
+		}
+	case models.ImportFormatXLSX:
+		f, err := excelize.OpenReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Excel file: %w", err)
+		}
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			f.Close()
+			return nil, fmt.Errorf("no sheets found in Excel file")
+		}
+		sheetName := sheets[0]
+		// Prefer "Products" sheet if it exists
+		for _, name := range sheets {
+			if strings.EqualFold(name, "Products") {
+				sheetName = name
+				break
+			}
+		}
+
+		rows, err := f.Rows(sheetName)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to read sheet: %w", err)
+		}
+		line := 0
+		r = &importRowReader{
+			next: func() ([]string, int, error) {
+				for rows.Next() {
+					line++
+					cols, err := rows.Columns()
+					if err != nil {
+						return nil, 0, fmt.Errorf("error reading row %d: %w", line, err)
+					}
+					if isBlankRow(cols) {
+						continue
+					}
+					return cols, line, nil
+				}
+				if err := rows.Error(); err != nil {
+					return nil, 0, fmt.Errorf("failed to read sheet: %w", err)
+				}
+				return nil, 0, io.EOF
+			},
+			close: func() {
+				rows.Close()
+				f.Close()
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+
+	headers, _, err := r.next()
+	if err != nil {
+		r.close()
+		if err == io.EOF {
+			return nil, fmt.Errorf("file is missing a header row")
+		}
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	// Normalize headers
+	r.headers = make([]string, len(headers))
+	for i := range headers {
+		r.headers[i] = strings.TrimSpace(strings.ToLower(headers[i]))
+		// Remove required marker if present
+		r.headers[i] = strings.TrimSuffix(r.headers[i], " *")
+	}
+
+	return r, nil
+}
+
+// Read returns the next data row keyed by normalized header, with "_row" set to the file line number
+func (r *importRowReader) Read() (map[string]string, error) {
+	record, line, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+
+	row := make(map[string]string, len(r.headers)+1)
+	for i, value := range record {
+		if i < len(r.headers) {
+			row[r.headers[i]] = strings.TrimSpace(value)
+		}
+	}
+	row["_row"] = strconv.Itoa(line) // Track row number for error reporting
+	return row, nil
+}
+
+// ReadBatch reads up to size data rows; an empty slice means the file is exhausted
+func (r *importRowReader) ReadBatch(size int) ([]map[string]string, error) {
+	rows := make([]map[string]string, 0, size)
+	for len(rows) < size {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Skip discards n data rows (used when resuming a job)
+func (r *importRowReader) Skip(n int) error {
+	for i := 0; i < n; i++ {
+		if _, _, err := r.next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *importRowReader) Close() {
+	r.close()
+}
+
+func isBlankRow(cols []string) bool {
+	for _, c := range cols {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// countImportRows counts the data rows in a stored import file
+func countImportRows(format models.ImportFormat, data []byte) (int, error) {
+	reader, err := newImportRowReader(format, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	count := 0
+	for {
+		if _, _, err := reader.next(); err != nil {
+			if err == io.EOF {
+				return count, nil
+			}
+			return count, err
+		}
+		count++
+	}
+}
+
+// detectImportFormat determines the import format from the uploaded file name
+func detectImportFormat(filename string) (models.ImportFormat, bool) {
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".csv"):
+		return models.ImportFormatCSV, true
+	case strings.HasSuffix(lower, ".xlsx"):
+		return models.ImportFormatXLSX, true
+	}
+	return "", false
+}
+
+// CreateImportJob uploads a file and queues it for asynchronous import
+// POST /api/v1/products/import/jobs
+func (h *ImportHandler) CreateImportJob(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FILE_REQUIRED",
+				Message: "Please upload a CSV or Excel file",
+			},
+		})
+		return
+	}
+	defer file.Close()
+
+	format, ok := detectImportFormat(header.Filename)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_FORMAT",
+				Message: "Only CSV and XLSX files are supported",
+			},
+		})
+		return
+	}
+
+	h.enqueueImportJob(c, file, header, format)
+}
+
+// enqueueImportJob stores the uploaded file as an import job and responds with 202 Accepted
+func (h *ImportHandler) enqueueImportJob(c *gin.Context, file multipart.File, header *multipart.FileHeader, format models.ImportFormat) {
+	tenantID, _ := c.Get("tenant_id")
+	userID, _ := c.Get("user_id")
+	userEmail, _ := c.Get("user_email")
+
+	if h.jobRepo == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "ASYNC_IMPORT_UNAVAILABLE",
+				Message: "Asynchronous imports are not configured",
+			},
+		})
+		return
+	}
+
+	if header.Size > MaxImportFileBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FILE_TOO_LARGE",
+				Message: fmt.Sprintf("Import files are limited to %dMB", MaxImportFileBytes>>20),
+			},
+		})
+		return
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "PARSE_ERROR",
+				Message: "Failed to read uploaded file",
+			},
+		})
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(file, MaxImportFileBytes+1))
+	if err != nil || len(data) > MaxImportFileBytes {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "PARSE_ERROR",
+				Message: "Failed to read uploaded file",
+			},
+		})
+		return
+	}
+
+	// Validate the header up front so obviously broken files fail fast
+	reader, err := newImportRowReader(format, bytes.NewReader(data))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "PARSE_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	reader.Close()
+
+	mode := models.ImportMode(c.DefaultPostForm("mode", string(models.ImportModeCreate)))
+	if c.DefaultPostForm("updateExisting", "false") == "true" {
+		mode = models.ImportModeUpsert
+	}
+	if mode != models.ImportModeCreate && mode != models.ImportModeUpsert {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_MODE",
+				Message: "mode must be 'create' or 'upsert'",
+			},
+		})
+		return
+	}
+
+	mapping, err := h.resolveColumnMapping(c, tenantID.(string), "products")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_MAPPING",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	job := &models.ImportJob{
+		TenantID:       tenantID.(string),
+		Mode:           mode,
+		FileName:       header.Filename,
+		Format:         format,
+		FileData:       data,
+		SkipDuplicates: c.DefaultPostForm("skipDuplicates", "false") == "true",
+		BatchSize:      parseBatchSize(c.DefaultPostForm("batchSize", "")),
+		MaxRetries:     parseMaxRetries(c.DefaultPostForm("maxRetries", "")),
+		CreatedBy:      stringPtr(userID.(string)),
+	}
+	if email, ok := userEmail.(string); ok && email != "" {
+		job.UserEmail = &email
+	}
+	if len(mapping) > 0 {
+		job.ColumnMapping = make(models.JSON, len(mapping))
+		for source, target := range mapping {
+			job.ColumnMapping[source] = target
+		}
+	}
+
+	if err := h.jobRepo.Create(job); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "JOB_CREATE_FAILED",
+				Message: "Failed to queue import job",
+			},
+		})
+		return
+	}
+
+	message := "Import queued. Poll the job for progress."
+	c.JSON(http.StatusAccepted, models.ImportJobResponse{
+		Success: true,
+		Data:    job,
+		Percent: 0,
+		Message: &message,
+	})
+}
+
+// ListImportJobs lists the tenant's import jobs
+// GET /api/v1/products/import/jobs
+func (h *ImportHandler) ListImportJobs(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	jobs, total, err := h.jobRepo.List(tenantID.(string), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve import jobs",
+			},
+		})
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	c.JSON(http.StatusOK, models.ImportJobListResponse{
+		Success: true,
+		Data:    jobs,
+		Pagination: &models.PaginationInfo{
+			Page:        page,
+			Limit:       limit,
+			Total:       total,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	})
+}
+
+// GetImportJob returns the status and progress of an import job
+// GET /api/v1/products/import/jobs/:jobId
+func (h *ImportHandler) GetImportJob(c *gin.Context) {
+	job, ok := h.loadImportJob(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ImportJobResponse{
+		Success: true,
+		Data:    job,
+		Percent: job.PercentComplete(),
+	})
+}
+
+// GetImportJobErrors returns the per-row error report for an import job
+// GET /api/v1/products/import/jobs/:jobId/errors?format=csv|json
+func (h *ImportHandler) GetImportJobErrors(c *gin.Context) {
+	job, ok := h.loadImportJob(c)
+	if !ok {
+		return
+	}
+
+	rowErrors, err := h.jobRepo.GetErrors(job.TenantID, job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve import errors",
+			},
+		})
+		return
+	}
+
+	if c.DefaultQuery("format", "json") != "csv" {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    rowErrors,
+			"total":   len(rowErrors),
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=import_%s_errors.csv", job.ID.String()))
+
+	writer := csv.NewWriter(c.Writer)
+	defer writer.Flush()
+
+	writer.Write([]string{"row", "sku", "column", "code", "message"})
+	for _, e := range rowErrors {
+		writer.Write([]string{strconv.Itoa(e.Row), e.SKU, e.Column, e.Code, e.Message})
+	}
+}
+
+// ResumeImportJob re-queues a failed import job; processing continues after the last committed chunk
+// POST /api/v1/products/import/jobs/:jobId/resume
+func (h *ImportHandler) ResumeImportJob(c *gin.Context) {
+	job, ok := h.loadImportJob(c)
+	if !ok {
+		return
+	}
+
+	if err := h.jobRepo.Resume(job.TenantID, job.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "NOT_RESUMABLE",
+					Message: fmt.Sprintf("Import job is %s and cannot be resumed", job.Status),
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "RESUME_FAILED",
+				Message: "Failed to resume import job",
+			},
+		})
+		return
+	}
+
+	job.Status = models.ImportStatusPending
+	job.LastError = nil
+	message := fmt.Sprintf("Import resumed from row %d", job.NextRow+1)
+	c.JSON(http.StatusAccepted, models.ImportJobResponse{
+		Success: true,
+		Data:    job,
+		Percent: job.PercentComplete(),
+		Message: &message,
+	})
+}
+
+// loadImportJob resolves :jobId for the current tenant, writing an error response on failure
+func (h *ImportHandler) loadImportJob(c *gin.Context) (*models.ImportJob, bool) {
+	tenantID, _ := c.Get("tenant_id")
+
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid import job ID format",
+			},
+		})
+		return nil, false
+	}
+
+	job, err := h.jobRepo.GetByID(tenantID.(string), jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Import job not found",
+			},
+		})
+		return nil, false
+	}
+
+	return job, true
+}
+
+// ProcessImportJob runs a claimed import job chunk by chunk, committing progress and row
+// errors after every chunk so an interrupted job resumes where it stopped.
+// Called by the import worker; returns ctx.Err() if interrupted by shutdown.
+func (h *ImportHandler) ProcessImportJob(ctx context.Context, job *models.ImportJob) error {
+	total, err := countImportRows(job.Format, job.FileData)
+	if err != nil {
+		return h.jobRepo.MarkFailed(job.ID, err.Error())
+	}
+	if total > models.MaxImportRows {
+		return h.jobRepo.MarkFailed(job.ID, fmt.Sprintf("file has %d rows; imports are limited to %d rows", total, models.MaxImportRows))
+	}
+	if total != job.TotalRows {
+		job.TotalRows = total
+		if err := h.jobRepo.SetTotalRows(job.ID, total); err != nil {
+			return err
+		}
+	}
+
+	reader, err := newImportRowReader(job.Format, bytes.NewReader(job.FileData))
+	if err != nil {
+		return h.jobRepo.MarkFailed(job.ID, err.Error())
+	}
+	defer reader.Close()
+
+	if err := reader.Skip(job.NextRow); err != nil && err != io.EOF {
+		return h.jobRepo.MarkFailed(job.ID, err.Error())
+	}
+
+	userID := ""
+	if job.CreatedBy != nil {
+		userID = *job.CreatedBy
+	}
+	userEmail := ""
+	if job.UserEmail != nil {
+		userEmail = *job.UserEmail
+	}
+	updateExisting := job.Mode == models.ImportModeUpsert
+
+	batchSize := job.BatchSize
+	if batchSize < 1 || batchSize > MaxBatchSize {
+		batchSize = DefaultBatchSize
+	}
+
+	categoryCache := make(map[string]string)
+	vendorCache := make(map[string]string)
+	warehouseCache := make(map[string]*struct{ ID, Name string })
+	supplierCache := make(map[string]*struct{ ID, Name string })
+	var cacheMutex sync.RWMutex
+
+	var mapping map[string]string
+	if len(job.ColumnMapping) > 0 {
+		mapping = make(map[string]string, len(job.ColumnMapping))
+		for source, target := range job.ColumnMapping {
+			if t, ok := target.(string); ok {
+				mapping[source] = t
+			}
+		}
+	}
+
+	batchNum := job.NextRow / batchSize
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rows, err := reader.ReadBatch(batchSize)
+		if err != nil {
+			return h.jobRepo.MarkFailed(job.ID, err.Error())
+		}
+		if len(rows) == 0 {
+			break
+		}
+		rows = applyColumnMapping(rows, mapping)
+		batchNum++
+
+		startRow, _ := strconv.Atoi(rows[0]["_row"])
+		endRow, _ := strconv.Atoi(rows[len(rows)-1]["_row"])
+
+		batchResult := h.processBatchWithRetry(
+			job.TenantID, userID, userEmail,
+			rows, batchNum, startRow, endRow,
+			job.SkipDuplicates, updateExisting, false,
+			job.MaxRetries,
+			categoryCache, vendorCache, warehouseCache, supplierCache,
+			&cacheMutex,
+		)
+
+		job.NextRow += len(rows)
+		job.CreatedCount += batchResult.CreatedCount
+		job.UpdatedCount += batchResult.UpdatedCount
+		job.FailedCount += batchResult.FailedCount
+		job.SkippedCount += batchResult.SkippedCount
+		// Duplicate warnings are kept with the errors so they can be reviewed after the job
+		rowErrors := append(batchResult.Errors, batchResult.Warnings...)
+		job.ErrorCount += len(rowErrors)
+
+		if err := h.jobRepo.SaveChunk(job, toImportJobErrors(rows, rowErrors)); err != nil {
+			// Progress was not committed; the chunk is retried when the job is resumed
+			return h.jobRepo.MarkFailed(job.ID, fmt.Sprintf("failed to save progress at row %d: %v", startRow, err))
+		}
+	}
+
+	return h.jobRepo.MarkCompleted(job.ID)
+}
+
+// toImportJobErrors converts batch row errors into persisted job errors, attaching the row's SKU for context
+func toImportJobErrors(rows []map[string]string, rowErrors []models.ImportRowError) []models.ImportJobError {
+	if len(rowErrors) == 0 {
+		return nil
+	}
+
+	skuByRow := make(map[int]string, len(rows))
+	for _, row := range rows {
+		rowNum, _ := strconv.Atoi(row["_row"])
+		skuByRow[rowNum] = row["sku"]
+	}
+
+	jobErrors := make([]models.ImportJobError, 0, len(rowErrors))
+	for _, e := range rowErrors {
+		jobErrors = append(jobErrors, models.ImportJobError{
+			Row:     e.Row,
+			SKU:     skuByRow[e.Row],
+			Column:  e.Column,
+			Code:    e.Code,
+			Message: e.Message,
+		})
+	}
+	return jobErrors
+}
+
+// parseImageURLs splits a pipe-separated imageUrls cell into gallery images
+func parseImageURLs(value string) ([]models.ProductImage, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	images := make([]models.ProductImage, 0)
+	for _, raw := range strings.Split(value, "|") {
+		url := strings.TrimSpace(raw)
+		if url == "" {
+			continue
+		}
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return nil, fmt.Errorf("image URL '%s' must start with http:// or https://", url)
+		}
+		images = append(images, models.ProductImage{
+			ID:       uuid.New().String(),
+			URL:      url,
+			Position: len(images),
+		})
+	}
+
+	if len(images) > models.MaxImportImageURLs {
+		return nil, fmt.Errorf("maximum %d images allowed per product", models.MaxImportImageURLs)
+	}
+	return images, nil
+}
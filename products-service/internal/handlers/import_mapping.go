@@ -0,0 +1,486 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"products-service/internal/models"
+)
+
+// importTemplateFor returns the import template for an importable entity
+func importTemplateFor(entity string) (models.ImportTemplate, bool) {
+	switch entity {
+	case "", "products":
+		return models.ProductImportTemplate(), true
+	}
+	return models.ImportTemplate{}, false
+}
+
+// normalizeImportHeader normalizes a source header the same way the parsers do
+func normalizeImportHeader(header string) string {
+	return strings.TrimSuffix(strings.TrimSpace(strings.ToLower(header)), " *")
+}
+
+// canonicalImportKey strips case and punctuation so "Cost Price", "cost_price" and "costPrice" compare equal
+func canonicalImportKey(header string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(header) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// suggestColumnMapping matches source headers to template columns by canonical name
+func suggestColumnMapping(headers []string, columns []models.ImportTemplateColumn) map[string]string {
+	byKey := make(map[string]string, len(columns))
+	for _, col := range columns {
+		byKey[canonicalImportKey(col.Name)] = col.Name
+	}
+
+	suggested := make(map[string]string)
+	for _, header := range headers {
+		if name, ok := byKey[canonicalImportKey(header)]; ok {
+			suggested[header] = name
+		}
+	}
+	return suggested
+}
+
+// normalizeColumnMapping lower-cases both sides of a mapping and drops empty targets
+func normalizeColumnMapping(mapping map[string]string) map[string]string {
+	normalized := make(map[string]string, len(mapping))
+	for source, target := range mapping {
+		source = normalizeImportHeader(source)
+		target = strings.ToLower(strings.TrimSpace(target))
+		if source == "" || target == "" {
+			continue
+		}
+		normalized[source] = target
+	}
+	return normalized
+}
+
+// applyColumnMapping renames row keys from source headers to template column names.
+// Unmapped headers pass through unchanged, so files already using template headers need no mapping.
+func applyColumnMapping(rows []map[string]string, mapping map[string]string) []map[string]string {
+	if len(mapping) == 0 {
+		return rows
+	}
+	for i, row := range rows {
+		mapped := make(map[string]string, len(row))
+		for key, value := range row {
+			if target, ok := mapping[key]; ok {
+				if existing := mapped[target]; existing == "" {
+					mapped[target] = value
+				}
+				continue
+			}
+			if _, set := mapped[key]; !set {
+				mapped[key] = value
+			}
+		}
+		rows[i] = mapped
+	}
+	return rows
+}
+
+// resolveColumnMapping reads the column mapping for an import request from the
+// "mapping" form field (JSON object) or a saved preset referenced by "presetId"
+func (h *ImportHandler) resolveColumnMapping(c *gin.Context, tenantID, entity string) (map[string]string, error) {
+	if raw := c.PostForm("mapping"); raw != "" {
+		var mapping map[string]string
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			return nil, fmt.Errorf("mapping must be a JSON object of sourceHeader to column name")
+		}
+		return normalizeColumnMapping(mapping), nil
+	}
+
+	presetID := c.PostForm("presetId")
+	if presetID == "" {
+		return nil, nil
+	}
+	if h.mappingRepo == nil {
+		return nil, fmt.Errorf("mapping presets are not configured")
+	}
+	id, err := uuid.Parse(presetID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid presetId")
+	}
+	preset, err := h.mappingRepo.GetByID(tenantID, id)
+	if err != nil || preset.Entity != entity {
+		return nil, fmt.Errorf("mapping preset not found")
+	}
+	return presetMapping(preset), nil
+}
+
+// presetMapping converts a stored preset into a normalized mapping
+func presetMapping(preset *models.ImportMappingPreset) map[string]string {
+	mapping := make(map[string]string, len(preset.Mapping))
+	for source, target := range preset.Mapping {
+		if s, ok := target.(string); ok {
+			mapping[source] = s
+		}
+	}
+	return normalizeColumnMapping(mapping)
+}
+
+// validateImportRow checks required columns and column types against the template.
+// It performs no lookups, so it is safe to run on preview rows without side effects.
+func validateImportRow(row map[string]string, rowNum int, columns []models.ImportTemplateColumn) []models.ImportRowError {
+	var rowErrors []models.ImportRowError
+	for _, col := range columns {
+		value := row[strings.ToLower(col.Name)]
+		if value == "" {
+			if col.Required {
+				rowErrors = append(rowErrors, models.ImportRowError{
+					Row: rowNum, Column: col.Name, Code: "REQUIRED",
+					Message: fmt.Sprintf("%s is required", col.Name),
+				})
+			}
+			continue
+		}
+
+		var err error
+		switch col.Type {
+		case "number":
+			_, err = strconv.ParseFloat(value, 64)
+		case "boolean":
+			_, err = strconv.ParseBool(value)
+		case "uuid":
+			_, err = uuid.Parse(value)
+		case "email":
+			if !strings.Contains(value, "@") {
+				err = errors.New("invalid email")
+			}
+		case "date":
+			if _, perr := time.Parse("2006-01-02", value); perr != nil {
+				_, err = time.Parse(time.RFC3339, value)
+			}
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, models.ImportRowError{
+				Row: rowNum, Column: col.Name, Code: "INVALID",
+				Message: fmt.Sprintf("%s must be a valid %s", col.Name, col.Type),
+			})
+		}
+	}
+	return rowErrors
+}
+
+// PreviewImport shows the detected headers, suggested mapping, and the first N mapped
+// rows with validation errors, without importing anything
+// POST /api/v1/products/import/preview
+func (h *ImportHandler) PreviewImport(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	entity := c.DefaultPostForm("entity", "products")
+	template, ok := importTemplateFor(entity)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ENTITY",
+				Message: fmt.Sprintf("Unknown import entity '%s'", entity),
+			},
+		})
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FILE_REQUIRED",
+				Message: "Please upload a CSV or Excel file",
+			},
+		})
+		return
+	}
+	defer file.Close()
+
+	format, ok := detectImportFormat(header.Filename)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_FORMAT",
+				Message: "Only CSV and XLSX files are supported",
+			},
+		})
+		return
+	}
+
+	previewRows := models.DefaultImportPreviewRows
+	if n, err := strconv.Atoi(c.DefaultPostForm("rows", "")); err == nil && n > 0 {
+		previewRows = n
+		if previewRows > models.MaxImportPreviewRows {
+			previewRows = models.MaxImportPreviewRows
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxImportFileBytes+1))
+	if err != nil || len(data) > MaxImportFileBytes {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "PARSE_ERROR",
+				Message: "Failed to read uploaded file",
+			},
+		})
+		return
+	}
+
+	reader, err := newImportRowReader(format, bytes.NewReader(data))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "PARSE_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	headers := reader.headers
+	rows, err := reader.ReadBatch(previewRows)
+	reader.Close()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "PARSE_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	totalRows, _ := countImportRows(format, data)
+
+	suggested := suggestColumnMapping(headers, template.Columns)
+	mapping, err := h.resolveColumnMapping(c, tenantID.(string), entity)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_MAPPING",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	if mapping == nil {
+		mapping = normalizeColumnMapping(suggested)
+	}
+	rows = applyColumnMapping(rows, mapping)
+
+	h.writeImportPreview(c, entity, template, headers, suggested, mapping, totalRows, rows)
+}
+
+// writeImportPreview validates the mapped preview rows and writes the preview response
+func (h *ImportHandler) writeImportPreview(c *gin.Context, entity string, template models.ImportTemplate, headers []string, suggested, mapping map[string]string, totalRows int, rows []map[string]string) {
+	known := make(map[string]bool, len(template.Columns))
+	for _, col := range template.Columns {
+		known[strings.ToLower(col.Name)] = true
+	}
+
+	result := &models.ImportPreviewResult{
+		Entity:           entity,
+		SourceHeaders:    headers,
+		SuggestedMapping: suggested,
+		AppliedMapping:   mapping,
+		TotalRows:        totalRows,
+		Rows:             rows,
+		Errors:           make([]models.ImportRowError, 0),
+	}
+
+	mappedColumns := make(map[string]bool)
+	for _, header := range headers {
+		target := header
+		if t, ok := mapping[header]; ok {
+			target = t
+		}
+		if known[target] {
+			mappedColumns[target] = true
+		} else {
+			result.UnmappedHeaders = append(result.UnmappedHeaders, header)
+		}
+	}
+	for _, col := range template.Columns {
+		if col.Required && !mappedColumns[strings.ToLower(col.Name)] {
+			result.MissingRequired = append(result.MissingRequired, col.Name)
+		}
+	}
+
+	for _, row := range rows {
+		rowNum, _ := strconv.Atoi(row["_row"])
+		rowErrors := validateImportRow(row, rowNum, template.Columns)
+		if len(rowErrors) == 0 {
+			result.ValidRowCount++
+		}
+		result.Errors = append(result.Errors, rowErrors...)
+	}
+	result.Success = len(result.MissingRequired) == 0
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListMappingPresets lists the tenant's saved column mappings
+// GET /api/v1/products/import/mappings
+func (h *ImportHandler) ListMappingPresets(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+	entity := c.DefaultQuery("entity", "products")
+
+	presets, err := h.mappingRepo.List(tenantID.(string), entity)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve mapping presets",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    presets,
+	})
+}
+
+// SaveMappingPreset creates or replaces a named column mapping
+// POST /api/v1/products/import/mappings
+func (h *ImportHandler) SaveMappingPreset(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+	userID, _ := c.Get("user_id")
+
+	var req models.SaveImportMappingPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	if req.Entity == "" {
+		req.Entity = "products"
+	}
+
+	template, ok := importTemplateFor(req.Entity)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ENTITY",
+				Message: fmt.Sprintf("Unknown import entity '%s'", req.Entity),
+			},
+		})
+		return
+	}
+
+	known := make(map[string]bool, len(template.Columns))
+	for _, col := range template.Columns {
+		known[strings.ToLower(col.Name)] = true
+	}
+	mapping := normalizeColumnMapping(req.Mapping)
+	stored := make(models.JSON, len(mapping))
+	for source, target := range mapping {
+		if !known[target] {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "INVALID_MAPPING",
+					Message: fmt.Sprintf("'%s' is not a %s import column", target, req.Entity),
+					Field:   source,
+				},
+			})
+			return
+		}
+		stored[source] = target
+	}
+
+	preset := &models.ImportMappingPreset{
+		TenantID: tenantID.(string),
+		Entity:   req.Entity,
+		Name:     strings.TrimSpace(req.Name),
+		Mapping:  stored,
+	}
+	if uid, ok := userID.(string); ok && uid != "" {
+		preset.CreatedBy = &uid
+	}
+
+	if err := h.mappingRepo.Save(preset); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "SAVE_FAILED",
+				Message: "Failed to save mapping preset",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data:    preset,
+	})
+}
+
+// DeleteMappingPreset deletes a saved column mapping
+// DELETE /api/v1/products/import/mappings/:presetId
+func (h *ImportHandler) DeleteMappingPreset(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	id, err := uuid.Parse(c.Param("presetId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid preset ID format",
+			},
+		})
+		return
+	}
+
+	deleted, err := h.mappingRepo.Delete(tenantID.(string), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "DELETE_FAILED",
+				Message: "Failed to delete mapping preset",
+			},
+		})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Mapping preset not found",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"products-service/internal/contracts"
+	"products-service/internal/models"
+)
+
+// ordersContractChecks verify products-service against each contract orders-service keeps for
+// it in orders-service/contracts/products-service. A new contract there needs a check here.
+var ordersContractChecks = map[string]func(t *testing.T, c *contracts.Contract){
+	"inventory-check": func(t *testing.T, c *contracts.Contract) {
+		var req models.StockCheckRequest
+		checkContractRequest(t, c, &req)
+
+		costPrice := "12.50"
+		message := "All items in stock"
+		checkContractResponse(t, c, models.StockCheckResponse{
+			Success:    true,
+			AllInStock: true,
+			Results: []models.StockCheckResult{{
+				ProductID:   uuid.NewString(),
+				Available:   true,
+				InStock:     40,
+				Requested:   2,
+				ProductName: "Linen Shirt",
+				CostPrice:   &costPrice,
+			}},
+			Message: &message,
+		})
+	},
+	// Bulk inventory responses are built inline by the handler; orders-service only reads the status
+	"inventory-bulk-deduct": func(t *testing.T, c *contracts.Contract) {
+		var req models.BulkInventoryRequest
+		checkContractRequest(t, c, &req)
+	},
+	"inventory-bulk-restore": func(t *testing.T, c *contracts.Contract) {
+		var req models.BulkInventoryRequest
+		checkContractRequest(t, c, &req)
+	},
+	"product": func(t *testing.T, c *contracts.Contract) {
+		weight := "0.35"
+		checkContractResponse(t, c, models.ProductResponse{
+			Success: true,
+			Data: &models.Product{
+				ID:         uuid.New(),
+				Name:       "Linen Shirt",
+				SKU:        "LS-001",
+				CategoryID: uuid.NewString(),
+				Price:      "49.00",
+				VendorID:   "vendor-1",
+				Weight:     &weight,
+				Dimensions: &models.JSON{"length": "30", "width": "25", "height": "3", "unit": "cm"},
+			},
+		})
+	},
+}
+
+// TestOrdersServiceContracts checks products-service still accepts what orders-service sends
+// and returns what it reads
+func TestOrdersServiceContracts(t *testing.T) {
+	all, err := contracts.Load(os.DirFS("../../../orders-service/contracts"), "products-service")
+	if err != nil {
+		t.Fatalf("failed to load orders-service contracts: %v", err)
+	}
+	for i := range all {
+		contract := &all[i]
+		t.Run(contract.ID(), func(t *testing.T) {
+			check, ok := ordersContractChecks[contract.Name]
+			if !ok {
+				t.Fatalf("no products-service check for %s", contract.ID())
+			}
+			check(t, contract)
+		})
+	}
+}
+
+func checkContractRequest(t *testing.T, c *contracts.Contract, req interface{}) {
+	t.Helper()
+	if err := c.DecodeRequest(req); err != nil {
+		t.Fatalf("request doesn't decode: %v", err)
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		t.Errorf("request fails validation: %v", err)
+	}
+}
+
+func checkContractResponse(t *testing.T, c *contracts.Contract, resp interface{}) {
+	t.Helper()
+	if err := c.VerifyResponse(resp); err != nil {
+		t.Errorf("response doesn't give orders-service what it expects: %v", err)
+	}
+}
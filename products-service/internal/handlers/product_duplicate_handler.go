@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"products-service/internal/models"
+	"products-service/internal/repository"
+)
+
+// GetDuplicatePolicy returns the tenant's duplicate detection policy
+// GET /api/v1/products/duplicates/policy
+func (h *ProductsHandler) GetDuplicatePolicy(c *gin.Context) {
+	policy, err := h.repo.GetDuplicatePolicy(c.GetString("tenant_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve duplicate policy",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    policy,
+	})
+}
+
+// UpdateDuplicatePolicy replaces the tenant's duplicate detection policy
+// PUT /api/v1/products/duplicates/policy
+func (h *ProductsHandler) UpdateDuplicatePolicy(c *gin.Context) {
+	if gosharedmw.GetVendorScopeFilter(c) != "" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FORBIDDEN",
+				Message: "Vendor users cannot change the duplicate policy",
+			},
+		})
+		return
+	}
+
+	var req models.UpdateDuplicatePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	policy := &models.ProductDuplicatePolicy{
+		TenantID:        c.GetString("tenant_id"),
+		Mode:            req.Mode,
+		MatchSKU:        req.MatchSKU,
+		MatchGTIN:       req.MatchGTIN,
+		MatchTitleBrand: req.MatchTitleBrand,
+		CrossVendor:     req.CrossVendor,
+		UpdatedBy:       stringPtr(c.GetString("user_id")),
+		UpdatedAt:       time.Now(),
+	}
+	if err := h.repo.SaveDuplicatePolicy(policy); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_FAILED",
+				Message: "Failed to save duplicate policy",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    policy,
+	})
+}
+
+// GetDuplicateClusters lists probable duplicate clusters as merge suggestions
+// GET /api/v1/products/duplicates
+func (h *ProductsHandler) GetDuplicateClusters(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	// Vendors only see clusters within their own catalog
+	vendorID := gosharedmw.GetVendorScopeFilter(c)
+	if vendorID == "" {
+		vendorID = c.Query("vendorId")
+	}
+
+	policy, err := h.repo.GetDuplicatePolicy(tenantID)
+	if err == nil && vendorID != "" {
+		policy.CrossVendor = false
+	}
+	var clusters []models.DuplicateCluster
+	if err == nil {
+		clusters, err = h.repo.GetDuplicateClusters(tenantID, vendorID, policy, limit)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve duplicate suggestions",
+			},
+		})
+		return
+	}
+
+	if clusters == nil {
+		clusters = []models.DuplicateCluster{}
+	}
+	c.JSON(http.StatusOK, models.DuplicateClusterListResponse{
+		Success: true,
+		Data:    clusters,
+		Total:   len(clusters),
+	})
+}
+
+// ResolveDuplicates merges a duplicate cluster into one product or dismisses it
+// POST /api/v1/products/duplicates/resolve
+func (h *ProductsHandler) ResolveDuplicates(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.ResolveDuplicatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(req.ProductIDs))
+	seen := make(map[uuid.UUID]bool, len(req.ProductIDs))
+	for _, idStr := range req.ProductIDs {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "INVALID_ID",
+					Message: "Invalid product ID format: " + idStr,
+					Field:   "productIds",
+				},
+			})
+			return
+		}
+		if !seen[id] {
+			seen[id] = true
+			productIDs = append(productIDs, id)
+		}
+	}
+
+	var primaryID *uuid.UUID
+	if req.Action == models.DuplicateResolutionMerge {
+		if req.PrimaryProductID == nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "VALIDATION_ERROR",
+					Message: "primaryProductId is required to merge duplicates",
+					Field:   "primaryProductId",
+				},
+			})
+			return
+		}
+		id, err := uuid.Parse(*req.PrimaryProductID)
+		if err != nil || !seen[id] {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "VALIDATION_ERROR",
+					Message: "primaryProductId must be one of productIds",
+					Field:   "primaryProductId",
+				},
+			})
+			return
+		}
+		primaryID = &id
+	}
+
+	products, err := h.repo.BatchGetProductsByIDs(tenantID, productIDs, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve products",
+			},
+		})
+		return
+	}
+	vendorScope := gosharedmw.GetVendorScopeFilter(c)
+	found := 0
+	for _, p := range products {
+		if vendorScope == "" || p.VendorID == vendorScope {
+			found++
+		}
+	}
+	if found != len(productIDs) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "One or more products not found",
+				Field:   "productIds",
+			},
+		})
+		return
+	}
+
+	if err := h.repo.ResolveDuplicates(tenantID, &req, productIDs, primaryID, c.GetString("user_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_FAILED",
+				Message: "Failed to resolve duplicates: " + err.Error(),
+			},
+		})
+		return
+	}
+
+	message := "Products marked as not duplicates"
+	if req.Action == models.DuplicateResolutionMerge {
+		message = fmt.Sprintf("Merged %d duplicate(s) into %s", len(productIDs)-1, primaryID.String())
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+	})
+}
+
+// findDuplicates runs the tenant's duplicate checks for a new product. A failed check is
+// logged and treated as no duplicates so it never blocks product creation.
+func findDuplicates(repo *repository.ProductsRepository, tenantID string, product *models.Product) ([]models.DuplicateMatch, *models.ProductDuplicatePolicy) {
+	policy, err := repo.GetDuplicatePolicy(tenantID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load duplicate policy for tenant %s: %v\n", tenantID, err)
+		return nil, nil
+	}
+	if !policy.Enabled() {
+		return nil, policy
+	}
+
+	matches, err := repo.FindDuplicates(tenantID, policy, []*models.Product{product})
+	if err != nil {
+		fmt.Printf("Warning: Duplicate check failed for product %s: %v\n", product.SKU, err)
+		return nil, policy
+	}
+	return matches[0], policy
+}
+
+// describeDuplicates summarises duplicate matches for import row errors and warnings
+func describeDuplicates(matches []models.DuplicateMatch) string {
+	parts := make([]string, len(matches))
+	for i, m := range matches {
+		parts[i] = fmt.Sprintf("%s (%s)", m.SKU, m.MatchType)
+	}
+	return "Probable duplicate of existing product(s): " + strings.Join(parts, ", ")
+}
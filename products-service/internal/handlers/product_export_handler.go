@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+	"products-service/internal/clients"
+	"products-service/internal/models"
+)
+
+const (
+	// maxExportProducts caps a single export; narrow the filters to export more
+	maxExportProducts = 10000
+	exportPageSize    = 500
+)
+
+var exportProductColumns = []string{
+	"id", "sku", "name", "brand", "gtin", "vendorId", "categoryId", "status", "price", "comparePrice",
+	"currencyCode", "quantity", "inventoryStatus", "createdAt", "updatedAt",
+}
+
+// exportedProduct is a product in a JSON export, with its review stats when requested
+type exportedProduct struct {
+	models.Product
+	ReviewStats *clients.ProductReviewStats `json:"reviewStats,omitempty"`
+}
+
+// ExportProducts exports the products matching the filters as CSV, Excel or JSON.
+// With includeReviews, each row also carries review stats and top review snippets from reviews-service.
+// POST /api/v1/products/export
+func (h *ProductsHandler) ExportProducts(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.ExportProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	if req.Format != "csv" && req.Format != "xlsx" && req.Format != "json" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "format must be one of csv, xlsx, json",
+				Field:   "format",
+			},
+		})
+		return
+	}
+
+	filters := req.Filters
+	if filters == nil {
+		filters = &models.SearchProductsRequest{}
+	}
+	// Vendors only export their own catalog
+	if vendorScope := gosharedmw.GetVendorScopeFilter(c); vendorScope != "" {
+		filters.VendorID = &vendorScope
+	}
+	filters.IncludeVariants = nil
+	if req.Format == "json" {
+		filters.IncludeVariants = req.IncludeVariants
+	}
+	filters.Limit = exportPageSize
+
+	var products []models.Product
+	for page := 1; ; page++ {
+		filters.Page = page
+		batch, total, err := h.repo.GetProducts(tenantID, filters)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "FETCH_FAILED",
+					Message: "Failed to retrieve products",
+				},
+			})
+			return
+		}
+		if total > maxExportProducts {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "EXPORT_TOO_LARGE",
+					Message: fmt.Sprintf("%d products match the filters; narrow them to export at most %d", total, maxExportProducts),
+					Field:   "filters",
+				},
+			})
+			return
+		}
+		products = append(products, batch...)
+		if len(batch) < exportPageSize || int64(len(products)) >= total {
+			break
+		}
+	}
+
+	var reviewStats map[string]clients.ProductReviewStats
+	if req.IncludeReviews {
+		productIDs := make([]string, len(products))
+		for i, p := range products {
+			productIDs[i] = p.ID.String()
+		}
+		// A reviews-service outage shouldn't fail the export; the review columns are left blank
+		stats, err := h.reviewsClient.GetProductReviewStats(tenantID, productIDs, req.ReviewSnippets)
+		if err != nil {
+			fmt.Printf("Warning: Failed to fetch review stats for export (tenant %s): %v\n", tenantID, err)
+			c.Header("X-Export-Warning", "Review stats unavailable")
+		} else {
+			reviewStats = stats
+		}
+	}
+
+	if req.Format == "json" {
+		data := make([]exportedProduct, len(products))
+		for i := range products {
+			data[i].Product = products[i]
+			if stats, ok := reviewStats[products[i].ID.String()]; ok {
+				data[i].ReviewStats = &stats
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    data,
+			"total":   len(data),
+		})
+		return
+	}
+
+	includeImages := req.IncludeImages != nil && *req.IncludeImages
+	headers := append([]string{}, exportProductColumns...)
+	if includeImages {
+		headers = append(headers, "images")
+	}
+	if req.IncludeReviews {
+		headers = append(headers, "reviewCount", "averageRating", "rating1", "rating2", "rating3", "rating4", "rating5")
+		for i := 1; i <= req.ReviewSnippets; i++ {
+			headers = append(headers,
+				fmt.Sprintf("review%dRating", i),
+				fmt.Sprintf("review%dTitle", i),
+				fmt.Sprintf("review%dExcerpt", i),
+			)
+		}
+	}
+
+	rows := make([][]string, len(products))
+	for i, p := range products {
+		rows[i] = exportProductRow(&p)
+		if includeImages {
+			rows[i] = append(rows[i], exportImageURLs(p.Images))
+		}
+		if req.IncludeReviews {
+			rows[i] = append(rows[i], exportReviewColumns(reviewStats, p.ID.String(), req.ReviewSnippets)...)
+		}
+	}
+
+	filename := fmt.Sprintf("products_export_%s.%s", time.Now().Format("20060102_150405"), req.Format)
+	if req.Format == "xlsx" {
+		writeXLSXExport(c, filename, headers, rows)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+
+	writer := csv.NewWriter(c.Writer)
+	defer writer.Flush()
+
+	writer.Write(headers)
+	for _, row := range rows {
+		writer.Write(row)
+	}
+}
+
+// exportProductRow renders a product's values in exportProductColumns order
+func exportProductRow(p *models.Product) []string {
+	quantity := ""
+	if p.Quantity != nil {
+		quantity = strconv.Itoa(*p.Quantity)
+	}
+	inventoryStatus := ""
+	if p.InventoryStatus != nil {
+		inventoryStatus = string(*p.InventoryStatus)
+	}
+
+	return []string{
+		p.ID.String(),
+		p.SKU,
+		p.Name,
+		derefString(p.Brand),
+		derefString(p.Gtin),
+		p.VendorID,
+		p.CategoryID,
+		string(p.Status),
+		p.Price,
+		derefString(p.ComparePrice),
+		derefString(p.CurrencyCode),
+		quantity,
+		inventoryStatus,
+		p.CreatedAt.Format(time.RFC3339),
+		p.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// exportImageURLs joins a product's image URLs with "|"
+func exportImageURLs(images *models.JSONArray) string {
+	if images == nil {
+		return ""
+	}
+	var urls []string
+	for _, img := range *images {
+		if m, ok := img.(map[string]interface{}); ok {
+			if url, ok := m["url"].(string); ok && url != "" {
+				urls = append(urls, url)
+			}
+		}
+	}
+	return strings.Join(urls, "|")
+}
+
+// exportReviewColumns renders the review stat and snippet columns for a product. Products
+// without approved reviews get a zero count; blank columns mean the stats were unavailable.
+func exportReviewColumns(reviewStats map[string]clients.ProductReviewStats, productID string, snippets int) []string {
+	columns := make([]string, 7+3*snippets)
+	if reviewStats == nil {
+		return columns
+	}
+
+	stats, ok := reviewStats[productID]
+	if !ok {
+		columns[0] = "0"
+		return columns
+	}
+
+	columns[0] = strconv.Itoa(stats.ReviewCount)
+	columns[1] = strconv.FormatFloat(stats.AverageRating, 'f', 2, 64)
+	for rating := 1; rating <= 5; rating++ {
+		columns[1+rating] = strconv.Itoa(stats.ByRating[strconv.Itoa(rating)])
+	}
+	for i, snippet := range stats.TopSnippets {
+		if i >= snippets {
+			break
+		}
+		if snippet.Rating != nil {
+			columns[7+3*i] = strconv.FormatFloat(*snippet.Rating, 'f', -1, 64)
+		}
+		columns[8+3*i] = derefString(snippet.Title)
+		columns[9+3*i] = snippet.Excerpt
+	}
+	return columns
+}
+
+// writeXLSXExport writes export rows as a single-sheet Excel download
+func writeXLSXExport(c *gin.Context, filename string, headers []string, rows [][]string) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheetName := "Products"
+	f.SetSheetName("Sheet1", sheetName)
+
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"4472C4"}, Pattern: 1},
+		Border: []excelize.Border{
+			{Type: "bottom", Color: "000000", Style: 1},
+		},
+	})
+
+	f.SetSheetRow(sheetName, "A1", &headers)
+	lastHeader, _ := excelize.CoordinatesToCellName(len(headers), 1)
+	f.SetCellStyle(sheetName, "A1", lastHeader, headerStyle)
+
+	for i := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		f.SetSheetRow(sheetName, cell, &rows[i])
+	}
+
+	lastCol, _ := excelize.ColumnNumberToName(len(headers))
+	f.SetColWidth(sheetName, "A", lastCol, 20)
+
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+
+	f.Write(c.Writer)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
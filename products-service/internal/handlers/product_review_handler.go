@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"products-service/internal/models"
+)
+
+// GetReviewQueue lists vendor products waiting for a reviewer, oldest submission first
+// GET /api/v1/products/review-queue
+func (h *ApprovalProductsHandler) GetReviewQueue(c *gin.Context) {
+	if !requireReviewer(c) {
+		return
+	}
+	tenantID := c.GetString("tenant_id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	products, total, err := h.repo.GetReviewQueue(tenantID, c.Query("vendorId"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve review queue",
+			},
+		})
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	c.JSON(http.StatusOK, models.ProductListResponse{
+		Success: true,
+		Data:    products,
+		Pagination: &models.PaginationInfo{
+			Page:        page,
+			Limit:       limit,
+			Total:       total,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	})
+}
+
+// RequestProductChanges sends a pending product back to its vendor with per-field change requests
+// POST /api/v1/products/:id/request-changes
+func (h *ApprovalProductsHandler) RequestProductChanges(c *gin.Context) {
+	if !requireReviewer(c) {
+		return
+	}
+	tenantID := c.GetString("tenant_id")
+	userID := c.GetString("user_id")
+	userName := c.GetString("username")
+
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var req models.RequestProductChangesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	for _, change := range req.Changes {
+		if !models.ReviewableProductFields[change.Field] {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "VALIDATION_ERROR",
+					Message: fmt.Sprintf("Unknown product field: %s", change.Field),
+					Field:   "changes.field",
+				},
+			})
+			return
+		}
+	}
+
+	product, err := h.repo.GetProductByID(tenantID, productID, false)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Product not found",
+			},
+		})
+		return
+	}
+
+	changes := make([]models.ProductChangeRequest, len(req.Changes))
+	fields := make([]string, len(req.Changes))
+	for i, change := range req.Changes {
+		changes[i] = models.ProductChangeRequest{
+			TenantID:        tenantID,
+			ProductID:       productID,
+			VendorID:        product.VendorID,
+			Field:           change.Field,
+			Comment:         change.Comment,
+			Status:          models.ChangeRequestOpen,
+			RequestedByID:   userID,
+			RequestedByName: userName,
+		}
+		fields[i] = change.Field
+	}
+
+	updated, err := h.repo.RequestChanges(tenantID, productID, changes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_FAILED",
+				Message: "Failed to record change requests: " + err.Error(),
+			},
+		})
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_STATUS",
+				Message: fmt.Sprintf("Product is not in PENDING status (current: %s)", product.Status),
+			},
+		})
+		return
+	}
+
+	// Mirror the decision in approval-service so the request leaves the approvers' inbox.
+	// The product has already left PENDING, so a later approval of this request won't publish it.
+	if product.ApprovalRequestID != nil {
+		comment := "Changes requested: " + strings.Join(fields, ", ")
+		if req.Comment != "" {
+			comment += ". " + req.Comment
+		}
+		resp, err := h.approvalClient.RequestChanges(*product.ApprovalRequestID, tenantID, userID, c.GetString("user_role"), userName, c.GetString("user_email"), comment)
+		if err != nil || resp == nil || !resp.Success {
+			fmt.Printf("RequestProductChanges: failed to update approval %s for product %s: %v\n", *product.ApprovalRequestID, productID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Changes requested from vendor",
+		"status":  models.ProductStatusChangesRequested,
+		"data":    changes,
+	})
+}
+
+// ApproveProduct approves a pending product through approval-service and publishes it once
+// every approver in the workflow has approved
+// POST /api/v1/products/:id/approve
+func (h *ApprovalProductsHandler) ApproveProduct(c *gin.Context) {
+	if !requireReviewer(c) {
+		return
+	}
+	tenantID := c.GetString("tenant_id")
+
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReviewProductRequest
+	_ = c.ShouldBindJSON(&req)
+
+	product, err := h.repo.GetProductByID(tenantID, productID, false)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Product not found",
+			},
+		})
+		return
+	}
+	if product.Status != models.ProductStatusPending || product.ApprovalRequestID == nil {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_STATUS",
+				Message: fmt.Sprintf("Product is not awaiting approval (current: %s)", product.Status),
+			},
+		})
+		return
+	}
+	approvalID := *product.ApprovalRequestID
+
+	resp, err := h.approvalClient.ApproveApprovalRequest(
+		approvalID,
+		tenantID,
+		c.GetString("user_id"),
+		c.GetString("user_role"),
+		c.GetString("username"),
+		c.GetString("user_email"),
+		req.Comment,
+	)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "APPROVAL_SERVICE_ERROR",
+				Message: "Failed to approve: " + err.Error(),
+			},
+		})
+		return
+	}
+	if !resp.Success {
+		c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "APPROVAL_FAILED",
+				Message: resp.Error,
+			},
+		})
+		return
+	}
+
+	// Multi-step workflows stay pending until the last approver; the approval event publishes it then
+	if resp.Data == nil || resp.Data.Status != "approved" {
+		c.JSON(http.StatusAccepted, gin.H{
+			"success":     true,
+			"message":     "Approval recorded, waiting for further approvers",
+			"approval_id": approvalID,
+			"status":      "pending_approval",
+		})
+		return
+	}
+
+	if _, err := h.repo.PublishApproved(tenantID, productID, approvalID); err != nil {
+		c.JSON(http.StatusAccepted, gin.H{
+			"success":     true,
+			"message":     "Product approved but status update failed",
+			"approval_id": approvalID,
+			"status":      "approved",
+			"warning":     "Failed to update product status: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"message":     "Product approved and published",
+		"approval_id": approvalID,
+		"status":      "active",
+	})
+}
+
+// GetChangeRequests lists a product's change requests; pass open=true for the ones still to address
+// GET /api/v1/products/:id/change-requests
+func (h *ApprovalProductsHandler) GetChangeRequests(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.repo.GetProductByID(tenantID, productID, false)
+	vendorScope := gosharedmw.GetVendorScopeFilter(c)
+	if err != nil || (vendorScope != "" && product.VendorID != vendorScope) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Product not found",
+			},
+		})
+		return
+	}
+
+	changes, err := h.repo.GetChangeRequests(tenantID, productID, c.Query("open") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve change requests",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    changes,
+	})
+}
+
+// requireReviewer rejects vendor users, who can't review products (including their own)
+func requireReviewer(c *gin.Context) bool {
+	if gosharedmw.GetVendorScopeFilter(c) != "" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FORBIDDEN",
+				Message: "Vendor users cannot review products",
+			},
+		})
+		return false
+	}
+	return true
+}
+
+func parseProductID(c *gin.Context) (uuid.UUID, bool) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid product ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return productID, true
+}
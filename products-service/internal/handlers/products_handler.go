@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -9,10 +11,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"products-service/internal/bulk"
 	"products-service/internal/clients"
 	"products-service/internal/events"
+	"products-service/internal/fieldset"
+	"products-service/internal/middleware"
 	"products-service/internal/models"
 	"products-service/internal/repository"
+	"products-service/internal/search"
+	"gorm.io/gorm"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 )
@@ -21,7 +28,11 @@ type ProductsHandler struct {
 	repo            *repository.ProductsRepository
 	inventoryClient *clients.InventoryClient
 	approvalClient  *clients.ApprovalClient
+	reviewsClient   *clients.ReviewsClient
+	tenantClient    *clients.TenantClient
 	eventsPublisher *events.Publisher
+	searchBackend   search.Backend  // nil without a search index
+	searchIndexer   *search.Indexer // nil without a search index
 }
 
 func NewProductsHandler(repo *repository.ProductsRepository, eventsPublisher *events.Publisher) *ProductsHandler {
@@ -29,10 +40,18 @@ func NewProductsHandler(repo *repository.ProductsRepository, eventsPublisher *ev
 		repo:            repo,
 		inventoryClient: clients.NewInventoryClient(),
 		approvalClient:  clients.NewApprovalClient(),
+		reviewsClient:   clients.NewReviewsClient(),
+		tenantClient:    clients.NewTenantClient(),
 		eventsPublisher: eventsPublisher,
 	}
 }
 
+// SetSearchIndex serves product searches from a search index, falling back to SQL
+func (h *ProductsHandler) SetSearchIndex(backend search.Backend, indexer *search.Indexer) {
+	h.searchBackend = backend
+	h.searchIndexer = indexer
+}
+
 // resolveWarehouse resolves warehouse by ID or Name, auto-creating if needed
 func (h *ProductsHandler) resolveWarehouse(tenantID string, warehouseID, warehouseName *string) (*string, *string, error) {
 	if warehouseID != nil && *warehouseID != "" {
@@ -96,6 +115,18 @@ func (h *ProductsHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
+	if req.HazmatClass != nil && !models.ValidHazmatClass(*req.HazmatClass) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "hazmatClass must be a UN dangerous goods class such as 3 or 2.1",
+				Field:   "hazmatClass",
+			},
+		})
+		return
+	}
+
 	// Validate image count limit (max 12 images per product)
 	if len(req.Images) > models.MediaLimits.MaxGalleryImages {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
@@ -143,6 +174,8 @@ func (h *ProductsHandler) CreateProduct(c *gin.Context) {
 		Name:              req.Name,
 		Slug:              req.Slug,
 		SKU:               req.SKU,
+		Brand:             req.Brand,
+		Gtin:              req.Gtin,
 		Description:       req.Description,
 		Price:             req.Price,
 		ComparePrice:      req.ComparePrice,
@@ -159,6 +192,9 @@ func (h *ProductsHandler) CreateProduct(c *gin.Context) {
 		LowStockThreshold: req.LowStockThreshold,
 		Weight:            req.Weight,
 		Dimensions:        req.Dimensions,
+		HazmatClass:       req.HazmatClass,
+		ContainsBattery:   req.ContainsBattery,
+		AgeRestricted:     req.AgeRestricted,
 		SearchKeywords:    req.SearchKeywords,
 		CurrencyCode:      req.CurrencyCode,
 		Status:            models.ProductStatusDraft,
@@ -241,6 +277,20 @@ func (h *ProductsHandler) CreateProduct(c *gin.Context) {
 		product.OgImage = req.OgImage
 	}
 
+	// Check for probable duplicates per the tenant's policy
+	duplicates, duplicatePolicy := findDuplicates(h.repo, tenantID.(string), product)
+	if len(duplicates) > 0 && duplicatePolicy.Mode == models.DuplicatePolicyBlock {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "DUPLICATE_PRODUCT",
+				Message: "Product looks like a duplicate of an existing product",
+				Details: &models.JSON{"duplicates": duplicates},
+			},
+		})
+		return
+	}
+
 	if err := h.repo.CreateProduct(tenantID.(string), product); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -283,20 +333,26 @@ func (h *ProductsHandler) CreateProduct(c *gin.Context) {
 		}
 	}
 
+	hideProductCost(c, product)
 	response := models.ProductResponse{
-		Success: true,
-		Data:    product,
-		Message: stringPtr("Product created successfully in draft status. Approval request submitted for publication."),
+		Success:    true,
+		Data:       product,
+		Message:    stringPtr("Product created successfully in draft status. Approval request submitted for publication."),
+		Duplicates: duplicates,
 	}
 
 	// Include approval ID in response if available
 	if approvalID != nil {
-		c.JSON(http.StatusAccepted, gin.H{
+		body := gin.H{
 			"success":    true,
 			"data":       product,
 			"message":    "Product created in draft status. Pending approval for publication.",
 			"approvalId": *approvalID,
-		})
+		}
+		if len(duplicates) > 0 {
+			body["duplicates"] = duplicates
+		}
+		c.JSON(http.StatusAccepted, body)
 		return
 	}
 
@@ -328,6 +384,9 @@ func (h *ProductsHandler) GetProducts(c *gin.Context) {
 	if search := c.Query("search"); search != "" {
 		req.Query = &search
 	}
+	if sku := c.Query("sku"); sku != "" {
+		req.SKU = &sku
+	}
 	if categoryID := c.Query("categoryId"); categoryID != "" {
 		req.CategoryID = &categoryID
 	}
@@ -337,15 +396,20 @@ func (h *ProductsHandler) GetProducts(c *gin.Context) {
 	if status := c.Query("status"); status != "" {
 		req.Status = []models.ProductStatus{models.ProductStatus(status)}
 	}
-	if includeVariants := c.Query("includeVariants"); includeVariants == "true" {
-		req.IncludeVariants = boolPtr(true)
-	}
 	if updatedAfter := c.Query("updatedAfter"); updatedAfter != "" {
 		if t, err := time.Parse(time.RFC3339, updatedAfter); err == nil {
 			req.UpdatedAfter = &t
 		}
 	}
 
+	selection, ok := parseProductFieldset(c)
+	if !ok {
+		return
+	}
+	if c.Query("includeVariants") == "true" || selection.Includes("variants") {
+		req.IncludeVariants = boolPtr(true)
+	}
+
 	products, total, err := h.repo.GetProducts(tenantID.(string), req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -372,7 +436,9 @@ func (h *ProductsHandler) GetProducts(c *gin.Context) {
 		HasPrevious: hasPrevious,
 	}
 
-	c.JSON(http.StatusOK, models.ProductListResponse{
+	hideProductCosts(c, products)
+	localizeProducts(c, products)
+	respondWithFieldset(c, selection, models.ProductListResponse{
 		Success:    true,
 		Data:       products,
 		Pagination: pagination,
@@ -396,7 +462,15 @@ func (h *ProductsHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
+	selection, ok := parseProductFieldset(c)
+	if !ok {
+		return
+	}
+
 	includeVariants := c.DefaultQuery("includeVariants", "true") == "true"
+	if selection.Active() {
+		includeVariants = selection.Includes("variants")
+	}
 
 	product, err := h.repo.GetProductByID(tenantID.(string), productID, includeVariants)
 	if err != nil {
@@ -410,12 +484,62 @@ func (h *ProductsHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.ProductResponse{
+	setProductETag(c, product)
+	hideProductCost(c, product)
+	localizeProduct(c, product)
+	if middleware.IsStorefront(c) {
+		h.addStructuredData(product)
+	}
+	respondWithFieldset(c, selection, models.ProductResponse{
 		Success: true,
 		Data:    product,
 	})
 }
 
+// productRelations are the relations a product response can expand
+var productRelations = []string{"variants"}
+
+// parseProductFieldset reads ?fields= and ?include= for a product response, answering 400
+// when they are invalid
+func parseProductFieldset(c *gin.Context) (*fieldset.Selection, bool) {
+	selection, err := fieldset.Parse(c, productRelations...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_FIELDS",
+				Message: err.Error(),
+			},
+		})
+		return nil, false
+	}
+	return selection, true
+}
+
+// respondWithFieldset writes a product response with its data pruned to the selection
+func respondWithFieldset(c *gin.Context, selection *fieldset.Selection, response interface{}) {
+	body, err := selection.FilterIn(response, "data")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "RESPONSE_FAILED",
+				Message: "Failed to build response",
+			},
+		})
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// addStructuredData attaches the product's JSON-LD so storefronts can embed it without
+// assembling it client-side
+func (h *ProductsHandler) addStructuredData(product *models.Product) {
+	productURL := h.tenantClient.BuildStorefrontURL(product.TenantID) +
+		models.SitemapTypeInfo[models.SitemapEntryProduct].PathPrefix + product.ID.String()
+	product.StructuredData = product.BuildStructuredData(productURL)
+}
+
 // BatchGetProducts retrieves multiple products by IDs in a single request
 // GET /api/v1/products/batch?ids=uuid1,uuid2,uuid3
 // Performance: Up to 50x faster than individual requests for bulk operations
@@ -498,6 +622,7 @@ func (h *ProductsHandler) BatchGetProducts(c *gin.Context) {
 	// Build response with found/not found information
 	foundMap := make(map[string]*models.Product)
 	for _, p := range products {
+		hideProductCost(c, p)
 		foundMap[p.ID.String()] = p
 	}
 
@@ -565,6 +690,33 @@ func (h *ProductsHandler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
+	// If-Match takes precedence over expectedVersion in the body
+	if version, present, err := parseIfMatchVersion(c); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_IF_MATCH",
+				Message: err.Error(),
+				Field:   "If-Match",
+			},
+		})
+		return
+	} else if present {
+		req.ExpectedVersion = version
+	}
+
+	if req.HazmatClass != nil && !models.ValidHazmatClass(*req.HazmatClass) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "hazmatClass must be a UN dangerous goods class such as 3 or 2.1",
+				Field:   "hazmatClass",
+			},
+		})
+		return
+	}
+
 	// Validate image count limit (max 12 images per product)
 	if len(req.Images) > models.MediaLimits.MaxGalleryImages {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
@@ -609,6 +761,9 @@ func (h *ProductsHandler) UpdateProduct(c *gin.Context) {
 	if req.Brand != nil {
 		updates.Brand = req.Brand
 	}
+	if req.Gtin != nil {
+		updates.Gtin = req.Gtin
+	}
 	if req.Description != nil {
 		updates.Description = req.Description
 	}
@@ -661,6 +816,15 @@ func (h *ProductsHandler) UpdateProduct(c *gin.Context) {
 	if req.Dimensions != nil {
 		updates.Dimensions = req.Dimensions
 	}
+	if req.HazmatClass != nil {
+		updates.HazmatClass = req.HazmatClass
+	}
+	if req.ContainsBattery != nil {
+		updates.ContainsBattery = req.ContainsBattery
+	}
+	if req.AgeRestricted != nil {
+		updates.AgeRestricted = req.AgeRestricted
+	}
 	if req.SearchKeywords != nil {
 		updates.SearchKeywords = req.SearchKeywords
 	}
@@ -726,7 +890,30 @@ func (h *ProductsHandler) UpdateProduct(c *gin.Context) {
 		updates.OgImage = req.OgImage
 	}
 
-	if err := h.repo.UpdateProduct(tenantID.(string), productID, updates); err != nil {
+	if err := h.repo.UpdateProduct(tenantID.(string), productID, updates, req.ExpectedVersion); err != nil {
+		var conflict *models.VersionConflictError
+		if errors.As(err, &conflict) {
+			setVersionETag(c, conflict.CurrentVersion)
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "VERSION_CONFLICT",
+					Message: "Product was modified by another request",
+					Details: &models.JSON{"currentVersion": conflict.CurrentVersion},
+				},
+			})
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "NOT_FOUND",
+					Message: "Product not found",
+				},
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
@@ -767,6 +954,8 @@ func (h *ProductsHandler) UpdateProduct(c *gin.Context) {
 		_ = h.eventsPublisher.PublishProductUpdated(c.Request.Context(), product, nil, changedFields, tenantID.(string), actor.ActorID, actor.ActorName, actor.ActorEmail, actor.ClientIP, actor.UserAgent)
 	}
 
+	setProductETag(c, product)
+	hideProductCost(c, product)
 	c.JSON(http.StatusOK, models.ProductResponse{
 		Success: true,
 		Data:    product,
@@ -879,6 +1068,92 @@ func (h *ProductsHandler) DeleteProduct(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// GetTrashedProducts lists soft-deleted products that can still be restored
+func (h *ProductsHandler) GetTrashedProducts(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	products, total, err := h.repo.GetDeletedProducts(tenantID.(string), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve deleted products",
+			},
+		})
+		return
+	}
+
+	hideProductCosts(c, products)
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	c.JSON(http.StatusOK, models.ProductListResponse{
+		Success: true,
+		Data:    products,
+		Pagination: &models.PaginationInfo{
+			Page:        page,
+			Limit:       limit,
+			Total:       total,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	})
+}
+
+// RestoreProduct moves a soft-deleted product out of the trash
+func (h *ProductsHandler) RestoreProduct(c *gin.Context) {
+	tenantID, _ := c.Get("tenant_id")
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid product ID format",
+			},
+		})
+		return
+	}
+
+	product, err := h.repo.RestoreProduct(tenantID.(string), productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "NOT_FOUND",
+					Message: "Product not found in trash",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "RESTORE_FAILED",
+				Message: "Failed to restore product",
+			},
+		})
+		return
+	}
+
+	hideProductCost(c, product)
+	c.JSON(http.StatusOK, models.ProductResponse{
+		Success: true,
+		Data:    product,
+	})
+}
+
 // UpdateProductStatus updates product status
 func (h *ProductsHandler) UpdateProductStatus(c *gin.Context) {
 	tenantID, _ := c.Get("tenant_id")
@@ -908,6 +1183,16 @@ func (h *ProductsHandler) UpdateProductStatus(c *gin.Context) {
 		return
 	}
 
+	if !vendorCanSetStatus(c, req.Status) {
+		return
+	}
+
+	// Load the current status for the status change event before it is overwritten
+	var before []*models.Product
+	if h.eventsPublisher != nil {
+		before, _ = h.repo.BatchGetProductsByIDs(tenantID.(string), []uuid.UUID{productID}, false)
+	}
+
 	if err := h.repo.UpdateProductStatus(tenantID.(string), productID, req.Status, req.Notes); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -919,12 +1204,61 @@ func (h *ProductsHandler) UpdateProductStatus(c *gin.Context) {
 		return
 	}
 
+	h.publishStatusChanges(c, tenantID.(string), before, req.Status)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Product status updated successfully",
 	})
 }
 
+// publishStatusChanges publishes an event for each product whose status changed: published when
+// it went ACTIVE, archived when it went ARCHIVED, and status_changed otherwise. Storefront
+// consumers such as the sitemap rely on these.
+func (h *ProductsHandler) publishStatusChanges(c *gin.Context, tenantID string, products []*models.Product, status models.ProductStatus) {
+	if h.eventsPublisher == nil {
+		return
+	}
+	actor := gosharedmw.GetActorInfo(c)
+	ctx := c.Request.Context()
+	for _, product := range products {
+		oldStatus := product.Status
+		if oldStatus == status {
+			continue
+		}
+		product.Status = status
+		switch status {
+		case models.ProductStatusActive:
+			_ = h.eventsPublisher.PublishProductPublished(ctx, product, tenantID, actor.ActorID, actor.ActorName, actor.ActorEmail, actor.ClientIP, actor.UserAgent)
+		case models.ProductStatusArchived:
+			_ = h.eventsPublisher.PublishProductArchived(ctx, product, tenantID, actor.ActorID, actor.ActorName, actor.ActorEmail, actor.ClientIP, actor.UserAgent)
+		default:
+			_ = h.eventsPublisher.PublishProductStatusChanged(ctx, product, string(oldStatus), string(status), tenantID, actor.ActorID, actor.ActorName, actor.ActorEmail, actor.ClientIP, actor.UserAgent)
+		}
+	}
+}
+
+// vendorCanSetStatus stops vendor users from publishing directly: products only become ACTIVE
+// through review, and only submit-for-approval can put them in the review queue
+func vendorCanSetStatus(c *gin.Context, status models.ProductStatus) bool {
+	if gosharedmw.GetVendorScopeFilter(c) == "" {
+		return true
+	}
+	switch status {
+	case models.ProductStatusActive, models.ProductStatusPending, models.ProductStatusRejected, models.ProductStatusChangesRequested:
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "APPROVAL_REQUIRED",
+				Message: "Vendor products are published through review; use POST /products/:id/submit-for-approval",
+				Field:   "status",
+			},
+		})
+		return false
+	}
+	return true
+}
+
 // BulkUpdateStatus updates status for multiple products
 func (h *ProductsHandler) BulkUpdateStatus(c *gin.Context) {
 	tenantID, _ := c.Get("tenant_id")
@@ -941,41 +1275,48 @@ func (h *ProductsHandler) BulkUpdateStatus(c *gin.Context) {
 		return
 	}
 
-	// Convert string IDs to UUIDs
-	productIDs := make([]uuid.UUID, len(req.ProductIDs))
-	for i, idStr := range req.ProductIDs {
-		id, err := uuid.Parse(idStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Success: false,
-				Error: models.Error{
-					Code:    "INVALID_ID",
-					Message: "Invalid product ID format: " + idStr,
-				},
-			})
-			return
-		}
-		productIDs[i] = id
-	}
-
-	if err := h.repo.BulkUpdateStatus(tenantID.(string), productIDs, req.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+	if len(req.ProductIDs) > bulk.MaxItems {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
-				Code:    "BULK_UPDATE_FAILED",
-				Message: "Failed to update product status",
+				Code:    "VALIDATION_ERROR",
+				Message: fmt.Sprintf("Maximum %d products allowed per request", bulk.MaxItems),
 			},
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Products status updated successfully",
-		"summary": gin.H{
-			"updated": len(productIDs),
-		},
-	})
+	if !vendorCanSetStatus(c, req.Status) {
+		return
+	}
+
+	var before []*models.Product
+	if h.eventsPublisher != nil {
+		productIDs := make([]uuid.UUID, 0, len(req.ProductIDs))
+		for _, idStr := range req.ProductIDs {
+			if id, err := uuid.Parse(idStr); err == nil {
+				productIDs = append(productIDs, id)
+			}
+		}
+		before, _ = h.repo.BatchGetProductsByIDs(tenantID.(string), productIDs, false)
+	}
+
+	result := h.repo.BulkUpdateStatus(tenantID.(string), req.ProductIDs, req.Status, bulk.ParseOptions(c))
+
+	// Only products whose update committed announce the change
+	updated := make(map[uuid.UUID]bool, result.SuccessCount)
+	for _, data := range result.Applied() {
+		updated[data.(*models.Product).ID] = true
+	}
+	changed := make([]*models.Product, 0, len(before))
+	for _, product := range before {
+		if updated[product.ID] {
+			changed = append(changed, product)
+		}
+	}
+	h.publishStatusChanges(c, tenantID.(string), changed, req.Status)
+
+	c.JSON(result.HTTPStatus(), result)
 }
 
 // ============================================================================
@@ -1012,12 +1353,12 @@ func (h *ProductsHandler) BulkCreateProducts(c *gin.Context) {
 		return
 	}
 
-	if len(req.Products) > 100 {
+	if len(req.Products) > bulk.MaxItems {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
 				Code:    "VALIDATION_ERROR",
-				Message: "Maximum 100 products allowed per request",
+				Message: fmt.Sprintf("Maximum %d products allowed per request", bulk.MaxItems),
 			},
 		})
 		return
@@ -1080,6 +1421,7 @@ func (h *ProductsHandler) BulkCreateProducts(c *gin.Context) {
 			Slug:              item.Slug,
 			SKU:               item.SKU,
 			Brand:             item.Brand,
+			Gtin:              item.Gtin,
 			Description:       item.Description,
 			Price:             item.Price,
 			ComparePrice:      item.ComparePrice,
@@ -1165,67 +1507,12 @@ func (h *ProductsHandler) BulkCreateProducts(c *gin.Context) {
 		products[i] = product
 	}
 
-	// Perform bulk create
-	result, err := h.repo.BulkCreate(tenantID.(string), products, req.SkipDuplicates)
-	if err != nil && result.Success == 0 {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Success: false,
-			Error: models.Error{
-				Code:    "BULK_CREATE_FAILED",
-				Message: err.Error(),
-			},
-		})
-		return
-	}
-
-	// Build response with consistent pattern
-	results := make([]models.BulkCreateResultItem, 0)
-
-	// Add successful items
-	for _, product := range result.Created {
-		// Find the original index and external ID
-		var externalID *string
-		for i, item := range req.Products {
-			if item.SKU == product.SKU {
-				externalID = item.ExternalID
-				results = append(results, models.BulkCreateResultItem{
-					Index:      i,
-					ExternalID: externalID,
-					Success:    true,
-					Data:       product,
-				})
-				break
-			}
-		}
+	result := h.repo.BulkCreate(tenantID.(string), products, req.SkipDuplicates, bulk.ParseOptions(c))
+	for i := range result.Results {
+		result.Results[i].ExternalID = req.Products[i].ExternalID
 	}
 
-	// Add failed items
-	for _, bulkErr := range result.Errors {
-		var externalID *string
-		if bulkErr.Index < len(req.Products) {
-			externalID = req.Products[bulkErr.Index].ExternalID
-		}
-		results = append(results, models.BulkCreateResultItem{
-			Index:      bulkErr.Index,
-			ExternalID: externalID,
-			Success:    false,
-			Error: &models.Error{
-				Code:    bulkErr.Code,
-				Message: bulkErr.Message,
-			},
-		})
-	}
-
-	// Determine overall success (partial success counts as success)
-	overallSuccess := result.Success > 0
-
-	c.JSON(http.StatusOK, models.BulkCreateProductsResponse{
-		Success:      overallSuccess,
-		TotalCount:   result.Total,
-		SuccessCount: result.Success,
-		FailedCount:  result.Failed,
-		Results:      results,
-	})
+	c.JSON(result.HTTPStatus(), result)
 }
 
 // BulkDeleteProducts deletes multiple products in a single request with optional cascade
@@ -1583,6 +1870,11 @@ func (h *ProductsHandler) CheckStock(c *gin.Context) {
 		})
 		return
 	}
+	if !middleware.CanViewCosts(c) {
+		for i := range results {
+			results[i].CostPrice = nil
+		}
+	}
 
 	// Check if all items are in stock
 	allInStock := true
@@ -1631,7 +1923,17 @@ func (h *ProductsHandler) SearchProducts(c *gin.Context) {
 		req.Limit = 20
 	}
 
-	products, total, err := h.repo.SearchProducts(tenantID.(string), &req)
+	// Storefront searches ranked by relevance get the tenant's boost/bury rules and pins
+	var merchandising *models.SearchMerchandising
+	if middleware.IsStorefront(c) && req.Query != nil && (req.SortBy == nil || *req.SortBy == "") {
+		var err error
+		merchandising, err = h.repo.GetSearchMerchandising(tenantID.(string), *req.Query, time.Now())
+		if err != nil {
+			log.Printf("Warning: Failed to load search merchandising (tenant %s): %v", tenantID, err)
+		}
+	}
+
+	products, total, facets, err := h.searchProducts(c, tenantID.(string), &req, merchandising)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -1657,10 +1959,13 @@ func (h *ProductsHandler) SearchProducts(c *gin.Context) {
 		HasPrevious: hasPrevious,
 	}
 
+	hideProductCosts(c, products)
+	localizeProducts(c, products)
 	c.JSON(http.StatusOK, models.ProductListResponse{
 		Success:    true,
 		Data:       products,
 		Pagination: pagination,
+		Facets:     facets,
 	})
 }
 
@@ -1687,6 +1992,11 @@ func (h *ProductsHandler) GetAnalytics(c *gin.Context) {
 		Trends:       models.ProductsTrends{},
 		TopProducts:  []models.TopProduct{},
 	}
+	if middleware.CanViewCosts(c) {
+		if margins, err := h.repo.GetMarginSummary(tenantID.(string)); err == nil {
+			analytics.Margins = margins
+		}
+	}
 
 	c.JSON(http.StatusOK, models.ProductsAnalyticsResponse{
 		Success: true,
@@ -1774,6 +2084,7 @@ func (h *ProductsHandler) GetVariants(c *gin.Context) {
 		return
 	}
 
+	hideVariantCosts(c, variants)
 	c.JSON(http.StatusOK, gin.H{
 		"data":  variants,
 		"total": total,
@@ -1802,7 +2113,8 @@ func (h *ProductsHandler) UpdateVariant(c *gin.Context) {
 		tenantID = tenantIDVal.(string)
 	}
 
-	if err := h.repo.UpdateProductVariant(tenantID, variantID, &updates); err != nil {
+	userID := c.GetString("user_id")
+	if err := h.repo.UpdateProductVariant(tenantID, variantID, &updates, optionalString(userID)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "An internal error occurred"})
 		return
 	}
@@ -1847,10 +2159,6 @@ func (h *ProductsHandler) DeleteImage(c *gin.Context) {
 }
 
 // Placeholder handlers for other operations
-func (h *ProductsHandler) ExportProducts(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
-}
-
 func (h *ProductsHandler) GetTrendingProducts(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
 }
@@ -1898,6 +2206,7 @@ func (h *ProductsHandler) GetCategories(c *gin.Context) {
 		return
 	}
 
+	localizeCategories(c, categories)
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
 	c.JSON(http.StatusOK, models.CategoryListResponse{
 		Success: true,
@@ -2076,6 +2385,7 @@ func (h *ProductsHandler) GetCategory(c *gin.Context) {
 		return
 	}
 
+	localizeCategory(c, category)
 	c.JSON(http.StatusOK, models.CategoryResponse{
 		Success: true,
 		Data:    category,
@@ -2632,6 +2942,33 @@ func boolPtr(b bool) *bool {
 	return &b
 }
 
+// parseIfMatchVersion reads a product version from the If-Match header ("3", W/"3" or 3).
+// present is false when the header is absent or "*", i.e. the update is unconditional.
+func parseIfMatchVersion(c *gin.Context) (version *int, present bool, err error) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" || raw == "*" {
+		return nil, false, nil
+	}
+	v, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(raw, "W/"), "\""))
+	if err != nil || v < 1 {
+		return nil, true, fmt.Errorf("If-Match must carry a product version, got %q", raw)
+	}
+	return &v, true, nil
+}
+
+// setVersionETag advertises the product version for use in a later If-Match
+func setVersionETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf("\"%d\"", version))
+}
+
+func setProductETag(c *gin.Context, product *models.Product) {
+	version := 1
+	if product.Version != nil {
+		version = *product.Version
+	}
+	setVersionETag(c, version)
+}
+
 func generateSlug(name string) string {
 	// Simple slug generation: lowercase, replace spaces with hyphens
 	slug := name
@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"products-service/internal/models"
+	"products-service/internal/search"
+)
+
+// reindexTimeout bounds a tenant's search index rebuild
+const reindexTimeout = 30 * time.Minute
+
+// searchProducts serves a search from the search index when it can and from PostgreSQL
+// otherwise: without an index, while it is unavailable, for searches it can't answer the same
+// way, and for merchandised searches, whose pins and boost/bury rules only the SQL path
+// applies. A failed index search is answered from SQL. X-Search-Backend says which served it.
+func (h *ProductsHandler) searchProducts(c *gin.Context, tenantID string, req *models.SearchProductsRequest, merchandising *models.SearchMerchandising) ([]models.Product, int64, *models.SearchFacets, error) {
+	if h.searchBackend != nil && h.searchBackend.Available() && merchandising.Empty() && search.Supports(req) {
+		products, total, facets, err := h.searchIndex(c.Request.Context(), tenantID, req)
+		if err == nil {
+			c.Header("X-Search-Backend", "index")
+			return products, total, facets, nil
+		}
+		log.Printf("Warning: Search index failed, falling back to SQL search (tenant %s): %v", tenantID, err)
+	}
+
+	c.Header("X-Search-Backend", "sql")
+	products, total, err := h.repo.SearchProducts(tenantID, req, merchandising)
+	return products, total, nil, err
+}
+
+// searchIndex finds a page of products in the search index and loads them from the database
+func (h *ProductsHandler) searchIndex(ctx context.Context, tenantID string, req *models.SearchProductsRequest) ([]models.Product, int64, *models.SearchFacets, error) {
+	query := &search.Query{TenantID: tenantID, Request: req}
+	if req.Query != nil && strings.TrimSpace(*req.Query) != "" {
+		analysis, err := h.repo.ExpandSearchQuery(tenantID, *req.Query)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		query.Analysis = analysis
+	}
+
+	result, err := h.searchBackend.Search(ctx, query)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	products, err := h.repo.GetProductsInOrder(tenantID, result.ProductIDs, req.IncludeVariants != nil && *req.IncludeVariants)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return products, result.Total, result.Facets, nil
+}
+
+// ReindexSearch rebuilds the tenant's products in the search index in the background, for
+// the initial load and to repair products whose change events were missed
+// POST /api/v1/products/search/reindex
+func (h *ProductsHandler) ReindexSearch(c *gin.Context) {
+	if !h.canManageSearch(c) {
+		return
+	}
+	if h.searchIndexer == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "SEARCH_INDEX_NOT_CONFIGURED",
+				Message: "No search index is configured; searches use the database",
+			},
+		})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reindexTimeout)
+		defer cancel()
+		indexed, err := h.searchIndexer.ReindexTenant(ctx, tenantID)
+		if err != nil {
+			log.Printf("Warning: Search reindex failed after %d products (tenant %s): %v", indexed, tenantID, err)
+			return
+		}
+		log.Printf("Search reindex indexed %d products (tenant %s)", indexed, tenantID)
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"message": "Search reindex started",
+	})
+}
+
+// AnalyzeSearchQuery shows how a search term is normalized, stripped of stop words and
+// expanded with synonyms and fuzzy matches before products are searched
+// GET /api/v1/products/search/analyze?q=
+func (h *ProductsHandler) AnalyzeSearchQuery(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "Query parameter 'q' is required",
+			},
+		})
+		return
+	}
+
+	analysis, err := h.repo.ExplainSearchQuery(c.GetString("tenant_id"), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "ANALYSIS_FAILED",
+				Message: "Failed to analyze search query",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    analysis,
+	})
+}
+
+// GetSearchSettings returns the tenant's search settings
+// GET /api/v1/products/search/settings
+func (h *ProductsHandler) GetSearchSettings(c *gin.Context) {
+	settings, err := h.repo.GetSearchSettings(c.GetString("tenant_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve search settings",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// UpdateSearchSettings replaces the tenant's search settings
+// PUT /api/v1/products/search/settings
+func (h *ProductsHandler) UpdateSearchSettings(c *gin.Context) {
+	if !h.canManageSearch(c) {
+		return
+	}
+
+	var req models.UpdateSearchSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	stopWords := models.NormalizeSynonymTerms(req.StopWords)
+	settings := &models.SearchSettings{
+		TenantID:           c.GetString("tenant_id"),
+		FuzzyEnabled:       req.FuzzyEnabled,
+		FuzzyDistance:      req.FuzzyDistance,
+		FuzzyMinTermLength: req.FuzzyMinTermLength,
+		StopWordsEnabled:   req.StopWordsEnabled,
+		StopWords:          stopWords,
+		UpdatedBy:          stringPtr(c.GetString("user_id")),
+		UpdatedAt:          time.Now(),
+	}
+	if err := h.repo.SaveSearchSettings(settings); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_FAILED",
+				Message: "Failed to save search settings",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// GetSearchSynonyms lists the tenant's synonym groups, optionally only those containing ?term=
+// GET /api/v1/products/search/synonyms
+func (h *ProductsHandler) GetSearchSynonyms(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	synonyms, total, err := h.repo.ListSearchSynonyms(c.GetString("tenant_id"), c.Query("term"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve search synonyms",
+			},
+		})
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	c.JSON(http.StatusOK, models.SearchSynonymListResponse{
+		Success: true,
+		Data:    synonyms,
+		Pagination: &models.PaginationInfo{
+			Page:        page,
+			Limit:       limit,
+			Total:       total,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	})
+}
+
+// CreateSearchSynonym creates a synonym group
+// POST /api/v1/products/search/synonyms
+func (h *ProductsHandler) CreateSearchSynonym(c *gin.Context) {
+	if !h.canManageSearch(c) {
+		return
+	}
+
+	terms, oneWay, ok := bindSearchSynonym(c)
+	if !ok {
+		return
+	}
+
+	userID := stringPtr(c.GetString("user_id"))
+	synonym := &models.SearchSynonym{
+		TenantID:  c.GetString("tenant_id"),
+		Terms:     terms,
+		OneWay:    oneWay,
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+	if err := h.repo.CreateSearchSynonym(synonym); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "CREATE_FAILED",
+				Message: "Failed to create search synonym",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    synonym,
+	})
+}
+
+// UpdateSearchSynonym replaces a synonym group's terms and direction
+// PUT /api/v1/products/search/synonyms/:synonymId
+func (h *ProductsHandler) UpdateSearchSynonym(c *gin.Context) {
+	if !h.canManageSearch(c) {
+		return
+	}
+
+	id, ok := parseSearchID(c, "synonymId", "synonym")
+	if !ok {
+		return
+	}
+	terms, oneWay, ok := bindSearchSynonym(c)
+	if !ok {
+		return
+	}
+
+	synonym, err := h.repo.UpdateSearchSynonym(c.GetString("tenant_id"), id, terms, oneWay, stringPtr(c.GetString("user_id")))
+	if err != nil {
+		respondSearchError(c, err, "Search synonym not found", "UPDATE_FAILED", "Failed to update search synonym")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    synonym,
+	})
+}
+
+// DeleteSearchSynonym deletes a synonym group
+// DELETE /api/v1/products/search/synonyms/:synonymId
+func (h *ProductsHandler) DeleteSearchSynonym(c *gin.Context) {
+	if !h.canManageSearch(c) {
+		return
+	}
+
+	id, ok := parseSearchID(c, "synonymId", "synonym")
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteSearchSynonym(c.GetString("tenant_id"), id); err != nil {
+		respondSearchError(c, err, "Search synonym not found", "DELETE_FAILED", "Failed to delete search synonym")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Search synonym deleted successfully",
+	})
+}
+
+// canManageSearch rejects vendor users, since search tuning applies to the whole storefront
+func (h *ProductsHandler) canManageSearch(c *gin.Context) bool {
+	if gosharedmw.GetVendorScopeFilter(c) == "" {
+		return true
+	}
+	c.JSON(http.StatusForbidden, models.ErrorResponse{
+		Success: false,
+		Error: models.Error{
+			Code:    "FORBIDDEN",
+			Message: "Vendor users cannot change search settings",
+		},
+	})
+	return false
+}
+
+// bindSearchSynonym binds a synonym group request and normalizes its terms
+func bindSearchSynonym(c *gin.Context) ([]string, bool, bool) {
+	var req models.CreateSearchSynonymRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return nil, false, false
+	}
+
+	terms := models.NormalizeSynonymTerms(req.Terms)
+	if len(terms) < 2 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "A synonym group needs at least two distinct terms",
+			},
+		})
+		return nil, false, false
+	}
+	return terms, req.OneWay, true
+}
+
+func parseSearchID(c *gin.Context, param, kind string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid " + kind + " ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondSearchError(c *gin.Context, err error, notFound, code, message string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: notFound,
+			},
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Success: false,
+		Error: models.Error{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"products-service/internal/models"
+)
+
+// GetSearchRules lists the tenant's boost and bury rules
+// GET /api/v1/products/search/rules
+func (h *ProductsHandler) GetSearchRules(c *gin.Context) {
+	page, limit := parsePageLimit(c)
+
+	rules, total, err := h.repo.ListSearchRules(c.GetString("tenant_id"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve search rules",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       rules,
+		"pagination": paginationInfo(page, limit, total),
+	})
+}
+
+// CreateSearchRule creates a boost or bury rule
+// POST /api/v1/products/search/rules
+func (h *ProductsHandler) CreateSearchRule(c *gin.Context) {
+	if !h.canManageSearch(c) {
+		return
+	}
+
+	req, ok := bindSearchRule(c)
+	if !ok {
+		return
+	}
+
+	userID := stringPtr(c.GetString("user_id"))
+	rule := req.Rule(c.GetString("tenant_id"))
+	rule.CreatedBy = userID
+	rule.UpdatedBy = userID
+	if err := h.repo.CreateSearchRule(&rule); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "CREATE_FAILED",
+				Message: "Failed to create search rule",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// UpdateSearchRule replaces a boost or bury rule
+// PUT /api/v1/products/search/rules/:ruleId
+func (h *ProductsHandler) UpdateSearchRule(c *gin.Context) {
+	if !h.canManageSearch(c) {
+		return
+	}
+
+	id, ok := parseSearchID(c, "ruleId", "rule")
+	if !ok {
+		return
+	}
+	req, ok := bindSearchRule(c)
+	if !ok {
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+	rule, err := h.repo.UpdateSearchRule(tenantID, id, req.Rule(tenantID), stringPtr(c.GetString("user_id")))
+	if err != nil {
+		respondSearchError(c, err, "Search rule not found", "UPDATE_FAILED", "Failed to update search rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// DeleteSearchRule deletes a boost or bury rule
+// DELETE /api/v1/products/search/rules/:ruleId
+func (h *ProductsHandler) DeleteSearchRule(c *gin.Context) {
+	if !h.canManageSearch(c) {
+		return
+	}
+
+	id, ok := parseSearchID(c, "ruleId", "rule")
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteSearchRule(c.GetString("tenant_id"), id); err != nil {
+		respondSearchError(c, err, "Search rule not found", "DELETE_FAILED", "Failed to delete search rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Search rule deleted successfully",
+	})
+}
+
+// GetSearchPins lists pinned results, optionally for one search query (?query=)
+// GET /api/v1/products/search/pins
+func (h *ProductsHandler) GetSearchPins(c *gin.Context) {
+	page, limit := parsePageLimit(c)
+
+	pins, total, err := h.repo.ListSearchPins(c.GetString("tenant_id"), c.Query("query"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve search pins",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       pins,
+		"pagination": paginationInfo(page, limit, total),
+	})
+}
+
+// SaveSearchPin pins a product for a search query, or moves it if it's already pinned
+// POST /api/v1/products/search/pins
+func (h *ProductsHandler) SaveSearchPin(c *gin.Context) {
+	if !h.canManageSearch(c) {
+		return
+	}
+
+	var req models.SearchPinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	query := models.NormalizeSearchTerm(req.Query)
+	if query == "" || (req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt)) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "A pin needs a query with at least one word, and endsAt must be after startsAt",
+			},
+		})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+	if _, err := h.repo.GetProductByID(tenantID, req.ProductID, false); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Product not found",
+			},
+		})
+		return
+	}
+
+	userID := stringPtr(c.GetString("user_id"))
+	pin := &models.SearchPin{
+		TenantID:  tenantID,
+		Query:     query,
+		ProductID: req.ProductID,
+		Position:  req.Position,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		CreatedBy: userID,
+		UpdatedBy: userID,
+		UpdatedAt: time.Now(),
+	}
+	if err := h.repo.SaveSearchPin(pin); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_FAILED",
+				Message: "Failed to save search pin",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    pin,
+	})
+}
+
+// DeleteSearchPin unpins a result
+// DELETE /api/v1/products/search/pins/:pinId
+func (h *ProductsHandler) DeleteSearchPin(c *gin.Context) {
+	if !h.canManageSearch(c) {
+		return
+	}
+
+	id, ok := parseSearchID(c, "pinId", "pin")
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteSearchPin(c.GetString("tenant_id"), id); err != nil {
+		respondSearchError(c, err, "Search pin not found", "DELETE_FAILED", "Failed to delete search pin")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Search pin deleted successfully",
+	})
+}
+
+// PreviewSearchMerchandising shows the first page of storefront results for a query under
+// the rules and pins active at a time, with unsaved draft rules added, and why each result
+// is placed where it is
+// POST /api/v1/products/search/merchandising/preview
+func (h *ProductsHandler) PreviewSearchMerchandising(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	var req models.SearchPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	for i := range req.DraftRules {
+		if message := req.DraftRules[i].Validate(); message != "" {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "VALIDATION_ERROR",
+					Message: "Draft rule " + strconv.Itoa(i+1) + ": " + message,
+				},
+			})
+			return
+		}
+	}
+
+	at := time.Now()
+	if req.At != nil {
+		at = *req.At
+	}
+
+	merchandising, err := h.repo.GetSearchMerchandising(tenantID, req.Query, at)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "PREVIEW_FAILED",
+				Message: "Failed to load search merchandising",
+			},
+		})
+		return
+	}
+	query := models.NormalizeSearchTerm(req.Query)
+	for i := range req.DraftRules {
+		rule := req.DraftRules[i].Rule(tenantID)
+		if rule.ActiveAt(at) && rule.AppliesTo(query) {
+			merchandising.Rules = append(merchandising.Rules, rule)
+		}
+	}
+
+	search := models.SearchProductsRequest{}
+	if req.Filters != nil {
+		search = *req.Filters
+	}
+	search.Query = &req.Query
+	search.SortBy = nil
+	search.Page = 1
+	search.Limit = req.Limit
+	if search.Limit < 1 || search.Limit > 100 {
+		search.Limit = 20
+	}
+
+	products, total, err := h.repo.SearchProducts(tenantID, &search, merchandising)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "PREVIEW_FAILED",
+				Message: "Failed to search products",
+			},
+		})
+		return
+	}
+
+	productIDs := make([]uuid.UUID, len(products))
+	for i := range products {
+		productIDs[i] = products[i].ID
+	}
+	matches, err := h.repo.MatchSearchRules(tenantID, merchandising.Rules, productIDs, at)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "PREVIEW_FAILED",
+				Message: "Failed to evaluate search rules",
+			},
+		})
+		return
+	}
+
+	pinned := make(map[uuid.UUID]int, len(merchandising.Pins))
+	for _, pin := range merchandising.Pins {
+		pinned[pin.ProductID] = pin.Position
+	}
+
+	preview := models.SearchPreview{
+		Query:   req.Query,
+		At:      at,
+		Total:   total,
+		Rules:   merchandising.Rules,
+		Pins:    merchandising.Pins,
+		Results: make([]models.SearchPreviewResult, len(products)),
+	}
+	for i := range products {
+		result := models.SearchPreviewResult{
+			Position:        i + 1,
+			ProductID:       products[i].ID,
+			Name:            products[i].Name,
+			SKU:             products[i].SKU,
+			InventoryStatus: products[i].InventoryStatus,
+			MatchedRules:    []models.SearchRuleMatch{},
+		}
+		if position, ok := pinned[products[i].ID]; ok {
+			result.PinnedPosition = &position
+		}
+		for j := range merchandising.Rules {
+			if !matches[j][products[i].ID] {
+				continue
+			}
+			rule := &merchandising.Rules[j]
+			match := models.SearchRuleMatch{Name: rule.Name, Action: rule.Action, Weight: rule.Weight}
+			if rule.ID != uuid.Nil {
+				id := rule.ID
+				match.ID = &id
+			}
+			result.MatchedRules = append(result.MatchedRules, match)
+			result.Score += rule.SignedWeight()
+		}
+		preview.Results[i] = result
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    preview,
+	})
+}
+
+// bindSearchRule binds and validates a search rule request
+func bindSearchRule(c *gin.Context) (*models.SearchRuleRequest, bool) {
+	var req models.SearchRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return nil, false
+	}
+	if message := req.Validate(); message != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: message,
+			},
+		})
+		return nil, false
+	}
+	return &req, true
+}
+
+func parsePageLimit(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
+
+func paginationInfo(page, limit int, total int64) *models.PaginationInfo {
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	return &models.PaginationInfo{
+		Page:        page,
+		Limit:       limit,
+		Total:       total,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"products-service/internal/clients"
+	"products-service/internal/models"
+	"products-service/internal/repository"
+)
+
+// SitemapHandler serves storefront sitemaps and manages their regeneration
+type SitemapHandler struct {
+	repo          *repository.ProductsRepository
+	tenantClient  *clients.TenantClient
+	contentClient *clients.ContentClient
+}
+
+// NewSitemapHandler creates a new sitemap handler
+func NewSitemapHandler(repo *repository.ProductsRepository, tenantClient *clients.TenantClient, contentClient *clients.ContentClient) *SitemapHandler {
+	return &SitemapHandler{
+		repo:          repo,
+		tenantClient:  tenantClient,
+		contentClient: contentClient,
+	}
+}
+
+// GetSitemapIndex returns the storefront's sitemap index, listing a sitemap file per entry type
+// and SitemapMaxURLs URLs. The storefront serves it as /sitemap.xml.
+// GET /api/v1/storefront/sitemap.xml
+func (h *SitemapHandler) GetSitemapIndex(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if !h.ensureSitemap(c, tenantID) {
+		return
+	}
+
+	summary, err := h.repo.GetSitemapSummary(tenantID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to load sitemap")
+		return
+	}
+
+	baseURL := h.tenantClient.BuildStorefrontURL(tenantID)
+	index := models.SitemapIndex{Xmlns: models.SitemapXMLNamespace}
+	for _, s := range summary {
+		lastMod := ""
+		if s.LastModified != nil {
+			lastMod = s.LastModified.UTC().Format(time.RFC3339)
+		}
+		files := int((s.Count + models.SitemapMaxURLs - 1) / models.SitemapMaxURLs)
+		for n := 1; n <= files; n++ {
+			index.Sitemaps = append(index.Sitemaps, models.SitemapRef{
+				Loc:     fmt.Sprintf("%s/sitemaps/%s-%d.xml", baseURL, s.EntityType, n),
+				LastMod: lastMod,
+			})
+		}
+	}
+
+	writeSitemapXML(c, index)
+}
+
+// GetSitemapFile returns one sitemap file from the index, named {entityType}-{n}.xml.
+// The storefront serves it under /sitemaps/.
+// GET /api/v1/storefront/sitemaps/:file
+func (h *SitemapHandler) GetSitemapFile(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	entityType, n, ok := parseSitemapFileName(c.Param("file"))
+	if !ok {
+		c.String(http.StatusNotFound, "Sitemap not found")
+		return
+	}
+	if !h.ensureSitemap(c, tenantID) {
+		return
+	}
+
+	entries, err := h.repo.GetSitemapEntries(tenantID, entityType, (n-1)*models.SitemapMaxURLs, models.SitemapMaxURLs)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to load sitemap")
+		return
+	}
+	if len(entries) == 0 && n > 1 {
+		c.String(http.StatusNotFound, "Sitemap not found")
+		return
+	}
+
+	baseURL := h.tenantClient.BuildStorefrontURL(tenantID)
+	info := models.SitemapTypeInfo[entityType]
+	urlSet := models.SitemapURLSet{
+		Xmlns: models.SitemapXMLNamespace,
+		URLs:  make([]models.SitemapURL, len(entries)),
+	}
+	for i, entry := range entries {
+		urlSet.URLs[i] = models.SitemapURL{
+			Loc:        baseURL + entry.Path,
+			LastMod:    entry.LastModified.UTC().Format(time.RFC3339),
+			ChangeFreq: info.ChangeFreq,
+			Priority:   info.Priority,
+		}
+	}
+
+	writeSitemapXML(c, urlSet)
+}
+
+// GetSitemapStatus reports how many URLs of each type the tenant's sitemap has and when it was
+// last rebuilt
+// GET /api/v1/sitemap
+func (h *SitemapHandler) GetSitemapStatus(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	status, err := h.sitemapStatus(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve sitemap status",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": status})
+}
+
+// RebuildSitemap regenerates the tenant's sitemap from the catalog and content-service now
+// rather than waiting for the next scheduled rebuild
+// POST /api/v1/sitemap/rebuild
+func (h *SitemapHandler) RebuildSitemap(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	if err := h.Rebuild(tenantID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "REBUILD_FAILED",
+				Message: "Failed to rebuild sitemap",
+			},
+		})
+		return
+	}
+
+	status, err := h.sitemapStatus(tenantID)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"success": true, "message": "Sitemap rebuilt"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": status})
+}
+
+// Rebuild regenerates a tenant's sitemap. If content-service can't be reached, the current
+// page entries are kept and only products and categories are regenerated.
+func (h *SitemapHandler) Rebuild(tenantID string) error {
+	var pageEntries []models.SitemapEntry
+	pages, err := h.contentClient.ListPublishedPages(tenantID)
+	if err != nil {
+		log.Printf("Warning: Failed to fetch content pages for sitemap (tenant %s): %v", tenantID, err)
+	} else {
+		pageEntries = make([]models.SitemapEntry, 0, len(pages))
+		for _, page := range pages {
+			if page.RequiresAuth || page.Slug == "" {
+				continue
+			}
+			pageEntries = append(pageEntries, models.SitemapEntry{
+				EntityID:     page.ID,
+				Path:         models.SitemapTypeInfo[models.SitemapEntryPage].PathPrefix + page.Slug,
+				LastModified: page.UpdatedAt,
+			})
+		}
+	}
+
+	return h.repo.RebuildSitemap(tenantID, pageEntries)
+}
+
+// ensureSitemap builds a tenant's sitemap the first time it is requested
+func (h *SitemapHandler) ensureSitemap(c *gin.Context, tenantID string) bool {
+	sitemap, err := h.repo.GetStorefrontSitemap(tenantID)
+	if err == nil && sitemap == nil {
+		err = h.Rebuild(tenantID)
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to load sitemap")
+		return false
+	}
+	return true
+}
+
+func (h *SitemapHandler) sitemapStatus(tenantID string) (*models.SitemapStatus, error) {
+	sitemap, err := h.repo.GetStorefrontSitemap(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := h.repo.GetSitemapSummary(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.SitemapStatus{
+		StorefrontURL: h.tenantClient.BuildStorefrontURL(tenantID),
+		Types:         summary,
+	}
+	if sitemap != nil {
+		status.LastRebuiltAt = &sitemap.LastRebuiltAt
+		status.PagesSyncedAt = sitemap.PagesSyncedAt
+	}
+	return status, nil
+}
+
+// parseSitemapFileName splits a sitemap file name such as "product-2.xml" into its entry type
+// and 1-based file number
+func parseSitemapFileName(name string) (models.SitemapEntryType, int, bool) {
+	name, ok := strings.CutSuffix(name, ".xml")
+	if !ok {
+		return "", 0, false
+	}
+	sep := strings.LastIndex(name, "-")
+	if sep < 0 {
+		return "", 0, false
+	}
+	entityType := models.SitemapEntryType(name[:sep])
+	if _, known := models.SitemapTypeInfo[entityType]; !known {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(name[sep+1:])
+	if err != nil || n < 1 {
+		return "", 0, false
+	}
+	return entityType, n, true
+}
+
+func writeSitemapXML(c *gin.Context, v interface{}) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to render sitemap")
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", append([]byte(xml.Header), data...))
+}
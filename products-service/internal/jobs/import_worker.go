@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"products-service/internal/models"
+	"products-service/internal/repository"
+)
+
+// ImportJobProcessor executes a single claimed import job
+type ImportJobProcessor interface {
+	ProcessImportJob(ctx context.Context, job *models.ImportJob) error
+}
+
+// ImportWorker polls for queued product import jobs and runs them one at a time.
+// Jobs are claimed with row locks, so running the worker on every replica is safe.
+type ImportWorker struct {
+	repo      *repository.ImportJobRepository
+	processor ImportJobProcessor
+	logger    *logrus.Logger
+	interval  time.Duration
+	stopCh    chan struct{}
+}
+
+// NewImportWorker creates a new import worker
+func NewImportWorker(repo *repository.ImportJobRepository, processor ImportJobProcessor, logger *logrus.Logger) *ImportWorker {
+	return &ImportWorker{
+		repo:      repo,
+		processor: processor,
+		logger:    logger,
+		interval:  5 * time.Second,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start begins polling for import jobs until Stop is called or ctx is cancelled
+func (w *ImportWorker) Start(ctx context.Context) {
+	w.logger.Info("Import worker started")
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.drainQueue(ctx)
+		case <-w.stopCh:
+			w.logger.Info("Import worker stopped")
+			return
+		case <-ctx.Done():
+			w.logger.Info("Import worker context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *ImportWorker) Stop() {
+	close(w.stopCh)
+}
+
+// drainQueue runs claimed jobs until the queue is empty or the worker is stopping
+func (w *ImportWorker) drainQueue(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		job, err := w.repo.ClaimNext(models.ImportJobStaleAfter)
+		if err != nil {
+			w.logger.Errorf("Failed to claim import job: %v", err)
+			return
+		}
+		if job == nil {
+			return
+		}
+
+		w.logger.Infof("Processing import job %s for tenant %s (resuming at row %d, attempt %d)",
+			job.ID, job.TenantID, job.NextRow, job.Attempts)
+
+		if err := w.processor.ProcessImportJob(ctx, job); err != nil {
+			if ctx.Err() != nil {
+				// Shutting down mid-job: hand the job back so another replica resumes it
+				if reqErr := w.repo.Requeue(job.ID); reqErr != nil {
+					w.logger.Errorf("Failed to requeue import job %s: %v", job.ID, reqErr)
+				}
+				return
+			}
+			w.logger.Errorf("Import job %s failed: %v", job.ID, err)
+			continue
+		}
+
+		w.logger.Infof("Import job %s finished", job.ID)
+	}
+}
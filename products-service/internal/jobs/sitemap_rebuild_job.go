@@ -0,0 +1,83 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"products-service/internal/repository"
+)
+
+// SitemapRebuilder regenerates one tenant's sitemap
+type SitemapRebuilder interface {
+	Rebuild(tenantID string) error
+}
+
+// SitemapRebuildJob periodically rebuilds sitemaps that haven't been rebuilt within maxAge.
+// Product and category entries are kept current incrementally; the rebuild corrects any drift
+// and picks up content-service pages, which don't publish events.
+type SitemapRebuildJob struct {
+	repo      *repository.ProductsRepository
+	rebuilder SitemapRebuilder
+	logger    *logrus.Logger
+	interval  time.Duration
+	maxAge    time.Duration
+	batchSize int
+	stopCh    chan struct{}
+}
+
+// NewSitemapRebuildJob creates a new sitemap rebuild job
+func NewSitemapRebuildJob(repo *repository.ProductsRepository, rebuilder SitemapRebuilder, logger *logrus.Logger) *SitemapRebuildJob {
+	return &SitemapRebuildJob{
+		repo:      repo,
+		rebuilder: rebuilder,
+		logger:    logger,
+		interval:  10 * time.Minute,
+		maxAge:    time.Hour,
+		batchSize: 50,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start rebuilds stale sitemaps until Stop is called or ctx is cancelled
+func (j *SitemapRebuildJob) Start(ctx context.Context) {
+	j.logger.Info("Sitemap rebuild job started")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.rebuildStale(ctx)
+		case <-j.stopCh:
+			j.logger.Info("Sitemap rebuild job stopped")
+			return
+		case <-ctx.Done():
+			j.logger.Info("Sitemap rebuild job context cancelled")
+			return
+		}
+	}
+}
+
+// Stop signals the job to stop
+func (j *SitemapRebuildJob) Stop() {
+	close(j.stopCh)
+}
+
+func (j *SitemapRebuildJob) rebuildStale(ctx context.Context) {
+	tenantIDs, err := j.repo.GetSitemapTenantsRebuiltBefore(time.Now().Add(-j.maxAge), j.batchSize)
+	if err != nil {
+		j.logger.Errorf("Failed to list stale sitemaps: %v", err)
+		return
+	}
+
+	for _, tenantID := range tenantIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := j.rebuilder.Rebuild(tenantID); err != nil {
+			j.logger.Errorf("Failed to rebuild sitemap for tenant %s: %v", tenantID, err)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/Tesseract-Nexus/go-shared/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionCostsView lets staff see cost prices, cost history and margins
+const PermissionCostsView = "catalog:costs:view"
+
+// CostVisibility records whether the caller may see cost prices: staff holding
+// catalog:costs:view, and internal service calls. Requests that don't pass through it, such
+// as the storefront API, never see them.
+func CostVisibility(hasPermission func(c *gin.Context, permission string) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		visible := c.GetHeader(rbac.InternalServiceHeader) != "" || hasPermission(c, PermissionCostsView)
+		c.Set("costs_visible", visible)
+		c.Next()
+	}
+}
+
+// CanViewCosts reports whether the caller may see cost prices
+func CanViewCosts(c *gin.Context) bool {
+	return c.GetBool("costs_visible")
+}
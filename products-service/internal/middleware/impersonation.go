@@ -0,0 +1,321 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+
+	"products-service/internal/serviceauth"
+)
+
+// ImpersonationHeader carries an impersonation token issued by staff-service
+const ImpersonationHeader = "X-Impersonation-Token"
+
+const (
+	impersonationValidTTL     = 30 * time.Second // Ended sessions stop working within this
+	impersonationInvalidTTL   = 10 * time.Second
+	impersonationCacheMax     = 10000
+	impersonationCheckTimeout = 5 * time.Second
+)
+
+// impersonationSession is staff-service's validation of an impersonation token
+type impersonationSession struct {
+	SessionID    string    `json:"sessionId"`
+	TenantID     string    `json:"tenantId"`
+	AdminStaffID string    `json:"adminStaffId"`
+	AdminUserID  *string   `json:"adminUserId,omitempty"`
+	AdminName    string    `json:"adminName"`
+	AdminEmail   string    `json:"adminEmail"`
+	TargetType   string    `json:"targetType"`
+	VendorID     *string   `json:"vendorId,omitempty"`
+	StaffID      *string   `json:"staffId,omitempty"`
+	TargetName   *string   `json:"targetName,omitempty"`
+	AllowWrites  bool      `json:"allowWrites"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// impersonationAction is one request reported to staff-service's audit trail
+type impersonationAction struct {
+	SessionID  string    `json:"sessionId"`
+	Service    string    `json:"service"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	StatusCode int       `json:"statusCode"`
+	Blocked    bool      `json:"blocked"`
+	IPAddress  *string   `json:"ipAddress,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+type impersonationCacheEntry struct {
+	session   *impersonationSession // nil for invalid tokens
+	expiresAt time.Time
+}
+
+type impersonationAuthenticator struct {
+	validateURL string
+	actionsURL  string
+	httpClient  *http.Client
+
+	mu    sync.Mutex
+	cache map[string]impersonationCacheEntry
+}
+
+// ImpersonationAuth lets a tenant admin holding an impersonation token act as the vendor or
+// staff member the session targets. It must run after authentication and VendorScopeFilter.
+// The token only works for the admin who started the session: vendor sessions scope the
+// request to the vendor, and staff sessions authorize it with the staff member's permissions
+// while actions stay attributed to the admin. Read-only sessions reject writes. Every
+// request, including rejected writes, is reported to staff-service, and the response
+// carries banner headers describing the session.
+func ImpersonationAuth(staffServiceURL string) gin.HandlerFunc {
+	baseURL := strings.TrimSuffix(staffServiceURL, "/") + "/api/v1/internal/impersonation"
+	a := &impersonationAuthenticator{
+		validateURL: baseURL + "/validate",
+		actionsURL:  baseURL + "/actions",
+		httpClient:  &http.Client{Timeout: impersonationCheckTimeout},
+		cache:       make(map[string]impersonationCacheEntry),
+	}
+
+	return func(c *gin.Context) {
+		token := c.GetHeader(ImpersonationHeader)
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		if c.GetString("auth_method") == "api_key" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "forbidden",
+				"message": "API keys cannot impersonate",
+			})
+			return
+		}
+
+		session, err := a.identify(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "service_unavailable",
+				"message": "Unable to validate impersonation session",
+			})
+			return
+		}
+		if session == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "unauthorized",
+				"message": "Invalid, expired or ended impersonation session",
+			})
+			return
+		}
+		if !session.heldBy(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "forbidden",
+				"message": "Impersonation session belongs to another user",
+			})
+			return
+		}
+
+		setImpersonationHeaders(c, session)
+
+		if !session.AllowWrites && !isReadMethod(c.Request.Method) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "impersonation_read_only",
+				"message": "This impersonation session is read-only",
+			})
+			a.report(c, session, true)
+			return
+		}
+
+		setImpersonationContext(c, session)
+		c.Next()
+		a.report(c, session, false)
+	}
+}
+
+// heldBy reports whether the request was authenticated as the admin who started the session
+func (s *impersonationSession) heldBy(c *gin.Context) bool {
+	if s.TenantID != c.GetString("tenant_id") {
+		return false
+	}
+	userID := c.GetString("user_id")
+	if userID != "" && (userID == s.AdminStaffID || (s.AdminUserID != nil && userID == *s.AdminUserID)) {
+		return true
+	}
+	return c.GetString("staff_id") == s.AdminStaffID
+}
+
+// setImpersonationContext narrows the admin's context to the session's target. The user ID
+// and name are left alone so audit logs and created records name the admin.
+func setImpersonationContext(c *gin.Context, session *impersonationSession) {
+	c.Request.Header.Del("X-Vendor-ID")
+	c.Request.Header.Del("x-jwt-claim-vendor-id")
+
+	var authCtx gosharedmw.AuthContext
+	if current := gosharedmw.GetAuthContext(c); current != nil {
+		authCtx = *current
+	}
+	authCtx.IsPlatformOwner = false
+	authCtx.VendorID = ""
+
+	if session.StaffID != nil {
+		authCtx.StaffID = *session.StaffID
+		authCtx.Roles = []string{}
+		c.Set("staff_id", *session.StaffID)
+		c.Set("staffId", *session.StaffID)
+		c.Set("roles", authCtx.Roles)
+	}
+	if session.VendorID != nil && *session.VendorID != "" {
+		authCtx.VendorID = *session.VendorID
+		c.Set("vendor_id", authCtx.VendorID)
+		c.Set("vendorId", authCtx.VendorID)
+		c.Set("vendor_scope_filter", authCtx.VendorID)
+	} else {
+		c.Set("vendor_id", "")
+		c.Set("vendorId", "")
+		c.Set("vendor_scope_filter", "")
+	}
+
+	c.Set(gosharedmw.AuthContextKey, &authCtx)
+	c.Set("impersonation_session_id", session.SessionID)
+	c.Set("impersonator_staff_id", session.AdminStaffID)
+}
+
+// setImpersonationHeaders adds the headers admin UIs use to show the impersonation banner
+func setImpersonationHeaders(c *gin.Context, session *impersonationSession) {
+	c.Header("X-Impersonation-Session", session.SessionID)
+	c.Header("X-Impersonation-Target-Type", session.TargetType)
+	if session.VendorID != nil {
+		c.Header("X-Impersonation-Vendor-ID", *session.VendorID)
+	}
+	if session.TargetName != nil {
+		c.Header("X-Impersonation-Target-Name", *session.TargetName)
+	}
+	c.Header("X-Impersonation-Read-Only", strconv.FormatBool(!session.AllowWrites))
+	c.Header("X-Impersonation-Expires-At", session.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// identify returns the token's session, nil for an invalid token, or an error if
+// staff-service couldn't be asked
+func (a *impersonationAuthenticator) identify(token string) (*impersonationSession, error) {
+	sum := sha256.Sum256([]byte(token))
+	cacheKey := hex.EncodeToString(sum[:])
+
+	a.mu.Lock()
+	entry, ok := a.cache[cacheKey]
+	a.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		if entry.session != nil && !time.Now().Before(entry.session.ExpiresAt) {
+			return nil, nil
+		}
+		return entry.session, nil
+	}
+
+	session, err := a.validate(token)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := impersonationValidTTL
+	if session == nil {
+		ttl = impersonationInvalidTTL
+	}
+	a.mu.Lock()
+	if len(a.cache) >= impersonationCacheMax {
+		a.cache = make(map[string]impersonationCacheEntry)
+	}
+	a.cache[cacheKey] = impersonationCacheEntry{session: session, expiresAt: time.Now().Add(ttl)}
+	a.mu.Unlock()
+	return session, nil
+}
+
+func (a *impersonationAuthenticator) validate(token string) (*impersonationSession, error) {
+	body, err := json.Marshal(map[string]string{"token": token})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, a.validateURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	serviceauth.Sign(req, "products-service")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(gosharedmw.InternalServiceHeader, "products-service")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call staff-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusBadRequest {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("staff-service returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success bool                  `json:"success"`
+		Data    *impersonationSession `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode impersonation validation: %w", err)
+	}
+	if !result.Success || result.Data == nil {
+		return nil, nil
+	}
+	return result.Data, nil
+}
+
+// report sends the request to staff-service's impersonation audit trail in the background
+func (a *impersonationAuthenticator) report(c *gin.Context, session *impersonationSession, blocked bool) {
+	ip := c.ClientIP()
+	action := impersonationAction{
+		SessionID:  session.SessionID,
+		Service:    "products-service",
+		Method:     c.Request.Method,
+		Path:       c.Request.URL.Path,
+		StatusCode: c.Writer.Status(),
+		Blocked:    blocked,
+		IPAddress:  &ip,
+		OccurredAt: time.Now(),
+	}
+
+	go func() {
+		body, err := json.Marshal(map[string]interface{}{"actions": []impersonationAction{action}})
+		if err != nil {
+			return
+		}
+		req, err := http.NewRequest(http.MethodPost, a.actionsURL, bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		serviceauth.Sign(req, "products-service")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(gosharedmw.InternalServiceHeader, "products-service")
+
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			log.Printf("[IMPERSONATION] Failed to report action for session %s: %v", action.SessionID, err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			log.Printf("[IMPERSONATION] staff-service returned status %d reporting action for session %s", resp.StatusCode, action.SessionID)
+		}
+	}()
+}
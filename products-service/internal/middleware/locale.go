@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"products-service/internal/models"
+)
+
+// StorefrontLocale resolves the shopper's locale chain from the locale query parameter
+// (comma-separated, most preferred first) or, without it, the Accept-Language header.
+// Storefront handlers use the chain to return translated product and category content.
+func StorefrontLocale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var locales []string
+		if locale := c.Query("locale"); locale != "" {
+			locales = strings.Split(locale, ",")
+		} else {
+			locales = models.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+		}
+
+		c.Header("Vary", "Accept-Language")
+		if chain := models.LocaleChain(locales); len(chain) > 0 {
+			c.Set("locale_chain", chain)
+		}
+		c.Next()
+	}
+}
+
+// GetLocaleChain retrieves the storefront locale chain from gin context, or nil when no
+// locale was requested
+func GetLocaleChain(c *gin.Context) []string {
+	if chain, ok := c.Get("locale_chain"); ok {
+		if locales, ok := chain.([]string); ok {
+			return locales
+		}
+	}
+	return nil
+}
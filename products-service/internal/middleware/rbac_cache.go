@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Tesseract-Nexus/go-shared/rbac"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DefaultRBACCacheTTL is how long effective permissions fetched from staff-service are reused
+const DefaultRBACCacheTTL = 30 * time.Second
+
+type rbacCacheKey struct {
+	tenantID string
+	vendorID string // Empty when the request has no vendor
+	staffID  uuid.UUID
+}
+
+// RBACPermissionCache bounds how long the go-shared RBAC client reuses effective permissions.
+// The client caches them for 5 minutes and can only drop one entry at a time, so this records
+// the entries requests fill and drops them once they are ttl old, or as soon as staff-service
+// publishes rbac.permissions_changed for the staff member or tenant.
+type RBACPermissionCache struct {
+	client *rbac.Client
+	ttl    time.Duration
+	stopCh chan struct{}
+
+	mu      sync.Mutex
+	entries map[rbacCacheKey]time.Time // When the entry was filled
+}
+
+// NewRBACPermissionCache creates a permission cache for the client. A ttl of 0 uses DefaultRBACCacheTTL.
+func NewRBACPermissionCache(client *rbac.Client, ttl time.Duration) *RBACPermissionCache {
+	if ttl <= 0 {
+		ttl = DefaultRBACCacheTTL
+	}
+	return &RBACPermissionCache{
+		client:  client,
+		ttl:     ttl,
+		stopCh:  make(chan struct{}),
+		entries: make(map[rbacCacheKey]time.Time),
+	}
+}
+
+// Track records the permissions entry used by the request's RBAC checks. It must run after
+// authentication. The entry is recorded again after the request in case it expired and was
+// refilled while the request ran.
+func (p *RBACPermissionCache) Track() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := rbacCacheKeyFor(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		p.record(key)
+		c.Next()
+		p.record(key)
+	}
+}
+
+// Start drops expired entries until the cache is stopped
+func (p *RBACPermissionCache) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.ttl / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.expire(time.Now().Add(-p.ttl))
+		case <-p.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Stop stops expiring entries
+func (p *RBACPermissionCache) Stop() {
+	close(p.stopCh)
+}
+
+// InvalidateStaff drops the cached permissions of staff members in the tenant
+func (p *RBACPermissionCache) InvalidateStaff(tenantID string, staffIDs []uuid.UUID) {
+	ids := make(map[uuid.UUID]bool, len(staffIDs))
+	for _, id := range staffIDs {
+		ids[id] = true
+		p.client.InvalidateCache(tenantID, nil, id)
+	}
+	p.invalidate(func(key rbacCacheKey) bool {
+		return key.tenantID == tenantID && ids[key.staffID]
+	})
+}
+
+// InvalidateTenant drops every cached permission in the tenant
+func (p *RBACPermissionCache) InvalidateTenant(tenantID string) {
+	p.invalidate(func(key rbacCacheKey) bool {
+		return key.tenantID == tenantID
+	})
+}
+
+func (p *RBACPermissionCache) record(key rbacCacheKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.entries[key]; !ok {
+		p.entries[key] = time.Now()
+	}
+}
+
+func (p *RBACPermissionCache) expire(filledBefore time.Time) {
+	p.invalidate(func(key rbacCacheKey) bool {
+		return p.entries[key].Before(filledBefore)
+	})
+}
+
+func (p *RBACPermissionCache) invalidate(match func(key rbacCacheKey) bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key := range p.entries {
+		if !match(key) {
+			continue
+		}
+		var vendorID *string
+		if key.vendorID != "" {
+			vendorID = &key.vendorID
+		}
+		p.client.InvalidateCache(key.tenantID, vendorID, key.staffID)
+		delete(p.entries, key)
+	}
+}
+
+// rbacCacheKeyFor resolves the tenant, vendor and staff ID the same way the RBAC middleware does
+func rbacCacheKeyFor(c *gin.Context) (rbacCacheKey, bool) {
+	key := rbacCacheKey{
+		tenantID: c.GetString("tenant_id"),
+		vendorID: c.GetString("vendor_id"),
+	}
+	if key.tenantID == "" {
+		key.tenantID = c.GetHeader("x-jwt-claim-tenant-id")
+	}
+	if key.vendorID == "" {
+		key.vendorID = c.GetHeader("x-jwt-claim-vendor-id")
+	}
+
+	staffID := c.GetString("staff_id")
+	if staffID == "" {
+		staffID = c.GetString("user_id")
+	}
+	if staffID == "" {
+		staffID = c.GetHeader("x-jwt-claim-sub")
+	}
+	parsed, err := uuid.Parse(staffID)
+	if err != nil {
+		return key, false
+	}
+	key.staffID = parsed
+	return key, true
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// Storefront marks requests made through the public storefront API, so shared handlers can
+// add shopper-facing data such as structured data
+func Storefront() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("storefront", true)
+		c.Next()
+	}
+}
+
+// IsStorefront reports whether the request came through the storefront API
+func IsStorefront(c *gin.Context) bool {
+	return c.GetBool("storefront")
+}
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxBundleComponents bounds how many products a bundle may contain
+const MaxBundleComponents = 20
+
+// BundlePricing is how a bundle's price is set
+type BundlePricing string
+
+const (
+	BundlePricingFixed      BundlePricing = "FIXED"      // The bundle product's own price
+	BundlePricingComponents BundlePricing = "COMPONENTS" // Sum of the components' prices less a discount
+)
+
+// ProductBundle makes a product a kit of other products. The bundle product holds no stock
+// of its own: stock checks, deductions and restores resolve it to its components.
+type ProductBundle struct {
+	ID              uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID        string            `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:idx_product_bundles_product"`
+	ProductID       uuid.UUID         `json:"productId" gorm:"type:uuid;not null;uniqueIndex:idx_product_bundles_product"`
+	Pricing         BundlePricing     `json:"pricing" gorm:"type:varchar(20);not null;default:'FIXED'"`
+	DiscountPercent float64           `json:"discountPercent" gorm:"not null;default:0"` // Off the components' total, COMPONENTS pricing only
+	Components      []BundleComponent `json:"components" gorm:"foreignKey:BundleID;constraint:OnDelete:CASCADE"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+}
+
+func (ProductBundle) TableName() string {
+	return "product_bundles"
+}
+
+// BundleComponent is a product and how many of it one bundle contains
+type BundleComponent struct {
+	ID                 uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	BundleID           uuid.UUID `json:"bundleId" gorm:"type:uuid;not null;index"`
+	ComponentProductID uuid.UUID `json:"componentProductId" gorm:"type:uuid;not null;index"`
+	Quantity           int       `json:"quantity" gorm:"not null"`
+	Position           int       `json:"position" gorm:"not null;default:0"`
+	Product            *Product  `json:"product,omitempty" gorm:"foreignKey:ComponentProductID;constraint:-"`
+}
+
+func (BundleComponent) TableName() string {
+	return "bundle_components"
+}
+
+// BundleComponentInput is one component of a bundle save request
+type BundleComponentInput struct {
+	ProductID uuid.UUID `json:"productId" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required,min=1,max=1000"`
+}
+
+// SaveBundleRequest represents a request to create or replace a product's bundle
+type SaveBundleRequest struct {
+	Pricing         BundlePricing          `json:"pricing" binding:"required,oneof=FIXED COMPONENTS"`
+	DiscountPercent float64                `json:"discountPercent" binding:"min=0,max=100"`
+	Components      []BundleComponentInput `json:"components" binding:"required,min=1,dive"`
+}
+
+// Validate checks the components of a bundle for productID, returning a message for the
+// first problem found. Whether the components exist is checked when the bundle is saved.
+func (r *SaveBundleRequest) Validate(productID uuid.UUID) string {
+	if len(r.Components) > MaxBundleComponents {
+		return fmt.Sprintf("A bundle can have at most %d components", MaxBundleComponents)
+	}
+	seen := make(map[uuid.UUID]bool, len(r.Components))
+	for _, component := range r.Components {
+		if component.ProductID == productID {
+			return "A bundle cannot contain itself"
+		}
+		if seen[component.ProductID] {
+			return "Component " + component.ProductID.String() + " is listed more than once"
+		}
+		seen[component.ProductID] = true
+	}
+	if r.Pricing == BundlePricingFixed && r.DiscountPercent != 0 {
+		return "discountPercent only applies to COMPONENTS pricing"
+	}
+	return ""
+}
+
+// Bundle builds the bundle the request describes, with components in request order
+func (r *SaveBundleRequest) Bundle(tenantID string, productID uuid.UUID) ProductBundle {
+	bundle := ProductBundle{
+		TenantID:        tenantID,
+		ProductID:       productID,
+		Pricing:         r.Pricing,
+		DiscountPercent: r.DiscountPercent,
+		Components:      make([]BundleComponent, len(r.Components)),
+	}
+	for i, component := range r.Components {
+		bundle.Components[i] = BundleComponent{
+			ComponentProductID: component.ProductID,
+			Quantity:           component.Quantity,
+			Position:           i,
+		}
+	}
+	return bundle
+}
+
+// BundlePrice is what a bundle costs and how that was worked out
+type BundlePrice struct {
+	Pricing         BundlePricing `json:"pricing"`
+	ComponentsTotal float64       `json:"componentsTotal"` // Components' prices times their quantities
+	DiscountPercent float64       `json:"discountPercent"`
+	Price           float64       `json:"price"`
+	Savings         float64       `json:"savings"` // Components' total less the bundle price, never negative
+}
+
+// CalculatePrice prices the bundle from its components, which must have their products
+// loaded. fixedPrice is the bundle product's own price, used for FIXED pricing.
+func (b *ProductBundle) CalculatePrice(fixedPrice string) BundlePrice {
+	price := BundlePrice{Pricing: b.Pricing, DiscountPercent: b.DiscountPercent}
+	for _, component := range b.Components {
+		if component.Product == nil {
+			continue
+		}
+		amount, _ := ParseAmount(component.Product.Price)
+		price.ComponentsTotal += amount * float64(component.Quantity)
+	}
+	price.ComponentsTotal = roundAmount(price.ComponentsTotal)
+
+	if b.Pricing == BundlePricingComponents {
+		price.Price = roundAmount(price.ComponentsTotal * (1 - b.DiscountPercent/100))
+	} else {
+		price.Price, _ = ParseAmount(fixedPrice)
+		price.DiscountPercent = 0
+	}
+	if price.ComponentsTotal > price.Price {
+		price.Savings = roundAmount(price.ComponentsTotal - price.Price)
+	}
+	return price
+}
+
+// CostPrice sums the components' cost prices. It is unset if any component has no cost.
+func (b *ProductBundle) CostPrice() *string {
+	total := 0.0
+	for _, component := range b.Components {
+		if component.Product == nil || component.Product.CostPrice == nil {
+			return nil
+		}
+		cost, ok := ParseAmount(*component.Product.CostPrice)
+		if !ok {
+			return nil
+		}
+		total += cost * float64(component.Quantity)
+	}
+	formatted := FormatAmount(total)
+	return &formatted
+}
+
+// Availability is how many whole bundles the components' stock can make. Like any other
+// product, a component without inventory tracking counts as out of stock.
+func (b *ProductBundle) Availability() int {
+	available := -1
+	for _, component := range b.Components {
+		inStock := 0
+		if component.Product != nil && component.Product.Quantity != nil {
+			inStock = *component.Product.Quantity
+		}
+		fits := inStock / component.Quantity
+		if fits < 0 {
+			fits = 0
+		}
+		if available < 0 || fits < available {
+			available = fits
+		}
+	}
+	if available < 0 {
+		return 0
+	}
+	return available
+}
+
+// BundleResponse represents a bundle with its current price
+type BundleResponse struct {
+	Success bool           `json:"success"`
+	Data    *ProductBundle `json:"data"`
+	Price   *BundlePrice   `json:"price,omitempty"`
+}
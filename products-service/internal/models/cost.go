@@ -0,0 +1,157 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CostSource records where a cost price change came from
+type CostSource string
+
+const (
+	CostSourceManual     CostSource = "MANUAL"      // Edited on the product or variant
+	CostSourceLandedCost CostSource = "LANDED_COST" // Landed unit cost of an inventory-service receipt
+)
+
+// ProductCostHistory is one change to a product's or variant's cost price
+type ProductCostHistory struct {
+	ID                uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID          string     `json:"tenantId" gorm:"type:varchar(255);not null;index:idx_product_cost_history_product"`
+	ProductID         uuid.UUID  `json:"productId" gorm:"type:uuid;not null;index:idx_product_cost_history_product"`
+	VariantID         *uuid.UUID `json:"variantId,omitempty" gorm:"type:uuid"`
+	PreviousCostPrice *string    `json:"previousCostPrice,omitempty"`
+	CostPrice         *string    `json:"costPrice,omitempty"`
+	Source            CostSource `json:"source" gorm:"type:varchar(20);not null"`
+	Reference         *string    `json:"reference,omitempty"` // Purchase order ID for landed costs
+	ChangedBy         *string    `json:"changedBy,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt" gorm:"index:idx_product_cost_history_product"`
+}
+
+func (ProductCostHistory) TableName() string {
+	return "product_cost_history"
+}
+
+// CostHistoryResponse represents a page of cost price changes
+type CostHistoryResponse struct {
+	Success    bool                 `json:"success"`
+	Data       []ProductCostHistory `json:"data"`
+	Pagination *PaginationInfo      `json:"pagination"`
+}
+
+// MarginLine is the gross margin of a product, or of one of its variants
+type MarginLine struct {
+	ProductID     uuid.UUID  `json:"productId"`
+	VariantID     *uuid.UUID `json:"variantId,omitempty"`
+	Name          string     `json:"name"`
+	SKU           string     `json:"sku"`
+	Price         float64    `json:"price"`
+	CostPrice     *float64   `json:"costPrice,omitempty"`     // Unset when no cost is recorded
+	Margin        *float64   `json:"margin,omitempty"`        // Price minus cost
+	MarginPercent *float64   `json:"marginPercent,omitempty"` // Margin as a percentage of price
+}
+
+// MarginSummary totals margins across a tenant's sellable products and variants
+type MarginSummary struct {
+	Items                int     `json:"items"`
+	CostedItems          int     `json:"costedItems"`
+	UncostedItems        int     `json:"uncostedItems"`
+	NegativeMarginItems  int     `json:"negativeMarginItems"`
+	AverageMarginPercent float64 `json:"averageMarginPercent"` // Over costed items priced above zero
+
+	pricedItems int
+}
+
+// MarginReport is a page of margin lines with the tenant-wide summary
+type MarginReport struct {
+	Summary MarginSummary `json:"summary"`
+	Lines   []MarginLine  `json:"lines"`
+}
+
+// MarginReportResponse represents response for the margin report
+type MarginReportResponse struct {
+	Success    bool            `json:"success"`
+	Data       *MarginReport   `json:"data"`
+	Pagination *PaginationInfo `json:"pagination"`
+}
+
+// NewMarginLine computes the margin of an item priced and costed as stored. Costs that are
+// missing or don't parse leave the margin unset.
+func NewMarginLine(productID uuid.UUID, variantID *uuid.UUID, name, sku, price string, costPrice *string) MarginLine {
+	line := MarginLine{
+		ProductID: productID,
+		VariantID: variantID,
+		Name:      name,
+		SKU:       sku,
+	}
+	line.Price, _ = ParseAmount(price)
+	if costPrice == nil {
+		return line
+	}
+	cost, ok := ParseAmount(*costPrice)
+	if !ok {
+		return line
+	}
+	margin := roundAmount(line.Price - cost)
+	line.CostPrice = &cost
+	line.Margin = &margin
+	if line.Price > 0 {
+		percent := roundAmount(margin / line.Price * 100)
+		line.MarginPercent = &percent
+	}
+	return line
+}
+
+// Add counts a line into the summary. The average is kept as a running mean; round it
+// with Finish once every line is in.
+func (s *MarginSummary) Add(line MarginLine) {
+	s.Items++
+	if line.Margin == nil {
+		s.UncostedItems++
+		return
+	}
+	s.CostedItems++
+	if *line.Margin < 0 {
+		s.NegativeMarginItems++
+	}
+	if line.MarginPercent != nil {
+		s.pricedItems++
+		s.AverageMarginPercent += (*line.MarginPercent - s.AverageMarginPercent) / float64(s.pricedItems)
+	}
+}
+
+// Finish rounds the average margin for display
+func (s *MarginSummary) Finish() {
+	s.AverageMarginPercent = roundAmount(s.AverageMarginPercent)
+}
+
+// ParseAmount parses a stored price or cost such as "19.99"
+func ParseAmount(value string) (float64, bool) {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, false
+	}
+	return amount, true
+}
+
+// FormatAmount formats a cost the way prices are stored
+func FormatAmount(amount float64) string {
+	return strconv.FormatFloat(roundAmount(amount), 'f', 2, 64)
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// HideCosts clears the product's and its variants' cost prices for callers who may not see them
+func (p *Product) HideCosts() {
+	p.CostPrice = nil
+	for _, variant := range p.Variants {
+		if variant != nil {
+			variant.CostPrice = nil
+		}
+	}
+}
@@ -53,6 +53,7 @@ type ImportResult struct {
 	FailedCount  int              `json:"failedCount"`
 	SkippedCount int              `json:"skippedCount"`
 	Errors       []ImportRowError `json:"errors,omitempty"`
+	Warnings     []ImportRowError `json:"warnings,omitempty"` // Rows imported despite a problem, e.g. a probable duplicate
 	CreatedIDs   []string         `json:"createdIds,omitempty"`
 	UpdatedIDs   []string         `json:"updatedIds,omitempty"`
 }
@@ -87,6 +88,7 @@ type BatchResult struct {
 	FailedCount  int              `json:"failedCount"`
 	SkippedCount int              `json:"skippedCount"`
 	Errors       []ImportRowError `json:"errors,omitempty"`
+	Warnings     []ImportRowError `json:"warnings,omitempty"`
 	CreatedIDs   []string         `json:"createdIds,omitempty"`
 	UpdatedIDs   []string         `json:"updatedIds,omitempty"`
 	RetryCount   int              `json:"retryCount"`
@@ -104,6 +106,7 @@ type EnhancedImportResult struct {
 	SkippedCount  int              `json:"skippedCount"`
 	BatchResults  []BatchResult    `json:"batchResults,omitempty"`
 	Errors        []ImportRowError `json:"errors,omitempty"`
+	Warnings      []ImportRowError `json:"warnings,omitempty"`
 	CreatedIDs    []string         `json:"createdIds,omitempty"`
 	UpdatedIDs    []string         `json:"updatedIds,omitempty"`
 	ProcessingMs  int64            `json:"processingMs"`
@@ -131,8 +134,10 @@ func ProductImportColumns() []ImportTemplateColumn {
 		{Name: "lowStockThreshold", Description: "Low stock alert threshold", Required: false, Type: "number", Example: ""},
 		{Name: "weight", Description: "Product weight (kg)", Required: false, Type: "number", Example: ""},
 		{Name: "brand", Description: "Brand name", Required: false, Type: "string", Example: ""},
+		{Name: "gtin", Description: "GTIN/EAN/UPC barcode, used for duplicate detection", Required: false, Type: "string", Example: ""},
 		{Name: "tags", Description: "Comma-separated tags", Required: false, Type: "string", Example: ""},
 		{Name: "searchKeywords", Description: "Search keywords", Required: false, Type: "string", Example: ""},
+		{Name: "imageUrls", Description: "Pipe-separated image URLs (max 12), downloaded into product media and attached as gallery images", Required: false, Type: "string", Example: "https://cdn.example.com/a.jpg|https://cdn.example.com/b.jpg"},
 	}
 }
 
@@ -140,7 +145,7 @@ func ProductImportColumns() []ImportTemplateColumn {
 func ProductImportTemplate() ImportTemplate {
 	return ImportTemplate{
 		Entity:  "products",
-		Version: "1.1",
+		Version: "1.2",
 		Columns: ProductImportColumns(),
 	}
 }
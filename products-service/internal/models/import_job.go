@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportMode controls how imported rows are written
+type ImportMode string
+
+const (
+	ImportModeCreate ImportMode = "create" // insert new products, skip or fail on duplicate SKU
+	ImportModeUpsert ImportMode = "upsert" // idempotent: update products with matching SKU, create the rest
+)
+
+// Import job limits
+const (
+	MaxImportRows       = 100000 // Maximum data rows accepted per import job
+	MaxImportImageURLs  = 12     // Matches MediaLimits.MaxGalleryImages
+	ImportJobStaleAfter = 5 * time.Minute
+)
+
+// ImportJob tracks an asynchronous, chunked product import.
+// The uploaded file is stored with the job so a failed or interrupted
+// import can resume from NextRow without re-uploading.
+type ImportJob struct {
+	ID             uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID       string       `json:"tenantId" gorm:"not null;index:idx_import_jobs_tenant_status"`
+	Status         ImportStatus `json:"status" gorm:"not null;default:'PENDING';index:idx_import_jobs_tenant_status;index"`
+	Mode           ImportMode   `json:"mode" gorm:"not null;default:'create'"`
+	FileName       string       `json:"fileName" gorm:"not null"`
+	Format         ImportFormat `json:"format" gorm:"not null"`
+	FileData       []byte       `json:"-" gorm:"type:bytea"`
+	SkipDuplicates bool         `json:"skipDuplicates"`
+	BatchSize      int          `json:"batchSize" gorm:"not null;default:100"`
+	MaxRetries     int          `json:"maxRetries" gorm:"not null;default:2"`
+	ColumnMapping  JSON         `json:"columnMapping,omitempty" gorm:"type:jsonb"`
+
+	// Progress
+	TotalRows    int `json:"totalRows"`
+	NextRow      int `json:"nextRow"` // number of data rows already committed; processing resumes here
+	CreatedCount int `json:"createdCount"`
+	UpdatedCount int `json:"updatedCount"`
+	FailedCount  int `json:"failedCount"`
+	SkippedCount int `json:"skippedCount"`
+	ErrorCount   int `json:"errorCount"`
+
+	LastError   *string    `json:"lastError,omitempty"`
+	Attempts    int        `json:"attempts"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	HeartbeatAt *time.Time `json:"heartbeatAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+
+	CreatedBy *string   `json:"createdBy,omitempty"`
+	UserEmail *string   `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName returns the table name for the ImportJob model
+func (ImportJob) TableName() string {
+	return "product_import_jobs"
+}
+
+// PercentComplete returns the job progress as a percentage
+func (j *ImportJob) PercentComplete() int {
+	if j.TotalRows == 0 {
+		if j.Status == ImportStatusCompleted {
+			return 100
+		}
+		return 0
+	}
+	return j.NextRow * 100 / j.TotalRows
+}
+
+// ImportJobError is a persisted per-row error for an import job
+type ImportJobError struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	JobID     uuid.UUID `json:"jobId" gorm:"type:uuid;not null;index"`
+	TenantID  string    `json:"-" gorm:"not null;index"`
+	Row       int       `json:"row" gorm:"column:row_number;not null"`
+	SKU       string    `json:"sku,omitempty"`
+	Column    string    `json:"column,omitempty" gorm:"column:column_name"`
+	Code      string    `json:"code"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName returns the table name for the ImportJobError model
+func (ImportJobError) TableName() string {
+	return "product_import_job_errors"
+}
+
+// ImportJobResponse wraps an import job with derived progress fields
+type ImportJobResponse struct {
+	Success bool       `json:"success"`
+	Data    *ImportJob `json:"data"`
+	Percent int        `json:"percentComplete"`
+	Message *string    `json:"message,omitempty"`
+}
+
+// ImportJobListResponse is a paginated list of import jobs
+type ImportJobListResponse struct {
+	Success    bool            `json:"success"`
+	Data       []ImportJob     `json:"data"`
+	Pagination *PaginationInfo `json:"pagination"`
+}
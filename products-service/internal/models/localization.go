@@ -0,0 +1,398 @@
+package models
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TranslationEntityType is the kind of content a translation belongs to
+type TranslationEntityType string
+
+const (
+	TranslationEntityProduct  TranslationEntityType = "product"
+	TranslationEntityCategory TranslationEntityType = "category"
+)
+
+// Translatable fields reported as missing in the translation status report
+const (
+	TranslationFieldName        = "name"
+	TranslationFieldDescription = "description"
+	TranslationFieldAttributes  = "attributes"
+)
+
+// ContentTranslation is one locale's content for a product or category. It is stored in the
+// entity's localizations column keyed by locale, e.g. {"fr-CA": {"name": "..."}}.
+// Fields left empty fall back to the next locale in the chain, then to the original content.
+type ContentTranslation struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Attributes are keyed by the original attribute name (products only)
+	Attributes map[string]AttributeTranslation `json:"attributes,omitempty"`
+	UpdatedAt  *time.Time                      `json:"updatedAt,omitempty"`
+	UpdatedBy  string                          `json:"updatedBy,omitempty"`
+}
+
+// AttributeTranslation is the translated label and value of a product attribute
+type AttributeTranslation struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// IsEmpty reports whether the translation has no content
+func (t *ContentTranslation) IsEmpty() bool {
+	return t.Name == "" && t.Description == "" && len(t.Attributes) == 0
+}
+
+// Merge copies the non-empty fields of other over t. Attributes are merged by name.
+func (t *ContentTranslation) Merge(other *ContentTranslation) {
+	if other.Name != "" {
+		t.Name = other.Name
+	}
+	if other.Description != "" {
+		t.Description = other.Description
+	}
+	for name, attr := range other.Attributes {
+		if t.Attributes == nil {
+			t.Attributes = map[string]AttributeTranslation{}
+		}
+		t.Attributes[name] = attr
+	}
+}
+
+// TranslatableContent is the original content of a product or category with its translations
+type TranslatableContent struct {
+	ID            uuid.UUID `json:"id"`
+	Key           string    `json:"key"` // SKU for products, slug for categories
+	Name          string    `json:"name"`
+	Description   *string   `json:"description,omitempty"`
+	Attributes    *JSON     `json:"-"`
+	Localizations *JSON     `json:"-"`
+}
+
+// Translation returns the content's translation for an exact locale, or nil
+func (c *TranslatableContent) Translation(locale string) *ContentTranslation {
+	return translationFor(c.Localizations, locale)
+}
+
+// SourceAttributes returns the original attribute values by name
+func (c *TranslatableContent) SourceAttributes() map[string]string {
+	attrs := productAttributes(c.Attributes)
+	if len(attrs) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		values[attr.Name] = attr.Value
+	}
+	return values
+}
+
+// MissingFields lists the fields with original content that the locale doesn't translate
+func (c *TranslatableContent) MissingFields(locale string) []string {
+	t := c.Translation(locale)
+	if t == nil {
+		t = &ContentTranslation{}
+	}
+
+	var missing []string
+	if t.Name == "" {
+		missing = append(missing, TranslationFieldName)
+	}
+	if c.Description != nil && strings.TrimSpace(*c.Description) != "" && t.Description == "" {
+		missing = append(missing, TranslationFieldDescription)
+	}
+	for _, attr := range productAttributes(c.Attributes) {
+		if _, ok := t.Attributes[attr.Name]; !ok {
+			missing = append(missing, TranslationFieldAttributes)
+			break
+		}
+	}
+	return missing
+}
+
+// TranslationStatusItem is a product or category that lacks some of a locale's content
+type TranslationStatusItem struct {
+	EntityType    TranslationEntityType `json:"entityType"`
+	ID            uuid.UUID             `json:"id"`
+	Key           string                `json:"key"`
+	Name          string                `json:"name"`
+	MissingFields []string              `json:"missingFields"`
+}
+
+// TranslationStatusReport summarizes how much of a tenant's catalog is translated into a locale
+type TranslationStatusReport struct {
+	Locale     string                  `json:"locale"`
+	EntityType TranslationEntityType   `json:"entityType"`
+	Total      int                     `json:"total"`
+	Complete   int                     `json:"complete"` // Every field with original content is translated
+	Partial    int                     `json:"partial"`  // Some fields are translated
+	Missing    int                     `json:"missing"`  // Nothing is translated
+	Coverage   float64                 `json:"coverage"` // Percentage of complete entities
+	Items      []TranslationStatusItem `json:"items"`    // Incomplete entities, paginated
+}
+
+// TranslationStatusResponse wraps the translation status report
+type TranslationStatusResponse struct {
+	Success    bool                     `json:"success"`
+	Data       *TranslationStatusReport `json:"data"`
+	Pagination *PaginationInfo          `json:"pagination"`
+}
+
+// TranslationRow is one entity's translation for one locale in a bulk import or export.
+// Either ID or Key (SKU for products, slug for categories) identifies the entity.
+type TranslationRow struct {
+	EntityType        TranslationEntityType           `json:"entityType"`
+	ID                string                          `json:"id,omitempty"`
+	Key               string                          `json:"key,omitempty"`
+	Locale            string                          `json:"locale"`
+	SourceName        string                          `json:"sourceName,omitempty"`        // Export only
+	SourceDescription string                          `json:"sourceDescription,omitempty"` // Export only
+	SourceAttributes  map[string]string               `json:"sourceAttributes,omitempty"`  // Export only, value by attribute name
+	Name              string                          `json:"name,omitempty"`
+	Description       string                          `json:"description,omitempty"`
+	Attributes        map[string]AttributeTranslation `json:"attributes,omitempty"`
+}
+
+// ImportTranslationsRequest is a JSON bulk translation import
+type ImportTranslationsRequest struct {
+	Translations []TranslationRow `json:"translations" binding:"required,min=1"`
+}
+
+// TranslationImportError is a row of a translation import that couldn't be applied
+type TranslationImportError struct {
+	Row     int    `json:"row"` // 1-based, excluding the CSV header
+	ID      string `json:"id,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Locale  string `json:"locale,omitempty"`
+	Message string `json:"message"`
+}
+
+// TranslationImportResult summarizes a bulk translation import
+type TranslationImportResult struct {
+	TotalRows int                      `json:"totalRows"`
+	Applied   int                      `json:"applied"`
+	Failed    int                      `json:"failed"`
+	Errors    []TranslationImportError `json:"errors,omitempty"`
+}
+
+// localePattern accepts BCP 47 style tags such as "fr", "fr-CA" or "zh-Hant-TW"
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// NormalizeLocale canonicalizes a locale tag's case ("FR_ca" becomes "fr-CA") and reports
+// whether it is valid
+func NormalizeLocale(locale string) (string, bool) {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	if !localePattern.MatchString(locale) {
+		return "", false
+	}
+
+	parts := strings.Split(locale, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch {
+		case len(parts[i]) == 2:
+			parts[i] = strings.ToUpper(parts[i]) // Region
+		case len(parts[i]) == 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:]) // Script
+		default:
+			parts[i] = strings.ToLower(parts[i])
+		}
+	}
+	return strings.Join(parts, "-"), true
+}
+
+// LocaleChain expands the requested locales, most preferred first, into the order translations
+// are looked up in: each locale is followed by its parents, e.g. "fr-CA, de" becomes
+// fr-CA, fr, de. Invalid and repeated locales are dropped.
+func LocaleChain(locales []string) []string {
+	var chain []string
+	seen := map[string]bool{}
+	for _, l := range locales {
+		locale, ok := NormalizeLocale(l)
+		if !ok {
+			continue
+		}
+		parts := strings.Split(locale, "-")
+		for i := len(parts); i > 0; i-- {
+			tag := strings.Join(parts[:i], "-")
+			if !seen[tag] {
+				seen[tag] = true
+				chain = append(chain, tag)
+			}
+		}
+	}
+	return chain
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header ordered by quality.
+// The wildcard and locales with q=0 are skipped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := strings.TrimSpace(fields[0])
+		if locale == "" || locale == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		entries = append(entries, weighted{locale: locale, q: q})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].q > entries[j].q })
+	locales := make([]string, len(entries))
+	for i, e := range entries {
+		locales[i] = e.locale
+	}
+	return locales
+}
+
+// Localize replaces the product's name, description and attributes with the first translation
+// found along the locale chain, field by field, and drops the other translations from the
+// response. Locale is set to the locale the name was taken from.
+func (p *Product) Localize(chain []string) {
+	if len(chain) == 0 {
+		return
+	}
+	translations := resolveTranslations(p.Localizations, chain)
+	p.Localizations = nil
+	if len(translations) == 0 {
+		return
+	}
+
+	if name, locale := firstTranslated(translations, func(t *ContentTranslation) string { return t.Name }); name != "" {
+		p.Name = name
+		p.Locale = locale
+	}
+	if description, _ := firstTranslated(translations, func(t *ContentTranslation) string { return t.Description }); description != "" {
+		p.Description = &description
+	}
+
+	attrs := productAttributes(p.Attributes)
+	if len(attrs) == 0 {
+		return
+	}
+	for i := range attrs {
+		for _, t := range translations {
+			if translated, ok := t.Attributes[attrs[i].Name]; ok {
+				if translated.Name != "" {
+					attrs[i].Name = translated.Name
+				}
+				if translated.Value != "" {
+					attrs[i].Value = translated.Value
+				}
+				break
+			}
+		}
+	}
+	p.Attributes = &JSON{"attributes": attrs}
+}
+
+// Localize replaces the category's name and description with the first translation found
+// along the locale chain and drops the other translations from the response
+func (c *Category) Localize(chain []string) {
+	if len(chain) == 0 {
+		return
+	}
+	translations := resolveTranslations(c.Localizations, chain)
+	c.Localizations = nil
+	if len(translations) == 0 {
+		return
+	}
+
+	if name, locale := firstTranslated(translations, func(t *ContentTranslation) string { return t.Name }); name != "" {
+		c.Name = name
+		c.Locale = locale
+	}
+	if description, _ := firstTranslated(translations, func(t *ContentTranslation) string { return t.Description }); description != "" {
+		c.Description = &description
+	}
+}
+
+// localeTranslation is a translation found while walking a locale chain
+type localeTranslation struct {
+	locale string
+	*ContentTranslation
+}
+
+// resolveTranslations returns the chain's translations that exist, in chain order
+func resolveTranslations(localizations *JSON, chain []string) []localeTranslation {
+	var found []localeTranslation
+	for _, locale := range chain {
+		if t := translationFor(localizations, locale); t != nil {
+			found = append(found, localeTranslation{locale: locale, ContentTranslation: t})
+		}
+	}
+	return found
+}
+
+// firstTranslated returns the first non-empty value of a field and the locale it came from
+func firstTranslated(translations []localeTranslation, field func(*ContentTranslation) string) (string, string) {
+	for _, t := range translations {
+		if v := field(t.ContentTranslation); v != "" {
+			return v, t.locale
+		}
+	}
+	return "", ""
+}
+
+// translationFor decodes one locale's entry from a localizations column
+func translationFor(localizations *JSON, locale string) *ContentTranslation {
+	if localizations == nil {
+		return nil
+	}
+	raw, ok := (*localizations)[locale]
+	if !ok || raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var t ContentTranslation
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil
+	}
+	return &t
+}
+
+// productAttributes decodes a product's attributes column ({"attributes": [...]})
+func productAttributes(attributes *JSON) []ProductAttribute {
+	if attributes == nil {
+		return nil
+	}
+	raw, ok := (*attributes)["attributes"]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var attrs []ProductAttribute
+	if err := json.Unmarshal(data, &attrs); err != nil {
+		return nil
+	}
+	return attrs
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,6 +21,8 @@ const (
 	ProductStatusInactive ProductStatus = "INACTIVE"
 	ProductStatusArchived ProductStatus = "ARCHIVED"
 	ProductStatusRejected ProductStatus = "REJECTED"
+	// ProductStatusChangesRequested means a reviewer sent the product back to its vendor with change requests
+	ProductStatusChangesRequested ProductStatus = "CHANGES_REQUESTED"
 )
 
 // InventoryStatus represents the inventory status of a product
@@ -195,6 +199,7 @@ type Product struct {
 	Slug              *string           `json:"slug,omitempty" gorm:"index:idx_products_tenant_slug,unique"`
 	SKU               string            `json:"sku" gorm:"not null;index:idx_products_tenant_sku,unique"`
 	Brand             *string           `json:"brand,omitempty" gorm:"index"`
+	Gtin              *string           `json:"gtin,omitempty" gorm:"column:gtin;index"` // GTIN/EAN/UPC barcode
 	Description       *string           `json:"description,omitempty"`
 	Price             string            `json:"price" gorm:"not null"`
 	ComparePrice      *string           `json:"comparePrice,omitempty"`
@@ -207,6 +212,10 @@ type Product struct {
 	LowStockThreshold *int              `json:"lowStockThreshold,omitempty"`
 	Weight            *string           `json:"weight,omitempty"`
 	Dimensions        *JSON             `json:"dimensions,omitempty" gorm:"type:jsonb"`
+	// Shipping restrictions checked by shipping-service when rating carts and creating shipments
+	HazmatClass     *string `json:"hazmatClass,omitempty" gorm:"column:hazmat_class;type:varchar(10)"` // UN dangerous goods class, e.g. "3" or "2.1"
+	ContainsBattery *bool   `json:"containsBattery,omitempty" gorm:"column:contains_battery"`         // Lithium battery, packed with or in the product
+	AgeRestricted   *bool   `json:"ageRestricted,omitempty" gorm:"column:age_restricted"`             // Needs age verification on delivery
 	SearchKeywords    *string           `json:"searchKeywords,omitempty"`
 	SearchVector      *string           `json:"-" gorm:"type:tsvector;index:,type:gin"`
 	AverageRating     *float64          `json:"averageRating,omitempty"`
@@ -217,7 +226,9 @@ type Product struct {
 	SyncedAt          *time.Time        `json:"syncedAt,omitempty"`
 	Version           *int              `json:"version,omitempty" gorm:"default:1"`
 	OfflineID         *string           `json:"offlineId,omitempty"`
-	Localizations     *JSON             `json:"localizations,omitempty" gorm:"type:jsonb"`
+	Localizations     *JSON             `json:"localizations,omitempty" gorm:"type:jsonb"` // ContentTranslation per locale
+	Locale            string            `json:"locale,omitempty" gorm:"-"`                  // Locale of a localized storefront response
+	StructuredData    JSON              `json:"structuredData,omitempty" gorm:"-"`          // schema.org JSON-LD, storefront responses only
 	Attributes        *JSON             `json:"attributes,omitempty" gorm:"type:jsonb"`
 	Images            *JSONArray        `json:"images,omitempty" gorm:"type:jsonb"`
 	// Media fields for storefront display
@@ -239,6 +250,9 @@ type Product struct {
 	SeoDescription *string    `json:"seoDescription,omitempty" gorm:"column:seo_description;type:text"`
 	SeoKeywords    *JSONArray `json:"seoKeywords,omitempty" gorm:"column:seo_keywords;type:jsonb"`
 	OgImage        *string    `json:"ogImage,omitempty" gorm:"column:og_image;type:text"`
+	// Review (marketplace vendor submissions)
+	ApprovalRequestID *string    `json:"approvalRequestId,omitempty" gorm:"column:approval_request_id"` // Latest approval-service request
+	SubmittedAt       *time.Time `json:"submittedAt,omitempty" gorm:"column:submitted_at"`
 }
 
 // ProductVariant represents a product variant
@@ -285,6 +299,9 @@ type Category struct {
 	SeoTitle       *string    `json:"seoTitle,omitempty" gorm:"column:seo_title;type:text"`
 	SeoDescription *string    `json:"seoDescription,omitempty" gorm:"column:seo_description;type:text"`
 	SeoKeywords    *JSONArray `json:"seoKeywords,omitempty" gorm:"column:seo_keywords;type:jsonb"`
+	// Translated content, a ContentTranslation per locale
+	Localizations *JSON  `json:"localizations,omitempty" gorm:"column:localizations;type:jsonb"`
+	Locale        string `json:"locale,omitempty" gorm:"-"` // Locale of a localized storefront response
 	CreatedAt   time.Time       `json:"createdAt" gorm:"column:created_at"`
 	UpdatedAt   time.Time       `json:"updatedAt" gorm:"column:updated_at"`
 	DeletedAt   *gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;index"`
@@ -307,6 +324,8 @@ type CreateProductRequest struct {
 	Name              string             `json:"name" binding:"required"`
 	Slug              *string            `json:"slug,omitempty"`
 	SKU               string             `json:"sku" binding:"required"`
+	Brand             *string            `json:"brand,omitempty"`
+	Gtin              *string            `json:"gtin,omitempty"`
 	Description       *string            `json:"description,omitempty"`
 	Price             string             `json:"price" binding:"required"`
 	ComparePrice      *string            `json:"comparePrice,omitempty"`
@@ -325,6 +344,9 @@ type CreateProductRequest struct {
 	LowStockThreshold *int               `json:"lowStockThreshold,omitempty"`
 	Weight            *string            `json:"weight,omitempty"`
 	Dimensions        *JSON              `json:"dimensions,omitempty"`
+	HazmatClass       *string            `json:"hazmatClass,omitempty"` // Empty string clears it
+	ContainsBattery   *bool              `json:"containsBattery,omitempty"`
+	AgeRestricted     *bool              `json:"ageRestricted,omitempty"`
 	SearchKeywords    *string            `json:"searchKeywords,omitempty"`
 	Tags              []string           `json:"tags,omitempty"`
 	CurrencyCode      *string            `json:"currencyCode,omitempty"`
@@ -346,6 +368,7 @@ type UpdateProductRequest struct {
 	Slug              *string            `json:"slug,omitempty"`
 	SKU               *string            `json:"sku,omitempty"`
 	Brand             *string            `json:"brand,omitempty"`
+	Gtin              *string            `json:"gtin,omitempty"`
 	Description       *string            `json:"description,omitempty"`
 	Price             *string            `json:"price,omitempty"`
 	ComparePrice      *string            `json:"comparePrice,omitempty"`
@@ -364,6 +387,9 @@ type UpdateProductRequest struct {
 	LowStockThreshold *int               `json:"lowStockThreshold,omitempty"`
 	Weight            *string            `json:"weight,omitempty"`
 	Dimensions        *JSON              `json:"dimensions,omitempty"`
+	HazmatClass       *string            `json:"hazmatClass,omitempty"` // Empty string clears it
+	ContainsBattery   *bool              `json:"containsBattery,omitempty"`
+	AgeRestricted     *bool              `json:"ageRestricted,omitempty"`
 	SearchKeywords    *string            `json:"searchKeywords,omitempty"`
 	CurrencyCode      *string            `json:"currencyCode,omitempty"`
 	Tags              []string           `json:"tags,omitempty"`
@@ -377,6 +403,9 @@ type UpdateProductRequest struct {
 	SeoDescription *string  `json:"seoDescription,omitempty"`
 	SeoKeywords    []string `json:"seoKeywords,omitempty"`
 	OgImage        *string  `json:"ogImage,omitempty"`
+	// Optimistic locking - rejected with 409 if the product has changed since this version was read.
+	// The If-Match header takes precedence when both are sent.
+	ExpectedVersion *int `json:"expectedVersion,omitempty"`
 }
 
 // UpdateProductStatusRequest represents a request to update product status
@@ -450,11 +479,12 @@ type StockCheckRequest struct {
 
 // StockCheckResult represents stock availability for a single product
 type StockCheckResult struct {
-	ProductID   string `json:"productId"`
-	Available   bool   `json:"available"`
-	InStock     int    `json:"inStock"`
-	Requested   int    `json:"requested"`
-	ProductName string `json:"productName,omitempty"`
+	ProductID   string  `json:"productId"`
+	Available   bool    `json:"available"`
+	InStock     int     `json:"inStock"`
+	Requested   int     `json:"requested"`
+	ProductName string  `json:"productName,omitempty"`
+	CostPrice   *string `json:"costPrice,omitempty"` // Only for callers who may see costs, e.g. orders-service snapshotting order costs
 }
 
 // StockCheckResponse for stock check results
@@ -483,6 +513,7 @@ type UpdateImageRequest struct {
 // SearchProductsRequest represents a search request
 type SearchProductsRequest struct {
 	Query           *string            `json:"query,omitempty"`
+	SKU             *string            `json:"sku,omitempty"` // exact match on the product or one of its variants
 	CategoryID      *string            `json:"categoryId,omitempty"`
 	VendorID        *string            `json:"vendorId,omitempty"`
 	Brands          []string           `json:"brands,omitempty"`
@@ -532,6 +563,7 @@ type BulkCreateProductItem struct {
 	SupplierID   *string `json:"supplierId,omitempty"`
 	SupplierName *string `json:"supplierName,omitempty"`
 	Brand             *string            `json:"brand,omitempty"`
+	Gtin              *string            `json:"gtin,omitempty"`
 	Quantity          *int               `json:"quantity,omitempty"`
 	MinOrderQty       *int               `json:"minOrderQty,omitempty"`
 	MaxOrderQty       *int               `json:"maxOrderQty,omitempty"`
@@ -558,24 +590,6 @@ type BulkCreateProductsRequest struct {
 	SkipDuplicates bool                    `json:"skipDuplicates,omitempty"`
 }
 
-// BulkCreateResultItem represents result for a single item (generic pattern)
-type BulkCreateResultItem struct {
-	Index      int         `json:"index"`
-	ExternalID *string     `json:"externalId,omitempty"`
-	Success    bool        `json:"success"`
-	Data       interface{} `json:"data,omitempty"`
-	Error      *Error      `json:"error,omitempty"`
-}
-
-// BulkCreateProductsResponse represents bulk create response for products
-type BulkCreateProductsResponse struct {
-	Success      bool                   `json:"success"`
-	TotalCount   int                    `json:"totalCount"`
-	SuccessCount int                    `json:"successCount"`
-	FailedCount  int                    `json:"failedCount"`
-	Results      []BulkCreateResultItem `json:"results"`
-}
-
 // BulkDeleteProductsRequest represents bulk delete request for products
 type BulkDeleteProductsRequest struct {
 	IDs []uuid.UUID `json:"ids" binding:"required,min=1,max=100"`
@@ -595,6 +609,8 @@ type ExportProductsRequest struct {
 	Filters         *SearchProductsRequest `json:"filters,omitempty"`
 	IncludeVariants *bool                  `json:"includeVariants,omitempty"`
 	IncludeImages   *bool                  `json:"includeImages,omitempty"`
+	IncludeReviews  bool                   `json:"includeReviews,omitempty"`                       // Add review stats from reviews-service
+	ReviewSnippets  int                    `json:"reviewSnippets,omitempty" binding:"min=0,max=5"` // Top review snippets per product
 }
 
 // CreateCategoryRequest represents a request to create a category
@@ -639,15 +655,17 @@ type PaginationInfo struct {
 }
 
 type ProductResponse struct {
-	Success bool     `json:"success"`
-	Data    *Product `json:"data"`
-	Message *string  `json:"message,omitempty"`
+	Success    bool             `json:"success"`
+	Data       *Product         `json:"data"`
+	Message    *string          `json:"message,omitempty"`
+	Duplicates []DuplicateMatch `json:"duplicates,omitempty"` // Probable duplicates found on create (WARN policy)
 }
 
 type ProductListResponse struct {
 	Success    bool            `json:"success"`
 	Data       []Product       `json:"data"`
 	Pagination *PaginationInfo `json:"pagination"`
+	Facets     *SearchFacets   `json:"facets,omitempty"` // Searches served by the search index only
 	Metadata   *JSON           `json:"metadata,omitempty"`
 }
 
@@ -687,6 +705,7 @@ type ProductsAnalytics struct {
 	Distribution ProductsDistribution `json:"distribution"`
 	Trends       ProductsTrends       `json:"trends"`
 	TopProducts  []TopProduct         `json:"topProducts"`
+	Margins      *MarginSummary       `json:"margins,omitempty"` // Only for callers who may see costs
 }
 
 type ProductsOverview struct {
@@ -755,6 +774,24 @@ type Error struct {
 	Details *JSON  `json:"details,omitempty"`
 }
 
+// VersionConflictError is returned by UpdateProduct when the stored product version
+// no longer matches the version the caller expected
+type VersionConflictError struct {
+	CurrentVersion int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("product version conflict: current version is %d", e.CurrentVersion)
+}
+
+// hazmatClassPattern matches UN dangerous goods classes 1-9, optionally with a division (e.g. "2.1", "4.3")
+var hazmatClassPattern = regexp.MustCompile(`^[1-9](\.[1-6])?$`)
+
+// ValidHazmatClass reports whether class is a UN dangerous goods class. Empty means not hazardous.
+func ValidHazmatClass(class string) bool {
+	return class == "" || hazmatClassPattern.MatchString(class)
+}
+
 type SuccessResponse struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// DuplicatePolicyMode controls what happens when a new product looks like an existing one
+type DuplicatePolicyMode string
+
+const (
+	DuplicatePolicyOff   DuplicatePolicyMode = "OFF"
+	DuplicatePolicyWarn  DuplicatePolicyMode = "WARN"  // Create the product and report the probable duplicates
+	DuplicatePolicyBlock DuplicatePolicyMode = "BLOCK" // Reject the product
+)
+
+// DuplicateMatchType is the similarity check that matched two products
+type DuplicateMatchType string
+
+const (
+	DuplicateMatchSKU        DuplicateMatchType = "SKU"         // SKUs equal ignoring case and punctuation
+	DuplicateMatchGTIN       DuplicateMatchType = "GTIN"        // Same GTIN/EAN/UPC
+	DuplicateMatchTitleBrand DuplicateMatchType = "TITLE_BRAND" // Normalized title and brand equal
+)
+
+// DuplicateResolutionAction records how a duplicate cluster was resolved
+type DuplicateResolutionAction string
+
+const (
+	DuplicateResolutionMerge   DuplicateResolutionAction = "MERGE"   // Duplicates archived in favour of the primary product
+	DuplicateResolutionDismiss DuplicateResolutionAction = "DISMISS" // Not duplicates; hidden from suggestions
+)
+
+// ProductDuplicatePolicy is a tenant's duplicate detection settings.
+// Tenants without a row use DefaultDuplicatePolicy.
+type ProductDuplicatePolicy struct {
+	TenantID        string              `json:"tenantId" gorm:"primaryKey"`
+	Mode            DuplicatePolicyMode `json:"mode" gorm:"not null"`
+	MatchSKU        bool                `json:"matchSku" gorm:"column:match_sku;not null"`
+	MatchGTIN       bool                `json:"matchGtin" gorm:"column:match_gtin;not null"`
+	MatchTitleBrand bool                `json:"matchTitleBrand" gorm:"column:match_title_brand;not null"`
+	CrossVendor     bool                `json:"crossVendor" gorm:"not null"` // Also compare against other vendors' products
+	UpdatedBy       *string             `json:"updatedBy,omitempty"`
+	CreatedAt       time.Time           `json:"createdAt"`
+	UpdatedAt       time.Time           `json:"updatedAt"`
+}
+
+// TableName returns the table name for the ProductDuplicatePolicy model
+func (ProductDuplicatePolicy) TableName() string {
+	return "product_duplicate_policies"
+}
+
+// DefaultDuplicatePolicy warns on every check within a vendor's own catalog
+func DefaultDuplicatePolicy(tenantID string) *ProductDuplicatePolicy {
+	return &ProductDuplicatePolicy{
+		TenantID:        tenantID,
+		Mode:            DuplicatePolicyWarn,
+		MatchSKU:        true,
+		MatchGTIN:       true,
+		MatchTitleBrand: true,
+	}
+}
+
+// Enabled reports whether any duplicate check runs under the policy
+func (p *ProductDuplicatePolicy) Enabled() bool {
+	return p.Mode != DuplicatePolicyOff && (p.MatchSKU || p.MatchGTIN || p.MatchTitleBrand)
+}
+
+// ProductDuplicateResolution records a product's part in a resolved duplicate cluster
+type ProductDuplicateResolution struct {
+	ID               uuid.UUID                 `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID         string                    `json:"tenantId" gorm:"not null;index:idx_product_duplicate_resolutions_key"`
+	MatchType        DuplicateMatchType        `json:"matchType" gorm:"not null;index:idx_product_duplicate_resolutions_key"`
+	MatchKey         string                    `json:"matchKey" gorm:"not null;index:idx_product_duplicate_resolutions_key"`
+	ProductID        uuid.UUID                 `json:"productId" gorm:"type:uuid;not null;index"`
+	PrimaryProductID *uuid.UUID                `json:"primaryProductId,omitempty" gorm:"type:uuid"` // Set for MERGE
+	Action           DuplicateResolutionAction `json:"action" gorm:"not null"`
+	ResolvedBy       string                    `json:"resolvedBy"`
+	CreatedAt        time.Time                 `json:"createdAt"`
+}
+
+// TableName returns the table name for the ProductDuplicateResolution model
+func (ProductDuplicateResolution) TableName() string {
+	return "product_duplicate_resolutions"
+}
+
+// DuplicateMatch is an existing product that a new product probably duplicates
+type DuplicateMatch struct {
+	ProductID uuid.UUID          `json:"productId"`
+	Name      string             `json:"name"`
+	SKU       string             `json:"sku"`
+	VendorID  string             `json:"vendorId"`
+	Status    ProductStatus      `json:"status"`
+	MatchType DuplicateMatchType `json:"matchType"`
+}
+
+// DuplicateCluster is a group of existing products that probably duplicate each other
+type DuplicateCluster struct {
+	MatchType DuplicateMatchType `json:"matchType"`
+	MatchKey  string             `json:"matchKey"` // Pass back with the resolve action
+	Products  []Product          `json:"products"`
+}
+
+// DuplicateClusterListResponse lists merge suggestions
+type DuplicateClusterListResponse struct {
+	Success bool               `json:"success"`
+	Data    []DuplicateCluster `json:"data"`
+	Total   int                `json:"total"`
+}
+
+// UpdateDuplicatePolicyRequest replaces a tenant's duplicate detection settings
+type UpdateDuplicatePolicyRequest struct {
+	Mode            DuplicatePolicyMode `json:"mode" binding:"required,oneof=OFF WARN BLOCK"`
+	MatchSKU        bool                `json:"matchSku"`
+	MatchGTIN       bool                `json:"matchGtin"`
+	MatchTitleBrand bool                `json:"matchTitleBrand"`
+	CrossVendor     bool                `json:"crossVendor"`
+}
+
+// ResolveDuplicatesRequest resolves a duplicate cluster. MERGE keeps PrimaryProductID and
+// archives the other listed products; DISMISS marks the listed products as not duplicates.
+type ResolveDuplicatesRequest struct {
+	MatchType        DuplicateMatchType        `json:"matchType" binding:"required,oneof=SKU GTIN TITLE_BRAND"`
+	MatchKey         string                    `json:"matchKey" binding:"required"`
+	Action           DuplicateResolutionAction `json:"action" binding:"required,oneof=MERGE DISMISS"`
+	ProductIDs       []string                  `json:"productIds" binding:"required,min=2,max=100"`
+	PrimaryProductID *string                   `json:"primaryProductId,omitempty"` // Required for MERGE
+}
+
+// NormalizeSKUKey lowercases a SKU and drops everything but letters and digits,
+// so "ABC-001" and "abc 001" compare equal
+func NormalizeSKUKey(sku string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(sku) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// NormalizeGTIN strips spaces and dashes from a GTIN
+func NormalizeGTIN(gtin *string) string {
+	if gtin == nil {
+		return ""
+	}
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(*gtin))
+}
+
+// NormalizeTitleBrandKey builds the title + brand comparison key: lowercase words
+// separated by single spaces, with the brand after a "|"
+func NormalizeTitleBrandKey(name string, brand *string) string {
+	key := normalizeWords(name)
+	if key == "" {
+		return ""
+	}
+	if brand != nil {
+		return key + "|" + normalizeWords(*brand)
+	}
+	return key + "|"
+}
+
+func normalizeWords(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
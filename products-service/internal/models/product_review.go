@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangeRequestStatus tracks whether a vendor has addressed a reviewer's change request
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestOpen     ChangeRequestStatus = "OPEN"
+	ChangeRequestResolved ChangeRequestStatus = "RESOLVED" // Product was resubmitted after the request
+)
+
+// ReviewableProductFields are the product fields a reviewer can ask a vendor to change
+var ReviewableProductFields = map[string]bool{
+	"name":           true,
+	"description":    true,
+	"sku":            true,
+	"brand":          true,
+	"categoryId":     true,
+	"price":          true,
+	"comparePrice":   true,
+	"images":         true,
+	"videos":         true,
+	"attributes":     true,
+	"variants":       true,
+	"weight":         true,
+	"dimensions":     true,
+	"tags":           true,
+	"seoTitle":       true,
+	"seoDescription": true,
+	"general":        true, // Feedback that isn't about a single field
+}
+
+// ProductChangeRequest is a reviewer's request for a vendor to change one field of a
+// product submitted for approval
+type ProductChangeRequest struct {
+	ID              uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID        string              `json:"tenantId" gorm:"not null;index:idx_product_change_requests_product"`
+	ProductID       uuid.UUID           `json:"productId" gorm:"type:uuid;not null;index:idx_product_change_requests_product"`
+	VendorID        string              `json:"vendorId" gorm:"not null;index"`
+	Field           string              `json:"field" gorm:"not null"`
+	Comment         string              `json:"comment" gorm:"type:text;not null"`
+	Status          ChangeRequestStatus `json:"status" gorm:"not null;default:'OPEN'"`
+	RequestedByID   string              `json:"requestedById"`
+	RequestedByName string              `json:"requestedByName,omitempty"`
+	ResolvedAt      *time.Time          `json:"resolvedAt,omitempty"`
+	CreatedAt       time.Time           `json:"createdAt"`
+	UpdatedAt       time.Time           `json:"updatedAt"`
+}
+
+// TableName returns the table name for the ProductChangeRequest model
+func (ProductChangeRequest) TableName() string {
+	return "product_change_requests"
+}
+
+// FieldChangeRequest asks for a change to one product field
+type FieldChangeRequest struct {
+	Field   string `json:"field" binding:"required"`
+	Comment string `json:"comment" binding:"required,max=2000"`
+}
+
+// RequestProductChangesRequest sends a pending product back to its vendor
+type RequestProductChangesRequest struct {
+	Changes []FieldChangeRequest `json:"changes" binding:"required,min=1,max=50,dive"`
+	Comment string               `json:"comment,omitempty"` // Overall note shown with the field requests
+}
+
+// ReviewProductRequest approves a pending product
+type ReviewProductRequest struct {
+	Comment string `json:"comment,omitempty"`
+}
@@ -0,0 +1,205 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// SearchSynonym is a tenant's group of interchangeable search terms. A one-way group only
+// expands its first term into the others, so "laptop" can also find "notebook" without
+// "notebook" finding every laptop.
+type SearchSynonym struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID  string    `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	Terms     []string  `json:"terms" gorm:"type:jsonb;serializer:json;not null"`
+	OneWay    bool      `json:"oneWay" gorm:"not null;default:false"`
+	CreatedBy *string   `json:"createdBy,omitempty"`
+	UpdatedBy *string   `json:"updatedBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName returns the table name for the SearchSynonym model
+func (SearchSynonym) TableName() string {
+	return "product_search_synonyms"
+}
+
+// Expansions returns the terms a query term also searches for under this group
+func (s *SearchSynonym) Expansions(term string) []string {
+	for i, t := range s.Terms {
+		if t != term {
+			continue
+		}
+		if s.OneWay && i > 0 {
+			return nil
+		}
+		expansions := make([]string, 0, len(s.Terms)-1)
+		for j, other := range s.Terms {
+			if j != i {
+				expansions = append(expansions, other)
+			}
+		}
+		return expansions
+	}
+	return nil
+}
+
+// SearchSettings is a tenant's product search tuning. Tenants without a row use
+// DefaultSearchSettings.
+type SearchSettings struct {
+	TenantID           string    `json:"tenantId" gorm:"primaryKey"`
+	FuzzyEnabled       bool      `json:"fuzzyEnabled" gorm:"not null"`
+	FuzzyDistance      int       `json:"fuzzyDistance" gorm:"not null"`      // Max edits between a query term and a catalog word
+	FuzzyMinTermLength int       `json:"fuzzyMinTermLength" gorm:"not null"` // Shorter terms only match exactly
+	StopWordsEnabled   bool      `json:"stopWordsEnabled" gorm:"not null"`
+	StopWords          []string  `json:"stopWords" gorm:"type:jsonb;serializer:json"` // Added to the default English stop words
+	UpdatedBy          *string   `json:"updatedBy,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+// TableName returns the table name for the SearchSettings model
+func (SearchSettings) TableName() string {
+	return "product_search_settings"
+}
+
+// DefaultSearchSettings tolerates one typo in terms of four or more characters and drops
+// English stop words
+func DefaultSearchSettings(tenantID string) *SearchSettings {
+	return &SearchSettings{
+		TenantID:           tenantID,
+		FuzzyEnabled:       true,
+		FuzzyDistance:      1,
+		FuzzyMinTermLength: 4,
+		StopWordsEnabled:   true,
+		StopWords:          []string{},
+	}
+}
+
+// defaultStopWords are common English words that say nothing about the product sought
+var defaultStopWords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "by", "for", "from", "in", "is", "it",
+	"of", "on", "or", "that", "the", "this", "to", "with",
+}
+
+// IsStopWord reports whether a normalized term is dropped from queries under these settings
+func (s *SearchSettings) IsStopWord(term string) bool {
+	if !s.StopWordsEnabled {
+		return false
+	}
+	for _, word := range defaultStopWords {
+		if word == term {
+			return true
+		}
+	}
+	for _, word := range s.StopWords {
+		if NormalizeSearchTerm(word) == term {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeSearchTerm lowercases a term and reduces it to letters, digits, single spaces and
+// inner hyphens, so "T-Shirt!" becomes "t-shirt"
+func NormalizeSearchTerm(term string) string {
+	words := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+	normalized := words[:0]
+	for _, word := range words {
+		if word = strings.Trim(word, "-"); word != "" {
+			normalized = append(normalized, word)
+		}
+	}
+	return strings.Join(normalized, " ")
+}
+
+// NormalizeSynonymTerms normalizes a synonym group's terms, dropping blanks and repeats
+// while keeping their order
+func NormalizeSynonymTerms(terms []string) []string {
+	normalized := make([]string, 0, len(terms))
+	seen := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		term = NormalizeSearchTerm(term)
+		if term == "" || seen[term] {
+			continue
+		}
+		seen[term] = true
+		normalized = append(normalized, term)
+	}
+	return normalized
+}
+
+// SearchTermAnalysis is how one term of a query was expanded
+type SearchTermAnalysis struct {
+	Term     string   `json:"term"`
+	Synonyms []string `json:"synonyms"`
+	Fuzzy    []string `json:"fuzzy"` // Catalog words within the fuzzy distance, or the term with hyphens removed or added
+}
+
+// Alternatives returns the term followed by everything it was expanded to
+func (t *SearchTermAnalysis) Alternatives() []string {
+	alternatives := append([]string{t.Term}, t.Synonyms...)
+	return append(alternatives, t.Fuzzy...)
+}
+
+// SearchQueryAnalysis is how a search query is turned into the terms matched against
+// products. A product must match every term, through the term itself or any of its
+// expansions.
+type SearchQueryAnalysis struct {
+	Query            string               `json:"query"`
+	Tokens           []string             `json:"tokens"`
+	RemovedStopWords []string             `json:"removedStopWords"`
+	Terms            []SearchTermAnalysis `json:"terms"`
+	TSQuery          string               `json:"tsQuery,omitempty"` // The PostgreSQL query searched with
+	Settings         *SearchSettings      `json:"settings,omitempty"`
+}
+
+// SearchFacets counts the products matching a search by category, brand, price range and
+// attribute value, for filter sidebars. Only searches served by the search index have facets.
+type SearchFacets struct {
+	Categories  []FacetBucket            `json:"categories"`
+	Brands      []FacetBucket            `json:"brands"`
+	PriceRanges []PriceRangeBucket       `json:"priceRanges"`
+	Attributes  map[string][]FacetBucket `json:"attributes"` // By attribute name
+}
+
+// FacetBucket is a facet value and how many matching products have it
+type FacetBucket struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// PriceRangeBucket counts matching products priced from From (inclusive) up to To
+// (exclusive). The lowest range has no From and the highest no To.
+type PriceRangeBucket struct {
+	From  *float64 `json:"from,omitempty"`
+	To    *float64 `json:"to,omitempty"`
+	Count int64    `json:"count"`
+}
+
+// CreateSearchSynonymRequest creates or replaces a synonym group
+type CreateSearchSynonymRequest struct {
+	Terms  []string `json:"terms" binding:"required,min=2,max=20,dive,required,max=100"`
+	OneWay bool     `json:"oneWay"`
+}
+
+// UpdateSearchSettingsRequest replaces a tenant's search settings
+type UpdateSearchSettingsRequest struct {
+	FuzzyEnabled       bool     `json:"fuzzyEnabled"`
+	FuzzyDistance      int      `json:"fuzzyDistance" binding:"min=0,max=2"`
+	FuzzyMinTermLength int      `json:"fuzzyMinTermLength" binding:"min=1,max=20"`
+	StopWordsEnabled   bool     `json:"stopWordsEnabled"`
+	StopWords          []string `json:"stopWords" binding:"max=200,dive,required,max=50"`
+}
+
+// SearchSynonymListResponse represents a page of synonym groups
+type SearchSynonymListResponse struct {
+	Success    bool            `json:"success"`
+	Data       []SearchSynonym `json:"data"`
+	Pagination *PaginationInfo `json:"pagination"`
+}
@@ -0,0 +1,237 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchRuleAction is whether a search rule raises or lowers matching products
+type SearchRuleAction string
+
+const (
+	SearchRuleBoost SearchRuleAction = "BOOST"
+	SearchRuleBury  SearchRuleAction = "BURY"
+)
+
+// SearchRuleField is the product attribute a search rule matches on
+type SearchRuleField string
+
+const (
+	SearchRuleInStock       SearchRuleField = "IN_STOCK"        // In stock or low stock
+	SearchRuleOnSale        SearchRuleField = "ON_SALE"         // Priced below its compare-at price
+	SearchRuleBrand         SearchRuleField = "BRAND"           // Value: brand name
+	SearchRuleCategory      SearchRuleField = "CATEGORY"        // Value: category ID
+	SearchRuleVendor        SearchRuleField = "VENDOR"          // Value: vendor ID
+	SearchRuleTag           SearchRuleField = "TAG"             // Value: tag
+	SearchRuleMinRating     SearchRuleField = "MIN_RATING"      // Value: average rating, e.g. "4"
+	SearchRuleMinMargin     SearchRuleField = "MIN_MARGIN"      // Value: gross margin percentage of price, e.g. "40"
+	SearchRuleNewWithinDays SearchRuleField = "NEW_WITHIN_DAYS" // Value: days since the product was created
+)
+
+// searchRuleFieldValues records which fields need a value, and whether it's numeric
+var searchRuleFieldValues = map[SearchRuleField]string{
+	SearchRuleInStock:       "",
+	SearchRuleOnSale:        "",
+	SearchRuleBrand:         "text",
+	SearchRuleCategory:      "text",
+	SearchRuleVendor:        "text",
+	SearchRuleTag:           "text",
+	SearchRuleMinRating:     "number",
+	SearchRuleMinMargin:     "number",
+	SearchRuleNewWithinDays: "number",
+}
+
+// SearchRule boosts or buries storefront search results matching a product attribute, such
+// as in-stock products first or high-margin products higher. Results are ordered by the sum
+// of their matching rules' weights (boosts add, buries subtract) before relevance.
+type SearchRule struct {
+	ID        uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID  string           `json:"tenantId" gorm:"type:varchar(255);not null;index"`
+	Name      string           `json:"name" gorm:"not null"`
+	Action    SearchRuleAction `json:"action" gorm:"type:varchar(10);not null"`
+	Field     SearchRuleField  `json:"field" gorm:"type:varchar(30);not null"`
+	Value     string           `json:"value,omitempty"`
+	Weight    int              `json:"weight" gorm:"not null"`
+	Queries   []string         `json:"queries" gorm:"type:jsonb;serializer:json"` // Only searches containing one of these terms; empty for every search
+	Enabled   bool             `json:"enabled" gorm:"not null;default:true"`
+	StartsAt  *time.Time       `json:"startsAt,omitempty"`
+	EndsAt    *time.Time       `json:"endsAt,omitempty"`
+	CreatedBy *string          `json:"createdBy,omitempty"`
+	UpdatedBy *string          `json:"updatedBy,omitempty"`
+	CreatedAt time.Time        `json:"createdAt"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
+
+// TableName returns the table name for the SearchRule model
+func (SearchRule) TableName() string {
+	return "product_search_rules"
+}
+
+// ActiveAt reports whether the rule is enabled and within its schedule at a time
+func (r *SearchRule) ActiveAt(at time.Time) bool {
+	return r.Enabled && (r.StartsAt == nil || !at.Before(*r.StartsAt)) && (r.EndsAt == nil || at.Before(*r.EndsAt))
+}
+
+// AppliesTo reports whether the rule applies to a normalized search query
+func (r *SearchRule) AppliesTo(query string) bool {
+	if len(r.Queries) == 0 {
+		return true
+	}
+	padded := " " + query + " "
+	for _, q := range r.Queries {
+		if strings.Contains(padded, " "+q+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// SignedWeight is the rule's weight, negative for buries
+func (r *SearchRule) SignedWeight() int {
+	if r.Action == SearchRuleBury {
+		return -r.Weight
+	}
+	return r.Weight
+}
+
+// SearchPin places a product at the top of the storefront results for a search query.
+// Pinned products are shown in position order ahead of every other result, whether or not
+// they match the query's text.
+type SearchPin struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID  string     `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:idx_product_search_pins_query_product"`
+	Query     string     `json:"query" gorm:"not null;uniqueIndex:idx_product_search_pins_query_product"` // Normalized search query
+	ProductID uuid.UUID  `json:"productId" gorm:"type:uuid;not null;uniqueIndex:idx_product_search_pins_query_product"`
+	Position  int        `json:"position" gorm:"not null"` // 1 for the first result
+	StartsAt  *time.Time `json:"startsAt,omitempty"`
+	EndsAt    *time.Time `json:"endsAt,omitempty"`
+	CreatedBy *string    `json:"createdBy,omitempty"`
+	UpdatedBy *string    `json:"updatedBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// TableName returns the table name for the SearchPin model
+func (SearchPin) TableName() string {
+	return "product_search_pins"
+}
+
+// SearchMerchandising is the rules and pins applied to one search
+type SearchMerchandising struct {
+	At    time.Time    `json:"at"` // When the rules and pins were active; rules on product age are relative to it
+	Rules []SearchRule `json:"rules"`
+	Pins  []SearchPin  `json:"pins"`
+}
+
+// Empty reports whether there is nothing to apply
+func (m *SearchMerchandising) Empty() bool {
+	return m == nil || (len(m.Rules) == 0 && len(m.Pins) == 0)
+}
+
+// SearchRuleRequest creates or replaces a search rule
+type SearchRuleRequest struct {
+	Name     string           `json:"name" binding:"required,max=255"`
+	Action   SearchRuleAction `json:"action" binding:"required,oneof=BOOST BURY"`
+	Field    SearchRuleField  `json:"field" binding:"required"`
+	Value    string           `json:"value" binding:"max=255"`
+	Weight   int              `json:"weight" binding:"required,min=1,max=100"`
+	Queries  []string         `json:"queries" binding:"max=50,dive,required,max=100"`
+	Enabled  *bool            `json:"enabled"` // Defaults to true
+	StartsAt *time.Time       `json:"startsAt"`
+	EndsAt   *time.Time       `json:"endsAt"`
+}
+
+// Validate checks the rule's field, value and schedule, returning a message for the first
+// problem found
+func (r *SearchRuleRequest) Validate() string {
+	kind, ok := searchRuleFieldValues[r.Field]
+	if !ok {
+		return "Unknown rule field " + string(r.Field)
+	}
+	value := strings.TrimSpace(r.Value)
+	switch kind {
+	case "text":
+		if value == "" {
+			return string(r.Field) + " rules need a value"
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(value, 64); err != nil || n < 0 {
+			return string(r.Field) + " rules need a non-negative number value"
+		}
+	}
+	if r.StartsAt != nil && r.EndsAt != nil && !r.EndsAt.After(*r.StartsAt) {
+		return "endsAt must be after startsAt"
+	}
+	return ""
+}
+
+// Rule builds the rule the request describes, with its queries normalized
+func (r *SearchRuleRequest) Rule(tenantID string) SearchRule {
+	rule := SearchRule{
+		TenantID: tenantID,
+		Name:     strings.TrimSpace(r.Name),
+		Action:   r.Action,
+		Field:    r.Field,
+		Weight:   r.Weight,
+		Queries:  NormalizeSynonymTerms(r.Queries),
+		Enabled:  r.Enabled == nil || *r.Enabled,
+		StartsAt: r.StartsAt,
+		EndsAt:   r.EndsAt,
+	}
+	if searchRuleFieldValues[r.Field] != "" {
+		rule.Value = strings.TrimSpace(r.Value)
+	}
+	return rule
+}
+
+// SearchPinRequest pins a product for a search query, or moves an existing pin
+type SearchPinRequest struct {
+	Query     string     `json:"query" binding:"required,max=255"`
+	ProductID uuid.UUID  `json:"productId" binding:"required"`
+	Position  int        `json:"position" binding:"required,min=1,max=100"`
+	StartsAt  *time.Time `json:"startsAt"`
+	EndsAt    *time.Time `json:"endsAt"`
+}
+
+// SearchPreviewRequest previews storefront search results under the merchandising active at
+// a time, optionally with unsaved rules added
+type SearchPreviewRequest struct {
+	Query      string                 `json:"query" binding:"required,max=255"`
+	At         *time.Time             `json:"at"` // Defaults to now, to preview scheduled rules and pins
+	DraftRules []SearchRuleRequest    `json:"draftRules" binding:"max=20,dive"`
+	Filters    *SearchProductsRequest `json:"filters"`
+	Limit      int                    `json:"limit"`
+}
+
+// SearchPreviewResult is one previewed result and why it's placed where it is
+type SearchPreviewResult struct {
+	Position        int               `json:"position"`
+	ProductID       uuid.UUID         `json:"productId"`
+	Name            string            `json:"name"`
+	SKU             string            `json:"sku"`
+	InventoryStatus *InventoryStatus  `json:"inventoryStatus,omitempty"`
+	PinnedPosition  *int              `json:"pinnedPosition,omitempty"`
+	Score           int               `json:"score"` // Sum of matching rules' signed weights
+	MatchedRules    []SearchRuleMatch `json:"matchedRules"`
+}
+
+// SearchRuleMatch is a rule that matched a previewed result. ID is unset for draft rules.
+type SearchRuleMatch struct {
+	ID     *uuid.UUID       `json:"id,omitempty"`
+	Name   string           `json:"name"`
+	Action SearchRuleAction `json:"action"`
+	Weight int              `json:"weight"`
+}
+
+// SearchPreview is the previewed first page of a storefront search
+type SearchPreview struct {
+	Query   string                `json:"query"`
+	At      time.Time             `json:"at"`
+	Total   int64                 `json:"total"`
+	Rules   []SearchRule          `json:"rules"` // Active rules that apply to the query, drafts included
+	Pins    []SearchPin           `json:"pins"`
+	Results []SearchPreviewResult `json:"results"`
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SitemapEntryType is the kind of storefront page a sitemap entry links to
+type SitemapEntryType string
+
+const (
+	SitemapEntryProduct  SitemapEntryType = "product"
+	SitemapEntryCategory SitemapEntryType = "category"
+	SitemapEntryPage     SitemapEntryType = "page" // content-service page
+)
+
+// SitemapEntryTypes lists the entry types in the order their sitemaps appear in the index
+var SitemapEntryTypes = []SitemapEntryType{SitemapEntryProduct, SitemapEntryCategory, SitemapEntryPage}
+
+// SitemapMaxURLs is the most URLs in one sitemap file; larger sets are split across files
+const SitemapMaxURLs = 10000
+
+// SitemapEntry is one storefront URL in a tenant's sitemap. Product and category entries are
+// kept current from product events and category changes; page entries are refreshed from
+// content-service on each rebuild.
+type SitemapEntry struct {
+	ID           uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID     string           `json:"tenantId" gorm:"type:varchar(255);not null;uniqueIndex:idx_sitemap_entries_entity"`
+	EntityType   SitemapEntryType `json:"entityType" gorm:"type:varchar(20);not null;uniqueIndex:idx_sitemap_entries_entity"`
+	EntityID     string           `json:"entityId" gorm:"type:varchar(255);not null;uniqueIndex:idx_sitemap_entries_entity"`
+	Path         string           `json:"path" gorm:"type:text;not null"` // Storefront path, e.g. /products/{id}
+	LastModified time.Time        `json:"lastModified" gorm:"not null"`
+	CreatedAt    time.Time        `json:"createdAt"`
+	UpdatedAt    time.Time        `json:"updatedAt"`
+}
+
+// TableName returns the table name for the SitemapEntry model
+func (SitemapEntry) TableName() string {
+	return "storefront_sitemap_entries"
+}
+
+// StorefrontSitemap records when a tenant's sitemap was last fully rebuilt. Tenants without a
+// row are rebuilt the first time their sitemap is requested.
+type StorefrontSitemap struct {
+	TenantID      string     `json:"tenantId" gorm:"primaryKey;type:varchar(255)"`
+	LastRebuiltAt time.Time  `json:"lastRebuiltAt" gorm:"not null"`
+	PagesSyncedAt *time.Time `json:"pagesSyncedAt,omitempty"` // Last successful content-service sync
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// TableName returns the table name for the StorefrontSitemap model
+func (StorefrontSitemap) TableName() string {
+	return "storefront_sitemaps"
+}
+
+// SitemapEntryTypeInfo says where an entry type's URLs live and how search engines should treat them
+type SitemapEntryTypeInfo struct {
+	PathPrefix string
+	ChangeFreq string
+	Priority   string
+}
+
+// SitemapTypeInfo holds the storefront path and crawl hints for each entry type
+var SitemapTypeInfo = map[SitemapEntryType]SitemapEntryTypeInfo{
+	SitemapEntryProduct:  {PathPrefix: "/products/", ChangeFreq: "daily", Priority: "0.8"},
+	SitemapEntryCategory: {PathPrefix: "/categories/", ChangeFreq: "weekly", Priority: "0.6"},
+	SitemapEntryPage:     {PathPrefix: "/pages/", ChangeFreq: "monthly", Priority: "0.5"},
+}
+
+// SitemapTypeSummary is the number of URLs of one entry type in a tenant's sitemap
+type SitemapTypeSummary struct {
+	EntityType   SitemapEntryType `json:"entityType"`
+	Count        int64            `json:"count"`
+	LastModified *time.Time       `json:"lastModified,omitempty"`
+}
+
+// SitemapStatus describes a tenant's sitemap
+type SitemapStatus struct {
+	StorefrontURL string               `json:"storefrontUrl"`
+	LastRebuiltAt *time.Time           `json:"lastRebuiltAt,omitempty"`
+	PagesSyncedAt *time.Time           `json:"pagesSyncedAt,omitempty"`
+	Types         []SitemapTypeSummary `json:"types"`
+}
+
+// SitemapURLSet is a sitemap file (https://www.sitemaps.org/protocol.html)
+type SitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// SitemapURL is one URL in a sitemap file
+type SitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+// SitemapIndex lists a storefront's sitemap files
+type SitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []SitemapRef `xml:"sitemap"`
+}
+
+// SitemapRef is one sitemap file in a sitemap index
+type SitemapRef struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// SitemapXMLNamespace is the sitemap protocol's XML namespace
+const SitemapXMLNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// schemaAvailability maps inventory statuses to schema.org ItemAvailability values
+var schemaAvailability = map[InventoryStatus]string{
+	InventoryStatusInStock:      "https://schema.org/InStock",
+	InventoryStatusLowStock:     "https://schema.org/LimitedAvailability",
+	InventoryStatusOutOfStock:   "https://schema.org/OutOfStock",
+	InventoryStatusBackOrder:    "https://schema.org/BackOrder",
+	InventoryStatusDiscontinued: "https://schema.org/Discontinued",
+}
+
+// BuildStructuredData returns the product's schema.org Product JSON-LD, with an Offer (or an
+// AggregateOffer across its variants) and an AggregateRating once it has reviews. productURL
+// is the product's storefront page.
+func (p *Product) BuildStructuredData(productURL string) JSON {
+	data := JSON{
+		"@context": "https://schema.org",
+		"@type":    "Product",
+		"name":     p.Name,
+		"sku":      p.SKU,
+		"url":      productURL,
+	}
+	if p.Description != nil && *p.Description != "" {
+		data["description"] = *p.Description
+	}
+	if p.Gtin != nil && *p.Gtin != "" {
+		data["gtin"] = *p.Gtin
+	}
+	if p.Brand != nil && *p.Brand != "" {
+		data["brand"] = JSON{"@type": "Brand", "name": *p.Brand}
+	}
+	if images := p.imageURLs(); len(images) > 0 {
+		data["image"] = images
+	}
+
+	data["offers"] = p.buildOffer(productURL)
+
+	if p.ReviewCount != nil && *p.ReviewCount > 0 && p.AverageRating != nil {
+		data["aggregateRating"] = JSON{
+			"@type":       "AggregateRating",
+			"ratingValue": strconv.FormatFloat(*p.AverageRating, 'f', 1, 64),
+			"reviewCount": *p.ReviewCount,
+			"bestRating":  "5",
+			"worstRating": "1",
+		}
+	}
+
+	return data
+}
+
+func (p *Product) buildOffer(productURL string) JSON {
+	offer := JSON{"url": productURL}
+	if p.CurrencyCode != nil && *p.CurrencyCode != "" {
+		offer["priceCurrency"] = *p.CurrencyCode
+	}
+	if p.InventoryStatus != nil {
+		if availability, ok := schemaAvailability[*p.InventoryStatus]; ok {
+			offer["availability"] = availability
+		}
+	}
+
+	low, high, priced := p.variantPriceRange()
+	if priced < 2 {
+		offer["@type"] = "Offer"
+		offer["price"] = p.Price
+		return offer
+	}
+
+	offer["@type"] = "AggregateOffer"
+	offer["lowPrice"] = strconv.FormatFloat(low, 'f', 2, 64)
+	offer["highPrice"] = strconv.FormatFloat(high, 'f', 2, 64)
+	offer["offerCount"] = priced
+	return offer
+}
+
+// variantPriceRange returns the lowest and highest variant prices and how many variants have a
+// parseable price
+func (p *Product) variantPriceRange() (low, high float64, priced int) {
+	for _, variant := range p.Variants {
+		if variant == nil {
+			continue
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(variant.Price), 64)
+		if err != nil {
+			continue
+		}
+		if priced == 0 || price < low {
+			low = price
+		}
+		if priced == 0 || price > high {
+			high = price
+		}
+		priced++
+	}
+	return low, high, priced
+}
+
+// imageURLs returns the URLs of the product's gallery images, in gallery order
+func (p *Product) imageURLs() []string {
+	if p.Images == nil {
+		return nil
+	}
+	urls := make([]string, 0, len(*p.Images))
+	for _, item := range *p.Images {
+		image, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if url, ok := image["url"].(string); ok && url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
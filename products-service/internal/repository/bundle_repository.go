@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products-service/internal/models"
+)
+
+// Product Bundle Operations
+
+// BundleError rejects a bundle save that conflicts with the product's existing bundle or
+// names components that can't be used
+type BundleError struct {
+	Code    string
+	Message string
+}
+
+func (e *BundleError) Error() string {
+	return e.Message
+}
+
+// GetBundle retrieves a product's bundle with its components' products, and prices it
+func (r *ProductsRepository) GetBundle(tenantID string, productID uuid.UUID) (*models.ProductBundle, *models.BundlePrice, error) {
+	bundle, err := getBundleTx(r.db, tenantID, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var product models.Product
+	if err := r.db.Select("price").Where("tenant_id = ? AND id = ?", tenantID, productID).First(&product).Error; err != nil {
+		return nil, nil, err
+	}
+	price := bundle.CalculatePrice(product.Price)
+	return bundle, &price, nil
+}
+
+func getBundleTx(tx *gorm.DB, tenantID string, productID uuid.UUID) (*models.ProductBundle, error) {
+	var bundle models.ProductBundle
+	err := tx.Preload("Components", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).
+		Preload("Components.Product").
+		Where("tenant_id = ? AND product_id = ?", tenantID, productID).
+		First(&bundle).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// SaveBundle makes a product a bundle, or replaces its bundle when replace is set. Components
+// must be products of the same tenant that aren't bundles themselves, and a product that is a
+// component of another bundle can't become one. With COMPONENTS pricing the bundle product's
+// price is set to the calculated price, so carts and orders charge it like any other product.
+// Returns gorm.ErrRecordNotFound if the product doesn't exist, or if replace is set and it
+// isn't a bundle yet.
+func (r *ProductsRepository) SaveBundle(bundle *models.ProductBundle, replace bool) (*models.ProductBundle, *models.BundlePrice, error) {
+	var saved *models.ProductBundle
+	var price models.BundlePrice
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "price").
+			Where("tenant_id = ? AND id = ?", bundle.TenantID, bundle.ProductID).
+			First(&product).Error; err != nil {
+			return err
+		}
+
+		var existing models.ProductBundle
+		err := tx.Where("tenant_id = ? AND product_id = ?", bundle.TenantID, bundle.ProductID).First(&existing).Error
+		found := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if replace && !found {
+			return gorm.ErrRecordNotFound
+		}
+		if !replace && found {
+			return &BundleError{Code: "BUNDLE_EXISTS", Message: "Product is already a bundle; use PUT to replace it"}
+		}
+
+		if err := validateBundleComponentsTx(tx, bundle); err != nil {
+			return err
+		}
+
+		components := bundle.Components
+		bundle.Components = nil
+		if found {
+			bundle.ID = existing.ID
+			bundle.CreatedAt = existing.CreatedAt
+			if err := tx.Where("bundle_id = ?", existing.ID).Delete(&models.BundleComponent{}).Error; err != nil {
+				return err
+			}
+		} else {
+			bundle.ID = uuid.New()
+		}
+		if err := tx.Omit(clause.Associations).Save(bundle).Error; err != nil {
+			return err
+		}
+		for i := range components {
+			components[i].ID = uuid.New()
+			components[i].BundleID = bundle.ID
+		}
+		if err := tx.Create(&components).Error; err != nil {
+			return err
+		}
+
+		saved, err = getBundleTx(tx, bundle.TenantID, bundle.ProductID)
+		if err != nil {
+			return err
+		}
+		price = saved.CalculatePrice(product.Price)
+		if saved.Pricing != models.BundlePricingComponents {
+			return nil
+		}
+		return tx.Model(&models.Product{}).
+			Where("tenant_id = ? AND id = ?", bundle.TenantID, bundle.ProductID).
+			Updates(map[string]interface{}{
+				"price":      models.FormatAmount(price.Price),
+				"updated_at": time.Now(),
+				"version":    gorm.Expr("COALESCE(version, 1) + 1"),
+			}).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.invalidateProductCaches(context.Background(), bundle.TenantID, bundle.ProductID)
+	return saved, &price, nil
+}
+
+// validateBundleComponentsTx checks that a bundle's components exist and that saving it
+// doesn't nest one bundle inside another
+func validateBundleComponentsTx(tx *gorm.DB, bundle *models.ProductBundle) error {
+	var asComponent int64
+	if err := tx.Model(&models.BundleComponent{}).
+		Joins("JOIN product_bundles ON product_bundles.id = bundle_components.bundle_id").
+		Where("product_bundles.tenant_id = ? AND bundle_components.component_product_id = ?", bundle.TenantID, bundle.ProductID).
+		Count(&asComponent).Error; err != nil {
+		return err
+	}
+	if asComponent > 0 {
+		return &BundleError{Code: "INVALID_BUNDLE", Message: "Product is a component of another bundle and can't be a bundle itself"}
+	}
+
+	ids := make([]uuid.UUID, len(bundle.Components))
+	for i, component := range bundle.Components {
+		ids[i] = component.ComponentProductID
+	}
+
+	var found []uuid.UUID
+	if err := tx.Model(&models.Product{}).
+		Where("tenant_id = ? AND id IN ?", bundle.TenantID, ids).
+		Pluck("id", &found).Error; err != nil {
+		return err
+	}
+	exists := make(map[uuid.UUID]bool, len(found))
+	for _, id := range found {
+		exists[id] = true
+	}
+	for _, id := range ids {
+		if !exists[id] {
+			return &BundleError{Code: "INVALID_COMPONENT", Message: fmt.Sprintf("Component product %s not found", id)}
+		}
+	}
+
+	var nested []uuid.UUID
+	if err := tx.Model(&models.ProductBundle{}).
+		Where("tenant_id = ? AND product_id IN ?", bundle.TenantID, ids).
+		Pluck("product_id", &nested).Error; err != nil {
+		return err
+	}
+	if len(nested) > 0 {
+		return &BundleError{Code: "INVALID_COMPONENT", Message: fmt.Sprintf("Component product %s is a bundle; bundles can't contain bundles", nested[0])}
+	}
+	return nil
+}
+
+// DeleteBundle turns a bundle back into an ordinary product, leaving its price as it is
+func (r *ProductsRepository) DeleteBundle(tenantID string, productID uuid.UUID) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var bundle models.ProductBundle
+		if err := tx.Where("tenant_id = ? AND product_id = ?", tenantID, productID).First(&bundle).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("bundle_id = ?", bundle.ID).Delete(&models.BundleComponent{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&bundle).Error
+	})
+	if err == nil {
+		r.invalidateProductCaches(context.Background(), tenantID, productID)
+	}
+	return err
+}
+
+// getBundlesTx loads the bundles among products with their components' products, keyed by
+// bundle product ID
+func getBundlesTx(tx *gorm.DB, tenantID string, productIDs []uuid.UUID) (map[uuid.UUID]*models.ProductBundle, error) {
+	var bundles []models.ProductBundle
+	if err := tx.Preload("Components", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).
+		Preload("Components.Product").
+		Where("tenant_id = ? AND product_id IN ?", tenantID, productIDs).
+		Find(&bundles).Error; err != nil {
+		return nil, err
+	}
+	byProduct := make(map[uuid.UUID]*models.ProductBundle, len(bundles))
+	for i := range bundles {
+		byProduct[bundles[i].ProductID] = &bundles[i]
+	}
+	return byProduct, nil
+}
+
+// expandBundleItemsTx replaces bundles in an inventory request with their components, so
+// deducting two camera kits deducts two cameras and two lenses. Quantities of a product
+// named more than once, directly or through bundles, are added together.
+func expandBundleItemsTx(tx *gorm.DB, tenantID string, items []models.BulkInventoryItem) ([]models.BulkInventoryItem, error) {
+	productIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid product ID %s: %w", item.ProductID, err)
+		}
+		productIDs = append(productIDs, productID)
+	}
+
+	bundles, err := getBundlesTx(tx, tenantID, productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bundles: %w", err)
+	}
+
+	expanded := make([]models.BulkInventoryItem, 0, len(items))
+	positions := make(map[string]int, len(items))
+	add := func(productID string, quantity int) {
+		if i, seen := positions[productID]; seen {
+			expanded[i].Quantity += quantity
+			return
+		}
+		positions[productID] = len(expanded)
+		expanded = append(expanded, models.BulkInventoryItem{ProductID: productID, Quantity: quantity})
+	}
+	for i, item := range items {
+		bundle, isBundle := bundles[productIDs[i]]
+		if !isBundle {
+			add(productIDs[i].String(), item.Quantity)
+			continue
+		}
+		for _, component := range bundle.Components {
+			add(component.ComponentProductID.String(), component.Quantity*item.Quantity)
+		}
+	}
+	return expanded, nil
+}
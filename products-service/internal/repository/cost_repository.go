@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products-service/internal/models"
+)
+
+// Cost Price Operations
+
+// recordCostChangeTx records a cost price change, unless the price didn't actually change
+func recordCostChangeTx(tx *gorm.DB, entry *models.ProductCostHistory) error {
+	if sameCostPrice(entry.PreviousCostPrice, entry.CostPrice) {
+		return nil
+	}
+	entry.ID = uuid.New()
+	entry.CreatedAt = time.Now()
+	return tx.Create(entry).Error
+}
+
+// sameCostPrice compares stored cost prices by amount, so "5" and "5.00" are the same price
+func sameCostPrice(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	amountA, okA := models.ParseAmount(*a)
+	amountB, okB := models.ParseAmount(*b)
+	if okA && okB {
+		return amountA == amountB
+	}
+	return *a == *b
+}
+
+// ListCostHistory retrieves a product's cost price changes, newest first, including its variants'
+func (r *ProductsRepository) ListCostHistory(tenantID string, productID uuid.UUID, page, limit int) ([]models.ProductCostHistory, int64, error) {
+	var history []models.ProductCostHistory
+	var total int64
+
+	query := r.db.Model(&models.ProductCostHistory{}).
+		Where("tenant_id = ? AND product_id = ?", tenantID, productID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&history).Error; err != nil {
+		return nil, 0, err
+	}
+	return history, total, nil
+}
+
+// ApplyLandedCost sets a product's or variant's cost price to the landed unit cost of a
+// purchase order receipt and records the change. Returns false if the product or variant
+// doesn't exist or already has that cost, so redelivered receipts change nothing.
+func (r *ProductsRepository) ApplyLandedCost(tenantID string, productID uuid.UUID, variantID *uuid.UUID, landedUnitCost float64, purchaseOrderID string) (bool, error) {
+	cost := models.FormatAmount(landedUnitCost)
+	changed := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "cost_price").
+			Where("tenant_id = ? AND id = ?", tenantID, productID).
+			First(&product).Error
+		if err != nil {
+			return err
+		}
+
+		previous := product.CostPrice
+		update := tx.Model(&models.Product{}).Where("id = ?", productID)
+		if variantID != nil {
+			var variant models.ProductVariant
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id", "cost_price").
+				Where("id = ? AND product_id = ?", *variantID, productID).
+				First(&variant).Error
+			if err != nil {
+				return err
+			}
+			previous = variant.CostPrice
+			update = tx.Model(&models.ProductVariant{}).Where("id = ?", *variantID)
+		}
+		if sameCostPrice(previous, &cost) {
+			return nil
+		}
+
+		if err := update.Updates(map[string]interface{}{
+			"cost_price": cost,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		changed = true
+		return recordCostChangeTx(tx, &models.ProductCostHistory{
+			TenantID:          tenantID,
+			ProductID:         productID,
+			VariantID:         variantID,
+			PreviousCostPrice: previous,
+			CostPrice:         &cost,
+			Source:            models.CostSourceLandedCost,
+			Reference:         &purchaseOrderID,
+		})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if changed {
+		r.invalidateProductCaches(context.Background(), tenantID, productID)
+	}
+	return changed, nil
+}
+
+// productMarginLines returns the margin lines of a product: one per variant, with variants
+// that have no cost of their own falling back to the product's, or one for the product
+// itself if it has no variants
+func productMarginLines(product *models.Product) []models.MarginLine {
+	if len(product.Variants) == 0 {
+		return []models.MarginLine{
+			models.NewMarginLine(product.ID, nil, product.Name, product.SKU, product.Price, product.CostPrice),
+		}
+	}
+
+	lines := make([]models.MarginLine, 0, len(product.Variants))
+	for _, variant := range product.Variants {
+		costPrice := variant.CostPrice
+		if costPrice == nil {
+			costPrice = product.CostPrice
+		}
+		variantID := variant.ID
+		lines = append(lines, models.NewMarginLine(product.ID, &variantID, variant.Name, variant.SKU, variant.Price, costPrice))
+	}
+	return lines
+}
+
+// GetMarginSummary totals margins across the tenant's products and variants
+func (r *ProductsRepository) GetMarginSummary(tenantID string) (*models.MarginSummary, error) {
+	var products []*models.Product
+	err := r.db.Select("id", "name", "sku", "price", "cost_price").
+		Where("tenant_id = ?", tenantID).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "product_id", "name", "sku", "price", "cost_price")
+		}).
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.MarginSummary{}
+	for _, product := range products {
+		for _, line := range productMarginLines(product) {
+			summary.Add(line)
+		}
+	}
+	summary.Finish()
+	return summary, nil
+}
+
+// GetMarginReport retrieves a page of products' margin lines, ordered by product name, with
+// the tenant-wide summary. The total counts products, not lines.
+func (r *ProductsRepository) GetMarginReport(tenantID string, page, limit int) (*models.MarginReport, int64, error) {
+	summary, err := r.GetMarginSummary(tenantID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	query := r.db.Model(&models.Product{}).Where("tenant_id = ?", tenantID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []*models.Product
+	offset := (page - 1) * limit
+	if err := query.Preload("Variants").Order("name ASC").Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+		return nil, 0, err
+	}
+
+	report := &models.MarginReport{
+		Summary: *summary,
+		Lines:   []models.MarginLine{},
+	}
+	for _, product := range products {
+		report.Lines = append(report.Lines, productMarginLines(product)...)
+	}
+	return report, total, nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products-service/internal/models"
+)
+
+// ImportJobRepository persists asynchronous product import jobs and their row errors
+type ImportJobRepository struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) *ImportJobRepository {
+	return &ImportJobRepository{db: db}
+}
+
+// Create stores a new import job in PENDING state
+func (r *ImportJobRepository) Create(job *models.ImportJob) error {
+	job.Status = models.ImportStatusPending
+	return r.db.Create(job).Error
+}
+
+// GetByID retrieves an import job with tenant isolation (file data is not loaded)
+func (r *ImportJobRepository) GetByID(tenantID string, jobID uuid.UUID) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.db.Omit("file_data").
+		Where("id = ? AND tenant_id = ?", jobID, tenantID).
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// List returns import jobs for a tenant, newest first
+func (r *ImportJobRepository) List(tenantID string, page, limit int) ([]models.ImportJob, int64, error) {
+	var jobs []models.ImportJob
+	var total int64
+
+	query := r.db.Model(&models.ImportJob{}).Where("tenant_id = ?", tenantID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Omit("file_data").
+		Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&jobs).Error
+
+	return jobs, total, err
+}
+
+// ClaimNext atomically claims the oldest runnable job. A job is runnable when it is
+// PENDING, or PROCESSING with a heartbeat older than staleAfter (the pod running it died).
+// Uses SKIP LOCKED so multiple replicas never pick up the same job.
+func (r *ImportJobRepository) ClaimNext(staleAfter time.Duration) (*models.ImportJob, error) {
+	var job models.ImportJob
+	now := time.Now()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND heartbeat_at < ?)",
+				models.ImportStatusPending, models.ImportStatusProcessing, now.Add(-staleAfter)).
+			Order("created_at ASC").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":       models.ImportStatusProcessing,
+			"heartbeat_at": now,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"updated_at":   now,
+		}
+		if job.StartedAt == nil {
+			updates["started_at"] = now
+		}
+		return tx.Model(&models.ImportJob{}).Where("id = ?", job.ID).Updates(updates).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = models.ImportStatusProcessing
+	job.Attempts++
+	return &job, nil
+}
+
+// SetTotalRows records the number of data rows found in the file
+func (r *ImportJobRepository) SetTotalRows(jobID uuid.UUID, total int) error {
+	return r.db.Model(&models.ImportJob{}).Where("id = ?", jobID).
+		Updates(map[string]interface{}{"total_rows": total, "updated_at": time.Now()}).Error
+}
+
+// SaveChunk commits the progress of one processed chunk and its row errors in a single transaction,
+// so NextRow never advances past rows whose errors were not recorded
+func (r *ImportJobRepository) SaveChunk(job *models.ImportJob, rowErrors []models.ImportJobError) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(rowErrors) > 0 {
+			for i := range rowErrors {
+				rowErrors[i].JobID = job.ID
+				rowErrors[i].TenantID = job.TenantID
+				rowErrors[i].CreatedAt = now
+			}
+			if err := tx.CreateInBatches(rowErrors, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.ImportJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+			"next_row":      job.NextRow,
+			"created_count": job.CreatedCount,
+			"updated_count": job.UpdatedCount,
+			"failed_count":  job.FailedCount,
+			"skipped_count": job.SkippedCount,
+			"error_count":   job.ErrorCount,
+			"heartbeat_at":  now,
+			"updated_at":    now,
+		}).Error
+	})
+}
+
+// MarkCompleted marks the job finished and releases the stored file
+func (r *ImportJobRepository) MarkCompleted(jobID uuid.UUID) error {
+	now := time.Now()
+	return r.db.Model(&models.ImportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"status":       models.ImportStatusCompleted,
+		"completed_at": now,
+		"file_data":    nil,
+		"updated_at":   now,
+	}).Error
+}
+
+// MarkFailed marks the job failed; the file is kept so the job can be resumed
+func (r *ImportJobRepository) MarkFailed(jobID uuid.UUID, message string) error {
+	now := time.Now()
+	return r.db.Model(&models.ImportJob{}).Where("id = ?", jobID).Updates(map[string]interface{}{
+		"status":     models.ImportStatusFailed,
+		"last_error": message,
+		"updated_at": now,
+	}).Error
+}
+
+// Requeue returns a job interrupted by shutdown to PENDING so another replica can pick it up immediately
+func (r *ImportJobRepository) Requeue(jobID uuid.UUID) error {
+	return r.db.Model(&models.ImportJob{}).
+		Where("id = ? AND status = ?", jobID, models.ImportStatusProcessing).
+		Updates(map[string]interface{}{
+			"status":     models.ImportStatusPending,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// Resume puts a FAILED job back in the queue. Processing continues from NextRow.
+// Returns gorm.ErrRecordNotFound if the job does not exist, is not failed, or its file was released.
+func (r *ImportJobRepository) Resume(tenantID string, jobID uuid.UUID) error {
+	result := r.db.Model(&models.ImportJob{}).
+		Where("id = ? AND tenant_id = ? AND status = ? AND file_data IS NOT NULL",
+			jobID, tenantID, models.ImportStatusFailed).
+		Updates(map[string]interface{}{
+			"status":     models.ImportStatusPending,
+			"last_error": nil,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetErrors returns the persisted row errors for a job ordered by row number
+func (r *ImportJobRepository) GetErrors(tenantID string, jobID uuid.UUID) ([]models.ImportJobError, error) {
+	var rowErrors []models.ImportJobError
+	err := r.db.Where("job_id = ? AND tenant_id = ?", jobID, tenantID).
+		Order("row_number ASC, created_at ASC").
+		Find(&rowErrors).Error
+	return rowErrors, err
+}
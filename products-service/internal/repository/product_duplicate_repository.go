@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products-service/internal/models"
+)
+
+// duplicateKeySQL computes each match type's comparison key in SQL, mirroring the
+// models.Normalize* helpers used for new products
+var duplicateKeySQL = map[models.DuplicateMatchType]string{
+	models.DuplicateMatchSKU:  "lower(regexp_replace(sku, '[^[:alnum:]]+', '', 'g'))",
+	models.DuplicateMatchGTIN: "regexp_replace(trim(gtin), '[ -]', '', 'g')",
+	models.DuplicateMatchTitleBrand: "lower(trim(regexp_replace(name, '[^[:alnum:]]+', ' ', 'g'))) || '|' || " +
+		"lower(trim(regexp_replace(coalesce(brand, ''), '[^[:alnum:]]+', ' ', 'g')))",
+}
+
+// duplicateKeyPresentSQL excludes products with nothing to compare for a match type
+var duplicateKeyPresentSQL = map[models.DuplicateMatchType]string{
+	models.DuplicateMatchSKU:        "sku ~ '[[:alnum:]]'",
+	models.DuplicateMatchGTIN:       "gtin ~ '[[:alnum:]]'",
+	models.DuplicateMatchTitleBrand: "name ~ '[[:alnum:]]'",
+}
+
+// maxDuplicateCandidates bounds how many existing products a duplicate check loads
+const maxDuplicateCandidates = 1000
+
+// GetDuplicatePolicy returns the tenant's duplicate detection policy, or the default if unset
+func (r *ProductsRepository) GetDuplicatePolicy(tenantID string) (*models.ProductDuplicatePolicy, error) {
+	var policy models.ProductDuplicatePolicy
+	err := r.db.Where("tenant_id = ?", tenantID).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultDuplicatePolicy(tenantID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SaveDuplicatePolicy creates or replaces the tenant's duplicate detection policy
+func (r *ProductsRepository) SaveDuplicatePolicy(policy *models.ProductDuplicatePolicy) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mode", "match_sku", "match_gtin", "match_title_brand", "cross_vendor", "updated_by", "updated_at"}),
+	}).Create(policy).Error
+}
+
+// FindDuplicates compares new products against the tenant's existing (non-archived) products
+// using the checks enabled in the policy. Matches are keyed by the new product's index.
+func (r *ProductsRepository) FindDuplicates(tenantID string, policy *models.ProductDuplicatePolicy, products []*models.Product) (map[int][]models.DuplicateMatch, error) {
+	type productKeys struct {
+		sku, gtin, titleBrand string
+	}
+
+	keys := make([]productKeys, len(products))
+	var skuKeys, gtins, titleBrandKeys, vendorIDs []string
+	for i, p := range products {
+		keys[i] = productKeys{
+			sku:        models.NormalizeSKUKey(p.SKU),
+			gtin:       models.NormalizeGTIN(p.Gtin),
+			titleBrand: models.NormalizeTitleBrandKey(p.Name, p.Brand),
+		}
+		if policy.MatchSKU && keys[i].sku != "" {
+			skuKeys = append(skuKeys, keys[i].sku)
+		}
+		if policy.MatchGTIN && keys[i].gtin != "" {
+			gtins = append(gtins, keys[i].gtin)
+		}
+		if policy.MatchTitleBrand && keys[i].titleBrand != "" {
+			titleBrandKeys = append(titleBrandKeys, keys[i].titleBrand)
+		}
+		vendorIDs = append(vendorIDs, p.VendorID)
+	}
+
+	conditions := r.db.Where("1 = 0")
+	if len(skuKeys) > 0 {
+		conditions = conditions.Or(duplicateKeySQL[models.DuplicateMatchSKU]+" IN ?", skuKeys)
+	}
+	if len(gtins) > 0 {
+		conditions = conditions.Or(duplicateKeySQL[models.DuplicateMatchGTIN]+" IN ?", gtins)
+	}
+	if len(titleBrandKeys) > 0 {
+		conditions = conditions.Or(duplicateKeySQL[models.DuplicateMatchTitleBrand]+" IN ?", titleBrandKeys)
+	}
+	if len(skuKeys)+len(gtins)+len(titleBrandKeys) == 0 {
+		return nil, nil
+	}
+
+	query := r.db.Model(&models.Product{}).
+		Select("id, name, sku, brand, gtin, vendor_id, status").
+		Where("tenant_id = ? AND status <> ?", tenantID, models.ProductStatusArchived).
+		Where(conditions)
+	if !policy.CrossVendor {
+		query = query.Where("vendor_id IN ?", vendorIDs)
+	}
+
+	var existing []models.Product
+	if err := query.Limit(maxDuplicateCandidates).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	matches := make(map[int][]models.DuplicateMatch)
+	for i, p := range products {
+		for _, e := range existing {
+			if !policy.CrossVendor && e.VendorID != p.VendorID {
+				continue
+			}
+
+			var matchType models.DuplicateMatchType
+			switch {
+			case policy.MatchSKU && keys[i].sku != "" && models.NormalizeSKUKey(e.SKU) == keys[i].sku:
+				matchType = models.DuplicateMatchSKU
+			case policy.MatchGTIN && keys[i].gtin != "" && models.NormalizeGTIN(e.Gtin) == keys[i].gtin:
+				matchType = models.DuplicateMatchGTIN
+			case policy.MatchTitleBrand && keys[i].titleBrand != "" && models.NormalizeTitleBrandKey(e.Name, e.Brand) == keys[i].titleBrand:
+				matchType = models.DuplicateMatchTitleBrand
+			default:
+				continue
+			}
+
+			matches[i] = append(matches[i], models.DuplicateMatch{
+				ProductID: e.ID,
+				Name:      e.Name,
+				SKU:       e.SKU,
+				VendorID:  e.VendorID,
+				Status:    e.Status,
+				MatchType: matchType,
+			})
+		}
+	}
+	return matches, nil
+}
+
+// GetDuplicateClusters groups the tenant's existing products into probable duplicate clusters,
+// largest first. Clusters whose products were all dismissed as not duplicates are left out.
+func (r *ProductsRepository) GetDuplicateClusters(tenantID, vendorID string, policy *models.ProductDuplicatePolicy, limit int) ([]models.DuplicateCluster, error) {
+	type clusterRow struct {
+		MatchKey   string
+		ProductIDs string
+	}
+
+	var matchTypes []models.DuplicateMatchType
+	if policy.MatchSKU {
+		matchTypes = append(matchTypes, models.DuplicateMatchSKU)
+	}
+	if policy.MatchGTIN {
+		matchTypes = append(matchTypes, models.DuplicateMatchGTIN)
+	}
+	if policy.MatchTitleBrand {
+		matchTypes = append(matchTypes, models.DuplicateMatchTitleBrand)
+	}
+
+	var clusters []models.DuplicateCluster
+	clusterIDs := make([][]string, 0)
+	for _, matchType := range matchTypes {
+		keySQL := duplicateKeySQL[matchType]
+		groupBy := keySQL
+		if !policy.CrossVendor {
+			groupBy += ", vendor_id"
+		}
+
+		query := r.db.Model(&models.Product{}).
+			Select(keySQL+" AS match_key, string_agg(id::text, ',' ORDER BY created_at) AS product_ids").
+			Where("tenant_id = ? AND status <> ?", tenantID, models.ProductStatusArchived).
+			Where(duplicateKeyPresentSQL[matchType])
+		if vendorID != "" {
+			query = query.Where("vendor_id = ?", vendorID)
+		}
+
+		var rows []clusterRow
+		if err := query.Group(groupBy).Having("count(*) > 1").
+			Order("count(*) DESC").Limit(limit).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		matchKeys := make([]string, len(rows))
+		for i, row := range rows {
+			matchKeys[i] = row.MatchKey
+		}
+		dismissed, err := r.dismissedDuplicates(tenantID, matchType, matchKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			ids := strings.Split(row.ProductIDs, ",")
+			if allDismissed(dismissed[row.MatchKey], ids) {
+				continue
+			}
+			clusters = append(clusters, models.DuplicateCluster{MatchType: matchType, MatchKey: row.MatchKey})
+			clusterIDs = append(clusterIDs, ids)
+		}
+	}
+
+	if len(clusters) > limit {
+		clusters = clusters[:limit]
+		clusterIDs = clusterIDs[:limit]
+	}
+
+	var allIDs []string
+	for _, ids := range clusterIDs {
+		allIDs = append(allIDs, ids...)
+	}
+	if len(allIDs) == 0 {
+		return clusters, nil
+	}
+
+	var products []models.Product
+	if err := r.db.Where("tenant_id = ? AND id IN ?", tenantID, allIDs).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.Product, len(products))
+	for _, p := range products {
+		byID[p.ID.String()] = p
+	}
+
+	for i, ids := range clusterIDs {
+		clusters[i].Products = make([]models.Product, 0, len(ids))
+		for _, id := range ids {
+			if p, ok := byID[id]; ok {
+				clusters[i].Products = append(clusters[i].Products, p)
+			}
+		}
+	}
+	return clusters, nil
+}
+
+// dismissedDuplicates returns the products dismissed as not duplicates for each of the given keys
+func (r *ProductsRepository) dismissedDuplicates(tenantID string, matchType models.DuplicateMatchType, matchKeys []string) (map[string]map[string]bool, error) {
+	if len(matchKeys) == 0 {
+		return nil, nil
+	}
+
+	var resolutions []models.ProductDuplicateResolution
+	if err := r.db.Select("match_key, product_id").
+		Where("tenant_id = ? AND match_type = ? AND action = ? AND match_key IN ?",
+			tenantID, matchType, models.DuplicateResolutionDismiss, matchKeys).
+		Find(&resolutions).Error; err != nil {
+		return nil, err
+	}
+
+	dismissed := make(map[string]map[string]bool)
+	for _, res := range resolutions {
+		if dismissed[res.MatchKey] == nil {
+			dismissed[res.MatchKey] = make(map[string]bool)
+		}
+		dismissed[res.MatchKey][res.ProductID.String()] = true
+	}
+	return dismissed, nil
+}
+
+// ResolveDuplicates records the resolution of a duplicate cluster. For MERGE every product
+// other than the primary is archived.
+func (r *ProductsRepository) ResolveDuplicates(tenantID string, req *models.ResolveDuplicatesRequest, productIDs []uuid.UUID, primaryID *uuid.UUID, resolvedBy string) error {
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		resolutions := make([]models.ProductDuplicateResolution, len(productIDs))
+		var archiveIDs []uuid.UUID
+		for i, id := range productIDs {
+			resolutions[i] = models.ProductDuplicateResolution{
+				TenantID:   tenantID,
+				MatchType:  req.MatchType,
+				MatchKey:   req.MatchKey,
+				ProductID:  id,
+				Action:     req.Action,
+				ResolvedBy: resolvedBy,
+			}
+			if req.Action == models.DuplicateResolutionMerge {
+				resolutions[i].PrimaryProductID = primaryID
+				if id != *primaryID {
+					archiveIDs = append(archiveIDs, id)
+				}
+			}
+		}
+
+		if len(archiveIDs) > 0 {
+			if err := tx.Model(&models.Product{}).
+				Where("tenant_id = ? AND id IN ?", tenantID, archiveIDs).
+				Updates(map[string]interface{}{
+					"status":     models.ProductStatusArchived,
+					"updated_by": resolvedBy,
+					"updated_at": now,
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&resolutions).Error
+	})
+
+	if err == nil {
+		for _, id := range productIDs {
+			r.invalidateProductCaches(context.Background(), tenantID, id)
+		}
+	}
+	return err
+}
+
+// allDismissed reports whether every product in a cluster was dismissed under its key
+func allDismissed(dismissed map[string]bool, ids []string) bool {
+	if len(dismissed) == 0 {
+		return false
+	}
+	for _, id := range ids {
+		if !dismissed[id] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"products-service/internal/models"
+)
+
+// GetReviewQueue returns products awaiting approval, oldest submission first
+func (r *ProductsRepository) GetReviewQueue(tenantID, vendorID string, page, limit int) ([]models.Product, int64, error) {
+	var products []models.Product
+	var total int64
+
+	query := r.db.Model(&models.Product{}).
+		Where("tenant_id = ? AND status = ?", tenantID, models.ProductStatusPending)
+	if vendorID != "" {
+		query = query.Where("vendor_id = ?", vendorID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("submitted_at ASC NULLS FIRST").Order("updated_at ASC").
+		Offset(offset).Limit(limit).
+		Find(&products).Error
+	return products, total, err
+}
+
+// SubmitForReview moves a product to PENDING under a new approval request and resolves the
+// change requests the vendor was working on
+func (r *ProductsRepository) SubmitForReview(tenantID string, productID uuid.UUID, approvalRequestID string) error {
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Product{}).
+			Where("tenant_id = ? AND id = ?", tenantID, productID).
+			Updates(map[string]interface{}{
+				"status":              models.ProductStatusPending,
+				"approval_request_id": approvalRequestID,
+				"submitted_at":        now,
+				"updated_at":          now,
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.ProductChangeRequest{}).
+			Where("tenant_id = ? AND product_id = ? AND status = ?", tenantID, productID, models.ChangeRequestOpen).
+			Updates(map[string]interface{}{
+				"status":      models.ChangeRequestResolved,
+				"resolved_at": now,
+				"updated_at":  now,
+			}).Error
+	})
+
+	if err == nil {
+		r.invalidateProductCaches(context.Background(), tenantID, productID)
+	}
+	return err
+}
+
+// RequestChanges records a reviewer's change requests and sends the product back to its vendor.
+// It only applies while the product is still PENDING and returns false otherwise.
+func (r *ProductsRepository) RequestChanges(tenantID string, productID uuid.UUID, changes []models.ProductChangeRequest) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Product{}).
+			Where("tenant_id = ? AND id = ? AND status = ?", tenantID, productID, models.ProductStatusPending).
+			Updates(map[string]interface{}{
+				"status":     models.ProductStatusChangesRequested,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		updated = true
+		return tx.Create(&changes).Error
+	})
+
+	if err == nil && updated {
+		r.invalidateProductCaches(context.Background(), tenantID, productID)
+	}
+	return updated, err
+}
+
+// PublishApproved moves a PENDING product to ACTIVE. When approvalRequestID is set it must match
+// the product's current approval request, so approving an outdated request doesn't publish a
+// product that has since been sent back for changes. Returns false if nothing was published.
+func (r *ProductsRepository) PublishApproved(tenantID string, productID uuid.UUID, approvalRequestID string) (bool, error) {
+	query := r.db.Model(&models.Product{}).
+		Where("tenant_id = ? AND id = ? AND status = ?", tenantID, productID, models.ProductStatusPending)
+	if approvalRequestID != "" {
+		query = query.Where("approval_request_id IS NULL OR approval_request_id = ?", approvalRequestID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status":     models.ProductStatusActive,
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		r.invalidateProductCaches(context.Background(), tenantID, productID)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetChangeRequests returns a product's change requests, newest first
+func (r *ProductsRepository) GetChangeRequests(tenantID string, productID uuid.UUID, openOnly bool) ([]models.ProductChangeRequest, error) {
+	var changes []models.ProductChangeRequest
+	query := r.db.Where("tenant_id = ? AND product_id = ?", tenantID, productID)
+	if openOnly {
+		query = query.Where("status = ?", models.ChangeRequestOpen)
+	}
+	err := query.Order("created_at DESC").Find(&changes).Error
+	return changes, err
+}
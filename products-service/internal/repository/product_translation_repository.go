@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"products-service/internal/models"
+)
+
+// translationSources maps each translatable entity to its table and the column that identifies
+// it in imports and exports
+var translationSources = map[models.TranslationEntityType]struct {
+	table     string
+	keyColumn string
+	columns   string
+}{
+	models.TranslationEntityProduct:  {table: "products", keyColumn: "sku", columns: "id, sku AS \"key\", name, description, attributes, localizations"},
+	models.TranslationEntityCategory: {table: "categories", keyColumn: "slug", columns: "id, slug AS \"key\", name, description, NULL AS attributes, localizations"},
+}
+
+// ListTranslatableContent returns up to limit of a tenant's products or categories with their
+// translations, ordered by ID and starting after afterID, so callers can page through the
+// whole catalog
+func (r *ProductsRepository) ListTranslatableContent(tenantID string, entityType models.TranslationEntityType, afterID uuid.UUID, limit int) ([]models.TranslatableContent, error) {
+	source, ok := translationSources[entityType]
+	if !ok {
+		return nil, fmt.Errorf("unknown translation entity type %q", entityType)
+	}
+
+	var content []models.TranslatableContent
+	err := r.db.Table(source.table).
+		Select(source.columns).
+		Where("tenant_id = ? AND deleted_at IS NULL AND id > ?", tenantID, afterID).
+		Order("id").
+		Limit(limit).
+		Scan(&content).Error
+	return content, err
+}
+
+// GetTranslatableContent finds a product or category by ID or, without an ID, by SKU or slug.
+// It returns gorm.ErrRecordNotFound when there is no match.
+func (r *ProductsRepository) GetTranslatableContent(tenantID string, entityType models.TranslationEntityType, id *uuid.UUID, key string) (*models.TranslatableContent, error) {
+	source, ok := translationSources[entityType]
+	if !ok {
+		return nil, fmt.Errorf("unknown translation entity type %q", entityType)
+	}
+
+	query := r.db.Table(source.table).
+		Select(source.columns).
+		Where("tenant_id = ? AND deleted_at IS NULL", tenantID)
+	if id != nil {
+		query = query.Where("id = ?", *id)
+	} else {
+		query = query.Where(source.keyColumn+" = ?", key)
+	}
+
+	var content []models.TranslatableContent
+	if err := query.Limit(1).Scan(&content).Error; err != nil {
+		return nil, err
+	}
+	if len(content) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &content[0], nil
+}
+
+// SetTranslation stores a locale's translation of a product or category, replacing the
+// locale's previous translation and leaving other locales untouched
+func (r *ProductsRepository) SetTranslation(tenantID string, entityType models.TranslationEntityType, id uuid.UUID, locale string, translation *models.ContentTranslation) error {
+	source, ok := translationSources[entityType]
+	if !ok {
+		return fmt.Errorf("unknown translation entity type %q", entityType)
+	}
+
+	data, err := json.Marshal(translation)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.Exec(
+		"UPDATE "+source.table+" SET localizations = jsonb_set(COALESCE(localizations, '{}'::jsonb), ARRAY[?]::text[], ?::jsonb), updated_at = NOW() "+
+			"WHERE tenant_id = ? AND id = ? AND deleted_at IS NULL",
+		locale, string(data), tenantID, id,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	r.invalidateTranslationCaches(tenantID, entityType, id)
+	return nil
+}
+
+// DeleteTranslation removes a locale's translation of a product or category and reports
+// whether there was one
+func (r *ProductsRepository) DeleteTranslation(tenantID string, entityType models.TranslationEntityType, id uuid.UUID, locale string) (bool, error) {
+	source, ok := translationSources[entityType]
+	if !ok {
+		return false, fmt.Errorf("unknown translation entity type %q", entityType)
+	}
+
+	result := r.db.Exec(
+		"UPDATE "+source.table+" SET localizations = localizations - ?::text, updated_at = NOW() "+
+			"WHERE tenant_id = ? AND id = ? AND deleted_at IS NULL AND jsonb_exists(localizations, ?::text)",
+		locale, tenantID, id, locale,
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	r.invalidateTranslationCaches(tenantID, entityType, id)
+	return true, nil
+}
+
+func (r *ProductsRepository) invalidateTranslationCaches(tenantID string, entityType models.TranslationEntityType, id uuid.UUID) {
+	ctx := context.Background()
+	if entityType == models.TranslationEntityCategory {
+		r.invalidateCategoryCaches(ctx, tenantID, &id)
+		return
+	}
+	r.invalidateProductCaches(ctx, tenantID, id)
+}
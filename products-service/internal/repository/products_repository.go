@@ -12,8 +12,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/Tesseract-Nexus/go-shared/cache"
+	"products-service/internal/bulk"
 	"products-service/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Cache TTL constants
@@ -23,6 +25,10 @@ const (
 	CategoryCacheTTL    = 30 * time.Minute // Categories rarely change
 )
 
+// cascadeRestoreWindow bounds how long before a product's deletion a variant may have been
+// deleted and still be treated as part of the same cascade when the product is restored
+const cascadeRestoreWindow = time.Minute
+
 type ProductsRepository struct {
 	db    *gorm.DB
 	redis *redis.Client
@@ -181,11 +187,66 @@ func (r *ProductsRepository) BatchGetProductsByIDs(tenantID string, productIDs [
 }
 
 // UpdateProduct updates a product and invalidates cache
-func (r *ProductsRepository) UpdateProduct(tenantID string, productID uuid.UUID, updates *models.Product) error {
+// If expectedVersion is non-nil the update only applies while the stored version still matches,
+// otherwise a *models.VersionConflictError is returned. The version is incremented on every update.
+func (r *ProductsRepository) UpdateProduct(tenantID string, productID uuid.UUID, updates *models.Product, expectedVersion *int) error {
 	updates.UpdatedAt = time.Now()
-	err := r.db.Model(&models.Product{}).
-		Where("tenant_id = ? AND id = ?", tenantID, productID).
-		Updates(updates).Error
+	updates.Version = nil // managed below, never taken from the caller
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Product{}).
+			Where("tenant_id = ? AND id = ?", tenantID, productID)
+		if expectedVersion != nil {
+			// Rows created before versioning may still hold NULL, which counts as version 1
+			query = query.Where("COALESCE(version, 1) = ?", *expectedVersion)
+		}
+
+		var previousCost *string
+		if updates.CostPrice != nil {
+			var current models.Product
+			if err := tx.Select("cost_price").
+				Where("tenant_id = ? AND id = ?", tenantID, productID).
+				First(&current).Error; err != nil {
+				return err
+			}
+			previousCost = current.CostPrice
+		}
+
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var current models.Product
+			if err := tx.Select("version").
+				Where("tenant_id = ? AND id = ?", tenantID, productID).
+				First(&current).Error; err != nil {
+				return err
+			}
+			currentVersion := 1
+			if current.Version != nil {
+				currentVersion = *current.Version
+			}
+			return &models.VersionConflictError{CurrentVersion: currentVersion}
+		}
+
+		if updates.CostPrice != nil {
+			if err := recordCostChangeTx(tx, &models.ProductCostHistory{
+				TenantID:          tenantID,
+				ProductID:         productID,
+				PreviousCostPrice: previousCost,
+				CostPrice:         updates.CostPrice,
+				Source:            models.CostSourceManual,
+				ChangedBy:         updates.UpdatedBy,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.Product{}).
+			Where("tenant_id = ? AND id = ?", tenantID, productID).
+			UpdateColumn("version", gorm.Expr("COALESCE(version, 1) + 1")).Error
+	})
 
 	if err == nil {
 		// Invalidate all caches related to this product
@@ -223,6 +284,57 @@ func (r *ProductsRepository) DeleteProduct(tenantID string, productID uuid.UUID)
 	return err
 }
 
+// GetDeletedProducts retrieves soft-deleted products, most recently deleted first
+func (r *ProductsRepository) GetDeletedProducts(tenantID string, page, limit int) ([]models.Product, int64, error) {
+	var products []models.Product
+	var total int64
+
+	query := r.db.Unscoped().Model(&models.Product{}).
+		Where("tenant_id = ? AND deleted_at IS NOT NULL", tenantID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("deleted_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&products).Error
+	return products, total, err
+}
+
+// RestoreProduct restores a soft-deleted product along with the variants removed in the
+// same cascade delete. Returns gorm.ErrRecordNotFound if the product is not in the trash.
+func (r *ProductsRepository) RestoreProduct(tenantID string, productID uuid.UUID) (*models.Product, error) {
+	var product models.Product
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Where("tenant_id = ? AND id = ? AND deleted_at IS NOT NULL", tenantID, productID).
+			First(&product).Error; err != nil {
+			return err
+		}
+		deletedAt := product.DeletedAt.Time
+
+		if err := tx.Unscoped().Model(&models.Product{}).
+			Where("tenant_id = ? AND id = ?", tenantID, productID).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		// Variants deleted individually before the product stay in the trash
+		return tx.Unscoped().Model(&models.ProductVariant{}).
+			Where("product_id = ? AND deleted_at BETWEEN ? AND ?", productID, deletedAt.Add(-cascadeRestoreWindow), deletedAt).
+			Update("deleted_at", nil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	product.DeletedAt = nil
+	r.invalidateProductCaches(context.Background(), tenantID, productID)
+	return &product, nil
+}
+
 // GetProducts retrieves products with filters and pagination
 func (r *ProductsRepository) GetProducts(tenantID string, req *models.SearchProductsRequest) ([]models.Product, int64, error) {
 	var products []models.Product
@@ -263,42 +375,48 @@ func (r *ProductsRepository) GetProducts(tenantID string, req *models.SearchProd
 	return products, total, nil
 }
 
-// SearchProducts performs text search on products with full-text search
-func (r *ProductsRepository) SearchProducts(tenantID string, req *models.SearchProductsRequest) ([]models.Product, int64, error) {
+// SearchProducts performs text search on products with full-text search. Merchandising, if
+// given, reorders results ranked by relevance: pinned products first, whether or not they
+// match the query's text, then boosted and buried products by their rules' weights.
+func (r *ProductsRepository) SearchProducts(tenantID string, req *models.SearchProductsRequest, merchandising *models.SearchMerchandising) ([]models.Product, int64, error) {
 	var products []models.Product
 	var total int64
 
 	query := r.db.Model(&models.Product{}).Where("tenant_id = ?", tenantID)
 
-	// Apply full-text search with PostgreSQL tsvector
-	if req.Query != nil && *req.Query != "" {
-		searchQuery := strings.TrimSpace(*req.Query)
-
-		// Use PostgreSQL full-text search with weighted ranking
-		// A = highest weight (name), B = description, C = SKU, D = keywords
-		tsQuery := strings.Join(strings.Fields(searchQuery), " & ")
-
-		query = query.Where(
-			`(
-				setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
-				setweight(to_tsvector('english', COALESCE(description, '')), 'B') ||
-				setweight(to_tsvector('english', COALESCE(sku, '')), 'C') ||
-				setweight(to_tsvector('english', COALESCE(search_keywords, '')), 'D')
-			) @@ to_tsquery('english', ?)`,
-			tsQuery,
-		)
-
-		// Order by relevance (rank) when searching
-		if req.SortBy == nil || *req.SortBy == "" {
-			query = query.Select(`*,
-				ts_rank(
-					setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
-					setweight(to_tsvector('english', COALESCE(description, '')), 'B') ||
-					setweight(to_tsvector('english', COALESCE(sku, '')), 'C') ||
-					setweight(to_tsvector('english', COALESCE(search_keywords, '')), 'D'),
-					to_tsquery('english', ?)
-				) AS rank`, tsQuery)
-			query = query.Order("rank DESC, created_at DESC")
+	// Apply full-text search with PostgreSQL tsvector, matching each query term or any of
+	// its synonym and fuzzy expansions
+	searching := false
+	if req.Query != nil && strings.TrimSpace(*req.Query) != "" {
+		analysis, err := r.AnalyzeSearchQuery(tenantID, *req.Query)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if len(analysis.Terms) > 0 {
+			searching = true
+			tsQuery, args := searchTSQuerySQL(analysis.Terms)
+			byRelevance := req.SortBy == nil || *req.SortBy == ""
+
+			if byRelevance && merchandising != nil && len(merchandising.Pins) > 0 {
+				pinnedIDs := make([]uuid.UUID, len(merchandising.Pins))
+				for i, pin := range merchandising.Pins {
+					pinnedIDs[i] = pin.ProductID
+				}
+				query = query.Where("("+productSearchVectorSQL+" @@ ("+tsQuery+") OR id IN ?)", append(args, pinnedIDs)...)
+			} else {
+				query = query.Where(productSearchVectorSQL+" @@ ("+tsQuery+")", args...)
+			}
+
+			// Order by relevance (rank) when searching
+			if byRelevance {
+				query = query.Select("*, ts_rank("+productSearchVectorSQL+", "+tsQuery+") AS rank", args...)
+				if orderSQL, vars := merchandisingOrderSQL(merchandising); orderSQL != "" {
+					query = query.Order(clause.OrderBy{Expression: clause.Expr{SQL: orderSQL + ", rank DESC, created_at DESC", Vars: vars}})
+				} else {
+					query = query.Order("rank DESC, created_at DESC")
+				}
+			}
 		}
 	}
 
@@ -311,7 +429,7 @@ func (r *ProductsRepository) SearchProducts(tenantID string, req *models.SearchP
 	}
 
 	// Apply sorting and pagination
-	if req.Query == nil || *req.Query == "" {
+	if !searching {
 		if req.SortBy != nil && *req.SortBy != "" {
 			sortOrder := "DESC"
 			if req.SortOrder != nil && strings.ToUpper(*req.SortOrder) == "ASC" {
@@ -399,7 +517,7 @@ func (r *ProductsRepository) GetAvailableFilters(tenantID string, categoryID *st
 	if err := r.db.Raw(`
 		SELECT FLOOR(average_rating) as rating, COUNT(*) as count
 		FROM products
-		WHERE tenant_id = ? AND status = ? AND average_rating IS NOT NULL
+		WHERE tenant_id = ? AND status = ? AND average_rating IS NOT NULL AND deleted_at IS NULL
 		GROUP BY FLOOR(average_rating)
 		ORDER BY rating DESC
 	`, tenantID, models.ProductStatusActive).Scan(&ratingDistribution).Error; err != nil {
@@ -422,10 +540,10 @@ func (r *ProductsRepository) GetAvailableFilters(tenantID string, categoryID *st
 			if attrs, ok := item.Attributes["attributes"].(map[string]interface{}); ok {
 				for key, value := range attrs {
 					if attributeMap[key] == nil {
-						attributeMap[key] = make(map[string]bool)
+					attributeMap[key] = make(map[string]bool)
 					}
 					if strVal, ok := value.(string); ok {
-						attributeMap[key][strVal] = true
+					attributeMap[key][strVal] = true
 					}
 				}
 			}
@@ -568,6 +686,12 @@ func (r *ProductsRepository) BulkDeductInventory(tenantID string, items []models
 		return nil
 	}
 
+	// Bundles hold no stock of their own; deduct their components instead
+	items, err := expandBundleItemsTx(r.db, tenantID, items)
+	if err != nil {
+		return err
+	}
+
 	// Parse all product IDs upfront
 	productIDs := make([]uuid.UUID, 0, len(items))
 	itemMap := make(map[string]int) // productID -> quantity to deduct
@@ -645,6 +769,12 @@ func (r *ProductsRepository) BulkRestoreInventory(tenantID string, items []model
 		return nil
 	}
 
+	// Bundles hold no stock of their own; restore their components instead
+	items, err := expandBundleItemsTx(r.db, tenantID, items)
+	if err != nil {
+		return err
+	}
+
 	// Parse all product IDs upfront
 	productIDs := make([]uuid.UUID, 0, len(items))
 	itemMap := make(map[string]int) // productID -> quantity to restore
@@ -710,10 +840,22 @@ func (r *ProductsRepository) BulkRestoreInventory(tenantID string, items []model
 	})
 }
 
-// CheckStock checks if requested quantities are available for multiple products
+// CheckStock checks if requested quantities are available for multiple products.
+// A bundle is available as many times as its components' stock can make it.
 func (r *ProductsRepository) CheckStock(tenantID string, items []models.StockCheckItem) ([]models.StockCheckResult, error) {
 	results := make([]models.StockCheckResult, 0, len(items))
 
+	productIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		if productID, err := uuid.Parse(item.ProductID); err == nil {
+			productIDs = append(productIDs, productID)
+		}
+	}
+	bundles, err := getBundlesTx(r.db, tenantID, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, item := range items {
 		productID, err := uuid.Parse(item.ProductID)
 		if err != nil {
@@ -741,6 +883,11 @@ func (r *ProductsRepository) CheckStock(tenantID string, items []models.StockChe
 		if product.Quantity != nil {
 			inStock = *product.Quantity
 		}
+		costPrice := product.CostPrice
+		if bundle, isBundle := bundles[productID]; isBundle {
+			inStock = bundle.Availability()
+			costPrice = bundle.CostPrice()
+		}
 
 		results = append(results, models.StockCheckResult{
 			ProductID:   item.ProductID,
@@ -748,37 +895,46 @@ func (r *ProductsRepository) CheckStock(tenantID string, items []models.StockChe
 			InStock:     inStock,
 			Requested:   item.Quantity,
 			ProductName: product.Name,
+			CostPrice:   costPrice,
 		})
 	}
 
 	return results, nil
 }
 
-// BulkUpdateStatus updates status for multiple products
-func (r *ProductsRepository) BulkUpdateStatus(tenantID string, productIDs []uuid.UUID, status models.ProductStatus) error {
-	return r.db.Model(&models.Product{}).
-		Where("tenant_id = ? AND id IN ?", tenantID, productIDs).
-		Updates(map[string]interface{}{
-			"status":     status,
-			"updated_at": time.Now(),
-		}).Error
+// BulkUpdateStatus updates status for multiple products. Each product succeeds or fails on
+// its own unless opts.Atomic is set.
+func (r *ProductsRepository) BulkUpdateStatus(tenantID string, productIDs []string, status models.ProductStatus, opts bulk.Options) *bulk.Response {
+	return bulk.Run(r.db, len(productIDs), opts, func(tx *gorm.DB, i int) (bulk.Outcome, error) {
+		id, err := uuid.Parse(productIDs[i])
+		if err != nil {
+			return bulk.Outcome{}, bulk.Fail("INVALID_ID", "Invalid product ID format: %s", productIDs[i])
+		}
+		result := tx.Model(&models.Product{}).
+			Where("tenant_id = ? AND id = ?", tenantID, id).
+			Updates(map[string]interface{}{
+				"status":     status,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return bulk.Outcome{}, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return bulk.Outcome{}, bulk.Fail("NOT_FOUND", "Product %s not found", id)
+		}
+		var product models.Product
+		if err := tx.Where("tenant_id = ? AND id = ?", tenantID, id).First(&product).Error; err != nil {
+			return bulk.Outcome{}, err
+		}
+		return bulk.Updated(&product), nil
+	})
 }
 
 // ============================================================================
 // Bulk Create Operations
 // ============================================================================
 
-// BulkCreateResult represents the result of a bulk create operation
-type BulkCreateResult struct {
-	Created []*models.Product
-	Errors  []BulkCreateError
-	Total   int
-	Success int
-	Failed  int
-	Skipped int
-}
-
-// BulkCreateError represents an error for a single item in bulk create
+// BulkCreateError represents an error for a single item in bulk upsert
 type BulkCreateError struct {
 	Index      int
 	ExternalID *string
@@ -786,124 +942,69 @@ type BulkCreateError struct {
 	Message    string
 }
 
-// BulkCreate creates multiple products in a transaction with tenant isolation
+// BulkCreate creates multiple products with tenant isolation. Each product succeeds or fails
+// on its own unless opts.Atomic is set.
 // SECURITY: All products are assigned the provided tenantID regardless of request data
-func (r *ProductsRepository) BulkCreate(tenantID string, products []*models.Product, skipDuplicates bool) (*BulkCreateResult, error) {
-	result := &BulkCreateResult{
-		Created: make([]*models.Product, 0, len(products)),
-		Errors:  make([]BulkCreateError, 0),
-		Total:   len(products),
-	}
-
-	err := r.db.Transaction(func(tx *gorm.DB) error {
-		for i, product := range products {
-			// SECURITY: Always enforce tenant isolation
-			product.TenantID = tenantID
-			product.CreatedAt = time.Now()
-			product.UpdatedAt = time.Now()
-
-			// Ensure product has an ID before generating slug (for uniqueness)
-			if product.ID == uuid.Nil {
-				product.ID = uuid.New()
-			}
-
-			// Generate slug from name if not provided or empty
-			if product.Slug == nil || *product.Slug == "" {
-				baseSlug := generateSlug(product.Name)
-				// Ensure slug uniqueness by appending first 8 chars of product ID
-				uniqueSlug := fmt.Sprintf("%s-%s", baseSlug, product.ID.String()[:8])
-				product.Slug = &uniqueSlug
-			}
-
-			// Check for duplicate SKU within tenant (including soft-deleted records for unique constraint)
-			var existingCount int64
-			if err := tx.Unscoped().Model(&models.Product{}).
-				Where("tenant_id = ? AND sku = ?", tenantID, product.SKU).
-				Count(&existingCount).Error; err != nil {
-				result.Errors = append(result.Errors, BulkCreateError{
-					Index:   i,
-					Code:    "DB_ERROR",
-					Message: "Failed to check for duplicate SKU",
-				})
-				continue
-			}
-
-			if existingCount > 0 {
-				if skipDuplicates {
-					// Skip silently when skipDuplicates is true
-					result.Skipped++
-					continue
-				}
-				result.Errors = append(result.Errors, BulkCreateError{
-					Index:   i,
-					Code:    "DUPLICATE_SKU",
-					Message: fmt.Sprintf("Product with SKU '%s' already exists for this tenant", product.SKU),
-				})
-				continue
-			}
-
-			// Check for duplicate slug within tenant if provided (including soft-deleted records for unique constraint)
-			if product.Slug != nil && *product.Slug != "" {
-				var slugCount int64
-				if err := tx.Unscoped().Model(&models.Product{}).
-					Where("tenant_id = ? AND slug = ?", tenantID, *product.Slug).
-					Count(&slugCount).Error; err != nil {
-					result.Errors = append(result.Errors, BulkCreateError{
-						Index:   i,
-						Code:    "DB_ERROR",
-						Message: "Failed to check for duplicate slug",
-					})
-					continue
-				}
+func (r *ProductsRepository) BulkCreate(tenantID string, products []*models.Product, skipDuplicates bool, opts bulk.Options) *bulk.Response {
+	return bulk.Run(r.db, len(products), opts, func(tx *gorm.DB, i int) (bulk.Outcome, error) {
+		product := products[i]
+
+		// SECURITY: Always enforce tenant isolation
+		product.TenantID = tenantID
+		product.CreatedAt = time.Now()
+		product.UpdatedAt = time.Now()
+
+		// Ensure product has an ID before generating slug (for uniqueness)
+		if product.ID == uuid.Nil {
+			product.ID = uuid.New()
+		}
 
-				if slugCount > 0 {
-					if skipDuplicates {
-						result.Skipped++
-						continue
-					}
-					result.Errors = append(result.Errors, BulkCreateError{
-						Index:   i,
-						Code:    "DUPLICATE_SLUG",
-						Message: fmt.Sprintf("Product with slug '%s' already exists for this tenant", *product.Slug),
-					})
-					continue
-				}
-			}
+		// Generate slug from name if not provided or empty
+		if product.Slug == nil || *product.Slug == "" {
+			baseSlug := generateSlug(product.Name)
+			// Ensure slug uniqueness by appending first 8 chars of product ID
+			uniqueSlug := fmt.Sprintf("%s-%s", baseSlug, product.ID.String()[:8])
+			product.Slug = &uniqueSlug
+		}
 
-			// Set default status if not provided
-			if product.Status == "" {
-				product.Status = models.ProductStatusDraft
+		// Check for duplicate SKU within tenant (including soft-deleted records for unique constraint)
+		var existingCount int64
+		if err := tx.Unscoped().Model(&models.Product{}).
+			Where("tenant_id = ? AND sku = ?", tenantID, product.SKU).
+			Count(&existingCount).Error; err != nil {
+			return bulk.Outcome{}, bulk.Fail("DB_ERROR", "Failed to check for duplicate SKU")
+		}
+		if existingCount > 0 {
+			if skipDuplicates {
+				return bulk.Skipped(), nil
 			}
+			return bulk.Outcome{}, bulk.Fail("DUPLICATE_SKU", "Product with SKU '%s' already exists for this tenant", product.SKU)
+		}
 
-			// Create the product
-			if err := tx.Create(product).Error; err != nil {
-				result.Errors = append(result.Errors, BulkCreateError{
-					Index:   i,
-					Code:    "CREATE_FAILED",
-					Message: err.Error(),
-				})
-				continue
+		// Check for duplicate slug within tenant (including soft-deleted records for unique constraint)
+		var slugCount int64
+		if err := tx.Unscoped().Model(&models.Product{}).
+			Where("tenant_id = ? AND slug = ?", tenantID, *product.Slug).
+			Count(&slugCount).Error; err != nil {
+			return bulk.Outcome{}, bulk.Fail("DB_ERROR", "Failed to check for duplicate slug")
+		}
+		if slugCount > 0 {
+			if skipDuplicates {
+				return bulk.Skipped(), nil
 			}
-
-			result.Created = append(result.Created, product)
+			return bulk.Outcome{}, bulk.Fail("DUPLICATE_SLUG", "Product with slug '%s' already exists for this tenant", *product.Slug)
 		}
 
-		result.Success = len(result.Created)
-		result.Failed = len(result.Errors)
-
-		// If all failed (and none were intentionally skipped), rollback the transaction
-		if result.Success == 0 && result.Skipped == 0 && result.Total > 0 {
-			return fmt.Errorf("all products failed to create")
+		// Set default status if not provided
+		if product.Status == "" {
+			product.Status = models.ProductStatusDraft
 		}
 
-		return nil
+		if err := tx.Create(product).Error; err != nil {
+			return bulk.Outcome{}, bulk.Fail("CREATE_FAILED", "%s", err.Error())
+		}
+		return bulk.Created(product), nil
 	})
-
-	if err != nil && result.Success == 0 {
-		return result, err
-	}
-
-	return result, nil
 }
 
 // BulkUpsertResult represents the result of a bulk upsert operation
@@ -943,33 +1044,39 @@ func (r *ProductsRepository) BulkUpsert(tenantID string, products []*models.Prod
 				product.CreatedAt = existingProduct.CreatedAt // Preserve original creation time
 
 				// Update the product (excluding ID and tenant_id), also clear deleted_at to restore if needed
+				updates := map[string]interface{}{
+					"name":                product.Name,
+					"slug":                product.Slug,
+					"description":         product.Description,
+					"price":               product.Price,
+					"compare_price":       product.ComparePrice,
+					"cost_price":          product.CostPrice,
+					"category_id":         product.CategoryID,
+					"vendor_id":           product.VendorID,
+					"warehouse_id":        product.WarehouseID,
+					"warehouse_name":      product.WarehouseName,
+					"supplier_id":         product.SupplierID,
+					"supplier_name":       product.SupplierName,
+					"brand":               product.Brand,
+					"gtin":                product.Gtin,
+					"quantity":            product.Quantity,
+					"min_order_qty":       product.MinOrderQty,
+					"max_order_qty":       product.MaxOrderQty,
+					"low_stock_threshold": product.LowStockThreshold,
+					"weight":              product.Weight,
+					"search_keywords":     product.SearchKeywords,
+					"tags":                product.Tags,
+					"updated_at":          product.UpdatedAt,
+					"updated_by":          product.UpdatedBy,
+					"deleted_at":          nil, // Restore if soft-deleted
+				}
+				// Only replace images when the import supplied them, so re-running an import is idempotent
+				if product.Images != nil {
+					updates["images"] = product.Images
+				}
 				updateResult := tx.Unscoped().Model(&models.Product{}).
 					Where("id = ? AND tenant_id = ?", existingProduct.ID, tenantID).
-					Updates(map[string]interface{}{
-						"name":                product.Name,
-						"slug":                product.Slug,
-						"description":         product.Description,
-						"price":               product.Price,
-						"compare_price":       product.ComparePrice,
-						"cost_price":          product.CostPrice,
-						"category_id":         product.CategoryID,
-						"vendor_id":           product.VendorID,
-						"warehouse_id":        product.WarehouseID,
-						"warehouse_name":      product.WarehouseName,
-						"supplier_id":         product.SupplierID,
-						"supplier_name":       product.SupplierName,
-						"brand":               product.Brand,
-						"quantity":            product.Quantity,
-						"min_order_qty":       product.MinOrderQty,
-						"max_order_qty":       product.MaxOrderQty,
-						"low_stock_threshold": product.LowStockThreshold,
-						"weight":              product.Weight,
-						"search_keywords":     product.SearchKeywords,
-						"tags":                product.Tags,
-						"updated_at":          product.UpdatedAt,
-						"updated_by":          product.UpdatedBy,
-						"deleted_at":          nil, // Restore if soft-deleted
-					})
+					Updates(updates)
 
 				if updateResult.Error != nil {
 					result.Errors = append(result.Errors, BulkCreateError{
@@ -1119,13 +1226,40 @@ func (r *ProductsRepository) GetProductVariantByID(tenantID string, variantID uu
 	return &variant, nil
 }
 
-// UpdateProductVariant updates a product variant
-func (r *ProductsRepository) UpdateProductVariant(tenantID string, variantID uuid.UUID, updates *models.ProductVariant) error {
+// UpdateProductVariant updates a product variant, recording cost price changes made by changedBy
+func (r *ProductsRepository) UpdateProductVariant(tenantID string, variantID uuid.UUID, updates *models.ProductVariant, changedBy *string) error {
 	updates.UpdatedAt = time.Now()
-	return r.db.Model(&models.ProductVariant{}).
-		Joins("JOIN products ON products.id = product_variants.product_id").
-		Where("products.tenant_id = ? AND product_variants.id = ?", tenantID, variantID).
-		Updates(updates).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.ProductVariant
+		if updates.CostPrice != nil {
+			if err := tx.Model(&models.ProductVariant{}).
+				Select("product_variants.id", "product_variants.product_id", "product_variants.cost_price").
+				Joins("JOIN products ON products.id = product_variants.product_id").
+				Where("products.tenant_id = ? AND product_variants.id = ?", tenantID, variantID).
+				First(&current).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.ProductVariant{}).
+			Where("id = ? AND product_id IN (?)", variantID, tx.Model(&models.Product{}).Select("id").Where("tenant_id = ?", tenantID)).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+
+		if updates.CostPrice == nil {
+			return nil
+		}
+		return recordCostChangeTx(tx, &models.ProductCostHistory{
+			TenantID:          tenantID,
+			ProductID:         current.ProductID,
+			VariantID:         &current.ID,
+			PreviousCostPrice: current.CostPrice,
+			CostPrice:         updates.CostPrice,
+			Source:            models.CostSourceManual,
+			ChangedBy:         changedBy,
+		})
+	})
 }
 
 // DeleteProductVariant soft deletes a product variant
@@ -1147,6 +1281,7 @@ func (r *ProductsRepository) CreateCategory(tenantID string, category *models.Ca
 	if err == nil {
 		// Invalidate category list caches
 		r.invalidateCategoryCaches(context.Background(), tenantID, nil)
+		r.syncCategorySitemapEntries(tenantID, []uuid.UUID{category.ID})
 	}
 	return err
 }
@@ -1232,6 +1367,7 @@ func (r *ProductsRepository) UpdateCategory(tenantID string, categoryID uuid.UUI
 
 	if err == nil {
 		r.invalidateCategoryCaches(context.Background(), tenantID, &categoryID)
+		r.syncCategorySitemapEntries(tenantID, []uuid.UUID{categoryID})
 	}
 	return err
 }
@@ -1243,6 +1379,7 @@ func (r *ProductsRepository) DeleteCategory(tenantID string, categoryID uuid.UUI
 
 	if err == nil {
 		r.invalidateCategoryCaches(context.Background(), tenantID, &categoryID)
+		r.syncCategorySitemapEntries(tenantID, []uuid.UUID{categoryID})
 	}
 	return err
 }
@@ -1259,6 +1396,7 @@ func (r *ProductsRepository) BulkUpdateCategoryStatus(tenantID string, categoryI
 	if result.Error == nil && result.RowsAffected > 0 {
 		// Invalidate all category caches for this tenant
 		r.invalidateCategoryCaches(context.Background(), tenantID, nil)
+		r.syncCategorySitemapEntries(tenantID, categoryIDs)
 	}
 	return result.RowsAffected, result.Error
 }
@@ -1401,6 +1539,10 @@ func (r *ProductsRepository) GetProductsOverview(tenantID string) (*models.Produ
 
 // Helper function to apply product filters
 func (r *ProductsRepository) applyProductFilters(query *gorm.DB, req *models.SearchProductsRequest) *gorm.DB {
+	if req.SKU != nil {
+		query = query.Where("sku = ? OR id IN (SELECT product_id FROM product_variants WHERE sku = ?)", *req.SKU, *req.SKU)
+	}
+
 	if req.CategoryID != nil {
 		query = query.Where("category_id = ?", *req.CategoryID)
 	}
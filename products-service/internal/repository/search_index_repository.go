@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"products-service/internal/models"
+)
+
+// GetProductForIndex loads a product with its variants for the search index, bypassing the
+// product cache so the index never picks up a stale copy. Deleted products are not found.
+func (r *ProductsRepository) GetProductForIndex(tenantID string, productID uuid.UUID) (*models.Product, error) {
+	var product models.Product
+	if err := r.db.Preload("Variants").Where("tenant_id = ? AND id = ?", tenantID, productID).First(&product).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ListProductsForIndex returns the tenant's products with IDs after afterID, in ID order and
+// with their variants, for rebuilding the search index a batch at a time
+func (r *ProductsRepository) ListProductsForIndex(tenantID string, afterID uuid.UUID, limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Preload("Variants").
+		Where("tenant_id = ? AND id > ?", tenantID, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+// GetProductsInOrder loads the products a search index returned, in the index's order.
+// Products deleted since they were indexed are left out.
+func (r *ProductsRepository) GetProductsInOrder(tenantID string, productIDs []uuid.UUID, includeVariants bool) ([]models.Product, error) {
+	if len(productIDs) == 0 {
+		return []models.Product{}, nil
+	}
+
+	query := r.db.Where("tenant_id = ? AND id IN ?", tenantID, productIDs)
+	if includeVariants {
+		query = query.Preload("Variants")
+	}
+	var found []models.Product
+	if err := query.Find(&found).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]models.Product, len(found))
+	for _, product := range found {
+		byID[product.ID] = product
+	}
+	products := make([]models.Product, 0, len(found))
+	for _, id := range productIDs {
+		if product, ok := byID[id]; ok {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
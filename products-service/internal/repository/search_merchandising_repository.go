@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products-service/internal/models"
+)
+
+// numericSQLPattern matches stored prices that can be cast to DECIMAL
+const numericSQLPattern = `'^\s*[0-9]+(\.[0-9]+)?\s*$'`
+
+// maxActiveSearchRules bounds how many rules are applied to one search
+const maxActiveSearchRules = 50
+
+// ListSearchRules retrieves the tenant's search rules, most recently created first
+func (r *ProductsRepository) ListSearchRules(tenantID string, page, limit int) ([]models.SearchRule, int64, error) {
+	var rules []models.SearchRule
+	var total int64
+
+	query := r.db.Model(&models.SearchRule{}).Where("tenant_id = ?", tenantID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&rules).Error; err != nil {
+		return nil, 0, err
+	}
+	return rules, total, nil
+}
+
+// CreateSearchRule creates a search rule
+func (r *ProductsRepository) CreateSearchRule(rule *models.SearchRule) error {
+	rule.ID = uuid.New()
+	return r.db.Create(rule).Error
+}
+
+// UpdateSearchRule replaces a search rule's definition and schedule
+func (r *ProductsRepository) UpdateSearchRule(tenantID string, id uuid.UUID, update models.SearchRule, updatedBy *string) (*models.SearchRule, error) {
+	var rule models.SearchRule
+	if err := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).First(&rule).Error; err != nil {
+		return nil, err
+	}
+
+	rule.Name = update.Name
+	rule.Action = update.Action
+	rule.Field = update.Field
+	rule.Value = update.Value
+	rule.Weight = update.Weight
+	rule.Queries = update.Queries
+	rule.Enabled = update.Enabled
+	rule.StartsAt = update.StartsAt
+	rule.EndsAt = update.EndsAt
+	rule.UpdatedBy = updatedBy
+	rule.UpdatedAt = time.Now()
+	if err := r.db.Save(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DeleteSearchRule deletes a search rule
+func (r *ProductsRepository) DeleteSearchRule(tenantID string, id uuid.UUID) error {
+	result := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&models.SearchRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListSearchPins retrieves the tenant's pinned results, optionally for one search query,
+// grouped by query in position order
+func (r *ProductsRepository) ListSearchPins(tenantID, query string, page, limit int) ([]models.SearchPin, int64, error) {
+	var pins []models.SearchPin
+	var total int64
+
+	db := r.db.Model(&models.SearchPin{}).Where("tenant_id = ?", tenantID)
+	if query = models.NormalizeSearchTerm(query); query != "" {
+		db = db.Where("query = ?", query)
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := db.Order("query ASC, position ASC").Offset(offset).Limit(limit).Find(&pins).Error; err != nil {
+		return nil, 0, err
+	}
+	return pins, total, nil
+}
+
+// SaveSearchPin pins a product for a search query, moving and rescheduling the pin if the
+// product is already pinned for it
+func (r *ProductsRepository) SaveSearchPin(pin *models.SearchPin) error {
+	pin.ID = uuid.New()
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "query"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"position", "starts_at", "ends_at", "updated_by", "updated_at"}),
+	}).Create(pin).Error
+}
+
+// DeleteSearchPin unpins a result
+func (r *ProductsRepository) DeleteSearchPin(tenantID string, id uuid.UUID) error {
+	result := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&models.SearchPin{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetSearchMerchandising returns the rules and pins that apply to a search query at a time.
+// Scheduling is evaluated here, so activation needs no background job.
+func (r *ProductsRepository) GetSearchMerchandising(tenantID, query string, at time.Time) (*models.SearchMerchandising, error) {
+	query = models.NormalizeSearchTerm(query)
+	merchandising := &models.SearchMerchandising{
+		At:    at,
+		Rules: []models.SearchRule{},
+		Pins:  []models.SearchPin{},
+	}
+
+	var rules []models.SearchRule
+	err := r.db.Where("tenant_id = ? AND enabled = ?", tenantID, true).
+		Where("(starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?)", at, at).
+		Order("created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.AppliesTo(query) && len(merchandising.Rules) < maxActiveSearchRules {
+			merchandising.Rules = append(merchandising.Rules, rule)
+		}
+	}
+
+	if query == "" {
+		return merchandising, nil
+	}
+	err = r.db.Where("tenant_id = ? AND query = ?", tenantID, query).
+		Where("(starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?)", at, at).
+		Order("position ASC, created_at ASC").
+		Find(&merchandising.Pins).Error
+	if err != nil {
+		return nil, err
+	}
+	return merchandising, nil
+}
+
+// MatchSearchRules returns, for each rule, which of the given products it matches
+func (r *ProductsRepository) MatchSearchRules(tenantID string, rules []models.SearchRule, productIDs []uuid.UUID, at time.Time) ([]map[uuid.UUID]bool, error) {
+	matches := make([]map[uuid.UUID]bool, len(rules))
+	for i := range rules {
+		matches[i] = make(map[uuid.UUID]bool)
+		if len(productIDs) == 0 {
+			continue
+		}
+
+		condition, args := searchRuleConditionSQL(&rules[i], at)
+		var ids []uuid.UUID
+		err := r.db.Model(&models.Product{}).
+			Where("tenant_id = ? AND id IN ?", tenantID, productIDs).
+			Where(condition, args...).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			matches[i][id] = true
+		}
+	}
+	return matches, nil
+}
+
+// searchRuleConditionSQL builds the condition a product must meet for a rule to apply.
+// Prices are stored as text, so they're only compared when they're plain numbers.
+func searchRuleConditionSQL(rule *models.SearchRule, at time.Time) (string, []interface{}) {
+	number, _ := strconv.ParseFloat(rule.Value, 64)
+	switch rule.Field {
+	case models.SearchRuleInStock:
+		return "inventory_status IN ?", []interface{}{[]models.InventoryStatus{models.InventoryStatusInStock, models.InventoryStatusLowStock}}
+	case models.SearchRuleOnSale:
+		return "CASE WHEN price ~ " + numericSQLPattern + " AND compare_price ~ " + numericSQLPattern +
+			" THEN CAST(price AS DECIMAL) < CAST(compare_price AS DECIMAL) ELSE FALSE END", nil
+	case models.SearchRuleBrand:
+		return "lower(brand) = lower(?)", []interface{}{rule.Value}
+	case models.SearchRuleCategory:
+		return "category_id = ?", []interface{}{rule.Value}
+	case models.SearchRuleVendor:
+		return "vendor_id = ?", []interface{}{rule.Value}
+	case models.SearchRuleTag:
+		return "tags::text LIKE ?", []interface{}{"%\"" + rule.Value + "\"%"}
+	case models.SearchRuleMinRating:
+		return "COALESCE(average_rating, 0) >= ?", []interface{}{number}
+	case models.SearchRuleMinMargin:
+		return "CASE WHEN price ~ " + numericSQLPattern + " AND cost_price ~ " + numericSQLPattern +
+			" THEN (CAST(price AS DECIMAL) - CAST(cost_price AS DECIMAL)) / NULLIF(CAST(price AS DECIMAL), 0) * 100 >= ? ELSE FALSE END", []interface{}{number}
+	case models.SearchRuleNewWithinDays:
+		return "created_at >= ?", []interface{}{at.Add(-time.Duration(number * float64(24*time.Hour)))}
+	}
+	return "FALSE", nil
+}
+
+// merchandisingOrderSQL orders pinned products first, in position order, then the rest by
+// the sum of their matching rules' signed weights. Empty if there is nothing to order by.
+func merchandisingOrderSQL(merchandising *models.SearchMerchandising) (string, []interface{}) {
+	if merchandising.Empty() {
+		return "", nil
+	}
+
+	var columns []string
+	var vars []interface{}
+
+	if len(merchandising.Pins) > 0 {
+		var pinned strings.Builder
+		pinned.WriteString("CASE")
+		for _, pin := range merchandising.Pins {
+			pinned.WriteString(" WHEN id = ? THEN ?")
+			vars = append(vars, pin.ProductID, pin.Position)
+		}
+		pinned.WriteString(" END ASC NULLS LAST")
+		columns = append(columns, pinned.String())
+	}
+
+	if len(merchandising.Rules) > 0 {
+		parts := make([]string, len(merchandising.Rules))
+		for i := range merchandising.Rules {
+			condition, args := searchRuleConditionSQL(&merchandising.Rules[i], merchandising.At)
+			parts[i] = "CASE WHEN " + condition + " THEN ? ELSE 0 END"
+			vars = append(vars, args...)
+			vars = append(vars, merchandising.Rules[i].SignedWeight())
+		}
+		columns = append(columns, "("+strings.Join(parts, " + ")+") DESC")
+	}
+	return strings.Join(columns, ", "), vars
+}
@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products-service/internal/models"
+)
+
+// productSearchVectorSQL is the weighted document products are searched in:
+// A = highest weight (name), B = description, C = SKU, D = keywords
+const productSearchVectorSQL = `(
+	setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
+	setweight(to_tsvector('english', COALESCE(description, '')), 'B') ||
+	setweight(to_tsvector('english', COALESCE(sku, '')), 'C') ||
+	setweight(to_tsvector('english', COALESCE(search_keywords, '')), 'D')
+)`
+
+// catalogWordsSQL splits the tenant's product names and keywords into lowercase words,
+// keeping hyphens so "t-shirt" stays one word
+const catalogWordsSQL = `DISTINCT regexp_split_to_table(lower(name || ' ' || COALESCE(search_keywords, '')), '[^[:alnum:]-]+') AS word`
+
+const (
+	maxSearchTerms         = 10  // Query terms beyond this are ignored
+	maxFuzzyMatches        = 5   // Catalog words a term expands to
+	maxFuzzyTermLength     = 50  // Longer terms only match exactly
+	maxSynonymPhraseWords  = 3   // Longest synonym phrase matched in a query
+	maxSearchSynonymGroups = 500 // Synonym groups loaded per search
+)
+
+// GetSearchSettings returns the tenant's search settings, or the defaults if unset
+func (r *ProductsRepository) GetSearchSettings(tenantID string) (*models.SearchSettings, error) {
+	var settings models.SearchSettings
+	err := r.db.Where("tenant_id = ?", tenantID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultSearchSettings(tenantID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SaveSearchSettings creates or replaces the tenant's search settings
+func (r *ProductsRepository) SaveSearchSettings(settings *models.SearchSettings) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"fuzzy_enabled", "fuzzy_distance", "fuzzy_min_term_length",
+			"stop_words_enabled", "stop_words", "updated_by", "updated_at"}),
+	}).Create(settings).Error
+}
+
+// ListSearchSynonyms retrieves the tenant's synonym groups, optionally only those containing a term
+func (r *ProductsRepository) ListSearchSynonyms(tenantID, term string, page, limit int) ([]models.SearchSynonym, int64, error) {
+	var synonyms []models.SearchSynonym
+	var total int64
+
+	query := r.db.Model(&models.SearchSynonym{}).Where("tenant_id = ?", tenantID)
+	if term = models.NormalizeSearchTerm(term); term != "" {
+		filter, _ := json.Marshal([]string{term})
+		query = query.Where("terms @> ?::jsonb", string(filter))
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&synonyms).Error; err != nil {
+		return nil, 0, err
+	}
+	return synonyms, total, nil
+}
+
+// CreateSearchSynonym creates a synonym group
+func (r *ProductsRepository) CreateSearchSynonym(synonym *models.SearchSynonym) error {
+	synonym.ID = uuid.New()
+	return r.db.Create(synonym).Error
+}
+
+// UpdateSearchSynonym replaces a synonym group's terms and direction
+func (r *ProductsRepository) UpdateSearchSynonym(tenantID string, id uuid.UUID, terms []string, oneWay bool, updatedBy *string) (*models.SearchSynonym, error) {
+	var synonym models.SearchSynonym
+	if err := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).First(&synonym).Error; err != nil {
+		return nil, err
+	}
+
+	synonym.Terms = terms
+	synonym.OneWay = oneWay
+	synonym.UpdatedBy = updatedBy
+	synonym.UpdatedAt = time.Now()
+	if err := r.db.Save(&synonym).Error; err != nil {
+		return nil, err
+	}
+	return &synonym, nil
+}
+
+// DeleteSearchSynonym deletes a synonym group
+func (r *ProductsRepository) DeleteSearchSynonym(tenantID string, id uuid.UUID) error {
+	result := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&models.SearchSynonym{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// AnalyzeSearchQuery turns a search query into the terms products are matched on. The query
+// is normalized and split into words, words forming a synonym phrase are kept together, stop
+// words are dropped (unless nothing else is left), and each term is expanded with its
+// synonyms and the catalog words it fuzzily matches.
+func (r *ProductsRepository) AnalyzeSearchQuery(tenantID, query string) (*models.SearchQueryAnalysis, error) {
+	return r.analyzeSearchQuery(tenantID, query, true)
+}
+
+// ExpandSearchQuery analyzes a search query like AnalyzeSearchQuery but without looking up
+// fuzzy catalog matches, for the search index, which tolerates typos itself
+func (r *ProductsRepository) ExpandSearchQuery(tenantID, query string) (*models.SearchQueryAnalysis, error) {
+	return r.analyzeSearchQuery(tenantID, query, false)
+}
+
+func (r *ProductsRepository) analyzeSearchQuery(tenantID, query string, catalogFuzzy bool) (*models.SearchQueryAnalysis, error) {
+	settings, err := r.GetSearchSettings(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var synonyms []models.SearchSynonym
+	if err := r.db.Where("tenant_id = ?", tenantID).Limit(maxSearchSynonymGroups).Find(&synonyms).Error; err != nil {
+		return nil, err
+	}
+	phrases := make(map[string]bool)
+	for _, synonym := range synonyms {
+		for _, term := range synonym.Terms {
+			if strings.Contains(term, " ") {
+				phrases[term] = true
+			}
+		}
+	}
+
+	analysis := &models.SearchQueryAnalysis{
+		Query:            query,
+		Tokens:           strings.Fields(models.NormalizeSearchTerm(query)),
+		RemovedStopWords: []string{},
+		Terms:            []models.SearchTermAnalysis{},
+		Settings:         settings,
+	}
+
+	var terms, kept []string
+	for i := 0; i < len(analysis.Tokens); i++ {
+		term := analysis.Tokens[i]
+		for n := maxSynonymPhraseWords; n > 1; n-- {
+			if i+n > len(analysis.Tokens) {
+				continue
+			}
+			if phrase := strings.Join(analysis.Tokens[i:i+n], " "); phrases[phrase] {
+				term = phrase
+				i += n - 1
+				break
+			}
+		}
+		terms = append(terms, term)
+		if settings.IsStopWord(term) {
+			analysis.RemovedStopWords = append(analysis.RemovedStopWords, term)
+		} else {
+			kept = append(kept, term)
+		}
+	}
+	if len(kept) > 0 {
+		terms = kept
+	} else {
+		analysis.RemovedStopWords = []string{}
+	}
+
+	seen := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		if seen[term] || len(analysis.Terms) == maxSearchTerms {
+			continue
+		}
+		seen[term] = true
+
+		termAnalysis := models.SearchTermAnalysis{Term: term, Synonyms: []string{}, Fuzzy: []string{}}
+		for i := range synonyms {
+			for _, expansion := range synonyms[i].Expansions(term) {
+				if !containsString(termAnalysis.Synonyms, expansion) {
+					termAnalysis.Synonyms = append(termAnalysis.Synonyms, expansion)
+				}
+			}
+		}
+		if catalogFuzzy && !strings.Contains(term, " ") {
+			fuzzy, err := r.fuzzyCatalogMatches(tenantID, term, settings)
+			if err != nil {
+				return nil, err
+			}
+			for _, word := range fuzzy {
+				if !containsString(termAnalysis.Synonyms, word) {
+					termAnalysis.Fuzzy = append(termAnalysis.Fuzzy, word)
+				}
+			}
+		}
+		analysis.Terms = append(analysis.Terms, termAnalysis)
+	}
+	return analysis, nil
+}
+
+// fuzzyCatalogMatches finds catalog words that are the term written with or without hyphens,
+// such as "t-shirt" for "tshirt", and, if fuzzy matching is on, words within the tenant's
+// edit distance of the term. Closest words come first.
+func (r *ProductsRepository) fuzzyCatalogMatches(tenantID, term string, settings *models.SearchSettings) ([]string, error) {
+	length := utf8.RuneCountInString(term)
+	fuzzy := settings.FuzzyEnabled && settings.FuzzyDistance > 0 &&
+		length >= settings.FuzzyMinTermLength && length <= maxFuzzyTermLength
+
+	compact := strings.ReplaceAll(term, "-", "")
+	words := r.db.Model(&models.Product{}).Select(catalogWordsSQL).Where("tenant_id = ?", tenantID)
+	query := r.db.Table("(?) AS words", words).Where("word <> '' AND word <> ?", term)
+	if fuzzy {
+		// levenshtein rejects inputs over 255 characters; words that long are never close anyway
+		query = query.Where("replace(word, '-', '') = ? OR (abs(length(word) - ?) <= ? AND levenshtein(left(word, 255), ?) <= ?)",
+			compact, length, settings.FuzzyDistance, term, settings.FuzzyDistance).
+			Order(clause.OrderBy{Expression: clause.Expr{SQL: "levenshtein(left(word, 255), ?), word", Vars: []interface{}{term}}})
+	} else {
+		query = query.Where("replace(word, '-', '') = ?", compact).Order("word")
+	}
+
+	var matches []string
+	if err := query.Limit(maxFuzzyMatches).Pluck("word", &matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// ExplainSearchQuery analyzes a search query and includes the PostgreSQL query it searches with
+func (r *ProductsRepository) ExplainSearchQuery(tenantID, query string) (*models.SearchQueryAnalysis, error) {
+	analysis, err := r.AnalyzeSearchQuery(tenantID, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(analysis.Terms) == 0 {
+		return analysis, nil
+	}
+
+	tsQuery, args := searchTSQuerySQL(analysis.Terms)
+	if err := r.db.Raw("SELECT ("+tsQuery+")::text", args...).Scan(&analysis.TSQuery).Error; err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+// searchTSQuerySQL builds a tsquery requiring every term, each satisfied by any of its
+// alternatives. Alternatives are matched as phrases, so user input is never parsed as
+// tsquery syntax.
+func searchTSQuerySQL(terms []models.SearchTermAnalysis) (string, []interface{}) {
+	groups := make([]string, 0, len(terms))
+	var args []interface{}
+	for i := range terms {
+		alternatives := terms[i].Alternatives()
+		parts := make([]string, len(alternatives))
+		for j, alternative := range alternatives {
+			parts[j] = "phraseto_tsquery('english', ?)"
+			args = append(args, alternative)
+		}
+		groups = append(groups, "("+strings.Join(parts, " || ")+")")
+	}
+	return strings.Join(groups, " && "), args
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
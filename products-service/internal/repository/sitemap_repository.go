@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"products-service/internal/models"
+)
+
+// publishedCategorySQL matches the categories shown on the storefront
+const publishedCategorySQL = "COALESCE(is_active, true) AND status = 'ACTIVE' AND deleted_at IS NULL"
+
+// SyncProductSitemapEntry adds a product to its tenant's sitemap while it is ACTIVE and removes
+// it otherwise, including once it is deleted
+func (r *ProductsRepository) SyncProductSitemapEntry(tenantID string, productID uuid.UUID) error {
+	var product models.Product
+	err := r.db.Select("id", "status", "updated_at").
+		Where("tenant_id = ? AND id = ?", tenantID, productID).
+		First(&product).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err == nil && product.Status == models.ProductStatusActive {
+		return r.upsertSitemapEntry(tenantID, models.SitemapEntryProduct, productID.String(), product.UpdatedAt)
+	}
+	return r.deleteSitemapEntries(tenantID, models.SitemapEntryProduct, []string{productID.String()})
+}
+
+// syncCategorySitemapEntries brings the sitemap entries of the given categories in line with
+// whether they are shown on the storefront. Failures are left for the next rebuild to fix.
+func (r *ProductsRepository) syncCategorySitemapEntries(tenantID string, categoryIDs []uuid.UUID) {
+	ids := make([]string, len(categoryIDs))
+	for i, id := range categoryIDs {
+		ids[i] = id.String()
+	}
+
+	_ = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tenant_id = ? AND entity_type = ? AND entity_id IN ?", tenantID, models.SitemapEntryCategory, ids).
+			Delete(&models.SitemapEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			INSERT INTO storefront_sitemap_entries (tenant_id, entity_type, entity_id, path, last_modified, created_at, updated_at)
+			SELECT tenant_id, ?, id::text, ? || id::text, updated_at, NOW(), NOW()
+			FROM categories
+			WHERE tenant_id = ? AND id::text IN ? AND `+publishedCategorySQL,
+			models.SitemapEntryCategory, models.SitemapTypeInfo[models.SitemapEntryCategory].PathPrefix, tenantID, ids,
+		).Error
+	})
+}
+
+// RebuildSitemap regenerates a tenant's product and category entries from the catalog. Page
+// entries are replaced with pages unless it is nil, which keeps the current ones.
+func (r *ProductsRepository) RebuildSitemap(tenantID string, pages []models.SitemapEntry) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tenant_id = ? AND entity_type IN ?", tenantID,
+			[]models.SitemapEntryType{models.SitemapEntryProduct, models.SitemapEntryCategory}).
+			Delete(&models.SitemapEntry{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO storefront_sitemap_entries (tenant_id, entity_type, entity_id, path, last_modified, created_at, updated_at)
+			SELECT tenant_id, ?, id::text, ? || id::text, updated_at, NOW(), NOW()
+			FROM products
+			WHERE tenant_id = ? AND status = ? AND deleted_at IS NULL`,
+			models.SitemapEntryProduct, models.SitemapTypeInfo[models.SitemapEntryProduct].PathPrefix,
+			tenantID, models.ProductStatusActive,
+		).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO storefront_sitemap_entries (tenant_id, entity_type, entity_id, path, last_modified, created_at, updated_at)
+			SELECT tenant_id, ?, id::text, ? || id::text, updated_at, NOW(), NOW()
+			FROM categories
+			WHERE tenant_id = ? AND `+publishedCategorySQL,
+			models.SitemapEntryCategory, models.SitemapTypeInfo[models.SitemapEntryCategory].PathPrefix, tenantID,
+		).Error; err != nil {
+			return err
+		}
+
+		sitemap := models.StorefrontSitemap{TenantID: tenantID, LastRebuiltAt: now}
+		updateColumns := []string{"last_rebuilt_at", "updated_at"}
+		if pages != nil {
+			if err := tx.Where("tenant_id = ? AND entity_type = ?", tenantID, models.SitemapEntryPage).
+				Delete(&models.SitemapEntry{}).Error; err != nil {
+				return err
+			}
+			if len(pages) > 0 {
+				for i := range pages {
+					pages[i].TenantID = tenantID
+					pages[i].EntityType = models.SitemapEntryPage
+				}
+				if err := tx.CreateInBatches(pages, 500).Error; err != nil {
+					return err
+				}
+			}
+			sitemap.PagesSyncedAt = &now
+			updateColumns = append(updateColumns, "pages_synced_at")
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}},
+			DoUpdates: clause.AssignmentColumns(updateColumns),
+		}).Create(&sitemap).Error
+	})
+}
+
+// GetStorefrontSitemap returns when a tenant's sitemap was last rebuilt, or nil if it never was
+func (r *ProductsRepository) GetStorefrontSitemap(tenantID string) (*models.StorefrontSitemap, error) {
+	var sitemap models.StorefrontSitemap
+	err := r.db.Where("tenant_id = ?", tenantID).First(&sitemap).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sitemap, nil
+}
+
+// GetSitemapTenantsRebuiltBefore returns the tenants whose sitemap was last rebuilt before the
+// given time, least recently rebuilt first
+func (r *ProductsRepository) GetSitemapTenantsRebuiltBefore(before time.Time, limit int) ([]string, error) {
+	var tenantIDs []string
+	err := r.db.Model(&models.StorefrontSitemap{}).
+		Where("last_rebuilt_at < ?", before).
+		Order("last_rebuilt_at").
+		Limit(limit).
+		Pluck("tenant_id", &tenantIDs).Error
+	return tenantIDs, err
+}
+
+// GetSitemapSummary counts a tenant's sitemap entries by type
+func (r *ProductsRepository) GetSitemapSummary(tenantID string) ([]models.SitemapTypeSummary, error) {
+	var rows []struct {
+		EntityType   models.SitemapEntryType
+		Count        int64
+		LastModified *time.Time
+	}
+	err := r.db.Model(&models.SitemapEntry{}).
+		Select("entity_type, COUNT(*) AS count, MAX(last_modified) AS last_modified").
+		Where("tenant_id = ?", tenantID).
+		Group("entity_type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[models.SitemapEntryType]models.SitemapTypeSummary, len(rows))
+	for _, row := range rows {
+		byType[row.EntityType] = models.SitemapTypeSummary{EntityType: row.EntityType, Count: row.Count, LastModified: row.LastModified}
+	}
+	summary := make([]models.SitemapTypeSummary, len(models.SitemapEntryTypes))
+	for i, entityType := range models.SitemapEntryTypes {
+		summary[i] = byType[entityType]
+		summary[i].EntityType = entityType
+	}
+	return summary, nil
+}
+
+// GetSitemapEntries returns one sitemap file's worth of a tenant's entries of a type, in a
+// stable order
+func (r *ProductsRepository) GetSitemapEntries(tenantID string, entityType models.SitemapEntryType, offset, limit int) ([]models.SitemapEntry, error) {
+	var entries []models.SitemapEntry
+	err := r.db.Where("tenant_id = ? AND entity_type = ?", tenantID, entityType).
+		Order("entity_id").
+		Offset(offset).
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *ProductsRepository) upsertSitemapEntry(tenantID string, entityType models.SitemapEntryType, entityID string, lastModified time.Time) error {
+	entry := models.SitemapEntry{
+		TenantID:     tenantID,
+		EntityType:   entityType,
+		EntityID:     entityID,
+		Path:         models.SitemapTypeInfo[entityType].PathPrefix + entityID,
+		LastModified: lastModified,
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "entity_type"}, {Name: "entity_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"path", "last_modified", "updated_at"}),
+	}).Create(&entry).Error
+}
+
+func (r *ProductsRepository) deleteSitemapEntries(tenantID string, entityType models.SitemapEntryType, entityIDs []string) error {
+	return r.db.Where("tenant_id = ? AND entity_type = ? AND entity_id IN ?", tenantID, entityType, entityIDs).
+		Delete(&models.SitemapEntry{}).Error
+}
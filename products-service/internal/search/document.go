@@ -0,0 +1,107 @@
+package search
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"products-service/internal/models"
+)
+
+// document is a product as stored in the search index
+type document struct {
+	TenantID        string      `json:"tenantId"`
+	ProductID       string      `json:"productId"`
+	Name            string      `json:"name"`
+	NameSort        string      `json:"nameSort"`
+	Description     string      `json:"description,omitempty"`
+	Keywords        string      `json:"keywords,omitempty"`
+	SKUs            []string    `json:"skus"` // The product's and its variants'
+	Brand           string      `json:"brand,omitempty"`
+	CategoryID      string      `json:"categoryId"`
+	VendorID        string      `json:"vendorId"`
+	Status          string      `json:"status"`
+	InventoryStatus string      `json:"inventoryStatus,omitempty"`
+	Price           *float64    `json:"price,omitempty"`
+	AverageRating   *float64    `json:"averageRating,omitempty"`
+	Tags            []string    `json:"tags"`
+	Attributes      []attribute `json:"attributes"`
+	CreatedAt       time.Time   `json:"createdAt"`
+	UpdatedAt       time.Time   `json:"updatedAt"`
+}
+
+type attribute struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func newDocument(product *models.Product) document {
+	doc := document{
+		TenantID:      product.TenantID,
+		ProductID:     product.ID.String(),
+		Name:          product.Name,
+		NameSort:      strings.ToLower(product.Name),
+		SKUs:          []string{product.SKU},
+		CategoryID:    product.CategoryID,
+		VendorID:      product.VendorID,
+		Status:        string(product.Status),
+		AverageRating: product.AverageRating,
+		Tags:          []string{},
+		Attributes:    []attribute{},
+		CreatedAt:     product.CreatedAt,
+		UpdatedAt:     product.UpdatedAt,
+	}
+	if product.Description != nil {
+		doc.Description = *product.Description
+	}
+	if product.SearchKeywords != nil {
+		doc.Keywords = *product.SearchKeywords
+	}
+	if product.Brand != nil {
+		doc.Brand = *product.Brand
+	}
+	if product.InventoryStatus != nil {
+		doc.InventoryStatus = string(*product.InventoryStatus)
+	}
+	if price, err := strconv.ParseFloat(strings.TrimSpace(product.Price), 64); err == nil {
+		doc.Price = &price
+	}
+	for _, variant := range product.Variants {
+		if variant != nil && variant.SKU != "" {
+			doc.SKUs = append(doc.SKUs, variant.SKU)
+		}
+	}
+
+	// Tags and attributes are stored wrapped, as {"tags": [...]} and {"attributes": [...]}
+	if product.Tags != nil {
+		var tags struct {
+			Tags []string `json:"tags"`
+		}
+		if decodeJSON(*product.Tags, &tags) {
+			doc.Tags = append(doc.Tags, tags.Tags...)
+		}
+	}
+	if product.Attributes != nil {
+		var attributes struct {
+			Attributes []models.ProductAttribute `json:"attributes"`
+		}
+		if decodeJSON(*product.Attributes, &attributes) {
+			for _, attr := range attributes.Attributes {
+				if attr.Name != "" && attr.Value != "" {
+					doc.Attributes = append(doc.Attributes, attribute{Name: attr.Name, Value: attr.Value})
+				}
+			}
+		}
+	}
+	return doc
+}
+
+// decodeJSON re-decodes a JSON column into a typed value, reporting whether it could
+func decodeJSON(value models.JSON, into interface{}) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, into) == nil
+}
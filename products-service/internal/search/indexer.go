@@ -0,0 +1,69 @@
+package search
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"products-service/internal/models"
+)
+
+// reindexBatchSize is how many products a rebuild loads and indexes at a time
+const reindexBatchSize = 500
+
+// ProductSource loads products as they currently are in the database
+type ProductSource interface {
+	GetProductForIndex(tenantID string, productID uuid.UUID) (*models.Product, error)
+	ListProductsForIndex(tenantID string, afterID uuid.UUID, limit int) ([]models.Product, error)
+}
+
+// Indexer keeps the search index in line with the database
+type Indexer struct {
+	backend Backend
+	source  ProductSource
+}
+
+// NewIndexer creates an indexer writing to backend
+func NewIndexer(backend Backend, source ProductSource) *Indexer {
+	return &Indexer{backend: backend, source: source}
+}
+
+// Sync re-indexes one product from its current state, removing it once it is deleted
+func (i *Indexer) Sync(ctx context.Context, tenantID string, productID uuid.UUID) error {
+	product, err := i.source.GetProductForIndex(tenantID, productID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return i.backend.Delete(ctx, tenantID, productID)
+	}
+	if err != nil {
+		return err
+	}
+	return i.backend.Index(ctx, []models.Product{*product})
+}
+
+// ReindexTenant indexes every product of a tenant, for the initial load and to repair drift
+// from missed events. It returns how many products were indexed.
+func (i *Indexer) ReindexTenant(ctx context.Context, tenantID string) (int, error) {
+	indexed := 0
+	after := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return indexed, err
+		}
+		products, err := i.source.ListProductsForIndex(tenantID, after, reindexBatchSize)
+		if err != nil {
+			return indexed, err
+		}
+		if len(products) == 0 {
+			return indexed, nil
+		}
+		if err := i.backend.Index(ctx, products); err != nil {
+			return indexed, err
+		}
+		indexed += len(products)
+		after = products[len(products)-1].ID
+		if len(products) < reindexBatchSize {
+			return indexed, nil
+		}
+	}
+}
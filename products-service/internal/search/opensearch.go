@@ -0,0 +1,575 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"products-service/internal/models"
+)
+
+const (
+	// unavailableCooldown is how long searches go to SQL after the index fails
+	unavailableCooldown = 30 * time.Second
+	// Facet sizes
+	maxValueFacets     = 50
+	maxAttributeFacets = 20
+	maxAttributeValues = 30
+)
+
+// searchFields are the text fields matched against query terms, with their weights. They
+// follow the SQL path's ranking: name, then SKU, then description and brand, then keywords.
+var searchFields = []string{"name^4", "skus.text^3", "description^2", "brand.text^2", "keywords"}
+
+// DefaultPriceRanges are the upper bounds of the price range facet's buckets
+var DefaultPriceRanges = []float64{25, 50, 100, 250, 500, 1000}
+
+// indexDefinition creates the products index. Text is analyzed in English with accents
+// folded; IDs, statuses, tags and attributes are exact keywords for filters and facets.
+const indexDefinition = `{
+  "settings": {
+    "analysis": {
+      "analyzer": {
+        "product_text": {"type": "custom", "tokenizer": "standard", "filter": ["lowercase", "asciifolding", "english_possessive_stemmer", "english_stemmer"]}
+      },
+      "filter": {
+        "english_possessive_stemmer": {"type": "stemmer", "language": "possessive_english"},
+        "english_stemmer": {"type": "stemmer", "language": "english"}
+      }
+    }
+  },
+  "mappings": {
+    "dynamic": "strict",
+    "properties": {
+      "tenantId": {"type": "keyword"},
+      "productId": {"type": "keyword"},
+      "name": {"type": "text", "analyzer": "product_text"},
+      "nameSort": {"type": "keyword"},
+      "description": {"type": "text", "analyzer": "product_text"},
+      "keywords": {"type": "text", "analyzer": "product_text"},
+      "skus": {"type": "keyword", "fields": {"text": {"type": "text", "analyzer": "standard"}}},
+      "brand": {"type": "keyword", "fields": {"text": {"type": "text", "analyzer": "product_text"}}},
+      "categoryId": {"type": "keyword"},
+      "vendorId": {"type": "keyword"},
+      "status": {"type": "keyword"},
+      "inventoryStatus": {"type": "keyword"},
+      "price": {"type": "double"},
+      "averageRating": {"type": "double"},
+      "tags": {"type": "keyword"},
+      "attributes": {"type": "nested", "properties": {"name": {"type": "keyword"}, "value": {"type": "keyword"}}},
+      "createdAt": {"type": "date"},
+      "updatedAt": {"type": "date"}
+    }
+  }
+}`
+
+// OpenSearchConfig configures the OpenSearch backend
+type OpenSearchConfig struct {
+	URL         string
+	Index       string
+	Username    string
+	Password    string
+	PriceRanges []float64 // Defaults to DefaultPriceRanges
+}
+
+// OpenSearch is a Backend on an OpenSearch (or Elasticsearch) cluster. All tenants share one
+// index; documents are routed by tenant so a search only touches its tenant's shard.
+type OpenSearch struct {
+	baseURL     string
+	index       string
+	username    string
+	password    string
+	priceRanges []float64
+	httpClient  *http.Client
+	logger      *logrus.Entry
+
+	mu               sync.Mutex
+	unavailableUntil time.Time
+	indexReady       bool
+}
+
+// NewOpenSearch creates an OpenSearch backend
+func NewOpenSearch(cfg OpenSearchConfig, logger *logrus.Logger) *OpenSearch {
+	priceRanges := cfg.PriceRanges
+	if len(priceRanges) == 0 {
+		priceRanges = DefaultPriceRanges
+	}
+	return &OpenSearch{
+		baseURL:     strings.TrimSuffix(cfg.URL, "/"),
+		index:       cfg.Index,
+		username:    cfg.Username,
+		password:    cfg.Password,
+		priceRanges: priceRanges,
+		httpClient:  &http.Client{Timeout: 5 * time.Second},
+		logger:      logger.WithField("component", "opensearch"),
+	}
+}
+
+// EnsureIndex creates the products index if it doesn't exist. Searches and indexing call it
+// until it succeeds, so the index is created with its mapping even if the cluster was down
+// at startup.
+func (o *OpenSearch) EnsureIndex(ctx context.Context) error {
+	o.mu.Lock()
+	ready := o.indexReady
+	o.mu.Unlock()
+	if ready {
+		return nil
+	}
+
+	status, _, err := o.do(ctx, http.MethodHead, "/"+o.index, nil, "")
+	if err != nil {
+		return err
+	}
+	if status == http.StatusOK {
+		o.setIndexReady()
+		return nil
+	}
+
+	status, body, err := o.do(ctx, http.MethodPut, "/"+o.index, strings.NewReader(indexDefinition), "application/json")
+	if err != nil {
+		return err
+	}
+	// Another replica may have created it first
+	if status >= 300 && !strings.Contains(string(body), "resource_already_exists_exception") {
+		return fmt.Errorf("failed to create index %s: status %d: %s", o.index, status, body)
+	}
+	o.setIndexReady()
+	return nil
+}
+
+func (o *OpenSearch) setIndexReady() {
+	o.mu.Lock()
+	o.indexReady = true
+	o.mu.Unlock()
+}
+
+// Available reports whether the index has gone a cooldown without failing
+func (o *OpenSearch) Available() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return time.Now().After(o.unavailableUntil)
+}
+
+func (o *OpenSearch) markUnavailable(err error) {
+	o.mu.Lock()
+	o.unavailableUntil = time.Now().Add(unavailableCooldown)
+	o.mu.Unlock()
+	o.logger.WithError(err).Warn("Search index unavailable, falling back to SQL search")
+}
+
+// Search runs a product search against the index. Any failure marks the index unavailable,
+// so searches don't wait on it before falling back.
+func (o *OpenSearch) Search(ctx context.Context, query *Query) (*Result, error) {
+	if err := o.EnsureIndex(ctx); err != nil {
+		o.markUnavailable(err)
+		return nil, err
+	}
+	body, err := json.Marshal(o.searchBody(query))
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/%s/_search?routing=%s", o.index, url.QueryEscape(query.TenantID))
+	status, respBody, err := o.do(ctx, http.MethodPost, path, bytes.NewReader(body), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		err := fmt.Errorf("search failed: status %d: %s", status, truncate(respBody))
+		o.markUnavailable(err)
+		return nil, err
+	}
+
+	var resp searchResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+	return o.result(&resp), nil
+}
+
+// Index adds or replaces products with one bulk request
+func (o *OpenSearch) Index(ctx context.Context, products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	if err := o.EnsureIndex(ctx); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for i := range products {
+		action := map[string]interface{}{
+			"index": map[string]string{"_index": o.index, "_id": products[i].ID.String(), "routing": products[i].TenantID},
+		}
+		if err := encoder.Encode(action); err != nil {
+			return err
+		}
+		if err := encoder.Encode(newDocument(&products[i])); err != nil {
+			return err
+		}
+	}
+
+	status, respBody, err := o.do(ctx, http.MethodPost, "/_bulk", &body, "application/x-ndjson")
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("bulk index failed: status %d: %s", status, truncate(respBody))
+	}
+
+	var resp bulkResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if resp.Errors {
+		for _, item := range resp.Items {
+			if item.Index.Error != nil {
+				return fmt.Errorf("failed to index product %s: %s", item.Index.ID, item.Index.Error)
+			}
+		}
+	}
+	return nil
+}
+
+// Delete removes a product from the index
+func (o *OpenSearch) Delete(ctx context.Context, tenantID string, productID uuid.UUID) error {
+	path := fmt.Sprintf("/%s/_doc/%s?routing=%s", o.index, productID, url.QueryEscape(tenantID))
+	status, respBody, err := o.do(ctx, http.MethodDelete, path, nil, "")
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK && status != http.StatusNotFound {
+		return fmt.Errorf("delete failed: status %d: %s", status, truncate(respBody))
+	}
+	return nil
+}
+
+// do sends a request to the cluster. Connection failures and server errors mark the index
+// unavailable.
+func (o *OpenSearch) do(ctx context.Context, method, path string, body io.Reader, contentType string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, o.baseURL+path, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if o.username != "" {
+		req.SetBasicAuth(o.username, o.password)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		o.markUnavailable(err)
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		o.markUnavailable(err)
+		return 0, nil, err
+	}
+	if resp.StatusCode >= 500 {
+		err := fmt.Errorf("%s %s: status %d", method, path, resp.StatusCode)
+		o.markUnavailable(err)
+		return 0, nil, fmt.Errorf("%w: %s", err, truncate(respBody))
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+// searchBody builds the search request: every query term must match one of its alternatives
+// in a text field, within the tenant's typo tolerance, and every filter must hold
+func (o *OpenSearch) searchBody(query *Query) map[string]interface{} {
+	req := query.Request
+	filters := []interface{}{term("tenantId", query.TenantID)}
+	if req.SKU != nil {
+		filters = append(filters, term("skus", *req.SKU))
+	}
+	if req.CategoryID != nil {
+		filters = append(filters, term("categoryId", *req.CategoryID))
+	}
+	if req.VendorID != nil {
+		filters = append(filters, term("vendorId", *req.VendorID))
+	}
+	if len(req.Brands) > 0 {
+		filters = append(filters, terms("brand", req.Brands))
+	}
+	if len(req.Status) > 0 {
+		statuses := make([]string, len(req.Status))
+		for i, status := range req.Status {
+			statuses[i] = string(status)
+		}
+		filters = append(filters, terms("status", statuses))
+	}
+	if len(req.InventoryStatus) > 0 {
+		statuses := make([]string, len(req.InventoryStatus))
+		for i, status := range req.InventoryStatus {
+			statuses[i] = string(status)
+		}
+		filters = append(filters, terms("inventoryStatus", statuses))
+	}
+	if req.MinPrice != nil || req.MaxPrice != nil {
+		bounds := map[string]interface{}{}
+		if req.MinPrice != nil {
+			bounds["gte"], _ = strconv.ParseFloat(strings.TrimSpace(*req.MinPrice), 64)
+		}
+		if req.MaxPrice != nil {
+			bounds["lte"], _ = strconv.ParseFloat(strings.TrimSpace(*req.MaxPrice), 64)
+		}
+		filters = append(filters, rangeFilter("price", bounds))
+	}
+	if req.MinRating != nil {
+		filters = append(filters, rangeFilter("averageRating", map[string]interface{}{"gte": *req.MinRating}))
+	}
+	for _, tag := range req.Tags {
+		filters = append(filters, term("tags", tag))
+	}
+	for name, values := range req.Attributes {
+		if len(values) == 0 {
+			continue
+		}
+		filters = append(filters, map[string]interface{}{
+			"nested": map[string]interface{}{
+				"path": "attributes",
+				"query": map[string]interface{}{
+					"bool": map[string]interface{}{
+						"filter": []interface{}{term("attributes.name", name), terms("attributes.value", values)},
+					},
+				},
+			},
+		})
+	}
+	if req.DateFrom != nil || req.DateTo != nil {
+		bounds := map[string]interface{}{}
+		if req.DateFrom != nil {
+			bounds["gte"] = req.DateFrom.Format(time.RFC3339Nano)
+		}
+		if req.DateTo != nil {
+			bounds["lte"] = req.DateTo.Format(time.RFC3339Nano)
+		}
+		filters = append(filters, rangeFilter("createdAt", bounds))
+	}
+	if req.UpdatedAfter != nil {
+		filters = append(filters, rangeFilter("updatedAt", map[string]interface{}{"gt": req.UpdatedAfter.Format(time.RFC3339Nano)}))
+	}
+
+	boolQuery := map[string]interface{}{"filter": filters}
+	searching := query.Analysis != nil && len(query.Analysis.Terms) > 0
+	if searching {
+		must := make([]interface{}, len(query.Analysis.Terms))
+		for i := range query.Analysis.Terms {
+			must[i] = termQuery(&query.Analysis.Terms[i], query.Analysis.Settings)
+		}
+		boolQuery["must"] = must
+	}
+
+	return map[string]interface{}{
+		"from":             (req.Page - 1) * req.Limit,
+		"size":             req.Limit,
+		"track_total_hits": true,
+		"_source":          false,
+		"query":            map[string]interface{}{"bool": boolQuery},
+		"sort":             searchSort(req, searching),
+		"aggs":             o.facetAggregations(),
+	}
+}
+
+// termQuery matches one query term through the term itself or any of its synonyms. Single
+// words tolerate the tenant's fuzzy distance once they are long enough; phrases match exactly.
+func termQuery(analysis *models.SearchTermAnalysis, settings *models.SearchSettings) map[string]interface{} {
+	alternatives := analysis.Alternatives()
+	should := make([]interface{}, len(alternatives))
+	for i, alternative := range alternatives {
+		match := map[string]interface{}{
+			"query":  alternative,
+			"fields": searchFields,
+		}
+		if strings.Contains(alternative, " ") {
+			match["type"] = "phrase"
+		} else {
+			match["fuzziness"] = fuzziness(alternative, settings)
+			match["prefix_length"] = 1
+		}
+		should[i] = map[string]interface{}{"multi_match": match}
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{"should": should, "minimum_should_match": 1},
+	}
+}
+
+// fuzziness is how many edits a word may be off by under the tenant's search settings
+func fuzziness(word string, settings *models.SearchSettings) string {
+	if settings == nil || !settings.FuzzyEnabled || utf8.RuneCountInString(word) < settings.FuzzyMinTermLength {
+		return "0"
+	}
+	return strconv.Itoa(settings.FuzzyDistance)
+}
+
+// searchSort orders results like the SQL path: by relevance when searching text without a
+// sort, otherwise by the requested field, newest first by default
+func searchSort(req *models.SearchProductsRequest, searching bool) []interface{} {
+	tieBreak := map[string]interface{}{"productId": "asc"}
+	if req.SortBy == nil || *req.SortBy == "" {
+		if searching {
+			return []interface{}{"_score", map[string]interface{}{"createdAt": "desc"}, tieBreak}
+		}
+		return []interface{}{map[string]interface{}{"createdAt": "desc"}, tieBreak}
+	}
+
+	order := "desc"
+	if req.SortOrder != nil && strings.ToUpper(*req.SortOrder) == "ASC" {
+		order = "asc"
+	}
+	return []interface{}{
+		map[string]interface{}{sortFields[*req.SortBy]: map[string]interface{}{"order": order, "missing": "_last"}},
+		tieBreak,
+	}
+}
+
+func (o *OpenSearch) facetAggregations() map[string]interface{} {
+	ranges := make([]interface{}, 0, len(o.priceRanges)+1)
+	var from *float64
+	for i := range o.priceRanges {
+		bucket := map[string]interface{}{"to": o.priceRanges[i]}
+		if from != nil {
+			bucket["from"] = *from
+		}
+		ranges = append(ranges, bucket)
+		from = &o.priceRanges[i]
+	}
+	ranges = append(ranges, map[string]interface{}{"from": *from})
+
+	return map[string]interface{}{
+		"categories": map[string]interface{}{"terms": map[string]interface{}{"field": "categoryId", "size": maxValueFacets}},
+		"brands":     map[string]interface{}{"terms": map[string]interface{}{"field": "brand", "size": maxValueFacets}},
+		"prices":     map[string]interface{}{"range": map[string]interface{}{"field": "price", "ranges": ranges}},
+		"attributes": map[string]interface{}{
+			"nested": map[string]interface{}{"path": "attributes"},
+			"aggs": map[string]interface{}{
+				"names": map[string]interface{}{
+					"terms": map[string]interface{}{"field": "attributes.name", "size": maxAttributeFacets},
+					"aggs": map[string]interface{}{
+						"values": map[string]interface{}{"terms": map[string]interface{}{"field": "attributes.value", "size": maxAttributeValues}},
+					},
+				},
+			},
+		},
+	}
+}
+
+type termsAggregation struct {
+	Buckets []struct {
+		Key      string `json:"key"`
+		DocCount int64  `json:"doc_count"`
+	} `json:"buckets"`
+}
+
+type searchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			ID string `json:"_id"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations struct {
+		Categories termsAggregation `json:"categories"`
+		Brands     termsAggregation `json:"brands"`
+		Prices     struct {
+			Buckets []struct {
+				From     *float64 `json:"from"`
+				To       *float64 `json:"to"`
+				DocCount int64    `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"prices"`
+		Attributes struct {
+			Names struct {
+				Buckets []struct {
+					Key    string           `json:"key"`
+					Values termsAggregation `json:"values"`
+				} `json:"buckets"`
+			} `json:"names"`
+		} `json:"attributes"`
+	} `json:"aggregations"`
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []struct {
+		Index struct {
+			ID    string          `json:"_id"`
+			Error json.RawMessage `json:"error,omitempty"`
+		} `json:"index"`
+	} `json:"items"`
+}
+
+func (o *OpenSearch) result(resp *searchResponse) *Result {
+	result := &Result{
+		ProductIDs: make([]uuid.UUID, 0, len(resp.Hits.Hits)),
+		Total:      resp.Hits.Total.Value,
+		Facets: &models.SearchFacets{
+			Categories:  facetBuckets(resp.Aggregations.Categories),
+			Brands:      facetBuckets(resp.Aggregations.Brands),
+			PriceRanges: []models.PriceRangeBucket{},
+			Attributes:  map[string][]models.FacetBucket{},
+		},
+	}
+	for _, hit := range resp.Hits.Hits {
+		if id, err := uuid.Parse(hit.ID); err == nil {
+			result.ProductIDs = append(result.ProductIDs, id)
+		}
+	}
+	for _, bucket := range resp.Aggregations.Prices.Buckets {
+		result.Facets.PriceRanges = append(result.Facets.PriceRanges, models.PriceRangeBucket{
+			From:  bucket.From,
+			To:    bucket.To,
+			Count: bucket.DocCount,
+		})
+	}
+	for _, name := range resp.Aggregations.Attributes.Names.Buckets {
+		result.Facets.Attributes[name.Key] = facetBuckets(name.Values)
+	}
+	return result
+}
+
+func facetBuckets(agg termsAggregation) []models.FacetBucket {
+	buckets := make([]models.FacetBucket, 0, len(agg.Buckets))
+	for _, bucket := range agg.Buckets {
+		buckets = append(buckets, models.FacetBucket{Value: bucket.Key, Count: bucket.DocCount})
+	}
+	return buckets
+}
+
+func term(field string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{"term": map[string]interface{}{field: value}}
+}
+
+func terms(field string, values []string) map[string]interface{} {
+	return map[string]interface{}{"terms": map[string]interface{}{field: values}}
+}
+
+func rangeFilter(field string, bounds map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"range": map[string]interface{}{field: bounds}}
+}
+
+func truncate(body []byte) string {
+	const max = 500
+	if len(body) > max {
+		return string(body[:max]) + "..."
+	}
+	return string(body)
+}
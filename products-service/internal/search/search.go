@@ -0,0 +1,78 @@
+// Package search serves product searches from a dedicated search index, with relevance
+// ranking, typo tolerance and facets that the PostgreSQL full-text path lacks. The index is
+// optional: handlers fall back to the SQL search whenever it is not configured, cannot answer
+// a request or is unavailable.
+package search
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"products-service/internal/models"
+)
+
+// maxResultWindow is the deepest result a search index pages to by default
+const maxResultWindow = 10000
+
+// sortFields maps the sortBy values accepted by product search to index fields
+var sortFields = map[string]string{
+	"name":           "nameSort",
+	"price":          "price",
+	"created_at":     "createdAt",
+	"createdAt":      "createdAt",
+	"updated_at":     "updatedAt",
+	"updatedAt":      "updatedAt",
+	"average_rating": "averageRating",
+	"averageRating":  "averageRating",
+}
+
+// Backend is a product search index
+type Backend interface {
+	// Available reports whether searches should be sent to the index. It turns false for a
+	// while after the index fails.
+	Available() bool
+	// Search returns one page of matching product IDs, best match first
+	Search(ctx context.Context, query *Query) (*Result, error)
+	// Index adds or replaces products in the index
+	Index(ctx context.Context, products []models.Product) error
+	// Delete removes a product from the index. Deleting a product that isn't indexed succeeds.
+	Delete(ctx context.Context, tenantID string, productID uuid.UUID) error
+}
+
+// Query is a product search for one tenant
+type Query struct {
+	TenantID string
+	Request  *models.SearchProductsRequest
+	// Analysis is the query text split into terms and expanded with synonyms, or nil without
+	// query text
+	Analysis *models.SearchQueryAnalysis
+}
+
+// Result is one page of a search
+type Result struct {
+	ProductIDs []uuid.UUID
+	Total      int64
+	Facets     *models.SearchFacets
+}
+
+// Supports reports whether the index can answer a search the same way the SQL path would.
+// Unknown sort columns, prices that aren't numbers and pages beyond the index's result
+// window are left to SQL.
+func Supports(req *models.SearchProductsRequest) bool {
+	if req.SortBy != nil && *req.SortBy != "" {
+		if _, ok := sortFields[*req.SortBy]; !ok {
+			return false
+		}
+	}
+	for _, price := range []*string{req.MinPrice, req.MaxPrice} {
+		if price == nil {
+			continue
+		}
+		if _, err := strconv.ParseFloat(strings.TrimSpace(*price), 64); err != nil {
+			return false
+		}
+	}
+	return req.Page*req.Limit <= maxResultWindow
+}
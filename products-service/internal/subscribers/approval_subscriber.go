@@ -8,16 +8,16 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"products-service/internal/eventconsumer"
 	"products-service/internal/models"
 	"products-service/internal/repository"
 )
 
 // ApprovalSubscriber handles incoming approval events for products
 type ApprovalSubscriber struct {
-	subscriber *gosharedevents.Subscriber
+	consumer   *eventconsumer.Consumer
 	repo       *repository.ProductsRepository
 	logger     *logrus.Entry
-	cancel     context.CancelFunc
 }
 
 // NewApprovalSubscriber creates a new approval event subscriber for products
@@ -30,19 +30,20 @@ func NewApprovalSubscriber(
 		natsURL = "nats://nats.nats.svc.cluster.local:4222"
 	}
 
-	config := gosharedevents.DefaultSubscriberConfig(natsURL, "products-service-approvals")
-	config.Name = "products-service-approval-subscriber"
-	config.DeliverPolicy = "new"
-	config.MaxDeliver = 3
-	config.AckWait = 30 * time.Second
-
-	subscriber, err := gosharedevents.NewSubscriber(config, logger)
+	consumer, err := eventconsumer.New(eventconsumer.Config{
+		NATSURL:    natsURL,
+		ClientName: "products-service-approval-subscriber",
+		Durable:    "products-service-approvals",
+		Stream:     gosharedevents.StreamApprovals,
+		MaxDeliver: 3,
+		AckWait:    30 * time.Second,
+	}, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ApprovalSubscriber{
-		subscriber: subscriber,
+		consumer:   consumer,
 		repo:       repo,
 		logger:     logger.WithField("component", "approval-subscriber"),
 	}, nil
@@ -50,15 +51,12 @@ func NewApprovalSubscriber(
 
 // Start starts listening for approval events
 func (s *ApprovalSubscriber) Start(ctx context.Context) error {
-	ctx, cancel := context.WithCancel(ctx)
-	s.cancel = cancel
-
 	// Subscribe to approval.granted events
 	subjects := []string{gosharedevents.ApprovalGranted}
 
 	s.logger.Info("Starting product approval event subscription...")
 
-	err := s.subscriber.SubscribeApprovalEvents(ctx, subjects, s.handleApprovalEvent)
+	err := s.consumer.Start(ctx, subjects, eventconsumer.JSON(s.handleApprovalEvent))
 	if err != nil {
 		return err
 	}
@@ -103,7 +101,7 @@ func (s *ApprovalSubscriber) handleApprovalEvent(ctx context.Context, event *gos
 	}
 }
 
-// executeProductPublish publishes an approved product (DRAFT -> ACTIVE)
+// executeProductPublish publishes an approved product (PENDING -> ACTIVE)
 func (s *ApprovalSubscriber) executeProductPublish(ctx context.Context, event *gosharedevents.ApprovalEvent, tenantID string) error {
 	s.logger.WithFields(logrus.Fields{
 		"resource_id": event.ResourceID,
@@ -117,11 +115,19 @@ func (s *ApprovalSubscriber) executeProductPublish(ctx context.Context, event *g
 		return nil // Don't retry for invalid IDs
 	}
 
-	// Update product status to ACTIVE
-	if err := s.repo.UpdateProductStatus(tenantID, productID, models.ProductStatusActive, nil); err != nil {
+	// Publish only if the product is still waiting on this approval request
+	published, err := s.repo.PublishApproved(tenantID, productID, event.ApprovalRequestID)
+	if err != nil {
 		s.logger.WithError(err).Error("Failed to update product status to ACTIVE")
 		return err
 	}
+	if !published {
+		s.logger.WithFields(logrus.Fields{
+			"product_id":  productID,
+			"approval_id": event.ApprovalRequestID,
+		}).Info("Product is no longer pending this approval, skipping publish")
+		return nil
+	}
 
 	s.logger.WithFields(logrus.Fields{
 		"product_id": productID,
@@ -159,11 +165,8 @@ func (s *ApprovalSubscriber) executeProductUpdate(ctx context.Context, event *go
 
 // Stop stops the approval subscriber
 func (s *ApprovalSubscriber) Stop() {
-	if s.cancel != nil {
-		s.cancel()
-	}
-	if s.subscriber != nil {
-		s.subscriber.Close()
+	if s.consumer != nil {
+		s.consumer.Stop()
 	}
 	s.logger.Info("Product approval subscriber stopped")
 }
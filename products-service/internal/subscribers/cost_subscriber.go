@@ -0,0 +1,139 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"products-service/internal/repository"
+)
+
+// LandedCostsReceived is published by inventory-service when a purchase order receipt
+// records landed costs
+const LandedCostsReceived = "inventory.landed_costs_received"
+
+// landedCostEvent is inventory-service's landed cost event
+type landedCostEvent struct {
+	gosharedevents.BaseEvent
+	PurchaseOrderID string `json:"purchaseOrderId"`
+	WarehouseID     string `json:"warehouseId"`
+	CurrencyCode    string `json:"currencyCode"`
+	Lines           []struct {
+		ProductID      string  `json:"productId"`
+		VariantID      string  `json:"variantId,omitempty"`
+		Quantity       int     `json:"quantity"`
+		LandedUnitCost float64 `json:"landedUnitCost"`
+	} `json:"lines"`
+}
+
+// CostSubscriber keeps product and variant cost prices at the landed unit cost of their
+// latest purchase order receipt
+type CostSubscriber struct {
+	subscriber *gosharedevents.Subscriber
+	repo       *repository.ProductsRepository
+	logger     *logrus.Entry
+	cancel     context.CancelFunc
+}
+
+// NewCostSubscriber creates a new landed cost event subscriber
+func NewCostSubscriber(
+	repo *repository.ProductsRepository,
+	logger *logrus.Logger,
+) (*CostSubscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	config := gosharedevents.DefaultSubscriberConfig(natsURL, "products-service-landed-costs")
+	config.Name = "products-service-cost-subscriber"
+	config.DeliverPolicy = "new"
+	config.MaxDeliver = 3
+	config.AckWait = 30 * time.Second
+
+	subscriber, err := gosharedevents.NewSubscriber(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CostSubscriber{
+		subscriber: subscriber,
+		repo:       repo,
+		logger:     logger.WithField("component", "cost-subscriber"),
+	}, nil
+}
+
+// Start starts listening for landed cost events
+func (s *CostSubscriber) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	subjects := []string{LandedCostsReceived}
+
+	err := s.subscriber.Subscribe(ctx, gosharedevents.StreamInventory, subjects, s.handleLandedCosts)
+	if err != nil {
+		return err
+	}
+
+	s.logger.WithField("subjects", subjects).Info("Cost subscriber started successfully")
+	return nil
+}
+
+// handleLandedCosts applies each received line's landed unit cost. Lines for products that
+// aren't in the catalog are skipped.
+func (s *CostSubscriber) handleLandedCosts(ctx context.Context, msg *gosharedevents.Message) error {
+	var event landedCostEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		s.logger.WithError(err).WithField("subject", msg.Subject).Error("Failed to decode landed cost event")
+		return nil // Don't retry malformed events
+	}
+	if event.TenantID == "" {
+		return nil
+	}
+
+	for _, line := range event.Lines {
+		productID, err := uuid.Parse(line.ProductID)
+		if err != nil || line.LandedUnitCost <= 0 {
+			continue
+		}
+		var variantID *uuid.UUID
+		if line.VariantID != "" {
+			id, err := uuid.Parse(line.VariantID)
+			if err != nil {
+				continue
+			}
+			variantID = &id
+		}
+
+		logger := s.logger.WithFields(logrus.Fields{
+			"tenant_id":         event.TenantID,
+			"product_id":        line.ProductID,
+			"variant_id":        line.VariantID,
+			"purchase_order_id": event.PurchaseOrderID,
+		})
+		changed, err := s.repo.ApplyLandedCost(event.TenantID, productID, variantID, line.LandedUnitCost, event.PurchaseOrderID)
+		if err != nil {
+			logger.WithError(err).Error("Failed to apply landed cost")
+			return err
+		}
+		if changed {
+			logger.WithField("landed_unit_cost", line.LandedUnitCost).Info("Cost price updated from landed cost")
+		}
+	}
+	return nil
+}
+
+// Stop stops the cost subscriber
+func (s *CostSubscriber) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.subscriber != nil {
+		s.subscriber.Close()
+	}
+	s.logger.Info("Cost subscriber stopped")
+}
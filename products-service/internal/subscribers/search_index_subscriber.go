@@ -0,0 +1,103 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"products-service/internal/search"
+)
+
+// SearchIndexSubscriber keeps the product search index current as products are created,
+// changed and deleted
+type SearchIndexSubscriber struct {
+	subscriber *gosharedevents.Subscriber
+	indexer    *search.Indexer
+	logger     *logrus.Entry
+	cancel     context.CancelFunc
+}
+
+// NewSearchIndexSubscriber creates a new product event subscriber for the search index
+func NewSearchIndexSubscriber(
+	indexer *search.Indexer,
+	logger *logrus.Logger,
+) (*SearchIndexSubscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	config := gosharedevents.DefaultSubscriberConfig(natsURL, "products-service-search-index")
+	config.Name = "products-service-search-index-subscriber"
+	config.DeliverPolicy = "new"
+	config.MaxDeliver = 5
+	config.AckWait = 30 * time.Second
+
+	subscriber, err := gosharedevents.NewSubscriber(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchIndexSubscriber{
+		subscriber: subscriber,
+		indexer:    indexer,
+		logger:     logger.WithField("component", "search-index-subscriber"),
+	}, nil
+}
+
+// Start starts listening for product events
+func (s *SearchIndexSubscriber) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	subjects := []string{"product.>"}
+
+	err := s.subscriber.Subscribe(ctx, gosharedevents.StreamProducts, subjects, s.handleProductEvent)
+	if err != nil {
+		return err
+	}
+
+	s.logger.WithField("subjects", subjects).Info("Search index subscriber started successfully")
+	return nil
+}
+
+// handleProductEvent re-indexes the product from its current state, so out-of-order and
+// repeated events leave the index correct. Failures are redelivered; products whose events
+// run out of deliveries are fixed by the next reindex.
+func (s *SearchIndexSubscriber) handleProductEvent(ctx context.Context, msg *gosharedevents.Message) error {
+	var event gosharedevents.ProductEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		s.logger.WithError(err).WithField("subject", msg.Subject).Error("Failed to decode product event")
+		return nil // Don't retry malformed events
+	}
+
+	productID, err := uuid.Parse(event.ProductID)
+	if err != nil || event.TenantID == "" {
+		s.logger.WithField("product_id", event.ProductID).Debug("Ignoring product event without product or tenant")
+		return nil
+	}
+
+	if err := s.indexer.Sync(ctx, event.TenantID, productID); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"tenant_id":  event.TenantID,
+			"product_id": event.ProductID,
+		}).Error("Failed to update search index")
+		return err
+	}
+	return nil
+}
+
+// Stop stops the search index subscriber
+func (s *SearchIndexSubscriber) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.subscriber != nil {
+		s.subscriber.Close()
+	}
+	s.logger.Info("Search index subscriber stopped")
+}
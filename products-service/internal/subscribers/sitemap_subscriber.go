@@ -0,0 +1,102 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"products-service/internal/repository"
+)
+
+// SitemapSubscriber keeps storefront sitemaps current as products are published, changed,
+// archived or deleted
+type SitemapSubscriber struct {
+	subscriber *gosharedevents.Subscriber
+	repo       *repository.ProductsRepository
+	logger     *logrus.Entry
+	cancel     context.CancelFunc
+}
+
+// NewSitemapSubscriber creates a new product event subscriber for sitemaps
+func NewSitemapSubscriber(
+	repo *repository.ProductsRepository,
+	logger *logrus.Logger,
+) (*SitemapSubscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	config := gosharedevents.DefaultSubscriberConfig(natsURL, "products-service-sitemap")
+	config.Name = "products-service-sitemap-subscriber"
+	config.DeliverPolicy = "new"
+	config.MaxDeliver = 3
+	config.AckWait = 30 * time.Second
+
+	subscriber, err := gosharedevents.NewSubscriber(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SitemapSubscriber{
+		subscriber: subscriber,
+		repo:       repo,
+		logger:     logger.WithField("component", "sitemap-subscriber"),
+	}, nil
+}
+
+// Start starts listening for product events
+func (s *SitemapSubscriber) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	subjects := []string{"product.>"}
+
+	err := s.subscriber.Subscribe(ctx, gosharedevents.StreamProducts, subjects, s.handleProductEvent)
+	if err != nil {
+		return err
+	}
+
+	s.logger.WithField("subjects", subjects).Info("Sitemap subscriber started successfully")
+	return nil
+}
+
+// handleProductEvent re-checks whether the product belongs in its tenant's sitemap. The event
+// itself is only a trigger; the product's current state decides.
+func (s *SitemapSubscriber) handleProductEvent(ctx context.Context, msg *gosharedevents.Message) error {
+	var event gosharedevents.ProductEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		s.logger.WithError(err).WithField("subject", msg.Subject).Error("Failed to decode product event")
+		return nil // Don't retry malformed events
+	}
+
+	productID, err := uuid.Parse(event.ProductID)
+	if err != nil || event.TenantID == "" {
+		s.logger.WithField("product_id", event.ProductID).Debug("Ignoring product event without product or tenant")
+		return nil
+	}
+
+	if err := s.repo.SyncProductSitemapEntry(event.TenantID, productID); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"tenant_id":  event.TenantID,
+			"product_id": event.ProductID,
+		}).Error("Failed to update sitemap entry")
+		return err
+	}
+	return nil
+}
+
+// Stop stops the sitemap subscriber
+func (s *SitemapSubscriber) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.subscriber != nil {
+		s.subscriber.Close()
+	}
+	s.logger.Info("Sitemap subscriber stopped")
+}
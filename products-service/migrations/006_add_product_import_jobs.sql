@@ -0,0 +1,50 @@
+-- Migration: Asynchronous product import jobs
+-- Large imports are processed in chunks by a background worker. The uploaded file is
+-- kept on the job until it completes so a failed import can resume from next_row.
+
+CREATE TABLE IF NOT EXISTS product_import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    mode VARCHAR(20) NOT NULL DEFAULT 'create',
+    file_name TEXT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    file_data BYTEA,
+    skip_duplicates BOOLEAN NOT NULL DEFAULT FALSE,
+    batch_size INTEGER NOT NULL DEFAULT 100,
+    max_retries INTEGER NOT NULL DEFAULT 2,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    next_row INTEGER NOT NULL DEFAULT 0,
+    created_count INTEGER NOT NULL DEFAULT 0,
+    updated_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    skipped_count INTEGER NOT NULL DEFAULT 0,
+    error_count INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE,
+    heartbeat_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255),
+    user_email VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_jobs_tenant_status ON product_import_jobs(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_product_import_jobs_status ON product_import_jobs(status);
+
+CREATE TABLE IF NOT EXISTS product_import_job_errors (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id UUID NOT NULL REFERENCES product_import_jobs(id) ON DELETE CASCADE,
+    tenant_id VARCHAR(255) NOT NULL,
+    row_number INTEGER NOT NULL,
+    sku VARCHAR(255),
+    column_name VARCHAR(100),
+    code VARCHAR(50) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_import_job_errors_job_id ON product_import_job_errors(job_id);
+CREATE INDEX IF NOT EXISTS idx_product_import_job_errors_tenant_id ON product_import_job_errors(tenant_id);
@@ -0,0 +1,25 @@
+-- Migration: Vendor product review
+-- Vendor products wait in a review queue (status PENDING) under an approval-service request.
+-- Reviewers can send them back with per-field change requests (status CHANGES_REQUESTED).
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS approval_request_id VARCHAR(255);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS product_change_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    vendor_id VARCHAR(255) NOT NULL,
+    field VARCHAR(100) NOT NULL,
+    comment TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN',
+    requested_by_id VARCHAR(255),
+    requested_by_name VARCHAR(255),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_change_requests_product ON product_change_requests(tenant_id, product_id);
+CREATE INDEX IF NOT EXISTS idx_product_change_requests_vendor_id ON product_change_requests(vendor_id);
+CREATE INDEX IF NOT EXISTS idx_products_review_queue ON products(tenant_id, status, submitted_at);
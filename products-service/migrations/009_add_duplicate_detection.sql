@@ -0,0 +1,34 @@
+-- Migration: Duplicate product detection
+-- Products are compared on normalized SKU, GTIN and normalized title + brand when created or imported.
+-- Each tenant's policy decides whether probable duplicates are allowed with a warning or rejected.
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS gtin VARCHAR(50);
+CREATE INDEX IF NOT EXISTS idx_products_gtin ON products(gtin);
+
+CREATE TABLE IF NOT EXISTS product_duplicate_policies (
+    tenant_id VARCHAR(255) PRIMARY KEY,
+    mode VARCHAR(20) NOT NULL DEFAULT 'WARN',
+    match_sku BOOLEAN NOT NULL DEFAULT TRUE,
+    match_gtin BOOLEAN NOT NULL DEFAULT TRUE,
+    match_title_brand BOOLEAN NOT NULL DEFAULT TRUE,
+    cross_vendor BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Resolved duplicate clusters: MERGE archives the duplicates, DISMISS hides the cluster from suggestions
+CREATE TABLE IF NOT EXISTS product_duplicate_resolutions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    match_type VARCHAR(20) NOT NULL,
+    match_key TEXT NOT NULL,
+    product_id UUID NOT NULL,
+    primary_product_id UUID,
+    action VARCHAR(20) NOT NULL,
+    resolved_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_duplicate_resolutions_key ON product_duplicate_resolutions(tenant_id, match_type, match_key);
+CREATE INDEX IF NOT EXISTS idx_product_duplicate_resolutions_product_id ON product_duplicate_resolutions(product_id);
@@ -0,0 +1,11 @@
+-- Migration: Product shipping restrictions
+-- shipping-service checks these when rating carts and creating shipments, blocking or
+-- restricting carriers and services for dangerous goods, batteries and age-restricted items.
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS hazmat_class VARCHAR(10);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS contains_battery BOOLEAN;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS age_restricted BOOLEAN;
+
+COMMENT ON COLUMN products.hazmat_class IS 'UN dangerous goods class (1-9, optional division such as 2.1). NULL when not hazardous.';
+COMMENT ON COLUMN products.contains_battery IS 'Product contains or is packed with lithium batteries.';
+COMMENT ON COLUMN products.age_restricted IS 'Delivery requires age verification.';
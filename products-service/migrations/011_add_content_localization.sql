@@ -0,0 +1,11 @@
+-- Migration: Product and category content localization
+-- Translations are stored per entity as a JSON object keyed by locale, e.g.
+-- {"fr-CA": {"name": "...", "description": "...", "attributes": {"Color": {"name": "Couleur", "value": "Rouge"}}}}.
+-- Storefront responses resolve each field along the shopper's locale chain (fr-CA, fr, ...) and
+-- fall back to the original content.
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS localizations JSONB;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS localizations JSONB;
+
+COMMENT ON COLUMN products.localizations IS 'Translated name, description and attributes keyed by locale.';
+COMMENT ON COLUMN categories.localizations IS 'Translated name and description keyed by locale.';
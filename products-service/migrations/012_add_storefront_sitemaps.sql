@@ -0,0 +1,27 @@
+-- Migration: Storefront sitemaps
+-- One row per storefront URL (active products, published categories and content-service pages).
+-- Product entries follow product events and category entries follow category changes; a periodic
+-- full rebuild refreshes content pages and corrects any drift.
+
+CREATE TABLE IF NOT EXISTS storefront_sitemap_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    path TEXT NOT NULL,
+    last_modified TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sitemap_entries_entity ON storefront_sitemap_entries(tenant_id, entity_type, entity_id);
+
+CREATE TABLE IF NOT EXISTS storefront_sitemaps (
+    tenant_id VARCHAR(255) PRIMARY KEY,
+    last_rebuilt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    pages_synced_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_storefront_sitemaps_last_rebuilt_at ON storefront_sitemaps(last_rebuilt_at);
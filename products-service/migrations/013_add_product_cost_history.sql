@@ -0,0 +1,18 @@
+-- Migration: Product cost history
+-- One row per change to a product's or variant's cost price, whether edited by staff or taken
+-- from the landed cost of an inventory-service purchase order receipt.
+
+CREATE TABLE IF NOT EXISTS product_cost_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    product_id UUID NOT NULL,
+    variant_id UUID,
+    previous_cost_price TEXT,
+    cost_price TEXT,
+    source VARCHAR(20) NOT NULL,
+    reference TEXT,
+    changed_by TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_cost_history_product ON product_cost_history(tenant_id, product_id, created_at);
@@ -0,0 +1,31 @@
+-- Migration: Product search synonyms and settings
+-- Tenant-managed synonym groups expand search terms ("tshirt" also finding "tee"), and
+-- per-tenant settings control fuzzy matching and stop words. Fuzzy matching uses
+-- levenshtein from fuzzystrmatch.
+
+CREATE EXTENSION IF NOT EXISTS fuzzystrmatch;
+
+CREATE TABLE IF NOT EXISTS product_search_synonyms (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    terms JSONB NOT NULL,
+    one_way BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_search_synonyms_tenant_id ON product_search_synonyms(tenant_id);
+
+CREATE TABLE IF NOT EXISTS product_search_settings (
+    tenant_id TEXT PRIMARY KEY,
+    fuzzy_enabled BOOLEAN NOT NULL,
+    fuzzy_distance BIGINT NOT NULL,
+    fuzzy_min_term_length BIGINT NOT NULL,
+    stop_words_enabled BOOLEAN NOT NULL,
+    stop_words JSONB,
+    updated_by TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1,39 @@
+-- Migration: Storefront search merchandising
+-- Boost/bury rules reorder storefront search results by product attribute, and pins put
+-- chosen products first for a search query. Both can be scheduled with starts_at/ends_at.
+
+CREATE TABLE IF NOT EXISTS product_search_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    name TEXT NOT NULL,
+    action VARCHAR(10) NOT NULL,
+    field VARCHAR(30) NOT NULL,
+    value TEXT,
+    weight BIGINT NOT NULL,
+    queries JSONB,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_search_rules_tenant_id ON product_search_rules(tenant_id);
+
+CREATE TABLE IF NOT EXISTS product_search_pins (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    query TEXT NOT NULL,
+    product_id UUID NOT NULL,
+    position BIGINT NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_search_pins_query_product ON product_search_pins(tenant_id, query, product_id);
@@ -0,0 +1,26 @@
+-- Migration: Product bundles
+-- A bundle product (e.g. camera + lens kit) is sold as one item and holds no stock of its own;
+-- stock checks, deductions and restores resolve it to its components.
+
+CREATE TABLE IF NOT EXISTS product_bundles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    product_id UUID NOT NULL,
+    pricing VARCHAR(20) NOT NULL DEFAULT 'FIXED',
+    discount_percent NUMERIC NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_bundles_product ON product_bundles(tenant_id, product_id);
+
+CREATE TABLE IF NOT EXISTS bundle_components (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bundle_id UUID NOT NULL REFERENCES product_bundles(id) ON DELETE CASCADE,
+    component_product_id UUID NOT NULL,
+    quantity BIGINT NOT NULL,
+    position BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_bundle_components_bundle_id ON bundle_components(bundle_id);
+CREATE INDEX IF NOT EXISTS idx_bundle_components_component_product_id ON bundle_components(component_product_id);
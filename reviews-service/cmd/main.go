@@ -3,7 +3,10 @@ package main
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,13 +15,16 @@ import (
 	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 	"reviews-service/internal/clients"
 	"reviews-service/internal/config"
+	"reviews-service/internal/database"
 	"reviews-service/internal/events"
 	"reviews-service/internal/handlers"
+	"reviews-service/internal/importer"
 	"reviews-service/internal/middleware"
-	"reviews-service/internal/models"
 	"reviews-service/internal/repository"
+	"reviews-service/internal/sentiment"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"github.com/Tesseract-Nexus/go-shared/rbac"
@@ -53,28 +59,25 @@ func main() {
 	// Initialize configuration
 	cfg := config.Load()
 
-	// Initialize database
-	db, err := config.InitDB(cfg)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return config.InitDB(cfg) })
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	// Auto-migrate models (runs on every startup, creates tables if not exist)
-	log.Println("Starting database migration...")
-
-	// Migrate Review model
-	if err := db.AutoMigrate(&models.Review{}); err != nil {
-		log.Fatal("Failed to migrate Review model:", err)
+	// "reviews-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
 	}
-	log.Println("✓ Review model migrated")
 
-	// Migrate ReviewReaction model (for per-user unique reactions)
-	if err := db.AutoMigrate(&models.ReviewReaction{}); err != nil {
-		log.Fatal("Failed to migrate ReviewReaction model:", err)
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
 	}
-	log.Println("✓ ReviewReaction model migrated (unique reactions per user)")
-
-	log.Println("✓ Database migration completed")
 
 	// Initialize Redis client (graceful degradation if unavailable)
 	var redisClient *redis.Client
@@ -118,12 +121,27 @@ func main() {
 	tenantClient := clients.NewTenantClient()
 	log.Println("✓ Notification client initialized")
 
+	// Marketing client issues loyalty points and discounts for incentivized reviews
+	marketingClient := clients.NewMarketingClient()
+
 	// Initialize repository with Redis caching
 	reviewsRepo := repository.NewReviewsRepository(db, redisClient)
 
+	// Initialize the sentiment provider (tags reviews with sentiment and topics)
+	sentimentProvider, err := sentiment.NewProvider(cfg.SentimentProvider, cfg.MLServiceURL)
+	if err != nil {
+		log.Fatal("Failed to initialize sentiment provider:", err)
+	}
+	if sentimentProvider != nil {
+		log.Printf("✓ Sentiment provider initialized (%s)", sentimentProvider.Name())
+	} else {
+		log.Println("⚠ Sentiment analysis disabled")
+	}
+
 	// Initialize handlers with notification client and events publisher
-	reviewsHandler := handlers.NewReviewsHandler(reviewsRepo, notificationClient, tenantClient, eventsPublisher)
+	reviewsHandler := handlers.NewReviewsHandler(reviewsRepo, notificationClient, tenantClient, eventsPublisher, sentimentProvider, marketingClient)
 	documentHandler := handlers.NewDocumentHandler(cfg.DocumentServiceURL, cfg.ProductID, reviewsRepo)
+	importHandler := handlers.NewImportHandler(reviewsRepo, reviewsHandler, importer.NewMediaSideloader(cfg.DocumentServiceURL, cfg.ProductID))
 
 	// Initialize Gin router
 	if cfg.Environment == "production" {
@@ -137,9 +155,11 @@ func main() {
 	// Security headers middleware
 	router.Use(gosharedmw.SecurityHeaders())
 
-	// Rate limiting middleware (uses Redis for distributed rate limiting)
+	// Rate limiting middleware (uses Redis for distributed rate limiting; profiles are managed in staff-service)
 	if redisClient != nil {
-		router.Use(gosharedmw.RedisRateLimitMiddlewareWithProfile(redisClient, "standard"))
+		rateLimiter := middleware.NewProfileRateLimiter(redisClient)
+		go rateLimiter.Start(context.Background())
+		router.Use(rateLimiter.Middleware())
 		log.Println("✓ Redis-based rate limiting enabled")
 	} else {
 		router.Use(gosharedmw.RateLimit())
@@ -190,6 +210,9 @@ func main() {
 			reviews.POST("/bulk/status", rbacMiddleware.RequirePermission(rbac.PermissionReviewsModerate), reviewsHandler.BulkUpdateStatus)
 			reviews.POST("/bulk/moderate", rbacMiddleware.RequirePermission(rbac.PermissionReviewsModerate), reviewsHandler.BulkModerate)
 
+			// Import from other platforms (Shopify, Amazon, Judge.me exports)
+			reviews.POST("/import", gosharedmw.VendorScopeFilter(), rbacMiddleware.RequirePermission(rbac.PermissionReviewsModerate), importHandler.ImportReviews)
+
 			// Media operations (JSON-based)
 			reviews.POST("/:id/media", rbacMiddleware.RequirePermission(rbac.PermissionReviewsModerate), reviewsHandler.AddMedia)
 			reviews.DELETE("/:id/media/:mediaId", rbacMiddleware.RequirePermission(rbac.PermissionReviewsModerate), reviewsHandler.DeleteMedia)
@@ -202,14 +225,27 @@ func main() {
 			// Reactions and comments (responses)
 			reviews.POST("/:id/reactions", rbacMiddleware.RequirePermission(rbac.PermissionReviewsRespond), reviewsHandler.AddReaction)
 			reviews.DELETE("/:id/reactions/:reactionId", rbacMiddleware.RequirePermission(rbac.PermissionReviewsRespond), reviewsHandler.RemoveReaction)
-			reviews.POST("/:id/comments", rbacMiddleware.RequirePermission(rbac.PermissionReviewsRespond), reviewsHandler.AddComment)
-			reviews.PUT("/:id/comments/:commentId", rbacMiddleware.RequirePermission(rbac.PermissionReviewsRespond), reviewsHandler.UpdateComment)
+			// Vendor-scoped staff can only respond to reviews of their own products
+			reviews.POST("/:id/comments", gosharedmw.VendorScopeFilter(), rbacMiddleware.RequirePermission(rbac.PermissionReviewsRespond), reviewsHandler.AddComment)
+			reviews.PUT("/:id/comments/:commentId", gosharedmw.VendorScopeFilter(), rbacMiddleware.RequirePermission(rbac.PermissionReviewsRespond), reviewsHandler.UpdateComment)
 			reviews.DELETE("/:id/comments/:commentId", rbacMiddleware.RequirePermission(rbac.PermissionReviewsModerate), reviewsHandler.DeleteComment)
 
+			// Response templates (vendor staff manage their own vendor's)
+			reviews.GET("/response-templates", gosharedmw.VendorScopeFilter(), rbacMiddleware.RequirePermission(rbac.PermissionReviewsRespond), reviewsHandler.ListResponseTemplates)
+			reviews.POST("/response-templates", gosharedmw.VendorScopeFilter(), rbacMiddleware.RequirePermission(rbac.PermissionReviewsRespond), reviewsHandler.CreateResponseTemplate)
+			reviews.GET("/response-templates/:templateId", gosharedmw.VendorScopeFilter(), rbacMiddleware.RequirePermission(rbac.PermissionReviewsRespond), reviewsHandler.GetResponseTemplate)
+			reviews.PUT("/response-templates/:templateId", gosharedmw.VendorScopeFilter(), rbacMiddleware.RequirePermission(rbac.PermissionReviewsRespond), reviewsHandler.UpdateResponseTemplate)
+			reviews.DELETE("/response-templates/:templateId", gosharedmw.VendorScopeFilter(), rbacMiddleware.RequirePermission(rbac.PermissionReviewsRespond), reviewsHandler.DeleteResponseTemplate)
+
 			// Analytics and reporting
 			reviews.GET("/analytics", rbacMiddleware.RequirePermission(rbac.PermissionReviewsRead), reviewsHandler.GetAnalytics)
+			reviews.GET("/analytics/vendor", gosharedmw.VendorScopeFilter(), rbacMiddleware.RequirePermission(rbac.PermissionReviewsRead), reviewsHandler.GetVendorRatingTrends)
+			reviews.GET("/analytics/incentives", gosharedmw.VendorScopeFilter(), rbacMiddleware.RequirePermission(rbac.PermissionReviewsRead), reviewsHandler.GetIncentiveReport)
 			reviews.GET("/stats", rbacMiddleware.RequirePermission(rbac.PermissionReviewsRead), reviewsHandler.GetStats)
+			// AllowInternal: products-service includes review stats in catalog exports
+			reviews.POST("/stats/products", rbacMiddleware.RequirePermissionAllowInternal(rbac.PermissionReviewsRead), reviewsHandler.GetProductReviewStats)
 			reviews.POST("/export", rbacMiddleware.RequirePermission(rbac.PermissionReviewsRead), reviewsHandler.ExportReviews)
+			reviews.GET("/mentions", rbacMiddleware.RequirePermission(rbac.PermissionReviewsRead), reviewsHandler.GetReviewMentions)
 
 			// Spam detection and ML features
 			reviews.POST("/:id/report", rbacMiddleware.RequirePermission(rbac.PermissionReviewsModerate), reviewsHandler.ReportReview)
@@ -227,6 +263,7 @@ func main() {
 	storefrontReviews.Use(middleware.TenantMiddleware())
 	{
 		storefrontReviews.GET("", reviewsHandler.StorefrontGetReviews)
+		storefrontReviews.GET("/mentions", reviewsHandler.StorefrontGetReviewMentions)
 		storefrontReviews.POST("", reviewsHandler.StorefrontCreateReview)
 		storefrontReviews.POST("/media/upload", documentHandler.UploadReviewMedia)
 		storefrontReviews.POST("/:id/reactions", reviewsHandler.AddReaction)
@@ -241,8 +278,30 @@ func main() {
 		port = "8084"
 	}
 
-	log.Printf("Reviews service starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+	go func() {
+		log.Printf("Reviews service starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down reviews-service...")
+
+	// Stop accepting connections and let in-flight requests finish
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
 	}
+
+	log.Println("Reviews service stopped")
 }
@@ -3,7 +3,7 @@ module reviews-service
 go 1.25
 
 require (
-	github.com/Tesseract-Nexus/go-shared v0.2.1
+	github.com/Tesseract-Nexus/go-shared v0.2.9-0.20260127060132-154fd449be13
 	github.com/gin-contrib/cors v1.4.0
 	github.com/gin-gonic/gin v1.11.0
 	github.com/google/uuid v1.6.0
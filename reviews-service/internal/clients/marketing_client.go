@@ -0,0 +1,100 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// MarketingClient handles HTTP communication with marketing-service for review incentives
+type MarketingClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// ReviewIncentiveRequest asks marketing-service to reward a customer for an approved review
+type ReviewIncentiveRequest struct {
+	CustomerID string  `json:"customerId"`
+	ReviewID   string  `json:"reviewId"`
+	Type       string  `json:"type"`            // LOYALTY_POINTS or DISCOUNT
+	Value      float64 `json:"value,omitempty"` // Points, or percent off; points default to the loyalty program's review bonus
+}
+
+// ReviewIncentiveResult is the reward marketing-service issued
+type ReviewIncentiveResult struct {
+	Type          string  `json:"type"`
+	Points        int     `json:"points,omitempty"`
+	TransactionID *string `json:"transactionId,omitempty"`
+	CouponCode    string  `json:"couponCode,omitempty"`
+	AlreadyIssued bool    `json:"alreadyIssued"`
+}
+
+// Reference returns the loyalty transaction ID or coupon code of the reward
+func (r *ReviewIncentiveResult) Reference() string {
+	if r.CouponCode != "" {
+		return r.CouponCode
+	}
+	if r.TransactionID != nil {
+		return *r.TransactionID
+	}
+	return ""
+}
+
+// NewMarketingClient creates a new marketing client
+func NewMarketingClient() *MarketingClient {
+	baseURL := os.Getenv("MARKETING_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://marketing-service.marketplace.svc.cluster.local:8080"
+	}
+
+	return &MarketingClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// IssueReviewIncentive issues the reward for an approved incentivized review. marketing-service
+// issues one reward per review, so retrying a failed call is safe.
+func (c *MarketingClient) IssueReviewIncentive(ctx context.Context, tenantID string, req *ReviewIncentiveRequest) (*ReviewIncentiveResult, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal incentive request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/loyalty/review-incentives", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Tenant-ID", tenantID)
+	httpReq.Header.Set("X-Internal-Service", "reviews-service")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to communicate with marketing service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&errResp) == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("marketing service returned status %d: %s", resp.StatusCode, errResp.Error)
+		}
+		return nil, fmt.Errorf("marketing service returned status %d", resp.StatusCode)
+	}
+
+	var result ReviewIncentiveResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode incentive response: %w", err)
+	}
+	return &result, nil
+}
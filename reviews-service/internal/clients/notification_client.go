@@ -29,8 +29,10 @@ type ReviewNotification struct {
 	Title          string
 	Comment        string
 	IsVerified     bool
-	Status         string // PENDING, APPROVED, REJECTED
+	Status         string // PENDING, APPROVED, REJECTED, RESPONDED
 	RejectionReason string
+	ResponseText   string // Store or vendor reply, for RESPONDED notifications
+	ResponderName  string
 	ReviewURL      string
 	ProductURL     string
 	AdminURL       string
@@ -177,6 +179,38 @@ func (c *NotificationClient) SendReviewRejectedNotification(ctx context.Context,
 	return c.sendNotification(ctx, notification.TenantID, req)
 }
 
+// SendReviewResponseNotification tells the customer the store or vendor replied to their review
+func (c *NotificationClient) SendReviewResponseNotification(ctx context.Context, notification *ReviewNotification) error {
+	if notification.CustomerEmail == "" {
+		log.Printf("[REVIEW] No customer email for review %s, skipping response notification", notification.ReviewID)
+		return nil
+	}
+
+	req := &notificationRequest{
+		To:       notification.CustomerEmail,
+		Subject:  fmt.Sprintf("You have a reply to your review of %s", notification.ProductName),
+		Template: "review_customer",
+		Variables: map[string]string{
+			"reviewId":      notification.ReviewID,
+			"productId":     notification.ProductID,
+			"productName":   notification.ProductName,
+			"customerName":  notification.CustomerName,
+			"customerEmail": notification.CustomerEmail,
+			"rating":        fmt.Sprintf("%d", notification.Rating),
+			"reviewTitle":   notification.Title,
+			"reviewComment": notification.Comment,
+			"isVerified":    fmt.Sprintf("%t", notification.IsVerified),
+			"reviewStatus":  "RESPONDED",
+			"responseText":  notification.ResponseText,
+			"responderName": notification.ResponderName,
+			"productUrl":    notification.ProductURL,
+			"tenantId":      notification.TenantID,
+		},
+	}
+
+	return c.sendNotification(ctx, notification.TenantID, req)
+}
+
 // sendNotification sends a notification request to notification-service
 func (c *NotificationClient) sendNotification(ctx context.Context, tenantID string, req *notificationRequest) error {
 	body, err := json.Marshal(req)
@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"reviews-service/internal/importer"
+	"reviews-service/internal/models"
+	"reviews-service/internal/repository"
+	"reviews-service/internal/sentiment"
+)
+
+const (
+	// maxImportFileSize caps an uploaded review export
+	maxImportFileSize = 50 << 20
+	// maxImportRows caps the reviews imported per request; larger exports are split
+	maxImportRows = 5000
+	// maxMediaPerImportedReview caps the media sideloaded for one review
+	maxMediaPerImportedReview = 10
+)
+
+// ImportHandler imports reviews from other platforms' exports
+type ImportHandler struct {
+	repo       *repository.ReviewsRepository
+	reviews    *ReviewsHandler
+	sideloader *importer.MediaSideloader
+}
+
+// NewImportHandler creates a review import handler. reviews is used to run imported reviews
+// through the sentiment pipeline.
+func NewImportHandler(repo *repository.ReviewsRepository, reviews *ReviewsHandler, sideloader *importer.MediaSideloader) *ImportHandler {
+	return &ImportHandler{
+		repo:       repo,
+		reviews:    reviews,
+		sideloader: sideloader,
+	}
+}
+
+// ImportReviews imports reviews from a Shopify, Amazon or Judge.me export
+// @Summary Import reviews from another platform
+// @Description Import a CSV or JSON review export. Rows already imported (same platform and external ID) are skipped,
+// @Description original review dates are kept and review photos are copied into document storage.
+// @Tags reviews
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or JSON export"
+// @Param platform formData string true "Source platform (shopify, amazon, judgeme, custom)"
+// @Param mapping formData string false "JSON object of export column to field, overriding the platform defaults"
+// @Param productMapping formData string false "JSON object of the export's product handle/ASIN to product ID"
+// @Param status formData string false "Status of imported reviews (APPROVED or PENDING)" default(APPROVED)
+// @Param vendorId formData string false "Vendor the reviewed products belong to (tenant-level users only)"
+// @Param sideloadMedia formData bool false "Copy review media into document storage" default(true)
+// @Param validateOnly formData bool false "Check the file without importing" default(false)
+// @Success 200 {object} models.ReviewImportResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/import [post]
+func (h *ImportHandler) ImportReviews(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+	userID := c.GetString("userId")
+
+	platform := importer.Platform(strings.ToLower(c.PostForm("platform")))
+	if !importer.ValidPlatform(platform) {
+		respondImportError(c, "INVALID_PLATFORM", "platform must be one of shopify, amazon, judgeme or custom", "platform")
+		return
+	}
+
+	var customMapping map[string]string
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &customMapping); err != nil {
+			respondImportError(c, "INVALID_MAPPING", "mapping must be a JSON object of column to field", "mapping")
+			return
+		}
+	}
+	mapping, err := importer.NewMapping(platform, customMapping)
+	if err != nil {
+		respondImportError(c, "INVALID_MAPPING", err.Error(), "mapping")
+		return
+	}
+
+	productMapping := make(map[string]string)
+	if raw := c.PostForm("productMapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &productMapping); err != nil {
+			respondImportError(c, "INVALID_MAPPING", "productMapping must be a JSON object of product handle to product ID", "productMapping")
+			return
+		}
+	}
+
+	status := models.ReviewStatus(strings.ToUpper(c.DefaultPostForm("status", string(models.ReviewStatusApproved))))
+	if status != models.ReviewStatusApproved && status != models.ReviewStatusPending {
+		respondImportError(c, "INVALID_STATUS", "status must be APPROVED or PENDING", "status")
+		return
+	}
+
+	// Vendor staff can only import reviews for their own products
+	vendorID := c.PostForm("vendorId")
+	if scoped := gosharedmw.GetVendorScopeFilter(c); scoped != "" {
+		vendorID = scoped
+	}
+
+	sideloadMedia := c.DefaultPostForm("sideloadMedia", "true") == "true"
+	validateOnly := c.DefaultPostForm("validateOnly", "false") == "true"
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		respondImportError(c, "FILE_REQUIRED", "Please upload a CSV or JSON export", "file")
+		return
+	}
+	defer file.Close()
+	if header.Size > maxImportFileSize {
+		respondImportError(c, "FILE_TOO_LARGE", fmt.Sprintf("Export files are limited to %dMB", maxImportFileSize>>20), "file")
+		return
+	}
+
+	var rows []importer.Row
+	switch strings.ToLower(filepath.Ext(header.Filename)) {
+	case ".csv":
+		rows, err = importer.ParseCSV(file)
+	case ".json":
+		rows, err = importer.ParseJSON(file)
+	default:
+		respondImportError(c, "INVALID_FORMAT", "Only CSV and JSON exports are supported", "file")
+		return
+	}
+	if err != nil {
+		respondImportError(c, "PARSE_ERROR", err.Error(), "file")
+		return
+	}
+	if len(rows) == 0 {
+		respondImportError(c, "EMPTY_FILE", "The export contains no reviews", "file")
+		return
+	}
+	if len(rows) > maxImportRows {
+		respondImportError(c, "TOO_MANY_ROWS", fmt.Sprintf("Imports are limited to %d reviews per file", maxImportRows), "file")
+		return
+	}
+
+	result := models.ReviewImportResult{
+		Platform:     string(platform),
+		TotalRows:    len(rows),
+		ValidateOnly: validateOnly,
+	}
+
+	// Map rows, dropping duplicates within the file
+	records := make([]*importer.Record, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		record, fieldErr := mapping.Map(row)
+		if fieldErr != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, models.ReviewImportRowError{
+				Row:     row.Number,
+				Field:   fieldErr.Field,
+				Code:    fieldErr.Code,
+				Message: fieldErr.Message,
+			})
+			continue
+		}
+		if seen[record.ExternalID] {
+			result.DuplicateCount++
+			continue
+		}
+		seen[record.ExternalID] = true
+		records = append(records, record)
+	}
+
+	// Skip reviews imported by an earlier run
+	externalIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		externalIDs = append(externalIDs, record.ExternalID)
+	}
+	existing, err := h.repo.GetExistingExternalIDs(tenantID, string(platform), externalIDs)
+	if err != nil {
+		log.Printf("[REVIEWS] Failed to check imported reviews for tenant %s: %v", tenantID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "IMPORT_FAILED",
+				Message: "Failed to check for previously imported reviews",
+			},
+		})
+		return
+	}
+
+	imported := make([]*models.Review, 0, len(records))
+	for _, record := range records {
+		if existing[record.ExternalID] {
+			result.DuplicateCount++
+			continue
+		}
+		if validateOnly {
+			result.ImportedCount++
+			continue
+		}
+
+		review := h.buildImportedReview(c.Request.Context(), tenantID, userID, vendorID, platform, status, record, productMapping, sideloadMedia, &result)
+		created, err := h.repo.ImportReview(tenantID, review)
+		if err != nil {
+			log.Printf("[REVIEWS] Failed to import row %d for tenant %s: %v", record.Row, tenantID, err)
+			result.FailedCount++
+			result.Errors = append(result.Errors, models.ReviewImportRowError{
+				Row:     record.Row,
+				Code:    "CREATE_FAILED",
+				Message: "Failed to save review",
+			})
+			continue
+		}
+		if !created {
+			// Imported concurrently by another request
+			result.DuplicateCount++
+			continue
+		}
+		result.ImportedCount++
+		result.ImportedIDs = append(result.ImportedIDs, review.ID.String())
+		imported = append(imported, review)
+	}
+
+	if len(imported) > 0 {
+		h.repo.InvalidateTenantCaches(tenantID)
+		h.analyzeImportedAsync(tenantID, imported)
+		log.Printf("[REVIEWS] Imported %d %s reviews for tenant %s (%d duplicates, %d failed)",
+			result.ImportedCount, platform, tenantID, result.DuplicateCount, result.FailedCount)
+	}
+
+	c.JSON(http.StatusOK, models.ReviewImportResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// buildImportedReview converts an export record into a review, keeping its original dates,
+// the store's reply and its media
+func (h *ImportHandler) buildImportedReview(ctx context.Context, tenantID, userID, vendorID string, platform importer.Platform, status models.ReviewStatus, record *importer.Record, productMapping map[string]string, sideloadMedia bool, result *models.ReviewImportResult) *models.Review {
+	targetID := record.Product
+	if mapped, ok := productMapping[record.Product]; ok && mapped != "" {
+		targetID = mapped
+	}
+
+	source := string(platform)
+	externalID := record.ExternalID
+	review := &models.Review{
+		ID:               uuid.New(),
+		VendorID:         vendorID,
+		ApplicationID:    string(models.ReviewTypeProduct),
+		TargetID:         targetID,
+		TargetType:       string(models.ReviewTypeProduct),
+		UserID:           importedReviewerID(platform, record),
+		Content:          record.Content,
+		Type:             models.ReviewTypeProduct,
+		Status:           status,
+		Visibility:       models.VisibilityPublic,
+		VerifiedPurchase: record.VerifiedPurchase,
+		ExternalSource:   &source,
+		ExternalID:       &externalID,
+		CreatedBy:        &userID,
+		UpdatedBy:        &userID,
+	}
+	if record.Title != "" && record.Title != record.Content {
+		review.Title = &record.Title
+	}
+	if record.ReviewerName != "" {
+		review.UserName = &record.ReviewerName
+	}
+	if record.ReviewerEmail != "" {
+		review.ContactEmail = &record.ReviewerEmail
+	}
+	if record.Language != "" {
+		review.Language = &record.Language
+	}
+
+	// Unpublished or spam reviews on the source platform aren't published here either
+	switch record.State {
+	case "unpublished", "hidden", "pending", "not_yet", "not-yet":
+		review.Status = models.ReviewStatusPending
+	case "spam", "rejected":
+		review.Status = models.ReviewStatusRejected
+	}
+
+	// Keep the review's original age so migrating stores don't look brand new
+	if record.ReviewDate != nil {
+		review.CreatedAt = *record.ReviewDate
+	}
+	if review.Status == models.ReviewStatusApproved {
+		publishedAt := review.CreatedAt
+		if publishedAt.IsZero() {
+			publishedAt = time.Now()
+		}
+		review.PublishedAt = &publishedAt
+	}
+
+	ratings := models.JSON{
+		"overall": map[string]interface{}{
+			"score":    record.Rating,
+			"maxScore": 5,
+		},
+	}
+	review.Ratings = &ratings
+
+	metadata := models.JSON{
+		"importSource":     source,
+		"importExternalId": externalID,
+		"importedAt":       time.Now().Format(time.RFC3339),
+	}
+	if record.Location != "" {
+		metadata["reviewerLocation"] = record.Location
+	}
+	review.Metadata = &metadata
+
+	// The store's reply on the source platform becomes a public response
+	if record.Reply != "" {
+		repliedAt := time.Now()
+		if record.ReplyDate != nil {
+			repliedAt = *record.ReplyDate
+		}
+		commentID := uuid.New().String()
+		reply := map[string]interface{}{
+			"id":         commentID,
+			"userId":     userID,
+			"userName":   "Store",
+			"content":    record.Reply,
+			"isInternal": false,
+			"createdAt":  repliedAt.Format(time.RFC3339),
+		}
+		if vendorID != "" {
+			reply["vendorId"] = vendorID
+		}
+		comments := models.JSON{commentID: reply}
+		review.Comments = &comments
+	}
+
+	if len(record.MediaURLs) > 0 {
+		media := make(models.JSON)
+		urls := record.MediaURLs
+		if len(urls) > maxMediaPerImportedReview {
+			urls = urls[:maxMediaPerImportedReview]
+		}
+		for _, sourceURL := range urls {
+			item := importer.ExternalMedia(uuid.New().String(), sourceURL)
+			if sideloadMedia && h.sideloader != nil {
+				if stored, err := h.sideloader.Sideload(ctx, tenantID, review.ID.String(), sourceURL); err == nil {
+					item = stored
+					result.MediaImported++
+				} else {
+					result.MediaFailed++
+					result.Warnings = append(result.Warnings, models.ReviewImportRowError{
+						Row:     record.Row,
+						Field:   importer.FieldMediaURLs,
+						Code:    "MEDIA_SIDELOAD_FAILED",
+						Message: fmt.Sprintf("Kept link to %s: %v", sourceURL, err),
+					})
+				}
+			}
+			media[item.ID] = map[string]interface{}{
+				"id":         item.ID,
+				"type":       item.Type,
+				"url":        item.URL,
+				"fileSize":   item.FileSize,
+				"width":      item.Width,
+				"height":     item.Height,
+				"uploadedAt": item.UploadedAt.Format(time.RFC3339),
+			}
+		}
+		review.Media = &media
+	}
+
+	return review
+}
+
+// importedReviewerID identifies an imported reviewer. Reviewers don't have accounts here, so
+// the ID is derived from the source platform and their email or name.
+func importedReviewerID(platform importer.Platform, record *importer.Record) string {
+	reviewer := record.ReviewerEmail
+	if reviewer == "" {
+		reviewer = strings.ToLower(record.ReviewerName)
+	}
+	if reviewer == "" {
+		return fmt.Sprintf("import-%s-anonymous", platform)
+	}
+	return fmt.Sprintf("import-%s-%s", platform, uuid.NewSHA1(uuid.NameSpaceURL, []byte(reviewer)))
+}
+
+// analyzeImportedAsync runs imported reviews through the sentiment pipeline one at a time
+// in the background, so a large import doesn't flood the ML service
+func (h *ImportHandler) analyzeImportedAsync(tenantID string, reviews []*models.Review) {
+	if h.reviews == nil || h.reviews.sentimentProvider == nil {
+		return
+	}
+	go func() {
+		for _, review := range reviews {
+			ctx, cancel := context.WithTimeout(context.Background(), sentimentTimeout)
+			if _, err := h.reviews.analyzeReview(ctx, tenantID, review); err != nil && !errors.Is(err, sentiment.ErrUnsupportedLanguage) {
+				log.Printf("[REVIEWS] Failed to analyze sentiment for imported review %s: %v", review.ID, err)
+			}
+			cancel()
+		}
+	}()
+}
+
+func respondImportError(c *gin.Context, code, message, field string) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Success: false,
+		Error: models.Error{
+			Code:    code,
+			Message: message,
+			Field:   field,
+		},
+	})
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"reviews-service/internal/clients"
+	"reviews-service/internal/models"
+)
+
+// incentiveTimeout bounds issuing the incentives of one approval
+const incentiveTimeout = 30 * time.Second
+
+// updateIncentive applies the incentive fields of an update request. Incentives that were
+// already issued can't be changed. Writes the error response and returns false on failure.
+func (h *ReviewsHandler) updateIncentive(c *gin.Context, tenantID string, reviewID uuid.UUID, req *models.UpdateReviewRequest) bool {
+	review, err := h.repo.GetReviewByID(tenantID, reviewID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Review not found",
+			},
+		})
+		return false
+	}
+	if review.IncentiveStatus == models.IncentiveStatusIssued {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INCENTIVE_ALREADY_ISSUED",
+				Message: "The incentive for this review was already issued and can't be changed",
+			},
+		})
+		return false
+	}
+
+	incentivized := review.Incentivized || req.IncentiveType != nil
+	if req.Incentivized != nil {
+		incentivized = *req.Incentivized
+	}
+	incentiveType := review.IncentiveType
+	if req.IncentiveType != nil {
+		incentiveType = req.IncentiveType
+	}
+	value := review.IncentiveValue
+	if req.IncentiveValue != nil {
+		value = req.IncentiveValue
+	}
+	review.SetIncentive(incentivized, incentiveType, value)
+
+	if err := h.repo.UpdateReviewIncentive(tenantID, review); err != nil {
+		log.Printf("Failed to update incentive of review %s: %v", reviewID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_FAILED",
+				Message: "Failed to update review incentive",
+			},
+		})
+		return false
+	}
+
+	// Incentives added to an already approved review are issued right away
+	if review.Status == models.ReviewStatusApproved {
+		h.issueIncentivesAsync(tenantID, []uuid.UUID{reviewID})
+	}
+	return true
+}
+
+// issueIncentivesAsync issues the pending incentives of newly approved reviews through
+// marketing-service (non-blocking)
+func (h *ReviewsHandler) issueIncentivesAsync(tenantID string, reviewIDs []uuid.UUID) {
+	if h.marketingClient == nil || len(reviewIDs) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), incentiveTimeout)
+		defer cancel()
+
+		reviews, err := h.repo.GetUnissuedIncentiveReviews(tenantID, reviewIDs)
+		if err != nil {
+			log.Printf("[REVIEWS] Failed to load review incentives to issue: %v", err)
+			return
+		}
+		for i := range reviews {
+			h.issueIncentive(ctx, tenantID, &reviews[i])
+		}
+	}()
+}
+
+// issueIncentive issues a review's incentive and records whether it was issued
+func (h *ReviewsHandler) issueIncentive(ctx context.Context, tenantID string, review *models.Review) {
+	status := models.IncentiveStatusFailed
+	reference := ""
+	defer func() {
+		if err := h.repo.UpdateIncentiveStatus(tenantID, review.ID, status, reference); err != nil {
+			log.Printf("[REVIEWS] Failed to record incentive status for review %s: %v", review.ID, err)
+		}
+	}()
+
+	// Incentives go to the reviewer's customer account; anonymous storefront reviewers have none
+	if _, err := uuid.Parse(review.UserID); err != nil {
+		log.Printf("[REVIEWS] Review %s has no customer account to issue its incentive to", review.ID)
+		return
+	}
+
+	req := &clients.ReviewIncentiveRequest{
+		CustomerID: review.UserID,
+		ReviewID:   review.ID.String(),
+		Type:       string(*review.IncentiveType),
+	}
+	if review.IncentiveValue != nil {
+		req.Value = *review.IncentiveValue
+	}
+
+	result, err := h.marketingClient.IssueReviewIncentive(ctx, tenantID, req)
+	if err != nil {
+		log.Printf("[REVIEWS] Failed to issue incentive for review %s: %v", review.ID, err)
+		return
+	}
+	status = models.IncentiveStatusIssued
+	reference = result.Reference()
+}
+
+// GetIncentiveReport compares incentivized and organic review ratings
+// @Summary Incentivized vs organic ratings
+// @Description Ratings of approved incentivized and organic reviews, with incentive issuance status. Vendor-scoped users always see their own vendor.
+// @Tags reviews
+// @Produce json
+// @Param vendorId query string false "Vendor ID (tenant-level users only)"
+// @Param targetId query string false "Product or other review target"
+// @Param dateFrom query string false "Start date (RFC3339)"
+// @Param dateTo query string false "End date (RFC3339)"
+// @Success 200 {object} models.IncentiveReportResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/analytics/incentives [get]
+func (h *ReviewsHandler) GetIncentiveReport(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	vendorID := c.Query("vendorId")
+	if scoped := gosharedmw.GetVendorScopeFilter(c); scoped != "" {
+		vendorID = scoped
+	}
+
+	var dateFrom, dateTo *time.Time
+	if dateFromStr := c.Query("dateFrom"); dateFromStr != "" {
+		if parsed, err := time.Parse(time.RFC3339, dateFromStr); err == nil {
+			dateFrom = &parsed
+		}
+	}
+	if dateToStr := c.Query("dateTo"); dateToStr != "" {
+		if parsed, err := time.Parse(time.RFC3339, dateToStr); err == nil {
+			dateTo = &parsed
+		}
+	}
+
+	report, err := h.repo.GetIncentiveReport(tenantID, vendorID, c.Query("targetId"), dateFrom, dateTo)
+	if err != nil {
+		log.Printf("Failed to compute incentive report: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "ANALYTICS_FAILED",
+				Message: "Failed to generate incentive report",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.IncentiveReportResponse{
+		Success: true,
+		Data:    *report,
+	})
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"reviews-service/internal/models"
+)
+
+// templateVisibleTo reports whether staff scoped to vendorID (empty for tenant-level staff)
+// can use the template. Tenant-wide templates are visible to everyone.
+func templateVisibleTo(template *models.ReviewResponseTemplate, vendorID string) bool {
+	return vendorID == "" || template.VendorID == "" || template.VendorID == vendorID
+}
+
+// ListResponseTemplates lists the response templates the caller can use
+// @Summary List review response templates
+// @Tags reviews
+// @Produce json
+// @Param vendorId query string false "Vendor ID (tenant-level users only)"
+// @Param active query bool false "Only active templates"
+// @Success 200 {object} models.ResponseTemplateListResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/response-templates [get]
+func (h *ReviewsHandler) ListResponseTemplates(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	// Vendor staff see the tenant-wide templates and their own vendor's
+	vendorID := c.Query("vendorId")
+	if scoped := gosharedmw.GetVendorScopeFilter(c); scoped != "" {
+		vendorID = scoped
+	}
+
+	templates, err := h.repo.ListResponseTemplates(tenantID, vendorID, c.Query("active") == "true")
+	if err != nil {
+		log.Printf("Failed to list response templates: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to list response templates",
+			},
+		})
+		return
+	}
+	if templates == nil {
+		templates = []models.ReviewResponseTemplate{}
+	}
+
+	c.JSON(http.StatusOK, models.ResponseTemplateListResponse{
+		Success: true,
+		Data:    templates,
+	})
+}
+
+// GetResponseTemplate returns a response template
+// @Summary Get a review response template
+// @Tags reviews
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Success 200 {object} models.ResponseTemplateResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/response-templates/{templateId} [get]
+func (h *ReviewsHandler) GetResponseTemplate(c *gin.Context) {
+	template, ok := h.loadResponseTemplate(c, false)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseTemplateResponse{
+		Success: true,
+		Data:    template,
+	})
+}
+
+// CreateResponseTemplate creates a response template
+// @Summary Create a review response template
+// @Description Placeholders {{customerName}}, {{reviewTitle}} and {{rating}} are filled from the review
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param template body models.CreateResponseTemplateRequest true "Template data"
+// @Success 201 {object} models.ResponseTemplateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/response-templates [post]
+func (h *ReviewsHandler) CreateResponseTemplate(c *gin.Context) {
+	var req models.CreateResponseTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	tenantID := c.GetString("tenantId")
+	userID := c.GetString("userId")
+
+	// Vendor staff always create templates for their own vendor
+	vendorID := req.VendorID
+	if scoped := gosharedmw.GetVendorScopeFilter(c); scoped != "" {
+		vendorID = scoped
+	}
+
+	template := &models.ReviewResponseTemplate{
+		TenantID:  tenantID,
+		VendorID:  vendorID,
+		Name:      req.Name,
+		Content:   req.Content,
+		Category:  req.Category,
+		IsActive:  true,
+		CreatedBy: &userID,
+		UpdatedBy: &userID,
+	}
+
+	if err := h.repo.CreateResponseTemplate(template); err != nil {
+		log.Printf("Failed to create response template: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "CREATE_FAILED",
+				Message: "Failed to create response template",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ResponseTemplateResponse{
+		Success: true,
+		Data:    template,
+	})
+}
+
+// UpdateResponseTemplate updates a response template
+// @Summary Update a review response template
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Param template body models.UpdateResponseTemplateRequest true "Template changes"
+// @Success 200 {object} models.ResponseTemplateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/response-templates/{templateId} [put]
+func (h *ReviewsHandler) UpdateResponseTemplate(c *gin.Context) {
+	template, ok := h.loadResponseTemplate(c, true)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateResponseTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.Name != nil {
+		template.Name = *req.Name
+	}
+	if req.Content != nil {
+		template.Content = *req.Content
+	}
+	if req.Category != nil {
+		template.Category = req.Category
+	}
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+	}
+	userID := c.GetString("userId")
+	template.UpdatedBy = &userID
+
+	if err := h.repo.UpdateResponseTemplate(template); err != nil {
+		log.Printf("Failed to update response template %s: %v", template.ID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_FAILED",
+				Message: "Failed to update response template",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseTemplateResponse{
+		Success: true,
+		Data:    template,
+	})
+}
+
+// DeleteResponseTemplate deletes a response template
+// @Summary Delete a review response template
+// @Tags reviews
+// @Produce json
+// @Param templateId path string true "Template ID"
+// @Success 200 {object} models.ResponseTemplateResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/response-templates/{templateId} [delete]
+func (h *ReviewsHandler) DeleteResponseTemplate(c *gin.Context) {
+	template, ok := h.loadResponseTemplate(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteResponseTemplate(template.TenantID, template.ID); err != nil {
+		log.Printf("Failed to delete response template %s: %v", template.ID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "DELETE_FAILED",
+				Message: "Failed to delete response template",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ResponseTemplateResponse{
+		Success: true,
+		Data:    template,
+	})
+}
+
+// loadResponseTemplate loads the :templateId template. Vendor staff can read tenant-wide
+// templates but only change their own vendor's.
+func (h *ReviewsHandler) loadResponseTemplate(c *gin.Context, forWrite bool) (*models.ReviewResponseTemplate, bool) {
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid template ID format",
+			},
+		})
+		return nil, false
+	}
+
+	template, err := h.repo.GetResponseTemplate(c.GetString("tenantId"), templateID)
+	vendorID := gosharedmw.GetVendorScopeFilter(c)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !templateVisibleTo(template, vendorID)) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Response template not found",
+			},
+		})
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Failed to get response template %s: %v", templateID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to get response template",
+			},
+		})
+		return nil, false
+	}
+
+	if forWrite && vendorID != "" && template.VendorID != vendorID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VENDOR_ACCESS_DENIED",
+				Message: "Tenant-wide templates can only be changed by tenant staff",
+			},
+		})
+		return nil, false
+	}
+	return template, true
+}
@@ -2,18 +2,22 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"log"
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"reviews-service/internal/clients"
 	"reviews-service/internal/events"
 	"reviews-service/internal/models"
 	"reviews-service/internal/repository"
+	"reviews-service/internal/sentiment"
 )
 
 type ReviewsHandler struct {
@@ -21,6 +25,8 @@ type ReviewsHandler struct {
 	notificationClient *clients.NotificationClient
 	tenantClient       *clients.TenantClient
 	eventsPublisher    *events.Publisher
+	sentimentProvider  sentiment.Provider // nil when sentiment analysis is turned off
+	marketingClient    *clients.MarketingClient
 }
 
 // extractAverageRating computes an average rating (1-5) from multi-aspect JSONB ratings.
@@ -53,12 +59,14 @@ func extractAverageRating(ratings *models.JSON) int {
 	return rounded
 }
 
-func NewReviewsHandler(repo *repository.ReviewsRepository, notificationClient *clients.NotificationClient, tenantClient *clients.TenantClient, eventsPublisher *events.Publisher) *ReviewsHandler {
+func NewReviewsHandler(repo *repository.ReviewsRepository, notificationClient *clients.NotificationClient, tenantClient *clients.TenantClient, eventsPublisher *events.Publisher, sentimentProvider sentiment.Provider, marketingClient *clients.MarketingClient) *ReviewsHandler {
 	return &ReviewsHandler{
 		repo:               repo,
 		notificationClient: notificationClient,
 		tenantClient:       tenantClient,
 		eventsPublisher:    eventsPublisher,
+		sentimentProvider:  sentimentProvider,
+		marketingClient:    marketingClient,
 	}
 }
 
@@ -112,6 +120,7 @@ func (h *ReviewsHandler) CreateReview(c *gin.Context) {
 		UserID:           userID,
 		UserName:         userNamePtr,
 		UserEmail:        userEmailPtr,
+		ContactEmail:     userEmailPtr,
 		Title:            req.Title,
 		Content:          req.Content,
 		Type:             req.Type,
@@ -150,6 +159,11 @@ func (h *ReviewsHandler) CreateReview(c *gin.Context) {
 		review.Tags = &tagsJSON
 	}
 
+	// A type without the flag still marks the review as incentivized
+	if req.Incentivized != nil || req.IncentiveType != nil {
+		review.SetIncentive(req.Incentivized == nil || *req.Incentivized, req.IncentiveType, req.IncentiveValue)
+	}
+
 	if err := h.repo.CreateReview(tenantID, review); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -161,6 +175,9 @@ func (h *ReviewsHandler) CreateReview(c *gin.Context) {
 		return
 	}
 
+	// Score sentiment and extract topics (non-blocking)
+	h.analyzeReviewAsync(tenantID, review)
+
 	// Send review notification via notification-service (non-blocking)
 	if h.notificationClient != nil {
 		go func() {
@@ -247,12 +264,19 @@ func (h *ReviewsHandler) CreateReview(c *gin.Context) {
 // @Param status query string false "Status filter"
 // @Param userId query string false "User ID filter"
 // @Param featured query bool false "Featured filter"
+// @Param incentivized query bool false "Incentivized filter"
 // @Success 200 {object} models.ReviewListResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Security BearerAuth
 // @Router /reviews [get]
 func (h *ReviewsHandler) GetReviews(c *gin.Context) {
+	h.listReviews(c, false)
+}
+
+// listReviews lists reviews matching the query parameters. Storefront listings disclose
+// incentivized reviews.
+func (h *ReviewsHandler) listReviews(c *gin.Context, storefront bool) {
 	tenantID := c.GetString("tenantId")
 
 	// Parse query parameters
@@ -284,6 +308,10 @@ func (h *ReviewsHandler) GetReviews(c *gin.Context) {
 		featuredBool, _ := strconv.ParseBool(featured)
 		req.Featured = &featuredBool
 	}
+	if incentivized := c.Query("incentivized"); incentivized != "" {
+		incentivizedBool, _ := strconv.ParseBool(incentivized)
+		req.Incentive = &incentivizedBool
+	}
 	if query := c.Query("q"); query != "" {
 		req.Query = &query
 	}
@@ -300,6 +328,12 @@ func (h *ReviewsHandler) GetReviews(c *gin.Context) {
 		return
 	}
 
+	if storefront {
+		for i := range reviews {
+			reviews[i].DiscloseIncentive()
+		}
+	}
+
 	// Calculate pagination info
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
 	pagination := &models.PaginationInfo{
@@ -450,6 +484,12 @@ func (h *ReviewsHandler) UpdateReview(c *gin.Context) {
 		updates.Tags = &tagsJSON
 	}
 
+	if req.Incentivized != nil || req.IncentiveType != nil || req.IncentiveValue != nil {
+		if !h.updateIncentive(c, tenantID, reviewID, &req) {
+			return
+		}
+	}
+
 	if err := h.repo.UpdateReview(tenantID, reviewID, updates); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -474,6 +514,11 @@ func (h *ReviewsHandler) UpdateReview(c *gin.Context) {
 		return
 	}
 
+	// Edited text is re-analyzed
+	if req.Title != nil || req.Content != nil {
+		h.analyzeReviewAsync(tenantID, updatedReview)
+	}
+
 	c.JSON(http.StatusOK, models.ReviewResponse{
 		Success: true,
 		Data:    updatedReview,
@@ -546,6 +591,10 @@ func (h *ReviewsHandler) UpdateReviewStatus(c *gin.Context) {
 		return
 	}
 
+	if req.Status == models.ReviewStatusApproved {
+		h.issueIncentivesAsync(tenantID, []uuid.UUID{reviewID})
+	}
+
 	// Send review status notification via notification-service (non-blocking)
 	if h.notificationClient != nil {
 		go func() {
@@ -558,10 +607,7 @@ func (h *ReviewsHandler) UpdateReviewStatus(c *gin.Context) {
 				customerName = *updatedReview.UserName
 			}
 
-			customerEmail := ""
-			if updatedReview.UserEmail != nil {
-				customerEmail = *updatedReview.UserEmail
-			}
+			customerEmail := updatedReview.CustomerEmail()
 
 			productName := updatedReview.TargetType
 			reviewTitle := ""
@@ -699,6 +745,16 @@ func (h *ReviewsHandler) BulkUpdateStatus(c *gin.Context) {
 		return
 	}
 
+	if req.Status == models.ReviewStatusApproved {
+		var reviewIDs []uuid.UUID
+		for _, idStr := range req.ReviewIDs {
+			if id, err := uuid.Parse(idStr); err == nil {
+				reviewIDs = append(reviewIDs, id)
+			}
+		}
+		h.issueIncentivesAsync(tenantID, reviewIDs)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Reviews updated successfully",
@@ -796,6 +852,141 @@ func (h *ReviewsHandler) GetAnalytics(c *gin.Context) {
 	})
 }
 
+// GetVendorRatingTrends returns rating trends for a single vendor
+// @Summary Get vendor rating trends
+// @Description Approved review ratings over time for a vendor. Vendor-scoped users always see their own vendor; tenant-level users must pass vendorId.
+// @Tags reviews
+// @Produce json
+// @Param vendorId query string false "Vendor ID (tenant-level users only)"
+// @Param dateFrom query string false "Start date (RFC3339), defaults to 30 days ago"
+// @Param dateTo query string false "End date (RFC3339), defaults to now"
+// @Success 200 {object} models.VendorRatingTrendsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/analytics/vendor [get]
+func (h *ReviewsHandler) GetVendorRatingTrends(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	// Vendor-scoped users are pinned to their own vendor by VendorScopeFilter
+	vendorID := c.Query("vendorId")
+	if scoped := gosharedmw.GetVendorScopeFilter(c); scoped != "" {
+		if vendorID != "" && vendorID != scoped {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "VENDOR_ACCESS_DENIED",
+					Message: "You can only view ratings for your own vendor",
+				},
+			})
+			return
+		}
+		vendorID = scoped
+	}
+	if vendorID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VENDOR_REQUIRED",
+				Message: "vendorId query parameter is required for tenant-level users",
+			},
+		})
+		return
+	}
+
+	dateTo := time.Now().UTC()
+	dateFrom := dateTo.AddDate(0, 0, -30)
+	if dateFromStr := c.Query("dateFrom"); dateFromStr != "" {
+		if parsed, err := time.Parse(time.RFC3339, dateFromStr); err == nil {
+			dateFrom = parsed
+		}
+	}
+	if dateToStr := c.Query("dateTo"); dateToStr != "" {
+		if parsed, err := time.Parse(time.RFC3339, dateToStr); err == nil {
+			dateTo = parsed
+		}
+	}
+	if !dateFrom.Before(dateTo) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_DATE_RANGE",
+				Message: "dateFrom must be before dateTo",
+			},
+		})
+		return
+	}
+
+	trends, err := h.repo.GetVendorRatingTrends(tenantID, vendorID, dateFrom, dateTo)
+	if err != nil {
+		log.Printf("Failed to compute vendor rating trends for vendor %s: %v", vendorID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "ANALYTICS_FAILED",
+				Message: "Failed to generate vendor rating trends",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.VendorRatingTrendsResponse{
+		Success: true,
+		Data:    *trends,
+	})
+}
+
+// GetProductReviewStats returns review counts, rating breakdowns and top review snippets
+// for a batch of products. Used by products-service catalog exports.
+func (h *ReviewsHandler) GetProductReviewStats(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+	if tenantID == "" {
+		// Internal service calls only set tenant_id
+		tenantID = c.GetString("tenant_id")
+	}
+
+	var req models.ProductReviewStatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	if req.Snippets < 0 || req.Snippets > 5 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "snippets must be between 0 and 5",
+			},
+		})
+		return
+	}
+
+	stats, err := h.repo.GetProductReviewStats(tenantID, req.ProductIDs, req.Snippets)
+	if err != nil {
+		log.Printf("Failed to compute product review stats: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "ANALYTICS_FAILED",
+				Message: "Failed to compute product review stats",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ProductReviewStatsResponse{
+		Success: true,
+		Data:    stats,
+	})
+}
+
 // Placeholder handlers for additional endpoints
 func (h *ReviewsHandler) BulkModerate(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
@@ -1037,11 +1228,50 @@ func (h *ReviewsHandler) AddComment(c *gin.Context) {
 		})
 		return
 	}
+	if strings.TrimSpace(req.Content) == "" && req.TemplateID == nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "content or templateId is required",
+				Field:   "content",
+			},
+		})
+		return
+	}
 
 	tenantID := c.GetString("tenantId")
 	userID := c.GetString("userId")
 	userName := c.GetString("userName")
 
+	review, ok := h.loadReviewForResponse(c, tenantID, reviewID)
+	if !ok {
+		return
+	}
+	vendorID := gosharedmw.GetVendorScopeFilter(c)
+
+	// Content given alongside a template overrides it
+	content := req.Content
+	var templateIDStr *string
+	if req.TemplateID != nil {
+		template, err := h.repo.GetResponseTemplate(tenantID, *req.TemplateID)
+		if err != nil || !template.IsActive || !templateVisibleTo(template, vendorID) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "INVALID_TEMPLATE",
+					Message: "Response template not found or inactive",
+					Field:   "templateId",
+				},
+			})
+			return
+		}
+		if strings.TrimSpace(content) == "" {
+			content = template.Render(review, extractAverageRating(review.Ratings))
+		}
+		templateIDStr = stringPtr(template.ID.String())
+	}
+
 	// Create comment
 	isInternal := false
 	if req.IsInternal != nil {
@@ -1052,12 +1282,14 @@ func (h *ReviewsHandler) AddComment(c *gin.Context) {
 		ID:         uuid.New().String(),
 		UserID:     userID,
 		UserName:   userName,
-		Content:    req.Content,
+		VendorID:   vendorID,
+		Content:    content,
 		IsInternal: isInternal,
+		TemplateID: templateIDStr,
 		CreatedAt:  time.Now(),
 	}
 
-	review, err := h.repo.AddComment(tenantID, reviewID, comment)
+	review, err = h.repo.AddComment(tenantID, reviewID, comment)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Success: false,
@@ -1069,14 +1301,168 @@ func (h *ReviewsHandler) AddComment(c *gin.Context) {
 		return
 	}
 
+	if req.TemplateID != nil {
+		if err := h.repo.IncrementTemplateUsage(tenantID, *req.TemplateID); err != nil {
+			log.Printf("[REVIEWS] Failed to count response template usage: %v", err)
+		}
+	}
+
+	// Public responses are sent to the customer (non-blocking)
+	if !isInternal {
+		h.notifyReviewResponse(tenantID, review, comment)
+	}
+
 	c.JSON(http.StatusCreated, models.ReviewResponse{
 		Success: true,
 		Data:    review,
 	})
 }
+
+// UpdateComment edits a comment, keeping the previous content in its edit history
 func (h *ReviewsHandler) UpdateComment(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
+	reviewID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid review ID format",
+			},
+		})
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	tenantID := c.GetString("tenantId")
+	commentID := c.Param("commentId")
+
+	review, ok := h.loadReviewForResponse(c, tenantID, reviewID)
+	if !ok {
+		return
+	}
+
+	// Vendor staff can only edit their own vendor's responses
+	if vendorID := gosharedmw.GetVendorScopeFilter(c); vendorID != "" && review.Comments != nil {
+		if entry, ok := (*review.Comments)[commentID].(map[string]interface{}); ok {
+			if commentVendor, _ := entry["vendorId"].(string); commentVendor != vendorID {
+				c.JSON(http.StatusForbidden, models.ErrorResponse{
+					Success: false,
+					Error: models.Error{
+						Code:    "VENDOR_ACCESS_DENIED",
+						Message: "You can only edit your own vendor's responses",
+					},
+				})
+				return
+			}
+		}
+	}
+
+	updated, err := h.repo.UpdateComment(tenantID, reviewID, commentID, req.Content, c.GetString("userId"))
+	if errors.Is(err, repository.ErrCommentNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Comment not found",
+			},
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_COMMENT_FAILED",
+				Message: "Failed to update comment",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ReviewResponse{
+		Success: true,
+		Data:    updated,
+	})
+}
+
+// loadReviewForResponse loads a review being responded to. Vendor-scoped staff may only
+// respond to reviews of their own vendor's products.
+func (h *ReviewsHandler) loadReviewForResponse(c *gin.Context, tenantID string, reviewID uuid.UUID) (*models.Review, bool) {
+	review, err := h.repo.GetReviewByID(tenantID, reviewID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Review not found",
+			},
+		})
+		return nil, false
+	}
+
+	if vendorID := gosharedmw.GetVendorScopeFilter(c); vendorID != "" && review.VendorID != vendorID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VENDOR_ACCESS_DENIED",
+				Message: "You can only respond to reviews of your own products",
+			},
+		})
+		return nil, false
+	}
+	return review, true
+}
+
+// notifyReviewResponse emails the customer a store or vendor response (non-blocking)
+func (h *ReviewsHandler) notifyReviewResponse(tenantID string, review *models.Review, comment *models.Comment) {
+	if h.notificationClient == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		customerName := ""
+		if review.UserName != nil {
+			customerName = *review.UserName
+		}
+
+		notification := &clients.ReviewNotification{
+			TenantID:      tenantID,
+			ReviewID:      review.ID.String(),
+			ProductID:     review.TargetID,
+			ProductName:   review.TargetType,
+			CustomerEmail: review.CustomerEmail(),
+			CustomerName:  customerName,
+			Rating:        extractAverageRating(review.Ratings),
+			Comment:       review.Content,
+			IsVerified:    review.VerifiedPurchase,
+			Status:        "RESPONDED",
+			ResponseText:  comment.Content,
+			ResponderName: comment.UserName,
+			ProductURL:    h.tenantClient.BuildProductURL(ctx, tenantID, review.TargetID),
+		}
+		if review.Title != nil {
+			notification.Title = *review.Title
+		}
+
+		if err := h.notificationClient.SendReviewResponseNotification(ctx, notification); err != nil {
+			log.Printf("[REVIEWS] Failed to send review response notification: %v", err)
+		}
+	}()
 }
+
 func (h *ReviewsHandler) DeleteComment(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
 }
@@ -1089,9 +1475,6 @@ func (h *ReviewsHandler) ExportReviews(c *gin.Context) {
 func (h *ReviewsHandler) ReportReview(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
 }
-func (h *ReviewsHandler) AIModeration(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
-}
 func (h *ReviewsHandler) FindSimilarReviews(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
 }
@@ -1134,6 +1517,7 @@ func (h *ReviewsHandler) StorefrontCreateReview(c *gin.Context) {
 		UserID:        userID,
 		UserName:      &userName,
 		UserEmail:     &userEmail,
+		ContactEmail:  &userEmail,
 		Title:         req.Title,
 		Content:       req.Content,
 		Type:          req.Type,
@@ -1143,6 +1527,12 @@ func (h *ReviewsHandler) StorefrontCreateReview(c *gin.Context) {
 		CreatedBy:     &userID,
 	}
 
+	// Self-declared incentives are disclosed on the storefront but never issued
+	if req.Incentivized || req.IncentiveType != nil {
+		review.Incentivized = true
+		review.IncentiveType = req.IncentiveType
+	}
+
 	// Convert ratings to JSON
 	if len(req.Ratings) > 0 {
 		ratingsJSON := make(models.JSON)
@@ -1166,6 +1556,9 @@ func (h *ReviewsHandler) StorefrontCreateReview(c *gin.Context) {
 		return
 	}
 
+	// Score sentiment and extract topics (non-blocking)
+	h.analyzeReviewAsync(tenantID, review)
+
 	// Send review notification via notification-service (non-blocking)
 	if h.notificationClient != nil {
 		go func() {
@@ -1227,6 +1620,8 @@ func (h *ReviewsHandler) StorefrontCreateReview(c *gin.Context) {
 		}
 	}
 
+	review.DiscloseIncentive()
+
 	c.JSON(http.StatusCreated, models.ReviewResponse{
 		Success: true,
 		Data:    review,
@@ -1243,7 +1638,7 @@ func (h *ReviewsHandler) StorefrontGetReviews(c *gin.Context) {
 		q.Set("status", "APPROVED")
 		c.Request.URL.RawQuery = q.Encode()
 	}
-	h.GetReviews(c)
+	h.listReviews(c, true)
 }
 
 // Helper function to create string pointer
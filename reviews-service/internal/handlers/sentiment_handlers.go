@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"reviews-service/internal/models"
+	"reviews-service/internal/sentiment"
+)
+
+// sentimentTimeout bounds a single review's trip through the sentiment pipeline
+const sentimentTimeout = 15 * time.Second
+
+// analyzeReview runs the review through the sentiment provider and stores the score and topics
+func (h *ReviewsHandler) analyzeReview(ctx context.Context, tenantID string, review *models.Review) (*models.ReviewAnalysis, error) {
+	language := ""
+	if review.Language != nil {
+		language = *review.Language
+	}
+
+	analysis, err := h.sentimentProvider.Analyze(ctx, sentiment.ReviewText(review), language)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.repo.SaveReviewAnalysis(tenantID, review, analysis); err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+// analyzeReviewAsync analyzes a new or edited review in the background (non-blocking)
+func (h *ReviewsHandler) analyzeReviewAsync(tenantID string, review *models.Review) {
+	if h.sentimentProvider == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sentimentTimeout)
+		defer cancel()
+
+		if _, err := h.analyzeReview(ctx, tenantID, review); err != nil && !errors.Is(err, sentiment.ErrUnsupportedLanguage) {
+			log.Printf("[REVIEWS] Failed to analyze sentiment for review %s: %v", review.ID, err)
+		}
+	}()
+}
+
+// AIModeration re-runs the sentiment pipeline on a review and returns the analysis
+// @Summary Analyze review sentiment
+// @Description Score a review's sentiment and extract the topics it mentions
+// @Tags reviews
+// @Produce json
+// @Param id path string true "Review ID"
+// @Success 200 {object} models.ReviewAnalysisResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/{id}/moderate/ai [post]
+func (h *ReviewsHandler) AIModeration(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	reviewID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid review ID format",
+			},
+		})
+		return
+	}
+
+	if h.sentimentProvider == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "SENTIMENT_DISABLED",
+				Message: "Sentiment analysis is not enabled",
+			},
+		})
+		return
+	}
+
+	review, err := h.repo.GetReviewByID(tenantID, reviewID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Review not found",
+			},
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), sentimentTimeout)
+	defer cancel()
+
+	analysis, err := h.analyzeReview(ctx, tenantID, review)
+	if errors.Is(err, sentiment.ErrUnsupportedLanguage) {
+		c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UNSUPPORTED_LANGUAGE",
+				Message: "The sentiment provider can't analyze reviews in this language",
+			},
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to analyze sentiment for review %s: %v", reviewID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "ANALYSIS_FAILED",
+				Message: "Failed to analyze review sentiment",
+			},
+		})
+		return
+	}
+
+	// Return the review with its stored analysis
+	if updated, err := h.repo.GetReviewByID(tenantID, reviewID); err == nil {
+		review = updated
+	}
+
+	c.JSON(http.StatusOK, models.ReviewAnalysisResponse{
+		Success:  true,
+		Data:     review,
+		Analysis: analysis,
+	})
+}
+
+// GetReviewMentions returns the sentiment split and most mentioned topics of approved reviews,
+// for one target (targetId) or the whole tenant
+// @Summary What customers mention
+// @Description Sentiment split and most mentioned topics of approved reviews
+// @Tags reviews
+// @Produce json
+// @Param targetId query string false "Product or other review target"
+// @Param limit query int false "Number of topics" default(10)
+// @Success 200 {object} models.ReviewMentionsResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/mentions [get]
+func (h *ReviewsHandler) GetReviewMentions(c *gin.Context) {
+	h.respondReviewMentions(c, c.GetString("tenantId"), c.Query("targetId"))
+}
+
+// StorefrontGetReviewMentions returns the "what customers mention" summary for a product page
+func (h *ReviewsHandler) StorefrontGetReviewMentions(c *gin.Context) {
+	targetID := c.Query("targetId")
+	if targetID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: "targetId query parameter is required",
+				Field:   "targetId",
+			},
+		})
+		return
+	}
+	h.respondReviewMentions(c, c.GetString("tenantId"), targetID)
+}
+
+func (h *ReviewsHandler) respondReviewMentions(c *gin.Context, tenantID, targetID string) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	mentions, err := h.repo.GetReviewMentions(tenantID, targetID, limit)
+	if err != nil {
+		log.Printf("Failed to compute review mentions for target %q: %v", targetID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "ANALYTICS_FAILED",
+				Message: "Failed to compute review mentions",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ReviewMentionsResponse{
+		Success: true,
+		Data:    *mentions,
+	})
+}
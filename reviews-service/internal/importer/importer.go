@@ -0,0 +1,459 @@
+// Package importer turns review exports from other platforms (Shopify Product Reviews,
+// Amazon and Judge.me) into reviews-service reviews.
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Platform is the platform a review export came from
+type Platform string
+
+const (
+	PlatformShopify Platform = "shopify"
+	PlatformAmazon  Platform = "amazon"
+	PlatformJudgeMe Platform = "judgeme"
+	PlatformCustom  Platform = "custom" // Generic export, mapped with the canonical field names
+)
+
+// Canonical fields an export column can be mapped onto
+const (
+	FieldExternalID       = "externalId"
+	FieldProduct          = "product"
+	FieldTitle            = "title"
+	FieldContent          = "content"
+	FieldRating           = "rating"
+	FieldReviewerName     = "reviewerName"
+	FieldReviewerEmail    = "reviewerEmail"
+	FieldReviewDate       = "reviewDate"
+	FieldVerifiedPurchase = "verifiedPurchase"
+	FieldMediaURLs        = "mediaUrls"
+	FieldReply            = "reply"
+	FieldReplyDate        = "replyDate"
+	FieldState            = "state"
+	FieldLanguage         = "language"
+	FieldLocation         = "location"
+)
+
+// CanonicalFields lists every field a column mapping can target
+var CanonicalFields = []string{
+	FieldExternalID, FieldProduct, FieldTitle, FieldContent, FieldRating, FieldReviewerName,
+	FieldReviewerEmail, FieldReviewDate, FieldVerifiedPurchase, FieldMediaURLs, FieldReply,
+	FieldReplyDate, FieldState, FieldLanguage, FieldLocation,
+}
+
+// platformMappings maps each platform's normalized export headers onto canonical fields
+var platformMappings = map[Platform]map[string]string{
+	// Shopify Product Reviews app CSV export
+	PlatformShopify: {
+		"id":             FieldExternalID,
+		"product_handle": FieldProduct,
+		"title":          FieldTitle,
+		"body":           FieldContent,
+		"rating":         FieldRating,
+		"author":         FieldReviewerName,
+		"email":          FieldReviewerEmail,
+		"created_at":     FieldReviewDate,
+		"reply":          FieldReply,
+		"replied_at":     FieldReplyDate,
+		"state":          FieldState,
+		"location":       FieldLocation,
+	},
+	// Amazon review reports and review exporters
+	PlatformAmazon: {
+		"review_id":         FieldExternalID,
+		"asin":              FieldProduct,
+		"sku":               FieldProduct,
+		"review_title":      FieldTitle,
+		"title":             FieldTitle,
+		"review_text":       FieldContent,
+		"body":              FieldContent,
+		"star_rating":       FieldRating,
+		"rating":            FieldRating,
+		"reviewer_name":     FieldReviewerName,
+		"author":            FieldReviewerName,
+		"review_date":       FieldReviewDate,
+		"date":              FieldReviewDate,
+		"verified_purchase": FieldVerifiedPurchase,
+		"image_urls":        FieldMediaURLs,
+		"images":            FieldMediaURLs,
+		"country":           FieldLocation,
+	},
+	// Judge.me CSV/JSON export
+	PlatformJudgeMe: {
+		"id":             FieldExternalID,
+		"product_handle": FieldProduct,
+		"title":          FieldTitle,
+		"body":           FieldContent,
+		"rating":         FieldRating,
+		"reviewer_name":  FieldReviewerName,
+		"reviewer_email": FieldReviewerEmail,
+		"review_date":    FieldReviewDate,
+		"created_at":     FieldReviewDate,
+		"verified_buyer": FieldVerifiedPurchase,
+		"picture_urls":   FieldMediaURLs,
+		"reply":          FieldReply,
+		"reply_date":     FieldReplyDate,
+		"curated":        FieldState,
+		"location":       FieldLocation,
+	},
+}
+
+// ValidPlatform reports whether p is a supported import platform
+func ValidPlatform(p Platform) bool {
+	if p == PlatformCustom {
+		return true
+	}
+	_, ok := platformMappings[p]
+	return ok
+}
+
+// Row is one export record keyed by normalized header, with its 1-based source row number
+type Row struct {
+	Number int
+	Values map[string]string
+}
+
+// NormalizeHeader lowercases a header and turns spaces and dashes into underscores,
+// so "Review Date" and "review-date" both become "review_date"
+func NormalizeHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(header)
+}
+
+// ParseCSV reads a CSV export with a header row
+func ParseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i := range headers {
+		headers[i] = NormalizeHeader(headers[i])
+	}
+
+	var rows []Row
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading line %d: %w", line, err)
+		}
+
+		values := make(map[string]string, len(headers))
+		for i, value := range record {
+			if i < len(headers) {
+				values[headers[i]] = strings.TrimSpace(value)
+			}
+		}
+		rows = append(rows, Row{Number: line, Values: values})
+	}
+	return rows, nil
+}
+
+// ParseJSON reads a JSON export: an array of review objects, or an object with a
+// "reviews" array (Judge.me). Arrays of strings, such as picture URLs, are joined with commas.
+func ParseJSON(r io.Reader) ([]Row, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var records []map[string]interface{}
+	if err := json.Unmarshal(raw, &records); err != nil {
+		var wrapped struct {
+			Reviews []map[string]interface{} `json:"reviews"`
+		}
+		if err := json.Unmarshal(raw, &wrapped); err != nil {
+			return nil, fmt.Errorf("JSON export must be an array of reviews or an object with a \"reviews\" array")
+		}
+		records = wrapped.Reviews
+	}
+
+	rows := make([]Row, 0, len(records))
+	for i, record := range records {
+		values := make(map[string]string, len(record))
+		for key, value := range record {
+			values[NormalizeHeader(key)] = stringifyJSON(value)
+		}
+		rows = append(rows, Row{Number: i + 1, Values: values})
+	}
+	return rows, nil
+}
+
+func stringifyJSON(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			// Picture lists are sometimes objects like {"url": "..."}
+			if obj, ok := item.(map[string]interface{}); ok {
+				item = obj["url"]
+			}
+			if s := stringifyJSON(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ",")
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// Mapping maps normalized export headers onto canonical fields. Custom entries are
+// applied over the platform's defaults, so tenants only remap the columns that differ.
+type Mapping map[string]string
+
+// NewMapping returns the platform's default mapping overlaid with custom entries
+// (source header → canonical field). Unknown canonical fields are rejected.
+func NewMapping(platform Platform, custom map[string]string) (Mapping, error) {
+	mapping := make(Mapping)
+	if platform == PlatformCustom {
+		for _, field := range CanonicalFields {
+			mapping[NormalizeHeader(field)] = field
+		}
+	}
+	for header, field := range platformMappings[platform] {
+		mapping[header] = field
+	}
+
+	valid := make(map[string]bool, len(CanonicalFields))
+	for _, field := range CanonicalFields {
+		valid[field] = true
+	}
+	for header, field := range custom {
+		if field == "" {
+			delete(mapping, NormalizeHeader(header)) // Ignore this column
+			continue
+		}
+		if !valid[field] {
+			return nil, fmt.Errorf("unknown field %q for column %q", field, header)
+		}
+		mapping[NormalizeHeader(header)] = field
+	}
+	return mapping, nil
+}
+
+// Record is a review read from an export, in canonical form
+type Record struct {
+	Row              int
+	ExternalID       string
+	Product          string
+	Title            string
+	Content          string
+	Rating           float64
+	ReviewerName     string
+	ReviewerEmail    string
+	ReviewDate       *time.Time
+	VerifiedPurchase bool
+	MediaURLs        []string
+	Reply            string
+	ReplyDate        *time.Time
+	State            string
+	Language         string
+	Location         string
+}
+
+// FieldError is a problem with one field of an export row
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// Map converts an export row to a Record. Rows without an external ID get a stable one
+// derived from their content, so re-importing the same export doesn't create duplicates.
+func (m Mapping) Map(row Row) (*Record, *FieldError) {
+	headers := make([]string, 0, len(row.Values))
+	for header := range row.Values {
+		headers = append(headers, header)
+	}
+	sort.Strings(headers)
+
+	fields := make(map[string]string)
+	for _, header := range headers {
+		field, ok := m[header]
+		value := row.Values[header]
+		if !ok || value == "" {
+			continue
+		}
+		// When several columns map onto one field the first non-empty one (by header) wins
+		if _, exists := fields[field]; !exists {
+			fields[field] = value
+		}
+	}
+
+	record := &Record{
+		Row:           row.Number,
+		ExternalID:    fields[FieldExternalID],
+		Product:       fields[FieldProduct],
+		Title:         fields[FieldTitle],
+		Content:       fields[FieldContent],
+		ReviewerName:  fields[FieldReviewerName],
+		ReviewerEmail: strings.ToLower(fields[FieldReviewerEmail]),
+		Reply:         fields[FieldReply],
+		State:         strings.ToLower(fields[FieldState]),
+		Language:      fields[FieldLanguage],
+		Location:      fields[FieldLocation],
+	}
+
+	if record.Product == "" {
+		return nil, &FieldError{Field: FieldProduct, Code: "REQUIRED_FIELD", Message: "Product is empty"}
+	}
+	if record.Content == "" {
+		// Rating-only reviews are common on other platforms; fall back to the title
+		record.Content = record.Title
+	}
+	if record.Content == "" {
+		return nil, &FieldError{Field: FieldContent, Code: "REQUIRED_FIELD", Message: "Review text is empty"}
+	}
+
+	rating, err := ParseRating(fields[FieldRating])
+	if err != nil {
+		return nil, &FieldError{Field: FieldRating, Code: "INVALID_RATING", Message: err.Error()}
+	}
+	record.Rating = rating
+
+	if value := fields[FieldReviewDate]; value != "" {
+		date, err := ParseDate(value)
+		if err != nil {
+			return nil, &FieldError{Field: FieldReviewDate, Code: "INVALID_DATE", Message: err.Error()}
+		}
+		record.ReviewDate = &date
+	}
+	if value := fields[FieldReplyDate]; value != "" {
+		if date, err := ParseDate(value); err == nil {
+			record.ReplyDate = &date
+		}
+	}
+
+	record.VerifiedPurchase = parseBool(fields[FieldVerifiedPurchase])
+	record.MediaURLs = splitURLs(fields[FieldMediaURLs])
+
+	if record.ExternalID == "" {
+		record.ExternalID = derivedExternalID(record)
+	}
+	return record, nil
+}
+
+// ParseRating reads a 1-5 star rating such as "4", "4.0", "4 out of 5 stars" or "80%"
+func ParseRating(value string) (float64, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if value == "" {
+		return 0, fmt.Errorf("rating is empty")
+	}
+
+	percent := strings.HasSuffix(value, "%")
+	value = strings.TrimSuffix(value, "%")
+	if fields := strings.Fields(value); len(fields) > 0 {
+		value = fields[0]
+	}
+
+	rating, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("rating %q is not a number", value)
+	}
+	if percent {
+		rating = rating / 20
+	}
+	if rating < 1 || rating > 5 {
+		return 0, fmt.Errorf("rating %v is outside 1-5", rating)
+	}
+	return math.Round(rating*10) / 10, nil
+}
+
+// dateLayouts are the date formats seen in platform exports
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"01/02/2006 15:04",
+	"01/02/2006",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+}
+
+// ParseDate reads an export date, keeping the original time so imported reviews keep their age
+func ParseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	// Amazon: "Reviewed in the United States on March 3, 2023"
+	if i := strings.LastIndex(value, " on "); i >= 0 {
+		value = value[i+len(" on "):]
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	// Unix timestamps
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds > 0 {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+}
+
+func parseBool(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "1", "verified", "verified purchase", "verified_buyer", "buyer":
+		return true
+	}
+	return false
+}
+
+// splitURLs splits a list of media URLs separated by commas, whitespace or pipes
+func splitURLs(value string) []string {
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == '|' || r == ';' || r == ' ' || r == '\n'
+	})
+	urls := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if strings.HasPrefix(part, "http://") || strings.HasPrefix(part, "https://") {
+			urls = append(urls, part)
+		}
+	}
+	return urls
+}
+
+// derivedExternalID hashes the fields that identify a review in exports without IDs (Shopify)
+func derivedExternalID(record *Record) string {
+	date := ""
+	if record.ReviewDate != nil {
+		date = record.ReviewDate.Format(time.RFC3339)
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		record.Product, record.ReviewerEmail, record.ReviewerName, date, record.Content,
+	}, "\x00")))
+	return "sha256:" + hex.EncodeToString(sum[:16])
+}
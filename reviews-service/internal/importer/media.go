@@ -0,0 +1,165 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"reviews-service/internal/models"
+)
+
+// maxSideloadSize caps a single sideloaded media file
+const maxSideloadSize = 20 << 20
+
+// MediaSideloader copies media from the source platform's CDN into document-service, so
+// imported reviews keep their photos after the old store is closed
+type MediaSideloader struct {
+	documentServiceURL string
+	productID          string
+	bucket             string
+	httpClient         *http.Client
+}
+
+// NewMediaSideloader creates a sideloader that uploads to document-service
+func NewMediaSideloader(documentServiceURL, productID string) *MediaSideloader {
+	if productID == "" {
+		productID = "marketplace"
+	}
+	bucket := os.Getenv("STORAGE_PUBLIC_BUCKET")
+	if bucket == "" {
+		bucket = "marketplace-devtest-public-au"
+	}
+	return &MediaSideloader{
+		documentServiceURL: strings.TrimSuffix(documentServiceURL, "/"),
+		productID:          productID,
+		bucket:             bucket,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Sideload downloads sourceURL and stores it as public media of the review
+func (s *MediaSideloader) Sideload(ctx context.Context, tenantID, reviewID, sourceURL string) (*models.Media, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid media URL: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download media: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("media download returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSideloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media: %w", err)
+	}
+	if len(data) > maxSideloadSize {
+		return nil, fmt.Errorf("media exceeds %dMB", maxSideloadSize>>20)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
+	}
+	mediaType, kind := mediaTypeFor(contentType)
+	if mediaType == models.MediaTypeFile {
+		return nil, fmt.Errorf("unsupported media type %q", contentType)
+	}
+
+	filename := path.Base(req.URL.Path)
+	if filename == "" || filename == "/" || filename == "." {
+		filename = "review-media"
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("bucket", s.bucket)
+	writer.WriteField("isPublic", "true")
+	writer.WriteField("tags", fmt.Sprintf("review_id:%s,tenant_id:%s,media_type:%s,source:import", reviewID, tenantID, kind))
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to write form: %w", err)
+	}
+	writer.Close()
+
+	uploadReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.documentServiceURL+"/api/v1/documents/upload", &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload request: %w", err)
+	}
+	uploadReq.Header.Set("Content-Type", writer.FormDataContentType())
+	uploadReq.Header.Set("X-Tenant-ID", tenantID)
+	uploadReq.Header.Set("X-Product-ID", s.productID)
+	uploadReq.Header.Set("X-Internal-Service", "reviews-service")
+
+	uploadResp, err := s.httpClient.Do(uploadReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to communicate with document service: %w", err)
+	}
+	defer uploadResp.Body.Close()
+	if uploadResp.StatusCode < 200 || uploadResp.StatusCode >= 300 {
+		return nil, fmt.Errorf("document service returned status %d", uploadResp.StatusCode)
+	}
+
+	// Document service returns the document object directly, not wrapped
+	var doc struct {
+		ID     string `json:"id"`
+		URL    string `json:"url"`
+		Size   int    `json:"size"`
+		Width  *int   `json:"width"`
+		Height *int   `json:"height"`
+	}
+	if err := json.NewDecoder(uploadResp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document response: %w", err)
+	}
+	if doc.ID == "" {
+		return nil, fmt.Errorf("document response missing ID")
+	}
+
+	return &models.Media{
+		ID:         doc.ID,
+		Type:       mediaType,
+		URL:        doc.URL,
+		FileSize:   &doc.Size,
+		Width:      doc.Width,
+		Height:     doc.Height,
+		UploadedAt: time.Now(),
+	}, nil
+}
+
+// mediaTypeFor maps a content type onto a review media type and document-service media kind
+func mediaTypeFor(contentType string) (models.MediaType, string) {
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		return models.MediaTypeImage, "image"
+	case strings.HasPrefix(contentType, "video/"):
+		return models.MediaTypeVideo, "video"
+	}
+	return models.MediaTypeFile, "file"
+}
+
+// ExternalMedia keeps a media URL that couldn't be sideloaded, so the review still shows it
+func ExternalMedia(id, sourceURL string) *models.Media {
+	return &models.Media{
+		ID:         id,
+		Type:       models.MediaTypeImage,
+		URL:        sourceURL,
+		UploadedAt: time.Now(),
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"reviews-service/internal/tenancy"
 )
 
 // TenantMiddleware extracts and validates tenant information
@@ -36,6 +38,8 @@ func TenantMiddleware() gin.HandlerFunc {
 		// Set tenant ID in context for use by handlers (both keys for compatibility)
 		c.Set("tenantId", tenantID)
 		c.Set("tenant_id", tenantID)
+		// Scope database statements made with the request context (see internal/tenancy)
+		c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), tenantID))
 		c.Next()
 	}
 }
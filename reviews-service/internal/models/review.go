@@ -3,6 +3,8 @@ package models
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -40,6 +42,40 @@ const (
 	VisibilityInternal VisibilityType = "INTERNAL"
 )
 
+// IncentiveType is what a reviewer received in exchange for writing a review
+type IncentiveType string
+
+const (
+	IncentiveLoyaltyPoints IncentiveType = "LOYALTY_POINTS"
+	IncentiveDiscount      IncentiveType = "DISCOUNT"
+	IncentiveFreeProduct   IncentiveType = "FREE_PRODUCT"
+	IncentiveOther         IncentiveType = "OTHER"
+)
+
+// Issuable reports whether marketing-service issues this incentive when the review is approved
+func (t IncentiveType) Issuable() bool {
+	return t == IncentiveLoyaltyPoints || t == IncentiveDiscount
+}
+
+// IncentiveStatus tracks the issuance of a review's incentive
+type IncentiveStatus string
+
+const (
+	IncentiveStatusPending IncentiveStatus = "PENDING"
+	IncentiveStatusIssued  IncentiveStatus = "ISSUED"
+	IncentiveStatusFailed  IncentiveStatus = "FAILED"
+)
+
+// incentiveDisclosures are the storefront labels shown on incentivized reviews
+var incentiveDisclosures = map[IncentiveType]string{
+	IncentiveLoyaltyPoints: "This reviewer received loyalty points for writing this review",
+	IncentiveDiscount:      "This reviewer received a discount for writing this review",
+	IncentiveFreeProduct:   "This reviewer received a free product for writing this review",
+}
+
+// defaultIncentiveDisclosure labels incentivized reviews of any other incentive type
+const defaultIncentiveDisclosure = "This reviewer received an incentive for writing this review"
+
 // MediaType represents the type of media
 type MediaType string
 
@@ -107,13 +143,23 @@ type Media struct {
 
 // Comment represents a review comment
 type Comment struct {
-	ID         string     `json:"id"`
-	UserID     string     `json:"userId"`
-	UserName   string     `json:"userName"`
-	Content    string     `json:"content"`
-	IsInternal bool       `json:"isInternal"`
-	CreatedAt  time.Time  `json:"createdAt"`
-	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+	ID          string            `json:"id"`
+	UserID      string            `json:"userId"`
+	UserName    string            `json:"userName"`
+	VendorID    string            `json:"vendorId,omitempty"` // Set when a vendor's staff responded
+	Content     string            `json:"content"`
+	IsInternal  bool              `json:"isInternal"`
+	TemplateID  *string           `json:"templateId,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   *time.Time        `json:"updatedAt,omitempty"`
+	EditHistory []CommentRevision `json:"editHistory,omitempty"`
+}
+
+// CommentRevision is an earlier version of an edited comment
+type CommentRevision struct {
+	Content  string    `json:"content"`
+	EditedBy string    `json:"editedBy"`
+	EditedAt time.Time `json:"editedAt"`
 }
 
 // Reaction represents a review reaction
@@ -135,7 +181,7 @@ type Tag struct {
 // VendorID enables vendor-specific review filtering in marketplace mode
 type Review struct {
 	ID               uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	TenantID         string          `json:"tenantId" gorm:"not null;index"`
+	TenantID         string          `json:"tenantId" gorm:"not null;index;uniqueIndex:idx_reviews_external"`
 	VendorID         string          `json:"vendorId,omitempty" gorm:"index:idx_reviews_tenant_vendor;index:idx_reviews_vendor_target;index:idx_reviews_vendor_status"` // Vendor isolation for marketplace
 	ApplicationID    string          `json:"applicationId" gorm:"not null"`
 	TargetID         string          `json:"targetId" gorm:"not null;index"`
@@ -143,6 +189,7 @@ type Review struct {
 	UserID           string          `json:"userId" gorm:"not null;index"`
 	UserName         *string         `json:"userName,omitempty"`
 	UserEmail        *string         `json:"userEmail,omitempty" gorm:"-"`
+	ContactEmail     *string         `json:"-"` // Reviewer's email, kept for notifications and never returned
 	Title            *string         `json:"title,omitempty"`
 	Content          string          `json:"content" gorm:"not null"`
 	Status           ReviewStatus    `json:"status" gorm:"not null;default:'PENDING'"`
@@ -163,7 +210,19 @@ type Review struct {
 	UserAgent        *string         `json:"userAgent,omitempty"`
 	SpamScore        *float64        `json:"spamScore,omitempty"`
 	SentimentScore   *float64        `json:"sentimentScore,omitempty"`
+	SentimentLabel   *string         `json:"sentimentLabel,omitempty"` // POSITIVE, NEUTRAL or NEGATIVE
+	Topics           StringArray     `json:"topics,omitempty" gorm:"type:jsonb"`
+	AnalyzedAt       *time.Time      `json:"analyzedAt,omitempty"`
 	ModerationNotes  *string         `json:"moderationNotes,omitempty"`
+	ExternalSource   *string         `json:"externalSource,omitempty" gorm:"uniqueIndex:idx_reviews_external"` // Platform an imported review came from (shopify, amazon, judgeme, custom)
+	ExternalID       *string         `json:"externalId,omitempty" gorm:"uniqueIndex:idx_reviews_external"`     // Review ID on that platform, used to skip duplicates on re-import
+	Incentivized     bool            `json:"incentivized" gorm:"default:false;index"`                          // Collected in exchange for points, a discount or a free product
+	IncentiveType    *IncentiveType  `json:"incentiveType,omitempty"`
+	IncentiveValue   *float64        `json:"incentiveValue,omitempty"`  // Points, or percent off for discounts
+	IncentiveStatus  IncentiveStatus `json:"incentiveStatus,omitempty"` // Set for incentives issued by marketing-service
+	IncentiveRef     *string         `json:"incentiveRef,omitempty"`    // Loyalty transaction ID or coupon code of the issued incentive
+	IncentiveIssued  *time.Time      `json:"incentiveIssuedAt,omitempty" gorm:"column:incentive_issued_at"`
+	Disclosure       *string         `json:"disclosure,omitempty" gorm:"-"` // Storefront incentive label, set by DiscloseIncentive
 	CreatedAt        time.Time       `json:"createdAt"`
 	UpdatedAt        time.Time       `json:"updatedAt"`
 	PublishedAt      *time.Time      `json:"publishedAt,omitempty"`
@@ -186,6 +245,9 @@ type CreateReviewRequest struct {
 	VerifiedPurchase *bool           `json:"verifiedPurchase,omitempty"`
 	Language         *string         `json:"language,omitempty"`
 	Metadata         *JSON           `json:"metadata,omitempty"`
+	Incentivized     *bool           `json:"incentivized,omitempty"`
+	IncentiveType    *IncentiveType  `json:"incentiveType,omitempty" binding:"omitempty,oneof=LOYALTY_POINTS DISCOUNT FREE_PRODUCT OTHER"`
+	IncentiveValue   *float64        `json:"incentiveValue,omitempty" binding:"omitempty,gt=0"` // Points, or percent off for discounts
 }
 
 // StorefrontCreateReviewRequest represents a public storefront review submission (no JWT required)
@@ -199,6 +261,10 @@ type StorefrontCreateReviewRequest struct {
 	ReviewerName  string     `json:"reviewerName" binding:"required"`
 	ReviewerEmail string     `json:"reviewerEmail" binding:"required,email"`
 	Language      *string    `json:"language,omitempty"`
+	// Reviewer's own disclosure that they were given something for the review. Recorded for
+	// the storefront label only; nothing is issued for self-declared incentives.
+	Incentivized  bool           `json:"incentivized,omitempty"`
+	IncentiveType *IncentiveType `json:"incentiveType,omitempty" binding:"omitempty,oneof=LOYALTY_POINTS DISCOUNT FREE_PRODUCT OTHER"`
 }
 
 // UpdateReviewRequest represents a request to update a review
@@ -210,6 +276,10 @@ type UpdateReviewRequest struct {
 	Tags       []string        `json:"tags,omitempty"`
 	Featured   *bool           `json:"featured,omitempty"`
 	Metadata   *JSON           `json:"metadata,omitempty"`
+
+	Incentivized   *bool          `json:"incentivized,omitempty"`
+	IncentiveType  *IncentiveType `json:"incentiveType,omitempty" binding:"omitempty,oneof=LOYALTY_POINTS DISCOUNT FREE_PRODUCT OTHER"`
+	IncentiveValue *float64       `json:"incentiveValue,omitempty" binding:"omitempty,gt=0"`
 }
 
 // UpdateStatusRequest represents a request to update review status
@@ -242,10 +312,12 @@ type AddReactionRequest struct {
 	Type ReactionType `json:"type" binding:"required"`
 }
 
-// AddCommentRequest represents a request to add a comment
+// AddCommentRequest represents a request to add a comment. Content may be left out when a
+// response template is given.
 type AddCommentRequest struct {
-	Content    string `json:"content" binding:"required"`
-	IsInternal *bool  `json:"isInternal,omitempty"`
+	Content    string     `json:"content"`
+	TemplateID *uuid.UUID `json:"templateId,omitempty"`
+	IsInternal *bool      `json:"isInternal,omitempty"`
 }
 
 // UpdateCommentRequest represents a request to update a comment
@@ -263,6 +335,7 @@ type SearchReviewsRequest struct {
 	Type       []ReviewType   `json:"type,omitempty"`
 	UserID     *string        `json:"userId,omitempty"`
 	Featured   *bool          `json:"featured,omitempty"`
+	Incentive  *bool          `json:"incentivized,omitempty"`
 	MinRating  *float64       `json:"minRating,omitempty"`
 	MaxRating  *float64       `json:"maxRating,omitempty"`
 	Tags       []string       `json:"tags,omitempty"`
@@ -363,6 +436,55 @@ type TrendData struct {
 	AverageRating float64 `json:"averageRating"`
 }
 
+// VendorRatingTrendsResponse wraps vendor rating trends
+type VendorRatingTrendsResponse struct {
+	Success bool               `json:"success"`
+	Data    VendorRatingTrends `json:"data"`
+}
+
+// VendorRatingTrends summarizes approved ratings for a single vendor's products and storefront
+type VendorRatingTrends struct {
+	VendorID      string            `json:"vendorId"`
+	TotalReviews  int               `json:"totalReviews"`
+	AverageRating float64           `json:"averageRating"`
+	ByRating      map[string]int    `json:"byRating"`
+	Daily         []TrendData       `json:"daily"`
+	TopRated      []TopReviewedItem `json:"topRated"`
+}
+
+// ProductReviewStatsRequest asks for review stats for a batch of products
+type ProductReviewStatsRequest struct {
+	ProductIDs []string `json:"productIds" binding:"required,min=1,max=500"`
+	Snippets   int      `json:"snippets"` // Top review snippets per product (0-5)
+}
+
+// ProductReviewStatsResponse wraps per-product review stats
+type ProductReviewStatsResponse struct {
+	Success bool                 `json:"success"`
+	Data    []ProductReviewStats `json:"data"`
+}
+
+// ProductReviewStats summarizes a product's approved reviews.
+// Products without approved reviews are omitted.
+type ProductReviewStats struct {
+	ProductID     string          `json:"productId"`
+	ReviewCount   int             `json:"reviewCount"`
+	AverageRating float64         `json:"averageRating"`
+	ByRating      map[string]int  `json:"byRating"`
+	TopSnippets   []ReviewSnippet `json:"topSnippets,omitempty"` // Most helpful first
+}
+
+// ReviewSnippet is a shortened review for exports and summaries
+type ReviewSnippet struct {
+	ReviewID         uuid.UUID `json:"reviewId"`
+	Title            *string   `json:"title,omitempty"`
+	Excerpt          string    `json:"excerpt"`
+	Rating           *float64  `json:"rating,omitempty"`
+	HelpfulCount     int       `json:"helpfulCount"`
+	VerifiedPurchase bool      `json:"verifiedPurchase"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
 // TopReviewedItem represents a top reviewed item
 type TopReviewedItem struct {
 	TargetID      string  `json:"targetId"`
@@ -372,9 +494,138 @@ type TopReviewedItem struct {
 	AverageRating float64 `json:"averageRating"`
 }
 
+// Sentiment labels derived from a review's sentiment score
+const (
+	SentimentPositive = "POSITIVE"
+	SentimentNeutral  = "NEUTRAL"
+	SentimentNegative = "NEGATIVE"
+)
+
+// SentimentLabelThreshold is how far from zero a score must be to count as positive or negative
+const SentimentLabelThreshold = 0.2
+
+// SentimentLabelFor maps a sentiment score (-1 to 1) to a sentiment label
+func SentimentLabelFor(score float64) string {
+	switch {
+	case score >= SentimentLabelThreshold:
+		return SentimentPositive
+	case score <= -SentimentLabelThreshold:
+		return SentimentNegative
+	}
+	return SentimentNeutral
+}
+
+// ReviewAnalysis is the output of the sentiment pipeline for a single review
+type ReviewAnalysis struct {
+	Provider string                `json:"provider"`
+	Score    float64               `json:"score"` // -1 (negative) to 1 (positive)
+	Label    string                `json:"label"`
+	Topics   []ReviewTopicAnalysis `json:"topics"`
+}
+
+// ReviewTopicAnalysis is a topic mentioned in a review, with the sentiment of the text mentioning it
+type ReviewTopicAnalysis struct {
+	Topic     string   `json:"topic"`
+	Sentiment float64  `json:"sentiment"`
+	Keywords  []string `json:"keywords"`
+}
+
+// ReviewTopic stores a topic extracted from a review, for per-product "what customers mention" summaries
+type ReviewTopic struct {
+	ID         uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID   string      `json:"tenantId" gorm:"not null;index:idx_review_topics_tenant_target"`
+	ReviewID   uuid.UUID   `json:"reviewId" gorm:"type:uuid;not null;index"`
+	TargetID   string      `json:"targetId" gorm:"not null;index:idx_review_topics_tenant_target"`
+	TargetType string      `json:"targetType" gorm:"not null"`
+	VendorID   string      `json:"vendorId,omitempty" gorm:"index"`
+	Topic      string      `json:"topic" gorm:"not null;index"`
+	Sentiment  float64     `json:"sentiment"`
+	Keywords   StringArray `json:"keywords" gorm:"type:jsonb"`
+	CreatedAt  time.Time   `json:"createdAt"`
+}
+
+// TableName returns the table name for the ReviewTopic model
+func (ReviewTopic) TableName() string {
+	return "review_topics"
+}
+
+// StringArray is a string list stored as a JSONB array
+type StringArray []string
+
+func (a StringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	return json.Marshal(a)
+}
+
+func (a *StringArray) Scan(value interface{}) error {
+	if value == nil {
+		*a = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+// ReviewAnalysisResponse wraps the result of running the sentiment pipeline on a review
+type ReviewAnalysisResponse struct {
+	Success  bool            `json:"success"`
+	Data     *Review         `json:"data"`
+	Analysis *ReviewAnalysis `json:"analysis"`
+}
+
+// IncentiveReportResponse wraps an incentivized vs organic review report
+type IncentiveReportResponse struct {
+	Success bool            `json:"success"`
+	Data    IncentiveReport `json:"data"`
+}
+
+// IncentiveReport compares the ratings of approved incentivized and organic reviews
+type IncentiveReport struct {
+	VendorID          string                `json:"vendorId,omitempty"`
+	TargetID          string                `json:"targetId,omitempty"`
+	Organic           RatingSegment         `json:"organic"`
+	Incentivized      RatingSegment         `json:"incentivized"`
+	RatingGap         float64               `json:"ratingGap"`         // Incentivized minus organic average, 0 unless both have ratings
+	IncentivizedShare float64               `json:"incentivizedShare"` // Share of reviews, 0-1
+	ByIncentiveType   []IncentiveTypeRating `json:"byIncentiveType"`
+	Issuance          map[string]int        `json:"issuance"` // Reviews per incentive status
+}
+
+// RatingSegment summarizes the ratings of a group of reviews
+type RatingSegment struct {
+	ReviewCount   int            `json:"reviewCount"`
+	AverageRating float64        `json:"averageRating"`
+	ByRating      map[string]int `json:"byRating"`
+}
+
+// IncentiveTypeRating summarizes the ratings of reviews collected with one incentive type
+type IncentiveTypeRating struct {
+	IncentiveType string  `json:"incentiveType"`
+	ReviewCount   int     `json:"reviewCount"`
+	AverageRating float64 `json:"averageRating"`
+}
+
+// ReviewMentionsResponse wraps a "what customers mention" summary
+type ReviewMentionsResponse struct {
+	Success bool           `json:"success"`
+	Data    ReviewMentions `json:"data"`
+}
+
+// ReviewMentions summarizes the sentiment and topics of a target's approved, analyzed reviews
+type ReviewMentions struct {
+	TargetID        string `json:"targetId,omitempty"`
+	AnalyzedReviews int    `json:"analyzedReviews"`
+	SentimentAnalysis
+}
+
 // SentimentAnalysis represents sentiment analysis data
 type SentimentAnalysis struct {
-	Positive float64          `json:"positive"`
+	Positive float64          `json:"positive"` // Share of reviews, 0-1
 	Negative float64          `json:"negative"`
 	Neutral  float64          `json:"neutral"`
 	Themes   []SentimentTheme `json:"themes"`
@@ -383,9 +634,11 @@ type SentimentAnalysis struct {
 // SentimentTheme represents a sentiment theme
 type SentimentTheme struct {
 	Theme     string   `json:"theme"`
-	Sentiment float64  `json:"sentiment"`
-	Frequency int      `json:"frequency"`
-	Keywords  []string `json:"keywords"`
+	Sentiment float64  `json:"sentiment"` // Average sentiment of the mentions, -1 to 1
+	Frequency int      `json:"frequency"` // Reviews mentioning the theme
+	Positive  int      `json:"positive"`
+	Negative  int      `json:"negative"`
+	Keywords  []string `json:"keywords"` // Most used words for the theme
 }
 
 // ErrorResponse represents an error response
@@ -409,6 +662,126 @@ func (Review) TableName() string {
 	return "reviews"
 }
 
+// CustomerEmail returns the reviewer's email address, if known
+func (r *Review) CustomerEmail() string {
+	if r.UserEmail != nil && *r.UserEmail != "" {
+		return *r.UserEmail
+	}
+	if r.ContactEmail != nil {
+		return *r.ContactEmail
+	}
+	return ""
+}
+
+// SetIncentive records whether the review was collected with an incentive. Loyalty points and
+// discounts are queued for issuance by marketing-service once the review is approved.
+func (r *Review) SetIncentive(incentivized bool, incentiveType *IncentiveType, value *float64) {
+	r.Incentivized = incentivized
+	r.IncentiveType = nil
+	r.IncentiveValue = nil
+	r.IncentiveStatus = ""
+	if !incentivized {
+		return
+	}
+	r.IncentiveType = incentiveType
+	r.IncentiveValue = value
+	if incentiveType != nil && incentiveType.Issuable() {
+		r.IncentiveStatus = IncentiveStatusPending
+	}
+}
+
+// DiscloseIncentive prepares the review for the storefront: incentivized reviews get a
+// disclosure label, and how the incentive was issued is left out
+func (r *Review) DiscloseIncentive() {
+	if !r.Incentivized {
+		return
+	}
+	label := defaultIncentiveDisclosure
+	if r.IncentiveType != nil {
+		if typed, ok := incentiveDisclosures[*r.IncentiveType]; ok {
+			label = typed
+		}
+	}
+	r.Disclosure = &label
+	r.IncentiveValue = nil
+	r.IncentiveStatus = ""
+	r.IncentiveRef = nil
+	r.IncentiveIssued = nil
+}
+
+// ReviewResponseTemplate is a reusable reply for responding to reviews. Templates without a
+// vendor are shared across the tenant; vendor templates are only visible to that vendor's staff.
+type ReviewResponseTemplate struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID   string    `json:"tenantId" gorm:"not null;index:idx_response_templates_tenant_vendor"`
+	VendorID   string    `json:"vendorId,omitempty" gorm:"index:idx_response_templates_tenant_vendor"`
+	Name       string    `json:"name" gorm:"not null"`
+	Content    string    `json:"content" gorm:"type:text;not null"`
+	Category   *string   `json:"category,omitempty"` // e.g. positive, negative, shipping
+	IsActive   bool      `json:"isActive" gorm:"default:true"`
+	UsageCount int       `json:"usageCount" gorm:"default:0"`
+	CreatedBy  *string   `json:"createdBy,omitempty"`
+	UpdatedBy  *string   `json:"updatedBy,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// TableName returns the table name for the ReviewResponseTemplate model
+func (ReviewResponseTemplate) TableName() string {
+	return "review_response_templates"
+}
+
+// Render fills the template's placeholders from the review:
+// {{customerName}}, {{reviewTitle}} and {{rating}}
+func (t *ReviewResponseTemplate) Render(review *Review, rating int) string {
+	customerName := "there"
+	if review.UserName != nil && strings.TrimSpace(*review.UserName) != "" {
+		customerName = strings.TrimSpace(*review.UserName)
+	}
+	reviewTitle := ""
+	if review.Title != nil {
+		reviewTitle = *review.Title
+	}
+	ratingText := ""
+	if rating > 0 {
+		ratingText = strconv.Itoa(rating)
+	}
+
+	return strings.NewReplacer(
+		"{{customerName}}", customerName,
+		"{{reviewTitle}}", reviewTitle,
+		"{{rating}}", ratingText,
+	).Replace(t.Content)
+}
+
+// CreateResponseTemplateRequest represents a request to create a response template
+type CreateResponseTemplateRequest struct {
+	Name     string  `json:"name" binding:"required,max=255"`
+	Content  string  `json:"content" binding:"required"`
+	Category *string `json:"category,omitempty"`
+	VendorID string  `json:"vendorId,omitempty"` // Tenant-level staff only; vendor staff always create for their vendor
+}
+
+// UpdateResponseTemplateRequest represents a request to update a response template
+type UpdateResponseTemplateRequest struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,max=255"`
+	Content  *string `json:"content,omitempty"`
+	Category *string `json:"category,omitempty"`
+	IsActive *bool   `json:"isActive,omitempty"`
+}
+
+// ResponseTemplateResponse represents a single response template response
+type ResponseTemplateResponse struct {
+	Success bool                    `json:"success"`
+	Data    *ReviewResponseTemplate `json:"data"`
+}
+
+// ResponseTemplateListResponse represents a list of response templates response
+type ResponseTemplateListResponse struct {
+	Success bool                     `json:"success"`
+	Data    []ReviewResponseTemplate `json:"data"`
+}
+
 // ReviewReaction represents a user's reaction to a review (stored in database)
 // Unique constraint on (review_id, user_id) ensures one reaction per user per review
 type ReviewReaction struct {
@@ -441,3 +814,32 @@ type UserReactionInfo struct {
 	HasReacted   bool         `json:"hasReacted"`
 	ReactionType ReactionType `json:"reactionType,omitempty"`
 }
+
+// ReviewImportRowError describes a row of a review import that wasn't imported
+type ReviewImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ReviewImportResult summarizes a review import
+type ReviewImportResult struct {
+	Platform       string                 `json:"platform"`
+	TotalRows      int                    `json:"totalRows"`
+	ImportedCount  int                    `json:"importedCount"`
+	DuplicateCount int                    `json:"duplicateCount"` // Already imported, or repeated in the file
+	FailedCount    int                    `json:"failedCount"`
+	MediaImported  int                    `json:"mediaImported"`
+	MediaFailed    int                    `json:"mediaFailed"` // Kept as links to the source platform
+	ValidateOnly   bool                   `json:"validateOnly"`
+	Errors         []ReviewImportRowError `json:"errors,omitempty"`
+	Warnings       []ReviewImportRowError `json:"warnings,omitempty"`
+	ImportedIDs    []string               `json:"importedIds,omitempty"`
+}
+
+// ReviewImportResponse represents a review import response
+type ReviewImportResponse struct {
+	Success bool               `json:"success"`
+	Data    ReviewImportResult `json:"data"`
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -12,8 +13,12 @@ import (
 	"github.com/Tesseract-Nexus/go-shared/cache"
 	"reviews-service/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrCommentNotFound is returned when a review has no comment with the given ID
+var ErrCommentNotFound = errors.New("comment not found")
+
 // Cache TTL constants for reviews
 const (
 	ReviewCacheTTL       = 15 * time.Minute // Individual review
@@ -94,6 +99,58 @@ func (r *ReviewsRepository) CreateReview(tenantID string, review *models.Review)
 	return err
 }
 
+// ImportReview creates a review imported from another platform, keeping its original
+// created and published dates. It returns false without error when the tenant already has a
+// review with the same external source and ID.
+func (r *ReviewsRepository) ImportReview(tenantID string, review *models.Review) (bool, error) {
+	review.TenantID = tenantID
+	if review.CreatedAt.IsZero() {
+		review.CreatedAt = time.Now()
+	}
+	review.UpdatedAt = time.Now()
+
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(review)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetExistingExternalIDs returns which of the external IDs the tenant has already imported
+// from the source platform
+func (r *ReviewsRepository) GetExistingExternalIDs(tenantID, source string, externalIDs []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	const batchSize = 1000
+	for start := 0; start < len(externalIDs); start += batchSize {
+		end := start + batchSize
+		if end > len(externalIDs) {
+			end = len(externalIDs)
+		}
+
+		var ids []string
+		err := r.db.Model(&models.Review{}).Unscoped().
+			Where("tenant_id = ? AND external_source = ? AND external_id IN ?", tenantID, source, externalIDs[start:end]).
+			Pluck("external_id", &ids).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+// InvalidateTenantCaches drops the tenant's cached review lists and stats after a bulk change
+func (r *ReviewsRepository) InvalidateTenantCaches(tenantID string) {
+	if r.cache == nil {
+		return
+	}
+	ctx := context.Background()
+	_ = r.cache.DeletePattern(ctx, fmt.Sprintf("review:list:%s:*", tenantID))
+	_ = r.cache.DeletePattern(ctx, fmt.Sprintf("review:stats:%s:*", tenantID))
+}
+
 // GetReviewByID retrieves a review by ID (with caching)
 func (r *ReviewsRepository) GetReviewByID(tenantID string, reviewID uuid.UUID) (*models.Review, error) {
 	ctx := context.Background()
@@ -237,6 +294,10 @@ func (r *ReviewsRepository) applyFilters(query *gorm.DB, req *models.SearchRevie
 		query = query.Where("featured = ?", *req.Featured)
 	}
 
+	if req.Incentive != nil {
+		query = query.Where("incentivized = ?", *req.Incentive)
+	}
+
 	if req.MinRating != nil {
 		// This assumes we have a computed average rating field or need to use JSON queries
 		query = query.Where("(ratings->>'average')::float >= ?", *req.MinRating)
@@ -374,6 +435,175 @@ func (r *ReviewsRepository) GetReviewsAnalytics(tenantID string, dateFrom, dateT
 	return &analytics, nil
 }
 
+// vendorScoredReviewsCTE selects a vendor's approved reviews with a per-review average
+// of the multi-aspect JSONB ratings ({"aspect": {"score": n, "maxScore": m}})
+const vendorScoredReviewsCTE = `WITH scored AS (
+	SELECT r.created_at, r.target_id, r.target_type,
+		(SELECT AVG((value->>'score')::float) FROM jsonb_each(COALESCE(r.ratings, '{}'::jsonb))) AS rating
+	FROM reviews r
+	WHERE r.tenant_id = ? AND r.vendor_id = ? AND r.status = ? AND r.deleted_at IS NULL
+		AND r.created_at >= ? AND r.created_at < ?
+) `
+
+// GetVendorRatingTrends aggregates approved review ratings for a vendor over [dateFrom, dateTo)
+func (r *ReviewsRepository) GetVendorRatingTrends(tenantID, vendorID string, dateFrom, dateTo time.Time) (*models.VendorRatingTrends, error) {
+	args := []interface{}{tenantID, vendorID, models.ReviewStatusApproved, dateFrom, dateTo}
+	trends := &models.VendorRatingTrends{
+		VendorID: vendorID,
+		ByRating: make(map[string]int),
+		Daily:    []models.TrendData{},
+		TopRated: []models.TopReviewedItem{},
+	}
+
+	var overview struct {
+		Total         int
+		AverageRating float64
+	}
+	if err := r.db.Raw(vendorScoredReviewsCTE+`SELECT COUNT(*) AS total, COALESCE(AVG(rating), 0) AS average_rating FROM scored`, args...).
+		Scan(&overview).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute vendor rating overview: %w", err)
+	}
+	trends.TotalReviews = overview.Total
+	trends.AverageRating = overview.AverageRating
+
+	if err := r.db.Raw(vendorScoredReviewsCTE+`SELECT to_char(date_trunc('day', created_at), 'YYYY-MM-DD') AS date,
+		COUNT(*) AS count, COALESCE(AVG(rating), 0) AS average_rating
+		FROM scored GROUP BY 1 ORDER BY 1`, args...).
+		Scan(&trends.Daily).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute vendor rating trend: %w", err)
+	}
+
+	var buckets []struct {
+		Bucket int
+		Count  int
+	}
+	if err := r.db.Raw(vendorScoredReviewsCTE+`SELECT ROUND(rating)::int AS bucket, COUNT(*) AS count
+		FROM scored WHERE rating IS NOT NULL GROUP BY 1`, args...).
+		Scan(&buckets).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute vendor rating distribution: %w", err)
+	}
+	for _, b := range buckets {
+		trends.ByRating[fmt.Sprintf("%d", b.Bucket)] = b.Count
+	}
+
+	if err := r.db.Raw(vendorScoredReviewsCTE+`SELECT target_id, target_type, COUNT(*) AS review_count, AVG(rating) AS average_rating
+		FROM scored WHERE rating IS NOT NULL
+		GROUP BY target_id, target_type
+		ORDER BY average_rating DESC, review_count DESC
+		LIMIT 5`, args...).
+		Scan(&trends.TopRated).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute vendor top rated items: %w", err)
+	}
+
+	return trends, nil
+}
+
+// productScoredReviewsCTE selects approved reviews of the given products with a per-review
+// average of the multi-aspect JSONB ratings
+const productScoredReviewsCTE = `WITH scored AS (
+	SELECT r.id, r.target_id, r.title, r.content, r.helpful_count, r.verified_purchase, r.created_at,
+		(SELECT AVG((value->>'score')::float) FROM jsonb_each(COALESCE(r.ratings, '{}'::jsonb))) AS rating
+	FROM reviews r
+	WHERE r.tenant_id = ? AND r.target_id IN ? AND r.type = ? AND r.status = ? AND r.deleted_at IS NULL
+) `
+
+// reviewExcerptLength caps review snippet excerpts, in characters
+const reviewExcerptLength = 280
+
+// GetProductReviewStats aggregates approved review ratings for a batch of products, with up to
+// snippets of each product's most helpful reviews
+func (r *ReviewsRepository) GetProductReviewStats(tenantID string, productIDs []string, snippets int) ([]models.ProductReviewStats, error) {
+	args := []interface{}{tenantID, productIDs, models.ReviewTypeProduct, models.ReviewStatusApproved}
+
+	var overviews []struct {
+		TargetID      string
+		ReviewCount   int
+		AverageRating float64
+	}
+	if err := r.db.Raw(productScoredReviewsCTE+`SELECT target_id, COUNT(*) AS review_count, COALESCE(AVG(rating), 0) AS average_rating
+		FROM scored GROUP BY target_id ORDER BY target_id`, args...).
+		Scan(&overviews).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute product review overview: %w", err)
+	}
+
+	stats := make([]models.ProductReviewStats, len(overviews))
+	byProduct := make(map[string]*models.ProductReviewStats, len(overviews))
+	for i, o := range overviews {
+		stats[i] = models.ProductReviewStats{
+			ProductID:     o.TargetID,
+			ReviewCount:   o.ReviewCount,
+			AverageRating: o.AverageRating,
+			ByRating:      make(map[string]int),
+		}
+		byProduct[o.TargetID] = &stats[i]
+	}
+	if len(stats) == 0 {
+		return stats, nil
+	}
+
+	var buckets []struct {
+		TargetID string
+		Bucket   int
+		Count    int
+	}
+	if err := r.db.Raw(productScoredReviewsCTE+`SELECT target_id, ROUND(rating)::int AS bucket, COUNT(*) AS count
+		FROM scored WHERE rating IS NOT NULL GROUP BY 1, 2`, args...).
+		Scan(&buckets).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute product rating distribution: %w", err)
+	}
+	for _, b := range buckets {
+		if s, ok := byProduct[b.TargetID]; ok {
+			s.ByRating[fmt.Sprintf("%d", b.Bucket)] = b.Count
+		}
+	}
+
+	if snippets <= 0 {
+		return stats, nil
+	}
+
+	var rows []struct {
+		ID               uuid.UUID
+		TargetID         string
+		Title            *string
+		Content          string
+		HelpfulCount     int
+		VerifiedPurchase bool
+		CreatedAt        time.Time
+		Rating           *float64
+	}
+	if err := r.db.Raw(productScoredReviewsCTE+`SELECT id, target_id, title, content, helpful_count, verified_purchase, created_at, rating
+		FROM (
+			SELECT scored.*, ROW_NUMBER() OVER (PARTITION BY target_id ORDER BY helpful_count DESC, created_at DESC) AS rn
+			FROM scored
+		) ranked
+		WHERE rn <= ?
+		ORDER BY target_id, rn`, append(args, snippets)...).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load product review snippets: %w", err)
+	}
+	for _, row := range rows {
+		s, ok := byProduct[row.TargetID]
+		if !ok {
+			continue
+		}
+		excerpt := []rune(strings.TrimSpace(row.Content))
+		if len(excerpt) > reviewExcerptLength {
+			excerpt = append(excerpt[:reviewExcerptLength-3], []rune("...")...)
+		}
+		s.TopSnippets = append(s.TopSnippets, models.ReviewSnippet{
+			ReviewID:         row.ID,
+			Title:            row.Title,
+			Excerpt:          string(excerpt),
+			Rating:           row.Rating,
+			HelpfulCount:     row.HelpfulCount,
+			VerifiedPurchase: row.VerifiedPurchase,
+			CreatedAt:        row.CreatedAt,
+		})
+	}
+
+	return stats, nil
+}
+
 // FindSimilarReviews finds reviews similar to the given one (simplified version)
 func (r *ReviewsRepository) FindSimilarReviews(tenantID string, reviewID uuid.UUID, limit int) ([]models.Review, error) {
 	// Get the source review first
@@ -450,7 +680,7 @@ func (r *ReviewsRepository) AddComment(tenantID string, reviewID uuid.UUID, comm
 
 	// Add the new comment to the JSONB field
 	comments := *review.Comments
-	comments[comment.ID] = map[string]interface{}{
+	entry := map[string]interface{}{
 		"id":         comment.ID,
 		"userId":     comment.UserID,
 		"userName":   comment.UserName,
@@ -458,6 +688,13 @@ func (r *ReviewsRepository) AddComment(tenantID string, reviewID uuid.UUID, comm
 		"isInternal": comment.IsInternal,
 		"createdAt":  comment.CreatedAt.Format(time.RFC3339),
 	}
+	if comment.VendorID != "" {
+		entry["vendorId"] = comment.VendorID
+	}
+	if comment.TemplateID != nil {
+		entry["templateId"] = *comment.TemplateID
+	}
+	comments[comment.ID] = entry
 	review.Comments = &comments
 
 	// Update the review
@@ -472,6 +709,51 @@ func (r *ReviewsRepository) AddComment(tenantID string, reviewID uuid.UUID, comm
 	if err != nil {
 		return nil, err
 	}
+	r.invalidateReviewCaches(context.Background(), tenantID, reviewID)
+
+	return review, nil
+}
+
+// UpdateComment replaces a comment's content, keeping the previous content in its edit history
+func (r *ReviewsRepository) UpdateComment(tenantID string, reviewID uuid.UUID, commentID, content, editedBy string) (*models.Review, error) {
+	review, err := r.GetReviewByID(tenantID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Comments == nil {
+		return nil, ErrCommentNotFound
+	}
+
+	comments := *review.Comments
+	entry, ok := comments[commentID].(map[string]interface{})
+	if !ok {
+		return nil, ErrCommentNotFound
+	}
+
+	now := time.Now()
+	history, _ := entry["editHistory"].([]interface{})
+	previous, _ := entry["content"].(string)
+	entry["editHistory"] = append(history, map[string]interface{}{
+		"content":  previous,
+		"editedBy": editedBy,
+		"editedAt": now.Format(time.RFC3339),
+	})
+	entry["content"] = content
+	entry["updatedAt"] = now.Format(time.RFC3339)
+	comments[commentID] = entry
+	review.Comments = &comments
+
+	review.UpdatedAt = now
+	err = r.db.Model(&models.Review{}).
+		Where("tenant_id = ? AND id = ?", tenantID, reviewID).
+		Updates(map[string]interface{}{
+			"comments":   review.Comments,
+			"updated_at": review.UpdatedAt,
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+	r.invalidateReviewCaches(context.Background(), tenantID, reviewID)
 
 	return review, nil
 }
@@ -636,3 +918,353 @@ func (r *ReviewsRepository) GetReviewWithUserReaction(tenantID string, reviewID
 
 	return review, reactionInfo, nil
 }
+
+// SaveReviewAnalysis stores the sentiment pipeline's result on a review and replaces the
+// review's extracted topics
+func (r *ReviewsRepository) SaveReviewAnalysis(tenantID string, review *models.Review, analysis *models.ReviewAnalysis) error {
+	now := time.Now()
+	topicNames := make(models.StringArray, 0, len(analysis.Topics))
+	topics := make([]models.ReviewTopic, 0, len(analysis.Topics))
+	for _, t := range analysis.Topics {
+		topicNames = append(topicNames, t.Topic)
+		topics = append(topics, models.ReviewTopic{
+			TenantID:   tenantID,
+			ReviewID:   review.ID,
+			TargetID:   review.TargetID,
+			TargetType: review.TargetType,
+			VendorID:   review.VendorID,
+			Topic:      t.Topic,
+			Sentiment:  t.Sentiment,
+			Keywords:   t.Keywords,
+			CreatedAt:  now,
+		})
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Review{}).
+			Where("tenant_id = ? AND id = ?", tenantID, review.ID).
+			Updates(map[string]interface{}{
+				"sentiment_score": analysis.Score,
+				"sentiment_label": analysis.Label,
+				"topics":          topicNames,
+				"analyzed_at":     now,
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tenant_id = ? AND review_id = ?", tenantID, review.ID).
+			Delete(&models.ReviewTopic{}).Error; err != nil {
+			return err
+		}
+		if len(topics) == 0 {
+			return nil
+		}
+		return tx.Create(&topics).Error
+	})
+	if err == nil {
+		r.invalidateReviewCaches(context.Background(), tenantID, review.ID)
+	}
+	return err
+}
+
+// mentionKeywordsPerTheme caps the keywords listed for each theme in a mentions summary
+const mentionKeywordsPerTheme = 5
+
+// GetReviewMentions summarizes the sentiment of approved, analyzed reviews and the topics they
+// mention most, for one target or (with an empty targetID) the whole tenant
+func (r *ReviewsRepository) GetReviewMentions(tenantID, targetID string, limit int) (*models.ReviewMentions, error) {
+	filter := "r.tenant_id = ? AND r.status = ? AND r.deleted_at IS NULL"
+	args := []interface{}{tenantID, models.ReviewStatusApproved}
+	if targetID != "" {
+		filter += " AND r.target_id = ?"
+		args = append(args, targetID)
+	}
+
+	mentions := &models.ReviewMentions{
+		TargetID: targetID,
+		SentimentAnalysis: models.SentimentAnalysis{
+			Themes: []models.SentimentTheme{},
+		},
+	}
+
+	var overview struct {
+		Total    int
+		Positive int
+		Negative int
+		Neutral  int
+	}
+	if err := r.db.Raw(`SELECT COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE r.sentiment_label = ?) AS positive,
+		COUNT(*) FILTER (WHERE r.sentiment_label = ?) AS negative,
+		COUNT(*) FILTER (WHERE r.sentiment_label = ?) AS neutral
+		FROM reviews r WHERE `+filter+` AND r.analyzed_at IS NOT NULL`,
+		append([]interface{}{models.SentimentPositive, models.SentimentNegative, models.SentimentNeutral}, args...)...).
+		Scan(&overview).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute review sentiment overview: %w", err)
+	}
+	mentions.AnalyzedReviews = overview.Total
+	if overview.Total == 0 {
+		return mentions, nil
+	}
+	mentions.Positive = float64(overview.Positive) / float64(overview.Total)
+	mentions.Negative = float64(overview.Negative) / float64(overview.Total)
+	mentions.Neutral = float64(overview.Neutral) / float64(overview.Total)
+
+	if err := r.db.Raw(`SELECT t.topic AS theme, COUNT(DISTINCT t.review_id) AS frequency, AVG(t.sentiment) AS sentiment,
+		COUNT(*) FILTER (WHERE t.sentiment >= ?) AS positive,
+		COUNT(*) FILTER (WHERE t.sentiment <= ?) AS negative
+		FROM review_topics t JOIN reviews r ON r.id = t.review_id
+		WHERE `+filter+`
+		GROUP BY t.topic
+		ORDER BY frequency DESC, theme
+		LIMIT ?`,
+		append(append([]interface{}{models.SentimentLabelThreshold, -models.SentimentLabelThreshold}, args...), limit)...).
+		Scan(&mentions.Themes).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute review themes: %w", err)
+	}
+	if len(mentions.Themes) == 0 {
+		return mentions, nil
+	}
+
+	themeNames := make([]string, len(mentions.Themes))
+	byTheme := make(map[string]*models.SentimentTheme, len(mentions.Themes))
+	for i := range mentions.Themes {
+		themeNames[i] = mentions.Themes[i].Theme
+		mentions.Themes[i].Keywords = []string{}
+		byTheme[mentions.Themes[i].Theme] = &mentions.Themes[i]
+	}
+
+	var keywords []struct {
+		Topic   string
+		Keyword string
+		Count   int
+	}
+	if err := r.db.Raw(`SELECT t.topic, kw.keyword, COUNT(*) AS count
+		FROM review_topics t JOIN reviews r ON r.id = t.review_id,
+			jsonb_array_elements_text(COALESCE(t.keywords, '[]'::jsonb)) AS kw(keyword)
+		WHERE `+filter+` AND t.topic IN ?
+		GROUP BY t.topic, kw.keyword
+		ORDER BY count DESC, kw.keyword`,
+		append(args, themeNames)...).
+		Scan(&keywords).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute review theme keywords: %w", err)
+	}
+	for _, k := range keywords {
+		if theme, ok := byTheme[k.Topic]; ok && len(theme.Keywords) < mentionKeywordsPerTheme {
+			theme.Keywords = append(theme.Keywords, k.Keyword)
+		}
+	}
+
+	return mentions, nil
+}
+
+// ListResponseTemplates returns a tenant's response templates. With a vendorID, only the
+// tenant-wide templates and that vendor's own are returned.
+func (r *ReviewsRepository) ListResponseTemplates(tenantID, vendorID string, activeOnly bool) ([]models.ReviewResponseTemplate, error) {
+	query := r.db.Where("tenant_id = ?", tenantID)
+	if vendorID != "" {
+		query = query.Where("(vendor_id IS NULL OR vendor_id = '' OR vendor_id = ?)", vendorID)
+	}
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var templates []models.ReviewResponseTemplate
+	err := query.Order("usage_count DESC, name").Find(&templates).Error
+	return templates, err
+}
+
+// GetResponseTemplate retrieves a response template by ID
+func (r *ReviewsRepository) GetResponseTemplate(tenantID string, templateID uuid.UUID) (*models.ReviewResponseTemplate, error) {
+	var template models.ReviewResponseTemplate
+	if err := r.db.Where("tenant_id = ? AND id = ?", tenantID, templateID).First(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// CreateResponseTemplate creates a response template
+func (r *ReviewsRepository) CreateResponseTemplate(template *models.ReviewResponseTemplate) error {
+	return r.db.Create(template).Error
+}
+
+// UpdateResponseTemplate saves changes to a response template
+func (r *ReviewsRepository) UpdateResponseTemplate(template *models.ReviewResponseTemplate) error {
+	return r.db.Save(template).Error
+}
+
+// DeleteResponseTemplate deletes a response template
+func (r *ReviewsRepository) DeleteResponseTemplate(tenantID string, templateID uuid.UUID) error {
+	return r.db.Where("tenant_id = ? AND id = ?", tenantID, templateID).
+		Delete(&models.ReviewResponseTemplate{}).Error
+}
+
+// IncrementTemplateUsage counts a response sent from a template
+func (r *ReviewsRepository) IncrementTemplateUsage(tenantID string, templateID uuid.UUID) error {
+	return r.db.Model(&models.ReviewResponseTemplate{}).
+		Where("tenant_id = ? AND id = ?", tenantID, templateID).
+		UpdateColumn("usage_count", gorm.Expr("usage_count + ?", 1)).Error
+}
+
+// UpdateReviewIncentive saves the incentive fields of a review, including cleared ones
+func (r *ReviewsRepository) UpdateReviewIncentive(tenantID string, review *models.Review) error {
+	err := r.db.Model(&models.Review{}).
+		Where("tenant_id = ? AND id = ?", tenantID, review.ID).
+		Select("incentivized", "incentive_type", "incentive_value", "incentive_status", "updated_at").
+		Updates(map[string]interface{}{
+			"incentivized":     review.Incentivized,
+			"incentive_type":   review.IncentiveType,
+			"incentive_value":  review.IncentiveValue,
+			"incentive_status": review.IncentiveStatus,
+			"updated_at":       time.Now(),
+		}).Error
+	if err == nil {
+		r.invalidateReviewCaches(context.Background(), tenantID, review.ID)
+	}
+	return err
+}
+
+// GetUnissuedIncentiveReviews returns the approved reviews among reviewIDs whose incentive is
+// still pending or failed to issue
+func (r *ReviewsRepository) GetUnissuedIncentiveReviews(tenantID string, reviewIDs []uuid.UUID) ([]models.Review, error) {
+	var reviews []models.Review
+	err := r.db.Where("tenant_id = ? AND id IN ? AND status = ? AND incentivized = ? AND incentive_status IN ?",
+		tenantID, reviewIDs, models.ReviewStatusApproved, true,
+		[]models.IncentiveStatus{models.IncentiveStatusPending, models.IncentiveStatusFailed}).
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// UpdateIncentiveStatus records the outcome of issuing a review's incentive
+func (r *ReviewsRepository) UpdateIncentiveStatus(tenantID string, reviewID uuid.UUID, status models.IncentiveStatus, reference string) error {
+	updates := map[string]interface{}{
+		"incentive_status": status,
+	}
+	if status == models.IncentiveStatusIssued {
+		updates["incentive_ref"] = reference
+		updates["incentive_issued_at"] = time.Now()
+	}
+
+	err := r.db.Model(&models.Review{}).
+		Where("tenant_id = ? AND id = ?", tenantID, reviewID).
+		UpdateColumns(updates).Error
+	if err == nil {
+		r.invalidateReviewCaches(context.Background(), tenantID, reviewID)
+	}
+	return err
+}
+
+// incentiveScoredReviewsCTE selects approved reviews with a per-review average of the
+// multi-aspect JSONB ratings. The %s placeholder takes additional filters.
+const incentiveScoredReviewsCTE = `WITH scored AS (
+	SELECT r.incentivized, r.incentive_type, r.incentive_status,
+		(SELECT AVG((value->>'score')::float) FROM jsonb_each(COALESCE(r.ratings, '{}'::jsonb))) AS rating
+	FROM reviews r
+	WHERE r.tenant_id = ? AND r.status = ? AND r.deleted_at IS NULL%s
+) `
+
+// GetIncentiveReport compares approved incentivized and organic review ratings, optionally for a
+// single vendor or target and a creation date range
+func (r *ReviewsRepository) GetIncentiveReport(tenantID, vendorID, targetID string, dateFrom, dateTo *time.Time) (*models.IncentiveReport, error) {
+	filter := ""
+	args := []interface{}{tenantID, models.ReviewStatusApproved}
+	if vendorID != "" {
+		filter += " AND r.vendor_id = ?"
+		args = append(args, vendorID)
+	}
+	if targetID != "" {
+		filter += " AND r.target_id = ?"
+		args = append(args, targetID)
+	}
+	if dateFrom != nil {
+		filter += " AND r.created_at >= ?"
+		args = append(args, *dateFrom)
+	}
+	if dateTo != nil {
+		filter += " AND r.created_at <= ?"
+		args = append(args, *dateTo)
+	}
+	cte := fmt.Sprintf(incentiveScoredReviewsCTE, filter)
+
+	report := &models.IncentiveReport{
+		VendorID:        vendorID,
+		TargetID:        targetID,
+		Organic:         models.RatingSegment{ByRating: make(map[string]int)},
+		Incentivized:    models.RatingSegment{ByRating: make(map[string]int)},
+		ByIncentiveType: []models.IncentiveTypeRating{},
+		Issuance:        make(map[string]int),
+	}
+	segment := func(incentivized bool) *models.RatingSegment {
+		if incentivized {
+			return &report.Incentivized
+		}
+		return &report.Organic
+	}
+
+	var overview []struct {
+		Incentivized  bool
+		ReviewCount   int
+		AverageRating float64
+	}
+	if err := r.db.Raw(cte+`SELECT incentivized, COUNT(*) AS review_count, COALESCE(AVG(rating), 0) AS average_rating
+		FROM scored GROUP BY incentivized`, args...).
+		Scan(&overview).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute incentive rating overview: %w", err)
+	}
+	for _, o := range overview {
+		seg := segment(o.Incentivized)
+		seg.ReviewCount = o.ReviewCount
+		seg.AverageRating = o.AverageRating
+	}
+
+	var buckets []struct {
+		Incentivized bool
+		Bucket       int
+		Count        int
+	}
+	if err := r.db.Raw(cte+`SELECT incentivized, ROUND(rating)::int AS bucket, COUNT(*) AS count
+		FROM scored WHERE rating IS NOT NULL GROUP BY 1, 2`, args...).
+		Scan(&buckets).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute incentive rating distribution: %w", err)
+	}
+	ratedCount := map[bool]int{}
+	for _, b := range buckets {
+		segment(b.Incentivized).ByRating[fmt.Sprintf("%d", b.Bucket)] = b.Count
+		ratedCount[b.Incentivized] += b.Count
+	}
+
+	total := report.Organic.ReviewCount + report.Incentivized.ReviewCount
+	if total > 0 {
+		report.IncentivizedShare = float64(report.Incentivized.ReviewCount) / float64(total)
+	}
+	if ratedCount[true] > 0 && ratedCount[false] > 0 {
+		report.RatingGap = report.Incentivized.AverageRating - report.Organic.AverageRating
+	}
+	if report.Incentivized.ReviewCount == 0 {
+		return report, nil
+	}
+
+	if err := r.db.Raw(cte+`SELECT COALESCE(incentive_type, ?) AS incentive_type, COUNT(*) AS review_count,
+		COALESCE(AVG(rating), 0) AS average_rating
+		FROM scored WHERE incentivized
+		GROUP BY 1 ORDER BY review_count DESC, incentive_type`,
+		append(append([]interface{}{}, args...), models.IncentiveOther)...).
+		Scan(&report.ByIncentiveType).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute incentive type breakdown: %w", err)
+	}
+
+	var issuance []struct {
+		IncentiveStatus string
+		Count           int
+	}
+	if err := r.db.Raw(cte+`SELECT incentive_status, COUNT(*) AS count
+		FROM scored WHERE incentivized AND COALESCE(incentive_status, '') <> ''
+		GROUP BY 1`, args...).
+		Scan(&issuance).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute incentive issuance: %w", err)
+	}
+	for _, i := range issuance {
+		report.Issuance[i.IncentiveStatus] = i.Count
+	}
+
+	return report, nil
+}
@@ -0,0 +1,222 @@
+package sentiment
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"reviews-service/internal/models"
+)
+
+// LexiconProvider is the built-in, rule-based English sentiment provider. Each clause is scored
+// from a word list, with negation and intensifiers, and topics are matched from keywords.
+type LexiconProvider struct{}
+
+// NewLexiconProvider creates the built-in lexicon provider
+func NewLexiconProvider() *LexiconProvider {
+	return &LexiconProvider{}
+}
+
+// Name returns the provider name recorded with each analysis
+func (p *LexiconProvider) Name() string {
+	return "lexicon"
+}
+
+// clauseSplitter splits text into clauses that each carry one sentiment
+var clauseSplitter = regexp.MustCompile(`[.!?;\n]+|,?\s+(?:but|however|although|though|except)\s+`)
+
+// normalizeAlpha controls how quickly summed word scores approach ±1 (as in VADER)
+const normalizeAlpha = 15
+
+// negationWindow is how many words back a negator flips a sentiment word
+const negationWindow = 3
+
+var positiveWords = map[string]float64{
+	"good": 1.5, "great": 2, "excellent": 2.5, "amazing": 2.5, "awesome": 2.5, "fantastic": 2.5,
+	"love": 2.5, "loved": 2.5, "loves": 2.5, "perfect": 2.5, "perfectly": 2, "best": 2, "nice": 1.5,
+	"happy": 2, "pleased": 2, "satisfied": 1.5, "recommend": 2, "recommended": 2, "beautiful": 2,
+	"comfortable": 1.5, "sturdy": 1.5, "solid": 1.5, "durable": 1.5, "fast": 1, "quick": 1,
+	"easy": 1, "helpful": 1.5, "friendly": 1.5, "worth": 1.5, "cheap": 0.5, "affordable": 1.5,
+	"lovely": 2, "impressed": 2, "impressive": 2, "works": 1, "reliable": 1.5,
+	"exactly": 1, "superb": 2.5, "wonderful": 2.5, "fine": 0.5, "smooth": 1, "soft": 1,
+	"accurate": 1, "bargain": 1.5, "glad": 1.5, "enjoy": 1.5, "enjoyed": 1.5, "outstanding": 2.5,
+}
+
+var negativeWords = map[string]float64{
+	"bad": -1.5, "poor": -2, "terrible": -2.5, "awful": -2.5, "horrible": -2.5, "worst": -2.5,
+	"hate": -2.5, "hated": -2.5, "broken": -2, "broke": -2, "damaged": -2, "defective": -2.5,
+	"disappointed": -2, "disappointing": -2, "disappointment": -2, "useless": -2.5, "waste": -2,
+	"cheaply": -1.5, "flimsy": -2, "slow": -1.5, "late": -1.5, "delayed": -1.5, "missing": -1.5,
+	"wrong": -1.5, "rude": -2, "unhelpful": -2, "expensive": -1, "overpriced": -2, "uncomfortable": -2,
+	"small": -0.5, "tight": -1, "loose": -1, "refund": -1, "returned": -1,
+	"fake": -2.5, "scratched": -1.5, "leaked": -2, "leaking": -2, "stopped": -1.5, "fail": -2,
+	"failed": -2, "fails": -2, "faulty": -2, "problem": -1.5, "problems": -1.5, "issue": -1,
+	"issues": -1, "avoid": -2, "never": -0.5, "unfortunately": -1, "mediocre": -1.5, "meh": -1,
+	"annoying": -1.5, "difficult": -1, "confusing": -1.5, "smell": -1, "smells": -1, "ripped": -2,
+}
+
+var negators = map[string]bool{
+	"not": true, "no": true, "never": true, "dont": true, "didnt": true, "doesnt": true,
+	"isnt": true, "wasnt": true, "arent": true, "werent": true, "cant": true, "couldnt": true,
+	"wont": true, "wouldnt": true, "hardly": true, "barely": true, "nothing": true, "without": true,
+}
+
+var intensifiers = map[string]float64{
+	"very": 1.5, "really": 1.5, "extremely": 1.8, "super": 1.5, "so": 1.3, "incredibly": 1.8,
+	"absolutely": 1.8, "totally": 1.5, "highly": 1.5, "quite": 1.2, "too": 1.3, "completely": 1.6,
+	"slightly": 0.6, "somewhat": 0.7, "bit": 0.7,
+}
+
+// topicKeywords maps the words and two-word phrases customers use onto topics
+var topicKeywords = map[string]string{
+	"quality": "quality", "material": "quality", "materials": "quality", "fabric": "quality",
+	"build": "quality", "made": "quality", "craftsmanship": "quality", "stitching": "quality",
+	"price": "price", "value": "price", "cost": "price", "money": "price", "expensive": "price",
+	"cheap": "price", "overpriced": "price", "affordable": "price", "bargain": "price", "worth": "price",
+	"shipping": "shipping", "delivery": "shipping", "delivered": "shipping", "arrived": "shipping",
+	"courier": "shipping", "shipped": "shipping", "dispatch": "shipping", "late": "shipping",
+	"packaging": "packaging", "package": "packaging", "packaged": "packaging", "box": "packaging",
+	"packed": "packaging", "wrapping": "packaging",
+	"size": "size & fit", "fit": "size & fit", "fits": "size & fit", "sizing": "size & fit",
+	"small": "size & fit", "large": "size & fit", "tight": "size & fit", "loose": "size & fit",
+	"comfortable": "comfort", "uncomfortable": "comfort", "comfort": "comfort", "soft": "comfort",
+	"durable": "durability", "durability": "durability", "broke": "durability", "broken": "durability",
+	"lasted": "durability", "sturdy": "durability", "flimsy": "durability", "ripped": "durability",
+	"customer service": "customer service", "support": "customer service", "seller": "customer service",
+	"refund": "customer service", "staff": "customer service", "service": "customer service",
+	"easy": "ease of use", "difficult": "ease of use", "instructions": "ease of use", "setup": "ease of use",
+	"assemble": "ease of use", "assembly": "ease of use", "confusing": "ease of use",
+	"color": "appearance", "colour": "appearance", "look": "appearance", "looks": "appearance",
+	"design": "appearance", "beautiful": "appearance", "style": "appearance",
+	"performance": "performance", "works": "performance", "working": "performance", "speed": "performance",
+	"powerful": "performance", "stopped": "performance",
+	"battery": "battery", "charge": "battery", "charging": "battery", "charger": "battery",
+	"taste": "taste", "flavor": "taste", "flavour": "taste", "delicious": "taste",
+	"smell": "scent", "smells": "scent", "scent": "scent", "fragrance": "scent",
+}
+
+// Analyze scores English text. Other languages return ErrUnsupportedLanguage.
+func (p *LexiconProvider) Analyze(ctx context.Context, text, language string) (*models.ReviewAnalysis, error) {
+	if language != "" && !strings.HasPrefix(strings.ToLower(language), "en") {
+		return nil, ErrUnsupportedLanguage
+	}
+
+	type topicMention struct {
+		scores   []float64
+		keywords map[string]bool
+	}
+	topics := make(map[string]*topicMention)
+	var total float64
+
+	for _, clause := range clauseSplitter.Split(strings.ToLower(text), -1) {
+		words := tokenize(clause)
+		if len(words) == 0 {
+			continue
+		}
+		raw := scoreWords(words)
+		total += raw
+
+		// Each topic mentioned in the clause takes the clause's sentiment once
+		mentioned := make(map[string]bool)
+		for i := 0; i < len(words); i++ {
+			keyword, topic, ok := "", "", false
+			if i+1 < len(words) {
+				keyword = words[i] + " " + words[i+1]
+				if topic, ok = topicKeywords[keyword]; ok {
+					i++ // The phrase's second word isn't matched again
+				}
+			}
+			if !ok {
+				keyword = words[i]
+				topic, ok = topicKeywords[keyword]
+			}
+			if !ok {
+				continue
+			}
+			mention := topics[topic]
+			if mention == nil {
+				mention = &topicMention{keywords: make(map[string]bool)}
+				topics[topic] = mention
+			}
+			mention.keywords[keyword] = true
+			if !mentioned[topic] {
+				mentioned[topic] = true
+				mention.scores = append(mention.scores, normalize(raw))
+			}
+		}
+	}
+
+	analysis := &models.ReviewAnalysis{
+		Provider: p.Name(),
+		Score:    normalize(total),
+		Topics:   make([]models.ReviewTopicAnalysis, 0, len(topics)),
+	}
+	analysis.Label = models.SentimentLabelFor(analysis.Score)
+
+	for topic, mention := range topics {
+		var sum float64
+		for _, s := range mention.scores {
+			sum += s
+		}
+		keywords := make([]string, 0, len(mention.keywords))
+		for k := range mention.keywords {
+			keywords = append(keywords, k)
+		}
+		sort.Strings(keywords)
+		analysis.Topics = append(analysis.Topics, models.ReviewTopicAnalysis{
+			Topic:     topic,
+			Sentiment: math.Round(sum/float64(len(mention.scores))*1000) / 1000,
+			Keywords:  keywords,
+		})
+	}
+	sort.Slice(analysis.Topics, func(i, j int) bool { return analysis.Topics[i].Topic < analysis.Topics[j].Topic })
+
+	return analysis, nil
+}
+
+// scoreWords sums the sentiment of a clause's words, applying intensifiers and negation
+func scoreWords(words []string) float64 {
+	var score float64
+	for i, word := range words {
+		value, ok := positiveWords[word]
+		if !ok {
+			value, ok = negativeWords[word]
+		}
+		if !ok {
+			continue
+		}
+
+		if i > 0 {
+			if boost, ok := intensifiers[words[i-1]]; ok {
+				value *= boost
+			}
+		}
+		for j := i - 1; j >= 0 && j >= i-negationWindow; j-- {
+			if negators[words[j]] {
+				value *= -0.75
+				break
+			}
+		}
+		score += value
+	}
+	return score
+}
+
+// tokenize splits a clause into lowercase words, dropping apostrophes so "don't" matches "dont"
+func tokenize(clause string) []string {
+	clause = strings.NewReplacer("'", "", "’", "").Replace(clause)
+	return strings.FieldsFunc(clause, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalize squashes a summed score into -1..1
+func normalize(score float64) float64 {
+	if score == 0 {
+		return 0
+	}
+	return math.Round(score/math.Sqrt(score*score+normalizeAlpha)*1000) / 1000
+}
@@ -0,0 +1,123 @@
+package sentiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"reviews-service/internal/models"
+)
+
+// MLProvider analyzes reviews with the ML service
+type MLProvider struct {
+	baseURL    string
+	httpClient *http.Client
+	fallback   Provider
+}
+
+// mlSentimentRequest is the ML service's sentiment request format
+type mlSentimentRequest struct {
+	Text     string `json:"text"`
+	Language string `json:"language,omitempty"`
+}
+
+// mlSentimentResponse is the ML service's sentiment response format
+type mlSentimentResponse struct {
+	Score  float64 `json:"score"`
+	Topics []struct {
+		Topic     string   `json:"topic"`
+		Sentiment float64  `json:"sentiment"`
+		Keywords  []string `json:"keywords"`
+	} `json:"topics"`
+}
+
+// NewMLProvider creates a provider backed by the ML service. fallback, if set, is used when
+// the ML service can't be reached or returns an error.
+func NewMLProvider(baseURL string, fallback Provider) *MLProvider {
+	return &MLProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		fallback: fallback,
+	}
+}
+
+// Name returns the provider name recorded with each analysis
+func (p *MLProvider) Name() string {
+	return "ml"
+}
+
+// Analyze scores the text with the ML service
+func (p *MLProvider) Analyze(ctx context.Context, text, language string) (*models.ReviewAnalysis, error) {
+	analysis, err := p.analyze(ctx, text, language)
+	if err != nil && p.fallback != nil {
+		log.Printf("[SENTIMENT] ML service analysis failed, using %s provider: %v", p.fallback.Name(), err)
+		return p.fallback.Analyze(ctx, text, language)
+	}
+	return analysis, err
+}
+
+func (p *MLProvider) analyze(ctx context.Context, text, language string) (*models.ReviewAnalysis, error) {
+	body, err := json.Marshal(mlSentimentRequest{Text: text, Language: language})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sentiment request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/api/v1/sentiment", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sentiment request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ML service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return nil, ErrUnsupportedLanguage
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ML service returned status %d", resp.StatusCode)
+	}
+
+	var result mlSentimentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode sentiment response: %w", err)
+	}
+
+	analysis := &models.ReviewAnalysis{
+		Provider: p.Name(),
+		Score:    clampScore(result.Score),
+		Topics:   make([]models.ReviewTopicAnalysis, 0, len(result.Topics)),
+	}
+	analysis.Label = models.SentimentLabelFor(analysis.Score)
+	for _, t := range result.Topics {
+		if strings.TrimSpace(t.Topic) == "" {
+			continue
+		}
+		analysis.Topics = append(analysis.Topics, models.ReviewTopicAnalysis{
+			Topic:     strings.ToLower(strings.TrimSpace(t.Topic)),
+			Sentiment: clampScore(t.Sentiment),
+			Keywords:  t.Keywords,
+		})
+	}
+	return analysis, nil
+}
+
+func clampScore(score float64) float64 {
+	if score > 1 {
+		return 1
+	}
+	if score < -1 {
+		return -1
+	}
+	return score
+}
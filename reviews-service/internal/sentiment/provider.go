@@ -0,0 +1,44 @@
+package sentiment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"reviews-service/internal/models"
+)
+
+// ErrUnsupportedLanguage is returned when a provider can't analyze text in the review's language
+var ErrUnsupportedLanguage = errors.New("language not supported by sentiment provider")
+
+// Provider scores a review's text and extracts the topics it mentions
+type Provider interface {
+	Name() string
+	Analyze(ctx context.Context, text, language string) (*models.ReviewAnalysis, error)
+}
+
+// NewProvider returns the named provider, or nil when sentiment analysis is turned off.
+// The ML provider falls back to the built-in lexicon when the ML service is unavailable.
+func NewProvider(name, mlServiceURL string) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "lexicon":
+		return NewLexiconProvider(), nil
+	case "ml":
+		if mlServiceURL == "" {
+			return nil, fmt.Errorf("ML_SERVICE_URL is required for the ml sentiment provider")
+		}
+		return NewMLProvider(mlServiceURL, NewLexiconProvider()), nil
+	case "none", "off", "disabled":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown sentiment provider %q", name)
+}
+
+// ReviewText is the text analyzed for a review: its title, then its content
+func ReviewText(review *models.Review) string {
+	if review.Title == nil || strings.TrimSpace(*review.Title) == "" {
+		return review.Content
+	}
+	return strings.TrimSpace(*review.Title) + ". " + review.Content
+}
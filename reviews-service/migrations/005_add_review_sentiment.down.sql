@@ -0,0 +1,6 @@
+-- Rollback: Remove the sentiment pipeline's columns and topics
+
+DROP TABLE IF EXISTS review_topics;
+ALTER TABLE reviews DROP COLUMN IF EXISTS analyzed_at;
+ALTER TABLE reviews DROP COLUMN IF EXISTS topics;
+ALTER TABLE reviews DROP COLUMN IF EXISTS sentiment_label;
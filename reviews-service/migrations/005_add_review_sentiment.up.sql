@@ -0,0 +1,24 @@
+-- Sentiment pipeline: per-review sentiment label and topics, plus extracted topics for
+-- per-product "what customers mention" summaries
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS sentiment_label VARCHAR(20);
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS topics JSONB;
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS analyzed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS review_topics (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    target_id VARCHAR(255) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    vendor_id VARCHAR(255),
+    topic VARCHAR(100) NOT NULL,
+    sentiment DOUBLE PRECISION NOT NULL DEFAULT 0,
+    keywords JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_topics_tenant_target ON review_topics(tenant_id, target_id);
+CREATE INDEX IF NOT EXISTS idx_review_topics_review_id ON review_topics(review_id);
+CREATE INDEX IF NOT EXISTS idx_review_topics_vendor_id ON review_topics(vendor_id);
+CREATE INDEX IF NOT EXISTS idx_review_topics_topic ON review_topics(topic);
@@ -0,0 +1,3 @@
+ALTER TABLE reviews DROP COLUMN IF EXISTS contact_email;
+
+DROP TABLE IF EXISTS review_response_templates;
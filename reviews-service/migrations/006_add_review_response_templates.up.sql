@@ -0,0 +1,21 @@
+-- Review response templates (tenant-wide or per vendor), plus the reviewer's contact email
+-- so customers can be notified when a review gets a response
+
+CREATE TABLE IF NOT EXISTS review_response_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    vendor_id VARCHAR(255),
+    name VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    category VARCHAR(100),
+    is_active BOOLEAN DEFAULT true,
+    usage_count INTEGER DEFAULT 0,
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_response_templates_tenant_vendor ON review_response_templates(tenant_id, vendor_id);
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS contact_email VARCHAR(255);
@@ -0,0 +1,5 @@
+-- Rollback: Remove imported review tracking
+
+DROP INDEX IF EXISTS idx_reviews_external;
+ALTER TABLE reviews DROP COLUMN IF EXISTS external_id;
+ALTER TABLE reviews DROP COLUMN IF EXISTS external_source;
@@ -0,0 +1,7 @@
+-- Reviews imported from other platforms (Shopify, Amazon, Judge.me) keep their source and
+-- external ID, so re-running an import skips reviews that were already brought over
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS external_source VARCHAR(50);
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS external_id VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_external ON reviews(tenant_id, external_source, external_id);
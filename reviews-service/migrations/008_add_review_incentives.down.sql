@@ -0,0 +1,9 @@
+-- Rollback: Remove incentivized review tracking
+
+DROP INDEX IF EXISTS idx_reviews_incentivized;
+ALTER TABLE reviews DROP COLUMN IF EXISTS incentive_issued_at;
+ALTER TABLE reviews DROP COLUMN IF EXISTS incentive_ref;
+ALTER TABLE reviews DROP COLUMN IF EXISTS incentive_status;
+ALTER TABLE reviews DROP COLUMN IF EXISTS incentive_value;
+ALTER TABLE reviews DROP COLUMN IF EXISTS incentive_type;
+ALTER TABLE reviews DROP COLUMN IF EXISTS incentivized;
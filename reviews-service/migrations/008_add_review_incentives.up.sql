@@ -0,0 +1,11 @@
+-- Reviews collected in exchange for loyalty points, discounts or free products are flagged
+-- for storefront disclosure. Points and discounts are issued by marketing-service on approval.
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS incentivized BOOLEAN DEFAULT false;
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS incentive_type VARCHAR(20);
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS incentive_value DECIMAL(10,2);
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS incentive_status VARCHAR(20);
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS incentive_ref VARCHAR(255);
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS incentive_issued_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_reviews_incentivized ON reviews(incentivized);
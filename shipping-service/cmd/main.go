@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -19,11 +22,15 @@ import (
 	"shipping-service/internal/carriers"
 	"shipping-service/internal/events"
 	"shipping-service/internal/config"
+	"shipping-service/internal/database"
+	"shipping-service/internal/encryption"
 	"shipping-service/internal/handlers"
 	"shipping-service/internal/middleware"
 	"shipping-service/internal/models"
 	"shipping-service/internal/repository"
 	"shipping-service/internal/services"
+	"shipping-service/internal/subscribers"
+	"shipping-service/internal/tenancy"
 )
 
 func main() {
@@ -36,18 +43,38 @@ func main() {
 	}
 	log.Printf("Configuration loaded successfully")
 
-	// Connect to database
-	db, err := connectDatabase(cfg.GetDatabaseDSN())
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Server.Env == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return connectDatabase(cfg.GetDatabaseDSN()) })
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	log.Println("Database connected successfully")
 
-	// Run migrations
-	if err := runMigrations(db); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	// "shipping-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
+	}
+	log.Println("Database schema is up to date")
+
+	// Per-tenant encryption of carrier credentials (disabled, i.e. plaintext, until a key is configured)
+	keyring, err := initFieldEncryption(cfg, db)
+	if err != nil {
+		log.Fatalf("Failed to initialize field encryption: %v", err)
+	}
+	if keyring != nil {
+		defer keyring.Close()
+		log.Println("✓ Field encryption enabled")
+	} else {
+		log.Println("WARNING: FIELD_ENCRYPTION_KMS_KEY not set, carrier credentials are stored unencrypted")
 	}
-	log.Println("Database migrations completed")
 
 	// Seed carrier templates
 	if err := repository.SeedCarrierTemplates(db); err != nil {
@@ -170,6 +197,20 @@ func main() {
 	}
 	log.Println("Carrier selector service initialized")
 
+	// Keep the ship-from address book in sync with inventory-service warehouses
+	warehouseSubscriber, err := subscribers.NewWarehouseSubscriber(carrierSelectorService, eventLogger)
+	if err != nil {
+		log.Printf("WARNING: Failed to initialize warehouse subscriber: %v (ship-from locations won't sync from inventory)", err)
+	} else {
+		defer warehouseSubscriber.Stop()
+		go func() {
+			if err := warehouseSubscriber.Start(context.Background()); err != nil {
+				log.Printf("WARNING: Warehouse subscriber error: %v", err)
+			}
+		}()
+		log.Println("✓ Warehouse subscriber initialized (syncing ship-from locations from inventory)")
+	}
+
 	// Initialize shipping service with carrier selector for database-driven carrier selection
 	shippingService := services.NewShippingServiceWithSelector(legacyCarrierService, carrierSelectorService, shipmentRepo)
 	log.Println("Shipping service initialized with carrier selector")
@@ -187,16 +228,50 @@ func main() {
 	rbacMw := rbac.NewMiddlewareWithURL(staffServiceURL, nil)
 	log.Println("✓ RBAC middleware initialized")
 
+	var keyRotationWorker *services.KeyRotationWorker
+	if keyring != nil {
+		keyRotationWorker = services.NewKeyRotationWorker(db, keyring, cfg.Encryption.RotationAge, &models.ShippingCarrierConfig{})
+		go keyRotationWorker.Start()
+		log.Println("✓ Key rotation worker started")
+	}
+
 	// Setup router
 	router := setupRouter(shippingHandler, carrierConfigHandler, cfg, rbacMw, redisClient)
 	log.Printf("Router configured")
 
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.Server.Port)
-	log.Printf("Starting server on %s (environment: %s)", addr, cfg.Server.Env)
-	if err := router.Run(addr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: router,
+	}
+	go func() {
+		log.Printf("Starting server on %s (environment: %s)", addr, cfg.Server.Env)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down shipping-service...")
+
+	// Stop accepting connections and let in-flight requests finish before stopping workers
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
 	}
+
+	if keyRotationWorker != nil {
+		keyRotationWorker.Stop()
+		log.Println("✓ Key rotation worker stopped")
+	}
+
+	log.Println("Shipping service stopped")
 }
 
 // connectDatabase establishes a connection to the PostgreSQL database
@@ -211,6 +286,11 @@ func connectDatabase(databaseURL string) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
+	}
+
 	// Configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -224,16 +304,32 @@ func connectDatabase(databaseURL string) (*gorm.DB, error) {
 	return db, nil
 }
 
-// runMigrations runs database migrations
-func runMigrations(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&models.Shipment{},
-		&models.ShipmentTracking{},
-		&models.ShippingCarrierConfig{},
-		&models.ShippingCarrierRegion{},
-		&models.ShippingCarrierTemplate{},
-		&models.ShippingSettings{},
-	)
+// initFieldEncryption configures the encrypted GORM serializer. It returns a nil keyring
+// when no key is configured, in which case encrypted fields are written in plaintext.
+func initFieldEncryption(cfg *config.Config, db *gorm.DB) (*encryption.Keyring, error) {
+	var wrapper encryption.KeyWrapper
+	switch {
+	case cfg.Encryption.KMSKey != "":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		kmsWrapper, err := encryption.NewKMSKeyWrapper(ctx, cfg.Encryption.KMSKey)
+		if err != nil {
+			return nil, err
+		}
+		wrapper = kmsWrapper
+	case cfg.Encryption.LocalKey != "" && cfg.Server.Env != "production":
+		localWrapper, err := encryption.NewLocalKeyWrapper(cfg.Encryption.LocalKey)
+		if err != nil {
+			return nil, err
+		}
+		wrapper = localWrapper
+	default:
+		return nil, nil
+	}
+
+	keyring := encryption.NewKeyring(db, wrapper)
+	encryption.Configure(keyring)
+	return keyring, nil
 }
 
 // initializeCarrier initializes a carrier if enabled
@@ -286,9 +382,11 @@ func setupRouter(shippingHandler *handlers.ShippingHandler, carrierConfigHandler
 	// Security headers middleware
 	router.Use(gosharedmw.SecurityHeaders())
 
-	// Rate limiting middleware (uses Redis for distributed rate limiting)
+	// Rate limiting middleware (uses Redis for distributed rate limiting; profiles are managed in staff-service)
 	if redisClient != nil {
-		router.Use(gosharedmw.RedisRateLimitMiddlewareWithProfile(redisClient, "standard"))
+		rateLimiter := middleware.NewProfileRateLimiter(redisClient)
+		go rateLimiter.Start(context.Background())
+		router.Use(rateLimiter.Middleware())
 		log.Println("✓ Redis-based rate limiting enabled")
 	} else {
 		router.Use(gosharedmw.RateLimit())
@@ -337,6 +435,7 @@ func setupRouter(shippingHandler *handlers.ShippingHandler, carrierConfigHandler
 
 		// Rates - require shipping:read permission
 		api.POST("/rates", rbacMw.RequirePermission(rbac.PermissionShippingRead), shippingHandler.GetRates)
+		api.POST("/rates/cart", rbacMw.RequirePermission(rbac.PermissionShippingRead), shippingHandler.GetCartRates)
 
 		// Tracking - require shipping:read permission
 		api.GET("/track/:trackingNumber", rbacMw.RequirePermission(rbac.PermissionShippingRead), shippingHandler.TrackShipment)
@@ -351,7 +450,15 @@ func setupRouter(shippingHandler *handlers.ShippingHandler, carrierConfigHandler
 		api.GET("/carrier-configs/:id/regions", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.ListCarrierRegions)
 		api.GET("/carriers/available", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.GetAvailableCarriers)
 		api.GET("/carriers/country-matrix", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.GetCountryCarrierMatrix)
+		api.GET("/carriers/capacity", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.GetCarrierCapacity)
 		api.GET("/shipping-settings", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.GetShippingSettings)
+		api.GET("/vendor-shipping-settings", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.ListVendorShippingSettings)
+		api.GET("/vendor-shipping-settings/:vendorId", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.GetVendorShippingSettings)
+		api.GET("/ship-from-locations", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.ListShipFromLocations)
+		api.GET("/ship-from-locations/:id", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.GetShipFromLocation)
+		api.GET("/rate-cards", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.ListRateCards)
+		api.GET("/rate-cards/:id", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.GetRateCard)
+		api.POST("/rate-cards/compare", rbacMw.RequirePermission(rbac.PermissionShippingRead), carrierConfigHandler.CompareRates)
 
 		// Carrier Configuration - Manage operations (require shipping:manage permission)
 		api.POST("/carrier-configs", rbacMw.RequirePermission(rbac.PermissionShippingManage), carrierConfigHandler.CreateCarrierConfig)
@@ -371,6 +478,21 @@ func setupRouter(shippingHandler *handlers.ShippingHandler, carrierConfigHandler
 
 		// Shipping Settings - Manage operations
 		api.PUT("/shipping-settings", rbacMw.RequirePermission(rbac.PermissionShippingManage), carrierConfigHandler.UpdateShippingSettings)
+
+		// Vendor Shipping Settings - Manage operations (vendor users are limited to their own vendor)
+		api.PUT("/vendor-shipping-settings/:vendorId", rbacMw.RequirePermission(rbac.PermissionShippingManage), carrierConfigHandler.UpdateVendorShippingSettings)
+		api.DELETE("/vendor-shipping-settings/:vendorId", rbacMw.RequirePermission(rbac.PermissionShippingManage), carrierConfigHandler.DeleteVendorShippingSettings)
+
+		// Ship-From Locations - Manage operations (vendor users are limited to their own locations)
+		api.POST("/ship-from-locations", rbacMw.RequirePermission(rbac.PermissionShippingManage), carrierConfigHandler.CreateShipFromLocation)
+		api.PUT("/ship-from-locations/:id", rbacMw.RequirePermission(rbac.PermissionShippingManage), carrierConfigHandler.UpdateShipFromLocation)
+		api.DELETE("/ship-from-locations/:id", rbacMw.RequirePermission(rbac.PermissionShippingManage), carrierConfigHandler.DeleteShipFromLocation)
+
+		// Negotiated Rate Cards and Rate Cache - Manage operations
+		api.POST("/rate-cards", rbacMw.RequirePermission(rbac.PermissionShippingManage), carrierConfigHandler.UploadRateCard)
+		api.POST("/rate-cards/:id/activate", rbacMw.RequirePermission(rbac.PermissionShippingManage), carrierConfigHandler.ActivateRateCard)
+		api.DELETE("/rate-cards/:id", rbacMw.RequirePermission(rbac.PermissionShippingManage), carrierConfigHandler.DeleteRateCard)
+		api.DELETE("/rates/cache", rbacMw.RequirePermission(rbac.PermissionShippingManage), carrierConfigHandler.ClearRateCache)
 	}
 
 	// Webhook routes (no tenant middleware for external carrier callbacks - no RBAC needed)
@@ -3,6 +3,7 @@ module shipping-service
 go 1.25
 
 require (
+	cloud.google.com/go/kms v1.15.5
 	github.com/Tesseract-Nexus/go-shared v0.3.0
 	github.com/gin-gonic/gin v1.10.0
 	github.com/google/uuid v1.6.0
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets"
 	"shipping-service/internal/carriers"
@@ -11,16 +12,18 @@ import (
 
 // Config holds all configuration for the shipping service
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	RedisURL string
-	Carriers CarriersConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	RedisURL   string
+	Carriers   CarriersConfig
+	Encryption EncryptionConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port string
-	Env  string
+	Port            string
+	Env             string
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
 }
 
 // DatabaseConfig holds database configuration
@@ -33,6 +36,14 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
+// EncryptionConfig holds field encryption configuration. Tenant data keys are wrapped with
+// the Cloud KMS key; the local key (base64, 32 bytes) is only honoured outside production.
+type EncryptionConfig struct {
+	KMSKey      string
+	LocalKey    string
+	RotationAge time.Duration
+}
+
 // CarriersConfig holds configuration for all carriers
 type CarriersConfig struct {
 	Shiprocket  carriers.CarrierConfig
@@ -45,8 +56,9 @@ type CarriersConfig struct {
 func Load() (*Config, error) {
 	config := &Config{
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8088"),
-			Env:  getEnv("NODE_ENV", "development"),
+			Port:            getEnv("PORT", "8088"),
+			Env:             getEnv("NODE_ENV", "development"),
+			ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -57,6 +69,11 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		RedisURL: getEnv("REDIS_URL", "redis://redis.redis-marketplace.svc.cluster.local:6379/0"),
+		Encryption: EncryptionConfig{
+			KMSKey:      getEnv("FIELD_ENCRYPTION_KMS_KEY", ""),
+			LocalKey:    getEnv("FIELD_ENCRYPTION_LOCAL_KEY", ""),
+			RotationAge: time.Duration(getEnvAsInt("FIELD_ENCRYPTION_ROTATION_DAYS", 90)) * 24 * time.Hour,
+		},
 		// Carrier env vars are optional fallbacks - carriers are configured per-tenant via database
 		Carriers: CarriersConfig{
 			Shiprocket: carriers.CarrierConfig{
@@ -170,3 +187,12 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return boolValue
 }
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
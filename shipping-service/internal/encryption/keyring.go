@@ -0,0 +1,274 @@
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DataKeyStatusActive  = "active"
+	DataKeyStatusRetired = "retired"
+
+	// activeKeyCacheTTL bounds how long a replica keeps encrypting with a key that
+	// another replica has already rotated out
+	activeKeyCacheTTL = 5 * time.Minute
+)
+
+// TenantDataKey is a tenant's data encryption key (DEK), stored wrapped by the KMS key.
+// A tenant has one active key used for new writes; retired keys are kept so values
+// encrypted with them stay readable until the rotation job has re-encrypted them.
+type TenantDataKey struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID   string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_tenant_data_keys_version" json:"tenantId"`
+	Version    int        `gorm:"not null;uniqueIndex:idx_tenant_data_keys_version" json:"version"`
+	WrappedKey []byte     `gorm:"type:bytea;not null" json:"-"`
+	KEKName    string     `gorm:"type:varchar(500);not null" json:"kekName"` // KMS key version that wrapped the key
+	Status     string     `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	RetiredAt  *time.Time `json:"retiredAt,omitempty"`
+}
+
+// TableName specifies the table name for TenantDataKey
+func (TenantDataKey) TableName() string {
+	return "tenant_data_keys"
+}
+
+type dataKeyID struct {
+	tenantID string
+	version  int
+}
+
+type activeKey struct {
+	version   int
+	expiresAt time.Time
+}
+
+// Keyring hands out per-tenant data keys, creating a tenant's first key on demand.
+// Unwrapped keys are cached in memory, so KMS is only called once per key and replica.
+type Keyring struct {
+	db      *gorm.DB
+	wrapper KeyWrapper
+
+	mu     sync.RWMutex
+	keys   map[dataKeyID][]byte
+	active map[string]activeKey
+}
+
+// NewKeyring creates a keyring storing wrapped keys in db
+func NewKeyring(db *gorm.DB, wrapper KeyWrapper) *Keyring {
+	return &Keyring{
+		db:      db,
+		wrapper: wrapper,
+		keys:    make(map[dataKeyID][]byte),
+		active:  make(map[string]activeKey),
+	}
+}
+
+// Close releases the key wrapper
+func (k *Keyring) Close() error {
+	return k.wrapper.Close()
+}
+
+// ActiveKey returns the version and key new values of the tenant are encrypted with
+func (k *Keyring) ActiveKey(ctx context.Context, tenantID string) (int, []byte, error) {
+	k.mu.RLock()
+	cached, ok := k.active[tenantID]
+	k.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		key, err := k.Key(ctx, tenantID, cached.version)
+		return cached.version, key, err
+	}
+
+	var record TenantDataKey
+	err := k.db.WithContext(ctx).
+		Where("tenant_id = ? AND status = ?", tenantID, DataKeyStatusActive).
+		Order("version DESC").
+		First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		record, err = k.createFirstKey(ctx, tenantID)
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load data key for tenant %s: %w", tenantID, err)
+	}
+
+	key, err := k.unwrap(ctx, &record)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	k.mu.Lock()
+	k.active[tenantID] = activeKey{version: record.Version, expiresAt: time.Now().Add(activeKeyCacheTTL)}
+	k.mu.Unlock()
+	return record.Version, key, nil
+}
+
+// Key returns a specific key version of the tenant, active or retired
+func (k *Keyring) Key(ctx context.Context, tenantID string, version int) ([]byte, error) {
+	k.mu.RLock()
+	key, ok := k.keys[dataKeyID{tenantID, version}]
+	k.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	var record TenantDataKey
+	if err := k.db.WithContext(ctx).
+		Where("tenant_id = ? AND version = ?", tenantID, version).
+		First(&record).Error; err != nil {
+		return nil, fmt.Errorf("failed to load data key v%d for tenant %s: %w", version, tenantID, err)
+	}
+	return k.unwrap(ctx, &record)
+}
+
+// Rotate retires the tenant's active key and creates the next version. Values encrypted
+// with the old key stay readable; ReencryptTenant moves them to the new one.
+func (k *Keyring) Rotate(ctx context.Context, tenantID string) (int, error) {
+	dek, wrapped, kekName, err := k.newKey(ctx, tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+	err = k.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current TenantDataKey
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("tenant_id = ? AND status = ?", tenantID, DataKeyStatusActive).
+			Order("version DESC").
+			First(&current).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&TenantDataKey{}).
+			Where("tenant_id = ?", tenantID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		if current.ID != uuid.Nil {
+			now := time.Now()
+			if err := tx.Model(&current).Updates(map[string]interface{}{
+				"status":     DataKeyStatusRetired,
+				"retired_at": now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		version = latest + 1
+		return tx.Create(&TenantDataKey{
+			TenantID:   tenantID,
+			Version:    version,
+			WrappedKey: wrapped,
+			KEKName:    kekName,
+			Status:     DataKeyStatusActive,
+		}).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to rotate data key for tenant %s: %w", tenantID, err)
+	}
+
+	k.mu.Lock()
+	k.keys[dataKeyID{tenantID, version}] = dek
+	k.active[tenantID] = activeKey{version: version, expiresAt: time.Now().Add(activeKeyCacheTTL)}
+	k.mu.Unlock()
+	return version, nil
+}
+
+// KeysDueForRotation returns the active keys created before the cutoff
+func (k *Keyring) KeysDueForRotation(ctx context.Context, cutoff time.Time) ([]TenantDataKey, error) {
+	var keys []TenantDataKey
+	err := k.db.WithContext(ctx).
+		Where("status = ? AND created_at < ?", DataKeyStatusActive, cutoff).
+		Find(&keys).Error
+	return keys, err
+}
+
+// TenantsWithRetiredKeys returns the tenants that still have retired keys, i.e. that may
+// have values left to re-encrypt
+func (k *Keyring) TenantsWithRetiredKeys(ctx context.Context) ([]string, error) {
+	var tenantIDs []string
+	err := k.db.WithContext(ctx).
+		Model(&TenantDataKey{}).
+		Where("status = ?", DataKeyStatusRetired).
+		Distinct().
+		Pluck("tenant_id", &tenantIDs).Error
+	return tenantIDs, err
+}
+
+// createFirstKey creates version 1 for a tenant without keys. If another replica wins
+// the race its key is used instead.
+func (k *Keyring) createFirstKey(ctx context.Context, tenantID string) (TenantDataKey, error) {
+	dek, wrapped, kekName, err := k.newKey(ctx, tenantID)
+	if err != nil {
+		return TenantDataKey{}, err
+	}
+
+	record := TenantDataKey{
+		TenantID:   tenantID,
+		Version:    1,
+		WrappedKey: wrapped,
+		KEKName:    kekName,
+		Status:     DataKeyStatusActive,
+	}
+	result := k.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if result.Error != nil {
+		return TenantDataKey{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		err := k.db.WithContext(ctx).
+			Where("tenant_id = ? AND status = ?", tenantID, DataKeyStatusActive).
+			Order("version DESC").
+			First(&record).Error
+		return record, err
+	}
+
+	k.mu.Lock()
+	k.keys[dataKeyID{tenantID, record.Version}] = dek
+	k.mu.Unlock()
+	return record, nil
+}
+
+func (k *Keyring) newKey(ctx context.Context, tenantID string) ([]byte, []byte, string, error) {
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, nil, "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	wrapped, kekName, err := k.wrapper.Wrap(ctx, tenantID, dek)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return dek, wrapped, kekName, nil
+}
+
+func (k *Keyring) unwrap(ctx context.Context, record *TenantDataKey) ([]byte, error) {
+	id := dataKeyID{record.TenantID, record.Version}
+
+	k.mu.RLock()
+	key, ok := k.keys[id]
+	k.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	key, err := k.wrapper.Unwrap(ctx, record.TenantID, record.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.keys[id] = key
+	k.mu.Unlock()
+	return key, nil
+}
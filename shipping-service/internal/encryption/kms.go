@@ -0,0 +1,135 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+)
+
+// KeyWrapper wraps and unwraps tenant data encryption keys with a key encryption key (KEK).
+// The tenant ID is bound to the wrapped key as additional authenticated data, so a wrapped
+// key copied to another tenant's row can't be unwrapped.
+type KeyWrapper interface {
+	// Wrap encrypts dek and returns the wrapped key with the name of the KEK (version) used
+	Wrap(ctx context.Context, tenantID string, dek []byte) ([]byte, string, error)
+	// Unwrap decrypts a key returned by Wrap
+	Unwrap(ctx context.Context, tenantID string, wrapped []byte) ([]byte, error)
+	Close() error
+}
+
+// KMSKeyWrapper wraps data keys with a GCP Cloud KMS symmetric key. KMS picks the key's
+// primary version for new wraps and keeps older versions usable for unwrapping, so the
+// KEK can be rotated in KMS without touching stored keys.
+type KMSKeyWrapper struct {
+	client  *kms.KeyManagementClient
+	keyName string // projects/{p}/locations/{l}/keyRings/{r}/cryptoKeys/{k}
+}
+
+// NewKMSKeyWrapper creates a wrapper for the given Cloud KMS crypto key
+func NewKMSKeyWrapper(ctx context.Context, keyName string) (*KMSKeyWrapper, error) {
+	client, err := kms.NewKeyManagementClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create KMS client: %w", err)
+	}
+	return &KMSKeyWrapper{client: client, keyName: keyName}, nil
+}
+
+// Wrap implements KeyWrapper
+func (w *KMSKeyWrapper) Wrap(ctx context.Context, tenantID string, dek []byte) ([]byte, string, error) {
+	resp, err := w.client.Encrypt(ctx, &kmspb.EncryptRequest{
+		Name:                        w.keyName,
+		Plaintext:                   dek,
+		AdditionalAuthenticatedData: []byte(tenantID),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to wrap data key with KMS: %w", err)
+	}
+	return resp.Ciphertext, resp.Name, nil
+}
+
+// Unwrap implements KeyWrapper
+func (w *KMSKeyWrapper) Unwrap(ctx context.Context, tenantID string, wrapped []byte) ([]byte, error) {
+	resp, err := w.client.Decrypt(ctx, &kmspb.DecryptRequest{
+		Name:                        w.keyName,
+		Ciphertext:                  wrapped,
+		AdditionalAuthenticatedData: []byte(tenantID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with KMS: %w", err)
+	}
+	return resp.Plaintext, nil
+}
+
+// Close closes the KMS client
+func (w *KMSKeyWrapper) Close() error {
+	return w.client.Close()
+}
+
+// LocalKeyWrapper wraps data keys with a static AES-256 key. It is meant for local
+// development only, where Cloud KMS isn't available.
+type LocalKeyWrapper struct {
+	kek cipher.AEAD
+}
+
+// NewLocalKeyWrapper creates a wrapper from a base64 encoded 32-byte key
+func NewLocalKeyWrapper(encodedKey string) (*LocalKeyWrapper, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode local key encryption key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("local key encryption key must be 32 bytes (256 bits), got %d bytes", len(key))
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalKeyWrapper{kek: gcm}, nil
+}
+
+// Wrap implements KeyWrapper
+func (w *LocalKeyWrapper) Wrap(ctx context.Context, tenantID string, dek []byte) ([]byte, string, error) {
+	nonce := make([]byte, w.kek.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return w.kek.Seal(nonce, nonce, dek, []byte(tenantID)), "local", nil
+}
+
+// Unwrap implements KeyWrapper
+func (w *LocalKeyWrapper) Unwrap(ctx context.Context, tenantID string, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < w.kek.NonceSize() {
+		return nil, fmt.Errorf("wrapped data key is too short")
+	}
+	nonce, ciphertext := wrapped[:w.kek.NonceSize()], wrapped[w.kek.NonceSize():]
+	dek, err := w.kek.Open(nil, nonce, ciphertext, []byte(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return dek, nil
+}
+
+// Close implements KeyWrapper
+func (w *LocalKeyWrapper) Close() error {
+	return nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
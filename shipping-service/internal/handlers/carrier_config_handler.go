@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -68,6 +72,7 @@ func (h *CarrierConfigHandler) GetCarrierConfig(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", versionETag(config.Version))
 	c.JSON(http.StatusOK, config.ToResponse())
 }
 
@@ -117,10 +122,28 @@ func (h *CarrierConfigHandler) CreateCarrierConfig(c *gin.Context) {
 		SupportedServices:  request.SupportedServices,
 		Priority:           request.Priority,
 		Description:        request.Description,
+		DailyShipmentCap:   request.DailyShipmentCap,
+		PickupCutoffTime:   request.PickupCutoffTime,
+		CutoffTimezone:     request.CutoffTimezone,
+		CapacityPolicy:     request.CapacityPolicy,
+		AllowsDangerousGoods:    request.AllowsDangerousGoods,
+		AllowsBatteries:         request.AllowsBatteries == nil || *request.AllowsBatteries,
+		SupportsAgeVerification: request.SupportsAgeVerification,
+		UseNegotiatedRates:      request.UseNegotiatedRates,
 		SupportsRates:      true,
 		SupportsTracking:   true,
 		SupportsLabels:     true,
 	}
+	if config.CapacityPolicy == "" {
+		config.CapacityPolicy = models.CapacityPolicyDeprioritize
+	}
+	if err := config.ValidateCapacitySettings(); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid capacity settings",
+			Message: err.Error(),
+		})
+		return
+	}
 
 	if err := h.repo.CreateCarrierConfig(c.Request.Context(), config); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -156,6 +179,17 @@ func (h *CarrierConfigHandler) UpdateCarrierConfig(c *gin.Context) {
 		return
 	}
 
+	// If-Match takes precedence over expectedVersion in the body
+	if version, present, err := parseIfMatchVersion(c); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid If-Match header",
+			Message: err.Error(),
+		})
+		return
+	} else if present {
+		request.ExpectedVersion = version
+	}
+
 	// Get existing config
 	config, err := h.repo.GetCarrierConfig(c.Request.Context(), id)
 	if err != nil {
@@ -175,6 +209,11 @@ func (h *CarrierConfigHandler) UpdateCarrierConfig(c *gin.Context) {
 		return
 	}
 
+	if request.ExpectedVersion != nil && *request.ExpectedVersion != config.Version {
+		respondVersionConflict(c, config.Version)
+		return
+	}
+
 	// Apply updates
 	if request.DisplayName != nil {
 		config.DisplayName = *request.DisplayName
@@ -219,8 +258,44 @@ func (h *CarrierConfigHandler) UpdateCarrierConfig(c *gin.Context) {
 	if request.Description != nil {
 		config.Description = *request.Description
 	}
+	if request.DailyShipmentCap != nil {
+		config.DailyShipmentCap = *request.DailyShipmentCap
+	}
+	if request.PickupCutoffTime != nil {
+		config.PickupCutoffTime = *request.PickupCutoffTime
+	}
+	if request.CutoffTimezone != nil {
+		config.CutoffTimezone = *request.CutoffTimezone
+	}
+	if request.CapacityPolicy != nil {
+		config.CapacityPolicy = *request.CapacityPolicy
+	}
+	if request.AllowsDangerousGoods != nil {
+		config.AllowsDangerousGoods = *request.AllowsDangerousGoods
+	}
+	if request.AllowsBatteries != nil {
+		config.AllowsBatteries = *request.AllowsBatteries
+	}
+	if request.SupportsAgeVerification != nil {
+		config.SupportsAgeVerification = *request.SupportsAgeVerification
+	}
+	if request.UseNegotiatedRates != nil {
+		config.UseNegotiatedRates = *request.UseNegotiatedRates
+	}
+	if err := config.ValidateCapacitySettings(); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid capacity settings",
+			Message: err.Error(),
+		})
+		return
+	}
 
 	if err := h.repo.UpdateCarrierConfig(c.Request.Context(), config); err != nil {
+		var conflict *models.VersionConflictError
+		if errors.As(err, &conflict) {
+			respondVersionConflict(c, conflict.CurrentVersion)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to update carrier configuration",
 			Message: err.Error(),
@@ -228,9 +303,39 @@ func (h *CarrierConfigHandler) UpdateCarrierConfig(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", versionETag(config.Version))
 	c.JSON(http.StatusOK, config.ToResponse())
 }
 
+// parseIfMatchVersion reads a carrier config version from If-Match ("3", W/"3" or 3).
+// present is false when the header is missing or "*", leaving the update unconditional.
+func parseIfMatchVersion(c *gin.Context) (version *int, present bool, err error) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" || raw == "*" {
+		return nil, false, nil
+	}
+	v, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(raw, "W/"), `"`))
+	if err != nil || v < 1 {
+		return nil, true, fmt.Errorf("If-Match must be a carrier config version, got %q", raw)
+	}
+	return &v, true, nil
+}
+
+func versionETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// respondVersionConflict answers 409 with the stored version so the client can reload and retry
+func respondVersionConflict(c *gin.Context, currentVersion int) {
+	c.Header("ETag", versionETag(currentVersion))
+	c.JSON(http.StatusConflict, gin.H{
+		"success":        false,
+		"error":          "Version conflict",
+		"message":        "Carrier configuration was modified by another request",
+		"currentVersion": currentVersion,
+	})
+}
+
 // DeleteCarrierConfig handles DELETE /api/carrier-configs/:id
 func (h *CarrierConfigHandler) DeleteCarrierConfig(c *gin.Context) {
 	tenantID := getTenantID(c)
@@ -634,6 +739,25 @@ func (h *CarrierConfigHandler) GetAvailableCarriers(c *gin.Context) {
 	c.JSON(http.StatusOK, carriers)
 }
 
+// GetCarrierCapacity handles GET /api/carriers/capacity
+func (h *CarrierConfigHandler) GetCarrierCapacity(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	carriers, err := h.selectorService.GetCarrierCapacity(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get carrier capacity",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CarrierCapacityResponse{
+		Success:  true,
+		Carriers: carriers,
+	})
+}
+
 // GetCountryCarrierMatrix handles GET /api/carriers/country-matrix
 func (h *CarrierConfigHandler) GetCountryCarrierMatrix(c *gin.Context) {
 	tenantID := getTenantID(c)
@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"shipping-service/internal/models"
+	"shipping-service/internal/services"
+)
+
+// ListRateCards handles GET /api/rate-cards
+func (h *CarrierConfigHandler) ListRateCards(c *gin.Context) {
+	if !rejectVendorUsers(c) {
+		return
+	}
+	tenantID := getTenantID(c)
+	carrierType := models.CarrierType(strings.ToUpper(c.Query("carrierType")))
+
+	cards, err := h.selectorService.ListRateCards(c.Request.Context(), tenantID, carrierType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to list rate cards",
+			Message: err.Error(),
+		})
+		return
+	}
+	if cards == nil {
+		cards = []models.RateCard{}
+	}
+
+	c.JSON(http.StatusOK, models.RateCardResponse{
+		Success:   true,
+		RateCards: cards,
+	})
+}
+
+// GetRateCard handles GET /api/rate-cards/:id
+func (h *CarrierConfigHandler) GetRateCard(c *gin.Context) {
+	if !rejectVendorUsers(c) {
+		return
+	}
+	id, ok := parseRateCardID(c)
+	if !ok {
+		return
+	}
+
+	card, err := h.selectorService.GetRateCard(c.Request.Context(), getTenantID(c), id)
+	if !respondRateCardError(c, err, "Failed to get rate card") {
+		return
+	}
+
+	c.JSON(http.StatusOK, card)
+}
+
+// UploadRateCard handles POST /api/rate-cards. The multipart form carries the CSV in "file"
+// along with carrierType, name, currency and optional effectiveFrom / effectiveTo (YYYY-MM-DD).
+func (h *CarrierConfigHandler) UploadRateCard(c *gin.Context) {
+	if !rejectVendorUsers(c) {
+		return
+	}
+	tenantID := getTenantID(c)
+
+	carrierType := models.CarrierType(strings.ToUpper(strings.TrimSpace(c.PostForm("carrierType"))))
+	name := strings.TrimSpace(c.PostForm("name"))
+	currency := strings.TrimSpace(c.PostForm("currency"))
+	if carrierType == "" || name == "" || len(currency) != 3 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: "carrierType, name and a 3-letter currency are required",
+		})
+		return
+	}
+
+	effectiveFrom, effectiveTo, err := parseRateCardDates(c.PostForm("effectiveFrom"), c.PostForm("effectiveTo"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: "A rate card CSV is required in the file field",
+		})
+		return
+	}
+	if fileHeader.Size > models.MaxRateCardFileBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+			Error:   "File too large",
+			Message: fmt.Sprintf("Rate cards are limited to %d MB", models.MaxRateCardFileBytes>>20),
+		})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	card := &models.RateCard{
+		TenantID:      tenantID,
+		CarrierType:   carrierType,
+		Name:          name,
+		Currency:      currency,
+		EffectiveFrom: effectiveFrom,
+		EffectiveTo:   effectiveTo,
+		FileName:      fileHeader.Filename,
+		UploadedBy:    gosharedmw.GetIstioUserID(c),
+	}
+
+	card, err = h.selectorService.UploadRateCard(c.Request.Context(), card, file)
+	var uploadErr *models.RateCardUploadError
+	switch {
+	case errors.As(err, &uploadErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   "Invalid rate card",
+			"message": uploadErr.Error(),
+			"errors":  uploadErr.Errors,
+		})
+		return
+	case errors.Is(err, services.ErrCarrierNotConfigured):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Carrier not configured",
+			Message: fmt.Sprintf("Enable %s before uploading its rate card", carrierType),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to upload rate card",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Entries can run to thousands of rows; GET /api/rate-cards/:id returns them
+	card.Entries = nil
+	c.JSON(http.StatusCreated, card)
+}
+
+// ActivateRateCard handles POST /api/rate-cards/:id/activate
+func (h *CarrierConfigHandler) ActivateRateCard(c *gin.Context) {
+	if !rejectVendorUsers(c) {
+		return
+	}
+	id, ok := parseRateCardID(c)
+	if !ok {
+		return
+	}
+
+	card, err := h.selectorService.ActivateRateCard(c.Request.Context(), getTenantID(c), id)
+	if !respondRateCardError(c, err, "Failed to activate rate card") {
+		return
+	}
+
+	c.JSON(http.StatusOK, card)
+}
+
+// DeleteRateCard handles DELETE /api/rate-cards/:id
+func (h *CarrierConfigHandler) DeleteRateCard(c *gin.Context) {
+	if !rejectVendorUsers(c) {
+		return
+	}
+	id, ok := parseRateCardID(c)
+	if !ok {
+		return
+	}
+
+	err := h.selectorService.DeleteRateCard(c.Request.Context(), getTenantID(c), id)
+	if !respondRateCardError(c, err, "Failed to delete rate card") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Rate card deleted"})
+}
+
+// CompareRates handles POST /api/rate-cards/compare
+func (h *CarrierConfigHandler) CompareRates(c *gin.Context) {
+	if !rejectVendorUsers(c) {
+		return
+	}
+
+	var request models.RateComparisonRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+	request.CarrierType = models.CarrierType(strings.ToUpper(string(request.CarrierType)))
+	if len(request.Shipments) > models.MaxRateComparisons {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: fmt.Sprintf("At most %d shipments can be compared at once", models.MaxRateComparisons),
+		})
+		return
+	}
+
+	report, err := h.selectorService.CompareRates(c.Request.Context(), getTenantID(c), &request)
+	switch {
+	case errors.Is(err, services.ErrNoActiveRateCard), errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Rate card not found",
+			Message: fmt.Sprintf("No rate card to compare for %s", request.CarrierType),
+		})
+		return
+	case errors.Is(err, services.ErrCarrierNotConfigured), errors.Is(err, services.ErrRateCardWrongCarrier):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to compare rates",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ClearRateCache handles DELETE /api/rates/cache, optionally for one ?carrierType
+func (h *CarrierConfigHandler) ClearRateCache(c *gin.Context) {
+	if !rejectVendorUsers(c) {
+		return
+	}
+	carrierType := models.CarrierType(strings.ToUpper(c.Query("carrierType")))
+
+	if err := h.selectorService.ClearRateCache(c.Request.Context(), getTenantID(c), carrierType); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to clear rate cache",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "Rate cache cleared"})
+}
+
+// respondRateCardError writes the error response for a rate card lookup or change and reports
+// whether the request can continue
+func respondRateCardError(c *gin.Context, err error, failure string) bool {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Rate card not found",
+			Message: "No rate card with this ID",
+		})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   failure,
+			Message: err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// rejectVendorUsers keeps vendor users away from the tenant's carrier contracts
+func rejectVendorUsers(c *gin.Context) bool {
+	if gosharedmw.GetVendorScopeFilter(c) != "" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Vendor users cannot manage negotiated carrier rates",
+		})
+		return false
+	}
+	return true
+}
+
+func parseRateCardID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid rate card ID",
+			Message: err.Error(),
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// parseRateCardDates parses optional YYYY-MM-DD effective dates. The card stays in effect
+// through the whole of its last day.
+func parseRateCardDates(from, to string) (*time.Time, *time.Time, error) {
+	effectiveFrom, err := parseRateCardDate(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	effectiveTo, err := parseRateCardDate(to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if effectiveFrom != nil && effectiveTo != nil && effectiveTo.Before(*effectiveFrom) {
+		return nil, nil, errors.New("effectiveTo must not be before effectiveFrom")
+	}
+	if effectiveTo != nil {
+		end := effectiveTo.Add(24*time.Hour - time.Nanosecond)
+		effectiveTo = &end
+	}
+	return effectiveFrom, effectiveTo, nil
+}
+
+func parseRateCardDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("dates must be YYYY-MM-DD, got %q", value)
+	}
+	return &date, nil
+}
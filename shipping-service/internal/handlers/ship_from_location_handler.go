@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"shipping-service/internal/models"
+	"shipping-service/internal/services"
+)
+
+// ListShipFromLocations handles GET /api/ship-from-locations
+func (h *CarrierConfigHandler) ListShipFromLocations(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	// Vendor users only see their own locations
+	vendorID := c.Query("vendorId")
+	if scope := gosharedmw.GetVendorScopeFilter(c); scope != "" {
+		vendorID = scope
+	}
+	activeOnly := c.Query("active") == "true"
+
+	locations, err := h.selectorService.ListShipFromLocations(c.Request.Context(), tenantID, vendorID, activeOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to list ship-from locations",
+			Message: err.Error(),
+		})
+		return
+	}
+	if locations == nil {
+		locations = []models.ShipFromLocation{}
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+// GetShipFromLocation handles GET /api/ship-from-locations/:id
+func (h *CarrierConfigHandler) GetShipFromLocation(c *gin.Context) {
+	location, ok := h.loadShipFromLocation(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// CreateShipFromLocation handles POST /api/ship-from-locations
+func (h *CarrierConfigHandler) CreateShipFromLocation(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	var request models.CreateShipFromLocationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Vendor users can only add locations for their own vendor
+	if scope := gosharedmw.GetVendorScopeFilter(c); scope != "" {
+		request.VendorID = scope
+	}
+
+	if !validShipFromAddress(c, request.Address) {
+		return
+	}
+
+	location, err := h.selectorService.CreateShipFromLocation(c.Request.Context(), tenantID, &request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to create ship-from location",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, location)
+}
+
+// UpdateShipFromLocation handles PUT /api/ship-from-locations/:id
+func (h *CarrierConfigHandler) UpdateShipFromLocation(c *gin.Context) {
+	tenantID := getTenantID(c)
+	existing, ok := h.loadShipFromLocation(c)
+	if !ok {
+		return
+	}
+
+	var request models.UpdateShipFromLocationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if request.Address != nil && !validShipFromAddress(c, *request.Address) {
+		return
+	}
+
+	location, err := h.selectorService.UpdateShipFromLocation(c.Request.Context(), tenantID, existing.ID, &request)
+	if errors.Is(err, services.ErrShipFromLocationSynced) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Ship-from location is synced from inventory",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to update ship-from location",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// DeleteShipFromLocation handles DELETE /api/ship-from-locations/:id
+func (h *CarrierConfigHandler) DeleteShipFromLocation(c *gin.Context) {
+	tenantID := getTenantID(c)
+	existing, ok := h.loadShipFromLocation(c)
+	if !ok {
+		return
+	}
+
+	err := h.selectorService.DeleteShipFromLocation(c.Request.Context(), tenantID, existing.ID)
+	if errors.Is(err, services.ErrShipFromLocationSynced) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Ship-from location is synced from inventory",
+			Message: "Delete the warehouse in inventory, or deactivate the location instead",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to delete ship-from location",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: stringPtr("Ship-from location deleted"),
+	})
+}
+
+// loadShipFromLocation loads the :id location. Vendor users may only address their own locations.
+func (h *CarrierConfigHandler) loadShipFromLocation(c *gin.Context) (*models.ShipFromLocation, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid location ID",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	location, err := h.selectorService.GetShipFromLocation(c.Request.Context(), getTenantID(c), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Ship-from location not found",
+			Message: "No ship-from location with this ID",
+		})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get ship-from location",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	if scope := gosharedmw.GetVendorScopeFilter(c); scope != "" && scope != location.VendorID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Vendor users can only manage their own ship-from locations",
+		})
+		return nil, false
+	}
+	return location, true
+}
+
+// validShipFromAddress rejects partial addresses, which carriers can't rate or book from
+func validShipFromAddress(c *gin.Context, addr models.Address) bool {
+	if addr.Street == "" || addr.City == "" || addr.PostalCode == "" || addr.Country == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid address",
+			Message: "Address requires street, city, postalCode and country",
+		})
+		return false
+	}
+	return true
+}
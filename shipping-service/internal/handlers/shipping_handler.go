@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"net/http"
@@ -52,6 +53,9 @@ func (h *ShippingHandler) CreateShipment(c *gin.Context) {
 	}
 
 	shipment, err := h.shippingService.CreateShipment(request, tenantID)
+	if respondShippingRestricted(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to create shipment",
@@ -170,7 +174,10 @@ func (h *ShippingHandler) GetRates(c *gin.Context) {
 		return
 	}
 
-	rates, err := h.shippingService.GetRates(request, tenantID)
+	rates, violations, err := h.shippingService.GetRates(request, tenantID)
+	if respondShippingRestricted(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Failed to get shipping rates",
@@ -180,8 +187,52 @@ func (h *ShippingHandler) GetRates(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, models.GetRatesResponse{
+		Success:    true,
+		Rates:      rates,
+		Violations: violations,
+	})
+}
+
+// respondShippingRestricted answers 422 with the violations when shipping restrictions leave
+// no way to ship the items
+func respondShippingRestricted(c *gin.Context, err error) bool {
+	var restrictionErr *models.ShippingRestrictionError
+	if !errors.As(err, &restrictionErr) {
+		return false
+	}
+	c.JSON(http.StatusUnprocessableEntity, models.ShippingRestrictionResponse{
+		Error:      "Shipping restricted",
+		Message:    restrictionErr.Error(),
+		Violations: restrictionErr.Violations,
+	})
+	return true
+}
+
+// GetCartRates handles POST /api/rates/cart
+func (h *ShippingHandler) GetCartRates(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	var request models.GetCartRatesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	groups, err := h.shippingService.GetCartRates(request, tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get cart shipping rates",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GetCartRatesResponse{
 		Success: true,
-		Rates:   rates,
+		Groups:  groups,
 	})
 }
 
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"shipping-service/internal/models"
+)
+
+// ListVendorShippingSettings handles GET /api/vendor-shipping-settings
+func (h *CarrierConfigHandler) ListVendorShippingSettings(c *gin.Context) {
+	tenantID := getTenantID(c)
+
+	// Vendor users only see their own settings
+	if vendorID := gosharedmw.GetVendorScopeFilter(c); vendorID != "" {
+		settings, err := h.selectorService.GetVendorShippingSettings(c.Request.Context(), tenantID, vendorID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusOK, []models.VendorShippingSettings{})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Failed to list vendor shipping settings",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, []models.VendorShippingSettings{*settings})
+		return
+	}
+
+	settings, err := h.selectorService.ListVendorShippingSettings(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to list vendor shipping settings",
+			Message: err.Error(),
+		})
+		return
+	}
+	if settings == nil {
+		settings = []models.VendorShippingSettings{}
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// GetVendorShippingSettings handles GET /api/vendor-shipping-settings/:vendorId
+func (h *CarrierConfigHandler) GetVendorShippingSettings(c *gin.Context) {
+	tenantID := getTenantID(c)
+	vendorID, ok := vendorIDParam(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.selectorService.GetVendorShippingSettings(c.Request.Context(), tenantID, vendorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Vendor shipping settings not found",
+			Message: "Vendor uses the tenant's shipping settings",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to get vendor shipping settings",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateVendorShippingSettings handles PUT /api/vendor-shipping-settings/:vendorId
+func (h *CarrierConfigHandler) UpdateVendorShippingSettings(c *gin.Context) {
+	tenantID := getTenantID(c)
+	vendorID, ok := vendorIDParam(c)
+	if !ok {
+		return
+	}
+
+	var request models.UpdateVendorShippingSettingsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// A partial origin would be silently ignored by the rate engine, so require a full address
+	if request.Origin != nil && *request.Origin != (models.Address{}) {
+		origin := request.Origin
+		if origin.Name == "" || origin.Street == "" || origin.City == "" || origin.PostalCode == "" || origin.Country == "" {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid origin address",
+				Message: "Origin requires name, street, city, postalCode and country",
+			})
+			return
+		}
+	}
+
+	settings, err := h.selectorService.UpdateVendorShippingSettings(c.Request.Context(), tenantID, vendorID, &request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to update vendor shipping settings",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// DeleteVendorShippingSettings handles DELETE /api/vendor-shipping-settings/:vendorId
+func (h *CarrierConfigHandler) DeleteVendorShippingSettings(c *gin.Context) {
+	tenantID := getTenantID(c)
+	vendorID, ok := vendorIDParam(c)
+	if !ok {
+		return
+	}
+
+	err := h.selectorService.DeleteVendorShippingSettings(c.Request.Context(), tenantID, vendorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Vendor shipping settings not found",
+			Message: "Vendor uses the tenant's shipping settings",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to delete vendor shipping settings",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Message: stringPtr("Vendor shipping settings deleted"),
+	})
+}
+
+// vendorIDParam returns the :vendorId route param. Vendor users may only address their own vendor.
+func vendorIDParam(c *gin.Context) (string, bool) {
+	vendorID := c.Param("vendorId")
+	if scope := gosharedmw.GetVendorScopeFilter(c); scope != "" && scope != vendorID {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Vendor users can only manage their own shipping settings",
+		})
+		return "", false
+	}
+	return vendorID, true
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"shipping-service/internal/tenancy"
 )
 
 // CORS middleware for handling Cross-Origin Resource Sharing
@@ -42,6 +44,8 @@ func TenantMiddleware() gin.HandlerFunc {
 
 		// Set tenant_id in context (even if empty - upstream middleware handles validation)
 		c.Set("tenant_id", tenantID)
+		// Scope database statements made with the request context (see internal/tenancy)
+		c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), tenantID))
 		c.Next()
 	}
 }
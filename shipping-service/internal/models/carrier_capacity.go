@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// CapacityPolicy decides what happens to a carrier's rates once it is past its pickup cut-off
+// or has reached its daily shipment cap
+type CapacityPolicy string
+
+const (
+	CapacityPolicyDeprioritize CapacityPolicy = "deprioritize" // Rates are flagged and listed after the other carriers' rates
+	CapacityPolicyExclude      CapacityPolicy = "exclude"      // Rates are dropped until the next day
+)
+
+// ValidateCapacitySettings checks the carrier's cut-off time, timezone and capacity policy
+func (c *ShippingCarrierConfig) ValidateCapacitySettings() error {
+	if c.PickupCutoffTime != "" {
+		if _, err := time.Parse("15:04", c.PickupCutoffTime); err != nil {
+			return fmt.Errorf("pickupCutoffTime must be HH:MM, got %q", c.PickupCutoffTime)
+		}
+	}
+	if c.CutoffTimezone != "" {
+		if _, err := time.LoadLocation(c.CutoffTimezone); err != nil {
+			return fmt.Errorf("cutoffTimezone must be an IANA timezone, got %q", c.CutoffTimezone)
+		}
+	}
+	switch c.CapacityPolicy {
+	case "", CapacityPolicyDeprioritize, CapacityPolicyExclude:
+	default:
+		return fmt.Errorf("capacityPolicy must be one of deprioritize, exclude")
+	}
+	if c.DailyShipmentCap < 0 {
+		return fmt.Errorf("dailyShipmentCap must not be negative")
+	}
+	return nil
+}
+
+// CutoffLocation returns the timezone the carrier's day and cut-off are counted in (UTC by default)
+func (c *ShippingCarrierConfig) CutoffLocation() *time.Location {
+	if c.CutoffTimezone != "" {
+		if loc, err := time.LoadLocation(c.CutoffTimezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// CarrierCapacityStatus is how much of today's capacity a carrier has left
+type CarrierCapacityStatus struct {
+	CarrierType      CarrierType    `json:"carrierType"`
+	DisplayName      string         `json:"displayName"`
+	DailyShipmentCap int            `json:"dailyShipmentCap"`    // 0 = unlimited
+	ShippedToday     int64          `json:"shippedToday"`        // Shipments booked since midnight, excluding cancelled and failed
+	Remaining        *int64         `json:"remaining,omitempty"` // Omitted when there is no cap
+	AtCapacity       bool           `json:"atCapacity"`
+	PickupCutoffTime string         `json:"pickupCutoffTime,omitempty"`
+	CutoffTimezone   string         `json:"cutoffTimezone"`
+	CutoffAt         *time.Time     `json:"cutoffAt,omitempty"` // Today's cut-off
+	PastCutoff       bool           `json:"pastCutoff"`
+	CapacityPolicy   CapacityPolicy `json:"capacityPolicy"`
+	NextPickupDate   string         `json:"nextPickupDate"` // YYYY-MM-DD in the carrier's timezone
+}
+
+// Constrained reports whether new shipments miss today's pickup
+func (s *CarrierCapacityStatus) Constrained() bool {
+	return s.AtCapacity || s.PastCutoff
+}
+
+// CarrierCapacityResponse lists today's capacity for a tenant's enabled carriers
+type CarrierCapacityResponse struct {
+	Success  bool                    `json:"success"`
+	Carriers []CarrierCapacityStatus `json:"carriers"`
+}
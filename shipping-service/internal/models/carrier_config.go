@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -126,12 +127,12 @@ type ShippingCarrierConfig struct {
 
 	// API Credentials
 	APIKeyPublic  string `gorm:"type:text" json:"apiKeyPublic"`
-	APIKeySecret  string `gorm:"type:text" json:"-"` // Never expose in JSON
-	WebhookSecret string `gorm:"type:text" json:"-"` // Never expose in JSON
+	APIKeySecret  string `gorm:"type:text;serializer:encrypted" json:"-"` // Never expose in JSON; encrypted with the tenant's data key
+	WebhookSecret string `gorm:"type:text;serializer:encrypted" json:"-"` // Never expose in JSON; encrypted with the tenant's data key
 	BaseURL       string `gorm:"type:text" json:"baseUrl,omitempty"`
 
 	// Carrier-specific credentials (e.g., Shiprocket email/password, FedEx account number)
-	Credentials JSONB `gorm:"type:jsonb" json:"-"` // Never expose credentials in JSON
+	Credentials JSONB `gorm:"type:text;serializer:encrypted" json:"-"` // Never expose credentials in JSON; stored as encrypted JSON
 
 	// Credential provider tracking (for GCP Secret Manager migration)
 	CredentialsProvisioned bool   `gorm:"default:false" json:"credentialsProvisioned"`
@@ -156,6 +157,24 @@ type ShippingCarrierConfig struct {
 	MaxLength float64 `gorm:"type:decimal(10,2)" json:"maxLength,omitempty"` // Max dimension in cm
 	MaxVolume float64 `gorm:"type:decimal(10,2)" json:"maxVolume,omitempty"` // Max volumetric weight
 
+	// Capacity: shipments booked per day (0 = unlimited) and the daily pickup cut-off, "HH:MM" in
+	// CutoffTimezone. Once either is reached, CapacityPolicy decides whether the carrier's rates
+	// are dropped or listed after the other carriers' rates.
+	DailyShipmentCap int            `gorm:"default:0" json:"dailyShipmentCap"`
+	PickupCutoffTime string         `gorm:"type:varchar(5)" json:"pickupCutoffTime,omitempty"`
+	CutoffTimezone   string         `gorm:"type:varchar(64)" json:"cutoffTimezone,omitempty"`
+	CapacityPolicy   CapacityPolicy `gorm:"type:varchar(20);default:'deprioritize'" json:"capacityPolicy"`
+
+	// Restricted goods the carrier accepts. No gorm defaults so a false on create is kept;
+	// the migration defaults existing rows.
+	AllowsDangerousGoods    bool `json:"allowsDangerousGoods"`    // Ground-shippable hazmat classes, domestic only
+	AllowsBatteries         bool `json:"allowsBatteries"`         // Lithium batteries on ground services
+	SupportsAgeVerification bool `json:"supportsAgeVerification"` // Adult signature / ID check on delivery
+
+	// UseNegotiatedRates prices the carrier from its active rate card instead of a live rate call,
+	// for lanes the card covers
+	UseNegotiatedRates bool `json:"useNegotiatedRates"`
+
 	// Display
 	Priority    int    `gorm:"default:0" json:"priority"`
 	Description string `gorm:"type:text" json:"description"`
@@ -163,6 +182,7 @@ type ShippingCarrierConfig struct {
 
 	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
 	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	Version   int       `gorm:"not null;default:1" json:"version"` // Optimistic locking, incremented on every update
 
 	// Relationships
 	Regions []ShippingCarrierRegion `gorm:"foreignKey:CarrierConfigID" json:"regions,omitempty"`
@@ -173,6 +193,16 @@ func (ShippingCarrierConfig) TableName() string {
 	return "shipping_carrier_configs"
 }
 
+// VersionConflictError is returned when a carrier config update was based on an
+// outdated version; CurrentVersion is the version now stored
+type VersionConflictError struct {
+	CurrentVersion int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("carrier config version conflict: current version is %d", e.CurrentVersion)
+}
+
 // HasCredentials returns true if the carrier has credentials configured
 func (c *ShippingCarrierConfig) HasCredentials() bool {
 	return c.APIKeyPublic != "" || c.APIKeySecret != "" || len(c.Credentials) > 0
@@ -434,9 +464,18 @@ type CarrierConfigResponse struct {
 	Description        string                 `json:"description"`
 	LogoURL            string                 `json:"logoUrl,omitempty"`
 	Config             JSONB                  `json:"config,omitempty"`
+	DailyShipmentCap   int                    `json:"dailyShipmentCap"`
+	PickupCutoffTime   string                 `json:"pickupCutoffTime,omitempty"`
+	CutoffTimezone     string                 `json:"cutoffTimezone,omitempty"`
+	CapacityPolicy     CapacityPolicy         `json:"capacityPolicy"`
+	AllowsDangerousGoods    bool              `json:"allowsDangerousGoods"`
+	AllowsBatteries         bool              `json:"allowsBatteries"`
+	SupportsAgeVerification bool              `json:"supportsAgeVerification"`
+	UseNegotiatedRates      bool              `json:"useNegotiatedRates"`
 	Regions            []ShippingCarrierRegion `json:"regions,omitempty"`
 	CreatedAt          time.Time              `json:"createdAt"`
 	UpdatedAt          time.Time              `json:"updatedAt"`
+	Version            int                    `json:"version"`
 }
 
 // ToResponse converts ShippingCarrierConfig to CarrierConfigResponse
@@ -460,9 +499,18 @@ func (c *ShippingCarrierConfig) ToResponse() CarrierConfigResponse {
 		Description:        c.Description,
 		LogoURL:            c.LogoURL,
 		Config:             c.Config,
+		DailyShipmentCap:   c.DailyShipmentCap,
+		PickupCutoffTime:   c.PickupCutoffTime,
+		CutoffTimezone:     c.CutoffTimezone,
+		CapacityPolicy:     c.CapacityPolicy,
+		AllowsDangerousGoods:    c.AllowsDangerousGoods,
+		AllowsBatteries:         c.AllowsBatteries,
+		SupportsAgeVerification: c.SupportsAgeVerification,
+		UseNegotiatedRates:      c.UseNegotiatedRates,
 		Regions:            c.Regions,
 		CreatedAt:          c.CreatedAt,
 		UpdatedAt:          c.UpdatedAt,
+		Version:            c.Version,
 	}
 }
 
@@ -482,6 +530,14 @@ type CreateCarrierConfigRequest struct {
 	SupportedServices  []string    `json:"supportedServices"`
 	Priority           int         `json:"priority"`
 	Description        string      `json:"description"`
+	DailyShipmentCap   int            `json:"dailyShipmentCap" binding:"min=0"`
+	PickupCutoffTime   string         `json:"pickupCutoffTime"`
+	CutoffTimezone     string         `json:"cutoffTimezone"`
+	CapacityPolicy     CapacityPolicy `json:"capacityPolicy"`
+	AllowsDangerousGoods    bool      `json:"allowsDangerousGoods"`
+	AllowsBatteries         *bool     `json:"allowsBatteries"` // Defaults to true
+	SupportsAgeVerification bool      `json:"supportsAgeVerification"`
+	UseNegotiatedRates      bool      `json:"useNegotiatedRates"`
 }
 
 // UpdateCarrierConfigRequest represents a request to update a carrier configuration
@@ -499,6 +555,16 @@ type UpdateCarrierConfigRequest struct {
 	SupportedServices  []string `json:"supportedServices"`
 	Priority           *int     `json:"priority"`
 	Description        *string  `json:"description"`
+	DailyShipmentCap   *int            `json:"dailyShipmentCap" binding:"omitempty,min=0"`
+	PickupCutoffTime   *string         `json:"pickupCutoffTime"` // Empty clears the cut-off
+	CutoffTimezone     *string         `json:"cutoffTimezone"`
+	CapacityPolicy     *CapacityPolicy `json:"capacityPolicy"`
+	AllowsDangerousGoods    *bool      `json:"allowsDangerousGoods"`
+	AllowsBatteries         *bool      `json:"allowsBatteries"`
+	SupportsAgeVerification *bool      `json:"supportsAgeVerification"`
+	UseNegotiatedRates      *bool      `json:"useNegotiatedRates"`
+	// ExpectedVersion rejects the update with 409 if the config changed meanwhile (If-Match takes precedence)
+	ExpectedVersion *int `json:"expectedVersion"`
 }
 
 // CreateCarrierFromTemplateRequest represents a request to create a carrier from a template
@@ -0,0 +1,336 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Where a rate came from
+const (
+	RateSourceLive       = "live"       // Fetched from the carrier's API
+	RateSourceCache      = "cache"      // Live rate served from the rate cache
+	RateSourceNegotiated = "negotiated" // Priced from the carrier's negotiated rate card
+)
+
+// Rate cache keys group requests by zone and weight bracket
+const (
+	RateZonePostalPrefix = 3   // Postal code characters in a rate cache zone
+	RateWeightBracket    = 0.5 // kg; chargeable weight is rounded up to this step
+	VolumetricDivisor    = 5000.0
+)
+
+// Rate card upload limits
+const (
+	MaxRateCardFileBytes = 2 << 20 // 2MB
+	MaxRateCardEntries   = 10000
+	MaxRateComparisons   = 50 // Lanes per comparison report; each is a live carrier call
+)
+
+// RateCard is a carrier's negotiated rate table, uploaded as CSV. Carriers with
+// UseNegotiatedRates set are priced from their active card instead of a live rate call; lanes
+// the card doesn't cover still go to the carrier.
+type RateCard struct {
+	ID            uuid.UUID   `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID      string      `gorm:"type:varchar(255);not null;index:idx_rate_cards_tenant_carrier" json:"tenantId"`
+	CarrierType   CarrierType `gorm:"type:varchar(50);not null;index:idx_rate_cards_tenant_carrier" json:"carrierType"`
+	Name          string      `gorm:"type:varchar(255);not null" json:"name"`
+	Currency      string      `gorm:"type:varchar(10);not null" json:"currency"`
+	IsActive      bool        `json:"isActive"` // One active card per carrier; uploading a card activates it
+	EffectiveFrom *time.Time  `json:"effectiveFrom,omitempty"`
+	EffectiveTo   *time.Time  `json:"effectiveTo,omitempty"`
+	FileName      string      `gorm:"type:varchar(255)" json:"fileName,omitempty"`
+	EntryCount    int         `json:"entryCount"`
+	UploadedBy    string      `gorm:"type:varchar(255)" json:"uploadedBy,omitempty"`
+
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+
+	Entries []RateCardEntry `gorm:"foreignKey:RateCardID;constraint:OnDelete:CASCADE" json:"entries,omitempty"`
+}
+
+// TableName specifies the table name for RateCard
+func (RateCard) TableName() string {
+	return "shipping_rate_cards"
+}
+
+// RateCardEntry prices one service between two zones for a weight range. Zones are "*" (any
+// address), a country code ("IN") or a country and postal code prefix ("IN:110").
+type RateCardEntry struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	RateCardID      uuid.UUID `gorm:"type:uuid;not null;index" json:"rateCardId"`
+	OriginZone      string    `gorm:"type:varchar(50);not null" json:"originZone"`
+	DestinationZone string    `gorm:"type:varchar(50);not null" json:"destinationZone"`
+	ServiceCode     string    `gorm:"type:varchar(100);not null" json:"serviceCode"`
+	ServiceName     string    `gorm:"type:varchar(255)" json:"serviceName"`
+	MinWeight       float64   `gorm:"type:decimal(10,3)" json:"minWeight"`       // kg, exclusive
+	MaxWeight       float64   `gorm:"type:decimal(10,3)" json:"maxWeight"`       // kg, inclusive; 0 = no upper limit
+	Rate            float64   `gorm:"type:decimal(10,2);not null" json:"rate"`   // Price at MinWeight
+	AdditionalPerKg float64   `gorm:"type:decimal(10,2)" json:"additionalPerKg"` // Added per kg above MinWeight
+	EstimatedDays   int       `json:"estimatedDays"`
+}
+
+// TableName specifies the table name for RateCardEntry
+func (RateCardEntry) TableName() string {
+	return "shipping_rate_card_entries"
+}
+
+// InEffect reports whether the card is active and within its effective dates
+func (c *RateCard) InEffect(now time.Time) bool {
+	if !c.IsActive {
+		return false
+	}
+	if c.EffectiveFrom != nil && now.Before(*c.EffectiveFrom) {
+		return false
+	}
+	if c.EffectiveTo != nil && now.After(*c.EffectiveTo) {
+		return false
+	}
+	return true
+}
+
+// RatesFor prices a request from the card: for each service, the entry whose zones match the
+// addresses most specifically and whose weight range holds the chargeable weight. It returns
+// nil when the card doesn't cover the lane.
+func (c *RateCard) RatesFor(request GetRatesRequest, now time.Time) []ShippingRate {
+	weight := ChargeableWeight(request.Weight, request.Length, request.Width, request.Height)
+
+	best := make(map[string]*RateCardEntry)
+	bestScore := make(map[string]int)
+	for i := range c.Entries {
+		entry := &c.Entries[i]
+		if !entry.CoversWeight(weight) {
+			continue
+		}
+		origin := ZoneSpecificity(entry.OriginZone, request.FromAddress)
+		destination := ZoneSpecificity(entry.DestinationZone, request.ToAddress)
+		if origin < 0 || destination < 0 {
+			continue
+		}
+		score := origin + destination
+		if current, ok := bestScore[entry.ServiceCode]; !ok || score > current {
+			best[entry.ServiceCode] = entry
+			bestScore[entry.ServiceCode] = score
+		}
+	}
+	if len(best) == 0 {
+		return nil
+	}
+
+	rates := make([]ShippingRate, 0, len(best))
+	for _, entry := range best {
+		rate := ShippingRate{
+			Carrier:       c.CarrierType,
+			ServiceName:   entry.ServiceName,
+			ServiceCode:   entry.ServiceCode,
+			Rate:          entry.PriceFor(weight),
+			Currency:      c.Currency,
+			EstimatedDays: entry.EstimatedDays,
+			Available:     true,
+			RateSource:    RateSourceNegotiated,
+		}
+		if entry.EstimatedDays > 0 {
+			delivery := now.AddDate(0, 0, entry.EstimatedDays)
+			rate.EstimatedDelivery = &delivery
+		}
+		rates = append(rates, rate)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].ServiceCode < rates[j].ServiceCode
+	})
+	return rates
+}
+
+// CoversWeight reports whether the entry's weight range holds the chargeable weight
+func (e *RateCardEntry) CoversWeight(weight float64) bool {
+	if weight <= e.MinWeight && e.MinWeight > 0 {
+		return false
+	}
+	return e.MaxWeight == 0 || weight <= e.MaxWeight
+}
+
+// PriceFor returns the entry's price for a chargeable weight
+func (e *RateCardEntry) PriceFor(weight float64) float64 {
+	price := e.Rate
+	if extra := weight - e.MinWeight; extra > 0 {
+		price += e.AdditionalPerKg * extra
+	}
+	return math.Round(price*100) / 100
+}
+
+// Validate checks an entry's zones, weights and prices
+func (e *RateCardEntry) Validate() error {
+	if e.ServiceCode == "" {
+		return fmt.Errorf("serviceCode is required")
+	}
+	for _, zone := range []struct{ name, value string }{{"originZone", e.OriginZone}, {"destinationZone", e.DestinationZone}} {
+		if !ValidRateZone(zone.value) {
+			return fmt.Errorf("%s must be *, a country code or COUNTRY:POSTALPREFIX, got %q", zone.name, zone.value)
+		}
+	}
+	if e.MinWeight < 0 || e.MaxWeight < 0 {
+		return fmt.Errorf("weights must not be negative")
+	}
+	if e.MaxWeight > 0 && e.MaxWeight <= e.MinWeight {
+		return fmt.Errorf("maxWeight must be greater than minWeight")
+	}
+	if e.Rate < 0 || e.AdditionalPerKg < 0 {
+		return fmt.Errorf("rates must not be negative")
+	}
+	if e.EstimatedDays < 0 {
+		return fmt.Errorf("estimatedDays must not be negative")
+	}
+	return nil
+}
+
+// ValidRateZone reports whether a rate card zone is "*", a two-letter country code, or a
+// country code and postal prefix separated by a colon
+func ValidRateZone(zone string) bool {
+	if zone == "*" {
+		return true
+	}
+	country, prefix, hasPrefix := strings.Cut(zone, ":")
+	if len(country) != 2 {
+		return false
+	}
+	return !hasPrefix || prefix != ""
+}
+
+// NormalizeRateZone upper-cases a zone and strips spaces from its postal prefix; blank is "*"
+func NormalizeRateZone(zone string) string {
+	zone = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(zone), " ", ""))
+	if zone == "" {
+		return "*"
+	}
+	return zone
+}
+
+// ZoneSpecificity reports how closely a rate card zone matches an address: -1 for no match,
+// 0 for "*", 1 for a country, and 1 plus the prefix length for a postal code prefix
+func ZoneSpecificity(zone string, addr Address) int {
+	if zone == "*" {
+		return 0
+	}
+	country, prefix, hasPrefix := strings.Cut(zone, ":")
+	if !strings.EqualFold(country, addr.Country) {
+		return -1
+	}
+	if !hasPrefix {
+		return 1
+	}
+	if !strings.HasPrefix(normalizePostalCode(addr.PostalCode), prefix) {
+		return -1
+	}
+	return 1 + len(prefix)
+}
+
+// RateZone is the zone an address falls in for rate caching: its country and the start of its
+// postal code
+func RateZone(addr Address) string {
+	postal := normalizePostalCode(addr.PostalCode)
+	if len(postal) > RateZonePostalPrefix {
+		postal = postal[:RateZonePostalPrefix]
+	}
+	return strings.ToUpper(addr.Country) + ":" + postal
+}
+
+// ChargeableWeight returns the greater of a package's actual weight and its volumetric weight
+// (L × W × H in cm / 5000), in kg
+func ChargeableWeight(weight, length, width, height float64) float64 {
+	volumetric := length * width * height / VolumetricDivisor
+	if volumetric > weight {
+		return volumetric
+	}
+	return weight
+}
+
+// WeightBracket rounds a chargeable weight up to the rate cache's weight step
+func WeightBracket(weight float64) float64 {
+	return math.Ceil(weight/RateWeightBracket) * RateWeightBracket
+}
+
+func normalizePostalCode(postal string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(postal), " ", ""))
+}
+
+// RateCardResponse lists a tenant's rate cards
+type RateCardResponse struct {
+	Success   bool       `json:"success"`
+	RateCards []RateCard `json:"rateCards"`
+}
+
+// RateCardUploadError lists the problems found in an uploaded rate card; nothing is saved
+type RateCardUploadError struct {
+	Errors []string
+}
+
+func (e *RateCardUploadError) Error() string {
+	return fmt.Sprintf("rate card has %d invalid row(s)", len(e.Errors))
+}
+
+// RateComparisonRequest asks for live and negotiated rates side by side. Without shipments,
+// the carrier's most recent shipments are used as sample lanes.
+type RateComparisonRequest struct {
+	CarrierType CarrierType              `json:"carrierType" binding:"required"`
+	RateCardID  *uuid.UUID               `json:"rateCardId"` // Defaults to the carrier's active card
+	Shipments   []RateComparisonShipment `json:"shipments"`
+	Limit       int                      `json:"limit"` // Recent shipments to sample (default 20, max 50)
+}
+
+// RateComparisonShipment is one sample lane for a rate comparison
+type RateComparisonShipment struct {
+	Reference   string  `json:"reference,omitempty"`
+	FromAddress Address `json:"fromAddress"`
+	ToAddress   Address `json:"toAddress"`
+	Weight      float64 `json:"weight"`
+	Length      float64 `json:"length"`
+	Width       float64 `json:"width"`
+	Height      float64 `json:"height"`
+}
+
+// RateComparisonService compares one service's live and negotiated rate on a lane. Savings is
+// live minus negotiated, so it is positive when the negotiated rate is cheaper.
+type RateComparisonService struct {
+	ServiceCode    string   `json:"serviceCode"`
+	ServiceName    string   `json:"serviceName"`
+	LiveRate       *float64 `json:"liveRate,omitempty"`
+	NegotiatedRate *float64 `json:"negotiatedRate,omitempty"`
+	Savings        *float64 `json:"savings,omitempty"`
+	SavingsPercent *float64 `json:"savingsPercent,omitempty"`
+}
+
+// RateComparisonLane compares a sample shipment's cheapest live and negotiated rates
+type RateComparisonLane struct {
+	Reference          string                  `json:"reference,omitempty"`
+	OriginZone         string                  `json:"originZone"`
+	DestinationZone    string                  `json:"destinationZone"`
+	ChargeableWeight   float64                 `json:"chargeableWeight"`
+	Services           []RateComparisonService `json:"services"`
+	CheapestLive       *float64                `json:"cheapestLive,omitempty"`
+	CheapestNegotiated *float64                `json:"cheapestNegotiated,omitempty"`
+	Savings            *float64                `json:"savings,omitempty"`
+	ErrorMessage       string                  `json:"errorMessage,omitempty"` // Live rate call failed
+}
+
+// RateComparisonReport compares a carrier's live rates with its negotiated rate card. Rates are
+// the carrier's own, before handling fees and markup. Totals cover lanes priced both ways.
+type RateComparisonReport struct {
+	Success                bool                 `json:"success"`
+	CarrierType            CarrierType          `json:"carrierType"`
+	RateCardID             uuid.UUID            `json:"rateCardId"`
+	RateCardName           string               `json:"rateCardName"`
+	Currency               string               `json:"currency"`
+	Lanes                  []RateComparisonLane `json:"lanes"`
+	LanesCompared          int                  `json:"lanesCompared"`
+	LanesWithoutNegotiated int                  `json:"lanesWithoutNegotiated"` // Not covered by the card
+	LanesWithoutLive       int                  `json:"lanesWithoutLive"`
+	TotalLive              float64              `json:"totalLive"`
+	TotalNegotiated        float64              `json:"totalNegotiated"`
+	TotalSavings           float64              `json:"totalSavings"`
+	SavingsPercent         float64              `json:"savingsPercent"`
+	GeneratedAt            time.Time            `json:"generatedAt"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShipFromLocationSource records where a ship-from location came from
+type ShipFromLocationSource string
+
+const (
+	ShipFromSourceManual    ShipFromLocationSource = "MANUAL"    // Added in shipping settings
+	ShipFromSourceInventory ShipFromLocationSource = "INVENTORY" // Synced from an inventory-service warehouse
+)
+
+// ShipFromLocation is an entry in the tenant's address book of warehouses and stores that
+// shipments leave from. Rate requests and shipments can name one instead of passing an address.
+type ShipFromLocation struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID string    `gorm:"type:varchar(255);not null;index;uniqueIndex:idx_ship_from_locations_warehouse" json:"tenantId"`
+	VendorID string    `gorm:"type:varchar(255);index" json:"vendorId,omitempty"` // Empty for the tenant's own locations
+
+	Name   string                 `gorm:"type:varchar(255);not null" json:"name"`
+	Code   string                 `gorm:"type:varchar(50)" json:"code,omitempty"`
+	Source ShipFromLocationSource `gorm:"type:varchar(20);not null;default:'MANUAL'" json:"source"`
+
+	// Inventory warehouse the location is synced from; nil for manual locations
+	WarehouseID *string `gorm:"type:varchar(255);uniqueIndex:idx_ship_from_locations_warehouse" json:"warehouseId,omitempty"`
+
+	Address     Address `gorm:"embedded;embeddedPrefix:address_" json:"address"`
+	ContactName string  `gorm:"type:varchar(255)" json:"contactName,omitempty"`
+
+	IsDefault bool `gorm:"default:false" json:"isDefault"` // Used when a request names no origin
+	IsActive  bool `gorm:"default:true" json:"isActive"`
+
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+}
+
+// TableName specifies the table name for ShipFromLocation
+func (ShipFromLocation) TableName() string {
+	return "ship_from_locations"
+}
+
+// IsSynced reports whether the location is managed by inventory-service
+func (l *ShipFromLocation) IsSynced() bool {
+	return l.Source == ShipFromSourceInventory
+}
+
+// ShipFromAddress returns the location as the from-address of a rate request or shipment
+func (l *ShipFromLocation) ShipFromAddress() Address {
+	addr := l.Address
+	if addr.Name == "" {
+		addr.Name = l.ContactName
+	}
+	if addr.Name == "" {
+		addr.Name = l.Name
+	}
+	return addr
+}
+
+// CreateShipFromLocationRequest represents a request to add a ship-from location
+type CreateShipFromLocationRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Code        string  `json:"code"`
+	VendorID    string  `json:"vendorId"`
+	Address     Address `json:"address" binding:"required"`
+	ContactName string  `json:"contactName"`
+	IsDefault   bool    `json:"isDefault"`
+}
+
+// UpdateShipFromLocationRequest represents a request to update a ship-from location.
+// Synced locations only accept IsDefault and IsActive; the rest comes from inventory-service.
+type UpdateShipFromLocationRequest struct {
+	Name        *string  `json:"name"`
+	Code        *string  `json:"code"`
+	Address     *Address `json:"address"`
+	ContactName *string  `json:"contactName"`
+	IsDefault   *bool    `json:"isDefault"`
+	IsActive    *bool    `json:"isActive"`
+}
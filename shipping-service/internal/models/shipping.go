@@ -59,6 +59,7 @@ type Shipment struct {
 	// Shipping details
 	FromAddress       Address         `json:"fromAddress" gorm:"embedded;embeddedPrefix:from_"`
 	ToAddress         Address         `json:"toAddress" gorm:"embedded;embeddedPrefix:to_"`
+	ShipFromLocationID *uuid.UUID     `json:"shipFromLocationId,omitempty" gorm:"type:uuid"` // Address book entry the shipment left from
 
 	// Package details
 	Weight            float64         `json:"weight" gorm:"type:decimal(10,2)"` // in kg
@@ -124,6 +125,10 @@ type ShippingRate struct {
 	EstimatedDays     int         `json:"estimatedDays"`
 	EstimatedDelivery *time.Time  `json:"estimatedDelivery"`
 	Available         bool        `json:"available"`
+	FreeShipping      bool        `json:"freeShipping,omitempty"` // Vendor free-shipping threshold met; Rate is 0
+	PastCutoff        bool        `json:"pastCutoff,omitempty"`   // Today's pickup cut-off has passed; ships with the next pickup
+	AtCapacity        bool        `json:"atCapacity,omitempty"`   // Carrier's daily shipment cap is reached; ships with the next pickup
+	RateSource        string      `json:"rateSource,omitempty"`   // live, cache or negotiated
 	ErrorMessage      string      `json:"errorMessage,omitempty"`
 }
 
@@ -133,6 +138,11 @@ type ShipmentItem struct {
 	SKU      string  `json:"sku"`
 	Quantity int     `json:"quantity"`
 	Price    float64 `json:"price"`
+
+	// Shipping restrictions, copied from the product
+	HazmatClass     string `json:"hazmatClass,omitempty"`     // UN dangerous goods class, e.g. "3" or "2.1"
+	ContainsBattery bool   `json:"containsBattery,omitempty"` // Lithium battery, packed with or in the item
+	AgeRestricted   bool   `json:"ageRestricted,omitempty"`   // Needs age verification on delivery
 }
 
 // CreateShipmentRequest represents a request to create a shipment
@@ -141,7 +151,8 @@ type CreateShipmentRequest struct {
 	OrderNumber        string         `json:"orderNumber" binding:"required"`
 	Carrier            string         `json:"carrier"`            // Optional - will auto-select if not provided
 	CourierServiceCode string         `json:"courierServiceCode"` // Carrier-specific courier ID for auto-assignment (e.g., Shiprocket courier_company_id)
-	FromAddress        Address        `json:"fromAddress" binding:"required"`
+	FromAddress        Address        `json:"fromAddress"`        // Optional when shipFromLocationId is set or a default location exists
+	ShipFromLocationID *uuid.UUID     `json:"shipFromLocationId"` // Ship-from address book entry; overrides fromAddress
 	ToAddress          Address        `json:"toAddress" binding:"required"`
 	Weight             float64        `json:"weight" binding:"required,gt=0"`
 	Length             float64        `json:"length" binding:"required,gt=0"`
@@ -155,13 +166,16 @@ type CreateShipmentRequest struct {
 
 // GetRatesRequest represents a request to get shipping rates
 type GetRatesRequest struct {
-	FromAddress   Address `json:"fromAddress" binding:"required"`
-	ToAddress     Address `json:"toAddress" binding:"required"`
-	Weight        float64 `json:"weight" binding:"required,gt=0"`
-	Length        float64 `json:"length" binding:"required,gt=0"`
-	Width         float64 `json:"width" binding:"required,gt=0"`
-	Height        float64 `json:"height" binding:"required,gt=0"`
-	DeclaredValue float64 `json:"declaredValue"` // Order/shipment value for accurate rate calculation
+	FromAddress        Address        `json:"fromAddress"`        // Optional when shipFromLocationId is set or a default location exists
+	ShipFromLocationID *uuid.UUID     `json:"shipFromLocationId"` // Ship-from address book entry; overrides fromAddress
+	ToAddress          Address        `json:"toAddress" binding:"required"`
+	Weight             float64        `json:"weight" binding:"required,gt=0"`
+	Length             float64        `json:"length" binding:"required,gt=0"`
+	Width              float64        `json:"width" binding:"required,gt=0"`
+	Height             float64        `json:"height" binding:"required,gt=0"`
+	DeclaredValue      float64        `json:"declaredValue"` // Order/shipment value for accurate rate calculation
+	VendorID           string         `json:"vendorId"`      // Optional - applies the vendor's shipping settings
+	Items              []ShipmentItem `json:"items"`         // Optional - checked against shipping restrictions
 }
 
 // TrackShipmentResponse represents tracking information
@@ -200,8 +214,9 @@ type ListShipmentsResponse struct {
 
 // GetRatesResponse represents shipping rates response
 type GetRatesResponse struct {
-	Success bool           `json:"success"`
-	Rates   []ShippingRate `json:"rates"`
+	Success    bool                           `json:"success"`
+	Rates      []ShippingRate                 `json:"rates"`
+	Violations []ShippingRestrictionViolation `json:"violations,omitempty"` // Carriers or services left out because of the items
 }
 
 // ReturnLabelRequest represents a request to generate a return shipping label
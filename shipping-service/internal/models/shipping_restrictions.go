@@ -0,0 +1,209 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RestrictionRule identifies which shipping restriction an item ran into
+type RestrictionRule string
+
+const (
+	RestrictionForbiddenHazmat     RestrictionRule = "FORBIDDEN_HAZMAT"     // Class no parcel carrier accepts
+	RestrictionHazmatInternational RestrictionRule = "HAZMAT_INTERNATIONAL" // Dangerous goods ship domestically only
+	RestrictionHazmatCarrier       RestrictionRule = "HAZMAT_CARRIER"       // Carrier doesn't accept dangerous goods
+	RestrictionBatteryCarrier      RestrictionRule = "BATTERY_CARRIER"      // Carrier doesn't accept lithium batteries
+	RestrictionAirService          RestrictionRule = "AIR_SERVICE"          // Hazmat and batteries can't fly
+	RestrictionAgeVerification     RestrictionRule = "AGE_VERIFICATION"     // Carrier can't verify the recipient's age
+	RestrictionNoEligibleCarrier   RestrictionRule = "NO_ELIGIBLE_CARRIER"  // Every carrier or service was ruled out
+)
+
+// forbiddenHazmatClasses are refused by parcel carriers outright: explosives, toxic gases,
+// infectious substances and radioactive material
+var forbiddenHazmatClasses = []string{"1", "2.3", "6.2", "7"}
+
+// ShippingRestrictionViolation explains why items can't ship, or can't ship with a carrier or service
+type ShippingRestrictionViolation struct {
+	Rule     RestrictionRule `json:"rule"`
+	SKU      string          `json:"sku,omitempty"`
+	ItemName string          `json:"itemName,omitempty"`
+	Carrier  CarrierType     `json:"carrier,omitempty"` // Set when only this carrier is ruled out
+	Service  string          `json:"service,omitempty"` // Set when only this service is ruled out
+	Blocking bool            `json:"blocking"`          // The package can't ship at all
+	Message  string          `json:"message"`
+}
+
+// ShippingRestrictionError is returned when restrictions leave no way to ship a package
+type ShippingRestrictionError struct {
+	Violations []ShippingRestrictionViolation
+}
+
+func (e *ShippingRestrictionError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		if v.Blocking {
+			messages = append(messages, v.Message)
+		}
+	}
+	return "shipping restricted: " + strings.Join(messages, "; ")
+}
+
+// ShippingRestrictionResponse is returned with 422 when a package can't ship
+type ShippingRestrictionResponse struct {
+	Error      string                         `json:"error"`
+	Message    string                         `json:"message"`
+	Violations []ShippingRestrictionViolation `json:"violations"`
+}
+
+// PackageRestrictions summarises what a package's items need from a carrier
+type PackageRestrictions struct {
+	DangerousGoods []ShipmentItem
+	Batteries      []ShipmentItem
+	AgeRestricted  []ShipmentItem
+	Violations     []ShippingRestrictionViolation // Blocking violations that rule out every carrier
+}
+
+// IsHazmat reports whether the item is classed as dangerous goods
+func (i *ShipmentItem) IsHazmat() bool {
+	return strings.TrimSpace(i.HazmatClass) != ""
+}
+
+// IsForbiddenHazmat reports whether no parcel carrier accepts the item's hazmat class
+func (i *ShipmentItem) IsForbiddenHazmat() bool {
+	class := strings.TrimSpace(i.HazmatClass)
+	for _, forbidden := range forbiddenHazmatClasses {
+		// A whole class covers its divisions ("1" covers "1.4")
+		if class == forbidden || (!strings.Contains(forbidden, ".") && strings.HasPrefix(class, forbidden+".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPackageRestrictions works out what the items need from a carrier, and flags items that
+// can't ship on this route at all
+func CheckPackageRestrictions(items []ShipmentItem, fromCountry, toCountry string) PackageRestrictions {
+	var r PackageRestrictions
+	international := fromCountry != "" && toCountry != "" && !strings.EqualFold(fromCountry, toCountry)
+
+	for _, item := range items {
+		switch {
+		case item.IsForbiddenHazmat():
+			r.Violations = append(r.Violations, ShippingRestrictionViolation{
+				Rule:     RestrictionForbiddenHazmat,
+				SKU:      item.SKU,
+				ItemName: item.Name,
+				Blocking: true,
+				Message:  fmt.Sprintf("%s is hazmat class %s, which parcel carriers don't accept", itemLabel(item), item.HazmatClass),
+			})
+		case item.IsHazmat() && international:
+			r.Violations = append(r.Violations, ShippingRestrictionViolation{
+				Rule:     RestrictionHazmatInternational,
+				SKU:      item.SKU,
+				ItemName: item.Name,
+				Blocking: true,
+				Message:  fmt.Sprintf("%s is hazmat class %s and can only ship within %s", itemLabel(item), item.HazmatClass, strings.ToUpper(fromCountry)),
+			})
+		case item.IsHazmat():
+			r.DangerousGoods = append(r.DangerousGoods, item)
+		}
+		if item.ContainsBattery {
+			r.Batteries = append(r.Batteries, item)
+		}
+		if item.AgeRestricted {
+			r.AgeRestricted = append(r.AgeRestricted, item)
+		}
+	}
+	return r
+}
+
+// Blocked reports whether the package can't ship with any carrier
+func (r *PackageRestrictions) Blocked() bool {
+	return len(r.Violations) > 0
+}
+
+// Restricted reports whether the package needs anything special from a carrier
+func (r *PackageRestrictions) Restricted() bool {
+	return len(r.DangerousGoods) > 0 || len(r.Batteries) > 0 || len(r.AgeRestricted) > 0
+}
+
+// GroundOnly reports whether the package must travel on a ground service
+func (r *PackageRestrictions) GroundOnly() bool {
+	return len(r.DangerousGoods) > 0 || len(r.Batteries) > 0
+}
+
+// CarrierViolations lists why a carrier can't take the package. A nil config means the
+// carrier's capabilities are unknown, so it is treated as accepting nothing restricted.
+func (r *PackageRestrictions) CarrierViolations(carrierType CarrierType, cfg *ShippingCarrierConfig) []ShippingRestrictionViolation {
+	var violations []ShippingRestrictionViolation
+	if len(r.DangerousGoods) > 0 && (cfg == nil || !cfg.AllowsDangerousGoods) {
+		item := r.DangerousGoods[0]
+		violations = append(violations, ShippingRestrictionViolation{
+			Rule:     RestrictionHazmatCarrier,
+			SKU:      item.SKU,
+			ItemName: item.Name,
+			Carrier:  carrierType,
+			Message:  fmt.Sprintf("%s doesn't accept dangerous goods (%s is hazmat class %s)", carrierType, itemLabel(item), item.HazmatClass),
+		})
+	}
+	if len(r.Batteries) > 0 && (cfg == nil || !cfg.AllowsBatteries) {
+		item := r.Batteries[0]
+		violations = append(violations, ShippingRestrictionViolation{
+			Rule:     RestrictionBatteryCarrier,
+			SKU:      item.SKU,
+			ItemName: item.Name,
+			Carrier:  carrierType,
+			Message:  fmt.Sprintf("%s doesn't accept lithium batteries (%s)", carrierType, itemLabel(item)),
+		})
+	}
+	if len(r.AgeRestricted) > 0 && (cfg == nil || !cfg.SupportsAgeVerification) {
+		item := r.AgeRestricted[0]
+		violations = append(violations, ShippingRestrictionViolation{
+			Rule:     RestrictionAgeVerification,
+			SKU:      item.SKU,
+			ItemName: item.Name,
+			Carrier:  carrierType,
+			Message:  fmt.Sprintf("%s can't verify the recipient's age, which %s requires", carrierType, itemLabel(item)),
+		})
+	}
+	return violations
+}
+
+// ServiceViolation explains why a service can't carry the package, or returns nil if it can
+func (r *PackageRestrictions) ServiceViolation(carrierType CarrierType, serviceName, serviceCode string) *ShippingRestrictionViolation {
+	if !r.GroundOnly() || !IsAirService(serviceName, serviceCode) {
+		return nil
+	}
+	item := append(append([]ShipmentItem{}, r.DangerousGoods...), r.Batteries...)[0]
+	return &ShippingRestrictionViolation{
+		Rule:     RestrictionAirService,
+		SKU:      item.SKU,
+		ItemName: item.Name,
+		Carrier:  carrierType,
+		Service:  serviceName,
+		Message:  fmt.Sprintf("%s %s is an air service; %s must ship by ground", carrierType, serviceName, itemLabel(item)),
+	}
+}
+
+// IsAirService reports whether a carrier service flies, judging by its name or code
+func IsAirService(serviceName, serviceCode string) bool {
+	s := strings.ToLower(serviceName + " " + serviceCode)
+	for _, keyword := range []string{"air", "express", "overnight", "next day", "next_day", "priority"} {
+		if strings.Contains(s, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func itemLabel(item ShipmentItem) string {
+	switch {
+	case item.Name != "" && item.SKU != "":
+		return fmt.Sprintf("%s (%s)", item.Name, item.SKU)
+	case item.Name != "":
+		return item.Name
+	case item.SKU != "":
+		return item.SKU
+	}
+	return "An item"
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VendorShippingSettings overrides the tenant's shipping settings for one marketplace vendor.
+// The rate engine consults it whenever a rate request carries the vendor's ID.
+type VendorShippingSettings struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TenantID string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_vendor_shipping_settings_vendor" json:"tenantId"`
+	VendorID string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_vendor_shipping_settings_vendor" json:"vendorId"`
+
+	// Origin the vendor ships from; empty falls back to the tenant warehouse
+	Origin Address `gorm:"embedded;embeddedPrefix:origin_" json:"origin"`
+
+	// Carriers the vendor ships with; empty allows every carrier the tenant has enabled
+	AllowedCarriers StringArray `gorm:"type:text[]" json:"allowedCarriers"`
+
+	// Fee overrides; nil uses the tenant's handling fee and markup
+	HandlingFee        *float64 `gorm:"type:decimal(10,2)" json:"handlingFee,omitempty"`
+	HandlingFeePercent *float64 `gorm:"type:decimal(5,4)" json:"handlingFeePercent,omitempty"`
+
+	// Free shipping when the vendor's share of the cart reaches the minimum
+	FreeShippingEnabled bool    `gorm:"default:false" json:"freeShippingEnabled"`
+	FreeShippingMinimum float64 `gorm:"type:decimal(10,2)" json:"freeShippingMinimum,omitempty"`
+
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+}
+
+// TableName specifies the table name for VendorShippingSettings
+func (VendorShippingSettings) TableName() string {
+	return "vendor_shipping_settings"
+}
+
+// HasOrigin reports whether the vendor configured its own ship-from address
+func (v *VendorShippingSettings) HasOrigin() bool {
+	return v.Origin.Country != "" && v.Origin.PostalCode != ""
+}
+
+// AllowsCarrier reports whether the vendor ships with the given carrier
+func (v *VendorShippingSettings) AllowsCarrier(carrierType CarrierType) bool {
+	if len(v.AllowedCarriers) == 0 {
+		return true
+	}
+	for _, allowed := range v.AllowedCarriers {
+		if strings.EqualFold(allowed, string(carrierType)) {
+			return true
+		}
+	}
+	return false
+}
+
+// QualifiesForFreeShipping reports whether a package of the given value ships free
+func (v *VendorShippingSettings) QualifiesForFreeShipping(declaredValue float64) bool {
+	return v.FreeShippingEnabled && declaredValue > 0 && declaredValue >= v.FreeShippingMinimum
+}
+
+// UpdateVendorShippingSettingsRequest represents a request to create or update vendor shipping settings
+type UpdateVendorShippingSettingsRequest struct {
+	Origin              *Address  `json:"origin"`
+	AllowedCarriers     *[]string `json:"allowedCarriers"`
+	HandlingFee         *float64  `json:"handlingFee" binding:"omitempty,gte=0"`
+	HandlingFeePercent  *float64  `json:"handlingFeePercent" binding:"omitempty,gte=0,lte=5"`
+	ClearFeeOverrides   bool      `json:"clearFeeOverrides"` // Go back to the tenant's fees
+	FreeShippingEnabled *bool     `json:"freeShippingEnabled"`
+	FreeShippingMinimum *float64  `json:"freeShippingMinimum" binding:"omitempty,gte=0"`
+}
+
+// CartPackage is the part of a cart shipped by one vendor
+type CartPackage struct {
+	VendorID           string         `json:"vendorId"`           // Empty for items the tenant ships itself
+	FromAddress        *Address       `json:"fromAddress"`        // Used when the vendor has no origin configured; defaults to the default ship-from location
+	ShipFromLocationID *uuid.UUID     `json:"shipFromLocationId"` // Ship-from address book entry; overrides fromAddress
+	Weight             float64        `json:"weight" binding:"required,gt=0"`
+	Length             float64        `json:"length" binding:"required,gt=0"`
+	Width              float64        `json:"width" binding:"required,gt=0"`
+	Height             float64        `json:"height" binding:"required,gt=0"`
+	DeclaredValue      float64        `json:"declaredValue"` // Value of the vendor's items, used for free-shipping thresholds
+	Items              []ShipmentItem `json:"items"`         // Checked against shipping restrictions
+}
+
+// GetCartRatesRequest represents a request to rate a cart shipped by one or more vendors
+type GetCartRatesRequest struct {
+	ToAddress Address       `json:"toAddress" binding:"required"`
+	Packages  []CartPackage `json:"packages" binding:"required,min=1,max=50,dive"`
+}
+
+// VendorRateGroup holds the rates for one vendor's package in a cart
+type VendorRateGroup struct {
+	VendorID     string                         `json:"vendorId,omitempty"`
+	FromAddress  Address                        `json:"fromAddress"`
+	FreeShipping bool                           `json:"freeShipping"`
+	Rates        []ShippingRate                 `json:"rates"`
+	ErrorMessage string                         `json:"errorMessage,omitempty"`
+	Violations   []ShippingRestrictionViolation `json:"violations,omitempty"`
+}
+
+// GetCartRatesResponse represents cart shipping rates grouped by vendor
+type GetCartRatesResponse struct {
+	Success bool              `json:"success"`
+	Groups  []VendorRateGroup `json:"groups"`
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Tesseract-Nexus/go-shared/cache"
 	"shipping-service/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Cache TTL constants for carrier config
@@ -195,10 +196,44 @@ func (r *CarrierConfigRepository) CreateCarrierConfig(ctx context.Context, confi
 	return r.db.WithContext(ctx).Create(config).Error
 }
 
-// UpdateCarrierConfig updates a carrier configuration
+// UpdateCarrierConfig updates a carrier configuration.
+// The update is conditional on config.Version still being the stored version; otherwise
+// a *models.VersionConflictError is returned. Regions are left untouched.
 func (r *CarrierConfigRepository) UpdateCarrierConfig(ctx context.Context, config *models.ShippingCarrierConfig) error {
+	expected := config.Version
 	config.UpdatedAt = time.Now()
-	return r.db.WithContext(ctx).Save(config).Error
+	config.Version = expected + 1
+
+	result := r.db.WithContext(ctx).Model(config).
+		Where("version = ?", expected).
+		Select("*").Omit(clause.Associations).
+		Updates(config)
+	if result.Error != nil {
+		config.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		config.Version = expected
+		var current models.ShippingCarrierConfig
+		if err := r.db.WithContext(ctx).Select("version").Where("id = ?", config.ID).First(&current).Error; err != nil {
+			return fmt.Errorf("carrier config not found: %w", err)
+		}
+		return &models.VersionConflictError{CurrentVersion: current.Version}
+	}
+	// Credentials or test mode may have changed the carrier's prices
+	r.InvalidateRateCache(ctx, config.TenantID, config.CarrierType)
+	return nil
+}
+
+// CountShipmentsSince counts a tenant's shipments booked with a carrier since the given time,
+// excluding cancelled and failed shipments, for daily capacity limits
+func (r *CarrierConfigRepository) CountShipmentsSince(ctx context.Context, tenantID string, carrierType models.CarrierType, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Shipment{}).
+		Where("tenant_id = ? AND carrier = ? AND created_at >= ?", tenantID, carrierType, since).
+		Where("status NOT IN ?", []models.ShipmentStatus{models.ShipmentStatusCancelled, models.ShipmentStatusFailed}).
+		Count(&count).Error
+	return count, err
 }
 
 // DeleteCarrierConfig deletes a carrier configuration and its regions
@@ -366,6 +401,313 @@ func (r *CarrierConfigRepository) UpdateShippingSettings(ctx context.Context, se
 	return r.db.WithContext(ctx).Save(settings).Error
 }
 
+// ==================== Vendor Shipping Settings Methods ====================
+
+// GetVendorShippingSettings gets a vendor's shipping settings
+func (r *CarrierConfigRepository) GetVendorShippingSettings(ctx context.Context, tenantID, vendorID string) (*models.VendorShippingSettings, error) {
+	var settings models.VendorShippingSettings
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND vendor_id = ?", tenantID, vendorID).
+		First(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// ListVendorShippingSettings lists every vendor's shipping settings for a tenant
+func (r *CarrierConfigRepository) ListVendorShippingSettings(ctx context.Context, tenantID string) ([]models.VendorShippingSettings, error) {
+	var settings []models.VendorShippingSettings
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ?", tenantID).
+		Order("vendor_id ASC").
+		Find(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SaveVendorShippingSettings creates or updates a vendor's shipping settings
+func (r *CarrierConfigRepository) SaveVendorShippingSettings(ctx context.Context, settings *models.VendorShippingSettings) error {
+	settings.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(settings).Error
+}
+
+// DeleteVendorShippingSettings deletes a vendor's shipping settings so it uses the tenant's again
+func (r *CarrierConfigRepository) DeleteVendorShippingSettings(ctx context.Context, tenantID, vendorID string) error {
+	result := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND vendor_id = ?", tenantID, vendorID).
+		Delete(&models.VendorShippingSettings{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ==================== Ship-From Location Methods ====================
+
+// ListShipFromLocations lists a tenant's ship-from locations, default first. A non-empty
+// vendorID limits the list to that vendor's locations.
+func (r *CarrierConfigRepository) ListShipFromLocations(ctx context.Context, tenantID, vendorID string, activeOnly bool) ([]models.ShipFromLocation, error) {
+	var locations []models.ShipFromLocation
+	query := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
+	if vendorID != "" {
+		query = query.Where("vendor_id = ?", vendorID)
+	}
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("is_default DESC, name ASC").Find(&locations).Error
+	if err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// GetShipFromLocation gets a ship-from location by ID
+func (r *CarrierConfigRepository) GetShipFromLocation(ctx context.Context, tenantID string, id uuid.UUID) (*models.ShipFromLocation, error) {
+	var location models.ShipFromLocation
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&location).Error
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// GetShipFromLocationByWarehouse gets the location synced from an inventory warehouse
+func (r *CarrierConfigRepository) GetShipFromLocationByWarehouse(ctx context.Context, tenantID, warehouseID string) (*models.ShipFromLocation, error) {
+	var location models.ShipFromLocation
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND warehouse_id = ?", tenantID, warehouseID).
+		First(&location).Error
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// GetDefaultShipFromLocation gets the active location used when a request names no origin.
+// A vendor's own default wins over the tenant's.
+func (r *CarrierConfigRepository) GetDefaultShipFromLocation(ctx context.Context, tenantID, vendorID string) (*models.ShipFromLocation, error) {
+	var location models.ShipFromLocation
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND is_default = ? AND is_active = ?", tenantID, true, true).
+		Where("vendor_id = ? OR vendor_id = '' OR vendor_id IS NULL", vendorID).
+		Order("vendor_id DESC NULLS LAST").
+		First(&location).Error
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// SaveShipFromLocation creates or updates a ship-from location. Marking it default clears the
+// flag on the other locations of the same owner (tenant or vendor).
+func (r *CarrierConfigRepository) SaveShipFromLocation(ctx context.Context, location *models.ShipFromLocation) error {
+	location.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if location.IsDefault {
+			if err := tx.Model(&models.ShipFromLocation{}).
+				Where("tenant_id = ? AND COALESCE(vendor_id, '') = ? AND id <> ?", location.TenantID, location.VendorID, location.ID).
+				Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(location).Error
+	})
+}
+
+// DeleteShipFromLocation deletes a ship-from location
+func (r *CarrierConfigRepository) DeleteShipFromLocation(ctx context.Context, tenantID string, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		Delete(&models.ShipFromLocation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ==================== Rate Cache Methods ====================
+
+// rateCacheKey keys cached carrier rates by tenant, carrier, origin and destination zone and
+// weight bracket
+func rateCacheKey(tenantID string, carrierType models.CarrierType, originZone, destinationZone string, weightBracket float64) string {
+	return fmt.Sprintf("rates:%s:%s:%s:%s:%.1f", tenantID, carrierType, originZone, destinationZone, weightBracket)
+}
+
+// GetCachedRates returns a carrier's cached rates for a zone pair and weight bracket. It
+// reports false on a miss or when caching is unavailable.
+func (r *CarrierConfigRepository) GetCachedRates(ctx context.Context, tenantID string, carrierType models.CarrierType, originZone, destinationZone string, weightBracket float64) ([]models.ShippingRate, bool) {
+	if r.cache == nil {
+		return nil, false
+	}
+	var rates []models.ShippingRate
+	key := rateCacheKey(tenantID, carrierType, originZone, destinationZone, weightBracket)
+	if err := r.cache.GetJSON(ctx, key, &rates); err != nil {
+		return nil, false
+	}
+	return rates, true
+}
+
+// CacheRates stores a carrier's rates for a zone pair and weight bracket
+func (r *CarrierConfigRepository) CacheRates(ctx context.Context, tenantID string, carrierType models.CarrierType, originZone, destinationZone string, weightBracket float64, rates []models.ShippingRate, ttl time.Duration) {
+	if r.cache == nil {
+		return
+	}
+	key := rateCacheKey(tenantID, carrierType, originZone, destinationZone, weightBracket)
+	_ = r.cache.SetJSON(ctx, key, rates, ttl)
+}
+
+// InvalidateRateCache drops a tenant's cached rates, for one carrier or, with an empty
+// carrierType, for all of them
+func (r *CarrierConfigRepository) InvalidateRateCache(ctx context.Context, tenantID string, carrierType models.CarrierType) error {
+	if r.cache == nil {
+		return nil
+	}
+	pattern := fmt.Sprintf("rates:%s:*", tenantID)
+	if carrierType != "" {
+		pattern = fmt.Sprintf("rates:%s:%s:*", tenantID, carrierType)
+	}
+	return r.cache.DeletePattern(ctx, pattern)
+}
+
+// ==================== Rate Card Methods ====================
+
+// ListRateCards lists a tenant's rate cards without their entries, newest first. A non-empty
+// carrierType limits the list to that carrier's cards.
+func (r *CarrierConfigRepository) ListRateCards(ctx context.Context, tenantID string, carrierType models.CarrierType) ([]models.RateCard, error) {
+	var cards []models.RateCard
+	query := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
+	if carrierType != "" {
+		query = query.Where("carrier_type = ?", carrierType)
+	}
+	err := query.Order("created_at DESC").Find(&cards).Error
+	if err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// GetRateCard gets a rate card with its entries
+func (r *CarrierConfigRepository) GetRateCard(ctx context.Context, tenantID string, id uuid.UUID) (*models.RateCard, error) {
+	var card models.RateCard
+	err := r.db.WithContext(ctx).
+		Preload("Entries", func(db *gorm.DB) *gorm.DB {
+			return db.Order("service_code ASC, origin_zone ASC, destination_zone ASC, min_weight ASC")
+		}).
+		Where("id = ? AND tenant_id = ?", id, tenantID).
+		First(&card).Error
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// GetActiveRateCard gets a carrier's active rate card with its entries
+func (r *CarrierConfigRepository) GetActiveRateCard(ctx context.Context, tenantID string, carrierType models.CarrierType) (*models.RateCard, error) {
+	var card models.RateCard
+	err := r.db.WithContext(ctx).
+		Preload("Entries").
+		Where("tenant_id = ? AND carrier_type = ? AND is_active = ?", tenantID, carrierType, true).
+		Order("created_at DESC").
+		First(&card).Error
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// CreateRateCard saves an uploaded rate card and its entries. An active card replaces the
+// carrier's previously active card.
+func (r *CarrierConfigRepository) CreateRateCard(ctx context.Context, card *models.RateCard) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if card.IsActive {
+			if err := tx.Model(&models.RateCard{}).
+				Where("tenant_id = ? AND carrier_type = ? AND is_active = ?", card.TenantID, card.CarrierType, true).
+				Updates(map[string]interface{}{"is_active": false, "updated_at": time.Now()}).Error; err != nil {
+				return err
+			}
+		}
+
+		entries := card.Entries
+		card.Entries = nil
+		if err := tx.Create(card).Error; err != nil {
+			return err
+		}
+		for i := range entries {
+			entries[i].RateCardID = card.ID
+		}
+		if len(entries) > 0 {
+			if err := tx.CreateInBatches(entries, 500).Error; err != nil {
+				return err
+			}
+		}
+		card.Entries = entries
+		return nil
+	})
+}
+
+// ActivateRateCard makes a rate card the carrier's active card
+func (r *CarrierConfigRepository) ActivateRateCard(ctx context.Context, tenantID string, id uuid.UUID) (*models.RateCard, error) {
+	var card models.RateCard
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND tenant_id = ?", id, tenantID).First(&card).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := tx.Model(&models.RateCard{}).
+			Where("tenant_id = ? AND carrier_type = ? AND id <> ? AND is_active = ?", tenantID, card.CarrierType, id, true).
+			Updates(map[string]interface{}{"is_active": false, "updated_at": now}).Error; err != nil {
+			return err
+		}
+		card.IsActive = true
+		card.UpdatedAt = now
+		return tx.Model(&card).Updates(map[string]interface{}{"is_active": true, "updated_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// DeleteRateCard deletes a rate card and its entries
+func (r *CarrierConfigRepository) DeleteRateCard(ctx context.Context, tenantID string, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND tenant_id = ?", id, tenantID).Delete(&models.RateCard{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("rate_card_id = ?", id).Delete(&models.RateCardEntry{}).Error
+	})
+}
+
+// ListRecentShipments lists a tenant's latest shipments with a carrier, for sampling lanes
+func (r *CarrierConfigRepository) ListRecentShipments(ctx context.Context, tenantID string, carrierType models.CarrierType, limit int) ([]models.Shipment, error) {
+	var shipments []models.Shipment
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND carrier = ? AND weight > 0", tenantID, carrierType).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&shipments).Error
+	if err != nil {
+		return nil, err
+	}
+	return shipments, nil
+}
+
 // ==================== Utility Methods ====================
 
 // CarrierExistsForTenant checks if a carrier type already exists for a tenant
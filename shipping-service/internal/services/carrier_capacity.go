@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"shipping-service/internal/models"
+)
+
+// carrierCapacity works out how much of today's capacity a carrier has left. The day runs from
+// midnight to midnight in the carrier's cut-off timezone.
+func (s *CarrierSelectorService) carrierCapacity(ctx context.Context, cfg *models.ShippingCarrierConfig, now time.Time) models.CarrierCapacityStatus {
+	loc := cfg.CutoffLocation()
+	localNow := now.In(loc)
+	startOfDay := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, loc)
+
+	policy := cfg.CapacityPolicy
+	if policy == "" {
+		policy = models.CapacityPolicyDeprioritize
+	}
+	status := models.CarrierCapacityStatus{
+		CarrierType:      cfg.CarrierType,
+		DisplayName:      cfg.DisplayName,
+		DailyShipmentCap: cfg.DailyShipmentCap,
+		PickupCutoffTime: cfg.PickupCutoffTime,
+		CutoffTimezone:   loc.String(),
+		CapacityPolicy:   policy,
+	}
+
+	if cfg.PickupCutoffTime != "" {
+		if cutoff, err := time.Parse("15:04", cfg.PickupCutoffTime); err == nil {
+			cutoffAt := startOfDay.Add(time.Duration(cutoff.Hour())*time.Hour + time.Duration(cutoff.Minute())*time.Minute)
+			status.CutoffAt = &cutoffAt
+			status.PastCutoff = !localNow.Before(cutoffAt)
+		}
+	}
+
+	if cfg.DailyShipmentCap > 0 {
+		shipped, err := s.repo.CountShipmentsSince(ctx, cfg.TenantID, cfg.CarrierType, startOfDay)
+		if err != nil {
+			// Without a count the cap can't be enforced; don't hold back the carrier
+			log.Printf("Failed to count today's %s shipments for tenant %s: %v", cfg.CarrierType, cfg.TenantID, err)
+		} else {
+			remaining := int64(cfg.DailyShipmentCap) - shipped
+			if remaining < 0 {
+				remaining = 0
+			}
+			status.ShippedToday = shipped
+			status.Remaining = &remaining
+			status.AtCapacity = remaining == 0
+		}
+	}
+
+	nextPickup := startOfDay
+	if status.Constrained() {
+		nextPickup = nextPickup.AddDate(0, 0, 1)
+	}
+	status.NextPickupDate = nextPickup.Format("2006-01-02")
+
+	return status
+}
+
+// GetCarrierCapacity returns today's remaining capacity and cut-off for each enabled carrier
+func (s *CarrierSelectorService) GetCarrierCapacity(ctx context.Context, tenantID string) ([]models.CarrierCapacityStatus, error) {
+	configs, err := s.repo.ListEnabledCarrierConfigs(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list carrier configs: %w", err)
+	}
+
+	now := time.Now()
+	statuses := make([]models.CarrierCapacityStatus, 0, len(configs))
+	for i := range configs {
+		statuses = append(statuses, s.carrierCapacity(ctx, &configs[i], now))
+	}
+	return statuses, nil
+}
+
+// flagConstrainedRates marks rates from a carrier that misses today's pickup. Those shipments
+// go out with the next pickup, so their delivery estimate moves back a day.
+func flagConstrainedRates(rates []models.ShippingRate, status models.CarrierCapacityStatus) []models.ShippingRate {
+	if !status.Constrained() {
+		return rates
+	}
+	for i := range rates {
+		rates[i].PastCutoff = status.PastCutoff
+		rates[i].AtCapacity = status.AtCapacity
+		if rates[i].EstimatedDays > 0 {
+			rates[i].EstimatedDays++
+		}
+		if rates[i].EstimatedDelivery != nil {
+			delivery := rates[i].EstimatedDelivery.AddDate(0, 0, 1)
+			rates[i].EstimatedDelivery = &delivery
+		}
+	}
+	return rates
+}
+
+// allRatesConstrained reports whether every rate is from a carrier that misses today's pickup
+func allRatesConstrained(rates []models.ShippingRate) bool {
+	for _, rate := range rates {
+		if !rate.PastCutoff && !rate.AtCapacity {
+			return false
+		}
+	}
+	return true
+}
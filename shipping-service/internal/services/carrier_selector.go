@@ -6,6 +6,7 @@ import (
 	"log"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Tesseract-Nexus/go-shared/secrets"
 	"github.com/google/uuid"
@@ -378,10 +379,16 @@ func (s *CarrierSelectorService) GetCarrierByType(ctx context.Context, tenantID
 
 // GetRatesFromAllCarriers gets rates from all available carriers for a route
 func (s *CarrierSelectorService) GetRatesFromAllCarriers(ctx context.Context, tenantID string, request models.GetRatesRequest) ([]models.ShippingRate, error) {
+	return s.GetRatesForVendor(ctx, tenantID, request, nil)
+}
+
+// GetRatesForVendor gets rates from all available carriers for a route, applying the vendor's
+// allowed carriers, fee overrides and free-shipping threshold when vendor is not nil
+func (s *CarrierSelectorService) GetRatesForVendor(ctx context.Context, tenantID string, request models.GetRatesRequest, vendor *models.VendorShippingSettings) ([]models.ShippingRate, error) {
 	fromCountry := strings.ToUpper(request.FromAddress.Country)
 	toCountry := strings.ToUpper(request.ToAddress.Country)
 
-	log.Printf("GetRatesFromAllCarriers: tenant=%s, route=%s->%s", tenantID, fromCountry, toCountry)
+	log.Printf("GetRatesFromAllCarriers: tenant=%s, vendor=%s, route=%s->%s", tenantID, request.VendorID, fromCountry, toCountry)
 
 	// Get shipping settings for markup configuration
 	settings, _ := s.repo.GetShippingSettings(ctx, tenantID)
@@ -390,8 +397,16 @@ func (s *CarrierSelectorService) GetRatesFromAllCarriers(ctx context.Context, te
 	if settings != nil {
 		markupPercent = settings.HandlingFeePercent
 		handlingFee = settings.HandlingFee
-		log.Printf("GetRatesFromAllCarriers: markup settings - percent=%.2f%%, fixed=%.2f", markupPercent*100, handlingFee)
 	}
+	if vendor != nil {
+		if vendor.HandlingFeePercent != nil {
+			markupPercent = *vendor.HandlingFeePercent
+		}
+		if vendor.HandlingFee != nil {
+			handlingFee = *vendor.HandlingFee
+		}
+	}
+	log.Printf("GetRatesFromAllCarriers: markup settings - percent=%.2f%%, fixed=%.2f", markupPercent*100, handlingFee)
 
 	// Get available carriers
 	configs, err := s.repo.ListEnabledCarrierConfigs(ctx, tenantID)
@@ -401,7 +416,8 @@ func (s *CarrierSelectorService) GetRatesFromAllCarriers(ctx context.Context, te
 		if err != nil {
 			return nil, err
 		}
-		return s.applyMarkupToRates(rates, markupPercent, handlingFee), nil
+		rates = s.applyMarkupToRates(filterRatesForVendor(rates, vendor), markupPercent, handlingFee)
+		return applyVendorFreeShipping(rates, vendor, request.DeclaredValue), nil
 	}
 	if len(configs) == 0 {
 		log.Printf("GetRatesFromAllCarriers: no configs found for tenant %s", tenantID)
@@ -409,7 +425,8 @@ func (s *CarrierSelectorService) GetRatesFromAllCarriers(ctx context.Context, te
 		if err != nil {
 			return nil, err
 		}
-		return s.applyMarkupToRates(rates, markupPercent, handlingFee), nil
+		rates = s.applyMarkupToRates(filterRatesForVendor(rates, vendor), markupPercent, handlingFee)
+		return applyVendorFreeShipping(rates, vendor, request.DeclaredValue), nil
 	}
 
 	log.Printf("GetRatesFromAllCarriers: found %d carrier configs", len(configs))
@@ -422,6 +439,21 @@ func (s *CarrierSelectorService) GetRatesFromAllCarriers(ctx context.Context, te
 		fallbackType = settings.FallbackCarrierType
 	}
 
+	// Vendors restricted to certain carriers only rate with those; if the tenant's preferred
+	// carrier isn't one of them, the vendor's highest-priority allowed carrier takes its place
+	if vendor != nil && len(vendor.AllowedCarriers) > 0 {
+		configs = filterConfigsForVendor(configs, vendor)
+		if len(configs) == 0 {
+			return nil, fmt.Errorf("none of vendor %s's allowed carriers are enabled", vendor.VendorID)
+		}
+		if !vendor.AllowsCarrier(models.CarrierType(preferredType)) {
+			preferredType = string(configs[0].CarrierType)
+		}
+		if !vendor.AllowsCarrier(models.CarrierType(fallbackType)) {
+			fallbackType = ""
+		}
+	}
+
 	sort.Slice(configs, func(i, j int) bool {
 		iType := string(configs[i].CarrierType)
 		jType := string(configs[j].CarrierType)
@@ -447,6 +479,8 @@ func (s *CarrierSelectorService) GetRatesFromAllCarriers(ctx context.Context, te
 	var allRates []models.ShippingRate
 	var preferredRates []models.ShippingRate
 	var fallbackRates []models.ShippingRate
+	now := time.Now()
+	cacheTTL := rateCacheTTL(settings)
 
 	// Try to get rates from preferred and fallback carriers only
 	for _, cfg := range configs {
@@ -468,17 +502,20 @@ func (s *CarrierSelectorService) GetRatesFromAllCarriers(ctx context.Context, te
 			continue
 		}
 
-		carrier, err := s.createCarrierForConfig(ctx, &cfg)
-		if err != nil {
-			log.Printf("Failed to create carrier %s: %v", cfg.CarrierType, err)
+		// Carriers past their pickup cut-off or daily cap are skipped or flagged per their policy
+		capacity := s.carrierCapacity(ctx, &cfg, now)
+		if capacity.Constrained() && capacity.CapacityPolicy == models.CapacityPolicyExclude {
+			log.Printf("GetRatesFromAllCarriers: skipping carrier %s (past cut-off=%v, at capacity=%v)", cfg.CarrierType, capacity.PastCutoff, capacity.AtCapacity)
 			continue
 		}
 
-		rates, err := carrier.GetRates(request)
+		// Negotiated rate card, rate cache or live rates, before markup
+		rates, err := s.carrierRates(ctx, &cfg, request, cacheTTL, now)
 		if err != nil {
 			log.Printf("Failed to get rates from %s: %v", cfg.CarrierType, err)
 			continue
 		}
+		rates = flagConstrainedRates(rates, capacity)
 
 		if len(rates) > 0 {
 			log.Printf("Got %d rates from %s", len(rates), cfg.CarrierType)
@@ -490,8 +527,12 @@ func (s *CarrierSelectorService) GetRatesFromAllCarriers(ctx context.Context, te
 		}
 	}
 
-	// Use preferred carrier rates if available, otherwise use fallback
-	if len(preferredRates) > 0 {
+	// Use preferred carrier rates if available, otherwise use fallback. A preferred carrier that
+	// misses today's pickup is offered after the fallback rather than instead of it.
+	if len(preferredRates) > 0 && allRatesConstrained(preferredRates) && len(fallbackRates) > 0 {
+		log.Printf("GetRatesFromAllCarriers: preferred carrier %s misses today's pickup, adding %d rates from fallback %s", preferredType, len(fallbackRates), fallbackType)
+		allRates = append(preferredRates, fallbackRates...)
+	} else if len(preferredRates) > 0 {
 		log.Printf("GetRatesFromAllCarriers: using %d rates from preferred carrier %s", len(preferredRates), preferredType)
 		allRates = preferredRates
 	} else if len(fallbackRates) > 0 {
@@ -501,11 +542,17 @@ func (s *CarrierSelectorService) GetRatesFromAllCarriers(ctx context.Context, te
 		log.Printf("GetRatesFromAllCarriers: no rates from preferred or fallback carriers")
 	}
 
-	// Apply markup to all rates
+	// Apply markup to all rates, then the vendor's free-shipping threshold
 	allRates = s.applyMarkupToRates(allRates, markupPercent, handlingFee)
-
-	// Sort by rate (final rate including markup)
-	sort.Slice(allRates, func(i, j int) bool {
+	allRates = applyVendorFreeShipping(allRates, vendor, request.DeclaredValue)
+
+	// Sort by rate (final rate including markup), rates that miss today's pickup last
+	sort.SliceStable(allRates, func(i, j int) bool {
+		iConstrained := allRates[i].PastCutoff || allRates[i].AtCapacity
+		jConstrained := allRates[j].PastCutoff || allRates[j].AtCapacity
+		if iConstrained != jConstrained {
+			return !iConstrained
+		}
 		return allRates[i].Rate < allRates[j].Rate
 	})
 
@@ -578,6 +625,7 @@ func (s *CarrierSelectorService) CreateCarrierFromTemplate(ctx context.Context,
 		SupportsLabels:     template.SupportsLabels,
 		SupportsReturns:    template.SupportsReturns,
 		SupportsPickup:     template.SupportsPickup,
+		AllowsBatteries:    true,
 		SupportedCountries: template.SupportedCountries,
 		SupportedServices:  template.SupportedServices,
 		Priority:           10,
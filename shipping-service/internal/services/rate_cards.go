@@ -0,0 +1,462 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"shipping-service/internal/models"
+)
+
+var (
+	// ErrNoActiveRateCard is returned when comparing a carrier that has no rate card in effect
+	ErrNoActiveRateCard = errors.New("carrier has no active rate card")
+	// ErrCarrierNotConfigured is returned when a rate card names a carrier the tenant hasn't enabled
+	ErrCarrierNotConfigured = errors.New("carrier is not configured for this tenant")
+	// ErrRateCardWrongCarrier is returned when comparing a carrier against another carrier's card
+	ErrRateCardWrongCarrier = errors.New("rate card is for a different carrier")
+)
+
+// defaultRateCacheTTL applies when the tenant has no shipping settings yet
+const defaultRateCacheTTL = time.Hour
+
+// rateCacheTTL returns how long a tenant's live rates are cached; 0 disables caching
+func rateCacheTTL(settings *models.ShippingSettings) time.Duration {
+	if settings == nil {
+		return defaultRateCacheTTL
+	}
+	if !settings.CacheRates || settings.RateCacheDuration <= 0 {
+		return 0
+	}
+	return time.Duration(settings.RateCacheDuration) * time.Second
+}
+
+// carrierRates returns a carrier's own rates (before markup) for a request. Carriers set to use
+// negotiated rates are priced from their active rate card when it covers the lane; otherwise
+// rates come from the rate cache, or from a live call whose result is cached for the zone pair
+// and weight bracket.
+func (s *CarrierSelectorService) carrierRates(ctx context.Context, cfg *models.ShippingCarrierConfig, request models.GetRatesRequest, ttl time.Duration, now time.Time) ([]models.ShippingRate, error) {
+	if cfg.UseNegotiatedRates {
+		card, err := s.repo.GetActiveRateCard(ctx, cfg.TenantID, cfg.CarrierType)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Failed to load %s rate card for tenant %s: %v", cfg.CarrierType, cfg.TenantID, err)
+		}
+		if card != nil && card.InEffect(now) {
+			if rates := card.RatesFor(request, now); len(rates) > 0 {
+				log.Printf("Priced %d %s rate(s) from rate card %s", len(rates), cfg.CarrierType, card.Name)
+				return rates, nil
+			}
+		}
+	}
+
+	originZone := models.RateZone(request.FromAddress)
+	destinationZone := models.RateZone(request.ToAddress)
+	bracket := models.WeightBracket(models.ChargeableWeight(request.Weight, request.Length, request.Width, request.Height))
+
+	if ttl > 0 {
+		if rates, ok := s.repo.GetCachedRates(ctx, cfg.TenantID, cfg.CarrierType, originZone, destinationZone, bracket); ok {
+			log.Printf("Using %d cached %s rate(s) for %s->%s (%.1fkg)", len(rates), cfg.CarrierType, originZone, destinationZone, bracket)
+			return refreshCachedRates(rates, now), nil
+		}
+	}
+
+	carrier, err := s.createCarrierForConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create carrier: %w", err)
+	}
+	rates, err := carrier.GetRates(request)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rates {
+		rates[i].RateSource = models.RateSourceLive
+	}
+	if ttl > 0 && len(rates) > 0 {
+		s.repo.CacheRates(ctx, cfg.TenantID, cfg.CarrierType, originZone, destinationZone, bracket, rates, ttl)
+	}
+	return rates, nil
+}
+
+// refreshCachedRates marks cached rates and moves their delivery estimates up to today
+func refreshCachedRates(rates []models.ShippingRate, now time.Time) []models.ShippingRate {
+	for i := range rates {
+		rates[i].RateSource = models.RateSourceCache
+		if rates[i].EstimatedDays > 0 {
+			delivery := now.AddDate(0, 0, rates[i].EstimatedDays)
+			rates[i].EstimatedDelivery = &delivery
+		}
+	}
+	return rates
+}
+
+// ClearRateCache drops a tenant's cached rates, for one carrier or all of them
+func (s *CarrierSelectorService) ClearRateCache(ctx context.Context, tenantID string, carrierType models.CarrierType) error {
+	return s.repo.InvalidateRateCache(ctx, tenantID, carrierType)
+}
+
+// ListRateCards returns a tenant's rate cards, optionally limited to one carrier's
+func (s *CarrierSelectorService) ListRateCards(ctx context.Context, tenantID string, carrierType models.CarrierType) ([]models.RateCard, error) {
+	return s.repo.ListRateCards(ctx, tenantID, carrierType)
+}
+
+// GetRateCard returns a rate card with its entries
+func (s *CarrierSelectorService) GetRateCard(ctx context.Context, tenantID string, id uuid.UUID) (*models.RateCard, error) {
+	return s.repo.GetRateCard(ctx, tenantID, id)
+}
+
+// ActivateRateCard makes a rate card its carrier's active card
+func (s *CarrierSelectorService) ActivateRateCard(ctx context.Context, tenantID string, id uuid.UUID) (*models.RateCard, error) {
+	return s.repo.ActivateRateCard(ctx, tenantID, id)
+}
+
+// DeleteRateCard deletes a rate card
+func (s *CarrierSelectorService) DeleteRateCard(ctx context.Context, tenantID string, id uuid.UUID) error {
+	return s.repo.DeleteRateCard(ctx, tenantID, id)
+}
+
+// UploadRateCard parses a rate card CSV and saves it as the carrier's active card. The CSV needs
+// destinationZone, serviceCode and rate columns; originZone ("*"), serviceName, minWeight,
+// maxWeight, additionalPerKg and estimatedDays are optional. A file with invalid rows is rejected
+// whole with a *models.RateCardUploadError.
+func (s *CarrierSelectorService) UploadRateCard(ctx context.Context, card *models.RateCard, file io.Reader) (*models.RateCard, error) {
+	if _, err := s.repo.GetCarrierConfigByType(ctx, card.TenantID, card.CarrierType); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCarrierNotConfigured
+		}
+		return nil, err
+	}
+
+	entries, err := parseRateCardCSV(file)
+	if err != nil {
+		return nil, err
+	}
+
+	card.ID = uuid.New()
+	card.Currency = strings.ToUpper(card.Currency)
+	card.IsActive = true
+	card.EntryCount = len(entries)
+	card.Entries = entries
+	if err := s.repo.CreateRateCard(ctx, card); err != nil {
+		return nil, fmt.Errorf("failed to save rate card: %w", err)
+	}
+	log.Printf("Uploaded %s rate card %q for tenant %s with %d entries", card.CarrierType, card.Name, card.TenantID, len(entries))
+	return card, nil
+}
+
+// rateCardColumns maps normalized CSV headers to rate card entry fields
+var rateCardColumns = map[string]string{
+	"originzone":      "originZone",
+	"origin":          "originZone",
+	"destinationzone": "destinationZone",
+	"destination":     "destinationZone",
+	"servicecode":     "serviceCode",
+	"service":         "serviceCode",
+	"servicename":     "serviceName",
+	"minweight":       "minWeight",
+	"maxweight":       "maxWeight",
+	"rate":            "rate",
+	"additionalperkg": "additionalPerKg",
+	"perkg":           "additionalPerKg",
+	"estimateddays":   "estimatedDays",
+}
+
+// maxRateCardErrors caps the row errors reported for a rejected upload
+const maxRateCardErrors = 50
+
+// parseRateCardCSV reads rate card entries from CSV
+func parseRateCardCSV(file io.Reader) ([]models.RateCardEntry, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, &models.RateCardUploadError{Errors: []string{"file has no header row"}}
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "", "\ufeff", "").Replace(name))
+		if field, ok := rateCardColumns[key]; ok {
+			columns[field] = i
+		}
+	}
+	var missing []string
+	for _, required := range []string{"destinationZone", "serviceCode", "rate"} {
+		if _, ok := columns[required]; !ok {
+			missing = append(missing, required)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &models.RateCardUploadError{Errors: []string{"missing required column(s): " + strings.Join(missing, ", ")}}
+	}
+
+	var entries []models.RateCardEntry
+	var rowErrors []string
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &models.RateCardUploadError{Errors: []string{fmt.Sprintf("row %d: %v", row, err)}}
+		}
+
+		value := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+		if len(entries) >= models.MaxRateCardEntries {
+			return nil, &models.RateCardUploadError{Errors: []string{fmt.Sprintf("rate card has more than %d entries", models.MaxRateCardEntries)}}
+		}
+
+		entry := models.RateCardEntry{
+			ID:              uuid.New(),
+			OriginZone:      models.NormalizeRateZone(value("originZone")),
+			DestinationZone: models.NormalizeRateZone(value("destinationZone")),
+			ServiceCode:     value("serviceCode"),
+			ServiceName:     value("serviceName"),
+		}
+		if entry.ServiceName == "" {
+			entry.ServiceName = entry.ServiceCode
+		}
+
+		var parseErr error
+		number := func(field string, required bool) float64 {
+			raw := value(field)
+			if raw == "" {
+				if required && parseErr == nil {
+					parseErr = fmt.Errorf("%s is required", field)
+				}
+				return 0
+			}
+			n, err := strconv.ParseFloat(raw, 64)
+			if err != nil && parseErr == nil {
+				parseErr = fmt.Errorf("%s must be a number, got %q", field, raw)
+			}
+			return n
+		}
+		entry.MinWeight = number("minWeight", false)
+		entry.MaxWeight = number("maxWeight", false)
+		entry.Rate = number("rate", true)
+		entry.AdditionalPerKg = number("additionalPerKg", false)
+		entry.EstimatedDays = int(number("estimatedDays", false))
+		if parseErr == nil {
+			parseErr = entry.Validate()
+		}
+		if parseErr != nil {
+			if len(rowErrors) < maxRateCardErrors {
+				rowErrors = append(rowErrors, fmt.Sprintf("row %d: %v", row, parseErr))
+			}
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(rowErrors) > 0 {
+		return nil, &models.RateCardUploadError{Errors: rowErrors}
+	}
+	if len(entries) == 0 {
+		return nil, &models.RateCardUploadError{Errors: []string{"rate card has no entries"}}
+	}
+	return entries, nil
+}
+
+// CompareRates prices sample lanes both live and from a negotiated rate card. Without sample
+// shipments in the request, the carrier's most recent shipments are used. Live rates are fetched
+// fresh, bypassing the rate cache.
+func (s *CarrierSelectorService) CompareRates(ctx context.Context, tenantID string, req *models.RateComparisonRequest) (*models.RateComparisonReport, error) {
+	cfg, err := s.repo.GetCarrierConfigByType(ctx, tenantID, req.CarrierType)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCarrierNotConfigured
+		}
+		return nil, err
+	}
+
+	var card *models.RateCard
+	if req.RateCardID != nil {
+		card, err = s.repo.GetRateCard(ctx, tenantID, *req.RateCardID)
+		if err == nil && card.CarrierType != req.CarrierType {
+			return nil, ErrRateCardWrongCarrier
+		}
+	} else {
+		card, err = s.repo.GetActiveRateCard(ctx, tenantID, req.CarrierType)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoActiveRateCard
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	samples := req.Shipments
+	if len(samples) == 0 {
+		limit := req.Limit
+		if limit <= 0 {
+			limit = 20
+		}
+		if limit > models.MaxRateComparisons {
+			limit = models.MaxRateComparisons
+		}
+		shipments, err := s.repo.ListRecentShipments(ctx, tenantID, req.CarrierType, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list recent shipments: %w", err)
+		}
+		for _, shipment := range shipments {
+			samples = append(samples, models.RateComparisonShipment{
+				Reference:   shipment.OrderNumber,
+				FromAddress: shipment.FromAddress,
+				ToAddress:   shipment.ToAddress,
+				Weight:      shipment.Weight,
+				Length:      shipment.Length,
+				Width:       shipment.Width,
+				Height:      shipment.Height,
+			})
+		}
+	}
+	if len(samples) > models.MaxRateComparisons {
+		samples = samples[:models.MaxRateComparisons]
+	}
+
+	carrier, err := s.createCarrierForConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create carrier: %w", err)
+	}
+
+	now := time.Now()
+	report := &models.RateComparisonReport{
+		Success:      true,
+		CarrierType:  req.CarrierType,
+		RateCardID:   card.ID,
+		RateCardName: card.Name,
+		Currency:     card.Currency,
+		Lanes:        make([]models.RateComparisonLane, 0, len(samples)),
+		GeneratedAt:  now,
+	}
+
+	for _, sample := range samples {
+		request := models.GetRatesRequest{
+			FromAddress: sample.FromAddress,
+			ToAddress:   sample.ToAddress,
+			Weight:      sample.Weight,
+			Length:      sample.Length,
+			Width:       sample.Width,
+			Height:      sample.Height,
+		}
+		lane := models.RateComparisonLane{
+			Reference:        sample.Reference,
+			OriginZone:       models.RateZone(sample.FromAddress),
+			DestinationZone:  models.RateZone(sample.ToAddress),
+			ChargeableWeight: models.ChargeableWeight(sample.Weight, sample.Length, sample.Width, sample.Height),
+		}
+
+		liveRates, err := carrier.GetRates(request)
+		if err != nil {
+			lane.ErrorMessage = err.Error()
+		}
+		negotiatedRates := card.RatesFor(request, now)
+		lane.Services = compareServices(liveRates, negotiatedRates)
+		lane.CheapestLive = cheapestRate(liveRates)
+		lane.CheapestNegotiated = cheapestRate(negotiatedRates)
+
+		switch {
+		case lane.CheapestLive == nil:
+			report.LanesWithoutLive++
+		case lane.CheapestNegotiated == nil:
+			report.LanesWithoutNegotiated++
+		default:
+			savings := roundRate(*lane.CheapestLive - *lane.CheapestNegotiated)
+			lane.Savings = &savings
+			report.LanesCompared++
+			report.TotalLive += *lane.CheapestLive
+			report.TotalNegotiated += *lane.CheapestNegotiated
+		}
+		report.Lanes = append(report.Lanes, lane)
+	}
+
+	report.TotalLive = roundRate(report.TotalLive)
+	report.TotalNegotiated = roundRate(report.TotalNegotiated)
+	report.TotalSavings = roundRate(report.TotalLive - report.TotalNegotiated)
+	if report.TotalLive > 0 {
+		report.SavingsPercent = roundRate(report.TotalSavings / report.TotalLive * 100)
+	}
+	return report, nil
+}
+
+// compareServices pairs live and negotiated rates by service code
+func compareServices(live, negotiated []models.ShippingRate) []models.RateComparisonService {
+	byCode := make(map[string]*models.RateComparisonService)
+	var codes []string
+	service := func(rate models.ShippingRate) *models.RateComparisonService {
+		code := strings.ToUpper(rate.ServiceCode)
+		if existing, ok := byCode[code]; ok {
+			return existing
+		}
+		byCode[code] = &models.RateComparisonService{ServiceCode: rate.ServiceCode, ServiceName: rate.ServiceName}
+		codes = append(codes, code)
+		return byCode[code]
+	}
+	for _, rate := range live {
+		s := service(rate)
+		if s.LiveRate == nil || rate.Rate < *s.LiveRate {
+			price := rate.Rate
+			s.LiveRate = &price
+		}
+	}
+	for _, rate := range negotiated {
+		s := service(rate)
+		price := rate.Rate
+		s.NegotiatedRate = &price
+	}
+
+	sort.Strings(codes)
+	services := make([]models.RateComparisonService, 0, len(codes))
+	for _, code := range codes {
+		s := byCode[code]
+		if s.LiveRate != nil && s.NegotiatedRate != nil {
+			savings := roundRate(*s.LiveRate - *s.NegotiatedRate)
+			s.Savings = &savings
+			if *s.LiveRate > 0 {
+				percent := roundRate(savings / *s.LiveRate * 100)
+				s.SavingsPercent = &percent
+			}
+		}
+		services = append(services, *s)
+	}
+	return services
+}
+
+// cheapestRate returns the lowest available rate, or nil if there is none
+func cheapestRate(rates []models.ShippingRate) *float64 {
+	var cheapest *float64
+	for _, rate := range rates {
+		if !rate.Available {
+			continue
+		}
+		if cheapest == nil || rate.Rate < *cheapest {
+			price := rate.Rate
+			cheapest = &price
+		}
+	}
+	return cheapest
+}
+
+func roundRate(value float64) float64 {
+	return math.Round(value*100) / 100
+}
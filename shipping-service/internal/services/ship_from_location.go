@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"shipping-service/internal/models"
+)
+
+var (
+	// ErrShipFromLocationNotFound is returned when a request names a location the tenant doesn't have
+	ErrShipFromLocationNotFound = errors.New("ship-from location not found")
+	// ErrShipFromLocationInactive is returned when a request names a deactivated location
+	ErrShipFromLocationInactive = errors.New("ship-from location is inactive")
+	// ErrShipFromLocationSynced is returned when editing fields inventory-service owns
+	ErrShipFromLocationSynced = errors.New("ship-from location is synced from inventory; edit the warehouse instead")
+)
+
+// ListShipFromLocations returns a tenant's ship-from locations, optionally limited to one vendor's
+func (s *CarrierSelectorService) ListShipFromLocations(ctx context.Context, tenantID, vendorID string, activeOnly bool) ([]models.ShipFromLocation, error) {
+	return s.repo.ListShipFromLocations(ctx, tenantID, vendorID, activeOnly)
+}
+
+// GetShipFromLocation returns a ship-from location
+func (s *CarrierSelectorService) GetShipFromLocation(ctx context.Context, tenantID string, id uuid.UUID) (*models.ShipFromLocation, error) {
+	return s.repo.GetShipFromLocation(ctx, tenantID, id)
+}
+
+// CreateShipFromLocation adds a location to the tenant's address book
+func (s *CarrierSelectorService) CreateShipFromLocation(ctx context.Context, tenantID string, req *models.CreateShipFromLocationRequest) (*models.ShipFromLocation, error) {
+	location := &models.ShipFromLocation{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		VendorID:    req.VendorID,
+		Name:        req.Name,
+		Code:        req.Code,
+		Source:      models.ShipFromSourceManual,
+		Address:     req.Address,
+		ContactName: req.ContactName,
+		IsDefault:   req.IsDefault,
+		IsActive:    true,
+	}
+	location.Address.Country = strings.ToUpper(location.Address.Country)
+
+	if err := s.repo.SaveShipFromLocation(ctx, location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// UpdateShipFromLocation updates a location. Synced locations keep the address and contact
+// details from inventory-service; only their default and active flags can change here.
+func (s *CarrierSelectorService) UpdateShipFromLocation(ctx context.Context, tenantID string, id uuid.UUID, req *models.UpdateShipFromLocationRequest) (*models.ShipFromLocation, error) {
+	location, err := s.repo.GetShipFromLocation(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if location.IsSynced() && (req.Name != nil || req.Code != nil || req.Address != nil || req.ContactName != nil) {
+		return nil, ErrShipFromLocationSynced
+	}
+
+	// Apply updates
+	if req.Name != nil {
+		location.Name = *req.Name
+	}
+	if req.Code != nil {
+		location.Code = *req.Code
+	}
+	if req.Address != nil {
+		location.Address = *req.Address
+		location.Address.Country = strings.ToUpper(location.Address.Country)
+	}
+	if req.ContactName != nil {
+		location.ContactName = *req.ContactName
+	}
+	if req.IsDefault != nil {
+		location.IsDefault = *req.IsDefault
+	}
+	if req.IsActive != nil {
+		location.IsActive = *req.IsActive
+	}
+
+	if err := s.repo.SaveShipFromLocation(ctx, location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// DeleteShipFromLocation removes a manual location. Synced locations go away when the
+// warehouse is deleted in inventory-service.
+func (s *CarrierSelectorService) DeleteShipFromLocation(ctx context.Context, tenantID string, id uuid.UUID) error {
+	location, err := s.repo.GetShipFromLocation(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if location.IsSynced() {
+		return ErrShipFromLocationSynced
+	}
+	return s.repo.DeleteShipFromLocation(ctx, tenantID, id)
+}
+
+// SyncWarehouseLocation creates or refreshes the location mirroring an inventory warehouse.
+// The default flag is only taken from the warehouse when the location is first created, so a
+// default chosen in shipping settings isn't overwritten by later warehouse edits.
+func (s *CarrierSelectorService) SyncWarehouseLocation(ctx context.Context, tenantID, warehouseID string, synced *models.ShipFromLocation) error {
+	location, err := s.repo.GetShipFromLocationByWarehouse(ctx, tenantID, warehouseID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		location = &models.ShipFromLocation{
+			ID:          uuid.New(),
+			TenantID:    tenantID,
+			Source:      models.ShipFromSourceInventory,
+			WarehouseID: &warehouseID,
+			IsDefault:   synced.IsDefault,
+		}
+	} else if err != nil {
+		return err
+	}
+
+	location.VendorID = synced.VendorID
+	location.Name = synced.Name
+	location.Code = synced.Code
+	location.Address = synced.Address
+	location.Address.Country = strings.ToUpper(location.Address.Country)
+	location.ContactName = synced.ContactName
+	location.IsActive = synced.IsActive
+
+	return s.repo.SaveShipFromLocation(ctx, location)
+}
+
+// RemoveWarehouseLocation deletes the location mirroring a deleted inventory warehouse
+func (s *CarrierSelectorService) RemoveWarehouseLocation(ctx context.Context, tenantID, warehouseID string) error {
+	location, err := s.repo.GetShipFromLocationByWarehouse(ctx, tenantID, warehouseID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteShipFromLocation(ctx, tenantID, location.ID)
+}
+
+// resolveShipFrom works out where a package ships from, in order of precedence: the named
+// ship-from location, the vendor's own origin, the address on the request, the default
+// ship-from location, then the warehouse in the tenant's shipping settings. It returns the
+// location ID when the origin came from the address book.
+func (s *CarrierSelectorService) resolveShipFrom(ctx context.Context, tenantID, vendorID string, locationID *uuid.UUID, given models.Address, vendor *models.VendorShippingSettings) (models.Address, *uuid.UUID, error) {
+	if locationID != nil {
+		location, err := s.repo.GetShipFromLocation(ctx, tenantID, *locationID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Address{}, nil, ErrShipFromLocationNotFound
+		}
+		if err != nil {
+			return models.Address{}, nil, fmt.Errorf("failed to load ship-from location: %w", err)
+		}
+		if !location.IsActive {
+			return models.Address{}, nil, ErrShipFromLocationInactive
+		}
+		return location.ShipFromAddress(), &location.ID, nil
+	}
+
+	if vendor != nil && vendor.HasOrigin() {
+		return vendor.Origin, nil, nil
+	}
+	if given != (models.Address{}) {
+		return given, nil, nil
+	}
+
+	location, err := s.repo.GetDefaultShipFromLocation(ctx, tenantID, vendorID)
+	if err == nil {
+		return location.ShipFromAddress(), &location.ID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Failed to load default ship-from location for tenant %s: %v", tenantID, err)
+	}
+
+	if warehouse, ok := s.tenantOrigin(ctx, tenantID); ok {
+		return warehouse, nil, nil
+	}
+	return given, nil, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"shipping-service/internal/models"
+)
+
+// carrierConfigsByType loads the tenant's enabled carrier configs, keyed by carrier, so their
+// shipping restriction flags can be checked
+func (s *CarrierSelectorService) carrierConfigsByType(ctx context.Context, tenantID string) map[models.CarrierType]*models.ShippingCarrierConfig {
+	configs, err := s.repo.ListEnabledCarrierConfigs(ctx, tenantID)
+	if err != nil {
+		log.Printf("Failed to load carrier configs for restriction checks (tenant: %s): %v", tenantID, err)
+		return nil
+	}
+
+	byType := make(map[models.CarrierType]*models.ShippingCarrierConfig, len(configs))
+	for i := range configs {
+		byType[configs[i].CarrierType] = &configs[i]
+	}
+	return byType
+}
+
+// restrictRates drops rates from carriers and services that can't take the package's items,
+// returning the rates left and why the others were dropped. Carriers without a config in
+// configs are dropped whenever the package is restricted.
+func restrictRates(rates []models.ShippingRate, configs map[models.CarrierType]*models.ShippingCarrierConfig, restrictions *models.PackageRestrictions) ([]models.ShippingRate, []models.ShippingRestrictionViolation) {
+	allowed := make([]models.ShippingRate, 0, len(rates))
+	var violations []models.ShippingRestrictionViolation
+
+	// Each carrier is checked once; its violations are only reported once
+	carrierChecked := make(map[models.CarrierType][]models.ShippingRestrictionViolation)
+	for _, rate := range rates {
+		carrierViolations, checked := carrierChecked[rate.Carrier]
+		if !checked {
+			carrierViolations = restrictions.CarrierViolations(rate.Carrier, configs[rate.Carrier])
+			carrierChecked[rate.Carrier] = carrierViolations
+			violations = append(violations, carrierViolations...)
+		}
+		if len(carrierViolations) > 0 {
+			continue
+		}
+
+		if v := restrictions.ServiceViolation(rate.Carrier, rate.ServiceName, rate.ServiceCode); v != nil {
+			violations = append(violations, *v)
+			continue
+		}
+		allowed = append(allowed, rate)
+	}
+
+	if len(allowed) == 0 && len(rates) > 0 {
+		violations = append(violations, models.ShippingRestrictionViolation{
+			Rule:     models.RestrictionNoEligibleCarrier,
+			Blocking: true,
+			Message:  "No available carrier or service can ship these items",
+		})
+	}
+	return allowed, violations
+}
@@ -21,7 +21,8 @@ type ShippingService interface {
 	GetShipment(id uuid.UUID, tenantID string) (*models.Shipment, error)
 	GetShipmentsByOrder(orderID uuid.UUID, tenantID string) ([]*models.Shipment, error)
 	ListShipments(tenantID string, limit, offset int) ([]*models.Shipment, int64, error)
-	GetRates(request models.GetRatesRequest, tenantID string) ([]models.ShippingRate, error)
+	GetRates(request models.GetRatesRequest, tenantID string) ([]models.ShippingRate, []models.ShippingRestrictionViolation, error)
+	GetCartRates(request models.GetCartRatesRequest, tenantID string) ([]models.VendorRateGroup, error)
 	TrackShipment(trackingNumber string, tenantID string) (*models.TrackShipmentResponse, error)
 	CancelShipment(id uuid.UUID, reason string, tenantID string) error
 	UpdateShipmentStatus(id uuid.UUID, status models.ShipmentStatus, tenantID string) error
@@ -89,12 +90,28 @@ func (s *shippingService) selectCarrier(ctx context.Context, tenantID, fromCount
 func (s *shippingService) CreateShipment(request models.CreateShipmentRequest, tenantID string) (*models.Shipment, error) {
 	log.Printf("Creating shipment for order %s (tenant: %s)", request.OrderNumber, tenantID)
 
+	ctx := context.Background()
+
+	// Ship from the selected address book entry, or the default location when no address is given
+	if s.carrierSelector != nil {
+		from, locationID, err := s.carrierSelector.resolveShipFrom(ctx, tenantID, "", request.ShipFromLocationID, request.FromAddress, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+		request.FromAddress = from
+		request.ShipFromLocationID = locationID
+	}
+
 	// Validate request
 	if err := s.validateCreateShipmentRequest(request); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
-	ctx := context.Background()
+	// Items no carrier can take on this route fail before a carrier is selected
+	restrictions := models.CheckPackageRestrictions(request.Items, request.FromAddress.Country, request.ToAddress.Country)
+	if restrictions.Blocked() {
+		return nil, &models.ShippingRestrictionError{Violations: restrictions.Violations}
+	}
 
 	// Select carrier using database config or legacy fallback
 	carrier, err := s.selectCarrier(ctx, tenantID, request.FromAddress.Country, request.ToAddress.Country)
@@ -102,6 +119,24 @@ func (s *shippingService) CreateShipment(request models.CreateShipmentRequest, t
 		return nil, fmt.Errorf("failed to select carrier: %w", err)
 	}
 
+	// The selected carrier and service must be able to take the items
+	if restrictions.Restricted() {
+		var configs map[models.CarrierType]*models.ShippingCarrierConfig
+		if s.carrierSelector != nil {
+			configs = s.carrierSelector.carrierConfigsByType(ctx, tenantID)
+		}
+		violations := restrictions.CarrierViolations(carrier.GetName(), configs[carrier.GetName()])
+		if v := restrictions.ServiceViolation(carrier.GetName(), request.ServiceType, request.CourierServiceCode); v != nil {
+			violations = append(violations, *v)
+		}
+		if len(violations) > 0 {
+			for i := range violations {
+				violations[i].Blocking = true
+			}
+			return nil, &models.ShippingRestrictionError{Violations: violations}
+		}
+	}
+
 	// Create shipment with the selected carrier
 	shipment, err := carrier.CreateShipment(request)
 	if err != nil {
@@ -110,6 +145,7 @@ func (s *shippingService) CreateShipment(request models.CreateShipmentRequest, t
 
 	// Set tenant ID
 	shipment.TenantID = tenantID
+	shipment.ShipFromLocationID = request.ShipFromLocationID
 
 	// Save to database
 	if err := s.shipmentRepo.Create(shipment); err != nil {
@@ -158,20 +194,61 @@ func (s *shippingService) ListShipments(tenantID string, limit, offset int) ([]*
 	return s.shipmentRepo.List(tenantID, limit, offset)
 }
 
-// GetRates retrieves shipping rates from carriers
-func (s *shippingService) GetRates(request models.GetRatesRequest, tenantID string) ([]models.ShippingRate, error) {
+// GetRates retrieves shipping rates from carriers. Rates from carriers and services that can't
+// take the request's items are dropped and reported as violations; when nothing is left the
+// error is a *models.ShippingRestrictionError.
+func (s *shippingService) GetRates(request models.GetRatesRequest, tenantID string) ([]models.ShippingRate, []models.ShippingRestrictionViolation, error) {
 	log.Printf("Getting shipping rates (tenant: %s)", tenantID)
 
+	ctx := context.Background()
+
+	// Vendor items ship from the vendor's own origin when it has one, unless the request
+	// names a ship-from location
+	var vendor *models.VendorShippingSettings
+	if s.carrierSelector != nil {
+		vendor = s.carrierSelector.vendorSettingsForRates(ctx, tenantID, request.VendorID)
+		from, locationID, err := s.carrierSelector.resolveShipFrom(ctx, tenantID, request.VendorID, request.ShipFromLocationID, request.FromAddress, vendor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid request: %w", err)
+		}
+		request.FromAddress = from
+		request.ShipFromLocationID = locationID
+	}
+
 	// Validate request
 	if err := s.validateGetRatesRequest(request); err != nil {
-		return nil, fmt.Errorf("invalid request: %w", err)
+		return nil, nil, fmt.Errorf("invalid request: %w", err)
 	}
 
-	ctx := context.Background()
+	// Items no carrier can take on this route fail before any carrier is asked
+	restrictions := models.CheckPackageRestrictions(request.Items, request.FromAddress.Country, request.ToAddress.Country)
+	if restrictions.Blocked() {
+		return nil, restrictions.Violations, &models.ShippingRestrictionError{Violations: restrictions.Violations}
+	}
 
+	rates, err := s.fetchRates(ctx, tenantID, request, vendor)
+	if err != nil || !restrictions.Restricted() {
+		return rates, nil, err
+	}
+
+	var configs map[models.CarrierType]*models.ShippingCarrierConfig
+	if s.carrierSelector != nil {
+		configs = s.carrierSelector.carrierConfigsByType(ctx, tenantID)
+	}
+	allowed, violations := restrictRates(rates, configs, &restrictions)
+	if len(allowed) == 0 {
+		return nil, violations, &models.ShippingRestrictionError{Violations: violations}
+	}
+	log.Printf("Shipping restrictions left %d of %d rate(s)", len(allowed), len(rates))
+	return allowed, violations, nil
+}
+
+// fetchRates asks the tenant's database-configured carriers for rates, falling back to the
+// legacy carrier service
+func (s *shippingService) fetchRates(ctx context.Context, tenantID string, request models.GetRatesRequest, vendor *models.VendorShippingSettings) ([]models.ShippingRate, error) {
 	// Try database-driven rates from all carriers first
 	if s.carrierSelector != nil {
-		rates, err := s.carrierSelector.GetRatesFromAllCarriers(ctx, tenantID, request)
+		rates, err := s.carrierSelector.GetRatesForVendor(ctx, tenantID, request, vendor)
 		if err == nil && len(rates) > 0 {
 			log.Printf("Retrieved %d shipping rate(s) from database configs", len(rates))
 			return rates, nil
@@ -185,6 +262,7 @@ func (s *shippingService) GetRates(request models.GetRatesRequest, tenantID stri
 		if err != nil {
 			return nil, fmt.Errorf("failed to get rates: %w", err)
 		}
+		rates = applyVendorFreeShipping(filterRatesForVendor(rates, vendor), vendor, request.DeclaredValue)
 		log.Printf("Retrieved %d shipping rate(s) from legacy config", len(rates))
 		return rates, nil
 	}
@@ -192,6 +270,70 @@ func (s *shippingService) GetRates(request models.GetRatesRequest, tenantID stri
 	return nil, fmt.Errorf("no carrier service configured")
 }
 
+// GetCartRates rates each vendor's package in a cart separately, since every vendor ships
+// from its own origin with its own carriers and fees. A package that can't be rated gets an
+// error message instead of failing the whole cart.
+func (s *shippingService) GetCartRates(request models.GetCartRatesRequest, tenantID string) ([]models.VendorRateGroup, error) {
+	log.Printf("Getting cart shipping rates for %d package(s) (tenant: %s)", len(request.Packages), tenantID)
+
+	ctx := context.Background()
+
+	groups := make([]models.VendorRateGroup, 0, len(request.Packages))
+	for _, pkg := range request.Packages {
+		rateRequest := models.GetRatesRequest{
+			ShipFromLocationID: pkg.ShipFromLocationID,
+			ToAddress:          request.ToAddress,
+			Weight:             pkg.Weight,
+			Length:             pkg.Length,
+			Width:              pkg.Width,
+			Height:             pkg.Height,
+			DeclaredValue:      pkg.DeclaredValue,
+			VendorID:           pkg.VendorID,
+			Items:              pkg.Items,
+		}
+		if pkg.FromAddress != nil {
+			rateRequest.FromAddress = *pkg.FromAddress
+		}
+		if s.carrierSelector != nil {
+			vendor := s.carrierSelector.vendorSettingsForRates(ctx, tenantID, pkg.VendorID)
+			from, locationID, err := s.carrierSelector.resolveShipFrom(ctx, tenantID, pkg.VendorID, pkg.ShipFromLocationID, rateRequest.FromAddress, vendor)
+			if err != nil {
+				groups = append(groups, models.VendorRateGroup{
+					VendorID:     pkg.VendorID,
+					Rates:        []models.ShippingRate{},
+					ErrorMessage: err.Error(),
+				})
+				continue
+			}
+			rateRequest.FromAddress = from
+			rateRequest.ShipFromLocationID = locationID
+		}
+
+		group := models.VendorRateGroup{
+			VendorID:    pkg.VendorID,
+			FromAddress: rateRequest.FromAddress,
+			Rates:       []models.ShippingRate{},
+		}
+		rates, violations, err := s.GetRates(rateRequest, tenantID)
+		group.Violations = violations
+		if err != nil {
+			log.Printf("Failed to rate package for vendor %q: %v", pkg.VendorID, err)
+			group.ErrorMessage = err.Error()
+		} else {
+			group.Rates = rates
+			for _, rate := range rates {
+				if rate.FreeShipping {
+					group.FreeShipping = true
+					break
+				}
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
 // TrackShipment tracks a shipment by tracking number
 func (s *shippingService) TrackShipment(trackingNumber string, tenantID string) (*models.TrackShipmentResponse, error) {
 	log.Printf("Tracking shipment: %s (tenant: %s)", trackingNumber, tenantID)
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"shipping-service/internal/models"
+)
+
+// GetVendorShippingSettings returns a vendor's shipping settings
+func (s *CarrierSelectorService) GetVendorShippingSettings(ctx context.Context, tenantID, vendorID string) (*models.VendorShippingSettings, error) {
+	return s.repo.GetVendorShippingSettings(ctx, tenantID, vendorID)
+}
+
+// ListVendorShippingSettings returns every vendor's shipping settings for a tenant
+func (s *CarrierSelectorService) ListVendorShippingSettings(ctx context.Context, tenantID string) ([]models.VendorShippingSettings, error) {
+	return s.repo.ListVendorShippingSettings(ctx, tenantID)
+}
+
+// UpdateVendorShippingSettings creates or updates a vendor's shipping settings
+func (s *CarrierSelectorService) UpdateVendorShippingSettings(ctx context.Context, tenantID, vendorID string, req *models.UpdateVendorShippingSettingsRequest) (*models.VendorShippingSettings, error) {
+	settings, err := s.repo.GetVendorShippingSettings(ctx, tenantID, vendorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		settings = &models.VendorShippingSettings{
+			ID:       uuid.New(),
+			TenantID: tenantID,
+			VendorID: vendorID,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Apply updates
+	if req.Origin != nil {
+		settings.Origin = *req.Origin
+		settings.Origin.Country = strings.ToUpper(settings.Origin.Country)
+	}
+	if req.AllowedCarriers != nil {
+		allowed := make(models.StringArray, 0, len(*req.AllowedCarriers))
+		for _, carrierType := range *req.AllowedCarriers {
+			allowed = append(allowed, strings.ToUpper(carrierType))
+		}
+		settings.AllowedCarriers = allowed
+	}
+	if req.ClearFeeOverrides {
+		settings.HandlingFee = nil
+		settings.HandlingFeePercent = nil
+	}
+	if req.HandlingFee != nil {
+		settings.HandlingFee = req.HandlingFee
+	}
+	if req.HandlingFeePercent != nil {
+		settings.HandlingFeePercent = req.HandlingFeePercent
+	}
+	if req.FreeShippingEnabled != nil {
+		settings.FreeShippingEnabled = *req.FreeShippingEnabled
+	}
+	if req.FreeShippingMinimum != nil {
+		settings.FreeShippingMinimum = *req.FreeShippingMinimum
+	}
+
+	if err := s.repo.SaveVendorShippingSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// DeleteVendorShippingSettings removes a vendor's shipping settings
+func (s *CarrierSelectorService) DeleteVendorShippingSettings(ctx context.Context, tenantID, vendorID string) error {
+	return s.repo.DeleteVendorShippingSettings(ctx, tenantID, vendorID)
+}
+
+// vendorSettingsForRates loads the settings the rate engine applies for a vendor. Vendors
+// without settings, or whose settings can't be loaded, are rated with the tenant's settings.
+func (s *CarrierSelectorService) vendorSettingsForRates(ctx context.Context, tenantID, vendorID string) *models.VendorShippingSettings {
+	if vendorID == "" {
+		return nil
+	}
+	settings, err := s.repo.GetVendorShippingSettings(ctx, tenantID, vendorID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Failed to load shipping settings for vendor %s: %v", vendorID, err)
+		}
+		return nil
+	}
+	return settings
+}
+
+// tenantOrigin returns the tenant's warehouse as a ship-from address, if one is configured
+func (s *CarrierSelectorService) tenantOrigin(ctx context.Context, tenantID string) (models.Address, bool) {
+	settings, err := s.repo.GetShippingSettings(ctx, tenantID)
+	if err != nil || settings.WarehousePostalCode == "" || settings.WarehouseCountry == "" {
+		return models.Address{}, false
+	}
+	w := settings.GetWarehouse()
+	return models.Address{
+		Name:       w.Name,
+		Company:    w.Company,
+		Phone:      w.Phone,
+		Email:      w.Email,
+		Street:     w.Street,
+		Street2:    w.Street2,
+		City:       w.City,
+		State:      w.State,
+		PostalCode: w.PostalCode,
+		Country:    w.Country,
+	}, true
+}
+
+// filterConfigsForVendor keeps the carrier configs the vendor is allowed to ship with
+func filterConfigsForVendor(configs []models.ShippingCarrierConfig, vendor *models.VendorShippingSettings) []models.ShippingCarrierConfig {
+	filtered := make([]models.ShippingCarrierConfig, 0, len(configs))
+	for _, cfg := range configs {
+		if vendor.AllowsCarrier(cfg.CarrierType) {
+			filtered = append(filtered, cfg)
+		}
+	}
+	return filtered
+}
+
+// filterRatesForVendor drops rates from carriers the vendor doesn't ship with
+func filterRatesForVendor(rates []models.ShippingRate, vendor *models.VendorShippingSettings) []models.ShippingRate {
+	if vendor == nil || len(vendor.AllowedCarriers) == 0 {
+		return rates
+	}
+	filtered := make([]models.ShippingRate, 0, len(rates))
+	for _, rate := range rates {
+		if vendor.AllowsCarrier(rate.Carrier) {
+			filtered = append(filtered, rate)
+		}
+	}
+	return filtered
+}
+
+// applyVendorFreeShipping zeroes the rates when the package meets the vendor's free-shipping
+// minimum. BaseRate keeps the carrier cost so the tenant can see what the vendor absorbs.
+func applyVendorFreeShipping(rates []models.ShippingRate, vendor *models.VendorShippingSettings, declaredValue float64) []models.ShippingRate {
+	if vendor == nil || !vendor.QualifiesForFreeShipping(declaredValue) {
+		return rates
+	}
+	for i := range rates {
+		rates[i].Rate = 0
+		rates[i].MarkupAmount = 0
+		rates[i].FreeShipping = true
+	}
+	return rates
+}
@@ -0,0 +1,167 @@
+package subscribers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	gosharedevents "github.com/Tesseract-Nexus/go-shared/events"
+	"github.com/sirupsen/logrus"
+	"shipping-service/internal/models"
+	"shipping-service/internal/services"
+)
+
+// Warehouse events published by inventory-service
+const (
+	WarehouseUpserted = "inventory.warehouse_upserted"
+	WarehouseDeleted  = "inventory.warehouse_deleted"
+)
+
+// WarehouseEvent is the payload of inventory-service's warehouse events
+type WarehouseEvent struct {
+	gosharedevents.BaseEvent
+	WarehouseID string `json:"warehouseId"`
+	VendorID    string `json:"vendorId"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Address1    string `json:"address1"`
+	Address2    string `json:"address2"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	PostalCode  string `json:"postalCode"`
+	Country     string `json:"country"`
+	Phone       string `json:"phone"`
+	Email       string `json:"email"`
+	ManagerName string `json:"managerName"`
+	IsDefault   bool   `json:"isDefault"`
+}
+
+// WarehouseSubscriber keeps the ship-from address book in sync with inventory-service warehouses
+type WarehouseSubscriber struct {
+	subscriber      *gosharedevents.Subscriber
+	selectorService *services.CarrierSelectorService
+	logger          *logrus.Entry
+	cancel          context.CancelFunc
+}
+
+// NewWarehouseSubscriber creates a new warehouse event subscriber
+func NewWarehouseSubscriber(
+	selectorService *services.CarrierSelectorService,
+	logger *logrus.Logger,
+) (*WarehouseSubscriber, error) {
+	natsURL := os.Getenv("NATS_URL")
+	if natsURL == "" {
+		natsURL = "nats://nats.nats.svc.cluster.local:4222"
+	}
+
+	config := gosharedevents.DefaultSubscriberConfig(natsURL, "shipping-service-warehouse-sync")
+	config.Name = "shipping-service-warehouse-subscriber"
+	config.DeliverPolicy = "all" // Replay existing warehouses on first start
+	config.MaxDeliver = 5
+	config.AckWait = 30 * time.Second
+
+	subscriber, err := gosharedevents.NewSubscriber(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WarehouseSubscriber{
+		subscriber:      subscriber,
+		selectorService: selectorService,
+		logger:          logger.WithField("component", "warehouse-subscriber"),
+	}, nil
+}
+
+// Start starts listening for warehouse events
+func (s *WarehouseSubscriber) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	subjects := []string{WarehouseUpserted, WarehouseDeleted}
+
+	s.logger.Info("Starting warehouse event subscription...")
+
+	// Note: The stream is created by inventory-service publisher
+	err := s.subscriber.Subscribe(ctx, gosharedevents.StreamInventory, subjects, s.handleWarehouseMessage)
+	if err != nil {
+		return err
+	}
+
+	s.logger.WithField("subjects", subjects).Info("Warehouse subscriber started successfully")
+	return nil
+}
+
+// handleWarehouseMessage processes warehouse messages from NATS
+func (s *WarehouseSubscriber) handleWarehouseMessage(ctx context.Context, msg *gosharedevents.Message) error {
+	var event WarehouseEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		s.logger.WithError(err).Error("Failed to unmarshal warehouse event")
+		return nil // Don't retry for invalid data
+	}
+	if event.TenantID == "" || event.WarehouseID == "" {
+		s.logger.WithField("event_type", event.EventType).Warn("Warehouse event missing tenant or warehouse ID")
+		return nil
+	}
+
+	logger := s.logger.WithFields(logrus.Fields{
+		"event_type":   event.EventType,
+		"tenant_id":    event.TenantID,
+		"warehouse_id": event.WarehouseID,
+	})
+
+	switch event.EventType {
+	case WarehouseUpserted:
+		if err := s.selectorService.SyncWarehouseLocation(ctx, event.TenantID, event.WarehouseID, event.toShipFromLocation()); err != nil {
+			logger.WithError(err).Error("Failed to sync ship-from location")
+			return err
+		}
+		logger.Info("Synced ship-from location from warehouse")
+	case WarehouseDeleted:
+		if err := s.selectorService.RemoveWarehouseLocation(ctx, event.TenantID, event.WarehouseID); err != nil {
+			logger.WithError(err).Error("Failed to remove ship-from location")
+			return err
+		}
+		logger.Info("Removed ship-from location for deleted warehouse")
+	default:
+		logger.Warn("Unknown warehouse event type")
+	}
+	return nil
+}
+
+// toShipFromLocation maps the warehouse onto a ship-from location. Inactive and closed
+// warehouses are kept in the address book but can't be shipped from.
+func (e *WarehouseEvent) toShipFromLocation() *models.ShipFromLocation {
+	return &models.ShipFromLocation{
+		VendorID: e.VendorID,
+		Name:     e.Name,
+		Code:     e.Code,
+		Address: models.Address{
+			Name:       e.ManagerName,
+			Company:    e.Name,
+			Phone:      e.Phone,
+			Email:      e.Email,
+			Street:     e.Address1,
+			Street2:    e.Address2,
+			City:       e.City,
+			State:      e.State,
+			PostalCode: e.PostalCode,
+			Country:    e.Country,
+		},
+		ContactName: e.ManagerName,
+		IsDefault:   e.IsDefault,
+		IsActive:    e.Status == "" || e.Status == "ACTIVE",
+	}
+}
+
+// Stop stops the warehouse subscriber
+func (s *WarehouseSubscriber) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.subscriber != nil {
+		s.subscriber.Close()
+	}
+	s.logger.Info("Warehouse subscriber stopped")
+}
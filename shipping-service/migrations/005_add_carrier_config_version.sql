@@ -0,0 +1,5 @@
+-- Migration: Add version column to shipping_carrier_configs
+-- Optimistic concurrency control: PUT /api/carrier-configs/:id accepts If-Match / expectedVersion
+-- and answers 409 with the current version when the config was changed by someone else
+
+ALTER TABLE shipping_carrier_configs ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
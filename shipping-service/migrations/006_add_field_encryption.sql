@@ -0,0 +1,23 @@
+-- Migration: Per-tenant field encryption
+-- Purpose: Store tenant data encryption keys (wrapped by Cloud KMS). Carrier API/webhook secrets
+-- and credentials are now stored as ciphertext (enc:v1:<key version>:<tenant id>:<payload>);
+-- existing plaintext stays readable and is encrypted by the key rotation worker.
+
+CREATE TABLE IF NOT EXISTS tenant_data_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    version INTEGER NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    kek_name VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    retired_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_data_keys_version ON tenant_data_keys(tenant_id, version);
+CREATE INDEX IF NOT EXISTS idx_tenant_data_keys_status ON tenant_data_keys(status);
+
+COMMENT ON TABLE tenant_data_keys IS 'Per-tenant data encryption keys, wrapped by the Cloud KMS key encryption key';
+
+-- Credentials now hold an encrypted JSON document instead of JSONB
+ALTER TABLE shipping_carrier_configs ALTER COLUMN credentials TYPE TEXT USING credentials::text;
@@ -0,0 +1,33 @@
+-- Migration: Per-vendor shipping settings for marketplace tenants
+-- Purpose: Vendors can ship from their own origin, with their own carriers, handling fees and
+-- free-shipping threshold. Rate requests carrying a vendorId (POST /api/rates, /api/rates/cart)
+-- apply these on top of the tenant's shipping_settings.
+
+CREATE TABLE IF NOT EXISTS vendor_shipping_settings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    vendor_id VARCHAR(255) NOT NULL,
+    origin_name VARCHAR(255),
+    origin_company VARCHAR(255),
+    origin_phone VARCHAR(50),
+    origin_email VARCHAR(255),
+    origin_street VARCHAR(500),
+    origin_street2 VARCHAR(500),
+    origin_city VARCHAR(100),
+    origin_state VARCHAR(100),
+    origin_postal_code VARCHAR(20),
+    origin_country VARCHAR(10),
+    allowed_carriers TEXT[],
+    handling_fee DECIMAL(10,2),
+    handling_fee_percent DECIMAL(5,4),
+    free_shipping_enabled BOOLEAN DEFAULT FALSE,
+    free_shipping_minimum DECIMAL(10,2),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vendor_shipping_settings_vendor ON vendor_shipping_settings(tenant_id, vendor_id);
+
+COMMENT ON COLUMN vendor_shipping_settings.allowed_carriers IS 'Carrier types the vendor ships with. Empty allows every carrier enabled for the tenant.';
+COMMENT ON COLUMN vendor_shipping_settings.handling_fee IS 'Overrides shipping_settings.handling_fee when set.';
+COMMENT ON COLUMN vendor_shipping_settings.handling_fee_percent IS 'Overrides shipping_settings.handling_fee_percent when set.';
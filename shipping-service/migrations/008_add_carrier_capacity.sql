@@ -0,0 +1,16 @@
+-- Migration: Carrier capacity limits and pickup cut-off times
+-- Purpose: Cap the shipments booked with a carrier per day and record its daily pickup cut-off.
+-- Once a carrier is past either, rate responses drop its rates (capacity_policy = 'exclude') or
+-- list them last with a one-day-later estimate ('deprioritize'). GET /api/carriers/capacity shows
+-- what is left today.
+
+ALTER TABLE shipping_carrier_configs ADD COLUMN IF NOT EXISTS daily_shipment_cap INTEGER DEFAULT 0;
+ALTER TABLE shipping_carrier_configs ADD COLUMN IF NOT EXISTS pickup_cutoff_time VARCHAR(5);
+ALTER TABLE shipping_carrier_configs ADD COLUMN IF NOT EXISTS cutoff_timezone VARCHAR(64);
+ALTER TABLE shipping_carrier_configs ADD COLUMN IF NOT EXISTS capacity_policy VARCHAR(20) DEFAULT 'deprioritize';
+
+-- Daily capacity counts shipments per tenant and carrier since midnight
+CREATE INDEX IF NOT EXISTS idx_shipments_tenant_carrier_created ON shipments(tenant_id, carrier, created_at);
+
+COMMENT ON COLUMN shipping_carrier_configs.daily_shipment_cap IS 'Shipments per day, counted in cutoff_timezone. 0 means unlimited.';
+COMMENT ON COLUMN shipping_carrier_configs.pickup_cutoff_time IS 'Daily pickup cut-off as HH:MM in cutoff_timezone (UTC when empty).';
@@ -0,0 +1,39 @@
+-- Migration: Ship-from address book
+-- Purpose: Tenants ship from several warehouses and stores. Locations are added by hand or synced
+-- from inventory-service warehouses (inventory.warehouse_* events). Rate requests, cart rates and
+-- shipments can name a shipFromLocationId; requests without an origin use the default location.
+
+CREATE TABLE IF NOT EXISTS ship_from_locations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    vendor_id VARCHAR(255),
+    name VARCHAR(255) NOT NULL,
+    code VARCHAR(50),
+    source VARCHAR(20) NOT NULL DEFAULT 'MANUAL',
+    warehouse_id VARCHAR(255),
+    address_name VARCHAR(255),
+    address_company VARCHAR(255),
+    address_phone VARCHAR(50),
+    address_email VARCHAR(255),
+    address_street VARCHAR(500),
+    address_street2 VARCHAR(500),
+    address_city VARCHAR(100),
+    address_state VARCHAR(100),
+    address_postal_code VARCHAR(20),
+    address_country VARCHAR(10),
+    contact_name VARCHAR(255),
+    is_default BOOLEAN DEFAULT FALSE,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ship_from_locations_tenant_id ON ship_from_locations(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_ship_from_locations_vendor_id ON ship_from_locations(vendor_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ship_from_locations_warehouse ON ship_from_locations(tenant_id, warehouse_id);
+
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS ship_from_location_id UUID;
+
+COMMENT ON COLUMN ship_from_locations.source IS 'MANUAL locations are edited here; INVENTORY locations mirror an inventory-service warehouse.';
+COMMENT ON COLUMN ship_from_locations.is_default IS 'Origin for rate requests and shipments that name no address or location.';
+COMMENT ON COLUMN shipments.ship_from_location_id IS 'Address book entry the shipment left from, when one was selected.';
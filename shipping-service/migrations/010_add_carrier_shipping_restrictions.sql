@@ -0,0 +1,13 @@
+-- Migration: Carrier shipping restrictions
+-- Purpose: Record which restricted items each carrier accepts. Rate requests, cart rates and
+-- shipments carrying dangerous goods, lithium batteries or age-restricted items drop carriers and
+-- air services that can't take them; hazmat classes 1, 2.3, 6.2 and 7, and any hazmat shipped
+-- internationally, are refused outright with a 422.
+
+ALTER TABLE shipping_carrier_configs ADD COLUMN IF NOT EXISTS allows_dangerous_goods BOOLEAN DEFAULT FALSE;
+ALTER TABLE shipping_carrier_configs ADD COLUMN IF NOT EXISTS allows_batteries BOOLEAN DEFAULT TRUE;
+ALTER TABLE shipping_carrier_configs ADD COLUMN IF NOT EXISTS supports_age_verification BOOLEAN DEFAULT FALSE;
+
+COMMENT ON COLUMN shipping_carrier_configs.allows_dangerous_goods IS 'Carrier accepts ground shipments of hazmat classes other than 1, 2.3, 6.2 and 7.';
+COMMENT ON COLUMN shipping_carrier_configs.allows_batteries IS 'Carrier accepts lithium batteries on ground services.';
+COMMENT ON COLUMN shipping_carrier_configs.supports_age_verification IS 'Carrier can check the recipient''s age on delivery.';
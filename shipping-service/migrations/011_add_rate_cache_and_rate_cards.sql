@@ -0,0 +1,45 @@
+-- Migration: Rate caching and negotiated rate cards
+-- Purpose: Carrier rates are cached per tenant, carrier, origin/destination zone (country and
+-- postal prefix) and 0.5kg weight bracket for shipping_settings.rate_cache_duration seconds.
+-- Carriers with use_negotiated_rates are priced from their active uploaded rate card instead of
+-- a live rate call for lanes the card covers. POST /api/rate-cards/compare reports live against
+-- negotiated rates.
+
+ALTER TABLE shipping_carrier_configs ADD COLUMN IF NOT EXISTS use_negotiated_rates BOOLEAN DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS shipping_rate_cards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    carrier_type VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    is_active BOOLEAN DEFAULT FALSE,
+    effective_from TIMESTAMPTZ,
+    effective_to TIMESTAMPTZ,
+    file_name VARCHAR(255),
+    entry_count INTEGER DEFAULT 0,
+    uploaded_by VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_cards_tenant_carrier ON shipping_rate_cards(tenant_id, carrier_type);
+
+CREATE TABLE IF NOT EXISTS shipping_rate_card_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    rate_card_id UUID NOT NULL REFERENCES shipping_rate_cards(id) ON DELETE CASCADE,
+    origin_zone VARCHAR(50) NOT NULL,
+    destination_zone VARCHAR(50) NOT NULL,
+    service_code VARCHAR(100) NOT NULL,
+    service_name VARCHAR(255),
+    min_weight DECIMAL(10,3) DEFAULT 0,
+    max_weight DECIMAL(10,3) DEFAULT 0,
+    rate DECIMAL(10,2) NOT NULL,
+    additional_per_kg DECIMAL(10,2) DEFAULT 0,
+    estimated_days INTEGER DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipping_rate_card_entries_rate_card_id ON shipping_rate_card_entries(rate_card_id);
+
+COMMENT ON COLUMN shipping_carrier_configs.use_negotiated_rates IS 'Price the carrier from its active rate card instead of live rates where the card covers the lane.';
+COMMENT ON COLUMN shipping_rate_card_entries.origin_zone IS '* for any address, a country code (IN) or a country and postal prefix (IN:110).';
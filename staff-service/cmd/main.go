@@ -3,9 +3,12 @@ package main
 // Build trigger: go-shared/auth password grant with master realm auth for customer Keycloak
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/Tesseract-Nexus/go-shared/auth"
@@ -14,12 +17,18 @@ import (
 	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 	"staff-service/internal/cache"
+	"staff-service/internal/clients"
 	"staff-service/internal/config"
+	"staff-service/internal/database"
 	"staff-service/internal/events"
 	"staff-service/internal/handlers"
+	"staff-service/internal/jobqueue"
 	"staff-service/internal/middleware"
+	"staff-service/internal/models"
 	"staff-service/internal/repository"
+	"staff-service/internal/serviceauth"
 	"staff-service/internal/services"
 )
 
@@ -57,14 +66,28 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Initialize database
-	db, err := config.InitDB(cfg)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return config.InitDB(cfg) })
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 	sqlDB, _ := db.DB()
 	defer sqlDB.Close()
 
+	// "staff-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
+	}
+
 	// Set database for health checks
 	handlers.SetDB(db)
 
@@ -91,6 +114,9 @@ func main() {
 	eventLogger.SetFormatter(&logrus.JSONFormatter{})
 	eventLogger.SetLevel(logrus.InfoLevel)
 
+	// Permission changes are published once the publisher connects (rbac.permissions_changed)
+	permissionsNotifier := events.NewPermissionsNotifier(eventLogger)
+
 	go func() {
 		publisher, err := events.NewPublisher(eventLogger)
 		if err != nil {
@@ -98,7 +124,7 @@ func main() {
 		} else {
 			log.Println("✓ NATS events publisher initialized")
 			// Publisher will be cleaned up when process exits
-			_ = publisher
+			permissionsNotifier.SetPublisher(publisher)
 		}
 	}()
 
@@ -107,29 +133,39 @@ func main() {
 	rbacRepo := repository.NewRBACRepository(db)
 	docRepo := repository.NewDocumentRepository(db)
 	authRepo := repository.NewAuthRepository(db)
-
-	// ROLE-005 FIX: Background job to cleanup expired role assignments
-	// Runs every hour to mark expired assignments as inactive
-	go func() {
-		ticker := time.NewTicker(1 * time.Hour)
-		defer ticker.Stop()
-
-		// Run immediately on startup
-		if count, err := rbacRepo.CleanupExpiredRoleAssignments(); err != nil {
-			log.Printf("WARNING: Failed to cleanup expired role assignments: %v", err)
-		} else if count > 0 {
-			log.Printf("✓ Cleaned up %d expired role assignments", count)
-		}
-
-		// Then run periodically
-		for range ticker.C {
-			if count, err := rbacRepo.CleanupExpiredRoleAssignments(); err != nil {
-				log.Printf("WARNING: Failed to cleanup expired role assignments: %v", err)
-			} else if count > 0 {
+	importMappingRepo := repository.NewImportMappingRepository(db)
+	retentionRepo := repository.NewRetentionRepository(db)
+	businessCalendarRepo := repository.NewBusinessCalendarRepository(db)
+
+	// Recurring jobs run from the persistent job runner: one replica runs each job at a time,
+	// failures are retried, and platform owners can pause or trigger them from /api/v1/admin/jobs
+	jobRunner := jobqueue.NewRunner(db, logrus.WithField("component", "jobqueue"))
+
+	// ROLE-005 FIX: Mark expired role assignments as inactive every hour
+	jobRunner.Register(jobqueue.Job{
+		Name:        "role_assignment_cleanup",
+		Description: "Marks expired role assignments as inactive",
+		Interval:    1 * time.Hour,
+		Run: func(ctx context.Context) error {
+			count, err := rbacRepo.CleanupExpiredRoleAssignments()
+			if err == nil && count > 0 {
 				log.Printf("✓ Cleaned up %d expired role assignments", count)
 			}
-		}
-	}()
+			return err
+		},
+	})
+	if err := jobRunner.Start(); err != nil {
+		log.Fatalf("Failed to start background job runner: %v", err)
+	}
+
+	// Enforce login audit retention policies. Abandoned carts and webhook events are purged
+	// by customers-service and payment-service using the policies served from here.
+	loginAuditRetentionWorker := services.NewLoginAuditRetentionWorker(retentionRepo, logrus.WithField("component", "login_audit_retention"))
+	go loginAuditRetentionWorker.Start()
+
+	// Send document expiry reminders to staff and managers, and expire lapsed documents
+	documentComplianceWorker := services.NewDocumentComplianceWorker(docRepo, clients.NewNotificationClient(), logrus.WithField("component", "document_compliance"))
+	go documentComplianceWorker.Start()
 
 	// Initialize Keycloak admin client for user management
 	// Supports both client_credentials grant (client_secret) and password grant (username/password)
@@ -161,7 +197,10 @@ func main() {
 	rbacHandler := handlers.NewRBACHandlerWithCache(rbacRepo, staffRepo, permCache)
 	staffDocHandler := handlers.NewStaffDocumentHandler(docRepo, staffRepo)
 	authHandler := handlers.NewAuthHandlerWithKeycloak(staffRepo, authRepo, cfg.JWTSecret, keycloakClient)
-	importHandler := handlers.NewImportHandler(staffRepo)
+	importHandler := handlers.NewImportHandler(staffRepo, importMappingRepo)
+	retentionHandler := handlers.NewRetentionHandler(retentionRepo)
+	businessCalendarHandler := handlers.NewBusinessCalendarHandler(businessCalendarRepo)
+	rateLimitHandler := handlers.NewRateLimitHandler(cache.NewRateLimitStore(permCache))
 
 	// ROLE-SYNC: Initialize Keycloak role sync service for automatic role synchronization
 	// This syncs Keycloak realm roles to staff-service RBAC database on each authenticated request
@@ -174,6 +213,43 @@ func main() {
 		log.Printf("⚠ Keycloak role sync disabled (RBAC_AUTO_SYNC_ROLES=false)")
 	}
 
+	// Sync Keycloak group membership to departments and teams. Removals are reconciled
+	// hourly against Keycloak, which needs the admin credentials.
+	groupSyncRepo := repository.NewGroupSyncRepository(db)
+	var groupReconcileWorker *services.GroupReconcileWorker
+	if roleSyncService != nil {
+		var groupLookup services.KeycloakGroupLookup
+		if keycloakClient != nil {
+			groupLookup = clients.NewKeycloakGroupClient(clients.KeycloakGroupClientConfig{
+				BaseURL:      cfg.KeycloakBaseURL,
+				Realm:        cfg.KeycloakRealm,
+				ClientID:     cfg.KeycloakClientID,
+				ClientSecret: cfg.KeycloakClientSecret,
+				Username:     cfg.KeycloakUsername,
+				Password:     cfg.KeycloakPassword,
+			})
+		}
+		roleSyncService.EnableGroupSync(groupSyncRepo, groupLookup)
+		if groupLookup != nil {
+			groupReconcileWorker = services.NewGroupReconcileWorker(roleSyncService, groupSyncRepo, logrus.WithField("component", "keycloak_group_reconcile"))
+			go groupReconcileWorker.Start()
+		}
+	}
+	groupSyncHandler := handlers.NewGroupSyncHandler(groupSyncRepo, roleSyncService)
+
+	// Tenant API keys for server-to-server integrations. Keys stand in for a staff member
+	// in effective-permission lookups, so services accepting them reuse their RBAC checks.
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyRepo, rbacRepo)
+	rbacHandler.SetAPIKeyRepository(apiKeyRepo)
+	rbacHandler.SetPermissionsNotifier(permissionsNotifier)
+	apiKeyHandler.SetPermissionsNotifier(permissionsNotifier)
+
+	// Admin impersonation of vendors and staff. Services that honor it validate tokens through
+	// the internal routes and report every request made while impersonating.
+	impersonationRepo := repository.NewImpersonationRepository(db)
+	impersonationHandler := handlers.NewImpersonationHandler(impersonationRepo, staffRepo, rbacRepo)
+
 	// SEC-002: Initialize RBAC middleware for route protection with caching
 	var rbacMiddleware *middleware.RBACMiddleware
 	if roleSyncService != nil {
@@ -273,26 +349,41 @@ func main() {
 		crossTenantAuth.POST("/validate", authHandler.ValidateStaffCredentials)
 	}
 
-	// Internal service-to-service routes (no authentication required)
-	// These are called by other services within the cluster
+	// Internal service-to-service routes, authenticated by the caller's mesh identity or a
+	// service token; each route only admits the services that call it
 	// OptionalTenantMiddleware extracts x-jwt-claim-tenant-id header for service-to-service calls
+	internalAuth := serviceauth.New(serviceauth.ConfigFromEnv("staff-service"), logrus.StandardLogger())
+	anyService := internalAuth.Require()
 	internalRoutes := router.Group("/api/v1/internal", middleware.OptionalTenantMiddleware())
 	{
 		// Bootstrap owner for a new tenant - called by tenant-service during onboarding
-		internalRoutes.POST("/bootstrap-owner", rbacHandler.BootstrapOwner)
+		internalRoutes.POST("/bootstrap-owner", internalAuth.Require("tenant-service"), rbacHandler.BootstrapOwner)
 		// Seed vendor roles - called by vendor-service when creating marketplace vendors
-		internalRoutes.POST("/seed-vendor-roles", rbacHandler.SeedVendorRoles)
+		internalRoutes.POST("/seed-vendor-roles", internalAuth.Require("vendor-service"), rbacHandler.SeedVendorRoles)
 		// Get staff by email - called by tenant-service for credential validation
-		internalRoutes.GET("/staff/by-email", staffHandler.GetStaffByEmailInternal)
+		internalRoutes.GET("/staff/by-email", internalAuth.Require("tenant-service", "tickets-service"), staffHandler.GetStaffByEmailInternal)
 		// Get staff tenants by Keycloak user ID - called by tenant-service for /users/me/tenants
-		internalRoutes.GET("/staff/:id/tenants", staffHandler.GetStaffTenantsInternal)
+		internalRoutes.GET("/staff/:id/tenants", internalAuth.Require("tenant-service"), staffHandler.GetStaffTenantsInternal)
 		// Sync keycloak_user_id after successful login - called by tenant-service
-		internalRoutes.POST("/staff/sync-keycloak-id", staffHandler.SyncKeycloakUserIDInternal)
+		internalRoutes.POST("/staff/sync-keycloak-id", internalAuth.Require("tenant-service"), staffHandler.SyncKeycloakUserIDInternal)
+		// Validate tenant API keys - called by services that accept API keys
+		internalRoutes.POST("/api-keys/validate", anyService, apiKeyHandler.ValidateAPIKeyInternal)
+		// Impersonation token validation and action reporting for services that honor impersonation
+		internalRoutes.POST("/impersonation/validate", anyService, impersonationHandler.ValidateImpersonationInternal)
+		internalRoutes.POST("/impersonation/actions", anyService, impersonationHandler.RecordImpersonationActionsInternal)
+		// Find qualified assignees by skill and certification - called by tickets-service
+		internalRoutes.POST("/staff/match", internalAuth.Require("tickets-service"), staffHandler.MatchStaffInternal)
 		// RBAC effective-permissions - called by go-shared/rbac client from other services
 		// This is an internal endpoint for service-to-service permission verification
-		internalRoutes.GET("/rbac/staff/:id/effective-permissions", rbacHandler.GetStaffEffectivePermissions)
+		internalRoutes.GET("/rbac/staff/:id/effective-permissions", anyService, rbacHandler.GetStaffEffectivePermissions)
 		// Update auth method - called by auth-bff when Google SSO login detected for password-based staff
-		internalRoutes.PATCH("/auth/update-auth-method", authHandler.UpdateAuthMethod)
+		internalRoutes.PATCH("/auth/update-auth-method", internalAuth.Require("auth-bff"), authHandler.UpdateAuthMethod)
+		// Retention policies and purge summaries - used by purge workers in customers-service and payment-service
+		purgeWorkers := internalAuth.Require("customers-service", "payment-service")
+		internalRoutes.GET("/retention/policies/:resource", purgeWorkers, retentionHandler.GetPolicySetInternal)
+		internalRoutes.POST("/retention/runs", purgeWorkers, retentionHandler.RecordPurgeRunInternal)
+		// Business calendars - used by SLA timers in tickets-service and escalation timers in approval-service
+		internalRoutes.GET("/business-calendars/resolve", internalAuth.Require("tickets-service", "approval-service"), businessCalendarHandler.ResolveCalendarInternal)
 	}
 
 	// Protected API routes
@@ -327,6 +418,10 @@ func main() {
 			// SEC-002: Require staff view permission for import template
 			staff.GET("/import/template", rbacMiddleware.RequirePermission("team:staff:view"), importHandler.GetImportTemplate)
 			staff.POST("/import", rbacMiddleware.RequirePermission("team:staff:create"), importHandler.ImportStaff)
+			staff.POST("/import/preview", rbacMiddleware.RequirePermission("team:staff:create"), importHandler.PreviewStaffImport)
+			staff.GET("/import/mappings", rbacMiddleware.RequirePermission("team:staff:view"), importHandler.ListStaffMappingPresets)
+			staff.POST("/import/mappings", rbacMiddleware.RequirePermission("team:staff:create"), importHandler.SaveStaffMappingPreset)
+			staff.DELETE("/import/mappings/:presetId", rbacMiddleware.RequirePermission("team:staff:create"), importHandler.DeleteStaffMappingPreset)
 
 			// Standard CRUD - SEC-002: Apply appropriate permissions
 			staff.POST("", rbacMiddleware.RequirePermission("team:staff:create"), staffHandler.CreateStaff)
@@ -339,6 +434,8 @@ func main() {
 			staff.POST("/export", rbacMiddleware.RequirePermission("team:staff:view"), staffHandler.ExportStaff)
 			staff.GET("/analytics", rbacMiddleware.RequirePermission("team:staff:view"), staffHandler.GetStaffAnalytics)
 			staff.GET("/hierarchy", rbacMiddleware.RequirePermission("team:staff:view"), staffHandler.GetStaffHierarchy)
+			staff.GET("/skills", rbacMiddleware.RequirePermission("team:staff:view"), staffHandler.GetSkillCatalog)
+			staff.PUT("/:id/skills", rbacMiddleware.RequirePermission("team:staff:edit"), staffHandler.UpdateStaffSkills)
 
 			// Document upload/download (proxied to document-service)
 			staff.POST("/documents/upload", rbacMiddleware.RequirePermission("team:staff:edit"), documentHandler.UploadStaffDocument)
@@ -378,6 +475,10 @@ func main() {
 			// Import routes (must be before :id routes)
 			departments.GET("/import/template", rbacMiddleware.RequirePermission("team:departments:view"), importHandler.GetDepartmentImportTemplate)
 			departments.POST("/import", rbacMiddleware.RequirePermission("team:departments:manage"), importHandler.ImportDepartments)
+			departments.POST("/import/preview", rbacMiddleware.RequirePermission("team:departments:manage"), importHandler.PreviewDepartmentImport)
+			departments.GET("/import/mappings", rbacMiddleware.RequirePermission("team:departments:view"), importHandler.ListDepartmentMappingPresets)
+			departments.POST("/import/mappings", rbacMiddleware.RequirePermission("team:departments:manage"), importHandler.SaveDepartmentMappingPreset)
+			departments.DELETE("/import/mappings/:presetId", rbacMiddleware.RequirePermission("team:departments:manage"), importHandler.DeleteDepartmentMappingPreset)
 
 			departments.POST("", rbacMiddleware.RequirePermission("team:departments:manage"), rbacHandler.CreateDepartment)
 			departments.GET("", rbacMiddleware.RequirePermission("team:departments:view"), rbacHandler.ListDepartments)
@@ -394,6 +495,10 @@ func main() {
 			// Import routes (must be before :id routes)
 			teams.GET("/import/template", rbacMiddleware.RequirePermission("team:teams:view"), importHandler.GetTeamImportTemplate)
 			teams.POST("/import", rbacMiddleware.RequirePermission("team:teams:manage"), importHandler.ImportTeams)
+			teams.POST("/import/preview", rbacMiddleware.RequirePermission("team:teams:manage"), importHandler.PreviewTeamImport)
+			teams.GET("/import/mappings", rbacMiddleware.RequirePermission("team:teams:view"), importHandler.ListTeamMappingPresets)
+			teams.POST("/import/mappings", rbacMiddleware.RequirePermission("team:teams:manage"), importHandler.SaveTeamMappingPreset)
+			teams.DELETE("/import/mappings/:presetId", rbacMiddleware.RequirePermission("team:teams:manage"), importHandler.DeleteTeamMappingPreset)
 
 			teams.POST("", rbacMiddleware.RequirePermission("team:teams:manage"), rbacHandler.CreateTeam)
 			teams.GET("", rbacMiddleware.RequirePermission("team:teams:view"), rbacHandler.ListTeams)
@@ -409,6 +514,10 @@ func main() {
 			// Import routes (must be before :id routes)
 			roles.GET("/import/template", rbacMiddleware.RequirePermission("team:roles:view"), importHandler.GetRoleImportTemplate)
 			roles.POST("/import", rbacMiddleware.RequireRoleManagement(), importHandler.ImportRoles)
+			roles.POST("/import/preview", rbacMiddleware.RequireRoleManagement(), importHandler.PreviewRoleImport)
+			roles.GET("/import/mappings", rbacMiddleware.RequirePermission("team:roles:view"), importHandler.ListRoleMappingPresets)
+			roles.POST("/import/mappings", rbacMiddleware.RequireRoleManagement(), importHandler.SaveRoleMappingPreset)
+			roles.DELETE("/import/mappings/:presetId", rbacMiddleware.RequireRoleManagement(), importHandler.DeleteRoleMappingPreset)
 
 			roles.POST("", rbacMiddleware.RequireRoleManagement(), rbacHandler.CreateRole)
 			roles.GET("", rbacMiddleware.RequirePermission("team:roles:view"), rbacHandler.ListRoles)
@@ -451,6 +560,7 @@ func main() {
 			documents.GET("/types", rbacMiddleware.RequirePermission("staff:read"), staffDocHandler.GetDocumentTypes)
 			documents.GET("/pending", rbacMiddleware.RequirePermission("staff:read"), staffDocHandler.GetPendingDocuments)
 			documents.GET("/expiring", rbacMiddleware.RequirePermission("staff:read"), staffDocHandler.GetExpiringDocuments)
+			documents.GET("/compliance-dashboard", rbacMiddleware.RequirePermission("staff:read"), staffDocHandler.GetComplianceDashboard)
 			documents.POST("/update-expired", rbacMiddleware.RequirePermission("staff:update"), staffDocHandler.UpdateExpiredDocuments)
 		}
 
@@ -461,6 +571,79 @@ func main() {
 			audit.GET("/logins", rbacMiddleware.RequirePermission("audit:read"), authHandler.GetLoginAudit)
 		}
 
+		// Data retention policies - stored centrally, enforced by purge workers in each owning service
+		retention := v1.Group("/retention")
+		{
+			retention.GET("/policies", rbacMiddleware.RequirePermission("settings:read"), retentionHandler.ListPolicies)
+			retention.PUT("/policies/:resource", rbacMiddleware.RequirePermission("settings:update"), retentionHandler.UpdatePolicy)
+			retention.DELETE("/policies/:resource", rbacMiddleware.RequirePermission("settings:update"), retentionHandler.ResetPolicy)
+			// Deletion audit summaries
+			retention.GET("/runs", rbacMiddleware.RequirePermission("audit:read"), retentionHandler.ListPurgeRuns)
+		}
+
+		// Business hours and holiday calendars, shared by ticket SLA and approval escalation timers
+		businessCalendars := v1.Group("/business-calendars")
+		{
+			businessCalendars.GET("", rbacMiddleware.RequirePermission("settings:read"), businessCalendarHandler.ListCalendars)
+			businessCalendars.POST("", rbacMiddleware.RequirePermission("settings:update"), businessCalendarHandler.CreateCalendar)
+			businessCalendars.GET("/:id", rbacMiddleware.RequirePermission("settings:read"), businessCalendarHandler.GetCalendar)
+			businessCalendars.PUT("/:id", rbacMiddleware.RequirePermission("settings:update"), businessCalendarHandler.UpdateCalendar)
+			businessCalendars.DELETE("/:id", rbacMiddleware.RequirePermission("settings:update"), businessCalendarHandler.DeleteCalendar)
+			businessCalendars.POST("/:id/holidays", rbacMiddleware.RequirePermission("settings:update"), businessCalendarHandler.AddHoliday)
+			businessCalendars.DELETE("/:id/holidays/:holidayId", rbacMiddleware.RequirePermission("settings:update"), businessCalendarHandler.DeleteHoliday)
+			businessCalendars.GET("/:id/elapsed", rbacMiddleware.RequirePermission("settings:read"), businessCalendarHandler.GetElapsed)
+		}
+
+		// Tenant API keys for server-to-server integrations
+		apiKeys := v1.Group("/api-keys")
+		{
+			apiKeys.GET("", rbacMiddleware.RequirePermission("settings:integrations:manage"), apiKeyHandler.ListAPIKeys)
+			apiKeys.POST("", rbacMiddleware.RequirePermission("settings:integrations:manage"), apiKeyHandler.CreateAPIKey)
+			apiKeys.GET("/:id", rbacMiddleware.RequirePermission("settings:integrations:manage"), apiKeyHandler.GetAPIKey)
+			apiKeys.PUT("/:id", rbacMiddleware.RequirePermission("settings:integrations:manage"), apiKeyHandler.UpdateAPIKey)
+			apiKeys.POST("/:id/rotate", rbacMiddleware.RequirePermission("settings:integrations:manage"), apiKeyHandler.RotateAPIKey)
+			apiKeys.DELETE("/:id", rbacMiddleware.RequirePermission("settings:integrations:manage"), apiKeyHandler.RevokeAPIKey)
+		}
+
+		// Admin impersonation of vendors and staff, with a full audit trail per session
+		impersonation := v1.Group("/impersonation/sessions")
+		{
+			impersonation.POST("", rbacMiddleware.RequirePermission("team:impersonate"), impersonationHandler.StartImpersonation)
+			impersonation.GET("/current", rbacMiddleware.RequirePermission("team:impersonate"), impersonationHandler.GetCurrentImpersonation)
+			impersonation.POST("/:id/end", rbacMiddleware.RequirePermission("team:impersonate"), impersonationHandler.EndImpersonation)
+			impersonation.GET("", rbacMiddleware.RequirePermission("audit:read"), impersonationHandler.ListImpersonationSessions)
+			impersonation.GET("/:id", rbacMiddleware.RequirePermission("audit:read"), impersonationHandler.GetImpersonationSession)
+			impersonation.GET("/:id/actions", rbacMiddleware.RequirePermission("audit:read"), impersonationHandler.ListImpersonationActions)
+		}
+
+		// Platform-wide rate limit profiles, read by every rate-limited service from Redis
+		rateLimits := v1.Group("/rate-limits")
+		rateLimits.Use(rbacMiddleware.RequireMinPriority(models.PlatformOwnerPriority))
+		{
+			rateLimits.GET("/settings", rateLimitHandler.GetSettings)
+			rateLimits.PUT("/settings", rateLimitHandler.UpdateSettings)
+			rateLimits.GET("/tenants", rateLimitHandler.ListTenantOverrides)
+			rateLimits.GET("/tenants/:tenantId", rateLimitHandler.GetTenantOverride)
+			rateLimits.PUT("/tenants/:tenantId", rateLimitHandler.SaveTenantOverride)
+			rateLimits.DELETE("/tenants/:tenantId", rateLimitHandler.DeleteTenantOverride)
+		}
+
+		// Background jobs of this service
+		jobsAdmin := v1.Group("/admin")
+		jobsAdmin.Use(rbacMiddleware.RequireMinPriority(models.PlatformOwnerPriority))
+		jobqueue.NewHandler(jobRunner).RegisterRoutes(jobsAdmin)
+
+		// Keycloak group to department/team mappings
+		groupSync := v1.Group("/keycloak-group-sync")
+		{
+			groupSync.GET("/settings", rbacMiddleware.RequirePermission("settings:read"), groupSyncHandler.GetSettings)
+			groupSync.PUT("/settings", rbacMiddleware.RequirePermission("settings:update"), groupSyncHandler.UpdateSettings)
+			groupSync.GET("/mappings", rbacMiddleware.RequirePermission("settings:read"), groupSyncHandler.ListMappings)
+			groupSync.PUT("/mappings", rbacMiddleware.RequirePermission("settings:update"), groupSyncHandler.SaveMapping)
+			groupSync.DELETE("/mappings/:id", rbacMiddleware.RequirePermission("settings:update"), groupSyncHandler.DeleteMapping)
+			groupSync.POST("/reconcile", rbacMiddleware.RequirePermission("settings:update"), groupSyncHandler.Reconcile)
+		}
+
 		// Protected auth routes (requires authentication)
 		// Note: These are user self-management, no RBAC needed beyond auth
 		auth := v1.Group("/auth")
@@ -502,8 +685,40 @@ func main() {
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// Start server
-	log.Printf("Staff service starting on port %s in %s mode", cfg.Port, cfg.Environment)
-	if err := router.Run(":" + cfg.Port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	srv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: router,
 	}
+
+	go func() {
+		log.Printf("Staff service starting on port %s in %s mode", cfg.Port, cfg.Environment)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down staff-service...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting connections and let in-flight requests finish before stopping workers
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
+	jobRunner.Stop()
+	loginAuditRetentionWorker.Stop()
+	documentComplianceWorker.Stop()
+	if groupReconcileWorker != nil {
+		groupReconcileWorker.Stop()
+	}
+	log.Println("✓ Background workers stopped")
+
+	log.Println("Staff service stopped")
 }
@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"staff-service/internal/models"
+)
+
+// Redis keys read by the rate limiters of other services. Every change increments the version
+// key, which the services poll to reload their profiles.
+const (
+	rateLimitSettingsKey = "ratelimit:config:settings"
+	rateLimitTenantsKey  = "ratelimit:config:tenants"
+	rateLimitVersionKey  = "ratelimit:config:version"
+)
+
+// ErrRateLimitStoreUnavailable is returned when Redis isn't connected
+var ErrRateLimitStoreUnavailable = errors.New("rate limit store unavailable")
+
+// RateLimitStore stores rate limit profiles and tenant overrides in Redis
+type RateLimitStore struct {
+	client *redis.Client
+}
+
+// NewRateLimitStore creates a rate limit store using the permission cache's Redis connection
+func NewRateLimitStore(permCache *PermissionCache) *RateLimitStore {
+	store := &RateLimitStore{}
+	if permCache != nil {
+		store.client = permCache.client
+	}
+	return store
+}
+
+// GetSettings returns the stored settings, or nil if none have been saved
+func (s *RateLimitStore) GetSettings(ctx context.Context) (*models.RateLimitSettings, error) {
+	if s.client == nil {
+		return nil, ErrRateLimitStoreUnavailable
+	}
+
+	data, err := s.client.Get(ctx, rateLimitSettingsKey).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var settings models.RateLimitSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SaveSettings replaces the settings
+func (s *RateLimitStore) SaveSettings(ctx context.Context, settings *models.RateLimitSettings) error {
+	if s.client == nil {
+		return ErrRateLimitStoreUnavailable
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, rateLimitSettingsKey, data, 0)
+	pipe.Incr(ctx, rateLimitVersionKey)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// ListTenantOverrides returns every tenant override
+func (s *RateLimitStore) ListTenantOverrides(ctx context.Context) ([]models.TenantRateLimitOverride, error) {
+	if s.client == nil {
+		return nil, ErrRateLimitStoreUnavailable
+	}
+
+	values, err := s.client.HGetAll(ctx, rateLimitTenantsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make([]models.TenantRateLimitOverride, 0, len(values))
+	for _, value := range values {
+		var override models.TenantRateLimitOverride
+		if err := json.Unmarshal([]byte(value), &override); err != nil {
+			continue
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, nil
+}
+
+// GetTenantOverride returns the tenant's override, or nil if it has none
+func (s *RateLimitStore) GetTenantOverride(ctx context.Context, tenantID string) (*models.TenantRateLimitOverride, error) {
+	if s.client == nil {
+		return nil, ErrRateLimitStoreUnavailable
+	}
+
+	data, err := s.client.HGet(ctx, rateLimitTenantsKey, tenantID).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var override models.TenantRateLimitOverride
+	if err := json.Unmarshal(data, &override); err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// SaveTenantOverride creates or replaces the tenant's override
+func (s *RateLimitStore) SaveTenantOverride(ctx context.Context, override *models.TenantRateLimitOverride) error {
+	if s.client == nil {
+		return ErrRateLimitStoreUnavailable
+	}
+
+	data, err := json.Marshal(override)
+	if err != nil {
+		return err
+	}
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, rateLimitTenantsKey, override.TenantID, data)
+	pipe.Incr(ctx, rateLimitVersionKey)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// DeleteTenantOverride removes the tenant's override. It reports whether one existed.
+func (s *RateLimitStore) DeleteTenantOverride(ctx context.Context, tenantID string) (bool, error) {
+	if s.client == nil {
+		return false, ErrRateLimitStoreUnavailable
+	}
+
+	pipe := s.client.TxPipeline()
+	deleted := pipe.HDel(ctx, rateLimitTenantsKey, tenantID)
+	pipe.Incr(ctx, rateLimitVersionKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return deleted.Val() > 0, nil
+}
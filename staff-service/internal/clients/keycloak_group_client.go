@@ -0,0 +1,163 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrKeycloakUserNotFound is returned when the Keycloak user no longer exists
+var ErrKeycloakUserNotFound = errors.New("keycloak user not found")
+
+// KeycloakGroupClient reads users' group memberships from the Keycloak admin API.
+// go-shared's admin client has no call for a user's groups, so this client authenticates
+// with the same admin credentials on its own.
+type KeycloakGroupClient struct {
+	baseURL      string
+	realm        string
+	authRealm    string
+	clientID     string
+	clientSecret string
+	username     string
+	password     string
+	httpClient   *http.Client
+
+	tokenMu     sync.Mutex
+	accessToken string
+	tokenExpiry time.Time
+}
+
+// KeycloakGroupClientConfig holds the admin credentials, as for go-shared's admin client
+type KeycloakGroupClientConfig struct {
+	BaseURL      string
+	Realm        string
+	ClientID     string
+	ClientSecret string // client_credentials grant
+	Username     string // password grant against the master realm
+	Password     string
+}
+
+// NewKeycloakGroupClient creates a new Keycloak group client
+func NewKeycloakGroupClient(config KeycloakGroupClientConfig) *KeycloakGroupClient {
+	authRealm := config.Realm
+	if config.Username != "" {
+		authRealm = "master"
+	}
+
+	return &KeycloakGroupClient{
+		baseURL:      strings.TrimSuffix(config.BaseURL, "/"),
+		realm:        config.Realm,
+		authRealm:    authRealm,
+		clientID:     config.ClientID,
+		clientSecret: config.ClientSecret,
+		username:     config.Username,
+		password:     config.Password,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetUserGroupPaths returns the full paths (e.g. "/Sales/EMEA") of the groups a user is a direct member of
+func (c *KeycloakGroupClient) GetUserGroupPaths(ctx context.Context, userID string) ([]string, error) {
+	token, err := c.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/admin/realms/%s/users/%s/groups?briefRepresentation=true&max=1000",
+		c.baseURL, c.realm, url.PathEscape(userID))
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	// Add User-Agent to bypass Cloudflare WAF blocking server-to-server requests
+	req.Header.Set("User-Agent", "Tesserix-Service/1.0 (Keycloak Admin Client)")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user groups: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrKeycloakUserNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get user groups: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var groups []struct {
+		Path string `json:"path"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&groups); err != nil {
+		return nil, fmt.Errorf("failed to decode user groups: %w", err)
+	}
+
+	paths := make([]string, 0, len(groups))
+	for _, g := range groups {
+		paths = append(paths, g.Path)
+	}
+	return paths, nil
+}
+
+// token returns a cached admin access token, requesting a new one when it's about to expire
+func (c *KeycloakGroupClient) token(ctx context.Context) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.accessToken != "" && time.Now().Before(c.tokenExpiry.Add(-30*time.Second)) {
+		return c.accessToken, nil
+	}
+
+	data := url.Values{}
+	data.Set("client_id", c.clientID)
+	if c.username != "" && c.password != "" {
+		data.Set("grant_type", "password")
+		data.Set("username", c.username)
+		data.Set("password", c.password)
+	} else {
+		data.Set("grant_type", "client_credentials")
+		data.Set("client_secret", c.clientSecret)
+	}
+
+	tokenURL := fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", c.baseURL, c.authRealm)
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "Tesserix-Service/1.0 (Keycloak Admin Client)")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	c.accessToken = tokenResp.AccessToken
+	c.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	return c.accessToken, nil
+}
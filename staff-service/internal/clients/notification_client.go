@@ -31,6 +31,21 @@ type StaffInvitationNotification struct {
 	ActivationLink string
 }
 
+// DocumentExpiryNotification contains data for document expiry reminder and lapse emails
+type DocumentExpiryNotification struct {
+	TenantID       string
+	StaffID        string
+	RecipientEmail string
+	RecipientName  string
+	StaffName      string // Staff member the document belongs to (differs from the recipient for managers)
+	IsManager      bool
+	DocumentType   string
+	DocumentName   string
+	ExpiryDate     time.Time
+	DaysUntil      int // Zero or negative once the document has lapsed
+	IsMandatory    bool
+}
+
 // notificationRequest is the API request format for notification-service
 type notificationRequest struct {
 	Channel        string                 `json:"channel"`
@@ -87,6 +102,51 @@ func (c *NotificationClient) SendStaffInvitation(ctx context.Context, notificati
 	return c.sendNotification(ctx, notification.TenantID, notification.InviterID, req)
 }
 
+// SendDocumentExpiryReminder reminds a staff member, or their manager, that a document expires soon
+func (c *NotificationClient) SendDocumentExpiryReminder(ctx context.Context, notification *DocumentExpiryNotification) error {
+	subject := fmt.Sprintf("%s expires in %d days", notification.DocumentName, notification.DaysUntil)
+	if notification.IsManager {
+		subject = fmt.Sprintf("%s's %s expires in %d days", notification.StaffName, notification.DocumentName, notification.DaysUntil)
+	}
+	return c.sendDocumentExpiry(ctx, notification, "staff_document_expiry_reminder", subject)
+}
+
+// SendDocumentLapsed notifies a staff member, or their manager, that a document has expired
+func (c *NotificationClient) SendDocumentLapsed(ctx context.Context, notification *DocumentExpiryNotification) error {
+	subject := fmt.Sprintf("%s has expired", notification.DocumentName)
+	if notification.IsManager {
+		subject = fmt.Sprintf("%s's %s has expired", notification.StaffName, notification.DocumentName)
+	}
+	return c.sendDocumentExpiry(ctx, notification, "staff_document_expired", subject)
+}
+
+func (c *NotificationClient) sendDocumentExpiry(ctx context.Context, notification *DocumentExpiryNotification, templateName, subject string) error {
+	if notification.RecipientEmail == "" {
+		log.Printf("[STAFF] No email for recipient of staff %s document reminder, skipping", notification.StaffID)
+		return nil
+	}
+
+	req := &notificationRequest{
+		Channel:        "EMAIL",
+		RecipientEmail: notification.RecipientEmail,
+		Subject:        subject,
+		TemplateName:   templateName,
+		Variables: map[string]interface{}{
+			"recipientName": notification.RecipientName,
+			"staffName":     notification.StaffName,
+			"isManager":     notification.IsManager,
+			"documentType":  notification.DocumentType,
+			"documentName":  notification.DocumentName,
+			"expiryDate":    notification.ExpiryDate.Format("2006-01-02"),
+			"daysUntil":     notification.DaysUntil,
+			"isMandatory":   notification.IsMandatory,
+			"tenantId":      notification.TenantID,
+		},
+	}
+
+	return c.sendNotification(ctx, notification.TenantID, "staff-service", req)
+}
+
 // sendNotification sends a notification request to notification-service
 func (c *NotificationClient) sendNotification(ctx context.Context, tenantID, userID string, req *notificationRequest) error {
 	body, err := json.Marshal(req)
@@ -114,6 +174,6 @@ func (c *NotificationClient) sendNotification(ctx context.Context, tenantID, use
 		return fmt.Errorf("notification service returned status %d", resp.StatusCode)
 	}
 
-	log.Printf("[STAFF] Invitation email sent successfully to %s (template: %s)", req.RecipientEmail, req.TemplateName)
+	log.Printf("[STAFF] Email sent successfully to %s (template: %s)", req.RecipientEmail, req.TemplateName)
 	return nil
 }
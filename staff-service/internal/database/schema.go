@@ -0,0 +1,16 @@
+package database
+
+import "gorm.io/gorm"
+
+// serviceName names the service in the migration lock key and log messages
+const serviceName = "staff-service"
+
+// BaselineVersion is 0 because the staff schema has always been built from the SQL files in
+// migrations/, so every file is applied from SQL. Databases migrated by the golang-migrate CLI
+// are adopted at the version it had reached.
+const BaselineVersion = 0
+
+// SyncModels is a no-op: the GORM models follow the SQL schema and are never auto-migrated
+func SyncModels(db *gorm.DB) error {
+	return nil
+}
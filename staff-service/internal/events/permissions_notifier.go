@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// PermissionsNotifier publishes rbac.permissions_changed events. The publisher connects in
+// the background at startup, so changes made before it connects are not published; services
+// pick them up once their cached permissions expire.
+type PermissionsNotifier struct {
+	publisher atomic.Pointer[Publisher]
+	logger    *logrus.Entry
+}
+
+// NewPermissionsNotifier creates a notifier with no publisher yet
+func NewPermissionsNotifier(logger *logrus.Logger) *PermissionsNotifier {
+	return &PermissionsNotifier{logger: logger.WithField("component", "events.permissions")}
+}
+
+// SetPublisher starts publishing through the publisher
+func (n *PermissionsNotifier) SetPublisher(publisher *Publisher) {
+	n.publisher.Store(publisher)
+}
+
+// StaffChanged reports that the staff members' (or API keys') effective permissions changed
+func (n *PermissionsNotifier) StaffChanged(tenantID string, staffIDs ...uuid.UUID) {
+	ids := make([]string, 0, len(staffIDs))
+	for _, id := range staffIDs {
+		ids = append(ids, id.String())
+	}
+	n.publish(tenantID, ids)
+}
+
+// TenantChanged reports that effective permissions changed across the tenant, e.g. when a
+// role's permissions are edited
+func (n *PermissionsNotifier) TenantChanged(tenantID string) {
+	n.publish(tenantID, nil)
+}
+
+// publish sends the event in the background, since publishing retries on failure
+func (n *PermissionsNotifier) publish(tenantID string, staffIDs []string) {
+	if n == nil || tenantID == "" {
+		return
+	}
+	publisher := n.publisher.Load()
+	if publisher == nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := publisher.PublishPermissionsChanged(ctx, tenantID, staffIDs); err != nil {
+			n.logger.WithError(err).WithField("tenant_id", tenantID).Warn("Failed to publish permissions changed event")
+		}
+	}()
+}
@@ -3,11 +3,36 @@ package events
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/Tesseract-Nexus/go-shared/events"
 )
 
+const (
+	// RBACPermissionsChanged is published when roles, role permissions, role assignments or
+	// API key scopes change, so services drop cached effective permissions
+	RBACPermissionsChanged = "rbac.permissions_changed"
+	StreamRBAC             = "RBAC_EVENTS"
+)
+
+// PermissionsChangedEvent names the staff members (or API keys) whose effective permissions
+// changed. Without staff IDs, every staff member of the tenant is affected.
+type PermissionsChangedEvent struct {
+	events.BaseEvent
+	StaffIDs []string `json:"staffIds,omitempty"`
+}
+
+// GetSubject returns the NATS subject for the event
+func (e *PermissionsChangedEvent) GetSubject() string {
+	return RBACPermissionsChanged
+}
+
+// GetStream returns the JetStream stream for the event
+func (e *PermissionsChangedEvent) GetStream() string {
+	return StreamRBAC
+}
+
 // Publisher wraps the shared events publisher for staff-specific events
 type Publisher struct {
 	publisher *events.Publisher
@@ -33,6 +58,9 @@ func NewPublisher(logger *logrus.Logger) (*Publisher, error) {
 	if err := publisher.EnsureStream(ctx, events.StreamStaff, []string{"staff.>"}); err != nil {
 		logger.WithError(err).Warn("Failed to ensure STAFF_EVENTS stream")
 	}
+	if err := publisher.EnsureStream(ctx, StreamRBAC, []string{"rbac.>"}); err != nil {
+		logger.WithError(err).Warn("Failed to ensure RBAC_EVENTS stream")
+	}
 
 	return &Publisher{
 		publisher: publisher,
@@ -94,6 +122,21 @@ func (p *Publisher) PublishStaffRoleChanged(ctx context.Context, tenantID, staff
 	return p.publisher.Publish(ctx, event)
 }
 
+// PublishPermissionsChanged publishes a permissions changed event. With no staff IDs,
+// the whole tenant's cached permissions are dropped.
+func (p *Publisher) PublishPermissionsChanged(ctx context.Context, tenantID string, staffIDs []string) error {
+	event := &PermissionsChangedEvent{
+		BaseEvent: events.BaseEvent{
+			EventType: RBACPermissionsChanged,
+			TenantID:  tenantID,
+			Timestamp: time.Now().UTC(),
+		},
+		StaffIDs: staffIDs,
+	}
+
+	return p.publisher.Publish(ctx, event)
+}
+
 // IsConnected returns true if connected to NATS
 func (p *Publisher) IsConnected() bool {
 	return p.publisher.IsConnected()
@@ -0,0 +1,533 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"staff-service/internal/events"
+	"staff-service/internal/models"
+	"staff-service/internal/repository"
+)
+
+// APIKeyHandler manages tenant API keys for server-to-server integrations. Keys are
+// scoped to RBAC permissions; services that accept them validate keys through the
+// internal route and authorize requests with the key's permissions.
+type APIKeyHandler struct {
+	repo     repository.APIKeyRepository
+	rbacRepo repository.RBACRepository
+	notifier *events.PermissionsNotifier
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(repo repository.APIKeyRepository, rbacRepo repository.RBACRepository) *APIKeyHandler {
+	return &APIKeyHandler{repo: repo, rbacRepo: rbacRepo}
+}
+
+// SetPermissionsNotifier publishes rbac.permissions_changed when a key's scope changes or it
+// is revoked, so services stop using its cached permissions
+func (h *APIKeyHandler) SetPermissionsNotifier(notifier *events.PermissionsNotifier) {
+	h.notifier = notifier
+}
+
+// ListAPIKeys returns the tenant's API keys
+// GET /api/v1/api-keys?include_revoked=true
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	keys, err := h.repo.List(tenantID, getVendorID(c), c.Query("include_revoked") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve API keys"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    keys,
+	})
+}
+
+// GetAPIKey returns one API key
+// GET /api/v1/api-keys/:id
+func (h *APIKeyHandler) GetAPIKey(c *gin.Context) {
+	key, ok := h.loadKey(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    key,
+	})
+}
+
+// CreateAPIKey issues a new API key. The key is returned once and can't be retrieved later.
+// POST /api/v1/api-keys
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	userID := c.GetString("user_id")
+
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	rateLimit, ok := validAPIKeyRateLimit(c, req.RateLimitPerMinute)
+	if !ok {
+		return
+	}
+	permissions, ok := h.grantablePermissions(c, req.Permissions)
+	if !ok {
+		return
+	}
+
+	secret, keyHash, err := generateAPIKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "CREATE_FAILED", Message: "Failed to generate API key"},
+		})
+		return
+	}
+
+	now := time.Now()
+	key := &models.TenantAPIKey{
+		TenantID:           tenantID,
+		VendorID:           getVendorID(c),
+		Name:               strings.TrimSpace(req.Name),
+		Description:        req.Description,
+		KeyPrefix:          secret[:12],
+		KeyHash:            keyHash,
+		Permissions:        permissions,
+		RateLimitPerMinute: rateLimit,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if req.ExpiresInDays != nil {
+		if *req.ExpiresInDays <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   models.Error{Code: "VALIDATION_ERROR", Message: "expiresInDays must be positive"},
+			})
+			return
+		}
+		expiresAt := now.AddDate(0, 0, *req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+	if userID != "" {
+		key.CreatedBy = &userID
+	}
+
+	if err := h.repo.Create(key); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "CREATE_FAILED", Message: "Failed to save API key"},
+		})
+		return
+	}
+
+	log.Printf("[API_KEYS] Created API key %s (%s) for tenant %s by %s", key.ID, key.KeyPrefix, tenantID, userID)
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    models.APIKeySecretResponse{APIKey: key, Key: secret},
+	})
+}
+
+// UpdateAPIKey changes an API key's name, scope or rate limit
+// PUT /api/v1/api-keys/:id
+func (h *APIKeyHandler) UpdateAPIKey(c *gin.Context) {
+	var req models.UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	key, ok := h.loadUsableKey(c)
+	if !ok {
+		return
+	}
+
+	if req.Name != nil && strings.TrimSpace(*req.Name) != "" {
+		key.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		key.Description = req.Description
+	}
+	if req.RateLimitPerMinute != nil {
+		rateLimit, ok := validAPIKeyRateLimit(c, req.RateLimitPerMinute)
+		if !ok {
+			return
+		}
+		key.RateLimitPerMinute = rateLimit
+	}
+	if req.Permissions != nil {
+		permissions, ok := h.grantablePermissions(c, req.Permissions)
+		if !ok {
+			return
+		}
+		key.Permissions = permissions
+	}
+
+	if err := h.repo.Save(key); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "UPDATE_FAILED", Message: "Failed to update API key"},
+		})
+		return
+	}
+	h.notifier.StaffChanged(key.TenantID, key.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    key,
+	})
+}
+
+// RotateAPIKey issues a new secret for an API key. The previous secret stays valid for
+// the grace period (24 hours by default, 0 to invalidate it immediately).
+// POST /api/v1/api-keys/:id/rotate
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	var req models.RotateAPIKeyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+			})
+			return
+		}
+	}
+	graceHours := models.DefaultAPIKeyGraceHours
+	if req.GracePeriodHours != nil {
+		graceHours = *req.GracePeriodHours
+	}
+	if graceHours < 0 || graceHours > models.MaxAPIKeyGraceHours {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: fmt.Sprintf("gracePeriodHours must be between 0 and %d", models.MaxAPIKeyGraceHours),
+			},
+		})
+		return
+	}
+
+	key, ok := h.loadUsableKey(c)
+	if !ok {
+		return
+	}
+
+	secret, keyHash, err := generateAPIKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "ROTATE_FAILED", Message: "Failed to generate API key"},
+		})
+		return
+	}
+
+	now := time.Now()
+	key.PreviousKeyHash = nil
+	key.PreviousKeyExpiresAt = nil
+	if graceHours > 0 {
+		previousHash := key.KeyHash
+		previousExpiresAt := now.Add(time.Duration(graceHours) * time.Hour)
+		key.PreviousKeyHash = &previousHash
+		key.PreviousKeyExpiresAt = &previousExpiresAt
+	}
+	key.KeyHash = keyHash
+	key.KeyPrefix = secret[:12]
+	key.RotatedAt = &now
+
+	if err := h.repo.Save(key); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "ROTATE_FAILED", Message: "Failed to save rotated API key"},
+		})
+		return
+	}
+
+	log.Printf("[API_KEYS] Rotated API key %s for tenant %s (grace period %dh)", key.ID, key.TenantID, graceHours)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    models.APIKeySecretResponse{APIKey: key, Key: secret},
+	})
+}
+
+// RevokeAPIKey revokes an API key immediately, including a previous secret in its grace period
+// DELETE /api/v1/api-keys/:id
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	userID := c.GetString("user_id")
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_ID", Message: "Invalid API key ID"},
+		})
+		return
+	}
+
+	revoked, err := h.repo.Revoke(tenantID, id, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "REVOKE_FAILED", Message: "Failed to revoke API key"},
+		})
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "NOT_FOUND", Message: "API key not found or already revoked"},
+		})
+		return
+	}
+
+	log.Printf("[API_KEYS] Revoked API key %s for tenant %s by %s", id, tenantID, userID)
+	h.notifier.StaffChanged(tenantID, id)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "API key revoked",
+	})
+}
+
+// ValidateAPIKeyInternal resolves an API key to its tenant, scope and rate limit.
+// Called by services that accept API keys; they cache the result briefly.
+// POST /api/v1/internal/api-keys/validate
+func (h *APIKeyHandler) ValidateAPIKeyInternal(c *gin.Context) {
+	var req models.ValidateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	key, err := h.repo.GetByHash(hashAPIKey(req.Key))
+	if err != nil || !key.IsUsable() {
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("[API_KEYS] Failed to look up API key: %v", err)
+		}
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_API_KEY", Message: "Invalid, expired or revoked API key"},
+		})
+		return
+	}
+
+	// Callers cache validations, so recording every use would only add writes
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > time.Minute {
+		if err := h.repo.TouchLastUsed(key.ID, now); err != nil {
+			log.Printf("[API_KEYS] Failed to record use of API key %s: %v", key.ID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": models.ValidatedAPIKey{
+			KeyID:              key.ID,
+			TenantID:           key.TenantID,
+			VendorID:           key.VendorID,
+			Name:               key.Name,
+			Permissions:        key.PermissionNames(),
+			RateLimitPerMinute: key.RateLimitPerMinute,
+		},
+	})
+}
+
+// loadKey loads the API key in the path, writing the error response if there is none
+func (h *APIKeyHandler) loadKey(c *gin.Context) (*models.TenantAPIKey, bool) {
+	tenantID := c.GetString("tenant_id")
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_ID", Message: "Invalid API key ID"},
+		})
+		return nil, false
+	}
+
+	key, err := h.repo.GetByID(tenantID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "NOT_FOUND", Message: "API key not found"},
+		})
+		return nil, false
+	}
+	return key, true
+}
+
+// loadUsableKey loads the API key in the path, rejecting revoked and expired keys
+func (h *APIKeyHandler) loadUsableKey(c *gin.Context) (*models.TenantAPIKey, bool) {
+	key, ok := h.loadKey(c)
+	if !ok {
+		return nil, false
+	}
+	if !key.IsUsable() {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "API_KEY_INACTIVE", Message: "API key is revoked or expired"},
+		})
+		return nil, false
+	}
+	return key, true
+}
+
+// grantablePermissions checks that the permissions exist and that the caller holds them,
+// so an API key can never do more than the staff member who scoped it
+func (h *APIKeyHandler) grantablePermissions(c *gin.Context, names []string) (models.JSONArray, bool) {
+	tenantID := c.GetString("tenant_id")
+
+	unique := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	if len(unique) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: "At least one permission is required"},
+		})
+		return nil, false
+	}
+
+	known, err := h.repo.GetPermissionsByNames(unique)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve permissions"},
+		})
+		return nil, false
+	}
+	if len(known) != len(unique) {
+		knownNames := make(map[string]bool, len(known))
+		for _, p := range known {
+			knownNames[p.Name] = true
+		}
+		var unknown []string
+		for _, name := range unique {
+			if !knownNames[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "UNKNOWN_PERMISSION", Message: "Unknown permissions: " + strings.Join(unknown, ", ")},
+		})
+		return nil, false
+	}
+
+	staffID, err := uuid.Parse(c.GetString("staff_id"))
+	if err != nil {
+		staffID, err = uuid.Parse(c.GetString("user_id"))
+	}
+	if err != nil {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FORBIDDEN", Message: "User context not found"},
+		})
+		return nil, false
+	}
+	held, err := h.rbacRepo.GetStaffEffectivePermissions(tenantID, getVendorID(c), staffID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FORBIDDEN", Message: "Failed to verify permissions"},
+		})
+		return nil, false
+	}
+
+	// Store owners can grant any permission
+	if held.MaxPriority < 100 {
+		heldNames := make(map[string]bool, len(held.Permissions))
+		for _, p := range held.Permissions {
+			heldNames[p.Name] = true
+		}
+		for _, name := range unique {
+			if !heldNames[name] {
+				c.JSON(http.StatusForbidden, models.ErrorResponse{
+					Success: false,
+					Error:   models.Error{Code: "FORBIDDEN", Message: "You can't grant a permission you don't hold: " + name},
+				})
+				return nil, false
+			}
+		}
+	}
+
+	permissions := make(models.JSONArray, len(unique))
+	for i, name := range unique {
+		permissions[i] = name
+	}
+	return permissions, true
+}
+
+// validAPIKeyRateLimit applies the default rate limit and checks the bounds
+func validAPIKeyRateLimit(c *gin.Context, rateLimit *int) (int, bool) {
+	if rateLimit == nil {
+		return models.DefaultAPIKeyRateLimit, true
+	}
+	if *rateLimit < 1 || *rateLimit > models.MaxAPIKeyRateLimit {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: fmt.Sprintf("rateLimitPerMinute must be between 1 and %d", models.MaxAPIKeyRateLimit),
+			},
+		})
+		return 0, false
+	}
+	return *rateLimit, true
+}
+
+// generateAPIKey returns a new random key and its hash
+func generateAPIKey() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	key := models.APIKeyPrefix + hex.EncodeToString(b)
+	return key, hashAPIKey(key), nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// getVendorID returns the vendor of the request, or nil for tenant-level requests
+func getVendorID(c *gin.Context) *string {
+	vendorID := c.GetString("vendor_id")
+	if vendorID == "" {
+		return nil
+	}
+	return &vendorID
+}
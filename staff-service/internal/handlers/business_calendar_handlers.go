@@ -0,0 +1,426 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"staff-service/internal/models"
+	"staff-service/internal/repository"
+)
+
+// BusinessCalendarHandler manages tenant business calendars: working hours and holidays per
+// region. tickets-service SLA timers and approval-service escalation timers fetch them
+// through the internal route and only count time while the calendar is open.
+type BusinessCalendarHandler struct {
+	repo repository.BusinessCalendarRepository
+}
+
+// NewBusinessCalendarHandler creates a new business calendar handler
+func NewBusinessCalendarHandler(repo repository.BusinessCalendarRepository) *BusinessCalendarHandler {
+	return &BusinessCalendarHandler{repo: repo}
+}
+
+// ListCalendars returns the tenant's business calendars, default first
+// GET /api/v1/business-calendars
+func (h *BusinessCalendarHandler) ListCalendars(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	calendars, err := h.repo.ListCalendars(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve business calendars"},
+		})
+		return
+	}
+	if calendars == nil {
+		calendars = []models.BusinessCalendar{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    calendars,
+	})
+}
+
+// CreateCalendar creates a business calendar
+// POST /api/v1/business-calendars
+func (h *BusinessCalendarHandler) CreateCalendar(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	userID := c.GetString("user_id")
+
+	var req models.CreateBusinessCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	calendar := &models.BusinessCalendar{
+		TenantID:     tenantID,
+		Name:         strings.TrimSpace(req.Name),
+		Region:       normalizeRegion(req.Region),
+		Timezone:     req.Timezone,
+		IsDefault:    req.IsDefault,
+		WorkingHours: req.WorkingHours,
+	}
+	if userID != "" {
+		calendar.UpdatedBy = &userID
+	}
+	if !validateCalendar(c, calendar) {
+		return
+	}
+
+	if err := h.repo.CreateCalendar(calendar); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Success: false,
+				Error:   models.Error{Code: "DUPLICATE_NAME", Message: "A business calendar with this name already exists"},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "CREATE_FAILED", Message: "Failed to create business calendar"},
+		})
+		return
+	}
+	calendar.Holidays = []models.BusinessHoliday{}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    calendar,
+	})
+}
+
+// GetCalendar returns a business calendar with its holidays
+// GET /api/v1/business-calendars/:id
+func (h *BusinessCalendarHandler) GetCalendar(c *gin.Context) {
+	calendar, ok := h.loadCalendar(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    calendar,
+	})
+}
+
+// UpdateCalendar updates a business calendar's name, region, timezone, default flag or working hours
+// PUT /api/v1/business-calendars/:id
+func (h *BusinessCalendarHandler) UpdateCalendar(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	calendar, ok := h.loadCalendar(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateBusinessCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	if req.Name != nil {
+		calendar.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Region != nil {
+		calendar.Region = normalizeRegion(*req.Region)
+	}
+	if req.Timezone != nil {
+		calendar.Timezone = *req.Timezone
+	}
+	if req.IsDefault != nil {
+		calendar.IsDefault = *req.IsDefault
+	}
+	if req.WorkingHours != nil {
+		calendar.WorkingHours = req.WorkingHours
+	}
+	if userID != "" {
+		calendar.UpdatedBy = &userID
+	}
+	if !validateCalendar(c, calendar) {
+		return
+	}
+
+	if err := h.repo.UpdateCalendar(calendar); err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Success: false,
+				Error:   models.Error{Code: "DUPLICATE_NAME", Message: "A business calendar with this name already exists"},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "UPDATE_FAILED", Message: "Failed to update business calendar"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    calendar,
+	})
+}
+
+// DeleteCalendar deletes a business calendar and its holidays
+// DELETE /api/v1/business-calendars/:id
+func (h *BusinessCalendarHandler) DeleteCalendar(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_ID", Message: "Invalid calendar ID format"},
+		})
+		return
+	}
+
+	deleted, err := h.repo.DeleteCalendar(tenantID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "DELETE_FAILED", Message: "Failed to delete business calendar"},
+		})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "NOT_FOUND", Message: "Business calendar not found"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Business calendar deleted",
+	})
+}
+
+// AddHoliday closes a business calendar for a day
+// POST /api/v1/business-calendars/:id/holidays
+func (h *BusinessCalendarHandler) AddHoliday(c *gin.Context) {
+	calendar, ok := h.loadCalendar(c)
+	if !ok {
+		return
+	}
+
+	var req models.AddBusinessHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: "date must be YYYY-MM-DD", Field: "date"},
+		})
+		return
+	}
+
+	holiday := &models.BusinessHoliday{
+		CalendarID: calendar.ID,
+		Date:       date,
+		Name:       strings.TrimSpace(req.Name),
+		Recurring:  req.Recurring,
+	}
+	if err := h.repo.AddHoliday(holiday); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "CREATE_FAILED", Message: "Failed to add holiday"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    holiday,
+	})
+}
+
+// DeleteHoliday removes a holiday from a business calendar
+// DELETE /api/v1/business-calendars/:id/holidays/:holidayId
+func (h *BusinessCalendarHandler) DeleteHoliday(c *gin.Context) {
+	calendar, ok := h.loadCalendar(c)
+	if !ok {
+		return
+	}
+
+	holidayID, err := uuid.Parse(c.Param("holidayId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_ID", Message: "Invalid holiday ID format"},
+		})
+		return
+	}
+
+	deleted, err := h.repo.DeleteHoliday(calendar.ID, holidayID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "DELETE_FAILED", Message: "Failed to delete holiday"},
+		})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "NOT_FOUND", Message: "Holiday not found"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Holiday deleted",
+	})
+}
+
+// GetElapsed returns the business minutes between two instants on a calendar
+// GET /api/v1/business-calendars/:id/elapsed?from=&to=
+func (h *BusinessCalendarHandler) GetElapsed(c *gin.Context) {
+	calendar, ok := h.loadCalendar(c)
+	if !ok {
+		return
+	}
+
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: "from must be an RFC3339 timestamp", Field: "from"},
+		})
+		return
+	}
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		if to, err = time.Parse(time.RFC3339, toStr); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   models.Error{Code: "VALIDATION_ERROR", Message: "to must be an RFC3339 timestamp", Field: "to"},
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": models.ElapsedBusinessTime{
+			CalendarID:     calendar.ID,
+			From:           from,
+			To:             to,
+			ElapsedMinutes: calendar.ElapsedBusinessMinutes(from, to),
+		},
+	})
+}
+
+// ResolveCalendarInternal returns the calendar that applies to a region, falling back to
+// the tenant's default calendar. Responds 404 when the tenant has no calendar.
+// GET /api/v1/internal/business-calendars/resolve?region= - called by tickets-service and approval-service
+func (h *BusinessCalendarHandler) ResolveCalendarInternal(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: "Tenant ID is required"},
+		})
+		return
+	}
+
+	calendar, err := h.repo.ResolveCalendar(tenantID, normalizeRegion(c.Query("region")))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error:   models.Error{Code: "NOT_FOUND", Message: "Tenant has no business calendar"},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to resolve business calendar"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    calendar,
+	})
+}
+
+// loadCalendar loads the tenant's calendar named by the :id param. Writes the error
+// response and returns false on failure.
+func (h *BusinessCalendarHandler) loadCalendar(c *gin.Context) (*models.BusinessCalendar, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_ID", Message: "Invalid calendar ID format"},
+		})
+		return nil, false
+	}
+
+	calendar, err := h.repo.GetCalendar(c.GetString("tenant_id"), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error:   models.Error{Code: "NOT_FOUND", Message: "Business calendar not found"},
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve business calendar"},
+		})
+		return nil, false
+	}
+	return calendar, true
+}
+
+// validateCalendar checks a calendar's name, timezone and working hours. Writes the error
+// response and returns false when invalid.
+func validateCalendar(c *gin.Context, calendar *models.BusinessCalendar) bool {
+	var field, message string
+	if calendar.Name == "" {
+		field, message = "name", "name is required"
+	} else if _, err := time.LoadLocation(calendar.Timezone); err != nil || calendar.Timezone == "" {
+		field, message = "timezone", "timezone must be an IANA timezone such as Australia/Sydney"
+	} else if err := models.ValidateWorkingHours(calendar.WorkingHours); err != nil {
+		field, message = "workingHours", err.Error()
+	} else {
+		return true
+	}
+
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Success: false,
+		Error:   models.Error{Code: "VALIDATION_ERROR", Message: message, Field: field},
+	})
+	return false
+}
+
+// normalizeRegion upper-cases region codes so "au-nsw" and "AU-NSW" match
+func normalizeRegion(region string) string {
+	return strings.ToUpper(strings.TrimSpace(region))
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"staff-service/internal/models"
+	"staff-service/internal/repository"
+	"staff-service/internal/services"
+)
+
+// GroupSyncHandler manages the mapping of Keycloak groups to departments and teams.
+// Memberships are synced from the groups claim on login and reconciled against Keycloak
+// by a scheduled job, so users who leave a group lose the membership.
+type GroupSyncHandler struct {
+	repo        repository.GroupSyncRepository
+	syncService *services.KeycloakRoleSyncService
+}
+
+// NewGroupSyncHandler creates a new group sync handler. syncService may be nil when
+// Keycloak role sync is disabled, in which case manual reconciliation is unavailable.
+func NewGroupSyncHandler(repo repository.GroupSyncRepository, syncService *services.KeycloakRoleSyncService) *GroupSyncHandler {
+	return &GroupSyncHandler{repo: repo, syncService: syncService}
+}
+
+// GetSettings returns the tenant's group sync settings
+// GET /api/v1/keycloak-group-sync/settings
+func (h *GroupSyncHandler) GetSettings(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	settings, err := h.repo.GetSettings(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve group sync settings"},
+		})
+		return
+	}
+	if settings == nil {
+		settings = defaultGroupSyncSettings(tenantID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// UpdateSettings enables or disables group sync and its options for the tenant
+// PUT /api/v1/keycloak-group-sync/settings
+func (h *GroupSyncHandler) UpdateSettings(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	userID := c.GetString("user_id")
+
+	var req models.UpdateGroupSyncSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	settings, err := h.repo.GetSettings(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve group sync settings"},
+		})
+		return
+	}
+	if settings == nil {
+		settings = defaultGroupSyncSettings(tenantID)
+	}
+
+	if req.Enabled != nil {
+		settings.Enabled = *req.Enabled
+	}
+	if req.ReconcileRemovals != nil {
+		settings.ReconcileRemovals = *req.ReconcileRemovals
+	}
+	if req.SetPrimary != nil {
+		settings.SetPrimary = *req.SetPrimary
+	}
+	if userID != "" {
+		settings.UpdatedBy = &userID
+	}
+
+	if err := h.repo.SaveSettings(settings); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "SAVE_FAILED", Message: "Failed to save group sync settings"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// ListMappings returns the tenant's group mappings
+// GET /api/v1/keycloak-group-sync/mappings
+func (h *GroupSyncHandler) ListMappings(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	mappings, err := h.repo.ListMappings(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve group mappings"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mappings,
+	})
+}
+
+// SaveMapping creates or replaces the mapping of a group path to a department and team
+// PUT /api/v1/keycloak-group-sync/mappings
+func (h *GroupSyncHandler) SaveMapping(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	userID := c.GetString("user_id")
+
+	var req models.SaveGroupMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	groupPath := strings.TrimSuffix(strings.TrimSpace(req.GroupPath), "/")
+	if !strings.HasPrefix(groupPath, "/") || len(groupPath) < 2 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: "groupPath must be a full Keycloak group path, e.g. /Sales/EMEA"},
+		})
+		return
+	}
+
+	dept, err := h.repo.GetDepartmentByCode(tenantID, req.DepartmentCode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: "No active department with code " + req.DepartmentCode},
+		})
+		return
+	}
+
+	var teamCode *string
+	if req.TeamCode != nil && *req.TeamCode != "" {
+		if _, err := h.repo.GetTeamByCode(tenantID, dept.ID, *req.TeamCode); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   models.Error{Code: "VALIDATION_ERROR", Message: "No active team with code " + *req.TeamCode + " in department " + req.DepartmentCode},
+			})
+			return
+		}
+		teamCode = req.TeamCode
+	}
+
+	mapping := &models.KeycloakGroupMapping{
+		TenantID:       tenantID,
+		GroupPath:      groupPath,
+		DepartmentCode: req.DepartmentCode,
+		TeamCode:       teamCode,
+		Priority:       req.Priority,
+	}
+	if userID != "" {
+		mapping.UpdatedBy = &userID
+	}
+
+	if err := h.repo.SaveMapping(mapping); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "SAVE_FAILED", Message: "Failed to save group mapping"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mapping,
+	})
+}
+
+// DeleteMapping removes a group mapping
+// DELETE /api/v1/keycloak-group-sync/mappings/:id
+func (h *GroupSyncHandler) DeleteMapping(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_ID", Message: "Invalid mapping ID"},
+		})
+		return
+	}
+
+	deleted, err := h.repo.DeleteMapping(tenantID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "DELETE_FAILED", Message: "Failed to delete group mapping"},
+		})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "NOT_FOUND", Message: "Group mapping not found"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Group mapping deleted",
+	})
+}
+
+// Reconcile re-reads the groups of the tenant's members from Keycloak and removes the
+// memberships of groups they have left
+// POST /api/v1/keycloak-group-sync/reconcile
+func (h *GroupSyncHandler) Reconcile(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	if h.syncService == nil || !h.syncService.GroupSyncEnabled() {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "SYNC_UNAVAILABLE", Message: "Keycloak group sync is not configured"},
+		})
+		return
+	}
+
+	result, err := h.syncService.ReconcileGroupMemberships(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "RECONCILE_FAILED", Message: err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// defaultGroupSyncSettings returns the settings of a tenant that hasn't configured group sync
+func defaultGroupSyncSettings(tenantID string) *models.GroupSyncSettings {
+	return &models.GroupSyncSettings{
+		TenantID:          tenantID,
+		Enabled:           false,
+		ReconcileRemovals: true,
+		SetPrimary:        true,
+	}
+}
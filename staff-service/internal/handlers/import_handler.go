@@ -11,18 +11,21 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"staff-service/internal/bulk"
 	"staff-service/internal/models"
 	"staff-service/internal/repository"
 	"github.com/xuri/excelize/v2"
 )
 
 type ImportHandler struct {
-	repo repository.StaffRepository
+	repo        repository.StaffRepository
+	mappingRepo repository.ImportMappingRepository
 }
 
-func NewImportHandler(repo repository.StaffRepository) *ImportHandler {
+func NewImportHandler(repo repository.StaffRepository, mappingRepo repository.ImportMappingRepository) *ImportHandler {
 	return &ImportHandler{
-		repo: repo,
+		repo:        repo,
+		mappingRepo: mappingRepo,
 	}
 }
 
@@ -266,6 +269,20 @@ func (h *ImportHandler) ImportStaff(c *gin.Context) {
 		return
 	}
 
+	// Map source headers onto template columns
+	mapping, err := h.resolveColumnMapping(c, tenantIDStr, "staff")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_MAPPING",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	rows = applyColumnMapping(rows, mapping)
+
 	// Limit to 100 rows per import
 	if len(rows) > 100 {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
@@ -488,40 +505,30 @@ func (h *ImportHandler) processImportRows(tenantID, userID, vendorID, businessCo
 	}
 
 	// Bulk create with auto-generated employee IDs
-	bulkResult, err := h.repo.BulkCreateWithEmployeeIDs(tenantID, vendorID, businessCode, staffList, skipDuplicates)
-	if err != nil && bulkResult == nil {
-		result.Success = false
-		result.Errors = append(result.Errors, models.ImportRowError{
-			Row:     0,
-			Code:    "BULK_CREATE_FAILED",
-			Message: err.Error(),
-		})
-		return result
-	}
-
-	// Add created staff IDs
-	for _, staff := range bulkResult.Created {
-		result.CreatedIDs = append(result.CreatedIDs, staff.ID.String())
-	}
-
-	// Add bulk create errors
-	for _, bulkErr := range bulkResult.Errors {
-		rowNum := 0
-		if bulkErr.Index < len(rows) {
-			rowNum, _ = strconv.Atoi(rows[bulkErr.Index]["_row"])
+	bulkResult := h.repo.BulkCreateWithEmployeeIDs(tenantID, vendorID, businessCode, staffList, skipDuplicates, bulk.Options{})
+
+	for _, item := range bulkResult.Results {
+		switch item.Status {
+		case bulk.StatusCreated:
+			result.CreatedIDs = append(result.CreatedIDs, item.Data.(*models.Staff).ID.String())
+		case bulk.StatusFailed:
+			rowNum := 0
+			if item.Index < len(rows) {
+				rowNum, _ = strconv.Atoi(rows[item.Index]["_row"])
+			}
+			result.Errors = append(result.Errors, models.ImportRowError{
+				Row:     rowNum,
+				Code:    item.Error.Code,
+				Message: item.Error.Message,
+			})
 		}
-		result.Errors = append(result.Errors, models.ImportRowError{
-			Row:     rowNum,
-			Code:    bulkErr.Code,
-			Message: bulkErr.Message,
-		})
 	}
 
-	result.Success = bulkResult.Success > 0 || bulkResult.Skipped > 0
-	result.SuccessCount = bulkResult.Success
-	result.CreatedCount = len(bulkResult.Created)
-	result.FailedCount = bulkResult.Failed + (result.TotalRows - len(staffList))
-	result.SkippedCount = result.TotalRows - len(staffList) - bulkResult.Failed + bulkResult.Skipped
+	result.Success = bulkResult.SuccessCount > 0 || bulkResult.SkippedCount > 0
+	result.SuccessCount = bulkResult.SuccessCount
+	result.CreatedCount = bulkResult.SuccessCount
+	result.FailedCount = bulkResult.FailedCount + (result.TotalRows - len(staffList))
+	result.SkippedCount = result.TotalRows - len(staffList) - bulkResult.FailedCount + bulkResult.SkippedCount
 
 	return result
 }
@@ -881,6 +888,16 @@ func (h *ImportHandler) ImportDepartments(c *gin.Context) {
 		return
 	}
 
+	mapping, err := h.resolveColumnMapping(c, tenantIDStr, "departments")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_MAPPING", Message: err.Error()},
+		})
+		return
+	}
+	rows = applyColumnMapping(rows, mapping)
+
 	if len(rows) > 100 {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
@@ -1111,6 +1128,16 @@ func (h *ImportHandler) ImportTeams(c *gin.Context) {
 		return
 	}
 
+	mapping, err := h.resolveColumnMapping(c, tenantIDStr, "teams")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_MAPPING", Message: err.Error()},
+		})
+		return
+	}
+	rows = applyColumnMapping(rows, mapping)
+
 	if len(rows) > 100 {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
@@ -1343,6 +1370,16 @@ func (h *ImportHandler) ImportRoles(c *gin.Context) {
 		return
 	}
 
+	mapping, err := h.resolveColumnMapping(c, tenantIDStr, "roles")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_MAPPING", Message: err.Error()},
+		})
+		return
+	}
+	rows = applyColumnMapping(rows, mapping)
+
 	if len(rows) > 100 {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
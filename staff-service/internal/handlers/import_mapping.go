@@ -0,0 +1,572 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"staff-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+)
+
+// readImportHeaders returns the normalized header row of an uploaded file in column order.
+// For XLSX files the "Staff" sheet is preferred, as the parsers do.
+func readImportHeaders(format models.ImportFormat, data []byte) ([]string, error) {
+	var headers []string
+	if format == models.ImportFormatCSV {
+		record, err := csv.NewReader(bytes.NewReader(data)).Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV header: %w", err)
+		}
+		headers = record
+	} else {
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Excel file: %w", err)
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("no sheets found in Excel file")
+		}
+		sheetName := sheets[0]
+		for _, name := range sheets {
+			if strings.EqualFold(name, "Staff") {
+				sheetName = name
+				break
+			}
+		}
+
+		excelRows, err := f.GetRows(sheetName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sheet: %w", err)
+		}
+		if len(excelRows) == 0 {
+			return nil, fmt.Errorf("file must have a header row")
+		}
+		headers = excelRows[0]
+	}
+
+	for i := range headers {
+		headers[i] = normalizeImportHeader(headers[i])
+	}
+	return headers, nil
+}
+
+// importTemplateFor returns the import template for an importable entity
+func importTemplateFor(entity string) (models.ImportTemplate, bool) {
+	switch entity {
+	case "staff":
+		return models.StaffImportTemplate(), true
+	case "departments":
+		return models.DepartmentImportTemplate(), true
+	case "teams":
+		return models.TeamImportTemplate(), true
+	case "roles":
+		return models.ImportTemplate{Entity: "role", Version: "1.0", Columns: models.RoleImportColumns()}, true
+	}
+	return models.ImportTemplate{}, false
+}
+
+// normalizeImportHeader normalizes a source header the same way the parsers do
+func normalizeImportHeader(header string) string {
+	return strings.TrimSuffix(strings.TrimSpace(strings.ToLower(header)), " *")
+}
+
+// canonicalImportKey strips case and punctuation so "Cost Price", "cost_price" and "costPrice" compare equal
+func canonicalImportKey(header string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(header) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// suggestColumnMapping matches source headers to template columns by canonical name
+func suggestColumnMapping(headers []string, columns []models.ImportTemplateColumn) map[string]string {
+	byKey := make(map[string]string, len(columns))
+	for _, col := range columns {
+		byKey[canonicalImportKey(col.Name)] = col.Name
+	}
+
+	suggested := make(map[string]string)
+	for _, header := range headers {
+		if name, ok := byKey[canonicalImportKey(header)]; ok {
+			suggested[header] = name
+		}
+	}
+	return suggested
+}
+
+// normalizeColumnMapping lower-cases both sides of a mapping and drops empty targets
+func normalizeColumnMapping(mapping map[string]string) map[string]string {
+	normalized := make(map[string]string, len(mapping))
+	for source, target := range mapping {
+		source = normalizeImportHeader(source)
+		target = strings.ToLower(strings.TrimSpace(target))
+		if source == "" || target == "" {
+			continue
+		}
+		normalized[source] = target
+	}
+	return normalized
+}
+
+// applyColumnMapping renames row keys from source headers to template column names.
+// Unmapped headers pass through unchanged, so files already using template headers need no mapping.
+func applyColumnMapping(rows []map[string]string, mapping map[string]string) []map[string]string {
+	if len(mapping) == 0 {
+		return rows
+	}
+	for i, row := range rows {
+		mapped := make(map[string]string, len(row))
+		for key, value := range row {
+			if target, ok := mapping[key]; ok {
+				if existing := mapped[target]; existing == "" {
+					mapped[target] = value
+				}
+				continue
+			}
+			if _, set := mapped[key]; !set {
+				mapped[key] = value
+			}
+		}
+		rows[i] = mapped
+	}
+	return rows
+}
+
+// resolveColumnMapping reads the column mapping for an import request from the
+// "mapping" form field (JSON object) or a saved preset referenced by "presetId"
+func (h *ImportHandler) resolveColumnMapping(c *gin.Context, tenantID, entity string) (map[string]string, error) {
+	if raw := c.PostForm("mapping"); raw != "" {
+		var mapping map[string]string
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			return nil, fmt.Errorf("mapping must be a JSON object of sourceHeader to column name")
+		}
+		return normalizeColumnMapping(mapping), nil
+	}
+
+	presetID := c.PostForm("presetId")
+	if presetID == "" {
+		return nil, nil
+	}
+	if h.mappingRepo == nil {
+		return nil, fmt.Errorf("mapping presets are not configured")
+	}
+	id, err := uuid.Parse(presetID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid presetId")
+	}
+	preset, err := h.mappingRepo.GetByID(tenantID, id)
+	if err != nil || preset.Entity != entity {
+		return nil, fmt.Errorf("mapping preset not found")
+	}
+	return presetMapping(preset), nil
+}
+
+// presetMapping converts a stored preset into a normalized mapping
+func presetMapping(preset *models.ImportMappingPreset) map[string]string {
+	mapping := make(map[string]string, len(preset.Mapping))
+	for source, target := range preset.Mapping {
+		if s, ok := target.(string); ok {
+			mapping[source] = s
+		}
+	}
+	return normalizeColumnMapping(mapping)
+}
+
+// validateImportRow checks required columns and column types against the template.
+// It performs no lookups, so it is safe to run on preview rows without side effects.
+func validateImportRow(row map[string]string, rowNum int, columns []models.ImportTemplateColumn) []models.ImportRowError {
+	var rowErrors []models.ImportRowError
+	for _, col := range columns {
+		value := row[strings.ToLower(col.Name)]
+		if value == "" {
+			if col.Required {
+				rowErrors = append(rowErrors, models.ImportRowError{
+					Row: rowNum, Column: col.Name, Code: "REQUIRED",
+					Message: fmt.Sprintf("%s is required", col.Name),
+				})
+			}
+			continue
+		}
+
+		var err error
+		switch col.Type {
+		case "number":
+			_, err = strconv.ParseFloat(value, 64)
+		case "boolean":
+			_, err = strconv.ParseBool(value)
+		case "uuid":
+			_, err = uuid.Parse(value)
+		case "email":
+			if !strings.Contains(value, "@") {
+				err = errors.New("invalid email")
+			}
+		case "date":
+			if _, perr := time.Parse("2006-01-02", value); perr != nil {
+				_, err = time.Parse(time.RFC3339, value)
+			}
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, models.ImportRowError{
+				Row: rowNum, Column: col.Name, Code: "INVALID",
+				Message: fmt.Sprintf("%s must be a valid %s", col.Name, col.Type),
+			})
+		}
+	}
+	return rowErrors
+}
+
+// PreviewStaffImport previews a staff import file
+// POST /api/v1/staff/import/preview
+func (h *ImportHandler) PreviewStaffImport(c *gin.Context) {
+	h.previewImport(c, "staff")
+}
+
+// PreviewDepartmentImport previews a department import file
+// POST /api/v1/departments/import/preview
+func (h *ImportHandler) PreviewDepartmentImport(c *gin.Context) {
+	h.previewImport(c, "departments")
+}
+
+// PreviewTeamImport previews a team import file
+// POST /api/v1/teams/import/preview
+func (h *ImportHandler) PreviewTeamImport(c *gin.Context) {
+	h.previewImport(c, "teams")
+}
+
+// PreviewRoleImport previews a role import file
+// POST /api/v1/roles/import/preview
+func (h *ImportHandler) PreviewRoleImport(c *gin.Context) {
+	h.previewImport(c, "roles")
+}
+
+// previewImport shows the detected headers, suggested mapping, and the first N mapped
+// rows with validation errors, without importing anything
+func (h *ImportHandler) previewImport(c *gin.Context, entity string) {
+	tenantID := c.GetString("tenant_id")
+
+	template, _ := importTemplateFor(entity)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FILE_REQUIRED", Message: "Please upload a CSV or Excel file"},
+		})
+		return
+	}
+	defer file.Close()
+
+	var format models.ImportFormat
+	if strings.HasSuffix(strings.ToLower(header.Filename), ".csv") {
+		format = models.ImportFormatCSV
+	} else if strings.HasSuffix(strings.ToLower(header.Filename), ".xlsx") {
+		format = models.ImportFormatXLSX
+	} else {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_FORMAT", Message: "Only CSV and XLSX files are supported"},
+		})
+		return
+	}
+
+	previewRows := models.DefaultImportPreviewRows
+	if n, err := strconv.Atoi(c.DefaultPostForm("rows", "")); err == nil && n > 0 {
+		previewRows = n
+		if previewRows > models.MaxImportPreviewRows {
+			previewRows = models.MaxImportPreviewRows
+		}
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "PARSE_ERROR", Message: "Failed to read uploaded file"},
+		})
+		return
+	}
+
+	headers, err := readImportHeaders(format, data)
+	var rows []map[string]string
+	if err == nil {
+		if format == models.ImportFormatCSV {
+			rows, err = h.parseCSV(bytes.NewReader(data))
+		} else {
+			rows, err = h.parseXLSX(bytes.NewReader(data))
+		}
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "PARSE_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	totalRows := len(rows)
+	if len(rows) > previewRows {
+		rows = rows[:previewRows]
+	}
+
+	suggested := suggestColumnMapping(headers, template.Columns)
+	mapping, err := h.resolveColumnMapping(c, tenantID, entity)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_MAPPING", Message: err.Error()},
+		})
+		return
+	}
+	if mapping == nil {
+		mapping = normalizeColumnMapping(suggested)
+	}
+	rows = applyColumnMapping(rows, mapping)
+
+	h.writeImportPreview(c, entity, template, headers, suggested, mapping, totalRows, rows)
+}
+
+// writeImportPreview validates the mapped preview rows and writes the preview response
+func (h *ImportHandler) writeImportPreview(c *gin.Context, entity string, template models.ImportTemplate, headers []string, suggested, mapping map[string]string, totalRows int, rows []map[string]string) {
+	known := make(map[string]bool, len(template.Columns))
+	for _, col := range template.Columns {
+		known[strings.ToLower(col.Name)] = true
+	}
+
+	result := &models.ImportPreviewResult{
+		Entity:           entity,
+		SourceHeaders:    headers,
+		SuggestedMapping: suggested,
+		AppliedMapping:   mapping,
+		TotalRows:        totalRows,
+		Rows:             rows,
+		Errors:           make([]models.ImportRowError, 0),
+	}
+
+	mappedColumns := make(map[string]bool)
+	for _, header := range headers {
+		target := header
+		if t, ok := mapping[header]; ok {
+			target = t
+		}
+		if known[target] {
+			mappedColumns[target] = true
+		} else {
+			result.UnmappedHeaders = append(result.UnmappedHeaders, header)
+		}
+	}
+	for _, col := range template.Columns {
+		if col.Required && !mappedColumns[strings.ToLower(col.Name)] {
+			result.MissingRequired = append(result.MissingRequired, col.Name)
+		}
+	}
+
+	for _, row := range rows {
+		rowNum, _ := strconv.Atoi(row["_row"])
+		rowErrors := validateImportRow(row, rowNum, template.Columns)
+		if len(rowErrors) == 0 {
+			result.ValidRowCount++
+		}
+		result.Errors = append(result.Errors, rowErrors...)
+	}
+	result.Success = len(result.MissingRequired) == 0
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListStaffMappingPresets lists saved staff column mappings
+// GET /api/v1/staff/import/mappings
+func (h *ImportHandler) ListStaffMappingPresets(c *gin.Context) {
+	h.listMappingPresets(c, "staff")
+}
+
+// ListDepartmentMappingPresets lists saved department column mappings
+// GET /api/v1/departments/import/mappings
+func (h *ImportHandler) ListDepartmentMappingPresets(c *gin.Context) {
+	h.listMappingPresets(c, "departments")
+}
+
+// ListTeamMappingPresets lists saved team column mappings
+// GET /api/v1/teams/import/mappings
+func (h *ImportHandler) ListTeamMappingPresets(c *gin.Context) {
+	h.listMappingPresets(c, "teams")
+}
+
+// ListRoleMappingPresets lists saved role column mappings
+// GET /api/v1/roles/import/mappings
+func (h *ImportHandler) ListRoleMappingPresets(c *gin.Context) {
+	h.listMappingPresets(c, "roles")
+}
+
+// listMappingPresets lists the tenant's saved column mappings for an entity
+func (h *ImportHandler) listMappingPresets(c *gin.Context, entity string) {
+	tenantID := c.GetString("tenant_id")
+
+	presets, err := h.mappingRepo.List(tenantID, entity)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve mapping presets"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    presets,
+	})
+}
+
+// SaveStaffMappingPreset creates or replaces a named staff column mapping
+// POST /api/v1/staff/import/mappings
+func (h *ImportHandler) SaveStaffMappingPreset(c *gin.Context) {
+	h.saveMappingPreset(c, "staff")
+}
+
+// SaveDepartmentMappingPreset creates or replaces a named department column mapping
+// POST /api/v1/departments/import/mappings
+func (h *ImportHandler) SaveDepartmentMappingPreset(c *gin.Context) {
+	h.saveMappingPreset(c, "departments")
+}
+
+// SaveTeamMappingPreset creates or replaces a named team column mapping
+// POST /api/v1/teams/import/mappings
+func (h *ImportHandler) SaveTeamMappingPreset(c *gin.Context) {
+	h.saveMappingPreset(c, "teams")
+}
+
+// SaveRoleMappingPreset creates or replaces a named role column mapping
+// POST /api/v1/roles/import/mappings
+func (h *ImportHandler) SaveRoleMappingPreset(c *gin.Context) {
+	h.saveMappingPreset(c, "roles")
+}
+
+// saveMappingPreset creates or replaces a named column mapping for an entity
+func (h *ImportHandler) saveMappingPreset(c *gin.Context, entity string) {
+	tenantID := c.GetString("tenant_id")
+	userID := c.GetString("user_id")
+
+	var req models.SaveImportMappingPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	template, _ := importTemplateFor(entity)
+
+	known := make(map[string]bool, len(template.Columns))
+	for _, col := range template.Columns {
+		known[strings.ToLower(col.Name)] = true
+	}
+	mapping := normalizeColumnMapping(req.Mapping)
+	stored := make(models.JSON, len(mapping))
+	for source, target := range mapping {
+		if !known[target] {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error:   models.Error{Code: "INVALID_MAPPING", Message: fmt.Sprintf("'%s' is not a %s import column", target, entity)},
+			})
+			return
+		}
+		stored[source] = target
+	}
+
+	preset := &models.ImportMappingPreset{
+		TenantID: tenantID,
+		Entity:   entity,
+		Name:     strings.TrimSpace(req.Name),
+		Mapping:  stored,
+	}
+	if userID != "" {
+		preset.CreatedBy = &userID
+	}
+
+	if err := h.mappingRepo.Save(preset); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "SAVE_FAILED", Message: "Failed to save mapping preset"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    preset,
+	})
+}
+
+// DeleteStaffMappingPreset deletes a saved staff column mapping
+// DELETE /api/v1/staff/import/mappings/:presetId
+func (h *ImportHandler) DeleteStaffMappingPreset(c *gin.Context) {
+	h.deleteMappingPreset(c, "staff")
+}
+
+// DeleteDepartmentMappingPreset deletes a saved department column mapping
+// DELETE /api/v1/departments/import/mappings/:presetId
+func (h *ImportHandler) DeleteDepartmentMappingPreset(c *gin.Context) {
+	h.deleteMappingPreset(c, "departments")
+}
+
+// DeleteTeamMappingPreset deletes a saved team column mapping
+// DELETE /api/v1/teams/import/mappings/:presetId
+func (h *ImportHandler) DeleteTeamMappingPreset(c *gin.Context) {
+	h.deleteMappingPreset(c, "teams")
+}
+
+// DeleteRoleMappingPreset deletes a saved role column mapping
+// DELETE /api/v1/roles/import/mappings/:presetId
+func (h *ImportHandler) DeleteRoleMappingPreset(c *gin.Context) {
+	h.deleteMappingPreset(c, "roles")
+}
+
+// deleteMappingPreset deletes a saved column mapping for an entity
+func (h *ImportHandler) deleteMappingPreset(c *gin.Context, entity string) {
+	tenantID := c.GetString("tenant_id")
+
+	id, err := uuid.Parse(c.Param("presetId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_ID", Message: "Invalid preset ID format"},
+		})
+		return
+	}
+
+	deleted, err := h.mappingRepo.Delete(tenantID, entity, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "DELETE_FAILED", Message: "Failed to delete mapping preset"},
+		})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "NOT_FOUND", Message: "Mapping preset not found"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
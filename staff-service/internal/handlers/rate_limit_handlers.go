@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"staff-service/internal/cache"
+	"staff-service/internal/models"
+)
+
+// RateLimitHandler manages the rate limit profiles, endpoint groups and tenant overrides used
+// by every rate-limited service. They are platform-wide, so only platform owners manage them.
+// Settings live in Redis; services reload them within seconds of a change.
+type RateLimitHandler struct {
+	store *cache.RateLimitStore
+}
+
+// NewRateLimitHandler creates a new rate limit handler
+func NewRateLimitHandler(store *cache.RateLimitStore) *RateLimitHandler {
+	return &RateLimitHandler{store: store}
+}
+
+// GetSettings returns the rate limit profiles and endpoint groups, or the defaults if none were saved
+// GET /api/v1/rate-limits/settings
+func (h *RateLimitHandler) GetSettings(c *gin.Context) {
+	settings, err := h.store.GetSettings(c.Request.Context())
+	if err != nil {
+		h.storeError(c, err, "Failed to retrieve rate limit settings")
+		return
+	}
+	if settings == nil {
+		settings = models.DefaultRateLimitSettings()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// UpdateSettings replaces the rate limit profiles and endpoint groups
+// PUT /api/v1/rate-limits/settings
+func (h *RateLimitHandler) UpdateSettings(c *gin.Context) {
+	var settings models.RateLimitSettings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+	if err := validateRateLimitSettings(&settings); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	now := time.Now()
+	settings.UpdatedAt = &now
+	settings.UpdatedBy = c.GetString("user_id")
+	if err := h.store.SaveSettings(c.Request.Context(), &settings); err != nil {
+		h.storeError(c, err, "Failed to save rate limit settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// ListTenantOverrides returns every tenant's override, ordered by tenant
+// GET /api/v1/rate-limits/tenants
+func (h *RateLimitHandler) ListTenantOverrides(c *gin.Context) {
+	overrides, err := h.store.ListTenantOverrides(c.Request.Context())
+	if err != nil {
+		h.storeError(c, err, "Failed to retrieve tenant rate limits")
+		return
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].TenantID < overrides[j].TenantID })
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    overrides,
+	})
+}
+
+// GetTenantOverride returns a tenant's override
+// GET /api/v1/rate-limits/tenants/:tenantId
+func (h *RateLimitHandler) GetTenantOverride(c *gin.Context) {
+	override, err := h.store.GetTenantOverride(c.Request.Context(), c.Param("tenantId"))
+	if err != nil {
+		h.storeError(c, err, "Failed to retrieve tenant rate limits")
+		return
+	}
+	if override == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "NOT_FOUND", Message: "Tenant has no rate limit override"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    override,
+	})
+}
+
+// SaveTenantOverride creates or replaces a tenant's override
+// PUT /api/v1/rate-limits/tenants/:tenantId
+func (h *RateLimitHandler) SaveTenantOverride(c *gin.Context) {
+	var override models.TenantRateLimitOverride
+	if err := c.ShouldBindJSON(&override); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+	override.TenantID = c.Param("tenantId")
+	if err := validateTenantRateLimitOverride(&override); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	now := time.Now()
+	override.UpdatedAt = &now
+	override.UpdatedBy = c.GetString("user_id")
+	if err := h.store.SaveTenantOverride(c.Request.Context(), &override); err != nil {
+		h.storeError(c, err, "Failed to save tenant rate limits")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    override,
+	})
+}
+
+// DeleteTenantOverride removes a tenant's override so the shared profiles apply
+// DELETE /api/v1/rate-limits/tenants/:tenantId
+func (h *RateLimitHandler) DeleteTenantOverride(c *gin.Context) {
+	deleted, err := h.store.DeleteTenantOverride(c.Request.Context(), c.Param("tenantId"))
+	if err != nil {
+		h.storeError(c, err, "Failed to delete tenant rate limits")
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "NOT_FOUND", Message: "Tenant has no rate limit override"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tenant rate limit override removed",
+	})
+}
+
+func (h *RateLimitHandler) storeError(c *gin.Context, err error, message string) {
+	if errors.Is(err, cache.ErrRateLimitStoreUnavailable) {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "SERVICE_UNAVAILABLE", Message: "Rate limit settings require Redis, which is not connected"},
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Success: false,
+		Error:   models.Error{Code: "INTERNAL_ERROR", Message: message},
+	})
+}
+
+func validateRateLimitProfiles(profiles map[string]models.RateLimitProfile) error {
+	for name, profile := range profiles {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("profile names must not be empty")
+		}
+		if profile.RequestsPerWindow <= 0 {
+			return fmt.Errorf("profile %s: requestsPerWindow must be positive", name)
+		}
+		if profile.WindowSeconds <= 0 || profile.WindowSeconds > models.MaxRateLimitWindowSeconds {
+			return fmt.Errorf("profile %s: windowSeconds must be between 1 and %d", name, models.MaxRateLimitWindowSeconds)
+		}
+	}
+	return nil
+}
+
+func validateRateLimitSettings(settings *models.RateLimitSettings) error {
+	if err := validateRateLimitProfiles(settings.Profiles); err != nil {
+		return err
+	}
+	if _, ok := settings.Profiles[settings.DefaultProfile]; !ok {
+		return fmt.Errorf("defaultProfile %q is not a defined profile", settings.DefaultProfile)
+	}
+
+	names := make(map[string]bool, len(settings.EndpointGroups))
+	for i := range settings.EndpointGroups {
+		group := &settings.EndpointGroups[i]
+		if group.Name == "" || names[group.Name] {
+			return fmt.Errorf("endpoint group names must be set and unique")
+		}
+		names[group.Name] = true
+		if len(group.PathPrefixes) == 0 && len(group.PathContains) == 0 {
+			return fmt.Errorf("endpoint group %s needs pathPrefixes or pathContains", group.Name)
+		}
+		if _, ok := settings.Profiles[group.Profile]; !ok {
+			return fmt.Errorf("endpoint group %s uses undefined profile %q", group.Name, group.Profile)
+		}
+		for j, method := range group.Methods {
+			group.Methods[j] = strings.ToUpper(method)
+		}
+	}
+	return nil
+}
+
+func validateTenantRateLimitOverride(override *models.TenantRateLimitOverride) error {
+	if override.Multiplier < 0 || override.Multiplier > models.MaxRateLimitMultiplier {
+		return fmt.Errorf("multiplier must be between 0 and %d", models.MaxRateLimitMultiplier)
+	}
+	if override.Multiplier == 0 && len(override.Profiles) == 0 {
+		return fmt.Errorf("set a multiplier or profiles")
+	}
+	return validateRateLimitProfiles(override.Profiles)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"staff-service/internal/cache"
+	"staff-service/internal/events"
 	"staff-service/internal/models"
 	"staff-service/internal/repository"
 )
@@ -34,9 +35,11 @@ var keycloakRoleMapping = map[string]string{
 }
 
 type RBACHandler struct {
-	repo      repository.RBACRepository
-	staffRepo repository.StaffRepository
-	permCache *cache.PermissionCache
+	repo       repository.RBACRepository
+	staffRepo  repository.StaffRepository
+	permCache  *cache.PermissionCache
+	apiKeyRepo repository.APIKeyRepository
+	notifier   *events.PermissionsNotifier
 }
 
 func NewRBACHandler(repo repository.RBACRepository, staffRepo repository.StaffRepository) *RBACHandler {
@@ -48,9 +51,22 @@ func NewRBACHandlerWithCache(repo repository.RBACRepository, staffRepo repositor
 	return &RBACHandler{repo: repo, staffRepo: staffRepo, permCache: permCache}
 }
 
+// SetAPIKeyRepository lets the internal effective-permissions endpoint resolve API key IDs,
+// which services accepting API keys pass in place of a staff ID
+func (h *RBACHandler) SetAPIKeyRepository(repo repository.APIKeyRepository) {
+	h.apiKeyRepo = repo
+}
+
+// SetPermissionsNotifier publishes rbac.permissions_changed whenever cached permissions are
+// invalidated, so other services' RBAC caches are dropped too
+func (h *RBACHandler) SetPermissionsNotifier(notifier *events.PermissionsNotifier) {
+	h.notifier = notifier
+}
+
 // PERF-001: Helper to invalidate cache for a staff member after permission changes
 // FIX: Made synchronous with timeout to prevent race conditions where stale permissions could be used
 func (h *RBACHandler) invalidateStaffCache(tenantID string, vendorID *string, staffID uuid.UUID) {
+	h.notifier.StaffChanged(tenantID, staffID)
 	if h.permCache != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 		defer cancel()
@@ -63,6 +79,7 @@ func (h *RBACHandler) invalidateStaffCache(tenantID string, vendorID *string, st
 // PERF-001: Helper to invalidate all cached permissions for a tenant (used on role/permission changes)
 // FIX: Made synchronous with timeout to prevent race conditions where stale permissions could be used
 func (h *RBACHandler) invalidateTenantCache(tenantID string) {
+	h.notifier.TenantChanged(tenantID)
 	if h.permCache != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
 		defer cancel()
@@ -1757,6 +1774,15 @@ func (h *RBACHandler) GetStaffEffectivePermissions(c *gin.Context) {
 					staffID = staffByEmail.ID
 					log.Printf("[GetStaffEffectivePermissions] Found staff by email %s: %s (requested ID: %s)", userEmail, staffID, staffIDStr)
 				}
+			} else if h.apiKeyRepo != nil {
+				// Requests authenticated with a tenant API key carry the key ID
+				if keyPermissions, err := h.apiKeyRepo.GetEffectivePermissions(tenantID, staffID); err == nil {
+					c.JSON(http.StatusOK, models.EffectivePermissionsResponse{
+						Success: true,
+						Data:    keyPermissions,
+					})
+					return
+				}
 			}
 		}
 	}
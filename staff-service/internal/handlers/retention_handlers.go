@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"staff-service/internal/models"
+	"staff-service/internal/repository"
+)
+
+// RetentionHandler manages per-tenant data retention policies and the purge audit trail.
+// Policies are stored here centrally; purge workers in the owning services fetch them
+// through the internal routes and report a summary of every purge back.
+type RetentionHandler struct {
+	repo repository.RetentionRepository
+}
+
+// NewRetentionHandler creates a new retention handler
+func NewRetentionHandler(repo repository.RetentionRepository) *RetentionHandler {
+	return &RetentionHandler{repo: repo}
+}
+
+// ListPolicies returns the effective retention policy for every resource
+// GET /api/v1/retention/policies
+func (h *RetentionHandler) ListPolicies(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	overrides, err := h.repo.ListPolicies(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve retention policies"},
+		})
+		return
+	}
+
+	byResource := make(map[string]models.RetentionPolicy, len(overrides))
+	for _, p := range overrides {
+		byResource[p.Resource] = p
+	}
+
+	resources := models.RetentionResources()
+	policies := make([]models.EffectiveRetentionPolicy, 0, len(resources))
+	for _, resource := range resources {
+		effective := models.EffectiveRetentionPolicy{
+			RetentionResource: resource,
+			RetentionDays:     resource.DefaultDays,
+			IsDefault:         true,
+		}
+		if p, ok := byResource[resource.Resource]; ok {
+			updatedAt := p.UpdatedAt
+			effective.RetentionDays = p.RetentionDays
+			effective.DryRun = p.DryRun
+			effective.IsDefault = false
+			effective.UpdatedBy = p.UpdatedBy
+			effective.UpdatedAt = &updatedAt
+		}
+		policies = append(policies, effective)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    policies,
+	})
+}
+
+// UpdatePolicy sets the tenant's retention and dry-run mode for a resource
+// PUT /api/v1/retention/policies/:resource
+func (h *RetentionHandler) UpdatePolicy(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	userID := c.GetString("user_id")
+
+	resource, ok := models.RetentionResourceFor(c.Param("resource"))
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "NOT_FOUND", Message: "Unknown retention resource"},
+		})
+		return
+	}
+
+	var req models.UpdateRetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+	if req.RetentionDays < resource.MinDays || req.RetentionDays > resource.MaxDays {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "VALIDATION_ERROR",
+				Message: fmt.Sprintf("retentionDays for %s must be between %d and %d", resource.Resource, resource.MinDays, resource.MaxDays),
+			},
+		})
+		return
+	}
+
+	policy := &models.RetentionPolicy{
+		TenantID:      tenantID,
+		Resource:      resource.Resource,
+		RetentionDays: req.RetentionDays,
+		DryRun:        req.DryRun,
+	}
+	if userID != "" {
+		policy.UpdatedBy = &userID
+	}
+
+	if err := h.repo.SavePolicy(policy); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "SAVE_FAILED", Message: "Failed to save retention policy"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    policy,
+	})
+}
+
+// ResetPolicy removes the tenant's override so the resource default applies
+// DELETE /api/v1/retention/policies/:resource
+func (h *RetentionHandler) ResetPolicy(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	resource, ok := models.RetentionResourceFor(c.Param("resource"))
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "NOT_FOUND", Message: "Unknown retention resource"},
+		})
+		return
+	}
+
+	if _, err := h.repo.DeletePolicy(tenantID, resource.Resource); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "DELETE_FAILED", Message: "Failed to reset retention policy"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Retention policy reset to default",
+	})
+}
+
+// ListPurgeRuns returns the deletion audit summaries for the tenant
+// GET /api/v1/retention/runs?resource=&page=&limit=
+func (h *RetentionHandler) ListPurgeRuns(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	page := getIntParam(c, "page", 1)
+	limit := getIntParam(c, "limit", 20)
+
+	runs, pagination, err := h.repo.ListPurgeRuns(tenantID, c.Query("resource"), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve purge runs"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       runs,
+		"pagination": pagination,
+	})
+}
+
+// GetPolicySetInternal returns the default and every tenant override for a resource
+// GET /api/v1/internal/retention/policies/:resource - called by purge workers in other services
+func (h *RetentionHandler) GetPolicySetInternal(c *gin.Context) {
+	resource, ok := models.RetentionResourceFor(c.Param("resource"))
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "NOT_FOUND", Message: "Unknown retention resource"},
+		})
+		return
+	}
+
+	policies, err := h.repo.ListPoliciesForResource(resource.Resource)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve retention policies"},
+		})
+		return
+	}
+	if policies == nil {
+		policies = []models.RetentionPolicy{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": models.RetentionPolicySet{
+			Resource:    resource.Resource,
+			DefaultDays: resource.DefaultDays,
+			Policies:    policies,
+		},
+	})
+}
+
+// RecordPurgeRunInternal stores a purge summary reported by another service's purge worker
+// POST /api/v1/internal/retention/runs
+func (h *RetentionHandler) RecordPurgeRunInternal(c *gin.Context) {
+	var run models.RetentionPurgeRun
+	if err := c.ShouldBindJSON(&run); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	resource, ok := models.RetentionResourceFor(run.Resource)
+	if !ok || run.TenantID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: "tenantId and a known resource are required"},
+		})
+		return
+	}
+	if run.Status != models.RetentionRunCompleted && run.Status != models.RetentionRunFailed {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: "status must be completed or failed"},
+		})
+		return
+	}
+
+	run.ID = uuid.Nil // Always assigned by the database
+	run.Service = resource.Service
+	if run.CompletedAt.IsZero() {
+		run.CompletedAt = time.Now().UTC()
+	}
+	if run.StartedAt.IsZero() {
+		run.StartedAt = run.CompletedAt
+	}
+
+	if err := h.repo.RecordPurgeRun(&run); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "SAVE_FAILED", Message: "Failed to record purge run"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    run,
+	})
+}
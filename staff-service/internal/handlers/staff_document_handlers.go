@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 
@@ -312,6 +313,14 @@ func (h *StaffDocumentHandler) VerifyStaffDocument(c *gin.Context) {
 	}
 
 	doc, _ := h.repo.GetDocumentByID(tenantID, vendorID, docID)
+
+	// Verifying a replacement for a lapsed mandatory document restores compliance
+	if doc != nil && req.Status == models.VerificationVerified {
+		if _, _, err := h.repo.RefreshStaffCompliance(tenantID, doc.StaffID); err != nil {
+			log.Printf("[STAFF] Failed to refresh compliance state for staff %s: %v", doc.StaffID, err)
+		}
+	}
+
 	c.JSON(http.StatusOK, models.StaffDocumentResponse{
 		Success: true,
 		Data:    doc,
@@ -400,6 +409,31 @@ func (h *StaffDocumentHandler) GetStaffComplianceStatus(c *gin.Context) {
 	})
 }
 
+// GetComplianceDashboard returns document counts by type and status and staff counts by
+// compliance state for the tenant
+func (h *StaffDocumentHandler) GetComplianceDashboard(c *gin.Context) {
+	tenantID, vendorID := h.getTenantAndVendor(c)
+
+	daysAhead, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if daysAhead < 1 {
+		daysAhead = 30
+	}
+
+	dashboard, err := h.repo.GetComplianceDashboard(tenantID, vendorID, daysAhead)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "GET_FAILED", Message: "Failed to get compliance dashboard"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ComplianceDashboardResponse{
+		Success: true,
+		Data:    dashboard,
+	})
+}
+
 // GetDocumentTypes returns all available document types
 func (h *StaffDocumentHandler) GetDocumentTypes(c *gin.Context) {
 	types := models.GetAllDocumentTypes()
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"staff-service/internal/bulk"
 	"staff-service/internal/clients"
 	"staff-service/internal/models"
 	"staff-service/internal/repository"
@@ -474,6 +475,10 @@ func (h *StaffHandler) GetStaffList(c *gin.Context) {
 		}
 	}
 
+	// Comma-separated; staff must hold all of them (expired entries don't count)
+	filters.Skills = splitQueryList(c.Query("skills"))
+	filters.Certifications = splitQueryList(c.Query("certifications"))
+
 	staff, pagination, err := h.repo.List(tenantID, filters, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -673,9 +678,11 @@ func (h *StaffHandler) DeleteStaff(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param staff body []models.CreateStaffRequest true "Array of staff data"
-// @Success 201 {object} models.StaffListResponse
+// @Param atomic query bool false "Create all or none"
+// @Success 200 {object} bulk.Response
+// @Success 207 {object} bulk.Response
 // @Failure 400 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 422 {object} bulk.Response
 // @Security BearerAuth
 // @Router /staff/bulk [post]
 func (h *StaffHandler) BulkCreateStaff(c *gin.Context) {
@@ -704,12 +711,12 @@ func (h *StaffHandler) BulkCreateStaff(c *gin.Context) {
 		return
 	}
 
-	if len(requests) > 100 {
+	if len(requests) > bulk.MaxItems {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
 				Code:    "TOO_MANY_ITEMS",
-				Message: "Maximum 100 staff members can be created at once",
+				Message: fmt.Sprintf("Maximum %d staff members can be created at once", bulk.MaxItems),
 			},
 		})
 		return
@@ -789,28 +796,8 @@ func (h *StaffHandler) BulkCreateStaff(c *gin.Context) {
 	}
 
 	// Bulk create with auto-generated employee IDs
-	result, err := h.repo.BulkCreateWithEmployeeIDs(tenantID, vendorID, businessCode, staffList, false)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Success: false,
-			Error: models.Error{
-				Code:    "BULK_CREATE_FAILED",
-				Message: "Failed to create staff members",
-			},
-		})
-		return
-	}
-
-	// Convert created staff to slice for response
-	createdStaff := make([]models.Staff, len(result.Created))
-	for i, s := range result.Created {
-		createdStaff[i] = *s
-	}
-
-	c.JSON(http.StatusCreated, models.StaffListResponse{
-		Success: true,
-		Data:    createdStaff,
-	})
+	result := h.repo.BulkCreateWithEmployeeIDs(tenantID, vendorID, businessCode, staffList, false, bulk.ParseOptions(c))
+	c.JSON(result.HTTPStatus(), result)
 }
 
 // BulkUpdateStaff updates multiple staff members
@@ -819,16 +806,18 @@ func (h *StaffHandler) BulkCreateStaff(c *gin.Context) {
 // @Tags staff
 // @Accept json
 // @Produce json
-// @Param updates body []models.UpdateStaffRequest true "Array of staff updates"
-// @Success 200 {object} gin.H
+// @Param updates body []models.BulkUpdateStaffItem true "Array of staff updates, each with the staff ID"
+// @Param atomic query bool false "Update all or none"
+// @Success 200 {object} bulk.Response
+// @Success 207 {object} bulk.Response
 // @Failure 400 {object} models.ErrorResponse
-// @Failure 500 {object} models.ErrorResponse
+// @Failure 422 {object} bulk.Response
 // @Security BearerAuth
 // @Router /staff/bulk [put]
 func (h *StaffHandler) BulkUpdateStaff(c *gin.Context) {
 	tenantID := c.GetString("tenant_id")
 
-	var updates []models.UpdateStaffRequest
+	var updates []models.BulkUpdateStaffItem
 	if err := c.ShouldBindJSON(&updates); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
@@ -840,21 +829,39 @@ func (h *StaffHandler) BulkUpdateStaff(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.BulkUpdate(tenantID, updates); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+	if len(updates) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Success: false,
 			Error: models.Error{
-				Code:    "BULK_UPDATE_FAILED",
-				Message: "Failed to update staff members",
+				Code:    "EMPTY_REQUEST",
+				Message: "No staff updates provided",
 			},
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Staff members updated successfully",
-	})
+	if len(updates) > bulk.MaxItems {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "TOO_MANY_ITEMS",
+				Message: fmt.Sprintf("Maximum %d staff members can be updated at once", bulk.MaxItems),
+			},
+		})
+		return
+	}
+
+	// STAFF-002 FIX: Convert TeamID to TeamUUID for proper FK relationship
+	for i := range updates {
+		if teamID := updates[i].TeamID; teamID != nil && *teamID != "" {
+			if teamUUID, err := uuid.Parse(*teamID); err == nil {
+				updates[i].TeamUUID = &teamUUID
+			}
+		}
+	}
+
+	result := h.repo.BulkUpdate(tenantID, updates, bulk.ParseOptions(c))
+	c.JSON(result.HTTPStatus(), result)
 }
 
 // ExportStaff exports staff data
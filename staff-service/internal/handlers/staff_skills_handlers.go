@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"staff-service/internal/models"
+)
+
+const (
+	defaultStaffMatchLimit = 10
+	maxStaffMatchLimit     = 50
+)
+
+// GetSkillCatalog returns the skills and certifications held by the tenant's active staff,
+// for pickers and assignment rules
+// GET /api/v1/staff/skills
+func (h *StaffHandler) GetSkillCatalog(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	catalog, err := h.repo.GetSkillCatalog(tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve skill catalog"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    catalog,
+	})
+}
+
+// UpdateStaffSkills replaces a staff member's skills and certifications
+// PUT /api/v1/staff/:id/skills
+func (h *StaffHandler) UpdateStaffSkills(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "INVALID_ID", Message: "Invalid staff ID format"},
+		})
+		return
+	}
+
+	var req models.UpdateStaffSkillsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+	if err := validateStaffSkills(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+
+	if _, err := h.repo.GetByID(tenantID, id); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "STAFF_NOT_FOUND", Message: "Staff member not found"},
+		})
+		return
+	}
+
+	skills, err := toJSONArray(req.Skills)
+	if err == nil {
+		var certifications *models.JSONArray
+		if certifications, err = toJSONArray(req.Certifications); err == nil {
+			err = h.repo.Update(tenantID, id, &models.UpdateStaffRequest{
+				Skills:         skills,
+				Certifications: certifications,
+			})
+		}
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "UPDATE_FAILED", Message: "Failed to update skills"},
+		})
+		return
+	}
+
+	staff, err := h.repo.GetByID(tenantID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "FETCH_FAILED", Message: "Failed to retrieve updated staff member"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.StaffResponse{
+		Success: true,
+		Data:    staff,
+	})
+}
+
+// MatchStaffInternal finds qualified assignees: active staff holding all the required
+// skills (at the minimum level) and unexpired certifications, best match first.
+// Called by tickets-service to route tickets.
+// POST /api/v1/internal/staff/match
+func (h *StaffHandler) MatchStaffInternal(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "MISSING_TENANT", Message: "tenant_id is required"},
+		})
+		return
+	}
+
+	var req models.StaffMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: err.Error()},
+		})
+		return
+	}
+	if len(req.Skills) == 0 && len(req.Certifications) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "VALIDATION_ERROR", Message: "At least one skill or certification is required"},
+		})
+		return
+	}
+	for _, s := range req.Skills {
+		if s.MinLevel != "" && models.SkillLevelRank(s.MinLevel) == 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "VALIDATION_ERROR",
+					Message: fmt.Sprintf("minLevel must be one of %s", strings.Join(models.SkillLevels, ", ")),
+				},
+			})
+			return
+		}
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultStaffMatchLimit
+	}
+	if req.Limit > maxStaffMatchLimit {
+		req.Limit = maxStaffMatchLimit
+	}
+
+	skillNames := make([]string, len(req.Skills))
+	for i, s := range req.Skills {
+		skillNames[i] = s.Name
+	}
+
+	candidates, err := h.repo.FindBySkills(tenantID, skillNames, req.Certifications, req.DepartmentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error:   models.Error{Code: "MATCH_FAILED", Message: "Failed to match staff"},
+		})
+		return
+	}
+
+	excluded := make(map[uuid.UUID]bool, len(req.ExcludeStaffIDs))
+	for _, id := range req.ExcludeStaffIDs {
+		excluded[id] = true
+	}
+
+	matches := make([]models.StaffMatch, 0, len(candidates))
+	for i := range candidates {
+		if excluded[candidates[i].ID] {
+			continue
+		}
+		if match, ok := matchStaff(&candidates[i], &req); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > req.Limit {
+		matches = matches[:req.Limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    matches,
+	})
+}
+
+// matchStaff checks the skill levels the database can't and collects the matched entries
+func matchStaff(staff *models.Staff, req *models.StaffMatchRequest) (models.StaffMatch, bool) {
+	match := models.StaffMatch{
+		StaffID:               staff.ID,
+		FirstName:             staff.FirstName,
+		LastName:              staff.LastName,
+		Email:                 staff.Email,
+		KeycloakUserID:        staff.KeycloakUserID,
+		DepartmentID:          staff.DepartmentID,
+		TeamID:                staff.TeamID,
+		MatchedSkills:         []models.Skill{},
+		MatchedCertifications: []models.Certification{},
+	}
+
+	now := time.Now()
+	var skills []models.Skill
+	var certifications []models.Certification
+	if fromJSONArray(staff.Skills, &skills) != nil || fromJSONArray(staff.Certifications, &certifications) != nil {
+		return match, false
+	}
+
+	for _, required := range req.Skills {
+		found := false
+		for _, skill := range skills {
+			if !strings.EqualFold(skill.Name, required.Name) || (skill.ExpiresAt != nil && !skill.ExpiresAt.After(now)) {
+				continue
+			}
+			rank := models.SkillLevelRank(skill.Level)
+			if required.MinLevel != "" && rank < models.SkillLevelRank(required.MinLevel) {
+				continue
+			}
+			match.MatchedSkills = append(match.MatchedSkills, skill)
+			match.Score += rank
+			found = true
+			break
+		}
+		if !found {
+			return match, false
+		}
+	}
+
+	for _, name := range req.Certifications {
+		for _, cert := range certifications {
+			if strings.EqualFold(cert.Name, name) && (cert.ExpiresAt == nil || cert.ExpiresAt.After(now)) {
+				match.MatchedCertifications = append(match.MatchedCertifications, cert)
+				break
+			}
+		}
+	}
+	return match, true
+}
+
+// validateStaffSkills checks skill levels, certification dates and duplicate names
+func validateStaffSkills(req *models.UpdateStaffSkillsRequest) error {
+	seen := make(map[string]bool)
+	for i := range req.Skills {
+		skill := &req.Skills[i]
+		skill.Name = strings.TrimSpace(skill.Name)
+		if skill.Name == "" {
+			return fmt.Errorf("skills[%d].name is required", i)
+		}
+		if models.SkillLevelRank(skill.Level) == 0 {
+			return fmt.Errorf("skills[%d].level must be one of %s", i, strings.Join(models.SkillLevels, ", "))
+		}
+		skill.Level = strings.ToLower(skill.Level)
+		if seen[strings.ToLower(skill.Name)] {
+			return fmt.Errorf("duplicate skill %q", skill.Name)
+		}
+		seen[strings.ToLower(skill.Name)] = true
+	}
+
+	seen = make(map[string]bool)
+	for i := range req.Certifications {
+		cert := &req.Certifications[i]
+		cert.Name = strings.TrimSpace(cert.Name)
+		if cert.Name == "" {
+			return fmt.Errorf("certifications[%d].name is required", i)
+		}
+		if cert.IssuedAt.IsZero() {
+			return fmt.Errorf("certifications[%d].issuedAt is required", i)
+		}
+		if cert.ExpiresAt != nil && !cert.ExpiresAt.After(cert.IssuedAt) {
+			return fmt.Errorf("certifications[%d].expiresAt must be after issuedAt", i)
+		}
+		if seen[strings.ToLower(cert.Name)] {
+			return fmt.Errorf("duplicate certification %q", cert.Name)
+		}
+		seen[strings.ToLower(cert.Name)] = true
+	}
+	return nil
+}
+
+// toJSONArray converts typed entries to the JSONB array stored on the staff record
+func toJSONArray(v interface{}) (*models.JSONArray, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	arr := models.JSONArray{}
+	if string(data) != "null" {
+		if err := json.Unmarshal(data, &arr); err != nil {
+			return nil, err
+		}
+	}
+	return &arr, nil
+}
+
+// fromJSONArray decodes a JSONB array stored on the staff record into typed entries
+func fromJSONArray(arr *models.JSONArray, v interface{}) error {
+	if arr == nil {
+		return nil
+	}
+	data, err := json.Marshal(arr)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// splitQueryList splits a comma-separated query parameter, dropping empty values
+func splitQueryList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
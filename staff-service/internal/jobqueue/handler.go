@@ -0,0 +1,161 @@
+package jobqueue
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRecentRuns      = 20
+	maxListLimit           = 200
+	defaultDeadLetterLimit = 50
+)
+
+// Handler serves the background jobs admin API
+type Handler struct {
+	runner *Runner
+}
+
+// NewHandler creates a handler for runner
+func NewHandler(runner *Runner) *Handler {
+	return &Handler{runner: runner}
+}
+
+// RegisterRoutes mounts the admin API on group. Access control is left to the caller; the
+// routes should be limited to platform owners (PlatformOwnerPriority).
+func (h *Handler) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/jobs", h.ListJobs)
+	group.GET("/jobs/:name", h.GetJob)
+	group.POST("/jobs/:name/pause", h.PauseJob)
+	group.POST("/jobs/:name/resume", h.ResumeJob)
+	group.POST("/jobs/:name/trigger", h.TriggerJob)
+	group.GET("/job-dead-letters", h.ListDeadLetters)
+	group.POST("/job-dead-letters/:id/retry", h.RetryDeadLetter)
+	group.POST("/job-dead-letters/:id/dismiss", h.DismissDeadLetter)
+}
+
+// ListJobs handles GET /jobs
+func (h *Handler) ListJobs(c *gin.Context) {
+	jobs, err := h.runner.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to list jobs")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": jobs})
+}
+
+// GetJob handles GET /jobs/:name, with the last ?runs attempts (default 20)
+func (h *Handler) GetJob(c *gin.Context) {
+	job, err := h.runner.Get(c.Request.Context(), c.Param("name"), queryLimit(c, "runs", defaultRecentRuns))
+	if err != nil {
+		respondError(c, err, "Failed to get job")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": job})
+}
+
+// PauseJob handles POST /jobs/:name/pause
+func (h *Handler) PauseJob(c *gin.Context) {
+	job, err := h.runner.SetPaused(c.Request.Context(), c.Param("name"), true, gosharedmw.GetIstioUserID(c))
+	if err != nil {
+		respondError(c, err, "Failed to pause job")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": job})
+}
+
+// ResumeJob handles POST /jobs/:name/resume
+func (h *Handler) ResumeJob(c *gin.Context) {
+	job, err := h.runner.SetPaused(c.Request.Context(), c.Param("name"), false, gosharedmw.GetIstioUserID(c))
+	if err != nil {
+		respondError(c, err, "Failed to resume job")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": job})
+}
+
+// TriggerJob handles POST /jobs/:name/trigger. The run starts on the next poll of any replica.
+func (h *Handler) TriggerJob(c *gin.Context) {
+	job, err := h.runner.Trigger(c.Request.Context(), c.Param("name"), gosharedmw.GetIstioUserID(c))
+	if err != nil {
+		respondError(c, err, "Failed to trigger job")
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"success": true, "data": job})
+}
+
+// ListDeadLetters handles GET /job-dead-letters. ?includeResolved=true also lists retried and
+// dismissed ones.
+func (h *Handler) ListDeadLetters(c *gin.Context) {
+	includeResolved := c.Query("includeResolved") == "true"
+	runs, err := h.runner.DeadLetters(c.Request.Context(), includeResolved, queryLimit(c, "limit", defaultDeadLetterLimit))
+	if err != nil {
+		respondError(c, err, "Failed to list dead letters")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": runs})
+}
+
+// RetryDeadLetter handles POST /job-dead-letters/:id/retry
+func (h *Handler) RetryDeadLetter(c *gin.Context) {
+	id, ok := parseRunID(c)
+	if !ok {
+		return
+	}
+	run, err := h.runner.RetryDeadLetter(c.Request.Context(), id, gosharedmw.GetIstioUserID(c))
+	if err != nil {
+		respondError(c, err, "Failed to retry dead letter")
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"success": true, "data": run})
+}
+
+// DismissDeadLetter handles POST /job-dead-letters/:id/dismiss
+func (h *Handler) DismissDeadLetter(c *gin.Context) {
+	id, ok := parseRunID(c)
+	if !ok {
+		return
+	}
+	run, err := h.runner.DismissDeadLetter(c.Request.Context(), id, gosharedmw.GetIstioUserID(c))
+	if err != nil {
+		respondError(c, err, "Failed to dismiss dead letter")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": run})
+}
+
+func respondError(c *gin.Context, err error, failure string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrJobNotFound), errors.Is(err, ErrRunNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrNotDeadLetter):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"success": false, "error": failure, "message": err.Error()})
+}
+
+func parseRunID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid run ID", "message": err.Error()})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func queryLimit(c *gin.Context, name string, fallback int) int {
+	limit, err := strconv.Atoi(c.Query(name))
+	if err != nil || limit <= 0 {
+		return fallback
+	}
+	if limit > maxListLimit {
+		return maxListLimit
+	}
+	return limit
+}
@@ -593,8 +593,17 @@ func (m *RBACMiddleware) syncKeycloakRoles(c *gin.Context, tenantID string, staf
 		isPlatformOwner = true
 	}
 
-	// Skip sync if no roles to sync
-	if len(keycloakRoles) == 0 && !isPlatformOwner {
+	// Get group paths from x-jwt-claim-groups (Keycloak group membership mapper with full paths).
+	// A missing header means the claim isn't mapped, so group memberships are left untouched.
+	groupsHeader, hasGroups := c.Request.Header[http.CanonicalHeaderKey("x-jwt-claim-groups")]
+	syncGroups := hasGroups && m.roleSyncService.GroupSyncEnabled()
+	var keycloakGroups []string
+	if syncGroups {
+		keycloakGroups = parseClaimList(strings.Join(groupsHeader, ","))
+	}
+
+	// Skip sync if no roles or groups to sync
+	if len(keycloakRoles) == 0 && !isPlatformOwner && !syncGroups {
 		return
 	}
 
@@ -607,6 +616,19 @@ func (m *RBACMiddleware) syncKeycloakRoles(c *gin.Context, tenantID string, staf
 	// Run sync asynchronously to not block the request
 	go func() {
 		ctx := context.Background()
+		if syncGroups {
+			if err := m.roleSyncService.SyncGroupsForStaff(ctx, tenantID, staffID, keycloakGroups); err != nil {
+				m.logger.WithError(err).WithFields(logrus.Fields{
+					"tenant_id": tenantID,
+					"staff_id":  staffID,
+					"groups":    keycloakGroups,
+				}).Warn("Failed to sync Keycloak groups")
+			}
+		}
+		if len(keycloakRoles) == 0 && !isPlatformOwner {
+			return
+		}
+
 		if err := m.roleSyncService.SyncRolesForStaff(ctx, tenantID, vendorID, staffID, keycloakRoles, isPlatformOwner); err != nil {
 			m.logger.WithError(err).WithFields(logrus.Fields{
 				"tenant_id": tenantID,
@@ -628,6 +650,19 @@ func (m *RBACMiddleware) syncKeycloakRoles(c *gin.Context, tenantID string, staf
 	}()
 }
 
+// parseClaimList parses a list claim forwarded as a header, either comma-separated or a JSON array
+func parseClaimList(header string) []string {
+	var values []string
+	header = strings.Trim(header, "[]\"")
+	for _, value := range strings.Split(header, ",") {
+		value = strings.Trim(strings.TrimSpace(value), "\"")
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getVendorIDPtr(c *gin.Context) *string {
 	vendorID := c.GetString("vendor_id")
 	if vendorID == "" {
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"staff-service/internal/tenancy"
 )
 
 // TenantMiddleware extracts tenant ID from Istio JWT claim headers
@@ -35,6 +37,8 @@ func TenantMiddleware() gin.HandlerFunc {
 
 		// Set tenant ID in context for handlers to use
 		c.Set("tenant_id", tenantID)
+		// Scope database statements made with the request context (see internal/tenancy)
+		c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), tenantID))
 		c.Next()
 	}
 }
@@ -55,6 +59,8 @@ func OptionalTenantMiddleware() gin.HandlerFunc {
 		// Set tenant ID in context if provided (don't abort if missing)
 		if tenantID != "" {
 			c.Set("tenant_id", tenantID)
+			// Scope database statements made with the request context (see internal/tenancy)
+			c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), tenantID))
 		}
 		c.Next()
 	}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// APIKeyPrefix starts every tenant API key, so leaked keys are easy to recognise
+	APIKeyPrefix = "tnx_"
+
+	DefaultAPIKeyRateLimit  = 600  // Requests per minute
+	MaxAPIKeyRateLimit      = 6000 // Requests per minute
+	DefaultAPIKeyGraceHours = 24   // Hours the previous key stays valid after a rotation
+	MaxAPIKeyGraceHours     = 168
+)
+
+// TenantAPIKey is a tenant's key for server-to-server integrations. Requests made with
+// it are authorized with its scoped permissions, as if it were a staff member; its ID
+// stands in for the staff ID. Only the SHA-256 hash of the key is stored.
+type TenantAPIKey struct {
+	ID                   uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID             string     `json:"tenantId" gorm:"not null;index"`
+	VendorID             *string    `json:"vendorId,omitempty"`
+	Name                 string     `json:"name" gorm:"not null"`
+	Description          *string    `json:"description,omitempty"`
+	KeyPrefix            string     `json:"keyPrefix" gorm:"not null"` // First characters of the key, to identify it
+	KeyHash              string     `json:"-" gorm:"not null;uniqueIndex"`
+	PreviousKeyHash      *string    `json:"-" gorm:"index"`
+	PreviousKeyExpiresAt *time.Time `json:"previousKeyExpiresAt,omitempty"` // End of the rotation grace period
+	Permissions          JSONArray  `json:"permissions" gorm:"type:jsonb;not null;default:'[]'"`
+	RateLimitPerMinute   int        `json:"rateLimitPerMinute" gorm:"not null;default:600"`
+	ExpiresAt            *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt           *time.Time `json:"lastUsedAt,omitempty"`
+	RotatedAt            *time.Time `json:"rotatedAt,omitempty"`
+	RevokedAt            *time.Time `json:"revokedAt,omitempty"`
+	RevokedBy            *string    `json:"revokedBy,omitempty"`
+	CreatedBy            *string    `json:"createdBy,omitempty"`
+	CreatedAt            time.Time  `json:"createdAt"`
+	UpdatedAt            time.Time  `json:"updatedAt"`
+}
+
+// TableName returns the table name for the TenantAPIKey model
+func (TenantAPIKey) TableName() string {
+	return "tenant_api_keys"
+}
+
+// IsUsable reports whether the key is neither revoked nor expired
+func (k *TenantAPIKey) IsUsable() bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt))
+}
+
+// PermissionNames returns the names of the key's permissions
+func (k *TenantAPIKey) PermissionNames() []string {
+	names := make([]string, 0, len(k.Permissions))
+	for _, p := range k.Permissions {
+		if name, ok := p.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// CreateAPIKeyRequest issues a new API key scoped to permissions
+type CreateAPIKeyRequest struct {
+	Name               string   `json:"name" binding:"required,max=255"`
+	Description        *string  `json:"description,omitempty"`
+	Permissions        []string `json:"permissions" binding:"required,min=1"`
+	RateLimitPerMinute *int     `json:"rateLimitPerMinute,omitempty"`
+	ExpiresInDays      *int     `json:"expiresInDays,omitempty"`
+}
+
+// UpdateAPIKeyRequest changes an API key's name, scope or rate limit
+type UpdateAPIKeyRequest struct {
+	Name               *string  `json:"name,omitempty" binding:"omitempty,max=255"`
+	Description        *string  `json:"description,omitempty"`
+	Permissions        []string `json:"permissions,omitempty"`
+	RateLimitPerMinute *int     `json:"rateLimitPerMinute,omitempty"`
+}
+
+// RotateAPIKeyRequest issues a new secret for an API key. The previous secret keeps
+// working for the grace period so integrations can be updated without downtime.
+type RotateAPIKeyRequest struct {
+	GracePeriodHours *int `json:"gracePeriodHours,omitempty"`
+}
+
+// APIKeySecretResponse returns a newly issued key. The key is only ever shown here.
+type APIKeySecretResponse struct {
+	APIKey *TenantAPIKey `json:"apiKey"`
+	Key    string        `json:"key"`
+}
+
+// ValidateAPIKeyRequest is sent by services that accept API keys
+type ValidateAPIKeyRequest struct {
+	Key string `json:"key" binding:"required"`
+}
+
+// ValidatedAPIKey identifies the tenant and scope of a valid API key
+type ValidatedAPIKey struct {
+	KeyID              uuid.UUID `json:"keyId"`
+	TenantID           string    `json:"tenantId"`
+	VendorID           *string   `json:"vendorId,omitempty"`
+	Name               string    `json:"name"`
+	Permissions        []string  `json:"permissions"`
+	RateLimitPerMinute int       `json:"rateLimitPerMinute"`
+}
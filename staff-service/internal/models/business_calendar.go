@@ -0,0 +1,272 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxCalendarSpanDays bounds how far the business time calculations walk a calendar
+const maxCalendarSpanDays = 3 * 366
+
+// WorkingHours is one working window on a weekday, in the calendar's timezone.
+// Start and End are "HH:MM"; End may be "24:00".
+type WorkingHours struct {
+	Weekday time.Weekday `json:"weekday"` // 0 = Sunday
+	Start   string       `json:"start"`
+	End     string       `json:"end"`
+}
+
+// WorkingHoursList is stored as a JSONB array
+type WorkingHoursList []WorkingHours
+
+func (w WorkingHoursList) Value() (driver.Value, error) {
+	return json.Marshal(w)
+}
+
+func (w *WorkingHoursList) Scan(value interface{}) error {
+	if value == nil {
+		*w = WorkingHoursList{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, w)
+}
+
+// BusinessCalendar holds a tenant's working hours and holidays for a region. Tickets SLA
+// timers and approval escalation timers count time only while the calendar is open.
+// Calendars without a region, or marked default, apply when no regional calendar matches.
+type BusinessCalendar struct {
+	ID           uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID     string            `json:"tenantId" gorm:"not null;uniqueIndex:idx_business_calendars_name"`
+	Name         string            `json:"name" gorm:"not null;uniqueIndex:idx_business_calendars_name"`
+	Region       string            `json:"region,omitempty"`
+	Timezone     string            `json:"timezone" gorm:"not null;default:'UTC'"`
+	IsDefault    bool              `json:"isDefault" gorm:"not null;default:false"`
+	WorkingHours WorkingHoursList  `json:"workingHours" gorm:"type:jsonb;not null"`
+	Holidays     []BusinessHoliday `json:"holidays" gorm:"foreignKey:CalendarID"`
+	UpdatedBy    *string           `json:"updatedBy,omitempty"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+}
+
+// TableName returns the table name for the BusinessCalendar model
+func (BusinessCalendar) TableName() string {
+	return "business_calendars"
+}
+
+// BusinessHoliday closes a calendar for a whole day. Recurring holidays repeat on the
+// same month and day every year.
+type BusinessHoliday struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CalendarID uuid.UUID `json:"calendarId" gorm:"type:uuid;not null;index"`
+	Date       time.Time `json:"date" gorm:"type:date;not null"`
+	Name       string    `json:"name" gorm:"not null"`
+	Recurring  bool      `json:"recurring" gorm:"not null;default:false"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName returns the table name for the BusinessHoliday model
+func (BusinessHoliday) TableName() string {
+	return "business_holidays"
+}
+
+// CreateBusinessCalendarRequest creates a business calendar
+type CreateBusinessCalendarRequest struct {
+	Name         string         `json:"name" binding:"required"`
+	Region       string         `json:"region,omitempty"`
+	Timezone     string         `json:"timezone" binding:"required"`
+	IsDefault    bool           `json:"isDefault"`
+	WorkingHours []WorkingHours `json:"workingHours" binding:"required"`
+}
+
+// UpdateBusinessCalendarRequest updates a business calendar. WorkingHours replaces the
+// existing windows when set.
+type UpdateBusinessCalendarRequest struct {
+	Name         *string        `json:"name,omitempty"`
+	Region       *string        `json:"region,omitempty"`
+	Timezone     *string        `json:"timezone,omitempty"`
+	IsDefault    *bool          `json:"isDefault,omitempty"`
+	WorkingHours []WorkingHours `json:"workingHours,omitempty"`
+}
+
+// AddBusinessHolidayRequest adds a holiday to a calendar
+type AddBusinessHolidayRequest struct {
+	Date      string `json:"date" binding:"required"` // YYYY-MM-DD
+	Name      string `json:"name" binding:"required"`
+	Recurring bool   `json:"recurring"`
+}
+
+// ElapsedBusinessTime is the business time between two instants on a calendar
+type ElapsedBusinessTime struct {
+	CalendarID     uuid.UUID `json:"calendarId"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	ElapsedMinutes int       `json:"elapsedMinutes"`
+}
+
+// ParseClock parses "HH:MM" into minutes after midnight, allowing "24:00"
+func ParseClock(clock string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &hour, &minute); err != nil || len(clock) != 5 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", clock)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time %q", clock)
+	}
+	return hour*60 + minute, nil
+}
+
+// ValidateWorkingHours checks that every window is on a valid weekday and ends after it starts
+func ValidateWorkingHours(hours []WorkingHours) error {
+	if len(hours) == 0 {
+		return fmt.Errorf("at least one working hours window is required")
+	}
+	for _, h := range hours {
+		if h.Weekday < time.Sunday || h.Weekday > time.Saturday {
+			return fmt.Errorf("weekday must be 0 (Sunday) to 6 (Saturday)")
+		}
+		start, err := ParseClock(h.Start)
+		if err != nil {
+			return err
+		}
+		end, err := ParseClock(h.End)
+		if err != nil {
+			return err
+		}
+		if end <= start {
+			return fmt.Errorf("working hours on %s must end after they start", h.Weekday)
+		}
+	}
+	return nil
+}
+
+// Location returns the calendar's timezone, falling back to UTC
+func (c *BusinessCalendar) Location() *time.Location {
+	if loc, err := time.LoadLocation(c.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// IsHoliday reports whether the calendar is closed all day on the given local date
+func (c *BusinessCalendar) IsHoliday(day time.Time) bool {
+	for _, h := range c.Holidays {
+		if h.Date.Month() != day.Month() || h.Date.Day() != day.Day() {
+			continue
+		}
+		if h.Recurring || h.Date.Year() == day.Year() {
+			return true
+		}
+	}
+	return false
+}
+
+// openWindows returns the calendar's working windows on a local date as instants, in order
+// with overlapping windows merged
+func (c *BusinessCalendar) openWindows(day time.Time) [][2]time.Time {
+	if c.IsHoliday(day) {
+		return nil
+	}
+	var windows [][2]time.Time
+	for _, h := range c.WorkingHours {
+		if h.Weekday != day.Weekday() {
+			continue
+		}
+		start, err1 := ParseClock(h.Start)
+		end, err2 := ParseClock(h.End)
+		if err1 != nil || err2 != nil || end <= start {
+			continue
+		}
+		windows = append(windows, [2]time.Time{
+			time.Date(day.Year(), day.Month(), day.Day(), start/60, start%60, 0, 0, day.Location()),
+			time.Date(day.Year(), day.Month(), day.Day(), end/60, end%60, 0, 0, day.Location()),
+		})
+	}
+
+	sort.Slice(windows, func(i, j int) bool { return windows[i][0].Before(windows[j][0]) })
+	merged := windows[:0]
+	for _, w := range windows {
+		if n := len(merged); n > 0 && !w[0].After(merged[n-1][1]) {
+			if w[1].After(merged[n-1][1]) {
+				merged[n-1][1] = w[1]
+			}
+			continue
+		}
+		merged = append(merged, w)
+	}
+	return merged
+}
+
+// ElapsedBusinessMinutes counts the minutes between from and to that fall within working
+// hours, skipping holidays. A nil calendar counts every minute.
+func (c *BusinessCalendar) ElapsedBusinessMinutes(from, to time.Time) int {
+	if !to.After(from) {
+		return 0
+	}
+	if c == nil {
+		return int(to.Sub(from) / time.Minute)
+	}
+
+	loc := c.Location()
+	from, to = from.In(loc), to.In(loc)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+
+	var elapsed time.Duration
+	for i := 0; i <= maxCalendarSpanDays && day.Before(to); i++ {
+		for _, w := range c.openWindows(day) {
+			start, end := w[0], w[1]
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				elapsed += end.Sub(start)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return int(elapsed / time.Minute)
+}
+
+// AddBusinessMinutes returns when the given business minutes after from will have elapsed,
+// e.g. an SLA due time. A nil calendar adds wall-clock minutes.
+func (c *BusinessCalendar) AddBusinessMinutes(from time.Time, minutes int) time.Time {
+	if c == nil || minutes <= 0 {
+		return from.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	loc := c.Location()
+	from = from.In(loc)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	remaining := time.Duration(minutes) * time.Minute
+
+	for i := 0; i <= maxCalendarSpanDays; i++ {
+		for _, w := range c.openWindows(day) {
+			start, end := w[0], w[1]
+			if start.Before(from) {
+				start = from
+			}
+			if !end.After(start) {
+				continue
+			}
+			open := end.Sub(start)
+			if open >= remaining {
+				return start.Add(remaining)
+			}
+			remaining -= open
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	// The calendar is never open; fall back to wall-clock time
+	return from.Add(time.Duration(minutes) * time.Minute)
+}
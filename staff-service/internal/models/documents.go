@@ -201,6 +201,7 @@ type StaffDocument struct {
 	AccessLevel        DocumentAccessLevel        `json:"accessLevel" gorm:"not null;default:'hr_only'"`
 	IsMandatory        bool                       `json:"isMandatory" gorm:"default:false"`
 	ReminderSentAt     *time.Time                 `json:"reminderSentAt,omitempty"`
+	LastReminderDays   *int                       `json:"lastReminderDays,omitempty"` // Smallest reminder threshold already sent for the current expiry date
 	Metadata           *JSON                      `json:"metadata,omitempty" gorm:"type:jsonb;default:'{}'"`
 	CreatedAt          time.Time                  `json:"createdAt"`
 	UpdatedAt          time.Time                  `json:"updatedAt"`
@@ -315,6 +316,24 @@ type UpdateEmergencyContactRequest struct {
 // COMPLIANCE STATUS
 // ============================================================================
 
+// DocumentComplianceState is a staff member's document compliance state
+type DocumentComplianceState string
+
+const (
+	ComplianceStateCompliant    DocumentComplianceState = "compliant"
+	ComplianceStateNonCompliant DocumentComplianceState = "non_compliant" // A mandatory document has lapsed
+)
+
+// DocumentExpiryReminderDays are the days before expiry at which staff and their managers
+// are reminded, largest first. Each threshold is sent at most once per expiry date.
+var DocumentExpiryReminderDays = []int{30, 14, 7, 1}
+
+// IsMandatoryDocument reports whether the document is mandatory, either by its type or because
+// it was flagged mandatory when uploaded
+func (d *StaffDocument) IsMandatoryDocument() bool {
+	return d.IsMandatory || GetDocumentTypeInfo(d.DocumentType).IsMandatory
+}
+
 // DocumentComplianceItem represents the compliance status of a single document type
 type DocumentComplianceItem struct {
 	DocumentType    StaffDocumentType          `json:"documentType"`
@@ -344,6 +363,32 @@ type StaffComplianceStatus struct {
 	LastUpdated          time.Time                `json:"lastUpdated"`
 }
 
+// DocumentTypeComplianceSummary counts a tenant's documents of one type by status
+type DocumentTypeComplianceSummary struct {
+	DocumentType StaffDocumentType `json:"documentType"`
+	DisplayName  string            `json:"displayName"`
+	IsMandatory  bool              `json:"isMandatory"`
+	Total        int64             `json:"total"`
+	Pending      int64             `json:"pending"`
+	Verified     int64             `json:"verified"`
+	Rejected     int64             `json:"rejected"`
+	Expired      int64             `json:"expired"`
+	ExpiringSoon int64             `json:"expiringSoon"`
+	Missing      int64             `json:"missing"` // Active staff without a submitted document of a mandatory type
+}
+
+// DocumentComplianceDashboard summarizes document compliance across a tenant's staff
+type DocumentComplianceDashboard struct {
+	TotalStaff         int64                                `json:"totalStaff"`
+	CompliantStaff     int64                                `json:"compliantStaff"`
+	NonCompliantStaff  int64                                `json:"nonCompliantStaff"`
+	TotalDocuments     int64                                `json:"totalDocuments"`
+	ByStatus           map[DocumentVerificationStatus]int64 `json:"byStatus"`
+	ByType             []DocumentTypeComplianceSummary      `json:"byType"`
+	ExpiringWithinDays int                                  `json:"expiringWithinDays"`
+	GeneratedAt        time.Time                            `json:"generatedAt"`
+}
+
 // ============================================================================
 // RESPONSE TYPES
 // ============================================================================
@@ -382,6 +427,12 @@ type ComplianceStatusResponse struct {
 	Message *string                `json:"message,omitempty"`
 }
 
+// ComplianceDashboardResponse represents a tenant compliance dashboard API response
+type ComplianceDashboardResponse struct {
+	Success bool                         `json:"success"`
+	Data    *DocumentComplianceDashboard `json:"data,omitempty"`
+}
+
 // DocumentTypesResponse represents available document types API response
 type DocumentTypesResponse struct {
 	Success bool               `json:"success"`
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Group membership sources
+const (
+	GroupMembershipSourceLogin     = "login"
+	GroupMembershipSourceReconcile = "reconcile"
+)
+
+// GroupSyncSettings is a tenant's Keycloak group sync configuration.
+// Tenants without a row don't sync groups.
+type GroupSyncSettings struct {
+	ID                uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID          string     `json:"tenantId" gorm:"not null;uniqueIndex"`
+	Enabled           bool       `json:"enabled" gorm:"not null;default:false"`
+	ReconcileRemovals bool       `json:"reconcileRemovals" gorm:"not null;default:true"` // Scheduled job removes memberships of users who left a group
+	SetPrimary        bool       `json:"setPrimary" gorm:"not null;default:true"`        // Also set the staff member's department and team
+	LastReconciledAt  *time.Time `json:"lastReconciledAt,omitempty"`
+	UpdatedBy         *string    `json:"updatedBy,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+// TableName returns the table name for the GroupSyncSettings model
+func (GroupSyncSettings) TableName() string {
+	return "keycloak_group_sync_settings"
+}
+
+// KeycloakGroupMapping maps a Keycloak group path to a department, and optionally a team
+// within it, by code. Members of subgroups match the closest mapped ancestor.
+type KeycloakGroupMapping struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID       string    `json:"tenantId" gorm:"not null;uniqueIndex:idx_keycloak_group_mappings_path"`
+	GroupPath      string    `json:"groupPath" gorm:"not null;uniqueIndex:idx_keycloak_group_mappings_path"` // e.g. "/Sales/EMEA"
+	DepartmentCode string    `json:"departmentCode" gorm:"not null"`
+	TeamCode       *string   `json:"teamCode,omitempty"`
+	Priority       int       `json:"priority" gorm:"not null;default:0"` // Highest priority membership sets the primary department
+	UpdatedBy      *string   `json:"updatedBy,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// TableName returns the table name for the KeycloakGroupMapping model
+func (KeycloakGroupMapping) TableName() string {
+	return "keycloak_group_mappings"
+}
+
+// StaffGroupMembership is a staff member's department (and team) membership derived from
+// a mapped Keycloak group. Only memberships created by the sync are ever removed by it.
+type StaffGroupMembership struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID     string     `json:"tenantId" gorm:"not null;uniqueIndex:idx_staff_group_memberships_group"`
+	StaffID      uuid.UUID  `json:"staffId" gorm:"type:uuid;not null;uniqueIndex:idx_staff_group_memberships_group"`
+	GroupPath    string     `json:"groupPath" gorm:"not null;uniqueIndex:idx_staff_group_memberships_group"`
+	DepartmentID uuid.UUID  `json:"departmentId" gorm:"type:uuid;not null"`
+	TeamID       *uuid.UUID `json:"teamId,omitempty" gorm:"type:uuid"`
+	Priority     int        `json:"priority" gorm:"not null;default:0"`
+	Source       string     `json:"source" gorm:"not null"`
+	SyncedAt     time.Time  `json:"syncedAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// TableName returns the table name for the StaffGroupMembership model
+func (StaffGroupMembership) TableName() string {
+	return "staff_group_memberships"
+}
+
+// UpdateGroupSyncSettingsRequest updates a tenant's group sync configuration
+type UpdateGroupSyncSettingsRequest struct {
+	Enabled           *bool `json:"enabled,omitempty"`
+	ReconcileRemovals *bool `json:"reconcileRemovals,omitempty"`
+	SetPrimary        *bool `json:"setPrimary,omitempty"`
+}
+
+// SaveGroupMappingRequest creates or replaces the mapping of a group path
+type SaveGroupMappingRequest struct {
+	GroupPath      string  `json:"groupPath" binding:"required"`
+	DepartmentCode string  `json:"departmentCode" binding:"required"`
+	TeamCode       *string `json:"teamCode,omitempty"`
+	Priority       int     `json:"priority"`
+}
+
+// GroupReconcileResult summarizes a reconciliation of a tenant's group memberships
+type GroupReconcileResult struct {
+	TenantID      string `json:"tenantId"`
+	StaffChecked  int    `json:"staffChecked"`
+	StaffChanged  int    `json:"staffChanged"`
+	StaffFailed   int    `json:"staffFailed"`
+	StaffUnlinked int    `json:"staffUnlinked"` // Inactive staff or staff without a Keycloak user whose memberships were removed
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ImpersonationTokenPrefix starts every impersonation token, so leaked tokens are easy to recognise
+	ImpersonationTokenPrefix = "imp_"
+
+	DefaultImpersonationMinutes = 30
+	MaxImpersonationMinutes     = 120
+	MaxImpersonationActionBatch = 100
+)
+
+// ImpersonationTargetType is what an admin acts as while impersonating
+type ImpersonationTargetType string
+
+const (
+	// ImpersonationTargetVendor scopes the admin's requests to one vendor's data
+	ImpersonationTargetVendor ImpersonationTargetType = "vendor"
+	// ImpersonationTargetStaff authorizes the admin's requests with one staff member's
+	// permissions and vendor
+	ImpersonationTargetStaff ImpersonationTargetType = "staff"
+)
+
+// ImpersonationSession is a time-limited grant for an admin to act as a vendor or staff member
+// while troubleshooting. Services that honor it take the token in the X-Impersonation-Token
+// header alongside the admin's own credentials and report every request made with it. Only
+// the SHA-256 hash of the token is stored.
+type ImpersonationSession struct {
+	ID             uuid.UUID               `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID       string                  `json:"tenantId" gorm:"not null;index"`
+	AdminStaffID   uuid.UUID               `json:"adminStaffId" gorm:"type:uuid;not null;index"`
+	AdminUserID    *string                 `json:"-"` // Keycloak user ID, which other services see as the user ID
+	AdminName      string                  `json:"adminName" gorm:"not null"`
+	AdminEmail     string                  `json:"adminEmail" gorm:"not null"`
+	TargetType     ImpersonationTargetType `json:"targetType" gorm:"type:varchar(20);not null"`
+	TargetVendorID *string                 `json:"targetVendorId,omitempty" gorm:"index"`
+	TargetStaffID  *uuid.UUID              `json:"targetStaffId,omitempty" gorm:"type:uuid"`
+	TargetName     *string                 `json:"targetName,omitempty"` // Staff member's name, for the banner
+	Reason         string                  `json:"reason" gorm:"type:text;not null"`
+	AllowWrites    bool                    `json:"allowWrites" gorm:"not null;default:false"` // Read-only unless granted
+	TokenPrefix    string                  `json:"tokenPrefix" gorm:"not null"`
+	TokenHash      string                  `json:"-" gorm:"not null;uniqueIndex"`
+	ExpiresAt      time.Time               `json:"expiresAt" gorm:"not null"`
+	EndedAt        *time.Time              `json:"endedAt,omitempty"`
+	EndedBy        *string                 `json:"endedBy,omitempty"`
+	ActionCount    int                     `json:"actionCount" gorm:"not null;default:0"`
+	LastActionAt   *time.Time              `json:"lastActionAt,omitempty"`
+	IPAddress      *string                 `json:"ipAddress,omitempty"`
+	UserAgent      *string                 `json:"userAgent,omitempty"`
+	CreatedAt      time.Time               `json:"createdAt"`
+	UpdatedAt      time.Time               `json:"updatedAt"`
+}
+
+// TableName returns the table name for the ImpersonationSession model
+func (ImpersonationSession) TableName() string {
+	return "staff_impersonation_sessions"
+}
+
+// IsActive reports whether the session has neither ended nor expired
+func (s *ImpersonationSession) IsActive() bool {
+	return s.EndedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// ImpersonationAction is one request an admin made while impersonating, as reported by the
+// service that handled it
+type ImpersonationAction struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SessionID  uuid.UUID `json:"sessionId" gorm:"type:uuid;not null;index"`
+	TenantID   string    `json:"tenantId" gorm:"not null;index"`
+	Service    string    `json:"service" gorm:"not null"`
+	Method     string    `json:"method" gorm:"not null"`
+	Path       string    `json:"path" gorm:"type:text;not null"`
+	StatusCode int       `json:"statusCode"`
+	Blocked    bool      `json:"blocked"` // Rejected because the session is read-only
+	IPAddress  *string   `json:"ipAddress,omitempty"`
+	OccurredAt time.Time `json:"occurredAt" gorm:"not null"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName returns the table name for the ImpersonationAction model
+func (ImpersonationAction) TableName() string {
+	return "staff_impersonation_actions"
+}
+
+// StartImpersonationRequest starts impersonating a vendor or staff member
+type StartImpersonationRequest struct {
+	TargetType      ImpersonationTargetType `json:"targetType" binding:"required,oneof=vendor staff"`
+	VendorID        *string                 `json:"vendorId,omitempty"`
+	StaffID         *uuid.UUID              `json:"staffId,omitempty"`
+	Reason          string                  `json:"reason" binding:"required,min=10,max=1000"`
+	DurationMinutes *int                    `json:"durationMinutes,omitempty"`
+	AllowWrites     bool                    `json:"allowWrites"`
+}
+
+// ImpersonationTokenResponse returns a newly started session. The token is only ever shown here.
+type ImpersonationTokenResponse struct {
+	Session *ImpersonationSession `json:"session"`
+	Token   string                `json:"token"`
+}
+
+// ImpersonationSessionFilters narrows the session list
+type ImpersonationSessionFilters struct {
+	ActiveOnly   bool
+	AdminStaffID *uuid.UUID
+	VendorID     *string
+}
+
+// ValidateImpersonationRequest is sent by services that honor impersonation tokens
+type ValidateImpersonationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ValidatedImpersonation describes an active impersonation session to the service checking it
+type ValidatedImpersonation struct {
+	SessionID    uuid.UUID               `json:"sessionId"`
+	TenantID     string                  `json:"tenantId"`
+	AdminStaffID uuid.UUID               `json:"adminStaffId"`
+	AdminUserID  *string                 `json:"adminUserId,omitempty"`
+	AdminName    string                  `json:"adminName"`
+	AdminEmail   string                  `json:"adminEmail"`
+	TargetType   ImpersonationTargetType `json:"targetType"`
+	VendorID     *string                 `json:"vendorId,omitempty"`
+	StaffID      *uuid.UUID              `json:"staffId,omitempty"`
+	TargetName   *string                 `json:"targetName,omitempty"`
+	AllowWrites  bool                    `json:"allowWrites"`
+	ExpiresAt    time.Time               `json:"expiresAt"`
+}
+
+// RecordImpersonationActionsRequest reports requests made while impersonating
+type RecordImpersonationActionsRequest struct {
+	Actions []ImpersonationActionReport `json:"actions" binding:"required,min=1,dive"`
+}
+
+// ImpersonationActionReport is one request made while impersonating
+type ImpersonationActionReport struct {
+	SessionID  uuid.UUID `json:"sessionId" binding:"required"`
+	Service    string    `json:"service" binding:"required"`
+	Method     string    `json:"method" binding:"required"`
+	Path       string    `json:"path" binding:"required"`
+	StatusCode int       `json:"statusCode"`
+	Blocked    bool      `json:"blocked"`
+	IPAddress  *string   `json:"ipAddress,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
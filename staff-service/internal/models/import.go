@@ -78,16 +78,6 @@ type BulkCreateError struct {
 	Message string `json:"message"`
 }
 
-// BulkCreateResult represents the result of a bulk create operation
-type BulkCreateResult struct {
-	Success int               `json:"success"`
-	Failed  int               `json:"failed"`
-	Skipped int               `json:"skipped"`
-	Created []*Staff          `json:"created,omitempty"`
-	Updated []*Staff          `json:"updated,omitempty"`
-	Errors  []BulkCreateError `json:"errors,omitempty"`
-}
-
 // BulkCreateStaffItem represents a single staff member in bulk import
 type BulkCreateStaffItem struct {
 	FirstName      string         `json:"firstName" binding:"required"`
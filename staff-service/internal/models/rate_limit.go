@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+const (
+	// PlatformOwnerPriority is the role priority of platform owners, who manage platform-wide settings
+	PlatformOwnerPriority = 200
+
+	MaxRateLimitWindowSeconds = 3600
+	MaxRateLimitMultiplier    = 100
+)
+
+// RateLimitProfile allows RequestsPerWindow requests per tenant and client IP in a sliding
+// window of WindowSeconds
+type RateLimitProfile struct {
+	RequestsPerWindow int `json:"requestsPerWindow"`
+	WindowSeconds     int `json:"windowSeconds"`
+}
+
+// RateLimitEndpointGroup applies a profile to matching requests. A request matches when its
+// method is listed (or no methods are) and its path starts with one of PathPrefixes or
+// contains one of PathContains.
+type RateLimitEndpointGroup struct {
+	Name         string   `json:"name"`
+	Methods      []string `json:"methods,omitempty"`
+	PathPrefixes []string `json:"pathPrefixes,omitempty"`
+	PathContains []string `json:"pathContains,omitempty"`
+	Profile      string   `json:"profile"`
+}
+
+// RateLimitSettings are the rate limit profiles used by every rate-limited service. Endpoint
+// groups are matched in order; requests matching none use DefaultProfile.
+type RateLimitSettings struct {
+	DefaultProfile string                      `json:"defaultProfile"`
+	Profiles       map[string]RateLimitProfile `json:"profiles"`
+	EndpointGroups []RateLimitEndpointGroup    `json:"endpointGroups"`
+	UpdatedAt      *time.Time                  `json:"updatedAt,omitempty"`
+	UpdatedBy      string                      `json:"updatedBy,omitempty"`
+}
+
+// DefaultRateLimitSettings keeps the "standard" profile the services used before profiles
+// were configurable, with stricter exports and looser storefront reads
+func DefaultRateLimitSettings() *RateLimitSettings {
+	return &RateLimitSettings{
+		DefaultProfile: "standard",
+		Profiles: map[string]RateLimitProfile{
+			"standard":   {RequestsPerWindow: 100, WindowSeconds: 1},
+			"export":     {RequestsPerWindow: 10, WindowSeconds: 60},
+			"storefront": {RequestsPerWindow: 500, WindowSeconds: 1},
+		},
+		EndpointGroups: []RateLimitEndpointGroup{
+			{Name: "exports", PathContains: []string{"/export"}, Profile: "export"},
+			{Name: "storefront_reads", Methods: []string{"GET"}, PathContains: []string{"/storefront/", "/public/"}, Profile: "storefront"},
+		},
+	}
+}
+
+// TenantRateLimitOverride raises or lowers a tenant's limits, e.g. for an enterprise plan.
+// Profiles replace the named profiles for the tenant; other profiles are scaled by Multiplier.
+type TenantRateLimitOverride struct {
+	TenantID   string                      `json:"tenantId"`
+	Plan       string                      `json:"plan,omitempty"`
+	Multiplier float64                     `json:"multiplier,omitempty"`
+	Profiles   map[string]RateLimitProfile `json:"profiles,omitempty"`
+	UpdatedAt  *time.Time                  `json:"updatedAt,omitempty"`
+	UpdatedBy  string                      `json:"updatedBy,omitempty"`
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Retention resources. Policies are stored here; each resource is purged by the service that owns the data.
+const (
+	RetentionResourceAbandonedCarts = "abandoned_carts"
+	RetentionResourceLoginAudit     = "login_audit"
+	RetentionResourceWebhookEvents  = "webhook_events"
+)
+
+// Purge run outcomes
+const (
+	RetentionRunCompleted = "completed"
+	RetentionRunFailed    = "failed"
+)
+
+// RetentionResource describes a purgeable resource and the bounds tenants may configure
+type RetentionResource struct {
+	Resource    string `json:"resource"`
+	Service     string `json:"service"`
+	Description string `json:"description"`
+	DefaultDays int    `json:"defaultDays"`
+	MinDays     int    `json:"minDays"`
+	MaxDays     int    `json:"maxDays"`
+}
+
+// RetentionResources returns every resource that supports a retention policy
+func RetentionResources() []RetentionResource {
+	return []RetentionResource{
+		{
+			Resource:    RetentionResourceAbandonedCarts,
+			Service:     "customers-service",
+			Description: "Abandoned cart records used for recovery campaigns",
+			DefaultDays: 90,
+			MinDays:     7,
+			MaxDays:     730,
+		},
+		{
+			Resource:    RetentionResourceLoginAudit,
+			Service:     "staff-service",
+			Description: "Staff login attempt audit trail",
+			DefaultDays: 365,
+			MinDays:     90, // Keep at least a quarter for security investigations
+			MaxDays:     2555,
+		},
+		{
+			Resource:    RetentionResourceWebhookEvents,
+			Service:     "payment-service",
+			Description: "Raw payment gateway webhook payloads",
+			DefaultDays: 30,
+			MinDays:     7,
+			MaxDays:     365,
+		},
+	}
+}
+
+// RetentionResourceFor looks up a resource definition by name
+func RetentionResourceFor(resource string) (RetentionResource, bool) {
+	for _, r := range RetentionResources() {
+		if r.Resource == resource {
+			return r, true
+		}
+	}
+	return RetentionResource{}, false
+}
+
+// RetentionPolicy is a tenant's override of a resource's default retention.
+// Tenants without a row use the resource default with dry-run off.
+type RetentionPolicy struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID      string    `json:"tenantId" gorm:"not null;uniqueIndex:idx_retention_policies_resource"`
+	Resource      string    `json:"resource" gorm:"not null;uniqueIndex:idx_retention_policies_resource"`
+	RetentionDays int       `json:"retentionDays" gorm:"not null"`
+	DryRun        bool      `json:"dryRun" gorm:"not null;default:false"`
+	UpdatedBy     *string   `json:"updatedBy,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// TableName returns the table name for the RetentionPolicy model
+func (RetentionPolicy) TableName() string {
+	return "retention_policies"
+}
+
+// RetentionPurgeRun is the audit summary of one purge of one resource for one tenant.
+// Dry runs record what would have been deleted with DeletedCount left at zero.
+type RetentionPurgeRun struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID      string    `json:"tenantId" gorm:"not null;index:idx_retention_purge_runs_tenant"`
+	Resource      string    `json:"resource" gorm:"not null"`
+	Service       string    `json:"service" gorm:"not null"`
+	RetentionDays int       `json:"retentionDays" gorm:"not null"`
+	Cutoff        time.Time `json:"cutoff" gorm:"not null"`
+	DryRun        bool      `json:"dryRun" gorm:"not null;default:false"`
+	MatchedCount  int64     `json:"matchedCount" gorm:"not null;default:0"`
+	DeletedCount  int64     `json:"deletedCount" gorm:"not null;default:0"`
+	Status        string    `json:"status" gorm:"not null"`
+	Error         *string   `json:"error,omitempty"`
+	StartedAt     time.Time `json:"startedAt" gorm:"not null"`
+	CompletedAt   time.Time `json:"completedAt" gorm:"not null;index:idx_retention_purge_runs_tenant"`
+}
+
+// TableName returns the table name for the RetentionPurgeRun model
+func (RetentionPurgeRun) TableName() string {
+	return "retention_purge_runs"
+}
+
+// EffectiveRetentionPolicy is a resource's policy as it applies to a tenant
+type EffectiveRetentionPolicy struct {
+	RetentionResource
+	RetentionDays int        `json:"retentionDays"`
+	DryRun        bool       `json:"dryRun"`
+	IsDefault     bool       `json:"isDefault"`
+	UpdatedBy     *string    `json:"updatedBy,omitempty"`
+	UpdatedAt     *time.Time `json:"updatedAt,omitempty"`
+}
+
+// UpdateRetentionPolicyRequest sets a tenant's retention for a resource
+type UpdateRetentionPolicyRequest struct {
+	RetentionDays int  `json:"retentionDays" binding:"required"`
+	DryRun        bool `json:"dryRun"`
+}
+
+// RetentionPolicySet is what purge workers fetch: the default plus every tenant override for a resource
+type RetentionPolicySet struct {
+	Resource    string            `json:"resource"`
+	DefaultDays int               `json:"defaultDays"`
+	Policies    []RetentionPolicy `json:"policies"`
+}
+
+// PolicyFor returns the retention that applies to a tenant
+func (s RetentionPolicySet) PolicyFor(tenantID string) (retentionDays int, dryRun bool) {
+	for _, p := range s.Policies {
+		if p.TenantID == tenantID {
+			return p.RetentionDays, p.DryRun
+		}
+	}
+	return s.DefaultDays, false
+}
+
+// ShortestDays returns the smallest retention across the default and all overrides,
+// which bounds the oldest cutoff any tenant can have
+func (s RetentionPolicySet) ShortestDays() int {
+	shortest := s.DefaultDays
+	for _, p := range s.Policies {
+		if p.RetentionDays < shortest {
+			shortest = p.RetentionDays
+		}
+	}
+	return shortest
+}
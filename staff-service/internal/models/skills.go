@@ -0,0 +1,68 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Skill levels, lowest first
+var SkillLevels = []string{"beginner", "intermediate", "advanced", "expert"}
+
+// SkillLevelRank returns the rank of a skill level (1 for beginner), or 0 if it's unknown
+func SkillLevelRank(level string) int {
+	for i, l := range SkillLevels {
+		if strings.EqualFold(l, level) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// UpdateStaffSkillsRequest replaces a staff member's skills and certifications
+type UpdateStaffSkillsRequest struct {
+	Skills         []Skill         `json:"skills"`
+	Certifications []Certification `json:"certifications"`
+}
+
+// SkillCatalogEntry is a skill or certification name held by the tenant's staff
+type SkillCatalogEntry struct {
+	Name       string `json:"name"`
+	StaffCount int64  `json:"staffCount"`
+}
+
+// SkillCatalog lists the skills and certifications held by the tenant's active staff
+type SkillCatalog struct {
+	Skills         []SkillCatalogEntry `json:"skills"`
+	Certifications []SkillCatalogEntry `json:"certifications"`
+}
+
+// SkillRequirement is a skill an assignee must have, optionally at a minimum level
+type SkillRequirement struct {
+	Name     string `json:"name" binding:"required"`
+	MinLevel string `json:"minLevel,omitempty"`
+}
+
+// StaffMatchRequest finds active staff with all the required skills and unexpired
+// certifications, e.g. a "Returns" specialist who speaks "Spanish"
+type StaffMatchRequest struct {
+	Skills          []SkillRequirement `json:"skills"`
+	Certifications  []string           `json:"certifications"`
+	DepartmentID    *string            `json:"departmentId,omitempty"`
+	ExcludeStaffIDs []uuid.UUID        `json:"excludeStaffIds,omitempty"`
+	Limit           int                `json:"limit,omitempty"`
+}
+
+// StaffMatch is a qualified assignee, with the skills and certifications that matched
+type StaffMatch struct {
+	StaffID               uuid.UUID       `json:"staffId"`
+	FirstName             string          `json:"firstName"`
+	LastName              string          `json:"lastName"`
+	Email                 string          `json:"email"`
+	KeycloakUserID        *string         `json:"keycloakUserId,omitempty"`
+	DepartmentID          *string         `json:"departmentId,omitempty"`
+	TeamID                *string         `json:"teamId,omitempty"`
+	Score                 int             `json:"score"` // Sum of the matched skill level ranks
+	MatchedSkills         []Skill         `json:"matchedSkills"`
+	MatchedCertifications []Certification `json:"matchedCertifications"`
+}
@@ -240,6 +240,10 @@ type Staff struct {
 	SSOProfileData              *JSON               `json:"-" gorm:"column:sso_profile_data;type:jsonb"`
 	LastPasswordChange          *time.Time          `json:"lastPasswordChange,omitempty" gorm:"column:last_password_change"`
 
+	// Document compliance (from migration 036) - downgraded when a mandatory document lapses
+	ComplianceState    *DocumentComplianceState `json:"complianceState,omitempty" gorm:"column:compliance_state;default:'compliant'"`
+	ComplianceLapsedAt *time.Time               `json:"complianceLapsedAt,omitempty" gorm:"column:compliance_lapsed_at"`
+
 	CreatedAt time.Time       `json:"createdAt"`
 	UpdatedAt time.Time       `json:"updatedAt"`
 	DeletedAt *gorm.DeletedAt `json:"deletedAt,omitempty" gorm:"index"`
@@ -352,6 +356,12 @@ type UpdateStaffRequest struct {
 	CustomFields   *JSON      `json:"customFields,omitempty"`
 }
 
+// BulkUpdateStaffItem is one staff member's changes in a bulk update
+type BulkUpdateStaffItem struct {
+	ID uuid.UUID `json:"id" binding:"required"`
+	UpdateStaffRequest
+}
+
 // UpdateProfilePhotoRequest represents a request to update staff profile photo
 type UpdateProfilePhotoRequest struct {
 	ProfilePhotoURL        *string    `json:"profilePhotoUrl,omitempty"`
@@ -366,6 +376,7 @@ type StaffFilters struct {
 	Locations       []string         `json:"locations,omitempty"`
 	Managers        []string         `json:"managers,omitempty"`
 	Skills          []string         `json:"skills,omitempty"`
+	Certifications  []string         `json:"certifications,omitempty"` // Unexpired certifications only
 	IsActive        *bool            `json:"is_active,omitempty"`
 	HasLogin        *bool            `json:"hasLogin,omitempty"`
 	StartDateFrom   *time.Time       `json:"startDateFrom,omitempty"`
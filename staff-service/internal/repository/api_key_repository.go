@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"staff-service/internal/models"
+)
+
+// ============================================================================
+// API KEY REPOSITORY INTERFACE
+// ============================================================================
+
+type APIKeyRepository interface {
+	Create(key *models.TenantAPIKey) error
+	GetByID(tenantID string, id uuid.UUID) (*models.TenantAPIKey, error)
+	GetByHash(keyHash string) (*models.TenantAPIKey, error)
+	List(tenantID string, vendorID *string, includeRevoked bool) ([]models.TenantAPIKey, error)
+	Save(key *models.TenantAPIKey) error
+	Revoke(tenantID string, id uuid.UUID, revokedBy string) (bool, error)
+	TouchLastUsed(id uuid.UUID, at time.Time) error
+	GetPermissionsByNames(names []string) ([]models.Permission, error)
+	GetEffectivePermissions(tenantID string, id uuid.UUID) (*models.EffectivePermissions, error)
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a repository for tenant API keys
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// Create stores a new API key
+func (r *apiKeyRepository) Create(key *models.TenantAPIKey) error {
+	return r.db.Create(key).Error
+}
+
+// GetByID returns one of the tenant's API keys, including revoked ones
+func (r *apiKeyRepository) GetByID(tenantID string, id uuid.UUID) (*models.TenantAPIKey, error) {
+	var key models.TenantAPIKey
+	if err := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetByHash returns the unrevoked API key whose current secret, or previous secret within
+// its rotation grace period, has the hash
+func (r *apiKeyRepository) GetByHash(keyHash string) (*models.TenantAPIKey, error) {
+	var key models.TenantAPIKey
+	err := r.db.
+		Where("revoked_at IS NULL").
+		Where("key_hash = ? OR (previous_key_hash = ? AND previous_key_expires_at > ?)", keyHash, keyHash, time.Now()).
+		First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// List returns the tenant's API keys, newest first. With a vendor, only that vendor's keys.
+func (r *apiKeyRepository) List(tenantID string, vendorID *string, includeRevoked bool) ([]models.TenantAPIKey, error) {
+	query := r.db.Where("tenant_id = ?", tenantID)
+	if vendorID != nil && *vendorID != "" {
+		query = query.Where("vendor_id = ?", *vendorID)
+	}
+	if !includeRevoked {
+		query = query.Where("revoked_at IS NULL")
+	}
+
+	var keys []models.TenantAPIKey
+	err := query.Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// Save updates an API key
+func (r *apiKeyRepository) Save(key *models.TenantAPIKey) error {
+	key.UpdatedAt = time.Now()
+	return r.db.Save(key).Error
+}
+
+// Revoke revokes an API key and its rotation grace period. It reports whether an
+// unrevoked key was found.
+func (r *apiKeyRepository) Revoke(tenantID string, id uuid.UUID, revokedBy string) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&models.TenantAPIKey{}).
+		Where("tenant_id = ? AND id = ? AND revoked_at IS NULL", tenantID, id).
+		Updates(map[string]interface{}{
+			"revoked_at":              now,
+			"revoked_by":              revokedBy,
+			"previous_key_hash":       nil,
+			"previous_key_expires_at": nil,
+			"updated_at":              now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// TouchLastUsed records when the API key was last used
+func (r *apiKeyRepository) TouchLastUsed(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.TenantAPIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+}
+
+// GetPermissionsByNames returns the active permissions with the names
+func (r *apiKeyRepository) GetPermissionsByNames(names []string) ([]models.Permission, error) {
+	var permissions []models.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	err := r.db.Where("name IN ? AND is_active = ?", names, true).Order("sort_order, name").Find(&permissions).Error
+	return permissions, err
+}
+
+// GetEffectivePermissions returns the permissions of a usable API key in the shape of a
+// staff member's effective permissions, so RBAC checks in other services authorize key
+// requests unchanged. API keys have no roles and priority 0.
+func (r *apiKeyRepository) GetEffectivePermissions(tenantID string, id uuid.UUID) (*models.EffectivePermissions, error) {
+	key, err := r.GetByID(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !key.IsUsable() {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	permissions, err := r.GetPermissionsByNames(key.PermissionNames())
+	if err != nil {
+		return nil, err
+	}
+	return &models.EffectivePermissions{
+		StaffID:     key.ID,
+		Roles:       []models.Role{},
+		Permissions: permissions,
+	}, nil
+}
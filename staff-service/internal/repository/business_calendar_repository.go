@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"staff-service/internal/models"
+)
+
+// ============================================================================
+// BUSINESS CALENDAR REPOSITORY INTERFACE
+// ============================================================================
+
+type BusinessCalendarRepository interface {
+	// Calendars
+	ListCalendars(tenantID string) ([]models.BusinessCalendar, error)
+	GetCalendar(tenantID string, id uuid.UUID) (*models.BusinessCalendar, error)
+	CreateCalendar(calendar *models.BusinessCalendar) error
+	UpdateCalendar(calendar *models.BusinessCalendar) error
+	DeleteCalendar(tenantID string, id uuid.UUID) (bool, error)
+	ResolveCalendar(tenantID, region string) (*models.BusinessCalendar, error)
+
+	// Holidays
+	AddHoliday(holiday *models.BusinessHoliday) error
+	DeleteHoliday(calendarID, holidayID uuid.UUID) (bool, error)
+}
+
+type businessCalendarRepository struct {
+	db *gorm.DB
+}
+
+// NewBusinessCalendarRepository creates a repository for tenant business calendars
+func NewBusinessCalendarRepository(db *gorm.DB) BusinessCalendarRepository {
+	return &businessCalendarRepository{db: db}
+}
+
+// withHolidays loads holidays in date order
+func withHolidays(db *gorm.DB) *gorm.DB {
+	return db.Order("date ASC")
+}
+
+// ListCalendars returns a tenant's calendars with their holidays, default first
+func (r *businessCalendarRepository) ListCalendars(tenantID string) ([]models.BusinessCalendar, error) {
+	var calendars []models.BusinessCalendar
+	err := r.db.Preload("Holidays", withHolidays).
+		Where("tenant_id = ?", tenantID).
+		Order("is_default DESC, name ASC").
+		Find(&calendars).Error
+	return calendars, err
+}
+
+// GetCalendar returns a tenant's calendar with its holidays
+func (r *businessCalendarRepository) GetCalendar(tenantID string, id uuid.UUID) (*models.BusinessCalendar, error) {
+	var calendar models.BusinessCalendar
+	err := r.db.Preload("Holidays", withHolidays).
+		Where("tenant_id = ? AND id = ?", tenantID, id).
+		First(&calendar).Error
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+// CreateCalendar creates a calendar. A new default calendar replaces the tenant's previous default.
+func (r *businessCalendarRepository) CreateCalendar(calendar *models.BusinessCalendar) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if calendar.IsDefault {
+			if err := clearDefaultCalendar(tx, calendar.TenantID, uuid.Nil); err != nil {
+				return err
+			}
+		}
+		return tx.Omit("Holidays").Create(calendar).Error
+	})
+}
+
+// UpdateCalendar saves a calendar's settings. Holidays are managed separately.
+func (r *businessCalendarRepository) UpdateCalendar(calendar *models.BusinessCalendar) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if calendar.IsDefault {
+			if err := clearDefaultCalendar(tx, calendar.TenantID, calendar.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.BusinessCalendar{}).
+			Where("tenant_id = ? AND id = ?", calendar.TenantID, calendar.ID).
+			Updates(map[string]interface{}{
+				"name":          calendar.Name,
+				"region":        calendar.Region,
+				"timezone":      calendar.Timezone,
+				"is_default":    calendar.IsDefault,
+				"working_hours": calendar.WorkingHours,
+				"updated_by":    calendar.UpdatedBy,
+				"updated_at":    time.Now(),
+			}).Error
+	})
+}
+
+func clearDefaultCalendar(tx *gorm.DB, tenantID string, keepID uuid.UUID) error {
+	return tx.Model(&models.BusinessCalendar{}).
+		Where("tenant_id = ? AND is_default = ? AND id <> ?", tenantID, true, keepID).
+		Update("is_default", false).Error
+}
+
+// DeleteCalendar deletes a calendar and its holidays
+func (r *businessCalendarRepository) DeleteCalendar(tenantID string, id uuid.UUID) (bool, error) {
+	var deleted bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&models.BusinessCalendar{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		return tx.Where("calendar_id = ?", id).Delete(&models.BusinessHoliday{}).Error
+	})
+	return deleted, err
+}
+
+// ResolveCalendar returns the calendar that applies to a region: the tenant's calendar for
+// that region, else its default calendar, else its calendar without a region. Returns
+// gorm.ErrRecordNotFound when the tenant has none, in which case timers use wall-clock time.
+func (r *businessCalendarRepository) ResolveCalendar(tenantID, region string) (*models.BusinessCalendar, error) {
+	var calendar models.BusinessCalendar
+	err := r.db.Preload("Holidays", withHolidays).
+		Where("tenant_id = ?", tenantID).
+		Where("(region = ? AND ? <> '') OR is_default = ? OR COALESCE(region, '') = ''", region, region, true).
+		Order(gorm.Expr("CASE WHEN region = ? AND ? <> '' THEN 0 WHEN is_default THEN 1 ELSE 2 END, created_at ASC", region, region)).
+		First(&calendar).Error
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+// AddHoliday adds a holiday to a calendar
+func (r *businessCalendarRepository) AddHoliday(holiday *models.BusinessHoliday) error {
+	holiday.CreatedAt = time.Now()
+	return r.db.Create(holiday).Error
+}
+
+// DeleteHoliday removes a holiday from a calendar
+func (r *businessCalendarRepository) DeleteHoliday(calendarID, holidayID uuid.UUID) (bool, error) {
+	result := r.db.Where("calendar_id = ? AND id = ?", calendarID, holidayID).Delete(&models.BusinessHoliday{})
+	return result.RowsAffected > 0, result.Error
+}
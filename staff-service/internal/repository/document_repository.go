@@ -38,6 +38,13 @@ type DocumentRepository interface {
 	// Batch Operations
 	UpdateExpiredDocumentStatus(tenantID string, vendorID *string) (int64, error)
 	SendExpiryReminders(tenantID string, vendorID *string, daysAhead int) ([]models.StaffDocument, error)
+
+	// Expiry Compliance
+	GetComplianceDashboard(tenantID string, vendorID *string, daysAhead int) (*models.DocumentComplianceDashboard, error)
+	GetDocumentsDueForReminder(daysAhead int) ([]models.StaffDocument, error)
+	MarkReminderSent(id uuid.UUID, days int) error
+	ExpireLapsedDocuments() ([]models.StaffDocument, error)
+	RefreshStaffCompliance(tenantID string, staffID uuid.UUID) (models.DocumentComplianceState, bool, error)
 }
 
 type documentRepository struct {
@@ -81,9 +88,24 @@ func (r *documentRepository) GetDocumentByID(tenantID string, vendorID *string,
 }
 
 func (r *documentRepository) UpdateDocument(tenantID string, vendorID *string, id uuid.UUID, updates *models.UpdateDocumentRequest) error {
-	query := r.db.Model(&models.StaffDocument{}).Where("tenant_id = ? AND id = ?", tenantID, id)
-	query = r.applyVendorFilter(query, vendorID)
-	return query.Updates(updates).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.StaffDocument{}).Where("tenant_id = ? AND id = ?", tenantID, id)
+		query = r.applyVendorFilter(query, vendorID)
+		if err := query.Updates(updates).Error; err != nil {
+			return err
+		}
+		if updates.ExpiryDate == nil {
+			return nil
+		}
+
+		// A new expiry date starts the reminder schedule over
+		query = tx.Model(&models.StaffDocument{}).Where("tenant_id = ? AND id = ?", tenantID, id)
+		query = r.applyVendorFilter(query, vendorID)
+		return query.Updates(map[string]interface{}{
+			"reminder_sent_at":   nil,
+			"last_reminder_days": nil,
+		}).Error
+	})
 }
 
 func (r *documentRepository) DeleteDocument(tenantID string, vendorID *string, id uuid.UUID, deletedBy string) error {
@@ -462,6 +484,256 @@ func (r *documentRepository) SendExpiryReminders(tenantID string, vendorID *stri
 	return docs, nil
 }
 
+// ============================================================================
+// EXPIRY COMPLIANCE
+// ============================================================================
+
+// GetComplianceDashboard counts the tenant's documents by type and status, and its active
+// staff by compliance state
+func (r *documentRepository) GetComplianceDashboard(tenantID string, vendorID *string, daysAhead int) (*models.DocumentComplianceDashboard, error) {
+	now := time.Now()
+	dashboard := &models.DocumentComplianceDashboard{
+		ByStatus:           make(map[models.DocumentVerificationStatus]int64),
+		ByType:             make([]models.DocumentTypeComplianceSummary, 0),
+		ExpiringWithinDays: daysAhead,
+		GeneratedAt:        now,
+	}
+
+	documents := func() *gorm.DB {
+		query := r.db.Model(&models.StaffDocument{}).Where("tenant_id = ? AND deleted_at IS NULL", tenantID)
+		return r.applyVendorFilter(query, vendorID)
+	}
+
+	var statusCounts []struct {
+		DocumentType       models.StaffDocumentType
+		VerificationStatus models.DocumentVerificationStatus
+		Count              int64
+	}
+	if err := documents().
+		Select("document_type, verification_status, COUNT(*) AS count").
+		Group("document_type, verification_status").
+		Scan(&statusCounts).Error; err != nil {
+		return nil, err
+	}
+
+	var expiringCounts []struct {
+		DocumentType models.StaffDocumentType
+		Count        int64
+	}
+	if err := documents().
+		Select("document_type, COUNT(*) AS count").
+		Where("expiry_date IS NOT NULL AND expiry_date <= ? AND expiry_date > ? AND verification_status = ?",
+			now.AddDate(0, 0, daysAhead), now, models.VerificationVerified).
+		Group("document_type").
+		Scan(&expiringCounts).Error; err != nil {
+		return nil, err
+	}
+
+	// Staff with a document of the type that hasn't been rejected count as having submitted it
+	var submittedCounts []struct {
+		DocumentType models.StaffDocumentType
+		Count        int64
+	}
+	if err := documents().
+		Select("document_type, COUNT(DISTINCT staff_id) AS count").
+		Where("verification_status <> ?", models.VerificationRejected).
+		Group("document_type").
+		Scan(&submittedCounts).Error; err != nil {
+		return nil, err
+	}
+
+	var stateCounts []struct {
+		ComplianceState *models.DocumentComplianceState
+		Count           int64
+	}
+	staffQuery := r.db.Model(&models.Staff{}).
+		Where("tenant_id = ? AND is_active = ? AND deleted_at IS NULL", tenantID, true)
+	staffQuery = r.applyVendorFilter(staffQuery, vendorID)
+	if err := staffQuery.
+		Select("compliance_state, COUNT(*) AS count").
+		Group("compliance_state").
+		Scan(&stateCounts).Error; err != nil {
+		return nil, err
+	}
+	for _, sc := range stateCounts {
+		dashboard.TotalStaff += sc.Count
+		if sc.ComplianceState != nil && *sc.ComplianceState == models.ComplianceStateNonCompliant {
+			dashboard.NonCompliantStaff += sc.Count
+		} else {
+			dashboard.CompliantStaff += sc.Count
+		}
+	}
+
+	summaries := make(map[models.StaffDocumentType]*models.DocumentTypeComplianceSummary)
+	for _, info := range models.GetAllDocumentTypes() {
+		summaries[info.Type] = &models.DocumentTypeComplianceSummary{
+			DocumentType: info.Type,
+			DisplayName:  info.DisplayName,
+			IsMandatory:  info.IsMandatory,
+		}
+	}
+	summaryFor := func(docType models.StaffDocumentType) *models.DocumentTypeComplianceSummary {
+		if summary, ok := summaries[docType]; ok {
+			return summary
+		}
+		info := models.GetDocumentTypeInfo(docType)
+		summaries[docType] = &models.DocumentTypeComplianceSummary{DocumentType: docType, DisplayName: info.DisplayName}
+		return summaries[docType]
+	}
+
+	for _, sc := range statusCounts {
+		summary := summaryFor(sc.DocumentType)
+		summary.Total += sc.Count
+		switch sc.VerificationStatus {
+		case models.VerificationPending, models.VerificationUnderReview:
+			summary.Pending += sc.Count
+		case models.VerificationVerified:
+			summary.Verified += sc.Count
+		case models.VerificationRejected:
+			summary.Rejected += sc.Count
+		case models.VerificationExpired, models.VerificationRequiresUpdate:
+			summary.Expired += sc.Count
+		}
+		dashboard.ByStatus[sc.VerificationStatus] += sc.Count
+		dashboard.TotalDocuments += sc.Count
+	}
+	for _, ec := range expiringCounts {
+		summaryFor(ec.DocumentType).ExpiringSoon = ec.Count
+	}
+
+	submitted := make(map[models.StaffDocumentType]int64)
+	for _, sc := range submittedCounts {
+		submitted[sc.DocumentType] = sc.Count
+	}
+	for _, info := range models.GetAllDocumentTypes() {
+		summary := summaries[info.Type]
+		if info.IsMandatory && dashboard.TotalStaff > submitted[info.Type] {
+			summary.Missing = dashboard.TotalStaff - submitted[info.Type]
+		}
+		dashboard.ByType = append(dashboard.ByType, *summary)
+		delete(summaries, info.Type)
+	}
+	// Types stored in the database but unknown to this version
+	for _, summary := range summaries {
+		dashboard.ByType = append(dashboard.ByType, *summary)
+	}
+
+	return dashboard, nil
+}
+
+// GetDocumentsDueForReminder returns verified documents of all tenants expiring within
+// daysAhead, with the staff member and their manager loaded
+func (r *documentRepository) GetDocumentsDueForReminder(daysAhead int) ([]models.StaffDocument, error) {
+	var docs []models.StaffDocument
+	now := time.Now()
+
+	err := r.db.
+		Where("expiry_date IS NOT NULL AND expiry_date <= ? AND expiry_date > ? AND verification_status = ? AND deleted_at IS NULL",
+			now.AddDate(0, 0, daysAhead), now, models.VerificationVerified).
+		Preload("Staff").
+		Preload("Staff.Manager").
+		Order("tenant_id, expiry_date ASC").
+		Find(&docs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range docs {
+		r.enrichDocumentWithExpiryInfo(&docs[i])
+	}
+	return docs, nil
+}
+
+// MarkReminderSent records that the reminder for the given threshold was sent
+func (r *documentRepository) MarkReminderSent(id uuid.UUID, days int) error {
+	return r.db.Model(&models.StaffDocument{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"reminder_sent_at":   time.Now(),
+			"last_reminder_days": days,
+		}).Error
+}
+
+// ExpireLapsedDocuments marks verified documents of all tenants whose expiry date has passed
+// as expired and returns them, with the staff member and their manager loaded
+func (r *documentRepository) ExpireLapsedDocuments() ([]models.StaffDocument, error) {
+	var docs []models.StaffDocument
+
+	err := r.db.
+		Where("expiry_date IS NOT NULL AND expiry_date < ? AND verification_status = ? AND deleted_at IS NULL",
+			time.Now(), models.VerificationVerified).
+		Preload("Staff").
+		Preload("Staff.Manager").
+		Find(&docs).Error
+	if err != nil || len(docs) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(docs))
+	for i := range docs {
+		ids[i] = docs[i].ID
+		docs[i].VerificationStatus = models.VerificationExpired
+	}
+	err = r.db.Model(&models.StaffDocument{}).
+		Where("id IN ? AND verification_status = ?", ids, models.VerificationVerified).
+		Updates(map[string]interface{}{
+			"verification_status": models.VerificationExpired,
+			"updated_at":          time.Now(),
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// RefreshStaffCompliance recomputes a staff member's compliance state. A staff member is
+// non-compliant while a mandatory document has expired and no verified, unexpired
+// document of the same type replaces it. It returns the state and whether it changed.
+func (r *documentRepository) RefreshStaffCompliance(tenantID string, staffID uuid.UUID) (models.DocumentComplianceState, bool, error) {
+	var docs []models.StaffDocument
+	if err := r.db.
+		Where("tenant_id = ? AND staff_id = ? AND deleted_at IS NULL", tenantID, staffID).
+		Find(&docs).Error; err != nil {
+		return "", false, err
+	}
+
+	now := time.Now()
+	lapsed := make(map[models.StaffDocumentType]bool)
+	valid := make(map[models.StaffDocumentType]bool)
+	for i := range docs {
+		doc := &docs[i]
+		switch {
+		case doc.VerificationStatus == models.VerificationExpired && doc.IsMandatoryDocument():
+			lapsed[doc.DocumentType] = true
+		case doc.VerificationStatus == models.VerificationVerified && (doc.ExpiryDate == nil || doc.ExpiryDate.After(now)):
+			valid[doc.DocumentType] = true
+		}
+	}
+
+	state := models.ComplianceStateCompliant
+	for docType := range lapsed {
+		if !valid[docType] {
+			state = models.ComplianceStateNonCompliant
+			break
+		}
+	}
+
+	updates := map[string]interface{}{
+		"compliance_state":     state,
+		"compliance_lapsed_at": nil,
+	}
+	if state == models.ComplianceStateNonCompliant {
+		updates["compliance_lapsed_at"] = now
+	}
+	result := r.db.Model(&models.Staff{}).
+		Where("tenant_id = ? AND id = ? AND compliance_state IS DISTINCT FROM ?", tenantID, staffID, state).
+		Updates(updates)
+	if result.Error != nil {
+		return "", false, result.Error
+	}
+	return state, result.RowsAffected > 0, nil
+}
+
 // ============================================================================
 // HELPERS
 // ============================================================================
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"staff-service/internal/models"
+)
+
+// ============================================================================
+// GROUP SYNC REPOSITORY INTERFACE
+// ============================================================================
+
+type GroupSyncRepository interface {
+	// Settings
+	GetSettings(tenantID string) (*models.GroupSyncSettings, error)
+	SaveSettings(settings *models.GroupSyncSettings) error
+	ListReconcileTenants() ([]string, error)
+	MarkReconciled(tenantID string, at time.Time) error
+
+	// Mappings
+	ListMappings(tenantID string) ([]models.KeycloakGroupMapping, error)
+	SaveMapping(mapping *models.KeycloakGroupMapping) error
+	DeleteMapping(tenantID string, id uuid.UUID) (bool, error)
+	GetDepartmentByCode(tenantID, code string) (*models.Department, error)
+	GetTeamByCode(tenantID string, departmentID uuid.UUID, code string) (*models.Team, error)
+
+	// Memberships
+	ListMemberships(tenantID string, staffID uuid.UUID) ([]models.StaffGroupMembership, error)
+	ListMembershipStaff(tenantID string) ([]models.Staff, error)
+	ReplaceMemberships(tenantID string, staffID uuid.UUID, memberships []models.StaffGroupMembership, setPrimary bool) error
+}
+
+type groupSyncRepository struct {
+	db *gorm.DB
+}
+
+// NewGroupSyncRepository creates a repository for Keycloak group sync settings, mappings and memberships
+func NewGroupSyncRepository(db *gorm.DB) GroupSyncRepository {
+	return &groupSyncRepository{db: db}
+}
+
+// GetSettings returns the tenant's group sync settings, or nil if the tenant has none
+func (r *groupSyncRepository) GetSettings(tenantID string) (*models.GroupSyncSettings, error) {
+	var settings models.GroupSyncSettings
+	err := r.db.Where("tenant_id = ?", tenantID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SaveSettings creates or replaces the tenant's group sync settings
+func (r *groupSyncRepository) SaveSettings(settings *models.GroupSyncSettings) error {
+	now := time.Now()
+	settings.UpdatedAt = now
+	if settings.CreatedAt.IsZero() {
+		settings.CreatedAt = now
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "reconcile_removals", "set_primary", "updated_by", "updated_at"}),
+	}).Create(settings).Error
+}
+
+// ListReconcileTenants returns the tenants with group sync and removal reconciliation enabled
+func (r *groupSyncRepository) ListReconcileTenants() ([]string, error) {
+	var tenantIDs []string
+	err := r.db.Model(&models.GroupSyncSettings{}).
+		Where("enabled = ? AND reconcile_removals = ?", true, true).
+		Pluck("tenant_id", &tenantIDs).Error
+	return tenantIDs, err
+}
+
+// MarkReconciled records when the tenant's memberships were last reconciled
+func (r *groupSyncRepository) MarkReconciled(tenantID string, at time.Time) error {
+	return r.db.Model(&models.GroupSyncSettings{}).
+		Where("tenant_id = ?", tenantID).
+		Update("last_reconciled_at", at).Error
+}
+
+// ListMappings returns the tenant's group mappings
+func (r *groupSyncRepository) ListMappings(tenantID string) ([]models.KeycloakGroupMapping, error) {
+	var mappings []models.KeycloakGroupMapping
+	err := r.db.Where("tenant_id = ?", tenantID).Order("group_path").Find(&mappings).Error
+	return mappings, err
+}
+
+// SaveMapping creates or replaces the mapping of a group path
+func (r *groupSyncRepository) SaveMapping(mapping *models.KeycloakGroupMapping) error {
+	now := time.Now()
+	mapping.UpdatedAt = now
+	if mapping.CreatedAt.IsZero() {
+		mapping.CreatedAt = now
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "group_path"}},
+		DoUpdates: clause.AssignmentColumns([]string{"department_code", "team_code", "priority", "updated_by", "updated_at"}),
+	}).Create(mapping).Error
+}
+
+// DeleteMapping removes a group mapping. Memberships derived from it are removed on the
+// member's next login or reconciliation.
+func (r *groupSyncRepository) DeleteMapping(tenantID string, id uuid.UUID) (bool, error) {
+	result := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&models.KeycloakGroupMapping{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetDepartmentByCode returns the tenant's active department with the code, preferring
+// tenant-level departments over vendor ones
+func (r *groupSyncRepository) GetDepartmentByCode(tenantID, code string) (*models.Department, error) {
+	var dept models.Department
+	err := r.db.
+		Where("tenant_id = ? AND code = ? AND is_active = ? AND deleted_at IS NULL", tenantID, code, true).
+		Order("vendor_id NULLS FIRST").
+		First(&dept).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dept, nil
+}
+
+// GetTeamByCode returns the department's active team with the code
+func (r *groupSyncRepository) GetTeamByCode(tenantID string, departmentID uuid.UUID, code string) (*models.Team, error) {
+	var team models.Team
+	err := r.db.
+		Where("tenant_id = ? AND department_id = ? AND code = ? AND is_active = ? AND deleted_at IS NULL", tenantID, departmentID, code, true).
+		First(&team).Error
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// ListMemberships returns a staff member's group memberships
+func (r *groupSyncRepository) ListMemberships(tenantID string, staffID uuid.UUID) ([]models.StaffGroupMembership, error) {
+	var memberships []models.StaffGroupMembership
+	err := r.db.Where("tenant_id = ? AND staff_id = ?", tenantID, staffID).Order("group_path").Find(&memberships).Error
+	return memberships, err
+}
+
+// ListMembershipStaff returns the staff members of the tenant that have group memberships
+func (r *groupSyncRepository) ListMembershipStaff(tenantID string) ([]models.Staff, error) {
+	var staff []models.Staff
+	err := r.db.
+		Where("tenant_id = ? AND id IN (?)", tenantID,
+			r.db.Model(&models.StaffGroupMembership{}).Select("staff_id").Where("tenant_id = ?", tenantID)).
+		Find(&staff).Error
+	return staff, err
+}
+
+// ReplaceMemberships makes memberships the staff member's complete set of group memberships.
+// With setPrimary, the staff member's department and team are set from the highest priority
+// membership, unless they were assigned manually (i.e. not from a previous membership).
+func (r *groupSyncRepository) ReplaceMemberships(tenantID string, staffID uuid.UUID, memberships []models.StaffGroupMembership, setPrimary bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var previous []models.StaffGroupMembership
+		if err := tx.Where("tenant_id = ? AND staff_id = ?", tenantID, staffID).Find(&previous).Error; err != nil {
+			return err
+		}
+
+		paths := make([]string, len(memberships))
+		for i := range memberships {
+			paths[i] = memberships[i].GroupPath
+		}
+		remove := tx.Where("tenant_id = ? AND staff_id = ?", tenantID, staffID)
+		if len(paths) > 0 {
+			remove = remove.Where("group_path NOT IN ?", paths)
+		}
+		if err := remove.Delete(&models.StaffGroupMembership{}).Error; err != nil {
+			return err
+		}
+
+		if len(memberships) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "staff_id"}, {Name: "group_path"}},
+				DoUpdates: clause.AssignmentColumns([]string{"department_id", "team_id", "priority", "source", "synced_at"}),
+			}).Create(&memberships).Error; err != nil {
+				return err
+			}
+		}
+
+		if !setPrimary {
+			return nil
+		}
+
+		var staff models.Staff
+		if err := tx.Select("id", "department_id", "team_uuid").
+			Where("tenant_id = ? AND id = ?", tenantID, staffID).
+			First(&staff).Error; err != nil {
+			return err
+		}
+
+		// Only a department that came from a group may be replaced by the sync
+		managed := staff.DepartmentID == nil || *staff.DepartmentID == ""
+		for _, m := range previous {
+			if staff.DepartmentID != nil && *staff.DepartmentID == m.DepartmentID.String() {
+				managed = true
+			}
+		}
+		if !managed {
+			return nil
+		}
+
+		updates := map[string]interface{}{
+			"department_id": nil,
+			"team_id":       nil,
+			"team_uuid":     nil,
+			"updated_at":    time.Now(),
+		}
+		if primary := primaryMembership(memberships); primary != nil {
+			updates["department_id"] = primary.DepartmentID.String()
+			if primary.TeamID != nil {
+				updates["team_id"] = primary.TeamID.String()
+				updates["team_uuid"] = *primary.TeamID
+			}
+		} else if len(previous) == 0 {
+			// Nothing was assigned by the sync, so there's nothing to clear
+			return nil
+		}
+		return tx.Model(&models.Staff{}).Where("tenant_id = ? AND id = ?", tenantID, staffID).Updates(updates).Error
+	})
+}
+
+// primaryMembership returns the highest priority membership, by group path on ties
+func primaryMembership(memberships []models.StaffGroupMembership) *models.StaffGroupMembership {
+	var primary *models.StaffGroupMembership
+	for i := range memberships {
+		m := &memberships[i]
+		if primary == nil || m.Priority > primary.Priority || (m.Priority == primary.Priority && m.GroupPath < primary.GroupPath) {
+			primary = m
+		}
+	}
+	return primary
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"staff-service/internal/models"
+)
+
+// ============================================================================
+// IMPERSONATION REPOSITORY INTERFACE
+// ============================================================================
+
+type ImpersonationRepository interface {
+	Create(session *models.ImpersonationSession) error
+	GetByID(tenantID string, id uuid.UUID) (*models.ImpersonationSession, error)
+	GetByIDGlobal(id uuid.UUID) (*models.ImpersonationSession, error)
+	GetActiveByHash(tokenHash string) (*models.ImpersonationSession, error)
+	List(tenantID string, filters models.ImpersonationSessionFilters, page, limit int) ([]models.ImpersonationSession, *models.PaginationInfo, error)
+	End(tenantID string, id uuid.UUID, endedBy string) (bool, error)
+	EndActiveForAdmin(tenantID string, adminStaffID uuid.UUID, endedBy string) ([]uuid.UUID, error)
+	RecordActions(actions []models.ImpersonationAction) error
+	ListActions(tenantID string, sessionID uuid.UUID, page, limit int) ([]models.ImpersonationAction, *models.PaginationInfo, error)
+}
+
+type impersonationRepository struct {
+	db *gorm.DB
+}
+
+// NewImpersonationRepository creates a repository for impersonation sessions and their audit trail
+func NewImpersonationRepository(db *gorm.DB) ImpersonationRepository {
+	return &impersonationRepository{db: db}
+}
+
+// Create stores a new impersonation session
+func (r *impersonationRepository) Create(session *models.ImpersonationSession) error {
+	return r.db.Create(session).Error
+}
+
+// GetByID returns one of the tenant's impersonation sessions, including ended ones
+func (r *impersonationRepository) GetByID(tenantID string, id uuid.UUID) (*models.ImpersonationSession, error) {
+	var session models.ImpersonationSession
+	if err := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetByIDGlobal returns an impersonation session without tenant filtering, for internal
+// callers that only know the session ID
+func (r *impersonationRepository) GetByIDGlobal(id uuid.UUID) (*models.ImpersonationSession, error) {
+	var session models.ImpersonationSession
+	if err := r.db.Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetActiveByHash returns the unended, unexpired session whose token has the hash
+func (r *impersonationRepository) GetActiveByHash(tokenHash string) (*models.ImpersonationSession, error) {
+	var session models.ImpersonationSession
+	err := r.db.
+		Where("token_hash = ? AND ended_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+		First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// List returns the tenant's impersonation sessions, newest first
+func (r *impersonationRepository) List(tenantID string, filters models.ImpersonationSessionFilters, page, limit int) ([]models.ImpersonationSession, *models.PaginationInfo, error) {
+	query := r.db.Model(&models.ImpersonationSession{}).Where("tenant_id = ?", tenantID)
+	if filters.ActiveOnly {
+		query = query.Where("ended_at IS NULL AND expires_at > ?", time.Now())
+	}
+	if filters.AdminStaffID != nil {
+		query = query.Where("admin_staff_id = ?", *filters.AdminStaffID)
+	}
+	if filters.VendorID != nil && *filters.VendorID != "" {
+		query = query.Where("target_vendor_id = ?", *filters.VendorID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var sessions []models.ImpersonationSession
+	err := query.Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&sessions).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return sessions, r.buildPagination(page, limit, total), nil
+}
+
+// End ends an active session. It reports whether one was found.
+func (r *impersonationRepository) End(tenantID string, id uuid.UUID, endedBy string) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&models.ImpersonationSession{}).
+		Where("tenant_id = ? AND id = ? AND ended_at IS NULL AND expires_at > ?", tenantID, id, now).
+		Updates(map[string]interface{}{
+			"ended_at":   now,
+			"ended_by":   endedBy,
+			"updated_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// EndActiveForAdmin ends the admin's active sessions and returns their IDs
+func (r *impersonationRepository) EndActiveForAdmin(tenantID string, adminStaffID uuid.UUID, endedBy string) ([]uuid.UUID, error) {
+	now := time.Now()
+	var ids []uuid.UUID
+	err := r.db.Model(&models.ImpersonationSession{}).
+		Where("tenant_id = ? AND admin_staff_id = ? AND ended_at IS NULL AND expires_at > ?", tenantID, adminStaffID, now).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	err = r.db.Model(&models.ImpersonationSession{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"ended_at":   now,
+			"ended_by":   endedBy,
+			"updated_at": now,
+		}).Error
+	return ids, err
+}
+
+// RecordActions stores reported actions and updates each session's action count
+func (r *impersonationRepository) RecordActions(actions []models.ImpersonationAction) error {
+	if len(actions) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&actions).Error; err != nil {
+			return err
+		}
+
+		counts := make(map[uuid.UUID]int)
+		last := make(map[uuid.UUID]time.Time)
+		for _, action := range actions {
+			counts[action.SessionID]++
+			if action.OccurredAt.After(last[action.SessionID]) {
+				last[action.SessionID] = action.OccurredAt
+			}
+		}
+		for sessionID, count := range counts {
+			if err := tx.Model(&models.ImpersonationSession{}).
+				Where("id = ?", sessionID).
+				Updates(map[string]interface{}{
+					"action_count":   gorm.Expr("action_count + ?", count),
+					"last_action_at": gorm.Expr("GREATEST(COALESCE(last_action_at, ?), ?)", last[sessionID], last[sessionID]),
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListActions returns the actions taken in a session, oldest first
+func (r *impersonationRepository) ListActions(tenantID string, sessionID uuid.UUID, page, limit int) ([]models.ImpersonationAction, *models.PaginationInfo, error) {
+	query := r.db.Model(&models.ImpersonationAction{}).Where("tenant_id = ? AND session_id = ?", tenantID, sessionID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var actions []models.ImpersonationAction
+	err := query.Order("occurred_at, created_at").Offset((page - 1) * limit).Limit(limit).Find(&actions).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return actions, r.buildPagination(page, limit, total), nil
+}
+
+func (r *impersonationRepository) buildPagination(page, limit int, total int64) *models.PaginationInfo {
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	return &models.PaginationInfo{
+		Page:        page,
+		Limit:       limit,
+		Total:       total,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"staff-service/internal/models"
+)
+
+// ============================================================================
+// RETENTION REPOSITORY INTERFACE
+// ============================================================================
+
+type RetentionRepository interface {
+	// Policies
+	ListPolicies(tenantID string) ([]models.RetentionPolicy, error)
+	ListPoliciesForResource(resource string) ([]models.RetentionPolicy, error)
+	SavePolicy(policy *models.RetentionPolicy) error
+	DeletePolicy(tenantID, resource string) (bool, error)
+
+	// Purge run audit summaries
+	RecordPurgeRun(run *models.RetentionPurgeRun) error
+	ListPurgeRuns(tenantID, resource string, page, limit int) ([]models.RetentionPurgeRun, *models.PaginationInfo, error)
+
+	// Login audit purging
+	ListLoginAuditTenants(before time.Time) ([]string, error)
+	CountLoginAuditBefore(tenantID string, before time.Time) (int64, error)
+	DeleteLoginAuditBatch(tenantID string, before time.Time, batchSize int) (int64, error)
+}
+
+type retentionRepository struct {
+	db *gorm.DB
+}
+
+// NewRetentionRepository creates a repository for per-tenant retention policies and purge runs
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+// ListPolicies returns a tenant's retention overrides
+func (r *retentionRepository) ListPolicies(tenantID string) ([]models.RetentionPolicy, error) {
+	var policies []models.RetentionPolicy
+	err := r.db.Where("tenant_id = ?", tenantID).Find(&policies).Error
+	return policies, err
+}
+
+// ListPoliciesForResource returns every tenant's override for a resource
+func (r *retentionRepository) ListPoliciesForResource(resource string) ([]models.RetentionPolicy, error) {
+	var policies []models.RetentionPolicy
+	err := r.db.Where("resource = ?", resource).Find(&policies).Error
+	return policies, err
+}
+
+// SavePolicy creates or replaces a tenant's override for a resource
+func (r *retentionRepository) SavePolicy(policy *models.RetentionPolicy) error {
+	now := time.Now()
+	policy.UpdatedAt = now
+	if policy.CreatedAt.IsZero() {
+		policy.CreatedAt = now
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "resource"}},
+		DoUpdates: clause.AssignmentColumns([]string{"retention_days", "dry_run", "updated_by", "updated_at"}),
+	}).Create(policy).Error
+}
+
+// DeletePolicy removes a tenant's override so the resource default applies again
+func (r *retentionRepository) DeletePolicy(tenantID, resource string) (bool, error) {
+	result := r.db.Where("tenant_id = ? AND resource = ?", tenantID, resource).Delete(&models.RetentionPolicy{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RecordPurgeRun stores the audit summary of a purge
+func (r *retentionRepository) RecordPurgeRun(run *models.RetentionPurgeRun) error {
+	return r.db.Create(run).Error
+}
+
+// ListPurgeRuns returns a tenant's purge summaries, newest first
+func (r *retentionRepository) ListPurgeRuns(tenantID, resource string, page, limit int) ([]models.RetentionPurgeRun, *models.PaginationInfo, error) {
+	var runs []models.RetentionPurgeRun
+	var total int64
+
+	query := r.db.Model(&models.RetentionPurgeRun{}).Where("tenant_id = ?", tenantID)
+	if resource != "" {
+		query = query.Where("resource = ?", resource)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).
+		Order("completed_at DESC").
+		Find(&runs).Error; err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	pagination := &models.PaginationInfo{
+		Page:        page,
+		Limit:       limit,
+		Total:       total,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+
+	return runs, pagination, nil
+}
+
+// ListLoginAuditTenants returns tenants holding login audit records older than before
+func (r *retentionRepository) ListLoginAuditTenants(before time.Time) ([]string, error) {
+	var tenantIDs []string
+	err := r.db.Model(&models.StaffLoginAudit{}).
+		Where("attempted_at < ?", before).
+		Distinct().
+		Pluck("tenant_id", &tenantIDs).Error
+	return tenantIDs, err
+}
+
+// CountLoginAuditBefore counts a tenant's login audit records older than before
+func (r *retentionRepository) CountLoginAuditBefore(tenantID string, before time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.StaffLoginAudit{}).
+		Where("tenant_id = ? AND attempted_at < ?", tenantID, before).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteLoginAuditBatch deletes up to batchSize of a tenant's login audit records older than before.
+// Deleting in batches keeps each statement short so purges don't hold long locks.
+func (r *retentionRepository) DeleteLoginAuditBatch(tenantID string, before time.Time, batchSize int) (int64, error) {
+	batch := r.db.Model(&models.StaffLoginAudit{}).
+		Select("id").
+		Where("tenant_id = ? AND attempted_at < ?", tenantID, before).
+		Limit(batchSize)
+	result := r.db.Where("id IN (?)", batch).Delete(&models.StaffLoginAudit{})
+	return result.RowsAffected, result.Error
+}
@@ -1,11 +1,13 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"staff-service/internal/bulk"
 	"staff-service/internal/models"
 	"gorm.io/gorm"
 )
@@ -25,8 +27,8 @@ type StaffRepository interface {
 	Delete(tenantID string, id uuid.UUID, deletedBy string) error
 	List(tenantID string, filters *models.StaffFilters, page, limit int) ([]models.Staff, *models.PaginationInfo, error)
 	BulkCreate(tenantID string, staff []models.Staff) error
-	BulkCreateWithEmployeeIDs(tenantID, vendorID, businessCode string, staff []*models.Staff, skipDuplicates bool) (*models.BulkCreateResult, error)
-	BulkUpdate(tenantID string, updates []models.UpdateStaffRequest) error
+	BulkCreateWithEmployeeIDs(tenantID, vendorID, businessCode string, staff []*models.Staff, skipDuplicates bool, opts bulk.Options) *bulk.Response
+	BulkUpdate(tenantID string, updates []models.BulkUpdateStaffItem, opts bulk.Options) *bulk.Response
 	GetHierarchy(tenantID string, managerID *uuid.UUID) ([]models.Staff, error)
 	GetDirectReports(tenantID string, managerID uuid.UUID) ([]models.Staff, error)
 	Search(tenantID, query string, page, limit int) ([]models.Staff, *models.PaginationInfo, error)
@@ -65,8 +67,20 @@ type StaffRepository interface {
 
 	// Team role lookup
 	GetTeamDefaultRoleName(teamID uuid.UUID) (string, error)
+
+	// Skills and certifications
+	FindBySkills(tenantID string, skills, certifications []string, departmentID *string) ([]models.Staff, error)
+	GetSkillCatalog(tenantID string) (*models.SkillCatalog, error)
 }
 
+// heldEntryCondition matches staff whose skills or certifications JSONB column holds an
+// entry with the name (case-insensitive) that hasn't expired
+const heldEntryCondition = `EXISTS (
+	SELECT 1 FROM jsonb_array_elements(CASE WHEN jsonb_typeof(%[1]s) = 'array' THEN %[1]s ELSE '[]'::jsonb END) AS e
+	WHERE LOWER(e->>'name') = LOWER(?)
+	AND (COALESCE(e->>'expiresAt', '') = '' OR (e->>'expiresAt')::timestamptz > NOW())
+)`
+
 type staffRepository struct {
 	db *gorm.DB
 }
@@ -296,30 +310,6 @@ func (r *staffRepository) BulkCreate(tenantID string, staff []models.Staff) erro
 	return r.db.CreateInBatches(staff, 100).Error
 }
 
-func (r *staffRepository) BulkUpdate(tenantID string, updates []models.UpdateStaffRequest) error {
-	// This would need to be implemented based on specific requirements
-	// For now, we'll implement a simple version
-	tx := r.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	for _, update := range updates {
-		// Assuming each update has an ID field (not shown in the model)
-		// This would need to be adjusted based on actual implementation
-		if err := tx.Model(&models.Staff{}).
-			Where("tenant_id = ?", tenantID).
-			Updates(update).Error; err != nil {
-			tx.Rollback()
-			return err
-		}
-	}
-
-	return tx.Commit().Error
-}
-
 func (r *staffRepository) GetHierarchy(tenantID string, managerID *uuid.UUID) ([]models.Staff, error) {
 	var staff []models.Staff
 	query := r.db.Where("tenant_id = ?", tenantID)
@@ -490,10 +480,12 @@ func (r *staffRepository) applyFilters(query *gorm.DB, filters *models.StaffFilt
 		query = query.Where("last_login_at <= ?", *filters.LastLoginTo)
 	}
 
-	if len(filters.Skills) > 0 {
-		for _, skill := range filters.Skills {
-			query = query.Where("skills @> ?", fmt.Sprintf(`[{"name": "%s"}]`, skill))
-		}
+	for _, skill := range filters.Skills {
+		query = query.Where(fmt.Sprintf(heldEntryCondition, "skills"), skill)
+	}
+
+	for _, certification := range filters.Certifications {
+		query = query.Where(fmt.Sprintf(heldEntryCondition, "certifications"), certification)
 	}
 
 	if len(filters.Tags) > 0 {
@@ -566,58 +558,28 @@ func (r *staffRepository) CreateWithEmployeeID(tenantID, vendorID, businessCode
 	return tx.Commit().Error
 }
 
-// BulkCreateWithEmployeeIDs creates multiple staff members with auto-generated employee IDs
-func (r *staffRepository) BulkCreateWithEmployeeIDs(tenantID, vendorID, businessCode string, staff []*models.Staff, skipDuplicates bool) (*models.BulkCreateResult, error) {
-	result := &models.BulkCreateResult{
-		Created: make([]*models.Staff, 0),
-		Errors:  make([]models.BulkCreateError, 0),
-	}
+// BulkCreateWithEmployeeIDs creates multiple staff members with auto-generated employee IDs.
+// Each member succeeds or fails on its own unless opts.Atomic is set.
+func (r *staffRepository) BulkCreateWithEmployeeIDs(tenantID, vendorID, businessCode string, staff []*models.Staff, skipDuplicates bool, opts bulk.Options) *bulk.Response {
+	return bulk.Run(r.db, len(staff), opts, func(tx *gorm.DB, i int) (bulk.Outcome, error) {
+		s := staff[i]
 
-	// Start a transaction
-	tx := r.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	for i, s := range staff {
-		// Check for duplicate email
+		// Check for duplicate email, including members created earlier in the batch
 		var existingCount int64
-		tx.Model(&models.Staff{}).Where("tenant_id = ? AND email = ? AND deleted_at IS NULL", tenantID, s.Email).Count(&existingCount)
-
+		if err := tx.Model(&models.Staff{}).Where("tenant_id = ? AND email = ? AND deleted_at IS NULL", tenantID, s.Email).Count(&existingCount).Error; err != nil {
+			return bulk.Outcome{}, err
+		}
 		if existingCount > 0 {
 			if skipDuplicates {
-				result.Skipped++
-				continue
-			} else {
-				result.Errors = append(result.Errors, models.BulkCreateError{
-					Index:   i,
-					Code:    "DUPLICATE_EMAIL",
-					Message: fmt.Sprintf("Staff with email '%s' already exists", s.Email),
-				})
-				result.Failed++
-				continue
+				return bulk.Skipped(), nil
 			}
+			return bulk.Outcome{}, bulk.Fail("DUPLICATE_EMAIL", "Staff with email '%s' already exists", s.Email)
 		}
 
 		// Generate employee ID
 		var employeeID string
-		err := tx.Raw(
-			"SELECT generate_employee_id($1, $2, $3)",
-			tenantID,
-			vendorID,
-			businessCode,
-		).Scan(&employeeID).Error
-
-		if err != nil {
-			result.Errors = append(result.Errors, models.BulkCreateError{
-				Index:   i,
-				Code:    "EMPLOYEE_ID_ERROR",
-				Message: fmt.Sprintf("Failed to generate employee ID: %s", err.Error()),
-			})
-			result.Failed++
-			continue
+		if err := tx.Raw("SELECT generate_employee_id($1, $2, $3)", tenantID, vendorID, businessCode).Scan(&employeeID).Error; err != nil {
+			return bulk.Outcome{}, bulk.Fail("EMPLOYEE_ID_ERROR", "Failed to generate employee ID: %s", err.Error())
 		}
 
 		// Set staff fields
@@ -629,27 +591,59 @@ func (r *staffRepository) BulkCreateWithEmployeeIDs(tenantID, vendorID, business
 		s.CreatedAt = time.Now()
 		s.UpdatedAt = time.Now()
 
-		// Create staff record
 		if err := tx.Create(s).Error; err != nil {
-			result.Errors = append(result.Errors, models.BulkCreateError{
-				Index:   i,
-				Code:    "CREATE_ERROR",
-				Message: err.Error(),
-			})
-			result.Failed++
-			continue
+			return bulk.Outcome{}, bulk.Fail("CREATE_ERROR", "%s", err.Error())
 		}
+		return bulk.Created(s), nil
+	})
+}
 
-		result.Created = append(result.Created, s)
-		result.Success++
-	}
+// BulkUpdate applies each item's changes to the staff member it names. Each item succeeds or
+// fails on its own unless opts.Atomic is set.
+func (r *staffRepository) BulkUpdate(tenantID string, updates []models.BulkUpdateStaffItem, opts bulk.Options) *bulk.Response {
+	return bulk.Run(r.db, len(updates), opts, func(tx *gorm.DB, i int) (bulk.Outcome, error) {
+		update := &updates[i]
 
-	// Commit the transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
+		var existing models.Staff
+		if err := tx.Where("tenant_id = ? AND id = ?", tenantID, update.ID).First(&existing).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return bulk.Outcome{}, bulk.Fail("NOT_FOUND", "Staff member %s not found", update.ID)
+			}
+			return bulk.Outcome{}, err
+		}
+
+		if update.Email != nil && *update.Email != existing.Email {
+			var conflicts int64
+			if err := tx.Model(&models.Staff{}).
+				Where("tenant_id = ? AND email = ? AND id <> ? AND deleted_at IS NULL", tenantID, *update.Email, update.ID).
+				Count(&conflicts).Error; err != nil {
+				return bulk.Outcome{}, err
+			}
+			if conflicts > 0 {
+				return bulk.Outcome{}, bulk.Fail("EMAIL_EXISTS", "Another staff member with email '%s' already exists", *update.Email)
+			}
+		}
+
+		if err := tx.Model(&models.Staff{}).
+			Where("tenant_id = ? AND id = ?", tenantID, update.ID).
+			Updates(&update.UpdateStaffRequest).Error; err != nil {
+			return bulk.Outcome{}, err
+		}
+		// Updates() skips nil pointers, so clearing the team needs its own update
+		if update.TeamID != nil && *update.TeamID == "" {
+			if err := tx.Model(&models.Staff{}).
+				Where("tenant_id = ? AND id = ?", tenantID, update.ID).
+				Update("team_uuid", nil).Error; err != nil {
+				return bulk.Outcome{}, err
+			}
+		}
 
-	return result, nil
+		var updated models.Staff
+		if err := tx.Where("tenant_id = ? AND id = ?", tenantID, update.ID).First(&updated).Error; err != nil {
+			return bulk.Outcome{}, err
+		}
+		return bulk.Updated(&updated), nil
+	})
 }
 
 // GetDepartmentByName retrieves a department by its name within a tenant/vendor scope
@@ -944,3 +938,46 @@ func (r *staffRepository) GetTeamDefaultRoleName(teamID uuid.UUID) (string, erro
 
 	return "", nil
 }
+
+// FindBySkills returns the tenant's active staff holding all the skills and unexpired
+// certifications, optionally within a department
+func (r *staffRepository) FindBySkills(tenantID string, skills, certifications []string, departmentID *string) ([]models.Staff, error) {
+	query := r.db.Model(&models.Staff{}).
+		Where("tenant_id = ? AND is_active = ? AND deleted_at IS NULL", tenantID, true)
+	if departmentID != nil && *departmentID != "" {
+		query = query.Where("department_id = ?", *departmentID)
+	}
+	query = r.applyFilters(query, &models.StaffFilters{Skills: skills, Certifications: certifications})
+
+	var staff []models.Staff
+	err := query.Order("first_name, last_name").Find(&staff).Error
+	return staff, err
+}
+
+// GetSkillCatalog returns the skill and certification names held by the tenant's active staff
+func (r *staffRepository) GetSkillCatalog(tenantID string) (*models.SkillCatalog, error) {
+	catalog := &models.SkillCatalog{
+		Skills:         []models.SkillCatalogEntry{},
+		Certifications: []models.SkillCatalogEntry{},
+	}
+
+	for column, entries := range map[string]*[]models.SkillCatalogEntry{
+		"skills":         &catalog.Skills,
+		"certifications": &catalog.Certifications,
+	} {
+		err := r.db.Raw(fmt.Sprintf(`
+			SELECT MIN(e->>'name') AS name, COUNT(DISTINCT s.id) AS staff_count
+			FROM staff s, jsonb_array_elements(CASE WHEN jsonb_typeof(s.%[1]s) = 'array' THEN s.%[1]s ELSE '[]'::jsonb END) AS e
+			WHERE s.tenant_id = ? AND s.is_active = TRUE AND s.deleted_at IS NULL
+			AND COALESCE(e->>'name', '') <> ''
+			AND (COALESCE(e->>'expiresAt', '') = '' OR (e->>'expiresAt')::timestamptz > NOW())
+			GROUP BY LOWER(e->>'name')
+			ORDER BY staff_count DESC, name`, column), tenantID).
+			Scan(entries).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return catalog, nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"staff-service/internal/clients"
+	"staff-service/internal/models"
+	"staff-service/internal/repository"
+)
+
+const documentComplianceInterval = 6 * time.Hour
+
+// DocumentComplianceWorker sends document expiry reminders to staff and their managers,
+// marks lapsed documents as expired and downgrades the compliance state of staff whose
+// mandatory documents have lapsed.
+type DocumentComplianceWorker struct {
+	repo     repository.DocumentRepository
+	notifier *clients.NotificationClient
+	logger   *logrus.Entry
+	stopCh   chan struct{}
+}
+
+// NewDocumentComplianceWorker creates a new document compliance worker
+func NewDocumentComplianceWorker(repo repository.DocumentRepository, notifier *clients.NotificationClient, logger *logrus.Entry) *DocumentComplianceWorker {
+	return &DocumentComplianceWorker{
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start runs a pass immediately and then every documentComplianceInterval
+func (w *DocumentComplianceWorker) Start() {
+	ticker := time.NewTicker(documentComplianceInterval)
+	defer ticker.Stop()
+
+	w.RunOnce()
+	for {
+		select {
+		case <-ticker.C:
+			w.RunOnce()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *DocumentComplianceWorker) Stop() {
+	close(w.stopCh)
+}
+
+// RunOnce expires lapsed documents, then sends the reminders that are due
+func (w *DocumentComplianceWorker) RunOnce() {
+	ctx := context.Background()
+	w.expireLapsed(ctx)
+	w.sendReminders(ctx)
+}
+
+// expireLapsed marks lapsed documents as expired, notifies the staff member and their
+// manager, and refreshes the compliance state of staff with a lapsed mandatory document
+func (w *DocumentComplianceWorker) expireLapsed(ctx context.Context) {
+	docs, err := w.repo.ExpireLapsedDocuments()
+	if err != nil {
+		w.logger.WithError(err).Error("Failed to expire lapsed staff documents")
+		return
+	}
+
+	type staffKey struct {
+		tenantID string
+		staffID  uuid.UUID
+	}
+	affected := make(map[staffKey]bool)
+	for i := range docs {
+		doc := &docs[i]
+		w.notify(ctx, doc, 0, w.notifier.SendDocumentLapsed)
+		if doc.IsMandatoryDocument() {
+			affected[staffKey{doc.TenantID, doc.StaffID}] = true
+		}
+	}
+
+	for key := range affected {
+		state, changed, err := w.repo.RefreshStaffCompliance(key.tenantID, key.staffID)
+		if err != nil {
+			w.logger.WithError(err).WithFields(logrus.Fields{
+				"tenant_id": key.tenantID,
+				"staff_id":  key.staffID,
+			}).Error("Failed to refresh staff compliance state")
+			continue
+		}
+		if changed {
+			w.logger.WithFields(logrus.Fields{
+				"tenant_id": key.tenantID,
+				"staff_id":  key.staffID,
+				"state":     state,
+			}).Info("Staff compliance state changed after mandatory document lapsed")
+		}
+	}
+
+	if len(docs) > 0 {
+		w.logger.WithField("expired", len(docs)).Info("Expired lapsed staff documents")
+	}
+}
+
+// sendReminders sends each document's reminder for the smallest threshold it has reached,
+// unless that threshold (or a smaller one) was already sent
+func (w *DocumentComplianceWorker) sendReminders(ctx context.Context) {
+	docs, err := w.repo.GetDocumentsDueForReminder(models.DocumentExpiryReminderDays[0])
+	if err != nil {
+		w.logger.WithError(err).Error("Failed to load staff documents due for an expiry reminder")
+		return
+	}
+
+	sent := 0
+	for i := range docs {
+		doc := &docs[i]
+		daysUntil := daysUntilExpiry(*doc.ExpiryDate)
+		threshold := reminderThreshold(daysUntil)
+		if threshold == 0 || (doc.LastReminderDays != nil && *doc.LastReminderDays <= threshold) {
+			continue
+		}
+
+		if !w.notify(ctx, doc, daysUntil, w.notifier.SendDocumentExpiryReminder) {
+			continue
+		}
+		if err := w.repo.MarkReminderSent(doc.ID, threshold); err != nil {
+			w.logger.WithError(err).WithField("document_id", doc.ID).Error("Failed to record document expiry reminder")
+			continue
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		w.logger.WithField("sent", sent).Info("Sent staff document expiry reminders")
+	}
+}
+
+// notify sends the notification to the staff member and their manager. It reports whether
+// the staff member was notified; a failed manager notification is only logged.
+func (w *DocumentComplianceWorker) notify(ctx context.Context, doc *models.StaffDocument, daysUntil int,
+	send func(context.Context, *clients.DocumentExpiryNotification) error) bool {
+	if doc.Staff == nil {
+		return false
+	}
+
+	staffName := doc.Staff.FirstName + " " + doc.Staff.LastName
+	notification := &clients.DocumentExpiryNotification{
+		TenantID:       doc.TenantID,
+		StaffID:        doc.StaffID.String(),
+		RecipientEmail: doc.Staff.Email,
+		RecipientName:  staffName,
+		StaffName:      staffName,
+		DocumentType:   string(doc.DocumentType),
+		DocumentName:   doc.DocumentName,
+		ExpiryDate:     *doc.ExpiryDate,
+		DaysUntil:      daysUntil,
+		IsMandatory:    doc.IsMandatoryDocument(),
+	}
+
+	logger := w.logger.WithFields(logrus.Fields{
+		"tenant_id":   doc.TenantID,
+		"document_id": doc.ID,
+	})
+	if err := send(ctx, notification); err != nil {
+		logger.WithError(err).Warn("Failed to notify staff member about document expiry")
+		return false
+	}
+
+	if manager := doc.Staff.Manager; manager != nil && manager.IsActive {
+		managerNotification := *notification
+		managerNotification.RecipientEmail = manager.Email
+		managerNotification.RecipientName = manager.FirstName + " " + manager.LastName
+		managerNotification.IsManager = true
+		if err := send(ctx, &managerNotification); err != nil {
+			logger.WithError(err).Warn("Failed to notify manager about document expiry")
+		}
+	}
+	return true
+}
+
+// reminderThreshold returns the smallest reminder threshold daysUntil has reached, or 0
+func reminderThreshold(daysUntil int) int {
+	threshold := 0
+	for _, days := range models.DocumentExpiryReminderDays {
+		if daysUntil <= days {
+			threshold = days
+		}
+	}
+	return threshold
+}
+
+// daysUntilExpiry counts whole calendar days from today to the expiry date
+func daysUntilExpiry(expiry time.Time) int {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	expiryDay := time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, time.UTC)
+	return int(expiryDay.Sub(today).Hours() / 24)
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"staff-service/internal/clients"
+	"staff-service/internal/models"
+)
+
+const (
+	// groupSyncLoginTTL skips re-syncing the same groups on every request of a session
+	groupSyncLoginTTL = 5 * time.Minute
+
+	groupReconcileInterval = 1 * time.Hour
+)
+
+// GroupSyncRepositoryInterface defines the repository methods needed for group sync
+type GroupSyncRepositoryInterface interface {
+	GetSettings(tenantID string) (*models.GroupSyncSettings, error)
+	ListReconcileTenants() ([]string, error)
+	MarkReconciled(tenantID string, at time.Time) error
+	ListMappings(tenantID string) ([]models.KeycloakGroupMapping, error)
+	GetDepartmentByCode(tenantID, code string) (*models.Department, error)
+	GetTeamByCode(tenantID string, departmentID uuid.UUID, code string) (*models.Team, error)
+	ListMemberships(tenantID string, staffID uuid.UUID) ([]models.StaffGroupMembership, error)
+	ListMembershipStaff(tenantID string) ([]models.Staff, error)
+	ReplaceMemberships(tenantID string, staffID uuid.UUID, memberships []models.StaffGroupMembership, setPrimary bool) error
+}
+
+// KeycloakGroupLookup reads a Keycloak user's group paths, for reconciliation
+type KeycloakGroupLookup interface {
+	GetUserGroupPaths(ctx context.Context, userID string) ([]string, error)
+}
+
+type groupSyncEntry struct {
+	groups   string
+	syncedAt time.Time
+}
+
+// EnableGroupSync turns on syncing Keycloak groups to department and team memberships.
+// lookup may be nil, in which case memberships are only synced on login and
+// ReconcileGroupMemberships is unavailable.
+func (s *KeycloakRoleSyncService) EnableGroupSync(repo GroupSyncRepositoryInterface, lookup KeycloakGroupLookup) {
+	s.groupMu.Lock()
+	defer s.groupMu.Unlock()
+	s.groupRepo = repo
+	s.groupLookup = lookup
+	s.groupSynced = make(map[string]groupSyncEntry)
+}
+
+// GroupSyncEnabled reports whether EnableGroupSync was called
+func (s *KeycloakRoleSyncService) GroupSyncEnabled() bool {
+	s.groupMu.Lock()
+	defer s.groupMu.Unlock()
+	return s.groupRepo != nil
+}
+
+// SyncGroupsForStaff syncs a staff member's memberships from the group paths in their token.
+// Groups without a mapping are ignored; memberships of mapped groups the user is no longer
+// in are removed. Tenants without group sync enabled are skipped.
+func (s *KeycloakRoleSyncService) SyncGroupsForStaff(ctx context.Context, tenantID string, staffID uuid.UUID, groups []string) error {
+	if !s.GroupSyncEnabled() {
+		return nil
+	}
+
+	sorted := append([]string(nil), groups...)
+	sort.Strings(sorted)
+	key := tenantID + ":" + staffID.String()
+	joined := strings.Join(sorted, ",")
+
+	s.groupMu.Lock()
+	entry, seen := s.groupSynced[key]
+	s.groupMu.Unlock()
+	if seen && entry.groups == joined && time.Since(entry.syncedAt) < groupSyncLoginTTL {
+		return nil
+	}
+
+	if _, err := s.applyGroups(tenantID, staffID, groups, models.GroupMembershipSourceLogin); err != nil {
+		return err
+	}
+
+	s.groupMu.Lock()
+	s.groupSynced[key] = groupSyncEntry{groups: joined, syncedAt: time.Now()}
+	s.groupMu.Unlock()
+	return nil
+}
+
+// ReconcileGroupMemberships re-reads the groups of every staff member of the tenant with
+// group memberships from Keycloak and removes the memberships of groups they have left.
+// Memberships of inactive staff and staff without a Keycloak user are removed.
+func (s *KeycloakRoleSyncService) ReconcileGroupMemberships(ctx context.Context, tenantID string) (*models.GroupReconcileResult, error) {
+	s.groupMu.Lock()
+	repo, lookup := s.groupRepo, s.groupLookup
+	s.groupMu.Unlock()
+	if repo == nil || lookup == nil {
+		return nil, fmt.Errorf("group reconciliation requires Keycloak admin credentials")
+	}
+
+	staffMembers, err := repo.ListMembershipStaff(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staff with group memberships: %w", err)
+	}
+
+	result := &models.GroupReconcileResult{TenantID: tenantID}
+	for _, staff := range staffMembers {
+		result.StaffChecked++
+
+		var groups []string
+		deleted := staff.DeletedAt != nil && staff.DeletedAt.Valid
+		if staff.IsActive && !deleted && staff.KeycloakUserID != nil && *staff.KeycloakUserID != "" {
+			groups, err = lookup.GetUserGroupPaths(ctx, *staff.KeycloakUserID)
+			if err != nil && !errors.Is(err, clients.ErrKeycloakUserNotFound) {
+				s.logger.WithError(err).WithFields(logrus.Fields{
+					"tenant_id": tenantID,
+					"staff_id":  staff.ID,
+				}).Warn("Failed to read Keycloak groups for reconciliation")
+				result.StaffFailed++
+				continue
+			}
+			if err != nil {
+				result.StaffUnlinked++
+			}
+		} else {
+			result.StaffUnlinked++
+		}
+
+		changed, err := s.applyGroups(tenantID, staff.ID, groups, models.GroupMembershipSourceReconcile)
+		if err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"tenant_id": tenantID,
+				"staff_id":  staff.ID,
+			}).Warn("Failed to reconcile group memberships")
+			result.StaffFailed++
+			continue
+		}
+		if changed {
+			result.StaffChanged++
+		}
+	}
+
+	if err := repo.MarkReconciled(tenantID, time.Now()); err != nil {
+		s.logger.WithError(err).WithField("tenant_id", tenantID).Warn("Failed to record group reconciliation")
+	}
+	return result, nil
+}
+
+// applyGroups replaces the staff member's memberships with those of the mapped groups.
+// It reports whether the memberships changed.
+func (s *KeycloakRoleSyncService) applyGroups(tenantID string, staffID uuid.UUID, groups []string, source string) (bool, error) {
+	s.groupMu.Lock()
+	repo := s.groupRepo
+	s.groupMu.Unlock()
+
+	settings, err := repo.GetSettings(tenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get group sync settings: %w", err)
+	}
+	if settings == nil || !settings.Enabled {
+		return false, nil
+	}
+
+	mappings, err := repo.ListMappings(tenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get group mappings: %w", err)
+	}
+
+	now := time.Now()
+	desired := make(map[string]models.StaffGroupMembership)
+	for _, group := range groups {
+		mapping := matchGroupMapping(mappings, group)
+		if mapping == nil {
+			continue
+		}
+		if _, ok := desired[mapping.GroupPath]; ok {
+			continue
+		}
+
+		dept, err := repo.GetDepartmentByCode(tenantID, mapping.DepartmentCode)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.WithFields(logrus.Fields{
+				"tenant_id":       tenantID,
+				"group_path":      mapping.GroupPath,
+				"department_code": mapping.DepartmentCode,
+			}).Warn("Group mapping refers to an unknown department")
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to find department %s: %w", mapping.DepartmentCode, err)
+		}
+
+		membership := models.StaffGroupMembership{
+			TenantID:     tenantID,
+			StaffID:      staffID,
+			GroupPath:    mapping.GroupPath,
+			DepartmentID: dept.ID,
+			Priority:     mapping.Priority,
+			Source:       source,
+			SyncedAt:     now,
+		}
+		if mapping.TeamCode != nil && *mapping.TeamCode != "" {
+			team, err := repo.GetTeamByCode(tenantID, dept.ID, *mapping.TeamCode)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, fmt.Errorf("failed to find team %s: %w", *mapping.TeamCode, err)
+			}
+			if team != nil {
+				membership.TeamID = &team.ID
+			} else {
+				s.logger.WithFields(logrus.Fields{
+					"tenant_id":  tenantID,
+					"group_path": mapping.GroupPath,
+					"team_code":  *mapping.TeamCode,
+				}).Warn("Group mapping refers to an unknown team, syncing department only")
+			}
+		}
+		desired[mapping.GroupPath] = membership
+	}
+
+	current, err := repo.ListMemberships(tenantID, staffID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get group memberships: %w", err)
+	}
+	if sameMemberships(current, desired) {
+		return false, nil
+	}
+
+	memberships := make([]models.StaffGroupMembership, 0, len(desired))
+	for _, m := range desired {
+		memberships = append(memberships, m)
+	}
+	if err := repo.ReplaceMemberships(tenantID, staffID, memberships, settings.SetPrimary); err != nil {
+		return false, fmt.Errorf("failed to save group memberships: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"tenant_id":   tenantID,
+		"staff_id":    staffID,
+		"memberships": len(memberships),
+		"source":      source,
+	}).Info("Group memberships synced from Keycloak")
+	return true, nil
+}
+
+// matchGroupMapping returns the mapping of the group or of its closest mapped ancestor
+func matchGroupMapping(mappings []models.KeycloakGroupMapping, group string) *models.KeycloakGroupMapping {
+	var match *models.KeycloakGroupMapping
+	for i := range mappings {
+		path := strings.TrimSuffix(mappings[i].GroupPath, "/")
+		if group != path && !strings.HasPrefix(group, path+"/") {
+			continue
+		}
+		if match == nil || len(path) > len(match.GroupPath) {
+			match = &mappings[i]
+		}
+	}
+	return match
+}
+
+func sameMemberships(current []models.StaffGroupMembership, desired map[string]models.StaffGroupMembership) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	for _, c := range current {
+		d, ok := desired[c.GroupPath]
+		if !ok || d.DepartmentID != c.DepartmentID || d.Priority != c.Priority {
+			return false
+		}
+		if (d.TeamID == nil) != (c.TeamID == nil) || (d.TeamID != nil && *d.TeamID != *c.TeamID) {
+			return false
+		}
+	}
+	return true
+}
+
+// GroupReconcileWorker periodically reconciles group memberships of the tenants that
+// enabled removal reconciliation
+type GroupReconcileWorker struct {
+	syncService *KeycloakRoleSyncService
+	repo        GroupSyncRepositoryInterface
+	logger      *logrus.Entry
+	stopCh      chan struct{}
+}
+
+// NewGroupReconcileWorker creates a new group reconciliation worker
+func NewGroupReconcileWorker(syncService *KeycloakRoleSyncService, repo GroupSyncRepositoryInterface, logger *logrus.Entry) *GroupReconcileWorker {
+	return &GroupReconcileWorker{
+		syncService: syncService,
+		repo:        repo,
+		logger:      logger,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start runs a reconciliation immediately and then every groupReconcileInterval
+func (w *GroupReconcileWorker) Start() {
+	ticker := time.NewTicker(groupReconcileInterval)
+	defer ticker.Stop()
+
+	w.RunOnce()
+	for {
+		select {
+		case <-ticker.C:
+			w.RunOnce()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *GroupReconcileWorker) Stop() {
+	close(w.stopCh)
+}
+
+// RunOnce reconciles every tenant with removal reconciliation enabled
+func (w *GroupReconcileWorker) RunOnce() {
+	tenantIDs, err := w.repo.ListReconcileTenants()
+	if err != nil {
+		w.logger.WithError(err).Error("Failed to list tenants for group reconciliation")
+		return
+	}
+
+	for _, tenantID := range tenantIDs {
+		result, err := w.syncService.ReconcileGroupMemberships(context.Background(), tenantID)
+		if err != nil {
+			w.logger.WithError(err).WithField("tenant_id", tenantID).Error("Group reconciliation failed")
+			continue
+		}
+		w.logger.WithFields(logrus.Fields{
+			"tenant_id": tenantID,
+			"checked":   result.StaffChecked,
+			"changed":   result.StaffChanged,
+			"failed":    result.StaffFailed,
+			"unlinked":  result.StaffUnlinked,
+		}).Info("Group reconciliation finished")
+	}
+}
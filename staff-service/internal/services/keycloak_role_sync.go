@@ -67,7 +67,7 @@ var VendorRoleNames = map[string]bool{
 	"vendor_staff":   true,
 }
 
-// KeycloakRoleSyncService handles synchronization of Keycloak roles to staff-service
+// KeycloakRoleSyncService handles synchronization of Keycloak roles, and optionally groups, to staff-service
 type KeycloakRoleSyncService struct {
 	rbacRepo      RBACRepositoryInterface
 	staffRepo     StaffRepositoryInterface
@@ -75,6 +75,12 @@ type KeycloakRoleSyncService struct {
 	roleMappings  map[string]KeycloakRoleMapping // keycloakRole -> mapping
 	seededTenants map[string]bool                // track which tenants have been seeded
 	mu            sync.RWMutex
+
+	// Group sync (see keycloak_group_sync.go), enabled with EnableGroupSync
+	groupRepo   GroupSyncRepositoryInterface
+	groupLookup KeycloakGroupLookup
+	groupSynced map[string]groupSyncEntry // tenant:staff -> last synced groups
+	groupMu     sync.Mutex
 }
 
 // NewKeycloakRoleSyncService creates a new role sync service
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"staff-service/internal/models"
+	"staff-service/internal/repository"
+)
+
+const (
+	retentionPurgeInterval  = 6 * time.Hour
+	retentionPurgeBatchSize = 1000
+)
+
+// LoginAuditRetentionWorker enforces per-tenant login_audit retention policies.
+// Policies are read from this service's retention tables, the same ones other
+// services' purge workers fetch over the internal API.
+type LoginAuditRetentionWorker struct {
+	repo   repository.RetentionRepository
+	logger *logrus.Entry
+	stopCh chan struct{}
+}
+
+// NewLoginAuditRetentionWorker creates a new login audit retention worker
+func NewLoginAuditRetentionWorker(repo repository.RetentionRepository, logger *logrus.Entry) *LoginAuditRetentionWorker {
+	return &LoginAuditRetentionWorker{
+		repo:   repo,
+		logger: logger,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start runs a purge immediately and then every retentionPurgeInterval
+func (w *LoginAuditRetentionWorker) Start() {
+	ticker := time.NewTicker(retentionPurgeInterval)
+	defer ticker.Stop()
+
+	w.RunOnce()
+	for {
+		select {
+		case <-ticker.C:
+			w.RunOnce()
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *LoginAuditRetentionWorker) Stop() {
+	close(w.stopCh)
+}
+
+// RunOnce purges expired login audit records for every tenant and records a summary per tenant
+func (w *LoginAuditRetentionWorker) RunOnce() {
+	resource, _ := models.RetentionResourceFor(models.RetentionResourceLoginAudit)
+
+	overrides, err := w.repo.ListPoliciesForResource(resource.Resource)
+	if err != nil {
+		w.logger.WithError(err).Error("Failed to load login audit retention policies")
+		return
+	}
+	policies := models.RetentionPolicySet{
+		Resource:    resource.Resource,
+		DefaultDays: resource.DefaultDays,
+		Policies:    overrides,
+	}
+
+	now := time.Now().UTC()
+	tenantIDs, err := w.repo.ListLoginAuditTenants(now.AddDate(0, 0, -policies.ShortestDays()))
+	if err != nil {
+		w.logger.WithError(err).Error("Failed to list tenants with expired login audit records")
+		return
+	}
+
+	for _, tenantID := range tenantIDs {
+		days, dryRun := policies.PolicyFor(tenantID)
+		run := &models.RetentionPurgeRun{
+			TenantID:      tenantID,
+			Resource:      resource.Resource,
+			Service:       resource.Service,
+			RetentionDays: days,
+			Cutoff:        now.AddDate(0, 0, -days),
+			DryRun:        dryRun,
+			StartedAt:     time.Now().UTC(),
+		}
+
+		run.MatchedCount, err = w.repo.CountLoginAuditBefore(tenantID, run.Cutoff)
+		if err == nil && run.MatchedCount == 0 {
+			continue
+		}
+		if err == nil && !dryRun {
+			err = w.purge(run)
+		}
+
+		run.Status = models.RetentionRunCompleted
+		if err != nil {
+			run.Status = models.RetentionRunFailed
+			msg := err.Error()
+			run.Error = &msg
+		}
+		run.CompletedAt = time.Now().UTC()
+
+		if err := w.repo.RecordPurgeRun(run); err != nil {
+			w.logger.WithError(err).WithField("tenant_id", tenantID).Error("Failed to record login audit purge run")
+		}
+		w.logger.WithFields(logrus.Fields{
+			"tenant_id": tenantID,
+			"matched":   run.MatchedCount,
+			"deleted":   run.DeletedCount,
+			"dry_run":   dryRun,
+			"status":    run.Status,
+		}).Info("Login audit retention purge finished")
+	}
+}
+
+// purge deletes in batches until nothing older than the cutoff is left
+func (w *LoginAuditRetentionWorker) purge(run *models.RetentionPurgeRun) error {
+	for {
+		deleted, err := w.repo.DeleteLoginAuditBatch(run.TenantID, run.Cutoff, retentionPurgeBatchSize)
+		run.DeletedCount += deleted
+		if err != nil {
+			return err
+		}
+		if deleted < retentionPurgeBatchSize {
+			return nil
+		}
+	}
+}
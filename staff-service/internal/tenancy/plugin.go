@@ -0,0 +1,365 @@
+package tenancy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	tenantColumn = "tenant_id"
+	vendorColumn = "vendor_id"
+)
+
+// Modes
+const (
+	// ModeEnforce fails writes that are unscoped or cross into another tenant
+	ModeEnforce = "enforce"
+	// ModeAudit logs those writes but lets them through
+	ModeAudit = "audit"
+)
+
+var (
+	// ErrUnscopedWrite is returned for an update, delete or create on a tenant-owned table
+	// when neither the context, the WHERE clause nor the row itself names a tenant
+	ErrUnscopedWrite = errors.New("tenancy: unscoped write to tenant-owned table")
+	// ErrTenantMismatch is returned when a write would store a row under a different
+	// tenant or vendor than the one in the context
+	ErrTenantMismatch = errors.New("tenancy: row belongs to a different tenant or vendor")
+)
+
+// Plugin scopes statements to the tenant and vendor carried on their context.
+//
+//   - Queries on tables with a tenant_id (vendor_id) column get a matching condition.
+//   - Creates fill an empty tenant_id/vendor_id from the context and refuse rows of another tenant.
+//     Upserts only overwrite rows of the same tenant.
+//   - Updates and deletes are scoped like queries. Without a tenant in the context they must
+//     already carry a tenant_id condition or target a loaded row with its tenant set,
+//     otherwise they fail with ErrUnscopedWrite.
+//
+// Statements whose context is marked with WithCrossTenant are left alone. Raw SQL is not inspected.
+// In ModeAudit the writes that would fail are logged and let through, for rolling the plugin out
+// to a service whose background jobs haven't been checked yet.
+type Plugin struct {
+	// Mode is ModeEnforce (the default) or ModeAudit
+	Mode string
+}
+
+// FromEnv returns a plugin in the mode set by TENANCY_MODE. It defaults to audit: a service is
+// switched to enforce once its workers and sweepers run with WithCrossTenant and its
+// repositories pass the request context, which the audit warnings point out.
+func FromEnv() Plugin {
+	if mode := os.Getenv("TENANCY_MODE"); mode != "" {
+		return Plugin{Mode: mode}
+	}
+	return Plugin{Mode: ModeAudit}
+}
+
+// Name implements gorm.Plugin
+func (Plugin) Name() string {
+	return "tenancy"
+}
+
+// Initialize implements gorm.Plugin
+func (p Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Query().Before("gorm:query").Register("tenancy:scope_query", scopeRead); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenancy:scope_row", scopeRead); err != nil {
+		return err
+	}
+	if err := cb.Create().Before("gorm:create").Register("tenancy:scope_create", p.scopeCreate); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenancy:scope_update", p.scopeWrite); err != nil {
+		return err
+	}
+	return cb.Delete().Before("gorm:delete").Register("tenancy:scope_delete", p.scopeWrite)
+}
+
+// reject fails the statement with err, or only logs it in ModeAudit
+func (p Plugin) reject(db *gorm.DB, err error) {
+	if p.Mode == ModeAudit {
+		db.Logger.Warn(db.Statement.Context, "%v (audit mode, allowed)", err)
+		return
+	}
+	db.AddError(err)
+}
+
+func scopeRead(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || IsCrossTenant(stmt.Context) {
+		return
+	}
+
+	if tenantID, ok := TenantFromContext(stmt.Context); ok {
+		addCondition(stmt, tenantColumn, tenantID)
+	}
+	if vendorID, ok := VendorFromContext(stmt.Context); ok {
+		addCondition(stmt, vendorColumn, vendorID)
+	}
+}
+
+func (p Plugin) scopeCreate(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || IsCrossTenant(stmt.Context) {
+		return
+	}
+
+	if field := stmt.Schema.LookUpField(tenantColumn); field != nil {
+		tenantID, hasTenant := TenantFromContext(stmt.Context)
+		if err := stampRows(stmt, field, tenantID, !hasTenant); err != nil {
+			p.reject(db, err)
+			return
+		}
+		if hasTenant {
+			guardUpsert(stmt, tenantID)
+		}
+	}
+
+	if vendorID, ok := VendorFromContext(stmt.Context); ok {
+		if field := stmt.Schema.LookUpField(vendorColumn); field != nil {
+			if err := stampRows(stmt, field, vendorID, false); err != nil {
+				p.reject(db, err)
+			}
+		}
+	}
+}
+
+func (p Plugin) scopeWrite(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || IsCrossTenant(stmt.Context) {
+		return
+	}
+
+	if field := stmt.Schema.LookUpField(tenantColumn); field != nil {
+		// gorm refuses global updates/deletes on its own; don't turn one into a tenant-wide write
+		if !hasConditions(stmt) && !db.AllowGlobalUpdate {
+			return
+		}
+
+		if tenantID, ok := TenantFromContext(stmt.Context); ok {
+			if assigned := assignedValue(stmt, field); assigned != "" && assigned != tenantID {
+				p.reject(db, fmt.Errorf("%w: %s", ErrTenantMismatch, stmt.Schema.Table))
+				return
+			}
+			addCondition(stmt, tenantColumn, tenantID)
+		} else if rowTenant := rowValue(stmt, field); rowTenant != "" {
+			addCondition(stmt, tenantColumn, rowTenant)
+		} else if !mentionsColumn(whereExprs(stmt), tenantColumn) {
+			p.reject(db, fmt.Errorf("%w: %s", ErrUnscopedWrite, stmt.Schema.Table))
+			return
+		}
+	}
+
+	if vendorID, ok := VendorFromContext(stmt.Context); ok {
+		addCondition(stmt, vendorColumn, vendorID)
+	}
+}
+
+// addCondition adds "<table>.<column> = value" when the table has the column and the
+// statement doesn't carry that exact condition yet (statements can be executed twice, e.g. Count then Find)
+func addCondition(stmt *gorm.Statement, column, value string) {
+	if stmt.Schema.LookUpField(column) == nil {
+		return
+	}
+
+	cond := clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Value: value}
+	for _, expr := range whereExprs(stmt) {
+		if eq, ok := expr.(clause.Eq); ok && eq == cond {
+			return
+		}
+	}
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{cond}})
+}
+
+// guardUpsert limits ON CONFLICT DO UPDATE to rows of the same tenant, so Save's
+// create fallback can't overwrite another tenant's row with a colliding key
+func guardUpsert(stmt *gorm.Statement, tenantID string) {
+	c, ok := stmt.Clauses["ON CONFLICT"]
+	if !ok {
+		return
+	}
+	onConflict, ok := c.Expression.(clause.OnConflict)
+	if !ok || onConflict.DoNothing || len(onConflict.Where.Exprs) > 0 {
+		return
+	}
+
+	onConflict.Where = clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: tenantColumn}, Value: tenantID},
+	}}
+	c.Expression = onConflict
+	stmt.Clauses["ON CONFLICT"] = c
+}
+
+// stampRows fills an empty column with value on every row being created and rejects rows
+// that already hold a different value. With required set, rows left empty are an error.
+func stampRows(stmt *gorm.Statement, field *schema.Field, value string, required bool) error {
+	rv := stmt.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := stampRow(stmt, field, reflect.Indirect(rv.Index(i)), value, required); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return stampRow(stmt, field, rv, value, required)
+	}
+	return nil
+}
+
+func stampRow(stmt *gorm.Statement, field *schema.Field, row reflect.Value, value string, required bool) error {
+	current, isZero := field.ValueOf(stmt.Context, row)
+	if !isZero {
+		if value != "" && stringValue(current) != value {
+			return fmt.Errorf("%w: %s", ErrTenantMismatch, stmt.Schema.Table)
+		}
+		return nil
+	}
+
+	if value == "" {
+		if required {
+			return fmt.Errorf("%w: %s.%s is empty", ErrUnscopedWrite, stmt.Schema.Table, field.DBName)
+		}
+		return nil
+	}
+	if field.IndirectFieldType.Kind() != reflect.String {
+		return nil
+	}
+	return field.Set(stmt.Context, row, value)
+}
+
+// hasConditions reports whether an update/delete is restricted by a WHERE clause or by the
+// primary key of the row it was given
+func hasConditions(stmt *gorm.Statement) bool {
+	if len(whereExprs(stmt)) > 0 {
+		return true
+	}
+	if stmt.ReflectValue.Kind() != reflect.Struct {
+		return false
+	}
+	for _, pk := range stmt.Schema.PrimaryFields {
+		if _, isZero := pk.ValueOf(stmt.Context, stmt.ReflectValue); !isZero {
+			return true
+		}
+	}
+	return false
+}
+
+// rowValue returns the column value of the loaded row an update/delete targets, if any
+func rowValue(stmt *gorm.Statement, field *schema.Field) string {
+	if stmt.ReflectValue.Kind() != reflect.Struct {
+		return ""
+	}
+	if v, isZero := field.ValueOf(stmt.Context, stmt.ReflectValue); !isZero {
+		return stringValue(v)
+	}
+	return ""
+}
+
+// assignedValue returns the value an update is about to write into the column, if any
+func assignedValue(stmt *gorm.Statement, field *schema.Field) string {
+	switch dest := stmt.Dest.(type) {
+	case map[string]interface{}:
+		if v, ok := dest[field.DBName]; ok {
+			return stringValue(v)
+		}
+		if v, ok := dest[field.Name]; ok {
+			return stringValue(v)
+		}
+		return ""
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(stmt.Dest))
+	if rv.Kind() != reflect.Struct || rv.Type() != stmt.Schema.ModelType {
+		return ""
+	}
+	if v, isZero := field.ValueOf(stmt.Context, rv); !isZero {
+		return stringValue(v)
+	}
+	return ""
+}
+
+func whereExprs(stmt *gorm.Statement) []clause.Expression {
+	c, ok := stmt.Clauses["WHERE"]
+	if !ok {
+		return nil
+	}
+	if where, ok := c.Expression.(clause.Where); ok {
+		return where.Exprs
+	}
+	return nil
+}
+
+// mentionsColumn reports whether the ANDed conditions reference column. Every branch of an
+// OR has to reference it for the OR to count.
+func mentionsColumn(exprs []clause.Expression, column string) bool {
+	for _, expr := range exprs {
+		switch e := expr.(type) {
+		case clause.Eq:
+			if columnName(e.Column) == column {
+				return true
+			}
+		case clause.IN:
+			if columnName(e.Column) == column {
+				return true
+			}
+		case clause.Expr:
+			if strings.Contains(e.SQL, column) {
+				return true
+			}
+		case clause.NamedExpr:
+			if strings.Contains(e.SQL, column) {
+				return true
+			}
+		case clause.AndConditions:
+			if mentionsColumn(e.Exprs, column) {
+				return true
+			}
+		case clause.OrConditions:
+			all := len(e.Exprs) > 0
+			for _, branch := range e.Exprs {
+				all = all && mentionsColumn([]clause.Expression{branch}, column)
+			}
+			if all {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func columnName(column interface{}) string {
+	switch c := column.(type) {
+	case clause.Column:
+		return c.Name
+	case string:
+		if i := strings.LastIndex(c, "."); i >= 0 {
+			c = c[i+1:]
+		}
+		return strings.Trim(c, `"`)
+	}
+	return ""
+}
+
+func stringValue(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(rv.Interface())
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_import_mapping_presets_name;
+DROP TABLE IF EXISTS import_mapping_presets;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_retention_purge_runs_tenant;
+DROP TABLE IF EXISTS retention_purge_runs;
+DROP INDEX IF EXISTS idx_retention_policies_resource;
+DROP TABLE IF EXISTS retention_policies;
@@ -0,0 +1,35 @@
+-- Per-tenant data retention policies
+-- Tenants override the default retention of purgeable resources (abandoned carts,
+-- login audit, webhook events). Purge workers in the owning services enforce them
+-- and report one summary row per tenant and run to retention_purge_runs.
+
+CREATE TABLE IF NOT EXISTS retention_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    resource VARCHAR(50) NOT NULL,
+    retention_days INTEGER NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_retention_policies_resource ON retention_policies(tenant_id, resource);
+
+CREATE TABLE IF NOT EXISTS retention_purge_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    resource VARCHAR(50) NOT NULL,
+    service VARCHAR(100) NOT NULL,
+    retention_days INTEGER NOT NULL,
+    cutoff TIMESTAMP WITH TIME ZONE NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    matched_count BIGINT NOT NULL DEFAULT 0,
+    deleted_count BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL,
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_purge_runs_tenant ON retention_purge_runs(tenant_id, completed_at DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_staff_documents_expiry_status;
+DROP INDEX IF EXISTS idx_staff_compliance_state;
+ALTER TABLE staff DROP COLUMN IF EXISTS compliance_lapsed_at;
+ALTER TABLE staff DROP COLUMN IF EXISTS compliance_state;
+ALTER TABLE staff_documents DROP COLUMN IF EXISTS last_reminder_days;
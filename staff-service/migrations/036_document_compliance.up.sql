@@ -0,0 +1,13 @@
+-- Staff document expiry compliance
+-- Reminders are sent at fixed thresholds before a document expires; last_reminder_days
+-- records the smallest threshold already sent so each is sent once per expiry date.
+-- Staff whose mandatory documents lapse are downgraded to non_compliant until a
+-- replacement is verified.
+
+ALTER TABLE staff_documents ADD COLUMN IF NOT EXISTS last_reminder_days INTEGER;
+
+ALTER TABLE staff ADD COLUMN IF NOT EXISTS compliance_state VARCHAR(20) NOT NULL DEFAULT 'compliant';
+ALTER TABLE staff ADD COLUMN IF NOT EXISTS compliance_lapsed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_staff_compliance_state ON staff(tenant_id, compliance_state);
+CREATE INDEX IF NOT EXISTS idx_staff_documents_expiry_status ON staff_documents(expiry_date, verification_status) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS staff_group_memberships;
+DROP TABLE IF EXISTS keycloak_group_mappings;
+DROP TABLE IF EXISTS keycloak_group_sync_settings;
@@ -0,0 +1,48 @@
+-- Keycloak group to department/team sync
+-- Tenants map Keycloak group paths to departments (and optionally teams) by code.
+-- Memberships are synced from the groups claim on login; a scheduled job reconciles
+-- them against Keycloak so users who leave a group lose the membership.
+
+CREATE TABLE IF NOT EXISTS keycloak_group_sync_settings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    reconcile_removals BOOLEAN NOT NULL DEFAULT TRUE,
+    set_primary BOOLEAN NOT NULL DEFAULT TRUE,
+    last_reconciled_at TIMESTAMP WITH TIME ZONE,
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_keycloak_group_sync_settings_tenant ON keycloak_group_sync_settings(tenant_id);
+
+CREATE TABLE IF NOT EXISTS keycloak_group_mappings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    group_path VARCHAR(500) NOT NULL,
+    department_code VARCHAR(50) NOT NULL,
+    team_code VARCHAR(50),
+    priority INTEGER NOT NULL DEFAULT 0,
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_keycloak_group_mappings_path ON keycloak_group_mappings(tenant_id, group_path);
+
+CREATE TABLE IF NOT EXISTS staff_group_memberships (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    staff_id UUID NOT NULL REFERENCES staff(id) ON DELETE CASCADE,
+    group_path VARCHAR(500) NOT NULL,
+    department_id UUID NOT NULL REFERENCES departments(id) ON DELETE CASCADE,
+    team_id UUID REFERENCES teams(id) ON DELETE SET NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    source VARCHAR(20) NOT NULL,
+    synced_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_staff_group_memberships_group ON staff_group_memberships(tenant_id, staff_id, group_path);
+CREATE INDEX IF NOT EXISTS idx_staff_group_memberships_department ON staff_group_memberships(department_id);
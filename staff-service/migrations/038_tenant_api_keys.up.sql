@@ -0,0 +1,30 @@
+-- Tenant API keys for server-to-server integrations
+-- Keys are scoped to RBAC permission names and rate limited per key. Only the SHA-256
+-- hash of a key is stored. After a rotation the previous hash stays valid until
+-- previous_key_expires_at.
+
+CREATE TABLE IF NOT EXISTS tenant_api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    vendor_id VARCHAR(255),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    key_prefix VARCHAR(20) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    previous_key_hash VARCHAR(64),
+    previous_key_expires_at TIMESTAMP WITH TIME ZONE,
+    permissions JSONB NOT NULL DEFAULT '[]',
+    rate_limit_per_minute INTEGER NOT NULL DEFAULT 600,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_by VARCHAR(255),
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_api_keys_hash ON tenant_api_keys(key_hash);
+CREATE INDEX IF NOT EXISTS idx_tenant_api_keys_previous_hash ON tenant_api_keys(previous_key_hash) WHERE previous_key_hash IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tenant_api_keys_tenant ON tenant_api_keys(tenant_id, created_at DESC);
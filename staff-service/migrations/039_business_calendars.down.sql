@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS business_holidays;
+DROP TABLE IF EXISTS business_calendars;
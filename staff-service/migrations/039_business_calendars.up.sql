@@ -0,0 +1,32 @@
+-- Tenant business calendars: working hours and holidays per region
+-- tickets-service SLA timers and approval-service escalation timers count time only
+-- while the calendar that applies to them is open. Tenants without a calendar keep
+-- wall-clock timers.
+
+CREATE TABLE IF NOT EXISTS business_calendars (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    region VARCHAR(50),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    working_hours JSONB NOT NULL DEFAULT '[]'::jsonb,
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_business_calendars_name ON business_calendars(tenant_id, name);
+-- At most one default calendar per tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_business_calendars_default ON business_calendars(tenant_id) WHERE is_default;
+
+CREATE TABLE IF NOT EXISTS business_holidays (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    calendar_id UUID NOT NULL REFERENCES business_calendars(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    recurring BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_business_holidays_calendar_id ON business_holidays(calendar_id, date);
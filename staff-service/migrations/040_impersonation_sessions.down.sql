@@ -0,0 +1,6 @@
+DELETE FROM staff_role_permissions
+WHERE permission_id IN (SELECT id FROM staff_permissions WHERE name = 'team:impersonate');
+DELETE FROM staff_permissions WHERE name = 'team:impersonate';
+
+DROP TABLE IF EXISTS staff_impersonation_actions;
+DROP TABLE IF EXISTS staff_impersonation_sessions;
@@ -0,0 +1,66 @@
+-- Admin impersonation of vendors and staff
+-- A session is a time-limited grant for one admin to act as a vendor (vendor_id override) or
+-- as a staff member (their permissions and vendor). Only the SHA-256 hash of its token is
+-- stored. Services that honor impersonation report every request made with the token to
+-- staff_impersonation_actions; session start and end also go to the RBAC audit log.
+
+CREATE TABLE IF NOT EXISTS staff_impersonation_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    admin_staff_id UUID NOT NULL,
+    admin_user_id VARCHAR(255),
+    admin_name VARCHAR(255) NOT NULL,
+    admin_email VARCHAR(255) NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_vendor_id VARCHAR(255),
+    target_staff_id UUID,
+    target_name VARCHAR(255),
+    reason TEXT NOT NULL,
+    allow_writes BOOLEAN NOT NULL DEFAULT FALSE,
+    token_prefix VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    ended_by VARCHAR(255),
+    action_count INTEGER NOT NULL DEFAULT 0,
+    last_action_at TIMESTAMP WITH TIME ZONE,
+    ip_address VARCHAR(64),
+    user_agent TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_impersonation_sessions_token_hash ON staff_impersonation_sessions(token_hash);
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_tenant ON staff_impersonation_sessions(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_admin ON staff_impersonation_sessions(tenant_id, admin_staff_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_impersonation_sessions_vendor ON staff_impersonation_sessions(tenant_id, target_vendor_id);
+
+CREATE TABLE IF NOT EXISTS staff_impersonation_actions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES staff_impersonation_sessions(id) ON DELETE CASCADE,
+    tenant_id VARCHAR(255) NOT NULL,
+    service VARCHAR(100) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    status_code INTEGER,
+    blocked BOOLEAN NOT NULL DEFAULT FALSE,
+    ip_address VARCHAR(64),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_impersonation_actions_session ON staff_impersonation_actions(session_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_impersonation_actions_tenant ON staff_impersonation_actions(tenant_id);
+
+-- Starting a session needs an explicit grant of team:impersonate
+INSERT INTO staff_permissions (id, category_id, name, display_name, description, resource, action, is_sensitive, requires_2fa, sort_order, is_active)
+VALUES ('22222222-2222-2222-2222-222222220080', '11111111-1111-1111-1111-111111111107', 'team:impersonate', 'Impersonate Vendors and Staff', 'Act as a vendor or lower-ranked staff member for troubleshooting; every action is audited', 'impersonation', 'create', true, false, 20, true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO staff_role_permissions (role_id, permission_id, granted_by)
+SELECT sr.id, sp.id, 'system'
+FROM staff_roles sr
+CROSS JOIN staff_permissions sp
+WHERE sr.name IN ('store_owner', 'owner')
+  AND sp.name = 'team:impersonate'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,3 @@
+DELETE FROM staff_role_permissions
+WHERE permission_id IN (SELECT id FROM staff_permissions WHERE name = 'catalog:costs:view');
+DELETE FROM staff_permissions WHERE name = 'catalog:costs:view';
@@ -0,0 +1,12 @@
+-- Product cost prices, cost history and margins are only shown to staff holding catalog:costs:view
+INSERT INTO staff_permissions (id, category_id, name, display_name, description, resource, action, is_sensitive, requires_2fa, sort_order, is_active)
+VALUES ('22222222-2222-2222-2222-222222220081', '11111111-1111-1111-1111-111111111101', 'catalog:costs:view', 'View Costs and Margins', 'See product cost prices, cost history and gross margins on products and orders', 'costs', 'view', true, false, 12, true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO staff_role_permissions (role_id, permission_id, granted_by)
+SELECT sr.id, sp.id, 'system'
+FROM staff_roles sr
+CROSS JOIN staff_permissions sp
+WHERE sr.name IN ('store_owner', 'owner', 'store_admin', 'admin')
+  AND sp.name = 'catalog:costs:view'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,4 @@
+-- Migration: Remove persistent background jobs
+
+DROP TABLE IF EXISTS background_job_runs;
+DROP TABLE IF EXISTS background_jobs;
@@ -3,7 +3,10 @@ package main
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,7 +16,9 @@ import (
 	"tax-service/internal/database"
 	"tax-service/internal/events"
 	"tax-service/internal/handlers"
+	"tax-service/internal/middleware"
 	"tax-service/internal/repository"
+	"tax-service/internal/runtimeconfig"
 	"tax-service/internal/services"
 	"gorm.io/gorm"
 
@@ -25,16 +30,25 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
-	// Connect to database
-	db, err := config.InitDB(cfg)
+	// Connect to database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return config.InitDB(cfg) })
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	log.Println("✓ Connected to database")
 
-	// Run automated database migrations (schema + seed data)
-	if err := database.RunMigrations(db); err != nil {
-		log.Fatalf("Failed to run database migrations: %v", err)
+	// "tax-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Run the schema and seed migrations, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
 	}
 
 	// Initialize Redis client (graceful degradation if unavailable)
@@ -72,6 +86,7 @@ func main() {
 	taxRepo := repository.NewTaxRepository(db, redisClient)
 
 	// Initialize NATS events subscriber for automatic tax nexus provisioning
+	subscriberCh := make(chan *events.Subscriber, 1)
 	go func() {
 		subscriber, err := events.NewSubscriber(taxRepo, eventLogger)
 		if err != nil {
@@ -82,15 +97,24 @@ func main() {
 			log.Printf("WARNING: Failed to start events subscriber: %v", err)
 			return
 		}
+		subscriberCh <- subscriber
 		log.Println("✓ NATS events subscriber initialized (listening for tenant.created)")
 	}()
 
+	// Runtime config: tunables that can change without a rollout, from RUNTIME_CONFIG_FILE or
+	// overrides saved through /api/v1/admin/config
+	runtimeConfig := runtimeconfig.New("tax-service", redisClient, cfg.RuntimeConfigFile, eventLogger.WithField("component", "runtimeconfig"))
+	cacheTTL := runtimeConfig.Duration("tax.calculation_cache_ttl", "How long a tax calculation is reused for identical requests",
+		time.Duration(cfg.CacheTTLMinutes)*time.Minute)
+	runtimeConfigCtx, stopRuntimeConfig := context.WithCancel(context.Background())
+	go runtimeConfig.Start(runtimeConfigCtx)
+
 	// Initialize services
-	cacheTTL := time.Duration(cfg.CacheTTLMinutes) * time.Minute
-	taxCalculator := services.NewTaxCalculator(taxRepo, cacheTTL)
+	taxCalculator := services.NewTaxCalculator(taxRepo, cacheTTL.Get)
 
 	// Initialize handlers
 	taxHandler := handlers.NewTaxHandler(taxCalculator, taxRepo)
+	configHandler := runtimeconfig.NewHandler(runtimeConfig, cfg.ConfigAdminSecret)
 
 	// Initialize RBAC middleware
 	staffServiceURL := os.Getenv("STAFF_SERVICE_URL")
@@ -101,17 +125,56 @@ func main() {
 	log.Println("✓ RBAC middleware initialized")
 
 	// Setup router
-	router := setupRouter(taxHandler, db, rbacMiddleware, redisClient)
+	router := setupRouter(taxHandler, configHandler, db, rbacMiddleware, redisClient)
 
 	// Start server
-	log.Printf("Tax Service starting on port %s (env: %s)", cfg.Port, cfg.Environment)
-	if err := router.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: router,
+	}
+	go func() {
+		log.Printf("Tax Service starting on port %s (env: %s)", cfg.Port, cfg.Environment)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down tax-service...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting connections and let in-flight requests finish before closing NATS
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
+	stopRuntimeConfig()
+	select {
+	case subscriber := <-subscriberCh:
+		subscriber.Close()
+	default:
+	}
+	if publisher := events.GetPublisher(); publisher != nil {
+		publisher.Close()
 	}
+	if redisClient != nil {
+		redisClient.Close()
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+
+	log.Println("Tax service stopped")
 }
 
 // setupRouter configures the HTTP router
-func setupRouter(taxHandler *handlers.TaxHandler, db *gorm.DB, rbacMiddleware *rbac.Middleware, redisClient *redis.Client) *gin.Engine {
+func setupRouter(taxHandler *handlers.TaxHandler, configHandler *runtimeconfig.Handler, db *gorm.DB, rbacMiddleware *rbac.Middleware, redisClient *redis.Client) *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
@@ -119,9 +182,11 @@ func setupRouter(taxHandler *handlers.TaxHandler, db *gorm.DB, rbacMiddleware *r
 	// Security headers middleware
 	router.Use(gosharedmw.SecurityHeaders())
 
-	// Rate limiting middleware (uses Redis for distributed rate limiting)
+	// Rate limiting middleware (uses Redis for distributed rate limiting; profiles are managed in staff-service)
 	if redisClient != nil {
-		router.Use(gosharedmw.RedisRateLimitMiddlewareWithProfile(redisClient, "standard"))
+		rateLimiter := middleware.NewProfileRateLimiter(redisClient)
+		go rateLimiter.Start(context.Background())
+		router.Use(rateLimiter.Middleware())
 		log.Println("✓ Redis-based rate limiting enabled")
 	} else {
 		router.Use(gosharedmw.RateLimit())
@@ -214,6 +279,10 @@ func setupRouter(taxHandler *handlers.TaxHandler, db *gorm.DB, rbacMiddleware *r
 			exemptions.POST("", rbacMiddleware.RequirePermission(rbac.PermissionTaxCreate), taxHandler.CreateExemptionCertificate)
 			exemptions.PUT("/:id", rbacMiddleware.RequirePermission(rbac.PermissionTaxUpdate), taxHandler.UpdateExemptionCertificate)
 		}
+
+		// Runtime config - platform owners only, with requests signed by CONFIG_ADMIN_SECRET
+		admin := v1.Group("/admin", rbacMiddleware.RequireMinPriority(runtimeconfig.PlatformOwnerPriority))
+		configHandler.RegisterRoutes(admin)
 	}
 
 	return router
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
+	"tax-service/internal/tenancy"
+
 	"github.com/Tesseract-Nexus/go-shared/secrets")
 
 // Config holds application configuration
@@ -29,6 +32,12 @@ type Config struct {
 	Environment     string
 	LogLevel        string
 	CacheTTLMinutes int
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
+
+	// Runtime config: an optional JSON file of setting overrides, and the secret that signs
+	// requests to the config admin API (the API is disabled without one)
+	RuntimeConfigFile string
+	ConfigAdminSecret string
 }
 
 // Load creates a new configuration from environment variables
@@ -53,6 +62,10 @@ func Load() *Config {
 		Environment:     getEnv("ENVIRONMENT", "development"),
 		LogLevel:        getEnv("LOG_LEVEL", "info"),
 		CacheTTLMinutes: cacheTTLMinutes,
+		ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+
+		RuntimeConfigFile: getEnv("RUNTIME_CONFIG_FILE", ""),
+		ConfigAdminSecret: secrets.GetSecretOrEnv("CONFIG_ADMIN_SECRET_NAME", "CONFIG_ADMIN_SECRET", ""),
 	}
 }
 
@@ -75,6 +88,11 @@ func InitDB(cfg *Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
+	}
+
 	return db, nil
 }
 
@@ -89,3 +107,12 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
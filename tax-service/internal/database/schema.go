@@ -0,0 +1,17 @@
+package database
+
+import "gorm.io/gorm"
+
+// serviceName names the service in the migration lock key and log messages
+const serviceName = "tax-service"
+
+// BaselineVersion is the last file in migrations/ covered by RunMigrations, which builds the
+// schema and seeds the tax data from its embedded copies (tracked in migration_records). Only
+// files numbered after it are applied from SQL.
+const BaselineVersion = 5
+
+// SyncModels runs the schema and seed migrations. It must only run while holding the
+// migration lock, so replicas never reset or seed the tax tables concurrently.
+func SyncModels(db *gorm.DB) error {
+	return RunMigrations(db)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"tax-service/internal/contracts"
+	"tax-service/internal/models"
+)
+
+// ordersContractChecks verify tax-service against each contract orders-service keeps for it in
+// orders-service/contracts/tax-service. A new contract there needs a check here.
+var ordersContractChecks = map[string]func(t *testing.T, c *contracts.Contract){
+	"calculate": func(t *testing.T, c *contracts.Contract) {
+		var req models.CalculateTaxRequest
+		checkContractRequest(t, c, &req)
+
+		jurisdictionID := uuid.New()
+		checkContractResponse(t, c, models.TaxCalculationResponse{
+			Subtotal:       2998,
+			ShippingAmount: 99,
+			TaxAmount:      359.76,
+			Total:          3456.76,
+			TaxBreakdown: []models.TaxBreakdown{
+				{JurisdictionID: jurisdictionID, JurisdictionName: "Karnataka", TaxType: "CGST", Rate: 6, TaxableAmount: 2998, TaxAmount: 179.88, HSNCode: "6205"},
+				{JurisdictionID: jurisdictionID, JurisdictionName: "Karnataka", TaxType: "SGST", Rate: 6, TaxableAmount: 2998, TaxAmount: 179.88, HSNCode: "6205"},
+			},
+			GSTSummary: &models.GSTSummary{CGST: 179.88, SGST: 179.88, TotalGST: 359.76},
+			VATSummary: &models.VATSummary{VATAmount: 0, VATRate: 0, SellerVATNumber: "DE123456789"},
+		})
+	},
+}
+
+// TestOrdersServiceContracts checks tax-service still accepts what orders-service sends
+// and returns what it reads
+func TestOrdersServiceContracts(t *testing.T) {
+	all, err := contracts.Load(os.DirFS("../../../orders-service/contracts"), "tax-service")
+	if err != nil {
+		t.Fatalf("failed to load orders-service contracts: %v", err)
+	}
+	for i := range all {
+		contract := &all[i]
+		t.Run(contract.ID(), func(t *testing.T) {
+			check, ok := ordersContractChecks[contract.Name]
+			if !ok {
+				t.Fatalf("no tax-service check for %s", contract.ID())
+			}
+			check(t, contract)
+		})
+	}
+}
+
+func checkContractRequest(t *testing.T, c *contracts.Contract, req interface{}) {
+	t.Helper()
+	if err := c.DecodeRequest(req); err != nil {
+		t.Fatalf("request doesn't decode: %v", err)
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		t.Errorf("request fails validation: %v", err)
+	}
+}
+
+func checkContractResponse(t *testing.T, c *contracts.Contract, resp interface{}) {
+	t.Helper()
+	if err := c.VerifyResponse(resp); err != nil {
+		t.Errorf("response doesn't give orders-service what it expects: %v", err)
+	}
+}
@@ -15,11 +15,12 @@ import (
 // TaxCalculator handles tax calculation logic
 type TaxCalculator struct {
 	repo     *repository.TaxRepository
-	cacheTTL time.Duration
+	cacheTTL func() time.Duration
 }
 
-// NewTaxCalculator creates a new tax calculator
-func NewTaxCalculator(repo *repository.TaxRepository, cacheTTL time.Duration) *TaxCalculator {
+// NewTaxCalculator creates a new tax calculator. cacheTTL is read for every calculation, so
+// changes to the runtime setting apply without a restart.
+func NewTaxCalculator(repo *repository.TaxRepository, cacheTTL func() time.Duration) *TaxCalculator {
 	return &TaxCalculator{
 		repo:     repo,
 		cacheTTL: cacheTTL,
@@ -732,7 +733,7 @@ func (c *TaxCalculator) cacheResult(ctx context.Context, cacheKey string, respon
 	cache := &models.TaxCalculationCache{
 		CacheKey:          cacheKey,
 		CalculationResult: string(resultJSON),
-		ExpiresAt:         time.Now().Add(c.cacheTTL),
+		ExpiresAt:         time.Now().Add(c.cacheTTL()),
 	}
 
 	c.repo.CacheTaxCalculation(ctx, cache)
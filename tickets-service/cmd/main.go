@@ -1,19 +1,26 @@
 package main
 
 import (
+	"context"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 	"tickets-service/internal/clients"
 	"tickets-service/internal/config"
+	"tickets-service/internal/database"
 	"tickets-service/internal/events"
 	"tickets-service/internal/handlers"
 	"tickets-service/internal/middleware"
 	"tickets-service/internal/repository"
+	"tickets-service/internal/serviceauth"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"github.com/Tesseract-Nexus/go-shared/rbac"
@@ -56,12 +63,26 @@ func main() {
 	// Initialize configuration
 	cfg := config.Load()
 
-	// Initialize database
-	db, err := config.InitDB(cfg)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return config.InitDB(cfg) })
 	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to database")
 	}
 
+	// "tickets-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
+	}
+
 	// Initialize NATS events publisher
 	eventsPublisher, err := events.NewPublisher(log)
 	if err != nil {
@@ -74,13 +95,15 @@ func main() {
 	// Initialize notification and tenant clients for email notifications
 	notificationClient := clients.NewNotificationClient()
 	tenantClient := clients.NewTenantClient()
+	staffClient := clients.NewStaffClient()
+	calendarClient := clients.NewBusinessCalendarClient()
 	log.Info("Notification client initialized for direct API calls")
 
 	// Initialize repository
 	ticketsRepo := repository.NewTicketsRepository(db)
 
 	// Initialize handlers with events publisher for audit logging
-	ticketsHandler := handlers.NewTicketsHandler(ticketsRepo, notificationClient, tenantClient, eventsPublisher)
+	ticketsHandler := handlers.NewTicketsHandler(ticketsRepo, notificationClient, tenantClient, staffClient, calendarClient, eventsPublisher)
 	documentHandler := handlers.NewDocumentHandler(cfg.DocumentServiceURL, cfg.ProductID)
 
 	// Initialize Gin router
@@ -107,6 +130,15 @@ func main() {
 	router.GET("/health", handlers.HealthCheck)
 	router.GET("/ready", handlers.HealthCheck)
 
+	// Internal routes for service-to-service calls (no user JWT; tenant from X-Tenant-ID).
+	// Callers authenticate with their mesh identity or a service token.
+	internalAuth := serviceauth.New(serviceauth.ConfigFromEnv("tickets-service"), log)
+	internalRoutes := router.Group("/api/v1/internal", internalAuth.Require("orders-service"), middleware.TenantMiddleware())
+	{
+		// Automatic tickets - called by orders-service for stuck order escalations
+		internalRoutes.POST("/tickets", ticketsHandler.CreateTicketInternal)
+	}
+
 	// Protected API routes
 	api := router.Group("/api/v1")
 
@@ -136,6 +168,11 @@ func main() {
 			tickets.POST("/:id/assign", rbacMiddleware.RequirePermission(rbac.PermissionTicketsAssign), ticketsHandler.AssignTicket)
 			tickets.DELETE("/:id/assign/:assigneeId", rbacMiddleware.RequirePermission(rbac.PermissionTicketsAssign), ticketsHandler.UnassignTicket)
 
+			// Watchers
+			tickets.GET("/:id/watchers", rbacMiddleware.RequirePermission(rbac.PermissionTicketsRead), ticketsHandler.GetWatchers)
+			tickets.POST("/:id/watchers", rbacMiddleware.RequirePermission(rbac.PermissionTicketsUpdate), ticketsHandler.AddWatcher)
+			tickets.DELETE("/:id/watchers/:userId", rbacMiddleware.RequirePermission(rbac.PermissionTicketsUpdate), ticketsHandler.RemoveWatcher)
+
 			// Comments and attachments
 			tickets.POST("/:id/comments", rbacMiddleware.RequirePermission(rbac.PermissionTicketsUpdate), ticketsHandler.AddComment)
 			tickets.PUT("/:id/comments/:commentId", rbacMiddleware.RequirePermission(rbac.PermissionTicketsUpdate), ticketsHandler.UpdateComment)
@@ -167,6 +204,19 @@ func main() {
 			// Advanced queries
 			tickets.POST("/search", rbacMiddleware.RequirePermission(rbac.PermissionTicketsRead), ticketsHandler.SearchTickets)
 			tickets.GET("/:id/similar", rbacMiddleware.RequirePermission(rbac.PermissionTicketsRead), ticketsHandler.GetSimilarTickets)
+			tickets.GET("/:id/suggested-articles", rbacMiddleware.RequirePermission(rbac.PermissionTicketsRead), ticketsHandler.GetTicketSuggestedArticles)
+		}
+
+		// Knowledge base articles and suggestions for the ticket form
+		kb := v1.Group("/knowledge-base")
+		{
+			kb.POST("/suggest", rbacMiddleware.RequirePermission(rbac.PermissionTicketsCreate), ticketsHandler.SuggestArticles)
+			kb.GET("/articles", rbacMiddleware.RequirePermission(rbac.PermissionTicketsRead), ticketsHandler.ListArticles)
+			kb.GET("/articles/:id", rbacMiddleware.RequirePermission(rbac.PermissionTicketsRead), ticketsHandler.GetArticle)
+			kb.POST("/articles", rbacMiddleware.RequirePermission(rbac.PermissionTicketsUpdate), ticketsHandler.CreateArticle)
+			kb.PUT("/articles/:id", rbacMiddleware.RequirePermission(rbac.PermissionTicketsUpdate), ticketsHandler.UpdateArticle)
+			kb.DELETE("/articles/:id", rbacMiddleware.RequirePermission(rbac.PermissionTicketsUpdate), ticketsHandler.DeleteArticle)
+			kb.POST("/articles/:id/feedback", rbacMiddleware.RequirePermission(rbac.PermissionTicketsRead), ticketsHandler.RecordArticleFeedback)
 		}
 	}
 
@@ -179,8 +229,30 @@ func main() {
 		port = "8085"
 	}
 
-	log.WithField("port", port).Info("Tickets service starting")
-	if err := router.Run(":" + port); err != nil {
-		log.WithError(err).Fatal("Failed to start server")
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
 	}
+	go func() {
+		log.WithField("port", port).Info("Tickets service starting")
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Fatal("Failed to start server")
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Info("Shutting down tickets-service...")
+
+	// Stop accepting connections and let in-flight requests finish
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.WithError(err).Warn("Server forced to shutdown")
+	}
+
+	log.Info("Tickets service stopped")
 }
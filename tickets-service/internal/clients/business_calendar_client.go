@@ -0,0 +1,258 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"tickets-service/internal/serviceauth"
+)
+
+const (
+	// businessCalendarCacheTTL is how long a resolved calendar is reused before refetching
+	businessCalendarCacheTTL = 5 * time.Minute
+	// maxCalendarSpanDays bounds how far the business time calculations walk a calendar
+	maxCalendarSpanDays = 3 * 366
+)
+
+// BusinessCalendarClient fetches tenant business calendars from staff-service, where they are
+// managed, so timers count only working hours. Calendars are cached briefly per tenant and region.
+type BusinessCalendarClient struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedBusinessCalendar
+}
+
+type cachedBusinessCalendar struct {
+	calendar  *BusinessCalendar
+	fetchedAt time.Time
+}
+
+// WorkingHours is one working window on a weekday, in the calendar's timezone.
+type WorkingHours struct {
+	Weekday time.Weekday `json:"weekday"`
+	Start   string       `json:"start"`
+	End     string       `json:"end"`
+}
+
+// BusinessHoliday closes a calendar for a whole day, every year when recurring.
+type BusinessHoliday struct {
+	Date      time.Time `json:"date"`
+	Name      string    `json:"name"`
+	Recurring bool      `json:"recurring"`
+}
+
+// BusinessCalendar is a tenant's working hours and holidays for a region.
+type BusinessCalendar struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Region       string            `json:"region,omitempty"`
+	Timezone     string            `json:"timezone"`
+	WorkingHours []WorkingHours    `json:"workingHours"`
+	Holidays     []BusinessHoliday `json:"holidays"`
+}
+
+// NewBusinessCalendarClient creates a new business calendar client.
+func NewBusinessCalendarClient() *BusinessCalendarClient {
+	baseURL := os.Getenv("STAFF_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://staff-service:8080"
+	}
+
+	return &BusinessCalendarClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+		cache: make(map[string]cachedBusinessCalendar),
+	}
+}
+
+// Resolve returns the calendar that applies to a tenant's region, or its default calendar.
+// Returns nil without an error when the tenant has no calendar, meaning wall-clock time.
+func (c *BusinessCalendarClient) Resolve(ctx context.Context, tenantID, region string) (*BusinessCalendar, error) {
+	key := tenantID + "|" + region
+	c.mu.Lock()
+	cached, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < businessCalendarCacheTTL {
+		return cached.calendar, nil
+	}
+
+	reqURL := fmt.Sprintf("%s/api/v1/internal/business-calendars/resolve?region=%s", c.baseURL, url.QueryEscape(region))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	serviceauth.Sign(req, "tickets-service")
+	req.Header.Set("x-jwt-claim-tenant-id", tenantID)
+	req.Header.Set("X-Internal-Service", "tickets-service")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch business calendar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var calendar *BusinessCalendar
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var result struct {
+			Data BusinessCalendar `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode business calendar: %w", err)
+		}
+		calendar = &result.Data
+	case http.StatusNotFound:
+		// No calendar configured
+	default:
+		return nil, fmt.Errorf("staff-service returned status %d for business calendar", resp.StatusCode)
+	}
+
+	c.mu.Lock()
+	c.cache[key] = cachedBusinessCalendar{calendar: calendar, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return calendar, nil
+}
+
+// ElapsedBusinessMinutes counts the minutes between from and to that fall within working
+// hours, skipping holidays. A nil calendar counts every minute.
+func (cal *BusinessCalendar) ElapsedBusinessMinutes(from, to time.Time) int {
+	if !to.After(from) {
+		return 0
+	}
+	if cal == nil {
+		return int(to.Sub(from) / time.Minute)
+	}
+
+	loc := cal.location()
+	from, to = from.In(loc), to.In(loc)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+
+	var elapsed time.Duration
+	for i := 0; i <= maxCalendarSpanDays && day.Before(to); i++ {
+		for _, w := range cal.openWindows(day) {
+			start, end := w[0], w[1]
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				elapsed += end.Sub(start)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return int(elapsed / time.Minute)
+}
+
+// AddBusinessMinutes returns when the given business minutes after from will have elapsed.
+// A nil calendar adds wall-clock minutes.
+func (cal *BusinessCalendar) AddBusinessMinutes(from time.Time, minutes int) time.Time {
+	if cal == nil || minutes <= 0 {
+		return from.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	loc := cal.location()
+	from = from.In(loc)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	remaining := time.Duration(minutes) * time.Minute
+
+	for i := 0; i <= maxCalendarSpanDays; i++ {
+		for _, w := range cal.openWindows(day) {
+			start, end := w[0], w[1]
+			if start.Before(from) {
+				start = from
+			}
+			if !end.After(start) {
+				continue
+			}
+			open := end.Sub(start)
+			if open >= remaining {
+				return start.Add(remaining)
+			}
+			remaining -= open
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	// The calendar is never open; fall back to wall-clock time
+	return from.Add(time.Duration(minutes) * time.Minute)
+}
+
+func (cal *BusinessCalendar) location() *time.Location {
+	if loc, err := time.LoadLocation(cal.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+func (cal *BusinessCalendar) isHoliday(day time.Time) bool {
+	for _, h := range cal.Holidays {
+		if h.Date.Month() != day.Month() || h.Date.Day() != day.Day() {
+			continue
+		}
+		if h.Recurring || h.Date.Year() == day.Year() {
+			return true
+		}
+	}
+	return false
+}
+
+// openWindows returns the working windows on a local date as instants, in order with
+// overlapping windows merged
+func (cal *BusinessCalendar) openWindows(day time.Time) [][2]time.Time {
+	if cal.isHoliday(day) {
+		return nil
+	}
+	var windows [][2]time.Time
+	for _, h := range cal.WorkingHours {
+		if h.Weekday != day.Weekday() {
+			continue
+		}
+		start, err1 := parseClock(h.Start)
+		end, err2 := parseClock(h.End)
+		if err1 != nil || err2 != nil || end <= start {
+			continue
+		}
+		windows = append(windows, [2]time.Time{
+			time.Date(day.Year(), day.Month(), day.Day(), start/60, start%60, 0, 0, day.Location()),
+			time.Date(day.Year(), day.Month(), day.Day(), end/60, end%60, 0, 0, day.Location()),
+		})
+	}
+
+	sort.Slice(windows, func(i, j int) bool { return windows[i][0].Before(windows[j][0]) })
+	merged := windows[:0]
+	for _, w := range windows {
+		if n := len(merged); n > 0 && !w[0].After(merged[n-1][1]) {
+			if w[1].After(merged[n-1][1]) {
+				merged[n-1][1] = w[1]
+			}
+			continue
+		}
+		merged = append(merged, w)
+	}
+	return merged
+}
+
+// parseClock parses "HH:MM" into minutes after midnight, allowing "24:00"
+func parseClock(clock string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &hour, &minute); err != nil || len(clock) != 5 {
+		return 0, fmt.Errorf("invalid time %q", clock)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time %q", clock)
+	}
+	return hour*60 + minute, nil
+}
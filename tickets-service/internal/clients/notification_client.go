@@ -442,6 +442,39 @@ func (c *NotificationClient) SendTicketStatusUpdateNotification(ctx context.Cont
 	return nil
 }
 
+// SendTicketWatcherNotification emails a ticket watcher about an update they didn't make.
+// mentioned tells the watcher they were @mentioned in a comment.
+func (c *NotificationClient) SendTicketWatcherNotification(ctx context.Context, ticket *TicketNotification, watcherEmail, watcherName, update, updatedBy string, mentioned bool) error {
+	subject := fmt.Sprintf("Ticket #%s updated - %s", ticket.TicketNumber, update)
+	if mentioned {
+		subject = fmt.Sprintf("You were mentioned on Ticket #%s", ticket.TicketNumber)
+	}
+
+	req := SendNotificationRequest{
+		Channel:        "EMAIL",
+		RecipientEmail: watcherEmail,
+		Subject:        subject,
+		TemplateName:   "ticket_admin",
+		Variables: map[string]interface{}{
+			"ticketId":       ticket.TicketID,
+			"ticketNumber":   ticket.TicketNumber,
+			"ticketSubject":  ticket.Subject,
+			"description":    ticket.Description,
+			"ticketStatus":   ticket.Status,
+			"ticketPriority": ticket.Priority,
+			"email":          ticket.CustomerEmail,
+			"customerName":   ticket.CustomerName,
+			"watcherName":    watcherName,
+			"update":         update,
+			"updatedBy":      updatedBy,
+			"mentioned":      mentioned,
+			"ticketUrl":      ticket.TicketURL,
+		},
+	}
+
+	return c.send(ctx, ticket.TenantID, req)
+}
+
 func (c *NotificationClient) send(ctx context.Context, tenantID string, req SendNotificationRequest) error {
 	body, err := json.Marshal(req)
 	if err != nil {
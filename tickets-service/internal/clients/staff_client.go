@@ -0,0 +1,105 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"tickets-service/internal/serviceauth"
+)
+
+// ErrStaffNotFound is returned when no staff member has the given email
+var ErrStaffNotFound = errors.New("staff member not found")
+
+// StaffClient looks up staff members in staff-service, used to resolve @mentions
+type StaffClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// StaffMember is a staff member who can watch tickets
+type StaffMember struct {
+	ID             string  `json:"id"`
+	Email          string  `json:"email"`
+	FirstName      string  `json:"first_name"`
+	LastName       string  `json:"last_name"`
+	KeycloakUserID *string `json:"keycloak_user_id"`
+	IsActive       bool    `json:"is_active"`
+}
+
+// UserID returns the ID the staff member is authenticated as, which tickets record as userId
+func (s *StaffMember) UserID() string {
+	if s.KeycloakUserID != nil && *s.KeycloakUserID != "" {
+		return *s.KeycloakUserID
+	}
+	return s.ID
+}
+
+// Name returns the staff member's display name
+func (s *StaffMember) Name() string {
+	name := strings.TrimSpace(s.FirstName + " " + s.LastName)
+	if name == "" {
+		return s.Email
+	}
+	return name
+}
+
+// NewStaffClient creates a new staff client
+func NewStaffClient() *StaffClient {
+	baseURL := os.Getenv("STAFF_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://staff-service:8080"
+	}
+
+	return &StaffClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// GetStaffByEmail finds an active staff member of the tenant by email
+func (c *StaffClient) GetStaffByEmail(ctx context.Context, tenantID, email string) (*StaffMember, error) {
+	reqURL := fmt.Sprintf("%s/api/v1/internal/staff/by-email?email=%s", c.baseURL, url.QueryEscape(email))
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	serviceauth.Sign(httpReq, "tickets-service")
+
+	httpReq.Header.Set("x-jwt-claim-tenant-id", tenantID)
+	httpReq.Header.Set("X-Internal-Service", "tickets-service")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrStaffNotFound
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("staff-service returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Staff StaffMember `json:"staff"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !result.Data.Staff.IsActive {
+		return nil, ErrStaffNotFound
+	}
+	return &result.Data.Staff, nil
+}
@@ -2,11 +2,11 @@ package config
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"strconv"
+	"time"
 
-	"tickets-service/internal/models"
+	"tickets-service/internal/tenancy"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -23,8 +23,9 @@ type Config struct {
 	DBSSLMode  string
 
 	// Server
-	Port        string
-	Environment string
+	Port            string
+	Environment     string
+	ShutdownTimeout time.Duration // How long in-flight requests get to finish on SIGTERM
 
 	// JWT
 	JWTSecret string
@@ -65,8 +66,9 @@ func Load() *Config {
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
 
 		// Server
-		Port:        getEnv("PORT", "8085"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Port:            getEnv("PORT", "8085"),
+		Environment:     getEnv("ENVIRONMENT", "development"),
+		ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
 
 		// JWT
 		JWTSecret: secrets.GetJWTSecret(),
@@ -108,81 +110,26 @@ func InitDB(cfg *Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	// Run migration for ticket_number column
-	if err := migrateTicketNumber(db); err != nil {
-		log.Printf("Warning: ticket_number migration failed: %v", err)
-	}
-
-	// Auto-migrate models to keep schema in sync
-	if err := db.AutoMigrate(&models.Ticket{}); err != nil {
-		log.Printf("Warning: AutoMigrate failed: %v", err)
-		// Don't return error - table may already exist with correct schema
-	} else {
-		log.Println("Database schema synchronized")
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
 	}
 
 	return db, nil
 }
 
-// migrateTicketNumber handles migration for the ticket_number column
-// It adds the column as nullable, populates existing rows, then adds NOT NULL constraint
-func migrateTicketNumber(db *gorm.DB) error {
-	// Check if tickets table exists
-	if !db.Migrator().HasTable("tickets") {
-		log.Println("Tickets table does not exist yet, skipping ticket_number migration")
-		return nil
-	}
-
-	// Check if ticket_number column exists
-	if db.Migrator().HasColumn(&models.Ticket{}, "ticket_number") {
-		log.Println("ticket_number column already exists")
-		return nil
-	}
-
-	log.Println("Adding ticket_number column to tickets table...")
-
-	// Step 1: Add column as nullable first
-	if err := db.Exec("ALTER TABLE tickets ADD COLUMN IF NOT EXISTS ticket_number VARCHAR(20)").Error; err != nil {
-		return fmt.Errorf("failed to add ticket_number column: %w", err)
-	}
-
-	// Step 2: Populate existing rows with sequential ticket numbers per tenant
-	// Uses a window function to generate sequential numbers per tenant
-	updateSQL := `
-		WITH numbered AS (
-			SELECT id, tenant_id,
-				   'TKT-' || LPAD(ROW_NUMBER() OVER (PARTITION BY tenant_id ORDER BY created_at)::TEXT, 8, '0') as new_number
-			FROM tickets
-			WHERE ticket_number IS NULL
-		)
-		UPDATE tickets t
-		SET ticket_number = n.new_number
-		FROM numbered n
-		WHERE t.id = n.id
-	`
-	result := db.Exec(updateSQL)
-	if result.Error != nil {
-		return fmt.Errorf("failed to populate ticket_number: %w", result.Error)
-	}
-	log.Printf("Updated %d existing tickets with sequential numbers", result.RowsAffected)
-
-	// Step 3: Add NOT NULL constraint
-	if err := db.Exec("ALTER TABLE tickets ALTER COLUMN ticket_number SET NOT NULL").Error; err != nil {
-		return fmt.Errorf("failed to set ticket_number NOT NULL: %w", err)
-	}
-
-	// Step 4: Add unique index (tenant_id + ticket_number)
-	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_ticket_number_tenant ON tickets(tenant_id, ticket_number)").Error; err != nil {
-		log.Printf("Warning: failed to create unique index: %v", err)
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
 	}
-
-	log.Println("ticket_number migration completed successfully")
-	return nil
+	return defaultValue
 }
 
-func getEnv(key, defaultValue string) string {
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
-		return value
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
 	}
 	return defaultValue
 }
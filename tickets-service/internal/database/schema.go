@@ -0,0 +1,85 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	"tickets-service/internal/models"
+)
+
+// serviceName names the service in the migration lock key and log messages
+const serviceName = "tickets-service"
+
+// BaselineVersion is the last SQL migration folded into the model schema. Databases that
+// predate versioned migrations were built by AutoMigrate, and new databases get the same
+// schema from SyncModels, so only files numbered after it are applied from SQL.
+const BaselineVersion = 5
+
+// SyncModels brings the schema in line with the GORM models. It must only run while holding
+// the migration lock.
+func SyncModels(db *gorm.DB) error {
+	// Run migration for ticket_number column
+	if err := migrateTicketNumber(db); err != nil {
+		log.Printf("Warning: ticket_number migration failed: %v", err)
+	}
+
+	return db.AutoMigrate(&models.Ticket{}, &models.TicketWatcher{}, &models.KBArticle{})
+}
+
+// migrateTicketNumber handles migration for the ticket_number column
+// It adds the column as nullable, populates existing rows, then adds NOT NULL constraint
+func migrateTicketNumber(db *gorm.DB) error {
+	// Check if tickets table exists
+	if !db.Migrator().HasTable("tickets") {
+		log.Println("Tickets table does not exist yet, skipping ticket_number migration")
+		return nil
+	}
+
+	// Check if ticket_number column exists
+	if db.Migrator().HasColumn(&models.Ticket{}, "ticket_number") {
+		log.Println("ticket_number column already exists")
+		return nil
+	}
+
+	log.Println("Adding ticket_number column to tickets table...")
+
+	// Step 1: Add column as nullable first
+	if err := db.Exec("ALTER TABLE tickets ADD COLUMN IF NOT EXISTS ticket_number VARCHAR(20)").Error; err != nil {
+		return fmt.Errorf("failed to add ticket_number column: %w", err)
+	}
+
+	// Step 2: Populate existing rows with sequential ticket numbers per tenant
+	// Uses a window function to generate sequential numbers per tenant
+	updateSQL := `
+		WITH numbered AS (
+			SELECT id, tenant_id,
+				   'TKT-' || LPAD(ROW_NUMBER() OVER (PARTITION BY tenant_id ORDER BY created_at)::TEXT, 8, '0') as new_number
+			FROM tickets
+			WHERE ticket_number IS NULL
+		)
+		UPDATE tickets t
+		SET ticket_number = n.new_number
+		FROM numbered n
+		WHERE t.id = n.id
+	`
+	result := db.Exec(updateSQL)
+	if result.Error != nil {
+		return fmt.Errorf("failed to populate ticket_number: %w", result.Error)
+	}
+	log.Printf("Updated %d existing tickets with sequential numbers", result.RowsAffected)
+
+	// Step 3: Add NOT NULL constraint
+	if err := db.Exec("ALTER TABLE tickets ALTER COLUMN ticket_number SET NOT NULL").Error; err != nil {
+		return fmt.Errorf("failed to set ticket_number NOT NULL: %w", err)
+	}
+
+	// Step 4: Add unique index (tenant_id + ticket_number)
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_ticket_number_tenant ON tickets(tenant_id, ticket_number)").Error; err != nil {
+		log.Printf("Warning: failed to create unique index: %v", err)
+	}
+
+	log.Println("ticket_number migration completed successfully")
+	return nil
+}
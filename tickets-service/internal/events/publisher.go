@@ -152,8 +152,9 @@ func (p *Publisher) PublishTicketUnassigned(ctx context.Context, tenantID, ticke
 	return p.publisher.Publish(ctx, event)
 }
 
-// PublishTicketCommentAdded publishes a ticket comment added event
-func (p *Publisher) PublishTicketCommentAdded(ctx context.Context, tenantID, ticketID, ticketNumber, subject, commentContent string, isInternal bool, actorID, actorName, actorEmail, clientIP, userAgent string) error {
+// PublishTicketCommentAdded publishes a ticket comment added event. mentions are the emails
+// of staff @mentioned in the comment.
+func (p *Publisher) PublishTicketCommentAdded(ctx context.Context, tenantID, ticketID, ticketNumber, subject, commentContent string, isInternal bool, mentions []string, actorID, actorName, actorEmail, clientIP, userAgent string) error {
 	event := events.NewTicketEvent(events.TicketCommentAdded, tenantID)
 	event.TicketID = ticketID
 	event.TicketNumber = ticketNumber
@@ -167,6 +168,11 @@ func (p *Publisher) PublishTicketCommentAdded(ctx context.Context, tenantID, tic
 	event.ActorEmail = actorEmail
 	event.ClientIP = clientIP
 	event.UserAgent = userAgent
+	if len(mentions) > 0 {
+		event.Metadata = map[string]interface{}{
+			"mentions": mentions,
+		}
+	}
 
 	return p.publisher.Publish(ctx, event)
 }
@@ -0,0 +1,558 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tickets-service/internal/models"
+	"tickets-service/internal/repository"
+)
+
+const (
+	// defaultSuggestionLimit and maxSuggestionLimit bound the articles suggested for a ticket
+	defaultSuggestionLimit = 5
+	maxSuggestionLimit     = 10
+	// maxSuggestionTerms bounds the words of a ticket searched for
+	maxSuggestionTerms = 20
+)
+
+// suggestionTerms extracts the unique lowercase words of a ticket's title and description
+// to search articles for. Words are letters and digits only, so they are safe in a tsquery.
+func suggestionTerms(text string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len([]rune(word)) < 3 || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == maxSuggestionTerms {
+			break
+		}
+	}
+	return terms
+}
+
+func validArticleStatus(status models.ArticleStatus) bool {
+	switch status {
+	case models.ArticleStatusDraft, models.ArticleStatusPublished, models.ArticleStatusArchived:
+		return true
+	}
+	return false
+}
+
+func validArticleVisibility(visibility models.ArticleVisibility) bool {
+	return visibility == models.ArticleVisibilityPublic || visibility == models.ArticleVisibilityInternal
+}
+
+// ListArticles lists knowledge base articles. Customers only see published public articles.
+func (h *TicketsHandler) ListArticles(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filters := &repository.ArticleFilters{
+		Status:     c.Query("status"),
+		Visibility: c.Query("visibility"),
+		Category:   c.Query("category"),
+		Search:     c.Query("search"),
+	}
+	if !isAdminRole(c.GetString("userRole")) {
+		filters.Status = string(models.ArticleStatusPublished)
+		filters.Visibility = string(models.ArticleVisibilityPublic)
+	}
+
+	articles, total, err := h.repo.GetArticles(tenantID, page, limit, filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve articles",
+			},
+		})
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	c.JSON(http.StatusOK, models.KBArticleListResponse{
+		Success: true,
+		Data:    articles,
+		Pagination: &models.PaginationInfo{
+			Page:        page,
+			Limit:       limit,
+			Total:       total,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	})
+}
+
+// GetArticle retrieves a knowledge base article. Customers only see published public articles.
+func (h *TicketsHandler) GetArticle(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	articleID, ok := parseArticleID(c)
+	if !ok {
+		return
+	}
+
+	article, err := h.repo.GetArticle(tenantID, articleID)
+	if err == nil && !isAdminRole(c.GetString("userRole")) &&
+		(article.Status != models.ArticleStatusPublished || article.Visibility != models.ArticleVisibilityPublic) {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Article not found",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.KBArticleResponse{
+		Success: true,
+		Data:    article,
+	})
+}
+
+// CreateArticle creates a knowledge base article
+func (h *TicketsHandler) CreateArticle(c *gin.Context) {
+	if !requireArticleEditor(c) {
+		return
+	}
+
+	var req models.CreateKBArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	article := &models.KBArticle{
+		Title:       req.Title,
+		Summary:     req.Summary,
+		Body:        req.Body,
+		Category:    req.Category,
+		Tags:        models.StringList(req.Tags),
+		Status:      req.Status,
+		Visibility:  req.Visibility,
+		ExternalURL: req.ExternalURL,
+		CreatedBy:   c.GetString("userId"),
+	}
+	if article.Status == "" {
+		article.Status = models.ArticleStatusDraft
+	}
+	if article.Visibility == "" {
+		article.Visibility = models.ArticleVisibilityPublic
+	}
+	if !validArticleStatus(article.Status) || !validArticleVisibility(article.Visibility) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: "status must be DRAFT, PUBLISHED or ARCHIVED and visibility PUBLIC or INTERNAL",
+			},
+		})
+		return
+	}
+	if article.Status == models.ArticleStatusPublished {
+		now := time.Now()
+		article.PublishedAt = &now
+	}
+
+	if err := h.repo.CreateArticle(c.GetString("tenantId"), article); err != nil {
+		log.Printf("[TicketsHandler] Failed to create article: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "CREATE_FAILED",
+				Message: "Failed to create article",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.KBArticleResponse{
+		Success: true,
+		Data:    article,
+	})
+}
+
+// UpdateArticle updates a knowledge base article
+func (h *TicketsHandler) UpdateArticle(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+	userID := c.GetString("userId")
+
+	if !requireArticleEditor(c) {
+		return
+	}
+	articleID, ok := parseArticleID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateKBArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+	if (req.Status != nil && !validArticleStatus(*req.Status)) ||
+		(req.Visibility != nil && !validArticleVisibility(*req.Visibility)) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: "status must be DRAFT, PUBLISHED or ARCHIVED and visibility PUBLIC or INTERNAL",
+			},
+		})
+		return
+	}
+
+	existing, err := h.repo.GetArticle(tenantID, articleID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Article not found",
+			},
+		})
+		return
+	}
+
+	updates := map[string]interface{}{"updated_by": userID}
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Summary != nil {
+		updates["summary"] = *req.Summary
+	}
+	if req.Body != nil {
+		updates["body"] = *req.Body
+	}
+	if req.Category != nil {
+		updates["category"] = *req.Category
+	}
+	if req.Tags != nil {
+		updates["tags"] = models.StringList(req.Tags)
+	}
+	if req.Visibility != nil {
+		updates["visibility"] = *req.Visibility
+	}
+	if req.ExternalURL != nil {
+		updates["external_url"] = *req.ExternalURL
+	}
+	if req.Status != nil {
+		updates["status"] = *req.Status
+		if *req.Status == models.ArticleStatusPublished && existing.PublishedAt == nil {
+			updates["published_at"] = time.Now()
+		}
+	}
+
+	if err := h.repo.UpdateArticle(tenantID, articleID, updates); err != nil {
+		log.Printf("[TicketsHandler] Failed to update article %s: %v", articleID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_FAILED",
+				Message: "Failed to update article",
+			},
+		})
+		return
+	}
+
+	article, err := h.repo.GetArticle(tenantID, articleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve updated article",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.KBArticleResponse{
+		Success: true,
+		Data:    article,
+	})
+}
+
+// DeleteArticle deletes a knowledge base article
+func (h *TicketsHandler) DeleteArticle(c *gin.Context) {
+	if !requireArticleEditor(c) {
+		return
+	}
+	articleID, ok := parseArticleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteArticle(c.GetString("tenantId"), articleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "NOT_FOUND",
+					Message: "Article not found",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "DELETE_FAILED",
+				Message: "Failed to delete article",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Article deleted successfully",
+	})
+}
+
+// SuggestArticles suggests articles for a ticket being written. On the storefront ticket form
+// customers get public articles that may answer their question before they submit; agents
+// also get internal articles and the article body to insert into a reply.
+func (h *TicketsHandler) SuggestArticles(c *gin.Context) {
+	var req models.SuggestArticlesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	suggestions, err := h.suggestArticles(c, req.Title+" "+req.Description, req.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to suggest articles",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ArticleSuggestionsResponse{
+		Success: true,
+		Data:    suggestions,
+	})
+}
+
+// GetTicketSuggestedArticles suggests articles for an existing ticket, for agents to insert
+// into a reply
+func (h *TicketsHandler) GetTicketSuggestedArticles(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	if !requireArticleEditor(c) {
+		return
+	}
+
+	ticketID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid ticket ID format",
+			},
+		})
+		return
+	}
+
+	ticket, err := h.repo.GetTicketByID(tenantID, ticketID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Ticket not found",
+			},
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	suggestions, err := h.suggestArticles(c, ticket.Title+" "+ticket.Description, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to suggest articles",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ArticleSuggestionsResponse{
+		Success: true,
+		Data:    suggestions,
+	})
+}
+
+// RecordArticleFeedback records that an agent inserted a suggested article into a reply, or
+// that a customer left the ticket form unsubmitted after reading it
+func (h *TicketsHandler) RecordArticleFeedback(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	articleID, ok := parseArticleID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ArticleFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	column := "deflected_count"
+	if req.Action == "inserted" {
+		if !requireArticleEditor(c) {
+			return
+		}
+		column = "inserted_count"
+	}
+
+	if err := h.repo.IncrementArticleCounter(tenantID, []uuid.UUID{articleID}, column); err != nil {
+		log.Printf("[TicketsHandler] Failed to record %s feedback for article %s: %v", req.Action, articleID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UPDATE_FAILED",
+				Message: "Failed to record article feedback",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Article feedback recorded",
+	})
+}
+
+// suggestArticles finds published articles matching text, counting them as suggested
+func (h *TicketsHandler) suggestArticles(c *gin.Context, text string, limit int) ([]models.ArticleSuggestion, error) {
+	tenantID := c.GetString("tenantId")
+	agent := isAdminRole(c.GetString("userRole"))
+
+	if limit < 1 {
+		limit = defaultSuggestionLimit
+	} else if limit > maxSuggestionLimit {
+		limit = maxSuggestionLimit
+	}
+
+	matches, err := h.repo.SuggestArticles(tenantID, suggestionTerms(text), !agent, limit)
+	if err != nil {
+		log.Printf("[TicketsHandler] Failed to suggest articles: %v", err)
+		return nil, err
+	}
+
+	suggestions := make([]models.ArticleSuggestion, 0, len(matches))
+	articleIDs := make([]uuid.UUID, 0, len(matches))
+	for _, match := range matches {
+		suggestion := models.ArticleSuggestion{
+			ID:          match.ID,
+			Title:       match.Title,
+			Summary:     match.Summary,
+			Category:    match.Category,
+			Visibility:  match.Visibility,
+			ExternalURL: match.ExternalURL,
+			Score:       match.Score,
+		}
+		if agent {
+			suggestion.Body = match.Body
+		}
+		suggestions = append(suggestions, suggestion)
+		articleIDs = append(articleIDs, match.ID)
+	}
+
+	if err := h.repo.IncrementArticleCounter(tenantID, articleIDs, "suggested_count"); err != nil {
+		log.Printf("[TicketsHandler] Failed to count article suggestions: %v", err)
+	}
+	return suggestions, nil
+}
+
+// requireArticleEditor rejects callers who are not staff
+func requireArticleEditor(c *gin.Context) bool {
+	if isAdminRole(c.GetString("userRole")) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, models.ErrorResponse{
+		Success: false,
+		Error: models.Error{
+			Code:    "FORBIDDEN",
+			Message: "Only staff can manage knowledge base articles",
+		},
+	})
+	return false
+}
+
+func parseArticleID(c *gin.Context) (uuid.UUID, bool) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid article ID format",
+			},
+		})
+		return uuid.Nil, false
+	}
+	return articleID, true
+}
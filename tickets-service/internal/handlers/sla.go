@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"tickets-service/internal/clients"
+	"tickets-service/internal/models"
+)
+
+// applySLA fills in the SLA status of tickets with a resolution target. SLA timers count
+// business minutes of the tenant's business calendar for the ticket's region (metadata
+// "region"), or wall-clock minutes when the tenant has no calendar.
+func (h *TicketsHandler) applySLA(ctx context.Context, tenantID string, tickets []models.Ticket) {
+	now := time.Now()
+	for i := range tickets {
+		ticket := &tickets[i]
+		if ticket.SLA == nil {
+			continue
+		}
+		sla := *ticket.SLA
+		target, ok := slaMinutes(sla[models.SLAResolutionMinutes])
+		if !ok {
+			continue
+		}
+
+		calendar := h.businessCalendar(ctx, tenantID, ticket)
+		end := now
+		if ticket.ResolvedAt != nil {
+			end = *ticket.ResolvedAt
+		}
+		elapsed := calendar.ElapsedBusinessMinutes(ticket.CreatedAt, end)
+
+		sla[models.SLAElapsedMinutes] = elapsed
+		sla[models.SLAResolutionDueAt] = calendar.AddBusinessMinutes(ticket.CreatedAt, target)
+		sla[models.SLARemainingMinutes] = target - elapsed
+		sla[models.SLAResolutionBreached] = elapsed > target
+	}
+}
+
+// businessCalendar returns the calendar for a ticket's region, or nil for wall-clock time
+func (h *TicketsHandler) businessCalendar(ctx context.Context, tenantID string, ticket *models.Ticket) *clients.BusinessCalendar {
+	if h.calendarClient == nil {
+		return nil
+	}
+	region := ""
+	if ticket.Metadata != nil {
+		region, _ = (*ticket.Metadata)["region"].(string)
+	}
+	calendar, err := h.calendarClient.Resolve(ctx, tenantID, region)
+	if err != nil {
+		log.Printf("[TicketsHandler] Failed to fetch business calendar, using wall-clock SLA: %v", err)
+		return nil
+	}
+	return calendar
+}
+
+// slaMinutes reads a positive minute target from SLA JSON
+func slaMinutes(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), v > 0
+	case int:
+		return v, v > 0
+	}
+	return 0, false
+}
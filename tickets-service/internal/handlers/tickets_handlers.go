@@ -21,14 +21,18 @@ type TicketsHandler struct {
 	repo               *repository.TicketsRepository
 	notificationClient *clients.NotificationClient
 	tenantClient       *clients.TenantClient
+	staffClient        *clients.StaffClient
+	calendarClient     *clients.BusinessCalendarClient
 	eventsPublisher    *events.Publisher
 }
 
-func NewTicketsHandler(repo *repository.TicketsRepository, notificationClient *clients.NotificationClient, tenantClient *clients.TenantClient, eventsPublisher *events.Publisher) *TicketsHandler {
+func NewTicketsHandler(repo *repository.TicketsRepository, notificationClient *clients.NotificationClient, tenantClient *clients.TenantClient, staffClient *clients.StaffClient, calendarClient *clients.BusinessCalendarClient, eventsPublisher *events.Publisher) *TicketsHandler {
 	return &TicketsHandler{
 		repo:               repo,
 		notificationClient: notificationClient,
 		tenantClient:       tenantClient,
+		staffClient:        staffClient,
+		calendarClient:     calendarClient,
 		eventsPublisher:    eventsPublisher,
 	}
 }
@@ -68,6 +72,7 @@ func (h *TicketsHandler) CreateTicket(c *gin.Context) {
 		CreatedBy:      userID,
 		CreatedByName:  userName,
 		CreatedByEmail: userEmail,
+		SLA:            req.SLA,
 	}
 
 	// Convert tags to JSON
@@ -90,6 +95,18 @@ func (h *TicketsHandler) CreateTicket(c *gin.Context) {
 		return
 	}
 
+	// Staff who open a ticket watch it; customers already get the customer emails
+	if isAdminRole(c.GetString("userRole")) {
+		h.addWatcher(tenantID, &models.TicketWatcher{
+			TicketID:  ticket.ID,
+			UserID:    userID,
+			UserName:  userName,
+			UserEmail: userEmail,
+			Source:    models.WatcherSourceCreator,
+			AddedBy:   &userID,
+		})
+	}
+
 	// Publish event for audit trail
 	if h.eventsPublisher != nil {
 		actor := gosharedmw.GetActorInfo(c)
@@ -149,6 +166,93 @@ func (h *TicketsHandler) CreateTicket(c *gin.Context) {
 	})
 }
 
+// CreateTicketInternal creates a ticket on behalf of another service (e.g. orders-service
+// escalating stuck orders). The calling service is recorded as the creator and no
+// customer emails are sent.
+// POST /api/v1/internal/tickets
+func (h *TicketsHandler) CreateTicketInternal(c *gin.Context) {
+	service := c.GetHeader("X-Internal-Service")
+	if service == "" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INTERNAL_ONLY",
+				Message: "X-Internal-Service header is required",
+			},
+		})
+		return
+	}
+
+	var req models.CreateTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	tenantID := c.GetString("tenantId")
+	ticket := &models.Ticket{
+		ApplicationID: "default-app",
+		Title:         req.Title,
+		Description:   req.Description,
+		Type:          req.Type,
+		Status:        models.TicketStatusOpen,
+		Priority:      req.Priority,
+		CreatedBy:     "service:" + service,
+		CreatedByName: service,
+		DueDate:       req.DueDate,
+		SLA:           req.SLA,
+		Metadata:      req.Metadata,
+	}
+	if len(req.Tags) > 0 {
+		tagsJSON := make(models.JSON)
+		for i, tag := range req.Tags {
+			tagsJSON[strconv.Itoa(i)] = tag
+		}
+		ticket.Tags = &tagsJSON
+	}
+
+	if err := h.repo.CreateTicket(tenantID, ticket); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "CREATE_FAILED",
+				Message: "Failed to create ticket",
+			},
+		})
+		return
+	}
+
+	if h.eventsPublisher != nil {
+		_ = h.eventsPublisher.PublishTicketCreated(
+			c.Request.Context(),
+			tenantID,
+			ticket.ID.String(),
+			ticket.TicketNumber,
+			"",
+			service,
+			ticket.Title,
+			string(ticket.Type),
+			string(ticket.Priority),
+			ticket.CreatedBy,
+			service,
+			"",
+			c.ClientIP(),
+			c.Request.UserAgent(),
+		)
+	}
+
+	c.JSON(http.StatusCreated, models.TicketResponse{
+		Success: true,
+		Data:    ticket,
+	})
+}
+
 // GetTickets retrieves tickets with pagination
 func (h *TicketsHandler) GetTickets(c *gin.Context) {
 	tenantID := c.GetString("tenantId")
@@ -190,6 +294,8 @@ func (h *TicketsHandler) GetTickets(c *gin.Context) {
 		return
 	}
 
+	h.applySLA(c.Request.Context(), tenantID, tickets)
+
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
 	pagination := &models.PaginationInfo{
 		Page:        page,
@@ -252,6 +358,8 @@ func (h *TicketsHandler) GetTicket(c *gin.Context) {
 		}
 	}
 
+	h.applySLA(c.Request.Context(), tenantID, []models.Ticket{*ticket})
+
 	c.JSON(http.StatusOK, models.TicketResponse{
 		Success: true,
 		Data:    ticket,
@@ -339,6 +447,9 @@ func (h *TicketsHandler) UpdateTicket(c *gin.Context) {
 	if req.ActualTime != nil {
 		updates.ActualTime = req.ActualTime
 	}
+	if req.SLA != nil {
+		updates.SLA = req.SLA
+	}
 	if req.Metadata != nil {
 		updates.Metadata = req.Metadata
 	}
@@ -395,6 +506,8 @@ func (h *TicketsHandler) UpdateTicket(c *gin.Context) {
 		)
 	}
 
+	h.notifyWatchers(tenantID, updatedTicket, userID, c.GetString("userName"), "Ticket details updated", nil)
+
 	c.JSON(http.StatusOK, models.TicketResponse{
 		Success: true,
 		Data:    updatedTicket,
@@ -643,19 +756,14 @@ func (h *TicketsHandler) UpdateTicketStatus(c *gin.Context) {
 		)
 	}
 
+	h.notifyWatchers(tenantID, updatedTicket, userID, c.GetString("userName"), "Status changed to "+string(updatedTicket.Status), nil)
+
 	c.JSON(http.StatusOK, models.TicketResponse{
 		Success: true,
 		Data:    updatedTicket,
 	})
 }
 
-func (h *TicketsHandler) AssignTicket(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
-}
-func (h *TicketsHandler) UnassignTicket(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Not implemented yet"})
-}
-
 // AddComment adds a comment to a ticket
 func (h *TicketsHandler) AddComment(c *gin.Context) {
 	tenantID := c.GetString("tenantId")
@@ -727,6 +835,15 @@ func (h *TicketsHandler) AddComment(c *gin.Context) {
 		"createdAt":  c.GetTime("requestTime"),
 	}
 
+	// Staff can pull colleagues into a ticket by @mentioning their email
+	var mentions []string
+	if isAdminRole(userRole) {
+		mentions = parseMentions(req.Content)
+	}
+	if len(mentions) > 0 {
+		comment["mentions"] = mentions
+	}
+
 	// Use current time if requestTime not set
 	if comment["createdAt"] == nil || comment["createdAt"].(interface{}) == nil {
 		comment["createdAt"] = time.Now().Format(time.RFC3339)
@@ -744,6 +861,8 @@ func (h *TicketsHandler) AddComment(c *gin.Context) {
 		return
 	}
 
+	mentionedIDs := h.addMentionedWatchers(c.Request.Context(), tenantID, ticketID, mentions, userID)
+
 	// Publish event for audit trail
 	if h.eventsPublisher != nil {
 		actor := gosharedmw.GetActorInfo(c)
@@ -755,6 +874,7 @@ func (h *TicketsHandler) AddComment(c *gin.Context) {
 			updatedTicket.Title,
 			req.Content,
 			req.IsInternal,
+			mentions,
 			actor.ActorID,
 			actor.ActorName,
 			actor.ActorEmail,
@@ -763,6 +883,8 @@ func (h *TicketsHandler) AddComment(c *gin.Context) {
 		)
 	}
 
+	h.notifyWatchers(tenantID, updatedTicket, userID, userName, "New comment from "+userName, mentionedIDs)
+
 	c.JSON(http.StatusCreated, models.TicketResponse{
 		Success: true,
 		Data:    updatedTicket,
@@ -0,0 +1,478 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tickets-service/internal/clients"
+	"tickets-service/internal/models"
+
+	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
+)
+
+// maxMentionsPerComment bounds the staff lookups a single comment can trigger
+const maxMentionsPerComment = 10
+
+// mentionPattern matches @mentions of staff by email, e.g. "@jane@example.com"
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w.@])@([A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+// parseMentions returns the unique, lowercased emails @mentioned in a comment
+func parseMentions(content string) []string {
+	var emails []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		email := strings.ToLower(match[1])
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		emails = append(emails, email)
+		if len(emails) == maxMentionsPerComment {
+			break
+		}
+	}
+	return emails
+}
+
+// GetWatchers lists the watchers of a ticket
+func (h *TicketsHandler) GetWatchers(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	ticket, ok := h.getWatchableTicket(c, tenantID)
+	if !ok {
+		return
+	}
+
+	watchers, err := h.repo.GetWatchers(tenantID, ticket.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve watchers",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WatcherListResponse{
+		Success: true,
+		Data:    watchers,
+	})
+}
+
+// AddWatcher adds a staff member as a watcher of a ticket. Without a userId or userEmail
+// the caller starts watching; a userEmail alone is resolved through staff-service.
+func (h *TicketsHandler) AddWatcher(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+	userID := c.GetString("userId")
+
+	ticket, ok := h.getWatchableTicket(c, tenantID)
+	if !ok {
+		return
+	}
+
+	var req models.AddWatcherRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "INVALID_REQUEST",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+	}
+
+	watcher := &models.TicketWatcher{
+		TicketID:  ticket.ID,
+		UserID:    req.UserID,
+		UserName:  req.UserName,
+		UserEmail: req.UserEmail,
+		Source:    models.WatcherSourceManual,
+		AddedBy:   &userID,
+	}
+	switch {
+	case req.UserID == "" && req.UserEmail == "":
+		watcher.UserID = userID
+		watcher.UserName = c.GetString("userName")
+		watcher.UserEmail = c.GetString("userEmail")
+	case req.UserID == "":
+		staff, err := h.lookupStaff(c.Request.Context(), tenantID, req.UserEmail)
+		if err != nil {
+			if errors.Is(err, clients.ErrStaffNotFound) {
+				c.JSON(http.StatusNotFound, models.ErrorResponse{
+					Success: false,
+					Error: models.Error{
+						Code:    "STAFF_NOT_FOUND",
+						Message: "No active staff member with this email",
+						Field:   "userEmail",
+					},
+				})
+				return
+			}
+			log.Printf("[TicketsHandler] Failed to look up watcher %s: %v", req.UserEmail, err)
+			c.JSON(http.StatusBadGateway, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "STAFF_LOOKUP_FAILED",
+					Message: "Failed to look up staff member",
+				},
+			})
+			return
+		}
+		watcher.UserID = staff.UserID()
+		watcher.UserName = staff.Name()
+		watcher.UserEmail = staff.Email
+	}
+
+	if _, err := h.repo.AddWatcher(tenantID, watcher); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "ADD_WATCHER_FAILED",
+				Message: "Failed to add watcher",
+			},
+		})
+		return
+	}
+
+	watchers, err := h.repo.GetWatchers(tenantID, ticket.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve watchers",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.WatcherListResponse{
+		Success: true,
+		Data:    watchers,
+	})
+}
+
+// RemoveWatcher stops a user watching a ticket
+func (h *TicketsHandler) RemoveWatcher(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+
+	ticket, ok := h.getWatchableTicket(c, tenantID)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.RemoveWatcher(tenantID, ticket.ID, c.Param("userId")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "NOT_FOUND",
+					Message: "User is not watching this ticket",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "REMOVE_WATCHER_FAILED",
+				Message: "Failed to remove watcher",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Watcher removed successfully",
+	})
+}
+
+// AssignTicket assigns a staff member to a ticket and adds them as a watcher
+func (h *TicketsHandler) AssignTicket(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+	userID := c.GetString("userId")
+
+	ticket, ok := h.getWatchableTicket(c, tenantID)
+	if !ok {
+		return
+	}
+
+	var req models.AssignTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	assignee := map[string]interface{}{
+		"userId":     req.AssigneeID,
+		"userName":   req.AssigneeName,
+		"userEmail":  req.AssigneeEmail,
+		"assignedBy": userID,
+		"assignedAt": time.Now().Format(time.RFC3339),
+	}
+	updatedTicket, err := h.repo.AssignTicket(tenantID, ticket.ID, req.AssigneeID, assignee, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "ASSIGN_FAILED",
+				Message: "Failed to assign ticket",
+			},
+		})
+		return
+	}
+
+	h.addWatcher(tenantID, &models.TicketWatcher{
+		TicketID:  ticket.ID,
+		UserID:    req.AssigneeID,
+		UserName:  req.AssigneeName,
+		UserEmail: req.AssigneeEmail,
+		Source:    models.WatcherSourceAssignee,
+		AddedBy:   &userID,
+	})
+
+	// Publish event for audit trail
+	if h.eventsPublisher != nil {
+		actor := gosharedmw.GetActorInfo(c)
+		_ = h.eventsPublisher.PublishTicketAssigned(
+			c.Request.Context(),
+			tenantID,
+			updatedTicket.ID.String(),
+			updatedTicket.TicketNumber,
+			updatedTicket.CreatedByEmail,
+			updatedTicket.Title,
+			req.AssigneeID,
+			req.AssigneeName,
+			"",
+			actor.ActorID,
+			actor.ActorName,
+			actor.ActorEmail,
+			actor.ClientIP,
+			actor.UserAgent,
+		)
+	}
+
+	assigneeName := req.AssigneeName
+	if assigneeName == "" {
+		assigneeName = req.AssigneeEmail
+	}
+	h.notifyWatchers(tenantID, updatedTicket, userID, c.GetString("userName"), "Assigned to "+assigneeName, nil)
+
+	c.JSON(http.StatusOK, models.TicketResponse{
+		Success: true,
+		Data:    updatedTicket,
+	})
+}
+
+// UnassignTicket removes an assignee from a ticket. The former assignee keeps watching the
+// ticket until they remove themselves.
+func (h *TicketsHandler) UnassignTicket(c *gin.Context) {
+	tenantID := c.GetString("tenantId")
+	userID := c.GetString("userId")
+	assigneeID := c.Param("assigneeId")
+
+	ticket, ok := h.getWatchableTicket(c, tenantID)
+	if !ok {
+		return
+	}
+
+	updatedTicket, err := h.repo.UnassignTicket(tenantID, ticket.ID, assigneeID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error: models.Error{
+					Code:    "NOT_FOUND",
+					Message: "User is not assigned to this ticket",
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "UNASSIGN_FAILED",
+				Message: "Failed to unassign ticket",
+			},
+		})
+		return
+	}
+
+	// Publish event for audit trail
+	if h.eventsPublisher != nil {
+		actor := gosharedmw.GetActorInfo(c)
+		_ = h.eventsPublisher.PublishTicketUnassigned(
+			c.Request.Context(),
+			tenantID,
+			updatedTicket.ID.String(),
+			updatedTicket.TicketNumber,
+			updatedTicket.Title,
+			assigneeID,
+			actor.ActorID,
+			actor.ActorName,
+			actor.ActorEmail,
+			actor.ClientIP,
+			actor.UserAgent,
+		)
+	}
+
+	c.JSON(http.StatusOK, models.TicketResponse{
+		Success: true,
+		Data:    updatedTicket,
+	})
+}
+
+// getWatchableTicket loads the ticket of the request for watcher and assignment changes,
+// which are limited to staff. Writes the error response and returns false on failure.
+func (h *TicketsHandler) getWatchableTicket(c *gin.Context, tenantID string) (*models.Ticket, bool) {
+	if !isAdminRole(c.GetString("userRole")) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FORBIDDEN",
+				Message: "Only staff can manage ticket watchers and assignees",
+			},
+		})
+		return nil, false
+	}
+
+	ticketID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid ticket ID format",
+			},
+		})
+		return nil, false
+	}
+
+	ticket, err := h.repo.GetTicketByID(tenantID, ticketID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "NOT_FOUND",
+				Message: "Ticket not found",
+			},
+		})
+		return nil, false
+	}
+	return ticket, true
+}
+
+// addWatcher adds a watcher as a side effect of another change, logging failures
+func (h *TicketsHandler) addWatcher(tenantID string, watcher *models.TicketWatcher) {
+	if watcher.UserID == "" {
+		return
+	}
+	if _, err := h.repo.AddWatcher(tenantID, watcher); err != nil {
+		log.Printf("[TicketsHandler] Failed to add watcher %s to ticket %s: %v", watcher.UserID, watcher.TicketID, err)
+	}
+}
+
+// addMentionedWatchers resolves @mentioned emails to staff and adds them as watchers.
+// Returns the user IDs of the mentioned staff; unknown emails are ignored.
+func (h *TicketsHandler) addMentionedWatchers(ctx context.Context, tenantID string, ticketID uuid.UUID, emails []string, addedBy string) []string {
+	var userIDs []string
+	for _, email := range emails {
+		staff, err := h.lookupStaff(ctx, tenantID, email)
+		if err != nil {
+			if !errors.Is(err, clients.ErrStaffNotFound) {
+				log.Printf("[TicketsHandler] Failed to resolve mention %s: %v", email, err)
+			}
+			continue
+		}
+		h.addWatcher(tenantID, &models.TicketWatcher{
+			TicketID:  ticketID,
+			UserID:    staff.UserID(),
+			UserName:  staff.Name(),
+			UserEmail: staff.Email,
+			Source:    models.WatcherSourceMention,
+			AddedBy:   &addedBy,
+		})
+		userIDs = append(userIDs, staff.UserID())
+	}
+	return userIDs
+}
+
+func (h *TicketsHandler) lookupStaff(ctx context.Context, tenantID, email string) (*clients.StaffMember, error) {
+	if h.staffClient == nil {
+		return nil, clients.ErrStaffNotFound
+	}
+	return h.staffClient.GetStaffByEmail(ctx, tenantID, email)
+}
+
+// notifyWatchers emails the ticket's watchers about an update (non-blocking). The actor
+// isn't notified of their own change; mentioned users get a mention email instead.
+func (h *TicketsHandler) notifyWatchers(tenantID string, ticket *models.Ticket, actorID, actorName, update string, mentioned []string) {
+	if h.notificationClient == nil {
+		return
+	}
+	if actorName == "" {
+		actorName = "Support Team"
+	}
+
+	go func() {
+		ctx := context.Background()
+		watchers, err := h.repo.GetWatchers(tenantID, ticket.ID)
+		if err != nil {
+			log.Printf("[TicketsHandler] Failed to load watchers of ticket %s: %v", ticket.ID, err)
+			return
+		}
+		if len(watchers) == 0 {
+			return
+		}
+
+		ticketURL := ""
+		if h.tenantClient != nil {
+			ticketURL = h.tenantClient.BuildTicketURL(ctx, tenantID, ticket.ID.String())
+		}
+		notification := &clients.TicketNotification{
+			TenantID:      tenantID,
+			TicketID:      ticket.ID.String(),
+			TicketNumber:  ticket.TicketNumber,
+			Subject:       ticket.Title,
+			Description:   ticket.Description,
+			Status:        string(ticket.Status),
+			Priority:      string(ticket.Priority),
+			CustomerEmail: ticket.CreatedByEmail,
+			CustomerName:  ticket.CreatedByName,
+			TicketURL:     ticketURL,
+		}
+
+		isMentioned := make(map[string]bool, len(mentioned))
+		for _, id := range mentioned {
+			isMentioned[id] = true
+		}
+		for _, watcher := range watchers {
+			if watcher.UserID == actorID || watcher.UserEmail == "" {
+				continue
+			}
+			if err := h.notificationClient.SendTicketWatcherNotification(ctx, notification, watcher.UserEmail, watcher.UserName, update, actorName, isMentioned[watcher.UserID]); err != nil {
+				log.Printf("[TicketsHandler] Failed to notify watcher %s: %v", watcher.UserID, err)
+			}
+		}
+	}()
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"tickets-service/internal/tenancy"
 )
 
 // TenantMiddleware extracts and validates tenant information
@@ -41,6 +43,8 @@ func TenantMiddleware() gin.HandlerFunc {
 		c.Set("tenantId", tenantID)
 		c.Set("tenant_id", tenantID)
 		c.Set("vendor_id", tenantID)
+		// Scope database statements made with the request context (see internal/tenancy)
+		c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), tenantID))
 		c.Next()
 	}
 }
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArticleStatus represents the publishing status of a knowledge base article
+type ArticleStatus string
+
+const (
+	ArticleStatusDraft     ArticleStatus = "DRAFT"
+	ArticleStatusPublished ArticleStatus = "PUBLISHED"
+	ArticleStatusArchived  ArticleStatus = "ARCHIVED"
+)
+
+// ArticleVisibility controls who can be shown a knowledge base article
+type ArticleVisibility string
+
+const (
+	// ArticleVisibilityPublic articles are suggested to customers on the storefront and to agents
+	ArticleVisibilityPublic ArticleVisibility = "PUBLIC"
+	// ArticleVisibilityInternal articles are only suggested to agents
+	ArticleVisibilityInternal ArticleVisibility = "INTERNAL"
+)
+
+// StringList is a list of strings stored as JSONB
+type StringList []string
+
+func (s StringList) Value() (driver.Value, error) {
+	if s == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal([]string(s))
+}
+
+func (s *StringList) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// KBArticle is a knowledge base article suggested for tickets. Articles hosted in an external
+// help center keep their content here for matching and link out with ExternalURL.
+type KBArticle struct {
+	ID          uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID    string            `json:"tenantId" gorm:"not null;index"`
+	Title       string            `json:"title" gorm:"not null"`
+	Summary     string            `json:"summary,omitempty"`
+	Body        string            `json:"body" gorm:"type:text;not null"`
+	Category    string            `json:"category,omitempty" gorm:"index"`
+	Tags        StringList        `json:"tags,omitempty" gorm:"type:jsonb"`
+	Status      ArticleStatus     `json:"status" gorm:"not null;default:'DRAFT'"`
+	Visibility  ArticleVisibility `json:"visibility" gorm:"not null;default:'PUBLIC'"`
+	ExternalURL string            `json:"externalUrl,omitempty"`
+	// Suggestion counters: shown with a ticket form, inserted into a reply, and tickets
+	// the customer chose not to submit after reading the article
+	SuggestedCount int        `json:"suggestedCount" gorm:"not null;default:0"`
+	InsertedCount  int        `json:"insertedCount" gorm:"not null;default:0"`
+	DeflectedCount int        `json:"deflectedCount" gorm:"not null;default:0"`
+	CreatedBy      string     `json:"createdBy" gorm:"not null"`
+	UpdatedBy      *string    `json:"updatedBy,omitempty"`
+	PublishedAt    *time.Time `json:"publishedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// TableName returns the table name for the KBArticle model
+func (KBArticle) TableName() string {
+	return "kb_articles"
+}
+
+// CreateKBArticleRequest represents a request to create a knowledge base article
+type CreateKBArticleRequest struct {
+	Title       string            `json:"title" binding:"required,max=255"`
+	Summary     string            `json:"summary,omitempty" binding:"max=500"`
+	Body        string            `json:"body" binding:"required"`
+	Category    string            `json:"category,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Status      ArticleStatus     `json:"status,omitempty"`
+	Visibility  ArticleVisibility `json:"visibility,omitempty"`
+	ExternalURL string            `json:"externalUrl,omitempty" binding:"omitempty,url"`
+}
+
+// UpdateKBArticleRequest represents a request to update a knowledge base article
+type UpdateKBArticleRequest struct {
+	Title       *string            `json:"title,omitempty" binding:"omitempty,max=255"`
+	Summary     *string            `json:"summary,omitempty" binding:"omitempty,max=500"`
+	Body        *string            `json:"body,omitempty"`
+	Category    *string            `json:"category,omitempty"`
+	Tags        []string           `json:"tags,omitempty"`
+	Status      *ArticleStatus     `json:"status,omitempty"`
+	Visibility  *ArticleVisibility `json:"visibility,omitempty"`
+	ExternalURL *string            `json:"externalUrl,omitempty" binding:"omitempty,url"`
+}
+
+// SuggestArticlesRequest asks for articles relevant to a ticket being written
+type SuggestArticlesRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Limit       int    `json:"limit,omitempty"`
+}
+
+// ArticleSuggestion is a published article matching a ticket, best match first
+type ArticleSuggestion struct {
+	ID          uuid.UUID         `json:"id"`
+	Title       string            `json:"title"`
+	Summary     string            `json:"summary,omitempty"`
+	Category    string            `json:"category,omitempty"`
+	Visibility  ArticleVisibility `json:"visibility"`
+	ExternalURL string            `json:"externalUrl,omitempty"`
+	Body        string            `json:"body,omitempty"` // agents only, for inserting into a reply
+	Score       float64           `json:"score"`
+}
+
+// ArticleFeedbackRequest records what happened to a suggested article
+type ArticleFeedbackRequest struct {
+	Action string `json:"action" binding:"required,oneof=inserted deflected"`
+}
+
+// KBArticleResponse represents a single knowledge base article response
+type KBArticleResponse struct {
+	Success bool       `json:"success"`
+	Data    *KBArticle `json:"data"`
+}
+
+// KBArticleListResponse represents a list of knowledge base articles response
+type KBArticleListResponse struct {
+	Success    bool            `json:"success"`
+	Data       []KBArticle     `json:"data"`
+	Pagination *PaginationInfo `json:"pagination"`
+}
+
+// ArticleSuggestionsResponse represents article suggestions for a ticket
+type ArticleSuggestionsResponse struct {
+	Success bool                `json:"success"`
+	Data    []ArticleSuggestion `json:"data"`
+}
@@ -90,6 +90,7 @@ type Ticket struct {
 	CreatedAt      time.Time       `json:"createdAt"`
 	UpdatedAt      time.Time       `json:"updatedAt"`
 	DueDate        *time.Time      `json:"dueDate,omitempty"`
+	ResolvedAt     *time.Time      `json:"resolvedAt,omitempty"`
 	EstimatedTime  *int            `json:"estimatedTime,omitempty"` // in minutes
 	ActualTime     *int            `json:"actualTime,omitempty"`    // in minutes
 	ParentTicketID *uuid.UUID      `json:"parentTicketId,omitempty"`
@@ -113,6 +114,7 @@ type CreateTicketRequest struct {
 	DueDate       *time.Time     `json:"dueDate,omitempty"`
 	EstimatedTime *int           `json:"estimatedTime,omitempty"`
 	AssigneeIDs   []string       `json:"assigneeIds,omitempty"`
+	SLA           *JSON          `json:"sla,omitempty"` // e.g. {"resolutionMinutes": 480} in business minutes
 	Metadata      *JSON          `json:"metadata,omitempty"`
 }
 
@@ -125,6 +127,7 @@ type UpdateTicketRequest struct {
 	DueDate       *time.Time      `json:"dueDate,omitempty"`
 	EstimatedTime *int            `json:"estimatedTime,omitempty"`
 	ActualTime    *int            `json:"actualTime,omitempty"`
+	SLA           *JSON           `json:"sla,omitempty"`
 	Metadata      *JSON           `json:"metadata,omitempty"`
 }
 
@@ -169,6 +172,16 @@ type Error struct {
 	Details *JSON  `json:"details,omitempty"`
 }
 
+// SLA keys. Targets are set on the ticket's sla JSON in business minutes of the tenant's
+// business calendar; the status keys are computed when the ticket is read.
+const (
+	SLAResolutionMinutes  = "resolutionMinutes"
+	SLAElapsedMinutes     = "elapsedBusinessMinutes"
+	SLAResolutionDueAt    = "resolutionDueAt"
+	SLARemainingMinutes   = "remainingBusinessMinutes"
+	SLAResolutionBreached = "resolutionBreached"
+)
+
 // TableName returns the table name for the Ticket model
 func (Ticket) TableName() string {
 	return "tickets"
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WatcherSource records how a user came to watch a ticket
+type WatcherSource string
+
+const (
+	WatcherSourceCreator  WatcherSource = "CREATOR"
+	WatcherSourceAssignee WatcherSource = "ASSIGNEE"
+	WatcherSourceManual   WatcherSource = "MANUAL"
+	WatcherSourceMention  WatcherSource = "MENTION"
+)
+
+// TicketWatcher is a staff member who is notified of updates to a ticket
+type TicketWatcher struct {
+	ID        uuid.UUID     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID  string        `json:"tenantId" gorm:"not null;index"`
+	TicketID  uuid.UUID     `json:"ticketId" gorm:"type:uuid;not null;uniqueIndex:idx_ticket_watchers_ticket_user"`
+	UserID    string        `json:"userId" gorm:"not null;uniqueIndex:idx_ticket_watchers_ticket_user"`
+	UserName  string        `json:"userName,omitempty"`
+	UserEmail string        `json:"userEmail,omitempty"`
+	Source    WatcherSource `json:"source" gorm:"not null;default:'MANUAL'"`
+	AddedBy   *string       `json:"addedBy,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+// TableName returns the table name for the TicketWatcher model
+func (TicketWatcher) TableName() string {
+	return "ticket_watchers"
+}
+
+// AddWatcherRequest adds a watcher to a ticket. An empty request adds the caller.
+type AddWatcherRequest struct {
+	UserID    string `json:"userId,omitempty"`
+	UserName  string `json:"userName,omitempty"`
+	UserEmail string `json:"userEmail,omitempty"`
+}
+
+// AssignTicketRequest assigns a staff member to a ticket
+type AssignTicketRequest struct {
+	AssigneeID    string `json:"assigneeId" binding:"required"`
+	AssigneeName  string `json:"assigneeName,omitempty"`
+	AssigneeEmail string `json:"assigneeEmail,omitempty"`
+}
+
+// WatcherListResponse represents a list of ticket watchers response
+type WatcherListResponse struct {
+	Success bool            `json:"success"`
+	Data    []TicketWatcher `json:"data"`
+}
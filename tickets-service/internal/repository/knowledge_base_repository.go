@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"tickets-service/internal/models"
+)
+
+// articleDocument is the weighted full-text document of an article: title and tags rank
+// above the summary, which ranks above the body
+const articleDocument = "setweight(to_tsvector('english', title), 'A') || " +
+	"setweight(to_tsvector('english', COALESCE(tags::text, '')), 'A') || " +
+	"setweight(to_tsvector('english', COALESCE(summary, '')), 'B') || " +
+	"setweight(to_tsvector('english', body), 'C')"
+
+// ArticleFilters contains optional filters for knowledge base article queries
+type ArticleFilters struct {
+	Status     string
+	Visibility string
+	Category   string
+	Search     string
+}
+
+// ScoredArticle is an article matched by full-text search with its relevance score
+type ScoredArticle struct {
+	models.KBArticle `gorm:"embedded"`
+	Score            float64
+}
+
+// CreateArticle creates a knowledge base article
+func (r *TicketsRepository) CreateArticle(tenantID string, article *models.KBArticle) error {
+	article.TenantID = tenantID
+	article.CreatedAt = time.Now()
+	article.UpdatedAt = time.Now()
+	return r.db.Create(article).Error
+}
+
+// GetArticle retrieves a knowledge base article by ID
+func (r *TicketsRepository) GetArticle(tenantID string, articleID uuid.UUID) (*models.KBArticle, error) {
+	var article models.KBArticle
+	err := r.db.Where("tenant_id = ? AND id = ?", tenantID, articleID).First(&article).Error
+	if err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// GetArticles retrieves knowledge base articles with pagination and optional filters
+func (r *TicketsRepository) GetArticles(tenantID string, page, limit int, filters *ArticleFilters) ([]models.KBArticle, int64, error) {
+	var articles []models.KBArticle
+	var total int64
+
+	query := r.db.Model(&models.KBArticle{}).Where("tenant_id = ?", tenantID)
+
+	if filters != nil {
+		if filters.Status != "" {
+			query = query.Where("status = ?", filters.Status)
+		}
+		if filters.Visibility != "" {
+			query = query.Where("visibility = ?", filters.Visibility)
+		}
+		if filters.Category != "" {
+			query = query.Where("category = ?", filters.Category)
+		}
+		if filters.Search != "" {
+			query = query.Where("title ILIKE ?", "%"+filters.Search+"%")
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("updated_at DESC").Offset(offset).Limit(limit).Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+// UpdateArticle updates a knowledge base article
+func (r *TicketsRepository) UpdateArticle(tenantID string, articleID uuid.UUID, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+	result := r.db.Model(&models.KBArticle{}).
+		Where("tenant_id = ? AND id = ?", tenantID, articleID).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteArticle deletes a knowledge base article
+func (r *TicketsRepository) DeleteArticle(tenantID string, articleID uuid.UUID) error {
+	result := r.db.Where("tenant_id = ? AND id = ?", tenantID, articleID).Delete(&models.KBArticle{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SuggestArticles returns the published articles matching any of the search terms, most
+// relevant first. Terms must be plain lowercase words; publicOnly excludes internal articles.
+func (r *TicketsRepository) SuggestArticles(tenantID string, terms []string, publicOnly bool, limit int) ([]ScoredArticle, error) {
+	var results []ScoredArticle
+	if len(terms) == 0 {
+		return results, nil
+	}
+	tsQuery := strings.Join(terms, " | ")
+
+	query := r.db.Model(&models.KBArticle{}).
+		Select("kb_articles.*, ts_rank("+articleDocument+", to_tsquery('english', ?)) AS score", tsQuery).
+		Where("tenant_id = ? AND status = ?", tenantID, models.ArticleStatusPublished).
+		Where(articleDocument+" @@ to_tsquery('english', ?)", tsQuery)
+	if publicOnly {
+		query = query.Where("visibility = ?", models.ArticleVisibilityPublic)
+	}
+
+	err := query.Order("score DESC, deflected_count DESC").Limit(limit).Scan(&results).Error
+	return results, err
+}
+
+// IncrementArticleCounter adds one to a suggestion counter (suggested_count, inserted_count
+// or deflected_count) of the given articles
+func (r *TicketsRepository) IncrementArticleCounter(tenantID string, articleIDs []uuid.UUID, column string) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.KBArticle{}).
+		Where("tenant_id = ? AND id IN ?", tenantID, articleIDs).
+		UpdateColumn(column, gorm.Expr(column+" + 1")).Error
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tickets-service/internal/models"
+)
+
+// AddWatcher adds a watcher to a ticket. Returns false if the user already watches it.
+func (r *TicketsRepository) AddWatcher(tenantID string, watcher *models.TicketWatcher) (bool, error) {
+	watcher.TenantID = tenantID
+	watcher.CreatedAt = time.Now()
+
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ticket_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Create(watcher)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RemoveWatcher removes a watcher from a ticket
+func (r *TicketsRepository) RemoveWatcher(tenantID string, ticketID uuid.UUID, userID string) error {
+	result := r.db.Where("tenant_id = ? AND ticket_id = ? AND user_id = ?", tenantID, ticketID, userID).
+		Delete(&models.TicketWatcher{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetWatchers retrieves the watchers of a ticket, oldest first
+func (r *TicketsRepository) GetWatchers(tenantID string, ticketID uuid.UUID) ([]models.TicketWatcher, error) {
+	var watchers []models.TicketWatcher
+	err := r.db.Where("tenant_id = ? AND ticket_id = ?", tenantID, ticketID).
+		Order("created_at ASC").
+		Find(&watchers).Error
+	return watchers, err
+}
+
+// AssignTicket adds an assignee to a ticket's assignees, keyed by user ID
+func (r *TicketsRepository) AssignTicket(tenantID string, ticketID uuid.UUID, userID string, assignee map[string]interface{}, updatedBy string) (*models.Ticket, error) {
+	ticket, err := r.GetTicketByID(tenantID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignees := make(models.JSON)
+	if ticket.Assignees != nil {
+		assignees = *ticket.Assignees
+	}
+	assignees[userID] = assignee
+
+	return ticket, r.saveAssignees(ticket, assignees, updatedBy)
+}
+
+// UnassignTicket removes an assignee from a ticket. Returns gorm.ErrRecordNotFound if the
+// user isn't assigned.
+func (r *TicketsRepository) UnassignTicket(tenantID string, ticketID uuid.UUID, userID string, updatedBy string) (*models.Ticket, error) {
+	ticket, err := r.GetTicketByID(tenantID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Assignees == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	assignees := *ticket.Assignees
+	if _, ok := assignees[userID]; !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	delete(assignees, userID)
+
+	return ticket, r.saveAssignees(ticket, assignees, updatedBy)
+}
+
+func (r *TicketsRepository) saveAssignees(ticket *models.Ticket, assignees models.JSON, updatedBy string) error {
+	ticket.Assignees = &assignees
+	ticket.UpdatedAt = time.Now()
+	ticket.UpdatedBy = &updatedBy
+
+	return r.db.Model(&models.Ticket{}).
+		Where("tenant_id = ? AND id = ?", ticket.TenantID, ticket.ID).
+		Updates(map[string]interface{}{
+			"assignees":  ticket.Assignees,
+			"updated_at": ticket.UpdatedAt,
+			"updated_by": updatedBy,
+		}).Error
+}
@@ -0,0 +1,4 @@
+-- Rollback ticket watchers
+DROP INDEX IF EXISTS idx_ticket_watchers_ticket_user;
+DROP INDEX IF EXISTS idx_ticket_watchers_tenant_id;
+DROP TABLE IF EXISTS ticket_watchers;
@@ -0,0 +1,16 @@
+-- Ticket watchers: staff notified of updates to a ticket without being assigned to it
+CREATE TABLE IF NOT EXISTS ticket_watchers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    user_name VARCHAR(255),
+    user_email VARCHAR(255),
+    -- How the user came to watch: CREATOR, ASSIGNEE, MANUAL or MENTION
+    source VARCHAR(20) NOT NULL DEFAULT 'MANUAL',
+    added_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ticket_watchers_tenant_id ON ticket_watchers(tenant_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ticket_watchers_ticket_user ON ticket_watchers(ticket_id, user_id);
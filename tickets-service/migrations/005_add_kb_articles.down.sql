@@ -0,0 +1,5 @@
+-- Rollback knowledge base articles
+DROP INDEX IF EXISTS idx_kb_articles_search;
+DROP INDEX IF EXISTS idx_kb_articles_category;
+DROP INDEX IF EXISTS idx_kb_articles_tenant_id;
+DROP TABLE IF EXISTS kb_articles;
@@ -0,0 +1,35 @@
+-- Knowledge base articles suggested to agents and on the storefront ticket form
+CREATE TABLE IF NOT EXISTS kb_articles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    summary TEXT,
+    body TEXT NOT NULL,
+    category VARCHAR(100),
+    tags JSONB DEFAULT '[]',
+    -- DRAFT, PUBLISHED or ARCHIVED; only published articles are suggested
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT',
+    -- PUBLIC articles are shown to customers, INTERNAL ones to agents only
+    visibility VARCHAR(20) NOT NULL DEFAULT 'PUBLIC',
+    -- Link to the article in an external help center, if hosted there
+    external_url TEXT,
+    suggested_count INTEGER NOT NULL DEFAULT 0,
+    inserted_count INTEGER NOT NULL DEFAULT 0,
+    deflected_count INTEGER NOT NULL DEFAULT 0,
+    created_by VARCHAR(255) NOT NULL,
+    updated_by VARCHAR(255),
+    published_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_kb_articles_tenant_id ON kb_articles(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_kb_articles_category ON kb_articles(category);
+
+-- Full-text index matching the weighted document searched for suggestions
+CREATE INDEX IF NOT EXISTS idx_kb_articles_search ON kb_articles USING GIN ((
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('english', COALESCE(tags::text, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(summary, '')), 'B') ||
+    setweight(to_tsvector('english', body), 'C')
+));
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,12 +20,14 @@ import (
 
 	"vendor-service/internal/clients"
 	"vendor-service/internal/config"
+	"vendor-service/internal/database"
 	"vendor-service/internal/events"
 	"vendor-service/internal/handlers"
 	localMiddleware "vendor-service/internal/middleware"
-	"vendor-service/internal/models"
 	"vendor-service/internal/repository"
+	"vendor-service/internal/serviceauth"
 	"vendor-service/internal/services"
+	"vendor-service/internal/tenancy"
 
 	gosharedmw "github.com/Tesseract-Nexus/go-shared/middleware"
 	"github.com/Tesseract-Nexus/go-shared/rbac"
@@ -78,15 +82,24 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// Initialize database
-	db, err := initializeDatabase(cfg)
+	// Initialize database, waiting while it is still starting up
+	dbCfg := database.ConfigFromEnv(cfg.Environment == "production")
+	db, err := database.Connect(dbCfg, func() (*gorm.DB, error) { return initializeDatabase(cfg) })
 	if err != nil {
 		log.WithError(err).Fatal("Failed to initialize database")
 	}
 
-	// Run database migrations
-	if err := runMigrations(db); err != nil {
-		log.WithError(err).Fatal("Failed to run migrations")
+	// "vendor-service migrate [status]" applies migrations and exits, for running as an init container
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := database.RunCommand(db, dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
+	// Migrate, or wait for the migrate init container, before serving
+	if err := database.Prepare(db, dbCfg); err != nil {
+		log.Fatalf("Database schema is not ready: %v", err)
 	}
 
 	// Initialize Redis client (graceful degradation if unavailable)
@@ -153,9 +166,33 @@ func main() {
 		"db_name":     cfg.DBName,
 	}).Info("🚀 Vendor Service starting")
 
-	if err := router.Run(serverAddr); err != nil {
-		log.WithError(err).Fatal("Failed to start server")
+	srv := &http.Server{
+		Addr:    serverAddr,
+		Handler: router,
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Fatal("Failed to start server")
+		}
+	}()
+
+	// Wait for interrupt signal, then let in-flight requests finish
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Info("Shutting down vendor-service...")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.WithError(err).Warn("Server forced to shutdown")
+	}
+
+	if redisClient != nil {
+		redisClient.Close()
 	}
+	log.Info("Vendor service stopped")
 }
 
 // initializeDatabase establishes database connection
@@ -168,6 +205,11 @@ func initializeDatabase(cfg *config.Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Scope statements to the tenant on their context (see internal/tenancy)
+	if err := db.Use(tenancy.FromEnv()); err != nil {
+		return nil, fmt.Errorf("failed to register tenancy plugin: %w", err)
+	}
+
 	// Get underlying SQL database for ping
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -183,24 +225,6 @@ func initializeDatabase(cfg *config.Config) (*gorm.DB, error) {
 	return db, nil
 }
 
-// runMigrations runs database migrations
-func runMigrations(db *gorm.DB) error {
-	log.Info("🔄 Running database migrations...")
-
-	// Auto-migrate database tables
-	if err := db.AutoMigrate(
-		&models.Vendor{},
-		&models.VendorAddress{},
-		&models.VendorPayment{},
-		&models.Storefront{},
-	); err != nil {
-		return fmt.Errorf("failed to migrate database: %w", err)
-	}
-
-	log.Info("✅ Database migrations completed successfully")
-	return nil
-}
-
 // setupRouter configures the Gin router with middleware and routes
 func setupRouter(cfg *config.Config, vendorHandler *handlers.VendorHandler, documentHandler *handlers.DocumentHandler, healthHandler *handlers.HealthHandler, storefrontHandler *handlers.StorefrontHandler, rbacMiddleware *rbac.Middleware, redisClient *redis.Client) *gin.Engine {
 	router := gin.New()
@@ -212,9 +236,11 @@ func setupRouter(cfg *config.Config, vendorHandler *handlers.VendorHandler, docu
 	// Security headers middleware
 	router.Use(gosharedmw.SecurityHeaders())
 
-	// Rate limiting middleware (uses Redis for distributed rate limiting)
+	// Rate limiting middleware (uses Redis for distributed rate limiting; profiles are managed in staff-service)
 	if redisClient != nil {
-		router.Use(gosharedmw.RedisRateLimitMiddlewareWithProfile(redisClient, "standard"))
+		rateLimiter := localMiddleware.NewProfileRateLimiter(redisClient)
+		go rateLimiter.Start(context.Background())
+		router.Use(rateLimiter.Middleware())
 		log.Info("✓ Redis-based rate limiting enabled")
 	} else {
 		router.Use(gosharedmw.RateLimit())
@@ -242,10 +268,11 @@ func setupRouter(cfg *config.Config, vendorHandler *handlers.VendorHandler, docu
 	}
 
 	// Internal API routes - for service-to-service communication (no RBAC)
-	// These routes are protected by network policies (only accessible within cluster)
-	// and require X-Tenant-ID header for tenant isolation
+	// Callers authenticate with their mesh identity or a service token, and the routes
+	// require X-Tenant-ID header for tenant isolation
+	internalAuth := serviceauth.New(serviceauth.ConfigFromEnv("vendor-service"), log)
 	internal := router.Group("/internal")
-	internal.Use(localMiddleware.TenantMiddleware())
+	internal.Use(internalAuth.Require("tenant-service"), localMiddleware.TenantMiddleware())
 	{
 		// Internal vendor creation - used by tenant-service during onboarding
 		internal.POST("/vendors", vendorHandler.CreateVendor)
@@ -258,6 +285,8 @@ func setupRouter(cfg *config.Config, vendorHandler *handlers.VendorHandler, docu
 		// Internal vendor storefronts lookup - used by tenant-service for reconciliation
 		internal.GET("/vendors/:id/storefronts", storefrontHandler.GetVendorStorefronts)
 	}
+	// Internal vendor lookup - used by payment-service for the vendor's legal details on commission invoices
+	router.GET("/internal/vendors/:id", internalAuth.Require("payment-service"), localMiddleware.TenantMiddleware(), vendorHandler.GetVendor)
 
 	// API v1 routes
 	api := router.Group("/api/v1")
@@ -287,6 +316,10 @@ func setupRouter(cfg *config.Config, vendorHandler *handlers.VendorHandler, docu
 		vendors.PUT("/:id", rbacMiddleware.RequirePermission(rbac.PermissionVendorsUpdate), vendorHandler.UpdateVendor)
 		vendors.DELETE("/:id", rbacMiddleware.RequirePermission(rbac.PermissionVendorsManage), vendorHandler.DeleteVendor)
 
+		// Trash - soft-deleted vendors can be listed and restored
+		vendors.GET("/trash", rbacMiddleware.RequirePermission(rbac.PermissionVendorsManage), vendorHandler.GetTrashedVendors)
+		vendors.POST("/:id/restore", rbacMiddleware.RequirePermission(rbac.PermissionVendorsManage), vendorHandler.RestoreVendor)
+
 		// Bulk operations
 		vendors.POST("/bulk", rbacMiddleware.RequirePermission(rbac.PermissionVendorsCreate), vendorHandler.BulkCreateVendors)
 
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"os"
 	"time"
+
+	"vendor-service/internal/serviceauth"
 )
 
 // StaffClient handles HTTP communication with staff-service for RBAC operations
@@ -69,6 +71,7 @@ func (c *StaffClient) SeedVendorRoles(ctx context.Context, tenantID, vendorID st
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
+	serviceauth.Sign(req, "vendor-service")
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Tenant-ID", tenantID)
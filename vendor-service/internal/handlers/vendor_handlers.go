@@ -385,6 +385,95 @@ func (h *VendorHandler) DeleteVendor(c *gin.Context) {
 	})
 }
 
+// GetTrashedVendors lists soft-deleted vendors
+// @Summary List deleted vendors
+// @Description Get a paginated list of soft-deleted vendors that can be restored
+// @Tags vendors
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} models.VendorListResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /vendors/trash [get]
+func (h *VendorHandler) GetTrashedVendors(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	vendors, pagination, err := h.service.ListDeletedVendors(tenantID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "FETCH_FAILED",
+				Message: "Failed to retrieve deleted vendors",
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.VendorListResponse{
+		Success:    true,
+		Data:       vendors,
+		Pagination: pagination,
+	})
+}
+
+// RestoreVendor restores a soft-deleted vendor
+// @Summary Restore vendor
+// @Description Move a soft-deleted vendor out of the trash
+// @Tags vendors
+// @Produce json
+// @Param id path string true "Vendor ID"
+// @Success 200 {object} models.VendorResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /vendors/{id}/restore [post]
+func (h *VendorHandler) RestoreVendor(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    "INVALID_ID",
+				Message: "Invalid vendor ID format",
+			},
+		})
+		return
+	}
+
+	vendor, err := h.service.RestoreVendor(tenantID, id, tenantID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		code := "RESTORE_FAILED"
+
+		if err.Error() == "vendor not found" {
+			status = http.StatusNotFound
+			code = "NOT_FOUND"
+		}
+
+		c.JSON(status, models.ErrorResponse{
+			Success: false,
+			Error: models.Error{
+				Code:    code,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.VendorResponse{
+		Success: true,
+		Data:    vendor,
+	})
+}
+
 // GetVendorAnalytics retrieves vendor analytics
 // @Summary Get vendor analytics
 // @Description Get analytics data for vendors
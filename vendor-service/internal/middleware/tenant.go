@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"vendor-service/internal/tenancy"
 )
 
 // TenantMiddleware extracts tenant info from headers
@@ -53,6 +55,8 @@ func TenantMiddleware() gin.HandlerFunc {
 		// Set values in context for handlers
 		c.Set("vendor_id", vendorID)
 		c.Set("tenant_id", vendorID) // backwards compatibility
+		// Scope database statements made with the request context (see internal/tenancy)
+		c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), vendorID))
 		if storefrontID != "" {
 			c.Set("storefront_id", storefrontID)
 		}
@@ -106,6 +110,8 @@ func RequireVendorID() gin.HandlerFunc {
 
 		c.Set("vendor_id", vendorID)
 		c.Set("tenant_id", vendorID)
+		// Scope database statements made with the request context (see internal/tenancy)
+		c.Request = c.Request.WithContext(tenancy.WithTenant(c.Request.Context(), vendorID))
 		c.Next()
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -29,6 +30,8 @@ type VendorRepository interface {
 	GetFirstByTenantID(tenantID string) (*models.Vendor, error)
 	Update(tenantID string, id uuid.UUID, updates *models.UpdateVendorRequest) error
 	Delete(tenantID string, id uuid.UUID, deletedBy string) error
+	ListDeleted(tenantID string, page, limit int) ([]models.Vendor, *models.PaginationInfo, error)
+	Restore(tenantID string, id uuid.UUID, restoredBy string) (*models.Vendor, error)
 	List(tenantID string, filters *models.VendorFilters, page, limit int) ([]models.Vendor, *models.PaginationInfo, error)
 	BulkCreate(tenantID string, vendors []models.Vendor) error
 	BulkUpdate(tenantID string, updates []models.UpdateVendorRequest) error
@@ -242,16 +245,71 @@ func (r *vendorRepository) Update(tenantID string, id uuid.UUID, updates *models
 }
 
 func (r *vendorRepository) Delete(tenantID string, id uuid.UUID, deletedBy string) error {
-	err := r.db.Model(&models.Vendor{}).
+	result := r.db.Model(&models.Vendor{}).
 		Where("tenant_id = ? AND id = ?", tenantID, id).
 		Updates(map[string]interface{}{
 			"deleted_at": time.Now(),
 			"updated_by": deletedBy,
-		}).Error
-	if err == nil {
-		r.invalidateVendorCaches(context.Background(), tenantID, id)
+		})
+	if result.Error != nil {
+		return result.Error
 	}
-	return err
+	if result.RowsAffected == 0 {
+		return errors.New("vendor not found")
+	}
+	r.invalidateVendorCaches(context.Background(), tenantID, id)
+	return nil
+}
+
+// ListDeleted retrieves soft-deleted vendors, most recently deleted first
+func (r *vendorRepository) ListDeleted(tenantID string, page, limit int) ([]models.Vendor, *models.PaginationInfo, error) {
+	var vendors []models.Vendor
+	var total int64
+
+	query := r.db.Unscoped().Model(&models.Vendor{}).
+		Where("tenant_id = ? AND deleted_at IS NOT NULL", tenantID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).
+		Order("deleted_at DESC").
+		Find(&vendors).Error; err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	pagination := &models.PaginationInfo{
+		Page:        page,
+		Limit:       limit,
+		Total:       total,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+
+	return vendors, pagination, nil
+}
+
+// Restore clears deleted_at on a soft-deleted vendor
+func (r *vendorRepository) Restore(tenantID string, id uuid.UUID, restoredBy string) (*models.Vendor, error) {
+	result := r.db.Unscoped().Model(&models.Vendor{}).
+		Where("tenant_id = ? AND id = ? AND deleted_at IS NOT NULL", tenantID, id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"updated_by": restoredBy,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("vendor not found")
+	}
+
+	r.invalidateVendorCaches(context.Background(), tenantID, id)
+	return r.GetByID(tenantID, id)
 }
 
 func (r *vendorRepository) List(tenantID string, filters *models.VendorFilters, page, limit int) ([]models.Vendor, *models.PaginationInfo, error) {
@@ -19,6 +19,8 @@ type VendorService interface {
 	GetVendor(tenantID string, id uuid.UUID) (*models.Vendor, error)
 	UpdateVendor(tenantID string, id uuid.UUID, req *models.UpdateVendorRequest) (*models.Vendor, error)
 	DeleteVendor(tenantID string, id uuid.UUID, deletedBy string) error
+	ListDeletedVendors(tenantID string, page, limit int) ([]models.Vendor, *models.PaginationInfo, error)
+	RestoreVendor(tenantID string, id uuid.UUID, restoredBy string) (*models.Vendor, error)
 	ListVendors(tenantID string, filters *models.VendorFilters, page, limit int) ([]models.Vendor, *models.PaginationInfo, error)
 
 	// Bulk operations
@@ -234,6 +236,31 @@ func (s *vendorService) DeleteVendor(tenantID string, id uuid.UUID, deletedBy st
 	return s.repo.Delete(tenantID, id, deletedBy)
 }
 
+// ListDeletedVendors retrieves soft-deleted vendors with pagination
+func (s *vendorService) ListDeletedVendors(tenantID string, page, limit int) ([]models.Vendor, *models.PaginationInfo, error) {
+	if tenantID == "" {
+		return nil, nil, errors.New("tenant ID is required")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	return s.repo.ListDeleted(tenantID, page, limit)
+}
+
+// RestoreVendor restores a soft-deleted vendor
+func (s *vendorService) RestoreVendor(tenantID string, id uuid.UUID, restoredBy string) (*models.Vendor, error) {
+	if tenantID == "" {
+		return nil, errors.New("tenant ID is required")
+	}
+
+	return s.repo.Restore(tenantID, id, restoredBy)
+}
+
 // ListVendors retrieves vendors with filters and pagination
 func (s *vendorService) ListVendors(tenantID string, filters *models.VendorFilters, page, limit int) ([]models.Vendor, *models.PaginationInfo, error) {
 	if tenantID == "" {